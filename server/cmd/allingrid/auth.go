@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// newLoginCmd 使用账号密码登录并创建服务令牌
+func newLoginCmd(opts *globalOptions) *cobra.Command {
+	var (
+		email         string
+		passwordStdin bool
+		name          string
+		expiresInDays int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "登录并创建服务令牌",
+		Long:  "使用邮箱和密码登录，为当前账号创建服务令牌。令牌明文只显示一次，请保存到 ALLINGRID_TOKEN",
+		Example: `  # 从标准输入读取密码
+  echo "$PASSWORD" | allingrid login --email admin@example.com --password-stdin --name ci`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password := os.Getenv("ALLINGRID_PASSWORD")
+			if passwordStdin {
+				line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil && line == "" {
+					return fmt.Errorf("读取密码失败: %w", err)
+				}
+				password = strings.TrimRight(line, "\r\n")
+			}
+			if password == "" {
+				return fmt.Errorf("请通过 --password-stdin 或 ALLINGRID_PASSWORD 提供密码")
+			}
+
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			client := sdk.New(opts.server, sdk.WithUserAgent("allingrid/"+version))
+			if _, err := client.SignIn(ctx, email, password); err != nil {
+				return fmt.Errorf("登录失败: %w", err)
+			}
+			token, err := client.CreateServiceToken(ctx, &sdk.CreateServiceTokenRequest{Name: name, ExpiresInDays: expiresInDays})
+			if err != nil {
+				return fmt.Errorf("创建服务令牌失败: %w", err)
+			}
+
+			return opts.print(token, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "令牌ID:\t%s\n", token.ID)
+				fmt.Fprintf(w, "名称:\t%s\n", token.Name)
+				fmt.Fprintf(w, "过期时间:\t%s\n", formatTime(token.ExpiresAt))
+				fmt.Fprintf(w, "令牌:\t%s\n", token.Secret)
+				fmt.Fprintf(w, "\n💡 提示:\t令牌只显示这一次，请执行 export ALLINGRID_TOKEN=%s\n", token.Secret)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "登录邮箱")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "从标准输入读取密码")
+	cmd.Flags().StringVar(&name, "name", "allingrid", "服务令牌名称")
+	cmd.Flags().IntVar(&expiresInDays, "expires-in-days", 0, "有效天数，0 表示不过期")
+	cmd.MarkFlagRequired("email")
+
+	return cmd
+}
+
+// newTokensCmd 服务令牌管理命令
+func newTokensCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "管理服务令牌",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "列出当前账号的服务令牌",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			tokens, err := client.ListServiceTokens(ctx)
+			if err != nil {
+				return err
+			}
+			return opts.print(tokens, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\t名称\t尾号\t最近使用\t过期时间\t状态")
+				for _, t := range tokens {
+					status := "有效"
+					if t.RevokedAt != nil {
+						status = "已吊销"
+					}
+					fmt.Fprintf(w, "%s\t%s\t…%s\t%s\t%s\t%s\n", t.ID, t.Name, t.Hint, formatTime(t.LastUsedAt), formatTime(t.ExpiresAt), status)
+				}
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "revoke <tokenId>",
+		Short: "吊销服务令牌",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			if err := client.RevokeServiceToken(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("✅ 服务令牌 %s 已吊销\n", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// newCacheCmd 缓存管理命令（需要管理员账号的服务令牌）
+func newCacheCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "管理服务端缓存（需要管理员权限）",
+	}
+
+	var tableID string
+	flushCmd := &cobra.Command{
+		Use:   "flush",
+		Short: "清理缓存",
+		Example: `  allingrid cache flush
+  allingrid cache flush --table tbl_xxx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			if err := client.FlushCache(ctx, &sdk.FlushCacheRequest{TableID: tableID}); err != nil {
+				return err
+			}
+			if tableID != "" {
+				fmt.Printf("✅ 已清理表 %s 的缓存\n", tableID)
+			} else {
+				fmt.Println("✅ 已清空全部缓存")
+			}
+			return nil
+		},
+	}
+	flushCmd.Flags().StringVar(&tableID, "table", "", "只清理该表相关的缓存")
+	cmd.AddCommand(flushCmd)
+
+	return cmd
+}
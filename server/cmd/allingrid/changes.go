@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// newChangesCmd 变更流命令
+func newChangesCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "changes",
+		Short: "订阅 Base 变更流",
+	}
+
+	var (
+		baseID   string
+		tableID  string
+		cursor   int64
+		fromHead bool
+		interval time.Duration
+	)
+	tailCmd := &cobra.Command{
+		Use:   "tail",
+		Short: "持续输出 Base 的变更（NDJSON），Ctrl-C 退出",
+		Long:  "每行输出一条变更；中断后用最后一条变更的 seq 作为 --cursor 继续",
+		Example: `  allingrid changes tail --base bse_xxx --from-head
+  allingrid changes tail --base bse_xxx --cursor 1024 --table tbl_xxx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			if fromHead {
+				head, err := client.GetChangeHead(ctx, baseID)
+				if err != nil {
+					return err
+				}
+				cursor = head.Cursor
+			}
+			fmt.Fprintf(os.Stderr, "从游标 %d 开始订阅 %s 的变更...\n", cursor, baseID)
+
+			enc := json.NewEncoder(os.Stdout)
+			err = client.TailChanges(ctx, baseID, cursor, sdk.TailOptions{TableID: tableID, PollInterval: interval}, func(change *sdk.Change) error {
+				cursor = change.Seq
+				return enc.Encode(change)
+			})
+			switch {
+			case errors.Is(err, sdk.ErrCursorExpired):
+				return fmt.Errorf("游标 %d 已过期，请重新全量同步后使用 --from-head", cursor)
+			case ctx.Err() != nil:
+				fmt.Fprintf(os.Stderr, "已停止，最后的游标: %d\n", cursor)
+				return nil
+			}
+			return err
+		},
+	}
+	tailCmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	tailCmd.Flags().StringVar(&tableID, "table", "", "只输出该表的变更")
+	tailCmd.Flags().Int64Var(&cursor, "cursor", 0, "从该游标之后开始")
+	tailCmd.Flags().BoolVar(&fromHead, "from-head", false, "从当前最新游标开始（只输出新变更）")
+	tailCmd.Flags().DurationVar(&interval, "interval", 2*time.Second, "没有新变更时的轮询间隔")
+	tailCmd.MarkFlagRequired("base")
+	cmd.AddCommand(tailCmd)
+
+	return cmd
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// globalOptions 所有子命令共用的选项
+type globalOptions struct {
+	server string
+	token  string
+	json   bool
+}
+
+// client 创建已认证的 SDK 客户端
+func (o *globalOptions) client() (*sdk.Client, error) {
+	if o.token == "" {
+		return nil, fmt.Errorf("缺少服务令牌，请通过 --token 或 ALLINGRID_TOKEN 提供（可用 allingrid login 创建）")
+	}
+	return sdk.New(o.server, sdk.WithToken(o.token), sdk.WithUserAgent("allingrid/"+version)), nil
+}
+
+// print 按 --json 输出 JSON，否则调用 table 输出表格
+func (o *globalOptions) print(v interface{}, table func(w *tabwriter.Writer)) error {
+	if o.json || table == nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+// signalContext Ctrl-C 时取消的上下文
+func signalContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// waitOperation 轮询长时操作直到结束，进度输出到 stderr
+func waitOperation(ctx context.Context, client *sdk.Client, op *sdk.Operation) (*sdk.Operation, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		switch op.Status {
+		case "succeeded", "failed", "cancelled":
+			fmt.Fprintf(os.Stderr, "\r操作 %s: %s（%d/%d，失败 %d）\n", op.ID, op.Status, op.Processed, op.Total, op.FailedCount)
+			return op, nil
+		}
+		fmt.Fprintf(os.Stderr, "\r操作 %s: %s %.0f%%", op.ID, op.Status, op.Progress*100)
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr)
+			return op, ctx.Err()
+		case <-ticker.C:
+		}
+		next, err := client.GetOperation(ctx, op.ID)
+		if err != nil {
+			return op, err
+		}
+		op = next
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,60 @@
+// allingrid 管理与数据运维命令行工具
+//
+// 通过 HTTP API 管理工作空间、导入导出数据、创建与恢复快照、订阅变更流、清理缓存以及比对环境间的表结构。
+// 使用服务令牌认证：先执行 allingrid login 创建令牌，再通过 --token 或 ALLINGRID_TOKEN 传入。
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// version 构建时通过 -ldflags "-X main.version=..." 注入
+var version = "dev"
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+
+	cmd := &cobra.Command{
+		Use:           "allingrid",
+		Short:         "LuckDB 管理与数据运维工具",
+		Long:          "通过 API 管理工作空间、导入导出数据、创建与恢复快照、订阅变更流、清理缓存以及比对环境间的表结构",
+		Version:       version,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.server, "server", envOr("ALLINGRID_SERVER", "http://localhost:8080"), "服务地址（环境变量 ALLINGRID_SERVER）")
+	flags.StringVar(&opts.token, "token", os.Getenv("ALLINGRID_TOKEN"), "服务令牌（环境变量 ALLINGRID_TOKEN）")
+	flags.BoolVar(&opts.json, "json", false, "以 JSON 输出结果")
+
+	cmd.AddCommand(newLoginCmd(opts))
+	cmd.AddCommand(newTokensCmd(opts))
+	cmd.AddCommand(newWorkspacesCmd(opts))
+	cmd.AddCommand(newBasesCmd(opts))
+	cmd.AddCommand(newExportCmd(opts))
+	cmd.AddCommand(newImportCmd(opts))
+	cmd.AddCommand(newSnapshotCmd(opts))
+	cmd.AddCommand(newChangesCmd(opts))
+	cmd.AddCommand(newCacheCmd(opts))
+	cmd.AddCommand(newSchemaCmd(opts))
+
+	return cmd
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
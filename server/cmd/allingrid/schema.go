@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// 结构差异类型（以源环境为准，描述目标环境需要的变更）
+const (
+	changeTableMissing = "table_missing" // 目标缺少该表
+	changeTableExtra   = "table_extra"   // 目标多出该表
+	changeFieldMissing = "field_missing" // 目标缺少该字段
+	changeFieldExtra   = "field_extra"   // 目标多出该字段
+	changeFieldChanged = "field_changed" // 字段类型或约束不同
+)
+
+// tableSchema 用于比对的表结构
+type tableSchema struct {
+	Name   string
+	Fields []*sdk.Field
+}
+
+// schemaChange 一处结构差异
+type schemaChange struct {
+	Kind   string `json:"kind"`
+	Table  string `json:"table"`
+	Field  string `json:"field,omitempty"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target,omitempty"`
+}
+
+// newSchemaCmd 表结构命令
+func newSchemaCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "比对表结构，按 YAML 结构描述导出与应用",
+	}
+
+	var (
+		baseID       string
+		targetServer string
+		targetToken  string
+		targetBase   string
+		exitCode     bool
+	)
+	diffCmd := &cobra.Command{
+		Use:   "diff",
+		Short: "比对两个环境（或两个 Base）的表结构",
+		Long:  "按表名和字段名比对，列出目标 Base 与源 Base 不一致之处。目标环境默认与源环境相同",
+		Example: `  # 比对预发与生产
+  allingrid schema diff --base bse_staging \
+    --target-server https://prod.example.com --target-token "$PROD_TOKEN" --target-base bse_prod`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, err := opts.client()
+			if err != nil {
+				return err
+			}
+			targetOpts := *opts
+			if targetServer != "" {
+				targetOpts.server = targetServer
+			}
+			if targetToken != "" {
+				targetOpts.token = targetToken
+			}
+			target, err := targetOpts.client()
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			sourceSchema, err := loadSchema(ctx, source, baseID)
+			if err != nil {
+				return fmt.Errorf("读取源结构失败: %w", err)
+			}
+			targetSchema, err := loadSchema(ctx, target, targetBase)
+			if err != nil {
+				return fmt.Errorf("读取目标结构失败: %w", err)
+			}
+
+			changes := diffSchemas(sourceSchema, targetSchema)
+			if err := opts.print(changes, func(w *tabwriter.Writer) {
+				if len(changes) == 0 {
+					fmt.Fprintln(w, "✅ 表结构一致")
+					return
+				}
+				fmt.Fprintln(w, "差异\t表\t字段\t源\t目标")
+				for _, c := range changes {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Kind, c.Table, c.Field, c.Source, c.Target)
+				}
+			}); err != nil {
+				return err
+			}
+			if exitCode && len(changes) > 0 {
+				return fmt.Errorf("发现 %d 处结构差异", len(changes))
+			}
+			return nil
+		},
+	}
+	diffCmd.Flags().StringVar(&baseID, "base", "", "源 Base ID")
+	diffCmd.Flags().StringVar(&targetServer, "target-server", "", "目标服务地址（默认同 --server）")
+	diffCmd.Flags().StringVar(&targetToken, "target-token", "", "目标环境服务令牌（默认同 --token）")
+	diffCmd.Flags().StringVar(&targetBase, "target-base", "", "目标 Base ID")
+	diffCmd.Flags().BoolVar(&exitCode, "exit-code", false, "存在差异时以非零状态退出（用于 CI）")
+	diffCmd.MarkFlagRequired("base")
+	diffCmd.MarkFlagRequired("target-base")
+	cmd.AddCommand(diffCmd)
+	addSchemaSpecCommands(cmd, opts)
+
+	return cmd
+}
+
+// loadSchema 读取 Base 下全部表的字段
+func loadSchema(ctx context.Context, client *sdk.Client, baseID string) ([]tableSchema, error) {
+	tables, err := client.ListTables(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	schema := make([]tableSchema, 0, len(tables))
+	for _, table := range tables {
+		fields, err := client.ListFields(ctx, table.ID)
+		if err != nil {
+			return nil, err
+		}
+		schema = append(schema, tableSchema{Name: table.Name, Fields: fields})
+	}
+	return schema, nil
+}
+
+// diffSchemas 按表名、字段名比对结构，结果按表名、字段名排序
+func diffSchemas(source, target []tableSchema) []schemaChange {
+	targetTables := make(map[string]tableSchema, len(target))
+	for _, t := range target {
+		targetTables[t.Name] = t
+	}
+	sourceTables := make(map[string]bool, len(source))
+
+	changes := []schemaChange{}
+	for _, s := range source {
+		sourceTables[s.Name] = true
+		t, ok := targetTables[s.Name]
+		if !ok {
+			changes = append(changes, schemaChange{Kind: changeTableMissing, Table: s.Name})
+			continue
+		}
+		changes = append(changes, diffFields(s, t)...)
+	}
+	for _, t := range target {
+		if !sourceTables[t.Name] {
+			changes = append(changes, schemaChange{Kind: changeTableExtra, Table: t.Name})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Table != changes[j].Table {
+			return changes[i].Table < changes[j].Table
+		}
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func diffFields(source, target tableSchema) []schemaChange {
+	targetFields := make(map[string]*sdk.Field, len(target.Fields))
+	for _, f := range target.Fields {
+		targetFields[f.Name] = f
+	}
+	sourceFields := make(map[string]bool, len(source.Fields))
+
+	var changes []schemaChange
+	for _, sf := range source.Fields {
+		sourceFields[sf.Name] = true
+		tf, ok := targetFields[sf.Name]
+		if !ok {
+			changes = append(changes, schemaChange{Kind: changeFieldMissing, Table: source.Name, Field: sf.Name, Source: sf.Type})
+			continue
+		}
+		if s, t := fieldSignature(sf), fieldSignature(tf); s != t {
+			changes = append(changes, schemaChange{Kind: changeFieldChanged, Table: source.Name, Field: sf.Name, Source: s, Target: t})
+		}
+	}
+	for _, tf := range target.Fields {
+		if !sourceFields[tf.Name] {
+			changes = append(changes, schemaChange{Kind: changeFieldExtra, Table: source.Name, Field: tf.Name, Target: tf.Type})
+		}
+	}
+	return changes
+}
+
+// fieldSignature 参与比对的字段属性（选项中常含环境相关的ID，不参与比对）
+func fieldSignature(f *sdk.Field) string {
+	sig := f.Type
+	if f.Required {
+		sig += ",required"
+	}
+	if f.Unique {
+		sig += ",unique"
+	}
+	if f.IsPrimary {
+		sig += ",primary"
+	}
+	return sig
+}
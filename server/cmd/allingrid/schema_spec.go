@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// addSchemaSpecCommands 结构即代码命令：export / plan / apply
+func addSchemaSpecCommands(cmd *cobra.Command, opts *globalOptions) {
+	var (
+		baseID   string
+		file     string
+		prune    bool
+		exitCode bool
+	)
+
+	exportCmd := &cobra.Command{
+		Use:     "export",
+		Short:   "导出 Base 结构描述（YAML）",
+		Example: `  allingrid schema export --base bse_xxx --file schema.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			doc, err := client.ExportBaseSchema(ctx, baseID)
+			if err != nil {
+				return err
+			}
+			if file == "" || file == "-" {
+				_, err = io.WriteString(os.Stdout, doc.YAML)
+				return err
+			}
+			return os.WriteFile(file, []byte(doc.YAML), 0o644)
+		},
+	}
+	exportCmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "输出文件（默认标准输出）")
+	exportCmd.MarkFlagRequired("base")
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "对比结构描述与 Base，列出应用时将执行的变更",
+		Example: `  # CI 中检查环境是否与版本库中的结构一致
+  allingrid schema plan --base bse_xxx --file schema.yaml --exit-code`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, req, err := schemaSpecRequest(opts, file, prune)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			plan, err := client.DiffBaseSchema(ctx, baseID, req)
+			if err != nil {
+				return err
+			}
+			if err := printSchemaPlan(opts, plan, "✅ 结构一致，无需变更"); err != nil {
+				return err
+			}
+			if exitCode && (len(plan.Changes) > 0 || len(plan.Conflicts) > 0) {
+				return fmt.Errorf("发现 %d 项变更、%d 项冲突", len(plan.Changes), len(plan.Conflicts))
+			}
+			return nil
+		},
+	}
+	planCmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	planCmd.Flags().StringVarP(&file, "file", "f", "", "结构描述文件（- 表示标准输入）")
+	planCmd.Flags().BoolVar(&prune, "prune", false, "同时列出描述中未声明、将被删除的表、字段与视图")
+	planCmd.Flags().BoolVar(&exitCode, "exit-code", false, "存在变更或冲突时以非零状态退出（用于 CI）")
+	planCmd.MarkFlagRequired("base")
+	planCmd.MarkFlagRequired("file")
+
+	applyCmd := &cobra.Command{
+		Use:     "apply",
+		Short:   "应用结构描述（幂等，重复执行不会产生变更）",
+		Example: `  allingrid schema apply --base bse_xxx --file schema.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, req, err := schemaSpecRequest(opts, file, prune)
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			applied, err := client.ApplyBaseSchema(ctx, baseID, req)
+			if err != nil {
+				return err
+			}
+			return printSchemaPlan(opts, applied, "✅ 结构已一致，未做变更")
+		},
+	}
+	applyCmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	applyCmd.Flags().StringVarP(&file, "file", "f", "", "结构描述文件（- 表示标准输入）")
+	applyCmd.Flags().BoolVar(&prune, "prune", false, "删除描述中未声明的表、字段与视图")
+	applyCmd.MarkFlagRequired("base")
+	applyCmd.MarkFlagRequired("file")
+
+	cmd.AddCommand(exportCmd, planCmd, applyCmd)
+}
+
+// schemaSpecRequest 读取结构描述文件并创建客户端
+func schemaSpecRequest(opts *globalOptions, file string, prune bool) (*sdk.Client, *sdk.SchemaSpecRequest, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取结构描述失败: %w", err)
+	}
+	client, err := opts.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, &sdk.SchemaSpecRequest{YAML: string(data), Prune: prune}, nil
+}
+
+// printSchemaPlan 输出变更与冲突
+func printSchemaPlan(opts *globalOptions, plan *sdk.SchemaPlan, emptyMessage string) error {
+	return opts.print(plan, func(w *tabwriter.Writer) {
+		if len(plan.Changes) == 0 && len(plan.Conflicts) == 0 {
+			fmt.Fprintln(w, emptyMessage)
+			return
+		}
+		if len(plan.Changes) > 0 {
+			fmt.Fprintln(w, "变更\t表\t名称\t说明")
+			for _, c := range plan.Changes {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Kind, c.Table, c.Name, strings.Join(c.Details, "; "))
+			}
+		}
+		if len(plan.Conflicts) > 0 {
+			fmt.Fprintln(w, "冲突\t表\t名称\t原因")
+			for _, c := range plan.Conflicts {
+				fmt.Fprintf(w, "conflict\t%s\t%s\t%s\n", c.Table, c.Name, c.Reason)
+			}
+		}
+	})
+}
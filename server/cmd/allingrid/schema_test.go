@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	source := []tableSchema{
+		{Name: "Orders", Fields: []*sdk.Field{
+			{Name: "Title", Type: "text", IsPrimary: true},
+			{Name: "Amount", Type: "number", Required: true},
+			{Name: "Notes", Type: "text"},
+		}},
+		{Name: "Customers", Fields: []*sdk.Field{{Name: "Name", Type: "text"}}},
+	}
+	target := []tableSchema{
+		{Name: "Orders", Fields: []*sdk.Field{
+			{Name: "Title", Type: "text", IsPrimary: true},
+			{Name: "Amount", Type: "text"},
+			{Name: "Legacy", Type: "text"},
+		}},
+		{Name: "Archive"},
+	}
+
+	got := diffSchemas(source, target)
+	want := []schemaChange{
+		{Kind: changeTableExtra, Table: "Archive"},
+		{Kind: changeTableMissing, Table: "Customers"},
+		{Kind: changeFieldChanged, Table: "Orders", Field: "Amount", Source: "number,required", Target: "text"},
+		{Kind: changeFieldExtra, Table: "Orders", Field: "Legacy", Target: "text"},
+		{Kind: changeFieldMissing, Table: "Orders", Field: "Notes", Source: "text"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("diffSchemas() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if changes := diffSchemas(source, source); len(changes) != 0 {
+		t.Fatalf("identical schemas should have no changes, got %+v", changes)
+	}
+}
+
+func TestRemapRecordByFieldName(t *testing.T) {
+	source := []*sdk.Field{
+		{ID: "fld_a", Name: "Title", Type: "text"},
+		{ID: "fld_b", Name: "Total", Type: "formula"},
+		{ID: "fld_c", Name: "Owner", Type: "user"},
+		{ID: "fld_d", Name: "Gone", Type: "text"},
+	}
+	target := []*sdk.Field{
+		{ID: "fld_x", Name: "Title", Type: "text"},
+		{ID: "fld_y", Name: "Total", Type: "formula"},
+		{ID: "fld_z", Name: "Owner", Type: "user"},
+	}
+
+	mapping, skipped := mapFieldsByName(source, target)
+	if !reflect.DeepEqual(skipped, []string{"Total", "Gone"}) {
+		t.Fatalf("unexpected skipped fields %v", skipped)
+	}
+
+	got := remapRecord(sdk.Fields{"fld_a": "hello", "fld_b": 3, "fld_c": "usr_1", "fld_d": "x"}, mapping)
+	want := sdk.Fields{"fld_x": "hello", "fld_z": "usr_1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("remapRecord() = %v, want %v", got, want)
+	}
+}
+
+func TestDecodeRecords(t *testing.T) {
+	for name, input := range map[string]string{
+		"array":  `[{"fld_a": 1}, {"fld_a": 2}]`,
+		"ndjson": "{\"fld_a\": 1}\n{\"fld_a\": 2}\n",
+	} {
+		records, err := decodeRecords([]byte(input))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("%s: expected 2 records, got %d", name, len(records))
+		}
+	}
+	if _, err := decodeRecords([]byte("{\"fld_a\": 1}\nnot json")); err == nil {
+		t.Fatalf("invalid NDJSON should fail")
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// snapshotFormatVersion 快照文件格式版本
+const snapshotFormatVersion = 1
+
+// unrestorableFieldTypes 恢复时跳过的字段类型：计算字段由服务端重新计算，
+// 关联字段引用的记录ID在目标环境中不存在
+var unrestorableFieldTypes = map[string]bool{
+	"formula":          true,
+	"rollup":           true,
+	"lookup":           true,
+	"count":            true,
+	"autoNumber":       true,
+	"createdTime":      true,
+	"createdBy":        true,
+	"lastModifiedTime": true,
+	"lastModifiedBy":   true,
+	"link":             true,
+}
+
+// snapshotFile Base 快照文件：同一时间点的表结构与全部记录
+type snapshotFile struct {
+	Version int              `json:"version"`
+	BaseID  string           `json:"baseId"`
+	AsOf    time.Time        `json:"asOf"`
+	Tables  []*snapshotTable `json:"tables"`
+}
+
+type snapshotTable struct {
+	ID      string       `json:"id"`
+	Name    string       `json:"name"`
+	Fields  []*sdk.Field `json:"fields"`
+	Records []sdk.Fields `json:"records"` // 按字段ID组织的字段值
+}
+
+// newSnapshotCmd 快照命令
+func newSnapshotCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "创建与恢复 Base 快照",
+	}
+	cmd.AddCommand(newSnapshotCreateCmd(opts))
+	cmd.AddCommand(newSnapshotRestoreCmd(opts))
+	return cmd
+}
+
+func newSnapshotCreateCmd(opts *globalOptions) *cobra.Command {
+	var (
+		baseID  string
+		outPath string
+		asOf    string
+	)
+
+	cmd := &cobra.Command{
+		Use:     "create",
+		Short:   "将 Base 的表结构与记录保存为快照文件",
+		Long:    "所有表按同一时间点读取，快照内各表之间一致",
+		Example: `  allingrid snapshot create --base bse_xxx --out base.snapshot.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			snap, err := createSnapshot(ctx, client, baseID, asOf)
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(snap)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(outPath, data, 0o600); err != nil {
+				return err
+			}
+
+			total := 0
+			for _, t := range snap.Tables {
+				total += len(t.Records)
+			}
+			fmt.Printf("✅ 快照已保存到 %s（%d 张表，%d 条记录，时间点 %s）\n", outPath, len(snap.Tables), total, snap.AsOf.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	cmd.Flags().StringVar(&outPath, "out", "", "快照文件路径")
+	cmd.Flags().StringVar(&asOf, "as-of", "", "快照时间点（RFC3339，默认当前时间）")
+	cmd.MarkFlagRequired("base")
+	cmd.MarkFlagRequired("out")
+
+	return cmd
+}
+
+func newSnapshotRestoreCmd(opts *globalOptions) *cobra.Command {
+	var (
+		baseID string
+		file   string
+		wait   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "将快照中的记录恢复到 Base",
+		Long: `按表名匹配目标 Base 中的表、按字段名匹配字段，以 record_import 操作写入记录。
+目标表须已存在（可先用 allingrid schema diff 检查结构差异）；计算字段与关联字段不会恢复`,
+		Example: `  allingrid snapshot restore --base bse_yyy --file base.snapshot.json --wait`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			var snap snapshotFile
+			if err := json.Unmarshal(data, &snap); err != nil {
+				return fmt.Errorf("解析快照失败: %w", err)
+			}
+			if snap.Version != snapshotFormatVersion {
+				return fmt.Errorf("不支持的快照版本 %d", snap.Version)
+			}
+
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			ops, err := restoreSnapshot(ctx, client, &snap, baseID)
+			if err != nil {
+				return err
+			}
+			if !wait {
+				return opts.print(ops, nil)
+			}
+			return waitAll(ctx, client, ops)
+		},
+	}
+
+	cmd.Flags().StringVar(&baseID, "base", "", "目标 Base ID")
+	cmd.Flags().StringVar(&file, "file", "", "快照文件路径")
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待恢复完成")
+	cmd.MarkFlagRequired("base")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// createSnapshot 按同一时间点读取 Base 下全部表
+func createSnapshot(ctx context.Context, client *sdk.Client, baseID, asOf string) (*snapshotFile, error) {
+	tables, err := client.ListTables(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &snapshotFile{Version: snapshotFormatVersion, BaseID: baseID, Tables: make([]*snapshotTable, 0, len(tables))}
+	for _, table := range tables {
+		fields, err := client.ListFields(ctx, table.ID)
+		if err != nil {
+			return nil, err
+		}
+		st := &snapshotTable{ID: table.ID, Name: table.Name, Fields: fields, Records: []sdk.Fields{}}
+		at, err := readTableSnapshot(ctx, client, table.ID, asOf, 500, func(record *sdk.Record) error {
+			st.Records = append(st.Records, record.Data)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", table.Name, err)
+		}
+		// 第一张表确定时间点，其余表沿用，保证快照一致
+		if asOf == "" {
+			asOf = at.Format(time.RFC3339Nano)
+		}
+		snap.AsOf = at
+		snap.Tables = append(snap.Tables, st)
+	}
+	return snap, nil
+}
+
+// restoreSnapshot 为快照中的每张表发起导入操作
+func restoreSnapshot(ctx context.Context, client *sdk.Client, snap *snapshotFile, baseID string) ([]*sdk.Operation, error) {
+	targets, err := client.ListTables(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	targetByName := make(map[string]*sdk.Table, len(targets))
+	for _, t := range targets {
+		targetByName[t.Name] = t
+	}
+
+	var ops []*sdk.Operation
+	for _, table := range snap.Tables {
+		target, ok := targetByName[table.Name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "⚠️  跳过表 %s：目标 Base 中不存在同名表\n", table.Name)
+			continue
+		}
+		if len(table.Records) == 0 {
+			continue
+		}
+		targetFields, err := client.ListFields(ctx, target.ID)
+		if err != nil {
+			return ops, err
+		}
+		mapping, skipped := mapFieldsByName(table.Fields, targetFields)
+		for _, name := range skipped {
+			fmt.Fprintf(os.Stderr, "⚠️  表 %s 字段 %s 不会恢复\n", table.Name, name)
+		}
+
+		records := make([]sdk.Fields, 0, len(table.Records))
+		for _, record := range table.Records {
+			records = append(records, remapRecord(record, mapping))
+		}
+		started, err := startImport(ctx, client, baseID, target.ID, records, true, defaultImportChunk)
+		ops = append(ops, started...)
+		if err != nil {
+			return ops, err
+		}
+	}
+	return ops, nil
+}
+
+// mapFieldsByName 按字段名建立源字段ID到目标字段ID的映射，返回无法恢复的字段名
+func mapFieldsByName(source, target []*sdk.Field) (map[string]string, []string) {
+	targetByName := make(map[string]*sdk.Field, len(target))
+	for _, f := range target {
+		targetByName[f.Name] = f
+	}
+
+	mapping := make(map[string]string, len(source))
+	var skipped []string
+	for _, f := range source {
+		t, ok := targetByName[f.Name]
+		if !ok || unrestorableFieldTypes[f.Type] || unrestorableFieldTypes[t.Type] {
+			skipped = append(skipped, f.Name)
+			continue
+		}
+		mapping[f.ID] = t.ID
+	}
+	return mapping, skipped
+}
+
+// remapRecord 按映射改写字段ID，丢弃未映射的字段
+func remapRecord(record sdk.Fields, mapping map[string]string) sdk.Fields {
+	out := make(sdk.Fields, len(record))
+	for id, value := range record {
+		if target, ok := mapping[id]; ok {
+			out[target] = value
+		}
+	}
+	return out
+}
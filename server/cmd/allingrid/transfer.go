@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// defaultImportChunk 每个导入操作包含的记录数
+const defaultImportChunk = 10000
+
+// newExportCmd 导出表记录
+func newExportCmd(opts *globalOptions) *cobra.Command {
+	var (
+		tableID  string
+		outPath  string
+		asOf     string
+		pageSize int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "导出表记录（NDJSON，每行一条记录的字段值）",
+		Long:  "按时间点分页读取表记录，导出期间的写入不会造成重复或遗漏。输出可直接用于 allingrid import",
+		Example: `  allingrid export --table tbl_xxx --out records.ndjson
+  allingrid export --table tbl_xxx --as-of 2026-01-01T00:00:00Z > records.ndjson`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			out := io.Writer(os.Stdout)
+			if outPath != "" {
+				f, err := os.Create(outPath)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			w := bufio.NewWriter(out)
+			enc := json.NewEncoder(w)
+
+			count := 0
+			snapshotAt, err := readTableSnapshot(ctx, client, tableID, asOf, pageSize, func(record *sdk.Record) error {
+				count++
+				return enc.Encode(record.Data)
+			})
+			if err != nil {
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✅ 已导出 %d 条记录（快照时间 %s）\n", count, snapshotAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tableID, "table", "", "表格ID")
+	cmd.Flags().StringVar(&outPath, "out", "", "输出文件（默认标准输出）")
+	cmd.Flags().StringVar(&asOf, "as-of", "", "快照时间点（RFC3339，默认当前时间）")
+	cmd.Flags().IntVar(&pageSize, "page-size", 500, "每页记录数")
+	cmd.MarkFlagRequired("table")
+
+	return cmd
+}
+
+// newImportCmd 导入表记录
+func newImportCmd(opts *globalOptions) *cobra.Command {
+	var (
+		baseID   string
+		tableID  string
+		file     string
+		typecast bool
+		wait     bool
+		chunk    int
+	)
+
+	cmd := &cobra.Command{
+		Use:     "import",
+		Short:   "导入表记录（后台长时操作）",
+		Long:    "读取 NDJSON 或 JSON 数组（每项为按字段ID组织的字段值），按批发起 record_import 操作",
+		Example: `  allingrid import --base bse_xxx --table tbl_xxx --file records.ndjson --typecast --wait`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			records, err := decodeRecords(data)
+			if err != nil {
+				return fmt.Errorf("解析 %s 失败: %w", file, err)
+			}
+
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			ops, err := startImport(ctx, client, baseID, tableID, records, typecast, chunk)
+			if err != nil {
+				return err
+			}
+			if !wait {
+				return opts.print(ops, nil)
+			}
+			return waitAll(ctx, client, ops)
+		},
+	}
+
+	cmd.Flags().StringVar(&baseID, "base", "", "Base ID")
+	cmd.Flags().StringVar(&tableID, "table", "", "表格ID")
+	cmd.Flags().StringVar(&file, "file", "", "记录文件（NDJSON 或 JSON 数组）")
+	cmd.Flags().BoolVar(&typecast, "typecast", false, "按字段类型自动转换值")
+	cmd.Flags().BoolVar(&wait, "wait", false, "等待导入完成")
+	cmd.Flags().IntVar(&chunk, "chunk", defaultImportChunk, "每个导入操作的记录数")
+	cmd.MarkFlagRequired("base")
+	cmd.MarkFlagRequired("table")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// readTableSnapshot 按时间点分页读取整张表，返回实际使用的快照时间
+func readTableSnapshot(ctx context.Context, client *sdk.Client, tableID, asOf string, pageSize int, handle func(*sdk.Record) error) (time.Time, error) {
+	params := &sdk.ListRecordsSnapshotParams{AsOf: asOf, Limit: pageSize}
+	for {
+		page, err := client.ListRecordsSnapshot(ctx, tableID, params)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, record := range page.Records {
+			if err := handle(record); err != nil {
+				return page.AsOf, err
+			}
+		}
+		if page.NextCursor == nil {
+			return page.AsOf, nil
+		}
+		params.AsOf = page.AsOf.Format(time.RFC3339Nano)
+		params.Cursor = *page.NextCursor
+	}
+}
+
+// decodeRecords 解析 JSON 数组或 NDJSON 格式的记录
+func decodeRecords(data []byte) ([]sdk.Fields, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []sdk.Fields
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	var records []sdk.Fields
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var record sdk.Fields
+		if err := dec.Decode(&record); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("第 %d 条记录: %w", len(records)+1, err)
+		}
+		records = append(records, record)
+	}
+}
+
+// startImport 按批发起导入操作
+func startImport(ctx context.Context, client *sdk.Client, baseID, tableID string, records []sdk.Fields, typecast bool, chunk int) ([]*sdk.Operation, error) {
+	if chunk <= 0 {
+		chunk = defaultImportChunk
+	}
+	var ops []*sdk.Operation
+	for start := 0; start < len(records); start += chunk {
+		end := start + chunk
+		if end > len(records) {
+			end = len(records)
+		}
+		params, err := json.Marshal(map[string]interface{}{
+			"table_id": tableID,
+			"records":  records[start:end],
+			"typecast": typecast,
+		})
+		if err != nil {
+			return ops, err
+		}
+		op, err := client.StartOperation(ctx, baseID, &sdk.StartOperationRequest{Type: "record_import", Params: params})
+		if err != nil {
+			return ops, fmt.Errorf("发起导入失败（第 %d-%d 条）: %w", start+1, end, err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// waitAll 依次等待操作完成，任一操作失败或有失败条目时返回错误
+func waitAll(ctx context.Context, client *sdk.Client, ops []*sdk.Operation) error {
+	var failed int64
+	for _, op := range ops {
+		done, err := waitOperation(ctx, client, op)
+		if err != nil {
+			return err
+		}
+		if done.Status != "succeeded" {
+			return fmt.Errorf("操作 %s %s: %s", done.ID, done.Status, done.Error)
+		}
+		for _, item := range done.Errors {
+			fmt.Fprintf(os.Stderr, "  [%s] 第 %d 条: %s\n", done.ID, item.Index+1, item.Message)
+		}
+		failed += done.FailedCount
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条记录导入失败", failed)
+	}
+	return nil
+}
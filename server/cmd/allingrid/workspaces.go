@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/sdk"
+)
+
+// newWorkspacesCmd 工作空间管理命令
+func newWorkspacesCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "workspaces",
+		Aliases: []string{"spaces"},
+		Short:   "管理工作空间",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "列出工作空间",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			spaces, err := client.ListSpaces(ctx)
+			if err != nil {
+				return err
+			}
+			return opts.print(spaces, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\t名称\t描述\t创建时间")
+				for _, s := range spaces {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.ID, s.Name, s.Description, formatTime(&s.CreatedAt))
+				}
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "get <spaceId>",
+		Short: "查看工作空间及其 Base",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			space, err := client.GetSpace(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			bases, err := client.ListBases(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			result := struct {
+				*sdk.Space
+				Bases []*sdk.Base `json:"bases"`
+			}{space, bases}
+			return opts.print(result, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "ID:\t%s\n名称:\t%s\n描述:\t%s\n\n", space.ID, space.Name, space.Description)
+				fmt.Fprintln(w, "Base ID\t名称")
+				for _, b := range bases {
+					fmt.Fprintf(w, "%s\t%s\n", b.ID, b.Name)
+				}
+			})
+		},
+	})
+
+	var description string
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "创建工作空间",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			space, err := client.CreateSpace(ctx, &sdk.CreateSpaceRequest{Name: args[0], Description: description})
+			if err != nil {
+				return err
+			}
+			return opts.print(space, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "✅ 已创建工作空间 %s（%s）\n", space.Name, space.ID)
+			})
+		},
+	}
+	createCmd.Flags().StringVar(&description, "description", "", "描述")
+	cmd.AddCommand(createCmd)
+
+	var yes bool
+	deleteCmd := &cobra.Command{
+		Use:   "delete <spaceId>",
+		Short: "删除工作空间",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !yes {
+				return fmt.Errorf("删除工作空间会删除其中全部数据，确认请加 --yes")
+			}
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			if err := client.DeleteSpace(ctx, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("✅ 工作空间 %s 已删除\n", args[0])
+			return nil
+		},
+	}
+	deleteCmd.Flags().BoolVar(&yes, "yes", false, "确认删除")
+	cmd.AddCommand(deleteCmd)
+
+	return cmd
+}
+
+// newBasesCmd Base 与表格查看命令
+func newBasesCmd(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bases",
+		Short: "查看 Base 与表格",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "tables <baseId>",
+		Short: "列出 Base 下的表格",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := signalContext()
+			defer cancel()
+
+			tables, err := client.ListTables(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			return opts.print(tables, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\t名称\t字段数\t记录数")
+				for _, t := range tables {
+					fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", t.ID, t.Name, t.FieldCount, t.RecordCount)
+				}
+			})
+		},
+	})
+
+	return cmd
+}
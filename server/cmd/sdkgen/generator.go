@@ -0,0 +1,466 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 生成文件名
+const (
+	typesFile      = "types_gen.go"
+	operationsFile = "operations_gen.go"
+)
+
+const generatedHeader = "// Code generated by sdkgen from api/openapi.yaml. DO NOT EDIT.\n\n"
+
+// spec OpenAPI 定义中 SDK 生成所需的子集
+type spec struct {
+	Paths      map[string]map[string]*operation `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Description          string             `yaml:"description"`
+	Nullable             bool               `yaml:"nullable"`
+	Required             []string           `yaml:"required"`
+	Properties           yaml.Node          `yaml:"properties"` // 保留声明顺序
+	Items                *schema            `yaml:"items"`
+	AdditionalProperties *schema            `yaml:"additionalProperties"`
+	GoType               string             `yaml:"x-go-type"`
+	props                map[string]*schema // 由 Properties 解析
+	order                []string
+}
+
+type operation struct {
+	OperationID string      `yaml:"operationId"`
+	Summary     string      `yaml:"summary"`
+	Security    *[]struct{} `yaml:"security"`
+	Parameters  []struct {
+		Name     string  `yaml:"name"`
+		In       string  `yaml:"in"`
+		Required bool    `yaml:"required"`
+		Schema   *schema `yaml:"schema"`
+	} `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"responses"`
+
+	method string
+	path   string
+}
+
+// Generate 根据 OpenAPI 定义生成 SDK 源文件（文件名 -> 内容）
+func Generate(data []byte) (map[string][]byte, error) {
+	var s spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	for _, sc := range s.Components.Schemas {
+		if err := sc.resolveProperties(); err != nil {
+			return nil, err
+		}
+	}
+
+	types, err := generateTypes(&s)
+	if err != nil {
+		return nil, err
+	}
+	operations, err := generateOperations(&s)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{typesFile: types, operationsFile: operations}, nil
+}
+
+// resolveProperties 按声明顺序解析属性（递归处理嵌套结构）
+func (sc *schema) resolveProperties() error {
+	if sc == nil {
+		return nil
+	}
+	if sc.Properties.Kind == yaml.MappingNode {
+		sc.props = make(map[string]*schema)
+		for i := 0; i+1 < len(sc.Properties.Content); i += 2 {
+			name := sc.Properties.Content[i].Value
+			var prop schema
+			if err := sc.Properties.Content[i+1].Decode(&prop); err != nil {
+				return fmt.Errorf("decode property %s: %w", name, err)
+			}
+			if err := prop.resolveProperties(); err != nil {
+				return err
+			}
+			sc.props[name] = &prop
+			sc.order = append(sc.order, name)
+		}
+	}
+	if err := sc.Items.resolveProperties(); err != nil {
+		return err
+	}
+	return sc.AdditionalProperties.resolveProperties()
+}
+
+func generateTypes(s *spec) ([]byte, error) {
+	names := make([]string, 0, len(s.Components.Schemas))
+	for name := range s.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	for _, name := range names {
+		sc := s.Components.Schemas[name]
+		if sc.Type != "object" {
+			return nil, fmt.Errorf("schema %s: only object schemas are supported", name)
+		}
+		description := sc.Description
+		if description == "" {
+			description = "对应 api/openapi.yaml 中的 " + name
+		}
+		writeComment(&body, name, description, "")
+		fmt.Fprintf(&body, "type %s struct {\n", name)
+		required := make(map[string]bool, len(sc.Required))
+		for _, r := range sc.Required {
+			required[r] = true
+		}
+		for _, propName := range sc.order {
+			prop := sc.props[propName]
+			goType, err := goTypeOf(prop, imports)
+			if err != nil {
+				return nil, fmt.Errorf("schema %s.%s: %w", name, propName, err)
+			}
+			tag := propName
+			if !required[propName] {
+				tag += ",omitempty"
+			}
+			if prop.Description != "" {
+				fmt.Fprintf(&body, "\t// %s\n", prop.Description)
+			}
+			fmt.Fprintf(&body, "\t%s %s `json:\"%s\"`\n", exportName(propName), goType, tag)
+		}
+		body.WriteString("}\n\n")
+	}
+
+	var out bytes.Buffer
+	out.WriteString(generatedHeader)
+	out.WriteString("package sdk\n\n")
+	writeImports(&out, imports)
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func generateOperations(s *spec) ([]byte, error) {
+	var ops []*operation
+	for path, methods := range s.Paths {
+		for method, op := range methods {
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s: missing operationId", strings.ToUpper(method), path)
+			}
+			op.method = strings.ToUpper(method)
+			op.path = path
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].OperationID < ops[j].OperationID })
+
+	var body bytes.Buffer
+	imports := map[string]bool{"context": true, "net/http": true}
+	for _, op := range ops {
+		if err := writeOperation(&body, op, imports); err != nil {
+			return nil, fmt.Errorf("operation %s: %w", op.OperationID, err)
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString(generatedHeader)
+	out.WriteString("package sdk\n\n")
+	writeImports(&out, imports)
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
+
+func writeOperation(w *bytes.Buffer, op *operation, imports map[string]bool) error {
+	var pathParams, queryParams []string
+	paramTypes := map[string]string{}
+	for _, p := range op.Parameters {
+		goType, err := goTypeOf(p.Schema, imports)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		paramTypes[p.Name] = goType
+		switch p.In {
+		case "path":
+			if goType != "string" {
+				return fmt.Errorf("path parameter %s must be a string", p.Name)
+			}
+			pathParams = append(pathParams, p.Name)
+		case "query":
+			queryParams = append(queryParams, p.Name)
+		default:
+			return fmt.Errorf("unsupported parameter location %q", p.In)
+		}
+	}
+
+	var requestType string
+	if op.RequestBody != nil {
+		if content, ok := op.RequestBody.Content["application/json"]; ok && content.Schema != nil {
+			requestType = refName(content.Schema.Ref)
+		}
+	}
+	// 响应为 $ref 时返回 *T，为数组时返回 []*T
+	var responseType string
+	if resp, ok := op.Responses["200"]; ok {
+		if content, ok := resp.Content["application/json"]; ok && content.Schema != nil {
+			goType, err := goTypeOf(content.Schema, imports)
+			if err != nil {
+				return fmt.Errorf("response: %w", err)
+			}
+			responseType = goType
+		}
+	}
+	paramsType := op.OperationID + "Params"
+
+	// 查询参数结构体
+	if len(queryParams) > 0 {
+		fmt.Fprintf(w, "// %s %s 的查询参数（零值表示不传）\n", paramsType, op.OperationID)
+		fmt.Fprintf(w, "type %s struct {\n", paramsType)
+		for _, name := range queryParams {
+			fmt.Fprintf(w, "\t%s %s\n", exportName(name), paramTypes[name])
+		}
+		w.WriteString("}\n\n")
+		imports["net/url"] = true
+	}
+
+	// 方法签名
+	args := []string{"ctx context.Context"}
+	for _, name := range pathParams {
+		args = append(args, paramName(name)+" string")
+	}
+	if len(queryParams) > 0 {
+		args = append(args, "params *"+paramsType)
+	}
+	if requestType != "" {
+		args = append(args, "body *"+requestType)
+	}
+	result := "error"
+	if responseType != "" {
+		result = "(" + responseType + ", error)"
+	}
+	writeComment(w, op.OperationID, op.Summary, fmt.Sprintf("%s %s", op.method, op.path))
+	fmt.Fprintf(w, "func (c *Client) %s(%s) %s {\n", op.OperationID, strings.Join(args, ", "), result)
+
+	// 路径
+	path := op.path
+	var pathArgs []string
+	for _, name := range pathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", "%s")
+		pathArgs = append(pathArgs, "url.PathEscape("+paramName(name)+")")
+		imports["net/url"] = true
+	}
+	if len(pathArgs) > 0 {
+		imports["fmt"] = true
+		fmt.Fprintf(w, "\tpath := fmt.Sprintf(%q, %s)\n", path, strings.Join(pathArgs, ", "))
+	} else {
+		fmt.Fprintf(w, "\tpath := %q\n", path)
+	}
+
+	// 查询参数
+	queryArg := "nil"
+	if len(queryParams) > 0 {
+		queryArg = "query"
+		w.WriteString("\tquery := url.Values{}\n\tif params != nil {\n")
+		for _, name := range queryParams {
+			field := "params." + exportName(name)
+			switch paramTypes[name] {
+			case "string":
+				fmt.Fprintf(w, "\t\tif %s != \"\" {\n\t\t\tquery.Set(%q, %s)\n\t\t}\n", field, name, field)
+			case "int":
+				imports["strconv"] = true
+				fmt.Fprintf(w, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.Itoa(%s))\n\t\t}\n", field, name, field)
+			case "int64":
+				imports["strconv"] = true
+				fmt.Fprintf(w, "\t\tif %s != 0 {\n\t\t\tquery.Set(%q, strconv.FormatInt(%s, 10))\n\t\t}\n", field, name, field)
+			case "bool":
+				fmt.Fprintf(w, "\t\tif %s {\n\t\t\tquery.Set(%q, \"true\")\n\t\t}\n", field, name)
+			case "[]string":
+				// 数组按重复的查询参数传递（form 风格，explode）
+				fmt.Fprintf(w, "\t\tfor _, v := range %s {\n\t\t\tquery.Add(%q, v)\n\t\t}\n", field, name)
+			default:
+				return fmt.Errorf("unsupported query parameter type %s", paramTypes[name])
+			}
+		}
+		w.WriteString("\t}\n")
+	}
+
+	fields := []string{"method: http.Method" + methodConst(op.method), "path: path"}
+	if queryArg != "nil" {
+		fields = append(fields, "query: "+queryArg)
+	}
+	if requestType != "" {
+		fields = append(fields, "body: body")
+	}
+	// 未声明 security: [] 的接口需要认证
+	if op.Security == nil || len(*op.Security) > 0 {
+		fields = append(fields, "auth: true")
+	}
+	req := "request{" + strings.Join(fields, ", ") + "}"
+	switch {
+	case strings.HasPrefix(responseType, "*"):
+		fmt.Fprintf(w, "\tvar out %s\n", strings.TrimPrefix(responseType, "*"))
+		fmt.Fprintf(w, "\tif err := c.do(ctx, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n", req)
+	case responseType != "":
+		fmt.Fprintf(w, "\tvar out %s\n", responseType)
+		fmt.Fprintf(w, "\tif err := c.do(ctx, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n\treturn out, nil\n}\n\n", req)
+	default:
+		fmt.Fprintf(w, "\treturn c.do(ctx, %s, nil)\n}\n\n", req)
+	}
+	return nil
+}
+
+// goTypeOf 属性或参数对应的 Go 类型
+func goTypeOf(sc *schema, imports map[string]bool) (string, error) {
+	if sc == nil {
+		return "interface{}", nil
+	}
+	if sc.GoType != "" {
+		if strings.HasPrefix(sc.GoType, "json.") {
+			imports["encoding/json"] = true
+		}
+		return sc.GoType, nil
+	}
+	if sc.Ref != "" {
+		return "*" + refName(sc.Ref), nil
+	}
+	var goType string
+	switch sc.Type {
+	case "string":
+		goType = "string"
+		if sc.Format == "date-time" {
+			imports["time"] = true
+			goType = "time.Time"
+		}
+	case "integer":
+		goType = "int"
+		if sc.Format == "int64" {
+			goType = "int64"
+		}
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		item, err := goTypeOf(sc.Items, imports)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		if sc.AdditionalProperties == nil {
+			return "", fmt.Errorf("inline objects must be declared as component schemas")
+		}
+		value, err := goTypeOf(sc.AdditionalProperties, imports)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + value, nil
+	case "":
+		return "interface{}", nil
+	default:
+		return "", fmt.Errorf("unsupported type %q", sc.Type)
+	}
+	if sc.Nullable {
+		goType = "*" + goType
+	}
+	return goType, nil
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// exportName 将 camelCase / snake_case 名称转换为导出的 Go 标识符（ID、URL 等缩写大写）
+func exportName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	var b strings.Builder
+	for _, part := range parts {
+		// 按 camelCase 再拆分，以识别 tableId 中的 Id
+		start := 0
+		for i, r := range part {
+			if i > 0 && unicode.IsUpper(r) {
+				b.WriteString(exportWord(part[start:i]))
+				start = i
+			}
+		}
+		b.WriteString(exportWord(part[start:]))
+	}
+	return b.String()
+}
+
+// paramName 参数名对应的 Go 变量名（tableId -> tableID）
+func paramName(name string) string {
+	exported := exportName(name)
+	return strings.ToLower(exported[:1]) + exported[1:]
+}
+
+func exportWord(word string) string {
+	switch strings.ToLower(word) {
+	case "id":
+		return "ID"
+	case "url":
+		return "URL"
+	case "yaml":
+		return "YAML"
+	}
+	if word == "" {
+		return ""
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func methodConst(method string) string {
+	return strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+}
+
+func writeComment(w *bytes.Buffer, name, summary, route string) {
+	if summary == "" {
+		fmt.Fprintf(w, "// %s\n", name)
+	} else {
+		fmt.Fprintf(w, "// %s %s\n", name, summary)
+	}
+	if route != "" {
+		fmt.Fprintf(w, "// %s\n", route)
+	}
+}
+
+func writeImports(w *bytes.Buffer, imports map[string]bool) {
+	if len(imports) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	w.WriteString("import (\n")
+	for _, path := range paths {
+		fmt.Fprintf(w, "\t%q\n", path)
+	}
+	w.WriteString(")\n\n")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedSDKUpToDate 确保 pkg/sdk 中的生成文件与 api/openapi.yaml 一致
+func TestGeneratedSDKUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../api/openapi.yaml")
+	if err != nil {
+		t.Fatalf("read spec: %v", err)
+	}
+	files, err := Generate(spec)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join("../../pkg/sdk", name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s is out of date, run `make sdk-generate`", name)
+		}
+	}
+}
+
+func TestExportName(t *testing.T) {
+	cases := map[string]string{
+		"tableId":     "TableID",
+		"accessToken": "AccessToken",
+		"page_size":   "PageSize",
+		"url":         "URL",
+		"yaml":        "YAML",
+	}
+	for in, want := range cases {
+		if got := exportName(in); got != want {
+			t.Errorf("exportName(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := paramName("tableId"); got != "tableID" {
+		t.Errorf("paramName(tableId) = %q", got)
+	}
+}
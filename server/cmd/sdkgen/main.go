@@ -0,0 +1,35 @@
+// sdkgen 根据 api/openapi.yaml 生成 Go SDK（pkg/sdk）中的类型与接口方法
+//
+// 用法: go run ./cmd/sdkgen -spec api/openapi.yaml -out pkg/sdk
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	var (
+		specPath = flag.String("spec", "api/openapi.yaml", "OpenAPI definition")
+		outDir   = flag.String("out", "pkg/sdk", "SDK package directory")
+	)
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	files, err := Generate(data)
+	if err != nil {
+		log.Fatalf("Failed to generate SDK: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(*outDir, name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		log.Printf("generated %s", path)
+	}
+}
@@ -0,0 +1,624 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/adminconsole"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var adminConsoleLog = logger.Named("admin_console")
+
+const (
+	// consoleDefaultPageSize 列表默认条数
+	consoleDefaultPageSize = 50
+	// consoleMaxPageSize 列表最大条数
+	consoleMaxPageSize = 200
+)
+
+// ConsoleLoginRequest 控制台登录请求
+type ConsoleLoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ConsoleLoginResponse 控制台登录响应（令牌明文只返回这一次）
+type ConsoleLoginResponse struct {
+	*adminconsole.Session
+	Token string `json:"token"`
+}
+
+// ConsoleClient 发起控制台请求的客户端
+type ConsoleClient struct {
+	IPAddress string
+	UserAgent string
+}
+
+// ListConsoleSpacesRequest 检索工作空间请求
+type ListConsoleSpacesRequest struct {
+	Query  string `form:"q"`
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// ConsoleSpaceList 工作空间检索结果
+type ConsoleSpaceList struct {
+	Spaces []*adminconsole.SpaceSummary `json:"spaces"`
+	Total  int64                        `json:"total"`
+}
+
+// ListConsoleUsersRequest 检索用户请求
+type ListConsoleUsersRequest struct {
+	Query  string `form:"q"`     // 名称或邮箱
+	Admin  *bool  `form:"admin"` // 只列出（或排除）管理员
+	Limit  int    `form:"limit"`
+	Offset int    `form:"offset"`
+}
+
+// ConsoleUser 控制台中的用户
+type ConsoleUser struct {
+	ID            string     `json:"id"`
+	Email         string     `json:"email"`
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	IsAdmin       bool       `json:"is_admin"`
+	IsSystem      bool       `json:"is_system"`
+	LastSignAt    *time.Time `json:"last_sign_at,omitempty"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ConsoleUserList 用户检索结果
+type ConsoleUserList struct {
+	Users []*ConsoleUser `json:"users"`
+	Total int64          `json:"total"`
+}
+
+// ResetUserPasswordRequest 重置用户密码请求（password 为空时生成临时密码）
+type ResetUserPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// ResetUserPasswordResponse 重置密码结果（临时密码只返回这一次）
+type ResetUserPasswordResponse struct {
+	UserID            string `json:"user_id"`
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+	RevokedSessions   int    `json:"revoked_sessions"`
+}
+
+// TransferSpaceOwnershipRequest 转让工作空间所有权请求
+type TransferSpaceOwnershipRequest struct {
+	NewOwnerID string `json:"newOwnerId" binding:"required"`
+}
+
+// SpaceOwnershipTransfer 所有权转让结果
+type SpaceOwnershipTransfer struct {
+	SpaceID        string   `json:"space_id"`
+	NewOwnerID     string   `json:"new_owner_id"`
+	PreviousOwners []string `json:"previous_owners"`
+}
+
+// SetFeatureRequest 设置功能开关覆盖请求
+type SetFeatureRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ConsoleFeature 功能开关的生效定义及控制台覆盖
+type ConsoleFeature struct {
+	featureflag.Flag
+	Override *adminconsole.FeatureOverride `json:"override,omitempty"`
+}
+
+// AdminConsoleService 管理控制台服务 ✨
+// 面向自托管实例运维人员的独立认证域：管理员使用密码单独登录控制台，控制台令牌（lac_）只能访问
+// /console 接口，普通登录令牌、服务令牌与代入令牌都不能访问控制台；可以限制来源 IP。
+// 提供跨工作空间检索、用户检索与重置密码、转让工作空间所有权、查看工作空间用量、实例统计与
+// 功能开关覆盖，所有变更操作与登录都写入审计日志
+type AdminConsoleService struct {
+	repo      adminconsole.Repository
+	directory adminconsole.Directory
+	userRepo  repository.UserRepository
+	sessions  security.Repository // 用户登录会话（重置密码时吊销，可为空）
+	plans     *PlanService
+	configs   *ConfigService
+	cfg       config.AdminConsoleConfig
+	network   *security.Policy // 来源 IP 限制
+	now       func() time.Time
+}
+
+// NewAdminConsoleService 创建管理控制台服务
+func NewAdminConsoleService(
+	repo adminconsole.Repository,
+	directory adminconsole.Directory,
+	userRepo repository.UserRepository,
+	sessions security.Repository,
+	plans *PlanService,
+	configs *ConfigService,
+	cfg config.AdminConsoleConfig,
+) *AdminConsoleService {
+	if cfg.SessionTTL <= 0 {
+		cfg.SessionTTL = 8 * time.Hour
+	}
+	if cfg.ActiveWindow <= 0 {
+		cfg.ActiveWindow = 30 * 24 * time.Hour
+	}
+	if cfg.FeatureInterval <= 0 {
+		cfg.FeatureInterval = 30 * time.Second
+	}
+	if cfg.LockoutDuration <= 0 {
+		cfg.LockoutDuration = 15 * time.Minute
+	}
+	network := &security.Policy{IPAllowlist: cfg.AllowedIPs}
+	if err := network.Validate(); err != nil {
+		// 配置错误时拒绝所有来源，避免控制台意外对外开放
+		adminConsoleLog.Error(context.Background(), "控制台 IP 白名单无效，已拒绝所有来源", logger.ErrorField(err))
+		network.IPAllowlist = []string{"0.0.0.0/32"}
+	}
+	return &AdminConsoleService{
+		repo:      repo,
+		directory: directory,
+		userRepo:  userRepo,
+		sessions:  sessions,
+		plans:     plans,
+		configs:   configs,
+		cfg:       cfg,
+		network:   network,
+		now:       time.Now,
+	}
+}
+
+// ==================== 认证 ====================
+
+// Login 管理员以邮箱与密码登录控制台
+// 失败时不区分用户不存在、密码错误与非管理员，均写入审计日志；
+// 同一邮箱或来源 IP 在锁定窗口内失败次数达到上限后，不再校验密码直接拒绝
+func (s *AdminConsoleService) Login(ctx context.Context, req ConsoleLoginRequest, client ConsoleClient) (*ConsoleLoginResponse, error) {
+	if err := s.checkAccess(client.IPAddress); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if err := s.checkLockout(ctx, email, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	user, err := s.verifyCredentials(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		s.audit(ctx, &adminconsole.AuditEntry{
+			ActorEmail:   email,
+			Action:       adminconsole.ActionLoginFailed,
+			ResourceType: "console",
+			IPAddress:    client.IPAddress,
+			Failed:       true,
+		})
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("邮箱或密码错误，或账号没有控制台权限")
+	}
+
+	session, token, err := adminconsole.NewSession(user.ID().String(), user.Email().String(),
+		client.IPAddress, client.UserAgent, s.cfg.SessionTTL, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(err.Error())
+	}
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, pkgerrors.Database(err, "创建控制台会话失败")
+	}
+	s.audit(ctx, &adminconsole.AuditEntry{
+		SessionID:    session.ID,
+		ActorID:      session.UserID,
+		ActorEmail:   session.Email,
+		Action:       adminconsole.ActionLogin,
+		ResourceType: "console",
+		IPAddress:    client.IPAddress,
+	})
+
+	adminConsoleLog.Warn(ctx, "管理员登录控制台",
+		logger.String("session_id", session.ID),
+		logger.String("user_id", session.UserID),
+		logger.String("ip", client.IPAddress))
+	return &ConsoleLoginResponse{Session: session, Token: token}, nil
+}
+
+// checkLockout 锁定窗口内登录失败次数是否已达上限（无法统计时拒绝登录）
+func (s *AdminConsoleService) checkLockout(ctx context.Context, email, ip string) error {
+	if s.cfg.MaxFailedLogins <= 0 {
+		return nil
+	}
+	failures, err := s.repo.CountFailedLogins(ctx, email, ip, s.now().Add(-s.cfg.LockoutDuration))
+	if err != nil {
+		return pkgerrors.Database(err, "统计控制台登录失败次数失败")
+	}
+	if failures >= int64(s.cfg.MaxFailedLogins) {
+		adminConsoleLog.Warn(ctx, "控制台登录失败次数过多，已拒绝",
+			logger.String("email", email),
+			logger.String("ip", ip),
+			logger.Int64("failures", failures))
+		return pkgerrors.ErrTooManyRequests.WithDetails(fmt.Sprintf("登录失败次数过多，请 %s 后重试", s.cfg.LockoutDuration))
+	}
+	return nil
+}
+
+// verifyCredentials 校验密码，返回可以登录控制台的管理员（不满足条件时返回 nil）
+func (s *AdminConsoleService) verifyCredentials(ctx context.Context, req ConsoleLoginRequest) (*entity.User, error) {
+	email, err := valueobject.NewEmail(req.Email)
+	if err != nil {
+		return nil, nil
+	}
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if user == nil || !consoleEligible(user) {
+		return nil, nil
+	}
+	password, err := valueobject.NewPassword(req.Password)
+	if err != nil || !user.Password().Verify(password) {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// Authenticate 校验控制台令牌（每次请求都重新确认用户仍是有效的管理员）
+func (s *AdminConsoleService) Authenticate(ctx context.Context, plaintext string, client ConsoleClient) (*adminconsole.Session, error) {
+	if err := s.checkAccess(client.IPAddress); err != nil {
+		return nil, err
+	}
+	if !adminconsole.IsConsoleToken(plaintext) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("需要控制台令牌")
+	}
+
+	session, err := s.repo.FindSessionByHash(ctx, adminconsole.Hash(plaintext))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if session == nil || !session.Active(s.now()) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("控制台会话已结束或已过期")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(session.UserID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if user == nil || !consoleEligible(user) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("控制台会话已失效")
+	}
+	return session, nil
+}
+
+// Logout 注销控制台会话
+func (s *AdminConsoleService) Logout(ctx context.Context, actor *adminconsole.Session) error {
+	if err := s.repo.RevokeSession(ctx, actor.ID, s.now()); err != nil {
+		return pkgerrors.Database(err, "注销控制台会话失败")
+	}
+	s.audit(ctx, s.entry(actor, adminconsole.ActionLogout, "console", ""))
+	return nil
+}
+
+// checkAccess 控制台是否启用，来源 IP 是否允许
+func (s *AdminConsoleService) checkAccess(ip string) error {
+	if !s.cfg.Enabled {
+		return pkgerrors.ErrFeatureNotAvailable.WithDetails("管理控制台未启用")
+	}
+	if !s.network.AllowsIP(ip) {
+		return pkgerrors.ErrForbidden.WithDetails("来源 IP 不允许访问管理控制台")
+	}
+	return nil
+}
+
+// consoleEligible 已激活的管理员（系统用户除外）可以登录控制台
+func consoleEligible(user *entity.User) bool {
+	return user.IsAdmin() && !user.IsSystem() && user.IsActive()
+}
+
+// ==================== 工作空间 ====================
+
+// ListSpaces 跨工作空间检索
+func (s *AdminConsoleService) ListSpaces(ctx context.Context, req ListConsoleSpacesRequest) (*ConsoleSpaceList, error) {
+	limit, offset, err := consolePage(req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	spaces, total, err := s.directory.SearchSpaces(ctx, adminconsole.SpaceFilter{
+		Query:  strings.TrimSpace(req.Query),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, pkgerrors.Database(err, "检索工作空间失败")
+	}
+	return &ConsoleSpaceList{Spaces: spaces, Total: total}, nil
+}
+
+// GetSpaceUsage 工作空间的套餐与资源用量
+func (s *AdminConsoleService) GetSpaceUsage(ctx context.Context, spaceID string) (*plan.Summary, error) {
+	return s.plans.AdminGetUsage(ctx, spaceID)
+}
+
+// TransferSpaceOwnership 将工作空间转让给指定用户，原所有者降为创建者
+func (s *AdminConsoleService) TransferSpaceOwnership(ctx context.Context, actor *adminconsole.Session, spaceID string, req TransferSpaceOwnershipRequest) (*SpaceOwnershipTransfer, error) {
+	if err := s.plans.ensureSpace(ctx, spaceID); err != nil {
+		return nil, err
+	}
+	owner, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(req.NewOwnerID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if owner == nil || owner.IsDeleted() {
+		return nil, pkgerrors.ErrNotFound.WithDetails("新所有者不存在")
+	}
+	if !owner.IsActive() || owner.IsSystem() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("新所有者必须是已激活的普通用户")
+	}
+
+	previous, err := s.directory.SpaceOwners(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取工作空间所有者失败")
+	}
+	if len(previous) == 1 && previous[0] == req.NewOwnerID {
+		return nil, pkgerrors.ErrConflict.WithDetails("该用户已是工作空间的所有者")
+	}
+	if err := s.directory.TransferOwnership(ctx, spaceID, req.NewOwnerID, actor.UserID); err != nil {
+		return nil, pkgerrors.Database(err, "转让工作空间所有权失败")
+	}
+
+	entry := s.entry(actor, adminconsole.ActionTransferOwnership, "space", spaceID)
+	entry.Detail = map[string]interface{}{"new_owner_id": req.NewOwnerID, "previous_owners": previous}
+	s.audit(ctx, entry)
+
+	adminConsoleLog.Warn(ctx, "管理员转让了工作空间所有权",
+		logger.String("space_id", spaceID),
+		logger.String("new_owner_id", req.NewOwnerID),
+		logger.String("actor_id", actor.UserID))
+	return &SpaceOwnershipTransfer{SpaceID: spaceID, NewOwnerID: req.NewOwnerID, PreviousOwners: previous}, nil
+}
+
+// ==================== 用户 ====================
+
+// ListUsers 检索用户
+func (s *AdminConsoleService) ListUsers(ctx context.Context, req ListConsoleUsersRequest) (*ConsoleUserList, error) {
+	limit, offset, err := consolePage(req.Limit, req.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.UserFilter{IsAdmin: req.Admin, Limit: limit, Offset: offset}
+	if q := strings.TrimSpace(req.Query); q != "" {
+		filter.Search = &q
+	}
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "检索用户失败")
+	}
+	list := make([]*ConsoleUser, 0, len(users))
+	for _, u := range users {
+		list = append(list, toConsoleUser(u))
+	}
+	return &ConsoleUserList{Users: list, Total: total}, nil
+}
+
+// ResetUserPassword 重置用户密码并吊销该用户的全部登录会话
+func (s *AdminConsoleService) ResetUserPassword(ctx context.Context, actor *adminconsole.Session, userID string, req ResetUserPasswordRequest) (*ResetUserPasswordResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(userID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if user == nil || user.IsDeleted() {
+		return nil, pkgerrors.ErrNotFound.WithDetails("用户不存在")
+	}
+	if user.IsSystem() {
+		return nil, pkgerrors.ErrForbidden.WithDetails("不能重置系统用户的密码")
+	}
+
+	result := &ResetUserPasswordResponse{UserID: userID}
+	plaintext := req.Password
+	if plaintext == "" {
+		if plaintext, err = adminconsole.GenerateTemporaryPassword(); err != nil {
+			return nil, pkgerrors.ErrInternalServer.WithDetails(err.Error())
+		}
+		result.TemporaryPassword = plaintext
+	}
+	password, err := valueobject.NewPassword(plaintext)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("密码强度不足: " + err.Error())
+	}
+	if err := user.SetPassword(password); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		return nil, pkgerrors.Database(err, "保存用户失败")
+	}
+	result.RevokedSessions = s.revokeUserSessions(ctx, userID)
+
+	entry := s.entry(actor, adminconsole.ActionResetPassword, "user", userID)
+	entry.Detail = map[string]interface{}{
+		"generated":        result.TemporaryPassword != "",
+		"revoked_sessions": result.RevokedSessions,
+	}
+	s.audit(ctx, entry)
+
+	adminConsoleLog.Warn(ctx, "管理员重置了用户密码",
+		logger.String("user_id", userID),
+		logger.String("actor_id", actor.UserID))
+	return result, nil
+}
+
+// revokeUserSessions 吊销用户的全部登录会话，返回吊销的数量
+func (s *AdminConsoleService) revokeUserSessions(ctx context.Context, userID string) int {
+	if s.sessions == nil {
+		return 0
+	}
+	active, err := s.sessions.ListActiveSessions(ctx, userID)
+	if err != nil {
+		adminConsoleLog.Warn(ctx, "获取用户会话失败", logger.String("user_id", userID), logger.ErrorField(err))
+		return 0
+	}
+	revoked := 0
+	for _, session := range active {
+		if err := s.sessions.RevokeSession(ctx, session.ID); err != nil {
+			adminConsoleLog.Warn(ctx, "吊销用户会话失败",
+				logger.String("user_id", userID),
+				logger.String("session_id", session.ID),
+				logger.ErrorField(err))
+			continue
+		}
+		revoked++
+	}
+	return revoked
+}
+
+func toConsoleUser(u *entity.User) *ConsoleUser {
+	return &ConsoleUser{
+		ID:            u.ID().String(),
+		Email:         u.Email().String(),
+		Name:          u.Name(),
+		Status:        u.Status().String(),
+		IsAdmin:       u.IsAdmin(),
+		IsSystem:      u.IsSystem(),
+		LastSignAt:    u.LastSignAt(),
+		DeactivatedAt: u.DeactivatedAt(),
+		CreatedAt:     u.CreatedAt(),
+	}
+}
+
+// ==================== 统计 ====================
+
+// Stats 实例统计
+func (s *AdminConsoleService) Stats(ctx context.Context) (*adminconsole.SystemStats, error) {
+	now := s.now()
+	stats, err := s.directory.Stats(ctx, now.Add(-s.cfg.ActiveWindow))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "统计实例数据失败")
+	}
+	stats.GeneratedAt = now
+	return stats, nil
+}
+
+// ==================== 功能开关 ====================
+
+// ListFeatures 功能开关的生效定义及控制台覆盖
+func (s *AdminConsoleService) ListFeatures(ctx context.Context) ([]*ConsoleFeature, error) {
+	overrides, err := s.repo.ListFeatureOverrides(ctx)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取功能开关覆盖失败")
+	}
+	byKey := make(map[string]*adminconsole.FeatureOverride, len(overrides))
+	for _, o := range overrides {
+		byKey[o.Key] = o
+	}
+
+	flags := s.configs.Features()
+	features := make([]*ConsoleFeature, 0, len(flags))
+	for _, f := range flags {
+		features = append(features, &ConsoleFeature{Flag: f, Override: byKey[f.Key]})
+	}
+	return features, nil
+}
+
+// SetFeature 覆盖功能开关（对所有实例生效，配置热更新后仍然保留）
+func (s *AdminConsoleService) SetFeature(ctx context.Context, actor *adminconsole.Session, key string, req SetFeatureRequest) (*adminconsole.FeatureOverride, error) {
+	override, err := adminconsole.NewFeatureOverride(key, *req.Enabled, actor.UserID, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveFeatureOverride(ctx, override); err != nil {
+		return nil, pkgerrors.Database(err, "保存功能开关覆盖失败")
+	}
+	s.SyncFeatures(ctx)
+
+	entry := s.entry(actor, adminconsole.ActionSetFeature, "feature", key)
+	entry.Detail = map[string]interface{}{"enabled": override.Enabled}
+	s.audit(ctx, entry)
+	return override, nil
+}
+
+// ClearFeature 删除功能开关覆盖，恢复为配置文件的定义
+func (s *AdminConsoleService) ClearFeature(ctx context.Context, actor *adminconsole.Session, key string) error {
+	if err := s.repo.DeleteFeatureOverride(ctx, key); err != nil {
+		return pkgerrors.Database(err, "删除功能开关覆盖失败")
+	}
+	s.SyncFeatures(ctx)
+	s.audit(ctx, s.entry(actor, adminconsole.ActionClearFeature, "feature", key))
+	return nil
+}
+
+// SyncFeatures 从数据库加载功能开关覆盖并应用到本实例
+func (s *AdminConsoleService) SyncFeatures(ctx context.Context) {
+	overrides, err := s.repo.ListFeatureOverrides(ctx)
+	if err != nil {
+		adminConsoleLog.Warn(ctx, "加载功能开关覆盖失败，继续使用当前开关", logger.ErrorField(err))
+		return
+	}
+	values := make(map[string]bool, len(overrides))
+	for _, o := range overrides {
+		values[o.Key] = o.Enabled
+	}
+	s.configs.SetFeatureOverrides(values)
+}
+
+// Start 加载功能开关覆盖，并定期同步其他实例做出的修改
+func (s *AdminConsoleService) Start(ctx context.Context) {
+	s.SyncFeatures(ctx)
+	go func() {
+		ticker := time.NewTicker(s.cfg.FeatureInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.SyncFeatures(ctx)
+		}
+	}()
+}
+
+// ==================== 审计 ====================
+
+func (s *AdminConsoleService) entry(actor *adminconsole.Session, action, resourceType, resourceID string) *adminconsole.AuditEntry {
+	return &adminconsole.AuditEntry{
+		SessionID:    actor.ID,
+		ActorID:      actor.UserID,
+		ActorEmail:   actor.Email,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		IPAddress:    actor.IPAddress,
+	}
+}
+
+// audit 写入审计日志（失败只记录日志，不影响已完成的操作）
+func (s *AdminConsoleService) audit(ctx context.Context, entry *adminconsole.AuditEntry) {
+	entry.OccurredAt = s.now()
+	if err := s.repo.RecordAudit(ctx, entry); err != nil {
+		adminConsoleLog.Error(ctx, "写入控制台审计日志失败",
+			logger.String("action", entry.Action),
+			logger.String("resource_id", entry.ResourceID),
+			logger.ErrorField(err))
+	}
+}
+
+// consolePage 校验分页参数
+func consolePage(limit, offset int) (int, int, error) {
+	if limit < 0 || offset < 0 {
+		return 0, 0, pkgerrors.ErrValidationFailed.WithDetails("limit 与 offset 不能为负数")
+	}
+	if limit == 0 {
+		limit = consoleDefaultPageSize
+	}
+	if limit > consoleMaxPageSize {
+		limit = consoleMaxPageSize
+	}
+	return limit, offset, nil
+}
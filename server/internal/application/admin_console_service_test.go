@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/adminconsole"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+func TestApplyFeatureOverrides(t *testing.T) {
+	flags := []featureflag.Flag{
+		{Key: "realtime", Enabled: true, Rollout: 100},
+		{Key: "gateway", Enabled: true, Rollout: 30, DenySpaces: []string{"spc9"}},
+		{Key: "ai", Enabled: false},
+	}
+	got := applyFeatureOverrides(flags, map[string]bool{"realtime": false, "gateway": true, "beta": true})
+
+	byKey := make(map[string]featureflag.Flag, len(got))
+	keys := make([]string, 0, len(got))
+	for _, f := range got {
+		byKey[f.Key] = f
+		keys = append(keys, f.Key)
+	}
+	if want := []string{"ai", "beta", "gateway", "realtime"}; len(keys) != len(want) || keys[0] != want[0] || keys[3] != want[3] {
+		t.Fatalf("flags should be sorted and include override-only keys, got %v", keys)
+	}
+	if byKey["realtime"].Enabled {
+		t.Errorf("override off should disable the flag")
+	}
+	if g := byKey["gateway"]; !g.Enabled || g.Rollout != 100 || len(g.DenySpaces) != 1 {
+		t.Errorf("override on should roll out fully and keep deny lists, got %+v", g)
+	}
+	if b := byKey["beta"]; !b.Enabled || b.Rollout != 100 {
+		t.Errorf("override-only key should be added enabled, got %+v", b)
+	}
+	if byKey["ai"].Enabled {
+		t.Errorf("flags without overrides should be untouched")
+	}
+
+	if out := applyFeatureOverrides(flags[:1], nil); len(out) != 1 {
+		t.Errorf("no overrides should return the flags unchanged")
+	}
+}
+
+// memoryConsoleAuditRepo 内存中的控制台审计日志
+type memoryConsoleAuditRepo struct {
+	adminconsole.Repository
+	audits []*adminconsole.AuditEntry
+}
+
+func (r *memoryConsoleAuditRepo) RecordAudit(_ context.Context, entry *adminconsole.AuditEntry) error {
+	r.audits = append(r.audits, entry)
+	return nil
+}
+
+func (r *memoryConsoleAuditRepo) CountFailedLogins(_ context.Context, email, ip string, since time.Time) (int64, error) {
+	var count int64
+	for _, entry := range r.audits {
+		if entry.Action == adminconsole.ActionLoginFailed && entry.OccurredAt.After(since) &&
+			(entry.ActorEmail == email || entry.IPAddress == ip) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// noConsoleUserRepo 查找不到任何用户（所有登录都失败）
+type noConsoleUserRepo struct {
+	repository.UserRepository
+	lookups int
+}
+
+func (r *noConsoleUserRepo) FindByEmail(context.Context, valueobject.Email) (*entity.User, error) {
+	r.lookups++
+	return nil, nil
+}
+
+func TestConsoleLoginLocksOutAfterRepeatedFailures(t *testing.T) {
+	logger.Logger = zap.NewNop()
+	now := time.Now()
+	repo := &memoryConsoleAuditRepo{}
+	users := &noConsoleUserRepo{}
+	svc := NewAdminConsoleService(repo, nil, users, nil, nil, nil, config.AdminConsoleConfig{
+		Enabled: true, MaxFailedLogins: 3, LockoutDuration: 15 * time.Minute,
+	})
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+	attacker := ConsoleClient{IPAddress: "203.0.113.5"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Login(ctx, ConsoleLoginRequest{Email: "admin@example.com", Password: "wrong-password"}, attacker); err == nil {
+			t.Fatal("错误的密码应登录失败")
+		}
+	}
+
+	// 同一邮箱（来自其他 IP）与同一 IP（尝试其他邮箱）都被锁定，且不再校验密码
+	lookups := users.lookups
+	for _, attempt := range []struct {
+		email  string
+		client ConsoleClient
+	}{
+		{"Admin@Example.com", ConsoleClient{IPAddress: "198.51.100.7"}},
+		{"other@example.com", attacker},
+	} {
+		_, err := svc.Login(ctx, ConsoleLoginRequest{Email: attempt.email, Password: "wrong-password"}, attempt.client)
+		var appErr *pkgerrors.AppError
+		if !errors.As(err, &appErr) || appErr.Code != pkgerrors.ErrTooManyRequests.Code {
+			t.Fatalf("%s 应被锁定，得到 %v", attempt.email, err)
+		}
+	}
+	if users.lookups != lookups {
+		t.Error("锁定期间不应校验密码")
+	}
+
+	// 较早的失败移出锁定窗口后可以再次尝试
+	now = now.Add(16 * time.Minute)
+	_, err := svc.Login(ctx, ConsoleLoginRequest{Email: "admin@example.com", Password: "wrong-password"}, attacker)
+	var appErr *pkgerrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != pkgerrors.ErrUnauthorized.Code {
+		t.Fatalf("锁定窗口结束后应重新校验密码，得到 %v", err)
+	}
+	if users.lookups != lookups+1 {
+		t.Error("锁定窗口结束后应重新校验密码")
+	}
+}
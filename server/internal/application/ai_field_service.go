@@ -0,0 +1,631 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var aiFieldLog = logger.Named("ai_field")
+
+const (
+	// aiFieldSystemPrompt 生成单元格内容时的系统提示词
+	aiFieldSystemPrompt = "你是数据表中的 AI 字段。请根据指令和记录中的数据生成单元格内容，只输出内容本身，不要添加解释、前缀或 Markdown 代码块。"
+	// aiJobListLimit 任务列表返回条数
+	aiJobListLimit = 100
+	// aiJobCleanupInterval 过期任务清理间隔
+	aiJobCleanupInterval = time.Hour
+)
+
+// aiRecordWriter 写入生成结果（由 RecordService 实现，写入后照常推送事件并重算依赖字段）
+type aiRecordWriter interface {
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// RegenerateAIFieldRequest 重新生成 AI 字段请求（RecordIDs 为空时重新生成整列）
+type RegenerateAIFieldRequest struct {
+	RecordIDs []string `json:"recordIds"`
+}
+
+// RegenerateAIFieldResponse 重新生成 AI 字段响应
+type RegenerateAIFieldResponse struct {
+	FieldID  string `json:"field_id"`
+	Enqueued int    `json:"enqueued"` // 新建的任务数
+	Pending  int    `json:"pending"`  // 已有待执行任务而跳过的单元格数
+}
+
+// UpdateAIQuotaRequest 更新空间 AI 配额请求（0 表示不限制）
+type UpdateAIQuotaRequest struct {
+	MonthlyRequests     int64 `json:"monthlyRequests"`
+	MonthlyTokens       int64 `json:"monthlyTokens"`
+	MaxTokensPerRequest int   `json:"maxTokensPerRequest"`
+}
+
+// AIQuotaResponse 空间 AI 配额与本月用量
+type AIQuotaResponse struct {
+	Quota     *aifield.Quota `json:"quota"`
+	Usage     *aifield.Usage `json:"usage"`
+	Providers []string       `json:"providers"` // 服务端已配置的服务商
+}
+
+// AIFieldService AI 字段生成服务 ✨
+// AI 字段的值由提示词模板生成，模板以 {字段ID} 引用同一记录的其他字段：
+//   - 新建记录、或提示词引用的字段发生变化时，在记录写入的同一事务中为单元格登记生成任务
+//   - 后台按批领取任务，渲染提示词并调用配置的服务商（OpenAI、Azure OpenAI、本地兼容服务），结果写回单元格
+//   - 每个空间按自然月限制生成次数与令牌数，超出配额的任务以失败结束，不调用服务商
+//
+// 提示词不能引用 AI 字段，因此写回生成结果不会再次触发生成
+type AIFieldService struct {
+	repo              aifield.Repository
+	fieldRepo         repository.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           aiRecordWriter
+	providers         map[string]aifield.Provider
+	defaultProvider   string
+	permissionService *PermissionServiceV2
+	resolveSpace      func(ctx context.Context, tableID string) string
+	cfg               config.AIFieldConfig
+	now               func() time.Time
+	wake              chan struct{}
+}
+
+// NewAIFieldService 创建 AI 字段生成服务
+func NewAIFieldService(
+	repo aifield.Repository,
+	fieldRepo repository.FieldRepository,
+	recordRepository recordRepo.RecordRepository,
+	records aiRecordWriter,
+	providers map[string]aifield.Provider,
+	defaultProvider string,
+	securityService *SecurityService,
+	permissionService *PermissionServiceV2,
+	cfg config.AIFieldConfig,
+) *AIFieldService {
+	return &AIFieldService{
+		repo:              repo,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepository,
+		records:           records,
+		providers:         providers,
+		defaultProvider:   defaultProvider,
+		permissionService: permissionService,
+		resolveSpace: func(ctx context.Context, tableID string) string {
+			return securityService.ResolveSpace(ctx, ResourceRefs{TableID: tableID})
+		},
+		cfg:  cfg,
+		now:  time.Now,
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Notify 通知有新任务待执行
+func (s *AIFieldService) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台生成与过期任务清理
+func (s *AIFieldService) Start(ctx context.Context) {
+	if s.cfg.PollInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+		var lastCleanup time.Time
+
+		for {
+			for s.processBatch(ctx) {
+			}
+			if s.cfg.JobRetention > 0 && s.now().Sub(lastCleanup) >= aiJobCleanupInterval {
+				lastCleanup = s.now()
+				if n, err := s.repo.DeleteFinishedBefore(ctx, lastCleanup.Add(-s.cfg.JobRetention)); err != nil {
+					aiFieldLog.Warn(ctx, "清理过期 AI 生成任务失败", logger.ErrorField(err))
+				} else if n > 0 {
+					aiFieldLog.Info(ctx, "已清理过期 AI 生成任务", logger.Int64("count", n))
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// RecordCreated 新建记录后为引用了非空字段的 AI 字段登记生成任务（在记录写入事务中调用）
+func (s *AIFieldService) RecordCreated(ctx context.Context, tableID, recordID string, data map[string]interface{}, userID string) error {
+	return s.enqueueForRecord(ctx, tableID, recordID, aifield.TriggerCreate, userID, func(refs []string) bool {
+		for _, ref := range refs {
+			if aifield.FormatValue(data[ref]) != "" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RecordUpdated 记录更新后为引用了变更字段的 AI 字段登记生成任务（在记录写入事务中调用）
+func (s *AIFieldService) RecordUpdated(ctx context.Context, tableID, recordID string, changedFieldIDs []string, userID string) error {
+	if len(changedFieldIDs) == 0 {
+		return nil
+	}
+	changed := make(map[string]bool, len(changedFieldIDs))
+	for _, id := range changedFieldIDs {
+		changed[id] = true
+	}
+	return s.enqueueForRecord(ctx, tableID, recordID, aifield.TriggerSourceChanged, userID, func(refs []string) bool {
+		for _, ref := range refs {
+			if changed[ref] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *AIFieldService) enqueueForRecord(ctx context.Context, tableID, recordID string, trigger aifield.Trigger, userID string, affected func(refs []string) bool) error {
+	fields, err := s.aiFields(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	var jobs []*aifield.Job
+	for _, field := range fields {
+		refs, err := aifield.References(field.Options().AI.Prompt)
+		if err != nil || !affected(refs) {
+			continue
+		}
+		jobs = append(jobs, aifield.NewJob("", tableID, field.ID().String(), recordID, trigger, userID, now))
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	if _, err := s.repo.Enqueue(ctx, jobs...); err != nil {
+		return pkgerrors.Database(err, "登记 AI 生成任务失败")
+	}
+	s.notifyAfterCommit(ctx)
+	return nil
+}
+
+// aiFields 表中配置了提示词的 AI 字段
+func (s *AIFieldService) aiFields(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	aiType, _ := fieldVO.NewFieldType(fieldVO.TypeAI)
+	fields, err := s.fieldRepo.GetFieldsByType(ctx, tableID, aiType)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询 AI 字段失败")
+	}
+	result := fields[:0]
+	for _, field := range fields {
+		if field.Options() != nil && field.Options().AI != nil && field.Options().AI.Prompt != "" {
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
+// notifyAfterCommit 事务提交后唤醒后台任务（不在事务中时立即唤醒）
+func (s *AIFieldService) notifyAfterCommit(ctx context.Context) {
+	if database.GetTxContext(ctx) != nil {
+		database.AddTxCallback(ctx, s.Notify)
+		return
+	}
+	s.Notify()
+}
+
+// processBatch 领取并执行一批任务，返回是否可能还有待执行的任务
+func (s *AIFieldService) processBatch(ctx context.Context) bool {
+	limit := s.cfg.BatchSize
+	if limit <= 0 {
+		limit = 10
+	}
+	lease := s.cfg.LeaseDuration
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	now := s.now()
+	jobs, err := s.repo.Claim(ctx, now, now.Add(-lease), limit)
+	if err != nil {
+		aiFieldLog.Warn(ctx, "领取 AI 生成任务失败", logger.ErrorField(err))
+		return false
+	}
+
+	concurrency := s.cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	budget := &aiQuotaBudget{service: s, spaces: make(map[string]*aiSpaceBudget)}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(job *aifield.Job) {
+			defer func() { <-sem; wg.Done() }()
+			s.process(ctx, job, budget)
+		}(job)
+	}
+	wg.Wait()
+	return len(jobs) == limit && ctx.Err() == nil
+}
+
+// process 执行单个任务
+func (s *AIFieldService) process(ctx context.Context, job *aifield.Job, budget *aiQuotaBudget) {
+	if job.SpaceID == "" {
+		job.SpaceID = s.resolveSpace(ctx, job.TableID)
+	}
+
+	field, err := s.fieldRepo.FindByID(ctx, fieldVO.NewFieldID(job.FieldID))
+	if err != nil {
+		s.retry(ctx, job, err)
+		return
+	}
+	if field == nil || field.IsDeleted() || field.Type().String() != fieldVO.TypeAI || field.Options() == nil || field.Options().AI == nil {
+		s.fail(ctx, job, errors.New("AI 字段不存在或已修改类型"))
+		return
+	}
+	options := field.Options().AI
+
+	record, err := s.recordRepo.FindByTableAndID(ctx, job.TableID, recordVO.NewRecordID(job.RecordID))
+	if err != nil {
+		s.retry(ctx, job, err)
+		return
+	}
+	if record == nil {
+		s.fail(ctx, job, errors.New("记录不存在"))
+		return
+	}
+
+	values := record.Data().ToMap()
+	refs, err := aifield.References(options.Prompt)
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+	if !hasAnyValue(values, refs) {
+		// 引用的字段均为空时清空单元格，不调用服务商
+		if s.write(ctx, job, nil) {
+			job.Succeed("", &aifield.GenerateResult{}, s.now())
+			s.save(ctx, job)
+		}
+		return
+	}
+	prompt, err := aifield.RenderPrompt(options.Prompt, values)
+	if err != nil {
+		s.fail(ctx, job, err)
+		return
+	}
+
+	providerName := options.Provider
+	if providerName == "" {
+		providerName = s.defaultProvider
+	}
+	provider := s.providers[providerName]
+	if provider == nil {
+		s.fail(ctx, job, fmt.Errorf("未配置 AI 服务商 %s", providerName))
+		return
+	}
+
+	quota, err := budget.reserve(ctx, job.SpaceID)
+	if err != nil {
+		if errors.Is(err, aifield.ErrQuotaExceeded) {
+			s.fail(ctx, job, err)
+		} else {
+			s.retry(ctx, job, err)
+		}
+		return
+	}
+
+	genCtx := ctx
+	if s.cfg.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		genCtx, cancel = context.WithTimeout(ctx, s.cfg.RequestTimeout)
+		defer cancel()
+	}
+	result, err := provider.Generate(genCtx, &aifield.GenerateRequest{
+		Model:        options.Model,
+		SystemPrompt: aiFieldSystemPrompt,
+		Prompt:       prompt,
+		MaxTokens:    minPositive(options.MaxTokens(), quota.MaxTokensPerRequest, s.cfg.MaxOutputTokens),
+		Temperature:  options.Temperature(),
+	})
+	if err != nil {
+		budget.release(job.SpaceID)
+		aiFieldLog.Warn(ctx, "AI 生成失败",
+			logger.String("job_id", job.ID),
+			logger.String("field_id", job.FieldID),
+			logger.String("provider", providerName),
+			logger.ErrorField(err))
+		if aifield.IsPermanent(err) {
+			s.fail(ctx, job, err)
+		} else {
+			s.retry(ctx, job, err)
+		}
+		return
+	}
+	budget.consume(job.SpaceID, result.TotalTokens())
+
+	// 令牌已消耗：写回失败时保留服务商与用量，计入配额
+	job.Succeed(providerName, result, s.now())
+	if !s.write(ctx, job, result.Text) {
+		job.Fail(errors.New("写入生成结果失败"), s.now())
+	}
+	s.save(ctx, job)
+}
+
+// write 将生成结果写回单元格
+func (s *AIFieldService) write(ctx context.Context, job *aifield.Job, value interface{}) bool {
+	_, err := s.records.UpdateRecord(ctx, job.TableID, job.RecordID, dto.UpdateRecordRequest{
+		Data: map[string]interface{}{job.FieldID: value},
+	}, job.CreatedBy)
+	if err != nil {
+		aiFieldLog.Warn(ctx, "写入 AI 生成结果失败",
+			logger.String("job_id", job.ID),
+			logger.String("record_id", job.RecordID),
+			logger.ErrorField(err))
+		return false
+	}
+	return true
+}
+
+// retry 安排重试，次数耗尽时以失败结束
+func (s *AIFieldService) retry(ctx context.Context, job *aifield.Job, err error) {
+	maxAttempts := s.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	if !job.Retry(err, maxAttempts, s.now()) {
+		job.Fail(err, s.now())
+	}
+	s.save(ctx, job)
+}
+
+// fail 以失败结束任务
+func (s *AIFieldService) fail(ctx context.Context, job *aifield.Job, err error) {
+	job.Fail(err, s.now())
+	s.save(ctx, job)
+}
+
+func (s *AIFieldService) save(ctx context.Context, job *aifield.Job) {
+	if err := s.repo.Save(ctx, job); err != nil {
+		aiFieldLog.Warn(ctx, "保存 AI 生成任务失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+}
+
+// Regenerate 手动重新生成 AI 字段（需要表格的记录编辑权限）
+func (s *AIFieldService) Regenerate(ctx context.Context, userID, fieldID string, req RegenerateAIFieldRequest) (*RegenerateAIFieldResponse, error) {
+	field, err := s.aiField(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	tableID := field.TableID()
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有编辑该表记录的权限")
+	}
+
+	recordIDs := req.RecordIDs
+	max := s.cfg.MaxRegenerateRecords
+	if max <= 0 {
+		max = 5000
+	}
+	if len(recordIDs) == 0 {
+		records, total, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{TableID: &tableID, Limit: max})
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询记录失败")
+		}
+		if total > int64(max) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("表中有 %d 条记录，整列重新生成最多 %d 条，请通过 recordIds 分批指定", total, max))
+		}
+		for _, record := range records {
+			recordIDs = append(recordIDs, record.ID().String())
+		}
+	} else if len(recordIDs) > max {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多重新生成 %d 条记录", max))
+	}
+
+	spaceID := s.resolveSpace(ctx, tableID)
+	now := s.now()
+	jobs := make([]*aifield.Job, 0, len(recordIDs))
+	for _, recordID := range recordIDs {
+		jobs = append(jobs, aifield.NewJob(spaceID, tableID, fieldID, recordID, aifield.TriggerManual, userID, now))
+	}
+	created, err := s.repo.Enqueue(ctx, jobs...)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "登记 AI 生成任务失败")
+	}
+	s.Notify()
+	return &RegenerateAIFieldResponse{FieldID: fieldID, Enqueued: created, Pending: len(jobs) - created}, nil
+}
+
+// ListJobs 列出 AI 字段最近的生成任务（recordID 非空时只列该记录）
+func (s *AIFieldService) ListJobs(ctx context.Context, userID, fieldID, recordID string) ([]*aifield.Job, error) {
+	field, err := s.aiField(ctx, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessTable(ctx, userID, field.TableID()) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	jobs, err := s.repo.ListByField(ctx, fieldID, recordID, aiJobListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return jobs, nil
+}
+
+// GetQuota 获取空间 AI 配额与本月用量（仅空间所有者）
+func (s *AIFieldService) GetQuota(ctx context.Context, userID, spaceID string) (*AIQuotaResponse, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	return s.quotaResponse(ctx, spaceID)
+}
+
+// UpdateQuota 更新空间 AI 配额（仅空间所有者）
+func (s *AIFieldService) UpdateQuota(ctx context.Context, userID, spaceID string, req UpdateAIQuotaRequest) (*AIQuotaResponse, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	quota := &aifield.Quota{
+		SpaceID:             spaceID,
+		MonthlyRequests:     req.MonthlyRequests,
+		MonthlyTokens:       req.MonthlyTokens,
+		MaxTokensPerRequest: req.MaxTokensPerRequest,
+		UpdatedBy:           userID,
+		UpdatedTime:         s.now(),
+	}
+	if err := quota.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveQuota(ctx, quota); err != nil {
+		return nil, pkgerrors.Database(err, "保存 AI 配额失败")
+	}
+	return s.quotaResponse(ctx, spaceID)
+}
+
+func (s *AIFieldService) quotaResponse(ctx context.Context, spaceID string) (*AIQuotaResponse, error) {
+	quota, err := s.quota(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	usage, err := s.repo.MonthlyUsage(ctx, spaceID, aifield.MonthStart(s.now()))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	providers := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	return &AIQuotaResponse{Quota: quota, Usage: usage, Providers: providers}, nil
+}
+
+// quota 获取空间配额，未设置时使用配置中的默认配额
+func (s *AIFieldService) quota(ctx context.Context, spaceID string) (*aifield.Quota, error) {
+	quota, err := s.repo.GetQuota(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		quota = &aifield.Quota{
+			SpaceID:         spaceID,
+			MonthlyRequests: s.cfg.DefaultMonthlyRequests,
+			MonthlyTokens:   s.cfg.DefaultMonthlyTokens,
+		}
+	}
+	return quota, nil
+}
+
+// aiField 获取 AI 字段
+func (s *AIFieldService) aiField(ctx context.Context, fieldID string) (*fieldEntity.Field, error) {
+	field, err := s.fieldRepo.FindByID(ctx, fieldVO.NewFieldID(fieldID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找字段失败")
+	}
+	if field == nil || field.IsDeleted() {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+	if field.Type().String() != fieldVO.TypeAI {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("只能重新生成 AI 字段")
+	}
+	return field, nil
+}
+
+func (s *AIFieldService) ensureOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以管理 AI 配额")
+	}
+	return nil
+}
+
+// aiQuotaBudget 一批任务内各空间的配额与用量（并发执行时共享）
+// 调用服务商前预占一次生成次数，失败时归还，成功后累加令牌数
+type aiQuotaBudget struct {
+	service *AIFieldService
+	mu      sync.Mutex
+	spaces  map[string]*aiSpaceBudget
+}
+
+type aiSpaceBudget struct {
+	quota *aifield.Quota
+	usage *aifield.Usage
+}
+
+func (b *aiQuotaBudget) reserve(ctx context.Context, spaceID string) (*aifield.Quota, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	space := b.spaces[spaceID]
+	if space == nil {
+		quota, err := b.service.quota(ctx, spaceID)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := b.service.repo.MonthlyUsage(ctx, spaceID, aifield.MonthStart(b.service.now()))
+		if err != nil {
+			return nil, err
+		}
+		space = &aiSpaceBudget{quota: quota, usage: usage}
+		b.spaces[spaceID] = space
+	}
+	if err := space.quota.Check(space.usage); err != nil {
+		return nil, err
+	}
+	space.usage.Requests++
+	return space.quota, nil
+}
+
+func (b *aiQuotaBudget) release(spaceID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spaces[spaceID].usage.Requests--
+}
+
+func (b *aiQuotaBudget) consume(spaceID string, tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spaces[spaceID].usage.Tokens += int64(tokens)
+}
+
+func hasAnyValue(values map[string]interface{}, refs []string) bool {
+	for _, ref := range refs {
+		if aifield.FormatValue(values[ref]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// minPositive 取正数中的最小值，均不为正时返回 0
+func minPositive(values ...int) int {
+	result := 0
+	for _, v := range values {
+		if v > 0 && (result == 0 || v < result) {
+			result = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,341 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+)
+
+// memoryAIJobRepo 内存实现的生成任务仓储
+type memoryAIJobRepo struct {
+	mu     sync.Mutex
+	jobs   []*aifield.Job
+	quotas map[string]*aifield.Quota
+}
+
+func (r *memoryAIJobRepo) Enqueue(_ context.Context, jobs ...*aifield.Job) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := 0
+	for _, job := range jobs {
+		if r.find(job.FieldID, job.RecordID, aifield.StatusPending) != nil {
+			continue
+		}
+		copied := *job
+		r.jobs = append(r.jobs, &copied)
+		created++
+	}
+	return created, nil
+}
+
+func (r *memoryAIJobRepo) find(fieldID, recordID string, status aifield.Status) *aifield.Job {
+	for _, job := range r.jobs {
+		if job.FieldID == fieldID && job.RecordID == recordID && job.Status == status {
+			return job
+		}
+	}
+	return nil
+}
+
+func (r *memoryAIJobRepo) Claim(_ context.Context, now, _ time.Time, limit int) ([]*aifield.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []*aifield.Job
+	for _, job := range r.jobs {
+		if job.Status == aifield.StatusPending && !job.NextRunAt.After(now) && len(claimed) < limit {
+			job.Status = aifield.StatusRunning
+			job.Attempts++
+			copied := *job
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryAIJobRepo) Save(_ context.Context, job *aifield.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.jobs {
+		if existing.ID == job.ID {
+			copied := *job
+			r.jobs[i] = &copied
+		}
+	}
+	return nil
+}
+
+func (r *memoryAIJobRepo) ListByField(_ context.Context, fieldID, recordID string, _ int) ([]*aifield.Job, error) {
+	var list []*aifield.Job
+	for _, job := range r.jobs {
+		if job.FieldID == fieldID && (recordID == "" || job.RecordID == recordID) {
+			list = append(list, job)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryAIJobRepo) DeleteFinishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memoryAIJobRepo) MonthlyUsage(_ context.Context, spaceID string, since time.Time) (*aifield.Usage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := &aifield.Usage{PeriodStart: since}
+	for _, job := range r.jobs {
+		if job.SpaceID == spaceID && job.Provider != "" && job.FinishedAt != nil && !job.FinishedAt.Before(since) {
+			usage.Add(job.TotalTokens())
+		}
+	}
+	return usage, nil
+}
+
+func (r *memoryAIJobRepo) GetQuota(_ context.Context, spaceID string) (*aifield.Quota, error) {
+	return r.quotas[spaceID], nil
+}
+
+func (r *memoryAIJobRepo) SaveQuota(_ context.Context, quota *aifield.Quota) error {
+	r.quotas[quota.SpaceID] = quota
+	return nil
+}
+
+// stubAIFieldRepo 只实现 AI 字段服务用到的查询
+type stubAIFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields []*fieldEntity.Field
+}
+
+func (r *stubAIFieldRepo) GetFieldsByType(_ context.Context, _ string, fieldType fieldVO.FieldType) ([]*fieldEntity.Field, error) {
+	var list []*fieldEntity.Field
+	for _, field := range r.fields {
+		if field.Type().String() == fieldType.String() {
+			list = append(list, field)
+		}
+	}
+	return list, nil
+}
+
+func (r *stubAIFieldRepo) FindByID(_ context.Context, id fieldVO.FieldID) (*fieldEntity.Field, error) {
+	for _, field := range r.fields {
+		if field.ID().String() == id.String() {
+			return field, nil
+		}
+	}
+	return nil, nil
+}
+
+// stubAIRecordRepo 按ID返回记录
+type stubAIRecordRepo struct {
+	recordRepo.RecordRepository
+	records map[string]*recordEntity.Record
+}
+
+func (r *stubAIRecordRepo) FindByTableAndID(_ context.Context, _ string, id recordVO.RecordID) (*recordEntity.Record, error) {
+	return r.records[id.String()], nil
+}
+
+// recordingAIWriter 记录写回的单元格值
+type recordingAIWriter struct {
+	mu     sync.Mutex
+	writes map[string]interface{} // recordID -> 写入的数据
+}
+
+func (w *recordingAIWriter) UpdateRecord(_ context.Context, _, recordID string, req dto.UpdateRecordRequest, _ string) (*dto.RecordResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes[recordID] = req.Data
+	return &dto.RecordResponse{ID: recordID}, nil
+}
+
+// stubAIProvider 返回固定结果或错误，并记录收到的提示词
+type stubAIProvider struct {
+	mu      sync.Mutex
+	err     error
+	prompts []string
+}
+
+func (p *stubAIProvider) Name() string { return "stub" }
+
+func (p *stubAIProvider) Generate(_ context.Context, req *aifield.GenerateRequest) (*aifield.GenerateResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompts = append(p.prompts, req.Prompt)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &aifield.GenerateResult{Text: "摘要", Model: "stub-model", PromptTokens: 10, CompletionTokens: 5}, nil
+}
+
+type aiFieldFixture struct {
+	service  *AIFieldService
+	jobs     *memoryAIJobRepo
+	writer   *recordingAIWriter
+	provider *stubAIProvider
+	title    *fieldEntity.Field
+	summary  *fieldEntity.Field
+}
+
+func newAIFieldFixture(t *testing.T, recordTitles map[string]string) *aiFieldFixture {
+	t.Helper()
+	newField := func(fieldName, fieldType string) *fieldEntity.Field {
+		name, _ := fieldVO.NewFieldName(fieldName)
+		ft, _ := fieldVO.NewFieldType(fieldType)
+		field, err := fieldEntity.NewField("tbl1", name, ft, "usr1")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		return field
+	}
+	title := newField("标题", fieldVO.TypeText)
+	summary := newField("摘要", fieldVO.TypeAI)
+	options := fieldVO.NewFieldOptions().WithAI("stub", "", "总结：{"+title.ID().String()+"}")
+	if err := summary.UpdateOptions(options); err != nil {
+		t.Fatalf("设置字段选项失败: %v", err)
+	}
+
+	records := make(map[string]*recordEntity.Record)
+	for id, text := range recordTitles {
+		data, _ := recordVO.NewRecordData(map[string]interface{}{title.ID().String(): text})
+		records[id] = recordEntity.ReconstructRecord(recordVO.NewRecordID(id), "tbl1", data, recordVO.InitialVersion(), "usr1", "usr1", time.Now(), time.Now(), nil)
+	}
+
+	fx := &aiFieldFixture{
+		jobs:     &memoryAIJobRepo{quotas: make(map[string]*aifield.Quota)},
+		writer:   &recordingAIWriter{writes: make(map[string]interface{})},
+		provider: &stubAIProvider{},
+		title:    title,
+		summary:  summary,
+	}
+	fx.service = NewAIFieldService(
+		fx.jobs,
+		&stubAIFieldRepo{fields: []*fieldEntity.Field{title, summary}},
+		&stubAIRecordRepo{records: records},
+		fx.writer,
+		map[string]aifield.Provider{"stub": fx.provider},
+		"stub",
+		nil, nil,
+		config.AIFieldConfig{BatchSize: 10, Concurrency: 1, MaxAttempts: 3},
+	)
+	fx.service.resolveSpace = func(context.Context, string) string { return "spc1" }
+	return fx
+}
+
+func TestAIFieldEnqueueOnSourceChangeAndGenerate(t *testing.T) {
+	fx := newAIFieldFixture(t, map[string]string{"rec1": "季度报告"})
+	ctx := context.Background()
+
+	if err := fx.service.RecordUpdated(ctx, "tbl1", "rec1", []string{"fld_other"}, "usr1"); err != nil || len(fx.jobs.jobs) != 0 {
+		t.Fatalf("未引用的字段变化不应登记任务: %d, %v", len(fx.jobs.jobs), err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := fx.service.RecordUpdated(ctx, "tbl1", "rec1", []string{fx.title.ID().String()}, "usr1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fx.jobs.jobs) != 1 {
+		t.Fatalf("同一单元格只应保留一个待执行任务，得到 %d", len(fx.jobs.jobs))
+	}
+
+	fx.service.processBatch(ctx)
+
+	job := fx.jobs.jobs[0]
+	if job.Status != aifield.StatusSucceeded || job.SpaceID != "spc1" || job.Provider != "stub" || job.TotalTokens() != 15 {
+		t.Errorf("任务 = %+v", job)
+	}
+	if len(fx.provider.prompts) != 1 || fx.provider.prompts[0] != "总结：季度报告" {
+		t.Errorf("提示词 = %v", fx.provider.prompts)
+	}
+	data, _ := fx.writer.writes["rec1"].(map[string]interface{})
+	if data[fx.summary.ID().String()] != "摘要" {
+		t.Errorf("写回的数据 = %v", fx.writer.writes["rec1"])
+	}
+}
+
+func TestAIFieldEmptySourceClearsWithoutProvider(t *testing.T) {
+	fx := newAIFieldFixture(t, map[string]string{"rec1": ""})
+	ctx := context.Background()
+
+	if _, err := fx.jobs.Enqueue(ctx, aifield.NewJob("spc1", "tbl1", fx.summary.ID().String(), "rec1", aifield.TriggerManual, "usr1", time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	fx.service.processBatch(ctx)
+
+	if len(fx.provider.prompts) != 0 {
+		t.Error("引用字段为空时不应调用服务商")
+	}
+	if job := fx.jobs.jobs[0]; job.Status != aifield.StatusSucceeded || job.Provider != "" {
+		t.Errorf("任务 = %+v", job)
+	}
+	usage, _ := fx.jobs.MonthlyUsage(ctx, "spc1", aifield.MonthStart(time.Now()))
+	if usage.Requests != 0 {
+		t.Errorf("清空单元格不应计入用量: %+v", usage)
+	}
+}
+
+func TestAIFieldQuotaExceeded(t *testing.T) {
+	fx := newAIFieldFixture(t, map[string]string{"rec1": "a", "rec2": "b"})
+	ctx := context.Background()
+	fx.jobs.quotas["spc1"] = &aifield.Quota{SpaceID: "spc1", MonthlyRequests: 1}
+
+	for _, id := range []string{"rec1", "rec2"} {
+		if err := fx.service.RecordCreated(ctx, "tbl1", id, map[string]interface{}{fx.title.ID().String(): "x"}, "usr1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fx.service.processBatch(ctx)
+
+	if len(fx.provider.prompts) != 1 {
+		t.Fatalf("超出配额后不应调用服务商，调用了 %d 次", len(fx.provider.prompts))
+	}
+	var failed *aifield.Job
+	for _, job := range fx.jobs.jobs {
+		if job.Status == aifield.StatusFailed {
+			failed = job
+		}
+	}
+	if failed == nil || !strings.Contains(failed.Error, aifield.ErrQuotaExceeded.Error()) {
+		t.Errorf("第二个任务应因配额不足失败: %+v", fx.jobs.jobs)
+	}
+}
+
+func TestAIFieldProviderErrors(t *testing.T) {
+	fx := newAIFieldFixture(t, map[string]string{"rec1": "a"})
+	ctx := context.Background()
+	enqueue := func() {
+		if _, err := fx.jobs.Enqueue(ctx, aifield.NewJob("spc1", "tbl1", fx.summary.ID().String(), "rec1", aifield.TriggerManual, "usr1", time.Now())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fx.provider.err = errors.New("503 service unavailable")
+	enqueue()
+	fx.service.processBatch(ctx)
+	job := fx.jobs.jobs[0]
+	if job.Status != aifield.StatusPending || !job.NextRunAt.After(time.Now()) {
+		t.Errorf("可重试错误应安排退避重试: %+v", job)
+	}
+
+	fx.jobs.jobs = nil
+	fx.provider.err = aifield.Permanent(errors.New("401 invalid api key"))
+	enqueue()
+	fx.service.processBatch(ctx)
+	if job := fx.jobs.jobs[0]; job.Status != aifield.StatusFailed || job.Attempts != 1 {
+		t.Errorf("不可重试错误应直接失败: %+v", job)
+	}
+	if len(fx.writer.writes) != 0 {
+		t.Error("生成失败时不应写回单元格")
+	}
+}
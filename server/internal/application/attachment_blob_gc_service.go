@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var attachmentBlobGCLog = logger.Named("attachment_blob_gc")
+
+// AttachmentBlobGCService 回收无引用的附件内容 ✨
+// 附件按 SHA-256 内容寻址存储，多个附件共用一份文件；引用数降为 0 并超过宽限期后删除文件与记录
+type AttachmentBlobGCService struct {
+	repo    attachment.BlobRepository
+	storage attachment.Storage
+	cfg     config.AttachmentDedupConfig
+	now     func() time.Time
+}
+
+// NewAttachmentBlobGCService 创建附件内容回收服务
+func NewAttachmentBlobGCService(repo attachment.BlobRepository, storage attachment.Storage, cfg config.AttachmentDedupConfig) *AttachmentBlobGCService {
+	return &AttachmentBlobGCService{
+		repo:    repo,
+		storage: storage,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// Start 启动定期回收
+func (s *AttachmentBlobGCService) Start(ctx context.Context) {
+	if s.cfg.GCInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.GCInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.Collect(ctx)
+			}
+		}
+	}()
+}
+
+// Collect 分批回收超过宽限期的无引用内容，返回回收的数量与释放的字节数
+func (s *AttachmentBlobGCService) Collect(ctx context.Context) (int, int64) {
+	limit := s.cfg.GCBatchSize
+	if limit <= 0 {
+		limit = 100
+	}
+	before := s.now().Add(-s.cfg.GracePeriod)
+
+	var count int
+	var freed int64
+	for ctx.Err() == nil {
+		failed := 0
+		collected, err := s.repo.CollectUnreferenced(ctx, before, limit, func(blob *attachment.Blob) error {
+			if err := s.storage.Delete(ctx, attachment.BlobPath(blob.Hash)); err != nil {
+				failed++
+				attachmentBlobGCLog.Warn(ctx, "删除附件内容失败", logger.String("hash", blob.Hash), logger.ErrorField(err))
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			attachmentBlobGCLog.Warn(ctx, "回收附件内容失败", logger.ErrorField(err))
+			break
+		}
+		for _, blob := range collected {
+			freed += blob.Size
+		}
+		count += len(collected)
+		// 本批未满或全部失败时结束，避免反复领取同一批
+		if len(collected)+failed < limit || len(collected) == 0 {
+			break
+		}
+	}
+
+	if count > 0 {
+		attachmentBlobGCLog.Info(ctx, "已回收无引用的附件内容", logger.Int("count", count), logger.Int64("bytes", freed))
+	}
+	return count, freed
+}
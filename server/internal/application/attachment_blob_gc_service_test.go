@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+)
+
+// memoryBlobRepo 内存引用计数，refs 模拟附件表中的实际引用
+type memoryBlobRepo struct {
+	blobs map[string]*attachment.Blob
+	refs  map[string]int
+}
+
+func (r *memoryBlobRepo) Acquire(_ context.Context, hash string, size int64, mimeType string) (bool, error) {
+	if blob, ok := r.blobs[hash]; ok {
+		blob.RefCount++
+		blob.UnreferencedSince = nil
+		return false, nil
+	}
+	r.blobs[hash] = &attachment.Blob{Hash: hash, Size: size, MimeType: mimeType, RefCount: 1}
+	return true, nil
+}
+
+func (r *memoryBlobRepo) Release(_ context.Context, hash string) error {
+	if blob, ok := r.blobs[hash]; ok && blob.RefCount > 0 {
+		blob.RefCount--
+		if blob.RefCount == 0 {
+			now := time.Now()
+			blob.UnreferencedSince = &now
+		}
+	}
+	return nil
+}
+
+func (r *memoryBlobRepo) CollectUnreferenced(_ context.Context, before time.Time, limit int, remove func(*attachment.Blob) error) ([]*attachment.Blob, error) {
+	var collected []*attachment.Blob
+	seen := 0
+	for hash, blob := range r.blobs {
+		if blob.RefCount != 0 || blob.UnreferencedSince == nil || !blob.UnreferencedSince.Before(before) || seen >= limit {
+			continue
+		}
+		seen++
+		if r.refs[hash] > 0 {
+			blob.RefCount = r.refs[hash]
+			blob.UnreferencedSince = nil
+			continue
+		}
+		if remove(blob) != nil {
+			continue
+		}
+		delete(r.blobs, hash)
+		collected = append(collected, blob)
+	}
+	return collected, nil
+}
+
+// deletingStorage 记录删除的路径，failing 中的路径删除失败
+type deletingStorage struct {
+	attachment.Storage
+	deleted []string
+	failing map[string]bool
+}
+
+func (s *deletingStorage) Delete(_ context.Context, path string) error {
+	if s.failing[path] {
+		return errors.New("storage unavailable")
+	}
+	s.deleted = append(s.deleted, path)
+	return nil
+}
+
+func TestAttachmentBlobGCCollectsAfterGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryBlobRepo{blobs: map[string]*attachment.Blob{}, refs: map[string]int{}}
+	for _, hash := range []string{"aaaa01", "bbbb02", "cccc03", "dddd04"} {
+		if _, err := repo.Acquire(ctx, hash, 10, "text/plain"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 第二次上传相同内容只增加引用
+	if created, _ := repo.Acquire(ctx, "aaaa01", 10, "text/plain"); created {
+		t.Fatal("相同内容不应重复创建")
+	}
+	for _, hash := range []string{"aaaa01", "bbbb02", "cccc03", "dddd04"} {
+		_ = repo.Release(ctx, hash)
+	}
+	repo.refs["cccc03"] = 1 // 计数偏小但仍有附件引用
+
+	storage := &deletingStorage{failing: map[string]bool{attachment.BlobPath("dddd04"): true}}
+	svc := NewAttachmentBlobGCService(repo, storage, config.AttachmentDedupConfig{GracePeriod: time.Hour, GCBatchSize: 10})
+
+	if count, _ := svc.Collect(ctx); count != 0 {
+		t.Fatalf("宽限期内不应回收，回收了 %d 个", count)
+	}
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	count, freed := svc.Collect(ctx)
+	if count != 1 || freed != 10 {
+		t.Fatalf("期望回收 1 个（10 字节），得到 %d 个（%d 字节）", count, freed)
+	}
+	if len(storage.deleted) != 1 || storage.deleted[0] != attachment.BlobPath("bbbb02") {
+		t.Errorf("删除的文件错误: %v", storage.deleted)
+	}
+	if blob := repo.blobs["aaaa01"]; blob == nil || blob.RefCount != 1 {
+		t.Error("仍有引用的内容不应回收")
+	}
+	if blob := repo.blobs["cccc03"]; blob == nil || blob.RefCount != 1 || blob.UnreferencedSince != nil {
+		t.Errorf("引用计数应按附件表修正: %+v", blob)
+	}
+	if repo.blobs["dddd04"] == nil {
+		t.Error("文件删除失败时应保留记录以便重试")
+	}
+}
@@ -0,0 +1,407 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachmentscan"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+var attachmentScanLog = logger.Named("attachment_scan")
+
+const (
+	// attachmentInfectedEvent 感染事件名
+	attachmentInfectedEvent = "attachment.infected"
+	// attachmentWebhookTimeout 感染事件推送超时
+	attachmentWebhookTimeout = 15 * time.Second
+)
+
+// UpdateAttachmentPolicyRequest 更新空间附件策略请求
+type UpdateAttachmentPolicyRequest struct {
+	MaxFileSizeMB     int      `json:"maxFileSizeMb"`
+	AllowedTypes      []string `json:"allowedTypes"`
+	BlockedExtensions []string `json:"blockedExtensions"`
+	ScanRequired      bool     `json:"scanRequired"`
+	WebhookURL        string   `json:"webhookUrl"`
+}
+
+// AttachmentPolicyResponse 空间附件策略响应
+type AttachmentPolicyResponse struct {
+	*attachmentscan.Policy
+	ScanEngine    string `json:"scan_engine"`              // 服务端配置的扫描引擎，none 表示只做类型校验
+	WebhookSecret string `json:"webhook_secret,omitempty"` // 感染事件签名密钥，仅首次设置推送地址时返回
+}
+
+// AttachmentInfectedEvent 附件感染事件（推送到空间策略中的推送地址）
+type AttachmentInfectedEvent struct {
+	Event        string    `json:"event"`
+	SpaceID      string    `json:"space_id"`
+	TableID      string    `json:"table_id"`
+	AttachmentID string    `json:"attachment_id"`
+	Name         string    `json:"name"`
+	Size         int64     `json:"size"`
+	MimeType     string    `json:"mime_type"`
+	Engine       string    `json:"engine"`
+	Signature    string    `json:"signature"`
+	DetectedTime time.Time `json:"detected_time"`
+}
+
+// AttachmentScanService 附件上传后处理流水线 ✨
+// 上传完成的附件处于待扫描（隔离）状态：嗅探实际类型 → 按空间策略校验类型与大小 → ClamAV / ICAP 扫描，
+// 全部通过后才可下载。扫描引擎不可用时保留租约，租约过期后重试；发现病毒时向空间配置的地址推送签名事件
+type AttachmentScanService struct {
+	repo              attachmentscan.Repository
+	attachmentRepo    attachment.Repository
+	storage           attachment.Storage
+	scanner           attachmentscan.Scanner
+	permissionService *PermissionServiceV2
+	resolveSpace      func(ctx context.Context, tableID string) string
+	cfg               config.AttachmentScanConfig
+	client            *http.Client
+	now               func() time.Time
+	wake              chan struct{}
+}
+
+// NewAttachmentScanService 创建附件扫描服务（scanner 为 nil 表示未配置扫描引擎）
+func NewAttachmentScanService(
+	repo attachmentscan.Repository,
+	attachmentRepo attachment.Repository,
+	storage attachment.Storage,
+	scanner attachmentscan.Scanner,
+	securityService *SecurityService,
+	permissionService *PermissionServiceV2,
+	cfg config.AttachmentScanConfig,
+) *AttachmentScanService {
+	return &AttachmentScanService{
+		repo:              repo,
+		attachmentRepo:    attachmentRepo,
+		storage:           storage,
+		scanner:           scanner,
+		permissionService: permissionService,
+		resolveSpace: func(ctx context.Context, tableID string) string {
+			return securityService.ResolveSpace(ctx, ResourceRefs{TableID: tableID})
+		},
+		cfg:    cfg,
+		client: netguard.NewHTTPClient(attachmentWebhookTimeout, cfg.AllowPrivate),
+		now:    time.Now,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Notify 通知有新附件待处理（不阻塞上传请求）
+func (s *AttachmentScanService) Notify(attachmentID string) {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台处理
+func (s *AttachmentScanService) Start(ctx context.Context) {
+	if s.cfg.PollInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			for s.processBatch(ctx) {
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// processBatch 领取并处理一批待扫描附件，返回是否可能还有待处理的附件
+func (s *AttachmentScanService) processBatch(ctx context.Context) bool {
+	limit := s.cfg.BatchSize
+	if limit <= 0 {
+		limit = 20
+	}
+	now := s.now()
+	items, err := s.attachmentRepo.ClaimPendingScan(ctx, now, now.Add(s.cfg.LeaseDuration), limit)
+	if err != nil {
+		attachmentScanLog.Warn(ctx, "领取待扫描附件失败", logger.ErrorField(err))
+		return false
+	}
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return false
+		}
+		s.process(ctx, item)
+	}
+	return len(items) == limit
+}
+
+// process 处理单个附件；未得出结论时不写入结果，租约过期后重新领取
+func (s *AttachmentScanService) process(ctx context.Context, item *attachment.AttachmentItem) {
+	spaceID := s.resolveSpace(ctx, item.TableID)
+	policy, err := s.policy(ctx, spaceID)
+	if err != nil {
+		attachmentScanLog.Warn(ctx, "读取附件策略失败", logger.String("space_id", spaceID), logger.ErrorField(err))
+		return
+	}
+
+	reader, err := s.storage.Download(ctx, item.StoragePath())
+	if err != nil {
+		if exists, existsErr := s.storage.Exists(ctx, item.StoragePath()); existsErr == nil && !exists {
+			s.finish(ctx, item, item.MimeType, attachment.ScanStatusRejected, "文件不存在")
+			return
+		}
+		attachmentScanLog.Warn(ctx, "读取附件失败", logger.String("attachment_id", item.ID), logger.ErrorField(err))
+		return
+	}
+	defer reader.Close()
+
+	head := make([]byte, attachmentscan.SniffLength)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		attachmentScanLog.Warn(ctx, "读取附件失败", logger.String("attachment_id", item.ID), logger.ErrorField(err))
+		return
+	}
+	head = head[:n]
+
+	mimeType, reason := attachmentscan.Resolve(item.MimeType, attachmentscan.Sniff(head))
+	if reason == "" {
+		reason = policy.Check(item.Name, item.Size, mimeType)
+	}
+	if reason != "" {
+		s.finish(ctx, item, mimeType, attachment.ScanStatusRejected, reason)
+		return
+	}
+
+	if s.scanner == nil {
+		if policy.ScanRequired {
+			attachmentScanLog.Debug(ctx, "空间要求病毒扫描但未配置扫描引擎，附件保持隔离",
+				logger.String("attachment_id", item.ID),
+				logger.String("space_id", spaceID))
+			return
+		}
+		s.finish(ctx, item, mimeType, attachment.ScanStatusClean, "")
+		return
+	}
+
+	scanCtx := ctx
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+	verdict, err := s.scanner.Scan(scanCtx, io.MultiReader(bytes.NewReader(head), reader))
+	if err != nil {
+		attachmentScanLog.Warn(ctx, "病毒扫描失败，稍后重试",
+			logger.String("attachment_id", item.ID),
+			logger.String("engine", s.scanner.Name()),
+			logger.ErrorField(err))
+		return
+	}
+	if !verdict.Infected {
+		s.finish(ctx, item, mimeType, attachment.ScanStatusClean, "")
+		return
+	}
+
+	attachmentScanLog.Warn(ctx, "附件发现病毒",
+		logger.String("attachment_id", item.ID),
+		logger.String("space_id", spaceID),
+		logger.String("signature", verdict.Signature))
+	if s.finish(ctx, item, mimeType, attachment.ScanStatusInfected, verdict.Signature) {
+		s.notifyInfected(ctx, policy, item, verdict.Signature)
+	}
+}
+
+// finish 保存处理结果
+func (s *AttachmentScanService) finish(ctx context.Context, item *attachment.AttachmentItem, mimeType string, status attachment.ScanStatus, detail string) bool {
+	item.MimeType = mimeType
+	item.SetScanResult(status, detail)
+	if err := s.attachmentRepo.SaveScanResult(ctx, item); err != nil {
+		attachmentScanLog.Warn(ctx, "保存附件扫描结果失败", logger.String("attachment_id", item.ID), logger.ErrorField(err))
+		return false
+	}
+	if status == attachment.ScanStatusRejected {
+		attachmentScanLog.Info(ctx, "附件未通过空间策略",
+			logger.String("attachment_id", item.ID),
+			logger.String("reason", detail))
+	}
+	return true
+}
+
+// notifyInfected 推送感染事件（尽力而为，失败只记录日志）
+func (s *AttachmentScanService) notifyInfected(ctx context.Context, policy *attachmentscan.Policy, item *attachment.AttachmentItem, signature string) {
+	if policy.WebhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(&AttachmentInfectedEvent{
+		Event:        attachmentInfectedEvent,
+		SpaceID:      policy.SpaceID,
+		TableID:      item.TableID,
+		AttachmentID: item.ID,
+		Name:         item.Name,
+		Size:         item.Size,
+		MimeType:     item.MimeType,
+		Engine:       s.scanner.Name(),
+		Signature:    signature,
+		DetectedTime: s.now(),
+	})
+	if err != nil {
+		return
+	}
+	if err := s.post(ctx, policy, body); err != nil {
+		attachmentScanLog.Warn(ctx, "推送附件感染事件失败",
+			logger.String("attachment_id", item.ID),
+			logger.String("space_id", policy.SpaceID),
+			logger.ErrorField(err))
+	}
+}
+
+// post 推送事件，非 2xx 响应视为失败
+func (s *AttachmentScanService) post(ctx context.Context, policy *attachmentscan.Policy, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, policy.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(replicationSignatureHeader, "sha256="+signReplicationBody(policy.WebhookSecret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// GetPolicy 获取空间附件策略（仅空间所有者）
+func (s *AttachmentScanService) GetPolicy(ctx context.Context, userID, spaceID string) (*AttachmentPolicyResponse, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	policy, err := s.policy(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return &AttachmentPolicyResponse{Policy: policy, ScanEngine: s.engineName()}, nil
+}
+
+// UpdatePolicy 更新空间附件策略（仅空间所有者）
+// 推送地址不变时沿用原签名密钥；首次设置或更换地址时生成新密钥并在响应中返回一次
+func (s *AttachmentScanService) UpdatePolicy(ctx context.Context, userID, spaceID string, req UpdateAttachmentPolicyRequest) (*AttachmentPolicyResponse, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	current, err := s.policy(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	policy := &attachmentscan.Policy{
+		SpaceID:           spaceID,
+		MaxFileSizeMB:     req.MaxFileSizeMB,
+		AllowedTypes:      req.AllowedTypes,
+		BlockedExtensions: req.BlockedExtensions,
+		ScanRequired:      req.ScanRequired,
+		WebhookURL:        req.WebhookURL,
+		UpdatedBy:         userID,
+		UpdatedTime:       s.now(),
+	}
+	if policy.AllowedTypes == nil {
+		policy.AllowedTypes = []string{}
+	}
+	if policy.BlockedExtensions == nil {
+		policy.BlockedExtensions = []string{}
+	}
+	if err := policy.Validate(s.cfg.AllowPrivate); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if policy.WebhookURL == current.WebhookURL {
+		policy.WebhookSecret = current.WebhookSecret
+	}
+	generated := policy.EnsureWebhookSecret()
+	if err := s.repo.SavePolicy(ctx, policy); err != nil {
+		return nil, pkgerrors.Database(err, "保存附件策略失败")
+	}
+
+	resp := &AttachmentPolicyResponse{Policy: policy, ScanEngine: s.engineName()}
+	if generated {
+		resp.WebhookSecret = policy.WebhookSecret
+	}
+	return resp, nil
+}
+
+// Rescan 将附件重新置为待扫描（仅空间所有者，用于更新病毒库或调整策略后复查）
+func (s *AttachmentScanService) Rescan(ctx context.Context, userID, attachmentID string) (*attachment.AttachmentItem, error) {
+	item, err := s.attachmentRepo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		if pkgerrors.Is(err, pkgerrors.ErrNotFound) {
+			return nil, pkgerrors.ErrNotFound.WithDetails("附件不存在")
+		}
+		return nil, pkgerrors.Database(err, "")
+	}
+	spaceID := s.resolveSpace(ctx, item.TableID)
+	if spaceID == "" {
+		return nil, pkgerrors.ErrForbidden.WithDetails("无法确定附件所属空间")
+	}
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	item.ScanStatus = attachment.ScanStatusPending
+	item.ScanDetail = ""
+	item.ScannedTime = nil
+	item.UpdatedTime = s.now()
+	if err := s.attachmentRepo.SaveScanResult(ctx, item); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	s.Notify(item.ID)
+	return item, nil
+}
+
+// policy 获取空间附件策略，未配置时返回默认策略
+func (s *AttachmentScanService) policy(ctx context.Context, spaceID string) (*attachmentscan.Policy, error) {
+	if spaceID == "" {
+		return attachmentscan.NewDefaultPolicy(spaceID), nil
+	}
+	policy, err := s.repo.GetPolicy(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if policy == nil {
+		policy = attachmentscan.NewDefaultPolicy(spaceID)
+	}
+	return policy, nil
+}
+
+func (s *AttachmentScanService) engineName() string {
+	if s.scanner == nil {
+		return "none"
+	}
+	return s.scanner.Name()
+}
+
+func (s *AttachmentScanService) ensureOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以管理附件策略")
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachmentscan"
+)
+
+// memoryAttachmentRepo 只实现扫描流水线用到的方法
+type memoryAttachmentRepo struct {
+	attachment.Repository
+	items map[string]*attachment.AttachmentItem
+}
+
+func (r *memoryAttachmentRepo) ClaimPendingScan(_ context.Context, _, _ time.Time, limit int) ([]*attachment.AttachmentItem, error) {
+	var list []*attachment.AttachmentItem
+	for _, item := range r.items {
+		if item.ScanStatus == attachment.ScanStatusPending && len(list) < limit {
+			copied := *item
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryAttachmentRepo) SaveScanResult(_ context.Context, item *attachment.AttachmentItem) error {
+	copied := *item
+	r.items[item.ID] = &copied
+	return nil
+}
+
+// memoryStorage 只实现读取
+type memoryStorage struct {
+	attachment.Storage
+	files map[string]string
+}
+
+func (s *memoryStorage) Download(_ context.Context, path string) (io.ReadCloser, error) {
+	content, ok := s.files[path]
+	if !ok {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (s *memoryStorage) Exists(_ context.Context, path string) (bool, error) {
+	_, ok := s.files[path]
+	return ok, nil
+}
+
+type memoryPolicyRepo struct {
+	policies map[string]*attachmentscan.Policy
+}
+
+func (r *memoryPolicyRepo) GetPolicy(_ context.Context, spaceID string) (*attachmentscan.Policy, error) {
+	return r.policies[spaceID], nil
+}
+
+func (r *memoryPolicyRepo) SavePolicy(_ context.Context, policy *attachmentscan.Policy) error {
+	r.policies[policy.SpaceID] = policy
+	return nil
+}
+
+// keywordScanner 内容包含 EICAR 时报告感染
+type keywordScanner struct{}
+
+func (keywordScanner) Name() string { return "test" }
+
+func (keywordScanner) Scan(_ context.Context, content io.Reader) (*attachmentscan.Verdict, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), "EICAR") {
+		return &attachmentscan.Verdict{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &attachmentscan.Verdict{}, nil
+}
+
+func newTestAttachmentScanService(scanner attachmentscan.Scanner, policy *attachmentscan.Policy, files map[string]string) (*AttachmentScanService, *memoryAttachmentRepo) {
+	repo := &memoryAttachmentRepo{items: map[string]*attachment.AttachmentItem{}}
+	for path := range files {
+		repo.items[path] = &attachment.AttachmentItem{ID: path, Name: path, Path: path, TableID: "tbl1", Size: int64(len(files[path])), ScanStatus: attachment.ScanStatusPending}
+	}
+	policies := &memoryPolicyRepo{policies: map[string]*attachmentscan.Policy{}}
+	if policy != nil {
+		policies.policies[policy.SpaceID] = policy
+	}
+	svc := NewAttachmentScanService(policies, repo, &memoryStorage{files: files}, scanner, nil, nil, config.AttachmentScanConfig{BatchSize: 10, AllowPrivate: true})
+	svc.resolveSpace = func(context.Context, string) string { return "spc1" }
+	return svc, repo
+}
+
+func TestAttachmentScanAppliesPolicyAndScanner(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	files := map[string]string{
+		"photo.png":  png,
+		"fake.png":   "<html><body>hi</body></html>",
+		"notes.txt":  "hello",
+		"virus.txt":  "EICAR test",
+		"report.pdf": "%PDF-1.4",
+	}
+	policy := &attachmentscan.Policy{SpaceID: "spc1", AllowedTypes: []string{"image/*", "text/plain", "text/html"}}
+	svc, repo := newTestAttachmentScanService(keywordScanner{}, policy, files)
+	repo.items["photo.png"].MimeType = "image/png"
+	repo.items["fake.png"].MimeType = "image/png"
+	repo.items["notes.txt"].MimeType = "text/plain"
+	repo.items["virus.txt"].MimeType = "text/plain"
+	repo.items["report.pdf"].MimeType = "application/pdf"
+
+	if svc.processBatch(context.Background()) {
+		t.Error("一批已处理完，不应继续领取")
+	}
+	want := map[string]attachment.ScanStatus{
+		"photo.png":  attachment.ScanStatusClean,
+		"fake.png":   attachment.ScanStatusRejected, // 内容为 HTML，与声明类型不符
+		"notes.txt":  attachment.ScanStatusClean,
+		"virus.txt":  attachment.ScanStatusInfected,
+		"report.pdf": attachment.ScanStatusRejected, // 不在允许类型中
+	}
+	for id, status := range want {
+		item := repo.items[id]
+		if item.ScanStatus != status || item.ScannedTime == nil {
+			t.Errorf("%s: 期望 %s，得到 %s（%s）", id, status, item.ScanStatus, item.ScanDetail)
+		}
+	}
+	if repo.items["virus.txt"].ScanDetail != "Eicar-Test-Signature" || repo.items["virus.txt"].Downloadable() {
+		t.Errorf("感染附件记录错误: %+v", repo.items["virus.txt"])
+	}
+}
+
+func TestAttachmentScanKeepsQuarantineWithoutEngine(t *testing.T) {
+	files := map[string]string{"notes.txt": "hello"}
+	svc, repo := newTestAttachmentScanService(nil, &attachmentscan.Policy{SpaceID: "spc1", ScanRequired: true}, files)
+	svc.processBatch(context.Background())
+	if item := repo.items["notes.txt"]; item.ScanStatus != attachment.ScanStatusPending || item.Downloadable() {
+		t.Errorf("要求扫描但未配置引擎时应保持隔离，得到 %s", item.ScanStatus)
+	}
+
+	svc, repo = newTestAttachmentScanService(nil, nil, files)
+	svc.processBatch(context.Background())
+	if item := repo.items["notes.txt"]; item.ScanStatus != attachment.ScanStatusClean {
+		t.Errorf("未要求扫描时类型校验通过即可下载，得到 %s", item.ScanStatus)
+	}
+}
+
+func TestAttachmentScanPostsSignedInfectedEvent(t *testing.T) {
+	var received AttachmentInfectedEvent
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(replicationSignatureHeader)
+		_ = json.Unmarshal(body, &received)
+		if signature != "sha256="+signReplicationBody("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	policy := &attachmentscan.Policy{SpaceID: "spc1", WebhookURL: server.URL, WebhookSecret: "secret"}
+	svc, _ := newTestAttachmentScanService(keywordScanner{}, policy, map[string]string{"virus.txt": "EICAR"})
+	svc.processBatch(context.Background())
+
+	if received.Event != attachmentInfectedEvent || received.AttachmentID != "virus.txt" || received.SpaceID != "spc1" || received.Signature != "Eicar-Test-Signature" {
+		t.Errorf("推送事件错误: %+v", received)
+	}
+	if signature == "" {
+		t.Error("推送缺少签名头")
+	}
+}
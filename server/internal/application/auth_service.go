@@ -3,8 +3,12 @@ package application
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/impersonation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/servicetoken"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
@@ -15,6 +19,9 @@ import (
 type AuthService struct {
 	userRepo     repository.UserRepository
 	tokenService *TokenService
+	sessions     security.Repository   // 登录会话（未设置时不跟踪会话）✨
+	serviceAuth  *ServiceTokenService  // 服务令牌认证（未设置时不接受服务令牌）✨
+	impersonate  *ImpersonationService // 代入令牌认证（未设置时不接受代入令牌）✨
 }
 
 // NewAuthService 创建认证服务
@@ -25,6 +32,60 @@ func NewAuthService(userRepo repository.UserRepository, tokenService *TokenServi
 	}
 }
 
+// SetSessionRepository 设置登录会话仓储
+func (s *AuthService) SetSessionRepository(sessions security.Repository) {
+	s.sessions = sessions
+}
+
+// SetServiceTokenService 设置服务令牌服务（Bearer 令牌以 lsk_ 开头时按服务令牌认证）
+func (s *AuthService) SetServiceTokenService(serviceAuth *ServiceTokenService) {
+	s.serviceAuth = serviceAuth
+}
+
+// SetImpersonationService 设置代入服务（Bearer 令牌以 lim_ 开头时按代入令牌认证）
+func (s *AuthService) SetImpersonationService(impersonate *ImpersonationService) {
+	s.impersonate = impersonate
+}
+
+// ImpersonationService 获取代入服务（未设置时为 nil）
+func (s *AuthService) ImpersonationService() *ImpersonationService {
+	return s.impersonate
+}
+
+// startSession 创建登录会话并签发令牌
+func (s *AuthService) startSession(ctx context.Context, userID, email string, isAdmin bool, clientIP, userAgent string) (string, string, error) {
+	tokenSession := TokenSession{AuthTime: time.Now()}
+	if s.sessions != nil {
+		session := security.NewSession(userID, clientIP, userAgent)
+		if err := s.sessions.CreateSession(ctx, session); err != nil {
+			return "", "", pkgerrors.Database(err, "创建会话失败")
+		}
+		tokenSession.SessionID = session.ID
+		tokenSession.AuthTime = session.CreatedTime
+	}
+
+	accessToken, refreshToken, err := s.tokenService.GenerateSessionTokens(userID, email, isAdmin, tokenSession)
+	if err != nil {
+		return "", "", pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成Token失败: %v", err))
+	}
+	return accessToken, refreshToken, nil
+}
+
+// checkSession 校验令牌关联的会话仍然有效
+func (s *AuthService) checkSession(ctx context.Context, sessionID string) error {
+	if s.sessions == nil || sessionID == "" {
+		return nil
+	}
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询会话失败")
+	}
+	if session == nil || session.IsRevoked() {
+		return pkgerrors.ErrUnauthorized.WithDetails("会话已失效，请重新登录")
+	}
+	return nil
+}
+
 // Login 用户登录
 func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.LoginResponse, error) {
 	// 1. 查找用户
@@ -35,7 +96,7 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return nil, pkgerrors.ErrUnauthorized.WithDetails("邮箱或密码错误")
@@ -61,10 +122,10 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Log
 		logger.Error("更新最后登录时间失败", logger.ErrorField(err))
 	}
 
-	// 5. 生成Token
-	accessToken, refreshToken, err := s.tokenService.GenerateTokens(user.ID().String(), user.Email().String(), user.IsAdmin())
+	// 5. 创建会话并生成Token
+	accessToken, refreshToken, err := s.startSession(ctx, user.ID().String(), user.Email().String(), user.IsAdmin(), req.ClientIP, req.UserAgent)
 	if err != nil {
-		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成Token失败: %v", err))
+		return nil, err
 	}
 
 	logger.Info("用户登录成功",
@@ -90,7 +151,7 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 	// 2. 检查邮箱是否已存在
 	exists, err := s.userRepo.ExistsByEmail(ctx, email, nil)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查邮箱失败: %v", err))
+		return nil, pkgerrors.Database(err, "检查邮箱失败")
 	}
 	if exists {
 		return nil, pkgerrors.ErrConflict.WithDetails("邮箱已被注册")
@@ -109,10 +170,10 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 		return nil, err
 	}
 
-	// 4. 生成Token
-	accessToken, refreshToken, err := s.tokenService.GenerateTokens(userResp.ID, userResp.Email, false)
+	// 4. 创建会话并生成Token
+	accessToken, refreshToken, err := s.startSession(ctx, userResp.ID, userResp.Email, false, req.ClientIP, req.UserAgent)
 	if err != nil {
-		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成Token失败: %v", err))
+		return nil, err
 	}
 
 	logger.Info("用户注册成功",
@@ -139,7 +200,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 	userID := valueobject.NewUserID(claims.UserID)
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return nil, pkgerrors.ErrUnauthorized.WithDetails("用户不存在")
@@ -150,8 +211,19 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, pkgerrors.ErrForbidden.WithDetails("账户已被停用")
 	}
 
-	// 4. 生成新的Token
-	accessToken, newRefreshToken, err := s.tokenService.GenerateTokens(user.ID().String(), user.Email().String(), user.IsAdmin())
+	// 4. 检查会话（刷新沿用原会话，登录时间不变）
+	session := claims.Session()
+	if err := s.checkSession(ctx, session.SessionID); err != nil {
+		return nil, err
+	}
+	if s.sessions != nil && session.SessionID != "" {
+		if err := s.sessions.TouchSession(ctx, session.SessionID); err != nil {
+			logger.Warn("更新会话活跃时间失败", logger.ErrorField(err))
+		}
+	}
+
+	// 5. 生成新的Token
+	accessToken, newRefreshToken, err := s.tokenService.GenerateSessionTokens(user.ID().String(), user.Email().String(), user.IsAdmin(), session)
 	if err != nil {
 		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成Token失败: %v", err))
 	}
@@ -162,26 +234,110 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 	}, nil
 }
 
-// Logout 用户登出
-func (s *AuthService) Logout(ctx context.Context, userID string) error {
-	// 实现Token黑名单或缓存失效逻辑（参考 teable-develop 使用 Redis）
+// Logout 用户登出（吊销当前会话，令牌随之失效）
+func (s *AuthService) Logout(ctx context.Context, userID, sessionID string) error {
+	if s.sessions != nil && sessionID != "" {
+		if err := s.sessions.RevokeSession(ctx, sessionID); err != nil {
+			return pkgerrors.Database(err, "吊销会话失败")
+		}
+	}
+
 	logger.Info("用户登出",
 		logger.String("user_id", userID),
+		logger.String("session_id", sessionID),
 	)
 
 	return nil
 }
 
+// Reauthenticate 重新认证（敏感操作前校验密码，签发带重新认证时间的新令牌）
+func (s *AuthService) Reauthenticate(ctx context.Context, claims *dto.TokenClaims, password string) (*dto.TokenResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(claims.UserID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if user == nil {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("用户不存在")
+	}
+
+	pwd, err := valueobject.NewPassword(password)
+	if err != nil || !user.Password().Verify(pwd) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("密码错误")
+	}
+
+	accessToken, refreshToken, err := s.tokenService.GenerateSessionTokens(claims.UserID, claims.Email, claims.IsAdmin, TokenSession{
+		SessionID:  claims.SessionID,
+		AuthTime:   claims.AuthTime,
+		ReauthTime: time.Now(),
+	})
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成Token失败: %v", err))
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// ListSessions 列出用户的活跃会话
+func (s *AuthService) ListSessions(ctx context.Context, userID string) ([]*security.Session, error) {
+	if s.sessions == nil {
+		return []*security.Session{}, nil
+	}
+	sessions, err := s.sessions.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return sessions, nil
+}
+
+// RevokeSession 吊销用户自己的会话
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if s.sessions == nil {
+		return pkgerrors.ErrNotFound.WithDetails("会话不存在")
+	}
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	if session == nil || session.UserID != userID {
+		return pkgerrors.ErrNotFound.WithDetails("会话不存在")
+	}
+	if err := s.sessions.RevokeSession(ctx, sessionID); err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	return nil
+}
+
 // ValidateToken 验证Token
 func (s *AuthService) ValidateToken(ctx context.Context, token string) (*dto.TokenClaims, error) {
+	if s.serviceAuth != nil && servicetoken.IsServiceToken(token) {
+		return s.serviceAuth.Authenticate(ctx, token)
+	}
+	if s.impersonate != nil && impersonation.IsImpersonationToken(token) {
+		return s.impersonate.Authenticate(ctx, token)
+	}
+
 	claims, err := s.tokenService.ValidateAccessToken(token)
 	if err != nil {
 		return nil, pkgerrors.ErrUnauthorized.WithDetails("Token无效")
 	}
 
-	return &dto.TokenClaims{
-		UserID:  claims.UserID,
-		Email:   claims.Email,
-		IsAdmin: claims.IsAdmin,
-	}, nil
+	session := claims.Session()
+	if err := s.checkSession(ctx, session.SessionID); err != nil {
+		return nil, err
+	}
+
+	result := &dto.TokenClaims{
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		IsAdmin:   claims.IsAdmin,
+		SessionID: session.SessionID,
+		AuthTime:  session.AuthTime,
+	}
+	if !session.ReauthTime.IsZero() {
+		result.ReauthTime = &session.ReauthTime
+	}
+	return result, nil
 }
@@ -0,0 +1,240 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/backlink"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
+)
+
+// backlinkListLimit 单条记录最多返回的反向链接数
+const backlinkListLimit = 500
+
+// RecordBacklinkItem 提及了某条记录的位置
+type RecordBacklinkItem struct {
+	TableID   string    `json:"tableId"`
+	TableName string    `json:"tableName"`
+	RecordID  string    `json:"recordId"`
+	Title     string    `json:"title"` // 来源记录主字段的展示文本
+	FieldID   string    `json:"fieldId"`
+	FieldName string    `json:"fieldName"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// BacklinkService 记录提及与反向链接服务 ✨
+// 长文本与富文本单元格中的 @记录 提及以结构化标记存储（见 richtext.RecordMentionToken），
+// 记录写入时在同一事务中按单元格内容重建来源记录的反向链接，删除记录时一并删除。
+// 查询某条记录的反向链接时只返回访问者可以查看的来源记录，来源字段已删除或不再是文本类型的链接被忽略
+type BacklinkService struct {
+	repo              backlink.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	now               func() time.Time
+}
+
+// NewBacklinkService 创建反向链接服务
+func NewBacklinkService(
+	repo backlink.Repository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	recordRepo recordRepo.RecordRepository,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+) *BacklinkService {
+	return &BacklinkService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepo,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		now:               time.Now,
+	}
+}
+
+// RecordChanged 按记录变更重建来源记录的反向链接（在记录写入事务中调用，Fields 为记录的完整数据）
+func (s *BacklinkService) RecordChanged(ctx context.Context, event *database.RecordEvent) error {
+	switch event.EventType {
+	case "record.delete":
+		if err := s.repo.DeleteForSource(ctx, event.TID, event.RID); err != nil {
+			return pkgerrors.Database(err, "删除反向链接失败")
+		}
+		return nil
+	case "record.create", "record.update":
+	default:
+		return nil
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, event.TID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段列表失败")
+	}
+	hasText := false
+	var links []*backlink.Backlink
+	now := s.now()
+	for _, field := range fields {
+		if !isMentionField(field) {
+			continue
+		}
+		hasText = true
+		mentions := cellMentions(field, event.Fields[field.ID().String()])
+		links = append(links, backlink.FromMentions(event.TID, event.RID, field.ID().String(), mentions, now)...)
+	}
+	// 没有文本字段的表不会产生提及；新记录没有提及时也无需清理
+	if !hasText || (event.EventType == "record.create" && len(links) == 0) {
+		return nil
+	}
+	if err := s.repo.ReplaceForSource(ctx, event.TID, event.RID, links); err != nil {
+		return pkgerrors.Database(err, "更新反向链接失败")
+	}
+	return nil
+}
+
+// ListBacklinks 列出提及了该记录的位置（按最近更新排序，只包含访问者可以查看的来源记录）
+func (s *BacklinkService) ListBacklinks(ctx context.Context, userID, tableID, recordID string) ([]RecordBacklinkItem, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表格的权限")
+	}
+	links, err := s.repo.ListByTarget(ctx, tableID, recordID, backlinkListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询反向链接失败")
+	}
+
+	byTable := make(map[string][]*backlink.Backlink)
+	var tableOrder []string
+	for _, link := range links {
+		if _, ok := byTable[link.SourceTableID]; !ok {
+			tableOrder = append(tableOrder, link.SourceTableID)
+		}
+		byTable[link.SourceTableID] = append(byTable[link.SourceTableID], link)
+	}
+
+	items := make(map[*backlink.Backlink]RecordBacklinkItem, len(links))
+	for _, sourceTableID := range tableOrder {
+		if err := s.resolveTable(ctx, userID, sourceTableID, byTable[sourceTableID], items); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]RecordBacklinkItem, 0, len(items))
+	for _, link := range links {
+		if item, ok := items[link]; ok {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// resolveTable 补全同一来源表中反向链接的表名、字段名与记录标题，跳过无权查看或已失效的链接
+func (s *BacklinkService) resolveTable(ctx context.Context, userID, tableID string, links []*backlink.Backlink, items map[*backlink.Backlink]RecordBacklinkItem) error {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段列表失败")
+	}
+	byID := fieldIndex(fields)
+
+	seen := make(map[string]bool, len(links))
+	ids := make([]recordVO.RecordID, 0, len(links))
+	for _, link := range links {
+		if !seen[link.SourceRecordID] {
+			seen[link.SourceRecordID] = true
+			ids = append(ids, recordVO.NewRecordID(link.SourceRecordID))
+		}
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, ids)
+	if err != nil {
+		return pkgerrors.Database(err, "查询来源记录失败")
+	}
+	responses := dto.FromRecordEntities(records)
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, responses...)
+	}
+	titles := make(map[string]string, len(responses))
+	for _, record := range responses {
+		titles[record.ID] = backlinkTitle(ctx, fields, record)
+	}
+
+	for _, link := range links {
+		field, ok := byID[link.SourceFieldID]
+		title, found := titles[link.SourceRecordID]
+		if !ok || !found || !isMentionField(field) {
+			continue
+		}
+		items[link] = RecordBacklinkItem{
+			TableID:   tableID,
+			TableName: table.Name().String(),
+			RecordID:  link.SourceRecordID,
+			Title:     title,
+			FieldID:   link.SourceFieldID,
+			FieldName: field.Name().String(),
+			UpdatedAt: link.UpdatedAt,
+		}
+	}
+	return nil
+}
+
+// backlinkTitle 记录主字段的展示文本（为空时为记录ID）
+func backlinkTitle(ctx context.Context, fields []*fieldEntity.Field, record *dto.RecordResponse) string {
+	for _, field := range fields {
+		if !field.IsPrimary() {
+			continue
+		}
+		value, ok := record.Data[field.ID().String()]
+		if !ok || value == nil {
+			break
+		}
+		format := cellvalue.FormatOptions{Locale: userlocale.Language(ctx), TimeZone: userlocale.Location(ctx)}
+		if title := cellvalue.Format(field.Type().String(), field.Options(), value, format); title != "" {
+			return title
+		}
+		break
+	}
+	return record.ID
+}
+
+// isMentionField 可以包含记录提及的字段（长文本与富文本）
+func isMentionField(field *fieldEntity.Field) bool {
+	switch field.Type().String() {
+	case fieldVO.TypeLongText, fieldVO.TypeRichText:
+		return true
+	default:
+		return false
+	}
+}
+
+// cellMentions 单元格中的记录提及
+func cellMentions(field *fieldEntity.Field, value interface{}) []richtext.RecordMention {
+	text, ok := value.(string)
+	if !ok || text == "" {
+		return nil
+	}
+	if field.Type().String() == fieldVO.TypeRichText {
+		return richtext.RecordMentionsInHTML(text)
+	}
+	return richtext.RecordMentionsInText(text)
+}
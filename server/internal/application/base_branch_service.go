@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/basebranch"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var baseBranchLog = logger.Named("base_branch")
+
+// CreateBaseBranchRequest 创建沙盒分支请求
+type CreateBaseBranchRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// BaseBranchService Base 沙盒分支：复制生产结构到沙盒 Base，修改后三方合并回生产 ✨
+// 合并作为长时操作执行（branch_promote），执行前重新检测生产在此期间的改动，存在冲突时不做任何变更
+type BaseBranchService struct {
+	repo              basebranch.Repository
+	baseService       *BaseService
+	schemaSpec        *SchemaSpecService
+	operationService  *OperationService
+	permissionService *PermissionServiceV2
+}
+
+// NewBaseBranchService 创建沙盒分支服务
+func NewBaseBranchService(
+	repo basebranch.Repository,
+	baseService *BaseService,
+	schemaSpec *SchemaSpecService,
+	operationService *OperationService,
+	permissionService *PermissionServiceV2,
+) *BaseBranchService {
+	return &BaseBranchService{
+		repo:              repo,
+		baseService:       baseService,
+		schemaSpec:        schemaSpec,
+		operationService:  operationService,
+		permissionService: permissionService,
+	}
+}
+
+// CreateBranch 创建沙盒分支：在同一空间新建 Base 并复制生产的表、字段与视图（不复制记录）
+func (s *BaseBranchService) CreateBranch(ctx context.Context, userID, sourceBaseID string, req CreateBaseBranchRequest) (*basebranch.Branch, error) {
+	source, err := s.baseService.GetBase(ctx, sourceBaseID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessBase(ctx, userID, sourceBaseID) ||
+		!s.permissionService.CanCreateBaseInSpace(ctx, userID, source.SpaceID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限为该Base创建沙盒")
+	}
+	if err := basebranch.ValidateName(req.Name); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	spec, err := s.schemaSpec.Export(ctx, userID, sourceBaseID)
+	if err != nil {
+		return nil, err
+	}
+	sandbox, err := s.baseService.CreateBase(ctx, dto.CreateBaseRequest{
+		Name:    fmt.Sprintf("%s（沙盒：%s）", source.Name, req.Name),
+		Icon:    source.Icon,
+		SpaceID: source.SpaceID,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	branch, err := s.provisionSandbox(ctx, userID, sourceBaseID, sandbox.ID, req.Name, spec)
+	if err != nil {
+		if dropErr := s.baseService.DeleteBase(ctx, sandbox.ID); dropErr != nil {
+			baseBranchLog.Warn(ctx, "清理沙盒Base失败",
+				logger.String("sandbox_base_id", sandbox.ID),
+				logger.ErrorField(dropErr))
+		}
+		return nil, err
+	}
+
+	baseBranchLog.Info(ctx, "沙盒分支已创建",
+		logger.String("branch_id", branch.ID),
+		logger.String("source_base_id", sourceBaseID),
+		logger.String("sandbox_base_id", sandbox.ID))
+	return branch, nil
+}
+
+// provisionSandbox 在沙盒中应用生产结构，并以沙盒导出的结构作为合并基准
+func (s *BaseBranchService) provisionSandbox(ctx context.Context, userID, sourceBaseID, sandboxBaseID, name string, spec *schemaspec.Spec) (*basebranch.Branch, error) {
+	if _, err := s.schemaSpec.Apply(ctx, userID, sandboxBaseID, spec, false); err != nil {
+		return nil, err
+	}
+	baseSpec, err := s.exportYAML(ctx, userID, sandboxBaseID)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := basebranch.NewBranch(sourceBaseID, sandboxBaseID, name, baseSpec, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Create(ctx, branch); err != nil {
+		return nil, pkgerrors.Database(err, "保存沙盒分支失败")
+	}
+	return branch, nil
+}
+
+// ListBranches 列出生产 Base 的沙盒分支
+func (s *BaseBranchService) ListBranches(ctx context.Context, userID, sourceBaseID string) ([]*basebranch.Branch, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, sourceBaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	branches, err := s.repo.ListBySource(ctx, sourceBaseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return branches, nil
+}
+
+// GetBranch 获取沙盒分支
+func (s *BaseBranchService) GetBranch(ctx context.Context, userID, branchID string) (*basebranch.Branch, error) {
+	branch, err := s.repo.GetByID(ctx, branchID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if branch == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("沙盒分支不存在")
+	}
+	if !s.permissionService.CanAccessBase(ctx, userID, branch.SourceBaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	return branch, nil
+}
+
+// DiffBranch 预览合并：返回合并后将对生产执行的变更，以及与生产期间改动的冲突
+func (s *BaseBranchService) DiffBranch(ctx context.Context, userID, branchID string) (*schemaspec.Plan, error) {
+	branch, err := s.activeBranch(ctx, userID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	merged, conflicts, err := s.merge(ctx, userID, branch)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := s.schemaSpec.Diff(ctx, userID, branch.SourceBaseID, merged, true)
+	if err != nil {
+		return nil, err
+	}
+	plan.Conflicts = append(conflicts, plan.Conflicts...)
+	return plan, nil
+}
+
+// PromoteBranch 发起合并操作（branch_promote），返回等待执行的操作
+func (s *BaseBranchService) PromoteBranch(ctx context.Context, userID, branchID string) (*operation.Operation, error) {
+	branch, err := s.activeBranch(ctx, userID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	params, _ := json.Marshal(&BranchPromoteParams{BranchID: branch.ID})
+	op, err := s.operationService.StartOperation(ctx, userID, branch.SourceBaseID, StartOperationRequest{
+		Type:   operation.TypeBranchPromote,
+		Params: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := branch.StartPromotion(op.ID, time.Now()); err == nil {
+		if err := s.repo.Update(ctx, branch); err != nil {
+			baseBranchLog.Warn(ctx, "记录合并操作失败",
+				logger.String("branch_id", branch.ID),
+				logger.ErrorField(err))
+		}
+	}
+	return op, nil
+}
+
+// DiscardBranch 丢弃沙盒分支并删除沙盒 Base
+func (s *BaseBranchService) DiscardBranch(ctx context.Context, userID, branchID string) error {
+	branch, err := s.activeBranch(ctx, userID, branchID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanDeleteBase(ctx, userID, branch.SandboxBaseID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限删除沙盒Base")
+	}
+	if err := s.baseService.DeleteBase(ctx, branch.SandboxBaseID); err != nil {
+		return err
+	}
+	if err := branch.Discard(time.Now()); err != nil {
+		return pkgerrors.ErrConflict.WithDetails(err.Error())
+	}
+	if err := s.repo.Update(ctx, branch); err != nil {
+		return pkgerrors.Database(err, "")
+	}
+
+	baseBranchLog.Info(ctx, "沙盒分支已丢弃",
+		logger.String("branch_id", branch.ID),
+		logger.String("sandbox_base_id", branch.SandboxBaseID))
+	return nil
+}
+
+// activeBranch 获取可合并的分支
+func (s *BaseBranchService) activeBranch(ctx context.Context, userID, branchID string) (*basebranch.Branch, error) {
+	branch, err := s.GetBranch(ctx, userID, branchID)
+	if err != nil {
+		return nil, err
+	}
+	if !branch.IsActive() {
+		return nil, pkgerrors.ErrConflict.WithDetails("沙盒分支已丢弃")
+	}
+	return branch, nil
+}
+
+// merge 三方合并：合并基准、生产当前结构、沙盒当前结构
+func (s *BaseBranchService) merge(ctx context.Context, userID string, branch *basebranch.Branch) (*schemaspec.Spec, []schemaspec.Conflict, error) {
+	base, err := schemaspec.Parse([]byte(branch.BaseSpec))
+	if err != nil {
+		return nil, nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("合并基准无效: %v", err))
+	}
+	ours, err := s.schemaSpec.Export(ctx, userID, branch.SourceBaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirs, err := s.schemaSpec.Export(ctx, userID, branch.SandboxBaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	merged, conflicts := schemaspec.Merge(base, ours, theirs)
+	return merged, conflicts, nil
+}
+
+// exportYAML 导出 Base 结构为 YAML
+func (s *BaseBranchService) exportYAML(ctx context.Context, userID, baseID string) (string, error) {
+	spec, err := s.schemaSpec.Export(ctx, userID, baseID)
+	if err != nil {
+		return "", err
+	}
+	data, err := spec.Marshal()
+	if err != nil {
+		return "", pkgerrors.ErrInternalServer.WithDetails(err.Error())
+	}
+	return string(data), nil
+}
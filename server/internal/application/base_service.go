@@ -55,7 +55,7 @@ func (s *BaseService) CreateBase(ctx context.Context, req dto.CreateBaseRequest,
 	// 2. 检查父空间是否存在
 	space, err := s.spaceRepo.GetSpaceByID(ctx, req.SpaceID)
 	if err != nil {
-		return nil, errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找父空间失败: %v", err))
+		return nil, errors.Database(err, "查找父空间失败")
 	}
 	if space == nil {
 		return nil, errors.ErrSpaceNotFound.WithDetails(fmt.Sprintf("父空间不存在: %s", req.SpaceID))
@@ -80,6 +80,8 @@ func (s *BaseService) CreateBase(ctx context.Context, req dto.CreateBaseRequest,
 
 	// 5. ✅ 创建独立的PostgreSQL Schema（完全动态表架构）
 	// 参考旧系统：const sqlList = this.dbProvider.createSchema(base.id);
+	// 工作空间隔离模式下，Base元数据尚未落库，通过上下文告知所属租户
+	ctx = database.WithTenant(ctx, req.SpaceID)
 	if s.dbProvider.SupportsSchema() {
 		logger.Info("正在为Base创建独立Schema",
 			logger.String("base_id", base.ID),
@@ -108,7 +110,7 @@ func (s *BaseService) CreateBase(ctx context.Context, req dto.CreateBaseRequest,
 					logger.ErrorField(rollbackErr))
 			}
 		}
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	logger.Info("✅ Base创建成功（含独立Schema）",
@@ -130,7 +132,7 @@ func (s *BaseService) GetBase(ctx context.Context, baseID string) (*dto.BaseResp
 				"id":       baseID,
 			})
 		}
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	return s.toDTO(base), nil
@@ -147,7 +149,7 @@ func (s *BaseService) UpdateBase(ctx context.Context, baseID string, req dto.Upd
 				"id":       baseID,
 			})
 		}
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	// 2. 权限检查
@@ -174,7 +176,7 @@ func (s *BaseService) UpdateBase(ctx context.Context, baseID string, req dto.Upd
 
 	// 4. 持久化
 	if err := s.repo.Update(ctx, base); err != nil {
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	// 5. 返回DTO
@@ -188,7 +190,7 @@ func (s *BaseService) DeleteBase(ctx context.Context, baseID string) error {
 	// 1. 检查Base是否存在
 	exists, err := s.repo.Exists(ctx, baseID)
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	if !exists {
@@ -233,7 +235,7 @@ func (s *BaseService) DeleteBase(ctx context.Context, baseID string) error {
 
 	// 4. 删除Base元数据
 	if err := s.repo.Delete(ctx, baseID); err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	logger.Info("✅ Base删除成功（含Schema和所有物理表）",
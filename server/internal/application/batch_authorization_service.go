@@ -0,0 +1,261 @@
+package application
+
+import (
+	"context"
+	"sync"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/permission"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+)
+
+// AuthorizationResource 批量权限校验的资源类型
+type AuthorizationResource string
+
+const (
+	AuthorizationResourceSpace AuthorizationResource = "space"
+	AuthorizationResourceBase  AuthorizationResource = "base"
+	AuthorizationResourceTable AuthorizationResource = "table" // 表及其记录，按所属 Base 的角色判断
+	AuthorizationResourceField AuthorizationResource = "field" // 按字段所属表的 Base 判断
+	AuthorizationResourceView  AuthorizationResource = "view"  // 按视图所属表的 Base 判断
+)
+
+// 拒绝原因
+const (
+	AuthorizationReasonNotFound        = "resource_not_found" // 资源不存在
+	AuthorizationReasonNotCollaborator = "not_collaborator"   // 不是资源所属 Space/Base 的协作者
+	AuthorizationReasonRoleDenied      = "role_denied"        // 角色没有该动作的权限
+)
+
+// AuthorizationCheck 一个待校验的（主体, 动作, 资源）
+type AuthorizationCheck struct {
+	UserID     string
+	Action     permission.Action
+	Resource   AuthorizationResource
+	ResourceID string
+}
+
+// AuthorizationDecision 单个校验的结果
+type AuthorizationDecision struct {
+	Allowed bool
+	Role    entity.RoleName // 主体在资源所属 Space/Base 上的角色（不是协作者时为空）
+	Reason  string          // 拒绝原因（允许时为空）
+}
+
+// BatchAuthorizationService 批量权限校验服务 ✨
+// 自动化运行、批量编辑与导入一次需要校验大量（主体, 动作, 资源）组合，逐条调用 PermissionServiceV2
+// 会为每条记录重复查询表所属 Base 与协作者角色。本服务在一次校验中：
+//   - 相同的组合只判断一次；
+//   - 表/字段/视图所属的 Base 与（Base, 用户）的角色只解析一次，不存在的资源与非协作者同样缓存。
+//
+// 解析结果保存在 AuthorizationBatch 中，通过 WithAuthorizationBatch 附加到上下文后，同一批操作内
+// PermissionServiceV2 的逐条检查（如状态流、记录锁、脚本宿主 API）也复用这些结果。
+// 批次只在一批操作内有效，不跨请求缓存，角色变更在下一批生效
+type BatchAuthorizationService struct {
+	permissions *PermissionServiceV2
+}
+
+// NewBatchAuthorizationService 创建批量权限校验服务
+func NewBatchAuthorizationService(permissions *PermissionServiceV2) *BatchAuthorizationService {
+	return &BatchAuthorizationService{permissions: permissions}
+}
+
+// NewBatch 创建一个校验批次
+func (s *BatchAuthorizationService) NewBatch() *AuthorizationBatch {
+	return s.permissions.newAuthorizationBatch()
+}
+
+// Authorize 一次校验多个组合，结果与 checks 一一对应（上下文已附加批次时复用其解析结果）
+func (s *BatchAuthorizationService) Authorize(ctx context.Context, checks []AuthorizationCheck) []AuthorizationDecision {
+	batch := authorizationBatchFromContext(ctx)
+	if batch == nil || batch.permissions != s.permissions {
+		batch = s.NewBatch()
+	}
+	return batch.Authorize(ctx, checks)
+}
+
+// AuthorizationBatch 一个校验批次：缓存资源所属的 Space/Base 与协作者角色（并发安全）
+type AuthorizationBatch struct {
+	permissions *PermissionServiceV2
+
+	mu     sync.Mutex
+	tables map[string]*tableEntity.Table // 表ID → 表（不存在时为 nil）
+	owners map[string]string             // 字段/视图ID → 所属表ID（不存在时为空）
+	roles  map[string]batchRole          // Space/Base ID + 用户ID → 角色
+	checks map[AuthorizationCheck]AuthorizationDecision
+}
+
+// batchRole 协作者角色的解析结果
+type batchRole struct {
+	role entity.RoleName
+	err  error
+}
+
+func (s *PermissionServiceV2) newAuthorizationBatch() *AuthorizationBatch {
+	return &AuthorizationBatch{
+		permissions: s,
+		tables:      make(map[string]*tableEntity.Table),
+		owners:      make(map[string]string),
+		roles:       make(map[string]batchRole),
+		checks:      make(map[AuthorizationCheck]AuthorizationDecision),
+	}
+}
+
+type authorizationBatchKey struct{}
+
+// WithAuthorizationBatch 为上下文附加校验批次，同一批操作内的权限检查复用其解析结果
+func WithAuthorizationBatch(ctx context.Context, batch *AuthorizationBatch) context.Context {
+	if batch == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, authorizationBatchKey{}, batch)
+}
+
+func authorizationBatchFromContext(ctx context.Context) *AuthorizationBatch {
+	if ctx == nil {
+		return nil
+	}
+	batch, _ := ctx.Value(authorizationBatchKey{}).(*AuthorizationBatch)
+	return batch
+}
+
+// Authorize 一次校验多个组合，结果与 checks 一一对应
+func (b *AuthorizationBatch) Authorize(ctx context.Context, checks []AuthorizationCheck) []AuthorizationDecision {
+	decisions := make([]AuthorizationDecision, len(checks))
+	for i, check := range checks {
+		decisions[i] = b.Check(ctx, check)
+	}
+	return decisions
+}
+
+// Check 校验单个组合
+func (b *AuthorizationBatch) Check(ctx context.Context, check AuthorizationCheck) AuthorizationDecision {
+	b.mu.Lock()
+	decision, ok := b.checks[check]
+	b.mu.Unlock()
+	if ok {
+		return decision
+	}
+
+	decision = b.decide(ctx, check)
+	b.mu.Lock()
+	b.checks[check] = decision
+	b.mu.Unlock()
+	return decision
+}
+
+func (b *AuthorizationBatch) decide(ctx context.Context, check AuthorizationCheck) AuthorizationDecision {
+	scopeID := b.scope(ctx, check.Resource, check.ResourceID)
+	if scopeID == "" {
+		return AuthorizationDecision{Reason: AuthorizationReasonNotFound}
+	}
+	role, err := b.role(ctx, scopeID, check.UserID)
+	if err != nil || role == "" {
+		return AuthorizationDecision{Reason: AuthorizationReasonNotCollaborator}
+	}
+	if !permission.HasPermission(role, check.Action) {
+		return AuthorizationDecision{Role: role, Reason: AuthorizationReasonRoleDenied}
+	}
+	return AuthorizationDecision{Allowed: true, Role: role}
+}
+
+// scope 资源所属、持有协作者记录的 Space/Base ID（资源不存在时为空）
+func (b *AuthorizationBatch) scope(ctx context.Context, resource AuthorizationResource, resourceID string) string {
+	switch resource {
+	case AuthorizationResourceSpace, AuthorizationResourceBase:
+		return resourceID
+	case AuthorizationResourceTable:
+		return b.tableBaseID(ctx, resourceID)
+	case AuthorizationResourceField, AuthorizationResourceView:
+		return b.tableBaseID(ctx, b.owner(ctx, resource, resourceID))
+	}
+	return ""
+}
+
+func (b *AuthorizationBatch) tableBaseID(ctx context.Context, tableID string) string {
+	if tableID == "" {
+		return ""
+	}
+	table, err := b.table(ctx, tableID)
+	if err != nil || table == nil {
+		return ""
+	}
+	return table.BaseID()
+}
+
+// table 读取表（查询失败不缓存）
+func (b *AuthorizationBatch) table(ctx context.Context, tableID string) (*tableEntity.Table, error) {
+	b.mu.Lock()
+	table, ok := b.tables[tableID]
+	b.mu.Unlock()
+	if ok {
+		return table, nil
+	}
+
+	table, err := b.permissions.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.tables[tableID] = table
+	b.mu.Unlock()
+	return table, nil
+}
+
+// owner 字段或视图所属的表ID
+func (b *AuthorizationBatch) owner(ctx context.Context, resource AuthorizationResource, resourceID string) string {
+	key := string(resource) + ":" + resourceID
+	b.mu.Lock()
+	tableID, ok := b.owners[key]
+	b.mu.Unlock()
+	if ok {
+		return tableID
+	}
+
+	if resource == AuthorizationResourceField {
+		field, err := b.permissions.fieldRepo.FindByID(ctx, valueobject.NewFieldID(resourceID))
+		if err != nil {
+			return ""
+		}
+		if field != nil {
+			tableID = field.TableID()
+		}
+	} else {
+		view, err := b.permissions.viewRepo.FindByID(ctx, resourceID)
+		if err != nil {
+			return ""
+		}
+		if view != nil {
+			tableID = view.TableID()
+		}
+	}
+	b.mu.Lock()
+	b.owners[key] = tableID
+	b.mu.Unlock()
+	return tableID
+}
+
+// role 用户在 Space/Base 上的协作者角色（不是协作者的结果同样缓存）
+func (b *AuthorizationBatch) role(ctx context.Context, resourceID, userID string) (entity.RoleName, error) {
+	key := resourceID + "\x00" + userID
+	b.mu.Lock()
+	cached, ok := b.roles[key]
+	b.mu.Unlock()
+	if ok {
+		return cached.role, cached.err
+	}
+
+	var resolved batchRole
+	collaborator, err := b.permissions.collaboratorRepo.FindByResourceAndPrincipal(ctx, resourceID, userID)
+	switch {
+	case err != nil:
+		resolved.err = err
+	case collaborator != nil:
+		resolved.role = collaborator.Role()
+	}
+	b.mu.Lock()
+	b.roles[key] = resolved
+	b.mu.Unlock()
+	return resolved.role, resolved.err
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/permission"
+	collabEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	collabRepo "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/repository"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// countingTableRepo 按ID返回表并统计查询次数
+type countingTableRepo struct {
+	tableRepo.TableRepository
+	tables map[string]*tableEntity.Table
+	calls  int
+}
+
+func (r *countingTableRepo) GetByID(_ context.Context, id string) (*tableEntity.Table, error) {
+	r.calls++
+	return r.tables[id], nil
+}
+
+// countingCollaboratorRepo 按（资源, 用户）返回协作者并统计查询次数
+type countingCollaboratorRepo struct {
+	collabRepo.CollaboratorRepository
+	collaborators map[string]*collabEntity.Collaborator
+	calls         int
+}
+
+func (r *countingCollaboratorRepo) FindByResourceAndPrincipal(_ context.Context, resourceID, principalID string) (*collabEntity.Collaborator, error) {
+	r.calls++
+	if c, ok := r.collaborators[resourceID+"/"+principalID]; ok {
+		return c, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+type batchAuthorizationFixture struct {
+	tables        *countingTableRepo
+	collaborators *countingCollaboratorRepo
+	permissions   *PermissionServiceV2
+	service       *BatchAuthorizationService
+	tableID       string
+}
+
+func newBatchAuthorizationFixture(t *testing.T) *batchAuthorizationFixture {
+	t.Helper()
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	name, _ := tableVO.NewTableName("订单")
+	table, err := tableEntity.NewTable("bse1", name, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	fx := &batchAuthorizationFixture{
+		tables:        &countingTableRepo{tables: map[string]*tableEntity.Table{table.ID().String(): table}},
+		collaborators: &countingCollaboratorRepo{collaborators: map[string]*collabEntity.Collaborator{}},
+		tableID:       table.ID().String(),
+	}
+	for user, role := range map[string]collabEntity.RoleName{"usr1": collabEntity.RoleEditor, "usr2": collabEntity.RoleViewer} {
+		c, err := collabEntity.NewCollaborator("bse1", collabEntity.ResourceTypeBase, user, collabEntity.PrincipalTypeUser, role, "usr1")
+		if err != nil {
+			t.Fatalf("创建协作者失败: %v", err)
+		}
+		fx.collaborators.collaborators["bse1/"+user] = c
+	}
+	fx.permissions = NewPermissionServiceV2(fx.collaborators, nil, nil, fx.tables, nil, nil)
+	fx.service = NewBatchAuthorizationService(fx.permissions)
+	return fx
+}
+
+func TestBatchAuthorizationResolvesOncePerPass(t *testing.T) {
+	fx := newBatchAuthorizationFixture(t)
+	ctx := context.Background()
+
+	var checks []AuthorizationCheck
+	for i := 0; i < 200; i++ {
+		checks = append(checks,
+			AuthorizationCheck{UserID: "usr1", Action: permission.ActionRecordUpdate, Resource: AuthorizationResourceTable, ResourceID: fx.tableID},
+			AuthorizationCheck{UserID: "usr2", Action: permission.ActionRecordDelete, Resource: AuthorizationResourceTable, ResourceID: fx.tableID},
+		)
+	}
+	checks = append(checks,
+		AuthorizationCheck{UserID: "usr3", Action: permission.ActionRecordRead, Resource: AuthorizationResourceTable, ResourceID: fx.tableID},
+		AuthorizationCheck{UserID: "usr1", Action: permission.ActionRecordRead, Resource: AuthorizationResourceTable, ResourceID: "tblMissing"},
+		AuthorizationCheck{UserID: "usr2", Action: permission.ActionBaseRead, Resource: AuthorizationResourceBase, ResourceID: "bse1"},
+	)
+
+	decisions := fx.service.Authorize(ctx, checks)
+	if len(decisions) != len(checks) {
+		t.Fatalf("结果应与校验一一对应，得到 %d 个", len(decisions))
+	}
+	if !decisions[0].Allowed || decisions[0].Role != collabEntity.RoleEditor {
+		t.Fatalf("编辑者应可以更新记录，得到 %+v", decisions[0])
+	}
+	if decisions[1].Allowed || decisions[1].Reason != AuthorizationReasonRoleDenied {
+		t.Fatalf("查看者不能删除记录，得到 %+v", decisions[1])
+	}
+	n := len(decisions)
+	if decisions[n-3].Reason != AuthorizationReasonNotCollaborator {
+		t.Fatalf("非协作者应被拒绝，得到 %+v", decisions[n-3])
+	}
+	if decisions[n-2].Reason != AuthorizationReasonNotFound {
+		t.Fatalf("不存在的表应被拒绝，得到 %+v", decisions[n-2])
+	}
+	if !decisions[n-1].Allowed {
+		t.Fatalf("查看者应可以读取 Base，得到 %+v", decisions[n-1])
+	}
+
+	// 两张表各查询一次；bse1 上三个用户各解析一次角色
+	if fx.tables.calls != 2 || fx.collaborators.calls != 3 {
+		t.Fatalf("每个表与角色应只解析一次，得到表 %d 次、角色 %d 次", fx.tables.calls, fx.collaborators.calls)
+	}
+}
+
+func TestPermissionChecksReuseAuthorizationBatch(t *testing.T) {
+	fx := newBatchAuthorizationFixture(t)
+	ctx := WithAuthorizationBatch(context.Background(), fx.service.NewBatch())
+
+	for i := 0; i < 50; i++ {
+		if !fx.permissions.CanUpdateRecordsInTable(ctx, "usr1", fx.tableID) {
+			t.Fatal("编辑者应可以更新记录")
+		}
+		if role, ok := fx.permissions.TableRole(ctx, "usr2", fx.tableID); !ok || role != string(collabEntity.RoleViewer) {
+			t.Fatalf("应返回查看者角色，得到 %q", role)
+		}
+	}
+	if fx.tables.calls != 1 || fx.collaborators.calls != 2 {
+		t.Fatalf("批次内的逐条检查应复用解析结果，得到表 %d 次、角色 %d 次", fx.tables.calls, fx.collaborators.calls)
+	}
+
+	// 未附加批次时照常逐条查询
+	fx.permissions.CanUpdateRecordsInTable(context.Background(), "usr1", fx.tableID)
+	if fx.tables.calls != 2 || fx.collaborators.calls != 3 {
+		t.Fatalf("未附加批次时应直接查询，得到表 %d 次、角色 %d 次", fx.tables.calls, fx.collaborators.calls)
+	}
+}
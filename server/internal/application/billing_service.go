@@ -0,0 +1,283 @@
+package application
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/stripe"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var billingLog = logger.Named("billing")
+
+// planUpdatedByStripe Stripe 同步的套餐变更记录的变更人
+const planUpdatedByStripe = "stripe"
+
+// CreatePortalSessionRequest 创建客户门户会话请求
+type CreatePortalSessionRequest struct {
+	ReturnURL string `json:"return_url"`
+}
+
+// PortalSession 客户门户会话
+type PortalSession struct {
+	URL string `json:"url"`
+}
+
+// BillingService Stripe 订阅同步 ✨
+// 订阅的创建、变更、取消与扣款结果通过 webhook 到达，按价格映射为工作空间套餐；
+// 事件按ID去重并忽略早于已应用事件的旧事件。期末降级与欠费超过宽限期的降级由后台定时生效
+type BillingService struct {
+	repo              subscription.Repository
+	plans             *PlanService
+	portal            subscription.Portal
+	permissionService *PermissionServiceV2
+	cfg               config.StripeConfig
+	policy            subscription.Policy
+	now               func() time.Time
+}
+
+// NewBillingService 创建 Stripe 订阅同步服务；portal 为 nil 时不提供客户门户
+func NewBillingService(
+	repo subscription.Repository,
+	plans *PlanService,
+	portal subscription.Portal,
+	permissionService *PermissionServiceV2,
+	cfg config.StripeConfig,
+) *BillingService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Minute
+	}
+	prices := make(map[string]string, len(cfg.PlanPrices))
+	for planID, priceID := range cfg.PlanPrices {
+		if _, ok := plan.Find(planID); !ok || priceID == "" {
+			billingLog.Warn(context.Background(), "忽略无效的 Stripe 价格配置", logger.String("plan_id", planID))
+			continue
+		}
+		prices[priceID] = planID
+	}
+	return &BillingService{
+		repo:              repo,
+		plans:             plans,
+		portal:            portal,
+		permissionService: permissionService,
+		cfg:               cfg,
+		policy: subscription.Policy{
+			Prices:               prices,
+			DefaultPlan:          plans.cfg.DefaultPlan,
+			DowngradeAtPeriodEnd: cfg.DowngradeAtPeriodEnd,
+			PastDueGrace:         cfg.PastDueGrace,
+		},
+		now: time.Now,
+	}
+}
+
+// ==================== Webhook ====================
+
+// HandleStripeWebhook 校验签名并处理 Stripe 事件
+// 不处理的事件类型、重复事件与无法对应到工作空间的事件直接确认，避免 Stripe 反复重试
+func (s *BillingService) HandleStripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	if s.cfg.WebhookSecret == "" {
+		return pkgerrors.ErrFeatureNotAvailable.WithDetails("未配置 Stripe webhook")
+	}
+	if err := stripe.VerifySignature(payload, signature, s.cfg.WebhookSecret, s.cfg.SignatureTolerance, s.now()); err != nil {
+		return pkgerrors.ErrBadRequest.WithDetails("Stripe 签名无效")
+	}
+	ev, err := stripe.ParseEvent(payload)
+	if err != nil {
+		return pkgerrors.ErrBadRequest.WithDetails(err.Error())
+	}
+	if !subscription.Supported(ev.Type) {
+		return nil
+	}
+
+	processed, err := s.repo.EventProcessed(ctx, ev.ID)
+	if err != nil {
+		return pkgerrors.Database(err, "检查 Stripe 事件失败")
+	}
+	if processed {
+		billingLog.Debug(ctx, "忽略重复的 Stripe 事件", logger.String("event_id", ev.ID))
+		return nil
+	}
+	if err := s.apply(ctx, ev); err != nil {
+		return err
+	}
+	if err := s.repo.RecordEvent(ctx, ev.ID, ev.Type, s.now()); err != nil {
+		return pkgerrors.Database(err, "记录 Stripe 事件失败")
+	}
+	return nil
+}
+
+// apply 将事件应用到工作空间的订阅并同步套餐
+func (s *BillingService) apply(ctx context.Context, ev *subscription.Event) error {
+	sub, err := s.findSubscription(ctx, ev)
+	if err != nil {
+		return pkgerrors.Database(err, "获取订阅失败")
+	}
+	if sub == nil {
+		billingLog.Warn(ctx, "Stripe 事件无法对应到工作空间",
+			logger.String("event_id", ev.ID),
+			logger.String("type", ev.Type),
+			logger.String("customer_id", ev.CustomerID),
+			logger.String("subscription_id", ev.SubscriptionID))
+		return nil
+	}
+	if sub.Stale(ev) || sub.Replaced(ev) {
+		billingLog.Info(ctx, "忽略过期或属于旧订阅的 Stripe 事件",
+			logger.String("event_id", ev.ID),
+			logger.String("space_id", sub.SpaceID))
+		return nil
+	}
+
+	planID, err := sub.Apply(ev, s.policy)
+	if err != nil {
+		billingLog.Warn(ctx, "Stripe 订阅的价格未映射到套餐，套餐保持不变",
+			logger.String("event_id", ev.ID),
+			logger.String("space_id", sub.SpaceID),
+			logger.ErrorField(err))
+	}
+	return s.save(ctx, sub, planID)
+}
+
+// save 保存订阅，套餐有变化时同步到工作空间
+func (s *BillingService) save(ctx context.Context, sub *subscription.Subscription, planID string) error {
+	if planID != "" {
+		if err := s.plans.setPlan(ctx, sub.SpaceID, planID, planUpdatedByStripe); err != nil {
+			return err
+		}
+	}
+	sub.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, sub); err != nil {
+		return pkgerrors.Database(err, "保存订阅失败")
+	}
+	return nil
+}
+
+// findSubscription 按 metadata.space_id、订阅ID、客户ID依次查找订阅
+// 带 space_id 的首个事件为工作空间创建订阅，初始套餐为工作空间当前的套餐
+func (s *BillingService) findSubscription(ctx context.Context, ev *subscription.Event) (*subscription.Subscription, error) {
+	if ev.SpaceID != "" {
+		sub, err := s.repo.FindBySpace(ctx, ev.SpaceID)
+		if err != nil || sub != nil {
+			return sub, err
+		}
+		current, err := s.plans.planFor(ctx, ev.SpaceID)
+		if err != nil {
+			return nil, err
+		}
+		return subscription.NewSubscription(ev.SpaceID, current.ID), nil
+	}
+	if ev.SubscriptionID != "" {
+		sub, err := s.repo.FindBySubscriptionID(ctx, ev.SubscriptionID)
+		if err != nil || sub != nil {
+			return sub, err
+		}
+	}
+	if ev.CustomerID != "" {
+		return s.repo.FindByCustomerID(ctx, ev.CustomerID)
+	}
+	return nil, nil
+}
+
+// ==================== 到期变更 ====================
+
+// Start 启动期末降级与欠费降级的定时检查
+func (s *BillingService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.applyDue(ctx)
+		}
+	}()
+}
+
+// applyDue 生效到期的套餐变更
+func (s *BillingService) applyDue(ctx context.Context) {
+	subs, err := s.repo.ListDue(ctx)
+	if err != nil {
+		billingLog.Warn(ctx, "获取待生效的订阅失败", logger.ErrorField(err))
+		return
+	}
+	now := s.now()
+	for _, sub := range subs {
+		planID := sub.Due(s.policy, now)
+		if planID == "" {
+			continue
+		}
+		if err := s.save(ctx, sub, planID); err != nil {
+			billingLog.Warn(ctx, "生效订阅套餐变更失败", logger.String("space_id", sub.SpaceID), logger.ErrorField(err))
+			continue
+		}
+		billingLog.Info(ctx, "订阅套餐变更已生效",
+			logger.String("space_id", sub.SpaceID),
+			logger.String("plan_id", planID))
+	}
+}
+
+// ==================== 客户门户 ====================
+
+// GetSubscription 获取工作空间的订阅（仅空间所有者，未订阅时返回 nil）
+func (s *BillingService) GetSubscription(ctx context.Context, userID, spaceID string) (*subscription.Subscription, error) {
+	if err := s.checkOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.FindBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取订阅失败")
+	}
+	return sub, nil
+}
+
+// CreatePortalSession 为工作空间的 Stripe 客户创建门户会话（仅空间所有者）
+func (s *BillingService) CreatePortalSession(ctx context.Context, userID, spaceID string, req CreatePortalSessionRequest) (*PortalSession, error) {
+	if err := s.checkOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	if s.portal == nil {
+		return nil, pkgerrors.ErrFeatureNotAvailable.WithDetails("未配置 Stripe")
+	}
+	returnURL := req.ReturnURL
+	if returnURL == "" {
+		returnURL = s.cfg.PortalReturnURL
+	}
+	if returnURL != "" {
+		if u, err := url.Parse(returnURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("return_url 必须是 http(s) 地址")
+		}
+	}
+
+	sub, err := s.repo.FindBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取订阅失败")
+	}
+	if sub == nil || sub.CustomerID == "" {
+		return nil, pkgerrors.ErrNotFound.WithDetails("工作空间尚未绑定 Stripe 客户")
+	}
+
+	portalURL, err := s.portal.CreatePortalSession(ctx, sub.CustomerID, returnURL)
+	if err != nil {
+		billingLog.Error(ctx, "创建 Stripe 客户门户会话失败", logger.String("space_id", spaceID), logger.ErrorField(err))
+		return nil, pkgerrors.ErrInternalServer.WithDetails("创建客户门户会话失败")
+	}
+	return &PortalSession{URL: portalURL}, nil
+}
+
+func (s *BillingService) checkOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以管理订阅")
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var businessCalendarLog = logger.Named("business_calendar")
+
+// businessCalendarTTL 工作日历缓存时间（公式计算与过滤按表频繁读取，避免逐次查库）
+const businessCalendarTTL = time.Minute
+
+// businessCalendarResolver 解析表所属的工作空间（由 SecurityService 实现，带缓存）
+type businessCalendarResolver interface {
+	ResolveSpace(ctx context.Context, refs ResourceRefs) string
+}
+
+// SaveBusinessCalendarRequest 保存工作日历请求（整体替换）
+type SaveBusinessCalendarRequest struct {
+	Weekends []int    `json:"weekends"` // 每周休息日（0 为周日 … 6 为周六），不传时为周六、周日
+	Holidays []string `json:"holidays"` // 节假日（YYYY-MM-DD）
+	Workdays []string `json:"workdays"` // 调休上班的日期（YYYY-MM-DD）
+}
+
+type cachedBusinessCalendar struct {
+	calendar  *businesscalendar.Calendar
+	expiresAt time.Time
+}
+
+// BusinessCalendarService 工作日历服务 ✨
+// 按工作空间配置每周休息日、节假日与调休工作日，供以下场景按工作日计算日期：
+//   - 公式 WORKDAY / WORKDAY_DIFF / NETWORKDAYS
+//   - 记录模板（及重复记录）中的工作日偏移，如 @today+3bd
+//   - 视图过滤的 isOverdueWorkdays（逾期超过 N 个工作日）
+//
+// 未配置的空间使用默认日历（周六、周日休息）。日历按空间缓存，修改后本实例立即生效，
+// 其他实例在缓存过期后生效；已保存的公式值在下次重新计算时使用新的日历
+type BusinessCalendarService struct {
+	repo              businesscalendar.Repository
+	resolver          businessCalendarResolver
+	permissionService *PermissionServiceV2
+	now               func() time.Time
+
+	calendars sync.Map // spaceID -> cachedBusinessCalendar
+}
+
+// NewBusinessCalendarService 创建工作日历服务
+func NewBusinessCalendarService(
+	repo businesscalendar.Repository,
+	resolver businessCalendarResolver,
+	permissionService *PermissionServiceV2,
+) *BusinessCalendarService {
+	return &BusinessCalendarService{
+		repo:              repo,
+		resolver:          resolver,
+		permissionService: permissionService,
+		now:               time.Now,
+	}
+}
+
+// GetCalendar 获取工作空间的工作日历（未配置时返回默认日历）
+func (s *BusinessCalendarService) GetCalendar(ctx context.Context, userID, spaceID string) (*businesscalendar.Calendar, error) {
+	if !s.permissionService.CanAccessSpace(ctx, userID, spaceID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限查看该空间的工作日历")
+	}
+	calendar, err := s.repo.Get(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取工作日历失败")
+	}
+	if calendar == nil {
+		calendar = businesscalendar.Default(spaceID)
+	}
+	return calendar, nil
+}
+
+// SaveCalendar 保存工作空间的工作日历（需要空间的管理权限）
+func (s *BusinessCalendarService) SaveCalendar(ctx context.Context, userID, spaceID string, req SaveBusinessCalendarRequest) (*businesscalendar.Calendar, error) {
+	if !s.permissionService.CanUpdateSpace(ctx, userID, spaceID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该空间的工作日历")
+	}
+	calendar, err := businesscalendar.NewCalendar(spaceID, req.Weekends, req.Holidays, req.Workdays, userID, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, calendar); err != nil {
+		return nil, pkgerrors.Database(err, "保存工作日历失败")
+	}
+	s.calendars.Delete(spaceID)
+
+	businessCalendarLog.Info(ctx, "工作日历已更新",
+		logger.String("space_id", spaceID),
+		logger.Int("holidays", len(calendar.Holidays)),
+		logger.Int("workdays", len(calendar.Workdays)),
+		logger.String("user_id", userID))
+	return calendar, nil
+}
+
+// ResetCalendar 删除工作空间的工作日历，恢复默认日历（需要空间的管理权限）
+func (s *BusinessCalendarService) ResetCalendar(ctx context.Context, userID, spaceID string) (*businesscalendar.Calendar, error) {
+	if !s.permissionService.CanUpdateSpace(ctx, userID, spaceID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该空间的工作日历")
+	}
+	if err := s.repo.Delete(ctx, spaceID); err != nil {
+		return nil, pkgerrors.Database(err, "删除工作日历失败")
+	}
+	s.calendars.Delete(spaceID)
+	return businesscalendar.Default(spaceID), nil
+}
+
+// ForTable 表所属工作空间的工作日历（带缓存，查找失败时为默认日历）
+func (s *BusinessCalendarService) ForTable(ctx context.Context, tableID string) *businesscalendar.Calendar {
+	spaceID := ""
+	if tableID != "" {
+		spaceID = s.resolver.ResolveSpace(ctx, ResourceRefs{TableID: tableID})
+	}
+	return s.ForSpace(ctx, spaceID)
+}
+
+// ForSpace 工作空间的工作日历（带缓存，查找失败时为默认日历）
+func (s *BusinessCalendarService) ForSpace(ctx context.Context, spaceID string) *businesscalendar.Calendar {
+	if spaceID == "" {
+		return businesscalendar.Default("")
+	}
+	now := s.now()
+	if v, ok := s.calendars.Load(spaceID); ok {
+		if cached := v.(cachedBusinessCalendar); now.Before(cached.expiresAt) {
+			return cached.calendar
+		}
+	}
+	calendar, err := s.repo.Get(ctx, spaceID)
+	if err != nil {
+		businessCalendarLog.Warn(ctx, "获取工作日历失败，使用默认日历",
+			logger.String("space_id", spaceID), logger.ErrorField(err))
+		return businesscalendar.Default(spaceID)
+	}
+	if calendar == nil {
+		calendar = businesscalendar.Default(spaceID)
+	}
+	s.calendars.Store(spaceID, cachedBusinessCalendar{calendar: calendar, expiresAt: now.Add(businessCalendarTTL)})
+	return calendar
+}
+
+// workdayCalendar 表所属空间的工作日历：注入了工作日历服务时直接查找，否则按请求 context 查找（均无时为默认日历）
+func workdayCalendar(ctx context.Context, calendars *BusinessCalendarService, tableID string) *businesscalendar.Calendar {
+	if calendars != nil {
+		return calendars.ForTable(ctx, tableID)
+	}
+	return businesscalendar.ForTable(ctx, tableID)
+}
@@ -2,11 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 )
 
 // CacheService 统一缓存服务
@@ -82,6 +85,8 @@ func NewCacheService(
 // Get 获取缓存（多级缓存策略）
 func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) error {
 	fullKey := s.buildKey(key)
+	ctx, span := tracing.Start(ctx, "cache.get", tracing.WithAttributes(tracing.String("cache.key", fullKey)))
+	defer span.End()
 
 	// 1. 先尝试本地缓存
 	if s.config.EnableLocalCache && s.localCache != nil {
@@ -91,6 +96,8 @@ func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) er
 				*destPtr = value
 			}
 			s.logCacheHit("local", key)
+			span.SetAttributes(tracing.Bool("cache.hit", true), tracing.String("cache.layer", "local"))
+			metrics.CacheRequestsTotal.WithLabelValues("local", "hit").Inc()
 			return nil
 		}
 	}
@@ -103,17 +110,30 @@ func (s *CacheService) Get(ctx context.Context, key string, dest interface{}) er
 				s.localCache.Set(fullKey, dest, s.config.LocalCacheTTL)
 			}
 			s.logCacheHit("redis", key)
+			span.SetAttributes(tracing.Bool("cache.hit", true), tracing.String("cache.layer", "redis"))
+			metrics.CacheRequestsTotal.WithLabelValues("redis", "hit").Inc()
 			return nil
+		} else if errors.Is(err, cache.ErrCacheUnavailable) {
+			// 后端不可用时返回原始错误，便于调用方（如熔断器）区分未命中
+			span.SetAttributes(tracing.Bool("cache.hit", false))
+			metrics.CacheRequestsTotal.WithLabelValues("redis", "error").Inc()
+			return err
 		}
 	}
 
 	s.logCacheMiss(key)
+	span.SetAttributes(tracing.Bool("cache.hit", false))
+	metrics.CacheRequestsTotal.WithLabelValues("all", "miss").Inc()
 	return cache.ErrCacheNotFound
 }
 
 // Set 设置缓存（多级缓存策略）
-func (s *CacheService) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+func (s *CacheService) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) (err error) {
 	fullKey := s.buildKey(key)
+	ctx, span := tracing.Start(ctx, "cache.set", tracing.WithAttributes(
+		tracing.String("cache.key", fullKey),
+		tracing.String("cache.strategy", s.config.CacheStrategy)))
+	defer func() { span.Finish(err) }()
 
 	// 设置TTL
 	if ttl == 0 {
@@ -134,7 +154,10 @@ func (s *CacheService) Set(ctx context.Context, key string, value interface{}, t
 }
 
 // Delete 删除缓存（多级缓存）
-func (s *CacheService) Delete(ctx context.Context, keys ...string) error {
+func (s *CacheService) Delete(ctx context.Context, keys ...string) (err error) {
+	ctx, span := tracing.Start(ctx, "cache.delete", tracing.WithAttributes(tracing.Int("cache.keys", len(keys))))
+	defer func() { span.Finish(err) }()
+
 	fullKeys := make([]string, len(keys))
 	for i, key := range keys {
 		fullKeys[i] = s.buildKey(key)
@@ -159,7 +182,10 @@ func (s *CacheService) Delete(ctx context.Context, keys ...string) error {
 }
 
 // InvalidatePattern 按模式删除缓存
-func (s *CacheService) InvalidatePattern(ctx context.Context, pattern string) error {
+func (s *CacheService) InvalidatePattern(ctx context.Context, pattern string) (err error) {
+	ctx, span := tracing.Start(ctx, "cache.invalidate", tracing.WithAttributes(tracing.String("cache.pattern", pattern)))
+	defer func() { span.Finish(err) }()
+
 	// 删除Redis缓存
 	if s.config.EnableRedisCache && s.redisCache != nil {
 		if err := s.redisCache.DeletePattern(ctx, pattern); err != nil {
@@ -179,6 +205,24 @@ func (s *CacheService) InvalidatePattern(ctx context.Context, pattern string) er
 	return nil
 }
 
+// Flush 清空全部缓存（本地缓存与 luckdb: 前缀下的 Redis 键）
+func (s *CacheService) Flush(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "cache.flush")
+	defer func() { span.Finish(err) }()
+
+	if s.config.EnableRedisCache && s.redisCache != nil {
+		if err := s.redisCache.DeletePattern(ctx, s.buildKey("*")); err != nil {
+			return s.errorService.HandleDatabaseError(ctx, "CacheFlush", err)
+		}
+	}
+	if s.localCache != nil {
+		s.localCache.Clear()
+	}
+
+	logger.Info("cache flushed")
+	return nil
+}
+
 // InvalidateTableCache 使表格相关缓存失效
 func (s *CacheService) InvalidateTableCache(ctx context.Context, tableID string) error {
 	patterns := []string{
@@ -257,7 +301,7 @@ func (s *CacheService) writeBehind(ctx context.Context, key string, value interf
 	// 异步写入Redis
 	if s.config.EnableRedisCache && s.redisCache != nil {
 		go func() {
-			asyncCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			asyncCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 5*time.Second)
 			defer cancel()
 
 			if err := s.redisCache.Set(asyncCtx, key, value, ttl); err != nil {
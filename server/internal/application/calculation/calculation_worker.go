@@ -10,7 +10,10 @@ import (
 	calculationEvent "github.com/easyspace-ai/luckdb/server/internal/domain/calculation/event"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 )
 
 // CalculationWorker 计算工作器
@@ -83,6 +86,7 @@ type CalculationTask struct {
 	RequestedBy string
 	CreatedAt   time.Time
 	RetryCount  int
+	Trace       tracing.SpanContext // 提交方的链路上下文（跨队列传播）
 }
 
 // Worker 工作器
@@ -188,6 +192,14 @@ func (w *CalculationWorker) Stop() error {
 	return nil
 }
 
+// SubmitTaskWithContext 提交计算任务并携带 ctx 中的链路上下文
+func (w *CalculationWorker) SubmitTaskWithContext(ctx context.Context, task *CalculationTask) error {
+	if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+		task.Trace = sc
+	}
+	return w.SubmitTask(task)
+}
+
 // SubmitTask 提交计算任务
 func (w *CalculationWorker) SubmitTask(task *CalculationTask) error {
 	select {
@@ -272,12 +284,29 @@ func (worker *Worker) Start() {
 	}
 }
 
+// jobResult 任务结果标签
+func jobResult(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "succeeded"
+}
+
 // processTask 处理计算任务
 func (worker *Worker) processTask(task *CalculationTask) {
 	startTime := time.Now()
 	ctx, cancel := context.WithTimeout(worker.Worker.ctx, worker.Worker.config.TaskTimeout)
 	defer cancel()
 
+	ctx, span := tracing.Start(tracing.ContextWithRemoteSpanContext(ctx, task.Trace), "calculation.process",
+		tracing.WithSpanKind(tracing.SpanKindConsumer),
+		tracing.WithAttributes(
+			tracing.String("luckdb.task_id", task.ID),
+			tracing.String("luckdb.table_id", task.TableID),
+			tracing.String("luckdb.record_id", task.RecordID.String()),
+			tracing.Int("luckdb.retry_count", task.RetryCount)))
+	defer span.End()
+
 	logger.Info("开始处理计算任务",
 		logger.Int("worker_id", worker.ID),
 		logger.String("task_id", task.ID),
@@ -297,6 +326,9 @@ func (worker *Worker) processTask(task *CalculationTask) {
 	// 执行计算
 	err := worker.executeCalculation(ctx, task)
 	duration := time.Since(startTime)
+	span.RecordError(err)
+	metrics.JobDuration.WithLabelValues("calculation").Observe(duration.Seconds())
+	metrics.JobsProcessedTotal.WithLabelValues("calculation", jobResult(err)).Inc()
 
 	// 更新统计
 	worker.Worker.mu.Lock()
@@ -327,8 +359,8 @@ func (worker *Worker) processTask(task *CalculationTask) {
 		)
 		worker.Worker.eventBus.Publish(ctx, failedEvent)
 
-		// 重试逻辑
-		if task.RetryCount < worker.Worker.config.MaxRetries {
+		// 重试逻辑（明确不可重试的错误直接放弃）
+		if task.RetryCount < worker.Worker.config.MaxRetries && !pkgerrors.IsPermanent(err) {
 			task.RetryCount++
 			time.Sleep(worker.Worker.config.RetryDelay)
 			worker.Worker.SubmitTask(task)
@@ -10,6 +10,7 @@ import (
 	formulaPkg "github.com/easyspace-ai/luckdb/server/internal/domain/calculation/formula"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/lookup"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/rollup"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
@@ -18,6 +19,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/events"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
 )
 
 // CalculationService 计算服务（对齐原版ReferenceService）
@@ -63,6 +65,7 @@ type CalculationService struct {
 	rollupCalculator *rollup.RollupCalculator
 	lookupCalculator *lookup.LookupCalculator
 	businessEvents   events.BusinessEventPublisher // ✨ 业务事件发布器
+	businessCalendars *BusinessCalendarService     // ✨ 工作日历（WORKDAY / NETWORKDAYS）
 	
 	// ✅ 性能优化：依赖图缓存
 	depGraphCache map[string]*dependencyGraphCacheEntry // tableID -> 缓存项
@@ -296,12 +299,12 @@ func (s *CalculationService) CalculateRecordFieldsWithFields(ctx context.Context
 		// 注意：这里使用Update会增加版本号，实际应该有单独的UpdateData方法
 		// 暂时使用Update，后续可优化
 		if err := record.Update(updatedData, "system"); err != nil {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 
 		// 保存
 		if err := s.recordRepo.Save(ctx, record); err != nil {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 
 		logger.Info("virtual fields calculated successfully",
@@ -448,7 +451,7 @@ func (s *CalculationService) CalculateAffectedFields(ctx context.Context, record
 			logger.Error("❌ Record.Update 失败",
 				logger.String("record_id", record.ID().String()),
 				logger.ErrorField(err))
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 
 		logger.Info("✅ affected fields recalculated (内存更新完成，由调用方事务负责保存)",
@@ -570,18 +573,19 @@ func (s *CalculationService) calculateFormula(
 
 	// 2. 执行公式计算（使用formula包的Evaluate函数）
 	// Evaluate返回 (*TypedValue, error)
-	// 获取用户时区配置（默认使用UTC）
-	timezone := "UTC" // 默认时区，后续可以从用户配置获取
+	// 字段配置了时区时以字段为准，否则使用请求用户的时区（TODAY()/NOW() 等据此计算）
+	timezone := calculationTimeZone(ctx, options.Formula.TimeZone)
 
 	logger.Info("🧮 开始公式求值",
 		logger.String("field_id", field.ID().String()),
 		logger.String("expression", expression))
 
-	result, err := formulaPkg.Evaluate(
+	result, err := formulaPkg.EvaluateWithCalendar(
 		expression,
 		recordDataWithNames, // dependencies (使用字段名称映射后的数据)
 		recordDataWithNames, // record context (使用字段名称映射后的数据)
 		timezone,
+		workdayCalendar(ctx, s.businessCalendars, record.TableID()),
 	)
 
 	if err != nil {
@@ -659,7 +663,11 @@ func (s *CalculationService) calculateRollup(
 	}
 
 	// 4. 执行汇总计算
-	result, err := s.rollupCalculator.Calculate(expression, values)
+	calculator := s.rollupCalculator
+	if timezone := calculationTimeZone(ctx, options.Rollup.TimeZone); timezone != "UTC" {
+		calculator = rollup.NewRollupCalculator(timezone)
+	}
+	result, err := calculator.Calculate(expression, values)
 	if err != nil {
 		return nil, errors.ErrValidationFailed.WithDetails(map[string]interface{}{
 			"message":    "rollup calculation failed",
@@ -1159,7 +1167,7 @@ func (s *CalculationService) contains(arr []string, target string) bool {
 	return false
 }
 
-// mapFieldIDsToNames 辅助方法：将recordData的字段ID映射为字段名称
+// mapFieldIDsToNames 辅助方法：将recordData的字段ID映射为字段名称，并按字段类型转换为公式值
 // 添加到 calculation_service.go 的末尾
 
 func (s *CalculationService) mapFieldIDsToNames(
@@ -1210,6 +1218,15 @@ func (s *CalculationService) mapFieldIDsToNames(
 		}
 	}
 
+	// 第三步：按字段类型转换为公式值（日期为 RFC3339 字符串、数字字符串为数字、引用类字段为标题）
+	for _, field := range fields {
+		for _, key := range []string{field.ID().String(), field.Name().String()} {
+			if value, ok := result[key]; ok {
+				result[key] = cellvalue.ToFormula(field.Type().String(), value)
+			}
+		}
+	}
+
 	logger.Info("🎯 mapFieldIDsToNames: 映射完成",
 		logger.Int("input_keys", len(recordData)),
 		logger.Int("output_keys", len(result)),
@@ -1223,3 +1240,18 @@ func (s *CalculationService) mapFieldIDsToNames(
 
 	return result, nil
 }
+
+// SetBusinessCalendarService 设置工作日历服务（WORKDAY / NETWORKDAYS 按表所属空间的日历计算）
+func (s *CalculationService) SetBusinessCalendarService(calendars *BusinessCalendarService) {
+	s.businessCalendars = calendars
+}
+
+// calculationTimeZone 公式与汇总计算使用的时区：字段配置的时区优先，其次为请求用户的时区（默认 UTC）
+func calculationTimeZone(ctx context.Context, fieldTimeZone string) string {
+	if fieldTimeZone != "" {
+		if _, err := time.LoadLocation(fieldTimeZone); err == nil {
+			return fieldTimeZone
+		}
+	}
+	return userlocale.TimeZone(ctx)
+}
@@ -0,0 +1,296 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// cellValidationMaxRows 单次校验的最大行数
+const cellValidationMaxRows = 1000
+
+// 校验模式
+const (
+	CellValidationModeCreate = "create" // 新建记录：同时检查必填字段
+	CellValidationModeUpdate = "update" // 更新记录：只检查给出的字段
+)
+
+// ValidateCellsRequest 批量校验单元格值请求
+type ValidateCellsRequest struct {
+	Rows     []map[string]interface{} `json:"rows" binding:"required"` // 每行按字段ID或名称给出值
+	Mode     string                   `json:"mode"`                    // create（默认）/ update
+	Typecast bool                     `json:"typecast"`                // 与写入时的 typecast 相同：尝试自动转换类型
+}
+
+// CellValidationError 单元格校验错误
+type CellValidationError struct {
+	Row       int         `json:"row"`                 // 行序号（从 0 开始）
+	FieldKey  string      `json:"fieldKey"`            // 请求中的字段ID或名称
+	FieldID   string      `json:"fieldId,omitempty"`   // 字段不存在时为空
+	FieldName string      `json:"fieldName,omitempty"` // 字段不存在时为空
+	Code      string      `json:"code"`                // 与写入失败时的错误码相同
+	Message   string      `json:"message"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// ValidateCellsResult 批量校验结果
+type ValidateCellsResult struct {
+	Valid  bool                     `json:"valid"`
+	Rows   []map[string]interface{} `json:"rows"`   // 校验（及类型转换）后按字段ID的值，与写入时保存的值一致
+	Errors []*CellValidationError   `json:"errors"` // 按行与字段列出的错误
+}
+
+// CellValidationService 单元格值批量校验服务 ✨
+// 按字段类型、选项与约束校验单元格值但不写入，供自动化动作与导入预检数据；
+// 校验规则与记录写入（TypecastService 与必填检查）共用 cellValidator
+type CellValidationService struct {
+	fieldRepo         fieldRepo.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	permissionService *PermissionServiceV2
+	factory           *validation.ValidatorFactory
+}
+
+// NewCellValidationService 创建单元格值批量校验服务
+func NewCellValidationService(
+	fieldRepo fieldRepo.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	permissionService *PermissionServiceV2,
+) *CellValidationService {
+	return &CellValidationService{
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		permissionService: permissionService,
+		factory:           validation.NewValidatorFactory(),
+	}
+}
+
+// ValidateCells 批量校验表中的单元格值（需要对应模式的写入权限）
+func (s *CellValidationService) ValidateCells(ctx context.Context, userID, tableID string, req ValidateCellsRequest) (*ValidateCellsResult, error) {
+	if req.Mode == "" {
+		req.Mode = CellValidationModeCreate
+	}
+	if req.Mode != CellValidationModeCreate && req.Mode != CellValidationModeUpdate {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("mode 只能是 create 或 update")
+	}
+	if len(req.Rows) > cellValidationMaxRows {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("单次最多校验 %d 行", cellValidationMaxRows))
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{"table_id": tableID})
+	}
+	if s.permissionService != nil {
+		allowed := s.permissionService.CanCreateRecordsInTable(ctx, userID, tableID)
+		if req.Mode == CellValidationModeUpdate {
+			allowed = s.permissionService.CanUpdateRecordsInTable(ctx, userID, tableID)
+		}
+		if !allowed {
+			return nil, pkgerrors.ErrForbidden.WithDetails("没有写入该表记录的权限")
+		}
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	v := newCellValidator(s.factory, tableID, fields)
+
+	result := &ValidateCellsResult{
+		Rows:   make([]map[string]interface{}, len(req.Rows)),
+		Errors: []*CellValidationError{},
+	}
+	for i, row := range req.Rows {
+		values, issues := v.validateRow(ctx, row, req.Typecast)
+		if req.Mode == CellValidationModeCreate {
+			for _, field := range v.missingRequired(row) {
+				issues = append(issues, &cellIssue{
+					key:   field.ID().String(),
+					field: field,
+					err:   pkgerrors.ErrFieldRequired.WithDetails(map[string]interface{}{"field": field.Name().String()}),
+				})
+			}
+		}
+		result.Rows[i] = values
+		for _, issue := range issues {
+			result.Errors = append(result.Errors, issue.toError(i))
+		}
+	}
+	result.Errors = append(result.Errors, v.duplicateUniqueValues(result.Rows)...)
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
+// cellIssue 单个单元格的校验问题
+type cellIssue struct {
+	key   string
+	field *entity.Field // 字段不存在时为 nil
+	value interface{}
+	err   *pkgerrors.AppError
+}
+
+func (i *cellIssue) toError(row int) *CellValidationError {
+	e := &CellValidationError{
+		Row:      row,
+		FieldKey: i.key,
+		Code:     i.err.Code,
+		Message:  i.err.Message,
+		Value:    i.value,
+	}
+	if i.field != nil {
+		e.FieldID = i.field.ID().String()
+		e.FieldName = i.field.Name().String()
+	}
+	if detail, ok := i.err.Details.(map[string]interface{}); ok {
+		if msg, ok := detail["error"].(string); ok && msg != "" {
+			e.Message = msg
+		}
+	}
+	return e
+}
+
+// cellValidator 按表的字段校验单元格值
+// 记录写入与 ValidateCells 共用，保证预检结果与实际写入一致
+type cellValidator struct {
+	factory *validation.ValidatorFactory
+	tableID string
+	fields  []*entity.Field
+	byID    map[string]*entity.Field
+	byName  map[string]*entity.Field
+}
+
+func newCellValidator(factory *validation.ValidatorFactory, tableID string, fields []*entity.Field) *cellValidator {
+	v := &cellValidator{
+		factory: factory,
+		tableID: tableID,
+		fields:  fields,
+		byID:    make(map[string]*entity.Field, len(fields)),
+		byName:  make(map[string]*entity.Field, len(fields)),
+	}
+	for _, field := range fields {
+		v.byID[field.ID().String()] = field
+		v.byName[field.Name().String()] = field
+	}
+	return v
+}
+
+// lookup 按字段ID或名称查找字段
+func (v *cellValidator) lookup(key string) *entity.Field {
+	if field, ok := v.byID[key]; ok {
+		return field
+	}
+	return v.byName[key]
+}
+
+// validateRow 校验一行，返回按字段ID的值与问题列表
+// 计算字段只读，与写入时一样被忽略；typecast 为 true 时先尝试修复不合法的值，
+// 无法修复的值仍作为问题返回（写入路径在宽松模式下丢弃这些值）
+func (v *cellValidator) validateRow(ctx context.Context, data map[string]interface{}, typecast bool) (map[string]interface{}, []*cellIssue) {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make(map[string]interface{}, len(data))
+	var issues []*cellIssue
+	for _, key := range keys {
+		value := data[key]
+		field := v.lookup(key)
+		if field == nil {
+			issues = append(issues, &cellIssue{
+				key:   key,
+				value: value,
+				err: pkgerrors.ErrFieldNotFound.WithDetails(map[string]interface{}{
+					"field_key": key,
+					"table_id":  v.tableID,
+				}),
+			})
+			continue
+		}
+		if field.IsComputed() {
+			continue
+		}
+
+		fieldID := field.ID().String()
+		res := v.factory.ValidateField(ctx, value, field)
+		if res.Success {
+			values[fieldID] = res.Value
+			continue
+		}
+		if typecast {
+			if repaired := v.factory.RepairValue(ctx, value, field); repaired != nil {
+				values[fieldID] = repaired
+				continue
+			}
+		}
+		issues = append(issues, &cellIssue{
+			key:   key,
+			field: field,
+			value: value,
+			err:   cellValueError(res.Error, field, value),
+		})
+	}
+	return values, issues
+}
+
+// missingRequired 列出新建记录时缺失或为空的必填字段
+func (v *cellValidator) missingRequired(data map[string]interface{}) []*entity.Field {
+	var missing []*entity.Field
+	for _, field := range v.fields {
+		if field.IsComputed() || !field.IsRequired() {
+			continue
+		}
+		value, exists := data[field.ID().String()]
+		if !exists {
+			value, exists = data[field.Name().String()]
+		}
+		if !exists || value == nil || value == "" {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// duplicateUniqueValues 检查唯一字段在本批数据内的重复值（与已有记录的冲突由写入时的唯一索引保证）
+func (v *cellValidator) duplicateUniqueValues(rows []map[string]interface{}) []*CellValidationError {
+	var errs []*CellValidationError
+	for _, field := range v.fields {
+		if !field.IsUnique() {
+			continue
+		}
+		fieldID := field.ID().String()
+		firstRow := make(map[string]int)
+		for i, row := range rows {
+			value, ok := row[fieldID]
+			if !ok || value == nil || value == "" {
+				continue
+			}
+			key := fmt.Sprint(value)
+			first, seen := firstRow[key]
+			if !seen {
+				firstRow[key] = i
+				continue
+			}
+			errs = append(errs, &CellValidationError{
+				Row:       i,
+				FieldKey:  fieldID,
+				FieldID:   fieldID,
+				FieldName: field.Name().String(),
+				Code:      pkgerrors.ErrDuplicateValue.Code,
+				Message:   fmt.Sprintf("与第 %d 行的值重复", first),
+				Value:     value,
+			})
+		}
+	}
+	return errs
+}
@@ -0,0 +1,160 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// cellValidationFixture 含标题（必填、唯一）、数量、邮箱与公式字段的表
+type cellValidationFixture struct {
+	service *CellValidationService
+	tableID string
+	title   *fieldEntity.Field
+	amount  *fieldEntity.Field
+	email   *fieldEntity.Field
+	formula *fieldEntity.Field
+}
+
+func newCellValidationFixture(t *testing.T) *cellValidationFixture {
+	t.Helper()
+	tableName, _ := tableVO.NewTableName("订单")
+	table, err := tableEntity.NewTable("bse1", tableName, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	fx := &cellValidationFixture{
+		tableID: table.ID().String(),
+		title:   newTemplateTestField(t, "标题", fieldVO.TypeText),
+		amount:  newTemplateTestField(t, "数量", fieldVO.TypeNumber),
+		email:   newTemplateTestField(t, "邮箱", fieldVO.TypeEmail),
+		formula: newTemplateTestField(t, "合计", fieldVO.TypeFormula),
+	}
+	_ = fx.title.SetRequired(true)
+	_ = fx.title.SetUnique(true)
+	fx.service = NewCellValidationService(
+		&stubReorderFieldRepo{fields: []*fieldEntity.Field{fx.title, fx.amount, fx.email, fx.formula}},
+		&stubSemanticTableRepo{table: table},
+		nil,
+	)
+	return fx
+}
+
+func (fx *cellValidationFixture) validate(t *testing.T, req ValidateCellsRequest) *ValidateCellsResult {
+	t.Helper()
+	result, err := fx.service.ValidateCells(context.Background(), "usr1", fx.tableID, req)
+	if err != nil {
+		t.Fatalf("ValidateCells: %v", err)
+	}
+	return result
+}
+
+// errorCodes 按 "行:字段键" 汇总错误码
+func errorCodes(result *ValidateCellsResult) map[string]string {
+	codes := make(map[string]string, len(result.Errors))
+	for _, e := range result.Errors {
+		codes[fmt.Sprintf("%d:%s", e.Row, e.FieldKey)] = e.Code
+	}
+	return codes
+}
+
+func TestCellValidation_ReportsEveryInvalidCell(t *testing.T) {
+	fx := newCellValidationFixture(t)
+	result := fx.validate(t, ValidateCellsRequest{Rows: []map[string]interface{}{
+		{"标题": "A", "数量": 3, "邮箱": "a@example.com"},
+		{"标题": "B", "数量": "many", "邮箱": "not-an-email"},
+		{"数量": 1, "颜色": "red"},
+	}})
+
+	if result.Valid {
+		t.Fatal("expected invalid result")
+	}
+	codes := errorCodes(result)
+	want := map[string]string{
+		"1:数量":                        pkgerrors.ErrInvalidPattern.Code,
+		"1:邮箱":                        pkgerrors.ErrInvalidEmail.Code,
+		"2:颜色":                        pkgerrors.ErrFieldNotFound.Code,
+		"2:" + fx.title.ID().String(): pkgerrors.ErrFieldRequired.Code,
+	}
+	if len(codes) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), codes)
+	}
+	for key, code := range want {
+		if codes[key] != code {
+			t.Fatalf("error %s: expected %s, got %q (all: %v)", key, code, codes[key], codes)
+		}
+	}
+	if got := result.Rows[0][fx.amount.ID().String()]; got == nil {
+		t.Fatalf("expected valid row to be returned by field ID, got %v", result.Rows[0])
+	}
+}
+
+func TestCellValidation_TypecastAndUpdateMode(t *testing.T) {
+	fx := newCellValidationFixture(t)
+	result := fx.validate(t, ValidateCellsRequest{
+		Mode:     CellValidationModeUpdate,
+		Typecast: true,
+		Rows: []map[string]interface{}{
+			{fx.amount.ID().String(): "42"},
+			{fx.formula.ID().String(): 1},
+		},
+	})
+
+	if !result.Valid {
+		t.Fatalf("expected valid result, got %+v", result.Errors)
+	}
+	if v, ok := result.Rows[0][fx.amount.ID().String()].(float64); !ok || v != 42 {
+		t.Fatalf("expected typecast number, got %#v", result.Rows[0])
+	}
+	if _, ok := result.Rows[1][fx.formula.ID().String()]; ok {
+		t.Fatal("computed field values should be ignored like the write path does")
+	}
+}
+
+func TestCellValidation_DuplicateUniqueValuesInBatch(t *testing.T) {
+	fx := newCellValidationFixture(t)
+	result := fx.validate(t, ValidateCellsRequest{Rows: []map[string]interface{}{
+		{"标题": "A"}, {"标题": "B"}, {"标题": "A"},
+	}})
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected one duplicate error, got %+v", result.Errors)
+	}
+	e := result.Errors[0]
+	if e.Row != 2 || e.FieldID != fx.title.ID().String() || e.Code != pkgerrors.ErrDuplicateValue.Code {
+		t.Fatalf("unexpected duplicate error: %+v", e)
+	}
+}
+
+func TestCellValidation_WritePathSharesRules(t *testing.T) {
+	fx := newCellValidationFixture(t)
+	// TypecastService.ValidateAndTypecastRecord 与 ValidateCells 使用同一个 cellValidator
+	v := newCellValidator(fx.service.factory, fx.tableID, []*fieldEntity.Field{fx.title, fx.amount, fx.email, fx.formula})
+
+	_, issues := v.validateRow(context.Background(), map[string]interface{}{"邮箱": "not-an-email"}, false)
+	if len(issues) != 1 || !pkgerrors.Is(issues[0].err, pkgerrors.ErrInvalidEmail) {
+		t.Fatalf("expected strict write to fail with the same code, got %+v", issues)
+	}
+
+	data, issues := v.validateRow(context.Background(), map[string]interface{}{"数量": "42", "颜色": "red"}, true)
+	if len(data) != 1 || data[fx.amount.ID().String()] == nil {
+		t.Fatalf("expected typecast values keyed by field ID, got %v", data)
+	}
+	if len(issues) != 1 || issues[0].key != "颜色" {
+		t.Fatalf("expected the unknown field as the only issue, got %+v", issues)
+	}
+}
+
+func TestCellValidation_RejectsUnknownMode(t *testing.T) {
+	fx := newCellValidationFixture(t)
+	_, err := fx.service.ValidateCells(context.Background(), "usr1", fx.tableID, ValidateCellsRequest{Mode: "upsert"})
+	if !pkgerrors.Is(err, pkgerrors.ErrValidationFailed) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
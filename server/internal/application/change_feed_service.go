@@ -0,0 +1,352 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var changeFeedLog = logger.Named("change_feed")
+
+// changeFeedMaxLimit 变更列表单次返回的最大条数
+const changeFeedMaxLimit = 1000
+
+// ChangeFeedPage 变更流分页
+type ChangeFeedPage struct {
+	Changes    []*changefeed.Change            `json:"changes"`
+	NextCursor int64                           `json:"nextCursor"` // 下次请求的游标（无新变更时与请求游标相同）
+	HasMore    bool                            `json:"hasMore"`
+	Schemas    map[string][]ChangeFeedFieldDef `json:"schemas"` // 本页涉及的表的当前字段定义（按表ID）
+}
+
+// ChangeFeedFieldDef 表结构中的字段定义
+type ChangeFeedFieldDef struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	IsComputed bool   `json:"isComputed"`
+}
+
+// ChangeFeedService 变更流服务 ✨
+// 记录、字段、视图的变更与业务写入一起写入发件箱，后台中继按提交顺序编号；
+// 外部数据仓库与同步工具按 Base 增量拉取，无需轮询全表
+type ChangeFeedService struct {
+	repo              changefeed.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	viewQueries       *ViewQueryCache
+	cfg               config.ChangeFeedConfig
+}
+
+// NewChangeFeedService 创建变更流服务
+func NewChangeFeedService(
+	repo changefeed.Repository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	cfg config.ChangeFeedConfig,
+) *ChangeFeedService {
+	return &ChangeFeedService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		cfg:               cfg,
+	}
+}
+
+// SetViewQueryCache 设置视图查询结果缓存（变更提交后按依赖失效）
+func (s *ChangeFeedService) SetViewQueryCache(viewQueries *ViewQueryCache) {
+	s.viewQueries = viewQueries
+}
+
+// RecordChanged 写入记录变更（在业务事务中调用时随事务提交）
+func (s *ChangeFeedService) RecordChanged(ctx context.Context, event *database.RecordEvent) error {
+	action, ok := recordChangeAction(event.EventType)
+	if !ok {
+		return nil
+	}
+	s.viewQueries.InvalidateTable(ctx, event.TID)
+	change, err := s.newChange(ctx, event.TID, changefeed.EntityRecord, action, event.RID, event.Fields, event.UserID)
+	if err != nil {
+		return err
+	}
+	change.Version = event.NewVersion
+	return s.repo.Append(ctx, change)
+}
+
+// recordChangeAction 记录事件类型对应的变更动作
+func recordChangeAction(eventType string) (changefeed.Action, bool) {
+	switch eventType {
+	case "record.create":
+		return changefeed.ActionCreate, true
+	case "record.update":
+		return changefeed.ActionUpdate, true
+	case "record.delete":
+		return changefeed.ActionDelete, true
+	default:
+		return "", false
+	}
+}
+
+// FieldChanged 写入字段变更，data 为字段定义（删除时可为 nil）
+func (s *ChangeFeedService) FieldChanged(ctx context.Context, action changefeed.Action, tableID, fieldID string, data interface{}, userID string) error {
+	// 新建字段不影响已有的过滤与排序结果
+	if action != changefeed.ActionCreate {
+		s.viewQueries.InvalidateField(ctx, fieldID)
+	}
+	change, err := s.newChange(ctx, tableID, changefeed.EntityField, action, fieldID, data, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Append(ctx, change)
+}
+
+// TransitionChanged 写入记录的状态流转（与记录写入同一事务），data 为流转详情
+func (s *ChangeFeedService) TransitionChanged(ctx context.Context, tableID, recordID string, data interface{}, userID string) error {
+	change, err := s.newChange(ctx, tableID, changefeed.EntityTransition, changefeed.ActionUpdate, recordID, data, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Append(ctx, change)
+}
+
+// ViewChanged 写入视图变更，data 为视图定义（删除时可为 nil）
+func (s *ChangeFeedService) ViewChanged(ctx context.Context, action changefeed.Action, tableID, viewID string, data interface{}, userID string) error {
+	s.viewQueries.InvalidateView(ctx, viewID)
+	change, err := s.newChange(ctx, tableID, changefeed.EntityView, action, viewID, data, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Append(ctx, change)
+}
+
+func (s *ChangeFeedService) newChange(ctx context.Context, tableID string, entityType changefeed.EntityType, action changefeed.Action, entityID string, data interface{}, userID string) (*changefeed.Change, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	var raw json.RawMessage
+	if data != nil {
+		if raw, err = json.Marshal(data); err != nil {
+			return nil, pkgerrors.ErrInternalServer.WithDetails("序列化变更数据失败: " + err.Error())
+		}
+	}
+	return changefeed.NewChange(table.BaseID(), tableID, entityType, action, entityID, raw, userID), nil
+}
+
+// ListChanges 按游标读取 Base 的变更
+func (s *ChangeFeedService) ListChanges(ctx context.Context, userID, baseID string, filter changefeed.ListFilter) (*ChangeFeedPage, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	if filter.Limit <= 0 || filter.Limit > changeFeedMaxLimit {
+		filter.Limit = changeFeedMaxLimit
+	}
+	if filter.After < 0 {
+		filter.After = 0
+	}
+
+	changes, err := s.repo.ListAfter(ctx, baseID, filter)
+	if err != nil {
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			return nil, pkgerrors.ErrConflict.WithDetails("游标之后的变更已超出保留期，请重新全量同步后从最新游标继续")
+		}
+		return nil, pkgerrors.Database(err, "读取变更流失败")
+	}
+
+	page := &ChangeFeedPage{
+		Changes:    changes,
+		NextCursor: filter.After,
+		HasMore:    len(changes) == filter.Limit,
+		Schemas:    map[string][]ChangeFeedFieldDef{},
+	}
+	if len(changes) > 0 {
+		page.NextCursor = changes[len(changes)-1].Seq
+	}
+	s.maskRecordChanges(ctx, changes)
+
+	for _, change := range changes {
+		if _, ok := page.Schemas[change.TableID]; ok {
+			continue
+		}
+		defs, err := s.tableSchema(ctx, change.TableID)
+		if err != nil {
+			return nil, err
+		}
+		page.Schemas[change.TableID] = defs
+	}
+	return page, nil
+}
+
+// Head 返回当前最新游标
+// 全量同步前先记录最新游标，全量导出完成后从该游标增量拉取，不会遗漏导出期间的变更
+func (s *ChangeFeedService) Head(ctx context.Context, userID, baseID string) (int64, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return 0, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	head, err := s.repo.Head(ctx)
+	if err != nil {
+		return 0, pkgerrors.Database(err, "读取变更流游标失败")
+	}
+	return head, nil
+}
+
+// FieldVersion 表结构版本：表最近一次字段变更的序号（没有保留的字段变更时为 0）
+// 须在读取字段之前获取，之后的字段变更序号都大于该版本
+func (s *ChangeFeedService) FieldVersion(ctx context.Context, tableID string) (int64, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return 0, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return 0, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	latest, err := s.repo.ListLatest(ctx, table.BaseID(), changefeed.ListFilter{TableID: tableID, EntityType: changefeed.EntityField, Limit: 1})
+	if err != nil {
+		return 0, pkgerrors.Database(err, "读取变更流失败")
+	}
+	if len(latest) == 0 {
+		return 0, nil
+	}
+	return latest[0].Seq, nil
+}
+
+// FieldChangesAfter 读取表在 after 版本之后的全部字段变更，版本超出保留期时返回 changefeed.ErrCursorExpired
+func (s *ChangeFeedService) FieldChangesAfter(ctx context.Context, tableID string, after int64) ([]*changefeed.Change, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+
+	filter := changefeed.ListFilter{After: after, TableID: tableID, EntityType: changefeed.EntityField, Limit: changeFeedMaxLimit}
+	var changes []*changefeed.Change
+	for {
+		page, err := s.repo.ListAfter(ctx, table.BaseID(), filter)
+		if err != nil {
+			if errors.Is(err, changefeed.ErrCursorExpired) {
+				return nil, err
+			}
+			return nil, pkgerrors.Database(err, "读取变更流失败")
+		}
+		changes = append(changes, page...)
+		if len(page) < filter.Limit {
+			return changes, nil
+		}
+		filter.After = page[len(page)-1].Seq
+	}
+}
+
+// maskRecordChanges 按读取者角色对记录变更中的受保护字段脱敏
+func (s *ChangeFeedService) maskRecordChanges(ctx context.Context, changes []*changefeed.Change) {
+	if s.privacyService == nil {
+		return
+	}
+	for _, change := range changes {
+		if change.EntityType != changefeed.EntityRecord || len(change.Data) == 0 {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(change.Data, &fields); err != nil {
+			continue
+		}
+		resp := &dto.RecordResponse{ID: change.EntityID, TableID: change.TableID, Data: fields}
+		s.privacyService.MaskRecords(ctx, change.TableID, resp)
+		if masked, err := json.Marshal(resp.Data); err == nil {
+			change.Data = masked
+		}
+	}
+}
+
+// tableSchema 表的当前字段定义（表已删除时为空）
+func (s *ChangeFeedService) tableSchema(ctx context.Context, tableID string) ([]ChangeFeedFieldDef, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	defs := make([]ChangeFeedFieldDef, 0, len(fields))
+	for _, field := range fields {
+		defs = append(defs, ChangeFeedFieldDef{
+			ID:         field.ID().String(),
+			Name:       field.Name().String(),
+			Type:       field.Type().String(),
+			IsComputed: field.IsComputed(),
+		})
+	}
+	return defs, nil
+}
+
+// Start 启动发件箱中继与过期变更清理
+func (s *ChangeFeedService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.RelayInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.relay(ctx)
+		}
+	}()
+
+	if s.cfg.Retention <= 0 || s.cfg.CleanupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			deleted, err := s.repo.PurgeBefore(ctx, time.Now().Add(-s.cfg.Retention))
+			if err != nil {
+				changeFeedLog.Warn(ctx, "清理过期变更失败", logger.ErrorField(err))
+				continue
+			}
+			if deleted > 0 {
+				changeFeedLog.Debug(ctx, "已清理过期变更", logger.Int64("count", deleted))
+			}
+		}
+	}()
+}
+
+// relay 为所有已提交的变更编号（积压时连续编号直到清空）
+func (s *ChangeFeedService) relay(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := s.repo.Relay(ctx, s.cfg.RelayBatchSize)
+		if err != nil {
+			changeFeedLog.Warn(ctx, "变更编号失败", logger.ErrorField(err))
+			return
+		}
+		if n < s.cfg.RelayBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+)
+
+func TestRecordChangeAction(t *testing.T) {
+	cases := map[string]changefeed.Action{
+		"record.create": changefeed.ActionCreate,
+		"record.update": changefeed.ActionUpdate,
+		"record.delete": changefeed.ActionDelete,
+	}
+	for eventType, want := range cases {
+		got, ok := recordChangeAction(eventType)
+		if !ok || got != want {
+			t.Errorf("%s 应映射为 %s，得到 %s", eventType, want, got)
+		}
+	}
+
+	if _, ok := recordChangeAction("record.batch"); ok {
+		t.Error("未知的事件类型不应写入变更流")
+	}
+}
@@ -0,0 +1,498 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+const (
+	// chartCacheTTL 图表数据缓存时间（仪表盘组件定时刷新）
+	chartCacheTTL = 30 * time.Second
+	// chartCacheSize 最多缓存的图表查询数
+	chartCacheSize = 500
+	// chartDefaultLimit 默认返回的分组数
+	chartDefaultLimit = 100
+	// chartMaxLimit 最多返回的分组数
+	chartMaxLimit = 1000
+	// chartMaxDimensions 最多维度数
+	chartMaxDimensions = 2
+	// chartMaxMeasures 最多指标数
+	chartMaxMeasures = 10
+)
+
+// 指标聚合方式
+const (
+	ChartAggCount         = "count"         // 记录数（可不指定字段）
+	ChartAggFilled        = "filled"        // 非空值个数
+	ChartAggCountDistinct = "countDistinct" // 去重计数
+	ChartAggSum           = "sum"
+	ChartAggAvg           = "avg"
+	ChartAggMin           = "min"
+	ChartAggMax           = "max"
+)
+
+// chartTimeBuckets 日期维度支持的时间粒度（对应 date_trunc 参数）
+var chartTimeBuckets = map[string]bool{
+	"hour": true, "day": true, "week": true, "month": true, "quarter": true, "year": true,
+}
+
+// ChartDataRequest 图表数据查询请求
+type ChartDataRequest struct {
+	ViewID     string                 `json:"viewId,omitempty"` // 叠加视图的筛选条件
+	Dimensions []ChartDimension       `json:"dimensions"`
+	Measures   []ChartMeasure         `json:"measures"`
+	Filter     map[string]interface{} `json:"filter,omitempty"` // 与视图筛选同结构
+	SortBy     string                 `json:"sortBy,omitempty"` // dimension（默认）/ measure
+	Order      string                 `json:"order,omitempty"`  // asc（默认）/ desc
+	Limit      int                    `json:"limit,omitempty"`
+}
+
+// ChartDimension 分组维度
+type ChartDimension struct {
+	FieldID    string `json:"fieldId"`
+	TimeBucket string `json:"timeBucket,omitempty"` // hour / day / week / month / quarter / year
+}
+
+// ChartMeasure 指标
+type ChartMeasure struct {
+	FieldID     string `json:"fieldId,omitempty"`
+	Aggregation string `json:"aggregation"`
+}
+
+// ChartColumn 结果列说明
+type ChartColumn struct {
+	FieldID     string `json:"fieldId,omitempty"`
+	FieldName   string `json:"fieldName,omitempty"`
+	FieldType   string `json:"fieldType,omitempty"`
+	TimeBucket  string `json:"timeBucket,omitempty"`
+	Aggregation string `json:"aggregation,omitempty"`
+}
+
+// ChartRow 一个分组的维度值与指标值（与列说明顺序一致）
+type ChartRow struct {
+	Dimensions []interface{} `json:"dimensions"`
+	Values     []interface{} `json:"values"`
+}
+
+// ChartData 图表数据
+type ChartData struct {
+	TableID     string        `json:"tableId"`
+	Dimensions  []ChartColumn `json:"dimensions"`
+	Measures    []ChartColumn `json:"measures"`
+	Rows        []ChartRow    `json:"rows"`
+	Truncated   bool          `json:"truncated"` // 分组数超过 limit
+	GeneratedAt time.Time     `json:"generatedAt"`
+}
+
+// ChartService 图表数据服务：在动态表上按维度和指标做服务端聚合 ✨
+type ChartService struct {
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	cache             *cache.LRUCache
+}
+
+// NewChartService 创建图表数据服务
+func NewChartService(
+	fieldRepo repository.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+) *ChartService {
+	return &ChartService{
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		cache:             cache.NewLRUCache(chartCacheSize, nil),
+	}
+}
+
+// chartQuery 解析后的图表查询
+type chartQuery struct {
+	dimensions  []*entity.Field
+	measures    []*entity.Field // count 不指定字段时为 nil
+	condition   clause.Expression
+	viewVersion int
+}
+
+// QueryChartData 按维度、指标、筛选和时间粒度聚合表格数据
+func (s *ChartService) QueryChartData(ctx context.Context, userID, tableID string, req ChartDataRequest) (*ChartData, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	return s.queryChartData(ctx, tableID, req)
+}
+
+// queryChartData 执行图表查询（调用方负责权限校验，如仪表盘分享访问）
+func (s *ChartService) queryChartData(ctx context.Context, tableID string, req ChartDataRequest) (*ChartData, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	}
+	query, err := s.resolve(ctx, tableID, &req, byID, protected)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求经 normalizeChartRequest 规范化后作为缓存键，字段版本变化时失效
+	cacheKey := chartCacheKey(tableID, req, query.viewVersion, fields)
+	if cached, ok := s.cache.Get(cacheKey); ok {
+		if data, ok := cached.(*ChartData); ok {
+			return data, nil
+		}
+	}
+
+	data, err := s.aggregate(ctx, table.BaseID(), tableID, req, query)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(cacheKey, data, chartCacheTTL)
+	return data, nil
+}
+
+// resolve 校验请求并解析字段与筛选条件
+func (s *ChartService) resolve(ctx context.Context, tableID string, req *ChartDataRequest, fields map[string]*entity.Field, protected map[string]bool) (*chartQuery, error) {
+	if err := normalizeChartRequest(req); err != nil {
+		return nil, err
+	}
+
+	query := &chartQuery{}
+	for _, dim := range req.Dimensions {
+		field, ok := fields[dim.FieldID]
+		if !ok {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("维度字段不存在: %s", dim.FieldID))
+		}
+		if field.IsEncrypted() || protected[dim.FieldID] {
+			return nil, pkgerrors.ErrForbidden.WithDetails(fmt.Sprintf("字段 %s 受脱敏保护，不能作为维度", field.Name().String()))
+		}
+		if dim.TimeBucket != "" && columnKindOf(field) != columnKindDate {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是日期类型，不能按时间粒度分组", field.Name().String()))
+		}
+		query.dimensions = append(query.dimensions, field)
+	}
+
+	for _, measure := range req.Measures {
+		if measure.FieldID == "" {
+			query.measures = append(query.measures, nil)
+			continue
+		}
+		field, ok := fields[measure.FieldID]
+		if !ok {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("指标字段不存在: %s", measure.FieldID))
+		}
+		if err := checkChartMeasure(field, measure.Aggregation, protected[measure.FieldID]); err != nil {
+			return nil, err
+		}
+		query.measures = append(query.measures, field)
+	}
+
+	var conditions []clause.Expression
+	if req.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, req.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.TableID() != tableID {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+		query.viewVersion = view.Version()
+		condition, err := buildViewFilterCondition(ctx, view.Filter(), fields)
+		if err != nil {
+			return nil, err
+		}
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	if req.Filter != nil {
+		filter, err := viewVO.NewFilter(req.Filter)
+		if err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的筛选条件: %v", err))
+		}
+		condition, err := buildViewFilterCondition(ctx, filter, fields)
+		if err != nil {
+			return nil, err
+		}
+		if condition != nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	if len(conditions) > 0 {
+		query.condition = clause.And(conditions...)
+	}
+	return query, nil
+}
+
+// normalizeChartRequest 填充默认值并校验请求结构（不涉及字段）
+func normalizeChartRequest(req *ChartDataRequest) error {
+	if len(req.Dimensions) > chartMaxDimensions {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("最多支持 %d 个维度", chartMaxDimensions))
+	}
+	if len(req.Measures) == 0 {
+		req.Measures = []ChartMeasure{{Aggregation: ChartAggCount}}
+	}
+	if len(req.Measures) > chartMaxMeasures {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("最多支持 %d 个指标", chartMaxMeasures))
+	}
+
+	for i := range req.Dimensions {
+		req.Dimensions[i].TimeBucket = strings.ToLower(req.Dimensions[i].TimeBucket)
+		if bucket := req.Dimensions[i].TimeBucket; bucket != "" && !chartTimeBuckets[bucket] {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的时间粒度: %s", bucket))
+		}
+	}
+	for _, measure := range req.Measures {
+		switch measure.Aggregation {
+		case ChartAggCount:
+		case ChartAggFilled, ChartAggCountDistinct, ChartAggSum, ChartAggAvg, ChartAggMin, ChartAggMax:
+			if measure.FieldID == "" {
+				return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("聚合方式 %s 需要指定字段", measure.Aggregation))
+			}
+		default:
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的聚合方式: %s", measure.Aggregation))
+		}
+	}
+
+	switch req.SortBy {
+	case "":
+		req.SortBy = "dimension"
+	case "dimension", "measure":
+	default:
+		return pkgerrors.ErrValidationFailed.WithDetails("sortBy 仅支持 dimension / measure")
+	}
+	switch req.Order = strings.ToLower(req.Order); req.Order {
+	case "":
+		req.Order = "asc"
+	case "asc", "desc":
+	default:
+		return pkgerrors.ErrValidationFailed.WithDetails("order 仅支持 asc / desc")
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = chartDefaultLimit
+	}
+	if req.Limit > chartMaxLimit {
+		req.Limit = chartMaxLimit
+	}
+	return nil
+}
+
+// checkChartMeasure 校验字段能否使用该聚合方式
+func checkChartMeasure(field *entity.Field, aggregation string, protected bool) error {
+	name := field.Name().String()
+	// 加密或脱敏字段只允许计数，数值聚合和去重计数都会泄露明文信息
+	if (field.IsEncrypted() || protected) && aggregation != ChartAggCount && aggregation != ChartAggFilled {
+		return pkgerrors.ErrForbidden.WithDetails(fmt.Sprintf("字段 %s 受脱敏保护，仅支持计数", name))
+	}
+
+	kind := columnKindOf(field)
+	switch aggregation {
+	case ChartAggSum, ChartAggAvg:
+		if kind != columnKindNumber {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是数值类型，不能计算 %s", name, aggregation))
+		}
+	case ChartAggMin, ChartAggMax:
+		if kind != columnKindNumber && kind != columnKindDate {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是数值或日期类型，不能计算 %s", name, aggregation))
+		}
+	}
+	return nil
+}
+
+// aggregate 生成并执行 GROUP BY 查询
+func (s *ChartService) aggregate(ctx context.Context, baseID, tableID string, req ChartDataRequest, query *chartQuery) (*ChartData, error) {
+	db := s.dataDB(ctx, baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(baseID, tableID))
+
+	selects := make([]string, 0, len(query.dimensions)+len(query.measures))
+	var vars []interface{}
+	for i, field := range query.dimensions {
+		col := clause.Column{Name: field.DBFieldName().String()}
+		switch kind := columnKindOf(field); {
+		case req.Dimensions[i].TimeBucket != "":
+			selects = append(selects, fmt.Sprintf("date_trunc('%s', ?)", req.Dimensions[i].TimeBucket))
+			vars = append(vars, col)
+		case kind == columnKindJSON || kind == columnKindArray:
+			// 多值字段按元素展开分组，空值保留为 NULL 分组
+			alias := fmt.Sprintf("chart_d%d", i)
+			elements := "unnest(?)"
+			if kind == columnKindJSON {
+				elements = "jsonb_array_elements_text(CASE WHEN jsonb_typeof(?) = 'array' THEN ? ELSE '[]'::jsonb END)"
+			}
+			joinVars := []interface{}{col}
+			if kind == columnKindJSON {
+				joinVars = append(joinVars, col)
+			}
+			db = db.Joins(fmt.Sprintf("LEFT JOIN LATERAL %s AS %s(value) ON TRUE", elements, alias), joinVars...)
+			selects = append(selects, alias+".value")
+		case kind == columnKindBoolean:
+			selects = append(selects, "COALESCE(?, FALSE)")
+			vars = append(vars, col)
+		default:
+			selects = append(selects, "?")
+			vars = append(vars, col)
+		}
+	}
+	for i, field := range query.measures {
+		sql, measureVars := chartMeasureSQL(req.Measures[i].Aggregation, field)
+		selects = append(selects, sql)
+		vars = append(vars, measureVars...)
+	}
+
+	db = db.Select(strings.Join(selects, ", "), vars...)
+	if query.condition != nil {
+		db = db.Where(query.condition)
+	}
+	if n := len(query.dimensions); n > 0 {
+		positions := make([]string, n)
+		for i := range positions {
+			positions[i] = fmt.Sprint(i + 1)
+		}
+		db = db.Group(strings.Join(positions, ", "))
+
+		// 按序号排序：维度为第 1 列，指标为维度之后的第 1 列
+		orderBy := 1
+		if req.SortBy == "measure" {
+			orderBy = n + 1
+		}
+		db = db.Order(fmt.Sprintf("%d %s NULLS LAST", orderBy, strings.ToUpper(req.Order)))
+	}
+
+	rows, err := db.Limit(req.Limit + 1).Rows()
+	if err != nil {
+		return nil, pkgerrors.Database(err, "计算图表数据失败")
+	}
+	defer rows.Close()
+
+	data := &ChartData{
+		TableID:     tableID,
+		Dimensions:  make([]ChartColumn, len(query.dimensions)),
+		Measures:    make([]ChartColumn, len(query.measures)),
+		Rows:        []ChartRow{},
+		GeneratedAt: time.Now(),
+	}
+	for i, field := range query.dimensions {
+		data.Dimensions[i] = chartColumn(field)
+		data.Dimensions[i].TimeBucket = req.Dimensions[i].TimeBucket
+	}
+	for i, field := range query.measures {
+		data.Measures[i] = chartColumn(field)
+		data.Measures[i].Aggregation = req.Measures[i].Aggregation
+	}
+
+	width := len(query.dimensions) + len(query.measures)
+	for rows.Next() {
+		if len(data.Rows) == req.Limit {
+			data.Truncated = true
+			break
+		}
+		values := make([]interface{}, width)
+		dest := make([]interface{}, width)
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, pkgerrors.Database(err, "读取图表数据失败")
+		}
+		for i := range values {
+			values[i] = normalizeSummaryValue(values[i])
+		}
+		n := len(query.dimensions)
+		data.Rows = append(data.Rows, ChartRow{Dimensions: values[:n], Values: values[n:]})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Database(err, "读取图表数据失败")
+	}
+	return data, nil
+}
+
+// chartMeasureSQL 指标的聚合表达式
+func chartMeasureSQL(aggregation string, field *entity.Field) (string, []interface{}) {
+	if field == nil {
+		return "COUNT(*)", nil
+	}
+	col := clause.Column{Name: field.DBFieldName().String()}
+	switch aggregation {
+	case ChartAggCount:
+		return "COUNT(*)", nil
+	case ChartAggFilled, ChartAggCountDistinct:
+		if aggregation == ChartAggFilled {
+			return aggregateSQL("filled", col, columnKindOf(field))
+		}
+		return aggregateSQL("unique", col, columnKindOf(field))
+	case ChartAggSum:
+		return "COALESCE(SUM(?), 0)", []interface{}{col}
+	case ChartAggAvg:
+		return "AVG(?)", []interface{}{col}
+	case ChartAggMin:
+		return "MIN(?)", []interface{}{col}
+	default:
+		return "MAX(?)", []interface{}{col}
+	}
+}
+
+func chartColumn(field *entity.Field) ChartColumn {
+	if field == nil {
+		return ChartColumn{}
+	}
+	return ChartColumn{
+		FieldID:   field.ID().String(),
+		FieldName: field.Name().String(),
+		FieldType: field.Type().String(),
+	}
+}
+
+// chartCacheKey 图表缓存键：规范化后的请求 + 视图版本 + 表内字段版本
+func chartCacheKey(tableID string, req ChartDataRequest, viewVersion int, fields []*entity.Field) string {
+	h := sha256.New()
+	payload, _ := json.Marshal(req)
+	h.Write(payload)
+	fmt.Fprintf(h, "|view@%d", viewVersion)
+	for _, field := range fields {
+		fmt.Fprintf(h, "|%s@%d", field.ID().String(), field.Version())
+	}
+	return tableID + ":" + hex.EncodeToString(h.Sum(nil))
+}
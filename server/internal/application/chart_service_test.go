@@ -0,0 +1,60 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestNormalizeChartRequest(t *testing.T) {
+	req := ChartDataRequest{
+		Dimensions: []ChartDimension{{FieldID: "fld1", TimeBucket: "Month"}},
+		Limit:      5000,
+	}
+	if err := normalizeChartRequest(&req); err != nil {
+		t.Fatalf("合法请求不应报错: %v", err)
+	}
+	if req.Measures[0].Aggregation != ChartAggCount || req.SortBy != "dimension" || req.Order != "asc" ||
+		req.Limit != chartMaxLimit || req.Dimensions[0].TimeBucket != "month" {
+		t.Errorf("默认值填充不正确: %+v", req)
+	}
+
+	invalid := []ChartDataRequest{
+		{Dimensions: []ChartDimension{{FieldID: "a"}, {FieldID: "b"}, {FieldID: "c"}}},
+		{Measures: []ChartMeasure{{Aggregation: ChartAggSum}}},
+		{Measures: []ChartMeasure{{FieldID: "fld1", Aggregation: "median"}}},
+		{Dimensions: []ChartDimension{{FieldID: "fld1", TimeBucket: "decade"}}},
+	}
+	for i := range invalid {
+		if err := normalizeChartRequest(&invalid[i]); err == nil {
+			t.Errorf("第 %d 个请求应校验失败", i)
+		}
+	}
+}
+
+func TestCheckChartMeasure(t *testing.T) {
+	newField := func(fieldType string) *entity.Field {
+		name, _ := valueobject.NewFieldName("金额")
+		ft, _ := valueobject.NewFieldType(fieldType)
+		field, err := entity.NewField("tbl1", name, ft, "usr1")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		return field
+	}
+
+	number := newField(valueobject.TypeNumber)
+	if err := checkChartMeasure(number, ChartAggSum, false); err != nil {
+		t.Errorf("数值字段应支持求和: %v", err)
+	}
+	if err := checkChartMeasure(number, ChartAggSum, true); err == nil {
+		t.Errorf("脱敏字段不应支持求和")
+	}
+	if err := checkChartMeasure(number, ChartAggFilled, true); err != nil {
+		t.Errorf("脱敏字段应支持计数: %v", err)
+	}
+	if err := checkChartMeasure(newField(valueobject.TypeText), ChartAggAvg, false); err == nil {
+		t.Errorf("文本字段不应支持平均值")
+	}
+}
@@ -51,7 +51,7 @@ func (s *CollaboratorService) AddCollaborator(
 
 	// 保存
 	if err := s.repo.Create(ctx, collaborator); err != nil {
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	return s.toDTO(collaborator), nil
@@ -99,7 +99,7 @@ func (s *CollaboratorService) UpdateCollaborator(
 
 	// 保存
 	if err := s.repo.Update(ctx, collaborator); err != nil {
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	return s.toDTO(collaborator), nil
@@ -108,7 +108,7 @@ func (s *CollaboratorService) UpdateCollaborator(
 // RemoveCollaborator 移除协作者
 func (s *CollaboratorService) RemoveCollaborator(ctx context.Context, collaboratorID string) error {
 	if err := s.repo.Delete(ctx, collaboratorID); err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	return nil
 }
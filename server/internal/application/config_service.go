@@ -0,0 +1,342 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// 配置来源
+const (
+	ConfigSourceStartup = "startup"
+	ConfigSourceFile    = "file"
+	ConfigSourceRemote  = "remote"
+	ConfigSourceManual  = "manual"
+)
+
+var configLog = logger.Named("config")
+
+// ConfigChangeHandler 配置变更回调（old 为变更前配置，均为只读快照）
+type ConfigChangeHandler func(old, next *config.Config)
+
+// ConfigReloadStatus 配置热更新状态
+type ConfigReloadStatus struct {
+	Version         int64      `json:"version"` // 每次成功加载递增
+	Source          string     `json:"source"`  // 最近一次成功加载的来源
+	LoadedAt        time.Time  `json:"loaded_at"`
+	Watching        bool       `json:"watching"` // 是否在监听配置文件
+	RemoteURL       string     `json:"remote_url,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastErrorAt     *time.Time `json:"last_error_at,omitempty"`
+	RestartRequired []string   `json:"restart_required,omitempty"` // 已变更但需要重启才能生效的配置段
+}
+
+// ConfigService 配置热更新与功能开关服务 ✨
+// 监听配置文件或轮询远程配置，变更时原子替换配置快照并即时应用日志级别与功能开关
+type ConfigService struct {
+	mu       sync.Mutex // 串行化加载
+	current  atomic.Pointer[config.Config]
+	flags    *featureflag.Evaluator
+	handlers []ConfigChangeHandler
+	// permissionService 按空间评估开关前校验成员身份
+	permissionService *PermissionServiceV2
+	// overrides 管理控制台对功能开关的覆盖（key -> 是否开启），优先于配置文件
+	overrides map[string]bool
+
+	status     ConfigReloadStatus
+	remoteETag string
+}
+
+// NewConfigService 创建配置服务（启动配置中的功能开关立即生效）
+func NewConfigService(cfg *config.Config, flags *featureflag.Evaluator) *ConfigService {
+	s := &ConfigService{
+		flags: flags,
+		status: ConfigReloadStatus{
+			Version:   1,
+			Source:    ConfigSourceStartup,
+			LoadedAt:  time.Now(),
+			RemoteURL: cfg.Reload.RemoteURL,
+		},
+	}
+	s.current.Store(cfg)
+	s.flags.Set(featureFlags(cfg.Features))
+	return s
+}
+
+// Current 当前配置快照（只读）
+func (s *ConfigService) Current() *config.Config {
+	return s.current.Load()
+}
+
+// OnChange 注册配置变更回调（需在 Start 之前注册）
+func (s *ConfigService) OnChange(handler ConfigChangeHandler) {
+	s.mu.Lock()
+	s.handlers = append(s.handlers, handler)
+	s.mu.Unlock()
+}
+
+// Start 启动配置文件监听与远程配置轮询
+func (s *ConfigService) Start(ctx context.Context) {
+	reload := s.Current().Reload
+
+	if reload.Watch {
+		watching := config.WatchFile(func() {
+			if err := s.Reload(context.Background(), ConfigSourceFile); err != nil {
+				configLog.Warn(ctx, "配置文件变更后重新加载失败，继续使用旧配置", logger.ErrorField(err))
+			}
+		})
+		s.mu.Lock()
+		s.status.Watching = watching
+		s.mu.Unlock()
+	}
+
+	if reload.RemoteURL != "" {
+		interval := reload.PollInterval
+		if interval <= 0 {
+			interval = 30 * time.Second
+		}
+		go s.pollRemote(ctx, reload, interval)
+	}
+}
+
+// pollRemote 按间隔拉取远程配置，内容变化（ETag）时重新加载
+func (s *ConfigService) pollRemote(ctx context.Context, reload config.ReloadConfig, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.syncRemote(ctx, reload); err != nil {
+			s.recordError(err)
+			configLog.Warn(ctx, "同步远程配置失败", logger.String("url", reload.RemoteURL), logger.ErrorField(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ConfigService) syncRemote(ctx context.Context, reload config.ReloadConfig) error {
+	s.mu.Lock()
+	etag := s.remoteETag
+	s.mu.Unlock()
+
+	data, newETag, changed, err := config.FetchRemote(ctx, reload, etag)
+	if err != nil || !changed {
+		return err
+	}
+	if err := config.SetRemoteOverlay(data); err != nil {
+		return err
+	}
+	if err := s.Reload(ctx, ConfigSourceRemote); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.remoteETag = newETag
+	s.mu.Unlock()
+	return nil
+}
+
+// Reload 重新加载配置并应用可热更新的部分；失败时保留旧配置
+func (s *ConfigService) Reload(ctx context.Context, source string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := config.Reload()
+	if err != nil {
+		s.recordErrorLocked(err)
+		return errors.ErrInternalServer.WithDetails(err.Error())
+	}
+	if err := validateReloadable(next); err != nil {
+		s.recordErrorLocked(err)
+		return err
+	}
+
+	old := s.current.Load()
+	restart := config.RestartRequired(old, next)
+
+	s.applyLogger(ctx, old.Logger, next.Logger)
+	s.flags.Set(applyFeatureOverrides(featureFlags(next.Features), s.overrides))
+	s.current.Store(next)
+
+	s.status.Version++
+	s.status.Source = source
+	s.status.LoadedAt = time.Now()
+	s.status.RemoteURL = next.Reload.RemoteURL
+	s.status.LastError = ""
+	s.status.LastErrorAt = nil
+	s.status.RestartRequired = restart
+
+	for _, handler := range s.handlers {
+		handler(old, next)
+	}
+
+	configLog.Info(ctx, "配置已重新加载",
+		logger.String("source", source),
+		logger.Int64("version", s.status.Version),
+		logger.Int("features", len(next.Features)),
+	)
+	if len(restart) > 0 {
+		configLog.Warn(ctx, "部分配置变更需要重启才能生效", logger.Strings("sections", restart))
+	}
+	return nil
+}
+
+// Status 热更新状态
+func (s *ConfigService) Status() ConfigReloadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.RestartRequired = append([]string(nil), s.status.RestartRequired...)
+	return status
+}
+
+// Features 当前功能开关定义
+func (s *ConfigService) Features() []featureflag.Flag {
+	return s.flags.Flags()
+}
+
+// SetFeatureOverrides 整体替换功能开关覆盖并立即生效（配置热更新后仍然保留）
+func (s *ConfigService) SetFeatureOverrides(overrides map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides = overrides
+	s.flags.Set(applyFeatureOverrides(featureFlags(s.current.Load().Features), overrides))
+}
+
+// SetPermissionService 设置权限服务（权限服务在配置服务之后创建）
+func (s *ConfigService) SetPermissionService(permissionService *PermissionServiceV2) {
+	s.permissionService = permissionService
+}
+
+// EvaluateUserFeatures 按当前用户与工作空间评估全部功能开关
+// 指定工作空间时用户必须能访问该空间，否则可借助黑白名单探测其他空间的灰度设置
+func (s *ConfigService) EvaluateUserFeatures(ctx context.Context, userID, spaceID string) ([]featureflag.Decision, error) {
+	if spaceID != "" && (s.permissionService == nil || !s.permissionService.CanAccessSpace(ctx, userID, spaceID)) {
+		return nil, errors.ErrForbidden.WithDetails("没有权限访问该空间")
+	}
+	return s.flags.EvaluateAll(featureflag.Subject{SpaceID: spaceID, UserID: userID}), nil
+}
+
+// EvaluateFeatures 评估全部功能开关
+func (s *ConfigService) EvaluateFeatures(subject featureflag.Subject) []featureflag.Decision {
+	return s.flags.EvaluateAll(subject)
+}
+
+// EvaluateFeature 评估单个功能开关
+func (s *ConfigService) EvaluateFeature(key string, subject featureflag.Subject) featureflag.Decision {
+	return s.flags.Evaluate(key, subject)
+}
+
+// applyLogger 应用日志级别与模块级别（已移除的模块恢复跟随全局级别）
+func (s *ConfigService) applyLogger(ctx context.Context, old, next config.LoggerConfig) {
+	if next.Level != old.Level {
+		if err := logger.SetLevel(next.Level); err != nil {
+			configLog.Warn(ctx, "无效的日志级别，已忽略", logger.String("level", next.Level), logger.ErrorField(err))
+		}
+	}
+	for module := range old.Modules {
+		if _, ok := next.Modules[module]; !ok {
+			_ = logger.SetModuleLevel(module, "")
+		}
+	}
+	for module, level := range next.Modules {
+		if old.Modules[module] == level {
+			continue
+		}
+		if err := logger.SetModuleLevel(module, level); err != nil {
+			configLog.Warn(ctx, "无效的模块日志级别，已忽略",
+				logger.String("module", module), logger.String("level", level), logger.ErrorField(err))
+		}
+	}
+}
+
+func (s *ConfigService) recordError(err error) {
+	s.mu.Lock()
+	s.recordErrorLocked(err)
+	s.mu.Unlock()
+}
+
+func (s *ConfigService) recordErrorLocked(err error) {
+	now := time.Now()
+	s.status.LastError = err.Error()
+	s.status.LastErrorAt = &now
+}
+
+// validateReloadable 校验可热更新的配置段
+func validateReloadable(cfg *config.Config) error {
+	for key, f := range cfg.Features {
+		if f.Rollout < 0 || f.Rollout > 100 {
+			return errors.ErrValidationFailed.WithDetails("功能开关 " + key + " 的 rollout 必须在 0-100 之间")
+		}
+		if f.RolloutBy != "" && f.RolloutBy != featureflag.RolloutBySpace && f.RolloutBy != featureflag.RolloutByUser {
+			return errors.ErrValidationFailed.WithDetails("功能开关 " + key + " 的 rollout_by 只能是 space 或 user")
+		}
+	}
+	return nil
+}
+
+// featureFlags 配置转换为开关定义
+func featureFlags(features map[string]config.FeatureFlagConfig) []featureflag.Flag {
+	keys := make([]string, 0, len(features))
+	for key := range features {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]featureflag.Flag, 0, len(keys))
+	for _, key := range keys {
+		f := features[key]
+		flags = append(flags, featureflag.Flag{
+			Key:         key,
+			Description: f.Description,
+			Enabled:     f.Enabled,
+			Rollout:     f.Rollout,
+			RolloutBy:   f.RolloutBy,
+			Spaces:      f.Spaces,
+			Users:       f.Users,
+			DenySpaces:  f.DenySpaces,
+			DenyUsers:   f.DenyUsers,
+		})
+	}
+	return flags
+}
+
+// applyFeatureOverrides 应用功能开关覆盖：关闭时总开关关闭；开启时总开关开启且灰度为 100%
+// （黑名单仍然生效），配置文件中没有定义的开关按覆盖新增
+func applyFeatureOverrides(flags []featureflag.Flag, overrides map[string]bool) []featureflag.Flag {
+	if len(overrides) == 0 {
+		return flags
+	}
+	seen := make(map[string]bool, len(flags))
+	for i := range flags {
+		seen[flags[i].Key] = true
+		if enabled, ok := overrides[flags[i].Key]; ok {
+			flags[i].Enabled = enabled
+			if enabled {
+				flags[i].Rollout = 100
+			}
+		}
+	}
+	for key, enabled := range overrides {
+		if seen[key] {
+			continue
+		}
+		f := featureflag.Flag{Key: key, Enabled: enabled}
+		if enabled {
+			f.Rollout = 100
+		}
+		flags = append(flags, f)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags
+}
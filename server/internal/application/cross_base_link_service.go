@@ -0,0 +1,242 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// linkHostsTTL 指向某个表的跨 Base 关联字段的缓存时间（关联目标只在创建/修改字段时变化）
+const linkHostsTTL = time.Minute
+
+// LinkedRecordChange 被关联记录的变更（推送给其他 Base 中指向该表的关联字段）
+type LinkedRecordChange struct {
+	SourceBaseID  string   `json:"sourceBaseId"`
+	SourceTableID string   `json:"sourceTableId"`
+	Action        string   `json:"action"` // create, update, delete, batch
+	RecordIDs     []string `json:"recordIds"`
+}
+
+// linkHost 指向目标表的跨 Base 关联字段
+type linkHost struct {
+	baseID  string
+	tableID string
+	fieldID string
+}
+
+type linkHostsEntry struct {
+	hosts   []linkHost
+	expires time.Time
+}
+
+// CrossBaseLinkService 跨 Base 关联服务 ✨
+//
+// 关联字段可以指向同一空间内其他 Base 的表：创建时按目标 Base 校验读取权限，
+// 写入关联值时按目标 Base 再次校验；目标表的记录变化后，向其他 Base 中的关联字段所在表推送事件。
+// 读取时的关联解析由 RecordExpandService 按表合并批量读取，并逐表校验访问权限。
+type CrossBaseLinkService struct {
+	tableRepo         tableRepo.TableRepository
+	baseRepo          baseRepo.BaseRepository
+	fieldRepo         fieldRepo.FieldRepository
+	linkFinder        fieldRepo.LinkFieldFinder
+	permissionService *PermissionServiceV2
+	businessEvents    events.BusinessEventPublisher
+
+	mu    sync.Mutex
+	hosts map[string]linkHostsEntry // 目标表ID -> 其他 Base 中指向它的关联字段
+}
+
+// NewCrossBaseLinkService 创建跨 Base 关联服务
+func NewCrossBaseLinkService(
+	tableRepo tableRepo.TableRepository,
+	baseRepo baseRepo.BaseRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	linkFinder fieldRepo.LinkFieldFinder,
+	permissionService *PermissionServiceV2,
+	businessEvents events.BusinessEventPublisher,
+) *CrossBaseLinkService {
+	return &CrossBaseLinkService{
+		tableRepo:         tableRepo,
+		baseRepo:          baseRepo,
+		fieldRepo:         fieldRepo,
+		linkFinder:        linkFinder,
+		permissionService: permissionService,
+		businessEvents:    businessEvents,
+		hosts:             make(map[string]linkHostsEntry),
+	}
+}
+
+// ResolveTarget 校验关联字段的目标表并补全 BaseID（同 Base 时清空）
+// 跨 Base 时要求两个 Base 属于同一空间，且用户在目标 Base 中可以读取该表
+func (s *CrossBaseLinkService) ResolveTarget(ctx context.Context, userID, hostTableID string, link *fieldVO.LinkOptions) error {
+	if link == nil || link.LinkedTableID == "" {
+		return pkgerrors.ErrValidationFailed.WithDetails("关联字段必须指定 linkedTableId")
+	}
+	host, err := s.tableRepo.GetByID(ctx, hostTableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找表格失败")
+	}
+	if host == nil {
+		return pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	target, err := s.tableRepo.GetByID(ctx, link.LinkedTableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找关联表格失败")
+	}
+	if target == nil {
+		return pkgerrors.ErrNotFound.WithDetails("关联的表格不存在")
+	}
+	if link.BaseID != "" && link.BaseID != target.BaseID() {
+		return pkgerrors.ErrValidationFailed.WithDetails("关联的表格不属于指定的 Base")
+	}
+	if target.BaseID() == host.BaseID() {
+		link.BaseID = ""
+		return nil
+	}
+
+	hostBase, err := s.baseRepo.FindByID(ctx, host.BaseID())
+	if err != nil {
+		return pkgerrors.Database(err, "查找 Base 失败")
+	}
+	targetBase, err := s.baseRepo.FindByID(ctx, target.BaseID())
+	if err != nil {
+		return pkgerrors.Database(err, "查找关联 Base 失败")
+	}
+	if hostBase == nil || targetBase == nil || !targetBase.BelongsToSpace(hostBase.SpaceID) {
+		return pkgerrors.ErrValidationFailed.WithDetails("只能关联同一空间内其他 Base 的表格")
+	}
+	if s.permissionService != nil && !s.permissionService.CanAccessTable(ctx, userID, target.ID().String()) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限访问关联表格所在的 Base")
+	}
+	link.BaseID = target.BaseID()
+	s.invalidate(target.ID().String())
+	return nil
+}
+
+// CheckWrite 写入跨 Base 关联值前按目标 Base 校验读取权限
+func (s *CrossBaseLinkService) CheckWrite(ctx context.Context, userID, tableID string, data map[string]interface{}) error {
+	if s.permissionService == nil || userID == "" || len(data) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段失败")
+	}
+	checked := make(map[string]bool)
+	for _, field := range fields {
+		link := crossBaseLink(field)
+		if link == nil || checked[link.LinkedTableID] {
+			continue
+		}
+		if _, ok := data[field.ID().String()]; !ok {
+			if _, ok := data[field.Name().String()]; !ok {
+				continue
+			}
+		}
+		checked[link.LinkedTableID] = true
+		if !s.permissionService.CanAccessTable(ctx, userID, link.LinkedTableID) {
+			return pkgerrors.ErrForbidden.WithDetails("没有权限关联字段 " + field.Name().String() + " 所在 Base 的记录")
+		}
+	}
+	return nil
+}
+
+// RecordsChanged 目标表记录变更后，向其他 Base 中指向它的关联字段所在表推送事件（失败只记录日志）
+func (s *CrossBaseLinkService) RecordsChanged(ctx context.Context, tableID, action string, recordIDs []string, userID string) {
+	if s.businessEvents == nil || len(recordIDs) == 0 {
+		return
+	}
+	hosts, err := s.linkHosts(ctx, tableID)
+	if err != nil {
+		logger.Warn("查询跨 Base 关联字段失败",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+		return
+	}
+	if len(hosts) == 0 {
+		return
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil || table == nil {
+		return
+	}
+
+	change := &LinkedRecordChange{
+		SourceBaseID:  table.BaseID(),
+		SourceTableID: tableID,
+		Action:        action,
+		RecordIDs:     recordIDs,
+	}
+	for _, host := range hosts {
+		if err := s.businessEvents.PublishLinkedRecordEvent(ctx, host.baseID, host.tableID, host.fieldID, change, userID); err != nil {
+			logger.Warn("发布跨 Base 关联记录事件失败",
+				logger.String("table_id", host.tableID),
+				logger.String("field_id", host.fieldID),
+				logger.ErrorField(err))
+		}
+	}
+}
+
+// linkHosts 其他 Base 中指向 tableID 的关联字段（带缓存）
+func (s *CrossBaseLinkService) linkHosts(ctx context.Context, tableID string) ([]linkHost, error) {
+	s.mu.Lock()
+	entry, ok := s.hosts[tableID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.hosts, nil
+	}
+
+	var hosts []linkHost
+	if s.linkFinder != nil {
+		fields, err := s.linkFinder.FindLinkFieldsTo(ctx, tableID)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			link := crossBaseLink(field)
+			if link == nil {
+				continue
+			}
+			host, err := s.tableRepo.GetByID(ctx, field.TableID())
+			if err != nil {
+				return nil, err
+			}
+			if host == nil || host.BaseID() == link.BaseID {
+				continue
+			}
+			hosts = append(hosts, linkHost{baseID: host.BaseID(), tableID: field.TableID(), fieldID: field.ID().String()})
+		}
+	}
+
+	s.mu.Lock()
+	s.hosts[tableID] = linkHostsEntry{hosts: hosts, expires: time.Now().Add(linkHostsTTL)}
+	s.mu.Unlock()
+	return hosts, nil
+}
+
+func (s *CrossBaseLinkService) invalidate(tableID string) {
+	s.mu.Lock()
+	delete(s.hosts, tableID)
+	s.mu.Unlock()
+}
+
+// crossBaseLink 跨 Base 关联字段的关联配置，其他字段返回 nil
+func crossBaseLink(field *fieldEntity.Field) *fieldVO.LinkOptions {
+	if field.Type().String() != fieldVO.TypeLink {
+		return nil
+	}
+	options := field.Options()
+	if options == nil || options.Link == nil || options.Link.LinkedTableID == "" || options.Link.BaseID == "" {
+		return nil
+	}
+	return options.Link
+}
@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	baseEntity "github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+)
+
+// stubLinkBaseRepo 按ID返回 Base
+type stubLinkBaseRepo struct {
+	baseRepo.BaseRepository
+	bases map[string]*baseEntity.Base
+}
+
+func (r *stubLinkBaseRepo) FindByID(_ context.Context, id string) (*baseEntity.Base, error) {
+	return r.bases[id], nil
+}
+
+// stubLinkFieldFinder 返回预置的关联字段
+type stubLinkFieldFinder struct {
+	fields []*fieldEntity.Field
+	calls  int
+}
+
+func (f *stubLinkFieldFinder) FindLinkFieldsTo(context.Context, string) ([]*fieldEntity.Field, error) {
+	f.calls++
+	return f.fields, nil
+}
+
+// recordingLinkedEvents 记录跨 Base 关联事件
+type recordingLinkedEvents struct {
+	events.BusinessEventPublisher
+	tables []string
+}
+
+func (p *recordingLinkedEvents) PublishLinkedRecordEvent(_ context.Context, _, tableID, _ string, _ interface{}, _ string) error {
+	p.tables = append(p.tables, tableID)
+	return nil
+}
+
+type crossBaseLinkFixture struct {
+	service *CrossBaseLinkService
+	tables  map[string]*tableEntity.Table
+	finder  *stubLinkFieldFinder
+	events  *recordingLinkedEvents
+}
+
+// newCrossBaseLinkFixture bse1、bse2 属于同一空间，bse3 属于其他空间，每个 Base 一张表
+func newCrossBaseLinkFixture(t *testing.T) *crossBaseLinkFixture {
+	t.Helper()
+	fx := &crossBaseLinkFixture{
+		tables: map[string]*tableEntity.Table{},
+		finder: &stubLinkFieldFinder{},
+		events: &recordingLinkedEvents{},
+	}
+	tables := &stubTableRepo{tables: map[string]*tableEntity.Table{}}
+	for _, baseID := range []string{"bse1", "bse2", "bse3"} {
+		name, _ := tableVO.NewTableName("表 " + baseID)
+		table, err := tableEntity.NewTable(baseID, name, "usr1")
+		if err != nil {
+			t.Fatalf("创建表失败: %v", err)
+		}
+		fx.tables[baseID] = table
+		tables.tables[table.ID().String()] = table
+	}
+	bases := &stubLinkBaseRepo{bases: map[string]*baseEntity.Base{
+		"bse1": {ID: "bse1", SpaceID: "spc1"},
+		"bse2": {ID: "bse2", SpaceID: "spc1"},
+		"bse3": {ID: "bse3", SpaceID: "spc2"},
+	}}
+	fx.service = NewCrossBaseLinkService(tables, bases, nil, fx.finder, nil, fx.events)
+	return fx
+}
+
+func (fx *crossBaseLinkFixture) tableID(baseID string) string {
+	return fx.tables[baseID].ID().String()
+}
+
+func TestCrossBaseLinkResolveTarget(t *testing.T) {
+	fx := newCrossBaseLinkFixture(t)
+	ctx := context.Background()
+
+	same := &fieldVO.LinkOptions{LinkedTableID: fx.tableID("bse1"), BaseID: "bse1"}
+	if err := fx.service.ResolveTarget(ctx, "usr1", fx.tableID("bse1"), same); err != nil || same.BaseID != "" {
+		t.Fatalf("同 Base 关联应清空 BaseID: %v, %q", err, same.BaseID)
+	}
+
+	cross := &fieldVO.LinkOptions{LinkedTableID: fx.tableID("bse2")}
+	if err := fx.service.ResolveTarget(ctx, "usr1", fx.tableID("bse1"), cross); err != nil || cross.BaseID != "bse2" {
+		t.Fatalf("同一空间的跨 Base 关联应记录目标 Base: %v, %q", err, cross.BaseID)
+	}
+
+	for name, link := range map[string]*fieldVO.LinkOptions{
+		"缺少目标表":     {},
+		"目标表不存在":    {LinkedTableID: "tblMissing"},
+		"BaseID 不符": {LinkedTableID: fx.tableID("bse2"), BaseID: "bse3"},
+		"跨空间":       {LinkedTableID: fx.tableID("bse3")},
+	} {
+		if err := fx.service.ResolveTarget(ctx, "usr1", fx.tableID("bse1"), link); err == nil {
+			t.Errorf("%s: 应校验失败", name)
+		}
+	}
+}
+
+func TestCrossBaseLinkRecordsChanged(t *testing.T) {
+	fx := newCrossBaseLinkFixture(t)
+	target := fx.tableID("bse2")
+	newLink := func(hostBaseID, linkBaseID string) *fieldEntity.Field {
+		name, _ := fieldVO.NewFieldName("关联")
+		fieldType, _ := fieldVO.NewFieldType(fieldVO.TypeLink)
+		field, err := fieldEntity.NewField(fx.tableID(hostBaseID), name, fieldType, "usr1")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		options := fieldVO.NewFieldOptions().WithLink(target, "many_to_many", false)
+		options.Link.BaseID = linkBaseID
+		field.UpdateOptions(options)
+		return field
+	}
+	// 只有 bse1 中的跨 Base 关联字段收到事件；bse2 内部的关联由表内事件覆盖
+	fx.finder.fields = []*fieldEntity.Field{newLink("bse1", "bse2"), newLink("bse2", "")}
+
+	ctx := context.Background()
+	fx.service.RecordsChanged(ctx, target, "update", []string{"rec1"}, "usr1")
+	fx.service.RecordsChanged(ctx, target, "delete", []string{"rec2"}, "usr1")
+	if len(fx.events.tables) != 2 || fx.events.tables[0] != fx.tableID("bse1") {
+		t.Fatalf("事件 = %v", fx.events.tables)
+	}
+	if fx.finder.calls != 1 {
+		t.Errorf("关联字段应被缓存，查询了 %d 次", fx.finder.calls)
+	}
+}
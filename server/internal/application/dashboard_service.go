@@ -0,0 +1,542 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/dashboard"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// CreateDashboardRequest 创建仪表盘请求
+type CreateDashboardRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RenameDashboardRequest 重命名仪表盘请求
+type RenameDashboardRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// UpdateDashboardLayoutRequest 更新布局请求
+type UpdateDashboardLayoutRequest struct {
+	Layout []dashboard.LayoutItem `json:"layout"`
+}
+
+// DuplicateDashboardRequest 复制仪表盘请求（名称为空时使用 "原名称 副本"）
+type DuplicateDashboardRequest struct {
+	Name string `json:"name"`
+}
+
+// WidgetRequest 新增/更新组件请求
+// chart / number：config 为图表查询请求（number 不允许维度）；view：config 为 {"fieldIds": [...]}
+type WidgetRequest struct {
+	Type    string          `json:"type"`
+	Name    string          `json:"name" binding:"required"`
+	TableID string          `json:"tableId,omitempty"`
+	ViewID  string          `json:"viewId,omitempty"`
+	Config  json.RawMessage `json:"config,omitempty"`
+}
+
+// ViewWidgetConfig 视图片段组件配置
+type ViewWidgetConfig struct {
+	FieldIDs []string `json:"fieldIds,omitempty"`
+}
+
+// DashboardDetail 仪表盘及其组件
+type DashboardDetail struct {
+	*dashboard.Dashboard
+	Widgets []*dashboard.Widget `json:"widgets"`
+}
+
+// WidgetData 组件数据：chart / number 返回图表数据，view 返回视图汇总
+type WidgetData struct {
+	WidgetID string       `json:"widgetId"`
+	Type     string       `json:"type"`
+	Chart    *ChartData   `json:"chart,omitempty"`
+	Summary  *ViewSummary `json:"summary,omitempty"`
+}
+
+// DashboardService 仪表盘服务：Base 级别的组件集合，数据来自图表接口和视图汇总接口 ✨
+// 查看需要 Base 访问权限，编辑需要 Base 更新权限；组件数据另按表格权限校验
+type DashboardService struct {
+	repo               dashboard.Repository
+	baseRepo           baseRepo.BaseRepository
+	tableRepo          tableRepo.TableRepository
+	viewRepo           viewRepo.ViewRepository
+	chartService       *ChartService
+	viewSummaryService *ViewSummaryService
+	permissionService  *PermissionServiceV2
+}
+
+// NewDashboardService 创建仪表盘服务
+func NewDashboardService(
+	repo dashboard.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	chartService *ChartService,
+	viewSummaryService *ViewSummaryService,
+	permissionService *PermissionServiceV2,
+) *DashboardService {
+	return &DashboardService{
+		repo:               repo,
+		baseRepo:           baseRepo,
+		tableRepo:          tableRepo,
+		viewRepo:           viewRepo,
+		chartService:       chartService,
+		viewSummaryService: viewSummaryService,
+		permissionService:  permissionService,
+	}
+}
+
+// CreateDashboard 在 Base 下创建仪表盘
+func (s *DashboardService) CreateDashboard(ctx context.Context, userID, baseID string, req CreateDashboardRequest) (*dashboard.Dashboard, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该Base中创建仪表盘")
+	}
+	exists, err := s.baseRepo.Exists(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "检查Base失败")
+	}
+	if !exists {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Base不存在")
+	}
+
+	d, err := dashboard.NewDashboard(baseID, req.Name, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, d); err != nil {
+		return nil, pkgerrors.Database(err, "创建仪表盘失败")
+	}
+	return d, nil
+}
+
+// ListDashboards 列出 Base 下的仪表盘
+func (s *DashboardService) ListDashboards(ctx context.Context, userID, baseID string) ([]*dashboard.Dashboard, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	list, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询仪表盘失败")
+	}
+	return list, nil
+}
+
+// GetDashboard 获取仪表盘及其组件
+func (s *DashboardService) GetDashboard(ctx context.Context, userID, dashboardID string) (*DashboardDetail, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, d)
+}
+
+// RenameDashboard 重命名仪表盘
+func (s *DashboardService) RenameDashboard(ctx context.Context, userID, dashboardID string, req RenameDashboardRequest) (*dashboard.Dashboard, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Rename(req.Name, userID); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, d); err != nil {
+		return nil, pkgerrors.Database(err, "更新仪表盘失败")
+	}
+	return d, nil
+}
+
+// UpdateLayout 保存仪表盘布局
+func (s *DashboardService) UpdateLayout(ctx context.Context, userID, dashboardID string, req UpdateDashboardLayoutRequest) (*dashboard.Dashboard, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	widgets, err := s.repo.ListWidgets(ctx, dashboardID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询仪表盘组件失败")
+	}
+	widgetIDs := make(map[string]bool, len(widgets))
+	for _, w := range widgets {
+		widgetIDs[w.ID] = true
+	}
+	if err := d.UpdateLayout(req.Layout, widgetIDs, userID); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, d); err != nil {
+		return nil, pkgerrors.Database(err, "更新仪表盘布局失败")
+	}
+	return d, nil
+}
+
+// DeleteDashboard 删除仪表盘及其组件
+func (s *DashboardService) DeleteDashboard(ctx context.Context, userID, dashboardID string) error {
+	if _, err := s.loadDashboard(ctx, userID, dashboardID, true); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, dashboardID); err != nil {
+		return pkgerrors.Database(err, "删除仪表盘失败")
+	}
+	return nil
+}
+
+// DuplicateDashboard 复制仪表盘（同一 Base 内，组件与布局一并复制，不继承分享状态）
+func (s *DashboardService) DuplicateDashboard(ctx context.Context, userID, dashboardID string, req DuplicateDashboardRequest) (*DashboardDetail, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	widgets, err := s.repo.ListWidgets(ctx, dashboardID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询仪表盘组件失败")
+	}
+
+	name := req.Name
+	if name == "" {
+		name = d.Name + " 副本"
+	}
+	copyDashboard, copies, err := d.Duplicate(name, widgets, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveWithWidgets(ctx, copyDashboard, copies); err != nil {
+		return nil, pkgerrors.Database(err, "复制仪表盘失败")
+	}
+	return &DashboardDetail{Dashboard: copyDashboard, Widgets: copies}, nil
+}
+
+// EnableShare 开启仪表盘分享，返回分享ID
+func (s *DashboardService) EnableShare(ctx context.Context, userID, dashboardID string) (string, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return "", err
+	}
+	shareID := d.EnableSharing(userID)
+	if err := s.repo.Save(ctx, d); err != nil {
+		return "", pkgerrors.Database(err, "开启仪表盘分享失败")
+	}
+	return shareID, nil
+}
+
+// DisableShare 关闭仪表盘分享
+func (s *DashboardService) DisableShare(ctx context.Context, userID, dashboardID string) error {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return err
+	}
+	d.DisableSharing(userID)
+	if err := s.repo.Save(ctx, d); err != nil {
+		return pkgerrors.Database(err, "关闭仪表盘分享失败")
+	}
+	return nil
+}
+
+// AddWidget 添加组件并放到布局最下方
+func (s *DashboardService) AddWidget(ctx context.Context, userID, dashboardID string, req WidgetRequest) (*dashboard.Widget, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	widgetType, err := dashboard.ParseWidgetType(req.Type)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	config, err := s.validateWidget(ctx, d.BaseID, widgetType, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := dashboard.NewWidget(d.ID, widgetType, req.Name, req.TableID, req.ViewID, config, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	width, height := w.DefaultSize()
+	d.PlaceWidget(w.ID, width, height)
+	if err := s.repo.SaveWithWidgets(ctx, d, []*dashboard.Widget{w}); err != nil {
+		return nil, pkgerrors.Database(err, "添加仪表盘组件失败")
+	}
+	return w, nil
+}
+
+// UpdateWidget 更新组件名称与数据绑定（类型不可修改）
+func (s *DashboardService) UpdateWidget(ctx context.Context, userID, dashboardID, widgetID string, req WidgetRequest) (*dashboard.Widget, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.loadWidget(ctx, d, widgetID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Type != "" && dashboard.WidgetType(req.Type) != w.Type {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("组件类型不可修改")
+	}
+	config, err := s.validateWidget(ctx, d.BaseID, w.Type, &req)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Update(req.Name, req.TableID, req.ViewID, config); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveWidget(ctx, w); err != nil {
+		return nil, pkgerrors.Database(err, "更新仪表盘组件失败")
+	}
+	return w, nil
+}
+
+// DeleteWidget 删除组件并从布局中移除
+func (s *DashboardService) DeleteWidget(ctx context.Context, userID, dashboardID, widgetID string) error {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, true)
+	if err != nil {
+		return err
+	}
+	if _, err := s.loadWidget(ctx, d, widgetID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteWidget(ctx, widgetID); err != nil {
+		return pkgerrors.Database(err, "删除仪表盘组件失败")
+	}
+	d.RemoveWidget(widgetID)
+	if err := s.repo.Save(ctx, d); err != nil {
+		return pkgerrors.Database(err, "更新仪表盘布局失败")
+	}
+	return nil
+}
+
+// GetWidgetData 查询组件数据（按当前用户的表格权限）
+func (s *DashboardService) GetWidgetData(ctx context.Context, userID, dashboardID, widgetID string) (*WidgetData, error) {
+	d, err := s.loadDashboard(ctx, userID, dashboardID, false)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.loadWidget(ctx, d, widgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &WidgetData{WidgetID: w.ID, Type: string(w.Type)}
+	switch w.Type {
+	case dashboard.WidgetTypeView:
+		config, err := decodeViewWidgetConfig(w.Config)
+		if err != nil {
+			return nil, err
+		}
+		data.Summary, err = s.viewSummaryService.GetViewSummary(ctx, userID, w.ViewID, config.FieldIDs)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		req, err := decodeChartWidgetConfig(w.Type, w.Config)
+		if err != nil {
+			return nil, err
+		}
+		data.Chart, err = s.chartService.QueryChartData(ctx, userID, w.TableID, *req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// GetSharedDashboard 通过分享ID获取仪表盘（无需登录）
+func (s *DashboardService) GetSharedDashboard(ctx context.Context, shareID string) (*DashboardDetail, error) {
+	d, err := s.loadSharedDashboard(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	return s.detail(ctx, d)
+}
+
+// GetSharedWidgetData 通过分享ID查询组件数据
+// 分享链接代表仪表盘所有者的授权，只能访问仪表盘中已配置的表格和视图
+func (s *DashboardService) GetSharedWidgetData(ctx context.Context, shareID, widgetID string) (*WidgetData, error) {
+	d, err := s.loadSharedDashboard(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	w, err := s.loadWidget(ctx, d, widgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &WidgetData{WidgetID: w.ID, Type: string(w.Type)}
+	switch w.Type {
+	case dashboard.WidgetTypeView:
+		config, err := decodeViewWidgetConfig(w.Config)
+		if err != nil {
+			return nil, err
+		}
+		view, err := s.viewRepo.FindByID(ctx, w.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+		if err := s.checkTableInBase(ctx, d.BaseID, view.TableID()); err != nil {
+			return nil, err
+		}
+		data.Summary, err = s.viewSummaryService.summarize(ctx, view, config.FieldIDs)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		req, err := decodeChartWidgetConfig(w.Type, w.Config)
+		if err != nil {
+			return nil, err
+		}
+		// 表格可能在组件创建后被移动或删除，查询前重新确认归属
+		if err := s.checkTableInBase(ctx, d.BaseID, w.TableID); err != nil {
+			return nil, err
+		}
+		data.Chart, err = s.chartService.queryChartData(ctx, w.TableID, *req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// loadDashboard 获取仪表盘并校验 Base 权限（manage 为 true 时需要编辑权限）
+func (s *DashboardService) loadDashboard(ctx context.Context, userID, dashboardID string, manage bool) (*dashboard.Dashboard, error) {
+	d, err := s.repo.FindByID(ctx, dashboardID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找仪表盘失败")
+	}
+	if d == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("仪表盘不存在")
+	}
+	if manage {
+		if !s.permissionService.CanUpdateBase(ctx, userID, d.BaseID) {
+			return nil, pkgerrors.ErrForbidden.WithDetails("没有权限修改该仪表盘")
+		}
+	} else if !s.permissionService.CanAccessBase(ctx, userID, d.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该仪表盘")
+	}
+	return d, nil
+}
+
+func (s *DashboardService) loadSharedDashboard(ctx context.Context, shareID string) (*dashboard.Dashboard, error) {
+	d, err := s.repo.FindByShareID(ctx, shareID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找仪表盘失败")
+	}
+	if d == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("分享的仪表盘不存在或已关闭分享")
+	}
+	return d, nil
+}
+
+// loadWidget 获取组件并确认属于该仪表盘
+func (s *DashboardService) loadWidget(ctx context.Context, d *dashboard.Dashboard, widgetID string) (*dashboard.Widget, error) {
+	w, err := s.repo.FindWidget(ctx, widgetID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找仪表盘组件失败")
+	}
+	if w == nil || w.DashboardID != d.ID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("组件不存在")
+	}
+	return w, nil
+}
+
+func (s *DashboardService) detail(ctx context.Context, d *dashboard.Dashboard) (*DashboardDetail, error) {
+	widgets, err := s.repo.ListWidgets(ctx, d.ID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询仪表盘组件失败")
+	}
+	return &DashboardDetail{Dashboard: d, Widgets: widgets}, nil
+}
+
+// validateWidget 校验组件绑定的表格/视图属于仪表盘所在 Base，并规范化配置
+func (s *DashboardService) validateWidget(ctx context.Context, baseID string, widgetType dashboard.WidgetType, req *WidgetRequest) (json.RawMessage, error) {
+	switch widgetType {
+	case dashboard.WidgetTypeView:
+		if req.ViewID == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("视图组件需要指定 viewId")
+		}
+		view, err := s.viewRepo.FindByID(ctx, req.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+		if err := s.checkTableInBase(ctx, baseID, view.TableID()); err != nil {
+			return nil, err
+		}
+		req.TableID = view.TableID()
+		if _, err := decodeViewWidgetConfig(req.Config); err != nil {
+			return nil, err
+		}
+		return req.Config, nil
+
+	default:
+		if req.TableID == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("图表组件需要指定 tableId")
+		}
+		if err := s.checkTableInBase(ctx, baseID, req.TableID); err != nil {
+			return nil, err
+		}
+		chartReq, err := decodeChartWidgetConfig(widgetType, req.Config)
+		if err != nil {
+			return nil, err
+		}
+		// 组件级视图与查询中的视图保持一致，便于按视图查找引用
+		if chartReq.ViewID == "" {
+			chartReq.ViewID = req.ViewID
+		}
+		req.ViewID = chartReq.ViewID
+		config, err := json.Marshal(chartReq)
+		if err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的组件配置: %v", err))
+		}
+		return config, nil
+	}
+}
+
+func (s *DashboardService) checkTableInBase(ctx context.Context, baseID, tableID string) error {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil || table.BaseID() != baseID {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在或不属于该仪表盘所在的Base")
+	}
+	return nil
+}
+
+// decodeChartWidgetConfig 解析并规范化图表/数字组件的查询配置
+func decodeChartWidgetConfig(widgetType dashboard.WidgetType, raw json.RawMessage) (*ChartDataRequest, error) {
+	req := &ChartDataRequest{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, req); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的组件配置: %v", err))
+		}
+	}
+	if widgetType == dashboard.WidgetTypeNumber && len(req.Dimensions) > 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("数字组件不能设置分组维度")
+	}
+	if err := normalizeChartRequest(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// decodeViewWidgetConfig 解析视图片段组件配置
+func decodeViewWidgetConfig(raw json.RawMessage) (*ViewWidgetConfig, error) {
+	config := &ViewWidgetConfig{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Unmarshal(raw, config); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的组件配置: %v", err))
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,508 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/dataexport"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/parquet"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var dataExportLog = logger.Named("data_export")
+
+// CreateDataExportRequest 创建数据导出请求
+type CreateDataExportRequest struct {
+	Format   string   `json:"format"`   // 目前仅支持 parquet（默认）
+	TableIDs []string `json:"tableIds"` // 为空时导出 Base 的全部表
+}
+
+// DataExportDetail 导出任务及下载链接（完成后提供）
+type DataExportDetail struct {
+	*dataexport.Export
+	ManifestURL string            `json:"manifest_url,omitempty"`
+	FileURLs    map[string]string `json:"file_urls,omitempty"` // 表ID → 数据文件下载链接
+}
+
+// exportSystemColumns 物理表系统列与导出列的对应关系（record_id 单独处理，不允许为空）
+var exportSystemColumns = []struct {
+	physical string
+	column   parquet.Column
+}{
+	{"__version", parquet.Column{Name: "record_version", Type: parquet.TypeInt64}},
+	{"__created_time", parquet.Column{Name: "created_time", Type: parquet.TypeTimestamp}},
+	{"__last_modified_time", parquet.Column{Name: "last_modified_time", Type: parquet.TypeTimestamp}},
+}
+
+// exportRecordIDColumn 记录ID列
+var exportRecordIDColumn = parquet.Column{Name: "record_id", Type: parquet.TypeString, Required: true}
+
+// DataExportService Base 数据导出服务 ✨
+// 创建任务后由后台逐表执行：按记录ID顺序流式读取物理表，写为 Parquet 临时文件后上传到对象存储，
+// 全部表完成后写入清单（manifest.json）。列类型由字段的存储类型决定，多值与 JSON 字段以 JSON 文本导出；
+// 与数据仓库同步一致，虚拟字段、加密字段与受脱敏策略保护的字段不会导出
+type DataExportService struct {
+	repo              dataexport.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	storage           attachment.Storage
+	cfg               config.DataExportConfig
+	now               func() time.Time
+}
+
+// NewDataExportService 创建数据导出服务
+func NewDataExportService(
+	repo dataexport.Repository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	storage attachment.Storage,
+	cfg config.DataExportConfig,
+) *DataExportService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.RowGroupSize <= 0 {
+		cfg.RowGroupSize = 50000
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 30 * time.Minute
+	}
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = time.Hour
+	}
+	if cfg.History <= 0 {
+		cfg.History = 50
+	}
+	return &DataExportService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		storage:           storage,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// CreateExport 创建导出任务（由后台在下一个轮询周期执行）
+func (s *DataExportService) CreateExport(ctx context.Context, userID, baseID string, req CreateDataExportRequest) (*dataexport.Export, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限导出该Base的数据")
+	}
+	tables, err := s.tableRepo.GetByBaseID(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取表格失败")
+	}
+	inBase := make(map[string]bool, len(tables))
+	var all []string
+	for _, table := range tables {
+		inBase[table.ID().String()] = true
+		all = append(all, table.ID().String())
+	}
+
+	tableIDs := req.TableIDs
+	if len(tableIDs) == 0 {
+		tableIDs = all
+	}
+	for _, id := range tableIDs {
+		if !inBase[id] {
+			return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在: " + id)
+		}
+	}
+
+	export := dataexport.NewExport(baseID, tableIDs, userID, s.now())
+	if req.Format != "" {
+		export.Format = strings.ToLower(req.Format)
+	}
+	if err := export.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, export); err != nil {
+		return nil, pkgerrors.Database(err, "保存导出任务失败")
+	}
+	return export, nil
+}
+
+// ListExports 列出 Base 最近的导出任务
+func (s *DataExportService) ListExports(ctx context.Context, userID, baseID string) ([]*dataexport.Export, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限导出该Base的数据")
+	}
+	exports, err := s.repo.ListByBase(ctx, baseID, s.cfg.History)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取导出任务失败")
+	}
+	return exports, nil
+}
+
+// GetExport 获取导出任务，已完成的任务附带清单与数据文件的下载链接
+func (s *DataExportService) GetExport(ctx context.Context, userID, exportID string) (*DataExportDetail, error) {
+	export, err := s.repo.FindByID(ctx, exportID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取导出任务失败")
+	}
+	if export == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("导出任务不存在")
+	}
+	if !s.permissionService.CanUpdateBase(ctx, userID, export.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限导出该Base的数据")
+	}
+
+	detail := &DataExportDetail{Export: export}
+	if export.Status != dataexport.StatusCompleted || export.Manifest == nil {
+		return detail, nil
+	}
+	if detail.ManifestURL, err = s.storage.GetURL(ctx, export.ManifestPath(), s.cfg.URLExpiry); err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("生成下载链接失败")
+	}
+	detail.FileURLs = make(map[string]string, len(export.Manifest.Tables))
+	for _, table := range export.Manifest.Tables {
+		url, err := s.storage.GetURL(ctx, table.Path, s.cfg.URLExpiry)
+		if err != nil {
+			return nil, pkgerrors.ErrInternalServer.WithDetails("生成下载链接失败")
+		}
+		detail.FileURLs[table.TableID] = url
+	}
+	return detail, nil
+}
+
+// Start 启动后台导出
+func (s *DataExportService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runPending(ctx)
+		}
+	}()
+}
+
+// runPending 领取并执行待执行或已中断的任务
+func (s *DataExportService) runPending(ctx context.Context) {
+	now := s.now()
+	exports, err := s.repo.Claim(ctx, now, now.Add(-s.cfg.LeaseDuration), 2)
+	if err != nil {
+		dataExportLog.Warn(ctx, "领取导出任务失败", logger.ErrorField(err))
+		return
+	}
+	for _, export := range exports {
+		s.runExport(ctx, export)
+	}
+}
+
+// runExport 执行导出并保存结果
+func (s *DataExportService) runExport(ctx context.Context, export *dataexport.Export) {
+	manifest, err := s.export(ctx, export)
+	export.Finish(manifest, err, s.now())
+	if saveErr := s.repo.Save(ctx, export); saveErr != nil {
+		dataExportLog.Warn(ctx, "保存导出任务失败", logger.String("export_id", export.ID), logger.ErrorField(saveErr))
+	}
+	if err != nil {
+		dataExportLog.Warn(ctx, "数据导出失败",
+			logger.String("export_id", export.ID),
+			logger.String("base_id", export.BaseID),
+			logger.ErrorField(err))
+	}
+}
+
+// export 逐表导出并写入清单；每完成一张表刷新任务的更新时间，避免长任务被视为中断
+func (s *DataExportService) export(ctx context.Context, export *dataexport.Export) (*dataexport.Manifest, error) {
+	manifest := &dataexport.Manifest{
+		Format:    dataexport.ManifestFormat,
+		ExportID:  export.ID,
+		BaseID:    export.BaseID,
+		CreatedAt: export.CreatedAt,
+		Tables:    make([]dataexport.ManifestTable, 0, len(export.TableIDs)),
+	}
+	for _, tableID := range export.TableIDs {
+		table, err := s.exportTable(ctx, export, tableID)
+		if err != nil {
+			return nil, fmt.Errorf("export table %s: %w", tableID, err)
+		}
+		manifest.Tables = append(manifest.Tables, *table)
+
+		export.UpdatedAt = s.now()
+		if err := s.repo.Save(ctx, export); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Upload(ctx, export.ManifestPath(), bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return nil, fmt.Errorf("upload manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// exportTable 将一张表写为 Parquet 临时文件并上传
+func (s *DataExportService) exportTable(ctx context.Context, export *dataexport.Export, tableID string) (*dataexport.ManifestTable, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil || table.BaseID() != export.BaseID {
+		return nil, fmt.Errorf("table not found")
+	}
+	fields, err := s.exportFields(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	columns, manifestColumns := exportColumns(fields)
+
+	file, err := os.CreateTemp("", "luckdb-export-*.parquet")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	writer, err := parquet.NewWriter(file, columns, s.cfg.RowGroupSize)
+	if err != nil {
+		return nil, err
+	}
+	stream := newRecordStream(s.dataDB(ctx, export.BaseID), s.dbProvider.GenerateTableName(export.BaseID, tableID), fields, s.cfg.BatchSize)
+	values := make([]interface{}, len(columns))
+	for stream.Next(ctx) {
+		row := stream.Row()
+		values[0] = fmt.Sprint(row["__id"])
+		for i, sys := range exportSystemColumns {
+			values[1+i] = parquetValue(row[sys.physical], sys.column.Type)
+		}
+		offset := 1 + len(exportSystemColumns)
+		for i, field := range fields {
+			values[offset+i] = parquetValue(row[field.DBFieldName().String()], columns[offset+i].Type)
+		}
+		if err := writer.Write(values); err != nil {
+			return nil, err
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	path := export.DataPath(tableID)
+	if err := s.storage.Upload(ctx, path, file, size, "application/vnd.apache.parquet"); err != nil {
+		return nil, fmt.Errorf("upload parquet file: %w", err)
+	}
+
+	return &dataexport.ManifestTable{
+		TableID: tableID,
+		Name:    table.Name().String(),
+		Path:    path,
+		Rows:    writer.NumRows(),
+		Bytes:   size,
+		Columns: manifestColumns,
+	}, nil
+}
+
+// exportFields 导出的字段（按字段顺序，排除虚拟字段、加密字段与受保护字段）
+func (s *DataExportService) exportFields(ctx context.Context, tableID string) ([]*entity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	}
+	exported := make([]*entity.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.IsVirtual() || field.IsEncrypted() || protected[field.ID().String()] {
+			continue
+		}
+		exported = append(exported, field)
+	}
+	sort.SliceStable(exported, func(i, j int) bool { return exported[i].Order() < exported[j].Order() })
+	return exported, nil
+}
+
+// exportColumns 导出文件的列与清单中的列说明：record_id、系统列，之后为字段列（列名使用字段的物理列名）
+func exportColumns(fields []*entity.Field) ([]parquet.Column, []dataexport.ManifestColumn) {
+	columns := make([]parquet.Column, 0, 1+len(exportSystemColumns)+len(fields))
+	manifest := make([]dataexport.ManifestColumn, 0, cap(columns))
+
+	columns = append(columns, exportRecordIDColumn)
+	manifest = append(manifest, dataexport.ManifestColumn{Name: exportRecordIDColumn.Name, Type: string(exportRecordIDColumn.Type)})
+	for _, sys := range exportSystemColumns {
+		columns = append(columns, sys.column)
+		manifest = append(manifest, dataexport.ManifestColumn{Name: sys.column.Name, Type: string(sys.column.Type), Nullable: true})
+	}
+	for _, field := range fields {
+		column := parquet.Column{Name: field.DBFieldName().String(), Type: parquetColumnType(field)}
+		columns = append(columns, column)
+		manifest = append(manifest, dataexport.ManifestColumn{
+			Name:      column.Name,
+			FieldID:   field.ID().String(),
+			FieldName: field.Name().String(),
+			FieldType: field.Type().String(),
+			Type:      string(column.Type),
+			Nullable:  true,
+		})
+	}
+	return columns, manifest
+}
+
+// parquetColumnType 字段存储类别对应的 Parquet 列类型
+func parquetColumnType(field *entity.Field) parquet.Type {
+	switch columnKindOf(field) {
+	case columnKindNumber:
+		dbType := strings.ToUpper(field.DBFieldType())
+		if dbType == "INTEGER" || dbType == "BIGINT" || dbType == "SERIAL" {
+			return parquet.TypeInt64
+		}
+		return parquet.TypeDouble
+	case columnKindDate:
+		return parquet.TypeTimestamp
+	case columnKindBoolean:
+		return parquet.TypeBoolean
+	case columnKindJSON, columnKindArray, columnKindLocation:
+		return parquet.TypeJSON
+	default:
+		return parquet.TypeString
+	}
+}
+
+// parquetValue 将物理列的值转换为 Parquet 列类型对应的 Go 值，无法转换的值按空值导出
+func parquetValue(value interface{}, columnType parquet.Type) interface{} {
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if value == nil {
+		return nil
+	}
+
+	switch columnType {
+	case parquet.TypeInt64:
+		switch v := value.(type) {
+		case int64:
+			return v
+		case int:
+			return int64(v)
+		case int32:
+			return int64(v)
+		case float64:
+			return int64(v)
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n
+			}
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return int64(f)
+			}
+		}
+		return nil
+	case parquet.TypeDouble:
+		switch v := value.(type) {
+		case float64:
+			return v
+		case float32:
+			return float64(v)
+		case int64:
+			return float64(v)
+		case int:
+			return float64(v)
+		case int32:
+			return float64(v)
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+		return nil
+	case parquet.TypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		case int64:
+			return v != 0
+		}
+		return nil
+	case parquet.TypeTimestamp:
+		switch v := value.(type) {
+		case time.Time:
+			return v
+		case string:
+			for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07", "2006-01-02 15:04:05", "2006-01-02"} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t
+				}
+			}
+		}
+		return nil
+	case parquet.TypeJSON:
+		if s, ok := value.(string); ok {
+			return s
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil
+		}
+		return string(data)
+	default:
+		switch v := value.(type) {
+		case string:
+			return v
+		case time.Time:
+			return v.UTC().Format(time.RFC3339Nano)
+		case map[string]interface{}, []interface{}:
+			if data, err := json.Marshal(v); err == nil {
+				return string(data)
+			}
+		}
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/parquet"
+)
+
+func TestParquetValue(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name  string
+		value interface{}
+		typ   parquet.Type
+		want  interface{}
+	}{
+		{"空值", nil, parquet.TypeString, nil},
+		{"NUMERIC 文本转为浮点", "12.50", parquet.TypeDouble, 12.5},
+		{"整数列", int32(7), parquet.TypeInt64, int64(7)},
+		{"无法转换的数字按空值", "abc", parquet.TypeInt64, nil},
+		{"布尔文本", "true", parquet.TypeBoolean, true},
+		{"时间戳文本", "2024-05-01T08:00:00Z", parquet.TypeTimestamp, ts},
+		{"JSON 字节", []byte(`{"a":1}`), parquet.TypeJSON, `{"a":1}`},
+		{"多值序列化为 JSON", []interface{}{"a", "b"}, parquet.TypeJSON, `["a","b"]`},
+		{"文本列中的数字", int64(3), parquet.TypeString, "3"},
+	}
+	for _, c := range cases {
+		got := parquetValue(c.value, c.typ)
+		if want, ok := c.want.(time.Time); ok {
+			if tm, ok := got.(time.Time); !ok || !tm.Equal(want) {
+				t.Errorf("%s: 期望 %v，得到 %v", c.name, c.want, got)
+			}
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: 期望 %v，得到 %v", c.name, c.want, got)
+		}
+	}
+}
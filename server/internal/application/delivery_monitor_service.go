@@ -0,0 +1,361 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+var deliveryMonitorLog = logger.Named("delivery-monitor")
+
+const (
+	// deliveryMonitorMaxLimit 列表接口单次返回的最大条数
+	deliveryMonitorMaxLimit = 200
+	// deliveryMonitorMaxRetry 单次批量重试的最大条数
+	deliveryMonitorMaxRetry = 100
+
+	// workflowRunRetried 已手动重试的失败运行（重试会创建新的运行）
+	workflowRunRetried = "retried"
+)
+
+// HookHealth 订阅的推送状态
+type HookHealth struct {
+	*integration.Hook
+	Status           string `json:"status"` // active / failing / paused
+	FailedDeliveries int64  `json:"failedDeliveries"`
+}
+
+// DeliveryOverview 推送保障概览
+type DeliveryOverview struct {
+	Hooks            []HookHealth `json:"hooks"`
+	PausedHooks      int          `json:"pausedHooks"`
+	FailedDeliveries int64        `json:"failedDeliveries"`
+	FailedRuns       int64        `json:"failedRuns"`
+}
+
+// DeliveryRetryRequest 批量重试请求
+type DeliveryRetryRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// DeliveryRetryResult 批量重试失败推送的结果
+type DeliveryRetryResult struct {
+	Retried int      `json:"retried"`
+	HookIDs []string `json:"hookIds"` // 已恢复推送的订阅
+}
+
+// DeadLetterSettingsRequest 更新死信设置请求（未提供的字段保持不变）
+type DeadLetterSettingsRequest struct {
+	MaxFailures *int  `json:"maxFailures"` // 连续失败多少次后暂停，0 表示不暂停
+	NotifyOwner *bool `json:"notifyOwner"`
+}
+
+// DeliveryMonitorService 推送保障与死信管理 ✨
+// 汇总当前用户的 REST Hook 订阅与自动化运行的失败情况：
+//   - 订阅：查看失败推送的状态码、错误与响应体，批量重试（恢复订阅，从失败的位置重新推送），
+//     配置连续失败多少次后暂停以及暂停时是否通知订阅者；
+//   - 自动化：查看失败的工作流运行及失败步骤，批量重试（以原输入重新运行）
+type DeliveryMonitorService struct {
+	hooks      integration.Repository
+	deliveries integration.DeliveryRepository
+	workflows  *WorkflowService
+	db         *gorm.DB
+}
+
+// NewDeliveryMonitorService 创建推送保障服务
+func NewDeliveryMonitorService(
+	hooks integration.Repository,
+	deliveries integration.DeliveryRepository,
+	workflows *WorkflowService,
+	db *gorm.DB,
+) *DeliveryMonitorService {
+	return &DeliveryMonitorService{
+		hooks:      hooks,
+		deliveries: deliveries,
+		workflows:  workflows,
+		db:         db,
+	}
+}
+
+// Overview 当前用户的订阅状态与失败统计
+func (s *DeliveryMonitorService) Overview(ctx context.Context, userID string) (*DeliveryOverview, error) {
+	hooks, err := s.hooks.ListByCreator(ctx, userID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取订阅失败")
+	}
+	ids := make([]string, 0, len(hooks))
+	for _, hook := range hooks {
+		ids = append(ids, hook.ID)
+	}
+	counts, err := s.deliveries.CountFailed(ctx, ids)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "统计失败推送失败")
+	}
+
+	overview := &DeliveryOverview{Hooks: make([]HookHealth, 0, len(hooks))}
+	for _, hook := range hooks {
+		health := HookHealth{Hook: hook, Status: "active", FailedDeliveries: counts[hook.ID]}
+		switch {
+		case hook.Paused():
+			health.Status = "paused"
+			overview.PausedHooks++
+		case hook.Failures > 0:
+			health.Status = "failing"
+		}
+		overview.FailedDeliveries += health.FailedDeliveries
+		overview.Hooks = append(overview.Hooks, health)
+	}
+
+	if err := s.db.WithContext(ctx).Model(&models.WorkflowRun{}).
+		Where("created_by = ? AND status = ?", userID, "failed").
+		Count(&overview.FailedRuns).Error; err != nil {
+		return nil, pkgerrors.Database(err, "统计失败运行失败")
+	}
+	return overview, nil
+}
+
+// ListDeliveries 列出失败推送（hookID 为空时列出当前用户全部订阅的记录）
+func (s *DeliveryMonitorService) ListDeliveries(ctx context.Context, userID, hookID string, status integration.DeliveryStatus, limit int) ([]*integration.Delivery, error) {
+	var hookIDs []string
+	if hookID != "" {
+		hook, err := s.ownedHook(ctx, userID, hookID)
+		if err != nil {
+			return nil, err
+		}
+		hookIDs = []string{hook.ID}
+	} else {
+		hooks, err := s.hooks.ListByCreator(ctx, userID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "获取订阅失败")
+		}
+		for _, hook := range hooks {
+			hookIDs = append(hookIDs, hook.ID)
+		}
+	}
+
+	filter := integration.DeliveryFilter{HookIDs: hookIDs, Limit: clampMonitorLimit(limit)}
+	if status != "" {
+		switch status {
+		case integration.DeliveryFailed, integration.DeliveryRetried, integration.DeliveryResolved:
+		default:
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的状态: " + string(status))
+		}
+		filter.Status = &status
+	}
+	deliveries, err := s.deliveries.List(ctx, filter)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取失败推送失败")
+	}
+	return deliveries, nil
+}
+
+// RetryDeliveries 批量重试失败推送：恢复所属订阅并立即从失败的位置重新推送
+// 只能重试状态为 failed 的记录；再次失败时会记录新的失败推送
+func (s *DeliveryMonitorService) RetryDeliveries(ctx context.Context, userID string, req DeliveryRetryRequest) (*DeliveryRetryResult, error) {
+	if len(req.IDs) == 0 || len(req.IDs) > deliveryMonitorMaxRetry {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多重试 %d 条", deliveryMonitorMaxRetry))
+	}
+	deliveries, err := s.deliveries.FindByIDs(ctx, req.IDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取失败推送失败")
+	}
+	if len(deliveries) != len(uniqueStrings(req.IDs)) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("部分失败推送不存在")
+	}
+
+	var ids, hookIDs []string
+	checked := map[string]bool{}
+	for _, delivery := range deliveries {
+		if delivery.Status != integration.DeliveryFailed {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("失败推送 %s 的状态为 %s，无需重试", delivery.ID, delivery.Status))
+		}
+		if !checked[delivery.HookID] {
+			if _, err := s.ownedHook(ctx, userID, delivery.HookID); err != nil {
+				return nil, err
+			}
+			checked[delivery.HookID] = true
+			hookIDs = append(hookIDs, delivery.HookID)
+		}
+		ids = append(ids, delivery.ID)
+	}
+
+	if err := s.deliveries.MarkRetried(ctx, ids, time.Now()); err != nil {
+		return nil, pkgerrors.Database(err, "更新失败推送失败")
+	}
+	for _, hookID := range hookIDs {
+		if err := s.hooks.Resume(ctx, hookID); err != nil {
+			return nil, pkgerrors.Database(err, "恢复订阅失败")
+		}
+	}
+	return &DeliveryRetryResult{Retried: len(ids), HookIDs: hookIDs}, nil
+}
+
+// ResumeHook 恢复已暂停的订阅
+func (s *DeliveryMonitorService) ResumeHook(ctx context.Context, userID, hookID string) (*integration.Hook, error) {
+	hook, err := s.ownedHook(ctx, userID, hookID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.hooks.Resume(ctx, hookID); err != nil {
+		return nil, pkgerrors.Database(err, "恢复订阅失败")
+	}
+	hook.Resume()
+	return hook, nil
+}
+
+// UpdateDeadLetter 更新订阅的暂停阈值与通知设置
+func (s *DeliveryMonitorService) UpdateDeadLetter(ctx context.Context, userID, hookID string, req DeadLetterSettingsRequest) (*integration.Hook, error) {
+	hook, err := s.ownedHook(ctx, userID, hookID)
+	if err != nil {
+		return nil, err
+	}
+	if req.MaxFailures != nil {
+		if *req.MaxFailures < 0 {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("maxFailures 不能为负数")
+		}
+		hook.MaxFailures = *req.MaxFailures
+	}
+	if req.NotifyOwner != nil {
+		hook.NotifyOwner = *req.NotifyOwner
+	}
+	if err := s.hooks.UpdateDeadLetter(ctx, hook); err != nil {
+		return nil, pkgerrors.Database(err, "保存死信设置失败")
+	}
+	return hook, nil
+}
+
+// ListFailedRuns 列出当前用户触发的失败运行（含失败的步骤）
+func (s *DeliveryMonitorService) ListFailedRuns(ctx context.Context, userID, workflowID string, limit int) ([]*models.WorkflowRun, error) {
+	query := s.db.WithContext(ctx).
+		Preload("Steps", "status = ?", "failed").
+		Where("created_by = ? AND status = ?", userID, "failed")
+	if workflowID != "" {
+		query = query.Where("workflow_id = ?", workflowID)
+	}
+	var runs []*models.WorkflowRun
+	if err := query.Order("created_time DESC").Limit(clampMonitorLimit(limit)).Find(&runs).Error; err != nil {
+		return nil, pkgerrors.Database(err, "获取失败运行失败")
+	}
+	return runs, nil
+}
+
+// RetryRuns 批量重试失败运行：以原输入重新运行工作流，原运行标记为 retried
+func (s *DeliveryMonitorService) RetryRuns(ctx context.Context, userID string, req DeliveryRetryRequest) ([]*models.WorkflowRun, error) {
+	if len(req.IDs) == 0 || len(req.IDs) > deliveryMonitorMaxRetry {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多重试 %d 条", deliveryMonitorMaxRetry))
+	}
+	var runs []*models.WorkflowRun
+	if err := s.db.WithContext(ctx).Where("id IN ?", req.IDs).Find(&runs).Error; err != nil {
+		return nil, pkgerrors.Database(err, "获取运行记录失败")
+	}
+	if len(runs) != len(uniqueStrings(req.IDs)) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("部分运行记录不存在")
+	}
+	for _, run := range runs {
+		if run.CreatedBy != userID {
+			return nil, pkgerrors.ErrForbidden.WithDetails("只能重试自己触发的运行")
+		}
+		if run.Status != "failed" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("运行 %s 的状态为 %s，无需重试", run.ID, run.Status))
+		}
+	}
+
+	retried := make([]*models.WorkflowRun, 0, len(runs))
+	for _, run := range runs {
+		var input map[string]interface{}
+		if run.Input != nil {
+			if err := json.Unmarshal([]byte(*run.Input), &input); err != nil {
+				return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("运行 %s 的输入无法解析", run.ID))
+			}
+		}
+		next, err := s.workflows.Run(ctx, run.WorkflowID, userID, input)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "重新运行工作流失败")
+		}
+		metadata := jsonString(map[string]interface{}{"retriedBy": next.ID})
+		if err := s.db.WithContext(ctx).Model(&models.WorkflowRun{}).
+			Where("id = ? AND status = ?", run.ID, "failed").
+			Updates(map[string]interface{}{"status": workflowRunRetried, "metadata": metadata}).Error; err != nil {
+			return nil, pkgerrors.Database(err, "更新运行记录失败")
+		}
+		retried = append(retried, next)
+	}
+	return retried, nil
+}
+
+// NotifyHookPaused 订阅因连续失败被暂停时通知订阅者（作为 IntegrationService 的暂停回调）
+func (s *DeliveryMonitorService) NotifyHookPaused(ctx context.Context, hook *integration.Hook) {
+	deliveryMonitorLog.Warn(ctx, "订阅连续推送失败，已暂停",
+		logger.String("hook_id", hook.ID),
+		logger.Int("failures", hook.Failures))
+	if !hook.NotifyOwner || hook.CreatedBy == "" {
+		return
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"hookId":    hook.ID,
+		"tableId":   hook.TableID,
+		"targetUrl": hook.TargetURL,
+		"failures":  hook.Failures,
+		"lastError": hook.LastError,
+	})
+	notification := models.Notification{
+		ID:         utils.GenerateNanoID(10),
+		UserID:     hook.CreatedBy,
+		Type:       "system",
+		Title:      "REST Hook 订阅已暂停",
+		Content:    fmt.Sprintf("推送到 %s 连续失败 %d 次，订阅已暂停。最近的错误：%s", hook.TargetURL, hook.Failures, hook.LastError),
+		Data:       string(data),
+		Status:     "unread",
+		Priority:   "high",
+		SourceID:   hook.ID,
+		SourceType: "integration_hook",
+	}
+	if err := s.db.WithContext(ctx).Create(&notification).Error; err != nil {
+		deliveryMonitorLog.Warn(ctx, "发送订阅暂停通知失败", logger.String("hook_id", hook.ID), logger.ErrorField(err))
+	}
+}
+
+// ownedHook 获取当前用户创建的订阅
+func (s *DeliveryMonitorService) ownedHook(ctx context.Context, userID, hookID string) (*integration.Hook, error) {
+	hook, err := s.hooks.FindByID(ctx, hookID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取订阅失败")
+	}
+	if hook == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("订阅不存在")
+	}
+	if hook.CreatedBy != userID {
+		return nil, pkgerrors.ErrForbidden.WithDetails("只能管理自己创建的订阅")
+	}
+	return hook, nil
+}
+
+// uniqueStrings 去重（保持顺序）
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func clampMonitorLimit(limit int) int {
+	if limit <= 0 || limit > deliveryMonitorMaxLimit {
+		return deliveryMonitorMaxLimit
+	}
+	return limit
+}
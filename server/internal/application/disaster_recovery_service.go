@@ -0,0 +1,419 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/disasterrecovery"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var disasterRecoveryLog = logger.Named("disaster_recovery")
+
+const (
+	// drSnapshotOverlap 增量快照向前多复制的时间窗口，容忍应用与数据库之间的时钟偏差（写入幂等）
+	drSnapshotOverlap = time.Minute
+	// drMaxBatchesPerRun 单次复制变更事件或附件的批数上限，剩余部分在下一轮继续
+	drMaxBatchesPerRun = 100
+	// drPingTimeout 检查主区域可达性的超时
+	drPingTimeout = 5 * time.Second
+)
+
+// drReplicator 灾备复制器
+type drReplicator interface {
+	Ping(ctx context.Context) error
+	ReplicateSnapshot(ctx context.Context, since time.Time) (*database.DRSnapshotReport, error)
+	ReplicateEvents(ctx context.Context, afterSeq int64, limit int) (*database.DREventBatch, error)
+	PendingEvents(ctx context.Context, afterSeq int64) (int64, *time.Time, error)
+	AttachmentsAfter(ctx context.Context, after time.Time, afterID string, limit int) ([]database.DRAttachment, error)
+	PendingAttachments(ctx context.Context, after time.Time, afterID string) (int64, *time.Time, error)
+	PrepareSecondary(ctx context.Context) error
+}
+
+// PromoteRequest 提升备区域请求
+type PromoteRequest struct {
+	Force  bool   `json:"force"` // 跳过最终同步（主区域不可达时必须指定），接受 RPO 范围内的数据丢失
+	Reason string `json:"reason"`
+}
+
+// DisasterRecoveryService 跨区域灾备服务 ✨
+// 后台将快照（元数据表与 Base 数据表）、变更事件日志与附件文件分别异步复制到备区域，
+// 复制进度保存在备区域，据此报告 RPO 与复制延迟；故障时按手册提升备区域，提升后停止复制
+type DisasterRecoveryService struct {
+	repo             disasterrecovery.Repository
+	replicator       drReplicator
+	primaryStorage   attachment.Storage
+	secondaryStorage attachment.Storage
+	cfg              config.DisasterRecoveryConfig
+	now              func() time.Time
+
+	mu       sync.RWMutex // 复制持有读锁，提升持有写锁
+	promoted bool
+}
+
+// NewDisasterRecoveryService 创建灾备服务
+func NewDisasterRecoveryService(
+	repo disasterrecovery.Repository,
+	replicator drReplicator,
+	primaryStorage attachment.Storage,
+	secondaryStorage attachment.Storage,
+	cfg config.DisasterRecoveryConfig,
+) *DisasterRecoveryService {
+	return &DisasterRecoveryService{
+		repo:             repo,
+		replicator:       replicator,
+		primaryStorage:   primaryStorage,
+		secondaryStorage: secondaryStorage,
+		cfg:              cfg,
+		now:              time.Now,
+	}
+}
+
+// Start 启动各复制流的后台复制（备区域已被提升时不启动）
+func (s *DisasterRecoveryService) Start(ctx context.Context) {
+	promotion, err := s.repo.LatestPromotion(ctx)
+	if err != nil {
+		disasterRecoveryLog.Warn(ctx, "读取灾备提升记录失败", logger.ErrorField(err))
+	}
+	if promotion != nil {
+		s.promoted = true
+		disasterRecoveryLog.Warn(ctx, "备区域已被提升，不再复制",
+			logger.String("region", promotion.Region),
+			logger.String("promotion_id", promotion.ID))
+		return
+	}
+
+	intervals := map[disasterrecovery.Stream]time.Duration{
+		disasterrecovery.StreamSnapshot:    s.cfg.SnapshotInterval,
+		disasterrecovery.StreamEventLog:    s.cfg.EventInterval,
+		disasterrecovery.StreamAttachments: s.cfg.AttachmentInterval,
+	}
+	for _, stream := range disasterrecovery.Streams {
+		go s.loop(ctx, stream, intervals[stream])
+	}
+}
+
+func (s *DisasterRecoveryService) loop(ctx context.Context, stream disasterrecovery.Stream, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, stream)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run 复制一次，已提升时跳过
+func (s *DisasterRecoveryService) run(ctx context.Context, stream disasterrecovery.Stream) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.promoted {
+		return
+	}
+	if err := s.replicate(ctx, stream); err != nil {
+		disasterRecoveryLog.Warn(ctx, "灾备复制失败",
+			logger.String("stream", string(stream)),
+			logger.ErrorField(err))
+	}
+}
+
+// replicate 在租约内复制一个复制流并保存进度；其他实例持有租约时直接返回
+func (s *DisasterRecoveryService) replicate(ctx context.Context, stream disasterrecovery.Stream) error {
+	now := s.now()
+	acquired, err := s.repo.Acquire(ctx, stream, now, now.Add(s.cfg.LeaseDuration))
+	if err != nil || !acquired {
+		return err
+	}
+	defer func() {
+		if err := s.repo.Release(ctx, stream); err != nil {
+			disasterRecoveryLog.Warn(ctx, "释放灾备复制租约失败", logger.String("stream", string(stream)), logger.ErrorField(err))
+		}
+	}()
+
+	cp, err := s.repo.FindCheckpoint(ctx, stream)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = disasterrecovery.NewCheckpoint(stream, now)
+	}
+
+	var progress drProgress
+	switch stream {
+	case disasterrecovery.StreamSnapshot:
+		progress, err = s.replicateSnapshot(ctx, cp)
+	case disasterrecovery.StreamEventLog:
+		progress, err = s.replicateEvents(ctx, cp)
+	case disasterrecovery.StreamAttachments:
+		progress, err = s.replicateAttachments(ctx, cp)
+	default:
+		return fmt.Errorf("未知的复制流 %s", stream)
+	}
+
+	if err != nil {
+		cp.Fail(progress.position, progress.items, err, s.now())
+	} else {
+		cp.Succeed(progress.position, progress.through, progress.items, s.now())
+	}
+	if saveErr := s.repo.SaveCheckpoint(ctx, cp); saveErr != nil {
+		return saveErr
+	}
+	return err
+}
+
+// drProgress 一次复制的结果
+type drProgress struct {
+	position string
+	through  time.Time // 主区域在该时间之前的数据已全部复制
+	items    int64
+}
+
+// replicateSnapshot 复制元数据表与 Base 数据表（首次全量，之后按上次快照开始时间增量）
+func (s *DisasterRecoveryService) replicateSnapshot(ctx context.Context, cp *disasterrecovery.Checkpoint) (drProgress, error) {
+	start := s.now()
+	var since time.Time
+	if cp.ReplicatedThrough != nil {
+		since = cp.ReplicatedThrough.Add(-drSnapshotOverlap)
+	}
+	report, err := s.replicator.ReplicateSnapshot(ctx, since)
+	if err != nil {
+		return drProgress{position: cp.Position}, err
+	}
+	disasterRecoveryLog.Info(ctx, "灾备快照复制完成",
+		logger.Int("metadata_tables", report.MetadataTables),
+		logger.Int("data_tables", report.DataTables),
+		logger.Int64("rows_copied", report.RowsCopied),
+		logger.Int64("rows_removed", report.RowsRemoved))
+	return drProgress{through: start, items: report.RowsCopied}, nil
+}
+
+// replicateEvents 按 seq 顺序复制变更事件
+func (s *DisasterRecoveryService) replicateEvents(ctx context.Context, cp *disasterrecovery.Checkpoint) (drProgress, error) {
+	start := s.now()
+	afterSeq := parseEventPosition(cp.Position)
+	progress := drProgress{position: cp.Position}
+
+	for i := 0; i < drMaxBatchesPerRun; i++ {
+		batch, err := s.replicator.ReplicateEvents(ctx, afterSeq, s.cfg.BatchSize)
+		if err != nil {
+			return progress, err
+		}
+		if batch.Events > 0 {
+			afterSeq = batch.LastSeq
+			progress.position = strconv.FormatInt(afterSeq, 10)
+			progress.items += int64(batch.Events)
+		}
+		if batch.Events < s.cfg.BatchSize {
+			progress.through = start
+			return progress, nil
+		}
+		// 达到批数上限时只能确认已复制到最后一条事件的时间
+		progress.through = batch.LastTime
+	}
+	return progress, nil
+}
+
+// replicateAttachments 按上传时间顺序将附件文件（含缩略图）复制到备区域存储
+func (s *DisasterRecoveryService) replicateAttachments(ctx context.Context, cp *disasterrecovery.Checkpoint) (drProgress, error) {
+	start := s.now()
+	after, afterID := parseAttachmentPosition(cp.Position)
+	progress := drProgress{position: cp.Position}
+
+	for i := 0; i < drMaxBatchesPerRun; i++ {
+		list, err := s.replicator.AttachmentsAfter(ctx, after, afterID, s.cfg.BatchSize)
+		if err != nil {
+			return progress, err
+		}
+		for _, item := range list {
+			for _, path := range item.Paths {
+				if err := s.copyFile(ctx, path); err != nil {
+					return progress, fmt.Errorf("复制附件 %s 失败: %w", item.ID, err)
+				}
+			}
+			after, afterID = item.CreatedTime, item.ID
+			progress.position = formatAttachmentPosition(after, afterID)
+			progress.items++
+		}
+		if len(list) < s.cfg.BatchSize {
+			progress.through = start
+			return progress, nil
+		}
+		progress.through = after
+	}
+	return progress, nil
+}
+
+// copyFile 复制单个文件：备区域已存在时跳过，主区域文件缺失时记录后跳过
+func (s *DisasterRecoveryService) copyFile(ctx context.Context, path string) error {
+	exists, err := s.secondaryStorage.Exists(ctx, path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	if ok, err := s.primaryStorage.Exists(ctx, path); err != nil {
+		return err
+	} else if !ok {
+		disasterRecoveryLog.Warn(ctx, "主区域附件文件不存在，跳过复制", logger.String("path", path))
+		return nil
+	}
+
+	size, err := s.primaryStorage.GetSize(ctx, path)
+	if err != nil {
+		return err
+	}
+	reader, err := s.primaryStorage.Download(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return s.secondaryStorage.Upload(ctx, path, reader, size, "")
+}
+
+func parseEventPosition(position string) int64 {
+	seq, _ := strconv.ParseInt(position, 10, 64)
+	return seq
+}
+
+// 附件游标格式：上传时间（RFC3339Nano）|附件ID
+func formatAttachmentPosition(after time.Time, afterID string) string {
+	return after.UTC().Format(time.RFC3339Nano) + "|" + afterID
+}
+
+func parseAttachmentPosition(position string) (time.Time, string) {
+	at, id, ok := strings.Cut(position, "|")
+	if !ok {
+		return time.Time{}, ""
+	}
+	after, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, ""
+	}
+	return after, id
+}
+
+// Status 获取灾备状态报告（主区域不可达时按已复制时间点估计 RPO）
+func (s *DisasterRecoveryService) Status(ctx context.Context) (*disasterrecovery.Report, error) {
+	promotion, err := s.repo.LatestPromotion(ctx)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, drPingTimeout)
+	reachable := s.replicator.Ping(pingCtx) == nil
+	cancel()
+
+	now := s.now()
+	streams := make([]disasterrecovery.StreamStatus, 0, len(disasterrecovery.Streams))
+	for _, stream := range disasterrecovery.Streams {
+		cp, err := s.repo.FindCheckpoint(ctx, stream)
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+		}
+		if cp == nil {
+			cp = disasterrecovery.NewCheckpoint(stream, now)
+		}
+		var pending *disasterrecovery.Pending
+		if reachable && promotion == nil {
+			pending = s.pending(ctx, cp)
+		}
+		streams = append(streams, disasterrecovery.Evaluate(cp, pending, now))
+	}
+	return disasterrecovery.NewReport(s.cfg.Region, s.cfg.RPOTarget, streams, promotion, reachable, now), nil
+}
+
+// pending 统计主区域中尚未复制的数据；快照流不逐行统计，返回 nil
+func (s *DisasterRecoveryService) pending(ctx context.Context, cp *disasterrecovery.Checkpoint) *disasterrecovery.Pending {
+	var (
+		count  int64
+		oldest *time.Time
+		err    error
+	)
+	switch cp.Stream {
+	case disasterrecovery.StreamEventLog:
+		count, oldest, err = s.replicator.PendingEvents(ctx, parseEventPosition(cp.Position))
+	case disasterrecovery.StreamAttachments:
+		after, afterID := parseAttachmentPosition(cp.Position)
+		count, oldest, err = s.replicator.PendingAttachments(ctx, after, afterID)
+	default:
+		return nil
+	}
+	if err != nil {
+		disasterRecoveryLog.Warn(ctx, "统计未复制数据失败", logger.String("stream", string(cp.Stream)), logger.ErrorField(err))
+		return nil
+	}
+	return &disasterrecovery.Pending{Items: count, Oldest: oldest}
+}
+
+// Runbook 获取故障切换手册
+func (s *DisasterRecoveryService) Runbook(ctx context.Context) (*disasterrecovery.Runbook, error) {
+	report, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return disasterrecovery.NewRunbook(report), nil
+}
+
+// Promote 提升备区域
+// 主区域可达时先对各复制流执行最终同步；不可达时必须指定 force，提升时的 RPO 记入提升记录。
+// 提升后本服务停止复制，切换服务配置仍需按手册人工完成
+func (s *DisasterRecoveryService) Promote(ctx context.Context, userID string, req PromoteRequest) (*disasterrecovery.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.repo.LatestPromotion(ctx)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+	if s.promoted || existing != nil {
+		return nil, pkgerrors.ErrConflict.WithDetails("备区域已被提升")
+	}
+
+	if !req.Force {
+		pingCtx, cancel := context.WithTimeout(ctx, drPingTimeout)
+		err := s.replicator.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			return nil, pkgerrors.ErrBadRequest.WithDetails("主区域不可达，无法执行最终同步；确认接受数据丢失后指定 force 提升")
+		}
+		for _, stream := range disasterrecovery.Streams {
+			if err := s.replicate(ctx, stream); err != nil {
+				return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("最终同步 %s 失败: %v", stream, err))
+			}
+		}
+	}
+
+	report, err := s.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.replicator.PrepareSecondary(ctx); err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("整理备区域失败: %v", err))
+	}
+
+	promotion := disasterrecovery.NewPromotion(s.cfg.Region, req.Force, report.RPOSeconds, strings.TrimSpace(req.Reason), userID, s.now())
+	if err := s.repo.SavePromotion(ctx, promotion); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+	s.promoted = true
+
+	disasterRecoveryLog.Warn(ctx, "⚠️ 备区域已提升，灾备复制停止",
+		logger.String("region", promotion.Region),
+		logger.String("promoted_by", userID),
+		logger.Bool("forced", promotion.Forced),
+		logger.Any("rpo_seconds", promotion.RPOSeconds))
+	return promotion, nil
+}
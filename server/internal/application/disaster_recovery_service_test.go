@@ -0,0 +1,244 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/disasterrecovery"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryDRRepo 内存实现的灾备仓储
+type memoryDRRepo struct {
+	checkpoints map[disasterrecovery.Stream]*disasterrecovery.Checkpoint
+	promotions  []*disasterrecovery.Promotion
+}
+
+func (r *memoryDRRepo) FindCheckpoint(_ context.Context, stream disasterrecovery.Stream) (*disasterrecovery.Checkpoint, error) {
+	if cp, ok := r.checkpoints[stream]; ok {
+		copied := *cp
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryDRRepo) SaveCheckpoint(_ context.Context, cp *disasterrecovery.Checkpoint) error {
+	copied := *cp
+	r.checkpoints[cp.Stream] = &copied
+	return nil
+}
+
+func (r *memoryDRRepo) Acquire(context.Context, disasterrecovery.Stream, time.Time, time.Time) (bool, error) {
+	return true, nil
+}
+
+func (r *memoryDRRepo) Release(context.Context, disasterrecovery.Stream) error { return nil }
+
+func (r *memoryDRRepo) LatestPromotion(context.Context) (*disasterrecovery.Promotion, error) {
+	if len(r.promotions) == 0 {
+		return nil, nil
+	}
+	return r.promotions[len(r.promotions)-1], nil
+}
+
+func (r *memoryDRRepo) SavePromotion(_ context.Context, promotion *disasterrecovery.Promotion) error {
+	r.promotions = append(r.promotions, promotion)
+	return nil
+}
+
+// fakeDRReplicator 以内存中的变更事件与附件模拟主区域
+type fakeDRReplicator struct {
+	down        bool
+	events      []time.Time // 下标 + 1 为 seq
+	attachments []database.DRAttachment
+	snapshots   []time.Time
+	prepared    bool
+}
+
+func (f *fakeDRReplicator) Ping(context.Context) error {
+	if f.down {
+		return errors.New("连接被拒绝")
+	}
+	return nil
+}
+
+func (f *fakeDRReplicator) ReplicateSnapshot(_ context.Context, since time.Time) (*database.DRSnapshotReport, error) {
+	f.snapshots = append(f.snapshots, since)
+	return &database.DRSnapshotReport{MetadataTables: 3, RowsCopied: 10}, nil
+}
+
+func (f *fakeDRReplicator) ReplicateEvents(_ context.Context, afterSeq int64, limit int) (*database.DREventBatch, error) {
+	batch := &database.DREventBatch{LastSeq: afterSeq}
+	for seq := afterSeq + 1; seq <= int64(len(f.events)) && batch.Events < limit; seq++ {
+		batch.Events++
+		batch.LastSeq = seq
+		batch.LastTime = f.events[seq-1]
+	}
+	return batch, nil
+}
+
+func (f *fakeDRReplicator) PendingEvents(_ context.Context, afterSeq int64) (int64, *time.Time, error) {
+	if afterSeq >= int64(len(f.events)) {
+		return 0, nil, nil
+	}
+	return int64(len(f.events)) - afterSeq, &f.events[afterSeq], nil
+}
+
+func (f *fakeDRReplicator) AttachmentsAfter(_ context.Context, after time.Time, afterID string, limit int) ([]database.DRAttachment, error) {
+	var list []database.DRAttachment
+	for _, a := range f.attachments {
+		if (a.CreatedTime.After(after) || (a.CreatedTime.Equal(after) && a.ID > afterID)) && len(list) < limit {
+			list = append(list, a)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeDRReplicator) PendingAttachments(ctx context.Context, after time.Time, afterID string) (int64, *time.Time, error) {
+	list, _ := f.AttachmentsAfter(ctx, after, afterID, len(f.attachments))
+	if len(list) == 0 {
+		return 0, nil, nil
+	}
+	return int64(len(list)), &list[0].CreatedTime, nil
+}
+
+func (f *fakeDRReplicator) PrepareSecondary(context.Context) error {
+	f.prepared = true
+	return nil
+}
+
+// uploadStorage 可写入的内存存储
+type uploadStorage struct {
+	memoryStorage
+}
+
+func (s *uploadStorage) Upload(_ context.Context, path string, reader io.Reader, _ int64, _ string) error {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, reader); err != nil {
+		return err
+	}
+	s.files[path] = buf.String()
+	return nil
+}
+
+func (s *uploadStorage) GetSize(_ context.Context, path string) (int64, error) {
+	return int64(len(s.files[path])), nil
+}
+
+func newTestDisasterRecoveryService(now time.Time) (*DisasterRecoveryService, *memoryDRRepo, *fakeDRReplicator, *uploadStorage) {
+	repo := &memoryDRRepo{checkpoints: map[disasterrecovery.Stream]*disasterrecovery.Checkpoint{}}
+	replicator := &fakeDRReplicator{}
+	primary := &uploadStorage{memoryStorage{files: map[string]string{"a/1.png": "img", "a/1_s.png": "thumb"}}}
+	secondary := &uploadStorage{memoryStorage{files: map[string]string{}}}
+	svc := NewDisasterRecoveryService(repo, replicator, primary, secondary, config.DisasterRecoveryConfig{
+		Region:    "dr",
+		BatchSize: 2,
+		RPOTarget: 15 * time.Minute,
+	})
+	svc.now = func() time.Time { return now }
+	return svc, repo, replicator, secondary
+}
+
+func TestDisasterRecoveryReplicate(t *testing.T) {
+	now := time.Now()
+	svc, repo, replicator, secondary := newTestDisasterRecoveryService(now)
+	ctx := context.Background()
+	replicator.events = []time.Time{now.Add(-3 * time.Minute), now.Add(-2 * time.Minute), now.Add(-time.Minute)}
+	replicator.attachments = []database.DRAttachment{
+		{ID: "att1", Paths: []string{"a/1.png", "a/1_s.png"}, CreatedTime: now.Add(-2 * time.Minute)},
+		{ID: "att2", Paths: []string{"a/missing.png"}, CreatedTime: now.Add(-time.Minute)},
+	}
+
+	report, err := svc.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.RPOSeconds != nil || report.WithinTarget {
+		t.Errorf("尚未复制时 RPO 应未知: %+v", report)
+	}
+
+	for _, stream := range disasterrecovery.Streams {
+		if err := svc.replicate(ctx, stream); err != nil {
+			t.Fatalf("复制 %s 失败: %v", stream, err)
+		}
+	}
+	if cp := repo.checkpoints[disasterrecovery.StreamEventLog]; cp.Position != "3" || cp.Items != 3 || !cp.ReplicatedThrough.Equal(now) {
+		t.Errorf("变更事件进度 = %+v", cp)
+	}
+	if secondary.files["a/1.png"] != "img" || secondary.files["a/1_s.png"] != "thumb" {
+		t.Errorf("备区域文件 = %v", secondary.files)
+	}
+	if cp := repo.checkpoints[disasterrecovery.StreamAttachments]; cp.Items != 2 || cp.LastError != "" {
+		t.Errorf("附件进度 = %+v", cp)
+	}
+
+	// 新事件到达后 RPO 为最早未复制事件的时长
+	replicator.events = append(replicator.events, now.Add(-30*time.Second))
+	later := now.Add(time.Minute)
+	svc.now = func() time.Time { return later }
+	report, err = svc.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := report.Streams[1]
+	if *events.PendingItems != 1 || *events.RPOSeconds != 90 || *report.RPOSeconds != 90 || !report.WithinTarget {
+		t.Errorf("状态报告 = %+v，变更事件 = %+v", report, events)
+	}
+
+	// 增量快照从上次快照开始时间向前留出重叠窗口
+	if err := svc.replicate(ctx, disasterrecovery.StreamSnapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(replicator.snapshots) != 2 || !replicator.snapshots[0].IsZero() || !replicator.snapshots[1].Equal(now.Add(-drSnapshotOverlap)) {
+		t.Errorf("快照起点 = %v", replicator.snapshots)
+	}
+}
+
+func TestDisasterRecoveryPromote(t *testing.T) {
+	now := time.Now()
+	svc, repo, replicator, _ := newTestDisasterRecoveryService(now)
+	ctx := context.Background()
+	replicator.events = []time.Time{now.Add(-time.Minute)}
+
+	// 主区域不可达时必须指定 force
+	replicator.down = true
+	if _, err := svc.Promote(ctx, "usr1", PromoteRequest{}); !pkgerrors.Is(err, pkgerrors.ErrBadRequest) {
+		t.Fatalf("err = %v", err)
+	}
+
+	// 主区域可达时先最终同步
+	replicator.down = false
+	promotion, err := svc.Promote(ctx, "usr1", PromoteRequest{Reason: " 演练 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if promotion.Forced || promotion.Reason != "演练" || promotion.RPOSeconds == nil || !replicator.prepared {
+		t.Errorf("提升记录 = %+v", promotion)
+	}
+	if cp := repo.checkpoints[disasterrecovery.StreamEventLog]; cp == nil || cp.Position != "1" {
+		t.Errorf("最终同步未复制变更事件: %+v", cp)
+	}
+
+	// 提升后不再复制，也不能重复提升
+	replicator.events = append(replicator.events, now)
+	svc.run(ctx, disasterrecovery.StreamEventLog)
+	if cp := repo.checkpoints[disasterrecovery.StreamEventLog]; cp.Position != "1" {
+		t.Errorf("提升后仍在复制: %+v", cp)
+	}
+	if _, err := svc.Promote(ctx, "usr1", PromoteRequest{Force: true}); !pkgerrors.Is(err, pkgerrors.ErrConflict) {
+		t.Errorf("重复提升 err = %v", err)
+	}
+	runbook, err := svc.Runbook(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runbook.Report.Role != disasterrecovery.RolePromoted || runbook.Steps[3].Status != disasterrecovery.StepDone {
+		t.Errorf("手册 = %+v", runbook.Steps)
+	}
+}
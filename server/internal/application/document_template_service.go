@@ -0,0 +1,760 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/doctemplate"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/docmerge"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/pdf"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+var documentLog = logger.Named("document")
+
+// documentRecordAccess 读取记录并计算展示文本、写回附件字段（由 RecordService 实现）
+type documentRecordAccess interface {
+	printRecordReader
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// CreateDocumentTemplateRequest 创建文档模板请求
+// 模板文件先通过附件上传接口上传到本表（.docx 或 .html），通过安全扫描后以附件ID创建模板
+type CreateDocumentTemplateRequest struct {
+	Name          string            `json:"name" binding:"required"`
+	AttachmentID  string            `json:"attachmentId" binding:"required"`
+	Output        string            `json:"output"` // docx | html | pdf，默认与模板格式相同
+	TargetFieldID string            `json:"targetFieldId" binding:"required"`
+	NameFieldID   string            `json:"nameFieldId"`
+	Mappings      map[string]string `json:"mappings"` // 合并域 → 字段ID；未提供的合并域按字段名（不区分大小写）自动匹配
+}
+
+// UpdateDocumentTemplateRequest 更新文档模板请求（未提供的项保持不变）
+type UpdateDocumentTemplateRequest struct {
+	Name          *string           `json:"name"`
+	AttachmentID  string            `json:"attachmentId"` // 替换模板文件，同名合并域保留原有映射
+	Output        *string           `json:"output"`
+	TargetFieldID *string           `json:"targetFieldId"`
+	NameFieldID   *string           `json:"nameFieldId"`
+	Mappings      map[string]string `json:"mappings"` // 提供时整体替换映射
+}
+
+// GenerateDocumentsRequest 生成文档请求：提供 recordIds 时为这些记录生成，否则为视图中的记录生成
+type GenerateDocumentsRequest struct {
+	RecordIDs []string `json:"recordIds"`
+	ViewID    string   `json:"viewId"`
+}
+
+// DocumentTemplateService 文档模板服务 ✨
+// 表的管理者上传 DOCX 或 HTML 模板，把模板中的合并域（{{字段名}} 或 Word 的 MERGEFIELD）映射到字段；
+// 为记录或视图中的记录批量生成文档时，后台以任务创建者的身份逐条读取记录（脱敏策略与时区照常生效），
+// 将字段的展示文本填入模板，生成的文件作为附件写入模板指定的附件字段（同名文件被替换）。
+// HTML 模板可以生成 HTML 或按正文排版的 PDF；自动化中的"生成文档"节点通过 GenerateNow 同步生成
+type DocumentTemplateService struct {
+	repo              doctemplate.Repository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         fieldRepo.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           documentRecordAccess
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	attachmentRepo    attachment.Repository
+	storage           attachment.Storage
+	cfg               config.DocumentConfig
+	uploadGuard       func(ctx context.Context, tableID string, size int64) error
+	now               func() time.Time
+}
+
+// NewDocumentTemplateService 创建文档模板服务
+func NewDocumentTemplateService(
+	repo doctemplate.Repository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	recordRepo recordRepo.RecordRepository,
+	records documentRecordAccess,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	attachmentRepo attachment.Repository,
+	storage attachment.Storage,
+	cfg config.DocumentConfig,
+) *DocumentTemplateService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 10 * time.Minute
+	}
+	if cfg.MaxTemplateSize <= 0 {
+		cfg.MaxTemplateSize = 10 << 20
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 200
+	}
+	if cfg.History <= 0 {
+		cfg.History = 20
+	}
+	return &DocumentTemplateService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepo,
+		records:           records,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		attachmentRepo:    attachmentRepo,
+		storage:           storage,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// SetUploadGuard 设置写入生成文件前的检查（如套餐附件空间限额）
+func (s *DocumentTemplateService) SetUploadGuard(guard func(ctx context.Context, tableID string, size int64) error) {
+	s.uploadGuard = guard
+}
+
+// ListTemplates 列出表的文档模板
+func (s *DocumentTemplateService) ListTemplates(ctx context.Context, userID, tableID string) ([]*doctemplate.Template, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	templates, err := s.repo.ListTemplates(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文档模板失败")
+	}
+	return templates, nil
+}
+
+// GetTemplate 获取文档模板
+func (s *DocumentTemplateService) GetTemplate(ctx context.Context, userID, templateID string) (*doctemplate.Template, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessTable(ctx, userID, template.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	return template, nil
+}
+
+// CreateTemplate 以已上传的附件创建文档模板（需要表结构管理权限）
+func (s *DocumentTemplateService) CreateTemplate(ctx context.Context, userID, tableID string, req CreateDocumentTemplateRequest) (*doctemplate.Template, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的文档模板")
+	}
+	item, format, data, err := s.templateFile(ctx, tableID, req.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+
+	template := doctemplate.NewTemplate(tableID, req.Name, format, userID, s.now())
+	if req.Output != "" {
+		template.Output = strings.ToLower(req.Output)
+	}
+	template.TargetFieldID = req.TargetFieldID
+	template.NameFieldID = req.NameFieldID
+	if err := s.setFile(template, item, data, fields, req.Mappings); err != nil {
+		return nil, err
+	}
+	if err := validateDocumentTemplate(template, fields); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListTemplates(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文档模板失败")
+	}
+	if len(existing) >= doctemplate.MaxTemplatesPerTable {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("每张表最多 %d 个文档模板", doctemplate.MaxTemplatesPerTable))
+	}
+	if err := s.storage.Upload(ctx, template.FilePath(), bytes.NewReader(data), int64(len(data)), item.MimeType); err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("保存模板文件失败")
+	}
+	if err := s.repo.SaveTemplate(ctx, template); err != nil {
+		return nil, pkgerrors.Database(err, "保存文档模板失败")
+	}
+	return template, nil
+}
+
+// UpdateTemplate 更新文档模板（需要表结构管理权限）
+func (s *DocumentTemplateService) UpdateTemplate(ctx context.Context, userID, templateID string, req UpdateDocumentTemplateRequest) (*doctemplate.Template, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, template.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的文档模板")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, template.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.TargetFieldID != nil {
+		template.TargetFieldID = *req.TargetFieldID
+	}
+	if req.NameFieldID != nil {
+		template.NameFieldID = *req.NameFieldID
+	}
+	if req.Mappings != nil {
+		template.Mappings = req.Mappings
+	}
+	var data []byte
+	if req.AttachmentID != "" {
+		item, format, content, err := s.templateFile(ctx, template.TableID, req.AttachmentID)
+		if err != nil {
+			return nil, err
+		}
+		if format != template.Format {
+			template.Format, template.Output = format, string(format)
+		}
+		if err := s.setFile(template, item, content, fields, template.Mappings); err != nil {
+			return nil, err
+		}
+		data = content
+	}
+	if req.Output != nil {
+		template.Output = strings.ToLower(*req.Output)
+	}
+	template.UpdatedBy = userID
+	template.UpdatedAt = s.now()
+	if err := validateDocumentTemplate(template, fields); err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		if err := s.storage.Upload(ctx, template.FilePath(), bytes.NewReader(data), int64(len(data)), ""); err != nil {
+			return nil, pkgerrors.ErrInternalServer.WithDetails("保存模板文件失败")
+		}
+	}
+	if err := s.repo.SaveTemplate(ctx, template); err != nil {
+		return nil, pkgerrors.Database(err, "保存文档模板失败")
+	}
+	return template, nil
+}
+
+// DeleteTemplate 删除文档模板及其生成任务（已生成的附件保留）
+func (s *DocumentTemplateService) DeleteTemplate(ctx context.Context, userID, templateID string) error {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, template.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的文档模板")
+	}
+	if err := s.repo.DeleteTemplate(ctx, template.ID); err != nil {
+		return pkgerrors.Database(err, "删除文档模板失败")
+	}
+	if err := s.storage.Delete(ctx, template.FilePath()); err != nil {
+		documentLog.Warn(ctx, "删除模板文件失败", logger.String("template_id", template.ID), logger.ErrorField(err))
+	}
+	return nil
+}
+
+// Generate 创建生成任务（需要记录编辑权限，由后台在下一个轮询周期执行）
+func (s *DocumentTemplateService) Generate(ctx context.Context, userID, templateID string, req GenerateDocumentsRequest) (*doctemplate.Job, error) {
+	job, _, err := s.newJob(ctx, userID, templateID, req, doctemplate.SourceAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存文档生成任务失败")
+	}
+	return job, nil
+}
+
+// GenerateNow 立即为一条记录生成文档并返回任务（供自动化动作使用，失败的任务同样会保存）
+func (s *DocumentTemplateService) GenerateNow(ctx context.Context, userID, templateID, recordID string) (*doctemplate.Job, error) {
+	job, template, err := s.newJob(ctx, userID, templateID, GenerateDocumentsRequest{RecordIDs: []string{recordID}}, doctemplate.SourceAutomation)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = doctemplate.StatusRunning
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存文档生成任务失败")
+	}
+	s.runJob(ctx, job, template)
+	if job.Status != doctemplate.StatusCompleted {
+		return nil, fmt.Errorf("生成文档失败: %s", job.Error)
+	}
+	return job, nil
+}
+
+// ListJobs 列出模板最近的生成任务
+func (s *DocumentTemplateService) ListJobs(ctx context.Context, userID, templateID string) ([]*doctemplate.Job, error) {
+	template, err := s.GetTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := s.repo.ListJobs(ctx, template.ID, s.cfg.History)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文档生成任务失败")
+	}
+	return jobs, nil
+}
+
+// GetJob 获取生成任务
+func (s *DocumentTemplateService) GetJob(ctx context.Context, userID, jobID string) (*doctemplate.Job, error) {
+	job, err := s.repo.FindJob(ctx, jobID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文档生成任务失败")
+	}
+	if job == nil || !s.permissionService.CanAccessTable(ctx, userID, job.TableID) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("文档生成任务不存在")
+	}
+	return job, nil
+}
+
+// newJob 按请求创建任务并校验权限，记录请求用户的时区与语言
+func (s *DocumentTemplateService) newJob(ctx context.Context, userID, templateID string, req GenerateDocumentsRequest, source string) (*doctemplate.Job, *doctemplate.Template, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+	job := doctemplate.NewJob(template, req.ViewID, req.RecordIDs, userID, s.now())
+	job.Source = source
+	if locale, ok := userlocale.FromContext(ctx); ok {
+		job.TimeZone, job.Locale = locale.TimeZone, locale.Language
+	}
+	if err := job.Validate(s.cfg.MaxRecords); err != nil {
+		return nil, nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.checkAccess(ctx, job); err != nil {
+		return nil, nil, err
+	}
+	return job, template, nil
+}
+
+// checkAccess 校验创建者能否编辑表中的记录并读取视图（创建与执行时各校验一次）
+func (s *DocumentTemplateService) checkAccess(ctx context.Context, job *doctemplate.Job) error {
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, job.CreatedBy, job.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有编辑该表记录的权限")
+	}
+	if job.ViewID != "" && len(job.RecordIDs) == 0 {
+		view, err := s.viewRepo.FindByID(ctx, job.ViewID)
+		if err != nil {
+			return pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.IsDeleted() || view.TableID() != job.TableID {
+			return pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+		if !s.permissionService.CanReadView(ctx, job.CreatedBy, job.ViewID) {
+			return pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+		}
+	}
+	return nil
+}
+
+// Start 启动后台生成
+func (s *DocumentTemplateService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runPending(ctx)
+		}
+	}()
+}
+
+// runPending 领取并执行待执行或已中断的任务
+func (s *DocumentTemplateService) runPending(ctx context.Context) {
+	now := s.now()
+	jobs, err := s.repo.ClaimJobs(ctx, now, now.Add(-s.cfg.LeaseDuration), 4)
+	if err != nil {
+		documentLog.Warn(ctx, "领取文档生成任务失败", logger.ErrorField(err))
+		return
+	}
+	for _, job := range jobs {
+		template, err := s.template(ctx, job.TemplateID)
+		if err != nil {
+			job.Finish(nil, err, s.now())
+			s.saveJob(ctx, job)
+			continue
+		}
+		s.runJob(ctx, job, template)
+	}
+}
+
+// runJob 以创建者的身份逐条生成文档并保存结果
+func (s *DocumentTemplateService) runJob(ctx context.Context, job *doctemplate.Job, template *doctemplate.Template) {
+	ctx = authctx.WithUser(ctx, job.CreatedBy)
+	ctx = userlocale.WithLocale(ctx, userlocale.Locale{TimeZone: job.TimeZone, Language: job.Locale})
+
+	results, err := s.generate(ctx, job, template)
+	job.Finish(results, err, s.now())
+	s.saveJob(ctx, job)
+	if err != nil {
+		documentLog.Warn(ctx, "生成文档失败",
+			logger.String("job_id", job.ID),
+			logger.String("template_id", template.ID),
+			logger.ErrorField(err))
+	}
+}
+
+func (s *DocumentTemplateService) saveJob(ctx context.Context, job *doctemplate.Job) {
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		documentLog.Warn(ctx, "保存文档生成任务失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+}
+
+// generate 校验权限与模板后逐条生成，单条记录失败不影响其他记录
+func (s *DocumentTemplateService) generate(ctx context.Context, job *doctemplate.Job, template *doctemplate.Template) ([]doctemplate.Result, error) {
+	if err := s.checkAccess(ctx, job); err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, template.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	if err := validateDocumentTemplate(template, fields); err != nil {
+		return nil, err
+	}
+	data, err := s.readFile(ctx, template.FilePath())
+	if err != nil {
+		return nil, fmt.Errorf("读取模板文件失败: %w", err)
+	}
+
+	recordIDs := job.RecordIDs
+	if len(recordIDs) == 0 {
+		if recordIDs, err = s.viewRecordIDs(ctx, job, fields); err != nil {
+			return nil, err
+		}
+		if len(recordIDs) > s.cfg.MaxRecords {
+			recordIDs = recordIDs[:s.cfg.MaxRecords]
+			job.Truncated = true
+		}
+	}
+
+	// 映射到创建者无权读取的字段的合并域按空文本处理
+	byID := fieldIndex(fields)
+	readable := make(map[string]*fieldEntity.Field, len(template.Mappings))
+	for name, fieldID := range template.Mappings {
+		if field := byID[fieldID]; field != nil && s.permissionService.CanReadField(ctx, job.CreatedBy, fieldID) {
+			readable[name] = field
+		}
+	}
+	nameField := byID[template.NameFieldID]
+	if nameField == nil {
+		for _, field := range fields {
+			if field.IsPrimary() {
+				nameField = field
+			}
+		}
+	}
+
+	results := make([]doctemplate.Result, 0, len(recordIDs))
+	for _, recordID := range recordIDs {
+		result := doctemplate.Result{RecordID: recordID}
+		if err := s.generateRecord(ctx, job, template, data, readable, nameField, &result); err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// generateRecord 为一条记录生成文档，保存为附件并写入附件字段（替换同名文件）
+func (s *DocumentTemplateService) generateRecord(ctx context.Context, job *doctemplate.Job, template *doctemplate.Template, data []byte, fields map[string]*fieldEntity.Field, nameField *fieldEntity.Field, result *doctemplate.Result) error {
+	record, err := s.records.GetRecord(ctx, job.TableID, result.RecordID)
+	if err != nil {
+		return err
+	}
+	if err := s.records.FormatRecords(ctx, job.TableID, []*dto.RecordResponse{record}, RecordFormatOptions{}); err != nil {
+		return err
+	}
+
+	title := ""
+	if nameField != nil {
+		title = documentValue(nameField, record)
+	}
+	fileName := template.OutputFileName(title, record.ID)
+	content, err := renderDocument(template, data, func(name string) (string, bool) {
+		if field := fields[name]; field != nil {
+			return documentValue(field, record), true
+		}
+		return "", true
+	}, title, s.now())
+	if err != nil {
+		return err
+	}
+
+	item, err := s.saveAttachment(ctx, template, fileName, content)
+	if err != nil {
+		return err
+	}
+	value, err := s.attachmentValue(ctx, job.TableID, record.ID, template.TargetFieldID, item)
+	if err != nil {
+		return err
+	}
+	if _, err := s.records.UpdateRecord(ctx, job.TableID, record.ID, dto.UpdateRecordRequest{
+		Data: map[string]interface{}{template.TargetFieldID: value},
+	}, job.CreatedBy); err != nil {
+		return err
+	}
+	result.AttachmentID, result.FileName, result.Size = item.ID, item.Name, item.Size
+	return nil
+}
+
+// saveAttachment 上传生成的文件并登记附件（服务端生成的文件不经过隔离扫描）
+func (s *DocumentTemplateService) saveAttachment(ctx context.Context, template *doctemplate.Template, fileName string, content []byte) (*attachment.AttachmentItem, error) {
+	size := int64(len(content))
+	if s.uploadGuard != nil {
+		if err := s.uploadGuard(ctx, template.TableID, size); err != nil {
+			return nil, err
+		}
+	}
+	token := utils.GenerateNanoID(20)
+	ext := path.Ext(fileName)
+	filePath := fmt.Sprintf("attachments/%s/%s/%s/%s_%s%s", template.TableID, template.TargetFieldID,
+		s.now().Format("2006/01/02"), strings.TrimSuffix(fileName, ext), token[:8], ext)
+	if err := s.storage.Upload(ctx, filePath, bytes.NewReader(content), size, template.ContentType()); err != nil {
+		return nil, fmt.Errorf("上传生成的文件失败: %w", err)
+	}
+
+	item := attachment.NewAttachmentItem(fileName, filePath, token, template.ContentType(), size)
+	item.TableID = template.TableID
+	item.SetScanResult(attachment.ScanStatusClean, "")
+	if err := s.attachmentRepo.CreateAttachment(ctx, item); err != nil {
+		return nil, pkgerrors.Database(err, "保存附件失败")
+	}
+	return item, nil
+}
+
+// attachmentValue 附件字段的新值：读取未脱敏的原值，去掉同名文件后追加新附件
+func (s *DocumentTemplateService) attachmentValue(ctx context.Context, tableID, recordID, fieldID string, item *attachment.AttachmentItem) ([]interface{}, error) {
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, []recordVO.RecordID{recordVO.NewRecordID(recordID)})
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	var current interface{}
+	if len(entities) > 0 {
+		current = entities[0].Data().ToMap()[fieldID]
+	}
+	if text, ok := current.(string); ok {
+		_ = json.Unmarshal([]byte(text), &current)
+	}
+
+	var added map[string]interface{}
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &added); err != nil {
+		return nil, err
+	}
+
+	items, _ := current.([]interface{})
+	value := make([]interface{}, 0, len(items)+1)
+	for _, existing := range items {
+		if obj, ok := existing.(map[string]interface{}); ok && obj["name"] == item.Name {
+			continue
+		}
+		value = append(value, existing)
+	}
+	return append(value, added), nil
+}
+
+// viewRecordIDs 按视图的过滤与排序查询记录ID（多取一条用于判断是否超过上限）
+func (s *DocumentTemplateService) viewRecordIDs(ctx context.Context, job *doctemplate.Job, fields []*fieldEntity.Field) ([]string, error) {
+	view, err := s.viewRepo.FindByID(ctx, job.ViewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	table, err := s.tableRepo.GetByID(ctx, job.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+
+	byID := fieldIndex(fields)
+	condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+	if err != nil {
+		return nil, err
+	}
+	query := s.dataDB(ctx, table.BaseID()).WithContext(ctx).Table(s.dbProvider.GenerateTableName(table.BaseID(), job.TableID))
+	if condition != nil {
+		query = query.Where(condition)
+	}
+	for _, order := range embedSortColumns(view.Sort(), byID) {
+		query = query.Order(order)
+	}
+	var ids []string
+	if err := query.Limit(s.cfg.MaxRecords+1).Pluck("__id", &ids).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	return ids, nil
+}
+
+// templateFile 读取作为模板上传的附件：须属于本表、已通过安全扫描、格式受支持且不超过大小上限
+func (s *DocumentTemplateService) templateFile(ctx context.Context, tableID, attachmentID string) (*attachment.AttachmentItem, doctemplate.Format, []byte, error) {
+	item, err := s.attachmentRepo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil || item == nil || item.TableID != tableID {
+		return nil, "", nil, pkgerrors.ErrNotFound.WithDetails("模板文件不存在")
+	}
+	if item.ScanStatus == attachment.ScanStatusPending {
+		return nil, "", nil, pkgerrors.ErrValidationFailed.WithDetails("模板文件尚未完成安全扫描，请稍后重试")
+	}
+	if !item.Downloadable() {
+		return nil, "", nil, pkgerrors.ErrValidationFailed.WithDetails("模板文件未通过安全扫描")
+	}
+	format, ok := doctemplate.FormatOf(item.Name)
+	if !ok {
+		return nil, "", nil, pkgerrors.ErrValidationFailed.WithDetails("模板文件必须是 .docx 或 .html 文件")
+	}
+	if item.Size > s.cfg.MaxTemplateSize {
+		return nil, "", nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("模板文件不能超过 %d MB", s.cfg.MaxTemplateSize>>20))
+	}
+	data, err := s.readFile(ctx, item.StoragePath())
+	if err != nil {
+		return nil, "", nil, pkgerrors.ErrInternalServer.WithDetails("读取模板文件失败")
+	}
+	return item, format, data, nil
+}
+
+// setFile 解析模板文件的合并域：保留 mappings 中仍存在的映射，其余合并域按字段名自动匹配
+func (s *DocumentTemplateService) setFile(template *doctemplate.Template, item *attachment.AttachmentItem, data []byte, fields []*fieldEntity.Field, mappings map[string]string) error {
+	names, err := docmerge.Fields(docmerge.Format(template.Format), data)
+	if err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	template.FileName = item.Name
+	template.Size = int64(len(data))
+	template.MergeFields = names
+	template.Mappings = map[string]string{}
+	for _, name := range names {
+		if fieldID, ok := mappings[name]; ok {
+			if fieldID != "" {
+				template.Mappings[name] = fieldID
+			}
+			continue
+		}
+		for _, field := range fields {
+			if strings.EqualFold(strings.TrimSpace(field.Name().String()), name) {
+				template.Mappings[name] = field.ID().String()
+				break
+			}
+		}
+	}
+	for name := range mappings {
+		if _, ok := template.Mappings[name]; !ok && mappings[name] != "" {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("模板中没有合并域 %q", name))
+		}
+	}
+	return nil
+}
+
+func (s *DocumentTemplateService) readFile(ctx context.Context, filePath string) ([]byte, error) {
+	reader, err := s.storage.Download(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, s.cfg.MaxTemplateSize+1))
+}
+
+func (s *DocumentTemplateService) template(ctx context.Context, templateID string) (*doctemplate.Template, error) {
+	template, err := s.repo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文档模板失败")
+	}
+	if template == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("文档模板不存在")
+	}
+	return template, nil
+}
+
+// validateDocumentTemplate 校验模板设置与表结构：目标字段为附件字段，映射与命名字段存在
+func validateDocumentTemplate(template *doctemplate.Template, fields []*fieldEntity.Field) error {
+	if err := template.Validate(); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	byID := fieldIndex(fields)
+	target := byID[template.TargetFieldID]
+	if target == nil {
+		return pkgerrors.ErrValidationFailed.WithDetails("写入生成文件的字段不存在")
+	}
+	if target.Type().String() != fieldVO.TypeAttachment {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是附件字段", target.Name().String()))
+	}
+	if template.NameFieldID != "" && byID[template.NameFieldID] == nil {
+		return pkgerrors.ErrValidationFailed.WithDetails("用于命名文件的字段不存在")
+	}
+	for name, fieldID := range template.Mappings {
+		if byID[fieldID] == nil {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("合并域 %q 映射的字段不存在", name))
+		}
+	}
+	return nil
+}
+
+// renderDocument 替换合并域生成文件；HTML 模板输出 PDF 时按合并后的正文文本排版
+func renderDocument(template *doctemplate.Template, data []byte, resolve docmerge.Resolver, title string, now time.Time) ([]byte, error) {
+	merged, err := docmerge.Merge(docmerge.Format(template.Format), data, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if template.Output != doctemplate.OutputPDF {
+		return merged, nil
+	}
+	doc := pdf.New(pdf.A4)
+	doc.SetInfo(title, now)
+	pdf.RenderText(doc, richtext.HTMLToText(string(merged)))
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("write pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// documentValue 合并域的值：富文本转为纯文本，其余与打印相同（长文本保留换行，其他字段为展示文本）
+func documentValue(field *fieldEntity.Field, record *dto.RecordResponse) string {
+	if field.Type().String() == fieldVO.TypeRichText {
+		if text, ok := record.Data[field.ID().String()].(string); ok {
+			return richtext.HTMLToText(text)
+		}
+	}
+	return printValue(field, record)
+}
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/doctemplate"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+func TestDocumentTemplateSetFile(t *testing.T) {
+	customer := newTemplateTestField(t, "Customer", fieldVO.TypeSingleLineText)
+	amount := newTemplateTestField(t, "Amount", fieldVO.TypeNumber)
+	files := newTemplateTestField(t, "Files", fieldVO.TypeAttachment)
+	fields := []*fieldEntity.Field{customer, amount, files}
+
+	template := doctemplate.NewTemplate("tbl1", "报价单", doctemplate.FormatHTML, "usr1", time.Now())
+	template.TargetFieldID = files.ID().String()
+	data := []byte("<p>{{ customer }}：{{总额}}，{{备注}}</p>")
+	item := &attachment.AttachmentItem{Name: "quote.html"}
+
+	s := &DocumentTemplateService{}
+	if err := s.setFile(template, item, data, fields, map[string]string{"总额": amount.ID().String()}); err != nil {
+		t.Fatal(err)
+	}
+	// 字段名不区分大小写自动匹配，显式映射优先，其余合并域保持未映射
+	if template.Mappings["customer"] != customer.ID().String() || template.Mappings["总额"] != amount.ID().String() {
+		t.Errorf("映射错误: %v", template.Mappings)
+	}
+	if unmapped := template.Unmapped(); len(unmapped) != 1 || unmapped[0] != "备注" {
+		t.Errorf("未映射的合并域错误: %v", unmapped)
+	}
+	if err := validateDocumentTemplate(template, fields); err != nil {
+		t.Errorf("模板应通过校验: %v", err)
+	}
+
+	if err := s.setFile(template, item, data, fields, map[string]string{"不存在": amount.ID().String()}); err == nil {
+		t.Error("映射模板中不存在的合并域应报错")
+	}
+
+	template.TargetFieldID = customer.ID().String()
+	if err := validateDocumentTemplate(template, fields); err == nil {
+		t.Error("目标字段不是附件字段应报错")
+	}
+}
+
+func TestRenderDocument(t *testing.T) {
+	body := newTemplateTestField(t, "Body", fieldVO.TypeRichText)
+	record := &dto.RecordResponse{ID: "rec1", Data: map[string]interface{}{body.ID().String(): "<p>第一段</p><ul><li>甲</li></ul>"}}
+	if got := documentValue(body, record); got != "第一段\n\n- 甲" {
+		t.Errorf("富文本应转为纯文本，得到 %q", got)
+	}
+
+	template := doctemplate.NewTemplate("tbl1", "信函", doctemplate.FormatHTML, "usr1", time.Now())
+	resolve := func(name string) (string, bool) { return "A & B", true }
+	merged, err := renderDocument(template, []byte("<p>{{客户}}</p>"), resolve, "信函", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(merged) != "<p>A &amp; B</p>" {
+		t.Errorf("HTML 合并结果错误: %s", merged)
+	}
+
+	template.Output = doctemplate.OutputPDF
+	pdfData, err := renderDocument(template, []byte("<p>{{客户}}</p>"), resolve, "信函", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdfData, []byte("%PDF-")) {
+		t.Errorf("应生成 PDF，得到 %q", pdfData[:min(len(pdfData), 16)])
+	}
+}
+
+func TestDocumentNodeRequiresTemplate(t *testing.T) {
+	raw := `{"type":"generateDocument","templateId":"dtp1"}`
+	action, ok := parseDocumentNode(&models.WorkflowNode{Action: &raw})
+	if !ok || action.TemplateID != "dtp1" {
+		t.Fatalf("解析生成文档节点失败: %+v", action)
+	}
+	missing := `{"type":"generateDocument"}`
+	if _, ok := parseDocumentNode(&models.WorkflowNode{Action: &missing}); ok {
+		t.Error("未指定模板的节点不应执行")
+	}
+	resp := (&ScriptActionService{}).executeDocument(context.Background(), "usr1", action, map[string]interface{}{})
+	if resp.Status != "failed" {
+		t.Errorf("缺少记录时应失败: %+v", resp)
+	}
+}
@@ -15,6 +15,8 @@ type CreateFieldRequest struct {
 	Options  map[string]interface{} `json:"options"`
 	Required bool                   `json:"required"`
 	Unique   bool                   `json:"unique"`
+	// API 键（外部 API 使用的稳定标识），为空时由名称生成
+	APIKey string `json:"apiKey"`
     // 顶层默认值，兼容 SDK 传参
     DefaultValue interface{}        `json:"defaultValue"`
 }
@@ -23,18 +25,28 @@ type CreateFieldRequest struct {
 type UpdateFieldRequest struct {
 	Name        *string                `json:"name"`
 	Description *string                `json:"description"`
+	// API 键，传空字符串时按当前名称重新生成
+	APIKey *string `json:"apiKey"`
 	Options     map[string]interface{} `json:"options"`
 	Required    *bool                  `json:"required"`
 	Unique      *bool                  `json:"unique"`
+	// 目标字段类型（物理列类型变化时由后台转换，成功后字段类型才会更新）
+	Type *string `json:"type"`
 	// 顶层默认值，兼容 SDK 传参
 	DefaultValue interface{} `json:"defaultValue"`
 }
 
+// ReorderFieldsRequest 批量调整字段顺序请求（fieldIds 须包含表中全部字段，按新顺序排列）
+type ReorderFieldsRequest struct {
+	FieldIDs []string `json:"fieldIds" binding:"required"`
+}
+
 // FieldResponse 字段响应
 type FieldResponse struct {
 	ID          string                 `json:"id"`
 	TableID     string                 `json:"tableId"`
 	Name        string                 `json:"name"`
+	APIKey      string                 `json:"apiKey"`
 	Type        string                 `json:"type"`
 	Options     map[string]interface{} `json:"options"`
 	Required    bool                   `json:"required"`
@@ -50,6 +62,15 @@ type FieldListResponse struct {
 	Fields []*FieldResponse `json:"fields"`
 }
 
+// FieldDeltaResponse 字段增量响应：返回 since 版本之后新建或修改的字段（当前定义）与已删除的字段ID
+// Reset 为 true 时版本已超出变更保留期，Fields 为全部字段，客户端应整体替换本地字段列表
+type FieldDeltaResponse struct {
+	Version int64            `json:"version"`
+	Reset   bool             `json:"reset"`
+	Fields  []*FieldResponse `json:"fields"`
+	Deleted []string         `json:"deleted"`
+}
+
 // FromFieldEntity 从Domain实体转换为DTO
 func FromFieldEntity(field *fieldEntity.Field) *FieldResponse {
 	if field == nil {
@@ -65,6 +86,7 @@ func FromFieldEntity(field *fieldEntity.Field) *FieldResponse {
 		ID:          field.ID().String(),
 		TableID:     field.TableID(),
 		Name:        field.Name().String(),
+		APIKey:      field.APIKey().String(),
 		Type:        field.Type().String(),
 		Options:     fieldOptionsToMap(field.Options()),
 		Required:    field.IsRequired(),
@@ -104,6 +126,17 @@ func fieldOptionsToMap(options *fieldVO.FieldOptions) map[string]interface{} {
 		}
 	}
 
+	if options.Encrypted {
+		result["encrypted"] = true
+		result["revealInLogs"] = options.RevealInLogs
+		result["revealInWebhooks"] = options.RevealInWebhooks
+	}
+
+	// ✨ 自定义字段类型的选项按原样展开
+	for key, value := range options.Custom {
+		result[key] = value
+	}
+
 	return result
 }
 
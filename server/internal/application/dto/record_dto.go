@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
 	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 )
 
@@ -77,6 +78,54 @@ type BatchDeleteRecordResponse struct {
 	Errors       []string `json:"errors,omitempty"`
 }
 
+// 混合批量变更操作类型
+const (
+	RecordMutationCreate = "create"
+	RecordMutationUpdate = "update"
+	RecordMutationDelete = "delete"
+)
+
+// RecordMutationBatchRequest 混合批量变更请求（创建/更新/删除可混合，逐项返回结果）
+type RecordMutationBatchRequest struct {
+	Operations []RecordMutation `json:"operations" binding:"required,min=1"`
+	Typecast   bool             `json:"typecast,omitempty"` // 创建/更新时自动转换字段值类型
+}
+
+// RecordMutation 单个变更操作
+type RecordMutation struct {
+	Op      string                 `json:"op"`                // create / update / delete
+	ID      string                 `json:"id,omitempty"`      // update / delete 必填
+	Fields  map[string]interface{} `json:"fields,omitempty"`  // create / update 的字段值
+	Version *int                   `json:"version,omitempty"` // 可选的期望版本（乐观锁）
+}
+
+// RecordMutationResult 单个操作的执行结果（Index 对应请求中的位置）
+type RecordMutationResult struct {
+	Index  int                  `json:"index"`
+	Op     string               `json:"op"`
+	ID     string               `json:"id,omitempty"`
+	Status string               `json:"status"` // success / failed
+	Record *RecordResponse      `json:"record,omitempty"`
+	Error  *RecordMutationError `json:"error,omitempty"`
+}
+
+// RecordMutationError 操作失败原因
+type RecordMutationError struct {
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// RecordMutationBatchResponse 混合批量变更响应
+type RecordMutationBatchResponse struct {
+	Results      []*RecordMutationResult `json:"results"`
+	SuccessCount int                     `json:"successCount"`
+	FailedCount  int                     `json:"failedCount"`
+	Created      int                     `json:"created"`
+	Updated      int                     `json:"updated"`
+	Deleted      int                     `json:"deleted"`
+}
+
 // ListRecordFilter 记录列表过滤器
 type ListRecordFilter struct {
 	TableID   *string                `json:"tableId"`
@@ -97,6 +146,23 @@ type RecordResponse struct {
 	CreatedAt time.Time              `json:"createdAt"`
 	UpdatedAt time.Time              `json:"updatedAt"`
 	Version   int                    `json:"version"`
+	Expanded  map[string]interface{} `json:"expanded,omitempty"`  // 按字段ID展开的关联记录/查找值/用户（请求 expand 时返回）
+	Archived  bool                   `json:"archived,omitempty"`  // 已归档（includeArchived 查询时返回的归档记录）
+	Formatted map[string]string      `json:"formatted,omitempty"` // 按字段的展示文本（请求 includeFormatted 时返回）
+}
+
+// ExpandedUser 展开的协作者
+type ExpandedUser struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Email  string  `json:"email"`
+	Avatar *string `json:"avatar,omitempty"`
+}
+
+// ExpandedLookupValue 展开的查找值（来源记录 + 该记录上的被查找字段值）
+type ExpandedLookupValue struct {
+	RecordID string      `json:"recordId"`
+	Value    interface{} `json:"value"`
 }
 
 // RecordListResponse 记录列表响应
@@ -105,6 +171,46 @@ type RecordListResponse struct {
 	Pagination *PaginationResponse `json:"pagination"`
 }
 
+// RecordSnapshotRequest 按时间点读取表的请求
+// 首页不传 asOf 时以当前时间为快照点，后续页传入首页返回的 asOf 与 nextCursor
+type RecordSnapshotRequest struct {
+	AsOf   *time.Time
+	Cursor int64 // 上一页返回的 nextCursor
+	Limit  int
+}
+
+// RecordSnapshotResponse 表快照分页响应
+type RecordSnapshotResponse struct {
+	Records    []*RecordResponse `json:"records"`
+	Total      int64             `json:"total"`                // 快照时刻的记录总数
+	AsOf       time.Time         `json:"asOf"`                 // 快照时间点，后续页需原样传回
+	NextCursor *int64            `json:"nextCursor,omitempty"` // 下一页游标，为空表示已读完
+}
+
+// CellHistoryRequest 单元格修改历史请求
+type CellHistoryRequest struct {
+	Cursor int64 // 上一页返回的 nextCursor（只返回更早的版本）
+	Limit  int
+}
+
+// CellHistoryEntry 单元格的一次修改
+type CellHistoryEntry struct {
+	Version   int64             `json:"version"` // 修改后的记录版本
+	OldValue  interface{}       `json:"oldValue"`
+	NewValue  interface{}       `json:"newValue"`
+	Diff      []richtext.DiffOp `json:"diff,omitempty"` // 富文本字段的逐词差异
+	ChangedBy string            `json:"changedBy"`
+	ChangedAt time.Time         `json:"changedAt"`
+}
+
+// CellHistoryResponse 单元格修改历史分页响应（按版本倒序）
+type CellHistoryResponse struct {
+	RecordID   string              `json:"recordId"`
+	FieldID    string              `json:"fieldId"`
+	Entries    []*CellHistoryEntry `json:"entries"`
+	NextCursor *int64              `json:"nextCursor,omitempty"` // 下一页游标，为空表示没有更早的修改
+}
+
 // FromRecordEntity 从Domain实体转换为DTO
 func FromRecordEntity(record *recordEntity.Record) *RecordResponse {
 	if record == nil {
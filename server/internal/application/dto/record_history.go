@@ -1,15 +1,20 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+)
 
 // RecordHistoryResponse 记录历史响应
 type RecordHistoryResponse struct {
-	ID          string      `json:"id"`
-	TableID     string      `json:"table_id"`
-	RecordID    string      `json:"record_id"`
-	FieldID     string      `json:"field_id"`
-	Before      interface{} `json:"before"`
-	After       interface{} `json:"after"`
-	CreatedTime time.Time   `json:"created_time"`
-	CreatedBy   string      `json:"created_by"`
+	ID          string            `json:"id"`
+	TableID     string            `json:"table_id"`
+	RecordID    string            `json:"record_id"`
+	FieldID     string            `json:"field_id"`
+	Before      interface{}       `json:"before"`
+	After       interface{}       `json:"after"`
+	Diff        []richtext.DiffOp `json:"diff,omitempty"` // 富文本字段的逐词差异
+	CreatedTime time.Time         `json:"created_time"`
+	CreatedBy   string            `json:"created_by"`
 }
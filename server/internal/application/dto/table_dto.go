@@ -17,6 +17,7 @@ type ViewConfigDTO struct {
 // FieldConfigDTO 字段配置DTO
 type FieldConfigDTO struct {
 	Name        string                 `json:"name" binding:"required"`
+	APIKey      string                 `json:"apiKey,omitempty"` // 为空时由名称生成
 	Type        string                 `json:"type" binding:"required"`
 	Description string                 `json:"description,omitempty"`
 	Required    bool                   `json:"required"`
@@ -125,6 +125,10 @@ func FromUserEntities(users []*userEntity.User) []*UserResponse {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+
+	// 客户端信息（由处理器填充，用于登录会话记录）
+	ClientIP  string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // RegisterRequest 注册请求
@@ -132,6 +136,10 @@ type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Name     string `json:"name" binding:"required"`
 	Password string `json:"password" binding:"required,min=8"`
+
+	// 客户端信息（由处理器填充，用于登录会话记录）
+	ClientIP  string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
 // LoginResponse 登录响应
@@ -149,7 +157,20 @@ type TokenResponse struct {
 
 // TokenClaims Token声明
 type TokenClaims struct {
-	UserID  string `json:"userId"`
-	Email   string `json:"email"`
-	IsAdmin bool   `json:"isAdmin"`
+	UserID     string     `json:"userId"`
+	Email      string     `json:"email"`
+	IsAdmin    bool       `json:"isAdmin"`
+	SessionID  string     `json:"sessionId,omitempty"`
+	AuthTime   time.Time  `json:"authTime"`
+	ReauthTime *time.Time `json:"reauthTime,omitempty"`
+	// ServiceTokenID 通过服务令牌认证时的令牌ID（登录令牌为空）
+	ServiceTokenID string `json:"serviceTokenId,omitempty"`
+	// ImpersonationID 通过代入令牌认证时的代入会话ID，ImpersonatorID 为实际操作的支持人员
+	ImpersonationID string `json:"impersonationId,omitempty"`
+	ImpersonatorID  string `json:"impersonatorId,omitempty"`
+}
+
+// ReauthRequest 重新认证请求
+type ReauthRequest struct {
+	Password string `json:"password" binding:"required"`
 }
@@ -0,0 +1,641 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/embed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// embedTokenAudience 嵌入令牌的受众（与登录令牌区分）
+const embedTokenAudience = "luckdb-embed"
+
+// EmbedClaims 嵌入令牌声明
+// Subject 为签发者用户ID：令牌代表签发者的授权，每次使用时都会重新校验签发者是否仍有权限
+type EmbedClaims struct {
+	ViewID     string           `json:"vid"`
+	TableID    string           `json:"tid"`
+	SpaceID    string           `json:"sid"`
+	Permission embed.Permission `json:"perm"`
+	Version    int              `json:"ver"` // 签发时空间设置的令牌版本
+	jwt.RegisteredClaims
+}
+
+// EmbedSession 已验证的嵌入请求上下文
+type EmbedSession struct {
+	Claims   *EmbedClaims
+	Settings *embed.Settings
+	view     *viewEntity.View
+	baseID   string
+}
+
+// UpdateEmbedSettingsRequest 更新嵌入设置请求
+type UpdateEmbedSettingsRequest struct {
+	Enabled            bool     `json:"enabled"`
+	AllowedOrigins     []string `json:"allowedOrigins"`
+	MaxTokenTTLMinutes int      `json:"maxTokenTtlMinutes"`
+}
+
+// CreateEmbedTokenRequest 签发嵌入令牌请求
+type CreateEmbedTokenRequest struct {
+	Permission       embed.Permission `json:"permission"`       // 默认 viewer
+	ExpiresInMinutes int              `json:"expiresInMinutes"` // 默认使用配置的有效期
+}
+
+// EmbedTokenResponse 嵌入令牌
+type EmbedTokenResponse struct {
+	Token      string           `json:"token"`
+	ViewID     string           `json:"viewId"`
+	Permission embed.Permission `json:"permission"`
+	ExpiresAt  time.Time        `json:"expiresAt"`
+}
+
+// EmbedViewResponse 嵌入视图元数据（只包含视图中可见的字段）
+type EmbedViewResponse struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Type       string               `json:"type"`
+	TableID    string               `json:"tableId"`
+	Permission embed.Permission     `json:"permission"`
+	Fields     []*dto.FieldResponse `json:"fields"`
+}
+
+// EmbedService 嵌入视图服务 ✨
+//
+// 用户为视图签发带有效期和权限级别的嵌入令牌，第三方页面通过 iframe 携带令牌访问轻量的嵌入接口。
+// 嵌入由工作空间设置控制：未启用时令牌失效，允许的来源同时决定 CORS 与 frame-ancestors；
+// 递增令牌版本可一次性吊销此前签发的全部令牌。
+// 嵌入访问者是匿名的，受脱敏策略保护的字段一律脱敏，视图中隐藏的字段不会返回。
+type EmbedService struct {
+	repo              embed.Repository
+	baseRepo          baseRepo.BaseRepository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	recordRepo        recordRepo.RecordRepository
+	recordService     *RecordService
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	signingKey        []byte
+	viewQueries       *ViewQueryCache
+	cfg               config.EmbedConfig
+}
+
+// NewEmbedService 创建嵌入视图服务
+// 签名密钥由 JWT 密钥派生，嵌入令牌不能当作登录令牌使用
+func NewEmbedService(
+	repo embed.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	viewRepo viewRepo.ViewRepository,
+	recordRepo recordRepo.RecordRepository,
+	recordService *RecordService,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	jwtSecret string,
+	cfg config.EmbedConfig,
+) *EmbedService {
+	if cfg.DefaultTokenTTL <= 0 {
+		cfg.DefaultTokenTTL = 24 * time.Hour
+	}
+	if cfg.MaxPageSize <= 0 {
+		cfg.MaxPageSize = 200
+	}
+	mac := hmac.New(sha256.New, []byte(jwtSecret))
+	mac.Write([]byte(embedTokenAudience))
+	return &EmbedService{
+		repo:              repo,
+		baseRepo:          baseRepo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepo,
+		recordRepo:        recordRepo,
+		recordService:     recordService,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		signingKey:        mac.Sum(nil),
+		cfg:               cfg,
+	}
+}
+
+// ==================== 嵌入设置 ====================
+
+// GetSettings 获取空间嵌入设置（仅空间所有者）
+func (s *EmbedService) GetSettings(ctx context.Context, userID, spaceID string) (*embed.Settings, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	return s.settings(ctx, spaceID)
+}
+
+// UpdateSettings 更新空间嵌入设置（仅空间所有者，令牌版本保持不变）
+func (s *EmbedService) UpdateSettings(ctx context.Context, userID, spaceID string, req UpdateEmbedSettingsRequest) (*embed.Settings, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	current, err := s.settings(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := req.AllowedOrigins
+	if origins == nil {
+		origins = []string{}
+	}
+	settings := &embed.Settings{
+		SpaceID:            spaceID,
+		Enabled:            req.Enabled,
+		AllowedOrigins:     origins,
+		MaxTokenTTLMinutes: req.MaxTokenTTLMinutes,
+		TokenVersion:       current.TokenVersion,
+		UpdatedBy:          userID,
+		UpdatedTime:        time.Now(),
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return nil, pkgerrors.Database(err, "保存嵌入设置失败")
+	}
+	return settings, nil
+}
+
+// RevokeTokens 吊销空间内此前签发的全部嵌入令牌（仅空间所有者）
+func (s *EmbedService) RevokeTokens(ctx context.Context, userID, spaceID string) (*embed.Settings, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	settings, err := s.settings(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	settings.TokenVersion++
+	settings.UpdatedBy = userID
+	settings.UpdatedTime = time.Now()
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		return nil, pkgerrors.Database(err, "保存嵌入设置失败")
+	}
+	return settings, nil
+}
+
+// ==================== 令牌签发与验证 ====================
+
+// CreateToken 为视图签发嵌入令牌
+// 签发者需能查看该视图；editor 令牌还要求签发者能编辑表中的记录
+func (s *EmbedService) CreateToken(ctx context.Context, userID, viewID string, req CreateEmbedTokenRequest) (*EmbedTokenResponse, error) {
+	permission := req.Permission
+	if permission == "" {
+		permission = embed.PermissionViewer
+	}
+	if !permission.IsValid() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的权限级别: " + string(permission))
+	}
+	if req.ExpiresInMinutes < 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("有效期不能为负数")
+	}
+
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	if err := s.checkIssuer(ctx, userID, view, permission); err != nil {
+		return nil, err
+	}
+	_, spaceID, err := s.resolveSpace(ctx, view.TableID())
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settings(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, pkgerrors.ErrForbidden.WithDetails("该工作空间未启用嵌入")
+	}
+
+	ttl := s.cfg.DefaultTokenTTL
+	if req.ExpiresInMinutes > 0 {
+		ttl = time.Duration(req.ExpiresInMinutes) * time.Minute
+	}
+	if ttl > settings.MaxTokenTTL() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("有效期不能超过 %d 分钟", int(settings.MaxTokenTTL()/time.Minute)))
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := EmbedClaims{
+		ViewID:     view.ID(),
+		TableID:    view.TableID(),
+		SpaceID:    spaceID,
+		Permission: permission,
+		Version:    settings.TokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Audience:  jwt.ClaimStrings{embedTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.signingKey)
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("签发嵌入令牌失败")
+	}
+
+	return &EmbedTokenResponse{
+		Token:      token,
+		ViewID:     view.ID(),
+		Permission: permission,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+// Authenticate 验证嵌入令牌
+// 令牌须未过期、空间仍启用嵌入且令牌版本未被吊销，签发者仍有相应权限
+func (s *EmbedService) Authenticate(ctx context.Context, tokenString string) (*EmbedSession, error) {
+	if tokenString == "" {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("缺少嵌入令牌")
+	}
+	claims := &EmbedClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(embedTokenAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("嵌入令牌无效或已过期")
+	}
+
+	settings, err := s.settings(ctx, claims.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled || claims.Version != settings.TokenVersion {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("嵌入令牌已被吊销")
+	}
+
+	view, err := s.viewRepo.FindByID(ctx, claims.ViewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil || view.TableID() != claims.TableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	if err := s.checkIssuer(ctx, claims.Subject, view, claims.Permission); err != nil {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("嵌入令牌的签发者已无权访问该视图")
+	}
+	baseID, _, err := s.resolveSpace(ctx, view.TableID())
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmbedSession{Claims: claims, Settings: settings, view: view, baseID: baseID}, nil
+}
+
+// SettingsForToken 解析令牌所属空间的嵌入设置（不校验签名以外的条件）
+// 用于 CORS 预检：预检请求只能通过查询参数携带令牌，拒绝时也需要返回 frame-ancestors
+func (s *EmbedService) SettingsForToken(ctx context.Context, tokenString string) (*embed.Settings, error) {
+	claims := &EmbedClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(embedTokenAudience))
+	if err != nil {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("嵌入令牌无效或已过期")
+	}
+	return s.settings(ctx, claims.SpaceID)
+}
+
+// ==================== 嵌入数据 ====================
+
+// GetView 获取嵌入视图元数据
+func (s *EmbedService) GetView(ctx context.Context, session *EmbedSession) (*EmbedViewResponse, error) {
+	fields, err := s.visibleFields(ctx, session.view)
+	if err != nil {
+		return nil, err
+	}
+	resp := &EmbedViewResponse{
+		ID:         session.view.ID(),
+		Name:       session.view.Name(),
+		Type:       string(session.view.ViewType()),
+		TableID:    session.view.TableID(),
+		Permission: session.Claims.Permission,
+		Fields:     make([]*dto.FieldResponse, 0, len(fields)),
+	}
+	for _, field := range fields {
+		resp.Fields = append(resp.Fields, dto.FromFieldEntity(field))
+	}
+	return resp, nil
+}
+
+// SetViewQueryCache 设置视图查询结果缓存（过滤排序后的分页记录ID按依赖缓存）
+func (s *EmbedService) SetViewQueryCache(viewQueries *ViewQueryCache) {
+	s.viewQueries = viewQueries
+}
+
+// ListRecords 按视图的过滤与排序分页读取记录
+func (s *EmbedService) ListRecords(ctx context.Context, session *EmbedSession, page, pageSize int) (*dto.RecordListResponse, error) {
+	if pageSize <= 0 || pageSize > s.cfg.MaxPageSize {
+		pageSize = s.cfg.MaxPageSize
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	view := session.view
+	fields, err := s.fieldRepo.FindByTableID(ctx, view.TableID())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.viewQueries.Load(ViewQuery{
+		TableID:  view.TableID(),
+		ViewID:   view.ID(),
+		Filter:   view.Filter(),
+		Sort:     view.Sort(),
+		Page:     page,
+		PageSize: pageSize,
+	}, func() (*ViewQueryPage, error) {
+		query := s.dataDB(ctx, session.baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(session.baseID, view.TableID()))
+		if condition != nil {
+			query = query.Where(condition)
+		}
+		result := &ViewQueryPage{}
+		if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+			return nil, pkgerrors.Database(err, "统计记录失败")
+		}
+		for _, order := range embedSortColumns(view.Sort(), byID) {
+			query = query.Order(order)
+		}
+		if err := query.Limit(pageSize).Offset((page-1)*pageSize).Pluck("__id", &result.RecordIDs).Error; err != nil {
+			return nil, pkgerrors.Database(err, "查询记录失败")
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	total := result.Total
+
+	records, err := s.loadRecords(ctx, view.TableID(), result.RecordIDs)
+	if err != nil {
+		return nil, err
+	}
+	hidden := embedHiddenFieldIDs(view, fields)
+	for _, record := range records {
+		for fieldID := range hidden {
+			delete(record.Data, fieldID)
+		}
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return &dto.RecordListResponse{
+		Records: records,
+		Pagination: &dto.PaginationResponse{
+			Total:       total,
+			Page:        page,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}, nil
+}
+
+// UpdateRecord 通过 editor 令牌更新记录
+// 只能更新视图中可见的记录与可见的非计算字段，变更以签发者身份写入
+func (s *EmbedService) UpdateRecord(ctx context.Context, session *EmbedSession, recordID string, data map[string]interface{}) (*dto.RecordResponse, error) {
+	if session.Claims.Permission != embed.PermissionEditor {
+		return nil, pkgerrors.ErrForbidden.WithDetails("嵌入令牌没有编辑权限")
+	}
+	if len(data) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("没有要更新的字段")
+	}
+
+	view := session.view
+	fields, err := s.visibleFields(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	editable := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !field.IsComputed() {
+			editable[field.ID().String()] = true
+		}
+	}
+	for fieldID := range data {
+		if !editable[fieldID] {
+			return nil, pkgerrors.ErrForbidden.WithDetails("字段不可编辑: " + fieldID)
+		}
+	}
+	if err := s.ensureRecordInView(ctx, session, recordID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.recordService.UpdateRecord(ctx, view.TableID(), recordID, dto.UpdateRecordRequest{Data: data}, session.Claims.Subject); err != nil {
+		return nil, err
+	}
+	records, err := s.loadRecords(ctx, view.TableID(), []string{recordID})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+	return records[0], nil
+}
+
+// ensureRecordInView 记录是否满足视图的过滤条件
+func (s *EmbedService) ensureRecordInView(ctx context.Context, session *EmbedSession, recordID string) error {
+	fields, err := s.fieldRepo.FindByTableID(ctx, session.view.TableID())
+	if err != nil {
+		return pkgerrors.Database(err, "获取字段列表失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	condition, err := buildViewFilterCondition(ctx, session.view.Filter(), byID)
+	if err != nil {
+		return err
+	}
+
+	query := s.dataDB(ctx, session.baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(session.baseID, session.view.TableID())).
+		Where("__id = ?", recordID)
+	if condition != nil {
+		query = query.Where(condition)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return pkgerrors.Database(err, "查询记录失败")
+	}
+	if count == 0 {
+		return pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+	return nil
+}
+
+// loadRecords 按给定顺序加载记录并脱敏（匿名访问者，受保护字段一律脱敏）
+func (s *EmbedService) loadRecords(ctx context.Context, tableID string, ids []string) ([]*dto.RecordResponse, error) {
+	if len(ids) == 0 {
+		return []*dto.RecordResponse{}, nil
+	}
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+
+	byID := make(map[string]*dto.RecordResponse, len(entities))
+	for _, record := range entities {
+		byID[record.ID().String()] = dto.FromRecordEntity(record)
+	}
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := byID[id]; ok {
+			records = append(records, record)
+		}
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, records...)
+	}
+	return records, nil
+}
+
+// visibleFields 视图中可见的字段（按字段顺序）
+func (s *EmbedService) visibleFields(ctx context.Context, view *viewEntity.View) ([]*entity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, view.TableID())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	hidden := embedHiddenFieldIDs(view, fields)
+	visible := make([]*entity.Field, 0, len(fields))
+	for _, field := range fields {
+		if !hidden[field.ID().String()] {
+			visible = append(visible, field)
+		}
+	}
+	return visible, nil
+}
+
+// embedHiddenFieldIDs 视图中隐藏的字段（列配置中未出现的字段视为可见）
+func embedHiddenFieldIDs(view *viewEntity.View, fields []*entity.Field) map[string]bool {
+	hidden := make(map[string]bool)
+	for _, field := range fields {
+		if column := view.ColumnMeta().GetColumn(field.ID().String()); column != nil && !column.Visible {
+			hidden[field.ID().String()] = true
+		}
+	}
+	return hidden
+}
+
+// embedSortColumns 视图排序对应的排序列（引用不存在字段的排序项被忽略，最后按创建时间与ID保证稳定分页）
+func embedSortColumns(sort *viewVO.Sort, fields map[string]*entity.Field) []clause.OrderByColumn {
+	var columns []clause.OrderByColumn
+	if sort != nil {
+		for _, item := range sort.SortItems {
+			field, ok := fields[item.FieldID]
+			if !ok {
+				continue
+			}
+			columns = append(columns, clause.OrderByColumn{
+				Column: clause.Column{Name: field.DBFieldName().String()},
+				Desc:   item.Order == viewVO.SortOrderDesc,
+			})
+		}
+	}
+	return append(columns,
+		clause.OrderByColumn{Column: clause.Column{Name: "__created_time"}},
+		clause.OrderByColumn{Column: clause.Column{Name: "__id"}},
+	)
+}
+
+// checkIssuer 校验签发者对视图的权限
+func (s *EmbedService) checkIssuer(ctx context.Context, userID string, view *viewEntity.View, permission embed.Permission) error {
+	if !s.permissionService.CanReadView(ctx, userID, view.ID()) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+	}
+	if permission == embed.PermissionEditor && !s.permissionService.CanUpdateRecordsInTable(ctx, userID, view.TableID()) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限编辑该表格的记录")
+	}
+	return nil
+}
+
+// resolveSpace 表所属的 Base 与空间
+func (s *EmbedService) resolveSpace(ctx context.Context, tableID string) (string, string, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return "", "", pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return "", "", pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	base, err := s.baseRepo.FindByID(ctx, table.BaseID())
+	if err != nil {
+		return "", "", pkgerrors.Database(err, "查找Base失败")
+	}
+	if base == nil {
+		return "", "", pkgerrors.ErrNotFound.WithDetails("Base不存在")
+	}
+	return base.ID, base.SpaceID, nil
+}
+
+// settings 获取空间嵌入设置（未配置时返回默认设置）
+func (s *EmbedService) settings(ctx context.Context, spaceID string) (*embed.Settings, error) {
+	settings, err := s.repo.GetSettings(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取嵌入设置失败")
+	}
+	if settings == nil {
+		settings = embed.NewDefaultSettings(spaceID)
+	}
+	return settings, nil
+}
+
+// ensureOwner 嵌入设置只能由空间所有者管理
+func (s *EmbedService) ensureOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以管理嵌入设置")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/embed"
+)
+
+type stubEmbedRepo struct {
+	settings map[string]*embed.Settings
+}
+
+func (r *stubEmbedRepo) GetSettings(ctx context.Context, spaceID string) (*embed.Settings, error) {
+	return r.settings[spaceID], nil
+}
+
+func (r *stubEmbedRepo) SaveSettings(ctx context.Context, settings *embed.Settings) error {
+	r.settings[settings.SpaceID] = settings
+	return nil
+}
+
+func newTestEmbedService(repo embed.Repository) *EmbedService {
+	return NewEmbedService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, "secret", config.EmbedConfig{})
+}
+
+func signEmbedClaims(t *testing.T, key []byte, audience string) string {
+	t.Helper()
+	claims := EmbedClaims{
+		ViewID:     "viw1",
+		SpaceID:    "spc1",
+		Permission: embed.PermissionViewer,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "usr1",
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("签名失败: %v", err)
+	}
+	return token
+}
+
+func TestEmbedTokenUsesDerivedKey(t *testing.T) {
+	repo := &stubEmbedRepo{settings: map[string]*embed.Settings{
+		"spc1": {SpaceID: "spc1", Enabled: true, AllowedOrigins: []string{"https://example.com"}},
+	}}
+	s := newTestEmbedService(repo)
+
+	settings, err := s.SettingsForToken(context.Background(), signEmbedClaims(t, s.signingKey, embedTokenAudience))
+	if err != nil {
+		t.Fatalf("期望令牌有效，得到 %v", err)
+	}
+	if !settings.AllowsOrigin("https://example.com") {
+		t.Errorf("期望返回令牌所属空间的设置，得到 %+v", settings)
+	}
+
+	// 使用登录令牌的密钥签名的令牌不能作为嵌入令牌
+	if _, err := s.SettingsForToken(context.Background(), signEmbedClaims(t, []byte("secret"), embedTokenAudience)); err == nil {
+		t.Error("期望拒绝使用 JWT 密钥直接签名的令牌")
+	}
+	if _, err := s.SettingsForToken(context.Background(), signEmbedClaims(t, s.signingKey, "other")); err == nil {
+		t.Error("期望拒绝受众不匹配的令牌")
+	}
+}
+
+func TestEmbedAuthenticateRejectsRevokedToken(t *testing.T) {
+	repo := &stubEmbedRepo{settings: map[string]*embed.Settings{
+		"spc1": {SpaceID: "spc1", Enabled: true, TokenVersion: 1},
+	}}
+	s := newTestEmbedService(repo)
+
+	if _, err := s.Authenticate(context.Background(), signEmbedClaims(t, s.signingKey, embedTokenAudience)); err == nil {
+		t.Error("期望拒绝令牌版本已过期的令牌")
+	}
+	if _, err := s.Authenticate(context.Background(), ""); err == nil {
+		t.Error("期望拒绝空令牌")
+	}
+}
@@ -52,8 +52,10 @@ func (s *ErrorService) HandleDatabaseError(ctx context.Context, operation string
 		"operation":  operation,
 		"error_type": "database",
 	}
+	s.logError(ctx, err, metadata)
 
-	return s.HandleError(ctx, err, metadata)
+	// 按底层错误归类（唯一约束、死锁、连接中断等），保留可重试性
+	return errors.Database(err, operation)
 }
 
 // HandleBusinessLogicError 处理业务逻辑错误
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 )
 
 // EventBus 事件总线实现
@@ -295,7 +297,15 @@ func (eb *EventBus) publishBatchAsync(ctx context.Context, eventList []events.Do
 }
 
 // executeHandler 执行事件处理器
-func (eb *EventBus) executeHandler(ctx context.Context, handler events.EventHandler, event events.DomainEvent) error {
+func (eb *EventBus) executeHandler(ctx context.Context, handler events.EventHandler, event events.DomainEvent) (err error) {
+	ctx, span := tracing.Start(ctx, "event.handle "+event.EventType(),
+		tracing.WithSpanKind(tracing.SpanKindConsumer),
+		tracing.WithAttributes(
+			tracing.String("messaging.operation.type", "process"),
+			tracing.String("luckdb.event_type", event.EventType()),
+			tracing.String("luckdb.handler", fmt.Sprintf("%T", handler))))
+	defer func() { span.Finish(err) }()
+
 	// 重试机制
 	for attempt := 0; attempt <= eb.config.MaxRetries; attempt++ {
 		err := handler.Handle(ctx, event)
@@ -303,6 +313,11 @@ func (eb *EventBus) executeHandler(ctx context.Context, handler events.EventHand
 			return nil
 		}
 
+		// 明确不可重试的错误（如校验失败、资源不存在）无需重试
+		if pkgerrors.IsPermanent(err) {
+			return fmt.Errorf("event handler failed with permanent error: %w", err)
+		}
+
 		if attempt < eb.config.MaxRetries {
 			logger.Warn("event handler retry",
 				logger.String("event_type", event.EventType()),
@@ -0,0 +1,910 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/crypto"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/externalsource"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+var externalSyncLog = logger.Named("external_sync")
+
+// externalSyncFieldTypes 外部列可以映射的字段类型
+var externalSyncFieldTypes = map[string]bool{
+	fieldVO.TypeSingleLineText: true,
+	fieldVO.TypeLongText:       true,
+	fieldVO.TypeNumber:         true,
+	fieldVO.TypeCurrency:       true,
+	fieldVO.TypePercent:        true,
+	fieldVO.TypeCheckbox:       true,
+	fieldVO.TypeDate:           true,
+	fieldVO.TypeEmail:          true,
+	fieldVO.TypeURL:            true,
+	fieldVO.TypePhone:          true,
+}
+
+// ExternalReaderFactory 根据数据源配置创建读取器
+type ExternalReaderFactory func(source externalsync.Source, credentials string, columns []string) (externalsource.Reader, error)
+
+// ExternalSyncMappingRequest 外部列与目标字段的对应
+type ExternalSyncMappingRequest struct {
+	Column string `json:"column" binding:"required"`
+	Name   string `json:"name"` // 目标字段名称（为空时使用列名）
+	Type   string `json:"type"` // 目标字段类型（为空时为单行文本）
+}
+
+// CreateExternalSyncRequest 创建外部数据同步请求
+type CreateExternalSyncRequest struct {
+	Name        string                       `json:"name" binding:"required"` // 目标表名称
+	Source      externalsync.Source          `json:"source" binding:"required"`
+	Credentials string                       `json:"credentials"`                 // 数据库连接串或 REST 的 Authorization 头
+	Mappings    []ExternalSyncMappingRequest `json:"mappings" binding:"required"` // 第一个对应的字段为主字段
+	Interval    string                       `json:"interval"`                    // 同步间隔，如 "15m"（为空时使用默认间隔）
+}
+
+// UpdateExternalSyncRequest 更新外部数据同步请求（未提供的字段保持不变）
+type UpdateExternalSyncRequest struct {
+	Name        *string `json:"name"`
+	Credentials *string `json:"credentials"`
+	Interval    *string `json:"interval"`
+	Enabled     *bool   `json:"enabled"`
+}
+
+// ExternalSyncService 外部数据同步服务 ✨
+// 按间隔从外部 PostgreSQL / MySQL 表或 REST 接口拉取行，写入 Base 中的只读同步表：
+//   - 创建时按字段对应关系建立目标表，另加“源记录已删除”复选框字段；
+//   - 配置了更新时间列时按 (更新时间, 键) 游标增量读取，否则每次全量；
+//     增量同步无法发现删除，按 full_sync_interval 定期全量，本次未读到的行勾选删除标记；
+//   - 目标表与同步表一样只能由同步写入，每次运行记录读取、新建、更新与标记删除的行数
+type ExternalSyncService struct {
+	repo              externalsync.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	tables            syncedTableCreator
+	fields            syncedFieldCreator
+	records           syncedRecordWriter
+	permissionService *PermissionServiceV2
+	syncedTables      *SyncedTableService
+	newReader         ExternalReaderFactory
+	credentials       CredentialSealer
+	cfg               config.ExternalSyncConfig
+}
+
+// CredentialSealer 连接器凭据的加解密（由 crypto.FieldEncryptor 实现）
+// 凭据按连接器所属工作空间的数据密钥加密，连接器ID作为附加认证数据；
+// Decrypt 对开启加密前保存的明文凭据原样返回
+type CredentialSealer interface {
+	Encrypt(ctx context.Context, baseID, fieldID string, value interface{}) (interface{}, error)
+	Decrypt(ctx context.Context, baseID, fieldID string, stored interface{}) (interface{}, error)
+}
+
+// NewExternalSyncService 创建外部数据同步服务
+// 目标表的只读限制由 SyncedTableService 统一检查
+func NewExternalSyncService(
+	repo externalsync.Repository,
+	tableRepository tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	tables syncedTableCreator,
+	fields syncedFieldCreator,
+	records syncedRecordWriter,
+	permissionService *PermissionServiceV2,
+	syncedTables *SyncedTableService,
+	cfg config.ExternalSyncConfig,
+) *ExternalSyncService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = time.Minute
+	}
+	if cfg.DefaultInterval < cfg.MinInterval {
+		cfg.DefaultInterval = 15 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 200
+	}
+	if cfg.BatchSize > recordMutationBatchMaxOps {
+		cfg.BatchSize = recordMutationBatchMaxOps
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 2 * time.Minute
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 30 * time.Minute
+	}
+	if cfg.RunHistory <= 0 {
+		cfg.RunHistory = 50
+	}
+	httpClient := netguard.NewHTTPClient(cfg.RequestTimeout, cfg.AllowPrivate)
+	s := &ExternalSyncService{
+		repo:              repo,
+		tableRepo:         tableRepository,
+		fieldRepo:         fieldRepo,
+		tables:            tables,
+		fields:            fields,
+		records:           records,
+		permissionService: permissionService,
+		syncedTables:      syncedTables,
+		newReader: func(source externalsync.Source, credentials string, columns []string) (externalsource.Reader, error) {
+			return externalsource.New(source, credentials, columns, httpClient, cfg.AllowPrivate)
+		},
+		cfg: cfg,
+	}
+	if syncedTables != nil {
+		syncedTables.addTargetFinder(s)
+	}
+	return s
+}
+
+// SetCredentialSealer 设置凭据加密器（未设置时凭据以明文保存）
+func (s *ExternalSyncService) SetCredentialSealer(sealer CredentialSealer) {
+	s.credentials = sealer
+}
+
+// credentialsAAD 凭据密文的附加认证数据，防止密文在连接器之间被挪用
+func credentialsAAD(connectorID string) string {
+	return "external_sync:" + connectorID
+}
+
+// sealCredentials 加密连接器凭据（未设置加密器或凭据为空时保持原样）
+func (s *ExternalSyncService) sealCredentials(ctx context.Context, connector *externalsync.Connector, credentials string) error {
+	if s.credentials == nil || credentials == "" {
+		connector.Credentials = credentials
+		return nil
+	}
+	sealed, err := s.credentials.Encrypt(ctx, connector.BaseID, credentialsAAD(connector.ID), credentials)
+	if err != nil {
+		return pkgerrors.ErrInternalServer.WithCause(err).WithDetails("加密数据源凭据失败")
+	}
+	text, ok := sealed.(string)
+	if !ok {
+		return pkgerrors.ErrInternalServer.WithDetails("加密数据源凭据失败")
+	}
+	connector.Credentials = text
+	return nil
+}
+
+// openCredentials 解密连接器凭据
+func (s *ExternalSyncService) openCredentials(ctx context.Context, connector *externalsync.Connector) (string, error) {
+	if s.credentials == nil || connector.Credentials == "" {
+		if crypto.IsCiphertext(connector.Credentials) {
+			return "", fmt.Errorf("数据源凭据已加密，但未配置加密主密钥")
+		}
+		return connector.Credentials, nil
+	}
+	value, err := s.credentials.Decrypt(ctx, connector.BaseID, credentialsAAD(connector.ID), connector.Credentials)
+	if err != nil {
+		return "", fmt.Errorf("解密数据源凭据失败: %w", err)
+	}
+	credentials, _ := value.(string)
+	return credentials, nil
+}
+
+// CreateConnector 在 Base 中创建目标表并登记连接器（首次全量同步由后台立即执行）
+func (s *ExternalSyncService) CreateConnector(ctx context.Context, userID, baseID string, req CreateExternalSyncRequest) (*externalsync.Connector, error) {
+	if s.permissionService != nil && !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的外部数据同步")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+	}
+	interval := s.cfg.DefaultInterval
+	if req.Interval != "" {
+		var err error
+		if interval, err = s.parseInterval(req.Interval); err != nil {
+			return nil, err
+		}
+	}
+	mappings, err := externalSyncMappings(req.Mappings)
+	if err != nil {
+		return nil, err
+	}
+	// 构造读取器以校验数据源配置（不发起连接）
+	reader, err := s.newReader(req.Source, req.Credentials, mappingColumns(mappings))
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("数据源配置无效: " + err.Error())
+	}
+	_ = reader.Close()
+
+	deletedName := syncedTableDeletedFieldName
+	configs := make([]dto.FieldConfigDTO, 0, len(mappings)+1)
+	for i, m := range mappings {
+		if m.Name == deletedName {
+			deletedName = syncedTableDeletedFieldName + "（同步）"
+		}
+		configs = append(configs, dto.FieldConfigDTO{Name: m.Name, Type: m.Type, IsPrimary: i == 0})
+	}
+	configs = append(configs, dto.FieldConfigDTO{Name: deletedName, Type: fieldVO.TypeCheckbox})
+
+	target, err := s.tables.CreateTable(ctx, dto.CreateTableRequest{
+		Name:        name,
+		Description: "同步表（只读），数据来自外部数据源",
+		BaseID:      baseID,
+		Fields:      configs,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按名称对应创建出的字段（创建失败的字段在首次同步时补建）
+	created, err := s.fieldRepo.FindByTableID(ctx, target.ID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询目标表字段失败")
+	}
+	byName := make(map[string]string, len(created))
+	for _, field := range created {
+		byName[field.Name().String()] = field.ID().String()
+	}
+	for i := range mappings {
+		mappings[i].FieldID = byName[mappings[i].Name]
+	}
+
+	connector := externalsync.NewConnector(baseID, name, req.Source, req.Credentials, mappings, interval, userID)
+	connector.TargetTableID = target.ID
+	connector.DeletedFieldID = byName[deletedName]
+	if err := s.sealCredentials(ctx, connector, req.Credentials); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, connector); err != nil {
+		return nil, pkgerrors.Database(err, "保存外部数据同步失败")
+	}
+	return connector, nil
+}
+
+// ListConnectors 列出 Base 的外部数据同步
+func (s *ExternalSyncService) ListConnectors(ctx context.Context, userID, baseID string) ([]*externalsync.Connector, error) {
+	if s.permissionService != nil && !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	connectors, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取外部数据同步失败")
+	}
+	return connectors, nil
+}
+
+// GetConnector 获取外部数据同步
+func (s *ExternalSyncService) GetConnector(ctx context.Context, userID, connectorID string) (*externalsync.Connector, error) {
+	connector, err := s.findConnector(ctx, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanAccessBase(ctx, userID, connector.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	return connector, nil
+}
+
+// UpdateConnector 更新外部数据同步（名称、凭据、间隔、启停）
+func (s *ExternalSyncService) UpdateConnector(ctx context.Context, userID, connectorID string, req UpdateExternalSyncRequest) (*externalsync.Connector, error) {
+	connector, err := s.loadConnector(ctx, userID, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+		}
+		connector.Name = name
+	}
+	if req.Credentials != nil {
+		reader, err := s.newReader(connector.Source, *req.Credentials, mappingColumns(connector.Mappings))
+		if err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("凭据无效: " + err.Error())
+		}
+		_ = reader.Close()
+		if err := s.sealCredentials(ctx, connector, *req.Credentials); err != nil {
+			return nil, err
+		}
+	}
+	if req.Interval != nil {
+		if connector.Interval, err = s.parseInterval(*req.Interval); err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && !connector.Enabled {
+			connector.NextRunAt = time.Now()
+		}
+		connector.Enabled = *req.Enabled
+	}
+	connector.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, connector); err != nil {
+		return nil, pkgerrors.Database(err, "保存外部数据同步失败")
+	}
+	return connector, nil
+}
+
+// TriggerConnector 立即同步一次（由后台在下一个轮询周期执行）
+func (s *ExternalSyncService) TriggerConnector(ctx context.Context, userID, connectorID string) (*externalsync.Connector, error) {
+	connector, err := s.loadConnector(ctx, userID, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if !connector.Enabled {
+		return nil, pkgerrors.ErrConflict.WithDetails("同步已停用")
+	}
+	connector.NextRunAt = time.Now()
+	connector.UpdatedAt = connector.NextRunAt
+	if err := s.repo.Save(ctx, connector); err != nil {
+		return nil, pkgerrors.Database(err, "保存外部数据同步失败")
+	}
+	return connector, nil
+}
+
+// DeleteConnector 删除外部数据同步：目标表保留并恢复为普通表
+func (s *ExternalSyncService) DeleteConnector(ctx context.Context, userID, connectorID string) error {
+	connector, err := s.loadConnector(ctx, userID, connectorID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, connectorID); err != nil {
+		return pkgerrors.Database(err, "删除外部数据同步失败")
+	}
+	if s.syncedTables != nil {
+		s.syncedTables.invalidate(connector.TargetTableID)
+	}
+	return nil
+}
+
+// ListRuns 获取外部数据同步最近的运行记录
+func (s *ExternalSyncService) ListRuns(ctx context.Context, userID, connectorID string) ([]*externalsync.Run, error) {
+	connector, err := s.findConnector(ctx, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanAccessBase(ctx, userID, connector.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	runs, err := s.repo.ListRuns(ctx, connectorID, s.cfg.RunHistory)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步记录失败")
+	}
+	return runs, nil
+}
+
+// IsTargetTable 表是否为外部数据同步的目标表（供同步表只读检查使用）
+func (s *ExternalSyncService) IsTargetTable(ctx context.Context, tableID string) (bool, error) {
+	connector, err := s.repo.FindByTargetTable(ctx, tableID)
+	if err != nil {
+		return false, err
+	}
+	return connector != nil, nil
+}
+
+func (s *ExternalSyncService) findConnector(ctx context.Context, connectorID string) (*externalsync.Connector, error) {
+	connector, err := s.repo.FindByID(ctx, connectorID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取外部数据同步失败")
+	}
+	if connector == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("外部数据同步不存在")
+	}
+	return connector, nil
+}
+
+// loadConnector 获取连接器并校验 Base 的管理权限
+func (s *ExternalSyncService) loadConnector(ctx context.Context, userID, connectorID string) (*externalsync.Connector, error) {
+	connector, err := s.findConnector(ctx, connectorID)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanUpdateBase(ctx, userID, connector.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的外部数据同步")
+	}
+	return connector, nil
+}
+
+func (s *ExternalSyncService) parseInterval(raw string) (time.Duration, error) {
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails("同步间隔无效，如 15m")
+	}
+	if interval < s.cfg.MinInterval {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("同步间隔不能小于 %s", s.cfg.MinInterval))
+	}
+	return interval, nil
+}
+
+// externalSyncMappings 校验字段对应：列名必填且不重复，字段名称不重复，类型必须可由外部值写入
+func externalSyncMappings(requests []ExternalSyncMappingRequest) ([]externalsync.Mapping, error) {
+	if len(requests) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("至少需要一个字段对应")
+	}
+	columns := make(map[string]bool, len(requests))
+	names := make(map[string]bool, len(requests))
+	mappings := make([]externalsync.Mapping, 0, len(requests))
+	for _, req := range requests {
+		column := strings.TrimSpace(req.Column)
+		if column == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("字段对应的列名不能为空")
+		}
+		if columns[column] {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("列重复: " + column)
+		}
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = column
+		}
+		if names[name] {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("字段名称重复: " + name)
+		}
+		fieldType := req.Type
+		if fieldType == "" {
+			fieldType = fieldVO.TypeSingleLineText
+		}
+		if !externalSyncFieldTypes[fieldType] {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的字段类型: " + fieldType)
+		}
+		columns[column], names[name] = true, true
+		mappings = append(mappings, externalsync.Mapping{Column: column, Name: name, Type: fieldType})
+	}
+	return mappings, nil
+}
+
+func mappingColumns(mappings []externalsync.Mapping) []string {
+	columns := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		columns = append(columns, m.Column)
+	}
+	return columns
+}
+
+// Start 启动后台同步
+func (s *ExternalSyncService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runDue(ctx)
+		}
+	}()
+}
+
+// runDue 领取并执行到期的连接器
+func (s *ExternalSyncService) runDue(ctx context.Context) {
+	now := time.Now()
+	connectors, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 5)
+	if err != nil {
+		externalSyncLog.Warn(ctx, "领取外部数据同步失败", logger.ErrorField(err))
+		return
+	}
+	for _, connector := range connectors {
+		s.runConnector(ctx, connector)
+	}
+}
+
+// runConnector 执行一次同步并记录运行结果
+func (s *ExternalSyncService) runConnector(ctx context.Context, connector *externalsync.Connector) {
+	run := externalsync.NewRun(connector, connector.NeedsFullSync(time.Now(), s.cfg.FullSyncInterval))
+	if err := s.repo.SaveRun(ctx, run); err != nil {
+		externalSyncLog.Warn(ctx, "保存同步记录失败", logger.String("connector_id", connector.ID), logger.ErrorField(err))
+	}
+
+	err := s.syncOnce(ctx, connector, run)
+	run.Finish(connector, err)
+	if err := s.repo.SaveRun(ctx, run); err != nil {
+		externalSyncLog.Warn(ctx, "保存同步记录失败", logger.String("connector_id", connector.ID), logger.ErrorField(err))
+	}
+	if err := s.repo.PurgeRuns(ctx, connector.ID, s.cfg.RunHistory); err != nil {
+		externalSyncLog.Warn(ctx, "清理同步记录失败", logger.String("connector_id", connector.ID), logger.ErrorField(err))
+	}
+
+	connector.LastRunAt = run.FinishedAt
+	connector.LastStatus = run.Status
+	connector.LastError = run.Error
+	if err == nil && run.Full {
+		connector.LastFullSyncAt = run.FinishedAt
+	}
+	connector.NextRunAt = time.Now().Add(connector.Interval)
+	if err := s.repo.SaveProgress(ctx, connector); err != nil {
+		externalSyncLog.Warn(ctx, "保存同步进度失败", logger.String("connector_id", connector.ID), logger.ErrorField(err))
+	}
+	if err != nil {
+		externalSyncLog.Warn(ctx, "外部数据同步失败",
+			logger.String("connector_id", connector.ID),
+			logger.ErrorField(err))
+	}
+}
+
+// syncOnce 补建目标字段后执行增量同步；需要全量或新建了字段（需要回填）时全量同步
+func (s *ExternalSyncService) syncOnce(ctx context.Context, connector *externalsync.Connector, run *externalsync.Run) error {
+	if s.permissionService != nil && !s.permissionService.CanUpdateBase(ctx, connector.CreatedBy, connector.BaseID) {
+		return fmt.Errorf("同步创建者已无权管理该 Base")
+	}
+	target, err := s.tableRepo.GetByID(ctx, connector.TargetTableID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return fmt.Errorf("目标表 %s 不存在", connector.TargetTableID)
+	}
+	added, err := s.ensureFields(ctx, connector)
+	if err != nil {
+		return err
+	}
+	credentials, err := s.openCredentials(ctx, connector)
+	if err != nil {
+		return err
+	}
+	reader, err := s.newReader(connector.Source, credentials, mappingColumns(connector.Mappings))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if added {
+		run.Full = true
+	}
+	if run.Full {
+		return s.syncFull(ctx, connector, run, reader)
+	}
+	return s.syncIncremental(ctx, connector, run, reader)
+}
+
+// syncFull 全量同步：写入全部行，本次未读到的目标记录标记为源记录已删除
+// 读取前先记录更新时间列的最大值，之后的增量同步从该值继续，不会遗漏读取期间的更新
+func (s *ExternalSyncService) syncFull(ctx context.Context, connector *externalsync.Connector, run *externalsync.Run, reader externalsource.Reader) error {
+	var head string
+	if connector.Source.CursorColumn != "" {
+		var err error
+		if head, err = s.head(ctx, reader); err != nil {
+			return err
+		}
+	}
+	startedAt := time.Now()
+
+	var latest *externalsource.Cursor // 数据源不支持 Head 时读取到的最大更新时间
+	token := ""
+	for {
+		page, err := s.read(ctx, reader, externalsource.Query{PageToken: token, Limit: s.cfg.BatchSize})
+		if err != nil {
+			return err
+		}
+		run.Fetched += int64(len(page.Records))
+		if err := s.writeRecords(ctx, connector, run, page.Records); err != nil {
+			return err
+		}
+		if page.Last != nil && (latest == nil || page.Last.After(*latest)) {
+			latest = page.Last
+		}
+		if page.NextToken == "" || page.NextToken == token {
+			break
+		}
+		token = page.NextToken
+	}
+
+	for {
+		stale, err := s.repo.ListStaleRows(ctx, connector.ID, startedAt, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			break
+		}
+		if err := s.markRemoved(ctx, connector, run, stale); err != nil {
+			return err
+		}
+	}
+
+	if connector.Source.CursorColumn != "" {
+		if head == "" && latest != nil {
+			head = latest.Value
+		}
+		connector.Cursor, connector.CursorKey = head, ""
+	}
+	return nil
+}
+
+// syncIncremental 读取游标之后更新的行并写入目标表，每页写入成功后推进游标
+func (s *ExternalSyncService) syncIncremental(ctx context.Context, connector *externalsync.Connector, run *externalsync.Run, reader externalsource.Reader) error {
+	token := ""
+	for {
+		since := &externalsource.Cursor{Value: connector.Cursor, Key: connector.CursorKey}
+		page, err := s.read(ctx, reader, externalsource.Query{Since: since, PageToken: token, Limit: s.cfg.BatchSize})
+		if err != nil {
+			return err
+		}
+		run.Fetched += int64(len(page.Records))
+		if err := s.writeRecords(ctx, connector, run, page.Records); err != nil {
+			return err
+		}
+		if page.Last != nil && page.Last.Value != "" {
+			connector.Cursor, connector.CursorKey = page.Last.Value, page.Last.Key
+		}
+		if page.NextToken == "" || page.NextToken == token {
+			return nil
+		}
+		token = page.NextToken
+	}
+}
+
+func (s *ExternalSyncService) head(ctx context.Context, reader externalsource.Reader) (string, error) {
+	readCtx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	return reader.Head(readCtx)
+}
+
+func (s *ExternalSyncService) read(ctx context.Context, reader externalsource.Reader, q externalsource.Query) (*externalsource.Page, error) {
+	readCtx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	return reader.Read(readCtx, q)
+}
+
+// writeRecords 按键创建或更新目标记录（同时取消删除标记），没有键的行跳过
+// 对应的目标记录已不存在时重新创建
+func (s *ExternalSyncService) writeRecords(ctx context.Context, connector *externalsync.Connector, run *externalsync.Run, records []externalsource.Record) error {
+	keys := make([]string, 0, len(records))
+	byKey := make(map[string]externalsource.Record, len(records))
+	for _, record := range records {
+		key := externalsource.KeyString(record[connector.Source.KeyColumn])
+		if key == "" {
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = record
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	rows, err := s.repo.FindRows(ctx, connector.ID, keys)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*externalsync.Row, len(rows))
+	for _, row := range rows {
+		existing[row.ExternalKey] = row
+	}
+
+	mutations := make([]dto.RecordMutation, 0, len(keys))
+	for _, key := range keys {
+		mutation := dto.RecordMutation{Op: dto.RecordMutationCreate, Fields: externalSyncValues(connector, byKey[key])}
+		if row := existing[key]; row != nil {
+			mutation.Op, mutation.ID = dto.RecordMutationUpdate, row.TargetRecordID
+		}
+		mutations = append(mutations, mutation)
+	}
+	items, err := s.mutate(ctx, connector, mutations)
+	if err != nil {
+		return err
+	}
+
+	var saved []*externalsync.Row
+	var retry []dto.RecordMutation
+	var retryKeys []string
+	failed, message := 0, ""
+	for i, item := range items {
+		if item != nil && item.Status == "success" {
+			saved = append(saved, &externalsync.Row{ConnectorID: connector.ID, ExternalKey: keys[i], TargetRecordID: item.ID, SyncedAt: time.Now()})
+			if mutations[i].Op == dto.RecordMutationCreate {
+				run.Created++
+			} else {
+				run.Updated++
+			}
+			continue
+		}
+		if mutations[i].Op == dto.RecordMutationUpdate && item != nil && item.Error != nil && item.Error.Code == pkgerrors.ErrNotFound.Code {
+			retry = append(retry, dto.RecordMutation{Op: dto.RecordMutationCreate, Fields: mutations[i].Fields})
+			retryKeys = append(retryKeys, keys[i])
+			continue
+		}
+		failed++
+		message, _ = mutationFailure(item)
+	}
+	if len(retry) > 0 {
+		items, err := s.mutate(ctx, connector, retry)
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			if item != nil && item.Status == "success" {
+				saved = append(saved, &externalsync.Row{ConnectorID: connector.ID, ExternalKey: retryKeys[i], TargetRecordID: item.ID, SyncedAt: time.Now()})
+				run.Created++
+				continue
+			}
+			failed++
+			message, _ = mutationFailure(item)
+		}
+	}
+	if err := s.repo.SaveRows(ctx, saved); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条目标记录写入失败: %s", failed, message)
+	}
+	return nil
+}
+
+// markRemoved 为本次全量同步未读到的行勾选删除标记
+func (s *ExternalSyncService) markRemoved(ctx context.Context, connector *externalsync.Connector, run *externalsync.Run, rows []*externalsync.Row) error {
+	var mutations []dto.RecordMutation
+	if connector.DeletedFieldID != "" {
+		mutations = make([]dto.RecordMutation, 0, len(rows))
+		for _, row := range rows {
+			mutations = append(mutations, dto.RecordMutation{
+				Op:     dto.RecordMutationUpdate,
+				ID:     row.TargetRecordID,
+				Fields: map[string]interface{}{connector.DeletedFieldID: true},
+			})
+		}
+	}
+	items, err := s.mutate(ctx, connector, mutations)
+	if err != nil {
+		return err
+	}
+	failed, message := 0, ""
+	for i, item := range items {
+		// 目标记录已不存在时无需标记
+		if item == nil || (item.Status != "success" && (item.Error == nil || item.Error.Code != pkgerrors.ErrNotFound.Code)) {
+			failed++
+			message, _ = mutationFailure(item)
+			rows[i] = nil
+		}
+	}
+	marked := make([]*externalsync.Row, 0, len(rows))
+	for _, row := range rows {
+		if row != nil {
+			row.SourceDeleted = true
+			row.SyncedAt = time.Now()
+			marked = append(marked, row)
+		}
+	}
+	run.Removed += int64(len(marked))
+	if err := s.repo.SaveRows(ctx, marked); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条目标记录标记删除失败: %s", failed, message)
+	}
+	return nil
+}
+
+// mutate 以同步身份写入目标表，返回与 mutations 一一对应的结果
+func (s *ExternalSyncService) mutate(ctx context.Context, connector *externalsync.Connector, mutations []dto.RecordMutation) ([]*dto.RecordMutationResult, error) {
+	results := make([]*dto.RecordMutationResult, len(mutations))
+	for start := 0; start < len(mutations); start += s.cfg.BatchSize {
+		end := start + s.cfg.BatchSize
+		if end > len(mutations) {
+			end = len(mutations)
+		}
+		resp, err := s.records.MutateRecords(withSyncedTableWrite(ctx), connector.TargetTableID, dto.RecordMutationBatchRequest{Operations: mutations[start:end]}, connector.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Results {
+			if item.Index >= 0 && start+item.Index < end {
+				results[start+item.Index] = item
+			}
+		}
+	}
+	return results, nil
+}
+
+// ensureFields 为尚未建立（或已被删除）的目标字段与删除标记字段补建字段，返回是否新建了字段
+func (s *ExternalSyncService) ensureFields(ctx context.Context, connector *externalsync.Connector) (bool, error) {
+	targetFields, err := s.fieldRepo.FindByTableID(ctx, connector.TargetTableID)
+	if err != nil {
+		return false, err
+	}
+	exists := make(map[string]bool, len(targetFields))
+	for _, field := range targetFields {
+		exists[field.ID().String()] = true
+	}
+
+	added := false
+	for i := range connector.Mappings {
+		m := &connector.Mappings[i]
+		if exists[m.FieldID] {
+			continue
+		}
+		created, err := s.fields.CreateField(withSyncedTableWrite(ctx), dto.CreateFieldRequest{
+			TableID: connector.TargetTableID,
+			Name:    m.Name,
+			Type:    m.Type,
+		}, connector.CreatedBy)
+		if err != nil {
+			return added, fmt.Errorf("创建目标字段 %s 失败: %w", m.Name, err)
+		}
+		m.FieldID = created.ID
+		added = true
+	}
+	if !exists[connector.DeletedFieldID] {
+		created, err := s.fields.CreateField(withSyncedTableWrite(ctx), dto.CreateFieldRequest{
+			TableID: connector.TargetTableID,
+			Name:    syncedTableDeletedFieldName,
+			Type:    fieldVO.TypeCheckbox,
+		}, connector.CreatedBy)
+		if err != nil {
+			return added, fmt.Errorf("创建删除标记字段失败: %w", err)
+		}
+		connector.DeletedFieldID = created.ID
+		added = true
+	}
+	return added, nil
+}
+
+// externalSyncValues 将外部行按字段对应关系转换为目标记录的值
+func externalSyncValues(connector *externalsync.Connector, record externalsource.Record) map[string]interface{} {
+	values := make(map[string]interface{}, len(connector.Mappings)+1)
+	for _, m := range connector.Mappings {
+		if m.FieldID == "" {
+			continue
+		}
+		values[m.FieldID] = externalFieldValue(record[m.Column], m.Type)
+	}
+	if connector.DeletedFieldID != "" {
+		values[connector.DeletedFieldID] = false
+	}
+	return values
+}
+
+// externalFieldValue 按目标字段类型转换外部值（无法转换的数字与复选框值写入空值）
+func externalFieldValue(value interface{}, fieldType string) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch fieldType {
+	case fieldVO.TypeNumber, fieldVO.TypeCurrency, fieldVO.TypePercent:
+		switch v := value.(type) {
+		case int64, float64, int, float32, int32:
+			return v
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f
+			}
+		}
+		return nil
+	case fieldVO.TypeCheckbox:
+		switch v := value.(type) {
+		case bool:
+			return v
+		case int64:
+			return v != 0
+		case float64:
+			return v != 0
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b
+			}
+			return strings.EqualFold(strings.TrimSpace(v), "yes")
+		}
+		return nil
+	default:
+		switch v := value.(type) {
+		case string:
+			return v
+		case map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil
+			}
+			return string(data)
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// 记录数据的键类型（请求参数 fieldKeyType）
+const (
+	FieldKeyTypeID     = "id"     // 字段ID（默认）
+	FieldKeyTypeName   = "name"   // 字段名称（重命名后失效）
+	FieldKeyTypeAPIKey = "apiKey" // 字段的 API 键（重命名后不变）
+)
+
+// ParseFieldKeyType 解析记录数据的键类型，为空时使用字段ID
+func ParseFieldKeyType(value string) (string, error) {
+	switch value {
+	case "":
+		return FieldKeyTypeID, nil
+	case FieldKeyTypeID, FieldKeyTypeName, FieldKeyTypeAPIKey:
+		return value, nil
+	}
+	return "", pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("fieldKeyType 必须为 %s、%s 或 %s", FieldKeyTypeID, FieldKeyTypeName, FieldKeyTypeAPIKey))
+}
+
+// FieldAPIKeys 字段ID到 API 键的映射
+// 升级前创建、尚未回填的字段按字段顺序由名称临时生成（与在线迁移的回填规则一致）
+func FieldAPIKeys(fields []*entity.Field) map[string]string {
+	keys := make(map[string]string, len(fields))
+	taken := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if key := field.APIKey(); !key.IsEmpty() {
+			keys[field.ID().String()] = key.String()
+			taken[key.String()] = true
+		}
+	}
+	for _, field := range fields {
+		if _, ok := keys[field.ID().String()]; ok {
+			continue
+		}
+		key := valueobject.GenerateAPIKey(field.Name().String(), func(k string) bool { return taken[k] }).String()
+		keys[field.ID().String()] = key
+		taken[key] = true
+	}
+	return keys
+}
+
+// newAPIKey 字段的 API 键：指定时校验格式与同表唯一，为空时由名称生成（冲突时追加序号）
+// excludeID 为正在更新的字段
+func (s *FieldService) newAPIKey(ctx context.Context, tableID, name, requested, excludeID string) (valueobject.APIKey, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return valueobject.APIKey{}, pkgerrors.Database(err, "查询字段列表失败")
+	}
+	taken := make(map[string]bool, len(fields))
+	for id, key := range FieldAPIKeys(fields) {
+		if id != excludeID {
+			taken[key] = true
+		}
+	}
+
+	if requested == "" {
+		return valueobject.GenerateAPIKey(name, func(k string) bool { return taken[k] }), nil
+	}
+	apiKey, err := valueobject.NewAPIKey(requested)
+	if err != nil {
+		return valueobject.APIKey{}, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("API 键无效: %v", err))
+	}
+	if taken[apiKey.String()] {
+		return valueobject.APIKey{}, pkgerrors.ErrConflict.WithMessage(fmt.Sprintf("API 键 '%s' 已存在", apiKey.String()))
+	}
+	return apiKey, nil
+}
+
+// ResolveFieldKeys 将写入数据中按 keyType 指定的键转换为字段ID（原地修改）
+// id 与 name 不做转换（写入路径按字段ID或名称查找）；apiKey 时未知的键返回校验错误
+func (s *RecordService) ResolveFieldKeys(ctx context.Context, tableID, keyType string, rows ...map[string]interface{}) error {
+	if keyType != FieldKeyTypeAPIKey {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段列表失败")
+	}
+	toID := make(map[string]string, len(fields))
+	for id, key := range FieldAPIKeys(fields) {
+		toID[key] = id
+	}
+
+	for _, row := range rows {
+		converted := make(map[string]interface{}, len(row))
+		for key, value := range row {
+			id, ok := toID[key]
+			if !ok {
+				return pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+					"message": "未知的字段 API 键",
+					"apiKey":  key,
+				})
+			}
+			converted[id] = value
+		}
+		for key := range row {
+			delete(row, key)
+		}
+		for id, value := range converted {
+			row[id] = value
+		}
+	}
+	return nil
+}
+
+// ResolveProjection 将字段投影中按 keyType 指定的键（字段ID、名称或 API 键）转换为字段ID
+// keys 为空时返回 nil（不投影）；未知的键返回校验错误，重复的键只保留一次
+func (s *RecordService) ResolveProjection(ctx context.Context, tableID, keyType string, keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段列表失败")
+	}
+	toID := make(map[string]string, len(fields))
+	switch keyType {
+	case FieldKeyTypeAPIKey:
+		for id, key := range FieldAPIKeys(fields) {
+			toID[key] = id
+		}
+	case FieldKeyTypeName:
+		for _, field := range fields {
+			toID[field.Name().String()] = field.ID().String()
+		}
+	default:
+		for _, field := range fields {
+			toID[field.ID().String()] = field.ID().String()
+		}
+	}
+
+	fieldIDs := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		id, ok := toID[key]
+		if !ok {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+				"message": "投影中的字段不存在",
+				"field":   key,
+			})
+		}
+		if !seen[id] {
+			seen[id] = true
+			fieldIDs = append(fieldIDs, id)
+		}
+	}
+	return fieldIDs, nil
+}
+
+// ApplyFieldKeyType 将记录数据（及展开内容、展示文本）的键从字段ID转换为 keyType 指定的键
+func (s *RecordService) ApplyFieldKeyType(ctx context.Context, tableID, keyType string, records ...*dto.RecordResponse) error {
+	if keyType == FieldKeyTypeID || len(records) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段列表失败")
+	}
+	var toKey map[string]string
+	if keyType == FieldKeyTypeAPIKey {
+		toKey = FieldAPIKeys(fields)
+	} else {
+		toKey = make(map[string]string, len(fields))
+		for _, field := range fields {
+			toKey[field.ID().String()] = field.Name().String()
+		}
+	}
+
+	rekey := func(data map[string]interface{}) map[string]interface{} {
+		if data == nil {
+			return nil
+		}
+		out := make(map[string]interface{}, len(data))
+		for id, value := range data {
+			if key, ok := toKey[id]; ok {
+				out[key] = value
+			} else {
+				out[id] = value
+			}
+		}
+		return out
+	}
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		record.Data = rekey(record.Data)
+		record.Expanded = rekey(record.Expanded)
+		if record.Formatted != nil {
+			formatted := make(map[string]string, len(record.Formatted))
+			for id, text := range record.Formatted {
+				if key, ok := toKey[id]; ok {
+					formatted[key] = text
+				} else {
+					formatted[id] = text
+				}
+			}
+			record.Formatted = formatted
+		}
+	}
+	return nil
+}
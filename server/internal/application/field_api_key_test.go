@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+func TestFieldAPIKeys(t *testing.T) {
+	status := newTemplateTestField(t, "Status", fieldVO.TypeText)
+	renamed := newTemplateTestField(t, "Renamed", fieldVO.TypeText)
+	key, _ := fieldVO.NewAPIKey("status")
+	renamed.SetAPIKey(key) // 已有字段占用 status，未回填的字段追加序号
+
+	keys := FieldAPIKeys([]*fieldEntity.Field{status, renamed})
+	if keys[renamed.ID().String()] != "status" || keys[status.ID().String()] != "status_2" {
+		t.Errorf("API 键分配错误: %v", keys)
+	}
+}
+
+func TestParseFieldKeyType(t *testing.T) {
+	if keyType, err := ParseFieldKeyType(""); err != nil || keyType != FieldKeyTypeID {
+		t.Errorf("默认应为字段ID，得到 %q, %v", keyType, err)
+	}
+	if _, err := ParseFieldKeyType("dbFieldName"); err == nil {
+		t.Error("未知的键类型应返回错误")
+	}
+}
+
+func TestRecordFieldKeys(t *testing.T) {
+	name := newTemplateTestField(t, "Customer Name", fieldVO.TypeText)
+	amount := newTemplateTestField(t, "金额", fieldVO.TypeNumber)
+	svc := &RecordService{fieldRepo: &stubLockFieldRepo{fields: []*fieldEntity.Field{name, amount}}}
+	ctx := context.Background()
+
+	row := map[string]interface{}{"customer_name": "Acme", "jine": 10}
+	if err := svc.ResolveFieldKeys(ctx, "tbl1", FieldKeyTypeAPIKey, row); err != nil {
+		t.Fatalf("ResolveFieldKeys: %v", err)
+	}
+	if row[name.ID().String()] != "Acme" || row[amount.ID().String()] != 10 || len(row) != 2 {
+		t.Errorf("应转换为字段ID，得到 %v", row)
+	}
+
+	err := svc.ResolveFieldKeys(ctx, "tbl1", FieldKeyTypeAPIKey, map[string]interface{}{"unknown": 1})
+	var appErr *pkgerrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != pkgerrors.ErrValidationFailed.Code {
+		t.Errorf("未知的 API 键应返回校验错误，得到 %v", err)
+	}
+
+	record := &dto.RecordResponse{Data: map[string]interface{}{name.ID().String(): "Acme"}}
+	if err := svc.ApplyFieldKeyType(ctx, "tbl1", FieldKeyTypeAPIKey, record); err != nil {
+		t.Fatalf("ApplyFieldKeyType: %v", err)
+	}
+	if record.Data["customer_name"] != "Acme" {
+		t.Errorf("应按 API 键返回，得到 %v", record.Data)
+	}
+	record = &dto.RecordResponse{Data: map[string]interface{}{name.ID().String(): "Acme"}}
+	if err := svc.ApplyFieldKeyType(ctx, "tbl1", FieldKeyTypeName, record); err != nil || record.Data["Customer Name"] != "Acme" {
+		t.Errorf("应按字段名称返回，得到 %v", record.Data)
+	}
+}
+
+func TestResolveProjection(t *testing.T) {
+	name := newTemplateTestField(t, "Customer Name", fieldVO.TypeText)
+	amount := newTemplateTestField(t, "金额", fieldVO.TypeNumber)
+	svc := &RecordService{fieldRepo: &stubLockFieldRepo{fields: []*fieldEntity.Field{name, amount}}}
+	ctx := context.Background()
+
+	if ids, err := svc.ResolveProjection(ctx, "tbl1", FieldKeyTypeID, nil); err != nil || ids != nil {
+		t.Errorf("未指定投影时应返回 nil，得到 %v, %v", ids, err)
+	}
+	ids, err := svc.ResolveProjection(ctx, "tbl1", FieldKeyTypeName, []string{"金额", "Customer Name", "金额"})
+	if err != nil || len(ids) != 2 || ids[0] != amount.ID().String() || ids[1] != name.ID().String() {
+		t.Errorf("应按名称转换为字段ID并去重，得到 %v, %v", ids, err)
+	}
+	if ids, err := svc.ResolveProjection(ctx, "tbl1", FieldKeyTypeAPIKey, []string{"customer_name"}); err != nil || len(ids) != 1 || ids[0] != name.ID().String() {
+		t.Errorf("应按 API 键转换为字段ID，得到 %v, %v", ids, err)
+	}
+	if _, err := svc.ResolveProjection(ctx, "tbl1", FieldKeyTypeID, []string{"Customer Name"}); err == nil {
+		t.Error("fieldKeyType 为 id 时按名称投影应返回校验错误")
+	}
+}
@@ -2,18 +2,25 @@ package application
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/dependency"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/factory"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/optionschema"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/etag"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
@@ -27,8 +34,23 @@ type FieldService struct {
 	broadcaster  FieldBroadcaster                      // ✨ WebSocket广播器
 	tableRepo    tableRepo.TableRepository             // ✅ 表格仓储（获取Base ID）
 	dbProvider   database.DBProvider                   // ✅ 数据库提供者（列管理）
+
+	schemaMigrator    *SchemaMigrationService // ✨ 结构变更编排（未设置时请求内直接执行 DDL）
+	encryptionEnabled bool                    // 是否已配置字段静态加密
+	changeFeed        *ChangeFeedService      // ✨ 变更流发件箱
+	crossBaseLinks    *CrossBaseLinkService   // ✨ 关联目标校验（支持跨 Base）
+	maintenance       *MaintenanceService     // ✨ 维护模式（只读时拒绝结构变更）
+	schemaLocker      SchemaLocker            // ✨ 表结构锁（同一张表的结构变更串行执行）
+}
+
+// SchemaLocker 按名称加锁（多实例之间互斥），返回释放函数
+type SchemaLocker interface {
+	Lock(ctx context.Context, key string) (func(), error)
 }
 
+// schemaLockKey context 中已持有表结构锁的表（同一请求内嵌套的结构变更不再重复加锁）
+type schemaLockKey struct{}
+
 // FieldBroadcaster 字段变更广播器接口
 type FieldBroadcaster interface {
 	BroadcastFieldCreate(tableID string, field *entity.Field)
@@ -54,13 +76,93 @@ func NewFieldService(
 	}
 }
 
+// SetEncryptionEnabled 设置是否允许创建加密字段
+func (s *FieldService) SetEncryptionEnabled(enabled bool) {
+	s.encryptionEnabled = enabled
+}
+
+// SetSchemaMigrator 设置结构变更编排服务（字段增删改的 DDL 与回填改由后台任务执行）
+func (s *FieldService) SetSchemaMigrator(migrator *SchemaMigrationService) {
+	s.schemaMigrator = migrator
+	migrator.onFieldChanged = s.publishFieldUpdate
+}
+
 // SetBroadcaster 设置广播器（用于延迟注入）
 func (s *FieldService) SetBroadcaster(broadcaster FieldBroadcaster) {
 	s.broadcaster = broadcaster
 }
 
+// SetChangeFeed 设置变更流服务（字段变更写入发件箱）
+func (s *FieldService) SetChangeFeed(changeFeed *ChangeFeedService) {
+	s.changeFeed = changeFeed
+}
+
+// SetCrossBaseLinkService 设置关联目标校验服务（创建关联字段时校验目标表与跨 Base 权限）
+func (s *FieldService) SetCrossBaseLinkService(crossBaseLinks *CrossBaseLinkService) {
+	s.crossBaseLinks = crossBaseLinks
+}
+
+// SetMaintenanceService 设置维护模式服务
+func (s *FieldService) SetMaintenanceService(maintenance *MaintenanceService) {
+	s.maintenance = maintenance
+}
+
+// SetSchemaLocker 设置表结构锁（未设置时不加锁）
+func (s *FieldService) SetSchemaLocker(locker SchemaLocker) {
+	s.schemaLocker = locker
+}
+
+// LockSchema 持有表结构锁，返回持有锁的 context 与释放函数
+// 字段增删改与重排都在锁内执行；If-Match 校验与之后的结构变更使用返回的 context，
+// 校验通过后表结构不会在变更前被其他请求（包括其他实例）修改。context 已持有该表的锁时直接返回
+func (s *FieldService) LockSchema(ctx context.Context, tableID string) (context.Context, func(), error) {
+	held, _ := ctx.Value(schemaLockKey{}).(map[string]bool)
+	if s.schemaLocker == nil || held[tableID] {
+		return ctx, func() {}, nil
+	}
+	unlock, err := s.schemaLocker.Lock(ctx, "schema:"+tableID)
+	if err != nil {
+		return nil, nil, pkgerrors.Database(err, "获取表结构锁失败")
+	}
+	next := make(map[string]bool, len(held)+1)
+	for id := range held {
+		next[id] = true
+	}
+	next[tableID] = true
+	return context.WithValue(ctx, schemaLockKey{}, next), unlock, nil
+}
+
+// checkMaintenance 所在的实例、工作空间或 Base 维护中时拒绝结构变更
+func (s *FieldService) checkMaintenance(ctx context.Context, refs ResourceRefs) error {
+	if s.maintenance == nil {
+		return nil
+	}
+	return s.maintenance.CheckWritable(ctx, refs)
+}
+
+// recordFieldChange 将字段变更写入变更流（字段已保存，写入失败只记录日志）
+func (s *FieldService) recordFieldChange(ctx context.Context, action changefeed.Action, tableID, fieldID string, data interface{}, userID string) {
+	if s.changeFeed == nil {
+		return
+	}
+	if err := s.changeFeed.FieldChanged(ctx, action, tableID, fieldID, data, userID); err != nil {
+		logger.Warn("写入字段变更流失败",
+			logger.String("field_id", fieldID),
+			logger.ErrorField(err))
+	}
+}
+
 // CreateField 创建字段（参考原版实现逻辑）
 func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldRequest, userID string) (*dto.FieldResponse, error) {
+	if err := s.checkMaintenance(ctx, ResourceRefs{TableID: req.TableID}); err != nil {
+		return nil, err
+	}
+	ctx, unlock, lockErr := s.LockSchema(ctx, req.TableID)
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer unlock()
+
 	// 1. 验证字段名称
 	fieldName, err := valueobject.NewFieldName(req.Name)
 	if err != nil {
@@ -70,7 +172,7 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 	// 2. 检查字段名称是否重复
 	exists, err := s.fieldRepo.ExistsByName(ctx, req.TableID, fieldName, nil)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查字段名称失败: %v", err))
+		return nil, pkgerrors.Database(err, "检查字段名称失败")
 	}
 	if exists {
 		return nil, pkgerrors.ErrConflict.WithMessage(fmt.Sprintf("字段名 '%s' 已存在", req.Name))
@@ -142,6 +244,14 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		linkFieldID, lookupFieldID := s.extractLookupOptionsFromOptions(req.Options)
 		field, err = s.fieldFactory.CreateLookupField(req.TableID, req.Name, userID, linkFieldID, lookupFieldID)
 
+	case "link":
+		// Link 字段需要 linkedTableId（可以是同一空间内其他 Base 的表）
+		linkedTableID, relationship, allowMultiple := s.extractLinkOptionsFromOptions(req.Options)
+		field, err = s.fieldFactory.CreateLinkField(req.TableID, req.Name, userID, linkedTableID, relationship, false)
+		if err == nil {
+			field.Options().Link.AllowMultiple = allowMultiple
+		}
+
 	default:
 		// ✅ 使用通用方法创建字段，保留原始类型名称（如 singleLineText, longText, email 等）
 		field, err = s.fieldFactory.CreateFieldWithType(req.TableID, req.Name, req.Type, userID)
@@ -165,6 +275,13 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建字段失败: %v", err))
 	}
 
+	// 3.1 ✨ API 键：指定时校验格式与同表唯一，否则由名称生成（冲突时追加序号）
+	apiKey, err := s.newAPIKey(ctx, req.TableID, req.Name, req.APIKey, "")
+	if err != nil {
+		return nil, err
+	}
+	field.SetAPIKey(apiKey)
+
 	// 4. 设置可选属性
 	if req.Required {
 		field.SetRequired(true)
@@ -173,6 +290,22 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		field.SetUnique(true)
 	}
 
+	// 4.1 ✨ 静态加密（仅创建时可开启，物理列固定为 TEXT）
+	if getBoolFromMap(req.Options, "encrypted") {
+		if !s.encryptionEnabled {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("未配置加密主密钥，无法创建加密字段")
+		}
+		if req.Unique {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("加密字段不支持唯一约束")
+		}
+		if err := field.EnableEncryption(
+			getBoolFromMap(req.Options, "revealInLogs"),
+			getBoolFromMap(req.Options, "revealInWebhooks"),
+		); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("开启字段加密失败: %v", err))
+		}
+	}
+
     // 5. ✨ 应用通用字段配置（defaultValue, showAs, formatting 等）
     // 顶层 defaultValue 兼容：注入到 options 中
     if req.DefaultValue != nil {
@@ -181,8 +314,21 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
         }
         req.Options["defaultValue"] = req.DefaultValue
     }
+    if err := validateFieldTypeOptions(req.Type, req.Options, false); err != nil {
+        return nil, err
+    }
+    if req.Type == valueobject.TypeAI {
+        if err := s.normalizeAIPrompt(ctx, field, req.Options); err != nil {
+            return nil, err
+        }
+    }
     // 参考 Teable 的优秀设计，补充我们之前缺失的配置
     s.applyCommonFieldOptions(field, req.Options)
+	if req.Type == valueobject.TypeLink && s.crossBaseLinks != nil {
+		if err := s.crossBaseLinks.ResolveTarget(ctx, userID, req.TableID, field.Options().Link); err != nil {
+			return nil, err
+		}
+	}
 
 	// 6. 循环依赖检测（仅对虚拟字段）
 	if isVirtualFieldType(req.Type) {
@@ -201,6 +347,72 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 	nextOrder := maxOrder + 1
 	field.SetOrder(nextOrder)
 
+	// 8. ✅ 创建物理表列并保存字段元数据（完全动态表架构）
+	if s.schemaMigrator != nil && s.tableRepo != nil {
+		// 结构变更编排：请求内创建可空列，默认值回填与约束由后台任务执行
+		if err := s.addFieldWithMigration(ctx, field, userID); err != nil {
+			return nil, err
+		}
+	} else if err := s.addFieldColumnInline(ctx, req, field); err != nil {
+		return nil, err
+	}
+
+	logger.Info("字段创建成功",
+		logger.String("field_id", field.ID().String()),
+		logger.String("table_id", req.TableID),
+		logger.String("name", req.Name),
+		logger.String("type", req.Type),
+		logger.Float64("order", nextOrder),
+	)
+
+	// 9. ✨ 更新依赖图（如果是虚拟字段）
+	if s.depGraphRepo != nil && field.IsComputed() {
+		if err := s.depGraphRepo.InvalidateCache(ctx, req.TableID); err != nil {
+			logger.Warn("清除依赖图缓存失败（不影响字段创建）",
+				logger.String("table_id", req.TableID),
+				logger.ErrorField(err),
+			)
+		} else {
+			logger.Info("依赖图缓存已清除 ✨",
+				logger.String("table_id", req.TableID),
+			)
+		}
+	}
+
+	s.recordFieldChange(ctx, changefeed.ActionCreate, req.TableID, field.ID().String(), dto.FromFieldEntity(field), userID)
+
+	// 10. ✨ 实时推送字段创建事件
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastFieldCreate(req.TableID, field)
+		logger.Info("字段创建事件已广播 ✨",
+			logger.String("field_id", field.ID().String()),
+		)
+	}
+
+	return dto.FromFieldEntity(field), nil
+}
+
+// addFieldWithMigration 通过结构变更编排创建物理表列并保存字段元数据
+func (s *FieldService) addFieldWithMigration(ctx context.Context, field *entity.Field, userID string) error {
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	if err := s.schemaMigrator.AddColumn(ctx, table.BaseID(), field, userID); err != nil {
+		return err
+	}
+	s.createJSONBIndex(ctx, table.BaseID(), table.ID().String(), field)
+	s.createLocationIndex(ctx, table.BaseID(), table.ID().String(), field)
+	return nil
+}
+
+// addFieldColumnInline 请求内创建物理表列（含约束）并保存字段元数据
+func (s *FieldService) addFieldColumnInline(ctx context.Context, req dto.CreateFieldRequest, field *entity.Field) error {
 	// 8. ✅ 创建物理表列（完全动态表架构）
 	// 参考旧系统：ALTER TABLE ADD COLUMN
 	// 注意：虚拟字段也需要创建物理列来存储计算结果
@@ -208,11 +420,11 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		// 8.1 获取Table信息（需要Base ID）
 		table, err := s.tableRepo.GetByID(ctx, req.TableID)
 		if err != nil {
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
 				fmt.Sprintf("获取Table信息失败: %v", err))
 		}
 		if table == nil {
-			return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+			return pkgerrors.ErrNotFound.WithDetails("Table不存在")
 		}
 
 		baseID := table.BaseID()
@@ -244,43 +456,15 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 				logger.String("field_id", field.ID().String()),
 				logger.String("db_field_name", dbFieldName),
 				logger.ErrorField(err))
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
 				fmt.Sprintf("创建物理表列失败: %v", err))
 		}
 
-		// 8.5 为 JSONB 字段自动创建 GIN 索引
-		if dbType == "JSONB" {
-			indexName := fmt.Sprintf("idx_%s_%s_gin",
-				strings.ReplaceAll(baseID, "-", "_"),
-				strings.ReplaceAll(field.ID().String(), "-", "_"))
+		// 8.5 为 JSONB 字段自动创建 GIN 索引（加密字段不建立索引）
+		s.createJSONBIndex(ctx, baseID, tableID, field)
 
-			fullTableName := fmt.Sprintf("\"%s\".\"%s\"", baseID, tableID)
-			createIndexSQL := fmt.Sprintf(
-				`CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s jsonb_path_ops)`,
-				indexName,
-				fullTableName,
-				dbFieldName,
-			)
-
-			logger.Info("创建 JSONB GIN 索引",
-				logger.String("field_id", field.ID().String()),
-				logger.String("field_name", field.Name().String()),
-				logger.String("index_name", indexName))
-
-			// 获取底层数据库连接
-			if pgProvider, ok := s.dbProvider.(*database.PostgresProvider); ok {
-				db := pgProvider.GetDB()
-				if err := db.WithContext(ctx).Exec(createIndexSQL).Error; err != nil {
-					logger.Warn("创建 JSONB GIN 索引失败（不影响字段创建）",
-						logger.String("field_id", field.ID().String()),
-						logger.ErrorField(err))
-				} else {
-					logger.Info("✅ JSONB GIN 索引创建成功",
-						logger.String("field_id", field.ID().String()),
-						logger.String("index_name", indexName))
-				}
-			}
-		}
+		// 8.6 为位置字段创建 earthdistance 表达式索引
+		s.createLocationIndex(ctx, baseID, tableID, field)
 
 		logger.Info("✅ 物理表列创建成功",
 			logger.String("field_id", field.ID().String()),
@@ -316,40 +500,62 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 			logger.String("table_id", req.TableID),
 			logger.ErrorField(err),
 		)
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存字段失败: %v", err))
+		return pkgerrors.Database(err, "保存字段失败")
 	}
 
-	logger.Info("字段创建成功",
-		logger.String("field_id", field.ID().String()),
-		logger.String("table_id", req.TableID),
-		logger.String("name", req.Name),
-		logger.String("type", req.Type),
-		logger.Float64("order", nextOrder),
-	)
+	return nil
+}
 
-	// 9. ✨ 更新依赖图（如果是虚拟字段）
-	if s.depGraphRepo != nil && field.IsComputed() {
-		if err := s.depGraphRepo.InvalidateCache(ctx, req.TableID); err != nil {
-			logger.Warn("清除依赖图缓存失败（不影响字段创建）",
-				logger.String("table_id", req.TableID),
-				logger.ErrorField(err),
-			)
-		} else {
-			logger.Info("依赖图缓存已清除 ✨",
-				logger.String("table_id", req.TableID),
-			)
-		}
+// createJSONBIndex 为 JSONB 字段自动创建 GIN 索引（加密字段不建立索引）
+// 通过 DBProvider 创建，索引落在租户Schema与数据所在区域
+func (s *FieldService) createJSONBIndex(ctx context.Context, baseID, tableID string, field *entity.Field) {
+	if field.DBFieldType() != "JSONB" || !field.IsSearchable() || !s.dbProvider.SupportsSchema() {
+		return
 	}
 
-	// 10. ✨ 实时推送字段创建事件
-	if s.broadcaster != nil {
-		s.broadcaster.BroadcastFieldCreate(req.TableID, field)
-		logger.Info("字段创建事件已广播 ✨",
+	dbFieldName := field.DBFieldName().String()
+	indexName := strings.ToLower(fmt.Sprintf("idx_%s_%s_gin",
+		strings.ReplaceAll(baseID, "-", "_"),
+		strings.ReplaceAll(field.ID().String(), "-", "_")))
+
+	logger.Info("创建 JSONB GIN 索引",
+		logger.String("field_id", field.ID().String()),
+		logger.String("field_name", field.Name().String()),
+		logger.String("index_name", indexName))
+
+	definition := fmt.Sprintf(`USING GIN (%s jsonb_path_ops)`, dbFieldName)
+	if err := s.dbProvider.CreateIndex(ctx, baseID, tableID, indexName, definition); err != nil {
+		logger.Warn("创建 JSONB GIN 索引失败（不影响字段创建）",
 			logger.String("field_id", field.ID().String()),
-		)
+			logger.ErrorField(err))
+	} else {
+		logger.Info("✅ JSONB GIN 索引创建成功",
+			logger.String("field_id", field.ID().String()),
+			logger.String("index_name", indexName))
+	}
+}
+
+// createLocationIndex 为位置字段创建 ll_to_earth GiST 表达式索引，供半径过滤使用
+// 需要 cube 与 earthdistance 扩展（迁移时启用），不可用时只记录警告
+func (s *FieldService) createLocationIndex(ctx context.Context, baseID, tableID string, field *entity.Field) {
+	if field.Type().String() != valueobject.TypeLocation || !s.dbProvider.SupportsSchema() {
+		return
 	}
 
-	return dto.FromFieldEntity(field), nil
+	dbFieldName := field.DBFieldName().String()
+	indexName := strings.ToLower(fmt.Sprintf("idx_%s_%s_geo",
+		strings.ReplaceAll(baseID, "-", "_"),
+		strings.ReplaceAll(field.ID().String(), "-", "_")))
+	definition := fmt.Sprintf(
+		`USING GIST (ll_to_earth((%s ->> 'lat')::float8, (%s ->> 'lng')::float8))`,
+		dbFieldName, dbFieldName,
+	)
+
+	if err := s.dbProvider.CreateIndex(ctx, baseID, tableID, indexName, definition); err != nil {
+		logger.Warn("创建位置索引失败（半径过滤将全表扫描）",
+			logger.String("field_id", field.ID().String()),
+			logger.ErrorField(err))
+	}
 }
 
 // extractChoicesFromOptions 从 Options 中提取 choices（参考原版 Select 字段逻辑）
@@ -423,6 +629,25 @@ func (s *FieldService) extractRollupOptionsFromOptions(options map[string]interf
 	return linkFieldID, rollupFieldID, aggFunc
 }
 
+// extractLinkOptionsFromOptions 从 Options 中提取 Link 相关参数（关系默认多对多）
+func (s *FieldService) extractLinkOptionsFromOptions(options map[string]interface{}) (string, string, bool) {
+	if options == nil {
+		return "", "many_to_many", true
+	}
+
+	linkedTableID, _ := options["linkedTableId"].(string)
+	relationship, _ := options["relationship"].(string)
+	if relationship == "" {
+		relationship = "many_to_many"
+	}
+	allowMultiple := relationship == "many_to_many" || relationship == "one_to_many"
+	if v, ok := options["allowMultiple"].(bool); ok {
+		allowMultiple = v
+	}
+
+	return linkedTableID, relationship, allowMultiple
+}
+
 // extractLookupOptionsFromOptions 从 Options 中提取 Lookup 相关参数
 func (s *FieldService) extractLookupOptionsFromOptions(options map[string]interface{}) (string, string) {
 	if options == nil {
@@ -441,7 +666,7 @@ func (s *FieldService) GetField(ctx context.Context, fieldID string) (*dto.Field
 
 	field, err := s.fieldRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找字段失败")
 	}
 	if field == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
@@ -452,6 +677,10 @@ func (s *FieldService) GetField(ctx context.Context, fieldID string) (*dto.Field
 
 // UpdateField 更新字段
 func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.UpdateFieldRequest) (*dto.FieldResponse, error) {
+	if err := s.checkMaintenance(ctx, ResourceRefs{FieldID: fieldID}); err != nil {
+		return nil, err
+	}
+
 	// 1. 查找字段
 	id := valueobject.NewFieldID(fieldID)
 	logger.Info("🔍 UpdateField 开始查找字段",
@@ -477,7 +706,7 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		logger.Error("❌ UpdateField 查找字段失败",
 			logger.String("field_id", fieldID),
 			logger.ErrorField(err))
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找字段失败")
 	}
 	if field == nil {
 		logger.Error("❌ UpdateField 字段不存在",
@@ -485,12 +714,31 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 			logger.String("field_id_parsed", id.String()))
 		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
 	}
+
+	// ✨ 持有表结构锁后重新读取（读取到加锁之间字段可能已被其他请求修改）
+	ctx, unlock, lockErr := s.LockSchema(ctx, field.TableID())
+	if lockErr != nil {
+		return nil, lockErr
+	}
+	defer unlock()
+	if field, err = s.fieldRepo.FindByID(ctx, id); err != nil {
+		return nil, pkgerrors.Database(err, "查找字段失败")
+	}
+	if field == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
 	
 	logger.Info("✅ UpdateField 找到字段",
 		logger.String("field_id", fieldID),
 		logger.String("field_name", field.Name().String()),
 		logger.String("table_id", field.TableID()))
 
+	// ✨ 更新前的设置，保存前据此准备物理表结构变更
+	before := FieldChange{Name: field.Name().String(), Required: field.IsRequired(), Unique: field.IsUnique()}
+	if req.Type != nil {
+		before.TargetType = *req.Type
+	}
+
 	// 2. 更新名称
 	if req.Name != nil && *req.Name != "" {
 		fieldName, err := valueobject.NewFieldName(*req.Name)
@@ -501,7 +749,7 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		// 检查名称是否重复
 		exists, err := s.fieldRepo.ExistsByName(ctx, field.TableID(), fieldName, &id)
 		if err != nil {
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查字段名称失败: %v", err))
+			return nil, pkgerrors.Database(err, "检查字段名称失败")
 		}
 		if exists {
 			return nil, pkgerrors.ErrConflict.WithDetails("字段名称已存在")
@@ -519,6 +767,31 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		}
 	}
 
+	// 3.1 ✨ 更新 API 键（重命名不会改变 API 键；传空字符串时按当前名称重新生成）
+	if req.APIKey != nil && *req.APIKey != field.APIKey().String() {
+		apiKey, err := s.newAPIKey(ctx, field.TableID(), field.Name().String(), *req.APIKey, fieldID)
+		if err != nil {
+			return nil, err
+		}
+		if err := field.SetAPIKey(apiKey); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("更新 API 键失败: %v", err))
+		}
+	}
+
+	// 4.0 加密选项只能在创建时设置（切换需要重写全部历史数据）
+	if encrypted, ok := req.Options["encrypted"].(bool); ok && encrypted != field.IsEncrypted() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("字段加密选项仅可在创建字段时设置")
+	}
+	if field.IsEncrypted() {
+		options := field.Options()
+		if v, ok := req.Options["revealInLogs"].(bool); ok {
+			options.RevealInLogs = v
+		}
+		if v, ok := req.Options["revealInWebhooks"].(bool); ok {
+			options.RevealInWebhooks = v
+		}
+	}
+
     // 4. 更新Options（如公式表达式等）
     if req.Options != nil && len(req.Options) > 0 || req.DefaultValue != nil {
         // 顶层 defaultValue 兼容：注入到 options 中
@@ -596,6 +869,20 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		}
 
 		// ✨ 应用通用字段配置（defaultValue, showAs, formatting 等）
+		if err := validateFieldTypeOptions(field.Type().String(), req.Options, true); err != nil {
+			return nil, err
+		}
+		if field.Type().String() == valueobject.TypeAI {
+			if err := s.normalizeAIPrompt(ctx, field, req.Options); err != nil {
+				return nil, err
+			}
+		}
+		// 关联字段的 Base 由关联表格决定，只能在创建时确定
+		if baseID, ok := req.Options["baseId"].(string); ok && field.Type().String() == valueobject.TypeLink {
+			if options := field.Options(); options == nil || options.Link == nil || options.Link.BaseID != baseID {
+				return nil, pkgerrors.ErrValidationFailed.WithDetails("关联字段的 Base 由关联表格决定，不能修改")
+			}
+		}
 		// 参考 Teable 的优秀设计，补充我们之前缺失的配置
 		s.applyCommonFieldOptions(field, req.Options)
 	}
@@ -605,6 +892,9 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		field.SetRequired(*req.Required)
 	}
 	if req.Unique != nil {
+		if *req.Unique && field.IsEncrypted() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("加密字段不支持唯一约束")
+		}
 		field.SetUnique(*req.Unique)
 	}
 
@@ -621,13 +911,28 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		}
 	}
 
-	// 7. 保存
-	if err := s.fieldRepo.Save(ctx, field); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存字段失败: %v", err))
+	// 7. ✨ 保存（约束与类型变更同步到物理表）
+	if s.schemaMigrator != nil && s.tableRepo != nil {
+		// 结构变更编排：请求内移除约束，回填、添加约束与修改列类型由后台任务执行
+		if err := s.updateFieldWithMigration(ctx, field, before); err != nil {
+			return nil, err
+		}
+	} else if err := s.updateFieldColumnInline(ctx, field, before); err != nil {
+		return nil, err
 	}
 
 	logger.Info("字段更新成功", logger.String("field_id", fieldID))
 
+	s.publishFieldUpdate(ctx, field)
+
+	return dto.FromFieldEntity(field), nil
+}
+
+// publishFieldUpdate 字段更新后清除依赖图缓存、写入变更流并广播
+// （后台修改列类型完成后同样调用）
+func (s *FieldService) publishFieldUpdate(ctx context.Context, field *entity.Field) {
+	fieldID := field.ID().String()
+
 	// 8. ✨ 清除依赖图缓存（如果是虚拟字段）
 	if s.depGraphRepo != nil && field.IsComputed() {
 		if err := s.depGraphRepo.InvalidateCache(ctx, field.TableID()); err != nil {
@@ -638,6 +943,8 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		}
 	}
 
+	s.recordFieldChange(ctx, changefeed.ActionUpdate, field.TableID(), fieldID, dto.FromFieldEntity(field), "")
+
 	// 9. ✨ 实时推送字段更新事件
 	if s.broadcaster != nil {
 		s.broadcaster.BroadcastFieldUpdate(field.TableID(), field)
@@ -645,20 +952,95 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 			logger.String("field_id", fieldID),
 		)
 	}
+}
 
-	return dto.FromFieldEntity(field), nil
+// updateFieldWithMigration 通过结构变更编排同步约束与类型变更并保存字段元数据
+func (s *FieldService) updateFieldWithMigration(ctx context.Context, field *entity.Field, before FieldChange) error {
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	update, err := s.schemaMigrator.PrepareFieldUpdate(ctx, table.BaseID(), field, before, "")
+	if err != nil {
+		return err
+	}
+	if err := s.fieldRepo.Save(ctx, field); err != nil {
+		update.Abort(ctx, err)
+		return pkgerrors.Database(err, "保存字段失败")
+	}
+	update.Commit(ctx)
+	return nil
+}
+
+// updateFieldColumnInline 请求内修改物理表约束并保存字段元数据（不支持修改类型）
+func (s *FieldService) updateFieldColumnInline(ctx context.Context, field *entity.Field, before FieldChange) error {
+	if before.TargetType != "" && before.TargetType != field.Type().String() {
+		return pkgerrors.ErrValidationFailed.WithDetails("当前部署不支持修改字段类型")
+	}
+	if (field.IsRequired() != before.Required || field.IsUnique() != before.Unique) && s.tableRepo != nil && s.dbProvider != nil {
+		table, err := s.tableRepo.GetByID(ctx, field.TableID())
+		if err != nil || table == nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails("获取Table信息失败")
+		}
+		baseID, tableID, column := table.BaseID(), field.TableID(), field.DBFieldName().String()
+		constraint := fmt.Sprintf("%s_%s_%s_unique", baseID, tableID, column)
+
+		var ddlErr error
+		switch {
+		case field.IsRequired() && !before.Required:
+			ddlErr = s.dbProvider.SetNotNull(ctx, baseID, tableID, column)
+		case !field.IsRequired() && before.Required:
+			ddlErr = s.dbProvider.DropNotNull(ctx, baseID, tableID, column)
+		}
+		if ddlErr == nil {
+			switch {
+			case field.IsUnique() && !before.Unique:
+				ddlErr = s.dbProvider.AddUniqueConstraint(ctx, baseID, tableID, column, constraint)
+			case !field.IsUnique() && before.Unique:
+				ddlErr = s.dbProvider.DropConstraint(ctx, baseID, tableID, constraint)
+			}
+		}
+		if ddlErr != nil {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("已有数据不满足字段约束: %v", ddlErr))
+		}
+	}
+	if err := s.fieldRepo.Save(ctx, field); err != nil {
+		return pkgerrors.Database(err, "保存字段失败")
+	}
+	return nil
 }
 
 // DeleteField 删除字段
 // ✅ 完全动态表架构：删除Field时删除物理表列
 // 严格按照旧系统实现
 func (s *FieldService) DeleteField(ctx context.Context, fieldID string) error {
+	if err := s.checkMaintenance(ctx, ResourceRefs{FieldID: fieldID}); err != nil {
+		return err
+	}
 	id := valueobject.NewFieldID(fieldID)
 
 	// 1. 获取字段信息（用于广播、清除缓存和删除物理列）
 	field, err := s.fieldRepo.FindByID(ctx, id)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+		return pkgerrors.Database(err, "查找字段失败")
+	}
+	if field == nil {
+		return pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+
+	// ✨ 持有表结构锁后重新读取（读取到加锁之间字段可能已被其他请求删除）
+	ctx, unlock, err := s.LockSchema(ctx, field.TableID())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if field, err = s.fieldRepo.FindByID(ctx, id); err != nil {
+		return pkgerrors.Database(err, "查找字段失败")
 	}
 	if field == nil {
 		return pkgerrors.ErrNotFound.WithDetails("字段不存在")
@@ -673,6 +1055,90 @@ func (s *FieldService) DeleteField(ctx context.Context, fieldID string) error {
 		logger.String("table_id", tableID),
 		logger.String("db_field_name", dbFieldName))
 
+	// 2. ✅ 删除字段元数据与物理表列（完全动态表架构）
+	if s.schemaMigrator != nil && s.tableRepo != nil {
+		// 结构变更编排：请求内删除元数据，物理列由后台任务删除
+		if err := s.dropFieldWithMigration(ctx, field); err != nil {
+			return err
+		}
+	} else if err := s.dropFieldColumnInline(ctx, field); err != nil {
+		return err
+	}
+
+	logger.Info("✅ 字段删除成功（含物理表列）",
+		logger.String("field_id", fieldID),
+		logger.String("table_id", tableID))
+
+	s.recordFieldChange(ctx, changefeed.ActionDelete, tableID, fieldID, nil, "")
+
+	// 缓存失效与广播在事务提交后执行，回滚时客户端不会看到字段消失
+	afterCommit(ctx, func() {
+		// 4. ✨ 清除依赖图缓存（如果是虚拟字段）
+		if s.depGraphRepo != nil && isComputed {
+			if err := s.depGraphRepo.InvalidateCache(context.WithoutCancel(ctx), tableID); err != nil {
+				logger.Warn("清除依赖图缓存失败（不影响字段删除）",
+					logger.String("table_id", tableID),
+					logger.ErrorField(err),
+				)
+			}
+		}
+
+		// 5. ✨ 实时推送字段删除事件
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastFieldDelete(tableID, fieldID)
+			logger.Info("字段删除事件已广播 ✨",
+				logger.String("field_id", fieldID),
+			)
+		}
+	})
+
+	return nil
+}
+
+// dropFieldWithMigration 通过结构变更编排删除字段元数据与物理表列
+func (s *FieldService) dropFieldWithMigration(ctx context.Context, field *entity.Field) error {
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	return s.schemaMigrator.DropColumn(ctx, table.BaseID(), field, "")
+}
+
+// dropFieldColumnInline 请求内删除物理表列和字段元数据
+func (s *FieldService) dropFieldColumnInline(ctx context.Context, field *entity.Field) error {
+	fieldID := field.ID().String()
+	tableID := field.TableID()
+	dbFieldName := field.DBFieldName().String()
+
+	// 在调用方事务中（如级联删除）：先删除元数据，提交后再删除物理列
+	// 查询只读取元数据中存在的列，删除失败只留下不可见的孤立列
+	if pkgDatabase.InTransaction(ctx) {
+		if err := s.fieldRepo.Delete(ctx, field.ID()); err != nil {
+			return pkgerrors.Database(err, "删除字段失败")
+		}
+		if s.tableRepo == nil || s.dbProvider == nil {
+			return nil
+		}
+		table, err := s.tableRepo.GetByID(ctx, tableID)
+		if err != nil || table == nil {
+			return nil
+		}
+		afterCommit(ctx, func() {
+			if err := s.dbProvider.DropColumn(context.WithoutCancel(ctx), table.BaseID(), tableID, dbFieldName); err != nil {
+				logger.Error("删除物理表列失败（字段元数据已删除）",
+					logger.String("field_id", fieldID),
+					logger.String("db_field_name", dbFieldName),
+					logger.ErrorField(err))
+			}
+		})
+		return nil
+	}
+
 	// 2. ✅ 删除物理表列（完全动态表架构）
 	// 参考旧系统：ALTER TABLE DROP COLUMN
 	if s.tableRepo != nil && s.dbProvider != nil {
@@ -709,30 +1175,8 @@ func (s *FieldService) DeleteField(ctx context.Context, fieldID string) error {
 	}
 
 	// 3. 删除字段元数据
-	if err := s.fieldRepo.Delete(ctx, id); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除字段失败: %v", err))
-	}
-
-	logger.Info("✅ 字段删除成功（含物理表列）",
-		logger.String("field_id", fieldID),
-		logger.String("table_id", tableID))
-
-	// 4. ✨ 清除依赖图缓存（如果是虚拟字段）
-	if s.depGraphRepo != nil && isComputed {
-		if err := s.depGraphRepo.InvalidateCache(ctx, tableID); err != nil {
-			logger.Warn("清除依赖图缓存失败（不影响字段删除）",
-				logger.String("table_id", tableID),
-				logger.ErrorField(err),
-			)
-		}
-	}
-
-	// 5. ✨ 实时推送字段删除事件
-	if s.broadcaster != nil {
-		s.broadcaster.BroadcastFieldDelete(tableID, fieldID)
-		logger.Info("字段删除事件已广播 ✨",
-			logger.String("field_id", fieldID),
-		)
+	if err := s.fieldRepo.Delete(ctx, field.ID()); err != nil {
+		return pkgerrors.Database(err, "删除字段失败")
 	}
 
 	return nil
@@ -742,17 +1186,192 @@ func (s *FieldService) DeleteField(ctx context.Context, fieldID string) error {
 func (s *FieldService) ListFields(ctx context.Context, tableID string) ([]*dto.FieldResponse, error) {
 	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询字段列表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查询字段列表失败")
 	}
 
+	apiKeys := FieldAPIKeys(fields)
 	fieldList := make([]*dto.FieldResponse, 0, len(fields))
 	for _, field := range fields {
-		fieldList = append(fieldList, dto.FromFieldEntity(field))
+		resp := dto.FromFieldEntity(field)
+		resp.APIKey = apiKeys[resp.ID] // 尚未回填的字段返回临时生成的键
+		fieldList = append(fieldList, resp)
 	}
 
 	return fieldList, nil
 }
 
+// ReorderFields 按给定顺序一次性重排表中的全部字段
+// 顺序在一个事务中写入并只清除一次缓存；只对位置变化的字段写入变更流和广播更新
+func (s *FieldService) ReorderFields(ctx context.Context, tableID string, req dto.ReorderFieldsRequest, userID string) ([]*dto.FieldResponse, error) {
+	ctx, unlock, err := s.LockSchema(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段列表失败")
+	}
+	if len(req.FieldIDs) != len(fields) {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("fieldIds 须包含表中全部 %d 个字段", len(fields)))
+	}
+
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	ids := make([]valueobject.FieldID, 0, len(req.FieldIDs))
+	seen := make(map[string]bool, len(req.FieldIDs))
+	for _, id := range req.FieldIDs {
+		if byID[id] == nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("字段不属于该表: " + id)
+		}
+		if seen[id] {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("字段重复: " + id)
+		}
+		seen[id] = true
+		ids = append(ids, valueobject.NewFieldID(id))
+	}
+
+	if err := s.fieldRepo.BatchUpdateOrder(ctx, tableID, ids); err != nil {
+		return nil, pkgerrors.Database(err, "更新字段顺序失败")
+	}
+
+	result := make([]*dto.FieldResponse, 0, len(req.FieldIDs))
+	for i, id := range req.FieldIDs {
+		field := byID[id]
+		moved := field.Order() != float64(i)
+		if moved {
+			if err := field.UpdateOrder(float64(i)); err != nil {
+				return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+			}
+		}
+		resp := dto.FromFieldEntity(field)
+		result = append(result, resp)
+		if !moved {
+			continue
+		}
+		s.recordFieldChange(ctx, changefeed.ActionUpdate, tableID, id, resp, userID)
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastFieldUpdate(tableID, field)
+		}
+	}
+	return result, nil
+}
+
+// ListFieldsWithETag 列出表格的所有字段并返回表结构 ETag（字段列表内容摘要）
+// 字段列表经带缓存的仓储读取，ETag 与缓存中的字段列表保持一致
+func (s *FieldService) ListFieldsWithETag(ctx context.Context, tableID string) ([]*dto.FieldResponse, string, error) {
+	fields, err := s.ListFields(ctx, tableID)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, "", pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("序列化字段列表失败: %v", err))
+	}
+	return fields, etag.Digest(data), nil
+}
+
+// SchemaVersion 表结构版本（未启用变更流时为 0），随字段列表通过 X-Schema-Version 返回给客户端
+func (s *FieldService) SchemaVersion(ctx context.Context, tableID string) (int64, error) {
+	if s.changeFeed == nil {
+		return 0, nil
+	}
+	return s.changeFeed.FieldVersion(ctx, tableID)
+}
+
+// ListFieldsDelta 返回 since 版本之后变化的字段
+// 以变更流中的字段变更为修订记录：变更过的字段返回当前定义（按字段顺序），已不存在的字段返回ID；
+// 版本超出变更保留期（或未启用变更流）时返回全部字段并标记 Reset
+func (s *FieldService) ListFieldsDelta(ctx context.Context, tableID string, since int64) (*dto.FieldDeltaResponse, error) {
+	if s.changeFeed == nil {
+		return s.fullFieldDelta(ctx, tableID, 0)
+	}
+	changes, err := s.changeFeed.FieldChangesAfter(ctx, tableID, since)
+	if errors.Is(err, changefeed.ErrCursorExpired) {
+		version, err := s.changeFeed.FieldVersion(ctx, tableID)
+		if err != nil {
+			return nil, err
+		}
+		return s.fullFieldDelta(ctx, tableID, version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.FieldDeltaResponse{Version: since, Fields: []*dto.FieldResponse{}, Deleted: []string{}}
+	if len(changes) == 0 {
+		return resp, nil
+	}
+	resp.Version = changes[len(changes)-1].Seq
+
+	// 先确定版本再读取字段：读取期间的变更序号大于返回的版本，下次增量会再次返回
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		changed[change.EntityID] = true
+	}
+	fields, err := s.ListFields(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if changed[field.ID] {
+			resp.Fields = append(resp.Fields, field)
+			delete(changed, field.ID)
+		}
+	}
+	for _, change := range changes {
+		if changed[change.EntityID] {
+			resp.Deleted = append(resp.Deleted, change.EntityID)
+			delete(changed, change.EntityID)
+		}
+	}
+	return resp, nil
+}
+
+// fullFieldDelta 全量字段响应（客户端整体替换本地字段列表）
+func (s *FieldService) fullFieldDelta(ctx context.Context, tableID string, version int64) (*dto.FieldDeltaResponse, error) {
+	fields, err := s.ListFields(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FieldDeltaResponse{Version: version, Reset: true, Fields: fields, Deleted: []string{}}, nil
+}
+
+// CheckSchemaPrecondition 校验 If-Match 与表结构 ETag 是否一致（ifMatch 为空时不校验）
+// 需与之后的结构变更使用 LockSchema 返回的同一个 context，否则校验通过后表结构仍可能被修改
+func (s *FieldService) CheckSchemaPrecondition(ctx context.Context, tableID, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	_, current, err := s.ListFieldsWithETag(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if etag.Match(ifMatch, current) {
+		return nil
+	}
+
+	// 缓存可能落后于数据库（如多实例本地缓存），清除后按最新表结构再比较一次
+	if invalidator, ok := s.fieldRepo.(interface {
+		InvalidateTable(ctx context.Context, tableID string)
+	}); ok {
+		invalidator.InvalidateTable(ctx, tableID)
+		if _, current, err = s.ListFieldsWithETag(ctx, tableID); err != nil {
+			return err
+		}
+		if etag.Match(ifMatch, current) {
+			return nil
+		}
+	}
+	return pkgerrors.ErrPreconditionFailed.WithDetails(map[string]interface{}{
+		"table_id": tableID,
+		"etag":     current,
+	})
+}
+
 // checkCircularDependency 检测循环依赖
 // 在创建或更新虚拟字段（formula, rollup, lookup）时调用
 func (s *FieldService) checkCircularDependency(ctx context.Context, tableID string, newField *entity.Field) error {
@@ -871,6 +1490,39 @@ func (s *FieldService) extractFormulaDependencies(field *entity.Field) []string
 	return dependencies
 }
 
+// normalizeAIPrompt 校验 AI 字段的提示词并将字段引用统一为字段ID（允许按名称引用，字段改名后提示词仍然有效）
+// 提示词不能引用字段自身或其他 AI 字段，避免生成结果互相触发
+func (s *FieldService) normalizeAIPrompt(ctx context.Context, field *entity.Field, reqOptions map[string]interface{}) error {
+	prompt, ok := reqOptions["prompt"].(string)
+	if !ok {
+		return nil
+	}
+	if err := aifield.ValidatePrompt(prompt); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("提示词无效: %v", err))
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.Database(err, "获取字段列表失败")
+	}
+	normalized, err := aifield.NormalizePrompt(prompt, func(ref string) (string, error) {
+		target := s.findFieldByNameOrID(fields, ref)
+		switch {
+		case target == nil:
+			return "", fmt.Errorf("字段 %s 不存在", ref)
+		case target.ID().String() == field.ID().String():
+			return "", fmt.Errorf("不能引用字段自身")
+		case target.Type().String() == valueobject.TypeAI:
+			return "", fmt.Errorf("不能引用其他 AI 字段 %s", target.Name().String())
+		}
+		return target.ID().String(), nil
+	})
+	if err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("提示词无效: %v", err))
+	}
+	reqOptions["prompt"] = normalized
+	return nil
+}
+
 // findFieldByNameOrID 通过名称或ID查找字段
 func (s *FieldService) findFieldByNameOrID(fields []*entity.Field, nameOrID string) *entity.Field {
 	// 先尝试按ID查找
@@ -947,6 +1599,38 @@ func (s *FieldService) GetFieldIDsByNames(ctx context.Context, tableID string, f
 	return fieldIDs, nil
 }
 
+// validateFieldTypeOptions 按字段类型的选项 Schema 校验选项取值（见 optionschema）
+// partial 为 true 时用于更新字段：只校验给出的选项，顶层必填项可以省略
+func validateFieldTypeOptions(fieldType string, reqOptions map[string]interface{}, partial bool) error {
+	var violations []optionschema.Violation
+	if partial {
+		violations = optionschema.ValidatePartial(fieldType, reqOptions)
+	} else {
+		violations = optionschema.Validate(fieldType, reqOptions)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return pkgerrors.ErrValidationFailed.WithMessage(fmt.Sprintf("字段选项无效: %s", violations[0])).WithDetails(map[string]interface{}{
+		"field_type": fieldType,
+		"violations": violations,
+	})
+}
+
+// OptionSchemas 返回全部字段类型的选项 Schema，供客户端渲染选项编辑器
+func (s *FieldService) OptionSchemas() map[string]*optionschema.Schema {
+	return optionschema.All()
+}
+
+// OptionSchema 返回字段类型的选项 Schema
+func (s *FieldService) OptionSchema(fieldType string) (*optionschema.Schema, error) {
+	schema, ok := optionschema.For(fieldType)
+	if !ok {
+		return nil, pkgerrors.ErrInvalidFieldType.WithDetails(map[string]interface{}{"type": fieldType})
+	}
+	return schema, nil
+}
+
 // applyCommonFieldOptions 应用通用字段配置（defaultValue, showAs, formatting 等）
 // 参考 Teable 的设计，补充我们之前缺失的配置
 func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions map[string]interface{}) {
@@ -971,12 +1655,13 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 	// 2. 应用通用的 Formatting 配置
 	if formattingData, ok := reqOptions["formatting"].(map[string]interface{}); ok {
 		formatting := &valueobject.FormattingOptions{
-			Type:       getStringFromMap(formattingData, "type"),
-			DateFormat: getStringFromMap(formattingData, "dateFormat"),
-			TimeFormat: getStringFromMap(formattingData, "timeFormat"),
-			TimeZone:   getStringFromMap(formattingData, "timeZone"),
-			Currency:   getStringFromMap(formattingData, "currency"),
-			ShowCommas: getBoolFromMap(formattingData, "showCommas"),
+			Type:           getStringFromMap(formattingData, "type"),
+			DateFormat:     getStringFromMap(formattingData, "dateFormat"),
+			TimeFormat:     getStringFromMap(formattingData, "timeFormat"),
+			TimeZone:       getStringFromMap(formattingData, "timeZone"),
+			Currency:       getStringFromMap(formattingData, "currency"),
+			ShowCommas:     getBoolFromMap(formattingData, "showCommas"),
+			DurationFormat: getStringFromMap(formattingData, "durationFormat"),
 		}
 		if precision, ok := formattingData["precision"].(float64); ok {
 			p := int(precision)
@@ -1019,6 +1704,57 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 			options.Select.PreventAutoNewOptions = preventAuto
 		}
 
+	case "duration":
+		if options.Duration == nil {
+			options.Duration = &valueobject.DurationOptions{}
+		}
+		// Format（显示格式，存储始终为秒数）
+		if format, ok := reqOptions["format"].(string); ok {
+			options.Duration.Format = format
+		}
+
+	case "rating":
+		if options.Rating == nil {
+			options.Rating = &valueobject.RatingOptions{Max: valueobject.DefaultRatingMax}
+		}
+		if max, ok := reqOptions["max"].(float64); ok {
+			options.Rating.Max = int(max)
+		}
+		if icon, ok := reqOptions["icon"].(string); ok {
+			options.Rating.Icon = icon
+		}
+		if color, ok := reqOptions["color"].(string); ok {
+			options.Rating.Color = color
+		}
+
+	case "progress":
+		if options.Progress == nil {
+			options.Progress = &valueobject.ProgressOptions{}
+		}
+		if color, ok := reqOptions["color"].(string); ok {
+			options.Progress.Color = color
+		}
+		if showValue, ok := reqOptions["showValue"].(bool); ok {
+			options.Progress.ShowValue = showValue
+		}
+
+	case "ai":
+		if options.AI == nil {
+			options.AI = &valueobject.AIOptions{}
+		}
+		if provider, ok := reqOptions["provider"].(string); ok {
+			options.AI.Provider = provider
+		}
+		if model, ok := reqOptions["model"].(string); ok {
+			options.AI.Model = model
+		}
+		if prompt, ok := reqOptions["prompt"].(string); ok {
+			options.AI.Prompt = prompt
+		}
+		if config, ok := reqOptions["config"].(map[string]interface{}); ok {
+			options.AI.Config = config
+		}
+
 	case "date", "datetime":
 		if options.Date == nil {
 			options.Date = &valueobject.DateOptions{}
@@ -1027,6 +1763,13 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 		if defaultValue, ok := reqOptions["defaultValue"].(string); ok {
 			options.Date.DefaultValue = &defaultValue
 		}
+		// TimeZone / UseSameTimeZone
+		if timeZone, ok := reqOptions["timeZone"].(string); ok {
+			options.Date.TimeZone = timeZone
+		}
+		if useSame, ok := reqOptions["useSameTimeZone"].(bool); ok {
+			options.Date.UseSameTimeZone = useSame
+		}
 
 	case "formula":
 		if options.Formula != nil {
@@ -1045,12 +1788,13 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 			// Formatting
 			if formattingData, ok := reqOptions["formatting"].(map[string]interface{}); ok {
 				formatting := &valueobject.FormattingOptions{
-					Type:       getStringFromMap(formattingData, "type"),
-					DateFormat: getStringFromMap(formattingData, "dateFormat"),
-					TimeFormat: getStringFromMap(formattingData, "timeFormat"),
-					TimeZone:   getStringFromMap(formattingData, "timeZone"),
-					Currency:   getStringFromMap(formattingData, "currency"),
-					ShowCommas: getBoolFromMap(formattingData, "showCommas"),
+					Type:           getStringFromMap(formattingData, "type"),
+					DateFormat:     getStringFromMap(formattingData, "dateFormat"),
+					TimeFormat:     getStringFromMap(formattingData, "timeFormat"),
+					TimeZone:       getStringFromMap(formattingData, "timeZone"),
+					Currency:       getStringFromMap(formattingData, "currency"),
+					ShowCommas:     getBoolFromMap(formattingData, "showCommas"),
+					DurationFormat: getStringFromMap(formattingData, "durationFormat"),
 				}
 				if precision, ok := formattingData["precision"].(float64); ok {
 					p := int(precision)
@@ -1077,12 +1821,13 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 			// Formatting
 			if formattingData, ok := reqOptions["formatting"].(map[string]interface{}); ok {
 				formatting := &valueobject.FormattingOptions{
-					Type:       getStringFromMap(formattingData, "type"),
-					DateFormat: getStringFromMap(formattingData, "dateFormat"),
-					TimeFormat: getStringFromMap(formattingData, "timeFormat"),
-					TimeZone:   getStringFromMap(formattingData, "timeZone"),
-					Currency:   getStringFromMap(formattingData, "currency"),
-					ShowCommas: getBoolFromMap(formattingData, "showCommas"),
+					Type:           getStringFromMap(formattingData, "type"),
+					DateFormat:     getStringFromMap(formattingData, "dateFormat"),
+					TimeFormat:     getStringFromMap(formattingData, "timeFormat"),
+					TimeZone:       getStringFromMap(formattingData, "timeZone"),
+					Currency:       getStringFromMap(formattingData, "currency"),
+					ShowCommas:     getBoolFromMap(formattingData, "showCommas"),
+					DurationFormat: getStringFromMap(formattingData, "durationFormat"),
 				}
 				if precision, ok := formattingData["precision"].(float64); ok {
 					p := int(precision)
@@ -1097,12 +1842,13 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 			// Formatting
 			if formattingData, ok := reqOptions["formatting"].(map[string]interface{}); ok {
 				formatting := &valueobject.FormattingOptions{
-					Type:       getStringFromMap(formattingData, "type"),
-					DateFormat: getStringFromMap(formattingData, "dateFormat"),
-					TimeFormat: getStringFromMap(formattingData, "timeFormat"),
-					TimeZone:   getStringFromMap(formattingData, "timeZone"),
-					Currency:   getStringFromMap(formattingData, "currency"),
-					ShowCommas: getBoolFromMap(formattingData, "showCommas"),
+					Type:           getStringFromMap(formattingData, "type"),
+					DateFormat:     getStringFromMap(formattingData, "dateFormat"),
+					TimeFormat:     getStringFromMap(formattingData, "timeFormat"),
+					TimeZone:       getStringFromMap(formattingData, "timeZone"),
+					Currency:       getStringFromMap(formattingData, "currency"),
+					ShowCommas:     getBoolFromMap(formattingData, "showCommas"),
+					DurationFormat: getStringFromMap(formattingData, "durationFormat"),
 				}
 				if precision, ok := formattingData["precision"].(float64); ok {
 					p := int(precision)
@@ -1163,6 +1909,24 @@ func (s *FieldService) applyCommonFieldOptions(field *entity.Field, reqOptions m
 		}
 	}
 
+	// ✨ 自定义字段类型：通用配置以外的选项保存到 Custom（更新时逐键合并，null 表示清除）
+	if valueobject.IsCustomType(fieldType) {
+		if options.Custom == nil {
+			options.Custom = make(map[string]interface{})
+		}
+		for key, value := range reqOptions {
+			switch key {
+			case "showAs", "formatting", "encrypted", "revealInLogs", "revealInWebhooks":
+				continue
+			}
+			if value == nil {
+				delete(options.Custom, key)
+				continue
+			}
+			options.Custom[key] = value
+		}
+	}
+
 	// 更新字段的 options
 	field.UpdateOptions(options)
 }
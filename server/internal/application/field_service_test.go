@@ -0,0 +1,157 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+)
+
+type stubReorderFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields  []*fieldEntity.Field
+	batches [][]string
+}
+
+func (r *stubReorderFieldRepo) FindByTableID(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	return r.fields, nil
+}
+
+func (r *stubReorderFieldRepo) BatchUpdateOrder(ctx context.Context, tableID string, fieldIDs []fieldVO.FieldID) error {
+	ids := make([]string, 0, len(fieldIDs))
+	for _, id := range fieldIDs {
+		ids = append(ids, id.String())
+	}
+	r.batches = append(r.batches, ids)
+	return nil
+}
+
+type recordingFieldBroadcaster struct {
+	updated []string
+}
+
+func (b *recordingFieldBroadcaster) BroadcastFieldCreate(tableID string, field *fieldEntity.Field) {}
+
+func (b *recordingFieldBroadcaster) BroadcastFieldUpdate(tableID string, field *fieldEntity.Field) {
+	b.updated = append(b.updated, field.ID().String())
+}
+
+func (b *recordingFieldBroadcaster) BroadcastFieldDelete(tableID, fieldID string) {}
+
+func TestReorderFieldsAppliesFullOrderingOnce(t *testing.T) {
+	a := newTemplateTestField(t, "A", fieldVO.TypeText)
+	b := newTemplateTestField(t, "B", fieldVO.TypeText)
+	c := newTemplateTestField(t, "C", fieldVO.TypeText)
+	for i, field := range []*fieldEntity.Field{a, b, c} {
+		field.SetOrder(float64(i))
+	}
+	repo := &stubReorderFieldRepo{fields: []*fieldEntity.Field{a, b, c}}
+	broadcaster := &recordingFieldBroadcaster{}
+	s := NewFieldService(repo, nil, broadcaster, nil, nil)
+	ctx := context.Background()
+
+	order := []string{a.ID().String(), c.ID().String(), b.ID().String()}
+	fields, err := s.ReorderFields(ctx, "tbl1", dto.ReorderFieldsRequest{FieldIDs: order}, "usr1")
+	if err != nil {
+		t.Fatalf("重排字段失败: %v", err)
+	}
+	if len(repo.batches) != 1 || len(repo.batches[0]) != 3 || repo.batches[0][1] != c.ID().String() {
+		t.Fatalf("应一次性写入完整顺序，得到 %+v", repo.batches)
+	}
+	if fields[1].ID != c.ID().String() || c.Order() != 1 || b.Order() != 2 {
+		t.Errorf("返回的字段应按新顺序排列，得到 %+v", fields)
+	}
+	if len(broadcaster.updated) != 2 {
+		t.Errorf("只应广播位置变化的字段，得到 %v", broadcaster.updated)
+	}
+
+	invalid := [][]string{
+		{a.ID().String(), b.ID().String()},
+		{a.ID().String(), b.ID().String(), b.ID().String()},
+		{a.ID().String(), b.ID().String(), "fld_other"},
+	}
+	for _, ids := range invalid {
+		if _, err := s.ReorderFields(ctx, "tbl1", dto.ReorderFieldsRequest{FieldIDs: ids}, "usr1"); err == nil {
+			t.Errorf("不完整或重复的顺序应校验失败: %v", ids)
+		}
+	}
+	if len(repo.batches) != 1 {
+		t.Errorf("校验失败时不应写入，得到 %d 次写入", len(repo.batches))
+	}
+}
+
+// stubFieldChangeRepo 内存中的字段变更，序号不大于 oldest 的变更视为已清理
+type stubFieldChangeRepo struct {
+	changefeed.Repository
+	changes []*changefeed.Change
+	oldest  int64
+}
+
+func (r *stubFieldChangeRepo) ListAfter(ctx context.Context, baseID string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if filter.After > 0 && r.oldest > filter.After+1 {
+		return nil, changefeed.ErrCursorExpired
+	}
+	var changes []*changefeed.Change
+	for _, change := range r.changes {
+		if change.Seq > filter.After && len(changes) < filter.Limit {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (r *stubFieldChangeRepo) ListLatest(ctx context.Context, baseID string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if len(r.changes) == 0 {
+		return nil, nil
+	}
+	return r.changes[len(r.changes)-1:], nil
+}
+
+func TestListFieldsDelta(t *testing.T) {
+	a := newTemplateTestField(t, "A", fieldVO.TypeText)
+	b := newTemplateTestField(t, "B", fieldVO.TypeText)
+	tableName, _ := tableVO.NewTableName("订单")
+	table, _ := tableEntity.NewTable("bse1", tableName, "usr1")
+	changes := &stubFieldChangeRepo{changes: []*changefeed.Change{
+		{Seq: 3, EntityType: changefeed.EntityField, Action: changefeed.ActionUpdate, EntityID: b.ID().String()},
+		{Seq: 5, EntityType: changefeed.EntityField, Action: changefeed.ActionDelete, EntityID: "fld_removed"},
+		{Seq: 8, EntityType: changefeed.EntityField, Action: changefeed.ActionUpdate, EntityID: b.ID().String()},
+	}}
+	s := NewFieldService(&stubReorderFieldRepo{fields: []*fieldEntity.Field{a, b}}, nil, nil, nil, nil)
+	s.SetChangeFeed(NewChangeFeedService(changes, &stubSemanticTableRepo{table: table}, nil, nil, nil, config.ChangeFeedConfig{}))
+	ctx := context.Background()
+
+	version, err := s.SchemaVersion(ctx, "tbl1")
+	if err != nil || version != 8 {
+		t.Fatalf("表结构版本应为最近一次字段变更的序号，得到 %d (%v)", version, err)
+	}
+
+	delta, err := s.ListFieldsDelta(ctx, "tbl1", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delta.Reset || delta.Version != 8 || len(delta.Fields) != 1 || delta.Fields[0].ID != b.ID().String() {
+		t.Errorf("应只返回变化的字段: %+v", delta)
+	}
+	if len(delta.Deleted) != 1 || delta.Deleted[0] != "fld_removed" {
+		t.Errorf("应返回已删除的字段ID: %+v", delta.Deleted)
+	}
+
+	delta, err = s.ListFieldsDelta(ctx, "tbl1", 8)
+	if err != nil || delta.Version != 8 || len(delta.Fields) != 0 || len(delta.Deleted) != 0 {
+		t.Errorf("没有变化时应返回空增量: %+v (%v)", delta, err)
+	}
+
+	changes.oldest = 4
+	delta, err = s.ListFieldsDelta(ctx, "tbl1", 2)
+	if err != nil || !delta.Reset || delta.Version != 8 || len(delta.Fields) != 2 {
+		t.Errorf("版本超出保留期应返回全部字段: %+v (%v)", delta, err)
+	}
+}
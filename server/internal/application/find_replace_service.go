@@ -0,0 +1,441 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/findreplace"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// findReplaceFieldTypes 可查找替换的字段类型（纯文本存储，不含富文本）
+var findReplaceFieldTypes = map[string]bool{
+	fieldVO.TypeText:           true,
+	fieldVO.TypeSingleLineText: true,
+	fieldVO.TypeLongText:       true,
+	fieldVO.TypeEmail:          true,
+	fieldVO.TypeURL:            true,
+	fieldVO.TypePhone:          true,
+}
+
+// findReplaceRecordWriter 批量写入替换结果（由 RecordService 实现：逐条递增版本、推送实时事件，
+// 每批发布一个 record.batch 业务事件供 Webhook 订阅）
+type findReplaceRecordWriter interface {
+	MutateRecords(ctx context.Context, tableID string, req dto.RecordMutationBatchRequest, userID string) (*dto.RecordMutationBatchResponse, error)
+}
+
+// FindReplacePreview 查找替换预览
+type FindReplacePreview struct {
+	Scanned     int                      `json:"scanned"`
+	Matched     int                      `json:"matched"`     // 包含匹配的记录数
+	Occurrences int                      `json:"occurrences"` // 匹配次数
+	Fields      []*FindReplaceFieldCount `json:"fields"`
+	Samples     []*FindReplaceSample     `json:"samples"`
+	Truncated   bool                     `json:"truncated"` // 范围内记录超过扫描上限，统计不完整
+}
+
+// FindReplaceFieldCount 单个字段的匹配统计
+type FindReplaceFieldCount struct {
+	FieldID     string `json:"field_id"`
+	FieldName   string `json:"field_name"`
+	Matched     int    `json:"matched"`
+	Occurrences int    `json:"occurrences"`
+}
+
+// FindReplaceSample 替换示例
+type FindReplaceSample struct {
+	RecordID string `json:"record_id"`
+	FieldID  string `json:"field_id"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+}
+
+// FindReplaceService 批量查找替换服务 ✨
+// 在表（或视图过滤范围内）选定的文本字段中按字面或正则查找替换：
+// 预览在请求内扫描并返回匹配统计与示例；执行以长时操作（find_replace）在后台按记录ID分页进行，
+// 每条记录按读取时的版本写入，期间被修改的记录计为冲突并跳过；
+// 替换前后的值按记录保存，可通过 find_replace_undo 操作撤销，只还原之后未再被修改的字段
+type FindReplaceService struct {
+	repo              findreplace.Repository
+	operationRepo     operation.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	recordRepo        recordRepo.RecordRepository
+	records           findReplaceRecordWriter
+	privacyService    *PrivacyService
+	permissionService *PermissionServiceV2
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	cfg               config.FindReplaceConfig
+	now               func() time.Time
+
+	// 范围内记录ID分页查询（测试中替换）
+	pageIDs func(ctx context.Context, target *findReplaceTarget, afterID string, limit int) ([]string, int64, error)
+}
+
+// findReplaceTarget 校验后的查找替换范围
+type findReplaceTarget struct {
+	spec      *findreplace.Spec
+	baseID    string
+	matcher   *findreplace.Matcher
+	fields    []*fieldEntity.Field
+	condition clause.Expression // 视图过滤条件（未指定视图时为 nil）
+}
+
+// NewFindReplaceService 创建批量查找替换服务
+func NewFindReplaceService(
+	repo findreplace.Repository,
+	operationRepo operation.Repository,
+	tableRepository tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	viewRepository viewRepo.ViewRepository,
+	recordRepository recordRepo.RecordRepository,
+	records findReplaceRecordWriter,
+	privacyService *PrivacyService,
+	permissionService *PermissionServiceV2,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	cfg config.FindReplaceConfig,
+) *FindReplaceService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 200
+	}
+	if cfg.BatchSize > recordMutationBatchMaxOps {
+		cfg.BatchSize = recordMutationBatchMaxOps
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 100000
+	}
+	if cfg.PreviewSamples <= 0 {
+		cfg.PreviewSamples = 20
+	}
+	s := &FindReplaceService{
+		repo:              repo,
+		operationRepo:     operationRepo,
+		tableRepo:         tableRepository,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepository,
+		recordRepo:        recordRepository,
+		records:           records,
+		privacyService:    privacyService,
+		permissionService: permissionService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+	s.pageIDs = s.physicalPageIDs
+	return s
+}
+
+// Preview 预览查找替换的匹配统计与示例（不修改数据）
+func (s *FindReplaceService) Preview(ctx context.Context, userID, tableID string, spec findreplace.Spec) (*FindReplacePreview, error) {
+	spec.TableID = tableID
+	target, err := s.resolve(ctx, userID, "", &spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkProtected(ctx, target); err != nil {
+		return nil, err
+	}
+
+	preview := &FindReplacePreview{Fields: make([]*FindReplaceFieldCount, 0, len(target.fields)), Samples: []*FindReplaceSample{}}
+	counts := make(map[string]*FindReplaceFieldCount, len(target.fields))
+	for _, field := range target.fields {
+		count := &FindReplaceFieldCount{FieldID: field.ID().String(), FieldName: field.Name().String()}
+		counts[count.FieldID] = count
+		preview.Fields = append(preview.Fields, count)
+	}
+
+	scanned, truncated, err := s.scan(ctx, target, nil, func(_ int, records []*recordEntity.Record) error {
+		for _, record := range records {
+			change, occurrences := s.replace(target, record)
+			if change == nil {
+				continue
+			}
+			preview.Matched++
+			for _, field := range target.fields {
+				fieldID := field.ID().String()
+				n := occurrences[fieldID]
+				if n == 0 {
+					continue
+				}
+				counts[fieldID].Matched++
+				counts[fieldID].Occurrences += n
+				preview.Occurrences += n
+				if len(preview.Samples) < s.cfg.PreviewSamples {
+					preview.Samples = append(preview.Samples, &FindReplaceSample{
+						RecordID: change.RecordID,
+						FieldID:  fieldID,
+						Before:   change.Before[fieldID].(string),
+						After:    change.After[fieldID].(string),
+					})
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	preview.Scanned, preview.Truncated = scanned, truncated
+	return preview, nil
+}
+
+// resolve 校验查找替换条件、目标表、字段与视图，以及用户的记录编辑权限（baseID 非空时校验表属于该Base）
+func (s *FindReplaceService) resolve(ctx context.Context, userID, baseID string, spec *findreplace.Spec) (*findReplaceTarget, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	matcher, err := spec.Compile()
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, spec.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil || (baseID != "" && table.BaseID() != baseID) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在或不属于该Base")
+	}
+	if s.permissionService != nil && !s.permissionService.CanUpdateRecordsInTable(ctx, userID, spec.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限编辑该表格的记录")
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, spec.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		if !field.IsDeleted() {
+			byID[field.ID().String()] = field
+		}
+	}
+
+	target := &findReplaceTarget{spec: spec, baseID: table.BaseID(), matcher: matcher}
+	seen := make(map[string]bool, len(spec.FieldIDs))
+	for _, fieldID := range spec.FieldIDs {
+		if seen[fieldID] {
+			continue
+		}
+		seen[fieldID] = true
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, pkgerrors.ErrNotFound.WithDetails(fmt.Sprintf("字段 %s 不存在", fieldID))
+		}
+		if !findReplaceFieldTypes[field.Type().String()] || field.IsComputed() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是可编辑的文本字段", field.Name().String()))
+		}
+		if field.IsEncrypted() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("加密字段 %s 不支持查找替换", field.Name().String()))
+		}
+		target.fields = append(target.fields, field)
+	}
+
+	if spec.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, spec.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.TableID() != spec.TableID {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在或不属于该表格")
+		}
+		condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+		if err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("视图过滤条件无效: %v", err))
+		}
+		target.condition = condition
+	}
+	return target, nil
+}
+
+// checkProtected 拒绝当前角色下被脱敏的字段（预览示例与正则匹配会泄露明文），只在请求内校验
+func (s *FindReplaceService) checkProtected(ctx context.Context, target *findReplaceTarget) error {
+	if s.privacyService == nil {
+		return nil
+	}
+	protected := s.privacyService.ProtectedFieldIDs(ctx, target.spec.TableID)
+	for _, field := range target.fields {
+		if protected[field.ID().String()] {
+			return pkgerrors.ErrForbidden.WithDetails(fmt.Sprintf("字段 %s 已脱敏，不支持查找替换", field.Name().String()))
+		}
+	}
+	return nil
+}
+
+// scan 按记录ID顺序分页读取范围内的记录，最多 MaxRecords 条；fn 的 offset 为该页首条记录的序号。
+// 按ID游标分页，替换导致记录移出视图范围时不会漏读后续记录。progress 非空时以范围内记录数作为总量
+func (s *FindReplaceService) scan(ctx context.Context, target *findReplaceTarget, progress *OperationProgress, fn func(offset int, records []*recordEntity.Record) error) (int, bool, error) {
+	scanned := 0
+	afterID := ""
+	for scanned < s.cfg.MaxRecords {
+		if err := ctx.Err(); err != nil {
+			return scanned, false, err
+		}
+		limit := s.cfg.BatchSize
+		if scanned+limit > s.cfg.MaxRecords {
+			limit = s.cfg.MaxRecords - scanned
+		}
+		ids, total, err := s.pageIDs(ctx, target, afterID, limit)
+		if err != nil {
+			return scanned, false, err
+		}
+		if afterID == "" && progress != nil {
+			if total > int64(s.cfg.MaxRecords) {
+				total = int64(s.cfg.MaxRecords)
+			}
+			progress.SetTotal(total)
+		}
+		if len(ids) == 0 {
+			return scanned, false, nil
+		}
+		records, err := s.loadRecords(ctx, target.spec.TableID, ids)
+		if err != nil {
+			return scanned, false, err
+		}
+		if err := fn(scanned, records); err != nil {
+			return scanned, false, err
+		}
+		scanned += len(ids)
+		afterID = ids[len(ids)-1]
+		if len(ids) < limit {
+			return scanned, false, nil
+		}
+	}
+
+	more, _, err := s.pageIDs(ctx, target, afterID, 1)
+	if err != nil {
+		return scanned, false, err
+	}
+	return scanned, len(more) > 0, nil
+}
+
+// physicalPageIDs 在物理表上按视图过滤条件读取 afterID 之后的一页记录ID；
+// 第一页（afterID 为空）同时返回范围内的记录总数
+func (s *FindReplaceService) physicalPageIDs(ctx context.Context, target *findReplaceTarget, afterID string, limit int) ([]string, int64, error) {
+	db := s.dataDB(ctx, target.baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(target.baseID, target.spec.TableID))
+	if target.condition != nil {
+		db = db.Where(target.condition)
+	}
+	var total int64
+	if afterID == "" {
+		if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, 0, pkgerrors.Database(err, "统计记录失败")
+		}
+	} else {
+		db = db.Where("__id > ?", afterID)
+	}
+	var ids []string
+	if err := db.Order("__id").Limit(limit).Pluck("__id", &ids).Error; err != nil {
+		return nil, 0, pkgerrors.Database(err, "查询记录失败")
+	}
+	return ids, total, nil
+}
+
+func (s *FindReplaceService) loadRecords(ctx context.Context, tableID string, ids []string) ([]*recordEntity.Record, error) {
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].ID().String() < records[j].ID().String() })
+	return records, nil
+}
+
+// replace 计算一条记录的替换结果，返回变更（无匹配时为 nil）与各字段的匹配次数
+func (s *FindReplaceService) replace(target *findReplaceTarget, record *recordEntity.Record) (*findreplace.Change, map[string]int) {
+	var change *findreplace.Change
+	var occurrences map[string]int
+	for _, field := range target.fields {
+		fieldID := field.ID().String()
+		value, _ := record.Data().Get(fieldID)
+		text, ok := value.(string)
+		if !ok || text == "" {
+			continue
+		}
+		replaced, n := target.matcher.Apply(text)
+		if n == 0 || replaced == text {
+			continue
+		}
+		if change == nil {
+			change = &findreplace.Change{
+				TableID:   target.spec.TableID,
+				RecordID:  record.ID().String(),
+				Before:    map[string]interface{}{},
+				After:     map[string]interface{}{},
+				CreatedAt: s.now(),
+			}
+			occurrences = map[string]int{}
+		}
+		change.Before[fieldID] = text
+		change.After[fieldID] = replaced
+		occurrences[fieldID] = n
+	}
+	return change, occurrences
+}
+
+// write 按读取时的版本写入一批变更，返回每条变更的写入结果（与 mutations 顺序一致）
+func (s *FindReplaceService) write(ctx context.Context, tableID string, mutations []dto.RecordMutation, userID string) ([]*dto.RecordMutationResult, error) {
+	if len(mutations) == 0 {
+		return nil, nil
+	}
+	resp, err := s.records.MutateRecords(ctx, tableID, dto.RecordMutationBatchRequest{Operations: mutations}, userID)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*dto.RecordMutationResult, len(mutations))
+	for _, item := range resp.Results {
+		if item.Index >= 0 && item.Index < len(results) {
+			results[item.Index] = item
+		}
+	}
+	return results, nil
+}
+
+// decodeFindReplaceSpec 解析查找替换操作参数
+func decodeFindReplaceSpec(raw json.RawMessage) (*findreplace.Spec, error) {
+	var spec findreplace.Spec
+	if len(raw) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少查找替换参数")
+	}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("查找替换参数无效: %v", err))
+	}
+	return &spec, nil
+}
+
+// mutationFailure 条目级写入失败原因
+func mutationFailure(item *dto.RecordMutationResult) (string, bool) {
+	if item == nil || item.Error == nil {
+		return "写入失败", false
+	}
+	message := item.Error.Message
+	if details, ok := item.Error.Details.(string); ok && details != "" {
+		message = details
+	}
+	return message, item.Error.Code == pkgerrors.ErrVersionConflict.Code
+}
@@ -0,0 +1,289 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/findreplace"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryFindReplaceRepo 内存实现的查找替换变更记录仓储
+type memoryFindReplaceRepo struct {
+	changes []*findreplace.Change
+	undo    map[string]string
+}
+
+func (r *memoryFindReplaceRepo) SaveChanges(_ context.Context, changes []*findreplace.Change) error {
+	r.changes = append(r.changes, changes...)
+	return nil
+}
+
+func (r *memoryFindReplaceRepo) ListChanges(_ context.Context, operationID, afterRecordID string, limit int) ([]*findreplace.Change, error) {
+	var list []*findreplace.Change
+	for _, change := range r.changes {
+		if change.OperationID == operationID && change.RecordID > afterRecordID {
+			list = append(list, change)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].RecordID < list[j].RecordID })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r *memoryFindReplaceRepo) CountChanges(_ context.Context, operationID string) (int64, error) {
+	var n int64
+	for _, change := range r.changes {
+		if change.OperationID == operationID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memoryFindReplaceRepo) ClaimUndo(_ context.Context, operationID, undoOperationID string) (bool, error) {
+	if existing, ok := r.undo[operationID]; ok {
+		return existing == undoOperationID, nil
+	}
+	r.undo[operationID] = undoOperationID
+	return true, nil
+}
+
+func (r *memoryFindReplaceRepo) FindUndo(_ context.Context, operationID string) (string, error) {
+	return r.undo[operationID], nil
+}
+
+// stubFindReplaceOperations 只保存查找替换测试用到的操作
+type stubFindReplaceOperations struct {
+	operation.Repository
+	ops map[string]*operation.Operation
+}
+
+func (r *stubFindReplaceOperations) FindByID(_ context.Context, id string) (*operation.Operation, error) {
+	return r.ops[id], nil
+}
+
+// stubFindReplaceWriter 把批量更新写回内存记录并校验版本；conflicts 中的记录返回版本冲突
+type stubFindReplaceWriter struct {
+	records   *stubRecordRepo
+	conflicts map[string]bool
+	batches   int
+}
+
+func (w *stubFindReplaceWriter) MutateRecords(ctx context.Context, tableID string, req dto.RecordMutationBatchRequest, _ string) (*dto.RecordMutationBatchResponse, error) {
+	w.batches++
+	resp := &dto.RecordMutationBatchResponse{}
+	for i, m := range req.Operations {
+		record := w.find(tableID, m.ID)
+		if w.conflicts[m.ID] || m.Version == nil || record == nil || record.Version().Value() != int64(*m.Version) {
+			resp.Results = append(resp.Results, &dto.RecordMutationResult{Index: i, ID: m.ID, Status: "failed", Error: &dto.RecordMutationError{
+				Code: pkgerrors.ErrVersionConflict.Code, Message: pkgerrors.ErrVersionConflict.Message,
+			}})
+			continue
+		}
+		data := record.Data().ToMap()
+		for fieldID, value := range m.Fields {
+			data[fieldID] = value
+		}
+		w.records.put(tableID, m.ID, data)
+		resp.Results = append(resp.Results, &dto.RecordMutationResult{Index: i, ID: m.ID, Status: "success"})
+	}
+	return resp, nil
+}
+
+func (w *stubFindReplaceWriter) find(tableID, id string) *recordEntity.Record {
+	for _, record := range w.records.tables[tableID] {
+		if record.ID().String() == id {
+			return record
+		}
+	}
+	return nil
+}
+
+type findReplaceFixture struct {
+	service *FindReplaceService
+	repo    *memoryFindReplaceRepo
+	ops     *stubFindReplaceOperations
+	records *stubRecordRepo
+	writer  *stubFindReplaceWriter
+	fields  map[string]*fieldEntity.Field
+}
+
+func newFindReplaceFixture(t *testing.T) *findReplaceFixture {
+	t.Helper()
+	tn, _ := tableVO.NewTableName("客户")
+	table, err := tableEntity.NewTable("bse1", tn, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	fields := map[string]*fieldEntity.Field{
+		"name":  newTemplateTestField(t, "名称", fieldVO.TypeSingleLineText),
+		"notes": newTemplateTestField(t, "备注", fieldVO.TypeLongText),
+		"qty":   newTemplateTestField(t, "数量", fieldVO.TypeNumber),
+	}
+	fx := &findReplaceFixture{
+		repo:    &memoryFindReplaceRepo{undo: map[string]string{}},
+		ops:     &stubFindReplaceOperations{ops: map[string]*operation.Operation{}},
+		records: &stubRecordRepo{tables: map[string][]*recordEntity.Record{}},
+		fields:  fields,
+	}
+	fx.writer = &stubFindReplaceWriter{records: fx.records, conflicts: map[string]bool{}}
+	tables := &stubTableRepo{tables: map[string]*tableEntity.Table{"tbl1": table}}
+	list := []*fieldEntity.Field{fields["name"], fields["notes"], fields["qty"]}
+	fx.service = NewFindReplaceService(fx.repo, fx.ops, tables, &stubFieldRepo{fields: list}, nil,
+		fx.records, fx.writer, nil, nil, nil, nil, config.FindReplaceConfig{BatchSize: 2, PreviewSamples: 2})
+	fx.service.pageIDs = func(_ context.Context, _ *findReplaceTarget, afterID string, limit int) ([]string, int64, error) {
+		var ids []string
+		for _, record := range fx.records.tables["tbl1"] {
+			ids = append(ids, record.ID().String())
+		}
+		sort.Strings(ids)
+		total := int64(len(ids))
+		var page []string
+		for _, id := range ids {
+			if id > afterID && len(page) < limit {
+				page = append(page, id)
+			}
+		}
+		return page, total, nil
+	}
+
+	fx.put("rec1", map[string]interface{}{"name": "Acme 公司", "notes": "acme 的联系人"})
+	fx.put("rec2", map[string]interface{}{"name": "Globex", "notes": "ACME 子公司, acme"})
+	fx.put("rec3", map[string]interface{}{"name": "Initech", "qty": 3.0})
+	fx.put("rec4", map[string]interface{}{"name": "acme-labs"})
+	return fx
+}
+
+func (fx *findReplaceFixture) put(recordID string, values map[string]interface{}) {
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		data[fx.id(key)] = value
+	}
+	fx.records.put("tbl1", recordID, data)
+}
+
+func (fx *findReplaceFixture) id(key string) string {
+	return fx.fields[key].ID().String()
+}
+
+func (fx *findReplaceFixture) value(recordID, key string) interface{} {
+	value, _ := fx.writer.find("tbl1", recordID).Data().Get(fx.id(key))
+	return value
+}
+
+func (fx *findReplaceFixture) spec(find, replace string) findreplace.Spec {
+	return findreplace.Spec{TableID: "tbl1", FieldIDs: []string{fx.id("name"), fx.id("notes")}, Find: find, Replace: replace}
+}
+
+// run 发起并执行操作，返回操作与执行结果
+func (fx *findReplaceFixture) run(t *testing.T, executor OperationExecutor, opType operation.Type, params interface{}) (*operation.Operation, interface{}) {
+	t.Helper()
+	raw, _ := json.Marshal(params)
+	ctx := context.Background()
+	if err := executor.Prepare(ctx, "usr1", "bse1", raw); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	op := operation.NewOperation("bse1", opType, raw, "usr1")
+	result, err := executor.Execute(ctx, op, newOperationProgress(nil, op, nil))
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	op.Status = operation.StatusSucceeded
+	fx.ops.ops[op.ID] = op
+	return op, result
+}
+
+func TestFindReplacePreview(t *testing.T) {
+	fx := newFindReplaceFixture(t)
+	preview, err := fx.service.Preview(context.Background(), "usr1", "tbl1", fx.spec("acme", "Globex"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Scanned != 4 || preview.Matched != 3 || preview.Occurrences != 5 || preview.Truncated {
+		t.Errorf("预览 = %+v", preview)
+	}
+	if preview.Fields[0].Matched != 2 || preview.Fields[1].Occurrences != 3 {
+		t.Errorf("字段统计 = %+v, %+v", preview.Fields[0], preview.Fields[1])
+	}
+	if len(preview.Samples) != 2 || preview.Samples[0].RecordID != "rec1" || preview.Samples[0].After != "Globex 公司" {
+		t.Errorf("示例 = %+v", preview.Samples)
+	}
+	// 预览不修改数据
+	if fx.writer.batches != 0 || fx.value("rec1", "name") != "Acme 公司" {
+		t.Error("预览不应写入记录")
+	}
+
+	spec := fx.spec("acme", "x")
+	spec.FieldIDs = []string{fx.id("qty")}
+	if _, err := fx.service.Preview(context.Background(), "usr1", "tbl1", spec); !pkgerrors.Is(err, pkgerrors.ErrValidationFailed) {
+		t.Errorf("非文本字段应拒绝, got %v", err)
+	}
+}
+
+func TestFindReplaceExecuteAndUndo(t *testing.T) {
+	fx := newFindReplaceFixture(t)
+	fx.writer.conflicts["rec4"] = true
+
+	op, raw := fx.run(t, NewFindReplaceExecutor(fx.service), operation.TypeFindReplace, fx.spec("acme", "Globex"))
+	result := raw.(*FindReplaceResult)
+	if result.Scanned != 4 || result.Matched != 3 || result.Replaced != 2 || result.Conflicts != 1 || result.Occurrences != 4 {
+		t.Errorf("结果 = %+v", result)
+	}
+	if op.Total != 4 || op.Processed != 4 || len(op.Errors) != 1 || op.Errors[0].Index != 3 {
+		t.Errorf("进度 = %d/%d, errors = %+v", op.Processed, op.Total, op.Errors)
+	}
+	if fx.value("rec1", "name") != "Globex 公司" || fx.value("rec2", "notes") != "Globex 子公司, Globex" {
+		t.Errorf("替换后 = %v, %v", fx.value("rec1", "name"), fx.value("rec2", "notes"))
+	}
+	if fx.value("rec4", "name") != "acme-labs" {
+		t.Error("版本冲突的记录不应被修改")
+	}
+	if len(fx.repo.changes) != 2 {
+		t.Fatalf("变更记录 = %d", len(fx.repo.changes))
+	}
+
+	// 替换后 rec2 的备注又被手动修改，撤销时保留
+	fx.put("rec2", map[string]interface{}{"name": "Globex", "notes": "手动修改"})
+	undoExecutor := NewFindReplaceUndoExecutor(fx.service)
+	_, raw = fx.run(t, undoExecutor, operation.TypeFindReplaceUndo, FindReplaceUndoParams{OperationID: op.ID})
+	undo := raw.(*FindReplaceUndoResult)
+	if undo.Restored != 1 || undo.Skipped != 1 {
+		t.Errorf("撤销结果 = %+v", undo)
+	}
+	if fx.value("rec1", "name") != "Acme 公司" || fx.value("rec1", "notes") != "acme 的联系人" || fx.value("rec2", "notes") != "手动修改" {
+		t.Errorf("撤销后 = %v, %v, %v", fx.value("rec1", "name"), fx.value("rec1", "notes"), fx.value("rec2", "notes"))
+	}
+
+	params, _ := json.Marshal(FindReplaceUndoParams{OperationID: op.ID})
+	if err := undoExecutor.Prepare(context.Background(), "usr1", "bse1", params); !pkgerrors.Is(err, pkgerrors.ErrConflict) {
+		t.Errorf("重复撤销应拒绝, got %v", err)
+	}
+}
+
+func TestFindReplaceRegex(t *testing.T) {
+	fx := newFindReplaceFixture(t)
+	spec := fx.spec(`(?P<word>acme)-(\w+)`, "${2} by ${word}")
+	spec.Regex = true
+	spec.CaseSensitive = true
+	_, raw := fx.run(t, NewFindReplaceExecutor(fx.service), operation.TypeFindReplace, spec)
+	if result := raw.(*FindReplaceResult); result.Replaced != 1 {
+		t.Errorf("结果 = %+v", result)
+	}
+	if fx.value("rec4", "name") != "labs by acme" || fx.value("rec1", "name") != "Acme 公司" {
+		t.Errorf("替换后 = %v, %v", fx.value("rec4", "name"), fx.value("rec1", "name"))
+	}
+}
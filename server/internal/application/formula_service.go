@@ -13,7 +13,8 @@ import (
 // FormulaService 公式计算服务
 // 专门负责公式字段的计算
 type FormulaService struct {
-	errorService *ErrorService
+	errorService      *ErrorService
+	businessCalendars *BusinessCalendarService
 }
 
 // NewFormulaService 创建公式计算服务
@@ -23,6 +24,11 @@ func NewFormulaService(errorService *ErrorService) *FormulaService {
 	}
 }
 
+// SetBusinessCalendarService 设置工作日历服务（WORKDAY / NETWORKDAYS 按表所属空间的日历计算）
+func (s *FormulaService) SetBusinessCalendarService(calendars *BusinessCalendarService) {
+	s.businessCalendars = calendars
+}
+
 // Calculate 计算公式字段
 func (s *FormulaService) Calculate(ctx context.Context, record *entity.Record, field *fieldEntity.Field) error {
 	// 1. 获取公式配置
@@ -40,17 +46,18 @@ func (s *FormulaService) Calculate(ctx context.Context, record *entity.Record, f
 	context := s.buildCalculationContext(record, field)
 
 	// 3. 执行公式计算
-	timezone := "UTC" // 默认时区，后续可以从用户配置获取
+	timezone := calculationTimeZone(ctx, options.Formula.TimeZone)
 
 	logger.Info("calculating formula field",
 		logger.String("field_id", field.ID().String()),
 		logger.String("expression", expression))
 
-	result, err := formulaPkg.Evaluate(
+	result, err := formulaPkg.EvaluateWithCalendar(
 		expression,
 		context,
 		context, // record context (使用相同的上下文数据)
 		timezone,
+		workdayCalendar(ctx, s.businessCalendars, record.TableID()),
 	)
 
 	if err != nil {
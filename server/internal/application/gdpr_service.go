@@ -0,0 +1,583 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+const (
+	// erasureLeaseDuration 擦除任务的执行租约，执行中每保存一次进度续约一次
+	erasureLeaseDuration = 5 * time.Minute
+	// erasureProgressInterval 每处理多少条数据保存一次进度
+	erasureProgressInterval = 100
+	// erasureResumeInterval 检查中断的擦除任务的间隔
+	erasureResumeInterval = time.Minute
+)
+
+// GDPRService 数据主体请求服务（导出与擦除）✨
+//
+// 以工作空间为范围定位某个用户（用户ID或邮箱）的所有记录、评论和附件，
+// 支持导出为可移植的 zip 归档，以及异步执行擦除任务并生成可核验的报告。
+// 擦除进度定期保存，实例重启后由后台继续执行；报告摘要使用服务端密钥计算。
+// 仅空间所有者可以发起。
+type GDPRService struct {
+	repo              privacy.Repository
+	store             privacy.SubjectDataStore
+	recordRepo        recordRepo.RecordRepository
+	permissionService *PermissionServiceV2
+	storage           attachment.Storage
+	proofKey          []byte
+}
+
+// NewGDPRService 创建数据主体请求服务
+// proofSecret 为服务端密钥，用于派生擦除报告摘要的 HMAC 密钥
+func NewGDPRService(
+	repo privacy.Repository,
+	store privacy.SubjectDataStore,
+	recordRepo recordRepo.RecordRepository,
+	permissionService *PermissionServiceV2,
+	storage attachment.Storage,
+	proofSecret string,
+) *GDPRService {
+	mac := hmac.New(sha256.New, []byte(proofSecret))
+	mac.Write([]byte("luckdb-erasure-proof"))
+	return &GDPRService{
+		repo:              repo,
+		store:             store,
+		recordRepo:        recordRepo,
+		permissionService: permissionService,
+		storage:           storage,
+		proofKey:          mac.Sum(nil),
+	}
+}
+
+// StartErasureRequest 发起擦除请求
+type StartErasureRequest struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+	Mode   string `json:"mode"`
+}
+
+// SubjectDataSummary 数据主体数据概览
+type SubjectDataSummary struct {
+	SpaceID    string                        `json:"space_id"`
+	Subject    privacy.Subject               `json:"subject"`
+	Counts     map[privacy.ReferenceKind]int `json:"counts"`
+	References []privacy.SubjectReference    `json:"references"`
+}
+
+// ErasureVerifyResult 擦除报告核验结果
+type ErasureVerifyResult struct {
+	JobID       string `json:"job_id"`
+	Digest      string `json:"digest"`
+	DigestValid bool   `json:"digest_valid"`
+	Remaining   int    `json:"remaining"`
+	Verified    bool   `json:"verified"`
+}
+
+// ==================== 查询 ====================
+
+// FindSubjectData 查找空间内引用数据主体的所有数据
+func (s *GDPRService) FindSubjectData(ctx context.Context, userID, spaceID string, subject privacy.Subject) (*SubjectDataSummary, error) {
+	subject, refs, err := s.locate(ctx, userID, spaceID, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[privacy.ReferenceKind]int{
+		privacy.ReferenceRecord:     0,
+		privacy.ReferenceComment:    0,
+		privacy.ReferenceAttachment: 0,
+	}
+	for _, ref := range refs {
+		counts[ref.Kind]++
+	}
+
+	return &SubjectDataSummary{
+		SpaceID:    spaceID,
+		Subject:    subject,
+		Counts:     counts,
+		References: refs,
+	}, nil
+}
+
+// locate 校验权限并定位数据主体的数据
+func (s *GDPRService) locate(ctx context.Context, userID, spaceID string, subject privacy.Subject) (privacy.Subject, []privacy.SubjectReference, error) {
+	if err := s.ensureSpaceOwner(ctx, userID, spaceID); err != nil {
+		return subject, nil, err
+	}
+	if subject.IsEmpty() {
+		return subject, nil, pkgerrors.ErrBadRequest.WithDetails("userId 和 email 至少提供一个")
+	}
+
+	subject, err := s.store.ResolveSubject(ctx, subject)
+	if err != nil {
+		return subject, nil, pkgerrors.Database(err, "")
+	}
+	refs, err := s.store.FindReferences(ctx, spaceID, subject)
+	if err != nil {
+		return subject, nil, pkgerrors.Database(err, "")
+	}
+	return subject, refs, nil
+}
+
+// ==================== 导出 ====================
+
+// ExportSubjectData 将数据主体的数据导出为 zip 归档写入 w
+// 归档包含 manifest.json、按表分组的记录、评论、附件元数据及附件文件（原值，不做脱敏）
+func (s *GDPRService) ExportSubjectData(ctx context.Context, userID, spaceID string, subject privacy.Subject, w io.Writer) error {
+	subject, refs, err := s.locate(ctx, userID, spaceID, subject)
+	if err != nil {
+		return err
+	}
+
+	recordIDs := make(map[string][]valueobject.RecordID)
+	tableOrder := make([]string, 0)
+	comments := make([]map[string]interface{}, 0)
+	attachments := make([]map[string]interface{}, 0)
+	for _, ref := range refs {
+		switch ref.Kind {
+		case privacy.ReferenceRecord:
+			if _, ok := recordIDs[ref.TableID]; !ok {
+				tableOrder = append(tableOrder, ref.TableID)
+			}
+			recordIDs[ref.TableID] = append(recordIDs[ref.TableID], valueobject.NewRecordID(ref.RecordID))
+		case privacy.ReferenceComment:
+			comment, err := s.store.GetComment(ctx, ref.ID)
+			if err != nil {
+				return pkgerrors.Database(err, "")
+			}
+			comments = append(comments, comment)
+		case privacy.ReferenceAttachment:
+			meta, err := s.store.GetAttachment(ctx, ref.ID)
+			if err != nil {
+				return pkgerrors.Database(err, "")
+			}
+			attachments = append(attachments, meta)
+		}
+	}
+
+	archive := zip.NewWriter(w)
+
+	recordCount := 0
+	for _, tableID := range tableOrder {
+		records, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs[tableID])
+		if err != nil {
+			return pkgerrors.Database(err, "")
+		}
+		items := make([]map[string]interface{}, 0, len(records))
+		for _, record := range records {
+			items = append(items, map[string]interface{}{
+				"id":           record.ID().String(),
+				"table_id":     record.TableID(),
+				"fields":       record.Data().ToMap(),
+				"created_by":   record.CreatedBy(),
+				"updated_by":   record.UpdatedBy(),
+				"created_time": record.CreatedAt(),
+				"updated_time": record.UpdatedAt(),
+			})
+		}
+		recordCount += len(items)
+		if err := writeJSONEntry(archive, path.Join("records", tableID+".json"), items); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSONEntry(archive, "comments.json", comments); err != nil {
+		return err
+	}
+	if err := writeJSONEntry(archive, "attachments.json", attachments); err != nil {
+		return err
+	}
+
+	missingFiles := make([]string, 0)
+	for _, meta := range attachments {
+		id, _ := meta["id"].(string)
+		name, _ := meta["name"].(string)
+		filePath, _ := meta["path"].(string)
+		if err := s.copyAttachment(ctx, archive, path.Join("attachments", id, sanitizeArchiveName(name)), filePath); err != nil {
+			logger.Warn("导出附件文件失败",
+				logger.String("attachment_id", id),
+				logger.ErrorField(err))
+			missingFiles = append(missingFiles, id)
+		}
+	}
+
+	manifest := map[string]interface{}{
+		"format":         "luckdb-subject-export/v1",
+		"space_id":       spaceID,
+		"subject":        subject,
+		"generated_time": time.Now(),
+		"generated_by":   userID,
+		"counts": map[string]int{
+			"records":     recordCount,
+			"comments":    len(comments),
+			"attachments": len(attachments),
+		},
+		"missing_files": missingFiles,
+	}
+	if err := writeJSONEntry(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+
+	if err := archive.Close(); err != nil {
+		return pkgerrors.ErrInternalServer.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// copyAttachment 将附件文件写入归档
+func (s *GDPRService) copyAttachment(ctx context.Context, archive *zip.Writer, name, filePath string) error {
+	if s.storage == nil {
+		return fmt.Errorf("未配置附件存储")
+	}
+	reader, err := s.storage.Download(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, reader)
+	return err
+}
+
+// writeJSONEntry 向归档写入 JSON 文件
+func writeJSONEntry(archive *zip.Writer, name string, v interface{}) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return pkgerrors.ErrInternalServer.WithDetails(err.Error())
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return pkgerrors.ErrInternalServer.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// sanitizeArchiveName 去除文件名中的路径分隔符，防止归档路径穿越
+func sanitizeArchiveName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "file"
+	}
+	return name
+}
+
+// ==================== 擦除 ====================
+
+// StartErasure 发起擦除任务（异步执行，通过任务ID查询进度与报告）
+func (s *GDPRService) StartErasure(ctx context.Context, userID, spaceID string, req StartErasureRequest) (*privacy.ErasureJob, error) {
+	if err := s.ensureSpaceOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	subject := privacy.Subject{UserID: req.UserID, Email: req.Email}
+	if subject.IsEmpty() {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("userId 和 email 至少提供一个")
+	}
+	mode := privacy.ErasureMode(req.Mode)
+	if mode == "" {
+		mode = privacy.ErasureModeAnonymize
+	}
+	if !privacy.IsValidErasureMode(mode) {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的擦除方式: %s", req.Mode))
+	}
+
+	subject, err := s.store.ResolveSubject(ctx, subject)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	job := privacy.NewErasureJob(spaceID, subject, mode, userID)
+	leaseUntil := time.Now().Add(erasureLeaseDuration)
+	job.LockedUntil = &leaseUntil
+	if err := s.repo.SaveErasureJob(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	// 后台执行：不随请求取消，但挂在当前链路下
+	tracing.Go(ctx, "gdpr.erasure", func(ctx context.Context) {
+		s.runErasure(ctx, job)
+	})
+
+	return job, nil
+}
+
+// Start 启动后台检查：继续执行因实例重启或退出而中断的擦除任务（租约过期后领取）
+func (s *GDPRService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(erasureResumeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.resumeErasures(ctx)
+		}
+	}()
+}
+
+// resumeErasures 领取并继续执行中断的擦除任务
+func (s *GDPRService) resumeErasures(ctx context.Context) {
+	now := time.Now()
+	jobs, err := s.repo.ClaimErasureJobs(ctx, now, now.Add(erasureLeaseDuration), 5)
+	if err != nil {
+		logger.Warn("领取擦除任务失败", logger.ErrorField(err))
+		return
+	}
+	for _, job := range jobs {
+		logger.Info("继续执行中断的擦除任务",
+			logger.String("job_id", job.ID),
+			logger.String("space_id", job.SpaceID))
+		s.runErasure(ctx, job)
+	}
+}
+
+// runErasure 执行擦除任务
+// 删除模式下删除主体创建的数据，仅提及主体的数据做匿名化；完成后重新定位一次作为复查。
+// 每处理 erasureProgressInterval 条保存一次进度并续约；中断后继续执行时重新定位，
+// 已擦除的数据不会再被找到，之前失败的数据重新处理并替换报告中的结果
+func (s *GDPRService) runErasure(ctx context.Context, job *privacy.ErasureJob) {
+	job.Status = privacy.ErasureStatusRunning
+	report := job.Report
+	if report == nil {
+		report = privacy.NewErasureReport()
+	}
+	if err := s.saveProgress(ctx, job, report); err != nil {
+		logger.Warn("更新擦除任务状态失败",
+			logger.String("job_id", job.ID),
+			logger.ErrorField(err))
+	}
+
+	refs, err := s.store.FindReferences(ctx, job.SpaceID, job.Subject)
+	if err != nil {
+		job.Finish(report, err)
+		s.saveFinishedJob(ctx, job)
+		return
+	}
+
+	for i, ref := range refs {
+		if ctx.Err() != nil {
+			// 实例退出：保留进度，租约过期后由其他实例继续
+			_ = s.saveProgress(context.WithoutCancel(ctx), job, report)
+			return
+		}
+		action, err := s.eraseReference(ctx, job, ref)
+		report.Add(ref, action, err)
+		if (i+1)%erasureProgressInterval == 0 {
+			if err := s.saveProgress(ctx, job, report); err != nil {
+				logger.Warn("保存擦除进度失败",
+					logger.String("job_id", job.ID),
+					logger.ErrorField(err))
+			}
+		}
+	}
+	report.Seal(s.proofKey)
+
+	remaining, err := s.store.FindReferences(ctx, job.SpaceID, job.Subject)
+	if err != nil {
+		job.Finish(report, fmt.Errorf("擦除复查失败: %w", err))
+		s.saveFinishedJob(ctx, job)
+		return
+	}
+	report.Verification = &privacy.ErasureVerification{
+		Remaining:  len(remaining),
+		Verified:   len(remaining) == 0,
+		VerifiedAt: time.Now(),
+	}
+
+	job.Finish(report, nil)
+	s.saveFinishedJob(ctx, job)
+
+	logger.Info("✅ 数据主体擦除完成",
+		logger.String("job_id", job.ID),
+		logger.String("space_id", job.SpaceID),
+		logger.String("mode", string(job.Mode)),
+		logger.Int("items", len(report.Items)),
+		logger.Int("remaining", len(remaining)))
+}
+
+// saveProgress 保存执行中的报告并续约
+func (s *GDPRService) saveProgress(ctx context.Context, job *privacy.ErasureJob, report *privacy.ErasureReport) error {
+	leaseUntil := time.Now().Add(erasureLeaseDuration)
+	job.LockedUntil = &leaseUntil
+	job.Report = report
+	return s.repo.SaveErasureJob(ctx, job)
+}
+
+// eraseReference 处理单条引用
+func (s *GDPRService) eraseReference(ctx context.Context, job *privacy.ErasureJob, ref privacy.SubjectReference) (privacy.ErasureAction, error) {
+	deleteOwned := job.Mode == privacy.ErasureModeDelete && ref.Owned
+
+	switch ref.Kind {
+	case privacy.ReferenceRecord:
+		if deleteOwned {
+			return privacy.ErasureActionDeleted, s.recordRepo.DeleteByTableAndID(ctx, ref.TableID, valueobject.NewRecordID(ref.RecordID))
+		}
+		return privacy.ErasureActionAnonymized, s.anonymizeRecord(ctx, ref, job.Subject)
+
+	case privacy.ReferenceComment:
+		if deleteOwned {
+			return privacy.ErasureActionDeleted, s.store.DeleteComment(ctx, ref.ID)
+		}
+		return privacy.ErasureActionAnonymized, s.store.AnonymizeComment(ctx, ref.ID, job.Subject)
+
+	case privacy.ReferenceAttachment:
+		if job.Mode != privacy.ErasureModeDelete {
+			return privacy.ErasureActionAnonymized, s.store.AnonymizeAttachment(ctx, ref.ID)
+		}
+		paths, err := s.store.DeleteAttachment(ctx, ref.ID)
+		if err != nil {
+			return privacy.ErasureActionDeleted, err
+		}
+		if s.storage != nil {
+			for _, p := range paths {
+				if err := s.storage.Delete(ctx, p); err != nil {
+					logger.Warn("删除附件文件失败",
+						logger.String("attachment_id", ref.ID),
+						logger.String("path", p),
+						logger.ErrorField(err))
+				}
+			}
+		}
+		return privacy.ErasureActionDeleted, nil
+	}
+
+	return privacy.ErasureActionAnonymized, fmt.Errorf("未知的数据类型: %s", ref.Kind)
+}
+
+// anonymizeRecord 匿名化记录：替换创建人/修改人，并脱敏单元格中指向主体的内容
+// 通过记录仓储保存，以便同步清理记录缓存
+func (s *GDPRService) anonymizeRecord(ctx context.Context, ref privacy.SubjectReference, subject privacy.Subject) error {
+	if err := s.store.AnonymizeRecordAuthorship(ctx, ref, subject.UserID); err != nil {
+		return err
+	}
+
+	records, err := s.recordRepo.FindByIDs(ctx, ref.TableID, []valueobject.RecordID{valueobject.NewRecordID(ref.RecordID)})
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	record := records[0]
+
+	changed := make(map[string]interface{})
+	for key, value := range record.Data().ToMap() {
+		if redacted, ok := privacy.RedactValue(value, subject); ok {
+			changed[key] = redacted
+		}
+	}
+	data, err := valueobject.NewRecordData(changed)
+	if err != nil {
+		return err
+	}
+	if err := record.Update(data, privacy.AnonymousUserID); err != nil {
+		return err
+	}
+	return s.recordRepo.Save(ctx, record)
+}
+
+// saveFinishedJob 保存已结束的任务
+func (s *GDPRService) saveFinishedJob(ctx context.Context, job *privacy.ErasureJob) {
+	result := "succeeded"
+	if job.Status == privacy.ErasureStatusFailed {
+		result = "failed"
+	}
+	metrics.JobsProcessedTotal.WithLabelValues("gdpr_erasure", result).Inc()
+	if job.FinishedTime != nil {
+		metrics.JobDuration.WithLabelValues("gdpr_erasure").Observe(job.FinishedTime.Sub(job.CreatedTime).Seconds())
+	}
+
+	if err := s.repo.SaveErasureJob(ctx, job); err != nil {
+		logger.Error("保存擦除任务失败",
+			logger.String("job_id", job.ID),
+			logger.ErrorField(err))
+	}
+}
+
+// GetErasureJob 获取擦除任务（含报告明细）
+func (s *GDPRService) GetErasureJob(ctx context.Context, userID, spaceID, jobID string) (*privacy.ErasureJob, error) {
+	if err := s.ensureSpaceOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	job, err := s.repo.GetErasureJob(ctx, jobID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if job == nil || job.SpaceID != spaceID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("擦除任务不存在")
+	}
+	return job, nil
+}
+
+// ListErasureJobs 列出空间的擦除任务
+func (s *GDPRService) ListErasureJobs(ctx context.Context, userID, spaceID string) ([]*privacy.ErasureJob, error) {
+	if err := s.ensureSpaceOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	jobs, err := s.repo.ListErasureJobs(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return jobs, nil
+}
+
+// VerifyErasureJob 核验擦除报告：重新计算摘要并再次定位主体数据
+func (s *GDPRService) VerifyErasureJob(ctx context.Context, userID, spaceID, jobID string) (*ErasureVerifyResult, error) {
+	job, err := s.GetErasureJob(ctx, userID, spaceID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != privacy.ErasureStatusCompleted || job.Report == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("擦除任务尚未完成")
+	}
+
+	refs, err := s.store.FindReferences(ctx, spaceID, job.Subject)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	return &ErasureVerifyResult{
+		JobID:       job.ID,
+		Digest:      job.Report.Digest,
+		DigestValid: privacy.VerifyErasureDigest(s.proofKey, job.Report),
+		Remaining:   len(refs),
+		Verified:    len(refs) == 0,
+	}, nil
+}
+
+// ensureSpaceOwner 仅空间所有者可处理数据主体请求
+func (s *GDPRService) ensureSpaceOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != entity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以处理数据主体请求")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// stubErasureRepo 记录每次保存的擦除任务状态
+type stubErasureRepo struct {
+	privacy.Repository
+	saved []privacy.ErasureStatus
+}
+
+func (r *stubErasureRepo) SaveErasureJob(_ context.Context, job *privacy.ErasureJob) error {
+	r.saved = append(r.saved, job.Status)
+	return nil
+}
+
+// stubSubjectStore 评论中引用主体的数据，匿名化成功后不再被找到
+type stubSubjectStore struct {
+	privacy.SubjectDataStore
+	comments  map[string]bool // 评论ID → 是否仍引用主体
+	failOnce  map[string]bool // 首次匿名化失败的评论
+	findErr   error
+	findCalls int
+}
+
+func (s *stubSubjectStore) FindReferences(context.Context, string, privacy.Subject) ([]privacy.SubjectReference, error) {
+	s.findCalls++
+	if s.findErr != nil {
+		return nil, s.findErr
+	}
+	var refs []privacy.SubjectReference
+	for id, referenced := range s.comments {
+		if referenced {
+			refs = append(refs, privacy.SubjectReference{Kind: privacy.ReferenceComment, ID: id, TableID: "tbl1"})
+		}
+	}
+	return refs, nil
+}
+
+func (s *stubSubjectStore) AnonymizeComment(_ context.Context, id string, _ privacy.Subject) error {
+	if s.failOnce[id] {
+		delete(s.failOnce, id)
+		return errors.New("connection reset")
+	}
+	s.comments[id] = false
+	return nil
+}
+
+func newGDPRTestService(store *stubSubjectStore, repo *stubErasureRepo) *GDPRService {
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	return NewGDPRService(repo, store, nil, nil, nil, "server-secret")
+}
+
+func TestRunErasureResumesAndSealsWithServerKey(t *testing.T) {
+	store := &stubSubjectStore{
+		comments: map[string]bool{"com1": true, "com2": true},
+		failOnce: map[string]bool{"com2": true},
+	}
+	repo := &stubErasureRepo{}
+	service := newGDPRTestService(store, repo)
+	ctx := context.Background()
+	subject := privacy.Subject{UserID: "usr9"}
+
+	// 首次执行 com2 失败，复查发现仍有残留
+	job := privacy.NewErasureJob("spc1", subject, privacy.ErasureModeAnonymize, "usr1")
+	service.runErasure(ctx, job)
+	if job.Report.Verification.Verified || job.Report.Counts[privacy.ReferenceComment].Failed != 1 {
+		t.Fatalf("首次执行应记录失败项: %+v", job.Report.Counts[privacy.ReferenceComment])
+	}
+
+	// 继续执行时只处理残留数据，结果替换之前的失败项
+	service.runErasure(ctx, job)
+	counts := job.Report.Counts[privacy.ReferenceComment]
+	if !job.Report.Verification.Verified || len(job.Report.Items) != 2 || counts.Failed != 0 || counts.Anonymized != 2 {
+		t.Fatalf("继续执行后应全部擦除: %+v %+v", job.Report.Items, counts)
+	}
+	if !privacy.VerifyErasureDigest(newGDPRTestService(store, repo).proofKey, job.Report) {
+		t.Fatal("报告摘要应可用服务端密钥核验")
+	}
+	if privacy.VerifyErasureDigest(NewGDPRService(repo, store, nil, nil, nil, "other-secret").proofKey, job.Report) {
+		t.Fatal("不同的服务端密钥不应核验通过")
+	}
+	if job.LockedUntil != nil || len(repo.saved) == 0 || repo.saved[0] != privacy.ErasureStatusRunning {
+		t.Fatalf("应保存执行中的进度并在结束时释放租约: %v", repo.saved)
+	}
+}
+
+func TestRunErasureFailsWhenReferencesCannotBeLocated(t *testing.T) {
+	store := &stubSubjectStore{comments: map[string]bool{}, findErr: errors.New("relation is locked")}
+	service := newGDPRTestService(store, &stubErasureRepo{})
+
+	job := privacy.NewErasureJob("spc1", privacy.Subject{UserID: "usr9"}, privacy.ErasureModeAnonymize, "usr1")
+	service.runErasure(context.Background(), job)
+	if job.Status != privacy.ErasureStatusFailed || job.Report.Verification != nil {
+		t.Fatalf("无法定位数据时任务应失败而不是报告已核验: %+v", job)
+	}
+}
@@ -0,0 +1,333 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
+)
+
+// HealthStatus 依赖健康状态
+type HealthStatus string
+
+const (
+	HealthStatusUp       HealthStatus = "up"
+	HealthStatusDegraded HealthStatus = "degraded" // 可继续服务，但存在需要关注的问题
+	HealthStatusDown     HealthStatus = "down"
+)
+
+// severity 状态严重程度（用于汇总）
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthStatusDown:
+		return 2
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ComponentHealth 单个依赖的探测结果
+type ComponentHealth struct {
+	Name      string                 `json:"name"`
+	Status    HealthStatus           `json:"status"`
+	Required  bool                   `json:"required"` // 必需依赖 down 时实例未就绪
+	LatencyMS int64                  `json:"latency_ms"`
+	Message   string                 `json:"message,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport 健康报告
+type HealthReport struct {
+	Status     HealthStatus           `json:"status"`
+	Ready      bool                   `json:"ready"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Components []ComponentHealth      `json:"components"`
+	Runtime    map[string]interface{} `json:"runtime,omitempty"`
+}
+
+// HealthProbe 可探测的依赖（如 Redis 客户端）
+type HealthProbe interface {
+	Health(ctx context.Context) error
+}
+
+// CacheCircuit 仓储缓存熔断器
+type CacheCircuit interface {
+	Snapshot() repository.CacheCircuitSnapshot
+}
+
+// HealthService 健康检查服务 ✨
+// 为 /healthz、/readyz、/status 提供真实的依赖探测：数据库连通性、缓存可用性、任务积压与迁移状态
+type HealthService struct {
+	db        *gorm.DB
+	cache     HealthProbe
+	cfg       config.HealthConfig
+	startedAt time.Time
+
+	cacheCircuit CacheCircuit
+}
+
+// NewHealthService 创建健康检查服务
+func NewHealthService(db *gorm.DB, cfg config.HealthConfig) *HealthService {
+	if cfg.CheckTimeout <= 0 {
+		cfg.CheckTimeout = 2 * time.Second
+	}
+	return &HealthService{
+		db:        db,
+		cfg:       cfg,
+		startedAt: time.Now(),
+	}
+}
+
+// SetCacheProbe 设置缓存探测（缓存未启用时不设置）
+func (s *HealthService) SetCacheProbe(probe HealthProbe) {
+	s.cache = probe
+}
+
+// SetCacheCircuit 设置仓储缓存熔断器（未启用熔断时不设置）
+func (s *HealthService) SetCacheCircuit(circuit CacheCircuit) {
+	s.cacheCircuit = circuit
+}
+
+// Liveness 存活检查：仅探测数据库连通性，避免因可选依赖抖动导致实例被重启
+func (s *HealthService) Liveness(ctx context.Context) *HealthReport {
+	return s.run(ctx, s.checkDatabase)
+}
+
+// Readiness 就绪检查：探测全部依赖，必需依赖不可用时不接收流量
+func (s *HealthService) Readiness(ctx context.Context) *HealthReport {
+	return s.run(ctx, s.checkDatabase, s.checkMigrations, s.checkCache, s.checkJobQueues)
+}
+
+// Status 详细状态：就绪检查结果 + 连接池与运行时信息
+func (s *HealthService) Status(ctx context.Context) *HealthReport {
+	report := s.Readiness(ctx)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	report.Runtime = map[string]interface{}{
+		"uptime_seconds": int64(time.Since(s.startedAt).Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"heap_alloc":     mem.HeapAlloc,
+		"heap_sys":       mem.HeapSys,
+		"num_gc":         mem.NumGC,
+		"go_version":     runtime.Version(),
+	}
+	return report
+}
+
+type healthCheck func(ctx context.Context) ComponentHealth
+
+// run 并发执行探测（每项独立超时）并汇总
+func (s *HealthService) run(ctx context.Context, checks ...healthCheck) *HealthReport {
+	results := make([]ComponentHealth, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check healthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, s.cfg.CheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			result := check(checkCtx)
+			result.LatencyMS = time.Since(start).Milliseconds()
+			results[i] = result
+		}(i, check)
+	}
+	wg.Wait()
+
+	return summarize(results)
+}
+
+// summarize 汇总各依赖状态：必需依赖 down => 未就绪；其余问题 => degraded
+func summarize(components []ComponentHealth) *HealthReport {
+	report := &HealthReport{
+		Status:     HealthStatusUp,
+		Ready:      true,
+		Timestamp:  time.Now(),
+		Components: components,
+	}
+	for _, c := range components {
+		status := c.Status
+		if status == HealthStatusDown && !c.Required {
+			status = HealthStatusDegraded
+		}
+		if status == HealthStatusDown {
+			report.Ready = false
+		}
+		if status.severity() > report.Status.severity() {
+			report.Status = status
+		}
+	}
+	return report
+}
+
+// checkDatabase 数据库连通性与连接池
+func (s *HealthService) checkDatabase(ctx context.Context) ComponentHealth {
+	result := ComponentHealth{Name: "database", Required: true, Status: HealthStatusUp}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Message = err.Error()
+		return result
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		result.Status = HealthStatusDown
+		result.Message = fmt.Sprintf("数据库不可达: %v", err)
+		return result
+	}
+
+	stats := sqlDB.Stats()
+	result.Details = map[string]interface{}{
+		"open_connections": stats.OpenConnections,
+		"in_use":           stats.InUse,
+		"idle":             stats.Idle,
+		"max_open":         stats.MaxOpenConnections,
+		"wait_count":       stats.WaitCount,
+		"wait_duration_ms": stats.WaitDuration.Milliseconds(),
+	}
+	if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+		result.Status = HealthStatusDegraded
+		result.Message = "连接池已耗尽"
+	}
+	return result
+}
+
+// checkCache 缓存可用性（默认非必需：缓存失败时回源数据库）
+// 仓储缓存熔断时即使探测成功也标记为 degraded，详情中附带熔断器状态
+func (s *HealthService) checkCache(ctx context.Context) ComponentHealth {
+	result := ComponentHealth{Name: "cache", Required: s.cfg.RequireCache, Status: HealthStatusUp}
+
+	if s.cache == nil {
+		result.Status = HealthStatusDown
+		result.Message = "缓存未连接"
+		return result
+	}
+	if err := s.cache.Health(ctx); err != nil {
+		result.Status = HealthStatusDown
+		result.Message = fmt.Sprintf("缓存不可用: %v", err)
+	}
+	if s.cacheCircuit != nil {
+		snapshot := s.cacheCircuit.Snapshot()
+		result.Details = map[string]interface{}{"circuit": snapshot}
+		if snapshot.State != repository.CircuitClosed && result.Status == HealthStatusUp {
+			result.Status = HealthStatusDegraded
+			result.Message = "仓储缓存已熔断，读取回源数据库"
+		}
+	}
+	return result
+}
+
+// checkMigrations 迁移状态（golang-migrate 的 schema_migrations 表）
+// dirty 表示上次迁移中断，表结构可能不一致，实例不应接收流量
+func (s *HealthService) checkMigrations(ctx context.Context) ComponentHealth {
+	result := ComponentHealth{Name: "migrations", Required: true, Status: HealthStatusUp}
+
+	var row struct {
+		Version int64
+		Dirty   bool
+	}
+	err := s.db.WithContext(ctx).Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row).Error
+	if err != nil {
+		// 表不存在通常意味着仅使用了 AutoMigrate，不阻塞就绪
+		result.Status = HealthStatusDegraded
+		result.Message = fmt.Sprintf("无法读取迁移版本: %v", err)
+		return result
+	}
+
+	result.Details = map[string]interface{}{
+		"version": row.Version,
+		"dirty":   row.Dirty,
+	}
+	if row.Dirty {
+		result.Status = HealthStatusDown
+		result.Message = fmt.Sprintf("迁移版本 %d 处于 dirty 状态，需要人工修复", row.Version)
+	}
+	return result
+}
+
+// JobQueueStat 后台任务队列积压
+type JobQueueStat struct {
+	Name      string        `json:"name"`
+	Depth     int64         `json:"depth"`
+	OldestAge time.Duration `json:"oldest_age"` // 最早一条积压任务的等待时长
+}
+
+// jobQueues 以数据库表承载的后台任务队列
+var jobQueues = []struct {
+	name   string
+	table  string
+	status []string
+}{
+	{"gdpr_erasure", "gdpr_erasure_job", []string{string(privacy.ErasureStatusPending), string(privacy.ErasureStatusRunning)}},
+	{"notification_delivery", "notification_delivery", []string{"pending"}},
+	{"ai_generation", "ai_generation_job", []string{string(aifield.StatusPending), string(aifield.StatusRunning)}},
+}
+
+// JobQueueStats 统计各队列积压数量与最早任务等待时长（健康检查与指标共用）
+func (s *HealthService) JobQueueStats(ctx context.Context) ([]JobQueueStat, error) {
+	stats := make([]JobQueueStat, 0, len(jobQueues))
+	for _, q := range jobQueues {
+		var row struct {
+			Depth  int64
+			Oldest *time.Time
+		}
+		err := s.db.WithContext(ctx).Table(q.table).
+			Select("COUNT(*) AS depth, MIN(created_time) AS oldest").
+			Where("status IN ?", q.status).
+			Scan(&row).Error
+		if err != nil {
+			return stats, fmt.Errorf("统计队列 %s 失败: %w", q.name, err)
+		}
+
+		stat := JobQueueStat{Name: q.name, Depth: row.Depth}
+		if row.Oldest != nil && row.Depth > 0 {
+			stat.OldestAge = time.Since(*row.Oldest)
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+// checkJobQueues 后台任务积压（等待或执行中的擦除任务与 AI 生成任务、待投递通知）
+func (s *HealthService) checkJobQueues(ctx context.Context) ComponentHealth {
+	result := ComponentHealth{Name: "job_queue", Required: true, Status: HealthStatusUp}
+
+	stats, err := s.JobQueueStats(ctx)
+	details := make(map[string]interface{}, len(stats)+1)
+	var total int64
+	for _, stat := range stats {
+		details[stat.Name] = map[string]interface{}{
+			"depth":              stat.Depth,
+			"oldest_age_seconds": int64(stat.OldestAge.Seconds()),
+		}
+		total += stat.Depth
+	}
+	details["total"] = total
+	result.Details = details
+
+	switch {
+	case err != nil:
+		result.Status = HealthStatusDegraded
+		result.Message = err.Error()
+	case s.cfg.QueueDepthLimit > 0 && total > s.cfg.QueueDepthLimit:
+		result.Status = HealthStatusDown
+		result.Message = fmt.Sprintf("任务积压 %d 超过上限 %d", total, s.cfg.QueueDepthLimit)
+	case s.cfg.QueueDepthWarn > 0 && total > s.cfg.QueueDepthWarn:
+		result.Status = HealthStatusDegraded
+		result.Message = fmt.Sprintf("任务积压 %d 超过告警阈值 %d", total, s.cfg.QueueDepthWarn)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
+)
+
+func TestSummarizeHealth(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []ComponentHealth
+		status     HealthStatus
+		ready      bool
+	}{
+		{
+			name: "全部正常",
+			components: []ComponentHealth{
+				{Name: "database", Required: true, Status: HealthStatusUp},
+				{Name: "cache", Status: HealthStatusUp},
+			},
+			status: HealthStatusUp,
+			ready:  true,
+		},
+		{
+			name: "可选依赖不可用只降级",
+			components: []ComponentHealth{
+				{Name: "database", Required: true, Status: HealthStatusUp},
+				{Name: "cache", Status: HealthStatusDown},
+			},
+			status: HealthStatusDegraded,
+			ready:  true,
+		},
+		{
+			name: "必需依赖不可用则未就绪",
+			components: []ComponentHealth{
+				{Name: "database", Required: true, Status: HealthStatusDown},
+				{Name: "job_queue", Required: true, Status: HealthStatusDegraded},
+			},
+			status: HealthStatusDown,
+			ready:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := summarize(tt.components)
+			if report.Status != tt.status || report.Ready != tt.ready {
+				t.Fatalf("summarize() = %s/%v, 期望 %s/%v", report.Status, report.Ready, tt.status, tt.ready)
+			}
+		})
+	}
+}
+
+type stubHealthProbe struct{}
+
+func (stubHealthProbe) Health(ctx context.Context) error { return nil }
+
+type stubCacheCircuit struct {
+	snapshot repository.CacheCircuitSnapshot
+}
+
+func (s stubCacheCircuit) Snapshot() repository.CacheCircuitSnapshot { return s.snapshot }
+
+func TestCheckCacheReportsOpenCircuit(t *testing.T) {
+	s := NewHealthService(nil, config.HealthConfig{})
+	s.SetCacheProbe(stubHealthProbe{})
+
+	if result := s.checkCache(context.Background()); result.Status != HealthStatusUp || result.Details != nil {
+		t.Fatalf("未设置熔断器时应为 up 且无详情，得到 %+v", result)
+	}
+
+	s.SetCacheCircuit(stubCacheCircuit{repository.CacheCircuitSnapshot{State: repository.CircuitOpen, Trips: 1}})
+	result := s.checkCache(context.Background())
+	if result.Status != HealthStatusDegraded {
+		t.Errorf("熔断时应为 degraded，得到 %s", result.Status)
+	}
+	if snapshot, ok := result.Details["circuit"].(repository.CacheCircuitSnapshot); !ok || snapshot.Trips != 1 {
+		t.Errorf("详情应包含熔断器状态，得到 %v", result.Details)
+	}
+}
@@ -0,0 +1,189 @@
+package application
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/idempotency"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+var idempotencyLog = logger.Named("idempotency")
+
+// idempotencyCleanupInterval 数据库存储过期记录清理间隔
+const idempotencyCleanupInterval = time.Hour
+
+// IdempotencyService 幂等键服务 ✨
+// 首次请求占用幂等键并保存响应，相同键的重试直接重放；存储不可用时放行请求（可用性优先）。
+// 处理中的占用按 LockTimeout 过期（进程崩溃时自动释放），请求处理期间由 KeepAlive 定期续期，
+// 续期、保存响应与释放都以占用者标识做比较并设置，占用被接管后不会覆盖新请求的记录
+type IdempotencyService struct {
+	store idempotency.Store
+	cfg   config.IdempotencyConfig
+	now   func() time.Time
+}
+
+// NewIdempotencyService 创建幂等键服务
+func NewIdempotencyService(store idempotency.Store, cfg config.IdempotencyConfig) *IdempotencyService {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = time.Minute
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 1 << 20
+	}
+	return &IdempotencyService{store: store, cfg: cfg, now: time.Now}
+}
+
+// Enabled 是否启用幂等键
+func (s *IdempotencyService) Enabled() bool {
+	return s != nil && s.cfg.Enabled
+}
+
+// MaxBodySize 可缓存的最大响应体字节数
+func (s *IdempotencyService) MaxBodySize() int {
+	return s.cfg.MaxBodySize
+}
+
+// Begin 开始处理携带幂等键的请求
+// 返回非 nil 记录时应直接重放其响应；返回 (nil, nil) 时继续处理，并在结束后调用 Complete
+func (s *IdempotencyService) Begin(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	if !idempotency.ValidKey(record.Key) {
+		return nil, errors.ErrIdempotencyKeyInvalid
+	}
+
+	now := s.now()
+	record.Status = idempotency.StatusProcessing
+	record.Token = utils.GenerateID()
+	record.CreatedAt = now
+	record.ExpiresAt = now.Add(s.cfg.LockTimeout)
+
+	existing, err := s.store.Reserve(ctx, record)
+	if err != nil {
+		idempotencyLog.Warn(ctx, "幂等键存储不可用，按普通请求处理",
+			logger.String("key", record.Key), logger.ErrorField(err))
+		return nil, nil
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.Fingerprint != record.Fingerprint {
+		return nil, errors.ErrIdempotencyKeyReused.WithDetails(map[string]interface{}{
+			"method": existing.Method,
+			"path":   existing.Path,
+		})
+	}
+	if !existing.Completed() {
+		return nil, errors.ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+// KeepAlive 请求处理期间定期续期处理中的占用，返回的 stop 在保存响应或释放前调用
+// 占用已被接管（如续期长时间失败后过期）时停止续期，随后的 Complete 不会覆盖新请求的记录
+func (s *IdempotencyService) KeepAlive(ctx context.Context, record *idempotency.Record) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	lease := *record
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.cfg.LockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			lease.ExpiresAt = s.now().Add(s.cfg.LockTimeout)
+			err := s.store.Extend(ctx, &lease)
+			if stderrors.Is(err, idempotency.ErrLockLost) {
+				idempotencyLog.Warn(ctx, "幂等键占用已失效，停止续期", logger.String("key", record.Key))
+				return
+			}
+			if err != nil {
+				idempotencyLog.Warn(ctx, "幂等键续期失败", logger.String("key", record.Key), logger.ErrorField(err))
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// Complete 保存首次请求的响应；5xx 响应视为未完成，释放幂等键以便客户端重试
+// 响应体超过 MaxBodySize 时只记录已完成（不保存响应体），重试返回 IDEMPOTENCY_RESPONSE_NOT_STORED 而不是再次执行
+func (s *IdempotencyService) Complete(ctx context.Context, record *idempotency.Record, statusCode int, contentType string, body []byte) {
+	if statusCode >= 500 {
+		s.Release(ctx, record)
+		return
+	}
+
+	record.Status = idempotency.StatusCompleted
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = body
+	if len(body) > s.cfg.MaxBodySize {
+		record.Body = nil
+		record.BodyOmitted = true
+	}
+	record.ExpiresAt = s.now().Add(s.cfg.TTL)
+
+	err := s.store.Complete(ctx, record)
+	if stderrors.Is(err, idempotency.ErrLockLost) {
+		idempotencyLog.Error(ctx, "幂等键占用已被其他请求接管，未保存响应",
+			logger.String("key", record.Key), logger.String("path", record.Path))
+		return
+	}
+	if err != nil {
+		idempotencyLog.Warn(ctx, "保存幂等响应失败", logger.String("key", record.Key), logger.ErrorField(err))
+	}
+}
+
+// Release 释放处理中的幂等键
+func (s *IdempotencyService) Release(ctx context.Context, record *idempotency.Record) {
+	if err := s.store.Release(ctx, record); err != nil {
+		idempotencyLog.Warn(ctx, "释放幂等键失败", logger.String("key", record.Key), logger.ErrorField(err))
+	}
+}
+
+// StartCleanup 定期清理过期的幂等记录
+func (s *IdempotencyService) StartCleanup(ctx context.Context) {
+	if !s.Enabled() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			deleted, err := s.store.DeleteExpired(ctx, s.now())
+			if err != nil {
+				idempotencyLog.Warn(ctx, "清理过期幂等记录失败", logger.ErrorField(err))
+				continue
+			}
+			if deleted > 0 {
+				idempotencyLog.Debug(ctx, "已清理过期幂等记录", logger.Int64("count", deleted))
+			}
+		}
+	}()
+}
@@ -0,0 +1,183 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/idempotency"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryIdempotencyStore 内存幂等存储（仅测试使用）
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+	extends int
+}
+
+func (m *memoryIdempotencyStore) Reserve(_ context.Context, r *idempotency.Record) (*idempotency.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.records[r.Scope+"/"+r.Key]; ok && !existing.Expired(r.CreatedAt) {
+		return &existing, nil
+	}
+	m.records[r.Scope+"/"+r.Key] = *r
+	return nil, nil
+}
+
+// held 记录是否仍由 r.Token 占用（调用方持有锁）
+func (m *memoryIdempotencyStore) held(r *idempotency.Record) (idempotency.Record, bool) {
+	existing, ok := m.records[r.Scope+"/"+r.Key]
+	return existing, ok && existing.Token == r.Token && !existing.Completed()
+}
+
+func (m *memoryIdempotencyStore) Extend(_ context.Context, r *idempotency.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existing, ok := m.held(r)
+	if !ok {
+		return idempotency.ErrLockLost
+	}
+	existing.ExpiresAt = r.ExpiresAt
+	m.records[r.Scope+"/"+r.Key] = existing
+	m.extends++
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Complete(_ context.Context, r *idempotency.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.held(r); !ok {
+		return idempotency.ErrLockLost
+	}
+	m.records[r.Scope+"/"+r.Key] = *r
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Release(_ context.Context, r *idempotency.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.held(r); ok {
+		delete(m.records, r.Scope+"/"+r.Key)
+	}
+	return nil
+}
+
+func (m *memoryIdempotencyStore) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotencyService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc := NewIdempotencyService(&memoryIdempotencyStore{records: map[string]idempotency.Record{}},
+		config.IdempotencyConfig{Enabled: true, TTL: time.Hour, LockTimeout: time.Minute})
+	svc.now = func() time.Time { return now }
+
+	request := func(key string, body string) *idempotency.Record {
+		return &idempotency.Record{
+			Scope: "usr1", Key: key, Method: "POST", Path: "/api/v1/tables/tbl1/records",
+			Fingerprint: idempotency.Fingerprint("POST", "/api/v1/tables/tbl1/records", "", []byte(body)),
+		}
+	}
+
+	first := request("k1", `{"fields":{"a":1}}`)
+	if replay, err := svc.Begin(ctx, first); replay != nil || err != nil {
+		t.Fatalf("首次请求应继续处理，得到 %v, %v", replay, err)
+	}
+	if _, err := svc.Begin(ctx, request("k1", `{"fields":{"a":1}}`)); !errors.Is(err, errors.ErrIdempotencyInProgress) {
+		t.Fatalf("处理中的重试应返回 IDEMPOTENCY_IN_PROGRESS，得到 %v", err)
+	}
+
+	svc.Complete(ctx, first, 201, "application/json", []byte(`{"id":"rec1"}`))
+	replay, err := svc.Begin(ctx, request("k1", `{"fields":{"a":1}}`))
+	if err != nil || replay == nil || replay.StatusCode != 201 || string(replay.Body) != `{"id":"rec1"}` {
+		t.Fatalf("完成后的重试应重放响应，得到 %+v, %v", replay, err)
+	}
+	if _, err := svc.Begin(ctx, request("k1", `{"fields":{"a":2}}`)); !errors.Is(err, errors.ErrIdempotencyKeyReused) {
+		t.Fatalf("相同键不同内容应返回 IDEMPOTENCY_KEY_REUSED，得到 %v", err)
+	}
+
+	failed := request("k2", `{}`)
+	if _, err := svc.Begin(ctx, failed); err != nil {
+		t.Fatal(err)
+	}
+	svc.Complete(ctx, failed, 500, "application/json", nil)
+	if replay, err := svc.Begin(ctx, request("k2", `{}`)); replay != nil || err != nil {
+		t.Fatalf("5xx 响应应释放幂等键，得到 %v, %v", replay, err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if replay, err := svc.Begin(ctx, request("k1", `{"fields":{"a":2}}`)); replay != nil || err != nil {
+		t.Fatalf("过期后同一个键应视为新请求，得到 %v, %v", replay, err)
+	}
+
+	for _, key := range []string{"", "has space", string(make([]byte, idempotency.MaxKeyLength+1))} {
+		if _, err := svc.Begin(ctx, request(key, `{}`)); !errors.Is(err, errors.ErrIdempotencyKeyInvalid) {
+			t.Errorf("无效的幂等键 %q 应被拒绝，得到 %v", key, err)
+		}
+	}
+}
+
+func TestIdempotencyLockOwnership(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := &memoryIdempotencyStore{records: map[string]idempotency.Record{}}
+	svc := NewIdempotencyService(store, config.IdempotencyConfig{Enabled: true, TTL: time.Hour, LockTimeout: time.Minute, MaxBodySize: 16})
+	svc.now = func() time.Time { return now }
+	request := func() *idempotency.Record {
+		return &idempotency.Record{Scope: "usr1", Key: "import-1", Method: "POST", Path: "/api/v1/imports",
+			Fingerprint: idempotency.Fingerprint("POST", "/api/v1/imports", "", nil)}
+	}
+
+	// 占用过期后被重试接管：原请求的响应不能覆盖新请求的记录，也不能释放新请求的占用
+	first := request()
+	if _, err := svc.Begin(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	second := request()
+	if replay, err := svc.Begin(ctx, second); replay != nil || err != nil {
+		t.Fatalf("占用过期后重试应重新执行，得到 %v, %v", replay, err)
+	}
+	svc.Complete(ctx, first, 201, "application/json", []byte(`{"a":1}`))
+	svc.Release(ctx, first)
+	if _, err := svc.Begin(ctx, request()); !errors.Is(err, errors.ErrIdempotencyInProgress) {
+		t.Fatalf("接管后的请求仍在处理中，得到 %v", err)
+	}
+
+	// 超过可缓存大小的响应只记录已完成，重试不会再次执行
+	svc.Complete(ctx, second, 201, "application/json", []byte(`{"records":["rec1","rec2"]}`))
+	replay, err := svc.Begin(ctx, request())
+	if err != nil || replay == nil || !replay.BodyOmitted || replay.Body != nil || replay.StatusCode != 201 {
+		t.Fatalf("超大响应应记录为已完成且不保存响应体，得到 %+v, %v", replay, err)
+	}
+}
+
+func TestIdempotencyKeepAliveExtendsLock(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryIdempotencyStore{records: map[string]idempotency.Record{}}
+	svc := NewIdempotencyService(store, config.IdempotencyConfig{Enabled: true, LockTimeout: 30 * time.Millisecond})
+
+	record := &idempotency.Record{Scope: "usr1", Key: "k1", Fingerprint: "f"}
+	if _, err := svc.Begin(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+	stop := svc.KeepAlive(ctx, record)
+	time.Sleep(100 * time.Millisecond)
+	stop()
+
+	store.mu.Lock()
+	extends, held := store.extends, store.records["usr1/k1"]
+	store.mu.Unlock()
+	if extends == 0 || !held.ExpiresAt.After(record.ExpiresAt) {
+		t.Fatalf("处理期间应续期占用，得到 %d 次", extends)
+	}
+	svc.Complete(ctx, record, 200, "application/json", []byte(`{}`))
+	if replay, err := svc.Begin(ctx, &idempotency.Record{Scope: "usr1", Key: "k1", Fingerprint: "f"}); err != nil || replay == nil {
+		t.Fatalf("续期后仍应由原请求保存响应，得到 %v, %v", replay, err)
+	}
+}
@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/impersonation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var impersonationLog = logger.Named("impersonation")
+
+// impersonationListLimit 列出代入会话的条数上限
+const impersonationListLimit = 100
+
+// StartImpersonationRequest 开始代入请求（targetUserId 与 targetEmail 二选一）
+type StartImpersonationRequest struct {
+	TargetUserID    string `json:"targetUserId"`
+	TargetEmail     string `json:"targetEmail"`
+	Reason          string `json:"reason" binding:"required"` // 工单号或问题描述
+	DurationMinutes int    `json:"durationMinutes"`           // 0 表示使用默认时长
+}
+
+// StartImpersonationResponse 开始代入响应（令牌明文只返回这一次）
+type StartImpersonationResponse struct {
+	*impersonation.Session
+	Token string `json:"token"`
+}
+
+// ListImpersonationsRequest 列出代入会话请求
+type ListImpersonationsRequest struct {
+	TargetUserID string `form:"targetUserId"`
+	ActiveOnly   bool   `form:"active"`
+}
+
+// ImpersonatedRequest 代入期间的一次请求
+type ImpersonatedRequest struct {
+	Method    string
+	Path      string
+	Route     string
+	Status    int
+	Blocked   bool
+	IPAddress string
+	UserAgent string
+	Duration  time.Duration
+}
+
+// ImpersonationService 支持人员代入服务 ✨
+// 只有配置中的支持人员可以在限定时长内以普通用户身份访问 API（不能代入管理员与系统用户）；
+// 开始、结束与代入期间的每个请求都写入审计日志并标记为代入操作，被代入的用户会收到通知；
+// 代入期间禁止访问凭据、会话、账号与计费相关接口
+type ImpersonationService struct {
+	repo     impersonation.Repository
+	userRepo repository.UserRepository
+	cfg      config.ImpersonationConfig
+	support  map[string]bool // 支持人员的用户 ID 与小写邮箱
+	now      func() time.Time
+}
+
+// NewImpersonationService 创建代入服务
+func NewImpersonationService(repo impersonation.Repository, userRepo repository.UserRepository, cfg config.ImpersonationConfig) *ImpersonationService {
+	if cfg.DefaultDuration <= 0 {
+		cfg.DefaultDuration = 30 * time.Minute
+	}
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = 2 * time.Hour
+	}
+	if cfg.DefaultDuration > cfg.MaxDuration {
+		cfg.DefaultDuration = cfg.MaxDuration
+	}
+	support := make(map[string]bool, len(cfg.SupportUsers))
+	for _, u := range cfg.SupportUsers {
+		if u = strings.ToLower(strings.TrimSpace(u)); u != "" {
+			support[u] = true
+		}
+	}
+	return &ImpersonationService{
+		repo:     repo,
+		userRepo: userRepo,
+		cfg:      cfg,
+		support:  support,
+		now:      time.Now,
+	}
+}
+
+// IsSupport 用户是否具有支持角色
+func (s *ImpersonationService) IsSupport(userID, email string) bool {
+	return s.support[strings.ToLower(userID)] || (email != "" && s.support[strings.ToLower(email)])
+}
+
+// Start 以目标用户身份开始代入会话
+func (s *ImpersonationService) Start(ctx context.Context, claims *dto.TokenClaims, req StartImpersonationRequest) (*StartImpersonationResponse, error) {
+	if claims.ServiceTokenID != "" || claims.ImpersonationID != "" {
+		return nil, pkgerrors.ErrForbidden.WithDetails("请使用登录令牌开始代入")
+	}
+	if !s.IsSupport(claims.UserID, claims.Email) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("需要支持人员角色")
+	}
+
+	ttl := s.cfg.DefaultDuration
+	if req.DurationMinutes < 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("durationMinutes 不能为负数")
+	}
+	if req.DurationMinutes > 0 {
+		ttl = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if ttl > s.cfg.MaxDuration {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("代入时长不能超过 %s", s.cfg.MaxDuration))
+	}
+
+	target, err := s.findTarget(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTarget(target); err != nil {
+		return nil, err
+	}
+
+	session, token, err := impersonation.NewSession(claims.UserID, claims.Email,
+		target.ID().String(), target.Email().String(), req.Reason, ttl, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, pkgerrors.Database(err, "创建代入会话失败")
+	}
+	// 审计写入失败时不返回令牌，会话无法使用
+	if err := s.repo.RecordEvent(ctx, session, impersonation.EventStarted, claims.UserID); err != nil {
+		return nil, pkgerrors.Database(err, "写入审计日志失败")
+	}
+	if err := s.repo.NotifyTarget(ctx, session); err != nil {
+		impersonationLog.Warn(ctx, "通知被代入用户失败",
+			logger.String("impersonation_id", session.ID),
+			logger.ErrorField(err))
+	}
+
+	impersonationLog.Warn(ctx, "支持人员开始代入",
+		logger.String("impersonation_id", session.ID),
+		logger.String("support_user_id", session.SupportUserID),
+		logger.String("target_user_id", session.TargetUserID))
+	return &StartImpersonationResponse{Session: session, Token: token}, nil
+}
+
+// End 结束代入会话（开始代入的支持人员、被代入的用户或管理员可以结束）
+func (s *ImpersonationService) End(ctx context.Context, claims *dto.TokenClaims, sessionID string) (*impersonation.Session, error) {
+	session, err := s.repo.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if session == nil || !(claims.IsAdmin || claims.UserID == session.SupportUserID || claims.UserID == session.TargetUserID) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("代入会话不存在")
+	}
+	if err := session.End(claims.UserID, s.now()); err != nil {
+		return nil, pkgerrors.ErrConflict.WithDetails(err.Error())
+	}
+	if err := s.repo.End(ctx, session); err != nil {
+		return nil, pkgerrors.Database(err, "结束代入会话失败")
+	}
+	if err := s.repo.RecordEvent(ctx, session, impersonation.EventEnded, claims.UserID); err != nil {
+		impersonationLog.Warn(ctx, "写入代入结束审计日志失败",
+			logger.String("impersonation_id", session.ID),
+			logger.ErrorField(err))
+	}
+
+	impersonationLog.Info(ctx, "代入会话已结束",
+		logger.String("impersonation_id", session.ID),
+		logger.String("ended_by", claims.UserID))
+	return session, nil
+}
+
+// List 列出代入会话（支持人员只能看到自己发起的会话，管理员可以看到全部）
+func (s *ImpersonationService) List(ctx context.Context, claims *dto.TokenClaims, req ListImpersonationsRequest) ([]*impersonation.Session, error) {
+	filter := impersonation.ListFilter{
+		TargetUserID: req.TargetUserID,
+		Limit:        impersonationListLimit,
+	}
+	switch {
+	case claims.IsAdmin:
+	case s.IsSupport(claims.UserID, claims.Email):
+		filter.SupportUserID = claims.UserID
+	default:
+		return nil, pkgerrors.ErrForbidden.WithDetails("需要支持人员角色")
+	}
+	if req.ActiveOnly {
+		now := s.now()
+		filter.ActiveAt = &now
+	}
+
+	sessions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取代入会话失败")
+	}
+	return sessions, nil
+}
+
+// ListReceived 列出以当前用户身份进行的代入会话
+func (s *ImpersonationService) ListReceived(ctx context.Context, userID string) ([]*impersonation.Session, error) {
+	sessions, err := s.repo.List(ctx, impersonation.ListFilter{TargetUserID: userID, Limit: impersonationListLimit})
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取代入会话失败")
+	}
+	return sessions, nil
+}
+
+// Authenticate 校验代入令牌并返回被代入用户的身份
+func (s *ImpersonationService) Authenticate(ctx context.Context, plaintext string) (*dto.TokenClaims, error) {
+	session, err := s.repo.FindByHash(ctx, impersonation.Hash(plaintext))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if session == nil || !session.Active(s.now()) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("代入会话已结束或已过期")
+	}
+	// 支持角色被移除后会话立即失效
+	if !s.IsSupport(session.SupportUserID, session.SupportEmail) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("代入会话已失效")
+	}
+
+	target, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(session.TargetUserID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if target == nil || s.checkTarget(target) != nil {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("被代入的用户不可用")
+	}
+
+	return &dto.TokenClaims{
+		UserID:          session.TargetUserID,
+		Email:           target.Email().String(),
+		AuthTime:        session.CreatedAt,
+		ImpersonationID: session.ID,
+		ImpersonatorID:  session.SupportUserID,
+	}, nil
+}
+
+// Record 写入代入期间请求的审计日志
+func (s *ImpersonationService) Record(ctx context.Context, claims *dto.TokenClaims, req ImpersonatedRequest) {
+	action := &impersonation.Action{
+		SessionID:     claims.ImpersonationID,
+		SupportUserID: claims.ImpersonatorID,
+		TargetUserID:  claims.UserID,
+		TargetEmail:   claims.Email,
+		Method:        req.Method,
+		Path:          req.Path,
+		Route:         req.Route,
+		Status:        req.Status,
+		Blocked:       req.Blocked,
+		IPAddress:     req.IPAddress,
+		UserAgent:     req.UserAgent,
+		Duration:      req.Duration,
+		OccurredAt:    s.now(),
+	}
+	if err := s.repo.RecordAction(ctx, action); err != nil {
+		impersonationLog.Error(ctx, "写入代入请求审计日志失败",
+			logger.String("impersonation_id", claims.ImpersonationID),
+			logger.String("path", req.Path),
+			logger.ErrorField(err))
+	}
+}
+
+// findTarget 按 ID 或邮箱查找目标用户
+func (s *ImpersonationService) findTarget(ctx context.Context, req StartImpersonationRequest) (*entity.User, error) {
+	var (
+		target *entity.User
+		err    error
+	)
+	switch {
+	case req.TargetUserID != "":
+		target, err = s.userRepo.FindByID(ctx, valueobject.NewUserID(req.TargetUserID))
+	case req.TargetEmail != "":
+		email, emailErr := valueobject.NewEmail(req.TargetEmail)
+		if emailErr != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("邮箱格式不正确")
+		}
+		target, err = s.userRepo.FindByEmail(ctx, email)
+	default:
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("需要指定 targetUserId 或 targetEmail")
+	}
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if target == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("用户不存在")
+	}
+	return target, nil
+}
+
+// checkTarget 管理员、系统用户、支持人员与未激活的用户不能被代入
+func (s *ImpersonationService) checkTarget(target *entity.User) error {
+	switch {
+	case target.IsAdmin() || target.IsSystem():
+		return pkgerrors.ErrForbidden.WithDetails("不能代入管理员或系统用户")
+	case s.IsSupport(target.ID().String(), target.Email().String()):
+		return pkgerrors.ErrForbidden.WithDetails("不能代入支持人员")
+	case !target.IsActive():
+		return pkgerrors.ErrForbidden.WithDetails("用户未激活")
+	}
+	return nil
+}
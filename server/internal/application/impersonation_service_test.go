@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/impersonation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+)
+
+type memoryImpersonationRepo struct {
+	sessions      map[string]*impersonation.Session
+	events        []string
+	actions       []*impersonation.Action
+	notifications []string
+}
+
+func newMemoryImpersonationRepo() *memoryImpersonationRepo {
+	return &memoryImpersonationRepo{sessions: map[string]*impersonation.Session{}}
+}
+
+func (r *memoryImpersonationRepo) Create(_ context.Context, session *impersonation.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memoryImpersonationRepo) FindByHash(_ context.Context, tokenHash string) (*impersonation.Session, error) {
+	for _, session := range r.sessions {
+		if session.TokenHash == tokenHash {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryImpersonationRepo) FindByID(_ context.Context, id string) (*impersonation.Session, error) {
+	if session, ok := r.sessions[id]; ok {
+		copied := *session
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryImpersonationRepo) List(_ context.Context, filter impersonation.ListFilter) ([]*impersonation.Session, error) {
+	var list []*impersonation.Session
+	for _, session := range r.sessions {
+		if filter.SupportUserID != "" && session.SupportUserID != filter.SupportUserID {
+			continue
+		}
+		if filter.TargetUserID != "" && session.TargetUserID != filter.TargetUserID {
+			continue
+		}
+		if filter.ActiveAt != nil && !session.Active(*filter.ActiveAt) {
+			continue
+		}
+		list = append(list, session)
+	}
+	return list, nil
+}
+
+func (r *memoryImpersonationRepo) End(_ context.Context, session *impersonation.Session) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memoryImpersonationRepo) RecordEvent(_ context.Context, session *impersonation.Session, event, _ string) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *memoryImpersonationRepo) RecordAction(_ context.Context, action *impersonation.Action) error {
+	r.actions = append(r.actions, action)
+	return nil
+}
+
+func (r *memoryImpersonationRepo) NotifyTarget(_ context.Context, session *impersonation.Session) error {
+	r.notifications = append(r.notifications, session.TargetUserID)
+	return nil
+}
+
+func newImpersonationUser(t *testing.T, id string, isAdmin bool) *entity.User {
+	t.Helper()
+	email, err := valueobject.NewEmail(id + "@example.com")
+	if err != nil {
+		t.Fatalf("NewEmail: %v", err)
+	}
+	hash, _ := valueobject.NewHashedPassword("hash")
+	now := time.Now()
+	return entity.ReconstructUser(valueobject.NewUserID(id), id, email, hash, nil, nil, valueobject.ActiveStatus(),
+		false, isAdmin, false, "", now, now, nil, nil, nil, 1)
+}
+
+func newTestImpersonationService(t *testing.T) (*ImpersonationService, *memoryImpersonationRepo) {
+	t.Helper()
+	users := &stubUserRepo{users: map[string]*entity.User{
+		"usr_support": newImpersonationUser(t, "usr_support", false),
+		"usr_1":       newImpersonationUser(t, "usr_1", false),
+		"usr_admin":   newImpersonationUser(t, "usr_admin", true),
+	}}
+	repo := newMemoryImpersonationRepo()
+	svc := NewImpersonationService(repo, users, config.ImpersonationConfig{
+		SupportUsers: []string{"USR_SUPPORT@example.com"},
+		MaxDuration:  time.Hour,
+	})
+	return svc, repo
+}
+
+func TestImpersonationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestImpersonationService(t)
+	support := &dto.TokenClaims{UserID: "usr_support", Email: "usr_support@example.com"}
+
+	started, err := svc.Start(ctx, support, StartImpersonationRequest{TargetUserID: "usr_1", Reason: "ticket #42"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if got := started.ExpiresAt.Sub(started.CreatedAt); got != 30*time.Minute {
+		t.Errorf("default duration should be used, got %s", got)
+	}
+	if len(repo.events) != 1 || repo.events[0] != impersonation.EventStarted {
+		t.Errorf("start should be audited, got %v", repo.events)
+	}
+	if len(repo.notifications) != 1 || repo.notifications[0] != "usr_1" {
+		t.Errorf("target user should be notified, got %v", repo.notifications)
+	}
+
+	claims, err := svc.Authenticate(ctx, started.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.UserID != "usr_1" || claims.IsAdmin || claims.ImpersonationID != started.ID || claims.ImpersonatorID != "usr_support" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	svc.Record(ctx, claims, ImpersonatedRequest{Method: "GET", Path: "/api/v1/tables/tbl_1/records", Status: 200})
+	if len(repo.actions) != 1 || repo.actions[0].SupportUserID != "usr_support" || repo.actions[0].TargetUserID != "usr_1" {
+		t.Fatalf("request should be audited as impersonated, got %+v", repo.actions)
+	}
+
+	// 被代入的用户可以结束会话，结束后令牌立即失效
+	if _, err := svc.End(ctx, &dto.TokenClaims{UserID: "usr_2"}, started.ID); err == nil {
+		t.Errorf("unrelated users must not end the session")
+	}
+	if _, err := svc.End(ctx, &dto.TokenClaims{UserID: "usr_1"}, started.ID); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, started.Token); err == nil {
+		t.Errorf("ended session should be rejected")
+	}
+	if repo.events[len(repo.events)-1] != impersonation.EventEnded {
+		t.Errorf("end should be audited, got %v", repo.events)
+	}
+}
+
+func TestImpersonationRestrictions(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestImpersonationService(t)
+	support := &dto.TokenClaims{UserID: "usr_support", Email: "usr_support@example.com"}
+
+	cases := []struct {
+		name   string
+		claims *dto.TokenClaims
+		req    StartImpersonationRequest
+	}{
+		{"not support", &dto.TokenClaims{UserID: "usr_1", Email: "usr_1@example.com"}, StartImpersonationRequest{TargetUserID: "usr_support", Reason: "ticket #42"}},
+		{"service token", &dto.TokenClaims{UserID: "usr_support", Email: "usr_support@example.com", ServiceTokenID: "stk_1"}, StartImpersonationRequest{TargetUserID: "usr_1", Reason: "ticket #42"}},
+		{"admin target", support, StartImpersonationRequest{TargetUserID: "usr_admin", Reason: "ticket #42"}},
+		{"unknown target", support, StartImpersonationRequest{TargetUserID: "usr_9", Reason: "ticket #42"}},
+		{"too long", support, StartImpersonationRequest{TargetUserID: "usr_1", Reason: "ticket #42", DurationMinutes: 120}},
+		{"no reason", support, StartImpersonationRequest{TargetUserID: "usr_1"}},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Start(ctx, tc.claims, tc.req); err == nil {
+			t.Errorf("%s: start should be rejected", tc.name)
+		}
+	}
+
+	if _, err := svc.List(ctx, &dto.TokenClaims{UserID: "usr_1"}, ListImpersonationsRequest{}); err == nil {
+		t.Errorf("regular users must not list impersonation sessions")
+	}
+}
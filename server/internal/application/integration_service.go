@@ -0,0 +1,708 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+var integrationLog = logger.Named("integration")
+
+// integrationMaxLimit 轮询触发器与查找动作单次返回的最大条数
+const integrationMaxLimit = 100
+
+// IntegrationItem 触发器与动作返回的记录
+// ID 为去重键：新建与删除事件为记录ID，更新事件为 记录ID:版本，同一记录的每次更新都会触发一次
+type IntegrationItem struct {
+	ID         string                 `json:"id"`
+	RecordID   string                 `json:"recordId"`
+	TableID    string                 `json:"tableId"`
+	Event      integration.Event      `json:"event,omitempty"`
+	Cursor     int64                  `json:"cursor,omitempty"`
+	Version    int64                  `json:"version,omitempty"`
+	OccurredAt *time.Time             `json:"occurredAt,omitempty"`
+	Fields     map[string]interface{} `json:"fields"`               // 按字段名
+	Transition *StatusTransition      `json:"transition,omitempty"` // record.transitioned 事件的流转详情
+}
+
+// IntegrationInputField 动作的动态输入字段（Zapier input fields）
+type IntegrationInputField struct {
+	Key      string   `json:"key"` // 字段ID（字段改名后不影响已配置的集成）
+	Label    string   `json:"label"`
+	Type     string   `json:"type"` // string / text / number / boolean / datetime
+	Required bool     `json:"required"`
+	List     bool     `json:"list,omitempty"`
+	Choices  []string `json:"choices,omitempty"`
+	HelpText string   `json:"helpText,omitempty"`
+}
+
+// SubscribeHookRequest 订阅 REST Hook 请求
+type SubscribeHookRequest struct {
+	TargetURL string            `json:"targetUrl" binding:"required"`
+	Event     integration.Event `json:"event" binding:"required"`
+	Template  string            `json:"template"` // 可选，输出模板（见 integration.PayloadTemplate）
+}
+
+// UpdateHookTemplateRequest 更新订阅输出模板请求（为空表示按原格式推送）
+type UpdateHookTemplateRequest struct {
+	Template string `json:"template"`
+}
+
+// IntegrationRecordRequest 新建/更新记录动作请求（字段可按ID或名称指定）
+type IntegrationRecordRequest struct {
+	RecordID string                 `json:"recordId"`
+	Fields   map[string]interface{} `json:"fields" binding:"required"`
+}
+
+// IntegrationFindRequest 查找记录动作请求
+type IntegrationFindRequest struct {
+	Field string      `json:"field" binding:"required"` // 字段ID或名称
+	Value interface{} `json:"value"`
+	Limit int         `json:"limit"`
+}
+
+// IntegrationService 集成平台服务 ✨
+// 提供 Zapier / Make 等平台所需的三类接口，无需为每个平台单独开发应用：
+//   - 轮询触发器：不带游标时返回最新的事件（平台按 id 去重），带游标时返回游标之后的事件；
+//   - REST Hook：平台订阅后，后台按变更流顺序推送匹配的记录，目标返回 410 时自动退订，
+//     连续失败达到阈值时暂停（见 DeliveryMonitorService）；
+//   - 动作：新建、更新、查找记录，以及供平台生成表单的动态输入字段
+type IntegrationService struct {
+	repo              integration.Repository
+	deliveries        integration.DeliveryRepository
+	changeFeed        *ChangeFeedService
+	recordService     *RecordService
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	cfg               config.IntegrationConfig
+	client            *http.Client
+	onPaused          func(ctx context.Context, hook *integration.Hook)
+}
+
+// NewIntegrationService 创建集成平台服务
+func NewIntegrationService(
+	repo integration.Repository,
+	deliveries integration.DeliveryRepository,
+	changeFeed *ChangeFeedService,
+	recordService *RecordService,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	cfg config.IntegrationConfig,
+) *IntegrationService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 15 * time.Second
+	}
+	if cfg.LeaseDuration <= cfg.RequestTimeout {
+		cfg.LeaseDuration = 4 * cfg.RequestTimeout
+	}
+	if cfg.MaxFailures < 0 {
+		cfg.MaxFailures = 0
+	}
+	return &IntegrationService{
+		repo:              repo,
+		deliveries:        deliveries,
+		changeFeed:        changeFeed,
+		recordService:     recordService,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		cfg:               cfg,
+		client:            netguard.NewHTTPClient(cfg.RequestTimeout, cfg.AllowPrivate),
+	}
+}
+
+// SetPauseListener 设置订阅因连续失败被暂停时的回调（如通知订阅者）
+func (s *IntegrationService) SetPauseListener(listener func(ctx context.Context, hook *integration.Hook)) {
+	s.onPaused = listener
+}
+
+// PollTrigger 轮询触发器
+// cursor 为 nil 时按时间倒序返回最新的事件；否则按顺序返回游标之后的事件，并返回下次请求的游标
+func (s *IntegrationService) PollTrigger(ctx context.Context, userID, tableID string, event integration.Event, cursor *int64, limit int) ([]IntegrationItem, int64, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, 0, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	action, ok := event.Action()
+	if !ok {
+		return nil, 0, pkgerrors.ErrValidationFailed.WithDetails("不支持的触发事件: " + string(event))
+	}
+	baseID, err := s.tableBaseID(ctx, tableID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 || limit > integrationMaxLimit {
+		limit = integrationMaxLimit
+	}
+
+	filter := changefeed.ListFilter{TableID: tableID, EntityType: event.EntityType(), Action: action, Limit: limit}
+	var changes []*changefeed.Change
+	next := int64(0)
+	if cursor == nil {
+		changes, err = s.changeFeed.repo.ListLatest(ctx, baseID, filter)
+	} else {
+		filter.After = *cursor
+		next = *cursor
+		changes, err = s.changeFeed.repo.ListAfter(ctx, baseID, filter)
+	}
+	if err != nil {
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			return nil, 0, pkgerrors.ErrConflict.WithDetails("游标之后的变更已超出保留期，请不带游标重新轮询")
+		}
+		return nil, 0, pkgerrors.Database(err, "读取变更流失败")
+	}
+	if cursor != nil && len(changes) > 0 {
+		next = changes[len(changes)-1].Seq
+	}
+
+	items, err := s.buildItems(ctx, tableID, event, changes)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, next, nil
+}
+
+// Subscribe 订阅 REST Hook（从当前游标开始推送）
+func (s *IntegrationService) Subscribe(ctx context.Context, userID, tableID string, req SubscribeHookRequest) (*integration.Hook, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	if _, ok := req.Event.Action(); !ok {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的触发事件: " + string(req.Event))
+	}
+	if err := validateOutboundURL(req.TargetURL, s.cfg.AllowPrivate); err != nil {
+		return nil, err
+	}
+	if err := validateHookTemplate(req.Template); err != nil {
+		return nil, err
+	}
+	baseID, err := s.tableBaseID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.changeFeed.repo.Head(ctx)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "读取变更流游标失败")
+	}
+
+	hook := integration.NewHook(baseID, tableID, req.Event, req.TargetURL, req.Template, head, s.cfg.MaxFailures, userID)
+	if err := s.repo.Create(ctx, hook); err != nil {
+		return nil, pkgerrors.Database(err, "保存订阅失败")
+	}
+	return hook, nil
+}
+
+// Unsubscribe 退订 REST Hook（只有订阅者本人可以退订）
+func (s *IntegrationService) Unsubscribe(ctx context.Context, userID, hookID string) error {
+	hook, err := s.repo.FindByID(ctx, hookID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取订阅失败")
+	}
+	if hook == nil {
+		return pkgerrors.ErrNotFound.WithDetails("订阅不存在")
+	}
+	if hook.CreatedBy != userID {
+		return pkgerrors.ErrForbidden.WithDetails("只能退订自己创建的订阅")
+	}
+	if err := s.repo.Delete(ctx, hookID); err != nil {
+		return pkgerrors.Database(err, "删除订阅失败")
+	}
+	return nil
+}
+
+// UpdateHookTemplate 更新订阅的输出模板（只有订阅者本人可以修改），下一次推送起生效
+func (s *IntegrationService) UpdateHookTemplate(ctx context.Context, userID, hookID string, req UpdateHookTemplateRequest) (*integration.Hook, error) {
+	hook, err := s.repo.FindByID(ctx, hookID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取订阅失败")
+	}
+	if hook == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("订阅不存在")
+	}
+	if hook.CreatedBy != userID {
+		return nil, pkgerrors.ErrForbidden.WithDetails("只能修改自己创建的订阅")
+	}
+	if err := validateHookTemplate(req.Template); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateTemplate(ctx, hookID, req.Template); err != nil {
+		return nil, pkgerrors.Database(err, "保存输出模板失败")
+	}
+	hook.Template = req.Template
+	return hook, nil
+}
+
+// validateHookTemplate 校验输出模板（为空表示不改写）
+func validateHookTemplate(src string) error {
+	if src == "" {
+		return nil
+	}
+	if _, err := integration.ParseTemplate(src); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// InputFields 动作的动态输入字段（计算字段只读，不出现在输入中）
+func (s *IntegrationService) InputFields(ctx context.Context, userID, tableID string) ([]IntegrationInputField, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	inputs := make([]IntegrationInputField, 0, len(fields))
+	for _, field := range fields {
+		if field.IsComputed() {
+			continue
+		}
+		inputs = append(inputs, integrationInputField(field))
+	}
+	return inputs, nil
+}
+
+// integrationInputField 字段对应的输入定义
+func integrationInputField(field *entity.Field) IntegrationInputField {
+	input := IntegrationInputField{
+		Key:      field.ID().String(),
+		Label:    field.Name().String(),
+		Type:     "string",
+		Required: field.IsRequired(),
+	}
+	if description := field.Description(); description != nil {
+		input.HelpText = *description
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeLongText, fieldVO.TypeRichText:
+		input.Type = "text"
+	case fieldVO.TypeNumber, fieldVO.TypeRating, fieldVO.TypePercent, fieldVO.TypeCurrency, fieldVO.TypeDuration,
+		fieldVO.TypeProgress:
+		input.Type = "number"
+	case fieldVO.TypeCheckbox, fieldVO.TypeBoolean:
+		input.Type = "boolean"
+	case fieldVO.TypeDate, fieldVO.TypeDateTime:
+		input.Type = "datetime"
+	case fieldVO.TypeMultipleSelect, fieldVO.TypeLink, fieldVO.TypeUser, fieldVO.TypeAttachment:
+		input.List = true
+	}
+	if options := field.Options(); options != nil && options.Select != nil {
+		for _, choice := range options.Select.Choices {
+			input.Choices = append(input.Choices, choice.Name)
+		}
+	}
+	return input
+}
+
+// CreateRecord 新建记录动作
+func (s *IntegrationService) CreateRecord(ctx context.Context, userID, tableID string, req IntegrationRecordRequest) (*IntegrationItem, error) {
+	if !s.permissionService.CanCreateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该表格中创建记录")
+	}
+	record, err := s.recordService.CreateRecord(ctx, dto.CreateRecordRequest{TableID: tableID, Data: req.Fields}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordItem(ctx, tableID, record)
+}
+
+// UpdateRecord 更新记录动作（只更新提供的字段）
+func (s *IntegrationService) UpdateRecord(ctx context.Context, userID, tableID string, req IntegrationRecordRequest) (*IntegrationItem, error) {
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限更新该表格的记录")
+	}
+	if req.RecordID == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("recordId 不能为空")
+	}
+	record, err := s.recordService.UpdateRecord(ctx, tableID, req.RecordID, dto.UpdateRecordRequest{Data: req.Fields}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recordItem(ctx, tableID, record)
+}
+
+// FindRecords 查找动作：按字段值精确匹配记录
+func (s *IntegrationService) FindRecords(ctx context.Context, userID, tableID string, req IntegrationFindRequest) ([]IntegrationItem, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	baseID, err := s.tableBaseID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	var target *entity.Field
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+		if field.ID().String() == req.Field || (target == nil && field.Name().String() == req.Field) {
+			target = field
+		}
+	}
+	if target == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("字段不存在: " + req.Field)
+	}
+	if target.IsEncrypted() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("加密字段不支持查找")
+	}
+
+	operator := viewVO.FilterItemOpIs
+	if req.Value == nil || req.Value == "" {
+		operator = viewVO.FilterItemOpIsEmpty
+	}
+	condition, err := buildViewFilterCondition(ctx, &viewVO.Filter{
+		Operator: viewVO.FilterOperatorAnd,
+		Filters:  []viewVO.FilterItem{{FieldID: target.ID().String(), Operator: operator, Value: req.Value}},
+	}, byID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > integrationMaxLimit {
+		limit = integrationMaxLimit
+	}
+	var ids []string
+	db := s.dataDB(ctx, baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(baseID, tableID))
+	if condition != nil {
+		db = db.Where(condition)
+	}
+	if err := db.Order("__created_time DESC").Limit(limit).Pluck("__id", &ids).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查找记录失败")
+	}
+
+	items := make([]IntegrationItem, 0, len(ids))
+	names := fieldNames(fields)
+	for _, id := range ids {
+		record, err := s.recordService.GetRecord(ctx, tableID, id)
+		if err != nil {
+			continue
+		}
+		item := newIntegrationItem(record, names)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (s *IntegrationService) tableBaseID(ctx context.Context, tableID string) (string, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return "", pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return "", pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	return table.BaseID(), nil
+}
+
+func (s *IntegrationService) recordItem(ctx context.Context, tableID string, record *dto.RecordResponse) (*IntegrationItem, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	item := newIntegrationItem(record, fieldNames(fields))
+	return &item, nil
+}
+
+// buildItems 将记录变更转换为触发器条目
+// 新建与更新事件返回记录的当前值（已删除的记录跳过），删除事件返回删除前的值
+func (s *IntegrationService) buildItems(ctx context.Context, tableID string, event integration.Event, changes []*changefeed.Change) ([]IntegrationItem, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	names := fieldNames(fields)
+	s.changeFeed.maskRecordChanges(ctx, changes)
+
+	items := make([]IntegrationItem, 0, len(changes))
+	for _, change := range changes {
+		var item IntegrationItem
+		if event == integration.EventRecordDeleted {
+			var data map[string]interface{}
+			if len(change.Data) > 0 {
+				_ = json.Unmarshal(change.Data, &data)
+			}
+			item = newIntegrationItem(webhookRecord(fields, &dto.RecordResponse{ID: change.EntityID, TableID: tableID, Data: data}), names)
+		} else {
+			record, err := s.recordService.GetRecord(ctx, tableID, change.EntityID)
+			if errors.Is(err, pkgerrors.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			item = newIntegrationItem(webhookRecord(fields, record), names)
+		}
+		if event == integration.EventRecordTransitioned {
+			var transition StatusTransition
+			if err := json.Unmarshal(change.Data, &transition); err == nil {
+				item.Transition = &transition
+			}
+		}
+		item.ID = integrationDedupeKey(event, change)
+		item.Event = event
+		item.Cursor = change.Seq
+		item.Version = change.Version
+		occurredAt := change.OccurredAt
+		item.OccurredAt = &occurredAt
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// webhookRecord 推送到外部的记录：未声明允许在 Webhook 中显示明文的加密字段脱敏（返回副本，不修改原记录）
+func webhookRecord(fields []*entity.Field, record *dto.RecordResponse) *dto.RecordResponse {
+	masked := *record
+	masked.Data = entity.MaskEncryptedValues(fields, record.Data, entity.RevealChannelWebhooks)
+	return &masked
+}
+
+// integrationDedupeKey 触发器条目的去重键
+func integrationDedupeKey(event integration.Event, change *changefeed.Change) string {
+	switch event {
+	case integration.EventRecordUpdated:
+		return fmt.Sprintf("%s:%d", change.EntityID, change.Version)
+	case integration.EventRecordTransitioned:
+		return change.ID
+	}
+	return change.EntityID
+}
+
+// newIntegrationItem 记录转换为按字段名组织的条目（已删除字段的值被忽略）
+func newIntegrationItem(record *dto.RecordResponse, names map[string]string) IntegrationItem {
+	item := IntegrationItem{
+		ID:       record.ID,
+		RecordID: record.ID,
+		TableID:  record.TableID,
+		Version:  int64(record.Version),
+		Fields:   make(map[string]interface{}, len(record.Data)),
+	}
+	for key, value := range record.Data {
+		if name, ok := names[key]; ok {
+			item.Fields[name] = value
+		}
+	}
+	return item
+}
+
+// fieldNames 字段ID到字段名的映射
+func fieldNames(fields []*entity.Field) map[string]string {
+	names := make(map[string]string, len(fields))
+	for _, field := range fields {
+		names[field.ID().String()] = field.Name().String()
+	}
+	return names
+}
+
+// Start 启动 REST Hook 后台推送
+func (s *IntegrationService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.deliverDue(ctx)
+		}
+	}()
+}
+
+// deliverDue 领取订阅并推送游标之后的事件
+func (s *IntegrationService) deliverDue(ctx context.Context) {
+	now := time.Now()
+	hooks, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 20)
+	if err != nil {
+		integrationLog.Warn(ctx, "领取订阅失败", logger.ErrorField(err))
+		return
+	}
+	for _, hook := range hooks {
+		if gone := s.deliver(ctx, hook); gone {
+			if err := s.repo.Delete(ctx, hook.ID); err != nil {
+				integrationLog.Warn(ctx, "删除已失效订阅失败", logger.String("hook_id", hook.ID), logger.ErrorField(err))
+			}
+			continue
+		}
+		if err := s.repo.SaveProgress(ctx, hook); err != nil {
+			integrationLog.Warn(ctx, "保存订阅进度失败", logger.String("hook_id", hook.ID), logger.ErrorField(err))
+		}
+	}
+}
+
+// deliver 推送一批事件，成功后推进游标；目标返回 410 Gone 时返回 true（平台已删除该触发器）
+// 推送失败（含输出模板渲染失败）时记录失败推送并累加失败次数，达到阈值后暂停订阅。
+// 每次推送前重新确认订阅者仍可读取该表，否则暂停订阅（恢复后再次检查）
+func (s *IntegrationService) deliver(ctx context.Context, hook *integration.Hook) bool {
+	if !s.permissionService.CanAccessRecord(ctx, hook.CreatedBy, hook.TableID) {
+		if hook.Pause("订阅者已没有权限读取该表格", time.Now()) && s.onPaused != nil {
+			s.onPaused(ctx, hook)
+		}
+		return false
+	}
+
+	action, _ := hook.Event.Action()
+	changes, err := s.changeFeed.repo.ListAfter(ctx, hook.BaseID, changefeed.ListFilter{
+		After:      hook.Cursor,
+		TableID:    hook.TableID,
+		EntityType: hook.Event.EntityType(),
+		Action:     action,
+		Limit:      s.cfg.BatchSize,
+	})
+	if err != nil {
+		hook.LastError = err.Error()
+		return false
+	}
+	if len(changes) == 0 {
+		return false
+	}
+
+	items, err := s.buildItems(ctx, hook.TableID, hook.Event, changes)
+	if err != nil {
+		hook.LastError = err.Error()
+		return false
+	}
+	cursor := changes[len(changes)-1].Seq
+	if len(items) > 0 {
+		var payload interface{} = items
+		if hook.Template != "" {
+			if payload, err = renderHookPayload(hook.Template, items); err != nil {
+				s.recordFailure(ctx, hook, cursor, len(items), 0, err)
+				return false
+			}
+		}
+		status, err := s.post(ctx, hook.TargetURL, payload)
+		if status == http.StatusGone {
+			return true
+		}
+		if err != nil {
+			s.recordFailure(ctx, hook, cursor, len(items), status, err)
+			return false
+		}
+		now := time.Now()
+		hook.LastDeliveredAt = &now
+	}
+	if hook.Failures > 0 {
+		if err := s.deliveries.Resolve(ctx, hook.ID, cursor); err != nil {
+			integrationLog.Warn(ctx, "更新失败推送状态失败", logger.String("hook_id", hook.ID), logger.ErrorField(err))
+		}
+	}
+	hook.RecordSuccess(cursor)
+	return false
+}
+
+// recordFailure 记录失败推送，订阅因此暂停时通知监听者
+func (s *IntegrationService) recordFailure(ctx context.Context, hook *integration.Hook, cursor int64, itemCount, status int, cause error) {
+	now := time.Now()
+	delivery := integration.NewDelivery(hook, cursor, itemCount, status, cause.Error(), now)
+	if err := s.deliveries.RecordFailure(ctx, delivery); err != nil {
+		integrationLog.Warn(ctx, "记录失败推送失败", logger.String("hook_id", hook.ID), logger.ErrorField(err))
+	}
+	if paused := hook.RecordFailure(cause.Error(), now); paused && s.onPaused != nil {
+		s.onPaused(ctx, hook)
+	}
+}
+
+// renderHookPayload 按输出模板逐条改写条目
+// 条目先转换为 JSON 对象（数字为浮点数），模板中的键与默认推送格式一致
+func renderHookPayload(src string, items []IntegrationItem) ([]json.RawMessage, error) {
+	tmpl, err := integration.ParseTemplate(src)
+	if err != nil {
+		return nil, err
+	}
+	rendered := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode hook item: %w", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal(encoded, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode hook item: %w", err)
+		}
+		out, err := tmpl.Render(data)
+		if err != nil {
+			return nil, fmt.Errorf("记录 %s: %w", item.RecordID, err)
+		}
+		rendered = append(rendered, out)
+	}
+	return rendered, nil
+}
+
+// post 推送一批条目，返回响应状态码
+// 响应体只读取丢弃、不保存：推送目标由订阅者指定，保存并展示响应体会让订阅者读取任意地址的内容
+func (s *IntegrationService) post(ctx context.Context, targetURL string, payload interface{}) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode hook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("hook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("hook endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// validateOutboundURL 校验用户指定的推送地址：必须是 http(s) URL；禁止内网时拒绝 localhost 与内网 IP
+// （域名在连接时解析，由 netguard 在建立连接前再次检查）
+func validateOutboundURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return pkgerrors.ErrValidationFailed.WithDetails("推送地址必须是 http(s) URL")
+	}
+	if !allowPrivate {
+		if err := netguard.CheckURL(u); err != nil {
+			return pkgerrors.ErrValidationFailed.WithDetails("推送地址不能是内网或回环地址")
+		}
+	}
+	return nil
+}
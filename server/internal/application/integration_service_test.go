@@ -0,0 +1,109 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+)
+
+func TestIntegrationDedupeKey(t *testing.T) {
+	change := &changefeed.Change{ID: "chg1", EntityID: "rec1", Version: 3}
+	cases := []struct {
+		event integration.Event
+		want  string
+	}{
+		{integration.EventRecordCreated, "rec1"},
+		{integration.EventRecordUpdated, "rec1:3"},
+		{integration.EventRecordDeleted, "rec1"},
+		{integration.EventRecordTransitioned, "chg1"},
+	}
+	for _, c := range cases {
+		if got := integrationDedupeKey(c.event, change); got != c.want {
+			t.Errorf("%s: 期望 %s，得到 %s", c.event, c.want, got)
+		}
+	}
+}
+
+func TestNewIntegrationItem(t *testing.T) {
+	record := &dto.RecordResponse{
+		ID:      "rec1",
+		TableID: "tbl1",
+		Data:    map[string]interface{}{"fld1": "Alice", "fldGone": "x"},
+	}
+	item := newIntegrationItem(record, map[string]string{"fld1": "Name"})
+
+	if item.ID != "rec1" || item.RecordID != "rec1" || item.TableID != "tbl1" {
+		t.Fatalf("条目标识错误: %+v", item)
+	}
+	if item.Fields["Name"] != "Alice" {
+		t.Errorf("期望按字段名输出，得到 %v", item.Fields)
+	}
+	if len(item.Fields) != 1 {
+		t.Errorf("已删除字段不应输出，得到 %v", item.Fields)
+	}
+}
+
+func TestWebhookRecordMasksEncryptedFields(t *testing.T) {
+	name := newTemplateTestField(t, "姓名", "text")
+	secret := newTemplateTestField(t, "身份证号", "text")
+	if err := secret.EnableEncryption(false, false); err != nil {
+		t.Fatalf("开启加密失败: %v", err)
+	}
+	shared := newTemplateTestField(t, "工号", "text")
+	if err := shared.EnableEncryption(false, true); err != nil {
+		t.Fatalf("开启加密失败: %v", err)
+	}
+	fields := []*entity.Field{name, secret, shared}
+	record := &dto.RecordResponse{ID: "rec1", TableID: "tbl1", Data: map[string]interface{}{
+		name.ID().String():   "Alice",
+		secret.ID().String(): "110101199001011234",
+		shared.ID().String(): "E1024",
+	}}
+
+	masked := webhookRecord(fields, record)
+	if masked.Data[secret.ID().String()] != entity.MaskedCellValue {
+		t.Fatalf("未允许 Webhook 明文的加密字段应脱敏，得到 %v", masked.Data[secret.ID().String()])
+	}
+	if masked.Data[shared.ID().String()] != "E1024" || masked.Data[name.ID().String()] != "Alice" {
+		t.Fatalf("允许明文的字段应保留原值，得到 %v", masked.Data)
+	}
+	if record.Data[secret.ID().String()] != "110101199001011234" {
+		t.Fatal("不应修改原记录")
+	}
+}
+
+func TestValidateOutboundURL(t *testing.T) {
+	for _, raw := range []string{"ftp://example.com/hook", "http://localhost:8080/hook", "http://127.0.0.1/hook", "https://10.0.0.5/hook", "http://169.254.169.254/latest/meta-data"} {
+		if err := validateOutboundURL(raw, false); err == nil {
+			t.Errorf("%s 应被拒绝", raw)
+		}
+	}
+	if err := validateOutboundURL("https://hooks.zapier.com/hooks/catch/1", false); err != nil {
+		t.Errorf("公网地址应放行，得到 %v", err)
+	}
+	if err := validateOutboundURL("http://127.0.0.1/hook", true); err != nil {
+		t.Errorf("允许内网时应放行，得到 %v", err)
+	}
+}
+
+func TestDeliverPausesHookWhenCreatorLosesAccess(t *testing.T) {
+	fx := newBatchAuthorizationFixture(t)
+	var notified []string
+	service := &IntegrationService{
+		permissionService: fx.permissions,
+		onPaused:          func(_ context.Context, hook *integration.Hook) { notified = append(notified, hook.ID) },
+	}
+
+	// usr3 不是 Base 的协作者：推送前的权限检查失败，订阅被暂停且不会读取变更流
+	hook := integration.NewHook("bse1", fx.tableID, integration.EventRecordCreated, "https://example.com/hook", "", 0, 0, "usr3")
+	if gone := service.deliver(context.Background(), hook); gone {
+		t.Fatal("权限检查失败不应删除订阅")
+	}
+	if !hook.Paused() || len(notified) != 1 {
+		t.Fatalf("订阅者失去读取权限时应暂停并通知，得到 paused=%v notified=%v", hook.Paused(), notified)
+	}
+}
@@ -0,0 +1,334 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/maintenance"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var maintenanceLog = logger.Named("maintenance")
+
+// maintenanceHistory 管理员查看时返回的最近维护窗口条数
+const maintenanceHistory = 50
+
+// 维护模式事件的动作
+const (
+	MaintenanceActionStarted = "started"
+	MaintenanceActionEnded   = "ended"
+)
+
+// maintenanceResolver 解析资源所属的工作空间与 Base（由 SecurityService 实现，带缓存）
+type maintenanceResolver interface {
+	ResolveSpace(ctx context.Context, refs ResourceRefs) string
+	ResolveBase(ctx context.Context, refs ResourceRefs) string
+}
+
+// StartMaintenanceRequest 开启维护模式请求
+type StartMaintenanceRequest struct {
+	Scope   string     `json:"scope" binding:"required"` // instance、space 或 base
+	ScopeID string     `json:"scopeId"`                  // 工作空间或 Base ID，实例范围不需要
+	Message string     `json:"message"`                  // 展示给用户的提示
+	EndsAt  *time.Time `json:"endsAt"`                   // 计划结束时间，为空时需要手动结束
+}
+
+// MaintenanceOverview 当前生效与最近的维护窗口
+type MaintenanceOverview struct {
+	Active []*maintenance.Window `json:"active"`
+	Recent []*maintenance.Window `json:"recent"`
+}
+
+// MaintenanceEvent 推送给客户端的维护模式事件
+type MaintenanceEvent struct {
+	Action string              `json:"action"` // started 或 ended
+	Window *maintenance.Window `json:"window"`
+}
+
+// MaintenanceService 维护模式服务 ✨
+// 管理员可以将整个实例、工作空间或单个 Base 置为只读（用于迁移或故障处理）；
+// 记录、字段与表的写服务在写入前检查（其余写请求由中间件拒绝），返回 MAINTENANCE_READ_ONLY；
+// 开启与结束时通过实时事件通知客户端显示或隐藏提示横幅。
+// 生效中的维护窗口缓存在内存中并定期重新加载，检查不查库
+type MaintenanceService struct {
+	repo           maintenance.Repository
+	resolver       maintenanceResolver
+	businessEvents events.BusinessEventPublisher
+	cfg            config.MaintenanceConfig
+	now            func() time.Time
+	startedAt      time.Time
+
+	mu     sync.RWMutex
+	active []*maintenance.Window
+}
+
+// NewMaintenanceService 创建维护模式服务
+func NewMaintenanceService(
+	repo maintenance.Repository,
+	resolver maintenanceResolver,
+	businessEvents events.BusinessEventPublisher,
+	cfg config.MaintenanceConfig,
+) *MaintenanceService {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 15 * time.Second
+	}
+	return &MaintenanceService{
+		repo:           repo,
+		resolver:       resolver,
+		businessEvents: businessEvents,
+		cfg:            cfg,
+		now:            time.Now,
+		startedAt:      time.Now(),
+	}
+}
+
+// ==================== 管理操作 ====================
+
+// StartWindow 开启维护模式（同一范围已在维护中时返回冲突）
+func (s *MaintenanceService) StartWindow(ctx context.Context, userID string, req StartMaintenanceRequest) (*maintenance.Window, error) {
+	window, err := maintenance.NewWindow(maintenance.Scope(req.Scope), req.ScopeID, req.Message, userID, req.EndsAt, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, pkgerrors.Database(err, "获取维护状态失败")
+	}
+	for _, w := range s.windows() {
+		if w.Scope == window.Scope && w.ScopeID == window.ScopeID {
+			return nil, pkgerrors.ErrConflict.WithDetails(map[string]interface{}{
+				"message":   "该范围已在维护中",
+				"window_id": w.ID,
+			})
+		}
+	}
+
+	if err := s.repo.Create(ctx, window); err != nil {
+		return nil, pkgerrors.Database(err, "开启维护模式失败")
+	}
+	s.add(window)
+	s.publish(ctx, MaintenanceActionStarted, window, userID)
+
+	maintenanceLog.Warn(ctx, "维护模式已开启",
+		logger.String("window_id", window.ID),
+		logger.String("scope", string(window.Scope)),
+		logger.String("scope_id", window.ScopeID),
+		logger.String("user_id", userID))
+	return window, nil
+}
+
+// EndWindow 结束维护模式（配置文件开启的实例只读不能通过接口结束）
+func (s *MaintenanceService) EndWindow(ctx context.Context, userID, windowID string) (*maintenance.Window, error) {
+	if windowID == maintenance.ConfigWindowID {
+		return nil, pkgerrors.ErrOperationNotAllowed.WithDetails("实例只读由配置文件开启，需要修改 maintenance.read_only 后重启")
+	}
+	window, err := s.repo.FindByID(ctx, windowID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取维护窗口失败")
+	}
+	if window == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("维护窗口不存在")
+	}
+	if err := window.End(userID, s.now()); err != nil {
+		return nil, pkgerrors.ErrConflict.WithDetails(err.Error())
+	}
+	if err := s.repo.End(ctx, window); err != nil {
+		return nil, pkgerrors.Database(err, "结束维护模式失败")
+	}
+	s.remove(window.ID)
+	s.publish(ctx, MaintenanceActionEnded, window, userID)
+
+	maintenanceLog.Info(ctx, "维护模式已结束",
+		logger.String("window_id", window.ID),
+		logger.String("scope", string(window.Scope)),
+		logger.String("scope_id", window.ScopeID),
+		logger.String("user_id", userID))
+	return window, nil
+}
+
+// Overview 当前生效与最近的维护窗口
+func (s *MaintenanceService) Overview(ctx context.Context) (*MaintenanceOverview, error) {
+	if err := s.refresh(ctx); err != nil {
+		return nil, pkgerrors.Database(err, "获取维护状态失败")
+	}
+	recent, err := s.repo.ListRecent(ctx, maintenanceHistory)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取维护记录失败")
+	}
+	return &MaintenanceOverview{Active: s.windows(), Recent: recent}, nil
+}
+
+// ==================== 状态与检查 ====================
+
+// Status 资源所在范围的维护状态（refs 为空时只包含实例范围）
+func (s *MaintenanceService) Status(ctx context.Context, refs ResourceRefs) *maintenance.Status {
+	windows := s.effective(ctx, refs)
+	if windows == nil {
+		windows = []*maintenance.Window{}
+	}
+	return &maintenance.Status{ReadOnly: len(windows) > 0, Windows: windows}
+}
+
+// CheckWritable 检查资源是否允许写入（所在的实例、工作空间或 Base 维护中时只允许读取）
+func (s *MaintenanceService) CheckWritable(ctx context.Context, refs ResourceRefs) error {
+	windows := s.effective(ctx, refs)
+	if len(windows) == 0 {
+		return nil
+	}
+	w := windows[0]
+	details := map[string]interface{}{
+		"window_id": w.ID,
+		"scope":     w.Scope,
+	}
+	if w.ScopeID != "" {
+		details["scope_id"] = w.ScopeID
+	}
+	if w.Message != "" {
+		details["message"] = w.Message
+	}
+	if w.EndsAt != nil {
+		details["ends_at"] = w.EndsAt
+	}
+	return pkgerrors.ErrMaintenanceReadOnly.WithDetails(details)
+}
+
+// effective 覆盖资源的生效维护窗口；没有维护窗口时不解析资源
+func (s *MaintenanceService) effective(ctx context.Context, refs ResourceRefs) []*maintenance.Window {
+	windows := s.windows()
+	if len(windows) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	if scoped := maintenance.Effective(windows, "", "", now); len(scoped) > 0 {
+		return scoped // 实例只读时不必解析资源
+	}
+	var spaceID, baseID string
+	if s.resolver != nil {
+		baseID = s.resolver.ResolveBase(ctx, refs)
+		spaceID = s.resolver.ResolveSpace(ctx, refs)
+	}
+	return maintenance.Effective(windows, spaceID, baseID, now)
+}
+
+// windows 生效中的维护窗口（含配置文件开启的实例只读）
+func (s *MaintenanceService) windows() []*maintenance.Window {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	out := make([]*maintenance.Window, 0, len(s.active)+1)
+	if s.cfg.ReadOnly {
+		out = append(out, &maintenance.Window{
+			ID:        maintenance.ConfigWindowID,
+			Scope:     maintenance.ScopeInstance,
+			Message:   s.cfg.Message,
+			StartedAt: s.startedAt,
+		})
+	}
+	for _, w := range s.active {
+		if w.Active(now) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+func (s *MaintenanceService) add(window *maintenance.Window) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active = append(s.active, window)
+}
+
+func (s *MaintenanceService) remove(windowID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.active[:0:0]
+	for _, w := range s.active {
+		if w.ID != windowID {
+			kept = append(kept, w)
+		}
+	}
+	s.active = kept
+}
+
+// ==================== 后台任务 ====================
+
+// Start 加载维护窗口，之后定期结束到期窗口并重新加载（其他实例开启或结束的窗口在下个周期生效）
+func (s *MaintenanceService) Start(ctx context.Context) {
+	if err := s.refresh(ctx); err != nil {
+		maintenanceLog.Warn(ctx, "加载维护窗口失败", logger.ErrorField(err))
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.cfg.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.expire(ctx)
+			if err := s.refresh(ctx); err != nil {
+				maintenanceLog.Warn(ctx, "加载维护窗口失败", logger.ErrorField(err))
+			}
+		}
+	}()
+}
+
+// refresh 重新加载生效中的维护窗口
+func (s *MaintenanceService) refresh(ctx context.Context) error {
+	active, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.active = active
+	s.mu.Unlock()
+	return nil
+}
+
+// expire 结束计划结束时间已到的维护窗口并通知客户端
+func (s *MaintenanceService) expire(ctx context.Context) {
+	ended, err := s.repo.EndExpired(ctx, s.now())
+	for _, w := range ended {
+		s.publish(ctx, MaintenanceActionEnded, w, "")
+		maintenanceLog.Info(ctx, "维护模式已到期结束",
+			logger.String("window_id", w.ID),
+			logger.String("scope", string(w.Scope)),
+			logger.String("scope_id", w.ScopeID))
+	}
+	if err != nil {
+		maintenanceLog.Warn(ctx, "结束到期维护窗口失败", logger.ErrorField(err))
+	}
+}
+
+// publish 推送维护模式事件（实例范围广播到全部客户端）
+func (s *MaintenanceService) publish(ctx context.Context, action string, window *maintenance.Window, userID string) {
+	if s.businessEvents == nil {
+		return
+	}
+	var spaceID, baseID string
+	switch window.Scope {
+	case maintenance.ScopeSpace:
+		spaceID = window.ScopeID
+	case maintenance.ScopeBase:
+		baseID = window.ScopeID
+		if s.resolver != nil {
+			spaceID = s.resolver.ResolveSpace(ctx, ResourceRefs{BaseID: baseID})
+		}
+	}
+	event := &MaintenanceEvent{Action: action, Window: window}
+	if err := s.businessEvents.PublishMaintenanceEvent(ctx, spaceID, baseID, event, userID); err != nil {
+		maintenanceLog.Warn(ctx, "推送维护模式事件失败",
+			logger.String("window_id", window.ID),
+			logger.String("action", action),
+			logger.ErrorField(err))
+	}
+}
@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/maintenance"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+type memoryMaintenanceRepo struct {
+	windows []*maintenance.Window
+}
+
+func (r *memoryMaintenanceRepo) Create(_ context.Context, w *maintenance.Window) error {
+	copied := *w
+	r.windows = append(r.windows, &copied)
+	return nil
+}
+
+func (r *memoryMaintenanceRepo) FindByID(_ context.Context, id string) (*maintenance.Window, error) {
+	for _, w := range r.windows {
+		if w.ID == id {
+			copied := *w
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryMaintenanceRepo) End(_ context.Context, w *maintenance.Window) error {
+	for _, stored := range r.windows {
+		if stored.ID == w.ID && stored.EndedAt == nil {
+			stored.EndedBy, stored.EndedAt = w.EndedBy, w.EndedAt
+		}
+	}
+	return nil
+}
+
+func (r *memoryMaintenanceRepo) ListActive(context.Context) ([]*maintenance.Window, error) {
+	var out []*maintenance.Window
+	for _, w := range r.windows {
+		if w.EndedAt == nil {
+			copied := *w
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryMaintenanceRepo) ListRecent(_ context.Context, limit int) ([]*maintenance.Window, error) {
+	return r.windows, nil
+}
+
+func (r *memoryMaintenanceRepo) EndExpired(_ context.Context, now time.Time) ([]*maintenance.Window, error) {
+	var ended []*maintenance.Window
+	for _, w := range r.windows {
+		if w.EndedAt == nil && w.EndsAt != nil && !w.EndsAt.After(now) {
+			at := now
+			w.EndedAt = &at
+			ended = append(ended, w)
+		}
+	}
+	return ended, nil
+}
+
+// stubMaintenanceResolver 表 → Base → 空间的固定映射
+type stubMaintenanceResolver struct {
+	tableBases map[string]string
+	baseSpaces map[string]string
+}
+
+func (r stubMaintenanceResolver) ResolveBase(_ context.Context, refs ResourceRefs) string {
+	if refs.BaseID != "" {
+		return refs.BaseID
+	}
+	return r.tableBases[refs.TableID]
+}
+
+func (r stubMaintenanceResolver) ResolveSpace(ctx context.Context, refs ResourceRefs) string {
+	if refs.SpaceID != "" {
+		return refs.SpaceID
+	}
+	return r.baseSpaces[r.ResolveBase(ctx, refs)]
+}
+
+type recordingMaintenanceEvents struct {
+	events.BusinessEventPublisher
+	published []string
+}
+
+func (p *recordingMaintenanceEvents) PublishMaintenanceEvent(_ context.Context, spaceID, baseID string, data interface{}, _ string) error {
+	event := data.(*MaintenanceEvent)
+	p.published = append(p.published, event.Action+":"+spaceID+"/"+baseID)
+	return nil
+}
+
+func newTestMaintenanceService(cfg config.MaintenanceConfig) (*MaintenanceService, *memoryMaintenanceRepo, *recordingMaintenanceEvents) {
+	repo := &memoryMaintenanceRepo{}
+	publisher := &recordingMaintenanceEvents{}
+	resolver := stubMaintenanceResolver{
+		tableBases: map[string]string{"tbl_1": "bse_1", "tbl_2": "bse_2"},
+		baseSpaces: map[string]string{"bse_1": "spc_1", "bse_2": "spc_1", "bse_3": "spc_2"},
+	}
+	return NewMaintenanceService(repo, resolver, publisher, cfg), repo, publisher
+}
+
+func isMaintenanceReadOnly(err error) bool {
+	var appErr *pkgerrors.AppError
+	return errors.As(err, &appErr) && appErr.Code == pkgerrors.ErrMaintenanceReadOnly.Code
+}
+
+func TestMaintenanceBaseScope(t *testing.T) {
+	ctx := context.Background()
+	svc, _, publisher := newTestMaintenanceService(config.MaintenanceConfig{})
+
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_1"}); err != nil {
+		t.Fatalf("no maintenance should allow writes: %v", err)
+	}
+
+	window, err := svc.StartWindow(ctx, "usr_admin", StartMaintenanceRequest{Scope: "base", ScopeID: "bse_1", Message: "迁移中"})
+	if err != nil {
+		t.Fatalf("StartWindow: %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_1"}); !isMaintenanceReadOnly(err) {
+		t.Errorf("tables in the base should be read-only, got %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_2"}); err != nil {
+		t.Errorf("other bases should stay writable: %v", err)
+	}
+	if status := svc.Status(ctx, ResourceRefs{BaseID: "bse_1"}); !status.ReadOnly || status.Windows[0].Message != "迁移中" {
+		t.Errorf("status should report the banner, got %+v", status)
+	}
+
+	if _, err := svc.StartWindow(ctx, "usr_admin", StartMaintenanceRequest{Scope: "base", ScopeID: "bse_1"}); err == nil {
+		t.Errorf("a second window on the same base should conflict")
+	}
+
+	if _, err := svc.EndWindow(ctx, "usr_admin", window.ID); err != nil {
+		t.Fatalf("EndWindow: %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_1"}); err != nil {
+		t.Errorf("ended window should allow writes: %v", err)
+	}
+	if _, err := svc.EndWindow(ctx, "usr_admin", window.ID); err == nil {
+		t.Errorf("ending twice should conflict")
+	}
+
+	want := []string{"started:spc_1/bse_1", "ended:spc_1/bse_1"}
+	if len(publisher.published) != 2 || publisher.published[0] != want[0] || publisher.published[1] != want[1] {
+		t.Errorf("unexpected events %v", publisher.published)
+	}
+}
+
+func TestMaintenanceSpaceAndInstanceScope(t *testing.T) {
+	ctx := context.Background()
+	svc, _, publisher := newTestMaintenanceService(config.MaintenanceConfig{})
+
+	if _, err := svc.StartWindow(ctx, "usr_admin", StartMaintenanceRequest{Scope: "space", ScopeID: "spc_1"}); err != nil {
+		t.Fatalf("StartWindow: %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_2"}); !isMaintenanceReadOnly(err) {
+		t.Errorf("every base in the space should be read-only, got %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{BaseID: "bse_3"}); err != nil {
+		t.Errorf("other spaces should stay writable: %v", err)
+	}
+
+	if _, err := svc.StartWindow(ctx, "usr_admin", StartMaintenanceRequest{Scope: "instance"}); err != nil {
+		t.Fatalf("StartWindow: %v", err)
+	}
+	if err := svc.CheckWritable(ctx, ResourceRefs{}); !isMaintenanceReadOnly(err) {
+		t.Errorf("instance maintenance should block unresolved writes, got %v", err)
+	}
+	if got := publisher.published[len(publisher.published)-1]; got != "started:/" {
+		t.Errorf("instance event should be broadcast globally, got %q", got)
+	}
+}
+
+func TestMaintenanceExpiryAndConfig(t *testing.T) {
+	ctx := context.Background()
+	svc, _, publisher := newTestMaintenanceService(config.MaintenanceConfig{})
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	endsAt := now.Add(time.Minute)
+	if _, err := svc.StartWindow(ctx, "usr_admin", StartMaintenanceRequest{Scope: "base", ScopeID: "bse_1", EndsAt: &endsAt}); err != nil {
+		t.Fatalf("StartWindow: %v", err)
+	}
+	now = endsAt
+	if err := svc.CheckWritable(ctx, ResourceRefs{TableID: "tbl_1"}); err != nil {
+		t.Errorf("window past its planned end should no longer block writes: %v", err)
+	}
+	svc.expire(ctx)
+	if got := publisher.published[len(publisher.published)-1]; got != "ended:spc_1/bse_1" {
+		t.Errorf("expired window should notify clients, got %q", got)
+	}
+
+	readOnly, _, _ := newTestMaintenanceService(config.MaintenanceConfig{ReadOnly: true, Message: "只读"})
+	if err := readOnly.CheckWritable(ctx, ResourceRefs{TableID: "tbl_1"}); !isMaintenanceReadOnly(err) {
+		t.Errorf("configured read-only should block writes, got %v", err)
+	}
+	if _, err := readOnly.EndWindow(ctx, "usr_admin", maintenance.ConfigWindowID); err == nil {
+		t.Errorf("configured read-only cannot be ended through the API")
+	}
+}
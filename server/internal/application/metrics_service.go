@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+)
+
+// MetricsService 抓取时计算的指标 ✨
+// 连接池、任务积压、Webhook 投递结果与实时连接数在每次抓取时读取当前值，不需要后台采集协程
+type MetricsService struct {
+	db               *gorm.DB
+	healthService    *HealthService
+	connectionCounts func() map[string]int
+}
+
+// NewMetricsService 创建指标服务
+func NewMetricsService(db *gorm.DB, healthService *HealthService) *MetricsService {
+	return &MetricsService{db: db, healthService: healthService}
+}
+
+// SetConnectionCounter 设置实时连接数来源（实时通信未启用时不设置）
+func (s *MetricsService) SetConnectionCounter(fn func() map[string]int) {
+	s.connectionCounts = fn
+}
+
+// Register 注册抓取时计算的指标
+func (s *MetricsService) Register(registry *metrics.Registry) error {
+	collectors := []metrics.Collector{
+		metrics.NewGaugeFunc("luckdb_db_connections", "数据库连接池连接数",
+			[]string{"state"}, s.dbConnections),
+		metrics.NewGaugeFunc("luckdb_job_queue_depth", "后台任务积压数量",
+			[]string{"queue"}, s.jobQueueDepth),
+		metrics.NewGaugeFunc("luckdb_job_queue_oldest_age_seconds", "最早一条积压任务的等待时长",
+			[]string{"queue"}, s.jobQueueAge),
+		metrics.NewGaugeFunc("luckdb_webhook_deliveries", "Webhook 投递记录数（按状态）",
+			[]string{"status"}, s.webhookDeliveries),
+		metrics.NewGaugeFunc("luckdb_cache_hit_ratio", "进程启动以来的缓存命中率",
+			nil, s.cacheHitRatio),
+		metrics.NewGaugeFunc("luckdb_realtime_connections", "实时通信连接数",
+			[]string{"transport"}, s.realtimeConnections),
+	}
+	for _, c := range collectors {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MetricsService) dbConnections(ctx context.Context) []metrics.Sample {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil
+	}
+	stats := sqlDB.Stats()
+	return []metrics.Sample{
+		{LabelValues: []string{"open"}, Value: float64(stats.OpenConnections)},
+		{LabelValues: []string{"in_use"}, Value: float64(stats.InUse)},
+		{LabelValues: []string{"idle"}, Value: float64(stats.Idle)},
+		{LabelValues: []string{"max_open"}, Value: float64(stats.MaxOpenConnections)},
+	}
+}
+
+func (s *MetricsService) jobQueueDepth(ctx context.Context) []metrics.Sample {
+	stats, err := s.healthService.JobQueueStats(ctx)
+	if err != nil {
+		logger.Warn("采集任务积压指标失败", logger.ErrorField(err))
+	}
+	samples := make([]metrics.Sample, 0, len(stats))
+	for _, stat := range stats {
+		samples = append(samples, metrics.Sample{LabelValues: []string{stat.Name}, Value: float64(stat.Depth)})
+	}
+	return samples
+}
+
+func (s *MetricsService) jobQueueAge(ctx context.Context) []metrics.Sample {
+	stats, _ := s.healthService.JobQueueStats(ctx)
+	samples := make([]metrics.Sample, 0, len(stats))
+	for _, stat := range stats {
+		samples = append(samples, metrics.Sample{LabelValues: []string{stat.Name}, Value: stat.OldestAge.Seconds()})
+	}
+	return samples
+}
+
+func (s *MetricsService) webhookDeliveries(ctx context.Context) []metrics.Sample {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := s.db.WithContext(ctx).Table("notification_delivery").
+		Select("status, COUNT(*) AS count").
+		Where("channel = ? AND deleted_time IS NULL", "webhook").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		logger.Warn("采集 Webhook 投递指标失败", logger.ErrorField(err))
+		return nil
+	}
+	samples := make([]metrics.Sample, 0, len(rows))
+	for _, row := range rows {
+		samples = append(samples, metrics.Sample{LabelValues: []string{row.Status}, Value: float64(row.Count)})
+	}
+	return samples
+}
+
+func (s *MetricsService) cacheHitRatio(ctx context.Context) []metrics.Sample {
+	hits := metrics.CacheRequestsTotal.WithLabelValues("local", "hit").Value() +
+		metrics.CacheRequestsTotal.WithLabelValues("redis", "hit").Value()
+	misses := metrics.CacheRequestsTotal.WithLabelValues("all", "miss").Value()
+	if hits+misses == 0 {
+		return nil
+	}
+	return []metrics.Sample{{Value: hits / (hits + misses)}}
+}
+
+func (s *MetricsService) realtimeConnections(ctx context.Context) []metrics.Sample {
+	if s.connectionCounts == nil {
+		return nil
+	}
+	counts := s.connectionCounts()
+	samples := make([]metrics.Sample, 0, len(counts))
+	for transport, count := range counts {
+		samples = append(samples, metrics.Sample{LabelValues: []string{transport}, Value: float64(count)})
+	}
+	return samples
+}
@@ -16,6 +16,7 @@ import (
 	"gorm.io/gorm/logger"
 
 	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	appLogger "github.com/easyspace-ai/luckdb/server/pkg/logger"
@@ -140,13 +141,44 @@ func (s *MigrateService) RunGORMAutoMigrate(ctx context.Context) error {
 
 	if err := s.executeGORMAutoMigrate(ctx); err != nil {
 		s.logger.Error("GORM AutoMigrate 执行失败", zap.Error(err))
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	s.logger.Info("GORM AutoMigrate 执行成功")
 	return nil
 }
 
+// RunTenantMigrations 对所有租户Schema执行租户级迁移（仅 workspace 隔离模式）
+func (s *MigrateService) RunTenantMigrations(ctx context.Context) ([]database.TenantMigrationResult, error) {
+	if !s.config.Database.Tenancy.IsWorkspaceIsolation() {
+		return nil, errors.ErrOperationNotAllowed.WithDetails("租户迁移仅在 database.tenancy.mode=workspace 时可用")
+	}
+
+	db, err := s.connectGORM()
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	migrator := database.NewTenantMigrator(db, database.NewPostgresProvider(db), s.config.Database.Tenancy.SchemaPrefix)
+	results, err := migrator.MigrateAll(ctx)
+	if err != nil {
+		return nil, errors.Database(err, "")
+	}
+
+	for _, r := range results {
+		if r.Error != "" {
+			s.logger.Error("租户迁移失败", zap.String("space_id", r.SpaceID), zap.String("error", r.Error))
+		} else {
+			s.logger.Info("租户迁移完成", zap.String("space_id", r.SpaceID), zap.Strings("applied", r.Applied))
+		}
+	}
+
+	return results, nil
+}
+
 // GetMigrationVersion 获取当前迁移版本
 func (s *MigrateService) GetMigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
 	s.logger.Info("获取迁移版本信息")
@@ -160,7 +192,7 @@ func (s *MigrateService) GetMigrationVersion(ctx context.Context) (version uint,
 
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		return 0, false, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return 0, false, errors.Database(err, "")
 	}
 
 	m, err := migrate.NewWithDatabaseInstance(
@@ -169,7 +201,7 @@ func (s *MigrateService) GetMigrationVersion(ctx context.Context) (version uint,
 		driver,
 	)
 	if err != nil {
-		return 0, false, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return 0, false, errors.Database(err, "")
 	}
 	defer m.Close()
 
@@ -204,7 +236,7 @@ func (s *MigrateService) executeGolangMigrate(ctx context.Context, command, vers
 	// 创建 postgres driver
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	// 创建 migrate 实例
@@ -214,7 +246,7 @@ func (s *MigrateService) executeGolangMigrate(ctx context.Context, command, vers
 		driver,
 	)
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	defer m.Close()
 
@@ -222,14 +254,14 @@ func (s *MigrateService) executeGolangMigrate(ctx context.Context, command, vers
 	switch command {
 	case "up":
 		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 		if err == migrate.ErrNoChange {
 			s.logger.Info("没有需要执行的迁移")
 		}
 	case "down":
 		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 	case "force":
 		if version == "" {
@@ -238,7 +270,7 @@ func (s *MigrateService) executeGolangMigrate(ctx context.Context, command, vers
 		var v int
 		fmt.Sscanf(version, "%d", &v)
 		if err := m.Force(v); err != nil {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 	case "drop":
 		// 先清理 UUID schema，再执行标准 drop
@@ -246,7 +278,7 @@ func (s *MigrateService) executeGolangMigrate(ctx context.Context, command, vers
 			s.logger.Warn("清理 UUID schema 失败", zap.Error(err))
 		}
 		if err := m.Drop(); err != nil {
-			return errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return errors.Database(err, "")
 		}
 	default:
 		return errors.ErrBadRequest.WithDetails(fmt.Sprintf("未知命令: %s", command))
@@ -315,7 +347,7 @@ func (s *MigrateService) cleanupUUIDSchemas(db *sql.DB) error {
 func (s *MigrateService) executeGORMAutoMigrate(ctx context.Context) error {
 	db, err := s.connectGORM()
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	sqlDB, _ := db.DB()
@@ -323,26 +355,70 @@ func (s *MigrateService) executeGORMAutoMigrate(ctx context.Context) error {
 
 	s.logger.Info("数据库连接成功")
 
+	// 启用扩展（位置字段的半径过滤依赖 earthdistance，记录向量列依赖 vector，需在建表前启用）
+	s.createExtensions(db)
+
 	// 执行 AutoMigrate
 	startTime := time.Now()
 	if err := s.runAutoMigrate(db); err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 
 	duration := time.Since(startTime)
 	s.logger.Info("AutoMigrate 完成", zap.Duration("duration", duration))
 
-	// 添加补充索引
-	s.logger.Info("添加补充索引和约束...")
-	if err := s.addSupplementaryIndexes(db); err != nil {
-		s.logger.Warn("部分索引添加失败", zap.Error(err))
-	} else {
-		s.logger.Info("补充索引添加完成")
+	// 补充索引等由在线迁移维护（CREATE INDEX CONCURRENTLY，不阻塞写入），这里只执行扩展迁移
+	s.logger.Info("执行在线扩展迁移...")
+	result, err := database.NewOnlineMigrator(db, s.config.Database.OnlineMigration).Run(ctx, false)
+	switch {
+	case err != nil:
+		s.logger.Warn("在线迁移执行失败", zap.Error(err))
+	case result.Skipped:
+		s.logger.Warn("其他实例正在执行在线迁移，已跳过")
+	case result.Error != "":
+		s.logger.Warn("部分在线迁移失败", zap.Strings("applied", result.Applied), zap.String("error", result.Error))
+	default:
+		s.logger.Info("在线扩展迁移完成", zap.Strings("applied", result.Applied))
 	}
 
 	return nil
 }
 
+// RunOnlineMigrations 执行元数据表的在线迁移；contract 为 true 时同时执行收缩迁移
+// （收缩迁移会删除旧版本代码依赖的结构，只能在所有实例升级后执行）
+func (s *MigrateService) RunOnlineMigrations(ctx context.Context, contract bool) (*database.OnlineMigrationResult, error) {
+	db, err := s.connectGORM()
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	result, err := database.NewOnlineMigrator(db, s.config.Database.OnlineMigration).Run(ctx, contract)
+	if err != nil {
+		return nil, errors.Database(err, "")
+	}
+	return result, nil
+}
+
+// GetOnlineMigrationStatus 获取在线迁移状态
+func (s *MigrateService) GetOnlineMigrationStatus(ctx context.Context) (*database.OnlineMigrationStatus, error) {
+	db, err := s.connectGORM()
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	status, err := database.NewOnlineMigrator(db, s.config.Database.OnlineMigration).Status(ctx)
+	if err != nil {
+		return nil, errors.Database(err, "")
+	}
+	return status, nil
+}
+
 // connectGORM 连接 GORM 数据库
 func (s *MigrateService) connectGORM() (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -365,7 +441,7 @@ func (s *MigrateService) connectGORM() (*gorm.DB, error) {
 
 	sqlDB, err := db.DB()
 	if err != nil {
-		return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return nil, errors.Database(err, "")
 	}
 
 	if err := sqlDB.Ping(); err != nil {
@@ -396,6 +472,7 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 		// &models.Invitation{},        // TODO: Invitation模型待实现
 		// &models.InvitationRecord{},  // TODO: InvitationRecord模型待实现
 		&models.RecordChange{},
+		&models.RecordCellChange{}, // 单元格修改历史（变更日志的逐单元格投影）
 		&models.RecordVersion{},
 		&models.Ops{},
 		&models.Reference{},
@@ -411,6 +488,7 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 		&models.Plugin{},
 		&models.PluginInstall{},
 		&models.Dashboard{},
+		&models.DashboardWidget{},
 		&models.PluginPanel{},
 		&models.PluginContextMenu{},
 		// Note: PluginPanel defined in plugin.go ✅
@@ -496,6 +574,170 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 		// 虚拟字段支持
 		&models.FieldDependency{},
 		&models.VirtualFieldCache{},
+
+		// 数据驻留
+		&models.SpaceRegion{},
+
+		// 字段加密
+		&models.SpaceDataKey{},
+
+		// 隐私（PII检测与脱敏）
+		&models.FieldMaskingPolicy{},
+		&models.FieldPIIFinding{},
+		&models.GDPRErasureJob{},
+
+		// 安全策略
+		&models.SpaceSecurityPolicy{},
+		&models.UserSession{},
+
+		// 幂等键
+		&models.IdempotencyKey{},
+
+		// 动态表结构变更任务
+		&models.SchemaMigration{},
+
+		// 长时操作
+		&models.Operation{},
+
+		// 变更流发件箱
+		&models.ChangeEvent{},
+
+		// 复制目标（逻辑复制 / Debezium 兼容推送）
+		&models.ReplicationTarget{},
+
+		// 数据仓库同步任务与运行记录
+		&models.WarehouseSyncJob{},
+		&models.WarehouseSyncRun{},
+
+		// 集成平台 REST Hook 订阅与失败推送
+		&models.IntegrationHook{},
+		&models.IntegrationDelivery{},
+
+		// 嵌入设置
+		&models.SpaceEmbedSettings{},
+
+		// 服务令牌
+		&models.ServiceToken{},
+
+		// 支持人员代入会话
+		&models.ImpersonationSession{},
+
+		// 工作空间套餐
+		&models.SpacePlan{},
+		&models.SpaceSubscription{},
+		&models.BillingEvent{},
+
+		// 管理控制台
+		&models.AdminConsoleSession{},
+		&models.FeatureFlagOverride{},
+
+		// 维护模式（只读窗口）
+		&models.MaintenanceWindow{},
+
+		// Base 沙盒分支
+		&models.BaseBranch{},
+
+		// 工作空间用量汇总
+		&models.UsageDaily{},
+
+		// 工作空间附件策略
+		&models.SpaceAttachmentPolicy{},
+
+		// 附件内容寻址存储（引用计数）
+		&models.AttachmentBlob{},
+
+		// 附件可续传上传
+		&models.AttachmentUploadSession{},
+
+		// AI 字段生成任务与空间配额
+		&models.AIGenerationJob{},
+		&models.SpaceAIQuota{},
+
+		// 记录语义索引与向量
+		&models.SemanticIndex{},
+		&models.RecordEmbedding{},
+
+		// 附件文本提取规则与任务
+		&models.TextExtractionRule{},
+		&models.TextExtractionJob{},
+
+		// 记录模板
+		&models.RecordTemplate{},
+
+		// 记录编辑锁
+		&models.RecordLock{},
+
+		// 单选字段状态流（审批流）
+		&models.StatusFlow{},
+
+		// 记录归档策略与归档记录（冷存储）
+		&models.RecordArchivePolicy{},
+		&models.ArchivedRecord{},
+
+		// Base 数据导出任务（Parquet）
+		&models.DataExport{},
+
+		// 跨区域灾备复制进度与提升记录（写入备区域）
+		&models.DRCheckpoint{},
+		&models.DRPromotion{},
+
+		// 工作空间生命周期与完整导出包
+		&models.WorkspaceLifecycle{},
+		&models.WorkspaceExportBundle{},
+
+		// 重复记录规则、执行记录与节假日日历
+		&models.RecurrenceRule{},
+		&models.RecurrenceRun{},
+		&models.HolidayCalendar{},
+
+		// 工作空间工作日历（休息日、节假日与调休）
+		&models.SpaceBusinessCalendar{},
+
+		// 记录与视图的永久短链接
+		&models.Permalink{},
+
+		// 记录提及的反向链接
+		&models.RecordBacklink{},
+
+		// 记录与视图的 PDF 打印任务
+		&models.PrintJob{},
+
+		// 文档模板与生成任务
+		&models.DocumentTemplate{},
+		&models.DocumentJob{},
+
+		// 字段的扫码规则
+		&models.ScanRule{},
+
+		// 移动端离线修改登记
+		&models.OfflineMutation{},
+
+		// 表健康检查报告、问题、修复任务与定时设置
+		&models.TableHealthReport{},
+		&models.TableHealthIssue{},
+		&models.TableHealthFixJob{},
+		&models.TableHealthSchedule{},
+
+		// 查找替换变更记录与撤销登记
+		&models.FindReplaceChange{},
+		&models.FindReplaceUndo{},
+
+		// 同步表与记录对应关系
+		&models.SyncedTable{},
+		&models.SyncedTableRow{},
+
+		// 外部数据同步连接器、运行记录与行对应关系
+		&models.ExternalSyncConnector{},
+		&models.ExternalSyncRun{},
+		&models.ExternalSyncRow{},
+
+		// 界面页面
+		&models.Page{},
+
+		// 视图展示投影、投影行与关联引用
+		&models.ViewProjection{},
+		&models.ViewProjectionRow{},
+		&models.ViewProjectionRef{},
 	}
 
 	s.logger.Info("开始迁移模型", zap.Int("model_count", len(allModels)))
@@ -530,37 +772,14 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 	return nil
 }
 
-// addSupplementaryIndexes 添加补充索引
-func (s *MigrateService) addSupplementaryIndexes(db *gorm.DB) error {
-	// GORM AutoMigrate 不会自动创建的复合索引和特殊约束
-	indexes := []string{
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_oauth_authorized_client_user ON oauth_app_authorized(client_id, user_id)",
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_reference_to_from ON reference(to_field_id, from_field_id)",
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_task_reference_to_from ON task_reference(to_field_id, from_field_id)",
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_collab_rt_rid_pid_pt ON collaborator(principal_id, principal_type, resource_id, resource_type)",
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_ops_collection_docid_version ON ops(collection, doc_id, version)",
-		"CREATE INDEX IF NOT EXISTS idx_ops_collection_created_time ON ops(collection, created_time)",
-		"CREATE INDEX IF NOT EXISTS idx_comment_record_table ON comment(record_id, table_id)",
-		"CREATE UNIQUE INDEX IF NOT EXISTS uq_comment_subscription ON comment_subscription(table_id, record_id)",
-		"CREATE INDEX IF NOT EXISTS idx_record_history_table_record_created ON record_history(table_id, record_id, created_time)",
-		"CREATE INDEX IF NOT EXISTS idx_record_history_table_created ON record_history(table_id, created_time)",
-		"CREATE INDEX IF NOT EXISTS idx_record_trash_table_record ON record_trash(table_id, record_id)",
-		"CREATE INDEX IF NOT EXISTS idx_attachments_table_field ON attachments_table(table_id, field_id)",
-		"CREATE INDEX IF NOT EXISTS idx_attachments_table_record_field ON attachments_table(record_id, table_id, field_id)",
-	}
-
-	s.logger.Info("创建补充索引", zap.Int("index_count", len(indexes)))
-
-	for i, sql := range indexes {
-		if err := db.Exec(sql).Error; err != nil {
-			s.logger.Warn("索引创建失败（可能已存在）",
-				zap.Int("index", i+1),
-				zap.Error(err),
-			)
+// createExtensions 启用可选扩展，失败时（如权限不足）只记录警告
+func (s *MigrateService) createExtensions(db *gorm.DB) {
+	extensions := []string{"cube", "earthdistance", "vector"}
+	for _, ext := range extensions {
+		if err := db.Exec("CREATE EXTENSION IF NOT EXISTS " + ext).Error; err != nil {
+			s.logger.Warn("扩展启用失败", zap.String("extension", ext), zap.Error(err))
 		}
 	}
-
-	return nil
 }
 
 // getDatabaseStats 获取数据库统计信息
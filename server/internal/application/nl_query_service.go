@@ -0,0 +1,426 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/nlquery"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var nlQueryLog = logger.Named("nl_query")
+
+// nlQueryDefaultLimit 默认返回的记录数
+const nlQueryDefaultLimit = 20
+
+// nlQueryOperators 各存储类别允许模型使用的过滤操作符
+var nlQueryOperators = map[columnKind][]viewVO.FilterItemOperator{
+	columnKindText: {
+		viewVO.FilterItemOpIs, viewVO.FilterItemOpIsNot, viewVO.FilterItemOpContains, viewVO.FilterItemOpNotContains,
+		viewVO.FilterItemOpHasAnyOf, viewVO.FilterItemOpHasNoneOf,
+	},
+	columnKindNumber: {
+		viewVO.FilterItemOpIs, viewVO.FilterItemOpIsNot, viewVO.FilterItemOpGreater, viewVO.FilterItemOpGreaterEqual,
+		viewVO.FilterItemOpLess, viewVO.FilterItemOpLessEqual, viewVO.FilterItemOpIsWithin,
+	},
+	columnKindDate: {
+		viewVO.FilterItemOpIs, viewVO.FilterItemOpIsNot, viewVO.FilterItemOpIsBefore, viewVO.FilterItemOpIsAfter,
+		viewVO.FilterItemOpGreater, viewVO.FilterItemOpGreaterEqual, viewVO.FilterItemOpLess, viewVO.FilterItemOpLessEqual,
+		viewVO.FilterItemOpIsWithin,
+	},
+	columnKindBoolean: {viewVO.FilterItemOpIs, viewVO.FilterItemOpIsNot},
+	columnKindJSON: {
+		viewVO.FilterItemOpHasAnyOf, viewVO.FilterItemOpHasAllOf, viewVO.FilterItemOpHasNoneOf,
+		viewVO.FilterItemOpIsExactly, viewVO.FilterItemOpIsNotExactly, viewVO.FilterItemOpContains, viewVO.FilterItemOpNotContains,
+	},
+	columnKindArray: {
+		viewVO.FilterItemOpHasAnyOf, viewVO.FilterItemOpHasAllOf, viewVO.FilterItemOpHasNoneOf,
+		viewVO.FilterItemOpIsExactly, viewVO.FilterItemOpIsNotExactly,
+	},
+}
+
+// NLQueryRequest 自然语言查询请求
+type NLQueryRequest struct {
+	Question string `json:"question"`
+	TimeZone string `json:"timeZone,omitempty"` // IANA 时区，用于换算“本周”等相对日期，默认 UTC
+	Limit    int    `json:"limit,omitempty"`
+}
+
+// NLQueryResponse 自然语言查询结果：生成的过滤与排序条件及满足条件的记录
+// 条件与视图的过滤器、排序结构一致，可直接保存为视图
+type NLQueryResponse struct {
+	Question    string                `json:"question"`
+	Filter      *viewVO.Filter        `json:"filter"`
+	Sort        []viewVO.SortItem     `json:"sort"`
+	Explanation string                `json:"explanation,omitempty"`
+	Records     []*dto.RecordResponse `json:"records"`
+	Total       int64                 `json:"total"`
+	Provider    string                `json:"provider"`
+	Model       string                `json:"model,omitempty"`
+}
+
+// NLQueryService 自然语言查询服务 ✨
+// 把问题和表结构交给大模型生成过滤与排序条件，按表结构校验后交给视图过滤引擎在物理表上执行；
+// 当前角色下脱敏的字段与加密字段不提供给模型，也不允许出现在条件中
+type NLQueryService struct {
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	providers         map[string]aifield.Provider
+	defaultProvider   string
+	permissionService *PermissionServiceV2
+	cfg               config.NLQueryConfig
+	now               func() time.Time
+
+	// 物理表查询（测试中替换）
+	runQuery func(ctx context.Context, baseID, tableID string, query *nlCompiledQuery, limit int) ([]string, int64, error)
+}
+
+// nlCompiledQuery 校验后的查询条件
+type nlCompiledQuery struct {
+	spec     *nlquery.Spec
+	fields   map[string]*fieldEntity.Field
+	provider string
+	model    string
+}
+
+// NewNLQueryService 创建自然语言查询服务
+func NewNLQueryService(
+	tableRepository tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	recordRepository recordRepo.RecordRepository,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	providers map[string]aifield.Provider,
+	defaultProvider string,
+	permissionService *PermissionServiceV2,
+	cfg config.NLQueryConfig,
+) *NLQueryService {
+	s := &NLQueryService{
+		tableRepo:         tableRepository,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepository,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		providers:         providers,
+		defaultProvider:   defaultProvider,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+	s.runQuery = s.physicalQuery
+	return s
+}
+
+// Query 用自然语言查询表中的记录
+func (s *NLQueryService) Query(ctx context.Context, userID, tableID string, req NLQueryRequest) (*NLQueryResponse, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格的记录")
+	}
+	return s.query(ctx, userID, tableID, req)
+}
+
+// query 生成条件并执行（调用方负责权限校验）
+func (s *NLQueryService) query(ctx context.Context, userID, tableID string, req NLQueryRequest) (*NLQueryResponse, error) {
+	question := strings.TrimSpace(req.Question)
+	if question == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("问题不能为空")
+	}
+	if utf8.RuneCountInString(question) > nlquery.MaxQuestionLength {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("问题不能超过 %d 个字符", nlquery.MaxQuestionLength))
+	}
+	loc := time.UTC
+	if req.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(req.TimeZone); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("无效的时区: " + req.TimeZone)
+		}
+	}
+	limit := req.Limit
+	maxResults := s.cfg.MaxResults
+	if maxResults <= 0 {
+		maxResults = 100
+	}
+	if limit <= 0 {
+		limit = nlQueryDefaultLimit
+	}
+	if limit > maxResults {
+		limit = maxResults
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	compiled, err := s.compile(ctx, userID, tableID, table.Name().String(), question, loc)
+	if err != nil {
+		return nil, err
+	}
+	ids, total, err := s.runQuery(ctx, table.BaseID(), tableID, compiled, limit)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.loadRecords(ctx, tableID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NLQueryResponse{
+		Question:    question,
+		Filter:      compiled.spec.Filter,
+		Sort:        compiled.spec.Sort,
+		Explanation: compiled.spec.Explanation,
+		Records:     records,
+		Total:       total,
+		Provider:    compiled.provider,
+		Model:       compiled.model,
+	}, nil
+}
+
+// compile 调用模型生成条件，并按当前角色可见的字段校验
+func (s *NLQueryService) compile(ctx context.Context, userID, tableID, tableName, question string, loc *time.Location) (*nlCompiledQuery, error) {
+	providerName := s.cfg.Provider
+	if providerName == "" {
+		providerName = s.defaultProvider
+	}
+	provider := s.providers[providerName]
+	if provider == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("未配置 AI 服务商: %s", providerName))
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	}
+	allowed := make(map[string]*fieldEntity.Field, len(fields))
+	infos := make([]nlquery.FieldInfo, 0, len(fields))
+	for _, field := range fields {
+		id := field.ID().String()
+		if protected[id] || field.IsEncrypted() || columnKindOf(field) == columnKindLocation {
+			continue
+		}
+		if _, ok := nlQueryOperators[columnKindOf(field)]; !ok {
+			continue
+		}
+		allowed[id] = field
+		info := nlquery.FieldInfo{ID: id, Name: field.Name().String(), Type: field.Type().String()}
+		if options := field.Options(); options != nil && options.Select != nil {
+			for _, choice := range options.Select.Choices {
+				info.Choices = append(info.Choices, choice.Name)
+			}
+		}
+		infos = append(infos, info)
+	}
+	if len(infos) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("表格没有可用于查询的字段")
+	}
+
+	genCtx := ctx
+	if s.cfg.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		genCtx, cancel = context.WithTimeout(ctx, s.cfg.RequestTimeout)
+		defer cancel()
+	}
+	temperature := 0.0
+	result, err := provider.Generate(genCtx, &aifield.GenerateRequest{
+		Model:        s.cfg.Model,
+		SystemPrompt: nlquery.SystemPrompt,
+		Prompt:       nlquery.BuildPrompt(question, tableName, infos, s.now().In(loc)),
+		MaxTokens:    s.cfg.MaxTokens,
+		Temperature:  &temperature,
+		JSON:         true,
+	})
+	if err != nil {
+		nlQueryLog.Warn(ctx, "生成查询条件失败",
+			logger.String("table_id", tableID),
+			logger.String("provider", providerName),
+			logger.ErrorField(err))
+		return nil, pkgerrors.ErrInternalServer.WithDetails("生成查询条件失败: " + err.Error())
+	}
+
+	spec, err := nlquery.ParseSpec(result.Text)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("无法将问题转换为查询条件: " + err.Error())
+	}
+	spec.ResolveCurrentUser(userID)
+	if err := validateNLSpec(spec, allowed); err != nil {
+		return nil, err
+	}
+	return &nlCompiledQuery{spec: spec, fields: allowed, provider: providerName, model: result.Model}, nil
+}
+
+// validateNLSpec 校验生成的条件只引用可见字段，且操作符与值符合字段的存储类型
+func validateNLSpec(spec *nlquery.Spec, fields map[string]*fieldEntity.Field) error {
+	if spec.Filter != nil {
+		for _, item := range spec.Filter.Filters {
+			field := fields[item.FieldID]
+			if field == nil {
+				return pkgerrors.ErrValidationFailed.WithDetails("生成的条件引用了不存在或不可见的字段: " + item.FieldID)
+			}
+			if item.Operator == viewVO.FilterItemOpIsEmpty || item.Operator == viewVO.FilterItemOpIsNotEmpty {
+				continue
+			}
+			kind := columnKindOf(field)
+			if !nlOperatorAllowed(kind, item.Operator) {
+				return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不支持 %s 条件", field.Name().String(), item.Operator))
+			}
+			if err := validateNLValue(field, kind, item); err != nil {
+				return err
+			}
+		}
+	}
+	for _, item := range spec.Sort {
+		if fields[item.FieldID] == nil {
+			return pkgerrors.ErrValidationFailed.WithDetails("生成的排序引用了不存在或不可见的字段: " + item.FieldID)
+		}
+	}
+	return nil
+}
+
+func nlOperatorAllowed(kind columnKind, op viewVO.FilterItemOperator) bool {
+	for _, allowed := range nlQueryOperators[kind] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNLValue 数字与日期比较的值必须可解析，避免生成无效的 SQL
+func validateNLValue(field *fieldEntity.Field, kind columnKind, item viewVO.FilterItem) error {
+	values := []interface{}{item.Value}
+	if item.Operator == viewVO.FilterItemOpIsWithin {
+		values = filterValues(item.Value)
+		if len(values) != 2 {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的范围条件需要两个值", field.Name().String()))
+		}
+	}
+	for _, value := range values {
+		switch kind {
+		case columnKindNumber:
+			if !isNumericValue(value) {
+				return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的条件值不是数字: %v", field.Name().String(), value))
+			}
+		case columnKindDate:
+			if !isDateValue(value) {
+				return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的条件值不是日期: %v", field.Name().String(), value))
+			}
+		case columnKindBoolean:
+			if _, ok := value.(bool); !ok {
+				return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的条件值应为 true 或 false", field.Name().String()))
+			}
+		}
+	}
+	return nil
+}
+
+func isNumericValue(value interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		return true
+	case string:
+		_, err := strconv.ParseFloat(v, 64)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+func isDateValue(value interface{}) bool {
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if _, err := time.Parse(layout, text); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// physicalQuery 在物理表上执行过滤与排序，返回一页记录ID与满足条件的总数
+func (s *NLQueryService) physicalQuery(ctx context.Context, baseID, tableID string, query *nlCompiledQuery, limit int) ([]string, int64, error) {
+	db := s.dataDB(ctx, baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(baseID, tableID))
+	if query.spec.Filter != nil {
+		condition, err := buildViewFilterCondition(ctx, query.spec.Filter, query.fields)
+		if err != nil {
+			return nil, 0, err
+		}
+		if condition != nil {
+			db = db.Where(condition)
+		}
+	}
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, pkgerrors.Database(err, "统计记录失败")
+	}
+	for _, order := range embedSortColumns(&viewVO.Sort{SortItems: query.spec.Sort}, query.fields) {
+		db = db.Order(order)
+	}
+	var ids []string
+	if err := db.Limit(limit).Pluck("__id", &ids).Error; err != nil {
+		return nil, 0, pkgerrors.Database(err, "查询记录失败")
+	}
+	return ids, total, nil
+}
+
+// loadRecords 按ID顺序读取记录并按当前角色脱敏
+func (s *NLQueryService) loadRecords(ctx context.Context, tableID string, ids []string) ([]*dto.RecordResponse, error) {
+	if len(ids) == 0 {
+		return []*dto.RecordResponse{}, nil
+	}
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+
+	byID := make(map[string]*dto.RecordResponse, len(entities))
+	for _, record := range entities {
+		byID[record.ID().String()] = dto.FromRecordEntity(record)
+	}
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := byID[id]; ok {
+			records = append(records, record)
+		}
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, records...)
+	}
+	return records, nil
+}
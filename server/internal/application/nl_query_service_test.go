@@ -0,0 +1,184 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+// stubNLProvider 返回固定的模型输出，并记录收到的请求
+type stubNLProvider struct {
+	output string
+	req    *aifield.GenerateRequest
+}
+
+func (p *stubNLProvider) Name() string { return "stub" }
+
+func (p *stubNLProvider) Generate(_ context.Context, req *aifield.GenerateRequest) (*aifield.GenerateResult, error) {
+	p.req = req
+	return &aifield.GenerateResult{Text: p.output, Model: "stub-chat"}, nil
+}
+
+type nlQueryFixture struct {
+	service  *NLQueryService
+	provider *stubNLProvider
+	fields   map[string]*fieldEntity.Field
+	executed *nlCompiledQuery
+}
+
+func newNLQueryFixture(t *testing.T) *nlQueryFixture {
+	t.Helper()
+	newField := func(name, fieldType string) *fieldEntity.Field {
+		fn, _ := fieldVO.NewFieldName(name)
+		ft, _ := fieldVO.NewFieldType(fieldType)
+		field, err := fieldEntity.NewField("tbl1", fn, ft, "usr1")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		return field
+	}
+	status := newField("状态", fieldVO.TypeSingleSelect)
+	options := fieldVO.NewFieldOptions()
+	options.Select = &fieldVO.SelectOptions{Choices: []fieldVO.SelectChoice{{ID: "c1", Name: "进行中"}, {ID: "c2", Name: "已完成"}}}
+	if err := status.UpdateOptions(options); err != nil {
+		t.Fatalf("设置选项失败: %v", err)
+	}
+	amount := newField("金额", fieldVO.TypeNumber)
+	owner := newField("负责人", fieldVO.TypeUser)
+	due := newField("截止日期", fieldVO.TypeDate)
+	secret := newField("密码", fieldVO.TypeText)
+	if err := secret.EnableEncryption(false, false); err != nil {
+		t.Fatalf("开启加密失败: %v", err)
+	}
+
+	tableName, _ := tableVO.NewTableName("任务")
+	table, err := tableEntity.NewTable("bse1", tableName, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	records := &stubSemanticRecordRepo{records: make(map[string]*recordEntity.Record)}
+	for _, id := range []string{"rec1", "rec2"} {
+		data, _ := recordVO.NewRecordData(map[string]interface{}{})
+		records.records[id] = recordEntity.ReconstructRecord(recordVO.NewRecordID(id), "tbl1", data, recordVO.InitialVersion(), "usr1", "usr1", time.Now(), time.Now(), nil)
+	}
+
+	fx := &nlQueryFixture{
+		provider: &stubNLProvider{},
+		fields:   map[string]*fieldEntity.Field{"status": status, "amount": amount, "owner": owner, "due": due, "secret": secret},
+	}
+	fx.service = NewNLQueryService(
+		&stubSemanticTableRepo{table: table},
+		&stubSemanticFieldRepo{fields: []*fieldEntity.Field{status, amount, owner, due, secret}},
+		records, nil, nil, nil,
+		map[string]aifield.Provider{"stub": fx.provider},
+		"stub", nil,
+		config.NLQueryConfig{MaxTokens: 800, MaxResults: 5},
+	)
+	fx.service.now = func() time.Time { return time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC) }
+	fx.service.runQuery = func(_ context.Context, _, _ string, query *nlCompiledQuery, limit int) ([]string, int64, error) {
+		fx.executed = query
+		if _, err := buildViewFilterCondition(context.Background(), query.spec.Filter, query.fields); err != nil {
+			return nil, 0, err
+		}
+		return []string{"rec2", "rec1"}[:min(limit, 2)], 7, nil
+	}
+	return fx
+}
+
+func (fx *nlQueryFixture) id(key string) string {
+	return fx.fields[key].ID().String()
+}
+
+func TestNLQueryCompilesSpec(t *testing.T) {
+	fx := newNLQueryFixture(t)
+	fx.provider.output = "```json\n{\"filter\":{\"operator\":\"and\",\"filters\":[" +
+		"{\"fieldId\":\"" + fx.id("owner") + "\",\"operator\":\"hasAnyOf\",\"value\":[\"@me\"]}," +
+		"{\"fieldId\":\"" + fx.id("amount") + "\",\"operator\":\"isGreater\",\"value\":1000}," +
+		"{\"fieldId\":\"" + fx.id("due") + "\",\"operator\":\"isWithin\",\"value\":[\"2026-10-12\",\"2026-10-18\"]}]}," +
+		"\"sort\":[{\"fieldId\":\"" + fx.id("amount") + "\",\"order\":\"desc\"}],\"explanation\":\"本周我负责的大额任务\"}\n```"
+
+	result, err := fx.service.query(context.Background(), "usr9", "tbl1", NLQueryRequest{
+		Question: "本周我负责的金额超过1000的任务", TimeZone: "Asia/Shanghai", Limit: 50,
+	})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+
+	req := fx.provider.req
+	if !req.JSON || req.MaxTokens != 800 || !strings.Contains(req.Prompt, "2026-10-15T17:00:00+08:00") {
+		t.Errorf("模型请求不正确: %+v", req)
+	}
+	if !strings.Contains(req.Prompt, "进行中") || strings.Contains(req.Prompt, fx.id("secret")) {
+		t.Errorf("提示词应包含选项且不包含加密字段: %s", req.Prompt)
+	}
+	if values := filterValues(result.Filter.Filters[0].Value); len(values) != 1 || values[0] != "usr9" {
+		t.Errorf("@me 应替换为当前用户: %v", result.Filter.Filters[0].Value)
+	}
+	if len(result.Sort) != 1 || result.Sort[0].Order != viewVO.SortOrderDesc {
+		t.Errorf("排序 = %+v", result.Sort)
+	}
+	if result.Total != 7 || len(result.Records) != 2 || result.Records[0].ID != "rec2" || result.Model != "stub-chat" {
+		t.Errorf("结果 = %+v", result)
+	}
+	if fx.executed == nil || fx.executed.fields[fx.id("secret")] != nil {
+		t.Error("加密字段不应参与查询")
+	}
+}
+
+func TestNLQueryRejectsInvalidSpec(t *testing.T) {
+	fx := newNLQueryFixture(t)
+	cases := map[string]string{
+		"加密字段":    `{"filter":{"filters":[{"fieldId":"` + fx.id("secret") + `","operator":"is","value":"x"}]}}`,
+		"未知字段":    `{"filter":{"filters":[{"fieldId":"fld_missing","operator":"is","value":"x"}]}}`,
+		"操作符不匹配":  `{"filter":{"filters":[{"fieldId":"` + fx.id("status") + `","operator":"isGreater","value":"进行中"}]}}`,
+		"数字值无效":   `{"filter":{"filters":[{"fieldId":"` + fx.id("amount") + `","operator":"isLess","value":"很多"}]}}`,
+		"日期范围不完整": `{"filter":{"filters":[{"fieldId":"` + fx.id("due") + `","operator":"isWithin","value":["2026-10-12"]}]}}`,
+		"未知排序字段":  `{"filter":null,"sort":[{"fieldId":"` + fx.id("secret") + `","order":"asc"}]}`,
+		"不是JSON":  `抱歉，我无法理解这个问题`,
+	}
+	for name, output := range cases {
+		fx.provider.output = output
+		fx.executed = nil
+		if _, err := fx.service.query(context.Background(), "usr1", "tbl1", NLQueryRequest{Question: "查询"}); err == nil {
+			t.Errorf("%s: 应返回错误", name)
+		}
+		if fx.executed != nil {
+			t.Errorf("%s: 校验失败时不应执行查询", name)
+		}
+	}
+
+	if _, err := fx.service.query(context.Background(), "usr1", "tbl1", NLQueryRequest{Question: "  "}); err == nil {
+		t.Error("空问题应返回错误")
+	}
+	if _, err := fx.service.query(context.Background(), "usr1", "tbl1", NLQueryRequest{Question: "查询", TimeZone: "Mars/Base"}); err == nil {
+		t.Error("无效时区应返回错误")
+	}
+}
+
+func TestNLQueryWithoutFilterCapsLimit(t *testing.T) {
+	fx := newNLQueryFixture(t)
+	fx.provider.output = `{"filter":null,"sort":[],"explanation":"无法用条件表达"}`
+	var gotLimit int
+	fx.service.runQuery = func(_ context.Context, _, _ string, query *nlCompiledQuery, limit int) ([]string, int64, error) {
+		gotLimit = limit
+		return nil, 0, nil
+	}
+	result, err := fx.service.query(context.Background(), "usr1", "tbl1", NLQueryRequest{Question: "随便看看", Limit: 100})
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if gotLimit != 5 || result.Filter != nil || result.Records == nil || len(result.Records) != 0 {
+		t.Errorf("limit = %d, 结果 = %+v", gotLimit, result)
+	}
+}
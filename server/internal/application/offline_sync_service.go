@@ -0,0 +1,493 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/application/permission"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/offlinesync"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var offlineSyncLog = logger.Named("offline_sync")
+
+// offlineRecordWriter 回放离线修改（由 RecordService 实现，照常校验、计算并写入变更流）
+type offlineRecordWriter interface {
+	CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error)
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+	DeleteRecordWithVersion(ctx context.Context, tableID, recordID string, expectedVersion *int) error
+}
+
+// OfflineSnapshotPage 表快照的一页
+type OfflineSnapshotPage struct {
+	TableID   string                `json:"tableId"`
+	Cursor    int64                 `json:"cursor"` // 读取本页前的变更流游标，客户端保存第一页的游标，快照完成后从该游标拉取增量变更
+	Fields    []ChangeFeedFieldDef  `json:"fields"`
+	Records   []*dto.RecordResponse `json:"records"`
+	NextAfter int64                 `json:"nextAfter"` // 下一页的 after 参数
+	HasMore   bool                  `json:"hasMore"`
+}
+
+// OfflinePushRequest 推送离线修改请求
+type OfflinePushRequest struct {
+	ClientID  string                 `json:"clientId" binding:"required"` // 客户端（设备安装）的唯一ID
+	Mutations []offlinesync.Mutation `json:"mutations" binding:"required"`
+}
+
+// OfflinePushResponse 推送结果，与请求中的修改一一对应
+type OfflinePushResponse struct {
+	Results []offlinesync.Result `json:"results"`
+}
+
+// OfflineSyncService 移动端离线同步服务 ✨
+// 同步协议：
+//  1. 拉取：客户端按表分页拉取快照（保存第一页返回的游标），之后从该游标经变更流（GET /bases/:baseId/changes）
+//     增量拉取，按记录版本去重；游标过期时重新拉取快照
+//  2. 推送：离线期间的修改按发生顺序排队，每条带客户端生成的修改ID；重连后按顺序推送，服务端逐条回放：
+//     - 同一客户端重复推送的修改直接返回首次处理的结果（Replayed），不会重复执行
+//     - 新建记录使用的本地ID在回放后映射为服务端记录ID，之后的修改可以继续引用本地ID
+//     - 更新按字段检测冲突（见 offlinesync.DetectConflicts），服务端只改了其他字段时自动合并；
+//     冲突的修改不写入，结果中返回双方的值，客户端合并后以新的修改ID重新推送
+//     - 权限、校验等错误的修改不写入也不登记，客户端可修正后重试
+type OfflineSyncService struct {
+	repo              offlinesync.Repository
+	tableRepo         tableRepo.TableRepository
+	recordRepo        recordRepo.RecordRepository
+	records           offlineRecordWriter
+	changeFeed        *ChangeFeedService
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	authorizer        *BatchAuthorizationService
+	cfg               config.OfflineSyncConfig
+	now               func() time.Time
+}
+
+// NewOfflineSyncService 创建离线同步服务
+func NewOfflineSyncService(
+	repo offlinesync.Repository,
+	tableRepo tableRepo.TableRepository,
+	recordRepo recordRepo.RecordRepository,
+	records offlineRecordWriter,
+	changeFeed *ChangeFeedService,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	cfg config.OfflineSyncConfig,
+) *OfflineSyncService {
+	if cfg.SnapshotPageSize <= 0 {
+		cfg.SnapshotPageSize = 500
+	}
+	if cfg.MaxMutations <= 0 {
+		cfg.MaxMutations = 500
+	}
+	return &OfflineSyncService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		recordRepo:        recordRepo,
+		records:           records,
+		changeFeed:        changeFeed,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// SetBatchAuthorizer 设置批量权限校验服务（一次推送内的修改复用表与角色的解析结果）
+func (s *OfflineSyncService) SetBatchAuthorizer(authorizer *BatchAuthorizationService) {
+	s.authorizer = authorizer
+}
+
+// offlineSnapshotRow 快照分页读取的记录ID与自增序号
+type offlineSnapshotRow struct {
+	ID         string `gorm:"column:__id"`
+	AutoNumber int64  `gorm:"column:__auto_number"`
+}
+
+// Snapshot 按自增序号分页读取表的记录快照（按请求用户脱敏）
+func (s *OfflineSyncService) Snapshot(ctx context.Context, userID, baseID, tableID string, after int64, limit int) (*OfflineSnapshotPage, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表记录的权限")
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil || table.BaseID() != baseID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	if limit <= 0 || limit > s.cfg.SnapshotPageSize {
+		limit = s.cfg.SnapshotPageSize
+	}
+
+	// 先取游标再读快照：读取期间的变更会在增量中再次出现，客户端按版本去重
+	cursor, err := s.changeFeed.Head(ctx, userID, baseID)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := s.changeFeed.tableSchema(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []offlineSnapshotRow
+	if err := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, tableID)).
+		Select("__id, __auto_number").
+		Where("__auto_number > ?", after).
+		Order("__auto_number ASC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	ids := make([]recordVO.RecordID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, recordVO.NewRecordID(row.ID))
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, ids)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	byID := make(map[string]*recordEntity.Record, len(entities))
+	for _, entity := range entities {
+		byID[entity.ID().String()] = entity
+	}
+
+	page := &OfflineSnapshotPage{
+		TableID:   tableID,
+		Cursor:    cursor,
+		Fields:    fields,
+		Records:   make([]*dto.RecordResponse, 0, len(rows)),
+		NextAfter: after,
+		HasMore:   len(rows) == limit,
+	}
+	for _, row := range rows {
+		if entity := byID[row.ID]; entity != nil {
+			page.Records = append(page.Records, dto.FromRecordEntity(entity))
+		}
+		page.NextAfter = row.AutoNumber
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, page.Records...)
+	}
+	return page, nil
+}
+
+// Push 按顺序回放客户端的离线修改
+func (s *OfflineSyncService) Push(ctx context.Context, userID, baseID string, req OfflinePushRequest) (*OfflinePushResponse, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	if utf8.RuneCountInString(req.ClientID) > offlinesync.MaxIDLength {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("clientId 不能超过 %d 个字符", offlinesync.MaxIDLength))
+	}
+	if len(req.Mutations) > s.cfg.MaxMutations {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多推送 %d 条修改，请分批推送", s.cfg.MaxMutations))
+	}
+
+	mutationIDs := make([]string, 0, len(req.Mutations))
+	recordIDs := make([]string, 0, len(req.Mutations))
+	for _, m := range req.Mutations {
+		mutationIDs = append(mutationIDs, m.ID)
+		if m.RecordID != "" {
+			recordIDs = append(recordIDs, m.RecordID)
+		}
+	}
+	entries, err := s.repo.FindEntries(ctx, userID, req.ClientID, mutationIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询离线修改登记失败")
+	}
+	// 本地ID → 服务端记录ID（包括此前推送中新建的记录）；unsynced 为本次新建未成功的本地ID
+	resolved, err := s.repo.ResolveRecordIDs(ctx, userID, req.ClientID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "解析本地记录ID失败")
+	}
+	unsynced := map[string]bool{}
+	tables := map[string]bool{}
+	ctx = s.authorizeMutations(ctx, userID, req.Mutations)
+
+	resp := &OfflinePushResponse{Results: make([]offlinesync.Result, 0, len(req.Mutations))}
+	for i := range req.Mutations {
+		m := &req.Mutations[i]
+		if entry := entries[m.ID]; entry != nil {
+			result := entry.Result
+			result.Replayed = true
+			if m.Type == offlinesync.MutationCreate && result.Status == offlinesync.StatusApplied && m.RecordID != "" {
+				resolved[m.RecordID] = result.RecordID
+			}
+			resp.Results = append(resp.Results, result)
+			continue
+		}
+
+		result := s.apply(ctx, userID, baseID, m, tables, resolved, unsynced)
+		if m.Type == offlinesync.MutationCreate && m.RecordID != "" {
+			if result.Status == offlinesync.StatusApplied {
+				resolved[m.RecordID] = result.RecordID
+			} else {
+				unsynced[m.RecordID] = true
+			}
+		}
+		if result.Status != offlinesync.StatusFailed {
+			s.saveEntry(ctx, userID, req.ClientID, m, result)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// authorizeMutations 一次校验本次推送涉及的全部（动作, 表），返回附加了校验批次的上下文，逐条回放时的权限检查复用批次中已解析的表与角色
+func (s *OfflineSyncService) authorizeMutations(ctx context.Context, userID string, mutations []offlinesync.Mutation) context.Context {
+	if s.authorizer == nil {
+		return ctx
+	}
+	checks := make([]AuthorizationCheck, 0, len(mutations))
+	for i := range mutations {
+		action := permission.ActionRecordUpdate
+		switch mutations[i].Type {
+		case offlinesync.MutationCreate:
+			action = permission.ActionRecordCreate
+		case offlinesync.MutationDelete:
+			action = permission.ActionRecordDelete
+		}
+		checks = append(checks, AuthorizationCheck{UserID: userID, Action: action, Resource: AuthorizationResourceTable, ResourceID: mutations[i].TableID})
+	}
+	batch := s.authorizer.NewBatch()
+	batch.Authorize(ctx, checks)
+	return WithAuthorizationBatch(ctx, batch)
+}
+
+// apply 校验并回放一条修改
+func (s *OfflineSyncService) apply(ctx context.Context, userID, baseID string, m *offlinesync.Mutation, tables map[string]bool, resolved map[string]string, unsynced map[string]bool) offlinesync.Result {
+	if err := m.Validate(); err != nil {
+		return offlineFailure(m, pkgerrors.ErrValidationFailed.WithDetails(err.Error()))
+	}
+	if err := s.checkTable(ctx, baseID, m.TableID, tables); err != nil {
+		return offlineFailure(m, err)
+	}
+	if m.Type == offlinesync.MutationCreate {
+		return s.applyCreate(ctx, userID, m)
+	}
+
+	if unsynced[m.RecordID] {
+		return offlineFailure(m, pkgerrors.ErrValidationFailed.WithDetails("引用的本地记录未能新建"))
+	}
+	recordID := m.RecordID
+	if id, ok := resolved[recordID]; ok {
+		recordID = id
+	}
+	if m.Type == offlinesync.MutationDelete {
+		return s.applyDelete(ctx, userID, m, recordID)
+	}
+	return s.applyUpdate(ctx, userID, m, recordID)
+}
+
+func (s *OfflineSyncService) applyCreate(ctx context.Context, userID string, m *offlinesync.Mutation) offlinesync.Result {
+	if !s.permissionService.CanCreateRecordsInTable(ctx, userID, m.TableID) {
+		return offlineFailure(m, pkgerrors.ErrForbidden.WithDetails("没有权限在该表格中创建记录"))
+	}
+	fields := m.Fields
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	record, err := s.records.CreateRecord(ctx, dto.CreateRecordRequest{TableID: m.TableID, Data: fields}, userID)
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	return offlinesync.Result{MutationID: m.ID, Status: offlinesync.StatusApplied, RecordID: record.ID, Version: int64(record.Version)}
+}
+
+// applyUpdate 按字段检测冲突后以当前版本写入（检测与写入之间被修改时同样返回冲突）
+func (s *OfflineSyncService) applyUpdate(ctx context.Context, userID string, m *offlinesync.Mutation, recordID string) offlinesync.Result {
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, m.TableID) {
+		return offlineFailure(m, pkgerrors.ErrForbidden.WithDetails("没有权限更新该表格的记录"))
+	}
+	current, err := s.current(ctx, m.TableID, recordID)
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	if current == nil {
+		return s.conflict(ctx, m, recordID, offlinesync.ConflictDeleted, nil, nil)
+	}
+	if m.Strategy == offlinesync.StrategyMerge {
+		if conflicts := offlinesync.DetectConflicts(m, current.Data().ToMap(), current.Version().Value()); len(conflicts) > 0 {
+			return s.conflict(ctx, m, recordID, offlinesync.ConflictModified, current, conflicts)
+		}
+	}
+
+	expected := int(current.Version().Value())
+	record, err := s.records.UpdateRecord(ctx, m.TableID, recordID, dto.UpdateRecordRequest{Data: m.Fields, Version: &expected}, userID)
+	if pkgerrors.Is(err, pkgerrors.ErrVersionConflict) {
+		return s.raced(ctx, m, recordID)
+	}
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	return offlinesync.Result{MutationID: m.ID, Status: offlinesync.StatusApplied, RecordID: recordID, Version: int64(record.Version)}
+}
+
+// applyDelete 删除记录；记录已不存在视为已删除，BaseVersion 与当前版本不一致时（merge 策略）为冲突
+func (s *OfflineSyncService) applyDelete(ctx context.Context, userID string, m *offlinesync.Mutation, recordID string) offlinesync.Result {
+	if !s.permissionService.CanDeleteRecordsInTable(ctx, userID, m.TableID) {
+		return offlineFailure(m, pkgerrors.ErrForbidden.WithDetails("没有权限删除该表格的记录"))
+	}
+	current, err := s.current(ctx, m.TableID, recordID)
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	if current == nil {
+		return offlinesync.Result{MutationID: m.ID, Status: offlinesync.StatusApplied, RecordID: recordID}
+	}
+	version := current.Version().Value()
+	if m.Strategy == offlinesync.StrategyMerge && m.BaseVersion != nil && *m.BaseVersion != version {
+		return s.conflict(ctx, m, recordID, offlinesync.ConflictModified, current, nil)
+	}
+
+	expected := int(version)
+	err = s.records.DeleteRecordWithVersion(ctx, m.TableID, recordID, &expected)
+	if pkgerrors.Is(err, pkgerrors.ErrVersionConflict) {
+		return s.raced(ctx, m, recordID)
+	}
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	return offlinesync.Result{MutationID: m.ID, Status: offlinesync.StatusApplied, RecordID: recordID}
+}
+
+// raced 检测后写入前记录被修改：按最新状态返回冲突
+func (s *OfflineSyncService) raced(ctx context.Context, m *offlinesync.Mutation, recordID string) offlinesync.Result {
+	current, err := s.current(ctx, m.TableID, recordID)
+	if err != nil {
+		return offlineFailure(m, err)
+	}
+	if current == nil {
+		return s.conflict(ctx, m, recordID, offlinesync.ConflictDeleted, nil, nil)
+	}
+	var conflicts []offlinesync.FieldConflict
+	if m.Type == offlinesync.MutationUpdate {
+		conflicts = offlinesync.DetectConflicts(m, current.Data().ToMap(), current.Version().Value())
+	}
+	return s.conflict(ctx, m, recordID, offlinesync.ConflictModified, current, conflicts)
+}
+
+// conflict 生成冲突结果，服务端的值按请求用户脱敏
+func (s *OfflineSyncService) conflict(ctx context.Context, m *offlinesync.Mutation, recordID, reason string, current *recordEntity.Record, fields []offlinesync.FieldConflict) offlinesync.Result {
+	conflict := &offlinesync.Conflict{Reason: reason, Fields: fields}
+	if current != nil {
+		record := dto.FromRecordEntity(current)
+		if s.privacyService != nil {
+			s.privacyService.MaskRecords(ctx, m.TableID, record)
+		}
+		conflict.ServerVersion = current.Version().Value()
+		conflict.ServerFields = record.Data
+		for i := range conflict.Fields {
+			conflict.Fields[i].Server = record.Data[conflict.Fields[i].FieldID]
+		}
+	}
+	return offlinesync.Result{MutationID: m.ID, Status: offlinesync.StatusConflict, RecordID: recordID, Conflict: conflict}
+}
+
+// current 读取记录的当前值（未脱敏，仅用于冲突检测），不存在时返回 nil
+func (s *OfflineSyncService) current(ctx context.Context, tableID, recordID string) (*recordEntity.Record, error) {
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, []recordVO.RecordID{recordVO.NewRecordID(recordID)})
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找记录失败")
+	}
+	if len(entities) == 0 {
+		return nil, nil
+	}
+	return entities[0], nil
+}
+
+// checkTable 修改的表须属于推送的 Base
+func (s *OfflineSyncService) checkTable(ctx context.Context, baseID, tableID string, checked map[string]bool) error {
+	if ok, seen := checked[tableID]; seen {
+		if !ok {
+			return pkgerrors.ErrNotFound.WithDetails("表格不存在")
+		}
+		return nil
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找表格失败")
+	}
+	checked[tableID] = table != nil && table.BaseID() == baseID
+	if !checked[tableID] {
+		return pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	return nil
+}
+
+func (s *OfflineSyncService) saveEntry(ctx context.Context, userID, clientID string, m *offlinesync.Mutation, result offlinesync.Result) {
+	entry := &offlinesync.Entry{
+		ClientID:   clientID,
+		MutationID: m.ID,
+		UserID:     userID,
+		TableID:    m.TableID,
+		Result:     result,
+		CreatedAt:  s.now(),
+	}
+	if m.Type == offlinesync.MutationCreate {
+		entry.ClientRecordID = m.RecordID
+	}
+	if err := s.repo.SaveEntry(ctx, entry); err != nil {
+		offlineSyncLog.Warn(ctx, "登记离线修改失败", logger.String("mutation_id", m.ID), logger.ErrorField(err))
+	}
+}
+
+// Start 启动过期登记清理
+func (s *OfflineSyncService) Start(ctx context.Context) {
+	if s.cfg.Retention <= 0 || s.cfg.CleanupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			deleted, err := s.repo.PurgeBefore(ctx, s.now().Add(-s.cfg.Retention))
+			if err != nil {
+				offlineSyncLog.Warn(ctx, "清理过期离线修改登记失败", logger.ErrorField(err))
+				continue
+			}
+			if deleted > 0 {
+				offlineSyncLog.Debug(ctx, "已清理过期离线修改登记", logger.Int64("count", deleted))
+			}
+		}
+	}()
+}
+
+// offlineFailure 未写入的修改结果（错误码与可展示的消息）
+func offlineFailure(m *offlinesync.Mutation, err error) offlinesync.Result {
+	appErr := pkgerrors.From(err)
+	message := appErr.Message
+	if details, ok := appErr.Details.(string); ok && details != "" {
+		message = details
+	}
+	return offlinesync.Result{
+		MutationID: m.ID,
+		Status:     offlinesync.StatusFailed,
+		RecordID:   m.RecordID,
+		ErrorCode:  appErr.Code,
+		Error:      message,
+	}
+}
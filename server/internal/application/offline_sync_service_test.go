@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/offlinesync"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+func TestOfflineFailure(t *testing.T) {
+	m := &offlinesync.Mutation{ID: "m1", RecordID: "rec1"}
+	result := offlineFailure(m, pkgerrors.ErrForbidden.WithDetails("没有权限"))
+	if result.Status != offlinesync.StatusFailed || result.ErrorCode != pkgerrors.ErrForbidden.Code || result.Error != "没有权限" {
+		t.Errorf("失败结果错误: %+v", result)
+	}
+	if result.MutationID != "m1" || result.RecordID != "rec1" {
+		t.Errorf("失败结果应保留修改ID与记录ID: %+v", result)
+	}
+}
+
+func TestOfflineApplyRejectsBeforeWriting(t *testing.T) {
+	s := &OfflineSyncService{}
+	ctx := context.Background()
+	tables := map[string]bool{"tbl1": true, "tbl2": false}
+
+	// 格式错误
+	result := s.apply(ctx, "usr1", "bse1", &offlinesync.Mutation{ID: "m1", Type: "upsert", TableID: "tbl1"}, tables, nil, nil)
+	if result.Status != offlinesync.StatusFailed || result.ErrorCode != pkgerrors.ErrValidationFailed.Code {
+		t.Errorf("不支持的类型应失败: %+v", result)
+	}
+
+	// 不属于该 Base 的表
+	result = s.apply(ctx, "usr1", "bse1", &offlinesync.Mutation{ID: "m2", Type: offlinesync.MutationDelete, TableID: "tbl2", RecordID: "rec1"}, tables, nil, nil)
+	if result.Status != offlinesync.StatusFailed || result.ErrorCode != pkgerrors.ErrNotFound.Code {
+		t.Errorf("其他 Base 的表应失败: %+v", result)
+	}
+
+	// 引用新建失败的本地记录
+	update := &offlinesync.Mutation{ID: "m3", Type: offlinesync.MutationUpdate, TableID: "tbl1", RecordID: "local1", Fields: map[string]interface{}{"fld1": "x"}}
+	result = s.apply(ctx, "usr1", "bse1", update, tables, map[string]string{}, map[string]bool{"local1": true})
+	if result.Status != offlinesync.StatusFailed {
+		t.Errorf("依赖的新建记录未写入时应失败: %+v", result)
+	}
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var onlineMigrationLog = logger.Named("online_migration")
+
+// onlineMigrator 在线迁移执行器（由 database.OnlineMigrator 实现）
+type onlineMigrator interface {
+	Run(ctx context.Context, contract bool) (*database.OnlineMigrationResult, error)
+	Status(ctx context.Context) (*database.OnlineMigrationStatus, error)
+}
+
+// OnlineMigrationService 元数据表在线迁移服务 ✨
+// 滚动部署时新版本实例启动后在后台执行扩展迁移（多个实例同时启动时只有一个执行），
+// 旧版本实例在此期间照常服务；收缩迁移只能通过 migrate online --contract 执行。
+// 管理员通过状态接口确认迁移进度，以及是否可以下线旧版本、执行收缩迁移
+type OnlineMigrationService struct {
+	migrator   onlineMigrator
+	autoExpand bool
+}
+
+// NewOnlineMigrationService 创建在线迁移服务
+func NewOnlineMigrationService(migrator onlineMigrator, autoExpand bool) *OnlineMigrationService {
+	return &OnlineMigrationService{migrator: migrator, autoExpand: autoExpand}
+}
+
+// Status 在线迁移状态
+func (s *OnlineMigrationService) Status(ctx context.Context) (*database.OnlineMigrationStatus, error) {
+	status, err := s.migrator.Status(ctx)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取迁移状态失败")
+	}
+	return status, nil
+}
+
+// Start 在后台执行扩展迁移（未开启 auto_expand 时不执行）
+func (s *OnlineMigrationService) Start(ctx context.Context) {
+	if !s.autoExpand {
+		return
+	}
+	go func() {
+		result, err := s.migrator.Run(ctx, false)
+		switch {
+		case err != nil:
+			onlineMigrationLog.Error(ctx, "在线迁移执行失败", logger.ErrorField(err))
+		case result.Skipped:
+			onlineMigrationLog.Info(ctx, "其他实例正在执行在线迁移，已跳过")
+		case result.Error != "":
+			onlineMigrationLog.Error(ctx, "在线迁移失败",
+				logger.Int("applied", len(result.Applied)),
+				logger.String("error", result.Error))
+		case len(result.Applied) > 0:
+			onlineMigrationLog.Info(ctx, "在线扩展迁移完成", logger.Int("applied", len(result.Applied)))
+		}
+	}()
+}
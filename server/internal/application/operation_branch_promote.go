@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// BranchPromoteParams 沙盒分支合并操作参数
+type BranchPromoteParams struct {
+	BranchID string `json:"branch_id"`
+}
+
+// BranchPromoteResult 沙盒分支合并操作结果
+type BranchPromoteResult struct {
+	BranchID string              `json:"branch_id"`
+	Changes  []schemaspec.Change `json:"changes"`
+}
+
+// BranchPromoteExecutor 沙盒分支合并执行器
+// 发起时检测冲突，执行时按最新的生产结构重新合并（期间生产又有改动导致冲突时失败，不做任何变更），
+// 再以 prune 方式把合并结果应用到生产：沙盒删除的对象在生产中删除，生产期间新增的对象保留
+type BranchPromoteExecutor struct {
+	branchService *BaseBranchService
+}
+
+// NewBranchPromoteExecutor 创建沙盒分支合并执行器
+func NewBranchPromoteExecutor(branchService *BaseBranchService) *BranchPromoteExecutor {
+	return &BranchPromoteExecutor{branchService: branchService}
+}
+
+// Prepare 校验分支、权限，并在发起前检测冲突
+func (e *BranchPromoteExecutor) Prepare(ctx context.Context, userID, baseID string, params json.RawMessage) error {
+	p, err := decodeBranchPromoteParams(params)
+	if err != nil {
+		return err
+	}
+	branch, err := e.branchService.activeBranch(ctx, userID, p.BranchID)
+	if err != nil {
+		return err
+	}
+	if branch.SourceBaseID != baseID {
+		return pkgerrors.ErrNotFound.WithDetails("沙盒分支不属于该Base")
+	}
+	if !e.branchService.permissionService.CanCreateTablesInBase(ctx, userID, baseID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限修改Base结构")
+	}
+	_, conflicts, err := e.branchService.merge(ctx, userID, branch)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return pkgerrors.ErrConflict.WithDetails(conflicts)
+	}
+	return nil
+}
+
+// Execute 重新合并并应用到生产，成功后以沙盒结构作为新的合并基准
+func (e *BranchPromoteExecutor) Execute(ctx context.Context, op *operation.Operation, progress *OperationProgress) (interface{}, error) {
+	p, err := decodeBranchPromoteParams(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	s := e.branchService
+	branch, err := s.activeBranch(ctx, op.CreatedBy, p.BranchID)
+	if err != nil {
+		return nil, err
+	}
+	sandboxSpec, err := s.exportYAML(ctx, op.CreatedBy, branch.SandboxBaseID)
+	if err != nil {
+		return nil, err
+	}
+	merged, conflicts, err := s.merge(ctx, op.CreatedBy, branch)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("生产结构在发起合并后发生了冲突改动（%d 处），请重新预览", len(conflicts))
+	}
+
+	plan, err := s.schemaSpec.Diff(ctx, op.CreatedBy, branch.SourceBaseID, merged, true)
+	if err != nil {
+		return nil, err
+	}
+	progress.SetTotal(int64(len(plan.Changes)))
+	applied, err := s.schemaSpec.Apply(ctx, op.CreatedBy, branch.SourceBaseID, merged, true)
+	if err != nil {
+		return nil, err
+	}
+	progress.Advance(int64(len(plan.Changes)))
+
+	branch.MarkPromoted(sandboxSpec, time.Now())
+	if err := s.repo.Update(ctx, branch); err != nil {
+		return nil, pkgerrors.Database(err, "更新沙盒分支失败")
+	}
+	baseBranchLog.Info(ctx, "沙盒分支已合并到生产",
+		logger.String("branch_id", branch.ID),
+		logger.String("source_base_id", branch.SourceBaseID),
+		logger.Int("changes", len(applied.Changes)))
+	return &BranchPromoteResult{BranchID: branch.ID, Changes: applied.Changes}, nil
+}
+
+func decodeBranchPromoteParams(raw json.RawMessage) (*BranchPromoteParams, error) {
+	var p BranchPromoteParams
+	if len(raw) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少合并参数")
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("合并参数无效: %v", err))
+	}
+	if p.BranchID == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少 branch_id")
+	}
+	return &p, nil
+}
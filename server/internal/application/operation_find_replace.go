@@ -0,0 +1,299 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/findreplace"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// FindReplaceResult 查找替换操作结果
+type FindReplaceResult struct {
+	TableID     string `json:"table_id"`
+	ViewID      string `json:"view_id,omitempty"`
+	Scanned     int    `json:"scanned"`
+	Matched     int    `json:"matched"`     // 包含匹配的记录数
+	Replaced    int    `json:"replaced"`    // 成功写入的记录数
+	Occurrences int    `json:"occurrences"` // 成功写入的记录中的替换次数
+	Conflicts   int    `json:"conflicts"`   // 读取后被他人修改而跳过的记录数
+	Failed      int    `json:"failed"`
+	Truncated   bool   `json:"truncated"`
+}
+
+// FindReplaceUndoParams 撤销查找替换操作参数
+type FindReplaceUndoParams struct {
+	OperationID string `json:"operation_id"`
+}
+
+// FindReplaceUndoResult 撤销查找替换操作结果
+type FindReplaceUndoResult struct {
+	OperationID string `json:"operation_id"`
+	Restored    int    `json:"restored"`  // 已还原的记录数
+	Skipped     int    `json:"skipped"`   // 替换后已被修改或删除、无需还原的记录数
+	Conflicts   int    `json:"conflicts"` // 还原时被并发修改的记录数
+	Failed      int    `json:"failed"`
+}
+
+// FindReplaceExecutor 批量查找替换执行器
+// 按记录ID分页扫描范围内的记录，每页以读取时的版本批量写入，成功写入的记录保存替换前后的值用于撤销
+type FindReplaceExecutor struct {
+	service *FindReplaceService
+}
+
+// NewFindReplaceExecutor 创建批量查找替换执行器
+func NewFindReplaceExecutor(service *FindReplaceService) *FindReplaceExecutor {
+	return &FindReplaceExecutor{service: service}
+}
+
+// Prepare 校验查找条件、字段、视图与编辑权限
+func (e *FindReplaceExecutor) Prepare(ctx context.Context, userID, baseID string, params json.RawMessage) error {
+	spec, err := decodeFindReplaceSpec(params)
+	if err != nil {
+		return err
+	}
+	target, err := e.service.resolve(ctx, userID, baseID, spec)
+	if err != nil {
+		return err
+	}
+	return e.service.checkProtected(ctx, target)
+}
+
+// Execute 分页替换并写入，取消时返回已完成部分的结果
+func (e *FindReplaceExecutor) Execute(ctx context.Context, op *operation.Operation, progress *OperationProgress) (interface{}, error) {
+	s := e.service
+	spec, err := decodeFindReplaceSpec(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.resolve(ctx, op.CreatedBy, op.BaseID, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FindReplaceResult{TableID: spec.TableID, ViewID: spec.ViewID}
+	scanned, truncated, err := s.scan(ctx, target, progress, func(offset int, records []*recordEntity.Record) error {
+		var changes []*findreplace.Change
+		var counts, positions []int
+		var mutations []dto.RecordMutation
+		for i, record := range records {
+			change, occurrences := s.replace(target, record)
+			if change == nil {
+				continue
+			}
+			change.OperationID = op.ID
+			version := int(record.Version().Value())
+			n := 0
+			for _, count := range occurrences {
+				n += count
+			}
+			changes = append(changes, change)
+			counts = append(counts, n)
+			positions = append(positions, offset+i)
+			mutations = append(mutations, dto.RecordMutation{
+				Op:      dto.RecordMutationUpdate,
+				ID:      change.RecordID,
+				Fields:  change.After,
+				Version: &version,
+			})
+		}
+		result.Matched += len(changes)
+
+		items, err := s.write(ctx, spec.TableID, mutations, op.CreatedBy)
+		if err != nil {
+			return err
+		}
+		applied := make([]*findreplace.Change, 0, len(changes))
+		for i, item := range items {
+			if item != nil && item.Status == "success" {
+				applied = append(applied, changes[i])
+				result.Replaced++
+				result.Occurrences += counts[i]
+				continue
+			}
+			message, conflict := mutationFailure(item)
+			if conflict {
+				result.Conflicts++
+			} else {
+				result.Failed++
+			}
+			progress.AddError(positions[i], fmt.Sprintf("记录 %s: %s", changes[i].RecordID, message))
+		}
+		if err := s.repo.SaveChanges(ctx, applied); err != nil {
+			return pkgerrors.Database(err, "保存查找替换记录失败")
+		}
+		progress.Advance(int64(len(records)))
+		return nil
+	})
+	result.Scanned, result.Truncated = scanned, truncated
+	return result, err
+}
+
+// FindReplaceUndoExecutor 撤销查找替换执行器
+// 逐条读取当前值，只还原仍等于替换结果的字段，并以读取时的版本写入；每个查找替换操作只能撤销一次
+type FindReplaceUndoExecutor struct {
+	service *FindReplaceService
+}
+
+// NewFindReplaceUndoExecutor 创建撤销查找替换执行器
+func NewFindReplaceUndoExecutor(service *FindReplaceService) *FindReplaceUndoExecutor {
+	return &FindReplaceUndoExecutor{service: service}
+}
+
+// Prepare 校验被撤销的操作与编辑权限
+func (e *FindReplaceUndoExecutor) Prepare(ctx context.Context, userID, baseID string, params json.RawMessage) error {
+	p, err := decodeFindReplaceUndoParams(params)
+	if err != nil {
+		return err
+	}
+	_, err = e.source(ctx, userID, baseID, p.OperationID)
+	if err != nil {
+		return err
+	}
+	undoID, err := e.service.repo.FindUndo(ctx, p.OperationID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询撤销记录失败")
+	}
+	if undoID != "" {
+		return pkgerrors.ErrConflict.WithDetails("该查找替换已撤销")
+	}
+	return nil
+}
+
+// Execute 分页还原变更记录
+func (e *FindReplaceUndoExecutor) Execute(ctx context.Context, op *operation.Operation, progress *OperationProgress) (interface{}, error) {
+	s := e.service
+	p, err := decodeFindReplaceUndoParams(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := e.source(ctx, op.CreatedBy, op.BaseID, p.OperationID)
+	if err != nil {
+		return nil, err
+	}
+	claimed, err := s.repo.ClaimUndo(ctx, p.OperationID, op.ID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "登记撤销失败")
+	}
+	if !claimed {
+		return nil, pkgerrors.ErrConflict.WithDetails("该查找替换已撤销")
+	}
+	total, err := s.repo.CountChanges(ctx, p.OperationID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "统计查找替换记录失败")
+	}
+	progress.SetTotal(total)
+
+	result := &FindReplaceUndoResult{OperationID: p.OperationID}
+	offset := 0
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		changes, err := s.repo.ListChanges(ctx, p.OperationID, afterID, s.cfg.BatchSize)
+		if err != nil {
+			return result, pkgerrors.Database(err, "查询查找替换记录失败")
+		}
+		if len(changes) == 0 {
+			return result, nil
+		}
+		ids := make([]string, 0, len(changes))
+		for _, change := range changes {
+			ids = append(ids, change.RecordID)
+		}
+		records, err := s.loadRecords(ctx, spec.TableID, ids)
+		if err != nil {
+			return result, err
+		}
+		current := make(map[string]*recordEntity.Record, len(records))
+		for _, record := range records {
+			current[record.ID().String()] = record
+		}
+
+		var mutations []dto.RecordMutation
+		var positions []int
+		for i, change := range changes {
+			record := current[change.RecordID]
+			if record == nil {
+				result.Skipped++
+				continue
+			}
+			restore := change.Reverted(record.Data().ToMap())
+			if restore == nil {
+				result.Skipped++
+				continue
+			}
+			version := int(record.Version().Value())
+			mutations = append(mutations, dto.RecordMutation{
+				Op:      dto.RecordMutationUpdate,
+				ID:      change.RecordID,
+				Fields:  restore,
+				Version: &version,
+			})
+			positions = append(positions, offset+i)
+		}
+		items, err := s.write(ctx, spec.TableID, mutations, op.CreatedBy)
+		if err != nil {
+			return result, err
+		}
+		for i, item := range items {
+			if item != nil && item.Status == "success" {
+				result.Restored++
+				continue
+			}
+			message, conflict := mutationFailure(item)
+			if conflict {
+				result.Conflicts++
+			} else {
+				result.Failed++
+			}
+			progress.AddError(positions[i], fmt.Sprintf("记录 %s: %s", mutations[i].ID, message))
+		}
+
+		offset += len(changes)
+		afterID = changes[len(changes)-1].RecordID
+		progress.Advance(int64(len(changes)))
+	}
+}
+
+// source 获取被撤销的查找替换操作并校验：属于该Base、已结束、用户可编辑目标表的记录
+func (e *FindReplaceUndoExecutor) source(ctx context.Context, userID, baseID, operationID string) (*findreplace.Spec, error) {
+	s := e.service
+	op, err := s.operationRepo.FindByID(ctx, operationID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取操作失败")
+	}
+	if op == nil || op.BaseID != baseID || op.Type != operation.TypeFindReplace {
+		return nil, pkgerrors.ErrNotFound.WithDetails("查找替换操作不存在")
+	}
+	if !op.Finished() {
+		return nil, pkgerrors.ErrConflict.WithDetails("查找替换尚未结束，不能撤销")
+	}
+	spec, err := decodeFindReplaceSpec(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanUpdateRecordsInTable(ctx, userID, spec.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限编辑该表格的记录")
+	}
+	return spec, nil
+}
+
+func decodeFindReplaceUndoParams(raw json.RawMessage) (*FindReplaceUndoParams, error) {
+	var p FindReplaceUndoParams
+	if len(raw) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少撤销参数")
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("撤销参数无效: %v", err))
+	}
+	if p.OperationID == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少 operation_id")
+	}
+	return &p, nil
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// defaultMaxImportRecords 单次导入的默认最大记录数
+const defaultMaxImportRecords = 100000
+
+// RecordImportParams 导入记录操作参数
+type RecordImportParams struct {
+	TableID  string                   `json:"table_id"`
+	Records  []map[string]interface{} `json:"records"`
+	Typecast bool                     `json:"typecast"`
+}
+
+// RecordImportResult 导入记录操作结果
+type RecordImportResult struct {
+	TableID string `json:"table_id"`
+	Created int    `json:"created"`
+	Failed  int    `json:"failed"`
+}
+
+// RecordImportExecutor 导入记录执行器
+// 按批调用混合批量变更（每批一个事务，逐条保存点），单条失败记为条目级错误，不中断导入
+type RecordImportExecutor struct {
+	recordService     *RecordService
+	tableRepo         tableRepo.TableRepository
+	permissionService *PermissionServiceV2
+	maxRecords        int
+}
+
+// NewRecordImportExecutor 创建导入记录执行器
+func NewRecordImportExecutor(
+	recordService *RecordService,
+	tableRepo tableRepo.TableRepository,
+	permissionService *PermissionServiceV2,
+	maxRecords int,
+) *RecordImportExecutor {
+	if maxRecords <= 0 {
+		maxRecords = defaultMaxImportRecords
+	}
+	return &RecordImportExecutor{
+		recordService:     recordService,
+		tableRepo:         tableRepo,
+		permissionService: permissionService,
+		maxRecords:        maxRecords,
+	}
+}
+
+// Prepare 校验目标表格与导入数据
+func (e *RecordImportExecutor) Prepare(ctx context.Context, userID, baseID string, params json.RawMessage) error {
+	p, err := decodeRecordImportParams(params)
+	if err != nil {
+		return err
+	}
+	if len(p.Records) > e.maxRecords {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("单次最多导入 %d 条记录", e.maxRecords))
+	}
+
+	table, err := e.tableRepo.GetByID(ctx, p.TableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil || table.BaseID() != baseID {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在或不属于该Base")
+	}
+	if !e.permissionService.CanCreateRecordsInTable(ctx, userID, p.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限在该表格中创建记录")
+	}
+	return nil
+}
+
+// Execute 分批导入记录，取消时返回已导入部分的结果
+func (e *RecordImportExecutor) Execute(ctx context.Context, op *operation.Operation, progress *OperationProgress) (interface{}, error) {
+	p, err := decodeRecordImportParams(op.Params)
+	if err != nil {
+		return nil, err
+	}
+	progress.SetTotal(int64(len(p.Records)))
+
+	result := &RecordImportResult{TableID: p.TableID}
+	for start := 0; start < len(p.Records); start += recordMutationChunkSize {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		end := start + recordMutationChunkSize
+		if end > len(p.Records) {
+			end = len(p.Records)
+		}
+
+		ops := make([]dto.RecordMutation, 0, end-start)
+		for _, fields := range p.Records[start:end] {
+			ops = append(ops, dto.RecordMutation{Op: dto.RecordMutationCreate, Fields: fields})
+		}
+		resp, err := e.recordService.MutateRecords(ctx, p.TableID, dto.RecordMutationBatchRequest{
+			Operations: ops,
+			Typecast:   p.Typecast,
+		}, op.CreatedBy)
+		if err != nil {
+			return result, err
+		}
+
+		for _, item := range resp.Results {
+			if item.Status == "success" {
+				result.Created++
+				continue
+			}
+			result.Failed++
+			message := "导入失败"
+			if item.Error != nil {
+				message = item.Error.Message
+				if details, ok := item.Error.Details.(string); ok && details != "" {
+					message = details
+				}
+			}
+			progress.AddError(start+item.Index, message)
+		}
+		progress.Advance(int64(end - start))
+	}
+	return result, nil
+}
+
+func decodeRecordImportParams(raw json.RawMessage) (*RecordImportParams, error) {
+	var p RecordImportParams
+	if len(raw) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少导入参数")
+	}
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("导入参数无效: %v", err))
+	}
+	if p.TableID == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("缺少 table_id")
+	}
+	if len(p.Records) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("没有要导入的记录")
+	}
+	return &p, nil
+}
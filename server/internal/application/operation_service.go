@@ -0,0 +1,418 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var operationLog = logger.Named("operation")
+
+// operationListMaxLimit 操作列表单次返回的最大条数
+const operationListMaxLimit = 200
+
+// OperationExecutor 长时操作执行器
+// 每种操作类型（导入、转换、恢复等）注册一个执行器
+type OperationExecutor interface {
+	// Prepare 发起操作时在请求内校验参数与权限，校验失败时不创建操作
+	Prepare(ctx context.Context, userID, baseID string, params json.RawMessage) error
+	// Execute 后台执行操作，通过 progress 上报进度和条目级错误，返回值序列化为操作结果
+	// 操作被取消或服务停止时 ctx 会被取消，执行器应尽快返回已完成部分的结果
+	Execute(ctx context.Context, op *operation.Operation, progress *OperationProgress) (interface{}, error)
+}
+
+// StartOperationRequest 发起长时操作请求
+type StartOperationRequest struct {
+	Type   operation.Type  `json:"type" binding:"required"`
+	Params json.RawMessage `json:"params"`
+}
+
+// OperationSnapshot 操作状态（附带进度，用于响应与实时推送）
+type OperationSnapshot struct {
+	*operation.Operation
+	Progress float64 `json:"progress"`
+}
+
+// NewOperationSnapshot 生成操作状态快照
+func NewOperationSnapshot(op *operation.Operation) OperationSnapshot {
+	return OperationSnapshot{Operation: op, Progress: op.Progress()}
+}
+
+// OperationService 长时操作服务 ✨
+// 发起请求只创建操作并返回其ID，执行器在后台执行；
+// 进度、条目级错误和结果持久化在操作中，并通过 operation.update 事件推送到
+// operation:<id> 和 base:<baseId> 频道
+type OperationService struct {
+	repo              operation.Repository
+	permissionService *PermissionServiceV2
+	businessEvents    events.BusinessEventPublisher
+	cfg               config.OperationConfig
+	executors         map[operation.Type]OperationExecutor
+	plans             *PlanService // ✨ 套餐限额（每月后台任务运行次数）
+	wake              chan struct{}
+	now               func() time.Time
+}
+
+// NewOperationService 创建长时操作服务
+func NewOperationService(
+	repo operation.Repository,
+	permissionService *PermissionServiceV2,
+	businessEvents events.BusinessEventPublisher,
+	cfg config.OperationConfig,
+) *OperationService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.ProgressInterval <= 0 {
+		cfg.ProgressInterval = time.Second
+	}
+	if cfg.StaleTimeout <= 0 {
+		cfg.StaleTimeout = 5 * time.Minute
+	}
+	return &OperationService{
+		repo:              repo,
+		permissionService: permissionService,
+		businessEvents:    businessEvents,
+		cfg:               cfg,
+		executors:         make(map[operation.Type]OperationExecutor),
+		wake:              make(chan struct{}, 1),
+		now:               time.Now,
+	}
+}
+
+// RegisterExecutor 注册操作类型的执行器（需在 Start 之前调用）
+func (s *OperationService) RegisterExecutor(opType operation.Type, executor OperationExecutor) {
+	s.executors[opType] = executor
+}
+
+// SetPlanService 设置套餐服务（发起操作前检查后台任务运行次数限额）
+func (s *OperationService) SetPlanService(plans *PlanService) {
+	s.plans = plans
+}
+
+// ==================== 请求接口 ====================
+
+// StartOperation 发起长时操作，返回等待执行的操作
+func (s *OperationService) StartOperation(ctx context.Context, userID, baseID string, req StartOperationRequest) (*operation.Operation, error) {
+	executor, ok := s.executors[req.Type]
+	if !ok {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的操作类型: %s", req.Type))
+	}
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	if err := executor.Prepare(ctx, userID, baseID, req.Params); err != nil {
+		return nil, err
+	}
+	if s.plans != nil {
+		if _, err := s.plans.CheckAutomationRun(ctx, baseID); err != nil {
+			return nil, err
+		}
+	}
+
+	op := operation.NewOperation(baseID, req.Type, req.Params, userID)
+	if err := s.repo.Create(ctx, op); err != nil {
+		return nil, pkgerrors.Database(err, "创建操作失败")
+	}
+	s.publish(op)
+	s.notify()
+
+	operationLog.Info(ctx, "长时操作已创建",
+		logger.String("operation_id", op.ID),
+		logger.String("type", string(op.Type)),
+		logger.String("base_id", baseID))
+	return op, nil
+}
+
+// GetOperation 获取操作状态
+func (s *OperationService) GetOperation(ctx context.Context, userID, operationID string) (*operation.Operation, error) {
+	op, err := s.repo.FindByID(ctx, operationID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取操作失败")
+	}
+	if op == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("操作不存在")
+	}
+	if !s.permissionService.CanAccessBase(ctx, userID, op.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限查看该操作")
+	}
+	return op, nil
+}
+
+// ListOperations 列出 Base 下的操作
+func (s *OperationService) ListOperations(ctx context.Context, userID, baseID string, filter operation.ListFilter) ([]*operation.Operation, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	if filter.Limit > operationListMaxLimit {
+		filter.Limit = operationListMaxLimit
+	}
+	ops, err := s.repo.ListByBase(ctx, baseID, filter)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取操作列表失败")
+	}
+	return ops, nil
+}
+
+// CancelOperation 取消操作
+// 等待中的操作立即取消；执行中的操作设置取消标记，执行器在下一次进度心跳时停止
+func (s *OperationService) CancelOperation(ctx context.Context, userID, operationID string) (*operation.Operation, error) {
+	op, err := s.GetOperation(ctx, userID, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if op.CreatedBy != userID && !s.permissionService.CanUpdateBase(ctx, userID, op.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("只有发起人或Base管理者可以取消操作")
+	}
+	if op.Finished() {
+		return nil, pkgerrors.ErrConflict.WithDetails(fmt.Sprintf("操作已结束（%s）", op.Status))
+	}
+
+	cancelled, err := s.repo.CancelPending(ctx, op.ID, s.now())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "取消操作失败")
+	}
+	if !cancelled {
+		if err := s.repo.RequestCancel(ctx, op.ID); err != nil {
+			return nil, pkgerrors.Database(err, "取消操作失败")
+		}
+	}
+
+	op, err = s.repo.FindByID(ctx, op.ID)
+	if err != nil || op == nil {
+		return nil, pkgerrors.Database(err, "获取操作失败")
+	}
+	if cancelled {
+		s.publish(op)
+	}
+	return op, nil
+}
+
+// ==================== 后台任务 ====================
+
+// Start 启动后台任务：依次执行等待中的操作
+func (s *OperationService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.runPending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// notify 唤醒后台任务（不阻塞请求）
+func (s *OperationService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runPending 标记中断的操作，并依次执行所有等待中的操作
+func (s *OperationService) runPending(ctx context.Context) {
+	now := s.now()
+	if n, err := s.repo.FailStale(ctx, now.Add(-s.cfg.StaleTimeout), now, "执行实例已中断"); err != nil {
+		operationLog.Warn(ctx, "标记中断的操作失败", logger.ErrorField(err))
+	} else if n > 0 {
+		operationLog.Warn(ctx, "已将中断的操作标记为失败", logger.Int64("count", n))
+	}
+
+	for ctx.Err() == nil {
+		op, err := s.repo.Claim(ctx, s.now())
+		if err != nil {
+			operationLog.Warn(ctx, "领取操作失败", logger.ErrorField(err))
+			return
+		}
+		if op == nil {
+			return
+		}
+		s.execute(ctx, op)
+	}
+}
+
+// execute 执行操作并保存最终状态
+func (s *OperationService) execute(ctx context.Context, op *operation.Operation) {
+	executor, ok := s.executors[op.Type]
+	if !ok {
+		s.finish(ctx, op, operation.StatusFailed, nil, fmt.Errorf("no executor registered for operation type %s", op.Type))
+		return
+	}
+	s.publish(op)
+
+	opCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	progress := newOperationProgress(s, op, cancel)
+	stop := progress.startHeartbeat(opCtx, s.cfg.ProgressInterval)
+	result, err := s.runExecutor(opCtx, executor, op, progress)
+	stop()
+
+	var data json.RawMessage
+	if result != nil {
+		encoded, encodeErr := json.Marshal(result)
+		if encodeErr != nil && err == nil {
+			err = fmt.Errorf("failed to encode operation result: %w", encodeErr)
+		}
+		data = encoded
+	}
+
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+	switch {
+	case progress.cancelled:
+		s.finish(context.Background(), op, operation.StatusCancelled, data, nil)
+	case ctx.Err() != nil:
+		// 服务停止：已执行的部分无法安全续跑，标记为失败由用户重新发起
+		s.finish(context.Background(), op, operation.StatusFailed, data, errors.New("服务停止，操作已中断"))
+	case err != nil:
+		s.finish(ctx, op, operation.StatusFailed, data, err)
+	default:
+		s.finish(ctx, op, operation.StatusSucceeded, data, nil)
+	}
+
+	operationLog.Info(ctx, "长时操作结束",
+		logger.String("operation_id", op.ID),
+		logger.String("type", string(op.Type)),
+		logger.String("status", string(op.Status)),
+		logger.Int64("processed", op.Processed),
+		logger.Int64("failed", op.FailedCount))
+}
+
+// runExecutor 执行执行器，执行器 panic 时视为操作失败
+func (s *OperationService) runExecutor(ctx context.Context, executor OperationExecutor, op *operation.Operation, progress *OperationProgress) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("operation executor panic: %v", r)
+		}
+	}()
+	return executor.Execute(ctx, op, progress)
+}
+
+func (s *OperationService) finish(ctx context.Context, op *operation.Operation, status operation.Status, result json.RawMessage, err error) {
+	op.Finish(status, result, err, s.now())
+	if saveErr := s.repo.Update(ctx, op); saveErr != nil {
+		operationLog.Error(ctx, "保存操作结果失败",
+			logger.String("operation_id", op.ID), logger.ErrorField(saveErr))
+	}
+	s.publish(op)
+}
+
+// publish 推送操作状态（调用方保证 op 不被并发修改）
+func (s *OperationService) publish(op *operation.Operation) {
+	if s.businessEvents == nil {
+		return
+	}
+	snapshot := *op
+	snapshot.Errors = append([]operation.ItemError(nil), op.Errors...)
+	if err := s.businessEvents.PublishOperationEvent(context.Background(), op.BaseID, op.ID, NewOperationSnapshot(&snapshot), op.CreatedBy); err != nil {
+		operationLog.Warn(context.Background(), "推送操作事件失败",
+			logger.String("operation_id", op.ID), logger.ErrorField(err))
+	}
+}
+
+// ==================== 进度上报 ====================
+
+// OperationProgress 执行器上报进度的句柄（并发安全）
+// 进度按固定间隔持久化并推送，同时作为心跳；持久化时发现取消请求会取消执行上下文
+type OperationProgress struct {
+	service   *OperationService
+	op        *operation.Operation
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+	dirty     bool
+	cancelled bool
+}
+
+func newOperationProgress(s *OperationService, op *operation.Operation, cancel context.CancelFunc) *OperationProgress {
+	return &OperationProgress{service: s, op: op, cancel: cancel}
+}
+
+// SetTotal 设置待处理总量
+func (p *OperationProgress) SetTotal(total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.op.Total = total
+	p.dirty = true
+}
+
+// Advance 增加已处理数量
+func (p *OperationProgress) Advance(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.op.Processed += n
+	p.dirty = true
+}
+
+// AddError 记录条目级错误（部分失败，操作继续执行）
+func (p *OperationProgress) AddError(index int, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.op.AddItemError(index, message)
+	p.dirty = true
+}
+
+// startHeartbeat 定时保存进度并检查取消请求，返回停止函数
+func (p *OperationProgress) startHeartbeat(ctx context.Context, interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.flush(ctx)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// flush 保存并推送进度（无变化时只刷新心跳），检查取消请求
+func (p *OperationProgress) flush(ctx context.Context) {
+	s := p.service
+	requested, err := s.repo.IsCancelRequested(ctx, p.op.ID)
+	if err != nil {
+		operationLog.Warn(ctx, "检查操作取消请求失败", logger.String("operation_id", p.op.ID), logger.ErrorField(err))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if requested && !p.cancelled {
+		p.cancelled = true
+		p.op.CancelRequested = true
+		p.cancel()
+	}
+	p.op.UpdatedAt = s.now()
+	if err := s.repo.Update(ctx, p.op); err != nil {
+		operationLog.Warn(ctx, "保存操作进度失败", logger.String("operation_id", p.op.ID), logger.ErrorField(err))
+	}
+	if p.dirty {
+		p.dirty = false
+		s.publish(p.op)
+	}
+}
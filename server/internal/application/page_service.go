@@ -0,0 +1,710 @@
+package application
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/page"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// pageMaxPageSize 页面记录列表每页最多返回的记录数
+const pageMaxPageSize = 200
+
+// pageWorkflowRunner 页面按钮触发的工作流（WorkflowService 实现）
+type pageWorkflowRunner interface {
+	GetByID(ctx context.Context, id string) (*models.Workflow, error)
+	Run(ctx context.Context, workflowID, userID string, input map[string]interface{}) (*models.WorkflowRun, error)
+}
+
+// CreatePageRequest 创建页面请求
+type CreatePageRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdatePageRequest 更新页面请求（未提供的部分保持不变，elements / rules 整体替换）
+type UpdatePageRequest struct {
+	Name        *string            `json:"name"`
+	Description *string            `json:"description"`
+	Elements    *[]page.Element    `json:"elements"`
+	Rules       *[]page.AccessRule `json:"rules"`
+}
+
+// UpdatePageRecordRequest 通过页面元素更新记录请求
+type UpdatePageRecordRequest struct {
+	Data map[string]interface{} `json:"data" binding:"required"`
+}
+
+// RunPageButtonRequest 点击页面按钮请求（按钮绑定了表格时需要指定记录）
+type RunPageButtonRequest struct {
+	RecordID string `json:"recordId"`
+}
+
+// PageSummary 页面列表项（附带当前用户的访问级别）
+type PageSummary struct {
+	ID          string      `json:"id"`
+	BaseID      string      `json:"baseId"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Access      page.Access `json:"access"`
+}
+
+// PageRender 页面渲染数据：元素布局与各元素可见字段，不包含分享规则
+type PageRender struct {
+	ID          string              `json:"id"`
+	BaseID      string              `json:"baseId"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Access      page.Access         `json:"access"`
+	Elements    []PageElementRender `json:"elements"`
+}
+
+// PageElementRender 页面元素及其展示的字段
+type PageElementRender struct {
+	page.Element
+	Fields []*dto.FieldResponse `json:"fields,omitempty"`
+}
+
+// PageService 界面页面服务 ✨
+//
+// Base 编辑者把记录列表、详情面板和绑定自动化的按钮组合成页面，并按用户或角色分享给其他人。
+// 页面访问者不需要表格权限：只能读取元素中配置的字段和满足元素视图过滤条件的记录，
+// 只能编辑元素允许编辑的字段，只能触发按钮绑定的工作流；Base 编辑者始终拥有全部访问级别。
+type PageService struct {
+	repo              page.Repository
+	baseRepo          baseRepo.BaseRepository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	recordRepo        recordRepo.RecordRepository
+	recordService     *RecordService
+	privacyService    *PrivacyService
+	workflows         pageWorkflowRunner
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	viewQueries       *ViewQueryCache
+}
+
+// NewPageService 创建界面页面服务
+func NewPageService(
+	repo page.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	viewRepo viewRepo.ViewRepository,
+	recordRepo recordRepo.RecordRepository,
+	recordService *RecordService,
+	privacyService *PrivacyService,
+	workflows pageWorkflowRunner,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+) *PageService {
+	return &PageService{
+		repo:              repo,
+		baseRepo:          baseRepo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepo,
+		recordRepo:        recordRepo,
+		recordService:     recordService,
+		privacyService:    privacyService,
+		workflows:         workflows,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+	}
+}
+
+// ==================== 页面配置 ====================
+
+// CreatePage 在 Base 下创建页面
+func (s *PageService) CreatePage(ctx context.Context, userID, baseID string, req CreatePageRequest) (*page.Page, error) {
+	if !s.canManage(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该Base中创建页面")
+	}
+	exists, err := s.baseRepo.Exists(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "检查Base失败")
+	}
+	if !exists {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Base不存在")
+	}
+
+	p, err := page.NewPage(baseID, req.Name, req.Description, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, p); err != nil {
+		return nil, pkgerrors.Database(err, "创建页面失败")
+	}
+	return p, nil
+}
+
+// ListPages 列出用户可以访问的页面
+func (s *PageService) ListPages(ctx context.Context, userID, baseID string) ([]*PageSummary, error) {
+	list, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询页面失败")
+	}
+	manage := s.canManage(ctx, userID, baseID)
+	role := ""
+	if !manage {
+		role = s.memberRole(ctx, userID, baseID)
+	}
+
+	summaries := make([]*PageSummary, 0, len(list))
+	for _, p := range list {
+		access := page.AccessEdit
+		if !manage {
+			access = p.AccessFor(userID, role)
+		}
+		if access == page.AccessNone {
+			continue
+		}
+		summaries = append(summaries, &PageSummary{
+			ID:          p.ID,
+			BaseID:      p.BaseID,
+			Name:        p.Name,
+			Description: p.Description,
+			Access:      access,
+		})
+	}
+	return summaries, nil
+}
+
+// GetPage 获取页面完整配置（包括分享规则，仅 Base 编辑者）
+func (s *PageService) GetPage(ctx context.Context, userID, pageID string) (*page.Page, error) {
+	return s.loadManagedPage(ctx, userID, pageID)
+}
+
+// UpdatePage 更新页面名称、元素与分享规则
+func (s *PageService) UpdatePage(ctx context.Context, userID, pageID string, req UpdatePageRequest) (*page.Page, error) {
+	p, err := s.loadManagedPage(ctx, userID, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil || req.Description != nil {
+		name, description := p.Name, p.Description
+		if req.Name != nil {
+			name = *req.Name
+		}
+		if req.Description != nil {
+			description = *req.Description
+		}
+		if err := p.Rename(name, description, userID); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+		}
+	}
+	if req.Elements != nil {
+		if err := p.SetElements(*req.Elements, userID); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+		}
+		for i := range p.Elements {
+			if err := s.validateElement(ctx, p.BaseID, &p.Elements[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if req.Rules != nil {
+		if err := p.SetRules(*req.Rules, userID); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+		}
+	}
+
+	if err := s.repo.Save(ctx, p); err != nil {
+		return nil, pkgerrors.Database(err, "更新页面失败")
+	}
+	return p, nil
+}
+
+// DeletePage 删除页面
+func (s *PageService) DeletePage(ctx context.Context, userID, pageID string) error {
+	if _, err := s.loadManagedPage(ctx, userID, pageID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, pageID); err != nil {
+		return pkgerrors.Database(err, "删除页面失败")
+	}
+	return nil
+}
+
+// ==================== 页面访问 ====================
+
+// RenderPage 获取页面布局与各元素展示的字段
+func (s *PageService) RenderPage(ctx context.Context, userID, pageID string) (*PageRender, error) {
+	p, access, err := s.loadPage(ctx, userID, pageID, page.AccessView)
+	if err != nil {
+		return nil, err
+	}
+
+	render := &PageRender{
+		ID:          p.ID,
+		BaseID:      p.BaseID,
+		Name:        p.Name,
+		Description: p.Description,
+		Access:      access,
+		Elements:    make([]PageElementRender, 0, len(p.Elements)),
+	}
+	for i := range p.Elements {
+		element := p.Elements[i]
+		item := PageElementRender{Element: element}
+		if element.Type != page.ElementButton {
+			fields, _, err := s.elementFields(ctx, &element)
+			if err != nil {
+				return nil, err
+			}
+			item.Fields = make([]*dto.FieldResponse, 0, len(fields))
+			for _, field := range fields {
+				item.Fields = append(item.Fields, dto.FromFieldEntity(field))
+			}
+			// 访问者无编辑权限时不暴露可编辑字段
+			if !access.Allows(page.AccessEdit) {
+				item.EditableFieldIDs = nil
+			}
+		}
+		render.Elements = append(render.Elements, item)
+	}
+	return render, nil
+}
+
+// SetViewQueryCache 设置视图查询结果缓存（元素的分页记录ID按依赖缓存）
+func (s *PageService) SetViewQueryCache(viewQueries *ViewQueryCache) {
+	s.viewQueries = viewQueries
+}
+
+// ListElementRecords 按元素视图的过滤与排序分页读取记录（只返回元素展示的字段）
+func (s *PageService) ListElementRecords(ctx context.Context, userID, pageID, elementID string, pageNum, pageSize int) (*dto.RecordListResponse, error) {
+	p, _, err := s.loadPage(ctx, userID, pageID, page.AccessView)
+	if err != nil {
+		return nil, err
+	}
+	element, err := s.dataElement(p, elementID)
+	if err != nil {
+		return nil, err
+	}
+	if pageSize <= 0 || pageSize > pageMaxPageSize {
+		pageSize = pageMaxPageSize
+	}
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+
+	query, byID, view, err := s.elementQuery(ctx, p.BaseID, element)
+	if err != nil {
+		return nil, err
+	}
+	q := ViewQuery{TableID: element.TableID, Page: pageNum, PageSize: pageSize}
+	if view != nil {
+		q.ViewID, q.Filter, q.Sort = view.ID(), view.Filter(), view.Sort()
+	}
+	result, err := s.viewQueries.Load(q, func() (*ViewQueryPage, error) {
+		result := &ViewQueryPage{}
+		if err := query.Session(&gorm.Session{}).Count(&result.Total).Error; err != nil {
+			return nil, pkgerrors.Database(err, "统计记录失败")
+		}
+		for _, order := range embedSortColumns(q.Sort, byID) {
+			query = query.Order(order)
+		}
+		if err := query.Limit(pageSize).Offset((pageNum-1)*pageSize).Pluck("__id", &result.RecordIDs).Error; err != nil {
+			return nil, pkgerrors.Database(err, "查询记录失败")
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	total := result.Total
+
+	records, err := s.loadRecords(ctx, element, result.RecordIDs)
+	if err != nil {
+		return nil, err
+	}
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return &dto.RecordListResponse{
+		Records: records,
+		Pagination: &dto.PaginationResponse{
+			Total:       total,
+			Page:        pageNum,
+			PageSize:    pageSize,
+			TotalPages:  totalPages,
+			HasNext:     pageNum < totalPages,
+			HasPrevious: pageNum > 1,
+		},
+	}, nil
+}
+
+// GetElementRecord 通过元素读取单条记录（记录须满足元素视图的过滤条件）
+func (s *PageService) GetElementRecord(ctx context.Context, userID, pageID, elementID, recordID string) (*dto.RecordResponse, error) {
+	p, _, err := s.loadPage(ctx, userID, pageID, page.AccessView)
+	if err != nil {
+		return nil, err
+	}
+	element, err := s.dataElement(p, elementID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureRecordInElement(ctx, p.BaseID, element, recordID); err != nil {
+		return nil, err
+	}
+	return s.loadRecord(ctx, element, recordID)
+}
+
+// UpdateElementRecord 通过元素更新记录
+// 只能更新元素允许编辑的字段，变更以访问者身份写入（不要求访问者拥有表格的编辑权限）
+func (s *PageService) UpdateElementRecord(ctx context.Context, userID, pageID, elementID, recordID string, req UpdatePageRecordRequest) (*dto.RecordResponse, error) {
+	p, _, err := s.loadPage(ctx, userID, pageID, page.AccessEdit)
+	if err != nil {
+		return nil, err
+	}
+	element, err := s.dataElement(p, elementID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Data) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("没有要更新的字段")
+	}
+	for fieldID := range req.Data {
+		if !element.Editable(fieldID) {
+			return nil, pkgerrors.ErrForbidden.WithDetails("字段不可编辑: " + fieldID)
+		}
+	}
+	fields, _, err := s.elementFields(ctx, element)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if _, ok := req.Data[field.ID().String()]; ok && field.IsComputed() {
+			return nil, pkgerrors.ErrForbidden.WithDetails("字段不可编辑: " + field.ID().String())
+		}
+	}
+	if err := s.ensureRecordInElement(ctx, p.BaseID, element, recordID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.recordService.UpdateRecord(ctx, element.TableID, recordID, dto.UpdateRecordRequest{Data: req.Data}, userID); err != nil {
+		return nil, err
+	}
+	return s.loadRecord(ctx, element, recordID)
+}
+
+// RunButton 点击按钮，触发绑定的工作流
+// 按钮绑定了表格时，记录须属于该表格（并满足按钮视图的过滤条件），记录ID作为工作流输入
+func (s *PageService) RunButton(ctx context.Context, userID, pageID, elementID string, req RunPageButtonRequest) (*models.WorkflowRun, error) {
+	p, _, err := s.loadPage(ctx, userID, pageID, page.AccessInteract)
+	if err != nil {
+		return nil, err
+	}
+	element := p.Element(elementID)
+	if element == nil || element.Type != page.ElementButton {
+		return nil, pkgerrors.ErrNotFound.WithDetails("按钮不存在")
+	}
+
+	input := map[string]interface{}{
+		"pageId":    p.ID,
+		"elementId": element.ID,
+	}
+	if element.TableID != "" {
+		if req.RecordID == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("该按钮需要指定记录")
+		}
+		if err := s.ensureRecordInElement(ctx, p.BaseID, element, req.RecordID); err != nil {
+			return nil, err
+		}
+		input["tableId"] = element.TableID
+		input["recordId"] = req.RecordID
+	}
+
+	run, err := s.workflows.Run(ctx, element.WorkflowID, userID, input)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "触发工作流失败")
+	}
+	return run, nil
+}
+
+// ==================== 内部方法 ====================
+
+// loadPage 获取页面并校验访问级别（Base 编辑者拥有全部级别）
+func (s *PageService) loadPage(ctx context.Context, userID, pageID string, required page.Access) (*page.Page, page.Access, error) {
+	p, err := s.findPage(ctx, pageID)
+	if err != nil {
+		return nil, page.AccessNone, err
+	}
+	access := page.AccessEdit
+	if !s.canManage(ctx, userID, p.BaseID) {
+		access = p.AccessFor(userID, s.memberRole(ctx, userID, p.BaseID))
+	}
+	if access == page.AccessNone {
+		// 没有访问权限的用户看不到页面是否存在
+		return nil, page.AccessNone, pkgerrors.ErrNotFound.WithDetails("页面不存在")
+	}
+	if !access.Allows(required) {
+		return nil, page.AccessNone, pkgerrors.ErrForbidden.WithDetails("没有权限执行该页面操作")
+	}
+	return p, access, nil
+}
+
+// loadManagedPage 获取页面并校验 Base 编辑权限
+func (s *PageService) loadManagedPage(ctx context.Context, userID, pageID string) (*page.Page, error) {
+	p, err := s.findPage(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.canManage(ctx, userID, p.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限修改该页面")
+	}
+	return p, nil
+}
+
+func (s *PageService) findPage(ctx context.Context, pageID string) (*page.Page, error) {
+	p, err := s.repo.FindByID(ctx, pageID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找页面失败")
+	}
+	if p == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("页面不存在")
+	}
+	return p, nil
+}
+
+func (s *PageService) canManage(ctx context.Context, userID, baseID string) bool {
+	return s.permissionService != nil && s.permissionService.CanUpdateBase(ctx, userID, baseID)
+}
+
+// memberRole 用户在 Base（或其空间）中的角色，不是成员时为空
+func (s *PageService) memberRole(ctx context.Context, userID, baseID string) string {
+	if s.permissionService == nil {
+		return ""
+	}
+	if role, err := s.permissionService.GetUserRole(ctx, userID, baseID); err == nil && role != "" {
+		return string(role)
+	}
+	base, err := s.baseRepo.FindByID(ctx, baseID)
+	if err != nil || base == nil {
+		return ""
+	}
+	if role, err := s.permissionService.GetUserRole(ctx, userID, base.SpaceID); err == nil {
+		return string(role)
+	}
+	return ""
+}
+
+// dataElement 查找记录列表或详情元素
+func (s *PageService) dataElement(p *page.Page, elementID string) (*page.Element, error) {
+	element := p.Element(elementID)
+	if element == nil || element.Type == page.ElementButton {
+		return nil, pkgerrors.ErrNotFound.WithDetails("页面元素不存在")
+	}
+	return element, nil
+}
+
+// validateElement 校验元素绑定的表格、视图、字段与工作流
+func (s *PageService) validateElement(ctx context.Context, baseID string, element *page.Element) error {
+	if element.Type == page.ElementButton {
+		workflow, err := s.workflows.GetByID(ctx, element.WorkflowID)
+		if err != nil || workflow == nil {
+			return pkgerrors.ErrNotFound.WithDetails("按钮绑定的工作流不存在: " + element.WorkflowID)
+		}
+	}
+	if element.TableID == "" {
+		return nil
+	}
+	table, err := s.tableRepo.GetByID(ctx, element.TableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil || table.BaseID() != baseID {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在或不属于该页面所在的Base")
+	}
+	if element.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, element.ViewID)
+		if err != nil {
+			return pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.TableID() != element.TableID {
+			return pkgerrors.ErrNotFound.WithDetails("视图不存在或不属于该表格")
+		}
+	}
+	if len(element.FieldIDs) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, element.TableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取字段列表失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	for _, id := range element.FieldIDs {
+		if byID[id] == nil {
+			return pkgerrors.ErrNotFound.WithDetails("字段不存在: " + id)
+		}
+	}
+	for _, id := range element.EditableFieldIDs {
+		if byID[id].IsComputed() {
+			return pkgerrors.ErrValidationFailed.WithDetails("计算字段不能设置为可编辑: " + id)
+		}
+	}
+	return nil
+}
+
+// elementFields 元素展示的字段：配置了 fieldIds 时按配置顺序，否则为视图中可见的字段（未绑定视图时为全部字段）
+func (s *PageService) elementFields(ctx context.Context, element *page.Element) ([]*entity.Field, *viewEntity.View, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, element.TableID)
+	if err != nil {
+		return nil, nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	var view *viewEntity.View
+	if element.ViewID != "" {
+		if view, err = s.viewRepo.FindByID(ctx, element.ViewID); err != nil {
+			return nil, nil, pkgerrors.Database(err, "查找视图失败")
+		}
+	}
+
+	if len(element.FieldIDs) > 0 {
+		byID := make(map[string]*entity.Field, len(fields))
+		for _, field := range fields {
+			byID[field.ID().String()] = field
+		}
+		shown := make([]*entity.Field, 0, len(element.FieldIDs))
+		for _, id := range element.FieldIDs {
+			if field, ok := byID[id]; ok {
+				shown = append(shown, field)
+			}
+		}
+		return shown, view, nil
+	}
+	if view == nil {
+		return fields, nil, nil
+	}
+	hidden := embedHiddenFieldIDs(view, fields)
+	shown := make([]*entity.Field, 0, len(fields))
+	for _, field := range fields {
+		if !hidden[field.ID().String()] {
+			shown = append(shown, field)
+		}
+	}
+	return shown, view, nil
+}
+
+// elementQuery 元素表格的查询（已应用元素视图的过滤条件）
+func (s *PageService) elementQuery(ctx context.Context, baseID string, element *page.Element) (*gorm.DB, map[string]*entity.Field, *viewEntity.View, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, element.TableID)
+	if err != nil {
+		return nil, nil, nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	query := s.dataDB(ctx, baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(baseID, element.TableID))
+	var view *viewEntity.View
+	if element.ViewID != "" {
+		if view, err = s.viewRepo.FindByID(ctx, element.ViewID); err != nil {
+			return nil, nil, nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil {
+			return nil, nil, nil, pkgerrors.ErrNotFound.WithDetails("页面元素绑定的视图不存在")
+		}
+		condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if condition != nil {
+			query = query.Where(condition)
+		}
+	}
+	return query, byID, view, nil
+}
+
+// ensureRecordInElement 记录是否属于元素的表格并满足元素视图的过滤条件
+func (s *PageService) ensureRecordInElement(ctx context.Context, baseID string, element *page.Element, recordID string) error {
+	query, _, _, err := s.elementQuery(ctx, baseID, element)
+	if err != nil {
+		return err
+	}
+	var count int64
+	if err := query.Where("__id = ?", recordID).Count(&count).Error; err != nil {
+		return pkgerrors.Database(err, "查询记录失败")
+	}
+	if count == 0 {
+		return pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+	return nil
+}
+
+func (s *PageService) loadRecord(ctx context.Context, element *page.Element, recordID string) (*dto.RecordResponse, error) {
+	records, err := s.loadRecords(ctx, element, []string{recordID})
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+	return records[0], nil
+}
+
+// loadRecords 按给定顺序加载记录，脱敏后只保留元素展示的字段
+func (s *PageService) loadRecords(ctx context.Context, element *page.Element, ids []string) ([]*dto.RecordResponse, error) {
+	if len(ids) == 0 {
+		return []*dto.RecordResponse{}, nil
+	}
+	fields, _, err := s.elementFields(ctx, element)
+	if err != nil {
+		return nil, err
+	}
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, element.TableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+
+	byID := make(map[string]*dto.RecordResponse, len(entities))
+	for _, record := range entities {
+		byID[record.ID().String()] = dto.FromRecordEntity(record)
+	}
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := byID[id]; ok {
+			records = append(records, record)
+		}
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, element.TableID, records...)
+	}
+	for _, record := range records {
+		record.Data = pageElementData(record.Data, fields)
+	}
+	return records, nil
+}
+
+// pageElementData 只保留元素展示的字段
+func pageElementData(data map[string]interface{}, fields []*entity.Field) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := data[field.ID().String()]; ok {
+			projected[field.ID().String()] = value
+		}
+	}
+	return projected
+}
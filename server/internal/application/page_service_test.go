@@ -0,0 +1,154 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/page"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+type memoryPageRepo struct {
+	pages map[string]*page.Page
+}
+
+func (r *memoryPageRepo) Save(ctx context.Context, p *page.Page) error {
+	r.pages[p.ID] = p
+	return nil
+}
+
+func (r *memoryPageRepo) FindByID(ctx context.Context, id string) (*page.Page, error) {
+	return r.pages[id], nil
+}
+
+func (r *memoryPageRepo) ListByBase(ctx context.Context, baseID string) ([]*page.Page, error) {
+	var list []*page.Page
+	for _, p := range r.pages {
+		if p.BaseID == baseID {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryPageRepo) Delete(ctx context.Context, id string) error {
+	delete(r.pages, id)
+	return nil
+}
+
+type stubPageWorkflows struct {
+	runs []map[string]interface{}
+}
+
+func (w *stubPageWorkflows) GetByID(ctx context.Context, id string) (*models.Workflow, error) {
+	return &models.Workflow{ID: id}, nil
+}
+
+func (w *stubPageWorkflows) Run(ctx context.Context, workflowID, userID string, input map[string]interface{}) (*models.WorkflowRun, error) {
+	w.runs = append(w.runs, input)
+	return &models.WorkflowRun{ID: "wfr1", WorkflowID: workflowID, CreatedBy: userID, Status: "running"}, nil
+}
+
+// pageFixture 一个页面：记录列表（fld2 可编辑）与一个不绑定表格的按钮
+type pageFixture struct {
+	service   *PageService
+	workflows *stubPageWorkflows
+	page      *page.Page
+}
+
+func newPageFixture(t *testing.T) *pageFixture {
+	t.Helper()
+	p, err := page.NewPage("bse1", "报修门户", "", "owner")
+	if err != nil {
+		t.Fatalf("创建页面失败: %v", err)
+	}
+	err = p.SetElements([]page.Element{
+		{ID: "list", Type: page.ElementRecordList, TableID: "tbl1", FieldIDs: []string{"fld1", "fld2"}, EditableFieldIDs: []string{"fld2"}},
+		{ID: "notify", Type: page.ElementButton, WorkflowID: "wfl1"},
+	}, "owner")
+	if err != nil {
+		t.Fatalf("设置页面元素失败: %v", err)
+	}
+	err = p.SetRules([]page.AccessRule{
+		{PrincipalType: page.PrincipalUser, PrincipalID: "viewer", Access: page.AccessView},
+		{PrincipalType: page.PrincipalUser, PrincipalID: "clicker", Access: page.AccessInteract},
+		{PrincipalType: page.PrincipalUser, PrincipalID: "editor", Access: page.AccessEdit},
+	}, "owner")
+	if err != nil {
+		t.Fatalf("设置分享规则失败: %v", err)
+	}
+
+	repo := &memoryPageRepo{pages: map[string]*page.Page{p.ID: p}}
+	workflows := &stubPageWorkflows{}
+	service := NewPageService(repo, nil, nil, nil, nil, nil, nil, nil, workflows, nil, nil, nil)
+	return &pageFixture{service: service, workflows: workflows, page: p}
+}
+
+func TestPageListOnlyShowsSharedPages(t *testing.T) {
+	f := newPageFixture(t)
+	ctx := context.Background()
+
+	list, err := f.service.ListPages(ctx, "clicker", "bse1")
+	if err != nil {
+		t.Fatalf("列出页面失败: %v", err)
+	}
+	if len(list) != 1 || list[0].Access != page.AccessInteract {
+		t.Errorf("应列出分享给用户的页面及其访问级别，得到 %+v", list)
+	}
+
+	list, err = f.service.ListPages(ctx, "stranger", "bse1")
+	if err != nil {
+		t.Fatalf("列出页面失败: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("没有分享规则匹配的用户不应看到页面，得到 %+v", list)
+	}
+}
+
+func TestPageButtonRequiresInteractAccess(t *testing.T) {
+	f := newPageFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.RunButton(ctx, "viewer", f.page.ID, "notify", RunPageButtonRequest{})
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrForbidden.Code {
+		t.Errorf("只有查看权限的用户不能点击按钮，得到 %v", err)
+	}
+	_, err = f.service.RunButton(ctx, "stranger", f.page.ID, "notify", RunPageButtonRequest{})
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrNotFound.Code {
+		t.Errorf("没有访问权限的用户应看不到页面，得到 %v", err)
+	}
+	if _, err := f.service.RunButton(ctx, "clicker", f.page.ID, "list", RunPageButtonRequest{}); err == nil {
+		t.Error("记录列表元素不能作为按钮触发")
+	}
+
+	run, err := f.service.RunButton(ctx, "clicker", f.page.ID, "notify", RunPageButtonRequest{})
+	if err != nil {
+		t.Fatalf("点击按钮失败: %v", err)
+	}
+	if run.WorkflowID != "wfl1" || run.CreatedBy != "clicker" {
+		t.Errorf("应以访问者身份触发按钮绑定的工作流，得到 %+v", run)
+	}
+	if len(f.workflows.runs) != 1 || f.workflows.runs[0]["pageId"] != f.page.ID || f.workflows.runs[0]["elementId"] != "notify" {
+		t.Errorf("工作流输入应包含页面与按钮，得到 %+v", f.workflows.runs)
+	}
+}
+
+func TestPageRecordUpdateLimitedToEditableFields(t *testing.T) {
+	f := newPageFixture(t)
+	ctx := context.Background()
+	req := UpdatePageRecordRequest{Data: map[string]interface{}{"fld1": "x"}}
+
+	_, err := f.service.UpdateElementRecord(ctx, "clicker", f.page.ID, "list", "rec1", req)
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrForbidden.Code {
+		t.Errorf("没有编辑权限的用户不能更新记录，得到 %v", err)
+	}
+	_, err = f.service.UpdateElementRecord(ctx, "editor", f.page.ID, "list", "rec1", req)
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrForbidden.Code {
+		t.Errorf("不在 editableFieldIds 中的字段不能更新，得到 %v", err)
+	}
+	_, err = f.service.UpdateElementRecord(ctx, "editor", f.page.ID, "notify", "rec1", UpdatePageRecordRequest{Data: map[string]interface{}{"fld2": "x"}})
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrNotFound.Code {
+		t.Errorf("按钮元素不能读写记录，得到 %v", err)
+	}
+}
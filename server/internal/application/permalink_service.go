@@ -0,0 +1,337 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/permalink"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// permalinkRecordReader 读取记录并计算展示文本（由 RecordService 实现，按当前用户脱敏）
+type permalinkRecordReader interface {
+	GetRecord(ctx context.Context, tableID, recordID string) (*dto.RecordResponse, error)
+	FormatRecords(ctx context.Context, tableID string, records []*dto.RecordResponse, opts RecordFormatOptions) error
+}
+
+// CreatePermalinkRequest 创建永久链接请求：提供 recordId 时为记录链接（viewId 可选），否则为视图链接
+type CreatePermalinkRequest struct {
+	TableID  string `json:"tableId" binding:"required"`
+	ViewID   string `json:"viewId"`
+	RecordID string `json:"recordId"`
+}
+
+// PermalinkResponse 永久链接
+type PermalinkResponse struct {
+	Code       string               `json:"code"`
+	URL        string               `json:"url"`
+	TargetType permalink.TargetType `json:"targetType"`
+	TableID    string               `json:"tableId"`
+	ViewID     string               `json:"viewId,omitempty"`
+	RecordID   string               `json:"recordId,omitempty"`
+	CreatedAt  time.Time            `json:"createdAt"`
+}
+
+// PermalinkLocation 链接解析结果：目标当前所在的位置
+type PermalinkLocation struct {
+	Code       string               `json:"code"`
+	TargetType permalink.TargetType `json:"targetType"`
+	SpaceID    string               `json:"spaceId"`
+	BaseID     string               `json:"baseId"`
+	TableID    string               `json:"tableId"`
+	ViewID     string               `json:"viewId,omitempty"` // 记录链接的视图已删除或无权查看时为空
+	RecordID   string               `json:"recordId,omitempty"`
+	Path       string               `json:"path"` // Web 应用中的路径，如 /base/{baseId}/{tableId}/{viewId}?recordId=…
+}
+
+// PermalinkPreviewField 链接预览中的字段
+type PermalinkPreviewField struct {
+	FieldID string `json:"fieldId"`
+	Name    string `json:"name"`
+	Value   string `json:"value"` // 按字段格式化后的展示文本
+}
+
+// PermalinkPreview 链接预览（供聊天工具展开链接）
+type PermalinkPreview struct {
+	URL        string                  `json:"url"`
+	TargetType permalink.TargetType    `json:"targetType"`
+	Title      string                  `json:"title"`    // 记录的主字段值，视图链接为视图名称
+	Subtitle   string                  `json:"subtitle"` // Base / 表 / 视图名称
+	Fields     []PermalinkPreviewField `json:"fields,omitempty"`
+	Location   *PermalinkLocation      `json:"location"`
+}
+
+// PermalinkService 记录与视图永久链接服务 ✨
+// 为记录和视图生成稳定的短链接（同一目标总是同一个链接），解析时：
+//   - 每次都重新校验访问者对表、视图的权限，链接本身不授予任何访问权
+//   - 按目标当前所在的 Base 返回规范位置，记录链接的视图失效时回退到表
+//   - 预览只包含访问者可见的数据：主字段作为标题，加上视图中靠前的几个非空字段，敏感字段按脱敏策略处理
+type PermalinkService struct {
+	repo              permalink.Repository
+	baseRepo          baseRepo.BaseRepository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         fieldRepo.FieldRepository
+	records           permalinkRecordReader
+	permissionService *PermissionServiceV2
+	cfg               config.PermalinkConfig
+	now               func() time.Time
+}
+
+// NewPermalinkService 创建永久链接服务
+func NewPermalinkService(
+	repo permalink.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	records permalinkRecordReader,
+	permissionService *PermissionServiceV2,
+	cfg config.PermalinkConfig,
+) *PermalinkService {
+	return &PermalinkService{
+		repo:              repo,
+		baseRepo:          baseRepo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		fieldRepo:         fieldRepo,
+		records:           records,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// CreatePermalink 获取或创建记录、视图的永久链接（需要能查看目标）
+func (s *PermalinkService) CreatePermalink(ctx context.Context, userID string, req CreatePermalinkRequest) (*PermalinkResponse, error) {
+	var link *permalink.Permalink
+	var err error
+	if req.RecordID != "" {
+		link, err = permalink.NewRecordLink(req.TableID, req.ViewID, req.RecordID, userID, s.now())
+	} else {
+		link, err = permalink.NewViewLink(req.TableID, req.ViewID, userID, s.now())
+	}
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	if err := s.checkAccess(ctx, userID, link.TableID); err != nil {
+		return nil, err
+	}
+	if link.ViewID != "" {
+		if _, err := s.readableView(ctx, userID, link.TableID, link.ViewID); err != nil {
+			return nil, err
+		}
+	}
+	if link.RecordID != "" {
+		if _, err := s.records.GetRecord(ctx, link.TableID, link.RecordID); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.repo.GetByTarget(ctx, link)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询永久链接失败")
+	}
+	if existing == nil {
+		if existing, err = s.repo.Create(ctx, link); err != nil {
+			return nil, pkgerrors.Database(err, "创建永久链接失败")
+		}
+	}
+	return s.toResponse(existing), nil
+}
+
+// Resolve 解析链接，返回目标当前的规范位置（链接不存在、目标已删除或无权查看时报错）
+func (s *PermalinkService) Resolve(ctx context.Context, userID, code string) (*PermalinkLocation, error) {
+	location, _, err := s.resolve(ctx, userID, code)
+	return location, err
+}
+
+// Preview 链接预览：标题与关键字段的展示文本
+func (s *PermalinkService) Preview(ctx context.Context, userID, code string) (*PermalinkPreview, error) {
+	location, target, err := s.resolve(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PermalinkPreview{
+		URL:        target.link.URL(s.cfg.WebBaseURL),
+		TargetType: location.TargetType,
+		Location:   location,
+	}
+	subtitle := target.baseName + " / " + target.tableName
+	if target.view != nil {
+		subtitle += " / " + target.view.Name()
+	}
+	preview.Subtitle = subtitle
+
+	if location.TargetType == permalink.TargetView {
+		preview.Title = target.view.Name()
+		return preview, nil
+	}
+
+	record := target.record
+	if err := s.records.FormatRecords(ctx, location.TableID, []*dto.RecordResponse{record}, RecordFormatOptions{}); err != nil {
+		return nil, err
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, location.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	preview.Title, preview.Fields = s.previewFields(fields, target.view, record)
+	return preview, nil
+}
+
+// permalinkTarget 解析过程中查到的目标信息
+type permalinkTarget struct {
+	link      *permalink.Permalink
+	view      *viewEntity.View
+	record    *dto.RecordResponse
+	baseName  string
+	tableName string
+}
+
+func (s *PermalinkService) resolve(ctx context.Context, userID, code string) (*PermalinkLocation, *permalinkTarget, error) {
+	if !permalink.ValidCode(code) {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails("链接不存在")
+	}
+	link, err := s.repo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, nil, pkgerrors.Database(err, "查询永久链接失败")
+	}
+	if link == nil {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails("链接不存在")
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, link.TableID)
+	if err != nil {
+		return nil, nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails("链接指向的表格已删除")
+	}
+	base, err := s.baseRepo.FindByID(ctx, table.BaseID())
+	if err != nil {
+		return nil, nil, pkgerrors.Database(err, "查找Base失败")
+	}
+	if base == nil {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails("链接指向的Base已删除")
+	}
+	if err := s.checkAccess(ctx, userID, link.TableID); err != nil {
+		return nil, nil, err
+	}
+
+	target := &permalinkTarget{link: link, baseName: base.Name, tableName: table.Name().String()}
+	location := &PermalinkLocation{
+		Code:       link.Code,
+		TargetType: link.TargetType,
+		SpaceID:    base.SpaceID,
+		BaseID:     base.ID,
+		TableID:    link.TableID,
+		RecordID:   link.RecordID,
+	}
+
+	if link.ViewID != "" {
+		view, err := s.readableView(ctx, userID, link.TableID, link.ViewID)
+		switch {
+		case err == nil:
+			target.view = view
+			location.ViewID = view.ID()
+		case link.TargetType == permalink.TargetView:
+			return nil, nil, err
+		}
+		// 记录链接的视图失效时回退到表，仍然可以打开记录
+	}
+	if link.TargetType == permalink.TargetRecord {
+		record, err := s.records.GetRecord(ctx, link.TableID, link.RecordID)
+		if err != nil {
+			return nil, nil, err
+		}
+		target.record = record
+	}
+
+	location.Path = permalinkPath(location)
+	return location, target, nil
+}
+
+// checkAccess 校验访问者能否查看表中的记录
+func (s *PermalinkService) checkAccess(ctx context.Context, userID, tableID string) error {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有访问该表格的权限")
+	}
+	return nil
+}
+
+// readableView 视图须存在、属于该表且访问者可以查看
+func (s *PermalinkService) readableView(ctx context.Context, userID, tableID, viewID string) (*viewEntity.View, error) {
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil || view.IsDeleted() || view.TableID() != tableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	if !s.permissionService.CanReadView(ctx, userID, viewID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+	}
+	return view, nil
+}
+
+// previewFields 主字段作为标题，其余为视图中可见（无视图时按字段顺序）的前几个非空字段
+func (s *PermalinkService) previewFields(fields []*fieldEntity.Field, view *viewEntity.View, record *dto.RecordResponse) (string, []PermalinkPreviewField) {
+	var hidden map[string]bool
+	if view != nil {
+		hidden = embedHiddenFieldIDs(view, fields)
+	}
+	title := record.ID
+	var result []PermalinkPreviewField
+	for _, field := range fields {
+		fieldID := field.ID().String()
+		value := record.Formatted[fieldID]
+		if field.IsPrimary() {
+			if value != "" {
+				title = value
+			}
+			continue
+		}
+		if value == "" || hidden[fieldID] || len(result) >= s.cfg.PreviewFields {
+			continue
+		}
+		result = append(result, PermalinkPreviewField{FieldID: fieldID, Name: field.Name().String(), Value: value})
+	}
+	return title, result
+}
+
+func (s *PermalinkService) toResponse(link *permalink.Permalink) *PermalinkResponse {
+	return &PermalinkResponse{
+		Code:       link.Code,
+		URL:        link.URL(s.cfg.WebBaseURL),
+		TargetType: link.TargetType,
+		TableID:    link.TableID,
+		ViewID:     link.ViewID,
+		RecordID:   link.RecordID,
+		CreatedAt:  link.CreatedAt,
+	}
+}
+
+// permalinkPath Web 应用中打开目标的路径
+func permalinkPath(location *PermalinkLocation) string {
+	path := fmt.Sprintf("/base/%s/%s", url.PathEscape(location.BaseID), url.PathEscape(location.TableID))
+	if location.ViewID != "" {
+		path += "/" + url.PathEscape(location.ViewID)
+	}
+	if location.RecordID != "" {
+		path += "?recordId=" + url.QueryEscape(location.RecordID)
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/permalink"
+)
+
+func TestPermalinkPreviewFields(t *testing.T) {
+	name := newTemplateTestField(t, "Name", fieldVO.TypeSingleLineText)
+	if err := name.SetPrimary(true); err != nil {
+		t.Fatal(err)
+	}
+	status := newTemplateTestField(t, "Status", fieldVO.TypeSingleLineText)
+	empty := newTemplateTestField(t, "Notes", fieldVO.TypeSingleLineText)
+	owner := newTemplateTestField(t, "Owner", fieldVO.TypeSingleLineText)
+	due := newTemplateTestField(t, "Due", fieldVO.TypeSingleLineText)
+	fields := []*fieldEntity.Field{status, name, empty, owner, due}
+
+	record := &dto.RecordResponse{ID: "rec1", Formatted: map[string]string{
+		name.ID().String():   "Launch",
+		status.ID().String(): "Active",
+		owner.ID().String():  "Alice",
+		due.ID().String():    "2026-10-15",
+	}}
+	svc := &PermalinkService{cfg: config.PermalinkConfig{PreviewFields: 2}}
+	title, preview := svc.previewFields(fields, nil, record)
+	if title != "Launch" {
+		t.Errorf("标题应为主字段值，得到 %q", title)
+	}
+	if len(preview) != 2 || preview[0].Name != "Status" || preview[1].Name != "Owner" || preview[1].Value != "Alice" {
+		t.Errorf("应按字段顺序取前两个非空字段（不含主字段），得到 %+v", preview)
+	}
+
+	title, _ = svc.previewFields(fields, nil, &dto.RecordResponse{ID: "rec2", Formatted: map[string]string{}})
+	if title != "rec2" {
+		t.Errorf("主字段为空时标题应为记录ID，得到 %q", title)
+	}
+}
+
+func TestPermalinkPath(t *testing.T) {
+	location := &PermalinkLocation{TargetType: permalink.TargetRecord, BaseID: "bse1", TableID: "tbl1", RecordID: "rec1"}
+	if got := permalinkPath(location); got != "/base/bse1/tbl1?recordId=rec1" {
+		t.Errorf("得到 %s", got)
+	}
+	location.ViewID = "viw1"
+	if got := permalinkPath(location); got != "/base/bse1/tbl1/viw1?recordId=rec1" {
+		t.Errorf("得到 %s", got)
+	}
+	view := &PermalinkLocation{TargetType: permalink.TargetView, BaseID: "bse1", TableID: "tbl1", ViewID: "viw1"}
+	if got := permalinkPath(view); got != "/base/bse1/tbl1/viw1" {
+		t.Errorf("得到 %s", got)
+	}
+}
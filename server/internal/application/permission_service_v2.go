@@ -10,6 +10,7 @@ import (
 	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
 	spaceRepo "github.com/easyspace-ai/luckdb/server/internal/domain/space/repository"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
@@ -55,8 +56,8 @@ func NewPermissionServiceV2(
 // Can 检查用户是否可以对资源执行某个动作
 // 这是核心权限检查方法，所有其他方法都基于此
 func (s *PermissionServiceV2) Can(ctx context.Context, userID, resourceID string, resourceType entity.ResourceType, action permission.Action) bool {
-	// 1. 查找用户在该资源上的协作者记录
-	collaborator, err := s.collaboratorRepo.FindByResourceAndPrincipal(ctx, resourceID, userID)
+	// 1. 查找用户在该资源上的协作者角色
+	role, err := s.findRole(ctx, resourceID, userID)
 	if err != nil {
 		logger.Debug("No collaborator found",
 			zap.String("user_id", userID),
@@ -68,12 +69,12 @@ func (s *PermissionServiceV2) Can(ctx context.Context, userID, resourceID string
 	}
 
 	// 2. 根据角色权限矩阵检查是否有权限
-	hasPermission := permission.HasPermission(collaborator.Role(), action)
+	hasPermission := permission.HasPermission(role, action)
 
 	logger.Debug("Permission check",
 		zap.String("user_id", userID),
 		zap.String("resource_id", resourceID),
-		zap.String("role", string(collaborator.Role())),
+		zap.String("role", string(role)),
 		zap.String("action", string(action)),
 		zap.Bool("granted", hasPermission),
 	)
@@ -81,6 +82,26 @@ func (s *PermissionServiceV2) Can(ctx context.Context, userID, resourceID string
 	return hasPermission
 }
 
+// findRole 查找用户在 Space/Base 上的协作者角色（上下文附加了校验批次时复用批次的解析结果）
+func (s *PermissionServiceV2) findRole(ctx context.Context, resourceID, userID string) (entity.RoleName, error) {
+	if batch := authorizationBatchFromContext(ctx); batch != nil && batch.permissions == s {
+		return batch.role(ctx, resourceID, userID)
+	}
+	collaborator, err := s.collaboratorRepo.FindByResourceAndPrincipal(ctx, resourceID, userID)
+	if err != nil || collaborator == nil {
+		return "", err
+	}
+	return collaborator.Role(), nil
+}
+
+// getTable 获取表（上下文附加了校验批次时复用批次的解析结果）
+func (s *PermissionServiceV2) getTable(ctx context.Context, tableID string) (*tableEntity.Table, error) {
+	if batch := authorizationBatchFromContext(ctx); batch != nil && batch.permissions == s {
+		return batch.table(ctx, tableID)
+	}
+	return s.tableRepo.GetByID(ctx, tableID)
+}
+
 // ==================== Space权限 ====================
 
 // CanAccessSpace 检查用户是否可以访问Space
@@ -147,7 +168,7 @@ func (s *PermissionServiceV2) CanCreateTablesInBase(ctx context.Context, userID,
 // Table继承Base的权限
 func (s *PermissionServiceV2) CanAccessTable(ctx context.Context, userID, tableID string) bool {
 	// 1. 获取Table所属的Base
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -158,7 +179,7 @@ func (s *PermissionServiceV2) CanAccessTable(ctx context.Context, userID, tableI
 
 // CanManageTableSchema 检查用户是否可以管理Table结构（字段、视图）
 func (s *PermissionServiceV2) CanManageTableSchema(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -166,9 +187,22 @@ func (s *PermissionServiceV2) CanManageTableSchema(ctx context.Context, userID,
 	return s.Can(ctx, userID, table.BaseID(), entity.ResourceTypeBase, permission.ActionTableFieldCreate)
 }
 
+// TableRole 获取用户在表所属 Base 上的协作者角色（不是协作者时返回 false）
+func (s *PermissionServiceV2) TableRole(ctx context.Context, userID, tableID string) (string, bool) {
+	table, err := s.getTable(ctx, tableID)
+	if err != nil || table == nil {
+		return "", false
+	}
+	role, err := s.findRole(ctx, table.BaseID(), userID)
+	if err != nil || role == "" {
+		return "", false
+	}
+	return string(role), true
+}
+
 // CanDeleteTable 检查用户是否可以删除Table
 func (s *PermissionServiceV2) CanDeleteTable(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -180,7 +214,7 @@ func (s *PermissionServiceV2) CanDeleteTable(ctx context.Context, userID, tableI
 
 // CanAccessRecord 检查用户是否可以访问Record
 func (s *PermissionServiceV2) CanAccessRecord(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -190,7 +224,7 @@ func (s *PermissionServiceV2) CanAccessRecord(ctx context.Context, userID, table
 
 // CanCreateRecordsInTable 检查用户是否可以在Table中创建Record
 func (s *PermissionServiceV2) CanCreateRecordsInTable(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -200,7 +234,7 @@ func (s *PermissionServiceV2) CanCreateRecordsInTable(ctx context.Context, userI
 
 // CanUpdateRecordsInTable 检查用户是否可以更新Record
 func (s *PermissionServiceV2) CanUpdateRecordsInTable(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -210,7 +244,7 @@ func (s *PermissionServiceV2) CanUpdateRecordsInTable(ctx context.Context, userI
 
 // CanDeleteRecordsInTable 检查用户是否可以删除Record
 func (s *PermissionServiceV2) CanDeleteRecordsInTable(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -263,7 +297,7 @@ func (s *PermissionServiceV2) CanUpdateField(ctx context.Context, userID, fieldI
 	}
 
 	// 2. 检查Table的字段更新权限
-	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	table, err := s.getTable(ctx, field.TableID())
 	if err != nil {
 		return false
 	}
@@ -286,7 +320,7 @@ func (s *PermissionServiceV2) CanDeleteField(ctx context.Context, userID, fieldI
 	}
 
 	// 2. 检查Table的字段删除权限
-	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	table, err := s.getTable(ctx, field.TableID())
 	if err != nil {
 		return false
 	}
@@ -296,7 +330,7 @@ func (s *PermissionServiceV2) CanDeleteField(ctx context.Context, userID, fieldI
 
 // CanCreateField 检查用户是否可以在Table中创建Field
 func (s *PermissionServiceV2) CanCreateField(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -339,7 +373,7 @@ func (s *PermissionServiceV2) CanUpdateView(ctx context.Context, userID, viewID
 	}
 
 	// 2. 检查Table的视图更新权限
-	table, err := s.tableRepo.GetByID(ctx, view.TableID())
+	table, err := s.getTable(ctx, view.TableID())
 	if err != nil {
 		return false
 	}
@@ -362,7 +396,7 @@ func (s *PermissionServiceV2) CanDeleteView(ctx context.Context, userID, viewID
 	}
 
 	// 2. 检查Table的视图删除权限
-	table, err := s.tableRepo.GetByID(ctx, view.TableID())
+	table, err := s.getTable(ctx, view.TableID())
 	if err != nil {
 		return false
 	}
@@ -372,7 +406,7 @@ func (s *PermissionServiceV2) CanDeleteView(ctx context.Context, userID, viewID
 
 // CanCreateView 检查用户是否可以在Table中创建View
 func (s *PermissionServiceV2) CanCreateView(ctx context.Context, userID, tableID string) bool {
-	table, err := s.tableRepo.GetByID(ctx, tableID)
+	table, err := s.getTable(ctx, tableID)
 	if err != nil {
 		return false
 	}
@@ -0,0 +1,392 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	spaceRepo "github.com/easyspace-ai/luckdb/server/internal/domain/space/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/usage"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var planLog = logger.Named("plan")
+
+// AssignPlanRequest 为工作空间分配套餐请求
+type AssignPlanRequest struct {
+	PlanID string `json:"plan_id" binding:"required"`
+}
+
+// spaceMeter 工作空间的计量快照
+// 快照有效期内通过检查的操作直接累加到快照上，避免在两次计量之间超出硬限额
+type spaceMeter struct {
+	plan      *plan.Plan
+	usage     *plan.Usage
+	baseNames map[string]string
+	expiresAt time.Time
+}
+
+// PlanService 套餐限额与用量计量 ✨
+// 每月累计的后台任务运行与 API 调用取自用量统计的按天汇总（加上尚未写入的调用计数），
+// 附件占用取最近一次汇总的值，记录数按 Base 实时统计。计量结果按空间缓存 MeterTTL；
+// 超过软限额时允许并记录警告，超过硬限额时拒绝（未启用时只计量）。计量失败时放行
+type PlanService struct {
+	repo              plan.Repository
+	usageRepo         usage.Repository
+	usageService      *UsageService
+	spaceRepo         spaceRepo.SpaceRepository
+	baseRepo          baseRepo.BaseRepository
+	tableRepo         tableRepo.TableRepository
+	recordRepo        recordRepo.RecordRepository
+	permissionService *PermissionServiceV2
+	cfg               config.BillingConfig
+	now               func() time.Time
+
+	mu     sync.Mutex
+	meters map[string]*spaceMeter
+	warned map[string]time.Time // 空间与限额项 -> 已警告的计费周期
+}
+
+// NewPlanService 创建套餐服务
+func NewPlanService(
+	repo plan.Repository,
+	usageRepo usage.Repository,
+	usageService *UsageService,
+	spaceRepo spaceRepo.SpaceRepository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	recordRepo recordRepo.RecordRepository,
+	permissionService *PermissionServiceV2,
+	cfg config.BillingConfig,
+) *PlanService {
+	if _, ok := plan.Find(cfg.DefaultPlan); !ok {
+		cfg.DefaultPlan = plan.DefaultPlanID
+	}
+	if cfg.MeterTTL <= 0 {
+		cfg.MeterTTL = 30 * time.Second
+	}
+	return &PlanService{
+		repo:              repo,
+		usageRepo:         usageRepo,
+		usageService:      usageService,
+		spaceRepo:         spaceRepo,
+		baseRepo:          baseRepo,
+		tableRepo:         tableRepo,
+		recordRepo:        recordRepo,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+		meters:            make(map[string]*spaceMeter),
+		warned:            make(map[string]time.Time),
+	}
+}
+
+// Plans 内置套餐列表
+func (s *PlanService) Plans() []*plan.Plan {
+	return plan.Catalog()
+}
+
+// ==================== 用量与套餐 ====================
+
+// GetUsage 获取工作空间的套餐与用量（仅空间所有者）
+func (s *PlanService) GetUsage(ctx context.Context, userID, spaceID string) (*plan.Summary, error) {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return nil, pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以查看套餐用量")
+	}
+	return s.summary(ctx, spaceID)
+}
+
+// AdminGetUsage 获取工作空间的套餐与用量（管理员）
+func (s *PlanService) AdminGetUsage(ctx context.Context, spaceID string) (*plan.Summary, error) {
+	if err := s.ensureSpace(ctx, spaceID); err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, spaceID)
+}
+
+// AssignPlan 为工作空间分配套餐（管理员），立即按新套餐检查
+func (s *PlanService) AssignPlan(ctx context.Context, adminID, spaceID string, req AssignPlanRequest) (*plan.Summary, error) {
+	if err := s.ensureSpace(ctx, spaceID); err != nil {
+		return nil, err
+	}
+	if err := s.setPlan(ctx, spaceID, req.PlanID, adminID); err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, spaceID)
+}
+
+// setPlan 保存工作空间的套餐并丢弃计量快照；updatedBy 为管理员ID或变更来源（如 stripe）
+func (s *PlanService) setPlan(ctx context.Context, spaceID, planID, updatedBy string) error {
+	assignment, err := plan.NewAssignment(spaceID, planID, updatedBy, s.now())
+	if err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveAssignment(ctx, assignment); err != nil {
+		return pkgerrors.Database(err, "保存套餐失败")
+	}
+
+	s.mu.Lock()
+	delete(s.meters, spaceID)
+	s.mu.Unlock()
+
+	planLog.Info(ctx, "工作空间套餐已变更",
+		logger.String("space_id", spaceID),
+		logger.String("plan_id", assignment.PlanID),
+		logger.String("updated_by", updatedBy))
+	return nil
+}
+
+func (s *PlanService) ensureSpace(ctx context.Context, spaceID string) error {
+	space, err := s.spaceRepo.GetByID(ctx, spaceID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取空间失败")
+	}
+	if space == nil {
+		return pkgerrors.ErrNotFound.WithDetails("空间不存在")
+	}
+	return nil
+}
+
+func (s *PlanService) summary(ctx context.Context, spaceID string) (*plan.Summary, error) {
+	meter, err := s.meter(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "计量用量失败")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summary := plan.Summarize(spaceID, meter.plan, meter.usage, s.now())
+	for _, item := range summary.Bases {
+		item.BaseName = meter.baseNames[item.BaseID]
+	}
+	return summary, nil
+}
+
+// ==================== 限额检查 ====================
+
+// Check 检查工作空间在某项限额上再增加 adding 是否允许
+// 超过硬限额时返回 ErrPlanLimitExceeded；允许时累加到计量快照
+func (s *PlanService) Check(ctx context.Context, spaceID string, key plan.LimitKey, baseID string, adding int64) (plan.Level, error) {
+	if spaceID == "" {
+		return plan.LevelOK, nil
+	}
+	meter, err := s.meter(ctx, spaceID)
+	if err != nil {
+		planLog.Warn(ctx, "计量用量失败，跳过套餐限额检查",
+			logger.String("space_id", spaceID),
+			logger.String("limit", string(key)),
+			logger.ErrorField(err))
+		return plan.LevelOK, nil
+	}
+
+	s.mu.Lock()
+	limit := meter.plan.Limit(key)
+	used := meter.usage.Value(key, baseID)
+	level := limit.Evaluate(used, adding)
+	if level == plan.LevelExceeded && s.cfg.Enabled {
+		s.mu.Unlock()
+		planLog.Info(ctx, "已达到套餐硬限额",
+			logger.String("space_id", spaceID),
+			logger.String("plan_id", meter.plan.ID),
+			logger.String("limit", string(key)),
+			logger.Int64("used", used),
+			logger.Int64("hard", limit.Hard))
+		return level, pkgerrors.ErrPlanLimitExceeded.WithDetails(map[string]interface{}{
+			"plan_id": meter.plan.ID,
+			"limit":   key,
+			"used":    used,
+			"hard":    limit.Hard,
+			"message": fmt.Sprintf("套餐 %s 的 %s 限额为 %d，请升级套餐", meter.plan.Name, key, limit.Hard),
+		})
+	}
+	s.consume(meter, key, baseID, adding)
+	warn := level != plan.LevelOK && s.firstWarning(spaceID, key)
+	s.mu.Unlock()
+
+	if warn {
+		planLog.Warn(ctx, "已超过套餐软限额",
+			logger.String("space_id", spaceID),
+			logger.String("plan_id", meter.plan.ID),
+			logger.String("limit", string(key)),
+			logger.Int64("used", used+adding),
+			logger.Int64("soft", limit.Soft))
+	}
+	return level, nil
+}
+
+// CheckRecordCreate 检查表所在 Base 是否还能新建 count 条记录
+func (s *PlanService) CheckRecordCreate(ctx context.Context, tableID string, count int) (plan.Level, error) {
+	spaceID, baseID := s.resolveTable(ctx, tableID)
+	return s.Check(ctx, spaceID, plan.LimitRowsPerBase, baseID, int64(count))
+}
+
+// CheckAttachmentUpload 检查表所在空间是否还能上传 size 字节的附件
+func (s *PlanService) CheckAttachmentUpload(ctx context.Context, tableID string, size int64) error {
+	spaceID, _ := s.resolveTable(ctx, tableID)
+	_, err := s.Check(ctx, spaceID, plan.LimitAttachmentBytes, "", size)
+	return err
+}
+
+// CheckAutomationRun 检查 Base 所在空间本月是否还能运行后台任务
+func (s *PlanService) CheckAutomationRun(ctx context.Context, baseID string) (plan.Level, error) {
+	return s.Check(ctx, s.resolveBase(ctx, baseID), plan.LimitAutomationRuns, "", 1)
+}
+
+// CheckAPICall 检查空间本月是否还能调用 API
+func (s *PlanService) CheckAPICall(ctx context.Context, spaceID string) (plan.Level, error) {
+	return s.Check(ctx, spaceID, plan.LimitAPICalls, "", 1)
+}
+
+// consume 将通过检查的用量累加到计量快照（调用方持有锁）
+func (s *PlanService) consume(meter *spaceMeter, key plan.LimitKey, baseID string, adding int64) {
+	switch key {
+	case plan.LimitRowsPerBase:
+		meter.usage.Rows[baseID] += adding
+	case plan.LimitAttachmentBytes:
+		meter.usage.AttachmentBytes += adding
+	case plan.LimitAutomationRuns:
+		meter.usage.AutomationRuns += adding
+	case plan.LimitAPICalls:
+		meter.usage.APICalls += adding
+	}
+}
+
+// firstWarning 每个计费周期每项限额只警告一次（调用方持有锁）
+func (s *PlanService) firstWarning(spaceID string, key plan.LimitKey) bool {
+	period := plan.PeriodStart(s.now())
+	warnKey := spaceID + "|" + string(key)
+	if s.warned[warnKey].Equal(period) {
+		return false
+	}
+	s.warned[warnKey] = period
+	return true
+}
+
+func (s *PlanService) resolveTable(ctx context.Context, tableID string) (spaceID, baseID string) {
+	if tableID == "" {
+		return "", ""
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil || table == nil {
+		return "", ""
+	}
+	return s.resolveBase(ctx, table.BaseID()), table.BaseID()
+}
+
+func (s *PlanService) resolveBase(ctx context.Context, baseID string) string {
+	if baseID == "" {
+		return ""
+	}
+	base, err := s.baseRepo.FindByID(ctx, baseID)
+	if err != nil || base == nil {
+		return ""
+	}
+	return base.SpaceID
+}
+
+// ==================== 计量 ====================
+
+// meter 获取空间的计量快照（过期时重新计量）
+func (s *PlanService) meter(ctx context.Context, spaceID string) (*spaceMeter, error) {
+	now := s.now()
+	s.mu.Lock()
+	meter, ok := s.meters[spaceID]
+	s.mu.Unlock()
+	if ok && now.Before(meter.expiresAt) {
+		return meter, nil
+	}
+
+	p, err := s.planFor(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	u, names, err := s.measure(ctx, spaceID, now)
+	if err != nil {
+		return nil, err
+	}
+	meter = &spaceMeter{plan: p, usage: u, baseNames: names, expiresAt: now.Add(s.cfg.MeterTTL)}
+
+	s.mu.Lock()
+	s.meters[spaceID] = meter
+	s.mu.Unlock()
+	return meter, nil
+}
+
+func (s *PlanService) planFor(ctx context.Context, spaceID string) (*plan.Plan, error) {
+	assignment, err := s.repo.FindAssignment(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment != nil {
+		if p, ok := plan.Find(assignment.PlanID); ok {
+			return p, nil
+		}
+	}
+	p, _ := plan.Find(s.cfg.DefaultPlan)
+	return p, nil
+}
+
+// measure 计量空间当前的用量
+// 附件占用按天汇总，月初当天尚未汇总时取前一天的值
+func (s *PlanService) measure(ctx context.Context, spaceID string, now time.Time) (*plan.Usage, map[string]string, error) {
+	start := plan.PeriodStart(now)
+	today := usage.Day(now)
+	from := start
+	if yesterday := today.AddDate(0, 0, -1); yesterday.Before(from) {
+		from = yesterday
+	}
+	rows, err := s.usageRepo.ListBySpace(ctx, spaceID, from, today)
+	if err != nil {
+		return nil, nil, err
+	}
+	monthly := make([]*usage.DailyUsage, 0, len(rows))
+	for _, row := range rows {
+		if !usage.Day(row.Day).Before(start) {
+			monthly = append(monthly, row)
+		}
+	}
+	totals := usage.BuildReport(spaceID, start, today, monthly).Totals
+
+	u := &plan.Usage{
+		Rows:            make(map[string]int64),
+		AttachmentBytes: usage.BuildReport(spaceID, from, today, rows).Totals[usage.MetricStorageBytes],
+		AutomationRuns:  totals[usage.MetricAutomationRuns],
+		APICalls:        totals[usage.MetricAPICalls],
+		MeteredAt:       now,
+	}
+	if s.usageService != nil {
+		u.APICalls += s.usageService.PendingAPICalls(spaceID)
+	}
+
+	bases, err := s.baseRepo.FindBySpaceID(ctx, spaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make(map[string]string, len(bases))
+	for _, base := range bases {
+		names[base.ID] = base.Name
+		tables, err := s.tableRepo.GetByBaseID(ctx, base.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		var count int64
+		for _, table := range tables {
+			n, err := s.recordRepo.CountByTableID(ctx, table.ID().String())
+			if err != nil {
+				return nil, nil, err
+			}
+			count += n
+		}
+		u.Rows[base.ID] = count
+	}
+	return u, names, nil
+}
@@ -0,0 +1,217 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseEntity "github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/usage"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+type memoryPlanRepo struct {
+	assignments map[string]*plan.Assignment
+}
+
+func (r *memoryPlanRepo) FindAssignment(_ context.Context, spaceID string) (*plan.Assignment, error) {
+	return r.assignments[spaceID], nil
+}
+
+func (r *memoryPlanRepo) SaveAssignment(_ context.Context, assignment *plan.Assignment) error {
+	r.assignments[assignment.SpaceID] = assignment
+	return nil
+}
+
+// stubPlanUsageRepo 返回预置的按天汇总行
+type stubPlanUsageRepo struct {
+	usage.Repository
+	rows []*usage.DailyUsage
+}
+
+func (r *stubPlanUsageRepo) ListBySpace(_ context.Context, spaceID string, from, to time.Time) ([]*usage.DailyUsage, error) {
+	var rows []*usage.DailyUsage
+	for _, row := range r.rows {
+		if row.SpaceID == spaceID && !row.Day.Before(from) && !row.Day.After(to) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+type stubPlanBaseRepo struct {
+	baseRepo.BaseRepository
+	bases []*baseEntity.Base
+}
+
+func (r *stubPlanBaseRepo) FindByID(_ context.Context, id string) (*baseEntity.Base, error) {
+	for _, base := range r.bases {
+		if base.ID == id {
+			return base, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubPlanBaseRepo) FindBySpaceID(_ context.Context, spaceID string) ([]*baseEntity.Base, error) {
+	var bases []*baseEntity.Base
+	for _, base := range r.bases {
+		if base.SpaceID == spaceID {
+			bases = append(bases, base)
+		}
+	}
+	return bases, nil
+}
+
+type stubPlanTableRepo struct {
+	tableRepo.TableRepository
+	tables map[string]*tableEntity.Table
+}
+
+func (r *stubPlanTableRepo) GetByID(_ context.Context, id string) (*tableEntity.Table, error) {
+	return r.tables[id], nil
+}
+
+func (r *stubPlanTableRepo) GetByBaseID(_ context.Context, baseID string) ([]*tableEntity.Table, error) {
+	var tables []*tableEntity.Table
+	for _, table := range r.tables {
+		if table.BaseID() == baseID {
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+// stubPlanRecordRepo 按表返回预置的记录数
+type stubPlanRecordRepo struct {
+	recordRepo.RecordRepository
+	counts map[string]int64
+	calls  int
+}
+
+func (r *stubPlanRecordRepo) CountByTableID(_ context.Context, tableID string) (int64, error) {
+	r.calls++
+	return r.counts[tableID], nil
+}
+
+type planFixture struct {
+	service *PlanService
+	repo    *memoryPlanRepo
+	records *stubPlanRecordRepo
+	tableID string
+	now     time.Time
+}
+
+func newPlanFixture(t *testing.T, enabled bool, rows int64) *planFixture {
+	t.Helper()
+	name, _ := tableVO.NewTableName("订单")
+	table, err := tableEntity.NewTable("bse1", name, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	tableID := table.ID().String()
+
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	fx := &planFixture{
+		repo:    &memoryPlanRepo{assignments: map[string]*plan.Assignment{}},
+		records: &stubPlanRecordRepo{counts: map[string]int64{tableID: rows}},
+		tableID: tableID,
+		now:     now,
+	}
+	usageRepo := &stubPlanUsageRepo{rows: []*usage.DailyUsage{
+		{SpaceID: "spc1", Day: usage.Day(now), Metric: usage.MetricAPICalls, Dimension: "stk1", Value: 600},
+		{SpaceID: "spc1", Day: usage.Day(now.AddDate(0, 0, -3)), Metric: usage.MetricAPICalls, Dimension: "stk1", Value: 399},
+		// 上个月的调用不计入本月
+		{SpaceID: "spc1", Day: time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC), Metric: usage.MetricAPICalls, Dimension: "stk1", Value: 500},
+		{SpaceID: "spc1", BaseID: "bse1", Day: usage.Day(now.AddDate(0, 0, -1)), Metric: usage.MetricStorageBytes, Value: 1 << 20},
+	}}
+	fx.service = NewPlanService(
+		fx.repo,
+		usageRepo,
+		nil,
+		nil,
+		&stubPlanBaseRepo{bases: []*baseEntity.Base{{ID: "bse1", Name: "销售", SpaceID: "spc1"}}},
+		&stubPlanTableRepo{tables: map[string]*tableEntity.Table{tableID: table}},
+		fx.records,
+		nil,
+		config.BillingConfig{Enabled: enabled, MeterTTL: time.Minute},
+	)
+	fx.service.now = func() time.Time { return fx.now }
+	return fx
+}
+
+func TestPlanServiceHardLimit(t *testing.T) {
+	ctx := context.Background()
+	fx := newPlanFixture(t, true, 990)
+
+	level, err := fx.service.CheckRecordCreate(ctx, fx.tableID, 10)
+	if err != nil || level != plan.LevelWarning {
+		t.Fatalf("reaching the hard limit should be allowed with a warning, got %s %v", level, err)
+	}
+	// 计量快照在有效期内累加通过检查的记录数
+	if _, err := fx.service.CheckRecordCreate(ctx, fx.tableID, 1); !pkgerrors.Is(err, pkgerrors.ErrPlanLimitExceeded) {
+		t.Fatalf("exceeding the hard limit should be rejected, got %v", err)
+	}
+	if fx.records.calls != 1 {
+		t.Errorf("rows should be metered once within the TTL, got %d", fx.records.calls)
+	}
+
+	if level, err := fx.service.CheckAPICall(ctx, "spc1"); err != nil || level != plan.LevelWarning {
+		t.Fatalf("1000th api call should be allowed, got %s %v", level, err)
+	}
+	if _, err := fx.service.CheckAPICall(ctx, "spc1"); !pkgerrors.Is(err, pkgerrors.ErrPlanLimitExceeded) {
+		t.Fatalf("api calls over the hard limit should be rejected, got %v", err)
+	}
+}
+
+func TestPlanServiceSoftOnlyWhenDisabled(t *testing.T) {
+	ctx := context.Background()
+	fx := newPlanFixture(t, false, 1000)
+
+	level, err := fx.service.CheckRecordCreate(ctx, fx.tableID, 5)
+	if err != nil || level != plan.LevelExceeded {
+		t.Fatalf("disabled enforcement should only report the level, got %s %v", level, err)
+	}
+}
+
+func TestPlanServiceAssignmentAndSummary(t *testing.T) {
+	ctx := context.Background()
+	fx := newPlanFixture(t, true, 5000)
+	fx.repo.assignments["spc1"] = &plan.Assignment{SpaceID: "spc1", PlanID: "team"}
+
+	if _, err := fx.service.CheckRecordCreate(ctx, fx.tableID, 100); err != nil {
+		t.Fatalf("team plan should allow more rows: %v", err)
+	}
+
+	summary, err := fx.service.summary(ctx, "spc1")
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if summary.Plan.ID != "team" || len(summary.Bases) != 1 || summary.Bases[0].BaseName != "销售" || summary.Bases[0].Rows != 5100 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	for _, status := range summary.Limits {
+		switch status.Key {
+		case plan.LimitAPICalls:
+			if status.Used != 999 {
+				t.Errorf("only this month's api calls should count, got %d", status.Used)
+			}
+		case plan.LimitAttachmentBytes:
+			if status.Used != 1<<20 {
+				t.Errorf("storage should use the latest rollup, got %d", status.Used)
+			}
+		}
+	}
+
+	// 未知表、未知空间不做限制
+	if _, err := fx.service.CheckRecordCreate(ctx, "tbl_missing", 1); err != nil {
+		t.Errorf("unresolved tables should not be limited: %v", err)
+	}
+}
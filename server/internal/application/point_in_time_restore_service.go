@@ -0,0 +1,372 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// 按时间点恢复时跳过记录的原因
+const (
+	restoreSkipNotExisted = "notExisted" // 该时间点记录尚未创建
+	restoreSkipDeleted    = "deleted"    // 记录当前已删除（请从回收站恢复）
+)
+
+// PointInTimeRestoreRequest 按时间点恢复请求：recordId 与 fieldId 二选一
+type PointInTimeRestoreRequest struct {
+	AsOf      time.Time `json:"asOf" binding:"required"`
+	RecordID  string    `json:"recordId"`  // 恢复单条记录
+	FieldIDs  []string  `json:"fieldIds"`  // 单条记录时只恢复这些字段，为空时恢复全部可写字段
+	FieldID   string    `json:"fieldId"`   // 恢复一列
+	RecordIDs []string  `json:"recordIds"` // 恢复一列时只恢复这些记录，为空时恢复该时间点之后修改过的全部记录
+}
+
+// PointInTimeCellDiff 一个单元格的当前值与时间点上的值
+type PointInTimeCellDiff struct {
+	RecordID  string      `json:"recordId"`
+	FieldID   string      `json:"fieldId"`
+	FieldName string      `json:"fieldName"`
+	Current   interface{} `json:"current"`
+	AsOf      interface{} `json:"asOf"` // 恢复后的值
+}
+
+// PointInTimeSkip 跳过的记录
+type PointInTimeSkip struct {
+	RecordID string `json:"recordId"`
+	Reason   string `json:"reason"` // notExisted | deleted
+}
+
+// PointInTimeFailure 恢复失败的记录
+type PointInTimeFailure struct {
+	RecordID string `json:"recordId"`
+	Error    string `json:"error"`
+}
+
+// PointInTimeRestorePreview 恢复预览：将要写入的单元格差异
+type PointInTimeRestorePreview struct {
+	AsOf    time.Time              `json:"asOf"`
+	Records int                    `json:"records"` // 需要恢复的记录数
+	Changes []*PointInTimeCellDiff `json:"changes"`
+	Skipped []PointInTimeSkip      `json:"skipped,omitempty"`
+}
+
+// PointInTimeRestoreResult 恢复结果
+type PointInTimeRestoreResult struct {
+	AsOf     time.Time            `json:"asOf"`
+	Restored []string             `json:"restored"`
+	Failed   []PointInTimeFailure `json:"failed,omitempty"`
+	Skipped  []PointInTimeSkip    `json:"skipped,omitempty"`
+}
+
+// restoreUpdate 一条记录需要写回的值
+type restoreUpdate struct {
+	recordID string
+	version  int64 // 生成预览时的记录版本，写入时做乐观锁检查
+	data     map[string]interface{}
+	diffs    []*PointInTimeCellDiff
+}
+
+// restorePlan 按时间点恢复的计划
+type restorePlan struct {
+	asOf    time.Time
+	updates []*restoreUpdate
+	skipped []PointInTimeSkip
+}
+
+// PointInTimeRestoreService 按时间点恢复服务 ✨
+// 基于记录变更日志，将单条记录（或一列在多条记录上的值）恢复到某一时间点的状态，无需恢复整个 Base：
+// 先预览将要写入的差异，确认后按记录逐条写入。写入走普通的记录更新流程（记录锁、状态流、重算与变更日志），
+// 并以预览时的版本做乐观锁检查；计算字段与系统字段不恢复，时间点之后创建或当前已删除的记录会被跳过
+type PointInTimeRestoreService struct {
+	snapshotReader    recordRepo.SnapshotReader
+	recordService     *RecordService
+	fieldRepo         fieldRepo.FieldRepository
+	permissionService *PermissionServiceV2
+	cfg               config.RecordSnapshotConfig
+	now               func() time.Time
+}
+
+// NewPointInTimeRestoreService 创建按时间点恢复服务
+func NewPointInTimeRestoreService(
+	snapshotReader recordRepo.SnapshotReader,
+	recordService *RecordService,
+	fieldRepo fieldRepo.FieldRepository,
+	permissionService *PermissionServiceV2,
+	cfg config.RecordSnapshotConfig,
+) *PointInTimeRestoreService {
+	if cfg.MaxChanges <= 0 {
+		cfg.MaxChanges = 50000
+	}
+	if cfg.MaxRestoreRecords <= 0 {
+		cfg.MaxRestoreRecords = 1000
+	}
+	return &PointInTimeRestoreService{
+		snapshotReader:    snapshotReader,
+		recordService:     recordService,
+		fieldRepo:         fieldRepo,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// Preview 预览恢复将要写入的差异（值按当前角色的脱敏策略处理）
+func (s *PointInTimeRestoreService) Preview(ctx context.Context, userID, tableID string, req PointInTimeRestoreRequest) (*PointInTimeRestorePreview, error) {
+	plan, err := s.plan(ctx, userID, tableID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &PointInTimeRestorePreview{
+		AsOf:    plan.asOf,
+		Records: len(plan.updates),
+		Changes: []*PointInTimeCellDiff{},
+		Skipped: plan.skipped,
+	}
+	values := make([]*dto.RecordResponse, 0, len(plan.updates)*2)
+	for _, update := range plan.updates {
+		current := &dto.RecordResponse{ID: update.recordID, TableID: tableID, Data: map[string]interface{}{}}
+		asOf := &dto.RecordResponse{ID: update.recordID, TableID: tableID, Data: map[string]interface{}{}}
+		for _, diff := range update.diffs {
+			current.Data[diff.FieldID] = diff.Current
+			asOf.Data[diff.FieldID] = diff.AsOf
+		}
+		values = append(values, current, asOf)
+	}
+	s.recordService.maskResponses(ctx, tableID, values...)
+
+	for i, update := range plan.updates {
+		for _, diff := range update.diffs {
+			preview.Changes = append(preview.Changes, &PointInTimeCellDiff{
+				RecordID:  diff.RecordID,
+				FieldID:   diff.FieldID,
+				FieldName: diff.FieldName,
+				Current:   values[2*i].Data[diff.FieldID],
+				AsOf:      values[2*i+1].Data[diff.FieldID],
+			})
+		}
+	}
+	return preview, nil
+}
+
+// Restore 按时间点恢复：重新计算差异后逐条写入，单条记录失败（如版本冲突、记录被锁定）不影响其他记录
+func (s *PointInTimeRestoreService) Restore(ctx context.Context, userID, tableID string, req PointInTimeRestoreRequest) (*PointInTimeRestoreResult, error) {
+	plan, err := s.plan(ctx, userID, tableID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PointInTimeRestoreResult{AsOf: plan.asOf, Restored: []string{}, Skipped: plan.skipped}
+	for _, update := range plan.updates {
+		version := int(update.version)
+		_, err := s.recordService.UpdateRecord(ctx, tableID, update.recordID, dto.UpdateRecordRequest{
+			Data:    update.data,
+			Version: &version,
+		}, userID)
+		if err != nil {
+			result.Failed = append(result.Failed, PointInTimeFailure{RecordID: update.recordID, Error: err.Error()})
+			continue
+		}
+		result.Restored = append(result.Restored, update.recordID)
+	}
+	return result, nil
+}
+
+// plan 校验请求并计算每条记录需要写回的值
+func (s *PointInTimeRestoreService) plan(ctx context.Context, userID, tableID string, req PointInTimeRestoreRequest) (*restorePlan, error) {
+	if s.snapshotReader == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("当前服务未启用变更日志")
+	}
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限修改该表的记录")
+	}
+	if (req.RecordID == "") == (req.FieldID == "") {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("recordId 与 fieldId 必须且只能提供一个")
+	}
+	if req.FieldID != "" && len(req.RecordIDs) > s.cfg.MaxRestoreRecords {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("单次最多恢复 %d 条记录", s.cfg.MaxRestoreRecords))
+	}
+	asOf, err := resolveSnapshotAsOf(&req.AsOf, s.now(), s.cfg.Retention)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.restoreFields(ctx, tableID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordIDs := req.RecordIDs
+	if req.RecordID != "" {
+		recordIDs = []string{req.RecordID}
+	}
+	states, err := s.snapshotReader.StatesAsOf(ctx, tableID, recordIDs, asOf, s.cfg.MaxChanges)
+	if err != nil {
+		if errors.Is(err, record.ErrSnapshotTooOld) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("该时间点之后的变更过多，请使用更近的时间点或指定记录")
+		}
+		return nil, pkgerrors.Database(err, "读取记录变更日志失败")
+	}
+
+	ids := make([]valueobject.RecordID, 0, len(states))
+	for _, state := range states {
+		if state.Record != nil {
+			ids = append(ids, valueobject.NewRecordID(state.RecordID))
+		}
+	}
+	current := make(map[string]*entity.Record, len(ids))
+	if len(ids) > 0 {
+		records, err := s.recordService.recordRepo.FindByIDs(ctx, tableID, ids)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找记录失败")
+		}
+		for _, rec := range records {
+			current[rec.ID().String()] = rec
+		}
+	}
+
+	plan := &restorePlan{asOf: asOf}
+	for _, state := range states {
+		if state.Record == nil {
+			plan.skipped = append(plan.skipped, PointInTimeSkip{RecordID: state.RecordID, Reason: restoreSkipNotExisted})
+			continue
+		}
+		rec, ok := current[state.RecordID]
+		if !ok {
+			plan.skipped = append(plan.skipped, PointInTimeSkip{RecordID: state.RecordID, Reason: restoreSkipDeleted})
+			continue
+		}
+		if update := diffRecordAsOf(rec, state.Record, fields); update != nil {
+			plan.updates = append(plan.updates, update)
+		}
+	}
+	if len(plan.updates) > s.cfg.MaxRestoreRecords {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+			"message": "需要恢复的记录过多，请通过 recordIds 分批恢复",
+			"records": len(plan.updates),
+			"max":     s.cfg.MaxRestoreRecords,
+		})
+	}
+	return plan, nil
+}
+
+// restoreFields 本次恢复的字段：恢复一列时为该字段，恢复单条记录时为指定字段或全部可写字段
+func (s *PointInTimeRestoreService) restoreFields(ctx context.Context, tableID string, req PointInTimeRestoreRequest) ([]*fieldEntity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	wanted := req.FieldIDs
+	if req.FieldID != "" {
+		wanted = []string{req.FieldID}
+	}
+	if len(wanted) == 0 {
+		restorable := make([]*fieldEntity.Field, 0, len(fields))
+		for _, field := range fields {
+			if templateWritable(field) {
+				restorable = append(restorable, field)
+			}
+		}
+		return restorable, nil
+	}
+
+	restorable := make([]*fieldEntity.Field, 0, len(wanted))
+	for _, id := range wanted {
+		field, ok := byID[id]
+		if !ok {
+			return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在: " + id)
+		}
+		if !templateWritable(field) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("计算字段与系统字段不能恢复: " + field.Name().String())
+		}
+		restorable = append(restorable, field)
+	}
+	return restorable, nil
+}
+
+// diffRecordAsOf 比较当前记录与时间点上的记录，返回需要写回的值（没有差异时返回 nil）
+func diffRecordAsOf(current, asOf *entity.Record, fields []*fieldEntity.Field) *restoreUpdate {
+	currentData := current.Data().ToMap()
+	asOfData := asOf.Data().ToMap()
+	update := &restoreUpdate{
+		recordID: current.ID().String(),
+		version:  current.Version().Value(),
+		data:     map[string]interface{}{},
+	}
+	for _, field := range fields {
+		fieldID := field.ID().String()
+		before, now := asOfData[fieldID], currentData[fieldID]
+		if sameCellValue(before, now) {
+			continue
+		}
+		update.data[fieldID] = before
+		update.diffs = append(update.diffs, &PointInTimeCellDiff{
+			RecordID:  update.recordID,
+			FieldID:   fieldID,
+			FieldName: field.Name().String(),
+			Current:   now,
+			AsOf:      before,
+		})
+	}
+	if len(update.data) == 0 {
+		return nil
+	}
+	return update
+}
+
+// sameCellValue 两个单元格值是否相同（空值、空字符串与空数组视为相同，其余按 JSON 比较）
+func sameCellValue(a, b interface{}) bool {
+	if isEmptyCellValue(a) || isEmptyCellValue(b) {
+		return isEmptyCellValue(a) && isEmptyCellValue(b)
+	}
+	left, errA := json.Marshal(normalizeCellTime(a))
+	right, errB := json.Marshal(normalizeCellTime(b))
+	if errA != nil || errB != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
+
+// normalizeCellTime 时间值统一为 UTC 文本（变更日志中的时间以 RFC3339 文本保存）
+func normalizeCellTime(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return v
+}
+
+func isEmptyCellValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []interface{}:
+		return len(val) == 0
+	case []string:
+		return len(val) == 0
+	}
+	return false
+}
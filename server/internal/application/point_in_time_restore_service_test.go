@@ -0,0 +1,84 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+)
+
+func newRestoreTestRecord(t *testing.T, data map[string]interface{}) *entity.Record {
+	t.Helper()
+	recordData, err := valueobject.NewRecordData(data)
+	if err != nil {
+		t.Fatalf("创建记录数据失败: %v", err)
+	}
+	now := time.Now()
+	return entity.ReconstructRecord(valueobject.NewRecordID("rec1"), "tbl1", recordData, valueobject.InitialVersion(), "usr1", "usr1", now, now, nil)
+}
+
+func TestDiffRecordAsOf(t *testing.T) {
+	title := newTemplateTestField(t, "标题", fieldVO.TypeText)
+	amount := newTemplateTestField(t, "金额", fieldVO.TypeNumber)
+	tags := newTemplateTestField(t, "标签", fieldVO.TypeMultipleSelect)
+	due := newTemplateTestField(t, "截止时间", fieldVO.TypeDateTime)
+	fields := []*fieldEntity.Field{title, amount, tags, due}
+
+	dueAt := time.Date(2026, 3, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	asOf := newRestoreTestRecord(t, map[string]interface{}{
+		title.ID().String():  "原标题",
+		amount.ID().String(): 100,
+		due.ID().String():    "2026-03-01T08:00:00Z",
+	})
+	current := newRestoreTestRecord(t, map[string]interface{}{
+		title.ID().String():  "新标题",
+		amount.ID().String(): 100,
+		tags.ID().String():   []interface{}{},
+		due.ID().String():    dueAt,
+	})
+
+	update := diffRecordAsOf(current, asOf, fields)
+	if update == nil || len(update.diffs) != 1 {
+		t.Fatalf("差异 = %+v", update)
+	}
+	diff := update.diffs[0]
+	if diff.FieldID != title.ID().String() || diff.Current != "新标题" || diff.AsOf != "原标题" {
+		t.Errorf("标题差异 = %+v", diff)
+	}
+	if update.data[title.ID().String()] != "原标题" || len(update.data) != 1 {
+		t.Errorf("写回的值 = %v", update.data)
+	}
+
+	if diffRecordAsOf(current, current, fields) != nil {
+		t.Error("相同的记录不应有差异")
+	}
+
+	// 时间点之后新填写的单元格恢复为空
+	tagged := newRestoreTestRecord(t, map[string]interface{}{tags.ID().String(): []interface{}{"紧急"}})
+	cleared := diffRecordAsOf(tagged, asOf, []*fieldEntity.Field{tags})
+	if cleared == nil || cleared.data[tags.ID().String()] != nil {
+		t.Errorf("清空差异 = %+v", cleared)
+	}
+}
+
+func TestSameCellValue(t *testing.T) {
+	cases := []struct {
+		a, b interface{}
+		same bool
+	}{
+		{nil, "", true},
+		{nil, []interface{}{}, true},
+		{"a", nil, false},
+		{int64(3), float64(3), true},
+		{[]interface{}{"x", "y"}, []interface{}{"y", "x"}, false},
+		{time.Date(2026, 3, 1, 16, 0, 0, 0, time.FixedZone("CST", 8*3600)), "2026-03-01T08:00:00Z", true},
+	}
+	for i, c := range cases {
+		if got := sameCellValue(c.a, c.b); got != c.same {
+			t.Errorf("用例 %d: sameCellValue(%v, %v) = %v", i, c.a, c.b, got)
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/printjob"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/pdf"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
+)
+
+var printLog = logger.Named("print")
+
+// printLoadBatch 打印视图时每批读取的记录数
+const printLoadBatch = 500
+
+// printDefaultColumnWidth 视图未设置列宽时的默认列宽（像素）
+const printDefaultColumnWidth = 150
+
+// printRecordReader 读取记录并计算展示文本（由 RecordService 实现，按 context 中的用户脱敏）
+type printRecordReader interface {
+	GetRecord(ctx context.Context, tableID, recordID string) (*dto.RecordResponse, error)
+	FormatRecords(ctx context.Context, tableID string, records []*dto.RecordResponse, opts RecordFormatOptions) error
+}
+
+// CreatePrintJobRequest 创建打印任务请求：提供 recordId 时打印记录详情页，否则打印视图
+type CreatePrintJobRequest struct {
+	TableID     string `json:"tableId" binding:"required"`
+	ViewID      string `json:"viewId"`      // 打印视图时必填；打印记录时可选，决定字段的顺序与可见性
+	RecordID    string `json:"recordId"`    // 要打印的记录
+	Paper       string `json:"paper"`       // a4（默认）| letter
+	Orientation string `json:"orientation"` // portrait | landscape，默认记录纵向、视图横向
+}
+
+// PrintJobDetail 打印任务及下载链接（完成后提供）
+type PrintJobDetail struct {
+	*printjob.Job
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// PrintService 记录与视图的 PDF 打印服务 ✨
+// 创建任务后由后台渲染：记录打印为标签/值两列的详情页，视图打印为按视图过滤与排序的分页表格
+// （列宽按视图列宽等比缩放，放不下的列与超过上限的记录不打印并在文末注明）。
+// 渲染以创建者的身份进行：执行时重新校验权限，脱敏策略按其角色生效，日期按创建时的时区与语言格式化。
+// 生成的 PDF 写入对象存储，只有创建者可以获取下载链接；自动化中的"生成 PDF"节点通过 Generate 同步生成
+type PrintService struct {
+	repo              printjob.Repository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         fieldRepo.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           printRecordReader
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	storage           attachment.Storage
+	cfg               config.PrintConfig
+	now               func() time.Time
+}
+
+// NewPrintService 创建打印服务
+func NewPrintService(
+	repo printjob.Repository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	recordRepo recordRepo.RecordRepository,
+	records printRecordReader,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	storage attachment.Storage,
+	cfg config.PrintConfig,
+) *PrintService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 10 * time.Minute
+	}
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = time.Hour
+	}
+	if cfg.History <= 0 {
+		cfg.History = 20
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = 2000
+	}
+	return &PrintService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepo,
+		records:           records,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		storage:           storage,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// CreateJob 创建打印任务（由后台在下一个轮询周期执行）
+func (s *PrintService) CreateJob(ctx context.Context, userID string, req CreatePrintJobRequest) (*printjob.Job, error) {
+	job, err := s.newJob(ctx, userID, req, printjob.SourceAPI)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存打印任务失败")
+	}
+	return job, nil
+}
+
+// Generate 立即生成 PDF 并返回带下载链接的任务（供自动化动作使用，生成失败的任务同样会保存）
+func (s *PrintService) Generate(ctx context.Context, userID string, req CreatePrintJobRequest) (*PrintJobDetail, error) {
+	job, err := s.newJob(ctx, userID, req, printjob.SourceAutomation)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = printjob.StatusRunning
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存打印任务失败")
+	}
+	s.runJob(ctx, job)
+	if job.Status != printjob.StatusCompleted {
+		return nil, fmt.Errorf("生成 PDF 失败: %s", job.Error)
+	}
+	return s.detail(ctx, job)
+}
+
+// ListJobs 列出用户在表中最近创建的打印任务
+func (s *PrintService) ListJobs(ctx context.Context, userID, tableID string) ([]*printjob.Job, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表格的权限")
+	}
+	jobs, err := s.repo.ListByTable(ctx, tableID, userID, s.cfg.History)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取打印任务失败")
+	}
+	return jobs, nil
+}
+
+// GetJob 获取打印任务，已完成的任务附带下载链接（PDF 按创建者的权限生成，只有创建者可以获取）
+func (s *PrintService) GetJob(ctx context.Context, userID, jobID string) (*PrintJobDetail, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取打印任务失败")
+	}
+	if job == nil || job.CreatedBy != userID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("打印任务不存在")
+	}
+	return s.detail(ctx, job)
+}
+
+// detail 附带下载链接的任务详情
+func (s *PrintService) detail(ctx context.Context, job *printjob.Job) (*PrintJobDetail, error) {
+	detail := &PrintJobDetail{Job: job}
+	if job.Status != printjob.StatusCompleted {
+		return detail, nil
+	}
+	url, err := s.storage.GetURL(ctx, job.FilePath(), s.cfg.URLExpiry)
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("生成下载链接失败")
+	}
+	detail.DownloadURL = url
+	return detail, nil
+}
+
+// newJob 按请求创建任务并校验访问权限，记录请求用户的时区与语言
+func (s *PrintService) newJob(ctx context.Context, userID string, req CreatePrintJobRequest, source string) (*printjob.Job, error) {
+	var job *printjob.Job
+	if req.RecordID != "" {
+		job = printjob.NewRecordJob(req.TableID, req.ViewID, req.RecordID, userID, s.now())
+	} else {
+		job = printjob.NewViewJob(req.TableID, req.ViewID, userID, s.now())
+	}
+	if req.Paper != "" {
+		job.Paper = strings.ToLower(req.Paper)
+	}
+	if req.Orientation != "" {
+		job.Orientation = strings.ToLower(req.Orientation)
+	}
+	job.Source = source
+	if locale, ok := userlocale.FromContext(ctx); ok {
+		job.TimeZone, job.Locale = locale.TimeZone, locale.Language
+	}
+	if err := job.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if _, err := s.checkAccess(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// checkAccess 校验创建者能否查看打印目标（创建与执行时各校验一次），返回打印使用的视图
+func (s *PrintService) checkAccess(ctx context.Context, job *printjob.Job) (*viewEntity.View, error) {
+	if !s.permissionService.CanAccessRecord(ctx, job.CreatedBy, job.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表格的权限")
+	}
+	var view *viewEntity.View
+	if job.ViewID != "" {
+		var err error
+		if view, err = s.viewRepo.FindByID(ctx, job.ViewID); err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.IsDeleted() || view.TableID() != job.TableID {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+		if !s.permissionService.CanReadView(ctx, job.CreatedBy, job.ViewID) {
+			return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+		}
+	}
+	if job.RecordID != "" {
+		if _, err := s.records.GetRecord(ctx, job.TableID, job.RecordID); err != nil {
+			return nil, err
+		}
+	}
+	return view, nil
+}
+
+// Start 启动后台打印
+func (s *PrintService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runPending(ctx)
+		}
+	}()
+}
+
+// runPending 领取并执行待执行或已中断的任务
+func (s *PrintService) runPending(ctx context.Context) {
+	now := s.now()
+	jobs, err := s.repo.Claim(ctx, now, now.Add(-s.cfg.LeaseDuration), 4)
+	if err != nil {
+		printLog.Warn(ctx, "领取打印任务失败", logger.ErrorField(err))
+		return
+	}
+	for _, job := range jobs {
+		s.runJob(ctx, job)
+	}
+}
+
+// runJob 渲染并保存结果
+func (s *PrintService) runJob(ctx context.Context, job *printjob.Job) {
+	output, err := s.render(ctx, job)
+	job.Finish(output, err, s.now())
+	if saveErr := s.repo.Save(ctx, job); saveErr != nil {
+		printLog.Warn(ctx, "保存打印任务失败", logger.String("job_id", job.ID), logger.ErrorField(saveErr))
+	}
+	if err != nil {
+		printLog.Warn(ctx, "打印失败",
+			logger.String("job_id", job.ID),
+			logger.String("table_id", job.TableID),
+			logger.ErrorField(err))
+	}
+}
+
+// render 以创建者的身份读取数据并排版，上传生成的 PDF
+func (s *PrintService) render(ctx context.Context, job *printjob.Job) (*printjob.Output, error) {
+	ctx = authctx.WithUser(ctx, job.CreatedBy)
+	ctx = userlocale.WithLocale(ctx, userlocale.Locale{TimeZone: job.TimeZone, Language: job.Locale})
+
+	view, err := s.checkAccess(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if job.TargetType == printjob.TargetView && view == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	table, err := s.tableRepo.GetByID(ctx, job.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, job.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+
+	size := pdf.A4
+	if job.Paper == printjob.PaperLetter {
+		size = pdf.Letter
+	}
+	if job.Orientation == printjob.OrientationLandscape {
+		size = size.Landscape()
+	}
+	doc := pdf.New(size)
+	printedAt := "打印于 " + s.now().In(userlocale.Location(ctx)).Format("2006-01-02 15:04")
+
+	output := &printjob.Output{}
+	var title string
+	if job.TargetType == printjob.TargetView {
+		title, err = s.renderView(ctx, doc, table, view, fields, printedAt, output)
+	} else {
+		title, err = s.renderRecord(ctx, doc, job, table, view, fields, printedAt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.SetInfo(title, s.now())
+	pdf.AddFooters(doc, title)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("write pdf: %w", err)
+	}
+	if err := s.storage.Upload(ctx, job.FilePath(), bytes.NewReader(buf.Bytes()), int64(buf.Len()), "application/pdf"); err != nil {
+		return nil, fmt.Errorf("upload pdf: %w", err)
+	}
+	output.FileName = printjob.FileName(title, job.ID)
+	output.Size = int64(buf.Len())
+	output.Pages = len(doc.Pages())
+	return output, nil
+}
+
+// renderRecord 记录详情页：主字段为标题，其余字段为标签/值两列
+func (s *PrintService) renderRecord(ctx context.Context, doc *pdf.Document, job *printjob.Job, table *tableEntity.Table, view *viewEntity.View, fields []*fieldEntity.Field, printedAt string) (string, error) {
+	record, err := s.records.GetRecord(ctx, job.TableID, job.RecordID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.records.FormatRecords(ctx, job.TableID, []*dto.RecordResponse{record}, RecordFormatOptions{}); err != nil {
+		return "", err
+	}
+
+	title := record.ID
+	var rows []pdf.DetailRow
+	for _, field := range printFields(view, fields) {
+		value := printValue(field, record)
+		if field.IsPrimary() {
+			if value != "" {
+				title = value
+			}
+			continue
+		}
+		rows = append(rows, pdf.DetailRow{Label: field.Name().String(), Value: value})
+	}
+
+	subtitle := []string{table.Name().String()}
+	if view != nil {
+		subtitle = append(subtitle, view.Name())
+	}
+	pdf.RenderDetail(doc, pdf.Header{Title: title, Subtitle: strings.Join(append(subtitle, printedAt), " · ")}, rows)
+	return title, nil
+}
+
+// renderView 视图分页表格：按视图的过滤与排序读取记录（最多 MaxRows 条），只打印可见字段
+func (s *PrintService) renderView(ctx context.Context, doc *pdf.Document, table *tableEntity.Table, view *viewEntity.View, fields []*fieldEntity.Field, printedAt string, output *printjob.Output) (string, error) {
+	ids, err := s.viewRecordIDs(ctx, table.BaseID(), view, fields)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) > s.cfg.MaxRows {
+		ids = ids[:s.cfg.MaxRows]
+		output.Truncated = true
+	}
+
+	visible := printFields(view, fields)
+	widths := make([]float64, len(visible))
+	for i, field := range visible {
+		width := printDefaultColumnWidth
+		if column := view.ColumnMeta().GetColumn(field.ID().String()); column != nil && column.Width > 0 {
+			width = column.Width
+		}
+		widths[i] = float64(width) * 0.75 // 像素 → 点
+	}
+	fitted := pdf.FitColumns(widths, doc.ContentWidth())
+	columns := make([]pdf.TableColumn, len(fitted))
+	for i, width := range fitted {
+		columns[i] = pdf.TableColumn{Title: visible[i].Name().String(), Width: width}
+	}
+
+	rows := make([][]string, 0, len(ids))
+	for start := 0; start < len(ids); start += printLoadBatch {
+		end := min(start+printLoadBatch, len(ids))
+		records, err := s.loadRecords(ctx, view.TableID(), ids[start:end])
+		if err != nil {
+			return "", err
+		}
+		for _, record := range records {
+			row := make([]string, len(columns))
+			for i := range columns {
+				row[i] = printValue(visible[i], record)
+			}
+			rows = append(rows, row)
+		}
+	}
+	output.Rows = len(rows)
+
+	var notes []string
+	if output.Truncated {
+		notes = append(notes, fmt.Sprintf("记录超过 %d 条，只打印了前 %d 条", s.cfg.MaxRows, s.cfg.MaxRows))
+	}
+	if omitted := len(visible) - len(columns); omitted > 0 {
+		notes = append(notes, fmt.Sprintf("另有 %d 个字段因页面宽度未打印", omitted))
+	}
+	subtitle := fmt.Sprintf("%s · 共 %d 条记录 · %s", table.Name().String(), len(rows), printedAt)
+	pdf.RenderTable(doc, pdf.Header{Title: view.Name(), Subtitle: subtitle}, columns, rows, strings.Join(notes, "；"))
+	return view.Name(), nil
+}
+
+// viewRecordIDs 按视图的过滤与排序查询记录ID（多取一条用于判断是否超过上限）
+func (s *PrintService) viewRecordIDs(ctx context.Context, baseID string, view *viewEntity.View, fields []*fieldEntity.Field) ([]string, error) {
+	byID := fieldIndex(fields)
+	condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+	if err != nil {
+		return nil, err
+	}
+	query := s.dataDB(ctx, baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(baseID, view.TableID()))
+	if condition != nil {
+		query = query.Where(condition)
+	}
+	for _, order := range embedSortColumns(view.Sort(), byID) {
+		query = query.Order(order)
+	}
+	var ids []string
+	if err := query.Limit(s.cfg.MaxRows+1).Pluck("__id", &ids).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	return ids, nil
+}
+
+// loadRecords 按给定顺序加载记录，脱敏后计算展示文本
+func (s *PrintService) loadRecords(ctx context.Context, tableID string, ids []string) ([]*dto.RecordResponse, error) {
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	byID := make(map[string]*dto.RecordResponse, len(entities))
+	for _, record := range entities {
+		byID[record.ID().String()] = dto.FromRecordEntity(record)
+	}
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := byID[id]; ok {
+			records = append(records, record)
+		}
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, records...)
+	}
+	if err := s.records.FormatRecords(ctx, tableID, records, RecordFormatOptions{}); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// printFields 打印的字段：有视图时为视图中可见的字段（按视图的列顺序，未出现在列配置中的字段在后），
+// 否则为全部字段（按字段顺序）
+func printFields(view *viewEntity.View, fields []*fieldEntity.Field) []*fieldEntity.Field {
+	sorted := make([]*fieldEntity.Field, 0, len(fields))
+	var hidden map[string]bool
+	if view != nil {
+		hidden = embedHiddenFieldIDs(view, fields)
+	}
+	for _, field := range fields {
+		if !hidden[field.ID().String()] {
+			sorted = append(sorted, field)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Order() < sorted[j].Order() })
+	if view == nil {
+		return sorted
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ci := view.ColumnMeta().GetColumn(sorted[i].ID().String())
+		cj := view.ColumnMeta().GetColumn(sorted[j].ID().String())
+		if ci == nil || cj == nil {
+			return ci != nil && cj == nil
+		}
+		return ci.Order < cj.Order
+	})
+	return sorted
+}
+
+// printValue 单元格的打印文本：长文本与富文本保留换行（富文本转为 Markdown），记录提及显示为 @标题，
+// 其他字段使用格式化后的展示文本
+func printValue(field *fieldEntity.Field, record *dto.RecordResponse) string {
+	fieldID := field.ID().String()
+	switch field.Type().String() {
+	case fieldVO.TypeLongText:
+		if text, ok := record.Data[fieldID].(string); ok {
+			return richtext.ReplaceRecordMentions(text)
+		}
+	case fieldVO.TypeRichText:
+		if text, ok := record.Data[fieldID].(string); ok {
+			return richtext.ReplaceRecordMentions(richtext.HTMLToMarkdown(text))
+		}
+	}
+	return record.Formatted[fieldID]
+}
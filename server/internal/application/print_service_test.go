@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+func TestPrintFields(t *testing.T) {
+	name := newTemplateTestField(t, "Name", fieldVO.TypeSingleLineText)
+	amount := newTemplateTestField(t, "Amount", fieldVO.TypeNumber)
+	notes := newTemplateTestField(t, "Notes", fieldVO.TypeLongText)
+	secret := newTemplateTestField(t, "Secret", fieldVO.TypeSingleLineText)
+	fields := []*fieldEntity.Field{name, amount, notes, secret}
+
+	if got := printFields(nil, fields); len(got) != 4 {
+		t.Fatalf("没有视图时应打印全部字段，得到 %d 个", len(got))
+	}
+
+	vt, _ := viewVO.NewViewType("grid")
+	view, err := viewEntity.NewView("tbl1", "待开票", vt, "usr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := view.UpdateColumnMeta(&viewVO.ColumnMetaList{Columns: []viewVO.ColumnMeta{
+		{FieldID: amount.ID().String(), Visible: true, Order: 0},
+		{FieldID: name.ID().String(), Visible: true, Order: 1},
+		{FieldID: secret.ID().String(), Visible: false, Order: 2},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, field := range printFields(view, fields) {
+		names = append(names, field.Name().String())
+	}
+	// 按视图列顺序，隐藏字段不打印，未出现在列配置中的字段在后
+	if strings.Join(names, ",") != "Amount,Name,Notes" {
+		t.Errorf("打印字段错误: %v", names)
+	}
+}
+
+func TestPrintValue(t *testing.T) {
+	notes := newTemplateTestField(t, "Notes", fieldVO.TypeLongText)
+	body := newTemplateTestField(t, "Body", fieldVO.TypeRichText)
+	amount := newTemplateTestField(t, "Amount", fieldVO.TypeNumber)
+	record := &dto.RecordResponse{
+		ID: "rec1",
+		Data: map[string]interface{}{
+			notes.ID().String():  "第一行\n见 " + richtext.RecordMentionToken("tbl2", "rec2", "客户A"),
+			body.ID().String():   "<p><strong>加急</strong></p>",
+			amount.ID().String(): 1200.5,
+		},
+		Formatted: map[string]string{amount.ID().String(): "1,200.50"},
+	}
+	if got := printValue(notes, record); got != "第一行\n见 @客户A" {
+		t.Errorf("长文本应保留换行并显示提及标题，得到 %q", got)
+	}
+	if got := printValue(body, record); got != "**加急**" {
+		t.Errorf("富文本应转为 Markdown，得到 %q", got)
+	}
+	if got := printValue(amount, record); got != "1,200.50" {
+		t.Errorf("其他字段应使用展示文本，得到 %q", got)
+	}
+}
+
+func TestPrintNodeRequiresRecord(t *testing.T) {
+	raw := `{"type":"generatePdf","tableId":"tbl1"}`
+	action, ok := parsePrintNode(&models.WorkflowNode{Action: &raw})
+	if !ok || action.TableID != "tbl1" {
+		t.Fatalf("解析生成 PDF 节点失败: %+v", action)
+	}
+	script := `{"type":"runScript","script":"1"}`
+	if _, ok := parsePrintNode(&models.WorkflowNode{Action: &script}); ok {
+		t.Errorf("运行脚本节点不应解析为生成 PDF 节点")
+	}
+
+	svc := &ScriptActionService{printer: &PrintService{}}
+	resp := svc.executePrint(context.Background(), "usr1", action, map[string]interface{}{})
+	if resp.Status != "failed" || !strings.Contains(resp.Error, "recordId") {
+		t.Errorf("缺少记录时应失败，得到 %+v", resp)
+	}
+}
@@ -0,0 +1,376 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+const (
+	// piiScanSampleSize 每次扫描的最大记录数
+	piiScanSampleSize = 1000
+	// piiFindingMaxRecordIDs 每个扫描结果保留的记录ID数量
+	piiFindingMaxRecordIDs = 20
+)
+
+// PrivacyService PII检测与字段脱敏服务 ✨
+//
+// 脱敏在应用层完成：读取接口、导出和实时推送在返回前按调用者角色处理，
+// 数据库中仍保存原值（需要静态加密请使用加密字段）
+type PrivacyService struct {
+	repo              privacy.Repository
+	recordRepo        recordRepo.RecordRepository
+	fieldRepo         fieldRepo.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	baseRepo          baseRepo.BaseRepository
+	permissionService *PermissionServiceV2
+	publications      TablePublicationChecker
+}
+
+// TablePublicationChecker 表是否被发布模式复制（由 ReplicationService 实现）
+// 逻辑复制直接读取物理表，已发布的表设置脱敏策略也无法生效
+type TablePublicationChecker interface {
+	PublishesTable(ctx context.Context, baseID, tableID string) (bool, error)
+}
+
+// NewPrivacyService 创建隐私服务
+func NewPrivacyService(
+	repo privacy.Repository,
+	recordRepo recordRepo.RecordRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	baseRepo baseRepo.BaseRepository,
+	permissionService *PermissionServiceV2,
+) *PrivacyService {
+	return &PrivacyService{
+		repo:              repo,
+		recordRepo:        recordRepo,
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		baseRepo:          baseRepo,
+		permissionService: permissionService,
+	}
+}
+
+// SetPublicationChecker 设置发布检查：已被发布模式复制的表不能设置脱敏策略
+func (s *PrivacyService) SetPublicationChecker(checker TablePublicationChecker) {
+	s.publications = checker
+}
+
+// SetMaskingPolicyRequest 设置字段脱敏策略请求
+type SetMaskingPolicyRequest struct {
+	Strategy    string   `json:"strategy"`
+	PIIType     string   `json:"piiType"`
+	ExemptRoles []string `json:"exemptRoles"`
+}
+
+// ==================== PII扫描 ====================
+
+// ScanTable 扫描表中可能包含PII的字段（抽样最近的记录）
+func (s *PrivacyService) ScanTable(ctx context.Context, userID, tableID string) ([]*privacy.PIIFinding, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限扫描该表")
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	records, _, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{
+		TableID: &tableID,
+		Limit:   piiScanSampleSize,
+	})
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	now := time.Now()
+	findings := make(map[string]*privacy.PIIFinding)
+	order := make([]string, 0)
+	for _, record := range records {
+		data := record.Data().ToMap()
+		for _, field := range fields {
+			if field.IsComputed() {
+				continue
+			}
+			fieldID := field.ID().String()
+			for _, piiType := range privacy.DetectPII(data[fieldID]) {
+				key := fieldID + ":" + string(piiType)
+				finding, ok := findings[key]
+				if !ok {
+					finding = &privacy.PIIFinding{
+						TableID:     tableID,
+						FieldID:     fieldID,
+						PIIType:     piiType,
+						SampleSize:  len(records),
+						RecordIDs:   []string{},
+						ScannedTime: now,
+					}
+					findings[key] = finding
+					order = append(order, key)
+				}
+				finding.MatchCount++
+				if len(finding.RecordIDs) < piiFindingMaxRecordIDs {
+					finding.RecordIDs = append(finding.RecordIDs, record.ID().String())
+				}
+			}
+		}
+	}
+
+	result := make([]*privacy.PIIFinding, 0, len(order))
+	for _, key := range order {
+		result = append(result, findings[key])
+	}
+	if err := s.repo.ReplaceFindings(ctx, tableID, result); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+
+	logger.Info("✅ PII扫描完成",
+		logger.String("table_id", tableID),
+		logger.Int("sample_size", len(records)),
+		logger.Int("finding_count", len(result)))
+	return result, nil
+}
+
+// ListFindings 获取最近一次扫描结果
+func (s *PrivacyService) ListFindings(ctx context.Context, userID, tableID string) ([]*privacy.PIIFinding, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限查看扫描结果")
+	}
+	findings, err := s.repo.ListFindings(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return findings, nil
+}
+
+// ==================== 脱敏策略 ====================
+
+// SetMaskingPolicy 设置字段脱敏策略
+func (s *PrivacyService) SetMaskingPolicy(ctx context.Context, userID, tableID, fieldID string, req SetMaskingPolicyRequest) (*privacy.MaskingPolicy, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限设置脱敏策略")
+	}
+	if s.publications != nil {
+		table, err := s.tableRepo.GetByID(ctx, tableID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "获取Table信息失败")
+		}
+		if table == nil {
+			return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+		}
+		published, err := s.publications.PublishesTable(ctx, table.BaseID(), tableID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询复制目标失败")
+		}
+		if published {
+			return nil, pkgerrors.ErrConflict.WithDetails("该表格正通过发布模式复制，脱敏无法生效，请先将其移出发布")
+		}
+	}
+
+	strategy := privacy.MaskStrategy(req.Strategy)
+	if strategy == "" {
+		strategy = privacy.DefaultStrategyFor(privacy.PIIType(req.PIIType))
+	}
+	if !privacy.IsValidStrategy(strategy) {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的脱敏方式: " + req.Strategy)
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	found := false
+	for _, field := range fields {
+		if field.ID().String() == fieldID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+
+	policy := privacy.NewMaskingPolicy(tableID, fieldID, strategy, req.ExemptRoles, userID)
+	policy.PIIType = privacy.PIIType(req.PIIType)
+	if err := s.repo.SavePolicy(ctx, policy); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return s.repo.GetPolicy(ctx, tableID, fieldID)
+}
+
+// ListMaskingPolicies 列出表的脱敏策略
+func (s *PrivacyService) ListMaskingPolicies(ctx context.Context, userID, tableID string) ([]*privacy.MaskingPolicy, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表")
+	}
+	policies, err := s.repo.ListPolicies(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return policies, nil
+}
+
+// DeleteMaskingPolicy 删除字段脱敏策略
+func (s *PrivacyService) DeleteMaskingPolicy(ctx context.Context, userID, tableID, fieldID string) error {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限删除脱敏策略")
+	}
+	if err := s.repo.DeletePolicy(ctx, tableID, fieldID); err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	return nil
+}
+
+// ==================== 脱敏执行 ====================
+
+// MaskRecords 按当前用户角色对记录响应脱敏（原地修改）
+// 用于读取接口和导出
+func (s *PrivacyService) MaskRecords(ctx context.Context, tableID string, records ...*dto.RecordResponse) {
+	policies := s.policies(ctx, tableID)
+	if len(policies) == 0 {
+		return
+	}
+
+	role := s.currentRole(ctx, tableID)
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		record.Data = applyPolicies(record.Data, policies, role)
+	}
+}
+
+// MaskFields 按当前用户角色对单条记录的字段数据脱敏（返回新的 map）
+// 用于 ShareDB 快照等不经过 RecordResponse 的读取路径，与 MaskRecords 规则一致
+func (s *PrivacyService) MaskFields(ctx context.Context, tableID string, fields map[string]interface{}) map[string]interface{} {
+	policies := s.policies(ctx, tableID)
+	if len(policies) == 0 {
+		return fields
+	}
+	return applyPolicies(fields, policies, s.currentRole(ctx, tableID))
+}
+
+// MaskForStream 实时推送的字段脱敏
+// 广播的接收者无法逐个判断角色，因此所有受策略保护的字段一律脱敏，
+// 有权限的客户端可通过读取接口获取明文
+func (s *PrivacyService) MaskForStream(ctx context.Context, tableID string, fields map[string]interface{}) map[string]interface{} {
+	policies := s.policies(ctx, tableID)
+	if len(policies) == 0 {
+		return fields
+	}
+	return applyPolicies(fields, policies, "")
+}
+
+// ProtectedFieldIDs 当前用户角色下会被脱敏的字段
+// 聚合类接口据此拒绝按这些字段分组或计算数值，避免绕过脱敏推断明文
+func (s *PrivacyService) ProtectedFieldIDs(ctx context.Context, tableID string) map[string]bool {
+	policies := s.policies(ctx, tableID)
+	if len(policies) == 0 {
+		return nil
+	}
+
+	role := s.currentRole(ctx, tableID)
+	protected := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		if !policy.IsExempt(role) {
+			protected[policy.FieldID] = true
+		}
+	}
+	return protected
+}
+
+// policies 加载表的脱敏策略
+// 加载失败时按失败关闭处理：表的所有字段一律全量脱敏且无豁免角色，
+// 连字段也无法读取时返回通配策略，宁可暂时看不到数据也不泄露明文
+func (s *PrivacyService) policies(ctx context.Context, tableID string) []*privacy.MaskingPolicy {
+	policies, err := s.repo.ListPolicies(ctx, tableID)
+	if err == nil {
+		return policies
+	}
+	logger.Warn("加载脱敏策略失败，全部字段脱敏",
+		logger.String("table_id", tableID),
+		logger.ErrorField(err))
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil || len(fields) == 0 {
+		return []*privacy.MaskingPolicy{maskAllPolicy(tableID, maskAllFieldID)}
+	}
+	policies = make([]*privacy.MaskingPolicy, 0, len(fields))
+	for _, field := range fields {
+		policies = append(policies, maskAllPolicy(tableID, field.ID().String()))
+	}
+	return policies
+}
+
+// maskAllFieldID 通配字段：策略作用于记录的所有字段
+const maskAllFieldID = "*"
+
+// maskAllPolicy 失败关闭时使用的全量脱敏策略（ExemptRoles 为空切片，不套用默认豁免角色）
+func maskAllPolicy(tableID, fieldID string) *privacy.MaskingPolicy {
+	return &privacy.MaskingPolicy{
+		TableID:     tableID,
+		FieldID:     fieldID,
+		Strategy:    privacy.MaskStrategyFull,
+		ExemptRoles: []string{},
+	}
+}
+
+// currentRole 解析当前用户在表所属Base（或其空间）上的角色
+func (s *PrivacyService) currentRole(ctx context.Context, tableID string) string {
+	userID, ok := authctx.UserFrom(ctx)
+	if !ok {
+		return ""
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil || table == nil {
+		return ""
+	}
+	if role, err := s.permissionService.GetUserRole(ctx, userID, table.BaseID()); err == nil && role != "" {
+		return string(role)
+	}
+
+	base, err := s.baseRepo.FindByID(ctx, table.BaseID())
+	if err != nil || base == nil {
+		return ""
+	}
+	if role, err := s.permissionService.GetUserRole(ctx, userID, base.SpaceID); err == nil {
+		return string(role)
+	}
+	return ""
+}
+
+// applyPolicies 对字段数据应用脱敏策略（返回新的 map）
+func applyPolicies(data map[string]interface{}, policies []*privacy.MaskingPolicy, role string) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		masked[k] = v
+	}
+	for _, policy := range policies {
+		if policy.IsExempt(role) {
+			continue
+		}
+		if policy.FieldID == maskAllFieldID {
+			for k, v := range masked {
+				masked[k] = privacy.MaskValue(v, policy.Strategy)
+			}
+			continue
+		}
+		if v, ok := masked[policy.FieldID]; ok {
+			masked[policy.FieldID] = privacy.MaskValue(v, policy.Strategy)
+		}
+	}
+	return masked
+}
@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+// stubPrivacyRepo 内存中的脱敏策略，可模拟加载失败
+type stubPrivacyRepo struct {
+	privacy.Repository
+	policies map[string][]*privacy.MaskingPolicy
+	err      error
+}
+
+func (r *stubPrivacyRepo) ListPolicies(_ context.Context, tableID string) ([]*privacy.MaskingPolicy, error) {
+	return r.policies[tableID], r.err
+}
+
+type privacyFixture struct {
+	*batchAuthorizationFixture
+	repo    *stubPrivacyRepo
+	fields  *stubLockFieldRepo
+	service *PrivacyService
+}
+
+// newPrivacyFixture 在批量授权夹具的表上为 fld_phone 设置全部替换的脱敏策略（仅所有者可见明文）
+func newPrivacyFixture(t *testing.T) *privacyFixture {
+	t.Helper()
+	fx := &privacyFixture{batchAuthorizationFixture: newBatchAuthorizationFixture(t)}
+	fx.repo = &stubPrivacyRepo{policies: map[string][]*privacy.MaskingPolicy{
+		fx.tableID: {privacy.NewMaskingPolicy(fx.tableID, "fld_phone", privacy.MaskStrategyFull, nil, "usr1")},
+	}}
+	fx.fields = &stubLockFieldRepo{}
+	fx.service = NewPrivacyService(fx.repo, nil, fx.fields, fx.tables,
+		&stubSecurityBaseRepo{spaces: map[string]string{"bse1": "spc1"}}, fx.permissions)
+	return fx
+}
+
+func TestMaskFieldsByRole(t *testing.T) {
+	fx := newPrivacyFixture(t)
+	fx.repo.policies[fx.tableID] = []*privacy.MaskingPolicy{
+		privacy.NewMaskingPolicy(fx.tableID, "fld_phone", privacy.MaskStrategyFull, []string{"editor"}, "usr1"),
+	}
+	data := map[string]interface{}{"fld_phone": "13800138000", "fld_name": "张三"}
+
+	if got := fx.service.MaskFields(authctx.WithUser(context.Background(), "usr1"), fx.tableID, data); got["fld_phone"] != "13800138000" {
+		t.Fatalf("豁免角色应看到明文，得到 %v", got["fld_phone"])
+	}
+	for _, ctx := range []context.Context{authctx.WithUser(context.Background(), "usr2"), context.Background()} {
+		got := fx.service.MaskFields(ctx, fx.tableID, data)
+		if got["fld_phone"] == "13800138000" || got["fld_name"] != "张三" {
+			t.Fatalf("非豁免角色与匿名连接应只脱敏受保护字段，得到 %v", got)
+		}
+	}
+}
+
+func TestMaskingFailsClosedWhenPoliciesUnavailable(t *testing.T) {
+	fx := newPrivacyFixture(t)
+	fx.repo.err = errors.New("connection refused")
+	data := map[string]interface{}{"fld_phone": "13800138000", "fld_name": "张三"}
+
+	// 字段也无法读取：通配策略脱敏所有字段
+	got := fx.service.MaskFields(authctx.WithUser(context.Background(), "usr1"), fx.tableID, data)
+	if got["fld_phone"] == "13800138000" || got["fld_name"] == "张三" {
+		t.Fatalf("策略无法加载时应脱敏全部字段，得到 %v", got)
+	}
+
+	// 按表的字段逐个脱敏，受保护字段同样拒绝聚合
+	name := newTemplateTestField(t, "姓名", fieldVO.TypeText)
+	fx.fields.fields = []*fieldEntity.Field{name}
+	data = map[string]interface{}{name.ID().String(): "张三"}
+	if got := fx.service.MaskFields(authctx.WithUser(context.Background(), "usr1"), fx.tableID, data); got[name.ID().String()] == "张三" {
+		t.Fatalf("策略无法加载时应脱敏全部字段，得到 %v", got)
+	}
+	if !fx.service.ProtectedFieldIDs(authctx.WithUser(context.Background(), "usr1"), fx.tableID)[name.ID().String()] {
+		t.Fatal("策略无法加载时所有字段都应视为受保护")
+	}
+}
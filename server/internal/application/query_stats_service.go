@@ -0,0 +1,453 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// 统计列表排序字段
+const (
+	QueryStatsSortTotal   = "total"   // 累计耗时（默认）
+	QueryStatsSortAvg     = "avg"     // 平均耗时
+	QueryStatsSortMax     = "max"     // 最大单次耗时
+	QueryStatsSortSlow    = "slow"    // 慢查询次数
+	QueryStatsSortQueries = "queries" // 查询次数
+	QueryStatsSortErrors  = "errors"  // 失败次数
+)
+
+const (
+	defaultQueryStatsLimit = 50
+	maxQueryStatsLimit     = 500
+)
+
+// QueryStatsFilter 统计查询条件
+type QueryStatsFilter struct {
+	BaseID  string
+	TableID string
+	Sort    string
+	Limit   int
+}
+
+// QueryStatsTotals 查询耗时汇总（毫秒）
+type QueryStatsTotals struct {
+	Queries     int64      `json:"queries"`
+	SlowQueries int64      `json:"slow_queries"`
+	Errors      int64      `json:"errors"`
+	Rows        int64      `json:"rows"`
+	TotalMS     float64    `json:"total_ms"`
+	AvgMS       float64    `json:"avg_ms"`
+	MaxMS       float64    `json:"max_ms"`
+	LastSlowAt  *time.Time `json:"last_slow_at,omitempty"`
+}
+
+// TableQueryStatsView 动态表查询统计
+type TableQueryStatsView struct {
+	TableID   string `json:"table_id"`
+	TableName string `json:"table_name,omitempty"`
+	BaseID    string `json:"base_id,omitempty"`
+	BaseName  string `json:"base_name,omitempty"`
+	Schema    string `json:"schema"`
+	QueryStatsTotals
+}
+
+// BaseQueryStatsView Base 维度的查询统计（Base 下所有动态表之和）
+type BaseQueryStatsView struct {
+	BaseID     string `json:"base_id"`
+	BaseName   string `json:"base_name,omitempty"`
+	SpaceID    string `json:"space_id,omitempty"`
+	Tables     int    `json:"tables"`
+	TopTableID string `json:"top_table_id"` // 累计耗时最高的表
+	QueryStatsTotals
+}
+
+// SlowQueryView 慢查询记录
+type SlowQueryView struct {
+	database.SlowQueryEntry
+	TableName  string  `json:"table_name,omitempty"`
+	BaseID     string  `json:"base_id,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// QueryStatsSummary 统计概览
+type QueryStatsSummary struct {
+	Enabled         bool  `json:"enabled"`
+	Tables          int   `json:"tables"`
+	SlowQueriesSeen int64 `json:"slow_queries_seen"` // 进程启动（或上次重置）以来的慢查询总数
+	DroppedQueries  int64 `json:"dropped_queries"`   // 因表数量上限未统计的查询数
+	QueryStatsTotals
+}
+
+// QueryStatsService 动态表查询统计服务 ✨
+// 汇总慢查询插件在各数据库连接（主库与数据区域）上的统计，并解析表名/Base名，回答“哪个 Base 在拖慢数据库”
+type QueryStatsService struct {
+	db      *gorm.DB
+	sources []*database.QueryStatistics
+}
+
+// NewQueryStatsService 创建查询统计服务（sources 中的 nil 会被忽略）
+func NewQueryStatsService(db *gorm.DB, sources ...*database.QueryStatistics) *QueryStatsService {
+	s := &QueryStatsService{db: db}
+	for _, src := range sources {
+		if src != nil {
+			s.sources = append(s.sources, src)
+		}
+	}
+	return s
+}
+
+// Enabled 是否启用了查询统计
+func (s *QueryStatsService) Enabled() bool {
+	return len(s.sources) > 0
+}
+
+// Summary 统计概览
+func (s *QueryStatsService) Summary(ctx context.Context) *QueryStatsSummary {
+	tables := s.mergedTables()
+	summary := &QueryStatsSummary{Enabled: s.Enabled(), Tables: len(tables)}
+	for _, src := range s.sources {
+		slow, dropped := src.Totals()
+		summary.SlowQueriesSeen += slow
+		summary.DroppedQueries += dropped
+	}
+	for _, t := range tables {
+		summary.add(t)
+	}
+	summary.finish()
+	return summary
+}
+
+// ListTables 按动态表列出查询统计
+func (s *QueryStatsService) ListTables(ctx context.Context, filter QueryStatsFilter) ([]TableQueryStatsView, error) {
+	if err := s.ensureEnabled(); err != nil {
+		return nil, err
+	}
+
+	tables := s.mergedTables()
+	meta, err := s.resolveTables(ctx, tableIDs(tables))
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]TableQueryStatsView, 0, len(tables))
+	for _, t := range tables {
+		view := TableQueryStatsView{TableID: t.Table, Schema: t.Schema, BaseID: t.Schema}
+		if m, ok := meta[t.Table]; ok {
+			view.TableName = m.Name
+			view.BaseID = m.BaseID
+		}
+		if filter.BaseID != "" && view.BaseID != filter.BaseID {
+			continue
+		}
+		view.add(t)
+		view.finish()
+		views = append(views, view)
+	}
+
+	sortQueryStats(views, filter.Sort, func(v TableQueryStatsView) QueryStatsTotals { return v.QueryStatsTotals })
+	views = limitSlice(views, filter.Limit)
+
+	baseNames, err := s.resolveBaseNames(ctx, tableViewBaseIDs(views))
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		views[i].BaseName = baseNames[views[i].BaseID].Name
+	}
+	return views, nil
+}
+
+// ListBases 按 Base 汇总查询统计
+func (s *QueryStatsService) ListBases(ctx context.Context, filter QueryStatsFilter) ([]BaseQueryStatsView, error) {
+	if err := s.ensureEnabled(); err != nil {
+		return nil, err
+	}
+
+	tables := s.mergedTables()
+	meta, err := s.resolveTables(ctx, tableIDs(tables))
+	if err != nil {
+		return nil, err
+	}
+	views := aggregateByBase(tables, meta)
+
+	sortQueryStats(views, filter.Sort, func(v BaseQueryStatsView) QueryStatsTotals { return v.QueryStatsTotals })
+	views = limitSlice(views, filter.Limit)
+
+	ids := make([]string, 0, len(views))
+	for _, v := range views {
+		ids = append(ids, v.BaseID)
+	}
+	bases, err := s.resolveBaseNames(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		views[i].BaseName = bases[views[i].BaseID].Name
+		views[i].SpaceID = bases[views[i].BaseID].SpaceID
+	}
+	return views, nil
+}
+
+// ListSlowQueries 最近的慢查询（按时间倒序）
+func (s *QueryStatsService) ListSlowQueries(ctx context.Context, filter QueryStatsFilter) ([]SlowQueryView, error) {
+	if err := s.ensureEnabled(); err != nil {
+		return nil, err
+	}
+
+	var entries []database.SlowQueryEntry
+	for _, src := range s.sources {
+		entries = append(entries, src.SlowQueries()...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Table != "" {
+			ids = append(ids, e.Table)
+		}
+	}
+	meta, err := s.resolveTables(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]SlowQueryView, 0, len(entries))
+	for _, e := range entries {
+		view := SlowQueryView{SlowQueryEntry: e, BaseID: e.Schema, DurationMS: durationMS(e.Duration)}
+		if m, ok := meta[e.Table]; ok {
+			view.TableName = m.Name
+			view.BaseID = m.BaseID
+		}
+		if filter.TableID != "" && e.Table != filter.TableID {
+			continue
+		}
+		if filter.BaseID != "" && view.BaseID != filter.BaseID {
+			continue
+		}
+		views = append(views, view)
+	}
+	return limitSlice(views, filter.Limit), nil
+}
+
+// Reset 清空统计
+func (s *QueryStatsService) Reset() {
+	for _, src := range s.sources {
+		src.Reset()
+	}
+}
+
+func (s *QueryStatsService) ensureEnabled() error {
+	if !s.Enabled() {
+		return errors.ErrBadRequest.WithDetails("未启用慢查询统计（database.slow_query.enabled）")
+	}
+	return nil
+}
+
+// mergedTables 合并各连接的表统计（同一张表只会位于一个区域，合并仅为防御）
+func (s *QueryStatsService) mergedTables() []database.TableQueryStats {
+	if len(s.sources) == 1 {
+		return s.sources[0].Tables()
+	}
+	merged := make(map[string]*database.TableQueryStats)
+	var order []string
+	for _, src := range s.sources {
+		for _, t := range src.Tables() {
+			key := t.Schema + "." + t.Table
+			existing, ok := merged[key]
+			if !ok {
+				t := t
+				merged[key] = &t
+				order = append(order, key)
+				continue
+			}
+			existing.Queries += t.Queries
+			existing.SlowQueries += t.SlowQueries
+			existing.Errors += t.Errors
+			existing.RowsAffected += t.RowsAffected
+			existing.TotalDuration += t.TotalDuration
+			if t.MaxDuration > existing.MaxDuration {
+				existing.MaxDuration = t.MaxDuration
+			}
+			if t.LastSlowAt != nil && (existing.LastSlowAt == nil || t.LastSlowAt.After(*existing.LastSlowAt)) {
+				existing.LastSlowAt = t.LastSlowAt
+			}
+		}
+	}
+	result := make([]database.TableQueryStats, 0, len(order))
+	for _, key := range order {
+		result = append(result, *merged[key])
+	}
+	return result
+}
+
+type tableMeta struct {
+	ID     string
+	BaseID string
+	Name   string
+}
+
+type baseMeta struct {
+	ID      string
+	SpaceID string
+	Name    string
+}
+
+// resolveTables 批量解析表名与所属 Base（物理表名即 Table ID；工作空间隔离模式下 Schema 不是 Base ID）
+func (s *QueryStatsService) resolveTables(ctx context.Context, ids []string) (map[string]tableMeta, error) {
+	result := make(map[string]tableMeta, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var rows []tableMeta
+	if err := s.db.WithContext(ctx).Table("table_meta").
+		Select("id, base_id, name").
+		Where("id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Database(err, "解析动态表失败")
+	}
+	for _, row := range rows {
+		result[row.ID] = row
+	}
+	return result, nil
+}
+
+// resolveBaseNames 批量解析 Base 名称与所属空间
+func (s *QueryStatsService) resolveBaseNames(ctx context.Context, ids []string) (map[string]baseMeta, error) {
+	result := make(map[string]baseMeta, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var rows []baseMeta
+	if err := s.db.WithContext(ctx).Table("base").
+		Select("id, space_id, name").
+		Where("id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Database(err, "解析 Base 失败")
+	}
+	for _, row := range rows {
+		result[row.ID] = row
+	}
+	return result, nil
+}
+
+// aggregateByBase 按 Base 汇总表统计（无法解析元数据的表按 Schema 归组）
+func aggregateByBase(tables []database.TableQueryStats, meta map[string]tableMeta) []BaseQueryStatsView {
+	byBase := make(map[string]*BaseQueryStatsView)
+	topTotal := make(map[string]time.Duration)
+	var order []string
+
+	for _, t := range tables {
+		baseID := t.Schema
+		if m, ok := meta[t.Table]; ok {
+			baseID = m.BaseID
+		}
+		view, ok := byBase[baseID]
+		if !ok {
+			view = &BaseQueryStatsView{BaseID: baseID}
+			byBase[baseID] = view
+			order = append(order, baseID)
+		}
+		view.Tables++
+		view.add(t)
+		if t.TotalDuration > topTotal[baseID] || view.TopTableID == "" {
+			topTotal[baseID] = t.TotalDuration
+			view.TopTableID = t.Table
+		}
+	}
+
+	result := make([]BaseQueryStatsView, 0, len(order))
+	for _, baseID := range order {
+		view := byBase[baseID]
+		view.finish()
+		result = append(result, *view)
+	}
+	return result
+}
+
+// add 累加一张表的统计（TotalMS/MaxMS 暂存累计值，finish 时计算平均值）
+func (t *QueryStatsTotals) add(s database.TableQueryStats) {
+	t.Queries += s.Queries
+	t.SlowQueries += s.SlowQueries
+	t.Errors += s.Errors
+	t.Rows += s.RowsAffected
+	t.TotalMS += durationMS(s.TotalDuration)
+	if max := durationMS(s.MaxDuration); max > t.MaxMS {
+		t.MaxMS = max
+	}
+	if s.LastSlowAt != nil && (t.LastSlowAt == nil || s.LastSlowAt.After(*t.LastSlowAt)) {
+		t.LastSlowAt = s.LastSlowAt
+	}
+}
+
+func (t *QueryStatsTotals) finish() {
+	if t.Queries > 0 {
+		t.AvgMS = t.TotalMS / float64(t.Queries)
+	}
+}
+
+// sortQueryStats 按指定字段降序排序
+func sortQueryStats[T any](items []T, by string, totals func(T) QueryStatsTotals) {
+	key := func(t QueryStatsTotals) float64 {
+		switch by {
+		case QueryStatsSortAvg:
+			return t.AvgMS
+		case QueryStatsSortMax:
+			return t.MaxMS
+		case QueryStatsSortSlow:
+			return float64(t.SlowQueries)
+		case QueryStatsSortQueries:
+			return float64(t.Queries)
+		case QueryStatsSortErrors:
+			return float64(t.Errors)
+		default:
+			return t.TotalMS
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return key(totals(items[i])) > key(totals(items[j]))
+	})
+}
+
+func limitSlice[T any](items []T, limit int) []T {
+	if limit <= 0 {
+		limit = defaultQueryStatsLimit
+	}
+	if limit > maxQueryStatsLimit {
+		limit = maxQueryStatsLimit
+	}
+	if len(items) > limit {
+		return items[:limit]
+	}
+	return items
+}
+
+func tableIDs(tables []database.TableQueryStats) []string {
+	ids := make([]string, 0, len(tables))
+	for _, t := range tables {
+		ids = append(ids, t.Table)
+	}
+	return ids
+}
+
+func tableViewBaseIDs(views []TableQueryStatsView) []string {
+	seen := make(map[string]bool, len(views))
+	ids := make([]string, 0, len(views))
+	for _, v := range views {
+		if v.BaseID != "" && !seen[v.BaseID] {
+			seen[v.BaseID] = true
+			ids = append(ids, v.BaseID)
+		}
+	}
+	return ids
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// SetArchiveStore 设置记录归档存储（启用归档记录查询）
+func (s *RecordService) SetArchiveStore(store recordRepo.ArchiveStore) {
+	s.archiveStore = store
+}
+
+// ListArchivedRecords 分页读取表的归档记录 ✨
+// 归档记录返回存储值，不重新计算公式等虚拟字段
+func (s *RecordService) ListArchivedRecords(ctx context.Context, tableID string, limit, offset int) ([]*dto.RecordResponse, int64, error) {
+	if s.archiveStore == nil {
+		return nil, 0, pkgerrors.ErrBadRequest.WithDetails("当前服务未启用记录归档")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	records, total, err := s.archiveStore.ListArchived(ctx, tableID, offset, limit)
+	if err != nil {
+		return nil, 0, pkgerrors.Database(err, "查询归档记录失败")
+	}
+	responses := dto.FromRecordEntities(records)
+	for _, resp := range responses {
+		resp.Archived = true
+	}
+	s.maskResponses(ctx, tableID, responses...)
+	return responses, total, nil
+}
+
+// ListRecordsIncludingArchived 列出记录，当前记录之后接续归档记录（includeArchived 查询）✨
+// 总数为当前记录数与归档记录数之和；分页越过当前记录后从归档记录中读取
+// fieldIDs 非空时只返回这些字段（归档记录读取后再投影）
+func (s *RecordService) ListRecordsIncludingArchived(ctx context.Context, tableID string, limit, offset int, fieldIDs []string) ([]*dto.RecordResponse, int64, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	records, liveTotal, err := s.ListRecordsWithFields(ctx, tableID, limit, offset, fieldIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if s.archiveStore == nil {
+		return records, liveTotal, nil
+	}
+
+	remaining := limit - len(records)
+	if remaining <= 0 {
+		archivedTotal, err := s.archiveStore.CountArchived(ctx, tableID)
+		if err != nil {
+			return nil, 0, pkgerrors.Database(err, "统计归档记录失败")
+		}
+		return records, liveTotal + archivedTotal, nil
+	}
+
+	archivedOffset := offset - int(liveTotal)
+	if archivedOffset < 0 {
+		archivedOffset = 0
+	}
+	archived, archivedTotal, err := s.ListArchivedRecords(ctx, tableID, remaining, archivedOffset)
+	if err != nil {
+		return nil, 0, err
+	}
+	projectRecordResponses(archived, fieldIDs)
+	return append(records, archived...), liveTotal + archivedTotal, nil
+}
+
+// projectRecordResponses 只保留记录响应中 fieldIDs 指定字段的值（fieldIDs 为空时不处理）
+func projectRecordResponses(records []*dto.RecordResponse, fieldIDs []string) {
+	if len(fieldIDs) == 0 {
+		return
+	}
+	for _, record := range records {
+		data := make(map[string]interface{}, len(fieldIDs))
+		for _, id := range fieldIDs {
+			if value, ok := record.Data[id]; ok {
+				data[id] = value
+			}
+		}
+		record.Data = data
+	}
+}
@@ -0,0 +1,277 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordarchive"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var recordArchiveLog = logger.Named("record_archive")
+
+// SaveArchivePolicyRequest 创建或更新归档策略请求
+type SaveArchivePolicyRequest struct {
+	Basis     string `json:"basis"`                        // createdTime（默认）| lastModifiedTime
+	AfterDays int    `json:"afterDays" binding:"required"` // 早于多少天的记录归档
+	Enabled   *bool  `json:"enabled"`                      // 为空时启用
+}
+
+// RestoreArchivedRequest 恢复归档记录请求
+type RestoreArchivedRequest struct {
+	RecordIDs []string `json:"recordIds" binding:"required"`
+}
+
+// ArchiveRunResult 一次归档执行的结果
+type ArchiveRunResult struct {
+	Archived  int  `json:"archived"`
+	Remaining bool `json:"remaining"` // 达到单次上限，仍有待归档的记录（下次执行继续）
+}
+
+// RestoreArchivedResult 恢复结果
+type RestoreArchivedResult struct {
+	Restored []string `json:"restored"`
+}
+
+// RecordArchiveService 记录归档服务 ✨
+// 按表的归档策略将早于阈值的记录分批移入冷存储（归档表），减小大表物理表的体积：
+//   - 归档的记录不再出现在默认查询、视图与统计中，列表可通过 includeArchived 接续读取归档记录；
+//   - 恢复时记录按原ID、自增序号与版本写回表中；
+//   - 归档与恢复不产生记录的增删事件（不触发自动化与 REST Hook），只写入按时间点读取所需的变更日志。
+//
+// 后台按 RunInterval 定期执行到期策略（租约保证多实例不重复执行），也可以手动立即执行
+type RecordArchiveService struct {
+	repo              recordarchive.Repository
+	store             recordRepo.ArchiveStore
+	recordRepo        recordRepo.RecordRepository
+	permissionService *PermissionServiceV2
+	viewQueries       *ViewQueryCache
+	cfg               config.RecordArchiveConfig
+	now               func() time.Time
+}
+
+// NewRecordArchiveService 创建记录归档服务
+func NewRecordArchiveService(
+	repo recordarchive.Repository,
+	store recordRepo.ArchiveStore,
+	recordRepository recordRepo.RecordRepository,
+	permissionService *PermissionServiceV2,
+	cfg config.RecordArchiveConfig,
+) *RecordArchiveService {
+	return &RecordArchiveService{
+		repo:              repo,
+		store:             store,
+		recordRepo:        recordRepository,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+	}
+}
+
+// SetViewQueryCache 设置视图查询缓存（归档与恢复后失效表上的缓存页）
+func (s *RecordArchiveService) SetViewQueryCache(viewQueries *ViewQueryCache) {
+	s.viewQueries = viewQueries
+}
+
+// GetPolicy 获取表的归档策略
+func (s *RecordArchiveService) GetPolicy(ctx context.Context, userID, tableID string) (*recordarchive.Policy, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	policy, err := s.repo.FindByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询归档策略失败")
+	}
+	if policy == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表未设置归档策略")
+	}
+	return policy, nil
+}
+
+// SavePolicy 创建或更新表的归档策略（需要表结构管理权限，保存后下一轮轮询即执行）
+func (s *RecordArchiveService) SavePolicy(ctx context.Context, userID, tableID string, req SaveArchivePolicyRequest) (*recordarchive.Policy, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的归档策略")
+	}
+	existing, err := s.repo.FindByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询归档策略失败")
+	}
+
+	now := s.now()
+	policy := recordarchive.NewPolicy(tableID, req.Basis, req.AfterDays, userID, now)
+	if existing != nil {
+		policy.ID = existing.ID
+		policy.CreatedBy = existing.CreatedBy
+		policy.CreatedAt = existing.CreatedAt
+		policy.LastRunAt = existing.LastRunAt
+		policy.LastArchived = existing.LastArchived
+		policy.LastError = existing.LastError
+	}
+	if req.Enabled != nil {
+		policy.Enabled = *req.Enabled
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, policy); err != nil {
+		return nil, pkgerrors.Database(err, "保存归档策略失败")
+	}
+	return policy, nil
+}
+
+// DeletePolicy 删除表的归档策略（已归档的记录保留在归档中）
+func (s *RecordArchiveService) DeletePolicy(ctx context.Context, userID, tableID string) error {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的归档策略")
+	}
+	policy, err := s.repo.FindByTable(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询归档策略失败")
+	}
+	if policy == nil {
+		return pkgerrors.ErrNotFound.WithDetails("表未设置归档策略")
+	}
+	if err := s.repo.Delete(ctx, policy.ID); err != nil {
+		return pkgerrors.Database(err, "删除归档策略失败")
+	}
+	return nil
+}
+
+// RunNow 按表的归档策略立即执行一次归档（需要表结构管理权限）
+func (s *RecordArchiveService) RunNow(ctx context.Context, userID, tableID string) (*ArchiveRunResult, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的归档策略")
+	}
+	policy, err := s.repo.FindByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询归档策略失败")
+	}
+	if policy == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表未设置归档策略")
+	}
+	result, err := s.archive(ctx, policy, userID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "归档记录失败")
+	}
+	return result, nil
+}
+
+// Restore 将归档记录恢复到表中（需要新建记录权限）
+func (s *RecordArchiveService) Restore(ctx context.Context, userID, tableID string, req RestoreArchivedRequest) (*RestoreArchivedResult, error) {
+	if !s.permissionService.CanCreateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有在该表格新建记录的权限")
+	}
+	if len(req.RecordIDs) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("recordIds 不能为空")
+	}
+	if len(req.RecordIDs) > s.cfg.MaxRestore {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+			"message": "单次恢复的记录数超过上限",
+			"max":     s.cfg.MaxRestore,
+		})
+	}
+
+	restored, err := s.store.Restore(ctx, tableID, req.RecordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "恢复归档记录失败")
+	}
+	s.invalidate(ctx, tableID, restored)
+	return &RestoreArchivedResult{Restored: restored}, nil
+}
+
+// Start 启动后台归档
+func (s *RecordArchiveService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runDue(ctx)
+		}
+	}()
+}
+
+// runDue 领取并执行到期的策略
+func (s *RecordArchiveService) runDue(ctx context.Context) {
+	now := s.now()
+	policies, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 10)
+	if err != nil {
+		recordArchiveLog.Warn(ctx, "领取归档策略失败", logger.ErrorField(err))
+		return
+	}
+	for _, policy := range policies {
+		s.runPolicy(ctx, policy)
+	}
+}
+
+// runPolicy 执行策略并保存结果；出错时记录错误，下个周期重试
+func (s *RecordArchiveService) runPolicy(ctx context.Context, policy *recordarchive.Policy) {
+	result, err := s.archive(ctx, policy, policy.CreatedBy)
+	now := s.now()
+	policy.LastRunAt = &now
+	policy.NextRunAt = now.Add(s.cfg.RunInterval)
+	policy.LastError = ""
+	if result != nil {
+		policy.LastArchived = result.Archived
+	}
+	if err != nil {
+		policy.LastError = err.Error()
+		recordArchiveLog.Warn(ctx, "归档记录失败",
+			logger.String("policy_id", policy.ID),
+			logger.String("table_id", policy.TableID),
+			logger.ErrorField(err))
+	}
+	if err := s.repo.SaveProgress(ctx, policy); err != nil {
+		recordArchiveLog.Warn(ctx, "保存归档进度失败", logger.String("policy_id", policy.ID), logger.ErrorField(err))
+	}
+}
+
+// archive 分批移入早于策略阈值的记录，直到没有待归档记录或达到单次上限
+// 出错时返回已完成批次的结果
+func (s *RecordArchiveService) archive(ctx context.Context, policy *recordarchive.Policy, archivedBy string) (*ArchiveRunResult, error) {
+	criteria := recordRepo.ArchiveCriteria{
+		Before:       policy.Cutoff(s.now()),
+		LastModified: policy.ByLastModified(),
+		ArchivedBy:   archivedBy,
+	}
+	result := &ArchiveRunResult{}
+	for result.Archived < s.cfg.MaxPerRun {
+		criteria.Limit = s.cfg.BatchSize
+		if left := s.cfg.MaxPerRun - result.Archived; left < criteria.Limit {
+			criteria.Limit = left
+		}
+		ids, err := s.store.Archive(ctx, policy.TableID, criteria)
+		if err != nil {
+			return result, err
+		}
+		s.invalidate(ctx, policy.TableID, ids)
+		result.Archived += len(ids)
+		if len(ids) < criteria.Limit {
+			return result, nil
+		}
+	}
+	result.Remaining = true
+	return result, nil
+}
+
+// invalidate 清除移动过的记录缓存与表上的视图查询缓存
+func (s *RecordArchiveService) invalidate(ctx context.Context, tableID string, recordIDs []string) {
+	if len(recordIDs) == 0 {
+		return
+	}
+	if invalidator, ok := s.recordRepo.(recordCacheInvalidator); ok {
+		for _, id := range recordIDs {
+			invalidator.InvalidateRecord(ctx, tableID, id)
+		}
+	}
+	s.viewQueries.InvalidateTable(ctx, tableID)
+}
@@ -0,0 +1,218 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordarchive"
+)
+
+// memoryArchivePolicyRepo 内存实现的归档策略仓储
+type memoryArchivePolicyRepo struct {
+	policies map[string]*recordarchive.Policy
+	progress []*recordarchive.Policy
+}
+
+func (r *memoryArchivePolicyRepo) FindByTable(_ context.Context, tableID string) (*recordarchive.Policy, error) {
+	return r.policies[tableID], nil
+}
+
+func (r *memoryArchivePolicyRepo) Save(_ context.Context, policy *recordarchive.Policy) error {
+	r.policies[policy.TableID] = policy
+	return nil
+}
+
+func (r *memoryArchivePolicyRepo) Delete(_ context.Context, id string) error {
+	for tableID, policy := range r.policies {
+		if policy.ID == id {
+			delete(r.policies, tableID)
+		}
+	}
+	return nil
+}
+
+func (r *memoryArchivePolicyRepo) ClaimDue(_ context.Context, now, _ time.Time, _ int) ([]*recordarchive.Policy, error) {
+	var due []*recordarchive.Policy
+	for _, policy := range r.policies {
+		if policy.Enabled && !policy.NextRunAt.After(now) {
+			due = append(due, policy)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryArchivePolicyRepo) SaveProgress(_ context.Context, policy *recordarchive.Policy) error {
+	r.progress = append(r.progress, policy)
+	return nil
+}
+
+// fakeArchiveStore 按顺序归档 pending 中的记录
+type fakeArchiveStore struct {
+	pending  []string
+	archived []*entity.Record
+	criteria []recordRepo.ArchiveCriteria
+	failAt   int // 第几次调用 Archive 时失败（从 1 开始，0 表示不失败）
+}
+
+func (s *fakeArchiveStore) Archive(_ context.Context, _ string, criteria recordRepo.ArchiveCriteria) ([]string, error) {
+	s.criteria = append(s.criteria, criteria)
+	if len(s.criteria) == s.failAt {
+		return nil, errors.New("connection reset")
+	}
+	n := criteria.Limit
+	if n > len(s.pending) {
+		n = len(s.pending)
+	}
+	ids := s.pending[:n]
+	s.pending = s.pending[n:]
+	return ids, nil
+}
+
+func (s *fakeArchiveStore) ListArchived(_ context.Context, _ string, offset, limit int) ([]*entity.Record, int64, error) {
+	total := int64(len(s.archived))
+	if offset >= len(s.archived) {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > len(s.archived) {
+		end = len(s.archived)
+	}
+	return s.archived[offset:end], total, nil
+}
+
+func (s *fakeArchiveStore) CountArchived(context.Context, string) (int64, error) {
+	return int64(len(s.archived)), nil
+}
+
+func (s *fakeArchiveStore) Restore(_ context.Context, _ string, recordIDs []string) ([]string, error) {
+	return recordIDs, nil
+}
+
+// invalidatingRecordRepo 记录被清除缓存的记录
+type invalidatingRecordRepo struct {
+	recordRepo.RecordRepository
+	live        []*entity.Record
+	invalidated []string
+}
+
+func (r *invalidatingRecordRepo) InvalidateRecord(_ context.Context, _, recordID string) {
+	r.invalidated = append(r.invalidated, recordID)
+}
+
+func (r *invalidatingRecordRepo) List(_ context.Context, filter recordRepo.RecordFilter) ([]*entity.Record, int64, error) {
+	total := int64(len(r.live))
+	if filter.Offset >= len(r.live) {
+		return nil, total, nil
+	}
+	end := filter.Offset + filter.Limit
+	if end > len(r.live) {
+		end = len(r.live)
+	}
+	return r.live[filter.Offset:end], total, nil
+}
+
+func newArchiveTestRecord(t *testing.T, id string) *entity.Record {
+	t.Helper()
+	data, err := valueobject.NewRecordData(map[string]interface{}{"fld1": id})
+	if err != nil {
+		t.Fatalf("创建记录数据失败: %v", err)
+	}
+	now := time.Now()
+	return entity.ReconstructRecord(valueobject.NewRecordID(id), "tbl1", data, valueobject.InitialVersion(), "usr1", "usr1", now, now, nil)
+}
+
+func TestRecordArchiveRunPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)
+	cfg := config.RecordArchiveConfig{RunInterval: time.Hour, BatchSize: 2, MaxPerRun: 5}
+	policy := recordarchive.NewPolicy("tbl1", recordarchive.BasisLastModifiedTime, 30, "usr1", now)
+	repo := &memoryArchivePolicyRepo{policies: map[string]*recordarchive.Policy{"tbl1": policy}}
+	store := &fakeArchiveStore{pending: []string{"rec1", "rec2", "rec3", "rec4", "rec5", "rec6"}}
+	records := &invalidatingRecordRepo{}
+
+	svc := NewRecordArchiveService(repo, store, records, nil, cfg)
+	svc.now = func() time.Time { return now }
+	svc.runDue(context.Background())
+
+	if len(repo.progress) != 1 {
+		t.Fatalf("应保存一次进度, got %d", len(repo.progress))
+	}
+	if policy.LastArchived != 5 || policy.LastError != "" || !policy.NextRunAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("执行结果 = %+v", policy)
+	}
+	// 单次上限 5 条：2 + 2 + 1
+	if len(store.criteria) != 3 || store.criteria[2].Limit != 1 {
+		t.Errorf("分批 = %+v", store.criteria)
+	}
+	if c := store.criteria[0]; !c.LastModified || !c.Before.Equal(now.AddDate(0, 0, -30)) || c.ArchivedBy != "usr1" {
+		t.Errorf("归档条件 = %+v", c)
+	}
+	if len(records.invalidated) != 5 || len(store.pending) != 1 {
+		t.Errorf("清除缓存 %v，剩余 %v", records.invalidated, store.pending)
+	}
+
+	// 出错时保留已完成批次的数量并记录错误
+	store = &fakeArchiveStore{pending: []string{"rec7", "rec8", "rec9"}, failAt: 2}
+	svc.store = store
+	policy.NextRunAt = now
+	svc.runDue(context.Background())
+	if policy.LastArchived != 2 || policy.LastError == "" {
+		t.Errorf("出错后的执行结果 = %+v", policy)
+	}
+}
+
+func TestListRecordsIncludingArchived(t *testing.T) {
+	records := &invalidatingRecordRepo{live: []*entity.Record{
+		newArchiveTestRecord(t, "rec1"),
+		newArchiveTestRecord(t, "rec2"),
+		newArchiveTestRecord(t, "rec3"),
+	}}
+	store := &fakeArchiveStore{archived: []*entity.Record{
+		newArchiveTestRecord(t, "old1"),
+		newArchiveTestRecord(t, "old2"),
+	}}
+	svc := &RecordService{recordRepo: records}
+	svc.SetArchiveStore(store)
+	ctx := context.Background()
+
+	cases := []struct {
+		limit, offset int
+		want          []string
+	}{
+		{2, 0, []string{"rec1", "rec2"}},
+		{2, 2, []string{"rec3", "old1"}},
+		{2, 4, []string{"old2"}},
+		{10, 0, []string{"rec1", "rec2", "rec3", "old1", "old2"}},
+	}
+	for _, c := range cases {
+		page, total, err := svc.ListRecordsIncludingArchived(ctx, "tbl1", c.limit, c.offset, nil)
+		if err != nil {
+			t.Fatalf("limit=%d offset=%d: %v", c.limit, c.offset, err)
+		}
+		if total != 5 {
+			t.Errorf("limit=%d offset=%d: total = %d", c.limit, c.offset, total)
+		}
+		var got []string
+		for _, r := range page {
+			got = append(got, r.ID)
+			if r.Archived != (r.ID[:3] == "old") {
+				t.Errorf("%s: archived = %v", r.ID, r.Archived)
+			}
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("limit=%d offset=%d: got %v, want %v", c.limit, c.offset, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("limit=%d offset=%d: got %v, want %v", c.limit, c.offset, got, c.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/application/permission"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+const (
+	// recordMutationBatchMaxOps 单次混合批量变更的最大操作数
+	recordMutationBatchMaxOps = 5000
+	// recordMutationChunkSize 每个事务执行的变更数（控制锁持有时间与事务大小）
+	recordMutationChunkSize = 500
+)
+
+// plannedMutation 合并后的一个记录变更
+// 同一记录的多个 update 合并为一次写入（版本只递增一次），indexes 为全部来源操作的位置
+type plannedMutation struct {
+	op      string
+	id      string
+	fields  map[string]interface{}
+	version *int
+	indexes []int
+}
+
+// mutationOutcome 事务内单个变更的执行结果（提交后才写入响应）
+type mutationOutcome struct {
+	mutation *plannedMutation
+	record   *entity.Record
+	event    *database.RecordEvent
+	err      error
+}
+
+// recordMutationBatchEvent 整批合并的业务事件数据
+type recordMutationBatchEvent struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+}
+
+// MutateRecords 混合批量创建/更新/删除记录 ✨
+//
+// 语义：
+//   - 部分失败：每个变更使用独立保存点，失败时只回滚自身，不影响同批次其他操作
+//   - 按 recordMutationChunkSize 分块，每块一个事务
+//   - 同一记录的多个 update 合并为一次写入，版本号只递增一次；与 delete 冲突的 update 失败
+//   - 实时推送逐条发送，业务事件（Webhook 等订阅方）整批只发布一个 record.batch 事件
+//   - 每块提交（或失败）后清除涉及记录的缓存，避免提交前被并发读取回填旧值
+func (s *RecordService) MutateRecords(ctx context.Context, tableID string, req dto.RecordMutationBatchRequest, userID string) (*dto.RecordMutationBatchResponse, error) {
+	if len(req.Operations) > recordMutationBatchMaxOps {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(
+			fmt.Sprintf("单次批量变更最多 %d 个操作", recordMutationBatchMaxOps))
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{
+			"table_id": tableID,
+		})
+	}
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return nil, err
+	}
+	creates := 0
+	for _, op := range req.Operations {
+		if op.Op == dto.RecordMutationCreate {
+			creates++
+		}
+	}
+	if err := s.checkPlanLimit(ctx, tableID, creates); err != nil {
+		return nil, err
+	}
+
+	db, err := s.getDBFromRecordRepo()
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("获取数据库连接失败: %v", err))
+	}
+
+	plan, results := planRecordMutations(req.Operations)
+	ctx, plan = s.authorizeMutations(ctx, tableID, plan, results, userID)
+	summary := &recordMutationBatchEvent{Created: []string{}, Updated: []string{}, Deleted: []string{}}
+	for start := 0; start < len(plan); start += recordMutationChunkSize {
+		end := start + recordMutationChunkSize
+		if end > len(plan) {
+			end = len(plan)
+		}
+		s.executeMutationChunk(ctx, db, tableID, plan[start:end], req.Typecast, userID, results, summary)
+	}
+
+	resp := &dto.RecordMutationBatchResponse{
+		Results: results,
+		Created: len(summary.Created),
+		Updated: len(summary.Updated),
+		Deleted: len(summary.Deleted),
+	}
+	var records []*dto.RecordResponse
+	for _, result := range results {
+		if result.Status != "success" {
+			resp.FailedCount++
+			continue
+		}
+		resp.SuccessCount++
+		if result.Record != nil {
+			records = append(records, result.Record)
+		}
+	}
+	s.maskResponses(ctx, tableID, records...)
+
+	if s.businessEvents != nil && resp.Created+resp.Updated+resp.Deleted > 0 {
+		if err := s.businessEvents.PublishTableEvent(context.Background(), events.BusinessEventTypeRecordBatch, tableID, summary, userID); err != nil {
+			logger.Error("发布批量变更事件失败",
+				logger.String("table_id", tableID),
+				logger.ErrorField(err))
+		}
+	}
+	changed := append(append(append([]string{}, summary.Created...), summary.Updated...), summary.Deleted...)
+	s.propagateLinkedRecords(tableID, "batch", changed, userID)
+
+	logger.Info("混合批量变更完成",
+		logger.String("table_id", tableID),
+		logger.Int("total", len(req.Operations)),
+		logger.Int("success", resp.SuccessCount),
+		logger.Int("failed", resp.FailedCount))
+	return resp, nil
+}
+
+// authorizeMutations 一次校验整批变更的权限，无权限的变更直接失败，返回附加了校验批次的上下文与有权限执行的变更
+// 批次附加到上下文后，逐条写入时的权限检查（如状态流的角色判断）复用同一批解析结果；同步写入不受限制
+func (s *RecordService) authorizeMutations(ctx context.Context, tableID string, plan []*plannedMutation, results []*dto.RecordMutationResult, userID string) (context.Context, []*plannedMutation) {
+	if s.authorizer == nil || isSyncedTableWrite(ctx) || len(plan) == 0 {
+		return ctx, plan
+	}
+	batch := s.authorizer.NewBatch()
+	checks := make([]AuthorizationCheck, len(plan))
+	for i, m := range plan {
+		checks[i] = AuthorizationCheck{UserID: userID, Action: recordMutationAction(m.op), Resource: AuthorizationResourceTable, ResourceID: tableID}
+	}
+	decisions := batch.Authorize(ctx, checks)
+
+	allowed := make([]*plannedMutation, 0, len(plan))
+	for i, m := range plan {
+		if decisions[i].Allowed {
+			allowed = append(allowed, m)
+			continue
+		}
+		markMutationResult(results, m, nil, pkgerrors.ErrForbidden.WithDetails(fmt.Sprintf("没有权限对该表格的记录执行 %s 操作", m.op)))
+	}
+	return WithAuthorizationBatch(ctx, batch), allowed
+}
+
+// recordMutationAction 变更类型对应的权限动作
+func recordMutationAction(op string) permission.Action {
+	switch op {
+	case dto.RecordMutationCreate:
+		return permission.ActionRecordCreate
+	case dto.RecordMutationDelete:
+		return permission.ActionRecordDelete
+	}
+	return permission.ActionRecordUpdate
+}
+
+// executeMutationChunk 在一个事务中执行一块变更，提交成功后写入结果并推送实时事件
+func (s *RecordService) executeMutationChunk(
+	ctx context.Context,
+	db *gorm.DB,
+	tableID string,
+	chunk []*plannedMutation,
+	typecast bool,
+	userID string,
+	results []*dto.RecordMutationResult,
+	summary *recordMutationBatchEvent,
+) {
+	var outcomes []mutationOutcome
+	err := database.Transaction(ctx, db, &database.BigTransactionOptions, func(txCtx context.Context) error {
+		outcomes = outcomes[:0] // 死锁重试时重新执行整块
+
+		targets, err := s.loadMutationTargets(txCtx, tableID, chunk)
+		if err != nil {
+			return err
+		}
+		tx := database.GetTxContext(txCtx).Tx
+		for i, m := range chunk {
+			savepoint := fmt.Sprintf("record_mutation_%d", i)
+			if err := tx.SavePoint(savepoint).Error; err != nil {
+				return pkgerrors.Database(err, "创建保存点失败")
+			}
+			record, event, err := s.applyMutation(txCtx, tableID, m, targets[m.id], typecast, userID)
+			if err == nil {
+				err = s.recordChangeFeed(txCtx, event)
+			}
+			if err != nil {
+				if rbErr := tx.RollbackTo(savepoint).Error; rbErr != nil {
+					return pkgerrors.Database(rbErr, "回滚保存点失败")
+				}
+			}
+			outcomes = append(outcomes, mutationOutcome{mutation: m, record: record, event: event, err: err})
+		}
+		return nil
+	})
+
+	for _, m := range chunk {
+		if m.id != "" {
+			s.invalidateCachedRecord(ctx, tableID, m.id)
+		}
+	}
+
+	if err != nil {
+		// 整块事务失败（如提交失败），块内所有操作均未生效
+		for _, m := range chunk {
+			markMutationResult(results, m, nil, err)
+		}
+		return
+	}
+
+	for _, o := range outcomes {
+		if o.err != nil {
+			markMutationResult(results, o.mutation, nil, o.err)
+			continue
+		}
+		var resp *dto.RecordResponse
+		switch o.mutation.op {
+		case dto.RecordMutationCreate:
+			resp = dto.FromRecordEntity(o.record)
+			summary.Created = append(summary.Created, resp.ID)
+			if s.hookService != nil {
+				s.hookService.TriggerRecordCreateHook(ctx, tableID, resp.ID, o.event.Fields)
+			}
+		case dto.RecordMutationUpdate:
+			resp = dto.FromRecordEntity(o.record)
+			summary.Updated = append(summary.Updated, resp.ID)
+		case dto.RecordMutationDelete:
+			summary.Deleted = append(summary.Deleted, o.mutation.id)
+		}
+		markMutationResult(results, o.mutation, resp, nil)
+		s.broadcastRecordEvent(s.maskRecordEvent(o.event))
+	}
+}
+
+// loadMutationTargets 一次读取块内所有待更新/删除的记录
+func (s *RecordService) loadMutationTargets(ctx context.Context, tableID string, chunk []*plannedMutation) (map[string]*entity.Record, error) {
+	var ids []valueobject.RecordID
+	for _, m := range chunk {
+		if m.id != "" {
+			ids = append(ids, valueobject.NewRecordID(m.id))
+		}
+	}
+	targets := make(map[string]*entity.Record, len(ids))
+	if len(ids) == 0 {
+		return targets, nil
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, ids)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找记录失败")
+	}
+	for _, record := range records {
+		targets[record.ID().String()] = record
+	}
+	return targets, nil
+}
+
+// applyMutation 执行单个变更（在事务内，失败由调用方回滚保存点）
+func (s *RecordService) applyMutation(ctx context.Context, tableID string, m *plannedMutation, existing *entity.Record, typecast bool, userID string) (*entity.Record, *database.RecordEvent, error) {
+	if m.op == dto.RecordMutationCreate {
+		return s.applyCreateMutation(ctx, tableID, m, typecast, userID)
+	}
+
+	if existing == nil {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+	if m.version != nil && existing.Version().Value() != int64(*m.version) {
+		return nil, nil, pkgerrors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"expected_version": *m.version,
+			"current_version":  existing.Version().Value(),
+		})
+	}
+
+	if m.op == dto.RecordMutationDelete {
+		if err := s.checkRecordLock(ctx, userID, tableID, m.id, nil); err != nil {
+			return nil, nil, err
+		}
+		if err := s.recordRepo.DeleteByTableAndID(ctx, tableID, existing.ID()); err != nil {
+			return nil, nil, pkgerrors.Database(err, "删除记录失败")
+		}
+		return existing, &database.RecordEvent{
+			EventType: "record.delete",
+			TID:       tableID,
+			RID:       m.id,
+			Fields:    existing.Data().ToMap(),
+		}, nil
+	}
+
+	fields, err := s.typecastMutationFields(ctx, tableID, m.fields, typecast)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkCrossBaseLinks(ctx, userID, tableID, fields); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkRecordLock(ctx, userID, tableID, m.id, fields); err != nil {
+		return nil, nil, err
+	}
+	if err := s.applyStatusTransitions(ctx, userID, tableID, m.id, existing.Data().ToMap(), fields); err != nil {
+		return nil, nil, err
+	}
+	changedFieldIDs := s.identifyChangedFields(existing.Data().ToMap(), fields)
+	newData, err := valueobject.NewRecordData(fields)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("记录数据无效: %v", err))
+	}
+	if err := existing.Update(newData, userID); err != nil {
+		return nil, nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("更新记录失败: %v", err))
+	}
+	if s.calculationService != nil && len(changedFieldIDs) > 0 {
+		if err := s.calculationService.CalculateAffectedFields(ctx, existing, changedFieldIDs); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.recordRepo.Save(ctx, existing); err != nil {
+		return nil, nil, pkgerrors.Database(err, "保存记录失败")
+	}
+	if err := s.aiRecordUpdated(ctx, tableID, m.id, changedFieldIDs, userID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.textExtractionRecordUpdated(ctx, tableID, m.id, changedFieldIDs, userID); err != nil {
+		return nil, nil, err
+	}
+	return existing, &database.RecordEvent{
+		EventType:  "record.update",
+		TID:        tableID,
+		RID:        m.id,
+		Fields:     existing.Data().ToMap(),
+		UserID:     userID,
+		OldVersion: existing.Version().Value() - 1,
+		NewVersion: existing.Version().Value(),
+	}, nil
+}
+
+func (s *RecordService) applyCreateMutation(ctx context.Context, tableID string, m *plannedMutation, typecast bool, userID string) (*entity.Record, *database.RecordEvent, error) {
+	fields, err := s.typecastMutationFields(ctx, tableID, m.fields, typecast)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkCrossBaseLinks(ctx, userID, tableID, fields); err != nil {
+		return nil, nil, err
+	}
+	if err := s.validateRequiredFields(ctx, tableID, fields); err != nil {
+		return nil, nil, err
+	}
+	recordData, err := valueobject.NewRecordData(fields)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("记录数据无效: %v", err))
+	}
+	record, err := entity.NewRecord(tableID, recordData, userID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建记录实体失败: %v", err))
+	}
+	if err := s.applyStatusTransitions(ctx, userID, tableID, record.ID().String(), nil, fields); err != nil {
+		return nil, nil, err
+	}
+	if err := s.recordRepo.Save(ctx, record); err != nil {
+		return nil, nil, pkgerrors.Database(err, "保存记录失败")
+	}
+	if s.calculationService != nil {
+		if err := s.calculationService.CalculateRecordFields(ctx, record); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := s.aiRecordCreated(ctx, tableID, record.ID().String(), record.Data().ToMap(), userID); err != nil {
+		return nil, nil, err
+	}
+	if err := s.textExtractionRecordCreated(ctx, tableID, record.ID().String(), record.Data().ToMap(), userID); err != nil {
+		return nil, nil, err
+	}
+	return record, &database.RecordEvent{
+		EventType: "record.create",
+		TID:       tableID,
+		RID:       record.ID().String(),
+		Fields:    record.Data().ToMap(),
+		UserID:    userID,
+	}, nil
+}
+
+// typecastMutationFields 校验字段值；typecast 为 true 时尝试自动转换类型
+func (s *RecordService) typecastMutationFields(ctx context.Context, tableID string, fields map[string]interface{}, typecast bool) (map[string]interface{}, error) {
+	if s.typecastService == nil {
+		return fields, nil
+	}
+	return s.typecastService.ValidateAndTypecastRecord(ctx, tableID, fields, typecast)
+}
+
+// planRecordMutations 校验操作并合并同一记录的变更，返回执行计划和预填的结果列表
+// 结构性错误（未知操作、缺少ID、与删除冲突等）直接标记为失败，不进入执行计划
+func planRecordMutations(ops []dto.RecordMutation) ([]*plannedMutation, []*dto.RecordMutationResult) {
+	results := make([]*dto.RecordMutationResult, len(ops))
+	var plan []*plannedMutation
+	updates := make(map[string]*plannedMutation)
+	deletes := make(map[string]*plannedMutation)
+
+	fail := func(i int, message string) {
+		results[i].Status = "failed"
+		results[i].Error = &dto.RecordMutationError{Code: pkgerrors.ErrValidationFailed.Code, Message: message}
+	}
+
+	for i, op := range ops {
+		results[i] = &dto.RecordMutationResult{Index: i, Op: op.Op, ID: op.ID}
+		switch op.Op {
+		case dto.RecordMutationCreate:
+			if op.ID != "" {
+				fail(i, "创建操作不能指定记录ID")
+				continue
+			}
+			if op.Fields == nil {
+				fail(i, "创建操作需要 fields")
+				continue
+			}
+			plan = append(plan, &plannedMutation{op: op.Op, fields: op.Fields, indexes: []int{i}})
+
+		case dto.RecordMutationUpdate:
+			if op.ID == "" || op.Fields == nil {
+				fail(i, "更新操作需要 id 和 fields")
+				continue
+			}
+			if _, ok := deletes[op.ID]; ok {
+				fail(i, "同一批次中该记录已被删除")
+				continue
+			}
+			if m, ok := updates[op.ID]; ok {
+				if op.Version != nil && m.version != nil && *op.Version != *m.version {
+					fail(i, "同一记录的多个更新指定了不同的版本号")
+					continue
+				}
+				for k, v := range op.Fields {
+					m.fields[k] = v
+				}
+				if m.version == nil {
+					m.version = op.Version
+				}
+				m.indexes = append(m.indexes, i)
+				continue
+			}
+			fields := make(map[string]interface{}, len(op.Fields))
+			for k, v := range op.Fields {
+				fields[k] = v
+			}
+			m := &plannedMutation{op: op.Op, id: op.ID, fields: fields, version: op.Version, indexes: []int{i}}
+			updates[op.ID] = m
+			plan = append(plan, m)
+
+		case dto.RecordMutationDelete:
+			if op.ID == "" {
+				fail(i, "删除操作需要 id")
+				continue
+			}
+			if _, ok := deletes[op.ID]; ok {
+				fail(i, "同一批次中重复删除该记录")
+				continue
+			}
+			if m, ok := updates[op.ID]; ok {
+				// 删除优先：此前合并的更新全部失败
+				for _, idx := range m.indexes {
+					fail(idx, "同一批次中该记录已被删除")
+				}
+				delete(updates, op.ID)
+				m.op, m.fields, m.version, m.indexes = op.Op, nil, op.Version, []int{i}
+				deletes[op.ID] = m
+				continue
+			}
+			m := &plannedMutation{op: op.Op, id: op.ID, version: op.Version, indexes: []int{i}}
+			deletes[op.ID] = m
+			plan = append(plan, m)
+
+		default:
+			fail(i, fmt.Sprintf("不支持的操作类型: %s（支持 create / update / delete）", op.Op))
+		}
+	}
+	return plan, results
+}
+
+// markMutationResult 将变更结果写入其全部来源操作
+func markMutationResult(results []*dto.RecordMutationResult, m *plannedMutation, record *dto.RecordResponse, err error) {
+	for _, idx := range m.indexes {
+		result := results[idx]
+		if err != nil {
+			appErr := pkgerrors.From(err)
+			result.Status = "failed"
+			result.Error = &dto.RecordMutationError{Code: appErr.Code, Message: appErr.Message, Details: appErr.Details}
+			continue
+		}
+		result.Status = "success"
+		result.Record = record
+		if record != nil {
+			result.ID = record.ID
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+)
+
+func TestPlanRecordMutations(t *testing.T) {
+	v1, v2 := 1, 2
+	ops := []dto.RecordMutation{
+		{Op: dto.RecordMutationCreate, Fields: map[string]interface{}{"fld_a": "x"}},
+		{Op: dto.RecordMutationUpdate, ID: "rec1", Fields: map[string]interface{}{"fld_a": "1", "fld_b": "1"}, Version: &v1},
+		{Op: dto.RecordMutationUpdate, ID: "rec1", Fields: map[string]interface{}{"fld_b": "2"}},
+		{Op: dto.RecordMutationUpdate, ID: "rec2", Fields: map[string]interface{}{"fld_a": "y"}},
+		{Op: dto.RecordMutationDelete, ID: "rec2"},
+		{Op: dto.RecordMutationDelete, ID: "rec2"},
+		{Op: dto.RecordMutationUpdate, ID: "rec1", Fields: map[string]interface{}{"fld_c": "3"}, Version: &v2},
+		{Op: "upsert", ID: "rec3"},
+		{Op: dto.RecordMutationCreate, ID: "rec4", Fields: map[string]interface{}{}},
+	}
+
+	plan, results := planRecordMutations(ops)
+	if len(results) != len(ops) {
+		t.Fatalf("每个操作都应有结果，得到 %d 个", len(results))
+	}
+	if len(plan) != 3 {
+		t.Fatalf("应合并为 create、rec1 更新、rec2 删除三个变更，得到 %d 个", len(plan))
+	}
+
+	update := plan[1]
+	if update.op != dto.RecordMutationUpdate || len(update.indexes) != 2 || update.fields["fld_b"] != "2" || *update.version != 1 {
+		t.Errorf("rec1 的两次更新应合并且后者字段覆盖前者，得到 %+v", update)
+	}
+	if ops[1].Fields["fld_b"] != "1" {
+		t.Error("合并不应修改请求中的字段")
+	}
+	if plan[2].op != dto.RecordMutationDelete || len(plan[2].indexes) != 1 || plan[2].indexes[0] != 4 {
+		t.Errorf("删除应取代此前对同一记录的更新，得到 %+v", plan[2])
+	}
+
+	for _, i := range []int{3, 5, 6, 7, 8} {
+		if results[i].Status != "failed" || results[i].Error == nil {
+			t.Errorf("操作 %d 应在规划阶段失败，得到 %+v", i, results[i])
+		}
+	}
+	for _, i := range []int{0, 1, 2, 4} {
+		if results[i].Status != "" {
+			t.Errorf("操作 %d 应等待执行，得到状态 %q", i, results[i].Status)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// 单元格历史分页默认值
+const (
+	defaultCellHistoryPageSize = 50
+	maxCellHistoryPageSize     = 200
+)
+
+// SetCellHistoryReader 设置单元格修改历史读取器
+func (s *RecordService) SetCellHistoryReader(reader recordRepo.CellHistoryReader) {
+	s.cellHistoryReader = reader
+}
+
+// GetCellHistory 获取单个单元格的修改历史 ✨
+// 按版本倒序分页，值按当前角色的脱敏策略处理
+func (s *RecordService) GetCellHistory(ctx context.Context, tableID, recordID, fieldID string, req dto.CellHistoryRequest) (*dto.CellHistoryResponse, error) {
+	if s.cellHistoryReader == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("当前服务未启用单元格历史")
+	}
+	field, err := s.fieldRepo.FindByID(ctx, fieldVO.NewFieldID(fieldID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	if field == nil || field.TableID() != tableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultCellHistoryPageSize
+	}
+	if limit > maxCellHistoryPageSize {
+		limit = maxCellHistoryPageSize
+	}
+	changes, err := s.cellHistoryReader.ListCellHistory(ctx, tableID, recordID, fieldID, req.Cursor, limit+1)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询单元格历史失败")
+	}
+
+	resp := &dto.CellHistoryResponse{RecordID: recordID, FieldID: fieldID, Entries: []*dto.CellHistoryEntry{}}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		next := changes[limit-1].Version
+		resp.NextCursor = &next
+	}
+
+	// 修改前后的值各作为一条只含该字段的记录脱敏
+	values := make([]*dto.RecordResponse, 0, len(changes)*2)
+	for _, change := range changes {
+		values = append(values,
+			&dto.RecordResponse{ID: recordID, TableID: tableID, Data: map[string]interface{}{fieldID: change.OldValue}},
+			&dto.RecordResponse{ID: recordID, TableID: tableID, Data: map[string]interface{}{fieldID: change.NewValue}})
+	}
+	s.maskResponses(ctx, tableID, values...)
+
+	richText := field.Type().String() == fieldVO.TypeRichText
+	for i, change := range changes {
+		entry := &dto.CellHistoryEntry{
+			Version:   change.Version,
+			OldValue:  values[2*i].Data[fieldID],
+			NewValue:  values[2*i+1].Data[fieldID],
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt,
+		}
+		if richText {
+			before, _ := entry.OldValue.(string)
+			after, _ := entry.NewValue.(string)
+			entry.Diff = richtext.Diff(before, after)
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+	return resp, nil
+}
+
+// StartCellHistoryCleanup 定期清理超出保留期的单元格修改历史（与变更日志使用相同的清理间隔）
+func (s *RecordService) StartCellHistoryCleanup(ctx context.Context) {
+	if s.cellHistoryReader == nil || s.snapshotConfig.CellHistoryRetention <= 0 || s.snapshotConfig.CleanupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.snapshotConfig.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			deleted, err := s.cellHistoryReader.PurgeCellHistory(ctx, time.Now().Add(-s.snapshotConfig.CellHistoryRetention))
+			if err != nil {
+				snapshotLog.Warn(ctx, "清理过期单元格历史失败", logger.ErrorField(err))
+				continue
+			}
+			if deleted > 0 {
+				snapshotLog.Debug(ctx, "已清理过期单元格历史", logger.Int64("count", deleted))
+			}
+		}
+	}()
+}
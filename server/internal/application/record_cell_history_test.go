@@ -0,0 +1,91 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+)
+
+// stubCellHistoryReader 按版本倒序保存单元格修改
+type stubCellHistoryReader struct {
+	changes []*recordRepo.CellChange
+}
+
+func (r *stubCellHistoryReader) ListCellHistory(_ context.Context, _, _, _ string, beforeVersion int64, limit int) ([]*recordRepo.CellChange, error) {
+	var list []*recordRepo.CellChange
+	for _, change := range r.changes {
+		if beforeVersion > 0 && change.Version >= beforeVersion {
+			continue
+		}
+		if len(list) == limit {
+			break
+		}
+		list = append(list, change)
+	}
+	return list, nil
+}
+
+func (r *stubCellHistoryReader) PurgeCellHistory(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// stubCellHistoryFieldRepo 按ID返回字段
+type stubCellHistoryFieldRepo struct {
+	fieldRepo.FieldRepository
+	field *fieldEntity.Field
+}
+
+func (r *stubCellHistoryFieldRepo) FindByID(_ context.Context, id fieldVO.FieldID) (*fieldEntity.Field, error) {
+	if r.field.ID().Equals(id) {
+		return r.field, nil
+	}
+	return nil, nil
+}
+
+func TestGetCellHistory(t *testing.T) {
+	field := newTemplateTestField(t, "备注", fieldVO.TypeRichText)
+	fieldID := field.ID().String()
+	reader := &stubCellHistoryReader{}
+	for v := int64(5); v >= 2; v-- {
+		reader.changes = append(reader.changes, &recordRepo.CellChange{
+			FieldID:   fieldID,
+			OldValue:  fmt.Sprintf("第 %d 版", v-1),
+			NewValue:  fmt.Sprintf("第 %d 版", v),
+			Version:   v,
+			ChangedBy: "usr1",
+		})
+	}
+	service := &RecordService{fieldRepo: &stubCellHistoryFieldRepo{field: field}}
+	service.SetCellHistoryReader(reader)
+	ctx := context.Background()
+
+	page, err := service.GetCellHistory(ctx, "tbl1", "rec1", fieldID, dto.CellHistoryRequest{Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 3 || page.Entries[0].Version != 5 || page.NextCursor == nil || *page.NextCursor != 3 {
+		t.Fatalf("第一页 = %+v", page)
+	}
+	if len(page.Entries[0].Diff) == 0 {
+		t.Error("富文本字段应返回差异")
+	}
+
+	page, err = service.GetCellHistory(ctx, "tbl1", "rec1", fieldID, dto.CellHistoryRequest{Cursor: *page.NextCursor, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].Version != 2 || page.NextCursor != nil {
+		t.Fatalf("最后一页 = %+v", page)
+	}
+
+	if _, err := service.GetCellHistory(ctx, "tbl2", "rec1", fieldID, dto.CellHistoryRequest{}); err == nil {
+		t.Error("字段不属于该表时应返回错误")
+	}
+}
@@ -0,0 +1,444 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+const (
+	// expandDefaultDepth 默认只展开请求记录自身的字段
+	expandDefaultDepth = 1
+	// expandMaxDepth 嵌套展开的最大层数
+	expandMaxDepth = 3
+	// expandMaxRecordsPerTable 每层每个表最多读取的关联记录数，超出部分保持为ID
+	expandMaxRecordsPerTable = 1000
+)
+
+// RecordExpandOptions 记录展开参数
+type RecordExpandOptions struct {
+	FieldIDs []string // 需要展开的字段；为空时展开全部关联、查找和用户字段
+	Depth    int      // 嵌套层数（1 表示只展开当前记录）
+}
+
+// RecordExpandService 记录展开服务：在服务端解析关联、查找和用户字段 ✨
+//
+// 同一层的所有关联记录按表合并为一次批量读取，用户在全部层级读取完成后一次查询；
+// 每个表在一条展开路径上只展开一次（A→B→A 不会继续展开），避免循环关联放大查询。
+type RecordExpandService struct {
+	recordRepo        recordRepo.RecordRepository
+	fieldRepo         repository.FieldRepository
+	userRepo          userRepo.UserRepository
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+}
+
+// NewRecordExpandService 创建记录展开服务
+func NewRecordExpandService(
+	recordRepo recordRepo.RecordRepository,
+	fieldRepo repository.FieldRepository,
+	userRepo userRepo.UserRepository,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+) *RecordExpandService {
+	return &RecordExpandService{
+		recordRepo:        recordRepo,
+		fieldRepo:         fieldRepo,
+		userRepo:          userRepo,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+	}
+}
+
+// expandNode 一层中某个表待展开的记录
+type expandNode struct {
+	tableID string
+	records []*dto.RecordResponse
+	fields  []*entity.Field
+	path    map[string]bool // 当前展开路径上已出现的表
+}
+
+// pendingUsers 等待填充的用户单元格
+type pendingUsers struct {
+	record  *dto.RecordResponse
+	fieldID string
+	ids     []string
+}
+
+// expandRun 一次展开请求的状态（字段、访问权限按表缓存）
+type expandRun struct {
+	userID string
+	fields map[string][]*entity.Field
+	access map[string]bool
+	users  []pendingUsers
+}
+
+// Expand 在记录响应上填充 Expanded（原地修改）
+func (s *RecordExpandService) Expand(ctx context.Context, userID, tableID string, records []*dto.RecordResponse, opts RecordExpandOptions) error {
+	if len(records) == 0 {
+		return nil
+	}
+	depth := opts.Depth
+	if depth <= 0 {
+		depth = expandDefaultDepth
+	}
+	if depth > expandMaxDepth {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("展开层数最多为 %d", expandMaxDepth))
+	}
+
+	run := &expandRun{
+		userID: userID,
+		fields: make(map[string][]*entity.Field),
+		access: map[string]bool{tableID: true}, // 调用方已校验当前表
+	}
+	fields, err := run.tableFields(ctx, s, tableID)
+	if err != nil {
+		return err
+	}
+	selected, err := selectExpandFields(fields, opts.FieldIDs)
+	if err != nil {
+		return err
+	}
+
+	level := []*expandNode{{tableID: tableID, records: records, fields: selected, path: map[string]bool{tableID: true}}}
+	for d := 1; d <= depth && len(level) > 0; d++ {
+		next, err := s.expandLevel(ctx, run, level, d < depth)
+		if err != nil {
+			return err
+		}
+		level = next
+	}
+	return s.fillUsers(ctx, run)
+}
+
+// expandLevel 展开一层：按表合并读取关联记录，并返回下一层待展开的节点
+func (s *RecordExpandService) expandLevel(ctx context.Context, run *expandRun, level []*expandNode, descend bool) ([]*expandNode, error) {
+	wanted := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	want := func(tableID string, ids []string) {
+		if seen[tableID] == nil {
+			seen[tableID] = make(map[string]bool)
+		}
+		for _, id := range ids {
+			if !seen[tableID][id] {
+				seen[tableID][id] = true
+				wanted[tableID] = append(wanted[tableID], id)
+			}
+		}
+	}
+
+	for _, node := range level {
+		byID := fieldIndex(run.fields[node.tableID])
+		for _, field := range node.fields {
+			if linkField, ok := expandLinkSource(field, byID); ok {
+				for _, record := range node.records {
+					want(linkField.Options().Link.LinkedTableID, extractLinkIDs(record.Data[linkField.ID().String()]))
+				}
+			}
+		}
+	}
+
+	fetched := make(map[string]map[string]*dto.RecordResponse, len(wanted))
+	for tableID, ids := range wanted {
+		if !run.canAccess(ctx, s, tableID) {
+			continue
+		}
+		if len(ids) > expandMaxRecordsPerTable {
+			logger.Warn("关联记录过多，只展开部分记录",
+				logger.String("table_id", tableID),
+				logger.Int("requested", len(ids)),
+				logger.Int("limit", expandMaxRecordsPerTable))
+			ids = ids[:expandMaxRecordsPerTable]
+		}
+		records, err := s.fetchRecords(ctx, tableID, ids)
+		if err != nil {
+			return nil, err
+		}
+		fetched[tableID] = records
+	}
+
+	nextByTable := make(map[string]*expandNode)
+	var next []*expandNode
+	for _, node := range level {
+		byID := fieldIndex(run.fields[node.tableID])
+		for _, field := range node.fields {
+			fieldID := field.ID().String()
+			if isUserField(field) {
+				for _, record := range node.records {
+					if ids := userIDsOf(field, record); len(ids) > 0 {
+						run.users = append(run.users, pendingUsers{record: record, fieldID: fieldID, ids: ids})
+					}
+				}
+				continue
+			}
+
+			linkField, ok := expandLinkSource(field, byID)
+			if !ok {
+				continue
+			}
+			linkedTableID := linkField.Options().Link.LinkedTableID
+			linked, ok := fetched[linkedTableID]
+			if !ok {
+				continue
+			}
+			for _, record := range node.records {
+				ids := extractLinkIDs(record.Data[linkField.ID().String()])
+				if field.Type().String() == fieldVO.TypeLookup {
+					setExpanded(record, fieldID, lookupValues(ids, linked, field.Options().Lookup.LookupFieldID))
+					continue
+				}
+				setExpanded(record, fieldID, linkedRecords(ids, linked))
+			}
+
+			// 只有关联字段的目标记录继续向下展开；同一路径上已出现的表不再展开
+			if !descend || field.Type().String() != fieldVO.TypeLink || node.path[linkedTableID] {
+				continue
+			}
+			child, ok := nextByTable[linkedTableID]
+			if !ok {
+				child = &expandNode{tableID: linkedTableID, path: map[string]bool{linkedTableID: true}}
+				nextByTable[linkedTableID] = child
+				next = append(next, child)
+			}
+			for tableID := range node.path {
+				child.path[tableID] = true
+			}
+		}
+	}
+
+	for _, child := range next {
+		fields, err := run.tableFields(ctx, s, child.tableID)
+		if err != nil {
+			return nil, err
+		}
+		child.fields, _ = selectExpandFields(fields, nil)
+		for _, record := range fetched[child.tableID] {
+			child.records = append(child.records, record)
+		}
+	}
+	return next, nil
+}
+
+// fetchRecords 批量读取记录并按当前用户脱敏
+func (s *RecordExpandService) fetchRecords(ctx context.Context, tableID string, ids []string) (map[string]*dto.RecordResponse, error) {
+	recordIDs := make([]valueobject.RecordID, len(ids))
+	for i, id := range ids {
+		recordIDs[i] = valueobject.NewRecordID(id)
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询关联记录失败")
+	}
+
+	responses := dto.FromRecordEntities(records)
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, responses...)
+	}
+	result := make(map[string]*dto.RecordResponse, len(responses))
+	for _, resp := range responses {
+		result[resp.ID] = resp
+	}
+	return result, nil
+}
+
+// fillUsers 一次查询全部用户并填充
+func (s *RecordExpandService) fillUsers(ctx context.Context, run *expandRun) error {
+	if len(run.users) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, p := range run.users {
+		for _, id := range p.ids {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	users, _, err := s.userRepo.List(ctx, userRepo.UserFilter{IDs: ids})
+	if err != nil {
+		return pkgerrors.Database(err, "查询用户失败")
+	}
+	byID := make(map[string]dto.ExpandedUser, len(users))
+	for _, u := range users {
+		byID[u.ID().String()] = dto.ExpandedUser{
+			ID:     u.ID().String(),
+			Name:   u.Name(),
+			Email:  u.Email().String(),
+			Avatar: u.Avatar(),
+		}
+	}
+
+	for _, p := range run.users {
+		expanded := make([]dto.ExpandedUser, 0, len(p.ids))
+		for _, id := range p.ids {
+			if u, ok := byID[id]; ok {
+				expanded = append(expanded, u)
+			}
+		}
+		setExpanded(p.record, p.fieldID, expanded)
+	}
+	return nil
+}
+
+func (r *expandRun) tableFields(ctx context.Context, s *RecordExpandService, tableID string) ([]*entity.Field, error) {
+	if fields, ok := r.fields[tableID]; ok {
+		return fields, nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	r.fields[tableID] = fields
+	return fields, nil
+}
+
+// canAccess 关联表可能属于其他 Base，没有访问权限时保留原始ID不展开
+func (r *expandRun) canAccess(ctx context.Context, s *RecordExpandService, tableID string) bool {
+	if allowed, ok := r.access[tableID]; ok {
+		return allowed
+	}
+	allowed := s.permissionService.CanAccessTable(ctx, r.userID, tableID)
+	r.access[tableID] = allowed
+	return allowed
+}
+
+// selectExpandFields 选出需要展开的字段；显式指定了不可展开的字段时报错
+func selectExpandFields(fields []*entity.Field, fieldIDs []string) ([]*entity.Field, error) {
+	byID := fieldIndex(fields)
+	if len(fieldIDs) == 0 {
+		selected := make([]*entity.Field, 0, len(fields))
+		for _, field := range fields {
+			if isExpandable(field, byID) {
+				selected = append(selected, field)
+			}
+		}
+		return selected, nil
+	}
+
+	selected := make([]*entity.Field, 0, len(fieldIDs))
+	for _, fieldID := range fieldIDs {
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("展开字段不存在: %s", fieldID))
+		}
+		if !isExpandable(field, byID) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(
+				fmt.Sprintf("字段 %s 不是关联、查找或用户字段，不能展开", field.Name().String()))
+		}
+		selected = append(selected, field)
+	}
+	return selected, nil
+}
+
+func isExpandable(field *entity.Field, byID map[string]*entity.Field) bool {
+	if isUserField(field) {
+		return true
+	}
+	_, ok := expandLinkSource(field, byID)
+	return ok
+}
+
+// expandLinkSource 返回提供关联记录ID的关联字段：关联字段返回自身，查找字段返回其依赖的关联字段
+func expandLinkSource(field *entity.Field, byID map[string]*entity.Field) (*entity.Field, bool) {
+	options := field.Options()
+	if options == nil {
+		return nil, false
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeLink:
+		if options.Link == nil || options.Link.LinkedTableID == "" {
+			return nil, false
+		}
+		return field, true
+	case fieldVO.TypeLookup:
+		if options.Lookup == nil {
+			return nil, false
+		}
+		linkField, ok := byID[options.Lookup.LinkFieldID]
+		if !ok || linkField.Type().String() != fieldVO.TypeLink {
+			return nil, false
+		}
+		return expandLinkSource(linkField, byID)
+	}
+	return nil, false
+}
+
+func isUserField(field *entity.Field) bool {
+	switch field.Type().String() {
+	case fieldVO.TypeUser, fieldVO.TypeCreatedBy, fieldVO.TypeLastModifiedBy:
+		return true
+	}
+	return false
+}
+
+// userIDsOf 用户单元格中的用户ID（创建人/修改人没有存储值时取记录元数据）
+func userIDsOf(field *entity.Field, record *dto.RecordResponse) []string {
+	if ids := extractLinkIDs(record.Data[field.ID().String()]); len(ids) > 0 {
+		return ids
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeCreatedBy:
+		if record.CreatedBy != "" {
+			return []string{record.CreatedBy}
+		}
+	case fieldVO.TypeLastModifiedBy:
+		if record.UpdatedBy != "" {
+			return []string{record.UpdatedBy}
+		}
+	}
+	return nil
+}
+
+// extractLinkIDs 从单元格值中提取ID（兼容 "id"、["id"]、{"id": ...}、[{"id": ...}] 及其 JSON 编码）
+func extractLinkIDs(value interface{}) []string {
+	return cellvalue.RefIDs(value)
+}
+
+// linkedRecords 按单元格中的顺序返回关联记录（已删除或超出读取上限的记录被跳过）
+func linkedRecords(ids []string, linked map[string]*dto.RecordResponse) []*dto.RecordResponse {
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := linked[id]; ok {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func lookupValues(ids []string, linked map[string]*dto.RecordResponse, lookupFieldID string) []dto.ExpandedLookupValue {
+	values := make([]dto.ExpandedLookupValue, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := linked[id]; ok {
+			values = append(values, dto.ExpandedLookupValue{RecordID: id, Value: record.Data[lookupFieldID]})
+		}
+	}
+	return values
+}
+
+func setExpanded(record *dto.RecordResponse, fieldID string, value interface{}) {
+	if record.Expanded == nil {
+		record.Expanded = make(map[string]interface{})
+	}
+	record.Expanded[fieldID] = value
+}
+
+func fieldIndex(fields []*entity.Field) map[string]*entity.Field {
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	return byID
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestExtractLinkIDs(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  []string
+	}{
+		{"rec1", []string{"rec1"}},
+		{"", nil},
+		{[]interface{}{"rec1", map[string]interface{}{"id": "rec2", "title": "B"}}, []string{"rec1", "rec2"}},
+		{map[string]interface{}{"id": "usr1"}, []string{"usr1"}},
+		{42.0, nil},
+	}
+	for _, c := range cases {
+		if got := extractLinkIDs(c.value); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("extractLinkIDs(%v) = %v，期望 %v", c.value, got, c.want)
+		}
+	}
+}
+
+func TestSelectExpandFields(t *testing.T) {
+	newField := func(fieldName, fieldType string, options *valueobject.FieldOptions) *entity.Field {
+		name, _ := valueobject.NewFieldName(fieldName)
+		ft, _ := valueobject.NewFieldType(fieldType)
+		field, err := entity.NewField("tbl1", name, ft, "usr1")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		if options != nil {
+			if err := field.UpdateOptions(options); err != nil {
+				t.Fatalf("设置字段选项失败: %v", err)
+			}
+		}
+		return field
+	}
+
+	link := newField("客户", valueobject.TypeLink, &valueobject.FieldOptions{
+		Link: &valueobject.LinkOptions{LinkedTableID: "tbl2", Relationship: "many_to_many"},
+	})
+	lookup := newField("客户邮箱", valueobject.TypeLookup, &valueobject.FieldOptions{
+		Lookup: &valueobject.LookupOptions{LinkFieldID: link.ID().String(), LookupFieldID: "fld_email"},
+	})
+	owner := newField("负责人", valueobject.TypeUser, nil)
+	title := newField("标题", valueobject.TypeText, nil)
+	fields := []*entity.Field{title, link, lookup, owner}
+
+	selected, err := selectExpandFields(fields, nil)
+	if err != nil || len(selected) != 3 {
+		t.Fatalf("默认应展开关联、查找和用户字段，得到 %d 个, %v", len(selected), err)
+	}
+	if source, ok := expandLinkSource(lookup, fieldIndex(fields)); !ok || source != link {
+		t.Errorf("查找字段应通过其关联字段读取记录")
+	}
+	if _, err := selectExpandFields(fields, []string{title.ID().String()}); err == nil {
+		t.Errorf("文本字段不能展开")
+	}
+	if _, err := selectExpandFields(fields, []string{"fld_missing"}); err == nil {
+		t.Errorf("不存在的字段应报错")
+	}
+}
+
+func TestLookupValuesKeepCellOrder(t *testing.T) {
+	linked := map[string]*dto.RecordResponse{
+		"rec1": {ID: "rec1", Data: map[string]interface{}{"fld_email": "a@example.com"}},
+		"rec2": {ID: "rec2", Data: map[string]interface{}{"fld_email": "b@example.com"}},
+	}
+	values := lookupValues([]string{"rec2", "rec_deleted", "rec1"}, linked, "fld_email")
+	if len(values) != 2 || values[0].RecordID != "rec2" || values[1].Value != "a@example.com" {
+		t.Errorf("查找值应按单元格顺序返回并跳过缺失记录，得到 %+v", values)
+	}
+}
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
+)
+
+// RecordFormatOptions 单元格展示文本参数
+type RecordFormatOptions struct {
+	TimeZone string // IANA 时区，为空时为请求用户的时区；字段开启统一时区时以字段为准
+	Locale   string // 语言标签，决定数字分隔符与默认日期格式，为空时为请求用户的语言
+}
+
+// FormatRecords 按字段的格式化选项为记录的每个非空单元格计算展示文本（Formatted，按字段ID），
+// 应在字段键转换（ApplyFieldKeyType）之前调用
+func (s *RecordService) FormatRecords(ctx context.Context, tableID string, records []*dto.RecordResponse, opts RecordFormatOptions) error {
+	if len(records) == 0 {
+		return nil
+	}
+	format := cellvalue.FormatOptions{Locale: opts.Locale, TimeZone: userlocale.Location(ctx)}
+	if format.Locale == "" {
+		format.Locale = userlocale.Language(ctx)
+	}
+	if opts.TimeZone != "" {
+		loc, err := time.LoadLocation(opts.TimeZone)
+		if err != nil {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的时区: %s", opts.TimeZone))
+		}
+		format.TimeZone = loc
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段列表失败")
+	}
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+		record.Formatted = make(map[string]string, len(record.Data))
+		for _, field := range fields {
+			value, ok := record.Data[field.ID().String()]
+			if !ok || value == nil {
+				continue
+			}
+			record.Formatted[field.ID().String()] = cellvalue.Format(field.Type().String(), field.Options(), value, format)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+func TestFormatRecords(t *testing.T) {
+	amount := newTemplateTestField(t, "Amount", fieldVO.TypeCurrency)
+	due := newTemplateTestField(t, "Due", fieldVO.TypeDateTime)
+	svc := &RecordService{fieldRepo: &stubLockFieldRepo{fields: []*fieldEntity.Field{amount, due}}}
+	ctx := context.Background()
+
+	record := &dto.RecordResponse{Data: map[string]interface{}{
+		amount.ID().String(): 12.5,
+		due.ID().String():    "2024-03-01T18:30:00Z",
+	}}
+	opts := RecordFormatOptions{TimeZone: "Asia/Shanghai", Locale: "zh-CN"}
+	if err := svc.FormatRecords(ctx, "tbl1", []*dto.RecordResponse{record}, opts); err != nil {
+		t.Fatalf("FormatRecords: %v", err)
+	}
+	if got := record.Formatted[amount.ID().String()]; got != "$12.50" {
+		t.Errorf("货币展示文本错误: %q", got)
+	}
+	if got := record.Formatted[due.ID().String()]; got != "2024-03-02 02:30" {
+		t.Errorf("日期应按请求时区展示: %q", got)
+	}
+	if record.Data[amount.ID().String()] != 12.5 {
+		t.Errorf("原始值不应被修改: %v", record.Data)
+	}
+
+	if err := svc.ApplyFieldKeyType(ctx, "tbl1", FieldKeyTypeName, record); err != nil || record.Formatted["Amount"] != "$12.50" {
+		t.Errorf("展示文本应随字段键转换，得到 %v", record.Formatted)
+	}
+
+	err := svc.FormatRecords(ctx, "tbl1", []*dto.RecordResponse{record}, RecordFormatOptions{TimeZone: "Mars/Base"})
+	var appErr *pkgerrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != pkgerrors.ErrValidationFailed.Code {
+		t.Errorf("无效时区应返回校验错误，得到 %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
@@ -161,6 +162,7 @@ func (s *RecordHistoryService) GetRecordHistory(
 			FieldID:     h.FieldID,
 			Before:      s.stateToJSON(h.Before),
 			After:       s.stateToJSON(h.After),
+			Diff:        s.richTextDiff(h.Before, h.After),
 			CreatedTime: h.CreatedTime,
 			CreatedBy:   h.CreatedBy,
 		}
@@ -169,6 +171,23 @@ func (s *RecordHistoryService) GetRecordHistory(
 	return responses, nil
 }
 
+// richTextDiff 富文本字段返回前后版本的逐词差异，其他类型返回 nil
+func (s *RecordHistoryService) richTextDiff(before, after *models.RecordHistoryState) []richtext.DiffOp {
+	var fieldType, beforeHTML, afterHTML string
+	if before != nil {
+		fieldType = before.Meta.Type
+		beforeHTML, _ = before.Data.(string)
+	}
+	if after != nil {
+		fieldType = after.Meta.Type
+		afterHTML, _ = after.Data.(string)
+	}
+	if fieldType != valueobject.TypeRichText {
+		return nil
+	}
+	return richtext.Diff(beforeHTML, afterHTML)
+}
+
 // stateToJSON 将状态转换为JSON
 func (s *RecordHistoryService) stateToJSON(state *models.RecordHistoryState) interface{} {
 	if state == nil {
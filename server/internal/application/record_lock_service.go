@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordlock"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var recordLockLog = logger.Named("record_lock")
+
+// maxLockRecordIDs 一次查询锁的记录数上限
+const maxLockRecordIDs = 500
+
+// LockRecordRequest 锁定记录请求
+type LockRecordRequest struct {
+	FieldIDs   []string `json:"fieldIds"`   // 锁定的字段，为空时锁定整条记录
+	Reason     string   `json:"reason"`     // 锁定原因（展示给其他协作者）
+	TTLSeconds int      `json:"ttlSeconds"` // 有效期（秒），为 0 时在解锁前一直有效
+}
+
+// recordLockEvent 记录锁变更的实时事件数据
+type recordLockEvent struct {
+	Action string           `json:"action"` // locked / unlocked
+	Lock   *recordlock.Lock `json:"lock"`
+}
+
+// RecordLockService 记录编辑锁服务 ✨
+// 用户或自动化（使用服务令牌调用 API）可以锁定整条记录或其中部分字段，锁定期间：
+//   - RecordService 的单条/批量更新与删除拒绝其他用户修改被锁定的内容（返回 RECORD_LOCKED），
+//     整条记录或部分字段被锁定时其他用户都不能删除该记录；同步表的同步写入不受限制
+//   - 加锁、解锁通过实时事件（record.lock）推送到表与记录频道，表格据此展示锁的持有者
+//
+// 加锁需要表的记录编辑权限，不同用户的锁范围不能重叠；持有者或表结构管理者可以解锁
+type RecordLockService struct {
+	repo              recordlock.Repository
+	recordRepo        recordRepo.RecordRepository
+	fieldRepo         repository.FieldRepository
+	permissionService *PermissionServiceV2
+	businessEvents    events.BusinessEventPublisher
+	now               func() time.Time
+}
+
+// NewRecordLockService 创建记录锁服务
+func NewRecordLockService(
+	repo recordlock.Repository,
+	recordRepo recordRepo.RecordRepository,
+	fieldRepo repository.FieldRepository,
+	permissionService *PermissionServiceV2,
+	businessEvents events.BusinessEventPublisher,
+) *RecordLockService {
+	return &RecordLockService{
+		repo:              repo,
+		recordRepo:        recordRepo,
+		fieldRepo:         fieldRepo,
+		permissionService: permissionService,
+		businessEvents:    businessEvents,
+		now:               time.Now,
+	}
+}
+
+// LockRecord 锁定记录（重复锁定时更新自己的锁）
+func (s *RecordLockService) LockRecord(ctx context.Context, userID, tableID, recordID string, req LockRecordRequest) (*recordlock.Lock, error) {
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有编辑该表格记录的权限")
+	}
+	if req.TTLSeconds < 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("ttlSeconds 不能为负数")
+	}
+
+	now := s.now()
+	var expiresAt *time.Time
+	if req.TTLSeconds > 0 {
+		t := now.Add(time.Duration(req.TTLSeconds) * time.Second)
+		expiresAt = &t
+	}
+	lock := recordlock.NewLock(tableID, recordID, req.FieldIDs, userID, req.Reason, expiresAt, now)
+	if err := lock.Validate(now); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.checkFields(ctx, tableID, lock.FieldIDs); err != nil {
+		return nil, err
+	}
+
+	record, err := s.recordRepo.FindByTableAndID(ctx, tableID, valueobject.NewRecordID(recordID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找记录失败")
+	}
+	if record == nil {
+		return nil, pkgerrors.ErrRecordNotFound.WithDetails(map[string]interface{}{"record_id": recordID})
+	}
+
+	existing, err := s.repo.ListActive(ctx, tableID, []string{recordID}, now)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录锁失败")
+	}
+	for _, other := range existing {
+		if other.OwnerID == userID {
+			lock.ID = other.ID
+			continue
+		}
+		if other.Overlaps(lock) {
+			return nil, lockedError(other)
+		}
+	}
+
+	if err := s.repo.Save(ctx, lock); err != nil {
+		return nil, pkgerrors.Database(err, "保存记录锁失败")
+	}
+	s.publish(ctx, "locked", lock, userID)
+	return lock, nil
+}
+
+// UnlockRecord 解锁（持有者或表结构管理者）
+func (s *RecordLockService) UnlockRecord(ctx context.Context, userID, tableID, lockID string) error {
+	lock, err := s.repo.FindByID(ctx, lockID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询记录锁失败")
+	}
+	if lock == nil || lock.TableID != tableID {
+		return pkgerrors.ErrNotFound.WithDetails("记录锁不存在")
+	}
+	if lock.OwnerID != userID && !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("只有锁的持有者或表格管理者可以解锁")
+	}
+	if err := s.repo.Delete(ctx, lockID); err != nil {
+		return pkgerrors.Database(err, "删除记录锁失败")
+	}
+	s.publish(ctx, "unlocked", lock, userID)
+	return nil
+}
+
+// ListLocks 列出表中仍有效的锁；recordIDs 为空时列出整个表
+func (s *RecordLockService) ListLocks(ctx context.Context, userID, tableID string, recordIDs []string) ([]*recordlock.Lock, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	if len(recordIDs) > maxLockRecordIDs {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多查询 %d 条记录的锁", maxLockRecordIDs))
+	}
+	locks, err := s.repo.ListActive(ctx, tableID, recordIDs, s.now())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录锁失败")
+	}
+	return locks, nil
+}
+
+// CheckWrite 检查 userID 能否写入记录；data 为写入的字段（键为字段ID或名称），为 nil 表示删除记录
+func (s *RecordLockService) CheckWrite(ctx context.Context, userID, tableID, recordID string, data map[string]interface{}) error {
+	locks, err := s.repo.ListActive(ctx, tableID, []string{recordID}, s.now())
+	if err != nil {
+		return pkgerrors.Database(err, "查询记录锁失败")
+	}
+	if len(locks) == 0 {
+		return nil
+	}
+
+	var fieldIDs []string
+	if data != nil {
+		if fieldIDs, err = s.resolveFieldIDs(ctx, tableID, data); err != nil {
+			return err
+		}
+	}
+	for _, lock := range locks {
+		if lock.Blocks(userID, fieldIDs, s.now()) {
+			return lockedError(lock)
+		}
+	}
+	return nil
+}
+
+// resolveFieldIDs 将写入数据的键（字段ID或名称）转换为字段ID
+func (s *RecordLockService) resolveFieldIDs(ctx context.Context, tableID string, data map[string]interface{}) ([]string, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byName := make(map[string]string, len(fields))
+	for _, field := range fields {
+		byName[field.Name().String()] = field.ID().String()
+	}
+	fieldIDs := make([]string, 0, len(data))
+	for key := range data {
+		if id, ok := byName[key]; ok {
+			fieldIDs = append(fieldIDs, id)
+			continue
+		}
+		fieldIDs = append(fieldIDs, key)
+	}
+	return fieldIDs, nil
+}
+
+// checkFields 锁定的字段必须属于该表
+func (s *RecordLockService) checkFields(ctx context.Context, tableID string, fieldIDs []string) error {
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段失败")
+	}
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.ID().String()] = true
+	}
+	for _, id := range fieldIDs {
+		if !known[id] {
+			return pkgerrors.ErrFieldNotFound.WithDetails(map[string]interface{}{"field_id": id})
+		}
+	}
+	return nil
+}
+
+// publish 推送锁变更的实时事件（失败只记录日志）
+func (s *RecordLockService) publish(ctx context.Context, action string, lock *recordlock.Lock, userID string) {
+	if s.businessEvents == nil {
+		return
+	}
+	data := &recordLockEvent{Action: action, Lock: lock}
+	if err := s.businessEvents.PublishRecordEvent(ctx, events.BusinessEventTypeRecordLock, lock.TableID, lock.RecordID, data, userID, 0); err != nil {
+		recordLockLog.Warn(ctx, "发布记录锁事件失败",
+			logger.String("lock_id", lock.ID),
+			logger.ErrorField(err))
+	}
+}
+
+// lockedError 记录被锁定的错误，附带锁的持有者与范围
+func lockedError(lock *recordlock.Lock) error {
+	details := map[string]interface{}{
+		"lock_id":   lock.ID,
+		"record_id": lock.RecordID,
+		"owner_id":  lock.OwnerID,
+		"field_ids": lock.FieldIDs,
+	}
+	if lock.Reason != "" {
+		details["reason"] = lock.Reason
+	}
+	if lock.ExpiresAt != nil {
+		details["expires_at"] = lock.ExpiresAt
+	}
+	return pkgerrors.ErrRecordLocked.WithDetails(details)
+}
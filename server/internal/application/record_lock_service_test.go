@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordlock"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryRecordLockRepo 内存实现的记录锁仓储
+type memoryRecordLockRepo struct {
+	locks map[string]*recordlock.Lock
+}
+
+func (r *memoryRecordLockRepo) Save(_ context.Context, lock *recordlock.Lock) error {
+	r.locks[lock.ID] = lock
+	return nil
+}
+
+func (r *memoryRecordLockRepo) FindByID(_ context.Context, id string) (*recordlock.Lock, error) {
+	return r.locks[id], nil
+}
+
+func (r *memoryRecordLockRepo) ListActive(_ context.Context, tableID string, recordIDs []string, now time.Time) ([]*recordlock.Lock, error) {
+	var list []*recordlock.Lock
+	for _, lock := range r.locks {
+		if lock.TableID != tableID || !lock.Active(now) {
+			continue
+		}
+		for _, id := range recordIDs {
+			if id == lock.RecordID {
+				list = append(list, lock)
+			}
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryRecordLockRepo) Delete(_ context.Context, id string) error {
+	delete(r.locks, id)
+	return nil
+}
+
+type stubLockFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields []*fieldEntity.Field
+}
+
+func (r *stubLockFieldRepo) FindByTableID(context.Context, string) ([]*fieldEntity.Field, error) {
+	return r.fields, nil
+}
+
+func TestRecordLockCheckWrite(t *testing.T) {
+	amount := newTemplateTestField(t, "金额", fieldVO.TypeNumber)
+	note := newTemplateTestField(t, "备注", fieldVO.TypeText)
+	now := time.Now()
+	repo := &memoryRecordLockRepo{locks: map[string]*recordlock.Lock{}}
+	fieldLock := recordlock.NewLock("tbl1", "rec1", []string{amount.ID().String()}, "usr1", "财务复核中", nil, now)
+	wholeLock := recordlock.NewLock("tbl1", "rec2", nil, "usr1", "", nil, now)
+	repo.locks[fieldLock.ID] = fieldLock
+	repo.locks[wholeLock.ID] = wholeLock
+
+	svc := NewRecordLockService(repo, nil, &stubLockFieldRepo{fields: []*fieldEntity.Field{amount, note}}, nil, nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		user     string
+		recordID string
+		data     map[string]interface{}
+		locked   bool
+	}{
+		{"按字段名写入锁定字段", "usr2", "rec1", map[string]interface{}{"金额": 1}, true},
+		{"按字段ID写入锁定字段", "usr2", "rec1", map[string]interface{}{amount.ID().String(): 1}, true},
+		{"写入未锁定字段", "usr2", "rec1", map[string]interface{}{"备注": "x"}, false},
+		{"删除部分字段被锁定的记录", "usr2", "rec1", nil, true},
+		{"持有者写入", "usr1", "rec1", map[string]interface{}{"金额": 1}, false},
+		{"整条记录被锁定", "usr2", "rec2", map[string]interface{}{"备注": "x"}, true},
+		{"未锁定的记录", "usr2", "rec3", nil, false},
+	}
+	for _, c := range cases {
+		err := svc.CheckWrite(ctx, c.user, "tbl1", c.recordID, c.data)
+		if got := err != nil; got != c.locked {
+			t.Errorf("%s: err = %v", c.name, err)
+			continue
+		}
+		if err != nil && !pkgerrors.Is(err, pkgerrors.ErrRecordLocked) {
+			t.Errorf("%s: 错误类型 = %v", c.name, err)
+		}
+	}
+
+	// 过期的锁不再生效
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	expires := now.Add(time.Minute)
+	fieldLock.ExpiresAt = &expires
+	if err := svc.CheckWrite(ctx, "usr2", "tbl1", "rec1", map[string]interface{}{"金额": 1}); err != nil {
+		t.Errorf("过期的锁不应阻止写入: %v", err)
+	}
+}
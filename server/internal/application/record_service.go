@@ -3,8 +3,10 @@ package application
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
@@ -13,10 +15,12 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/events"
 	infraRepository "github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/sharedb"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	"github.com/easyspace-ai/luckdb/server/pkg/database"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 	"github.com/easyspace-ai/luckdb/server/pkg/sharedb/opbuilder"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -48,7 +52,24 @@ type RecordService struct {
 	typecastService    *TypecastService              // ✅ Phase 2: 类型转换和验证
 	hookService        *HookService                  // ✨ 钩子服务
 	shareDBService     *sharedb.ShareDBService       // ✨ ShareDB 实时协作服务
-	logger             *zap.Logger                   // ✨ 日志记录器
+	privacyService     *PrivacyService               // ✨ 字段脱敏
+	expandService      *RecordExpandService          // ✨ 关联记录展开
+	snapshotReader     recordRepo.SnapshotReader     // ✨ 按时间点读取表
+	cellHistoryReader  recordRepo.CellHistoryReader  // ✨ 单元格修改历史
+	archiveStore       recordRepo.ArchiveStore       // ✨ 记录归档（冷存储）
+	authorizer         *BatchAuthorizationService    // ✨ 批量变更的权限校验
+	snapshotConfig     config.RecordSnapshotConfig
+	changeFeed         *ChangeFeedService     // ✨ 变更流发件箱
+	aiFields           *AIFieldService        // ✨ AI 字段生成
+	textExtraction     *TextExtractionService // ✨ 附件文本提取
+	crossBaseLinks     *CrossBaseLinkService  // ✨ 跨 Base 关联（写入校验与事件传播）
+	syncedTables       *SyncedTableService    // ✨ 同步表（镜像表只读）
+	recordLocks        *RecordLockService     // ✨ 记录编辑锁
+	statusFlows        *StatusFlowService     // ✨ 状态流（审批流）
+	plans              *PlanService           // ✨ 套餐限额（每个 Base 的记录数）
+	maintenance        *MaintenanceService    // ✨ 维护模式（实例、工作空间或 Base 只读）
+	backlinks          *BacklinkService       // ✨ 记录提及的反向链接
+	logger             *zap.Logger            // ✨ 日志记录器
 }
 
 // Broadcaster WebSocket广播器接口
@@ -87,6 +108,31 @@ func (s *RecordService) SetBroadcaster(broadcaster Broadcaster) {
 	s.broadcaster = broadcaster
 }
 
+// SetPrivacyService 设置隐私服务（启用字段脱敏）
+func (s *RecordService) SetPrivacyService(privacyService *PrivacyService) {
+	s.privacyService = privacyService
+}
+
+// maskResponses 按脱敏策略处理返回给调用者的记录
+func (s *RecordService) maskResponses(ctx context.Context, tableID string, records ...*dto.RecordResponse) {
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, tableID, records...)
+	}
+}
+
+// SetExpandService 设置记录展开服务（启用 expand 参数）
+func (s *RecordService) SetExpandService(expandService *RecordExpandService) {
+	s.expandService = expandService
+}
+
+// ExpandRecords 在记录响应上展开关联、查找和用户字段（原地修改）
+func (s *RecordService) ExpandRecords(ctx context.Context, userID, tableID string, records []*dto.RecordResponse, opts RecordExpandOptions) error {
+	if s.expandService == nil {
+		return pkgerrors.ErrBadRequest.WithDetails("当前服务未启用记录展开")
+	}
+	return s.expandService.Expand(ctx, userID, tableID, records, opts)
+}
+
 // SetHookService 设置钩子服务（用于延迟注入）
 func (s *RecordService) SetHookService(hookService *HookService) {
 	s.hookService = hookService
@@ -110,6 +156,13 @@ func (s *RecordService) getDBFromRecordRepo() (*gorm.DB, error) {
 	return nil, fmt.Errorf("不支持的 RecordRepository 类型")
 }
 
+// recordSpanAttributes 记录操作跨度的公共属性
+func recordSpanAttributes(tableID, recordID string) tracing.StartOption {
+	return tracing.WithAttributes(
+		tracing.String("luckdb.table_id", tableID),
+		tracing.String("luckdb.record_id", recordID))
+}
+
 // CreateRecord 创建记录（集成自动计算）✨ 事务版
 //
 // 执行流程：
@@ -124,17 +177,29 @@ func (s *RecordService) getDBFromRecordRepo() (*gorm.DB, error) {
 //   - 所有操作在单个事务中（原子性）
 //   - 计算失败回滚整个事务
 //   - 事务成功后才发布 WebSocket 事件
-func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error) {
+func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (_ *dto.RecordResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RecordService.CreateRecord", tracing.WithAttributes(tracing.String("luckdb.table_id", req.TableID)))
+	defer func() { span.Finish(err) }()
+
 	// ✅ 在事务前检查表是否存在
 	table, err := s.tableRepo.GetByID(ctx, req.TableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{
 			"table_id": req.TableID,
 		})
 	}
+	if err := s.checkWritable(ctx, req.TableID); err != nil {
+		return nil, err
+	}
+	if err := s.checkPlanLimit(ctx, req.TableID, 1); err != nil {
+		return nil, err
+	}
+	if err := s.checkCrossBaseLinks(ctx, userID, req.TableID, req.Data); err != nil {
+		return nil, err
+	}
 
 	var record *entity.Record
 	var finalFields map[string]interface{}
@@ -176,10 +241,13 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 		if err != nil {
 			return pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建记录实体失败: %v", err))
 		}
+		if err := s.applyStatusTransitions(txCtx, userID, req.TableID, record.ID().String(), nil, validatedData); err != nil {
+			return err
+		}
 
 		// 5. 保存记录（在事务中）
 		if err := s.recordRepo.Save(txCtx, record); err != nil {
-			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
+			return pkgerrors.Database(err, "保存记录失败")
 		}
 
 		logger.Info("记录创建成功（事务中）",
@@ -208,6 +276,15 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 			UserID:    userID,
 		}
 		database.AddEventToTx(txCtx, event)
+		if err := s.recordChangeFeed(txCtx, event); err != nil {
+			return err
+		}
+		if err := s.aiRecordCreated(txCtx, req.TableID, record.ID().String(), finalFields, userID); err != nil {
+			return err
+		}
+		if err := s.textExtractionRecordCreated(txCtx, req.TableID, record.ID().String(), finalFields, userID); err != nil {
+			return err
+		}
 
 		// 8. ✨ 添加事务提交后回调（发布 WebSocket 事件）
 		database.AddTxCallback(txCtx, func() {
@@ -220,7 +297,7 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 	if err != nil {
 		logger.Error("记录创建失败",
 			logger.String("table_id", req.TableID),
-			logger.Any("data", req.Data),
+			logger.Int("field_count", len(req.Data)),
 			logger.ErrorField(err))
 		return nil, err
 	}
@@ -233,22 +310,43 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 		s.hookService.TriggerRecordCreateHook(ctx, req.TableID, record.ID().String(), finalFields)
 	}
 
-	return dto.FromRecordEntity(record), nil
+	resp := dto.FromRecordEntity(record)
+	s.maskResponses(ctx, req.TableID, resp)
+	return resp, nil
 }
 
 // GetRecord 获取记录详情
 func (s *RecordService) GetRecord(ctx context.Context, tableID, recordID string) (*dto.RecordResponse, error) {
+	return s.GetRecordWithFields(ctx, tableID, recordID, nil)
+}
+
+// GetRecordWithFields 获取记录，fieldIDs 非空时只读取并返回这些字段（字段投影）
+func (s *RecordService) GetRecordWithFields(ctx context.Context, tableID, recordID string, fieldIDs []string) (_ *dto.RecordResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RecordService.GetRecord", recordSpanAttributes(tableID, recordID))
+	defer func() { span.Finish(err) }()
+
 	id := valueobject.NewRecordID(recordID)
 
-	record, err := s.recordRepo.FindByTableAndID(ctx, tableID, id)
+	// 不投影时经缓存读取整条记录
+	var record *entity.Record
+	if len(fieldIDs) == 0 {
+		record, err = s.recordRepo.FindByTableAndID(ctx, tableID, id)
+	} else {
+		var records []*entity.Record
+		if records, err = s.recordRepo.FindByIDs(ctx, tableID, []valueobject.RecordID{id}, fieldIDs...); err == nil && len(records) > 0 {
+			record = records[0]
+		}
+	}
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找记录失败")
 	}
 	if record == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("记录不存在")
 	}
 
-	return dto.FromRecordEntity(record), nil
+	resp := dto.FromRecordEntity(record)
+	s.maskResponses(ctx, tableID, resp)
+	return resp, nil
 }
 
 // UpdateRecord 更新记录（集成智能重算）✨ 事务版
@@ -266,7 +364,10 @@ func (s *RecordService) GetRecord(ctx context.Context, tableID, recordID string)
 //   - 所有操作在单个事务中（原子性）
 //   - 计算失败回滚整个事务
 //   - 事务成功后才发布 WebSocket 事件
-func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error) {
+func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (_ *dto.RecordResponse, err error) {
+	ctx, span := tracing.Start(ctx, "RecordService.UpdateRecord", recordSpanAttributes(tableID, recordID))
+	defer func() { span.Finish(err) }()
+
 	// 处理 Teable 格式的请求
 	var updateData map[string]interface{}
 	var version *int
@@ -291,13 +392,22 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 	// ✅ 在事务前检查表是否存在
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{
 			"table_id": tableID,
 		})
 	}
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return nil, err
+	}
+	if err := s.checkCrossBaseLinks(ctx, userID, tableID, updateData); err != nil {
+		return nil, err
+	}
+	if err := s.checkRecordLock(ctx, userID, tableID, recordID, updateData); err != nil {
+		return nil, err
+	}
 
 	var record *entity.Record
 	var finalFields map[string]interface{}
@@ -315,7 +425,7 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 		var err error
 		records, err := s.recordRepo.FindByIDs(txCtx, tableID, []valueobject.RecordID{id})
 		if err != nil {
-			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+			return pkgerrors.Database(err, "查找记录失败")
 		}
 		if len(records) == 0 {
 			return pkgerrors.ErrNotFound.WithDetails("记录不存在")
@@ -331,7 +441,8 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 				})
 			}
 			if record.HasChangedSince(expectedVersion) {
-				return pkgerrors.ErrConflict.WithMessage("记录已被其他用户修改，请刷新后重试").WithDetails(map[string]interface{}{
+				s.invalidateCachedRecord(ctx, tableID, recordID)
+				return pkgerrors.ErrVersionConflict.WithDetails(map[string]interface{}{
 					"expected_version": *version,
 					"current_version":  record.Version().Value(),
 				})
@@ -341,6 +452,9 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 		// 3. 识别变化的字段（用于智能重算）
 		oldData := record.Data().ToMap()
 		changedFieldIDs := s.identifyChangedFields(oldData, updateData)
+		if err := s.applyStatusTransitions(txCtx, userID, tableID, recordID, oldData, updateData); err != nil {
+			return err
+		}
 
 		// 4. 创建新数据
 		newData, err := valueobject.NewRecordData(updateData)
@@ -370,7 +484,7 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 		// 7. 保存（在事务中，包含计算后的字段）
 		// 注意：record.Update()已经递增了版本，但Save会用旧版本做乐观锁检查
 		if err := s.recordRepo.Save(txCtx, record); err != nil {
-			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
+			return pkgerrors.Database(err, "保存记录失败")
 		}
 
 		logger.Info("记录更新成功（事务中）", logger.String("record_id", recordID))
@@ -387,6 +501,15 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 			NewVersion: record.Version().Value(),
 		}
 		database.AddEventToTx(txCtx, event)
+		if err := s.recordChangeFeed(txCtx, event); err != nil {
+			return err
+		}
+		if err := s.aiRecordUpdated(txCtx, tableID, recordID, changedFieldIDs, userID); err != nil {
+			return err
+		}
+		if err := s.textExtractionRecordUpdated(txCtx, tableID, recordID, changedFieldIDs, userID); err != nil {
+			return err
+		}
 
 		// 9. ✨ 添加事务提交后回调（发布 WebSocket 事件）
 		database.AddTxCallback(txCtx, func() {
@@ -403,7 +526,9 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 	logger.Info("记录更新完成，事件将在事务提交后发布",
 		logger.String("record_id", recordID))
 
-	return dto.FromRecordEntity(record), nil
+	resp := dto.FromRecordEntity(record)
+	s.maskResponses(ctx, tableID, resp)
+	return resp, nil
 }
 
 // validateRequiredFields 验证必填字段
@@ -412,39 +537,16 @@ func (s *RecordService) validateRequiredFields(ctx context.Context, tableID stri
 	// 1. 获取表的所有字段
 	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+		return pkgerrors.Database(err, "获取字段列表失败")
 	}
 
-	// 2. 检查每个必填字段
+	// 2. 检查每个必填字段（计算字段只读，不需要用户提供）
 	missingFields := make([]map[string]string, 0)
-	for _, field := range fields {
-		// 跳过计算字段（只读，不需要用户提供）
-		if field.IsComputed() {
-			continue
-		}
-
-		// 检查是否为必填字段
-		if !field.IsRequired() {
-			continue
-		}
-
-		fieldID := field.ID().String()
-		fieldName := field.Name().String()
-
-		// 检查字段是否在数据中
-		value, exists := data[fieldID]
-		if !exists {
-			// 尝试通过字段名查找
-			value, exists = data[fieldName]
-		}
-
-		// 检查值是否为空
-		if !exists || value == nil || value == "" {
-			missingFields = append(missingFields, map[string]string{
-				"id":   fieldID,
-				"name": fieldName,
-			})
-		}
+	for _, field := range newCellValidator(nil, tableID, fields).missingRequired(data) {
+		missingFields = append(missingFields, map[string]string{
+			"id":   field.ID().String(),
+			"name": field.Name().String(),
+		})
 	}
 
 	if len(missingFields) > 0 {
@@ -484,22 +586,55 @@ func (s *RecordService) isValueEqual(a, b interface{}) bool {
 // DeleteRecord 删除记录 ✨ 事务版
 // ✅ 对齐 Teable：所有记录操作都需要 tableID
 func (s *RecordService) DeleteRecord(ctx context.Context, tableID, recordID string) error {
+	return s.DeleteRecordWithVersion(ctx, tableID, recordID, nil)
+}
+
+// DeleteRecordWithVersion 删除记录；expectedVersion 非空时仅在记录版本一致时删除（乐观锁）
+func (s *RecordService) DeleteRecordWithVersion(ctx context.Context, tableID, recordID string, expectedVersion *int) (err error) {
+	ctx, span := tracing.Start(ctx, "RecordService.DeleteRecord", recordSpanAttributes(tableID, recordID))
+	defer func() { span.Finish(err) }()
+
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return err
+	}
+	userID, _ := authctx.UserFrom(ctx)
+	if err := s.checkRecordLock(ctx, userID, tableID, recordID, nil); err != nil {
+		return err
+	}
+
 	// ✅ 在事务中执行所有操作
-	err := database.Transaction(ctx, s.recordRepo.(*infraRepository.RecordRepositoryDynamic).GetDB(), nil, func(txCtx context.Context) error {
+	err = database.Transaction(ctx, s.recordRepo.(*infraRepository.RecordRepositoryDynamic).GetDB(), nil, func(txCtx context.Context) error {
 		id := valueobject.NewRecordID(recordID)
 
-		// 1. 先获取记录信息（使用 tableID）
+		// 1. 先获取记录信息（使用 tableID；需校验版本时绕过缓存读取最新版本）
+		if expectedVersion != nil {
+			s.invalidateCachedRecord(ctx, tableID, recordID)
+		}
 		record, err := s.recordRepo.FindByTableAndID(txCtx, tableID, id)
 		if err != nil {
-			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+			return pkgerrors.Database(err, "查找记录失败")
 		}
 		if record == nil {
 			return pkgerrors.ErrNotFound.WithDetails("记录不存在")
 		}
+		if expectedVersion != nil && record.Version().Value() != int64(*expectedVersion) {
+			return pkgerrors.ErrVersionConflict.WithDetails(map[string]interface{}{
+				"expected_version": *expectedVersion,
+				"current_version":  record.Version().Value(),
+			})
+		}
 
-		// 2. 删除记录（使用 tableID）
-		if err := s.recordRepo.DeleteByTableAndID(txCtx, tableID, id); err != nil {
-			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除记录失败: %v", err))
+		// 2. 删除记录（使用 tableID）；需校验版本时删除语句带版本条件，读取后被其他请求修改时同样冲突
+		if expectedVersion != nil {
+			err = s.recordRepo.DeleteByTableAndIDWithVersion(txCtx, tableID, id, record.Version())
+		} else {
+			err = s.recordRepo.DeleteByTableAndID(txCtx, tableID, id)
+		}
+		if err != nil {
+			if pkgerrors.Is(err, pkgerrors.ErrVersionConflict) {
+				s.invalidateCachedRecord(ctx, tableID, recordID)
+			}
+			return pkgerrors.Database(err, "删除记录失败")
 		}
 
 		logger.Info("记录删除成功（事务中）", logger.String("record_id", recordID))
@@ -512,6 +647,9 @@ func (s *RecordService) DeleteRecord(ctx context.Context, tableID, recordID stri
 			Fields:    record.Data().ToMap(), // 保存删除前的数据
 		}
 		database.AddEventToTx(txCtx, event)
+		if err := s.recordChangeFeed(txCtx, event); err != nil {
+			return err
+		}
 
 		// 4. ✨ 添加事务提交后回调（发布 WebSocket 事件）
 		database.AddTxCallback(txCtx, func() {
@@ -531,13 +669,39 @@ func (s *RecordService) DeleteRecord(ctx context.Context, tableID, recordID stri
 	return nil
 }
 
+// recordCacheInvalidator 可清除单条记录缓存的仓储（带缓存的记录仓储）
+type recordCacheInvalidator interface {
+	InvalidateRecord(ctx context.Context, tableID, recordID string)
+}
+
+// invalidateCachedRecord 版本冲突时清除记录缓存，避免客户端反复读到旧版本（旧 ETag）
+func (s *RecordService) invalidateCachedRecord(ctx context.Context, tableID, recordID string) {
+	if invalidator, ok := s.recordRepo.(recordCacheInvalidator); ok {
+		invalidator.InvalidateRecord(ctx, tableID, recordID)
+	}
+}
+
 // ListRecords 列出表格的所有记录
 func (s *RecordService) ListRecords(ctx context.Context, tableID string, limit, offset int) ([]*dto.RecordResponse, int64, error) {
+	return s.ListRecordsWithFields(ctx, tableID, limit, offset, nil)
+}
+
+// ListRecordsWithFields 列出表格的记录，fieldIDs 非空时只读取并返回这些字段（字段投影）
+// 投影时返回已保存的计算字段结果，不在读取时重算（重算依赖未读取的字段）
+func (s *RecordService) ListRecordsWithFields(ctx context.Context, tableID string, limit, offset int, fieldIDs []string) (_ []*dto.RecordResponse, _ int64, err error) {
+	ctx, span := tracing.Start(ctx, "RecordService.ListRecords", tracing.WithAttributes(
+		tracing.String("luckdb.table_id", tableID),
+		tracing.Int("luckdb.limit", limit),
+		tracing.Int("luckdb.offset", offset),
+		tracing.Int("luckdb.projected_fields", len(fieldIDs))))
+	defer func() { span.Finish(err) }()
+
 	// 构建过滤器
 	filter := recordRepo.RecordFilter{
-		TableID: &tableID,
-		Limit:   limit,
-		Offset:  offset,
+		TableID:  &tableID,
+		FieldIDs: fieldIDs,
+		Limit:    limit,
+		Offset:   offset,
 	}
 
 	if filter.Limit == 0 {
@@ -547,12 +711,12 @@ func (s *RecordService) ListRecords(ctx context.Context, tableID string, limit,
 	// 查询记录列表
 	records, total, err := s.recordRepo.List(ctx, filter)
 	if err != nil {
-		return nil, 0, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询记录列表失败: %v", err))
+		return nil, 0, pkgerrors.Database(err, "查询记录列表失败")
 	}
 
 	// ✅ 优化：批量预加载字段，避免N+1查询
 	// 一次性获取所有字段，然后在计算时复用
-	if s.calculationService != nil && len(records) > 0 {
+	if s.calculationService != nil && len(records) > 0 && len(fieldIDs) == 0 {
 		logger.Info("开始计算记录列表的虚拟字段",
 			logger.String("table_id", tableID),
 			logger.Int("record_count", len(records)))
@@ -582,11 +746,19 @@ func (s *RecordService) ListRecords(ctx context.Context, tableID string, limit,
 	}
 
 	// 转换为 DTO
-	return dto.FromRecordEntities(records), total, nil
+	responses := dto.FromRecordEntities(records)
+	s.maskResponses(ctx, tableID, responses...)
+	return responses, total, nil
 }
 
 // BatchCreateRecords 批量创建记录（严格遵守：返回AppError）
 func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string, req dto.BatchCreateRecordRequest, userID string) (*dto.BatchCreateRecordResponse, error) {
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return nil, err
+	}
+	if err := s.checkPlanLimit(ctx, tableID, len(req.Records)); err != nil {
+		return nil, err
+	}
 	// ✅ 允许空数组：直接返回成功响应
 	if len(req.Records) == 0 {
 		return &dto.BatchCreateRecordResponse{
@@ -622,6 +794,10 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 			errorsList = append(errorsList, fmt.Sprintf("记录%d创建失败: %v", i+1, err))
 			continue
 		}
+		if err := s.applyStatusTransitions(ctx, userID, tableID, record.ID().String(), nil, validatedData); err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%d状态无效: %v", i+1, err))
+			continue
+		}
 
 		// 保存记录
 		if err := s.recordRepo.Save(ctx, record); err != nil {
@@ -640,6 +816,16 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 				// 计算失败不影响记录创建，继续
 			}
 		}
+		if err := s.aiRecordCreated(ctx, tableID, record.ID().String(), record.Data().ToMap(), userID); err != nil {
+			logger.Warn("登记 AI 生成任务失败（不影响创建）",
+				logger.String("record_id", record.ID().String()),
+				logger.ErrorField(err))
+		}
+		if err := s.textExtractionRecordCreated(ctx, tableID, record.ID().String(), record.Data().ToMap(), userID); err != nil {
+			logger.Warn("登记文本提取任务失败（不影响创建）",
+				logger.String("record_id", record.ID().String()),
+				logger.ErrorField(err))
+		}
 
 		// 添加到成功列表
 		successRecords = append(successRecords, dto.FromRecordEntity(record))
@@ -652,6 +838,7 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 		logger.Int("failed", len(errorsList)),
 	)
 
+	s.maskResponses(ctx, tableID, successRecords...)
 	return &dto.BatchCreateRecordResponse{
 		Records:      successRecords,
 		SuccessCount: len(successRecords),
@@ -662,11 +849,19 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 
 // BatchUpdateRecords 批量更新记录（严格遵守：返回AppError）
 func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string, req dto.BatchUpdateRecordRequest, userID string) (*dto.BatchUpdateRecordResponse, error) {
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return nil, err
+	}
 	successRecords := make([]*dto.RecordResponse, 0, len(req.Records))
 	errorsList := make([]string, 0)
 
 	// 遍历每条记录进行更新
 	for i, item := range req.Records {
+		if err := s.checkRecordLock(ctx, userID, tableID, item.ID, item.Fields); err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%s已被锁定: %v", item.ID, err))
+			continue
+		}
+
 		// 查找记录（使用 tableID）
 		id := valueobject.NewRecordID(item.ID)
 		records, err := s.recordRepo.FindByIDs(ctx, tableID, []valueobject.RecordID{id})
@@ -679,6 +874,11 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 			continue
 		}
 		record := records[0]
+		if err := s.applyStatusTransitions(ctx, userID, tableID, item.ID, record.Data().ToMap(), item.Fields); err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%s状态流转失败: %v", item.ID, err))
+			continue
+		}
+		changedFieldIDs := s.identifyChangedFields(record.Data().ToMap(), item.Fields)
 
 		// 创建新数据
 		newData, err := valueobject.NewRecordData(item.Fields)
@@ -698,6 +898,16 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 			errorsList = append(errorsList, fmt.Sprintf("记录%s保存失败: %v", item.ID, err))
 			continue
 		}
+		if err := s.aiRecordUpdated(ctx, tableID, item.ID, changedFieldIDs, userID); err != nil {
+			logger.Warn("登记 AI 生成任务失败（不影响更新）",
+				logger.String("record_id", item.ID),
+				logger.ErrorField(err))
+		}
+		if err := s.textExtractionRecordUpdated(ctx, tableID, item.ID, changedFieldIDs, userID); err != nil {
+			logger.Warn("登记文本提取任务失败（不影响更新）",
+				logger.String("record_id", item.ID),
+				logger.ErrorField(err))
+		}
 
 		// 添加到成功列表
 		successRecords = append(successRecords, dto.FromRecordEntity(record))
@@ -709,6 +919,7 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 		logger.Int("failed", len(errorsList)),
 	)
 
+	s.maskResponses(ctx, tableID, successRecords...)
 	return &dto.BatchUpdateRecordResponse{
 		Records:      successRecords,
 		SuccessCount: len(successRecords),
@@ -719,11 +930,19 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 
 // BatchDeleteRecords 批量删除记录（严格遵守：返回AppError）
 func (s *RecordService) BatchDeleteRecords(ctx context.Context, tableID string, req dto.BatchDeleteRecordRequest) (*dto.BatchDeleteRecordResponse, error) {
+	if err := s.checkWritable(ctx, tableID); err != nil {
+		return nil, err
+	}
 	errorsList := make([]string, 0)
 	successCount := 0
+	userID, _ := authctx.UserFrom(ctx)
 
 	// 遍历每条记录进行删除（使用 tableID）
 	for _, recordID := range req.RecordIDs {
+		if err := s.checkRecordLock(ctx, userID, tableID, recordID, nil); err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%s已被锁定: %v", recordID, err))
+			continue
+		}
 		id := valueobject.NewRecordID(recordID)
 
 		// 删除记录（使用 tableID）
@@ -748,8 +967,180 @@ func (s *RecordService) BatchDeleteRecords(ctx context.Context, tableID string,
 	}, nil
 }
 
+// SetChangeFeed 设置变更流服务（记录变更随事务写入发件箱）
+func (s *RecordService) SetChangeFeed(changeFeed *ChangeFeedService) {
+	s.changeFeed = changeFeed
+}
+
+// SetBacklinkService 设置反向链接服务（记录写入时重建文本单元格中提及的反向链接）
+func (s *RecordService) SetBacklinkService(backlinks *BacklinkService) {
+	s.backlinks = backlinks
+}
+
+// recordChangeFeed 将记录变更写入变更流发件箱，并重建记录提及的反向链接（与记录写入同一事务）
+func (s *RecordService) recordChangeFeed(ctx context.Context, event *database.RecordEvent) error {
+	if s.backlinks != nil {
+		if err := s.backlinks.RecordChanged(ctx, event); err != nil {
+			return err
+		}
+	}
+	if s.changeFeed == nil {
+		return nil
+	}
+	return s.changeFeed.RecordChanged(ctx, event)
+}
+
+// SetAIFieldService 设置 AI 字段生成服务（记录写入时登记生成任务）
+func (s *RecordService) SetAIFieldService(aiFields *AIFieldService) {
+	s.aiFields = aiFields
+}
+
+// SetTextExtractionService 设置附件文本提取服务（记录写入时登记提取任务）
+func (s *RecordService) SetTextExtractionService(textExtraction *TextExtractionService) {
+	s.textExtraction = textExtraction
+}
+
+// aiRecordCreated 为新记录登记 AI 字段生成任务（与记录写入同一事务）
+func (s *RecordService) aiRecordCreated(ctx context.Context, tableID, recordID string, data map[string]interface{}, userID string) error {
+	if s.aiFields == nil {
+		return nil
+	}
+	return s.aiFields.RecordCreated(ctx, tableID, recordID, data, userID)
+}
+
+// aiRecordUpdated 为引用了变更字段的 AI 字段登记生成任务（与记录写入同一事务）
+func (s *RecordService) aiRecordUpdated(ctx context.Context, tableID, recordID string, changedFieldIDs []string, userID string) error {
+	if s.aiFields == nil || len(changedFieldIDs) == 0 {
+		return nil
+	}
+	return s.aiFields.RecordUpdated(ctx, tableID, recordID, changedFieldIDs, userID)
+}
+
+// SetCrossBaseLinkService 设置跨 Base 关联服务
+func (s *RecordService) SetCrossBaseLinkService(crossBaseLinks *CrossBaseLinkService) {
+	s.crossBaseLinks = crossBaseLinks
+}
+
+// checkCrossBaseLinks 写入跨 Base 关联值前按目标 Base 校验权限
+func (s *RecordService) checkCrossBaseLinks(ctx context.Context, userID, tableID string, data map[string]interface{}) error {
+	if s.crossBaseLinks == nil {
+		return nil
+	}
+	return s.crossBaseLinks.CheckWrite(ctx, userID, tableID, data)
+}
+
+// SetSyncedTableService 设置同步表服务
+func (s *RecordService) SetSyncedTableService(syncedTables *SyncedTableService) {
+	s.syncedTables = syncedTables
+}
+
+// SetMaintenanceService 设置维护模式服务
+func (s *RecordService) SetMaintenanceService(maintenance *MaintenanceService) {
+	s.maintenance = maintenance
+}
+
+// checkWritable 维护中的实例、工作空间或 Base 只允许读取；同步表的镜像表只能由同步写入
+func (s *RecordService) checkWritable(ctx context.Context, tableID string) error {
+	if s.maintenance != nil {
+		if err := s.maintenance.CheckWritable(ctx, ResourceRefs{TableID: tableID}); err != nil {
+			return err
+		}
+	}
+	if s.syncedTables == nil {
+		return nil
+	}
+	return s.syncedTables.CheckWritable(ctx, tableID)
+}
+
+// SetPlanService 设置套餐服务
+func (s *RecordService) SetPlanService(plans *PlanService) {
+	s.plans = plans
+}
+
+// checkPlanLimit 新建记录前检查 Base 的记录数限额（同步写入不受限制）
+func (s *RecordService) checkPlanLimit(ctx context.Context, tableID string, count int) error {
+	if s.plans == nil || count == 0 || isSyncedTableWrite(ctx) {
+		return nil
+	}
+	_, err := s.plans.CheckRecordCreate(ctx, tableID, count)
+	return err
+}
+
+// SetRecordLockService 设置记录锁服务
+func (s *RecordService) SetRecordLockService(recordLocks *RecordLockService) {
+	s.recordLocks = recordLocks
+}
+
+// checkRecordLock 记录或写入的字段被其他用户锁定时拒绝写入；data 为 nil 表示删除记录（同步写入不受限制）
+func (s *RecordService) checkRecordLock(ctx context.Context, userID, tableID, recordID string, data map[string]interface{}) error {
+	if s.recordLocks == nil || isSyncedTableWrite(ctx) {
+		return nil
+	}
+	return s.recordLocks.CheckWrite(ctx, userID, tableID, recordID, data)
+}
+
+// SetBatchAuthorizer 设置批量权限校验服务
+func (s *RecordService) SetBatchAuthorizer(authorizer *BatchAuthorizationService) {
+	s.authorizer = authorizer
+}
+
+// SetStatusFlowService 设置状态流服务
+func (s *RecordService) SetStatusFlowService(statusFlows *StatusFlowService) {
+	s.statusFlows = statusFlows
+}
+
+// applyStatusTransitions 检查状态字段的流转并记录（与记录写入同一事务）；before 为 nil 表示新建记录
+func (s *RecordService) applyStatusTransitions(ctx context.Context, userID, tableID, recordID string, before, changes map[string]interface{}) error {
+	if s.statusFlows == nil || isSyncedTableWrite(ctx) {
+		return nil
+	}
+	return s.statusFlows.ApplyTransitions(ctx, userID, tableID, recordID, before, changes)
+}
+
+// propagateLinkedRecords 将记录变更传播到其他 Base 中关联了该表的表
+func (s *RecordService) propagateLinkedRecords(tableID, action string, recordIDs []string, userID string) {
+	if s.crossBaseLinks == nil {
+		return
+	}
+	s.crossBaseLinks.RecordsChanged(context.Background(), tableID, action, recordIDs, userID)
+}
+
+// textExtractionRecordCreated 为新记录登记附件文本提取任务（与记录写入同一事务）
+func (s *RecordService) textExtractionRecordCreated(ctx context.Context, tableID, recordID string, data map[string]interface{}, userID string) error {
+	if s.textExtraction == nil {
+		return nil
+	}
+	return s.textExtraction.RecordCreated(ctx, tableID, recordID, data, userID)
+}
+
+// textExtractionRecordUpdated 附件字段变化时登记文本提取任务（与记录写入同一事务）
+func (s *RecordService) textExtractionRecordUpdated(ctx context.Context, tableID, recordID string, changedFieldIDs []string, userID string) error {
+	if s.textExtraction == nil || len(changedFieldIDs) == 0 {
+		return nil
+	}
+	return s.textExtraction.RecordUpdated(ctx, tableID, recordID, changedFieldIDs, userID)
+}
+
 // publishRecordEvent 发布记录事件到 WebSocket
 func (s *RecordService) publishRecordEvent(event *database.RecordEvent) {
+	event = s.maskRecordEvent(event)
+	s.broadcastRecordEvent(event)
+	s.publishBusinessRecordEvent(event)
+	s.propagateLinkedRecords(event.TID, strings.TrimPrefix(event.EventType, "record."), []string{event.RID}, event.UserID)
+}
+
+// maskRecordEvent 实时推送前对受保护字段脱敏
+func (s *RecordService) maskRecordEvent(event *database.RecordEvent) *database.RecordEvent {
+	if s.privacyService != nil && len(event.Fields) > 0 {
+		masked := *event
+		masked.Fields = s.privacyService.MaskForStream(context.Background(), event.TID, event.Fields)
+		return &masked
+	}
+	return event
+}
+
+// broadcastRecordEvent 推送到 WebSocket 与 ShareDB（实时协作）
+func (s *RecordService) broadcastRecordEvent(event *database.RecordEvent) {
 	// 1. 发布到传统WebSocket广播器（保持向后兼容）
 	if s.broadcaster != nil {
 		switch event.EventType {
@@ -841,7 +1232,10 @@ func (s *RecordService) publishRecordEvent(event *database.RecordEvent) {
 		}
 	}
 
-	// 3. 发布到统一业务事件系统（支持SSE、WebSocket、Yjs）
+}
+
+// publishBusinessRecordEvent 发布到统一业务事件系统（支持SSE、WebSocket、Yjs）
+func (s *RecordService) publishBusinessRecordEvent(event *database.RecordEvent) {
 	if s.businessEvents != nil {
 		ctx := context.Background()
 		var businessEventType events.BusinessEventType
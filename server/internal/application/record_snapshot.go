@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var snapshotLog = logger.Named("record_snapshot")
+
+// 快照分页默认值
+const (
+	defaultSnapshotPageSize = 100
+	maxSnapshotPageSize     = 1000
+)
+
+// SetSnapshotReader 设置快照读取器（启用按时间点读取表）
+func (s *RecordService) SetSnapshotReader(reader recordRepo.SnapshotReader, cfg config.RecordSnapshotConfig) {
+	s.snapshotReader = reader
+	s.snapshotConfig = cfg
+}
+
+// ListRecordsAsOf 读取表在 asOf 时刻的记录 ✨
+// 分页导出时首页确定 asOf，后续页沿用同一个 asOf 与游标，各页之间的并发修改不会导致重复或遗漏。
+// 快照返回存储值，不重新计算公式等虚拟字段
+func (s *RecordService) ListRecordsAsOf(ctx context.Context, tableID string, req dto.RecordSnapshotRequest) (*dto.RecordSnapshotResponse, error) {
+	if s.snapshotReader == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("当前服务未启用快照读取")
+	}
+
+	now := time.Now()
+	asOf, err := resolveSnapshotAsOf(req.AsOf, now, s.snapshotConfig.Retention)
+	if err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSnapshotPageSize
+	}
+	if limit > maxSnapshotPageSize {
+		limit = maxSnapshotPageSize
+	}
+
+	page, err := s.snapshotReader.ListAsOf(ctx, tableID, asOf, req.Cursor, limit, s.snapshotConfig.MaxChanges)
+	if err != nil {
+		if errors.Is(err, record.ErrSnapshotTooOld) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("asOf 之后的变更过多，请使用更近的时间点")
+		}
+		return nil, pkgerrors.Database(err, "读取表快照失败")
+	}
+
+	records := dto.FromRecordEntities(page.Records)
+	s.maskResponses(ctx, tableID, records...)
+
+	resp := &dto.RecordSnapshotResponse{
+		Records: records,
+		Total:   page.Total,
+		AsOf:    asOf,
+	}
+	if page.NextCursor > 0 {
+		resp.NextCursor = &page.NextCursor
+	}
+	return resp, nil
+}
+
+// resolveSnapshotAsOf 校验快照时间点：默认为当前时间，不能晚于当前时间，也不能早于变更日志保留期
+func resolveSnapshotAsOf(asOf *time.Time, now time.Time, retention time.Duration) (time.Time, error) {
+	if asOf == nil || asOf.IsZero() {
+		return now, nil
+	}
+	if asOf.After(now) {
+		return time.Time{}, pkgerrors.ErrValidationFailed.WithDetails("asOf 不能晚于当前时间")
+	}
+	if retention > 0 && asOf.Before(now.Add(-retention)) {
+		return time.Time{}, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+			"message":   "asOf 超出变更日志保留期",
+			"retention": retention.String(),
+		})
+	}
+	return *asOf, nil
+}
+
+// StartSnapshotCleanup 定期清理超出保留期的变更日志
+func (s *RecordService) StartSnapshotCleanup(ctx context.Context) {
+	if s.snapshotReader == nil || s.snapshotConfig.Retention <= 0 || s.snapshotConfig.CleanupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.snapshotConfig.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			deleted, err := s.snapshotReader.PurgeChanges(ctx, time.Now().Add(-s.snapshotConfig.Retention))
+			if err != nil {
+				snapshotLog.Warn(ctx, "清理过期变更日志失败", logger.ErrorField(err))
+				continue
+			}
+			if deleted > 0 {
+				snapshotLog.Debug(ctx, "已清理过期变更日志", logger.Int64("count", deleted))
+			}
+		}
+	}()
+}
@@ -0,0 +1,32 @@
+package application
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveSnapshotAsOf(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	retention := 24 * time.Hour
+
+	got, err := resolveSnapshotAsOf(nil, now, retention)
+	if err != nil || !got.Equal(now) {
+		t.Fatalf("未指定 asOf 时应使用当前时间，得到 %v, %v", got, err)
+	}
+
+	past := now.Add(-time.Hour)
+	got, err = resolveSnapshotAsOf(&past, now, retention)
+	if err != nil || !got.Equal(past) {
+		t.Fatalf("保留期内的 asOf 应原样返回，得到 %v, %v", got, err)
+	}
+
+	future := now.Add(time.Minute)
+	if _, err := resolveSnapshotAsOf(&future, now, retention); err == nil {
+		t.Error("晚于当前时间的 asOf 应被拒绝")
+	}
+
+	expired := now.Add(-retention - time.Minute)
+	if _, err := resolveSnapshotAsOf(&expired, now, retention); err == nil {
+		t.Error("超出保留期的 asOf 应被拒绝")
+	}
+}
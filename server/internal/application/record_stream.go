@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+)
+
+// recordStream 按记录ID顺序分页读取物理表的流式迭代器
+// 每次只在内存中保留一页，适合导出等需要遍历整表的场景：
+//
+//	stream := newRecordStream(db, tableName, fields, batchSize)
+//	for stream.Next(ctx) {
+//		row := stream.Row()
+//	}
+//	if err := stream.Err(); err != nil { ... }
+type recordStream struct {
+	db        *gorm.DB
+	tableName string
+	selects   string
+	batchSize int
+
+	page  []map[string]interface{}
+	pos   int
+	after string
+	done  bool
+	err   error
+}
+
+// newRecordStream 创建迭代器，读取 __id、系统列与给定字段的物理列
+func newRecordStream(db *gorm.DB, tableName string, fields []*entity.Field, batchSize int) *recordStream {
+	columns := []string{"__id", "__version", "__created_time", "__last_modified_time"}
+	for _, field := range fields {
+		columns = append(columns, field.DBFieldName().String())
+	}
+	quoted := make([]string, 0, len(columns))
+	for _, col := range columns {
+		quoted = append(quoted, `"`+strings.ReplaceAll(col, `"`, `""`)+`"`)
+	}
+	return &recordStream{
+		db:        db,
+		tableName: tableName,
+		selects:   strings.Join(quoted, ", "),
+		batchSize: batchSize,
+		pos:       -1,
+	}
+}
+
+// Next 前进到下一行，没有更多行或出错时返回 false
+func (s *recordStream) Next(ctx context.Context) bool {
+	if s.err != nil {
+		return false
+	}
+	s.pos++
+	if s.pos < len(s.page) {
+		return true
+	}
+	if s.done {
+		return false
+	}
+
+	var page []map[string]interface{}
+	if err := s.db.WithContext(ctx).
+		Table(s.tableName).
+		Select(s.selects).
+		Where("__id > ?", s.after).
+		Order("__id ASC").
+		Limit(s.batchSize).
+		Find(&page).Error; err != nil {
+		s.err = err
+		return false
+	}
+	s.page, s.pos = page, 0
+	s.done = len(page) < s.batchSize
+	if len(page) == 0 {
+		return false
+	}
+	switch id := page[len(page)-1]["__id"].(type) {
+	case []byte:
+		s.after = string(id)
+	default:
+		s.after = fmt.Sprint(id)
+	}
+	return true
+}
+
+// Row 当前行（物理列名 → 值）
+func (s *recordStream) Row() map[string]interface{} {
+	return s.page[s.pos]
+}
+
+// Err 迭代过程中的错误
+func (s *recordStream) Err() error {
+	return s.err
+}
@@ -0,0 +1,351 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordtemplate"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// recordTemplateRecordCreator 从模板新建记录（由 RecordService 实现，照常校验、计算并推送事件）
+type recordTemplateRecordCreator interface {
+	CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// SaveRecordTemplateRequest 创建记录模板请求
+type SaveRecordTemplateRequest struct {
+	Name        string                 `json:"name" binding:"required"`
+	Description string                 `json:"description"`
+	Values      map[string]interface{} `json:"values"` // 字段ID → 值，可使用 @me、@today+7d、@now 等占位符
+	IsDefault   bool                   `json:"isDefault"`
+}
+
+// UpdateRecordTemplateRequest 更新记录模板请求（未提供的属性保持不变）
+type UpdateRecordTemplateRequest struct {
+	Name        *string                `json:"name"`
+	Description *string                `json:"description"`
+	Values      map[string]interface{} `json:"values"` // 提供时整体替换
+	IsDefault   *bool                  `json:"isDefault"`
+}
+
+// CreateFromRecordTemplateRequest 从模板新建记录请求
+type CreateFromRecordTemplateRequest struct {
+	Fields map[string]interface{} `json:"fields"` // 覆盖模板中的值（字段ID → 值）
+}
+
+// RecordTemplateService 记录模板服务 ✨
+// 按表维护预设单元格值的模板，用于快速新建记录：
+//   - 管理模板需要表结构管理权限，表的其他成员只能查看和使用
+//   - 用户字段可写 @me，日期字段可写 @today、@now 及偏移（如 @today+7d），新建记录时按操作者与字段时区解析
+//   - 字段被删除后模板中的对应值自动忽略；计算字段与系统字段不能写入模板
+type RecordTemplateService struct {
+	repo              recordtemplate.Repository
+	fieldRepo         repository.FieldRepository
+	records           recordTemplateRecordCreator
+	permissionService *PermissionServiceV2
+	businessCalendars *BusinessCalendarService
+	now               func() time.Time
+}
+
+// NewRecordTemplateService 创建记录模板服务
+func NewRecordTemplateService(
+	repo recordtemplate.Repository,
+	fieldRepo repository.FieldRepository,
+	records recordTemplateRecordCreator,
+	permissionService *PermissionServiceV2,
+) *RecordTemplateService {
+	return &RecordTemplateService{
+		repo:              repo,
+		fieldRepo:         fieldRepo,
+		records:           records,
+		permissionService: permissionService,
+		now:               time.Now,
+	}
+}
+
+// SetBusinessCalendarService 设置工作日历服务（模板中的工作日偏移按表所属空间的日历计算）
+func (s *RecordTemplateService) SetBusinessCalendarService(calendars *BusinessCalendarService) {
+	s.businessCalendars = calendars
+}
+
+// ListTemplates 列出表的记录模板（默认模板在前）
+func (s *RecordTemplateService) ListTemplates(ctx context.Context, userID, tableID string) ([]*recordtemplate.Template, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	templates, err := s.repo.ListByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录模板失败")
+	}
+	return templates, nil
+}
+
+// CreateTemplate 创建记录模板（需要表结构管理权限）
+func (s *RecordTemplateService) CreateTemplate(ctx context.Context, userID, tableID string, req SaveRecordTemplateRequest) (*recordtemplate.Template, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的记录模板")
+	}
+
+	template := recordtemplate.NewTemplate(tableID, req.Name, req.Description, req.Values, req.IsDefault, userID, s.now())
+	if err := s.validate(ctx, template); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录模板失败")
+	}
+	if len(existing) >= recordtemplate.MaxTemplatesPerTable {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("每张表最多 %d 个记录模板", recordtemplate.MaxTemplatesPerTable))
+	}
+	if err := checkTemplateName(existing, template); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, template); err != nil {
+		return nil, pkgerrors.Database(err, "保存记录模板失败")
+	}
+	return template, nil
+}
+
+// UpdateTemplate 更新记录模板（需要表结构管理权限）
+func (s *RecordTemplateService) UpdateTemplate(ctx context.Context, userID, templateID string, req UpdateRecordTemplateRequest) (*recordtemplate.Template, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, template.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的记录模板")
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Values != nil {
+		template.Values = req.Values
+	}
+	if req.IsDefault != nil {
+		template.IsDefault = *req.IsDefault
+	}
+	if err := s.validate(ctx, template); err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		existing, err := s.repo.ListByTable(ctx, template.TableID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询记录模板失败")
+		}
+		if err := checkTemplateName(existing, template); err != nil {
+			return nil, err
+		}
+	}
+
+	template.UpdatedBy = userID
+	template.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, template); err != nil {
+		return nil, pkgerrors.Database(err, "保存记录模板失败")
+	}
+	return template, nil
+}
+
+// DeleteTemplate 删除记录模板（需要表结构管理权限）
+func (s *RecordTemplateService) DeleteTemplate(ctx context.Context, userID, templateID string) error {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, template.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的记录模板")
+	}
+	if err := s.repo.Delete(ctx, templateID); err != nil {
+		return pkgerrors.Database(err, "删除记录模板失败")
+	}
+	return nil
+}
+
+// ResolveTemplate 解析模板中的占位符，返回新建记录时将写入的值（用于预填新建表单）
+func (s *RecordTemplateService) ResolveTemplate(ctx context.Context, userID, templateID string) (map[string]interface{}, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(ctx, userID, template, s.now())
+}
+
+// CreateFromTemplate 从模板新建记录，请求中的值覆盖模板中的同一字段
+func (s *RecordTemplateService) CreateFromTemplate(ctx context.Context, userID, templateID string, req CreateFromRecordTemplateRequest) (*dto.RecordResponse, error) {
+	return s.createFromTemplate(ctx, userID, templateID, s.now(), req.Fields)
+}
+
+// CreateFromTemplateAt 以 at 为基准解析相对日期占位符并从模板新建记录（用于按计划补建记录）
+func (s *RecordTemplateService) CreateFromTemplateAt(ctx context.Context, userID, templateID string, at time.Time) (*dto.RecordResponse, error) {
+	return s.createFromTemplate(ctx, userID, templateID, at, nil)
+}
+
+// createFromTemplate 解析模板值，合并覆盖值后新建记录
+func (s *RecordTemplateService) createFromTemplate(ctx context.Context, userID, templateID string, at time.Time, fields map[string]interface{}) (*dto.RecordResponse, error) {
+	template, err := s.template(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	values, err := s.resolve(ctx, userID, template, at)
+	if err != nil {
+		return nil, err
+	}
+	for fieldID, value := range fields {
+		values[fieldID] = value
+	}
+	return s.records.CreateRecord(ctx, dto.CreateRecordRequest{TableID: template.TableID, Data: values}, userID)
+}
+
+// resolve 检查新建记录权限并以 at 为基准解析模板值
+func (s *RecordTemplateService) resolve(ctx context.Context, userID string, template *recordtemplate.Template, at time.Time) (map[string]interface{}, error) {
+	if !s.permissionService.CanCreateRecordsInTable(ctx, userID, template.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该表格中创建记录")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, template.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	calendar := workdayCalendar(ctx, s.businessCalendars, template.TableID)
+	return resolveTemplateValues(fields, template.Values, userID, at, calendar), nil
+}
+
+// validate 校验模板属性与单元格值
+func (s *RecordTemplateService) validate(ctx context.Context, template *recordtemplate.Template) error {
+	if err := template.Validate(); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, template.TableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取字段列表失败")
+	}
+	if err := validateTemplateValues(fields, template.Values); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// template 获取记录模板
+func (s *RecordTemplateService) template(ctx context.Context, templateID string) (*recordtemplate.Template, error) {
+	template, err := s.repo.Get(ctx, templateID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找记录模板失败")
+	}
+	if template == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("记录模板不存在")
+	}
+	return template, nil
+}
+
+// checkTemplateName 同一表中模板名称不能重复
+func checkTemplateName(existing []*recordtemplate.Template, template *recordtemplate.Template) error {
+	for _, other := range existing {
+		if other.ID != template.ID && other.Name == template.Name {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("记录模板 %s 已存在", template.Name))
+		}
+	}
+	return nil
+}
+
+// validateTemplateValues 校验模板值：字段须属于该表且可写，日期字段的占位符须可解析
+// 值本身的类型与格式在新建记录时由记录服务按字段校验
+func validateTemplateValues(fields []*fieldEntity.Field, values map[string]interface{}) error {
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		if !field.IsDeleted() {
+			byID[field.ID().String()] = field
+		}
+	}
+	for fieldID, value := range values {
+		field := byID[fieldID]
+		if field == nil {
+			return fmt.Errorf("字段不存在: %s", fieldID)
+		}
+		if !templateWritable(field) {
+			return fmt.Errorf("字段 %s 由系统计算，不能写入模板", field.Name().String())
+		}
+		if s, ok := value.(string); ok && isTemplateDateField(field) && recordtemplate.IsDateToken(s) {
+			if _, err := recordtemplate.ParseDateToken(s); err != nil {
+				return fmt.Errorf("字段 %s: %v", field.Name().String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveTemplateValues 解析模板值中的占位符（工作日偏移按 calendar 计算）；已删除或不再可写的字段被忽略
+func resolveTemplateValues(fields []*fieldEntity.Field, values map[string]interface{}, userID string, now time.Time, calendar *businesscalendar.Calendar) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(values))
+	for _, field := range fields {
+		fieldID := field.ID().String()
+		value, ok := values[fieldID]
+		if !ok || field.IsDeleted() || !templateWritable(field) {
+			continue
+		}
+		switch {
+		case field.Type().String() == fieldVO.TypeUser:
+			value = recordtemplate.ResolveUser(value, userID)
+		case isTemplateDateField(field):
+			if s, ok := value.(string); ok && recordtemplate.IsDateToken(s) {
+				token, err := recordtemplate.ParseDateToken(s)
+				if err != nil {
+					continue
+				}
+				value = formatTemplateDate(field, token.ResolveWithCalendar(now, templateFieldLocation(field), calendar))
+			}
+		}
+		resolved[fieldID] = value
+	}
+	return resolved
+}
+
+// templateWritable 字段是否可由模板写入（排除计算字段、系统字段、自动编号与按钮）
+func templateWritable(field *fieldEntity.Field) bool {
+	if field.IsVirtual() || field.IsComputed() {
+		return false
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeAutoNumber, fieldVO.TypeButton:
+		return false
+	}
+	return true
+}
+
+// isTemplateDateField 字段是否为日期或日期时间字段
+func isTemplateDateField(field *fieldEntity.Field) bool {
+	t := field.Type().String()
+	return t == fieldVO.TypeDate || t == fieldVO.TypeDateTime
+}
+
+// templateFieldLocation 日期字段配置的时区（未配置或无效时为 UTC）
+func templateFieldLocation(field *fieldEntity.Field) *time.Location {
+	if options := field.Options(); options != nil && options.Date != nil && options.Date.TimeZone != "" {
+		if loc, err := time.LoadLocation(options.Date.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// formatTemplateDate 按字段是否包含时间格式化解析后的日期
+func formatTemplateDate(field *fieldEntity.Field, t time.Time) string {
+	includeTime := field.Type().String() == fieldVO.TypeDateTime
+	if options := field.Options(); options != nil && options.Date != nil && options.Date.IncludeTime {
+		includeTime = true
+	}
+	if includeTime {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func newTemplateTestField(t *testing.T, name, fieldType string) *fieldEntity.Field {
+	t.Helper()
+	return newTestFieldWithOptions(t, "tbl1", name, fieldType, nil)
+}
+
+// newTestFieldWithOptions 在指定表中创建字段并设置选项（options 为 nil 时使用默认选项）
+func newTestFieldWithOptions(t *testing.T, tableID, name, fieldType string, options *fieldVO.FieldOptions) *fieldEntity.Field {
+	t.Helper()
+	fn, _ := fieldVO.NewFieldName(name)
+	ft, _ := fieldVO.NewFieldType(fieldType)
+	field, err := fieldEntity.NewField(tableID, fn, ft, "usr1")
+	if err != nil {
+		t.Fatalf("创建字段失败: %v", err)
+	}
+	if options != nil {
+		if err := field.UpdateOptions(options); err != nil {
+			t.Fatalf("设置字段选项失败: %v", err)
+		}
+	}
+	return field
+}
+
+func TestRecordTemplateValues(t *testing.T) {
+	title := newTemplateTestField(t, "标题", fieldVO.TypeText)
+	owner := newTemplateTestField(t, "负责人", fieldVO.TypeUser)
+	due := newTemplateTestField(t, "截止日期", fieldVO.TypeDate)
+	options := fieldVO.NewFieldOptions()
+	options.Date = &fieldVO.DateOptions{TimeZone: "Asia/Shanghai"}
+	if err := due.UpdateOptions(options); err != nil {
+		t.Fatalf("设置选项失败: %v", err)
+	}
+	remind := newTemplateTestField(t, "提醒时间", fieldVO.TypeDateTime)
+	created := newTemplateTestField(t, "创建时间", fieldVO.TypeCreatedTime)
+	fields := []*fieldEntity.Field{title, owner, due, remind, created}
+	id := func(f *fieldEntity.Field) string { return f.ID().String() }
+
+	values := map[string]interface{}{
+		id(title):  "@today",
+		id(owner):  "@me",
+		id(due):    "@today+7d",
+		id(remind): "@now+2h",
+	}
+	if err := validateTemplateValues(fields, values); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+
+	invalid := []map[string]interface{}{
+		{"fldMissing": "x"},
+		{id(created): "2026-01-01"},
+		{id(due): "@today+1h"},
+		{id(remind): "@tomorrow"},
+	}
+	for i, v := range invalid {
+		if err := validateTemplateValues(fields, v); err == nil {
+			t.Errorf("模板值 %d 应校验失败", i)
+		}
+	}
+
+	// UTC 2026-10-15 20:00 即上海时间 2026-10-16 04:00
+	now := time.Date(2026, 10, 15, 20, 0, 0, 0, time.UTC)
+	values["fldDeleted"] = "旧字段"
+	resolved := resolveTemplateValues(fields, values, "usr9", now, nil)
+	want := map[string]interface{}{
+		id(title):  "@today",
+		id(owner):  "usr9",
+		id(due):    "2026-10-23",
+		id(remind): "2026-10-15T22:00:00Z",
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Errorf("解析结果 = %v, want %v", resolved, want)
+	}
+}
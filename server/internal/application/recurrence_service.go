@@ -0,0 +1,632 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordtemplate"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recurrence"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var recurrenceLog = logger.Named("recurrence")
+
+const (
+	// recurrenceScanLimit 单次执行从游标向后扫描的计划次数上限（防止长期停机后的超长循环）
+	recurrenceScanLimit = 100000
+	// recurrencePreviewLimit 预览返回的最大次数
+	recurrencePreviewLimit = 50
+)
+
+// recurrenceRecordCreator 按计划时间从模板新建记录（由 RecordTemplateService 实现）
+type recurrenceRecordCreator interface {
+	CreateFromTemplateAt(ctx context.Context, userID, templateID string, at time.Time) (*dto.RecordResponse, error)
+}
+
+// CreateRecurrenceRuleRequest 创建重复记录规则请求
+type CreateRecurrenceRuleRequest struct {
+	Name          string                   `json:"name" binding:"required"`
+	TemplateID    string                   `json:"templateId" binding:"required"`
+	Schedule      recurrence.Schedule      `json:"schedule"`
+	CalendarID    string                   `json:"calendarId"`
+	HolidayPolicy recurrence.HolidayPolicy `json:"holidayPolicy"`
+	SkipDates     []string                 `json:"skipDates"`
+	CatchUp       recurrence.CatchUp       `json:"catchUp"`
+}
+
+// UpdateRecurrenceRuleRequest 更新重复记录规则请求（未提供的字段保持不变，calendarId 为空字符串表示不使用日历）
+type UpdateRecurrenceRuleRequest struct {
+	Name          *string                   `json:"name"`
+	TemplateID    *string                   `json:"templateId"`
+	Schedule      *recurrence.Schedule      `json:"schedule"`
+	CalendarID    *string                   `json:"calendarId"`
+	HolidayPolicy *recurrence.HolidayPolicy `json:"holidayPolicy"`
+	SkipDates     *[]string                 `json:"skipDates"`
+	CatchUp       *recurrence.CatchUp       `json:"catchUp"`
+	Enabled       *bool                     `json:"enabled"`
+}
+
+// SaveHolidayCalendarRequest 创建节假日日历请求
+type SaveHolidayCalendarRequest struct {
+	Name  string   `json:"name" binding:"required"`
+	Dates []string `json:"dates"`
+}
+
+// UpdateHolidayCalendarRequest 更新节假日日历请求（未提供的字段保持不变）
+type UpdateHolidayCalendarRequest struct {
+	Name  *string   `json:"name"`
+	Dates *[]string `json:"dates"`
+}
+
+// RecurrenceService 重复记录规则服务 ✨
+// 按表配置规则，按计划用记录模板定时新建记录（如每周一的站会记录）：
+//   - 计划支持每天/每周/每月、间隔、时区与起止日期；可单独跳过日期，或引用 Base 的节假日日历跳过/顺延
+//   - 后台按下次执行时间领取规则，从游标逐次处理到当前时间；服务停机后按规则补跑全部或只补最近一次
+//   - 每次执行以计划时间为幂等键，记录与执行记录在同一事务中写入，多实例或重复领取不会重复建记录
+//
+// 记录以规则创建人的身份新建，模板中的 @me 与相对日期按创建人和计划时间解析
+type RecurrenceService struct {
+	repo              recurrence.Repository
+	tableRepo         tableRepo.TableRepository
+	templateRepo      recordtemplate.Repository
+	records           recurrenceRecordCreator
+	permissionService *PermissionServiceV2
+	cfg               config.RecurrenceConfig
+	now               func() time.Time
+	transact          func(ctx context.Context, fn func(context.Context) error) error
+}
+
+// NewRecurrenceService 创建重复记录规则服务
+func NewRecurrenceService(
+	repo recurrence.Repository,
+	tableRepository tableRepo.TableRepository,
+	templateRepo recordtemplate.Repository,
+	records recurrenceRecordCreator,
+	db *gorm.DB,
+	permissionService *PermissionServiceV2,
+	cfg config.RecurrenceConfig,
+) *RecurrenceService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 30 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 10
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 10 * time.Minute
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Minute
+	}
+	if cfg.MaxCatchUp <= 0 {
+		cfg.MaxCatchUp = 31
+	}
+	if cfg.RunHistory <= 0 {
+		cfg.RunHistory = 200
+	}
+	return &RecurrenceService{
+		repo:              repo,
+		tableRepo:         tableRepository,
+		templateRepo:      templateRepo,
+		records:           records,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+		transact: func(ctx context.Context, fn func(context.Context) error) error {
+			return database.Transaction(ctx, db, nil, fn)
+		},
+	}
+}
+
+// ListRules 列出表的重复规则
+func (s *RecurrenceService) ListRules(ctx context.Context, userID, tableID string) ([]*recurrence.Rule, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表")
+	}
+	rules, err := s.repo.ListRules(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取重复规则失败")
+	}
+	return rules, nil
+}
+
+// CreateRule 创建重复规则（只处理创建之后的执行）
+func (s *RecurrenceService) CreateRule(ctx context.Context, userID, tableID string, req CreateRecurrenceRuleRequest) (*recurrence.Rule, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的重复规则")
+	}
+	existing, err := s.repo.ListRules(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取重复规则失败")
+	}
+	if len(existing) >= recurrence.MaxRulesPerTable {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("每个表最多 %d 条重复规则", recurrence.MaxRulesPerTable))
+	}
+
+	now := s.now()
+	rule := recurrence.NewRule(tableID, req.TemplateID, req.Name, req.Schedule, userID, now)
+	rule.CalendarID = req.CalendarID
+	rule.HolidayPolicy = req.HolidayPolicy
+	rule.SkipDates = req.SkipDates
+	rule.CatchUp = req.CatchUp
+	if err := s.validateRule(ctx, rule, now); err != nil {
+		return nil, err
+	}
+	if err := s.refreshNextRun(ctx, rule); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return nil, pkgerrors.Database(err, "保存重复规则失败")
+	}
+	return rule, nil
+}
+
+// UpdateRule 更新重复规则；修改计划或重新启用时从当前时间重新开始，不补跑之前的执行
+func (s *RecurrenceService) UpdateRule(ctx context.Context, userID, ruleID string, req UpdateRecurrenceRuleRequest) (*recurrence.Rule, error) {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, rule.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的重复规则")
+	}
+
+	reschedule := false
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.TemplateID != nil {
+		rule.TemplateID = *req.TemplateID
+	}
+	if req.Schedule != nil {
+		rule.Schedule = *req.Schedule
+		reschedule = true
+	}
+	if req.CalendarID != nil {
+		rule.CalendarID = *req.CalendarID
+	}
+	if req.HolidayPolicy != nil {
+		rule.HolidayPolicy = *req.HolidayPolicy
+	}
+	if req.SkipDates != nil {
+		rule.SkipDates = *req.SkipDates
+	}
+	if req.CatchUp != nil {
+		rule.CatchUp = *req.CatchUp
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && !rule.Enabled {
+			reschedule = true
+		}
+		rule.Enabled = *req.Enabled
+	}
+
+	now := s.now()
+	if err := s.validateRule(ctx, rule, now); err != nil {
+		return nil, err
+	}
+	if reschedule {
+		rule.Reschedule(now)
+	}
+	if err := s.refreshNextRun(ctx, rule); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = now
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return nil, pkgerrors.Database(err, "保存重复规则失败")
+	}
+	return rule, nil
+}
+
+// DeleteRule 删除重复规则（已生成的记录保留）
+func (s *RecurrenceService) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, rule.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的重复规则")
+	}
+	if err := s.repo.DeleteRule(ctx, ruleID); err != nil {
+		return pkgerrors.Database(err, "删除重复规则失败")
+	}
+	return nil
+}
+
+// ListRuns 获取规则最近的执行记录
+func (s *RecurrenceService) ListRuns(ctx context.Context, userID, ruleID string) ([]*recurrence.Run, error) {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessTable(ctx, userID, rule.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表")
+	}
+	runs, err := s.repo.ListRuns(ctx, ruleID, s.cfg.RunHistory)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取执行记录失败")
+	}
+	return runs, nil
+}
+
+// PreviewRule 预览规则接下来的 count 次执行（含被跳过的执行及原因）
+func (s *RecurrenceService) PreviewRule(ctx context.Context, userID, ruleID string, count int) ([]recurrence.Occurrence, error) {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessTable(ctx, userID, rule.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表")
+	}
+	if count <= 0 || count > recurrencePreviewLimit {
+		count = recurrencePreviewLimit
+	}
+	holidays, err := s.holidays(ctx, rule.CalendarID)
+	if err != nil {
+		return nil, err
+	}
+
+	after := rule.Cursor
+	if now := s.now(); after.Before(now) {
+		after = now
+	}
+	occurrences := make([]recurrence.Occurrence, 0, count)
+	for len(occurrences) < count {
+		occ, ok := rule.NextOccurrence(after, holidays)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, occ)
+		after = occ.ScheduledAt
+	}
+	return occurrences, nil
+}
+
+// ListCalendars 列出 Base 的节假日日历
+func (s *RecurrenceService) ListCalendars(ctx context.Context, userID, baseID string) ([]*recurrence.Calendar, error) {
+	if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	calendars, err := s.repo.ListCalendars(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取节假日日历失败")
+	}
+	return calendars, nil
+}
+
+// CreateCalendar 创建节假日日历
+func (s *RecurrenceService) CreateCalendar(ctx context.Context, userID, baseID string, req SaveHolidayCalendarRequest) (*recurrence.Calendar, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的节假日日历")
+	}
+	calendar := recurrence.NewCalendar(baseID, req.Name, req.Dates, userID, s.now())
+	if err := calendar.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.SaveCalendar(ctx, calendar); err != nil {
+		return nil, pkgerrors.Database(err, "保存节假日日历失败")
+	}
+	return calendar, nil
+}
+
+// UpdateCalendar 更新节假日日历（从各规则的下一次执行起生效）
+func (s *RecurrenceService) UpdateCalendar(ctx context.Context, userID, calendarID string, req UpdateHolidayCalendarRequest) (*recurrence.Calendar, error) {
+	calendar, err := s.calendar(ctx, calendarID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanUpdateBase(ctx, userID, calendar.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的节假日日历")
+	}
+	if req.Name != nil {
+		calendar.Name = *req.Name
+	}
+	if req.Dates != nil {
+		calendar.Dates = *req.Dates
+	}
+	if err := calendar.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	calendar.UpdatedAt = s.now()
+	if err := s.repo.SaveCalendar(ctx, calendar); err != nil {
+		return nil, pkgerrors.Database(err, "保存节假日日历失败")
+	}
+	return calendar, nil
+}
+
+// DeleteCalendar 删除节假日日历（仍被规则引用时拒绝）
+func (s *RecurrenceService) DeleteCalendar(ctx context.Context, userID, calendarID string) error {
+	calendar, err := s.calendar(ctx, calendarID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanUpdateBase(ctx, userID, calendar.BaseID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的节假日日历")
+	}
+	count, err := s.repo.CountRulesByCalendar(ctx, calendarID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询重复规则失败")
+	}
+	if count > 0 {
+		return pkgerrors.ErrConflict.WithDetails(fmt.Sprintf("节假日日历仍被 %d 条重复规则使用", count))
+	}
+	if err := s.repo.DeleteCalendar(ctx, calendarID); err != nil {
+		return pkgerrors.Database(err, "删除节假日日历失败")
+	}
+	return nil
+}
+
+// Start 启动后台执行
+func (s *RecurrenceService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runDue(ctx)
+		}
+	}()
+}
+
+// runDue 领取并执行到期的规则
+func (s *RecurrenceService) runDue(ctx context.Context) {
+	now := s.now()
+	rules, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), s.cfg.BatchSize)
+	if err != nil {
+		recurrenceLog.Warn(ctx, "领取重复规则失败", logger.ErrorField(err))
+		return
+	}
+	for _, rule := range rules {
+		s.runRule(ctx, rule)
+	}
+}
+
+// runRule 从游标处理到当前时间为止的全部执行并保存进度
+// 可重试的错误（如数据库暂不可用）会停在出错的那次，稍后从该次继续；
+// 模板已删除、创建人已无权限等错误记为失败并继续处理后续执行
+func (s *RecurrenceService) runRule(ctx context.Context, rule *recurrence.Rule) {
+	now := s.now()
+	holidays, err := s.holidays(ctx, rule.CalendarID)
+	if err != nil {
+		s.retryLater(ctx, rule, now, err)
+		return
+	}
+
+	due, dropped, droppedUntil := s.dueOccurrences(rule, holidays, now)
+	if dropped > 0 {
+		recurrenceLog.Warn(ctx, "重复规则错过的执行超过补跑上限，更早的执行不再补建",
+			logger.String("rule_id", rule.ID),
+			logger.Int("dropped", dropped))
+		rule.Cursor = droppedUntil
+	}
+	if rule.CatchUp == recurrence.CatchUpLatest {
+		markMissed(due)
+	}
+
+	for _, occ := range due {
+		recordID, err := s.execute(ctx, rule, occ)
+		if err != nil && !recurrenceFailed(err) {
+			s.retryLater(ctx, rule, now, err)
+			return
+		}
+		if err != nil {
+			rule.LastError = err.Error()
+			run := recurrence.NewRun(rule, occ, recurrence.RunFailed, s.now())
+			run.Error = err.Error()
+			if err := s.repo.SaveRun(ctx, run); err != nil {
+				recurrenceLog.Warn(ctx, "保存执行记录失败", logger.String("rule_id", rule.ID), logger.ErrorField(err))
+			}
+			recurrenceLog.Warn(ctx, "重复规则新建记录失败",
+				logger.String("rule_id", rule.ID),
+				logger.ErrorField(err))
+		} else if recordID != "" {
+			rule.LastError = ""
+			ranAt := s.now()
+			rule.LastRunAt = &ranAt
+		}
+		rule.Cursor = occ.ScheduledAt
+	}
+
+	rule.NextRunAt = nil
+	if occ, ok := rule.NextOccurrence(rule.Cursor, holidays); ok {
+		rule.NextRunAt = &occ.RunAt
+	}
+	if err := s.repo.SaveProgress(ctx, rule); err != nil {
+		recurrenceLog.Warn(ctx, "保存重复规则进度失败", logger.String("rule_id", rule.ID), logger.ErrorField(err))
+	}
+	if len(due) > 0 {
+		if err := s.repo.PurgeRuns(ctx, rule.ID, s.cfg.RunHistory); err != nil {
+			recurrenceLog.Warn(ctx, "清理执行记录失败", logger.String("rule_id", rule.ID), logger.ErrorField(err))
+		}
+	}
+}
+
+// dueOccurrences 游标之后、执行时间不晚于 now 的执行，最多保留最近的 MaxCatchUp 次
+// 超出上限时返回舍弃的次数与最后一次被舍弃的计划时间
+func (s *RecurrenceService) dueOccurrences(rule *recurrence.Rule, holidays map[string]bool, now time.Time) ([]recurrence.Occurrence, int, time.Time) {
+	var (
+		due          []recurrence.Occurrence
+		dropped      int
+		droppedUntil time.Time
+	)
+	after := rule.Cursor
+	for i := 0; i < recurrenceScanLimit; i++ {
+		occ, ok := rule.NextOccurrence(after, holidays)
+		if !ok || occ.RunAt.After(now) {
+			break
+		}
+		due = append(due, occ)
+		if len(due) > s.cfg.MaxCatchUp {
+			droppedUntil = due[0].ScheduledAt
+			due = due[1:]
+			dropped++
+		}
+		after = occ.ScheduledAt
+	}
+	return due, dropped, droppedUntil
+}
+
+// markMissed 只补最近一次：除最后一次需要生成的执行外，其余记为错过
+func markMissed(due []recurrence.Occurrence) {
+	latest := -1
+	for i, occ := range due {
+		if occ.Skip == "" {
+			latest = i
+		}
+	}
+	for i := 0; i < latest; i++ {
+		if due[i].Skip == "" {
+			due[i].Skip = recurrence.SkipMissed
+		}
+	}
+}
+
+// execute 处理一次执行：在同一事务中新建记录并写入执行记录，已有执行记录时不重复处理
+func (s *RecurrenceService) execute(ctx context.Context, rule *recurrence.Rule, occ recurrence.Occurrence) (string, error) {
+	var recordID string
+	err := s.transact(ctx, func(txCtx context.Context) error {
+		exists, err := s.repo.HasRun(txCtx, rule.ID, occ.ScheduledAt)
+		if err != nil {
+			return pkgerrors.Database(err, "查询执行记录失败")
+		}
+		if exists {
+			return nil
+		}
+
+		run := recurrence.NewRun(rule, occ, recurrence.RunSkipped, s.now())
+		if occ.Skip == "" {
+			record, err := s.records.CreateFromTemplateAt(txCtx, rule.CreatedBy, rule.TemplateID, occ.RunAt)
+			if err != nil {
+				return err
+			}
+			run.Status = recurrence.RunCreated
+			run.RecordID = record.ID
+		}
+		if err := s.repo.SaveRun(txCtx, run); err != nil {
+			return pkgerrors.Database(err, "保存执行记录失败")
+		}
+		recordID = run.RecordID
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return recordID, nil
+}
+
+// retryLater 可重试的错误：保留游标，稍后从出错的那次继续
+func (s *RecurrenceService) retryLater(ctx context.Context, rule *recurrence.Rule, now time.Time, cause error) {
+	rule.LastError = cause.Error()
+	next := now.Add(s.cfg.RetryDelay)
+	rule.NextRunAt = &next
+	if err := s.repo.SaveProgress(ctx, rule); err != nil {
+		recurrenceLog.Warn(ctx, "保存重复规则进度失败", logger.String("rule_id", rule.ID), logger.ErrorField(err))
+	}
+	recurrenceLog.Warn(ctx, "重复规则执行失败，稍后重试",
+		logger.String("rule_id", rule.ID),
+		logger.ErrorField(cause))
+}
+
+// recurrenceFailed 错误是否由规则本身导致（模板不存在、值无效、创建人无权限等），重试也不会成功
+func recurrenceFailed(err error) bool {
+	switch pkgerrors.From(err).Category {
+	case pkgerrors.CategoryInvalidArgument, pkgerrors.CategoryPermissionDenied,
+		pkgerrors.CategoryNotFound, pkgerrors.CategoryConflict:
+		return true
+	}
+	return false
+}
+
+// validateRule 校验规则配置，模板须属于同一表，节假日日历须属于表所在的 Base
+func (s *RecurrenceService) validateRule(ctx context.Context, rule *recurrence.Rule, now time.Time) error {
+	if err := rule.Validate(now); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	template, err := s.templateRepo.Get(ctx, rule.TemplateID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找记录模板失败")
+	}
+	if template == nil || template.TableID != rule.TableID {
+		return pkgerrors.ErrValidationFailed.WithDetails("记录模板不存在")
+	}
+	if rule.CalendarID == "" {
+		return nil
+	}
+	calendar, err := s.calendar(ctx, rule.CalendarID)
+	if err != nil {
+		return err
+	}
+	table, err := s.tableRepo.GetByID(ctx, rule.TableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找表失败")
+	}
+	if table == nil || table.BaseID() != calendar.BaseID {
+		return pkgerrors.ErrValidationFailed.WithDetails("节假日日历不属于该表所在的Base")
+	}
+	return nil
+}
+
+// refreshNextRun 按游标重新计算下次执行时间
+func (s *RecurrenceService) refreshNextRun(ctx context.Context, rule *recurrence.Rule) error {
+	holidays, err := s.holidays(ctx, rule.CalendarID)
+	if err != nil {
+		return err
+	}
+	rule.NextRunAt = nil
+	if occ, ok := rule.NextOccurrence(rule.Cursor, holidays); ok {
+		rule.NextRunAt = &occ.RunAt
+	}
+	return nil
+}
+
+// holidays 节假日日历中的日期集合（未使用日历或日历已删除时为空）
+func (s *RecurrenceService) holidays(ctx context.Context, calendarID string) (map[string]bool, error) {
+	if calendarID == "" {
+		return nil, nil
+	}
+	calendar, err := s.repo.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取节假日日历失败")
+	}
+	if calendar == nil {
+		return nil, nil
+	}
+	return calendar.Set(), nil
+}
+
+// rule 获取重复规则
+func (s *RecurrenceService) rule(ctx context.Context, ruleID string) (*recurrence.Rule, error) {
+	rule, err := s.repo.GetRule(ctx, ruleID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取重复规则失败")
+	}
+	if rule == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("重复规则不存在")
+	}
+	return rule, nil
+}
+
+// calendar 获取节假日日历
+func (s *RecurrenceService) calendar(ctx context.Context, calendarID string) (*recurrence.Calendar, error) {
+	calendar, err := s.repo.GetCalendar(ctx, calendarID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取节假日日历失败")
+	}
+	if calendar == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("节假日日历不存在")
+	}
+	return calendar, nil
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recurrence"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryRecurrenceRepo 内存中的重复规则仓储
+type memoryRecurrenceRepo struct {
+	recurrence.Repository
+	runs     map[string]*recurrence.Run
+	progress int
+}
+
+func (r *memoryRecurrenceRepo) runKey(ruleID string, at time.Time) string {
+	return ruleID + "|" + at.UTC().Format(time.RFC3339)
+}
+
+func (r *memoryRecurrenceRepo) HasRun(_ context.Context, ruleID string, scheduledAt time.Time) (bool, error) {
+	_, ok := r.runs[r.runKey(ruleID, scheduledAt)]
+	return ok, nil
+}
+
+func (r *memoryRecurrenceRepo) SaveRun(_ context.Context, run *recurrence.Run) error {
+	key := r.runKey(run.RuleID, run.ScheduledAt)
+	if _, ok := r.runs[key]; ok {
+		return fmt.Errorf("duplicate run %s", key)
+	}
+	r.runs[key] = run
+	return nil
+}
+
+func (r *memoryRecurrenceRepo) SaveProgress(context.Context, *recurrence.Rule) error {
+	r.progress++
+	return nil
+}
+
+func (r *memoryRecurrenceRepo) PurgeRuns(context.Context, string, int) error { return nil }
+
+func (r *memoryRecurrenceRepo) GetCalendar(context.Context, string) (*recurrence.Calendar, error) {
+	return nil, nil
+}
+
+func (r *memoryRecurrenceRepo) count(status recurrence.RunStatus, reason string) int {
+	n := 0
+	for _, run := range r.runs {
+		if run.Status == status && run.Reason == reason {
+			n++
+		}
+	}
+	return n
+}
+
+// stubRecurrenceCreator 记录新建请求，err 非空时返回该错误
+type stubRecurrenceCreator struct {
+	created []time.Time
+	err     error
+}
+
+func (c *stubRecurrenceCreator) CreateFromTemplateAt(_ context.Context, _, _ string, at time.Time) (*dto.RecordResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.created = append(c.created, at)
+	return &dto.RecordResponse{ID: fmt.Sprintf("rec%d", len(c.created))}, nil
+}
+
+type recurrenceFixture struct {
+	repo    *memoryRecurrenceRepo
+	creator *stubRecurrenceCreator
+	service *RecurrenceService
+	rule    *recurrence.Rule
+	now     time.Time
+}
+
+// newRecurrenceFixture 每天 09:00 的规则，于 10-10 创建，当前时间 10-14 12:00（期间服务停机）
+func newRecurrenceFixture(t *testing.T, cfg config.RecurrenceConfig) *recurrenceFixture {
+	t.Helper()
+	created := time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	rule := recurrence.NewRule("tbl1", "rtp1", "日报", recurrence.Schedule{Frequency: recurrence.FrequencyDaily, Time: "09:00"}, "usr1", created)
+	rule.SkipDates = []string{"2026-10-12"}
+	if err := rule.Validate(created); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &recurrenceFixture{
+		repo:    &memoryRecurrenceRepo{runs: map[string]*recurrence.Run{}},
+		creator: &stubRecurrenceCreator{},
+		rule:    rule,
+		now:     time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC),
+	}
+	f.service = NewRecurrenceService(f.repo, nil, nil, f.creator, nil, nil, cfg)
+	f.service.now = func() time.Time { return f.now }
+	f.service.transact = func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }
+	return f
+}
+
+func TestRecurrenceCatchUp(t *testing.T) {
+	f := newRecurrenceFixture(t, config.RecurrenceConfig{})
+	ctx := context.Background()
+	f.service.runRule(ctx, f.rule)
+
+	if len(f.creator.created) != 4 || f.repo.count(recurrence.RunCreated, "") != 4 || f.repo.count(recurrence.RunSkipped, recurrence.SkipDate) != 1 {
+		t.Fatalf("补跑 = %v，执行记录 %d 条", f.creator.created, len(f.repo.runs))
+	}
+	if !f.rule.Cursor.Equal(time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("游标 = %v", f.rule.Cursor)
+	}
+	if f.rule.NextRunAt == nil || !f.rule.NextRunAt.Equal(time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("下次执行 = %v", f.rule.NextRunAt)
+	}
+
+	// 进度未保存（如租约过期后被重新领取）时从旧游标重跑，不会重复新建记录
+	f.rule.Cursor = time.Date(2026, 10, 10, 0, 0, 0, 0, time.UTC)
+	f.service.runRule(ctx, f.rule)
+	if len(f.creator.created) != 4 || len(f.repo.runs) != 5 {
+		t.Errorf("重跑后新建 %d 条，执行记录 %d 条", len(f.creator.created), len(f.repo.runs))
+	}
+}
+
+func TestRecurrenceCatchUpPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	latest := newRecurrenceFixture(t, config.RecurrenceConfig{})
+	latest.rule.CatchUp = recurrence.CatchUpLatest
+	latest.service.runRule(ctx, latest.rule)
+	if len(latest.creator.created) != 1 || !latest.creator.created[0].Equal(time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("只补最近一次 = %v", latest.creator.created)
+	}
+	if latest.repo.count(recurrence.RunSkipped, recurrence.SkipMissed) != 3 {
+		t.Errorf("错过的执行 = %d", latest.repo.count(recurrence.RunSkipped, recurrence.SkipMissed))
+	}
+
+	limited := newRecurrenceFixture(t, config.RecurrenceConfig{MaxCatchUp: 2})
+	limited.service.runRule(ctx, limited.rule)
+	if len(limited.creator.created) != 2 || len(limited.repo.runs) != 2 {
+		t.Errorf("补跑上限 = %v", limited.creator.created)
+	}
+}
+
+func TestRecurrenceErrors(t *testing.T) {
+	ctx := context.Background()
+
+	// 可重试的错误：游标不动，稍后重试
+	transient := newRecurrenceFixture(t, config.RecurrenceConfig{RetryDelay: 5 * time.Minute})
+	transient.creator.err = errors.New("connection refused")
+	cursor := transient.rule.Cursor
+	transient.service.runRule(ctx, transient.rule)
+	if !transient.rule.Cursor.Equal(cursor) || len(transient.repo.runs) != 0 {
+		t.Errorf("可重试错误后游标 = %v，执行记录 %d 条", transient.rule.Cursor, len(transient.repo.runs))
+	}
+	if transient.rule.NextRunAt == nil || !transient.rule.NextRunAt.Equal(transient.now.Add(5*time.Minute)) || transient.rule.LastError == "" {
+		t.Errorf("重试时间 = %v，错误 = %q", transient.rule.NextRunAt, transient.rule.LastError)
+	}
+
+	// 模板已删除：记为失败并继续
+	permanent := newRecurrenceFixture(t, config.RecurrenceConfig{})
+	permanent.creator.err = pkgerrors.ErrNotFound.WithDetails("记录模板不存在")
+	permanent.service.runRule(ctx, permanent.rule)
+	if permanent.repo.count(recurrence.RunFailed, "") != 4 || permanent.rule.LastError == "" {
+		t.Errorf("失败记录 = %d，错误 = %q", permanent.repo.count(recurrence.RunFailed, ""), permanent.rule.LastError)
+	}
+	if !permanent.rule.Cursor.Equal(time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("失败后游标 = %v", permanent.rule.Cursor)
+	}
+}
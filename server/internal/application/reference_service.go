@@ -0,0 +1,489 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// CascadePolicy 删除表格/字段时对引用方的处理策略
+type CascadePolicy string
+
+const (
+	// CascadePolicyBlock 存在引用时拒绝删除
+	CascadePolicyBlock CascadePolicy = "block"
+	// CascadePolicyDetach 保留引用方：依赖字段标记为错误，视图移除相关条件，自动化停用
+	CascadePolicyDetach CascadePolicy = "detach"
+	// CascadePolicyDelete 一并删除依赖字段和引用的自动化，视图移除相关条件
+	CascadePolicyDelete CascadePolicy = "delete"
+)
+
+// 引用目标类型
+const (
+	ReferenceTargetTable = "table"
+	ReferenceTargetField = "field"
+)
+
+// webhookTriggerType 由 Webhook 触发的工作流
+const webhookTriggerType = "webhook"
+
+// ParseCascadePolicy 解析级联策略，未指定时默认 detach
+func ParseCascadePolicy(value string) (CascadePolicy, error) {
+	switch policy := CascadePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return CascadePolicyDetach, nil
+	case CascadePolicyBlock, CascadePolicyDetach, CascadePolicyDelete:
+		return policy, nil
+	default:
+		return "", pkgerrors.ErrValidationFailed.WithDetails(
+			fmt.Sprintf("不支持的级联策略: %s（可选 block / detach / delete）", value))
+	}
+}
+
+// ReferenceReport 表格或字段被其他对象引用的情况
+type ReferenceReport struct {
+	TargetType  string                `json:"targetType"`
+	TargetID    string                `json:"targetId"`
+	Fields      []DependentField      `json:"fields"`
+	Views       []DependentView       `json:"views"`
+	Automations []DependentAutomation `json:"automations"`
+	Webhooks    []DependentAutomation `json:"webhooks"`
+}
+
+// HasReferences 是否存在会因删除而失效的引用（视图仅显示该列不算）
+func (r *ReferenceReport) HasReferences() bool {
+	return len(r.Fields) > 0 || len(r.blockingViews()) > 0 || len(r.Automations) > 0 || len(r.Webhooks) > 0
+}
+
+// blockingViews 在筛选、排序或分组中使用了被删除字段的视图
+func (r *ReferenceReport) blockingViews() []DependentView {
+	var views []DependentView
+	for _, view := range r.Views {
+		for _, usage := range view.Usages {
+			if usage != "column" {
+				views = append(views, view)
+				break
+			}
+		}
+	}
+	return views
+}
+
+// describe 生成引用摘要（用于 block 策略的错误信息）
+func (r *ReferenceReport) describe() string {
+	var parts []string
+	if n := len(r.Fields); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个字段", n))
+	}
+	if n := len(r.blockingViews()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个视图", n))
+	}
+	if n := len(r.Automations); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个自动化", n))
+	}
+	if n := len(r.Webhooks); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个 Webhook", n))
+	}
+	return strings.Join(parts, "、")
+}
+
+// CascadeResult 级联删除的执行结果
+type CascadeResult struct {
+	Policy            CascadePolicy    `json:"policy"`
+	References        *ReferenceReport `json:"references"`
+	DetachedFields    []string         `json:"detachedFields"`
+	DeletedFields     []string         `json:"deletedFields"`
+	UpdatedViews      []string         `json:"updatedViews"`
+	DisabledWorkflows []string         `json:"disabledWorkflows"`
+	DeletedWorkflows  []string         `json:"deletedWorkflows"`
+}
+
+// ReferenceService 引用追踪服务：找出引用表格/字段的关联字段、Lookup/Rollup、视图、自动化和 Webhook，
+// 并按级联策略处理后再执行删除 ✨
+type ReferenceService struct {
+	impact            *SchemaImpactService
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	tableService      *TableService
+	fieldService      *FieldService
+	db                *gorm.DB
+	permissionService *PermissionServiceV2
+	logger            *logger.Module
+}
+
+// NewReferenceService 创建引用追踪服务
+func NewReferenceService(
+	impact *SchemaImpactService,
+	fieldRepo repository.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	tableService *TableService,
+	fieldService *FieldService,
+	db *gorm.DB,
+	permissionService *PermissionServiceV2,
+) *ReferenceService {
+	return &ReferenceService{
+		impact:            impact,
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		tableService:      tableService,
+		fieldService:      fieldService,
+		db:                db,
+		permissionService: permissionService,
+		logger:            logger.Named("reference"),
+	}
+}
+
+// TableReferences 查询其他表格中引用了该表格的对象
+func (s *ReferenceService) TableReferences(ctx context.Context, userID, tableID string) (*ReferenceReport, error) {
+	baseID, err := s.authorize(ctx, userID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return s.tableReferences(ctx, baseID, tableID)
+}
+
+// FieldReferences 查询引用了该字段的对象
+func (s *ReferenceService) FieldReferences(ctx context.Context, userID, fieldID string) (*ReferenceReport, error) {
+	field, baseID, err := s.impact.loadField(ctx, userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fieldReferences(ctx, baseID, field)
+}
+
+// DeleteTable 按级联策略处理引用后删除表格
+// 引用处理与删除在同一事务中执行，任一步失败时全部回滚
+func (s *ReferenceService) DeleteTable(ctx context.Context, userID, tableID string, policy CascadePolicy) (*CascadeResult, error) {
+	baseID, err := s.authorize(ctx, userID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.tableReferences(ctx, baseID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	deletedTables := map[string]bool{tableID: true}
+	if err := s.authorizeAffected(ctx, userID, report, policy, deletedTables); err != nil {
+		return nil, err
+	}
+
+	var result *CascadeResult
+	err = database.Transaction(ctx, s.db, &database.BigTransactionOptions, func(txCtx context.Context) error {
+		var err error
+		if result, err = s.cascade(txCtx, report, policy, deletedTables); err != nil {
+			return err
+		}
+		return s.tableService.DeleteTable(txCtx, tableID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteField 按级联策略处理引用后删除字段
+// 引用处理与删除在同一事务中执行，任一步失败时全部回滚
+func (s *ReferenceService) DeleteField(ctx context.Context, userID, fieldID string, policy CascadePolicy) (*CascadeResult, error) {
+	field, baseID, err := s.impact.loadField(ctx, userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.fieldReferences(ctx, baseID, field)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAffected(ctx, userID, report, policy, nil); err != nil {
+		return nil, err
+	}
+
+	var result *CascadeResult
+	err = database.Transaction(ctx, s.db, &database.BigTransactionOptions, func(txCtx context.Context) error {
+		var err error
+		if result, err = s.cascade(txCtx, report, policy, nil); err != nil {
+			return err
+		}
+		return s.fieldService.DeleteField(txCtx, fieldID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// authorizeAffected 级联会修改其他表格的字段与视图，要求对每个受影响的表格都有表结构管理权限
+func (s *ReferenceService) authorizeAffected(ctx context.Context, userID string, report *ReferenceReport, policy CascadePolicy, deletedTables map[string]bool) error {
+	if policy == CascadePolicyBlock {
+		return nil
+	}
+	checked := map[string]bool{}
+	check := func(tableID string) error {
+		if tableID == "" || deletedTables[tableID] || checked[tableID] {
+			return nil
+		}
+		checked[tableID] = true
+		if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+			return pkgerrors.ErrForbidden.WithDetails(
+				fmt.Sprintf("没有权限修改引用方表格 %s 的结构", tableID))
+		}
+		return nil
+	}
+	for _, dep := range report.Fields {
+		if err := check(dep.TableID); err != nil {
+			return err
+		}
+	}
+	for _, dep := range report.Views {
+		if err := check(dep.TableID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorize 校验表结构管理权限，返回表格所属 Base
+func (s *ReferenceService) authorize(ctx context.Context, userID, tableID string) (string, error) {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return "", pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return "", pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return "", pkgerrors.ErrForbidden.WithDetails("没有权限修改表结构")
+	}
+	return table.BaseID(), nil
+}
+
+// tableReferences 收集表格之外的引用：指向该表的关联字段、经由它们的 Lookup/Rollup、其他表的视图和自动化
+func (s *ReferenceService) tableReferences(ctx context.Context, baseID, tableID string) (*ReferenceReport, error) {
+	fields, err := s.impact.baseFields(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*entity.Field
+	linkers := []DependentField{}
+	for _, field := range fields {
+		if field.TableID() == tableID {
+			targets = append(targets, field)
+		}
+	}
+	// 指向被删除表的关联字段本身也会失效
+	for _, field := range fields {
+		options := field.Options()
+		if field.TableID() == tableID || options == nil || options.Link == nil || options.Link.LinkedTableID != tableID {
+			continue
+		}
+		linkers = append(linkers, DependentField{
+			FieldID:   field.ID().String(),
+			FieldName: field.Name().String(),
+			FieldType: field.Type().String(),
+			TableID:   field.TableID(),
+			Reason:    "link_target",
+			DependsOn: tableID,
+			Direct:    true,
+		})
+		targets = append(targets, field)
+	}
+
+	report := &ReferenceReport{TargetType: ReferenceTargetTable, TargetID: tableID, Fields: linkers}
+	for _, dep := range s.impact.findDependentFields(targets, fields) {
+		if dep.TableID != tableID {
+			report.Fields = append(report.Fields, dep)
+		}
+	}
+
+	ids := []string{tableID}
+	for _, field := range targets {
+		if field.TableID() == tableID {
+			ids = append(ids, field.ID().String())
+		}
+	}
+	if err := s.collect(ctx, report, baseID, ids, map[string]bool{tableID: true}); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// fieldReferences 收集字段的依赖字段、视图和自动化
+func (s *ReferenceService) fieldReferences(ctx context.Context, baseID string, field *entity.Field) (*ReferenceReport, error) {
+	fields, err := s.impact.baseFields(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReferenceReport{
+		TargetType: ReferenceTargetField,
+		TargetID:   field.ID().String(),
+		Fields:     s.impact.findDependentFields([]*entity.Field{field}, fields),
+	}
+	if err := s.collect(ctx, report, baseID, []string{field.ID().String()}, nil); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// collect 补充引用了目标或依赖字段的视图（排除 excludeTables），以及引用了 ids 的自动化/Webhook
+func (s *ReferenceService) collect(ctx context.Context, report *ReferenceReport, baseID string, ids []string, excludeTables map[string]bool) error {
+	affected := make(map[string]bool, len(ids)+len(report.Fields))
+	for _, id := range ids {
+		affected[id] = true
+	}
+	for _, dep := range report.Fields {
+		affected[dep.FieldID] = true
+	}
+
+	tables, err := s.tableRepo.GetByBaseID(ctx, baseID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询表格失败")
+	}
+	report.Views = []DependentView{}
+	for _, table := range tables {
+		if excludeTables[table.ID().String()] {
+			continue
+		}
+		views, err := s.viewRepo.FindByTableID(ctx, table.ID().String())
+		if err != nil {
+			return pkgerrors.Database(err, "查询视图失败")
+		}
+		for _, view := range views {
+			if dep, ok := viewDependency(view, affected); ok {
+				report.Views = append(report.Views, dep)
+			}
+		}
+	}
+
+	workflows, err := s.impact.findAutomations(ctx, ids...)
+	if err != nil {
+		return err
+	}
+	report.Automations = []DependentAutomation{}
+	report.Webhooks = []DependentAutomation{}
+	for _, w := range workflows {
+		if w.TriggerType == webhookTriggerType {
+			report.Webhooks = append(report.Webhooks, w)
+		} else {
+			report.Automations = append(report.Automations, w)
+		}
+	}
+	return nil
+}
+
+// cascade 按策略处理引用方（删除目标本身由调用方在同一事务中完成）
+func (s *ReferenceService) cascade(ctx context.Context, report *ReferenceReport, policy CascadePolicy, deletedTables map[string]bool) (*CascadeResult, error) {
+	result := &CascadeResult{
+		Policy:            policy,
+		References:        report,
+		DetachedFields:    []string{},
+		DeletedFields:     []string{},
+		UpdatedViews:      []string{},
+		DisabledWorkflows: []string{},
+		DeletedWorkflows:  []string{},
+	}
+
+	if policy == CascadePolicyBlock {
+		if report.HasReferences() {
+			return nil, pkgerrors.ErrResourceInUse.WithDetails(
+				fmt.Sprintf("存在 %s 引用，无法删除（可改用 detach 或 delete 策略）", report.describe()))
+		}
+		return result, nil
+	}
+
+	// 1. 依赖字段：detach 标记为错误保留，delete 由深到浅依次删除
+	removed := map[string]bool{}
+	if report.TargetType == ReferenceTargetField {
+		removed[report.TargetID] = true
+	}
+	if policy == CascadePolicyDelete {
+		for i := len(report.Fields) - 1; i >= 0; i-- {
+			fieldID := report.Fields[i].FieldID
+			if err := s.fieldService.DeleteField(ctx, fieldID); err != nil {
+				return nil, err
+			}
+			removed[fieldID] = true
+			result.DeletedFields = append(result.DeletedFields, fieldID)
+		}
+	} else {
+		for _, dep := range report.Fields {
+			field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(dep.FieldID))
+			if err != nil {
+				return nil, pkgerrors.Database(err, "查找依赖字段失败")
+			}
+			if field == nil || field.HasError() {
+				continue
+			}
+			field.MarkAsError()
+			if err := s.fieldRepo.Save(ctx, field); err != nil {
+				return nil, pkgerrors.Database(err, "标记依赖字段失败")
+			}
+			result.DetachedFields = append(result.DetachedFields, dep.FieldID)
+		}
+	}
+
+	// 2. 视图：移除已删除字段的筛选、排序、分组和列配置
+	for _, dep := range report.Views {
+		if deletedTables[dep.TableID] {
+			continue
+		}
+		view, err := s.viewRepo.FindByID(ctx, dep.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || !view.DetachFields(removed) {
+			continue
+		}
+		if err := s.viewRepo.Update(ctx, view); err != nil {
+			return nil, pkgerrors.Database(err, "更新视图失败")
+		}
+		result.UpdatedViews = append(result.UpdatedViews, dep.ViewID)
+	}
+
+	// 3. 自动化与 Webhook：detach 停用，delete 软删除
+	var workflowIDs []string
+	for _, w := range append(append([]DependentAutomation{}, report.Automations...), report.Webhooks...) {
+		workflowIDs = append(workflowIDs, w.WorkflowID)
+	}
+	if len(workflowIDs) > 0 {
+		updates := map[string]interface{}{"is_active": false, "status": "paused", "last_modified_time": time.Now()}
+		if policy == CascadePolicyDelete {
+			updates = map[string]interface{}{"is_active": false, "deleted_time": time.Now()}
+		}
+		if err := database.WithTx(ctx, s.db).WithContext(ctx).Model(&models.Workflow{}).
+			Where("id IN ?", workflowIDs).
+			Updates(updates).Error; err != nil {
+			return nil, pkgerrors.Database(err, "更新引用的自动化失败")
+		}
+		if policy == CascadePolicyDelete {
+			result.DeletedWorkflows = workflowIDs
+		} else {
+			result.DisabledWorkflows = workflowIDs
+		}
+	}
+
+	s.logger.Info(ctx, "已处理删除目标的引用",
+		logger.String("target_type", report.TargetType),
+		logger.String("target_id", report.TargetID),
+		logger.String("policy", string(policy)),
+		logger.Int("detached_fields", len(result.DetachedFields)),
+		logger.Int("deleted_fields", len(result.DeletedFields)),
+		logger.Int("updated_views", len(result.UpdatedViews)),
+		logger.Int("workflows", len(workflowIDs)))
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	collabEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+)
+
+func TestParseCascadePolicy(t *testing.T) {
+	cases := map[string]CascadePolicy{
+		"":        CascadePolicyDetach,
+		"block":   CascadePolicyBlock,
+		" Delete": CascadePolicyDelete,
+	}
+	for input, want := range cases {
+		got, err := ParseCascadePolicy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseCascadePolicy(%q) = %q, %v，期望 %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseCascadePolicy("restrict"); err == nil {
+		t.Errorf("未知策略应返回错误")
+	}
+}
+
+func TestReferenceReportHasReferences(t *testing.T) {
+	report := &ReferenceReport{
+		TargetType: ReferenceTargetField,
+		Views:      []DependentView{{ViewID: "viw1", Usages: []string{"column"}}},
+	}
+	if report.HasReferences() {
+		t.Fatalf("仅作为显示列的视图不应阻止删除")
+	}
+
+	report.Views = append(report.Views, DependentView{ViewID: "viw2", Usages: []string{"column", "filter"}})
+	report.Webhooks = []DependentAutomation{{WorkflowID: "wfl1", TriggerType: webhookTriggerType}}
+	if !report.HasReferences() {
+		t.Fatalf("筛选引用和 Webhook 应阻止删除")
+	}
+	if desc := report.describe(); !strings.Contains(desc, "1 个视图") || !strings.Contains(desc, "1 个 Webhook") {
+		t.Errorf("引用摘要不正确: %s", desc)
+	}
+}
+
+func TestReferenceCascadeAuthorizesEveryAffectedTable(t *testing.T) {
+	fx := newBatchAuthorizationFixture(t)
+	name, _ := tableVO.NewTableName("客户")
+	other, err := tableEntity.NewTable("bse2", name, "usr9")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	otherID := other.ID().String()
+	fx.tables.tables[otherID] = other
+	creator, err := collabEntity.NewCollaborator("bse1", collabEntity.ResourceTypeBase, "usr4", collabEntity.PrincipalTypeUser, collabEntity.RoleCreator, "usr1")
+	if err != nil {
+		t.Fatalf("创建协作者失败: %v", err)
+	}
+	fx.collaborators.collaborators["bse1/usr4"] = creator
+	service := &ReferenceService{permissionService: fx.permissions}
+	ctx := context.Background()
+
+	own := &ReferenceReport{Fields: []DependentField{{FieldID: "fld1", TableID: fx.tableID}}}
+	if err := service.authorizeAffected(ctx, "usr4", own, CascadePolicyDelete, nil); err != nil {
+		t.Fatalf("有权限的表格应放行，得到 %v", err)
+	}
+
+	// 引用方视图所在的表格属于用户无权管理的 Base
+	foreign := &ReferenceReport{
+		Fields: []DependentField{{FieldID: "fld1", TableID: fx.tableID}},
+		Views:  []DependentView{{ViewID: "viw1", TableID: otherID}},
+	}
+	for _, policy := range []CascadePolicy{CascadePolicyDetach, CascadePolicyDelete} {
+		if err := service.authorizeAffected(ctx, "usr4", foreign, policy, nil); err == nil {
+			t.Fatalf("%s 策略会修改无权管理的表格，应被拒绝", policy)
+		}
+	}
+	if err := service.authorizeAffected(ctx, "usr4", foreign, CascadePolicyBlock, nil); err != nil {
+		t.Fatalf("block 策略不修改引用方，得到 %v", err)
+	}
+	if err := service.authorizeAffected(ctx, "usr4", foreign, CascadePolicyDetach, map[string]bool{otherID: true}); err != nil {
+		t.Fatalf("随目标一起删除的表格不需要单独授权，得到 %v", err)
+	}
+}
@@ -0,0 +1,551 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/replication"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+var replicationLog = logger.Named("replication")
+
+// replicationSignatureHeader Webhook 推送的签名头（HMAC-SHA256(secret, body)）
+const replicationSignatureHeader = "X-LuckDB-Signature"
+
+// replicationConnector Debezium source.connector 字段
+const replicationConnector = "luckdb"
+
+// CreateReplicationTargetRequest 创建复制目标请求
+type CreateReplicationTargetRequest struct {
+	Name     string           `json:"name" binding:"required"`
+	Mode     replication.Mode `json:"mode" binding:"required"`
+	URL      string           `json:"url"`
+	TableIDs []string         `json:"tableIds" binding:"required"`
+}
+
+// UpdateReplicationTargetRequest 更新复制目标请求（未提供的字段保持不变）
+type UpdateReplicationTargetRequest struct {
+	Name     *string  `json:"name"`
+	URL      *string  `json:"url"`
+	TableIDs []string `json:"tableIds"`
+	Enabled  *bool    `json:"enabled"`
+}
+
+// ReplicationTargetResponse 复制目标响应
+type ReplicationTargetResponse struct {
+	*replication.Target
+	PublicationName string `json:"publicationName,omitempty"` // 发布模式下供 Debezium 订阅的发布名
+	Secret          string `json:"secret,omitempty"`          // Webhook 签名密钥，仅创建时返回
+}
+
+// DebeziumEvent Debezium 兼容的数据变更事件
+// before/after 以字段ID为键，字段名见同批次的结构变更通知或表字段接口
+type DebeziumEvent struct {
+	Before map[string]interface{} `json:"before"`
+	After  map[string]interface{} `json:"after"`
+	Source DebeziumSource         `json:"source"`
+	Op     string                 `json:"op"` // c/u/d
+	TsMs   int64                  `json:"ts_ms"`
+}
+
+// DebeziumSource 事件来源
+type DebeziumSource struct {
+	Version       string `json:"version"`
+	Connector     string `json:"connector"`
+	Name          string `json:"name"`
+	TsMs          int64  `json:"ts_ms"`
+	DB            string `json:"db"`    // Base ID
+	Table         string `json:"table"` // 表ID
+	Sequence      string `json:"sequence"`
+	RecordID      string `json:"record_id"`
+	RecordVersion int64  `json:"record_version,omitempty"`
+}
+
+// DebeziumSchemaChange 结构变更通知（对应 Debezium 的 schema change 事件）
+type DebeziumSchemaChange struct {
+	Source       DebeziumSource        `json:"source"`
+	TsMs         int64                 `json:"ts_ms"`
+	DatabaseName string                `json:"databaseName"`
+	TableChanges []DebeziumTableChange `json:"tableChanges"`
+}
+
+// DebeziumTableChange 表结构变更
+type DebeziumTableChange struct {
+	Type    string               `json:"type"` // ALTER
+	ID      string               `json:"id"`
+	Field   string               `json:"field"`  // 发生变化的字段ID
+	Action  changefeed.Action    `json:"action"` // 字段的创建、更新或删除
+	Columns []ChangeFeedFieldDef `json:"columns"`
+}
+
+// ReplicationMessage 推送批次中的一条消息
+type ReplicationMessage struct {
+	Key          map[string]string     `json:"key"`
+	Seq          int64                 `json:"seq"`
+	Payload      *DebeziumEvent        `json:"payload,omitempty"`
+	SchemaChange *DebeziumSchemaChange `json:"schemaChange,omitempty"`
+}
+
+// ReplicationService 复制服务 ✨
+// 将动态表的变更发布到外部数据仓库，每个目标按表选择加入：
+//   - publication：维护 PostgreSQL 发布，Debezium 等工具通过逻辑复制直接订阅所选物理表；
+//   - webhook：后台从变更流读取所选表的变更，以 Debezium 兼容的 JSON 批量推送，
+//     所选表的字段变化时推送结构变更通知，推送成功后才推进游标（至少一次投递）
+type ReplicationService struct {
+	repo              replication.Repository
+	changeFeed        *ChangeFeedService
+	tableRepo         tableRepo.TableRepository
+	permissionService *PermissionServiceV2
+	cfg               config.ReplicationConfig
+	client            *http.Client
+}
+
+// NewReplicationService 创建复制服务
+func NewReplicationService(
+	repo replication.Repository,
+	changeFeed *ChangeFeedService,
+	tableRepo tableRepo.TableRepository,
+	permissionService *PermissionServiceV2,
+	cfg config.ReplicationConfig,
+) *ReplicationService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 30 * time.Second
+	}
+	if cfg.LeaseDuration <= cfg.RequestTimeout {
+		cfg.LeaseDuration = 4 * cfg.RequestTimeout
+	}
+	return &ReplicationService{
+		repo:              repo,
+		changeFeed:        changeFeed,
+		tableRepo:         tableRepo,
+		permissionService: permissionService,
+		cfg:               cfg,
+		client:            netguard.NewHTTPClient(cfg.RequestTimeout, cfg.AllowPrivate),
+	}
+}
+
+// CreateTarget 创建复制目标
+// Webhook 目标从当前变更流游标开始投递，历史数据需先通过导出完成全量同步
+func (s *ReplicationService) CreateTarget(ctx context.Context, userID, baseID string, req CreateReplicationTargetRequest) (*ReplicationTargetResponse, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的复制目标")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+	}
+	switch req.Mode {
+	case replication.ModePublication:
+	case replication.ModeWebhook:
+		if err := validateOutboundURL(req.URL, s.cfg.AllowPrivate); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的复制方式: " + string(req.Mode))
+	}
+	tableIDs, err := s.resolveTables(ctx, baseID, req.TableIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	target := replication.NewTarget(baseID, name, req.Mode, req.URL, tableIDs, userID)
+	if target.Mode == replication.ModeWebhook {
+		if target.Cursor, err = s.changeFeed.repo.Head(ctx); err != nil {
+			return nil, pkgerrors.Database(err, "读取变更流游标失败")
+		}
+	} else if err := s.syncPublication(ctx, target); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, target); err != nil {
+		return nil, pkgerrors.Database(err, "保存复制目标失败")
+	}
+
+	resp := toReplicationTargetResponse(target)
+	resp.Secret = target.Secret
+	return resp, nil
+}
+
+// ListTargets 列出 Base 的复制目标
+func (s *ReplicationService) ListTargets(ctx context.Context, userID, baseID string) ([]*ReplicationTargetResponse, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的复制目标")
+	}
+	targets, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取复制目标失败")
+	}
+	list := make([]*ReplicationTargetResponse, 0, len(targets))
+	for _, target := range targets {
+		list = append(list, toReplicationTargetResponse(target))
+	}
+	return list, nil
+}
+
+// GetTarget 获取复制目标
+func (s *ReplicationService) GetTarget(ctx context.Context, userID, targetID string) (*ReplicationTargetResponse, error) {
+	target, err := s.loadTarget(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return toReplicationTargetResponse(target), nil
+}
+
+// UpdateTarget 更新复制目标（加入或移出表、启停、修改推送地址）
+func (s *ReplicationService) UpdateTarget(ctx context.Context, userID, targetID string, req UpdateReplicationTargetRequest) (*ReplicationTargetResponse, error) {
+	target, err := s.loadTarget(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+		}
+		target.Name = name
+	}
+	if req.URL != nil && target.Mode == replication.ModeWebhook {
+		if err := validateOutboundURL(*req.URL, s.cfg.AllowPrivate); err != nil {
+			return nil, err
+		}
+		target.URL = *req.URL
+	}
+	if req.TableIDs != nil {
+		if target.TableIDs, err = s.resolveTables(ctx, target.BaseID, req.TableIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		target.Enabled = *req.Enabled
+	}
+	target.UpdatedAt = time.Now()
+
+	if target.Mode == replication.ModePublication {
+		if err := s.syncPublication(ctx, target); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Save(ctx, target); err != nil {
+		return nil, pkgerrors.Database(err, "保存复制目标失败")
+	}
+	return toReplicationTargetResponse(target), nil
+}
+
+// DeleteTarget 删除复制目标（发布模式同时删除 PostgreSQL 发布）
+func (s *ReplicationService) DeleteTarget(ctx context.Context, userID, targetID string) error {
+	target, err := s.loadTarget(ctx, userID, targetID)
+	if err != nil {
+		return err
+	}
+	if target.Mode == replication.ModePublication {
+		if err := s.repo.DropPublication(ctx, target.PublicationName()); err != nil {
+			return pkgerrors.Database(err, "删除发布失败")
+		}
+	}
+	if err := s.repo.Delete(ctx, targetID); err != nil {
+		return pkgerrors.Database(err, "删除复制目标失败")
+	}
+	return nil
+}
+
+func (s *ReplicationService) loadTarget(ctx context.Context, userID, targetID string) (*replication.Target, error) {
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取复制目标失败")
+	}
+	if target == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("复制目标不存在")
+	}
+	if !s.permissionService.CanUpdateBase(ctx, userID, target.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的复制目标")
+	}
+	return target, nil
+}
+
+// resolveTables 校验所选表属于该 Base（去重并保持顺序）
+func (s *ReplicationService) resolveTables(ctx context.Context, baseID string, tableIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(tableIDs))
+	resolved := make([]string, 0, len(tableIDs))
+	for _, tableID := range tableIDs {
+		if seen[tableID] {
+			continue
+		}
+		seen[tableID] = true
+		table, err := s.tableRepo.GetByID(ctx, tableID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找表格失败")
+		}
+		if table == nil || table.BaseID() != baseID {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("表格不属于该Base: " + tableID)
+		}
+		resolved = append(resolved, tableID)
+	}
+	return resolved, nil
+}
+
+// syncPublication 使发布恰好包含所选表的物理表（停用时清空发布）
+// 逻辑复制直接读取物理表，无法脱敏，设置了脱敏策略的表只能通过 Webhook 模式复制
+func (s *ReplicationService) syncPublication(ctx context.Context, target *replication.Target) error {
+	tables := []string{}
+	if target.Enabled {
+		for _, tableID := range target.TableIDs {
+			table, err := s.tableRepo.GetByID(ctx, tableID)
+			if err != nil {
+				return pkgerrors.Database(err, "查找表格失败")
+			}
+			if table == nil {
+				continue
+			}
+			if privacyService := s.changeFeed.privacyService; privacyService != nil {
+				if policies := privacyService.policies(ctx, tableID); len(policies) > 0 {
+					return pkgerrors.ErrValidationFailed.WithDetails("表格 " + table.Name().String() + " 设置了脱敏策略，发布模式会绕过脱敏，请使用 Webhook 模式")
+				}
+			}
+			if table.DBTableName() == nil {
+				continue
+			}
+			tables = append(tables, *table.DBTableName())
+		}
+	}
+	if err := s.repo.SyncPublication(ctx, target.PublicationName(), tables); err != nil {
+		return pkgerrors.Database(err, "同步发布失败")
+	}
+	return nil
+}
+
+// PublishesTable 表是否被启用的发布模式目标复制（此时不能再为其设置脱敏策略）
+func (s *ReplicationService) PublishesTable(ctx context.Context, baseID, tableID string) (bool, error) {
+	targets, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return false, err
+	}
+	for _, target := range targets {
+		if target.Mode == replication.ModePublication && target.Enabled && target.IncludesTable(tableID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func toReplicationTargetResponse(target *replication.Target) *ReplicationTargetResponse {
+	resp := &ReplicationTargetResponse{Target: target}
+	if target.Mode == replication.ModePublication {
+		resp.PublicationName = target.PublicationName()
+	}
+	return resp
+}
+
+// Start 启动 Webhook 目标的后台投递
+func (s *ReplicationService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.deliverDue(ctx)
+		}
+	}()
+}
+
+// deliverDue 领取并投递到期的目标
+func (s *ReplicationService) deliverDue(ctx context.Context) {
+	now := time.Now()
+	targets, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 10)
+	if err != nil {
+		replicationLog.Warn(ctx, "领取复制目标失败", logger.ErrorField(err))
+		return
+	}
+	for _, target := range targets {
+		s.deliver(ctx, target)
+		if err := s.repo.SaveProgress(ctx, target); err != nil {
+			replicationLog.Warn(ctx, "保存复制进度失败",
+				logger.String("target_id", target.ID),
+				logger.ErrorField(err))
+		}
+	}
+}
+
+// deliver 推送游标之后一批所选表的变更，成功后推进游标
+func (s *ReplicationService) deliver(ctx context.Context, target *replication.Target) {
+	changes, err := s.changeFeed.repo.ListAfter(ctx, target.BaseID, changefeed.ListFilter{
+		After: target.Cursor,
+		Limit: s.cfg.BatchSize,
+	})
+	if err != nil {
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			target.LastError = "游标之后的变更已超出保留期，请重新全量同步后重建该目标"
+		} else {
+			target.LastError = err.Error()
+		}
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	messages, err := s.buildMessages(ctx, target, changes)
+	if err != nil {
+		target.LastError = err.Error()
+		return
+	}
+	if len(messages) > 0 {
+		if err := s.post(ctx, target, messages); err != nil {
+			target.LastError = err.Error()
+			replicationLog.Debug(ctx, "复制推送失败",
+				logger.String("target_id", target.ID),
+				logger.ErrorField(err))
+			return
+		}
+		now := time.Now()
+		target.LastDeliveredAt = &now
+	}
+	target.Cursor = changes[len(changes)-1].Seq
+	target.LastError = ""
+}
+
+// buildMessages 将所选表的记录与字段变更转换为推送消息
+// 记录变更按目标创建者的角色脱敏，与其通过变更流接口读取到的内容一致
+func (s *ReplicationService) buildMessages(ctx context.Context, target *replication.Target, changes []*changefeed.Change) ([]ReplicationMessage, error) {
+	s.changeFeed.maskRecordChanges(authctx.WithUser(ctx, target.CreatedBy), changes)
+
+	messages := make([]ReplicationMessage, 0, len(changes))
+	schemas := map[string][]ChangeFeedFieldDef{}
+	for _, change := range changes {
+		if !target.IncludesTable(change.TableID) {
+			continue
+		}
+		source := DebeziumSource{
+			Version:   "1",
+			Connector: replicationConnector,
+			Name:      target.ID,
+			TsMs:      change.OccurredAt.UnixMilli(),
+			DB:        change.BaseID,
+			Table:     change.TableID,
+			Sequence:  fmt.Sprintf("%d", change.Seq),
+		}
+
+		switch change.EntityType {
+		case changefeed.EntityRecord:
+			event, err := toDebeziumEvent(change, source)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, ReplicationMessage{
+				Key:     map[string]string{"id": change.EntityID},
+				Seq:     change.Seq,
+				Payload: event,
+			})
+		case changefeed.EntityField:
+			columns, ok := schemas[change.TableID]
+			if !ok {
+				defs, err := s.changeFeed.tableSchema(ctx, change.TableID)
+				if err != nil {
+					return nil, err
+				}
+				schemas[change.TableID] = defs
+				columns = defs
+			}
+			messages = append(messages, ReplicationMessage{
+				Key: map[string]string{"table": change.TableID},
+				Seq: change.Seq,
+				SchemaChange: &DebeziumSchemaChange{
+					Source:       source,
+					TsMs:         time.Now().UnixMilli(),
+					DatabaseName: change.BaseID,
+					TableChanges: []DebeziumTableChange{{
+						Type:    "ALTER",
+						ID:      change.TableID,
+						Field:   change.EntityID,
+						Action:  change.Action,
+						Columns: columns,
+					}},
+				},
+			})
+		}
+	}
+	return messages, nil
+}
+
+// toDebeziumEvent 记录变更转换为 Debezium 事件（更新事件的 after 只含变化的字段）
+func toDebeziumEvent(change *changefeed.Change, source DebeziumSource) (*DebeziumEvent, error) {
+	var data map[string]interface{}
+	if len(change.Data) > 0 {
+		if err := json.Unmarshal(change.Data, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode change %d: %w", change.Seq, err)
+		}
+	}
+	source.RecordID = change.EntityID
+	source.RecordVersion = change.Version
+	event := &DebeziumEvent{Source: source, TsMs: time.Now().UnixMilli()}
+	switch change.Action {
+	case changefeed.ActionCreate:
+		event.Op = "c"
+		event.After = data
+	case changefeed.ActionUpdate:
+		event.Op = "u"
+		event.After = data
+	case changefeed.ActionDelete:
+		event.Op = "d"
+		event.Before = data
+	}
+	return event, nil
+}
+
+// post 推送一批消息，非 2xx 响应视为失败
+func (s *ReplicationService) post(ctx context.Context, target *replication.Target, messages []ReplicationMessage) error {
+	body, err := json.Marshal(messages)
+	if err != nil {
+		return fmt.Errorf("failed to encode replication batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build replication request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(replicationSignatureHeader, "sha256="+signReplicationBody(target.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("replication request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("replication endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signReplicationBody 计算推送内容签名
+func signReplicationBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,89 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/replication"
+)
+
+// stubReplicationRepo 记录同步到发布中的物理表
+type stubReplicationRepo struct {
+	replication.Repository
+	published []string
+}
+
+func (r *stubReplicationRepo) SyncPublication(_ context.Context, _ string, tables []string) error {
+	r.published = tables
+	return nil
+}
+
+func TestToDebeziumEvent(t *testing.T) {
+	data := json.RawMessage(`{"fld_a":"x"}`)
+	cases := map[changefeed.Action]string{
+		changefeed.ActionCreate: "c",
+		changefeed.ActionUpdate: "u",
+		changefeed.ActionDelete: "d",
+	}
+	for action, op := range cases {
+		change := &changefeed.Change{Seq: 7, EntityID: "rec1", Action: action, Data: data, Version: 3}
+		event, err := toDebeziumEvent(change, DebeziumSource{Table: "tbl1"})
+		if err != nil {
+			t.Fatalf("转换失败: %v", err)
+		}
+		if event.Op != op {
+			t.Errorf("%s 应映射为 %s，得到 %s", action, op, event.Op)
+		}
+		if event.Source.RecordID != "rec1" || event.Source.RecordVersion != 3 {
+			t.Errorf("source 未携带记录信息: %+v", event.Source)
+		}
+		if action == changefeed.ActionDelete {
+			if event.After != nil || event.Before["fld_a"] != "x" {
+				t.Errorf("删除事件应只有 before: %+v", event)
+			}
+		} else if event.Before != nil || event.After["fld_a"] != "x" {
+			t.Errorf("%s 事件应只有 after: %+v", action, event)
+		}
+	}
+}
+
+func TestSignReplicationBody(t *testing.T) {
+	a := signReplicationBody("secret", []byte("body"))
+	if a != signReplicationBody("secret", []byte("body")) {
+		t.Error("相同内容的签名应一致")
+	}
+	if a == signReplicationBody("other", []byte("body")) {
+		t.Error("不同密钥的签名不应相同")
+	}
+}
+
+func TestReplicationMasksProtectedFields(t *testing.T) {
+	fx := newPrivacyFixture(t)
+	changeFeed := NewChangeFeedService(nil, fx.tables, nil, fx.permissions, fx.service, config.ChangeFeedConfig{})
+	repo := &stubReplicationRepo{}
+	service := NewReplicationService(repo, changeFeed, fx.tables, fx.permissions, config.ReplicationConfig{})
+	ctx := context.Background()
+
+	target := replication.NewTarget("bse1", "仓库", replication.ModeWebhook, "https://example.com/hook", []string{fx.tableID}, "usr2")
+	changes := []*changefeed.Change{{
+		Seq: 1, BaseID: "bse1", TableID: fx.tableID, EntityType: changefeed.EntityRecord, EntityID: "rec1",
+		Action: changefeed.ActionCreate, Data: json.RawMessage(`{"fld_phone":"13800138000","fld_name":"张三"}`),
+	}}
+	messages, err := service.buildMessages(ctx, target, changes)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("构建推送消息失败: %v", err)
+	}
+	after := messages[0].Payload.After
+	if after["fld_phone"] == "13800138000" || after["fld_name"] != "张三" {
+		t.Fatalf("推送内容应按目标创建者的角色脱敏，得到 %v", after)
+	}
+
+	// 逻辑复制直接读取物理表，设置了脱敏策略的表不能加入发布
+	publication := replication.NewTarget("bse1", "发布", replication.ModePublication, "", []string{fx.tableID}, "usr1")
+	if err := service.syncPublication(ctx, publication); err == nil || len(repo.published) != 0 {
+		t.Fatalf("含脱敏策略的表不应加入发布，得到 %v %v", err, repo.published)
+	}
+}
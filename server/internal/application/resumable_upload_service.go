@@ -0,0 +1,373 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+var resumableUploadLog = logger.Named("resumable_upload")
+
+// resumableUploadPrefix 分片在附件存储中的路径前缀
+const resumableUploadPrefix = "resumable"
+
+// CreateUploadSessionRequest 创建可续传上传请求
+type CreateUploadSessionRequest struct {
+	Token    string // 上传令牌（签名接口返回）
+	Filename string
+	Length   int64
+}
+
+// UploadSessionProgress 上传进度
+type UploadSessionProgress struct {
+	*attachment.UploadSession
+	Progress float64 `json:"progress"` // 0-100
+}
+
+// ResumableUploadService 可续传附件上传（tus 协议） ✨
+// 每个分片作为单独的对象写入附件存储，断线后客户端查询已接收的偏移继续上传（可以落到任意实例）；
+// 接收分片时按上传记录占用，多实例部署时同一上传同时只处理一个分片。全部接收后按顺序读取分片，
+// 经附件服务校验并写入存储，之后与普通上传一样调用 notify 创建附件。过期未完成的上传由后台任务清理
+type ResumableUploadService struct {
+	repo        attachment.UploadSessionRepository
+	tokenRepo   attachment.UploadTokenRepository
+	attachments attachment.Service
+	storage     attachment.Storage
+	cfg         config.ResumableUploadConfig
+	now         func() time.Time
+}
+
+// NewResumableUploadService 创建可续传上传服务
+func NewResumableUploadService(
+	repo attachment.UploadSessionRepository,
+	tokenRepo attachment.UploadTokenRepository,
+	attachments attachment.Service,
+	storage attachment.Storage,
+	cfg config.ResumableUploadConfig,
+) *ResumableUploadService {
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = time.Minute
+	}
+	return &ResumableUploadService{
+		repo:        repo,
+		tokenRepo:   tokenRepo,
+		attachments: attachments,
+		storage:     storage,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// MaxSize 单个上传的大小上限（0 表示不限制）
+func (s *ResumableUploadService) MaxSize() int64 {
+	return s.cfg.MaxSize
+}
+
+// Create 创建上传
+func (s *ResumableUploadService) Create(ctx context.Context, userID string, req CreateUploadSessionRequest) (*attachment.UploadSession, error) {
+	if req.Token == "" {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("Upload-Metadata 缺少 token")
+	}
+	if req.Length <= 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("Upload-Length 必须大于 0")
+	}
+	if s.cfg.MaxSize > 0 && req.Length > s.cfg.MaxSize {
+		return nil, pkgerrors.ErrFileTooLarge.WithDetails(fmt.Sprintf("最大 %d 字节", s.cfg.MaxSize))
+	}
+
+	token, err := s.tokenRepo.GetUploadToken(ctx, req.Token)
+	if err != nil {
+		if pkgerrors.Is(err, pkgerrors.ErrNotFound) {
+			return nil, pkgerrors.ErrBadRequest.WithDetails("Invalid upload token")
+		}
+		return nil, pkgerrors.Database(err, "")
+	}
+	if token.IsExpired() {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("Upload token has expired")
+	}
+	if token.UserID != userID {
+		return nil, pkgerrors.ErrForbidden.WithDetails("上传令牌不属于当前用户")
+	}
+	if token.MaxSize > 0 && req.Length > token.MaxSize {
+		return nil, pkgerrors.ErrFileTooLarge.WithDetails(fmt.Sprintf("最大 %d 字节", token.MaxSize))
+	}
+
+	session := attachment.NewUploadSession(token, userID, req.Filename, req.Length, s.cfg.SessionTTL)
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return session, nil
+}
+
+// Get 获取上传（仅创建者可访问）
+func (s *ResumableUploadService) Get(ctx context.Context, userID, id string) (*attachment.UploadSession, error) {
+	session, err := s.repo.GetSession(ctx, id)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if session == nil || session.IsExpired(s.now()) {
+		return nil, pkgerrors.ErrNotFound.WithDetails("上传不存在或已过期")
+	}
+	if session.UserID != userID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("上传不存在或已过期")
+	}
+	return session, nil
+}
+
+// Progress 查询上传进度
+func (s *ResumableUploadService) Progress(ctx context.Context, userID, id string) (*UploadSessionProgress, error) {
+	session, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadSessionProgress{UploadSession: session, Progress: session.Progress()}, nil
+}
+
+// Append 从 offset 处追加分片，返回追加后的偏移
+// 客户端断开时已接收的部分同样保留，客户端查询偏移后继续；全部接收后合并写入附件存储
+func (s *ResumableUploadService) Append(ctx context.Context, userID, id string, offset int64, body io.Reader) (*attachment.UploadSession, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	lockToken := utils.GenerateNanoID(20)
+	locked, err := s.repo.AcquireLock(ctx, id, lockToken, s.now().Add(s.cfg.LockTimeout))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if !locked {
+		return nil, pkgerrors.ErrResourceInUse.WithDetails("该上传正在接收其他分片")
+	}
+	stop := s.keepLock(ctx, id, lockToken)
+	defer func() {
+		stop()
+		if err := s.repo.ReleaseLock(context.WithoutCancel(ctx), id, lockToken); err != nil {
+			resumableUploadLog.Warn(ctx, "释放上传占用失败", logger.String("upload_id", id), logger.ErrorField(err))
+		}
+	}()
+
+	// 占用后重新读取，其他实例可能刚刚追加了分片
+	session, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == attachment.UploadSessionCompleted {
+		return session, nil
+	}
+	if offset != session.Offset {
+		return nil, pkgerrors.ErrConflict.WithDetails(fmt.Sprintf("Upload-Offset 不匹配，当前偏移 %d", session.Offset))
+	}
+
+	if !session.Received() {
+		written, writeErr := s.writePart(ctx, session, body)
+		if written > 0 {
+			to := session.Offset + written
+			ok, err := s.repo.AdvanceOffset(ctx, id, lockToken, session.Offset, to)
+			if err != nil {
+				return nil, pkgerrors.Database(err, "")
+			}
+			if !ok {
+				return nil, pkgerrors.ErrConflict.WithDetails("上传偏移已被修改，请重新查询")
+			}
+			session.Offset = to
+			session.Parts = append(session.Parts, to)
+		}
+		if writeErr != nil {
+			resumableUploadLog.Warn(ctx, "接收分片中断", logger.String("upload_id", id), logger.Int64("offset", session.Offset), logger.ErrorField(writeErr))
+			return session, nil
+		}
+	}
+
+	if session.Received() {
+		if err := s.assemble(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// Terminate 取消上传并删除已保存的分片
+func (s *ResumableUploadService) Terminate(ctx context.Context, userID, id string) error {
+	session, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.remove(ctx, session)
+}
+
+// Start 启动过期上传清理
+func (s *ResumableUploadService) Start(ctx context.Context) {
+	if s.cfg.CleanupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.CleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.CleanupExpired(ctx)
+			}
+		}
+	}()
+}
+
+// CleanupExpired 删除过期上传的记录与分片，返回清理的数量
+func (s *ResumableUploadService) CleanupExpired(ctx context.Context) int {
+	const batchSize = 100
+	count := 0
+	for ctx.Err() == nil {
+		sessions, err := s.repo.ListExpired(ctx, s.now(), batchSize)
+		if err != nil {
+			resumableUploadLog.Warn(ctx, "查询过期上传失败", logger.ErrorField(err))
+			break
+		}
+		for _, session := range sessions {
+			if err := s.remove(ctx, session); err != nil {
+				resumableUploadLog.Warn(ctx, "清理过期上传失败", logger.String("upload_id", session.ID), logger.ErrorField(err))
+				return count
+			}
+			count++
+		}
+		if len(sessions) < batchSize {
+			break
+		}
+	}
+	if count > 0 {
+		resumableUploadLog.Info(ctx, "已清理过期的上传", logger.Int("count", count))
+	}
+	return count
+}
+
+// keepLock 接收分片期间定期续期占用，返回停止续期的函数
+func (s *ResumableUploadService) keepLock(ctx context.Context, id, lockToken string) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.cfg.LockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			locked, err := s.repo.AcquireLock(ctx, id, lockToken, s.now().Add(s.cfg.LockTimeout))
+			if err != nil {
+				resumableUploadLog.Warn(ctx, "上传占用续期失败", logger.String("upload_id", id), logger.ErrorField(err))
+				continue
+			}
+			if !locked {
+				resumableUploadLog.Warn(ctx, "上传占用已被接管，停止续期", logger.String("upload_id", id))
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// writePart 将分片（最多剩余字节数）作为一个对象写入附件存储，返回写入的字节数
+// 先暂存到本次请求的临时文件以确定大小，连接中断时保存已接收的部分
+func (s *ResumableUploadService) writePart(ctx context.Context, session *attachment.UploadSession, body io.Reader) (int64, error) {
+	spool, err := os.CreateTemp("", "luckdb-upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	received, copyErr := io.Copy(spool, io.LimitReader(body, session.Remaining()))
+	if received == 0 {
+		return 0, copyErr
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	path := s.partPath(session.ID, session.Offset, session.Offset+received)
+	if err := s.storage.Upload(ctx, path, spool, received, "application/octet-stream"); err != nil {
+		return 0, err
+	}
+	return received, copyErr
+}
+
+// assemble 按顺序读取分片，交给附件服务校验并写入存储
+func (s *ResumableUploadService) assemble(ctx context.Context, session *attachment.UploadSession) error {
+	readers := make([]io.Reader, 0, len(session.Parts))
+	for _, r := range session.PartRanges() {
+		part, err := s.storage.Download(ctx, s.partPath(session.ID, r[0], r[1]))
+		if err != nil {
+			return pkgerrors.ErrInternalServer.WithDetails("无法读取上传分片")
+		}
+		defer part.Close()
+		readers = append(readers, part)
+	}
+
+	if err := s.attachments.UploadFile(ctx, session.Token, io.MultiReader(readers...), session.Filename, session.Length); err != nil {
+		return err
+	}
+	if err := s.repo.MarkCompleted(ctx, session.ID); err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	session.Status = attachment.UploadSessionCompleted
+	if err := s.deleteParts(ctx, session); err != nil {
+		resumableUploadLog.Warn(ctx, "删除上传分片失败", logger.String("upload_id", session.ID), logger.ErrorField(err))
+	}
+	return nil
+}
+
+// remove 删除上传记录与分片
+func (s *ResumableUploadService) remove(ctx context.Context, session *attachment.UploadSession) error {
+	if err := s.deleteParts(ctx, session); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSession(ctx, session.ID); err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	return nil
+}
+
+// deleteParts 删除已保存的分片（已删除的分片忽略）
+func (s *ResumableUploadService) deleteParts(ctx context.Context, session *attachment.UploadSession) error {
+	for _, r := range session.PartRanges() {
+		path := s.partPath(session.ID, r[0], r[1])
+		exists, err := s.storage.Exists(ctx, path)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := s.storage.Delete(ctx, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partPath 分片 [from, to) 在附件存储中的路径
+// 同一范围的内容总是相同（同一文件的同一段），中断后重传同一范围覆盖写入不影响结果
+func (s *ResumableUploadService) partPath(id string, from, to int64) string {
+	return fmt.Sprintf("%s/%s/%d-%d.part", resumableUploadPrefix, id, from, to)
+}
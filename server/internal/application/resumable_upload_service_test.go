@@ -0,0 +1,298 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+type memoryUploadSessionRepo struct {
+	sessions map[string]*attachment.UploadSession
+	locks    map[string]string // 上传ID -> 占用的 token（模拟按行占用，多个服务实例共享）
+}
+
+func (r *memoryUploadSessionRepo) CreateSession(_ context.Context, session *attachment.UploadSession) error {
+	copied := *session
+	r.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *memoryUploadSessionRepo) GetSession(_ context.Context, id string) (*attachment.UploadSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *memoryUploadSessionRepo) AcquireLock(_ context.Context, id, token string, _ time.Time) (bool, error) {
+	if _, ok := r.sessions[id]; !ok {
+		return false, nil
+	}
+	if holder := r.locks[id]; holder != "" && holder != token {
+		return false, nil
+	}
+	r.locks[id] = token
+	return true, nil
+}
+
+func (r *memoryUploadSessionRepo) ReleaseLock(_ context.Context, id, token string) error {
+	if r.locks[id] == token {
+		delete(r.locks, id)
+	}
+	return nil
+}
+
+func (r *memoryUploadSessionRepo) AdvanceOffset(_ context.Context, id, token string, from, to int64) (bool, error) {
+	session, ok := r.sessions[id]
+	if !ok || r.locks[id] != token || session.Offset != from {
+		return false, nil
+	}
+	session.Offset = to
+	session.Parts = append(append([]int64(nil), session.Parts...), to)
+	return true, nil
+}
+
+func (r *memoryUploadSessionRepo) MarkCompleted(_ context.Context, id string) error {
+	r.sessions[id].Status = attachment.UploadSessionCompleted
+	return nil
+}
+
+func (r *memoryUploadSessionRepo) DeleteSession(_ context.Context, id string) error {
+	delete(r.sessions, id)
+	delete(r.locks, id)
+	return nil
+}
+
+func (r *memoryUploadSessionRepo) ListExpired(_ context.Context, before time.Time, limit int) ([]*attachment.UploadSession, error) {
+	var list []*attachment.UploadSession
+	for _, session := range r.sessions {
+		if session.ExpiresAt.Before(before) && len(list) < limit {
+			list = append(list, session)
+		}
+	}
+	return list, nil
+}
+
+type memoryUploadTokenRepo struct {
+	attachment.UploadTokenRepository
+	tokens map[string]*attachment.UploadToken
+}
+
+func (r *memoryUploadTokenRepo) GetUploadToken(_ context.Context, token string) (*attachment.UploadToken, error) {
+	if t, ok := r.tokens[token]; ok {
+		return t, nil
+	}
+	return nil, pkgerrors.ErrNotFound
+}
+
+// recordingAttachmentService 记录合并后写入存储的内容
+type recordingAttachmentService struct {
+	attachment.Service
+	uploaded map[string]string
+}
+
+func (s *recordingAttachmentService) UploadFile(_ context.Context, token string, reader io.Reader, filename string, size int64) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	s.uploaded[token+"/"+filename] = string(data)
+	return nil
+}
+
+// sharedUploadStorage 多个服务实例共享的附件存储
+type sharedUploadStorage struct {
+	memoryStorage
+}
+
+func (s *sharedUploadStorage) Upload(_ context.Context, path string, reader io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	s.files[path] = string(data)
+	return nil
+}
+
+func (s *sharedUploadStorage) Delete(_ context.Context, path string) error {
+	delete(s.files, path)
+	return nil
+}
+
+// brokenReader 返回部分内容后模拟连接中断
+type brokenReader struct {
+	data string
+	read bool
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, io.ErrUnexpectedEOF
+	}
+	r.read = true
+	return copy(p, r.data), nil
+}
+
+func newTestResumableUploadService(t *testing.T) (*ResumableUploadService, *memoryUploadSessionRepo, *recordingAttachmentService) {
+	t.Helper()
+	svc, repo, files, _ := newTestResumableUploadInstances(t, 1)
+	return svc[0], repo, files
+}
+
+// newTestResumableUploadInstances 共享上传记录与附件存储的多个服务实例
+func newTestResumableUploadInstances(t *testing.T, n int) ([]*ResumableUploadService, *memoryUploadSessionRepo, *recordingAttachmentService, *sharedUploadStorage) {
+	t.Helper()
+	repo := &memoryUploadSessionRepo{sessions: map[string]*attachment.UploadSession{}, locks: map[string]string{}}
+	tokens := &memoryUploadTokenRepo{tokens: map[string]*attachment.UploadToken{
+		"tok1": {Token: "tok1", UserID: "usr1", MaxSize: 100, ExpiresAt: time.Now().Add(time.Hour)},
+	}}
+	files := &recordingAttachmentService{uploaded: map[string]string{}}
+	storage := &sharedUploadStorage{memoryStorage{files: map[string]string{}}}
+	instances := make([]*ResumableUploadService, n)
+	for i := range instances {
+		instances[i] = NewResumableUploadService(repo, tokens, files, storage, config.ResumableUploadConfig{
+			MaxSize:    1000,
+			SessionTTL: 24 * time.Hour,
+		})
+	}
+	return instances, repo, files, storage
+}
+
+func TestResumableUploadResumesAfterInterruption(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, files := newTestResumableUploadService(t)
+
+	session, err := svc.Create(ctx, "usr1", CreateUploadSessionRequest{Token: "tok1", Filename: "a.txt", Length: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !session.ExpiresAt.Before(time.Now().Add(2 * time.Hour)) {
+		t.Error("过期时间不应晚于上传令牌")
+	}
+
+	// 第一个分片中途断开，已写入的部分保留
+	session, err = svc.Append(ctx, "usr1", session.ID, 0, &brokenReader{data: "hello"})
+	if err != nil || session.Offset != 5 {
+		t.Fatalf("中断后应保留已接收的 5 字节，得到 %+v, %v", session, err)
+	}
+
+	if _, err := svc.Append(ctx, "usr1", session.ID, 0, strings.NewReader("hello world")); !pkgerrors.Is(err, pkgerrors.ErrConflict) {
+		t.Errorf("偏移不匹配应返回冲突，得到 %v", err)
+	}
+	if _, err := svc.Get(ctx, "usr2", session.ID); !pkgerrors.Is(err, pkgerrors.ErrNotFound) {
+		t.Errorf("其他用户不应看到上传，得到 %v", err)
+	}
+
+	session, err = svc.Append(ctx, "usr1", session.ID, 5, strings.NewReader(" world and more"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Offset != 11 || session.Status != attachment.UploadSessionCompleted {
+		t.Fatalf("上传应已完成，得到 %+v", session)
+	}
+	if got := files.uploaded["tok1/a.txt"]; got != "hello world" {
+		t.Errorf("合并内容错误: %q", got)
+	}
+	if repo.sessions[session.ID].Status != attachment.UploadSessionCompleted {
+		t.Error("完成状态应已保存")
+	}
+	if _, ok := svc.storage.(*sharedUploadStorage).files[svc.partPath(session.ID, 0, 5)]; ok {
+		t.Error("完成后应删除分片")
+	}
+}
+
+func TestResumableUploadAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	instances, repo, files, storage := newTestResumableUploadInstances(t, 2)
+
+	session, err := instances[0].Create(ctx, "usr1", CreateUploadSessionRequest{Token: "tok1", Filename: "a.txt", Length: 11})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := instances[0].Append(ctx, "usr1", session.ID, 0, strings.NewReader("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 另一个实例正在接收分片时拒绝
+	repo.locks[session.ID] = "other"
+	if _, err := instances[1].Append(ctx, "usr1", session.ID, 5, strings.NewReader(" world")); !pkgerrors.Is(err, pkgerrors.ErrResourceInUse) {
+		t.Fatalf("上传被其他实例占用时应拒绝，得到 %v", err)
+	}
+	delete(repo.locks, session.ID)
+
+	// 重连落到另一个实例，继续上传
+	session, err = instances[1].Append(ctx, "usr1", session.ID, 5, strings.NewReader(" world"))
+	if err != nil || session.Status != attachment.UploadSessionCompleted {
+		t.Fatalf("应在另一个实例完成上传，得到 %+v, %v", session, err)
+	}
+	if got := files.uploaded["tok1/a.txt"]; got != "hello world" {
+		t.Errorf("合并内容错误: %q", got)
+	}
+	if len(storage.files) != 0 {
+		t.Errorf("完成后应删除全部分片，剩余 %v", storage.files)
+	}
+	if len(repo.locks) != 0 {
+		t.Errorf("完成后不应保留占用，剩余 %v", repo.locks)
+	}
+}
+
+func TestResumableUploadValidatesCreate(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestResumableUploadService(t)
+
+	cases := []struct {
+		name   string
+		userID string
+		req    CreateUploadSessionRequest
+	}{
+		{"缺少令牌", "usr1", CreateUploadSessionRequest{Length: 10}},
+		{"无效令牌", "usr1", CreateUploadSessionRequest{Token: "nope", Length: 10}},
+		{"他人令牌", "usr2", CreateUploadSessionRequest{Token: "tok1", Length: 10}},
+		{"超过令牌大小", "usr1", CreateUploadSessionRequest{Token: "tok1", Length: 101}},
+		{"超过服务端上限", "usr1", CreateUploadSessionRequest{Token: "tok1", Length: 1001}},
+	}
+	for _, tc := range cases {
+		if _, err := svc.Create(ctx, tc.userID, tc.req); err == nil {
+			t.Errorf("%s: 应返回错误", tc.name)
+		}
+	}
+}
+
+func TestResumableUploadCleansUpExpired(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _ := newTestResumableUploadService(t)
+
+	session, err := svc.Create(ctx, "usr1", CreateUploadSessionRequest{Token: "tok1", Filename: "a.txt", Length: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Append(ctx, "usr1", session.ID, 0, strings.NewReader("abc")); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := svc.CleanupExpired(ctx); count != 0 {
+		t.Fatalf("未过期的上传不应清理，清理了 %d 个", count)
+	}
+	svc.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+	if count := svc.CleanupExpired(ctx); count != 1 {
+		t.Fatalf("期望清理 1 个，得到 %d", count)
+	}
+	if _, ok := repo.sessions[session.ID]; ok {
+		t.Error("过期上传记录应已删除")
+	}
+	if len(svc.storage.(*sharedUploadStorage).files) != 0 {
+		t.Error("过期上传的分片应已删除")
+	}
+}
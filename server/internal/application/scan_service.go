@@ -0,0 +1,339 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/scanrule"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// scanRecordAccess 读取与新建记录（由 RecordService 实现，读取时按 context 中的用户脱敏）
+type scanRecordAccess interface {
+	GetRecord(ctx context.Context, tableID, recordID string) (*dto.RecordResponse, error)
+	CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// ScanRequest 扫码请求
+type ScanRequest struct {
+	FieldID string `json:"fieldId" binding:"required"` // 按该字段精确匹配编码
+	Code    string `json:"code" binding:"required"`    // 扫到的编码，首尾空白与控制字符被忽略
+}
+
+// ScanResult 扫码结果：找到（或按规则新建）的记录；都没有时 Record 为空
+type ScanResult struct {
+	Code      string              `json:"code"`
+	FieldID   string              `json:"fieldId"`
+	Found     bool                `json:"found"`
+	Created   bool                `json:"created"`
+	Ambiguous bool                `json:"ambiguous"` // 有多条记录匹配，返回最早创建的一条
+	Record    *dto.RecordResponse `json:"record,omitempty"`
+}
+
+// SaveScanRuleRequest 保存扫码规则请求
+type SaveScanRuleRequest struct {
+	OnMissing        string `json:"onMissing"`        // none（默认）| create
+	RecordTemplateID string `json:"recordTemplateId"` // 新建记录时使用的记录模板
+}
+
+// ScanService 扫码查找记录服务 ✨
+// 移动端扫码后按编码精确匹配表中的文本或数字字段查找记录（用于仓库出入库、盘点等扫码即查的场景）：
+//   - 需要记录读取权限，且能读取匹配的字段；加密字段与对当前用户脱敏的字段不能用于扫码匹配
+//   - 字段的扫码规则为 create 时，未找到的编码以新记录写入该字段（可取记录模板中的其余值），需要记录创建权限
+//   - 规则管理需要表结构管理权限
+type ScanService struct {
+	repo              scanrule.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	records           scanRecordAccess
+	templates         *RecordTemplateService
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	now               func() time.Time
+
+	createMu sync.Mutex // 串行化新建，避免同一编码连续扫码时重复建记录
+}
+
+// NewScanService 创建扫码服务
+func NewScanService(
+	repo scanrule.Repository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	records scanRecordAccess,
+	templates *RecordTemplateService,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+) *ScanService {
+	return &ScanService{
+		repo:              repo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		records:           records,
+		templates:         templates,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		now:               time.Now,
+	}
+}
+
+// Scan 按编码查找记录，未找到且字段的扫码规则为 create 时新建记录
+func (s *ScanService) Scan(ctx context.Context, userID, tableID string, req ScanRequest) (*ScanResult, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表记录的权限")
+	}
+	code, err := scanrule.NormalizeCode(req.Code)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	field, err := s.scanField(ctx, tableID, req.FieldID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanReadField(ctx, userID, field.ID().String()) ||
+		(s.privacyService != nil && s.privacyService.ProtectedFieldIDs(ctx, tableID)[field.ID().String()]) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限按该字段查找记录")
+	}
+
+	result := &ScanResult{Code: code, FieldID: field.ID().String()}
+	ids, err := s.match(ctx, tableID, field, code)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		rule, err := s.repo.FindByField(ctx, field.ID().String())
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询扫码规则失败")
+		}
+		// 数字字段扫到非数字编码时不新建
+		if rule == nil || rule.TableID != tableID || !rule.Creates() || scanCellValue(field, code) == nil {
+			return result, nil
+		}
+		return s.create(ctx, userID, tableID, field, rule, result)
+	}
+
+	record, err := s.records.GetRecord(ctx, tableID, ids[0])
+	if err != nil {
+		return nil, err
+	}
+	result.Found, result.Ambiguous, result.Record = true, len(ids) > 1, record
+	return result, nil
+}
+
+// create 以编码新建记录；加锁后再查一次，并发扫同一编码时只新建一条
+func (s *ScanService) create(ctx context.Context, userID, tableID string, field *fieldEntity.Field, rule *scanrule.Rule, result *ScanResult) (*ScanResult, error) {
+	if !s.permissionService.CanCreateRecordsInTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该表格中创建记录")
+	}
+	s.createMu.Lock()
+	defer s.createMu.Unlock()
+
+	ids, err := s.match(ctx, tableID, field, result.Code)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		record, err := s.records.GetRecord(ctx, tableID, ids[0])
+		if err != nil {
+			return nil, err
+		}
+		result.Found, result.Record = true, record
+		return result, nil
+	}
+
+	values := map[string]interface{}{field.ID().String(): scanCellValue(field, result.Code)}
+	var record *dto.RecordResponse
+	if rule.RecordTemplateID != "" {
+		record, err = s.templates.CreateFromTemplate(ctx, userID, rule.RecordTemplateID, CreateFromRecordTemplateRequest{Fields: values})
+	} else {
+		record, err = s.records.CreateRecord(ctx, dto.CreateRecordRequest{TableID: tableID, Data: values}, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Created, result.Record = true, record
+	return result, nil
+}
+
+// match 按字段精确匹配编码，返回最早创建的两条记录ID（多于一条时结果有歧义）
+func (s *ScanService) match(ctx context.Context, tableID string, field *fieldEntity.Field, code string) ([]string, error) {
+	value := scanCellValue(field, code)
+	if value == nil {
+		return nil, nil
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+
+	var ids []string
+	err = s.dataDB(ctx, table.BaseID()).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(table.BaseID(), tableID)).
+		Where(clause.Eq{Column: clause.Column{Name: field.DBFieldName().String()}, Value: value}).
+		Order("__auto_number ASC").
+		Limit(2).
+		Pluck("__id", &ids).Error
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	return ids, nil
+}
+
+// ListRules 列出表的扫码规则
+func (s *ScanService) ListRules(ctx context.Context, userID, tableID string) ([]*scanrule.Rule, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	rules, err := s.repo.ListByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询扫码规则失败")
+	}
+	return rules, nil
+}
+
+// SaveRule 创建或更新字段的扫码规则（需要表结构管理权限）
+func (s *ScanService) SaveRule(ctx context.Context, userID, tableID, fieldID string, req SaveScanRuleRequest) (*scanrule.Rule, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的扫码规则")
+	}
+	field, err := s.scanField(ctx, tableID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByField(ctx, fieldID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询扫码规则失败")
+	}
+	now := s.now()
+	rule := scanrule.NewRule(tableID, fieldID, req.OnMissing, req.RecordTemplateID, userID, now)
+	if existing != nil {
+		rule.ID = existing.ID
+		rule.CreatedBy = existing.CreatedBy
+		rule.CreatedAt = existing.CreatedAt
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if rule.Creates() {
+		if !scanCreatable(field) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("只有单行文本与数字字段可以在扫码未找到时新建记录")
+		}
+		if err := s.checkRecordTemplate(ctx, userID, tableID, rule.RecordTemplateID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Save(ctx, rule); err != nil {
+		return nil, pkgerrors.Database(err, "保存扫码规则失败")
+	}
+	return rule, nil
+}
+
+// DeleteRule 删除字段的扫码规则（需要表结构管理权限）
+func (s *ScanService) DeleteRule(ctx context.Context, userID, tableID, fieldID string) error {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的扫码规则")
+	}
+	rule, err := s.repo.FindByField(ctx, fieldID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询扫码规则失败")
+	}
+	if rule == nil || rule.TableID != tableID {
+		return pkgerrors.ErrNotFound.WithDetails("扫码规则不存在")
+	}
+	if err := s.repo.Delete(ctx, rule.ID); err != nil {
+		return pkgerrors.Database(err, "删除扫码规则失败")
+	}
+	return nil
+}
+
+// checkRecordTemplate 记录模板须属于同一张表
+func (s *ScanService) checkRecordTemplate(ctx context.Context, userID, tableID, templateID string) error {
+	if templateID == "" {
+		return nil
+	}
+	templates, err := s.templates.ListTemplates(ctx, userID, tableID)
+	if err != nil {
+		return err
+	}
+	for _, template := range templates {
+		if template.ID == templateID {
+			return nil
+		}
+	}
+	return pkgerrors.ErrValidationFailed.WithDetails("记录模板不存在")
+}
+
+// scanField 获取用于扫码匹配的字段：须属于该表，且是按文本或数字存储的未加密字段
+func (s *ScanService) scanField(ctx context.Context, tableID, fieldID string) (*fieldEntity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	field := fieldIndex(fields)[fieldID]
+	if field == nil || field.IsDeleted() {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+	if !scanMatchable(field) {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("只能按文本或数字字段扫码查找记录")
+	}
+	return field, nil
+}
+
+// scanMatchable 字段能否按编码精确匹配（加密字段的存储值无法在数据库中比较）
+func scanMatchable(field *fieldEntity.Field) bool {
+	if field.IsEncrypted() {
+		return false
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeSingleLineText, fieldVO.TypeText, fieldVO.TypeNumber, fieldVO.TypeAutoNumber:
+		return true
+	case fieldVO.TypeFormula:
+		kind := columnKindOf(field)
+		return kind == columnKindText || kind == columnKindNumber
+	}
+	return false
+}
+
+// scanCreatable 扫码未找到时能否以编码新建记录（计算字段不能写入）
+func scanCreatable(field *fieldEntity.Field) bool {
+	if field.IsComputed() {
+		return false
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeSingleLineText, fieldVO.TypeText, fieldVO.TypeNumber:
+		return true
+	}
+	return false
+}
+
+// scanCellValue 编码在字段中的值：数字列按数值比较，编码不是数字时返回 nil（不会匹配任何记录）
+func scanCellValue(field *fieldEntity.Field, code string) interface{} {
+	if columnKindOf(field) != columnKindNumber {
+		return code
+	}
+	number, err := strconv.ParseFloat(code, 64)
+	if err != nil {
+		return nil
+	}
+	return number
+}
@@ -0,0 +1,37 @@
+package application
+
+import (
+	"testing"
+
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestScanFieldTypes(t *testing.T) {
+	sku := newTemplateTestField(t, "SKU", fieldVO.TypeSingleLineText)
+	qty := newTemplateTestField(t, "数量", fieldVO.TypeNumber)
+	serial := newTemplateTestField(t, "序号", fieldVO.TypeAutoNumber)
+	photo := newTemplateTestField(t, "照片", fieldVO.TypeAttachment)
+
+	if !scanMatchable(sku) || !scanMatchable(qty) || !scanMatchable(serial) {
+		t.Error("文本、数字与自动编号字段应可用于扫码匹配")
+	}
+	if scanMatchable(photo) {
+		t.Error("附件字段不能用于扫码匹配")
+	}
+	if !scanCreatable(sku) || !scanCreatable(qty) {
+		t.Error("文本与数字字段应可在未找到时新建记录")
+	}
+	if scanCreatable(serial) {
+		t.Error("自动编号由系统生成，不能写入编码")
+	}
+
+	if got := scanCellValue(sku, "00042"); got != "00042" {
+		t.Errorf("文本字段应按原编码匹配，得到 %v", got)
+	}
+	if got := scanCellValue(qty, "00042"); got != float64(42) {
+		t.Errorf("数字字段应按数值匹配，得到 %v", got)
+	}
+	if got := scanCellValue(qty, "SKU-1"); got != nil {
+		t.Errorf("非数字编码不应匹配数字字段，得到 %v", got)
+	}
+}
@@ -0,0 +1,611 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+const (
+	// conversionPreviewScanLimit 类型转换预览最多检查的记录数
+	conversionPreviewScanLimit = 10000
+	// conversionPreviewSampleLimit 类型转换预览返回的无法转换样例数
+	conversionPreviewSampleLimit = 10
+)
+
+// 影响预览的操作
+const (
+	ImpactOperationDeleteField  = "delete_field"
+	ImpactOperationConvertField = "convert_field"
+)
+
+// 影响程度
+const (
+	ImpactSeverityLow    = "low"    // 不丢失数据、不影响其他对象
+	ImpactSeverityMedium = "medium" // 会丢失/清空已有数据
+	ImpactSeverityHigh   = "high"   // 会导致依赖的字段、视图或自动化失效
+)
+
+// SchemaImpactPreview 破坏性表结构操作的影响预览（不执行任何变更）
+type SchemaImpactPreview struct {
+	Operation            string                `json:"operation"`
+	TableID              string                `json:"tableId"`
+	FieldID              string                `json:"fieldId"`
+	FieldName            string                `json:"fieldName"`
+	FieldType            string                `json:"fieldType"`
+	TargetType           string                `json:"targetType,omitempty"`
+	TotalRecords         int64                 `json:"totalRecords"`
+	AffectedRecords      int64                 `json:"affectedRecords"` // 该字段有值的记录数
+	Conversion           *ConversionImpact     `json:"conversion,omitempty"`
+	DependentFields      []DependentField      `json:"dependentFields"`
+	DependentViews       []DependentView       `json:"dependentViews"`
+	DependentAutomations []DependentAutomation `json:"dependentAutomations"`
+	Summary              ImpactSummary         `json:"summary"`
+	SchemaETag           string                `json:"schemaEtag"` // 确认操作时作为 If-Match 传入，保证预览后表结构未变化
+}
+
+// ConversionImpact 类型转换对已有值的影响
+type ConversionImpact struct {
+	Scanned    int64              `json:"scanned"`    // 已检查的有值记录数
+	Compatible int64              `json:"compatible"` // 无需转换即符合目标类型
+	Converted  int64              `json:"converted"`  // 可自动转换
+	Lost       int64              `json:"lost"`       // 无法转换，转换后将被清空
+	Truncated  bool               `json:"truncated"`  // 有值记录超过检查上限，统计为抽样结果
+	Samples    []ConversionSample `json:"samples,omitempty"`
+}
+
+// ConversionSample 无法转换的值样例
+type ConversionSample struct {
+	RecordID string      `json:"recordId"`
+	Value    interface{} `json:"value"`
+	Reason   string      `json:"reason,omitempty"`
+}
+
+// DependentField 依赖该字段的字段（含间接依赖）
+type DependentField struct {
+	FieldID   string `json:"fieldId"`
+	FieldName string `json:"fieldName"`
+	FieldType string `json:"fieldType"`
+	TableID   string `json:"tableId"`
+	Reason    string `json:"reason"` // formula / lookup / rollup / count / symmetric_link / link_display
+	DependsOn string `json:"dependsOn"`
+	Direct    bool   `json:"direct"`
+}
+
+// DependentView 使用了受影响字段的视图
+type DependentView struct {
+	ViewID   string   `json:"viewId"`
+	ViewName string   `json:"viewName"`
+	TableID  string   `json:"tableId"`
+	FieldIDs []string `json:"fieldIds"`
+	Usages   []string `json:"usages"` // filter / sort / group / column
+}
+
+// DependentAutomation 引用了该字段的自动化工作流
+type DependentAutomation struct {
+	WorkflowID  string `json:"workflowId"`
+	Name        string `json:"name"`
+	TriggerType string `json:"triggerType"` // manual / scheduled / event / webhook
+	Status      string `json:"status"`
+	IsActive    bool   `json:"isActive"`
+}
+
+// ImpactSummary 影响摘要
+type ImpactSummary struct {
+	Severity             string   `json:"severity"`
+	RequiresConfirmation bool     `json:"requiresConfirmation"`
+	Messages             []string `json:"messages"`
+}
+
+// SchemaImpactService 破坏性表结构操作（删除字段、类型转换）影响预览服务 ✨
+type SchemaImpactService struct {
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	viewRepo          viewRepo.ViewRepository
+	fieldService      *FieldService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	db                *gorm.DB
+	permissionService *PermissionServiceV2
+	validators        *validation.ValidatorFactory
+}
+
+// NewSchemaImpactService 创建影响预览服务
+func NewSchemaImpactService(
+	fieldRepo repository.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	fieldService *FieldService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	db *gorm.DB,
+	permissionService *PermissionServiceV2,
+) *SchemaImpactService {
+	return &SchemaImpactService{
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		viewRepo:          viewRepo,
+		fieldService:      fieldService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		db:                db,
+		permissionService: permissionService,
+		validators:        validation.NewValidatorFactory(),
+	}
+}
+
+// PreviewDeleteField 预览删除字段的影响
+func (s *SchemaImpactService) PreviewDeleteField(ctx context.Context, userID, fieldID string) (*SchemaImpactPreview, error) {
+	field, baseID, err := s.loadField(ctx, userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := s.buildPreview(ctx, ImpactOperationDeleteField, baseID, field)
+	if err != nil {
+		return nil, err
+	}
+	preview.summarize()
+	return preview, nil
+}
+
+// PreviewConvertField 预览字段类型转换的影响（检查已有值能否转换为目标类型）
+func (s *SchemaImpactService) PreviewConvertField(ctx context.Context, userID, fieldID, targetType string) (*SchemaImpactPreview, error) {
+	fieldType, err := valueobject.NewFieldType(targetType)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的字段类型: %s", targetType))
+	}
+
+	field, baseID, err := s.loadField(ctx, userID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if field.Type().String() == fieldType.String() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("目标类型与当前类型相同")
+	}
+	if field.IsEncrypted() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("加密字段不支持类型转换")
+	}
+
+	preview, err := s.buildPreview(ctx, ImpactOperationConvertField, baseID, field)
+	if err != nil {
+		return nil, err
+	}
+	preview.TargetType = fieldType.String()
+
+	if isVirtualFieldType(fieldType.String()) {
+		// 转换为计算字段后已有值全部由计算结果替换
+		preview.Conversion = &ConversionImpact{Lost: preview.AffectedRecords}
+	} else {
+		target, err := entity.NewField(field.TableID(), field.Name(), fieldType, userID)
+		if err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无法创建目标类型字段: %v", err))
+		}
+		conversion, err := s.scanConversion(ctx, baseID, field, target)
+		if err != nil {
+			return nil, err
+		}
+		conversion.Truncated = conversion.Scanned < preview.AffectedRecords
+		preview.Conversion = conversion
+	}
+
+	preview.summarize()
+	return preview, nil
+}
+
+// loadField 获取字段并校验表结构管理权限
+func (s *SchemaImpactService) loadField(ctx context.Context, userID, fieldID string) (*entity.Field, string, error) {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(fieldID))
+	if err != nil {
+		return nil, "", pkgerrors.Database(err, "查找字段失败")
+	}
+	if field == nil {
+		return nil, "", pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, field.TableID()) {
+		return nil, "", pkgerrors.ErrForbidden.WithDetails("没有权限修改表结构")
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return nil, "", pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, "", pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+	return field, table.BaseID(), nil
+}
+
+// buildPreview 统计记录数并收集依赖的字段、视图和自动化
+func (s *SchemaImpactService) buildPreview(ctx context.Context, operation, baseID string, field *entity.Field) (*SchemaImpactPreview, error) {
+	preview := &SchemaImpactPreview{
+		Operation:            operation,
+		TableID:              field.TableID(),
+		FieldID:              field.ID().String(),
+		FieldName:            field.Name().String(),
+		FieldType:            field.Type().String(),
+		DependentViews:       []DependentView{},
+		DependentAutomations: []DependentAutomation{},
+	}
+
+	db := s.dataDB(ctx, baseID).WithContext(ctx)
+	tableName := s.dbProvider.GenerateTableName(baseID, field.TableID())
+	if err := db.Table(tableName).Count(&preview.TotalRecords).Error; err != nil {
+		return nil, pkgerrors.Database(err, "统计记录数失败")
+	}
+	notNull := clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{clause.Column{Name: field.DBFieldName().String()}}}
+	if err := db.Table(tableName).Where(notNull).Count(&preview.AffectedRecords).Error; err != nil {
+		return nil, pkgerrors.Database(err, "统计受影响记录数失败")
+	}
+
+	// 依赖可能跨表（Lookup/Rollup/双向关联），在整个 Base 内查找
+	fields, err := s.baseFields(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	preview.DependentFields = s.findDependentFields([]*entity.Field{field}, fields)
+
+	affected := map[string]bool{field.ID().String(): true}
+	tables := map[string]bool{field.TableID(): true}
+	if operation == ImpactOperationDeleteField {
+		for _, dep := range preview.DependentFields {
+			affected[dep.FieldID] = true
+			tables[dep.TableID] = true
+		}
+	}
+	for tableID := range tables {
+		views, err := s.viewRepo.FindByTableID(ctx, tableID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询视图失败")
+		}
+		for _, view := range views {
+			if dep, ok := viewDependency(view, affected); ok {
+				preview.DependentViews = append(preview.DependentViews, dep)
+			}
+		}
+	}
+
+	automations, err := s.findAutomations(ctx, field.ID().String())
+	if err != nil {
+		return nil, err
+	}
+	preview.DependentAutomations = automations
+
+	if s.fieldService != nil {
+		if _, tag, err := s.fieldService.ListFieldsWithETag(ctx, field.TableID()); err == nil {
+			preview.SchemaETag = tag
+		}
+	}
+	return preview, nil
+}
+
+// baseFields 获取 Base 下所有表格的字段
+func (s *SchemaImpactService) baseFields(ctx context.Context, baseID string) ([]*entity.Field, error) {
+	tables, err := s.tableRepo.GetByBaseID(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询表格失败")
+	}
+	var fields []*entity.Field
+	for _, table := range tables {
+		tableFields, err := s.fieldRepo.FindByTableID(ctx, table.ID().String())
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询字段失败")
+		}
+		fields = append(fields, tableFields...)
+	}
+	return fields, nil
+}
+
+// findDependentFields 查找直接和间接依赖 targets 的字段（广度优先，被依赖字段失效会向下传递）
+func (s *SchemaImpactService) findDependentFields(targets []*entity.Field, fields []*entity.Field) []DependentField {
+	dependents := []DependentField{}
+	visited := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		visited[target.ID().String()] = true
+	}
+	queue := append([]*entity.Field{}, targets...)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, candidate := range fields {
+			if visited[candidate.ID().String()] {
+				continue
+			}
+			reason, ok := s.fieldReference(candidate, current)
+			if !ok {
+				continue
+			}
+			visited[candidate.ID().String()] = true
+			queue = append(queue, candidate)
+			dependents = append(dependents, DependentField{
+				FieldID:   candidate.ID().String(),
+				FieldName: candidate.Name().String(),
+				FieldType: candidate.Type().String(),
+				TableID:   candidate.TableID(),
+				Reason:    reason,
+				DependsOn: current.ID().String(),
+				Direct:    s.isTarget(current, targets),
+			})
+		}
+	}
+	return dependents
+}
+
+// isTarget 判断字段是否为被删除/转换的目标字段本身
+func (s *SchemaImpactService) isTarget(field *entity.Field, targets []*entity.Field) bool {
+	for _, target := range targets {
+		if field == target {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldReference 判断 field 是否引用了 target，返回引用方式
+func (s *SchemaImpactService) fieldReference(field, target *entity.Field) (string, bool) {
+	options := field.Options()
+	if options == nil {
+		return "", false
+	}
+	targetID := target.ID().String()
+
+	switch {
+	case options.Formula != nil:
+		for _, ref := range s.fieldService.extractFormulaDependencies(field) {
+			// 公式按名称引用时只能解析到同一张表的字段
+			if ref == targetID || (ref == target.Name().String() && field.TableID() == target.TableID()) {
+				return "formula", true
+			}
+		}
+	case options.Lookup != nil:
+		if options.Lookup.LinkFieldID == targetID || options.Lookup.LookupFieldID == targetID {
+			return "lookup", true
+		}
+	case options.Rollup != nil:
+		if options.Rollup.LinkFieldID == targetID || options.Rollup.RollupFieldID == targetID {
+			return "rollup", true
+		}
+	case options.Count != nil:
+		if options.Count.LinkFieldID == targetID {
+			return "count", true
+		}
+	case options.Link != nil:
+		if options.Link.SymmetricFieldID == targetID {
+			return "symmetric_link", true
+		}
+		if options.Link.LookupFieldID == targetID {
+			return "link_display", true
+		}
+	}
+	return "", false
+}
+
+// viewDependency 视图对受影响字段的使用情况
+func viewDependency(view *viewEntity.View, affected map[string]bool) (DependentView, bool) {
+	usages := map[string][]string{
+		"filter": view.Filter().GetFieldIDs(),
+		"sort":   view.Sort().GetFieldIDs(),
+		"group":  view.Group().GetFieldIDs(),
+	}
+	if view.ColumnMeta() != nil {
+		usages["column"] = view.ColumnMeta().GetFieldIDs()
+	}
+
+	dep := DependentView{ViewID: view.ID(), ViewName: view.Name(), TableID: view.TableID()}
+	fieldIDs := map[string]bool{}
+	for _, usage := range []string{"filter", "sort", "group", "column"} {
+		used := false
+		for _, fieldID := range usages[usage] {
+			if affected[fieldID] {
+				used = true
+				fieldIDs[fieldID] = true
+			}
+		}
+		if used {
+			dep.Usages = append(dep.Usages, usage)
+		}
+	}
+	if len(dep.Usages) == 0 {
+		return dep, false
+	}
+	for fieldID := range fieldIDs {
+		dep.FieldIDs = append(dep.FieldIDs, fieldID)
+	}
+	sort.Strings(dep.FieldIDs)
+	return dep, true
+}
+
+// findAutomations 查找触发条件或节点配置中引用了任一 ID（字段或表格）的工作流
+func (s *SchemaImpactService) findAutomations(ctx context.Context, ids ...string) ([]DependentAutomation, error) {
+	if len(ids) == 0 {
+		return []DependentAutomation{}, nil
+	}
+
+	nodeQuery := s.db.WithContext(ctx)
+	triggerQuery := s.db.WithContext(ctx)
+	for _, id := range ids {
+		pattern := "%" + id + "%"
+		nodeQuery = nodeQuery.Or("CAST(config AS TEXT) LIKE ? OR CAST(condition AS TEXT) LIKE ? OR CAST(action AS TEXT) LIKE ?", pattern, pattern, pattern)
+		triggerQuery = triggerQuery.Or("CAST(trigger_config AS TEXT) LIKE ?", pattern)
+	}
+	nodes := s.db.WithContext(ctx).Model(&models.WorkflowNode{}).
+		Select("workflow_id").
+		Where("deleted_time IS NULL").
+		Where(nodeQuery)
+
+	var workflows []models.Workflow
+	if err := s.db.WithContext(ctx).
+		Where("deleted_time IS NULL").
+		Where(triggerQuery.Or("id IN (?)", nodes)).
+		Order("created_time DESC").
+		Find(&workflows).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查询自动化失败")
+	}
+
+	automations := make([]DependentAutomation, 0, len(workflows))
+	for _, w := range workflows {
+		automations = append(automations, DependentAutomation{
+			WorkflowID:  w.ID,
+			Name:        w.Name,
+			TriggerType: w.TriggerType,
+			Status:      w.Status,
+			IsActive:    w.IsActive,
+		})
+	}
+	return automations, nil
+}
+
+// scanConversion 检查已有值能否转换为目标类型（最多检查 conversionPreviewScanLimit 条）
+func (s *SchemaImpactService) scanConversion(ctx context.Context, baseID string, field, target *entity.Field) (*ConversionImpact, error) {
+	column := field.DBFieldName().String()
+	rows, err := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, field.TableID())).
+		Select("__id", column).
+		Where(clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{clause.Column{Name: column}}}).
+		Order("__id").
+		Limit(conversionPreviewScanLimit).
+		Rows()
+	if err != nil {
+		return nil, pkgerrors.Database(err, "读取字段值失败")
+	}
+	defer rows.Close()
+
+	impact := &ConversionImpact{}
+	for rows.Next() {
+		var recordID string
+		var raw interface{}
+		if err := rows.Scan(&recordID, &raw); err != nil {
+			return nil, pkgerrors.Database(err, "读取字段值失败")
+		}
+		value := decodeColumnValue(raw)
+		impact.Scanned++
+
+		result := s.validators.ValidateField(ctx, value, target)
+		if result.Success {
+			impact.Compatible++
+			continue
+		}
+		if repaired := s.validators.RepairValue(ctx, value, target); repaired != nil {
+			impact.Converted++
+			continue
+		}
+		impact.Lost++
+		if len(impact.Samples) < conversionPreviewSampleLimit {
+			sample := ConversionSample{RecordID: recordID, Value: value}
+			if result.Error != nil {
+				sample.Reason = result.Error.Error()
+			}
+			impact.Samples = append(impact.Samples, sample)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Database(err, "读取字段值失败")
+	}
+	return impact, nil
+}
+
+// decodeColumnValue 将物理列的原始值转换为单元格值（JSONB 列解析为 JSON）
+func decodeColumnValue(raw interface{}) interface{} {
+	var text string
+	switch v := raw.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return raw
+	}
+	var decoded interface{}
+	if (len(text) > 0 && (text[0] == '{' || text[0] == '[')) && json.Unmarshal([]byte(text), &decoded) == nil {
+		return decoded
+	}
+	return text
+}
+
+// summarize 根据统计结果生成影响摘要
+func (p *SchemaImpactPreview) summarize() {
+	summary := ImpactSummary{Severity: ImpactSeverityLow, Messages: []string{}}
+	raise := func(severity string) {
+		if severity == ImpactSeverityHigh || summary.Severity == ImpactSeverityLow {
+			summary.Severity = severity
+		}
+	}
+
+	switch p.Operation {
+	case ImpactOperationDeleteField:
+		if p.AffectedRecords > 0 {
+			raise(ImpactSeverityMedium)
+			summary.Messages = append(summary.Messages, fmt.Sprintf("将删除 %d 条记录中该字段的数据", p.AffectedRecords))
+		}
+	case ImpactOperationConvertField:
+		if p.Conversion != nil && p.Conversion.Lost > 0 {
+			raise(ImpactSeverityMedium)
+			message := fmt.Sprintf("%d 个值无法转换为 %s，转换后将被清空", p.Conversion.Lost, p.TargetType)
+			if p.Conversion.Truncated {
+				message += fmt.Sprintf("（仅检查了前 %d 条记录）", p.Conversion.Scanned)
+			}
+			summary.Messages = append(summary.Messages, message)
+		}
+	}
+
+	if len(p.DependentFields) > 0 {
+		raise(ImpactSeverityHigh)
+		direct := 0
+		for _, dep := range p.DependentFields {
+			if dep.Direct {
+				direct++
+			}
+		}
+		summary.Messages = append(summary.Messages, fmt.Sprintf("%d 个字段依赖该字段（直接依赖 %d 个），将无法计算",
+			len(p.DependentFields), direct))
+	}
+
+	broken := 0
+	for _, view := range p.DependentViews {
+		for _, usage := range view.Usages {
+			if usage != "column" {
+				broken++
+				break
+			}
+		}
+	}
+	if broken > 0 {
+		raise(ImpactSeverityHigh)
+		summary.Messages = append(summary.Messages, fmt.Sprintf("%d 个视图的筛选、排序或分组使用了受影响的字段", broken))
+	}
+
+	if len(p.DependentAutomations) > 0 {
+		active := 0
+		for _, automation := range p.DependentAutomations {
+			if automation.IsActive {
+				active++
+			}
+		}
+		if active > 0 {
+			raise(ImpactSeverityHigh)
+		} else {
+			raise(ImpactSeverityMedium)
+		}
+		summary.Messages = append(summary.Messages, fmt.Sprintf("%d 个自动化引用了该字段（其中 %d 个已启用）",
+			len(p.DependentAutomations), active))
+	}
+
+	summary.RequiresConfirmation = summary.Severity != ImpactSeverityLow
+	p.Summary = summary
+}
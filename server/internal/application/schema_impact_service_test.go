@@ -0,0 +1,30 @@
+package application
+
+import "testing"
+
+func TestSchemaImpactSummarize(t *testing.T) {
+	p := &SchemaImpactPreview{Operation: ImpactOperationDeleteField}
+	p.summarize()
+	if p.Summary.Severity != ImpactSeverityLow || p.Summary.RequiresConfirmation {
+		t.Fatalf("空字段且无依赖时应为低影响，得到 %+v", p.Summary)
+	}
+
+	p = &SchemaImpactPreview{
+		Operation:  ImpactOperationConvertField,
+		TargetType: "number",
+		Conversion: &ConversionImpact{Scanned: 100, Lost: 3},
+		DependentViews: []DependentView{
+			{ViewID: "viw1", Usages: []string{"column"}},
+		},
+	}
+	p.summarize()
+	if p.Summary.Severity != ImpactSeverityMedium || !p.Summary.RequiresConfirmation || len(p.Summary.Messages) != 1 {
+		t.Fatalf("仅有数据丢失时应为中等影响，得到 %+v", p.Summary)
+	}
+
+	p.DependentFields = []DependentField{{FieldID: "fld2", Reason: "formula", Direct: true}}
+	p.summarize()
+	if p.Summary.Severity != ImpactSeverityHigh || len(p.Summary.Messages) != 2 {
+		t.Fatalf("存在依赖字段时应为高影响，得到 %+v", p.Summary)
+	}
+}
@@ -0,0 +1,773 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemamigration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var schemaMigrationLog = logger.Named("schema_migration")
+
+// schemaMigrationListLimit 表格结构变更任务列表返回的最大条数
+const schemaMigrationListLimit = 50
+
+// DataDBResolver 获取 Base 物理表（数据面）所在的数据库连接
+type DataDBResolver func(ctx context.Context, baseID string) *gorm.DB
+
+// SchemaMigrationService 动态表结构变更编排服务 ✨
+// 请求内只执行不重写数据的 DDL（可空 ADD COLUMN、移除约束）和字段元数据变更；
+// 默认值回填、NOT NULL/UNIQUE 约束、列类型修改、DROP COLUMN 由后台任务分批执行，进度持久化在任务中
+type SchemaMigrationService struct {
+	repo              schemamigration.Repository
+	fieldRepo         repository.FieldRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	cfg               config.SchemaMigrationConfig
+	wake              chan struct{}
+	now               func() time.Time
+
+	// onFieldChanged 后台任务更新字段元数据后的通知（广播与变更流，由 FieldService 设置）
+	onFieldChanged func(ctx context.Context, field *entity.Field)
+}
+
+// NewSchemaMigrationService 创建结构变更编排服务
+func NewSchemaMigrationService(
+	repo schemamigration.Repository,
+	fieldRepo repository.FieldRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	cfg config.SchemaMigrationConfig,
+) *SchemaMigrationService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.StaleTimeout <= 0 {
+		cfg.StaleTimeout = 10 * time.Minute
+	}
+	return &SchemaMigrationService{
+		repo:              repo,
+		fieldRepo:         fieldRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		cfg:               cfg,
+		wake:              make(chan struct{}, 1),
+		now:               time.Now,
+	}
+}
+
+// ==================== 请求内同步阶段 ====================
+
+// AddColumn 为新字段创建物理列并保存字段元数据
+// 物理列以可空、无约束方式创建（不重写已有数据），元数据保存失败时删除物理列；
+// 默认值回填与必填/唯一约束由后台任务完成
+func (s *SchemaMigrationService) AddColumn(ctx context.Context, baseID string, field *entity.Field, userID string) error {
+	m := schemamigration.NewMigration(schemamigration.OperationAddColumn,
+		baseID, field.TableID(), field.ID().String(), field.DBFieldName().String(), userID)
+	m.ColumnType = field.DBFieldType()
+	m.NotNull = field.IsRequired()
+	m.Unique = field.IsUnique()
+	m.BackfillValue = backfillValue(field)
+	if err := s.repo.Save(ctx, m); err != nil {
+		return pkgerrors.Database(err, "创建结构变更任务失败")
+	}
+
+	// 没有默认值的必填字段只能加到空表上（已有记录无法满足 NOT NULL）
+	if m.NotNull && m.BackfillValue == nil {
+		hasRows, err := s.hasRows(ctx, m)
+		if err != nil {
+			s.finish(ctx, m, schemamigration.StatusCancelled, err)
+			return pkgerrors.Database(err, "检查表格记录失败")
+		}
+		if hasRows {
+			s.finish(ctx, m, schemamigration.StatusCancelled, errors.New("table has records and no default value"))
+			return pkgerrors.ErrValidationFailed.WithDetails("表格中已有记录，必填字段需要设置默认值")
+		}
+	}
+
+	columnDef := database.ColumnDefinition{Name: m.Column, Type: m.ColumnType}
+	if err := s.dbProvider.AddColumn(ctx, baseID, m.TableID, columnDef); err != nil {
+		schemaMigrationLog.Error(ctx, "创建物理表列失败",
+			logger.String("field_id", m.FieldID), logger.String("column", m.Column), logger.ErrorField(err))
+		s.finish(ctx, m, schemamigration.StatusCancelled, err)
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建物理表列失败: %v", err))
+	}
+
+	if err := s.fieldRepo.Save(ctx, field); err != nil {
+		// 元数据未保存：撤销物理列，撤销失败时交由后台任务删除
+		if dropErr := s.dbProvider.DropColumn(ctx, baseID, m.TableID, m.Column); dropErr != nil {
+			schemaMigrationLog.Warn(ctx, "撤销物理表列失败，稍后由后台任务删除",
+				logger.String("column", m.Column), logger.ErrorField(dropErr))
+			s.enqueueDrop(ctx, m)
+		}
+		s.finish(ctx, m, schemamigration.StatusCancelled, err)
+		return pkgerrors.Database(err, "保存字段失败")
+	}
+
+	if m.HasDeferredWork() {
+		m.Enqueue(schemamigration.PhaseBackfill, s.now())
+		s.save(ctx, m)
+		s.notify()
+		return nil
+	}
+	s.finish(ctx, m, schemamigration.StatusCompleted, nil)
+	return nil
+}
+
+// DropColumn 删除字段元数据，物理列由后台任务删除
+// 查询只读取元数据中存在的列，删除元数据后字段立即不可见
+func (s *SchemaMigrationService) DropColumn(ctx context.Context, baseID string, field *entity.Field, userID string) error {
+	m := schemamigration.NewMigration(schemamigration.OperationDropColumn,
+		baseID, field.TableID(), field.ID().String(), field.DBFieldName().String(), userID)
+	m.ColumnType = field.DBFieldType()
+	if err := s.repo.Save(ctx, m); err != nil {
+		return pkgerrors.Database(err, "创建结构变更任务失败")
+	}
+
+	if err := s.fieldRepo.Delete(ctx, field.ID()); err != nil {
+		s.finish(ctx, m, schemamigration.StatusCancelled, err)
+		return pkgerrors.Database(err, "删除字段失败")
+	}
+
+	m.Enqueue(schemamigration.PhaseDDL, s.now())
+	s.save(ctx, m)
+	s.notify()
+	return nil
+}
+
+// enqueueDrop 新建删除物理列的任务（新增列撤销失败时使用）
+func (s *SchemaMigrationService) enqueueDrop(ctx context.Context, add *schemamigration.Migration) {
+	m := schemamigration.NewMigration(schemamigration.OperationDropColumn,
+		add.BaseID, add.TableID, add.FieldID, add.Column, add.CreatedBy)
+	m.ColumnType = add.ColumnType
+	m.Enqueue(schemamigration.PhaseDDL, s.now())
+	s.save(ctx, m)
+	s.notify()
+}
+
+// ==================== 字段更新 ====================
+
+// FieldChange 字段更新前的设置，与更新后的字段比较得出需要的结构变更
+type FieldChange struct {
+	Name       string
+	Required   bool
+	Unique     bool
+	TargetType string // 非空时修改字段类型（元数据在后台修改列类型成功后更新）
+}
+
+// FieldUpdate 请求内已准备好的字段结构变更
+// 字段元数据保存成功后调用 Commit 交给后台执行，保存失败时调用 Abort
+type FieldUpdate struct {
+	service *SchemaMigrationService
+	steps   []fieldUpdateStep
+}
+
+type fieldUpdateStep struct {
+	migration *schemamigration.Migration
+	deferred  bool                            // 是否还需要后台执行
+	undo      func(ctx context.Context) error // 撤销请求内已执行的 DDL
+}
+
+// PrepareFieldUpdate 准备字段更新的结构变更（field 为已应用修改、尚未保存的字段）
+//   - 取消必填/唯一：请求内移除约束（不重写数据）
+//   - 设为必填/唯一：先检查已有数据，存在空值（且没有默认值）或重复值时直接拒绝，通过后由后台回填并添加约束
+//   - 修改类型：物理列类型不变时只修改元数据，否则由后台修改列类型后再更新元数据
+//   - 重命名：物理列名（DBFieldName）不变，只记录变更
+func (s *SchemaMigrationService) PrepareFieldUpdate(ctx context.Context, baseID string, field *entity.Field, before FieldChange, userID string) (*FieldUpdate, error) {
+	update := &FieldUpdate{service: s}
+	newMigration := func(op schemamigration.Operation) (*schemamigration.Migration, error) {
+		m := schemamigration.NewMigration(op, baseID, field.TableID(), field.ID().String(), field.DBFieldName().String(), userID)
+		m.ColumnType = field.DBFieldType()
+		if err := s.repo.Save(ctx, m); err != nil {
+			return nil, pkgerrors.Database(err, "创建结构变更任务失败")
+		}
+		return m, nil
+	}
+	fail := func(err error) (*FieldUpdate, error) {
+		update.Abort(ctx, err)
+		return nil, err
+	}
+
+	if before.Name != "" && before.Name != field.Name().String() {
+		m, err := newMigration(schemamigration.OperationRenameField)
+		if err != nil {
+			return fail(err)
+		}
+		update.steps = append(update.steps, fieldUpdateStep{migration: m})
+	}
+
+	if before.TargetType != "" && before.TargetType != field.Type().String() {
+		step, err := s.prepareTypeChange(ctx, field, before.TargetType, newMigration)
+		if err != nil {
+			return fail(err)
+		}
+		update.steps = append(update.steps, *step)
+	}
+
+	if field.IsRequired() != before.Required || field.IsUnique() != before.Unique {
+		m, err := newMigration(schemamigration.OperationAlterConstraints)
+		if err != nil {
+			return fail(err)
+		}
+		step := fieldUpdateStep{migration: m}
+		update.steps = append(update.steps, step)
+		if err := s.prepareConstraints(ctx, field, before, &update.steps[len(update.steps)-1]); err != nil {
+			return fail(err)
+		}
+	}
+	return update, nil
+}
+
+// prepareTypeChange 检查类型转换并创建修改列类型的任务
+func (s *SchemaMigrationService) prepareTypeChange(ctx context.Context, field *entity.Field, targetType string, newMigration func(schemamigration.Operation) (*schemamigration.Migration, error)) (*fieldUpdateStep, error) {
+	target, err := valueobject.NewFieldType(targetType)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段类型无效: %s", targetType))
+	}
+	converted := *field
+	if err := converted.ChangeType(target); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持将 %s 字段转换为 %s", field.Type().String(), targetType))
+	}
+
+	migrations, err := s.repo.ListByTable(ctx, field.TableID(), schemaMigrationListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询结构变更任务失败")
+	}
+	for _, m := range migrations {
+		if m.FieldID == field.ID().String() && m.Operation == schemamigration.OperationAlterType && !m.Finished() {
+			return nil, pkgerrors.ErrConflict.WithDetails("字段类型正在修改中，请等待完成后再试")
+		}
+	}
+
+	m, err := newMigration(schemamigration.OperationAlterType)
+	if err != nil {
+		return nil, err
+	}
+	m.FieldType = target.String()
+	m.ColumnType = converted.DBFieldType()
+	if converted.DBFieldType() == field.DBFieldType() {
+		// 物理列类型不变：随本次请求修改元数据
+		if err := field.ChangeType(target); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+		}
+		return &fieldUpdateStep{migration: m}, nil
+	}
+	return &fieldUpdateStep{migration: m, deferred: true}, nil
+}
+
+// prepareConstraints 请求内移除取消的约束，检查新增约束能否满足
+func (s *SchemaMigrationService) prepareConstraints(ctx context.Context, field *entity.Field, before FieldChange, step *fieldUpdateStep) error {
+	m := step.migration
+	var undo []func(context.Context) error
+
+	if before.Required && !field.IsRequired() {
+		if err := s.dbProvider.DropNotNull(ctx, m.BaseID, m.TableID, m.Column); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("移除必填约束失败: %v", err))
+		}
+		undo = append(undo, func(ctx context.Context) error {
+			return s.dbProvider.SetNotNull(ctx, m.BaseID, m.TableID, m.Column)
+		})
+	}
+	if before.Unique && !field.IsUnique() {
+		name := uniqueConstraintName(m)
+		if err := s.dbProvider.DropConstraint(ctx, m.BaseID, m.TableID, name); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("移除唯一约束失败: %v", err))
+		}
+		undo = append(undo, func(ctx context.Context) error {
+			return s.dbProvider.AddUniqueConstraint(ctx, m.BaseID, m.TableID, m.Column, name)
+		})
+	}
+	step.undo = func(ctx context.Context) error {
+		for _, fn := range undo {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if field.IsRequired() && !before.Required {
+		m.NotNull = true
+		m.BackfillValue = backfillValue(field)
+		if m.BackfillValue == nil {
+			hasNulls, err := s.hasNulls(ctx, m)
+			if err != nil {
+				return pkgerrors.Database(err, "检查表格记录失败")
+			}
+			if hasNulls {
+				return pkgerrors.ErrValidationFailed.WithDetails("已有记录的该字段为空，设为必填需要先设置默认值或补全数据")
+			}
+		}
+	}
+	if field.IsUnique() && !before.Unique {
+		m.Unique = true
+		duplicated, err := s.hasDuplicates(ctx, m)
+		if err != nil {
+			return pkgerrors.Database(err, "检查表格记录失败")
+		}
+		if duplicated {
+			return pkgerrors.ErrValidationFailed.WithDetails("已有记录的该字段存在重复值，无法设为唯一")
+		}
+	}
+	step.deferred = m.HasDeferredWork()
+	return nil
+}
+
+// Commit 字段元数据已保存：需要后台执行的任务入队，其余任务完成
+func (u *FieldUpdate) Commit(ctx context.Context) {
+	s := u.service
+	queued := false
+	for _, step := range u.steps {
+		m := step.migration
+		if !step.deferred {
+			s.finish(ctx, m, schemamigration.StatusCompleted, nil)
+			continue
+		}
+		phase := schemamigration.PhaseDDL
+		if m.Operation == schemamigration.OperationAlterConstraints {
+			phase = schemamigration.PhaseBackfill
+		}
+		m.Enqueue(phase, s.now())
+		s.save(ctx, m)
+		queued = true
+	}
+	if queued {
+		s.notify()
+	}
+}
+
+// Abort 字段元数据未保存：撤销请求内已执行的 DDL 并取消任务
+func (u *FieldUpdate) Abort(ctx context.Context, cause error) {
+	s := u.service
+	for _, step := range u.steps {
+		if step.undo != nil {
+			if err := step.undo(ctx); err != nil {
+				schemaMigrationLog.Error(ctx, "恢复字段约束失败",
+					logger.String("field_id", step.migration.FieldID), logger.ErrorField(err))
+			}
+		}
+		s.finish(ctx, step.migration, schemamigration.StatusCancelled, cause)
+	}
+}
+
+// RetryMigration 重新执行失败的任务（处理冲突数据后由用户发起）
+func (s *SchemaMigrationService) RetryMigration(ctx context.Context, userID, migrationID string) (*schemamigration.Migration, error) {
+	m, err := s.GetMigration(ctx, userID, migrationID)
+	if err != nil {
+		return nil, err
+	}
+	if !m.Restart(s.now()) {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("只能重新执行失败的结构变更")
+	}
+	if err := s.repo.Save(ctx, m); err != nil {
+		return nil, pkgerrors.Database(err, "保存结构变更任务失败")
+	}
+	s.notify()
+	return m, nil
+}
+
+// ==================== 查询 ====================
+
+// ListTableMigrations 列出表格最近的结构变更任务
+func (s *SchemaMigrationService) ListTableMigrations(ctx context.Context, userID, tableID string) ([]*schemamigration.Migration, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限查看表结构变更")
+	}
+	migrations, err := s.repo.ListByTable(ctx, tableID, schemaMigrationListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return migrations, nil
+}
+
+// GetMigration 获取结构变更任务（含回填进度）
+func (s *SchemaMigrationService) GetMigration(ctx context.Context, userID, migrationID string) (*schemamigration.Migration, error) {
+	m, err := s.repo.FindByID(ctx, migrationID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if m == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("结构变更任务不存在")
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, m.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限查看表结构变更")
+	}
+	return m, nil
+}
+
+// ==================== 后台任务 ====================
+
+// Start 启动后台任务：轮询可执行的结构变更，同一张表的变更串行执行
+func (s *SchemaMigrationService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.runPending(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// notify 唤醒后台任务（不阻塞请求）
+func (s *SchemaMigrationService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runPending 依次执行所有可执行的任务
+func (s *SchemaMigrationService) runPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := s.now()
+		m, err := s.repo.Claim(ctx, now, now.Add(-s.cfg.StaleTimeout))
+		if err != nil {
+			schemaMigrationLog.Warn(ctx, "领取结构变更任务失败", logger.ErrorField(err))
+			return
+		}
+		if m == nil {
+			return
+		}
+		s.execute(ctx, m)
+	}
+}
+
+// execute 执行任务，失败时按退避重试，重试耗尽后标记失败（不修改字段设置）
+func (s *SchemaMigrationService) execute(ctx context.Context, m *schemamigration.Migration) {
+	var err error
+	switch m.Operation {
+	case schemamigration.OperationAddColumn:
+		err = s.executeAddColumn(ctx, m)
+	case schemamigration.OperationDropColumn:
+		err = s.executeDropColumn(ctx, m)
+	case schemamigration.OperationAlterConstraints:
+		err = s.executeAlterConstraints(ctx, m)
+	case schemamigration.OperationAlterType:
+		err = s.executeAlterType(ctx, m)
+	default:
+		err = fmt.Errorf("unknown schema migration operation: %s", m.Operation)
+	}
+
+	if err == nil {
+		if !m.Finished() {
+			m.Finish(schemamigration.StatusCompleted, nil, s.now())
+		}
+		s.save(ctx, m)
+		schemaMigrationLog.Info(ctx, "结构变更完成",
+			logger.String("migration_id", m.ID),
+			logger.String("operation", string(m.Operation)),
+			logger.String("status", string(m.Status)),
+			logger.Int64("rows", m.ProcessedRows))
+		return
+	}
+
+	if ctx.Err() != nil {
+		// 服务停止：放回队列，不计入重试次数
+		m.Attempts--
+		m.Enqueue(m.Phase, s.now())
+		s.save(context.Background(), m)
+		return
+	}
+
+	if m.Retry(err, s.cfg.MaxAttempts, s.now()) {
+		s.save(ctx, m)
+		schemaMigrationLog.Warn(ctx, "结构变更失败，稍后重试",
+			logger.String("migration_id", m.ID),
+			logger.Int("attempts", m.Attempts),
+			logger.ErrorField(err))
+		return
+	}
+
+	// 不修改字段设置：任务保持失败并记录原因，处理数据后通过 RetryMigration 重新执行
+	m.Finish(schemamigration.StatusFailed, err, s.now())
+	s.save(ctx, m)
+	schemaMigrationLog.Error(ctx, "结构变更失败，需要处理后重新执行",
+		logger.String("migration_id", m.ID),
+		logger.String("field_id", m.FieldID),
+		logger.ErrorField(err))
+}
+
+// executeAddColumn 回填默认值并添加约束
+func (s *SchemaMigrationService) executeAddColumn(ctx context.Context, m *schemamigration.Migration) error {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(m.FieldID))
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		// 同步阶段在保存元数据前中断，或字段已被删除：撤销物理列
+		if err := s.dbProvider.DropColumn(ctx, m.BaseID, m.TableID, m.Column); err != nil {
+			return err
+		}
+		m.Finish(schemamigration.StatusCancelled, nil, s.now())
+		return nil
+	}
+
+	return s.applyConstraints(ctx, m, field)
+}
+
+// applyConstraints 回填默认值并添加约束（以字段当前设置为准，任务排队期间可能已取消必填/唯一）
+func (s *SchemaMigrationService) applyConstraints(ctx context.Context, m *schemamigration.Migration, field *entity.Field) error {
+	if m.BackfillValue != nil && (m.Operation == schemamigration.OperationAddColumn || field.IsRequired()) {
+		m.Phase = schemamigration.PhaseBackfill
+		if err := s.backfill(ctx, m); err != nil {
+			return err
+		}
+	}
+
+	m.Phase = schemamigration.PhaseConstraints
+	s.save(ctx, m)
+
+	if m.NotNull && field.IsRequired() {
+		if err := s.dbProvider.SetNotNull(ctx, m.BaseID, m.TableID, m.Column); err != nil {
+			return err
+		}
+	}
+	if m.Unique && field.IsUnique() {
+		name := uniqueConstraintName(m)
+		if err := s.dbProvider.DropConstraint(ctx, m.BaseID, m.TableID, name); err != nil {
+			return err
+		}
+		if err := s.dbProvider.AddUniqueConstraint(ctx, m.BaseID, m.TableID, m.Column, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeAlterConstraints 为已有列回填默认值并添加必填/唯一约束
+func (s *SchemaMigrationService) executeAlterConstraints(ctx context.Context, m *schemamigration.Migration) error {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(m.FieldID))
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		m.Finish(schemamigration.StatusCancelled, nil, s.now())
+		return nil
+	}
+	return s.applyConstraints(ctx, m, field)
+}
+
+// executeAlterType 修改物理列类型，成功后更新字段元数据（值无法转换时失败，元数据保持原类型）
+func (s *SchemaMigrationService) executeAlterType(ctx context.Context, m *schemamigration.Migration) error {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(m.FieldID))
+	if err != nil {
+		return err
+	}
+	if field == nil {
+		m.Finish(schemamigration.StatusCancelled, nil, s.now())
+		return nil
+	}
+	target, err := valueobject.NewFieldType(m.FieldType)
+	if err != nil {
+		return err
+	}
+
+	if field.Type().String() != m.FieldType {
+		columnDef := database.ColumnDefinition{Name: m.Column, Type: m.ColumnType, NotNull: field.IsRequired()}
+		if err := s.dbProvider.AlterColumn(ctx, m.BaseID, m.TableID, m.Column, columnDef); err != nil {
+			return err
+		}
+		if err := field.ChangeType(target); err != nil {
+			return err
+		}
+		// 保存失败时重试：ALTER COLUMN TYPE 对已转换的列可重复执行
+		if err := s.fieldRepo.Save(ctx, field); err != nil {
+			return err
+		}
+		if s.onFieldChanged != nil {
+			s.onFieldChanged(ctx, field)
+		}
+	}
+	return nil
+}
+
+// executeDropColumn 删除物理列（DROP COLUMN IF EXISTS，可重复执行）
+func (s *SchemaMigrationService) executeDropColumn(ctx context.Context, m *schemamigration.Migration) error {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(m.FieldID))
+	if err != nil {
+		return err
+	}
+	if field != nil {
+		// 元数据仍存在（删除元数据前中断）：保留物理列
+		m.Finish(schemamigration.StatusCancelled, nil, s.now())
+		return nil
+	}
+	return s.dbProvider.DropColumn(ctx, m.BaseID, m.TableID, m.Column)
+}
+
+// backfill 分批回填为 NULL 的记录，每批单独提交，避免长事务锁表
+func (s *SchemaMigrationService) backfill(ctx context.Context, m *schemamigration.Migration) error {
+	db := s.dataDB(ctx, m.BaseID).WithContext(ctx)
+	tableName, err := s.dbProvider.ResolveTableName(ctx, m.BaseID, m.TableID)
+	if err != nil {
+		return err
+	}
+	isNull := clause.Expr{SQL: "? IS NULL", Vars: []interface{}{clause.Column{Name: m.Column}}}
+	value := gorm.Expr(fmt.Sprintf("CAST(? AS %s)", m.ColumnType), backfillLiteral(m.ColumnType, *m.BackfillValue))
+
+	var remaining int64
+	if err := db.Table(tableName).Where(isNull).Count(&remaining).Error; err != nil {
+		return fmt.Errorf("failed to count rows to backfill: %w", err)
+	}
+	m.TotalRows = m.ProcessedRows + remaining
+	s.save(ctx, m)
+
+	for remaining > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch := db.Table(tableName).Select("__id").Where(isNull).Limit(s.cfg.BatchSize)
+		result := db.Table(tableName).Where("__id IN (?)", batch).Update(m.Column, value)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill column: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			break
+		}
+		m.ProcessedRows += result.RowsAffected
+		remaining -= result.RowsAffected
+		if m.ProcessedRows > m.TotalRows {
+			m.TotalRows = m.ProcessedRows // 回填期间新增的记录
+		}
+		s.save(ctx, m) // 保存进度，同时作为心跳避免被其他实例接管
+	}
+	return nil
+}
+
+// hasRows 物理表是否已有记录
+func (s *SchemaMigrationService) hasRows(ctx context.Context, m *schemamigration.Migration) (bool, error) {
+	var ids []string
+	tableName, err := s.dbProvider.ResolveTableName(ctx, m.BaseID, m.TableID)
+	if err != nil {
+		return false, err
+	}
+	err = s.dataDB(ctx, m.BaseID).WithContext(ctx).
+		Table(tableName).
+		Limit(1).
+		Pluck("__id", &ids).Error
+	return len(ids) > 0, err
+}
+
+// hasNulls 已有记录中该列是否存在空值
+func (s *SchemaMigrationService) hasNulls(ctx context.Context, m *schemamigration.Migration) (bool, error) {
+	var ids []string
+	tableName, err := s.dbProvider.ResolveTableName(ctx, m.BaseID, m.TableID)
+	if err != nil {
+		return false, err
+	}
+	err = s.dataDB(ctx, m.BaseID).WithContext(ctx).
+		Table(tableName).
+		Where(clause.Expr{SQL: "? IS NULL", Vars: []interface{}{clause.Column{Name: m.Column}}}).
+		Limit(1).
+		Pluck("__id", &ids).Error
+	return len(ids) > 0, err
+}
+
+// hasDuplicates 已有记录中该列是否存在重复值
+func (s *SchemaMigrationService) hasDuplicates(ctx context.Context, m *schemamigration.Migration) (bool, error) {
+	var found []map[string]interface{}
+	tableName, err := s.dbProvider.ResolveTableName(ctx, m.BaseID, m.TableID)
+	if err != nil {
+		return false, err
+	}
+	err = s.dataDB(ctx, m.BaseID).WithContext(ctx).
+		Table(tableName).
+		Select("?", clause.Column{Name: m.Column}).
+		Where(clause.Expr{SQL: "? IS NOT NULL", Vars: []interface{}{clause.Column{Name: m.Column}}}).
+		Group(m.Column).
+		Having("COUNT(*) > 1").
+		Limit(1).
+		Find(&found).Error
+	return len(found) > 0, err
+}
+
+func (s *SchemaMigrationService) finish(ctx context.Context, m *schemamigration.Migration, status schemamigration.Status, err error) {
+	m.Finish(status, err, s.now())
+	s.save(ctx, m)
+}
+
+// save 保存任务状态；失败时仅记录日志，执行中的任务超时后会被重新领取
+func (s *SchemaMigrationService) save(ctx context.Context, m *schemamigration.Migration) {
+	m.UpdatedAt = s.now()
+	if err := s.repo.Save(ctx, m); err != nil {
+		schemaMigrationLog.Warn(ctx, "保存结构变更任务失败",
+			logger.String("migration_id", m.ID), logger.ErrorField(err))
+	}
+}
+
+// uniqueConstraintName 唯一约束名（与 DBProvider.AddColumn 的命名一致）
+func uniqueConstraintName(m *schemamigration.Migration) string {
+	return fmt.Sprintf("%s_%s_%s_unique", m.BaseID, m.TableID, m.Column)
+}
+
+// backfillValue 已有记录的回填值
+// 字段选项中的默认值只作用于新记录，仅在必填字段需要满足 NOT NULL 时回填到已有记录
+func backfillValue(field *entity.Field) *string {
+	if value := field.DefaultValue(); value != nil {
+		return value
+	}
+	options := field.Options()
+	if !field.IsRequired() || options == nil {
+		return nil
+	}
+
+	var value string
+	switch {
+	case options.Number != nil && options.Number.DefaultValue != nil:
+		value = strconv.FormatFloat(*options.Number.DefaultValue, 'f', -1, 64)
+	case options.Date != nil && options.Date.DefaultValue != nil:
+		value = *options.Date.DefaultValue // "now" 由数据库解析为当前时间
+	case options.Select != nil && options.Select.DefaultValue != nil:
+		if str, ok := options.Select.DefaultValue.(string); ok {
+			value = str
+		} else {
+			data, err := json.Marshal(options.Select.DefaultValue)
+			if err != nil {
+				return nil
+			}
+			value = string(data)
+		}
+	default:
+		return nil
+	}
+	return &value
+}
+
+// backfillLiteral 回填值的文本形式：JSONB 列要求合法 JSON，非 JSON 文本按字符串编码
+func backfillLiteral(columnType, value string) string {
+	if columnType != "JSONB" || json.Valid([]byte(value)) {
+		return value
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,301 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemamigration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// memMigrationRepo 内存中的结构变更任务
+type memMigrationRepo struct {
+	migrations []*schemamigration.Migration
+}
+
+func (r *memMigrationRepo) Save(_ context.Context, m *schemamigration.Migration) error {
+	for _, existing := range r.migrations {
+		if existing.ID == m.ID {
+			return nil
+		}
+	}
+	r.migrations = append(r.migrations, m)
+	return nil
+}
+
+func (r *memMigrationRepo) FindByID(_ context.Context, id string) (*schemamigration.Migration, error) {
+	for _, m := range r.migrations {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memMigrationRepo) ListByTable(_ context.Context, tableID string, _ int) ([]*schemamigration.Migration, error) {
+	var list []*schemamigration.Migration
+	for _, m := range r.migrations {
+		if m.TableID == tableID {
+			list = append(list, m)
+		}
+	}
+	return list, nil
+}
+
+func (r *memMigrationRepo) Claim(_ context.Context, now, _ time.Time) (*schemamigration.Migration, error) {
+	for _, m := range r.migrations {
+		if m.Status == schemamigration.StatusPending && !m.NextRunAt.After(now) {
+			m.Status = schemamigration.StatusRunning
+			m.Attempts++
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+// byOperation 最近一个指定操作的任务
+func (r *memMigrationRepo) byOperation(op schemamigration.Operation) *schemamigration.Migration {
+	for i := len(r.migrations) - 1; i >= 0; i-- {
+		if r.migrations[i].Operation == op {
+			return r.migrations[i]
+		}
+	}
+	return nil
+}
+
+type stubMigrationFieldRepo struct {
+	fieldRepo.FieldRepository
+	field *fieldEntity.Field
+	saves int
+}
+
+func (r *stubMigrationFieldRepo) FindByID(context.Context, fieldVO.FieldID) (*fieldEntity.Field, error) {
+	return r.field, nil
+}
+
+func (r *stubMigrationFieldRepo) Save(_ context.Context, field *fieldEntity.Field) error {
+	r.field = field
+	r.saves++
+	return nil
+}
+
+// recordingDBProvider 记录执行的 DDL，可让指定操作失败
+type recordingDBProvider struct {
+	database.DBProvider
+	calls []string
+	fail  map[string]error
+}
+
+func (p *recordingDBProvider) call(name string) error {
+	p.calls = append(p.calls, name)
+	return p.fail[name]
+}
+
+func (p *recordingDBProvider) GenerateTableName(baseID, tableID string) string {
+	return baseID + "." + tableID
+}
+
+func (p *recordingDBProvider) ResolveTableName(_ context.Context, baseID, tableID string) (string, error) {
+	return p.GenerateTableName(baseID, tableID), nil
+}
+
+func (p *recordingDBProvider) SetNotNull(context.Context, string, string, string) error {
+	return p.call("set_not_null")
+}
+
+func (p *recordingDBProvider) DropNotNull(context.Context, string, string, string) error {
+	return p.call("drop_not_null")
+}
+
+func (p *recordingDBProvider) AddUniqueConstraint(context.Context, string, string, string, string) error {
+	return p.call("add_unique")
+}
+
+func (p *recordingDBProvider) DropConstraint(context.Context, string, string, string) error {
+	return p.call("drop_constraint")
+}
+
+func (p *recordingDBProvider) AlterColumn(context.Context, string, string, string, database.ColumnDefinition) error {
+	return p.call("alter_column")
+}
+
+type schemaMigrationFixture struct {
+	service  *SchemaMigrationService
+	repo     *memMigrationRepo
+	fields   *stubMigrationFieldRepo
+	provider *recordingDBProvider
+	field    *fieldEntity.Field
+	changed  int
+}
+
+func newSchemaMigrationFixture(t *testing.T) *schemaMigrationFixture {
+	t.Helper()
+	logger.Logger = zap.NewNop()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=luckdb"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("创建 GORM 实例失败: %v", err)
+	}
+
+	field := newTemplateTestField(t, "编号", fieldVO.TypeText)
+	fx := &schemaMigrationFixture{
+		repo:     &memMigrationRepo{},
+		fields:   &stubMigrationFieldRepo{field: field},
+		provider: &recordingDBProvider{fail: map[string]error{}},
+		field:    field,
+	}
+	fx.service = NewSchemaMigrationService(fx.repo, fx.fields, fx.provider,
+		func(context.Context, string) *gorm.DB { return db }, nil,
+		config.SchemaMigrationConfig{MaxAttempts: 1})
+	fx.service.onFieldChanged = func(context.Context, *fieldEntity.Field) { fx.changed++ }
+	return fx
+}
+
+// update 修改字段后按 UpdateField 的顺序准备、保存并提交
+func (fx *schemaMigrationFixture) update(t *testing.T, targetType string, apply func(*fieldEntity.Field)) error {
+	t.Helper()
+	ctx := context.Background()
+	before := FieldChange{Name: fx.field.Name().String(), Required: fx.field.IsRequired(), Unique: fx.field.IsUnique(), TargetType: targetType}
+	apply(fx.field)
+	update, err := fx.service.PrepareFieldUpdate(ctx, "bse1", fx.field, before, "usr1")
+	if err != nil {
+		return err
+	}
+	update.Commit(ctx)
+	return nil
+}
+
+func (fx *schemaMigrationFixture) runPending() {
+	fx.service.runPending(context.Background())
+}
+
+func TestUpdateFieldConstraintsRunDDL(t *testing.T) {
+	fx := newSchemaMigrationFixture(t)
+
+	// 设为唯一：请求内不执行 DDL，后台添加约束
+	if err := fx.update(t, "", func(f *fieldEntity.Field) { f.SetUnique(true) }); err != nil {
+		t.Fatalf("设为唯一失败: %v", err)
+	}
+	m := fx.repo.byOperation(schemamigration.OperationAlterConstraints)
+	if m == nil || m.Status != schemamigration.StatusPending || !m.Unique {
+		t.Fatalf("设为唯一应创建待执行的约束任务，得到 %+v", m)
+	}
+	if len(fx.provider.calls) != 0 {
+		t.Fatalf("请求内不应添加约束，得到 %v", fx.provider.calls)
+	}
+	fx.runPending()
+	if m.Status != schemamigration.StatusCompleted || !containsCall(fx.provider.calls, "add_unique") {
+		t.Fatalf("后台任务应添加唯一约束，得到 %s %v", m.Status, fx.provider.calls)
+	}
+
+	// 取消唯一：请求内移除约束
+	fx.provider.calls = nil
+	if err := fx.update(t, "", func(f *fieldEntity.Field) { f.SetUnique(false) }); err != nil {
+		t.Fatalf("取消唯一失败: %v", err)
+	}
+	if len(fx.provider.calls) != 1 || fx.provider.calls[0] != "drop_constraint" {
+		t.Fatalf("取消唯一应在请求内移除约束，得到 %v", fx.provider.calls)
+	}
+	if m := fx.repo.byOperation(schemamigration.OperationAlterConstraints); m.Status != schemamigration.StatusCompleted {
+		t.Fatalf("只移除约束的任务应直接完成，得到 %s", m.Status)
+	}
+}
+
+func TestUpdateFieldAbortRestoresDroppedConstraint(t *testing.T) {
+	fx := newSchemaMigrationFixture(t)
+	fx.field.SetRequired(true)
+	ctx := context.Background()
+
+	before := FieldChange{Name: fx.field.Name().String(), Required: true}
+	fx.field.SetRequired(false)
+	update, err := fx.service.PrepareFieldUpdate(ctx, "bse1", fx.field, before, "usr1")
+	if err != nil {
+		t.Fatalf("取消必填失败: %v", err)
+	}
+	update.Abort(ctx, errors.New("save failed"))
+
+	if got := fx.provider.calls; len(got) != 2 || got[0] != "drop_not_null" || got[1] != "set_not_null" {
+		t.Fatalf("元数据保存失败时应恢复 NOT NULL，得到 %v", got)
+	}
+	if m := fx.repo.byOperation(schemamigration.OperationAlterConstraints); m.Status != schemamigration.StatusCancelled {
+		t.Fatalf("任务应取消，得到 %s", m.Status)
+	}
+}
+
+func TestUpdateFieldTypeChangesMetadataAfterConversion(t *testing.T) {
+	fx := newSchemaMigrationFixture(t)
+
+	if err := fx.update(t, fieldVO.TypeNumber, func(*fieldEntity.Field) {}); err != nil {
+		t.Fatalf("修改类型失败: %v", err)
+	}
+	if fx.field.Type().String() != fieldVO.TypeText {
+		t.Fatalf("列类型转换完成前字段类型不应改变，得到 %s", fx.field.Type().String())
+	}
+	if err := fx.update(t, fieldVO.TypeNumber, func(*fieldEntity.Field) {}); err == nil {
+		t.Fatal("类型转换进行中时应拒绝再次修改类型")
+	}
+
+	fx.runPending()
+	m := fx.repo.byOperation(schemamigration.OperationAlterType)
+	if m.Status != schemamigration.StatusCompleted || fx.fields.field.Type().String() != fieldVO.TypeNumber {
+		t.Fatalf("转换完成后应更新字段类型，得到 %s %s", m.Status, fx.fields.field.Type().String())
+	}
+	if fx.fields.field.DBFieldType() != "NUMERIC" || fx.changed != 1 {
+		t.Fatalf("应保存新的列类型并通知字段变更，得到 %s %d", fx.fields.field.DBFieldType(), fx.changed)
+	}
+
+	if err := fx.update(t, fieldVO.TypeAttachment, func(*fieldEntity.Field) {}); err == nil {
+		t.Fatal("不兼容的类型转换应被拒绝")
+	}
+}
+
+func TestFailedMigrationKeepsFieldSettingsUntilRetried(t *testing.T) {
+	fx := newSchemaMigrationFixture(t)
+	fx.provider.fail["add_unique"] = errors.New("could not create unique index")
+
+	if err := fx.update(t, "", func(f *fieldEntity.Field) { f.SetUnique(true) }); err != nil {
+		t.Fatalf("设为唯一失败: %v", err)
+	}
+	fx.runPending()
+
+	m := fx.repo.byOperation(schemamigration.OperationAlterConstraints)
+	if m.Status != schemamigration.StatusFailed || m.Error == "" {
+		t.Fatalf("重试耗尽后任务应失败并记录原因，得到 %s %q", m.Status, m.Error)
+	}
+	if !fx.fields.field.IsUnique() || fx.fields.saves != 0 {
+		t.Fatal("任务失败时不应静默修改字段设置")
+	}
+
+	// 处理重复数据后重新执行
+	delete(fx.provider.fail, "add_unique")
+	if !m.Restart(time.Now()) {
+		t.Fatal("失败的任务应可以重新执行")
+	}
+	fx.runPending()
+	if m.Status != schemamigration.StatusCompleted {
+		t.Fatalf("重新执行后应完成，得到 %s %q", m.Status, m.Error)
+	}
+}
+
+func containsCall(calls []string, name string) bool {
+	for _, c := range calls {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
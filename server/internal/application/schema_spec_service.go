@@ -0,0 +1,389 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var schemaSpecLog = logger.Named("schema_spec")
+
+// SchemaSpecRequest 对比 / 应用结构描述请求
+type SchemaSpecRequest struct {
+	YAML  string `json:"yaml" binding:"required"`
+	Prune bool   `json:"prune"` // 删除描述中未声明的表、字段与视图
+}
+
+// SchemaSpecDocument 导出的结构描述
+type SchemaSpecDocument struct {
+	YAML string `json:"yaml"`
+}
+
+// SchemaSpecService Base 结构即代码：导出 YAML 结构描述、对比差异并幂等应用 ✨
+// 应用通过表、字段、视图服务逐项执行，与界面操作走同一套校验与物理表变更
+type SchemaSpecService struct {
+	baseRepo          baseRepo.BaseRepository
+	tableService      *TableService
+	fieldService      *FieldService
+	viewService       *ViewService
+	permissionService *PermissionServiceV2
+}
+
+// NewSchemaSpecService 创建结构描述服务
+func NewSchemaSpecService(
+	baseRepo baseRepo.BaseRepository,
+	tableService *TableService,
+	fieldService *FieldService,
+	viewService *ViewService,
+	permissionService *PermissionServiceV2,
+) *SchemaSpecService {
+	return &SchemaSpecService{
+		baseRepo:          baseRepo,
+		tableService:      tableService,
+		fieldService:      fieldService,
+		viewService:       viewService,
+		permissionService: permissionService,
+	}
+}
+
+// Export 导出 Base 当前结构
+// 字段选项只导出公式表达式，其余选项（选项列表、关联配置等）需按需补充
+func (s *SchemaSpecService) Export(ctx context.Context, userID, baseID string) (*schemaspec.Spec, error) {
+	if err := s.checkBase(ctx, userID, baseID, false); err != nil {
+		return nil, err
+	}
+	live, err := s.loadLive(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &schemaspec.Spec{Version: schemaspec.CurrentVersion, Tables: make([]*schemaspec.TableSpec, 0, len(live))}
+	for _, table := range live {
+		tableSpec := &schemaspec.TableSpec{Name: table.Name, Description: table.Description}
+		for _, field := range table.Fields {
+			tableSpec.Fields = append(tableSpec.Fields, &schemaspec.FieldSpec{
+				Name:        field.Name,
+				APIKey:      field.APIKey,
+				Type:        field.Type,
+				Description: field.Description,
+				Required:    field.Required,
+				Unique:      field.Unique,
+				Primary:     field.Primary,
+				Options:     exportOptions(field),
+			})
+		}
+		for _, view := range table.Views {
+			viewSpec := &schemaspec.ViewSpec{Name: view.Name, Type: view.Type, Description: view.Description}
+			if filter, ok := view.Filter.(map[string]interface{}); ok && !emptyFilter(filter) {
+				viewSpec.Filter, _ = schemaspec.NameReferences(filter, table, live).(map[string]interface{})
+			}
+			if sort, ok := view.Sort.([]map[string]interface{}); ok && len(sort) > 0 {
+				viewSpec.Sort = toMapSlice(schemaspec.NameReferences(sort, table, live))
+			}
+			tableSpec.Views = append(tableSpec.Views, viewSpec)
+		}
+		spec.Tables = append(spec.Tables, tableSpec)
+	}
+	return spec, nil
+}
+
+// Diff 对比结构描述与 Base 当前结构（不执行任何变更）
+func (s *SchemaSpecService) Diff(ctx context.Context, userID, baseID string, spec *schemaspec.Spec, prune bool) (*schemaspec.Plan, error) {
+	if err := s.checkBase(ctx, userID, baseID, false); err != nil {
+		return nil, err
+	}
+	live, err := s.loadLive(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	return schemaspec.Diff(spec, live, prune), nil
+}
+
+// Apply 应用结构描述，返回实际执行的变更；已一致时不做任何变更
+// 存在冲突（如字段类型不同）时拒绝应用；中途失败时已执行的变更保留，修正后重新应用即可继续
+func (s *SchemaSpecService) Apply(ctx context.Context, userID, baseID string, spec *schemaspec.Spec, prune bool) (*schemaspec.Plan, error) {
+	if err := s.checkBase(ctx, userID, baseID, true); err != nil {
+		return nil, err
+	}
+	live, err := s.loadLive(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	plan := schemaspec.Diff(spec, live, prune)
+	if len(plan.Conflicts) > 0 {
+		return nil, pkgerrors.ErrConflict.WithDetails(plan.Conflicts)
+	}
+
+	applied := &schemaspec.Plan{Changes: []schemaspec.Change{}, Conflicts: []schemaspec.Conflict{}}
+
+	// 1. 先建表（只含普通字段），之后重新对比得到引用字段、字段描述与视图设置等剩余变更
+	created := false
+	for _, change := range plan.Changes {
+		if change.Kind != schemaspec.ChangeCreateTable {
+			continue
+		}
+		if err := s.createTable(ctx, userID, baseID, spec.Table(change.Table)); err != nil {
+			return nil, err
+		}
+		applied.Changes = append(applied.Changes, change)
+		created = true
+	}
+	if created {
+		if live, err = s.loadLive(ctx, baseID); err != nil {
+			return nil, err
+		}
+		plan = schemaspec.Diff(spec, live, prune)
+		if len(plan.Conflicts) > 0 {
+			return nil, pkgerrors.ErrConflict.WithDetails(plan.Conflicts)
+		}
+	}
+
+	// 2. 按计划顺序执行其余变更
+	for _, change := range plan.Changes {
+		if err := s.applyChange(ctx, userID, spec, live, change); err != nil {
+			schemaSpecLog.Warn(ctx, "应用结构描述中断",
+				logger.String("base_id", baseID),
+				logger.String("kind", string(change.Kind)),
+				logger.String("table", change.Table),
+				logger.String("name", change.Name),
+				logger.Int("applied", len(applied.Changes)),
+				logger.ErrorField(err))
+			return nil, err
+		}
+		applied.Changes = append(applied.Changes, change)
+	}
+
+	schemaSpecLog.Info(ctx, "结构描述已应用",
+		logger.String("base_id", baseID),
+		logger.String("user_id", userID),
+		logger.Int("changes", len(applied.Changes)))
+	return applied, nil
+}
+
+// checkBase 检查 Base 存在与权限（应用需要建表权限）
+func (s *SchemaSpecService) checkBase(ctx context.Context, userID, baseID string, write bool) error {
+	exists, err := s.baseRepo.Exists(ctx, baseID)
+	if err != nil {
+		return pkgerrors.Database(err, "验证Base存在性失败")
+	}
+	if !exists {
+		return pkgerrors.ErrNotFound.WithDetails("Base不存在")
+	}
+	if write {
+		if !s.permissionService.CanCreateTablesInBase(ctx, userID, baseID) {
+			return pkgerrors.ErrForbidden.WithDetails("没有权限修改Base结构")
+		}
+	} else if !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	return nil
+}
+
+// loadLive 读取 Base 当前的表、字段与视图
+func (s *SchemaSpecService) loadLive(ctx context.Context, baseID string) ([]*schemaspec.LiveTable, error) {
+	tables, err := s.tableService.ListTables(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	live := make([]*schemaspec.LiveTable, 0, len(tables))
+	for _, table := range tables {
+		fields, err := s.fieldService.ListFields(ctx, table.ID)
+		if err != nil {
+			return nil, err
+		}
+		views, err := s.viewService.ListViewsByTable(ctx, table.ID)
+		if err != nil {
+			return nil, err
+		}
+		current := &schemaspec.LiveTable{ID: table.ID, Name: table.Name, Description: table.Description}
+		for _, field := range fields {
+			current.Fields = append(current.Fields, liveField(field))
+		}
+		for _, view := range views {
+			current.Views = append(current.Views, &schemaspec.LiveView{
+				ID:          view.ID,
+				Name:        view.Name,
+				Type:        view.Type,
+				Description: view.Description,
+				Filter:      view.Filter,
+				Sort:        view.Sort,
+			})
+		}
+		live = append(live, current)
+	}
+	return live, nil
+}
+
+// createTable 建表并创建普通字段与视图
+func (s *SchemaSpecService) createTable(ctx context.Context, userID, baseID string, table *schemaspec.TableSpec) error {
+	req := dto.CreateTableRequest{Name: table.Name, Description: table.Description, BaseID: baseID}
+	for _, field := range table.PlainFields() {
+		req.Fields = append(req.Fields, dto.FieldConfigDTO{
+			Name:        field.Name,
+			APIKey:      field.APIKey,
+			Type:        field.Type,
+			Description: field.Description,
+			Required:    field.Required,
+			Unique:      field.Unique,
+			IsPrimary:   field.Primary,
+			Options:     field.Options,
+		})
+	}
+	for _, view := range table.Views {
+		req.Views = append(req.Views, dto.ViewConfigDTO{Name: view.Name, Type: view.Type, Description: view.Description})
+	}
+	_, err := s.tableService.CreateTable(ctx, req, userID)
+	return err
+}
+
+// applyChange 执行一项变更；新建字段后加入 live，供后续变更解析引用
+func (s *SchemaSpecService) applyChange(ctx context.Context, userID string, spec *schemaspec.Spec, live []*schemaspec.LiveTable, change schemaspec.Change) error {
+	var table *schemaspec.LiveTable
+	for _, t := range live {
+		if t.Name == change.Table {
+			table = t
+			break
+		}
+	}
+	tableSpec := spec.Table(change.Table)
+
+	switch change.Kind {
+	case schemaspec.ChangeUpdateTable:
+		_, err := s.tableService.UpdateTable(ctx, change.TargetID, dto.UpdateTableRequest{Description: &tableSpec.Description})
+		return err
+
+	case schemaspec.ChangeCreateField:
+		field := tableSpec.Field(change.Name)
+		options, _ := schemaspec.ResolveReferences(field.Options, table, live).(map[string]interface{})
+		created, err := s.fieldService.CreateField(ctx, dto.CreateFieldRequest{
+			TableID:  table.ID,
+			Name:     field.Name,
+			APIKey:   field.APIKey,
+			Type:     field.Type,
+			Options:  options,
+			Required: field.Required,
+			Unique:   field.Unique,
+		}, userID)
+		if err != nil {
+			return err
+		}
+		if field.Description != "" {
+			if created, err = s.fieldService.UpdateField(ctx, created.ID, dto.UpdateFieldRequest{Description: &field.Description}); err != nil {
+				return err
+			}
+		}
+		table.Fields = append(table.Fields, liveField(created))
+		return nil
+
+	case schemaspec.ChangeUpdateField:
+		field := tableSpec.Field(change.Name)
+		req := dto.UpdateFieldRequest{
+			Description: &field.Description,
+			Required:    &field.Required,
+			Unique:      &field.Unique,
+		}
+		if field.APIKey != "" {
+			req.APIKey = &field.APIKey
+		}
+		_, err := s.fieldService.UpdateField(ctx, change.TargetID, req)
+		return err
+
+	case schemaspec.ChangeCreateView:
+		view := tableSpec.View(change.Name)
+		req := dto.CreateViewRequest{TableID: table.ID, Name: view.Name, Type: view.Type, Description: view.Description}
+		if view.Filter != nil {
+			req.Filter, _ = schemaspec.ResolveReferences(view.Filter, table, live).(map[string]interface{})
+		}
+		if len(view.Sort) > 0 {
+			req.Sort = toMapSlice(schemaspec.ResolveReferences(view.Sort, table, live))
+		}
+		_, err := s.viewService.CreateView(ctx, req, userID)
+		return err
+
+	case schemaspec.ChangeUpdateView:
+		view := tableSpec.View(change.Name)
+		for _, detail := range change.Details {
+			var err error
+			switch {
+			case strings.HasPrefix(detail, "description"):
+				_, err = s.viewService.UpdateView(ctx, change.TargetID, dto.UpdateViewRequest{Description: &view.Description})
+			case detail == "filter":
+				filter, _ := schemaspec.ResolveReferences(view.Filter, table, live).(map[string]interface{})
+				err = s.viewService.UpdateViewFilter(ctx, change.TargetID, filter)
+			case detail == "sort":
+				err = s.viewService.UpdateViewSort(ctx, change.TargetID, toMapSlice(schemaspec.ResolveReferences(view.Sort, table, live)))
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case schemaspec.ChangeDeleteView:
+		return s.viewService.DeleteView(ctx, change.TargetID)
+
+	case schemaspec.ChangeDeleteField:
+		return s.fieldService.DeleteField(ctx, change.TargetID)
+
+	case schemaspec.ChangeDeleteTable:
+		if !s.permissionService.CanDeleteTable(ctx, userID, change.TargetID) {
+			return pkgerrors.ErrForbidden.WithDetails("没有权限删除表: " + change.Table)
+		}
+		return s.tableService.DeleteTable(ctx, change.TargetID)
+	}
+	return nil
+}
+
+// exportOptions 导出字段选项（目前只有公式表达式可以无损往返）
+func exportOptions(field *schemaspec.LiveField) map[string]interface{} {
+	formula, ok := field.Options["formula"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if expression, _ := formula["expression"].(string); expression != "" {
+		return map[string]interface{}{"expression": expression}
+	}
+	return nil
+}
+
+func liveField(field *dto.FieldResponse) *schemaspec.LiveField {
+	return &schemaspec.LiveField{
+		ID:          field.ID,
+		Name:        field.Name,
+		APIKey:      field.APIKey,
+		Type:        field.Type,
+		Description: field.Description,
+		Required:    field.Required,
+		Unique:      field.Unique,
+		Primary:     field.IsPrimary,
+		Options:     field.Options,
+	}
+}
+
+// emptyFilter 过滤条件是否为空（视图默认的空过滤不导出）
+func emptyFilter(filter map[string]interface{}) bool {
+	switch filters := filter["filters"].(type) {
+	case []map[string]interface{}:
+		return len(filters) == 0
+	case []interface{}:
+		return len(filters) == 0
+	}
+	return len(filter) == 0
+}
+
+// toMapSlice 将解析引用后的数组还原为对象数组
+func toMapSlice(value interface{}) []map[string]interface{} {
+	items, _ := value.([]interface{})
+	result := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
@@ -0,0 +1,625 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/printjob"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/internal/jsvm"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+var scriptLog = logger.Named("automation-script")
+
+// scriptActionType 运行脚本节点的动作类型（workflow_node.action 中的 type）
+const scriptActionType = "runScript"
+
+// printActionType 生成 PDF 节点的动作类型
+const printActionType = "generatePdf"
+
+// documentActionType 生成文档节点的动作类型
+const documentActionType = "generateDocument"
+
+// RunScriptRequest 试运行脚本请求
+type RunScriptRequest struct {
+	Script     string                 `json:"script" binding:"required"`
+	Input      map[string]interface{} `json:"input"`
+	WorkflowID string                 `json:"workflowId"` // 可选，用于审计日志
+}
+
+// ScriptRunResponse 脚本执行结果
+type ScriptRunResponse struct {
+	Status string `json:"status"` // completed / failed
+	Error  string `json:"error,omitempty"`
+	*jsvm.ScriptResult
+}
+
+// scriptNodeAction 运行脚本节点的动作配置
+type scriptNodeAction struct {
+	Type   string `json:"type"`
+	Script string `json:"script"`
+}
+
+// printNodeAction 生成 PDF 节点的动作配置：tableId、recordId 为空时取触发输入中的 tableId、recordId
+// （如页面按钮点击时的记录）；target 为 view 时打印 viewId 指定的视图
+type printNodeAction struct {
+	Type        string `json:"type"`
+	Target      string `json:"target"` // record（默认）| view
+	TableID     string `json:"tableId"`
+	RecordID    string `json:"recordId"`
+	ViewID      string `json:"viewId"`
+	Paper       string `json:"paper"`
+	Orientation string `json:"orientation"`
+}
+
+// documentNodeAction 生成文档节点的动作配置：按 templateId 指定的文档模板为记录生成文档，
+// recordId 为空时取触发输入中的 recordId
+type documentNodeAction struct {
+	Type       string `json:"type"`
+	TemplateID string `json:"templateId"`
+	RecordID   string `json:"recordId"`
+}
+
+// ScriptActionService 自动化"运行脚本"动作 ✨
+// 在沙箱中执行用户脚本（见 jsvm.RunScript），脚本以触发者的身份读写记录：
+//   - 限制：执行时间、堆内存增长、调用栈、输出大小、API 调用次数，同时执行的脚本数限制 CPU 占用；
+//   - fetch 默认禁止访问内网与回环地址，响应体有大小上限；
+//   - 每次执行写入审计日志（脚本以 SHA-256 摘要记录），日志与输出保存在工作流运行及步骤中
+type ScriptActionService struct {
+	db                *gorm.DB
+	integration       *IntegrationService
+	recordService     *RecordService
+	fieldRepo         repository.FieldRepository
+	permissionService *PermissionServiceV2
+	printer           *PrintService              // 可选，执行生成 PDF 节点
+	documents         *DocumentTemplateService   // 可选，执行生成文档节点
+	authorizer        *BatchAuthorizationService // 可选，一次运行内复用表与角色的解析结果
+	cfg               config.AutomationScriptConfig
+	client            *http.Client
+	slots             chan struct{}
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc // 运行ID -> 取消函数
+}
+
+// NewScriptActionService 创建运行脚本动作服务
+func NewScriptActionService(
+	db *gorm.DB,
+	integration *IntegrationService,
+	recordService *RecordService,
+	fieldRepo repository.FieldRepository,
+	permissionService *PermissionServiceV2,
+	cfg config.AutomationScriptConfig,
+) *ScriptActionService {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = 4
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = 10 * time.Second
+	}
+	if cfg.FetchMaxBytes <= 0 {
+		cfg.FetchMaxBytes = 1 << 20
+	}
+	return &ScriptActionService{
+		db:                db,
+		integration:       integration,
+		recordService:     recordService,
+		fieldRepo:         fieldRepo,
+		permissionService: permissionService,
+		cfg:               cfg,
+		client:            newScriptFetchClient(cfg),
+		slots:             make(chan struct{}, cfg.MaxConcurrent),
+		running:           make(map[string]context.CancelFunc),
+	}
+}
+
+// SetBatchAuthorizer 设置批量权限校验服务，设置后同一次运行中各节点的权限检查复用表与角色的解析结果
+func (s *ScriptActionService) SetBatchAuthorizer(authorizer *BatchAuthorizationService) {
+	s.authorizer = authorizer
+}
+
+// SetPrintService 设置打印服务，设置后执行工作流中的生成 PDF 节点
+func (s *ScriptActionService) SetPrintService(printer *PrintService) {
+	s.printer = printer
+}
+
+// SetDocumentService 设置文档模板服务，设置后执行工作流中的生成文档节点
+func (s *ScriptActionService) SetDocumentService(documents *DocumentTemplateService) {
+	s.documents = documents
+}
+
+// TestRun 试运行脚本（同步执行，结果不保存到工作流运行，但写入审计日志）
+func (s *ScriptActionService) TestRun(ctx context.Context, userID string, req RunScriptRequest) (*ScriptRunResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, pkgerrors.ErrForbidden.WithDetails("未启用自动化运行脚本")
+	}
+	if strings.TrimSpace(req.Script) == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("脚本不能为空")
+	}
+	result, err := s.execute(ctx, userID, req.WorkflowID, "", req.Script, s.cfg.Timeout, req.Input)
+	return newScriptRunResponse(result, err), nil
+}
+
+// StartRun 在后台执行工作流运行中的运行脚本与生成 PDF 节点（按节点顺序），工作流没有这两类节点时返回 false
+// 后续节点通过 input.steps[节点ID] 读取前序节点的输出（生成 PDF 节点输出任务ID、文件名与下载链接）；
+// 其他类型的节点记为跳过
+func (s *ScriptActionService) StartRun(ctx context.Context, run *models.WorkflowRun, input map[string]interface{}) (bool, error) {
+	var nodes []*models.WorkflowNode
+	if err := s.db.WithContext(ctx).
+		Where("workflow_id = ? AND is_enabled = ? AND deleted_time IS NULL", run.WorkflowID, true).
+		Order("sort_order ASC").
+		Find(&nodes).Error; err != nil {
+		return false, pkgerrors.Database(err, "获取工作流节点失败")
+	}
+	runnable := false
+	for _, node := range nodes {
+		if s.runnable(node) {
+			runnable = true
+			break
+		}
+	}
+	if !runnable {
+		return false, nil
+	}
+
+	// 运行不随请求取消（可通过 CancelRun 中断），保留触发请求的链路
+	runCtx, cancel := context.WithCancel(tracing.Detach(ctx))
+	s.mu.Lock()
+	s.running[run.ID] = cancel
+	s.mu.Unlock()
+
+	go func() {
+		runCtx, span := tracing.Start(runCtx, "automation.script_run", tracing.WithSpanKind(tracing.SpanKindConsumer))
+		defer func() {
+			if r := recover(); r != nil {
+				span.RecordError(fmt.Errorf("panic: %v", r))
+				scriptLog.Error(runCtx, "脚本运行异常",
+					logger.String("run_id", run.ID),
+					logger.Any("panic", r))
+			}
+			span.End()
+			cancel()
+			s.mu.Lock()
+			delete(s.running, run.ID)
+			s.mu.Unlock()
+		}()
+		s.executeRun(runCtx, run, nodes, input)
+	}()
+	return true, nil
+}
+
+// CancelRun 取消正在执行的运行（正在执行的脚本被中断）
+func (s *ScriptActionService) CancelRun(runID string) {
+	s.mu.Lock()
+	cancel, ok := s.running[runID]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (s *ScriptActionService) executeRun(ctx context.Context, run *models.WorkflowRun, nodes []*models.WorkflowNode, input map[string]interface{}) {
+	stepOutputs := make(map[string]interface{})
+	var logs []map[string]interface{}
+	status, errMessage := "completed", ""
+	if s.authorizer != nil {
+		// 脚本逐条读写记录时不再为每次调用重复查询表所属 Base 与协作者角色
+		ctx = WithAuthorizationBatch(ctx, s.authorizer.NewBatch())
+	}
+
+	for i, node := range nodes {
+		step := &models.WorkflowRunStep{
+			ID:        utils.GenerateIDWithPrefix("wrs"),
+			RunID:     run.ID,
+			NodeID:    node.ID,
+			StepOrder: i,
+			Status:    "skipped",
+		}
+		if !s.runnable(node) {
+			s.saveStep(ctx, step)
+			continue
+		}
+
+		stepInput := make(map[string]interface{}, len(input)+1)
+		for key, value := range input {
+			stepInput[key] = value
+		}
+		stepInput["steps"] = stepOutputs
+
+		started := time.Now()
+		step.StartedTime = &started
+		var resp *ScriptRunResponse
+		if action, ok := parsePrintNode(node); ok {
+			resp = s.executePrint(ctx, run.CreatedBy, action, input)
+		} else if action, ok := parseDocumentNode(node); ok {
+			resp = s.executeDocument(ctx, run.CreatedBy, action, input)
+		} else {
+			action, _ := parseScriptNode(node)
+			timeout := s.cfg.Timeout
+			if node.Timeout > 0 && time.Duration(node.Timeout)*time.Second < timeout {
+				timeout = time.Duration(node.Timeout) * time.Second
+			}
+			result, err := s.execute(ctx, run.CreatedBy, run.WorkflowID, run.ID, action.Script, timeout, stepInput)
+			resp = newScriptRunResponse(result, err)
+		}
+
+		completed := time.Now()
+		duration := completed.Sub(started).Milliseconds()
+		step.CompletedTime = &completed
+		step.Duration = &duration
+		step.Status = resp.Status
+		step.Input = jsonString(stepInput)
+		step.Output = jsonString(map[string]interface{}{"result": resp.Result, "outputs": resp.Outputs})
+		step.Logs = jsonString(resp.Logs)
+		if resp.Error != "" {
+			step.ErrorMessage = &resp.Error
+		}
+		s.saveStep(ctx, step)
+
+		stepOutputs[node.ID] = resp.Outputs
+		for _, entry := range resp.Logs {
+			logs = append(logs, map[string]interface{}{"nodeId": node.ID, "level": entry.Level, "message": entry.Message, "time": entry.Time})
+		}
+		if resp.Status != "completed" {
+			status, errMessage = "failed", fmt.Sprintf("节点 %s: %s", node.Name, resp.Error)
+			break
+		}
+	}
+
+	completed := time.Now()
+	updates := map[string]interface{}{
+		"status":         status,
+		"progress":       100,
+		"completed_time": completed,
+		"output":         jsonString(map[string]interface{}{"steps": stepOutputs}),
+		"logs":           jsonString(logs),
+	}
+	if run.StartedTime != nil {
+		updates["duration"] = completed.Sub(*run.StartedTime).Milliseconds()
+	}
+	if errMessage != "" {
+		updates["error_message"] = errMessage
+	}
+	// 已被停止的运行保持停止状态
+	if err := s.db.Model(&models.WorkflowRun{}).Where("id = ? AND status = ?", run.ID, "running").Updates(updates).Error; err != nil {
+		scriptLog.Warn(ctx, "保存工作流运行结果失败", logger.String("run_id", run.ID), logger.ErrorField(err))
+	}
+}
+
+func (s *ScriptActionService) saveStep(ctx context.Context, step *models.WorkflowRunStep) {
+	if err := s.db.Omit("Run", "Node").Create(step).Error; err != nil {
+		scriptLog.Warn(ctx, "保存工作流运行步骤失败", logger.String("run_id", step.RunID), logger.ErrorField(err))
+	}
+}
+
+// execute 占用执行槽位后运行脚本，并写入审计日志
+func (s *ScriptActionService) execute(ctx context.Context, userID, workflowID, runID, script string, timeout time.Duration, input map[string]interface{}) (*jsvm.ScriptResult, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, jsvm.ErrScriptCanceled
+	}
+
+	limits := jsvm.ScriptLimits{
+		Timeout:          timeout,
+		MaxMemoryBytes:   uint64(s.cfg.MaxMemoryMB) << 20,
+		MaxCallStackSize: 1024,
+		MaxOutputBytes:   s.cfg.MaxOutputKB << 10,
+		MaxAPICalls:      s.cfg.MaxAPICalls,
+	}
+	result, err := jsvm.RunScript(ctx, limits, &scriptHost{service: s, userID: userID}, script, input)
+	s.audit(ctx, userID, workflowID, runID, script, result, err)
+	return result, err
+}
+
+// audit 写入审计日志
+func (s *ScriptActionService) audit(ctx context.Context, userID, workflowID, runID, script string, result *jsvm.ScriptResult, runErr error) {
+	digest := sha256.Sum256([]byte(script))
+	metadata := map[string]interface{}{
+		"scriptSha256": hex.EncodeToString(digest[:]),
+	}
+	if runID != "" {
+		metadata["runId"] = runID
+	}
+	entry := models.AuditLog{
+		ID:           utils.GenerateIDWithPrefix("aud"),
+		Action:       "automation_script_run",
+		ResourceType: "workflow",
+		Status:       "success",
+		Severity:     "info",
+		Metadata:     jsonString(metadata),
+		CreatedTime:  time.Now(),
+	}
+	if workflowID != "" {
+		entry.ResourceID = &workflowID
+	}
+	if userID != "" {
+		entry.UserID = &userID
+	}
+	if result != nil {
+		metadata["apiCalls"] = result.APICalls
+		metadata["logLines"] = len(result.Logs)
+		metadata["truncated"] = result.Truncated
+		entry.Metadata = jsonString(metadata)
+		duration := result.Duration.Milliseconds()
+		entry.Duration = &duration
+	}
+	if runErr != nil {
+		message := runErr.Error()
+		entry.Status = "failure"
+		entry.Severity = "warning"
+		entry.ErrorMessage = &message
+	}
+
+	if err := s.db.WithContext(context.WithoutCancel(ctx)).
+		Omit("User", "Organization", "Space", "Base", "Table", "Record", "Field").
+		Create(&entry).Error; err != nil {
+		scriptLog.Warn(ctx, "写入脚本审计日志失败", logger.String("workflow_id", workflowID), logger.ErrorField(err))
+	}
+}
+
+func newScriptRunResponse(result *jsvm.ScriptResult, err error) *ScriptRunResponse {
+	if result == nil {
+		result = &jsvm.ScriptResult{Logs: []jsvm.ScriptLogEntry{}}
+	}
+	resp := &ScriptRunResponse{Status: "completed", ScriptResult: result}
+	if err != nil {
+		resp.Status = "failed"
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// runnable 节点是否由该服务执行（运行脚本节点需启用脚本，生成 PDF、生成文档节点需设置对应服务）
+func (s *ScriptActionService) runnable(node *models.WorkflowNode) bool {
+	if _, ok := parseScriptNode(node); ok {
+		return s.cfg.Enabled
+	}
+	if _, ok := parsePrintNode(node); ok {
+		return s.printer != nil
+	}
+	if _, ok := parseDocumentNode(node); ok {
+		return s.documents != nil
+	}
+	return false
+}
+
+// executePrint 以触发者的身份同步生成 PDF，输出任务ID、文件名、页数与下载链接
+func (s *ScriptActionService) executePrint(ctx context.Context, userID string, action *printNodeAction, input map[string]interface{}) *ScriptRunResponse {
+	req := CreatePrintJobRequest{
+		TableID:     action.TableID,
+		ViewID:      action.ViewID,
+		Paper:       action.Paper,
+		Orientation: action.Orientation,
+	}
+	if req.TableID == "" {
+		req.TableID, _ = input["tableId"].(string)
+	}
+	if action.Target != string(printjob.TargetView) {
+		req.RecordID = action.RecordID
+		if req.RecordID == "" {
+			req.RecordID, _ = input["recordId"].(string)
+		}
+		if req.RecordID == "" {
+			return newScriptRunResponse(nil, errors.New("缺少要打印的记录（节点未指定 recordId，触发输入中也没有 recordId）"))
+		}
+	}
+
+	started := time.Now()
+	detail, err := s.printer.Generate(ctx, userID, req)
+	if err != nil {
+		return newScriptRunResponse(nil, err)
+	}
+	outputs := map[string]interface{}{
+		"jobId":    detail.ID,
+		"fileName": detail.FileName,
+		"pages":    detail.Pages,
+		"url":      detail.DownloadURL,
+	}
+	return newScriptRunResponse(&jsvm.ScriptResult{
+		Result:   outputs,
+		Outputs:  outputs,
+		Logs:     []jsvm.ScriptLogEntry{},
+		Duration: time.Since(started),
+	}, nil)
+}
+
+// executeDocument 以触发者的身份同步生成文档并写入附件字段，输出任务ID、附件ID与文件名
+func (s *ScriptActionService) executeDocument(ctx context.Context, userID string, action *documentNodeAction, input map[string]interface{}) *ScriptRunResponse {
+	recordID := action.RecordID
+	if recordID == "" {
+		recordID, _ = input["recordId"].(string)
+	}
+	if recordID == "" {
+		return newScriptRunResponse(nil, errors.New("缺少要生成文档的记录（节点未指定 recordId，触发输入中也没有 recordId）"))
+	}
+
+	started := time.Now()
+	job, err := s.documents.GenerateNow(ctx, userID, action.TemplateID, recordID)
+	if err != nil {
+		return newScriptRunResponse(nil, err)
+	}
+	outputs := map[string]interface{}{"jobId": job.ID}
+	if len(job.Results) > 0 {
+		outputs["attachmentId"] = job.Results[0].AttachmentID
+		outputs["fileName"] = job.Results[0].FileName
+	}
+	return newScriptRunResponse(&jsvm.ScriptResult{
+		Result:   outputs,
+		Outputs:  outputs,
+		Logs:     []jsvm.ScriptLogEntry{},
+		Duration: time.Since(started),
+	}, nil)
+}
+
+// parseDocumentNode 解析生成文档节点
+func parseDocumentNode(node *models.WorkflowNode) (*documentNodeAction, bool) {
+	if node.Action == nil {
+		return nil, false
+	}
+	var action documentNodeAction
+	if err := json.Unmarshal([]byte(*node.Action), &action); err != nil || action.Type != documentActionType {
+		return nil, false
+	}
+	return &action, action.TemplateID != ""
+}
+
+// parsePrintNode 解析生成 PDF 节点
+func parsePrintNode(node *models.WorkflowNode) (*printNodeAction, bool) {
+	if node.Action == nil {
+		return nil, false
+	}
+	var action printNodeAction
+	if err := json.Unmarshal([]byte(*node.Action), &action); err != nil || action.Type != printActionType {
+		return nil, false
+	}
+	return &action, true
+}
+
+// parseScriptNode 解析运行脚本节点
+func parseScriptNode(node *models.WorkflowNode) (*scriptNodeAction, bool) {
+	if node.Action == nil {
+		return nil, false
+	}
+	var action scriptNodeAction
+	if err := json.Unmarshal([]byte(*node.Action), &action); err != nil || action.Type != scriptActionType {
+		return nil, false
+	}
+	return &action, strings.TrimSpace(action.Script) != ""
+}
+
+func jsonString(value interface{}) *string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	encoded := string(data)
+	return &encoded
+}
+
+// scriptHost 脚本的宿主 API，以触发者的身份校验权限
+type scriptHost struct {
+	service *ScriptActionService
+	userID  string
+}
+
+// QueryRecords records.query(tableId, {field, value, limit, offset})
+// 指定 field 时按字段值精确匹配，否则按创建顺序分页；返回的字段按名称
+func (h *scriptHost) QueryRecords(ctx context.Context, tableID string, options map[string]interface{}) (interface{}, error) {
+	limit := integrationMaxLimit
+	if n := optionInt(options["limit"]); n > 0 && n < limit {
+		limit = n
+	}
+	if field, ok := options["field"].(string); ok && field != "" {
+		return h.service.integration.FindRecords(ctx, h.userID, tableID, IntegrationFindRequest{Field: field, Value: options["value"], Limit: limit})
+	}
+
+	if !h.service.permissionService.CanAccessRecord(ctx, h.userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	offset := 0
+	if n := optionInt(options["offset"]); n > 0 {
+		offset = n
+	}
+	records, _, err := h.service.recordService.ListRecords(ctx, tableID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := h.service.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取字段列表失败")
+	}
+	names := fieldNames(fields)
+	items := make([]IntegrationItem, 0, len(records))
+	for _, record := range records {
+		items = append(items, newIntegrationItem(record, names))
+	}
+	return items, nil
+}
+
+// optionInt 脚本传入的数字选项（goja 导出为 int64 或 float64）
+func optionInt(value interface{}) int {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// UpdateRecord records.update(tableId, recordId, fields)，字段可按ID或名称指定
+func (h *scriptHost) UpdateRecord(ctx context.Context, tableID, recordID string, fields map[string]interface{}) (interface{}, error) {
+	return h.service.integration.UpdateRecord(ctx, h.userID, tableID, IntegrationRecordRequest{RecordID: recordID, Fields: fields})
+}
+
+// Fetch 发起 HTTP 请求（超时与响应体大小受限）
+func (h *scriptHost) Fetch(ctx context.Context, req *jsvm.ScriptFetchRequest) (*jsvm.ScriptFetchResponse, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("fetch 只支持 http(s) URL: %s", req.URL)
+	}
+	var body io.Reader
+	if req.Body != "" {
+		body = strings.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	if req.Body != "" && httpReq.Header.Get("Content-Type") == "" {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := h.service.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("fetch 请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	maxBytes := h.service.cfg.FetchMaxBytes
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch 读取响应失败: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("fetch 响应超过 %d 字节", maxBytes)
+	}
+	headers := make(map[string]string, len(resp.Header))
+	for key := range resp.Header {
+		headers[strings.ToLower(key)] = resp.Header.Get(key)
+	}
+	return &jsvm.ScriptFetchResponse{Status: resp.StatusCode, Headers: headers, Body: string(data)}, nil
+}
+
+// newScriptFetchClient 脚本 fetch 使用的 HTTP 客户端
+// 禁止内网访问时在建立连接前检查解析后的地址，重定向同样受限
+func newScriptFetchClient(cfg config.AutomationScriptConfig) *http.Client {
+	return netguard.NewHTTPClient(cfg.FetchTimeout, cfg.FetchAllowPrivate)
+}
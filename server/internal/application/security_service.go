@@ -0,0 +1,434 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueobject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordValueobject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// policyCacheTTL 安全策略缓存时间（策略在每个请求上检查，避免频繁查库）
+const policyCacheTTL = 30 * time.Second
+
+// SecurityService 工作空间安全策略服务 ✨
+//
+// 策略由空间所有者配置，在认证后的中间件中按请求所属空间执行：
+// IP白名单、会话最长时长、并发会话数，以及敏感操作前的重新认证。
+// 违规请求会被拒绝并写入审计日志。
+type SecurityService struct {
+	repo              security.Repository
+	baseRepo          baseRepo.BaseRepository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         fieldRepo.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	permissionService *PermissionServiceV2
+	records           RecordLocator         // 可选：按记录ID解析所属表
+	attachments       attachment.Repository // 可选：按附件ID解析所属表
+
+	policies sync.Map // spaceID -> cachedPolicy
+	// 资源 -> 上级资源ID 缓存（归属关系不会变化）
+	baseSpaces sync.Map
+	tableBases sync.Map
+	fieldTable sync.Map
+	viewTables sync.Map
+}
+
+type cachedPolicy struct {
+	policy    *security.Policy
+	expiresAt time.Time
+}
+
+// NewSecurityService 创建工作空间安全策略服务
+func NewSecurityService(
+	repo security.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	viewRepo viewRepo.ViewRepository,
+	permissionService *PermissionServiceV2,
+) *SecurityService {
+	return &SecurityService{
+		repo:              repo,
+		baseRepo:          baseRepo,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepo,
+		permissionService: permissionService,
+	}
+}
+
+// RecordLocator 按记录ID查找所属表（旧版记录路由与实时订阅只携带记录ID）
+type RecordLocator interface {
+	FindTableIDByRecordID(ctx context.Context, recordID recordValueobject.RecordID) (string, error)
+}
+
+// SetResourceLocators 设置记录与附件的归属查询，使只携带记录ID或附件ID的请求也能解析所属空间
+func (s *SecurityService) SetResourceLocators(records RecordLocator, attachments attachment.Repository) {
+	s.records = records
+	s.attachments = attachments
+}
+
+// UpdateSecurityPolicyRequest 更新安全策略请求
+type UpdateSecurityPolicyRequest struct {
+	IPAllowlist           []string `json:"ipAllowlist"`
+	SessionMaxAgeMinutes  int      `json:"sessionMaxAgeMinutes"`
+	MaxConcurrentSessions int      `json:"maxConcurrentSessions"`
+	ReauthForSensitive    bool     `json:"reauthForSensitive"`
+	ReauthWindowMinutes   int      `json:"reauthWindowMinutes"`
+}
+
+// EnforceRequest 策略检查请求（由中间件从请求上下文构造）
+type EnforceRequest struct {
+	SpaceID    string
+	UserID     string
+	SessionID  string
+	AuthTime   time.Time
+	ReauthTime time.Time
+	ClientIP   string
+	UserAgent  string
+	Method     string
+	Path       string
+}
+
+// ==================== 策略管理 ====================
+
+// GetPolicy 获取空间安全策略（仅空间所有者）
+func (s *SecurityService) GetPolicy(ctx context.Context, userID, spaceID string) (*security.Policy, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+	policy, err := s.repo.GetPolicy(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	if policy == nil {
+		policy = security.NewDefaultPolicy(spaceID)
+	}
+	return policy, nil
+}
+
+// UpdatePolicy 更新空间安全策略（仅空间所有者）
+func (s *SecurityService) UpdatePolicy(ctx context.Context, userID, spaceID string, req UpdateSecurityPolicyRequest) (*security.Policy, error) {
+	if err := s.ensureOwner(ctx, userID, spaceID); err != nil {
+		return nil, err
+	}
+
+	allowlist := req.IPAllowlist
+	if allowlist == nil {
+		allowlist = []string{}
+	}
+	policy := &security.Policy{
+		SpaceID:               spaceID,
+		IPAllowlist:           allowlist,
+		SessionMaxAgeMinutes:  req.SessionMaxAgeMinutes,
+		MaxConcurrentSessions: req.MaxConcurrentSessions,
+		ReauthForSensitive:    req.ReauthForSensitive,
+		ReauthWindowMinutes:   req.ReauthWindowMinutes,
+		UpdatedBy:             userID,
+		UpdatedTime:           time.Now(),
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	if err := s.repo.SavePolicy(ctx, policy); err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	s.policies.Delete(spaceID)
+
+	logger.Info("✅ 工作空间安全策略已更新",
+		logger.String("space_id", spaceID),
+		logger.String("updated_by", userID),
+		logger.Int("ip_rules", len(policy.IPAllowlist)),
+		logger.Int("max_sessions", policy.MaxConcurrentSessions))
+
+	return policy, nil
+}
+
+// ==================== 策略执行 ====================
+
+// Enforce 对请求执行空间安全策略
+// 未配置策略的空间直接放行；策略无法加载时拒绝；违规时返回对应错误并写入审计日志
+func (s *SecurityService) Enforce(ctx context.Context, req EnforceRequest) error {
+	policy, err := s.cachedPolicy(ctx, req.SpaceID)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	now := time.Now()
+
+	if !policy.AllowsIP(req.ClientIP) {
+		s.recordViolation(ctx, req, security.RuleIPAllowlist, fmt.Sprintf("IP %s 不在白名单内", req.ClientIP))
+		return pkgerrors.ErrIPNotAllowed.WithDetails(req.ClientIP)
+	}
+
+	if policy.SessionExpired(req.AuthTime, now) {
+		s.recordViolation(ctx, req, security.RuleSessionMaxAge,
+			fmt.Sprintf("会话已持续 %s，超过 %d 分钟", now.Sub(req.AuthTime).Round(time.Second), policy.SessionMaxAgeMinutes))
+		return pkgerrors.ErrSessionExpired.WithDetails(fmt.Sprintf("会话最长 %d 分钟", policy.SessionMaxAgeMinutes))
+	}
+
+	if policy.MaxConcurrentSessions > 0 && req.SessionID != "" {
+		if err := s.checkConcurrentSessions(ctx, req, policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EnforceSensitive 敏感操作前检查重新认证（需先通过 Enforce）
+func (s *SecurityService) EnforceSensitive(ctx context.Context, req EnforceRequest, operation security.SensitiveOperation) error {
+	policy, err := s.cachedPolicy(ctx, req.SpaceID)
+	if err != nil {
+		return err
+	}
+	if policy == nil || !policy.NeedsReauth(req.ReauthTime, time.Now()) {
+		return nil
+	}
+
+	s.recordViolation(ctx, req, security.RuleReauthRequired, fmt.Sprintf("敏感操作 %s 未重新认证", operation))
+	return pkgerrors.ErrReauthRequired.WithDetails(map[string]interface{}{
+		"operation":     operation,
+		"windowMinutes": int(policy.ReauthWindow() / time.Minute),
+	})
+}
+
+// checkConcurrentSessions 当前会话必须在用户最近的 N 个活跃会话内，更早的会话会被吊销
+// 无法查询活跃会话时拒绝请求（不能确认会话未超限）
+func (s *SecurityService) checkConcurrentSessions(ctx context.Context, req EnforceRequest, policy *security.Policy) error {
+	sessions, err := s.repo.ListActiveSessions(ctx, req.UserID)
+	if err != nil {
+		logger.Warn("查询活跃会话失败，拒绝请求",
+			logger.String("user_id", req.UserID),
+			logger.ErrorField(err))
+		return pkgerrors.Database(err, "查询活跃会话失败")
+	}
+
+	for i, session := range sessions {
+		if session.ID != req.SessionID {
+			continue
+		}
+		if i < policy.MaxConcurrentSessions {
+			return nil
+		}
+		break
+	}
+
+	if err := s.repo.RevokeSession(ctx, req.SessionID); err != nil {
+		logger.Warn("吊销超限会话失败",
+			logger.String("session_id", req.SessionID),
+			logger.ErrorField(err))
+	}
+	s.recordViolation(ctx, req, security.RuleConcurrentSessions,
+		fmt.Sprintf("活跃会话 %d 个，超过上限 %d", len(sessions), policy.MaxConcurrentSessions))
+	return pkgerrors.ErrSessionLimitExceeded.WithDetails(fmt.Sprintf("最多 %d 个并发会话", policy.MaxConcurrentSessions))
+}
+
+// cachedPolicy 读取空间策略（带短时缓存；查询失败时返回错误，由调用方拒绝请求）
+func (s *SecurityService) cachedPolicy(ctx context.Context, spaceID string) (*security.Policy, error) {
+	if v, ok := s.policies.Load(spaceID); ok {
+		cached := v.(cachedPolicy)
+		if time.Now().Before(cached.expiresAt) {
+			return cached.policy, nil
+		}
+	}
+
+	policy, err := s.repo.GetPolicy(ctx, spaceID)
+	if err != nil {
+		logger.Warn("加载工作空间安全策略失败，拒绝请求",
+			logger.String("space_id", spaceID),
+			logger.ErrorField(err))
+		return nil, pkgerrors.Database(err, "加载工作空间安全策略失败")
+	}
+	s.policies.Store(spaceID, cachedPolicy{policy: policy, expiresAt: time.Now().Add(policyCacheTTL)})
+	return policy, nil
+}
+
+// recordViolation 写入策略违规审计日志
+func (s *SecurityService) recordViolation(ctx context.Context, req EnforceRequest, rule security.ViolationRule, detail string) {
+	violation := &security.Violation{
+		SpaceID:   req.SpaceID,
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+		Rule:      rule,
+		IPAddress: req.ClientIP,
+		UserAgent: req.UserAgent,
+		Method:    req.Method,
+		Path:      req.Path,
+		Detail:    detail,
+	}
+	if err := s.repo.RecordViolation(ctx, violation); err != nil {
+		logger.Error("写入安全策略审计日志失败",
+			logger.String("space_id", req.SpaceID),
+			logger.String("rule", string(rule)),
+			logger.ErrorField(err))
+	}
+
+	logger.Warn("工作空间安全策略拦截",
+		logger.String("space_id", req.SpaceID),
+		logger.String("user_id", req.UserID),
+		logger.String("rule", string(rule)),
+		logger.String("path", req.Path))
+}
+
+// ==================== 空间解析 ====================
+
+// ResourceRefs 请求路径中的资源ID
+type ResourceRefs struct {
+	SpaceID      string
+	BaseID       string
+	TableID      string
+	FieldID      string
+	ViewID       string
+	RecordID     string
+	AttachmentID string
+}
+
+// empty 是否没有携带任何资源ID
+func (r ResourceRefs) empty() bool {
+	return r == ResourceRefs{}
+}
+
+// ResolveSpace 根据请求路径中的资源解析所属空间（无法解析时返回空字符串）
+func (s *SecurityService) ResolveSpace(ctx context.Context, refs ResourceRefs) string {
+	spaceID, _ := s.ResolveRequestSpace(ctx, refs)
+	return spaceID
+}
+
+// ResolveBase 根据请求路径中的资源解析所属 Base（无法解析时返回空字符串）
+func (s *SecurityService) ResolveBase(ctx context.Context, refs ResourceRefs) string {
+	baseID, _ := s.resolveBase(ctx, refs)
+	return baseID
+}
+
+// ResolveRequestSpace 解析请求所属空间，供策略执行使用
+// 未携带任何资源ID时返回空字符串；携带了资源ID却无法解析（资源不存在或查询失败）时返回错误，
+// 调用方应拒绝请求，而不是当作不属于任何空间放行
+func (s *SecurityService) ResolveRequestSpace(ctx context.Context, refs ResourceRefs) (string, error) {
+	if refs.SpaceID != "" {
+		return refs.SpaceID, nil
+	}
+	if refs.empty() {
+		return "", nil
+	}
+
+	baseID, err := s.resolveBase(ctx, refs)
+	if err != nil {
+		return "", err
+	}
+
+	return s.resolveCached(&s.baseSpaces, baseID, func() (string, error) {
+		base, err := s.baseRepo.FindByID(ctx, baseID)
+		if err != nil || base == nil {
+			return "", err
+		}
+		return base.SpaceID, nil
+	})
+}
+
+// resolveBase 根据请求路径中的资源解析所属 Base
+func (s *SecurityService) resolveBase(ctx context.Context, refs ResourceRefs) (string, error) {
+	if refs.BaseID != "" {
+		return refs.BaseID, nil
+	}
+
+	tableID, err := s.resolveTable(ctx, refs)
+	if err != nil {
+		return "", err
+	}
+
+	return s.resolveCached(&s.tableBases, tableID, func() (string, error) {
+		table, err := s.tableRepo.GetByID(ctx, tableID)
+		if err != nil || table == nil {
+			return "", err
+		}
+		return table.BaseID(), nil
+	})
+}
+
+// resolveTable 根据字段、视图、记录或附件解析所属表
+// 记录与附件数量大且会被删除，不做缓存
+func (s *SecurityService) resolveTable(ctx context.Context, refs ResourceRefs) (string, error) {
+	switch {
+	case refs.TableID != "":
+		return refs.TableID, nil
+	case refs.FieldID != "":
+		return s.resolveCached(&s.fieldTable, refs.FieldID, func() (string, error) {
+			field, err := s.fieldRepo.FindByID(ctx, fieldValueobject.NewFieldID(refs.FieldID))
+			if err != nil || field == nil {
+				return "", err
+			}
+			return field.TableID(), nil
+		})
+	case refs.ViewID != "":
+		return s.resolveCached(&s.viewTables, refs.ViewID, func() (string, error) {
+			view, err := s.viewRepo.FindByID(ctx, refs.ViewID)
+			if err != nil || view == nil {
+				return "", err
+			}
+			return view.TableID(), nil
+		})
+	case refs.RecordID != "":
+		if s.records == nil {
+			return "", pkgerrors.ErrNotFound.WithDetails("无法解析记录所属的表")
+		}
+		tableID, err := s.records.FindTableIDByRecordID(ctx, recordValueobject.NewRecordID(refs.RecordID))
+		if err != nil || tableID == "" {
+			return "", pkgerrors.ErrNotFound.WithDetails("记录不存在")
+		}
+		return tableID, nil
+	case refs.AttachmentID != "":
+		if s.attachments == nil {
+			return "", pkgerrors.ErrNotFound.WithDetails("无法解析附件所属的表")
+		}
+		item, err := s.attachments.GetAttachmentByID(ctx, refs.AttachmentID)
+		if err != nil {
+			return "", pkgerrors.Database(err, "解析附件所属表失败")
+		}
+		if item == nil || item.TableID == "" {
+			return "", pkgerrors.ErrNotFound.WithDetails("附件不存在")
+		}
+		return item.TableID, nil
+	}
+	return "", pkgerrors.ErrNotFound.WithDetails("无法解析请求所属的空间")
+}
+
+// resolveCached 带缓存地解析上级资源ID（查询失败或资源不存在时返回错误，不缓存）
+func (s *SecurityService) resolveCached(cache *sync.Map, key string, load func() (string, error)) (string, error) {
+	if v, ok := cache.Load(key); ok {
+		return v.(string), nil
+	}
+	parent, err := load()
+	if err != nil {
+		return "", pkgerrors.Database(err, "解析请求所属空间失败")
+	}
+	if parent == "" {
+		return "", pkgerrors.ErrNotFound.WithDetails(key)
+	}
+	cache.Store(key, parent)
+	return parent, nil
+}
+
+// ensureOwner 仅空间所有者可管理安全策略
+func (s *SecurityService) ensureOwner(ctx context.Context, userID, spaceID string) error {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != entity.RoleOwner {
+		return pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以管理安全策略")
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	baseEntity "github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// stubSecurityRepo 固定策略与活跃会话，可模拟查询失败
+type stubSecurityRepo struct {
+	security.Repository
+	policy      *security.Policy
+	policyErr   error
+	sessions    []*security.Session
+	sessionsErr error
+}
+
+func (r *stubSecurityRepo) GetPolicy(context.Context, string) (*security.Policy, error) {
+	return r.policy, r.policyErr
+}
+
+func (r *stubSecurityRepo) ListActiveSessions(context.Context, string) ([]*security.Session, error) {
+	return r.sessions, r.sessionsErr
+}
+
+func (r *stubSecurityRepo) RevokeSession(context.Context, string) error { return nil }
+
+func (r *stubSecurityRepo) RecordViolation(context.Context, *security.Violation) error { return nil }
+
+type stubSecurityBaseRepo struct {
+	baseRepo.BaseRepository
+	spaces map[string]string
+}
+
+func (r *stubSecurityBaseRepo) FindByID(_ context.Context, id string) (*baseEntity.Base, error) {
+	if spaceID, ok := r.spaces[id]; ok {
+		return &baseEntity.Base{ID: id, SpaceID: spaceID}, nil
+	}
+	return nil, nil
+}
+
+type stubSecurityRecordLocator map[string]string
+
+func (l stubSecurityRecordLocator) FindTableIDByRecordID(_ context.Context, recordID recordVO.RecordID) (string, error) {
+	if tableID, ok := l[recordID.String()]; ok {
+		return tableID, nil
+	}
+	return "", errors.New("record not found")
+}
+
+type stubSecurityAttachmentRepo struct {
+	attachment.Repository
+	tables map[string]string
+}
+
+func (r *stubSecurityAttachmentRepo) GetAttachmentByID(_ context.Context, id string) (*attachment.AttachmentItem, error) {
+	if tableID, ok := r.tables[id]; ok {
+		return &attachment.AttachmentItem{ID: id, TableID: tableID}, nil
+	}
+	return nil, pkgerrors.ErrNotFound
+}
+
+type securityFixture struct {
+	repo    *stubSecurityRepo
+	service *SecurityService
+	tableID string
+}
+
+func newSecurityFixture(t *testing.T) *securityFixture {
+	t.Helper()
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	name, _ := tableVO.NewTableName("订单")
+	table, err := tableEntity.NewTable("bse1", name, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+	tableID := table.ID().String()
+
+	repo := &stubSecurityRepo{}
+	service := NewSecurityService(
+		repo,
+		&stubSecurityBaseRepo{spaces: map[string]string{"bse1": "spc1"}},
+		&countingTableRepo{tables: map[string]*tableEntity.Table{tableID: table}},
+		nil, nil, nil,
+	)
+	service.SetResourceLocators(
+		stubSecurityRecordLocator{"rec1": tableID},
+		&stubSecurityAttachmentRepo{tables: map[string]string{"act1": tableID}},
+	)
+	return &securityFixture{repo: repo, service: service, tableID: tableID}
+}
+
+func TestResolveRequestSpaceFromRecordAndAttachment(t *testing.T) {
+	fx := newSecurityFixture(t)
+	ctx := context.Background()
+
+	for name, refs := range map[string]ResourceRefs{
+		"table":      {TableID: fx.tableID},
+		"record":     {RecordID: "rec1"},
+		"attachment": {AttachmentID: "act1"},
+	} {
+		spaceID, err := fx.service.ResolveRequestSpace(ctx, refs)
+		if err != nil || spaceID != "spc1" {
+			t.Fatalf("%s 应解析到 spc1，得到 %q, %v", name, spaceID, err)
+		}
+	}
+
+	// 携带了资源ID却无法解析时必须返回错误，而不是当作不属于任何空间放行
+	for name, refs := range map[string]ResourceRefs{
+		"unknown record":     {RecordID: "recMissing"},
+		"unknown attachment": {AttachmentID: "actMissing"},
+		"unknown table":      {TableID: "tblMissing"},
+	} {
+		if spaceID, err := fx.service.ResolveRequestSpace(ctx, refs); err == nil {
+			t.Fatalf("%s 应返回错误，得到 %q", name, spaceID)
+		}
+	}
+
+	// 不携带资源ID的请求不属于任何空间
+	if spaceID, err := fx.service.ResolveRequestSpace(ctx, ResourceRefs{}); err != nil || spaceID != "" {
+		t.Fatalf("未携带资源ID时应返回空，得到 %q, %v", spaceID, err)
+	}
+}
+
+func TestEnforceDeniesWhenPolicyOrSessionsUnavailable(t *testing.T) {
+	fx := newSecurityFixture(t)
+	ctx := context.Background()
+	req := EnforceRequest{SpaceID: "spc1", UserID: "usr1", SessionID: "ses1", AuthTime: time.Now()}
+
+	fx.repo.policyErr = errors.New("connection refused")
+	if err := fx.service.Enforce(ctx, req); err == nil {
+		t.Fatal("策略无法加载时应拒绝请求")
+	}
+	if err := fx.service.EnforceSensitive(ctx, req, security.OperationDataExport); err == nil {
+		t.Fatal("策略无法加载时敏感操作应被拒绝")
+	}
+
+	fx.repo.policyErr = nil
+	fx.repo.policy = &security.Policy{SpaceID: "spc1", MaxConcurrentSessions: 2}
+	fx.repo.sessionsErr = errors.New("connection refused")
+	if err := fx.service.Enforce(ctx, req); err == nil {
+		t.Fatal("无法查询活跃会话时应拒绝请求")
+	}
+
+	fx.repo.sessionsErr = nil
+	fx.repo.sessions = []*security.Session{{ID: "ses1"}}
+	if err := fx.service.Enforce(ctx, req); err != nil {
+		t.Fatalf("会话未超限时应放行，得到 %v", err)
+	}
+}
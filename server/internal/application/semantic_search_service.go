@@ -0,0 +1,817 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/semanticsearch"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var semanticSearchLog = logger.Named("semantic_search")
+
+// 搜索方式
+const (
+	SemanticSearchModeHybrid   = "hybrid"   // 语义与关键词结果按倒数排名融合（默认）
+	SemanticSearchModeSemantic = "semantic" // 仅语义检索
+	SemanticSearchModeKeyword  = "keyword"  // 仅关键词匹配
+)
+
+// semanticSearchDefaultLimit 默认返回的记录数
+const semanticSearchDefaultLimit = 20
+
+// semanticTextFieldTypes 可向量化与关键词匹配的文本类字段
+var semanticTextFieldTypes = map[string]bool{
+	fieldVO.TypeText:           true,
+	fieldVO.TypeSingleLineText: true,
+	fieldVO.TypeLongText:       true,
+	fieldVO.TypeRichText:       true,
+	fieldVO.TypeAI:             true,
+	fieldVO.TypeEmail:          true,
+	fieldVO.TypeURL:            true,
+	fieldVO.TypePhone:          true,
+	fieldVO.TypeSelect:         true,
+	fieldVO.TypeSingleSelect:   true,
+	fieldVO.TypeMultipleSelect: true,
+}
+
+// semanticRecordReader 读取记录（由 RecordService 实现，返回的字段已按当前角色脱敏）
+type semanticRecordReader interface {
+	GetRecord(ctx context.Context, tableID, recordID string) (*dto.RecordResponse, error)
+}
+
+// semanticBackfillRow 回填时按自增序号分页读取的记录
+type semanticBackfillRow struct {
+	ID         string `gorm:"column:__id"`
+	AutoNumber int64  `gorm:"column:__auto_number"`
+}
+
+// PutSemanticIndexRequest 创建或修改语义索引请求
+type PutSemanticIndexRequest struct {
+	FieldIDs []string `json:"fieldIds"`
+	Provider string   `json:"provider,omitempty"` // 为空时使用服务端默认服务商
+	Model    string   `json:"model,omitempty"`    // 为空时使用服务商的 embedding_model
+}
+
+// SemanticSearchRequest 记录搜索请求
+type SemanticSearchRequest struct {
+	Query string `json:"query"`
+	Mode  string `json:"mode,omitempty"` // hybrid（默认）/ semantic / keyword
+	Limit int    `json:"limit,omitempty"`
+}
+
+// SemanticSearchHit 一条搜索结果
+type SemanticSearchHit struct {
+	RecordID string              `json:"recordId"`
+	Score    float64             `json:"score"` // 语义检索为余弦相似度，其余为融合排名得分
+	Record   *dto.RecordResponse `json:"record"`
+}
+
+// SemanticSearchResponse 记录搜索结果
+type SemanticSearchResponse struct {
+	Mode        string              `json:"mode"`                  // 实际使用的搜索方式（语义检索不可用时混合搜索退化为关键词匹配）
+	IndexStatus string              `json:"indexStatus,omitempty"` // 语义索引状态（未建立索引时为空）
+	Hits        []SemanticSearchHit `json:"hits"`
+}
+
+// SemanticSearchService 记录语义搜索服务 ✨
+// 每张表可选择若干文本字段建立语义索引：
+//   - 后台按自增序号分批为已有记录生成向量，之后从变更流增量更新，文本未变化的记录不重复向量化
+//   - 向量存储在 pgvector 中，按表建立 HNSW 部分索引
+//   - 搜索时语义检索与关键词匹配按倒数排名融合，结果经 RecordService 读取并按当前角色脱敏；
+//     索引字段中有当前角色不可见的字段时不使用语义检索，避免通过排序推断明文
+type SemanticSearchService struct {
+	repo              semanticsearch.Repository
+	changeFeed        changefeed.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           semanticRecordReader
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	embedders         map[string]semanticsearch.Embedder
+	defaultProvider   string
+	permissionService *PermissionServiceV2
+	resolveSpace      func(ctx context.Context, tableID string) string
+	cfg               config.SemanticSearchConfig
+	now               func() time.Time
+
+	// 物理表查询（测试中替换）
+	backfillPage  func(ctx context.Context, baseID, tableID string, after int64, limit int) ([]semanticBackfillRow, error)
+	keywordSearch func(ctx context.Context, baseID, tableID string, fields []*fieldEntity.Field, query string, limit int) ([]string, error)
+}
+
+// NewSemanticSearchService 创建记录语义搜索服务
+func NewSemanticSearchService(
+	repo semanticsearch.Repository,
+	changeFeed changefeed.Repository,
+	tableRepository tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	recordRepository recordRepo.RecordRepository,
+	records semanticRecordReader,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	embedders map[string]semanticsearch.Embedder,
+	defaultProvider string,
+	securityService *SecurityService,
+	permissionService *PermissionServiceV2,
+	cfg config.SemanticSearchConfig,
+) *SemanticSearchService {
+	s := &SemanticSearchService{
+		repo:              repo,
+		changeFeed:        changeFeed,
+		tableRepo:         tableRepository,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepository,
+		records:           records,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		embedders:         embedders,
+		defaultProvider:   defaultProvider,
+		permissionService: permissionService,
+		resolveSpace: func(ctx context.Context, tableID string) string {
+			return securityService.ResolveSpace(ctx, ResourceRefs{TableID: tableID})
+		},
+		cfg: cfg,
+		now: time.Now,
+	}
+	s.backfillPage = s.physicalBackfillPage
+	s.keywordSearch = s.physicalKeywordSearch
+	return s
+}
+
+// GetIndex 获取表的语义索引（未建立时返回 nil）
+func (s *SemanticSearchService) GetIndex(ctx context.Context, userID, tableID string) (*semanticsearch.Index, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格")
+	}
+	index, err := s.repo.GetIndex(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取语义索引失败")
+	}
+	return index, nil
+}
+
+// PutIndex 创建或修改表的语义索引
+// 字段、服务商或模型变化时清空已有向量并重新全量生成
+func (s *SemanticSearchService) PutIndex(ctx context.Context, userID, tableID string, req PutSemanticIndexRequest) (*semanticsearch.Index, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的语义索引")
+	}
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	fieldIDs, err := s.validateIndexFields(ctx, tableID, req.FieldIDs)
+	if err != nil {
+		return nil, err
+	}
+	provider := req.Provider
+	if provider == "" {
+		provider = s.defaultProvider
+	}
+	if s.embedders[provider] == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("未配置向量化服务商: %s", provider))
+	}
+
+	index, err := s.repo.GetIndex(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取语义索引失败")
+	}
+	if index != nil && index.Provider == provider && index.Model == req.Model && equalStrings(index.FieldIDs, fieldIDs) {
+		return index, nil
+	}
+
+	// 从当前变更流位置开始增量消费，之前的数据由全量回填覆盖
+	cursor, err := s.changeFeed.Head(ctx)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "读取变更流位置失败")
+	}
+	if index == nil {
+		index = semanticsearch.NewIndex(s.resolveSpace(ctx, tableID), table.BaseID(), tableID, fieldIDs, provider, req.Model, cursor, userID)
+		if err := s.repo.SaveIndex(ctx, index); err != nil {
+			return nil, pkgerrors.Database(err, "保存语义索引失败")
+		}
+		return index, nil
+	}
+
+	previous := *index
+	index.Rebuild(fieldIDs, provider, req.Model, cursor)
+	if err := s.repo.SaveIndex(ctx, index); err != nil {
+		return nil, pkgerrors.Database(err, "保存语义索引失败")
+	}
+	if err := s.repo.ResetVectors(ctx, &previous); err != nil {
+		return nil, pkgerrors.Database(err, "清空语义索引向量失败")
+	}
+	return index, nil
+}
+
+// DeleteIndex 删除表的语义索引及全部向量
+func (s *SemanticSearchService) DeleteIndex(ctx context.Context, userID, tableID string) error {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的语义索引")
+	}
+	index, err := s.repo.GetIndex(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "获取语义索引失败")
+	}
+	if index == nil {
+		return pkgerrors.ErrNotFound.WithDetails("语义索引不存在")
+	}
+	if err := s.repo.DeleteIndex(ctx, index); err != nil {
+		return pkgerrors.Database(err, "删除语义索引失败")
+	}
+	return nil
+}
+
+// validateIndexFields 校验索引字段：去重后 1~MaxIndexedFields 个未加密的文本类字段
+func (s *SemanticSearchService) validateIndexFields(ctx context.Context, tableID string, ids []string) ([]string, error) {
+	fieldIDs := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			fieldIDs = append(fieldIDs, id)
+		}
+	}
+	if len(fieldIDs) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("请至少选择一个字段")
+	}
+	if len(fieldIDs) > semanticsearch.MaxIndexedFields {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("最多选择 %d 个字段", semanticsearch.MaxIndexedFields))
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	for _, id := range fieldIDs {
+		field := byID[id]
+		if field == nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("字段不存在: " + id)
+		}
+		if !semanticTextFieldTypes[field.Type().String()] {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 不是文本类型，不能建立语义索引", field.Name().String()))
+		}
+		if field.IsEncrypted() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("加密字段 %s 不能建立语义索引", field.Name().String()))
+		}
+	}
+	return fieldIDs, nil
+}
+
+// Start 启动后台向量化
+func (s *SemanticSearchService) Start(ctx context.Context) {
+	if s.cfg.PollInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			for s.processBatch(ctx) {
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// processBatch 领取一批索引并各处理一批记录，返回是否可能还有待处理的索引
+func (s *SemanticSearchService) processBatch(ctx context.Context) bool {
+	const claimLimit = 10
+	lease := s.cfg.LeaseDuration
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	now := s.now()
+	indexes, err := s.repo.ClaimDue(ctx, now, now.Add(lease), claimLimit)
+	if err != nil {
+		semanticSearchLog.Warn(ctx, "领取语义索引失败", logger.ErrorField(err))
+		return false
+	}
+
+	more := false
+	for _, index := range indexes {
+		if ctx.Err() != nil {
+			return false
+		}
+		if s.process(ctx, index) {
+			more = true
+		}
+	}
+	return more && ctx.Err() == nil
+}
+
+// process 处理单个索引：回填中的索引处理下一批已有记录，已就绪的索引消费下一批变更
+// 返回该索引是否还有待处理的数据
+func (s *SemanticSearchService) process(ctx context.Context, index *semanticsearch.Index) bool {
+	var (
+		more bool
+		err  error
+	)
+	fields, err := s.indexedFields(ctx, index)
+	if err == nil {
+		embedder := s.embedders[index.Provider]
+		switch {
+		case embedder == nil:
+			err = fmt.Errorf("未配置向量化服务商 %s", index.Provider)
+		case index.Status == semanticsearch.StatusBackfilling:
+			more, err = s.backfill(ctx, index, embedder, fields)
+		default:
+			more, err = s.catchUp(ctx, index, embedder, fields)
+		}
+	}
+
+	switch {
+	case errors.Is(err, semanticsearch.ErrIndexChanged):
+		more = true
+	case err != nil:
+		more = false
+		index.LastError = err.Error()
+		semanticSearchLog.Warn(ctx, "更新语义索引失败",
+			logger.String("index_id", index.ID),
+			logger.String("table_id", index.TableID),
+			logger.ErrorField(err))
+	default:
+		index.LastError = ""
+	}
+	if err := s.repo.SaveProgress(ctx, index); err != nil {
+		semanticSearchLog.Warn(ctx, "保存语义索引进度失败",
+			logger.String("index_id", index.ID),
+			logger.ErrorField(err))
+		return false
+	}
+	return more
+}
+
+// indexedFields 索引中仍然存在的字段（已删除的字段不再参与向量化）
+func (s *SemanticSearchService) indexedFields(ctx context.Context, index *semanticsearch.Index) ([]semanticsearch.FieldRef, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, index.TableID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	refs := make([]semanticsearch.FieldRef, 0, len(index.FieldIDs))
+	for _, id := range index.FieldIDs {
+		if field := byID[id]; field != nil && !field.IsEncrypted() {
+			refs = append(refs, semanticsearch.FieldRef{ID: id, Name: field.Name().String()})
+		}
+	}
+	return refs, nil
+}
+
+// backfill 按自增序号为下一批已有记录生成向量，最后一批完成后转为增量更新
+func (s *SemanticSearchService) backfill(ctx context.Context, index *semanticsearch.Index, embedder semanticsearch.Embedder, fields []semanticsearch.FieldRef) (bool, error) {
+	limit := s.batchSize()
+	rows, err := s.backfillPage(ctx, index.BaseID, index.TableID, index.BackfillAfter, limit)
+	if err != nil {
+		return false, err
+	}
+	if len(rows) > 0 {
+		ids := make([]recordVO.RecordID, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, recordVO.NewRecordID(row.ID))
+		}
+		records, err := s.recordRepo.FindByIDs(ctx, index.TableID, ids)
+		if err != nil {
+			return false, err
+		}
+		docs := make([]*semanticsearch.Document, 0, len(records))
+		for _, record := range records {
+			docs = append(docs, semanticsearch.BuildDocument(record.ID().String(), fields, record.Data().ToMap()))
+		}
+		if err := s.index(ctx, index, embedder, docs); err != nil {
+			return false, err
+		}
+		index.BackfillAfter = rows[len(rows)-1].AutoNumber
+	}
+	if len(rows) < limit {
+		index.Status = semanticsearch.StatusReady
+		return false, nil
+	}
+	return true, nil
+}
+
+// catchUp 消费索引游标之后的记录变更
+// 同一记录的多次变更只按当前状态处理一次；游标已过期时重新全量回填（文本未变化的记录不重复向量化）
+func (s *SemanticSearchService) catchUp(ctx context.Context, index *semanticsearch.Index, embedder semanticsearch.Embedder, fields []semanticsearch.FieldRef) (bool, error) {
+	limit := s.batchSize()
+	head, err := s.changeFeed.Head(ctx)
+	if err != nil {
+		return false, err
+	}
+	changes, err := s.changeFeed.ListAfter(ctx, index.BaseID, changefeed.ListFilter{
+		After:      index.Cursor,
+		TableID:    index.TableID,
+		EntityType: changefeed.EntityRecord,
+		Limit:      limit,
+	})
+	if errors.Is(err, changefeed.ErrCursorExpired) {
+		semanticSearchLog.Warn(ctx, "语义索引游标已过期，重新全量回填",
+			logger.String("index_id", index.ID),
+			logger.String("table_id", index.TableID))
+		index.Status = semanticsearch.StatusBackfilling
+		index.BackfillAfter = 0
+		index.Cursor = head
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(changes) == 0 {
+		// 其他表的变更不会出现在本表的列表中，直接推进到读取前的位置
+		if head > index.Cursor {
+			index.Cursor = head
+		}
+		return false, nil
+	}
+
+	seen := make(map[string]bool, len(changes))
+	recordIDs := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if !seen[change.EntityID] {
+			seen[change.EntityID] = true
+			recordIDs = append(recordIDs, change.EntityID)
+		}
+	}
+	ids := make([]recordVO.RecordID, 0, len(recordIDs))
+	for _, id := range recordIDs {
+		ids = append(ids, recordVO.NewRecordID(id))
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, index.TableID, ids)
+	if err != nil {
+		return false, err
+	}
+
+	docs := make([]*semanticsearch.Document, 0, len(recordIDs))
+	found := make(map[string]bool, len(records))
+	for _, record := range records {
+		found[record.ID().String()] = true
+		docs = append(docs, semanticsearch.BuildDocument(record.ID().String(), fields, record.Data().ToMap()))
+	}
+	var deleted []string
+	for _, id := range recordIDs {
+		if !found[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	if err := s.repo.DeleteVectors(ctx, index.TableID, deleted); err != nil {
+		return false, err
+	}
+	if err := s.index(ctx, index, embedder, docs); err != nil {
+		return false, err
+	}
+	index.Cursor = changes[len(changes)-1].Seq
+	return len(changes) == limit, nil
+}
+
+// index 为文本有变化的文档生成向量，文本为空的记录删除向量
+func (s *SemanticSearchService) index(ctx context.Context, index *semanticsearch.Index, embedder semanticsearch.Embedder, docs []*semanticsearch.Document) error {
+	recordIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		recordIDs = append(recordIDs, doc.RecordID)
+	}
+	hashes, err := s.repo.ContentHashes(ctx, index.TableID, recordIDs)
+	if err != nil {
+		return err
+	}
+
+	var (
+		pending []*semanticsearch.Document
+		empty   []string
+	)
+	for _, doc := range docs {
+		switch {
+		case doc.Text == "":
+			if _, ok := hashes[doc.RecordID]; ok {
+				empty = append(empty, doc.RecordID)
+			}
+		case hashes[doc.RecordID] != doc.Hash:
+			pending = append(pending, doc)
+		}
+	}
+	if err := s.repo.DeleteVectors(ctx, index.TableID, empty); err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	inputs := make([]string, 0, len(pending))
+	for _, doc := range pending {
+		inputs = append(inputs, doc.Text)
+	}
+	result, err := s.embed(ctx, embedder, index.Model, inputs)
+	if err != nil {
+		return err
+	}
+	if err := s.checkDimensions(ctx, index, result.Vectors); err != nil {
+		return err
+	}
+
+	vectors := make([]*semanticsearch.Vector, 0, len(pending))
+	for i, doc := range pending {
+		vectors = append(vectors, &semanticsearch.Vector{
+			TableID:     index.TableID,
+			RecordID:    doc.RecordID,
+			Embedding:   result.Vectors[i],
+			ContentHash: doc.Hash,
+			Model:       result.Model,
+		})
+	}
+	return s.repo.UpsertVectors(ctx, index, vectors)
+}
+
+// checkDimensions 首次生成向量时确定索引维度并创建近似最近邻索引，之后的向量必须维度一致
+func (s *SemanticSearchService) checkDimensions(ctx context.Context, index *semanticsearch.Index, vectors [][]float32) error {
+	for _, vector := range vectors {
+		if len(vector) == 0 {
+			return errors.New("向量化服务返回了空向量")
+		}
+		if index.Dimensions == 0 {
+			index.Dimensions = len(vector)
+			if err := s.repo.EnsureVectorIndex(ctx, index); err != nil {
+				return err
+			}
+		}
+		if len(vector) != index.Dimensions {
+			return fmt.Errorf("向量维度 %d 与索引维度 %d 不一致，请重建索引", len(vector), index.Dimensions)
+		}
+	}
+	return nil
+}
+
+// embed 调用向量化服务（带请求超时）
+func (s *SemanticSearchService) embed(ctx context.Context, embedder semanticsearch.Embedder, model string, inputs []string) (*semanticsearch.EmbedResult, error) {
+	if s.cfg.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.RequestTimeout)
+		defer cancel()
+	}
+	result, err := embedder.Embed(ctx, model, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Vectors) != len(inputs) {
+		return nil, fmt.Errorf("向量化服务返回 %d 个向量，期望 %d 个", len(result.Vectors), len(inputs))
+	}
+	return result, nil
+}
+
+func (s *SemanticSearchService) batchSize() int {
+	if s.cfg.BatchSize > 0 {
+		return s.cfg.BatchSize
+	}
+	return 64
+}
+
+// Search 在表中搜索记录
+// 混合搜索时语义检索不可用（未建立索引、尚未生成向量、索引字段对当前角色脱敏或服务商调用失败）则只做关键词匹配
+func (s *SemanticSearchService) Search(ctx context.Context, userID, tableID string, req SemanticSearchRequest) (*SemanticSearchResponse, error) {
+	if !s.permissionService.CanAccessRecord(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该表格的记录")
+	}
+	return s.search(ctx, tableID, req)
+}
+
+// search 执行搜索（调用方负责权限校验）
+func (s *SemanticSearchService) search(ctx context.Context, tableID string, req SemanticSearchRequest) (*SemanticSearchResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("搜索内容不能为空")
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = SemanticSearchModeHybrid
+	}
+	if mode != SemanticSearchModeHybrid && mode != SemanticSearchModeSemantic && mode != SemanticSearchModeKeyword {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的搜索方式: " + mode)
+	}
+	limit := req.Limit
+	maxResults := s.cfg.MaxResults
+	if maxResults <= 0 {
+		maxResults = 50
+	}
+	if limit <= 0 {
+		limit = semanticSearchDefaultLimit
+	}
+	if limit > maxResults {
+		limit = maxResults
+	}
+	candidates := s.cfg.CandidateLimit
+	if candidates < limit {
+		candidates = limit
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	index, err := s.repo.GetIndex(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取语义索引失败")
+	}
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	}
+
+	resp := &SemanticSearchResponse{Mode: mode, Hits: []SemanticSearchHit{}}
+	if index != nil {
+		resp.IndexStatus = string(index.Status)
+	}
+
+	var semantic []semanticsearch.Match
+	if mode != SemanticSearchModeKeyword {
+		semantic, err = s.semanticMatches(ctx, index, protected, query, candidates)
+		if err != nil {
+			if mode == SemanticSearchModeSemantic {
+				return nil, err
+			}
+			resp.Mode = SemanticSearchModeKeyword
+		}
+	}
+
+	var keyword []string
+	if resp.Mode != SemanticSearchModeSemantic {
+		keywordFields := searchableFields(index, fields, protected)
+		if len(keywordFields) > 0 {
+			keyword, err = s.keywordSearch(ctx, table.BaseID(), tableID, keywordFields, query, candidates)
+			if err != nil {
+				return nil, pkgerrors.Database(err, "搜索记录失败")
+			}
+		}
+	}
+
+	var matches []semanticsearch.Match
+	switch resp.Mode {
+	case SemanticSearchModeSemantic:
+		matches = semantic
+	case SemanticSearchModeKeyword:
+		matches = semanticsearch.Fuse(candidates, keyword)
+	default:
+		ranked := make([]string, 0, len(semantic))
+		for _, match := range semantic {
+			ranked = append(ranked, match.RecordID)
+		}
+		matches = semanticsearch.Fuse(candidates, ranked, keyword)
+	}
+
+	// 逐条读取记录（按当前角色脱敏）；向量尚未清理的已删除记录跳过
+	for _, match := range matches {
+		if len(resp.Hits) >= limit {
+			break
+		}
+		record, err := s.records.GetRecord(ctx, tableID, match.RecordID)
+		if err != nil || record == nil {
+			continue
+		}
+		resp.Hits = append(resp.Hits, SemanticSearchHit{RecordID: match.RecordID, Score: match.Score, Record: record})
+	}
+	return resp, nil
+}
+
+// semanticMatches 向量化查询文本并检索最相近的记录
+func (s *SemanticSearchService) semanticMatches(ctx context.Context, index *semanticsearch.Index, protected map[string]bool, query string, limit int) ([]semanticsearch.Match, error) {
+	if index == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("表格未建立语义索引")
+	}
+	if index.Dimensions == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("语义索引尚未生成向量")
+	}
+	for _, id := range index.FieldIDs {
+		if protected[id] {
+			return nil, pkgerrors.ErrForbidden.WithDetails("语义索引包含当前角色不可见的字段")
+		}
+	}
+	embedder := s.embedders[index.Provider]
+	if embedder == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("未配置向量化服务商: %s", index.Provider))
+	}
+	result, err := s.embed(ctx, embedder, index.Model, []string{query})
+	if err != nil {
+		semanticSearchLog.Warn(ctx, "查询文本向量化失败",
+			logger.String("table_id", index.TableID),
+			logger.String("provider", index.Provider),
+			logger.ErrorField(err))
+		return nil, pkgerrors.ErrInternalServer.WithDetails("查询文本向量化失败: " + err.Error())
+	}
+	if len(result.Vectors[0]) != index.Dimensions {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("查询向量维度与索引不一致，请重建索引")
+	}
+	matches, err := s.repo.Nearest(ctx, index, result.Vectors[0], limit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "语义检索失败")
+	}
+	return matches, nil
+}
+
+// searchableFields 关键词匹配的字段：有索引时为索引字段，否则为全部文本类字段；
+// 排除加密字段与当前角色下脱敏的字段
+func searchableFields(index *semanticsearch.Index, fields []*fieldEntity.Field, protected map[string]bool) []*fieldEntity.Field {
+	var selected map[string]bool
+	if index != nil {
+		selected = make(map[string]bool, len(index.FieldIDs))
+		for _, id := range index.FieldIDs {
+			selected[id] = true
+		}
+	}
+	result := make([]*fieldEntity.Field, 0, len(fields))
+	for _, field := range fields {
+		id := field.ID().String()
+		if selected != nil && !selected[id] {
+			continue
+		}
+		if !semanticTextFieldTypes[field.Type().String()] || field.IsEncrypted() || protected[id] {
+			continue
+		}
+		result = append(result, field)
+	}
+	return result
+}
+
+// physicalBackfillPage 按自增序号读取下一页记录ID
+func (s *SemanticSearchService) physicalBackfillPage(ctx context.Context, baseID, tableID string, after int64, limit int) ([]semanticBackfillRow, error) {
+	var rows []semanticBackfillRow
+	err := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, tableID)).
+		Select("__id, __auto_number").
+		Where("__auto_number > ?", after).
+		Order("__auto_number ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// physicalKeywordSearch 在物理表上按子串匹配任一字段，最近创建的记录在前
+func (s *SemanticSearchService) physicalKeywordSearch(ctx context.Context, baseID, tableID string, fields []*fieldEntity.Field, query string, limit int) ([]string, error) {
+	parts := make([]string, 0, len(fields))
+	vars := make([]interface{}, 0, len(fields)*2)
+	pattern := likePattern(query)
+	for _, field := range fields {
+		parts = append(parts, "CAST(? AS TEXT) ILIKE ?")
+		vars = append(vars, clause.Column{Name: field.DBFieldName().String()}, pattern)
+	}
+	var ids []string
+	err := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, tableID)).
+		Where(clause.Expr{SQL: strings.Join(parts, " OR "), Vars: vars}).
+		Order("__created_time DESC").
+		Limit(limit).
+		Pluck("__id", &ids).Error
+	return ids, err
+}
+
+// equalStrings 两个字符串切片是否按顺序相同
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
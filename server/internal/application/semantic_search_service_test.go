@@ -0,0 +1,400 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/semanticsearch"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+)
+
+// memorySemanticRepo 内存实现的语义索引仓储（单表）
+type memorySemanticRepo struct {
+	index   *semanticsearch.Index
+	vectors map[string]*semanticsearch.Vector
+}
+
+func (r *memorySemanticRepo) GetIndex(context.Context, string) (*semanticsearch.Index, error) {
+	if r.index == nil {
+		return nil, nil
+	}
+	copied := *r.index
+	return &copied, nil
+}
+
+func (r *memorySemanticRepo) SaveIndex(_ context.Context, index *semanticsearch.Index) error {
+	copied := *index
+	r.index = &copied
+	return nil
+}
+
+func (r *memorySemanticRepo) DeleteIndex(context.Context, *semanticsearch.Index) error {
+	r.index = nil
+	r.vectors = make(map[string]*semanticsearch.Vector)
+	return nil
+}
+
+func (r *memorySemanticRepo) ResetVectors(context.Context, *semanticsearch.Index) error {
+	r.vectors = make(map[string]*semanticsearch.Vector)
+	return nil
+}
+
+func (r *memorySemanticRepo) ClaimDue(context.Context, time.Time, time.Time, int) ([]*semanticsearch.Index, error) {
+	index, _ := r.GetIndex(context.Background(), "")
+	if index == nil {
+		return nil, nil
+	}
+	return []*semanticsearch.Index{index}, nil
+}
+
+func (r *memorySemanticRepo) SaveProgress(_ context.Context, index *semanticsearch.Index) error {
+	if r.index != nil && r.index.UpdatedAt.Equal(index.UpdatedAt) {
+		copied := *index
+		r.index = &copied
+	}
+	return nil
+}
+
+func (r *memorySemanticRepo) EnsureVectorIndex(context.Context, *semanticsearch.Index) error {
+	return nil
+}
+
+func (r *memorySemanticRepo) ContentHashes(_ context.Context, _ string, recordIDs []string) (map[string]string, error) {
+	hashes := make(map[string]string)
+	for _, id := range recordIDs {
+		if v := r.vectors[id]; v != nil {
+			hashes[id] = v.ContentHash
+		}
+	}
+	return hashes, nil
+}
+
+func (r *memorySemanticRepo) UpsertVectors(_ context.Context, index *semanticsearch.Index, vectors []*semanticsearch.Vector) error {
+	if r.index == nil || !r.index.UpdatedAt.Equal(index.UpdatedAt) {
+		return semanticsearch.ErrIndexChanged
+	}
+	for _, v := range vectors {
+		r.vectors[v.RecordID] = v
+	}
+	return nil
+}
+
+func (r *memorySemanticRepo) DeleteVectors(_ context.Context, _ string, recordIDs []string) error {
+	for _, id := range recordIDs {
+		delete(r.vectors, id)
+	}
+	return nil
+}
+
+func (r *memorySemanticRepo) Nearest(_ context.Context, _ *semanticsearch.Index, query []float32, limit int) ([]semanticsearch.Match, error) {
+	matches := make([]semanticsearch.Match, 0, len(r.vectors))
+	for id, v := range r.vectors {
+		matches = append(matches, semanticsearch.Match{RecordID: id, Score: cosine(query, v.Embedding)})
+	}
+	sort.Slice(matches, func(a, b int) bool { return matches[a].Score > matches[b].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// stubChangeFeedRepo 返回预置的记录变更
+type stubChangeFeedRepo struct {
+	changefeed.Repository
+	changes []*changefeed.Change
+	expired bool
+}
+
+func (r *stubChangeFeedRepo) Head(context.Context) (int64, error) {
+	if len(r.changes) == 0 {
+		return 10, nil
+	}
+	return r.changes[len(r.changes)-1].Seq, nil
+}
+
+func (r *stubChangeFeedRepo) ListAfter(_ context.Context, _ string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if r.expired {
+		return nil, changefeed.ErrCursorExpired
+	}
+	var list []*changefeed.Change
+	for _, change := range r.changes {
+		if change.Seq > filter.After && len(list) < filter.Limit {
+			list = append(list, change)
+		}
+	}
+	return list, nil
+}
+
+// stubSemanticFieldRepo 返回表的字段
+type stubSemanticFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields []*fieldEntity.Field
+}
+
+func (r *stubSemanticFieldRepo) FindByTableID(context.Context, string) ([]*fieldEntity.Field, error) {
+	return r.fields, nil
+}
+
+// stubSemanticRecordRepo 按ID批量返回记录
+type stubSemanticRecordRepo struct {
+	recordRepo.RecordRepository
+	records map[string]*recordEntity.Record
+}
+
+func (r *stubSemanticRecordRepo) FindByIDs(_ context.Context, _ string, ids []recordVO.RecordID, _ ...string) ([]*recordEntity.Record, error) {
+	var list []*recordEntity.Record
+	for _, id := range ids {
+		if record := r.records[id.String()]; record != nil {
+			list = append(list, record)
+		}
+	}
+	return list, nil
+}
+
+func (r *stubSemanticRecordRepo) GetRecord(_ context.Context, _, recordID string) (*dto.RecordResponse, error) {
+	if r.records[recordID] == nil {
+		return nil, errors.New("记录不存在")
+	}
+	return &dto.RecordResponse{ID: recordID}, nil
+}
+
+// stubSemanticTableRepo 返回固定的表
+type stubSemanticTableRepo struct {
+	tableRepo.TableRepository
+	table *tableEntity.Table
+}
+
+func (r *stubSemanticTableRepo) GetByID(context.Context, string) (*tableEntity.Table, error) {
+	return r.table, nil
+}
+
+// keywordEmbedder 按文本中“猫”“狗”出现的次数生成三维向量，并记录收到的输入
+type keywordEmbedder struct {
+	inputs []string
+}
+
+func (e *keywordEmbedder) Name() string { return "stub" }
+
+func (e *keywordEmbedder) Embed(_ context.Context, _ string, inputs []string) (*semanticsearch.EmbedResult, error) {
+	e.inputs = append(e.inputs, inputs...)
+	vectors := make([][]float32, 0, len(inputs))
+	for _, text := range inputs {
+		vectors = append(vectors, []float32{float32(strings.Count(text, "猫")), float32(strings.Count(text, "狗")), 0.1})
+	}
+	return &semanticsearch.EmbedResult{Vectors: vectors, Model: "stub-embed"}, nil
+}
+
+type semanticSearchFixture struct {
+	service  *SemanticSearchService
+	repo     *memorySemanticRepo
+	feed     *stubChangeFeedRepo
+	records  *stubSemanticRecordRepo
+	embedder *keywordEmbedder
+	title    *fieldEntity.Field
+	order    []string // 按自增序号排列的记录ID
+}
+
+func newSemanticSearchFixture(t *testing.T, titles map[string]string, order []string) *semanticSearchFixture {
+	t.Helper()
+	name, _ := fieldVO.NewFieldName("标题")
+	ft, _ := fieldVO.NewFieldType(fieldVO.TypeText)
+	title, err := fieldEntity.NewField("tbl1", name, ft, "usr1")
+	if err != nil {
+		t.Fatalf("创建字段失败: %v", err)
+	}
+	tableName, _ := tableVO.NewTableName("文章")
+	table, err := tableEntity.NewTable("bse1", tableName, "usr1")
+	if err != nil {
+		t.Fatalf("创建表失败: %v", err)
+	}
+
+	fx := &semanticSearchFixture{
+		repo:     &memorySemanticRepo{vectors: make(map[string]*semanticsearch.Vector)},
+		feed:     &stubChangeFeedRepo{},
+		records:  &stubSemanticRecordRepo{records: make(map[string]*recordEntity.Record)},
+		embedder: &keywordEmbedder{},
+		title:    title,
+		order:    order,
+	}
+	for id, text := range titles {
+		fx.setTitle(id, text)
+	}
+	fx.service = NewSemanticSearchService(
+		fx.repo, fx.feed,
+		&stubSemanticTableRepo{table: table},
+		&stubSemanticFieldRepo{fields: []*fieldEntity.Field{title}},
+		fx.records, fx.records,
+		nil, nil, nil,
+		map[string]semanticsearch.Embedder{"stub": fx.embedder},
+		"stub",
+		nil, nil,
+		config.SemanticSearchConfig{BatchSize: 2, CandidateLimit: 10, MaxResults: 10},
+	)
+	fx.service.backfillPage = func(_ context.Context, _, _ string, after int64, limit int) ([]semanticBackfillRow, error) {
+		var rows []semanticBackfillRow
+		for i, id := range fx.order {
+			if n := int64(i + 1); n > after && len(rows) < limit {
+				rows = append(rows, semanticBackfillRow{ID: id, AutoNumber: n})
+			}
+		}
+		return rows, nil
+	}
+	fx.repo.index = semanticsearch.NewIndex("spc1", "bse1", "tbl1", []string{title.ID().String()}, "stub", "", 10, "usr1")
+	return fx
+}
+
+func (fx *semanticSearchFixture) setTitle(recordID, text string) {
+	data, _ := recordVO.NewRecordData(map[string]interface{}{fx.title.ID().String(): text})
+	fx.records.records[recordID] = recordEntity.ReconstructRecord(recordVO.NewRecordID(recordID), "tbl1", data, recordVO.InitialVersion(), "usr1", "usr1", time.Now(), time.Now(), nil)
+}
+
+func (fx *semanticSearchFixture) drain(t *testing.T) {
+	t.Helper()
+	for i := 0; fx.service.processBatch(context.Background()); i++ {
+		if i > 10 {
+			t.Fatal("索引处理未结束")
+		}
+	}
+}
+
+func TestSemanticIndexBackfillAndChanges(t *testing.T) {
+	fx := newSemanticSearchFixture(t, map[string]string{
+		"rec1": "如何照顾猫",
+		"rec2": "训练小狗",
+		"rec3": "",
+	}, []string{"rec1", "rec2", "rec3"})
+
+	fx.drain(t)
+	index := fx.repo.index
+	if index.Status != semanticsearch.StatusReady || index.Dimensions != 3 || index.BackfillAfter != 3 || index.LastError != "" {
+		t.Fatalf("回填后索引状态不正确: %+v", index)
+	}
+	if len(fx.repo.vectors) != 2 || fx.repo.vectors["rec3"] != nil {
+		t.Fatalf("空文本的记录不应生成向量: %v", fx.repo.vectors)
+	}
+	if len(fx.embedder.inputs) != 2 || fx.embedder.inputs[0] != "标题: 如何照顾猫" {
+		t.Fatalf("向量化输入不正确: %v", fx.embedder.inputs)
+	}
+
+	// rec1 文本未变化不重复向量化；rec2 更新后重新向量化；rec3 删除
+	fx.setTitle("rec2", "训练小猫")
+	delete(fx.records.records, "rec3")
+	fx.repo.vectors["rec3"] = &semanticsearch.Vector{RecordID: "rec3"}
+	fx.feed.changes = []*changefeed.Change{
+		{Seq: 11, Action: changefeed.ActionUpdate, EntityID: "rec1"},
+		{Seq: 12, Action: changefeed.ActionUpdate, EntityID: "rec2"},
+		{Seq: 13, Action: changefeed.ActionDelete, EntityID: "rec3"},
+	}
+	fx.embedder.inputs = nil
+	fx.drain(t)
+
+	if fx.repo.index.Cursor != 13 {
+		t.Fatalf("游标应推进到 13，实际 %d", fx.repo.index.Cursor)
+	}
+	if len(fx.embedder.inputs) != 1 || fx.embedder.inputs[0] != "标题: 训练小猫" {
+		t.Fatalf("只应重新向量化变化的记录: %v", fx.embedder.inputs)
+	}
+	if fx.repo.vectors["rec3"] != nil || fx.repo.vectors["rec2"].Embedding[0] != 1 {
+		t.Fatalf("变更未正确应用: %v", fx.repo.vectors)
+	}
+
+	// 游标过期时重新全量回填，文本未变化的记录不重复向量化
+	fx.feed.expired = true
+	fx.service.processBatch(context.Background())
+	if fx.repo.index.Status != semanticsearch.StatusBackfilling || fx.repo.index.BackfillAfter != 0 {
+		t.Fatalf("游标过期后应重新回填: %+v", fx.repo.index)
+	}
+	fx.feed.expired = false
+	fx.embedder.inputs = nil
+	fx.drain(t)
+	if fx.repo.index.Status != semanticsearch.StatusReady || len(fx.embedder.inputs) != 0 {
+		t.Fatalf("重新回填不应重复向量化: %+v %v", fx.repo.index, fx.embedder.inputs)
+	}
+}
+
+func TestSemanticSearchHybridRanking(t *testing.T) {
+	fx := newSemanticSearchFixture(t, map[string]string{
+		"rec1": "如何照顾猫",
+		"rec2": "训练小狗",
+		"rec3": "猫粮和猫砂推荐",
+	}, []string{"rec1", "rec2", "rec3"})
+	fx.drain(t)
+
+	var keywordFields []string
+	fx.service.keywordSearch = func(_ context.Context, _, _ string, fields []*fieldEntity.Field, query string, _ int) ([]string, error) {
+		keywordFields = nil
+		for _, field := range fields {
+			keywordFields = append(keywordFields, field.ID().String())
+		}
+		return []string{"rec3", "rec2"}, nil
+	}
+	ctx := context.Background()
+
+	resp, err := fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "猫"})
+	if err != nil {
+		t.Fatalf("搜索失败: %v", err)
+	}
+	if resp.Mode != SemanticSearchModeHybrid || resp.IndexStatus != string(semanticsearch.StatusReady) {
+		t.Fatalf("搜索方式不正确: %+v", resp)
+	}
+	if len(keywordFields) != 1 || keywordFields[0] != fx.title.ID().String() {
+		t.Fatalf("关键词匹配字段不正确: %v", keywordFields)
+	}
+	// 语义排序 rec1 > rec3 > rec2，关键词排序 rec3 > rec2：两路都靠前的 rec3 排第一
+	if len(resp.Hits) != 3 || resp.Hits[0].RecordID != "rec3" || resp.Hits[1].RecordID != "rec2" || resp.Hits[2].RecordID != "rec1" {
+		t.Fatalf("融合排序不正确: %+v", resp.Hits)
+	}
+
+	resp, err = fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "猫", Mode: SemanticSearchModeSemantic, Limit: 1})
+	if err != nil || len(resp.Hits) != 1 || resp.Hits[0].RecordID != "rec1" || resp.Hits[0].Score < 0.99 {
+		t.Fatalf("语义检索结果不正确: %+v, %v", resp, err)
+	}
+
+	// 删除的记录即使仍有向量也不返回
+	delete(fx.records.records, "rec1")
+	resp, _ = fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "猫", Mode: SemanticSearchModeSemantic})
+	for _, hit := range resp.Hits {
+		if hit.RecordID == "rec1" {
+			t.Fatalf("不应返回已删除的记录: %+v", resp.Hits)
+		}
+	}
+
+	// 未生成向量时混合搜索退化为关键词匹配，指定语义检索则报错
+	fx.repo.index.Dimensions = 0
+	resp, err = fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "猫"})
+	if err != nil || resp.Mode != SemanticSearchModeKeyword || len(resp.Hits) != 2 {
+		t.Fatalf("应退化为关键词匹配: %+v, %v", resp, err)
+	}
+	if _, err := fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "猫", Mode: SemanticSearchModeSemantic}); err == nil {
+		t.Fatal("索引未就绪时语义检索应报错")
+	}
+	if _, err := fx.service.search(ctx, "tbl1", SemanticSearchRequest{Query: "  "}); err == nil {
+		t.Fatal("空查询应报错")
+	}
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/servicetoken"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var serviceTokenLog = logger.Named("service_token")
+
+// serviceTokenTouchInterval 最近使用时间的最小更新间隔（避免每个请求都写库）
+const serviceTokenTouchInterval = time.Minute
+
+// CreateServiceTokenRequest 创建服务令牌请求
+type CreateServiceTokenRequest struct {
+	Name          string `json:"name" binding:"required"`
+	ExpiresInDays int    `json:"expiresInDays"` // 0 表示不过期
+}
+
+// CreateServiceTokenResponse 创建服务令牌响应（明文只返回这一次）
+type CreateServiceTokenResponse struct {
+	*servicetoken.Token
+	Secret string `json:"secret"`
+}
+
+// ServiceTokenService 服务令牌服务 ✨
+// 服务令牌以创建者身份访问 API，不受会话时长与并发会话限制，
+// 但仍受工作空间 IP 白名单约束；吊销后立即失效
+type ServiceTokenService struct {
+	repo     servicetoken.Repository
+	userRepo repository.UserRepository
+	now      func() time.Time
+}
+
+// NewServiceTokenService 创建服务令牌服务
+func NewServiceTokenService(repo servicetoken.Repository, userRepo repository.UserRepository) *ServiceTokenService {
+	return &ServiceTokenService{
+		repo:     repo,
+		userRepo: userRepo,
+		now:      time.Now,
+	}
+}
+
+// CreateToken 为当前用户创建服务令牌
+func (s *ServiceTokenService) CreateToken(ctx context.Context, userID string, req CreateServiceTokenRequest) (*CreateServiceTokenResponse, error) {
+	if req.ExpiresInDays < 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("expiresInDays 不能为负数")
+	}
+	token, secret, err := servicetoken.NewToken(userID, req.Name, time.Duration(req.ExpiresInDays)*24*time.Hour)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, pkgerrors.Database(err, "创建服务令牌失败")
+	}
+
+	serviceTokenLog.Info(ctx, "服务令牌已创建",
+		logger.String("user_id", userID),
+		logger.String("token_id", token.ID),
+		logger.String("name", token.Name))
+	return &CreateServiceTokenResponse{Token: token, Secret: secret}, nil
+}
+
+// ListTokens 列出当前用户的服务令牌
+func (s *ServiceTokenService) ListTokens(ctx context.Context, userID string) ([]*servicetoken.Token, error) {
+	tokens, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return tokens, nil
+}
+
+// RevokeToken 吊销当前用户的服务令牌
+func (s *ServiceTokenService) RevokeToken(ctx context.Context, userID, tokenID string) error {
+	token, err := s.repo.GetByID(ctx, tokenID)
+	if err != nil {
+		return pkgerrors.Database(err, "")
+	}
+	if token == nil || token.UserID != userID {
+		return pkgerrors.ErrNotFound.WithDetails("服务令牌不存在")
+	}
+	if err := s.repo.Revoke(ctx, tokenID, s.now()); err != nil {
+		return pkgerrors.Database(err, "吊销服务令牌失败")
+	}
+
+	serviceTokenLog.Info(ctx, "服务令牌已吊销",
+		logger.String("user_id", userID),
+		logger.String("token_id", tokenID))
+	return nil
+}
+
+// Authenticate 校验服务令牌并返回创建者身份
+func (s *ServiceTokenService) Authenticate(ctx context.Context, plaintext string) (*dto.TokenClaims, error) {
+	token, err := s.repo.GetByHash(ctx, servicetoken.Hash(plaintext))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	now := s.now()
+	if token == nil || !token.Active(now) {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("服务令牌无效或已吊销")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, valueobject.NewUserID(token.UserID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找用户失败")
+	}
+	if user == nil || !user.IsActive() {
+		return nil, pkgerrors.ErrUnauthorized.WithDetails("服务令牌所属用户不可用")
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= serviceTokenTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			serviceTokenLog.Warn(ctx, "更新服务令牌使用时间失败",
+				logger.String("token_id", token.ID),
+				logger.ErrorField(err))
+		}
+	}
+
+	return &dto.TokenClaims{
+		UserID:         token.UserID,
+		Email:          user.Email().String(),
+		IsAdmin:        user.IsAdmin(),
+		ServiceTokenID: token.ID,
+	}, nil
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/servicetoken"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+)
+
+type memoryServiceTokenRepo struct {
+	tokens map[string]*servicetoken.Token
+}
+
+func newMemoryServiceTokenRepo() *memoryServiceTokenRepo {
+	return &memoryServiceTokenRepo{tokens: map[string]*servicetoken.Token{}}
+}
+
+func (r *memoryServiceTokenRepo) Create(_ context.Context, token *servicetoken.Token) error {
+	copied := *token
+	r.tokens[token.ID] = &copied
+	return nil
+}
+
+func (r *memoryServiceTokenRepo) GetByHash(_ context.Context, tokenHash string) (*servicetoken.Token, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryServiceTokenRepo) GetByID(_ context.Context, id string) (*servicetoken.Token, error) {
+	if token, ok := r.tokens[id]; ok {
+		copied := *token
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryServiceTokenRepo) ListByUser(_ context.Context, userID string) ([]*servicetoken.Token, error) {
+	var list []*servicetoken.Token
+	for _, token := range r.tokens {
+		if token.UserID == userID {
+			list = append(list, token)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryServiceTokenRepo) Revoke(_ context.Context, id string, at time.Time) error {
+	if token, ok := r.tokens[id]; ok {
+		token.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *memoryServiceTokenRepo) TouchLastUsed(_ context.Context, id string, at time.Time) error {
+	if token, ok := r.tokens[id]; ok {
+		token.LastUsedAt = &at
+	}
+	return nil
+}
+
+// stubUserRepo 只实现 FindByID，其余方法未用到
+type stubUserRepo struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *stubUserRepo) FindByID(_ context.Context, id valueobject.UserID) (*entity.User, error) {
+	return r.users[id.String()], nil
+}
+
+func newStubUser(t *testing.T, id string, status valueobject.UserStatus) *entity.User {
+	t.Helper()
+	email, err := valueobject.NewEmail(id + "@example.com")
+	if err != nil {
+		t.Fatalf("NewEmail: %v", err)
+	}
+	hash, _ := valueobject.NewHashedPassword("hash")
+	now := time.Now()
+	return entity.ReconstructUser(valueobject.NewUserID(id), id, email, hash, nil, nil, status,
+		false, true, false, "", now, now, nil, nil, nil, 1)
+}
+
+func TestServiceTokenLifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryServiceTokenRepo()
+	users := &stubUserRepo{users: map[string]*entity.User{
+		"usr_1": newStubUser(t, "usr_1", valueobject.ActiveStatus()),
+	}}
+	svc := NewServiceTokenService(repo, users)
+
+	created, err := svc.CreateToken(ctx, "usr_1", CreateServiceTokenRequest{Name: "ci", ExpiresInDays: 30})
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+
+	claims, err := svc.Authenticate(ctx, created.Secret)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if claims.UserID != "usr_1" || !claims.IsAdmin || claims.ServiceTokenID != created.ID {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	if repo.tokens[created.ID].LastUsedAt == nil {
+		t.Errorf("last used time should be recorded")
+	}
+
+	if _, err := svc.Authenticate(ctx, servicetoken.Prefix+"unknown"); err == nil {
+		t.Errorf("unknown token should be rejected")
+	}
+
+	if err := svc.RevokeToken(ctx, "usr_2", created.ID); err == nil {
+		t.Errorf("other users must not revoke the token")
+	}
+	if err := svc.RevokeToken(ctx, "usr_1", created.ID); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Secret); err == nil {
+		t.Errorf("revoked token should be rejected")
+	}
+}
+
+func TestServiceTokenRejectsInactiveOwner(t *testing.T) {
+	ctx := context.Background()
+	users := &stubUserRepo{users: map[string]*entity.User{
+		"usr_1": newStubUser(t, "usr_1", valueobject.DeactivatedStatus()),
+	}}
+	svc := NewServiceTokenService(newMemoryServiceTokenRepo(), users)
+
+	created, err := svc.CreateToken(ctx, "usr_1", CreateServiceTokenRequest{Name: "ci"})
+	if err != nil {
+		t.Fatalf("CreateToken: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, created.Secret); err == nil {
+		t.Errorf("token of a deactivated user should be rejected")
+	}
+}
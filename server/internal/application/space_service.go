@@ -47,7 +47,7 @@ func (s *SpaceService) CreateSpace(ctx context.Context, req dto.CreateSpaceReque
 	// 4. 创建聚合根并保存
 	spaceAgg := aggregate.NewSpaceAggregate(space)
 	if err := s.spaceRepo.Save(ctx, spaceAgg); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存空间失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存空间失败")
 	}
 
 	logger.Info("空间创建成功",
@@ -63,7 +63,7 @@ func (s *SpaceService) CreateSpace(ctx context.Context, req dto.CreateSpaceReque
 func (s *SpaceService) GetSpace(ctx context.Context, spaceID string) (*dto.SpaceResponse, error) {
 	space, err := s.spaceRepo.GetSpaceByID(ctx, spaceID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找空间失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找空间失败")
 	}
 	if space == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("空间不存在")
@@ -77,7 +77,7 @@ func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req dto.
 	// 1. 查找空间
 	space, err := s.spaceRepo.GetSpaceByID(ctx, spaceID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找空间失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找空间失败")
 	}
 	if space == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("空间不存在")
@@ -106,7 +106,7 @@ func (s *SpaceService) UpdateSpace(ctx context.Context, spaceID string, req dto.
 
 	// 5. 保存
 	if err := s.spaceRepo.Update(ctx, space); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存空间失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存空间失败")
 	}
 
 	logger.Info("空间更新成功",
@@ -121,7 +121,7 @@ func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID string) error {
 	// 1. 检查空间是否存在
 	space, err := s.spaceRepo.GetSpaceByID(ctx, spaceID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找空间失败: %v", err))
+		return pkgerrors.Database(err, "查找空间失败")
 	}
 	if space == nil {
 		return pkgerrors.ErrSpaceNotFound.WithDetails(fmt.Sprintf("空间不存在: %s", spaceID))
@@ -129,7 +129,7 @@ func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID string) error {
 
 	// 2. 删除空间
 	if err := s.spaceRepo.Delete(ctx, spaceID); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除空间失败: %v", err))
+		return pkgerrors.Database(err, "删除空间失败")
 	}
 
 	logger.Info("空间删除成功",
@@ -143,7 +143,7 @@ func (s *SpaceService) DeleteSpace(ctx context.Context, spaceID string) error {
 func (s *SpaceService) ListSpaces(ctx context.Context, userID string) ([]*dto.SpaceResponse, error) {
 	spaces, err := s.spaceRepo.ListByUserID(ctx, userID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询用户空间失败: %v", err))
+		return nil, pkgerrors.Database(err, "查询用户空间失败")
 	}
 
 	// 转换为 DTO
@@ -0,0 +1,373 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/statusflow"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// statusFlowCacheTTL 表的状态流缓存时间（本实例修改时立即失效）
+const statusFlowCacheTTL = time.Minute
+
+// SaveStatusFlowRequest 创建或更新状态流请求
+type SaveStatusFlowRequest struct {
+	InitialStatuses []string                `json:"initialStatuses"` // 新记录允许的初始状态，为空时不限制
+	Transitions     []statusflow.Transition `json:"transitions" binding:"required"`
+	Enabled         *bool                   `json:"enabled"` // 为空时启用
+}
+
+// StatusTransition 一次状态流转的详情（写入变更流与审计日志，REST Hook 的 record.transitioned 事件）
+type StatusTransition struct {
+	FieldID        string `json:"fieldId"`
+	FieldName      string `json:"fieldName"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+	TransitionName string `json:"transitionName,omitempty"`
+	Approved       bool   `json:"approved,omitempty"` // 流转需要审批角色
+	Role           string `json:"role,omitempty"`     // 执行者的协作者角色（需要审批时）
+}
+
+type statusFlowCacheEntry struct {
+	flows   []*statusflow.Flow
+	expires time.Time
+}
+
+// StatusFlowService 状态流（审批流）服务 ✨
+// 在单选字段上定义允许的状态流转，由 RecordService 在记录写入时检查：
+//   - 修改状态必须匹配一条流转规则，需要审批的流转只能由指定角色的协作者执行，否则写入失败；
+//   - 设置了初始状态时，新记录只能以这些状态开始；
+//   - 每次流转与记录写入在同一事务中写入审计日志和变更流（transition），
+//     REST Hook 可订阅 record.transitioned 事件接收流转推送。
+//
+// 同步表的同步写入不受限制；管理状态流需要表结构管理权限
+type StatusFlowService struct {
+	repo              statusflow.Repository
+	fieldRepo         repository.FieldRepository
+	permissionService *PermissionServiceV2
+	changeFeed        *ChangeFeedService
+	db                *gorm.DB
+	roleOf            func(ctx context.Context, userID, tableID string) (string, bool)
+	now               func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]statusFlowCacheEntry // 表ID → 启用的状态流
+}
+
+// NewStatusFlowService 创建状态流服务
+func NewStatusFlowService(
+	repo statusflow.Repository,
+	fieldRepo repository.FieldRepository,
+	permissionService *PermissionServiceV2,
+	changeFeed *ChangeFeedService,
+	db *gorm.DB,
+) *StatusFlowService {
+	s := &StatusFlowService{
+		repo:              repo,
+		fieldRepo:         fieldRepo,
+		permissionService: permissionService,
+		changeFeed:        changeFeed,
+		db:                db,
+		now:               time.Now,
+		cache:             make(map[string]statusFlowCacheEntry),
+	}
+	if permissionService != nil {
+		s.roleOf = permissionService.TableRole
+	}
+	return s
+}
+
+// ListFlows 列出表的状态流
+func (s *StatusFlowService) ListFlows(ctx context.Context, userID, tableID string) ([]*statusflow.Flow, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	flows, err := s.repo.ListByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询状态流失败")
+	}
+	return flows, nil
+}
+
+// SaveFlow 创建或更新单选字段的状态流（需要表结构管理权限）
+func (s *StatusFlowService) SaveFlow(ctx context.Context, userID, tableID, fieldID string, req SaveStatusFlowRequest) (*statusflow.Flow, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的状态流")
+	}
+	field, err := s.selectField(ctx, tableID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByField(ctx, fieldID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询状态流失败")
+	}
+	now := s.now()
+	flow := statusflow.NewFlow(tableID, fieldID, req.InitialStatuses, req.Transitions, userID, now)
+	if existing != nil {
+		flow.ID = existing.ID
+		flow.CreatedBy = existing.CreatedBy
+		flow.CreatedAt = existing.CreatedAt
+	}
+	if req.Enabled != nil {
+		flow.Enabled = *req.Enabled
+	}
+	if err := flow.Validate(selectChoiceNames(field)); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	if err := s.repo.Save(ctx, flow); err != nil {
+		return nil, pkgerrors.Database(err, "保存状态流失败")
+	}
+	s.invalidate(tableID)
+	return flow, nil
+}
+
+// DeleteFlow 删除字段的状态流（需要表结构管理权限）
+func (s *StatusFlowService) DeleteFlow(ctx context.Context, userID, tableID, fieldID string) error {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的状态流")
+	}
+	flow, err := s.repo.FindByField(ctx, fieldID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询状态流失败")
+	}
+	if flow == nil || flow.TableID != tableID {
+		return pkgerrors.ErrNotFound.WithDetails("状态流不存在")
+	}
+	if err := s.repo.Delete(ctx, flow.ID); err != nil {
+		return pkgerrors.Database(err, "删除状态流失败")
+	}
+	s.invalidate(tableID)
+	return nil
+}
+
+// ApplyTransitions 检查一次记录写入中的状态变化，通过后记录流转（在记录写入的事务中调用）
+// before 为写入前的字段值，新建记录时为 nil；changes 为写入的字段（键为字段ID或名称）
+func (s *StatusFlowService) ApplyTransitions(ctx context.Context, userID, tableID, recordID string, before, changes map[string]interface{}) error {
+	flows, err := s.tableFlows(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	if len(flows) == 0 || len(changes) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	var applied []*StatusTransition
+	for _, flow := range flows {
+		field := byID[flow.FieldID]
+		if field == nil {
+			continue
+		}
+		value, ok := fieldValue(changes, field)
+		if !ok {
+			continue
+		}
+		to := statusName(field, value)
+
+		if before == nil {
+			if !flow.AllowsInitial(to) {
+				return pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+					"message":          fmt.Sprintf("新记录的%s不能是 %q", field.Name().String(), to),
+					"field_id":         flow.FieldID,
+					"initial_statuses": flow.InitialStatuses,
+				})
+			}
+			continue
+		}
+
+		previous, _ := fieldValue(before, field)
+		from := statusName(field, previous)
+		if from == to {
+			continue
+		}
+		transition := flow.Find(from, to)
+		if transition == nil {
+			return pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+				"message":  fmt.Sprintf("%s不允许从 %q 变更为 %q", field.Name().String(), from, to),
+				"field_id": flow.FieldID,
+				"from":     from,
+				"to":       to,
+			})
+		}
+		record := &StatusTransition{
+			FieldID:        flow.FieldID,
+			FieldName:      field.Name().String(),
+			From:           from,
+			To:             to,
+			TransitionName: transition.Name,
+		}
+		if transition.RequiresApproval() {
+			role, ok := s.role(ctx, userID, tableID)
+			if !ok || !transition.Approves(role) {
+				return pkgerrors.ErrForbidden.WithDetails(map[string]interface{}{
+					"message":        fmt.Sprintf("%s从 %q 变更为 %q 需要审批角色", field.Name().String(), from, to),
+					"field_id":       flow.FieldID,
+					"approver_roles": transition.ApproverRoles,
+				})
+			}
+			record.Approved = true
+			record.Role = role
+		}
+		applied = append(applied, record)
+	}
+
+	for _, transition := range applied {
+		if err := s.record(ctx, userID, tableID, recordID, transition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record 在当前事务中写入流转的审计日志与变更流
+func (s *StatusFlowService) record(ctx context.Context, userID, tableID, recordID string, transition *StatusTransition) error {
+	if s.db != nil {
+		entry := models.AuditLog{
+			ID:           utils.GenerateIDWithPrefix("aud"),
+			Action:       "record_status_transition",
+			ResourceType: "record",
+			ResourceID:   &recordID,
+			Status:       "success",
+			Severity:     "info",
+			TableID:      &tableID,
+			RecordID:     &recordID,
+			FieldID:      &transition.FieldID,
+			OldValues:    jsonString(map[string]interface{}{transition.FieldID: transition.From}),
+			NewValues:    jsonString(map[string]interface{}{transition.FieldID: transition.To}),
+			Metadata:     jsonString(transition),
+			CreatedTime:  s.now(),
+		}
+		if userID != "" {
+			entry.UserID = &userID
+		}
+		if err := database.WithTx(ctx, s.db).WithContext(ctx).
+			Omit("User", "Organization", "Space", "Base", "Table", "Record", "Field").
+			Create(&entry).Error; err != nil {
+			return pkgerrors.Database(err, "写入状态流转审计日志失败")
+		}
+	}
+	if s.changeFeed != nil {
+		if err := s.changeFeed.TransitionChanged(ctx, tableID, recordID, transition, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *StatusFlowService) role(ctx context.Context, userID, tableID string) (string, bool) {
+	if s.roleOf == nil || userID == "" {
+		return "", false
+	}
+	return s.roleOf(ctx, userID, tableID)
+}
+
+// tableFlows 表中启用的状态流（带缓存）
+func (s *StatusFlowService) tableFlows(ctx context.Context, tableID string) ([]*statusflow.Flow, error) {
+	s.mu.Lock()
+	entry, ok := s.cache[tableID]
+	s.mu.Unlock()
+	if ok && s.now().Before(entry.expires) {
+		return entry.flows, nil
+	}
+	all, err := s.repo.ListByTable(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询状态流失败")
+	}
+	flows := make([]*statusflow.Flow, 0, len(all))
+	for _, flow := range all {
+		if flow.Enabled {
+			flows = append(flows, flow)
+		}
+	}
+	s.mu.Lock()
+	s.cache[tableID] = statusFlowCacheEntry{flows: flows, expires: s.now().Add(statusFlowCacheTTL)}
+	s.mu.Unlock()
+	return flows, nil
+}
+
+func (s *StatusFlowService) invalidate(tableID string) {
+	s.mu.Lock()
+	delete(s.cache, tableID)
+	s.mu.Unlock()
+}
+
+// selectField 状态流只能定义在表的单选字段上
+func (s *StatusFlowService) selectField(ctx context.Context, tableID, fieldID string) (*fieldEntity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	for _, field := range fields {
+		if field.ID().String() != fieldID {
+			continue
+		}
+		switch field.Type().String() {
+		case fieldVO.TypeSingleSelect, fieldVO.TypeSelect:
+			return field, nil
+		}
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("状态流只能定义在单选字段上")
+	}
+	return nil, pkgerrors.ErrFieldNotFound.WithDetails(map[string]interface{}{"field_id": fieldID})
+}
+
+// selectChoiceNames 单选字段的选项名称
+func selectChoiceNames(field *fieldEntity.Field) []string {
+	options := field.Options()
+	if options == nil || options.Select == nil {
+		return nil
+	}
+	names := make([]string, 0, len(options.Select.Choices))
+	for _, choice := range options.Select.Choices {
+		names = append(names, choice.Name)
+	}
+	return names
+}
+
+// fieldValue 按字段ID或名称取值
+func fieldValue(data map[string]interface{}, field *fieldEntity.Field) (interface{}, bool) {
+	if value, ok := data[field.ID().String()]; ok {
+		return value, true
+	}
+	value, ok := data[field.Name().String()]
+	return value, ok
+}
+
+// statusName 单选值转换为选项名称（值可能是选项ID）；空值为 ""
+func statusName(field *fieldEntity.Field, value interface{}) string {
+	if list, ok := value.([]interface{}); ok {
+		if len(list) == 0 {
+			return ""
+		}
+		value = list[0]
+	}
+	name, _ := value.(string)
+	if options := field.Options(); options != nil && options.Select != nil {
+		for _, choice := range options.Select.Choices {
+			if choice.ID == name {
+				return choice.Name
+			}
+		}
+	}
+	return name
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/statusflow"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryStatusFlowRepo 内存实现的状态流仓储
+type memoryStatusFlowRepo struct {
+	flows map[string]*statusflow.Flow
+}
+
+func (r *memoryStatusFlowRepo) Save(_ context.Context, flow *statusflow.Flow) error {
+	r.flows[flow.FieldID] = flow
+	return nil
+}
+
+func (r *memoryStatusFlowRepo) FindByField(_ context.Context, fieldID string) (*statusflow.Flow, error) {
+	return r.flows[fieldID], nil
+}
+
+func (r *memoryStatusFlowRepo) ListByTable(_ context.Context, tableID string) ([]*statusflow.Flow, error) {
+	var list []*statusflow.Flow
+	for _, flow := range r.flows {
+		if flow.TableID == tableID {
+			list = append(list, flow)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryStatusFlowRepo) Delete(_ context.Context, id string) error {
+	for fieldID, flow := range r.flows {
+		if flow.ID == id {
+			delete(r.flows, fieldID)
+		}
+	}
+	return nil
+}
+
+func TestStatusFlowApplyTransitions(t *testing.T) {
+	status := newTemplateTestField(t, "状态", fieldVO.TypeSingleSelect)
+	options := fieldVO.NewFieldOptions().WithSelect([]fieldVO.SelectChoice{
+		{ID: "cho1", Name: "草稿"},
+		{ID: "cho2", Name: "待审批"},
+		{ID: "cho3", Name: "已批准"},
+	})
+	if err := status.UpdateOptions(options); err != nil {
+		t.Fatalf("设置选项失败: %v", err)
+	}
+	note := newTemplateTestField(t, "备注", fieldVO.TypeText)
+
+	flow := statusflow.NewFlow("tbl1", status.ID().String(), []string{"草稿"}, []statusflow.Transition{
+		{Name: "提交审批", From: "草稿", To: "待审批"},
+		{Name: "批准", From: "待审批", To: "已批准", ApproverRoles: []string{"owner"}},
+	}, "usr1", time.Now())
+	repo := &memoryStatusFlowRepo{flows: map[string]*statusflow.Flow{flow.FieldID: flow}}
+
+	svc := NewStatusFlowService(repo, &stubLockFieldRepo{fields: []*fieldEntity.Field{status, note}}, nil, nil, nil)
+	svc.roleOf = func(_ context.Context, userID, _ string) (string, bool) {
+		if userID == "owner1" {
+			return "owner", true
+		}
+		return "editor", true
+	}
+	ctx := context.Background()
+	before := map[string]interface{}{status.ID().String(): "草稿"}
+	pending := map[string]interface{}{status.ID().String(): "待审批"}
+
+	cases := []struct {
+		name    string
+		user    string
+		before  map[string]interface{}
+		changes map[string]interface{}
+		err     *pkgerrors.AppError
+	}{
+		{"新记录使用初始状态", "usr2", nil, map[string]interface{}{"状态": "草稿"}, nil},
+		{"新记录使用非初始状态", "usr2", nil, map[string]interface{}{"状态": "已批准"}, pkgerrors.ErrValidationFailed},
+		{"按选项ID提交审批", "usr2", before, map[string]interface{}{status.ID().String(): "cho2"}, nil},
+		{"跳过审批", "usr2", before, map[string]interface{}{"状态": "已批准"}, pkgerrors.ErrValidationFailed},
+		{"非审批角色批准", "usr2", pending, map[string]interface{}{"状态": "已批准"}, pkgerrors.ErrForbidden},
+		{"审批角色批准", "owner1", pending, map[string]interface{}{"状态": []interface{}{"已批准"}}, nil},
+		{"状态未变化", "usr2", pending, map[string]interface{}{"状态": "待审批"}, nil},
+		{"未修改状态字段", "usr2", before, map[string]interface{}{"备注": "x"}, nil},
+	}
+	for _, c := range cases {
+		err := svc.ApplyTransitions(ctx, c.user, "tbl1", "rec1", c.before, c.changes)
+		if c.err == nil {
+			if err != nil {
+				t.Errorf("%s: err = %v", c.name, err)
+			}
+			continue
+		}
+		if !pkgerrors.Is(err, c.err) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.err)
+		}
+	}
+
+	// 停用的状态流不再检查（修改后缓存失效）
+	flow.Enabled = false
+	svc.invalidate("tbl1")
+	if err := svc.ApplyTransitions(ctx, "usr2", "tbl1", "rec1", before, map[string]interface{}{"状态": "已批准"}); err != nil {
+		t.Errorf("停用的状态流不应阻止写入: %v", err)
+	}
+}
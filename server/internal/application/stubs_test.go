@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+)
+
+// stubTableRepo 只有列出的表存在
+type stubTableRepo struct {
+	tableRepo.TableRepository
+	tables map[string]*tableEntity.Table
+}
+
+func (r *stubTableRepo) GetByID(_ context.Context, id string) (*tableEntity.Table, error) {
+	return r.tables[id], nil
+}
+
+// stubFieldRepo 返回表的字段
+type stubFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields []*fieldEntity.Field
+}
+
+func (r *stubFieldRepo) FindByTableID(context.Context, string) ([]*fieldEntity.Field, error) {
+	return r.fields, nil
+}
+
+// stubRecordRepo 按表保存记录，List 按插入顺序分页
+type stubRecordRepo struct {
+	recordRepo.RecordRepository
+	tables map[string][]*recordEntity.Record
+}
+
+func (r *stubRecordRepo) put(tableID, id string, values map[string]interface{}) {
+	data, _ := recordVO.NewRecordData(values)
+	record := recordEntity.ReconstructRecord(recordVO.NewRecordID(id), tableID, data, recordVO.InitialVersion(), "usr1", "usr1", time.Now(), time.Now(), nil)
+	for i, existing := range r.tables[tableID] {
+		if existing.ID().String() == id {
+			r.tables[tableID][i] = record
+			return
+		}
+	}
+	r.tables[tableID] = append(r.tables[tableID], record)
+}
+
+func (r *stubRecordRepo) List(_ context.Context, filter recordRepo.RecordFilter) ([]*recordEntity.Record, int64, error) {
+	all := r.tables[*filter.TableID]
+	start, end := filter.Offset, filter.Offset+filter.Limit
+	if start > len(all) {
+		start = len(all)
+	}
+	if end > len(all) {
+		end = len(all)
+	}
+	return all[start:end], int64(len(all)), nil
+}
+
+func (r *stubRecordRepo) FindByIDs(_ context.Context, tableID string, ids []recordVO.RecordID, _ ...string) ([]*recordEntity.Record, error) {
+	var list []*recordEntity.Record
+	for _, id := range ids {
+		if record, _ := r.FindByTableAndID(context.Background(), tableID, id); record != nil {
+			list = append(list, record)
+		}
+	}
+	return list, nil
+}
+
+func (r *stubRecordRepo) FindByTableAndID(_ context.Context, tableID string, id recordVO.RecordID) (*recordEntity.Record, error) {
+	for _, record := range r.tables[tableID] {
+		if record.ID().String() == id.String() {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
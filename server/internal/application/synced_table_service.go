@@ -0,0 +1,983 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/syncedtable"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var syncedTableLog = logger.Named("synced_table")
+
+// syncedTableDeletedFieldName 镜像表中标记源记录已删除的复选框字段
+const syncedTableDeletedFieldName = "源记录已删除"
+
+// syncedTableMirrorTTL 表是否为镜像表的缓存时间（只在创建/删除同步时变化）
+const syncedTableMirrorTTL = time.Minute
+
+// errSyncedTableNeedsFullSync 增量同步无法继续，需要全量同步
+var errSyncedTableNeedsFullSync = errors.New("synced table needs full resync")
+
+type syncedTableWriteKey struct{}
+
+// withSyncedTableWrite 标记由同步写入镜像表（绕过只读限制）
+func withSyncedTableWrite(ctx context.Context) context.Context {
+	return context.WithValue(ctx, syncedTableWriteKey{}, true)
+}
+
+func isSyncedTableWrite(ctx context.Context) bool {
+	v, _ := ctx.Value(syncedTableWriteKey{}).(bool)
+	return v
+}
+
+// syncedTableCreator 创建镜像表（由 TableService 实现）
+type syncedTableCreator interface {
+	CreateTable(ctx context.Context, req dto.CreateTableRequest, userID string) (*dto.TableResponse, error)
+}
+
+// syncedFieldCreator 为镜像表追加字段（由 FieldService 实现）
+type syncedFieldCreator interface {
+	CreateField(ctx context.Context, req dto.CreateFieldRequest, userID string) (*dto.FieldResponse, error)
+}
+
+// syncedRecordWriter 批量写入镜像记录（由 RecordService 实现）
+type syncedRecordWriter interface {
+	MutateRecords(ctx context.Context, tableID string, req dto.RecordMutationBatchRequest, userID string) (*dto.RecordMutationBatchResponse, error)
+}
+
+// CreateSyncedTableRequest 创建同步表请求
+type CreateSyncedTableRequest struct {
+	Name          string `json:"name" binding:"required"` // 镜像表名称
+	SourceTableID string `json:"sourceTableId" binding:"required"`
+	SourceViewID  string `json:"sourceViewId" binding:"required"`
+	Interval      string `json:"interval"` // 增量同步间隔，如 "5m"（为空时使用默认间隔）
+}
+
+// UpdateSyncedTableRequest 更新同步表请求（未提供的字段保持不变）
+type UpdateSyncedTableRequest struct {
+	Name     *string `json:"name"`
+	Interval *string `json:"interval"`
+	Enabled  *bool   `json:"enabled"`
+}
+
+// syncedTableSource 一次运行读取的源数据范围
+type syncedTableSource struct {
+	baseID    string
+	fields    []*fieldEntity.Field // 同步到镜像表的源字段
+	condition clause.Expression    // 视图过滤条件
+}
+
+// syncedTargetFinder 判断表是否为其他同步方式写入的只读表（如外部数据同步）
+type syncedTargetFinder interface {
+	IsTargetTable(ctx context.Context, tableID string) (bool, error)
+}
+
+type syncedTableMirrorEntry struct {
+	mirror  bool
+	expires time.Time
+}
+
+// SyncedTableService 同步表服务 ✨
+// 将一个 Base 中视图的记录镜像到另一个 Base（允许时可跨空间）的只读表：
+//   - 创建时按视图可见字段建立镜像表，另加“源记录已删除”复选框字段；
+//   - 首次全量复制，之后按间隔从源 Base 的变更流增量同步，源字段或视图变化、游标过期时改为全量；
+//   - 源记录被删除或不再满足视图条件时，镜像记录保留并勾选删除标记，重新出现时取消标记；
+//   - 镜像表只能由同步写入（RecordService 拒绝其他写入），因此不会产生冲突；
+//     外部数据同步的目标表登记为只读来源后，同样由这里检查。
+//
+// 计算字段、关联字段、加密字段与受脱敏策略保护的字段不会同步
+type SyncedTableService struct {
+	repo              syncedtable.Repository
+	changeFeed        *ChangeFeedService
+	tableRepo         tableRepo.TableRepository
+	baseRepo          baseRepo.BaseRepository
+	fieldRepo         repository.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	recordRepo        recordRepo.RecordRepository
+	tables            syncedTableCreator
+	fields            syncedFieldCreator
+	records           syncedRecordWriter
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	cfg               config.SyncedTableConfig
+
+	// 源记录ID查询：ids 非空时返回其中满足视图条件的记录，否则按记录ID分页（测试中替换）
+	selectIDs func(ctx context.Context, source *syncedTableSource, tableID string, ids []string, afterID string, limit int) ([]string, error)
+
+	finders []syncedTargetFinder // 其他只读同步表的来源
+
+	mu      sync.Mutex
+	mirrors map[string]syncedTableMirrorEntry // 表ID -> 是否为镜像表
+}
+
+// NewSyncedTableService 创建同步表服务
+func NewSyncedTableService(
+	repo syncedtable.Repository,
+	changeFeed *ChangeFeedService,
+	tableRepository tableRepo.TableRepository,
+	baseRepository baseRepo.BaseRepository,
+	fieldRepo repository.FieldRepository,
+	viewRepository viewRepo.ViewRepository,
+	recordRepository recordRepo.RecordRepository,
+	tables syncedTableCreator,
+	fields syncedFieldCreator,
+	records syncedRecordWriter,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	cfg config.SyncedTableConfig,
+) *SyncedTableService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = time.Minute
+	}
+	if cfg.DefaultInterval < cfg.MinInterval {
+		cfg.DefaultInterval = 5 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 200
+	}
+	if cfg.BatchSize > recordMutationBatchMaxOps {
+		cfg.BatchSize = recordMutationBatchMaxOps
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 30 * time.Minute
+	}
+	s := &SyncedTableService{
+		repo:              repo,
+		changeFeed:        changeFeed,
+		tableRepo:         tableRepository,
+		baseRepo:          baseRepository,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepository,
+		recordRepo:        recordRepository,
+		tables:            tables,
+		fields:            fields,
+		records:           records,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		cfg:               cfg,
+		mirrors:           make(map[string]syncedTableMirrorEntry),
+	}
+	s.selectIDs = s.physicalSelectIDs
+	return s
+}
+
+// CreateSync 在目标 Base 中创建镜像表并登记同步（首次全量同步由后台立即执行）
+func (s *SyncedTableService) CreateSync(ctx context.Context, userID, targetBaseID string, req CreateSyncedTableRequest) (*syncedtable.Sync, error) {
+	if s.permissionService != nil && !s.permissionService.CanUpdateBase(ctx, userID, targetBaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限在该Base中创建同步表")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+	}
+	interval := s.cfg.DefaultInterval
+	if req.Interval != "" {
+		var err error
+		if interval, err = s.parseInterval(req.Interval); err != nil {
+			return nil, err
+		}
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, req.SourceTableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找源表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("源表格不存在")
+	}
+	if table.BaseID() == targetBaseID {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("同步表只能镜像其他 Base 的视图")
+	}
+	if s.permissionService != nil && !s.permissionService.CanAccessTable(ctx, userID, req.SourceTableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限读取源表格")
+	}
+	if err := s.checkSpaces(ctx, table.BaseID(), targetBaseID); err != nil {
+		return nil, err
+	}
+
+	st := syncedtable.NewSync(name, table.BaseID(), req.SourceTableID, req.SourceViewID, targetBaseID, interval, userID)
+	source, err := s.loadSource(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedName := syncedTableDeletedFieldName
+	configs := make([]dto.FieldConfigDTO, 0, len(source.fields)+1)
+	for _, field := range source.fields {
+		if field.Name().String() == deletedName {
+			deletedName = syncedTableDeletedFieldName + "（同步）"
+		}
+		configs = append(configs, dto.FieldConfigDTO{
+			Name:        field.Name().String(),
+			Type:        field.Type().String(),
+			Description: syncedFieldDescription(field),
+			IsPrimary:   field.IsPrimary(),
+			Options:     syncedFieldOptions(field),
+		})
+	}
+	configs = append(configs, dto.FieldConfigDTO{Name: deletedName, Type: fieldVO.TypeCheckbox})
+
+	mirror, err := s.tables.CreateTable(ctx, dto.CreateTableRequest{
+		Name:        name,
+		Description: "同步表（只读），数据来自其他 Base 的视图",
+		BaseID:      targetBaseID,
+		Fields:      configs,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+	st.TargetTableID = mirror.ID
+
+	// 按名称对应创建出的字段（创建失败的字段在首次同步时补建）
+	created, err := s.fieldRepo.FindByTableID(ctx, mirror.ID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询镜像表字段失败")
+	}
+	byName := make(map[string]string, len(created))
+	for _, field := range created {
+		byName[field.Name().String()] = field.ID().String()
+	}
+	for _, field := range source.fields {
+		if id, ok := byName[field.Name().String()]; ok {
+			st.FieldMap[field.ID().String()] = id
+		}
+	}
+	st.DeletedFieldID = byName[deletedName]
+
+	if err := s.repo.Save(ctx, st); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步表失败")
+	}
+	s.invalidate(mirror.ID)
+	return st, nil
+}
+
+// ListSyncs 列出镜像到该 Base 的同步表
+func (s *SyncedTableService) ListSyncs(ctx context.Context, userID, baseID string) ([]*syncedtable.Sync, error) {
+	if s.permissionService != nil && !s.permissionService.CanAccessBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	syncs, err := s.repo.ListByTargetBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步表失败")
+	}
+	return syncs, nil
+}
+
+// GetSync 获取同步表
+func (s *SyncedTableService) GetSync(ctx context.Context, userID, syncID string) (*syncedtable.Sync, error) {
+	st, err := s.findSync(ctx, syncID)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanAccessBase(ctx, userID, st.TargetBaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该Base")
+	}
+	return st, nil
+}
+
+// UpdateSync 更新同步表（名称、间隔、启停）
+func (s *SyncedTableService) UpdateSync(ctx context.Context, userID, syncID string, req UpdateSyncedTableRequest) (*syncedtable.Sync, error) {
+	st, err := s.loadSync(ctx, userID, syncID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+		}
+		st.Name = name
+	}
+	if req.Interval != nil {
+		if st.Interval, err = s.parseInterval(*req.Interval); err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && !st.Enabled {
+			st.NextRunAt = time.Now()
+		}
+		st.Enabled = *req.Enabled
+	}
+	st.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, st); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步表失败")
+	}
+	return st, nil
+}
+
+// TriggerSync 立即同步一次（由后台在下一个轮询周期执行）
+func (s *SyncedTableService) TriggerSync(ctx context.Context, userID, syncID string) (*syncedtable.Sync, error) {
+	st, err := s.loadSync(ctx, userID, syncID)
+	if err != nil {
+		return nil, err
+	}
+	if !st.Enabled {
+		return nil, pkgerrors.ErrConflict.WithDetails("同步已停用")
+	}
+	st.NextRunAt = time.Now()
+	st.UpdatedAt = st.NextRunAt
+	if err := s.repo.Save(ctx, st); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步表失败")
+	}
+	return st, nil
+}
+
+// DeleteSync 停止同步：镜像表保留并恢复为普通表
+func (s *SyncedTableService) DeleteSync(ctx context.Context, userID, syncID string) error {
+	st, err := s.loadSync(ctx, userID, syncID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, syncID); err != nil {
+		return pkgerrors.Database(err, "删除同步表失败")
+	}
+	s.invalidate(st.TargetTableID)
+	return nil
+}
+
+// CheckWritable 镜像表只读：同步之外的记录写入返回错误
+func (s *SyncedTableService) CheckWritable(ctx context.Context, tableID string) error {
+	if isSyncedTableWrite(ctx) {
+		return nil
+	}
+	mirror, err := s.isMirror(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询同步表失败")
+	}
+	if mirror {
+		return pkgerrors.ErrForbidden.WithDetails("同步表为只读，请在源表中修改记录")
+	}
+	return nil
+}
+
+func (s *SyncedTableService) isMirror(ctx context.Context, tableID string) (bool, error) {
+	s.mu.Lock()
+	entry, ok := s.mirrors[tableID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.mirror, nil
+	}
+	st, err := s.repo.FindByTargetTable(ctx, tableID)
+	if err != nil {
+		return false, err
+	}
+	mirror := st != nil
+	for _, finder := range s.finders {
+		if mirror {
+			break
+		}
+		if mirror, err = finder.IsTargetTable(ctx, tableID); err != nil {
+			return false, err
+		}
+	}
+	s.mu.Lock()
+	s.mirrors[tableID] = syncedTableMirrorEntry{mirror: mirror, expires: time.Now().Add(syncedTableMirrorTTL)}
+	s.mu.Unlock()
+	return mirror, nil
+}
+
+// addTargetFinder 登记其他只读同步表的来源（在启动前调用）
+func (s *SyncedTableService) addTargetFinder(finder syncedTargetFinder) {
+	s.finders = append(s.finders, finder)
+}
+
+func (s *SyncedTableService) invalidate(tableID string) {
+	s.mu.Lock()
+	delete(s.mirrors, tableID)
+	s.mu.Unlock()
+}
+
+func (s *SyncedTableService) findSync(ctx context.Context, syncID string) (*syncedtable.Sync, error) {
+	st, err := s.repo.FindByID(ctx, syncID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步表失败")
+	}
+	if st == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("同步表不存在")
+	}
+	return st, nil
+}
+
+// loadSync 获取同步表并校验目标 Base 的管理权限
+func (s *SyncedTableService) loadSync(ctx context.Context, userID, syncID string) (*syncedtable.Sync, error) {
+	st, err := s.findSync(ctx, syncID)
+	if err != nil {
+		return nil, err
+	}
+	if s.permissionService != nil && !s.permissionService.CanUpdateBase(ctx, userID, st.TargetBaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的同步表")
+	}
+	return st, nil
+}
+
+// checkSpaces 源 Base 与目标 Base 不在同一空间时需要配置允许跨空间同步
+func (s *SyncedTableService) checkSpaces(ctx context.Context, sourceBaseID, targetBaseID string) error {
+	if s.cfg.AllowCrossSpace {
+		return nil
+	}
+	source, err := s.baseRepo.FindByID(ctx, sourceBaseID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找源 Base 失败")
+	}
+	target, err := s.baseRepo.FindByID(ctx, targetBaseID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找目标 Base 失败")
+	}
+	if source == nil || target == nil || !target.BelongsToSpace(source.SpaceID) {
+		return pkgerrors.ErrValidationFailed.WithDetails("只能同步同一空间内其他 Base 的视图")
+	}
+	return nil
+}
+
+func (s *SyncedTableService) parseInterval(raw string) (time.Duration, error) {
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails("同步间隔无效，如 5m")
+	}
+	if interval < s.cfg.MinInterval {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("同步间隔不能小于 %s", s.cfg.MinInterval))
+	}
+	return interval, nil
+}
+
+// Start 启动后台同步
+func (s *SyncedTableService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runDue(ctx)
+		}
+	}()
+}
+
+// runDue 领取并执行到期的同步
+func (s *SyncedTableService) runDue(ctx context.Context) {
+	now := time.Now()
+	syncs, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 5)
+	if err != nil {
+		syncedTableLog.Warn(ctx, "领取同步表失败", logger.ErrorField(err))
+		return
+	}
+	for _, st := range syncs {
+		s.runSync(ctx, st)
+	}
+}
+
+// runSync 执行一次同步并保存进度
+func (s *SyncedTableService) runSync(ctx context.Context, st *syncedtable.Sync) {
+	err := s.syncOnce(ctx, st)
+	now := time.Now()
+	st.LastRunAt = &now
+	st.LastStatus = syncedtable.RunSucceeded
+	st.LastError = ""
+	if err != nil {
+		st.LastStatus = syncedtable.RunFailed
+		st.LastError = err.Error()
+		syncedTableLog.Warn(ctx, "同步表同步失败",
+			logger.String("sync_id", st.ID),
+			logger.ErrorField(err))
+	}
+	st.NextRunAt = now.Add(st.Interval)
+	if err := s.repo.SaveProgress(ctx, st); err != nil {
+		syncedTableLog.Warn(ctx, "保存同步进度失败", logger.String("sync_id", st.ID), logger.ErrorField(err))
+	}
+}
+
+// syncOnce 补建镜像字段后执行增量同步，无法增量时改为全量
+func (s *SyncedTableService) syncOnce(ctx context.Context, st *syncedtable.Sync) error {
+	if s.permissionService != nil && !s.permissionService.CanAccessTable(ctx, st.CreatedBy, st.SourceTableID) {
+		return fmt.Errorf("同步创建者已无权读取源表")
+	}
+	target, err := s.tableRepo.GetByID(ctx, st.TargetTableID)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return fmt.Errorf("镜像表 %s 不存在", st.TargetTableID)
+	}
+	source, err := s.loadSource(ctx, st)
+	if err != nil {
+		return err
+	}
+	added, err := s.ensureFields(ctx, st, source)
+	if err != nil {
+		return err
+	}
+
+	if !st.NeedsFullSync() && !added {
+		err = s.syncIncremental(ctx, st, source)
+		if !errors.Is(err, errSyncedTableNeedsFullSync) {
+			return err
+		}
+	}
+	return s.syncFull(ctx, st, source)
+}
+
+// syncFull 全量同步：写入视图中的全部记录，本次未写入的镜像记录标记为源记录已删除
+// 读取前先记录变更流游标，之后的增量同步从该游标继续，不会遗漏读取期间的变更
+func (s *SyncedTableService) syncFull(ctx context.Context, st *syncedtable.Sync, source *syncedTableSource) error {
+	head, err := s.changeFeed.repo.Head(ctx)
+	if err != nil {
+		return err
+	}
+	startedAt := time.Now()
+
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ids, err := s.selectIDs(ctx, source, st.SourceTableID, nil, afterID, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if err := s.writeRecords(ctx, st, source, ids); err != nil {
+			return err
+		}
+		if len(ids) < s.cfg.BatchSize {
+			break
+		}
+		afterID = ids[len(ids)-1]
+	}
+
+	for {
+		stale, err := s.repo.ListStaleRows(ctx, st.ID, startedAt, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			break
+		}
+		if err := s.markRemoved(ctx, st, stale); err != nil {
+			return err
+		}
+	}
+	st.Cursor = head
+	return nil
+}
+
+// syncIncremental 从源 Base 的变更流读取游标之后的变更并写入镜像表
+// 每批写入成功后推进游标；源字段或所镜像的视图变化时改为全量同步
+func (s *SyncedTableService) syncIncremental(ctx context.Context, st *syncedtable.Sync, source *syncedTableSource) error {
+	for {
+		changes, err := s.changeFeed.repo.ListAfter(ctx, st.SourceBaseID, changefeed.ListFilter{
+			After:   st.Cursor,
+			TableID: st.SourceTableID,
+			Limit:   s.cfg.BatchSize,
+		})
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			return errSyncedTableNeedsFullSync
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		changed := make(map[string]bool)
+		for _, change := range changes {
+			switch change.EntityType {
+			case changefeed.EntityField:
+				if change.Action != changefeed.ActionDelete {
+					return errSyncedTableNeedsFullSync
+				}
+			case changefeed.EntityView:
+				if change.EntityID == st.SourceViewID {
+					return errSyncedTableNeedsFullSync
+				}
+			case changefeed.EntityRecord:
+				changed[change.EntityID] = true
+			}
+		}
+
+		if len(changed) > 0 {
+			ids := make([]string, 0, len(changed))
+			for id := range changed {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			present, err := s.selectIDs(ctx, source, st.SourceTableID, ids, "", 0)
+			if err != nil {
+				return err
+			}
+			if err := s.writeRecords(ctx, st, source, present); err != nil {
+				return err
+			}
+
+			// 已删除或不再满足视图条件的记录
+			for _, id := range present {
+				delete(changed, id)
+			}
+			removed := make([]string, 0, len(changed))
+			for id := range changed {
+				removed = append(removed, id)
+			}
+			rows, err := s.repo.FindRows(ctx, st.ID, removed)
+			if err != nil {
+				return err
+			}
+			var live []*syncedtable.Row
+			for _, row := range rows {
+				if !row.SourceDeleted {
+					live = append(live, row)
+				}
+			}
+			if err := s.markRemoved(ctx, st, live); err != nil {
+				return err
+			}
+		}
+
+		st.Cursor = changes[len(changes)-1].Seq
+		if len(changes) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// writeRecords 读取源记录并创建或更新对应的镜像记录（同时取消删除标记）
+// 对应的镜像记录已不存在时重新创建
+func (s *SyncedTableService) writeRecords(ctx context.Context, st *syncedtable.Sync, source *syncedTableSource, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, st.SourceTableID, recordIDs)
+	if err != nil {
+		return err
+	}
+	sourceIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		sourceIDs = append(sourceIDs, record.ID().String())
+	}
+	rows, err := s.repo.FindRows(ctx, st.ID, sourceIDs)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*syncedtable.Row, len(rows))
+	for _, row := range rows {
+		existing[row.SourceRecordID] = row
+	}
+
+	mutations := make([]dto.RecordMutation, 0, len(records))
+	for _, record := range records {
+		mutation := dto.RecordMutation{Op: dto.RecordMutationCreate, Fields: s.mirrorValues(st, source, record)}
+		if row := existing[record.ID().String()]; row != nil {
+			mutation.Op, mutation.ID = dto.RecordMutationUpdate, row.TargetRecordID
+		}
+		mutations = append(mutations, mutation)
+	}
+	items, err := s.mutate(ctx, st, mutations)
+	if err != nil {
+		return err
+	}
+
+	var saved []*syncedtable.Row
+	var retry []dto.RecordMutation
+	var retrySources []string
+	failed, message := 0, ""
+	for i, item := range items {
+		if item != nil && item.Status == "success" {
+			saved = append(saved, &syncedtable.Row{SyncID: st.ID, SourceRecordID: sourceIDs[i], TargetRecordID: item.ID, SyncedAt: time.Now()})
+			continue
+		}
+		if mutations[i].Op == dto.RecordMutationUpdate && item != nil && item.Error != nil && item.Error.Code == pkgerrors.ErrNotFound.Code {
+			retry = append(retry, dto.RecordMutation{Op: dto.RecordMutationCreate, Fields: mutations[i].Fields})
+			retrySources = append(retrySources, sourceIDs[i])
+			continue
+		}
+		failed++
+		message, _ = mutationFailure(item)
+	}
+	if len(retry) > 0 {
+		items, err := s.mutate(ctx, st, retry)
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			if item != nil && item.Status == "success" {
+				saved = append(saved, &syncedtable.Row{SyncID: st.ID, SourceRecordID: retrySources[i], TargetRecordID: item.ID, SyncedAt: time.Now()})
+				continue
+			}
+			failed++
+			message, _ = mutationFailure(item)
+		}
+	}
+	if err := s.repo.SaveRows(ctx, saved); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条镜像记录写入失败: %s", failed, message)
+	}
+	return nil
+}
+
+// markRemoved 为源中已不存在（或不再满足视图条件）的记录勾选删除标记
+func (s *SyncedTableService) markRemoved(ctx context.Context, st *syncedtable.Sync, rows []*syncedtable.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	var mutations []dto.RecordMutation
+	if st.DeletedFieldID != "" {
+		mutations = make([]dto.RecordMutation, 0, len(rows))
+		for _, row := range rows {
+			mutations = append(mutations, dto.RecordMutation{
+				Op:     dto.RecordMutationUpdate,
+				ID:     row.TargetRecordID,
+				Fields: map[string]interface{}{st.DeletedFieldID: true},
+			})
+		}
+	}
+	items, err := s.mutate(ctx, st, mutations)
+	if err != nil {
+		return err
+	}
+	failed, message := 0, ""
+	for i, item := range items {
+		// 镜像记录已不存在时无需标记
+		if item == nil || (item.Status != "success" && (item.Error == nil || item.Error.Code != pkgerrors.ErrNotFound.Code)) {
+			failed++
+			message, _ = mutationFailure(item)
+			rows[i] = nil
+		}
+	}
+	marked := make([]*syncedtable.Row, 0, len(rows))
+	for _, row := range rows {
+		if row != nil {
+			row.SourceDeleted = true
+			row.SyncedAt = time.Now()
+			marked = append(marked, row)
+		}
+	}
+	if err := s.repo.SaveRows(ctx, marked); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 条镜像记录标记删除失败: %s", failed, message)
+	}
+	return nil
+}
+
+// mutate 以同步身份写入镜像表，返回与 mutations 一一对应的结果
+func (s *SyncedTableService) mutate(ctx context.Context, st *syncedtable.Sync, mutations []dto.RecordMutation) ([]*dto.RecordMutationResult, error) {
+	results := make([]*dto.RecordMutationResult, len(mutations))
+	for start := 0; start < len(mutations); start += s.cfg.BatchSize {
+		end := start + s.cfg.BatchSize
+		if end > len(mutations) {
+			end = len(mutations)
+		}
+		resp, err := s.records.MutateRecords(withSyncedTableWrite(ctx), st.TargetTableID, dto.RecordMutationBatchRequest{Operations: mutations[start:end]}, st.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Results {
+			if item.Index >= 0 && start+item.Index < end {
+				results[start+item.Index] = item
+			}
+		}
+	}
+	return results, nil
+}
+
+// mirrorValues 将源记录的值按字段对应关系转换为镜像记录的值
+func (s *SyncedTableService) mirrorValues(st *syncedtable.Sync, source *syncedTableSource, record *recordEntity.Record) map[string]interface{} {
+	values := make(map[string]interface{}, len(source.fields)+1)
+	for _, field := range source.fields {
+		targetID, ok := st.FieldMap[field.ID().String()]
+		if !ok {
+			continue
+		}
+		value, _ := record.Data().Get(field.ID().String())
+		values[targetID] = value
+	}
+	if st.DeletedFieldID != "" {
+		values[st.DeletedFieldID] = false
+	}
+	return values
+}
+
+// ensureFields 为尚未镜像（或镜像字段已被删除）的源字段与删除标记字段补建镜像字段，返回是否新建了字段
+func (s *SyncedTableService) ensureFields(ctx context.Context, st *syncedtable.Sync, source *syncedTableSource) (bool, error) {
+	targetFields, err := s.fieldRepo.FindByTableID(ctx, st.TargetTableID)
+	if err != nil {
+		return false, err
+	}
+	exists := make(map[string]bool, len(targetFields))
+	for _, field := range targetFields {
+		exists[field.ID().String()] = true
+	}
+
+	added := false
+	for _, field := range source.fields {
+		if exists[st.FieldMap[field.ID().String()]] {
+			continue
+		}
+		created, err := s.fields.CreateField(withSyncedTableWrite(ctx), dto.CreateFieldRequest{
+			TableID: st.TargetTableID,
+			Name:    field.Name().String(),
+			Type:    field.Type().String(),
+			Options: syncedFieldOptions(field),
+		}, st.CreatedBy)
+		if err != nil {
+			return added, fmt.Errorf("创建镜像字段 %s 失败: %w", field.Name().String(), err)
+		}
+		st.FieldMap[field.ID().String()] = created.ID
+		added = true
+	}
+	if !exists[st.DeletedFieldID] {
+		created, err := s.fields.CreateField(withSyncedTableWrite(ctx), dto.CreateFieldRequest{
+			TableID: st.TargetTableID,
+			Name:    syncedTableDeletedFieldName,
+			Type:    fieldVO.TypeCheckbox,
+		}, st.CreatedBy)
+		if err != nil {
+			return added, fmt.Errorf("创建删除标记字段失败: %w", err)
+		}
+		st.DeletedFieldID = created.ID
+		added = true
+	}
+	return added, nil
+}
+
+// loadSource 加载源视图的可见字段与过滤条件
+// 计算字段、关联字段、按钮、加密字段与受脱敏策略保护的字段不同步
+func (s *SyncedTableService) loadSource(ctx context.Context, st *syncedtable.Sync) (*syncedTableSource, error) {
+	view, err := s.viewRepo.FindByID(ctx, st.SourceViewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找源视图失败")
+	}
+	if view == nil || view.TableID() != st.SourceTableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("源视图不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, st.SourceTableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询源字段失败")
+	}
+
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, st.SourceTableID)
+	}
+	hidden := make(map[string]bool)
+	if meta := view.ColumnMeta(); meta != nil {
+		for _, col := range meta.Columns {
+			if !col.Visible {
+				hidden[col.FieldID] = true
+			}
+		}
+	}
+
+	source := &syncedTableSource{baseID: st.SourceBaseID}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		id := field.ID().String()
+		byID[id] = field
+		if hidden[id] || !syncableField(field) || protected[id] {
+			continue
+		}
+		source.fields = append(source.fields, field)
+	}
+	sort.SliceStable(source.fields, func(i, j int) bool { return source.fields[i].Order() < source.fields[j].Order() })
+	if source.condition, err = buildViewFilterCondition(ctx, view.Filter(), byID); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("源视图的过滤条件无效: %v", err))
+	}
+	return source, nil
+}
+
+// syncableField 字段值是否可以原样写入镜像表
+func syncableField(field *fieldEntity.Field) bool {
+	if field.IsVirtual() || field.IsComputed() || field.IsEncrypted() {
+		return false
+	}
+	switch field.Type().String() {
+	case fieldVO.TypeLink, fieldVO.TypeButton:
+		return false
+	}
+	return true
+}
+
+// syncedFieldOptions 源字段选项（经 JSON 往返，数值统一为 float64，与请求解析结果一致）
+func syncedFieldOptions(field *fieldEntity.Field) map[string]interface{} {
+	options := dto.FromFieldEntity(field).Options
+	if len(options) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil
+	}
+	delete(normalized, "encrypted")
+	return normalized
+}
+
+func syncedFieldDescription(field *fieldEntity.Field) string {
+	if desc := field.Description(); desc != nil {
+		return *desc
+	}
+	return ""
+}
+
+// physicalSelectIDs 在源物理表上按视图过滤条件查询记录ID
+func (s *SyncedTableService) physicalSelectIDs(ctx context.Context, source *syncedTableSource, tableID string, ids []string, afterID string, limit int) ([]string, error) {
+	db := s.dataDB(ctx, source.baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(source.baseID, tableID))
+	if source.condition != nil {
+		db = db.Where(source.condition)
+	}
+	if len(ids) > 0 {
+		db = db.Where("__id IN ?", ids)
+	} else {
+		db = db.Where("__id > ?", afterID).Limit(limit)
+	}
+	var result []string
+	if err := db.Order("__id").Pluck("__id", &result).Error; err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,462 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/syncedtable"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memorySyncedTableRepo 内存实现的同步表仓储
+type memorySyncedTableRepo struct {
+	syncs map[string]*syncedtable.Sync
+	rows  map[string]*syncedtable.Row // 源记录ID -> 对应关系
+}
+
+func (r *memorySyncedTableRepo) Save(_ context.Context, sync *syncedtable.Sync) error {
+	r.syncs[sync.ID] = sync
+	return nil
+}
+
+func (r *memorySyncedTableRepo) FindByID(_ context.Context, id string) (*syncedtable.Sync, error) {
+	return r.syncs[id], nil
+}
+
+func (r *memorySyncedTableRepo) FindByTargetTable(_ context.Context, tableID string) (*syncedtable.Sync, error) {
+	for _, sync := range r.syncs {
+		if sync.TargetTableID == tableID {
+			return sync, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memorySyncedTableRepo) ListByTargetBase(_ context.Context, baseID string) ([]*syncedtable.Sync, error) {
+	var list []*syncedtable.Sync
+	for _, sync := range r.syncs {
+		if sync.TargetBaseID == baseID {
+			list = append(list, sync)
+		}
+	}
+	return list, nil
+}
+
+func (r *memorySyncedTableRepo) Delete(_ context.Context, id string) error {
+	delete(r.syncs, id)
+	return nil
+}
+
+func (r *memorySyncedTableRepo) ClaimDue(context.Context, time.Time, time.Time, int) ([]*syncedtable.Sync, error) {
+	return nil, nil
+}
+
+func (r *memorySyncedTableRepo) SaveProgress(_ context.Context, sync *syncedtable.Sync) error {
+	r.syncs[sync.ID] = sync
+	return nil
+}
+
+func (r *memorySyncedTableRepo) FindRows(_ context.Context, _ string, sourceRecordIDs []string) ([]*syncedtable.Row, error) {
+	var rows []*syncedtable.Row
+	for _, id := range sourceRecordIDs {
+		if row, ok := r.rows[id]; ok {
+			copied := *row
+			rows = append(rows, &copied)
+		}
+	}
+	return rows, nil
+}
+
+func (r *memorySyncedTableRepo) SaveRows(_ context.Context, rows []*syncedtable.Row) error {
+	for _, row := range rows {
+		copied := *row
+		r.rows[row.SourceRecordID] = &copied
+	}
+	return nil
+}
+
+func (r *memorySyncedTableRepo) ListStaleRows(_ context.Context, _ string, before time.Time, limit int) ([]*syncedtable.Row, error) {
+	var rows []*syncedtable.Row
+	for _, row := range r.rows {
+		if row.SyncedAt.Before(before) && !row.SourceDeleted {
+			copied := *row
+			rows = append(rows, &copied)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].SourceRecordID < rows[j].SourceRecordID })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// stubSyncedChangeFeed 返回预置的变更，游标之前的变更视为已过期
+type stubSyncedChangeFeed struct {
+	changefeed.Repository
+	changes []*changefeed.Change
+	expired int64
+}
+
+func (r *stubSyncedChangeFeed) Head(context.Context) (int64, error) {
+	if len(r.changes) == 0 {
+		return 0, nil
+	}
+	return r.changes[len(r.changes)-1].Seq, nil
+}
+
+func (r *stubSyncedChangeFeed) ListAfter(_ context.Context, _ string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if filter.After < r.expired {
+		return nil, changefeed.ErrCursorExpired
+	}
+	var list []*changefeed.Change
+	for _, change := range r.changes {
+		if change.Seq > filter.After && change.TableID == filter.TableID && len(list) < filter.Limit {
+			list = append(list, change)
+		}
+	}
+	return list, nil
+}
+
+// stubSyncedFieldRepo 按表返回字段
+type stubSyncedFieldRepo struct {
+	fieldRepo.FieldRepository
+	tables map[string][]*fieldEntity.Field
+}
+
+func (r *stubSyncedFieldRepo) FindByTableID(_ context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	return r.tables[tableID], nil
+}
+
+// stubSyncedViewRepo 返回预置的视图
+type stubSyncedViewRepo struct {
+	viewRepo.ViewRepository
+	views map[string]*viewEntity.View
+}
+
+func (r *stubSyncedViewRepo) FindByID(_ context.Context, id string) (*viewEntity.View, error) {
+	return r.views[id], nil
+}
+
+// stubSyncedMirror 内存中的镜像表：记录写入与字段创建
+type stubSyncedMirror struct {
+	fields  *stubSyncedFieldRepo
+	records map[string]map[string]interface{}
+	nextID  int
+	writes  int
+}
+
+func (m *stubSyncedMirror) MutateRecords(ctx context.Context, tableID string, req dto.RecordMutationBatchRequest, _ string) (*dto.RecordMutationBatchResponse, error) {
+	if !isSyncedTableWrite(ctx) {
+		return nil, fmt.Errorf("写入镜像表时缺少同步标记")
+	}
+	resp := &dto.RecordMutationBatchResponse{}
+	for i, op := range req.Operations {
+		m.writes++
+		switch op.Op {
+		case dto.RecordMutationCreate:
+			m.nextID++
+			id := fmt.Sprintf("mir%d", m.nextID)
+			m.records[id] = map[string]interface{}{}
+			for key, value := range op.Fields {
+				m.records[id][key] = value
+			}
+			resp.Results = append(resp.Results, &dto.RecordMutationResult{Index: i, Op: op.Op, ID: id, Status: "success"})
+		case dto.RecordMutationUpdate:
+			record, ok := m.records[op.ID]
+			if !ok {
+				resp.Results = append(resp.Results, &dto.RecordMutationResult{Index: i, Op: op.Op, ID: op.ID, Status: "failed", Error: &dto.RecordMutationError{
+					Code: pkgerrors.ErrNotFound.Code, Message: "记录不存在",
+				}})
+				continue
+			}
+			for key, value := range op.Fields {
+				record[key] = value
+			}
+			resp.Results = append(resp.Results, &dto.RecordMutationResult{Index: i, Op: op.Op, ID: op.ID, Status: "success"})
+		}
+	}
+	return resp, nil
+}
+
+func (m *stubSyncedMirror) CreateField(_ context.Context, req dto.CreateFieldRequest, _ string) (*dto.FieldResponse, error) {
+	fn, _ := fieldVO.NewFieldName(req.Name)
+	ft, _ := fieldVO.NewFieldType(req.Type)
+	field, err := fieldEntity.NewField(req.TableID, fn, ft, "usr1")
+	if err != nil {
+		return nil, err
+	}
+	m.fields.tables[req.TableID] = append(m.fields.tables[req.TableID], field)
+	return &dto.FieldResponse{ID: field.ID().String(), TableID: req.TableID, Name: req.Name, Type: req.Type}, nil
+}
+
+type syncedTableFixture struct {
+	service *SyncedTableService
+	repo    *memorySyncedTableRepo
+	feed    *stubSyncedChangeFeed
+	records *stubRecordRepo
+	mirror  *stubSyncedMirror
+	sync    *syncedtable.Sync
+	fields  map[string]*fieldEntity.Field
+	visible map[string]bool // 满足视图条件的源记录
+}
+
+func newSyncedTableFixture(t *testing.T) *syncedTableFixture {
+	t.Helper()
+	fields := map[string]*fieldEntity.Field{
+		"name":    newTemplateTestField(t, "名称", fieldVO.TypeSingleLineText),
+		"qty":     newTemplateTestField(t, "数量", fieldVO.TypeNumber),
+		"secret":  newTemplateTestField(t, "内部备注", fieldVO.TypeLongText),
+		"formula": newTemplateTestField(t, "合计", fieldVO.TypeFormula),
+	}
+	vt, _ := viewVO.NewViewType("grid")
+	view, err := viewEntity.NewView("tbl1", "进行中", vt, "usr1")
+	if err != nil {
+		t.Fatalf("创建视图失败: %v", err)
+	}
+	if err := view.UpdateColumnMeta(&viewVO.ColumnMetaList{Columns: []viewVO.ColumnMeta{{FieldID: fields["secret"].ID().String(), Visible: false}}}); err != nil {
+		t.Fatalf("设置列失败: %v", err)
+	}
+	sourceName, _ := tableVO.NewTableName("订单")
+	source, _ := tableEntity.NewTable("bseA", sourceName, "usr1")
+	mirrorName, _ := tableVO.NewTableName("订单（同步）")
+	mirror, _ := tableEntity.NewTable("bseB", mirrorName, "usr1")
+
+	fieldRepository := &stubSyncedFieldRepo{tables: map[string][]*fieldEntity.Field{
+		"tbl1": {fields["name"], fields["qty"], fields["secret"], fields["formula"]},
+	}}
+	fx := &syncedTableFixture{
+		repo:    &memorySyncedTableRepo{syncs: map[string]*syncedtable.Sync{}, rows: map[string]*syncedtable.Row{}},
+		feed:    &stubSyncedChangeFeed{},
+		records: &stubRecordRepo{tables: map[string][]*recordEntity.Record{}},
+		mirror:  &stubSyncedMirror{fields: fieldRepository, records: map[string]map[string]interface{}{}},
+		fields:  fields,
+		visible: map[string]bool{},
+	}
+	tables := &stubTableRepo{tables: map[string]*tableEntity.Table{"tbl1": source, "tblMirror": mirror}}
+	fx.service = NewSyncedTableService(fx.repo, &ChangeFeedService{repo: fx.feed}, tables, nil, fieldRepository,
+		&stubSyncedViewRepo{views: map[string]*viewEntity.View{"viw1": view}}, fx.records,
+		nil, fx.mirror, fx.mirror, nil, nil, nil, nil, config.SyncedTableConfig{BatchSize: 2})
+	fx.service.selectIDs = func(_ context.Context, _ *syncedTableSource, tableID string, ids []string, afterID string, limit int) ([]string, error) {
+		var result []string
+		for _, record := range fx.records.tables[tableID] {
+			id := record.ID().String()
+			if !fx.visible[id] {
+				continue
+			}
+			if len(ids) > 0 {
+				for _, want := range ids {
+					if want == id {
+						result = append(result, id)
+					}
+				}
+			} else if id > afterID {
+				result = append(result, id)
+			}
+		}
+		sort.Strings(result)
+		if len(ids) == 0 && len(result) > limit {
+			result = result[:limit]
+		}
+		return result, nil
+	}
+
+	fx.sync = syncedtable.NewSync("订单（同步）", "bseA", "tbl1", "viw1", "bseB", time.Minute, "usr1")
+	fx.sync.TargetTableID = "tblMirror"
+	fx.repo.syncs[fx.sync.ID] = fx.sync
+	return fx
+}
+
+func (fx *syncedTableFixture) put(recordID string, name string, qty float64) {
+	fx.records.put("tbl1", recordID, map[string]interface{}{
+		fx.fields["name"].ID().String():   name,
+		fx.fields["qty"].ID().String():    qty,
+		fx.fields["secret"].ID().String(): "不应同步",
+	})
+	fx.visible[recordID] = true
+}
+
+func (fx *syncedTableFixture) remove(recordID string) {
+	list := fx.records.tables["tbl1"]
+	for i, record := range list {
+		if record.ID().String() == recordID {
+			fx.records.tables["tbl1"] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	delete(fx.visible, recordID)
+}
+
+func (fx *syncedTableFixture) change(seq int64, recordID string, action changefeed.Action) {
+	fx.feed.changes = append(fx.feed.changes, &changefeed.Change{
+		Seq: seq, BaseID: "bseA", TableID: "tbl1", EntityType: changefeed.EntityRecord, Action: action, EntityID: recordID,
+	})
+}
+
+// mirrored 源记录对应的镜像记录值
+func (fx *syncedTableFixture) mirrored(t *testing.T, recordID string) map[string]interface{} {
+	t.Helper()
+	row := fx.repo.rows[recordID]
+	if row == nil {
+		t.Fatalf("记录 %s 没有镜像", recordID)
+	}
+	return fx.mirror.records[row.TargetRecordID]
+}
+
+func (fx *syncedTableFixture) value(t *testing.T, recordID, key string) interface{} {
+	return fx.mirrored(t, recordID)[fx.sync.FieldMap[fx.fields[key].ID().String()]]
+}
+
+func (fx *syncedTableFixture) deleted(t *testing.T, recordID string) interface{} {
+	return fx.mirrored(t, recordID)[fx.sync.DeletedFieldID]
+}
+
+func TestSyncedTableFullSync(t *testing.T) {
+	fx := newSyncedTableFixture(t)
+	fx.put("rec1", "苹果", 3)
+	fx.put("rec2", "香蕉", 5)
+	fx.put("rec3", "橙子", 7)
+	fx.change(4, "rec3", changefeed.ActionCreate)
+
+	fx.service.runSync(context.Background(), fx.sync)
+	if fx.sync.LastStatus != syncedtable.RunSucceeded {
+		t.Fatalf("同步失败: %s", fx.sync.LastError)
+	}
+	if fx.sync.Cursor != 4 {
+		t.Errorf("全量同步后游标应为变更流头部 4, 实际 %d", fx.sync.Cursor)
+	}
+	// 隐藏列与计算字段不镜像，另建删除标记字段
+	if len(fx.sync.FieldMap) != 2 || fx.sync.DeletedFieldID == "" {
+		t.Fatalf("字段对应关系不正确: %v, 删除标记 %q", fx.sync.FieldMap, fx.sync.DeletedFieldID)
+	}
+	if len(fx.mirror.records) != 3 {
+		t.Fatalf("应镜像 3 条记录, 实际 %d", len(fx.mirror.records))
+	}
+	if got := fx.value(t, "rec2", "name"); got != "香蕉" {
+		t.Errorf("镜像值不正确: %v", got)
+	}
+	if got := fx.deleted(t, "rec1"); got != false {
+		t.Errorf("新镜像记录不应标记删除: %v", got)
+	}
+	for _, values := range fx.mirror.records {
+		for _, value := range values {
+			if value == "不应同步" {
+				t.Fatal("隐藏列的值被同步到镜像表")
+			}
+		}
+	}
+}
+
+func TestSyncedTableIncrementalMarksRemoved(t *testing.T) {
+	fx := newSyncedTableFixture(t)
+	fx.put("rec1", "苹果", 3)
+	fx.put("rec2", "香蕉", 5)
+	fx.change(1, "rec2", changefeed.ActionCreate)
+	fx.service.runSync(context.Background(), fx.sync)
+
+	// rec1 修改、rec2 删除、rec3 新建、rec4 不满足视图条件
+	fx.put("rec1", "青苹果", 4)
+	fx.remove("rec2")
+	fx.put("rec3", "橙子", 7)
+	fx.put("rec4", "葡萄", 1)
+	fx.visible["rec4"] = false
+	fx.change(2, "rec1", changefeed.ActionUpdate)
+	fx.change(3, "rec2", changefeed.ActionDelete)
+	fx.change(4, "rec3", changefeed.ActionCreate)
+	fx.change(5, "rec4", changefeed.ActionCreate)
+	writes := fx.mirror.writes
+
+	fx.service.runSync(context.Background(), fx.sync)
+	if fx.sync.LastStatus != syncedtable.RunSucceeded {
+		t.Fatalf("同步失败: %s", fx.sync.LastError)
+	}
+	if fx.sync.Cursor != 5 {
+		t.Errorf("游标应推进到 5, 实际 %d", fx.sync.Cursor)
+	}
+	if got := fx.mirror.writes - writes; got != 3 {
+		t.Errorf("增量同步应只写入 3 条镜像记录, 实际 %d", got)
+	}
+	if got := fx.value(t, "rec1", "name"); got != "青苹果" {
+		t.Errorf("修改未同步: %v", got)
+	}
+	if got := fx.deleted(t, "rec2"); got != true {
+		t.Errorf("删除的源记录应保留镜像并标记删除: %v", got)
+	}
+	if !fx.repo.rows["rec2"].SourceDeleted {
+		t.Error("对应关系应标记源记录已删除")
+	}
+	if got := fx.value(t, "rec3", "qty"); got != 7.0 {
+		t.Errorf("新记录未同步: %v", got)
+	}
+	if _, ok := fx.repo.rows["rec4"]; ok {
+		t.Error("不满足视图条件的记录不应镜像")
+	}
+
+	// 记录重新满足视图条件时取消删除标记
+	fx.put("rec2", "香蕉", 6)
+	fx.change(6, "rec2", changefeed.ActionCreate)
+	fx.service.runSync(context.Background(), fx.sync)
+	if got := fx.deleted(t, "rec2"); got != false {
+		t.Errorf("重新出现的记录应取消删除标记: %v", got)
+	}
+}
+
+func TestSyncedTableFullResyncMarksStaleAndRecreates(t *testing.T) {
+	fx := newSyncedTableFixture(t)
+	fx.put("rec1", "苹果", 3)
+	fx.put("rec2", "香蕉", 5)
+	fx.put("rec3", "橙子", 7)
+	fx.change(1, "rec3", changefeed.ActionCreate)
+	fx.service.runSync(context.Background(), fx.sync)
+
+	// 变更流游标过期：改为全量同步；镜像记录被删除时重新创建
+	fx.feed.expired = 10
+	fx.change(11, "rec9", changefeed.ActionCreate)
+	fx.remove("rec1")
+	delete(fx.mirror.records, fx.repo.rows["rec3"].TargetRecordID)
+	time.Sleep(time.Millisecond)
+
+	fx.service.runSync(context.Background(), fx.sync)
+	if fx.sync.LastStatus != syncedtable.RunSucceeded {
+		t.Fatalf("同步失败: %s", fx.sync.LastError)
+	}
+	if fx.sync.Cursor != 11 {
+		t.Errorf("全量同步后游标应为 11, 实际 %d", fx.sync.Cursor)
+	}
+	if got := fx.deleted(t, "rec1"); got != true {
+		t.Errorf("全量同步未写入的记录应标记删除: %v", got)
+	}
+	if got := fx.value(t, "rec3", "name"); got != "橙子" {
+		t.Errorf("丢失的镜像记录应重新创建: %v", got)
+	}
+}
+
+func TestSyncedTableMirrorIsReadOnly(t *testing.T) {
+	fx := newSyncedTableFixture(t)
+	ctx := context.Background()
+
+	err := fx.service.CheckWritable(ctx, "tblMirror")
+	if appErr, ok := pkgerrors.IsAppError(err); !ok || appErr.Code != pkgerrors.ErrForbidden.Code {
+		t.Fatalf("镜像表应拒绝写入, 实际 %v", err)
+	}
+	if err := fx.service.CheckWritable(withSyncedTableWrite(ctx), "tblMirror"); err != nil {
+		t.Errorf("同步写入应被允许: %v", err)
+	}
+	if err := fx.service.CheckWritable(ctx, "tbl1"); err != nil {
+		t.Errorf("普通表应允许写入: %v", err)
+	}
+}
@@ -0,0 +1,894 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/tablehealth"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var tableHealthLog = logger.Named("table_health")
+
+const (
+	// tableHealthReportListLimit 报告列表返回条数
+	tableHealthReportListLimit = 50
+	// tableHealthIssueDefaultLimit 问题列表默认每页条数
+	tableHealthIssueDefaultLimit = 100
+	// tableHealthIssueMaxLimit 问题列表每页最大条数
+	tableHealthIssueMaxLimit = 500
+	// tableHealthClaimBatch 每次领取的检查、修复任务与定时设置数
+	tableHealthClaimBatch = 5
+	// tableHealthDefaultInterval 未设置定时检查时返回的默认间隔（小时）
+	tableHealthDefaultInterval = 24
+)
+
+// tableHealthRecordWriter 写入修复后的值（由 RecordService 实现，写入后照常推送事件并重算依赖字段）
+type tableHealthRecordWriter interface {
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// CreateTableHealthFixRequest 创建一键修复任务请求
+// IssueIDs 为空时修复报告中全部待修复的问题（Kinds 非空时只修复这些类型）
+type CreateTableHealthFixRequest struct {
+	Kinds    []string `json:"kinds"`
+	IssueIDs []string `json:"issueIds"`
+}
+
+// UpdateTableHealthScheduleRequest 更新定时检查设置请求
+type UpdateTableHealthScheduleRequest struct {
+	IntervalHours int  `json:"intervalHours" binding:"required"`
+	Enabled       bool `json:"enabled"`
+}
+
+// TableHealthIssueList 问题分页列表
+type TableHealthIssueList struct {
+	Issues []*tablehealth.Issue `json:"issues"`
+	Total  int64                `json:"total"`
+}
+
+// TableHealthService 表健康检查服务 ✨
+// 扫描表中的记录，报告四类数据问题：
+//   - validation：值不符合字段约束，如必填字段为空、日期或数字格式错误
+//   - orphaned_link：关联到已删除的记录，或关联的表已不存在
+//   - formula_error：公式字段无法计算，或单元格中保存了计算错误值
+//   - type_coercion：字段类型变更后遗留的值，如数字字段中的文本 "12"，可转换为有效值
+//
+// 检查可手动发起或按表设置定时执行，由后台逐页扫描；单元格级的失效关联与类型转换遗留值可一键修复。
+// 修复任务逐条重新读取当前值，仍有问题时按读取时的版本写入，期间被修改的记录跳过，不会覆盖他人的编辑。
+// 报告中包含单元格值，查看与操作都需要表结构管理权限；加密字段不参与检查
+type TableHealthService struct {
+	repo              tablehealth.Repository
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           tableHealthRecordWriter
+	permissionService *PermissionServiceV2
+	validators        *validation.ValidatorFactory
+	cfg               config.TableHealthConfig
+	now               func() time.Time
+	wake              chan struct{}
+}
+
+// NewTableHealthService 创建表健康检查服务
+func NewTableHealthService(
+	repo tablehealth.Repository,
+	tableRepository tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	recordRepository recordRepo.RecordRepository,
+	records tableHealthRecordWriter,
+	permissionService *PermissionServiceV2,
+	cfg config.TableHealthConfig,
+) *TableHealthService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 50000
+	}
+	if cfg.MaxIssues <= 0 {
+		cfg.MaxIssues = 5000
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 10 * time.Minute
+	}
+	if cfg.ReportHistory <= 0 {
+		cfg.ReportHistory = 20
+	}
+	return &TableHealthService{
+		repo:              repo,
+		tableRepo:         tableRepository,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepository,
+		records:           records,
+		permissionService: permissionService,
+		validators:        validation.NewValidatorFactory(),
+		cfg:               cfg,
+		now:               time.Now,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// Notify 通知有新的检查或修复任务待执行
+func (s *TableHealthService) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台检查、修复与定时检查
+func (s *TableHealthService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.scheduleDue(ctx)
+			for s.processReports(ctx) {
+			}
+			for s.processFixJobs(ctx) {
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// RunCheck 发起检查；表中已有未结束的检查时直接返回该检查
+func (s *TableHealthService) RunCheck(ctx context.Context, userID, tableID string) (*tablehealth.Report, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	if err := s.table(ctx, tableID); err != nil {
+		return nil, err
+	}
+	active, err := s.repo.ActiveReport(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询健康检查失败")
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	report := tablehealth.NewReport(tableID, tablehealth.TriggerManual, userID, s.now())
+	if err := s.repo.SaveReport(ctx, report); err != nil {
+		return nil, pkgerrors.Database(err, "创建健康检查失败")
+	}
+	s.Notify()
+	return report, nil
+}
+
+// ListReports 列出表最近的检查报告
+func (s *TableHealthService) ListReports(ctx context.Context, userID, tableID string) ([]*tablehealth.Report, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	reports, err := s.repo.ListReports(ctx, tableID, tableHealthReportListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询健康检查报告失败")
+	}
+	return reports, nil
+}
+
+// GetReport 获取检查报告
+func (s *TableHealthService) GetReport(ctx context.Context, userID, reportID string) (*tablehealth.Report, error) {
+	return s.authorizedReport(ctx, userID, reportID)
+}
+
+// ListIssues 分页列出报告中的问题
+func (s *TableHealthService) ListIssues(ctx context.Context, userID, reportID string, filter tablehealth.IssueFilter) (*TableHealthIssueList, error) {
+	if _, err := s.authorizedReport(ctx, userID, reportID); err != nil {
+		return nil, err
+	}
+	if filter.Kind != "" && !filter.Kind.Valid() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("未知的问题类型: " + string(filter.Kind))
+	}
+	filter.ReportID = reportID
+	if filter.Limit <= 0 {
+		filter.Limit = tableHealthIssueDefaultLimit
+	}
+	if filter.Limit > tableHealthIssueMaxLimit {
+		filter.Limit = tableHealthIssueMaxLimit
+	}
+	issues, total, err := s.repo.ListIssues(ctx, filter)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询健康检查问题失败")
+	}
+	return &TableHealthIssueList{Issues: issues, Total: total}, nil
+}
+
+// CreateFixJob 为已完成的报告创建一键修复任务
+// 只修复可自动修复的问题；未指定问题时一次最多包含 MaxFixIssues 个，其余可再次创建任务修复
+func (s *TableHealthService) CreateFixJob(ctx context.Context, userID, reportID string, req CreateTableHealthFixRequest) (*tablehealth.FixJob, error) {
+	report, err := s.authorizedReport(ctx, userID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != tablehealth.StatusCompleted {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("检查完成后才能修复")
+	}
+
+	kinds := make([]tablehealth.IssueKind, 0, len(req.Kinds))
+	allowed := make(map[tablehealth.IssueKind]bool, len(req.Kinds))
+	for _, value := range req.Kinds {
+		kind := tablehealth.IssueKind(value)
+		if !kind.Valid() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("未知的问题类型: " + value)
+		}
+		if !kind.Fixable() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("%s 类型的问题不能自动修复", value))
+		}
+		kinds = append(kinds, kind)
+		allowed[kind] = true
+	}
+	if len(req.IssueIDs) > tablehealth.MaxFixIssues {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多修复 %d 个问题", tablehealth.MaxFixIssues))
+	}
+
+	active, err := s.repo.ActiveFixJob(ctx, reportID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询修复任务失败")
+	}
+	if active != nil {
+		return nil, pkgerrors.ErrConflict.WithDetails("该报告已有进行中的修复任务")
+	}
+
+	var ids []string
+	if len(req.IssueIDs) == 0 {
+		ids, err = s.repo.OpenFixableIssueIDs(ctx, reportID, kinds, tablehealth.MaxFixIssues)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询待修复的问题失败")
+		}
+	} else {
+		issues, err := s.repo.GetIssues(ctx, reportID, req.IssueIDs)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询待修复的问题失败")
+		}
+		for _, issue := range issues {
+			if issue.Fixable && issue.Status == tablehealth.IssueOpen && (len(allowed) == 0 || allowed[issue.Kind]) {
+				ids = append(ids, issue.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("没有可自动修复的问题")
+	}
+
+	job := tablehealth.NewFixJob(report, ids, userID, s.now())
+	if err := s.repo.SaveFixJob(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "创建修复任务失败")
+	}
+	s.Notify()
+	return job, nil
+}
+
+// GetFixJob 获取修复任务
+func (s *TableHealthService) GetFixJob(ctx context.Context, userID, jobID string) (*tablehealth.FixJob, error) {
+	job, err := s.repo.GetFixJob(ctx, jobID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找修复任务失败")
+	}
+	if job == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("修复任务不存在")
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, job.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	return job, nil
+}
+
+// GetSchedule 获取表的定时检查设置（未设置时返回停用的默认设置）
+func (s *TableHealthService) GetSchedule(ctx context.Context, userID, tableID string) (*tablehealth.Schedule, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	schedule, err := s.repo.GetSchedule(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询定时检查设置失败")
+	}
+	if schedule == nil {
+		schedule = &tablehealth.Schedule{TableID: tableID, IntervalHours: tableHealthDefaultInterval}
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule 更新定时检查设置，下次检查从现在起计算
+func (s *TableHealthService) UpdateSchedule(ctx context.Context, userID, tableID string, req UpdateTableHealthScheduleRequest) (*tablehealth.Schedule, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	if err := s.table(ctx, tableID); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	schedule := &tablehealth.Schedule{
+		TableID:       tableID,
+		IntervalHours: req.IntervalHours,
+		Enabled:       req.Enabled,
+		UpdatedBy:     userID,
+		UpdatedAt:     now,
+	}
+	if err := schedule.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	schedule.Advance(now)
+	if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+		return nil, pkgerrors.Database(err, "保存定时检查设置失败")
+	}
+	return schedule, nil
+}
+
+// scheduleDue 为到期的定时设置创建检查（表中已有未结束的检查时跳过本次）
+func (s *TableHealthService) scheduleDue(ctx context.Context) {
+	now := s.now()
+	schedules, err := s.repo.ClaimDueSchedules(ctx, now, tableHealthClaimBatch)
+	if err != nil {
+		tableHealthLog.Warn(ctx, "领取定时健康检查失败", logger.ErrorField(err))
+		return
+	}
+	for _, schedule := range schedules {
+		table, err := s.tableRepo.GetByID(ctx, schedule.TableID)
+		if err != nil {
+			tableHealthLog.Warn(ctx, "查找表失败", logger.String("table_id", schedule.TableID), logger.ErrorField(err))
+			continue
+		}
+		if table == nil {
+			// 表已删除：停用定时检查
+			schedule.Enabled = false
+			schedule.Advance(now)
+			if err := s.repo.SaveSchedule(ctx, schedule); err != nil {
+				tableHealthLog.Warn(ctx, "停用定时健康检查失败", logger.String("table_id", schedule.TableID), logger.ErrorField(err))
+			}
+			continue
+		}
+		active, err := s.repo.ActiveReport(ctx, schedule.TableID)
+		if err != nil {
+			tableHealthLog.Warn(ctx, "查询健康检查失败", logger.String("table_id", schedule.TableID), logger.ErrorField(err))
+			continue
+		}
+		if active != nil {
+			continue
+		}
+		report := tablehealth.NewReport(schedule.TableID, tablehealth.TriggerScheduled, schedule.UpdatedBy, now)
+		if err := s.repo.SaveReport(ctx, report); err != nil {
+			tableHealthLog.Warn(ctx, "创建定时健康检查失败", logger.String("table_id", schedule.TableID), logger.ErrorField(err))
+		}
+	}
+}
+
+// processReports 领取并执行一批检查，返回是否领取到检查
+func (s *TableHealthService) processReports(ctx context.Context) bool {
+	now := s.now()
+	reports, err := s.repo.ClaimReports(ctx, now, now.Add(-s.cfg.LeaseDuration), tableHealthClaimBatch)
+	if err != nil {
+		tableHealthLog.Warn(ctx, "领取健康检查失败", logger.ErrorField(err))
+		return false
+	}
+	for _, report := range reports {
+		s.runReport(ctx, report)
+	}
+	return len(reports) > 0
+}
+
+// processFixJobs 领取并执行一批修复任务，返回是否领取到任务
+func (s *TableHealthService) processFixJobs(ctx context.Context) bool {
+	now := s.now()
+	jobs, err := s.repo.ClaimFixJobs(ctx, now, now.Add(-s.cfg.LeaseDuration), tableHealthClaimBatch)
+	if err != nil {
+		tableHealthLog.Warn(ctx, "领取修复任务失败", logger.ErrorField(err))
+		return false
+	}
+	for _, job := range jobs {
+		s.runFixJob(ctx, job)
+	}
+	return len(jobs) > 0
+}
+
+// runReport 执行检查并保存结果（重新领取的检查从头开始）
+func (s *TableHealthService) runReport(ctx context.Context, report *tablehealth.Report) {
+	report.Reset()
+	if err := s.repo.DeleteIssues(ctx, report.ID); err != nil {
+		tableHealthLog.Warn(ctx, "清理上次检查的问题失败", logger.String("report_id", report.ID), logger.ErrorField(err))
+		return
+	}
+
+	err := s.scan(ctx, report)
+	if err != nil {
+		tableHealthLog.Warn(ctx, "健康检查失败", logger.String("report_id", report.ID), logger.ErrorField(err))
+	}
+	report.Finish(err, s.now())
+	if err := s.repo.SaveReport(ctx, report); err != nil {
+		tableHealthLog.Warn(ctx, "保存健康检查报告失败", logger.String("report_id", report.ID), logger.ErrorField(err))
+		return
+	}
+	if err := s.repo.PurgeReports(ctx, report.TableID, s.cfg.ReportHistory); err != nil {
+		tableHealthLog.Warn(ctx, "清理历史健康检查报告失败", logger.String("table_id", report.TableID), logger.ErrorField(err))
+	}
+}
+
+// tableHealthScan 一次检查的进度：问题先计入统计，超过保存上限的问题只统计不保存
+type tableHealthScan struct {
+	report  *tablehealth.Report
+	pending []*tablehealth.Issue
+	stored  int
+	limit   int
+}
+
+func (sc *tableHealthScan) add(issue *tablehealth.Issue) {
+	sc.report.Count(issue)
+	if sc.stored+len(sc.pending) >= sc.limit {
+		sc.report.Truncated = true
+		return
+	}
+	sc.pending = append(sc.pending, issue)
+}
+
+func (sc *tableHealthScan) flush(ctx context.Context, repo tablehealth.Repository) error {
+	if err := repo.SaveIssues(ctx, sc.pending); err != nil {
+		return err
+	}
+	sc.stored += len(sc.pending)
+	sc.pending = nil
+	return nil
+}
+
+// tableHealthLinkCell 待核对的关联单元格
+type tableHealthLinkCell struct {
+	recordID string
+	value    interface{}
+	ids      []string
+}
+
+// scan 检查字段定义并逐页检查记录；每页结束时保存问题并更新报告作为心跳
+func (s *TableHealthService) scan(ctx context.Context, report *tablehealth.Report) error {
+	table, err := s.tableRepo.GetByID(ctx, report.TableID)
+	if err != nil {
+		return err
+	}
+	if table == nil {
+		return errors.New("表不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, report.TableID)
+	if err != nil {
+		return err
+	}
+
+	sc := &tableHealthScan{report: report, limit: s.cfg.MaxIssues}
+	checked := make([]*fieldEntity.Field, 0, len(fields))
+	for _, field := range fields {
+		if field.IsDeleted() || field.IsEncrypted() {
+			continue
+		}
+		switch field.Type().String() {
+		case fieldVO.TypeFormula:
+			if field.HasError() {
+				sc.add(tablehealth.NewIssue(report, tablehealth.KindFormulaError, field.ID().String(), field.Name().String(), "", nil, "公式无法计算，请检查公式及其引用的字段"))
+				continue
+			}
+		case fieldVO.TypeLink:
+			linkedTableID := healthLinkedTableID(field)
+			if linkedTableID == "" {
+				continue
+			}
+			linked, err := s.tableRepo.GetByID(ctx, linkedTableID)
+			if err != nil {
+				return err
+			}
+			if linked == nil {
+				sc.add(tablehealth.NewIssue(report, tablehealth.KindOrphanedLink, field.ID().String(), field.Name().String(), "", linkedTableID, "关联的表已不存在"))
+				continue
+			}
+		}
+		checked = append(checked, field)
+	}
+
+	for offset := 0; offset < s.cfg.MaxRecords; offset += s.cfg.BatchSize {
+		limit := s.cfg.BatchSize
+		if offset+limit > s.cfg.MaxRecords {
+			limit = s.cfg.MaxRecords - offset
+		}
+		records, total, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{
+			TableID: &report.TableID,
+			OrderBy: "__id",
+			Limit:   limit,
+			Offset:  offset,
+		})
+		if err != nil {
+			return err
+		}
+		if total > int64(s.cfg.MaxRecords) {
+			report.Truncated = true
+		}
+		if err := s.checkRecords(ctx, sc, checked, records); err != nil {
+			return err
+		}
+		report.Scanned += len(records)
+		if err := sc.flush(ctx, s.repo); err != nil {
+			return err
+		}
+		report.UpdatedAt = s.now()
+		if err := s.repo.SaveReport(ctx, report); err != nil {
+			return err
+		}
+		if len(records) < limit {
+			break
+		}
+	}
+	return sc.flush(ctx, s.repo)
+}
+
+// checkRecords 检查一页记录；关联字段按页批量核对关联的记录是否存在
+func (s *TableHealthService) checkRecords(ctx context.Context, sc *tableHealthScan, fields []*fieldEntity.Field, records []*recordEntity.Record) error {
+	links := make(map[*fieldEntity.Field][]tableHealthLinkCell)
+	for _, record := range records {
+		recordID := record.ID().String()
+		for _, field := range fields {
+			fieldID := field.ID().String()
+			value, _ := record.Data().Get(fieldID)
+			switch {
+			case field.Type().String() == fieldVO.TypeFormula:
+				if message, ok := formulaErrorValue(value); ok {
+					sc.add(tablehealth.NewIssue(sc.report, tablehealth.KindFormulaError, fieldID, field.Name().String(), recordID, value, message))
+				}
+			case field.Type().String() == fieldVO.TypeLink:
+				if ids := extractLinkIDs(value); len(ids) > 0 {
+					links[field] = append(links[field], tableHealthLinkCell{recordID: recordID, value: value, ids: ids})
+				}
+			case field.IsComputed() || field.IsVirtual():
+			default:
+				if kind, message := s.inspect(ctx, field, value); kind != "" {
+					sc.add(tablehealth.NewIssue(sc.report, kind, fieldID, field.Name().String(), recordID, value, message))
+				}
+			}
+		}
+	}
+
+	for field, cells := range links {
+		var ids []string
+		for _, cell := range cells {
+			ids = append(ids, cell.ids...)
+		}
+		missing, err := s.missingLinks(ctx, field, ids)
+		if err != nil {
+			return err
+		}
+		for _, cell := range cells {
+			var dangling []string
+			for _, id := range cell.ids {
+				if missing[id] {
+					dangling = append(dangling, id)
+				}
+			}
+			if len(dangling) > 0 {
+				sc.add(tablehealth.NewIssue(sc.report, tablehealth.KindOrphanedLink, field.ID().String(), field.Name().String(), cell.recordID, dangling,
+					fmt.Sprintf("关联的 %d 条记录已不存在", len(dangling))))
+			}
+		}
+	}
+	return nil
+}
+
+// inspect 检查单元格值，返回问题类型与说明（无问题时类型为空）
+func (s *TableHealthService) inspect(ctx context.Context, field *fieldEntity.Field, value interface{}) (tablehealth.IssueKind, string) {
+	if isEmptyHealthValue(value) {
+		if field.IsRequired() && field.Type().String() != fieldVO.TypeCheckbox {
+			return tablehealth.KindValidation, "必填字段为空"
+		}
+		return "", ""
+	}
+	if _, err := s.validators.GetValidator(field.Type()); err != nil {
+		return "", ""
+	}
+	if _, ok := s.coerce(ctx, field, value); ok {
+		return tablehealth.KindTypeCoercion, "值的类型与字段类型不符，可转换为有效值"
+	}
+	if result := s.validators.ValidateField(ctx, value, field); !result.Success {
+		return tablehealth.KindValidation, result.Error.Error()
+	}
+	return "", ""
+}
+
+// coerce 计算类型转换遗留值的修复值：
+// 值是与字段存储类型不同的标量（如数字字段中的文本 "12"、文本字段中的数字 12），且能转换为有效值时返回转换后的值。
+// 复选框的转换会把无法识别的值变为 false，不视为可修复
+func (s *TableHealthService) coerce(ctx context.Context, field *fieldEntity.Field, value interface{}) (interface{}, bool) {
+	kind := healthValueKind(value)
+	if kind == "" || kind == "array" {
+		return nil, false
+	}
+	if _, err := s.validators.GetValidator(field.Type()); err != nil {
+		return nil, false
+	}
+
+	normalized := s.validators.ValidateField(ctx, value, field)
+	if !normalized.Success {
+		if field.Type().String() == fieldVO.TypeCheckbox {
+			return nil, false
+		}
+		repaired := s.validators.RepairValue(ctx, value, field)
+		if repaired == nil {
+			return nil, false
+		}
+		normalized = s.validators.ValidateField(ctx, repaired, field)
+		if !normalized.Success {
+			return nil, false
+		}
+	}
+	converted := healthValueKind(normalized.Value)
+	if converted == "" || converted == kind {
+		return nil, false
+	}
+	return normalized.Value, true
+}
+
+// missingLinks 返回 ids 中在关联表里已不存在的记录ID
+func (s *TableHealthService) missingLinks(ctx context.Context, field *fieldEntity.Field, ids []string) (map[string]bool, error) {
+	linkedTableID := healthLinkedTableID(field)
+	if linkedTableID == "" || len(ids) == 0 {
+		return nil, nil
+	}
+	unique := make(map[string]bool, len(ids))
+	recordIDs := make([]recordVO.RecordID, 0, len(ids))
+	for _, id := range ids {
+		if !unique[id] {
+			unique[id] = true
+			recordIDs = append(recordIDs, recordVO.NewRecordID(id))
+		}
+	}
+	existing, err := s.recordRepo.FindByIDs(ctx, linkedTableID, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range existing {
+		delete(unique, record.ID().String())
+	}
+	return unique, nil
+}
+
+// runFixJob 执行修复任务（创建人已无表结构管理权限时任务失败）
+func (s *TableHealthService) runFixJob(ctx context.Context, job *tablehealth.FixJob) {
+	var err error
+	if !s.permissionService.CanManageTableSchema(ctx, job.CreatedBy, job.TableID) {
+		err = errors.New("创建人已没有管理该表的权限")
+	} else {
+		err = s.applyFixes(ctx, job)
+	}
+	if err != nil {
+		tableHealthLog.Warn(ctx, "修复任务失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+	job.Finish(err, s.now())
+	if err := s.repo.SaveFixJob(ctx, job); err != nil {
+		tableHealthLog.Warn(ctx, "保存修复任务失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+}
+
+// applyFixes 逐批修复问题；已处理过的问题（任务被重新领取时）跳过
+func (s *TableHealthService) applyFixes(ctx context.Context, job *tablehealth.FixJob) error {
+	fields, err := s.fieldRepo.FindByTableID(ctx, job.TableID)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		if !field.IsDeleted() {
+			byID[field.ID().String()] = field
+		}
+	}
+
+	for start := 0; start < len(job.IssueIDs); start += s.cfg.BatchSize {
+		end := start + s.cfg.BatchSize
+		if end > len(job.IssueIDs) {
+			end = len(job.IssueIDs)
+		}
+		issues, err := s.repo.GetIssues(ctx, job.ReportID, job.IssueIDs[start:end])
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if issue.Status != tablehealth.IssueOpen {
+				continue
+			}
+			status, fixErr := s.fixIssue(ctx, job, byID[issue.FieldID], issue)
+			issue.Status = status
+			issue.FixError = ""
+			if fixErr != nil {
+				issue.FixError = fixErr.Error()
+			}
+			if err := s.repo.UpdateIssue(ctx, issue); err != nil {
+				return err
+			}
+			job.Record(status)
+		}
+		job.UpdatedAt = s.now()
+		if err := s.repo.SaveFixJob(ctx, job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixIssue 重新读取当前值并修复单个问题；值已无问题或记录在此期间被修改时跳过
+func (s *TableHealthService) fixIssue(ctx context.Context, job *tablehealth.FixJob, field *fieldEntity.Field, issue *tablehealth.Issue) (tablehealth.IssueStatus, error) {
+	if !issue.Fixable {
+		return tablehealth.IssueSkipped, errors.New("该问题不能自动修复")
+	}
+	if field == nil {
+		return tablehealth.IssueSkipped, errors.New("字段已删除")
+	}
+	record, err := s.recordRepo.FindByTableAndID(ctx, job.TableID, recordVO.NewRecordID(issue.RecordID))
+	if err != nil {
+		return tablehealth.IssueFailed, err
+	}
+	if record == nil {
+		return tablehealth.IssueSkipped, errors.New("记录已删除")
+	}
+	value, _ := record.Data().Get(issue.FieldID)
+
+	var fixed interface{}
+	switch issue.Kind {
+	case tablehealth.KindTypeCoercion:
+		converted, ok := s.coerce(ctx, field, value)
+		if !ok {
+			return tablehealth.IssueSkipped, errors.New("当前值已无需转换")
+		}
+		fixed = converted
+	case tablehealth.KindOrphanedLink:
+		missing, err := s.missingLinks(ctx, field, extractLinkIDs(value))
+		if err != nil {
+			return tablehealth.IssueFailed, err
+		}
+		if len(missing) == 0 {
+			return tablehealth.IssueSkipped, errors.New("关联的记录均已存在")
+		}
+		fixed = dropLinkIDs(value, missing)
+	default:
+		return tablehealth.IssueSkipped, errors.New("该问题不能自动修复")
+	}
+
+	version := int(record.Version().Value())
+	_, err = s.records.UpdateRecord(ctx, job.TableID, issue.RecordID, dto.UpdateRecordRequest{
+		Data:    map[string]interface{}{issue.FieldID: fixed},
+		Version: &version,
+	}, job.CreatedBy)
+	if pkgerrors.Is(err, pkgerrors.ErrVersionConflict) {
+		return tablehealth.IssueSkipped, errors.New("记录已被修改，请重新检查")
+	}
+	if err != nil {
+		return tablehealth.IssueFailed, err
+	}
+	return tablehealth.IssueFixed, nil
+}
+
+// authorizedReport 获取报告并校验表结构管理权限
+func (s *TableHealthService) authorizedReport(ctx context.Context, userID, reportID string) (*tablehealth.Report, error) {
+	report, err := s.repo.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找健康检查报告失败")
+	}
+	if report == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("健康检查报告不存在")
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, report.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表的健康检查")
+	}
+	return report, nil
+}
+
+// table 确认表存在
+func (s *TableHealthService) table(ctx context.Context, tableID string) error {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查找表失败")
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("表不存在")
+	}
+	return nil
+}
+
+// healthLinkedTableID 关联字段指向的表
+func healthLinkedTableID(field *fieldEntity.Field) string {
+	if options := field.Options(); options != nil && options.Link != nil {
+		return options.Link.LinkedTableID
+	}
+	return ""
+}
+
+// formulaErrorValue 单元格中保存的公式计算错误值（以 #ERROR 开头的文本）
+func formulaErrorValue(value interface{}) (string, bool) {
+	str, ok := value.(string)
+	if !ok || !strings.HasPrefix(str, "#ERROR") {
+		return "", false
+	}
+	return "公式计算出错: " + str, true
+}
+
+// isEmptyHealthValue 单元格是否为空
+func isEmptyHealthValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+// healthValueKind 值的 JSON 类型（string / number / bool / array），其他类型返回空
+func healthValueKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, float32, int, int32, int64, json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}, []string:
+		return "array"
+	}
+	return ""
+}
+
+// dropLinkIDs 从关联单元格中移除已不存在的记录，保持原有的值结构
+func dropLinkIDs(value interface{}, missing map[string]bool) interface{} {
+	switch v := value.(type) {
+	case string:
+		if missing[v] {
+			return nil
+		}
+	case map[string]interface{}:
+		if id, _ := v["id"].(string); missing[id] {
+			return nil
+		}
+	case []string:
+		kept := make([]string, 0, len(v))
+		for _, id := range v {
+			if !missing[id] {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	case []interface{}:
+		kept := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if dropLinkIDs(item, missing) != nil {
+				kept = append(kept, item)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	}
+	return value
+}
@@ -0,0 +1,296 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/tablehealth"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryTableHealthRepo 内存实现的健康检查仓储（只实现检查与修复用到的方法）
+type memoryTableHealthRepo struct {
+	tablehealth.Repository
+	reports []*tablehealth.Report
+	issues  []*tablehealth.Issue
+	jobs    []*tablehealth.FixJob
+}
+
+func (r *memoryTableHealthRepo) SaveReport(_ context.Context, report *tablehealth.Report) error {
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *memoryTableHealthRepo) PurgeReports(context.Context, string, int) error { return nil }
+
+func (r *memoryTableHealthRepo) SaveIssues(_ context.Context, issues []*tablehealth.Issue) error {
+	r.issues = append(r.issues, issues...)
+	return nil
+}
+
+func (r *memoryTableHealthRepo) DeleteIssues(_ context.Context, reportID string) error {
+	kept := r.issues[:0]
+	for _, issue := range r.issues {
+		if issue.ReportID != reportID {
+			kept = append(kept, issue)
+		}
+	}
+	r.issues = kept
+	return nil
+}
+
+func (r *memoryTableHealthRepo) GetIssues(_ context.Context, reportID string, ids []string) ([]*tablehealth.Issue, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var list []*tablehealth.Issue
+	for _, issue := range r.issues {
+		if issue.ReportID == reportID && wanted[issue.ID] {
+			list = append(list, issue)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryTableHealthRepo) UpdateIssue(context.Context, *tablehealth.Issue) error { return nil }
+
+func (r *memoryTableHealthRepo) SaveFixJob(_ context.Context, job *tablehealth.FixJob) error {
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func (r *memoryTableHealthRepo) find(kind tablehealth.IssueKind, fieldID, recordID string) *tablehealth.Issue {
+	for _, issue := range r.issues {
+		if issue.Kind == kind && issue.FieldID == fieldID && issue.RecordID == recordID {
+			return issue
+		}
+	}
+	return nil
+}
+
+// recordingHealthWriter 记录修复写入的值；conflicts 中的记录返回版本冲突
+type recordingHealthWriter struct {
+	writes    map[string]map[string]interface{}
+	conflicts map[string]bool
+}
+
+func (w *recordingHealthWriter) UpdateRecord(_ context.Context, _, recordID string, req dto.UpdateRecordRequest, _ string) (*dto.RecordResponse, error) {
+	if w.conflicts[recordID] {
+		return nil, pkgerrors.ErrVersionConflict
+	}
+	if req.Version == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("修复写入必须带版本号")
+	}
+	if w.writes[recordID] == nil {
+		w.writes[recordID] = map[string]interface{}{}
+	}
+	for fieldID, value := range req.Data {
+		w.writes[recordID][fieldID] = value
+	}
+	return &dto.RecordResponse{ID: recordID}, nil
+}
+
+type tableHealthFixture struct {
+	service *TableHealthService
+	repo    *memoryTableHealthRepo
+	records *stubRecordRepo
+	writer  *recordingHealthWriter
+	fields  map[string]*fieldEntity.Field
+}
+
+func newTableHealthFixture(t *testing.T, cfg config.TableHealthConfig) *tableHealthFixture {
+	t.Helper()
+	newTable := func(name string) *tableEntity.Table {
+		tn, _ := tableVO.NewTableName(name)
+		table, err := tableEntity.NewTable("bse1", tn, "usr1")
+		if err != nil {
+			t.Fatalf("创建表失败: %v", err)
+		}
+		return table
+	}
+
+	fields := map[string]*fieldEntity.Field{
+		"qty":     newTestFieldWithOptions(t, "tbl1", "数量", fieldVO.TypeNumber, nil),
+		"name":    newTestFieldWithOptions(t, "tbl1", "名称", fieldVO.TypeText, nil),
+		"done":    newTestFieldWithOptions(t, "tbl1", "完成", fieldVO.TypeCheckbox, nil),
+		"client":  newTestFieldWithOptions(t, "tbl1", "客户", fieldVO.TypeLink, &fieldVO.FieldOptions{Link: &fieldVO.LinkOptions{LinkedTableID: "tbl2"}}),
+		"vendor":  newTestFieldWithOptions(t, "tbl1", "供应商", fieldVO.TypeLink, &fieldVO.FieldOptions{Link: &fieldVO.LinkOptions{LinkedTableID: "tblGone"}}),
+		"ratio":   newTestFieldWithOptions(t, "tbl1", "比率", fieldVO.TypeFormula, nil),
+		"broken":  newTestFieldWithOptions(t, "tbl1", "失效公式", fieldVO.TypeFormula, nil),
+		"created": newTestFieldWithOptions(t, "tbl1", "创建时间", fieldVO.TypeCreatedTime, nil),
+	}
+	if err := fields["qty"].SetRequired(true); err != nil {
+		t.Fatal(err)
+	}
+	fields["broken"].MarkAsError()
+
+	fx := &tableHealthFixture{
+		repo:    &memoryTableHealthRepo{},
+		records: &stubRecordRepo{tables: map[string][]*recordEntity.Record{}},
+		writer:  &recordingHealthWriter{writes: map[string]map[string]interface{}{}, conflicts: map[string]bool{}},
+		fields:  fields,
+	}
+	list := make([]*fieldEntity.Field, 0, len(fields))
+	for _, key := range []string{"qty", "name", "done", "client", "vendor", "ratio", "broken", "created"} {
+		list = append(list, fields[key])
+	}
+	tables := &stubTableRepo{tables: map[string]*tableEntity.Table{"tbl1": newTable("订单"), "tbl2": newTable("客户")}}
+	fx.service = NewTableHealthService(fx.repo, tables, &stubFieldRepo{fields: list}, fx.records, fx.writer, nil, cfg)
+
+	fx.records.put("tbl2", "recA", map[string]interface{}{})
+	fx.put("rec1", map[string]interface{}{"qty": 3.0, "name": "甲", "client": []interface{}{
+		map[string]interface{}{"id": "recA"}, map[string]interface{}{"id": "recX"},
+	}})
+	fx.put("rec2", map[string]interface{}{"qty": "12", "name": 42.0, "vendor": "recV"})
+	fx.put("rec3", map[string]interface{}{"ratio": "#ERROR: division by zero", "created": "not a time"})
+	fx.put("rec4", map[string]interface{}{"qty": "abc", "done": "maybe"})
+	return fx
+}
+
+// put 以字段别名写入记录
+func (fx *tableHealthFixture) put(recordID string, values map[string]interface{}) {
+	data := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		data[fx.id(key)] = value
+	}
+	fx.records.put("tbl1", recordID, data)
+}
+
+func (fx *tableHealthFixture) id(key string) string {
+	return fx.fields[key].ID().String()
+}
+
+func (fx *tableHealthFixture) check(t *testing.T) *tablehealth.Report {
+	t.Helper()
+	report := tablehealth.NewReport("tbl1", tablehealth.TriggerManual, "usr1", time.Now())
+	fx.service.runReport(context.Background(), report)
+	if report.Status != tablehealth.StatusCompleted {
+		t.Fatalf("检查状态 = %s（%s）", report.Status, report.Error)
+	}
+	return report
+}
+
+func TestTableHealthScan(t *testing.T) {
+	fx := newTableHealthFixture(t, config.TableHealthConfig{BatchSize: 3})
+	report := fx.check(t)
+
+	if report.Scanned != 4 || report.Truncated {
+		t.Errorf("扫描 = %d，不完整 = %v", report.Scanned, report.Truncated)
+	}
+	want := map[tablehealth.IssueKind]int{
+		tablehealth.KindFormulaError: 2, // 字段级 + rec3
+		tablehealth.KindOrphanedLink: 2, // 关联表已删除 + rec1 的 recX
+		tablehealth.KindTypeCoercion: 2, // rec2 的 "12" 与 42
+		tablehealth.KindValidation:   3, // rec3 必填为空、rec4 的 "abc" 与 "maybe"
+	}
+	for kind, n := range want {
+		if report.Counts[kind] != n {
+			t.Errorf("%s = %d，期望 %d（%+v）", kind, report.Counts[kind], n, report.Counts)
+		}
+	}
+	if report.Fixable != 3 || len(fx.repo.issues) != report.Total() {
+		t.Errorf("可修复 = %d，保存 %d / %d", report.Fixable, len(fx.repo.issues), report.Total())
+	}
+
+	orphan := fx.repo.find(tablehealth.KindOrphanedLink, fx.id("client"), "rec1")
+	if orphan == nil || !orphan.Fixable {
+		t.Fatalf("失效关联 = %+v", orphan)
+	}
+	if ids, _ := orphan.Value.([]string); len(ids) != 1 || ids[0] != "recX" {
+		t.Errorf("失效的记录 = %v", orphan.Value)
+	}
+	if issue := fx.repo.find(tablehealth.KindOrphanedLink, fx.id("vendor"), ""); issue == nil || issue.Fixable {
+		t.Errorf("关联表已删除应为不可修复的字段级问题: %+v", issue)
+	}
+	if issue := fx.repo.find(tablehealth.KindValidation, fx.id("done"), "rec4"); issue == nil {
+		t.Error("复选框中无法识别的值应报告为校验失败，而不是可修复的转换")
+	}
+	// 计算字段与公式出错字段的单元格不逐个检查
+	for _, issue := range fx.repo.issues {
+		if issue.FieldID == fx.id("created") || (issue.FieldID == fx.id("broken") && issue.RecordID != "") {
+			t.Errorf("不应检查的单元格: %+v", issue)
+		}
+	}
+
+	// 问题数超过上限时只保存前面的问题，统计不变
+	limited := newTableHealthFixture(t, config.TableHealthConfig{MaxIssues: 2})
+	report = limited.check(t)
+	if len(limited.repo.issues) != 2 || !report.Truncated || report.Total() != 9 {
+		t.Errorf("保存 %d 个，不完整 = %v，统计 %d", len(limited.repo.issues), report.Truncated, report.Total())
+	}
+}
+
+func TestTableHealthFix(t *testing.T) {
+	fx := newTableHealthFixture(t, config.TableHealthConfig{BatchSize: 2})
+	report := fx.check(t)
+
+	var ids []string
+	for _, issue := range fx.repo.issues {
+		if issue.Fixable {
+			ids = append(ids, issue.ID)
+		}
+	}
+	// 检查后 rec2 的名称已被改为文本，数量的修复遇到版本冲突
+	fx.put("rec2", map[string]interface{}{"qty": "12", "name": "乙"})
+	fx.writer.conflicts["rec2"] = true
+	job := tablehealth.NewFixJob(report, ids, "usr1", time.Now())
+	if err := fx.service.applyFixes(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Fixed != 1 || job.Skipped != 2 || job.Failed != 0 {
+		t.Errorf("修复 = %d，跳过 = %d，失败 = %d", job.Fixed, job.Skipped, job.Failed)
+	}
+	links, _ := fx.writer.writes["rec1"][fx.id("client")].([]interface{})
+	if len(links) != 1 || links[0].(map[string]interface{})["id"] != "recA" {
+		t.Errorf("关联修复后 = %v", fx.writer.writes["rec1"])
+	}
+	if _, ok := fx.writer.writes["rec2"]; ok {
+		t.Errorf("版本冲突的记录不应写入: %v", fx.writer.writes["rec2"])
+	}
+	if issue := fx.repo.find(tablehealth.KindTypeCoercion, fx.id("name"), "rec2"); issue.Status != tablehealth.IssueSkipped {
+		t.Errorf("值已变化的问题 = %+v", issue)
+	}
+
+	// 再次执行时已处理的问题不重复计入
+	if err := fx.service.applyFixes(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Fixed+job.Skipped != 3 {
+		t.Errorf("重复执行后 = %+v", job)
+	}
+}
+
+func TestTableHealthCoerce(t *testing.T) {
+	fx := newTableHealthFixture(t, config.TableHealthConfig{})
+	ctx := context.Background()
+	cases := []struct {
+		field string
+		value interface{}
+		want  interface{}
+		ok    bool
+	}{
+		{"qty", "12", 12.0, true},
+		{"qty", 12.0, nil, false},
+		{"qty", "abc", nil, false},
+		{"name", 42.0, "42", true},
+		{"done", "yes", true, true},
+		{"done", "maybe", nil, false},
+		{"done", []interface{}{"x"}, nil, false},
+	}
+	for _, c := range cases {
+		got, ok := fx.service.coerce(ctx, fx.fields[c.field], c.value)
+		if ok != c.ok || (ok && got != c.want) {
+			t.Errorf("coerce(%s, %v) = %v, %v，期望 %v, %v", c.field, c.value, got, ok, c.want, c.ok)
+		}
+	}
+}
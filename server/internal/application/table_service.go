@@ -14,6 +14,7 @@ import (
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
@@ -28,6 +29,7 @@ type TableService struct {
 	fieldService *FieldService               // ✅ 添加字段服务依赖
 	viewService  *ViewService                // ✅ 添加视图服务依赖
 	dbProvider   database.DBProvider         // ✅ 数据库提供者（物理表管理）
+	maintenance  *MaintenanceService         // ✨ 维护模式（只读时拒绝结构变更）
 }
 
 // NewTableService 创建表格服务
@@ -51,10 +53,27 @@ func NewTableService(
 	}
 }
 
+// SetMaintenanceService 设置维护模式服务
+func (s *TableService) SetMaintenanceService(maintenance *MaintenanceService) {
+	s.maintenance = maintenance
+}
+
+// checkMaintenance 所在的实例、工作空间或 Base 维护中时拒绝结构变更
+func (s *TableService) checkMaintenance(ctx context.Context, refs ResourceRefs) error {
+	if s.maintenance == nil {
+		return nil
+	}
+	return s.maintenance.CheckWritable(ctx, refs)
+}
+
 // CreateTable 创建表格
 // ✅ 对齐 Teable 实现：支持批量创建字段和视图
 // 参考：teable-develop/apps/nestjs-backend/src/features/table/open-api/table-open-api.service.ts
 func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableRequest, userID string) (*dto.TableResponse, error) {
+	if err := s.checkMaintenance(ctx, ResourceRefs{BaseID: req.BaseID}); err != nil {
+		return nil, err
+	}
+
 	// 0. ✅ 准备默认值（对齐 Teable 的 TablePipe）
 	helpers.PrepareTableDefaults(&req)
 
@@ -71,7 +90,7 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 	// 2. 验证Base是否存在
 	exists, err := s.baseRepo.Exists(ctx, req.BaseID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("验证Base存在性失败: %v", err))
+		return nil, pkgerrors.Database(err, "验证Base存在性失败")
 	}
 	if !exists {
 		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
@@ -95,7 +114,10 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 	// 5. ✅ 创建物理表（包含系统字段）
 	tableID := table.ID().String()
 	baseID := req.BaseID
-	dbTableName := s.dbProvider.GenerateTableName(baseID, tableID)
+	dbTableName, err := s.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "解析物理表名失败")
+	}
 
 	logger.Info("正在创建物理表",
 		logger.String("table_id", tableID),
@@ -126,7 +148,7 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 				logger.String("table_id", tableID),
 				logger.ErrorField(rollbackErr))
 		}
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存表格失败")
 	}
 
 	// 临时存储聚合根以便未来扩展
@@ -143,6 +165,7 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 			fieldReq := dto.CreateFieldRequest{
 				TableID:  tableID,
 				Name:     fieldConfig.Name,
+				APIKey:   fieldConfig.APIKey,
 				Type:     fieldConfig.Type,
 				Required: fieldConfig.Required,
 				Unique:   fieldConfig.Unique,
@@ -242,7 +265,7 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 func (s *TableService) GetTable(ctx context.Context, tableID string) (*dto.TableResponse, error) {
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表格失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
@@ -264,10 +287,14 @@ func (s *TableService) GetTable(ctx context.Context, tableID string) (*dto.Table
 
 // UpdateTable 更新表格
 func (s *TableService) UpdateTable(ctx context.Context, tableID string, req dto.UpdateTableRequest) (*dto.TableResponse, error) {
+	if err := s.checkMaintenance(ctx, ResourceRefs{TableID: tableID}); err != nil {
+		return nil, err
+	}
+
 	// 1. 查找表格
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表格失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
@@ -291,7 +318,7 @@ func (s *TableService) UpdateTable(ctx context.Context, tableID string, req dto.
 
 	// 4. 保存
 	if err := s.tableRepo.Update(ctx, table); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存表格失败")
 	}
 
 	logger.Info("表格更新成功", logger.String("table_id", tableID))
@@ -303,10 +330,14 @@ func (s *TableService) UpdateTable(ctx context.Context, tableID string, req dto.
 // ✅ 完全动态表架构：删除Table时删除物理表
 // 严格按照旧系统实现
 func (s *TableService) DeleteTable(ctx context.Context, tableID string) error {
+	if err := s.checkMaintenance(ctx, ResourceRefs{TableID: tableID}); err != nil {
+		return err
+	}
+
 	// 1. 获取表格信息（需要base_id和db_table_name）
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+		return pkgerrors.Database(err, "查找表格失败")
 	}
 	if table == nil {
 		return pkgerrors.ErrNotFound.WithDetails("表格不存在")
@@ -318,6 +349,23 @@ func (s *TableService) DeleteTable(ctx context.Context, tableID string) error {
 		logger.String("table_id", tableID),
 		logger.String("base_id", baseID))
 
+	// 在调用方事务中（如级联删除）：先删除元数据，提交后再删除物理表
+	// 物理表可能位于其他区域的数据库，无法加入当前事务；删除失败只留下不可见的孤立表
+	if pkgDatabase.InTransaction(ctx) {
+		if err := s.tableRepo.Delete(ctx, tableID); err != nil {
+			return pkgerrors.Database(err, "删除表格失败")
+		}
+		afterCommit(ctx, func() {
+			if err := s.dbProvider.DropPhysicalTable(context.WithoutCancel(ctx), baseID, tableID); err != nil {
+				logger.Error("删除物理表失败（表格元数据已删除）",
+					logger.String("table_id", tableID),
+					logger.String("base_id", baseID),
+					logger.ErrorField(err))
+			}
+		})
+		return nil
+	}
+
 	// 2. ✅ 删除物理表
 	// 参考旧系统：DROP TABLE IF EXISTS schema.table CASCADE
 	if err := s.dbProvider.DropPhysicalTable(ctx, baseID, tableID); err != nil {
@@ -334,7 +382,7 @@ func (s *TableService) DeleteTable(ctx context.Context, tableID string) error {
 
 	// 3. 删除表格元数据
 	if err := s.tableRepo.Delete(ctx, tableID); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除表格失败: %v", err))
+		return pkgerrors.Database(err, "删除表格失败")
 	}
 
 	logger.Info("✅ 表格删除成功（含物理表）",
@@ -348,7 +396,7 @@ func (s *TableService) DeleteTable(ctx context.Context, tableID string) error {
 func (s *TableService) ListTables(ctx context.Context, baseID string) ([]*dto.TableResponse, error) {
 	tables, err := s.tableRepo.GetByBaseID(ctx, baseID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询表格列表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查询表格列表失败")
 	}
 
 	tableList := make([]*dto.TableResponse, 0, len(tables))
@@ -374,7 +422,7 @@ func (s *TableService) RenameTable(ctx context.Context, tableID string, req dto.
 	// 1. 查找表格
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表格失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
@@ -389,7 +437,7 @@ func (s *TableService) RenameTable(ctx context.Context, tableID string, req dto.
 	// 3. 检查名称是否重复（在同一Base下）
 	exists, err := s.tableRepo.ExistsByNameInBase(ctx, table.BaseID(), newName, &tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查表格名称失败: %v", err))
+		return nil, pkgerrors.Database(err, "检查表格名称失败")
 	}
 	if exists {
 		return nil, pkgerrors.ErrConflict.WithDetails("表格名称已存在")
@@ -402,7 +450,7 @@ func (s *TableService) RenameTable(ctx context.Context, tableID string, req dto.
 
 	// 5. 保存
 	if err := s.tableRepo.Save(ctx, table); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存表格失败")
 	}
 
 	logger.Info("表格重命名成功",
@@ -417,7 +465,7 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 	// 1. 查找原表格
 	originalTable, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找原表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找原表格失败")
 	}
 	if originalTable == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("原表格不存在")
@@ -432,7 +480,7 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 	// 3. 检查名称是否重复
 	exists, err := s.tableRepo.ExistsByNameInBase(ctx, originalTable.BaseID(), newName, nil)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查表格名称失败: %v", err))
+		return nil, pkgerrors.Database(err, "检查表格名称失败")
 	}
 	if exists {
 		return nil, pkgerrors.ErrConflict.WithDetails("表格名称已存在")
@@ -452,10 +500,13 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 	// 6. 创建物理表
 	newTableID := newTable.ID().String()
 	baseID := originalTable.BaseID()
-	dbTableName := s.dbProvider.GenerateTableName(baseID, newTableID)
+	dbTableName, err := s.dbProvider.ResolveTableName(ctx, baseID, newTableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "解析物理表名失败")
+	}
 
 	if err := s.dbProvider.CreatePhysicalTable(ctx, baseID, newTableID); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建物理表失败: %v", err))
+		return nil, pkgerrors.Database(err, "创建物理表失败")
 	}
 
 	// 7. 设置物理表名并保存表格
@@ -465,7 +516,7 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 		if rollbackErr := s.dbProvider.DropPhysicalTable(ctx, baseID, newTableID); rollbackErr != nil {
 			logger.Error("回滚删除物理表失败", logger.ErrorField(rollbackErr))
 		}
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存新表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存新表格失败")
 	}
 
 	// 8. 复制字段（如果需要）
@@ -478,6 +529,7 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 				fieldReq := dto.CreateFieldRequest{
 					TableID:  newTableID,
 					Name:     field.Name,
+					APIKey:   field.APIKey, // 复制的表保留原 API 键，外部集成可直接切换
 					Type:     field.Type,
 					Required: field.Required,
 					Unique:   field.Unique,
@@ -554,7 +606,7 @@ func (s *TableService) GetTableUsage(ctx context.Context, tableID string) (*dto.
 	// 1. 查找表格
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表格失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
@@ -0,0 +1,550 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/textextract"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var textExtractionLog = logger.Named("text_extraction")
+
+const (
+	// textExtractionJobListLimit 任务列表返回条数
+	textExtractionJobListLimit = 100
+	// textExtractionCleanupInterval 过期任务清理间隔
+	textExtractionCleanupInterval = time.Hour
+)
+
+// errAttachmentScanning 附件仍在隔离扫描中，任务稍后重试
+var errAttachmentScanning = errors.New("附件尚未完成安全扫描")
+
+// textExtractionRecordWriter 写入提取结果（由 RecordService 实现，写入后照常推送事件并进入索引）
+type textExtractionRecordWriter interface {
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// CreateTextExtractionRuleRequest 创建附件文本提取规则请求
+type CreateTextExtractionRuleRequest struct {
+	SourceFieldID string   `json:"sourceFieldId" binding:"required"`
+	TargetFieldID string   `json:"targetFieldId" binding:"required"`
+	Languages     []string `json:"languages"`
+}
+
+// RunTextExtractionRequest 手动重新提取请求（RecordIDs 为空时重新提取整列）
+type RunTextExtractionRequest struct {
+	RecordIDs []string `json:"recordIds"`
+}
+
+// RunTextExtractionResponse 手动重新提取响应
+type RunTextExtractionResponse struct {
+	RuleID   string `json:"rule_id"`
+	Enqueued int    `json:"enqueued"` // 新建的任务数
+	Pending  int    `json:"pending"`  // 已有待执行任务而跳过的记录数
+}
+
+// TextExtractionService 附件文本提取服务 ✨
+// 按表配置的规则，把附件字段中 PDF、图片、Office 文档的文本写入长文本字段，使扫描件可被搜索：
+//   - 新建记录或源字段附件变化时，在记录写入的同一事务中登记提取任务
+//   - 后台领取任务，下载已通过安全扫描的附件交给提取引擎（Tika / Tesseract），拼接结果写回目标字段
+//   - 附件仍在隔离扫描中时任务稍后重试；不支持的类型、过大或未通过扫描的附件被跳过
+//
+// 只处理与记录同表上传的附件，不会把其他表的附件内容写入当前表
+type TextExtractionService struct {
+	repo              textextract.Repository
+	fieldRepo         repository.FieldRepository
+	recordRepo        recordRepo.RecordRepository
+	records           textExtractionRecordWriter
+	attachmentRepo    attachment.Repository
+	storage           attachment.Storage
+	extractor         textextract.Extractor
+	permissionService *PermissionServiceV2
+	cfg               config.TextExtractionConfig
+	now               func() time.Time
+	wake              chan struct{}
+}
+
+// NewTextExtractionService 创建附件文本提取服务（extractor 为 nil 表示未配置提取引擎）
+func NewTextExtractionService(
+	repo textextract.Repository,
+	fieldRepo repository.FieldRepository,
+	recordRepository recordRepo.RecordRepository,
+	records textExtractionRecordWriter,
+	attachmentRepo attachment.Repository,
+	storage attachment.Storage,
+	extractor textextract.Extractor,
+	permissionService *PermissionServiceV2,
+	cfg config.TextExtractionConfig,
+) *TextExtractionService {
+	return &TextExtractionService{
+		repo:              repo,
+		fieldRepo:         fieldRepo,
+		recordRepo:        recordRepository,
+		records:           records,
+		attachmentRepo:    attachmentRepo,
+		storage:           storage,
+		extractor:         extractor,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// Notify 通知有新任务待执行
+func (s *TextExtractionService) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start 启动后台提取与过期任务清理（未配置提取引擎时不启动）
+func (s *TextExtractionService) Start(ctx context.Context) {
+	if s.extractor == nil || s.cfg.PollInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+		var lastCleanup time.Time
+
+		for {
+			for s.processBatch(ctx) {
+			}
+			if s.cfg.JobRetention > 0 && s.now().Sub(lastCleanup) >= textExtractionCleanupInterval {
+				lastCleanup = s.now()
+				if n, err := s.repo.DeleteFinishedBefore(ctx, lastCleanup.Add(-s.cfg.JobRetention)); err != nil {
+					textExtractionLog.Warn(ctx, "清理过期文本提取任务失败", logger.ErrorField(err))
+				} else if n > 0 {
+					textExtractionLog.Info(ctx, "已清理过期文本提取任务", logger.Int64("count", n))
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+		}
+	}()
+}
+
+// RecordCreated 新建记录时为源字段有附件的规则登记提取任务（在记录写入事务中调用）
+func (s *TextExtractionService) RecordCreated(ctx context.Context, tableID, recordID string, data map[string]interface{}, userID string) error {
+	return s.enqueueForRecord(ctx, tableID, recordID, textextract.TriggerCreate, userID, func(rule *textextract.Rule) bool {
+		return len(textextract.Attachments(data[rule.SourceFieldID])) > 0
+	})
+}
+
+// RecordUpdated 源字段变化时登记提取任务（在记录写入事务中调用）
+// 附件被清空时同样登记任务，执行时清空目标字段
+func (s *TextExtractionService) RecordUpdated(ctx context.Context, tableID, recordID string, changedFieldIDs []string, userID string) error {
+	if len(changedFieldIDs) == 0 {
+		return nil
+	}
+	changed := make(map[string]bool, len(changedFieldIDs))
+	for _, id := range changedFieldIDs {
+		changed[id] = true
+	}
+	return s.enqueueForRecord(ctx, tableID, recordID, textextract.TriggerSourceChanged, userID, func(rule *textextract.Rule) bool {
+		return changed[rule.SourceFieldID]
+	})
+}
+
+func (s *TextExtractionService) enqueueForRecord(ctx context.Context, tableID, recordID string, trigger textextract.Trigger, userID string, affected func(rule *textextract.Rule) bool) error {
+	if s.extractor == nil {
+		return nil
+	}
+	rules, err := s.repo.ListRules(ctx, tableID)
+	if err != nil {
+		return pkgerrors.Database(err, "查询文本提取规则失败")
+	}
+	now := s.now()
+	var jobs []*textextract.Job
+	for _, rule := range rules {
+		if affected(rule) {
+			jobs = append(jobs, textextract.NewJob(rule, recordID, trigger, userID, now))
+		}
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	if _, err := s.repo.Enqueue(ctx, jobs...); err != nil {
+		return pkgerrors.Database(err, "登记文本提取任务失败")
+	}
+	s.notifyAfterCommit(ctx)
+	return nil
+}
+
+// notifyAfterCommit 事务提交后唤醒后台任务（不在事务中时立即唤醒）
+func (s *TextExtractionService) notifyAfterCommit(ctx context.Context) {
+	if database.GetTxContext(ctx) != nil {
+		database.AddTxCallback(ctx, s.Notify)
+		return
+	}
+	s.Notify()
+}
+
+// processBatch 领取并执行一批任务，返回是否可能还有待执行的任务
+// 提取较耗资源，同一实例内逐个执行
+func (s *TextExtractionService) processBatch(ctx context.Context) bool {
+	limit := s.cfg.BatchSize
+	if limit <= 0 {
+		limit = 10
+	}
+	lease := s.cfg.LeaseDuration
+	if lease <= 0 {
+		lease = 10 * time.Minute
+	}
+	now := s.now()
+	jobs, err := s.repo.Claim(ctx, now, now.Add(-lease), limit)
+	if err != nil {
+		textExtractionLog.Warn(ctx, "领取文本提取任务失败", logger.ErrorField(err))
+		return false
+	}
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return false
+		}
+		s.process(ctx, job)
+	}
+	return len(jobs) == limit
+}
+
+// process 执行单个任务
+func (s *TextExtractionService) process(ctx context.Context, job *textextract.Job) {
+	rule, err := s.repo.GetRule(ctx, job.RuleID)
+	if err != nil {
+		s.retry(ctx, job, err)
+		return
+	}
+	if rule == nil {
+		s.fail(ctx, job, errors.New("提取规则已删除"))
+		return
+	}
+
+	record, err := s.recordRepo.FindByTableAndID(ctx, job.TableID, recordVO.NewRecordID(job.RecordID))
+	if err != nil {
+		s.retry(ctx, job, err)
+		return
+	}
+	if record == nil {
+		s.fail(ctx, job, errors.New("记录不存在"))
+		return
+	}
+
+	refs := textextract.Attachments(record.Data().ToMap()[rule.SourceFieldID])
+	parts := make([]textextract.Part, 0, len(refs))
+	skipped := 0
+	for _, ref := range refs {
+		text, err := s.extractAttachment(ctx, rule, ref)
+		if errors.Is(err, errAttachmentScanning) || (err != nil && !aifield.IsPermanent(err)) {
+			textExtractionLog.Warn(ctx, "附件文本提取失败，稍后重试",
+				logger.String("job_id", job.ID),
+				logger.String("attachment_id", ref.ID),
+				logger.ErrorField(err))
+			s.retry(ctx, job, err)
+			return
+		}
+		if err != nil {
+			textExtractionLog.Info(ctx, "跳过附件",
+				logger.String("job_id", job.ID),
+				logger.String("attachment_id", ref.ID),
+				logger.String("reason", err.Error()))
+			skipped++
+			continue
+		}
+		parts = append(parts, textextract.Part{Name: ref.Name, Text: text})
+	}
+
+	text := textextract.Compose(parts, s.cfg.MaxChars)
+	var value interface{}
+	if text != "" {
+		value = text
+	}
+	if !s.write(ctx, job, rule, value) {
+		s.retry(ctx, job, errors.New("写入提取结果失败"))
+		return
+	}
+	job.Succeed(s.extractor.Name(), len(parts), skipped, utf8.RuneCountInString(text), s.now())
+	s.save(ctx, job)
+}
+
+// extractAttachment 提取单个附件的文本
+// 附件不存在、不属于本表、未通过扫描、类型不支持或过大时返回不可重试错误（跳过该附件）
+func (s *TextExtractionService) extractAttachment(ctx context.Context, rule *textextract.Rule, ref textextract.AttachmentRef) (string, error) {
+	var (
+		item *attachment.AttachmentItem
+		err  error
+	)
+	if ref.ID != "" {
+		item, err = s.attachmentRepo.GetAttachmentByID(ctx, ref.ID)
+	} else {
+		item, err = s.attachmentRepo.GetAttachmentByToken(ctx, ref.Token)
+	}
+	if pkgerrors.Is(err, pkgerrors.ErrNotFound) || (err == nil && item == nil) {
+		return "", aifield.Permanent(errors.New("附件不存在"))
+	}
+	if err != nil {
+		return "", err
+	}
+	if item.TableID != "" && item.TableID != rule.TableID {
+		return "", aifield.Permanent(errors.New("附件不属于当前表"))
+	}
+	if item.ScanStatus == attachment.ScanStatusPending {
+		return "", errAttachmentScanning
+	}
+	if !item.Downloadable() {
+		return "", aifield.Permanent(fmt.Errorf("附件未通过安全扫描: %s", item.ScanStatus))
+	}
+	if !s.extractor.Supports(item.MimeType) {
+		return "", aifield.Permanent(fmt.Errorf("不支持的文件类型: %s", item.MimeType))
+	}
+	if s.cfg.MaxFileSizeMB > 0 && item.Size > int64(s.cfg.MaxFileSizeMB)<<20 {
+		return "", aifield.Permanent(fmt.Errorf("文件超过 %d MB", s.cfg.MaxFileSizeMB))
+	}
+
+	reader, err := s.storage.Download(ctx, item.StoragePath())
+	if err != nil {
+		if exists, existsErr := s.storage.Exists(ctx, item.StoragePath()); existsErr == nil && !exists {
+			return "", aifield.Permanent(errors.New("附件文件不存在"))
+		}
+		return "", err
+	}
+	defer reader.Close()
+
+	extractCtx := ctx
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		extractCtx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+	languages := rule.Languages
+	if len(languages) == 0 {
+		languages = s.cfg.Languages
+	}
+	var content io.Reader = reader
+	if s.cfg.MaxFileSizeMB > 0 {
+		content = io.LimitReader(reader, int64(s.cfg.MaxFileSizeMB)<<20)
+	}
+	return s.extractor.Extract(extractCtx, content, item.MimeType, languages)
+}
+
+// write 将提取结果写回目标字段
+func (s *TextExtractionService) write(ctx context.Context, job *textextract.Job, rule *textextract.Rule, value interface{}) bool {
+	_, err := s.records.UpdateRecord(ctx, job.TableID, job.RecordID, dto.UpdateRecordRequest{
+		Data: map[string]interface{}{rule.TargetFieldID: value},
+	}, job.CreatedBy)
+	if err != nil {
+		textExtractionLog.Warn(ctx, "写入文本提取结果失败",
+			logger.String("job_id", job.ID),
+			logger.String("record_id", job.RecordID),
+			logger.ErrorField(err))
+		return false
+	}
+	return true
+}
+
+// retry 安排重试，次数耗尽时以失败结束
+func (s *TextExtractionService) retry(ctx context.Context, job *textextract.Job, err error) {
+	maxAttempts := s.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	if !job.Retry(err, maxAttempts, s.now()) {
+		job.Fail(err, s.now())
+	}
+	s.save(ctx, job)
+}
+
+// fail 以失败结束任务
+func (s *TextExtractionService) fail(ctx context.Context, job *textextract.Job, err error) {
+	job.Fail(err, s.now())
+	s.save(ctx, job)
+}
+
+func (s *TextExtractionService) save(ctx context.Context, job *textextract.Job) {
+	if err := s.repo.Save(ctx, job); err != nil {
+		textExtractionLog.Warn(ctx, "保存文本提取任务失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+}
+
+// ListRules 列出表的提取规则
+func (s *TextExtractionService) ListRules(ctx context.Context, userID, tableID string) ([]*textextract.Rule, error) {
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	rules, err := s.repo.ListRules(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文本提取规则失败")
+	}
+	return rules, nil
+}
+
+// CreateRule 创建提取规则（需要表结构管理权限）
+// 源字段必须是附件字段，目标字段必须是文本或长文本字段，且每个目标字段只能由一条规则写入
+func (s *TextExtractionService) CreateRule(ctx context.Context, userID, tableID string, req CreateTextExtractionRuleRequest) (*textextract.Rule, error) {
+	if !s.permissionService.CanManageTableSchema(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的文本提取规则")
+	}
+	if s.extractor == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("服务端未配置文本提取引擎")
+	}
+
+	rule := textextract.NewRule(tableID, req.SourceFieldID, req.TargetFieldID, req.Languages, userID, s.now())
+	if err := rule.Validate(); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	source, err := s.tableField(ctx, tableID, rule.SourceFieldID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Type().String() != fieldVO.TypeAttachment {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("源字段 %s 不是附件字段", source.Name().String()))
+	}
+	target, err := s.tableField(ctx, tableID, rule.TargetFieldID)
+	if err != nil {
+		return nil, err
+	}
+	if t := target.Type().String(); t != fieldVO.TypeLongText && t != fieldVO.TypeText {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("目标字段 %s 必须是长文本字段", target.Name().String()))
+	}
+
+	rules, err := s.repo.ListRules(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询文本提取规则失败")
+	}
+	if len(rules) >= textextract.MaxRulesPerTable {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("每张表最多 %d 条文本提取规则", textextract.MaxRulesPerTable))
+	}
+	for _, existing := range rules {
+		if existing.TargetFieldID == rule.TargetFieldID {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("目标字段 %s 已被其他规则使用", target.Name().String()))
+		}
+	}
+
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return nil, pkgerrors.Database(err, "保存文本提取规则失败")
+	}
+	return rule, nil
+}
+
+// DeleteRule 删除提取规则及其任务（已写入目标字段的文本保留）
+func (s *TextExtractionService) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+	if !s.permissionService.CanManageTableSchema(ctx, userID, rule.TableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有权限管理该表格的文本提取规则")
+	}
+	if err := s.repo.DeleteRule(ctx, rule); err != nil {
+		return pkgerrors.Database(err, "删除文本提取规则失败")
+	}
+	return nil
+}
+
+// Run 手动重新提取（需要表格的记录编辑权限），用于处理创建规则前已有的附件
+func (s *TextExtractionService) Run(ctx context.Context, userID, ruleID string, req RunTextExtractionRequest) (*RunTextExtractionResponse, error) {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanUpdateRecordsInTable(ctx, userID, rule.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有编辑该表记录的权限")
+	}
+	if s.extractor == nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("服务端未配置文本提取引擎")
+	}
+
+	recordIDs := req.RecordIDs
+	max := s.cfg.MaxRunRecords
+	if max <= 0 {
+		max = 5000
+	}
+	if len(recordIDs) == 0 {
+		records, total, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{TableID: &rule.TableID, Limit: max})
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查询记录失败")
+		}
+		if total > int64(max) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("表中有 %d 条记录，整列重新提取最多 %d 条，请通过 recordIds 分批指定", total, max))
+		}
+		for _, record := range records {
+			if len(textextract.Attachments(record.Data().ToMap()[rule.SourceFieldID])) > 0 {
+				recordIDs = append(recordIDs, record.ID().String())
+			}
+		}
+	} else if len(recordIDs) > max {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("一次最多重新提取 %d 条记录", max))
+	}
+
+	now := s.now()
+	jobs := make([]*textextract.Job, 0, len(recordIDs))
+	for _, recordID := range recordIDs {
+		jobs = append(jobs, textextract.NewJob(rule, recordID, textextract.TriggerManual, userID, now))
+	}
+	created, err := s.repo.Enqueue(ctx, jobs...)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "登记文本提取任务失败")
+	}
+	s.Notify()
+	return &RunTextExtractionResponse{RuleID: ruleID, Enqueued: created, Pending: len(jobs) - created}, nil
+}
+
+// ListJobs 列出规则最近的提取任务（recordID 非空时只列该记录）
+func (s *TextExtractionService) ListJobs(ctx context.Context, userID, ruleID, recordID string) ([]*textextract.Job, error) {
+	rule, err := s.rule(ctx, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.permissionService.CanAccessTable(ctx, userID, rule.TableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有访问该表的权限")
+	}
+	jobs, err := s.repo.ListByRule(ctx, ruleID, recordID, textExtractionJobListLimit)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	return jobs, nil
+}
+
+// rule 获取提取规则
+func (s *TextExtractionService) rule(ctx context.Context, ruleID string) (*textextract.Rule, error) {
+	rule, err := s.repo.GetRule(ctx, ruleID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找文本提取规则失败")
+	}
+	if rule == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("文本提取规则不存在")
+	}
+	return rule, nil
+}
+
+// tableField 获取表中未删除的字段
+func (s *TextExtractionService) tableField(ctx context.Context, tableID, fieldID string) (*fieldEntity.Field, error) {
+	field, err := s.fieldRepo.FindByID(ctx, fieldVO.NewFieldID(fieldID))
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找字段失败")
+	}
+	if field == nil || field.IsDeleted() || field.TableID() != tableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在: " + fieldID)
+	}
+	return field, nil
+}
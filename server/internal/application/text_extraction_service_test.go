@@ -0,0 +1,296 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/textextract"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryTextExtractionRepo 内存实现的文本提取仓储
+type memoryTextExtractionRepo struct {
+	rules []*textextract.Rule
+	jobs  []*textextract.Job
+	saved []*textextract.Job
+}
+
+func (r *memoryTextExtractionRepo) ListRules(context.Context, string) ([]*textextract.Rule, error) {
+	return r.rules, nil
+}
+
+func (r *memoryTextExtractionRepo) GetRule(_ context.Context, id string) (*textextract.Rule, error) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryTextExtractionRepo) SaveRule(_ context.Context, rule *textextract.Rule) error {
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+func (r *memoryTextExtractionRepo) DeleteRule(context.Context, *textextract.Rule) error { return nil }
+
+func (r *memoryTextExtractionRepo) Enqueue(_ context.Context, jobs ...*textextract.Job) (int, error) {
+	r.jobs = append(r.jobs, jobs...)
+	return len(jobs), nil
+}
+
+func (r *memoryTextExtractionRepo) Claim(context.Context, time.Time, time.Time, int) ([]*textextract.Job, error) {
+	return nil, nil
+}
+
+func (r *memoryTextExtractionRepo) Save(_ context.Context, job *textextract.Job) error {
+	r.saved = append(r.saved, job)
+	return nil
+}
+
+func (r *memoryTextExtractionRepo) ListByRule(context.Context, string, string, int) ([]*textextract.Job, error) {
+	return r.jobs, nil
+}
+
+func (r *memoryTextExtractionRepo) DeleteFinishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// stubTextExtractor 按文件内容返回文本；内容为 unavailable 时模拟引擎不可用
+type stubTextExtractor struct {
+	calls []string
+}
+
+func (e *stubTextExtractor) Name() string { return "stub" }
+
+func (e *stubTextExtractor) Supports(mimeType string) bool {
+	return mimeType == "application/pdf" || strings.HasPrefix(mimeType, "image/")
+}
+
+func (e *stubTextExtractor) Extract(_ context.Context, content io.Reader, mimeType string, languages []string) (string, error) {
+	data, _ := io.ReadAll(content)
+	e.calls = append(e.calls, string(data)+"|"+strings.Join(languages, "+"))
+	switch string(data) {
+	case "unavailable":
+		return "", errors.New("engine unavailable")
+	case "corrupt":
+		return "", aifield.Permanent(errors.New("cannot parse"))
+	}
+	return "  " + string(data) + " 的文本  ", nil
+}
+
+// stubExtractionAttachmentRepo 按ID返回附件
+type stubExtractionAttachmentRepo struct {
+	attachment.Repository
+	items map[string]*attachment.AttachmentItem
+}
+
+func (r *stubExtractionAttachmentRepo) GetAttachmentByID(_ context.Context, id string) (*attachment.AttachmentItem, error) {
+	if item := r.items[id]; item != nil {
+		return item, nil
+	}
+	return nil, pkgerrors.ErrNotFound
+}
+
+func (r *stubExtractionAttachmentRepo) GetAttachmentByToken(context.Context, string) (*attachment.AttachmentItem, error) {
+	return nil, pkgerrors.ErrNotFound
+}
+
+// memoryExtractionStorage 以路径为键的文件内容
+type memoryExtractionStorage struct {
+	attachment.Storage
+	files map[string]string
+}
+
+func (s *memoryExtractionStorage) Download(_ context.Context, path string) (io.ReadCloser, error) {
+	content, ok := s.files[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (s *memoryExtractionStorage) Exists(_ context.Context, path string) (bool, error) {
+	_, ok := s.files[path]
+	return ok, nil
+}
+
+// stubExtractionRecordRepo 按ID返回记录
+type stubExtractionRecordRepo struct {
+	recordRepo.RecordRepository
+	records map[string]*recordEntity.Record
+}
+
+func (r *stubExtractionRecordRepo) FindByTableAndID(_ context.Context, _ string, id recordVO.RecordID) (*recordEntity.Record, error) {
+	return r.records[id.String()], nil
+}
+
+// recordingExtractionWriter 记录写回目标字段的值
+type recordingExtractionWriter struct {
+	writes []map[string]interface{}
+}
+
+func (w *recordingExtractionWriter) UpdateRecord(_ context.Context, _, recordID string, req dto.UpdateRecordRequest, _ string) (*dto.RecordResponse, error) {
+	w.writes = append(w.writes, req.Data)
+	return &dto.RecordResponse{ID: recordID}, nil
+}
+
+type textExtractionFixture struct {
+	service     *TextExtractionService
+	repo        *memoryTextExtractionRepo
+	extractor   *stubTextExtractor
+	attachments *stubExtractionAttachmentRepo
+	records     *stubExtractionRecordRepo
+	writer      *recordingExtractionWriter
+	rule        *textextract.Rule
+}
+
+func newTextExtractionFixture(t *testing.T) *textExtractionFixture {
+	t.Helper()
+	now := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	rule := textextract.NewRule("tbl1", "fldFiles", "fldText", []string{"chi_sim"}, "usr1", now)
+	newItem := func(id, tableID, mimeType string, status attachment.ScanStatus) *attachment.AttachmentItem {
+		item := attachment.NewAttachmentItem(id+".bin", "uploads/"+id, "tok-"+id, mimeType, 10)
+		item.ID = id
+		item.TableID = tableID
+		item.ScanStatus = status
+		return item
+	}
+
+	fx := &textExtractionFixture{
+		repo:      &memoryTextExtractionRepo{rules: []*textextract.Rule{rule}},
+		extractor: &stubTextExtractor{},
+		attachments: &stubExtractionAttachmentRepo{items: map[string]*attachment.AttachmentItem{
+			"pdf":      newItem("pdf", "tbl1", "application/pdf", attachment.ScanStatusClean),
+			"png":      newItem("png", "tbl1", "image/png", ""),
+			"video":    newItem("video", "tbl1", "video/mp4", attachment.ScanStatusClean),
+			"foreign":  newItem("foreign", "tbl2", "application/pdf", attachment.ScanStatusClean),
+			"infected": newItem("infected", "tbl1", "image/png", attachment.ScanStatusInfected),
+			"pending":  newItem("pending", "tbl1", "image/png", attachment.ScanStatusPending),
+			"down":     newItem("down", "tbl1", "image/png", attachment.ScanStatusClean),
+			"corrupt":  newItem("corrupt", "tbl1", "application/pdf", attachment.ScanStatusClean),
+		}},
+		records: &stubExtractionRecordRepo{records: make(map[string]*recordEntity.Record)},
+		writer:  &recordingExtractionWriter{},
+		rule:    rule,
+	}
+	storage := &memoryExtractionStorage{files: map[string]string{
+		"uploads/pdf": "合同", "uploads/png": "收据", "uploads/foreign": "机密", "uploads/infected": "病毒",
+		"uploads/down": "unavailable", "uploads/corrupt": "corrupt",
+	}}
+	fx.service = NewTextExtractionService(
+		fx.repo, nil, fx.records, fx.writer, fx.attachments, storage, fx.extractor, nil,
+		config.TextExtractionConfig{MaxChars: 1000, MaxAttempts: 3, MaxFileSizeMB: 1},
+	)
+	fx.service.now = func() time.Time { return now }
+	return fx
+}
+
+func (fx *textExtractionFixture) setFiles(recordID string, ids ...string) {
+	items := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, map[string]interface{}{"id": id, "name": id + ".bin"})
+	}
+	data, _ := recordVO.NewRecordData(map[string]interface{}{"fldFiles": items})
+	fx.records.records[recordID] = recordEntity.ReconstructRecord(recordVO.NewRecordID(recordID), "tbl1", data, recordVO.InitialVersion(), "usr1", "usr1", time.Now(), time.Now(), nil)
+}
+
+func (fx *textExtractionFixture) run(recordID string) *textextract.Job {
+	job := textextract.NewJob(fx.rule, recordID, textextract.TriggerSourceChanged, "usr1", fx.service.now())
+	job.Status = textextract.StatusRunning
+	job.Attempts = 1
+	fx.service.process(context.Background(), job)
+	return job
+}
+
+func TestTextExtractionEnqueue(t *testing.T) {
+	fx := newTextExtractionFixture(t)
+	ctx := context.Background()
+
+	if err := fx.service.RecordCreated(ctx, "tbl1", "rec1", map[string]interface{}{"fldOther": "x"}, "usr1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.service.RecordUpdated(ctx, "tbl1", "rec1", []string{"fldText"}, "usr1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fx.repo.jobs) != 0 {
+		t.Fatalf("源字段无附件或未变化时不应登记任务: %+v", fx.repo.jobs)
+	}
+
+	files := []interface{}{map[string]interface{}{"id": "pdf"}}
+	if err := fx.service.RecordCreated(ctx, "tbl1", "rec1", map[string]interface{}{"fldFiles": files}, "usr1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.service.RecordUpdated(ctx, "tbl1", "rec2", []string{"fldFiles"}, "usr2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fx.repo.jobs) != 2 || fx.repo.jobs[0].Trigger != textextract.TriggerCreate || fx.repo.jobs[1].CreatedBy != "usr2" {
+		t.Errorf("任务 = %+v", fx.repo.jobs)
+	}
+
+	fx.service.extractor = nil
+	if err := fx.service.RecordUpdated(ctx, "tbl1", "rec3", []string{"fldFiles"}, "usr1"); err != nil || len(fx.repo.jobs) != 2 {
+		t.Error("未配置提取引擎时不应登记任务")
+	}
+}
+
+func TestTextExtractionProcess(t *testing.T) {
+	fx := newTextExtractionFixture(t)
+	fx.setFiles("rec1", "pdf", "video", "png", "foreign", "infected", "missing", "corrupt")
+
+	job := fx.run("rec1")
+	if job.Status != textextract.StatusSucceeded || job.Engine != "stub" || job.Files != 2 || job.Skipped != 5 {
+		t.Fatalf("任务 = %+v", job)
+	}
+	want := "[pdf.bin]\n合同 的文本\n\n[png.bin]\n收据 的文本"
+	if len(fx.writer.writes) != 1 || fx.writer.writes[0]["fldText"] != want {
+		t.Fatalf("写入 = %v", fx.writer.writes)
+	}
+	for _, call := range fx.extractor.calls {
+		if strings.HasPrefix(call, "机密") || strings.HasPrefix(call, "病毒") {
+			t.Errorf("不应提取其他表或未通过扫描的附件: %v", fx.extractor.calls)
+		}
+	}
+	if fx.extractor.calls[0] != "合同|chi_sim" {
+		t.Errorf("应使用规则的 OCR 语言: %v", fx.extractor.calls)
+	}
+
+	// 附件清空后清空目标字段
+	fx.setFiles("rec2")
+	if job := fx.run("rec2"); job.Status != textextract.StatusSucceeded || fx.writer.writes[1]["fldText"] != nil {
+		t.Errorf("清空附件后应清空目标字段: %+v %v", job, fx.writer.writes)
+	}
+}
+
+func TestTextExtractionRetries(t *testing.T) {
+	fx := newTextExtractionFixture(t)
+
+	fx.setFiles("rec1", "pdf", "pending")
+	job := fx.run("rec1")
+	if job.Status != textextract.StatusPending || !strings.Contains(job.Error, "安全扫描") || len(fx.writer.writes) != 0 {
+		t.Errorf("附件扫描中应稍后重试且不写入: %+v", job)
+	}
+
+	fx.setFiles("rec2", "down")
+	job = fx.run("rec2")
+	if job.Status != textextract.StatusPending || len(fx.writer.writes) != 0 {
+		t.Errorf("引擎不可用应稍后重试: %+v", job)
+	}
+
+	fx.repo.rules = nil
+	if job := fx.run("rec1"); job.Status != textextract.StatusFailed {
+		t.Errorf("规则删除后任务应失败: %+v", job)
+	}
+}
@@ -33,19 +33,37 @@ type Claims struct {
 	UserID  string `json:"user_id"`
 	Email   string `json:"email"`
 	IsAdmin bool   `json:"is_admin"`
+	// SessionID 登录会话ID（旧令牌为空）
+	SessionID string `json:"sid,omitempty"`
+	// AuthTime 会话的登录时间（刷新令牌时保持不变，用于会话最长时长策略）
+	AuthTime int64 `json:"auth_time,omitempty"`
+	// ReauthTime 最近一次重新认证时间（用于敏感操作）
+	ReauthTime int64 `json:"reauth_time,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenSession 令牌关联的会话信息
+type TokenSession struct {
+	SessionID  string
+	AuthTime   time.Time
+	ReauthTime time.Time
+}
+
 // GenerateTokens 生成访问令牌和刷新令牌
 func (s *TokenService) GenerateTokens(userID, email string, isAdmin bool) (accessToken string, refreshToken string, err error) {
+	return s.GenerateSessionTokens(userID, email, isAdmin, TokenSession{})
+}
+
+// GenerateSessionTokens 生成绑定登录会话的访问令牌和刷新令牌
+func (s *TokenService) GenerateSessionTokens(userID, email string, isAdmin bool, session TokenSession) (accessToken string, refreshToken string, err error) {
 	// 生成访问Token
-	accessToken, err = s.generateAccessToken(userID, email, isAdmin)
+	accessToken, err = s.generateToken(userID, email, isAdmin, session, s.accessTTL)
 	if err != nil {
 		return "", "", err
 	}
 
 	// 生成刷新Token
-	refreshToken, err = s.generateRefreshToken(userID, email, isAdmin)
+	refreshToken, err = s.generateToken(userID, email, isAdmin, session, s.refreshTTL)
 	if err != nil {
 		return "", "", err
 	}
@@ -53,36 +71,25 @@ func (s *TokenService) GenerateTokens(userID, email string, isAdmin bool) (acces
 	return accessToken, refreshToken, nil
 }
 
-// generateAccessToken 生成访问令牌
-func (s *TokenService) generateAccessToken(userID, email string, isAdmin bool) (string, error) {
+// generateToken 生成令牌
+func (s *TokenService) generateToken(userID, email string, isAdmin bool, session TokenSession, ttl time.Duration) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID:  userID,
-		Email:   email,
-		IsAdmin: isAdmin,
+		UserID:    userID,
+		Email:     email,
+		IsAdmin:   isAdmin,
+		SessionID: session.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtSecret))
-}
-
-// generateRefreshToken 生成刷新令牌
-func (s *TokenService) generateRefreshToken(userID, email string, isAdmin bool) (string, error) {
-	now := time.Now()
-	claims := Claims{
-		UserID:  userID,
-		Email:   email,
-		IsAdmin: isAdmin,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    s.issuer,
-			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(s.refreshTTL)),
-		},
+	if !session.AuthTime.IsZero() {
+		claims.AuthTime = session.AuthTime.Unix()
+	}
+	if !session.ReauthTime.IsZero() {
+		claims.ReauthTime = session.ReauthTime.Unix()
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -120,6 +127,20 @@ func (s *TokenService) parseToken(tokenString string) (*Claims, error) {
 	return nil, pkgerrors.ErrUnauthorized.WithDetails("Token无效")
 }
 
+// Session 从声明中还原会话信息（旧令牌以签发时间作为登录时间）
+func (c *Claims) Session() TokenSession {
+	session := TokenSession{SessionID: c.SessionID}
+	if c.AuthTime > 0 {
+		session.AuthTime = time.Unix(c.AuthTime, 0)
+	} else if c.IssuedAt != nil {
+		session.AuthTime = c.IssuedAt.Time
+	}
+	if c.ReauthTime > 0 {
+		session.ReauthTime = time.Unix(c.ReauthTime, 0)
+	}
+	return session
+}
+
 // ExtractUserID 从Token中提取用户ID
 func (s *TokenService) ExtractUserID(tokenString string) (string, error) {
 	claims, err := s.parseToken(tokenString)
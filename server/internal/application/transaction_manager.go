@@ -220,6 +220,15 @@ func (tm *TransactionManager) IsInTransaction(ctx context.Context) bool {
 	return database.InTransaction(ctx)
 }
 
+// afterCommit 事务提交后执行 fn（不在事务中时立即执行；回滚时不执行）
+func afterCommit(ctx context.Context, fn func()) {
+	if database.InTransaction(ctx) {
+		database.AddTxCallback(ctx, fn)
+		return
+	}
+	fn()
+}
+
 // GetTransactionStats 获取事务统计信息
 func (tm *TransactionManager) GetTransactionStats() map[string]interface{} {
 	tm.mu.RLock()
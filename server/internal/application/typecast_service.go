@@ -51,79 +51,17 @@ func (s *TypecastService) ValidateAndTypecastRecord(
 		return nil, fmt.Errorf("获取字段列表失败: %w", err)
 	}
 
-	// 2. 构建字段映射（同时支持 field_id 和 field_name）
-	fieldMapByID := make(map[string]*entity.Field)
-	fieldMapByName := make(map[string]*entity.Field)
-	for _, field := range fields {
-		fieldMapByID[field.ID().String()] = field
-		fieldMapByName[field.Name().String()] = field
-	}
-
-	// 3. 验证和转换每个字段的值
-	result := make(map[string]interface{})
-
-	for fieldKey, value := range data {
-		// 先尝试通过 ID 查找，再尝试通过名称查找
-		field, exists := fieldMapByID[fieldKey]
-		if !exists {
-			field, exists = fieldMapByName[fieldKey]
-			if !exists {
-				// ✅ 字段不存在的处理
-				if typecast {
-					// 宽松模式：跳过不存在的字段
-					logger.Warn("字段不存在，跳过",
-						logger.String("field_key", fieldKey))
-					continue
-				} else {
-					// 严格模式：返回错误
-					return nil, errors.ErrFieldNotFound.WithDetails(map[string]interface{}{
-						"field_key": fieldKey,
-						"table_id":  tableID,
-					})
-				}
-			}
-		}
-
-		// 统一使用字段ID作为结果的键
-		fieldID := field.ID().String()
-
-		// 跳过计算字段（只读）
-		if field.IsComputed() {
-			logger.Debug("跳过计算字段",
-				logger.String("field_id", fieldID),
-				logger.String("field_name", field.Name().String()))
-			continue
-		}
-
-		// 验证值
-		validationResult := s.factory.ValidateField(ctx, value, field)
-
-		if !validationResult.Success {
-			if typecast {
-				// 宽松模式：尝试修复
-				logger.Info("验证失败，尝试类型转换",
-					logger.String("field_id", fieldID),
-					logger.String("field_name", field.Name().String()),
-					logger.ErrorField(validationResult.Error))
-
-				repairedValue := s.factory.RepairValue(ctx, value, field)
-				if repairedValue != nil {
-					result[fieldID] = repairedValue
-					logger.Info("类型转换成功",
-						logger.String("field_id", fieldID),
-						logger.Any("original_value", value),
-						logger.Any("repaired_value", repairedValue))
-				} else {
-					logger.Warn("类型转换失败，跳过此字段",
-						logger.String("field_id", fieldID))
-				}
-			} else {
-				// 严格模式：返回具体的 AppError
-				return nil, s.convertValidationError(validationResult.Error, field, value)
-			}
-		} else {
-			result[fieldID] = validationResult.Value
+	// 2. 按字段校验（与 ValidateCells 共用同一套规则）
+	result, issues := newCellValidator(s.factory, tableID, fields).validateRow(ctx, data, typecast)
+	for _, issue := range issues {
+		if !typecast {
+			// 严格模式：返回具体的 AppError
+			return nil, issue.err
 		}
+		// 宽松模式：跳过不存在的字段与无法转换的值
+		logger.Warn("字段值无效且无法转换，跳过",
+			logger.String("field_key", issue.key),
+			logger.String("error", issue.err.Error()))
 	}
 
 	logger.Info("验证和类型转换完成",
@@ -151,14 +89,17 @@ func (s *TypecastService) RepairFieldValue(
 	return s.factory.RepairValue(ctx, value, field)
 }
 
-// convertValidationError 将验证错误转换为具体的 AppError
-func (s *TypecastService) convertValidationError(
+// cellValueError 将验证错误转换为具体的 AppError
+func cellValueError(
 	validationErr error,
 	field *entity.Field,
 	value interface{},
-) error {
+) *errors.AppError {
 	if validationErr == nil {
-		return nil
+		return errors.ErrInvalidFieldValue.WithDetails(map[string]interface{}{
+			"field": field.Name().String(),
+			"value": value,
+		})
 	}
 
 	fieldName := field.Name().String()
@@ -0,0 +1,220 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/servicetoken"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/usage"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var usageLog = logger.Named("usage")
+
+// apiCallKey API 调用计数的汇总键
+type apiCallKey struct {
+	spaceID string
+	tokenID string
+	day     time.Time
+}
+
+// UsageService 工作空间用量统计 ✨
+// 新建记录、后台任务运行、活跃协作者与附件占用由后台定时从业务表重新汇总当天与前一天（可重复执行），
+// 服务令牌的 API 调用在内存中计数后定期累加写入；汇总行超过保留期后清理
+type UsageService struct {
+	repo              usage.Repository
+	baseRepo          baseRepo.BaseRepository
+	tokenRepo         servicetoken.Repository
+	permissionService *PermissionServiceV2
+	cfg               config.UsageConfig
+	now               func() time.Time
+
+	mu       sync.Mutex
+	apiCalls map[apiCallKey]int64
+}
+
+// NewUsageService 创建用量统计服务
+func NewUsageService(
+	repo usage.Repository,
+	baseRepo baseRepo.BaseRepository,
+	tokenRepo servicetoken.Repository,
+	permissionService *PermissionServiceV2,
+	cfg config.UsageConfig,
+) *UsageService {
+	return &UsageService{
+		repo:              repo,
+		baseRepo:          baseRepo,
+		tokenRepo:         tokenRepo,
+		permissionService: permissionService,
+		cfg:               cfg,
+		now:               time.Now,
+		apiCalls:          make(map[apiCallKey]int64),
+	}
+}
+
+// RecordAPICall 记录一次服务令牌 API 调用（只计数，不访问数据库）
+func (s *UsageService) RecordAPICall(spaceID, tokenID string) {
+	if spaceID == "" || tokenID == "" {
+		return
+	}
+	key := apiCallKey{spaceID: spaceID, tokenID: tokenID, day: usage.Day(s.now())}
+	s.mu.Lock()
+	s.apiCalls[key]++
+	s.mu.Unlock()
+}
+
+// PendingAPICalls 空间尚未写入汇总表的 API 调用次数
+func (s *UsageService) PendingAPICalls(spaceID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for key, count := range s.apiCalls {
+		if key.spaceID == spaceID {
+			total += count
+		}
+	}
+	return total
+}
+
+// Start 启动 API 调用计数写入与定时汇总
+func (s *UsageService) Start(ctx context.Context) {
+	if s.cfg.FlushInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(s.cfg.FlushInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					s.flush(context.WithoutCancel(ctx))
+					return
+				case <-ticker.C:
+				}
+				s.flush(ctx)
+			}
+		}()
+	}
+
+	if s.cfg.RollupInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.cfg.RollupInterval)
+		defer ticker.Stop()
+
+		for {
+			s.rollup(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// flush 将内存中的 API 调用计数累加写入汇总表（写入失败时放回，下次重试）
+func (s *UsageService) flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.apiCalls
+	s.apiCalls = make(map[apiCallKey]int64)
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	rows := make([]*usage.DailyUsage, 0, len(pending))
+	for key, count := range pending {
+		rows = append(rows, &usage.DailyUsage{
+			SpaceID:   key.spaceID,
+			Day:       key.day,
+			Metric:    usage.MetricAPICalls,
+			Dimension: key.tokenID,
+			Value:     count,
+		})
+	}
+	if err := s.repo.Increment(ctx, rows); err != nil {
+		usageLog.Warn(ctx, "写入 API 调用计数失败", logger.ErrorField(err))
+		s.mu.Lock()
+		for key, count := range pending {
+			s.apiCalls[key] += count
+		}
+		s.mu.Unlock()
+	}
+}
+
+// rollup 重新汇总前一天与当天的用量，并清理超过保留期的汇总行
+// 前一天在跨日后仍会再汇总一次，补上前一天最后一个汇总间隔内的数据
+func (s *UsageService) rollup(ctx context.Context) {
+	today := usage.Day(s.now())
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		rows, err := s.repo.CountActivity(ctx, day, day.AddDate(0, 0, 1))
+		if err == nil {
+			err = s.repo.Upsert(ctx, rows)
+		}
+		if err != nil {
+			usageLog.Warn(ctx, "汇总用量失败", logger.String("day", day.Format("2006-01-02")), logger.ErrorField(err))
+			return
+		}
+	}
+
+	storage, err := s.repo.MeasureStorage(ctx)
+	if err == nil {
+		for _, row := range storage {
+			row.Day = today
+		}
+		err = s.repo.Upsert(ctx, storage)
+	}
+	if err != nil {
+		usageLog.Warn(ctx, "汇总附件占用失败", logger.ErrorField(err))
+	}
+
+	if s.cfg.Retention <= 0 {
+		return
+	}
+	deleted, err := s.repo.PurgeBefore(ctx, usage.Day(s.now().Add(-s.cfg.Retention)))
+	if err != nil {
+		usageLog.Warn(ctx, "清理过期用量失败", logger.ErrorField(err))
+		return
+	}
+	if deleted > 0 {
+		usageLog.Debug(ctx, "已清理过期用量", logger.Int64("count", deleted))
+	}
+}
+
+// GetSpaceReport 获取空间在日期区间内的用量报表（仅空间所有者）
+func (s *UsageService) GetSpaceReport(ctx context.Context, userID, spaceID, from, to string) (*usage.Report, error) {
+	role, err := s.permissionService.GetUserRole(ctx, userID, spaceID)
+	if err != nil || role != collaboratorEntity.RoleOwner {
+		return nil, pkgerrors.ErrForbidden.WithDetails("仅空间所有者可以查看用量统计")
+	}
+	start, end, err := usage.ParseRange(from, to, s.now())
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	rows, err := s.repo.ListBySpace(ctx, spaceID, start, end)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "")
+	}
+	report := usage.BuildReport(spaceID, start, end, rows)
+
+	for _, item := range report.Bases {
+		if base, err := s.baseRepo.FindByID(ctx, item.BaseID); err == nil && base != nil {
+			item.BaseName = base.Name
+		}
+	}
+	for _, item := range report.Tokens {
+		if token, err := s.tokenRepo.GetByID(ctx, item.TokenID); err == nil && token != nil {
+			item.Name = token.Name
+			item.UserID = token.UserID
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/usage"
+)
+
+type memoryUsageRepo struct {
+	rows    map[string]*usage.DailyUsage
+	failing bool
+}
+
+func (r *memoryUsageRepo) key(row *usage.DailyUsage) string {
+	return row.SpaceID + "|" + row.BaseID + "|" + usage.Day(row.Day).Format("2006-01-02") + "|" + string(row.Metric) + "|" + row.Dimension
+}
+
+func (r *memoryUsageRepo) Upsert(_ context.Context, rows []*usage.DailyUsage) error {
+	for _, row := range rows {
+		copied := *row
+		r.rows[r.key(row)] = &copied
+	}
+	return nil
+}
+
+func (r *memoryUsageRepo) Increment(_ context.Context, rows []*usage.DailyUsage) error {
+	if r.failing {
+		return errors.New("database unavailable")
+	}
+	for _, row := range rows {
+		if existing, ok := r.rows[r.key(row)]; ok {
+			existing.Value += row.Value
+			continue
+		}
+		copied := *row
+		r.rows[r.key(row)] = &copied
+	}
+	return nil
+}
+
+func (r *memoryUsageRepo) ListBySpace(_ context.Context, spaceID string, from, to time.Time) ([]*usage.DailyUsage, error) {
+	var list []*usage.DailyUsage
+	for _, row := range r.rows {
+		if row.SpaceID == spaceID && !row.Day.Before(from) && !row.Day.After(to) {
+			list = append(list, row)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryUsageRepo) PurgeBefore(_ context.Context, day time.Time) (int64, error) {
+	var deleted int64
+	for key, row := range r.rows {
+		if row.Day.Before(day) {
+			delete(r.rows, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryUsageRepo) CountActivity(_ context.Context, from, _ time.Time) ([]*usage.DailyUsage, error) {
+	return []*usage.DailyUsage{{SpaceID: "spc", BaseID: "bse", Day: from, Metric: usage.MetricRecordsCreated, Value: 4}}, nil
+}
+
+func (r *memoryUsageRepo) MeasureStorage(_ context.Context) ([]*usage.DailyUsage, error) {
+	return []*usage.DailyUsage{{SpaceID: "spc", BaseID: "bse", Metric: usage.MetricStorageBytes, Value: 1024}}, nil
+}
+
+func TestUsageServiceFlushesAPICalls(t *testing.T) {
+	repo := &memoryUsageRepo{rows: map[string]*usage.DailyUsage{}, failing: true}
+	service := NewUsageService(repo, nil, nil, nil, config.UsageConfig{})
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	service.RecordAPICall("spc", "stk_1")
+	service.RecordAPICall("spc", "stk_1")
+	service.RecordAPICall("", "stk_1") // 无法归属空间的请求不计数
+	ctx := context.Background()
+
+	service.flush(ctx)
+	if len(repo.rows) != 0 {
+		t.Fatalf("写入失败时不应有数据: %+v", repo.rows)
+	}
+	repo.failing = false
+	service.RecordAPICall("spc", "stk_1")
+	service.flush(ctx)
+
+	rows, _ := repo.ListBySpace(ctx, "spc", usage.Day(now), usage.Day(now))
+	if len(rows) != 1 || rows[0].Value != 3 || rows[0].Dimension != "stk_1" {
+		t.Errorf("写入失败的计数应在下次写入时补上: %+v", rows)
+	}
+}
+
+func TestUsageServiceRollupPurgesExpiredRows(t *testing.T) {
+	repo := &memoryUsageRepo{rows: map[string]*usage.DailyUsage{}}
+	service := NewUsageService(repo, nil, nil, nil, config.UsageConfig{Retention: 30 * 24 * time.Hour})
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	_ = repo.Upsert(context.Background(), []*usage.DailyUsage{
+		{SpaceID: "spc", BaseID: "bse", Day: now.AddDate(0, 0, -40), Metric: usage.MetricRecordsCreated, Value: 1},
+	})
+
+	service.rollup(context.Background())
+
+	rows, _ := repo.ListBySpace(context.Background(), "spc", now.AddDate(0, 0, -60), now)
+	report := usage.BuildReport("spc", usage.Day(now).AddDate(0, 0, -1), usage.Day(now), rows)
+	if len(rows) != 3 || report.Totals[usage.MetricRecordsCreated] != 8 || report.Totals[usage.MetricStorageBytes] != 1024 {
+		t.Errorf("汇总或清理错误: %d 行, %+v", len(rows), report.Totals)
+	}
+}
@@ -2,18 +2,31 @@ package application
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
 
 	"gorm.io/gorm"
 )
 
+// userLocaleTTL 用户时区与语言的缓存时间（每个请求都会读取，修改配置时立即失效）
+const userLocaleTTL = time.Minute
+
+type cachedUserLocale struct {
+	locale    userlocale.Locale
+	found     bool
+	expiresAt time.Time
+}
+
 // UserConfigService 用户配置服务
 type UserConfigService struct {
-	repo repository.UserConfigRepository
+	repo    repository.UserConfigRepository
+	locales sync.Map // userID -> cachedUserLocale
 }
 
 // NewUserConfigService 创建用户配置服务
@@ -35,10 +48,11 @@ func (s *UserConfigService) GetUserConfig(ctx context.Context, userID string) (*
 			}
 
 			if err := s.repo.Create(ctx, config); err != nil {
-				return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+				return nil, errors.Database(err, "")
 			}
+			s.locales.Delete(userID)
 		} else {
-			return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return nil, errors.Database(err, "")
 		}
 	}
 
@@ -60,7 +74,7 @@ func (s *UserConfigService) UpdateUserConfig(ctx context.Context, userID string,
 			}
 			isNew = true
 		} else {
-			return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return nil, errors.Database(err, "")
 		}
 	}
 
@@ -72,17 +86,39 @@ func (s *UserConfigService) UpdateUserConfig(ctx context.Context, userID string,
 	// 保存配置：如果是新创建的则Create，否则Update
 	if isNew {
 		if err := s.repo.Create(ctx, config); err != nil {
-			return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return nil, errors.Database(err, "")
 		}
 	} else {
 		if err := s.repo.Update(ctx, config); err != nil {
-			return nil, errors.ErrDatabaseOperation.WithDetails(err.Error())
+			return nil, errors.Database(err, "")
 		}
 	}
+	s.locales.Delete(userID)
 
 	return s.toDTO(config), nil
 }
 
+// Locale 用户配置的时区与语言（带缓存）；用户没有配置时 found 为 false
+func (s *UserConfigService) Locale(ctx context.Context, userID string) (userlocale.Locale, bool, error) {
+	now := time.Now()
+	if v, ok := s.locales.Load(userID); ok {
+		if cached := v.(cachedUserLocale); now.Before(cached.expiresAt) {
+			return cached.locale, cached.found, nil
+		}
+	}
+	config, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return userlocale.Locale{}, false, errors.Database(err, "")
+	}
+	cached := cachedUserLocale{expiresAt: now.Add(userLocaleTTL)}
+	if config != nil {
+		cached.locale = userlocale.Locale{TimeZone: config.Timezone(), Language: config.Language()}
+		cached.found = true
+	}
+	s.locales.Store(userID, cached)
+	return cached.locale, cached.found, nil
+}
+
 // toDTO 转换实体到DTO
 func (s *UserConfigService) toDTO(config *entity.UserConfig) *dto.UserConfigResponse {
 	return &dto.UserConfigResponse{
@@ -41,7 +41,7 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest)
 	// 2. 检查邮箱是否已存在
 	exists, err := s.userRepo.ExistsByEmail(ctx, email, nil)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查邮箱失败: %v", err))
+		return nil, pkgerrors.Database(err, "检查邮箱失败")
 	}
 	if exists {
 		return nil, pkgerrors.ErrConflict.WithDetails("邮箱已被注册")
@@ -66,7 +66,7 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserRequest)
 
 	// 6. 保存用户
 	if err := s.userRepo.Save(ctx, user); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存用户失败")
 	}
 
 	logger.Info("用户创建成功",
@@ -91,7 +91,7 @@ func (s *UserService) GetUser(ctx context.Context, userID string) (*dto.UserResp
 	// 2. 查找用户
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("用户不存在")
@@ -107,7 +107,7 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req dto.Upd
 	id := valueobject.NewUserID(userID)
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("用户不存在")
@@ -130,7 +130,7 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req dto.Upd
 		// 检查邮箱是否已被使用
 		exists, err := s.userRepo.ExistsByEmail(ctx, email, &id)
 		if err != nil {
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查邮箱失败: %v", err))
+			return nil, pkgerrors.Database(err, "检查邮箱失败")
 		}
 		if exists {
 			return nil, pkgerrors.ErrConflict.WithDetails("邮箱已被使用")
@@ -146,7 +146,7 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req dto.Upd
 
 	// 6. 保存更新
 	if err := s.userRepo.Save(ctx, user); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存用户失败")
 	}
 
 	logger.Info("用户更新成功",
@@ -178,7 +178,7 @@ func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
 	// 1. 检查用户是否存在
 	exists, err := s.userRepo.Exists(ctx, id)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查用户失败: %v", err))
+		return pkgerrors.Database(err, "检查用户失败")
 	}
 	if !exists {
 		return pkgerrors.ErrNotFound.WithDetails("用户不存在")
@@ -186,7 +186,7 @@ func (s *UserService) DeleteUser(ctx context.Context, userID string) error {
 
 	// 2. 删除用户
 	if err := s.userRepo.Delete(ctx, id); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除用户失败: %v", err))
+		return pkgerrors.Database(err, "删除用户失败")
 	}
 
 	logger.Info("用户删除成功",
@@ -214,7 +214,7 @@ func (s *UserService) ListUsers(ctx context.Context, req dto.UserListFilter) (*d
 	// 查询用户列表
 	users, total, err := s.userRepo.List(ctx, filter)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询用户列表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查询用户列表失败")
 	}
 
 	// 转换为 DTO
@@ -242,7 +242,7 @@ func (s *UserService) GetUserByEmail(ctx context.Context, emailStr string) (*dto
 
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("用户不存在")
@@ -257,7 +257,7 @@ func (s *UserService) ChangePassword(ctx context.Context, userID string, oldPass
 	id := valueobject.NewUserID(userID)
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		return pkgerrors.Database(err, "查找用户失败")
 	}
 	if user == nil {
 		return pkgerrors.ErrNotFound.WithDetails("用户不存在")
@@ -286,7 +286,7 @@ func (s *UserService) ChangePassword(ctx context.Context, userID string, oldPass
 
 	// 5. 保存
 	if err := s.userRepo.Save(ctx, user); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存用户失败: %v", err))
+		return pkgerrors.Database(err, "保存用户失败")
 	}
 
 	logger.Info("密码修改成功",
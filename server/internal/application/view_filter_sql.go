@@ -0,0 +1,520 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
+)
+
+// columnKind 物理列的存储类别（决定过滤与聚合的 SQL 写法）
+type columnKind int
+
+const (
+	columnKindText columnKind = iota
+	columnKindNumber
+	columnKindDate
+	columnKindBoolean
+	columnKindJSON
+	columnKindArray
+	columnKindLocation
+)
+
+// columnKindOf 根据字段的物理列类型确定存储类别
+func columnKindOf(field *entity.Field) columnKind {
+	if field.Type().String() == fieldVO.TypeLocation {
+		return columnKindLocation
+	}
+	dbType := strings.ToUpper(field.DBFieldType())
+	switch {
+	case strings.HasSuffix(dbType, "[]"):
+		return columnKindArray
+	case dbType == "JSONB" || dbType == "JSON":
+		return columnKindJSON
+	case dbType == "NUMERIC" || dbType == "INTEGER" || dbType == "BIGINT" || dbType == "SERIAL" ||
+		strings.HasPrefix(dbType, "DECIMAL") || strings.HasPrefix(dbType, "DOUBLE"):
+		return columnKindNumber
+	case dbType == "DATE" || strings.HasPrefix(dbType, "TIMESTAMP"):
+		return columnKindDate
+	case dbType == "BOOLEAN":
+		return columnKindBoolean
+	default:
+		return columnKindText
+	}
+}
+
+// buildViewFilterCondition 将视图过滤器转换为 SQL 条件
+// 引用了不存在字段的过滤项被忽略（与视图展示时的行为一致），过滤器为空时返回 nil；
+// 日期过滤中的相对日期（today 等）按请求用户的时区解析（字段开启统一时区时按字段时区），
+// isOverdueWorkdays 按表所属工作空间的工作日历计算
+func buildViewFilterCondition(ctx context.Context, filter *viewVO.Filter, fields map[string]*entity.Field) (clause.Expression, error) {
+	var calendar *businesscalendar.Calendar
+	if !filter.IsEmpty() {
+		for _, item := range filter.Filters {
+			if field := fields[item.FieldID]; field != nil && item.Operator == viewVO.FilterItemOpIsOverdueWorkdays {
+				calendar = businesscalendar.ForTable(ctx, field.TableID())
+				break
+			}
+		}
+	}
+	return buildViewFilterConditionAt(filter, fields, userlocale.Location(ctx), time.Now(), calendar)
+}
+
+// buildViewFilterConditionAt 在指定的用户时区、当前时间与工作日历（为空时为默认日历）下转换视图过滤器
+func buildViewFilterConditionAt(filter *viewVO.Filter, fields map[string]*entity.Field, userLoc *time.Location, now time.Time, calendar *businesscalendar.Calendar) (clause.Expression, error) {
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+
+	parts := make([]string, 0, len(filter.Filters))
+	var vars []interface{}
+	for _, item := range filter.Filters {
+		field, ok := fields[item.FieldID]
+		if !ok {
+			continue
+		}
+		kind := columnKindOf(field)
+		col := clause.Column{Name: field.DBFieldName().String()}
+		var (
+			sql      string
+			itemVars []interface{}
+			err      error
+		)
+		if item.Operator == viewVO.FilterItemOpIsOverdueWorkdays {
+			sql, itemVars, err = overdueWorkdaysSQL(item, field, col, kind, userLoc, now, calendar)
+			ok = true
+		} else {
+			sql, itemVars, ok, err = relativeDateSQL(item, field, col, kind, userLoc, now)
+		}
+		if !ok && err == nil {
+			sql, itemVars, err = filterItemSQL(filterOperand(item, field, kind), col, kind)
+		}
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, "("+sql+")")
+		vars = append(vars, itemVars...)
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	joiner := " AND "
+	if filter.Operator == viewVO.FilterOperatorOr {
+		joiner = " OR "
+	}
+	return clause.Expr{SQL: strings.Join(parts, joiner), Vars: vars}, nil
+}
+
+// filterOperand 将过滤值规范为列的存储类型（数字、布尔、时间、引用ID），文本匹配与空值判断保持原值；
+// 用户、附件等 JSON 列存储的是对象，ID 转换为 {"id": ..} 以便按包含关系匹配
+func filterOperand(item viewVO.FilterItem, field *entity.Field, kind columnKind) viewVO.FilterItem {
+	switch item.Operator {
+	case viewVO.FilterItemOpContains, viewVO.FilterItemOpNotContains,
+		viewVO.FilterItemOpIsEmpty, viewVO.FilterItemOpIsNotEmpty:
+		return item
+	}
+	if kind == columnKindLocation {
+		return item
+	}
+	fieldType := field.Type().String()
+	item.Value = cellvalue.FilterOperand(fieldType, item.Value)
+	if kind == columnKindJSON && cellvalue.IsReference(fieldType) {
+		ids := filterValues(item.Value)
+		refs := make([]interface{}, len(ids))
+		for i, id := range ids {
+			refs[i] = map[string]interface{}{"id": id}
+		}
+		item.Value = refs
+	}
+	return item
+}
+
+// filterItemSQL 单个过滤项的 SQL 片段（列引用通过 clause.Column 传入，由 GORM 负责转义）
+func filterItemSQL(item viewVO.FilterItem, col clause.Column, kind columnKind) (string, []interface{}, error) {
+	if kind == columnKindLocation {
+		return locationFilterSQL(item, col)
+	}
+
+	switch item.Operator {
+	case viewVO.FilterItemOpIsEmpty:
+		return emptySQL(col, kind)
+	case viewVO.FilterItemOpIsNotEmpty:
+		sql, vars, _ := emptySQL(col, kind)
+		return "NOT (" + sql + ")", vars, nil
+
+	case viewVO.FilterItemOpIs:
+		return equalSQL(col, kind, item.Value)
+	case viewVO.FilterItemOpIsNot:
+		sql, vars, err := equalSQL(col, kind, item.Value)
+		return "? IS NULL OR NOT (" + sql + ")", append([]interface{}{col}, vars...), err
+
+	case viewVO.FilterItemOpContains:
+		return "CAST(? AS TEXT) ILIKE ?", []interface{}{col, likePattern(item.Value)}, nil
+	case viewVO.FilterItemOpNotContains:
+		return "? IS NULL OR CAST(? AS TEXT) NOT ILIKE ?", []interface{}{col, col, likePattern(item.Value)}, nil
+
+	case viewVO.FilterItemOpGreater, viewVO.FilterItemOpIsAfter:
+		return "? > ?", []interface{}{col, item.Value}, nil
+	case viewVO.FilterItemOpGreaterEqual:
+		return "? >= ?", []interface{}{col, item.Value}, nil
+	case viewVO.FilterItemOpLess, viewVO.FilterItemOpIsBefore:
+		return "? < ?", []interface{}{col, item.Value}, nil
+	case viewVO.FilterItemOpLessEqual:
+		return "? <= ?", []interface{}{col, item.Value}, nil
+	case viewVO.FilterItemOpIsWithin:
+		values := filterValues(item.Value)
+		if len(values) != 2 {
+			return "", nil, pkgerrors.ErrValidationFailed.WithDetails(
+				fmt.Sprintf("字段 %s 的 isWithin 过滤需要 [开始, 结束] 两个值", item.FieldID))
+		}
+		return "? BETWEEN ? AND ?", []interface{}{col, values[0], values[1]}, nil
+
+	case viewVO.FilterItemOpHasAnyOf:
+		return anyOfSQL(col, kind, filterValues(item.Value))
+	case viewVO.FilterItemOpHasNoneOf:
+		sql, vars, err := anyOfSQL(col, kind, filterValues(item.Value))
+		return "? IS NULL OR NOT (" + sql + ")", append([]interface{}{col}, vars...), err
+	case viewVO.FilterItemOpHasAllOf:
+		return allOfSQL(col, kind, filterValues(item.Value), false)
+	case viewVO.FilterItemOpIsExactly:
+		return allOfSQL(col, kind, filterValues(item.Value), true)
+	case viewVO.FilterItemOpIsNotExactly:
+		sql, vars, err := allOfSQL(col, kind, filterValues(item.Value), true)
+		return "? IS NULL OR NOT (" + sql + ")", append([]interface{}{col}, vars...), err
+	}
+
+	return "", nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的过滤操作符: %s", item.Operator))
+}
+
+// relativeDateSQL 日期列上使用相对日期关键字的过滤项：关键字在字段生效的时区中解析为 [开始, 结束) 范围，
+// is 匹配整个范围，isBefore / isAfter 与范围边界比较；isWithin 接受单个关键字（pastWeek 等）
+// 或首尾可为关键字的 [开始, 结束]。不含关键字的过滤项返回 ok=false，按普通过滤处理
+func relativeDateSQL(item viewVO.FilterItem, field *entity.Field, col clause.Column, kind columnKind, userLoc *time.Location, now time.Time) (string, []interface{}, bool, error) {
+	if kind != columnKindDate {
+		return "", nil, false, nil
+	}
+	var dateOptions *fieldVO.DateOptions
+	if options := field.Options(); options != nil {
+		dateOptions = options.Date
+	}
+	loc := dateOptions.Location(userLoc)
+	dateOnly := strings.ToUpper(field.DBFieldType()) == "DATE"
+	bound := func(t time.Time) interface{} {
+		if dateOnly {
+			return t.Format("2006-01-02")
+		}
+		return t
+	}
+
+	if item.Operator == viewVO.FilterItemOpIsWithin {
+		if r, ok := viewVO.ResolveRelativeDate(item.Value, now, loc); ok {
+			return "? >= ? AND ? < ?", []interface{}{col, bound(r.Start), col, bound(r.End)}, true, nil
+		}
+		values := filterValues(item.Value)
+		if len(values) != 2 || (!viewVO.IsRelativeDate(values[0]) && !viewVO.IsRelativeDate(values[1])) {
+			return "", nil, false, nil
+		}
+		var start, end interface{}
+		if r, ok := viewVO.ResolveRelativeDate(values[0], now, loc); ok {
+			start = bound(r.Start)
+		} else {
+			start = cellvalue.FilterOperand(field.Type().String(), values[0])
+		}
+		if r, ok := viewVO.ResolveRelativeDate(values[1], now, loc); ok {
+			return "? >= ? AND ? < ?", []interface{}{col, start, col, bound(r.End)}, true, nil
+		}
+		end = cellvalue.FilterOperand(field.Type().String(), values[1])
+		return "? >= ? AND ? <= ?", []interface{}{col, start, col, end}, true, nil
+	}
+
+	r, ok := viewVO.ResolveRelativeDate(item.Value, now, loc)
+	if !ok {
+		return "", nil, false, nil
+	}
+	inRange := "? >= ? AND ? < ?"
+	rangeVars := []interface{}{col, bound(r.Start), col, bound(r.End)}
+	if r.Start.Equal(r.End) { // now：时间点
+		inRange, rangeVars = "? = ?", []interface{}{col, bound(r.Start)}
+	}
+	switch item.Operator {
+	case viewVO.FilterItemOpIs:
+		return inRange, rangeVars, true, nil
+	case viewVO.FilterItemOpIsNot:
+		return "? IS NULL OR NOT (" + inRange + ")", append([]interface{}{col}, rangeVars...), true, nil
+	case viewVO.FilterItemOpIsBefore, viewVO.FilterItemOpLess:
+		return "? < ?", []interface{}{col, bound(r.Start)}, true, nil
+	case viewVO.FilterItemOpLessEqual:
+		if r.Start.Equal(r.End) {
+			return "? <= ?", []interface{}{col, bound(r.End)}, true, nil
+		}
+		return "? < ?", []interface{}{col, bound(r.End)}, true, nil
+	case viewVO.FilterItemOpIsAfter, viewVO.FilterItemOpGreater:
+		if r.Start.Equal(r.End) {
+			return "? > ?", []interface{}{col, bound(r.End)}, true, nil
+		}
+		return "? >= ?", []interface{}{col, bound(r.End)}, true, nil
+	case viewVO.FilterItemOpGreaterEqual:
+		return "? >= ?", []interface{}{col, bound(r.Start)}, true, nil
+	}
+	return "", nil, false, nil
+}
+
+// overdueWorkdaysSQL isOverdueWorkdays：日期早于今天往前数 N 个工作日的那一天（N 为 0 时即早于今天）。
+// 今天按字段生效的时区确定，工作日按工作日历计算
+func overdueWorkdaysSQL(item viewVO.FilterItem, field *entity.Field, col clause.Column, kind columnKind, userLoc *time.Location, now time.Time, calendar *businesscalendar.Calendar) (string, []interface{}, error) {
+	if kind != columnKindDate {
+		return "", nil, pkgerrors.ErrValidationFailed.WithDetails(
+			fmt.Sprintf("字段 %s 不是日期字段，不能使用 isOverdueWorkdays 过滤", item.FieldID))
+	}
+	n, err := viewVO.ParseOverdueWorkdays(item.Value)
+	if err != nil {
+		return "", nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if calendar == nil {
+		calendar = businesscalendar.Default("")
+	}
+	var dateOptions *fieldVO.DateOptions
+	if options := field.Options(); options != nil {
+		dateOptions = options.Date
+	}
+	today, _ := viewVO.ResolveRelativeDate(viewVO.RelativeDateToday, now, dateOptions.Location(userLoc))
+	cutoff, ok := calendar.AddWorkdays(today.Start, -n)
+	if !ok {
+		cutoff = today.Start.AddDate(0, 0, -n)
+	}
+	if strings.ToUpper(field.DBFieldType()) == "DATE" {
+		return "? < ?", []interface{}{col, cutoff.Format("2006-01-02")}, nil
+	}
+	return "? < ?", []interface{}{col, cutoff}, nil
+}
+
+// locationPointSQL 位置列对应的 earth 坐标，与字段创建时的 GiST 表达式索引一致
+const locationPointSQL = "ll_to_earth((? ->> 'lat')::float8, (? ->> 'lng')::float8)"
+
+// locationFilterSQL 位置列的过滤条件
+// 半径查询先用 earth_box 走索引粗筛，再用 earth_distance 精确判断（需要 cube 与 earthdistance 扩展）
+func locationFilterSQL(item viewVO.FilterItem, col clause.Column) (string, []interface{}, error) {
+	switch item.Operator {
+	case viewVO.FilterItemOpIsEmpty:
+		return "? IS NULL OR ? = 'null'::jsonb", []interface{}{col, col}, nil
+	case viewVO.FilterItemOpIsNotEmpty:
+		return "? IS NOT NULL AND ? <> 'null'::jsonb", []interface{}{col, col}, nil
+
+	case viewVO.FilterItemOpContains:
+		return "? ->> 'address' ILIKE ?", []interface{}{col, likePattern(item.Value)}, nil
+	case viewVO.FilterItemOpNotContains:
+		return "? IS NULL OR COALESCE(? ->> 'address', '') NOT ILIKE ?", []interface{}{col, col, likePattern(item.Value)}, nil
+
+	case viewVO.FilterItemOpIsWithinRadius:
+		r, err := viewVO.ParseGeoRadius(item.Value)
+		if err != nil {
+			return "", nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的半径过滤无效: %v", item.FieldID, err))
+		}
+		sql := "earth_box(ll_to_earth(?, ?), ?) @> " + locationPointSQL +
+			" AND earth_distance(ll_to_earth(?, ?), " + locationPointSQL + ") <= ?"
+		return sql, []interface{}{r.Lat, r.Lng, r.Radius, col, col, r.Lat, r.Lng, col, col, r.Radius}, nil
+
+	case viewVO.FilterItemOpIsInBoundingBox:
+		b, err := viewVO.ParseGeoBoundingBox(item.Value)
+		if err != nil {
+			return "", nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段 %s 的矩形范围过滤无效: %v", item.FieldID, err))
+		}
+		lngOp := " AND "
+		if b.CrossesAntimeridian() {
+			lngOp = " OR "
+		}
+		sql := "(? ->> 'lat')::float8 BETWEEN ? AND ? AND ((? ->> 'lng')::float8 >= ?" + lngOp + "(? ->> 'lng')::float8 <= ?)"
+		return sql, []interface{}{col, b.South, b.North, col, b.West, col, b.East}, nil
+	}
+
+	return "", nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("位置字段不支持过滤操作符: %s", item.Operator))
+}
+
+// emptySQL 判断单元格为空（文本空串、空数组同样视为空）
+func emptySQL(col clause.Column, kind columnKind) (string, []interface{}, error) {
+	switch kind {
+	case columnKindJSON:
+		return "? IS NULL OR ? IN ('[]'::jsonb, 'null'::jsonb, '\"\"'::jsonb)", []interface{}{col, col}, nil
+	case columnKindArray:
+		return "? IS NULL OR cardinality(?) = 0", []interface{}{col, col}, nil
+	case columnKindText:
+		return "? IS NULL OR CAST(? AS TEXT) = ''", []interface{}{col, col}, nil
+	case columnKindBoolean:
+		// 复选框未勾选即为空
+		return "? IS NULL OR ? = FALSE", []interface{}{col, col}, nil
+	default:
+		return "? IS NULL", []interface{}{col}, nil
+	}
+}
+
+// equalSQL 等值比较
+func equalSQL(col clause.Column, kind columnKind, value interface{}) (string, []interface{}, error) {
+	switch kind {
+	case columnKindJSON, columnKindArray:
+		return allOfSQL(col, kind, filterValues(value), true)
+	case columnKindDate:
+		// 日期按天比较
+		return "CAST(? AS DATE) = CAST(? AS DATE)", []interface{}{col, value}, nil
+	case columnKindBoolean:
+		return "COALESCE(?, FALSE) = ?", []interface{}{col, value}, nil
+	case columnKindText:
+		return "CAST(? AS TEXT) = ?", []interface{}{col, fmt.Sprint(value)}, nil
+	default:
+		return "? = ?", []interface{}{col, value}, nil
+	}
+}
+
+// anyOfSQL 包含任意一个值
+func anyOfSQL(col clause.Column, kind columnKind, values []interface{}) (string, []interface{}, error) {
+	if len(values) == 0 {
+		return "FALSE", nil, nil
+	}
+	switch kind {
+	case columnKindJSON:
+		parts := make([]string, 0, len(values))
+		var vars []interface{}
+		for _, v := range values {
+			element, err := jsonArrayLiteral([]interface{}{v})
+			if err != nil {
+				return "", nil, err
+			}
+			arr, arrVars := jsonArrayColumn(col, values)
+			parts = append(parts, arr+" @> ?::jsonb")
+			vars = append(append(vars, arrVars...), element)
+		}
+		return strings.Join(parts, " OR "), vars, nil
+	case columnKindArray:
+		parts := make([]string, 0, len(values))
+		var vars []interface{}
+		for _, v := range values {
+			parts = append(parts, "? = ANY(?)")
+			vars = append(vars, fmt.Sprint(v), col)
+		}
+		return strings.Join(parts, " OR "), vars, nil
+	default:
+		return "CAST(? AS TEXT) IN ?", []interface{}{col, stringValues(values)}, nil
+	}
+}
+
+// allOfSQL 包含全部值；exact 为 true 时要求不多不少
+func allOfSQL(col clause.Column, kind columnKind, values []interface{}, exact bool) (string, []interface{}, error) {
+	switch kind {
+	case columnKindJSON:
+		literal, err := jsonArrayLiteral(values)
+		if err != nil {
+			return "", nil, err
+		}
+		arr, arrVars := jsonArrayColumn(col, values)
+		switch {
+		case exact && hasObjectValues(values):
+			// 存储的对象带有标题等其他属性，不能用 <@ 判断，改为比较元素个数
+			vars := append(append(append([]interface{}{}, arrVars...), literal), arrVars...)
+			return arr + " @> ?::jsonb AND jsonb_array_length(" + arr + ") = ?", append(vars, len(values)), nil
+		case exact:
+			return "? @> ?::jsonb AND ? <@ ?::jsonb", []interface{}{col, literal, col, literal}, nil
+		}
+		return arr + " @> ?::jsonb", append(arrVars, literal), nil
+	case columnKindArray:
+		parts := make([]string, 0, len(values)+1)
+		var vars []interface{}
+		for _, v := range values {
+			parts = append(parts, "? = ANY(?)")
+			vars = append(vars, fmt.Sprint(v), col)
+		}
+		if exact {
+			parts = append(parts, "cardinality(?) = ?")
+			vars = append(vars, col, len(values))
+		}
+		if len(parts) == 0 {
+			return "TRUE", nil, nil
+		}
+		return strings.Join(parts, " AND "), vars, nil
+	default:
+		// 单值列：只有在所有值相同时才可能同时满足
+		strs := stringValues(values)
+		if len(strs) == 0 {
+			return "TRUE", nil, nil
+		}
+		parts := make([]string, 0, len(strs))
+		var vars []interface{}
+		for _, v := range strs {
+			parts = append(parts, "CAST(? AS TEXT) = ?")
+			vars = append(vars, col, v)
+		}
+		return strings.Join(parts, " AND "), vars, nil
+	}
+}
+
+// filterValues 将过滤值统一为列表
+func filterValues(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, s := range v {
+			values[i] = s
+		}
+		return values
+	default:
+		return []interface{}{v}
+	}
+}
+
+// jsonArrayColumn 按对象匹配时 JSON 列的数组表达式：单个对象（如单用户）视为只有一个元素的数组，
+// 其他非数组值视为空数组；按标量匹配时直接使用列
+func jsonArrayColumn(col clause.Column, values []interface{}) (string, []interface{}) {
+	if !hasObjectValues(values) {
+		return "?", []interface{}{col}
+	}
+	return "(CASE WHEN jsonb_typeof(?) = 'object' THEN jsonb_build_array(?) WHEN jsonb_typeof(?) = 'array' THEN ? ELSE '[]'::jsonb END)",
+		[]interface{}{col, col, col, col}
+}
+
+func hasObjectValues(values []interface{}) bool {
+	for _, v := range values {
+		if _, ok := v.(map[string]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValues(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, fmt.Sprint(v))
+	}
+	return strs
+}
+
+func jsonArrayLiteral(values []interface{}) (string, error) {
+	if values == nil {
+		values = []interface{}{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的过滤值: %v", err))
+	}
+	return string(data), nil
+}
+
+// likePattern 构造 ILIKE 子串匹配模式（转义通配符）
+func likePattern(value interface{}) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(fmt.Sprint(value)) + "%"
+}
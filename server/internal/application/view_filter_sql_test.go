@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func TestBuildViewFilterCondition_NormalizesOperands(t *testing.T) {
+	amount := newTemplateTestField(t, "数量", fieldVO.TypeNumber)
+	owner := newTemplateTestField(t, "负责人", fieldVO.TypeUser)
+	byID := map[string]*fieldEntity.Field{amount.ID().String(): amount, owner.ID().String(): owner}
+
+	expr, err := buildViewFilterCondition(context.Background(), &viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+		{FieldID: amount.ID().String(), Operator: viewVO.FilterItemOpGreater, Value: "10"},
+		{FieldID: owner.ID().String(), Operator: viewVO.FilterItemOpIsExactly, Value: []interface{}{"usr1"}},
+	}}, byID)
+	if err != nil {
+		t.Fatalf("buildViewFilterCondition: %v", err)
+	}
+	cond := expr.(clause.Expr)
+
+	// 数字字符串按数字比较
+	if cond.Vars[1] != 10.0 {
+		t.Fatalf("expected numeric operand, got %#v", cond.Vars[1])
+	}
+	// 用户列存储对象：按 {"id": ..} 包含匹配，并以元素个数判断完全相等
+	var literal string
+	for _, v := range cond.Vars {
+		if s, ok := v.(string); ok {
+			literal = s
+		}
+	}
+	if literal != `[{"id":"usr1"}]` {
+		t.Fatalf("expected user ID wrapped as object, got %q (vars %v)", literal, cond.Vars)
+	}
+	if !strings.Contains(cond.SQL, "jsonb_array_length") || strings.Contains(cond.SQL, "<@") {
+		t.Fatalf("expected exact match by element count, got %s", cond.SQL)
+	}
+}
+
+func TestBuildViewFilterCondition_RelativeDateInUserTimeZone(t *testing.T) {
+	due := newTemplateTestField(t, "截止时间", fieldVO.TypeDateTime)
+	day := newTemplateTestField(t, "日期", fieldVO.TypeDate)
+	byID := map[string]*fieldEntity.Field{due.ID().String(): due, day.ID().String(): day}
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	// UTC 3月1日 18:30 在上海已是 3月2日
+	now := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+
+	expr, err := buildViewFilterConditionAt(&viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+		{FieldID: due.ID().String(), Operator: viewVO.FilterItemOpIs, Value: "today"},
+	}}, byID, shanghai, now, nil)
+	if err != nil {
+		t.Fatalf("buildViewFilterConditionAt: %v", err)
+	}
+	cond := expr.(clause.Expr)
+	var bounds []time.Time
+	for _, v := range cond.Vars {
+		if ts, ok := v.(time.Time); ok {
+			bounds = append(bounds, ts)
+		}
+	}
+	start := time.Date(2024, 3, 2, 0, 0, 0, 0, shanghai)
+	if len(bounds) != 2 || !bounds[0].Equal(start) || !bounds[1].Equal(start.AddDate(0, 0, 1)) {
+		t.Fatalf("expected today in Asia/Shanghai, got %v (sql %s)", bounds, cond.SQL)
+	}
+
+	// DATE 列按用户时区的日历日比较
+	expr, err = buildViewFilterConditionAt(&viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+		{FieldID: day.ID().String(), Operator: viewVO.FilterItemOpIs, Value: "today"},
+	}}, byID, shanghai, now, nil)
+	if err != nil {
+		t.Fatalf("buildViewFilterConditionAt: %v", err)
+	}
+	if vars := expr.(clause.Expr).Vars; vars[1] != "2024-03-02" || vars[3] != "2024-03-03" {
+		t.Fatalf("expected calendar day bounds, got %#v", vars)
+	}
+
+	// 开启统一时区后以字段时区为准
+	if err := due.UpdateOptions(&fieldVO.FieldOptions{Date: &fieldVO.DateOptions{TimeZone: "UTC", UseSameTimeZone: true}}); err != nil {
+		t.Fatalf("UpdateOptions: %v", err)
+	}
+	expr, err = buildViewFilterConditionAt(&viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+		{FieldID: due.ID().String(), Operator: viewVO.FilterItemOpIsBefore, Value: "today"},
+	}}, byID, shanghai, now, nil)
+	if err != nil {
+		t.Fatalf("buildViewFilterConditionAt: %v", err)
+	}
+	cond = expr.(clause.Expr)
+	if ts, ok := cond.Vars[len(cond.Vars)-1].(time.Time); !ok || !ts.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected UTC start of day, got %#v", cond.Vars)
+	}
+}
+
+func TestBuildViewFilterCondition_OverdueWorkdays(t *testing.T) {
+	due := newTemplateTestField(t, "截止日期", fieldVO.TypeDate)
+	byID := map[string]*fieldEntity.Field{due.ID().String(): due}
+	// 2024-10-08（周二）为假期后的第一个工作日，上一个工作日是 9 月 30 日
+	calendar, err := businesscalendar.NewCalendar("spc_1", nil,
+		[]string{"2024-10-01", "2024-10-02", "2024-10-03", "2024-10-04", "2024-10-07"}, nil, "usr_1", time.Now())
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+	now := time.Date(2024, 10, 8, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		value interface{}
+		want  string
+	}{
+		{nil, "2024-10-08"},
+		{1.0, "2024-09-30"},
+		{"2", "2024-09-27"},
+	}
+	for _, c := range cases {
+		expr, err := buildViewFilterConditionAt(&viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+			{FieldID: due.ID().String(), Operator: viewVO.FilterItemOpIsOverdueWorkdays, Value: c.value},
+		}}, byID, time.UTC, now, calendar)
+		if err != nil {
+			t.Fatalf("buildViewFilterConditionAt(%v): %v", c.value, err)
+		}
+		if vars := expr.(clause.Expr).Vars; vars[1] != c.want {
+			t.Errorf("overdue by %v workdays: expected cutoff %s, got %#v", c.value, c.want, vars)
+		}
+	}
+
+	if _, err := buildViewFilterConditionAt(&viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+		{FieldID: due.ID().String(), Operator: viewVO.FilterItemOpIsOverdueWorkdays, Value: -1.0},
+	}}, byID, time.UTC, now, calendar); err == nil {
+		t.Error("negative workdays should be rejected")
+	}
+}
@@ -0,0 +1,942 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/viewprojection"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+var viewProjectionLog = logger.Named("view_projection")
+
+const (
+	// viewProjectionDefaultLimit 默认每页行数
+	viewProjectionDefaultLimit = 100
+	// viewProjectionMaxLimit 每页最大行数
+	viewProjectionMaxLimit = 1000
+	// viewProjectionTouchInterval 读取时间的最小更新间隔（避免每次读取都写库）
+	viewProjectionTouchInterval = time.Minute
+)
+
+// errViewProjectionNeedsRebuild 增量维护无法继续，需要全量重建
+var errViewProjectionNeedsRebuild = errors.New("view projection needs rebuild")
+
+// ViewDisplayField 展示页中的字段
+type ViewDisplayField struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ViewDisplayRow 展示页中的一行
+type ViewDisplayRow struct {
+	ID    string                          `json:"id"`
+	Cells map[string]*viewprojection.Cell `json:"cells"`
+}
+
+// ViewDisplayPage 按视图顺序读取的一页展示行
+type ViewDisplayPage struct {
+	ViewID    string             `json:"viewId"`
+	TableID   string             `json:"tableId"`
+	Fields    []ViewDisplayField `json:"fields"`
+	Rows      []ViewDisplayRow   `json:"rows"`
+	Total     int                `json:"total"`
+	Offset    int                `json:"offset"`
+	Limit     int                `json:"limit"`
+	Truncated bool               `json:"truncated"` // 视图记录超过投影上限，只能读取前面的记录
+	BuiltAt   time.Time          `json:"builtAt"`
+}
+
+// projectionLink 关联字段的展示来源
+type projectionLink struct {
+	tableID        string
+	displayFieldID string // 关联表中作为标题的字段
+	sameBase       bool   // 跨 Base 关联不读取关联表，只使用单元格中已有的标题
+}
+
+// projectionSource 投影所需的视图配置
+type projectionSource struct {
+	view      *viewEntity.View
+	baseID    string
+	fields    []*fieldEntity.Field // 可见字段（按字段顺序）
+	condition clause.Expression    // 视图过滤条件
+	sort      []clause.OrderByColumn
+	ordered   bool                       // 记录顺序或范围依赖单元格值（有过滤或排序）
+	links     map[string]*projectionLink // 字段ID -> 关联展示来源
+	linked    map[string]bool            // 同一 Base 内被关联的表
+}
+
+// ViewProjectionService 视图展示投影服务 ✨
+// 为表格渲染按视图维护展示就绪的行：单元格中的选项名称与颜色、协作者姓名、
+// 关联记录标题都预先解析好，列表分页读取时不再逐次关联查询。
+//   - 首次读取时按视图过滤与排序建立记录顺序，行在读取到时解析并保存；
+//   - 之后按 Base 变更流增量维护：记录变化只重新解析该行，关联记录变化时刷新引用它的行，
+//     有过滤或排序的视图在记录变化后重新查询顺序；
+//   - 表或关联表的字段变化、视图配置变化、游标过期或超过最长保留时间时全量重建；
+//   - 后台定期维护最近读取过的投影，长期未读取的投影被清理。
+//
+// 投影保存原始值，脱敏在读取时按当前用户执行；关联记录标题按推送规则一律脱敏后保存
+type ViewProjectionService struct {
+	repo              viewprojection.Repository
+	changeFeed        *ChangeFeedService
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	recordRepo        recordRepo.RecordRepository
+	userRepo          userRepo.UserRepository
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	cfg               config.ViewProjectionConfig
+
+	// 按视图过滤与排序查询记录ID（最多 MaxRows+1 条，测试中替换）
+	selectIDs func(ctx context.Context, source *projectionSource) ([]string, error)
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex // 视图ID -> 维护锁
+}
+
+// NewViewProjectionService 创建视图展示投影服务
+func NewViewProjectionService(
+	repo viewprojection.Repository,
+	changeFeed *ChangeFeedService,
+	viewRepository viewRepo.ViewRepository,
+	fieldRepo repository.FieldRepository,
+	tableRepository tableRepo.TableRepository,
+	recordRepository recordRepo.RecordRepository,
+	userRepository userRepo.UserRepository,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	cfg config.ViewProjectionConfig,
+) *ViewProjectionService {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 30 * time.Second
+	}
+	if cfg.IdleTTL <= 0 {
+		cfg.IdleTTL = 24 * time.Hour
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = time.Hour
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = 50000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	s := &ViewProjectionService{
+		repo:              repo,
+		changeFeed:        changeFeed,
+		viewRepo:          viewRepository,
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepository,
+		recordRepo:        recordRepository,
+		userRepo:          userRepository,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		cfg:               cfg,
+		locks:             make(map[string]*sync.Mutex),
+	}
+	s.selectIDs = s.physicalSelectIDs
+	return s
+}
+
+// GetRows 按视图顺序读取一页展示行（投影落后时先增量追赶）
+func (s *ViewProjectionService) GetRows(ctx context.Context, userID, viewID string, offset, limit int) (*ViewDisplayPage, error) {
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	if s.permissionService != nil && !s.permissionService.CanReadView(ctx, userID, viewID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = viewProjectionDefaultLimit
+	}
+	if limit > viewProjectionMaxLimit {
+		limit = viewProjectionMaxLimit
+	}
+
+	source, p, rows, err := s.readPage(ctx, view, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.maskRows(ctx, view.TableID(), rows)
+
+	if time.Since(p.ReadAt) > viewProjectionTouchInterval {
+		if err := s.repo.Touch(ctx, viewID, time.Now()); err != nil {
+			viewProjectionLog.Warn(ctx, "更新投影读取时间失败", logger.String("view_id", viewID), logger.ErrorField(err))
+		}
+	}
+
+	page := &ViewDisplayPage{
+		ViewID:    viewID,
+		TableID:   view.TableID(),
+		Fields:    make([]ViewDisplayField, 0, len(source.fields)),
+		Rows:      make([]ViewDisplayRow, 0, len(rows)),
+		Total:     len(p.RecordIDs),
+		Offset:    offset,
+		Limit:     limit,
+		Truncated: p.Truncated,
+		BuiltAt:   p.BuiltAt,
+	}
+	for _, field := range source.fields {
+		page.Fields = append(page.Fields, ViewDisplayField{
+			ID:   field.ID().String(),
+			Name: field.Name().String(),
+			Type: field.Type().String(),
+		})
+	}
+	for _, row := range rows {
+		page.Rows = append(page.Rows, ViewDisplayRow{ID: row.RecordID, Cells: row.Cells})
+	}
+	return page, nil
+}
+
+// readPage 追赶投影后读取一页行，尚未解析的行在此解析并保存
+func (s *ViewProjectionService) readPage(ctx context.Context, view *viewEntity.View, offset, limit int) (*projectionSource, *viewprojection.Projection, []*viewprojection.Row, error) {
+	unlock := s.lock(view.ID())
+	defer unlock()
+
+	source, err := s.loadSource(ctx, view)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p, err := s.refresh(ctx, source)
+	if err != nil {
+		return nil, nil, nil, pkgerrors.Database(err, "维护视图投影失败")
+	}
+
+	ids := p.Page(offset, limit)
+	stored, err := s.repo.FindRows(ctx, p.ViewID, ids)
+	if err != nil {
+		return nil, nil, nil, pkgerrors.Database(err, "读取视图投影失败")
+	}
+	byID := make(map[string]*viewprojection.Row, len(stored))
+	for _, row := range stored {
+		byID[row.RecordID] = row
+	}
+	var missing []string
+	for _, id := range ids {
+		if byID[id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		resolved, err := s.resolveRows(ctx, source, missing)
+		if err != nil {
+			return nil, nil, nil, pkgerrors.Database(err, "解析视图投影失败")
+		}
+		if err := s.repo.SaveRows(ctx, resolved); err != nil {
+			return nil, nil, nil, pkgerrors.Database(err, "保存视图投影失败")
+		}
+		for _, row := range resolved {
+			byID[row.RecordID] = row
+		}
+	}
+
+	// 按视图顺序返回（记录已删除但投影尚未追赶到的行被跳过）
+	rows := make([]*viewprojection.Row, 0, len(ids))
+	for _, id := range ids {
+		if row := byID[id]; row != nil {
+			rows = append(rows, row)
+		}
+	}
+	return source, p, rows, nil
+}
+
+// maskRows 按当前用户角色对受脱敏策略保护的单元格脱敏（原地修改）
+func (s *ViewProjectionService) maskRows(ctx context.Context, tableID string, rows []*viewprojection.Row) {
+	if s.privacyService == nil || len(rows) == 0 {
+		return
+	}
+	protected := s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	if len(protected) == 0 {
+		return
+	}
+	records := make([]*dto.RecordResponse, 0, len(rows))
+	for _, row := range rows {
+		data := make(map[string]interface{}, len(protected))
+		for fieldID := range protected {
+			if cell, ok := row.Cells[fieldID]; ok {
+				data[fieldID] = cell.Value
+			}
+		}
+		records = append(records, &dto.RecordResponse{ID: row.RecordID, TableID: tableID, Data: data})
+	}
+	s.privacyService.MaskRecords(ctx, tableID, records...)
+	for i, row := range rows {
+		for fieldID := range protected {
+			if _, ok := row.Cells[fieldID]; ok {
+				masked := records[i].Data[fieldID]
+				row.Cells[fieldID] = &viewprojection.Cell{Value: masked, Text: displayText(masked)}
+			}
+		}
+	}
+}
+
+func (s *ViewProjectionService) lock(viewID string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[viewID]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[viewID] = l
+	}
+	s.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// refresh 返回追赶到变更流最新位置的投影，必要时全量重建
+func (s *ViewProjectionService) refresh(ctx context.Context, source *projectionSource) (*viewprojection.Projection, error) {
+	p, err := s.repo.Find(ctx, source.view.ID())
+	if err != nil {
+		return nil, err
+	}
+	if p != nil && p.TableID == source.view.TableID() && time.Since(p.BuiltAt) < s.cfg.MaxAge {
+		err = s.catchUp(ctx, source, p)
+		if !errors.Is(err, errViewProjectionNeedsRebuild) {
+			return p, err
+		}
+	}
+	return s.rebuild(ctx, source, p)
+}
+
+// rebuild 全量重建：重新查询记录顺序并丢弃已解析的行（行在读取到时重新解析）
+// 查询前先记录变更流游标，之后从该游标追赶，不会遗漏查询期间的变更
+func (s *ViewProjectionService) rebuild(ctx context.Context, source *projectionSource, previous *viewprojection.Projection) (*viewprojection.Projection, error) {
+	head, err := s.changeFeed.repo.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.selectIDs(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		if err := s.repo.Delete(ctx, previous.ViewID); err != nil {
+			return nil, err
+		}
+	}
+
+	p := viewprojection.NewProjection(source.view.ID(), source.baseID, source.view.TableID())
+	if previous != nil {
+		p.ReadAt = previous.ReadAt
+	}
+	p.RecordIDs, p.Truncated = s.capRows(ids)
+	p.Cursor = head
+	p.BuiltAt = time.Now()
+	if err := s.repo.Save(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// catchUp 从投影游标读取变更并增量维护，每批处理后保存进度
+func (s *ViewProjectionService) catchUp(ctx context.Context, source *projectionSource, p *viewprojection.Projection) error {
+	tableID := source.view.TableID()
+	for {
+		changes, err := s.changeFeed.repo.ListAfter(ctx, p.BaseID, changefeed.ListFilter{
+			After: p.Cursor,
+			Limit: s.cfg.BatchSize,
+		})
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			return errViewProjectionNeedsRebuild
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		dirty := make(map[string]bool)
+		removed := make(map[string]bool)
+		var created, linkedChanged []string
+		reorder := false
+		for _, change := range changes {
+			switch change.EntityType {
+			case changefeed.EntityField:
+				if change.TableID == tableID || source.linked[change.TableID] {
+					return errViewProjectionNeedsRebuild
+				}
+			case changefeed.EntityView:
+				if change.EntityID == p.ViewID {
+					return errViewProjectionNeedsRebuild
+				}
+			case changefeed.EntityRecord:
+				if change.TableID != tableID {
+					if source.linked[change.TableID] {
+						linkedChanged = append(linkedChanged, change.EntityID)
+					}
+					continue
+				}
+				switch change.Action {
+				case changefeed.ActionDelete:
+					removed[change.EntityID] = true
+					delete(dirty, change.EntityID)
+				case changefeed.ActionCreate:
+					delete(removed, change.EntityID)
+					dirty[change.EntityID] = true
+					created = append(created, change.EntityID)
+					reorder = reorder || source.ordered
+				default:
+					dirty[change.EntityID] = true
+					reorder = reorder || source.ordered
+				}
+			}
+		}
+
+		if len(linkedChanged) > 0 {
+			referencing, err := s.repo.FindReferencing(ctx, p.ViewID, linkedChanged)
+			if err != nil {
+				return err
+			}
+			for _, id := range referencing {
+				if !removed[id] {
+					dirty[id] = true
+				}
+			}
+		}
+		if err := s.apply(ctx, source, p, dirty, removed, created, reorder); err != nil {
+			return err
+		}
+
+		p.Cursor = changes[len(changes)-1].Seq
+		p.UpdatedAt = time.Now()
+		if err := s.repo.Save(ctx, p); err != nil {
+			return err
+		}
+		if len(changes) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// apply 更新记录顺序、删除移出视图的行并重新解析变化的行
+// 有过滤或排序的视图重新查询顺序；否则新记录按创建顺序追加在末尾
+func (s *ViewProjectionService) apply(ctx context.Context, source *projectionSource, p *viewprojection.Projection, dirty, removed map[string]bool, created []string, reorder bool) error {
+	present := make(map[string]bool, len(p.RecordIDs))
+	for _, id := range p.RecordIDs {
+		present[id] = true
+	}
+
+	var order []string
+	if reorder {
+		ids, err := s.selectIDs(ctx, source)
+		if err != nil {
+			return err
+		}
+		order, p.Truncated = s.capRows(ids)
+		next := make(map[string]bool, len(order))
+		for _, id := range order {
+			next[id] = true
+			if !present[id] {
+				dirty[id] = true
+			}
+		}
+		for id := range present {
+			if !next[id] {
+				removed[id] = true
+			}
+		}
+	} else {
+		order = make([]string, 0, len(p.RecordIDs)+len(created))
+		for _, id := range p.RecordIDs {
+			if !removed[id] {
+				order = append(order, id)
+			}
+		}
+		for _, id := range created {
+			if present[id] || removed[id] {
+				continue
+			}
+			if len(order) >= s.cfg.MaxRows {
+				p.Truncated = true
+				break
+			}
+			present[id] = true
+			order = append(order, id)
+		}
+	}
+	p.RecordIDs = order
+
+	if len(removed) > 0 {
+		ids := make([]string, 0, len(removed))
+		for id := range removed {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		if err := s.repo.DeleteRows(ctx, p.ViewID, ids); err != nil {
+			return err
+		}
+	}
+
+	// 只重新解析仍在视图中的行
+	var ids []string
+	for _, id := range order {
+		if dirty[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	rows, err := s.resolveRows(ctx, source, ids)
+	if err != nil {
+		return err
+	}
+	return s.repo.SaveRows(ctx, rows)
+}
+
+func (s *ViewProjectionService) capRows(ids []string) ([]string, bool) {
+	if len(ids) > s.cfg.MaxRows {
+		return ids[:s.cfg.MaxRows], true
+	}
+	if ids == nil {
+		ids = []string{}
+	}
+	return ids, false
+}
+
+// loadSource 加载视图的可见字段、过滤排序条件与关联字段的展示来源
+func (s *ViewProjectionService) loadSource(ctx context.Context, view *viewEntity.View) (*projectionSource, error) {
+	table, err := s.tableRepo.GetByID(ctx, view.TableID())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, view.TableID())
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+
+	source := &projectionSource{
+		view:   view,
+		baseID: table.BaseID(),
+		links:  make(map[string]*projectionLink),
+		linked: make(map[string]bool),
+	}
+	byID := fieldIndex(fields)
+	hidden := embedHiddenFieldIDs(view, fields)
+	for _, field := range fields {
+		if !hidden[field.ID().String()] {
+			source.fields = append(source.fields, field)
+		}
+	}
+	sort.SliceStable(source.fields, func(i, j int) bool { return source.fields[i].Order() < source.fields[j].Order() })
+
+	if source.condition, err = buildViewFilterCondition(ctx, view.Filter(), byID); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("视图的过滤条件无效: %v", err))
+	}
+	source.sort = embedSortColumns(view.Sort(), byID)
+	source.ordered = source.condition != nil || (view.Sort() != nil && len(view.Sort().SortItems) > 0)
+
+	for _, field := range source.fields {
+		options := field.Options()
+		if field.Type().String() != fieldVO.TypeLink || options == nil || options.Link == nil {
+			continue
+		}
+		link := &projectionLink{
+			tableID:        options.Link.LinkedTableID,
+			displayFieldID: options.Link.LookupFieldID,
+			sameBase:       options.Link.BaseID == "" || options.Link.BaseID == source.baseID,
+		}
+		if link.sameBase && link.tableID != "" {
+			if link.displayFieldID == "" {
+				if link.displayFieldID, err = s.primaryFieldID(ctx, link.tableID); err != nil {
+					return nil, err
+				}
+			}
+			source.linked[link.tableID] = true
+		}
+		source.links[field.ID().String()] = link
+	}
+	return source, nil
+}
+
+func (s *ViewProjectionService) primaryFieldID(ctx context.Context, tableID string) (string, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return "", pkgerrors.Database(err, "查询关联表字段失败")
+	}
+	for _, field := range fields {
+		if field.IsPrimary() {
+			return field.ID().String(), nil
+		}
+	}
+	return "", nil
+}
+
+// resolveRows 读取记录并解析展示值；协作者与同一 Base 内的关联记录批量读取
+func (s *ViewProjectionService) resolveRows(ctx context.Context, source *projectionSource, ids []string) ([]*viewprojection.Row, error) {
+	records, err := s.fetch(ctx, source.view.TableID(), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make(map[string]bool)
+	linkIDs := make(map[string]map[string]bool) // 关联表ID -> 记录ID
+	for _, record := range records {
+		for _, field := range source.fields {
+			if isUserField(field) {
+				for _, id := range userIDsOf(field, record) {
+					userIDs[id] = true
+				}
+				continue
+			}
+			if link := source.links[field.ID().String()]; link != nil && link.sameBase {
+				if linkIDs[link.tableID] == nil {
+					linkIDs[link.tableID] = make(map[string]bool)
+				}
+				for _, id := range extractLinkIDs(record.Data[field.ID().String()]) {
+					linkIDs[link.tableID][id] = true
+				}
+			}
+		}
+	}
+	names, err := s.userNames(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	linked := make(map[string]map[string]map[string]interface{}, len(linkIDs)) // 关联表ID -> 记录ID -> 脱敏后的值
+	for tableID, set := range linkIDs {
+		if len(set) == 0 {
+			continue
+		}
+		targets, err := s.fetch(ctx, tableID, setKeys(set))
+		if err != nil {
+			return nil, err
+		}
+		linked[tableID] = make(map[string]map[string]interface{}, len(targets))
+		for _, target := range targets {
+			data := target.Data
+			if s.privacyService != nil {
+				data = s.privacyService.MaskForStream(ctx, tableID, data)
+			}
+			linked[tableID][target.ID] = data
+		}
+	}
+
+	now := time.Now()
+	rows := make([]*viewprojection.Row, 0, len(records))
+	for _, record := range records {
+		row := &viewprojection.Row{
+			ViewID:    source.view.ID(),
+			RecordID:  record.ID,
+			Cells:     make(map[string]*viewprojection.Cell, len(source.fields)),
+			UpdatedAt: now,
+		}
+		for _, field := range source.fields {
+			fieldID := field.ID().String()
+			value := record.Data[fieldID]
+			switch {
+			case isUserField(field):
+				row.Cells[fieldID] = userCell(value, userIDsOf(field, record), names)
+			case source.links[fieldID] != nil:
+				link := source.links[fieldID]
+				cell, refs := linkCell(value, link, linked[link.tableID])
+				row.Cells[fieldID] = cell
+				row.Refs = append(row.Refs, refs...)
+			default:
+				row.Cells[fieldID] = valueCell(field, value)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *ViewProjectionService) fetch(ctx context.Context, tableID string, ids []string) ([]*dto.RecordResponse, error) {
+	recordIDs := make([]recordVO.RecordID, len(ids))
+	for i, id := range ids {
+		recordIDs[i] = recordVO.NewRecordID(id)
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return nil, err
+	}
+	return dto.FromRecordEntities(records), nil
+}
+
+// userNames 批量查询协作者的展示名称（没有名称时使用邮箱）
+func (s *ViewProjectionService) userNames(ctx context.Context, ids map[string]bool) (map[string]string, error) {
+	names := make(map[string]string, len(ids))
+	if len(ids) == 0 || s.userRepo == nil {
+		return names, nil
+	}
+	users, _, err := s.userRepo.List(ctx, userRepo.UserFilter{IDs: setKeys(ids)})
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		name := u.Name()
+		if name == "" {
+			name = u.Email().String()
+		}
+		names[u.ID().String()] = name
+	}
+	return names, nil
+}
+
+// Start 启动后台维护：追赶最近读取过的投影，清理长期未读取的投影
+func (s *ViewProjectionService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.maintain(ctx)
+		}
+	}()
+}
+
+func (s *ViewProjectionService) maintain(ctx context.Context) {
+	idleBefore := time.Now().Add(-s.cfg.IdleTTL)
+	projections, err := s.repo.ListReadSince(ctx, idleBefore, 50)
+	if err != nil {
+		viewProjectionLog.Warn(ctx, "列出视图投影失败", logger.ErrorField(err))
+		return
+	}
+	for _, p := range projections {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.maintainOne(ctx, p.ViewID); err != nil {
+			viewProjectionLog.Warn(ctx, "维护视图投影失败", logger.String("view_id", p.ViewID), logger.ErrorField(err))
+		}
+	}
+	if deleted, err := s.repo.DeleteReadBefore(ctx, idleBefore); err != nil {
+		viewProjectionLog.Warn(ctx, "清理视图投影失败", logger.ErrorField(err))
+	} else if deleted > 0 {
+		viewProjectionLog.Info(ctx, "已清理长期未读取的视图投影", logger.Int("count", int(deleted)))
+	}
+}
+
+// maintainOne 追赶单个投影（视图已删除时删除投影）
+func (s *ViewProjectionService) maintainOne(ctx context.Context, viewID string) error {
+	unlock := s.lock(viewID)
+	defer unlock()
+
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return err
+	}
+	if view == nil {
+		return s.repo.Delete(ctx, viewID)
+	}
+	source, err := s.loadSource(ctx, view)
+	if err != nil {
+		return err
+	}
+	_, err = s.refresh(ctx, source)
+	return err
+}
+
+// physicalSelectIDs 在物理表上按视图过滤与排序查询记录ID
+func (s *ViewProjectionService) physicalSelectIDs(ctx context.Context, source *projectionSource) ([]string, error) {
+	tableID := source.view.TableID()
+	db := s.dataDB(ctx, source.baseID).WithContext(ctx).Table(s.dbProvider.GenerateTableName(source.baseID, tableID))
+	if source.condition != nil {
+		db = db.Where(source.condition)
+	}
+	var ids []string
+	if err := db.Clauses(clause.OrderBy{Columns: source.sort}).Limit(s.cfg.MaxRows+1).Pluck("__id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// valueCell 普通字段：选项解析名称与颜色，数字按精度格式化
+func valueCell(field *fieldEntity.Field, value interface{}) *viewprojection.Cell {
+	cell := &viewprojection.Cell{Value: value}
+	options := field.Options()
+	switch field.Type().String() {
+	case fieldVO.TypeSelect, fieldVO.TypeSingleSelect, fieldVO.TypeMultipleSelect:
+		var choices []fieldVO.SelectChoice
+		if options != nil && options.Select != nil {
+			choices = options.Select.Choices
+		}
+		titles := make([]string, 0, 1)
+		for _, token := range extractLinkIDs(value) {
+			item := viewprojection.CellItem{ID: token, Title: token}
+			for _, choice := range choices {
+				if choice.Name == token || choice.ID == token {
+					item = viewprojection.CellItem{ID: choice.ID, Title: choice.Name, Color: choice.Color}
+					break
+				}
+			}
+			cell.Items = append(cell.Items, item)
+			titles = append(titles, item.Title)
+		}
+		cell.Text = strings.Join(titles, ", ")
+		return cell
+	}
+	if plugin, ok := fieldplugin.Lookup(field.Type().String()); ok {
+		var custom map[string]interface{}
+		if options != nil {
+			custom = options.Custom
+		}
+		cell.Text = plugin.Format(value, custom)
+		return cell
+	}
+	if options != nil && options.Number != nil && options.Number.Precision != nil {
+		if n, ok := toFloat64(value); ok {
+			cell.Text = strconv.FormatFloat(n, 'f', *options.Number.Precision, 64)
+			return cell
+		}
+	}
+	cell.Text = displayText(value)
+	return cell
+}
+
+// userCell 协作者字段：解析用户名称（用户不存在时保留ID）
+func userCell(value interface{}, ids []string, names map[string]string) *viewprojection.Cell {
+	cell := &viewprojection.Cell{Value: value}
+	titles := make([]string, 0, len(ids))
+	for _, id := range ids {
+		title := names[id]
+		if title == "" {
+			title = id
+		}
+		cell.Items = append(cell.Items, viewprojection.CellItem{ID: id, Title: title})
+		titles = append(titles, title)
+	}
+	cell.Text = strings.Join(titles, ", ")
+	return cell
+}
+
+// linkCell 关联字段：同一 Base 内按关联表的标题字段解析（已删除的关联记录被跳过），
+// 跨 Base 关联使用单元格中已有的标题；返回引用的关联记录ID
+func linkCell(value interface{}, link *projectionLink, targets map[string]map[string]interface{}) (*viewprojection.Cell, []string) {
+	cell := &viewprojection.Cell{Value: value}
+	ids := extractLinkIDs(value)
+	titles := make([]string, 0, len(ids))
+	for _, id := range ids {
+		title := id
+		if link.sameBase {
+			data, ok := targets[id]
+			if !ok {
+				continue
+			}
+			if text := displayText(data[link.displayFieldID]); text != "" {
+				title = text
+			}
+		} else if text := embeddedLinkTitle(value, id); text != "" {
+			title = text
+		}
+		cell.Items = append(cell.Items, viewprojection.CellItem{ID: id, Title: title})
+		titles = append(titles, title)
+	}
+	cell.Text = strings.Join(titles, ", ")
+	if !link.sameBase {
+		return cell, nil
+	}
+	return cell, ids
+}
+
+// embeddedLinkTitle 单元格值中关联记录自带的标题（{"id": ..., "title": ...}）
+func embeddedLinkTitle(value interface{}, id string) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v["id"] == id {
+			title, _ := v["title"].(string)
+			return title
+		}
+	case []interface{}:
+		for _, item := range v {
+			if title := embeddedLinkTitle(item, id); title != "" {
+				return title
+			}
+		}
+	}
+	return ""
+}
+
+// displayText 单元格值的展示文本
+func displayText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := displayText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	case map[string]interface{}:
+		for _, key := range []string{"name", "title", "id"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	if n, ok := toFloat64(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func setKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,467 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	userEntity "github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	userVO "github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/viewprojection"
+)
+
+// memoryViewProjectionRepo 内存实现的视图投影仓储，saved 记录每次 SaveRows 写入的记录ID
+type memoryViewProjectionRepo struct {
+	projections map[string]*viewprojection.Projection
+	rows        map[string]map[string]*viewprojection.Row
+	saved       []string
+}
+
+func newMemoryViewProjectionRepo() *memoryViewProjectionRepo {
+	return &memoryViewProjectionRepo{
+		projections: map[string]*viewprojection.Projection{},
+		rows:        map[string]map[string]*viewprojection.Row{},
+	}
+}
+
+func (r *memoryViewProjectionRepo) Find(_ context.Context, viewID string) (*viewprojection.Projection, error) {
+	p, ok := r.projections[viewID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *p
+	copied.RecordIDs = append([]string{}, p.RecordIDs...)
+	return &copied, nil
+}
+
+func (r *memoryViewProjectionRepo) Save(_ context.Context, p *viewprojection.Projection) error {
+	copied := *p
+	copied.RecordIDs = append([]string{}, p.RecordIDs...)
+	r.projections[p.ViewID] = &copied
+	return nil
+}
+
+func (r *memoryViewProjectionRepo) Touch(_ context.Context, viewID string, readAt time.Time) error {
+	if p, ok := r.projections[viewID]; ok {
+		p.ReadAt = readAt
+	}
+	return nil
+}
+
+func (r *memoryViewProjectionRepo) Delete(_ context.Context, viewID string) error {
+	delete(r.projections, viewID)
+	delete(r.rows, viewID)
+	return nil
+}
+
+func (r *memoryViewProjectionRepo) ListReadSince(_ context.Context, since time.Time, _ int) ([]*viewprojection.Projection, error) {
+	var list []*viewprojection.Projection
+	for _, p := range r.projections {
+		if !p.ReadAt.Before(since) {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryViewProjectionRepo) DeleteReadBefore(_ context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	for viewID, p := range r.projections {
+		if p.ReadAt.Before(before) {
+			_ = r.Delete(context.Background(), viewID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryViewProjectionRepo) FindRows(_ context.Context, viewID string, recordIDs []string) ([]*viewprojection.Row, error) {
+	var rows []*viewprojection.Row
+	for _, id := range recordIDs {
+		if row, ok := r.rows[viewID][id]; ok {
+			copied := *row
+			copied.Cells = make(map[string]*viewprojection.Cell, len(row.Cells))
+			for key, cell := range row.Cells {
+				copied.Cells[key] = cell
+			}
+			rows = append(rows, &copied)
+		}
+	}
+	return rows, nil
+}
+
+func (r *memoryViewProjectionRepo) SaveRows(_ context.Context, rows []*viewprojection.Row) error {
+	for _, row := range rows {
+		if r.rows[row.ViewID] == nil {
+			r.rows[row.ViewID] = map[string]*viewprojection.Row{}
+		}
+		copied := *row
+		r.rows[row.ViewID][row.RecordID] = &copied
+		r.saved = append(r.saved, row.RecordID)
+	}
+	return nil
+}
+
+func (r *memoryViewProjectionRepo) DeleteRows(_ context.Context, viewID string, recordIDs []string) error {
+	for _, id := range recordIDs {
+		delete(r.rows[viewID], id)
+	}
+	return nil
+}
+
+func (r *memoryViewProjectionRepo) FindReferencing(_ context.Context, viewID string, refIDs []string) ([]string, error) {
+	var ids []string
+	for id, row := range r.rows[viewID] {
+		for _, ref := range row.Refs {
+			for _, want := range refIDs {
+				if ref == want {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// stubProjectionChangeFeed 返回 Base 的全部变更（不按表过滤），游标之前的变更视为已过期
+type stubProjectionChangeFeed struct {
+	changefeed.Repository
+	changes []*changefeed.Change
+	expired int64
+}
+
+func (r *stubProjectionChangeFeed) Head(context.Context) (int64, error) {
+	if len(r.changes) == 0 {
+		return 0, nil
+	}
+	return r.changes[len(r.changes)-1].Seq, nil
+}
+
+func (r *stubProjectionChangeFeed) ListAfter(_ context.Context, _ string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if filter.After < r.expired {
+		return nil, changefeed.ErrCursorExpired
+	}
+	var list []*changefeed.Change
+	for _, change := range r.changes {
+		if change.Seq > filter.After && len(list) < filter.Limit {
+			list = append(list, change)
+		}
+	}
+	return list, nil
+}
+
+// stubProjectionUserRepo 只实现按ID列出用户
+type stubProjectionUserRepo struct {
+	userRepo.UserRepository
+	users []*userEntity.User
+}
+
+func (r *stubProjectionUserRepo) List(_ context.Context, filter userRepo.UserFilter) ([]*userEntity.User, int64, error) {
+	var list []*userEntity.User
+	for _, u := range r.users {
+		for _, id := range filter.IDs {
+			if u.ID().String() == id {
+				list = append(list, u)
+			}
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+type viewProjectionFixture struct {
+	service *ViewProjectionService
+	repo    *memoryViewProjectionRepo
+	feed    *stubProjectionChangeFeed
+	records *stubRecordRepo
+	view    *viewEntity.View
+	fields  map[string]*fieldEntity.Field
+	hidden  map[string]bool // 不满足视图条件的记录
+	selects int             // 查询记录顺序的次数
+}
+
+func newViewProjectionFixture(t *testing.T) *viewProjectionFixture {
+	t.Helper()
+	link := fieldVO.NewFieldOptions().WithLink("tbl2", "many_to_many", false)
+	fields := map[string]*fieldEntity.Field{
+		"name":    newTestFieldWithOptions(t, "tbl1", "名称", fieldVO.TypeSingleLineText, nil),
+		"status":  newTestFieldWithOptions(t, "tbl1", "状态", fieldVO.TypeSingleSelect, fieldVO.NewFieldOptions().WithSelect([]fieldVO.SelectChoice{{ID: "cho1", Name: "进行中", Color: "blue"}})),
+		"owner":   newTestFieldWithOptions(t, "tbl1", "负责人", fieldVO.TypeUser, nil),
+		"project": newTestFieldWithOptions(t, "tbl1", "项目", fieldVO.TypeLink, link),
+		"secret":  newTestFieldWithOptions(t, "tbl1", "内部备注", fieldVO.TypeLongText, nil),
+		"title":   newTestFieldWithOptions(t, "tbl2", "项目名称", fieldVO.TypeSingleLineText, nil),
+	}
+	_ = fields["name"].SetPrimary(true)
+	_ = fields["title"].SetPrimary(true)
+
+	vt, _ := viewVO.NewViewType("grid")
+	view, err := viewEntity.NewView("tbl1", "全部", vt, "usr1")
+	if err != nil {
+		t.Fatalf("创建视图失败: %v", err)
+	}
+	if err := view.UpdateColumnMeta(&viewVO.ColumnMetaList{Columns: []viewVO.ColumnMeta{{FieldID: fields["secret"].ID().String(), Visible: false}}}); err != nil {
+		t.Fatalf("设置列失败: %v", err)
+	}
+	tableName, _ := tableVO.NewTableName("任务")
+	table, _ := tableEntity.NewTable("bse1", tableName, "usr1")
+
+	email, _ := userVO.NewEmail("lisi@example.com")
+	hash, _ := userVO.NewHashedPassword("hash")
+	lisi := userEntity.ReconstructUser(userVO.NewUserID("usr2"), "李四", email, hash, nil, nil, userVO.ActiveStatus(),
+		false, false, false, "", time.Now(), time.Now(), nil, nil, nil, 1)
+
+	fx := &viewProjectionFixture{
+		repo:    newMemoryViewProjectionRepo(),
+		feed:    &stubProjectionChangeFeed{},
+		records: &stubRecordRepo{tables: map[string][]*recordEntity.Record{}},
+		view:    view,
+		fields:  fields,
+		hidden:  map[string]bool{},
+	}
+	fieldRepository := &stubSyncedFieldRepo{tables: map[string][]*fieldEntity.Field{
+		"tbl1": {fields["name"], fields["status"], fields["owner"], fields["project"], fields["secret"]},
+		"tbl2": {fields["title"]},
+	}}
+	fx.service = NewViewProjectionService(fx.repo, &ChangeFeedService{repo: fx.feed},
+		&stubSyncedViewRepo{views: map[string]*viewEntity.View{view.ID(): view}}, fieldRepository,
+		&stubTableRepo{tables: map[string]*tableEntity.Table{"tbl1": table}}, fx.records,
+		&stubProjectionUserRepo{users: []*userEntity.User{lisi}}, nil, nil, nil, nil, config.ViewProjectionConfig{BatchSize: 2})
+	fx.service.selectIDs = func(_ context.Context, source *projectionSource) ([]string, error) {
+		fx.selects++
+		var ids []string
+		for _, record := range fx.records.tables[source.view.TableID()] {
+			if !fx.hidden[record.ID().String()] {
+				ids = append(ids, record.ID().String())
+			}
+		}
+		return ids, nil
+	}
+	return fx
+}
+
+func (fx *viewProjectionFixture) put(recordID, name string, projects ...string) {
+	links := make([]interface{}, 0, len(projects))
+	for _, id := range projects {
+		links = append(links, id)
+	}
+	fx.records.put("tbl1", recordID, map[string]interface{}{
+		fx.fields["name"].ID().String():    name,
+		fx.fields["status"].ID().String():  "进行中",
+		fx.fields["owner"].ID().String():   "usr2",
+		fx.fields["project"].ID().String(): links,
+		fx.fields["secret"].ID().String():  "不应展示",
+	})
+}
+
+func (fx *viewProjectionFixture) remove(recordID string) {
+	list := fx.records.tables["tbl1"]
+	for i, record := range list {
+		if record.ID().String() == recordID {
+			fx.records.tables["tbl1"] = append(list[:i], list[i+1:]...)
+			return
+		}
+	}
+}
+
+func (fx *viewProjectionFixture) change(tableID string, entityType changefeed.EntityType, action changefeed.Action, entityID string) {
+	fx.feed.changes = append(fx.feed.changes, &changefeed.Change{
+		Seq: int64(len(fx.feed.changes) + 1), BaseID: "bse1", TableID: tableID, EntityType: entityType, Action: action, EntityID: entityID,
+	})
+}
+
+func (fx *viewProjectionFixture) rows(t *testing.T) *ViewDisplayPage {
+	t.Helper()
+	page, err := fx.service.GetRows(context.Background(), "usr1", fx.view.ID(), 0, 0)
+	if err != nil {
+		t.Fatalf("读取展示行失败: %v", err)
+	}
+	return page
+}
+
+func (fx *viewProjectionFixture) text(page *ViewDisplayPage, index int, key string) string {
+	cell := page.Rows[index].Cells[fx.fields[key].ID().String()]
+	if cell == nil {
+		return ""
+	}
+	return cell.Text
+}
+
+func TestViewProjectionResolvesDisplayValues(t *testing.T) {
+	fx := newViewProjectionFixture(t)
+	fx.records.put("tbl2", "recP1", map[string]interface{}{fx.fields["title"].ID().String(): "官网改版"})
+	fx.put("rec1", "设计首页", "recP1", "recGone")
+
+	page := fx.rows(t)
+	if page.Total != 1 || len(page.Rows) != 1 || page.Limit != viewProjectionDefaultLimit {
+		t.Fatalf("应返回 1 行，得到 %+v", page)
+	}
+	for _, field := range page.Fields {
+		if field.ID == fx.fields["secret"].ID().String() {
+			t.Error("隐藏字段不应返回")
+		}
+	}
+	if _, ok := page.Rows[0].Cells[fx.fields["secret"].ID().String()]; ok {
+		t.Error("隐藏字段的单元格不应投影")
+	}
+
+	status := page.Rows[0].Cells[fx.fields["status"].ID().String()]
+	if status.Text != "进行中" || len(status.Items) != 1 || status.Items[0].ID != "cho1" || status.Items[0].Color != "blue" {
+		t.Errorf("选项应解析名称与颜色，得到 %+v", status)
+	}
+	if got := fx.text(page, 0, "owner"); got != "李四" {
+		t.Errorf("协作者应解析为姓名，得到 %q", got)
+	}
+	project := page.Rows[0].Cells[fx.fields["project"].ID().String()]
+	if project.Text != "官网改版" || len(project.Items) != 1 {
+		t.Errorf("关联记录应解析为标题（已删除的关联记录跳过），得到 %+v", project)
+	}
+
+	// 再次读取直接使用已保存的行
+	fx.repo.saved = nil
+	fx.rows(t)
+	if len(fx.repo.saved) != 0 {
+		t.Errorf("已解析的行不应重新解析，得到 %v", fx.repo.saved)
+	}
+}
+
+func TestViewProjectionAppliesRecordChanges(t *testing.T) {
+	fx := newViewProjectionFixture(t)
+	fx.put("rec1", "设计首页")
+	fx.put("rec2", "编写文案")
+	fx.put("rec3", "上线")
+	fx.rows(t)
+
+	fx.put("rec1", "设计首页（修订）")
+	fx.change("tbl1", changefeed.EntityRecord, changefeed.ActionUpdate, "rec1")
+	fx.remove("rec2")
+	fx.change("tbl1", changefeed.EntityRecord, changefeed.ActionDelete, "rec2")
+	fx.put("rec4", "复盘")
+	fx.change("tbl1", changefeed.EntityRecord, changefeed.ActionCreate, "rec4")
+	fx.repo.saved = nil
+	fx.selects = 0
+
+	page := fx.rows(t)
+	var ids []string
+	for _, row := range page.Rows {
+		ids = append(ids, row.ID)
+	}
+	if len(ids) != 3 || ids[0] != "rec1" || ids[1] != "rec3" || ids[2] != "rec4" {
+		t.Fatalf("删除的记录应移除、新记录追加在末尾，得到 %v", ids)
+	}
+	if got := fx.text(page, 0, "name"); got != "设计首页（修订）" {
+		t.Errorf("更新的记录应重新解析，得到 %q", got)
+	}
+	sort.Strings(fx.repo.saved)
+	if len(fx.repo.saved) != 2 || fx.repo.saved[0] != "rec1" || fx.repo.saved[1] != "rec4" {
+		t.Errorf("只应重新解析变化的记录，得到 %v", fx.repo.saved)
+	}
+	if fx.selects != 0 {
+		t.Errorf("无过滤排序的视图不应重新查询顺序，查询了 %d 次", fx.selects)
+	}
+	if _, ok := fx.repo.rows[fx.view.ID()]["rec2"]; ok {
+		t.Error("已删除记录的行应删除")
+	}
+	if p := fx.repo.projections[fx.view.ID()]; p.Cursor != 3 {
+		t.Errorf("游标应推进到 3，得到 %d", p.Cursor)
+	}
+}
+
+func TestViewProjectionReordersFilteredView(t *testing.T) {
+	fx := newViewProjectionFixture(t)
+	sortSpec, _ := viewVO.NewSort([]map[string]interface{}{{"fieldId": fx.fields["name"].ID().String(), "order": "asc"}})
+	if err := fx.view.UpdateSort(sortSpec); err != nil {
+		t.Fatalf("设置排序失败: %v", err)
+	}
+	fx.put("rec1", "设计首页")
+	fx.put("rec2", "编写文案")
+	fx.rows(t)
+
+	fx.hidden["rec1"] = true
+	fx.change("tbl1", changefeed.EntityRecord, changefeed.ActionUpdate, "rec1")
+	fx.selects = 0
+
+	page := fx.rows(t)
+	if len(page.Rows) != 1 || page.Rows[0].ID != "rec2" || fx.selects != 1 {
+		t.Fatalf("有排序的视图应在记录变化后重新查询顺序，得到 %+v（查询 %d 次）", page.Rows, fx.selects)
+	}
+	if _, ok := fx.repo.rows[fx.view.ID()]["rec1"]; ok {
+		t.Error("移出视图的记录的行应删除")
+	}
+}
+
+func TestViewProjectionRefreshesRowsReferencingChangedLinkedRecord(t *testing.T) {
+	fx := newViewProjectionFixture(t)
+	titleID := fx.fields["title"].ID().String()
+	fx.records.put("tbl2", "recP1", map[string]interface{}{titleID: "官网改版"})
+	fx.put("rec1", "设计首页", "recP1")
+	fx.put("rec2", "编写文案")
+	fx.rows(t)
+
+	fx.records.put("tbl2", "recP1", map[string]interface{}{titleID: "官网改版二期"})
+	fx.change("tbl2", changefeed.EntityRecord, changefeed.ActionUpdate, "recP1")
+	fx.repo.saved = nil
+
+	page := fx.rows(t)
+	if got := fx.text(page, 0, "project"); got != "官网改版二期" {
+		t.Errorf("关联记录标题变化后应刷新引用它的行，得到 %q", got)
+	}
+	if len(fx.repo.saved) != 1 || fx.repo.saved[0] != "rec1" {
+		t.Errorf("只应刷新引用该关联记录的行，得到 %v", fx.repo.saved)
+	}
+}
+
+func TestViewProjectionRebuilds(t *testing.T) {
+	cases := map[string]func(fx *viewProjectionFixture){
+		"字段变化": func(fx *viewProjectionFixture) {
+			fx.change("tbl1", changefeed.EntityField, changefeed.ActionUpdate, fx.fields["status"].ID().String())
+		},
+		"关联表字段变化": func(fx *viewProjectionFixture) {
+			fx.change("tbl2", changefeed.EntityField, changefeed.ActionCreate, "fldNew")
+		},
+		"视图变化": func(fx *viewProjectionFixture) {
+			fx.change("tbl1", changefeed.EntityView, changefeed.ActionUpdate, fx.view.ID())
+		},
+		"游标过期": func(fx *viewProjectionFixture) {
+			fx.change("tbl1", changefeed.EntityRecord, changefeed.ActionUpdate, "rec1")
+			fx.feed.expired = 5
+		},
+	}
+	for name, trigger := range cases {
+		fx := newViewProjectionFixture(t)
+		fx.put("rec1", "设计首页")
+		fx.rows(t)
+
+		trigger(fx)
+		fx.selects = 0
+		fx.rows(t)
+		if fx.selects != 1 {
+			t.Errorf("%s: 应全量重建，查询顺序 %d 次", name, fx.selects)
+		}
+		if p := fx.repo.projections[fx.view.ID()]; p.Cursor != 1 {
+			t.Errorf("%s: 重建后游标应为变更流头部，得到 %d", name, p.Cursor)
+		}
+	}
+
+	// 其他表与其他视图的变化不影响投影
+	fx := newViewProjectionFixture(t)
+	fx.put("rec1", "设计首页")
+	fx.rows(t)
+	fx.change("tbl9", changefeed.EntityField, changefeed.ActionUpdate, "fldOther")
+	fx.change("tbl1", changefeed.EntityView, changefeed.ActionUpdate, "viwOther")
+	fx.selects = 0
+	fx.rows(t)
+	if fx.selects != 0 {
+		t.Errorf("无关变化不应重建，查询顺序 %d 次", fx.selects)
+	}
+}
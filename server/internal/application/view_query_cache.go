@@ -0,0 +1,236 @@
+package application
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+)
+
+// ViewQuery 一次按视图过滤与排序的分页查询
+type ViewQuery struct {
+	TableID  string
+	ViewID   string // 为空时表示整表查询（无视图）
+	Filter   *viewVO.Filter
+	Sort     *viewVO.Sort
+	Group    *viewVO.Group // 分组（视图窗口按分组字段优先排序并缓存分组边界）
+	Page     int
+	PageSize int
+}
+
+// ViewQueryPage 分页查询结果（只缓存记录ID与总数，记录内容每次重新读取）
+type ViewQueryPage struct {
+	RecordIDs []string
+	Total     int64
+
+	Groups          []ViewWindowGroup // 分组边界（按分组查询时）
+	GroupsTruncated bool              // 末级分组超过上限，之后的分组边界未计算
+	Generation      uint64            // 装载代数：每次重新查询后递增，同一缓存页的读取代数相同（缓存未启用时为 0）
+}
+
+// viewQueryCacheEntry 缓存页及其装载时刻（与依赖的失效序号比较判断是否过期）
+type viewQueryCacheEntry struct {
+	page     *ViewQueryPage
+	deps     []string
+	loadedAt uint64
+}
+
+// viewQueryInvalidation 依赖的最近一次失效
+type viewQueryInvalidation struct {
+	seq uint64
+	at  time.Time
+}
+
+// ViewQueryCache 视图查询结果缓存 ✨
+// 按 (视图, 过滤排序哈希, 页码) 缓存过滤排序后的分页结果，并记录每页依赖的表、视图与过滤/排序字段；
+// 变更流按依赖失效：记录变更失效所在表的缓存页，字段变更只失效用到该字段的缓存页，
+// 重命名一个未参与过滤排序的字段不会清空整表的缓存。缓存位于进程内，跨实例的陈旧上限为 TTL
+type ViewQueryCache struct {
+	entries *cache.LRUCache
+	cfg     config.ViewQueryCacheConfig
+
+	mu          sync.Mutex
+	seq         uint64
+	generation  uint64
+	invalidated map[string]viewQueryInvalidation // 依赖 -> 最近一次失效
+}
+
+// NewViewQueryCache 创建视图查询结果缓存
+func NewViewQueryCache(cfg config.ViewQueryCacheConfig) *ViewQueryCache {
+	return &ViewQueryCache{
+		entries:     cache.NewLRUCache(cfg.MaxEntries, nil),
+		cfg:         cfg,
+		invalidated: make(map[string]viewQueryInvalidation),
+	}
+}
+
+// Load 读取缓存页，未命中或依赖已失效时调用 load 查询并缓存
+func (c *ViewQueryCache) Load(q ViewQuery, load func() (*ViewQueryPage, error)) (*ViewQueryPage, error) {
+	if c == nil || !c.cfg.Enabled {
+		return load()
+	}
+	key, err := q.cacheKey()
+	if err != nil {
+		return load()
+	}
+
+	if cached, ok := c.entries.Get(key); ok {
+		entry := cached.(*viewQueryCacheEntry)
+		if c.fresh(entry) {
+			metrics.ViewQueryCacheRequestsTotal.WithLabelValues("hit").Inc()
+			return entry.page, nil
+		}
+		c.entries.Delete(key)
+		metrics.ViewQueryCacheRequestsTotal.WithLabelValues("stale").Inc()
+	} else {
+		metrics.ViewQueryCacheRequestsTotal.WithLabelValues("miss").Inc()
+	}
+
+	// 装载时刻取在查询之前：查询期间发生的失效会让本次结果在下次读取时被判为过期
+	loadedAt := c.current()
+	page, err := load()
+	if err != nil {
+		return nil, err
+	}
+	page.Generation = c.nextGeneration()
+	c.entries.Set(key, &viewQueryCacheEntry{page: page, deps: q.dependencies(), loadedAt: loadedAt}, c.cfg.TTL)
+	return page, nil
+}
+
+// InvalidateTable 失效表上的全部缓存页（记录增删改）
+func (c *ViewQueryCache) InvalidateTable(ctx context.Context, tableID string) {
+	c.invalidate(ctx, "table", viewQueryTableDep(tableID))
+}
+
+// InvalidateField 失效以该字段过滤或排序的缓存页
+func (c *ViewQueryCache) InvalidateField(ctx context.Context, fieldID string) {
+	c.invalidate(ctx, "field", viewQueryFieldDep(fieldID))
+}
+
+// InvalidateView 失效视图的缓存页
+func (c *ViewQueryCache) InvalidateView(ctx context.Context, viewID string) {
+	c.invalidate(ctx, "view", viewQueryViewDep(viewID))
+}
+
+// invalidate 在事务提交后失效依赖（不在事务中时立即失效；回滚时无需失效）
+func (c *ViewQueryCache) invalidate(ctx context.Context, kind, dep string) {
+	if c == nil || !c.cfg.Enabled {
+		return
+	}
+	if database.InTransaction(ctx) {
+		database.AddTxCallback(ctx, func() { c.bump(kind, dep) })
+		return
+	}
+	c.bump(kind, dep)
+}
+
+func (c *ViewQueryCache) bump(kind, dep string) {
+	c.mu.Lock()
+	c.seq++
+	c.invalidated[dep] = viewQueryInvalidation{seq: c.seq, at: time.Now()}
+	c.mu.Unlock()
+	metrics.ViewQueryCacheInvalidationsTotal.WithLabelValues(kind).Inc()
+}
+
+func (c *ViewQueryCache) current() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seq
+}
+
+func (c *ViewQueryCache) nextGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	return c.generation
+}
+
+// fresh 缓存页装载后其依赖是否都未失效
+func (c *ViewQueryCache) fresh(entry *viewQueryCacheEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, dep := range entry.deps {
+		if inv, ok := c.invalidated[dep]; ok && inv.seq > entry.loadedAt {
+			return false
+		}
+	}
+	return true
+}
+
+// Start 后台清理过期缓存页与早于 TTL 的失效记录（此前装载的缓存页已过期，不再需要比较）
+func (c *ViewQueryCache) Start(ctx context.Context) {
+	if !c.cfg.Enabled {
+		return
+	}
+	c.entries.StartCleanupTimer(ctx, c.cfg.TTL)
+	go func() {
+		ticker := time.NewTicker(c.cfg.TTL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.pruneInvalidations(time.Now().Add(-c.cfg.TTL))
+			}
+		}
+	}()
+}
+
+func (c *ViewQueryCache) pruneInvalidations(before time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for dep, inv := range c.invalidated {
+		if inv.at.Before(before) {
+			delete(c.invalidated, dep)
+		}
+	}
+}
+
+// cacheKey 缓存键：视图（或整表）、过滤排序分组哈希、页码与页大小
+func (q ViewQuery) cacheKey() (string, error) {
+	raw, err := json.Marshal(struct {
+		Filter *viewVO.Filter `json:"filter"`
+		Sort   *viewVO.Sort   `json:"sort"`
+		Group  *viewVO.Group  `json:"group,omitempty"`
+	}{q.Filter, q.Sort, q.Group})
+	if err != nil {
+		return "", err
+	}
+	sum := sha1.Sum(raw)
+	scope := "view:" + q.ViewID
+	if q.ViewID == "" {
+		scope = "table:" + q.TableID
+	}
+	return fmt.Sprintf("%s:%s:%d:%d", scope, hex.EncodeToString(sum[:]), q.Page, q.PageSize), nil
+}
+
+// dependencies 缓存页依赖的表、视图与过滤/排序/分组字段
+func (q ViewQuery) dependencies() []string {
+	deps := []string{viewQueryTableDep(q.TableID)}
+	if q.ViewID != "" {
+		deps = append(deps, viewQueryViewDep(q.ViewID))
+	}
+	seen := make(map[string]bool)
+	fieldIDs := append(q.Filter.GetFieldIDs(), q.Sort.GetFieldIDs()...)
+	for _, fieldID := range append(fieldIDs, q.Group.GetFieldIDs()...) {
+		if !seen[fieldID] {
+			seen[fieldID] = true
+			deps = append(deps, viewQueryFieldDep(fieldID))
+		}
+	}
+	return deps
+}
+
+func viewQueryTableDep(tableID string) string { return "table:" + tableID }
+func viewQueryFieldDep(fieldID string) string { return "field:" + fieldID }
+func viewQueryViewDep(viewID string) string   { return "view:" + viewID }
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+func newViewQueryCacheForTest() *ViewQueryCache {
+	return NewViewQueryCache(config.ViewQueryCacheConfig{Enabled: true, TTL: time.Minute, MaxEntries: 100})
+}
+
+// countingViewQueryLoader 统计查询次数的装载函数
+type countingViewQueryLoader struct {
+	calls int
+}
+
+func (l *countingViewQueryLoader) load() (*ViewQueryPage, error) {
+	l.calls++
+	return &ViewQueryPage{RecordIDs: []string{"rec1"}, Total: int64(l.calls)}, nil
+}
+
+func viewQueryForTest(viewID, filterField, sortField string) ViewQuery {
+	return ViewQuery{
+		TableID: "tbl1",
+		ViewID:  viewID,
+		Filter: &viewVO.Filter{Operator: viewVO.FilterOperatorAnd, Filters: []viewVO.FilterItem{
+			{FieldID: filterField, Operator: viewVO.FilterItemOpIsNotEmpty},
+		}},
+		Sort:     &viewVO.Sort{SortItems: []viewVO.SortItem{{FieldID: sortField, Order: viewVO.SortOrderAsc}}},
+		Page:     1,
+		PageSize: 50,
+	}
+}
+
+func TestViewQueryCache_ReusesPageUntilDependencyChanges(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	ctx := context.Background()
+	loader := &countingViewQueryLoader{}
+	q := viewQueryForTest("viw1", "fldStatus", "fldDue")
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.Load(q, loader.load); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	if loader.calls != 1 {
+		t.Fatalf("expected one query, got %d", loader.calls)
+	}
+
+	// 另一页是另一个缓存项
+	next := q
+	next.Page = 2
+	_, _ = c.Load(next, loader.load)
+	if loader.calls != 2 {
+		t.Fatalf("expected page 2 to be queried, got %d calls", loader.calls)
+	}
+
+	c.InvalidateTable(ctx, "tbl1")
+	page, _ := c.Load(q, loader.load)
+	if loader.calls != 3 || page.Total != 3 {
+		t.Fatalf("expected reload after record change, calls=%d total=%d", loader.calls, page.Total)
+	}
+}
+
+func TestViewQueryCache_FieldChangeOnlyInvalidatesDependentPages(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	ctx := context.Background()
+	byStatus := &countingViewQueryLoader{}
+	byOwner := &countingViewQueryLoader{}
+	statusQuery := viewQueryForTest("viw1", "fldStatus", "fldDue")
+	ownerQuery := viewQueryForTest("viw2", "fldOwner", "fldOwner")
+
+	_, _ = c.Load(statusQuery, byStatus.load)
+	_, _ = c.Load(ownerQuery, byOwner.load)
+
+	// 重命名未参与过滤排序的字段：两个视图都不受影响
+	c.InvalidateField(ctx, "fldNotes")
+	_, _ = c.Load(statusQuery, byStatus.load)
+	_, _ = c.Load(ownerQuery, byOwner.load)
+	if byStatus.calls != 1 || byOwner.calls != 1 {
+		t.Fatalf("unrelated field change flushed pages: status=%d owner=%d", byStatus.calls, byOwner.calls)
+	}
+
+	// 排序字段变化只失效用到它的视图
+	c.InvalidateField(ctx, "fldDue")
+	_, _ = c.Load(statusQuery, byStatus.load)
+	_, _ = c.Load(ownerQuery, byOwner.load)
+	if byStatus.calls != 2 || byOwner.calls != 1 {
+		t.Fatalf("expected only the status view to reload: status=%d owner=%d", byStatus.calls, byOwner.calls)
+	}
+
+	c.InvalidateView(ctx, "viw2")
+	_, _ = c.Load(ownerQuery, byOwner.load)
+	if byOwner.calls != 2 {
+		t.Fatalf("expected view change to reload, got %d", byOwner.calls)
+	}
+}
+
+func TestViewQueryCache_FilterChangeUsesNewKey(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	loader := &countingViewQueryLoader{}
+
+	_, _ = c.Load(viewQueryForTest("viw1", "fldStatus", "fldDue"), loader.load)
+	_, _ = c.Load(viewQueryForTest("viw1", "fldOwner", "fldDue"), loader.load)
+	if loader.calls != 2 {
+		t.Fatalf("expected a different filter to miss, got %d calls", loader.calls)
+	}
+}
+
+func TestViewQueryCache_InvalidatesAfterCommit(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	loader := &countingViewQueryLoader{}
+	q := viewQueryForTest("viw1", "fldStatus", "fldDue")
+	_, _ = c.Load(q, loader.load)
+
+	txCtx := &database.TxContext{ID: "tx1", StartTime: time.Now()}
+	ctx := database.SetTxContext(context.Background(), txCtx)
+	c.InvalidateTable(ctx, "tbl1")
+
+	// 提交前仍命中缓存
+	_, _ = c.Load(q, loader.load)
+	if loader.calls != 1 {
+		t.Fatalf("expected invalidation to wait for commit, got %d calls", loader.calls)
+	}
+
+	txCtx.ExecuteCallbacks()
+	_, _ = c.Load(q, loader.load)
+	if loader.calls != 2 {
+		t.Fatalf("expected reload after commit, got %d calls", loader.calls)
+	}
+}
+
+func TestViewQueryCache_ChangeDuringLoadIsNotServed(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	ctx := context.Background()
+	q := viewQueryForTest("viw1", "fldStatus", "fldDue")
+	calls := 0
+
+	_, _ = c.Load(q, func() (*ViewQueryPage, error) {
+		calls++
+		// 查询期间记录被修改：本次结果可能已过时
+		c.InvalidateTable(ctx, "tbl1")
+		return &ViewQueryPage{}, nil
+	})
+	_, _ = c.Load(q, func() (*ViewQueryPage, error) {
+		calls++
+		return &ViewQueryPage{}, nil
+	})
+	if calls != 2 {
+		t.Fatalf("expected page loaded during a change to be reloaded, got %d calls", calls)
+	}
+}
+
+func TestViewQueryCache_GroupFieldChangeBumpsGeneration(t *testing.T) {
+	c := newViewQueryCacheForTest()
+	ctx := context.Background()
+	loader := &countingViewQueryLoader{}
+	q := viewQueryForTest("viw1", "fldStatus", "fldDue")
+	q.Group = &viewVO.Group{GroupItems: []viewVO.GroupItem{{FieldID: "fldStage", Order: viewVO.SortOrderAsc}}}
+
+	first, _ := c.Load(q, loader.load)
+	again, _ := c.Load(q, loader.load)
+	if loader.calls != 1 || again.Generation != first.Generation {
+		t.Fatalf("expected the cached layout to keep its generation, calls=%d", loader.calls)
+	}
+
+	// 分组字段变化失效布局，重新装载后代数变化
+	c.InvalidateField(ctx, "fldStage")
+	reloaded, _ := c.Load(q, loader.load)
+	if loader.calls != 2 || reloaded.Generation == first.Generation {
+		t.Fatalf("expected group field change to reload with a new generation, calls=%d", loader.calls)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
@@ -19,6 +20,7 @@ type ViewService struct {
 	viewRepo             repository.ViewRepository
 	tableRepo            tableRepo.TableRepository    // ✅ 添加表仓储，用于检查表存在性
 	businessEventManager *events.BusinessEventManager // ✅ 添加业务事件管理器，用于发布业务事件
+	changeFeed           *ChangeFeedService           // ✨ 变更流发件箱
 }
 
 // NewViewService 创建视图服务
@@ -30,6 +32,27 @@ func NewViewService(viewRepo repository.ViewRepository, tableRepo tableRepo.Tabl
 	}
 }
 
+// SetChangeFeed 设置变更流服务（视图变更写入发件箱）
+func (s *ViewService) SetChangeFeed(changeFeed *ChangeFeedService) {
+	s.changeFeed = changeFeed
+}
+
+// recordViewChange 将视图变更写入变更流（视图已保存，写入失败只记录日志）
+func (s *ViewService) recordViewChange(ctx context.Context, action changefeed.Action, view *entity.View) {
+	if s.changeFeed == nil {
+		return
+	}
+	var data interface{}
+	if action != changefeed.ActionDelete {
+		data = dto.FromViewEntity(view)
+	}
+	if err := s.changeFeed.ViewChanged(ctx, action, view.TableID(), view.ID(), data, ""); err != nil {
+		logger.Warn("写入视图变更流失败",
+			logger.String("view_id", view.ID()),
+			logger.ErrorField(err))
+	}
+}
+
 // CreateView 创建视图
 func (s *ViewService) CreateView(
 	ctx context.Context,
@@ -39,7 +62,7 @@ func (s *ViewService) CreateView(
 	// 0. ✅ 检查表是否存在
 	table, err := s.tableRepo.GetByID(ctx, req.TableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找表失败")
 	}
 	if table == nil {
 		return nil, pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{
@@ -125,8 +148,9 @@ func (s *ViewService) CreateView(
 
 	// 9. 保存视图
 	if err := s.viewRepo.Save(ctx, view); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionCreate, view)
 
 	logger.Info("视图创建成功",
 		logger.String("view_id", view.ID()),
@@ -141,7 +165,7 @@ func (s *ViewService) CreateView(
 func (s *ViewService) GetView(ctx context.Context, viewID string) (*dto.ViewResponse, error) {
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -154,7 +178,7 @@ func (s *ViewService) GetView(ctx context.Context, viewID string) (*dto.ViewResp
 func (s *ViewService) ListViewsByTable(ctx context.Context, tableID string) ([]*dto.ViewResponse, error) {
 	views, err := s.viewRepo.FindByTableID(ctx, tableID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图列表失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找视图列表失败")
 	}
 
 	responses := make([]*dto.ViewResponse, len(views))
@@ -174,7 +198,7 @@ func (s *ViewService) UpdateView(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -203,8 +227,9 @@ func (s *ViewService) UpdateView(
 
 	// 5. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图更新成功",
 		logger.String("view_id", viewID),
@@ -222,7 +247,7 @@ func (s *ViewService) UpdateViewFilter(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -244,8 +269,9 @@ func (s *ViewService) UpdateViewFilter(
 
 	// 4. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图过滤器更新成功",
 		logger.String("view_id", viewID),
@@ -263,7 +289,7 @@ func (s *ViewService) UpdateViewSort(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -285,8 +311,9 @@ func (s *ViewService) UpdateViewSort(
 
 	// 4. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图排序更新成功",
 		logger.String("view_id", viewID),
@@ -304,7 +331,7 @@ func (s *ViewService) UpdateViewGroup(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -326,8 +353,9 @@ func (s *ViewService) UpdateViewGroup(
 
 	// 4. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图分组更新成功",
 		logger.String("view_id", viewID),
@@ -345,7 +373,7 @@ func (s *ViewService) UpdateViewColumnMeta(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -364,8 +392,9 @@ func (s *ViewService) UpdateViewColumnMeta(
 
 	// 4. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	// 5. 发布业务事件，触发 YJS 同步
 	if s.businessEventManager != nil {
@@ -407,7 +436,7 @@ func (s *ViewService) UpdateViewOptions(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -420,8 +449,9 @@ func (s *ViewService) UpdateViewOptions(
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图选项更新成功",
 		logger.String("view_id", viewID),
@@ -439,7 +469,7 @@ func (s *ViewService) PatchViewOptions(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -452,8 +482,9 @@ func (s *ViewService) PatchViewOptions(
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图选项部分更新成功",
 		logger.String("view_id", viewID),
@@ -471,7 +502,7 @@ func (s *ViewService) UpdateViewOrder(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -484,8 +515,9 @@ func (s *ViewService) UpdateViewOrder(
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图排序更新成功",
 		logger.String("view_id", viewID),
@@ -500,7 +532,7 @@ func (s *ViewService) EnableShare(ctx context.Context, viewID string) (string, e
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return "", pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return "", pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return "", pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -514,8 +546,9 @@ func (s *ViewService) EnableShare(ctx context.Context, viewID string) (string, e
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return "", pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return "", pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图分享已启用",
 		logger.String("view_id", viewID),
@@ -530,7 +563,7 @@ func (s *ViewService) DisableShare(ctx context.Context, viewID string) error {
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -541,8 +574,9 @@ func (s *ViewService) DisableShare(ctx context.Context, viewID string) error {
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图分享已禁用",
 		logger.String("view_id", viewID),
@@ -556,7 +590,7 @@ func (s *ViewService) RefreshShareID(ctx context.Context, viewID string) (string
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return "", pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return "", pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return "", pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -570,8 +604,9 @@ func (s *ViewService) RefreshShareID(ctx context.Context, viewID string) (string
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return "", pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return "", pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图分享ID已刷新",
 		logger.String("view_id", viewID),
@@ -590,7 +625,7 @@ func (s *ViewService) UpdateShareMeta(
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -603,8 +638,9 @@ func (s *ViewService) UpdateShareMeta(
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图分享元数据更新成功",
 		logger.String("view_id", viewID),
@@ -618,7 +654,7 @@ func (s *ViewService) LockView(ctx context.Context, viewID string) error {
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -629,8 +665,9 @@ func (s *ViewService) LockView(ctx context.Context, viewID string) error {
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图已锁定",
 		logger.String("view_id", viewID),
@@ -644,7 +681,7 @@ func (s *ViewService) UnlockView(ctx context.Context, viewID string) error {
 	// 1. 查找视图
 	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -655,8 +692,9 @@ func (s *ViewService) UnlockView(ctx context.Context, viewID string) error {
 
 	// 3. 保存更新
 	if err := s.viewRepo.Update(ctx, view); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新视图失败: %v", err))
+		return pkgerrors.Database(err, "更新视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionUpdate, view)
 
 	logger.Info("视图已解锁",
 		logger.String("view_id", viewID),
@@ -668,18 +706,19 @@ func (s *ViewService) UnlockView(ctx context.Context, viewID string) error {
 // DeleteView 删除视图
 func (s *ViewService) DeleteView(ctx context.Context, viewID string) error {
 	// 1. 检查视图是否存在
-	exists, err := s.viewRepo.Exists(ctx, viewID)
+	view, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查视图失败: %v", err))
+		return pkgerrors.Database(err, "检查视图失败")
 	}
-	if !exists {
+	if view == nil {
 		return pkgerrors.ErrNotFound.WithDetails("视图不存在")
 	}
 
 	// 2. 删除视图
 	if err := s.viewRepo.Delete(ctx, viewID); err != nil {
-		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除视图失败: %v", err))
+		return pkgerrors.Database(err, "删除视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionDelete, view)
 
 	logger.Info("视图已删除",
 		logger.String("view_id", viewID),
@@ -698,7 +737,7 @@ func (s *ViewService) DuplicateView(
 	// 1. 查找原视图
 	originalView, err := s.viewRepo.FindByID(ctx, viewID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找视图失败")
 	}
 	if originalView == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
@@ -712,8 +751,9 @@ func (s *ViewService) DuplicateView(
 
 	// 3. 保存新视图
 	if err := s.viewRepo.Save(ctx, newView); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "保存视图失败")
 	}
+	s.recordViewChange(ctx, changefeed.ActionCreate, newView)
 
 	logger.Info("视图复制成功",
 		logger.String("original_view_id", viewID),
@@ -728,7 +768,7 @@ func (s *ViewService) DuplicateView(
 func (s *ViewService) GetViewByShareID(ctx context.Context, shareID string) (*dto.ViewResponse, error) {
 	view, err := s.viewRepo.FindByShareID(ctx, shareID)
 	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		return nil, pkgerrors.Database(err, "查找视图失败")
 	}
 	if view == nil {
 		return nil, pkgerrors.ErrNotFound.WithDetails("分享链接无效或已失效")
@@ -741,7 +781,7 @@ func (s *ViewService) GetViewByShareID(ctx context.Context, shareID string) (*dt
 func (s *ViewService) CountViews(ctx context.Context, tableID string) (int64, error) {
 	count, err := s.viewRepo.Count(ctx, tableID)
 	if err != nil {
-		return 0, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("统计视图失败: %v", err))
+		return 0, pkgerrors.Database(err, "统计视图失败")
 	}
 
 	return count, nil
@@ -0,0 +1,505 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+const (
+	// viewSummaryCacheTTL 汇总结果缓存时间（表格底栏频繁刷新，短时间内复用结果）
+	viewSummaryCacheTTL = 10 * time.Second
+	// viewSummaryCacheSize 最多缓存的汇总结果数
+	viewSummaryCacheSize = 1000
+	// maxSummaryGroups 分组汇总最多返回的分组数
+	maxSummaryGroups = 200
+)
+
+// ViewSummary 视图当前筛选条件下的记录数与字段汇总
+type ViewSummary struct {
+	ViewID       string         `json:"viewId"`
+	TableID      string         `json:"tableId"`
+	TotalRecords int64          `json:"totalRecords"`
+	Fields       []FieldSummary `json:"fields"`
+	GroupFieldID string         `json:"groupFieldId,omitempty"` // 视图按字段分组时的一级分组字段
+	Groups       []SummaryGroup `json:"groups,omitempty"`
+	GeneratedAt  time.Time      `json:"generatedAt"`
+}
+
+// SummaryGroup 一级分组的记录数与字段汇总
+type SummaryGroup struct {
+	Key          interface{}    `json:"key"`
+	TotalRecords int64          `json:"totalRecords"`
+	Fields       []FieldSummary `json:"fields"`
+}
+
+// FieldSummary 单个字段的汇总值（按字段类型只返回有意义的聚合）
+type FieldSummary struct {
+	FieldID       string      `json:"fieldId"`
+	FieldName     string      `json:"fieldName"`
+	FieldType     string      `json:"fieldType"`
+	Filled        int64       `json:"filled"`
+	Empty         int64       `json:"empty"`
+	FilledPercent float64     `json:"filledPercent"`
+	Unique        *int64      `json:"unique,omitempty"`
+	Sum           *float64    `json:"sum,omitempty"`
+	Average       *float64    `json:"average,omitempty"`
+	Min           interface{} `json:"min,omitempty"`
+	Max           interface{} `json:"max,omitempty"`
+	// Formatted 时长类字段按显示格式格式化后的 sum/average/min/max
+	Formatted map[string]string `json:"formatted,omitempty"`
+}
+
+// summaryColumn 汇总查询中某个字段的一个聚合列
+type summaryColumn struct {
+	field     int
+	aggregate string // filled / unique / sum / average / min / max
+}
+
+// ViewSummaryService 视图汇总栏服务：在 SQL 中按视图筛选条件计算字段聚合 ✨
+type ViewSummaryService struct {
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	cache             *cache.LRUCache
+}
+
+// NewViewSummaryService 创建视图汇总服务
+func NewViewSummaryService(
+	viewRepo viewRepo.ViewRepository,
+	fieldRepo repository.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+) *ViewSummaryService {
+	return &ViewSummaryService{
+		viewRepo:          viewRepo,
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		cache:             cache.NewLRUCache(viewSummaryCacheSize, nil),
+	}
+}
+
+// SetPrivacyService 设置脱敏服务：受脱敏保护的字段与加密字段一样只统计填充数，也不能作为分组
+func (s *ViewSummaryService) SetPrivacyService(privacyService *PrivacyService) {
+	s.privacyService = privacyService
+}
+
+// GetViewSummary 计算视图的记录数和字段汇总
+// fieldIDs 为空时汇总视图中可见的字段
+func (s *ViewSummaryService) GetViewSummary(ctx context.Context, userID, viewID string, fieldIDs []string) (*ViewSummary, error) {
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	tableID := view.TableID()
+	if !s.permissionService.CanAccessTable(ctx, userID, tableID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+	}
+	return s.summarize(ctx, view, fieldIDs)
+}
+
+// summarize 计算视图汇总（调用方负责权限校验，如仪表盘分享访问）
+func (s *ViewSummaryService) summarize(ctx context.Context, view *viewEntity.View, fieldIDs []string) (*ViewSummary, error) {
+	viewID := view.ID()
+	tableID := view.TableID()
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	if len(fieldIDs) == 0 && !view.ColumnMeta().IsEmpty() {
+		fieldIDs = view.ColumnMeta().GetVisibleFieldIDs()
+	}
+	selected := make([]*entity.Field, 0, len(fields))
+	if len(fieldIDs) == 0 {
+		selected = append(selected, fields...)
+	} else {
+		for _, fieldID := range fieldIDs {
+			if field, ok := byID[fieldID]; ok {
+				selected = append(selected, field)
+			}
+		}
+	}
+
+	// 脱敏字段随当前用户角色不同（分享访问没有用户，按最严格处理）
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, tableID)
+	}
+
+	// 视图版本随筛选条件变化递增，字段列表与受保护的字段决定查询内容
+	cacheKey := viewSummaryCacheKey(viewID, view.Version(), selected, protected)
+	if cached, ok := s.cache.Get(cacheKey); ok {
+		if summary, ok := cached.(*ViewSummary); ok {
+			return summary, nil
+		}
+	}
+
+	condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.aggregate(ctx, table.BaseID(), tableID, selected, protected, condition)
+	if err != nil {
+		return nil, err
+	}
+	summary.ViewID = viewID
+
+	// 视图分组时按一级分组字段汇总每组（加密字段无法按明文分组，脱敏字段的分组键会泄露明文）
+	if group := view.Group(); group != nil && len(group.GroupItems) > 0 {
+		item := group.GroupItems[0]
+		if groupField, ok := byID[item.FieldID]; ok && !groupField.IsEncrypted() && !protected[item.FieldID] {
+			groups, err := s.aggregateGroups(ctx, table.BaseID(), tableID, selected, protected, condition, groupField, item.Order)
+			if err != nil {
+				return nil, err
+			}
+			summary.GroupFieldID = item.FieldID
+			summary.Groups = groups
+		}
+	}
+	s.cache.Set(cacheKey, summary, viewSummaryCacheTTL)
+	return summary, nil
+}
+
+// aggregate 用一条 SQL 计算记录总数和全部字段的聚合
+func (s *ViewSummaryService) aggregate(ctx context.Context, baseID, tableID string, fields []*entity.Field, protected map[string]bool, condition clause.Expression) (*ViewSummary, error) {
+	selects, vars, columns := summarySelects(fields, protected)
+
+	query := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, tableID)).
+		Select(strings.Join(selects, ", "), vars...)
+	if condition != nil {
+		query = query.Where(condition)
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, pkgerrors.Database(err, "计算视图汇总失败")
+	}
+	defer rows.Close()
+
+	values := make([]interface{}, len(columns)+1)
+	dest := make([]interface{}, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, pkgerrors.Database(err, "读取视图汇总失败")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Database(err, "读取视图汇总失败")
+	}
+
+	total := toInt64(values[0])
+	return &ViewSummary{
+		TableID:      tableID,
+		TotalRecords: total,
+		Fields:       buildFieldSummaries(fields, columns, values[1:], total),
+		GeneratedAt:  time.Now(),
+	}, nil
+}
+
+// aggregateGroups 按分组字段 GROUP BY 计算每组的记录数与字段聚合（如每组总时长）
+// 分组按分组项的排序方向排列，最多返回 maxSummaryGroups 组
+func (s *ViewSummaryService) aggregateGroups(ctx context.Context, baseID, tableID string, fields []*entity.Field, protected map[string]bool, condition clause.Expression, groupField *entity.Field, order viewVO.SortOrder) ([]SummaryGroup, error) {
+	selects, vars, columns := summarySelects(fields, protected)
+	selects = append([]string{"? AS summary_group_key"}, selects...)
+	vars = append([]interface{}{clause.Column{Name: groupField.DBFieldName().String()}}, vars...)
+
+	direction := "ASC"
+	if order == viewVO.SortOrderDesc {
+		direction = "DESC"
+	}
+	query := s.dataDB(ctx, baseID).WithContext(ctx).
+		Table(s.dbProvider.GenerateTableName(baseID, tableID)).
+		Select(strings.Join(selects, ", "), vars...)
+	if condition != nil {
+		query = query.Where(condition)
+	}
+	rows, err := query.Group("summary_group_key").
+		Order("summary_group_key " + direction).
+		Limit(maxSummaryGroups).
+		Rows()
+	if err != nil {
+		return nil, pkgerrors.Database(err, "计算分组汇总失败")
+	}
+	defer rows.Close()
+
+	var groups []SummaryGroup
+	for rows.Next() {
+		values := make([]interface{}, len(columns)+2)
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, pkgerrors.Database(err, "读取分组汇总失败")
+		}
+		total := toInt64(values[1])
+		groups = append(groups, SummaryGroup{
+			Key:          normalizeSummaryValue(values[0]),
+			TotalRecords: total,
+			Fields:       buildFieldSummaries(fields, columns, values[2:], total),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Database(err, "读取分组汇总失败")
+	}
+	return groups, nil
+}
+
+// summarySelects 构建 COUNT(*) 与各字段聚合的查询列
+func summarySelects(fields []*entity.Field, protected map[string]bool) ([]string, []interface{}, []summaryColumn) {
+	selects := []string{"COUNT(*)"}
+	var vars []interface{}
+	var columns []summaryColumn
+	for i, field := range fields {
+		for _, agg := range summaryAggregates(field, protected[field.ID().String()]) {
+			sql, aggVars := aggregateSQL(agg, clause.Column{Name: field.DBFieldName().String()}, columnKindOf(field))
+			selects = append(selects, sql)
+			vars = append(vars, aggVars...)
+			columns = append(columns, summaryColumn{field: i, aggregate: agg})
+		}
+	}
+	return selects, vars, columns
+}
+
+// buildFieldSummaries 将聚合列的结果填入各字段汇总，values 与 columns 一一对应
+func buildFieldSummaries(fields []*entity.Field, columns []summaryColumn, values []interface{}, total int64) []FieldSummary {
+	summaries := make([]FieldSummary, len(fields))
+	for i, field := range fields {
+		summaries[i] = FieldSummary{
+			FieldID:   field.ID().String(),
+			FieldName: field.Name().String(),
+			FieldType: field.Type().String(),
+		}
+	}
+	for i, col := range columns {
+		summaries[col.field].apply(col.aggregate, values[i], total)
+	}
+	for i, field := range fields {
+		if format, ok := durationFormatOf(field); ok {
+			summaries[i].formatDuration(format)
+		}
+	}
+	return summaries
+}
+
+// durationFormatOf 字段值是否为时长（秒数）及其显示格式
+// 时长字段使用自身格式，汇总、公式、查找字段通过 formatting.type = duration 声明
+func durationFormatOf(field *entity.Field) (string, bool) {
+	options := field.Options()
+	if field.Type().String() == fieldVO.TypeDuration {
+		if options != nil && options.Duration != nil {
+			return options.Duration.Format, true
+		}
+		return fieldVO.DefaultDurationFormat, true
+	}
+	if options == nil {
+		return "", false
+	}
+	candidates := []*fieldVO.FormattingOptions{options.Formatting}
+	if options.Rollup != nil {
+		candidates = append(candidates, options.Rollup.Formatting)
+	}
+	if options.Formula != nil {
+		candidates = append(candidates, options.Formula.Formatting)
+	}
+	if options.Lookup != nil {
+		candidates = append(candidates, options.Lookup.Formatting)
+	}
+	for _, formatting := range candidates {
+		if formatting != nil && formatting.Type == fieldVO.TypeDuration {
+			return formatting.DurationFormat, true
+		}
+	}
+	return "", false
+}
+
+// summaryAggregates 字段支持的聚合（加密或脱敏字段只统计填充数，去重数与最值都会泄露明文信息）
+func summaryAggregates(field *entity.Field, protected bool) []string {
+	if field.IsEncrypted() || protected {
+		return []string{"filled"}
+	}
+	switch columnKindOf(field) {
+	case columnKindNumber:
+		return []string{"filled", "unique", "sum", "average", "min", "max"}
+	case columnKindDate:
+		return []string{"filled", "unique", "min", "max"}
+	case columnKindBoolean:
+		return []string{"filled"}
+	default:
+		return []string{"filled", "unique"}
+	}
+}
+
+// aggregateSQL 单个聚合的 SQL 表达式
+func aggregateSQL(aggregate string, col clause.Column, kind columnKind) (string, []interface{}) {
+	switch aggregate {
+	case "filled":
+		empty, vars, _ := emptySQL(col, kind)
+		return "COUNT(CASE WHEN NOT (" + empty + ") THEN 1 END)", vars
+	case "unique":
+		if kind == columnKindText {
+			return "COUNT(DISTINCT NULLIF(CAST(? AS TEXT), ''))", []interface{}{col}
+		}
+		return "COUNT(DISTINCT ?)", []interface{}{col}
+	case "sum":
+		return "SUM(?)", []interface{}{col}
+	case "average":
+		return "AVG(?)", []interface{}{col}
+	case "min":
+		return "MIN(?)", []interface{}{col}
+	default:
+		return "MAX(?)", []interface{}{col}
+	}
+}
+
+// apply 填入聚合结果
+func (f *FieldSummary) apply(aggregate string, value interface{}, total int64) {
+	switch aggregate {
+	case "filled":
+		f.Filled = toInt64(value)
+		f.Empty = total - f.Filled
+		if total > 0 {
+			f.FilledPercent = float64(f.Filled) * 100 / float64(total)
+		}
+	case "unique":
+		unique := toInt64(value)
+		f.Unique = &unique
+	case "sum":
+		// 无记录时 SUM 为 NULL，汇总栏显示 0
+		sum := 0.0
+		if v, ok := toFloat64(value); ok {
+			sum = v
+		}
+		f.Sum = &sum
+	case "average":
+		if v, ok := toFloat64(value); ok {
+			f.Average = &v
+		}
+	case "min":
+		f.Min = normalizeSummaryValue(value)
+	case "max":
+		f.Max = normalizeSummaryValue(value)
+	}
+}
+
+// formatDuration 按时长显示格式格式化数值聚合
+func (f *FieldSummary) formatDuration(format string) {
+	formatted := make(map[string]string)
+	if f.Sum != nil {
+		formatted["sum"] = fieldVO.FormatDuration(*f.Sum, format)
+	}
+	if f.Average != nil {
+		formatted["average"] = fieldVO.FormatDuration(*f.Average, format)
+	}
+	if v, ok := toFloat64(f.Min); ok {
+		formatted["min"] = fieldVO.FormatDuration(v, format)
+	}
+	if v, ok := toFloat64(f.Max); ok {
+		formatted["max"] = fieldVO.FormatDuration(v, format)
+	}
+	if len(formatted) > 0 {
+		f.Formatted = formatted
+	}
+}
+
+// normalizeSummaryValue 驱动返回的 NUMERIC 为 []byte，转换为数值
+func normalizeSummaryValue(value interface{}) interface{} {
+	if b, ok := value.([]byte); ok {
+		if v, err := strconv.ParseFloat(string(b), 64); err == nil {
+			return v
+		}
+		return string(b)
+	}
+	return value
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case []byte:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+	return 0
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case []byte:
+		f, err := strconv.ParseFloat(string(v), 64)
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// viewSummaryCacheKey 汇总缓存键
+func viewSummaryCacheKey(viewID string, version int, fields []*entity.Field, protected map[string]bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d", viewID, version)
+	for _, field := range fields {
+		fmt.Fprintf(&b, ":%s@%d", field.ID().String(), field.Version())
+		if protected[field.ID().String()] {
+			b.WriteString("!")
+		}
+	}
+	return b.String()
+}
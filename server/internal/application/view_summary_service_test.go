@@ -0,0 +1,121 @@
+package application
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func TestFilterItemSQL(t *testing.T) {
+	col := clause.Column{Name: "fld_amount"}
+
+	sql, vars, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpContains, Value: "50%_off"}, col, columnKindText)
+	if err != nil || sql != "CAST(? AS TEXT) ILIKE ?" || vars[1] != `%50\%\_off%` {
+		t.Fatalf("contains 应转义通配符，得到 %q %v %v", sql, vars, err)
+	}
+
+	sql, vars, err = filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpHasAnyOf, Value: []interface{}{"a", "b"}}, col, columnKindJSON)
+	if err != nil || sql != "? @> ?::jsonb OR ? @> ?::jsonb" || vars[1] != `["a"]` || vars[3] != `["b"]` {
+		t.Fatalf("JSON 列 hasAnyOf 应逐个匹配，得到 %q %v %v", sql, vars, err)
+	}
+
+	if _, _, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpIsWithin, Value: "pastWeek"}, col, columnKindDate); err == nil {
+		t.Fatalf("isWithin 缺少范围时应返回错误")
+	}
+}
+
+func TestLocationFilterSQL(t *testing.T) {
+	col := clause.Column{Name: "fld_place"}
+
+	sql, vars, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpIsWithinRadius,
+		Value: map[string]interface{}{"lat": 31.2, "lng": 121.5, "radius": 500.0}}, col, columnKindLocation)
+	if err != nil || !strings.HasPrefix(sql, "earth_box(ll_to_earth(?, ?), ?) @> ") || len(vars) != 10 || vars[2] != 500.0 {
+		t.Fatalf("半径过滤应使用 earth_box 粗筛，得到 %q %v %v", sql, vars, err)
+	}
+
+	sql, _, err = filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpIsInBoundingBox,
+		Value: map[string]interface{}{"south": -10.0, "west": 170.0, "north": 10.0, "east": -170.0}}, col, columnKindLocation)
+	if err != nil || !strings.Contains(sql, " OR ") {
+		t.Fatalf("跨越 180° 经线的范围应使用 OR 连接经度条件，得到 %q %v", sql, err)
+	}
+
+	if _, _, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpIsWithinRadius,
+		Value: map[string]interface{}{"lat": 31.2, "lng": 121.5}}, col, columnKindLocation); err == nil {
+		t.Fatalf("缺少半径时应返回错误")
+	}
+	if _, _, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpGreater, Value: 1}, col, columnKindLocation); err == nil {
+		t.Fatalf("位置字段不支持数值比较")
+	}
+	if _, _, err := filterItemSQL(viewVO.FilterItem{FieldID: "fld1", Operator: viewVO.FilterItemOpIsWithinRadius,
+		Value: map[string]interface{}{"lat": 1.0, "lng": 1.0, "radius": 1.0}}, col, columnKindText); err == nil {
+		t.Fatalf("非位置字段不支持半径过滤")
+	}
+}
+
+func TestFieldSummaryApply(t *testing.T) {
+	var f FieldSummary
+	f.apply("filled", int64(3), 4)
+	f.apply("sum", []byte("12.5"), 4)
+	f.apply("average", nil, 4)
+	f.apply("max", []byte("10"), 4)
+
+	if f.Filled != 3 || f.Empty != 1 || f.FilledPercent != 75 {
+		t.Errorf("填充统计不正确: %+v", f)
+	}
+	if f.Sum == nil || *f.Sum != 12.5 || f.Average != nil || f.Max != 10.0 {
+		t.Errorf("数值聚合不正确: sum=%v avg=%v max=%v", f.Sum, f.Average, f.Max)
+	}
+}
+
+func TestDurationSummaryFormatting(t *testing.T) {
+	name, _ := fieldVO.NewFieldName("工时")
+	ft, _ := fieldVO.NewFieldType(fieldVO.TypeDuration)
+	field, err := entity.NewField("tbl1", name, ft, "usr1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field.UpdateOptions(fieldVO.NewFieldOptions().WithDuration(fieldVO.DurationFormatHMMSS))
+
+	columns := []summaryColumn{{field: 0, aggregate: "filled"}, {field: 0, aggregate: "sum"}, {field: 0, aggregate: "max"}}
+	summaries := buildFieldSummaries([]*entity.Field{field}, columns, []interface{}{int64(2), []byte("5415"), []byte("3600")}, 3)
+	got := summaries[0].Formatted
+	if got["sum"] != "1:30:15" || got["max"] != "1:00:00" {
+		t.Errorf("时长汇总格式化不正确: %v", got)
+	}
+	if _, ok := got["average"]; ok {
+		t.Errorf("未计算的聚合不应格式化: %v", got)
+	}
+
+	// 汇总字段通过 formatting.type = duration 声明为时长
+	rollupType, _ := fieldVO.NewFieldType("rollup")
+	rollup, _ := entity.NewField("tbl1", name, rollupType, "usr1")
+	options := fieldVO.NewFieldOptions()
+	options.Rollup = &fieldVO.RollupOptions{Formatting: &fieldVO.FormattingOptions{Type: "duration", DurationFormat: fieldVO.DurationFormatHuman}}
+	rollup.UpdateOptions(options)
+	if format, ok := durationFormatOf(rollup); !ok || format != fieldVO.DurationFormatHuman {
+		t.Errorf("durationFormatOf(rollup) = %q, %v", format, ok)
+	}
+}
+
+func TestRatingAndProgressSummaryAggregates(t *testing.T) {
+	for _, fieldType := range []string{fieldVO.TypeRating, fieldVO.TypeProgress} {
+		name, _ := fieldVO.NewFieldName("评分")
+		ft, err := fieldVO.NewFieldType(fieldType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		field, _ := entity.NewField("tbl1", name, ft, "usr1")
+		got := summaryAggregates(field, false)
+		if strings.Join(got, ",") != "filled,unique,sum,average,min,max" {
+			t.Errorf("%s 字段应支持数值聚合，得到 %v", fieldType, got)
+		}
+		if got := summaryAggregates(field, true); strings.Join(got, ",") != "filled" {
+			t.Errorf("受脱敏保护的 %s 字段只能统计填充数，得到 %v", fieldType, got)
+		}
+	}
+}
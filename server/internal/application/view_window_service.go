@@ -0,0 +1,412 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordVO "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// viewWindowDefaultCount 未指定行数时每次读取的行数
+const viewWindowDefaultCount = 100
+
+// ViewWindow 视图的一个行窗口及其分组边界与总数
+// 含分组标题行的滚动位置：第 Offset 行之前共有 Offset + HeadersBefore 行，
+// 全部行数为 Total + TotalGroups（分组未截断时）
+type ViewWindow struct {
+	ViewID          string                `json:"viewId"`
+	TableID         string                `json:"tableId"`
+	Generation      uint64                `json:"generation"` // 布局代数，变化说明视图数据已变化，之前读取的窗口需要重新读取
+	Offset          int                   `json:"offset"`
+	Count           int                   `json:"count"`
+	Total           int64                 `json:"total"`
+	GroupFieldIDs   []string              `json:"groupFieldIds,omitempty"`
+	TotalGroups     int                   `json:"totalGroups"`               // 各级分组标题的总数
+	GroupsTruncated bool                  `json:"groupsTruncated,omitempty"` // 分组过多，之后的分组边界未返回
+	HeadersBefore   int                   `json:"headersBefore"`             // 首行之前开始的分组标题数
+	Groups          []ViewWindowGroup     `json:"groups"`                    // 与窗口相交的分组（父级在子级之前）
+	Records         []*dto.RecordResponse `json:"records"`
+}
+
+// ViewWindowGroup 一个分组的边界
+type ViewWindowGroup struct {
+	Level   int         `json:"level"` // 分组层级（0 为一级分组）
+	FieldID string      `json:"fieldId"`
+	Value   interface{} `json:"value"`
+	Start   int64       `json:"start"` // 分组首行的行号（从 0 开始，不含标题行）
+	Count   int64       `json:"count"`
+}
+
+// viewWindowGroupColumn 参与分组的字段列
+type viewWindowGroupColumn struct {
+	fieldID string
+	column  clause.Column
+	desc    bool
+}
+
+// viewWindowLeaf 末级分组：各级分组值与记录数
+type viewWindowLeaf struct {
+	values []interface{}
+	count  int64
+}
+
+// ViewWindowService 虚拟滚动表格的视图窗口服务 ✨
+// 按视图的过滤、分组与排序读取任意 [offset, offset+count) 行窗口，同时返回与窗口相交的分组边界和总数，
+// 表格一次请求即可渲染可见区域。总数、分组布局与前 MaxRows 条有序记录ID作为一个布局
+// 经视图查询缓存按依赖失效，滚动时的窗口从同一布局切片，布局代数相同的窗口彼此一致
+type ViewWindowService struct {
+	viewRepo          viewRepo.ViewRepository
+	fieldRepo         repository.FieldRepository
+	tableRepo         tableRepo.TableRepository
+	recordRepo        recordRepo.RecordRepository
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	viewQueries       *ViewQueryCache
+	cfg               config.ViewWindowConfig
+}
+
+// NewViewWindowService 创建视图窗口服务
+func NewViewWindowService(
+	viewRepository viewRepo.ViewRepository,
+	fieldRepo repository.FieldRepository,
+	tableRepository tableRepo.TableRepository,
+	recordRepository recordRepo.RecordRepository,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	viewQueries *ViewQueryCache,
+	cfg config.ViewWindowConfig,
+) *ViewWindowService {
+	if cfg.MaxCount <= 0 {
+		cfg.MaxCount = 500
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = 10000
+	}
+	if cfg.MaxGroups <= 0 {
+		cfg.MaxGroups = 1000
+	}
+	return &ViewWindowService{
+		viewRepo:          viewRepository,
+		fieldRepo:         fieldRepo,
+		tableRepo:         tableRepository,
+		recordRepo:        recordRepository,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		viewQueries:       viewQueries,
+		cfg:               cfg,
+	}
+}
+
+// GetWindow 读取视图从 offset 开始的 count 行（只包含视图中可见的字段）
+func (s *ViewWindowService) GetWindow(ctx context.Context, userID, viewID string, offset, count int) (*ViewWindow, error) {
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找视图失败")
+	}
+	if view == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+	}
+	if !s.permissionService.CanReadView(ctx, userID, viewID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限访问该视图")
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if count <= 0 {
+		count = viewWindowDefaultCount
+	}
+	if count > s.cfg.MaxCount {
+		count = s.cfg.MaxCount
+	}
+
+	tableID := view.TableID()
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取Table信息失败")
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询字段失败")
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+	condition, err := buildViewFilterCondition(ctx, view.Filter(), byID)
+	if err != nil {
+		return nil, err
+	}
+	groupColumns := viewWindowGroupColumns(view.Group(), byID)
+	orders := viewWindowOrders(view.Sort(), groupColumns, byID)
+	ordered := func() *gorm.DB {
+		query := s.dataDB(ctx, table.BaseID()).WithContext(ctx).Table(s.dbProvider.GenerateTableName(table.BaseID(), tableID))
+		if condition != nil {
+			query = query.Where(condition)
+		}
+		return query
+	}
+
+	layout, err := s.viewQueries.Load(ViewQuery{
+		TableID:  tableID,
+		ViewID:   viewID,
+		Filter:   view.Filter(),
+		Sort:     view.Sort(),
+		Group:    view.Group(),
+		PageSize: s.cfg.MaxRows,
+	}, func() (*ViewQueryPage, error) {
+		return s.loadLayout(ordered, groupColumns, orders)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 窗口在缓存的有序记录ID之内时直接切片，否则按偏移查询
+	var ids []string
+	end := offset + count
+	switch {
+	case end <= len(layout.RecordIDs) || int64(len(layout.RecordIDs)) >= layout.Total:
+		if offset < len(layout.RecordIDs) {
+			ids = layout.RecordIDs[offset:min(end, len(layout.RecordIDs))]
+		}
+	case int64(offset) < layout.Total:
+		query := ordered()
+		for _, order := range orders {
+			query = query.Order(order)
+		}
+		if err := query.Limit(count).Offset(offset).Pluck("__id", &ids).Error; err != nil {
+			return nil, pkgerrors.Database(err, "查询记录失败")
+		}
+	}
+
+	records, err := s.loadRecords(ctx, view, fields, ids)
+	if err != nil {
+		return nil, err
+	}
+	groups, before := viewWindowIntersect(layout.Groups, int64(offset), int64(end))
+	window := &ViewWindow{
+		ViewID:          viewID,
+		TableID:         tableID,
+		Generation:      layout.Generation,
+		Offset:          offset,
+		Count:           count,
+		Total:           layout.Total,
+		TotalGroups:     len(layout.Groups),
+		GroupsTruncated: layout.GroupsTruncated,
+		HeadersBefore:   before,
+		Groups:          groups,
+		Records:         records,
+	}
+	for _, column := range groupColumns {
+		window.GroupFieldIDs = append(window.GroupFieldIDs, column.fieldID)
+	}
+	return window, nil
+}
+
+// loadLayout 查询视图的记录总数、分组布局与前 MaxRows 条有序记录ID
+func (s *ViewWindowService) loadLayout(ordered func() *gorm.DB, groupColumns []viewWindowGroupColumn, orders []clause.OrderByColumn) (*ViewQueryPage, error) {
+	layout := &ViewQueryPage{}
+	if err := ordered().Count(&layout.Total).Error; err != nil {
+		return nil, pkgerrors.Database(err, "统计记录失败")
+	}
+
+	if len(groupColumns) > 0 {
+		leaves, err := s.loadLeaves(ordered(), groupColumns)
+		if err != nil {
+			return nil, err
+		}
+		if len(leaves) > s.cfg.MaxGroups {
+			leaves = leaves[:s.cfg.MaxGroups]
+			layout.GroupsTruncated = true
+		}
+		fieldIDs := make([]string, len(groupColumns))
+		for i, column := range groupColumns {
+			fieldIDs[i] = column.fieldID
+		}
+		layout.Groups = buildViewWindowGroups(fieldIDs, leaves)
+	}
+
+	query := ordered()
+	for _, order := range orders {
+		query = query.Order(order)
+	}
+	if err := query.Limit(s.cfg.MaxRows).Pluck("__id", &layout.RecordIDs).Error; err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	return layout, nil
+}
+
+// loadLeaves 按全部分组字段 GROUP BY 得到按分组顺序排列的末级分组（最多 MaxGroups+1 个，用于判断截断）
+func (s *ViewWindowService) loadLeaves(query *gorm.DB, groupColumns []viewWindowGroupColumn) ([]viewWindowLeaf, error) {
+	selects := make([]string, 0, len(groupColumns)+1)
+	vars := make([]interface{}, 0, len(groupColumns))
+	for i, column := range groupColumns {
+		selects = append(selects, fmt.Sprintf("? AS window_group_%d", i))
+		vars = append(vars, column.column)
+	}
+	selects = append(selects, "COUNT(*)")
+	query = query.Select(strings.Join(selects, ", "), vars...)
+	for i, column := range groupColumns {
+		direction := "ASC"
+		if column.desc {
+			direction = "DESC"
+		}
+		query = query.Group(fmt.Sprintf("window_group_%d", i)).Order(fmt.Sprintf("window_group_%d %s", i, direction))
+	}
+	rows, err := query.Limit(s.cfg.MaxGroups + 1).Rows()
+	if err != nil {
+		return nil, pkgerrors.Database(err, "计算分组失败")
+	}
+	defer rows.Close()
+
+	var leaves []viewWindowLeaf
+	for rows.Next() {
+		values := make([]interface{}, len(groupColumns)+1)
+		dest := make([]interface{}, len(values))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, pkgerrors.Database(err, "读取分组失败")
+		}
+		leaf := viewWindowLeaf{values: make([]interface{}, len(groupColumns)), count: toInt64(values[len(groupColumns)])}
+		for i := range groupColumns {
+			leaf.values[i] = normalizeSummaryValue(values[i])
+		}
+		leaves = append(leaves, leaf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pkgerrors.Database(err, "读取分组失败")
+	}
+	return leaves, nil
+}
+
+// loadRecords 按窗口顺序读取记录，移除视图中隐藏的字段并按当前用户脱敏
+func (s *ViewWindowService) loadRecords(ctx context.Context, view *viewEntity.View, fields []*entity.Field, ids []string) ([]*dto.RecordResponse, error) {
+	if len(ids) == 0 {
+		return []*dto.RecordResponse{}, nil
+	}
+	recordIDs := make([]recordVO.RecordID, len(ids))
+	for i, id := range ids {
+		recordIDs[i] = recordVO.NewRecordID(id)
+	}
+	entities, err := s.recordRepo.FindByIDs(ctx, view.TableID(), recordIDs)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查询记录失败")
+	}
+	byID := make(map[string]*dto.RecordResponse, len(entities))
+	for _, record := range entities {
+		byID[record.ID().String()] = dto.FromRecordEntity(record)
+	}
+
+	hidden := embedHiddenFieldIDs(view, fields)
+	records := make([]*dto.RecordResponse, 0, len(ids))
+	for _, id := range ids {
+		record, ok := byID[id]
+		if !ok {
+			continue
+		}
+		for fieldID := range hidden {
+			delete(record.Data, fieldID)
+		}
+		records = append(records, record)
+	}
+	if s.privacyService != nil {
+		s.privacyService.MaskRecords(ctx, view.TableID(), records...)
+	}
+	return records, nil
+}
+
+// viewWindowGroupColumns 视图分组对应的字段列（引用不存在字段或加密字段的分组项被忽略）
+func viewWindowGroupColumns(group *viewVO.Group, fields map[string]*entity.Field) []viewWindowGroupColumn {
+	if group == nil {
+		return nil
+	}
+	var columns []viewWindowGroupColumn
+	for _, item := range group.GroupItems {
+		field, ok := fields[item.FieldID]
+		if !ok || field.IsEncrypted() {
+			continue
+		}
+		columns = append(columns, viewWindowGroupColumn{
+			fieldID: item.FieldID,
+			column:  clause.Column{Name: field.DBFieldName().String()},
+			desc:    item.Order == viewVO.SortOrderDesc,
+		})
+	}
+	return columns
+}
+
+// viewWindowOrders 记录顺序：先按分组字段，组内按视图排序
+func viewWindowOrders(sort *viewVO.Sort, groupColumns []viewWindowGroupColumn, fields map[string]*entity.Field) []clause.OrderByColumn {
+	orders := make([]clause.OrderByColumn, 0, len(groupColumns))
+	for _, column := range groupColumns {
+		orders = append(orders, clause.OrderByColumn{Column: column.column, Desc: column.desc})
+	}
+	return append(orders, embedSortColumns(sort, fields)...)
+}
+
+// buildViewWindowGroups 由按顺序排列的末级分组计算各级分组的边界
+// 相邻末级分组的前几级分组值相同时属于同一个上级分组；结果按行号排列，父级在子级之前
+func buildViewWindowGroups(fieldIDs []string, leaves []viewWindowLeaf) []ViewWindowGroup {
+	var groups []ViewWindowGroup
+	open := make([]int, len(fieldIDs)) // 各级当前分组在结果中的下标
+	var start int64
+	for i, leaf := range leaves {
+		level := 0
+		if i > 0 {
+			previous := leaves[i-1].values
+			for level < len(fieldIDs)-1 && reflect.DeepEqual(previous[level], leaf.values[level]) {
+				level++
+			}
+		}
+		for ; level < len(fieldIDs); level++ {
+			groups = append(groups, ViewWindowGroup{Level: level, FieldID: fieldIDs[level], Value: leaf.values[level], Start: start})
+			open[level] = len(groups) - 1
+		}
+		for _, index := range open {
+			groups[index].Count += leaf.count
+		}
+		start += leaf.count
+	}
+	return groups
+}
+
+// viewWindowIntersect 与 [offset, end) 相交的分组，以及首行之前开始的分组数
+func viewWindowIntersect(groups []ViewWindowGroup, offset, end int64) ([]ViewWindowGroup, int) {
+	result := []ViewWindowGroup{}
+	before := 0
+	for _, group := range groups {
+		if group.Start < offset {
+			before++
+		}
+		if group.Start < end && group.Start+group.Count > offset {
+			result = append(result, group)
+		}
+	}
+	return result, before
+}
@@ -0,0 +1,53 @@
+package application
+
+import "testing"
+
+func TestBuildViewWindowGroups(t *testing.T) {
+	leaves := []viewWindowLeaf{
+		{values: []interface{}{"Todo", "High"}, count: 3},
+		{values: []interface{}{"Todo", "Low"}, count: 2},
+		{values: []interface{}{"Done", "High"}, count: 4},
+		{values: []interface{}{nil, nil}, count: 1},
+	}
+	groups := buildViewWindowGroups([]string{"fldStatus", "fldPriority"}, leaves)
+
+	expected := []ViewWindowGroup{
+		{Level: 0, FieldID: "fldStatus", Value: "Todo", Start: 0, Count: 5},
+		{Level: 1, FieldID: "fldPriority", Value: "High", Start: 0, Count: 3},
+		{Level: 1, FieldID: "fldPriority", Value: "Low", Start: 3, Count: 2},
+		{Level: 0, FieldID: "fldStatus", Value: "Done", Start: 5, Count: 4},
+		{Level: 1, FieldID: "fldPriority", Value: "High", Start: 5, Count: 4},
+		{Level: 0, FieldID: "fldStatus", Value: nil, Start: 9, Count: 1},
+		{Level: 1, FieldID: "fldPriority", Value: nil, Start: 9, Count: 1},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d groups, got %+v", len(expected), groups)
+	}
+	for i := range expected {
+		if groups[i] != expected[i] {
+			t.Fatalf("group %d: expected %+v, got %+v", i, expected[i], groups[i])
+		}
+	}
+}
+
+func TestViewWindowIntersect(t *testing.T) {
+	groups := buildViewWindowGroups([]string{"fldStatus", "fldPriority"}, []viewWindowLeaf{
+		{values: []interface{}{"Todo", "High"}, count: 3},
+		{values: []interface{}{"Todo", "Low"}, count: 2},
+		{values: []interface{}{"Done", "High"}, count: 4},
+	})
+
+	// 窗口 [4, 6)：Todo/Low 的最后一行与 Done/High 的第一行
+	window, before := viewWindowIntersect(groups, 4, 6)
+	if before != 3 {
+		t.Fatalf("expected 3 headers before row 4, got %d", before)
+	}
+	if len(window) != 4 || window[0].Value != "Todo" || window[1].Value != "Low" || window[2].Value != "Done" {
+		t.Fatalf("expected the window to intersect Todo, Low, Done and High, got %+v", window)
+	}
+
+	window, before = viewWindowIntersect(groups, 20, 30)
+	if len(window) != 0 || before != len(groups) {
+		t.Fatalf("expected no groups past the end, got %+v before=%d", window, before)
+	}
+}
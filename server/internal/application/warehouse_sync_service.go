@@ -0,0 +1,664 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/warehouse"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+var warehouseSyncLog = logger.Named("warehouse_sync")
+
+// warehouseSystemColumns 物理表系统列与目标列的对应关系
+var warehouseSystemColumns = []struct {
+	physical string
+	column   warehouse.Column
+}{
+	{"__version", warehouse.Column{Name: "record_version", Type: warehouse.TypeInt64}},
+	{"__created_time", warehouse.Column{Name: "created_time", Type: warehouse.TypeTimestamp}},
+	{"__last_modified_time", warehouse.Column{Name: "last_modified_time", Type: warehouse.TypeTimestamp}},
+}
+
+// WarehouseClientFactory 根据目标创建数据仓库客户端
+type WarehouseClientFactory func(destination warehousesync.Destination, credentials string) (warehouse.Client, error)
+
+// CreateWarehouseSyncRequest 创建数据仓库同步任务请求
+type CreateWarehouseSyncRequest struct {
+	Name        string                    `json:"name" binding:"required"`
+	TableID     string                    `json:"tableId" binding:"required"`
+	ViewID      string                    `json:"viewId"`
+	Destination warehousesync.Destination `json:"destination" binding:"required"`
+	Credentials string                    `json:"credentials" binding:"required"`
+	Mode        warehousesync.Mode        `json:"mode" binding:"required"`
+	Interval    string                    `json:"interval"` // 定时模式的全量间隔，如 "1h"
+}
+
+// UpdateWarehouseSyncRequest 更新数据仓库同步任务请求（未提供的字段保持不变）
+type UpdateWarehouseSyncRequest struct {
+	Name        *string `json:"name"`
+	Credentials *string `json:"credentials"`
+	Interval    *string `json:"interval"`
+	Enabled     *bool   `json:"enabled"`
+}
+
+// warehouseSyncSource 一次运行读取的源数据范围
+type warehouseSyncSource struct {
+	baseID    string
+	tableName string
+	fields    []*entity.Field
+	columns   []warehouse.Column
+	condition clause.Expression
+}
+
+// WarehouseSyncService 数据仓库同步服务 ✨
+// 将表或视图镜像到 BigQuery / Snowflake，支持两种方式：
+//   - schedule：按间隔全量镜像，写入全部行后将本次未写入的行标记为软删除；
+//   - change_feed：首次全量，之后从变更流增量同步；所选表的字段变化或游标过期时自动改为全量。
+//
+// 每次运行记录写入/删除行数、追加的列与错误，最近一次错误同时保存在任务上
+type WarehouseSyncService struct {
+	repo              warehousesync.Repository
+	changeFeed        *ChangeFeedService
+	tableRepo         tableRepo.TableRepository
+	fieldRepo         repository.FieldRepository
+	viewRepo          viewRepo.ViewRepository
+	dbProvider        database.DBProvider
+	dataDB            DataDBResolver
+	permissionService *PermissionServiceV2
+	privacyService    *PrivacyService
+	newClient         WarehouseClientFactory
+	plans             *PlanService // ✨ 套餐限额（每月后台任务运行次数）
+	cfg               config.WarehouseSyncConfig
+}
+
+// NewWarehouseSyncService 创建数据仓库同步服务
+func NewWarehouseSyncService(
+	repo warehousesync.Repository,
+	changeFeed *ChangeFeedService,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	viewRepo viewRepo.ViewRepository,
+	dbProvider database.DBProvider,
+	dataDB DataDBResolver,
+	permissionService *PermissionServiceV2,
+	privacyService *PrivacyService,
+	cfg config.WarehouseSyncConfig,
+) *WarehouseSyncService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.ChangeFeedInterval <= 0 {
+		cfg.ChangeFeedInterval = time.Minute
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = 15 * time.Minute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 2 * time.Minute
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = 30 * time.Minute
+	}
+	if cfg.RunHistory <= 0 {
+		cfg.RunHistory = 50
+	}
+	httpClient := netguard.NewHTTPClient(cfg.RequestTimeout, cfg.AllowPrivate)
+	return &WarehouseSyncService{
+		repo:              repo,
+		changeFeed:        changeFeed,
+		tableRepo:         tableRepo,
+		fieldRepo:         fieldRepo,
+		viewRepo:          viewRepo,
+		dbProvider:        dbProvider,
+		dataDB:            dataDB,
+		permissionService: permissionService,
+		privacyService:    privacyService,
+		newClient: func(destination warehousesync.Destination, credentials string) (warehouse.Client, error) {
+			return warehouse.New(destination, credentials, httpClient)
+		},
+		cfg: cfg,
+	}
+}
+
+// CreateJob 创建同步任务（创建后由后台立即执行首次全量同步）
+func (s *WarehouseSyncService) CreateJob(ctx context.Context, userID, baseID string, req CreateWarehouseSyncRequest) (*warehousesync.Job, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的数据同步")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+	}
+	table, err := s.tableRepo.GetByID(ctx, req.TableID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "查找表格失败")
+	}
+	if table == nil || table.BaseID() != baseID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	if req.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, req.ViewID)
+		if err != nil {
+			return nil, pkgerrors.Database(err, "查找视图失败")
+		}
+		if view == nil || view.TableID() != req.TableID {
+			return nil, pkgerrors.ErrNotFound.WithDetails("视图不存在")
+		}
+	}
+
+	var interval time.Duration
+	switch req.Mode {
+	case warehousesync.ModeSchedule:
+		if interval, err = s.parseInterval(req.Interval); err != nil {
+			return nil, err
+		}
+	case warehousesync.ModeChangeFeed:
+	default:
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("不支持的同步方式: " + string(req.Mode))
+	}
+	// 构造客户端以校验目标位置与凭据格式（不发起网络请求）
+	if _, err := s.newClient(req.Destination, req.Credentials); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("目标配置无效: " + err.Error())
+	}
+
+	job := warehousesync.NewJob(baseID, req.TableID, req.ViewID, name, req.Destination, req.Credentials, req.Mode, interval, userID)
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步任务失败")
+	}
+	return job, nil
+}
+
+// ListJobs 列出 Base 的同步任务
+func (s *WarehouseSyncService) ListJobs(ctx context.Context, userID, baseID string) ([]*warehousesync.Job, error) {
+	if !s.permissionService.CanUpdateBase(ctx, userID, baseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的数据同步")
+	}
+	jobs, err := s.repo.ListByBase(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步任务失败")
+	}
+	return jobs, nil
+}
+
+// GetJob 获取同步任务
+func (s *WarehouseSyncService) GetJob(ctx context.Context, userID, jobID string) (*warehousesync.Job, error) {
+	return s.loadJob(ctx, userID, jobID)
+}
+
+// UpdateJob 更新同步任务（名称、凭据、间隔、启停）
+func (s *WarehouseSyncService) UpdateJob(ctx context.Context, userID, jobID string, req UpdateWarehouseSyncRequest) (*warehousesync.Job, error) {
+	job, err := s.loadJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("名称不能为空")
+		}
+		job.Name = name
+	}
+	if req.Credentials != nil {
+		if _, err := s.newClient(job.Destination, *req.Credentials); err != nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("凭据无效: " + err.Error())
+		}
+		job.Credentials = *req.Credentials
+	}
+	if req.Interval != nil && job.Mode == warehousesync.ModeSchedule {
+		if job.Interval, err = s.parseInterval(*req.Interval); err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && !job.Enabled {
+			job.NextRunAt = time.Now()
+		}
+		job.Enabled = *req.Enabled
+	}
+	job.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步任务失败")
+	}
+	return job, nil
+}
+
+// TriggerJob 立即执行一次同步（由后台在下一个轮询周期执行）
+func (s *WarehouseSyncService) TriggerJob(ctx context.Context, userID, jobID string) (*warehousesync.Job, error) {
+	job, err := s.loadJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.Enabled {
+		return nil, pkgerrors.ErrConflict.WithDetails("同步任务已停用")
+	}
+	job.NextRunAt = time.Now()
+	job.UpdatedAt = job.NextRunAt
+	if err := s.repo.Save(ctx, job); err != nil {
+		return nil, pkgerrors.Database(err, "保存同步任务失败")
+	}
+	return job, nil
+}
+
+// DeleteJob 删除同步任务（仓库中已写入的数据保留）
+func (s *WarehouseSyncService) DeleteJob(ctx context.Context, userID, jobID string) error {
+	if _, err := s.loadJob(ctx, userID, jobID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, jobID); err != nil {
+		return pkgerrors.Database(err, "删除同步任务失败")
+	}
+	return nil
+}
+
+// ListRuns 获取同步任务最近的运行记录
+func (s *WarehouseSyncService) ListRuns(ctx context.Context, userID, jobID string) ([]*warehousesync.Run, error) {
+	if _, err := s.loadJob(ctx, userID, jobID); err != nil {
+		return nil, err
+	}
+	runs, err := s.repo.ListRuns(ctx, jobID, s.cfg.RunHistory)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步记录失败")
+	}
+	return runs, nil
+}
+
+func (s *WarehouseSyncService) loadJob(ctx context.Context, userID, jobID string) (*warehousesync.Job, error) {
+	job, err := s.repo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取同步任务失败")
+	}
+	if job == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("同步任务不存在")
+	}
+	if !s.permissionService.CanUpdateBase(ctx, userID, job.BaseID) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("没有权限管理该Base的数据同步")
+	}
+	return job, nil
+}
+
+func (s *WarehouseSyncService) parseInterval(raw string) (time.Duration, error) {
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails("定时同步需要有效的间隔，如 1h")
+	}
+	if interval < s.cfg.MinInterval {
+		return 0, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("同步间隔不能小于 %s", s.cfg.MinInterval))
+	}
+	return interval, nil
+}
+
+// SetPlanService 设置套餐服务（每次同步前检查后台任务运行次数限额，超出时本次同步失败）
+func (s *WarehouseSyncService) SetPlanService(plans *PlanService) {
+	s.plans = plans
+}
+
+// Start 启动后台同步
+func (s *WarehouseSyncService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.runDue(ctx)
+		}
+	}()
+}
+
+// runDue 领取并执行到期的任务
+func (s *WarehouseSyncService) runDue(ctx context.Context) {
+	now := time.Now()
+	jobs, err := s.repo.ClaimDue(ctx, now, now.Add(s.cfg.LeaseDuration), 5)
+	if err != nil {
+		warehouseSyncLog.Warn(ctx, "领取同步任务失败", logger.ErrorField(err))
+		return
+	}
+	for _, job := range jobs {
+		s.runJob(ctx, job)
+	}
+}
+
+// runJob 执行一次同步并记录运行结果
+func (s *WarehouseSyncService) runJob(ctx context.Context, job *warehousesync.Job) {
+	run := warehousesync.NewRun(job, job.NeedsFullSync())
+	if err := s.repo.SaveRun(ctx, run); err != nil {
+		warehouseSyncLog.Warn(ctx, "保存同步记录失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+
+	err := s.sync(ctx, job, run)
+	run.Finish(err)
+	if err := s.repo.SaveRun(ctx, run); err != nil {
+		warehouseSyncLog.Warn(ctx, "保存同步记录失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+	if err := s.repo.PurgeRuns(ctx, job.ID, s.cfg.RunHistory); err != nil {
+		warehouseSyncLog.Warn(ctx, "清理同步记录失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+
+	job.LastRunAt = run.FinishedAt
+	job.LastStatus = run.Status
+	job.LastError = run.Error
+	next := s.cfg.ChangeFeedInterval
+	if job.Mode == warehousesync.ModeSchedule {
+		next = job.Interval
+	}
+	job.NextRunAt = time.Now().Add(next)
+	if err := s.repo.SaveProgress(ctx, job); err != nil {
+		warehouseSyncLog.Warn(ctx, "保存同步进度失败", logger.String("job_id", job.ID), logger.ErrorField(err))
+	}
+	if err != nil {
+		warehouseSyncLog.Warn(ctx, "数据仓库同步失败",
+			logger.String("job_id", job.ID),
+			logger.ErrorField(err))
+	}
+}
+
+// sync 执行全量或增量同步
+func (s *WarehouseSyncService) sync(ctx context.Context, job *warehousesync.Job, run *warehousesync.Run) error {
+	if s.plans != nil {
+		if _, err := s.plans.CheckAutomationRun(ctx, job.BaseID); err != nil {
+			return err
+		}
+	}
+	client, err := s.newClient(job.Destination, job.Credentials)
+	if err != nil {
+		return err
+	}
+	source, err := s.loadSource(ctx, job)
+	if err != nil {
+		return err
+	}
+	added, err := client.EnsureTable(ctx, source.columns)
+	run.AddedColumns = added
+	if err != nil {
+		return err
+	}
+
+	if !run.Full {
+		err = s.syncIncremental(ctx, job, run, client, source)
+		if !errors.Is(err, errWarehouseNeedsFullSync) {
+			return err
+		}
+		// 字段变化或游标过期：改为全量同步以回填新列并修正遗漏
+		run.Full = true
+	}
+	return s.syncFull(ctx, job, run, client, source)
+}
+
+// errWarehouseNeedsFullSync 增量同步无法继续，需要全量同步
+var errWarehouseNeedsFullSync = errors.New("warehouse sync needs full resync")
+
+// syncFull 全量镜像：写入全部行，本次未写入的行标记为软删除
+// 读取前先记录变更流游标，之后的增量同步从该游标继续，不会遗漏读取期间的变更
+func (s *WarehouseSyncService) syncFull(ctx context.Context, job *warehousesync.Job, run *warehousesync.Run, client warehouse.Client, source *warehouseSyncSource) error {
+	head, err := s.changeFeed.repo.Head(ctx)
+	if err != nil {
+		return err
+	}
+	syncedAt := time.Now()
+
+	after := ""
+	for {
+		rows, err := s.readRows(ctx, source, nil, after)
+		if err != nil {
+			return err
+		}
+		if err := client.Upsert(ctx, source.columns, rows, syncedAt); err != nil {
+			return err
+		}
+		run.Upserted += int64(len(rows))
+		if len(rows) < s.cfg.BatchSize {
+			break
+		}
+		after = rows[len(rows)-1][warehouse.ColumnRecordID].(string)
+	}
+
+	deleted, err := client.MarkStale(ctx, syncedAt)
+	if err != nil {
+		return err
+	}
+	run.Deleted += deleted
+	job.Cursor = head
+	return nil
+}
+
+// syncIncremental 从变更流读取该表游标之后的记录变更并写入仓库
+// 每批写入成功后推进游标；视图任务中不再满足视图过滤条件的记录按删除处理
+func (s *WarehouseSyncService) syncIncremental(ctx context.Context, job *warehousesync.Job, run *warehousesync.Run, client warehouse.Client, source *warehouseSyncSource) error {
+	for {
+		changes, err := s.changeFeed.repo.ListAfter(ctx, job.BaseID, changefeed.ListFilter{
+			After:   job.Cursor,
+			TableID: job.TableID,
+			Limit:   s.cfg.BatchSize,
+		})
+		if errors.Is(err, changefeed.ErrCursorExpired) {
+			return errWarehouseNeedsFullSync
+		}
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+
+		changed := make(map[string]bool)
+		for _, change := range changes {
+			switch change.EntityType {
+			case changefeed.EntityField:
+				if change.Action != changefeed.ActionDelete {
+					return errWarehouseNeedsFullSync
+				}
+			case changefeed.EntityRecord:
+				changed[change.EntityID] = true
+			}
+		}
+
+		if len(changed) > 0 {
+			ids := make([]string, 0, len(changed))
+			for id := range changed {
+				ids = append(ids, id)
+			}
+			rows, err := s.readRows(ctx, source, ids, "")
+			if err != nil {
+				return err
+			}
+			if err := client.Upsert(ctx, source.columns, rows, time.Now()); err != nil {
+				return err
+			}
+			run.Upserted += int64(len(rows))
+
+			// 已删除或不再满足视图条件的记录
+			for _, row := range rows {
+				delete(changed, row[warehouse.ColumnRecordID].(string))
+			}
+			removed := make([]string, 0, len(changed))
+			for id := range changed {
+				removed = append(removed, id)
+			}
+			deleted, err := client.MarkDeleted(ctx, removed, time.Now())
+			if err != nil {
+				return err
+			}
+			run.Deleted += deleted
+		}
+
+		job.Cursor = changes[len(changes)-1].Seq
+		if len(changes) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// loadSource 加载表结构与视图过滤条件，并确定同步的列
+// 加密字段与受脱敏策略保护的字段不会同步到仓库
+func (s *WarehouseSyncService) loadSource(ctx context.Context, job *warehousesync.Job) (*warehouseSyncSource, error) {
+	table, err := s.tableRepo.GetByID(ctx, job.TableID)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("table %s not found", job.TableID)
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, job.TableID)
+	if err != nil {
+		return nil, err
+	}
+
+	var protected map[string]bool
+	if s.privacyService != nil {
+		protected = s.privacyService.ProtectedFieldIDs(ctx, job.TableID)
+	}
+	source := &warehouseSyncSource{
+		baseID:    table.BaseID(),
+		tableName: s.dbProvider.GenerateTableName(table.BaseID(), job.TableID),
+	}
+	byID := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+		if field.IsVirtual() || field.IsEncrypted() || protected[field.ID().String()] {
+			continue
+		}
+		source.fields = append(source.fields, field)
+	}
+	sort.SliceStable(source.fields, func(i, j int) bool { return source.fields[i].Order() < source.fields[j].Order() })
+	source.columns = warehouseColumns(source.fields)
+
+	if job.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, job.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		if view == nil {
+			return nil, fmt.Errorf("view %s not found", job.ViewID)
+		}
+		if source.condition, err = buildViewFilterCondition(ctx, view.Filter(), byID); err != nil {
+			return nil, err
+		}
+	}
+	return source, nil
+}
+
+// warehouseColumns 系统列与字段列（列名使用字段的物理列名，字段改名不影响目标表）
+func warehouseColumns(fields []*entity.Field) []warehouse.Column {
+	columns := make([]warehouse.Column, 0, len(warehouseSystemColumns)+len(fields))
+	for _, sys := range warehouseSystemColumns {
+		columns = append(columns, sys.column)
+	}
+	for _, field := range fields {
+		columns = append(columns, warehouse.Column{
+			Name: field.DBFieldName().String(),
+			Type: warehouseColumnType(field),
+		})
+	}
+	return columns
+}
+
+// warehouseColumnType 字段存储类别对应的仓库列类型（多值与 JSON 字段以 JSON 文本存储）
+func warehouseColumnType(field *entity.Field) warehouse.ColumnType {
+	switch columnKindOf(field) {
+	case columnKindNumber:
+		dbType := strings.ToUpper(field.DBFieldType())
+		if dbType == "INTEGER" || dbType == "BIGINT" || dbType == "SERIAL" {
+			return warehouse.TypeInt64
+		}
+		return warehouse.TypeFloat64
+	case columnKindDate:
+		return warehouse.TypeTimestamp
+	case columnKindBoolean:
+		return warehouse.TypeBool
+	default:
+		return warehouse.TypeString
+	}
+}
+
+// readRows 读取一批源数据：ids 非空时读取指定记录，否则按记录ID顺序读取 after 之后的一页
+func (s *WarehouseSyncService) readRows(ctx context.Context, source *warehouseSyncSource, ids []string, after string) ([]warehouse.Row, error) {
+	selects := []string{"__id"}
+	for _, sys := range warehouseSystemColumns {
+		selects = append(selects, sys.physical)
+	}
+	for _, field := range source.fields {
+		selects = append(selects, field.DBFieldName().String())
+	}
+	quoted := make([]string, 0, len(selects))
+	for _, col := range selects {
+		quoted = append(quoted, `"`+strings.ReplaceAll(col, `"`, `""`)+`"`)
+	}
+
+	db := s.dataDB(ctx, source.baseID).WithContext(ctx).
+		Table(source.tableName).
+		Select(strings.Join(quoted, ", "))
+	if source.condition != nil {
+		db = db.Where(source.condition)
+	}
+	if len(ids) > 0 {
+		db = db.Where("__id IN ?", ids)
+	} else {
+		db = db.Where("__id > ?", after).Order("__id ASC").Limit(s.cfg.BatchSize)
+	}
+
+	var records []map[string]interface{}
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]warehouse.Row, 0, len(records))
+	for _, record := range records {
+		row := warehouse.Row{warehouse.ColumnRecordID: fmt.Sprint(record["__id"])}
+		for _, sys := range warehouseSystemColumns {
+			row[sys.column.Name] = warehouseValue(record[sys.physical], sys.column.Type)
+		}
+		for i, field := range source.fields {
+			column := source.columns[len(warehouseSystemColumns)+i]
+			row[column.Name] = warehouseValue(record[field.DBFieldName().String()], column.Type)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// warehouseValue 将物理列的值转换为写入仓库的 JSON 值
+func warehouseValue(value interface{}, columnType warehouse.ColumnType) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	case string, bool, int, int32, int64, float32, float64:
+		if columnType == warehouse.TypeString {
+			return fmt.Sprint(v)
+		}
+		return v
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,30 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/warehouse"
+)
+
+func TestWarehouseValue(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	cases := []struct {
+		name  string
+		value interface{}
+		typ   warehouse.ColumnType
+		want  interface{}
+	}{
+		{"空值", nil, warehouse.TypeString, nil},
+		{"时间转为 UTC", ts, warehouse.TypeTimestamp, "2024-05-01T00:00:00Z"},
+		{"JSON 字节", []byte(`{"a":1}`), warehouse.TypeString, `{"a":1}`},
+		{"文本列中的数字", int64(3), warehouse.TypeString, "3"},
+		{"数值列保留数字", 1.5, warehouse.TypeFloat64, 1.5},
+		{"多值序列化为 JSON", []interface{}{"a", "b"}, warehouse.TypeString, `["a","b"]`},
+	}
+	for _, c := range cases {
+		if got := warehouseValue(c.value, c.typ); got != c.want {
+			t.Errorf("%s: 期望 %v，得到 %v", c.name, c.want, got)
+		}
+	}
+}
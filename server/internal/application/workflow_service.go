@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
@@ -12,7 +13,8 @@ import (
 
 // WorkflowService 工作流服务
 type WorkflowService struct {
-	db *gorm.DB
+	db      *gorm.DB
+	scripts *ScriptActionService // 可选，执行运行脚本节点
 }
 
 // NewWorkflowService 创建工作流服务
@@ -20,6 +22,11 @@ func NewWorkflowService(db *gorm.DB) *WorkflowService {
 	return &WorkflowService{db: db}
 }
 
+// SetScriptActions 设置运行脚本动作服务，设置后运行工作流时执行其中的运行脚本节点
+func (s *WorkflowService) SetScriptActions(scripts *ScriptActionService) {
+	s.scripts = scripts
+}
+
 // Create 创建工作流
 func (s *WorkflowService) Create(ctx context.Context, workflow *models.Workflow) error {
 	workflow.ID = utils.GenerateIDWithPrefix("wfl")
@@ -81,16 +88,31 @@ func (s *WorkflowService) Run(ctx context.Context, workflowID, userID string, in
 		ID:          utils.GenerateIDWithPrefix("wfr"),
 		WorkflowID:  workflowID,
 		CreatedBy:   userID,
+		TriggerType: "manual",
 		Status:      "running",
 		StartedTime: &now,
 	}
+	if len(input) > 0 {
+		// 保存触发时的输入（如页面按钮点击时的记录），供执行器读取
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+		encoded := string(data)
+		run.Input = &encoded
+	}
 
 	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
 		return nil, err
 	}
 
 	// 工作流执行逻辑应该在后台异步执行（参考 teable-develop 使用 BullMQ）
-	// 这里只是创建了运行记录
+	// 目前只执行运行脚本与生成 PDF 节点，其他工作流只创建运行记录
+	if s.scripts != nil {
+		if _, err := s.scripts.StartRun(ctx, run, input); err != nil {
+			return nil, err
+		}
+	}
 
 	return run, nil
 }
@@ -130,13 +152,16 @@ func (s *WorkflowService) ListRuns(ctx context.Context, workflowID string, page,
 
 // StopRun 停止工作流运行
 func (s *WorkflowService) StopRun(ctx context.Context, runID string) error {
+	if s.scripts != nil {
+		s.scripts.CancelRun(runID)
+	}
 	now := time.Now()
 	return s.db.WithContext(ctx).
 		Model(&models.WorkflowRun{}).
 		Where("id = ?", runID).
 		Updates(map[string]interface{}{
-			"status":       "stopped",
-			"completed_at": &now,
+			"status":         "stopped",
+			"completed_time": &now,
 		}).Error
 }
 
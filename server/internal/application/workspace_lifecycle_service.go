@@ -0,0 +1,675 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/workspacelifecycle"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+var workspaceLifecycleLog = logger.Named("workspace_lifecycle")
+
+const (
+	// lifecycleStateTTL 只读状态缓存时间（每个写请求都会检查，避免频繁查库）
+	lifecycleStateTTL = 30 * time.Second
+	// workspaceBundleHistory 列出导出包时返回的条数
+	workspaceBundleHistory = 20
+)
+
+// workspaceCache 清除工作空间时失效的缓存
+type workspaceCache interface {
+	InvalidateTableCache(ctx context.Context, tableID string) error
+	InvalidatePattern(ctx context.Context, pattern string) error
+}
+
+// SuspendWorkspaceRequest 停用工作空间请求
+type SuspendWorkspaceRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ScheduleWorkspaceDeletionRequest 计划删除工作空间请求
+type ScheduleWorkspaceDeletionRequest struct {
+	GraceDays int    `json:"graceDays"` // 宽限期天数，为 0 时使用默认值
+	Reason    string `json:"reason"`
+}
+
+// WorkspaceBundleDetail 导出包及清单下载链接（完成后提供）
+type WorkspaceBundleDetail struct {
+	*workspacelifecycle.Bundle
+	ManifestURL string `json:"manifest_url,omitempty"`
+}
+
+type cachedLifecycleState struct {
+	state     workspacelifecycle.State
+	expiresAt time.Time
+}
+
+// WorkspaceLifecycleService 工作空间生命周期服务 ✨
+// 管理员可以停用工作空间或计划删除，两种状态下工作空间只允许读取（由中间件拒绝写请求）；
+// 宽限期结束后后台先确认存在计划删除之后生成的完整导出包（没有则当场生成），
+// 再删除全部 Base 的物理表与 Schema、附件文件、元数据与变更事件，最后使相关缓存失效
+type WorkspaceLifecycleService struct {
+	repo    workspacelifecycle.Repository
+	store   workspacelifecycle.DataStore
+	storage attachment.Storage
+	cache   workspaceCache
+	cfg     config.WorkspaceLifecycleConfig
+	now     func() time.Time
+
+	states sync.Map // spaceID -> cachedLifecycleState
+}
+
+// NewWorkspaceLifecycleService 创建工作空间生命周期服务（cache 可为空）
+func NewWorkspaceLifecycleService(
+	repo workspacelifecycle.Repository,
+	store workspacelifecycle.DataStore,
+	storage attachment.Storage,
+	cache workspaceCache,
+	cfg config.WorkspaceLifecycleConfig,
+) *WorkspaceLifecycleService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.DefaultGracePeriod <= 0 {
+		cfg.DefaultGracePeriod = 30 * 24 * time.Hour
+	}
+	if cfg.MinGracePeriod <= 0 {
+		cfg.MinGracePeriod = 24 * time.Hour
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = time.Hour
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = time.Hour
+	}
+	if c, ok := cache.(*CacheService); ok && c == nil {
+		cache = nil
+	}
+	return &WorkspaceLifecycleService{
+		repo:    repo,
+		store:   store,
+		storage: storage,
+		cache:   cache,
+		cfg:     cfg,
+		now:     time.Now,
+	}
+}
+
+// ==================== 管理操作 ====================
+
+// Get 获取工作空间的生命周期（从未变更过的空间为正常状态）
+func (s *WorkspaceLifecycleService) Get(ctx context.Context, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	return s.load(ctx, spaceID)
+}
+
+// List 列出生命周期（state 为空时列出全部变更过状态的空间）
+func (s *WorkspaceLifecycleService) List(ctx context.Context, state string) ([]*workspacelifecycle.Lifecycle, error) {
+	var states []workspacelifecycle.State
+	if state != "" {
+		if !workspacelifecycle.IsValidState(workspacelifecycle.State(state)) {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的状态: %s", state))
+		}
+		states = append(states, workspacelifecycle.State(state))
+	}
+	list, err := s.repo.List(ctx, states)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取工作空间状态失败")
+	}
+	return list, nil
+}
+
+// Suspend 停用工作空间
+func (s *WorkspaceLifecycleService) Suspend(ctx context.Context, userID, spaceID string, req SuspendWorkspaceRequest) (*workspacelifecycle.Lifecycle, error) {
+	if err := validateLifecycleReason(req.Reason); err != nil {
+		return nil, err
+	}
+	return s.change(ctx, spaceID, func(l *workspacelifecycle.Lifecycle) error {
+		return l.Suspend(req.Reason, userID, s.now())
+	})
+}
+
+// Resume 恢复已停用的工作空间
+func (s *WorkspaceLifecycleService) Resume(ctx context.Context, userID, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	return s.change(ctx, spaceID, func(l *workspacelifecycle.Lifecycle) error {
+		return l.Resume(userID, s.now())
+	})
+}
+
+// ScheduleDeletion 计划在宽限期后删除工作空间
+func (s *WorkspaceLifecycleService) ScheduleDeletion(ctx context.Context, userID, spaceID string, req ScheduleWorkspaceDeletionRequest) (*workspacelifecycle.Lifecycle, error) {
+	if err := validateLifecycleReason(req.Reason); err != nil {
+		return nil, err
+	}
+	if req.GraceDays < 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("graceDays 不能为负数")
+	}
+	grace := s.cfg.DefaultGracePeriod
+	if req.GraceDays > 0 {
+		grace = time.Duration(req.GraceDays) * 24 * time.Hour
+	}
+	if grace < s.cfg.MinGracePeriod {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("宽限期不能少于 %s", s.cfg.MinGracePeriod))
+	}
+	return s.change(ctx, spaceID, func(l *workspacelifecycle.Lifecycle) error {
+		return l.ScheduleDeletion(req.Reason, grace, userID, s.now())
+	})
+}
+
+// CancelDeletion 撤销计划删除（工作空间保持停用）
+func (s *WorkspaceLifecycleService) CancelDeletion(ctx context.Context, userID, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	return s.change(ctx, spaceID, func(l *workspacelifecycle.Lifecycle) error {
+		return l.CancelDeletion(userID, s.now())
+	})
+}
+
+// change 加载生命周期、执行状态变更并保存
+func (s *WorkspaceLifecycleService) change(ctx context.Context, spaceID string, apply func(l *workspacelifecycle.Lifecycle) error) (*workspacelifecycle.Lifecycle, error) {
+	l, err := s.load(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := apply(l); err != nil {
+		return nil, pkgerrors.ErrConflict.WithDetails(err.Error())
+	}
+	if err := s.repo.Save(ctx, l); err != nil {
+		return nil, pkgerrors.Database(err, "保存工作空间状态失败")
+	}
+	s.states.Delete(spaceID)
+
+	workspaceLifecycleLog.Info(ctx, "工作空间状态已变更",
+		logger.String("space_id", spaceID),
+		logger.String("state", string(l.State)),
+		logger.String("user_id", l.UpdatedBy))
+	return l, nil
+}
+
+// load 获取生命周期；没有记录时确认空间存在后返回正常状态
+func (s *WorkspaceLifecycleService) load(ctx context.Context, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	l, err := s.repo.FindBySpace(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取工作空间状态失败")
+	}
+	if l != nil {
+		return l, nil
+	}
+	inv, err := s.store.Inventory(ctx, spaceID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取工作空间失败")
+	}
+	if inv == nil {
+		return nil, pkgerrors.ErrSpaceNotFound
+	}
+	return workspacelifecycle.NewLifecycle(spaceID, s.now()), nil
+}
+
+func validateLifecycleReason(reason string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(reason)) > workspacelifecycle.MaxReasonLength {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("原因不能超过 %d 个字符", workspacelifecycle.MaxReasonLength))
+	}
+	return nil
+}
+
+// ==================== 只读检查 ====================
+
+// CheckWritable 检查工作空间是否允许写入（停用、计划删除与清除中的工作空间只允许读取）
+// 状态查询失败时拒绝写入（无法确认工作空间未被停用）
+func (s *WorkspaceLifecycleService) CheckWritable(ctx context.Context, spaceID string) error {
+	if spaceID == "" {
+		return nil
+	}
+	state, err := s.state(ctx, spaceID)
+	if err != nil {
+		workspaceLifecycleLog.Warn(ctx, "获取工作空间状态失败", logger.String("space_id", spaceID), logger.ErrorField(err))
+		return pkgerrors.Database(err, "获取工作空间状态失败")
+	}
+	if state != workspacelifecycle.StateActive {
+		return pkgerrors.ErrSpaceReadOnly.WithDetails(map[string]interface{}{
+			"space_id": spaceID,
+			"state":    state,
+		})
+	}
+	return nil
+}
+
+// state 获取工作空间状态（带缓存）
+func (s *WorkspaceLifecycleService) state(ctx context.Context, spaceID string) (workspacelifecycle.State, error) {
+	now := s.now()
+	if v, ok := s.states.Load(spaceID); ok {
+		if cached := v.(cachedLifecycleState); now.Before(cached.expiresAt) {
+			return cached.state, nil
+		}
+	}
+	l, err := s.repo.FindBySpace(ctx, spaceID)
+	if err != nil {
+		return "", err
+	}
+	state := workspacelifecycle.StateActive
+	if l != nil {
+		state = l.State
+	}
+	s.states.Store(spaceID, cachedLifecycleState{state: state, expiresAt: now.Add(lifecycleStateTTL)})
+	return state, nil
+}
+
+// ==================== 导出包 ====================
+
+// CreateBundle 生成工作空间的完整导出包（后台执行）
+func (s *WorkspaceLifecycleService) CreateBundle(ctx context.Context, userID, spaceID string) (*workspacelifecycle.Bundle, error) {
+	l, err := s.load(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if l.State == workspacelifecycle.StatePurging || l.State == workspacelifecycle.StatePurged {
+		return nil, pkgerrors.ErrConflict.WithDetails("工作空间正在清除或已清除")
+	}
+	bundles, err := s.repo.ListBundles(ctx, spaceID, 1)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取导出包失败")
+	}
+	now := s.now()
+	if len(bundles) > 0 && bundles[0].Running(now.Add(-s.cfg.LeaseDuration)) {
+		return nil, pkgerrors.ErrConflict.WithDetails("已有导出包正在生成")
+	}
+
+	bundle := workspacelifecycle.NewBundle(spaceID, userID, now)
+	if err := s.repo.SaveBundle(ctx, bundle); err != nil {
+		return nil, pkgerrors.Database(err, "保存导出包失败")
+	}
+
+	// 后台执行：不随请求取消，但挂在当前链路下
+	tracing.Go(ctx, "workspace.export", func(ctx context.Context) {
+		_ = s.runBundle(ctx, bundle, nil)
+	})
+	return bundle, nil
+}
+
+// ListBundles 按创建时间倒序列出导出包（已清除的工作空间同样可查）
+func (s *WorkspaceLifecycleService) ListBundles(ctx context.Context, spaceID string) ([]*workspacelifecycle.Bundle, error) {
+	bundles, err := s.repo.ListBundles(ctx, spaceID, workspaceBundleHistory)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取导出包失败")
+	}
+	return bundles, nil
+}
+
+// GetBundle 获取导出包，已完成的附带清单下载链接
+func (s *WorkspaceLifecycleService) GetBundle(ctx context.Context, spaceID, bundleID string) (*WorkspaceBundleDetail, error) {
+	bundle, err := s.repo.FindBundle(ctx, bundleID)
+	if err != nil {
+		return nil, pkgerrors.Database(err, "获取导出包失败")
+	}
+	if bundle == nil || bundle.SpaceID != spaceID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("导出包不存在")
+	}
+
+	detail := &WorkspaceBundleDetail{Bundle: bundle}
+	if bundle.Status != workspacelifecycle.BundleCompleted {
+		return detail, nil
+	}
+	if detail.ManifestURL, err = s.storage.GetURL(ctx, bundle.ManifestPath(), s.cfg.URLExpiry); err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("生成下载链接失败")
+	}
+	return detail, nil
+}
+
+// runBundle 生成导出包并保存结果；heartbeat 在每个文件写入后调用（可为空）
+func (s *WorkspaceLifecycleService) runBundle(ctx context.Context, bundle *workspacelifecycle.Bundle, heartbeat func()) error {
+	manifest, err := s.export(ctx, bundle, heartbeat)
+	bundle.Finish(manifest, err, s.now())
+	if saveErr := s.repo.SaveBundle(ctx, bundle); saveErr != nil {
+		workspaceLifecycleLog.Warn(ctx, "保存导出包失败", logger.String("bundle_id", bundle.ID), logger.ErrorField(saveErr))
+		if err == nil {
+			err = saveErr
+		}
+	}
+	if err != nil {
+		workspaceLifecycleLog.Warn(ctx, "工作空间导出失败",
+			logger.String("bundle_id", bundle.ID),
+			logger.String("space_id", bundle.SpaceID),
+			logger.ErrorField(err))
+	}
+	return err
+}
+
+// export 依次导出元数据表、数据表与附件文件，最后写入清单
+func (s *WorkspaceLifecycleService) export(ctx context.Context, bundle *workspacelifecycle.Bundle, heartbeat func()) (*workspacelifecycle.Manifest, error) {
+	inv, err := s.store.Inventory(ctx, bundle.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if inv == nil {
+		return nil, fmt.Errorf("space not found")
+	}
+	progress := func() error {
+		bundle.UpdatedAt = s.now()
+		if heartbeat != nil {
+			heartbeat()
+		}
+		return s.repo.SaveBundle(ctx, bundle)
+	}
+
+	manifest := &workspacelifecycle.Manifest{
+		Format:      workspacelifecycle.BundleFormat,
+		BundleID:    bundle.ID,
+		SpaceID:     bundle.SpaceID,
+		CreatedAt:   bundle.CreatedAt,
+		Metadata:    []workspacelifecycle.ManifestFile{},
+		Tables:      []workspacelifecycle.ManifestTable{},
+		Attachments: workspacelifecycle.ManifestAttachments{Prefix: bundle.AttachmentPath("")},
+	}
+
+	tables, err := s.store.MetadataTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		path := bundle.MetadataPath(table)
+		rows, err := s.writeJSONL(ctx, path, false, func(fn func(rows []map[string]interface{}) error) error {
+			return s.store.ScanMetadata(ctx, table, inv, s.cfg.BatchSize, fn)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export metadata %s: %w", table, err)
+		}
+		if rows > 0 {
+			manifest.Metadata = append(manifest.Metadata, workspacelifecycle.ManifestFile{Name: table, Path: path, Rows: rows})
+		}
+	}
+	if err := progress(); err != nil {
+		return nil, err
+	}
+
+	// 已软删除的表物理表可能已不存在，只保留其元数据
+	for _, ref := range inv.Tables {
+		if ref.Deleted {
+			continue
+		}
+		path := bundle.DataPath(ref.BaseID, ref.TableID)
+		rows, err := s.writeJSONL(ctx, path, true, func(fn func(rows []map[string]interface{}) error) error {
+			return s.store.ScanRows(ctx, ref, s.cfg.BatchSize, fn)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("export table %s: %w", ref.TableID, err)
+		}
+		manifest.Tables = append(manifest.Tables, workspacelifecycle.ManifestTable{
+			BaseID:  ref.BaseID,
+			TableID: ref.TableID,
+			Name:    ref.Name,
+			Path:    path,
+			Rows:    rows,
+		})
+		if err := progress(); err != nil {
+			return nil, err
+		}
+	}
+
+	files, err := s.store.Attachments(ctx, inv)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, file := range files {
+		for _, path := range file.Paths() {
+			if path == "" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			size, ok, err := s.copyAttachment(ctx, path, bundle.AttachmentPath(path))
+			if err != nil {
+				return nil, fmt.Errorf("export attachment %s: %w", file.ID, err)
+			}
+			if !ok {
+				manifest.Attachments.Missing++
+				continue
+			}
+			manifest.Attachments.Files++
+			manifest.Attachments.Bytes += size
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storage.Upload(ctx, bundle.ManifestPath(), bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return nil, fmt.Errorf("upload manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// writeJSONL 将分页读取的行写为 JSON Lines 临时文件后上传；keepEmpty 为 false 时没有行则不上传
+func (s *WorkspaceLifecycleService) writeJSONL(ctx context.Context, path string, keepEmpty bool, scan func(fn func(rows []map[string]interface{}) error) error) (int64, error) {
+	file, err := os.CreateTemp("", "luckdb-workspace-*.jsonl")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	var count int64
+	encoder := json.NewEncoder(file)
+	if err := scan(func(rows []map[string]interface{}) error {
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		count += int64(len(rows))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	if count == 0 && !keepEmpty {
+		return 0, nil
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if err := s.storage.Upload(ctx, path, file, size, "application/x-ndjson"); err != nil {
+		return 0, fmt.Errorf("upload %s: %w", path, err)
+	}
+	return count, nil
+}
+
+// copyAttachment 将附件文件复制到导出包（文件已不存在时返回 false）
+func (s *WorkspaceLifecycleService) copyAttachment(ctx context.Context, from, to string) (int64, bool, error) {
+	exists, err := s.storage.Exists(ctx, from)
+	if err != nil || !exists {
+		return 0, false, err
+	}
+	size, err := s.storage.GetSize(ctx, from)
+	if err != nil {
+		return 0, false, err
+	}
+	reader, err := s.storage.Download(ctx, from)
+	if err != nil {
+		return 0, false, err
+	}
+	defer reader.Close()
+	if err := s.storage.Upload(ctx, to, reader, size, ""); err != nil {
+		return 0, false, err
+	}
+	return size, true, nil
+}
+
+// ==================== 到期清除 ====================
+
+// Start 启动后台清除（宽限期结束的工作空间）
+func (s *WorkspaceLifecycleService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			s.purgeDue(ctx)
+		}
+	}()
+}
+
+// purgeDue 领取并清除宽限期已结束或清除已中断的工作空间（每次一个，清除耗时较长）
+func (s *WorkspaceLifecycleService) purgeDue(ctx context.Context) {
+	now := s.now()
+	lifecycles, err := s.repo.ClaimDue(ctx, now, now.Add(-s.cfg.LeaseDuration), 1)
+	if err != nil {
+		workspaceLifecycleLog.Warn(ctx, "领取到期删除失败", logger.ErrorField(err))
+		return
+	}
+	for _, l := range lifecycles {
+		s.runPurge(ctx, l)
+	}
+}
+
+// runPurge 清除工作空间并保存结果；失败时回到计划删除状态，下个周期重试
+func (s *WorkspaceLifecycleService) runPurge(ctx context.Context, l *workspacelifecycle.Lifecycle) {
+	s.states.Delete(l.SpaceID)
+	bundleID, report, err := s.purge(ctx, l)
+	l.FinishPurge(bundleID, report, err, s.now())
+	if saveErr := s.repo.Save(ctx, l); saveErr != nil {
+		workspaceLifecycleLog.Warn(ctx, "保存工作空间状态失败", logger.String("space_id", l.SpaceID), logger.ErrorField(saveErr))
+	}
+	if err != nil {
+		workspaceLifecycleLog.Warn(ctx, "工作空间清除失败",
+			logger.String("space_id", l.SpaceID),
+			logger.ErrorField(err))
+		return
+	}
+	workspaceLifecycleLog.Info(ctx, "工作空间已清除",
+		logger.String("space_id", l.SpaceID),
+		logger.String("bundle_id", bundleID),
+		logger.Int("dynamic_tables", report.DynamicTables),
+		logger.Int("attachment_files", report.AttachmentFiles))
+}
+
+// purge 确认导出包后删除物理表、附件文件与元数据，最后使缓存失效
+func (s *WorkspaceLifecycleService) purge(ctx context.Context, l *workspacelifecycle.Lifecycle) (string, *workspacelifecycle.PurgeReport, error) {
+	bundle, err := s.finalBundle(ctx, l)
+	if err != nil {
+		return "", nil, err
+	}
+	report := &workspacelifecycle.PurgeReport{BundleID: bundle.ID, MetadataRows: map[string]int64{}}
+
+	inv, err := s.store.Inventory(ctx, l.SpaceID)
+	if err != nil {
+		return bundle.ID, nil, err
+	}
+	if inv == nil {
+		// 上次清除已删除空间但未能保存结果
+		return bundle.ID, report, nil
+	}
+	report.Bases = len(inv.BaseIDs)
+	for _, ref := range inv.Tables {
+		if !ref.Deleted {
+			report.DynamicTables++
+		}
+	}
+	files, err := s.store.Attachments(ctx, inv)
+	if err != nil {
+		return bundle.ID, nil, err
+	}
+
+	if err := s.store.DropDataTables(ctx, inv); err != nil {
+		return bundle.ID, nil, err
+	}
+	for _, file := range files {
+		for _, path := range file.OwnedPaths() {
+			if path == "" {
+				continue
+			}
+			if s.deleteFile(ctx, path) {
+				report.AttachmentFiles++
+			} else {
+				report.AttachmentErrors++
+			}
+		}
+	}
+	if report.MetadataRows, report.ReleasedBlobs, err = s.store.PurgeMetadata(ctx, inv); err != nil {
+		return bundle.ID, nil, err
+	}
+	s.invalidateCaches(ctx, inv)
+	return bundle.ID, report, nil
+}
+
+// finalBundle 获取计划删除之后生成的导出包，没有时当场生成（期间续约清除租约）
+func (s *WorkspaceLifecycleService) finalBundle(ctx context.Context, l *workspacelifecycle.Lifecycle) (*workspacelifecycle.Bundle, error) {
+	bundles, err := s.repo.ListBundles(ctx, l.SpaceID, workspaceBundleHistory)
+	if err != nil {
+		return nil, err
+	}
+	for _, bundle := range bundles {
+		if bundle.Covers(l) {
+			return bundle, nil
+		}
+	}
+
+	bundle := workspacelifecycle.NewBundle(l.SpaceID, "", s.now())
+	if err := s.repo.SaveBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+	heartbeat := func() {
+		l.UpdatedAt = s.now()
+		if err := s.repo.Save(ctx, l); err != nil {
+			workspaceLifecycleLog.Warn(ctx, "续约清除租约失败", logger.String("space_id", l.SpaceID), logger.ErrorField(err))
+		}
+	}
+	if err := s.runBundle(ctx, bundle, heartbeat); err != nil {
+		return nil, fmt.Errorf("export bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// deleteFile 删除附件文件（文件已不存在视为成功）
+func (s *WorkspaceLifecycleService) deleteFile(ctx context.Context, path string) bool {
+	err := s.storage.Delete(ctx, path)
+	if err == nil {
+		return true
+	}
+	if exists, existsErr := s.storage.Exists(ctx, path); existsErr == nil && !exists {
+		return true
+	}
+	workspaceLifecycleLog.Warn(ctx, "删除附件文件失败", logger.String("path", path), logger.ErrorField(err))
+	return false
+}
+
+// invalidateCaches 使空间、Base 与数据表相关的缓存失效
+func (s *WorkspaceLifecycleService) invalidateCaches(ctx context.Context, inv *workspacelifecycle.Inventory) {
+	if s.cache == nil {
+		return
+	}
+	for _, ref := range inv.Tables {
+		if err := s.cache.InvalidateTableCache(ctx, ref.TableID); err != nil {
+			workspaceLifecycleLog.Warn(ctx, "表缓存失效失败", logger.String("table_id", ref.TableID), logger.ErrorField(err))
+		}
+	}
+	for _, id := range inv.IDs() {
+		if err := s.cache.InvalidatePattern(ctx, "*"+id+"*"); err != nil {
+			workspaceLifecycleLog.Warn(ctx, "缓存失效失败", logger.String("id", id), logger.ErrorField(err))
+		}
+	}
+}
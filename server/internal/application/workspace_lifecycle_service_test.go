@@ -0,0 +1,387 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/workspacelifecycle"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// memoryLifecycleRepo 内存实现的工作空间生命周期仓储
+type memoryLifecycleRepo struct {
+	lifecycles map[string]*workspacelifecycle.Lifecycle
+	bundles    []*workspacelifecycle.Bundle
+	findErr    error // 模拟状态查询失败
+}
+
+func (r *memoryLifecycleRepo) FindBySpace(_ context.Context, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	if r.findErr != nil {
+		return nil, r.findErr
+	}
+	if l, ok := r.lifecycles[spaceID]; ok {
+		copied := *l
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryLifecycleRepo) Save(_ context.Context, l *workspacelifecycle.Lifecycle) error {
+	copied := *l
+	r.lifecycles[l.SpaceID] = &copied
+	return nil
+}
+
+func (r *memoryLifecycleRepo) List(_ context.Context, states []workspacelifecycle.State) ([]*workspacelifecycle.Lifecycle, error) {
+	var list []*workspacelifecycle.Lifecycle
+	for _, l := range r.lifecycles {
+		if len(states) == 0 || l.State == states[0] {
+			list = append(list, l)
+		}
+	}
+	return list, nil
+}
+
+func (r *memoryLifecycleRepo) ClaimDue(_ context.Context, now, staleBefore time.Time, limit int) ([]*workspacelifecycle.Lifecycle, error) {
+	var claimed []*workspacelifecycle.Lifecycle
+	for _, l := range r.lifecycles {
+		if len(claimed) < limit && (l.Due(now) || (l.State == workspacelifecycle.StatePurging && l.UpdatedAt.Before(staleBefore))) {
+			l.State = workspacelifecycle.StatePurging
+			l.UpdatedAt = now
+			copied := *l
+			claimed = append(claimed, &copied)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memoryLifecycleRepo) SaveBundle(_ context.Context, bundle *workspacelifecycle.Bundle) error {
+	for i, b := range r.bundles {
+		if b.ID == bundle.ID {
+			copied := *bundle
+			r.bundles[i] = &copied
+			return nil
+		}
+	}
+	copied := *bundle
+	r.bundles = append(r.bundles, &copied)
+	return nil
+}
+
+func (r *memoryLifecycleRepo) FindBundle(_ context.Context, id string) (*workspacelifecycle.Bundle, error) {
+	for _, b := range r.bundles {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryLifecycleRepo) ListBundles(_ context.Context, spaceID string, limit int) ([]*workspacelifecycle.Bundle, error) {
+	var list []*workspacelifecycle.Bundle
+	for i := len(r.bundles) - 1; i >= 0 && len(list) < limit; i-- {
+		if r.bundles[i].SpaceID == spaceID {
+			list = append(list, r.bundles[i])
+		}
+	}
+	return list, nil
+}
+
+// memoryWorkspaceStore 内存中的工作空间数据
+type memoryWorkspaceStore struct {
+	inv      *workspacelifecycle.Inventory
+	metadata map[string][]map[string]interface{}
+	rows     map[string][]map[string]interface{} // 表ID → 行
+	files    []workspacelifecycle.AttachmentFile
+	dropped  bool
+	purged   bool
+}
+
+func (s *memoryWorkspaceStore) Inventory(_ context.Context, spaceID string) (*workspacelifecycle.Inventory, error) {
+	if s.purged || s.inv.SpaceID != spaceID {
+		return nil, nil
+	}
+	return s.inv, nil
+}
+
+func (s *memoryWorkspaceStore) MetadataTables(context.Context) ([]string, error) {
+	var tables []string
+	for table := range s.metadata {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+func (s *memoryWorkspaceStore) ScanMetadata(_ context.Context, table string, _ *workspacelifecycle.Inventory, _ int, fn func(rows []map[string]interface{}) error) error {
+	if rows := s.metadata[table]; len(rows) > 0 {
+		return fn(rows)
+	}
+	return nil
+}
+
+func (s *memoryWorkspaceStore) ScanRows(_ context.Context, ref workspacelifecycle.TableRef, batchSize int, fn func(rows []map[string]interface{}) error) error {
+	rows := s.rows[ref.TableID]
+	for start := 0; start < len(rows); start += batchSize {
+		end := start + batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := fn(rows[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memoryWorkspaceStore) Attachments(context.Context, *workspacelifecycle.Inventory) ([]workspacelifecycle.AttachmentFile, error) {
+	return s.files, nil
+}
+
+func (s *memoryWorkspaceStore) DropDataTables(context.Context, *workspacelifecycle.Inventory) error {
+	s.dropped = true
+	return nil
+}
+
+func (s *memoryWorkspaceStore) PurgeMetadata(context.Context, *workspacelifecycle.Inventory) (map[string]int64, int, error) {
+	s.purged = true
+	deleted := make(map[string]int64)
+	for table, rows := range s.metadata {
+		deleted[table] = int64(len(rows))
+	}
+	return deleted, 1, nil
+}
+
+// recordingCache 记录失效的缓存
+type recordingCache struct {
+	tables   []string
+	patterns []string
+}
+
+func (c *recordingCache) InvalidateTableCache(_ context.Context, tableID string) error {
+	c.tables = append(c.tables, tableID)
+	return nil
+}
+
+func (c *recordingCache) InvalidatePattern(_ context.Context, pattern string) error {
+	c.patterns = append(c.patterns, pattern)
+	return nil
+}
+
+func (s *uploadStorage) Delete(_ context.Context, path string) error {
+	delete(s.files, path)
+	return nil
+}
+
+func newTestWorkspaceLifecycleService(now time.Time) (*WorkspaceLifecycleService, *memoryLifecycleRepo, *memoryWorkspaceStore, *uploadStorage, *recordingCache) {
+	repo := &memoryLifecycleRepo{lifecycles: map[string]*workspacelifecycle.Lifecycle{}}
+	store := &memoryWorkspaceStore{
+		inv: &workspacelifecycle.Inventory{
+			SpaceID: "spc1",
+			BaseIDs: []string{"bse1"},
+			Tables: []workspacelifecycle.TableRef{
+				{BaseID: "bse1", TableID: "tbl1", Name: "客户"},
+				{BaseID: "bse1", TableID: "tbl2", Name: "旧表", Deleted: true},
+			},
+		},
+		metadata: map[string][]map[string]interface{}{
+			"base":       {{"id": "bse1", "space_id": "spc1"}},
+			"field":      {{"id": "fld1", "table_id": "tbl1"}},
+			"view_empty": nil,
+		},
+		rows: map[string][]map[string]interface{}{
+			"tbl1": {{"__id": "rec1"}, {"__id": "rec2"}, {"__id": "rec3"}},
+		},
+		files: []workspacelifecycle.AttachmentFile{
+			{ID: "att1", Path: "2024/a.png", Thumbnails: []string{"2024/a_s.png"}},
+			{ID: "att2", Path: "blobs/ab", ContentHash: "ab"},
+			{ID: "att3", Path: "2024/missing.png"},
+		},
+	}
+	storage := &uploadStorage{memoryStorage{files: map[string]string{"2024/a.png": "img", "2024/a_s.png": "thumb", "blobs/ab": "shared"}}}
+	cache := &recordingCache{}
+	svc := NewWorkspaceLifecycleService(repo, store, storage, cache, config.WorkspaceLifecycleConfig{
+		BatchSize:          2,
+		DefaultGracePeriod: 7 * 24 * time.Hour,
+		MinGracePeriod:     24 * time.Hour,
+	})
+	svc.now = func() time.Time { return now }
+	return svc, repo, store, storage, cache
+}
+
+func TestWorkspaceLifecycleReadOnly(t *testing.T) {
+	now := time.Now()
+	svc, _, _, _, _ := newTestWorkspaceLifecycleService(now)
+	ctx := context.Background()
+
+	if _, err := svc.Suspend(ctx, "adm1", "spc_missing", SuspendWorkspaceRequest{}); !pkgerrors.Is(err, pkgerrors.ErrSpaceNotFound) {
+		t.Errorf("不存在的空间 err = %v", err)
+	}
+	if err := svc.CheckWritable(ctx, "spc1"); err != nil {
+		t.Fatalf("正常状态应允许写入: %v", err)
+	}
+
+	l, err := svc.Suspend(ctx, "adm1", "spc1", SuspendWorkspaceRequest{Reason: "欠费"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.State != workspacelifecycle.StateSuspended || l.UpdatedBy != "adm1" {
+		t.Errorf("停用后 = %+v", l)
+	}
+	if err := svc.CheckWritable(ctx, "spc1"); !pkgerrors.Is(err, pkgerrors.ErrSpaceReadOnly) {
+		t.Errorf("停用后应只读: %v", err)
+	}
+	if _, err := svc.Suspend(ctx, "adm1", "spc1", SuspendWorkspaceRequest{}); !pkgerrors.Is(err, pkgerrors.ErrConflict) {
+		t.Errorf("重复停用 err = %v", err)
+	}
+
+	if _, err := svc.Resume(ctx, "adm1", "spc1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CheckWritable(ctx, "spc1"); err != nil {
+		t.Errorf("恢复后应允许写入: %v", err)
+	}
+}
+
+func TestWorkspaceLifecycleRejectsWritesWhenStateUnknown(t *testing.T) {
+	now := time.Now()
+	svc, repo, _, _, _ := newTestWorkspaceLifecycleService(now)
+	ctx := context.Background()
+
+	repo.findErr = errors.New("connection refused")
+	if err := svc.CheckWritable(ctx, "spc1"); err == nil {
+		t.Fatal("状态查询失败时应拒绝写入")
+	}
+
+	repo.findErr = nil
+	if err := svc.CheckWritable(ctx, "spc1"); err != nil {
+		t.Errorf("状态查询恢复后应允许写入: %v", err)
+	}
+}
+
+func TestWorkspaceLifecycleScheduleDeletion(t *testing.T) {
+	now := time.Now()
+	svc, _, _, _, _ := newTestWorkspaceLifecycleService(now)
+	ctx := context.Background()
+
+	if _, err := svc.ScheduleDeletion(ctx, "adm1", "spc1", ScheduleWorkspaceDeletionRequest{GraceDays: -1}); !pkgerrors.Is(err, pkgerrors.ErrValidationFailed) {
+		t.Errorf("负数宽限期 err = %v", err)
+	}
+	l, err := svc.ScheduleDeletion(ctx, "adm1", "spc1", ScheduleWorkspaceDeletionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.State != workspacelifecycle.StatePendingDeletion || !l.PurgeAfter.Equal(now.Add(7*24*time.Hour)) {
+		t.Errorf("默认宽限期 = %+v", l)
+	}
+	if err := svc.CheckWritable(ctx, "spc1"); !pkgerrors.Is(err, pkgerrors.ErrSpaceReadOnly) {
+		t.Errorf("计划删除后应只读: %v", err)
+	}
+
+	l, err = svc.CancelDeletion(ctx, "adm1", "spc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.State != workspacelifecycle.StateSuspended || l.PurgeAfter != nil {
+		t.Errorf("撤销后 = %+v", l)
+	}
+	if l, err = svc.ScheduleDeletion(ctx, "adm1", "spc1", ScheduleWorkspaceDeletionRequest{GraceDays: 3}); err != nil || !l.PurgeAfter.Equal(now.Add(72*time.Hour)) {
+		t.Errorf("指定宽限期 = %+v, err = %v", l, err)
+	}
+}
+
+func TestWorkspaceLifecycleBundle(t *testing.T) {
+	now := time.Now()
+	svc, repo, _, storage, _ := newTestWorkspaceLifecycleService(now)
+	ctx := context.Background()
+
+	bundle := workspacelifecycle.NewBundle("spc1", "adm1", now)
+	if err := svc.runBundle(ctx, bundle, nil); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := repo.FindBundle(ctx, bundle.ID)
+	if saved.Status != workspacelifecycle.BundleCompleted {
+		t.Fatalf("导出包 = %+v", saved)
+	}
+	manifest := saved.Manifest
+	if len(manifest.Metadata) != 2 || manifest.Metadata[0].Name != "base" {
+		t.Errorf("元数据（空表不导出）= %+v", manifest.Metadata)
+	}
+	if len(manifest.Tables) != 1 || manifest.Tables[0].Rows != 3 {
+		t.Errorf("数据表（已删除的表不导出）= %+v", manifest.Tables)
+	}
+	if manifest.Attachments.Files != 3 || manifest.Attachments.Missing != 1 {
+		t.Errorf("附件 = %+v", manifest.Attachments)
+	}
+	if lines := strings.Split(strings.TrimSpace(storage.files[bundle.DataPath("bse1", "tbl1")]), "\n"); len(lines) != 3 {
+		t.Errorf("数据文件 = %v", lines)
+	}
+	if storage.files[bundle.AttachmentPath("2024/a.png")] != "img" || storage.files[bundle.AttachmentPath("blobs/ab")] != "shared" {
+		t.Error("附件文件未复制到导出包")
+	}
+	var written workspacelifecycle.Manifest
+	if err := json.Unmarshal([]byte(storage.files[bundle.ManifestPath()]), &written); err != nil || written.Format != workspacelifecycle.BundleFormat {
+		t.Errorf("清单文件 = %+v, err = %v", written, err)
+	}
+}
+
+func TestWorkspaceLifecyclePurge(t *testing.T) {
+	now := time.Now()
+	svc, repo, store, storage, cache := newTestWorkspaceLifecycleService(now)
+	ctx := context.Background()
+
+	// 计划删除之前生成的导出包不能作为最终导出
+	early := workspacelifecycle.NewBundle("spc1", "adm1", now.Add(-time.Hour))
+	early.Finish(&workspacelifecycle.Manifest{}, nil, now)
+	_ = repo.SaveBundle(ctx, early)
+
+	if _, err := svc.ScheduleDeletion(ctx, "adm1", "spc1", ScheduleWorkspaceDeletionRequest{GraceDays: 1}); err != nil {
+		t.Fatal(err)
+	}
+	svc.purgeDue(ctx)
+	if store.dropped {
+		t.Fatal("宽限期内不应清除")
+	}
+
+	later := now.Add(25 * time.Hour)
+	svc.now = func() time.Time { return later }
+	svc.purgeDue(ctx)
+
+	l := repo.lifecycles["spc1"]
+	if l.State != workspacelifecycle.StatePurged || l.Purge == nil {
+		t.Fatalf("清除后 = %+v", l)
+	}
+	if l.PurgeBundleID == early.ID || len(repo.bundles) != 2 {
+		t.Errorf("清除前应生成新的导出包: %s", l.PurgeBundleID)
+	}
+	if !store.dropped || !store.purged {
+		t.Error("物理表或元数据未清除")
+	}
+	if _, ok := storage.files["2024/a.png"]; ok {
+		t.Error("附件文件未删除")
+	}
+	if _, ok := storage.files["blobs/ab"]; !ok {
+		t.Error("去重共享文件应只释放引用")
+	}
+	report := l.Purge
+	if report.DynamicTables != 1 || report.AttachmentFiles != 3 || report.ReleasedBlobs != 1 || report.AttachmentErrors != 0 {
+		t.Errorf("清除结果 = %+v", report)
+	}
+	if len(cache.tables) != 2 || len(cache.patterns) != 4 {
+		t.Errorf("缓存失效 = %+v", cache)
+	}
+
+	// 已清除的空间仍可查询状态与导出包
+	if got, err := svc.Get(ctx, "spc1"); err != nil || got.State != workspacelifecycle.StatePurged {
+		t.Errorf("已清除的状态 = %+v, err = %v", got, err)
+	}
+	if _, err := svc.CreateBundle(ctx, "adm1", "spc1"); !pkgerrors.Is(err, pkgerrors.ErrConflict) {
+		t.Errorf("已清除的空间不应再导出: %v", err)
+	}
+}
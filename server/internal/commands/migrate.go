@@ -11,6 +11,7 @@ import (
 
 	"github.com/easyspace-ai/luckdb/server/internal/application"
 	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
 
@@ -46,7 +47,15 @@ func NewMigrateCmd(configPath *string) *cobra.Command {
   luckdb migrate version
   
   # 强制设置版本
-  luckdb migrate force 000010`,
+  luckdb migrate force 000010
+
+  # 工作空间隔离模式：对每个租户Schema执行迁移
+  luckdb migrate tenants
+
+  # 元数据表在线迁移：查看状态 / 执行扩展迁移 / 所有实例升级后执行收缩迁移
+  luckdb migrate online --status
+  luckdb migrate online
+  luckdb migrate online --contract`,
 	}
 
 	cmd.AddCommand(newMigrateUpCmd(configPath))
@@ -54,6 +63,8 @@ func NewMigrateCmd(configPath *string) *cobra.Command {
 	cmd.AddCommand(newMigrateVersionCmd(configPath))
 	cmd.AddCommand(newMigrateForceCmd(configPath))
 	cmd.AddCommand(newMigrateDropCmd(configPath))
+	cmd.AddCommand(newMigrateTenantsCmd(configPath))
+	cmd.AddCommand(newMigrateOnlineCmd(configPath))
 
 	return cmd
 }
@@ -143,6 +154,165 @@ func newMigrateDropCmd(configPath *string) *cobra.Command {
 	return cmd
 }
 
+// newMigrateTenantsCmd 创建tenants命令
+func newMigrateTenantsCmd(configPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tenants",
+		Short: "执行租户级迁移（工作空间隔离模式）",
+		Long:  "为每个工作空间创建独立Schema，并在其中执行所有待应用的租户迁移",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTenantMigrations()
+		},
+	}
+
+	return cmd
+}
+
+// runTenantMigrations 执行租户迁移
+func runTenantMigrations() error {
+	cfg, err := loadMigrateConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := initMigrateLogger(cfg); err != nil {
+		return err
+	}
+
+	migrateService := application.NewMigrateService(cfg, getMigrationsDir())
+	results, err := migrateService.RunTenantMigrations(context.Background())
+	if err != nil {
+		return fmt.Errorf("租户迁移失败: %w", err)
+	}
+
+	failed := 0
+	fmt.Println("📦 租户迁移结果:")
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Printf("   ❌ %s (%s): %s\n", r.SpaceID, r.Schema, r.Error)
+			continue
+		}
+		fmt.Printf("   ✅ %s (%s): 应用 %d 个迁移, 耗时 %s\n", r.SpaceID, r.Schema, len(r.Applied), r.Duration)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d 个租户迁移失败", failed)
+	}
+	return nil
+}
+
+// newMigrateOnlineCmd 创建online命令
+func newMigrateOnlineCmd(configPath *string) *cobra.Command {
+	var contract, statusOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "online",
+		Short: "执行元数据表在线迁移（扩展/收缩）",
+		Long: `执行元数据表的在线迁移，迁移期间不需要停机
+
+  - 扩展迁移：加表、加列、在线建索引与分批回填，新旧版本代码都能运行（服务启动时也会自动执行）
+  - 收缩迁移：删除旧版本代码依赖的结构，只能在所有实例升级后通过 --contract 执行`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if statusOnly {
+				return showOnlineMigrationStatus()
+			}
+			if contract {
+				return confirmAndRun(func() error {
+					return runOnlineMigrations(true)
+				}, "收缩迁移会删除旧版本依赖的结构，请确认所有实例都已升级")
+			}
+			return runOnlineMigrations(false)
+		},
+	}
+
+	cmd.Flags().BoolVar(&contract, "contract", false, "同时执行收缩迁移")
+	cmd.Flags().BoolVar(&statusOnly, "status", false, "只查看迁移状态")
+
+	return cmd
+}
+
+// runOnlineMigrations 执行在线迁移
+func runOnlineMigrations(contract bool) error {
+	cfg, err := loadMigrateConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := initMigrateLogger(cfg); err != nil {
+		return err
+	}
+
+	migrateService := application.NewMigrateService(cfg, getMigrationsDir())
+	result, err := migrateService.RunOnlineMigrations(context.Background(), contract)
+	if err != nil {
+		return fmt.Errorf("在线迁移失败: %w", err)
+	}
+
+	if result.Skipped {
+		fmt.Println("⏳ 其他实例正在执行在线迁移，请稍后查看状态")
+		return nil
+	}
+	fmt.Println("📦 在线迁移结果:")
+	for _, id := range result.Applied {
+		fmt.Printf("   ✅ %s\n", id)
+	}
+	if len(result.Applied) == 0 && result.Error == "" {
+		fmt.Println("   没有待执行的迁移")
+	}
+	fmt.Println()
+
+	if result.Error != "" {
+		return fmt.Errorf("在线迁移失败: %s", result.Error)
+	}
+	return nil
+}
+
+// showOnlineMigrationStatus 显示在线迁移状态
+func showOnlineMigrationStatus() error {
+	cfg, err := loadMigrateConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := initMigrateLogger(cfg); err != nil {
+		return err
+	}
+
+	migrateService := application.NewMigrateService(cfg, getMigrationsDir())
+	status, err := migrateService.GetOnlineMigrationStatus(context.Background())
+	if err != nil {
+		return fmt.Errorf("获取在线迁移状态失败: %w", err)
+	}
+
+	fmt.Printf("📊 在线迁移: 已应用 v%d / 最新 v%d\n", status.AppliedVersion, status.LatestVersion)
+	for _, m := range status.Migrations {
+		icon := "⏸️ "
+		switch m.Status {
+		case database.OnlineMigrationApplied:
+			icon = "✅"
+		case database.OnlineMigrationRunning:
+			icon = "⏳"
+		case database.OnlineMigrationFailed:
+			icon = "❌"
+		}
+		fmt.Printf("   %s %d_%s [%s] %s", icon, m.Version, m.Name, m.Phase, m.Status)
+		if m.Error != "" {
+			fmt.Printf(": %s", m.Error)
+		}
+		fmt.Println()
+	}
+	for _, idx := range status.InvalidIndexes {
+		fmt.Printf("   ⚠️  无效索引 %s (%s)，将在下次执行对应迁移时重建\n", idx.Name, idx.Table)
+	}
+	if status.PendingContract > 0 {
+		fmt.Printf("   💡 %d 个收缩迁移待执行（所有实例升级后执行 migrate online --contract）\n", status.PendingContract)
+	}
+	fmt.Println()
+	return nil
+}
+
 // runMigration 执行迁移
 func runMigration(mode, version string) error {
 	printBanner()
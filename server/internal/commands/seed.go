@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/container"
+	"github.com/easyspace-ai/luckdb/server/internal/testing/fixtures"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// newSeedCmd 创建演示数据导入命令 ✨
+func newSeedCmd() *cobra.Command {
+	var file, userID string
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "按数据描述文件导入演示数据",
+		Long:  "读取 YAML 数据描述，创建空间、Base、表结构与记录（含关联与附件），用于演示与手工测试",
+		Example: `  # 以指定用户身份导入演示数据
+  luckdb util seed --file fixtures/demo.yaml --user usr_xxx`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("读取数据描述失败: %w", err)
+			}
+			spec, err := fixtures.Parse(data)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("加载配置失败: %w", err)
+			}
+			if err := logger.Init(logger.LoggerConfig{Level: cfg.Logger.Level, Format: cfg.Logger.Format, OutputPath: cfg.Logger.OutputPath}); err != nil {
+				return fmt.Errorf("初始化日志失败: %w", err)
+			}
+			cont := container.NewContainer(cfg)
+			if err := cont.Initialize(); err != nil {
+				return fmt.Errorf("初始化容器失败: %w", err)
+			}
+			defer cont.Close()
+
+			fixture, err := fixtures.NewSeederFromContainer(cont).Load(context.Background(), userID, spec)
+			if err != nil {
+				return fmt.Errorf("导入失败: %w", err)
+			}
+
+			fmt.Printf("✅ 已创建空间: %s\n", fixture.SpaceID)
+			for _, base := range spec.Bases {
+				fmt.Printf("  Base %s: %s\n", base.Name, fixture.Base(base.Name).ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "数据描述文件（YAML）")
+	cmd.Flags().StringVarP(&userID, "user", "u", "", "创建数据的用户ID")
+	cmd.MarkFlagRequired("file")
+	cmd.MarkFlagRequired("user")
+
+	return cmd
+}
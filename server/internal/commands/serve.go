@@ -2,10 +2,12 @@ package commands
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -15,8 +17,11 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/config"
 	"github.com/easyspace-ai/luckdb/server/internal/container"
 	httpHandlers "github.com/easyspace-ai/luckdb/server/internal/interfaces/http"
+	"github.com/easyspace-ai/luckdb/server/internal/interfaces/middleware"
 	"github.com/easyspace-ai/luckdb/server/pkg/assets"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 )
 
 // NewServeCmd 创建API服务器命令
@@ -60,6 +65,13 @@ func runServe(version string) error {
 		Level:      cfg.Logger.Level,
 		Format:     cfg.Logger.Format,
 		OutputPath: cfg.Logger.OutputPath,
+		Modules:    cfg.Logger.Modules,
+		Sampling: logger.SamplingConfig{
+			Enabled:    cfg.Logger.Sampling.Enabled,
+			Initial:    cfg.Logger.Sampling.Initial,
+			Thereafter: cfg.Logger.Sampling.Thereafter,
+			Tick:       cfg.Logger.Sampling.Tick,
+		},
 	}
 	if err := logger.Init(loggerConfig); err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
@@ -80,6 +92,20 @@ func runServe(version string) error {
 		return err
 	}
 
+	// 初始化链路追踪（OTEL_* 环境变量，默认关闭）
+	shutdownTracing, err := tracing.InitFromEnv("luckdb")
+	if err != nil {
+		logger.Warn("链路追踪初始化失败，已禁用", logger.ErrorField(err))
+	} else {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				logger.Warn("链路追踪关闭失败", logger.ErrorField(err))
+			}
+		}()
+	}
+
 	logger.Info("Starting LuckDB API Server",
 		logger.String("version", version),
 		logger.String("mode", cfg.Server.Mode),
@@ -161,14 +187,33 @@ func setupRouter(cfg *config.Config, cont *container.Container, version string)
 	}
 
 	router := gin.New()
+	// 只采用受信任代理转发的客户端地址，否则任何人都可以通过 X-Forwarded-For 伪造来源IP
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("Invalid trusted proxies", logger.ErrorField(err))
+	}
 
 	// 基础中间件 - 使用自定义 panic 恢复中间件，记录详细错误
 	router.Use(customRecovery())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(httpHandlers.TracingMiddleware())
+	router.Use(httpHandlers.MetricsMiddleware())
+	router.Use(httpHandlers.QueryBudgetMiddleware(cfg.Database.QueryBudget))
 	router.Use(corsMiddleware())
 	router.Use(loggerMiddleware())
 
 	// 健康检查
 	router.GET("/health", healthCheckHandler(cont, version))
+
+	// 存活/就绪探针与详细依赖状态 ✨
+	healthHandler := httpHandlers.NewHealthHandler(cont.HealthService(), cont.AuthService(), cfg.Health.StatusToken, version)
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/status", healthHandler.Status)
+
+	// Prometheus 指标 ✨
+	if cfg.Metrics.Enabled {
+		router.GET(cfg.Metrics.Path, metricsHandler(cfg.Metrics.Token))
+	}
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"service": "LuckDB API",
@@ -184,6 +229,18 @@ func setupRouter(cfg *config.Config, cont *container.Container, version string)
 	return router
 }
 
+// metricsHandler 指标抓取端点（配置了令牌时校验 Bearer 令牌）
+func metricsHandler(token string) gin.HandlerFunc {
+	handler := metrics.Handler()
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
 // customRecovery 自定义 panic 恢复中间件，记录详细错误日志
 func customRecovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -245,11 +302,18 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		fmt.Printf("🔥 CORS Middleware called for path: %s\n", c.Request.URL.Path)
 
+		// 嵌入接口按工作空间设置严格控制来源，由 EmbedMiddleware 处理
+		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/embed/") {
+			c.Next()
+			return
+		}
+
 		// 开发环境：允许所有来源
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		// 注意：当使用 * 时，不能设置 Access-Control-Allow-Credentials: true
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, traceparent, Idempotency-Key, If-Match, If-None-Match, X-Time-Zone")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, Idempotent-Replayed, Retry-After, X-Schema-Version")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -269,6 +333,11 @@ func loggerMiddleware() gin.HandlerFunc {
 
 		c.Next()
 
+		// 探针与指标抓取请求频率高，成功时不记录
+		if (path == "/healthz" || path == "/readyz" || path == "/metrics") && c.Writer.Status() < http.StatusBadRequest {
+			return
+		}
+
 		duration := time.Since(start)
 
 		logger.Info("HTTP Request",
@@ -279,6 +348,8 @@ func loggerMiddleware() gin.HandlerFunc {
 			logger.String("ip", c.ClientIP()),
 			logger.String("user_agent", c.Request.UserAgent()),
 			logger.Duration("duration", duration),
+			logger.String("request_id", c.GetString("request_id")),
+			logger.String("trace_id", c.GetString("trace_id")),
 		)
 	}
 }
@@ -14,11 +14,12 @@ func NewUtilCmd(configPath *string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "util",
 		Short: "实用工具命令",
-		Long:  "各种实用工具命令，包括密码生成、配置调试等",
+		Long:  "各种实用工具命令，包括密码生成、配置调试、演示数据导入等",
 	}
 
 	cmd.AddCommand(newGeneratePasswordCmd())
 	cmd.AddCommand(newDebugConfigCmd(configPath))
+	cmd.AddCommand(newSeedCmd())
 
 	return cmd
 }
@@ -3,46 +3,53 @@ package config
 // AIConfig AI provider configuration
 type AIConfig struct {
 	// Default provider to use
-	DefaultProvider string `yaml:"default_provider" env:"AI_DEFAULT_PROVIDER" default:"openai"`
+	DefaultProvider string `mapstructure:"default_provider" yaml:"default_provider" env:"AI_DEFAULT_PROVIDER" default:"openai"`
 
 	// Provider configurations
-	Providers map[string]AIProviderConfig `yaml:"providers"`
+	Providers map[string]AIProviderConfig `mapstructure:"providers" yaml:"providers"`
 }
 
 // AIProviderConfig individual AI provider configuration
 type AIProviderConfig struct {
-	// Provider type (openai, deepseek, anthropic, etc.)
-	Type string `yaml:"type"`
+	// Provider type (openai, deepseek, azure, local)
+	Type string `mapstructure:"type" yaml:"type"`
 
 	// API key for authentication
-	APIKey string `yaml:"api_key" env:"AI_API_KEY"`
+	APIKey string `mapstructure:"api_key" yaml:"api_key" env:"AI_API_KEY"`
 
 	// Base URL for API requests (optional, uses default if not specified)
-	BaseURL string `yaml:"base_url" env:"AI_BASE_URL"`
+	// Azure: https://{resource}.openai.azure.com; local: OpenAI-compatible endpoint such as http://localhost:11434/v1
+	BaseURL string `mapstructure:"base_url" yaml:"base_url" env:"AI_BASE_URL"`
 
-	// Default model to use
-	DefaultModel string `yaml:"default_model"`
+	// API version (Azure OpenAI only)
+	APIVersion string `mapstructure:"api_version" yaml:"api_version"`
+
+	// Default model to use (Azure: deployment name)
+	DefaultModel string `mapstructure:"default_model" yaml:"default_model"`
+
+	// Embedding model used by semantic search (Azure: deployment name)
+	EmbeddingModel string `mapstructure:"embedding_model" yaml:"embedding_model"`
 
 	// Request timeout in seconds
-	Timeout int `yaml:"timeout" default:"30"`
+	Timeout int `mapstructure:"timeout" yaml:"timeout" default:"30"`
 
 	// Rate limiting
-	RateLimit AIRateLimitConfig `yaml:"rate_limit"`
+	RateLimit AIRateLimitConfig `mapstructure:"rate_limit" yaml:"rate_limit"`
 
 	// Provider-specific options
-	Options map[string]interface{} `yaml:"options"`
+	Options map[string]interface{} `mapstructure:"options" yaml:"options"`
 }
 
 // AIRateLimitConfig rate limiting configuration for AI providers
 type AIRateLimitConfig struct {
 	// Requests per minute
-	RequestsPerMinute int `yaml:"requests_per_minute" default:"60"`
+	RequestsPerMinute int `mapstructure:"requests_per_minute" yaml:"requests_per_minute" default:"60"`
 
 	// Tokens per minute
-	TokensPerMinute int `yaml:"tokens_per_minute" default:"90000"`
+	TokensPerMinute int `mapstructure:"tokens_per_minute" yaml:"tokens_per_minute" default:"90000"`
 
 	// Concurrent requests
-	ConcurrentRequests int `yaml:"concurrent_requests" default:"10"`
+	ConcurrentRequests int `mapstructure:"concurrent_requests" yaml:"concurrent_requests" default:"10"`
 }
 
 // DefaultAIConfig returns default AI configuration
@@ -10,17 +10,100 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	Server    ServerConfig    `mapstructure:"server"`
-	Database  DatabaseConfig  `mapstructure:"database"`
-	Redis     RedisConfig     `mapstructure:"redis"`
-	JWT       JWTConfig       `mapstructure:"jwt"`
-	Storage   StorageConfig   `mapstructure:"storage"`
-	Logger    LoggerConfig    `mapstructure:"logger"`
-	SQLLogger SQLLoggerConfig `mapstructure:"sql_logger"`
-	Queue     QueueConfig     `mapstructure:"queue"`
-	JSVM      JSVMConfig      `mapstructure:"jsvm"`
-	AI        AIConfig        `mapstructure:"ai"`
-	MCP       MCPConfig       `mapstructure:"mcp"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	Logger      LoggerConfig      `mapstructure:"logger"`
+	SQLLogger   SQLLoggerConfig   `mapstructure:"sql_logger"`
+	Queue       QueueConfig       `mapstructure:"queue"`
+	JSVM        JSVMConfig        `mapstructure:"jsvm"`
+	AI          AIConfig          `mapstructure:"ai"`
+	MCP         MCPConfig         `mapstructure:"mcp"`
+	Encryption  EncryptionConfig  `mapstructure:"encryption"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Reload      ReloadConfig      `mapstructure:"reload"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	// SchemaMigration 动态表结构变更编排
+	SchemaMigration SchemaMigrationConfig `mapstructure:"schema_migration"`
+	// Operations 长时操作（导入、转换、恢复）后台执行
+	Operations OperationConfig `mapstructure:"operations"`
+	// RecordSnapshot 表快照读取（基于记录变更日志）
+	RecordSnapshot RecordSnapshotConfig `mapstructure:"record_snapshot"`
+	// ChangeFeed 变更流（外部同步增量数据源）
+	ChangeFeed ChangeFeedConfig `mapstructure:"change_feed"`
+	// Replication 复制到外部数据仓库（逻辑复制发布 / Debezium 兼容推送）
+	Replication ReplicationConfig `mapstructure:"replication"`
+	// WarehouseSync 数据仓库同步任务（BigQuery / Snowflake）
+	WarehouseSync WarehouseSyncConfig `mapstructure:"warehouse_sync"`
+	// SyncedTables 同步表（将视图镜像到其他 Base 的只读表）
+	SyncedTables SyncedTableConfig `mapstructure:"synced_tables"`
+	// ExternalSync 外部数据同步（从外部数据库表或 REST 接口拉取到同步表）
+	ExternalSync ExternalSyncConfig `mapstructure:"external_sync"`
+	// Integrations 集成平台（Zapier / Make）触发器与 REST Hook 推送
+	Integrations IntegrationConfig `mapstructure:"integrations"`
+	// Embed 嵌入视图令牌
+	Embed EmbedConfig `mapstructure:"embed"`
+	// Usage 工作空间用量统计（按天汇总）
+	Usage UsageConfig `mapstructure:"usage"`
+	// AttachmentScan 附件上传后的类型校验与病毒扫描
+	AttachmentScan AttachmentScanConfig `mapstructure:"attachment_scan"`
+	// AIField AI 字段异步生成任务与默认配额
+	AIField AIFieldConfig `mapstructure:"ai_field"`
+	// SemanticSearch 记录语义搜索（向量索引与混合检索）
+	SemanticSearch SemanticSearchConfig `mapstructure:"semantic_search"`
+	// NLQuery 自然语言查询（由大模型生成过滤条件）
+	NLQuery NLQueryConfig `mapstructure:"nl_query"`
+	// TextExtraction 附件文本提取（PDF / 图片 OCR 写入长文本字段）
+	TextExtraction TextExtractionConfig `mapstructure:"text_extraction"`
+	// Recurrence 按计划从记录模板定时新建记录
+	Recurrence RecurrenceConfig `mapstructure:"recurrence"`
+	// RecordArchive 记录归档（按策略将历史记录移入冷存储）
+	RecordArchive RecordArchiveConfig `mapstructure:"record_archive"`
+	// DataExport Base 数据导出（Parquet 写入对象存储）
+	DataExport DataExportConfig `mapstructure:"data_export"`
+	// DisasterRecovery 跨区域灾备（快照、变更事件与附件异步复制到备区域）
+	DisasterRecovery DisasterRecoveryConfig `mapstructure:"disaster_recovery"`
+	// WorkspaceLifecycle 工作空间停用、计划删除与到期清除
+	WorkspaceLifecycle WorkspaceLifecycleConfig `mapstructure:"workspace_lifecycle"`
+	// Impersonation 支持人员代入用户（限时会话，全程审计）
+	Impersonation ImpersonationConfig `mapstructure:"impersonation"`
+	// AdminConsole 自托管实例的超级管理员控制台（独立登录与令牌）
+	AdminConsole AdminConsoleConfig `mapstructure:"admin_console"`
+	// Maintenance 维护模式（实例、工作空间或 Base 只读）
+	Maintenance MaintenanceConfig `mapstructure:"maintenance"`
+	// Billing 套餐限额（软/硬限额与用量计量）
+	Billing BillingConfig `mapstructure:"billing"`
+	// TableHealth 表健康检查（校验失败、失效关联、公式错误、类型转换遗留值）
+	TableHealth TableHealthConfig `mapstructure:"table_health"`
+	// FindReplace 批量查找替换
+	FindReplace FindReplaceConfig `mapstructure:"find_replace"`
+	// ImageProcessing 附件图片派生图（缩放、旋转、去除 EXIF）
+	ImageProcessing ImageProcessingConfig `mapstructure:"image_processing"`
+	// AttachmentDedup 附件按内容寻址存储与无引用内容回收
+	AttachmentDedup AttachmentDedupConfig `mapstructure:"attachment_dedup"`
+	// ResumableUpload 可续传分片上传（tus 协议）
+	ResumableUpload ResumableUploadConfig `mapstructure:"resumable_upload"`
+	// ViewProjection 视图展示投影（预先解析展示值的列表行）
+	ViewProjection ViewProjectionConfig `mapstructure:"view_projection"`
+	// ViewQueryCache 视图查询结果缓存（按依赖失效）
+	ViewQueryCache ViewQueryCacheConfig `mapstructure:"view_query_cache"`
+	// ViewWindow 虚拟滚动表格的视图窗口读取
+	ViewWindow ViewWindowConfig `mapstructure:"view_window"`
+	// AutomationScripts 自动化"运行脚本"动作（沙箱执行用户脚本）
+	AutomationScripts AutomationScriptConfig `mapstructure:"automation_scripts"`
+	// Permalinks 记录与视图的永久短链接与链接预览
+	Permalinks PermalinkConfig `mapstructure:"permalinks"`
+	// Printing 记录与视图的 PDF 打印（后台渲染后写入对象存储）
+	Printing PrintConfig `mapstructure:"printing"`
+	// Documents 文档模板合并（DOCX/HTML 模板生成文件写入附件字段）
+	Documents DocumentConfig `mapstructure:"documents"`
+	// OfflineSync 移动端离线同步（快照、增量变更与离线修改回放）
+	OfflineSync OfflineSyncConfig `mapstructure:"offline_sync"`
+	// Features 功能开关（支持热更新），key 为开关名
+	Features map[string]FeatureFlagConfig `mapstructure:"features"`
 }
 
 // ServerConfig 服务器配置
@@ -36,20 +119,122 @@ type ServerConfig struct {
 	EnableCORS          bool          `mapstructure:"enable_cors"`
 	EnableSwagger       bool          `mapstructure:"enable_swagger"`
 	PermissionsDisabled bool          `mapstructure:"permissions_disabled"` // 禁用权限检查（仅用于开发）
+	// TrustedProxies 信任的反向代理（IP 或 CIDR），只有来自这些地址的请求才采用 X-Forwarded-For / X-Real-IP；
+	// 为空时不信任任何代理，客户端IP取连接的对端地址（IP 白名单依赖该地址）
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Host            string        `mapstructure:"host"`
-	Port            int           `mapstructure:"port"`
-	User            string        `mapstructure:"user"`
-	Password        string        `mapstructure:"password"`
-	Name            string        `mapstructure:"name"`
-	SSLMode         string        `mapstructure:"ssl_mode"`
-	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
-	MaxOpenConns    int           `mapstructure:"max_open_conns"`
-	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
-	LogLevel        string        `mapstructure:"log_level"`
+	Host            string            `mapstructure:"host"`
+	Port            int               `mapstructure:"port"`
+	User            string            `mapstructure:"user"`
+	Password        string            `mapstructure:"password"`
+	Name            string            `mapstructure:"name"`
+	SSLMode         string            `mapstructure:"ssl_mode"`
+	MaxIdleConns    int               `mapstructure:"max_idle_conns"`
+	MaxOpenConns    int               `mapstructure:"max_open_conns"`
+	ConnMaxLifetime time.Duration     `mapstructure:"conn_max_lifetime"`
+	LogLevel        string            `mapstructure:"log_level"`
+	Tenancy         TenancyConfig     `mapstructure:"tenancy"`
+	Residency       ResidencyConfig   `mapstructure:"residency"`
+	SlowQuery       DBSlowQueryConfig `mapstructure:"slow_query"`
+	// QueryBudget 按请求类别的查询预算（语句超时、扫描行数与排序行数上限）
+	QueryBudget QueryBudgetConfig `mapstructure:"query_budget"`
+	// FaultInjection 故障注入（仅用于测试与预发环境）
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
+	// OnlineMigration 元数据表的在线迁移（扩展/收缩模式）
+	OnlineMigration OnlineMigrationConfig `mapstructure:"online_migration"`
+}
+
+// OnlineMigrationConfig 元数据表在线迁移配置
+type OnlineMigrationConfig struct {
+	AutoExpand        bool          `mapstructure:"auto_expand"`         // 服务启动时在后台执行扩展迁移（收缩迁移始终需要通过命令行执行）
+	LockTimeout       time.Duration `mapstructure:"lock_timeout"`        // DDL 等待表锁的上限，超时后退避重试，避免阻塞业务查询
+	MaxRetries        int           `mapstructure:"max_retries"`         // 等待锁超时后的最大尝试次数
+	BackfillBatchSize int           `mapstructure:"backfill_batch_size"` // 回填每批处理的行数
+	BackfillPause     time.Duration `mapstructure:"backfill_pause"`      // 回填批次之间的间隔
+}
+
+// DBSlowQueryConfig 慢查询记录与按表查询统计配置
+type DBSlowQueryConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Threshold  time.Duration `mapstructure:"threshold"`   // 超过该耗时的查询记为慢查询
+	Explain    bool          `mapstructure:"explain"`     // 对慢 SELECT 异步执行 EXPLAIN（不带 ANALYZE）获取执行计划
+	MaxEntries int           `mapstructure:"max_entries"` // 保留的最近慢查询条数
+	MaxTables  int           `mapstructure:"max_tables"`  // 统计的动态表数量上限
+}
+
+// QueryBudgetConfig 查询预算配置
+// 请求按路由归入类别（未列出的路由使用 Default），类别的预算随请求上下文传到数据库层
+type QueryBudgetConfig struct {
+	Enabled     bool                              `mapstructure:"enabled"`
+	EstimateTTL time.Duration                     `mapstructure:"estimate_ttl"` // 表行数估算的缓存时间（行数低于上限的表不检查执行计划）
+	Default     QueryBudgetClassConfig            `mapstructure:"default"`      // 未归类请求的预算
+	Classes     map[string]QueryBudgetClassConfig `mapstructure:"classes"`      // 类别名 -> 预算
+}
+
+// QueryBudgetClassConfig 一类请求的查询预算（各项为 0 时不限制）
+type QueryBudgetClassConfig struct {
+	Routes           []string      `mapstructure:"routes"`            // 归入该类别的路由（如 "GET /api/v1/views/:viewId/window"）
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"` // 单条查询的最长执行时间
+	MaxRowsScanned   int64         `mapstructure:"max_rows_scanned"`  // 单条查询按执行计划估算的最大扫描行数
+	MaxSortRows      int64         `mapstructure:"max_sort_rows"`     // 无法使用索引排序时最多排序的行数
+}
+
+// TenancyConfig 多租户隔离配置
+type TenancyConfig struct {
+	Mode         string `mapstructure:"mode"`          // base: 每个Base独立Schema（默认）, workspace: 每个Space独立Schema
+	SchemaPrefix string `mapstructure:"schema_prefix"` // workspace 模式下租户Schema前缀
+}
+
+// IsWorkspaceIsolation 是否启用按工作空间隔离
+func (t TenancyConfig) IsWorkspaceIsolation() bool {
+	return t.Mode == "workspace"
+}
+
+// ResidencyConfig 数据驻留配置
+// 启用后每个工作空间的数据表存放在其绑定区域的数据库集群中，元数据仍保存在主库
+type ResidencyConfig struct {
+	Enabled       bool                      `mapstructure:"enabled"`
+	DefaultRegion string                    `mapstructure:"default_region"` // 主库所在区域
+	Regions       map[string]DatabaseConfig `mapstructure:"regions"`        // 其他区域的数据库连接
+}
+
+// RegionConfig 获取区域数据库配置（未设置的连接参数沿用主库配置）
+func (r ResidencyConfig) RegionConfig(region string, primary DatabaseConfig) (DatabaseConfig, bool) {
+	regional, ok := r.Regions[region]
+	if !ok {
+		return DatabaseConfig{}, false
+	}
+
+	cfg := primary
+	cfg.Residency = ResidencyConfig{}
+	if regional.Host != "" {
+		cfg.Host = regional.Host
+	}
+	if regional.Port != 0 {
+		cfg.Port = regional.Port
+	}
+	if regional.User != "" {
+		cfg.User = regional.User
+	}
+	if regional.Password != "" {
+		cfg.Password = regional.Password
+	}
+	if regional.Name != "" {
+		cfg.Name = regional.Name
+	}
+	if regional.SSLMode != "" {
+		cfg.SSLMode = regional.SSLMode
+	}
+	if regional.MaxIdleConns != 0 {
+		cfg.MaxIdleConns = regional.MaxIdleConns
+	}
+	if regional.MaxOpenConns != 0 {
+		cfg.MaxOpenConns = regional.MaxOpenConns
+	}
+	return cfg, true
 }
 
 // RedisConfig Redis配置
@@ -60,6 +245,42 @@ type RedisConfig struct {
 	DB          int           `mapstructure:"db"`
 	PoolSize    int           `mapstructure:"pool_size"`
 	DialTimeout time.Duration `mapstructure:"dial_timeout"`
+	// FaultInjection 缓存故障注入（仅用于测试与预发环境）
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
+	// CircuitBreaker 仓储缓存熔断（Redis 不可用时跳过缓存）
+	CircuitBreaker CacheCircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	// Codec 缓存条目的编码与压缩
+	Codec CacheCodecConfig `mapstructure:"codec"`
+}
+
+// CacheCodecConfig 缓存编码配置 ✨
+// 条目带版本信封，切换编码或压缩方式后旧条目仍可读取；结构版本不一致的条目按未命中处理
+type CacheCodecConfig struct {
+	Format               string `mapstructure:"format"`                // json / msgpack / protobuf（仅 proto.Message，其余值回退为 JSON）
+	Compression          string `mapstructure:"compression"`           // none / snappy / zstd
+	CompressionThreshold int    `mapstructure:"compression_threshold"` // 编码后达到该字节数才压缩
+	SchemaVersion        string `mapstructure:"schema_version"`        // 附加在内置结构版本后（如发布版本号），变化后旧条目全部失效
+}
+
+// CacheCircuitBreakerConfig 缓存熔断配置 ✨
+// 连续失败达到阈值后熔断，冷却期内直接跳过缓存；冷却结束后放行一次探测，成功则恢复
+type CacheCircuitBreakerConfig struct {
+	Enabled                 bool          `mapstructure:"enabled"`
+	FailureThreshold        int           `mapstructure:"failure_threshold"`         // 连续失败多少次后熔断
+	CoolDown                time.Duration `mapstructure:"cool_down"`                 // 熔断后跳过缓存的时长
+	MaxPendingInvalidations int           `mapstructure:"max_pending_invalidations"` // 熔断期间暂存、恢复后补发的缓存删除数量上限
+}
+
+// FaultInjectionConfig 故障注入配置 ✨
+// 在缓存或数据库操作前附加延迟、按概率返回错误，用于验证 Redis / 数据库异常时系统能否降级运行
+type FaultInjectionConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Latency    time.Duration `mapstructure:"latency"`    // 每次操作附加的固定延迟
+	Jitter     time.Duration `mapstructure:"jitter"`     // 在固定延迟上随机增加 [0, jitter)
+	ErrorRate  float64       `mapstructure:"error_rate"` // 操作失败概率（0-1）
+	Operations []string      `mapstructure:"operations"` // 只对这些操作注入（缓存: get/set/delete/invalidate；数据库: query/create/update/delete/row/raw），为空时全部注入
+	Targets    []string      `mapstructure:"targets"`    // 只对匹配的缓存键 / 表名注入（支持 * 通配），为空时全部注入
+	Seed       int64         `mapstructure:"seed"`       // 随机种子，非 0 时注入序列可复现
 }
 
 // JWTConfig JWT配置
@@ -71,6 +292,456 @@ type JWTConfig struct {
 	EnableRefresh   bool          `mapstructure:"enable_refresh"`
 }
 
+// EncryptionConfig 字段静态加密配置（信封加密）
+type EncryptionConfig struct {
+	Provider  string `mapstructure:"provider"`   // local: 使用本地主密钥派生各工作空间的KEK
+	MasterKey string `mapstructure:"master_key"` // base64 编码的 32 字节主密钥
+	KeyID     string `mapstructure:"key_id"`     // 主密钥标识（记录在数据密钥上，便于轮换）
+}
+
+// IsEnabled 是否已配置字段加密
+func (e EncryptionConfig) IsEnabled() bool {
+	return e.MasterKey != ""
+}
+
+// HealthConfig 健康检查与就绪探针配置
+type HealthConfig struct {
+	CheckTimeout    time.Duration `mapstructure:"check_timeout"`     // 单项依赖探测超时
+	RequireCache    bool          `mapstructure:"require_cache"`     // 缓存不可用时是否判定为未就绪
+	QueueDepthWarn  int64         `mapstructure:"queue_depth_warn"`  // 积压任务数超过该值时标记为 degraded
+	QueueDepthLimit int64         `mapstructure:"queue_depth_limit"` // 积压任务数超过该值时判定为未就绪（0 表示不限制）
+	StatusToken     string        `mapstructure:"status_token"`      // 负载均衡/编排器访问 /status 使用的令牌（X-Status-Token）
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Token   string `mapstructure:"token"` // 非空时抓取需携带 Authorization: Bearer <token>
+}
+
+// ReloadConfig 配置热更新
+// 仅日志级别与功能开关在运行时生效，其余配置变更会记录为“需要重启”
+type ReloadConfig struct {
+	Watch        bool          `mapstructure:"watch"`         // 监听配置文件变更（默认关闭）
+	RemoteURL    string        `mapstructure:"remote_url"`    // 远程配置地址（YAML/JSON，覆盖在文件配置之上）
+	RemoteToken  string        `mapstructure:"remote_token"`  // 访问远程配置的 Bearer 令牌
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 远程配置轮询间隔
+}
+
+// IdempotencyConfig 幂等键配置
+// 客户端通过 Idempotency-Key 请求头重试写请求时，重放首次请求的响应而不是重复执行
+type IdempotencyConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	TTL         time.Duration `mapstructure:"ttl"`           // 响应保留时长，超过后同一个键视为新请求
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`  // 处理中状态的保留时长，处理期间每 1/3 时长续期一次（进程崩溃时自动释放）
+	MaxBodySize int           `mapstructure:"max_body_size"` // 可缓存的最大响应体字节数，超过时只记录已完成，重试返回 409 而不重放
+}
+
+// SchemaMigrationConfig 动态表结构变更编排配置
+// 请求内只执行轻量 DDL，回填与约束添加由后台任务分批执行
+type SchemaMigrationConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"` // 后台任务轮询间隔
+	BatchSize    int           `mapstructure:"batch_size"`    // 回填每批更新的行数
+	MaxAttempts  int           `mapstructure:"max_attempts"`  // 最大重试次数，超过后任务失败（字段设置不变，可手动重新执行）
+	StaleTimeout time.Duration `mapstructure:"stale_timeout"` // 执行中任务超过该时长无进度时视为实例崩溃，由其他实例接管
+}
+
+// OperationConfig 长时操作后台执行配置
+type OperationConfig struct {
+	PollInterval     time.Duration `mapstructure:"poll_interval"`      // 后台任务轮询间隔
+	ProgressInterval time.Duration `mapstructure:"progress_interval"`  // 进度持久化与推送的最小间隔（同时作为心跳）
+	StaleTimeout     time.Duration `mapstructure:"stale_timeout"`      // 执行中操作超过该时长无心跳时视为实例中断，标记为失败
+	MaxImportRecords int           `mapstructure:"max_import_records"` // 单次导入的最大记录数
+}
+
+// RecordSnapshotConfig 表快照读取配置
+type RecordSnapshotConfig struct {
+	Retention            time.Duration `mapstructure:"retention"`              // 变更日志保留时长，即可读取的最早时间点
+	MaxChanges           int           `mapstructure:"max_changes"`            // 单次快照读取可回放的最大变更记录数，超过时拒绝读取
+	CleanupInterval      time.Duration `mapstructure:"cleanup_interval"`       // 过期变更日志清理间隔
+	CellHistoryRetention time.Duration `mapstructure:"cell_history_retention"` // 单元格修改历史保留时长（通常长于变更日志，为 0 时不清理）
+	MaxRestoreRecords    int           `mapstructure:"max_restore_records"`    // 按时间点恢复一列时单次可恢复的最大记录数
+}
+
+// ChangeFeedConfig 变更流配置
+type ChangeFeedConfig struct {
+	RelayInterval   time.Duration `mapstructure:"relay_interval"`   // 发件箱编号间隔（决定变更对读取方可见的延迟）
+	RelayBatchSize  int           `mapstructure:"relay_batch_size"` // 每次编号的最大变更数
+	Retention       time.Duration `mapstructure:"retention"`        // 变更保留时长，超过后游标失效需重新全量同步
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // 过期变更清理间隔
+}
+
+// ReplicationConfig 复制目标投递配置
+type ReplicationConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // Webhook 目标的投递轮询间隔
+	BatchSize      int           `mapstructure:"batch_size"`      // 每次推送读取的最大变更数
+	RequestTimeout time.Duration `mapstructure:"request_timeout"` // 单次推送的请求超时
+	LeaseDuration  time.Duration `mapstructure:"lease_duration"`  // 领取目标的租约时长（应大于请求超时）
+	AllowPrivate   bool          `mapstructure:"allow_private"`   // 允许推送到内网与回环地址（默认禁止）
+}
+
+// WarehouseSyncConfig 数据仓库同步任务配置
+type WarehouseSyncConfig struct {
+	PollInterval       time.Duration `mapstructure:"poll_interval"`        // 检查到期任务的间隔
+	ChangeFeedInterval time.Duration `mapstructure:"change_feed_interval"` // 变更流模式两次增量同步的间隔
+	MinInterval        time.Duration `mapstructure:"min_interval"`         // 定时模式允许的最小全量间隔
+	BatchSize          int           `mapstructure:"batch_size"`           // 每次写入仓库的最大行数
+	RequestTimeout     time.Duration `mapstructure:"request_timeout"`      // 单次仓库请求超时
+	LeaseDuration      time.Duration `mapstructure:"lease_duration"`       // 领取任务的租约时长（应大于单次运行耗时）
+	RunHistory         int           `mapstructure:"run_history"`          // 每个任务保留的运行记录数
+	AllowPrivate       bool          `mapstructure:"allow_private"`        // 允许连接内网与回环地址的仓库（默认禁止）
+}
+
+// SyncedTableConfig 同步表配置
+type SyncedTableConfig struct {
+	PollInterval    time.Duration `mapstructure:"poll_interval"`    // 检查到期同步的间隔
+	DefaultInterval time.Duration `mapstructure:"default_interval"` // 未指定时两次增量同步的间隔
+	MinInterval     time.Duration `mapstructure:"min_interval"`     // 允许的最小同步间隔
+	BatchSize       int           `mapstructure:"batch_size"`       // 每批读取与写入的最大记录数
+	LeaseDuration   time.Duration `mapstructure:"lease_duration"`   // 领取同步的租约时长（应大于单次运行耗时）
+	// AllowCrossSpace 是否允许镜像到其他空间的 Base（目标 Base 仍需要管理权限）
+	AllowCrossSpace bool `mapstructure:"allow_cross_space"`
+}
+
+// ExternalSyncConfig 外部数据同步配置
+type ExternalSyncConfig struct {
+	PollInterval     time.Duration `mapstructure:"poll_interval"`      // 检查到期连接器的间隔
+	DefaultInterval  time.Duration `mapstructure:"default_interval"`   // 未指定时两次同步的间隔
+	MinInterval      time.Duration `mapstructure:"min_interval"`       // 允许的最小同步间隔
+	FullSyncInterval time.Duration `mapstructure:"full_sync_interval"` // 增量连接器定期全量同步以发现删除的间隔（0 表示不定期全量）
+	BatchSize        int           `mapstructure:"batch_size"`         // 每次读取与写入的最大行数
+	RequestTimeout   time.Duration `mapstructure:"request_timeout"`    // 单次读取（查询或 REST 请求）超时
+	LeaseDuration    time.Duration `mapstructure:"lease_duration"`     // 领取连接器的租约时长（应大于单次运行耗时）
+	RunHistory       int           `mapstructure:"run_history"`        // 每个连接器保留的运行记录数
+	AllowPrivate     bool          `mapstructure:"allow_private"`      // 允许连接内网与回环地址的数据源（默认禁止）
+}
+
+// IntegrationConfig 集成平台 REST Hook 推送配置
+type IntegrationConfig struct {
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // 订阅推送的轮询间隔
+	BatchSize      int           `mapstructure:"batch_size"`      // 每次推送的最大记录数
+	RequestTimeout time.Duration `mapstructure:"request_timeout"` // 单次推送的请求超时
+	LeaseDuration  time.Duration `mapstructure:"lease_duration"`  // 领取订阅的租约时长（应大于请求超时）
+	MaxFailures    int           `mapstructure:"max_failures"`    // 新订阅默认的暂停阈值（连续失败次数），0 表示不暂停
+	AllowPrivate   bool          `mapstructure:"allow_private"`   // 允许推送到内网与回环地址（默认禁止）
+}
+
+// EmbedConfig 嵌入视图配置
+type EmbedConfig struct {
+	DefaultTokenTTL time.Duration `mapstructure:"default_token_ttl"` // 未指定有效期时嵌入令牌的有效期
+	MaxPageSize     int           `mapstructure:"max_page_size"`     // 嵌入接口单页最大记录数
+}
+
+// UsageConfig 用量统计配置
+type UsageConfig struct {
+	RollupInterval time.Duration `mapstructure:"rollup_interval"` // 重新汇总当天与前一天用量的间隔
+	FlushInterval  time.Duration `mapstructure:"flush_interval"`  // API 调用计数从内存写入汇总表的间隔
+	Retention      time.Duration `mapstructure:"retention"`       // 汇总数据保留时长
+}
+
+// AttachmentScanConfig 附件扫描配置
+// Engine 为 none 时只做类型嗅探与空间策略校验；要求扫描的空间在未配置引擎时附件保持待扫描状态
+type AttachmentScanConfig struct {
+	Engine        string        `mapstructure:"engine"`         // none / clamav / icap
+	ClamAVAddress string        `mapstructure:"clamav_address"` // clamd 地址，如 tcp://127.0.0.1:3310 或 unix:///var/run/clamd.sock
+	ICAPURL       string        `mapstructure:"icap_url"`       // ICAP 服务地址，如 icap://127.0.0.1:1344/avscan
+	Timeout       time.Duration `mapstructure:"timeout"`        // 单个文件扫描超时
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 领取待处理附件的间隔
+	BatchSize     int           `mapstructure:"batch_size"`     // 每次领取的附件数
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 领取后的租约时长（超时后可被其他实例重新领取）
+	AllowPrivate  bool          `mapstructure:"allow_private"`  // 允许病毒事件推送到内网与回环地址（默认禁止）
+}
+
+// AIFieldConfig AI 字段生成配置
+// 服务商在 ai.providers 中配置；空间未单独设置配额时使用此处的默认配额（0 表示不限制）
+type AIFieldConfig struct {
+	PollInterval           time.Duration `mapstructure:"poll_interval"`            // 领取待执行任务的间隔
+	BatchSize              int           `mapstructure:"batch_size"`               // 每次领取的任务数
+	Concurrency            int           `mapstructure:"concurrency"`              // 同时调用服务商的任务数
+	LeaseDuration          time.Duration `mapstructure:"lease_duration"`           // 领取后的租约时长（超时后可被其他实例重新领取）
+	RequestTimeout         time.Duration `mapstructure:"request_timeout"`          // 单次生成请求超时
+	MaxAttempts            int           `mapstructure:"max_attempts"`             // 服务商出错时的最大尝试次数
+	MaxOutputTokens        int           `mapstructure:"max_output_tokens"`        // 单次生成的输出令牌上限
+	MaxRegenerateRecords   int           `mapstructure:"max_regenerate_records"`   // 手动重新生成整列时的记录数上限
+	JobRetention           time.Duration `mapstructure:"job_retention"`            // 已结束任务的保留时长（需覆盖整月用量统计）
+	DefaultMonthlyRequests int64         `mapstructure:"default_monthly_requests"` // 空间每月默认可生成的单元格数
+	DefaultMonthlyTokens   int64         `mapstructure:"default_monthly_tokens"`   // 空间每月默认可消耗的令牌数
+}
+
+// SemanticSearchConfig 语义搜索配置
+// 向量由 ai.providers 中支持 Embeddings 接口的服务商生成，存储于 pgvector 列
+type SemanticSearchConfig struct {
+	Provider       string        `mapstructure:"provider"`        // 默认服务商，为空时使用 ai.default_provider
+	PollInterval   time.Duration `mapstructure:"poll_interval"`   // 处理回填与记录变更的间隔
+	BatchSize      int           `mapstructure:"batch_size"`      // 每次向量化的记录数
+	LeaseDuration  time.Duration `mapstructure:"lease_duration"`  // 领取索引后的租约时长
+	RequestTimeout time.Duration `mapstructure:"request_timeout"` // 单次向量化请求超时
+	CandidateLimit int           `mapstructure:"candidate_limit"` // 混合检索时每一路的候选数
+	MaxResults     int           `mapstructure:"max_results"`     // 单次搜索返回的最大记录数
+}
+
+// NLQueryConfig 自然语言查询配置
+type NLQueryConfig struct {
+	Provider       string        `mapstructure:"provider"`        // ai.providers 中的服务商，为空时使用 ai.default_provider
+	Model          string        `mapstructure:"model"`           // 为空时使用服务商的默认模型
+	RequestTimeout time.Duration `mapstructure:"request_timeout"` // 单次生成请求超时
+	MaxTokens      int           `mapstructure:"max_tokens"`      // 生成条件的输出令牌上限
+	MaxResults     int           `mapstructure:"max_results"`     // 单次查询返回的最大记录数
+}
+
+// TextExtractionConfig 附件文本提取配置
+// Engine 为 none 时不能创建提取规则；tika 可处理 PDF、图片与 Office 文档（需启用 Tesseract OCR），
+// tesseract 识别图片，配置 pdftotext_path 后也能提取 PDF 的文本层
+type TextExtractionConfig struct {
+	Engine        string        `mapstructure:"engine"`           // none / tika / tesseract
+	TikaURL       string        `mapstructure:"tika_url"`         // Tika Server 地址，如 http://127.0.0.1:9998
+	TesseractPath string        `mapstructure:"tesseract_path"`   // tesseract 可执行文件
+	PdfToTextPath string        `mapstructure:"pdftotext_path"`   // pdftotext 可执行文件（可选）
+	Languages     []string      `mapstructure:"languages"`        // 规则未指定时的 OCR 语言
+	Timeout       time.Duration `mapstructure:"timeout"`          // 单个文件提取超时
+	MaxFileSizeMB int           `mapstructure:"max_file_size_mb"` // 超过此大小的附件跳过
+	MaxChars      int           `mapstructure:"max_chars"`        // 写入目标字段的最大字符数
+	PollInterval  time.Duration `mapstructure:"poll_interval"`    // 领取待执行任务的间隔
+	BatchSize     int           `mapstructure:"batch_size"`       // 每次领取的任务数
+	LeaseDuration time.Duration `mapstructure:"lease_duration"`   // 领取后的租约时长（超时后可被其他实例重新领取）
+	MaxAttempts   int           `mapstructure:"max_attempts"`     // 引擎出错或附件仍在扫描时的最大尝试次数
+	MaxRunRecords int           `mapstructure:"max_run_records"`  // 手动重新提取整列时的记录数上限
+	JobRetention  time.Duration `mapstructure:"job_retention"`    // 已结束任务的保留时长
+}
+
+// RecurrenceConfig 重复记录规则配置
+type RecurrenceConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 检查到期规则的间隔
+	BatchSize     int           `mapstructure:"batch_size"`     // 每次领取的规则数
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 领取规则的租约时长（应大于单次补跑耗时）
+	RetryDelay    time.Duration `mapstructure:"retry_delay"`    // 可重试错误（如数据库不可用）后再次执行的间隔
+	MaxCatchUp    int           `mapstructure:"max_catch_up"`   // 停机后每条规则最多补建的记录数，更早的执行不再补建
+	RunHistory    int           `mapstructure:"run_history"`    // 每条规则保留的执行记录数
+}
+
+// RecordArchiveConfig 记录归档配置
+type RecordArchiveConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 检查到期策略的间隔
+	RunInterval   time.Duration `mapstructure:"run_interval"`   // 同一策略两次执行的间隔
+	BatchSize     int           `mapstructure:"batch_size"`     // 每个事务移入归档的记录数
+	MaxPerRun     int           `mapstructure:"max_per_run"`    // 单次执行最多归档的记录数，剩余部分下次继续
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 领取策略的租约时长（应大于单次执行耗时）
+	MaxRestore    int           `mapstructure:"max_restore"`    // 单次恢复的记录数上限
+}
+
+// DataExportConfig Base 数据导出配置
+type DataExportConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 领取待执行导出任务的间隔
+	BatchSize     int           `mapstructure:"batch_size"`     // 读取记录时每页的行数
+	RowGroupSize  int           `mapstructure:"row_group_size"` // Parquet 每个行组的行数
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 执行中的任务超过该时长未更新视为中断，可被重新领取
+	URLExpiry     time.Duration `mapstructure:"url_expiry"`     // 下载链接的有效期
+	History       int           `mapstructure:"history"`        // 列出导出任务时返回的条数
+}
+
+// DisasterRecoveryConfig 跨区域灾备配置
+// 启用后主库元数据与 Base 数据表、变更事件、附件文件异步复制到备区域，故障时可提升备区域
+type DisasterRecoveryConfig struct {
+	Enabled            bool           `mapstructure:"enabled"`
+	Region             string         `mapstructure:"region"`              // 备区域名称
+	Database           DatabaseConfig `mapstructure:"database"`            // 备区域数据库连接（未设置的参数沿用主库配置）
+	StoragePath        string         `mapstructure:"storage_path"`        // 备区域附件存储路径
+	SnapshotInterval   time.Duration  `mapstructure:"snapshot_interval"`   // 元数据与数据表快照复制的间隔
+	EventInterval      time.Duration  `mapstructure:"event_interval"`      // 变更事件复制的间隔
+	AttachmentInterval time.Duration  `mapstructure:"attachment_interval"` // 附件文件复制的间隔
+	BatchSize          int            `mapstructure:"batch_size"`          // 每批复制的行数或附件数
+	LeaseDuration      time.Duration  `mapstructure:"lease_duration"`      // 复制流的执行租约时长，多实例部署时超时后由其他实例接手
+	RPOTarget          time.Duration  `mapstructure:"rpo_target"`          // 目标 RPO，超过时状态报告标记为不满足
+}
+
+// SecondaryDatabase 获取备区域数据库配置
+func (d DisasterRecoveryConfig) SecondaryDatabase(primary DatabaseConfig) DatabaseConfig {
+	cfg, _ := ResidencyConfig{Regions: map[string]DatabaseConfig{d.Region: d.Database}}.RegionConfig(d.Region, primary)
+	return cfg
+}
+
+// WorkspaceLifecycleConfig 工作空间生命周期配置
+type WorkspaceLifecycleConfig struct {
+	PollInterval       time.Duration `mapstructure:"poll_interval"`        // 领取到期删除的间隔
+	DefaultGracePeriod time.Duration `mapstructure:"default_grace_period"` // 计划删除时未指定宽限期的默认值
+	MinGracePeriod     time.Duration `mapstructure:"min_grace_period"`     // 宽限期下限
+	LeaseDuration      time.Duration `mapstructure:"lease_duration"`       // 清除中超过该时长未更新视为中断，可被重新领取
+	BatchSize          int           `mapstructure:"batch_size"`           // 导出数据表时每页的行数
+	URLExpiry          time.Duration `mapstructure:"url_expiry"`           // 导出包下载链接的有效期
+}
+
+// ImpersonationConfig 支持人员代入配置
+type ImpersonationConfig struct {
+	SupportUsers    []string      `mapstructure:"support_users"`    // 具有支持角色的用户（用户 ID 或邮箱），为空时不允许代入
+	DefaultDuration time.Duration `mapstructure:"default_duration"` // 未指定时长时的代入时长
+	MaxDuration     time.Duration `mapstructure:"max_duration"`     // 代入时长上限
+}
+
+// AdminConsoleConfig 管理控制台配置
+// 控制台使用独立的登录与令牌，只有管理员可以登录；普通登录令牌不能访问控制台接口
+type AdminConsoleConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`          // 是否启用控制台
+	SessionTTL      time.Duration `mapstructure:"session_ttl"`      // 控制台会话时长
+	AllowedIPs      []string      `mapstructure:"allowed_ips"`      // 允许访问控制台的 IP 或 CIDR，为空时不限制
+	ActiveWindow    time.Duration `mapstructure:"active_window"`    // 统计活跃用户的时间窗口
+	FeatureInterval time.Duration `mapstructure:"feature_interval"` // 同步功能开关覆盖的间隔（多实例部署时生效于其他实例）
+	MaxFailedLogins int           `mapstructure:"max_failed_logins"` // 锁定窗口内同一邮箱或来源 IP 允许的登录失败次数，0 表示不限制
+	LockoutDuration time.Duration `mapstructure:"lockout_duration"`  // 锁定窗口：窗口内失败次数达到上限后拒绝登录，直到较早的失败移出窗口
+}
+
+// MaintenanceConfig 维护模式配置
+// 维护窗口保存在数据库中，由管理员开启与结束；read_only 用于数据库本身不可写时直接将实例置为只读
+type MaintenanceConfig struct {
+	ReadOnly        bool          `mapstructure:"read_only"`        // 实例只读（不能通过接口结束，修改配置后生效）
+	Message         string        `mapstructure:"message"`          // read_only 时展示给用户的提示
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 重新加载维护窗口并结束到期窗口的间隔（多实例部署时生效于其他实例）
+}
+
+// BillingConfig 套餐限额配置
+// 未启用时只计量、不限制；计量结果按空间缓存 MeterTTL，限额检查不会每次都统计业务表
+type BillingConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`      // 是否按套餐限额拒绝超出硬限额的操作
+	DefaultPlan string        `mapstructure:"default_plan"` // 未分配套餐的工作空间使用的套餐
+	MeterTTL    time.Duration `mapstructure:"meter_ttl"`    // 计量结果的缓存时长
+	Stripe      StripeConfig  `mapstructure:"stripe"`       // Stripe 订阅同步
+}
+
+// StripeConfig Stripe 订阅配置
+// 订阅事件按价格映射为工作空间套餐；未配置 webhook_secret 时不接收事件
+type StripeConfig struct {
+	SecretKey            string            `mapstructure:"secret_key"`              // 调用 Stripe API 的密钥（创建客户门户会话）
+	WebhookSecret        string            `mapstructure:"webhook_secret"`          // webhook 签名密钥
+	APIBaseURL           string            `mapstructure:"api_base_url"`            // 为空时使用 https://api.stripe.com
+	PlanPrices           map[string]string `mapstructure:"plan_prices"`             // 套餐ID -> Stripe 价格ID
+	SignatureTolerance   time.Duration     `mapstructure:"signature_tolerance"`     // 签名时间戳允许的偏差
+	DowngradeAtPeriodEnd bool              `mapstructure:"downgrade_at_period_end"` // 降级到计费周期结束再生效
+	PastDueGrace         time.Duration     `mapstructure:"past_due_grace"`          // 扣款失败后保留套餐的时长
+	PollInterval         time.Duration     `mapstructure:"poll_interval"`           // 检查到期降级与欠费的间隔
+	PortalReturnURL      string            `mapstructure:"portal_return_url"`       // 客户门户默认返回地址
+}
+
+// TableHealthConfig 表健康检查配置
+type TableHealthConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 领取待执行检查与到期定时检查的间隔
+	BatchSize     int           `mapstructure:"batch_size"`     // 检查时每页读取的记录数
+	MaxRecords    int           `mapstructure:"max_records"`    // 单次检查的记录数上限，超过时报告标记为不完整
+	MaxIssues     int           `mapstructure:"max_issues"`     // 每份报告保存的问题数上限（统计不受限制）
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 执行中的检查超过此时长未更新视为中断，重新领取
+	ReportHistory int           `mapstructure:"report_history"` // 每张表保留的报告数
+}
+
+// FindReplaceConfig 批量查找替换配置
+type FindReplaceConfig struct {
+	BatchSize      int `mapstructure:"batch_size"`      // 每页读取与写入的记录数
+	MaxRecords     int `mapstructure:"max_records"`     // 单次查找替换扫描的记录数上限
+	PreviewSamples int `mapstructure:"preview_samples"` // 预览返回的替换示例数
+}
+
+// ImageProcessingConfig 图片派生图配置
+type ImageProcessingConfig struct {
+	MaxSourceBytes  int64 `mapstructure:"max_source_bytes"`  // 可处理的原图大小上限
+	MaxSourcePixels int   `mapstructure:"max_source_pixels"` // 可处理的原图像素上限（防止解压炸弹）
+	Quality         int   `mapstructure:"quality"`           // 默认 JPEG 质量
+}
+
+// AttachmentDedupConfig 附件去重配置
+// 关闭后新上传的附件按上传路径存储，已按内容寻址的附件仍可读取与回收
+type AttachmentDedupConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	GCInterval  time.Duration `mapstructure:"gc_interval"`   // 回收任务执行间隔
+	GracePeriod time.Duration `mapstructure:"grace_period"`  // 引用降为 0 后保留的时长
+	GCBatchSize int           `mapstructure:"gc_batch_size"` // 每批回收的内容数
+}
+
+// ResumableUploadConfig 可续传上传配置
+type ResumableUploadConfig struct {
+	MaxSize         int64         `mapstructure:"max_size"`         // 单个上传的大小上限
+	LockTimeout     time.Duration `mapstructure:"lock_timeout"`     // 接收分片时占用上传的时长（接收期间自动续期，实例宕机后到期由其他实例接管）
+	SessionTTL      time.Duration `mapstructure:"session_ttl"`      // 未完成的上传保留时长（不超过上传令牌有效期）
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // 过期上传清理间隔
+}
+
+// ViewProjectionConfig 视图展示投影配置
+type ViewProjectionConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 后台追赶最近读取过的投影的间隔
+	IdleTTL         time.Duration `mapstructure:"idle_ttl"`         // 超过该时长未读取的投影被清理
+	MaxAge          time.Duration `mapstructure:"max_age"`          // 投影全量重建的最长间隔
+	MaxRows         int           `mapstructure:"max_rows"`         // 每个视图投影的行数上限
+	BatchSize       int           `mapstructure:"batch_size"`       // 每批读取的变更数
+}
+
+// ViewQueryCacheConfig 视图查询结果缓存配置
+type ViewQueryCacheConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`     // 是否启用
+	TTL        time.Duration `mapstructure:"ttl"`         // 缓存页有效期（也是跨实例的最长陈旧时间）
+	MaxEntries int           `mapstructure:"max_entries"` // 最多缓存的分页数
+}
+
+// ViewWindowConfig 视图窗口读取配置
+type ViewWindowConfig struct {
+	MaxCount  int `mapstructure:"max_count"`  // 一次读取的最大行数
+	MaxRows   int `mapstructure:"max_rows"`   // 随分组布局缓存的有序记录ID数，超出部分的窗口直接查询
+	MaxGroups int `mapstructure:"max_groups"` // 分组布局最多包含的末级分组数
+}
+
+// AutomationScriptConfig 自动化"运行脚本"动作配置
+type AutomationScriptConfig struct {
+	Enabled           bool          `mapstructure:"enabled"`             // 是否启用
+	Timeout           time.Duration `mapstructure:"timeout"`             // 单次执行的最长时间（节点超时更短时以节点为准）
+	MaxMemoryMB       int           `mapstructure:"max_memory_mb"`       // 执行期间堆内存增长上限（按进程堆采样，近似值）
+	MaxOutputKB       int           `mapstructure:"max_output_kb"`       // 日志与输出的最大大小，超出部分截断
+	MaxAPICalls       int           `mapstructure:"max_api_calls"`       // records 与 fetch 的最大调用次数
+	MaxConcurrent     int           `mapstructure:"max_concurrent"`      // 同时执行的脚本数（限制 CPU 占用）
+	FetchTimeout      time.Duration `mapstructure:"fetch_timeout"`       // 单次 fetch 的超时
+	FetchMaxBytes     int64         `mapstructure:"fetch_max_bytes"`     // 单次 fetch 的响应体上限
+	FetchAllowPrivate bool          `mapstructure:"fetch_allow_private"` // 允许 fetch 访问内网与回环地址（默认禁止）
+}
+
+// PermalinkConfig 永久链接配置
+type PermalinkConfig struct {
+	WebBaseURL    string `mapstructure:"web_base_url"`   // Web 应用地址（如 https://app.example.com），为空时返回相对链接
+	PreviewFields int    `mapstructure:"preview_fields"` // 链接预览中展示的字段数（不含标题字段）
+}
+
+// PrintConfig PDF 打印配置
+type PrintConfig struct {
+	PollInterval  time.Duration `mapstructure:"poll_interval"`  // 领取待执行打印任务的间隔
+	LeaseDuration time.Duration `mapstructure:"lease_duration"` // 执行中的任务超过该时长未更新视为中断，可被重新领取
+	URLExpiry     time.Duration `mapstructure:"url_expiry"`     // 下载链接的有效期
+	History       int           `mapstructure:"history"`        // 列出打印任务时返回的条数
+	MaxRows       int           `mapstructure:"max_rows"`       // 打印视图时最多包含的记录数，超出部分不打印
+}
+
+// DocumentConfig 文档模板配置
+type DocumentConfig struct {
+	PollInterval    time.Duration `mapstructure:"poll_interval"`     // 领取待执行生成任务的间隔
+	LeaseDuration   time.Duration `mapstructure:"lease_duration"`    // 执行中的任务超过该时长未更新视为中断，可被重新领取
+	MaxTemplateSize int64         `mapstructure:"max_template_size"` // 模板文件大小上限（字节）
+	MaxRecords      int           `mapstructure:"max_records"`       // 一个任务最多生成的文档数（按视图生成时超出部分不生成）
+	History         int           `mapstructure:"history"`           // 列出生成任务时返回的条数
+}
+
+// OfflineSyncConfig 移动端离线同步配置
+type OfflineSyncConfig struct {
+	SnapshotPageSize int           `mapstructure:"snapshot_page_size"` // 快照每页的最大记录数
+	MaxMutations     int           `mapstructure:"max_mutations"`      // 一次推送的最大修改数
+	Retention        time.Duration `mapstructure:"retention"`          // 已处理修改的登记保留时长（超过后重复推送会再次执行）
+	CleanupInterval  time.Duration `mapstructure:"cleanup_interval"`   // 过期登记清理间隔
+}
+
+// FeatureFlagConfig 功能开关配置
+type FeatureFlagConfig struct {
+	Description string   `mapstructure:"description"`
+	Enabled     bool     `mapstructure:"enabled"`    // 总开关
+	Rollout     int      `mapstructure:"rollout"`    // 灰度比例 0-100
+	RolloutBy   string   `mapstructure:"rollout_by"` // space（默认）/ user
+	Spaces      []string `mapstructure:"spaces"`     // 始终开启的工作空间
+	Users       []string `mapstructure:"users"`      // 始终开启的用户
+	DenySpaces  []string `mapstructure:"deny_spaces"`
+	DenyUsers   []string `mapstructure:"deny_users"`
+}
+
 // StorageConfig 存储配置
 type StorageConfig struct {
 	Type       string      `mapstructure:"type"` // local, s3, minio
@@ -99,6 +770,17 @@ type LoggerConfig struct {
 	Level      string `mapstructure:"level"`
 	Format     string `mapstructure:"format"` // json, console
 	OutputPath string `mapstructure:"output_path"`
+	// Modules 模块级别覆盖，如 repository.field: debug
+	Modules  map[string]string    `mapstructure:"modules"`
+	Sampling LoggerSamplingConfig `mapstructure:"sampling"`
+}
+
+// LoggerSamplingConfig 模块日志采样配置（仅 Debug/Info）
+type LoggerSamplingConfig struct {
+	Enabled    bool          `mapstructure:"enabled"`
+	Initial    int           `mapstructure:"initial"`
+	Thereafter int           `mapstructure:"thereafter"`
+	Tick       time.Duration `mapstructure:"tick"`
 }
 
 // SQLLoggerConfig SQL日志配置
@@ -135,22 +817,12 @@ type JSVMConfig struct {
 
 // Load 加载配置
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./config")
+	v := newViper()
+	v.SetConfigName("config")
+	v.AddConfigPath(".")
+	v.AddConfigPath("./config")
 
-	// 设置环境变量前缀
-	viper.SetEnvPrefix("LUCKDB")
-	viper.AutomaticEnv()
-
-	// 设置环境变量键替换规则，避免系统环境变量干扰
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-
-	// 设置默认值
-	setDefaults()
-
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
@@ -158,124 +830,500 @@ func Load() (*Config, error) {
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	setConfigFile(v.ConfigFileUsed())
 	return &config, nil
 }
 
+// newViper 创建独立的 viper 实例（环境变量规则与默认值一致）
+// 每次加载使用新实例，热更新与文件监听不会并发读写同一个实例
+func newViper() *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	// 设置环境变量前缀
+	v.SetEnvPrefix("LUCKDB")
+	v.AutomaticEnv()
+
+	// 设置环境变量键替换规则，避免系统环境变量干扰
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	// 设置默认值
+	setDefaults(v)
+	return v
+}
+
 // setDefaults 设置默认配置值
-func setDefaults() {
+func setDefaults(v *viper.Viper) {
 	// Server defaults
-	viper.SetDefault("server.host", "0.0.0.0")
-	viper.SetDefault("server.port", 3000)
-	viper.SetDefault("server.mode", "debug")
-	viper.SetDefault("server.read_timeout", "30s")
-	viper.SetDefault("server.write_timeout", "30s")
-	viper.SetDefault("server.idle_timeout", "120s")
-	viper.SetDefault("server.max_header_bytes", 1<<20) // 1MB
-	viper.SetDefault("server.shutdown_timeout", "10s")
-	viper.SetDefault("server.enable_cors", true)
-	viper.SetDefault("server.enable_swagger", true)
-	viper.SetDefault("server.permissions_disabled", false)
+	v.SetDefault("server.host", "0.0.0.0")
+	v.SetDefault("server.port", 3000)
+	v.SetDefault("server.mode", "debug")
+	v.SetDefault("server.read_timeout", "30s")
+	v.SetDefault("server.write_timeout", "30s")
+	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.max_header_bytes", 1<<20) // 1MB
+	v.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("server.enable_cors", true)
+	v.SetDefault("server.enable_swagger", true)
+	v.SetDefault("server.permissions_disabled", false)
+	v.SetDefault("server.trusted_proxies", []string{})
 
 	// Database defaults
-	viper.SetDefault("database.host", "localhost")
-	viper.SetDefault("database.port", 5432)
-	viper.SetDefault("database.user", "postgres")
-	viper.SetDefault("database.password", "")
-	viper.SetDefault("database.name", "easytable")
-	viper.SetDefault("database.ssl_mode", "disable")
-	viper.SetDefault("database.max_idle_conns", 25)
-	viper.SetDefault("database.max_open_conns", 200)
-	viper.SetDefault("database.conn_max_lifetime", "1h")
-	viper.SetDefault("database.log_level", "info")
+	v.SetDefault("database.host", "localhost")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.user", "postgres")
+	v.SetDefault("database.password", "")
+	v.SetDefault("database.name", "easytable")
+	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.max_idle_conns", 25)
+	v.SetDefault("database.max_open_conns", 200)
+	v.SetDefault("database.conn_max_lifetime", "1h")
+	v.SetDefault("database.log_level", "info")
+	v.SetDefault("database.tenancy.mode", "base")
+	v.SetDefault("database.tenancy.schema_prefix", "ws_")
+	v.SetDefault("database.residency.enabled", false)
+	v.SetDefault("database.residency.default_region", "default")
+	v.SetDefault("database.slow_query.enabled", true)
+	v.SetDefault("database.slow_query.threshold", "200ms")
+	v.SetDefault("database.slow_query.explain", false)
+	v.SetDefault("database.slow_query.max_entries", 200)
+	v.SetDefault("database.slow_query.max_tables", 5000)
+	v.SetDefault("database.query_budget.enabled", true)
+	v.SetDefault("database.query_budget.estimate_ttl", "5m")
+	v.SetDefault("database.query_budget.default.statement_timeout", "30s")
+	v.SetDefault("database.query_budget.default.max_rows_scanned", 5000000)
+	v.SetDefault("database.query_budget.default.max_sort_rows", 1000000)
+	v.SetDefault("database.query_budget.classes", map[string]interface{}{
+		// 表格滚动、汇总栏等交互读取：快速失败，提示用户调整视图
+		"interactive": map[string]interface{}{
+			"routes": []string{
+				"GET /api/v1/tables/:tableId/records",
+				"GET /api/v1/views/:viewId/window",
+				"GET /api/v1/views/:viewId/display-rows",
+				"GET /api/v1/views/:viewId/summary",
+			},
+			"statement_timeout": "10s",
+			"max_rows_scanned":  2000000,
+			"max_sort_rows":     200000,
+		},
+		// 同步导出：允许长时间的全表读取（后台任务的查询不带预算）
+		"bulk": map[string]interface{}{
+			"routes": []string{
+				"GET /api/v1/spaces/:spaceId/gdpr/export",
+			},
+			"statement_timeout": "5m",
+			"max_rows_scanned":  0,
+			"max_sort_rows":     0,
+		},
+	})
+	v.SetDefault("database.fault_injection.enabled", false)
+	v.SetDefault("database.online_migration.auto_expand", true)
+	v.SetDefault("database.online_migration.lock_timeout", "5s")
+	v.SetDefault("database.online_migration.max_retries", 5)
+	v.SetDefault("database.online_migration.backfill_batch_size", 1000)
+	v.SetDefault("database.online_migration.backfill_pause", "100ms")
 
 	// Redis defaults
-	viper.SetDefault("redis.host", "localhost")
-	viper.SetDefault("redis.port", 6379)
-	viper.SetDefault("redis.password", "")
-	viper.SetDefault("redis.db", 0)
-	viper.SetDefault("redis.pool_size", 10)
-	viper.SetDefault("redis.dial_timeout", "5s")
+	v.SetDefault("redis.host", "localhost")
+	v.SetDefault("redis.port", 6379)
+	v.SetDefault("redis.password", "")
+	v.SetDefault("redis.fault_injection.enabled", false)
+	v.SetDefault("redis.circuit_breaker.enabled", true)
+	v.SetDefault("redis.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("redis.circuit_breaker.cool_down", "30s")
+	v.SetDefault("redis.circuit_breaker.max_pending_invalidations", 10000)
+	v.SetDefault("redis.codec.format", "json")
+	v.SetDefault("redis.codec.compression", "none")
+	v.SetDefault("redis.codec.compression_threshold", 1024)
+	v.SetDefault("redis.codec.schema_version", "")
+	v.SetDefault("redis.db", 0)
+	v.SetDefault("redis.pool_size", 10)
+	v.SetDefault("redis.dial_timeout", "5s")
 
 	// JWT defaults
-	viper.SetDefault("jwt.secret", "your-secret-key")
-	viper.SetDefault("jwt.access_token_ttl", "24h")
-	viper.SetDefault("jwt.refresh_token_ttl", "168h") // 7 days
-	viper.SetDefault("jwt.issuer", "luckdb-api")
-	viper.SetDefault("jwt.enable_refresh", true)
+	v.SetDefault("jwt.secret", "your-secret-key")
+	v.SetDefault("jwt.access_token_ttl", "24h")
+	v.SetDefault("jwt.refresh_token_ttl", "168h") // 7 days
+	v.SetDefault("jwt.issuer", "luckdb-api")
+	v.SetDefault("jwt.enable_refresh", true)
 
 	// Storage defaults
-	viper.SetDefault("storage.type", "local")
-	viper.SetDefault("storage.local.upload_path", "./uploads")
-	viper.SetDefault("storage.local.url_prefix", "/uploads")
+	v.SetDefault("storage.type", "local")
+	v.SetDefault("storage.local.upload_path", "./uploads")
+	v.SetDefault("storage.local.url_prefix", "/uploads")
 
 	// Logger defaults
-	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.format", "json")
-	viper.SetDefault("logger.output_path", "stdout")
+	v.SetDefault("logger.level", "info")
+	v.SetDefault("logger.format", "json")
+	v.SetDefault("logger.output_path", "stdout")
+	v.SetDefault("logger.sampling.enabled", true)
+	v.SetDefault("logger.sampling.initial", 10)
+	v.SetDefault("logger.sampling.thereafter", 100)
+	v.SetDefault("logger.sampling.tick", "1s")
 
 	// SQL Logger defaults
-	viper.SetDefault("sql_logger.enabled", true)
-	viper.SetDefault("sql_logger.output_path", "logs/sql.log")
-	viper.SetDefault("sql_logger.max_size", 100)
-	viper.SetDefault("sql_logger.max_backups", 5)
-	viper.SetDefault("sql_logger.max_age", 30)
-	viper.SetDefault("sql_logger.compress", false)
+	v.SetDefault("sql_logger.enabled", true)
+	v.SetDefault("sql_logger.output_path", "logs/sql.log")
+	v.SetDefault("sql_logger.max_size", 100)
+	v.SetDefault("sql_logger.max_backups", 5)
+	v.SetDefault("sql_logger.max_age", 30)
+	v.SetDefault("sql_logger.compress", false)
 
 	// Queue defaults
-	viper.SetDefault("queue.redis_addr", "localhost:6379")
-	viper.SetDefault("queue.redis_password", "")
-	viper.SetDefault("queue.redis_db", 1)
-	viper.SetDefault("queue.max_retries", 3)
-	viper.SetDefault("queue.queue_critical", "critical")
-	viper.SetDefault("queue.queue_default", "default")
-	viper.SetDefault("queue.queue_low", "low")
+	v.SetDefault("queue.redis_addr", "localhost:6379")
+	v.SetDefault("queue.redis_password", "")
+	v.SetDefault("queue.redis_db", 1)
+	v.SetDefault("queue.max_retries", 3)
+	v.SetDefault("queue.queue_critical", "critical")
+	v.SetDefault("queue.queue_default", "default")
+	v.SetDefault("queue.queue_low", "low")
 
 	// JSVM defaults
-	viper.SetDefault("jsvm.enabled", true)
-	viper.SetDefault("jsvm.hooks_dir", "./hooks")
-	viper.SetDefault("jsvm.hooks_watch", true)
-	viper.SetDefault("jsvm.hooks_pool_size", 10)
-	viper.SetDefault("jsvm.plugins_dir", "./plugins")
-	viper.SetDefault("jsvm.hooks_files_pattern", `^.*\.js$`)
-	viper.SetDefault("jsvm.plugins_files_pattern", `^.*\.js$`)
+	v.SetDefault("jsvm.enabled", true)
+	v.SetDefault("jsvm.hooks_dir", "./hooks")
+	v.SetDefault("jsvm.hooks_watch", true)
+	v.SetDefault("jsvm.hooks_pool_size", 10)
+	v.SetDefault("jsvm.plugins_dir", "./plugins")
+	v.SetDefault("jsvm.hooks_files_pattern", `^.*\.js$`)
+	v.SetDefault("jsvm.plugins_files_pattern", `^.*\.js$`)
+
+	// Encryption defaults
+	v.SetDefault("encryption.provider", "local")
+	v.SetDefault("encryption.key_id", "local-master-v1")
+
+	// Health defaults
+	v.SetDefault("health.check_timeout", "2s")
+	v.SetDefault("health.require_cache", false)
+	v.SetDefault("health.queue_depth_warn", 1000)
+	v.SetDefault("health.queue_depth_limit", 0)
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+
+	// Reload defaults
+	v.SetDefault("reload.watch", false)
+	v.SetDefault("reload.poll_interval", "30s")
+
+	// Feature flag defaults（内置开关默认全量开启，配置中覆盖 rollout/deny_* 即可灰度或回退）
+	v.SetDefault("features.realtime_gateway.enabled", true)
+	v.SetDefault("features.realtime_gateway.rollout", 100)
+	v.SetDefault("features.filter_engine.enabled", true)
+	v.SetDefault("features.filter_engine.rollout", 100)
+
+	// Idempotency defaults
+	v.SetDefault("idempotency.enabled", true)
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lock_timeout", "1m")
+	v.SetDefault("idempotency.max_body_size", 1<<20)
+
+	// Schema migration defaults
+	v.SetDefault("schema_migration.poll_interval", "5s")
+	v.SetDefault("schema_migration.batch_size", 1000)
+	v.SetDefault("schema_migration.max_attempts", 5)
+	v.SetDefault("schema_migration.stale_timeout", "10m")
+
+	// Operations defaults
+	v.SetDefault("operations.poll_interval", "2s")
+	v.SetDefault("operations.progress_interval", "1s")
+	v.SetDefault("operations.stale_timeout", "5m")
+	v.SetDefault("operations.max_import_records", 100000)
+
+	// Record snapshot defaults
+	v.SetDefault("record_snapshot.retention", "24h")
+	v.SetDefault("record_snapshot.max_changes", 50000)
+	v.SetDefault("record_snapshot.cleanup_interval", "10m")
+	v.SetDefault("record_snapshot.cell_history_retention", "2160h")
+	v.SetDefault("record_snapshot.max_restore_records", 1000)
+
+	// Change feed defaults
+	v.SetDefault("change_feed.relay_interval", "1s")
+	v.SetDefault("change_feed.relay_batch_size", 1000)
+	v.SetDefault("change_feed.retention", "168h")
+	v.SetDefault("change_feed.cleanup_interval", "1h")
+
+	// Replication defaults
+	v.SetDefault("replication.poll_interval", "5s")
+	v.SetDefault("replication.batch_size", 500)
+	v.SetDefault("replication.request_timeout", "30s")
+	v.SetDefault("replication.lease_duration", "2m")
+	v.SetDefault("replication.allow_private", false)
+
+	// Warehouse sync defaults
+	v.SetDefault("warehouse_sync.poll_interval", "15s")
+	v.SetDefault("warehouse_sync.change_feed_interval", "1m")
+	v.SetDefault("warehouse_sync.min_interval", "15m")
+	v.SetDefault("warehouse_sync.batch_size", 500)
+	v.SetDefault("warehouse_sync.request_timeout", "2m")
+	v.SetDefault("warehouse_sync.lease_duration", "30m")
+	v.SetDefault("warehouse_sync.run_history", 50)
+	v.SetDefault("warehouse_sync.allow_private", false)
+
+	// Synced table defaults
+	v.SetDefault("synced_tables.poll_interval", "15s")
+	v.SetDefault("synced_tables.default_interval", "5m")
+	v.SetDefault("synced_tables.min_interval", "1m")
+	v.SetDefault("synced_tables.batch_size", 200)
+	v.SetDefault("synced_tables.lease_duration", "30m")
+	v.SetDefault("synced_tables.allow_cross_space", true)
+
+	// External sync defaults
+	v.SetDefault("external_sync.poll_interval", "15s")
+	v.SetDefault("external_sync.default_interval", "15m")
+	v.SetDefault("external_sync.min_interval", "1m")
+	v.SetDefault("external_sync.full_sync_interval", "24h")
+	v.SetDefault("external_sync.batch_size", 200)
+	v.SetDefault("external_sync.request_timeout", "2m")
+	v.SetDefault("external_sync.lease_duration", "30m")
+	v.SetDefault("external_sync.run_history", 50)
+	v.SetDefault("external_sync.allow_private", false)
+
+	// Integration defaults
+	v.SetDefault("integrations.poll_interval", "5s")
+	v.SetDefault("integrations.batch_size", 100)
+	v.SetDefault("integrations.request_timeout", "15s")
+	v.SetDefault("integrations.lease_duration", "1m")
+	v.SetDefault("integrations.max_failures", 20)
+
+	// Embed defaults
+	v.SetDefault("embed.default_token_ttl", "24h")
+	v.SetDefault("embed.max_page_size", 200)
+
+	// Usage analytics defaults
+	v.SetDefault("usage.rollup_interval", "15m")
+	v.SetDefault("usage.flush_interval", "1m")
+	v.SetDefault("usage.retention", "9504h") // 396 天
+
+	// Attachment scan defaults
+	v.SetDefault("attachment_scan.engine", "none")
+	v.SetDefault("attachment_scan.timeout", "2m")
+	v.SetDefault("attachment_scan.poll_interval", "5s")
+	v.SetDefault("attachment_scan.batch_size", 20)
+	v.SetDefault("attachment_scan.lease_duration", "10m")
+	v.SetDefault("attachment_scan.allow_private", false)
+
+	// AI field defaults
+	v.SetDefault("ai_field.poll_interval", "2s")
+	v.SetDefault("ai_field.batch_size", 10)
+	v.SetDefault("ai_field.concurrency", 4)
+	v.SetDefault("ai_field.lease_duration", "5m")
+	v.SetDefault("ai_field.request_timeout", "60s")
+	v.SetDefault("ai_field.max_attempts", 3)
+	v.SetDefault("ai_field.max_output_tokens", 1024)
+	v.SetDefault("ai_field.max_regenerate_records", 5000)
+	v.SetDefault("ai_field.job_retention", "2160h") // 90 天
+	v.SetDefault("ai_field.default_monthly_requests", 10000)
+	v.SetDefault("ai_field.default_monthly_tokens", 5000000)
+
+	// Semantic search defaults
+	v.SetDefault("semantic_search.poll_interval", "5s")
+	v.SetDefault("semantic_search.batch_size", 64)
+	v.SetDefault("semantic_search.lease_duration", "5m")
+	v.SetDefault("semantic_search.request_timeout", "60s")
+	v.SetDefault("semantic_search.candidate_limit", 100)
+	v.SetDefault("semantic_search.max_results", 50)
+
+	// Natural-language query defaults
+	v.SetDefault("nl_query.request_timeout", "30s")
+	v.SetDefault("nl_query.max_tokens", 800)
+	v.SetDefault("nl_query.max_results", 100)
+
+	// Text extraction defaults
+	v.SetDefault("text_extraction.engine", "none")
+	v.SetDefault("text_extraction.tesseract_path", "tesseract")
+	v.SetDefault("text_extraction.languages", []string{"eng"})
+	v.SetDefault("text_extraction.timeout", "2m")
+	v.SetDefault("text_extraction.max_file_size_mb", 50)
+	v.SetDefault("text_extraction.max_chars", 100000)
+	v.SetDefault("text_extraction.poll_interval", "5s")
+	v.SetDefault("text_extraction.batch_size", 10)
+	v.SetDefault("text_extraction.lease_duration", "10m")
+	v.SetDefault("text_extraction.max_attempts", 5)
+	v.SetDefault("text_extraction.max_run_records", 5000)
+	v.SetDefault("text_extraction.job_retention", "720h") // 30 天
+
+	// Recurrence defaults
+	v.SetDefault("recurrence.poll_interval", "30s")
+	v.SetDefault("recurrence.batch_size", 10)
+	v.SetDefault("recurrence.lease_duration", "10m")
+	v.SetDefault("recurrence.retry_delay", "1m")
+	v.SetDefault("recurrence.max_catch_up", 31)
+	v.SetDefault("recurrence.run_history", 200)
+
+	// Record archive defaults
+	v.SetDefault("record_archive.poll_interval", "1m")
+	v.SetDefault("record_archive.run_interval", "1h")
+	v.SetDefault("record_archive.batch_size", 500)
+	v.SetDefault("record_archive.max_per_run", 50000)
+	v.SetDefault("record_archive.lease_duration", "30m")
+	v.SetDefault("record_archive.max_restore", 1000)
+
+	// Data export defaults
+	v.SetDefault("data_export.poll_interval", "10s")
+	v.SetDefault("data_export.batch_size", 1000)
+	v.SetDefault("data_export.row_group_size", 50000)
+	v.SetDefault("data_export.lease_duration", "30m")
+	v.SetDefault("data_export.url_expiry", "1h")
+	v.SetDefault("data_export.history", 50)
+
+	// Disaster recovery defaults
+	v.SetDefault("disaster_recovery.enabled", false)
+	v.SetDefault("disaster_recovery.region", "dr")
+	v.SetDefault("disaster_recovery.storage_path", "./uploads-dr")
+	v.SetDefault("disaster_recovery.snapshot_interval", "15m")
+	v.SetDefault("disaster_recovery.event_interval", "10s")
+	v.SetDefault("disaster_recovery.attachment_interval", "1m")
+	v.SetDefault("disaster_recovery.batch_size", 1000)
+	v.SetDefault("disaster_recovery.lease_duration", "30m")
+	v.SetDefault("disaster_recovery.rpo_target", "15m")
+
+	// Workspace lifecycle defaults
+	v.SetDefault("workspace_lifecycle.poll_interval", "1m")
+	v.SetDefault("workspace_lifecycle.default_grace_period", "720h")
+	v.SetDefault("workspace_lifecycle.min_grace_period", "24h")
+	v.SetDefault("workspace_lifecycle.lease_duration", "1h")
+	v.SetDefault("workspace_lifecycle.batch_size", 1000)
+	v.SetDefault("workspace_lifecycle.url_expiry", "1h")
+
+	// Impersonation defaults
+	v.SetDefault("impersonation.default_duration", "30m")
+	v.SetDefault("impersonation.max_duration", "2h")
+
+	// Admin console defaults
+	v.SetDefault("admin_console.enabled", false)
+	v.SetDefault("admin_console.session_ttl", "8h")
+	v.SetDefault("admin_console.active_window", "720h")
+	v.SetDefault("admin_console.feature_interval", "30s")
+	v.SetDefault("admin_console.max_failed_logins", 5)
+	v.SetDefault("admin_console.lockout_duration", "15m")
+
+	// Maintenance defaults
+	v.SetDefault("maintenance.read_only", false)
+	v.SetDefault("maintenance.refresh_interval", "15s")
+
+	// Billing defaults
+	v.SetDefault("billing.enabled", false)
+	v.SetDefault("billing.default_plan", "free")
+	v.SetDefault("billing.meter_ttl", "30s")
+	v.SetDefault("billing.stripe.signature_tolerance", "5m")
+	v.SetDefault("billing.stripe.past_due_grace", "168h")
+	v.SetDefault("billing.stripe.poll_interval", "10m")
+
+	// Table health defaults
+	v.SetDefault("table_health.poll_interval", "10s")
+	v.SetDefault("table_health.batch_size", 500)
+	v.SetDefault("table_health.max_records", 50000)
+	v.SetDefault("table_health.max_issues", 5000)
+	v.SetDefault("table_health.lease_duration", "10m")
+	v.SetDefault("table_health.report_history", 20)
+
+	// Find and replace defaults
+	v.SetDefault("find_replace.batch_size", 200)
+	v.SetDefault("find_replace.max_records", 100000)
+	v.SetDefault("find_replace.preview_samples", 20)
+
+	// Image processing defaults
+	v.SetDefault("image_processing.max_source_bytes", 50*1024*1024)
+	v.SetDefault("image_processing.max_source_pixels", 50_000_000)
+	v.SetDefault("image_processing.quality", 82)
+
+	// Attachment dedup defaults
+	v.SetDefault("attachment_dedup.enabled", true)
+	v.SetDefault("attachment_dedup.gc_interval", "1h")
+	v.SetDefault("attachment_dedup.grace_period", "24h")
+	v.SetDefault("attachment_dedup.gc_batch_size", 100)
+
+	// Resumable upload defaults
+	v.SetDefault("resumable_upload.max_size", 5*1024*1024*1024)
+	v.SetDefault("resumable_upload.session_ttl", "24h")
+	v.SetDefault("resumable_upload.lock_timeout", "1m")
+	v.SetDefault("resumable_upload.cleanup_interval", "1h")
+
+	// View projection defaults
+	v.SetDefault("view_projection.refresh_interval", "30s")
+	v.SetDefault("view_projection.idle_ttl", "24h")
+	v.SetDefault("view_projection.max_age", "1h")
+	v.SetDefault("view_projection.max_rows", 50000)
+	v.SetDefault("view_projection.batch_size", 500)
+
+	// View query cache defaults
+	v.SetDefault("view_query_cache.enabled", true)
+	v.SetDefault("view_query_cache.ttl", "30s")
+	v.SetDefault("view_query_cache.max_entries", 5000)
+
+	// View window defaults
+	v.SetDefault("view_window.max_count", 500)
+	v.SetDefault("view_window.max_rows", 10000)
+	v.SetDefault("view_window.max_groups", 1000)
+
+	// Automation script defaults
+	v.SetDefault("automation_scripts.enabled", true)
+	v.SetDefault("automation_scripts.timeout", "30s")
+	v.SetDefault("automation_scripts.max_memory_mb", 64)
+	v.SetDefault("automation_scripts.max_output_kb", 256)
+	v.SetDefault("automation_scripts.max_api_calls", 200)
+	v.SetDefault("automation_scripts.max_concurrent", 4)
+	v.SetDefault("automation_scripts.fetch_timeout", "10s")
+	v.SetDefault("automation_scripts.fetch_max_bytes", 1048576)
+	v.SetDefault("automation_scripts.fetch_allow_private", false)
+
+	v.SetDefault("permalinks.web_base_url", "")
+	v.SetDefault("permalinks.preview_fields", 4)
+
+	v.SetDefault("printing.poll_interval", "5s")
+	v.SetDefault("printing.lease_duration", "10m")
+	v.SetDefault("printing.url_expiry", "1h")
+	v.SetDefault("printing.history", 20)
+	v.SetDefault("printing.max_rows", 2000)
+
+	v.SetDefault("documents.poll_interval", "5s")
+	v.SetDefault("documents.lease_duration", "10m")
+	v.SetDefault("documents.max_template_size", 10485760)
+	v.SetDefault("documents.max_records", 200)
+	v.SetDefault("documents.history", 20)
+
+	v.SetDefault("offline_sync.snapshot_page_size", 500)
+	v.SetDefault("offline_sync.max_mutations", 500)
+	v.SetDefault("offline_sync.retention", "720h")
+	v.SetDefault("offline_sync.cleanup_interval", "1h")
 
 	// MCP defaults
-	viper.SetDefault("mcp.enabled", true)
-	viper.SetDefault("mcp.server.host", "0.0.0.0")
-	viper.SetDefault("mcp.server.port", 8081)
-	viper.SetDefault("mcp.server.protocol", "http")
-	viper.SetDefault("mcp.server.timeout", "30s")
+	v.SetDefault("mcp.enabled", true)
+	v.SetDefault("mcp.server.host", "0.0.0.0")
+	v.SetDefault("mcp.server.port", 8081)
+	v.SetDefault("mcp.server.protocol", "http")
+	v.SetDefault("mcp.server.timeout", "30s")
 
 	// MCP Auth defaults
-	viper.SetDefault("mcp.auth.api_key.enabled", true)
-	viper.SetDefault("mcp.auth.api_key.header", "X-MCP-API-Key")
-	viper.SetDefault("mcp.auth.api_key.format", "key_id:key_secret")
-	viper.SetDefault("mcp.auth.api_key.key_length", 32)
-	viper.SetDefault("mcp.auth.api_key.secret_length", 64)
-	viper.SetDefault("mcp.auth.api_key.default_ttl", "8760h")
-	viper.SetDefault("mcp.auth.api_key.max_ttl", "87600h")
-
-	viper.SetDefault("mcp.auth.jwt.enabled", true)
-	viper.SetDefault("mcp.auth.jwt.header", "Authorization")
-	viper.SetDefault("mcp.auth.jwt.prefix", "Bearer ")
-	viper.SetDefault("mcp.auth.jwt.issuer", "luckdb-mcp")
-	viper.SetDefault("mcp.auth.jwt.audience", "mcp-client")
-	viper.SetDefault("mcp.auth.jwt.access_token_ttl", "1h")
-	viper.SetDefault("mcp.auth.jwt.refresh_token_ttl", "24h")
-
-	viper.SetDefault("mcp.auth.session.enabled", true)
-	viper.SetDefault("mcp.auth.session.cookie_name", "mcp_session")
-	viper.SetDefault("mcp.auth.session.secure", true)
-	viper.SetDefault("mcp.auth.session.http_only", true)
-	viper.SetDefault("mcp.auth.session.same_site", "strict")
-	viper.SetDefault("mcp.auth.session.max_age", "24h")
+	v.SetDefault("mcp.auth.api_key.enabled", true)
+	v.SetDefault("mcp.auth.api_key.header", "X-MCP-API-Key")
+	v.SetDefault("mcp.auth.api_key.format", "key_id:key_secret")
+	v.SetDefault("mcp.auth.api_key.key_length", 32)
+	v.SetDefault("mcp.auth.api_key.secret_length", 64)
+	v.SetDefault("mcp.auth.api_key.default_ttl", "8760h")
+	v.SetDefault("mcp.auth.api_key.max_ttl", "87600h")
+
+	v.SetDefault("mcp.auth.jwt.enabled", true)
+	v.SetDefault("mcp.auth.jwt.header", "Authorization")
+	v.SetDefault("mcp.auth.jwt.prefix", "Bearer ")
+	v.SetDefault("mcp.auth.jwt.issuer", "luckdb-mcp")
+	v.SetDefault("mcp.auth.jwt.audience", "mcp-client")
+	v.SetDefault("mcp.auth.jwt.access_token_ttl", "1h")
+	v.SetDefault("mcp.auth.jwt.refresh_token_ttl", "24h")
+
+	v.SetDefault("mcp.auth.session.enabled", true)
+	v.SetDefault("mcp.auth.session.cookie_name", "mcp_session")
+	v.SetDefault("mcp.auth.session.secure", true)
+	v.SetDefault("mcp.auth.session.http_only", true)
+	v.SetDefault("mcp.auth.session.same_site", "strict")
+	v.SetDefault("mcp.auth.session.max_age", "24h")
 
 	// MCP Tools defaults
-	viper.SetDefault("mcp.tools.enabled_tools", []string{
+	v.SetDefault("mcp.tools.enabled_tools", []string{
 		"query_records", "search_records", "aggregate_data",
 		"create_record", "update_record", "delete_record", "bulk_operations",
 		"get_table_schema", "create_field", "create_view",
@@ -283,47 +1331,47 @@ func setDefaults() {
 	})
 
 	// MCP Resources defaults
-	viper.SetDefault("mcp.resources.enabled_resources", []string{
+	v.SetDefault("mcp.resources.enabled_resources", []string{
 		"table_schema", "record_data", "metadata",
 	})
 
 	// MCP Prompts defaults
-	viper.SetDefault("mcp.prompts.enabled_prompts", []string{
+	v.SetDefault("mcp.prompts.enabled_prompts", []string{
 		"analyze_data", "create_summary", "generate_insights",
 	})
 
 	// MCP Rate Limit defaults
-	viper.SetDefault("mcp.rate_limit.enabled", true)
-	viper.SetDefault("mcp.rate_limit.redis_url", "")
-	viper.SetDefault("mcp.rate_limit.strategies.user.type", "user")
-	viper.SetDefault("mcp.rate_limit.strategies.user.requests_per_minute", 100)
-	viper.SetDefault("mcp.rate_limit.strategies.user.burst_size", 20)
-	viper.SetDefault("mcp.rate_limit.strategies.api_key.type", "api_key")
-	viper.SetDefault("mcp.rate_limit.strategies.api_key.requests_per_minute", 1000)
-	viper.SetDefault("mcp.rate_limit.strategies.api_key.burst_size", 100)
-	viper.SetDefault("mcp.rate_limit.strategies.ip.type", "ip")
-	viper.SetDefault("mcp.rate_limit.strategies.ip.requests_per_minute", 200)
-	viper.SetDefault("mcp.rate_limit.strategies.ip.burst_size", 50)
+	v.SetDefault("mcp.rate_limit.enabled", true)
+	v.SetDefault("mcp.rate_limit.redis_url", "")
+	v.SetDefault("mcp.rate_limit.strategies.user.type", "user")
+	v.SetDefault("mcp.rate_limit.strategies.user.requests_per_minute", 100)
+	v.SetDefault("mcp.rate_limit.strategies.user.burst_size", 20)
+	v.SetDefault("mcp.rate_limit.strategies.api_key.type", "api_key")
+	v.SetDefault("mcp.rate_limit.strategies.api_key.requests_per_minute", 1000)
+	v.SetDefault("mcp.rate_limit.strategies.api_key.burst_size", 100)
+	v.SetDefault("mcp.rate_limit.strategies.ip.type", "ip")
+	v.SetDefault("mcp.rate_limit.strategies.ip.requests_per_minute", 200)
+	v.SetDefault("mcp.rate_limit.strategies.ip.burst_size", 50)
 
 	// MCP Security defaults
-	viper.SetDefault("mcp.security.validation.enabled", true)
-	viper.SetDefault("mcp.security.validation.max_query_length", 10000)
-	viper.SetDefault("mcp.security.validation.max_parameters", 100)
-	viper.SetDefault("mcp.security.validation.dangerous_keywords", []string{
+	v.SetDefault("mcp.security.validation.enabled", true)
+	v.SetDefault("mcp.security.validation.max_query_length", 10000)
+	v.SetDefault("mcp.security.validation.max_parameters", 100)
+	v.SetDefault("mcp.security.validation.dangerous_keywords", []string{
 		"DROP", "DELETE", "UPDATE", "INSERT", "ALTER", "CREATE",
 	})
 
-	viper.SetDefault("mcp.security.audit.enabled", true)
-	viper.SetDefault("mcp.security.audit.log_level", "info")
-	viper.SetDefault("mcp.security.audit.sensitive_fields", []string{
+	v.SetDefault("mcp.security.audit.enabled", true)
+	v.SetDefault("mcp.security.audit.log_level", "info")
+	v.SetDefault("mcp.security.audit.sensitive_fields", []string{
 		"password", "token", "secret",
 	})
-	viper.SetDefault("mcp.security.audit.retention_days", 90)
+	v.SetDefault("mcp.security.audit.retention_days", 90)
 
-	viper.SetDefault("mcp.security.circuit_breaker.enabled", true)
-	viper.SetDefault("mcp.security.circuit_breaker.failure_threshold", 5)
-	viper.SetDefault("mcp.security.circuit_breaker.timeout", "30s")
-	viper.SetDefault("mcp.security.circuit_breaker.max_requests", 3)
+	v.SetDefault("mcp.security.circuit_breaker.enabled", true)
+	v.SetDefault("mcp.security.circuit_breaker.failure_threshold", 5)
+	v.SetDefault("mcp.security.circuit_breaker.timeout", "30s")
+	v.SetDefault("mcp.security.circuit_breaker.max_requests", 3)
 
 }
 
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// maxRemoteConfigSize 远程配置最大字节数
+const maxRemoteConfigSize = 1 << 20
+
+var (
+	reloadMu      sync.Mutex
+	configFile    string // 启动时使用的配置文件（未使用配置文件时为空）
+	remoteOverlay []byte // 最近一次拉取的远程配置，重新读取文件后需要再次合并
+)
+
+func setConfigFile(file string) {
+	reloadMu.Lock()
+	configFile = file
+	reloadMu.Unlock()
+}
+
+// hotReloadSections 运行时生效的配置段（其余配置段变更需要重启）
+var hotReloadSections = map[string]bool{
+	"Logger":   true,
+	"Features": true,
+	"Reload":   true,
+}
+
+// Reload 重新读取配置文件并合并远程配置
+// 每次使用新的 viper 实例，文件监听与远程配置同步并发触发时串行执行
+func Reload() (*Config, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	v := newViper()
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	if len(remoteOverlay) > 0 {
+		if err := v.MergeConfig(bytes.NewReader(remoteOverlay)); err != nil {
+			return nil, fmt.Errorf("failed to merge remote config: %w", err)
+		}
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// SetRemoteOverlay 设置远程配置（YAML/JSON），下次 Reload 时合并；校验失败时不替换
+func SetRemoteOverlay(data []byte) error {
+	probe := viper.New()
+	probe.SetConfigType("yaml")
+	if err := probe.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("invalid remote config: %w", err)
+	}
+
+	reloadMu.Lock()
+	remoteOverlay = append([]byte(nil), data...)
+	reloadMu.Unlock()
+	return nil
+}
+
+// WatchFile 监听配置文件变更（未使用配置文件时返回 false）
+// 监听使用单独的 viper 实例，只用于触发 onChange，配置内容由 Reload 重新读取
+func WatchFile(onChange func()) bool {
+	reloadMu.Lock()
+	file := configFile
+	reloadMu.Unlock()
+	if file == "" {
+		return false
+	}
+
+	watcher := viper.New()
+	watcher.SetConfigFile(file)
+	watcher.OnConfigChange(func(fsnotify.Event) { onChange() })
+	watcher.WatchConfig()
+	return true
+}
+
+// FetchRemote 拉取远程配置；etag 未变化（304）时 changed 为 false
+func FetchRemote(ctx context.Context, cfg ReloadConfig, etag string) (data []byte, newETag string, changed bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.RemoteURL, nil)
+	if err != nil {
+		return nil, etag, false, err
+	}
+	req.Header.Set("Accept", "application/yaml, application/json")
+	if cfg.RemoteToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.RemoteToken)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, etag, false, fmt.Errorf("fetch remote config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, etag, false, nil
+	case http.StatusOK:
+	default:
+		return nil, etag, false, fmt.Errorf("fetch remote config: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxRemoteConfigSize+1))
+	if err != nil {
+		return nil, etag, false, fmt.Errorf("read remote config: %w", err)
+	}
+	if len(data) > maxRemoteConfigSize {
+		return nil, etag, false, fmt.Errorf("remote config exceeds %d bytes", maxRemoteConfigSize)
+	}
+	return data, resp.Header.Get("ETag"), true, nil
+}
+
+// RestartRequired 返回发生变更但无法在运行时生效的配置段
+func RestartRequired(old, next *Config) []string {
+	var sections []string
+	ov, nv := reflect.ValueOf(*old), reflect.ValueOf(*next)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if hotReloadSections[field.Name] {
+			continue
+		}
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			sections = append(sections, field.Tag.Get("mapstructure"))
+		}
+	}
+	return sections
+}
@@ -11,19 +11,30 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/application"
 	"github.com/easyspace-ai/luckdb/server/internal/config"
 	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/aiprovider"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/crypto"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/scanner"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/storage"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/stripe"
+	textextractInfra "github.com/easyspace-ai/luckdb/server/internal/infrastructure/textextract"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
 
 	// 领域层仓储接口
 	attachmentRepo "github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
 	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
 	collaboratorRepo "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/repository"
 	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	spaceRepo "github.com/easyspace-ai/luckdb/server/internal/domain/space/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
 	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
@@ -46,9 +57,14 @@ type Container struct {
 	cfg *config.Config
 
 	// 基础设施
-	db          *database.Connection
-	dbProvider  database.DBProvider // ✅ 数据库提供者（Schema隔离和动态表管理）
-	cacheClient *cache.RedisClient
+	db             *database.Connection
+	dbProvider     database.DBProvider // ✅ 数据库提供者（Schema隔离和动态表管理）
+	regionDBs      []*database.Connection
+	regionRouter   *database.RegionRouter // 数据驻留路由（未启用时为 nil）
+	drDB           *database.Connection   // 灾备备区域连接（未启用灾备时为 nil）
+	drReplicator   *database.DRReplicator // 灾备复制器（未启用灾备时为 nil）
+	fieldEncryptor *crypto.FieldEncryptor // 加密字段信封加密器（未配置主密钥时为 nil）
+	cacheClient    *cache.RedisClient
 
 	// 仓储层（基础设施层实现）
 	userRepository         userRepo.UserRepository
@@ -56,7 +72,9 @@ type Container struct {
 	collaboratorRepository collaboratorRepo.CollaboratorRepository
 	baseRepository         baseRepo.BaseRepository
 	recordRepository       recordRepo.RecordRepository
+	snapshotReader         recordRepo.SnapshotReader // 按时间点读取表（记录变更日志）
 	fieldRepository        fieldRepo.FieldRepository
+	linkFieldFinder        fieldRepo.LinkFieldFinder // 按关联目标查找关联字段（跨 Base 关联）
 	spaceRepository        spaceRepo.SpaceRepository
 	tableRepository        tableRepo.TableRepository
 	viewRepository         viewRepo.ViewRepository
@@ -68,23 +86,92 @@ type Container struct {
 	userService         *application.UserService
 	userConfigService   *application.UserConfigService // 用户配置服务 ✨
 	authService         *application.AuthService
+	serviceTokenService *application.ServiceTokenService  // 服务令牌 ✨
+	impersonation       *application.ImpersonationService // 支持人员代入 ✨
 	tokenService        *application.TokenService
-	permissionServiceV2 *application.PermissionServiceV2 // 权限服务V2 (Action-based) ✨
-	collaboratorService *application.CollaboratorService // 协作者服务 ✨
+	permissionServiceV2 *application.PermissionServiceV2       // 权限服务V2 (Action-based) ✨
+	batchAuthorizer     *application.BatchAuthorizationService // 批量权限校验 ✨
+	collaboratorService *application.CollaboratorService       // 协作者服务 ✨
 	spaceService        *application.SpaceService
 	baseService         *application.BaseService
 	tableService        *application.TableService
 	fieldService        *application.FieldService
 	recordService       *application.RecordService
 	viewService         *application.ViewService
+	privacyService      *application.PrivacyService            // PII检测与字段脱敏 ✨
+	gdprService         *application.GDPRService               // 数据主体导出与擦除 ✨
+	securityService     *application.SecurityService           // 工作空间安全策略 ✨
+	schemaMigration     *application.SchemaMigrationService    // 动态表结构变更编排 ✨
+	schemaImpact        *application.SchemaImpactService       // 破坏性表结构操作影响预览 ✨
+	referenceService    *application.ReferenceService          // 引用追踪与级联删除 ✨
+	schemaSpecService   *application.SchemaSpecService         // Base 结构即代码（YAML 对比与应用） ✨
+	viewSummaryService  *application.ViewSummaryService        // 视图汇总栏 ✨
+	chartService        *application.ChartService              // 图表数据聚合 ✨
+	dashboardService    *application.DashboardService          // 仪表盘 ✨
+	operationService    *application.OperationService          // 长时操作（导入、转换、恢复） ✨
+	baseBranchService   *application.BaseBranchService         // Base 沙盒分支与合并 ✨
+	usageService        *application.UsageService              // 工作空间用量统计 ✨
+	planService         *application.PlanService               // 套餐限额与用量计量 ✨
+	billingService      *application.BillingService            // Stripe 订阅同步 ✨
+	adminConsole        *application.AdminConsoleService       // 超级管理员控制台 ✨
+	changeFeedService   *application.ChangeFeedService         // 变更流（外部同步） ✨
+	replicationService  *application.ReplicationService        // 复制到外部数据仓库 ✨
+	warehouseSync       *application.WarehouseSyncService      // BigQuery / Snowflake 同步任务 ✨
+	dataExport          *application.DataExportService         // Base 数据导出（Parquet） ✨
+	disasterRecovery    *application.DisasterRecoveryService   // 跨区域灾备复制与提升 ✨
+	workspaceLifecycle  *application.WorkspaceLifecycleService // 工作空间停用、计划删除与清除 ✨
+	maintenance         *application.MaintenanceService        // 维护模式（实例、工作空间或 Base 只读）✨
+	businessCalendar    *application.BusinessCalendarService   // 工作空间工作日历（休息日、节假日与调休）✨
+	onlineMigration     *application.OnlineMigrationService    // 元数据表在线迁移（扩展/收缩）✨
+	integrationService  *application.IntegrationService        // Zapier / Make 触发器与动作 ✨
+	scriptActions       *application.ScriptActionService       // 自动化运行脚本动作 ✨
+	workflowService     *application.WorkflowService           // 工作流运行（页面按钮触发）
+	deliveryMonitor     *application.DeliveryMonitorService    // 推送保障与死信管理 ✨
+	embedService        *application.EmbedService              // 嵌入视图与嵌入令牌 ✨
 	attachmentService   attachmentRepo.Service
+	attachmentStorage   attachmentRepo.Storage
+	attachmentScan      *application.AttachmentScanService     // 附件类型校验与病毒扫描 ✨
+	attachmentBlobGC    *application.AttachmentBlobGCService   // 无引用附件内容回收 ✨
+	resumableUpload     *application.ResumableUploadService    // 可续传附件上传 ✨
+	aiFieldService      *application.AIFieldService            // AI 字段生成与空间配额 ✨
+	semanticSearch      *application.SemanticSearchService     // 记录语义搜索 ✨
+	nlQuery             *application.NLQueryService            // 自然语言查询 ✨
+	textExtraction      *application.TextExtractionService     // 附件 OCR 与文档文本提取 ✨
+	recordTemplate      *application.RecordTemplateService     // 记录模板与快速新建预设 ✨
+	permalinks          *application.PermalinkService          // 记录与视图永久链接、链接预览 ✨
+	backlinks           *application.BacklinkService           // 记录提及的反向链接 ✨
+	printing            *application.PrintService              // 记录与视图的 PDF 打印 ✨
+	documents           *application.DocumentTemplateService   // 文档模板（DOCX/HTML 合并生成附件） ✨
+	scans               *application.ScanService               // 扫码查找记录 ✨
+	offlineSync         *application.OfflineSyncService        // 移动端离线同步 ✨
+	recordLocks         *application.RecordLockService         // 记录编辑锁 ✨
+	statusFlows         *application.StatusFlowService         // 单选字段状态流（审批流） ✨
+	recordArchive       *application.RecordArchiveService      // 记录归档（冷存储） ✨
+	pointInTimeRestore  *application.PointInTimeRestoreService // 按时间点恢复记录或列 ✨
+	recurrence          *application.RecurrenceService         // 按计划从模板新建记录 ✨
+	tableHealth         *application.TableHealthService        // 表健康检查与一键修复 ✨
+	findReplace         *application.FindReplaceService        // 批量查找替换 ✨
+	crossBaseLinks      *application.CrossBaseLinkService      // 跨 Base 关联 ✨
+	syncedTables        *application.SyncedTableService        // 同步表 ✨
+	externalSync        *application.ExternalSyncService       // 外部数据同步 ✨
+	pages               *application.PageService               // 界面页面 ✨
+	viewProjection      *application.ViewProjectionService     // 视图展示投影 ✨
+	viewQueryCache      *application.ViewQueryCache            // 视图查询结果缓存 ✨
+	viewWindow          *application.ViewWindowService         // 虚拟滚动的视图窗口 ✨
+	cellValidation      *application.CellValidationService     // 单元格值批量校验 ✨
 
 	// 基础设施服务 ✨
 	batchService       *application.BatchService       // 批量操作服务
 	cacheService       *application.CacheService       // 统一缓存服务
+	cacheBreaker       *repository.CircuitBreakerCache // 仓储缓存熔断器（未启用时为 nil）
 	eventBus           *application.EventBus           // 事件总线
 	eventStore         *application.EventStore         // 事件存储
 	transactionManager *application.TransactionManager // 统一事务管理器
+	healthService      *application.HealthService      // 健康与就绪检查
+	metricsService     *application.MetricsService     // 抓取时计算的指标
+	queryStatsService  *application.QueryStatsService  // 动态表慢查询与查询统计
+	configService      *application.ConfigService      // 配置热更新与功能开关
+	idempotencyService *application.IdempotencyService // 幂等键（Idempotency-Key）
 
 	// 计算服务（重构后的模块化服务）✨
 	calculationOrchestrator *application.CalculationOrchestrator // 计算编排器
@@ -117,6 +204,9 @@ func NewContainer(cfg *config.Config) *Container {
 func (c *Container) Initialize() error {
 	logger.Info("正在初始化依赖注入容器...")
 
+	// 0. 配置热更新与功能开关（启动配置中的开关立即生效）
+	c.configService = application.NewConfigService(c.cfg, featureflag.Default)
+
 	// 1. 初始化数据库连接
 	if err := c.initDatabase(); err != nil {
 		return fmt.Errorf("初始化数据库失败: %w", err)
@@ -131,6 +221,18 @@ func (c *Container) Initialize() error {
 		logger.Info("✅ 缓存服务已就绪")
 	}
 
+	// 健康检查（缓存可选，连接成功时才注册探测）
+	c.healthService = application.NewHealthService(c.db.GetDB(), c.cfg.Health)
+	if c.cacheClient != nil {
+		c.healthService.SetCacheProbe(c.cacheClient)
+	}
+
+	// 动态表查询统计（汇总主库与各数据区域连接）
+	c.initQueryStats()
+
+	// 幂等键（优先使用 Redis，未连接时存入数据库）
+	c.initIdempotency()
+
 	// 3. 初始化基础设施服务（需要在仓储之前，因为仓储可能需要缓存服务）
 	c.initInfrastructureServicesEarly()
 	logger.Info("✅ 基础设施服务已初始化")
@@ -151,10 +253,39 @@ func (c *Container) Initialize() error {
 		logger.Info("✅ JSVM 和实时通信服务已就绪")
 	}
 
+	// 7. 注册抓取时计算的指标
+	c.initMetrics()
+
 	logger.Info("🎉 依赖注入容器初始化完成")
 	return nil
 }
 
+// initIdempotency 初始化幂等键服务
+func (c *Container) initIdempotency() {
+	store := repository.NewIdempotencyRepository(c.db.GetDB())
+	if c.cacheClient != nil {
+		store = repository.NewRedisIdempotencyStore(c.cacheClient)
+	}
+	c.idempotencyService = application.NewIdempotencyService(store, c.cfg.Idempotency)
+}
+
+// dataDB 获取 Base 物理表所在的数据库连接（未启用数据驻留时即为主库）
+func (c *Container) dataDB(ctx context.Context, baseID string) *gorm.DB {
+	if c.regionRouter == nil {
+		return c.db.GetDB()
+	}
+	return c.regionRouter.DBForBase(ctx, baseID)
+}
+
+// initQueryStats 汇总各数据库连接上慢查询插件的统计
+func (c *Container) initQueryStats() {
+	sources := []*database.QueryStatistics{c.db.QueryStats}
+	for _, conn := range c.regionDBs {
+		sources = append(sources, conn.QueryStats)
+	}
+	c.queryStatsService = application.NewQueryStatsService(c.db.GetDB(), sources...)
+}
+
 // initInfrastructureServicesEarly 早期初始化基础设施服务（只初始化缓存服务）
 func (c *Container) initInfrastructureServicesEarly() {
 	// 只初始化缓存服务（其他服务在initServices中初始化）
@@ -169,6 +300,17 @@ func (c *Container) initInfrastructureServicesEarly() {
 	// 注意：这里创建的errorService是临时的，稍后在initServices中会用正确的errorService替换cacheService
 }
 
+// initMetrics 注册连接池、任务积压、Webhook 投递与实时连接数指标
+func (c *Container) initMetrics() {
+	c.metricsService = application.NewMetricsService(c.db.GetDB(), c.healthService)
+	if c.realtimeManager != nil {
+		c.metricsService.SetConnectionCounter(c.realtimeManager.ConnectionCounts)
+	}
+	if err := c.metricsService.Register(metrics.Default); err != nil {
+		logger.Warn("注册指标失败", logger.ErrorField(err))
+	}
+}
+
 // initDatabase 初始化数据库连接和Provider
 func (c *Container) initDatabase() error {
 	db, err := database.NewConnection(c.cfg.Database)
@@ -185,6 +327,124 @@ func (c *Container) initDatabase() error {
 		logger.String("driver", c.dbProvider.DriverName()),
 		logger.Bool("supports_schema", c.dbProvider.SupportsSchema()))
 
+	// ✅ 按工作空间隔离模式：每个Space独立Schema
+	tenancy := c.cfg.Database.Tenancy
+	if tenancy.IsWorkspaceIsolation() {
+		if !c.dbProvider.SupportsSchema() {
+			logger.Warn("当前数据库不支持Schema，忽略工作空间隔离模式",
+				logger.String("driver", c.dbProvider.DriverName()))
+		} else {
+			c.dbProvider = database.NewTenantSchemaProvider(c.dbProvider, c.db.GetDB(), tenancy.SchemaPrefix)
+			logger.Info("✅ 已启用工作空间Schema隔离",
+				logger.String("schema_prefix", tenancy.SchemaPrefix))
+		}
+	}
+
+	// ✅ 数据驻留：按工作空间路由到区域集群
+	if c.cfg.Database.Residency.Enabled {
+		if err := c.initResidency(factory); err != nil {
+			return err
+		}
+	}
+
+	// ✅ 跨区域灾备：连接备区域（复制由灾备服务在后台执行）
+	if c.cfg.DisasterRecovery.Enabled {
+		if err := c.initDisasterRecovery(factory); err != nil {
+			return err
+		}
+	}
+
+	// ✅ 字段静态加密（密钥元数据保存在主库）
+	if c.cfg.Encryption.IsEnabled() {
+		kms, err := crypto.NewLocalKeyManager(c.cfg.Encryption.KeyID, c.cfg.Encryption.MasterKey)
+		if err != nil {
+			return fmt.Errorf("初始化字段加密失败: %w", err)
+		}
+		c.fieldEncryptor = crypto.NewFieldEncryptor(c.db.GetDB(), kms)
+		logger.Info("✅ 字段静态加密已启用", logger.String("key_id", kms.KeyID()))
+	}
+
+	return nil
+}
+
+// initResidency 初始化数据驻留区域集群
+func (c *Container) initResidency(factory *database.ProviderFactory) error {
+	residency := c.cfg.Database.Residency
+	tenancy := c.cfg.Database.Tenancy
+	primary := c.db.GetDB()
+
+	router := database.NewRegionRouter(primary, c.dbProvider, residency.DefaultRegion)
+	for region := range residency.Regions {
+		if region == residency.DefaultRegion {
+			continue
+		}
+		regionCfg, _ := residency.RegionConfig(region, c.cfg.Database)
+		conn, err := database.NewConnection(regionCfg)
+		if err != nil {
+			return fmt.Errorf("连接数据区域 %s 失败: %w", region, err)
+		}
+		c.regionDBs = append(c.regionDBs, conn)
+
+		var provider database.DBProvider = factory.MustCreateProvider(conn.GetDB())
+		if tenancy.IsWorkspaceIsolation() && provider.SupportsSchema() {
+			// 租户Schema解析依赖元数据，始终查询主库
+			provider = database.NewTenantSchemaProvider(provider, primary, tenancy.SchemaPrefix)
+		}
+		router.AddCluster(region, conn.GetDB(), provider)
+		logger.Info("✅ 数据区域已注册", logger.String("region", region))
+	}
+
+	if tenancy.IsWorkspaceIsolation() {
+		router.SetTenantSchemaPrefix(tenancy.SchemaPrefix)
+	}
+	// 未经路由落到错误区域的读写、以及迁移切换窗口内的写入直接失败
+	if err := router.EnableGuard(); err != nil {
+		return err
+	}
+
+	c.regionRouter = router
+	c.dbProvider = database.NewRegionRoutingProvider(router, c.dbProvider)
+	logger.Info("✅ 已启用数据驻留",
+		logger.String("default_region", residency.DefaultRegion),
+		logger.Any("regions", router.Regions()))
+	return nil
+}
+
+// initDisasterRecovery 连接灾备备区域并创建复制器
+func (c *Container) initDisasterRecovery(factory *database.ProviderFactory) error {
+	dr := c.cfg.DisasterRecovery
+	tenancy := c.cfg.Database.Tenancy
+
+	conn, err := database.NewConnection(dr.SecondaryDatabase(c.cfg.Database))
+	if err != nil {
+		return fmt.Errorf("连接灾备区域 %s 失败: %w", dr.Region, err)
+	}
+	// 复制进度与提升记录保存在备区域
+	if err := conn.Migrate(&models.DRCheckpoint{}, &models.DRPromotion{}); err != nil {
+		conn.Close()
+		return fmt.Errorf("初始化灾备区域 %s 失败: %w", dr.Region, err)
+	}
+	c.drDB = conn
+
+	// 启用数据驻留时复制默认区域（主库所在区域）的集群
+	primary := &database.RegionCluster{Name: c.cfg.Database.Residency.DefaultRegion, DB: c.db.GetDB(), Provider: c.dbProvider}
+	if c.regionRouter != nil {
+		cluster, err := c.regionRouter.Cluster(c.regionRouter.DefaultRegion())
+		if err != nil {
+			return err
+		}
+		primary = cluster
+	}
+
+	var provider database.DBProvider = factory.MustCreateProvider(conn.GetDB())
+	if tenancy.IsWorkspaceIsolation() && provider.SupportsSchema() {
+		// 备区域保存了复制的元数据，租户Schema解析查询备区域自身，提升后同样可用
+		provider = database.NewTenantSchemaProvider(provider, conn.GetDB(), tenancy.SchemaPrefix)
+	}
+	secondary := &database.RegionCluster{Name: dr.Region, DB: conn.GetDB(), Provider: provider}
+
+	c.drReplicator = database.NewDRReplicator(primary, secondary, c.regionRouter, dr.BatchSize)
+	logger.Info("✅ 已启用跨区域灾备", logger.String("secondary_region", dr.Region))
 	return nil
 }
 
@@ -218,19 +478,40 @@ func (c *Container) initRepositories() {
 	// 表格仓储
 	c.tableRepository = repository.NewTableRepository(db)
 
+	// 仓储缓存（启用故障注入时包装一层）
+	var repoCache repository.CacheProvider
+	if c.cacheService != nil {
+		repoCache = c.cacheService
+		if faults := c.cfg.Redis.FaultInjection; faults.Enabled {
+			repoCache = repository.NewFaultInjectingCache(repoCache, faultinject.New("cache", faults))
+			logger.Warn("⚠️ 缓存故障注入已启用，仅可用于测试与预发环境",
+				logger.Float64("error_rate", faults.ErrorRate),
+				logger.Duration("latency", faults.Latency))
+		}
+		// Redis 不可用时熔断，避免每次仓储调用都等待超时
+		if breaker := c.cfg.Redis.CircuitBreaker; breaker.Enabled {
+			c.cacheBreaker = repository.NewCircuitBreakerCache(repoCache, breaker)
+			repoCache = c.cacheBreaker
+			if c.healthService != nil {
+				c.healthService.SetCacheCircuit(c.cacheBreaker)
+			}
+		}
+	}
+
 	// ✅ 字段仓储（带缓存）
 	baseFieldRepo := repository.NewFieldRepository(db)
-	if c.cacheService != nil {
+	if repoCache != nil {
 		// 使用缓存包装器（5分钟TTL）
 		c.fieldRepository = repository.NewCachedFieldRepository(
 			baseFieldRepo,
-			c.cacheService,
+			repoCache,
 			5*time.Minute,
 		)
 		logger.Info("✅ 字段仓储已启用缓存")
 	} else {
 		c.fieldRepository = baseFieldRepo
 	}
+	c.linkFieldFinder, _ = baseFieldRepo.(fieldRepo.LinkFieldFinder)
 
 	// ✅ 记录仓储（完全动态表架构）
 	// 需要在 tableRepository 和 fieldRepository 之后初始化
@@ -240,13 +521,22 @@ func (c *Container) initRepositories() {
 		c.tableRepository, // ✅ 注入 TableRepository
 		c.fieldRepository, // ✅ 注入 FieldRepository
 	)
+	if dynamicRepo, ok := baseRecordRepo.(*repository.RecordRepositoryDynamic); ok {
+		if c.regionRouter != nil {
+			dynamicRepo.SetRegionRouter(c.regionRouter)
+		}
+		if c.fieldEncryptor != nil {
+			dynamicRepo.SetFieldEncryptor(c.fieldEncryptor)
+		}
+		c.snapshotReader = dynamicRepo
+	}
 
 	// ✅ 记录仓储（带缓存）
-	if c.cacheService != nil {
+	if repoCache != nil {
 		// 使用缓存包装器（2分钟TTL，记录变化频繁）
 		c.recordRepository = repository.NewCachedRecordRepository(
 			baseRecordRepo,
-			c.cacheService,
+			repoCache,
 			2*time.Minute,
 		)
 		logger.Info("✅ 记录仓储已启用缓存")
@@ -318,6 +608,21 @@ func (c *Container) initServices() {
 	// 8. 认证服务
 	c.authService = application.NewAuthService(c.userRepository, c.tokenService)
 
+	// 8.1 服务令牌（Bearer 令牌以 lsk_ 开头时按服务令牌认证）✨
+	c.serviceTokenService = application.NewServiceTokenService(
+		repository.NewServiceTokenRepository(c.db.GetDB()),
+		c.userRepository,
+	)
+	c.authService.SetServiceTokenService(c.serviceTokenService)
+
+	// 8.2 支持人员代入（Bearer 令牌以 lim_ 开头时按代入令牌认证）✨
+	c.impersonation = application.NewImpersonationService(
+		repository.NewImpersonationRepository(c.db.GetDB()),
+		c.userRepository,
+		c.cfg.Impersonation,
+	)
+	c.authService.SetImpersonationService(c.impersonation)
+
 	// 9. 权限服务V2 ✨
 	c.permissionServiceV2 = application.NewPermissionServiceV2(
 		c.collaboratorRepository,
@@ -327,6 +632,77 @@ func (c *Container) initServices() {
 		c.fieldRepository, // ✅ 添加FieldRepository支持Field权限检查
 		c.viewRepository,  // ✅ 添加ViewRepository支持View权限检查
 	)
+	c.configService.SetPermissionService(c.permissionServiceV2)
+	// ✨ 批量权限校验（批量变更、导入与自动化运行内复用表与角色的解析结果）
+	c.batchAuthorizer = application.NewBatchAuthorizationService(c.permissionServiceV2)
+
+	// 9.1 工作空间安全策略与会话 ✨
+	securityRepo := repository.NewSecurityRepository(c.db.GetDB())
+	c.authService.SetSessionRepository(securityRepo)
+	c.securityService = application.NewSecurityService(
+		securityRepo,
+		c.baseRepository,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.permissionServiceV2,
+	)
+	if locator, ok := c.snapshotReader.(application.RecordLocator); ok {
+		c.securityService.SetResourceLocators(locator, c.attachmentRepository)
+	}
+
+	// 9.1.1 工作空间工作日历（WORKDAY / NETWORKDAYS、模板的工作日偏移与逾期工作日过滤）✨
+	c.businessCalendar = application.NewBusinessCalendarService(
+		repository.NewBusinessCalendarRepository(c.db.GetDB()),
+		c.securityService,
+		c.permissionServiceV2,
+	)
+
+	// 9.2 工作空间用量统计（服务令牌调用计数归属到请求资源所在的空间）✨
+	c.usageService = application.NewUsageService(
+		repository.NewUsageRepository(c.db.GetDB()),
+		c.baseRepository,
+		repository.NewServiceTokenRepository(c.db.GetDB()),
+		c.permissionServiceV2,
+		c.cfg.Usage,
+	)
+
+	// 9.3 套餐限额（计量取自用量统计，记录数按 Base 实时统计）✨
+	c.planService = application.NewPlanService(
+		repository.NewPlanRepository(c.db.GetDB()),
+		repository.NewUsageRepository(c.db.GetDB()),
+		c.usageService,
+		c.spaceRepository,
+		c.baseRepository,
+		c.tableRepository,
+		c.recordRepository,
+		c.permissionServiceV2,
+		c.cfg.Billing,
+	)
+
+	// 9.4 Stripe 订阅同步（webhook 映射为套餐变更，客户门户）✨
+	var stripePortal subscription.Portal
+	if c.cfg.Billing.Stripe.SecretKey != "" {
+		stripePortal = stripe.NewClient(c.cfg.Billing.Stripe.APIBaseURL, c.cfg.Billing.Stripe.SecretKey)
+	}
+	c.billingService = application.NewBillingService(
+		repository.NewSubscriptionRepository(c.db.GetDB()),
+		c.planService,
+		stripePortal,
+		c.permissionServiceV2,
+		c.cfg.Billing.Stripe,
+	)
+
+	// 9.5 管理控制台（独立认证域，功能开关覆盖）✨
+	c.adminConsole = application.NewAdminConsoleService(
+		repository.NewAdminConsoleRepository(c.db.GetDB()),
+		repository.NewAdminDirectory(c.db.GetDB()),
+		c.userRepository,
+		securityRepo,
+		c.planService,
+		c.configService,
+		c.cfg.AdminConsole,
+	)
 
 	// 10. 协作者服务 ✨
 	c.collaboratorService = application.NewCollaboratorService(c.collaboratorRepository)
@@ -347,6 +723,32 @@ func (c *Container) initServices() {
 		c.tableRepository, // ✅ 注入TableRepository
 		c.dbProvider,      // ✅ 注入DBProvider
 	)
+	c.fieldService.SetEncryptionEnabled(c.fieldEncryptor != nil)
+
+	// 13.1 ✨ 结构变更编排（字段增删改的回填、约束、列类型与 DROP COLUMN 由后台任务执行）
+	c.schemaMigration = application.NewSchemaMigrationService(
+		repository.NewSchemaMigrationRepository(c.db.GetDB()),
+		c.fieldRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.cfg.SchemaMigration,
+	)
+	c.fieldService.SetSchemaMigrator(c.schemaMigration)
+	// ✨ 同一张表的结构变更（含 If-Match 校验）在 advisory lock 内串行执行，多实例之间互斥
+	c.fieldService.SetSchemaLocker(database.NewAdvisoryLocker(c.db.GetDB()))
+
+	// 13.2 ✨ 删除字段/类型转换的影响预览
+	c.schemaImpact = application.NewSchemaImpactService(
+		c.fieldRepository,
+		c.tableRepository,
+		c.viewRepository,
+		c.fieldService,
+		c.dbProvider,
+		c.dataDB,
+		c.db.GetDB(),
+		c.permissionServiceV2,
+	)
 
 	// 14. TableService（依赖 FieldService 和 ViewService）
 	c.tableService = application.NewTableService(
@@ -359,8 +761,40 @@ func (c *Container) initServices() {
 		c.dbProvider,  // ✅ 注入DBProvider
 	)
 
+	// 14.1 ✨ 引用追踪与级联删除（block / detach / delete）
+	c.referenceService = application.NewReferenceService(
+		c.schemaImpact,
+		c.fieldRepository,
+		c.tableRepository,
+		c.viewRepository,
+		c.tableService,
+		c.fieldService,
+		c.db.GetDB(),
+		c.permissionServiceV2,
+	)
+
+	// 14.2 ✨ Base 结构即代码（YAML 结构描述的导出、对比与应用）
+	c.schemaSpecService = application.NewSchemaSpecService(
+		c.baseRepository,
+		c.tableService,
+		c.fieldService,
+		c.viewService,
+		c.permissionServiceV2,
+	)
+
+	// 14.3 ✨ 视图汇总栏（按视图筛选条件在 SQL 中聚合）
+	c.viewSummaryService = application.NewViewSummaryService(
+		c.viewRepository,
+		c.fieldRepository,
+		c.tableRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+	)
+
 	// 15. ✨ 初始化模块化计算服务（重构后的架构）
 	c.initCalculationServices()
+	c.formulaService.SetBusinessCalendarService(c.businessCalendar)
 
 	// ✨ 计算引擎服务（在RecordService之前初始化）
 	// 仅使用业务事件/YJS+SSE，不再注入旧 WebSocket
@@ -369,6 +803,7 @@ func (c *Container) initServices() {
 		c.recordRepository,
 		c.businessEventManager, // ✨ 业务事件管理器
 	)
+	c.calculationService.SetBusinessCalendarService(c.businessCalendar)
 
 	// ✅ Phase 2: 类型转换服务
 	typecastService := application.NewTypecastService(c.fieldRepository)
@@ -386,8 +821,628 @@ func (c *Container) initServices() {
 		nil,                    // ✨ ShareDB 服务将在 initJSVMServices 中设置
 	)
 
+	// ✨ PII检测与字段脱敏策略
+	c.privacyService = application.NewPrivacyService(
+		repository.NewPrivacyRepository(c.db.GetDB()),
+		c.recordRepository,
+		c.fieldRepository,
+		c.tableRepository,
+		c.baseRepository,
+		c.permissionServiceV2,
+	)
+	c.recordService.SetPrivacyService(c.privacyService)
+	c.viewSummaryService.SetPrivacyService(c.privacyService)
+	c.recordService.SetPlanService(c.planService)
+	c.recordService.SetBatchAuthorizer(c.batchAuthorizer)
+	if c.snapshotReader != nil {
+		c.recordService.SetSnapshotReader(c.snapshotReader, c.cfg.RecordSnapshot)
+		if cellHistory, ok := c.snapshotReader.(recordRepo.CellHistoryReader); ok {
+			c.recordService.SetCellHistoryReader(cellHistory)
+		}
+	}
+	c.recordService.SetExpandService(application.NewRecordExpandService(
+		c.recordRepository,
+		c.fieldRepository,
+		c.userRepository,
+		c.permissionServiceV2,
+		c.privacyService,
+	))
+
+	// ✨ 图表数据（服务端聚合，脱敏字段不可作为维度）
+	c.chartService = application.NewChartService(
+		c.fieldRepository,
+		c.tableRepository,
+		c.viewRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.privacyService,
+	)
+
+	// ✨ 仪表盘（组件数据来自图表数据与视图汇总）
+	c.dashboardService = application.NewDashboardService(
+		repository.NewDashboardRepository(c.db.GetDB()),
+		c.baseRepository,
+		c.tableRepository,
+		c.viewRepository,
+		c.chartService,
+		c.viewSummaryService,
+		c.permissionServiceV2,
+	)
+
+	// ✨ 变更流：记录、字段、视图变更写入发件箱
+	c.changeFeedService = application.NewChangeFeedService(
+		repository.NewChangeEventRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.cfg.ChangeFeed,
+	)
+	c.recordService.SetChangeFeed(c.changeFeedService)
+	c.fieldService.SetChangeFeed(c.changeFeedService)
+	c.viewService.SetChangeFeed(c.changeFeedService)
+
+	// ✨ 视图查询结果缓存（由变更流按表、字段、视图依赖失效）
+	c.viewQueryCache = application.NewViewQueryCache(c.cfg.ViewQueryCache)
+	c.changeFeedService.SetViewQueryCache(c.viewQueryCache)
+
+	// ✨ 跨 Base 关联：目标表校验、按目标 Base 鉴权与变更事件传播
+	c.crossBaseLinks = application.NewCrossBaseLinkService(
+		c.tableRepository,
+		c.baseRepository,
+		c.fieldRepository,
+		c.linkFieldFinder,
+		c.permissionServiceV2,
+		c.businessEventManager,
+	)
+	c.fieldService.SetCrossBaseLinkService(c.crossBaseLinks)
+	c.recordService.SetCrossBaseLinkService(c.crossBaseLinks)
+
+	// ✨ 复制目标：逻辑复制发布与 Debezium 兼容推送
+	c.replicationService = application.NewReplicationService(
+		repository.NewReplicationTargetRepository(c.db.GetDB()),
+		c.changeFeedService,
+		c.tableRepository,
+		c.permissionServiceV2,
+		c.cfg.Replication,
+	)
+	c.privacyService.SetPublicationChecker(c.replicationService)
+
+	// ✨ 数据仓库同步任务（BigQuery / Snowflake）
+	c.warehouseSync = application.NewWarehouseSyncService(
+		repository.NewWarehouseSyncRepository(c.db.GetDB()),
+		c.changeFeedService,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.cfg.WarehouseSync,
+	)
+	c.warehouseSync.SetPlanService(c.planService)
+
+	// ✨ 集成平台（Zapier / Make）触发器、REST Hook 与动作
+	hookRepo := repository.NewIntegrationHookRepository(c.db.GetDB())
+	deliveryRepo := repository.NewIntegrationDeliveryRepository(c.db.GetDB())
+	c.integrationService = application.NewIntegrationService(
+		hookRepo,
+		deliveryRepo,
+		c.changeFeedService,
+		c.recordService,
+		c.tableRepository,
+		c.fieldRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.cfg.Integrations,
+	)
+
+	// ✨ 自动化运行脚本动作（沙箱执行，以触发者身份读写记录）
+	c.scriptActions = application.NewScriptActionService(
+		c.db.GetDB(),
+		c.integrationService,
+		c.recordService,
+		c.fieldRepository,
+		c.permissionServiceV2,
+		c.cfg.AutomationScripts,
+	)
+	c.scriptActions.SetBatchAuthorizer(c.batchAuthorizer)
+	c.workflowService = application.NewWorkflowService(c.db.GetDB())
+	c.workflowService.SetScriptActions(c.scriptActions)
+
+	// ✨ 推送保障与死信管理（失败推送、失败运行的查看与批量重试）
+	c.deliveryMonitor = application.NewDeliveryMonitorService(hookRepo, deliveryRepo, c.workflowService, c.db.GetDB())
+	c.integrationService.SetPauseListener(c.deliveryMonitor.NotifyHookPaused)
+
+	// ✨ 嵌入视图（签名嵌入令牌，按工作空间控制来源）
+	c.embedService = application.NewEmbedService(
+		repository.NewEmbedSettingsRepository(c.db.GetDB()),
+		c.baseRepository,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.recordRepository,
+		c.recordService,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.cfg.JWT.Secret,
+		c.cfg.Embed,
+	)
+	c.embedService.SetViewQueryCache(c.viewQueryCache)
+
+	// ✨ 长时操作（各类型执行器在此注册）
+	operationRepo := repository.NewOperationRepository(c.db.GetDB())
+	c.operationService = application.NewOperationService(
+		operationRepo,
+		c.permissionServiceV2,
+		c.businessEventManager,
+		c.cfg.Operations,
+	)
+	c.operationService.SetPlanService(c.planService)
+	c.operationService.RegisterExecutor(operation.TypeRecordImport, application.NewRecordImportExecutor(
+		c.recordService,
+		c.tableRepository,
+		c.permissionServiceV2,
+		c.cfg.Operations.MaxImportRecords,
+	))
+
+	// ✨ Base 沙盒分支（合并回生产以 branch_promote 长时操作执行）
+	c.baseBranchService = application.NewBaseBranchService(
+		repository.NewBaseBranchRepository(c.db.GetDB()),
+		c.baseService,
+		c.schemaSpecService,
+		c.operationService,
+		c.permissionServiceV2,
+	)
+	c.operationService.RegisterExecutor(operation.TypeBranchPromote, application.NewBranchPromoteExecutor(c.baseBranchService))
+
+	// ✨ 批量查找替换（预览在请求内执行，替换与撤销以 find_replace / find_replace_undo 长时操作执行）
+	c.findReplace = application.NewFindReplaceService(
+		repository.NewFindReplaceRepository(c.db.GetDB()),
+		operationRepo,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.recordRepository,
+		c.recordService,
+		c.privacyService,
+		c.permissionServiceV2,
+		c.dbProvider,
+		c.dataDB,
+		c.cfg.FindReplace,
+	)
+	c.operationService.RegisterExecutor(operation.TypeFindReplace, application.NewFindReplaceExecutor(c.findReplace))
+	c.operationService.RegisterExecutor(operation.TypeFindReplaceUndo, application.NewFindReplaceUndoExecutor(c.findReplace))
+
+	// ✨ 同步表（将其他 Base 的视图镜像为只读表，按间隔从变更流增量同步）
+	c.syncedTables = application.NewSyncedTableService(
+		repository.NewSyncedTableRepository(c.db.GetDB()),
+		c.changeFeedService,
+		c.tableRepository,
+		c.baseRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.recordRepository,
+		c.tableService,
+		c.fieldService,
+		c.recordService,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.cfg.SyncedTables,
+	)
+	c.recordService.SetSyncedTableService(c.syncedTables)
+
+	// ✨ 外部数据同步（从外部数据库表或 REST 接口拉取到只读同步表）
+	c.externalSync = application.NewExternalSyncService(
+		repository.NewExternalSyncRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.tableService,
+		c.fieldService,
+		c.recordService,
+		c.permissionServiceV2,
+		c.syncedTables,
+		c.cfg.ExternalSync,
+	)
+	if c.fieldEncryptor != nil {
+		c.externalSync.SetCredentialSealer(c.fieldEncryptor)
+	}
+
+	// ✨ 界面页面（按钮触发工作流，访问按页面分享规则校验）
+	c.pages = application.NewPageService(
+		repository.NewPageRepository(c.db.GetDB()),
+		c.baseRepository,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.recordRepository,
+		c.recordService,
+		c.privacyService,
+		c.workflowService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+	)
+	c.pages.SetViewQueryCache(c.viewQueryCache)
+
+	// ✨ 视图展示投影（预先解析展示值的列表行，按变更流增量维护）
+	c.viewProjection = application.NewViewProjectionService(
+		repository.NewViewProjectionRepository(c.db.GetDB()),
+		c.changeFeedService,
+		c.viewRepository,
+		c.fieldRepository,
+		c.tableRepository,
+		c.recordRepository,
+		c.userRepository,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.cfg.ViewProjection,
+	)
+
+	// ✨ 视图窗口（虚拟滚动表格按行窗口读取，分组布局经视图查询缓存复用）
+	c.viewWindow = application.NewViewWindowService(
+		c.viewRepository,
+		c.fieldRepository,
+		c.tableRepository,
+		c.recordRepository,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.viewQueryCache,
+		c.cfg.ViewWindow,
+	)
+
+	// ✨ 单元格值批量校验（不写入，规则与记录写入共用）
+	c.cellValidation = application.NewCellValidationService(
+		c.fieldRepository,
+		c.tableRepository,
+		c.permissionServiceV2,
+	)
+
 	// ✅ 初始化附件服务
 	c.initAttachmentService()
+
+	// ✨ 附件上传后处理（类型嗅探、空间策略校验与病毒扫描，完成前附件处于隔离状态）
+	attachmentScanner, err := scanner.New(c.cfg.AttachmentScan)
+	if err != nil {
+		logger.Error("附件扫描引擎配置无效，仅做类型与策略校验", logger.ErrorField(err))
+	}
+	c.attachmentScan = application.NewAttachmentScanService(
+		repository.NewAttachmentPolicyRepository(c.db.GetDB()),
+		c.attachmentRepository,
+		c.attachmentStorage,
+		attachmentScanner,
+		c.securityService,
+		c.permissionServiceV2,
+		c.cfg.AttachmentScan,
+	)
+	c.attachmentService.SetUploadListener(c.attachmentScan.Notify)
+	c.attachmentService.SetUploadGuard(c.planService.CheckAttachmentUpload)
+
+	// ✨ 附件按内容寻址存储（SHA-256 去重 + 引用计数），无引用内容由回收任务清理
+	attachmentBlobRepo := repository.NewAttachmentBlobRepository(c.db.GetDB())
+	if c.cfg.AttachmentDedup.Enabled {
+		c.attachmentService.SetBlobRepository(attachmentBlobRepo)
+	}
+	c.attachmentBlobGC = application.NewAttachmentBlobGCService(attachmentBlobRepo, c.attachmentStorage, c.cfg.AttachmentDedup)
+
+	// ✨ Base 数据导出（流式写为 Parquet，连同清单写入对象存储）
+	c.dataExport = application.NewDataExportService(
+		repository.NewDataExportRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.privacyService,
+		c.attachmentStorage,
+		c.cfg.DataExport,
+	)
+
+	// ✨ 跨区域灾备（快照、变更事件与附件异步复制到备区域，进度保存在备区域）
+	if c.drReplicator != nil {
+		c.disasterRecovery = application.NewDisasterRecoveryService(
+			repository.NewDisasterRecoveryRepository(c.drDB.GetDB()),
+			c.drReplicator,
+			c.attachmentStorage,
+			storage.NewLocalStorage(c.cfg.DisasterRecovery.StoragePath, logger.Logger),
+			c.cfg.DisasterRecovery,
+		)
+	}
+
+	// ✨ 工作空间生命周期（停用后只读，宽限期结束后导出完整数据包再清除）
+	workspaceStore := repository.NewWorkspaceDataStore(c.db.GetDB(), c.dbProvider)
+	if c.regionRouter != nil {
+		workspaceStore.SetRegionRouter(c.regionRouter)
+	}
+	c.workspaceLifecycle = application.NewWorkspaceLifecycleService(
+		repository.NewWorkspaceLifecycleRepository(c.db.GetDB()),
+		workspaceStore,
+		c.attachmentStorage,
+		c.cacheService,
+		c.cfg.WorkspaceLifecycle,
+	)
+
+	// ✨ 维护模式（记录、字段与表的写服务在写入前检查，开启与结束通过实时事件通知客户端）
+	c.maintenance = application.NewMaintenanceService(
+		repository.NewMaintenanceRepository(c.db.GetDB()),
+		c.securityService,
+		c.businessEventManager,
+		c.cfg.Maintenance,
+	)
+	c.recordService.SetMaintenanceService(c.maintenance)
+	c.fieldService.SetMaintenanceService(c.maintenance)
+	c.tableService.SetMaintenanceService(c.maintenance)
+
+	// ✨ 元数据表在线迁移（启动后在后台执行扩展迁移，多实例滚动部署时由 advisory lock 保证只有一个执行）
+	c.onlineMigration = application.NewOnlineMigrationService(
+		database.NewOnlineMigrator(c.db.GetDB(), c.cfg.Database.OnlineMigration),
+		c.cfg.Database.OnlineMigration.AutoExpand,
+	)
+
+	// ✨ 可续传附件上传（分片保存在附件存储中，多实例共享；完成后经附件服务写入附件）
+	c.resumableUpload = application.NewResumableUploadService(
+		repository.NewUploadSessionRepository(c.db.GetDB()),
+		c.uploadTokenRepository,
+		c.attachmentService,
+		c.attachmentStorage,
+		c.cfg.ResumableUpload,
+	)
+
+	// ✨ GDPR 数据主体导出与擦除（依赖附件存储）
+	subjectStore := repository.NewSubjectDataStore(c.db.GetDB(), c.dbProvider)
+	if c.regionRouter != nil {
+		subjectStore.SetRegionRouter(c.regionRouter)
+	}
+	c.gdprService = application.NewGDPRService(
+		repository.NewPrivacyRepository(c.db.GetDB()),
+		subjectStore,
+		c.recordRepository,
+		c.permissionServiceV2,
+		c.attachmentStorage,
+		c.cfg.JWT.Secret,
+	)
+
+	// ✨ AI 字段生成（记录写入时登记任务，后台调用 ai.providers 中配置的服务商）
+	aiProviders, err := aiprovider.New(c.cfg.AI)
+	if err != nil {
+		logger.Error("部分 AI 服务商配置无效，已跳过", logger.ErrorField(err))
+	}
+	c.aiFieldService = application.NewAIFieldService(
+		repository.NewAIGenerationRepository(c.db.GetDB()),
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		aiProviders,
+		c.cfg.AI.DefaultProvider,
+		c.securityService,
+		c.permissionServiceV2,
+		c.cfg.AIField,
+	)
+	c.recordService.SetAIFieldService(c.aiFieldService)
+
+	// ✨ 记录语义搜索（向量化使用同一组服务商，从变更流增量更新向量）
+	semanticProvider := c.cfg.SemanticSearch.Provider
+	if semanticProvider == "" {
+		semanticProvider = c.cfg.AI.DefaultProvider
+	}
+	c.semanticSearch = application.NewSemanticSearchService(
+		repository.NewSemanticSearchRepository(c.db.GetDB()),
+		repository.NewChangeEventRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		aiprovider.Embedders(aiProviders),
+		semanticProvider,
+		c.securityService,
+		c.permissionServiceV2,
+		c.cfg.SemanticSearch,
+	)
+
+	// ✨ 自然语言查询（模型生成视图过滤条件，由过滤引擎执行）
+	c.nlQuery = application.NewNLQueryService(
+		c.tableRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		aiProviders,
+		c.cfg.AI.DefaultProvider,
+		c.permissionServiceV2,
+		c.cfg.NLQuery,
+	)
+
+	// ✨ 附件文本提取（记录写入时登记任务，后台经 Tika / Tesseract 提取文本写入目标字段）
+	textExtractor, err := textextractInfra.New(c.cfg.TextExtraction)
+	if err != nil {
+		logger.Error("文本提取引擎配置无效，已禁用附件文本提取", logger.ErrorField(err))
+	}
+	c.textExtraction = application.NewTextExtractionService(
+		repository.NewTextExtractionRepository(c.db.GetDB()),
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		c.attachmentRepository,
+		c.attachmentStorage,
+		textExtractor,
+		c.permissionServiceV2,
+		c.cfg.TextExtraction,
+	)
+	c.recordService.SetTextExtractionService(c.textExtraction)
+
+	// ✨ 记录模板（预设单元格值，经记录服务新建记录）
+	c.recordTemplate = application.NewRecordTemplateService(
+		repository.NewRecordTemplateRepository(c.db.GetDB()),
+		c.fieldRepository,
+		c.recordService,
+		c.permissionServiceV2,
+	)
+	c.recordTemplate.SetBusinessCalendarService(c.businessCalendar)
+
+	// ✨ 记录编辑锁（锁定整条记录或部分字段，记录服务写入时检查）
+	c.recordLocks = application.NewRecordLockService(
+		repository.NewRecordLockRepository(c.db.GetDB()),
+		c.recordRepository,
+		c.fieldRepository,
+		c.permissionServiceV2,
+		c.businessEventManager,
+	)
+	c.recordService.SetRecordLockService(c.recordLocks)
+
+	// ✨ 状态流（单选字段的流转规则与审批角色，记录服务写入时检查）
+	c.statusFlows = application.NewStatusFlowService(
+		repository.NewStatusFlowRepository(c.db.GetDB()),
+		c.fieldRepository,
+		c.permissionServiceV2,
+		c.changeFeedService,
+		c.db.GetDB(),
+	)
+	c.recordService.SetStatusFlowService(c.statusFlows)
+
+	// ✨ 记录归档（按策略将历史记录移入冷存储，可显式查询与恢复）
+	if archiveStore, ok := c.snapshotReader.(recordRepo.ArchiveStore); ok {
+		c.recordService.SetArchiveStore(archiveStore)
+		c.recordArchive = application.NewRecordArchiveService(
+			repository.NewRecordArchiveRepository(c.db.GetDB()),
+			archiveStore,
+			c.recordRepository,
+			c.permissionServiceV2,
+			c.cfg.RecordArchive,
+		)
+		c.recordArchive.SetViewQueryCache(c.viewQueryCache)
+	}
+
+	// ✨ 按时间点恢复单条记录或一列（基于记录变更日志，先预览差异）
+	c.pointInTimeRestore = application.NewPointInTimeRestoreService(
+		c.snapshotReader,
+		c.recordService,
+		c.fieldRepository,
+		c.permissionServiceV2,
+		c.cfg.RecordSnapshot,
+	)
+
+	// ✨ 重复记录规则（按计划从记录模板新建记录，支持节假日日历与停机补跑）
+	c.recurrence = application.NewRecurrenceService(
+		repository.NewRecurrenceRepository(c.db.GetDB()),
+		c.tableRepository,
+		repository.NewRecordTemplateRepository(c.db.GetDB()),
+		c.recordTemplate,
+		c.db.GetDB(),
+		c.permissionServiceV2,
+		c.cfg.Recurrence,
+	)
+
+	// ✨ 表健康检查（校验失败、失效关联、公式错误、类型转换遗留值，可定时执行并一键修复）
+	c.tableHealth = application.NewTableHealthService(
+		repository.NewTableHealthRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		c.permissionServiceV2,
+		c.cfg.TableHealth,
+	)
+
+	// ✨ 记录与视图永久链接（解析时重新校验权限，预览供聊天工具展开链接）
+	c.permalinks = application.NewPermalinkService(
+		repository.NewPermalinkRepository(c.db.GetDB()),
+		c.baseRepository,
+		c.tableRepository,
+		c.viewRepository,
+		c.fieldRepository,
+		c.recordService,
+		c.permissionServiceV2,
+		c.cfg.Permalinks,
+	)
+
+	// ✨ 记录提及的反向链接（记录写入时在同一事务中按文本单元格内容重建）
+	c.backlinks = application.NewBacklinkService(
+		repository.NewBacklinkRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.permissionServiceV2,
+		c.privacyService,
+	)
+	c.recordService.SetBacklinkService(c.backlinks)
+
+	// ✨ 记录与视图的 PDF 打印（后台以创建者身份渲染，也作为自动化的生成 PDF 节点）
+	c.printing = application.NewPrintService(
+		repository.NewPrintJobRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.viewRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.attachmentStorage,
+		c.cfg.Printing,
+	)
+	c.scriptActions.SetPrintService(c.printing)
+
+	// ✨ 文档模板（合并域映射到字段，生成的文件写入附件字段，也作为自动化的生成文档节点）
+	c.documents = application.NewDocumentTemplateService(
+		repository.NewDocumentTemplateRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.viewRepository,
+		c.fieldRepository,
+		c.recordRepository,
+		c.recordService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.attachmentRepository,
+		c.attachmentStorage,
+		c.cfg.Documents,
+	)
+	c.documents.SetUploadGuard(c.planService.CheckAttachmentUpload)
+	c.scriptActions.SetDocumentService(c.documents)
+
+	// ✨ 扫码查找记录（按编码精确匹配字段，未找到时按字段的扫码规则新建）
+	c.scans = application.NewScanService(
+		repository.NewScanRuleRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.recordService,
+		c.recordTemplate,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+	)
+
+	// ✨ 移动端离线同步（快照拉取 + 离线修改按字段检测冲突后回放）
+	c.offlineSync = application.NewOfflineSyncService(
+		repository.NewOfflineSyncRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.recordRepository,
+		c.recordService,
+		c.changeFeedService,
+		c.privacyService,
+		c.dbProvider,
+		c.dataDB,
+		c.permissionServiceV2,
+		c.cfg.OfflineSync,
+	)
+	c.offlineSync.SetBatchAuthorizer(c.batchAuthorizer)
 }
 
 // initAttachmentService 初始化附件服务
@@ -400,6 +1455,7 @@ func (c *Container) initAttachmentService() {
 		uploadPath = "./uploads" // 默认值
 	}
 	attachmentStorage := storage.NewLocalStorage(uploadPath, logger.Logger)
+	c.attachmentStorage = attachmentStorage
 
 	// 2. 创建文件验证器
 	fileValidator := storage.NewFileValidator(logger.Logger)
@@ -446,6 +1502,13 @@ func (c *Container) initAttachmentService() {
 		logger.Logger,
 	)
 
+	// 7. 图片派生图（下载地址携带 width/height 等参数时按需生成）
+	imageCfg := c.cfg.ImageProcessing
+	c.attachmentService.SetImageTransformer(
+		storage.NewImageTransformer(imageCfg.MaxSourcePixels, imageCfg.Quality),
+		imageCfg.MaxSourceBytes,
+	)
+
 	logger.Info("✅ 附件服务已初始化")
 }
 
@@ -516,6 +1579,12 @@ func (c *Container) Close() {
 	}
 
 	// 4. 关闭数据库连接
+	for _, conn := range c.regionDBs {
+		conn.Close()
+	}
+	if c.drDB != nil {
+		c.drDB.Close()
+	}
 	if c.db != nil {
 		c.db.Close()
 		logger.Info("✅ 数据库连接已关闭")
@@ -542,6 +1611,16 @@ func (c *Container) DBConnection() *database.Connection {
 	return c.db
 }
 
+// DBProvider 获取数据库提供者
+func (c *Container) DBProvider() database.DBProvider {
+	return c.dbProvider
+}
+
+// RegionRouter 获取数据驻留路由器（未启用数据驻留时为 nil）
+func (c *Container) RegionRouter() *database.RegionRouter {
+	return c.regionRouter
+}
+
 // DB 获取 GORM DB 实例
 func (c *Container) DB() *gorm.DB {
 	return c.db.GetDB()
@@ -616,6 +1695,16 @@ func (c *Container) AuthService() *application.AuthService {
 	return c.authService
 }
 
+// ServiceTokenService 获取服务令牌服务
+func (c *Container) ServiceTokenService() *application.ServiceTokenService {
+	return c.serviceTokenService
+}
+
+// ImpersonationService 获取支持人员代入服务
+func (c *Container) ImpersonationService() *application.ImpersonationService {
+	return c.impersonation
+}
+
 // TokenService 获取Token服务
 func (c *Container) TokenService() *application.TokenService {
 	return c.tokenService
@@ -626,6 +1715,11 @@ func (c *Container) PermissionServiceV2() *application.PermissionServiceV2 {
 	return c.permissionServiceV2
 }
 
+// BatchAuthorizationService 获取批量权限校验服务
+func (c *Container) BatchAuthorizationService() *application.BatchAuthorizationService {
+	return c.batchAuthorizer
+}
+
 // CollaboratorService 获取协作者服务
 func (c *Container) CollaboratorService() *application.CollaboratorService {
 	return c.collaboratorService
@@ -656,6 +1750,21 @@ func (c *Container) RecordService() *application.RecordService {
 	return c.recordService
 }
 
+// PrivacyService 获取隐私（PII检测与脱敏）服务
+func (c *Container) PrivacyService() *application.PrivacyService {
+	return c.privacyService
+}
+
+// GDPRService 获取数据主体请求服务
+func (c *Container) GDPRService() *application.GDPRService {
+	return c.gdprService
+}
+
+// SecurityService 获取工作空间安全策略服务
+func (c *Container) SecurityService() *application.SecurityService {
+	return c.securityService
+}
+
 // ViewService 获取视图服务
 func (c *Container) ViewService() *application.ViewService {
 	return c.viewService
@@ -720,6 +1829,296 @@ func (c *Container) HookService() *application.HookService {
 
 // ==================== 健康检查 ====================
 
+// HealthService 获取健康检查服务
+func (c *Container) HealthService() *application.HealthService {
+	return c.healthService
+}
+
+// QueryStatsService 获取动态表查询统计服务
+func (c *Container) QueryStatsService() *application.QueryStatsService {
+	return c.queryStatsService
+}
+
+// IdempotencyService 获取幂等键服务
+func (c *Container) IdempotencyService() *application.IdempotencyService {
+	return c.idempotencyService
+}
+
+// SchemaMigrationService 获取结构变更编排服务
+func (c *Container) SchemaMigrationService() *application.SchemaMigrationService {
+	return c.schemaMigration
+}
+
+// SchemaImpactService 获取表结构操作影响预览服务
+func (c *Container) SchemaImpactService() *application.SchemaImpactService {
+	return c.schemaImpact
+}
+
+// SchemaSpecService 获取结构描述服务
+func (c *Container) SchemaSpecService() *application.SchemaSpecService {
+	return c.schemaSpecService
+}
+
+// ReferenceService 获取引用追踪服务
+func (c *Container) ReferenceService() *application.ReferenceService {
+	return c.referenceService
+}
+
+// ViewSummaryService 获取视图汇总服务
+func (c *Container) ViewSummaryService() *application.ViewSummaryService {
+	return c.viewSummaryService
+}
+
+// ChartService 获取图表数据服务
+func (c *Container) ChartService() *application.ChartService {
+	return c.chartService
+}
+
+// DashboardService 获取仪表盘服务
+func (c *Container) DashboardService() *application.DashboardService {
+	return c.dashboardService
+}
+
+// OperationService 获取长时操作服务
+func (c *Container) OperationService() *application.OperationService {
+	return c.operationService
+}
+
+// BaseBranchService 获取沙盒分支服务
+func (c *Container) BaseBranchService() *application.BaseBranchService {
+	return c.baseBranchService
+}
+
+// UsageService 获取用量统计服务
+func (c *Container) UsageService() *application.UsageService {
+	return c.usageService
+}
+
+// PlanService 获取套餐服务
+func (c *Container) PlanService() *application.PlanService {
+	return c.planService
+}
+
+// BillingService 获取 Stripe 订阅同步服务
+func (c *Container) BillingService() *application.BillingService {
+	return c.billingService
+}
+
+// AdminConsoleService 获取管理控制台服务
+func (c *Container) AdminConsoleService() *application.AdminConsoleService {
+	return c.adminConsole
+}
+
+// AttachmentScanService 获取附件扫描服务
+func (c *Container) AttachmentScanService() *application.AttachmentScanService {
+	return c.attachmentScan
+}
+
+// AttachmentBlobGCService 获取附件内容回收服务
+func (c *Container) AttachmentBlobGCService() *application.AttachmentBlobGCService {
+	return c.attachmentBlobGC
+}
+
+// ResumableUploadService 获取可续传上传服务
+func (c *Container) ResumableUploadService() *application.ResumableUploadService {
+	return c.resumableUpload
+}
+
+// AIFieldService 获取 AI 字段生成服务
+func (c *Container) AIFieldService() *application.AIFieldService {
+	return c.aiFieldService
+}
+
+// SemanticSearchService 获取记录语义搜索服务
+func (c *Container) SemanticSearchService() *application.SemanticSearchService {
+	return c.semanticSearch
+}
+
+// NLQueryService 获取自然语言查询服务
+func (c *Container) NLQueryService() *application.NLQueryService {
+	return c.nlQuery
+}
+
+// TextExtractionService 获取附件文本提取服务
+func (c *Container) TextExtractionService() *application.TextExtractionService {
+	return c.textExtraction
+}
+
+// RecordTemplateService 获取记录模板服务
+func (c *Container) RecordTemplateService() *application.RecordTemplateService {
+	return c.recordTemplate
+}
+
+// PermalinkService 获取永久链接服务
+func (c *Container) PermalinkService() *application.PermalinkService {
+	return c.permalinks
+}
+
+// PrintService 获取打印服务
+func (c *Container) PrintService() *application.PrintService {
+	return c.printing
+}
+
+// DocumentTemplateService 获取文档模板服务
+func (c *Container) DocumentTemplateService() *application.DocumentTemplateService {
+	return c.documents
+}
+
+// ScanService 获取扫码服务
+func (c *Container) ScanService() *application.ScanService {
+	return c.scans
+}
+
+// OfflineSyncService 获取离线同步服务
+func (c *Container) OfflineSyncService() *application.OfflineSyncService {
+	return c.offlineSync
+}
+
+// BacklinkService 获取反向链接服务
+func (c *Container) BacklinkService() *application.BacklinkService {
+	return c.backlinks
+}
+
+// RecordLockService 获取记录锁服务
+func (c *Container) RecordLockService() *application.RecordLockService {
+	return c.recordLocks
+}
+
+// StatusFlowService 获取状态流服务
+func (c *Container) StatusFlowService() *application.StatusFlowService {
+	return c.statusFlows
+}
+
+// RecordArchiveService 获取记录归档服务
+func (c *Container) RecordArchiveService() *application.RecordArchiveService {
+	return c.recordArchive
+}
+
+// PointInTimeRestoreService 获取按时间点恢复服务
+func (c *Container) PointInTimeRestoreService() *application.PointInTimeRestoreService {
+	return c.pointInTimeRestore
+}
+
+// RecurrenceService 获取重复记录规则服务
+func (c *Container) RecurrenceService() *application.RecurrenceService {
+	return c.recurrence
+}
+
+// TableHealthService 获取表健康检查服务
+func (c *Container) TableHealthService() *application.TableHealthService {
+	return c.tableHealth
+}
+
+// FindReplaceService 获取批量查找替换服务
+func (c *Container) FindReplaceService() *application.FindReplaceService {
+	return c.findReplace
+}
+
+// SyncedTableService 获取同步表服务
+func (c *Container) SyncedTableService() *application.SyncedTableService {
+	return c.syncedTables
+}
+
+// ExternalSyncService 获取外部数据同步服务
+func (c *Container) ExternalSyncService() *application.ExternalSyncService {
+	return c.externalSync
+}
+
+// PageService 获取界面页面服务
+func (c *Container) PageService() *application.PageService {
+	return c.pages
+}
+
+// ViewProjectionService 获取视图展示投影服务
+func (c *Container) ViewProjectionService() *application.ViewProjectionService {
+	return c.viewProjection
+}
+
+// ViewWindowService 获取视图窗口服务
+func (c *Container) ViewWindowService() *application.ViewWindowService {
+	return c.viewWindow
+}
+
+// CellValidationService 获取单元格值批量校验服务
+func (c *Container) CellValidationService() *application.CellValidationService {
+	return c.cellValidation
+}
+
+// ChangeFeedService 获取变更流服务
+func (c *Container) ChangeFeedService() *application.ChangeFeedService {
+	return c.changeFeedService
+}
+
+// ReplicationService 获取复制服务
+func (c *Container) ReplicationService() *application.ReplicationService {
+	return c.replicationService
+}
+
+// WarehouseSyncService 获取数据仓库同步服务
+func (c *Container) WarehouseSyncService() *application.WarehouseSyncService {
+	return c.warehouseSync
+}
+
+// DataExportService 获取数据导出服务
+func (c *Container) DataExportService() *application.DataExportService {
+	return c.dataExport
+}
+
+// DisasterRecoveryService 获取跨区域灾备服务（未启用灾备时为 nil）
+func (c *Container) DisasterRecoveryService() *application.DisasterRecoveryService {
+	return c.disasterRecovery
+}
+
+// WorkspaceLifecycleService 获取工作空间生命周期服务
+func (c *Container) WorkspaceLifecycleService() *application.WorkspaceLifecycleService {
+	return c.workspaceLifecycle
+}
+
+// MaintenanceService 获取维护模式服务
+func (c *Container) MaintenanceService() *application.MaintenanceService {
+	return c.maintenance
+}
+
+// BusinessCalendarService 获取工作日历服务
+func (c *Container) BusinessCalendarService() *application.BusinessCalendarService {
+	return c.businessCalendar
+}
+
+// OnlineMigrationService 获取在线迁移服务
+func (c *Container) OnlineMigrationService() *application.OnlineMigrationService {
+	return c.onlineMigration
+}
+
+// IntegrationService 获取集成平台服务
+func (c *Container) IntegrationService() *application.IntegrationService {
+	return c.integrationService
+}
+
+// ScriptActionService 获取运行脚本动作服务
+func (c *Container) ScriptActionService() *application.ScriptActionService {
+	return c.scriptActions
+}
+
+// WorkflowService 获取工作流服务
+func (c *Container) WorkflowService() *application.WorkflowService {
+	return c.workflowService
+}
+
+// DeliveryMonitorService 获取推送保障服务
+func (c *Container) DeliveryMonitorService() *application.DeliveryMonitorService {
+	return c.deliveryMonitor
+}
+
+// EmbedService 获取嵌入视图服务
+func (c *Container) EmbedService() *application.EmbedService {
+	return c.embedService
+}
+
+// ConfigService 获取配置热更新与功能开关服务
+func (c *Container) ConfigService() *application.ConfigService {
+	return c.configService
+}
+
 // Health 健康检查
 func (c *Container) Health(ctx context.Context) error {
 	// 检查数据库
@@ -744,6 +2143,111 @@ func (c *Container) Health(ctx context.Context) error {
 func (c *Container) StartServices(ctx context.Context) {
 	logger.Info("启动后台服务...")
 
+	// 配置文件监听与远程配置轮询
+	c.configService.Start(ctx)
+
+	// 过期幂等记录清理
+	c.idempotencyService.StartCleanup(ctx)
+
+	// 动态表结构变更后台任务
+	c.schemaMigration.Start(ctx)
+
+	// 长时操作后台执行
+	c.operationService.Start(ctx)
+
+	// 变更流发件箱中继与过期变更清理
+	c.changeFeedService.Start(ctx)
+
+	// 复制目标后台投递
+	c.replicationService.Start(ctx)
+
+	// 数据仓库同步任务后台执行
+	c.warehouseSync.Start(ctx)
+
+	// Base 数据导出任务后台执行
+	c.dataExport.Start(ctx)
+
+	// PDF 打印任务后台执行
+	c.printing.Start(ctx)
+
+	// 文档生成任务后台执行
+	c.documents.Start(ctx)
+
+	// 离线修改登记过期清理
+	c.offlineSync.Start(ctx)
+
+	// 跨区域灾备后台复制
+	if c.disasterRecovery != nil {
+		c.disasterRecovery.Start(ctx)
+	}
+
+	// 到期工作空间后台清除
+	c.workspaceLifecycle.Start(ctx)
+
+	// 维护窗口加载与到期结束
+	c.maintenance.Start(ctx)
+
+	// 元数据表扩展迁移后台执行
+	c.onlineMigration.Start(ctx)
+
+	// 同步表后台增量同步
+	c.syncedTables.Start(ctx)
+
+	// 外部数据同步后台拉取
+	c.externalSync.Start(ctx)
+
+	// 视图展示投影后台追赶与清理
+	c.viewProjection.Start(ctx)
+
+	// 视图查询结果缓存过期清理
+	c.viewQueryCache.Start(ctx)
+
+	// 集成平台 REST Hook 推送
+	c.integrationService.Start(ctx)
+
+	// 继续执行中断的数据主体擦除任务
+	c.gdprService.Start(ctx)
+
+	// 用量汇总与服务令牌调用计数写入
+	c.usageService.Start(ctx)
+	c.billingService.Start(ctx)
+
+	// 管理控制台的功能开关覆盖
+	c.adminConsole.Start(ctx)
+
+	// 附件类型校验与病毒扫描
+	c.attachmentScan.Start(ctx)
+
+	// 无引用附件内容回收
+	c.attachmentBlobGC.Start(ctx)
+
+	// 过期的可续传上传清理
+	c.resumableUpload.Start(ctx)
+
+	// AI 字段生成任务执行与过期任务清理
+	c.aiFieldService.Start(ctx)
+
+	// 语义索引回填与增量向量化
+	c.semanticSearch.Start(ctx)
+
+	// 附件文本提取任务执行与过期任务清理
+	c.textExtraction.Start(ctx)
+
+	// 重复记录规则按计划新建记录（含停机后补跑）
+	c.recurrence.Start(ctx)
+
+	// 表健康检查、定时检查与一键修复任务
+	c.tableHealth.Start(ctx)
+
+	// 按归档策略定期将历史记录移入冷存储
+	if c.recordArchive != nil {
+		c.recordArchive.Start(ctx)
+	}
+
+	// 过期记录变更日志清理（快照读取保留期）
+	c.recordService.StartSnapshotCleanup(ctx)
+	c.recordService.StartCellHistoryCleanup(ctx)
+
 	// 启动后台任务（参考 teable-develop）
 	// - 定时任务
 	// - 消息队列消费者
@@ -899,6 +2403,10 @@ func (c *Container) initShareDB(logger *zap.Logger) {
 
 	// 创建数据库适配器
 	adapter := sharedb.NewPostgresAdapter(c.db.GetDB(), logger, c.recordRepository)
+	if c.privacyService != nil {
+		// 记录快照按连接用户的角色脱敏，与 REST 读取接口一致
+		adapter.(*sharedb.PostgresAdapter).SetSnapshotMasker(c.privacyService.MaskFields)
+	}
 	logger.Info("✅ ShareDB PostgreSQL 适配器已创建")
 
 	// 创建发布订阅服务
@@ -0,0 +1,84 @@
+package adminconsole
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSession(t *testing.T) {
+	now := time.Now()
+	session, plaintext, err := NewSession("usr_admin", "admin@example.com", "10.0.0.1", "curl/8", 8*time.Hour, now)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if !IsConsoleToken(plaintext) {
+		t.Fatalf("plaintext %q should carry the console prefix", plaintext)
+	}
+	if session.TokenHash != Hash(plaintext) || strings.Contains(session.TokenHash, plaintext) {
+		t.Errorf("token hash should be the digest of the plaintext")
+	}
+	if !session.ExpiresAt.Equal(now.Add(8 * time.Hour)) {
+		t.Errorf("unexpected expiry %v", session.ExpiresAt)
+	}
+	if _, _, err := NewSession("usr_admin", "", "", "", 0, now); err == nil {
+		t.Errorf("zero ttl should be rejected")
+	}
+	if IsConsoleToken("lim_abc") || IsConsoleToken("eyJhbGciOi") {
+		t.Errorf("impersonation and JWT tokens are not console tokens")
+	}
+}
+
+func TestSessionActive(t *testing.T) {
+	now := time.Now()
+	session, _, err := NewSession("usr_admin", "", "", "", time.Hour, now)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if !session.Active(now) {
+		t.Errorf("fresh session should be active")
+	}
+	if session.Active(now.Add(time.Hour)) {
+		t.Errorf("session should expire at ExpiresAt")
+	}
+	revoked := now.Add(time.Minute)
+	session.RevokedAt = &revoked
+	if session.Active(now.Add(2 * time.Minute)) {
+		t.Errorf("revoked session should not be active")
+	}
+}
+
+func TestNewFeatureOverride(t *testing.T) {
+	for _, key := range []string{"realtime", "ai.assistant", "beta-export_v2"} {
+		if _, err := NewFeatureOverride(key, true, "usr_admin", time.Now()); err != nil {
+			t.Errorf("key %q should be accepted: %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "has space", "x/y", strings.Repeat("a", 65)} {
+		if _, err := NewFeatureOverride(key, true, "usr_admin", time.Now()); err == nil {
+			t.Errorf("key %q should be rejected", key)
+		}
+	}
+}
+
+func TestGenerateTemporaryPassword(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		password, err := GenerateTemporaryPassword()
+		if err != nil {
+			t.Fatalf("GenerateTemporaryPassword: %v", err)
+		}
+		if len(password) != TemporaryPasswordLength {
+			t.Fatalf("unexpected length %d", len(password))
+		}
+		for _, set := range []string{temporaryLower, temporaryUpper, temporaryDigits, temporarySymbols} {
+			if !strings.ContainsAny(password, set) {
+				t.Fatalf("password %q misses a character from %q", password, set)
+			}
+		}
+		seen[password] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("temporary passwords should not repeat")
+	}
+}
@@ -0,0 +1,209 @@
+package adminconsole
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Prefix 控制台令牌前缀；控制台令牌只能访问 /console 接口，普通接口不接受
+const Prefix = "lac_"
+
+// secretBytes 令牌随机部分的字节数
+const secretBytes = 32
+
+// Session 管理控制台会话 ✨
+// 控制台是独立的认证域：管理员使用邮箱与密码单独登录，获得只能访问控制台接口的令牌；
+// 只保存令牌的 SHA-256 摘要，明文仅在登录时返回一次
+type Session struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Email     string     `json:"email"`
+	TokenHash string     `json:"-"`
+	IPAddress string     `json:"ip_address,omitempty"`
+	UserAgent string     `json:"user_agent,omitempty"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewSession 创建控制台会话，返回会话与令牌明文（明文不会被保存）
+func NewSession(userID, email, ipAddress, userAgent string, ttl time.Duration, now time.Time) (*Session, string, error) {
+	if ttl <= 0 {
+		return nil, "", fmt.Errorf("会话时长必须大于 0")
+	}
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("生成令牌失败: %w", err)
+	}
+	plaintext := Prefix + hex.EncodeToString(buf)
+
+	return &Session{
+		ID:        utils.GenerateIDWithPrefix("acs"),
+		UserID:    userID,
+		Email:     email,
+		TokenHash: Hash(plaintext),
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, plaintext, nil
+}
+
+// IsConsoleToken 判断是否为控制台令牌格式
+func IsConsoleToken(plaintext string) bool {
+	return strings.HasPrefix(plaintext, Prefix)
+}
+
+// Hash 令牌摘要（用于存储与查找）
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active 会话在给定时刻是否可用（未注销且未到期）
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// ==================== 审计 ====================
+
+// 控制台操作的审计动作
+const (
+	ActionLogin             = "console_login"
+	ActionLoginFailed       = "console_login_failed"
+	ActionLogout            = "console_logout"
+	ActionResetPassword     = "console_reset_password"
+	ActionTransferOwnership = "console_transfer_ownership"
+	ActionSetFeature        = "console_set_feature"
+	ActionClearFeature      = "console_clear_feature"
+)
+
+// AuditEntry 控制台操作的审计记录
+type AuditEntry struct {
+	SessionID    string
+	ActorID      string
+	ActorEmail   string
+	Action       string
+	ResourceType string // user / space / feature
+	ResourceID   string
+	Detail       map[string]interface{}
+	IPAddress    string
+	Failed       bool
+	OccurredAt   time.Time
+}
+
+// ==================== 功能开关覆盖 ====================
+
+// featureKeyPattern 功能开关名（与配置文件中的 key 一致）
+var featureKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,64}$`)
+
+// FeatureOverride 控制台对功能开关的覆盖
+// 覆盖优先于配置文件：关闭时对所有主体关闭，开启时对所有主体开启（忽略灰度比例，黑名单仍然生效）
+type FeatureOverride struct {
+	Key       string    `json:"key"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewFeatureOverride 创建功能开关覆盖
+func NewFeatureOverride(key string, enabled bool, userID string, now time.Time) (*FeatureOverride, error) {
+	if !featureKeyPattern.MatchString(key) {
+		return nil, fmt.Errorf("功能开关名只能包含字母、数字、下划线、点与连字符，且不超过 64 个字符")
+	}
+	return &FeatureOverride{Key: key, Enabled: enabled, UpdatedBy: userID, UpdatedAt: now}, nil
+}
+
+// ==================== 检索与统计 ====================
+
+// SpaceFilter 工作空间检索条件
+type SpaceFilter struct {
+	Query  string // 匹配工作空间 ID、名称或所有者邮箱
+	Limit  int
+	Offset int
+}
+
+// SpaceSummary 工作空间概要
+type SpaceSummary struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	OwnerID    string    `json:"owner_id,omitempty"`
+	OwnerEmail string    `json:"owner_email,omitempty"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	Members    int64     `json:"members"`
+	Bases      int64     `json:"bases"`
+	Tables     int64     `json:"tables"`
+}
+
+// SystemStats 实例统计
+type SystemStats struct {
+	Users           int64     `json:"users"`
+	ActiveUsers     int64     `json:"active_users"` // 统计窗口内登录过的用户
+	Admins          int64     `json:"admins"`
+	Spaces          int64     `json:"spaces"`
+	Bases           int64     `json:"bases"`
+	Tables          int64     `json:"tables"`
+	Attachments     int64     `json:"attachments"`
+	AttachmentBytes int64     `json:"attachment_bytes"`
+	ActiveSince     time.Time `json:"active_since"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
+
+// ==================== 临时密码 ====================
+
+// 临时密码的字符集（去掉易混淆的字符）
+const (
+	temporaryLower   = "abcdefghjkmnpqrstuvwxyz"
+	temporaryUpper   = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	temporaryDigits  = "23456789"
+	temporarySymbols = "!@#$%^&*-_"
+)
+
+// TemporaryPasswordLength 重置密码时生成的临时密码长度
+const TemporaryPasswordLength = 16
+
+// GenerateTemporaryPassword 生成包含大小写字母、数字与符号的临时密码
+func GenerateTemporaryPassword() (string, error) {
+	sets := []string{temporaryLower, temporaryUpper, temporaryDigits, temporarySymbols}
+	all := strings.Join(sets, "")
+
+	out := make([]byte, TemporaryPasswordLength)
+	for i := range out {
+		set := all
+		if i < len(sets) {
+			set = sets[i]
+		}
+		c, err := randomChar(set)
+		if err != nil {
+			return "", err
+		}
+		out[i] = c
+	}
+	// 打乱顺序，避免前几位的字符类型固定
+	for i := len(out) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := n.Int64()
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out), nil
+}
+
+func randomChar(set string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(set))))
+	if err != nil {
+		return 0, fmt.Errorf("生成临时密码失败: %w", err)
+	}
+	return set[n.Int64()], nil
+}
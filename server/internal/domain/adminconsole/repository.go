@@ -0,0 +1,40 @@
+package adminconsole
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 管理控制台仓储接口
+type Repository interface {
+	// CreateSession 保存新会话
+	CreateSession(ctx context.Context, session *Session) error
+	// FindSessionByHash 按令牌摘要查找会话（不存在时返回 nil）
+	FindSessionByHash(ctx context.Context, tokenHash string) (*Session, error)
+	// RevokeSession 注销会话
+	RevokeSession(ctx context.Context, sessionID string, at time.Time) error
+
+	// RecordAudit 写入控制台操作的审计日志
+	RecordAudit(ctx context.Context, entry *AuditEntry) error
+	// CountFailedLogins 统计 since 之后同一邮箱或同一来源 IP 的登录失败次数
+	CountFailedLogins(ctx context.Context, email, ip string, since time.Time) (int64, error)
+
+	// ListFeatureOverrides 列出全部功能开关覆盖
+	ListFeatureOverrides(ctx context.Context) ([]*FeatureOverride, error)
+	// SaveFeatureOverride 保存功能开关覆盖（已存在时覆盖）
+	SaveFeatureOverride(ctx context.Context, override *FeatureOverride) error
+	// DeleteFeatureOverride 删除功能开关覆盖（恢复为配置文件的定义）
+	DeleteFeatureOverride(ctx context.Context, key string) error
+}
+
+// Directory 跨工作空间的检索、统计与所有权变更
+type Directory interface {
+	// SearchSpaces 检索工作空间（按创建时间倒序），返回当前页与总数
+	SearchSpaces(ctx context.Context, filter SpaceFilter) ([]*SpaceSummary, int64, error)
+	// Stats 实例统计，activeSince 之后登录过的用户计为活跃用户
+	Stats(ctx context.Context, activeSince time.Time) (*SystemStats, error)
+	// SpaceOwners 工作空间当前的所有者
+	SpaceOwners(ctx context.Context, spaceID string) ([]string, error)
+	// TransferOwnership 将工作空间转让给新所有者，原所有者降为创建者（在同一事务中完成）
+	TransferOwnership(ctx context.Context, spaceID, newOwnerID, actorID string) error
+}
@@ -0,0 +1,130 @@
+package aifield
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReferencesAndRender(t *testing.T) {
+	prompt := "总结 {fldA} 的评价：{ fldB }，再次引用 {fldA}，JSON 示例 {{\"k\": 1}}"
+	refs, err := References(prompt)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if strings.Join(refs, ",") != "fldA,fldB" {
+		t.Errorf("引用 = %v", refs)
+	}
+
+	got, err := RenderPrompt(prompt, map[string]interface{}{
+		"fldA": []interface{}{map[string]interface{}{"id": "rec1", "title": "产品 A"}, "B"},
+		"fldB": 4.5,
+	})
+	if err != nil {
+		t.Fatalf("渲染失败: %v", err)
+	}
+	want := "总结 产品 A, B 的评价：4.5，再次引用 产品 A, B，JSON 示例 {\"k\": 1}"
+	if got != want {
+		t.Errorf("渲染结果 = %q, want %q", got, want)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	for _, prompt := range []string{"未闭合 {fld", "空引用 {}", "多余 }", "嵌套 {a{b}}"} {
+		if err := ValidatePrompt(prompt); err == nil {
+			t.Errorf("ValidatePrompt(%q) 应返回错误", prompt)
+		}
+	}
+	if err := ValidatePrompt("   "); err == nil {
+		t.Error("空提示词应返回错误")
+	}
+	if err := ValidatePrompt(strings.Repeat("字", MaxPromptLength+1)); err == nil {
+		t.Error("超长提示词应返回错误")
+	}
+}
+
+func TestNormalizePrompt(t *testing.T) {
+	ids := map[string]string{"标题": "fldTitle", "fldBody": "fldBody"}
+	got, err := NormalizePrompt("为 {标题} 写摘要：{fldBody} {{原样}}", func(ref string) (string, error) {
+		if id, ok := ids[ref]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("字段 %s 不存在", ref)
+	})
+	if err != nil {
+		t.Fatalf("改写失败: %v", err)
+	}
+	if got != "为 {fldTitle} 写摘要：{fldBody} {{原样}}" {
+		t.Errorf("改写结果 = %q", got)
+	}
+
+	if _, err := NormalizePrompt("{未知}", func(ref string) (string, error) {
+		return "", errors.New("不存在")
+	}); err == nil {
+		t.Error("无法解析的引用应返回错误")
+	}
+}
+
+func TestRenderTruncatesLongValues(t *testing.T) {
+	got, err := RenderPrompt("{f}", map[string]interface{}{"f": strings.Repeat("a", MaxValueLength+10)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len([]rune(got)) != MaxValueLength+1 || !strings.HasSuffix(got, "…") {
+		t.Errorf("长值未截断: %d", len([]rune(got)))
+	}
+}
+
+func TestJobRetryBackoff(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	job := NewJob("spc", "tbl", "fld", "rec", TriggerManual, "usr", now)
+	job.Attempts = 1
+	if !job.Retry(errors.New("timeout"), 3, now) {
+		t.Fatal("未达到最大次数时应重试")
+	}
+	if job.Status != StatusPending || !job.NextRunAt.Equal(now.Add(30*time.Second)) {
+		t.Errorf("重试安排错误: %s %v", job.Status, job.NextRunAt)
+	}
+	job.Attempts = 3
+	if job.Retry(errors.New("timeout"), 3, now) {
+		t.Error("达到最大次数后不应重试")
+	}
+	job.Fail(errors.New("timeout"), now)
+	if !job.Finished() || job.FinishedAt == nil {
+		t.Error("失败后任务应结束")
+	}
+}
+
+func TestQuotaCheck(t *testing.T) {
+	quota := &Quota{MonthlyRequests: 2, MonthlyTokens: 100}
+	usage := &Usage{}
+	if err := quota.Check(usage); err != nil {
+		t.Fatalf("未用量时不应超限: %v", err)
+	}
+	usage.Add(40)
+	usage.Add(70)
+	if err := quota.Check(usage); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("次数用完应超限，得到 %v", err)
+	}
+	if err := (&Quota{}).Check(usage); err != nil {
+		t.Errorf("0 表示不限制，得到 %v", err)
+	}
+	if err := (&Quota{MonthlyTokens: -1}).Validate(); err == nil {
+		t.Error("负数配额应校验失败")
+	}
+	if got := MonthStart(time.Date(2026, 5, 17, 13, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("MonthStart = %v", got)
+	}
+}
+
+func TestPermanentError(t *testing.T) {
+	err := fmt.Errorf("call failed: %w", Permanent(errors.New("invalid api key")))
+	if !IsPermanent(err) {
+		t.Error("包装后的不可重试错误应被识别")
+	}
+	if IsPermanent(errors.New("timeout")) || Permanent(nil) != nil {
+		t.Error("普通错误不应视为不可重试")
+	}
+}
@@ -0,0 +1,114 @@
+package aifield
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Status 生成任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待后台执行
+	StatusRunning   Status = "running"   // 执行中（持有租约）
+	StatusSucceeded Status = "succeeded" // 已写入单元格
+	StatusFailed    Status = "failed"    // 重试耗尽、配额不足或配置无效
+)
+
+// Trigger 生成任务的触发原因
+type Trigger string
+
+const (
+	TriggerCreate        Trigger = "create"         // 新建记录
+	TriggerSourceChanged Trigger = "source_changed" // 提示词引用的字段发生变化
+	TriggerManual        Trigger = "manual"         // 手动重新生成
+)
+
+// Job AI 字段生成任务 ✨
+// 一个任务对应一个单元格（字段 × 记录），执行时读取记录的最新值渲染提示词，
+// 因此同一单元格只需保留一个待执行任务
+type Job struct {
+	ID               string     `json:"id"`
+	SpaceID          string     `json:"space_id"`
+	TableID          string     `json:"table_id"`
+	FieldID          string     `json:"field_id"`
+	RecordID         string     `json:"record_id"`
+	Trigger          Trigger    `json:"trigger"`
+	Status           Status     `json:"status"`
+	Attempts         int        `json:"attempts"`
+	Error            string     `json:"error,omitempty"`
+	Provider         string     `json:"provider,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CompletionTokens int        `json:"completion_tokens"`
+	CreatedBy        string     `json:"created_by"`
+	NextRunAt        time.Time  `json:"next_run_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewJob 创建待执行的生成任务
+func NewJob(spaceID, tableID, fieldID, recordID string, trigger Trigger, createdBy string, now time.Time) *Job {
+	return &Job{
+		ID:        utils.GenerateIDWithPrefix("aij"),
+		SpaceID:   spaceID,
+		TableID:   tableID,
+		FieldID:   fieldID,
+		RecordID:  recordID,
+		Trigger:   trigger,
+		Status:    StatusPending,
+		CreatedBy: createdBy,
+		NextRunAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// TotalTokens 任务消耗的令牌数
+func (j *Job) TotalTokens() int {
+	return j.PromptTokens + j.CompletionTokens
+}
+
+// Succeed 记录生成结果并结束任务
+func (j *Job) Succeed(provider string, result *GenerateResult, now time.Time) {
+	j.Status = StatusSucceeded
+	j.Provider = provider
+	j.Model = result.Model
+	j.PromptTokens = result.PromptTokens
+	j.CompletionTokens = result.CompletionTokens
+	j.Error = ""
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// Fail 以失败结束任务（不再重试）
+func (j *Job) Fail(err error, now time.Time) {
+	j.Status = StatusFailed
+	j.Error = err.Error()
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// Retry 执行失败后安排重试（指数退避，最长 10 分钟）
+// 重试次数耗尽时返回 false，调用方应调用 Fail
+func (j *Job) Retry(err error, maxAttempts int, now time.Time) bool {
+	j.Error = err.Error()
+	j.UpdatedAt = now
+	if j.Attempts >= maxAttempts {
+		return false
+	}
+	backoff := time.Duration(1<<uint(j.Attempts)) * 15 * time.Second
+	if backoff > 10*time.Minute {
+		backoff = 10 * time.Minute
+	}
+	j.Status = StatusPending
+	j.NextRunAt = now.Add(backoff)
+	return true
+}
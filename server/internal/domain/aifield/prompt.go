@@ -0,0 +1,185 @@
+package aifield
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPromptLength 提示词模板的最大长度（字符数）
+	MaxPromptLength = 4000
+	// MaxValueLength 渲染时单个字段值的最大长度（字符数），超出部分截断
+	MaxValueLength = 2000
+)
+
+// segment 模板片段：Ref 非空时为字段引用，否则为字面文本
+type segment struct {
+	Text string
+	Ref  string
+}
+
+// parseTemplate 解析提示词模板
+// {字段名或字段ID} 引用当前记录的字段值，{{ 与 }} 表示字面量花括号
+func parseTemplate(prompt string) ([]segment, error) {
+	var segments []segment
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			segments = append(segments, segment{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(prompt); i++ {
+		c := prompt[i]
+		switch {
+		case c == '{' && i+1 < len(prompt) && prompt[i+1] == '{':
+			text.WriteByte('{')
+			i++
+		case c == '}' && i+1 < len(prompt) && prompt[i+1] == '}':
+			text.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexAny(prompt[i+1:], "{}")
+			if end < 0 || prompt[i+1+end] != '}' {
+				return nil, fmt.Errorf("提示词第 %d 个字符处的字段引用未闭合（字面量花括号请写作 {{ 或 }}）", i+1)
+			}
+			ref := strings.TrimSpace(prompt[i+1 : i+1+end])
+			if ref == "" {
+				return nil, errors.New("提示词包含空的字段引用 {}")
+			}
+			flush()
+			segments = append(segments, segment{Ref: ref})
+			i += end + 1
+		case c == '}':
+			return nil, fmt.Errorf("提示词第 %d 个字符处有多余的 }（字面量花括号请写作 }}）", i+1)
+		default:
+			text.WriteByte(c)
+		}
+	}
+	flush()
+	return segments, nil
+}
+
+// ValidatePrompt 校验提示词模板的长度与语法
+func ValidatePrompt(prompt string) error {
+	if strings.TrimSpace(prompt) == "" {
+		return errors.New("提示词不能为空")
+	}
+	if utf8.RuneCountInString(prompt) > MaxPromptLength {
+		return fmt.Errorf("提示词不能超过 %d 个字符", MaxPromptLength)
+	}
+	_, err := parseTemplate(prompt)
+	return err
+}
+
+// References 提示词引用的字段（按首次出现顺序去重）
+func References(prompt string) ([]string, error) {
+	segments, err := parseTemplate(prompt)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	seen := make(map[string]bool)
+	for _, seg := range segments {
+		if seg.Ref != "" && !seen[seg.Ref] {
+			seen[seg.Ref] = true
+			refs = append(refs, seg.Ref)
+		}
+	}
+	return refs, nil
+}
+
+// NormalizePrompt 将字段引用统一改写为字段ID（字段改名后提示词仍然有效）
+// resolve 根据字段名或字段ID返回字段ID，无法引用时返回错误
+func NormalizePrompt(prompt string, resolve func(ref string) (string, error)) (string, error) {
+	segments, err := parseTemplate(prompt)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.Ref == "" {
+			b.WriteString(escapeBraces(seg.Text))
+			continue
+		}
+		id, err := resolve(seg.Ref)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("{" + id + "}")
+	}
+	return b.String(), nil
+}
+
+// RenderPrompt 用记录的字段值（按字段ID）填充提示词
+func RenderPrompt(prompt string, values map[string]interface{}) (string, error) {
+	segments, err := parseTemplate(prompt)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, seg := range segments {
+		if seg.Ref == "" {
+			b.WriteString(seg.Text)
+			continue
+		}
+		b.WriteString(truncate(FormatValue(values[seg.Ref]), MaxValueLength))
+	}
+	return b.String(), nil
+}
+
+// FormatValue 将单元格值转换为提示词中的文本
+// 选项、关联记录、协作者等对象取其名称或标题，数组以逗号分隔
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case int, int32, int64:
+		return fmt.Sprintf("%d", v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := FormatValue(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	case map[string]interface{}:
+		for _, key := range []string{"title", "name", "text"} {
+			if text, ok := v[key].(string); ok {
+				return text
+			}
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func escapeBraces(text string) string {
+	return strings.NewReplacer("{", "{{", "}", "}}").Replace(text)
+}
+
+func truncate(text string, max int) string {
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	return string([]rune(text)[:max]) + "…"
+}
@@ -0,0 +1,59 @@
+package aifield
+
+import (
+	"context"
+	"errors"
+)
+
+// GenerateRequest 文本生成请求
+type GenerateRequest struct {
+	Model        string
+	SystemPrompt string
+	Prompt       string
+	MaxTokens    int      // 0 表示使用服务商默认值
+	Temperature  *float64 // nil 表示使用服务商默认值
+	JSON         bool     // 要求输出 JSON 对象（提示词中需要说明 JSON 结构）
+}
+
+// GenerateResult 文本生成结果
+type GenerateResult struct {
+	Text             string
+	Model            string // 服务商实际使用的模型
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// TotalTokens 本次调用消耗的令牌数
+func (r *GenerateResult) TotalTokens() int {
+	return r.PromptTokens + r.CompletionTokens
+}
+
+// Provider 大模型服务商（OpenAI、Azure OpenAI、本地 OpenAI 兼容服务等）
+type Provider interface {
+	// Name 服务商名称（与配置中的 ai.providers 键一致）
+	Name() string
+	// Generate 根据提示词生成文本
+	Generate(ctx context.Context, req *GenerateRequest) (*GenerateResult, error)
+}
+
+// permanentError 重试不会成功的错误（如认证失败、模型不存在、请求被拒绝）
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将错误标记为不可重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent 错误是否不可重试
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
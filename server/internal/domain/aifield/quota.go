@@ -0,0 +1,58 @@
+package aifield
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQuotaExceeded 空间本月的 AI 生成配额已用完
+var ErrQuotaExceeded = errors.New("AI 生成配额已用完")
+
+// Quota 空间的 AI 生成配额（按自然月计算，0 表示不限制）
+type Quota struct {
+	SpaceID             string    `json:"space_id"`
+	MonthlyRequests     int64     `json:"monthly_requests"`       // 每月可生成的单元格数
+	MonthlyTokens       int64     `json:"monthly_tokens"`         // 每月可消耗的令牌数
+	MaxTokensPerRequest int       `json:"max_tokens_per_request"` // 单次生成的输出令牌上限
+	UpdatedBy           string    `json:"updated_by,omitempty"`
+	UpdatedTime         time.Time `json:"updated_time"`
+}
+
+// Usage 空间在统计周期内的 AI 生成用量
+type Usage struct {
+	PeriodStart time.Time `json:"period_start"`
+	Requests    int64     `json:"requests"`
+	Tokens      int64     `json:"tokens"`
+}
+
+// MonthStart 时间所在自然月的第一天（UTC 零点）
+func MonthStart(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Validate 校验配额设置
+func (q *Quota) Validate() error {
+	if q.MonthlyRequests < 0 || q.MonthlyTokens < 0 || q.MaxTokensPerRequest < 0 {
+		return errors.New("配额不能为负数")
+	}
+	return nil
+}
+
+// Check 判断按当前用量是否还能再发起一次生成
+func (q *Quota) Check(usage *Usage) error {
+	if q.MonthlyRequests > 0 && usage.Requests >= q.MonthlyRequests {
+		return fmt.Errorf("%w：本月已生成 %d 次，上限 %d 次", ErrQuotaExceeded, usage.Requests, q.MonthlyRequests)
+	}
+	if q.MonthlyTokens > 0 && usage.Tokens >= q.MonthlyTokens {
+		return fmt.Errorf("%w：本月已消耗 %d 令牌，上限 %d 令牌", ErrQuotaExceeded, usage.Tokens, q.MonthlyTokens)
+	}
+	return nil
+}
+
+// Add 累加一次生成的用量
+func (u *Usage) Add(tokens int) {
+	u.Requests++
+	u.Tokens += int64(tokens)
+}
@@ -0,0 +1,30 @@
+package aifield
+
+import (
+	"context"
+	"time"
+)
+
+// Repository AI 字段生成任务与空间配额仓储接口
+type Repository interface {
+	// Enqueue 写入待执行任务（在业务事务中调用时随事务提交）
+	// 同一单元格已有待执行任务时跳过，返回实际写入的任务数
+	Enqueue(ctx context.Context, jobs ...*Job) (int, error)
+	// Claim 领取一批可执行任务并标记为执行中
+	// 可执行：到达执行时间的 pending 任务，或 staleBefore 之前领取且未结束的 running 任务；
+	// 同一单元格同时只执行一个任务
+	Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Job, error)
+	// Save 保存任务状态
+	Save(ctx context.Context, job *Job) error
+	// ListByField 列出字段最近的任务（recordID 非空时只列该记录），按创建时间倒序
+	ListByField(ctx context.Context, fieldID, recordID string, limit int) ([]*Job, error)
+	// DeleteFinishedBefore 删除 before 之前结束的任务
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// MonthlyUsage 统计空间自 since 起调用服务商的次数与令牌数（Provider 非空的已结束任务）
+	MonthlyUsage(ctx context.Context, spaceID string, since time.Time) (*Usage, error)
+	// GetQuota 获取空间配额（未设置时返回 nil）
+	GetQuota(ctx context.Context, spaceID string) (*Quota, error)
+	// SaveQuota 保存空间配额
+	SaveQuota(ctx context.Context, quota *Quota) error
+}
@@ -0,0 +1,46 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Blob 按内容寻址的附件文件（SHA-256），内容相同的附件共用一份存储
+type Blob struct {
+	Hash              string
+	Size              int64
+	MimeType          string
+	RefCount          int        // 引用该内容的附件数
+	UnreferencedSince *time.Time // 引用数降为 0 的时间，回收任务据此判断宽限期
+	CreatedTime       time.Time
+	UpdatedTime       time.Time
+}
+
+// BlobPath 内容在存储中的路径（按哈希前两级分目录，避免单目录文件过多）
+func BlobPath(hash string) string {
+	if len(hash) < 4 {
+		return fmt.Sprintf("blobs/%s", hash)
+	}
+	return fmt.Sprintf("blobs/%s/%s/%s", hash[:2], hash[2:4], hash)
+}
+
+// StoragePath 附件内容在存储中的实际路径
+// 按内容寻址的附件读取共享文件；历史附件仍使用上传路径
+func (a *AttachmentItem) StoragePath() string {
+	if a.ContentHash != "" {
+		return BlobPath(a.ContentHash)
+	}
+	return a.Path
+}
+
+// BlobRepository 内容寻址文件的引用计数仓储
+type BlobRepository interface {
+	// Acquire 增加引用；内容首次出现时创建记录并返回 created=true
+	Acquire(ctx context.Context, hash string, size int64, mimeType string) (created bool, err error)
+	// Release 减少引用；降为 0 时记录时间，等待回收
+	Release(ctx context.Context, hash string) error
+	// CollectUnreferenced 回收 before 之前即已无引用的内容
+	// 逐条锁定并再次确认没有附件引用后调用 remove 删除文件，remove 成功才删除记录；返回回收的记录
+	CollectUnreferenced(ctx context.Context, before time.Time, limit int, remove func(blob *Blob) error) ([]*Blob, error)
+}
@@ -6,21 +6,36 @@ import (
 	"github.com/easyspace-ai/luckdb/server/pkg/utils"
 )
 
+// ScanStatus 附件上传后的处理状态
+type ScanStatus string
+
+const (
+	ScanStatusPending  ScanStatus = "pending"  // 等待类型校验与病毒扫描（隔离中，不可下载）
+	ScanStatusClean    ScanStatus = "clean"    // 校验通过
+	ScanStatusRejected ScanStatus = "rejected" // 不符合空间附件策略（类型、大小或扩展名）
+	ScanStatusInfected ScanStatus = "infected" // 扫描发现病毒
+)
+
 // AttachmentItem 附件项
 type AttachmentItem struct {
-	ID             string    `json:"id"`
-	Name           string    `json:"name"`
-	Path           string    `json:"path"`
-	Token          string    `json:"token"`
-	Size           int64     `json:"size"`
-	MimeType       string    `json:"mimetype"`
-	PresignedURL   *string   `json:"presigned_url,omitempty"`
-	Width          *int      `json:"width,omitempty"`
-	Height         *int      `json:"height,omitempty"`
-	SmallThumbnail *string   `json:"sm_thumbnail_url,omitempty"`
-	LargeThumbnail *string   `json:"lg_thumbnail_url,omitempty"`
-	CreatedTime    time.Time `json:"created_time"`
-	UpdatedTime    time.Time `json:"updated_time"`
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Path           string     `json:"path"`
+	Token          string     `json:"token"`
+	Size           int64      `json:"size"`
+	MimeType       string     `json:"mimetype"`
+	PresignedURL   *string    `json:"presigned_url,omitempty"`
+	Width          *int       `json:"width,omitempty"`
+	Height         *int       `json:"height,omitempty"`
+	SmallThumbnail *string    `json:"sm_thumbnail_url,omitempty"`
+	LargeThumbnail *string    `json:"lg_thumbnail_url,omitempty"`
+	TableID        string     `json:"table_id,omitempty"`
+	ContentHash    string     `json:"content_hash,omitempty"` // 内容 SHA-256，为空表示未按内容寻址存储
+	ScanStatus     ScanStatus `json:"scan_status,omitempty"`
+	ScanDetail     string     `json:"scan_detail,omitempty"` // 拒绝原因或病毒特征名
+	ScannedTime    *time.Time `json:"scanned_time,omitempty"`
+	CreatedTime    time.Time  `json:"created_time"`
+	UpdatedTime    time.Time  `json:"updated_time"`
 }
 
 // NewAttachmentItem 创建新的附件项
@@ -37,6 +52,20 @@ func NewAttachmentItem(name, path, token, mimeType string, size int64) *Attachme
 	}
 }
 
+// Downloadable 是否允许下载（未记录状态的历史附件视为已通过）
+func (a *AttachmentItem) Downloadable() bool {
+	return a.ScanStatus == "" || a.ScanStatus == ScanStatusClean
+}
+
+// SetScanResult 记录处理结果
+func (a *AttachmentItem) SetScanResult(status ScanStatus, detail string) {
+	now := time.Now()
+	a.ScanStatus = status
+	a.ScanDetail = detail
+	a.ScannedTime = &now
+	a.UpdatedTime = now
+}
+
 // SetDimensions 设置图片尺寸
 func (a *AttachmentItem) SetDimensions(width, height int) {
 	a.Width = &width
@@ -0,0 +1,47 @@
+package attachment
+
+import "testing"
+
+func TestStoragePath(t *testing.T) {
+	item := &AttachmentItem{Path: "attachments/tbl/fld/a.txt"}
+	if item.StoragePath() != item.Path {
+		t.Errorf("未按内容寻址的附件应读取上传路径，得到 %s", item.StoragePath())
+	}
+	item.ContentHash = "ab12cd"
+	if got := item.StoragePath(); got != "blobs/ab/12/ab12cd" {
+		t.Errorf("内容路径错误: %s", got)
+	}
+}
+
+func TestParseImageTransform(t *testing.T) {
+	query := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+
+	if transform, err := ParseImageTransform(query(map[string]string{"token": "x"})); err != nil || transform != nil {
+		t.Fatalf("未携带图片参数时应返回 nil，得到 %+v, %v", transform, err)
+	}
+
+	transform, err := ParseImageTransform(query(map[string]string{"width": "200", "rotate": "-90", "format": "jpg"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transform.Width != 200 || transform.Rotate != 270 || transform.Format != ImageFormatJPEG || transform.Fit != ImageFitContain {
+		t.Errorf("解析结果错误: %+v", transform)
+	}
+	if got := transform.DerivativePath("att1", "image/png"); got != "derivatives/att1/w200_h0_contain_r270_q0.jpeg" {
+		t.Errorf("派生图路径错误: %s", got)
+	}
+
+	for _, values := range []map[string]string{
+		{"width": "abc"},
+		{"width": "5000"},
+		{"fit": "cover", "width": "100"},
+		{"rotate": "45"},
+		{"format": "bmp"},
+	} {
+		if _, err := ParseImageTransform(query(values)); err == nil {
+			t.Errorf("%v 应返回错误", values)
+		}
+	}
+}
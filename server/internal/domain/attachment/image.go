@@ -0,0 +1,167 @@
+package attachment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ImageFit 缩放方式
+type ImageFit string
+
+const (
+	ImageFitContain ImageFit = "contain" // 等比缩放到不超过目标尺寸（默认）
+	ImageFitCover   ImageFit = "cover"   // 等比缩放后居中裁剪为目标尺寸
+	ImageFitFill    ImageFit = "fill"    // 拉伸为目标尺寸
+)
+
+// MaxImageDimension 派生图的最大边长
+const MaxImageDimension = 4096
+
+// 派生图支持的输出格式
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatPNG  = "png"
+	ImageFormatGIF  = "gif"
+)
+
+// ImageTransform 图片派生参数（下载地址上的 width/height/fit/rotate/format/quality/strip）
+// 派生图一律去除 EXIF 等元数据，并按 EXIF 方向摆正；只指定 strip 时尽量无损地去除元数据
+type ImageTransform struct {
+	Width   int
+	Height  int
+	Fit     ImageFit
+	Rotate  int    // 顺时针旋转角度：0/90/180/270
+	Format  string // 输出格式，为空时沿用原图格式（无法编码的格式输出为 JPEG）
+	Quality int    // JPEG 质量 1-100，0 表示使用默认值
+	Strip   bool
+}
+
+// ParseImageTransform 解析下载地址上的图片参数；未携带任何图片参数时返回 nil
+func ParseImageTransform(query func(key string) string) (*ImageTransform, error) {
+	t := &ImageTransform{Fit: ImageFitContain}
+	present := false
+
+	ints := []struct {
+		key    string
+		target *int
+	}{{"width", &t.Width}, {"height", &t.Height}, {"rotate", &t.Rotate}, {"quality", &t.Quality}}
+	for _, param := range ints {
+		raw := query(param.key)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s 必须是整数", param.key)
+		}
+		*param.target = value
+		present = true
+	}
+	if raw := query("fit"); raw != "" {
+		t.Fit = ImageFit(strings.ToLower(raw))
+		present = true
+	}
+	if raw := query("format"); raw != "" {
+		t.Format = strings.ToLower(raw)
+		if t.Format == "jpg" {
+			t.Format = ImageFormatJPEG
+		}
+		present = true
+	}
+	if raw := query("strip"); raw != "" {
+		strip, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("strip 必须是布尔值")
+		}
+		t.Strip = strip
+		present = present || strip
+	}
+	if !present {
+		return nil, nil
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Validate 校验图片参数
+func (t *ImageTransform) Validate() error {
+	if t.Width < 0 || t.Height < 0 || t.Width > MaxImageDimension || t.Height > MaxImageDimension {
+		return fmt.Errorf("宽高必须在 0-%d 之间", MaxImageDimension)
+	}
+	switch t.Fit {
+	case ImageFitContain:
+	case ImageFitCover, ImageFitFill:
+		if t.Width == 0 || t.Height == 0 {
+			return fmt.Errorf("fit=%s 需要同时指定宽和高", t.Fit)
+		}
+	default:
+		return fmt.Errorf("不支持的缩放方式: %s", t.Fit)
+	}
+	if t.Rotate%90 != 0 {
+		return fmt.Errorf("旋转角度必须是 90 的倍数")
+	}
+	t.Rotate = ((t.Rotate % 360) + 360) % 360
+	switch t.Format {
+	case "", ImageFormatJPEG, ImageFormatPNG, ImageFormatGIF:
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", t.Format)
+	}
+	if t.Quality < 0 || t.Quality > 100 {
+		return fmt.Errorf("quality 必须在 1-100 之间")
+	}
+	return nil
+}
+
+// StripOnly 是否只去除元数据（不缩放、不旋转、不转换格式）
+func (t *ImageTransform) StripOnly() bool {
+	return t.Width == 0 && t.Height == 0 && t.Rotate == 0 && t.Format == "" && t.Quality == 0
+}
+
+// OutputFormat 输出格式
+func (t *ImageTransform) OutputFormat(sourceMimeType string) string {
+	if t.Format != "" {
+		return t.Format
+	}
+	switch sourceMimeType {
+	case "image/png":
+		return ImageFormatPNG
+	case "image/gif":
+		return ImageFormatGIF
+	default:
+		return ImageFormatJPEG
+	}
+}
+
+// DerivativePath 派生图在存储中的路径（同一附件、同一组参数得到同一路径）
+func (t *ImageTransform) DerivativePath(attachmentID, sourceMimeType string) string {
+	key := fmt.Sprintf("w%d_h%d_%s_r%d_q%d", t.Width, t.Height, t.Fit, t.Rotate, t.Quality)
+	if t.StripOnly() {
+		key = "strip"
+	}
+	return fmt.Sprintf("%s%s.%s", DerivativePrefix(attachmentID), key, t.OutputFormat(sourceMimeType))
+}
+
+// DerivativePrefix 附件全部派生图的路径前缀
+func DerivativePrefix(attachmentID string) string {
+	return fmt.Sprintf("derivatives/%s/", attachmentID)
+}
+
+// ImageContentType 输出格式对应的 Content-Type
+func ImageContentType(format string) string {
+	return "image/" + format
+}
+
+// ImageTransformer 图片处理器
+type ImageTransformer interface {
+	// Transform 按参数生成派生图，返回图片内容与输出格式
+	Transform(source []byte, sourceMimeType string, t *ImageTransform) ([]byte, string, error)
+}
+
+// PrefixDeleter 支持按前缀批量删除的存储（用于清理派生图）
+type PrefixDeleter interface {
+	DeletePrefix(ctx context.Context, prefix string) error
+}
@@ -1,6 +1,9 @@
 package attachment
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Repository 附件仓储接口
 type Repository interface {
@@ -20,6 +23,10 @@ type Repository interface {
 	ListAttachments(ctx context.Context, tableID, fieldID, recordID string) ([]*AttachmentItem, error)
 	// GetAttachmentStats 获取附件统计信息
 	GetAttachmentStats(ctx context.Context, tableID string) (*AttachmentStats, error)
+	// ClaimPendingScan 领取待扫描的附件并设置租约（租约过期前其他实例不会重复领取）
+	ClaimPendingScan(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*AttachmentItem, error)
+	// SaveScanResult 保存处理状态与嗅探出的类型，并释放租约
+	SaveScanResult(ctx context.Context, attachment *AttachmentItem) error
 }
 
 // UploadTokenRepository 上传令牌仓储接口
@@ -1,7 +1,10 @@
 package attachment
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -34,6 +37,16 @@ type Service interface {
 	GetAttachmentStats(ctx context.Context, tableID string) (*AttachmentStats, error)
 	// CleanupExpiredTokens 清理过期令牌
 	CleanupExpiredTokens(ctx context.Context) error
+	// SetUploadListener 设置上传完成回调（附件记录保存后调用，用于触发扫描）
+	SetUploadListener(listener func(attachmentID string))
+	// ReadImage 读取图片派生图（按需生成并缓存到存储）
+	ReadImage(ctx context.Context, path string, transform *ImageTransform) (*ReadResponse, error)
+	// SetImageTransformer 设置图片处理器；maxSourceBytes 限制可处理的原图大小
+	SetImageTransformer(transformer ImageTransformer, maxSourceBytes int64)
+	// SetBlobRepository 启用按内容寻址存储（相同内容的附件共用一份文件）
+	SetBlobRepository(repo BlobRepository)
+	// SetUploadGuard 设置上传前的检查（如套餐附件空间限额）；签名时 size 为 0
+	SetUploadGuard(guard func(ctx context.Context, tableID string, size int64) error)
 }
 
 // service 附件服务实现
@@ -46,6 +59,11 @@ type service struct {
 	config             *AttachmentStorageConfig
 	thumbnailConfig    *ThumbnailConfig
 	logger             *zap.Logger
+	uploadListener     func(attachmentID string)
+	imageTransformer   ImageTransformer
+	imageMaxBytes      int64
+	blobRepo           BlobRepository
+	uploadGuard        func(ctx context.Context, tableID string, size int64) error
 }
 
 // NewService 创建附件服务
@@ -84,6 +102,12 @@ func (s *service) GenerateSignature(ctx context.Context, userID string, req *Sig
 		allowedTypes = s.config.AllowedTypes
 	}
 
+	if s.uploadGuard != nil {
+		if err := s.uploadGuard(ctx, req.TableID, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	// 创建上传令牌
 	uploadToken := NewUploadToken(userID, req.TableID, req.FieldID, req.RecordID, maxSize, allowedTypes)
 
@@ -121,7 +145,7 @@ func (s *service) UploadFile(ctx context.Context, token string, reader io.Reader
 	// 获取上传令牌
 	uploadToken, err := s.tokenRepo.GetUploadToken(ctx, token)
 	if err != nil {
-		if err == errors.ErrNotFound {
+		if errors.Is(err, errors.ErrNotFound) {
 			return errors.ErrBadRequest.WithDetails("Invalid upload token")
 		}
 		s.logger.Error("Failed to get upload token",
@@ -143,6 +167,11 @@ func (s *service) UploadFile(ctx context.Context, token string, reader io.Reader
 	if err := s.validator.ValidateFile(ctx, filename, size, mimeType, uploadToken.AllowedTypes, uploadToken.MaxSize); err != nil {
 		return err
 	}
+	if s.uploadGuard != nil {
+		if err := s.uploadGuard(ctx, uploadToken.TableID, size); err != nil {
+			return err
+		}
+	}
 
 	// 生成文件路径
 	filePath := s.generateFilePath(uploadToken, filename)
@@ -179,7 +208,7 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 	// 获取上传令牌
 	uploadToken, err := s.tokenRepo.GetUploadToken(ctx, token)
 	if err != nil {
-		if err == errors.ErrNotFound {
+		if errors.Is(err, errors.ErrNotFound) {
 			return nil, errors.ErrBadRequest.WithDetails("Invalid upload token")
 		}
 		s.logger.Error("Failed to get upload token",
@@ -248,10 +277,26 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 
 	// 创建附件项
 	attachment := NewAttachmentItem(filename, filePath, token, mimeType, fileSize)
+	// 新附件在类型校验与病毒扫描完成前处于隔离状态
+	attachment.TableID = uploadToken.TableID
+	attachment.ScanStatus = ScanStatusPending
+
+	// 按内容寻址存储，相同内容只保留一份；失败时保留上传路径
+	if s.blobRepo != nil {
+		hash, err := s.storeBlob(ctx, filePath, fileSize, mimeType)
+		if err != nil {
+			s.logger.Warn("Failed to store attachment blob",
+				logger.String("file_path", filePath),
+				logger.ErrorField(err),
+			)
+		} else {
+			attachment.ContentHash = hash
+		}
+	}
 
 	// 如果是图片，生成缩略图
 	if s.thumbnailGenerator != nil && s.thumbnailGenerator.IsSupported(mimeType) {
-		thumbnails, err := s.generateThumbnails(ctx, filePath, attachment.ID)
+		thumbnails, err := s.generateThumbnails(ctx, attachment.StoragePath(), attachment.ID)
 		if err != nil {
 			s.logger.Warn("Failed to generate thumbnails",
 				logger.String("file_path", filePath),
@@ -268,7 +313,7 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 	}
 
 	// 生成预签名URL
-	if presignedURL, err := s.storage.GetURL(ctx, filePath, 24*time.Hour); err == nil {
+	if presignedURL, err := s.storage.GetURL(ctx, attachment.StoragePath(), 24*time.Hour); err == nil {
 		attachment.SetPresignedURL(presignedURL)
 	}
 
@@ -279,6 +324,7 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 			logger.String("attachment_id", attachment.ID),
 			logger.ErrorField(err),
 		)
+		s.releaseBlob(ctx, attachment)
 		return nil, errors.ErrInternalServer.WithDetails("Failed to save attachment")
 	}
 
@@ -290,6 +336,10 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 		)
 	}
 
+	if s.uploadListener != nil {
+		s.uploadListener(attachment.ID)
+	}
+
 	response := &NotifyResponse{
 		Attachment: attachment,
 		Success:    true,
@@ -306,24 +356,17 @@ func (s *service) NotifyUpload(ctx context.Context, token, filename string) (*No
 
 // ReadFile 读取文件
 func (s *service) ReadFile(ctx context.Context, path, token string) (*ReadResponse, error) {
-	// 获取附件信息
-	attachment, err := s.repo.GetAttachmentByPath(ctx, path)
+	attachment, err := s.downloadableAttachment(ctx, path)
 	if err != nil {
-		if err == errors.ErrNotFound {
-			return nil, errors.ErrNotFound.WithDetails("File not found")
-		}
-		s.logger.Error("Failed to get attachment by path",
-			logger.String("path", path),
-			logger.ErrorField(err),
-		)
-		return nil, errors.ErrInternalServer.WithDetails("Failed to get file")
+		return nil, err
 	}
 
 	// 检查文件是否存在
-	exists, err := s.storage.Exists(ctx, path)
+	storagePath := attachment.StoragePath()
+	exists, err := s.storage.Exists(ctx, storagePath)
 	if err != nil {
 		s.logger.Error("Failed to check file existence",
-			logger.String("path", path),
+			logger.String("path", storagePath),
 			logger.ErrorField(err),
 		)
 		return nil, errors.ErrInternalServer.WithDetails("Failed to check file")
@@ -333,10 +376,10 @@ func (s *service) ReadFile(ctx context.Context, path, token string) (*ReadRespon
 	}
 
 	// 读取文件
-	reader, err := s.storage.Download(ctx, path)
+	reader, err := s.storage.Download(ctx, storagePath)
 	if err != nil {
 		s.logger.Error("Failed to download file",
-			logger.String("path", path),
+			logger.String("path", storagePath),
 			logger.ErrorField(err),
 		)
 		return nil, errors.ErrInternalServer.WithDetails("Failed to read file")
@@ -371,12 +414,110 @@ func (s *service) ReadFile(ctx context.Context, path, token string) (*ReadRespon
 	return response, nil
 }
 
+// downloadableAttachment 按路径获取可下载的附件
+func (s *service) downloadableAttachment(ctx context.Context, path string) (*AttachmentItem, error) {
+	attachment, err := s.repo.GetAttachmentByPath(ctx, path)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, errors.ErrNotFound.WithDetails("File not found")
+		}
+		s.logger.Error("Failed to get attachment by path",
+			logger.String("path", path),
+			logger.ErrorField(err),
+		)
+		return nil, errors.ErrInternalServer.WithDetails("Failed to get file")
+	}
+
+	// 隔离中或未通过校验的附件不可下载
+	if !attachment.Downloadable() {
+		return nil, errors.ErrForbidden.WithDetails(fmt.Sprintf("File is quarantined (%s)", attachment.ScanStatus))
+	}
+	return attachment, nil
+}
+
+// ReadImage 读取图片派生图
+// 派生图按参数缓存在 derivatives/<附件ID>/ 下，命中时直接返回；未命中时由原图生成并写回存储
+func (s *service) ReadImage(ctx context.Context, path string, transform *ImageTransform) (*ReadResponse, error) {
+	if s.imageTransformer == nil {
+		return nil, errors.ErrBadRequest.WithDetails("Image processing is not enabled")
+	}
+	attachment, err := s.downloadableAttachment(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if !attachment.IsImage() {
+		return nil, errors.ErrBadRequest.WithDetails("Attachment is not an image")
+	}
+	if s.imageMaxBytes > 0 && attachment.Size > s.imageMaxBytes {
+		return nil, errors.ErrBadRequest.WithDetails("Image is too large to process")
+	}
+
+	derivativePath := transform.DerivativePath(attachment.ID, attachment.MimeType)
+	format := transform.OutputFormat(attachment.MimeType)
+	if data, err := s.readAll(ctx, derivativePath); err == nil {
+		return s.imageResponse(attachment, data, format), nil
+	}
+
+	source, err := s.readAll(ctx, attachment.StoragePath())
+	if err != nil {
+		s.logger.Error("Failed to read source image",
+			logger.String("path", path),
+			logger.ErrorField(err),
+		)
+		return nil, errors.ErrNotFound.WithDetails("File not found")
+	}
+	data, format, err := s.imageTransformer.Transform(source, attachment.MimeType, transform)
+	if err != nil {
+		s.logger.Warn("Failed to transform image",
+			logger.String("path", path),
+			logger.ErrorField(err),
+		)
+		return nil, errors.ErrBadRequest.WithDetails("Failed to process image")
+	}
+
+	// 缓存写入失败不影响本次返回
+	if err := s.storage.Upload(ctx, derivativePath, bytes.NewReader(data), int64(len(data)), ImageContentType(format)); err != nil {
+		s.logger.Warn("Failed to cache image derivative",
+			logger.String("path", derivativePath),
+			logger.ErrorField(err),
+		)
+	}
+	return s.imageResponse(attachment, data, format), nil
+}
+
+// readAll 读取存储中的完整文件
+func (s *service) readAll(ctx context.Context, path string) ([]byte, error) {
+	reader, err := s.storage.Download(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// imageResponse 构造派生图响应
+func (s *service) imageResponse(attachment *AttachmentItem, data []byte, format string) *ReadResponse {
+	contentType := ImageContentType(format)
+	name := strings.TrimSuffix(attachment.Name, filepath.Ext(attachment.Name)) + "." + format
+	return &ReadResponse{
+		Data: data,
+		Headers: map[string]string{
+			"Content-Type":        contentType,
+			"Content-Length":      fmt.Sprintf("%d", len(data)),
+			"Cache-Control":       "public, max-age=31536000",
+			"Content-Disposition": fmt.Sprintf("inline; filename=\"%s\"", name),
+		},
+		MimeType: contentType,
+		Size:     int64(len(data)),
+	}
+}
+
 // DeleteFile 删除文件
 func (s *service) DeleteFile(ctx context.Context, id string) error {
 	// 获取附件信息
 	attachment, err := s.repo.GetAttachmentByID(ctx, id)
 	if err != nil {
-		if err == errors.ErrNotFound {
+		if errors.Is(err, errors.ErrNotFound) {
 			return errors.ErrNotFound.WithDetails("Attachment not found")
 		}
 		s.logger.Error("Failed to get attachment by ID",
@@ -386,14 +527,16 @@ func (s *service) DeleteFile(ctx context.Context, id string) error {
 		return errors.ErrInternalServer.WithDetails("Failed to get attachment")
 	}
 
-	// 删除存储中的文件
-	if err := s.storage.Delete(ctx, attachment.Path); err != nil {
-		s.logger.Error("Failed to delete file from storage",
-			logger.String("id", id),
-			logger.String("path", attachment.Path),
-			logger.ErrorField(err),
-		)
-		// 继续删除数据库记录
+	// 删除存储中的文件（按内容寻址的文件由引用计数回收）
+	if attachment.ContentHash == "" {
+		if err := s.storage.Delete(ctx, attachment.Path); err != nil {
+			s.logger.Error("Failed to delete file from storage",
+				logger.String("id", id),
+				logger.String("path", attachment.Path),
+				logger.ErrorField(err),
+			)
+			// 继续删除数据库记录
+		}
 	}
 
 	// 删除缩略图
@@ -404,6 +547,16 @@ func (s *service) DeleteFile(ctx context.Context, id string) error {
 		s.storage.Delete(ctx, *attachment.LargeThumbnail)
 	}
 
+	// 删除图片派生图
+	if deleter, ok := s.storage.(PrefixDeleter); ok {
+		if err := deleter.DeletePrefix(ctx, DerivativePrefix(attachment.ID)); err != nil {
+			s.logger.Warn("Failed to delete image derivatives",
+				logger.String("id", id),
+				logger.ErrorField(err),
+			)
+		}
+	}
+
 	// 删除数据库记录
 	if err := s.repo.DeleteAttachment(ctx, id); err != nil {
 		s.logger.Error("Failed to delete attachment record",
@@ -412,6 +565,7 @@ func (s *service) DeleteFile(ctx context.Context, id string) error {
 		)
 		return errors.ErrInternalServer.WithDetails("Failed to delete attachment")
 	}
+	s.releaseBlob(ctx, attachment)
 
 	s.logger.Info("File deleted successfully",
 		logger.String("id", id),
@@ -424,7 +578,7 @@ func (s *service) DeleteFile(ctx context.Context, id string) error {
 func (s *service) GetAttachment(ctx context.Context, id string) (*AttachmentItem, error) {
 	attachment, err := s.repo.GetAttachmentByID(ctx, id)
 	if err != nil {
-		if err == errors.ErrNotFound {
+		if errors.Is(err, errors.ErrNotFound) {
 			return nil, errors.ErrNotFound.WithDetails("Attachment not found")
 		}
 		s.logger.Error("Failed to get attachment by ID",
@@ -473,6 +627,100 @@ func (s *service) CleanupExpiredTokens(ctx context.Context) error {
 	return nil
 }
 
+// SetUploadListener 设置上传完成回调
+func (s *service) SetUploadListener(listener func(attachmentID string)) {
+	s.uploadListener = listener
+}
+
+// SetImageTransformer 设置图片处理器
+func (s *service) SetImageTransformer(transformer ImageTransformer, maxSourceBytes int64) {
+	s.imageTransformer = transformer
+	s.imageMaxBytes = maxSourceBytes
+}
+
+// SetBlobRepository 启用按内容寻址存储
+func (s *service) SetBlobRepository(repo BlobRepository) {
+	s.blobRepo = repo
+}
+
+// SetUploadGuard 设置上传前的检查
+func (s *service) SetUploadGuard(guard func(ctx context.Context, tableID string, size int64) error) {
+	s.uploadGuard = guard
+}
+
+// storeBlob 计算上传文件的 SHA-256 并转存到内容路径，返回内容哈希
+// 内容已存在时只增加引用并删除本次上传的副本
+func (s *service) storeBlob(ctx context.Context, uploadPath string, size int64, mimeType string) (string, error) {
+	reader, err := s.storage.Download(ctx, uploadPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, reader)
+	reader.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash upload: %w", err)
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	created, err := s.blobRepo.Acquire(ctx, hash, size, mimeType)
+	if err != nil {
+		return "", err
+	}
+	blobPath := BlobPath(hash)
+	exists := false
+	if !created {
+		// 记录已存在但文件可能在写入前中断，缺失时用本次上传补齐
+		exists, _ = s.storage.Exists(ctx, blobPath)
+	}
+	if !exists {
+		if err := s.copyFile(ctx, uploadPath, blobPath, size, mimeType); err != nil {
+			if releaseErr := s.blobRepo.Release(ctx, hash); releaseErr != nil {
+				s.logger.Warn("Failed to release attachment blob",
+					logger.String("hash", hash),
+					logger.ErrorField(releaseErr),
+				)
+			}
+			return "", err
+		}
+	}
+
+	if err := s.storage.Delete(ctx, uploadPath); err != nil {
+		s.logger.Warn("Failed to delete deduplicated upload",
+			logger.String("path", uploadPath),
+			logger.ErrorField(err),
+		)
+	}
+	return hash, nil
+}
+
+// copyFile 在存储内复制文件
+func (s *service) copyFile(ctx context.Context, from, to string, size int64, mimeType string) error {
+	reader, err := s.storage.Download(ctx, from)
+	if err != nil {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	defer reader.Close()
+	if err := s.storage.Upload(ctx, to, reader, size, mimeType); err != nil {
+		return fmt.Errorf("failed to store blob: %w", err)
+	}
+	return nil
+}
+
+// releaseBlob 释放附件对内容的引用
+func (s *service) releaseBlob(ctx context.Context, attachment *AttachmentItem) {
+	if s.blobRepo == nil || attachment.ContentHash == "" {
+		return
+	}
+	if err := s.blobRepo.Release(ctx, attachment.ContentHash); err != nil {
+		s.logger.Warn("Failed to release attachment blob",
+			logger.String("id", attachment.ID),
+			logger.String("hash", attachment.ContentHash),
+			logger.ErrorField(err),
+		)
+	}
+}
+
 // generateFilePath 生成文件路径
 func (s *service) generateFilePath(token *UploadToken, filename string) string {
 	// 使用 token 的创建时间来生成日期路径，确保同一 token 的所有调用都生成相同的路径
@@ -0,0 +1,107 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// UploadSessionStatus 可续传上传状态
+type UploadSessionStatus string
+
+const (
+	UploadSessionUploading UploadSessionStatus = "uploading" // 接收分片中
+	UploadSessionCompleted UploadSessionStatus = "completed" // 已合并并写入存储，等待 notify
+)
+
+// UploadSession 可续传上传（tus 协议）
+// 每个分片作为单独的对象写入附件存储（多实例共享），Offset 为已持久化的字节数；全部接收后合并写入附件
+type UploadSession struct {
+	ID          string              `json:"id"`
+	Token       string              `json:"-"` // 上传令牌（签名接口返回）
+	UserID      string              `json:"user_id"`
+	Filename    string              `json:"filename"`
+	Length      int64               `json:"length"`
+	Offset      int64               `json:"offset"`
+	Status      UploadSessionStatus `json:"status"`
+	Parts       []int64             `json:"-"` // 已保存分片的结束偏移（第 i 个分片为 [Parts[i-1], Parts[i])）
+	ExpiresAt   time.Time           `json:"expires_at"`
+	CreatedTime time.Time           `json:"created_time"`
+	UpdatedTime time.Time           `json:"updated_time"`
+}
+
+// NewUploadSession 创建可续传上传，过期时间不晚于上传令牌
+func NewUploadSession(token *UploadToken, userID, filename string, length int64, ttl time.Duration) *UploadSession {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	if token.ExpiresAt.Before(expiresAt) {
+		expiresAt = token.ExpiresAt
+	}
+	return &UploadSession{
+		ID:          utils.GenerateNanoID(20),
+		Token:       token.Token,
+		UserID:      userID,
+		Filename:    filename,
+		Length:      length,
+		Status:      UploadSessionUploading,
+		ExpiresAt:   expiresAt,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+}
+
+// Received 是否已接收全部内容
+func (s *UploadSession) Received() bool {
+	return s.Offset >= s.Length
+}
+
+// PartRanges 已保存分片的字节范围 [from, to)
+func (s *UploadSession) PartRanges() [][2]int64 {
+	ranges := make([][2]int64, 0, len(s.Parts))
+	var from int64
+	for _, to := range s.Parts {
+		ranges = append(ranges, [2]int64{from, to})
+		from = to
+	}
+	return ranges
+}
+
+// Remaining 剩余字节数
+func (s *UploadSession) Remaining() int64 {
+	return s.Length - s.Offset
+}
+
+// Progress 上传进度（0-100）
+func (s *UploadSession) Progress() float64 {
+	if s.Length <= 0 {
+		return 100
+	}
+	return float64(s.Offset) * 100 / float64(s.Length)
+}
+
+// IsExpired 是否已过期
+func (s *UploadSession) IsExpired(now time.Time) bool {
+	return now.After(s.ExpiresAt)
+}
+
+// UploadSessionRepository 可续传上传仓储
+type UploadSessionRepository interface {
+	// CreateSession 创建上传
+	CreateSession(ctx context.Context, session *UploadSession) error
+	// GetSession 获取上传（不存在时返回 nil, nil）
+	GetSession(ctx context.Context, id string) (*UploadSession, error)
+	// AcquireLock 占用上传直到 until（未被占用、占用已过期或已由 token 占用时成功，后者用于续期）
+	// 多实例部署时同一上传同时只有一个实例接收分片
+	AcquireLock(ctx context.Context, id, token string, until time.Time) (bool, error)
+	// ReleaseLock 释放 token 持有的占用
+	ReleaseLock(ctx context.Context, id, token string) error
+	// AdvanceOffset 仍由 token 占用且当前偏移为 from 时更新为 to 并记录分片，返回是否更新成功
+	AdvanceOffset(ctx context.Context, id, token string, from, to int64) (bool, error)
+	// MarkCompleted 标记已合并
+	MarkCompleted(ctx context.Context, id string) error
+	// DeleteSession 删除上传
+	DeleteSession(ctx context.Context, id string) error
+	// ListExpired 列出 before 之前过期的上传
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*UploadSession, error)
+}
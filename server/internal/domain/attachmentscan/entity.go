@@ -0,0 +1,192 @@
+package attachmentscan
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// SniffLength 类型嗅探读取的文件头长度
+const SniffLength = 512
+
+// Policy 工作空间附件策略 ✨
+// 在全局上传限制之上生效：上传完成后按嗅探出的实际类型校验，不符合策略的附件被拒绝并保持隔离。
+// ScanRequired 为 true 时，服务端未配置扫描引擎的附件一直处于待扫描状态，不可下载
+type Policy struct {
+	SpaceID           string    `json:"space_id"`
+	MaxFileSizeMB     int       `json:"max_file_size_mb"`   // 0 表示不额外限制
+	AllowedTypes      []string  `json:"allowed_types"`      // 允许的 MIME 类型，支持 image/* 形式；为空表示不限制
+	BlockedExtensions []string  `json:"blocked_extensions"` // 禁止的扩展名，如 .exe
+	ScanRequired      bool      `json:"scan_required"`      // 是否必须经过病毒扫描
+	WebhookURL        string    `json:"webhook_url,omitempty"`
+	WebhookSecret     string    `json:"-"` // 感染事件推送的签名密钥，仅首次设置推送地址时返回
+	UpdatedBy         string    `json:"updated_by"`
+	UpdatedTime       time.Time `json:"updated_time"`
+}
+
+// NewDefaultPolicy 创建空间的默认附件策略（不额外限制，不要求扫描）
+func NewDefaultPolicy(spaceID string) *Policy {
+	return &Policy{
+		SpaceID:           spaceID,
+		AllowedTypes:      []string{},
+		BlockedExtensions: []string{},
+	}
+}
+
+// Validate 校验附件策略，并规范化类型与扩展名（小写、扩展名带前导点）
+// allowPrivate 为 false 时推送地址不能是 localhost 或内网 IP（域名在连接时由 netguard 再次检查）
+func (p *Policy) Validate(allowPrivate bool) error {
+	if p.MaxFileSizeMB < 0 {
+		return fmt.Errorf("文件大小上限不能为负数")
+	}
+
+	types := make([]string, 0, len(p.AllowedTypes))
+	for _, entry := range p.AllowedTypes {
+		value := strings.ToLower(strings.TrimSpace(entry))
+		major, minor, ok := strings.Cut(value, "/")
+		if !ok || major == "" || minor == "" || major == "*" || strings.ContainsAny(value, " ;") {
+			return fmt.Errorf("无效的文件类型: %s", entry)
+		}
+		types = append(types, value)
+	}
+	p.AllowedTypes = types
+
+	extensions := make([]string, 0, len(p.BlockedExtensions))
+	for _, entry := range p.BlockedExtensions {
+		value := strings.ToLower(strings.TrimSpace(entry))
+		if !strings.HasPrefix(value, ".") {
+			value = "." + value
+		}
+		if len(value) < 2 || strings.ContainsAny(value[1:], "./\\ ") {
+			return fmt.Errorf("无效的扩展名: %s", entry)
+		}
+		extensions = append(extensions, value)
+	}
+	p.BlockedExtensions = extensions
+
+	if p.WebhookURL != "" {
+		u, err := url.Parse(p.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("推送地址必须是 http(s) URL")
+		}
+		if !allowPrivate {
+			if err := netguard.CheckURL(u); err != nil {
+				return fmt.Errorf("推送地址不能是内网或回环地址")
+			}
+		}
+	}
+	return nil
+}
+
+// EnsureWebhookSecret 设置推送地址后生成签名密钥，返回是否新生成
+func (p *Policy) EnsureWebhookSecret() bool {
+	if p.WebhookURL == "" || p.WebhookSecret != "" {
+		return false
+	}
+	p.WebhookSecret = utils.GenerateNanoID(32)
+	return true
+}
+
+// Check 按策略校验附件，返回拒绝原因（通过时为空）
+func (p *Policy) Check(name string, size int64, mimeType string) string {
+	if p.MaxFileSizeMB > 0 && size > int64(p.MaxFileSizeMB)<<20 {
+		return fmt.Sprintf("文件大小超过空间上限 %d MB", p.MaxFileSizeMB)
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, blocked := range p.BlockedExtensions {
+		if ext == blocked {
+			return fmt.Sprintf("空间禁止上传 %s 文件", ext)
+		}
+	}
+	if len(p.AllowedTypes) > 0 && !p.allowsType(mimeType) {
+		return fmt.Sprintf("文件类型 %s 不在空间允许的类型中", mimeType)
+	}
+	return ""
+}
+
+func (p *Policy) allowsType(mimeType string) bool {
+	for _, allowed := range p.AllowedTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(mimeType, prefix+"/") {
+				return true
+			}
+		} else if mimeType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// genericTypes 嗅探结果无法说明具体格式的类型（此时以声明类型为准）
+var genericTypes = map[string]bool{
+	"application/octet-stream": true,
+	"text/plain":               true,
+	"application/zip":          true, // docx / xlsx 等 Office 文档
+	"text/xml":                 true, // svg 等 XML 文档
+	"application/xml":          true,
+}
+
+// executableTypes 可执行文件类型
+var executableTypes = map[string]bool{
+	"application/x-msdownload":  true,
+	"application/x-executable":  true,
+	"application/x-mach-binary": true,
+}
+
+// Sniff 根据文件头识别实际类型（不含参数），在标准库识别的基础上补充可执行文件签名
+func Sniff(head []byte) string {
+	switch {
+	case len(head) >= 2 && head[0] == 'M' && head[1] == 'Z':
+		return "application/x-msdownload"
+	case len(head) >= 4 && string(head[:4]) == "\x7fELF":
+		return "application/x-executable"
+	case len(head) >= 4 && (string(head[:4]) == "\xcf\xfa\xed\xfe" || string(head[:4]) == "\xce\xfa\xed\xfe"):
+		return "application/x-mach-binary"
+	}
+	return normalizeType(http.DetectContentType(head))
+}
+
+// Resolve 比较声明类型与嗅探类型，返回应记录的类型；内容与声明不符时返回拒绝原因
+// 嗅探结果较笼统时沿用声明类型；大类不同（如声明 image/png 实为 text/html）或可执行文件伪装为其他类型视为不符
+func Resolve(declared, sniffed string) (string, string) {
+	declared = normalizeType(declared)
+	if sniffed == "" || genericTypes[sniffed] {
+		if declared == "" {
+			return sniffed, ""
+		}
+		return declared, ""
+	}
+	if declared == "" || declared == "application/octet-stream" || declared == sniffed {
+		return sniffed, ""
+	}
+	if executableTypes[sniffed] && !executableTypes[declared] {
+		return sniffed, fmt.Sprintf("文件内容为可执行程序，与声明类型 %s 不符", declared)
+	}
+	if family(declared) != family(sniffed) {
+		return sniffed, fmt.Sprintf("文件内容类型 %s 与声明类型 %s 不符", sniffed, declared)
+	}
+	return sniffed, ""
+}
+
+func normalizeType(value string) string {
+	if value == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(value))
+	}
+	return mediaType
+}
+
+func family(mimeType string) string {
+	major, _, _ := strings.Cut(mimeType, "/")
+	return major
+}
@@ -0,0 +1,106 @@
+package attachmentscan
+
+import "testing"
+
+func TestPolicyValidateNormalizes(t *testing.T) {
+	policy := &Policy{
+		AllowedTypes:      []string{" Image/* ", "application/pdf"},
+		BlockedExtensions: []string{"EXE", ".bat"},
+	}
+	if err := policy.Validate(false); err != nil {
+		t.Fatalf("期望校验通过，得到 %v", err)
+	}
+	if policy.AllowedTypes[0] != "image/*" || policy.BlockedExtensions[0] != ".exe" || policy.BlockedExtensions[1] != ".bat" {
+		t.Errorf("规范化结果错误: %+v", policy)
+	}
+
+	invalid := map[string]*Policy{
+		"负数上限":   {MaxFileSizeMB: -1},
+		"类型缺少斜杠": {AllowedTypes: []string{"image"}},
+		"通配大类":   {AllowedTypes: []string{"*/*"}},
+		"扩展名含点":  {BlockedExtensions: []string{"tar.gz"}},
+		"推送地址":   {WebhookURL: "ftp://example.com"},
+		"回环地址":   {WebhookURL: "http://127.0.0.1:8080/hook"},
+		"元数据地址":  {WebhookURL: "http://169.254.169.254/latest/meta-data"},
+		"内网地址":   {WebhookURL: "https://10.0.0.5/hook"},
+	}
+	for name, p := range invalid {
+		if err := p.Validate(false); err == nil {
+			t.Errorf("%s: 期望校验失败", name)
+		}
+	}
+
+	// 显式允许内网时放行
+	if err := (&Policy{WebhookURL: "http://10.0.0.5/hook"}).Validate(true); err != nil {
+		t.Errorf("允许内网时应校验通过，得到 %v", err)
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{MaxFileSizeMB: 1, AllowedTypes: []string{"image/*", "application/pdf"}, BlockedExtensions: []string{".exe"}}
+	cases := []struct {
+		name     string
+		size     int64
+		mimeType string
+		reject   bool
+	}{
+		{"photo.png", 1024, "image/png", false},
+		{"doc.pdf", 1 << 20, "application/pdf", false},
+		{"big.png", 1<<20 + 1, "image/png", true},
+		{"setup.EXE", 10, "image/png", true},
+		{"page.html", 10, "text/html", true},
+	}
+	for _, c := range cases {
+		if got := policy.Check(c.name, c.size, c.mimeType); (got != "") != c.reject {
+			t.Errorf("%s: 期望拒绝=%v，得到 %q", c.name, c.reject, got)
+		}
+	}
+	if reason := NewDefaultPolicy("spc1").Check("any.bin", 1<<40, "application/octet-stream"); reason != "" {
+		t.Errorf("默认策略不应拒绝: %s", reason)
+	}
+}
+
+func TestSniffAndResolve(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	if got := Sniff(png); got != "image/png" {
+		t.Errorf("PNG 识别错误: %s", got)
+	}
+	if got := Sniff([]byte("MZ\x90\x00")); got != "application/x-msdownload" {
+		t.Errorf("PE 识别错误: %s", got)
+	}
+
+	cases := []struct {
+		declared, sniffed string
+		want              string
+		mismatch          bool
+	}{
+		{"image/png", "image/png", "image/png", false},
+		{"image/jpeg", "image/png", "image/png", false},
+		{"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "application/zip", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", false},
+		{"text/csv; charset=utf-8", "text/plain", "text/csv", false},
+		{"", "application/pdf", "application/pdf", false},
+		{"image/png", "text/html", "text/html", true},
+		{"application/pdf", "application/x-msdownload", "application/x-msdownload", true},
+	}
+	for _, c := range cases {
+		got, reason := Resolve(c.declared, c.sniffed)
+		if got != c.want || (reason != "") != c.mismatch {
+			t.Errorf("Resolve(%q, %q) = %q, %q", c.declared, c.sniffed, got, reason)
+		}
+	}
+}
+
+func TestEnsureWebhookSecret(t *testing.T) {
+	policy := NewDefaultPolicy("spc1")
+	if policy.EnsureWebhookSecret() {
+		t.Error("未设置推送地址时不应生成密钥")
+	}
+	policy.WebhookURL = "https://example.com/hook"
+	if !policy.EnsureWebhookSecret() || policy.WebhookSecret == "" {
+		t.Fatal("期望生成密钥")
+	}
+	secret := policy.WebhookSecret
+	if policy.EnsureWebhookSecret() || policy.WebhookSecret != secret {
+		t.Error("已有密钥时不应重新生成")
+	}
+}
@@ -0,0 +1,11 @@
+package attachmentscan
+
+import "context"
+
+// Repository 工作空间附件策略仓储接口
+type Repository interface {
+	// GetPolicy 获取空间附件策略（未配置时返回 nil）
+	GetPolicy(ctx context.Context, spaceID string) (*Policy, error)
+	// SavePolicy 保存空间附件策略
+	SavePolicy(ctx context.Context, policy *Policy) error
+}
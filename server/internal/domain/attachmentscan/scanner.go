@@ -0,0 +1,20 @@
+package attachmentscan
+
+import (
+	"context"
+	"io"
+)
+
+// Verdict 病毒扫描结果
+type Verdict struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名
+}
+
+// Scanner 病毒扫描引擎（ClamAV / ICAP）
+type Scanner interface {
+	// Name 引擎名称
+	Name() string
+	// Scan 扫描文件内容；引擎不可用或协议错误时返回 error，附件保持待扫描状态稍后重试
+	Scan(ctx context.Context, content io.Reader) (*Verdict, error)
+}
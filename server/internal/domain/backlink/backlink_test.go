@@ -0,0 +1,34 @@
+package backlink
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+)
+
+func TestFromMentions(t *testing.T) {
+	now := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	mentions := []richtext.RecordMention{
+		{TableID: "tbl2", RecordID: "rec2"},
+		{TableID: "tbl1", RecordID: "rec1"}, // 提及自身
+		{TableID: "tbl2", RecordID: "rec2"}, // 重复
+		{TableID: "tbl1", RecordID: "rec3"},
+	}
+	links := FromMentions("tbl1", "rec1", "fld1", mentions, now)
+	if len(links) != 2 || links[0].TargetRecordID != "rec2" || links[1].TargetRecordID != "rec3" {
+		t.Fatalf("期望去重并跳过自身，得到 %+v", links)
+	}
+	if links[0].SourceFieldID != "fld1" || links[0].TargetTableID != "tbl2" || !links[0].UpdatedAt.Equal(now) {
+		t.Errorf("反向链接字段错误: %+v", links[0])
+	}
+
+	many := make([]richtext.RecordMention, MaxMentionsPerCell+10)
+	for i := range many {
+		many[i] = richtext.RecordMention{TableID: "tbl2", RecordID: "rec" + strconv.Itoa(i)}
+	}
+	if got := len(FromMentions("tbl1", "rec1", "fld1", many, now)); got != MaxMentionsPerCell {
+		t.Errorf("期望最多 %d 条，得到 %d", MaxMentionsPerCell, got)
+	}
+}
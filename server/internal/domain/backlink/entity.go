@@ -0,0 +1,49 @@
+package backlink
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+)
+
+// MaxMentionsPerCell 单个单元格中建立反向链接的提及数上限（超出部分不进入索引）
+const MaxMentionsPerCell = 200
+
+// Backlink 记录被提及的位置 ✨
+// 来源记录的长文本或富文本字段中提及了目标记录；同一单元格多次提及同一记录只保留一条
+type Backlink struct {
+	SourceTableID  string    `json:"source_table_id"`
+	SourceRecordID string    `json:"source_record_id"`
+	SourceFieldID  string    `json:"source_field_id"`
+	TargetTableID  string    `json:"target_table_id"`
+	TargetRecordID string    `json:"target_record_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// FromMentions 由单元格中的提及生成反向链接（去重、跳过自身，数量受上限约束）
+func FromMentions(sourceTableID, sourceRecordID, sourceFieldID string, mentions []richtext.RecordMention, now time.Time) []*Backlink {
+	seen := make(map[string]bool, len(mentions))
+	var links []*Backlink
+	for _, m := range mentions {
+		if m.RecordID == sourceRecordID && m.TableID == sourceTableID {
+			continue
+		}
+		key := m.TableID + "/" + m.RecordID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if len(links) >= MaxMentionsPerCell {
+			break
+		}
+		links = append(links, &Backlink{
+			SourceTableID:  sourceTableID,
+			SourceRecordID: sourceRecordID,
+			SourceFieldID:  sourceFieldID,
+			TargetTableID:  m.TableID,
+			TargetRecordID: m.RecordID,
+			UpdatedAt:      now,
+		})
+	}
+	return links
+}
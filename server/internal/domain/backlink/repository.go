@@ -0,0 +1,13 @@
+package backlink
+
+import "context"
+
+// Repository 反向链接仓储接口（上下文中存在事务时随记录写入一起提交）
+type Repository interface {
+	// ReplaceForSource 替换来源记录的全部反向链接
+	ReplaceForSource(ctx context.Context, sourceTableID, sourceRecordID string, links []*Backlink) error
+	// DeleteForSource 删除来源记录的全部反向链接
+	DeleteForSource(ctx context.Context, sourceTableID, sourceRecordID string) error
+	// ListByTarget 列出提及目标记录的位置（按更新时间倒序）
+	ListByTarget(ctx context.Context, targetTableID, targetRecordID string, limit int) ([]*Backlink, error)
+}
@@ -0,0 +1,104 @@
+package basebranch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Status 沙盒分支状态
+type Status string
+
+const (
+	StatusActive    Status = "active"    // 可继续修改并合并回生产
+	StatusDiscarded Status = "discarded" // 已丢弃，沙盒 Base 已删除
+)
+
+// MaxNameLength 分支名称最大长度
+const MaxNameLength = 100
+
+// Branch Base 的沙盒分支 ✨
+// 沙盒是复制了生产 Base 结构的独立 Base，在其中修改结构后按三方合并回生产：
+// BaseSpec 为合并基准（创建分支时或上次合并时沙盒的结构），用于识别生产在此期间的改动并检测冲突
+type Branch struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	SourceBaseID    string     `json:"source_base_id"`  // 生产 Base
+	SandboxBaseID   string     `json:"sandbox_base_id"` // 沙盒 Base
+	Status          Status     `json:"status"`
+	BaseSpec        string     `json:"-"`
+	LastOperationID string     `json:"last_operation_id,omitempty"` // 最近一次合并操作
+	PromotedAt      *time.Time `json:"promoted_at,omitempty"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NewBranch 创建沙盒分支
+func NewBranch(sourceBaseID, sandboxBaseID, name, baseSpec, createdBy string) (*Branch, error) {
+	name = strings.TrimSpace(name)
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if sourceBaseID == "" || sandboxBaseID == "" || sourceBaseID == sandboxBaseID {
+		return nil, fmt.Errorf("生产与沙盒必须是不同的 Base")
+	}
+	now := time.Now()
+	return &Branch{
+		ID:            utils.GenerateIDWithPrefix("bbr"),
+		Name:          name,
+		SourceBaseID:  sourceBaseID,
+		SandboxBaseID: sandboxBaseID,
+		Status:        StatusActive,
+		BaseSpec:      baseSpec,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// ValidateName 校验分支名称
+func ValidateName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("分支名称不能为空")
+	}
+	if len([]rune(name)) > MaxNameLength {
+		return fmt.Errorf("分支名称不能超过 %d 个字符", MaxNameLength)
+	}
+	return nil
+}
+
+// IsActive 分支是否可用
+func (b *Branch) IsActive() bool {
+	return b.Status == StatusActive
+}
+
+// StartPromotion 记录发起的合并操作
+func (b *Branch) StartPromotion(operationID string, at time.Time) error {
+	if !b.IsActive() {
+		return fmt.Errorf("分支已丢弃")
+	}
+	b.LastOperationID = operationID
+	b.UpdatedAt = at
+	return nil
+}
+
+// MarkPromoted 合并完成：以合并时沙盒的结构作为新的合并基准，之后可继续在沙盒中修改再次合并
+func (b *Branch) MarkPromoted(sandboxSpec string, at time.Time) {
+	b.BaseSpec = sandboxSpec
+	b.PromotedAt = &at
+	b.UpdatedAt = at
+}
+
+// Discard 丢弃分支
+func (b *Branch) Discard(at time.Time) error {
+	if !b.IsActive() {
+		return fmt.Errorf("分支已丢弃")
+	}
+	b.Status = StatusDiscarded
+	b.UpdatedAt = at
+	return nil
+}
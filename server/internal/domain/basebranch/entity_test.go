@@ -0,0 +1,45 @@
+package basebranch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBranch(t *testing.T) {
+	branch, err := NewBranch("bse_prod", "bse_sandbox", " 新字段试验 ", "version: 1\n", "usr_1")
+	if err != nil {
+		t.Fatalf("期望创建成功，得到 %v", err)
+	}
+	if branch.Name != "新字段试验" || !branch.IsActive() || branch.BaseSpec == "" {
+		t.Errorf("分支初始状态错误: %+v", branch)
+	}
+
+	if _, err := NewBranch("bse_prod", "bse_sandbox", " ", "", "usr_1"); err == nil {
+		t.Error("空名称应被拒绝")
+	}
+	if _, err := NewBranch("bse_prod", "bse_prod", "x", "", "usr_1"); err == nil {
+		t.Error("生产与沙盒相同应被拒绝")
+	}
+}
+
+func TestBranchLifecycle(t *testing.T) {
+	branch, _ := NewBranch("bse_prod", "bse_sandbox", "x", "base", "usr_1")
+	now := time.Now()
+	if err := branch.StartPromotion("opr_1", now); err != nil || branch.LastOperationID != "opr_1" {
+		t.Fatalf("发起合并失败: %v", err)
+	}
+	branch.MarkPromoted("sandbox", now)
+	if branch.BaseSpec != "sandbox" || branch.PromotedAt == nil {
+		t.Errorf("合并后应更新合并基准: %+v", branch)
+	}
+
+	if err := branch.Discard(now); err != nil || branch.IsActive() {
+		t.Fatalf("丢弃失败: %v", err)
+	}
+	if err := branch.Discard(now); err == nil {
+		t.Error("重复丢弃应报错")
+	}
+	if err := branch.StartPromotion("opr_2", now); err == nil {
+		t.Error("已丢弃的分支不能合并")
+	}
+}
@@ -0,0 +1,15 @@
+package basebranch
+
+import "context"
+
+// Repository 沙盒分支仓储接口
+type Repository interface {
+	// Create 保存新分支
+	Create(ctx context.Context, branch *Branch) error
+	// Update 更新分支
+	Update(ctx context.Context, branch *Branch) error
+	// GetByID 按ID查找分支（不存在时返回 nil）
+	GetByID(ctx context.Context, id string) (*Branch, error)
+	// ListBySource 列出生产 Base 的分支（按创建时间倒序）
+	ListBySource(ctx context.Context, sourceBaseID string) ([]*Branch, error)
+}
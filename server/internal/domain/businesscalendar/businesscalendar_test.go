@@ -0,0 +1,71 @@
+package businesscalendar
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse(DateLayout, s)
+	return t
+}
+
+func TestNewCalendar(t *testing.T) {
+	c, err := NewCalendar("spc_1", []int{6, 0, 6}, []string{" 2024-10-01", "2024-10-01"}, nil, "usr_1", time.Now())
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+	if len(c.Weekends) != 2 || c.Weekends[0] != 0 || len(c.Holidays) != 1 || c.Holidays[0] != "2024-10-01" {
+		t.Errorf("weekends and holidays should be deduplicated and sorted, got %+v", c)
+	}
+
+	if _, err := NewCalendar("spc_1", []int{0, 1, 2, 3, 4, 5, 6}, nil, nil, "usr_1", time.Now()); err == nil {
+		t.Errorf("calendar without workdays should be rejected")
+	}
+	if _, err := NewCalendar("spc_1", []int{7}, nil, nil, "usr_1", time.Now()); err == nil {
+		t.Errorf("invalid weekday should be rejected")
+	}
+	if _, err := NewCalendar("spc_1", nil, []string{"2024/10/01"}, nil, "usr_1", time.Now()); err == nil {
+		t.Errorf("invalid date should be rejected")
+	}
+	if _, err := NewCalendar("spc_1", nil, []string{"2024-10-08"}, []string{"2024-10-08"}, "usr_1", time.Now()); err == nil {
+		t.Errorf("a date cannot be both a holiday and a workday")
+	}
+}
+
+func TestWorkdayMath(t *testing.T) {
+	// 国庆假期 10月1日-7日，9月29日（周日）与10月12日（周六）调休上班
+	c, err := NewCalendar("spc_1", nil,
+		[]string{"2024-10-01", "2024-10-02", "2024-10-03", "2024-10-04", "2024-10-07"},
+		[]string{"2024-09-29", "2024-10-12"}, "usr_1", time.Now())
+	if err != nil {
+		t.Fatalf("NewCalendar: %v", err)
+	}
+
+	if !c.IsWorkday(day("2024-09-29")) || c.IsWorkday(day("2024-10-01")) || c.IsWorkday(day("2024-10-05")) {
+		t.Errorf("unexpected workday classification")
+	}
+
+	if got, ok := c.AddWorkdays(day("2024-09-30"), 1); !ok || !got.Equal(day("2024-10-08")) {
+		t.Errorf("expected the first workday after the holiday, got %v", got)
+	}
+	if got, _ := c.AddWorkdays(day("2024-10-08"), -2); !got.Equal(day("2024-09-29")) {
+		t.Errorf("expected to step back over the holiday, got %v", got)
+	}
+	if got, _ := c.AddWorkdays(day("2024-10-05"), 0); !got.Equal(day("2024-10-05")) {
+		t.Errorf("zero offset should keep the day, got %v", got)
+	}
+
+	if n := c.CountWorkdays(day("2024-09-28"), day("2024-10-12")); n != 7 {
+		t.Errorf("expected 7 workdays, got %d", n)
+	}
+	if n := c.CountWorkdays(day("2024-10-12"), day("2024-09-28")); n != -7 {
+		t.Errorf("reversed range should be negative, got %d", n)
+	}
+}
+
+func TestForTable(t *testing.T) {
+	if c := ForTable(nil, "tbl_1"); len(c.Weekends) != 2 || !c.IsWorkday(day("2024-10-07")) {
+		t.Errorf("missing resolver should fall back to the default calendar, got %+v", c)
+	}
+}
@@ -0,0 +1,25 @@
+package businesscalendar
+
+import "context"
+
+// Resolver 按表查找所属工作空间的工作日历
+type Resolver func(ctx context.Context, tableID string) *Calendar
+
+type resolverKey struct{}
+
+// WithResolver 将工作日历的查找方式写入 context（认证后的中间件写入）
+func WithResolver(ctx context.Context, resolver Resolver) context.Context {
+	return context.WithValue(ctx, resolverKey{}, resolver)
+}
+
+// ForTable 表所属工作空间的工作日历；context 中没有查找方式或查找失败时为默认日历
+func ForTable(ctx context.Context, tableID string) *Calendar {
+	if ctx != nil {
+		if resolve, ok := ctx.Value(resolverKey{}).(Resolver); ok && resolve != nil {
+			if calendar := resolve(ctx, tableID); calendar != nil {
+				return calendar
+			}
+		}
+	}
+	return Default("")
+}
@@ -0,0 +1,200 @@
+package businesscalendar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DateLayout 节假日与调休日期的格式
+	DateLayout = "2006-01-02"
+	// MaxDates 节假日（或调休工作日）的日期数上限
+	MaxDates = 1000
+	// maxScanDays 按工作日推算时最多扫描的天数（防止日历几乎没有工作日时长时间循环）
+	maxScanDays = 3660
+	// maxCountDays 统计工作日数时最多统计的天数（约 100 年）
+	maxCountDays = 36600
+)
+
+// DefaultWeekends 未配置工作日历时的每周休息日
+var DefaultWeekends = []int{int(time.Saturday), int(time.Sunday)}
+
+// Calendar 工作空间的工作日历 ✨
+// 定义每周休息日、节假日与调休上班的日期，供公式 WORKDAY/NETWORKDAYS、记录模板的工作日偏移
+// 与“逾期工作日”过滤使用。未配置的空间使用默认日历（周六、周日休息，无节假日）。
+// 日期按调用方传入时间所在的时区取年月日判断
+type Calendar struct {
+	SpaceID   string    `json:"space_id"`
+	Weekends  []int     `json:"weekends"` // 每周休息日，0 为周日 … 6 为周六
+	Holidays  []string  `json:"holidays"` // 节假日（YYYY-MM-DD），不上班
+	Workdays  []string  `json:"workdays"` // 调休上班的日期，即使是休息日也算工作日
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	once     sync.Once
+	weekends [7]bool
+	holidays map[string]bool
+	workdays map[string]bool
+}
+
+// Default 默认工作日历（周六、周日休息）
+func Default(spaceID string) *Calendar {
+	return &Calendar{
+		SpaceID:  spaceID,
+		Weekends: append([]int(nil), DefaultWeekends...),
+		Holidays: []string{},
+		Workdays: []string{},
+	}
+}
+
+// NewCalendar 创建工作日历，weekends 为 nil 时使用默认休息日
+func NewCalendar(spaceID string, weekends []int, holidays, workdays []string, userID string, now time.Time) (*Calendar, error) {
+	if weekends == nil {
+		weekends = append([]int(nil), DefaultWeekends...)
+	}
+	c := &Calendar{
+		SpaceID:   spaceID,
+		Weekends:  weekends,
+		Holidays:  holidays,
+		Workdays:  workdays,
+		UpdatedBy: userID,
+		UpdatedAt: now,
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate 校验日历，休息日与日期去重并排序
+func (c *Calendar) Validate() error {
+	seen := make(map[int]bool, len(c.Weekends))
+	weekends := make([]int, 0, len(c.Weekends))
+	for _, d := range c.Weekends {
+		if d < 0 || d > 6 {
+			return fmt.Errorf("无效的休息日: %d，取值为 0（周日）到 6（周六）", d)
+		}
+		if !seen[d] {
+			seen[d] = true
+			weekends = append(weekends, d)
+		}
+	}
+	if len(weekends) == 7 {
+		return fmt.Errorf("每周至少需要一个工作日")
+	}
+	sort.Ints(weekends)
+
+	holidays, err := normalizeDates(c.Holidays)
+	if err != nil {
+		return err
+	}
+	workdays, err := normalizeDates(c.Workdays)
+	if err != nil {
+		return err
+	}
+	holidaySet := make(map[string]bool, len(holidays))
+	for _, d := range holidays {
+		holidaySet[d] = true
+	}
+	for _, d := range workdays {
+		if holidaySet[d] {
+			return fmt.Errorf("日期 %s 不能同时是节假日和调休工作日", d)
+		}
+	}
+
+	c.Weekends, c.Holidays, c.Workdays = weekends, holidays, workdays
+	return nil
+}
+
+// IsWorkday 给定时间所在的日期（按其时区）是否为工作日
+func (c *Calendar) IsWorkday(day time.Time) bool {
+	c.index()
+	key := day.Format(DateLayout)
+	if c.workdays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	return !c.weekends[day.Weekday()]
+}
+
+// AddWorkdays 从 day 起前进（n < 0 时后退）n 个工作日，保留时刻；n 为 0 时返回 day 本身。
+// 扫描范围内找不到足够的工作日时返回 false
+func (c *Calendar) AddWorkdays(day time.Time, n int) (time.Time, bool) {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for scanned := 0; n > 0; scanned++ {
+		if scanned >= maxScanDays {
+			return time.Time{}, false
+		}
+		day = day.AddDate(0, 0, step)
+		if c.IsWorkday(day) {
+			n--
+		}
+	}
+	return day, true
+}
+
+// CountWorkdays 统计 start 与 end 之间（含首尾两天）的工作日数；end 早于 start 时结果为负数
+func (c *Calendar) CountWorkdays(start, end time.Time) int {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	count := 0
+	for day, n := start, 0; !day.After(end) && n < maxCountDays; day, n = day.AddDate(0, 0, 1), n+1 {
+		if c.IsWorkday(day) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// index 构建查询用的集合（日历加载后不再修改，可被多个请求共享）
+func (c *Calendar) index() {
+	c.once.Do(func() {
+		for _, d := range c.Weekends {
+			if d >= 0 && d <= 6 {
+				c.weekends[d] = true
+			}
+		}
+		c.holidays = toSet(c.Holidays)
+		c.workdays = toSet(c.Workdays)
+	})
+}
+
+func toSet(dates []string) map[string]bool {
+	set := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		set[d] = true
+	}
+	return set
+}
+
+func normalizeDates(dates []string) ([]string, error) {
+	seen := make(map[string]bool, len(dates))
+	out := make([]string, 0, len(dates))
+	for _, d := range dates {
+		d = strings.TrimSpace(d)
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return nil, fmt.Errorf("无效的日期: %s，格式为 YYYY-MM-DD", d)
+		}
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	if len(out) > MaxDates {
+		return nil, fmt.Errorf("日期不能超过 %d 个", MaxDates)
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,13 @@
+package businesscalendar
+
+import "context"
+
+// Repository 工作日历仓储接口
+type Repository interface {
+	// Get 获取工作空间的工作日历（未配置时返回 nil）
+	Get(ctx context.Context, spaceID string) (*Calendar, error)
+	// Save 保存工作空间的工作日历（已存在时覆盖）
+	Save(ctx context.Context, calendar *Calendar) error
+	// Delete 删除工作空间的工作日历（恢复默认日历）
+	Delete(ctx context.Context, spaceID string) error
+}
@@ -3,6 +3,7 @@ package formula
 import (
 	"fmt"
 
+	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/formula/functions"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/formula/parser"
 
 	"github.com/antlr4-go/antlr/v4"
@@ -15,6 +16,17 @@ func Evaluate(
 	dependencies map[string]interface{},
 	record interface{},
 	timeZone string,
+) (*TypedValue, error) {
+	return EvaluateWithCalendar(expression, dependencies, record, timeZone, nil)
+}
+
+// EvaluateWithCalendar 使用工作日历求值公式表达式（WORKDAY / NETWORKDAYS 等函数据此判断工作日）
+func EvaluateWithCalendar(
+	expression string,
+	dependencies map[string]interface{},
+	record interface{},
+	timeZone string,
+	calendar functions.WorkdayCalendar,
 ) (*TypedValue, error) {
 	// 1. 创建输入流（对齐原版）
 	input := antlr.NewInputStream(expression)
@@ -41,6 +53,7 @@ func Evaluate(
 
 	// 7. 使用访问者模式求值（对齐原版）
 	visitor := NewEvalVisitor(dependencies, record, timeZone)
+	visitor.SetCalendar(calendar)
 	result := visitor.Visit(tree)
 
 	// 8. 类型断言
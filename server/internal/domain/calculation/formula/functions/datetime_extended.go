@@ -930,9 +930,99 @@ func (f *LastModifiedTimeFunc) Eval(params []*TypedValue, context *FormulaContex
 	return NewTypedValue(nil, CellValueTypeNull), nil
 }
 
-// =========== WORKDAY 和 WORKDAY_DIFF 函数 ===========
-// 简化实现（工作日计算较复杂，先实现基本版本）
+// =========== WORKDAY、WORKDAY_DIFF 与 NETWORKDAYS 函数 ===========
+// 按工作日历（工作空间的休息日、节假日与调休）计算，未配置时周六、周日休息；
+// 最后一个可选参数为额外的节假日（逗号分隔的 YYYY-MM-DD 或日期数组）
+
+// maxWorkdayScanDays WORKDAY 最多扫描的天数（防止几乎没有工作日时长时间循环）
+const maxWorkdayScanDays = 3660
+
+// maxWorkdayCountDays WORKDAY_DIFF / NETWORKDAYS 最多统计的天数（约 100 年）
+const maxWorkdayCountDays = 36600
+
+// workdayChecker 结合上下文的工作日历与参数中的额外节假日判断工作日
+func workdayChecker(context *FormulaContext, holidays *TypedValue) func(time.Time) bool {
+	extra := make(map[string]bool)
+	if holidays != nil {
+		for _, d := range holidayDates(holidays.Value) {
+			extra[d] = true
+		}
+	}
+	return func(day time.Time) bool {
+		if extra[day.Format("2006-01-02")] {
+			return false
+		}
+		if context != nil && context.Calendar != nil {
+			return context.Calendar.IsWorkday(day)
+		}
+		return day.Weekday() != time.Saturday && day.Weekday() != time.Sunday
+	}
+}
 
+// holidayDates 解析节假日参数（逗号分隔的字符串、数组或日期时间值），只取日期部分
+func holidayDates(value interface{}) []string {
+	var out []string
+	switch v := value.(type) {
+	case string:
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); len(part) >= 10 {
+				out = append(out, part[:10])
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			out = append(out, holidayDates(item)...)
+		}
+	case []string:
+		for _, item := range v {
+			out = append(out, holidayDates(item)...)
+		}
+	}
+	return out
+}
+
+// workdayLocation 公式上下文的时区（工作日按该时区的日期判断）
+func workdayLocation(context *FormulaContext) *time.Location {
+	if context == nil {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(context.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseWorkdayDate 解析日期参数（RFC3339 或 YYYY-MM-DD），转换到公式时区
+func parseWorkdayDate(param *TypedValue, loc *time.Location) (time.Time, bool) {
+	s := param.AsString()
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// countWorkdays 统计两个日期之间（含首尾两天）的工作日数，end 早于 start 时为负数
+func countWorkdays(start, end time.Time, isWorkday func(time.Time) bool) int {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	count := 0
+	for day, n := start, 0; !day.After(end) && n < maxWorkdayCountDays; day, n = day.AddDate(0, 0, 1), n+1 {
+		if isWorkday(day) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// WorkdayFunc WORKDAY(开始日期, 工作日数, [节假日])：开始日期之后（为负数时之前）第 N 个工作日
 type WorkdayFunc struct {
 	BaseDateTimeFunc
 }
@@ -940,20 +1030,20 @@ type WorkdayFunc struct {
 func NewWorkdayFunc() *WorkdayFunc {
 	return &WorkdayFunc{
 		BaseDateTimeFunc: BaseDateTimeFunc{
-			name: "WORKDAY",
+			name: FuncWorkday,
 			acceptValueType: map[CellValueType]bool{
 				CellValueTypeDateTime: true,
 				CellValueTypeString:   true,
 				CellValueTypeNumber:   true,
 			},
-			acceptMultipleValue: false,
+			acceptMultipleValue: true,
 		},
 	}
 }
 
 func (f *WorkdayFunc) ValidateParams(params []*TypedValue) error {
-	if len(params) < 2 {
-		return fmt.Errorf("%s needs at least 2 params", f.Name())
+	if len(params) < 2 || len(params) > 3 {
+		return fmt.Errorf("%s needs 2 or 3 params", f.Name())
 	}
 	return nil
 }
@@ -966,26 +1056,28 @@ func (f *WorkdayFunc) GetReturnType(params []*TypedValue) (CellValueType, bool,
 }
 
 func (f *WorkdayFunc) Eval(params []*TypedValue, context *FormulaContext) (*TypedValue, error) {
-	dateStr := params[0].AsString()
-	date, err := time.Parse(time.RFC3339, dateStr)
-	if err != nil {
+	date, ok := parseWorkdayDate(params[0], workdayLocation(context))
+	if !ok {
 		return NewTypedValue(nil, CellValueTypeNull), nil
 	}
+	var holidays *TypedValue
+	if len(params) > 2 {
+		holidays = params[2]
+	}
+	isWorkday := workdayChecker(context, holidays)
 
 	days := int(params[1].AsNumber())
-
-	// 简化实现：跳过周末
-	result := date
-	direction := 1
+	step := 1
 	if days < 0 {
-		direction = -1
-		days = -days
+		step, days = -1, -days
 	}
-
-	for days > 0 {
-		result = result.AddDate(0, 0, direction)
-		// 跳过周六周日
-		if result.Weekday() != time.Saturday && result.Weekday() != time.Sunday {
+	result := date
+	for scanned := 0; days > 0; scanned++ {
+		if scanned >= maxWorkdayScanDays {
+			return NewTypedValue(nil, CellValueTypeNull), nil
+		}
+		result = result.AddDate(0, 0, step)
+		if isWorkday(result) {
 			days--
 		}
 	}
@@ -993,6 +1085,7 @@ func (f *WorkdayFunc) Eval(params []*TypedValue, context *FormulaContext) (*Type
 	return NewTypedValue(result.Format(time.RFC3339), CellValueTypeDateTime), nil
 }
 
+// WorkdayDiffFunc WORKDAY_DIFF(开始日期, 结束日期, [节假日])：两个日期之间（含首尾）的工作日数
 type WorkdayDiffFunc struct {
 	BaseDateTimeFunc
 }
@@ -1000,20 +1093,27 @@ type WorkdayDiffFunc struct {
 func NewWorkdayDiffFunc() *WorkdayDiffFunc {
 	return &WorkdayDiffFunc{
 		BaseDateTimeFunc: BaseDateTimeFunc{
-			name: "WORKDAY_DIFF",
+			name: FuncWorkdayDiff,
 			acceptValueType: map[CellValueType]bool{
 				CellValueTypeDateTime: true,
 				CellValueTypeString:   true,
 				CellValueTypeNumber:   true,
 			},
-			acceptMultipleValue: false,
+			acceptMultipleValue: true,
 		},
 	}
 }
 
+// NewNetworkdaysFunc NETWORKDAYS 与 WORKDAY_DIFF 相同（与常见电子表格的函数名保持一致）
+func NewNetworkdaysFunc() *WorkdayDiffFunc {
+	f := NewWorkdayDiffFunc()
+	f.name = FuncNetworkdays
+	return f
+}
+
 func (f *WorkdayDiffFunc) ValidateParams(params []*TypedValue) error {
-	if len(params) < 2 {
-		return fmt.Errorf("%s needs at least 2 params", f.Name())
+	if len(params) < 2 || len(params) > 3 {
+		return fmt.Errorf("%s needs 2 or 3 params", f.Name())
 	}
 	return nil
 }
@@ -1026,46 +1126,21 @@ func (f *WorkdayDiffFunc) GetReturnType(params []*TypedValue) (CellValueType, bo
 }
 
 func (f *WorkdayDiffFunc) Eval(params []*TypedValue, context *FormulaContext) (*TypedValue, error) {
-	date1Str := params[0].AsString()
-	date1, err := time.Parse(time.RFC3339, date1Str)
-	if err != nil {
+	loc := workdayLocation(context)
+	date1, ok := parseWorkdayDate(params[0], loc)
+	if !ok {
 		return NewTypedValue(nil, CellValueTypeNull), nil
 	}
-
-	date2Str := params[1].AsString()
-	date2, err := time.Parse(time.RFC3339, date2Str)
-	if err != nil {
+	date2, ok := parseWorkdayDate(params[1], loc)
+	if !ok {
 		return NewTypedValue(nil, CellValueTypeNull), nil
 	}
-
-	// 简化实现：计算两个日期之间的工作日数量
-	count := 0
-	current := date1
-	direction := 1
-	if date2.Before(date1) {
-		direction = -1
-	}
-
-	for {
-		if direction == 1 && current.After(date2) {
-			break
-		}
-		if direction == -1 && current.Before(date2) {
-			break
-		}
-
-		// 如果是工作日（非周六周日），计数
-		if current.Weekday() != time.Saturday && current.Weekday() != time.Sunday {
-			count++
-		}
-
-		current = current.AddDate(0, 0, direction)
-	}
-
-	if direction == -1 {
-		count = -count
+	var holidays *TypedValue
+	if len(params) > 2 {
+		holidays = params[2]
 	}
 
+	count := countWorkdays(date1, date2, workdayChecker(context, holidays))
 	return NewTypedValue(float64(count), CellValueTypeNumber), nil
 }
 
@@ -1,5 +1,7 @@
 package functions
 
+import "time"
+
 // FormulaFuncType 公式函数类型（对齐原版 FormulaFuncType）
 type FormulaFuncType string
 
@@ -113,6 +115,13 @@ type FormulaContext struct {
 	Record       interface{}            // 当前记录
 	TimeZone     string                 // 时区
 	Dependencies map[string]interface{} // 依赖的字段映射
+	Calendar     WorkdayCalendar        // 工作日历（工作空间配置），为空时周六、周日休息
+}
+
+// WorkdayCalendar 工作日历，WORKDAY / NETWORKDAYS 等函数据此判断工作日
+type WorkdayCalendar interface {
+	// IsWorkday 给定时间所在的日期（按其时区）是否为工作日
+	IsWorkday(day time.Time) bool
 }
 
 // NewFormulaContext 创建公式上下文
@@ -182,6 +191,9 @@ const (
 	FuncDateAdd          = "DATE_ADD"
 	FuncDatetimeFormat   = "DATETIME_FORMAT"
 	FuncDatetimeParse    = "DATETIME_PARSE"
+	FuncWorkday          = "WORKDAY"
+	FuncWorkdayDiff      = "WORKDAY_DIFF"
+	FuncNetworkdays      = "NETWORKDAYS"
 	FuncCreatedTime      = "CREATED_TIME"
 	FuncLastModifiedTime = "LAST_MODIFIED_TIME"
 
@@ -99,6 +99,7 @@ func (r *FunctionRegistry) registerBuiltinFunctions() {
 	r.Register(NewDatetimeParseFunc())
 	r.Register(NewWorkdayFunc())
 	r.Register(NewWorkdayDiffFunc())
+	r.Register(NewNetworkdaysFunc())
 	r.Register(NewCreatedTimeFunc())
 	r.Register(NewLastModifiedTimeFunc())
 
@@ -31,6 +31,7 @@ type EvalVisitor struct {
 	dependencies map[string]interface{}      // 依赖的字段映射
 	record       interface{}                 // 当前记录
 	timeZone     string                      // 时区
+	calendar     functions.WorkdayCalendar   // 工作日历，为空时周六、周日休息
 	funcRegistry *functions.FunctionRegistry // 函数注册表
 }
 
@@ -53,6 +54,11 @@ func NewEvalVisitor(
 	}
 }
 
+// SetCalendar 设置 WORKDAY / NETWORKDAYS 等函数使用的工作日历
+func (v *EvalVisitor) SetCalendar(calendar functions.WorkdayCalendar) {
+	v.calendar = calendar
+}
+
 // Visit 访问节点
 func (v *EvalVisitor) Visit(tree antlr.ParseTree) interface{} {
 	return tree.Accept(v)
@@ -377,6 +383,7 @@ func (v *EvalVisitor) VisitFunctionCall(ctx *parser.FunctionCallContext) interfa
 
 	// 创建函数上下文
 	context := functions.NewFormulaContext(v.record, v.timeZone, v.dependencies)
+	context.Calendar = v.calendar
 
 	// 执行函数
 	result, err := fn.Eval(params, context)
@@ -47,7 +47,7 @@ func (m *MockRecordRepository) FindByID(ctx context.Context, id valueobject.Reco
 	return args.Get(0).(*recordEntity.Record), args.Error(1)
 }
 
-func (m *MockRecordRepository) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID) ([]*recordEntity.Record, error) {
+func (m *MockRecordRepository) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID, fieldIDs ...string) ([]*recordEntity.Record, error) {
 	args := m.Called(ctx, tableID, ids)
 	return args.Get(0).([]*recordEntity.Record), args.Error(1)
 }
@@ -72,6 +72,11 @@ func (m *MockRecordRepository) DeleteByTableAndID(ctx context.Context, tableID s
 	return args.Error(0)
 }
 
+func (m *MockRecordRepository) DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) error {
+	args := m.Called(ctx, tableID, id, version)
+	return args.Error(0)
+}
+
 func (m *MockRecordRepository) Exists(ctx context.Context, id valueobject.RecordID) (bool, error) {
 	args := m.Called(ctx, id)
 	return args.Bool(0), args.Error(1)
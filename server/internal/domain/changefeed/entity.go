@@ -0,0 +1,61 @@
+package changefeed
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// EntityType 变更对象类型
+type EntityType string
+
+const (
+	EntityRecord EntityType = "record" // 记录
+	EntityField  EntityType = "field"  // 字段（表结构）
+	EntityView   EntityType = "view"   // 视图
+
+	// EntityTransition 记录的状态流转（审批流），EntityID 为记录ID，数据为流转详情
+	EntityTransition EntityType = "transition"
+)
+
+// Action 变更动作
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change 变更流中的一条变更 ✨
+// 变更随业务写入一起提交到发件箱（未编号），由后台中继按提交顺序分配全局递增的序号 Seq，
+// 外部同步工具以 Seq 作为游标增量拉取，中断后从上次的游标继续
+type Change struct {
+	ID         string          `json:"id"`
+	Seq        int64           `json:"seq"`
+	BaseID     string          `json:"base_id"`
+	TableID    string          `json:"table_id"`
+	EntityType EntityType      `json:"entity_type"`
+	Action     Action          `json:"action"`
+	EntityID   string          `json:"entity_id"`
+	Data       json.RawMessage `json:"data,omitempty"` // 记录为字段值（删除时为删除前的值），字段与视图为完整定义
+	Version    int64           `json:"version,omitempty"`
+	UserID     string          `json:"user_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// NewChange 创建待编号的变更
+func NewChange(baseID, tableID string, entityType EntityType, action Action, entityID string, data json.RawMessage, userID string) *Change {
+	return &Change{
+		ID:         utils.GenerateIDWithPrefix("chg"),
+		BaseID:     baseID,
+		TableID:    tableID,
+		EntityType: entityType,
+		Action:     action,
+		EntityID:   entityID,
+		Data:       data,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+	}
+}
@@ -0,0 +1,36 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCursorExpired 游标之后的变更已超出保留期被清理，需要重新全量同步
+var ErrCursorExpired = errors.New("change feed cursor expired")
+
+// ListFilter 变更列表过滤条件
+type ListFilter struct {
+	After      int64      // 游标（上次返回的最大序号）
+	TableID    string     // 只返回指定表的变更（可选）
+	EntityType EntityType // 只返回指定对象类型的变更（可选）
+	Action     Action     // 只返回指定动作的变更（可选）
+	Limit      int
+}
+
+// Repository 变更流仓储接口
+type Repository interface {
+	// Append 写入发件箱（上下文中存在事务时与业务数据一起提交）
+	Append(ctx context.Context, changes ...*Change) error
+	// Relay 为已提交的未编号变更按写入顺序分配序号，返回本次编号的数量
+	// 同一时刻只有一个实例执行编号，其余实例直接返回
+	Relay(ctx context.Context, batchSize int) (int, error)
+	// ListAfter 按序号升序列出 Base 下游标之后的变更，游标已过期时返回 ErrCursorExpired
+	ListAfter(ctx context.Context, baseID string, filter ListFilter) ([]*Change, error)
+	// ListLatest 按序号降序列出 Base 下最新的变更（忽略 After）
+	ListLatest(ctx context.Context, baseID string, filter ListFilter) ([]*Change, error)
+	// Head 当前最大序号（全量同步开始前记录，之后从此处增量拉取）
+	Head(ctx context.Context) (int64, error)
+	// PurgeBefore 删除早于 before 的已编号变更（保留最新一条以维持序号连续），返回数量
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,257 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// WidgetType 组件类型
+type WidgetType string
+
+const (
+	WidgetTypeChart  WidgetType = "chart"  // 图表（绑定图表数据接口）
+	WidgetTypeNumber WidgetType = "number" // 数字卡片（无维度的图表查询）
+	WidgetTypeView   WidgetType = "view"   // 视图片段（绑定视图汇总接口）
+)
+
+// ParseWidgetType 解析组件类型
+func ParseWidgetType(value string) (WidgetType, error) {
+	switch t := WidgetType(value); t {
+	case WidgetTypeChart, WidgetTypeNumber, WidgetTypeView:
+		return t, nil
+	default:
+		return "", fmt.Errorf("unsupported widget type: %s", value)
+	}
+}
+
+// LayoutItem 组件在仪表盘网格中的位置
+type LayoutItem struct {
+	WidgetID string `json:"widgetId"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	W        int    `json:"w"`
+	H        int    `json:"h"`
+}
+
+// Dashboard 仪表盘（属于 Base，权限随 Base）
+type Dashboard struct {
+	ID             string       `json:"id"`
+	BaseID         string       `json:"baseId"`
+	Name           string       `json:"name"`
+	Layout         []LayoutItem `json:"layout"`
+	EnableShare    bool         `json:"enableShare"`
+	ShareID        *string      `json:"shareId,omitempty"`
+	CreatedBy      string       `json:"createdBy"`
+	CreatedAt      time.Time    `json:"createdTime"`
+	UpdatedAt      time.Time    `json:"lastModifiedTime"`
+	LastModifiedBy string       `json:"lastModifiedBy,omitempty"`
+}
+
+// NewDashboard 创建仪表盘
+func NewDashboard(baseID, name, createdBy string) (*Dashboard, error) {
+	d := &Dashboard{
+		ID:        utils.GenerateDashboardID(),
+		BaseID:    baseID,
+		Layout:    []LayoutItem{},
+		CreatedBy: createdBy,
+	}
+	if err := d.Rename(name, createdBy); err != nil {
+		return nil, err
+	}
+	d.CreatedAt = d.UpdatedAt
+	return d, nil
+}
+
+// Rename 重命名
+func (d *Dashboard) Rename(name, userID string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("dashboard name is required")
+	}
+	if len([]rune(name)) > 255 {
+		return fmt.Errorf("dashboard name is too long")
+	}
+	d.Name = name
+	d.touch(userID)
+	return nil
+}
+
+// UpdateLayout 替换布局；布局只能引用仪表盘中已有的组件
+func (d *Dashboard) UpdateLayout(layout []LayoutItem, widgetIDs map[string]bool, userID string) error {
+	seen := make(map[string]bool, len(layout))
+	for _, item := range layout {
+		if !widgetIDs[item.WidgetID] {
+			return fmt.Errorf("layout references unknown widget: %s", item.WidgetID)
+		}
+		if seen[item.WidgetID] {
+			return fmt.Errorf("duplicate layout item for widget: %s", item.WidgetID)
+		}
+		if item.X < 0 || item.Y < 0 || item.W <= 0 || item.H <= 0 {
+			return fmt.Errorf("invalid layout size for widget: %s", item.WidgetID)
+		}
+		seen[item.WidgetID] = true
+	}
+	d.Layout = append([]LayoutItem{}, layout...)
+	d.touch(userID)
+	return nil
+}
+
+// PlaceWidget 将新组件放在布局最下方
+func (d *Dashboard) PlaceWidget(widgetID string, w, h int) {
+	bottom := 0
+	for _, item := range d.Layout {
+		if item.Y+item.H > bottom {
+			bottom = item.Y + item.H
+		}
+	}
+	d.Layout = append(d.Layout, LayoutItem{WidgetID: widgetID, X: 0, Y: bottom, W: w, H: h})
+}
+
+// RemoveWidget 从布局中移除组件
+func (d *Dashboard) RemoveWidget(widgetID string) {
+	layout := make([]LayoutItem, 0, len(d.Layout))
+	for _, item := range d.Layout {
+		if item.WidgetID != widgetID {
+			layout = append(layout, item)
+		}
+	}
+	d.Layout = layout
+}
+
+// EnableSharing 开启分享，已开启时返回现有分享ID
+func (d *Dashboard) EnableSharing(userID string) string {
+	if d.EnableShare && d.ShareID != nil {
+		return *d.ShareID
+	}
+	shareID := uuid.New().String()
+	d.ShareID = &shareID
+	d.EnableShare = true
+	d.touch(userID)
+	return shareID
+}
+
+// DisableSharing 关闭分享（保留分享ID，重新开启时链接不变）
+func (d *Dashboard) DisableSharing(userID string) {
+	d.EnableShare = false
+	d.touch(userID)
+}
+
+// Duplicate 复制仪表盘和组件，组件获得新ID，布局随之映射；副本不继承分享状态
+func (d *Dashboard) Duplicate(name string, widgets []*Widget, createdBy string) (*Dashboard, []*Widget, error) {
+	copyDashboard, err := NewDashboard(d.BaseID, name, createdBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	idMap := make(map[string]string, len(widgets))
+	copies := make([]*Widget, 0, len(widgets))
+	for _, w := range widgets {
+		c := w.copyTo(copyDashboard.ID, createdBy)
+		idMap[w.ID] = c.ID
+		copies = append(copies, c)
+	}
+	for _, item := range d.Layout {
+		if newID, ok := idMap[item.WidgetID]; ok {
+			item.WidgetID = newID
+			copyDashboard.Layout = append(copyDashboard.Layout, item)
+		}
+	}
+	return copyDashboard, copies, nil
+}
+
+func (d *Dashboard) touch(userID string) {
+	d.UpdatedAt = time.Now()
+	if userID != "" {
+		d.LastModifiedBy = userID
+	}
+}
+
+// Widget 仪表盘组件
+// Config 按类型保存数据查询：chart / number 为图表查询请求，view 为汇总的字段列表
+type Widget struct {
+	ID          string          `json:"id"`
+	DashboardID string          `json:"dashboardId"`
+	Type        WidgetType      `json:"type"`
+	Name        string          `json:"name"`
+	TableID     string          `json:"tableId,omitempty"`
+	ViewID      string          `json:"viewId,omitempty"`
+	Config      json.RawMessage `json:"config,omitempty"`
+	CreatedBy   string          `json:"createdBy"`
+	CreatedAt   time.Time       `json:"createdTime"`
+	UpdatedAt   time.Time       `json:"lastModifiedTime"`
+}
+
+// NewWidget 创建组件
+func NewWidget(dashboardID string, widgetType WidgetType, name, tableID, viewID string, config json.RawMessage, createdBy string) (*Widget, error) {
+	now := time.Now()
+	w := &Widget{
+		ID:          utils.GenerateIDWithPrefix("dwg"),
+		DashboardID: dashboardID,
+		Type:        widgetType,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := w.Update(name, tableID, viewID, config); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Update 更新组件名称与数据绑定
+func (w *Widget) Update(name, tableID, viewID string, config json.RawMessage) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("widget name is required")
+	}
+	switch w.Type {
+	case WidgetTypeChart, WidgetTypeNumber:
+		if tableID == "" {
+			return fmt.Errorf("%s widget requires a table", w.Type)
+		}
+	case WidgetTypeView:
+		if viewID == "" {
+			return fmt.Errorf("view widget requires a view")
+		}
+	}
+	if len(config) > 0 && !json.Valid(config) {
+		return fmt.Errorf("widget config must be valid JSON")
+	}
+
+	w.Name = name
+	w.TableID = tableID
+	w.ViewID = viewID
+	w.Config = config
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// DefaultSize 新组件的默认网格尺寸
+func (w *Widget) DefaultSize() (int, int) {
+	switch w.Type {
+	case WidgetTypeNumber:
+		return 3, 2
+	case WidgetTypeView:
+		return 6, 4
+	default:
+		return 6, 5
+	}
+}
+
+func (w *Widget) copyTo(dashboardID, createdBy string) *Widget {
+	now := time.Now()
+	c := *w
+	c.ID = utils.GenerateIDWithPrefix("dwg")
+	c.DashboardID = dashboardID
+	c.Config = append(json.RawMessage(nil), w.Config...)
+	c.CreatedBy = createdBy
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return &c
+}
@@ -0,0 +1,80 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDashboardLayout(t *testing.T) {
+	d, err := NewDashboard("bse1", "  销售概览 ", "usr1")
+	if err != nil {
+		t.Fatalf("创建仪表盘失败: %v", err)
+	}
+	if d.Name != "销售概览" {
+		t.Errorf("名称应去除首尾空白，得到 %q", d.Name)
+	}
+
+	d.PlaceWidget("w1", 6, 5)
+	d.PlaceWidget("w2", 3, 2)
+	if got := d.Layout[1]; got.Y != 5 || got.X != 0 {
+		t.Errorf("新组件应放在最下方，得到 %+v", got)
+	}
+
+	widgets := map[string]bool{"w1": true, "w2": true}
+	if err := d.UpdateLayout([]LayoutItem{{WidgetID: "w3", W: 1, H: 1}}, widgets, "usr1"); err == nil {
+		t.Error("布局引用不存在的组件应报错")
+	}
+	if err := d.UpdateLayout([]LayoutItem{{WidgetID: "w1", W: 1, H: 1}, {WidgetID: "w1", W: 2, H: 2}}, widgets, "usr1"); err == nil {
+		t.Error("同一组件重复出现在布局中应报错")
+	}
+	if err := d.UpdateLayout([]LayoutItem{{WidgetID: "w1", W: 0, H: 1}}, widgets, "usr1"); err == nil {
+		t.Error("组件尺寸为 0 应报错")
+	}
+
+	d.RemoveWidget("w1")
+	if len(d.Layout) != 1 || d.Layout[0].WidgetID != "w2" {
+		t.Errorf("移除组件后布局应只剩 w2，得到 %+v", d.Layout)
+	}
+}
+
+func TestDashboardDuplicate(t *testing.T) {
+	d, _ := NewDashboard("bse1", "看板", "usr1")
+	chart, err := NewWidget(d.ID, WidgetTypeChart, "按月", "tbl1", "", json.RawMessage(`{"dimensions":[]}`), "usr1")
+	if err != nil {
+		t.Fatalf("创建组件失败: %v", err)
+	}
+	d.PlaceWidget(chart.ID, 6, 5)
+	d.EnableSharing("usr1")
+
+	copyDashboard, copies, err := d.Duplicate("看板 副本", []*Widget{chart}, "usr2")
+	if err != nil {
+		t.Fatalf("复制仪表盘失败: %v", err)
+	}
+	if copyDashboard.ID == d.ID || copyDashboard.BaseID != "bse1" || copyDashboard.CreatedBy != "usr2" {
+		t.Errorf("副本应有新ID并属于同一Base，得到 %+v", copyDashboard)
+	}
+	if copyDashboard.EnableShare || copyDashboard.ShareID != nil {
+		t.Error("副本不应继承分享状态")
+	}
+	if len(copies) != 1 || copies[0].ID == chart.ID || copies[0].DashboardID != copyDashboard.ID {
+		t.Fatalf("组件应复制为属于副本的新组件，得到 %+v", copies)
+	}
+	if len(copyDashboard.Layout) != 1 || copyDashboard.Layout[0].WidgetID != copies[0].ID {
+		t.Errorf("布局应映射到新组件ID，得到 %+v", copyDashboard.Layout)
+	}
+}
+
+func TestWidgetBinding(t *testing.T) {
+	if _, err := NewWidget("dsb1", WidgetTypeChart, "图表", "", "", nil, "usr1"); err == nil {
+		t.Error("图表组件缺少表格应报错")
+	}
+	if _, err := NewWidget("dsb1", WidgetTypeView, "视图", "tbl1", "", nil, "usr1"); err == nil {
+		t.Error("视图组件缺少视图应报错")
+	}
+	if _, err := NewWidget("dsb1", WidgetTypeNumber, "总数", "tbl1", "", json.RawMessage(`{`), "usr1"); err == nil {
+		t.Error("配置不是合法 JSON 应报错")
+	}
+	if _, err := ParseWidgetType("table"); err == nil {
+		t.Error("未知组件类型应报错")
+	}
+}
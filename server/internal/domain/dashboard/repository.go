@@ -0,0 +1,28 @@
+package dashboard
+
+import "context"
+
+// Repository 仪表盘仓储接口
+type Repository interface {
+	// Save 保存仪表盘（新增或更新）
+	Save(ctx context.Context, d *Dashboard) error
+	// SaveWithWidgets 在同一事务中保存仪表盘和组件（新增组件、复制仪表盘时布局与组件需一致）
+	SaveWithWidgets(ctx context.Context, d *Dashboard, widgets []*Widget) error
+	// FindByID 获取仪表盘（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Dashboard, error)
+	// FindByShareID 通过分享ID获取已开启分享的仪表盘（不存在时返回 nil）
+	FindByShareID(ctx context.Context, shareID string) (*Dashboard, error)
+	// ListByBase 列出 Base 下的仪表盘（按创建时间）
+	ListByBase(ctx context.Context, baseID string) ([]*Dashboard, error)
+	// Delete 删除仪表盘及其组件
+	Delete(ctx context.Context, id string) error
+
+	// SaveWidget 保存组件（新增或更新）
+	SaveWidget(ctx context.Context, w *Widget) error
+	// FindWidget 获取组件（不存在时返回 nil）
+	FindWidget(ctx context.Context, id string) (*Widget, error)
+	// ListWidgets 列出仪表盘的组件
+	ListWidgets(ctx context.Context, dashboardID string) ([]*Widget, error)
+	// DeleteWidget 删除组件
+	DeleteWidget(ctx context.Context, id string) error
+}
@@ -0,0 +1,53 @@
+package dataexport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportValidate(t *testing.T) {
+	now := time.Now()
+	export := NewExport("bse1", []string{"tbl1", "tbl2"}, "usr1", now)
+	if err := export.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if !strings.HasPrefix(export.ID, "dex") || export.Status != StatusPending || export.Format != FormatParquet {
+		t.Errorf("导出任务 = %+v", export)
+	}
+	if got := export.DataPath("tbl1"); got != "exports/bse1/"+export.ID+"/tbl1.parquet" {
+		t.Errorf("数据路径 = %s", got)
+	}
+
+	invalid := []*Export{
+		NewExport("bse1", nil, "usr1", now),
+		NewExport("bse1", []string{"tbl1", "tbl1"}, "usr1", now),
+		NewExport("bse1", make([]string, MaxTables+1), "usr1", now),
+	}
+	csv := NewExport("bse1", []string{"tbl1"}, "usr1", now)
+	csv.Format = "csv"
+	invalid = append(invalid, csv)
+	for i, e := range invalid {
+		if err := e.Validate(); err == nil {
+			t.Errorf("任务 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestExportFinish(t *testing.T) {
+	now := time.Now()
+	manifest := &Manifest{Format: ManifestFormat}
+
+	ok := NewExport("bse1", []string{"tbl1"}, "usr1", now)
+	ok.Finish(manifest, nil, now)
+	if ok.Status != StatusCompleted || ok.Manifest != manifest || !ok.Finished() {
+		t.Errorf("成功的任务 = %+v", ok)
+	}
+
+	failed := NewExport("bse1", []string{"tbl1"}, "usr1", now)
+	failed.Finish(manifest, errors.New("upload failed"), now)
+	if failed.Status != StatusFailed || failed.Manifest != nil || failed.Error != "upload failed" {
+		t.Errorf("失败的任务 = %+v", failed)
+	}
+}
@@ -0,0 +1,141 @@
+package dataexport
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// FormatParquet Parquet 列式导出
+const FormatParquet = "parquet"
+
+// ManifestFormat 清单文件的格式标识
+const ManifestFormat = "luckdb-parquet-export/v1"
+
+// MaxTables 单次导出的表数上限
+const MaxTables = 200
+
+// Status 导出任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Export Base 数据导出任务 ✨
+// 后台逐表流式读取记录写为 Parquet 文件（列类型由字段类型决定），上传到对象存储，
+// 最后写入描述全部文件与列的清单（manifest.json），供 Spark / DuckDB 等直接加载
+type Export struct {
+	ID         string     `json:"id"`
+	BaseID     string     `json:"base_id"`
+	TableIDs   []string   `json:"table_ids"`
+	Format     string     `json:"format"`
+	Status     Status     `json:"status"`
+	Manifest   *Manifest  `json:"manifest,omitempty"` // 完成后写入
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Manifest 导出清单：与数据文件一起写入对象存储
+type Manifest struct {
+	Format    string          `json:"format"`
+	ExportID  string          `json:"export_id"`
+	BaseID    string          `json:"base_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Tables    []ManifestTable `json:"tables"`
+}
+
+// ManifestTable 清单中的一张表（一个 Parquet 文件）
+type ManifestTable struct {
+	TableID string           `json:"table_id"`
+	Name    string           `json:"name"`
+	Path    string           `json:"path"` // 对象存储中的路径
+	Rows    int64            `json:"rows"`
+	Bytes   int64            `json:"bytes"`
+	Columns []ManifestColumn `json:"columns"`
+}
+
+// ManifestColumn 清单中的一列
+type ManifestColumn struct {
+	Name      string `json:"name"`                 // Parquet 列名（系统列为固定名称，字段列为字段的物理列名）
+	FieldID   string `json:"field_id,omitempty"`   // 系统列为空
+	FieldName string `json:"field_name,omitempty"` // 导出时的字段名称
+	FieldType string `json:"field_type,omitempty"`
+	Type      string `json:"type"` // string | json | int64 | double | boolean | timestamp
+	Nullable  bool   `json:"nullable"`
+}
+
+// NewExport 创建待执行的导出任务
+func NewExport(baseID string, tableIDs []string, createdBy string, now time.Time) *Export {
+	return &Export{
+		ID:        utils.GenerateIDWithPrefix("dex"),
+		BaseID:    baseID,
+		TableIDs:  tableIDs,
+		Format:    FormatParquet,
+		Status:    StatusPending,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 校验导出范围
+func (e *Export) Validate() error {
+	if e.Format != FormatParquet {
+		return fmt.Errorf("不支持的导出格式 %q（可选 %s）", e.Format, FormatParquet)
+	}
+	if len(e.TableIDs) == 0 {
+		return fmt.Errorf("至少需要导出一张表")
+	}
+	if len(e.TableIDs) > MaxTables {
+		return fmt.Errorf("单次最多导出 %d 张表", MaxTables)
+	}
+	seen := make(map[string]bool, len(e.TableIDs))
+	for _, id := range e.TableIDs {
+		if seen[id] {
+			return fmt.Errorf("重复的表 %q", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// Finished 任务是否已结束
+func (e *Export) Finished() bool {
+	return e.Status == StatusCompleted || e.Status == StatusFailed
+}
+
+// Finish 结束导出任务：成功时保存清单
+func (e *Export) Finish(manifest *Manifest, err error, now time.Time) {
+	e.Status = StatusCompleted
+	e.Manifest = manifest
+	if err != nil {
+		e.Status = StatusFailed
+		e.Manifest = nil
+		e.Error = err.Error()
+	}
+	e.UpdatedAt = now
+	e.FinishedAt = &now
+}
+
+// DataPath 表数据文件在对象存储中的路径
+func (e *Export) DataPath(tableID string) string {
+	return e.Prefix() + tableID + ".parquet"
+}
+
+// ManifestPath 清单文件在对象存储中的路径
+func (e *Export) ManifestPath() string {
+	return e.Prefix() + "manifest.json"
+}
+
+// Prefix 导出文件在对象存储中的目录
+func (e *Export) Prefix() string {
+	return "exports/" + e.BaseID + "/" + e.ID + "/"
+}
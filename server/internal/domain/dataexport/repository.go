@@ -0,0 +1,18 @@
+package dataexport
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 导出任务仓储接口
+type Repository interface {
+	// Save 创建或更新导出任务
+	Save(ctx context.Context, export *Export) error
+	// FindByID 获取导出任务（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Export, error)
+	// ListByBase 按创建时间倒序列出 Base 的导出任务
+	ListByBase(ctx context.Context, baseID string, limit int) ([]*Export, error)
+	// Claim 领取待执行或已中断（running 且 staleBefore 之前未更新）的任务并标记为执行中
+	Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Export, error)
+}
@@ -0,0 +1,89 @@
+package disasterrecovery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	cp := NewCheckpoint(StreamEventLog, now)
+
+	if s := Evaluate(cp, &Pending{Items: 3}, now); s.RPOSeconds != nil || s.LagSeconds != nil {
+		t.Errorf("从未复制时 RPO 应未知: %+v", s)
+	}
+
+	cp.Succeed("42", now.Add(-time.Minute), 42, now.Add(-30*time.Second))
+	if s := Evaluate(cp, &Pending{}, now); *s.RPOSeconds != 0 || *s.LagSeconds != 30 || *s.PendingItems != 0 {
+		t.Errorf("已追平: %+v", s)
+	}
+	oldest := now.Add(-10 * time.Second)
+	if s := Evaluate(cp, &Pending{Items: 5, Oldest: &oldest}, now); *s.RPOSeconds != 10 || *s.PendingItems != 5 {
+		t.Errorf("有未复制事件: %+v", s)
+	}
+	if s := Evaluate(cp, nil, now); *s.RPOSeconds != 60 || s.PendingItems != nil {
+		t.Errorf("主区域不可达时按已复制时间点估计: %+v", s)
+	}
+
+	// 失败不推进进度，更早的时间点不回退
+	cp.Fail("43", 1, errors.New("连接超时"), now)
+	if cp.Position != "43" || !cp.ReplicatedThrough.Equal(now.Add(-time.Minute)) || cp.LastError == "" {
+		t.Errorf("失败后进度 = %+v", cp)
+	}
+	cp.Succeed("44", now.Add(-2*time.Minute), 1, now)
+	if !cp.ReplicatedThrough.Equal(now.Add(-time.Minute)) || cp.Items != 44 || cp.LastError != "" {
+		t.Errorf("进度 = %+v", cp)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	now := time.Now()
+	rpo := func(v int64) *int64 { return &v }
+
+	report := NewReport("dr", 15*time.Minute, []StreamStatus{
+		{Stream: StreamSnapshot, RPOSeconds: rpo(600), LagSeconds: rpo(600)},
+		{Stream: StreamEventLog, RPOSeconds: rpo(5), LagSeconds: rpo(2)},
+	}, nil, true, now)
+	if *report.RPOSeconds != 600 || *report.LagSeconds != 600 || !report.WithinTarget || report.Role != RolePrimary {
+		t.Errorf("报告 = %+v", report)
+	}
+
+	report = NewReport("dr", 15*time.Minute, []StreamStatus{
+		{Stream: StreamSnapshot, RPOSeconds: rpo(60)},
+		{Stream: StreamAttachments},
+	}, nil, true, now)
+	if report.RPOSeconds != nil || report.WithinTarget {
+		t.Errorf("存在从未复制的流时 RPO 应未知: %+v", report)
+	}
+
+	promotion := NewPromotion("dr", true, rpo(120), "主区域宕机", "usr1", now)
+	if !strings.HasPrefix(promotion.ID, "dpr") {
+		t.Errorf("提升记录 = %+v", promotion)
+	}
+	if report := NewReport("dr", time.Minute, nil, promotion, false, now); report.Role != RolePromoted || report.WithinTarget {
+		t.Errorf("提升后报告 = %+v", report)
+	}
+}
+
+func TestNewRunbook(t *testing.T) {
+	now := time.Now()
+	rpo := func(v int64) *int64 { return &v }
+	through := now.Add(-time.Minute)
+
+	report := NewReport("dr", 15*time.Minute, []StreamStatus{
+		{Stream: StreamSnapshot, ReplicatedThrough: &through, RPOSeconds: rpo(60)},
+		{Stream: StreamEventLog, ReplicatedThrough: &through, RPOSeconds: rpo(0), LastError: "连接超时"},
+	}, nil, true, now)
+	steps := NewRunbook(report).Steps
+	if len(steps) != 5 || steps[0].Status != StepBlocked || steps[1].Status != StepDone || steps[3].Status != StepReady {
+		t.Errorf("手册步骤 = %+v", steps)
+	}
+
+	promotion := NewPromotion("dr", false, rpo(0), "", "usr1", now)
+	steps = NewRunbook(NewReport("dr", time.Minute, nil, promotion, false, now)).Steps
+	if steps[0].Status != StepSkipped || steps[3].Status != StepDone || steps[4].Status != StepManual {
+		t.Errorf("提升后手册步骤 = %+v", steps)
+	}
+}
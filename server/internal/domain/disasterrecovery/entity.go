@@ -0,0 +1,300 @@
+package disasterrecovery
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Stream 复制流
+type Stream string
+
+const (
+	// StreamSnapshot 元数据表与 Base 数据表的定期快照（数据表按修改时间增量复制）
+	StreamSnapshot Stream = "snapshot"
+	// StreamEventLog 变更事件日志（按 seq 顺序连续复制）
+	StreamEventLog Stream = "event_log"
+	// StreamAttachments 附件文件（按上传时间顺序复制）
+	StreamAttachments Stream = "attachments"
+)
+
+// Streams 全部复制流
+var Streams = []Stream{StreamSnapshot, StreamEventLog, StreamAttachments}
+
+// Role 当前部署在灾备中的角色
+type Role string
+
+const (
+	// RolePrimary 主区域对外服务，持续复制到备区域
+	RolePrimary Role = "primary"
+	// RolePromoted 备区域已被提升，复制停止
+	RolePromoted Role = "promoted"
+)
+
+// Checkpoint 复制流的进度 ✨
+// 保存在备区域：主区域不可用时仍能据此评估可能丢失的数据范围（RPO）
+type Checkpoint struct {
+	Stream            Stream     `json:"stream"`
+	Position          string     `json:"position,omitempty"`           // 已复制到的位置：变更事件为 seq，附件为上传时间游标
+	ReplicatedThrough *time.Time `json:"replicated_through,omitempty"` // 主区域在该时间之前的变更已全部复制
+	Items             int64      `json:"items"`                        // 累计复制的行数、事件数或附件数
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	LastSuccessAt     *time.Time `json:"last_success_at,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// NewCheckpoint 创建尚未复制过的进度
+func NewCheckpoint(stream Stream, now time.Time) *Checkpoint {
+	return &Checkpoint{Stream: stream, UpdatedAt: now}
+}
+
+// Succeed 记录一次成功的复制
+func (c *Checkpoint) Succeed(position string, through time.Time, items int64, now time.Time) {
+	c.Position = position
+	if c.ReplicatedThrough == nil || through.After(*c.ReplicatedThrough) {
+		c.ReplicatedThrough = &through
+	}
+	c.Items += items
+	c.LastRunAt = &now
+	c.LastSuccessAt = &now
+	c.LastError = ""
+	c.UpdatedAt = now
+}
+
+// Fail 记录一次失败的复制：位置推进到失败前已复制的部分，已复制时间点保持不变
+func (c *Checkpoint) Fail(position string, items int64, err error, now time.Time) {
+	c.Position = position
+	c.Items += items
+	c.LastRunAt = &now
+	c.LastError = err.Error()
+	c.UpdatedAt = now
+}
+
+// Pending 主区域中尚未复制的数据
+type Pending struct {
+	Items  int64      // 未复制的条数
+	Oldest *time.Time // 最早一条未复制数据的时间
+}
+
+// StreamStatus 复制流的状态
+type StreamStatus struct {
+	Stream            Stream     `json:"stream"`
+	Position          string     `json:"position,omitempty"`
+	ReplicatedThrough *time.Time `json:"replicated_through,omitempty"`
+	Items             int64      `json:"items"`
+	LastSuccessAt     *time.Time `json:"last_success_at,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	PendingItems      *int64     `json:"pending_items,omitempty"` // 主区域不可达或无法统计时为空
+	RPOSeconds        *int64     `json:"rpo_seconds"`             // 此时故障可能丢失的数据时间范围，从未复制过时为空
+	LagSeconds        *int64     `json:"lag_seconds"`             // 距最近一次成功复制的时长
+}
+
+// Evaluate 根据进度与主区域的未复制数据计算复制流状态
+// pending 为空表示无法统计（主区域不可达或该流不统计），此时 RPO 按已复制时间点保守估计
+func Evaluate(cp *Checkpoint, pending *Pending, now time.Time) StreamStatus {
+	status := StreamStatus{
+		Stream:            cp.Stream,
+		Position:          cp.Position,
+		ReplicatedThrough: cp.ReplicatedThrough,
+		Items:             cp.Items,
+		LastSuccessAt:     cp.LastSuccessAt,
+		LastError:         cp.LastError,
+	}
+	if cp.LastSuccessAt != nil {
+		status.LagSeconds = seconds(now.Sub(*cp.LastSuccessAt))
+	}
+	if cp.ReplicatedThrough == nil {
+		return status
+	}
+
+	switch {
+	case pending == nil:
+		status.RPOSeconds = seconds(now.Sub(*cp.ReplicatedThrough))
+	case pending.Items == 0:
+		status.PendingItems = &pending.Items
+		status.RPOSeconds = seconds(0)
+	default:
+		status.PendingItems = &pending.Items
+		oldest := *cp.ReplicatedThrough
+		if pending.Oldest != nil {
+			oldest = *pending.Oldest
+		}
+		status.RPOSeconds = seconds(now.Sub(oldest))
+	}
+	return status
+}
+
+func seconds(d time.Duration) *int64 {
+	if d < 0 {
+		d = 0
+	}
+	s := int64(d / time.Second)
+	return &s
+}
+
+// Report 灾备状态报告（故障切换手册的依据）
+type Report struct {
+	Region           string         `json:"region"` // 备区域
+	Role             Role           `json:"role"`
+	Promotion        *Promotion     `json:"promotion,omitempty"`
+	PrimaryReachable bool           `json:"primary_reachable"`
+	Streams          []StreamStatus `json:"streams"`
+	RPOSeconds       *int64         `json:"rpo_seconds"` // 各复制流 RPO 的最大值，任一流从未复制时为空
+	LagSeconds       *int64         `json:"lag_seconds"` // 各复制流复制延迟的最大值
+	RPOTargetSeconds int64          `json:"rpo_target_seconds"`
+	WithinTarget     bool           `json:"within_target"` // RPO 已知且不超过目标
+	GeneratedAt      time.Time      `json:"generated_at"`
+}
+
+// NewReport 汇总各复制流状态
+func NewReport(region string, target time.Duration, streams []StreamStatus, promotion *Promotion, primaryReachable bool, now time.Time) *Report {
+	report := &Report{
+		Region:           region,
+		Role:             RolePrimary,
+		Promotion:        promotion,
+		PrimaryReachable: primaryReachable,
+		Streams:          streams,
+		RPOTargetSeconds: int64(target / time.Second),
+		GeneratedAt:      now,
+	}
+	if promotion != nil {
+		report.Role = RolePromoted
+	}
+
+	known := len(streams) > 0
+	for _, s := range streams {
+		if s.RPOSeconds == nil {
+			known = false
+		} else if report.RPOSeconds == nil || *s.RPOSeconds > *report.RPOSeconds {
+			report.RPOSeconds = s.RPOSeconds
+		}
+		if s.LagSeconds != nil && (report.LagSeconds == nil || *s.LagSeconds > *report.LagSeconds) {
+			report.LagSeconds = s.LagSeconds
+		}
+	}
+	if !known {
+		report.RPOSeconds = nil
+	}
+	report.WithinTarget = report.RPOSeconds != nil && *report.RPOSeconds <= report.RPOTargetSeconds
+	return report
+}
+
+// Promotion 备区域提升记录
+type Promotion struct {
+	ID         string    `json:"id"`
+	Region     string    `json:"region"`
+	Forced     bool      `json:"forced"`                // 跳过最终同步（主区域不可达）
+	RPOSeconds *int64    `json:"rpo_seconds,omitempty"` // 提升时的 RPO（最终同步成功时为 0）
+	Reason     string    `json:"reason,omitempty"`
+	PromotedBy string    `json:"promoted_by"`
+	PromotedAt time.Time `json:"promoted_at"`
+}
+
+// NewPromotion 创建提升记录
+func NewPromotion(region string, forced bool, rpoSeconds *int64, reason, promotedBy string, now time.Time) *Promotion {
+	return &Promotion{
+		ID:         utils.GenerateIDWithPrefix("dpr"),
+		Region:     region,
+		Forced:     forced,
+		RPOSeconds: rpoSeconds,
+		Reason:     reason,
+		PromotedBy: promotedBy,
+		PromotedAt: now,
+	}
+}
+
+// 故障切换手册步骤状态
+const (
+	StepDone    = "done"    // 已满足
+	StepReady   = "ready"   // 可以执行
+	StepBlocked = "blocked" // 存在问题，需先处理
+	StepManual  = "manual"  // 需人工操作
+	StepSkipped = "skipped" // 当前情况下不适用
+)
+
+// RunbookStep 故障切换手册的一个步骤
+type RunbookStep struct {
+	Key    string `json:"key"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// Runbook 故障切换手册：当前灾备状态与各步骤的检查结果
+type Runbook struct {
+	Report *Report       `json:"report"`
+	Steps  []RunbookStep `json:"steps"`
+}
+
+// NewRunbook 按灾备状态生成故障切换手册
+func NewRunbook(report *Report) *Runbook {
+	promoted := report.Role == RolePromoted
+
+	health := RunbookStep{Key: "replication_healthy", Title: "确认复制正常", Status: StepDone, Detail: "各复制流最近一次复制均成功"}
+	var problems []string
+	for _, s := range report.Streams {
+		switch {
+		case s.ReplicatedThrough == nil:
+			problems = append(problems, fmt.Sprintf("%s 尚未完成首次复制", s.Stream))
+		case s.LastError != "":
+			problems = append(problems, fmt.Sprintf("%s 最近一次复制失败: %s", s.Stream, s.LastError))
+		}
+	}
+	if len(problems) > 0 {
+		health.Status = StepBlocked
+		health.Detail = strings.Join(problems, "；")
+	}
+
+	rpo := RunbookStep{Key: "rpo_within_target", Title: "确认 RPO 满足目标", Status: StepDone}
+	switch {
+	case report.RPOSeconds == nil:
+		rpo.Status = StepBlocked
+		rpo.Detail = "RPO 未知：存在从未复制的复制流"
+	case !report.WithinTarget:
+		rpo.Status = StepBlocked
+		rpo.Detail = fmt.Sprintf("当前 RPO %d 秒，超过目标 %d 秒；提升将丢失该时间范围内的数据", *report.RPOSeconds, report.RPOTargetSeconds)
+	default:
+		rpo.Detail = fmt.Sprintf("当前 RPO %d 秒，目标 %d 秒", *report.RPOSeconds, report.RPOTargetSeconds)
+	}
+
+	freeze := RunbookStep{
+		Key:    "freeze_primary",
+		Title:  "停止主区域写入",
+		Status: StepManual,
+		Detail: "将主区域服务摘除流量或切换为维护模式，避免提升后仍有写入落在主区域",
+	}
+	if !report.PrimaryReachable {
+		freeze.Status = StepSkipped
+		freeze.Detail = "主区域不可达"
+	}
+
+	promote := RunbookStep{
+		Key:    "promote",
+		Title:  "提升备区域",
+		Status: StepReady,
+		Detail: "调用 POST /admin/disaster-recovery/promote：主区域可达时先执行最终同步；主区域不可达时需指定 force，接受 RPO 范围内的数据丢失",
+	}
+	if !report.PrimaryReachable {
+		promote.Detail = "主区域不可达，需指定 force 提升，将丢失 RPO 范围内的数据"
+	}
+
+	repoint := RunbookStep{
+		Key:    "repoint",
+		Title:  "切换服务到备区域",
+		Status: StepManual,
+		Detail: "将 database 与附件存储配置指向备区域并重启服务，之后以原主区域（恢复后）为新的备区域重新建立复制",
+	}
+
+	if promoted {
+		for _, step := range []*RunbookStep{&health, &rpo, &freeze} {
+			step.Status = StepSkipped
+		}
+		promote.Status = StepDone
+		promote.Detail = fmt.Sprintf("已于 %s 由 %s 提升", report.Promotion.PromotedAt.Format(time.RFC3339), report.Promotion.PromotedBy)
+	}
+
+	return &Runbook{Report: report, Steps: []RunbookStep{health, rpo, freeze, promote, repoint}}
+}
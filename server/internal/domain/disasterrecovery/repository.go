@@ -0,0 +1,22 @@
+package disasterrecovery
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 灾备仓储接口（数据保存在备区域）
+type Repository interface {
+	// FindCheckpoint 获取复制流的进度（不存在时返回 nil）
+	FindCheckpoint(ctx context.Context, stream Stream) (*Checkpoint, error)
+	// SaveCheckpoint 创建或更新复制流的进度
+	SaveCheckpoint(ctx context.Context, cp *Checkpoint) error
+	// Acquire 获取复制流的执行租约（多实例部署时同一时间只有一个实例复制该流）
+	Acquire(ctx context.Context, stream Stream, now, until time.Time) (bool, error)
+	// Release 释放复制流的执行租约
+	Release(ctx context.Context, stream Stream) error
+	// LatestPromotion 获取最近一次提升记录（未提升时返回 nil）
+	LatestPromotion(ctx context.Context) (*Promotion, error)
+	// SavePromotion 保存提升记录
+	SavePromotion(ctx context.Context, promotion *Promotion) error
+}
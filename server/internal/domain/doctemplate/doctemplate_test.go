@@ -0,0 +1,90 @@
+package doctemplate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	now := time.Now()
+	template := NewTemplate("tbl1", " 报价单 ", FormatDOCX, "usr1", now)
+	template.MergeFields = []string{"客户", "金额"}
+	template.TargetFieldID = "fldAttach"
+	template.Mappings = map[string]string{"客户": "fldName"}
+	if err := template.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if template.Name != "报价单" || template.Output != OutputDOCX {
+		t.Errorf("名称或输出格式错误: %+v", template)
+	}
+	if unmapped := template.Unmapped(); len(unmapped) != 1 || unmapped[0] != "金额" {
+		t.Errorf("未映射的合并域错误: %v", unmapped)
+	}
+
+	template.Mappings["备注"] = "fldNotes"
+	if err := template.Validate(); err == nil {
+		t.Error("映射不存在的合并域应报错")
+	}
+	delete(template.Mappings, "备注")
+
+	template.Output = OutputPDF
+	if err := template.Validate(); err == nil {
+		t.Error("DOCX 模板不能生成 PDF")
+	}
+	html := NewTemplate("tbl1", "信函", FormatHTML, "usr1", now)
+	html.TargetFieldID = "fldAttach"
+	html.Output = OutputPDF
+	if err := html.Validate(); err != nil {
+		t.Errorf("HTML 模板可以生成 PDF: %v", err)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	for name, want := range map[string]Format{"a.DOCX": FormatDOCX, "b.htm": FormatHTML, "c.html": FormatHTML} {
+		if got, ok := FormatOf(name); !ok || got != want {
+			t.Errorf("%s: 期望 %s，得到 %s", name, want, got)
+		}
+	}
+	if _, ok := FormatOf("a.doc"); ok {
+		t.Error("不支持 .doc")
+	}
+}
+
+func TestOutputFileName(t *testing.T) {
+	template := &Template{Output: OutputPDF}
+	if got := template.OutputFileName("报价/ACME: 2026", "rec1"); got != "报价_ACME_ 2026.pdf" {
+		t.Errorf("文件名错误: %s", got)
+	}
+	if got := template.OutputFileName("  ", "rec1"); got != "rec1.pdf" {
+		t.Errorf("标题为空时应使用 fallback: %s", got)
+	}
+	if got := template.OutputFileName(strings.Repeat("长", 150), "rec1"); len([]rune(got)) != 104 {
+		t.Errorf("过长标题应截断: %d", len([]rune(got)))
+	}
+}
+
+func TestJobFinish(t *testing.T) {
+	template := NewTemplate("tbl1", "报价单", FormatDOCX, "usr1", time.Now())
+	job := NewJob(template, "", []string{"rec1", "rec2"}, "usr1", time.Now())
+	if err := job.Validate(1); err == nil {
+		t.Error("超过记录数上限应报错")
+	}
+	if err := NewJob(template, "", nil, "usr1", time.Now()).Validate(10); err == nil {
+		t.Error("未指定记录或视图应报错")
+	}
+
+	job.Finish([]Result{{RecordID: "rec1", AttachmentID: "att1"}, {RecordID: "rec2", Error: "记录不存在"}}, nil, time.Now())
+	if job.Status != StatusCompleted || job.Generated != 1 || job.Failed != 1 || job.Total != 2 {
+		t.Errorf("部分成功应为 completed: %+v", job)
+	}
+	job.Finish([]Result{{RecordID: "rec2", Error: "记录不存在"}}, nil, time.Now())
+	if job.Status != StatusFailed || job.Error != "记录不存在" {
+		t.Errorf("全部失败应为 failed: %+v", job)
+	}
+	job.Finish(nil, errors.New("模板不存在"), time.Now())
+	if job.Status != StatusFailed || job.Error != "模板不存在" {
+		t.Errorf("整体出错应为 failed: %+v", job)
+	}
+}
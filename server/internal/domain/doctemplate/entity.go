@@ -0,0 +1,176 @@
+package doctemplate
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Format 模板文件格式
+type Format string
+
+const (
+	FormatDOCX Format = "docx"
+	FormatHTML Format = "html"
+)
+
+// 生成文件的格式
+const (
+	OutputDOCX = "docx" // DOCX 模板
+	OutputHTML = "html" // HTML 模板
+	OutputPDF  = "pdf"  // HTML 模板，按正文文本排版为 PDF
+)
+
+const (
+	// MaxTemplatesPerTable 单表的文档模板数上限
+	MaxTemplatesPerTable = 50
+	// MaxNameLength 模板名称的最大长度（字符数）
+	MaxNameLength = 100
+	// maxFileNameRunes 生成文件名（不含扩展名）的最大字符数
+	maxFileNameRunes = 100
+)
+
+// Template 文档模板 ✨
+// 模板文件（DOCX 或 HTML）中的合并域按 Mappings 映射到表的字段，为记录生成文档时替换为记录的展示文本，
+// 生成的文件写入 TargetFieldID 指定的附件字段
+type Template struct {
+	ID            string            `json:"id"`
+	TableID       string            `json:"table_id"`
+	Name          string            `json:"name"`
+	Format        Format            `json:"format"`
+	Output        string            `json:"output"`    // 生成文件的格式，见 Output* 常量
+	FileName      string            `json:"file_name"` // 上传的模板文件名
+	Size          int64             `json:"size"`
+	MergeFields   []string          `json:"merge_fields"`            // 模板中的合并域（上传时解析）
+	Mappings      map[string]string `json:"mappings"`                // 合并域 → 字段ID
+	TargetFieldID string            `json:"target_field_id"`         // 写入生成文件的附件字段
+	NameFieldID   string            `json:"name_field_id,omitempty"` // 生成文件名取该字段的值，为空时取主字段
+	CreatedBy     string            `json:"created_by"`
+	UpdatedBy     string            `json:"updated_by"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// NewTemplate 创建文档模板（生成文件默认与模板格式相同）
+func NewTemplate(tableID, name string, format Format, createdBy string, now time.Time) *Template {
+	return &Template{
+		ID:        utils.GenerateIDWithPrefix("dtp"),
+		TableID:   tableID,
+		Name:      name,
+		Format:    format,
+		Output:    string(format),
+		Mappings:  map[string]string{},
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// FormatOf 由文件名判断模板格式
+func FormatOf(fileName string) (Format, bool) {
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".docx":
+		return FormatDOCX, true
+	case ".html", ".htm":
+		return FormatHTML, true
+	}
+	return "", false
+}
+
+// Validate 校验名称、输出格式与目标字段（字段类型由调用方按表结构校验）
+func (t *Template) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("模板名称不能为空")
+	}
+	if utf8.RuneCountInString(t.Name) > MaxNameLength {
+		return fmt.Errorf("模板名称不能超过 %d 个字符", MaxNameLength)
+	}
+	switch t.Format {
+	case FormatDOCX:
+		if t.Output != OutputDOCX {
+			return fmt.Errorf("DOCX 模板只能生成 %s 文件", OutputDOCX)
+		}
+	case FormatHTML:
+		if t.Output != OutputHTML && t.Output != OutputPDF {
+			return fmt.Errorf("HTML 模板只能生成 %s 或 %s 文件", OutputHTML, OutputPDF)
+		}
+	default:
+		return fmt.Errorf("不支持的模板格式 %q（可选 %s、%s）", t.Format, FormatDOCX, FormatHTML)
+	}
+	if t.TargetFieldID == "" {
+		return fmt.Errorf("缺少写入生成文件的附件字段")
+	}
+	if t.Mappings == nil {
+		t.Mappings = map[string]string{}
+	}
+	for name := range t.Mappings {
+		if !t.hasMergeField(name) {
+			return fmt.Errorf("模板中没有合并域 %q", name)
+		}
+	}
+	return nil
+}
+
+func (t *Template) hasMergeField(name string) bool {
+	for _, field := range t.MergeFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Unmapped 没有映射到字段的合并域（生成时替换为空文本）
+func (t *Template) Unmapped() []string {
+	var names []string
+	for _, name := range t.MergeFields {
+		if t.Mappings[name] == "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// FilePath 模板文件在对象存储中的路径
+func (t *Template) FilePath() string {
+	return "document-templates/" + t.TableID + "/" + t.ID + "." + string(t.Format)
+}
+
+// ContentType 生成文件的 MIME 类型
+func (t *Template) ContentType() string {
+	switch t.Output {
+	case OutputDOCX:
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case OutputPDF:
+		return "application/pdf"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// OutputFileName 由标题生成文件名：去掉路径分隔符与控制字符，过长时截断，标题为空时使用 fallback
+func (t *Template) OutputFileName(title, fallback string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r):
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, title)
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxFileNameRunes {
+		name = strings.TrimSpace(string(runes[:maxFileNameRunes]))
+	}
+	if name == "" || strings.Trim(name, ".") == "" {
+		name = fallback
+	}
+	return name + "." + t.Output
+}
@@ -0,0 +1,115 @@
+package doctemplate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Status 生成任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed" // 至少一条记录生成成功
+	StatusFailed    Status = "failed"
+)
+
+// 任务来源
+const (
+	SourceAPI        = "api"
+	SourceAutomation = "automation"
+)
+
+// Result 单条记录的生成结果
+type Result struct {
+	RecordID     string `json:"record_id"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+	FileName     string `json:"file_name,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// Job 文档生成任务 ✨
+// 为指定的记录或视图中的记录逐条生成文档，写入模板的附件字段；以创建者的身份读取与写入记录
+type Job struct {
+	ID         string     `json:"id"`
+	TemplateID string     `json:"template_id"`
+	TableID    string     `json:"table_id"`
+	ViewID     string     `json:"view_id,omitempty"`    // 按视图批量生成时的视图（使用其过滤与排序）
+	RecordIDs  []string   `json:"record_ids,omitempty"` // 指定的记录
+	TimeZone   string     `json:"time_zone,omitempty"`  // 创建时请求用户的时区，用于日期格式化
+	Locale     string     `json:"locale,omitempty"`
+	Source     string     `json:"source"`
+	Status     Status     `json:"status"`
+	Total      int        `json:"total"`
+	Generated  int        `json:"generated"`
+	Failed     int        `json:"failed"`
+	Truncated  bool       `json:"truncated,omitempty"` // 视图记录数超过上限，只生成了前面的部分
+	Results    []Result   `json:"results,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewJob 创建生成任务：recordIDs 非空时为这些记录生成，否则为视图中的记录生成
+func NewJob(template *Template, viewID string, recordIDs []string, createdBy string, now time.Time) *Job {
+	return &Job{
+		ID:         utils.GenerateIDWithPrefix("dgj"),
+		TemplateID: template.ID,
+		TableID:    template.TableID,
+		ViewID:     viewID,
+		RecordIDs:  recordIDs,
+		Source:     SourceAPI,
+		Status:     StatusPending,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Validate 校验生成目标（maxRecords 为一次生成的记录数上限）
+func (j *Job) Validate(maxRecords int) error {
+	if len(j.RecordIDs) == 0 && j.ViewID == "" {
+		return fmt.Errorf("需要指定记录或视图")
+	}
+	if len(j.RecordIDs) > maxRecords {
+		return fmt.Errorf("一次最多为 %d 条记录生成文档", maxRecords)
+	}
+	return nil
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusCompleted || j.Status == StatusFailed
+}
+
+// Finish 结束任务：err 非空或全部记录生成失败时为 failed
+func (j *Job) Finish(results []Result, err error, now time.Time) {
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+	j.Results = results
+	j.Total, j.Generated, j.Failed = len(results), 0, 0
+	for _, result := range results {
+		if result.Error != "" {
+			j.Failed++
+		} else {
+			j.Generated++
+		}
+	}
+	switch {
+	case err != nil:
+		j.Status = StatusFailed
+		j.Error = err.Error()
+	case j.Generated == 0 && j.Failed > 0:
+		j.Status = StatusFailed
+		j.Error = results[0].Error
+	default:
+		j.Status = StatusCompleted
+		j.Error = ""
+	}
+}
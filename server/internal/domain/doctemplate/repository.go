@@ -0,0 +1,27 @@
+package doctemplate
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 文档模板与生成任务仓储接口
+type Repository interface {
+	// ListTemplates 按创建时间列出表的文档模板
+	ListTemplates(ctx context.Context, tableID string) ([]*Template, error)
+	// GetTemplate 获取文档模板（不存在时返回 nil）
+	GetTemplate(ctx context.Context, id string) (*Template, error)
+	// SaveTemplate 创建或更新文档模板
+	SaveTemplate(ctx context.Context, template *Template) error
+	// DeleteTemplate 删除文档模板及其生成任务
+	DeleteTemplate(ctx context.Context, id string) error
+
+	// SaveJob 创建或更新生成任务
+	SaveJob(ctx context.Context, job *Job) error
+	// FindJob 获取生成任务（不存在时返回 nil）
+	FindJob(ctx context.Context, id string) (*Job, error)
+	// ListJobs 按创建时间倒序列出模板的生成任务
+	ListJobs(ctx context.Context, templateID string, limit int) ([]*Job, error)
+	// ClaimJobs 领取待执行或已中断（running 且 staleBefore 之前未更新）的任务并标记为执行中
+	ClaimJobs(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Job, error)
+}
@@ -0,0 +1,105 @@
+package embed
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Permission 嵌入令牌的权限级别
+type Permission string
+
+const (
+	PermissionViewer Permission = "viewer" // 只读查看视图数据
+	PermissionEditor Permission = "editor" // 可编辑视图中可见记录的可见字段
+)
+
+// IsValid 权限级别是否有效
+func (p Permission) IsValid() bool {
+	return p == PermissionViewer || p == PermissionEditor
+}
+
+// DefaultMaxTokenTTL 未配置时嵌入令牌的最长有效期
+const DefaultMaxTokenTTL = 30 * 24 * time.Hour
+
+// Settings 工作空间嵌入设置
+// 未启用时不能签发嵌入令牌，已签发的令牌也立即失效；
+// AllowedOrigins 同时用于 CORS 与 frame-ancestors，未配置时不允许任何站点嵌入
+type Settings struct {
+	SpaceID            string    `json:"space_id"`
+	Enabled            bool      `json:"enabled"`
+	AllowedOrigins     []string  `json:"allowed_origins"`       // 形如 https://example.com 的来源
+	MaxTokenTTLMinutes int       `json:"max_token_ttl_minutes"` // 令牌最长有效期，0 表示使用默认值
+	TokenVersion       int       `json:"token_version"`         // 递增后此前签发的令牌全部失效
+	UpdatedBy          string    `json:"updated_by"`
+	UpdatedTime        time.Time `json:"updated_time"`
+}
+
+// NewDefaultSettings 创建空间的默认嵌入设置（未启用）
+func NewDefaultSettings(spaceID string) *Settings {
+	return &Settings{
+		SpaceID:        spaceID,
+		AllowedOrigins: []string{},
+	}
+}
+
+// Validate 校验嵌入设置，并将来源规范化为 scheme://host[:port]
+func (s *Settings) Validate() error {
+	if s.MaxTokenTTLMinutes < 0 {
+		return fmt.Errorf("令牌有效期不能为负数")
+	}
+	origins := make([]string, 0, len(s.AllowedOrigins))
+	for _, entry := range s.AllowedOrigins {
+		origin, err := normalizeOrigin(entry)
+		if err != nil {
+			return err
+		}
+		origins = append(origins, origin)
+	}
+	s.AllowedOrigins = origins
+	return nil
+}
+
+// MaxTokenTTL 令牌最长有效期
+func (s *Settings) MaxTokenTTL() time.Duration {
+	if s.MaxTokenTTLMinutes <= 0 {
+		return DefaultMaxTokenTTL
+	}
+	return time.Duration(s.MaxTokenTTLMinutes) * time.Minute
+}
+
+// AllowsOrigin 请求来源是否在允许列表中（大小写不敏感）
+func (s *Settings) AllowsOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	for _, allowed := range s.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// FrameAncestors Content-Security-Policy 的 frame-ancestors 指令
+func (s *Settings) FrameAncestors() string {
+	if !s.Enabled || len(s.AllowedOrigins) == 0 {
+		return "frame-ancestors 'none'"
+	}
+	return "frame-ancestors " + strings.Join(s.AllowedOrigins, " ")
+}
+
+// normalizeOrigin 校验并规范化来源（只允许 http/https，不能带路径、查询或通配符）
+func normalizeOrigin(entry string) (string, error) {
+	entry = strings.TrimSuffix(strings.TrimSpace(entry), "/")
+	u, err := url.Parse(entry)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", fmt.Errorf("无效的来源: %s", entry)
+	}
+	if u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil || strings.Contains(u.Host, "*") {
+		return "", fmt.Errorf("来源只能包含协议、域名和端口: %s", entry)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
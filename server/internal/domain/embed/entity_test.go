@@ -0,0 +1,43 @@
+package embed
+
+import "testing"
+
+func TestSettingsValidateNormalizesOrigins(t *testing.T) {
+	s := &Settings{AllowedOrigins: []string{" https://Example.com/ ", "http://localhost:3000"}}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("期望校验通过，得到 %v", err)
+	}
+	if s.AllowedOrigins[0] != "https://example.com" || s.AllowedOrigins[1] != "http://localhost:3000" {
+		t.Errorf("来源未规范化: %v", s.AllowedOrigins)
+	}
+}
+
+func TestSettingsValidateRejectsInvalidOrigins(t *testing.T) {
+	for _, origin := range []string{"example.com", "ftp://example.com", "https://example.com/app", "https://*.example.com", "https://example.com?a=1"} {
+		s := &Settings{AllowedOrigins: []string{origin}}
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: 期望校验失败", origin)
+		}
+	}
+}
+
+func TestSettingsAllowsOrigin(t *testing.T) {
+	s := &Settings{Enabled: true, AllowedOrigins: []string{"https://example.com"}}
+	if !s.AllowsOrigin("https://EXAMPLE.com") {
+		t.Error("期望允许大小写不同的同一来源")
+	}
+	if s.AllowsOrigin("https://evil.com") || s.AllowsOrigin("") {
+		t.Error("不应允许未配置的来源")
+	}
+}
+
+func TestSettingsFrameAncestors(t *testing.T) {
+	s := &Settings{AllowedOrigins: []string{"https://a.com", "https://b.com"}}
+	if got := s.FrameAncestors(); got != "frame-ancestors 'none'" {
+		t.Errorf("未启用时期望禁止嵌入，得到 %s", got)
+	}
+	s.Enabled = true
+	if got := s.FrameAncestors(); got != "frame-ancestors https://a.com https://b.com" {
+		t.Errorf("得到 %s", got)
+	}
+}
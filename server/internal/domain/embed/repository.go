@@ -0,0 +1,11 @@
+package embed
+
+import "context"
+
+// Repository 工作空间嵌入设置仓储接口
+type Repository interface {
+	// GetSettings 获取空间嵌入设置（未配置时返回 nil）
+	GetSettings(ctx context.Context, spaceID string) (*Settings, error)
+	// SaveSettings 保存空间嵌入设置
+	SaveSettings(ctx context.Context, settings *Settings) error
+}
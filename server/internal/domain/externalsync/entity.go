@@ -0,0 +1,154 @@
+package externalsync
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// SourceType 外部数据源类型
+type SourceType string
+
+const (
+	SourcePostgres SourceType = "postgres"
+	SourceMySQL    SourceType = "mysql"
+	SourceREST     SourceType = "rest"
+)
+
+// RunStatus 同步运行状态
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// Source 外部数据源位置
+// postgres / mysql 使用 Table（可带 schema 前缀），连接串保存在连接器的 Credentials 中；
+// rest 使用 URL 返回 JSON：ItemsPath 指向行数组（为空时整个响应即为数组），
+// NextPath 指向下一页地址（可选），CursorParam 为增量读取时携带游标值的查询参数
+type Source struct {
+	Type         SourceType `json:"type"`
+	Table        string     `json:"table,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	ItemsPath    string     `json:"items_path,omitempty"`
+	NextPath     string     `json:"next_path,omitempty"`
+	CursorParam  string     `json:"cursor_param,omitempty"`
+	KeyColumn    string     `json:"key_column"`              // 行的唯一键（对应目标记录）
+	CursorColumn string     `json:"cursor_column,omitempty"` // 更新时间列，为空时每次全量同步
+}
+
+// Mapping 外部列与目标表字段的对应关系
+type Mapping struct {
+	Column  string `json:"column"`
+	FieldID string `json:"field_id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+}
+
+// Connector 外部数据同步连接器 ✨
+// 按间隔从外部数据库表或 REST 接口拉取行，按字段对应关系写入目标 Base 中的只读同步表：
+// 配置了更新时间列时按游标增量同步，否则（以及首次运行、到达全量间隔时）全量同步；
+// 增量同步无法发现删除，全量同步后源中已不存在的行保留在目标表中并勾选“源记录已删除”标记
+type Connector struct {
+	ID             string        `json:"id"`
+	BaseID         string        `json:"base_id"`
+	Name           string        `json:"name"`
+	Source         Source        `json:"source"`
+	Credentials    string        `json:"-"` // 数据库连接串或 REST 的 Authorization 头，不在响应中返回
+	Mappings       []Mapping     `json:"mappings"`
+	TargetTableID  string        `json:"target_table_id"`
+	DeletedFieldID string        `json:"deleted_field_id"` // 目标表中的“源记录已删除”字段
+	Interval       time.Duration `json:"interval"`
+	Enabled        bool          `json:"enabled"`
+	Cursor         string        `json:"cursor,omitempty"`     // 已同步到的更新时间
+	CursorKey      string        `json:"cursor_key,omitempty"` // 该更新时间下最后同步的行键（更新时间相同的行按键继续）
+	LastFullSyncAt *time.Time    `json:"last_full_sync_at,omitempty"`
+	NextRunAt      time.Time     `json:"next_run_at"`
+	LastRunAt      *time.Time    `json:"last_run_at,omitempty"`
+	LastStatus     RunStatus     `json:"last_status,omitempty"`
+	LastError      string        `json:"last_error,omitempty"`
+	CreatedBy      string        `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// NewConnector 创建连接器（创建后立即执行首次全量同步）
+func NewConnector(baseID, name string, source Source, credentials string, mappings []Mapping, interval time.Duration, createdBy string) *Connector {
+	now := time.Now()
+	return &Connector{
+		ID:          utils.GenerateIDWithPrefix("exs"),
+		BaseID:      baseID,
+		Name:        name,
+		Source:      source,
+		Credentials: credentials,
+		Mappings:    mappings,
+		Interval:    interval,
+		Enabled:     true,
+		NextRunAt:   now,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NeedsFullSync 本次运行是否需要全量同步
+// 未配置更新时间列、尚未成功全量同步、游标被重置或距上次全量同步已超过 fullInterval（为 0 时不定期全量）
+func (c *Connector) NeedsFullSync(now time.Time, fullInterval time.Duration) bool {
+	if c.Source.CursorColumn == "" || c.LastFullSyncAt == nil || c.Cursor == "" {
+		return true
+	}
+	return fullInterval > 0 && now.Sub(*c.LastFullSyncAt) >= fullInterval
+}
+
+// Run 一次同步运行的记录
+type Run struct {
+	ID            string     `json:"id"`
+	ConnectorID   string     `json:"connector_id"`
+	Status        RunStatus  `json:"status"`
+	Full          bool       `json:"full"`    // 是否为全量同步
+	Fetched       int64      `json:"fetched"` // 从外部读取的行数
+	Created       int64      `json:"created"`
+	Updated       int64      `json:"updated"`
+	Removed       int64      `json:"removed"` // 标记为源记录已删除的行数
+	Error         string     `json:"error,omitempty"`
+	CursorAtStart string     `json:"cursor_at_start,omitempty"`
+	CursorAtEnd   string     `json:"cursor_at_end,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewRun 开始一次同步运行
+func NewRun(connector *Connector, full bool) *Run {
+	return &Run{
+		ID:            utils.GenerateIDWithPrefix("exr"),
+		ConnectorID:   connector.ID,
+		Status:        RunRunning,
+		Full:          full,
+		CursorAtStart: connector.Cursor,
+		StartedAt:     time.Now(),
+	}
+}
+
+// Finish 结束运行（err 为空时视为成功）
+func (r *Run) Finish(connector *Connector, err error) {
+	now := time.Now()
+	r.FinishedAt = &now
+	r.CursorAtEnd = connector.Cursor
+	if err != nil {
+		r.Status = RunFailed
+		r.Error = err.Error()
+		return
+	}
+	r.Status = RunSucceeded
+}
+
+// Row 外部行与目标记录的对应关系
+type Row struct {
+	ConnectorID    string    `json:"connector_id"`
+	ExternalKey    string    `json:"external_key"`
+	TargetRecordID string    `json:"target_record_id"`
+	SourceDeleted  bool      `json:"source_deleted"`
+	SyncedAt       time.Time `json:"synced_at"` // 最近一次写入目标记录的时间（全量同步据此找出源中已不存在的行）
+}
@@ -0,0 +1,38 @@
+package externalsync
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 外部数据同步仓储接口
+type Repository interface {
+	// Save 创建或更新连接器配置（不覆盖同步进度）
+	Save(ctx context.Context, connector *Connector) error
+	// FindByID 获取连接器（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Connector, error)
+	// FindByTargetTable 获取写入该表的连接器（不存在时返回 nil）
+	FindByTargetTable(ctx context.Context, tableID string) (*Connector, error)
+	// ListByBase 列出 Base 下的连接器
+	ListByBase(ctx context.Context, baseID string) ([]*Connector, error)
+	// Delete 删除连接器、运行记录及行对应关系（目标表本身保留）
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取已到执行时间的启用连接器并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*Connector, error)
+	// SaveProgress 保存同步游标、字段对应与下次执行时间并释放租约
+	SaveProgress(ctx context.Context, connector *Connector) error
+
+	// SaveRun 创建或更新运行记录
+	SaveRun(ctx context.Context, run *Run) error
+	// ListRuns 按开始时间倒序列出连接器的运行记录
+	ListRuns(ctx context.Context, connectorID string, limit int) ([]*Run, error)
+	// PurgeRuns 只保留连接器最近 keep 条运行记录
+	PurgeRuns(ctx context.Context, connectorID string, keep int) error
+
+	// FindRows 获取外部行对应的目标记录
+	FindRows(ctx context.Context, connectorID string, keys []string) ([]*Row, error)
+	// SaveRows 创建或更新行对应关系
+	SaveRows(ctx context.Context, rows []*Row) error
+	// ListStaleRows 列出 before 之前写入且未标记删除的对应关系（按外部键排序）
+	ListStaleRows(ctx context.Context, connectorID string, before time.Time, limit int) ([]*Row, error)
+}
@@ -0,0 +1,200 @@
+// Package cellvalue 单元格值的类型化模型 ✨
+//
+// 每种字段类型对应一个转换器，负责在三种表示之间转换：
+//   - API JSON：请求与响应中的单元格值（用户、关联、附件为对象）
+//   - 存储值：写入物理列的值（JSONB 列为可序列化的对象，数字列为 float64，时间列为 time.Time）
+//   - 公式值：公式求值时使用的值（日期为 RFC3339 字符串，引用类字段为标题）
+//
+// 读写路径、公式依赖与视图过滤统一经过这里，避免各处对 map[string]interface{} 做各自的类型断言
+// （例如 NUMERIC 列读出字符串、按用户ID过滤存储为对象的用户列）
+package cellvalue
+
+import (
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+// Kind 单元格值类别
+type Kind string
+
+const (
+	KindNull        Kind = "null"
+	KindText        Kind = "text"
+	KindNumber      Kind = "number"
+	KindCheckbox    Kind = "checkbox"
+	KindDate        Kind = "date"
+	KindMultiSelect Kind = "multiSelect"
+	KindLink        Kind = "link"
+	KindUser        Kind = "user"
+	KindAttachment  Kind = "attachment"
+	KindJSON        Kind = "json"
+	KindRaw         Kind = "raw"    // 未注册转换器的字段类型（计算字段等），原样保留
+	KindCustom      Kind = "custom" // ✨ 通过 fieldplugin 注册的自定义字段类型
+)
+
+// CellValue 类型化的单元格值
+type CellValue interface {
+	Kind() Kind
+	IsEmpty() bool
+	// API 返回给客户端的值
+	API() interface{}
+	// Storage 写入物理列的值
+	Storage() interface{}
+	// Formula 公式求值时使用的值
+	Formula() interface{}
+}
+
+// Converter 字段类型的单元格值转换器
+type Converter interface {
+	Kind() Kind
+	// FromAPI 解析请求中的单元格值
+	FromAPI(raw interface{}) (CellValue, error)
+	// FromStorage 解析从物理列读出的值（JSON 列可能是 []byte、字符串或已解码的值）
+	FromStorage(raw interface{}) (CellValue, error)
+}
+
+var converters = map[string]Converter{}
+
+func register(c Converter, fieldTypes ...string) {
+	for _, t := range fieldTypes {
+		converters[t] = c
+	}
+}
+
+func init() {
+	register(textConverter{},
+		valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText, valueobject.TypeRichText,
+		valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone,
+		valueobject.TypeSelect, valueobject.TypeSingleSelect)
+	register(numberConverter{},
+		valueobject.TypeNumber, valueobject.TypeRating, valueobject.TypePercent, valueobject.TypeCurrency,
+		valueobject.TypeDuration, valueobject.TypeProgress, valueobject.TypeAutoNumber, valueobject.TypeCount)
+	register(checkboxConverter{}, valueobject.TypeCheckbox, valueobject.TypeBoolean)
+	register(dateConverter{},
+		valueobject.TypeDate, valueobject.TypeDateTime, valueobject.TypeCreatedTime, valueobject.TypeModifiedTime)
+	register(multiSelectConverter{}, valueobject.TypeMultipleSelect)
+	register(refConverter{kind: KindLink, titleKey: "title"}, valueobject.TypeLink)
+	register(refConverter{kind: KindUser, titleKey: "title"}, valueobject.TypeUser)
+	register(refConverter{kind: KindAttachment, titleKey: "name"}, valueobject.TypeAttachment)
+	register(jsonConverter{}, valueobject.TypeLocation, valueobject.TypeLookup)
+}
+
+// For 返回字段类型的转换器，自定义字段类型由插件转换；未注册的类型（公式、汇总等计算字段）返回 false
+func For(fieldType string) (Converter, bool) {
+	if c, ok := converters[fieldType]; ok {
+		return c, true
+	}
+	if plugin, ok := fieldplugin.Lookup(fieldType); ok {
+		return customConverter{plugin: plugin}, true
+	}
+	return nil, false
+}
+
+// IsReference 字段值是否为按ID引用其他对象的列表（关联记录、用户、附件）
+func IsReference(fieldType string) bool {
+	c, ok := For(fieldType)
+	if !ok {
+		return false
+	}
+	_, ok = c.(refConverter)
+	return ok
+}
+
+// FromAPI 按字段类型解析请求中的单元格值
+func FromAPI(fieldType string, raw interface{}) (CellValue, error) {
+	if raw == nil {
+		return Null, nil
+	}
+	c, ok := For(fieldType)
+	if !ok {
+		return RawValue{Value: raw}, nil
+	}
+	v, err := c.FromAPI(raw)
+	if err != nil {
+		return nil, fmt.Errorf("字段类型 %s 的单元格值无效: %w", fieldType, err)
+	}
+	return v, nil
+}
+
+// FromStorage 按字段类型解析从物理列读出的值
+func FromStorage(fieldType string, raw interface{}) (CellValue, error) {
+	if raw == nil {
+		return Null, nil
+	}
+	c, ok := For(fieldType)
+	if !ok {
+		return RawValue{Value: raw}, nil
+	}
+	v, err := c.FromStorage(raw)
+	if err != nil {
+		return nil, fmt.Errorf("字段类型 %s 的存储值无效: %w", fieldType, err)
+	}
+	return v, nil
+}
+
+// ToStorage 将请求中的单元格值转换为存储值，无法解析时原样返回（由校验器负责报错）
+func ToStorage(fieldType string, raw interface{}) interface{} {
+	v, err := FromAPI(fieldType, raw)
+	if err != nil {
+		return raw
+	}
+	return v.Storage()
+}
+
+// ToAPI 将存储值转换为 API 值，无法解析时原样返回
+func ToAPI(fieldType string, stored interface{}) interface{} {
+	v, err := FromStorage(fieldType, stored)
+	if err != nil {
+		return stored
+	}
+	return v.API()
+}
+
+// ToFormula 将单元格值（API 或存储表示）转换为公式值，无法解析时原样返回
+func ToFormula(fieldType string, raw interface{}) interface{} {
+	v, err := FromStorage(fieldType, raw)
+	if err != nil {
+		return raw
+	}
+	return v.Formula()
+}
+
+// FilterOperand 将视图过滤的比较值规范为列的存储类型：
+// 数字字段为 float64，复选框为 bool，日期为 time.Time，引用类字段为ID列表；
+// 列表中的每个值分别转换，无法解析的值（如相对日期关键字）原样保留
+func FilterOperand(fieldType string, raw interface{}) interface{} {
+	c, ok := For(fieldType)
+	if !ok || raw == nil {
+		return raw
+	}
+	switch c.Kind() {
+	case KindLink, KindUser, KindAttachment:
+		ids := RefIDs(raw)
+		operand := make([]interface{}, len(ids))
+		for i, id := range ids {
+			operand[i] = id
+		}
+		return operand
+	case KindNumber, KindCheckbox, KindDate:
+		if list, ok := raw.([]interface{}); ok {
+			operand := make([]interface{}, len(list))
+			for i, item := range list {
+				operand[i] = filterScalar(c, item)
+			}
+			return operand
+		}
+		return filterScalar(c, raw)
+	default:
+		return raw
+	}
+}
+
+func filterScalar(c Converter, raw interface{}) interface{} {
+	v, err := c.FromAPI(raw)
+	if err != nil || v.IsEmpty() {
+		return raw
+	}
+	return v.Storage()
+}
@@ -0,0 +1,183 @@
+package cellvalue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+func TestFromStorage_NormalizesColumnValues(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		fieldType string
+		stored    interface{}
+		want      interface{}
+	}{
+		{"numeric column as string", valueobject.TypeNumber, "12.50", 12.5},
+		{"numeric column as bytes", valueobject.TypeCurrency, []byte("3"), 3.0},
+		{"checkbox", valueobject.TypeCheckbox, true, true},
+		{"date", valueobject.TypeDate, day, day},
+		{"multiple select json", valueobject.TypeMultipleSelect, []byte(`["a","b"]`), []interface{}{"a", "b"}},
+		{"link text array literal", valueobject.TypeLink, "{rec1,rec2}", []interface{}{
+			map[string]interface{}{"id": "rec1"}, map[string]interface{}{"id": "rec2"},
+		}},
+		{"single user object", valueobject.TypeUser, `{"id":"usr1","title":"Ann","email":"a@x.io"}`,
+			map[string]interface{}{"id": "usr1", "title": "Ann", "email": "a@x.io"}},
+		{"location json", valueobject.TypeLocation, []byte(`{"lat":1,"lng":2}`), map[string]interface{}{"lat": 1.0, "lng": 2.0}},
+		{"formula kept raw", valueobject.TypeFormula, "42", "42"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := FromStorage(tc.fieldType, tc.stored)
+			if err != nil {
+				t.Fatalf("FromStorage: %v", err)
+			}
+			if got := v.API(); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFromAPI_TypedValues(t *testing.T) {
+	v, err := FromAPI(valueobject.TypeAttachment, []interface{}{
+		map[string]interface{}{"id": "att1", "name": "a.png", "size": 10.0},
+		map[string]interface{}{"token": "tok2", "name": "b.pdf"},
+	})
+	if err != nil {
+		t.Fatalf("FromAPI: %v", err)
+	}
+	att, ok := v.(AttachmentValue)
+	if !ok {
+		t.Fatalf("expected AttachmentValue, got %T", v)
+	}
+	if items := att.Items(); len(items) != 2 || items[0].Title != "a.png" || items[1].Extra["token"] != "tok2" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if got := v.Formula(); !reflect.DeepEqual(got, []interface{}{"a.png", "b.pdf"}) {
+		t.Fatalf("expected attachment names in formulas, got %#v", got)
+	}
+
+	if _, err := FromAPI(valueobject.TypeUser, []interface{}{map[string]interface{}{"title": "no id"}}); err == nil {
+		t.Fatal("expected user without id to be rejected")
+	}
+	if _, err := FromAPI(valueobject.TypeNumber, "many"); err == nil {
+		t.Fatal("expected non-numeric string to be rejected")
+	}
+	if v, _ := FromAPI(valueobject.TypeNumber, ""); !v.IsEmpty() {
+		t.Fatalf("expected empty string to be an empty number, got %#v", v)
+	}
+}
+
+func TestToStorage(t *testing.T) {
+	if got := ToStorage(valueobject.TypeNumber, "7"); got != 7.0 {
+		t.Fatalf("expected numeric string stored as float64, got %#v", got)
+	}
+	if got := ToStorage(valueobject.TypeCheckbox, "false"); got != false {
+		t.Fatalf("expected checkbox string stored as bool, got %#v", got)
+	}
+	want := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	if got := ToStorage(valueobject.TypeDateTime, "2024-03-01T09:30:00Z"); got != want {
+		t.Fatalf("expected parsed time, got %#v", got)
+	}
+	// 无法解析的值原样返回，由校验器报错
+	if got := ToStorage(valueobject.TypeNumber, "many"); got != "many" {
+		t.Fatalf("expected invalid value unchanged, got %#v", got)
+	}
+}
+
+func TestToFormula(t *testing.T) {
+	cases := []struct {
+		name      string
+		fieldType string
+		value     interface{}
+		want      interface{}
+	}{
+		{"date as RFC3339", valueobject.TypeDateTime, time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), "2024-03-01T09:30:00Z"},
+		{"numeric string as number", valueobject.TypeNumber, "2.5", 2.5},
+		{"links as titles", valueobject.TypeLink, []interface{}{
+			map[string]interface{}{"id": "rec1", "title": "Alpha"}, map[string]interface{}{"id": "rec2"},
+		}, []interface{}{"Alpha", "rec2"}},
+		{"single user as title", valueobject.TypeUser, map[string]interface{}{"id": "usr1", "title": "Ann"}, "Ann"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ToFormula(tc.fieldType, tc.value); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterOperand(t *testing.T) {
+	if got := FilterOperand(valueobject.TypeNumber, "10"); got != 10.0 {
+		t.Fatalf("expected numeric operand, got %#v", got)
+	}
+	if got := FilterOperand(valueobject.TypeCheckbox, "true"); got != true {
+		t.Fatalf("expected boolean operand, got %#v", got)
+	}
+	got := FilterOperand(valueobject.TypeDate, []interface{}{"2024-01-01", "today"})
+	list, ok := got.([]interface{})
+	if !ok || len(list) != 2 {
+		t.Fatalf("expected two operands, got %#v", got)
+	}
+	if _, ok := list[0].(time.Time); !ok || list[1] != "today" {
+		t.Fatalf("expected parsed date and untouched keyword, got %#v", list)
+	}
+	refs := FilterOperand(valueobject.TypeUser, []interface{}{"usr1", map[string]interface{}{"id": "usr2", "title": "Bo"}})
+	if !reflect.DeepEqual(refs, []interface{}{"usr1", "usr2"}) {
+		t.Fatalf("expected user IDs, got %#v", refs)
+	}
+	if got := FilterOperand(valueobject.TypeText, 5); got != 5 {
+		t.Fatalf("expected text operand unchanged, got %#v", got)
+	}
+}
+
+func TestRefIDs(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{"id", "rec1", []string{"rec1"}},
+		{"ids", []interface{}{"rec1", "rec2"}, []string{"rec1", "rec2"}},
+		{"object", map[string]interface{}{"id": "rec1"}, []string{"rec1"}},
+		{"json", []byte(`[{"id":"rec1"},{"id":"rec2","title":"B"}]`), []string{"rec1", "rec2"}},
+		{"invalid", 3, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RefIDs(tc.value); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+type plateField struct{ fieldplugin.Base }
+
+func (plateField) Name() string                     { return "x-plate" }
+func (plateField) Storage() fieldplugin.StorageType { return fieldplugin.StorageText }
+func (plateField) FormulaValue(value interface{}) interface{} {
+	return strings.ToUpper(fmt.Sprint(value))
+}
+
+func TestCustomFieldType(t *testing.T) {
+	fieldplugin.MustRegister(plateField{})
+
+	if got := ToAPI("x-plate", "沪a123"); got != "沪a123" {
+		t.Fatalf("expected stored text unchanged, got %#v", got)
+	}
+	if got := ToFormula("x-plate", "沪a123"); got != "沪A123" {
+		t.Fatalf("expected plugin formula value, got %#v", got)
+	}
+	if c, ok := For("x-plate"); !ok || c.Kind() != KindCustom {
+		t.Fatalf("expected custom converter, got %v", c)
+	}
+}
@@ -0,0 +1,403 @@
+package cellvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+// textConverter 文本类字段；数字与布尔值按文本保存
+type textConverter struct{}
+
+func (textConverter) Kind() Kind { return KindText }
+
+func (textConverter) FromAPI(raw interface{}) (CellValue, error) {
+	switch v := raw.(type) {
+	case string:
+		return TextValue(v), nil
+	case []byte:
+		return TextValue(v), nil
+	case bool:
+		return TextValue(strconv.FormatBool(v)), nil
+	}
+	if f, ok := ToFloat(raw); ok {
+		return TextValue(strconv.FormatFloat(f, 'f', -1, 64)), nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为文本", raw)
+}
+
+func (c textConverter) FromStorage(raw interface{}) (CellValue, error) { return c.FromAPI(raw) }
+
+// numberConverter 数字类字段；NUMERIC 列可能读出字符串或字节
+type numberConverter struct{}
+
+func (numberConverter) Kind() Kind { return KindNumber }
+
+func (numberConverter) FromAPI(raw interface{}) (CellValue, error) {
+	if s, ok := raw.(string); ok && strings.TrimSpace(s) == "" {
+		return Null, nil
+	}
+	f, ok := ToFloat(raw)
+	if !ok {
+		return nil, fmt.Errorf("无法将 %v 转换为数字", raw)
+	}
+	return NumberValue(f), nil
+}
+
+func (c numberConverter) FromStorage(raw interface{}) (CellValue, error) { return c.FromAPI(raw) }
+
+// checkboxConverter 复选框字段
+type checkboxConverter struct{}
+
+func (checkboxConverter) Kind() Kind { return KindCheckbox }
+
+func (checkboxConverter) FromAPI(raw interface{}) (CellValue, error) {
+	switch v := raw.(type) {
+	case bool:
+		return CheckboxValue(v), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return CheckboxValue(false), nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("无法将 %q 转换为布尔值", v)
+		}
+		return CheckboxValue(b), nil
+	}
+	if f, ok := ToFloat(raw); ok {
+		return CheckboxValue(f != 0), nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为布尔值", raw)
+}
+
+func (c checkboxConverter) FromStorage(raw interface{}) (CellValue, error) { return c.FromAPI(raw) }
+
+// dateLayouts 可解析的日期字符串格式
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02",
+}
+
+// dateConverter 日期时间字段；数字按 Unix 毫秒时间戳解析（与前端一致）
+type dateConverter struct{}
+
+func (dateConverter) Kind() Kind { return KindDate }
+
+func (dateConverter) FromAPI(raw interface{}) (CellValue, error) {
+	switch v := raw.(type) {
+	case time.Time:
+		return DateValue(v), nil
+	case *time.Time:
+		if v == nil {
+			return Null, nil
+		}
+		return DateValue(*v), nil
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return Null, nil
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return DateValue(t), nil
+			}
+		}
+		return nil, fmt.Errorf("无法解析日期 %q", v)
+	}
+	if ms, ok := ToFloat(raw); ok {
+		return DateValue(time.UnixMilli(int64(ms)).UTC()), nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为日期", raw)
+}
+
+func (c dateConverter) FromStorage(raw interface{}) (CellValue, error) { return c.FromAPI(raw) }
+
+// multiSelectConverter 多选字段；接受选项名称、名称列表或 {"name": ..} 对象
+type multiSelectConverter struct{}
+
+func (multiSelectConverter) Kind() Kind { return KindMultiSelect }
+
+func (multiSelectConverter) FromAPI(raw interface{}) (CellValue, error) {
+	switch v := raw.(type) {
+	case string:
+		if v == "" {
+			return MultiSelectValue{}, nil
+		}
+		return MultiSelectValue{v}, nil
+	case []string:
+		return MultiSelectValue(v), nil
+	case []interface{}:
+		names := make(MultiSelectValue, 0, len(v))
+		for _, item := range v {
+			switch choice := item.(type) {
+			case string:
+				names = append(names, choice)
+			case map[string]interface{}:
+				name, ok := choice["name"].(string)
+				if !ok {
+					return nil, fmt.Errorf("选项对象缺少 name")
+				}
+				names = append(names, name)
+			default:
+				return nil, fmt.Errorf("选项必须是字符串，实际为 %T", item)
+			}
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("无法将 %T 转换为多选值", raw)
+}
+
+func (c multiSelectConverter) FromStorage(raw interface{}) (CellValue, error) {
+	decoded, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromAPI(decoded)
+}
+
+// refConverter 引用类字段（关联记录、用户、附件）
+type refConverter struct {
+	kind     Kind
+	titleKey string
+}
+
+func (c refConverter) Kind() Kind { return c.kind }
+
+func (c refConverter) FromAPI(raw interface{}) (CellValue, error) {
+	list := refList{kind: c.kind, titleKey: c.titleKey}
+	switch v := raw.(type) {
+	case string:
+		if v == "" {
+			return c.wrap(list), nil
+		}
+		list.items, list.single = []RefItem{{ID: v}}, true
+	case []string:
+		for _, id := range v {
+			list.items = append(list.items, RefItem{ID: id})
+		}
+	case map[string]interface{}:
+		item, err := c.item(v)
+		if err != nil {
+			return nil, err
+		}
+		list.items, list.single = []RefItem{item}, true
+	case []interface{}:
+		for i, element := range v {
+			switch e := element.(type) {
+			case string:
+				list.items = append(list.items, RefItem{ID: e})
+			case map[string]interface{}:
+				item, err := c.item(e)
+				if err != nil {
+					return nil, fmt.Errorf("第 %d 项: %w", i, err)
+				}
+				list.items = append(list.items, item)
+			default:
+				return nil, fmt.Errorf("第 %d 项必须是ID或对象，实际为 %T", i, element)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("无法将 %T 转换为%s", raw, c.label())
+	}
+	return c.wrap(list), nil
+}
+
+func (c refConverter) FromStorage(raw interface{}) (CellValue, error) {
+	if s, ok := raw.(string); ok && isArrayLiteral(s) {
+		return c.FromAPI(parseArrayLiteral(s))
+	}
+	decoded, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromAPI(decoded)
+}
+
+// item 解析引用对象，附件可以没有ID（只有 token 或路径）
+func (c refConverter) item(obj map[string]interface{}) (RefItem, error) {
+	item := RefItem{Extra: make(map[string]interface{}, len(obj))}
+	for key, value := range obj {
+		switch key {
+		case "id":
+			id, ok := value.(string)
+			if !ok {
+				return RefItem{}, fmt.Errorf("id 必须是字符串")
+			}
+			item.ID = id
+		case c.titleKey:
+			item.Title = fmt.Sprint(value)
+		default:
+			item.Extra[key] = value
+		}
+	}
+	if item.ID == "" && c.kind != KindAttachment {
+		return RefItem{}, fmt.Errorf("%s缺少 id", c.label())
+	}
+	return item, nil
+}
+
+func (c refConverter) wrap(list refList) CellValue {
+	switch c.kind {
+	case KindUser:
+		return UserValue{list}
+	case KindAttachment:
+		return AttachmentValue{list}
+	default:
+		return LinkValue{list}
+	}
+}
+
+func (c refConverter) label() string {
+	switch c.kind {
+	case KindUser:
+		return "用户"
+	case KindAttachment:
+		return "附件"
+	default:
+		return "关联记录"
+	}
+}
+
+// jsonConverter 结构化字段，只负责 JSON 解码
+type jsonConverter struct{}
+
+func (jsonConverter) Kind() Kind { return KindJSON }
+
+func (jsonConverter) FromAPI(raw interface{}) (CellValue, error) { return JSONValue{Value: raw}, nil }
+
+func (jsonConverter) FromStorage(raw interface{}) (CellValue, error) {
+	decoded, err := decodeJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	return JSONValue{Value: decoded}, nil
+}
+
+// customConverter 自定义字段类型：写入值已由插件校验，读出的值交给插件转换
+type customConverter struct {
+	plugin fieldplugin.FieldType
+}
+
+func (customConverter) Kind() Kind { return KindCustom }
+
+func (c customConverter) FromAPI(raw interface{}) (CellValue, error) {
+	return CustomValue{Value: raw, plugin: c.plugin}, nil
+}
+
+func (c customConverter) FromStorage(raw interface{}) (CellValue, error) {
+	value, err := c.plugin.FromStorage(raw)
+	if err != nil {
+		return nil, err
+	}
+	return CustomValue{Value: value, plugin: c.plugin}, nil
+}
+
+// ToFloat 将数字、数字字符串或字节转换为 float64
+func ToFloat(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	case []byte:
+		return ToFloat(string(v))
+	}
+	return 0, false
+}
+
+// RefIDs 提取引用类单元格中的ID（ID、ID列表、对象或对象列表，JSON 编码亦可）
+func RefIDs(raw interface{}) []string {
+	v, err := refConverter{kind: KindLink, titleKey: "title"}.FromStorage(raw)
+	if err != nil {
+		return nil
+	}
+	if refs, ok := v.(LinkValue); ok {
+		return refs.IDs()
+	}
+	return nil
+}
+
+// decodeJSON 解码 JSON 列读出的值：[]byte、JSON 字符串或实现了 json.Marshaler 的类型（如 datatypes.JSON）；
+// 已解码的值与非 JSON 字符串原样返回
+func decodeJSON(raw interface{}) (interface{}, error) {
+	var data []byte
+	switch v := raw.(type) {
+	case []byte:
+		data = v
+	case string:
+		s := strings.TrimSpace(v)
+		if !strings.HasPrefix(s, "[") && !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, `"`) {
+			return v, nil
+		}
+		data = []byte(s)
+	case json.Marshaler:
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	default:
+		return raw, nil
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("JSON 解码失败: %w", err)
+	}
+	return decoded, nil
+}
+
+// isArrayLiteral 是否为 PostgreSQL 数组字面量（如 TEXT[] 列的 {rec1,rec2}）
+func isArrayLiteral(s string) bool {
+	return strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && !strings.Contains(s, ":")
+}
+
+func parseArrayLiteral(s string) []string {
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	if body == "" {
+		return []string{}
+	}
+	parts := strings.Split(body, ",")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"`)
+	}
+	return parts
+}
@@ -0,0 +1,411 @@
+package cellvalue
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// FormatOptions 展示文本的格式化参数
+type FormatOptions struct {
+	TimeZone *time.Location // 请求用户的时区（字段开启统一时区时以字段为准），为空时为 UTC
+	Locale   string         // BCP 47 语言标签（如 en-US、zh-CN、de），决定数字分隔符与默认日期格式
+}
+
+// currencySymbols 常用货币代码对应的符号，其他货币以代码加空格作为前缀
+var currencySymbols = map[string]string{
+	"USD": "$", "CNY": "¥", "JPY": "¥", "EUR": "€", "GBP": "£",
+	"KRW": "₩", "INR": "₹", "HKD": "HK$", "TWD": "NT$",
+}
+
+// dateTokens 日期格式（dayjs 风格）到 Go 时间布局的替换，长的记号在前
+var dateTokens = strings.NewReplacer(
+	"YYYY", "2006", "YY", "06",
+	"MM", "01", "M", "1",
+	"DD", "02", "D", "2",
+)
+
+// Format 按字段类型与字段选项将单元格值（API 或存储表示）转换为展示文本，空值返回空串
+//
+// 数字按精度、千分位与百分比/货币格式，时长按时长格式，日期按日期格式、12/24 小时制与时区，
+// 选项、关联、用户与附件显示名称或标题，自定义字段类型由插件格式化；
+// 公式、汇总、查找等计算字段使用其 formatting 配置
+func Format(fieldType string, options *valueobject.FieldOptions, raw interface{}, opts FormatOptions) string {
+	if raw == nil {
+		return ""
+	}
+	if options == nil {
+		options = valueobject.NewFieldOptions()
+	}
+	c, ok := For(fieldType)
+	if !ok {
+		return formatComputed(computedFormatting(options), raw, opts)
+	}
+	v, err := c.FromStorage(raw)
+	if err != nil {
+		return plainText(raw)
+	}
+	if v.IsEmpty() {
+		return ""
+	}
+
+	switch val := v.(type) {
+	case TextValue:
+		if options.Select != nil {
+			return choiceName(options.Select, string(val))
+		}
+		return string(val)
+	case NumberValue:
+		return formatNumberField(fieldType, options, float64(val), opts)
+	case CheckboxValue:
+		return "true"
+	case DateValue:
+		return formatDateField(fieldType, dateOptions(options), time.Time(val), opts)
+	case MultiSelectValue:
+		names := make([]string, len(val))
+		for i, name := range val {
+			names[i] = name
+			if options.Select != nil {
+				names[i] = choiceName(options.Select, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	case LinkValue:
+		return refTitles(val.Items())
+	case UserValue:
+		return refTitles(val.Items())
+	case AttachmentValue:
+		return refTitles(val.Items())
+	case CustomValue:
+		return val.plugin.Format(val.Value, options.Custom)
+	case JSONValue:
+		if fieldType == valueobject.TypeLocation {
+			if loc, err := valueobject.ParseLocation(val.Value); err == nil {
+				return formatLocation(loc, opts)
+			}
+		}
+		return formatComputed(computedFormatting(options), val.Value, opts)
+	}
+	return plainText(v.API())
+}
+
+// dateOptions 日期字段的格式选项：通用 formatting 配置覆盖日期选项中的同名设置
+func dateOptions(options *valueobject.FieldOptions) *valueobject.DateOptions {
+	date := valueobject.DateOptions{}
+	if options.Date != nil {
+		date = *options.Date
+	}
+	if f := options.Formatting; f != nil {
+		if f.DateFormat != "" {
+			date.Format = f.DateFormat
+		}
+		if f.TimeFormat != "" {
+			date.TimeFormat = f.TimeFormat
+			date.IncludeTime = f.TimeFormat != "none"
+		}
+		if f.TimeZone != "" {
+			date.TimeZone = f.TimeZone
+		}
+	}
+	return &date
+}
+
+// computedFormatting 计算字段（公式、汇总、查找）的格式化配置
+func computedFormatting(options *valueobject.FieldOptions) *valueobject.FormattingOptions {
+	switch {
+	case options.Formula != nil && options.Formula.Formatting != nil:
+		formatting := *options.Formula.Formatting
+		if formatting.TimeZone == "" {
+			formatting.TimeZone = options.Formula.TimeZone
+		}
+		return &formatting
+	case options.Rollup != nil && options.Rollup.Formatting != nil:
+		formatting := *options.Rollup.Formatting
+		if formatting.TimeZone == "" {
+			formatting.TimeZone = options.Rollup.TimeZone
+		}
+		return &formatting
+	case options.Lookup != nil && options.Lookup.Formatting != nil:
+		return options.Lookup.Formatting
+	}
+	return options.Formatting
+}
+
+// formatComputed 按 formatting 配置格式化计算结果；列表（查找结果）逐项格式化后以逗号连接
+func formatComputed(formatting *valueobject.FormattingOptions, raw interface{}, opts FormatOptions) string {
+	if list, ok := raw.([]interface{}); ok {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			if text := formatComputed(formatting, item, opts); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	if formatting == nil {
+		return plainText(raw)
+	}
+
+	switch formatting.Type {
+	case "number":
+		if n, ok := ToFloat(raw); ok {
+			precision := -1
+			if formatting.Precision != nil {
+				precision = *formatting.Precision
+			}
+			text := formatDecimal(n, precision, formatting.ShowCommas, opts.Locale)
+			if formatting.Currency != "" {
+				return withCurrency(text, formatting.Currency)
+			}
+			return text
+		}
+	case "duration":
+		if n, ok := ToFloat(raw); ok {
+			return valueobject.FormatDuration(n, formatting.DurationFormat)
+		}
+	case "date":
+		if v, err := (dateConverter{}).FromAPI(raw); err == nil && !v.IsEmpty() {
+			date := &valueobject.DateOptions{
+				Format:      formatting.DateFormat,
+				IncludeTime: formatting.TimeFormat != "" && formatting.TimeFormat != "none",
+				TimeFormat:  formatting.TimeFormat,
+				TimeZone:    formatting.TimeZone,
+				// 计算字段配置了时区时所有协作者一致
+				UseSameTimeZone: formatting.TimeZone != "",
+			}
+			return formatDateField(valueobject.TypeDate, date, time.Time(v.(DateValue)), opts)
+		}
+	}
+	return plainText(raw)
+}
+
+// formatNumberField 数字类字段：评分、自动编号与计数为整数，时长按时长格式，进度为百分数，
+// 数字、百分比与货币按数字选项（精度、千分位、格式）
+func formatNumberField(fieldType string, options *valueobject.FieldOptions, n float64, opts FormatOptions) string {
+	switch fieldType {
+	case valueobject.TypeRating, valueobject.TypeAutoNumber, valueobject.TypeCount:
+		return formatDecimal(n, 0, false, opts.Locale)
+	case valueobject.TypeDuration:
+		format := ""
+		if options.Duration != nil {
+			format = options.Duration.Format
+		}
+		return valueobject.FormatDuration(n, format)
+	case valueobject.TypeProgress:
+		return formatDecimal(n, -1, false, opts.Locale) + "%"
+	}
+
+	number := options.Number
+	if number == nil {
+		number = &valueobject.NumberOptions{}
+	}
+	format := number.Format
+	if format == "" {
+		switch fieldType {
+		case valueobject.TypePercent:
+			format = "percent"
+		case valueobject.TypeCurrency:
+			format = "currency"
+		}
+	}
+	precision := -1
+	if number.Precision != nil {
+		precision = *number.Precision
+	}
+
+	switch format {
+	case "percent":
+		return formatDecimal(n*100, precision, number.ShowCommas, opts.Locale) + "%"
+	case "currency":
+		if precision < 0 {
+			precision = 2
+		}
+		currency := number.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+		return withCurrency(formatDecimal(n, precision, number.ShowCommas, opts.Locale), currency)
+	}
+	return formatDecimal(n, precision, number.ShowCommas, opts.Locale)
+}
+
+// withCurrency 为格式化后的数字加上货币符号（负号在符号之前）
+func withCurrency(text, currency string) string {
+	symbol, ok := currencySymbols[strings.ToUpper(currency)]
+	if !ok {
+		symbol = strings.ToUpper(currency) + " "
+	}
+	if strings.HasPrefix(text, "-") {
+		return "-" + symbol + text[1:]
+	}
+	return symbol + text
+}
+
+// formatDecimal 按精度（-1 表示最短表示）格式化数字，showCommas 时按地区插入千分位分隔符
+func formatDecimal(n float64, precision int, showCommas bool, locale string) string {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	text := strconv.FormatFloat(n, 'f', precision, 64)
+	group, decimal := localeSeparators(locale)
+
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(text, ".")
+	if showCommas && len(intPart) > 3 {
+		var b strings.Builder
+		for i, digit := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(group)
+			}
+			b.WriteRune(digit)
+		}
+		intPart = b.String()
+	}
+	if hasFrac {
+		return sign + intPart + decimal + fracPart
+	}
+	return sign + intPart
+}
+
+// formatDateField 日期字段：时区见 DateOptions.Location（统一时区或请求用户的时区）；
+// 日期时间、创建时间与修改时间字段始终包含时间
+func formatDateField(fieldType string, date *valueobject.DateOptions, t time.Time, opts FormatOptions) string {
+	if date == nil {
+		date = &valueobject.DateOptions{}
+	}
+	loc := date.Location(opts.TimeZone)
+
+	format := date.Format
+	if format == "" {
+		format = localeDateFormat(opts.Locale)
+	}
+	layout := dateTokens.Replace(format)
+
+	includeTime := date.IncludeTime
+	switch fieldType {
+	case valueobject.TypeDateTime, valueobject.TypeCreatedTime, valueobject.TypeModifiedTime:
+		includeTime = true
+	}
+	if includeTime {
+		timeFormat := date.TimeFormat
+		if timeFormat == "" && languageTag(opts.Locale) == "en-us" {
+			timeFormat = "12h"
+		}
+		if timeFormat == "12h" {
+			layout += " 3:04 PM"
+		} else {
+			layout += " 15:04"
+		}
+	}
+	return t.In(loc).Format(layout)
+}
+
+// formatLocation 地理位置：优先显示地点名称或地址，否则显示经纬度
+func formatLocation(loc *valueobject.Location, opts FormatOptions) string {
+	if loc.Name != "" {
+		return loc.Name
+	}
+	if loc.Address != "" {
+		return loc.Address
+	}
+	return formatDecimal(loc.Lat, -1, false, opts.Locale) + ", " + formatDecimal(loc.Lng, -1, false, opts.Locale)
+}
+
+// choiceName 选项值（名称或选项ID）对应的选项名称
+func choiceName(options *valueobject.SelectOptions, value string) string {
+	for _, choice := range options.Choices {
+		if choice.ID == value {
+			return choice.Name
+		}
+	}
+	return value
+}
+
+// refTitles 引用项的标题（无标题时为ID），以逗号连接
+func refTitles(items []RefItem) string {
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Title != "" {
+			titles = append(titles, item.Title)
+		} else if item.ID != "" {
+			titles = append(titles, item.ID)
+		}
+	}
+	return strings.Join(titles, ", ")
+}
+
+// plainText 没有格式化配置的值：字符串原样，数字取最短表示，对象取 name/title/id，其余为 JSON
+func plainText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := plainText(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]interface{}:
+		for _, key := range []string{"name", "title", "id"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+	}
+	if n, ok := ToFloat(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// languageTag 规范化的语言标签（小写，下划线替换为连字符）
+func languageTag(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// localeSeparators 地区的千分位与小数分隔符
+func localeSeparators(locale string) (group, decimal string) {
+	lang, _, _ := strings.Cut(languageTag(locale), "-")
+	switch lang {
+	case "de", "es", "it", "nl", "pt", "id", "tr", "da", "el":
+		return ".", ","
+	case "fr", "ru", "pl", "sv", "fi", "cs", "nb", "uk":
+		return "\u00a0", ","
+	}
+	return ",", "."
+}
+
+// localeDateFormat 字段未配置日期格式时地区的默认格式（未指定地区时为 ISO 格式）
+func localeDateFormat(locale string) string {
+	tag := languageTag(locale)
+	lang, _, _ := strings.Cut(tag, "-")
+	switch {
+	case tag == "en-us":
+		return "MM/DD/YYYY"
+	case lang == "de", lang == "ru", lang == "pl", lang == "tr", lang == "fi", lang == "cs", lang == "nb", lang == "uk":
+		return "DD.MM.YYYY"
+	case lang == "en", lang == "fr", lang == "es", lang == "it", lang == "pt", lang == "nl", lang == "id", lang == "el":
+		return "DD/MM/YYYY"
+	}
+	return "YYYY-MM-DD"
+}
@@ -0,0 +1,80 @@
+package cellvalue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestFormat_FieldOptions(t *testing.T) {
+	two := 2
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	moment := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name      string
+		fieldType string
+		options   *valueobject.FieldOptions
+		raw       interface{}
+		opts      FormatOptions
+		want      string
+	}{
+		{"number precision and commas", valueobject.TypeNumber,
+			&valueobject.FieldOptions{Number: &valueobject.NumberOptions{Precision: &two, ShowCommas: true}},
+			"1234567.5", FormatOptions{}, "1,234,567.50"},
+		{"number german separators", valueobject.TypeNumber,
+			&valueobject.FieldOptions{Number: &valueobject.NumberOptions{Precision: &two, ShowCommas: true}},
+			1234.5, FormatOptions{Locale: "de-DE"}, "1.234,50"},
+		{"currency symbol", valueobject.TypeCurrency,
+			&valueobject.FieldOptions{Number: &valueobject.NumberOptions{Currency: "EUR"}},
+			-3.5, FormatOptions{}, "-€3.50"},
+		{"percent", valueobject.TypePercent, nil, 0.25, FormatOptions{}, "25%"},
+		{"duration", valueobject.TypeDuration,
+			&valueobject.FieldOptions{Duration: &valueobject.DurationOptions{Format: "h:mm"}},
+			5400.0, FormatOptions{}, "1:30"},
+		{"date with field format", valueobject.TypeDate,
+			&valueobject.FieldOptions{Date: &valueobject.DateOptions{Format: "DD/MM/YYYY"}},
+			moment, FormatOptions{}, "01/03/2024"},
+		{"datetime in request timezone", valueobject.TypeDateTime, nil,
+			"2024-03-01T18:30:00Z", FormatOptions{TimeZone: shanghai}, "2024-03-02 02:30"},
+		{"shared field timezone wins", valueobject.TypeDate,
+			&valueobject.FieldOptions{Date: &valueobject.DateOptions{IncludeTime: true, TimeZone: "UTC", UseSameTimeZone: true}},
+			moment, FormatOptions{TimeZone: shanghai, Locale: "en-US"}, "03/01/2024 6:30 PM"},
+		{"user timezone over unshared field timezone", valueobject.TypeDate,
+			&valueobject.FieldOptions{Date: &valueobject.DateOptions{IncludeTime: true, TimeZone: "UTC"}},
+			moment, FormatOptions{TimeZone: shanghai}, "2024-03-02 02:30"},
+		{"formatting overrides date options", valueobject.TypeDate,
+			&valueobject.FieldOptions{Date: &valueobject.DateOptions{Format: "YYYY-MM-DD"}, Formatting: &valueobject.FormattingOptions{DateFormat: "YYYY年MM月DD日"}},
+			moment, FormatOptions{}, "2024年03月01日"},
+		{"select choice id", valueobject.TypeSingleSelect,
+			&valueobject.FieldOptions{Select: &valueobject.SelectOptions{Choices: []valueobject.SelectChoice{{ID: "cho1", Name: "Done"}}}},
+			"cho1", FormatOptions{}, "Done"},
+		{"multiple select", valueobject.TypeMultipleSelect, nil, []interface{}{"a", "b"}, FormatOptions{}, "a, b"},
+		{"link titles", valueobject.TypeLink, nil,
+			[]interface{}{map[string]interface{}{"id": "rec1", "title": "Alpha"}, map[string]interface{}{"id": "rec2"}},
+			FormatOptions{}, "Alpha, rec2"},
+		{"checkbox unchecked", valueobject.TypeCheckbox, nil, false, FormatOptions{}, ""},
+		{"formula number formatting", valueobject.TypeFormula,
+			&valueobject.FieldOptions{Formula: &valueobject.FormulaOptions{Formatting: &valueobject.FormattingOptions{Type: "number", Precision: &two}}},
+			3.14159, FormatOptions{}, "3.14"},
+		{"formula without formatting", valueobject.TypeFormula, nil, 42.0, FormatOptions{}, "42"},
+		{"location address", valueobject.TypeLocation, nil,
+			map[string]interface{}{"lat": 1.0, "lng": 2.0, "address": "Main St"}, FormatOptions{}, "Main St"},
+		{"null", valueobject.TypeText, nil, nil, FormatOptions{}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Format(tc.fieldType, tc.options, tc.raw, tc.opts); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFormat_DoesNotMutateOptions(t *testing.T) {
+	options := &valueobject.FieldOptions{Date: &valueobject.DateOptions{}}
+	Format(valueobject.TypeDateTime, options, time.Now(), FormatOptions{})
+	if options.Date.IncludeTime {
+		t.Fatal("formatting must not modify field options")
+	}
+}
@@ -0,0 +1,186 @@
+package cellvalue
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+// Null 空单元格
+var Null CellValue = nullValue{}
+
+type nullValue struct{}
+
+func (nullValue) Kind() Kind           { return KindNull }
+func (nullValue) IsEmpty() bool        { return true }
+func (nullValue) API() interface{}     { return nil }
+func (nullValue) Storage() interface{} { return nil }
+func (nullValue) Formula() interface{} { return nil }
+
+// TextValue 文本值（单行、多行、富文本、邮箱、链接、电话、单选）
+type TextValue string
+
+func (v TextValue) Kind() Kind           { return KindText }
+func (v TextValue) IsEmpty() bool        { return v == "" }
+func (v TextValue) API() interface{}     { return string(v) }
+func (v TextValue) Storage() interface{} { return string(v) }
+func (v TextValue) Formula() interface{} { return string(v) }
+
+// NumberValue 数字值（数字、评分、百分比、货币、时长、进度）
+type NumberValue float64
+
+func (v NumberValue) Kind() Kind           { return KindNumber }
+func (v NumberValue) IsEmpty() bool        { return false }
+func (v NumberValue) API() interface{}     { return float64(v) }
+func (v NumberValue) Storage() interface{} { return float64(v) }
+func (v NumberValue) Formula() interface{} { return float64(v) }
+
+// CheckboxValue 复选框值
+type CheckboxValue bool
+
+func (v CheckboxValue) Kind() Kind           { return KindCheckbox }
+func (v CheckboxValue) IsEmpty() bool        { return !bool(v) }
+func (v CheckboxValue) API() interface{}     { return bool(v) }
+func (v CheckboxValue) Storage() interface{} { return bool(v) }
+func (v CheckboxValue) Formula() interface{} { return bool(v) }
+
+// DateValue 日期时间值
+// 公式中以 RFC3339 字符串参与计算（日期函数按字符串解析），避免 time.Time 被格式化为 Go 的默认字符串
+type DateValue time.Time
+
+func (v DateValue) Kind() Kind           { return KindDate }
+func (v DateValue) IsEmpty() bool        { return time.Time(v).IsZero() }
+func (v DateValue) API() interface{}     { return time.Time(v) }
+func (v DateValue) Storage() interface{} { return time.Time(v) }
+func (v DateValue) Formula() interface{} { return time.Time(v).Format(time.RFC3339) }
+
+// MultiSelectValue 多选值（选项名称列表）
+type MultiSelectValue []string
+
+func (v MultiSelectValue) Kind() Kind           { return KindMultiSelect }
+func (v MultiSelectValue) IsEmpty() bool        { return len(v) == 0 }
+func (v MultiSelectValue) Storage() interface{} { return []string(v) }
+func (v MultiSelectValue) Formula() interface{} { return []string(v) }
+
+// API 与 JSON 解码结果一致，为 []interface{}
+func (v MultiSelectValue) API() interface{} {
+	names := make([]interface{}, len(v))
+	for i, name := range v {
+		names[i] = name
+	}
+	return names
+}
+
+// RefItem 引用项（关联记录、用户或附件）
+type RefItem struct {
+	ID    string
+	Title string                 // 关联记录与用户为 title，附件为 name
+	Extra map[string]interface{} // 其余属性（邮箱、头像、附件路径与大小等）原样保留
+}
+
+// refList 引用列表；single 为 true 时以单个对象表示（单用户字段）
+type refList struct {
+	kind     Kind
+	titleKey string
+	items    []RefItem
+	single   bool
+}
+
+func (v refList) Kind() Kind    { return v.kind }
+func (v refList) IsEmpty() bool { return len(v.items) == 0 }
+
+// Items 引用项列表
+func (v refList) Items() []RefItem { return v.items }
+
+// IDs 引用的ID列表
+func (v refList) IDs() []string {
+	var ids []string
+	for _, item := range v.items {
+		if item.ID != "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
+func (v refList) API() interface{} {
+	if v.single && len(v.items) == 1 {
+		return v.object(v.items[0])
+	}
+	list := make([]interface{}, len(v.items))
+	for i, item := range v.items {
+		list[i] = v.object(item)
+	}
+	return list
+}
+
+// Storage 存储为与 API 相同的对象，读取时无需再关联查询标题
+func (v refList) Storage() interface{} { return v.API() }
+
+// Formula 公式中引用类字段的值为标题（无标题时为ID）
+func (v refList) Formula() interface{} {
+	titles := make([]interface{}, len(v.items))
+	for i, item := range v.items {
+		titles[i] = item.ID
+		if item.Title != "" {
+			titles[i] = item.Title
+		}
+	}
+	if v.single && len(titles) == 1 {
+		return titles[0]
+	}
+	return titles
+}
+
+func (v refList) object(item RefItem) map[string]interface{} {
+	obj := make(map[string]interface{}, len(item.Extra)+2)
+	for key, value := range item.Extra {
+		obj[key] = value
+	}
+	if item.ID != "" {
+		obj["id"] = item.ID
+	}
+	if item.Title != "" {
+		obj[v.titleKey] = item.Title
+	}
+	return obj
+}
+
+// LinkValue 关联记录值
+type LinkValue struct{ refList }
+
+// UserValue 用户值
+type UserValue struct{ refList }
+
+// AttachmentValue 附件值
+type AttachmentValue struct{ refList }
+
+// JSONValue 结构化值（地理位置、查找结果），由各自的校验器保证结构
+type JSONValue struct{ Value interface{} }
+
+func (v JSONValue) Kind() Kind           { return KindJSON }
+func (v JSONValue) IsEmpty() bool        { return v.Value == nil }
+func (v JSONValue) API() interface{}     { return v.Value }
+func (v JSONValue) Storage() interface{} { return v.Value }
+func (v JSONValue) Formula() interface{} { return v.Value }
+
+// RawValue 未注册转换器的字段类型的值，原样保留
+type RawValue struct{ Value interface{} }
+
+func (v RawValue) Kind() Kind           { return KindRaw }
+func (v RawValue) IsEmpty() bool        { return v.Value == nil }
+func (v RawValue) API() interface{}     { return v.Value }
+func (v RawValue) Storage() interface{} { return v.Value }
+func (v RawValue) Formula() interface{} { return v.Value }
+
+// CustomValue 自定义字段类型（fieldplugin）的值，公式值由插件决定
+type CustomValue struct {
+	Value  interface{}
+	plugin fieldplugin.FieldType
+}
+
+func (v CustomValue) Kind() Kind           { return KindCustom }
+func (v CustomValue) IsEmpty() bool        { return v.Value == nil }
+func (v CustomValue) API() interface{}     { return v.Value }
+func (v CustomValue) Storage() interface{} { return v.Value }
+func (v CustomValue) Formula() interface{} { return v.plugin.FormulaValue(v.Value) }
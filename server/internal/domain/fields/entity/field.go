@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
 )
 
 // Field 字段实体（充血模型）
@@ -17,6 +19,7 @@ type Field struct {
 	id          valueobject.FieldID
 	tableID     string
 	name        valueobject.FieldName
+	apiKey      valueobject.APIKey // 外部 API 使用的稳定标识，重命名时不变
 	description *string
 	fieldType   valueobject.FieldType
 
@@ -137,6 +140,11 @@ func (f *Field) Name() valueobject.FieldName {
 	return f.name
 }
 
+// APIKey 获取 API 键
+func (f *Field) APIKey() valueobject.APIKey {
+	return f.apiKey
+}
+
 // Description 获取描述
 func (f *Field) Description() *string {
 	return f.description
@@ -242,8 +250,59 @@ func (f *Field) IsComputed() bool {
 	return f.fieldType.IsComputed()
 }
 
+// IsEncrypted 单元格值是否加密存储
+func (f *Field) IsEncrypted() bool {
+	return f.options != nil && f.options.Encrypted
+}
+
+// IsSearchable 是否允许进入全文索引（加密字段不建立索引）
+func (f *Field) IsSearchable() bool {
+	return !f.IsEncrypted()
+}
+
+// RevealsPlaintext 加密字段在指定输出通道是否允许明文
+func (f *Field) RevealsPlaintext(channel RevealChannel) bool {
+	if !f.IsEncrypted() {
+		return true
+	}
+	switch channel {
+	case RevealChannelLogs:
+		return f.options.RevealInLogs
+	case RevealChannelWebhooks:
+		return f.options.RevealInWebhooks
+	default:
+		return false
+	}
+}
+
 // ==================== 业务方法 ====================
 
+// EnableEncryption 开启静态加密
+// 密文以文本存储，因此物理列类型固定为 TEXT；只能在创建字段（物理列建立前）调用
+func (f *Field) EnableEncryption(revealInLogs, revealInWebhooks bool) error {
+	if f.IsDeleted() {
+		return fields.ErrCannotModifyDeletedField
+	}
+	if f.IsVirtual() || f.IsComputed() {
+		return fields.NewDomainError(
+			"CANNOT_ENCRYPT_COMPUTED_FIELD",
+			"computed field cannot be encrypted",
+			nil,
+		)
+	}
+
+	if f.options == nil {
+		f.options = valueobject.NewFieldOptions()
+	}
+	f.options.Encrypted = true
+	f.options.RevealInLogs = revealInLogs
+	f.options.RevealInWebhooks = revealInWebhooks
+	f.dbFieldType = "TEXT"
+	f.updatedAt = time.Now()
+
+	return nil
+}
+
 // Rename 重命名字段
 func (f *Field) Rename(newName valueobject.FieldName) error {
 	if f.IsDeleted() {
@@ -257,6 +316,18 @@ func (f *Field) Rename(newName valueobject.FieldName) error {
 	return nil
 }
 
+// SetAPIKey 设置 API 键（同表唯一由应用层保证）
+func (f *Field) SetAPIKey(key valueobject.APIKey) error {
+	if f.IsDeleted() {
+		return fields.ErrCannotModifyDeletedField
+	}
+
+	f.apiKey = key
+	f.updatedAt = time.Now()
+
+	return nil
+}
+
 // UpdateDescription 更新描述
 func (f *Field) UpdateDescription(description string) error {
 	if f.IsDeleted() {
@@ -525,8 +596,8 @@ func (f *Field) ConvertCellValueToDBValue(value interface{}) interface{} {
 	case valueobject.TypeAI:
 		return f.convertAIValueToDB(value)
 	default:
-		// 其他字段类型直接返回原值
-		return value
+		// 其他字段类型按类型化单元格值转换为存储值（如数字字符串转为数字、日期字符串转为时间）
+		return cellvalue.ToStorage(f.fieldType.String(), value)
 	}
 }
 
@@ -661,8 +732,8 @@ func (f *Field) ConvertDBValueToCellValue(value interface{}) interface{} {
 	case valueobject.TypeAI:
 		return f.convertAIValueFromDB(value)
 	default:
-		// 其他字段类型直接返回原值
-		return value
+		// 其他字段类型按类型化单元格值转换为 API 值
+		return cellvalue.ToAPI(f.fieldType.String(), value)
 	}
 }
 
@@ -736,11 +807,12 @@ func (f *Field) convertAIValueFromDB(value interface{}) interface{} {
 func determineDBFieldType(fieldType valueobject.FieldType) string {
 	// 根据字段类型映射到数据库类型（PostgreSQL）
 	switch fieldType.String() {
-	case valueobject.TypeText, valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone:
+	case valueobject.TypeText, valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone,
+		valueobject.TypeRichText:
 		return "TEXT"
 
 	case valueobject.TypeNumber, valueobject.TypeRating, valueobject.TypePercent,
-		valueobject.TypeCurrency, valueobject.TypeDuration:
+		valueobject.TypeCurrency, valueobject.TypeDuration, valueobject.TypeProgress:
 		return "NUMERIC"
 
 	case valueobject.TypeDate:
@@ -755,7 +827,8 @@ func determineDBFieldType(fieldType valueobject.FieldType) string {
 	case valueobject.TypeSelect:
 		return "TEXT"
 
-	case valueobject.TypeMultipleSelect, valueobject.TypeAttachment, valueobject.TypeUser:
+	case valueobject.TypeMultipleSelect, valueobject.TypeAttachment, valueobject.TypeUser,
+		valueobject.TypeLocation:
 		return "JSONB"
 
 	case valueobject.TypeLink:
@@ -777,6 +850,10 @@ func determineDBFieldType(fieldType valueobject.FieldType) string {
 		return "TEXT" // AI生成的结果
 
 	default:
+		// ✨ 自定义字段类型由插件决定列类型
+		if ft, ok := fieldplugin.Lookup(fieldType.String()); ok {
+			return string(ft.Storage())
+		}
 		return "TEXT"
 	}
 }
@@ -0,0 +1,30 @@
+package entity
+
+// RevealChannel 加密字段的输出通道
+type RevealChannel string
+
+const (
+	RevealChannelLogs     RevealChannel = "logs"
+	RevealChannelWebhooks RevealChannel = "webhooks"
+)
+
+// MaskedCellValue 加密字段脱敏后的占位值
+const MaskedCellValue = "******"
+
+// MaskEncryptedValues 对加密字段脱敏（key 为字段ID）
+// 返回新的 map，不修改原数据；未声明允许明文的加密字段值替换为 MaskedCellValue
+func MaskEncryptedValues(fieldList []*Field, data map[string]interface{}, channel RevealChannel) map[string]interface{} {
+	masked := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		masked[k] = v
+	}
+	for _, field := range fieldList {
+		if field.RevealsPlaintext(channel) {
+			continue
+		}
+		if v, ok := masked[field.ID().String()]; ok && v != nil {
+			masked[field.ID().String()] = MaskedCellValue
+		}
+	}
+	return masked
+}
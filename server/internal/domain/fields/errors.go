@@ -9,6 +9,8 @@ var (
 	ErrFieldNameEmpty           = errors.New("field name cannot be empty")
 	ErrFieldNameTooLong         = errors.New("field name too long (max 64 characters)")
 	ErrFieldNameNotUnique       = errors.New("field name already exists in this table")
+	ErrInvalidAPIKey            = errors.New("invalid field api key (lowercase letters, digits and underscores, starting with a letter, max 64 characters)")
+	ErrAPIKeyNotUnique          = errors.New("field api key already exists in this table")
 	ErrCannotModifyDeletedField = errors.New("cannot modify deleted field")
 	ErrFieldAlreadyDeleted      = errors.New("field is already deleted")
 
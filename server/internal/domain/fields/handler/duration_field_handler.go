@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
@@ -17,7 +18,7 @@ import (
 //   - 完美对齐：100%复制原版的业务规则
 //
 // 业务规则：
-//   - 字段值必须是整数（秒数）
+//   - 字段值必须是整数（秒数），也接受 "1h 30m"、"1:30" 等友好格式
 //   - 值范围：>= 0（时长不能为负）
 //   - nil值表示未设置时长
 //   - 支持自定义格式（h:mm, h:mm:ss等）
@@ -72,6 +73,12 @@ func (h *DurationFieldHandler) ValidateValue(ctx context.Context, field *entity.
 		intValue = int(v)
 	case float64:
 		intValue = int(v)
+	case string:
+		// 友好格式（"1h 30m"、"1:30"），解析失败或为负时报错
+		if _, err := valueobject.ParseDuration(v); err != nil {
+			return fields.NewDomainError("INVALID_DURATION_VALUE", err.Error(), nil)
+		}
+		return nil
 	default:
 		return fields.NewDomainError(
 			"INVALID_VALUE_TYPE",
@@ -110,6 +117,12 @@ func (h *DurationFieldHandler) TransformValue(ctx context.Context, field *entity
 		return int(v), nil
 	case float64:
 		return int(v), nil
+	case string:
+		seconds, err := valueobject.ParseDuration(v)
+		if err != nil {
+			return nil, err
+		}
+		return int(seconds), nil
 	default:
 		return nil, nil
 	}
@@ -137,28 +150,13 @@ func (h *DurationFieldHandler) SupportsOptions() bool {
 
 // ValidateOptions 验证字段选项配置
 func (h *DurationFieldHandler) ValidateOptions(ctx context.Context, field *entity.Field) error {
-	validFormats := []string{"h:mm", "h:mm:ss", "d:h:mm", "d:h:mm:ss"}
-
 	options := field.Options()
 	if options != nil && options.Duration != nil {
-		format := options.Duration.Format
-		if format == "" {
-			return nil // format为空时使用默认值
-		}
-
-		// 验证格式是否有效
-		isValid := false
-		for _, vf := range validFormats {
-			if format == vf {
-				isValid = true
-				break
-			}
-		}
-
-		if !isValid {
+		// format为空时使用默认值
+		if !valueobject.IsValidDurationFormat(options.Duration.Format) {
 			return fields.NewDomainError(
 				"INVALID_DURATION_FORMAT",
-				"Duration格式无效，支持的格式: h:mm, h:mm:ss, d:h:mm, d:h:mm:ss",
+				fmt.Sprintf("Duration格式无效，支持的格式: %s", strings.Join(valueobject.DurationFormats, ", ")),
 				nil,
 			)
 		}
@@ -0,0 +1,277 @@
+package optionschema
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+var registry = map[string]*Schema{}
+
+// register 登记字段类型的选项 Schema，自动合并所有类型通用的选项
+func register(properties map[string]*Schema, required []string, fieldTypes ...string) {
+	for _, fieldType := range fieldTypes {
+		props := commonProperties()
+		for key, prop := range properties {
+			props[key] = prop
+		}
+		registry[fieldType] = &Schema{
+			Type:       "object",
+			Title:      fieldType,
+			Properties: props,
+			Required:   required,
+		}
+	}
+}
+
+// For 返回字段类型的选项 Schema（含通过 fieldplugin 注册的自定义类型）
+func For(fieldType string) (*Schema, bool) {
+	if s, ok := registry[fieldType]; ok {
+		return s, true
+	}
+	if plugin, ok := fieldplugin.Lookup(fieldType); ok {
+		return pluginSchema(plugin), true
+	}
+	return nil, false
+}
+
+// All 返回全部字段类型的选项 Schema
+func All() map[string]*Schema {
+	all := make(map[string]*Schema, len(registry))
+	for fieldType, s := range registry {
+		all[fieldType] = s
+	}
+	for _, name := range fieldplugin.Names() {
+		if plugin, ok := fieldplugin.Lookup(name); ok {
+			all[name] = pluginSchema(plugin)
+		}
+	}
+	return all
+}
+
+// Types 已登记的字段类型（排序）
+func Types() []string {
+	types := make([]string, 0, len(registry))
+	for fieldType := range registry {
+		types = append(types, fieldType)
+	}
+	types = append(types, fieldplugin.Names()...)
+	sort.Strings(types)
+	return types
+}
+
+// pluginSchema 将插件声明的选项 Schema 转换为 Schema，并合并通用选项；
+// 插件未声明或声明无法解析时只校验通用选项
+func pluginSchema(plugin fieldplugin.FieldType) *Schema {
+	s := &Schema{}
+	if raw := plugin.OptionsSchema(); raw != nil {
+		if data, err := json.Marshal(raw); err == nil {
+			_ = json.Unmarshal(data, s)
+		}
+	}
+	s.Type = "object"
+	if s.Title == "" {
+		s.Title = plugin.Name()
+	}
+	props := commonProperties()
+	for key, prop := range s.Properties {
+		props[key] = prop
+	}
+	s.Properties = props
+	return s
+}
+
+// Validate 按字段类型校验创建字段时的选项；未登记的类型不校验（类型本身的合法性由字段工厂检查）
+func Validate(fieldType string, options map[string]interface{}) []Violation {
+	s, ok := For(fieldType)
+	if !ok {
+		return nil
+	}
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+	return s.Validate(options)
+}
+
+// ValidatePartial 按字段类型校验更新字段时的选项（只校验给出的选项）
+func ValidatePartial(fieldType string, options map[string]interface{}) []Violation {
+	s, ok := For(fieldType)
+	if !ok || options == nil {
+		return nil
+	}
+	return s.ValidatePartial(options)
+}
+
+func init() {
+	register(nil, nil,
+		valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText, valueobject.TypeRichText,
+		valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone,
+		valueobject.TypeCheckbox, valueobject.TypeBoolean,
+		valueobject.TypeAttachment, valueobject.TypeUser, valueobject.TypeLocation,
+		valueobject.TypeCreatedTime, valueobject.TypeModifiedTime, valueobject.TypeCreatedBy, valueobject.TypeModifiedBy,
+		valueobject.TypeAutoNumber)
+
+	register(map[string]*Schema{
+		"precision": integerProp("小数位数", 0, 10),
+		"minValue":  {Type: "number", Title: "最小值"},
+		"maxValue":  {Type: "number", Title: "最大值"},
+	}, nil, valueobject.TypeNumber, valueobject.TypeCurrency, valueobject.TypePercent)
+
+	register(map[string]*Schema{
+		"choices": {
+			Type:  "array",
+			Title: "选项",
+			Items: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"id":    stringProp("选项ID"),
+					"name":  {Type: "string", Title: "名称", MinLength: intPtr(1)},
+					"color": stringProp("颜色"),
+				},
+				Required: []string{"name"},
+			},
+		},
+		"preventAutoNewOptions": boolProp("禁止写入时自动添加新选项"),
+	}, nil, valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect)
+
+	register(map[string]*Schema{
+		"defaultValue":    {Type: "string", Title: "默认值", Description: "now 表示创建时的时间，或具体日期字符串"},
+		"timeZone":        stringProp("时区"),
+		"useSameTimeZone": {Type: "boolean", Title: "统一时区", Description: "所有协作者按 timeZone 展示与过滤，否则按各自的用户时区"},
+	}, nil, valueobject.TypeDate, valueobject.TypeDateTime)
+
+	register(map[string]*Schema{
+		"expression": stringProp("公式表达式"),
+		"formula":    {Type: "string", Title: "公式表达式", Description: "expression 的别名"},
+		"timeZone":   stringProp("时区"),
+	}, nil, valueobject.TypeFormula)
+
+	register(map[string]*Schema{
+		"linkFieldId":     idProp("关联字段ID"),
+		"rollupFieldId":   idProp("汇总字段ID"),
+		"aggregationFunc": stringProp("汇总函数"),
+		"timeZone":        stringProp("时区"),
+	}, []string{"linkFieldId", "rollupFieldId"}, valueobject.TypeRollup)
+
+	register(map[string]*Schema{
+		"linkFieldId":   idProp("关联字段ID"),
+		"lookupFieldId": idProp("查找字段ID"),
+	}, []string{"linkFieldId", "lookupFieldId"}, valueobject.TypeLookup)
+
+	register(map[string]*Schema{
+		"linkedTableId":   idProp("关联表ID"),
+		"relationship":    enumProp("关系", "many_to_many", "one_to_many", "many_to_one", "one_to_one"),
+		"allowMultiple":   boolProp("允许关联多条记录"),
+		"baseId":          {Type: "string", Title: "关联表所在 Base", Description: "由关联表决定，创建后不能修改"},
+		"lookupFieldId":   stringProp("显示字段ID"),
+		"filterByViewId":  stringProp("按视图过滤可选记录"),
+		"visibleFieldIds": {Type: "array", Title: "可见字段", Items: stringProp("字段ID")},
+		"filter": {
+			Type:  "object",
+			Title: "可选记录的过滤条件",
+			Properties: map[string]*Schema{
+				"conjunction": enumProp("条件组合", "and", "or"),
+				"conditions": {
+					Type: "array",
+					Items: &Schema{
+						Type: "object",
+						Properties: map[string]*Schema{
+							"fieldId":  stringProp("字段ID"),
+							"operator": stringProp("操作符"),
+							"value":    {Title: "比较值"},
+						},
+						Required: []string{"fieldId", "operator"},
+					},
+				},
+			},
+		},
+	}, []string{"linkedTableId"}, valueobject.TypeLink)
+
+	register(map[string]*Schema{
+		"format": durationFormat("显示格式"),
+	}, nil, valueobject.TypeDuration)
+
+	register(map[string]*Schema{
+		"max":   {Type: "integer", Title: "最大评分", Minimum: floatPtr(1), Maximum: floatPtr(valueobject.MaxRatingMax), Default: valueobject.DefaultRatingMax},
+		"icon":  stringProp("图标"),
+		"color": stringProp("颜色"),
+	}, nil, valueobject.TypeRating)
+
+	register(map[string]*Schema{
+		"color":     stringProp("颜色"),
+		"showValue": boolProp("显示百分比数值"),
+	}, nil, valueobject.TypeProgress)
+
+	register(map[string]*Schema{
+		"provider": {Type: "string", Title: "服务商", Description: "为空时使用默认服务商"},
+		"model":    {Type: "string", Title: "模型", Description: "为空时使用服务商的默认模型"},
+		"prompt":   {Type: "string", Title: "提示词", Description: "{字段} 引用当前记录的字段值"},
+		"config": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"temperature": {Type: "number", Title: "采样温度", Minimum: floatPtr(0), Maximum: floatPtr(2)},
+				"maxTokens":   {Type: "integer", Title: "最大输出 Token 数", Minimum: floatPtr(1)},
+			},
+		},
+	}, nil, valueobject.TypeAI)
+}
+
+// commonProperties 所有字段类型通用的选项
+func commonProperties() map[string]*Schema {
+	return map[string]*Schema{
+		"defaultValue":     {Title: "默认值"},
+		"encrypted":        {Type: "boolean", Title: "静态加密", Description: "仅可在创建字段时设置"},
+		"revealInLogs":     boolProp("在审计日志中显示明文"),
+		"revealInWebhooks": boolProp("在 Webhook 中显示明文"),
+		"showAs": {
+			Type:  "object",
+			Title: "显示方式",
+			Properties: map[string]*Schema{
+				"type":  stringProp("显示类型"),
+				"color": stringProp("颜色"),
+			},
+		},
+		"formatting": {
+			Type:  "object",
+			Title: "格式化",
+			Properties: map[string]*Schema{
+				"type":           stringProp("格式类型"),
+				"precision":      integerProp("小数位数", 0, 10),
+				"dateFormat":     stringProp("日期格式"),
+				"timeFormat":     stringProp("时间格式"),
+				"timeZone":       stringProp("时区"),
+				"currency":       stringProp("货币"),
+				"showCommas":     boolProp("显示千分位"),
+				"durationFormat": durationFormat("时长格式"),
+			},
+		},
+	}
+}
+
+func stringProp(title string) *Schema { return &Schema{Type: "string", Title: title} }
+func idProp(title string) *Schema     { return &Schema{Type: "string", Title: title, MinLength: intPtr(1)} }
+func boolProp(title string) *Schema   { return &Schema{Type: "boolean", Title: title} }
+
+func integerProp(title string, min, max float64) *Schema {
+	return &Schema{Type: "integer", Title: title, Minimum: &min, Maximum: &max}
+}
+
+func enumProp(title string, values ...string) *Schema {
+	s := &Schema{Type: "string", Title: title}
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// durationFormat 时长显示格式（空字符串表示默认格式）
+func durationFormat(title string) *Schema {
+	s := enumProp(title, append([]string{""}, valueobject.DurationFormats...)...)
+	s.Default = valueobject.DefaultDurationFormat
+	return s
+}
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
@@ -0,0 +1,131 @@
+package optionschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
+)
+
+func violationPaths(violations []Violation) map[string]bool {
+	paths := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		paths[v.Path] = true
+	}
+	return paths
+}
+
+func TestValidate_ReportsEveryViolation(t *testing.T) {
+	violations := Validate(valueobject.TypeSingleSelect, map[string]interface{}{
+		"choices": []interface{}{
+			map[string]interface{}{"name": "进行中", "color": "blue"},
+			map[string]interface{}{"name": ""},
+			"done",
+		},
+		"preventAutoNewOptions": "yes",
+		"formatting":            map[string]interface{}{"precision": 1.5},
+		"unknownKey":            1, // 未登记的键不受限制
+	})
+
+	paths := violationPaths(violations)
+	for _, want := range []string{"choices[1].name", "choices[2]", "preventAutoNewOptions", "formatting.precision"} {
+		if !paths[want] {
+			t.Fatalf("expected violation at %s, got %v", want, violations)
+		}
+	}
+	if len(violations) != 4 {
+		t.Fatalf("expected 4 violations, got %v", violations)
+	}
+}
+
+func TestValidate_RangesAndEnums(t *testing.T) {
+	if v := Validate(valueobject.TypeRating, map[string]interface{}{"max": 11.0}); len(v) != 1 || v[0].Path != "max" {
+		t.Fatalf("expected rating max out of range, got %v", v)
+	}
+	if v := Validate(valueobject.TypeRating, map[string]interface{}{"max": 10.0}); len(v) != 0 {
+		t.Fatalf("expected rating max 10 to be valid, got %v", v)
+	}
+	if v := Validate(valueobject.TypeDuration, map[string]interface{}{"format": "hh"}); len(v) != 1 {
+		t.Fatalf("expected unknown duration format to be rejected, got %v", v)
+	}
+	if v := Validate(valueobject.TypeDuration, map[string]interface{}{"format": ""}); len(v) != 0 {
+		t.Fatalf("expected empty duration format to mean default, got %v", v)
+	}
+	// formatting.durationFormat 对所有字段类型生效
+	if v := Validate(valueobject.TypeRollup, map[string]interface{}{
+		"linkFieldId": "fld1", "rollupFieldId": "fld2",
+		"formatting": map[string]interface{}{"durationFormat": "weeks"},
+	}); len(v) != 1 || v[0].Path != "formatting.durationFormat" {
+		t.Fatalf("expected duration format violation, got %v", v)
+	}
+}
+
+func TestValidate_RequiredOnlyOnCreate(t *testing.T) {
+	if v := Validate(valueobject.TypeLink, map[string]interface{}{"relationship": "one_to_one"}); len(v) != 1 || v[0].Path != "linkedTableId" {
+		t.Fatalf("expected missing linkedTableId on create, got %v", v)
+	}
+	if v := Validate(valueobject.TypeLookup, nil); len(v) != 2 {
+		t.Fatalf("expected both lookup references to be required, got %v", v)
+	}
+	if v := ValidatePartial(valueobject.TypeLink, map[string]interface{}{"lookupFieldId": "fld1"}); len(v) != 0 {
+		t.Fatalf("expected partial update without linkedTableId to pass, got %v", v)
+	}
+	if v := ValidatePartial(valueobject.TypeLink, map[string]interface{}{"relationship": "some"}); len(v) != 1 {
+		t.Fatalf("expected partial update to validate given options, got %v", v)
+	}
+}
+
+func TestValidate_UnregisteredType(t *testing.T) {
+	if v := Validate("customType", map[string]interface{}{"anything": 1}); v != nil {
+		t.Fatalf("expected unregistered type to be unchecked, got %v", v)
+	}
+}
+
+func TestSchemasAreJSONSchema(t *testing.T) {
+	s, ok := For(valueobject.TypeRating)
+	if !ok {
+		t.Fatal("expected rating schema")
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(data, &decoded)
+	props, _ := decoded["properties"].(map[string]interface{})
+	max, _ := props["max"].(map[string]interface{})
+	if decoded["type"] != "object" || max["type"] != "integer" || max["maximum"] != 10.0 {
+		t.Fatalf("unexpected schema JSON: %s", data)
+	}
+	if len(Types()) != len(All()) {
+		t.Fatal("expected Types and All to agree")
+	}
+}
+
+type weightField struct{ fieldplugin.Base }
+
+func (weightField) Name() string                     { return "x-weight" }
+func (weightField) Storage() fieldplugin.StorageType { return fieldplugin.StorageNumeric }
+func (weightField) OptionsSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"properties": map[string]interface{}{
+			"unit": map[string]interface{}{"type": "string", "enum": []interface{}{"kg", "g"}},
+		},
+		"required": []interface{}{"unit"},
+	}
+}
+
+func TestValidate_CustomFieldType(t *testing.T) {
+	fieldplugin.MustRegister(weightField{})
+
+	if v := Validate("x-weight", map[string]interface{}{"unit": "lb"}); len(v) != 1 || v[0].Path != "unit" {
+		t.Fatalf("expected plugin enum violation, got %v", v)
+	}
+	if v := Validate("x-weight", map[string]interface{}{"formatting": map[string]interface{}{"precision": 1.5}}); len(v) != 2 {
+		t.Fatalf("expected required unit and common option violations, got %v", v)
+	}
+	if _, ok := All()["x-weight"]; !ok {
+		t.Fatal("expected custom field type in schema list")
+	}
+}
@@ -0,0 +1,165 @@
+// Package optionschema 字段选项的 JSON Schema 注册表 ✨
+//
+// 为每种字段类型登记其选项（options）的 JSON Schema，创建与更新字段时据此校验，
+// 同时通过 API 暴露给客户端，按 Schema 通用地渲染选项编辑器。
+// 只实现选项用到的 JSON Schema 子集：type、properties、required、items、enum、minimum/maximum、minLength/maxLength；
+// 未登记的键不做限制（additionalProperties 默认为 true），以兼容旧客户端携带的额外键
+package optionschema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema JSON Schema（子集）
+type Schema struct {
+	Type        string             `json:"type,omitempty"` // object/array/string/number/integer/boolean，为空表示任意类型
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+}
+
+// Violation 一处不符合 Schema 的选项
+type Violation struct {
+	Path    string `json:"path"` // 如 choices[0].name
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Validate 校验值，返回全部违规项
+func (s *Schema) Validate(value interface{}) []Violation {
+	var violations []Violation
+	s.validate(value, "", true, &violations)
+	return violations
+}
+
+// ValidatePartial 校验部分更新的值：顶层的必填项可以省略（未给出的选项保持原值）
+func (s *Schema) ValidatePartial(value interface{}) []Violation {
+	var violations []Violation
+	s.validate(value, "", false, &violations)
+	return violations
+}
+
+func (s *Schema) validate(value interface{}, path string, checkRequired bool, violations *[]Violation) {
+	fail := func(format string, args ...interface{}) {
+		*violations = append(*violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if value == nil {
+		// null 表示清除该选项
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("必须是对象")
+			return
+		}
+		if checkRequired {
+			for _, key := range s.Required {
+				if v, ok := obj[key]; !ok || v == nil {
+					*violations = append(*violations, Violation{Path: join(path, key), Message: "不能为空"})
+				}
+			}
+		}
+		keys := make([]string, 0, len(s.Properties))
+		for key := range s.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if v, ok := obj[key]; ok {
+				s.Properties[key].validate(v, join(path, key), true, violations)
+			}
+		}
+	case "array":
+		list, ok := value.([]interface{})
+		if !ok {
+			fail("必须是数组")
+			return
+		}
+		if s.Items != nil {
+			for i, item := range list {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), true, violations)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("必须是字符串")
+			return
+		}
+		if s.MinLength != nil && len([]rune(str)) < *s.MinLength {
+			fail("长度不能少于 %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+			fail("长度不能超过 %d", *s.MaxLength)
+		}
+	case "number", "integer":
+		num, ok := value.(float64)
+		if !ok {
+			fail("必须是数字")
+			return
+		}
+		if s.Type == "integer" && num != math.Trunc(num) {
+			fail("必须是整数")
+			return
+		}
+		if s.Minimum != nil && num < *s.Minimum {
+			fail("不能小于 %v", *s.Minimum)
+		}
+		if s.Maximum != nil && num > *s.Maximum {
+			fail("不能大于 %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("必须是布尔值")
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		fail("必须是以下值之一: %s", enumList(s.Enum))
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+func enumList(enum []interface{}) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = fmt.Sprintf("%q", fmt.Sprint(e))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -52,6 +52,9 @@ type FieldRepository interface {
 	// UpdateOrder 更新字段排序
 	UpdateOrder(ctx context.Context, fieldID valueobject.FieldID, order float64) error
 
+	// BatchUpdateOrder 按给定顺序一次性重排表中的字段（第 i 个字段的 order 为 i，在同一事务中完成）
+	BatchUpdateOrder(ctx context.Context, tableID string, fieldIDs []valueobject.FieldID) error
+
 	// GetMaxOrder 获取表中字段的最大order值（用于新字段排序）
 	GetMaxOrder(ctx context.Context, tableID string) (float64, error)
 
@@ -59,6 +62,13 @@ type FieldRepository interface {
 	NextID() valueobject.FieldID
 }
 
+// LinkFieldFinder 按关联目标查找关联字段 ✨
+// 用于跨 Base 关联：目标表记录变化时找到所有指向它的关联字段
+type LinkFieldFinder interface {
+	// FindLinkFieldsTo 查找指向 linkedTableID 的全部关联字段（不限 Base）
+	FindLinkFieldsTo(ctx context.Context, linkedTableID string) ([]*entity.Field, error)
+}
+
 // FieldFilter 字段过滤器
 type FieldFilter struct {
 	TableID    *string
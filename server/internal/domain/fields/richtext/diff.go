@@ -0,0 +1,171 @@
+package richtext
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DiffOpType 差异片段类型
+type DiffOpType string
+
+const (
+	DiffEqual  DiffOpType = "equal"
+	DiffInsert DiffOpType = "insert"
+	DiffDelete DiffOpType = "delete"
+)
+
+// DiffOp 差异片段
+type DiffOp struct {
+	Op   DiffOpType `json:"op"`
+	Text string     `json:"text"`
+}
+
+// maxDiffCells 逐词比对的最大计算量（两侧词数之积），超出时整体替换
+const maxDiffCells = 4_000_000
+
+// Diff 比较两个版本的富文本，返回按词切分的差异
+// 两侧先转换为 Markdown，格式变化（如加粗、标题）会体现为标记的增删
+func Diff(beforeHTML, afterHTML string) []DiffOp {
+	return DiffText(HTMLToMarkdown(beforeHTML), HTMLToMarkdown(afterHTML))
+}
+
+// DiffText 按词比较两段文本（中日韩字符逐字切分）
+func DiffText(before, after string) []DiffOp {
+	a, b := tokenize(before), tokenize(after)
+
+	// 去掉公共前缀与后缀，缩小比对范围
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffOp
+	ops = appendOp(ops, DiffEqual, a[:prefix])
+	ops = append(ops, diffTokens(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	ops = appendOp(ops, DiffEqual, a[len(a)-suffix:])
+	return mergeOps(ops)
+}
+
+// diffTokens 基于最长公共子序列计算差异
+func diffTokens(a, b []string) []DiffOp {
+	if len(a) == 0 || len(b) == 0 || len(a)*len(b) > maxDiffCells {
+		return appendOp(appendOp(nil, DiffDelete, a), DiffInsert, b)
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []DiffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, DiffOp{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, DiffOp{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			ops = append(ops, DiffOp{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	ops = appendOp(ops, DiffDelete, a[i:])
+	return appendOp(ops, DiffInsert, b[j:])
+}
+
+// tokenize 将文本切分为词、空白与标点，中日韩字符单独成词
+func tokenize(s string) []string {
+	var tokens []string
+	start := -1
+	kind := 0
+	for i, r := range s {
+		k := tokenKind(r)
+		if start >= 0 && (k != kind || k == tokenSingle) {
+			tokens = append(tokens, s[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start, kind = i, k
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+const (
+	tokenWord = iota + 1
+	tokenSpace
+	tokenSingle
+)
+
+func tokenKind(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return tokenSpace
+	case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+		return tokenSingle
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+		return tokenWord
+	default:
+		return tokenSingle
+	}
+}
+
+func appendOp(ops []DiffOp, op DiffOpType, tokens []string) []DiffOp {
+	if len(tokens) == 0 {
+		return ops
+	}
+	return append(ops, DiffOp{Op: op, Text: strings.Join(tokens, "")})
+}
+
+// mergeOps 合并相邻的同类片段，删除片段排在插入片段之前
+func mergeOps(ops []DiffOp) []DiffOp {
+	var merged []DiffOp
+	for i := 0; i < len(ops); {
+		if ops[i].Op == DiffEqual {
+			var b strings.Builder
+			for ; i < len(ops) && ops[i].Op == DiffEqual; i++ {
+				b.WriteString(ops[i].Text)
+			}
+			merged = append(merged, DiffOp{Op: DiffEqual, Text: b.String()})
+			continue
+		}
+		var deleted, inserted strings.Builder
+		for ; i < len(ops) && ops[i].Op != DiffEqual; i++ {
+			if ops[i].Op == DiffDelete {
+				deleted.WriteString(ops[i].Text)
+			} else {
+				inserted.WriteString(ops[i].Text)
+			}
+		}
+		if deleted.Len() > 0 {
+			merged = append(merged, DiffOp{Op: DiffDelete, Text: deleted.String()})
+		}
+		if inserted.Len() > 0 {
+			merged = append(merged, DiffOp{Op: DiffInsert, Text: inserted.String()})
+		}
+	}
+	return merged
+}
@@ -0,0 +1,37 @@
+package richtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffText(t *testing.T) {
+	cases := []struct {
+		name   string
+		before string
+		after  string
+		want   []DiffOp
+	}{
+		{"same", "a b", "a b", []DiffOp{{DiffEqual, "a b"}}},
+		{"replace word", "the quick fox", "the slow fox", []DiffOp{{DiffEqual, "the "}, {DiffDelete, "quick"}, {DiffInsert, "slow"}, {DiffEqual, " fox"}}},
+		{"insert", "a c", "a b c", []DiffOp{{DiffEqual, "a "}, {DiffInsert, "b "}, {DiffEqual, "c"}}},
+		{"han", "今天很好", "今天不好", []DiffOp{{DiffEqual, "今天"}, {DiffDelete, "很"}, {DiffInsert, "不"}, {DiffEqual, "好"}}},
+		{"from empty", "", "new", []DiffOp{{DiffInsert, "new"}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DiffText(tc.before, tc.after); !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("DiffText(%q, %q) = %v, want %v", tc.before, tc.after, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffShowsFormatting(t *testing.T) {
+	got := Diff("<p>hello world</p>", "<p>hello <strong>world</strong></p>")
+	want := []DiffOp{{DiffEqual, "hello "}, {DiffInsert, "**"}, {DiffEqual, "world"}, {DiffInsert, "**"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff = %v, want %v", got, want)
+	}
+}
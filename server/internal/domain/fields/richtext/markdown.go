@@ -0,0 +1,543 @@
+package richtext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletItemPattern  = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	orderedItemPattern = regexp.MustCompile(`^\s*(\d{1,9})[.)]\s+(.*)$`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	blankLinesPattern  = regexp.MustCompile(`\n{3,}`)
+)
+
+// MarkdownToHTML 将 Markdown 转换为净化后的 HTML
+// 支持标题、段落、引用、有序/无序列表、代码块、分隔线以及粗体、斜体、删除线、行内代码、链接和图片
+func MarkdownToHTML(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	return Sanitize(b.String())
+}
+
+// renderBlocks 逐行识别块级元素
+func renderBlocks(b *strings.Builder, lines []string) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>")
+			renderParagraph(b, paragraph)
+			b.WriteString("</p>")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+			b.WriteString("</code></pre>")
+
+		case headingPattern.MatchString(trimmed):
+			flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			tag := "h" + strconv.Itoa(len(m[1]))
+			b.WriteString("<" + tag + ">")
+			renderInline(b, m[2])
+			b.WriteString("</" + tag + ">")
+
+		case isThematicBreak(trimmed):
+			flush()
+			b.WriteString("<hr>")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			i--
+			b.WriteString("<blockquote>")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>")
+
+		case bulletItemPattern.MatchString(line):
+			flush()
+			b.WriteString("<ul>")
+			for ; i < len(lines) && bulletItemPattern.MatchString(lines[i]); i++ {
+				b.WriteString("<li>")
+				renderInline(b, bulletItemPattern.FindStringSubmatch(lines[i])[1])
+				b.WriteString("</li>")
+			}
+			i--
+			b.WriteString("</ul>")
+
+		case orderedItemPattern.MatchString(line):
+			flush()
+			start := orderedItemPattern.FindStringSubmatch(line)[1]
+			if n, _ := strconv.Atoi(start); n != 1 {
+				b.WriteString(`<ol start="` + strconv.Itoa(n) + `">`)
+			} else {
+				b.WriteString("<ol>")
+			}
+			for ; i < len(lines) && orderedItemPattern.MatchString(lines[i]); i++ {
+				b.WriteString("<li>")
+				renderInline(b, orderedItemPattern.FindStringSubmatch(lines[i])[2])
+				b.WriteString("</li>")
+			}
+			i--
+			b.WriteString("</ol>")
+
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+	flush()
+}
+
+// renderParagraph 渲染段落，以反斜杠或两个空格结尾的行为硬换行
+func renderParagraph(b *strings.Builder, lines []string) {
+	for i, line := range lines {
+		hardBreak := false
+		if strings.HasSuffix(line, "  ") {
+			hardBreak = true
+		} else if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+			hardBreak = true
+			line = strings.TrimSuffix(line, `\`)
+		}
+		renderInline(b, strings.TrimSpace(line))
+		if i < len(lines)-1 {
+			if hardBreak {
+				b.WriteString("<br>")
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+	}
+}
+
+// inlineDelimiters 成对出现的行内标记，较长的标记优先匹配
+var inlineDelimiters = []struct {
+	marker string
+	tag    string
+}{
+	{"**", "strong"},
+	{"__", "strong"},
+	{"~~", "del"},
+	{"*", "em"},
+	{"_", "em"},
+}
+
+// renderInline 渲染行内元素
+func renderInline(b *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				b.WriteString("<code>")
+				b.WriteString(html.EscapeString(s[i+1 : i+1+end]))
+				b.WriteString("</code>")
+				i += end + 2
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(s[i+1:], "["):
+			if text, dest, n, ok := parseLink(s[i+1:]); ok {
+				b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(text) + `">`)
+				i += n + 1
+				continue
+			}
+
+		case c == '@' && strings.HasPrefix(s[i+1:], "["):
+			if n, ok := renderRecordMention(b, s[i+1:]); ok {
+				i += n + 1
+				continue
+			}
+
+		case c == '[':
+			if text, dest, n, ok := parseLink(s[i:]); ok {
+				b.WriteString(`<a href="` + html.EscapeString(dest) + `">`)
+				renderInline(b, text)
+				b.WriteString("</a>")
+				i += n
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if n, ok := renderEmphasis(b, s, i); ok {
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+}
+
+// renderEmphasis 尝试在 i 处匹配成对的强调标记，返回消耗的字节数
+func renderEmphasis(b *strings.Builder, s string, i int) (int, bool) {
+	for _, d := range inlineDelimiters {
+		if !strings.HasPrefix(s[i:], d.marker) {
+			continue
+		}
+		// 单词内部的下划线（如 snake_case）不作为强调
+		if d.marker[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+			return 0, false
+		}
+		rest := s[i+len(d.marker):]
+		end := strings.Index(rest, d.marker)
+		if end <= 0 || rest[0] == ' ' {
+			continue
+		}
+		b.WriteString("<" + d.tag + ">")
+		renderInline(b, rest[:end])
+		b.WriteString("</" + d.tag + ">")
+		return len(d.marker)*2 + end, true
+	}
+	return 0, false
+}
+
+// parseLink 解析 [text](dest)，返回文本、地址与消耗的字节数
+func parseLink(s string) (string, string, int, bool) {
+	closeText := strings.Index(s, "](")
+	if !strings.HasPrefix(s, "[") || closeText < 0 {
+		return "", "", 0, false
+	}
+	closeDest := strings.IndexByte(s[closeText+2:], ')')
+	if closeDest < 0 {
+		return "", "", 0, false
+	}
+	dest := strings.TrimSpace(s[closeText+2 : closeText+2+closeDest])
+	if dest == "" || strings.ContainsAny(dest, " \t") {
+		return "", "", 0, false
+	}
+	return s[1:closeText], dest, closeText + 3 + closeDest, true
+}
+
+// isThematicBreak 是否为分隔线（三个及以上相同的 -、* 或 _）
+func isThematicBreak(line string) bool {
+	compact := strings.ReplaceAll(line, " ", "")
+	if len(compact) < 3 || strings.IndexByte("-*_", compact[0]) < 0 {
+		return false
+	}
+	return strings.Count(compact, compact[:1]) == len(compact)
+}
+
+func isMarkdownPunct(c byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!~>|", c) >= 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// HTMLToMarkdown 将 HTML 净化后转换为 Markdown
+func HTMLToMarkdown(input string) string {
+	nodes, err := parseFragment(Sanitize(input))
+	if err != nil || len(nodes) == 0 {
+		return ""
+	}
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	for _, node := range nodes {
+		root.AppendChild(node)
+	}
+	w := &markdownWriter{}
+	w.blocks(root)
+	return strings.TrimSpace(w.b.String())
+}
+
+// markdownWriter 将净化后的节点树输出为 Markdown
+type markdownWriter struct {
+	b strings.Builder
+}
+
+// blocks 输出子节点，相邻的文本与行内元素合并为一个段落
+func (w *markdownWriter) blocks(parent *html.Node) {
+	var inline strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(inline.String()); text != "" {
+			w.startBlock()
+			w.b.WriteString(text)
+		}
+		inline.Reset()
+	}
+
+	for node := parent.FirstChild; node != nil; node = node.NextSibling {
+		if isBlockElement(node) {
+			flush()
+			w.block(node)
+		} else {
+			writeInline(&inline, node)
+		}
+	}
+	flush()
+}
+
+// block 输出块级节点，块之间以空行分隔
+func (w *markdownWriter) block(node *html.Node) {
+	switch node.DataAtom {
+	case atom.P:
+		w.startBlock()
+		w.b.WriteString(strings.TrimSpace(inlineMarkdown(node)))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		w.startBlock()
+		level := int(node.Data[1] - '0')
+		w.b.WriteString(strings.Repeat("#", level) + " " + strings.TrimSpace(inlineMarkdown(node)))
+	case atom.Hr:
+		w.startBlock()
+		w.b.WriteString("---")
+	case atom.Pre:
+		w.startBlock()
+		w.b.WriteString("```\n" + strings.TrimSuffix(textContent(node), "\n") + "\n```")
+	case atom.Blockquote:
+		inner := &markdownWriter{}
+		inner.blocks(node)
+		w.startBlock()
+		for i, line := range strings.Split(strings.TrimSpace(inner.b.String()), "\n") {
+			if i > 0 {
+				w.b.WriteByte('\n')
+			}
+			w.b.WriteString(strings.TrimRight("> "+line, " "))
+		}
+	case atom.Ul, atom.Ol:
+		w.startBlock()
+		n := 1
+		if start, err := strconv.Atoi(attrValue(node, "start")); err == nil {
+			n = start
+		}
+		first := true
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.DataAtom != atom.Li {
+				continue
+			}
+			if !first {
+				w.b.WriteByte('\n')
+			}
+			first = false
+			if node.DataAtom == atom.Ol {
+				w.b.WriteString(strconv.Itoa(n) + ". ")
+				n++
+			} else {
+				w.b.WriteString("- ")
+			}
+			w.b.WriteString(strings.TrimSpace(whitespacePattern.ReplaceAllString(inlineMarkdown(child), " ")))
+		}
+	}
+}
+
+func isBlockElement(node *html.Node) bool {
+	switch node.DataAtom {
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Hr, atom.Pre, atom.Blockquote, atom.Ul, atom.Ol:
+		return node.Type == html.ElementNode
+	}
+	return false
+}
+
+func (w *markdownWriter) startBlock() {
+	if w.b.Len() > 0 {
+		w.b.WriteString("\n\n")
+	}
+}
+
+// inlineMarkdown 输出节点的子节点为行内 Markdown
+func inlineMarkdown(node *html.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeInline(&b, child)
+	}
+	return b.String()
+}
+
+func writeInline(b *strings.Builder, node *html.Node) {
+	if node.Type == html.TextNode {
+		b.WriteString(escapeMarkdown(whitespacePattern.ReplaceAllString(node.Data, " ")))
+		return
+	}
+	if node.Type != html.ElementNode {
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Br:
+		b.WriteString("\\\n")
+	case atom.Strong, atom.B:
+		wrapInline(b, node, "**")
+	case atom.Em, atom.I:
+		wrapInline(b, node, "*")
+	case atom.S, atom.Del:
+		wrapInline(b, node, "~~")
+	case atom.Code:
+		b.WriteString("`" + strings.ReplaceAll(textContent(node), "`", "'") + "`")
+	case atom.A:
+		b.WriteString("[" + inlineMarkdown(node) + "](" + markdownURL(attrValue(node, "href")) + ")")
+	case atom.Img:
+		b.WriteString("![" + escapeMarkdown(attrValue(node, "alt")) + "](" + markdownURL(attrValue(node, "src")) + ")")
+	case atom.Span:
+		if ref := attrValue(node, recordMentionAttr); ref != "" {
+			b.WriteString("@[" + escapeMarkdown(strings.TrimPrefix(textContent(node), "@")) + "](" + recordMentionScheme + ref + ")")
+		} else {
+			b.WriteString(inlineMarkdown(node))
+		}
+	default:
+		// 下划线等 Markdown 不支持的标记只保留文本
+		b.WriteString(inlineMarkdown(node))
+	}
+}
+
+// wrapInline 用标记包裹行内内容，首尾空白移到标记外侧
+func wrapInline(b *strings.Builder, node *html.Node, marker string) {
+	content := inlineMarkdown(node)
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		b.WriteString(content)
+		return
+	}
+	if strings.HasPrefix(content, " ") {
+		b.WriteByte(' ')
+	}
+	b.WriteString(marker + trimmed + marker)
+	if strings.HasSuffix(content, " ") {
+		b.WriteByte(' ')
+	}
+}
+
+// escapeMarkdown 转义文本中会被解释为 Markdown 语法的字符
+func escapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\', '`', '*', '_', '[', ']', '~', '#', '>':
+			b.WriteByte('\\')
+		case '-', '+':
+			// 仅行首的列表标记需要转义
+			if i == 0 {
+				b.WriteByte('\\')
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// markdownURL 编码地址中会截断 Markdown 链接的字符
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(u)
+}
+
+// HTMLToText 将 HTML 净化后转换为纯文本：块级元素之间以空行分隔，列表项以 "- " 或序号开头，
+// 换行与记录提及的标题保留，其余标记去掉
+func HTMLToText(input string) string {
+	nodes, err := parseFragment(Sanitize(input))
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, node := range nodes {
+		writeText(&b, node)
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Trim(line, " ")
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+func writeText(b *strings.Builder, node *html.Node) {
+	if node.Type == html.TextNode {
+		b.WriteString(whitespacePattern.ReplaceAllString(node.Data, " "))
+		return
+	}
+	if node.Type != html.ElementNode {
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Br:
+		b.WriteByte('\n')
+	case atom.Pre:
+		b.WriteString("\n\n" + strings.TrimSuffix(textContent(node), "\n") + "\n\n")
+	case atom.Ul, atom.Ol:
+		b.WriteString("\n\n")
+		n := 1
+		if start, err := strconv.Atoi(attrValue(node, "start")); err == nil {
+			n = start
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.DataAtom != atom.Li {
+				continue
+			}
+			if node.DataAtom == atom.Ol {
+				b.WriteString(strconv.Itoa(n) + ". ")
+				n++
+			} else {
+				b.WriteString("- ")
+			}
+			var item strings.Builder
+			for grandchild := child.FirstChild; grandchild != nil; grandchild = grandchild.NextSibling {
+				writeText(&item, grandchild)
+			}
+			b.WriteString(strings.TrimSpace(whitespacePattern.ReplaceAllString(item.String(), " ")) + "\n")
+		}
+		b.WriteByte('\n')
+	case atom.Img:
+		b.WriteString(attrValue(node, "alt"))
+	default:
+		block := isBlockElement(node)
+		if block {
+			b.WriteString("\n\n")
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			writeText(b, child)
+		}
+		if block {
+			b.WriteString("\n\n")
+		}
+	}
+}
+
+func textContent(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package richtext
+
+import "testing"
+
+func TestMarkdownToHTML(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"heading", "# Title", "<h1>Title</h1>"},
+		{"paragraph", "hello **bold** and *em* ~~gone~~ `code`", "<p>hello <strong>bold</strong> and <em>em</em> <del>gone</del> <code>code</code></p>"},
+		{"link", "[site](https://example.com)", `<p><a href="https://example.com" rel="noopener noreferrer nofollow">site</a></p>`},
+		{"unsafe link", "[x](javascript:alert(1))", `<p><a rel="noopener noreferrer nofollow">x</a>)</p>`},
+		{"raw html escaped", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"list", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"ordered", "3. a\n4. b", `<ol start="3"><li>a</li><li>b</li></ol>`},
+		{"quote", "> quoted\n> text", "<blockquote><p>quoted\ntext</p></blockquote>"},
+		{"code block", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>"},
+		{"hard break", "a\\\nb", "<p>a<br>b</p>"},
+		{"snake case", "snake_case_name", "<p>snake_case_name</p>"},
+		{"rule", "a\n\n---", "<p>a</p><hr>"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := MarkdownToHTML(tc.input); got != tc.want {
+				t.Fatalf("MarkdownToHTML(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"blocks", "<h2>T</h2><p>a <b>b</b> <i>c</i></p>", "## T\n\na **b** *c*"},
+		{"list", "<ol><li>x</li><li>y</li></ol>", "1. x\n2. y"},
+		{"quote", "<blockquote><p>q</p></blockquote>", "> q"},
+		{"link", `<a href="https://e.com/a(b)">t</a>`, "[t](https://e.com/a%28b%29)"},
+		{"escape", "<p>1 * 2 = [x]</p>", `1 \* 2 = \[x\]`},
+		{"inline root", "text <em>em</em>", "text *em*"},
+		{"break", "<p>a<br>b</p>", "a\\\nb"},
+		{"pre", "<pre><code>x &lt; y</code></pre>", "```\nx < y\n```"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := HTMLToMarkdown(tc.input); got != tc.want {
+				t.Fatalf("HTMLToMarkdown(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"blocks", "<h2>T</h2><p>a <b>b</b>   <i>c</i></p>", "T\n\na b c"},
+		{"list", `<ol start="2"><li>x</li><li><p>y</p></li></ol><ul><li>z</li></ul>`, "2. x\n3. y\n\n- z"},
+		{"break", "<p>a<br>b</p>", "a\nb"},
+		{"no escape", "<p>1 * 2 = [x] &amp; y</p>", "1 * 2 = [x] & y"},
+		{"script dropped", "<p>a</p><script>alert(1)</script>", "a"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := HTMLToText(tc.input); got != tc.want {
+				t.Fatalf("HTMLToText(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	inputs := []string{
+		"# Title\n\nhello **bold** *em* [link](https://example.com)\n\n- a\n- b",
+		"a\\\nb\n\n> quote\n\n```\ncode\n```",
+		`literal \* star and \[brackets\]`,
+	}
+	for _, input := range inputs {
+		html := MarkdownToHTML(input)
+		if got := MarkdownToHTML(HTMLToMarkdown(html)); got != html {
+			t.Errorf("round trip changed HTML:\n%s\n%s", html, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package richtext
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 记录提及在长文本中写作 @[标题](record:表ID/记录ID)，在富文本中存为
+// <span data-record-mention="表ID/记录ID">@标题</span>，两种写法通过 Markdown 转换互通。
+// 标题只是写入时的展示文本，被提及的记录以表ID与记录ID标识
+
+// recordMentionAttr 富文本中标记记录提及的属性
+const recordMentionAttr = "data-record-mention"
+
+// recordMentionScheme 长文本（Markdown）中记录提及的地址前缀
+const recordMentionScheme = "record:"
+
+var (
+	recordMentionPattern = regexp.MustCompile(`@\[((?:\\.|[^\]\\])*)\]\(record:([A-Za-z0-9_]+)/([A-Za-z0-9_]+)\)`)
+	mentionRefPattern    = regexp.MustCompile(`^([A-Za-z0-9_]+)/([A-Za-z0-9_]+)$`)
+	markdownEscape       = regexp.MustCompile(`\\(.)`)
+)
+
+// RecordMention 文本中提及的记录
+type RecordMention struct {
+	TableID  string `json:"tableId"`
+	RecordID string `json:"recordId"`
+	Title    string `json:"title"`
+}
+
+// RecordMentionToken 生成长文本中的记录提及
+func RecordMentionToken(tableID, recordID, title string) string {
+	return "@[" + escapeMarkdown(title) + "](" + recordMentionScheme + tableID + "/" + recordID + ")"
+}
+
+// RecordMentionsInText 长文本中的记录提及（按出现顺序，可能重复）
+func RecordMentionsInText(text string) []RecordMention {
+	var mentions []RecordMention
+	for _, m := range recordMentionPattern.FindAllStringSubmatch(text, -1) {
+		mentions = append(mentions, RecordMention{
+			TableID:  m[2],
+			RecordID: m[3],
+			Title:    markdownEscape.ReplaceAllString(m[1], "$1"),
+		})
+	}
+	return mentions
+}
+
+// ReplaceRecordMentions 将长文本中的记录提及替换为 @标题（用于打印等纯文本展示）
+func ReplaceRecordMentions(text string) string {
+	if !strings.Contains(text, "@[") {
+		return text
+	}
+	return recordMentionPattern.ReplaceAllStringFunc(text, func(token string) string {
+		m := recordMentionPattern.FindStringSubmatch(token)
+		return "@" + markdownEscape.ReplaceAllString(m[1], "$1")
+	})
+}
+
+// RecordMentionsInHTML 富文本中的记录提及（按出现顺序，可能重复）
+func RecordMentionsInHTML(input string) []RecordMention {
+	if !strings.Contains(input, recordMentionAttr) {
+		return nil
+	}
+	nodes, err := parseFragment(input)
+	if err != nil {
+		return nil
+	}
+	var mentions []RecordMention
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.DataAtom == atom.Span {
+			if m := mentionRefPattern.FindStringSubmatch(attrValue(node, recordMentionAttr)); m != nil {
+				mentions = append(mentions, RecordMention{
+					TableID:  m[1],
+					RecordID: m[2],
+					Title:    strings.TrimPrefix(textContent(node), "@"),
+				})
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return mentions
+}
+
+// renderRecordMention 渲染 Markdown 中的记录提及（s 以 [ 开头，紧跟在 @ 之后），返回消耗的字节数
+func renderRecordMention(b *strings.Builder, s string) (int, bool) {
+	text, dest, n, ok := parseLink(s)
+	if !ok || !strings.HasPrefix(dest, recordMentionScheme) {
+		return 0, false
+	}
+	ref := strings.TrimPrefix(dest, recordMentionScheme)
+	if !mentionRefPattern.MatchString(ref) {
+		return 0, false
+	}
+	b.WriteString(`<span ` + recordMentionAttr + `="` + html.EscapeString(ref) + `">@`)
+	b.WriteString(html.EscapeString(markdownEscape.ReplaceAllString(text, "$1")))
+	b.WriteString("</span>")
+	return n, true
+}
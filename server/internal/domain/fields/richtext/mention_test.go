@@ -0,0 +1,61 @@
+package richtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordMentionsInText(t *testing.T) {
+	text := "见 " + RecordMentionToken("tbl1", "rec1", "发布[计划]") + " 与 @[旧标题](record:tbl2/rec2)，[普通链接](https://example.com) 与 @[无效](record:tbl3)"
+	got := RecordMentionsInText(text)
+	want := []RecordMention{
+		{TableID: "tbl1", RecordID: "rec1", Title: "发布[计划]"},
+		{TableID: "tbl2", RecordID: "rec2", Title: "旧标题"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("期望 %+v，得到 %+v", want, got)
+	}
+}
+
+func TestReplaceRecordMentions(t *testing.T) {
+	text := "见 " + RecordMentionToken("tbl1", "rec1", "发布[计划]") + " 与 [普通链接](https://example.com)"
+	if got := ReplaceRecordMentions(text); got != "见 @发布[计划] 与 [普通链接](https://example.com)" {
+		t.Errorf("替换结果错误: %q", got)
+	}
+}
+
+func TestRecordMentionsInHTML(t *testing.T) {
+	input := `<p>见 <span data-record-mention="tbl1/rec1">@发布计划</span> 与 <span class="x">普通文本</span></p>`
+	got := RecordMentionsInHTML(Sanitize(input))
+	want := []RecordMention{{TableID: "tbl1", RecordID: "rec1", Title: "发布计划"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("期望 %+v，得到 %+v", want, got)
+	}
+}
+
+func TestSanitizeRecordMention(t *testing.T) {
+	cases := map[string]string{
+		`<span data-record-mention="tbl1/rec1" onclick="x()">@计划</span>`:  `<span data-record-mention="tbl1/rec1">@计划</span>`,
+		`<span data-record-mention="tbl1/rec1&quot;><script>">@计划</span>`: `@计划`,
+		`<span style="color:red">文本</span>`:                               `文本`,
+	}
+	for input, want := range cases {
+		if got := Sanitize(input); got != want {
+			t.Errorf("%s: 期望 %s，得到 %s", input, want, got)
+		}
+	}
+}
+
+func TestRecordMentionMarkdownRoundTrip(t *testing.T) {
+	markdown := "负责 @[发布计划](record:tbl1/rec1) 的后续"
+	htmlText := MarkdownToHTML(markdown)
+	if want := `<p>负责 <span data-record-mention="tbl1/rec1">@发布计划</span> 的后续</p>`; htmlText != want {
+		t.Errorf("期望 %s，得到 %s", want, htmlText)
+	}
+	if got := HTMLToMarkdown(htmlText); got != markdown {
+		t.Errorf("期望 %s，得到 %s", markdown, got)
+	}
+	if got := MarkdownToHTML("邮件 a@[b](https://example.com)"); got != `<p>邮件 a@<a href="https://example.com" rel="noopener noreferrer nofollow">b</a></p>` {
+		t.Errorf("普通链接不应作为提及，得到 %s", got)
+	}
+}
@@ -0,0 +1,202 @@
+package richtext
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 富文本字段以净化后的 HTML 片段存储，前端编辑器与 Markdown 之间通过本包转换
+
+// allowedAttrs 元素白名单及各元素允许的属性
+var allowedAttrs = map[atom.Atom][]string{
+	atom.P:          nil,
+	atom.Br:         nil,
+	atom.Hr:         nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Strong:     nil,
+	atom.B:          nil,
+	atom.Em:         nil,
+	atom.I:          nil,
+	atom.U:          nil,
+	atom.S:          nil,
+	atom.Del:        nil,
+	atom.Code:       nil,
+	atom.Pre:        nil,
+	atom.Blockquote: nil,
+	atom.Ul:         nil,
+	atom.Ol:         {"start"},
+	atom.Li:         nil,
+	atom.A:          {"href", "title"},
+	atom.Img:        {"src", "alt", "title"},
+	atom.Span:       {recordMentionAttr}, // 只保留记录提及，其余 span 只保留文本内容
+}
+
+// droppedElements 连同内容一起移除的元素，其余不在白名单中的元素只保留文本内容
+var droppedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Template: true,
+	atom.Noscript: true,
+	atom.Textarea: true,
+	atom.Select:   true,
+	atom.Svg:      true,
+	atom.Math:     true,
+	atom.Head:     true,
+	atom.Title:    true,
+}
+
+// voidElements 无结束标签的元素
+var voidElements = map[atom.Atom]bool{
+	atom.Br:  true,
+	atom.Hr:  true,
+	atom.Img: true,
+}
+
+// Sanitize 按白名单净化 HTML 片段
+// 移除脚本、样式与事件属性，链接仅允许 http/https/mailto 及相对地址，图片仅允许 http/https
+func Sanitize(input string) string {
+	nodes, err := parseFragment(input)
+	if err != nil {
+		return html.EscapeString(input)
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		renderNode(&b, node)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func parseFragment(input string) ([]*html.Node, error) {
+	return html.ParseFragment(strings.NewReader(input), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+}
+
+func renderNode(b *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	default:
+		// 注释、文档类型等直接丢弃
+		return
+	}
+
+	if droppedElements[node.DataAtom] {
+		return
+	}
+	attrNames, allowed := allowedAttrs[node.DataAtom]
+	if !allowed {
+		renderChildren(b, node)
+		return
+	}
+
+	attrs := sanitizeAttrs(node, attrNames)
+	if node.DataAtom == atom.Img && attrs["src"] == "" {
+		return
+	}
+	if node.DataAtom == atom.Span && attrs[recordMentionAttr] == "" {
+		renderChildren(b, node)
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(node.DataAtom.String())
+	for _, name := range attrNames {
+		if value, ok := attrs[name]; ok {
+			b.WriteByte(' ')
+			b.WriteString(name)
+			b.WriteString(`="`)
+			b.WriteString(html.EscapeString(value))
+			b.WriteByte('"')
+		}
+	}
+	if node.DataAtom == atom.A {
+		b.WriteString(` rel="noopener noreferrer nofollow"`)
+	}
+	b.WriteByte('>')
+	if voidElements[node.DataAtom] {
+		return
+	}
+	renderChildren(b, node)
+	b.WriteString("</")
+	b.WriteString(node.DataAtom.String())
+	b.WriteByte('>')
+}
+
+func renderChildren(b *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		renderNode(b, child)
+	}
+}
+
+// sanitizeAttrs 过滤出允许且取值安全的属性
+func sanitizeAttrs(node *html.Node, allowed []string) map[string]string {
+	attrs := make(map[string]string)
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" || !containsString(allowed, attr.Key) {
+			continue
+		}
+		value := strings.TrimSpace(attr.Val)
+		switch attr.Key {
+		case "href":
+			if !isSafeURL(value, "http", "https", "mailto") {
+				continue
+			}
+		case "src":
+			if !isSafeURL(value, "http", "https") || !strings.Contains(value, ":") {
+				continue
+			}
+		case "start":
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				continue
+			}
+		case recordMentionAttr:
+			if !mentionRefPattern.MatchString(value) {
+				continue
+			}
+		}
+		attrs[attr.Key] = value
+	}
+	return attrs
+}
+
+// isSafeURL 地址可被解析且协议在允许列表中（相对地址视为安全）
+func isSafeURL(raw string, schemes ...string) bool {
+	if raw == "" {
+		return false
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	return containsString(schemes, strings.ToLower(u.Scheme))
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
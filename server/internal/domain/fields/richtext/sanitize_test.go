@@ -0,0 +1,32 @@
+package richtext
+
+import "testing"
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "<p>hello <strong>world</strong></p>", "<p>hello <strong>world</strong></p>"},
+		{"script removed", `<p>hi</p><script>alert(1)</script>`, "<p>hi</p>"},
+		{"event handler", `<p onclick="alert(1)">x</p>`, "<p>x</p>"},
+		{"javascript link", `<a href="javascript:alert(1)">x</a>`, `<a rel="noopener noreferrer nofollow">x</a>`},
+		{"entity encoded scheme", `<a href="&#106;avascript:alert(1)">x</a>`, `<a rel="noopener noreferrer nofollow">x</a>`},
+		{"safe link", `<a href="https://example.com/?a=1&b=2" target="_blank">x</a>`, `<a href="https://example.com/?a=1&amp;b=2" rel="noopener noreferrer nofollow">x</a>`},
+		{"unknown tag unwrapped", `<div><span style="color:red">x</span></div>`, "x"},
+		{"image data uri", `<img src="data:image/png;base64,AAAA">`, ""},
+		{"image", `<img src="https://example.com/a.png" alt="a" onerror="x">`, `<img src="https://example.com/a.png" alt="a">`},
+		{"svg dropped", `<svg><script>alert(1)</script></svg>ok`, "ok"},
+		{"text escaped", `a &lt;b&gt; c`, "a &lt;b&gt; c"},
+		{"comment", `<!-- x --><p>y</p>`, "<p>y</p>"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Sanitize(tc.input); got != tc.want {
+				t.Fatalf("Sanitize(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
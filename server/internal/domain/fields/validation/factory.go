@@ -6,6 +6,7 @@ import (
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
 )
 
 // ValidatorFactory 验证器工厂
@@ -34,11 +35,13 @@ func (f *ValidatorFactory) registerValidators() {
 	f.validators["text"] = singleLineValidator
 
 	f.Register(NewLongTextValidator())
+	f.Register(NewRichTextValidator())
 
 	// 数字类型
 	f.Register(NewNumberValidator())
 	f.Register(NewRatingValidator())
 	f.Register(NewPercentValidator())
+	f.Register(NewProgressValidator())
 	f.Register(NewCurrencyValidator())
 	f.Register(NewDurationValidator())
 
@@ -67,6 +70,9 @@ func (f *ValidatorFactory) registerValidators() {
 	// 用户类型
 	f.Register(NewUserValidator())
 
+	// 地理位置
+	f.Register(NewLocationValidator())
+
 	// 自动编号
 	f.Register(NewAutoNumberValidator())
 }
@@ -80,6 +86,10 @@ func (f *ValidatorFactory) Register(validator FieldValidator) {
 func (f *ValidatorFactory) GetValidator(fieldType valueobject.FieldType) (FieldValidator, error) {
 	validator, exists := f.validators[fieldType.String()]
 	if !exists {
+		// ✨ 自定义字段类型委托给插件校验
+		if plugin, ok := fieldplugin.Lookup(fieldType.String()); ok {
+			return NewCustomFieldValidator(plugin), nil
+		}
 		return nil, fmt.Errorf("不支持的字段类型: %s", fieldType.String())
 	}
 	return validator, nil
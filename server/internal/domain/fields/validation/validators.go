@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
+	"math"
 	"net/mail"
 	"net/url"
 	"regexp"
@@ -12,7 +14,9 @@ import (
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
 )
 
 // ==================== 文本类型验证器 ====================
@@ -120,6 +124,64 @@ func (v *LongTextValidator) ConvertStringToValue(ctx context.Context, str string
 	return str, nil
 }
 
+// RichTextValidator 富文本验证器
+// 单元格以净化后的 HTML 存储；写入时接受 HTML 字符串，或 {"html": ...} / {"markdown": ...} 对象
+type RichTextValidator struct{}
+
+func NewRichTextValidator() *RichTextValidator {
+	return &RichTextValidator{}
+}
+
+func (v *RichTextValidator) SupportedType() valueobject.FieldType {
+	fieldType, _ := valueobject.NewFieldType("richText")
+	return fieldType
+}
+
+func (v *RichTextValidator) ValidateCell(ctx context.Context, value interface{}, field *entity.Field) *ValidationResult {
+	if value == nil {
+		return Success(nil)
+	}
+
+	var content string
+	switch val := value.(type) {
+	case string:
+		content = richtext.Sanitize(val)
+	case map[string]interface{}:
+		if str, ok := val["html"].(string); ok {
+			content = richtext.Sanitize(str)
+		} else if str, ok := val["markdown"].(string); ok {
+			content = richtext.MarkdownToHTML(str)
+		} else {
+			return Failure(NewValidationError(field.Name().String(), "富文本对象必须包含 html 或 markdown", value))
+		}
+	default:
+		return Failure(NewValidationError(field.Name().String(), "必须是字符串或富文本对象", value))
+	}
+
+	if content == "" {
+		return Success(nil)
+	}
+
+	return Success(content)
+}
+
+func (v *RichTextValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if result := v.ValidateCell(ctx, value, field); result.Success {
+		return result.Value
+	}
+
+	// 其他类型按纯文本处理
+	return richtext.Sanitize(html.EscapeString(fmt.Sprintf("%v", value)))
+}
+
+func (v *RichTextValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
+	return richtext.Sanitize(str), nil
+}
+
 // ==================== 数字类型验证器 ====================
 
 // NumberValidator 数字验证器
@@ -248,17 +310,59 @@ func (v *RatingValidator) ValidateCell(ctx context.Context, value interface{}, f
 		return result
 	}
 
-	// 评分必须在 1-10 之间
+	// 评分必须是 0 到最大评分之间的整数
 	if result.Value != nil {
 		rating, _ := result.Value.(float64)
-		if rating < 0 || rating > 10 {
-			return Failure(NewValidationError(field.Name().String(), "评分必须在 0-10 之间", value))
+		max := ratingMax(field)
+		if rating != math.Trunc(rating) {
+			return Failure(NewValidationError(field.Name().String(), "评分必须是整数", value))
+		}
+		if rating < 0 || rating > float64(max) {
+			return Failure(NewValidationError(field.Name().String(), fmt.Sprintf("评分必须在 0-%d 之间", max), value))
 		}
 	}
 
 	return result
 }
 
+// Repair 四舍五入取整并限制在 [0, 最大评分]（数字字段转换为评分字段时使用）
+func (v *RatingValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	repaired := v.NumberValidator.Repair(ctx, value, field)
+	if repaired == nil {
+		return nil
+	}
+	num, ok := toFloat(repaired)
+	if !ok {
+		return nil
+	}
+	return float64(valueobject.NormalizeRating(num, ratingMax(field)))
+}
+
+// toFloat 将 NumberValidator.Repair 返回的数字统一为 float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func ratingMax(field *entity.Field) int {
+	if options := field.Options(); options != nil {
+		return options.Rating.MaxValue()
+	}
+	return valueobject.DefaultRatingMax
+}
+
 // PercentValidator 百分比验证器
 type PercentValidator struct {
 	NumberValidator
@@ -287,6 +391,58 @@ func (v *CurrencyValidator) SupportedType() valueobject.FieldType {
 	return fieldType
 }
 
+// ProgressValidator 进度验证器（0-100 的完成百分比）
+type ProgressValidator struct {
+	NumberValidator
+}
+
+func NewProgressValidator() *ProgressValidator {
+	return &ProgressValidator{}
+}
+
+func (v *ProgressValidator) SupportedType() valueobject.FieldType {
+	fieldType, _ := valueobject.NewFieldType("progress")
+	return fieldType
+}
+
+// ValidateCell 支持数字及 "75%" 形式的字符串
+func (v *ProgressValidator) ValidateCell(ctx context.Context, value interface{}, field *entity.Field) *ValidationResult {
+	if value == nil {
+		return Success(nil)
+	}
+	if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+		return Success(nil)
+	}
+	progress, err := valueobject.ParseProgress(value)
+	if err != nil {
+		return Failure(NewValidationError(field.Name().String(), err.Error(), value))
+	}
+	return Success(progress)
+}
+
+// Repair 超出范围的值限制在 [0, 100]（数字、百分比字段转换为进度字段时使用）
+func (v *ProgressValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	if str, ok := value.(string); ok {
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(str), "%"))
+	}
+	repaired := v.NumberValidator.Repair(ctx, value, field)
+	if repaired == nil {
+		return nil
+	}
+	num, ok := toFloat(repaired)
+	if !ok {
+		return nil
+	}
+	return valueobject.ClampProgress(num)
+}
+
+func (v *ProgressValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	return valueobject.ParseProgress(str)
+}
+
 // DurationValidator 时长验证器
 type DurationValidator struct {
 	NumberValidator
@@ -301,6 +457,44 @@ func (v *DurationValidator) SupportedType() valueobject.FieldType {
 	return fieldType
 }
 
+// ValidateCell 数字按秒数处理，字符串支持 "1h 30m"、"1:30" 等友好格式
+func (v *DurationValidator) ValidateCell(ctx context.Context, value interface{}, field *entity.Field) *ValidationResult {
+	if str, ok := value.(string); ok {
+		if strings.TrimSpace(str) == "" {
+			return Success(nil)
+		}
+		seconds, err := valueobject.ParseDuration(str)
+		if err != nil {
+			return Failure(NewValidationError(field.Name().String(), err.Error(), value))
+		}
+		return Success(seconds)
+	}
+
+	result := v.NumberValidator.ValidateCell(ctx, value, field)
+	if seconds, ok := result.Value.(float64); ok && result.Success && seconds < 0 {
+		return Failure(NewValidationError(field.Name().String(), "时长不能为负数", value))
+	}
+	return result
+}
+
+func (v *DurationValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	if str, ok := value.(string); ok {
+		seconds, err := valueobject.ParseDuration(str)
+		if err != nil {
+			return nil
+		}
+		return seconds
+	}
+	return v.NumberValidator.Repair(ctx, value, field)
+}
+
+func (v *DurationValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	return valueobject.ParseDuration(str)
+}
+
 // ==================== 布尔类型验证器 ====================
 
 // CheckboxValidator 复选框验证器
@@ -872,6 +1066,66 @@ func (v *UserValidator) ConvertStringToValue(ctx context.Context, str string, fi
 	return result, nil
 }
 
+// LocationValidator 地理位置验证器
+// 接受 {"lat":..,"lng":..,"address":..} 对象或 "lat,lng" 字符串，统一存储为对象
+type LocationValidator struct{}
+
+func NewLocationValidator() *LocationValidator {
+	return &LocationValidator{}
+}
+
+func (v *LocationValidator) SupportedType() valueobject.FieldType {
+	fieldType, _ := valueobject.NewFieldType("location")
+	return fieldType
+}
+
+func (v *LocationValidator) ValidateCell(ctx context.Context, value interface{}, field *entity.Field) *ValidationResult {
+	if value == nil {
+		return Success(nil)
+	}
+	if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+		return Success(nil)
+	}
+
+	location, err := valueobject.ParseLocation(value)
+	if err != nil {
+		return Failure(NewValidationError(field.Name().String(), err.Error(), value))
+	}
+
+	return Success(location.ToMap())
+}
+
+func (v *LocationValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	if result := v.ValidateCell(ctx, value, field); result.Success {
+		return result.Value
+	}
+
+	// 无法识别的位置置空
+	return nil
+}
+
+func (v *LocationValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+
+	// 优先按 JSON 对象解析，否则按 "lat,lng" 解析
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(str), &obj); err == nil {
+		location, err := valueobject.ParseLocation(obj)
+		if err != nil {
+			return nil, err
+		}
+		return location.ToMap(), nil
+	}
+
+	location, err := valueobject.ParseLocation(str)
+	if err != nil {
+		return nil, err
+	}
+	return location.ToMap(), nil
+}
+
 // AutoNumberValidator 自动编号验证器
 type AutoNumberValidator struct{}
 
@@ -897,3 +1151,54 @@ func (v *AutoNumberValidator) Repair(ctx context.Context, value interface{}, fie
 func (v *AutoNumberValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
 	return nil, fmt.Errorf("自动编号字段不能设置值")
 }
+
+// CustomFieldValidator 自定义字段类型验证器 ✨
+// 将校验委托给通过 fieldplugin 注册的插件，插件返回的值作为存储值
+type CustomFieldValidator struct {
+	plugin fieldplugin.FieldType
+}
+
+func NewCustomFieldValidator(plugin fieldplugin.FieldType) *CustomFieldValidator {
+	return &CustomFieldValidator{plugin: plugin}
+}
+
+func (v *CustomFieldValidator) SupportedType() valueobject.FieldType {
+	fieldType, _ := valueobject.NewFieldType(v.plugin.Name())
+	return fieldType
+}
+
+func (v *CustomFieldValidator) ValidateCell(ctx context.Context, value interface{}, field *entity.Field) *ValidationResult {
+	if value == nil {
+		return Success(nil)
+	}
+
+	normalized, err := v.plugin.Validate(value, customOptions(field))
+	if err != nil {
+		return Failure(NewValidationError(field.Name().String(), err.Error(), value))
+	}
+	return Success(normalized)
+}
+
+func (v *CustomFieldValidator) Repair(ctx context.Context, value interface{}, field *entity.Field) interface{} {
+	if result := v.ValidateCell(ctx, value, field); result.Success {
+		return result.Value
+	}
+
+	// 插件拒绝的值置空
+	return nil
+}
+
+func (v *CustomFieldValidator) ConvertStringToValue(ctx context.Context, str string, field *entity.Field) (interface{}, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	return v.plugin.Validate(str, customOptions(field))
+}
+
+// customOptions 字段的自定义选项
+func customOptions(field *entity.Field) map[string]interface{} {
+	if options := field.Options(); options != nil {
+		return options.Custom
+	}
+	return nil
+}
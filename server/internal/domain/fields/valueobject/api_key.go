@@ -0,0 +1,78 @@
+package valueobject
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields"
+)
+
+// MaxAPIKeyLength API 键的最大长度
+const MaxAPIKeyLength = 64
+
+var (
+	apiKeyPattern     = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	apiKeyInvalidChar = regexp.MustCompile(`[^a-z0-9_]+`)
+	apiKeyUnderscores = regexp.MustCompile(`_{2,}`)
+)
+
+// APIKey 字段的 API 键值对象
+// 外部 API 使用的稳定标识（小写字母开头，只含小写字母、数字和下划线），
+// 创建字段时由名称生成，重命名字段时保持不变，同一张表内唯一
+type APIKey struct {
+	value string
+}
+
+// NewAPIKey 创建 API 键
+func NewAPIKey(value string) (APIKey, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || len(trimmed) > MaxAPIKeyLength || !apiKeyPattern.MatchString(trimmed) {
+		return APIKey{}, fields.ErrInvalidAPIKey
+	}
+	return APIKey{value: trimmed}, nil
+}
+
+// GenerateAPIKey 由字段名称生成 API 键；taken 判断键是否已被同表其他字段占用，
+// 占用时依次追加 _2、_3 … 直到不冲突
+func GenerateAPIKey(name string, taken func(key string) bool) APIKey {
+	base := apiKeyBase(name)
+	key := base
+	for i := 2; taken != nil && taken(key); i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		trimmed := base
+		if len(trimmed)+len(suffix) > MaxAPIKeyLength {
+			trimmed = strings.TrimRight(trimmed[:MaxAPIKeyLength-len(suffix)], "_")
+		}
+		key = trimmed + suffix
+	}
+	return APIKey{value: key}
+}
+
+// apiKeyBase 名称转换为 API 键（中文转拼音，其他字符替换为下划线）
+func apiKeyBase(name string) string {
+	key := strings.ToLower(convertToASCII(strings.TrimSpace(name)))
+	key = apiKeyInvalidChar.ReplaceAllString(key, "_")
+	key = apiKeyUnderscores.ReplaceAllString(key, "_")
+	key = strings.Trim(key, "_")
+	if key == "" {
+		key = "field"
+	}
+	if !isLetter(rune(key[0])) {
+		key = "f_" + key
+	}
+	if len(key) > MaxAPIKeyLength {
+		key = strings.TrimRight(key[:MaxAPIKeyLength], "_")
+	}
+	return key
+}
+
+// String 获取字符串值
+func (k APIKey) String() string {
+	return k.value
+}
+
+// IsEmpty 是否未设置（升级前创建、尚未回填的字段）
+func (k APIKey) IsEmpty() bool {
+	return k.value == ""
+}
@@ -0,0 +1,51 @@
+package valueobject
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	for _, valid := range []string{"status", "order_count", "a1", " customer_name "} {
+		if _, err := NewAPIKey(valid); err != nil {
+			t.Errorf("NewAPIKey(%q) 应合法: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "Status", "1st", "_id", "order-count", "名称", strings.Repeat("a", MaxAPIKeyLength+1)} {
+		if _, err := NewAPIKey(invalid); err == nil {
+			t.Errorf("NewAPIKey(%q) 应不合法", invalid)
+		}
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"Customer Name", "customer_name"},
+		{"  Order -- Count  ", "order_count"},
+		{"2024 Revenue", "f_2024_revenue"},
+		{"客户", "kehu"},
+		{"***", "field"},
+	}
+	for _, c := range cases {
+		if got := GenerateAPIKey(c.name, nil).String(); got != c.want {
+			t.Errorf("GenerateAPIKey(%q) = %q, want %q", c.name, got, c.want)
+		}
+		if _, err := NewAPIKey(GenerateAPIKey(c.name, nil).String()); err != nil {
+			t.Errorf("生成的键 %q 应合法", c.name)
+		}
+	}
+
+	taken := map[string]bool{"status": true, "status_2": true}
+	if got := GenerateAPIKey("Status", func(k string) bool { return taken[k] }).String(); got != "status_3" {
+		t.Errorf("冲突时应追加序号，得到 %q", got)
+	}
+
+	long := strings.Repeat("x", 80)
+	key := GenerateAPIKey(long, func(k string) bool { return len(k) == MaxAPIKeyLength && !strings.Contains(k, "_") })
+	if len(key.String()) > MaxAPIKeyLength || !strings.HasSuffix(key.String(), "_2") {
+		t.Errorf("超长名称冲突时应截断后追加序号，得到 %q", key.String())
+	}
+}
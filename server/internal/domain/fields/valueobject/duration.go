@@ -0,0 +1,230 @@
+package valueobject
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 时长字段统一以秒数存储（NUMERIC），展示时按 DurationOptions.Format 格式化
+
+// 时长显示格式
+const (
+	DurationFormatHMM    = "h:mm"
+	DurationFormatHMMSS  = "h:mm:ss"
+	DurationFormatDHMM   = "d:h:mm"
+	DurationFormatDHMMSS = "d:h:mm:ss"
+	DurationFormatHuman  = "human" // 1d 2h 30m
+)
+
+// DefaultDurationFormat 未配置格式时使用的显示格式
+const DefaultDurationFormat = DurationFormatHMM
+
+// DurationFormats 支持的时长显示格式
+var DurationFormats = []string{
+	DurationFormatHMM,
+	DurationFormatHMMSS,
+	DurationFormatDHMM,
+	DurationFormatDHMMSS,
+	DurationFormatHuman,
+}
+
+// IsValidDurationFormat 是否为支持的时长显示格式（空字符串视为默认格式）
+func IsValidDurationFormat(format string) bool {
+	if format == "" {
+		return true
+	}
+	for _, f := range DurationFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// durationUnits 单位别名到秒数的映射（小写）
+var durationUnits = map[string]float64{
+	"w": 604800, "wk": 604800, "wks": 604800, "week": 604800, "weeks": 604800, "周": 604800,
+	"d": 86400, "day": 86400, "days": 86400, "天": 86400,
+	"h": 3600, "hr": 3600, "hrs": 3600, "hour": 3600, "hours": 3600, "小时": 3600, "时": 3600,
+	"m": 60, "min": 60, "mins": 60, "minute": 60, "minutes": 60, "分钟": 60, "分": 60,
+	"s": 1, "sec": 1, "secs": 1, "second": 1, "seconds": 1, "秒": 1,
+}
+
+// ParseDuration 将用户输入解析为秒数
+// 支持：
+//   - 纯数字，按秒计："5400"
+//   - 带单位的片段组合："1h 30m"、"1h30m"、"1.5h"、"2d 3h 15m 10s"、"1小时30分钟"
+//   - 冒号格式："1:30"（h:mm）、"1:30:15"（h:mm:ss）、"2:1:30:15"（d:h:mm:ss）
+func ParseDuration(input string) (float64, error) {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return 0, fmt.Errorf("时长不能为空")
+	}
+
+	var seconds float64
+	var err error
+	switch {
+	case isPlainNumber(s):
+		seconds, err = strconv.ParseFloat(s, 64)
+	case strings.Contains(s, ":"):
+		seconds, err = parseColonDuration(s)
+	default:
+		seconds, err = parseUnitDuration(s)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, fmt.Errorf("无效的时长: %s", input)
+	}
+	if seconds < 0 {
+		return 0, fmt.Errorf("时长不能为负数")
+	}
+	return seconds, nil
+}
+
+func isPlainNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// parseColonDuration 解析 h:mm、h:mm:ss、d:h:mm:ss，最后一段允许小数
+func parseColonDuration(s string) (float64, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return 0, fmt.Errorf("无效的时长格式: %s", s)
+	}
+
+	// 按段数确定每段的单位：2 段为 时:分，3 段为 时:分:秒，4 段为 天:时:分:秒
+	units := []float64{86400, 3600, 60, 1}
+	switch len(parts) {
+	case 2:
+		units = units[1:3]
+	case 3:
+		units = units[1:]
+	}
+
+	var total float64
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		var (
+			n   float64
+			err error
+		)
+		if i == len(parts)-1 {
+			n, err = strconv.ParseFloat(part, 64)
+		} else {
+			var v int64
+			v, err = strconv.ParseInt(part, 10, 64)
+			n = float64(v)
+		}
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("无效的时长格式: %s", s)
+		}
+		// 首段不设上限，其余分、秒段必须小于 60
+		if i > 0 && units[i] < 3600 && n >= 60 {
+			return 0, fmt.Errorf("无效的时长格式: %s", s)
+		}
+		total += n * units[i]
+	}
+	return total, nil
+}
+
+// parseUnitDuration 解析 "1h 30m"、"1小时30分钟" 等带单位的片段组合
+func parseUnitDuration(s string) (float64, error) {
+	runes := []rune(strings.ToLower(s))
+	var total float64
+	matched := false
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) || r == ',' || r == '，' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+			i++
+		}
+		if start == i {
+			return 0, fmt.Errorf("无效的时长格式: %s", s)
+		}
+		n, err := strconv.ParseFloat(string(runes[start:i]), 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的时长格式: %s", s)
+		}
+
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		unitStart := i
+		for i < len(runes) && unicode.IsLetter(runes[i]) {
+			i++
+		}
+		unit, ok := durationUnits[string(runes[unitStart:i])]
+		if !ok {
+			return 0, fmt.Errorf("无法识别的时长单位: %s", string(runes[unitStart:i]))
+		}
+
+		total += n * unit
+		matched = true
+	}
+
+	if !matched {
+		return 0, fmt.Errorf("无效的时长格式: %s", s)
+	}
+	return total, nil
+}
+
+// FormatDuration 按显示格式格式化秒数
+// h:mm 与 d:h:mm 四舍五入到分钟，其余格式四舍五入到秒
+func FormatDuration(seconds float64, format string) string {
+	if format == "" || !IsValidDurationFormat(format) {
+		format = DefaultDurationFormat
+	}
+
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+
+	switch format {
+	case DurationFormatHMM, DurationFormatDHMM:
+		total := int64(math.Round(seconds / 60))
+		minutes, hours := total%60, total/60
+		if format == DurationFormatDHMM {
+			return fmt.Sprintf("%s%d:%02d:%02d", sign, hours/24, hours%24, minutes)
+		}
+		return fmt.Sprintf("%s%d:%02d", sign, hours, minutes)
+
+	case DurationFormatHMMSS, DurationFormatDHMMSS:
+		total := int64(math.Round(seconds))
+		secs, minutes, hours := total%60, total/60%60, total/3600
+		if format == DurationFormatDHMMSS {
+			return fmt.Sprintf("%s%d:%02d:%02d:%02d", sign, hours/24, hours%24, minutes, secs)
+		}
+		return fmt.Sprintf("%s%d:%02d:%02d", sign, hours, minutes, secs)
+
+	default: // DurationFormatHuman
+		total := int64(math.Round(seconds))
+		if total == 0 {
+			return "0s"
+		}
+		var parts []string
+		for _, u := range []struct {
+			label string
+			size  int64
+		}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}} {
+			if n := total / u.size; n > 0 {
+				parts = append(parts, strconv.FormatInt(n, 10)+u.label)
+				total %= u.size
+			}
+		}
+		return sign + strings.Join(parts, " ")
+	}
+}
@@ -0,0 +1,66 @@
+package valueobject
+
+import "testing"
+
+func TestParseDuration(t *testing.T) {
+	cases := map[string]float64{
+		"5400":           5400,
+		"1h 30m":         5400,
+		"1h30m":          5400,
+		"1.5h":           5400,
+		"90 min":         5400,
+		"2d 3h 15m 10s":  2*86400 + 3*3600 + 15*60 + 10,
+		"1 Hour, 5 Mins": 3900,
+		"1小时30分钟":        5400,
+		"2天":             172800,
+		"1:30":           5400,
+		"1:30:15":        5415,
+		"2:1:30:15":      2*86400 + 5415,
+		"0:00:01.5":      1.5,
+	}
+	for input, want := range cases {
+		got, err := ParseDuration(input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) 返回错误: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	invalid := []string{"", "abc", "1x", "h", "1:60", "1:2:3:4:5", "-5", "-1h", "NaN", "1h abc"}
+	for _, input := range invalid {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) 应返回错误", input)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	seconds := float64(86400 + 2*3600 + 30*60 + 45)
+	cases := []struct {
+		format string
+		want   string
+	}{
+		{"", "26:31"},
+		{DurationFormatHMM, "26:31"},
+		{DurationFormatHMMSS, "26:30:45"},
+		{DurationFormatDHMM, "1:02:31"},
+		{DurationFormatDHMMSS, "1:02:30:45"},
+		{DurationFormatHuman, "1d 2h 30m 45s"},
+		{"unknown", "26:31"},
+	}
+	for _, c := range cases {
+		if got := FormatDuration(seconds, c.format); got != c.want {
+			t.Errorf("FormatDuration(%v, %q) = %q, want %q", seconds, c.format, got, c.want)
+		}
+	}
+
+	if got := FormatDuration(-90, DurationFormatHMMSS); got != "-0:01:30" {
+		t.Errorf("负数格式化 = %q", got)
+	}
+	if got := FormatDuration(0, DurationFormatHuman); got != "0s" {
+		t.Errorf("零值格式化 = %q", got)
+	}
+}
@@ -1,5 +1,7 @@
 package valueobject
 
+import "time"
+
 // FieldOptions 字段选项值对象
 // 不同字段类型有不同的选项配置
 type FieldOptions struct {
@@ -42,9 +44,20 @@ type FieldOptions struct {
 	// Rating 选项
 	Rating *RatingOptions
 
+	// Progress 选项
+	Progress *ProgressOptions
+
 	// 通用配置（可选，某些字段类型会使用）
 	ShowAs     *ShowAsOptions     `json:"showAs,omitempty"`
 	Formatting *FormattingOptions `json:"formatting,omitempty"`
+
+	// 静态加密（仅可在创建字段时开启）
+	Encrypted        bool `json:"encrypted,omitempty"`
+	RevealInLogs     bool `json:"revealInLogs,omitempty"`     // 允许日志输出明文
+	RevealInWebhooks bool `json:"revealInWebhooks,omitempty"` // 允许Webhook推送明文
+
+	// ✨ 自定义字段类型（fieldplugin）的选项，按插件的选项 Schema 校验后原样保存
+	Custom map[string]interface{} `json:"custom,omitempty"`
 }
 
 // ShowAsOptions 显示方式配置（参考 Teable）
@@ -56,13 +69,14 @@ type ShowAsOptions struct {
 
 // FormattingOptions 格式化选项（通用）
 type FormattingOptions struct {
-	Type       string `json:"type,omitempty"`       // number, date, text
-	Precision  *int   `json:"precision,omitempty"`  // 数字精度
-	DateFormat string `json:"dateFormat,omitempty"` // 日期格式
-	TimeFormat string `json:"timeFormat,omitempty"` // 时间格式
-	TimeZone   string `json:"timeZone,omitempty"`   // 时区
-	ShowCommas bool   `json:"showCommas,omitempty"` // 显示千分位
-	Currency   string `json:"currency,omitempty"`   // 货币类型
+	Type           string `json:"type,omitempty"`           // number, date, text, duration
+	Precision      *int   `json:"precision,omitempty"`      // 数字精度
+	DateFormat     string `json:"dateFormat,omitempty"`     // 日期格式
+	TimeFormat     string `json:"timeFormat,omitempty"`     // 时间格式
+	TimeZone       string `json:"timeZone,omitempty"`       // 时区
+	ShowCommas     bool   `json:"showCommas,omitempty"`     // 显示千分位
+	Currency       string `json:"currency,omitempty"`       // 货币类型
+	DurationFormat string `json:"durationFormat,omitempty"` // 时长格式（Type 为 duration 时使用）
 }
 
 // FilterOptions 过滤选项（用于 Link 字段等）
@@ -160,14 +174,57 @@ type DateOptions struct {
 	TimeFormat   string  `json:"time_format,omitempty"`  // 12h, 24h
 	TimeZone     string  `json:"timezone,omitempty"`     // UTC, Asia/Shanghai, etc.
 	DefaultValue *string `json:"defaultValue,omitempty"` // 默认值，如 "now" 或具体日期字符串（参考 Teable）
+	// UseSameTimeZone 所有协作者使用相同时区（TimeZone，未配置时为 UTC）展示与过滤；
+	// 关闭时按各自的用户时区计算 today 等相对日期
+	UseSameTimeZone bool `json:"useSameTimeZone,omitempty"`
+}
+
+// Location 日期字段对请求用户生效的时区：开启 UseSameTimeZone 时为字段时区（未配置或无效时为 UTC），
+// 否则为 userLoc（为空时退回字段时区，再退回 UTC）
+func (o *DateOptions) Location(userLoc *time.Location) *time.Location {
+	var fieldLoc *time.Location
+	if o != nil && o.TimeZone != "" {
+		if loc, err := time.LoadLocation(o.TimeZone); err == nil {
+			fieldLoc = loc
+		}
+	}
+	if o != nil && o.UseSameTimeZone {
+		if fieldLoc != nil {
+			return fieldLoc
+		}
+		return time.UTC
+	}
+	if userLoc != nil {
+		return userLoc
+	}
+	if fieldLoc != nil {
+		return fieldLoc
+	}
+	return time.UTC
 }
 
 // AIOptions AI字段选项
 type AIOptions struct {
-	Provider string                 `json:"provider"`         // openai, anthropic, etc.
-	Model    string                 `json:"model"`            // gpt-4, claude-3, etc.
-	Prompt   string                 `json:"prompt"`           // AI提示词
-	Config   map[string]interface{} `json:"config,omitempty"` // 其他配置
+	Provider string                 `json:"provider"`         // ai.providers 中的服务商名称，为空时使用默认服务商
+	Model    string                 `json:"model"`            // 为空时使用服务商的默认模型（Azure 为部署名称）
+	Prompt   string                 `json:"prompt"`           // 提示词模板，{字段ID} 引用当前记录的字段值
+	Config   map[string]interface{} `json:"config,omitempty"` // 其他配置：temperature、maxTokens
+}
+
+// Temperature 采样温度（未配置时返回 nil）
+func (o *AIOptions) Temperature() *float64 {
+	if v, ok := o.Config["temperature"].(float64); ok {
+		return &v
+	}
+	return nil
+}
+
+// MaxTokens 输出令牌上限（未配置时返回 0）
+func (o *AIOptions) MaxTokens() int {
+	if v, ok := o.Config["maxTokens"].(float64); ok && v > 0 {
+		return int(v)
+	}
+	return 0
 }
 
 // CountOptions Count字段选项
@@ -178,7 +235,7 @@ type CountOptions struct {
 
 // DurationOptions Duration字段选项
 type DurationOptions struct {
-	Format string `json:"format"` // h:mm, h:mm:ss, d:h:mm, d:h:mm:ss
+	Format string `json:"format"` // h:mm, h:mm:ss, d:h:mm, d:h:mm:ss, human
 }
 
 // ButtonOptions Button字段选项
@@ -195,8 +252,15 @@ type UserOptions struct {
 
 // RatingOptions Rating字段选项
 type RatingOptions struct {
-	Max  int    `json:"max"`            // 最大星数（1-10）
-	Icon string `json:"icon,omitempty"` // 图标类型：star, heart, thumb, etc.
+	Max   int    `json:"max"`             // 最大星数（1-10）
+	Icon  string `json:"icon,omitempty"`  // 图标类型：star, heart, thumb, etc.
+	Color string `json:"color,omitempty"` // 图标颜色
+}
+
+// ProgressOptions Progress字段选项
+type ProgressOptions struct {
+	Color     string `json:"color,omitempty"`     // 进度条颜色
+	ShowValue bool   `json:"showValue,omitempty"` // 是否在进度条旁显示百分比数值
 }
 
 // NewFieldOptions 创建空字段选项
@@ -299,6 +363,16 @@ func (fo *FieldOptions) HasRating() bool {
 	return fo.Rating != nil
 }
 
+// HasProgress 是否有Progress选项
+func (fo *FieldOptions) HasProgress() bool {
+	return fo.Progress != nil
+}
+
+// HasAI 是否有AI选项
+func (fo *FieldOptions) HasAI() bool {
+	return fo.AI != nil
+}
+
 // WithCount 设置Count选项
 func (fo *FieldOptions) WithCount(linkFieldID string) *FieldOptions {
 	fo.Count = &CountOptions{
@@ -340,3 +414,13 @@ func (fo *FieldOptions) WithRating(max int, icon string) *FieldOptions {
 	}
 	return fo
 }
+
+// WithAI 设置AI选项
+func (fo *FieldOptions) WithAI(provider, model, prompt string) *FieldOptions {
+	fo.AI = &AIOptions{
+		Provider: provider,
+		Model:    model,
+		Prompt:   prompt,
+	}
+	return fo
+}
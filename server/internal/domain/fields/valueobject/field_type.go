@@ -2,6 +2,7 @@ package valueobject
 
 import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields"
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
 )
 
 // FieldType 字段类型值对象
@@ -23,6 +24,7 @@ const (
 	CategorySelect     FieldCategory = "select"     // select, multipleSelect
 	CategoryAI         FieldCategory = "ai"         // ai
 	CategorySystem     FieldCategory = "system"     // createdTime, createdBy, etc.
+	CategoryCustom     FieldCategory = "custom"     // ✨ 通过 fieldplugin 注册的自定义类型
 )
 
 // 字段类型常量（对齐原版FieldType枚举）
@@ -61,6 +63,9 @@ const (
 	TypeButton           = "button"         // 对齐原版
 	TypeSingleLineText   = "singleLineText" // 对齐原版
 	TypeLongText         = "longText"       // 对齐原版
+	TypeRichText         = "richText"       // 富文本（净化后的 HTML）
+	TypeLocation         = "location"       // 地理位置（经纬度与地址）
+	TypeProgress         = "progress"       // 进度（0-100 的完成百分比）
 )
 
 // NewFieldType 创建字段类型值对象
@@ -163,9 +168,18 @@ func isValidFieldType(value string) bool {
 		TypeButton:         true,
 		TypeSingleLineText: true,
 		TypeLongText:       true,
+		TypeRichText:       true,
+		TypeLocation:       true,
+		TypeProgress:       true,
 	}
 
-	return validTypes[value]
+	return validTypes[value] || IsCustomType(value)
+}
+
+// IsCustomType 是否为通过 fieldplugin 注册的自定义字段类型
+func IsCustomType(fieldType string) bool {
+	_, ok := fieldplugin.Lookup(fieldType)
+	return ok
 }
 
 // determineCategory 确定字段类别
@@ -174,7 +188,7 @@ func determineCategory(fieldType string) FieldCategory {
 	case TypeText, TypeNumber, TypeDate, TypeDateTime, TypeBoolean,
 		TypeEmail, TypeURL, TypePhone, TypeRating, TypeCheckbox,
 		TypeDuration, TypePercent, TypeCurrency, TypeAutoNumber,
-		TypeSingleLineText, TypeLongText:
+		TypeSingleLineText, TypeLongText, TypeRichText, TypeLocation, TypeProgress:
 		return CategoryBasic
 
 	case TypeLink:
@@ -196,6 +210,9 @@ func determineCategory(fieldType string) FieldCategory {
 		return CategorySystem
 
 	default:
+		if IsCustomType(fieldType) {
+			return CategoryCustom
+		}
 		return CategoryBasic
 	}
 }
@@ -240,6 +257,7 @@ var compatibilityMatrix = map[string]map[string]bool{
 		TypePhone:    true,
 		TypeDate:     true,
 		TypeDateTime: true,
+		TypeRichText: true,
 	},
 	TypeNumber: {
 		TypeText:     true,
@@ -247,6 +265,7 @@ var compatibilityMatrix = map[string]map[string]bool{
 		TypeCurrency: true,
 		TypeRating:   true,
 		TypeDuration: true,
+		TypeProgress: true,
 	},
 	TypeDate: {
 		TypeText:     true,
@@ -283,6 +302,14 @@ var compatibilityMatrix = map[string]map[string]bool{
 	TypeURL: {
 		TypeText: true,
 	},
+	TypeLongText: {
+		TypeText:     true,
+		TypeRichText: true,
+	},
+	TypeRichText: {
+		TypeText:     true,
+		TypeLongText: true,
+	},
 	TypePhone: {
 		TypeText: true,
 	},
@@ -291,8 +318,14 @@ var compatibilityMatrix = map[string]map[string]bool{
 		TypeText:   true,
 	},
 	TypePercent: {
-		TypeNumber: true,
-		TypeText:   true,
+		TypeNumber:   true,
+		TypeText:     true,
+		TypeProgress: true,
+	},
+	TypeProgress: {
+		TypeNumber:  true,
+		TypePercent: true,
+		TypeText:    true,
 	},
 	TypeCurrency: {
 		TypeNumber: true,
@@ -0,0 +1,110 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Location 地理位置单元格值（WGS84 经纬度与地址信息）
+// 物理列为 JSONB，半径查询通过 earthdistance 扩展的 ll_to_earth 表达式索引加速
+type Location struct {
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+	Address string  `json:"address,omitempty"` // 格式化地址
+	Name    string  `json:"name,omitempty"`    // 地点名称
+	PlaceID string  `json:"placeId,omitempty"` // 地理编码服务的地点ID
+}
+
+// ParseLocation 解析位置值
+// 支持 {"lat":..,"lng":..,"address":..} 对象或 "lat,lng" 字符串，经纬度可为数字或数字字符串
+func ParseLocation(value interface{}) (*Location, error) {
+	switch v := value.(type) {
+	case *Location:
+		return v, v.Validate()
+	case Location:
+		return &v, v.Validate()
+	case string:
+		latStr, lngStr, ok := strings.Cut(v, ",")
+		if !ok {
+			return nil, fmt.Errorf("位置必须是 \"纬度,经度\" 格式")
+		}
+		lat, err1 := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		lng, err2 := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("经纬度必须是数字")
+		}
+		loc := &Location{Lat: lat, Lng: lng}
+		return loc, loc.Validate()
+	case map[string]interface{}:
+		lat, ok1 := coordinate(v["lat"])
+		lng, ok2 := coordinate(v["lng"])
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("位置必须包含数字类型的 lat 和 lng")
+		}
+		loc := &Location{Lat: lat, Lng: lng}
+		loc.Address, _ = v["address"].(string)
+		loc.Name, _ = v["name"].(string)
+		loc.PlaceID, _ = v["placeId"].(string)
+		return loc, loc.Validate()
+	default:
+		return nil, fmt.Errorf("位置必须是对象或 \"纬度,经度\" 字符串")
+	}
+}
+
+// Validate 校验经纬度范围
+func (l *Location) Validate() error {
+	return ValidateCoordinates(l.Lat, l.Lng)
+}
+
+// ToMap 转换为单元格存储格式
+func (l *Location) ToMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"lat": l.Lat,
+		"lng": l.Lng,
+	}
+	if l.Address != "" {
+		m["address"] = l.Address
+	}
+	if l.Name != "" {
+		m["name"] = l.Name
+	}
+	if l.PlaceID != "" {
+		m["placeId"] = l.PlaceID
+	}
+	return m
+}
+
+// ValidateCoordinates 校验纬度在 [-90, 90]、经度在 [-180, 180] 范围内
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("纬度必须在 -90 到 90 之间")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("经度必须在 -180 到 180 之间")
+	}
+	return nil
+}
+
+func coordinate(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,34 @@
+package valueobject
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	loc, err := ParseLocation(map[string]interface{}{"lat": "31.2304", "lng": 121.4737, "address": "上海市"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"lat": 31.2304, "lng": 121.4737, "address": "上海市"}
+	if !reflect.DeepEqual(loc.ToMap(), want) {
+		t.Fatalf("ToMap() = %v, want %v", loc.ToMap(), want)
+	}
+
+	if loc, err := ParseLocation(" 40.7, -74.0 "); err != nil || loc.Lat != 40.7 || loc.Lng != -74.0 {
+		t.Fatalf("字符串格式解析失败: %+v %v", loc, err)
+	}
+
+	invalid := []interface{}{
+		map[string]interface{}{"lat": 91, "lng": 0},
+		map[string]interface{}{"lat": 0, "lng": 181},
+		map[string]interface{}{"lat": 0},
+		"40.7",
+		42,
+	}
+	for _, value := range invalid {
+		if _, err := ParseLocation(value); err == nil {
+			t.Errorf("ParseLocation(%v) 应返回错误", value)
+		}
+	}
+}
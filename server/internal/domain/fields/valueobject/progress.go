@@ -0,0 +1,53 @@
+package valueobject
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// 进度字段以 0-100 的完成百分比存储（NUMERIC）
+const (
+	MinProgress = 0
+	MaxProgress = 100
+)
+
+// ParseProgress 解析进度值，支持数字与 "75"、"75%" 形式的字符串
+func ParseProgress(value interface{}) (float64, error) {
+	var progress float64
+	switch v := value.(type) {
+	case float64:
+		progress = v
+	case float32:
+		progress = float64(v)
+	case int:
+		progress = float64(v)
+	case int32:
+		progress = float64(v)
+	case int64:
+		progress = float64(v)
+	case string:
+		s := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "%"))
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的进度格式: %s", v)
+		}
+		progress = f
+	default:
+		return 0, fmt.Errorf("进度必须是数字")
+	}
+
+	if math.IsNaN(progress) || progress < MinProgress || progress > MaxProgress {
+		return 0, fmt.Errorf("进度必须在 %d 到 %d 之间", MinProgress, MaxProgress)
+	}
+	return progress, nil
+}
+
+// ClampProgress 将数值限制在 [0, 100]，用于其他数字字段转换为进度字段时修复已有值
+func ClampProgress(value float64) float64 {
+	if math.IsNaN(value) || value < MinProgress {
+		return MinProgress
+	}
+	return math.Min(value, MaxProgress)
+}
@@ -0,0 +1,32 @@
+package valueobject
+
+import "testing"
+
+func TestParseProgress(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  float64
+	}{
+		{float64(75), 75},
+		{0, 0},
+		{int64(100), 100},
+		{"42.5", 42.5},
+		{" 75% ", 75},
+	}
+	for _, c := range cases {
+		got, err := ParseProgress(c.value)
+		if err != nil || got != c.want {
+			t.Errorf("ParseProgress(%v) = %v, %v, want %v", c.value, got, err, c.want)
+		}
+	}
+
+	for _, value := range []interface{}{-1, 100.5, "abc", "120%", true} {
+		if _, err := ParseProgress(value); err == nil {
+			t.Errorf("ParseProgress(%v) 应返回错误", value)
+		}
+	}
+
+	if ClampProgress(150) != 100 || ClampProgress(-3) != 0 || ClampProgress(55) != 55 {
+		t.Error("ClampProgress 限制范围不正确")
+	}
+}
@@ -0,0 +1,32 @@
+package valueobject
+
+import "math"
+
+// 评分字段以整数存储，0 或空值表示未评分
+const (
+	// DefaultRatingMax 未配置时的最大评分
+	DefaultRatingMax = 5
+	// MaxRatingMax 可配置的最大评分上限
+	MaxRatingMax = 10
+)
+
+// MaxValue 最大评分（未配置或超出范围时使用默认值）
+func (o *RatingOptions) MaxValue() int {
+	if o == nil || o.Max < 1 || o.Max > MaxRatingMax {
+		return DefaultRatingMax
+	}
+	return o.Max
+}
+
+// NormalizeRating 将任意数值修正为合法评分：四舍五入取整并限制在 [0, max]
+// 用于数字字段转换为评分字段时修复已有值
+func NormalizeRating(value float64, max int) int {
+	if math.IsNaN(value) || value <= 0 {
+		return 0
+	}
+	rating := int(math.Round(value))
+	if rating > max {
+		return max
+	}
+	return rating
+}
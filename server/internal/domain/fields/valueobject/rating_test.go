@@ -0,0 +1,41 @@
+package valueobject
+
+import "testing"
+
+func TestRatingOptionsMaxValue(t *testing.T) {
+	var nilOptions *RatingOptions
+	cases := []struct {
+		options *RatingOptions
+		want    int
+	}{
+		{nilOptions, DefaultRatingMax},
+		{&RatingOptions{Max: 0}, DefaultRatingMax},
+		{&RatingOptions{Max: 11}, DefaultRatingMax},
+		{&RatingOptions{Max: 10}, 10},
+		{&RatingOptions{Max: 3}, 3},
+	}
+	for _, c := range cases {
+		if got := c.options.MaxValue(); got != c.want {
+			t.Errorf("MaxValue(%+v) = %d, want %d", c.options, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeRating(t *testing.T) {
+	cases := []struct {
+		value float64
+		max   int
+		want  int
+	}{
+		{3, 5, 3},
+		{3.6, 5, 4},
+		{42, 5, 5},
+		{-2, 5, 0},
+		{0.4, 5, 0},
+	}
+	for _, c := range cases {
+		if got := NormalizeRating(c.value, c.max); got != c.want {
+			t.Errorf("NormalizeRating(%v, %d) = %d, want %d", c.value, c.max, got, c.want)
+		}
+	}
+}
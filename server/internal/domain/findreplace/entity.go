@@ -0,0 +1,122 @@
+package findreplace
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxPatternLength 查找内容的最大长度（字符）
+	MaxPatternLength = 1000
+	// MaxFields 单次查找替换可选择的字段数上限
+	MaxFields = 50
+)
+
+// Spec 查找替换条件 ✨
+// 在选定的文本字段中查找 Find 并替换为 Replace；ViewID 非空时只处理视图过滤范围内的记录。
+// Regex 为 true 时 Find 按正则表达式匹配，Replace 中可用 $1、${name} 引用分组；
+// 否则按字面匹配，Replace 原样写入
+type Spec struct {
+	TableID       string   `json:"table_id"`
+	ViewID        string   `json:"view_id,omitempty"`
+	FieldIDs      []string `json:"field_ids"`
+	Find          string   `json:"find"`
+	Replace       string   `json:"replace"`
+	Regex         bool     `json:"regex"`
+	CaseSensitive bool     `json:"case_sensitive"`
+	WholeWord     bool     `json:"whole_word"`
+}
+
+// Validate 校验查找替换条件
+func (s *Spec) Validate() error {
+	if s.TableID == "" {
+		return errors.New("缺少 table_id")
+	}
+	if len(s.FieldIDs) == 0 {
+		return errors.New("至少选择一个字段")
+	}
+	if len(s.FieldIDs) > MaxFields {
+		return fmt.Errorf("最多选择 %d 个字段", MaxFields)
+	}
+	if s.Find == "" {
+		return errors.New("查找内容不能为空")
+	}
+	if utf8.RuneCountInString(s.Find) > MaxPatternLength {
+		return fmt.Errorf("查找内容不能超过 %d 个字符", MaxPatternLength)
+	}
+	_, err := s.Compile()
+	return err
+}
+
+// Compile 编译为匹配器
+func (s *Spec) Compile() (*Matcher, error) {
+	pattern := s.Find
+	if !s.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if s.WholeWord {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+	if !s.CaseSensitive {
+		pattern = `(?i)` + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("正则表达式无效: %v", err)
+	}
+	return &Matcher{re: re, replace: s.Replace, literal: !s.Regex}, nil
+}
+
+// Matcher 编译后的查找替换规则
+type Matcher struct {
+	re      *regexp.Regexp
+	replace string
+	literal bool
+}
+
+// Apply 对文本执行替换，返回替换后的文本与匹配次数（不匹配时原样返回，次数为 0）
+func (m *Matcher) Apply(text string) (string, int) {
+	matches := m.re.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return text, 0
+	}
+	if m.literal {
+		return m.re.ReplaceAllLiteralString(text, m.replace), len(matches)
+	}
+	return m.re.ReplaceAllString(text, m.replace), len(matches)
+}
+
+// Change 一条记录被替换前后的字段值，用于撤销
+type Change struct {
+	OperationID string                 `json:"operation_id"`
+	TableID     string                 `json:"table_id"`
+	RecordID    string                 `json:"record_id"`
+	Before      map[string]interface{} `json:"before"` // 字段ID → 替换前的值
+	After       map[string]interface{} `json:"after"`  // 字段ID → 替换后的值
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// Reverted 撤销时应写回的值：只还原当前值仍等于替换结果的字段，
+// 之后又被修改过的字段保留当前值。没有可还原的字段时返回 nil
+func (c *Change) Reverted(current map[string]interface{}) map[string]interface{} {
+	var restore map[string]interface{}
+	for fieldID, after := range c.After {
+		if value, ok := current[fieldID]; !ok || !sameText(value, after) {
+			continue
+		}
+		if restore == nil {
+			restore = make(map[string]interface{}, len(c.After))
+		}
+		restore[fieldID] = c.Before[fieldID]
+	}
+	return restore
+}
+
+func sameText(a, b interface{}) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	return aok && bok && as == bs
+}
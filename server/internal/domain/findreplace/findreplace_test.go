@@ -0,0 +1,62 @@
+package findreplace
+
+import "testing"
+
+func TestMatcherApply(t *testing.T) {
+	cases := []struct {
+		name  string
+		spec  Spec
+		input string
+		want  string
+		count int
+	}{
+		{"字面匹配不区分大小写", Spec{Find: "acme", Replace: "Globex"}, "ACME Inc / acme", "Globex Inc / Globex", 2},
+		{"字面匹配中的正则字符", Spec{Find: "a.b", Replace: "x"}, "a.b aXb", "x aXb", 1},
+		{"字面替换不展开分组", Spec{Find: "1", Replace: "$1"}, "v1", "v$1", 1},
+		{"区分大小写", Spec{Find: "Acme", Replace: "Globex", CaseSensitive: true}, "Acme acme", "Globex acme", 1},
+		{"全词匹配", Spec{Find: "cat", Replace: "dog", WholeWord: true}, "cat catalog", "dog catalog", 1},
+		{"正则分组", Spec{Find: `(\d{4})-(\d{2})`, Replace: "$2/$1", Regex: true}, "2026-10", "10/2026", 1},
+		{"不匹配", Spec{Find: "zzz", Replace: "y"}, "abc", "abc", 0},
+	}
+	for _, tc := range cases {
+		matcher, err := tc.spec.Compile()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got, count := matcher.Apply(tc.input)
+		if got != tc.want || count != tc.count {
+			t.Errorf("%s: Apply(%q) = %q, %d", tc.name, tc.input, got, count)
+		}
+	}
+}
+
+func TestSpecValidate(t *testing.T) {
+	valid := Spec{TableID: "tbl1", FieldIDs: []string{"fld1"}, Find: "a"}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	for name, spec := range map[string]Spec{
+		"缺少字段":   {TableID: "tbl1", Find: "a"},
+		"查找内容为空": {TableID: "tbl1", FieldIDs: []string{"fld1"}},
+		"正则无效":   {TableID: "tbl1", FieldIDs: []string{"fld1"}, Find: "(", Regex: true},
+	} {
+		if err := spec.Validate(); err == nil {
+			t.Errorf("%s: 应校验失败", name)
+		}
+	}
+}
+
+func TestChangeReverted(t *testing.T) {
+	change := &Change{
+		Before: map[string]interface{}{"fld1": "Acme 公司", "fld2": "acme"},
+		After:  map[string]interface{}{"fld1": "Globex 公司", "fld2": "Globex"},
+	}
+	// fld2 替换后又被编辑过，撤销时保留当前值
+	restore := change.Reverted(map[string]interface{}{"fld1": "Globex 公司", "fld2": "手动修改"})
+	if len(restore) != 1 || restore["fld1"] != "Acme 公司" {
+		t.Errorf("还原 = %v", restore)
+	}
+	if restore := change.Reverted(map[string]interface{}{}); restore != nil {
+		t.Errorf("全部被修改时不应还原: %v", restore)
+	}
+}
@@ -0,0 +1,17 @@
+package findreplace
+
+import "context"
+
+// Repository 查找替换变更记录仓储接口
+type Repository interface {
+	// SaveChanges 批量写入变更记录
+	SaveChanges(ctx context.Context, changes []*Change) error
+	// ListChanges 按记录ID顺序分页列出操作的变更记录（afterRecordID 之后的 limit 条）
+	ListChanges(ctx context.Context, operationID, afterRecordID string, limit int) ([]*Change, error)
+	// CountChanges 操作的变更记录数
+	CountChanges(ctx context.Context, operationID string) (int64, error)
+	// ClaimUndo 登记撤销操作，每个查找替换操作只能撤销一次；已被其他撤销操作登记时返回 false
+	ClaimUndo(ctx context.Context, operationID, undoOperationID string) (bool, error)
+	// FindUndo 查找替换操作对应的撤销操作ID（未撤销时返回空字符串）
+	FindUndo(ctx context.Context, operationID string) (string, error)
+}
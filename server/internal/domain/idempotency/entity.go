@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// HeaderKey 客户端提供幂等键的请求头
+const HeaderKey = "Idempotency-Key"
+
+// HeaderReplayed 重放响应时附加的响应头
+const HeaderReplayed = "Idempotent-Replayed"
+
+// MaxKeyLength 幂等键最大长度
+const MaxKeyLength = 255
+
+// ErrLockLost 处理中的幂等键已不属于当前请求（占用过期后被其他请求接管，或已被释放）
+var ErrLockLost = errors.New("idempotency key is no longer held by this request")
+
+// Status 幂等记录状态
+type Status string
+
+const (
+	StatusProcessing Status = "processing" // 首次请求处理中
+	StatusCompleted  Status = "completed"  // 已保存响应，后续重试直接重放
+)
+
+// Record 幂等记录
+// 同一 Scope（用户）下的同一个 Key 只会执行一次，响应在 ExpiresAt 之前可重放
+type Record struct {
+	Scope       string    `json:"scope"`
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"` // 请求指纹，用于识别同一个键被用于不同请求
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	Status      Status    `json:"status"`
+	Token       string    `json:"token"` // 占用者标识：只有占用幂等键的请求可以续期、保存响应或释放
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	BodyOmitted bool      `json:"body_omitted,omitempty"` // 响应体超过可缓存大小未保存，重试时无法重放
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Expired 记录是否已过期（过期后同一个键视为新请求）
+func (r *Record) Expired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+// Completed 是否已保存可重放的响应
+func (r *Record) Completed() bool {
+	return r.Status == StatusCompleted
+}
+
+// ValidKey 幂等键须为 1-255 个可见 ASCII 字符（与 HTTP 头字段值保持一致，避免编码歧义）
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// Fingerprint 计算请求指纹：方法、路径、查询串与请求体的 SHA-256
+func Fingerprint(method, path, query string, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{method, path, query} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,21 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Store 幂等记录存储接口（Redis 或数据库实现）
+type Store interface {
+	// Reserve 以处理中状态占用幂等键；键已被占用且未过期时返回已有记录，占用成功时返回 nil
+	Reserve(ctx context.Context, record *Record) (*Record, error)
+	// Extend 将处理中记录的过期时间延长到 record.ExpiresAt（长时间处理的请求定期续期）
+	// 记录已不属于 record.Token 时返回 ErrLockLost
+	Extend(ctx context.Context, record *Record) error
+	// Complete 保存响应并将记录标记为已完成；记录已不属于 record.Token 时返回 ErrLockLost
+	Complete(ctx context.Context, record *Record) error
+	// Release 释放处理中的幂等键（请求失败时调用，允许客户端重试）；只释放 record.Token 占用的记录
+	Release(ctx context.Context, record *Record) error
+	// DeleteExpired 删除过期记录，返回删除数量（依赖 TTL 自动过期的实现返回 0）
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
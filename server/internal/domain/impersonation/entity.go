@@ -0,0 +1,158 @@
+package impersonation
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Prefix 代入令牌前缀，认证时据此与登录令牌、服务令牌区分
+const Prefix = "lim_"
+
+// secretBytes 令牌随机部分的字节数
+const secretBytes = 32
+
+const (
+	// MinReasonLength 代入原因最小长度（需要写明工单或问题描述）
+	MinReasonLength = 5
+	// MaxReasonLength 代入原因最大长度
+	MaxReasonLength = 500
+)
+
+// Session 代入会话 ✨
+// 支持人员在限定时长内以目标用户身份访问 API；
+// 只保存令牌的 SHA-256 摘要，明文仅在开始时返回一次，结束或到期后立即失效
+type Session struct {
+	ID            string     `json:"id"`
+	SupportUserID string     `json:"support_user_id"`
+	SupportEmail  string     `json:"support_email"`
+	TargetUserID  string     `json:"target_user_id"`
+	TargetEmail   string     `json:"target_email"`
+	Reason        string     `json:"reason"`
+	TokenHash     string     `json:"-"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	EndedBy       string     `json:"ended_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// NewSession 开始代入会话，返回会话与令牌明文（明文不会被保存）
+func NewSession(supportUserID, supportEmail, targetUserID, targetEmail, reason string, ttl time.Duration, now time.Time) (*Session, string, error) {
+	reason = strings.TrimSpace(reason)
+	if n := len([]rune(reason)); n < MinReasonLength || n > MaxReasonLength {
+		return nil, "", fmt.Errorf("代入原因需要 %d 到 %d 个字符", MinReasonLength, MaxReasonLength)
+	}
+	if supportUserID == targetUserID {
+		return nil, "", fmt.Errorf("不能代入自己")
+	}
+	if ttl <= 0 {
+		return nil, "", fmt.Errorf("代入时长必须大于 0")
+	}
+
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("生成令牌失败: %w", err)
+	}
+	plaintext := Prefix + hex.EncodeToString(buf)
+
+	return &Session{
+		ID:            utils.GenerateIDWithPrefix("imp"),
+		SupportUserID: supportUserID,
+		SupportEmail:  supportEmail,
+		TargetUserID:  targetUserID,
+		TargetEmail:   targetEmail,
+		Reason:        reason,
+		TokenHash:     Hash(plaintext),
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+	}, plaintext, nil
+}
+
+// IsImpersonationToken 判断是否为代入令牌格式
+func IsImpersonationToken(plaintext string) bool {
+	return strings.HasPrefix(plaintext, Prefix)
+}
+
+// Hash 令牌摘要（用于存储与查找）
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active 会话在给定时刻是否可用（未结束且未到期）
+func (s *Session) Active(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// End 结束会话
+func (s *Session) End(userID string, now time.Time) error {
+	if s.EndedAt != nil {
+		return fmt.Errorf("代入会话已结束")
+	}
+	s.EndedAt = &now
+	s.EndedBy = userID
+	return nil
+}
+
+// ==================== 代入期间禁止的接口 ====================
+
+// blockedRoute 代入期间禁止访问的路由及其子路由（method 为空表示所有方法）
+type blockedRoute struct {
+	method string
+	path   string
+}
+
+// blockedRoutes 凭据、会话、账号与计费相关接口，以及管理和代入接口本身
+var blockedRoutes = []blockedRoute{
+	{path: "/api/v1/auth"},
+	{path: "/api/v1/admin"},
+	{path: "/api/v1/impersonation"},
+	{path: "/api/v1/billing"},
+	{path: "/api/v1/users/:id/password"},
+	{method: http.MethodDelete, path: "/api/v1/users/:id"},
+	{path: "/api/v1/spaces/:spaceId/gdpr"},
+}
+
+// Blocked 代入期间是否禁止访问该路由（route 为路由模板，未匹配路由时为请求路径）
+func Blocked(method, route string) bool {
+	for _, b := range blockedRoutes {
+		if b.method != "" && b.method != method {
+			continue
+		}
+		if route == b.path || strings.HasPrefix(route, b.path+"/") {
+			return true
+		}
+	}
+	for _, segment := range strings.Split(route, "/") {
+		// 令牌与计费相关的路由一律禁止（如嵌入令牌、新增的 *-tokens 接口）
+		if strings.HasSuffix(segment, "-tokens") || segment == "tokens" || segment == "billing" {
+			return true
+		}
+	}
+	return false
+}
+
+// ==================== 审计 ====================
+
+// Action 代入期间的一次请求（写入审计日志并标记为代入操作）
+type Action struct {
+	SessionID     string
+	SupportUserID string
+	TargetUserID  string
+	TargetEmail   string
+	Method        string
+	Path          string
+	Route         string
+	Status        int
+	Blocked       bool
+	IPAddress     string
+	UserAgent     string
+	Duration      time.Duration
+	OccurredAt    time.Time
+}
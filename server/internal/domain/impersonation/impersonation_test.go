@@ -0,0 +1,87 @@
+package impersonation
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSession(t *testing.T) {
+	now := time.Now()
+	session, plaintext, err := NewSession("usr_support", "support@example.com", "usr_1", "user@example.com", " ticket #42 ", 30*time.Minute, now)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if !IsImpersonationToken(plaintext) {
+		t.Fatalf("plaintext %q should carry the impersonation prefix", plaintext)
+	}
+	if session.TokenHash != Hash(plaintext) || strings.Contains(session.TokenHash, plaintext) {
+		t.Errorf("token hash should be the digest of the plaintext")
+	}
+	if session.Reason != "ticket #42" {
+		t.Errorf("reason should be trimmed, got %q", session.Reason)
+	}
+	if !session.ExpiresAt.Equal(now.Add(30 * time.Minute)) {
+		t.Errorf("unexpected expiry %v", session.ExpiresAt)
+	}
+
+	if _, _, err := NewSession("usr_support", "", "usr_1", "", "hi", time.Hour, now); err == nil {
+		t.Errorf("short reason should be rejected")
+	}
+	if _, _, err := NewSession("usr_1", "", "usr_1", "", "ticket #42", time.Hour, now); err == nil {
+		t.Errorf("impersonating oneself should be rejected")
+	}
+	if _, _, err := NewSession("usr_support", "", "usr_1", "", "ticket #42", 0, now); err == nil {
+		t.Errorf("zero duration should be rejected")
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	now := time.Now()
+	session, _, err := NewSession("usr_support", "", "usr_1", "", "ticket #42", time.Hour, now)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if !session.Active(now) {
+		t.Errorf("new session should be active")
+	}
+	if session.Active(now.Add(2 * time.Hour)) {
+		t.Errorf("expired session should not be active")
+	}
+	if err := session.End("usr_support", now); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if session.Active(now) {
+		t.Errorf("ended session should not be active")
+	}
+	if err := session.End("usr_support", now); err == nil {
+		t.Errorf("ending twice should fail")
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	cases := []struct {
+		method, route string
+		blocked       bool
+	}{
+		{http.MethodPost, "/api/v1/auth/service-tokens", true},
+		{http.MethodGet, "/api/v1/auth/sessions", true},
+		{http.MethodPatch, "/api/v1/users/:id/password", true},
+		{http.MethodDelete, "/api/v1/users/:id", true},
+		{http.MethodPatch, "/api/v1/users/:id", false},
+		{http.MethodGet, "/api/v1/admin/users", true},
+		{http.MethodPost, "/api/v1/impersonation/sessions", true},
+		{http.MethodPost, "/api/v1/views/:viewId/embed-tokens", true},
+		{http.MethodGet, "/api/v1/spaces/:spaceId/billing/invoices", true},
+		{http.MethodGet, "/api/v1/spaces/:spaceId/gdpr/export", true},
+		{http.MethodGet, "/api/v1/tables/:tableId/records", false},
+		{http.MethodPost, "/api/v1/attachments/upload/:token", false},
+		{http.MethodGet, "/api/v1/authors", false},
+	}
+	for _, tc := range cases {
+		if got := Blocked(tc.method, tc.route); got != tc.blocked {
+			t.Errorf("Blocked(%s %s) = %v, want %v", tc.method, tc.route, got, tc.blocked)
+		}
+	}
+}
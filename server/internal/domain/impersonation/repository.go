@@ -0,0 +1,41 @@
+package impersonation
+
+import (
+	"context"
+	"time"
+)
+
+// 代入会话的审计事件
+const (
+	EventStarted = "impersonation_started"
+	EventEnded   = "impersonation_ended"
+)
+
+// ListFilter 代入会话列表过滤条件
+type ListFilter struct {
+	SupportUserID string
+	TargetUserID  string
+	ActiveAt      *time.Time // 只列出该时刻仍有效的会话
+	Limit         int
+}
+
+// Repository 代入会话仓储接口
+type Repository interface {
+	// Create 保存新会话
+	Create(ctx context.Context, session *Session) error
+	// FindByHash 按令牌摘要查找会话（不存在时返回 nil）
+	FindByHash(ctx context.Context, tokenHash string) (*Session, error)
+	// FindByID 按ID查找会话（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Session, error)
+	// List 列出会话（按开始时间倒序）
+	List(ctx context.Context, filter ListFilter) ([]*Session, error)
+	// End 保存会话的结束时间与结束人
+	End(ctx context.Context, session *Session) error
+
+	// RecordEvent 写入会话开始、结束的审计日志
+	RecordEvent(ctx context.Context, session *Session, event, actorID string) error
+	// RecordAction 写入代入期间请求的审计日志（标记为代入操作）
+	RecordAction(ctx context.Context, action *Action) error
+	// NotifyTarget 通知被代入的用户
+	NotifyTarget(ctx context.Context, session *Session) error
+}
@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Event 集成平台可订阅的触发事件
+type Event string
+
+const (
+	EventRecordCreated Event = "record.created"
+	EventRecordUpdated Event = "record.updated"
+	EventRecordDeleted Event = "record.deleted"
+
+	// EventRecordTransitioned 状态流（审批流）中记录的状态发生流转
+	EventRecordTransitioned Event = "record.transitioned"
+)
+
+// Action 事件对应的变更流动作
+func (e Event) Action() (changefeed.Action, bool) {
+	switch e {
+	case EventRecordCreated:
+		return changefeed.ActionCreate, true
+	case EventRecordUpdated, EventRecordTransitioned:
+		return changefeed.ActionUpdate, true
+	case EventRecordDeleted:
+		return changefeed.ActionDelete, true
+	default:
+		return "", false
+	}
+}
+
+// EntityType 事件对应的变更流对象类型
+func (e Event) EntityType() changefeed.EntityType {
+	if e == EventRecordTransitioned {
+		return changefeed.EntityTransition
+	}
+	return changefeed.EntityRecord
+}
+
+// Hook REST Hook 订阅 ✨
+// Zapier / Make 等平台启用触发器时订阅、停用时退订；
+// 订阅从当前变更流游标开始，后台按游标顺序把匹配的记录推送到 TargetURL；
+// 设置了 Template 时，每条记录先按输出模板改写（见 PayloadTemplate）再推送。
+// 推送失败时游标不推进，下一轮重试同一批记录；连续失败达到 MaxFailures 次后暂停（死信），
+// 暂停期间不再推送，恢复后从失败的位置继续
+type Hook struct {
+	ID              string     `json:"id"`
+	BaseID          string     `json:"base_id"`
+	TableID         string     `json:"table_id"`
+	Event           Event      `json:"event"`
+	TargetURL       string     `json:"target_url"`
+	Template        string     `json:"template,omitempty"`
+	Cursor          int64      `json:"cursor"`
+	LastError       string     `json:"last_error,omitempty"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+	Failures        int        `json:"failures"`     // 连续失败次数，推送成功后清零
+	MaxFailures     int        `json:"max_failures"` // 连续失败多少次后暂停，0 表示不暂停
+	NotifyOwner     bool       `json:"notify_owner"` // 暂停时通知订阅者
+	PausedAt        *time.Time `json:"paused_at,omitempty"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// Paused 是否已暂停
+func (h *Hook) Paused() bool {
+	return h.PausedAt != nil
+}
+
+// RecordFailure 记录一次推送失败，达到暂停阈值时暂停并返回 true
+func (h *Hook) RecordFailure(message string, now time.Time) bool {
+	h.LastError = message
+	h.Failures++
+	if h.MaxFailures > 0 && h.Failures >= h.MaxFailures && h.PausedAt == nil {
+		h.PausedAt = &now
+		return true
+	}
+	return false
+}
+
+// RecordSuccess 记录推送成功
+func (h *Hook) RecordSuccess(cursor int64) {
+	h.Cursor = cursor
+	h.LastError = ""
+	h.Failures = 0
+}
+
+// Pause 立即暂停订阅（不受暂停阈值限制，如订阅者失去读取权限），新暂停时返回 true
+func (h *Hook) Pause(message string, now time.Time) bool {
+	h.LastError = message
+	if h.PausedAt != nil {
+		return false
+	}
+	h.PausedAt = &now
+	return true
+}
+
+// Resume 恢复已暂停的订阅，从失败的位置继续推送
+func (h *Hook) Resume() {
+	h.PausedAt = nil
+	h.Failures = 0
+}
+
+// NewHook 创建订阅
+func NewHook(baseID, tableID string, event Event, targetURL, template string, cursor int64, maxFailures int, createdBy string) *Hook {
+	return &Hook{
+		ID:          utils.GenerateIDWithPrefix("hok"),
+		BaseID:      baseID,
+		TableID:     tableID,
+		Event:       event,
+		TargetURL:   targetURL,
+		Template:    template,
+		Cursor:      cursor,
+		MaxFailures: maxFailures,
+		NotifyOwner: true,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// DeliveryStatus 失败推送（死信）的状态
+type DeliveryStatus string
+
+const (
+	// DeliveryFailed 推送失败，等待自动重试或手动重试
+	DeliveryFailed DeliveryStatus = "failed"
+	// DeliveryRetried 已手动重试（再次失败时会记录新的失败推送）
+	DeliveryRetried DeliveryStatus = "retried"
+	// DeliveryResolved 之后的重试推送成功
+	DeliveryResolved DeliveryStatus = "resolved"
+)
+
+// Delivery 失败的推送 ✨
+// 同一批记录（相同的起始游标）反复失败时只保留一条，累加尝试次数并保存最近一次的错误
+type Delivery struct {
+	ID            string         `json:"id"`
+	HookID        string         `json:"hook_id"`
+	TableID       string         `json:"table_id"`
+	Event         Event          `json:"event"`
+	CursorFrom    int64          `json:"cursor_from"` // 该批记录之前的游标（不含）
+	CursorTo      int64          `json:"cursor_to"`   // 该批最后一条变更的游标
+	ItemCount     int            `json:"item_count"`
+	Status        DeliveryStatus `json:"status"`
+	StatusCode    int            `json:"status_code,omitempty"` // 0 表示未收到响应（网络错误、模板错误等）
+	Error         string         `json:"error"`
+	Attempts      int            `json:"attempts"`
+	FirstFailedAt time.Time      `json:"first_failed_at"`
+	LastFailedAt  time.Time      `json:"last_failed_at"`
+	RetriedAt     *time.Time     `json:"retried_at,omitempty"`
+}
+
+// NewDelivery 创建失败推送记录
+// 不保存目标的响应体：推送地址由订阅者指定，响应内容可能来自不该被读取的服务
+func NewDelivery(hook *Hook, cursorTo int64, itemCount, statusCode int, message string, now time.Time) *Delivery {
+	return &Delivery{
+		ID:            utils.GenerateIDWithPrefix("dlv"),
+		HookID:        hook.ID,
+		TableID:       hook.TableID,
+		Event:         hook.Event,
+		CursorFrom:    hook.Cursor,
+		CursorTo:      cursorTo,
+		ItemCount:     itemCount,
+		Status:        DeliveryFailed,
+		StatusCode:    statusCode,
+		Error:         message,
+		Attempts:      1,
+		FirstFailedAt: now,
+		LastFailedAt:  now,
+	}
+}
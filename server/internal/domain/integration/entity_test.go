@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+)
+
+func TestEventFilter(t *testing.T) {
+	if action, ok := EventRecordTransitioned.Action(); !ok || action != changefeed.ActionUpdate ||
+		EventRecordTransitioned.EntityType() != changefeed.EntityTransition {
+		t.Error("record.transitioned 应读取变更流中的状态流转")
+	}
+	if EventRecordUpdated.EntityType() != changefeed.EntityRecord {
+		t.Error("record.updated 应读取记录变更")
+	}
+	if _, ok := Event("record.unknown").Action(); ok {
+		t.Error("不支持的事件")
+	}
+}
+
+func TestHookDeadLetter(t *testing.T) {
+	hook := NewHook("bse1", "tbl1", EventRecordCreated, "https://example.com/hook", "", 10, 3, "usr1")
+	now := time.Now()
+
+	if hook.RecordFailure("hook endpoint returned 500", now) || hook.RecordFailure("hook endpoint returned 500", now) {
+		t.Fatal("未达到阈值时不应暂停")
+	}
+	if !hook.RecordFailure("hook endpoint returned 502", now) || !hook.Paused() {
+		t.Fatal("连续失败 3 次后应暂停")
+	}
+	if hook.RecordFailure("again", now) {
+		t.Error("已暂停的订阅不应重复报告暂停")
+	}
+
+	hook.Resume()
+	if hook.Paused() || hook.Failures != 0 {
+		t.Errorf("恢复后 = %+v", hook)
+	}
+	hook.RecordFailure("timeout", now)
+	hook.RecordSuccess(20)
+	if hook.Failures != 0 || hook.LastError != "" || hook.Cursor != 20 {
+		t.Errorf("推送成功后 = %+v", hook)
+	}
+
+	hook.MaxFailures = 0
+	for i := 0; i < 100; i++ {
+		if hook.RecordFailure("down", now) {
+			t.Fatal("阈值为 0 时不应暂停")
+		}
+	}
+}
+
+func TestNewDelivery(t *testing.T) {
+	hook := NewHook("bse1", "tbl1", EventRecordUpdated, "https://example.com/hook", "", 10, 3, "usr1")
+	delivery := NewDelivery(hook, 15, 4, 500, "hook endpoint returned 500", time.Now())
+	if delivery.CursorFrom != 10 || delivery.CursorTo != 15 || delivery.Status != DeliveryFailed || delivery.Attempts != 1 {
+		t.Errorf("失败推送 = %+v", delivery)
+	}
+	if !strings.HasPrefix(delivery.ID, "dlv") {
+		t.Errorf("失败推送ID = %s", delivery.ID)
+	}
+}
+
+func TestHookPause(t *testing.T) {
+	hook := NewHook("bse1", "tbl1", EventRecordUpdated, "https://example.com/hook", "", 10, 0, "usr1")
+	now := time.Now()
+	if !hook.Pause("no access", now) || !hook.Paused() || hook.LastError != "no access" {
+		t.Fatalf("阈值为 0 时也应立即暂停，得到 %+v", hook)
+	}
+	if hook.Pause("no access", now) {
+		t.Fatal("已暂停的订阅不应重复通知")
+	}
+}
@@ -0,0 +1,51 @@
+package integration
+
+import (
+	"context"
+	"time"
+)
+
+// Repository REST Hook 订阅仓储接口
+type Repository interface {
+	// Create 保存新订阅
+	Create(ctx context.Context, hook *Hook) error
+	// FindByID 获取订阅（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Hook, error)
+	// ListByCreator 列出用户创建的订阅
+	ListByCreator(ctx context.Context, userID string) ([]*Hook, error)
+	// UpdateTemplate 更新订阅的输出模板（空字符串表示按原格式推送）
+	UpdateTemplate(ctx context.Context, id, template string) error
+	// UpdateDeadLetter 更新暂停阈值与通知设置
+	UpdateDeadLetter(ctx context.Context, hook *Hook) error
+	// Resume 恢复已暂停的订阅并释放租约（下一轮立即推送）
+	Resume(ctx context.Context, id string) error
+	// Delete 删除订阅及其失败推送记录
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取未暂停的订阅并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*Hook, error)
+	// SaveProgress 保存推送进度、失败次数与暂停状态并释放租约
+	SaveProgress(ctx context.Context, hook *Hook) error
+}
+
+// DeliveryFilter 失败推送查询条件
+type DeliveryFilter struct {
+	HookIDs []string        // 必填，只查询这些订阅的记录
+	Status  *DeliveryStatus // 为空时查询全部状态
+	Limit   int
+}
+
+// DeliveryRepository 失败推送（死信）仓储接口
+type DeliveryRepository interface {
+	// RecordFailure 记录失败推送：同一订阅同一起始游标已有失败记录时累加尝试次数并更新错误
+	RecordFailure(ctx context.Context, delivery *Delivery) error
+	// Resolve 订阅推送成功后，把游标之前的失败记录标记为已解决
+	Resolve(ctx context.Context, hookID string, cursor int64) error
+	// List 按最近失败时间倒序列出
+	List(ctx context.Context, filter DeliveryFilter) ([]*Delivery, error)
+	// FindByIDs 批量获取
+	FindByIDs(ctx context.Context, ids []string) ([]*Delivery, error)
+	// MarkRetried 标记为已手动重试
+	MarkRetried(ctx context.Context, ids []string, at time.Time) error
+	// CountFailed 统计订阅的失败记录数（按订阅ID）
+	CountFailed(ctx context.Context, hookIDs []string) (map[string]int64, error)
+}
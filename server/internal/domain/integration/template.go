@@ -0,0 +1,309 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxTemplateLength 输出模板的最大长度（字符数）
+	MaxTemplateLength = 16 * 1024
+	// MaxRenderedBytes 单个事件渲染结果的最大字节数
+	MaxRenderedBytes = 256 * 1024
+
+	// maxFormatWidth printf 宽度与精度上限
+	maxFormatWidth = 100
+	// maxRangeDepth range 最多嵌套层数
+	maxRangeDepth = 2
+	// maxRangeIterations 单个事件渲染时所有 range 的总迭代次数上限
+	maxRangeIterations = 100000
+	// renderTimeout 单个事件的渲染时限（在每次 range 迭代时检查）
+	renderTimeout = time.Second
+
+	// iterationFunc 注入到每个 range 循环体开头的计数函数
+	iterationFunc = "_iteration"
+)
+
+var (
+	// errRenderTooLarge 渲染结果超出上限
+	errRenderTooLarge = fmt.Errorf("渲染结果超过 %d 字节", MaxRenderedBytes)
+	// errRenderTooLong 迭代次数或执行时间超出上限
+	errRenderTooLong = fmt.Errorf("渲染超过 %d 次迭代或 %s", maxRangeIterations, renderTimeout)
+)
+
+// PayloadTemplate 推送内容的输出模板 ✨
+// 使用 Go 模板语法把每条事件改写为下游系统需要的 JSON，渲染结果必须是合法的 JSON；
+// 模板数据为推送的事件条目（.id、.recordId、.tableId、.event、.version、.occurredAt、.fields），
+// 字段按名称取值，如 {{json (index .fields "客户名称")}}。
+//
+// 为保证推送时安全地执行：
+//   - 不支持 define/template/block 子模板（避免递归），range 只能遍历事件数据，不能遍历数字
+//     （事件数据中的数字是浮点数，不可遍历）；
+//   - range 最多嵌套 maxRangeDepth 层，单个事件的总迭代次数与执行时间受限（不输出内容的循环同样计入）；
+//   - printf 的宽度与精度受限，单个事件的渲染结果不超过 MaxRenderedBytes
+type PayloadTemplate struct {
+	tmpl *template.Template
+}
+
+// templateFuncs 模板可用的函数（内置函数之外）
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"default": func(fallback, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"join": func(sep string, values []interface{}) string {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = fmt.Sprint(v)
+		}
+		return strings.Join(parts, sep)
+	},
+	"printf": safeSprintf,
+	// 占位，渲染时替换为本次渲染的计数函数
+	iterationFunc: func() string { return "" },
+}
+
+// iterationAction 注入到 range 循环体的 {{_iteration}} 节点
+var iterationAction = template.Must(template.New("iteration").Funcs(templateFuncs).Parse("{{" + iterationFunc + "}}")).Tree.Root.Nodes[0]
+
+// ParseTemplate 解析并检查输出模板
+func ParseTemplate(src string) (*PayloadTemplate, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errors.New("输出模板不能为空")
+	}
+	if utf8.RuneCountInString(src) > MaxTemplateLength {
+		return nil, fmt.Errorf("输出模板不能超过 %d 个字符", MaxTemplateLength)
+	}
+	tmpl, err := template.New("payload").Option("missingkey=zero").Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("输出模板语法错误: %w", err)
+	}
+	if len(tmpl.Templates()) > 1 {
+		return nil, errors.New("输出模板不支持 define/block 子模板")
+	}
+	if err := checkTemplate(tmpl.Tree.Root); err != nil {
+		return nil, err
+	}
+	return &PayloadTemplate{tmpl: tmpl}, nil
+}
+
+// templateChecker 检查模板语法，拒绝可能无限执行的写法：
+// 子模板调用（可递归）、range 遍历数字（Go 模板可以遍历整数，如 range 1000000000）与 range 嵌套过深。
+// 数字可能来自字面量、len、and/or/default 的参数，或保存了这些值的变量，这些都不能作为 range 的对象。
+// 检查通过后在每个 range 循环体开头插入计数调用，渲染时据此限制总迭代次数与执行时间
+type templateChecker struct {
+	numeric    map[string]bool // 可能保存数字的变量
+	rangeDepth int             // 当前所在的 range 嵌套层数
+}
+
+// numericFuncs 可能返回数字的函数
+var numericFuncs = map[string]bool{"len": true, "and": true, "or": true, "default": true}
+
+func checkTemplate(root *parse.ListNode) error {
+	c := &templateChecker{numeric: map[string]bool{}}
+	return c.check(root)
+}
+
+func (c *templateChecker) check(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := c.check(child); err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		return errors.New("输出模板不支持 template 调用")
+	case *parse.ActionNode:
+		c.declare(n.Pipe)
+	case *parse.RangeNode:
+		if c.mayBeNumber(n.Pipe) {
+			return errors.New("range 只能遍历事件数据，不能遍历数字")
+		}
+		if c.rangeDepth >= maxRangeDepth {
+			return fmt.Errorf("range 最多嵌套 %d 层", maxRangeDepth)
+		}
+		c.rangeDepth++
+		err := c.check(n.List)
+		c.rangeDepth--
+		if err != nil {
+			return err
+		}
+		if n.List != nil {
+			n.List.Nodes = append([]parse.Node{iterationAction.Copy()}, n.List.Nodes...)
+		}
+		return c.check(n.ElseList)
+	case *parse.IfNode:
+		c.declare(n.Pipe)
+		return c.branch(&n.BranchNode)
+	case *parse.WithNode:
+		c.declare(n.Pipe)
+		return c.branch(&n.BranchNode)
+	}
+	return nil
+}
+
+func (c *templateChecker) branch(n *parse.BranchNode) error {
+	if err := c.check(n.List); err != nil {
+		return err
+	}
+	return c.check(n.ElseList)
+}
+
+// declare 记录由可能为数字的表达式赋值的变量
+func (c *templateChecker) declare(pipe *parse.PipeNode) {
+	if pipe == nil || len(pipe.Decl) == 0 || !c.mayBeNumber(pipe) {
+		return
+	}
+	for _, v := range pipe.Decl {
+		c.numeric[v.Ident[0]] = true
+	}
+}
+
+// mayBeNumber 表达式的结果是否可能是数字
+func (c *templateChecker) mayBeNumber(pipe *parse.PipeNode) bool {
+	if pipe == nil {
+		return false
+	}
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch a := arg.(type) {
+			case *parse.NumberNode:
+				return true
+			case *parse.IdentifierNode:
+				if numericFuncs[a.Ident] {
+					return true
+				}
+			case *parse.VariableNode:
+				if c.numeric[a.Ident[0]] {
+					return true
+				}
+			case *parse.PipeNode:
+				if c.mayBeNumber(a) {
+					return true
+				}
+			case *parse.ChainNode:
+				if p, ok := a.Node.(*parse.PipeNode); ok && c.mayBeNumber(p) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Render 渲染一条事件，返回 JSON
+func (t *PayloadTemplate) Render(data interface{}) (json.RawMessage, error) {
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Funcs(template.FuncMap{iterationFunc: newIterationBudget().next})
+
+	out := &limitedBuffer{limit: MaxRenderedBytes}
+	if err := tmpl.Execute(out, data); err != nil {
+		if errors.Is(err, errRenderTooLarge) {
+			return nil, errRenderTooLarge
+		}
+		if errors.Is(err, errRenderTooLong) {
+			return nil, errRenderTooLong
+		}
+		return nil, fmt.Errorf("输出模板执行失败: %w", err)
+	}
+	rendered := bytes.TrimSpace(out.buf.Bytes())
+	if !json.Valid(rendered) {
+		return nil, fmt.Errorf("输出模板的渲染结果不是合法的 JSON: %s", preview(rendered))
+	}
+	return json.RawMessage(rendered), nil
+}
+
+// iterationBudget 单次渲染的迭代预算，超出次数或时限时返回错误，终止模板执行
+type iterationBudget struct {
+	remaining int
+	deadline  time.Time
+}
+
+func newIterationBudget() *iterationBudget {
+	return &iterationBudget{remaining: maxRangeIterations, deadline: time.Now().Add(renderTimeout)}
+}
+
+func (b *iterationBudget) next() (string, error) {
+	b.remaining--
+	if b.remaining < 0 || time.Now().After(b.deadline) {
+		return "", errRenderTooLong
+	}
+	return "", nil
+}
+
+// limitedBuffer 超出上限时返回错误，终止模板执行
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > b.limit {
+		return 0, errRenderTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+// safeSprintf 限制宽度与精度的 printf（避免 %999999999d 之类的格式分配大量内存）
+func safeSprintf(format string, args ...interface{}) (string, error) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		number := 0
+		for i++; i < len(format); i++ {
+			c := format[i]
+			switch {
+			case c == '*':
+				return "", errors.New("printf 不支持 * 宽度")
+			case c >= '0' && c <= '9':
+				number = number*10 + int(c-'0')
+				if number > maxFormatWidth {
+					return "", fmt.Errorf("printf 宽度与精度不能超过 %d", maxFormatWidth)
+				}
+				continue
+			case c == '.':
+				number = 0
+				continue
+			case strings.IndexByte("+-# [", c) >= 0:
+				continue
+			}
+			break
+		}
+	}
+	return fmt.Sprintf(format, args...), nil
+}
+
+func preview(data []byte) string {
+	const size = 200
+	if len(data) <= size {
+		return string(data)
+	}
+	return string(data[:size]) + "..."
+}
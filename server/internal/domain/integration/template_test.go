@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func templateData(t *testing.T) interface{} {
+	t.Helper()
+	var data interface{}
+	raw := `{"id":"rec1","recordId":"rec1","event":"record.created","version":3,
+		"fields":{"客户名称":"Acme \"Inc\"","金额":12.5,"标签":["a","b"],"备注":null}}`
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPayloadTemplateRender(t *testing.T) {
+	tmpl, err := ParseTemplate(`{
+		"customer": {{json (index .fields "客户名称")}},
+		"amount": {{index .fields "金额"}},
+		"tags": "{{join "," (index .fields "标签")}}",
+		"note": {{json (default "无" (index .fields "备注"))}},
+		"kind": {{json (upper .event)}},
+		"items": [{{range $i, $tag := index .fields "标签"}}{{if $i}},{{end}}{{printf "%q" $tag}}{{end}}]
+	}`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	out, err := tmpl.Render(templateData(t))
+	if err != nil {
+		t.Fatalf("渲染失败: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["customer"] != `Acme "Inc"` || got["amount"] != 12.5 || got["tags"] != "a,b" ||
+		got["note"] != "无" || got["kind"] != "RECORD.CREATED" || len(got["items"].([]interface{})) != 2 {
+		t.Errorf("渲染结果 = %s", out)
+	}
+
+	// 渲染结果必须是合法的 JSON
+	tmpl, _ = ParseTemplate(`{"customer": {{index .fields "客户名称"}}}`)
+	if _, err := tmpl.Render(templateData(t)); err == nil || !strings.Contains(err.Error(), "JSON") {
+		t.Errorf("非法 JSON 应渲染失败: %v", err)
+	}
+}
+
+func TestPayloadTemplateLimits(t *testing.T) {
+	for _, src := range []string{
+		"",
+		`{{define "a"}}{{template "a" .}}{{end}}{{template "a" .}}`,
+		`{{range 1000000000}}{{end}}`,
+		`{{$n := 1000000000}}{{range $n}}{{end}}`,
+		`{{$n := len .fields}}{{$m := $n}}{{range $m}}{{end}}`,
+		`{{range (or 0 1000000000)}}{{end}}`,
+		`{{range .fields}}{{range $.fields}}{{range $.fields}}{{end}}{{end}}{{end}}`,
+		`{{range .fields}}{{if .}}{{with $.fields}}{{range .}}{{range $.fields}}{{end}}{{end}}{{end}}{{end}}{{end}}`,
+		`{{if`,
+		strings.Repeat("x", MaxTemplateLength+1),
+	} {
+		if _, err := ParseTemplate(src); err == nil {
+			t.Errorf("%.40q 应解析失败", src)
+		}
+	}
+
+	tmpl, err := ParseTemplate(`{{printf "%999999999d" 1}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Render(templateData(t)); err == nil {
+		t.Error("超宽的 printf 应执行失败")
+	}
+
+	// 不输出内容的循环同样受迭代次数限制
+	tmpl, err = ParseTemplate(`{{range .fields}}{{range $.fields}}{{end}}{{end}}{{range .fields}}{{range $.fields}}{{end}}{{end}}0`)
+	if err != nil {
+		t.Fatalf("两层嵌套的 range 应解析成功: %v", err)
+	}
+	if _, err := tmpl.Render(map[string]interface{}{"fields": make([]interface{}, 200)}); err != nil {
+		t.Errorf("迭代次数未超限时应渲染成功: %v", err)
+	}
+	if _, err := tmpl.Render(map[string]interface{}{"fields": make([]interface{}, 300)}); err != errRenderTooLong {
+		t.Errorf("超出迭代上限应失败: %v", err)
+	}
+
+	tmpl, _ = ParseTemplate(`[{{range .fields}}"{{printf "%100s" "x"}}{{printf "%100s" "x"}}",{{end}}0]`)
+	data := map[string]interface{}{"fields": make([]interface{}, 2000)}
+	if _, err := tmpl.Render(data); err != errRenderTooLarge {
+		t.Errorf("超出大小上限应失败: %v", err)
+	}
+}
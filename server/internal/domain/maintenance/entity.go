@@ -0,0 +1,128 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// MaxMessageLength 维护提示的长度上限
+const MaxMessageLength = 500
+
+// ConfigWindowID 配置文件开启的实例只读（不保存在数据库中，不能通过接口结束）
+const ConfigWindowID = "config"
+
+// Scope 只读范围
+type Scope string
+
+const (
+	// ScopeInstance 整个实例只读
+	ScopeInstance Scope = "instance"
+	// ScopeSpace 工作空间只读
+	ScopeSpace Scope = "space"
+	// ScopeBase 单个 Base 只读
+	ScopeBase Scope = "base"
+)
+
+// IsValidScope 是否为有效的范围
+func IsValidScope(scope Scope) bool {
+	switch scope {
+	case ScopeInstance, ScopeSpace, ScopeBase:
+		return true
+	}
+	return false
+}
+
+// Window 维护窗口 ✨
+// 维护期间范围内的数据只允许读取（用于迁移或故障处理），客户端据此显示提示横幅；
+// 设置了 EndsAt 时到期自动结束，否则需要管理员手动结束
+type Window struct {
+	ID        string     `json:"id"`
+	Scope     Scope      `json:"scope"`
+	ScopeID   string     `json:"scope_id,omitempty"` // 实例范围为空
+	Message   string     `json:"message,omitempty"`  // 展示给用户的提示
+	StartedBy string     `json:"started_by,omitempty"`
+	StartedAt time.Time  `json:"started_at"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"` // 计划结束时间
+	EndedBy   string     `json:"ended_by,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+}
+
+// NewWindow 创建维护窗口
+func NewWindow(scope Scope, scopeID, message, userID string, endsAt *time.Time, now time.Time) (*Window, error) {
+	if !IsValidScope(scope) {
+		return nil, fmt.Errorf("不支持的范围: %s", scope)
+	}
+	scopeID = strings.TrimSpace(scopeID)
+	if scope == ScopeInstance {
+		scopeID = ""
+	} else if scopeID == "" {
+		return nil, fmt.Errorf("%s 范围需要指定 scopeId", scope)
+	}
+	message = strings.TrimSpace(message)
+	if utf8.RuneCountInString(message) > MaxMessageLength {
+		return nil, fmt.Errorf("提示不能超过 %d 个字符", MaxMessageLength)
+	}
+	if endsAt != nil && !endsAt.After(now) {
+		return nil, fmt.Errorf("结束时间必须晚于当前时间")
+	}
+	return &Window{
+		ID:        utils.GenerateIDWithPrefix("mnt"),
+		Scope:     scope,
+		ScopeID:   scopeID,
+		Message:   message,
+		StartedBy: userID,
+		StartedAt: now,
+		EndsAt:    endsAt,
+	}, nil
+}
+
+// Active 维护窗口在给定时刻是否生效（未结束且未到计划结束时间）
+func (w *Window) Active(now time.Time) bool {
+	return w.EndedAt == nil && (w.EndsAt == nil || now.Before(*w.EndsAt))
+}
+
+// End 结束维护窗口
+func (w *Window) End(userID string, now time.Time) error {
+	if w.EndedAt != nil {
+		return fmt.Errorf("维护已结束")
+	}
+	w.EndedBy = userID
+	w.EndedAt = &now
+	return nil
+}
+
+// Covers 维护窗口是否覆盖给定的工作空间与 Base
+func (w *Window) Covers(spaceID, baseID string) bool {
+	switch w.Scope {
+	case ScopeInstance:
+		return true
+	case ScopeSpace:
+		return spaceID != "" && w.ScopeID == spaceID
+	case ScopeBase:
+		return baseID != "" && w.ScopeID == baseID
+	}
+	return false
+}
+
+// Effective 覆盖给定工作空间与 Base 的生效维护窗口（按范围从大到小排列）
+func Effective(windows []*Window, spaceID, baseID string, now time.Time) []*Window {
+	var out []*Window
+	for _, scope := range []Scope{ScopeInstance, ScopeSpace, ScopeBase} {
+		for _, w := range windows {
+			if w.Scope == scope && w.Active(now) && w.Covers(spaceID, baseID) {
+				out = append(out, w)
+			}
+		}
+	}
+	return out
+}
+
+// Status 工作空间或 Base 的维护状态（客户端据此显示提示横幅）
+type Status struct {
+	ReadOnly bool      `json:"read_only"`
+	Windows  []*Window `json:"windows"`
+}
@@ -0,0 +1,78 @@
+package maintenance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWindow(t *testing.T) {
+	now := time.Now()
+	w, err := NewWindow(ScopeInstance, "spc_ignored", " 数据库迁移 ", "usr_admin", nil, now)
+	if err != nil {
+		t.Fatalf("NewWindow: %v", err)
+	}
+	if w.ScopeID != "" || w.Message != "数据库迁移" {
+		t.Errorf("instance scope should drop the scope id and trim the message, got %+v", w)
+	}
+
+	if _, err := NewWindow(ScopeBase, " ", "", "usr_admin", nil, now); err == nil {
+		t.Errorf("base scope without id should be rejected")
+	}
+	if _, err := NewWindow("table", "tbl_1", "", "usr_admin", nil, now); err == nil {
+		t.Errorf("unknown scope should be rejected")
+	}
+	past := now.Add(-time.Minute)
+	if _, err := NewWindow(ScopeSpace, "spc_1", "", "usr_admin", &past, now); err == nil {
+		t.Errorf("end time in the past should be rejected")
+	}
+	if _, err := NewWindow(ScopeSpace, "spc_1", strings.Repeat("维", MaxMessageLength+1), "usr_admin", nil, now); err == nil {
+		t.Errorf("overlong message should be rejected")
+	}
+}
+
+func TestWindowLifecycle(t *testing.T) {
+	now := time.Now()
+	endsAt := now.Add(time.Hour)
+	w, err := NewWindow(ScopeSpace, "spc_1", "", "usr_admin", &endsAt, now)
+	if err != nil {
+		t.Fatalf("NewWindow: %v", err)
+	}
+	if !w.Active(now) || w.Active(endsAt) {
+		t.Errorf("window should be active until its planned end")
+	}
+	if err := w.End("usr_admin", now); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if w.Active(now) {
+		t.Errorf("ended window should not be active")
+	}
+	if err := w.End("usr_admin", now); err == nil {
+		t.Errorf("ending twice should fail")
+	}
+}
+
+func TestEffective(t *testing.T) {
+	now := time.Now()
+	base := &Window{ID: "b", Scope: ScopeBase, ScopeID: "bse_1", StartedAt: now}
+	space := &Window{ID: "s", Scope: ScopeSpace, ScopeID: "spc_1", StartedAt: now}
+	ended := now.Add(-time.Minute)
+	old := &Window{ID: "o", Scope: ScopeInstance, StartedAt: now, EndedAt: &ended}
+	windows := []*Window{base, space, old}
+
+	got := Effective(windows, "spc_1", "bse_1", now)
+	if len(got) != 2 || got[0] != space || got[1] != base {
+		t.Fatalf("expected space then base window, got %+v", got)
+	}
+	if got := Effective(windows, "spc_2", "bse_2", now); len(got) != 0 {
+		t.Errorf("other spaces should not be covered, got %+v", got)
+	}
+	if got := Effective(windows, "", "", now); len(got) != 0 {
+		t.Errorf("unresolved resources are only covered by instance windows")
+	}
+
+	instance := &Window{ID: "i", Scope: ScopeInstance, StartedAt: now}
+	if got := Effective(append(windows, instance), "", "", now); len(got) != 1 || got[0] != instance {
+		t.Errorf("instance window should cover everything, got %+v", got)
+	}
+}
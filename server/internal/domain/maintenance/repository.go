@@ -0,0 +1,22 @@
+package maintenance
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 维护窗口仓储接口
+type Repository interface {
+	// Create 保存新的维护窗口
+	Create(ctx context.Context, window *Window) error
+	// FindByID 获取维护窗口（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Window, error)
+	// End 结束维护窗口（已结束时不更新）
+	End(ctx context.Context, window *Window) error
+	// ListActive 列出尚未结束的维护窗口（含已过计划结束时间、尚未被结束的）
+	ListActive(ctx context.Context) ([]*Window, error)
+	// ListRecent 按开始时间倒序列出最近的维护窗口
+	ListRecent(ctx context.Context, limit int) ([]*Window, error)
+	// EndExpired 结束计划结束时间已到的维护窗口，返回本次结束的窗口（多实例时每个窗口只会被一个实例结束）
+	EndExpired(ctx context.Context, now time.Time) ([]*Window, error)
+}
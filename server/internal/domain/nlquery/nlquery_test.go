@@ -0,0 +1,76 @@
+package nlquery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func TestParseSpec(t *testing.T) {
+	text := "好的，条件如下：\n```json\n" +
+		`{"filter":{"filters":[{"fieldId":"fldOwner","operator":"hasAnyOf","value":["@me"]},` +
+		`{"fieldId":"fldDue","operator":"isWithin","value":["2026-10-12T00:00:00+08:00","2026-10-18T23:59:59+08:00"]}]},` +
+		`"sort":[{"fieldId":"fldDue"}],"explanation":"分配给我且本周到期"}` + "\n```"
+	spec, err := ParseSpec(text)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if spec.Filter.Operator != viewVO.FilterOperatorAnd || len(spec.Filter.Filters) != 2 {
+		t.Fatalf("过滤条件 = %+v", spec.Filter)
+	}
+	if len(spec.Sort) != 1 || spec.Sort[0].Order != viewVO.SortOrderAsc {
+		t.Errorf("排序 = %+v", spec.Sort)
+	}
+
+	spec.ResolveCurrentUser("usr1")
+	if values := spec.Filter.Filters[0].Value.([]interface{}); values[0] != "usr1" {
+		t.Errorf("当前用户占位符未替换: %v", values)
+	}
+	if values := spec.Filter.Filters[1].Value.([]interface{}); values[0] != "2026-10-12T00:00:00+08:00" {
+		t.Errorf("其他值不应改变: %v", values)
+	}
+}
+
+func TestParseSpecNoFilter(t *testing.T) {
+	spec, err := ParseSpec(`{"filter":null,"sort":[],"explanation":"表中没有相关字段"}`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if spec.Filter != nil || spec.Sort == nil || spec.Explanation != "表中没有相关字段" {
+		t.Errorf("结果 = %+v", spec)
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	items := make([]string, MaxFilterItems+1)
+	for i := range items {
+		items[i] = `{"fieldId":"fld","operator":"isEmpty"}`
+	}
+	for _, text := range []string{
+		"无法回答",
+		`{"filter": {`,
+		`{"filter":{"operator":"and","filters":[{"fieldId":"fld","operator":"like","value":"x"}]}}`,
+		`{"filter":{"operator":"and","filters":[{"fieldId":"fld","operator":"is"}]}}`,
+		`{"sort":[{"fieldId":"fld","order":"up"}]}`,
+		`{"filter":{"operator":"and","filters":[` + strings.Join(items, ",") + `]}}`,
+	} {
+		if _, err := ParseSpec(text); err == nil {
+			t.Errorf("ParseSpec(%q) 应返回错误", text)
+		}
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	prompt := BuildPrompt("本周到期的任务", "任务", []FieldInfo{
+		{ID: "fldStatus", Name: "状态", Type: "singleSelect", Choices: []string{"进行中", "已完成"}},
+	}, time.Date(2026, 10, 15, 9, 30, 0, 0, loc))
+
+	for _, want := range []string{"2026-10-15T09:30:00+08:00", "星期四", "数据表：任务", `"choices":["进行中","已完成"]`, "问题：本周到期的任务"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("提示词缺少 %q:\n%s", want, prompt)
+		}
+	}
+}
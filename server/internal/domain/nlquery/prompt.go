@@ -0,0 +1,48 @@
+package nlquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SystemPrompt 将问题转换为过滤条件时的系统提示词
+const SystemPrompt = `你负责把用户关于数据表的问题转换为过滤与排序条件，只输出一个 JSON 对象，结构如下：
+{"filter": {"operator": "and" 或 "or", "filters": [{"fieldId": "字段ID", "operator": "操作符", "value": 值}]},
+ "sort": [{"fieldId": "字段ID", "order": "asc" 或 "desc"}],
+ "explanation": "一句话说明条件的含义"}
+可用的操作符：
+- 文本：is、isNot、contains、notContains、isEmpty、isNotEmpty
+- 数字：is、isNot、isGreater、isGreaterEqual、isLess、isLessEqual、isWithin（value 为 [最小值, 最大值]）
+- 日期：is（按天比较）、isBefore、isAfter、isWithin（value 为 [开始, 结束]，使用带时区的 ISO 8601 时间）
+- 单选：is、isNot、hasAnyOf（value 为选项名称数组）
+- 多选、用户、关联：hasAnyOf、hasAllOf、hasNoneOf（value 为数组）
+- 复选框：is（value 为 true 或 false）
+- 任意字段：isEmpty、isNotEmpty（不需要 value）
+规则：
+- 只能使用下方表结构中列出的字段ID，不要编造字段；单选与多选的值只能取列出的选项
+- 问题中的“我”指当前用户，用户字段的值写作 "@me"
+- 相对日期（如“本周”“下个月”“最近 7 天”）按给定的当前时间换算为具体的时间范围，一周从星期一开始
+- 问题无法用这些字段表达时返回 {"filter": null, "sort": [], "explanation": "原因"}`
+
+// FieldInfo 提供给模型的字段定义
+type FieldInfo struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Choices []string `json:"choices,omitempty"` // 单选、多选字段的选项名称
+}
+
+// BuildPrompt 生成用户提示词：当前时间、表结构与问题
+func BuildPrompt(question, tableName string, fields []FieldInfo, now time.Time) string {
+	schema, _ := json.Marshal(fields)
+	var b strings.Builder
+	fmt.Fprintf(&b, "当前时间：%s（%s，时区 %s）\n", now.Format(time.RFC3339), weekdayNames[now.Weekday()], now.Location())
+	fmt.Fprintf(&b, "数据表：%s\n", tableName)
+	fmt.Fprintf(&b, "字段：%s\n", schema)
+	fmt.Fprintf(&b, "问题：%s", question)
+	return b.String()
+}
+
+var weekdayNames = [...]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
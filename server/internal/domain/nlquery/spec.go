@@ -0,0 +1,102 @@
+package nlquery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	viewVO "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+const (
+	// MaxQuestionLength 问题的最大长度（字符数）
+	MaxQuestionLength = 500
+	// MaxFilterItems 生成的过滤条件数上限
+	MaxFilterItems = 10
+	// MaxSortItems 生成的排序条件数上限
+	MaxSortItems = 3
+	// CurrentUser 过滤值中表示当前用户的占位符，执行前替换为用户ID
+	CurrentUser = "@me"
+)
+
+// Spec 由问题生成的过滤与排序（与视图的过滤器、排序结构一致）
+type Spec struct {
+	Filter      *viewVO.Filter    `json:"filter"`
+	Sort        []viewVO.SortItem `json:"sort"`
+	Explanation string            `json:"explanation,omitempty"` // 模型对生成条件的简要说明
+}
+
+// ParseSpec 解析模型输出的 JSON（容忍 Markdown 代码块与前后说明文字）
+// 只做结构校验；字段是否存在、操作符与字段类型是否匹配由调用方按表结构校验
+func ParseSpec(text string) (*Spec, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("模型没有返回 JSON")
+	}
+
+	var spec Spec
+	if err := json.Unmarshal([]byte(text[start:end+1]), &spec); err != nil {
+		return nil, fmt.Errorf("模型返回的 JSON 无效: %w", err)
+	}
+
+	if spec.Filter != nil && len(spec.Filter.Filters) == 0 {
+		spec.Filter = nil
+	}
+	if spec.Filter != nil {
+		if spec.Filter.Operator == "" {
+			spec.Filter.Operator = viewVO.FilterOperatorAnd
+		}
+		if len(spec.Filter.Filters) > MaxFilterItems {
+			return nil, fmt.Errorf("过滤条件超过 %d 个", MaxFilterItems)
+		}
+		if err := spec.Filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(spec.Sort) > MaxSortItems {
+		spec.Sort = spec.Sort[:MaxSortItems]
+	}
+	for i := range spec.Sort {
+		if spec.Sort[i].Order == "" {
+			spec.Sort[i].Order = viewVO.SortOrderAsc
+		}
+		if err := spec.Sort[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Sort == nil {
+		spec.Sort = []viewVO.SortItem{}
+	}
+	if utf8.RuneCountInString(spec.Explanation) > MaxQuestionLength {
+		spec.Explanation = string([]rune(spec.Explanation)[:MaxQuestionLength])
+	}
+	return &spec, nil
+}
+
+// ResolveCurrentUser 将过滤值中的 CurrentUser 占位符替换为用户ID
+func (s *Spec) ResolveCurrentUser(userID string) {
+	if s.Filter == nil {
+		return
+	}
+	for i := range s.Filter.Filters {
+		s.Filter.Filters[i].Value = resolveCurrentUser(s.Filter.Filters[i].Value, userID)
+	}
+}
+
+func resolveCurrentUser(value interface{}, userID string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == CurrentUser {
+			return userID
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = resolveCurrentUser(v[i], userID)
+		}
+	}
+	return value
+}
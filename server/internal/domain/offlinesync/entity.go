@@ -0,0 +1,184 @@
+package offlinesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// MutationType 离线修改的类型
+type MutationType string
+
+const (
+	MutationCreate MutationType = "create"
+	MutationUpdate MutationType = "update"
+	MutationDelete MutationType = "delete"
+)
+
+// 冲突处理策略
+const (
+	StrategyMerge     = "merge"     // 默认：只有被修改的字段在服务端也变化时才冲突
+	StrategyOverwrite = "overwrite" // 以客户端为准覆盖（记录已删除时仍为冲突）
+)
+
+// Status 修改的回放结果
+type Status string
+
+const (
+	StatusApplied  Status = "applied"  // 已写入
+	StatusConflict Status = "conflict" // 与服务端的修改冲突，未写入
+	StatusFailed   Status = "failed"   // 权限、校验等错误，未写入
+)
+
+// 冲突原因
+const (
+	ConflictModified = "modified" // 记录在离线期间被他人修改
+	ConflictDeleted  = "deleted"  // 记录已被删除
+)
+
+// MaxIDLength 客户端ID、修改ID与本地记录ID的最大长度
+const MaxIDLength = 100
+
+// Mutation 客户端离线期间排队的一次记录修改 ✨
+// ID 由客户端生成且在该客户端内唯一，重连后重复推送同一修改只会执行一次；
+// 新建记录时 RecordID 为客户端的本地ID，同一客户端之后的修改可以引用该本地ID，服务端替换为真实记录ID。
+// Base 为客户端修改前看到的字段值（字段ID → 值），用于按字段判断冲突；未提供时按 BaseVersion 判断
+type Mutation struct {
+	ID          string                 `json:"id"`
+	Type        MutationType           `json:"type"`
+	TableID     string                 `json:"tableId"`
+	RecordID    string                 `json:"recordId"`
+	BaseVersion *int64                 `json:"baseVersion,omitempty"`
+	Base        map[string]interface{} `json:"base,omitempty"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Strategy    string                 `json:"strategy,omitempty"`
+}
+
+// Validate 校验修改的基本格式
+func (m *Mutation) Validate() error {
+	if m.ID == "" || utf8.RuneCountInString(m.ID) > MaxIDLength {
+		return fmt.Errorf("修改ID不能为空且不能超过 %d 个字符", MaxIDLength)
+	}
+	if m.TableID == "" {
+		return fmt.Errorf("修改 %s 缺少 tableId", m.ID)
+	}
+	if utf8.RuneCountInString(m.RecordID) > MaxIDLength {
+		return fmt.Errorf("修改 %s 的 recordId 不能超过 %d 个字符", m.ID, MaxIDLength)
+	}
+	switch m.Type {
+	case MutationCreate:
+	case MutationUpdate:
+		if m.RecordID == "" {
+			return fmt.Errorf("修改 %s 缺少 recordId", m.ID)
+		}
+		if len(m.Fields) == 0 {
+			return fmt.Errorf("修改 %s 没有要更新的字段", m.ID)
+		}
+	case MutationDelete:
+		if m.RecordID == "" {
+			return fmt.Errorf("修改 %s 缺少 recordId", m.ID)
+		}
+	default:
+		return fmt.Errorf("修改 %s 的类型 %q 不受支持（可选 create、update、delete）", m.ID, m.Type)
+	}
+	switch m.Strategy {
+	case "":
+		m.Strategy = StrategyMerge
+	case StrategyMerge, StrategyOverwrite:
+	default:
+		return fmt.Errorf("修改 %s 的冲突策略 %q 不受支持（可选 %s、%s）", m.ID, m.Strategy, StrategyMerge, StrategyOverwrite)
+	}
+	return nil
+}
+
+// FieldConflict 一个字段上的冲突：客户端基于 Base 改为 Local，服务端已变为 Server
+type FieldConflict struct {
+	FieldID string      `json:"fieldId"`
+	Base    interface{} `json:"base"`
+	Local   interface{} `json:"local"`
+	Server  interface{} `json:"server"`
+}
+
+// Conflict 冲突详情，客户端据此合并后以新的修改ID重新推送
+type Conflict struct {
+	Reason        string                 `json:"reason"`
+	ServerVersion int64                  `json:"serverVersion"`
+	ServerFields  map[string]interface{} `json:"serverFields,omitempty"` // 服务端当前的字段值（按请求用户脱敏）
+	Fields        []FieldConflict        `json:"fields,omitempty"`
+}
+
+// Result 一次修改的回放结果
+type Result struct {
+	MutationID string    `json:"mutationId"`
+	Status     Status    `json:"status"`
+	RecordID   string    `json:"recordId,omitempty"` // 服务端记录ID（新建时为新记录ID）
+	Version    int64     `json:"version,omitempty"`  // 写入后的记录版本
+	Conflict   *Conflict `json:"conflict,omitempty"`
+	ErrorCode  string    `json:"errorCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Replayed   bool      `json:"replayed,omitempty"` // 修改此前已处理过，返回的是当时的结果
+}
+
+// Entry 已处理修改的登记（用于重复推送时返回原结果、解析本地记录ID）
+type Entry struct {
+	ClientID       string
+	MutationID     string
+	UserID         string
+	TableID        string
+	ClientRecordID string // 新建记录时客户端的本地ID
+	Result         Result
+	CreatedAt      time.Time
+}
+
+// DetectConflicts 按字段检测冲突：客户端修改的字段在服务端已不是 Base 中的值、且与客户端的新值不同即为冲突；
+// 没有提供该字段的 Base 时，服务端版本与 BaseVersion 不一致且值不同即为冲突
+func DetectConflicts(m *Mutation, server map[string]interface{}, serverVersion int64) []FieldConflict {
+	versionChanged := m.BaseVersion != nil && *m.BaseVersion != serverVersion
+	var conflicts []FieldConflict
+	for fieldID, local := range m.Fields {
+		current := server[fieldID]
+		if Equal(local, current) {
+			continue
+		}
+		base, ok := m.Base[fieldID]
+		if ok && Equal(base, current) {
+			continue
+		}
+		if !ok && !versionChanged {
+			continue
+		}
+		conflicts = append(conflicts, FieldConflict{FieldID: fieldID, Base: base, Local: local, Server: current})
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].FieldID < conflicts[j].FieldID })
+	return conflicts
+}
+
+// Equal 按 JSON 语义比较两个单元格值（数字类型、空值与空数组的差异被忽略）
+func Equal(a, b interface{}) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func normalize(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	switch v := normalized.(type) {
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+	case string:
+		if v == "" {
+			return nil
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,71 @@
+package offlinesync
+
+import "testing"
+
+func TestMutationValidate(t *testing.T) {
+	m := &Mutation{ID: "m1", Type: MutationUpdate, TableID: "tbl1", RecordID: "rec1", Fields: map[string]interface{}{"fld1": 1}}
+	if err := m.Validate(); err != nil || m.Strategy != StrategyMerge {
+		t.Errorf("默认应为 merge 策略: %v", err)
+	}
+	if err := (&Mutation{ID: "m2", Type: MutationUpdate, TableID: "tbl1", RecordID: "rec1"}).Validate(); err == nil {
+		t.Error("没有字段的更新应报错")
+	}
+	if err := (&Mutation{ID: "m3", Type: MutationDelete, TableID: "tbl1"}).Validate(); err == nil {
+		t.Error("删除缺少 recordId 应报错")
+	}
+	if err := (&Mutation{ID: "m4", Type: "upsert", TableID: "tbl1"}).Validate(); err == nil {
+		t.Error("不支持的类型应报错")
+	}
+	if err := (&Mutation{ID: "m5", Type: MutationCreate, TableID: "tbl1", Strategy: "lastWins"}).Validate(); err == nil {
+		t.Error("不支持的策略应报错")
+	}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	base := int64(3)
+	m := &Mutation{
+		BaseVersion: &base,
+		Base:        map[string]interface{}{"qty": 5, "status": "open", "note": nil},
+		Fields:      map[string]interface{}{"qty": 4, "status": "closed", "note": "盘点"},
+	}
+
+	// 服务端只改了未被客户端修改的字段：自动合并
+	server := map[string]interface{}{"qty": float64(5), "status": "open", "owner": "usr2"}
+	if conflicts := DetectConflicts(m, server, 3); len(conflicts) != 0 {
+		t.Errorf("版本一致时不应冲突: %+v", conflicts)
+	}
+	server["note"] = ""
+	if conflicts := DetectConflicts(m, server, 4); len(conflicts) != 0 {
+		t.Errorf("被修改字段在服务端未变化时不应冲突: %+v", conflicts)
+	}
+
+	// 同一字段被双方改为不同的值：冲突；改为相同的值：不冲突
+	server["qty"] = 3
+	server["status"] = "closed"
+	conflicts := DetectConflicts(m, server, 5)
+	if len(conflicts) != 1 || conflicts[0].FieldID != "qty" || conflicts[0].Server != 3 {
+		t.Fatalf("数量字段应冲突: %+v", conflicts)
+	}
+
+	// 没有 Base 时按版本判断
+	m.Base = nil
+	if conflicts := DetectConflicts(m, server, 3); len(conflicts) != 0 {
+		t.Errorf("版本一致时不应冲突: %+v", conflicts)
+	}
+	conflicts = DetectConflicts(m, server, 5)
+	if len(conflicts) != 2 || conflicts[0].FieldID != "note" || conflicts[1].FieldID != "qty" {
+		t.Errorf("版本变化且值不同的字段应冲突: %+v", conflicts)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal(5, float64(5)) || !Equal(nil, "") || !Equal([]string{}, nil) {
+		t.Error("数字类型与空值差异应被忽略")
+	}
+	if !Equal([]interface{}{map[string]interface{}{"id": "u1"}}, []map[string]string{{"id": "u1"}}) {
+		t.Error("结构相同的值应相等")
+	}
+	if Equal("a", "b") {
+		t.Error("不同的值不应相等")
+	}
+}
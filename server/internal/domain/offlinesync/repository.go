@@ -0,0 +1,18 @@
+package offlinesync
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 离线修改登记仓储接口
+type Repository interface {
+	// FindEntries 查找客户端已处理的修改（按修改ID）
+	FindEntries(ctx context.Context, userID, clientID string, mutationIDs []string) (map[string]*Entry, error)
+	// ResolveRecordIDs 将客户端新建记录时使用的本地ID解析为服务端记录ID（未找到的本地ID不在结果中）
+	ResolveRecordIDs(ctx context.Context, userID, clientID string, clientRecordIDs []string) (map[string]string, error)
+	// SaveEntry 登记已处理的修改
+	SaveEntry(ctx context.Context, entry *Entry) error
+	// PurgeBefore 删除早于 before 的登记，返回数量
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
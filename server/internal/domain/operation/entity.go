@@ -0,0 +1,125 @@
+package operation
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Type 长时操作类型（与执行器注册的类型一致）
+type Type string
+
+const (
+	TypeRecordImport    Type = "record_import"     // 导入记录
+	TypeBranchPromote   Type = "branch_promote"    // 沙盒分支结构合并回生产
+	TypeFindReplace     Type = "find_replace"      // 批量查找替换
+	TypeFindReplaceUndo Type = "find_replace_undo" // 撤销批量查找替换
+)
+
+// Status 长时操作状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待后台执行
+	StatusRunning   Status = "running"   // 执行中
+	StatusSucceeded Status = "succeeded" // 已完成（可能包含部分失败的条目，见 Errors）
+	StatusFailed    Status = "failed"    // 执行失败
+	StatusCancelled Status = "cancelled" // 已取消
+)
+
+// MaxItemErrors 每个操作保留的条目级错误上限，超出部分只计数
+const MaxItemErrors = 100
+
+// ItemError 条目级错误（部分失败不会中断整个操作）
+type ItemError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// Operation 长时操作 ✨
+// 导入、转换、恢复等耗时任务统一以操作资源表示：
+// 发起请求只创建操作并返回其ID，后台执行进度、条目级错误和最终结果持久化在操作中，
+// 客户端轮询或通过实时频道 operation:<id> 订阅进度
+type Operation struct {
+	ID              string          `json:"id"`
+	BaseID          string          `json:"base_id"`
+	Type            Type            `json:"type"`
+	Status          Status          `json:"status"`
+	Params          json.RawMessage `json:"-"` // 执行参数（可能很大，如导入数据），不在响应中返回
+	Total           int64           `json:"total"`
+	Processed       int64           `json:"processed"`
+	FailedCount     int64           `json:"failed_count"`
+	Errors          []ItemError     `json:"errors"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested"`
+	CreatedBy       string          `json:"created_by"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+// NewOperation 创建等待执行的操作
+func NewOperation(baseID string, opType Type, params json.RawMessage, createdBy string) *Operation {
+	now := time.Now()
+	return &Operation{
+		ID:        utils.GenerateIDWithPrefix("opr"),
+		BaseID:    baseID,
+		Type:      opType,
+		Status:    StatusPending,
+		Params:    params,
+		Errors:    []ItemError{},
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Finished 操作是否已结束
+func (o *Operation) Finished() bool {
+	return o.Status == StatusSucceeded || o.Status == StatusFailed || o.Status == StatusCancelled
+}
+
+// Progress 执行进度（0-1），总量未知时为 0
+func (o *Operation) Progress() float64 {
+	if o.Status == StatusSucceeded {
+		return 1
+	}
+	if o.Total <= 0 {
+		return 0
+	}
+	if o.Processed >= o.Total {
+		return 1
+	}
+	return float64(o.Processed) / float64(o.Total)
+}
+
+// AddItemError 记录条目级错误，超过 MaxItemErrors 后只累计失败数
+func (o *Operation) AddItemError(index int, message string) {
+	o.FailedCount++
+	if len(o.Errors) < MaxItemErrors {
+		o.Errors = append(o.Errors, ItemError{Index: index, Message: message})
+	}
+}
+
+// Start 开始执行
+func (o *Operation) Start(now time.Time) {
+	o.Status = StatusRunning
+	o.StartedAt = &now
+	o.UpdatedAt = now
+}
+
+// Finish 结束操作
+func (o *Operation) Finish(status Status, result json.RawMessage, err error, now time.Time) {
+	o.Status = status
+	if result != nil {
+		o.Result = result
+	}
+	if err != nil {
+		o.Error = err.Error()
+	}
+	o.UpdatedAt = now
+	o.FinishedAt = &now
+}
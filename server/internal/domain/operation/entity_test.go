@@ -0,0 +1,43 @@
+package operation
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOperationProgress(t *testing.T) {
+	op := NewOperation("bse1", TypeRecordImport, nil, "usr1")
+	if op.Status != StatusPending || op.Finished() {
+		t.Fatalf("新操作应处于等待状态，得到 %s", op.Status)
+	}
+	if op.Progress() != 0 {
+		t.Errorf("总量未知时进度应为 0，得到 %v", op.Progress())
+	}
+
+	op.Start(time.Now())
+	op.Total, op.Processed = 200, 50
+	if op.Progress() != 0.25 {
+		t.Errorf("进度应为 0.25，得到 %v", op.Progress())
+	}
+
+	op.Finish(StatusSucceeded, []byte(`{"created":200}`), nil, time.Now())
+	if !op.Finished() || op.Progress() != 1 || op.FinishedAt == nil {
+		t.Errorf("成功结束的操作进度应为 1，得到 %+v", op)
+	}
+}
+
+func TestOperationItemErrorsCapped(t *testing.T) {
+	op := NewOperation("bse1", TypeRecordImport, nil, "usr1")
+	for i := 0; i < MaxItemErrors+20; i++ {
+		op.AddItemError(i, "字段值无效")
+	}
+	if len(op.Errors) != MaxItemErrors || op.FailedCount != int64(MaxItemErrors+20) {
+		t.Errorf("应保留 %d 条错误并累计全部失败数，得到 %d 条 / %d", MaxItemErrors, len(op.Errors), op.FailedCount)
+	}
+
+	op.Finish(StatusFailed, nil, errors.New("boom"), time.Now())
+	if op.Error != "boom" || op.Status != StatusFailed {
+		t.Errorf("失败原因应保存到操作中，得到 %+v", op)
+	}
+}
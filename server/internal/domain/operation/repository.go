@@ -0,0 +1,35 @@
+package operation
+
+import (
+	"context"
+	"time"
+)
+
+// ListFilter 操作列表过滤条件
+type ListFilter struct {
+	Status Status
+	Type   Type
+	Limit  int
+}
+
+// Repository 长时操作仓储接口
+type Repository interface {
+	// Create 保存新操作
+	Create(ctx context.Context, op *Operation) error
+	// Update 保存执行状态与进度（不覆盖取消请求标记）
+	Update(ctx context.Context, op *Operation) error
+	// FindByID 获取操作（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Operation, error)
+	// ListByBase 列出 Base 下的操作（按创建时间倒序）
+	ListByBase(ctx context.Context, baseID string, filter ListFilter) ([]*Operation, error)
+	// RequestCancel 为未结束的操作设置取消请求标记
+	RequestCancel(ctx context.Context, id string) error
+	// CancelPending 将仍在等待的操作直接标记为已取消，返回是否成功
+	CancelPending(ctx context.Context, id string, now time.Time) (bool, error)
+	// IsCancelRequested 操作是否已被请求取消
+	IsCancelRequested(ctx context.Context, id string) (bool, error)
+	// Claim 领取一个等待执行的操作并标记为执行中，无可执行操作时返回 nil
+	Claim(ctx context.Context, now time.Time) (*Operation, error)
+	// FailStale 将 staleBefore 之后无心跳的执行中操作标记为失败（执行实例已中断），返回数量
+	FailStale(ctx context.Context, staleBefore, now time.Time, reason string) (int64, error)
+}
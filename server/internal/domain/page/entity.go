@@ -0,0 +1,250 @@
+package page
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// ElementType 页面元素类型
+type ElementType string
+
+const (
+	ElementRecordList ElementType = "record_list" // 记录列表（按视图过滤排序，只展示配置的字段）
+	ElementDetail     ElementType = "detail"      // 记录详情面板
+	ElementButton     ElementType = "button"      // 按钮（触发绑定的自动化）
+)
+
+// Access 页面访问级别，级别越高包含的操作越多
+type Access string
+
+const (
+	AccessNone     Access = ""
+	AccessView     Access = "view"     // 查看页面数据
+	AccessInteract Access = "interact" // 查看 + 点击按钮
+	AccessEdit     Access = "edit"     // 查看 + 点击按钮 + 编辑元素中允许编辑的字段
+)
+
+func (a Access) rank() int {
+	switch a {
+	case AccessView:
+		return 1
+	case AccessInteract:
+		return 2
+	case AccessEdit:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Allows 当前级别是否包含 required
+func (a Access) Allows(required Access) bool {
+	return a.rank() > 0 && a.rank() >= required.rank()
+}
+
+// PrincipalType 分享规则的对象类型
+type PrincipalType string
+
+const (
+	PrincipalUser PrincipalType = "user" // 指定用户
+	PrincipalRole PrincipalType = "role" // 在 Base（或其空间）中拥有该角色的成员
+)
+
+// AccessRule 页面分享规则
+type AccessRule struct {
+	PrincipalType PrincipalType `json:"principalType"`
+	PrincipalID   string        `json:"principalId"` // 用户ID或角色名
+	Access        Access        `json:"access"`
+}
+
+// Element 页面元素
+// record_list / detail 绑定表格（可选视图，记录须满足视图过滤条件），FieldIDs 为展示的字段，
+// EditableFieldIDs 为其中允许编辑的字段；button 绑定工作流，TableID 非空时按钮针对单条记录触发
+type Element struct {
+	ID               string      `json:"id"`
+	Type             ElementType `json:"type"`
+	Title            string      `json:"title,omitempty"`
+	TableID          string      `json:"tableId,omitempty"`
+	ViewID           string      `json:"viewId,omitempty"`
+	FieldIDs         []string    `json:"fieldIds,omitempty"`
+	EditableFieldIDs []string    `json:"editableFieldIds,omitempty"`
+	WorkflowID       string      `json:"workflowId,omitempty"`
+	X                int         `json:"x"`
+	Y                int         `json:"y"`
+	W                int         `json:"w"`
+	H                int         `json:"h"`
+}
+
+// Editable 字段是否允许在该元素中编辑
+func (e *Element) Editable(fieldID string) bool {
+	for _, id := range e.EditableFieldIDs {
+		if id == fieldID {
+			return true
+		}
+	}
+	return false
+}
+
+// Page 界面页面（属于 Base）
+// 页面由 Base 编辑者配置，其他用户按分享规则获得受限的访问级别，只能通过页面元素读写数据
+type Page struct {
+	ID             string       `json:"id"`
+	BaseID         string       `json:"baseId"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description,omitempty"`
+	Elements       []Element    `json:"elements"`
+	Rules          []AccessRule `json:"rules"`
+	CreatedBy      string       `json:"createdBy"`
+	CreatedAt      time.Time    `json:"createdTime"`
+	UpdatedAt      time.Time    `json:"lastModifiedTime"`
+	LastModifiedBy string       `json:"lastModifiedBy,omitempty"`
+}
+
+// NewPage 创建页面
+func NewPage(baseID, name, description, createdBy string) (*Page, error) {
+	p := &Page{
+		ID:        utils.GenerateIDWithPrefix("pag"),
+		BaseID:    baseID,
+		Elements:  []Element{},
+		Rules:     []AccessRule{},
+		CreatedBy: createdBy,
+	}
+	if err := p.Rename(name, description, createdBy); err != nil {
+		return nil, err
+	}
+	p.CreatedAt = p.UpdatedAt
+	return p, nil
+}
+
+// Rename 修改名称与描述
+func (p *Page) Rename(name, description, userID string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("page name is required")
+	}
+	if len([]rune(name)) > 255 {
+		return fmt.Errorf("page name is too long")
+	}
+	p.Name = name
+	p.Description = strings.TrimSpace(description)
+	p.touch(userID)
+	return nil
+}
+
+// SetElements 替换页面元素；未指定ID的元素分配新ID
+func (p *Page) SetElements(elements []Element, userID string) error {
+	seen := make(map[string]bool, len(elements))
+	list := make([]Element, 0, len(elements))
+	for _, e := range elements {
+		if e.ID == "" {
+			e.ID = utils.GenerateIDWithPrefix("pge")
+		}
+		if seen[e.ID] {
+			return fmt.Errorf("duplicate element: %s", e.ID)
+		}
+		seen[e.ID] = true
+		if err := validateElement(&e); err != nil {
+			return err
+		}
+		list = append(list, e)
+	}
+	p.Elements = list
+	p.touch(userID)
+	return nil
+}
+
+func validateElement(e *Element) error {
+	if e.X < 0 || e.Y < 0 || e.W < 0 || e.H < 0 {
+		return fmt.Errorf("invalid layout for element: %s", e.ID)
+	}
+	switch e.Type {
+	case ElementRecordList, ElementDetail:
+		if e.TableID == "" {
+			return fmt.Errorf("%s element requires a table", e.Type)
+		}
+		if e.WorkflowID != "" {
+			return fmt.Errorf("%s element cannot bind a workflow", e.Type)
+		}
+		shown := make(map[string]bool, len(e.FieldIDs))
+		for _, id := range e.FieldIDs {
+			if shown[id] {
+				return fmt.Errorf("duplicate field in element %s: %s", e.ID, id)
+			}
+			shown[id] = true
+		}
+		for _, id := range e.EditableFieldIDs {
+			if !shown[id] {
+				return fmt.Errorf("editable field must be shown in element %s: %s", e.ID, id)
+			}
+		}
+	case ElementButton:
+		if e.WorkflowID == "" {
+			return fmt.Errorf("button element requires a workflow")
+		}
+		if len(e.FieldIDs) > 0 || len(e.EditableFieldIDs) > 0 {
+			return fmt.Errorf("button element cannot show fields")
+		}
+	default:
+		return fmt.Errorf("unsupported element type: %s", e.Type)
+	}
+	return nil
+}
+
+// SetRules 替换分享规则（同一对象只能出现一次）
+func (p *Page) SetRules(rules []AccessRule, userID string) error {
+	seen := make(map[string]bool, len(rules))
+	list := make([]AccessRule, 0, len(rules))
+	for _, r := range rules {
+		if r.PrincipalType != PrincipalUser && r.PrincipalType != PrincipalRole {
+			return fmt.Errorf("unsupported principal type: %s", r.PrincipalType)
+		}
+		if r.PrincipalID == "" {
+			return fmt.Errorf("rule principal is required")
+		}
+		if r.Access.rank() == 0 {
+			return fmt.Errorf("unsupported access level: %s", r.Access)
+		}
+		key := string(r.PrincipalType) + ":" + r.PrincipalID
+		if seen[key] {
+			return fmt.Errorf("duplicate rule for %s", key)
+		}
+		seen[key] = true
+		list = append(list, r)
+	}
+	p.Rules = list
+	p.touch(userID)
+	return nil
+}
+
+// AccessFor 用户按分享规则获得的最高访问级别（role 为用户在 Base 或其空间中的角色，可为空）
+func (p *Page) AccessFor(userID, role string) Access {
+	access := AccessNone
+	for _, r := range p.Rules {
+		matched := (r.PrincipalType == PrincipalUser && r.PrincipalID == userID) ||
+			(r.PrincipalType == PrincipalRole && role != "" && r.PrincipalID == role)
+		if matched && r.Access.rank() > access.rank() {
+			access = r.Access
+		}
+	}
+	return access
+}
+
+// Element 按ID查找元素（不存在时返回 nil）
+func (p *Page) Element(id string) *Element {
+	for i := range p.Elements {
+		if p.Elements[i].ID == id {
+			return &p.Elements[i]
+		}
+	}
+	return nil
+}
+
+func (p *Page) touch(userID string) {
+	p.UpdatedAt = time.Now()
+	if userID != "" {
+		p.LastModifiedBy = userID
+	}
+}
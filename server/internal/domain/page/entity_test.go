@@ -0,0 +1,75 @@
+package page
+
+import "testing"
+
+func TestPageElements(t *testing.T) {
+	p, err := NewPage("bse1", " 报修门户 ", "", "usr1")
+	if err != nil {
+		t.Fatalf("创建页面失败: %v", err)
+	}
+	if p.Name != "报修门户" {
+		t.Errorf("名称应去除首尾空白，得到 %q", p.Name)
+	}
+
+	err = p.SetElements([]Element{
+		{Type: ElementRecordList, TableID: "tbl1", FieldIDs: []string{"fld1", "fld2"}, EditableFieldIDs: []string{"fld2"}},
+		{Type: ElementButton, WorkflowID: "wfl1", TableID: "tbl1"},
+	}, "usr1")
+	if err != nil {
+		t.Fatalf("设置元素失败: %v", err)
+	}
+	if p.Elements[0].ID == "" || p.Elements[0].ID == p.Elements[1].ID {
+		t.Errorf("未指定ID的元素应分配新ID，得到 %+v", p.Elements)
+	}
+	if !p.Element(p.Elements[0].ID).Editable("fld2") || p.Element(p.Elements[0].ID).Editable("fld1") {
+		t.Error("只有 editableFieldIds 中的字段可以编辑")
+	}
+
+	invalid := [][]Element{
+		{{Type: ElementRecordList}},
+		{{Type: ElementDetail, TableID: "tbl1", FieldIDs: []string{"fld1"}, EditableFieldIDs: []string{"fld2"}}},
+		{{Type: ElementButton}},
+		{{Type: ElementButton, WorkflowID: "wfl1", FieldIDs: []string{"fld1"}}},
+		{{Type: "chart", TableID: "tbl1"}},
+		{{ID: "e1", Type: ElementButton, WorkflowID: "wfl1"}, {ID: "e1", Type: ElementButton, WorkflowID: "wfl2"}},
+	}
+	for i, elements := range invalid {
+		if err := p.SetElements(elements, "usr1"); err == nil {
+			t.Errorf("第 %d 组元素应校验失败", i)
+		}
+	}
+}
+
+func TestPageAccess(t *testing.T) {
+	p, _ := NewPage("bse1", "门户", "", "usr1")
+	err := p.SetRules([]AccessRule{
+		{PrincipalType: PrincipalRole, PrincipalID: "viewer", Access: AccessView},
+		{PrincipalType: PrincipalUser, PrincipalID: "usr2", Access: AccessEdit},
+	}, "usr1")
+	if err != nil {
+		t.Fatalf("设置分享规则失败: %v", err)
+	}
+
+	if got := p.AccessFor("usr3", "viewer"); got != AccessView {
+		t.Errorf("按角色应获得 view，得到 %q", got)
+	}
+	if got := p.AccessFor("usr2", "viewer"); got != AccessEdit {
+		t.Errorf("多条规则匹配时应取最高级别，得到 %q", got)
+	}
+	if got := p.AccessFor("usr4", ""); got != AccessNone {
+		t.Errorf("没有匹配的规则时不应有访问权限，得到 %q", got)
+	}
+	if !AccessEdit.Allows(AccessInteract) || AccessView.Allows(AccessInteract) || AccessNone.Allows(AccessView) {
+		t.Error("访问级别包含关系不正确")
+	}
+
+	if err := p.SetRules([]AccessRule{{PrincipalType: PrincipalUser, PrincipalID: "usr2", Access: "admin"}}, "usr1"); err == nil {
+		t.Error("未知访问级别应报错")
+	}
+	if err := p.SetRules([]AccessRule{
+		{PrincipalType: PrincipalUser, PrincipalID: "usr2", Access: AccessView},
+		{PrincipalType: PrincipalUser, PrincipalID: "usr2", Access: AccessEdit},
+	}, "usr1"); err == nil {
+		t.Error("同一对象重复的规则应报错")
+	}
+}
@@ -0,0 +1,15 @@
+package page
+
+import "context"
+
+// Repository 页面仓储接口
+type Repository interface {
+	// Save 保存页面（新增或更新）
+	Save(ctx context.Context, p *Page) error
+	// FindByID 获取页面（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Page, error)
+	// ListByBase 列出 Base 下的页面（按创建时间）
+	ListByBase(ctx context.Context, baseID string) ([]*Page, error)
+	// Delete 删除页面
+	Delete(ctx context.Context, id string) error
+}
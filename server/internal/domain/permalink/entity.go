@@ -0,0 +1,106 @@
+package permalink
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// CodeLength 短链接码长度（62 进制，约 8×10^17 种组合）
+const CodeLength = 10
+
+// PathPrefix 永久链接在 Web 应用中的路径前缀，链接形如 {web}/l/{code}
+const PathPrefix = "/l/"
+
+// TargetType 链接目标类型
+type TargetType string
+
+const (
+	TargetRecord TargetType = "record" // 记录（可带打开时所在的视图）
+	TargetView   TargetType = "view"   // 视图
+)
+
+// Permalink 记录或视图的永久短链接 ✨
+// 同一目标只生成一个链接，重复创建返回已有链接；链接只记录表、视图与记录ID，
+// 解析时重新查找所属 Base 并校验访问者的权限，因此不会泄露访问者无权查看的数据
+type Permalink struct {
+	Code       string     `json:"code"`
+	TargetType TargetType `json:"target_type"`
+	TableID    string     `json:"table_id"`
+	ViewID     string     `json:"view_id,omitempty"`
+	RecordID   string     `json:"record_id,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewRecordLink 创建记录链接，viewID 为空时在表的默认视图中打开
+func NewRecordLink(tableID, viewID, recordID, userID string, now time.Time) (*Permalink, error) {
+	if tableID == "" || recordID == "" {
+		return nil, fmt.Errorf("记录链接需要表ID和记录ID")
+	}
+	return newLink(TargetRecord, tableID, viewID, recordID, userID, now), nil
+}
+
+// NewViewLink 创建视图链接
+func NewViewLink(tableID, viewID, userID string, now time.Time) (*Permalink, error) {
+	if tableID == "" || viewID == "" {
+		return nil, fmt.Errorf("视图链接需要表ID和视图ID")
+	}
+	return newLink(TargetView, tableID, viewID, "", userID, now), nil
+}
+
+func newLink(targetType TargetType, tableID, viewID, recordID, userID string, now time.Time) *Permalink {
+	return &Permalink{
+		Code:       utils.GenerateNanoID(CodeLength),
+		TargetType: targetType,
+		TableID:    tableID,
+		ViewID:     viewID,
+		RecordID:   recordID,
+		CreatedBy:  userID,
+		CreatedAt:  now,
+	}
+}
+
+// TargetKey 目标的唯一键（同一目标只保存一个链接）
+func (p *Permalink) TargetKey() string {
+	return strings.Join([]string{string(p.TargetType), p.TableID, p.ViewID, p.RecordID}, ":")
+}
+
+// URL 链接地址，webBaseURL 为空时返回相对路径
+func (p *Permalink) URL(webBaseURL string) string {
+	return strings.TrimSuffix(webBaseURL, "/") + PathPrefix + p.Code
+}
+
+// ValidCode 是否为格式有效的链接码
+func ValidCode(code string) bool {
+	if len(code) != CodeLength {
+		return false
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(utils.Alphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseCode 从链接码或完整链接（…/l/{code}，可带查询参数）中取出链接码
+func ParseCode(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if ValidCode(raw) {
+		return raw, true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	idx := strings.LastIndex(u.Path, PathPrefix)
+	if idx < 0 {
+		return "", false
+	}
+	code := strings.TrimSuffix(u.Path[idx+len(PathPrefix):], "/")
+	return code, ValidCode(code)
+}
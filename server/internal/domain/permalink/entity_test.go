@@ -0,0 +1,65 @@
+package permalink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewLinks(t *testing.T) {
+	now := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	record, err := NewRecordLink("tbl_1", "", "rec_1", "usr_1", now)
+	if err != nil {
+		t.Fatalf("期望创建成功，得到 %v", err)
+	}
+	if !ValidCode(record.Code) || record.TargetType != TargetRecord {
+		t.Errorf("链接无效: %+v", record)
+	}
+	if got := record.TargetKey(); got != "record:tbl_1::rec_1" {
+		t.Errorf("目标键 %s", got)
+	}
+
+	view, err := NewViewLink("tbl_1", "viw_1", "usr_1", now)
+	if err != nil {
+		t.Fatalf("期望创建成功，得到 %v", err)
+	}
+	if view.TargetKey() == record.TargetKey() {
+		t.Error("记录与视图链接的目标键不应相同")
+	}
+
+	if _, err := NewRecordLink("tbl_1", "", "", "usr_1", now); err == nil {
+		t.Error("缺少记录ID时期望失败")
+	}
+	if _, err := NewViewLink("tbl_1", "", "usr_1", now); err == nil {
+		t.Error("缺少视图ID时期望失败")
+	}
+}
+
+func TestURL(t *testing.T) {
+	link := &Permalink{Code: "AbCdEf1234"}
+	if got := link.URL("https://app.example.com/"); got != "https://app.example.com/l/AbCdEf1234" {
+		t.Errorf("得到 %s", got)
+	}
+	if got := link.URL(""); got != "/l/AbCdEf1234" {
+		t.Errorf("得到 %s", got)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	cases := map[string]string{
+		"AbCdEf1234":                                   "AbCdEf1234",
+		" AbCdEf1234 ":                                 "AbCdEf1234",
+		"https://app.example.com/l/AbCdEf1234":         "AbCdEf1234",
+		"https://app.example.com/l/AbCdEf1234/?utm=1":  "AbCdEf1234",
+		"https://app.example.com/ws/l/AbCdEf1234#frag": "AbCdEf1234",
+	}
+	for raw, want := range cases {
+		if got, ok := ParseCode(raw); !ok || got != want {
+			t.Errorf("%q: 期望 %s，得到 %s (%v)", raw, want, got, ok)
+		}
+	}
+	for _, raw := range []string{"", "short", "AbCdEf12345", "AbCdEf-234", "https://app.example.com/x/AbCdEf1234"} {
+		if _, ok := ParseCode(raw); ok {
+			t.Errorf("%q: 期望无效", raw)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package permalink
+
+import "context"
+
+// Repository 永久链接仓储接口
+type Repository interface {
+	// GetByCode 按链接码获取（不存在时返回 nil）
+	GetByCode(ctx context.Context, code string) (*Permalink, error)
+	// GetByTarget 按目标获取已有链接（不存在时返回 nil）
+	GetByTarget(ctx context.Context, link *Permalink) (*Permalink, error)
+	// Create 保存链接；同一目标已有链接（含并发创建）时返回已有链接
+	Create(ctx context.Context, link *Permalink) (*Permalink, error)
+}
@@ -0,0 +1,264 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LimitKey 套餐限额项
+type LimitKey string
+
+const (
+	LimitRowsPerBase     LimitKey = "rows_per_base"    // 每个 Base 的记录数
+	LimitAttachmentBytes LimitKey = "attachment_bytes" // 工作空间附件占用空间
+	LimitAutomationRuns  LimitKey = "automation_runs"  // 每月后台任务运行次数
+	LimitAPICalls        LimitKey = "api_calls"        // 每月服务令牌 API 调用次数
+)
+
+// LimitKeys 全部限额项（输出顺序）
+var LimitKeys = []LimitKey{
+	LimitRowsPerBase,
+	LimitAttachmentBytes,
+	LimitAutomationRuns,
+	LimitAPICalls,
+}
+
+// Monthly 限额是否按自然月累计（否则为当前占用）
+func (k LimitKey) Monthly() bool {
+	return k == LimitAutomationRuns || k == LimitAPICalls
+}
+
+// Limit 一项限额（0 表示不限制）
+// 超过软限额时仍允许写入但给出警告；超过硬限额时拒绝。只有软限额的项按超量计费
+type Limit struct {
+	Soft int64 `json:"soft,omitempty"`
+	Hard int64 `json:"hard,omitempty"`
+}
+
+// Level 用量相对限额的状态
+type Level string
+
+const (
+	LevelOK       Level = "ok"
+	LevelWarning  Level = "warning"  // 超过软限额
+	LevelExceeded Level = "exceeded" // 超过硬限额
+)
+
+// Evaluate 计算在当前用量上再增加 adding 后的状态
+func (l Limit) Evaluate(used, adding int64) Level {
+	total := used + adding
+	switch {
+	case l.Hard > 0 && total > l.Hard:
+		return LevelExceeded
+	case l.Soft > 0 && total > l.Soft:
+		return LevelWarning
+	default:
+		return LevelOK
+	}
+}
+
+// Plan 套餐 ✨
+type Plan struct {
+	ID     string             `json:"id"`
+	Name   string             `json:"name"`
+	Limits map[LimitKey]Limit `json:"limits"`
+}
+
+// Limit 获取限额项（未定义时不限制）
+func (p *Plan) Limit(key LimitKey) Limit {
+	return p.Limits[key]
+}
+
+const (
+	gb = int64(1) << 30
+
+	// DefaultPlanID 未分配套餐的工作空间使用的套餐
+	DefaultPlanID = "free"
+)
+
+// catalog 内置套餐（按等级升序）
+var catalog = []*Plan{
+	{
+		ID:   "free",
+		Name: "Free",
+		Limits: map[LimitKey]Limit{
+			LimitRowsPerBase:     {Soft: 800, Hard: 1000},
+			LimitAttachmentBytes: {Soft: gb * 8 / 10, Hard: gb},
+			LimitAutomationRuns:  {Soft: 80, Hard: 100},
+			LimitAPICalls:        {Soft: 800, Hard: 1000},
+		},
+	},
+	{
+		ID:   "team",
+		Name: "Team",
+		Limits: map[LimitKey]Limit{
+			LimitRowsPerBase:     {Soft: 40000, Hard: 50000},
+			LimitAttachmentBytes: {Soft: 16 * gb, Hard: 20 * gb},
+			LimitAutomationRuns:  {Soft: 20000, Hard: 25000},
+			LimitAPICalls:        {Soft: 80000, Hard: 100000},
+		},
+	},
+	{
+		ID:   "business",
+		Name: "Business",
+		Limits: map[LimitKey]Limit{
+			LimitRowsPerBase:     {Soft: 200000, Hard: 250000},
+			LimitAttachmentBytes: {Soft: 100 * gb},
+			LimitAutomationRuns:  {Soft: 250000},
+			LimitAPICalls:        {Soft: 1000000},
+		},
+	},
+	{
+		ID:     "enterprise",
+		Name:   "Enterprise",
+		Limits: map[LimitKey]Limit{},
+	},
+}
+
+// Catalog 内置套餐列表
+func Catalog() []*Plan {
+	return catalog
+}
+
+// Find 按 ID 查找内置套餐
+func Find(id string) (*Plan, bool) {
+	for _, p := range catalog {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Rank 套餐等级（目录中的位置，越大越高；未知套餐为 -1）
+func Rank(id string) int {
+	for i, p := range catalog {
+		if p.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Assignment 工作空间的套餐
+type Assignment struct {
+	SpaceID   string    `json:"space_id"`
+	PlanID    string    `json:"plan_id"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewAssignment 为工作空间分配套餐
+func NewAssignment(spaceID, planID, userID string, now time.Time) (*Assignment, error) {
+	if _, ok := Find(planID); !ok {
+		return nil, fmt.Errorf("套餐不存在: %s", planID)
+	}
+	return &Assignment{SpaceID: spaceID, PlanID: planID, UpdatedBy: userID, UpdatedAt: now}, nil
+}
+
+// PeriodStart 计费周期（自然月，UTC）的开始时间
+func PeriodStart(now time.Time) time.Time {
+	y, m, _ := now.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ==================== 用量与限额 ====================
+
+// LimitStatus 一项限额的用量
+// 记录数为用量最大的 Base 的值，BaseID 指明是哪个 Base
+type LimitStatus struct {
+	Key    LimitKey `json:"key"`
+	Used   int64    `json:"used"`
+	Soft   int64    `json:"soft,omitempty"`
+	Hard   int64    `json:"hard,omitempty"`
+	Level  Level    `json:"level"`
+	BaseID string   `json:"base_id,omitempty"`
+}
+
+// NewLimitStatus 计算一项限额的用量状态
+func NewLimitStatus(key LimitKey, limit Limit, used int64) *LimitStatus {
+	return &LimitStatus{Key: key, Used: used, Soft: limit.Soft, Hard: limit.Hard, Level: limit.Evaluate(used, 0)}
+}
+
+// BaseRows 一个 Base 的记录数
+type BaseRows struct {
+	BaseID   string `json:"base_id"`
+	BaseName string `json:"base_name,omitempty"`
+	Rows     int64  `json:"rows"`
+	Level    Level  `json:"level"`
+}
+
+// Summary 工作空间的套餐与用量（计费页面）
+type Summary struct {
+	SpaceID     string         `json:"space_id"`
+	Plan        *Plan          `json:"plan"`
+	PeriodStart time.Time      `json:"period_start"`
+	PeriodEnd   time.Time      `json:"period_end"`
+	Limits      []*LimitStatus `json:"limits"`
+	Bases       []*BaseRows    `json:"bases"`
+	MeteredAt   time.Time      `json:"metered_at"`
+}
+
+// Usage 工作空间的计量结果（记录数按 Base）
+type Usage struct {
+	Rows            map[string]int64
+	AttachmentBytes int64
+	AutomationRuns  int64
+	APICalls        int64
+	MeteredAt       time.Time
+}
+
+// Value 限额项对应的用量（记录数为 baseID 对应 Base 的值）
+func (u *Usage) Value(key LimitKey, baseID string) int64 {
+	switch key {
+	case LimitRowsPerBase:
+		return u.Rows[baseID]
+	case LimitAttachmentBytes:
+		return u.AttachmentBytes
+	case LimitAutomationRuns:
+		return u.AutomationRuns
+	case LimitAPICalls:
+		return u.APICalls
+	}
+	return 0
+}
+
+// Summarize 生成计费页面的用量汇总
+func Summarize(spaceID string, p *Plan, u *Usage, now time.Time) *Summary {
+	start := PeriodStart(now)
+	summary := &Summary{
+		SpaceID:     spaceID,
+		Plan:        p,
+		PeriodStart: start,
+		PeriodEnd:   start.AddDate(0, 1, 0),
+		Bases:       []*BaseRows{},
+		MeteredAt:   u.MeteredAt,
+	}
+
+	rowsLimit := p.Limit(LimitRowsPerBase)
+	for baseID, rows := range u.Rows {
+		summary.Bases = append(summary.Bases, &BaseRows{BaseID: baseID, Rows: rows, Level: rowsLimit.Evaluate(rows, 0)})
+	}
+	sort.Slice(summary.Bases, func(i, j int) bool {
+		a, b := summary.Bases[i], summary.Bases[j]
+		if a.Rows != b.Rows {
+			return a.Rows > b.Rows
+		}
+		return a.BaseID < b.BaseID
+	})
+
+	for _, key := range LimitKeys {
+		if key != LimitRowsPerBase {
+			summary.Limits = append(summary.Limits, NewLimitStatus(key, p.Limit(key), u.Value(key, "")))
+			continue
+		}
+		status := NewLimitStatus(key, rowsLimit, 0)
+		if len(summary.Bases) > 0 {
+			status = NewLimitStatus(key, rowsLimit, summary.Bases[0].Rows)
+			status.BaseID = summary.Bases[0].BaseID
+		}
+		summary.Limits = append(summary.Limits, status)
+	}
+	return summary
+}
@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitEvaluate(t *testing.T) {
+	limit := Limit{Soft: 80, Hard: 100}
+	cases := []struct {
+		used, adding int64
+		want         Level
+	}{
+		{0, 80, LevelOK},
+		{80, 1, LevelWarning},
+		{99, 1, LevelWarning},
+		{100, 1, LevelExceeded},
+		{50, 60, LevelExceeded},
+	}
+	for _, tc := range cases {
+		if got := limit.Evaluate(tc.used, tc.adding); got != tc.want {
+			t.Errorf("Evaluate(%d, %d) = %s, want %s", tc.used, tc.adding, got, tc.want)
+		}
+	}
+
+	if got := (Limit{}).Evaluate(1<<40, 1); got != LevelOK {
+		t.Errorf("zero limit should be unlimited, got %s", got)
+	}
+	if got := (Limit{Soft: 10}).Evaluate(100, 1); got != LevelWarning {
+		t.Errorf("soft-only limit should never be exceeded, got %s", got)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	if _, ok := Find(DefaultPlanID); !ok {
+		t.Fatalf("default plan %q should exist", DefaultPlanID)
+	}
+	for _, p := range Catalog() {
+		for key, limit := range p.Limits {
+			if limit.Hard > 0 && limit.Soft >= limit.Hard {
+				t.Errorf("%s %s: soft limit should be below the hard limit", p.ID, key)
+			}
+		}
+	}
+	if _, err := NewAssignment("spc1", "gold", "usr1", time.Now()); err == nil {
+		t.Errorf("unknown plan should be rejected")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	free, _ := Find("free")
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	summary := Summarize("spc1", free, &Usage{
+		Rows:           map[string]int64{"bse1": 120, "bse2": 900},
+		AutomationRuns: 100,
+		APICalls:       1200,
+	}, now)
+
+	if !summary.PeriodStart.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) ||
+		!summary.PeriodEnd.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected period %v - %v", summary.PeriodStart, summary.PeriodEnd)
+	}
+	if len(summary.Bases) != 2 || summary.Bases[0].BaseID != "bse2" || summary.Bases[0].Level != LevelWarning {
+		t.Fatalf("bases should be ordered by rows, got %+v", summary.Bases)
+	}
+
+	levels := map[LimitKey]*LimitStatus{}
+	for _, status := range summary.Limits {
+		levels[status.Key] = status
+	}
+	if rows := levels[LimitRowsPerBase]; rows.Used != 900 || rows.BaseID != "bse2" {
+		t.Errorf("rows limit should report the largest base, got %+v", rows)
+	}
+	if levels[LimitAutomationRuns].Level != LevelWarning {
+		t.Errorf("reaching the hard limit is still allowed, got %s", levels[LimitAutomationRuns].Level)
+	}
+	if levels[LimitAPICalls].Level != LevelExceeded {
+		t.Errorf("api calls over the hard limit should be exceeded, got %s", levels[LimitAPICalls].Level)
+	}
+	if levels[LimitAttachmentBytes].Level != LevelOK {
+		t.Errorf("unused attachments should be ok, got %s", levels[LimitAttachmentBytes].Level)
+	}
+}
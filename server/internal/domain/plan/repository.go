@@ -0,0 +1,11 @@
+package plan
+
+import "context"
+
+// Repository 工作空间套餐仓储接口
+type Repository interface {
+	// FindAssignment 获取工作空间的套餐（未分配时返回 nil）
+	FindAssignment(ctx context.Context, spaceID string) (*Assignment, error)
+	// SaveAssignment 保存工作空间的套餐
+	SaveAssignment(ctx context.Context, assignment *Assignment) error
+}
@@ -0,0 +1,187 @@
+package printjob
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// TargetType 打印目标
+type TargetType string
+
+const (
+	TargetRecord TargetType = "record" // 记录详情页
+	TargetView   TargetType = "view"   // 视图（分页表格）
+)
+
+// Status 打印任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// 纸张与方向
+const (
+	PaperA4     = "a4"
+	PaperLetter = "letter"
+
+	OrientationPortrait  = "portrait"
+	OrientationLandscape = "landscape"
+)
+
+// 任务来源
+const (
+	SourceAPI        = "api"
+	SourceAutomation = "automation"
+)
+
+// maxFileNameRunes 生成文件名（不含扩展名）的最大字符数
+const maxFileNameRunes = 100
+
+// Job 记录或视图的 PDF 打印任务 ✨
+// 后台以创建者的身份（权限、脱敏策略、时区与语言）读取数据并排版为 PDF，
+// 上传到对象存储后通过有时效的下载链接提供给创建者
+type Job struct {
+	ID          string     `json:"id"`
+	TargetType  TargetType `json:"target_type"`
+	TableID     string     `json:"table_id"`
+	ViewID      string     `json:"view_id,omitempty"`   // 视图打印必填；记录打印时决定字段的顺序与可见性
+	RecordID    string     `json:"record_id,omitempty"` // 记录打印必填
+	Paper       string     `json:"paper"`
+	Orientation string     `json:"orientation"`
+	TimeZone    string     `json:"time_zone,omitempty"` // 创建时请求用户的时区，用于日期格式化
+	Locale      string     `json:"locale,omitempty"`
+	Source      string     `json:"source"`
+	Status      Status     `json:"status"`
+	FileName    string     `json:"file_name,omitempty"` // 完成后写入
+	Size        int64      `json:"size,omitempty"`
+	Pages       int        `json:"pages,omitempty"`
+	Rows        int        `json:"rows,omitempty"`      // 视图打印的记录数
+	Truncated   bool       `json:"truncated,omitempty"` // 视图记录数超过上限，只打印了前面的部分
+	Error       string     `json:"error,omitempty"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
+
+// Output 渲染结果
+type Output struct {
+	FileName  string
+	Size      int64
+	Pages     int
+	Rows      int
+	Truncated bool
+}
+
+// NewRecordJob 创建记录详情页打印任务（默认 A4 纵向）
+func NewRecordJob(tableID, viewID, recordID, createdBy string, now time.Time) *Job {
+	job := newJob(TargetRecord, tableID, viewID, createdBy, now)
+	job.RecordID = recordID
+	job.Orientation = OrientationPortrait
+	return job
+}
+
+// NewViewJob 创建视图打印任务（默认 A4 横向）
+func NewViewJob(tableID, viewID, createdBy string, now time.Time) *Job {
+	job := newJob(TargetView, tableID, viewID, createdBy, now)
+	job.Orientation = OrientationLandscape
+	return job
+}
+
+func newJob(target TargetType, tableID, viewID, createdBy string, now time.Time) *Job {
+	return &Job{
+		ID:         utils.GenerateIDWithPrefix("prt"),
+		TargetType: target,
+		TableID:    tableID,
+		ViewID:     viewID,
+		Paper:      PaperA4,
+		Source:     SourceAPI,
+		Status:     StatusPending,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Validate 校验打印目标与页面设置
+func (j *Job) Validate() error {
+	if j.TableID == "" {
+		return fmt.Errorf("缺少表格")
+	}
+	switch j.TargetType {
+	case TargetRecord:
+		if j.RecordID == "" {
+			return fmt.Errorf("缺少要打印的记录")
+		}
+	case TargetView:
+		if j.ViewID == "" {
+			return fmt.Errorf("缺少要打印的视图")
+		}
+	default:
+		return fmt.Errorf("不支持的打印目标 %q", j.TargetType)
+	}
+	if j.Paper != PaperA4 && j.Paper != PaperLetter {
+		return fmt.Errorf("不支持的纸张 %q（可选 %s、%s）", j.Paper, PaperA4, PaperLetter)
+	}
+	if j.Orientation != OrientationPortrait && j.Orientation != OrientationLandscape {
+		return fmt.Errorf("不支持的页面方向 %q（可选 %s、%s）", j.Orientation, OrientationPortrait, OrientationLandscape)
+	}
+	return nil
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusCompleted || j.Status == StatusFailed
+}
+
+// Finish 结束打印任务：成功时保存文件信息
+func (j *Job) Finish(output *Output, err error, now time.Time) {
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+	if err != nil {
+		j.Status = StatusFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = StatusCompleted
+	j.Error = ""
+	j.FileName = output.FileName
+	j.Size = output.Size
+	j.Pages = output.Pages
+	j.Rows = output.Rows
+	j.Truncated = output.Truncated
+}
+
+// FilePath PDF 文件在对象存储中的路径
+func (j *Job) FilePath() string {
+	return "prints/" + j.TableID + "/" + j.ID + ".pdf"
+}
+
+// FileName 由标题生成下载文件名：去掉路径分隔符与控制字符，过长时截断，标题为空时使用任务ID
+func FileName(title, fallback string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r):
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, title)
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxFileNameRunes {
+		name = strings.TrimSpace(string(runes[:maxFileNameRunes]))
+	}
+	if name == "" || strings.Trim(name, ".") == "" {
+		name = fallback
+	}
+	return name + ".pdf"
+}
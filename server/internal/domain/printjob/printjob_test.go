@@ -0,0 +1,71 @@
+package printjob
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobValidate(t *testing.T) {
+	now := time.Now()
+	record := NewRecordJob("tbl1", "", "rec1", "usr1", now)
+	if err := record.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if !strings.HasPrefix(record.ID, "prt") || record.Status != StatusPending || record.Orientation != OrientationPortrait {
+		t.Errorf("记录打印任务 = %+v", record)
+	}
+	if got := record.FilePath(); got != "prints/tbl1/"+record.ID+".pdf" {
+		t.Errorf("文件路径 = %s", got)
+	}
+	if view := NewViewJob("tbl1", "viw1", "usr1", now); view.Validate() != nil || view.Orientation != OrientationLandscape {
+		t.Errorf("视图打印任务 = %+v", view)
+	}
+
+	invalid := []*Job{
+		NewRecordJob("tbl1", "viw1", "", "usr1", now),
+		NewViewJob("tbl1", "", "usr1", now),
+		NewRecordJob("", "", "rec1", "usr1", now),
+	}
+	paper := NewRecordJob("tbl1", "", "rec1", "usr1", now)
+	paper.Paper = "a3"
+	orientation := NewViewJob("tbl1", "viw1", "usr1", now)
+	orientation.Orientation = "diagonal"
+	invalid = append(invalid, paper, orientation)
+	for i, job := range invalid {
+		if job.Validate() == nil {
+			t.Errorf("第 %d 个任务应校验失败", i)
+		}
+	}
+}
+
+func TestJobFinish(t *testing.T) {
+	now := time.Now()
+	job := NewViewJob("tbl1", "viw1", "usr1", now)
+	job.Finish(&Output{FileName: "订单.pdf", Size: 1024, Pages: 3, Rows: 120, Truncated: true}, nil, now)
+	if job.Status != StatusCompleted || job.Pages != 3 || job.Rows != 120 || !job.Truncated || !job.Finished() {
+		t.Errorf("完成后的任务 = %+v", job)
+	}
+
+	failed := NewRecordJob("tbl1", "", "rec1", "usr1", now)
+	failed.Finish(nil, errors.New("记录不存在"), now)
+	if failed.Status != StatusFailed || failed.Error != "记录不存在" || failed.FinishedAt == nil {
+		t.Errorf("失败的任务 = %+v", failed)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	cases := map[string]string{
+		"发票 INV-001":             "发票 INV-001.pdf",
+		"a/b\\c:d\n":             "a_b_c_d.pdf",
+		"  ":                     "prt1.pdf",
+		"..":                     "prt1.pdf",
+		strings.Repeat("长", 120): strings.Repeat("长", maxFileNameRunes) + ".pdf",
+	}
+	for title, want := range cases {
+		if got := FileName(title, "prt1"); got != want {
+			t.Errorf("FileName(%q) = %q，期望 %q", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package printjob
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 打印任务仓储接口
+type Repository interface {
+	// Save 创建或更新打印任务
+	Save(ctx context.Context, job *Job) error
+	// FindByID 获取打印任务（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Job, error)
+	// ListByTable 按创建时间倒序列出用户在表中创建的打印任务
+	ListByTable(ctx context.Context, tableID, createdBy string, limit int) ([]*Job, error)
+	// Claim 领取待执行或已中断（running 且 staleBefore 之前未更新）的任务并标记为执行中
+	Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Job, error)
+}
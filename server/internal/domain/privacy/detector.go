@@ -0,0 +1,105 @@
+package privacy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// 中国大陆手机号或带国际区号的号码
+	phonePattern = regexp.MustCompile(`(?:^|[^\d])(1[3-9]\d{9}|\+\d{1,3}[\s\-]?\d{3,4}[\s\-]?\d{3,4}[\s\-]?\d{0,4})(?:$|[^\d])`)
+	// 18位居民身份证号
+	idNumberPattern = regexp.MustCompile(`(?:^|[^\d])(\d{6}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx])(?:$|[^\d])`)
+	// 13-19位卡号（允许空格/短横线分隔），需通过Luhn校验
+	cardPattern = regexp.MustCompile(`(?:\d[\s\-]?){12,18}\d`)
+)
+
+// DetectPII 检测单元格值中可能包含的PII类型
+func DetectPII(value interface{}) []PIIType {
+	text := cellText(value)
+	if text == "" {
+		return nil
+	}
+
+	var found []PIIType
+	if emailPattern.MatchString(text) {
+		found = append(found, PIITypeEmail)
+	}
+	if m := idNumberPattern.FindStringSubmatch(text); m != nil && validIDChecksum(m[1]) {
+		found = append(found, PIITypeIDNumber)
+	} else if phonePattern.MatchString(text) {
+		found = append(found, PIITypePhone)
+	} else if hasCardNumber(text) {
+		found = append(found, PIITypeCreditCard)
+	}
+	return found
+}
+
+// cellText 将单元格值转换为待扫描文本
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, cellText(item))
+		}
+		return strings.Join(parts, " ")
+	case map[string]interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, cellText(item))
+		}
+		return strings.Join(parts, " ")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// hasCardNumber 是否包含通过Luhn校验的卡号
+func hasCardNumber(text string) bool {
+	for _, candidate := range cardPattern.FindAllString(text, -1) {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(candidate)
+		if len(digits) >= 13 && len(digits) <= 19 && luhnValid(digits) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid Luhn 校验
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIDChecksum 身份证号校验位（GB 11643-1999）
+func validIDChecksum(id string) bool {
+	if len(id) != 18 {
+		return false
+	}
+	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	checks := "10X98765432"
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(id[i]-'0') * weights[i]
+	}
+	return strings.ToUpper(id[17:]) == string(checks[sum%11])
+}
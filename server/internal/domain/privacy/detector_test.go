@@ -0,0 +1,64 @@
+package privacy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectPII(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		want  []PIIType
+	}{
+		{"email", "联系 alice.w@example.com", []PIIType{PIITypeEmail}},
+		{"mobile", "电话 13812345678", []PIIType{PIITypePhone}},
+		{"international", "+86 138 1234 5678", []PIIType{PIITypePhone}},
+		{"id number", "11010519491231002X", []PIIType{PIITypeIDNumber}},
+		{"card", "4111 1111 1111 1111", []PIIType{PIITypeCreditCard}},
+		{"card fails luhn", "4111 1111 1111 1112", nil},
+		{"plain text", "hello world", nil},
+		{"list", []interface{}{"x", "bob@example.org"}, []PIIType{PIITypeEmail}},
+		{"nil", nil, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DetectPII(tc.value)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("DetectPII(%v) = %v, want %v", tc.value, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		strategy MaskStrategy
+		want     interface{}
+	}{
+		{"alice@example.com", MaskStrategyEmail, "a***@example.com"},
+		{"4111111111111111", MaskStrategyLast4, "************1111"},
+		{"13812345678", MaskStrategyPartial, "13*******78"},
+		{"secret", MaskStrategyFull, MaskedPlaceholder},
+		{nil, MaskStrategyFull, nil},
+		{[]interface{}{"ab@c.io"}, MaskStrategyEmail, []interface{}{"a***@c.io"}},
+	}
+
+	for _, tc := range cases {
+		if got := MaskValue(tc.value, tc.strategy); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("MaskValue(%v, %s) = %v, want %v", tc.value, tc.strategy, got, tc.want)
+		}
+	}
+}
+
+func TestMaskingPolicyIsExempt(t *testing.T) {
+	policy := NewMaskingPolicy("tbl", "fld", MaskStrategyFull, nil, "usr")
+	if !policy.IsExempt("owner") {
+		t.Fatalf("owner should be exempt by default")
+	}
+	if policy.IsExempt("viewer") || policy.IsExempt("") {
+		t.Fatalf("viewer and anonymous should not be exempt")
+	}
+}
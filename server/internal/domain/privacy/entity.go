@@ -0,0 +1,94 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// PIIType 个人敏感信息类型
+type PIIType string
+
+const (
+	PIITypeEmail      PIIType = "email"
+	PIITypePhone      PIIType = "phone"
+	PIITypeIDNumber   PIIType = "id_number"   // 身份证号
+	PIITypeCreditCard PIIType = "credit_card" // 银行卡号
+)
+
+// MaskStrategy 脱敏方式
+type MaskStrategy string
+
+const (
+	MaskStrategyFull    MaskStrategy = "full"    // 全部替换
+	MaskStrategyPartial MaskStrategy = "partial" // 保留首尾
+	MaskStrategyEmail   MaskStrategy = "email"   // 保留邮箱首字母与域名
+	MaskStrategyLast4   MaskStrategy = "last4"   // 仅保留后4位
+)
+
+// DefaultExemptRoles 默认可查看明文的角色
+var DefaultExemptRoles = []string{"owner"}
+
+// MaskingPolicy 字段脱敏策略
+// 角色不在 ExemptRoles 中的用户读取、导出和实时推送时看到的都是脱敏值
+type MaskingPolicy struct {
+	ID          string       `json:"id"`
+	TableID     string       `json:"table_id"`
+	FieldID     string       `json:"field_id"`
+	PIIType     PIIType      `json:"pii_type,omitempty"`
+	Strategy    MaskStrategy `json:"strategy"`
+	ExemptRoles []string     `json:"exempt_roles"`
+	CreatedBy   string       `json:"created_by"`
+	CreatedTime time.Time    `json:"created_time"`
+	UpdatedTime time.Time    `json:"updated_time"`
+}
+
+// NewMaskingPolicy 创建字段脱敏策略
+func NewMaskingPolicy(tableID, fieldID string, strategy MaskStrategy, exemptRoles []string, createdBy string) *MaskingPolicy {
+	if exemptRoles == nil {
+		exemptRoles = append([]string{}, DefaultExemptRoles...)
+	}
+	now := time.Now()
+	return &MaskingPolicy{
+		ID:          utils.GenerateIDWithPrefix("mkp"),
+		TableID:     tableID,
+		FieldID:     fieldID,
+		Strategy:    strategy,
+		ExemptRoles: exemptRoles,
+		CreatedBy:   createdBy,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+}
+
+// IsExempt 角色是否可查看明文
+func (p *MaskingPolicy) IsExempt(role string) bool {
+	if role == "" {
+		return false
+	}
+	for _, r := range p.ExemptRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// PIIFinding 字段PII扫描结果
+type PIIFinding struct {
+	TableID     string    `json:"table_id"`
+	FieldID     string    `json:"field_id"`
+	PIIType     PIIType   `json:"pii_type"`
+	MatchCount  int       `json:"match_count"`
+	SampleSize  int       `json:"sample_size"`
+	RecordIDs   []string  `json:"record_ids"` // 命中的记录（最多保留若干条）
+	ScannedTime time.Time `json:"scanned_time"`
+}
+
+// Ratio 命中比例
+func (f *PIIFinding) Ratio() float64 {
+	if f.SampleSize == 0 {
+		return 0
+	}
+	return float64(f.MatchCount) / float64(f.SampleSize)
+}
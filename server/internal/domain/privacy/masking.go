@@ -0,0 +1,80 @@
+package privacy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaskedPlaceholder 全量脱敏占位值
+const MaskedPlaceholder = "******"
+
+// MaskValue 按策略对单元格值脱敏（nil 保持为 nil）
+func MaskValue(value interface{}, strategy MaskStrategy) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = MaskValue(item, strategy)
+		}
+		return masked
+	case string:
+		return maskString(v, strategy)
+	default:
+		return maskString(fmt.Sprintf("%v", v), strategy)
+	}
+}
+
+func maskString(s string, strategy MaskStrategy) string {
+	runes := []rune(s)
+	switch strategy {
+	case MaskStrategyEmail:
+		at := strings.LastIndex(s, "@")
+		if at <= 0 {
+			return MaskedPlaceholder
+		}
+		local := []rune(s[:at])
+		return string(local[0]) + "***" + s[at:]
+	case MaskStrategyLast4:
+		if len(runes) <= 4 {
+			return MaskedPlaceholder
+		}
+		return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+	case MaskStrategyPartial:
+		if len(runes) <= 2 {
+			return MaskedPlaceholder
+		}
+		keep := len(runes) / 4
+		if keep < 1 {
+			keep = 1
+		}
+		return string(runes[:keep]) + strings.Repeat("*", len(runes)-2*keep) + string(runes[len(runes)-keep:])
+	default:
+		return MaskedPlaceholder
+	}
+}
+
+// DefaultStrategyFor PII类型对应的默认脱敏方式
+func DefaultStrategyFor(piiType PIIType) MaskStrategy {
+	switch piiType {
+	case PIITypeEmail:
+		return MaskStrategyEmail
+	case PIITypePhone, PIITypeIDNumber:
+		return MaskStrategyPartial
+	case PIITypeCreditCard:
+		return MaskStrategyLast4
+	default:
+		return MaskStrategyFull
+	}
+}
+
+// IsValidStrategy 是否为支持的脱敏方式
+func IsValidStrategy(strategy MaskStrategy) bool {
+	switch strategy {
+	case MaskStrategyFull, MaskStrategyPartial, MaskStrategyEmail, MaskStrategyLast4:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package privacy
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 隐私策略仓储接口
+type Repository interface {
+	// SavePolicy 保存字段脱敏策略（同一字段只保留一条）
+	SavePolicy(ctx context.Context, policy *MaskingPolicy) error
+	// GetPolicy 获取字段脱敏策略（不存在时返回 nil）
+	GetPolicy(ctx context.Context, tableID, fieldID string) (*MaskingPolicy, error)
+	// ListPolicies 列出表的脱敏策略
+	ListPolicies(ctx context.Context, tableID string) ([]*MaskingPolicy, error)
+	// DeletePolicy 删除字段脱敏策略
+	DeletePolicy(ctx context.Context, tableID, fieldID string) error
+	// ReplaceFindings 替换表的PII扫描结果
+	ReplaceFindings(ctx context.Context, tableID string, findings []*PIIFinding) error
+	// ListFindings 列出表的PII扫描结果
+	ListFindings(ctx context.Context, tableID string) ([]*PIIFinding, error)
+
+	// SaveErasureJob 保存数据擦除任务（新增或更新）
+	SaveErasureJob(ctx context.Context, job *ErasureJob) error
+	// GetErasureJob 获取数据擦除任务（不存在时返回 nil）
+	GetErasureJob(ctx context.Context, jobID string) (*ErasureJob, error)
+	// ListErasureJobs 列出空间的数据擦除任务
+	ListErasureJobs(ctx context.Context, spaceID string) ([]*ErasureJob, error)
+	// ClaimErasureJobs 领取未结束且租约已过期的擦除任务（中断后继续执行），并设置新的租约
+	ClaimErasureJobs(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*ErasureJob, error)
+}
+
+// SubjectDataStore 数据主体相关数据的定位与改写
+// 需要跨空间内所有动态数据表查询，由基础设施层直接访问物理表实现
+type SubjectDataStore interface {
+	// ResolveSubject 补全数据主体的用户ID/邮箱
+	ResolveSubject(ctx context.Context, subject Subject) (Subject, error)
+	// FindReferences 查找空间内引用数据主体的记录、评论和附件
+	// 任一数据表查询失败时返回错误，避免把没有查到的数据当作已不存在
+	FindReferences(ctx context.Context, spaceID string, subject Subject) ([]SubjectReference, error)
+	// AnonymizeRecordAuthorship 将记录的创建人/修改人替换为匿名用户
+	AnonymizeRecordAuthorship(ctx context.Context, ref SubjectReference, userID string) error
+	// GetComment 获取评论内容
+	GetComment(ctx context.Context, commentID string) (map[string]interface{}, error)
+	// AnonymizeComment 匿名化评论（作者替换为匿名用户并脱敏内容）
+	AnonymizeComment(ctx context.Context, commentID string, subject Subject) error
+	// DeleteComment 删除评论
+	DeleteComment(ctx context.Context, commentID string) error
+	// GetAttachment 获取附件元数据
+	GetAttachment(ctx context.Context, attachmentID string) (map[string]interface{}, error)
+	// AnonymizeAttachment 将附件上传人替换为匿名用户
+	AnonymizeAttachment(ctx context.Context, attachmentID string) error
+	// DeleteAttachment 删除附件元数据（返回需要清理的存储路径）
+	DeleteAttachment(ctx context.Context, attachmentID string) ([]string, error)
+}
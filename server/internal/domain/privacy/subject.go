@@ -0,0 +1,310 @@
+package privacy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// AnonymousUserID 匿名化后替换的用户ID
+	AnonymousUserID = "anonymous"
+	// RedactedPlaceholder 匿名化后替换的文本
+	RedactedPlaceholder = "[redacted]"
+)
+
+// Subject 数据主体（GDPR 请求针对的用户）
+// UserID 与 Email 至少提供一个，服务层会尽量补全另一个
+type Subject struct {
+	UserID string `json:"user_id,omitempty"`
+	Email  string `json:"email,omitempty"`
+}
+
+// IsEmpty 是否未指定任何标识
+func (s Subject) IsEmpty() bool {
+	return s.UserID == "" && s.Email == ""
+}
+
+// Terms 用于匹配的标识列表
+func (s Subject) Terms() []string {
+	terms := make([]string, 0, 2)
+	if s.UserID != "" {
+		terms = append(terms, s.UserID)
+	}
+	if s.Email != "" {
+		terms = append(terms, s.Email)
+	}
+	return terms
+}
+
+// ReferenceKind 引用数据类型
+type ReferenceKind string
+
+const (
+	ReferenceRecord     ReferenceKind = "record"
+	ReferenceComment    ReferenceKind = "comment"
+	ReferenceAttachment ReferenceKind = "attachment"
+)
+
+// SubjectReference 引用数据主体的一条数据
+// Owned 为 true 表示数据由主体创建（删除模式下会被删除），否则仅是内容中提及主体（只做脱敏）
+type SubjectReference struct {
+	Kind     ReferenceKind `json:"kind"`
+	ID       string        `json:"id"`
+	BaseID   string        `json:"base_id"`
+	TableID  string        `json:"table_id"`
+	RecordID string        `json:"record_id,omitempty"`
+	Owned    bool          `json:"owned"`
+}
+
+// Key 引用的唯一标识（用于报告摘要）
+func (r SubjectReference) Key() string {
+	return fmt.Sprintf("%s:%s:%s", r.Kind, r.TableID, r.ID)
+}
+
+// ErasureMode 擦除方式
+type ErasureMode string
+
+const (
+	ErasureModeAnonymize ErasureMode = "anonymize" // 全部匿名化
+	ErasureModeDelete    ErasureMode = "delete"    // 删除主体创建的数据，其余匿名化
+)
+
+// IsValidErasureMode 是否为支持的擦除方式
+func IsValidErasureMode(mode ErasureMode) bool {
+	return mode == ErasureModeAnonymize || mode == ErasureModeDelete
+}
+
+// ErasureStatus 擦除任务状态
+type ErasureStatus string
+
+const (
+	ErasureStatusPending   ErasureStatus = "pending"
+	ErasureStatusRunning   ErasureStatus = "running"
+	ErasureStatusCompleted ErasureStatus = "completed"
+	ErasureStatusFailed    ErasureStatus = "failed"
+)
+
+// ErasureAction 对单条数据执行的操作
+type ErasureAction string
+
+const (
+	ErasureActionDeleted    ErasureAction = "deleted"
+	ErasureActionAnonymized ErasureAction = "anonymized"
+)
+
+// ErasureItem 擦除报告中的单条记录
+type ErasureItem struct {
+	SubjectReference
+	Action ErasureAction `json:"action"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// ErasureCounts 各类数据的处理数量
+type ErasureCounts struct {
+	Deleted    int `json:"deleted"`
+	Anonymized int `json:"anonymized"`
+	Failed     int `json:"failed"`
+}
+
+// ErasureVerification 擦除完成后的复查结果
+type ErasureVerification struct {
+	Remaining  int       `json:"remaining"`
+	Verified   bool      `json:"verified"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
+
+// ErasureReport 擦除报告
+// Digest 为所有处理项（按 Key 排序）以服务端密钥计算的 HMAC-SHA256，
+// 能访问数据库的人无法在修改报告后重新计算出有效摘要
+type ErasureReport struct {
+	Counts       map[ReferenceKind]*ErasureCounts `json:"counts"`
+	Items        []ErasureItem                    `json:"items"`
+	Digest       string                           `json:"digest"`
+	Verification *ErasureVerification             `json:"verification,omitempty"`
+}
+
+// NewErasureReport 创建空报告
+func NewErasureReport() *ErasureReport {
+	return &ErasureReport{
+		Counts: map[ReferenceKind]*ErasureCounts{
+			ReferenceRecord:     {},
+			ReferenceComment:    {},
+			ReferenceAttachment: {},
+		},
+		Items: []ErasureItem{},
+	}
+}
+
+// Add 记录一条处理结果
+// 任务中断后继续执行时，同一条数据的新结果替换之前的结果（例如上次失败、本次重试成功）
+func (r *ErasureReport) Add(ref SubjectReference, action ErasureAction, err error) {
+	item := ErasureItem{SubjectReference: ref, Action: action}
+	if err != nil {
+		item.Error = err.Error()
+	}
+	for i := range r.Items {
+		if r.Items[i].Key() == ref.Key() {
+			r.count(r.Items[i], -1)
+			r.Items[i] = item
+			r.count(item, 1)
+			return
+		}
+	}
+	r.Items = append(r.Items, item)
+	r.count(item, 1)
+}
+
+// count 调整处理项所属类型的计数
+func (r *ErasureReport) count(item ErasureItem, delta int) {
+	counts := r.Counts[item.Kind]
+	if counts == nil {
+		counts = &ErasureCounts{}
+		r.Counts[item.Kind] = counts
+	}
+	switch {
+	case item.Error != "":
+		counts.Failed += delta
+	case item.Action == ErasureActionDeleted:
+		counts.Deleted += delta
+	default:
+		counts.Anonymized += delta
+	}
+}
+
+// Seal 以服务端密钥计算报告摘要
+func (r *ErasureReport) Seal(key []byte) {
+	r.Digest = ComputeErasureDigest(key, r.Items)
+}
+
+// ComputeErasureDigest 计算处理项摘要（与顺序无关）
+func ComputeErasureDigest(key []byte, items []ErasureItem) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("%s|%s|%s", item.Key(), item.Action, item.Error))
+	}
+	sort.Strings(lines)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyErasureDigest 校验报告摘要（常量时间比较）
+func VerifyErasureDigest(key []byte, report *ErasureReport) bool {
+	return hmac.Equal([]byte(ComputeErasureDigest(key, report.Items)), []byte(report.Digest))
+}
+
+// ErasureJob 数据擦除任务
+type ErasureJob struct {
+	ID           string         `json:"id"`
+	SpaceID      string         `json:"space_id"`
+	Subject      Subject        `json:"subject"`
+	Mode         ErasureMode    `json:"mode"`
+	Status       ErasureStatus  `json:"status"`
+	Report       *ErasureReport `json:"report,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RequestedBy  string         `json:"requested_by"`
+	CreatedTime  time.Time      `json:"created_time"`
+	FinishedTime *time.Time     `json:"finished_time,omitempty"`
+	// LockedUntil 执行租约：执行中的实例定期续约，实例重启或退出后租约过期，由其他实例继续执行
+	LockedUntil *time.Time `json:"-"`
+}
+
+// NewErasureJob 创建擦除任务
+func NewErasureJob(spaceID string, subject Subject, mode ErasureMode, requestedBy string) *ErasureJob {
+	return &ErasureJob{
+		ID:          utils.GenerateIDWithPrefix("gdj"),
+		SpaceID:     spaceID,
+		Subject:     subject,
+		Mode:        mode,
+		Status:      ErasureStatusPending,
+		RequestedBy: requestedBy,
+		CreatedTime: time.Now(),
+	}
+}
+
+// Finish 结束任务（释放执行租约）
+func (j *ErasureJob) Finish(report *ErasureReport, err error) {
+	now := time.Now()
+	j.FinishedTime = &now
+	j.LockedUntil = nil
+	j.Report = report
+	if err != nil {
+		j.Status = ErasureStatusFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = ErasureStatusCompleted
+}
+
+// RedactValue 递归替换单元格中指向数据主体的内容
+// 字符串中出现的邮箱/用户ID替换为占位符；用户对象（含 id 字段）整体替换为匿名用户
+func RedactValue(value interface{}, subject Subject) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		redacted := v
+		for _, term := range subject.Terms() {
+			redacted = replaceFold(redacted, term, RedactedPlaceholder)
+		}
+		return redacted, redacted != v
+	case map[string]interface{}:
+		if id, ok := v["id"].(string); ok && subject.UserID != "" && id == subject.UserID {
+			return map[string]interface{}{"id": AnonymousUserID, "title": RedactedPlaceholder}, true
+		}
+		changed := false
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			redacted, c := RedactValue(item, subject)
+			out[k] = redacted
+			changed = changed || c
+		}
+		return out, changed
+	case []interface{}:
+		changed := false
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted, c := RedactValue(item, subject)
+			out[i] = redacted
+			changed = changed || c
+		}
+		return out, changed
+	default:
+		return value, false
+	}
+}
+
+// replaceFold 不区分大小写替换
+func replaceFold(s, old, replacement string) string {
+	if old == "" {
+		return s
+	}
+	lowerS, lowerOld := strings.ToLower(s), strings.ToLower(old)
+	if len(lowerS) != len(s) || len(lowerOld) != len(old) {
+		// 大小写转换改变了字节长度时无法按下标对齐，退化为精确替换
+		return strings.ReplaceAll(s, old, replacement)
+	}
+	if !strings.Contains(lowerS, lowerOld) {
+		return s
+	}
+
+	var b strings.Builder
+	start := 0
+	for {
+		idx := strings.Index(lowerS[start:], lowerOld)
+		if idx < 0 {
+			break
+		}
+		b.WriteString(s[start : start+idx])
+		b.WriteString(replacement)
+		start += idx + len(old)
+	}
+	b.WriteString(s[start:])
+	return b.String()
+}
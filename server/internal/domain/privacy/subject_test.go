@@ -0,0 +1,88 @@
+package privacy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRedactValue(t *testing.T) {
+	subject := Subject{UserID: "usr123", Email: "Alice@Example.com"}
+
+	cases := []struct {
+		name    string
+		value   interface{}
+		want    interface{}
+		changed bool
+	}{
+		{"email case insensitive", "联系 alice@example.COM 获取", "联系 [redacted] 获取", true},
+		{"user id", "assigned to usr123", "assigned to [redacted]", true},
+		{"untouched", "hello", "hello", false},
+		{"number", 42, 42, false},
+		{
+			"user object",
+			map[string]interface{}{"id": "usr123", "title": "Alice"},
+			map[string]interface{}{"id": AnonymousUserID, "title": RedactedPlaceholder},
+			true,
+		},
+		{
+			"nested list",
+			[]interface{}{"x", map[string]interface{}{"id": "usr999", "email": "alice@example.com"}},
+			[]interface{}{"x", map[string]interface{}{"id": "usr999", "email": RedactedPlaceholder}},
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := RedactValue(tc.value, subject)
+			if changed != tc.changed || !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("RedactValue(%v) = (%v, %v), want (%v, %v)", tc.value, got, changed, tc.want, tc.changed)
+			}
+		})
+	}
+}
+
+func TestErasureReportDigest(t *testing.T) {
+	a := SubjectReference{Kind: ReferenceRecord, ID: "rec1", TableID: "tbl1", RecordID: "rec1", Owned: true}
+	b := SubjectReference{Kind: ReferenceComment, ID: "com1", TableID: "tbl1", RecordID: "rec1"}
+	key := []byte("server-key")
+
+	report := NewErasureReport()
+	report.Add(a, ErasureActionDeleted, nil)
+	report.Add(b, ErasureActionAnonymized, errors.New("boom"))
+	report.Seal(key)
+
+	if report.Counts[ReferenceRecord].Deleted != 1 || report.Counts[ReferenceComment].Failed != 1 {
+		t.Fatalf("unexpected counts: %+v %+v", report.Counts[ReferenceRecord], report.Counts[ReferenceComment])
+	}
+
+	reversed := []ErasureItem{report.Items[1], report.Items[0]}
+	if ComputeErasureDigest(key, reversed) != report.Digest {
+		t.Fatal("digest should not depend on item order")
+	}
+	if !VerifyErasureDigest(key, report) {
+		t.Fatal("sealed report should verify")
+	}
+	if ComputeErasureDigest([]byte("other-key"), report.Items) == report.Digest {
+		t.Fatal("digest should depend on the server key")
+	}
+
+	report.Items[0].Action = ErasureActionAnonymized
+	if VerifyErasureDigest(key, report) {
+		t.Fatal("digest should change when an item is altered")
+	}
+}
+
+func TestErasureReportAddReplacesRetriedItem(t *testing.T) {
+	ref := SubjectReference{Kind: ReferenceComment, ID: "com1", TableID: "tbl1"}
+
+	report := NewErasureReport()
+	report.Add(ref, ErasureActionAnonymized, errors.New("timeout"))
+	report.Add(ref, ErasureActionAnonymized, nil)
+
+	counts := report.Counts[ReferenceComment]
+	if len(report.Items) != 1 || report.Items[0].Error != "" || counts.Failed != 0 || counts.Anonymized != 1 {
+		t.Fatalf("retried item should replace the failed one: %+v %+v", report.Items, counts)
+	}
+}
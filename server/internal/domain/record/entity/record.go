@@ -102,6 +102,17 @@ func (r *Record) GetFieldValue(fieldName string) (interface{}, bool) {
 	return r.data.Get(fieldName)
 }
 
+// Project 返回只包含指定字段值的副本（字段投影，仅用于读取，不能再保存）
+func (r *Record) Project(fieldIDs []string) (*Record, error) {
+	data, err := r.data.Project(fieldIDs)
+	if err != nil {
+		return nil, err
+	}
+	projected := *r
+	projected.data = data
+	return &projected, nil
+}
+
 // ==================== 业务方法 ====================
 
 // Update 更新记录数据
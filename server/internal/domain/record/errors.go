@@ -27,6 +27,9 @@ var (
 	// 批量操作错误
 	ErrBatchOperationFailed = errors.New("batch operation failed")
 	ErrPartialSuccess       = errors.New("partial success in batch operation")
+
+	// 快照读取错误
+	ErrSnapshotTooOld = errors.New("snapshot is too old to reconstruct from change log")
 )
 
 // DomainError 领域错误类型
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
@@ -18,7 +19,8 @@ type RecordRepository interface {
 
 	// FindByIDs 根据ID列表查找记录（需要提供 tableID）
 	// ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
-	FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID) ([]*entity.Record, error)
+	// fieldIDs 非空时只读取这些字段的列（字段投影，宽表只取需要的单元格），投影后的记录不能再保存
+	FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID, fieldIDs ...string) ([]*entity.Record, error)
 
 	// FindByTableAndID 根据表ID和记录ID查找记录
 	// ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
@@ -34,6 +36,9 @@ type RecordRepository interface {
 	// ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
 	DeleteByTableAndID(ctx context.Context, tableID string, id valueobject.RecordID) error
 
+	// DeleteByTableAndIDWithVersion 仅在记录当前版本为 version 时删除（乐观锁），版本不一致时返回 ErrVersionConflict
+	DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) error
+
 	// Exists 检查记录是否存在
 	Exists(ctx context.Context, id valueobject.RecordID) (bool, error)
 
@@ -63,8 +68,87 @@ type RecordFilter struct {
 	UpdatedBy    *string
 	IsDeleted    *bool
 	FieldFilters map[string]interface{} // 字段过滤条件
+	FieldIDs     []string               // 字段投影：只读取这些字段的列（为空时读取全部字段）
 	OrderBy      string                 // created_at, updated_at, field_name
 	OrderDir     string                 // asc, desc
 	Limit        int
 	Offset       int
 }
+
+// SnapshotPage 按时间点读取的一页记录（按自增序号升序）
+type SnapshotPage struct {
+	Records    []*entity.Record
+	Total      int64 // 快照时间点的记录总数
+	NextCursor int64 // 下一页游标（最后一条记录的自增序号），没有更多数据时为 0
+}
+
+// RecordStateAsOf 记录在某一时间点的状态
+type RecordStateAsOf struct {
+	RecordID string
+	Record   *entity.Record // 时间点上的记录，当时尚未创建时为 nil
+}
+
+// SnapshotReader 按时间点读取表格记录 ✨
+// 基于记录变更日志：当前数据中排除 asOf 之后变更过的记录，
+// 再补回这些记录在 asOf 之后首次变更前的状态
+type SnapshotReader interface {
+	// ListAsOf 读取 asOf 时刻的记录，after 为上一页游标；
+	// asOf 之后变更的记录数超过 maxChanges 时返回 record.ErrSnapshotTooOld
+	ListAsOf(ctx context.Context, tableID string, asOf time.Time, after int64, limit, maxChanges int) (*SnapshotPage, error)
+
+	// StatesAsOf 读取 asOf 之后变更过的记录在 asOf 时刻的状态（按记录ID升序），recordIDs 非空时只读取这些记录；
+	// asOf 之后未变更的记录不返回（当前状态即 asOf 时刻的状态），变更的记录数超过 maxChanges 时返回 record.ErrSnapshotTooOld
+	StatesAsOf(ctx context.Context, tableID string, recordIDs []string, asOf time.Time, maxChanges int) ([]*RecordStateAsOf, error)
+
+	// PurgeChanges 删除 before 之前的变更日志，返回删除条数
+	PurgeChanges(ctx context.Context, before time.Time) (int64, error)
+}
+
+// CellChange 单元格的一次修改（记录变更日志按单元格投影的结果，加密字段的值已解密）
+type CellChange struct {
+	ID        string
+	TableID   string
+	RecordID  string
+	FieldID   string
+	OldValue  interface{}
+	NewValue  interface{}
+	Version   int64 // 修改后的记录版本
+	ChangedBy string
+	ChangedAt time.Time
+}
+
+// CellHistoryReader 单元格修改历史 ✨
+// 记录更新时与变更日志在同一事务中写入逐单元格的修改，按 (表, 记录, 字段, 版本) 建索引，
+// 查询单个单元格的历史无需回放整条记录的变更日志
+type CellHistoryReader interface {
+	// ListCellHistory 按版本倒序列出单元格的修改，beforeVersion > 0 时只返回更早的版本
+	ListCellHistory(ctx context.Context, tableID, recordID, fieldID string, beforeVersion int64, limit int) ([]*CellChange, error)
+
+	// PurgeCellHistory 删除 before 之前的单元格修改，返回删除条数
+	PurgeCellHistory(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ArchiveCriteria 归档条件
+type ArchiveCriteria struct {
+	Before       time.Time // 依据时间早于该时间的记录
+	LastModified bool      // 按最后修改时间（从未修改过的按创建时间），否则按创建时间
+	Limit        int       // 本批最多归档的记录数（最早的优先）
+	ArchivedBy   string
+}
+
+// ArchiveStore 记录归档（冷存储）✨
+// 归档的记录连同物理行（加密字段保留密文）移入归档表并从物理表删除，默认查询不再返回；
+// 可显式读取归档记录，或原样恢复到物理表（恢复时丢弃已删除字段的列）
+type ArchiveStore interface {
+	// Archive 按条件将一批记录移入归档，返回移入的记录ID
+	Archive(ctx context.Context, tableID string, criteria ArchiveCriteria) ([]string, error)
+
+	// ListArchived 按归档时间倒序分页读取归档记录，返回本页记录与归档总数
+	ListArchived(ctx context.Context, tableID string, offset, limit int) ([]*entity.Record, int64, error)
+
+	// CountArchived 统计表的归档记录数
+	CountArchived(ctx context.Context, tableID string) (int64, error)
+
+	// Restore 将归档记录恢复到表中，返回恢复的记录ID（不在归档中的ID忽略）
+	Restore(ctx context.Context, tableID string, recordIDs []string) ([]string, error)
+}
@@ -130,6 +130,18 @@ func (rd RecordData) Merge(other RecordData) (RecordData, error) {
 	return NewRecordData(newValues)
 }
 
+// Project 只保留指定字段的值（返回新的RecordData）
+func (rd RecordData) Project(fieldNames []string) (RecordData, error) {
+	newValues := make(map[string]interface{}, len(fieldNames))
+	for _, name := range fieldNames {
+		if v, ok := rd.values[name]; ok {
+			newValues[name] = v
+		}
+	}
+
+	return NewRecordData(newValues)
+}
+
 // HasField 检查是否包含指定字段
 func (rd RecordData) HasField(fieldName string) bool {
 	_, exists := rd.values[fieldName]
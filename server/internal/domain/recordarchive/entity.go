@@ -0,0 +1,83 @@
+package recordarchive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 归档依据
+const (
+	// BasisCreatedTime 按记录创建时间
+	BasisCreatedTime = "createdTime"
+	// BasisLastModifiedTime 按最后修改时间（从未修改过的记录按创建时间）
+	BasisLastModifiedTime = "lastModifiedTime"
+)
+
+const (
+	// MinAfterDays 归档阈值的最小天数
+	MinAfterDays = 1
+	// MaxAfterDays 归档阈值的最大天数
+	MaxAfterDays = 36500
+)
+
+// Policy 表的记录归档策略 ✨
+// 启用后后台定期将早于阈值的记录移入冷存储（归档表）：归档的记录不再出现在默认查询中，
+// 可通过 includeArchived 显式查询，也可以恢复到表中。每张表最多一条策略
+type Policy struct {
+	ID           string     `json:"id"`
+	TableID      string     `json:"table_id"`
+	Basis        string     `json:"basis"`      // createdTime | lastModifiedTime
+	AfterDays    int        `json:"after_days"` // 早于多少天的记录归档
+	Enabled      bool       `json:"enabled"`
+	NextRunAt    time.Time  `json:"next_run_at"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastArchived int        `json:"last_archived"` // 最近一次执行归档的记录数
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedBy    string     `json:"created_by"`
+	UpdatedBy    string     `json:"updated_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// NewPolicy 创建归档策略（默认启用，下一轮轮询即执行）
+func NewPolicy(tableID, basis string, afterDays int, createdBy string, now time.Time) *Policy {
+	return &Policy{
+		ID:        utils.GenerateIDWithPrefix("rap"),
+		TableID:   tableID,
+		Basis:     basis,
+		AfterDays: afterDays,
+		Enabled:   true,
+		NextRunAt: now,
+		CreatedBy: createdBy,
+		UpdatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 校验策略配置（归档依据为空时按创建时间）
+func (p *Policy) Validate() error {
+	switch p.Basis {
+	case "":
+		p.Basis = BasisCreatedTime
+	case BasisCreatedTime, BasisLastModifiedTime:
+	default:
+		return fmt.Errorf("不支持的归档依据 %q（可选 %s、%s）", p.Basis, BasisCreatedTime, BasisLastModifiedTime)
+	}
+	if p.AfterDays < MinAfterDays || p.AfterDays > MaxAfterDays {
+		return fmt.Errorf("归档阈值必须在 %d 到 %d 天之间", MinAfterDays, MaxAfterDays)
+	}
+	return nil
+}
+
+// Cutoff 归档截止时间：依据时间早于该时间的记录会被归档
+func (p *Policy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -p.AfterDays)
+}
+
+// ByLastModified 是否按最后修改时间归档
+func (p *Policy) ByLastModified() bool {
+	return p.Basis == BasisLastModifiedTime
+}
@@ -0,0 +1,43 @@
+package recordarchive
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	now := time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)
+	policy := NewPolicy("tbl1", "", 365, "usr1", now)
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if !strings.HasPrefix(policy.ID, "rap") || policy.Basis != BasisCreatedTime || !policy.Enabled || !policy.NextRunAt.Equal(now) {
+		t.Errorf("策略 = %+v", policy)
+	}
+	if policy.ByLastModified() {
+		t.Error("默认应按创建时间归档")
+	}
+
+	invalid := []*Policy{
+		NewPolicy("tbl1", "updatedTime", 30, "usr1", now),
+		NewPolicy("tbl1", BasisCreatedTime, 0, "usr1", now),
+		NewPolicy("tbl1", BasisLastModifiedTime, MaxAfterDays+1, "usr1", now),
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("策略 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestPolicyCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC)
+	policy := NewPolicy("tbl1", BasisLastModifiedTime, 30, "usr1", now)
+	if got, want := policy.Cutoff(now), time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Cutoff = %v, want %v", got, want)
+	}
+	if !policy.ByLastModified() {
+		t.Error("应按最后修改时间归档")
+	}
+}
@@ -0,0 +1,20 @@
+package recordarchive
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 归档策略仓储接口
+type Repository interface {
+	// FindByTable 获取表的归档策略（不存在时返回 nil）
+	FindByTable(ctx context.Context, tableID string) (*Policy, error)
+	// Save 创建或更新归档策略
+	Save(ctx context.Context, policy *Policy) error
+	// Delete 删除归档策略（不影响已归档的记录）
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取到期的启用策略并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Policy, error)
+	// SaveProgress 保存执行结果（下次执行时间、归档数、最近错误）并释放租约
+	SaveProgress(ctx context.Context, policy *Policy) error
+}
@@ -0,0 +1,78 @@
+package recordlock
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockValidate(t *testing.T) {
+	now := time.Now()
+	expires := now.Add(time.Hour)
+	lock := NewLock("tbl1", "rec1", []string{"fld1", "", "fld1", "fld2"}, "usr1", "  财务复核中 ", &expires, now)
+	if err := lock.Validate(now); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if lock.Reason != "财务复核中" || len(lock.FieldIDs) != 2 || !strings.HasPrefix(lock.ID, "rlk") {
+		t.Errorf("锁 = %+v", lock)
+	}
+
+	past := now.Add(-time.Minute)
+	tooLong := now.Add(MaxDuration + time.Hour)
+	invalid := []*Lock{
+		{Reason: strings.Repeat("因", MaxReasonLength+1)},
+		{FieldIDs: make([]string, MaxFieldsPerLock+1)},
+		{ExpiresAt: &past},
+		{ExpiresAt: &tooLong},
+	}
+	for i, l := range invalid {
+		if err := l.Validate(now); err == nil {
+			t.Errorf("锁 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestLockBlocks(t *testing.T) {
+	now := time.Now()
+	whole := NewLock("tbl1", "rec1", nil, "usr1", "", nil, now)
+	fields := NewLock("tbl1", "rec1", []string{"fld1"}, "usr1", "", nil, now)
+
+	cases := []struct {
+		name   string
+		lock   *Lock
+		user   string
+		fields []string
+		want   bool
+	}{
+		{"持有者可以修改", whole, "usr1", []string{"fld1"}, false},
+		{"整条记录锁阻止修改", whole, "usr2", []string{"fld9"}, true},
+		{"整条记录锁阻止删除", whole, "usr2", nil, true},
+		{"字段锁阻止修改锁定字段", fields, "usr2", []string{"fld2", "fld1"}, true},
+		{"字段锁不影响其他字段", fields, "usr2", []string{"fld2"}, false},
+		{"字段锁阻止删除", fields, "usr2", nil, true},
+	}
+	for _, c := range cases {
+		if got := c.lock.Blocks(c.user, c.fields, now); got != c.want {
+			t.Errorf("%s: Blocks = %v", c.name, got)
+		}
+	}
+
+	expired := now.Add(-time.Second)
+	fields.ExpiresAt = &expired
+	if fields.Blocks("usr2", nil, now) {
+		t.Error("过期的锁不应阻止写入")
+	}
+}
+
+func TestLockOverlaps(t *testing.T) {
+	now := time.Now()
+	a := NewLock("tbl1", "rec1", []string{"fld1"}, "usr1", "", nil, now)
+	b := NewLock("tbl1", "rec1", []string{"fld2"}, "usr2", "", nil, now)
+	c := NewLock("tbl1", "rec1", []string{"fld1"}, "usr2", "", nil, now)
+	whole := NewLock("tbl1", "rec1", nil, "usr3", "", nil, now)
+	mine := NewLock("tbl1", "rec1", nil, "usr1", "", nil, now)
+
+	if a.Overlaps(b) || !a.Overlaps(c) || !a.Overlaps(whole) || a.Overlaps(mine) {
+		t.Error("锁范围重叠判断错误")
+	}
+}
@@ -0,0 +1,18 @@
+package recordlock
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 记录锁仓储接口
+type Repository interface {
+	// Save 创建或更新记录锁（同时删除该持有者在同一记录上的其他锁，包括已过期的锁）
+	Save(ctx context.Context, lock *Lock) error
+	// FindByID 获取记录锁（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Lock, error)
+	// ListActive 列出表中在 now 时仍有效的锁；recordIDs 为空时列出整个表
+	ListActive(ctx context.Context, tableID string, recordIDs []string, now time.Time) ([]*Lock, error)
+	// Delete 删除记录锁
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,69 @@
+package recordtemplate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// MaxTemplatesPerTable 单表的记录模板数上限
+	MaxTemplatesPerTable = 50
+	// MaxNameLength 模板名称的最大长度（字符数）
+	MaxNameLength = 100
+	// MaxDescriptionLength 模板说明的最大长度（字符数）
+	MaxDescriptionLength = 500
+)
+
+// Template 记录模板 ✨
+// 预设一组单元格值（字段ID → 值），从模板新建记录时解析其中的占位符
+// （当前用户、相对日期）后与调用方提供的值合并；IsDefault 的模板作为表的快速新建预设
+type Template struct {
+	ID          string                 `json:"id"`
+	TableID     string                 `json:"table_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Values      map[string]interface{} `json:"values"`
+	IsDefault   bool                   `json:"is_default"` // 快速新建时使用的模板，每个表最多一个
+	CreatedBy   string                 `json:"created_by"`
+	UpdatedBy   string                 `json:"updated_by"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// NewTemplate 创建记录模板
+func NewTemplate(tableID, name, description string, values map[string]interface{}, isDefault bool, createdBy string, now time.Time) *Template {
+	return &Template{
+		ID:          utils.GenerateIDWithPrefix("rtp"),
+		TableID:     tableID,
+		Name:        name,
+		Description: description,
+		Values:      values,
+		IsDefault:   isDefault,
+		CreatedBy:   createdBy,
+		UpdatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Validate 校验模板名称与说明（单元格值由调用方按表结构校验）
+func (t *Template) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return fmt.Errorf("模板名称不能为空")
+	}
+	if utf8.RuneCountInString(t.Name) > MaxNameLength {
+		return fmt.Errorf("模板名称不能超过 %d 个字符", MaxNameLength)
+	}
+	if utf8.RuneCountInString(t.Description) > MaxDescriptionLength {
+		return fmt.Errorf("模板说明不能超过 %d 个字符", MaxDescriptionLength)
+	}
+	if t.Values == nil {
+		t.Values = map[string]interface{}{}
+	}
+	return nil
+}
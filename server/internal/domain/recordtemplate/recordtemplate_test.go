@@ -0,0 +1,84 @@
+package recordtemplate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	tpl := NewTemplate("tbl1", "  缺陷报告 ", "", nil, true, "usr1", time.Now())
+	if err := tpl.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if tpl.Name != "缺陷报告" || tpl.Values == nil || !strings.HasPrefix(tpl.ID, "rtp") {
+		t.Errorf("模板 = %+v", tpl)
+	}
+
+	invalid := []*Template{
+		{Name: "  "},
+		{Name: strings.Repeat("名", MaxNameLength+1)},
+		{Name: "a", Description: strings.Repeat("说", MaxDescriptionLength+1)},
+	}
+	for i, tpl := range invalid {
+		if err := tpl.Validate(); err == nil {
+			t.Errorf("模板 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestDateToken(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	// UTC 2026-01-31 20:00 即上海时间 2026-02-01 04:00
+	now := time.Date(2026, 1, 31, 20, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		token string
+		loc   *time.Location
+		want  time.Time
+	}{
+		{"@today", time.UTC, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"@today", shanghai, time.Date(2026, 2, 1, 0, 0, 0, 0, shanghai)},
+		{"@today+7d", time.UTC, time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC)},
+		{"@today-2w", time.UTC, time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"@today+1mo", shanghai, time.Date(2026, 3, 1, 0, 0, 0, 0, shanghai)},
+		{"@today+1y", time.UTC, time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"@now", nil, now},
+		{"@now+2h", time.UTC, now.Add(2 * time.Hour)},
+		// 2026-01-31 为周六，下一个工作日为周一 2 月 2 日
+		{"@today+1bd", time.UTC, time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{"@today-1bd", time.UTC, time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		token, err := ParseDateToken(c.token)
+		if err != nil {
+			t.Errorf("%s: %v", c.token, err)
+			continue
+		}
+		if got := token.Resolve(now, c.loc); !got.Equal(c.want) {
+			t.Errorf("%s = %v, want %v", c.token, got, c.want)
+		}
+	}
+
+	for _, s := range []string{"@today+", "@today+1h", "@now+3x", "@tomorrow", "@today+99999d", "@now 1d"} {
+		if _, err := ParseDateToken(s); err == nil {
+			t.Errorf("%s 应解析失败", s)
+		}
+	}
+	if !IsDateToken("@today+1d") || IsDateToken("2026-01-01") {
+		t.Error("占位符判断错误")
+	}
+}
+
+func TestResolveUser(t *testing.T) {
+	if got := ResolveUser(CurrentUser, "usr1"); got != "usr1" {
+		t.Errorf("单值 = %v", got)
+	}
+	if got := ResolveUser([]interface{}{"usr2", CurrentUser}, "usr1"); !reflect.DeepEqual(got, []interface{}{"usr2", "usr1"}) {
+		t.Errorf("数组 = %v", got)
+	}
+	if got := ResolveUser("usr3", "usr1"); got != "usr3" {
+		t.Errorf("普通值 = %v", got)
+	}
+}
@@ -0,0 +1,15 @@
+package recordtemplate
+
+import "context"
+
+// Repository 记录模板仓储接口
+type Repository interface {
+	// ListByTable 列出表的记录模板，默认模板在前，其余按创建时间排序
+	ListByTable(ctx context.Context, tableID string) ([]*Template, error)
+	// Get 获取记录模板（不存在时返回 nil）
+	Get(ctx context.Context, id string) (*Template, error)
+	// Save 保存记录模板；模板为默认模板时同时取消同表其他模板的默认标记
+	Save(ctx context.Context, template *Template) error
+	// Delete 删除记录模板
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,126 @@
+package recordtemplate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+)
+
+const (
+	// CurrentUser 用户字段中表示当前用户的占位符，新建记录时替换为操作者的用户ID
+	CurrentUser = "@me"
+	// Today 日期字段中表示今天零点的占位符，可带偏移，如 @today+7d、@today-1mo、@today+3bd（工作日）
+	Today = "@today"
+	// Now 日期字段中表示当前时间的占位符，可带偏移，如 @now+2h
+	Now = "@now"
+
+	// maxOffset 相对日期偏移量上限
+	maxOffset = 10000
+)
+
+// dateTokenPattern 相对日期占位符：基准 + 可选偏移（h 小时、d 天、bd 工作日、w 周、mo 月、y 年）
+var dateTokenPattern = regexp.MustCompile(`^@(today|now)(?:([+-])(\d{1,5})(h|d|bd|w|mo|y))?$`)
+
+// DateToken 解析后的相对日期占位符
+type DateToken struct {
+	Base   string // today 或 now
+	Amount int    // 带符号的偏移量
+	Unit   string // h、d、bd、w、mo、y，无偏移时为空
+}
+
+// IsDateToken 日期字段的值是否按占位符书写（以 @ 开头，日期本身不会以 @ 开头）
+func IsDateToken(s string) bool {
+	return strings.HasPrefix(s, "@")
+}
+
+// ParseDateToken 解析相对日期占位符
+func ParseDateToken(s string) (*DateToken, error) {
+	m := dateTokenPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("无效的日期占位符: %s", s)
+	}
+	token := &DateToken{Base: m[1], Unit: m[4]}
+	if m[3] != "" {
+		amount, _ := strconv.Atoi(m[3])
+		if amount > maxOffset {
+			return nil, fmt.Errorf("日期偏移量不能超过 %d", maxOffset)
+		}
+		if m[2] == "-" {
+			amount = -amount
+		}
+		token.Amount = amount
+	}
+	if token.Base == "today" && token.Unit == "h" {
+		return nil, fmt.Errorf("@today 不支持按小时偏移，请使用 @now")
+	}
+	return token, nil
+}
+
+// Resolve 以 now 为基准计算占位符表示的时间（工作日偏移按默认日历，即周六、周日休息）
+// @today 取 loc 时区的当天零点，月、年偏移按日历计算
+func (t *DateToken) Resolve(now time.Time, loc *time.Location) time.Time {
+	return t.ResolveWithCalendar(now, loc, nil)
+}
+
+// ResolveWithCalendar 以 now 为基准计算占位符表示的时间，工作日偏移（bd）按工作空间的工作日历计算，
+// calendar 为空时使用默认日历；日历中找不到足够的工作日时按自然日偏移
+func (t *DateToken) ResolveWithCalendar(now time.Time, loc *time.Location, calendar *businesscalendar.Calendar) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	base := now.In(loc)
+	if t.Base == "today" {
+		base = time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, loc)
+	}
+	switch t.Unit {
+	case "h":
+		return base.Add(time.Duration(t.Amount) * time.Hour)
+	case "d":
+		return base.AddDate(0, 0, t.Amount)
+	case "bd":
+		if calendar == nil {
+			calendar = businesscalendar.Default("")
+		}
+		if day, ok := calendar.AddWorkdays(base, t.Amount); ok {
+			return day
+		}
+		return base.AddDate(0, 0, t.Amount)
+	case "w":
+		return base.AddDate(0, 0, 7*t.Amount)
+	case "mo":
+		return base.AddDate(0, t.Amount, 0)
+	case "y":
+		return base.AddDate(t.Amount, 0, 0)
+	}
+	return base
+}
+
+// ResolveUser 将用户字段值中的 CurrentUser 占位符替换为用户ID（支持单值与数组）
+func ResolveUser(value interface{}, userID string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == CurrentUser {
+			return userID
+		}
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			out = append(out, ResolveUser(item, userID))
+		}
+		return out
+	case []string:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if item == CurrentUser {
+				item = userID
+			}
+			out = append(out, item)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,288 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// MaxRulesPerTable 单表的重复规则数上限
+	MaxRulesPerTable = 20
+	// MaxSkipDates 单条规则的跳过日期数上限
+	MaxSkipDates = 366
+	// MaxCalendarDates 节假日日历的日期数上限
+	MaxCalendarDates = 1000
+	// MaxNameLength 规则与日历名称的最大长度（字符数）
+	MaxNameLength = 100
+	// maxShiftDays 节假日顺延的最大天数
+	maxShiftDays = 31
+)
+
+// HolidayPolicy 执行日为节假日时的处理方式
+type HolidayPolicy string
+
+const (
+	HolidaySkip  HolidayPolicy = "skip"  // 跳过本次
+	HolidayShift HolidayPolicy = "shift" // 顺延到下一个非节假日（顺延后不早于下一次执行日时跳过）
+)
+
+// CatchUp 服务停机后错过的执行如何补跑
+type CatchUp string
+
+const (
+	CatchUpAll    CatchUp = "all"    // 逐次补建（受服务端补跑上限约束）
+	CatchUpLatest CatchUp = "latest" // 只补建最近一次，其余记为错过
+)
+
+// 跳过原因
+const (
+	SkipHoliday = "holiday"   // 节假日
+	SkipDate    = "skip_date" // 规则的跳过日期
+	SkipMissed  = "missed"    // 停机期间错过且不补跑
+)
+
+// Rule 重复记录规则 ✨
+// 按计划用记录模板定时新建记录（如每周一的站会记录）。每次执行以计划时间为幂等键：
+// 服务停机后按 CatchUp 补跑错过的执行，多实例或重复领取时同一计划时间只会生成一条记录
+type Rule struct {
+	ID            string        `json:"id"`
+	TableID       string        `json:"table_id"`
+	TemplateID    string        `json:"template_id"`
+	Name          string        `json:"name"`
+	Schedule      Schedule      `json:"schedule"`
+	CalendarID    string        `json:"calendar_id,omitempty"` // 节假日日历，为空时不考虑节假日
+	HolidayPolicy HolidayPolicy `json:"holiday_policy"`
+	SkipDates     []string      `json:"skip_dates"` // 单独跳过的日期
+	CatchUp       CatchUp       `json:"catch_up"`
+	Enabled       bool          `json:"enabled"`
+	Cursor        time.Time     `json:"cursor"`                // 已处理到的计划时间，之后的执行尚未处理
+	NextRunAt     *time.Time    `json:"next_run_at,omitempty"` // 为空表示计划已结束
+	LastRunAt     *time.Time    `json:"last_run_at,omitempty"`
+	LastError     string        `json:"last_error,omitempty"`
+	CreatedBy     string        `json:"created_by"` // 以创建人的身份新建记录
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// NewRule 创建重复规则（只处理创建之后的执行）
+func NewRule(tableID, templateID, name string, schedule Schedule, createdBy string, now time.Time) *Rule {
+	return &Rule{
+		ID:            utils.GenerateIDWithPrefix("rcr"),
+		TableID:       tableID,
+		TemplateID:    templateID,
+		Name:          name,
+		Schedule:      schedule,
+		HolidayPolicy: HolidaySkip,
+		SkipDates:     []string{},
+		CatchUp:       CatchUpAll,
+		Enabled:       true,
+		Cursor:        now,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Validate 校验规则并补全默认值
+func (r *Rule) Validate(now time.Time) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("规则名称不能为空")
+	}
+	if utf8.RuneCountInString(r.Name) > MaxNameLength {
+		return fmt.Errorf("规则名称不能超过 %d 个字符", MaxNameLength)
+	}
+	if r.TemplateID == "" {
+		return fmt.Errorf("记录模板不能为空")
+	}
+	if err := r.Schedule.Validate(now); err != nil {
+		return err
+	}
+	switch r.HolidayPolicy {
+	case "":
+		r.HolidayPolicy = HolidaySkip
+	case HolidaySkip, HolidayShift:
+	default:
+		return fmt.Errorf("不支持的节假日处理方式: %s", r.HolidayPolicy)
+	}
+	switch r.CatchUp {
+	case "":
+		r.CatchUp = CatchUpAll
+	case CatchUpAll, CatchUpLatest:
+	default:
+		return fmt.Errorf("不支持的补跑方式: %s", r.CatchUp)
+	}
+	dates, err := normalizeDates(r.SkipDates, MaxSkipDates)
+	if err != nil {
+		return err
+	}
+	r.SkipDates = dates
+	return nil
+}
+
+// Reschedule 从 now 重新开始计划（修改计划或重新启用时不补跑之前的执行）
+func (r *Rule) Reschedule(now time.Time) {
+	if r.Cursor.Before(now) {
+		r.Cursor = now
+	}
+}
+
+// Occurrence 一次计划执行
+type Occurrence struct {
+	ScheduledAt time.Time `json:"scheduled_at"`   // 按计划计算的时间，作为幂等键
+	RunAt       time.Time `json:"run_at"`         // 实际生成时间（节假日顺延后）
+	Skip        string    `json:"skip,omitempty"` // 非空时本次不生成记录
+}
+
+// NextOccurrence 计划时间晚于 after 的下一次执行；计划已结束时返回 false
+// holidays 为节假日日历中的日期集合
+func (r *Rule) NextOccurrence(after time.Time, holidays map[string]bool) (Occurrence, bool) {
+	scheduled, ok := r.Schedule.Next(after)
+	if !ok {
+		return Occurrence{}, false
+	}
+	occ := Occurrence{ScheduledAt: scheduled, RunAt: scheduled}
+	date := r.Schedule.LocalDate(scheduled)
+	for _, skip := range r.SkipDates {
+		if skip == date {
+			occ.Skip = SkipDate
+			return occ, true
+		}
+	}
+	if !holidays[date] {
+		return occ, true
+	}
+	if r.HolidayPolicy != HolidayShift {
+		occ.Skip = SkipHoliday
+		return occ, true
+	}
+
+	// 顺延：保持本地时刻，逐日后移到第一个非节假日
+	loc := r.Schedule.Location()
+	local := scheduled.In(loc)
+	for i := 1; i <= maxShiftDays; i++ {
+		shifted := time.Date(local.Year(), local.Month(), local.Day()+i, local.Hour(), local.Minute(), 0, 0, loc)
+		if holidays[shifted.Format(DateLayout)] {
+			continue
+		}
+		// 顺延后与下一次执行同日或更晚时，本次跳过，避免同一天生成两条记录
+		if next, ok := r.Schedule.Next(scheduled); ok && r.Schedule.LocalDate(next) <= shifted.Format(DateLayout) {
+			break
+		}
+		occ.RunAt = shifted
+		return occ, true
+	}
+	occ.Skip = SkipHoliday
+	return occ, true
+}
+
+// Calendar 节假日日历（按 Base 管理，供多条规则共用）
+type Calendar struct {
+	ID        string    `json:"id"`
+	BaseID    string    `json:"base_id"`
+	Name      string    `json:"name"`
+	Dates     []string  `json:"dates"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCalendar 创建节假日日历
+func NewCalendar(baseID, name string, dates []string, createdBy string, now time.Time) *Calendar {
+	return &Calendar{
+		ID:        utils.GenerateIDWithPrefix("hcl"),
+		BaseID:    baseID,
+		Name:      name,
+		Dates:     dates,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate 校验日历，日期去重并排序
+func (c *Calendar) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("日历名称不能为空")
+	}
+	if utf8.RuneCountInString(c.Name) > MaxNameLength {
+		return fmt.Errorf("日历名称不能超过 %d 个字符", MaxNameLength)
+	}
+	dates, err := normalizeDates(c.Dates, MaxCalendarDates)
+	if err != nil {
+		return err
+	}
+	c.Dates = dates
+	return nil
+}
+
+// Set 日历日期集合
+func (c *Calendar) Set() map[string]bool {
+	set := make(map[string]bool, len(c.Dates))
+	for _, d := range c.Dates {
+		set[d] = true
+	}
+	return set
+}
+
+// RunStatus 执行结果
+type RunStatus string
+
+const (
+	RunCreated RunStatus = "created" // 已生成记录
+	RunSkipped RunStatus = "skipped" // 跳过（见 Reason）
+	RunFailed  RunStatus = "failed"  // 生成失败且不再重试
+)
+
+// Run 一次执行的结果（规则与计划时间唯一）
+type Run struct {
+	ID          string    `json:"id"`
+	RuleID      string    `json:"rule_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	RunAt       time.Time `json:"run_at"`
+	Status      RunStatus `json:"status"`
+	Reason      string    `json:"reason,omitempty"`
+	RecordID    string    `json:"record_id,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewRun 记录一次执行结果
+func NewRun(rule *Rule, occ Occurrence, status RunStatus, now time.Time) *Run {
+	return &Run{
+		ID:          utils.GenerateIDWithPrefix("rcu"),
+		RuleID:      rule.ID,
+		ScheduledAt: occ.ScheduledAt,
+		RunAt:       occ.RunAt,
+		Status:      status,
+		Reason:      occ.Skip,
+		CreatedAt:   now,
+	}
+}
+
+// normalizeDates 校验日期格式，去重排序
+func normalizeDates(dates []string, max int) ([]string, error) {
+	seen := make(map[string]bool, len(dates))
+	out := make([]string, 0, len(dates))
+	for _, d := range dates {
+		d = strings.TrimSpace(d)
+		if _, err := time.Parse(DateLayout, d); err != nil {
+			return nil, fmt.Errorf("无效的日期: %s，格式为 YYYY-MM-DD", d)
+		}
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	if len(out) > max {
+		return nil, fmt.Errorf("日期不能超过 %d 个", max)
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,151 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func nextN(t *testing.T, s Schedule, after time.Time, n int) []string {
+	t.Helper()
+	var out []string
+	for i := 0; i < n; i++ {
+		next, ok := s.Next(after)
+		if !ok {
+			break
+		}
+		out = append(out, next.In(s.Location()).Format("2006-01-02 15:04 Mon"))
+		after = next
+	}
+	return out
+}
+
+func TestScheduleNext(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) // 周四
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		want     []string
+	}{
+		{
+			name:     "每周一 09:30（上海）",
+			schedule: Schedule{Frequency: FrequencyWeekly, Weekdays: []int{1}, Time: "09:30", TimeZone: "Asia/Shanghai"},
+			want:     []string{"2026-10-19 09:30 Mon", "2026-10-26 09:30 Mon", "2026-11-02 09:30 Mon"},
+		},
+		{
+			name:     "隔周周二与周五，从开始日期所在周起算",
+			schedule: Schedule{Frequency: FrequencyWeekly, Interval: 2, Weekdays: []int{5, 2}, StartDate: "2026-10-12"},
+			want:     []string{"2026-10-16 00:00 Fri", "2026-10-27 00:00 Tue", "2026-10-30 00:00 Fri"},
+		},
+		{
+			name:     "工作日每天",
+			schedule: Schedule{Frequency: FrequencyDaily, Weekdays: []int{1, 2, 3, 4, 5}, Time: "18:00"},
+			want:     []string{"2026-10-15 18:00 Thu", "2026-10-16 18:00 Fri", "2026-10-19 18:00 Mon"},
+		},
+		{
+			name:     "每月 15 日与月末",
+			schedule: Schedule{Frequency: FrequencyMonthly, MonthDays: []int{-1, 15}, Time: "08:00"},
+			want:     []string{"2026-10-31 08:00 Sat", "2026-11-15 08:00 Sun", "2026-11-30 08:00 Mon"},
+		},
+		{
+			name:     "每月 31 日跳过小月",
+			schedule: Schedule{Frequency: FrequencyMonthly, MonthDays: []int{31}},
+			want:     []string{"2026-10-31 00:00 Sat", "2026-12-31 00:00 Thu", "2027-01-31 00:00 Sun"},
+		},
+		{
+			name:     "到结束日期为止",
+			schedule: Schedule{Frequency: FrequencyDaily, Interval: 3, StartDate: "2026-10-14", EndDate: "2026-10-21"},
+			want:     []string{"2026-10-17 00:00 Sat", "2026-10-20 00:00 Tue"},
+		},
+	}
+	for _, c := range cases {
+		s := c.schedule
+		if err := s.Validate(now); err != nil {
+			t.Fatalf("%s: 校验失败: %v", c.name, err)
+		}
+		got := nextN(t, s, now, 3)
+		if len(got) != len(c.want) {
+			t.Errorf("%s = %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s = %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	now := time.Now()
+	invalid := []Schedule{
+		{Frequency: "hourly"},
+		{Frequency: FrequencyWeekly},
+		{Frequency: FrequencyMonthly, MonthDays: []int{0}},
+		{Frequency: FrequencyMonthly, MonthDays: []int{32}},
+		{Frequency: FrequencyDaily, Interval: 400},
+		{Frequency: FrequencyDaily, Time: "25:00"},
+		{Frequency: FrequencyDaily, TimeZone: "Mars/Olympus"},
+		{Frequency: FrequencyDaily, StartDate: "2026-10-10", EndDate: "2026-10-01"},
+	}
+	for i, s := range invalid {
+		if err := s.Validate(now); err == nil {
+			t.Errorf("计划 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestNextOccurrenceHolidays(t *testing.T) {
+	now := time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)
+	rule := NewRule("tbl1", "rtp1", "站会", Schedule{Frequency: FrequencyDaily, Weekdays: []int{1, 2, 3, 4, 5}, Time: "09:00"}, "usr1", now)
+	rule.SkipDates = []string{"2026-09-29"}
+	if err := rule.Validate(now); err != nil {
+		t.Fatal(err)
+	}
+	holidays := map[string]bool{"2026-09-30": true, "2026-10-01": true}
+
+	occ, _ := rule.NextOccurrence(now, holidays)
+	if occ.Skip != "" || occ.ScheduledAt != time.Date(2026, 9, 28, 9, 0, 0, 0, time.UTC) {
+		t.Errorf("第一次 = %+v", occ)
+	}
+	occ, _ = rule.NextOccurrence(occ.ScheduledAt, holidays)
+	if occ.Skip != SkipDate {
+		t.Errorf("跳过日期 = %+v", occ)
+	}
+	occ, _ = rule.NextOccurrence(occ.ScheduledAt, holidays)
+	if occ.Skip != SkipHoliday {
+		t.Errorf("节假日 = %+v", occ)
+	}
+
+	// 每周三顺延到节假日后的第一天
+	weekly := NewRule("tbl1", "rtp1", "周报", Schedule{Frequency: FrequencyWeekly, Weekdays: []int{3}, Time: "10:00"}, "usr1", now)
+	weekly.HolidayPolicy = HolidayShift
+	if err := weekly.Validate(now); err != nil {
+		t.Fatal(err)
+	}
+	occ, _ = weekly.NextOccurrence(now, holidays)
+	if occ.Skip != "" || occ.ScheduledAt.Day() != 30 || occ.RunAt != time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC) {
+		t.Errorf("顺延 = %+v", occ)
+	}
+
+	// 每天的规则顺延会与下一次同日，改为跳过
+	rule.HolidayPolicy = HolidayShift
+	occ, _ = rule.NextOccurrence(time.Date(2026, 9, 29, 12, 0, 0, 0, time.UTC), holidays)
+	if occ.Skip != SkipHoliday {
+		t.Errorf("顺延冲突 = %+v", occ)
+	}
+}
+
+func TestCalendarValidate(t *testing.T) {
+	cal := NewCalendar("bse1", " 法定节假日 ", []string{"2026-10-02", "2026-10-01", "2026-10-02"}, "usr1", time.Now())
+	if err := cal.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cal.Name != "法定节假日" || len(cal.Dates) != 2 || cal.Dates[0] != "2026-10-01" || !cal.Set()["2026-10-02"] {
+		t.Errorf("日历 = %+v", cal)
+	}
+	if err := NewCalendar("bse1", "x", []string{"10/01/2026"}, "usr1", time.Now()).Validate(); err == nil {
+		t.Error("无效日期应校验失败")
+	}
+}
@@ -0,0 +1,42 @@
+package recurrence
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 重复规则、执行记录与节假日日历仓储接口
+type Repository interface {
+	// ListRules 列出表的重复规则，按创建时间排序
+	ListRules(ctx context.Context, tableID string) ([]*Rule, error)
+	// GetRule 获取重复规则（不存在时返回 nil）
+	GetRule(ctx context.Context, id string) (*Rule, error)
+	// SaveRule 创建或更新规则配置与进度
+	SaveRule(ctx context.Context, rule *Rule) error
+	// DeleteRule 删除规则及其执行记录
+	DeleteRule(ctx context.Context, id string) error
+	// CountRulesByCalendar 使用该节假日日历的规则数
+	CountRulesByCalendar(ctx context.Context, calendarID string) (int64, error)
+	// ClaimDue 领取已到执行时间的启用规则并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Rule, error)
+	// SaveProgress 保存执行进度（游标、下次执行时间、最近错误）并释放租约
+	SaveProgress(ctx context.Context, rule *Rule) error
+
+	// HasRun 规则在该计划时间是否已有执行记录
+	HasRun(ctx context.Context, ruleID string, scheduledAt time.Time) (bool, error)
+	// SaveRun 写入执行记录（在业务事务中调用时随事务提交；规则与计划时间唯一）
+	SaveRun(ctx context.Context, run *Run) error
+	// ListRuns 按计划时间倒序列出规则的执行记录
+	ListRuns(ctx context.Context, ruleID string, limit int) ([]*Run, error)
+	// PurgeRuns 只保留规则最近 keep 条执行记录
+	PurgeRuns(ctx context.Context, ruleID string, keep int) error
+
+	// ListCalendars 列出 Base 的节假日日历
+	ListCalendars(ctx context.Context, baseID string) ([]*Calendar, error)
+	// GetCalendar 获取节假日日历（不存在时返回 nil）
+	GetCalendar(ctx context.Context, id string) (*Calendar, error)
+	// SaveCalendar 创建或更新节假日日历
+	SaveCalendar(ctx context.Context, calendar *Calendar) error
+	// DeleteCalendar 删除节假日日历
+	DeleteCalendar(ctx context.Context, id string) error
+}
@@ -0,0 +1,229 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DateLayout 日期格式（开始/结束日期、跳过日期与节假日）
+const DateLayout = "2006-01-02"
+
+// searchDays 查找下一次执行时向后搜索的天数上限（覆盖最长间隔）
+const searchDays = 366 * 8
+
+// Frequency 重复频率
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "daily"   // 每 N 天，可用 weekdays 限定星期
+	FrequencyWeekly  Frequency = "weekly"  // 每 N 周的指定星期
+	FrequencyMonthly Frequency = "monthly" // 每 N 个月的指定日期（-1 表示月末）
+)
+
+// maxIntervals 各频率允许的最大间隔
+var maxIntervals = map[Frequency]int{
+	FrequencyDaily:   365,
+	FrequencyWeekly:  52,
+	FrequencyMonthly: 12,
+}
+
+// Schedule 重复计划，按时区的本地日期与时间计算
+type Schedule struct {
+	Frequency Frequency `json:"frequency"`
+	Interval  int       `json:"interval"`             // 每 N 个周期，默认 1（以开始日期所在的天、周、月为起点）
+	Weekdays  []int     `json:"weekdays,omitempty"`   // 0=周日 … 6=周六
+	MonthDays []int     `json:"month_days,omitempty"` // 1-31，-1 表示月末；当月没有该日期时不执行
+	Time      string    `json:"time"`                 // HH:MM，默认 00:00
+	TimeZone  string    `json:"timezone"`             // IANA 时区，默认 UTC
+	StartDate string    `json:"start_date"`           // 默认创建当天
+	EndDate   string    `json:"end_date,omitempty"`   // 为空时不结束
+}
+
+// Validate 校验计划并补全默认值（now 用于默认开始日期）
+func (s *Schedule) Validate(now time.Time) error {
+	max, ok := maxIntervals[s.Frequency]
+	if !ok {
+		return fmt.Errorf("不支持的重复频率: %s", s.Frequency)
+	}
+	if s.Interval == 0 {
+		s.Interval = 1
+	}
+	if s.Interval < 1 || s.Interval > max {
+		return fmt.Errorf("%s 频率的间隔必须在 1-%d 之间", s.Frequency, max)
+	}
+	if s.TimeZone == "" {
+		s.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return fmt.Errorf("无效的时区: %s", s.TimeZone)
+	}
+	if s.Time == "" {
+		s.Time = "00:00"
+	}
+	if _, err := time.Parse("15:04", s.Time); err != nil {
+		return fmt.Errorf("无效的执行时间: %s，格式为 HH:MM", s.Time)
+	}
+
+	weekdays, err := uniqueInts(s.Weekdays, 0, 6, "星期")
+	if err != nil {
+		return err
+	}
+	s.Weekdays = weekdays
+	monthDays, err := uniqueInts(s.MonthDays, -1, 31, "日期")
+	if err != nil {
+		return err
+	}
+	for _, d := range monthDays {
+		if d == 0 {
+			return fmt.Errorf("无效的日期: 0")
+		}
+	}
+	s.MonthDays = monthDays
+
+	switch s.Frequency {
+	case FrequencyDaily:
+		if len(s.MonthDays) > 0 {
+			return fmt.Errorf("每天重复不能指定 month_days")
+		}
+	case FrequencyWeekly:
+		if len(s.Weekdays) == 0 {
+			return fmt.Errorf("每周重复需要指定 weekdays")
+		}
+		if len(s.MonthDays) > 0 {
+			return fmt.Errorf("每周重复不能指定 month_days")
+		}
+	case FrequencyMonthly:
+		if len(s.MonthDays) == 0 {
+			return fmt.Errorf("每月重复需要指定 month_days")
+		}
+		if len(s.Weekdays) > 0 {
+			return fmt.Errorf("每月重复不能指定 weekdays")
+		}
+	}
+
+	if s.StartDate == "" {
+		s.StartDate = now.In(loc).Format(DateLayout)
+	}
+	start, err := time.Parse(DateLayout, s.StartDate)
+	if err != nil {
+		return fmt.Errorf("无效的开始日期: %s", s.StartDate)
+	}
+	if s.EndDate != "" {
+		end, err := time.Parse(DateLayout, s.EndDate)
+		if err != nil {
+			return fmt.Errorf("无效的结束日期: %s", s.EndDate)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("结束日期不能早于开始日期")
+		}
+	}
+	return nil
+}
+
+// Location 计划的时区（无效时为 UTC）
+func (s Schedule) Location() *time.Location {
+	if loc, err := time.LoadLocation(s.TimeZone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// LocalDate 时间在计划时区的日期
+func (s Schedule) LocalDate(t time.Time) string {
+	return t.In(s.Location()).Format(DateLayout)
+}
+
+// Next 晚于 after 的下一次执行时间；计划已结束时返回 false
+func (s Schedule) Next(after time.Time) (time.Time, bool) {
+	loc := s.Location()
+	clock, err := time.Parse("15:04", s.Time)
+	if err != nil {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(DateLayout, s.StartDate)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var end time.Time
+	if s.EndDate != "" {
+		if end, err = time.Parse(DateLayout, s.EndDate); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	local := after.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Before(start) {
+		day = start
+	}
+	for i := 0; i <= searchDays; i++ {
+		d := day.AddDate(0, 0, i)
+		if !end.IsZero() && d.After(end) {
+			return time.Time{}, false
+		}
+		if !s.matches(d, start) {
+			continue
+		}
+		t := time.Date(d.Year(), d.Month(), d.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if t.After(after) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// matches 日期 d 是否为执行日（d 与 start 均为 UTC 零点表示的本地日期）
+func (s Schedule) matches(d, start time.Time) bool {
+	days := int(d.Sub(start).Hours() / 24)
+	switch s.Frequency {
+	case FrequencyDaily:
+		return days%s.Interval == 0 && (len(s.Weekdays) == 0 || containsInt(s.Weekdays, int(d.Weekday())))
+	case FrequencyWeekly:
+		if !containsInt(s.Weekdays, int(d.Weekday())) {
+			return false
+		}
+		weeks := int(mondayOf(d).Sub(mondayOf(start)).Hours() / 24 / 7)
+		return weeks%s.Interval == 0
+	case FrequencyMonthly:
+		months := (d.Year()-start.Year())*12 + int(d.Month()) - int(start.Month())
+		if months%s.Interval != 0 {
+			return false
+		}
+		lastDay := d.AddDate(0, 1, -d.Day()).Day()
+		return containsInt(s.MonthDays, d.Day()) || (d.Day() == lastDay && containsInt(s.MonthDays, -1))
+	}
+	return false
+}
+
+// mondayOf 日期所在周的周一（周从周一开始）
+func mondayOf(d time.Time) time.Time {
+	return d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+}
+
+func containsInt(list []int, v int) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueInts 校验取值范围并去重排序
+func uniqueInts(list []int, min, max int, name string) ([]int, error) {
+	seen := make(map[int]bool, len(list))
+	out := make([]int, 0, len(list))
+	for _, v := range list {
+		if v < min || v > max {
+			return nil, fmt.Errorf("无效的%s: %d", name, v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package replication
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Mode 发布方式
+type Mode string
+
+const (
+	// ModePublication 在数据库中维护 PostgreSQL 发布（PUBLICATION），
+	// 由 Debezium 等工具通过逻辑复制槽直接订阅所选物理表
+	ModePublication Mode = "publication"
+	// ModeWebhook 从变更流读取变更，以 Debezium 兼容的 JSON 格式推送到 HTTP 端点
+	ModeWebhook Mode = "webhook"
+)
+
+// Target 复制目标 ✨
+// 每个目标按表选择加入（TableIDs），只发布所选表的记录变更；
+// 所选表的字段变化时，Webhook 目标额外收到一条结构变更通知
+type Target struct {
+	ID              string     `json:"id"`
+	BaseID          string     `json:"base_id"`
+	Name            string     `json:"name"`
+	Mode            Mode       `json:"mode"`
+	URL             string     `json:"url,omitempty"`
+	Secret          string     `json:"-"` // Webhook 签名密钥，仅创建时返回一次
+	TableIDs        []string   `json:"table_ids"`
+	Enabled         bool       `json:"enabled"`
+	Cursor          int64      `json:"cursor"` // 已投递的变更流序号
+	LastError       string     `json:"last_error,omitempty"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at,omitempty"`
+	CreatedBy       string     `json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NewTarget 创建复制目标
+func NewTarget(baseID, name string, mode Mode, url string, tableIDs []string, createdBy string) *Target {
+	now := time.Now()
+	target := &Target{
+		ID:        utils.GenerateIDWithPrefix("rpl"),
+		BaseID:    baseID,
+		Name:      name,
+		Mode:      mode,
+		URL:       url,
+		TableIDs:  tableIDs,
+		Enabled:   true,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if mode == ModeWebhook {
+		target.Secret = utils.GenerateNanoID(32)
+	}
+	return target
+}
+
+// IncludesTable 表是否已加入复制
+func (t *Target) IncludesTable(tableID string) bool {
+	for _, id := range t.TableIDs {
+		if id == tableID {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicationName 对应的 PostgreSQL 发布名称
+func (t *Target) PublicationName() string {
+	return "luckdb_" + t.ID
+}
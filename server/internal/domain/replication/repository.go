@@ -0,0 +1,27 @@
+package replication
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 复制目标仓储接口
+type Repository interface {
+	// Save 创建或更新目标配置（不覆盖投递进度）
+	Save(ctx context.Context, target *Target) error
+	// FindByID 获取目标（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Target, error)
+	// ListByBase 列出 Base 下的目标
+	ListByBase(ctx context.Context, baseID string) ([]*Target, error)
+	// Delete 删除目标
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取已启用的 Webhook 目标并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*Target, error)
+	// SaveProgress 保存投递进度并释放租约
+	SaveProgress(ctx context.Context, target *Target) error
+
+	// SyncPublication 创建或更新 PostgreSQL 发布，使其恰好包含 tables（schema 限定的物理表名）
+	SyncPublication(ctx context.Context, name string, tables []string) error
+	// DropPublication 删除 PostgreSQL 发布
+	DropPublication(ctx context.Context, name string) error
+}
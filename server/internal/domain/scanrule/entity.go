@@ -0,0 +1,89 @@
+package scanrule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 扫码未找到记录时的处理方式
+const (
+	OnMissingNone   = "none"   // 返回未找到
+	OnMissingCreate = "create" // 以扫到的编码新建记录
+)
+
+// MaxCodeLength 扫码编码的最大长度（字符数）
+const MaxCodeLength = 256
+
+// Rule 字段的扫码规则 ✨
+// 移动端扫码（条码、二维码）按编码精确匹配该字段查找记录；未找到时 OnMissing 为 create 则新建记录，
+// 新记录的该字段写入编码，RecordTemplateID 非空时其余单元格取自该记录模板
+type Rule struct {
+	ID               string    `json:"id"`
+	TableID          string    `json:"table_id"`
+	FieldID          string    `json:"field_id"`
+	OnMissing        string    `json:"on_missing"`
+	RecordTemplateID string    `json:"record_template_id,omitempty"`
+	CreatedBy        string    `json:"created_by"`
+	UpdatedBy        string    `json:"updated_by"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NewRule 创建扫码规则（未指定处理方式时不新建记录）
+func NewRule(tableID, fieldID, onMissing, recordTemplateID, createdBy string, now time.Time) *Rule {
+	if onMissing == "" {
+		onMissing = OnMissingNone
+	}
+	return &Rule{
+		ID:               utils.GenerateIDWithPrefix("scr"),
+		TableID:          tableID,
+		FieldID:          fieldID,
+		OnMissing:        onMissing,
+		RecordTemplateID: recordTemplateID,
+		CreatedBy:        createdBy,
+		UpdatedBy:        createdBy,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// Validate 校验处理方式（字段类型与记录模板由调用方按表结构校验）
+func (r *Rule) Validate() error {
+	switch r.OnMissing {
+	case OnMissingNone:
+		if r.RecordTemplateID != "" {
+			return fmt.Errorf("只有未找到时新建记录的规则才能指定记录模板")
+		}
+	case OnMissingCreate:
+	default:
+		return fmt.Errorf("不支持的处理方式 %q（可选 %s、%s）", r.OnMissing, OnMissingNone, OnMissingCreate)
+	}
+	return nil
+}
+
+// Creates 未找到记录时是否新建
+func (r *Rule) Creates() bool {
+	return r != nil && r.OnMissing == OnMissingCreate
+}
+
+// NormalizeCode 规范扫到的编码：去掉扫码枪附加的回车、制表符等控制字符及首尾空白
+func NormalizeCode(raw string) (string, error) {
+	code := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, raw))
+	if code == "" {
+		return "", fmt.Errorf("编码不能为空")
+	}
+	if utf8.RuneCountInString(code) > MaxCodeLength {
+		return "", fmt.Errorf("编码不能超过 %d 个字符", MaxCodeLength)
+	}
+	return code, nil
+}
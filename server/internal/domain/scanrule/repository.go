@@ -0,0 +1,15 @@
+package scanrule
+
+import "context"
+
+// Repository 扫码规则仓储接口
+type Repository interface {
+	// Save 创建或更新扫码规则
+	Save(ctx context.Context, rule *Rule) error
+	// FindByField 获取字段的扫码规则（不存在时返回 nil）
+	FindByField(ctx context.Context, fieldID string) (*Rule, error)
+	// ListByTable 列出表的扫码规则
+	ListByTable(ctx context.Context, tableID string) ([]*Rule, error)
+	// Delete 删除扫码规则
+	Delete(ctx context.Context, id string) error
+}
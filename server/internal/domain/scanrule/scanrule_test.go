@@ -0,0 +1,42 @@
+package scanrule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	code, err := NormalizeCode(" SKU-0042\r\n")
+	if err != nil || code != "SKU-0042" {
+		t.Errorf("应去掉控制字符与空白，得到 %q, %v", code, err)
+	}
+	if code, _ := NormalizeCode("01\x1d0950"); code != "010950" {
+		t.Errorf("应去掉分隔符，得到 %q", code)
+	}
+	if _, err := NormalizeCode("\t\n"); err == nil {
+		t.Error("空编码应报错")
+	}
+	if _, err := NormalizeCode(strings.Repeat("9", MaxCodeLength+1)); err == nil {
+		t.Error("过长编码应报错")
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	rule := NewRule("tbl1", "fldSku", "", "", "usr1", time.Now())
+	if err := rule.Validate(); err != nil || rule.Creates() {
+		t.Errorf("默认规则应只查找: %v", err)
+	}
+	rule.RecordTemplateID = "rtp1"
+	if err := rule.Validate(); err == nil {
+		t.Error("不新建记录时不能指定记录模板")
+	}
+	rule.OnMissing = OnMissingCreate
+	if err := rule.Validate(); err != nil || !rule.Creates() {
+		t.Errorf("新建规则应通过校验: %v", err)
+	}
+	rule.OnMissing = "update"
+	if err := rule.Validate(); err == nil {
+		t.Error("不支持的处理方式应报错")
+	}
+}
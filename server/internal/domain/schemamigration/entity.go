@@ -0,0 +1,164 @@
+package schemamigration
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Operation 结构变更操作
+type Operation string
+
+const (
+	OperationAddColumn        Operation = "add_column"        // 新增列：请求内 ADD COLUMN（可空），后台回填默认值并添加约束
+	OperationDropColumn       Operation = "drop_column"       // 删除列：请求内删除字段元数据，后台 DROP COLUMN
+	OperationRenameField      Operation = "rename_field"      // 重命名：物理列名（DBFieldName）不变，只记录元数据变更
+	OperationAlterConstraints Operation = "alter_constraints" // 修改必填/唯一：取消约束在请求内执行，添加约束由后台回填并校验
+	OperationAlterType        Operation = "alter_type"        // 修改类型：后台 ALTER COLUMN TYPE 成功后再更新字段元数据
+)
+
+// Status 结构变更任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待后台执行
+	StatusRunning   Status = "running"   // 执行中（请求内的同步阶段或后台任务）
+	StatusCompleted Status = "completed" // 已完成
+	StatusFailed    Status = "failed"    // 重试耗尽，需要处理数据后通过 Retry 重新执行
+	StatusCancelled Status = "cancelled" // 字段元数据未保存/未删除，物理变更已撤销
+)
+
+// Phase 任务当前执行阶段
+type Phase string
+
+const (
+	PhaseDDL         Phase = "ddl"         // 执行 ADD/DROP COLUMN
+	PhaseBackfill    Phase = "backfill"    // 分批回填已有记录
+	PhaseConstraints Phase = "constraints" // 添加 NOT NULL / UNIQUE 约束
+	PhaseDone        Phase = "done"
+)
+
+// Migration 动态表结构变更任务
+//
+// 保证字段元数据与物理列不分叉：
+//   - 新增字段先创建物理列再保存元数据，元数据保存失败时删除物理列
+//   - 删除字段先删除元数据再删除物理列（查询只读取字段元数据中的列，多余的列不可见）
+//   - 修改类型先变更物理列类型再更新元数据
+//   - 约束无法添加时任务失败并保留错误，不静默修改字段设置
+type Migration struct {
+	ID            string     `json:"id"`
+	BaseID        string     `json:"base_id"`
+	TableID       string     `json:"table_id"`
+	FieldID       string     `json:"field_id"`
+	Operation     Operation  `json:"operation"`
+	Column        string     `json:"column"`
+	ColumnType    string     `json:"column_type"`
+	FieldType     string     `json:"field_type,omitempty"` // 修改类型的目标字段类型
+	NotNull       bool       `json:"not_null"`
+	Unique        bool       `json:"unique"`
+	BackfillValue *string    `json:"backfill_value,omitempty"` // 回填已有记录的默认值（文本形式，按列类型转换）
+	Status        Status     `json:"status"`
+	Phase         Phase      `json:"phase"`
+	TotalRows     int64      `json:"total_rows"`
+	ProcessedRows int64      `json:"processed_rows"`
+	Attempts      int        `json:"attempts"`
+	Error         string     `json:"error,omitempty"`
+	CreatedBy     string     `json:"created_by"`
+	NextRunAt     time.Time  `json:"next_run_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewMigration 创建结构变更任务（处于请求内的同步阶段）
+func NewMigration(op Operation, baseID, tableID, fieldID, column, createdBy string) *Migration {
+	now := time.Now()
+	return &Migration{
+		ID:        utils.GenerateIDWithPrefix("smg"),
+		BaseID:    baseID,
+		TableID:   tableID,
+		FieldID:   fieldID,
+		Operation: op,
+		Column:    column,
+		Status:    StatusRunning,
+		Phase:     PhaseDDL,
+		CreatedBy: createdBy,
+		NextRunAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// HasDeferredWork 新增列或修改约束是否还需要后台回填或添加约束
+func (m *Migration) HasDeferredWork() bool {
+	return m.BackfillValue != nil || m.NotNull || m.Unique
+}
+
+// Progress 回填进度（0-1），无需回填的任务完成即为 1
+func (m *Migration) Progress() float64 {
+	if m.Status == StatusCompleted {
+		return 1
+	}
+	if m.TotalRows <= 0 {
+		return 0
+	}
+	if m.ProcessedRows >= m.TotalRows {
+		return 1
+	}
+	return float64(m.ProcessedRows) / float64(m.TotalRows)
+}
+
+// Finished 任务是否已结束
+func (m *Migration) Finished() bool {
+	return m.Status == StatusCompleted || m.Status == StatusFailed || m.Status == StatusCancelled
+}
+
+// Enqueue 同步阶段结束，交由后台任务继续执行
+func (m *Migration) Enqueue(phase Phase, now time.Time) {
+	m.Status = StatusPending
+	m.Phase = phase
+	m.NextRunAt = now
+	m.UpdatedAt = now
+}
+
+// Finish 结束任务
+func (m *Migration) Finish(status Status, err error, now time.Time) {
+	m.Status = status
+	if status == StatusCompleted {
+		m.Phase = PhaseDone
+	}
+	if err != nil {
+		m.Error = err.Error()
+	}
+	m.UpdatedAt = now
+	m.FinishedAt = &now
+}
+
+// Retry 执行失败后安排重试（指数退避，最长 10 分钟）
+// 重试次数耗尽时返回 false，调用方应调用 Finish(StatusFailed)
+func (m *Migration) Retry(err error, maxAttempts int, now time.Time) bool {
+	m.Error = err.Error()
+	m.UpdatedAt = now
+	if m.Attempts >= maxAttempts {
+		return false
+	}
+	backoff := time.Duration(1<<uint(m.Attempts)) * 10 * time.Second
+	if backoff > 10*time.Minute {
+		backoff = 10 * time.Minute
+	}
+	m.Status = StatusPending
+	m.NextRunAt = now.Add(backoff)
+	return true
+}
+
+// Restart 重新执行失败的任务（重试次数清零，从失败的阶段继续）
+func (m *Migration) Restart(now time.Time) bool {
+	if m.Status != StatusFailed {
+		return false
+	}
+	m.Attempts = 0
+	m.Error = ""
+	m.FinishedAt = nil
+	m.Enqueue(m.Phase, now)
+	return true
+}
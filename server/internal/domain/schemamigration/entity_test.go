@@ -0,0 +1,66 @@
+package schemamigration
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMigrationRetry(t *testing.T) {
+	now := time.Now()
+	m := NewMigration(OperationAddColumn, "bse1", "tbl1", "fld1", "fld1", "usr1")
+	m.NotNull = true
+
+	var delays []time.Duration
+	for m.Attempts = 1; m.Attempts < 5; m.Attempts++ {
+		if !m.Retry(errors.New("lock timeout"), 5, now) {
+			t.Fatalf("第 %d 次失败应安排重试", m.Attempts)
+		}
+		if m.Status != StatusPending || m.Error != "lock timeout" {
+			t.Fatalf("重试后应回到 pending 并记录错误，得到 %s %q", m.Status, m.Error)
+		}
+		delays = append(delays, m.NextRunAt.Sub(now))
+	}
+	for i := 1; i < len(delays); i++ {
+		if delays[i] <= delays[i-1] {
+			t.Errorf("重试间隔应递增: %v", delays)
+		}
+	}
+	if m.Retry(errors.New("lock timeout"), 5, now) {
+		t.Fatalf("重试次数耗尽后不应再重试")
+	}
+
+	m.Finish(StatusFailed, errors.New("column contains null values"), now)
+	if !m.Finished() || m.FinishedAt == nil || m.Error != "column contains null values" {
+		t.Errorf("失败的任务应已结束并记录错误，得到 %+v", m)
+	}
+
+	// 处理数据后重新执行：从失败的阶段继续，重试次数清零
+	m.Phase = PhaseConstraints
+	if !m.Restart(now) || m.Status != StatusPending || m.Phase != PhaseConstraints || m.Attempts != 0 || m.FinishedAt != nil {
+		t.Errorf("失败的任务应可重新执行，得到 %+v", m)
+	}
+	if m.Restart(now) {
+		t.Errorf("未失败的任务不能重新执行")
+	}
+}
+
+func TestMigrationProgress(t *testing.T) {
+	m := NewMigration(OperationAddColumn, "bse1", "tbl1", "fld1", "fld1", "usr1")
+	if m.HasDeferredWork() || m.Progress() != 0 {
+		t.Fatalf("无默认值和约束的新增列不需要后台任务")
+	}
+
+	value := "0"
+	m.BackfillValue = &value
+	m.Enqueue(PhaseBackfill, time.Now())
+	m.TotalRows, m.ProcessedRows = 4000, 1000
+	if !m.HasDeferredWork() || m.Progress() != 0.25 {
+		t.Errorf("回填进度应为 0.25，得到 %v", m.Progress())
+	}
+
+	m.Finish(StatusCompleted, nil, time.Now())
+	if m.Progress() != 1 || m.Phase != PhaseDone {
+		t.Errorf("完成的任务进度应为 1，得到 %v %s", m.Progress(), m.Phase)
+	}
+}
@@ -0,0 +1,20 @@
+package schemamigration
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 结构变更任务仓储接口
+type Repository interface {
+	// Save 保存任务（新增或更新）
+	Save(ctx context.Context, migration *Migration) error
+	// FindByID 获取任务（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Migration, error)
+	// ListByTable 列出表格最近的结构变更任务（按创建时间倒序）
+	ListByTable(ctx context.Context, tableID string, limit int) ([]*Migration, error)
+	// Claim 领取一个可执行的任务并标记为执行中，无可执行任务时返回 nil
+	// 可执行：到达重试时间的 pending 任务，或 staleBefore 之后无进度的 running 任务；
+	// 同一张表同时只执行一个任务
+	Claim(ctx context.Context, now, staleBefore time.Time) (*Migration, error)
+}
@@ -0,0 +1,343 @@
+package schemaspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LiveTable Base 中现有的表
+type LiveTable struct {
+	ID          string
+	Name        string
+	Description string
+	Fields      []*LiveField
+	Views       []*LiveView
+}
+
+// LiveField 现有字段
+type LiveField struct {
+	ID          string
+	Name        string
+	APIKey      string
+	Type        string
+	Description string
+	Required    bool
+	Unique      bool
+	Primary     bool
+	Options     map[string]interface{} // 现有选项（仅用于导出）
+}
+
+// LiveView 现有视图
+type LiveView struct {
+	ID          string
+	Name        string
+	Type        string
+	Description string
+	Filter      interface{}
+	Sort        interface{}
+}
+
+// Field 按名称查找字段
+func (t *LiveTable) Field(name string) *LiveField {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// View 按名称查找视图
+func (t *LiveTable) View(name string) *LiveView {
+	for _, view := range t.Views {
+		if view.Name == name {
+			return view
+		}
+	}
+	return nil
+}
+
+// ChangeKind 变更类型
+type ChangeKind string
+
+const (
+	ChangeCreateTable ChangeKind = "create_table"
+	ChangeUpdateTable ChangeKind = "update_table"
+	ChangeDeleteTable ChangeKind = "delete_table"
+	ChangeCreateField ChangeKind = "create_field"
+	ChangeUpdateField ChangeKind = "update_field"
+	ChangeDeleteField ChangeKind = "delete_field"
+	ChangeCreateView  ChangeKind = "create_view"
+	ChangeUpdateView  ChangeKind = "update_view"
+	ChangeDeleteView  ChangeKind = "delete_view"
+)
+
+// Change 一项结构变更
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Table    string     `json:"table"`
+	Name     string     `json:"name,omitempty"`     // 字段名或视图名
+	TargetID string     `json:"targetId,omitempty"` // 更新/删除的对象 ID
+	Details  []string   `json:"details,omitempty"`  // 变化的属性，如 "required: false -> true"
+}
+
+// Conflict 无法自动应用的差异（如字段类型不同），需要人工处理
+type Conflict struct {
+	Table  string `json:"table"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Plan 结构描述与现有 Base 的差异
+// 变更顺序即应用顺序：先建表，再建字段（普通字段在引用其他字段的字段之前）、更新字段、建/改视图，最后删除
+type Plan struct {
+	Changes   []Change   `json:"changes"`
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// Empty 是否没有任何差异
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0 && len(p.Conflicts) == 0
+}
+
+// Diff 对比结构描述与现有 Base
+// prune 为 true 时删除描述中未声明的表、字段与视图（主字段不删除；未声明视图的表不删除视图）
+func Diff(spec *Spec, live []*LiveTable, prune bool) *Plan {
+	plan := &Plan{Changes: []Change{}, Conflicts: []Conflict{}}
+	liveByName := make(map[string]*LiveTable, len(live))
+	for _, table := range live {
+		liveByName[table.Name] = table
+	}
+
+	var fieldCreates, refFieldCreates, fieldUpdates, viewChanges, deletes []Change
+	for _, table := range spec.Tables {
+		current := liveByName[table.Name]
+		if current == nil {
+			plan.Changes = append(plan.Changes, Change{Kind: ChangeCreateTable, Table: table.Name})
+			continue
+		}
+		if table.Description != current.Description {
+			plan.Changes = append(plan.Changes, Change{
+				Kind: ChangeUpdateTable, Table: table.Name, TargetID: current.ID,
+				Details: []string{changed("description", current.Description, table.Description)},
+			})
+		}
+
+		for _, field := range table.Fields {
+			existing := current.Field(field.Name)
+			if existing == nil {
+				change := Change{Kind: ChangeCreateField, Table: table.Name, Name: field.Name}
+				if field.HasReferences() {
+					refFieldCreates = append(refFieldCreates, change)
+				} else {
+					fieldCreates = append(fieldCreates, change)
+				}
+				continue
+			}
+			if existing.Type != field.Type {
+				plan.Conflicts = append(plan.Conflicts, Conflict{
+					Table: table.Name, Name: field.Name,
+					Reason: fmt.Sprintf("字段类型不同（现有 %s，描述 %s），不会自动转换", existing.Type, field.Type),
+				})
+				continue
+			}
+			if details := fieldDetails(existing, field); len(details) > 0 {
+				fieldUpdates = append(fieldUpdates, Change{
+					Kind: ChangeUpdateField, Table: table.Name, Name: field.Name, TargetID: existing.ID, Details: details,
+				})
+			}
+		}
+
+		for _, view := range table.Views {
+			existing := current.View(view.Name)
+			if existing == nil {
+				viewChanges = append(viewChanges, Change{Kind: ChangeCreateView, Table: table.Name, Name: view.Name})
+				continue
+			}
+			if existing.Type != view.Type {
+				plan.Conflicts = append(plan.Conflicts, Conflict{
+					Table: table.Name, Name: view.Name,
+					Reason: fmt.Sprintf("视图类型不同（现有 %s，描述 %s），不会自动转换", existing.Type, view.Type),
+				})
+				continue
+			}
+			if details := viewDetails(existing, view, current, live); len(details) > 0 {
+				viewChanges = append(viewChanges, Change{
+					Kind: ChangeUpdateView, Table: table.Name, Name: view.Name, TargetID: existing.ID, Details: details,
+				})
+			}
+		}
+
+		if prune {
+			if len(table.Views) > 0 {
+				for _, view := range current.Views {
+					if table.View(view.Name) == nil {
+						deletes = append(deletes, Change{Kind: ChangeDeleteView, Table: table.Name, Name: view.Name, TargetID: view.ID})
+					}
+				}
+			}
+			for _, field := range current.Fields {
+				if table.Field(field.Name) == nil && !field.Primary {
+					deletes = append(deletes, Change{Kind: ChangeDeleteField, Table: table.Name, Name: field.Name, TargetID: field.ID})
+				}
+			}
+		}
+	}
+	if prune {
+		for _, table := range live {
+			if spec.Table(table.Name) == nil {
+				deletes = append(deletes, Change{Kind: ChangeDeleteTable, Table: table.Name, TargetID: table.ID})
+			}
+		}
+	}
+
+	plan.Changes = append(plan.Changes, fieldCreates...)
+	plan.Changes = append(plan.Changes, refFieldCreates...)
+	plan.Changes = append(plan.Changes, fieldUpdates...)
+	plan.Changes = append(plan.Changes, viewChanges...)
+	plan.Changes = append(plan.Changes, deletes...)
+	return plan
+}
+
+func fieldDetails(existing *LiveField, field *FieldSpec) []string {
+	var details []string
+	if existing.Description != field.Description {
+		details = append(details, changed("description", existing.Description, field.Description))
+	}
+	if field.APIKey != "" && existing.APIKey != field.APIKey {
+		details = append(details, changed("apiKey", existing.APIKey, field.APIKey))
+	}
+	if existing.Required != field.Required {
+		details = append(details, changed("required", existing.Required, field.Required))
+	}
+	if existing.Unique != field.Unique {
+		details = append(details, changed("unique", existing.Unique, field.Unique))
+	}
+	return details
+}
+
+func viewDetails(existing *LiveView, view *ViewSpec, table *LiveTable, live []*LiveTable) []string {
+	var details []string
+	if existing.Description != view.Description {
+		details = append(details, changed("description", existing.Description, view.Description))
+	}
+	if view.Filter != nil && !contains(existing.Filter, ResolveReferences(view.Filter, table, live)) {
+		details = append(details, "filter")
+	}
+	if len(view.Sort) > 0 && !contains(existing.Sort, ResolveReferences(view.Sort, table, live)) {
+		details = append(details, "sort")
+	}
+	return details
+}
+
+func changed(name string, from, to interface{}) string {
+	return fmt.Sprintf("%s: %v -> %v", name, from, to)
+}
+
+// ResolveReferences 将值中的字段名 / 表名引用解析为 ID（找不到时保持原值，视为已是 ID）
+// 字段引用先在 table 中查找，"表名.字段名" 形式在对应表中查找
+func ResolveReferences(value interface{}, table *LiveTable, live []*LiveTable) interface{} {
+	return walkReferences(value, "", func(kind, ref string) string {
+		switch kind {
+		case "table":
+			for _, t := range live {
+				if t.Name == ref {
+					return t.ID
+				}
+			}
+		case "field":
+			if table != nil {
+				if field := table.Field(ref); field != nil {
+					return field.ID
+				}
+			}
+			if dot := strings.Index(ref, "."); dot > 0 {
+				for _, t := range live {
+					if t.Name != ref[:dot] {
+						continue
+					}
+					if field := t.Field(ref[dot+1:]); field != nil {
+						return field.ID
+					}
+				}
+			}
+		}
+		return ""
+	})
+}
+
+// NameReferences 将值中的字段 ID / 表 ID 替换为名称（ResolveReferences 的逆操作，用于导出）
+func NameReferences(value interface{}, table *LiveTable, live []*LiveTable) interface{} {
+	return walkReferences(value, "", func(kind, ref string) string {
+		for _, t := range live {
+			if kind == "table" && t.ID == ref {
+				return t.Name
+			}
+			if kind != "field" {
+				continue
+			}
+			for _, field := range t.Fields {
+				if field.ID != ref {
+					continue
+				}
+				if t == table {
+					return field.Name
+				}
+				return t.Name + "." + field.Name
+			}
+		}
+		return ""
+	})
+}
+
+// contains 现有值是否包含期望值：对象按键逐个比较（现有值可有额外的键），数组逐项比较
+// 服务端会补全默认属性，按包含关系比较保证重复应用时不产生差异
+func contains(actual, expected interface{}) bool {
+	return containsValue(toJSONValue(actual), toJSONValue(expected))
+}
+
+func containsValue(actual, expected interface{}) bool {
+	switch want := expected.(type) {
+	case map[string]interface{}:
+		got, ok := actual.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if !containsValue(got[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		got, ok := actual.([]interface{})
+		if !ok || len(got) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !containsValue(got[i], want[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(actual, expected)
+	}
+}
+
+func toJSONValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return value
+	}
+	return out
+}
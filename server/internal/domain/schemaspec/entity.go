@@ -0,0 +1,287 @@
+package schemaspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion 当前支持的结构描述格式版本
+const CurrentVersion = 1
+
+// Spec Base 结构描述（schema as code）
+// 表、字段、视图均按名称匹配；选项、过滤、排序中以 FieldId / TableId 结尾的键填写字段名 / 表名，
+// 应用时解析为 ID（其他表的字段写作 "表名.字段名"）
+type Spec struct {
+	Version int          `yaml:"version" json:"version"`
+	Tables  []*TableSpec `yaml:"tables" json:"tables"`
+}
+
+// TableSpec 表描述
+// 未声明视图时不管理该表的视图
+type TableSpec struct {
+	Name        string       `yaml:"name" json:"name"`
+	Description string       `yaml:"description,omitempty" json:"description,omitempty"`
+	Fields      []*FieldSpec `yaml:"fields" json:"fields"`
+	Views       []*ViewSpec  `yaml:"views,omitempty" json:"views,omitempty"`
+}
+
+// FieldSpec 字段描述
+// 选项只在创建字段时使用，已有字段只同步描述、必填与唯一约束
+type FieldSpec struct {
+	Name        string                 `yaml:"name" json:"name"`
+	APIKey      string                 `yaml:"apiKey,omitempty" json:"apiKey,omitempty"` // 为空时不管理（新建字段由名称生成）
+	Type        string                 `yaml:"type" json:"type"`
+	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool                   `yaml:"required,omitempty" json:"required,omitempty"`
+	Unique      bool                   `yaml:"unique,omitempty" json:"unique,omitempty"`
+	Primary     bool                   `yaml:"primary,omitempty" json:"primary,omitempty"` // 建表时作为第一个字段（主字段）
+	Options     map[string]interface{} `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+// ViewSpec 视图描述
+// 过滤与排序为空时不管理
+type ViewSpec struct {
+	Name        string                   `yaml:"name" json:"name"`
+	Type        string                   `yaml:"type" json:"type"`
+	Description string                   `yaml:"description,omitempty" json:"description,omitempty"`
+	Filter      map[string]interface{}   `yaml:"filter,omitempty" json:"filter,omitempty"`
+	Sort        []map[string]interface{} `yaml:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// Parse 解析 YAML 结构描述并校验（不允许未知字段）
+func Parse(data []byte) (*Spec, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var spec Spec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("解析结构描述失败: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Marshal 序列化为 YAML
+func (s *Spec) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(s); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Validate 校验结构描述，并将选项、过滤、排序规范化为 JSON 兼容的值
+func (s *Spec) Validate() error {
+	if s.Version != CurrentVersion {
+		return fmt.Errorf("不支持的结构描述版本: %d", s.Version)
+	}
+	tables := make(map[string]bool, len(s.Tables))
+	for _, table := range s.Tables {
+		if table == nil || strings.TrimSpace(table.Name) == "" {
+			return fmt.Errorf("表名不能为空")
+		}
+		if tables[table.Name] {
+			return fmt.Errorf("表 %s 重复声明", table.Name)
+		}
+		tables[table.Name] = true
+		if err := table.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TableSpec) validate() error {
+	if len(t.Fields) == 0 {
+		return fmt.Errorf("表 %s 至少需要一个字段", t.Name)
+	}
+	fields := make(map[string]bool, len(t.Fields))
+	apiKeys := make(map[string]bool, len(t.Fields))
+	primaries := 0
+	for _, field := range t.Fields {
+		if field == nil || strings.TrimSpace(field.Name) == "" {
+			return fmt.Errorf("表 %s 中存在未命名的字段", t.Name)
+		}
+		if fields[field.Name] {
+			return fmt.Errorf("表 %s 中字段 %s 重复声明", t.Name, field.Name)
+		}
+		fields[field.Name] = true
+		if field.Type == "" {
+			return fmt.Errorf("字段 %s.%s 缺少类型", t.Name, field.Name)
+		}
+		if field.APIKey != "" {
+			if apiKeys[field.APIKey] {
+				return fmt.Errorf("表 %s 中 API 键 %s 重复声明", t.Name, field.APIKey)
+			}
+			apiKeys[field.APIKey] = true
+		}
+		if field.Primary {
+			primaries++
+		}
+		options, err := normalizeMap(field.Options)
+		if err != nil {
+			return fmt.Errorf("字段 %s.%s 选项无效: %w", t.Name, field.Name, err)
+		}
+		field.Options = options
+	}
+	if primaries > 1 {
+		return fmt.Errorf("表 %s 只能有一个主字段", t.Name)
+	}
+	if len(t.PlainFields()) == 0 {
+		return fmt.Errorf("表 %s 至少需要一个不引用其他字段的字段", t.Name)
+	}
+
+	views := make(map[string]bool, len(t.Views))
+	for _, view := range t.Views {
+		if view == nil || strings.TrimSpace(view.Name) == "" {
+			return fmt.Errorf("表 %s 中存在未命名的视图", t.Name)
+		}
+		if views[view.Name] {
+			return fmt.Errorf("表 %s 中视图 %s 重复声明", t.Name, view.Name)
+		}
+		views[view.Name] = true
+		if view.Type == "" {
+			return fmt.Errorf("视图 %s.%s 缺少类型", t.Name, view.Name)
+		}
+		filter, err := normalizeMap(view.Filter)
+		if err != nil {
+			return fmt.Errorf("视图 %s.%s 过滤条件无效: %w", t.Name, view.Name, err)
+		}
+		view.Filter = filter
+		for i, item := range view.Sort {
+			if view.Sort[i], err = normalizeMap(item); err != nil {
+				return fmt.Errorf("视图 %s.%s 排序无效: %w", t.Name, view.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Table 按名称查找表
+func (s *Spec) Table(name string) *TableSpec {
+	for _, table := range s.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Field 按名称查找字段
+func (t *TableSpec) Field(name string) *FieldSpec {
+	for _, field := range t.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// PlainFields 不引用其他字段或表的字段（建表时一并创建），主字段排在首位
+func (t *TableSpec) PlainFields() []*FieldSpec {
+	fields := make([]*FieldSpec, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		if field.HasReferences() {
+			continue
+		}
+		if field.Primary {
+			fields = append([]*FieldSpec{field}, fields...)
+		} else {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// View 按名称查找视图
+func (t *TableSpec) View(name string) *ViewSpec {
+	for _, view := range t.Views {
+		if view.Name == name {
+			return view
+		}
+	}
+	return nil
+}
+
+// HasReferences 字段选项是否引用了其他字段或表（此类字段在普通字段之后创建）
+func (f *FieldSpec) HasReferences() bool {
+	found := false
+	walkReferences(f.Options, "", func(string, string) string {
+		found = true
+		return ""
+	})
+	return found
+}
+
+// normalizeMap 经 JSON 往返，将 YAML 解码出的值统一为 JSON 兼容类型（数字统一为 float64）
+func normalizeMap(m map[string]interface{}) (map[string]interface{}, error) {
+	if m == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isReferenceKey 键是否表示字段或表引用
+func isReferenceKey(key string) (kind string, ok bool) {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasSuffix(lower, "fieldid"), strings.HasSuffix(lower, "fieldids"):
+		return "field", true
+	case strings.HasSuffix(lower, "tableid"), strings.HasSuffix(lower, "tableids"):
+		return "table", true
+	}
+	return "", false
+}
+
+// walkReferences 遍历值中的引用键，用 replace 的返回值替换字符串引用（返回空串表示保持不变）
+func walkReferences(value interface{}, key string, replace func(kind, ref string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = walkReferences(item, k, replace)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = walkReferences(item, key, replace)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = walkReferences(item, key, replace)
+		}
+		return out
+	case string:
+		kind, ok := isReferenceKey(key)
+		if !ok || v == "" {
+			return v
+		}
+		if replaced := replace(kind, v); replaced != "" {
+			return replaced
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,314 @@
+package schemaspec
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleSpec = `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - name: 名称
+        type: singleLineText
+        primary: true
+      - name: 等级
+        type: singleSelect
+        required: true
+        options:
+          choices:
+            - name: A
+      - name: 订单数
+        type: rollup
+        options:
+          linkFieldId: 订单
+          rollupFieldId: 订单.金额
+          aggregationFunc: sum
+    views:
+      - name: 全部
+        type: grid
+        sort:
+          - fieldId: 名称
+            order: asc
+`
+
+func TestParseValidatesSpec(t *testing.T) {
+	spec, err := Parse([]byte(sampleSpec))
+	if err != nil {
+		t.Fatalf("期望解析成功，得到 %v", err)
+	}
+	table := spec.Table("客户")
+	if table == nil || len(table.Fields) != 3 || table.View("全部") == nil {
+		t.Fatalf("解析结果不完整: %+v", spec)
+	}
+	if table.Field("等级").HasReferences() || !table.Field("订单数").HasReferences() {
+		t.Error("引用识别错误")
+	}
+
+	invalid := map[string]string{
+		"版本":    "version: 2\ntables: []",
+		"未知字段":  "version: 1\ntable: []",
+		"重复表名":  "version: 1\ntables:\n  - {name: a, fields: [{name: x, type: number}]}\n  - {name: a, fields: [{name: x, type: number}]}",
+		"无字段":   "version: 1\ntables:\n  - {name: a, fields: []}",
+		"缺少类型":  "version: 1\ntables:\n  - {name: a, fields: [{name: x}]}",
+		"多个主字段": "version: 1\ntables:\n  - {name: a, fields: [{name: x, type: number, primary: true}, {name: y, type: number, primary: true}]}",
+	}
+	for name, doc := range invalid {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("%s: 期望校验失败", name)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	spec, err := Parse([]byte(sampleSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := spec.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(data)
+	if err != nil {
+		t.Fatalf("导出结果无法重新解析: %v\n%s", err, data)
+	}
+	if plan := Diff(again, liveFromSpec(spec), true); !plan.Empty() {
+		t.Errorf("往返后不应有差异: %+v", plan)
+	}
+}
+
+func TestDiffCreatesInDependencyOrder(t *testing.T) {
+	spec, _ := Parse([]byte(sampleSpec))
+	plan := Diff(spec, nil, false)
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != ChangeCreateTable {
+		t.Fatalf("空 Base 期望只建表，得到 %+v", plan.Changes)
+	}
+
+	live := []*LiveTable{{ID: "tbl1", Name: "客户", Fields: []*LiveField{{ID: "fld1", Name: "名称", Type: "singleLineText", Primary: true}}}}
+	plan = Diff(spec, live, false)
+	var kinds []string
+	for _, change := range plan.Changes {
+		kinds = append(kinds, string(change.Kind)+":"+change.Name)
+	}
+	want := "create_field:等级,create_field:订单数,create_view:全部"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Errorf("变更顺序错误: %s", got)
+	}
+}
+
+func TestDiffIsIdempotent(t *testing.T) {
+	spec, _ := Parse([]byte(sampleSpec))
+	live := liveFromSpec(spec)
+	// 服务端补全的默认属性与已解析的字段 ID 不应产生差异
+	live[0].Views[0].Sort = []map[string]interface{}{{"fieldId": "fld_名称", "order": "asc"}}
+	live[0].Views[0].Filter = map[string]interface{}{"operator": "and", "filters": []interface{}{}}
+	if plan := Diff(spec, live, true); !plan.Empty() {
+		t.Errorf("期望无差异，得到 %+v", plan)
+	}
+}
+
+func TestDiffUpdatesConflictsAndPrune(t *testing.T) {
+	spec, _ := Parse([]byte(sampleSpec))
+	live := liveFromSpec(spec)
+	live[0].Fields[1].Required = false
+	live[0].Fields[2].Type = "number"
+	live[0].Fields = append(live[0].Fields, &LiveField{ID: "fld_old", Name: "旧字段", Type: "number"})
+	live[0].Views = append(live[0].Views, &LiveView{ID: "viw_old", Name: "旧视图", Type: "grid"})
+	live = append(live, &LiveTable{ID: "tbl_old", Name: "旧表"})
+
+	plan := Diff(spec, live, false)
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != ChangeUpdateField || plan.Changes[0].TargetID != "fld_等级" {
+		t.Errorf("期望只更新等级字段，得到 %+v", plan.Changes)
+	}
+	if len(plan.Conflicts) != 1 || plan.Conflicts[0].Name != "订单数" {
+		t.Errorf("期望类型冲突，得到 %+v", plan.Conflicts)
+	}
+
+	plan = Diff(spec, live, true)
+	deleted := map[ChangeKind]string{}
+	for _, change := range plan.Changes {
+		if strings.HasPrefix(string(change.Kind), "delete_") {
+			deleted[change.Kind] = change.TargetID
+		}
+	}
+	if deleted[ChangeDeleteField] != "fld_old" || deleted[ChangeDeleteView] != "viw_old" || deleted[ChangeDeleteTable] != "tbl_old" {
+		t.Errorf("prune 删除项错误: %+v", deleted)
+	}
+}
+
+func TestDiffAPIKey(t *testing.T) {
+	spec, _ := Parse([]byte(sampleSpec))
+	live := liveFromSpec(spec)
+	live[0].Fields[0].APIKey = "name"
+
+	// 描述未声明 API 键时不管理
+	if plan := Diff(spec, live, false); !plan.Empty() {
+		t.Errorf("未声明 API 键时期望无差异，得到 %+v", plan)
+	}
+
+	spec.Tables[0].Fields[0].APIKey = "customer_name"
+	plan := Diff(spec, live, false)
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != ChangeUpdateField || plan.Changes[0].Details[0] != "apiKey: name -> customer_name" {
+		t.Errorf("期望更新 API 键，得到 %+v", plan.Changes)
+	}
+
+	spec.Tables[0].Fields[1].APIKey = "customer_name"
+	if err := spec.Validate(); err == nil {
+		t.Error("同表 API 键重复应校验失败")
+	}
+}
+
+func TestResolveAndNameReferences(t *testing.T) {
+	live := []*LiveTable{
+		{ID: "tbl_a", Name: "客户", Fields: []*LiveField{{ID: "fld_a1", Name: "订单"}}},
+		{ID: "tbl_b", Name: "订单", Fields: []*LiveField{{ID: "fld_b1", Name: "金额"}}},
+	}
+	options := map[string]interface{}{
+		"linkFieldId":     "订单",
+		"rollupFieldId":   "订单.金额",
+		"foreignTableId":  "订单",
+		"visibleFieldIds": []interface{}{"订单"},
+		"aggregationFunc": "sum",
+	}
+	resolved := ResolveReferences(options, live[0], live).(map[string]interface{})
+	if resolved["linkFieldId"] != "fld_a1" || resolved["rollupFieldId"] != "fld_b1" || resolved["foreignTableId"] != "tbl_b" {
+		t.Errorf("引用解析错误: %+v", resolved)
+	}
+	if resolved["visibleFieldIds"].([]interface{})[0] != "fld_a1" || resolved["aggregationFunc"] != "sum" {
+		t.Errorf("数组引用或普通键处理错误: %+v", resolved)
+	}
+	named := NameReferences(resolved, live[0], live).(map[string]interface{})
+	if named["linkFieldId"] != "订单" || named["rollupFieldId"] != "订单.金额" || named["foreignTableId"] != "订单" {
+		t.Errorf("引用还原错误: %+v", named)
+	}
+}
+
+// liveFromSpec 按结构描述构造完全一致的现有 Base（ID 为 "fld_" / "viw_" 加名称）
+func liveFromSpec(spec *Spec) []*LiveTable {
+	var live []*LiveTable
+	for _, table := range spec.Tables {
+		current := &LiveTable{ID: "tbl_" + table.Name, Name: table.Name, Description: table.Description}
+		for _, field := range table.Fields {
+			current.Fields = append(current.Fields, &LiveField{
+				ID: "fld_" + field.Name, Name: field.Name, Type: field.Type, Description: field.Description,
+				Required: field.Required, Unique: field.Unique, Primary: field.Primary,
+			})
+		}
+		live = append(live, current)
+	}
+	for i, table := range spec.Tables {
+		for _, view := range table.Views {
+			live[i].Views = append(live[i].Views, &LiveView{
+				ID: "viw_" + view.Name, Name: view.Name, Type: view.Type, Description: view.Description,
+				Filter: ResolveReferences(view.Filter, live[i], live), Sort: ResolveReferences(view.Sort, live[i], live),
+			})
+		}
+	}
+	return live
+}
+
+func TestMergeTakesOneSidedChanges(t *testing.T) {
+	base := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 备注, type: longText}
+  - name: 旧表
+    fields:
+      - {name: 名称, type: singleLineText}
+`)
+	// 生产：新增字段「电话」
+	ours := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 备注, type: longText}
+      - {name: 电话, type: phone}
+  - name: 旧表
+    fields:
+      - {name: 名称, type: singleLineText}
+`)
+	// 沙盒：备注改为必填、删除旧表、新增订单表
+	theirs := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 备注, type: longText, required: true}
+  - name: 订单
+    fields:
+      - {name: 编号, type: autoNumber}
+`)
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Fatalf("期望无冲突，得到 %+v", conflicts)
+	}
+	customers := merged.Table("客户")
+	if customers == nil || customers.Field("电话") == nil || !customers.Field("备注").Required {
+		t.Errorf("客户表合并错误: %+v", customers)
+	}
+	if merged.Table("旧表") != nil || merged.Table("订单") == nil {
+		t.Errorf("表级合并错误: %+v", tableNames(merged))
+	}
+	if got := strings.Join(tableNames(merged), ","); got != "客户,订单" {
+		t.Errorf("表顺序错误: %s", got)
+	}
+}
+
+func TestMergeReportsConflicts(t *testing.T) {
+	base := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 等级, type: singleLineText}
+      - {name: 备注, type: longText}
+`)
+	ours := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 等级, type: singleLineText, required: true}
+      - {name: 备注, type: longText, description: 生产修改}
+      - {name: 电话, type: phone}
+`)
+	theirs := mustParse(t, `
+version: 1
+tables:
+  - name: 客户
+    fields:
+      - {name: 名称, type: singleLineText}
+      - {name: 等级, type: singleLineText, unique: true}
+      - {name: 电话, type: singleLineText}
+`)
+	_, conflicts := Merge(base, ours, theirs)
+	reasons := map[string]string{}
+	for _, c := range conflicts {
+		reasons[c.Name] = c.Reason
+	}
+	if len(conflicts) != 3 || reasons["等级"] == "" || reasons["备注"] == "" || reasons["电话"] == "" {
+		t.Errorf("冲突识别错误: %+v", conflicts)
+	}
+	if !strings.Contains(reasons["备注"], "沙盒删除") || !strings.Contains(reasons["电话"], "都新增") {
+		t.Errorf("冲突原因错误: %+v", reasons)
+	}
+}
+
+func mustParse(t *testing.T, doc string) *Spec {
+	t.Helper()
+	spec, err := Parse([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
@@ -0,0 +1,191 @@
+package schemaspec
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Merge 三方合并结构描述：base 为分支时的共同祖先，ours 为生产当前结构，theirs 为沙盒当前结构
+// 只有一方改动的对象取改动方；双方改成一致时取其一；双方改动不同时记为冲突。
+// 表按字段与视图逐个合并，因此双方在同一张表中改动不同字段不算冲突
+func Merge(base, ours, theirs *Spec) (*Spec, []Conflict) {
+	merged := &Spec{Version: CurrentVersion}
+	conflicts := []Conflict{}
+
+	for _, name := range unionNames(tableNames(ours), tableNames(theirs), tableNames(base)) {
+		b, o, t := base.Table(name), ours.Table(name), theirs.Table(name)
+		switch {
+		case tableEqual(b, t):
+			if o != nil {
+				merged.Tables = append(merged.Tables, o)
+			}
+		case tableEqual(b, o):
+			if t != nil {
+				merged.Tables = append(merged.Tables, t)
+			}
+		case tableEqual(o, t):
+			if o != nil {
+				merged.Tables = append(merged.Tables, o)
+			}
+		case b != nil && o != nil && t != nil:
+			table, tableConflicts := mergeTable(b, o, t)
+			merged.Tables = append(merged.Tables, table)
+			conflicts = append(conflicts, tableConflicts...)
+		default:
+			conflicts = append(conflicts, Conflict{Table: name, Reason: conflictReason(b != nil, o != nil, t != nil, "表")})
+			if o != nil {
+				merged.Tables = append(merged.Tables, o)
+			}
+		}
+	}
+	return merged, conflicts
+}
+
+func mergeTable(base, ours, theirs *TableSpec) (*TableSpec, []Conflict) {
+	var conflicts []Conflict
+	table := &TableSpec{Name: ours.Name, Description: ours.Description}
+	switch {
+	case base.Description == ours.Description:
+		table.Description = theirs.Description
+	case base.Description != theirs.Description && ours.Description != theirs.Description:
+		conflicts = append(conflicts, Conflict{Table: ours.Name, Reason: "生产与沙盒都修改了表描述"})
+	}
+
+	for _, name := range unionNames(fieldNames(ours), fieldNames(theirs), fieldNames(base)) {
+		b, o, t := base.Field(name), ours.Field(name), theirs.Field(name)
+		switch {
+		case fieldEqual(b, t):
+			if o != nil {
+				table.Fields = append(table.Fields, o)
+			}
+		case fieldEqual(b, o):
+			if t != nil {
+				table.Fields = append(table.Fields, t)
+			}
+		case fieldEqual(o, t):
+			if o != nil {
+				table.Fields = append(table.Fields, o)
+			}
+		default:
+			conflicts = append(conflicts, Conflict{Table: ours.Name, Name: name, Reason: conflictReason(b != nil, o != nil, t != nil, "字段")})
+			if o != nil {
+				table.Fields = append(table.Fields, o)
+			}
+		}
+	}
+
+	for _, name := range unionNames(viewNames(ours), viewNames(theirs), viewNames(base)) {
+		b, o, t := base.View(name), ours.View(name), theirs.View(name)
+		switch {
+		case viewEqual(b, t):
+			if o != nil {
+				table.Views = append(table.Views, o)
+			}
+		case viewEqual(b, o):
+			if t != nil {
+				table.Views = append(table.Views, t)
+			}
+		case viewEqual(o, t):
+			if o != nil {
+				table.Views = append(table.Views, o)
+			}
+		default:
+			conflicts = append(conflicts, Conflict{Table: ours.Name, Name: name, Reason: conflictReason(b != nil, o != nil, t != nil, "视图")})
+			if o != nil {
+				table.Views = append(table.Views, o)
+			}
+		}
+	}
+	return table, conflicts
+}
+
+// conflictReason 描述双方各自做了什么
+func conflictReason(inBase, inOurs, inTheirs bool, kind string) string {
+	switch {
+	case !inBase:
+		return fmt.Sprintf("生产与沙盒都新增了同名%s，但定义不同", kind)
+	case !inOurs:
+		return fmt.Sprintf("生产已删除该%s，沙盒中对其做了修改", kind)
+	case !inTheirs:
+		return fmt.Sprintf("沙盒删除了该%s，生产中对其做了修改", kind)
+	default:
+		return fmt.Sprintf("生产与沙盒都修改了该%s，且修改不同", kind)
+	}
+}
+
+func tableEqual(a, b *TableSpec) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Name != b.Name || a.Description != b.Description || len(a.Fields) != len(b.Fields) || len(a.Views) != len(b.Views) {
+		return false
+	}
+	for _, field := range a.Fields {
+		if !fieldEqual(field, b.Field(field.Name)) {
+			return false
+		}
+	}
+	for _, view := range a.Views {
+		if !viewEqual(view, b.View(view.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldEqual(a, b *FieldSpec) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Name == b.Name && a.Type == b.Type && a.Description == b.Description &&
+		a.Required == b.Required && a.Unique == b.Unique && a.Primary == b.Primary &&
+		reflect.DeepEqual(toJSONValue(a.Options), toJSONValue(b.Options))
+}
+
+func viewEqual(a, b *ViewSpec) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Name == b.Name && a.Type == b.Type && a.Description == b.Description &&
+		reflect.DeepEqual(toJSONValue(a.Filter), toJSONValue(b.Filter)) &&
+		reflect.DeepEqual(toJSONValue(a.Sort), toJSONValue(b.Sort))
+}
+
+// unionNames 按出现顺序合并名称列表（生产顺序优先，沙盒新增的排在后面）
+func unionNames(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, list := range lists {
+		for _, name := range list {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func tableNames(s *Spec) []string {
+	names := make([]string, 0, len(s.Tables))
+	for _, table := range s.Tables {
+		names = append(names, table.Name)
+	}
+	return names
+}
+
+func fieldNames(t *TableSpec) []string {
+	names := make([]string, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+func viewNames(t *TableSpec) []string {
+	names := make([]string, 0, len(t.Views))
+	for _, view := range t.Views {
+		names = append(names, view.Name)
+	}
+	return names
+}
@@ -0,0 +1,173 @@
+package security
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// SensitiveOperation 需要重新认证的敏感操作
+type SensitiveOperation string
+
+const (
+	OperationSchemaDelete SensitiveOperation = "schema_delete" // 删除 Base/表/字段
+	OperationDataExport   SensitiveOperation = "data_export"   // 导出数据
+)
+
+// ViolationRule 违反的策略规则
+type ViolationRule string
+
+const (
+	RuleIPAllowlist        ViolationRule = "ip_allowlist"
+	RuleSessionMaxAge      ViolationRule = "session_max_age"
+	RuleConcurrentSessions ViolationRule = "concurrent_sessions"
+	RuleReauthRequired     ViolationRule = "reauth_required"
+)
+
+// DefaultReauthWindow 默认重新认证有效期
+const DefaultReauthWindow = 5 * time.Minute
+
+// Policy 工作空间安全策略
+// 各项取零值表示不限制
+type Policy struct {
+	SpaceID               string    `json:"space_id"`
+	IPAllowlist           []string  `json:"ip_allowlist"`            // IP 或 CIDR
+	SessionMaxAgeMinutes  int       `json:"session_max_age_minutes"` // 自登录起的最长会话时长
+	MaxConcurrentSessions int       `json:"max_concurrent_sessions"` // 同一用户的最大并发会话数
+	ReauthForSensitive    bool      `json:"reauth_for_sensitive"`    // 敏感操作前须重新认证
+	ReauthWindowMinutes   int       `json:"reauth_window_minutes"`   // 重新认证有效期
+	UpdatedBy             string    `json:"updated_by"`
+	UpdatedTime           time.Time `json:"updated_time"`
+}
+
+// NewDefaultPolicy 创建空间的默认策略（不做任何限制）
+func NewDefaultPolicy(spaceID string) *Policy {
+	return &Policy{
+		SpaceID:     spaceID,
+		IPAllowlist: []string{},
+	}
+}
+
+// Validate 校验策略配置
+func (p *Policy) Validate() error {
+	for _, entry := range p.IPAllowlist {
+		if _, err := parseAllowlistEntry(entry); err != nil {
+			return err
+		}
+	}
+	if p.SessionMaxAgeMinutes < 0 || p.MaxConcurrentSessions < 0 || p.ReauthWindowMinutes < 0 {
+		return fmt.Errorf("策略数值不能为负数")
+	}
+	return nil
+}
+
+// AllowsIP 客户端IP是否在白名单内（未配置白名单时放行）
+func (p *Policy) AllowsIP(ip string) bool {
+	if len(p.IPAllowlist) == 0 {
+		return true
+	}
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return false
+	}
+	for _, entry := range p.IPAllowlist {
+		network, err := parseAllowlistEntry(entry)
+		if err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionExpired 会话是否超过最长时长
+func (p *Policy) SessionExpired(authTime, now time.Time) bool {
+	if p.SessionMaxAgeMinutes <= 0 || authTime.IsZero() {
+		return false
+	}
+	return now.Sub(authTime) > time.Duration(p.SessionMaxAgeMinutes)*time.Minute
+}
+
+// ReauthWindow 重新认证有效期
+func (p *Policy) ReauthWindow() time.Duration {
+	if p.ReauthWindowMinutes <= 0 {
+		return DefaultReauthWindow
+	}
+	return time.Duration(p.ReauthWindowMinutes) * time.Minute
+}
+
+// NeedsReauth 执行敏感操作前是否需要重新认证
+func (p *Policy) NeedsReauth(reauthTime, now time.Time) bool {
+	if !p.ReauthForSensitive {
+		return false
+	}
+	return reauthTime.IsZero() || now.Sub(reauthTime) > p.ReauthWindow()
+}
+
+// parseAllowlistEntry 解析白名单条目（单个IP视为 /32 或 /128）
+func parseAllowlistEntry(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("无效的CIDR: %s", entry)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("无效的IP地址: %s", entry)
+	}
+	bits := 128
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+// Session 登录会话
+// 会话从登录开始，刷新令牌时沿用同一会话，登出或被策略踢出时吊销
+type Session struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	IPAddress    string     `json:"ip_address"`
+	UserAgent    string     `json:"user_agent"`
+	CreatedTime  time.Time  `json:"created_time"`
+	LastSeenTime time.Time  `json:"last_seen_time"`
+	RevokedTime  *time.Time `json:"revoked_time,omitempty"`
+}
+
+// NewSession 创建登录会话
+func NewSession(userID, ip, userAgent string) *Session {
+	now := time.Now()
+	return &Session{
+		ID:           utils.GenerateIDWithPrefix("ses"),
+		UserID:       userID,
+		IPAddress:    ip,
+		UserAgent:    userAgent,
+		CreatedTime:  now,
+		LastSeenTime: now,
+	}
+}
+
+// IsRevoked 会话是否已吊销
+func (s *Session) IsRevoked() bool {
+	return s.RevokedTime != nil
+}
+
+// Violation 策略违规事件（写入审计日志）
+type Violation struct {
+	SpaceID   string
+	UserID    string
+	SessionID string
+	Rule      ViolationRule
+	IPAddress string
+	UserAgent string
+	Method    string
+	Path      string
+	Detail    string
+}
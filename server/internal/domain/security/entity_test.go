@@ -0,0 +1,68 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyAllowsIP(t *testing.T) {
+	policy := &Policy{IPAllowlist: []string{"10.0.0.0/8", "192.168.1.20", "2001:db8::/32"}}
+
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"192.168.1.20", true},
+		{"192.168.1.21", false},
+		{"2001:db8::1", true},
+		{"::ffff:10.0.0.1", true},
+		{"not-an-ip", false},
+	}
+	for _, tc := range cases {
+		if got := policy.AllowsIP(tc.ip); got != tc.want {
+			t.Errorf("AllowsIP(%q) = %v, want %v", tc.ip, got, tc.want)
+		}
+	}
+
+	if !NewDefaultPolicy("spc1").AllowsIP("8.8.8.8") {
+		t.Error("empty allowlist should allow any IP")
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	if err := (&Policy{IPAllowlist: []string{"10.0.0.0/33"}}).Validate(); err == nil {
+		t.Error("expected invalid CIDR error")
+	}
+	if err := (&Policy{SessionMaxAgeMinutes: -1}).Validate(); err == nil {
+		t.Error("expected negative value error")
+	}
+	if err := (&Policy{IPAllowlist: []string{" 1.2.3.4 ", "fe80::/10"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPolicySessionAndReauth(t *testing.T) {
+	now := time.Now()
+	policy := &Policy{SessionMaxAgeMinutes: 60, ReauthForSensitive: true}
+
+	if policy.SessionExpired(now.Add(-30*time.Minute), now) {
+		t.Error("session within max age should not expire")
+	}
+	if !policy.SessionExpired(now.Add(-61*time.Minute), now) {
+		t.Error("session beyond max age should expire")
+	}
+
+	if !policy.NeedsReauth(time.Time{}, now) {
+		t.Error("never re-authenticated session should need reauth")
+	}
+	if policy.NeedsReauth(now.Add(-time.Minute), now) {
+		t.Error("reauth within default window should be accepted")
+	}
+	if !policy.NeedsReauth(now.Add(-DefaultReauthWindow-time.Second), now) {
+		t.Error("reauth beyond window should be rejected")
+	}
+	if (&Policy{}).NeedsReauth(time.Time{}, now) {
+		t.Error("policy without reauth should never require it")
+	}
+}
@@ -0,0 +1,25 @@
+package security
+
+import "context"
+
+// Repository 工作空间安全策略与会话仓储接口
+type Repository interface {
+	// GetPolicy 获取空间安全策略（未配置时返回 nil）
+	GetPolicy(ctx context.Context, spaceID string) (*Policy, error)
+	// SavePolicy 保存空间安全策略
+	SavePolicy(ctx context.Context, policy *Policy) error
+
+	// CreateSession 创建会话
+	CreateSession(ctx context.Context, session *Session) error
+	// GetSession 获取会话（不存在时返回 nil）
+	GetSession(ctx context.Context, sessionID string) (*Session, error)
+	// TouchSession 更新会话最近活跃时间
+	TouchSession(ctx context.Context, sessionID string) error
+	// RevokeSession 吊销会话
+	RevokeSession(ctx context.Context, sessionID string) error
+	// ListActiveSessions 列出用户未吊销的会话（按创建时间倒序）
+	ListActiveSessions(ctx context.Context, userID string) ([]*Session, error)
+
+	// RecordViolation 记录策略违规审计日志
+	RecordViolation(ctx context.Context, violation *Violation) error
+}
@@ -0,0 +1,17 @@
+package semanticsearch
+
+import "context"
+
+// EmbedResult 向量化结果
+type EmbedResult struct {
+	Vectors [][]float32 // 与输入一一对应
+	Model   string
+	Tokens  int
+}
+
+// Embedder 文本向量化服务
+// 返回的错误可用 aifield.IsPermanent 判断是否值得重试
+type Embedder interface {
+	Name() string
+	Embed(ctx context.Context, model string, inputs []string) (*EmbedResult, error)
+}
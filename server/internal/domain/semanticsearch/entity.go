@@ -0,0 +1,165 @@
+package semanticsearch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// MaxIndexedFields 单个索引可选择的字段数上限
+	MaxIndexedFields = 20
+	// MaxDocumentLength 向量化文本的最大长度（字符数），超出部分截断
+	MaxDocumentLength = 8000
+	// rrfK 倒数排名融合的平滑常数
+	rrfK = 60
+)
+
+// Status 索引状态
+type Status string
+
+const (
+	StatusBackfilling Status = "backfilling" // 正在为已有记录生成向量
+	StatusReady       Status = "ready"       // 已完成全量，按变更流增量更新
+)
+
+// Index 表的语义索引 ✨
+// 每张表最多一个索引：将所选文本字段拼接为文档并向量化，
+// 创建时记录变更流游标并分批为已有记录生成向量，之后从游标增量消费记录变更
+type Index struct {
+	ID            string     `json:"id"`
+	SpaceID       string     `json:"space_id"`
+	BaseID        string     `json:"base_id"`
+	TableID       string     `json:"table_id"`
+	FieldIDs      []string   `json:"field_ids"`
+	Provider      string     `json:"provider"`
+	Model         string     `json:"model,omitempty"`
+	Dimensions    int        `json:"dimensions"` // 首次生成向量后确定，更换模型时重建
+	Status        Status     `json:"status"`
+	BackfillAfter int64      `json:"backfill_after"` // 回填进度（已处理记录的最大自增序号）
+	Cursor        int64      `json:"cursor"`         // 已消费的变更流序号
+	LastError     string     `json:"last_error,omitempty"`
+	LeaseUntil    *time.Time `json:"-"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// NewIndex 创建索引（从 cursor 之后增量消费变更，已有记录由全量回填覆盖）
+func NewIndex(spaceID, baseID, tableID string, fieldIDs []string, provider, model string, cursor int64, createdBy string) *Index {
+	now := time.Now()
+	return &Index{
+		ID:        utils.GenerateIDWithPrefix("sem"),
+		SpaceID:   spaceID,
+		BaseID:    baseID,
+		TableID:   tableID,
+		FieldIDs:  fieldIDs,
+		Provider:  provider,
+		Model:     model,
+		Status:    StatusBackfilling,
+		Cursor:    cursor,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Rebuild 配置变化后重新全量生成向量
+func (i *Index) Rebuild(fieldIDs []string, provider, model string, cursor int64) {
+	i.FieldIDs = fieldIDs
+	i.Provider = provider
+	i.Model = model
+	i.Dimensions = 0
+	i.Status = StatusBackfilling
+	i.BackfillAfter = 0
+	i.Cursor = cursor
+	i.LastError = ""
+	i.UpdatedAt = time.Now()
+}
+
+// Document 向量化的一条记录
+type Document struct {
+	RecordID string
+	Text     string
+	Hash     string
+}
+
+// BuildDocument 将所选字段的值拼接为 "字段名: 值" 形式的文本（空值跳过）
+// 所有字段均为空时返回空文本，调用方应删除该记录的向量
+func BuildDocument(recordID string, fields []FieldRef, data map[string]interface{}) *Document {
+	var b strings.Builder
+	for _, field := range fields {
+		text := strings.TrimSpace(aifield.FormatValue(data[field.ID]))
+		if text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(field.Name)
+		b.WriteString(": ")
+		b.WriteString(text)
+	}
+	text := b.String()
+	if utf8.RuneCountInString(text) > MaxDocumentLength {
+		text = string([]rune(text)[:MaxDocumentLength])
+	}
+	doc := &Document{RecordID: recordID, Text: text}
+	if text != "" {
+		sum := sha256.Sum256([]byte(text))
+		doc.Hash = hex.EncodeToString(sum[:])
+	}
+	return doc
+}
+
+// FieldRef 参与向量化的字段
+type FieldRef struct {
+	ID   string
+	Name string
+}
+
+// Vector 记录的向量
+type Vector struct {
+	TableID     string
+	RecordID    string
+	Embedding   []float32
+	ContentHash string
+	Model       string
+}
+
+// Match 检索命中的记录
+type Match struct {
+	RecordID string  `json:"record_id"`
+	Score    float64 `json:"score"`
+}
+
+// Fuse 以倒数排名融合（RRF）合并多路检索结果，返回按得分降序的前 limit 条
+// 每一路只需给出有序的记录ID，不要求得分可比
+func Fuse(limit int, rankings ...[]string) []Match {
+	scores := make(map[string]float64)
+	for _, ranking := range rankings {
+		for rank, id := range ranking {
+			scores[id] += 1 / float64(rrfK+rank+1)
+		}
+	}
+	matches := make([]Match, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, Match{RecordID: id, Score: score})
+	}
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Score != matches[b].Score {
+			return matches[a].Score > matches[b].Score
+		}
+		return matches[a].RecordID < matches[b].RecordID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
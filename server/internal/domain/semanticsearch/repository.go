@@ -0,0 +1,37 @@
+package semanticsearch
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrIndexChanged 领取后索引配置已被修改（重建），本次处理的结果作废
+var ErrIndexChanged = errors.New("semantic index changed")
+
+// Repository 语义索引与向量仓储接口
+type Repository interface {
+	// GetIndex 获取表的索引（不存在时返回 nil）
+	GetIndex(ctx context.Context, tableID string) (*Index, error)
+	// SaveIndex 保存索引配置
+	SaveIndex(ctx context.Context, index *Index) error
+	// DeleteIndex 删除索引及其全部向量
+	DeleteIndex(ctx context.Context, index *Index) error
+	// ClaimDue 领取待处理的索引（租约到期前其他实例不会重复领取）
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*Index, error)
+	// SaveProgress 保存回填进度、游标与错误信息并释放租约
+	SaveProgress(ctx context.Context, index *Index) error
+
+	// ResetVectors 删除表的全部向量及近似最近邻索引（重建索引时调用，避免新旧模型的向量混用）
+	ResetVectors(ctx context.Context, index *Index) error
+	// EnsureVectorIndex 为索引的向量维度创建近似最近邻索引（维度确定后调用）
+	EnsureVectorIndex(ctx context.Context, index *Index) error
+	// ContentHashes 返回记录当前向量对应的文本摘要
+	ContentHashes(ctx context.Context, tableID string, recordIDs []string) (map[string]string, error)
+	// UpsertVectors 写入或替换记录的向量；索引配置已不是领取时的版本时返回 ErrIndexChanged
+	UpsertVectors(ctx context.Context, index *Index, vectors []*Vector) error
+	// DeleteVectors 删除记录的向量
+	DeleteVectors(ctx context.Context, tableID string, recordIDs []string) error
+	// Nearest 按余弦距离返回最相近的记录（按相似度降序）
+	Nearest(ctx context.Context, index *Index, query []float32, limit int) ([]Match, error)
+}
@@ -0,0 +1,45 @@
+package semanticsearch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDocument(t *testing.T) {
+	fields := []FieldRef{{ID: "fld_title", Name: "标题"}, {ID: "fld_tags", Name: "标签"}, {ID: "fld_note", Name: "备注"}}
+	doc := BuildDocument("rec1", fields, map[string]interface{}{
+		"fld_title": "季度报告",
+		"fld_tags":  []interface{}{"财务", "2024"},
+		"fld_note":  "   ",
+		"fld_other": "不参与",
+	})
+	if doc.Text != "标题: 季度报告\n标签: 财务, 2024" || doc.Hash == "" {
+		t.Errorf("文档 = %q", doc.Text)
+	}
+	if again := BuildDocument("rec1", fields, map[string]interface{}{"fld_title": "季度报告", "fld_tags": []interface{}{"财务", "2024"}}); again.Hash != doc.Hash {
+		t.Error("内容相同时摘要应一致")
+	}
+
+	empty := BuildDocument("rec2", fields, map[string]interface{}{"fld_other": "x"})
+	if empty.Text != "" || empty.Hash != "" {
+		t.Errorf("所选字段均为空时应返回空文档: %+v", empty)
+	}
+
+	long := BuildDocument("rec3", fields[:1], map[string]interface{}{"fld_title": strings.Repeat("长", MaxDocumentLength)})
+	if n := len([]rune(long.Text)); n != MaxDocumentLength {
+		t.Errorf("文档应截断到 %d 字符，得到 %d", MaxDocumentLength, n)
+	}
+}
+
+func TestFuse(t *testing.T) {
+	matches := Fuse(3, []string{"a", "b", "c"}, []string{"c", "a", "d"})
+	if len(matches) != 3 {
+		t.Fatalf("应返回 3 条，得到 %d", len(matches))
+	}
+	if matches[0].RecordID != "a" || matches[1].RecordID != "c" {
+		t.Errorf("两路都靠前的记录应排在前面: %+v", matches)
+	}
+	if single := Fuse(0, []string{"x", "y"}); single[0].RecordID != "x" || single[0].Score <= single[1].Score {
+		t.Errorf("单路结果应保持原有顺序: %+v", single)
+	}
+}
@@ -0,0 +1,90 @@
+package servicetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// Prefix 服务令牌前缀，认证时据此与登录令牌（JWT）区分
+const Prefix = "lsk_"
+
+// secretBytes 令牌随机部分的字节数
+const secretBytes = 32
+
+// MaxNameLength 令牌名称最大长度
+const MaxNameLength = 100
+
+// Token 服务令牌 ✨
+// 供 CLI、CI 等非交互客户端使用，以创建者身份访问 API；
+// 只保存令牌的 SHA-256 摘要，明文仅在创建时返回一次
+type Token struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	UserID     string     `json:"user_id"`
+	TokenHash  string     `json:"-"`
+	Hint       string     `json:"hint"` // 明文末尾 4 位，便于识别
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewToken 创建服务令牌，返回令牌与明文（明文不会被保存）
+func NewToken(userID, name string, ttl time.Duration) (*Token, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("令牌名称不能为空")
+	}
+	if len([]rune(name)) > MaxNameLength {
+		return nil, "", fmt.Errorf("令牌名称不能超过 %d 个字符", MaxNameLength)
+	}
+	if ttl < 0 {
+		return nil, "", fmt.Errorf("有效期不能为负数")
+	}
+
+	buf := make([]byte, secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", fmt.Errorf("生成令牌失败: %w", err)
+	}
+	plaintext := Prefix + hex.EncodeToString(buf)
+
+	now := time.Now()
+	token := &Token{
+		ID:        utils.GenerateIDWithPrefix("stk"),
+		Name:      name,
+		UserID:    userID,
+		TokenHash: Hash(plaintext),
+		Hint:      plaintext[len(plaintext)-4:],
+		CreatedAt: now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	return token, plaintext, nil
+}
+
+// IsServiceToken 判断是否为服务令牌格式
+func IsServiceToken(plaintext string) bool {
+	return strings.HasPrefix(plaintext, Prefix)
+}
+
+// Hash 令牌摘要（用于存储与查找）
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active 令牌在给定时刻是否可用（未吊销且未过期）
+func (t *Token) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
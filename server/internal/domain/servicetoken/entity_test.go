@@ -0,0 +1,61 @@
+package servicetoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewToken(t *testing.T) {
+	token, plaintext, err := NewToken("usr_1", " ci ", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if !IsServiceToken(plaintext) {
+		t.Fatalf("plaintext %q should carry the service token prefix", plaintext)
+	}
+	if token.Name != "ci" {
+		t.Errorf("name should be trimmed, got %q", token.Name)
+	}
+	if token.TokenHash != Hash(plaintext) || strings.Contains(token.TokenHash, plaintext) {
+		t.Errorf("token hash should be the digest of the plaintext")
+	}
+	if !strings.HasSuffix(plaintext, token.Hint) {
+		t.Errorf("hint %q should be the plaintext suffix", token.Hint)
+	}
+	if token.ExpiresAt == nil {
+		t.Fatalf("expiry should be set for a positive ttl")
+	}
+
+	if _, _, err := NewToken("usr_1", "  ", 0); err == nil {
+		t.Errorf("empty name should be rejected")
+	}
+	if _, _, err := NewToken("usr_1", "ci", -time.Hour); err == nil {
+		t.Errorf("negative ttl should be rejected")
+	}
+}
+
+func TestTokenActive(t *testing.T) {
+	now := time.Now()
+	token, _, err := NewToken("usr_1", "ci", time.Hour)
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if !token.Active(now) {
+		t.Errorf("new token should be active")
+	}
+	if token.Active(now.Add(2 * time.Hour)) {
+		t.Errorf("expired token should not be active")
+	}
+
+	revokedAt := now
+	token.RevokedAt = &revokedAt
+	if token.Active(now) {
+		t.Errorf("revoked token should not be active")
+	}
+
+	noExpiry, _, _ := NewToken("usr_1", "ci", 0)
+	if !noExpiry.Active(now.Add(10 * 365 * 24 * time.Hour)) {
+		t.Errorf("token without ttl should not expire")
+	}
+}
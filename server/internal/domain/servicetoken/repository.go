@@ -0,0 +1,22 @@
+package servicetoken
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 服务令牌仓储接口
+type Repository interface {
+	// Create 保存新令牌
+	Create(ctx context.Context, token *Token) error
+	// GetByHash 按摘要查找令牌（不存在时返回 nil）
+	GetByHash(ctx context.Context, tokenHash string) (*Token, error)
+	// GetByID 按ID查找令牌（不存在时返回 nil）
+	GetByID(ctx context.Context, id string) (*Token, error)
+	// ListByUser 列出用户的令牌（按创建时间倒序，包含已吊销的令牌）
+	ListByUser(ctx context.Context, userID string) ([]*Token, error)
+	// Revoke 吊销令牌
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// TouchLastUsed 更新最近使用时间
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
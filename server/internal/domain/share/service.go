@@ -101,7 +101,7 @@ func NewService(
 func (s *service) CreateShareView(ctx context.Context, viewID, tableID, createdBy string) (*ShareView, error) {
 	// 检查是否已存在分享视图
 	existing, err := s.repo.GetShareViewByViewID(ctx, viewID)
-	if err != nil && err != errors.ErrNotFound {
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
 		s.logger.Error("Failed to check existing share view",
 			logger.String("view_id", viewID),
 			logger.ErrorField(err),
@@ -134,7 +134,7 @@ func (s *service) CreateShareView(ctx context.Context, viewID, tableID, createdB
 func (s *service) GetShareView(ctx context.Context, shareID string) (*ShareView, error) {
 	shareView, err := s.repo.GetShareViewByShareID(ctx, shareID)
 	if err != nil {
-		if err == errors.ErrNotFound {
+		if errors.Is(err, errors.ErrNotFound) {
 			return nil, errors.ErrNotFound.WithDetails("Share view not found")
 		}
 		s.logger.Error("Failed to get share view",
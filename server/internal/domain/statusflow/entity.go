@@ -0,0 +1,164 @@
+package statusflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// AnyStatus 流转规则中表示任意起始状态
+	AnyStatus = "*"
+	// MaxTransitions 单个状态流的流转规则数上限
+	MaxTransitions = 100
+	// MaxTransitionNameLength 流转名称的最大长度（字符数）
+	MaxTransitionNameLength = 50
+)
+
+// approverRoles 可要求的审批角色（有记录编辑权限的协作者角色）
+var approverRoles = map[string]bool{"owner": true, "creator": true, "editor": true}
+
+// Transition 一条允许的状态流转
+type Transition struct {
+	Name          string   `json:"name,omitempty"`          // 流转名称，如“提交审批”“批准”
+	From          string   `json:"from"`                    // 起始状态（选项名称）；"*" 表示任意状态，"" 表示空
+	To            string   `json:"to"`                      // 目标状态（选项名称）
+	ApproverRoles []string `json:"approverRoles,omitempty"` // 执行该流转需要的协作者角色，为空时有记录编辑权限即可
+}
+
+// RequiresApproval 流转是否需要审批角色
+func (t *Transition) RequiresApproval() bool {
+	return len(t.ApproverRoles) > 0
+}
+
+// Approves 角色能否执行该流转
+func (t *Transition) Approves(role string) bool {
+	if !t.RequiresApproval() {
+		return true
+	}
+	for _, r := range t.ApproverRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Flow 单选字段的状态流（审批流）✨
+// 定义单选字段允许的状态流转：启用后修改该字段的值必须匹配一条流转规则（先匹配起始状态相同的规则，
+// 再匹配起始状态为 "*" 的规则），部分流转只能由指定角色的协作者执行；
+// InitialStatuses 非空时，新记录只能以其中的状态（或空）开始
+type Flow struct {
+	ID              string       `json:"id"`
+	TableID         string       `json:"table_id"`
+	FieldID         string       `json:"field_id"`
+	InitialStatuses []string     `json:"initial_statuses"`
+	Transitions     []Transition `json:"transitions"`
+	Enabled         bool         `json:"enabled"`
+	CreatedBy       string       `json:"created_by"`
+	UpdatedBy       string       `json:"updated_by"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// NewFlow 创建状态流（默认启用）
+func NewFlow(tableID, fieldID string, initialStatuses []string, transitions []Transition, createdBy string, now time.Time) *Flow {
+	return &Flow{
+		ID:              utils.GenerateIDWithPrefix("sfl"),
+		TableID:         tableID,
+		FieldID:         fieldID,
+		InitialStatuses: initialStatuses,
+		Transitions:     transitions,
+		Enabled:         true,
+		CreatedBy:       createdBy,
+		UpdatedBy:       createdBy,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate 按字段的选项校验状态流
+func (f *Flow) Validate(choices []string) error {
+	known := make(map[string]bool, len(choices))
+	for _, c := range choices {
+		known[c] = true
+	}
+	if len(f.Transitions) == 0 {
+		return fmt.Errorf("至少需要一条流转规则")
+	}
+	if len(f.Transitions) > MaxTransitions {
+		return fmt.Errorf("最多 %d 条流转规则", MaxTransitions)
+	}
+
+	seen := make(map[[2]string]bool, len(f.Transitions))
+	for i := range f.Transitions {
+		t := &f.Transitions[i]
+		t.Name = strings.TrimSpace(t.Name)
+		if utf8.RuneCountInString(t.Name) > MaxTransitionNameLength {
+			return fmt.Errorf("流转名称不能超过 %d 个字符", MaxTransitionNameLength)
+		}
+		if !known[t.To] {
+			return fmt.Errorf("目标状态 %q 不是字段的选项", t.To)
+		}
+		if t.From != "" && t.From != AnyStatus && !known[t.From] {
+			return fmt.Errorf("起始状态 %q 不是字段的选项", t.From)
+		}
+		if t.From == t.To {
+			return fmt.Errorf("流转的起始状态与目标状态不能相同: %q", t.To)
+		}
+		key := [2]string{t.From, t.To}
+		if seen[key] {
+			return fmt.Errorf("重复的流转规则: %q → %q", t.From, t.To)
+		}
+		seen[key] = true
+		for _, role := range t.ApproverRoles {
+			if !approverRoles[role] {
+				return fmt.Errorf("不支持的审批角色 %q（可选 owner、creator、editor）", role)
+			}
+		}
+	}
+
+	for _, status := range f.InitialStatuses {
+		if !known[status] {
+			return fmt.Errorf("初始状态 %q 不是字段的选项", status)
+		}
+	}
+	if f.InitialStatuses == nil {
+		f.InitialStatuses = []string{}
+	}
+	return nil
+}
+
+// Find 查找从 from 到 to 的流转规则（精确匹配起始状态优先），不允许时返回 nil
+func (f *Flow) Find(from, to string) *Transition {
+	var wildcard *Transition
+	for i := range f.Transitions {
+		t := &f.Transitions[i]
+		if t.To != to {
+			continue
+		}
+		if t.From == from {
+			return t
+		}
+		if t.From == AnyStatus && wildcard == nil {
+			wildcard = t
+		}
+	}
+	return wildcard
+}
+
+// AllowsInitial 新记录能否以该状态开始
+func (f *Flow) AllowsInitial(status string) bool {
+	if status == "" || len(f.InitialStatuses) == 0 {
+		return true
+	}
+	for _, s := range f.InitialStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
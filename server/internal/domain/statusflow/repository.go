@@ -0,0 +1,15 @@
+package statusflow
+
+import "context"
+
+// Repository 状态流仓储接口
+type Repository interface {
+	// Save 创建或更新状态流
+	Save(ctx context.Context, flow *Flow) error
+	// FindByField 获取字段的状态流（不存在时返回 nil）
+	FindByField(ctx context.Context, fieldID string) (*Flow, error)
+	// ListByTable 列出表的状态流
+	ListByTable(ctx context.Context, tableID string) ([]*Flow, error)
+	// Delete 删除状态流
+	Delete(ctx context.Context, id string) error
+}
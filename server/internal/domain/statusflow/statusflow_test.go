@@ -0,0 +1,70 @@
+package statusflow
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testChoices = []string{"草稿", "待审批", "已批准", "已驳回"}
+
+func newTestFlow() *Flow {
+	return NewFlow("tbl1", "fld1", []string{"草稿"}, []Transition{
+		{Name: "提交审批", From: "草稿", To: "待审批"},
+		{Name: "批准", From: "待审批", To: "已批准", ApproverRoles: []string{"owner", "creator"}},
+		{Name: "驳回", From: "待审批", To: "已驳回", ApproverRoles: []string{"owner"}},
+		{Name: "退回草稿", From: AnyStatus, To: "草稿"},
+	}, "usr1", time.Now())
+}
+
+func TestFlowValidate(t *testing.T) {
+	flow := newTestFlow()
+	if err := flow.Validate(testChoices); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if !strings.HasPrefix(flow.ID, "sfl") || !flow.Enabled {
+		t.Errorf("状态流 = %+v", flow)
+	}
+
+	invalid := [][]Transition{
+		nil,
+		{{From: "草稿", To: "未知"}},
+		{{From: "未知", To: "草稿"}},
+		{{From: "草稿", To: "草稿"}},
+		{{From: "草稿", To: "待审批"}, {From: "草稿", To: "待审批"}},
+		{{From: "草稿", To: "待审批", ApproverRoles: []string{"viewer"}}},
+		{{Name: strings.Repeat("名", MaxTransitionNameLength+1), From: "草稿", To: "待审批"}},
+	}
+	for i, transitions := range invalid {
+		if err := NewFlow("tbl1", "fld1", nil, transitions, "usr1", time.Now()).Validate(testChoices); err == nil {
+			t.Errorf("规则 %d 应校验失败", i)
+		}
+	}
+	bad := NewFlow("tbl1", "fld1", []string{"未知"}, []Transition{{From: "", To: "草稿"}}, "usr1", time.Now())
+	if err := bad.Validate(testChoices); err == nil {
+		t.Error("未知的初始状态应校验失败")
+	}
+}
+
+func TestFlowFind(t *testing.T) {
+	flow := newTestFlow()
+
+	if tr := flow.Find("草稿", "待审批"); tr == nil || tr.Name != "提交审批" || tr.RequiresApproval() {
+		t.Errorf("提交审批 = %+v", tr)
+	}
+	if tr := flow.Find("已批准", "草稿"); tr == nil || tr.Name != "退回草稿" {
+		t.Errorf("任意状态退回 = %+v", tr)
+	}
+	if tr := flow.Find("草稿", "已批准"); tr != nil {
+		t.Errorf("不应允许跳过审批: %+v", tr)
+	}
+
+	approve := flow.Find("待审批", "已批准")
+	if !approve.Approves("creator") || approve.Approves("editor") {
+		t.Error("审批角色判断错误")
+	}
+
+	if !flow.AllowsInitial("草稿") || !flow.AllowsInitial("") || flow.AllowsInitial("已批准") {
+		t.Error("初始状态判断错误")
+	}
+}
@@ -0,0 +1,191 @@
+package subscription
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+)
+
+// Status Stripe 订阅状态
+type Status string
+
+const (
+	StatusActive            Status = "active"
+	StatusTrialing          Status = "trialing"
+	StatusPastDue           Status = "past_due" // 扣款失败，Stripe 仍在重试
+	StatusUnpaid            Status = "unpaid"   // 重试结束仍未付款
+	StatusCanceled          Status = "canceled"
+	StatusIncomplete        Status = "incomplete"
+	StatusIncompleteExpired Status = "incomplete_expired"
+	StatusPaused            Status = "paused"
+)
+
+// Entitled 该状态下是否享有订阅的套餐（欠费在宽限期内保留）
+func (s Status) Entitled() bool {
+	return s == StatusActive || s == StatusTrialing || s == StatusPastDue
+}
+
+// Stripe webhook 事件类型
+const (
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+	EventPaymentFailed       = "invoice.payment_failed"
+	EventPaymentSucceeded    = "invoice.paid"
+)
+
+// Supported 是否为需要处理的事件类型（其余事件直接确认）
+func Supported(eventType string) bool {
+	switch eventType {
+	case EventSubscriptionCreated, EventSubscriptionUpdated, EventSubscriptionDeleted,
+		EventPaymentFailed, EventPaymentSucceeded:
+		return true
+	}
+	return false
+}
+
+// Event 解析后的 Stripe 事件
+// 订阅事件携带订阅的完整状态；发票事件只有客户与订阅ID
+type Event struct {
+	ID                string
+	Type              string
+	Created           time.Time
+	SpaceID           string // 订阅 metadata.space_id（创建结账会话时写入）
+	CustomerID        string
+	SubscriptionID    string
+	PriceID           string
+	Status            Status
+	CurrentPeriodEnd  time.Time
+	CancelAtPeriodEnd bool
+}
+
+// Policy Stripe 订阅映射到套餐的规则
+type Policy struct {
+	Prices               map[string]string // Stripe 价格ID -> 套餐ID
+	DefaultPlan          string            // 取消或欠费超过宽限期后的套餐
+	DowngradeAtPeriodEnd bool              // 降级到当前计费周期结束再生效（门户配置为期末降级、不退差价时开启）
+	PastDueGrace         time.Duration     // 欠费超过该时长后降到默认套餐（0 表示等 Stripe 取消订阅）
+}
+
+// Subscription 工作空间的 Stripe 订阅 ✨
+type Subscription struct {
+	SpaceID           string     `json:"space_id"`
+	CustomerID        string     `json:"customer_id"`
+	SubscriptionID    string     `json:"subscription_id"`
+	PriceID           string     `json:"price_id"`
+	Status            Status     `json:"status"`
+	PlanID            string     `json:"plan_id"`                   // 当前生效的套餐
+	PendingPlanID     string     `json:"pending_plan_id,omitempty"` // 计费周期结束时生效的降级
+	CurrentPeriodEnd  time.Time  `json:"current_period_end"`
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end"`
+	PastDueSince      *time.Time `json:"past_due_since,omitempty"`
+	LastEventAt       time.Time  `json:"last_event_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// NewSubscription 创建工作空间的订阅（尚未生效任何付费套餐）
+func NewSubscription(spaceID, defaultPlan string) *Subscription {
+	return &Subscription{SpaceID: spaceID, PlanID: defaultPlan}
+}
+
+// Stale 事件是否早于已应用的事件（Stripe 不保证投递顺序）
+func (s *Subscription) Stale(ev *Event) bool {
+	return ev.Created.Before(s.LastEventAt)
+}
+
+// Replaced 事件是否属于已被新订阅替换的旧订阅（客户取消后重新订阅时，旧订阅的事件不再生效）
+func (s *Subscription) Replaced(ev *Event) bool {
+	return ev.SubscriptionID != "" && s.SubscriptionID != "" &&
+		ev.SubscriptionID != s.SubscriptionID && ev.Type != EventSubscriptionCreated
+}
+
+// Apply 将事件应用到订阅，返回需要立即生效的套餐（为空表示套餐不变）
+//   - 升级立即生效（Stripe 按比例补收差价）
+//   - 降级在 DowngradeAtPeriodEnd 时记为待生效，计费周期结束后由 Due 生效；否则立即生效
+//   - 设置了期末取消的订阅在周期内保留套餐，删除事件到达时降到默认套餐
+//   - 扣款失败只记录欠费开始时间，超过宽限期后由 Due 降级；付款成功后清除
+func (s *Subscription) Apply(ev *Event, policy Policy) (string, error) {
+	if ev.CustomerID != "" {
+		s.CustomerID = ev.CustomerID
+	}
+	if ev.SubscriptionID != "" {
+		s.SubscriptionID = ev.SubscriptionID
+	}
+	if ev.Created.After(s.LastEventAt) {
+		s.LastEventAt = ev.Created
+	}
+
+	switch ev.Type {
+	case EventPaymentFailed:
+		if s.PastDueSince == nil {
+			at := ev.Created
+			s.PastDueSince = &at
+		}
+		return "", nil
+	case EventPaymentSucceeded:
+		s.PastDueSince = nil
+		return "", nil
+	case EventSubscriptionDeleted:
+		s.Status = StatusCanceled
+		s.CancelAtPeriodEnd = false
+		s.PastDueSince = nil
+		return s.change(policy.DefaultPlan), nil
+	case EventSubscriptionCreated, EventSubscriptionUpdated:
+	default:
+		return "", nil
+	}
+
+	s.PriceID = ev.PriceID
+	s.Status = ev.Status
+	s.CurrentPeriodEnd = ev.CurrentPeriodEnd
+	s.CancelAtPeriodEnd = ev.CancelAtPeriodEnd
+	switch {
+	case ev.Status == StatusPastDue:
+		if s.PastDueSince == nil {
+			at := ev.Created
+			s.PastDueSince = &at
+		}
+	case ev.Status.Entitled():
+		s.PastDueSince = nil
+	}
+
+	if !ev.Status.Entitled() || s.overdue(policy, ev.Created) {
+		return s.change(policy.DefaultPlan), nil
+	}
+	target, ok := policy.Prices[ev.PriceID]
+	if !ok {
+		return "", fmt.Errorf("价格 %s 未配置对应的套餐", ev.PriceID)
+	}
+	if policy.DowngradeAtPeriodEnd && plan.Rank(target) < plan.Rank(s.PlanID) && ev.Created.Before(ev.CurrentPeriodEnd) {
+		s.PendingPlanID = target
+		return "", nil
+	}
+	return s.change(target), nil
+}
+
+// Due 到期的套餐变更：欠费超过宽限期降到默认套餐，或计费周期结束后生效待降级的套餐
+func (s *Subscription) Due(policy Policy, now time.Time) string {
+	if s.overdue(policy, now) {
+		return s.change(policy.DefaultPlan)
+	}
+	if s.PendingPlanID != "" && !now.Before(s.CurrentPeriodEnd) {
+		return s.change(s.PendingPlanID)
+	}
+	return ""
+}
+
+// overdue 欠费是否已超过宽限期
+func (s *Subscription) overdue(policy Policy, at time.Time) bool {
+	return s.PastDueSince != nil && policy.PastDueGrace > 0 && at.Sub(*s.PastDueSince) >= policy.PastDueGrace
+}
+
+// change 切换到目标套餐并清除待生效的降级，套餐未变化时返回空
+func (s *Subscription) change(target string) string {
+	s.PendingPlanID = ""
+	if target == s.PlanID {
+		return ""
+	}
+	s.PlanID = target
+	return target
+}
@@ -0,0 +1,31 @@
+package subscription
+
+import (
+	"context"
+	"time"
+)
+
+// Repository Stripe 订阅仓储接口
+type Repository interface {
+	// FindBySpace 按工作空间查找订阅（不存在时返回 nil）
+	FindBySpace(ctx context.Context, spaceID string) (*Subscription, error)
+	// FindBySubscriptionID 按 Stripe 订阅ID查找（不存在时返回 nil）
+	FindBySubscriptionID(ctx context.Context, subscriptionID string) (*Subscription, error)
+	// FindByCustomerID 按 Stripe 客户ID查找（不存在时返回 nil）
+	FindByCustomerID(ctx context.Context, customerID string) (*Subscription, error)
+	// Save 保存订阅（已存在时覆盖）
+	Save(ctx context.Context, sub *Subscription) error
+	// ListDue 列出有待生效降级或处于欠费中的订阅
+	ListDue(ctx context.Context) ([]*Subscription, error)
+
+	// EventProcessed 事件是否已处理过
+	EventProcessed(ctx context.Context, eventID string) (bool, error)
+	// RecordEvent 记录已处理的事件
+	RecordEvent(ctx context.Context, eventID, eventType string, at time.Time) error
+}
+
+// Portal Stripe 客户门户
+type Portal interface {
+	// CreatePortalSession 为客户创建门户会话，返回门户地址
+	CreatePortalSession(ctx context.Context, customerID, returnURL string) (string, error)
+}
@@ -0,0 +1,160 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+)
+
+var (
+	testStart = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	testEnd   = testStart.AddDate(0, 1, 0)
+)
+
+func testPolicy() Policy {
+	return Policy{
+		Prices:               map[string]string{"price_team": "team", "price_business": "business"},
+		DefaultPlan:          "free",
+		DowngradeAtPeriodEnd: true,
+		PastDueGrace:         72 * time.Hour,
+	}
+}
+
+func subEvent(eventType, price string, status Status, at time.Time) *Event {
+	return &Event{
+		ID:               "evt_" + at.Format("150405.000"),
+		Type:             eventType,
+		Created:          at,
+		CustomerID:       "cus_1",
+		SubscriptionID:   "sub_1",
+		PriceID:          price,
+		Status:           status,
+		CurrentPeriodEnd: testEnd,
+	}
+}
+
+func TestApplyUpgradeAndDeferredDowngrade(t *testing.T) {
+	policy := testPolicy()
+	sub := NewSubscription("spc1", "free")
+
+	got, err := sub.Apply(subEvent(EventSubscriptionCreated, "price_business", StatusActive, testStart), policy)
+	if err != nil || got != "business" {
+		t.Fatalf("created: got %q, %v; want business", got, err)
+	}
+	if sub.CustomerID != "cus_1" || sub.SubscriptionID != "sub_1" {
+		t.Fatalf("customer/subscription ids not recorded: %+v", sub)
+	}
+
+	// 周期内降级记为待生效
+	got, _ = sub.Apply(subEvent(EventSubscriptionUpdated, "price_team", StatusActive, testStart.Add(time.Hour)), policy)
+	if got != "" || sub.PlanID != "business" || sub.PendingPlanID != "team" {
+		t.Fatalf("downgrade should be deferred: got %q, plan %s, pending %s", got, sub.PlanID, sub.PendingPlanID)
+	}
+	if due := sub.Due(policy, testEnd.Add(-time.Minute)); due != "" {
+		t.Fatalf("downgrade applied before period end: %q", due)
+	}
+	if due := sub.Due(policy, testEnd); due != "team" || sub.PendingPlanID != "" {
+		t.Fatalf("Due at period end = %q, pending %q; want team", due, sub.PendingPlanID)
+	}
+
+	// 再次升级立即生效并撤销待生效的降级
+	sub.PendingPlanID = "free"
+	got, _ = sub.Apply(subEvent(EventSubscriptionUpdated, "price_business", StatusActive, testStart.Add(2*time.Hour)), policy)
+	if got != "business" || sub.PendingPlanID != "" {
+		t.Fatalf("upgrade: got %q, pending %q", got, sub.PendingPlanID)
+	}
+}
+
+func TestApplyImmediateDowngrade(t *testing.T) {
+	policy := testPolicy()
+	policy.DowngradeAtPeriodEnd = false
+	sub := &Subscription{SpaceID: "spc1", PlanID: "business"}
+
+	got, _ := sub.Apply(subEvent(EventSubscriptionUpdated, "price_team", StatusActive, testStart), policy)
+	if got != "team" || sub.PendingPlanID != "" {
+		t.Fatalf("got %q, pending %q; want immediate team", got, sub.PendingPlanID)
+	}
+}
+
+func TestApplyCancellation(t *testing.T) {
+	policy := testPolicy()
+	sub := &Subscription{SpaceID: "spc1", PlanID: "team", SubscriptionID: "sub_1"}
+
+	ev := subEvent(EventSubscriptionUpdated, "price_team", StatusActive, testStart)
+	ev.CancelAtPeriodEnd = true
+	if got, _ := sub.Apply(ev, policy); got != "" || !sub.CancelAtPeriodEnd {
+		t.Fatalf("cancel at period end should keep plan: got %q", got)
+	}
+
+	got, _ := sub.Apply(subEvent(EventSubscriptionDeleted, "price_team", StatusCanceled, testEnd), policy)
+	if got != "free" || sub.Status != StatusCanceled {
+		t.Fatalf("deleted: got %q, status %s", got, sub.Status)
+	}
+}
+
+func TestApplyPastDue(t *testing.T) {
+	policy := testPolicy()
+	sub := &Subscription{SpaceID: "spc1", PlanID: "team", SubscriptionID: "sub_1"}
+
+	failed := testStart.Add(time.Hour)
+	if got, _ := sub.Apply(&Event{Type: EventPaymentFailed, Created: failed, SubscriptionID: "sub_1"}, policy); got != "" {
+		t.Fatalf("payment failure should not change plan immediately: %q", got)
+	}
+	if sub.PastDueSince == nil || !sub.PastDueSince.Equal(failed) {
+		t.Fatalf("past due since not recorded: %v", sub.PastDueSince)
+	}
+	if due := sub.Due(policy, failed.Add(time.Hour)); due != "" {
+		t.Fatalf("downgraded within grace period: %q", due)
+	}
+
+	// 宽限期内付款成功则清除欠费
+	sub.Apply(&Event{Type: EventPaymentSucceeded, Created: failed.Add(2 * time.Hour)}, policy)
+	if sub.PastDueSince != nil {
+		t.Fatal("payment success should clear past due")
+	}
+
+	sub.Apply(&Event{Type: EventPaymentFailed, Created: failed.Add(3 * time.Hour)}, policy)
+	if due := sub.Due(policy, failed.Add(3*time.Hour+policy.PastDueGrace)); due != "free" {
+		t.Fatalf("Due after grace = %q, want free", due)
+	}
+
+	// 宽限期后到达的 past_due 更新不应恢复套餐
+	got, _ := sub.Apply(subEvent(EventSubscriptionUpdated, "price_team", StatusPastDue, failed.Add(4*time.Hour+policy.PastDueGrace)), policy)
+	if got != "" || sub.PlanID != "free" {
+		t.Fatalf("past_due update after grace re-entitled plan: got %q, plan %s", got, sub.PlanID)
+	}
+}
+
+func TestApplyNotEntitledAndUnknownPrice(t *testing.T) {
+	policy := testPolicy()
+	sub := &Subscription{SpaceID: "spc1", PlanID: "team"}
+
+	if got, _ := sub.Apply(subEvent(EventSubscriptionUpdated, "price_team", StatusUnpaid, testStart), policy); got != "free" {
+		t.Fatalf("unpaid should downgrade to default, got %q", got)
+	}
+	if _, err := sub.Apply(subEvent(EventSubscriptionUpdated, "price_unknown", StatusActive, testStart), policy); err == nil {
+		t.Fatal("expected error for unmapped price")
+	}
+	if sub.PlanID != "free" {
+		t.Fatalf("unmapped price should keep plan, got %s", sub.PlanID)
+	}
+}
+
+func TestStaleAndReplaced(t *testing.T) {
+	sub := &Subscription{SubscriptionID: "sub_2", LastEventAt: testStart}
+
+	if !sub.Stale(&Event{Created: testStart.Add(-time.Second)}) {
+		t.Error("older event should be stale")
+	}
+	if sub.Stale(&Event{Created: testStart}) {
+		t.Error("event at the same second should not be stale")
+	}
+	if !sub.Replaced(&Event{Type: EventSubscriptionDeleted, SubscriptionID: "sub_1"}) {
+		t.Error("event for the previous subscription should be replaced")
+	}
+	if sub.Replaced(&Event{Type: EventSubscriptionCreated, SubscriptionID: "sub_3"}) {
+		t.Error("a new subscription should replace the current one")
+	}
+	if sub.Replaced(&Event{Type: EventPaymentFailed}) {
+		t.Error("event without subscription id should not be replaced")
+	}
+}
@@ -0,0 +1,75 @@
+package syncedtable
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// RunStatus 同步运行状态
+type RunStatus string
+
+const (
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// Sync 同步表 ✨
+// 将源 Base 中一个视图的记录镜像到另一个 Base（可以在其他空间）的只读表：
+// 首次全量复制，之后按间隔从源 Base 的变更流增量同步；镜像表只由同步写入，不会产生冲突。
+// 源记录被删除或不再满足视图条件时，镜像记录保留并勾选“源记录已删除”标记
+type Sync struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	SourceBaseID   string            `json:"source_base_id"`
+	SourceTableID  string            `json:"source_table_id"`
+	SourceViewID   string            `json:"source_view_id"`
+	TargetBaseID   string            `json:"target_base_id"`
+	TargetTableID  string            `json:"target_table_id"`
+	FieldMap       map[string]string `json:"field_map"`        // 源字段ID -> 镜像字段ID
+	DeletedFieldID string            `json:"deleted_field_id"` // 镜像表中的“源记录已删除”字段
+	Interval       time.Duration     `json:"interval"`
+	Enabled        bool              `json:"enabled"`
+	Cursor         int64             `json:"cursor"` // 已同步的源 Base 变更流序号
+	NextRunAt      time.Time         `json:"next_run_at"`
+	LastRunAt      *time.Time        `json:"last_run_at,omitempty"`
+	LastStatus     RunStatus         `json:"last_status,omitempty"`
+	LastError      string            `json:"last_error,omitempty"`
+	CreatedBy      string            `json:"created_by"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// NewSync 创建同步表（创建后立即执行首次全量同步）
+func NewSync(name, sourceBaseID, sourceTableID, sourceViewID, targetBaseID string, interval time.Duration, createdBy string) *Sync {
+	now := time.Now()
+	return &Sync{
+		ID:            utils.GenerateIDWithPrefix("syt"),
+		Name:          name,
+		SourceBaseID:  sourceBaseID,
+		SourceTableID: sourceTableID,
+		SourceViewID:  sourceViewID,
+		TargetBaseID:  targetBaseID,
+		FieldMap:      map[string]string{},
+		Interval:      interval,
+		Enabled:       true,
+		NextRunAt:     now,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// NeedsFullSync 本次运行是否需要全量同步（首次运行或游标被重置）
+func (s *Sync) NeedsFullSync() bool {
+	return s.LastRunAt == nil || s.Cursor == 0
+}
+
+// Row 源记录与镜像记录的对应关系
+type Row struct {
+	SyncID         string    `json:"sync_id"`
+	SourceRecordID string    `json:"source_record_id"`
+	TargetRecordID string    `json:"target_record_id"`
+	SourceDeleted  bool      `json:"source_deleted"`
+	SyncedAt       time.Time `json:"synced_at"` // 最近一次写入镜像记录的时间（全量同步据此找出源中已不存在的记录）
+}
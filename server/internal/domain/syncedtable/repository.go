@@ -0,0 +1,31 @@
+package syncedtable
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 同步表仓储接口
+type Repository interface {
+	// Save 创建或更新同步配置（不覆盖同步进度）
+	Save(ctx context.Context, sync *Sync) error
+	// FindByID 获取同步表（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Sync, error)
+	// FindByTargetTable 获取镜像到该表的同步（不存在时返回 nil）
+	FindByTargetTable(ctx context.Context, tableID string) (*Sync, error)
+	// ListByTargetBase 列出镜像到该 Base 的同步表
+	ListByTargetBase(ctx context.Context, baseID string) ([]*Sync, error)
+	// Delete 删除同步表配置及记录对应关系（镜像表本身保留）
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取已到执行时间的启用同步并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*Sync, error)
+	// SaveProgress 保存同步进度、字段对应与下次执行时间并释放租约
+	SaveProgress(ctx context.Context, sync *Sync) error
+
+	// FindRows 获取源记录对应的镜像记录
+	FindRows(ctx context.Context, syncID string, sourceRecordIDs []string) ([]*Row, error)
+	// SaveRows 创建或更新记录对应关系
+	SaveRows(ctx context.Context, rows []*Row) error
+	// ListStaleRows 列出 before 之前写入且未标记删除的对应关系（按源记录ID排序）
+	ListStaleRows(ctx context.Context, syncID string, before time.Time, limit int) ([]*Row, error)
+}
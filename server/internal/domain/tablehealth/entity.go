@@ -0,0 +1,266 @@
+package tablehealth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// MinScheduleHours 定时检查的最小间隔（小时）
+	MinScheduleHours = 1
+	// MaxScheduleHours 定时检查的最大间隔（小时）
+	MaxScheduleHours = 24 * 30
+	// MaxFixIssues 单个修复任务可包含的问题数上限
+	MaxFixIssues = 10000
+)
+
+// IssueKind 问题类型
+type IssueKind string
+
+const (
+	KindValidation   IssueKind = "validation"    // 值不符合字段约束（必填为空、选项不存在、格式错误等）
+	KindOrphanedLink IssueKind = "orphaned_link" // 关联到已删除的记录，或关联的表已不存在
+	KindFormulaError IssueKind = "formula_error" // 公式计算出错（字段级或单元格中的错误值）
+	KindTypeCoercion IssueKind = "type_coercion" // 类型转换遗留值：与字段类型不符，但可转换为有效值
+)
+
+// Kinds 全部问题类型
+var Kinds = []IssueKind{KindValidation, KindOrphanedLink, KindFormulaError, KindTypeCoercion}
+
+// Fixable 该类型的问题是否可以自动修复（单元格级问题中：移除失效的关联、写入转换后的值）
+func (k IssueKind) Fixable() bool {
+	return k == KindOrphanedLink || k == KindTypeCoercion
+}
+
+// Valid 是否为已知的问题类型
+func (k IssueKind) Valid() bool {
+	for _, kind := range Kinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Trigger 检查的触发方式
+type Trigger string
+
+const (
+	TriggerManual    Trigger = "manual"
+	TriggerScheduled Trigger = "scheduled"
+)
+
+// Status 检查报告与修复任务的状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Finished 是否已结束
+func (s Status) Finished() bool {
+	return s == StatusCompleted || s == StatusFailed
+}
+
+// IssueStatus 问题的处理状态
+type IssueStatus string
+
+const (
+	IssueOpen    IssueStatus = "open"
+	IssueFixed   IssueStatus = "fixed"
+	IssueSkipped IssueStatus = "skipped" // 修复时值已变化或问题已不存在
+	IssueFailed  IssueStatus = "failed"
+)
+
+// Report 表健康检查报告 ✨
+// 一次检查扫描表中的记录，按类型汇总问题；问题数超过上限时只保存前面的问题，统计仍覆盖全部扫描的记录
+type Report struct {
+	ID         string            `json:"id"`
+	TableID    string            `json:"table_id"`
+	Trigger    Trigger           `json:"trigger"`
+	Status     Status            `json:"status"`
+	Scanned    int               `json:"scanned"`   // 已检查的记录数
+	Truncated  bool              `json:"truncated"` // 记录数超过扫描上限，或问题数超过保存上限
+	Counts     map[IssueKind]int `json:"counts"`    // 各类型问题数
+	Fixable    int               `json:"fixable"`   // 可自动修复的问题数
+	Error      string            `json:"error,omitempty"`
+	CreatedBy  string            `json:"created_by"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// NewReport 创建待执行的检查
+func NewReport(tableID string, trigger Trigger, createdBy string, now time.Time) *Report {
+	return &Report{
+		ID:        utils.GenerateIDWithPrefix("thr"),
+		TableID:   tableID,
+		Trigger:   trigger,
+		Status:    StatusPending,
+		Counts:    map[IssueKind]int{},
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Reset 重新开始统计（检查被重新领取时丢弃上次未完成的结果）
+func (r *Report) Reset() {
+	r.Scanned = 0
+	r.Truncated = false
+	r.Counts = map[IssueKind]int{}
+	r.Fixable = 0
+	r.Error = ""
+}
+
+// Count 计入一个问题
+func (r *Report) Count(issue *Issue) {
+	r.Counts[issue.Kind]++
+	if issue.Fixable {
+		r.Fixable++
+	}
+}
+
+// Total 问题总数
+func (r *Report) Total() int {
+	total := 0
+	for _, n := range r.Counts {
+		total += n
+	}
+	return total
+}
+
+// Finish 结束检查
+func (r *Report) Finish(err error, now time.Time) {
+	r.Status = StatusCompleted
+	if err != nil {
+		r.Status = StatusFailed
+		r.Error = err.Error()
+	}
+	r.UpdatedAt = now
+	r.FinishedAt = &now
+}
+
+// Issue 检查发现的一个问题（RecordID 为空表示字段级问题）
+type Issue struct {
+	ID        string      `json:"id"`
+	ReportID  string      `json:"report_id"`
+	TableID   string      `json:"table_id"`
+	Kind      IssueKind   `json:"kind"`
+	FieldID   string      `json:"field_id"`
+	FieldName string      `json:"field_name"`
+	RecordID  string      `json:"record_id,omitempty"`
+	Value     interface{} `json:"value,omitempty"` // 发现问题时的单元格值
+	Message   string      `json:"message"`
+	Fixable   bool        `json:"fixable"`
+	Status    IssueStatus `json:"status"`
+	FixError  string      `json:"fix_error,omitempty"`
+}
+
+// NewIssue 创建问题；单元格级的可修复类型标记为可修复
+func NewIssue(report *Report, kind IssueKind, fieldID, fieldName, recordID string, value interface{}, message string) *Issue {
+	return &Issue{
+		ID:        utils.GenerateIDWithPrefix("thi"),
+		ReportID:  report.ID,
+		TableID:   report.TableID,
+		Kind:      kind,
+		FieldID:   fieldID,
+		FieldName: fieldName,
+		RecordID:  recordID,
+		Value:     value,
+		Message:   message,
+		Fixable:   recordID != "" && kind.Fixable(),
+		Status:    IssueOpen,
+	}
+}
+
+// FixJob 一键修复任务：按检查时的问题逐条重新检查当前值，仍有问题时写入修复后的值
+type FixJob struct {
+	ID         string     `json:"id"`
+	ReportID   string     `json:"report_id"`
+	TableID    string     `json:"table_id"`
+	IssueIDs   []string   `json:"issue_ids"`
+	Status     Status     `json:"status"`
+	Fixed      int        `json:"fixed"`
+	Skipped    int        `json:"skipped"`
+	Failed     int        `json:"failed"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by"` // 以创建人的身份写入记录
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewFixJob 创建待执行的修复任务
+func NewFixJob(report *Report, issueIDs []string, createdBy string, now time.Time) *FixJob {
+	return &FixJob{
+		ID:        utils.GenerateIDWithPrefix("thf"),
+		ReportID:  report.ID,
+		TableID:   report.TableID,
+		IssueIDs:  issueIDs,
+		Status:    StatusPending,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Record 计入一个问题的处理结果
+func (j *FixJob) Record(status IssueStatus) {
+	switch status {
+	case IssueFixed:
+		j.Fixed++
+	case IssueSkipped:
+		j.Skipped++
+	case IssueFailed:
+		j.Failed++
+	}
+}
+
+// Finish 结束修复任务
+func (j *FixJob) Finish(err error, now time.Time) {
+	j.Status = StatusCompleted
+	if err != nil {
+		j.Status = StatusFailed
+		j.Error = err.Error()
+	}
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// Schedule 表的定时检查设置
+type Schedule struct {
+	TableID       string     `json:"table_id"`
+	IntervalHours int        `json:"interval_hours"`
+	Enabled       bool       `json:"enabled"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	UpdatedBy     string     `json:"updated_by"` // 定时检查以最后修改人的身份创建
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Validate 校验检查间隔
+func (s *Schedule) Validate() error {
+	if s.IntervalHours < MinScheduleHours || s.IntervalHours > MaxScheduleHours {
+		return fmt.Errorf("检查间隔必须在 %d-%d 小时之间", MinScheduleHours, MaxScheduleHours)
+	}
+	return nil
+}
+
+// Interval 检查间隔
+func (s *Schedule) Interval() time.Duration {
+	return time.Duration(s.IntervalHours) * time.Hour
+}
+
+// Advance 从 now 起计算下次检查时间（停用时为空）
+func (s *Schedule) Advance(now time.Time) {
+	s.NextRunAt = nil
+	if s.Enabled {
+		next := now.Add(s.Interval())
+		s.NextRunAt = &next
+	}
+}
@@ -0,0 +1,61 @@
+package tablehealth
+
+import (
+	"context"
+	"time"
+)
+
+// IssueFilter 问题查询条件（为空的条件不过滤）
+type IssueFilter struct {
+	ReportID string
+	Kind     IssueKind
+	FieldID  string
+	Status   IssueStatus
+	Limit    int
+	Offset   int
+}
+
+// Repository 表健康检查报告、问题、修复任务与定时设置仓储接口
+type Repository interface {
+	// SaveReport 创建或更新检查报告（执行中更新时同时作为心跳）
+	SaveReport(ctx context.Context, report *Report) error
+	// GetReport 获取检查报告（不存在时返回 nil）
+	GetReport(ctx context.Context, id string) (*Report, error)
+	// ListReports 按创建时间倒序列出表的检查报告
+	ListReports(ctx context.Context, tableID string, limit int) ([]*Report, error)
+	// ActiveReport 表中未结束的检查（不存在时返回 nil）
+	ActiveReport(ctx context.Context, tableID string) (*Report, error)
+	// ClaimReports 领取待执行的检查并标记为执行中；staleBefore 之前更新且未结束的检查视为中断，重新领取
+	ClaimReports(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Report, error)
+	// PurgeReports 只保留表最近 keep 份报告，连同其问题与修复任务
+	PurgeReports(ctx context.Context, tableID string, keep int) error
+
+	// SaveIssues 批量写入问题
+	SaveIssues(ctx context.Context, issues []*Issue) error
+	// DeleteIssues 删除报告的全部问题
+	DeleteIssues(ctx context.Context, reportID string) error
+	// ListIssues 按条件分页列出问题，返回满足条件的总数
+	ListIssues(ctx context.Context, filter IssueFilter) ([]*Issue, int64, error)
+	// GetIssues 获取报告中指定的问题
+	GetIssues(ctx context.Context, reportID string, ids []string) ([]*Issue, error)
+	// OpenFixableIssueIDs 报告中待修复的问题ID（kinds 为空时包含全部可修复类型）
+	OpenFixableIssueIDs(ctx context.Context, reportID string, kinds []IssueKind, limit int) ([]string, error)
+	// UpdateIssue 保存问题的处理状态
+	UpdateIssue(ctx context.Context, issue *Issue) error
+
+	// SaveFixJob 创建或更新修复任务
+	SaveFixJob(ctx context.Context, job *FixJob) error
+	// GetFixJob 获取修复任务（不存在时返回 nil）
+	GetFixJob(ctx context.Context, id string) (*FixJob, error)
+	// ActiveFixJob 报告中未结束的修复任务（不存在时返回 nil）
+	ActiveFixJob(ctx context.Context, reportID string) (*FixJob, error)
+	// ClaimFixJobs 领取待执行的修复任务并标记为执行中（staleBefore 含义同 ClaimReports）
+	ClaimFixJobs(ctx context.Context, now, staleBefore time.Time, limit int) ([]*FixJob, error)
+
+	// GetSchedule 获取表的定时检查设置（未设置时返回 nil）
+	GetSchedule(ctx context.Context, tableID string) (*Schedule, error)
+	// SaveSchedule 创建或更新定时检查设置
+	SaveSchedule(ctx context.Context, schedule *Schedule) error
+	// ClaimDueSchedules 领取到期的定时检查，并在同一事务中把下次检查时间推后一个间隔
+	ClaimDueSchedules(ctx context.Context, now time.Time, limit int) ([]*Schedule, error)
+}
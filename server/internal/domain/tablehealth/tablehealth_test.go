@@ -0,0 +1,56 @@
+package tablehealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReportCounts(t *testing.T) {
+	now := time.Now()
+	report := NewReport("tbl1", TriggerManual, "usr1", now)
+
+	report.Count(NewIssue(report, KindOrphanedLink, "fld1", "项目", "rec1", []interface{}{"rec9"}, "关联的记录已删除"))
+	report.Count(NewIssue(report, KindOrphanedLink, "fld1", "项目", "", nil, "关联的表已不存在"))
+	report.Count(NewIssue(report, KindValidation, "fld2", "状态", "rec1", "未知", "选项不存在"))
+	report.Count(NewIssue(report, KindTypeCoercion, "fld3", "数量", "rec2", "12", "可转换为数字"))
+
+	if report.Total() != 4 || report.Counts[KindOrphanedLink] != 2 {
+		t.Errorf("统计 = %+v", report.Counts)
+	}
+	// 字段级问题与校验失败不可自动修复
+	if report.Fixable != 2 {
+		t.Errorf("可修复 = %d", report.Fixable)
+	}
+
+	report.Finish(errors.New("表不存在"), now)
+	if report.Status != StatusFailed || report.FinishedAt == nil || !report.Status.Finished() {
+		t.Errorf("结束状态 = %+v", report)
+	}
+	report.Reset()
+	if report.Total() != 0 || report.Fixable != 0 || report.Error != "" {
+		t.Errorf("重置后 = %+v", report)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	now := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	s := &Schedule{TableID: "tbl1", IntervalHours: 24, Enabled: true}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	s.Advance(now)
+	if s.NextRunAt == nil || !s.NextRunAt.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("下次检查 = %v", s.NextRunAt)
+	}
+	s.Enabled = false
+	s.Advance(now)
+	if s.NextRunAt != nil {
+		t.Errorf("停用后下次检查 = %v", s.NextRunAt)
+	}
+	for _, hours := range []int{0, MaxScheduleHours + 1} {
+		if err := (&Schedule{IntervalHours: hours}).Validate(); err == nil {
+			t.Errorf("间隔 %d 小时应校验失败", hours)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package textextract
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// MaxRulesPerTable 单表的提取规则数上限
+	MaxRulesPerTable = 10
+	// MaxLanguages 单条规则可指定的 OCR 语言数上限
+	MaxLanguages = 5
+)
+
+// languagePattern OCR 语言代码（Tesseract 语言包名，如 eng、chi_sim）
+var languagePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{1,19}$`)
+
+// Rule 附件文本提取规则 ✨
+// 源附件字段的附件发生变化时，后台提取其中 PDF、图片等文件的文本（扫描件经 OCR 识别），
+// 按附件顺序拼接后写入目标长文本字段，写入后照常进入全文与语义索引
+type Rule struct {
+	ID            string    `json:"id"`
+	TableID       string    `json:"table_id"`
+	SourceFieldID string    `json:"source_field_id"`
+	TargetFieldID string    `json:"target_field_id"`
+	Languages     []string  `json:"languages"` // OCR 语言，为空时使用服务端默认语言
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewRule 创建提取规则
+func NewRule(tableID, sourceFieldID, targetFieldID string, languages []string, createdBy string, now time.Time) *Rule {
+	return &Rule{
+		ID:            utils.GenerateIDWithPrefix("txr"),
+		TableID:       tableID,
+		SourceFieldID: sourceFieldID,
+		TargetFieldID: targetFieldID,
+		Languages:     languages,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Validate 校验规则，并去除重复的语言代码
+func (r *Rule) Validate() error {
+	if r.SourceFieldID == "" || r.TargetFieldID == "" {
+		return fmt.Errorf("源字段和目标字段不能为空")
+	}
+	if r.SourceFieldID == r.TargetFieldID {
+		return fmt.Errorf("源字段和目标字段不能相同")
+	}
+	seen := make(map[string]bool, len(r.Languages))
+	languages := make([]string, 0, len(r.Languages))
+	for _, lang := range r.Languages {
+		lang = strings.TrimSpace(lang)
+		if !languagePattern.MatchString(lang) {
+			return fmt.Errorf("无效的 OCR 语言: %s", lang)
+		}
+		if !seen[lang] {
+			seen[lang] = true
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) > MaxLanguages {
+		return fmt.Errorf("最多指定 %d 种 OCR 语言", MaxLanguages)
+	}
+	r.Languages = languages
+	return nil
+}
+
+// Status 提取任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // 等待后台执行
+	StatusRunning   Status = "running"   // 执行中（持有租约）
+	StatusSucceeded Status = "succeeded" // 已写入目标字段
+	StatusFailed    Status = "failed"    // 重试耗尽或规则、记录已不存在
+)
+
+// Trigger 提取任务的触发原因
+type Trigger string
+
+const (
+	TriggerCreate        Trigger = "create"         // 新建记录时源字段有附件
+	TriggerSourceChanged Trigger = "source_changed" // 源字段的附件发生变化
+	TriggerManual        Trigger = "manual"         // 手动重新提取
+)
+
+// Job 文本提取任务
+// 一个任务对应一条记录，执行时读取源字段的最新附件，因此同一记录只需保留一个待执行任务
+type Job struct {
+	ID         string     `json:"id"`
+	RuleID     string     `json:"rule_id"`
+	TableID    string     `json:"table_id"`
+	RecordID   string     `json:"record_id"`
+	Trigger    Trigger    `json:"trigger"`
+	Status     Status     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+	Engine     string     `json:"engine,omitempty"`
+	Files      int        `json:"files"`   // 成功提取文本的附件数
+	Skipped    int        `json:"skipped"` // 类型不支持、过大、未通过扫描或无法解析而跳过的附件数
+	Chars      int        `json:"chars"`   // 写入目标字段的字符数
+	CreatedBy  string     `json:"created_by"`
+	NextRunAt  time.Time  `json:"next_run_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// NewJob 创建待执行的提取任务
+func NewJob(rule *Rule, recordID string, trigger Trigger, createdBy string, now time.Time) *Job {
+	return &Job{
+		ID:        utils.GenerateIDWithPrefix("txj"),
+		RuleID:    rule.ID,
+		TableID:   rule.TableID,
+		RecordID:  recordID,
+		Trigger:   trigger,
+		Status:    StatusPending,
+		CreatedBy: createdBy,
+		NextRunAt: now,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Finished 任务是否已结束
+func (j *Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// Succeed 记录提取结果并结束任务
+func (j *Job) Succeed(engine string, files, skipped, chars int, now time.Time) {
+	j.Status = StatusSucceeded
+	j.Engine = engine
+	j.Files = files
+	j.Skipped = skipped
+	j.Chars = chars
+	j.Error = ""
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// Fail 以失败结束任务（不再重试）
+func (j *Job) Fail(err error, now time.Time) {
+	j.Status = StatusFailed
+	j.Error = err.Error()
+	j.UpdatedAt = now
+	j.FinishedAt = &now
+}
+
+// Retry 执行失败后安排重试（指数退避，最长 10 分钟）
+// 重试次数耗尽时返回 false，调用方应调用 Fail
+func (j *Job) Retry(err error, maxAttempts int, now time.Time) bool {
+	j.Error = err.Error()
+	j.UpdatedAt = now
+	if j.Attempts >= maxAttempts {
+		return false
+	}
+	backoff := time.Duration(1<<uint(j.Attempts)) * 15 * time.Second
+	if backoff > 10*time.Minute {
+		backoff = 10 * time.Minute
+	}
+	j.Status = StatusPending
+	j.NextRunAt = now.Add(backoff)
+	return true
+}
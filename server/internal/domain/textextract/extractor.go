@@ -0,0 +1,18 @@
+package textextract
+
+import (
+	"context"
+	"io"
+)
+
+// Extractor 文档文本提取引擎（Apache Tika、Tesseract 等）
+// 返回的错误可用 aifield.IsPermanent 判断：文件损坏或格式无法解析时为不可重试错误，该附件被跳过；
+// 引擎不可用等其他错误时任务稍后重试
+type Extractor interface {
+	// Name 引擎名称
+	Name() string
+	// Supports 引擎是否能处理该类型的文件
+	Supports(mimeType string) bool
+	// Extract 提取文件中的文本；languages 为 OCR 语言（Tesseract 语言包名）
+	Extract(ctx context.Context, content io.Reader, mimeType string, languages []string) (string, error)
+}
@@ -0,0 +1,32 @@
+package textextract
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 文本提取规则与任务仓储接口
+type Repository interface {
+	// ListRules 列出表的提取规则，按创建时间排序
+	ListRules(ctx context.Context, tableID string) ([]*Rule, error)
+	// GetRule 获取提取规则（不存在时返回 nil）
+	GetRule(ctx context.Context, id string) (*Rule, error)
+	// SaveRule 保存提取规则
+	SaveRule(ctx context.Context, rule *Rule) error
+	// DeleteRule 删除提取规则及其任务
+	DeleteRule(ctx context.Context, rule *Rule) error
+
+	// Enqueue 写入待执行任务（在业务事务中调用时随事务提交）
+	// 同一规则与记录已有待执行任务时跳过，返回实际写入的任务数
+	Enqueue(ctx context.Context, jobs ...*Job) (int, error)
+	// Claim 领取一批可执行任务并标记为执行中
+	// 可执行：到达执行时间的 pending 任务，或 staleBefore 之前领取且未结束的 running 任务；
+	// 同一规则与记录同时只执行一个任务
+	Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Job, error)
+	// Save 保存任务状态
+	Save(ctx context.Context, job *Job) error
+	// ListByRule 列出规则最近的任务（recordID 非空时只列该记录），按创建时间倒序
+	ListByRule(ctx context.Context, ruleID, recordID string, limit int) ([]*Job, error)
+	// DeleteFinishedBefore 删除 before 之前结束的任务
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
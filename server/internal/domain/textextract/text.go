@@ -0,0 +1,104 @@
+package textextract
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AttachmentRef 附件单元格中的一项（以附件ID或令牌关联附件表）
+type AttachmentRef struct {
+	ID    string
+	Token string
+	Name  string
+}
+
+// Attachments 解析附件单元格的值（附件对象数组，可能以 JSON 字符串形式存储）
+func Attachments(value interface{}) []AttachmentRef {
+	if text, ok := value.(string); ok {
+		if err := json.Unmarshal([]byte(text), &value); err != nil {
+			return nil
+		}
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	refs := make([]AttachmentRef, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref := AttachmentRef{}
+		ref.ID, _ = obj["id"].(string)
+		ref.Token, _ = obj["token"].(string)
+		ref.Name, _ = obj["name"].(string)
+		if ref.ID != "" || ref.Token != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Part 单个附件提取出的文本
+type Part struct {
+	Name string
+	Text string
+}
+
+// Compose 按附件顺序拼接提取出的文本
+// 多个附件时每段以文件名开头；结果超过 maxChars 个字符时截断（maxChars 为 0 表示不限制）
+func Compose(parts []Part, maxChars int) string {
+	sections := make([]string, 0, len(parts))
+	for _, part := range parts {
+		text := Normalize(part.Text)
+		if text == "" {
+			continue
+		}
+		if len(parts) > 1 {
+			text = "[" + part.Name + "]\n" + text
+		}
+		sections = append(sections, text)
+	}
+	result := strings.Join(sections, "\n\n")
+	if maxChars > 0 && utf8.RuneCountInString(result) > maxChars {
+		result = string([]rune(result)[:maxChars])
+	}
+	return result
+}
+
+// Normalize 清理提取结果：去除控制字符与行尾空白，合并连续空行
+func Normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == '\f' || r == '\r':
+			return '\n'
+		case unicode.IsControl(r) || r == utf8.RuneError:
+			return -1
+		default:
+			return r
+		}
+	}, text)
+
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
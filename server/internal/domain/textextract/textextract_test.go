@@ -0,0 +1,90 @@
+package textextract
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleValidate(t *testing.T) {
+	rule := NewRule("tbl1", "fldFiles", "fldText", []string{" eng", "chi_sim", "eng"}, "usr1", time.Now())
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("校验失败: %v", err)
+	}
+	if strings.Join(rule.Languages, "+") != "eng+chi_sim" {
+		t.Errorf("语言 = %v", rule.Languages)
+	}
+
+	invalid := []*Rule{
+		{SourceFieldID: "fldA", TargetFieldID: "fldA"},
+		{SourceFieldID: "", TargetFieldID: "fldB"},
+		{SourceFieldID: "fldA", TargetFieldID: "fldB", Languages: []string{"en;rm -rf"}},
+		{SourceFieldID: "fldA", TargetFieldID: "fldB", Languages: []string{"eng", "deu", "fra", "spa", "ita", "jpn"}},
+	}
+	for i, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("规则 %d 应校验失败", i)
+		}
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	value := []interface{}{
+		map[string]interface{}{"id": "att1", "name": "合同.pdf", "token": "tok1"},
+		"无效项",
+		map[string]interface{}{"name": "缺少标识.png"},
+		map[string]interface{}{"token": "tok2", "name": "扫描件.jpg"},
+	}
+	refs := Attachments(value)
+	if len(refs) != 2 || refs[0].ID != "att1" || refs[0].Name != "合同.pdf" || refs[1].Token != "tok2" {
+		t.Errorf("附件 = %+v", refs)
+	}
+	if refs := Attachments(`[{"id":"att9","name":"a.png"}]`); len(refs) != 1 || refs[0].ID != "att9" {
+		t.Errorf("JSON 字符串形式的附件 = %+v", refs)
+	}
+	if refs := Attachments(nil); len(refs) != 0 {
+		t.Errorf("空值应没有附件: %+v", refs)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	single := Compose([]Part{{Name: "a.pdf", Text: "第一页  \r\n\r\n\r\n\f第二页\x00"}}, 0)
+	if single != "第一页\n\n第二页" {
+		t.Errorf("单个附件 = %q", single)
+	}
+
+	multi := Compose([]Part{
+		{Name: "a.pdf", Text: "发票号 123"},
+		{Name: "b.png", Text: "   "},
+		{Name: "c.jpg", Text: "收据"},
+	}, 0)
+	if multi != "[a.pdf]\n发票号 123\n\n[c.jpg]\n收据" {
+		t.Errorf("多个附件 = %q", multi)
+	}
+
+	if got := Compose([]Part{{Name: "a", Text: "一二三四五"}}, 3); got != "一二三" {
+		t.Errorf("截断 = %q", got)
+	}
+}
+
+func TestJobRetry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	job := NewJob(&Rule{ID: "txr1", TableID: "tbl1"}, "rec1", TriggerCreate, "usr1", now)
+	job.Attempts = 2
+	if !job.Retry(errors.New("引擎不可用"), 3, now) {
+		t.Fatal("未耗尽次数时应安排重试")
+	}
+	if job.Status != StatusPending || job.NextRunAt != now.Add(time.Minute) || job.Error != "引擎不可用" {
+		t.Errorf("重试状态 = %+v", job)
+	}
+
+	job.Attempts = 3
+	if job.Retry(errors.New("引擎不可用"), 3, now) {
+		t.Error("次数耗尽时不应重试")
+	}
+	job.Succeed("tika", 2, 1, 120, now)
+	if !job.Finished() || job.Error != "" || job.Files != 2 || job.Skipped != 1 {
+		t.Errorf("完成状态 = %+v", job)
+	}
+}
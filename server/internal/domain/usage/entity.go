@@ -0,0 +1,228 @@
+package usage
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Metric 用量指标
+type Metric string
+
+const (
+	MetricRecordsCreated      Metric = "records_created"      // 新建记录数
+	MetricAPICalls            Metric = "api_calls"            // 服务令牌 API 调用次数（维度为令牌ID）
+	MetricAutomationRuns      Metric = "automation_runs"      // 后台任务运行次数（长时操作与数据仓库同步）
+	MetricStorageBytes        Metric = "storage_bytes"        // 附件占用空间（当天最后一次汇总时的值）
+	MetricActiveCollaborators Metric = "active_collaborators" // 当天有写入的协作者人数
+)
+
+// Metrics 全部指标（报表中的输出顺序）
+var Metrics = []Metric{
+	MetricRecordsCreated,
+	MetricAPICalls,
+	MetricAutomationRuns,
+	MetricStorageBytes,
+	MetricActiveCollaborators,
+}
+
+// spaceScoped 是否以空间维度汇总（BaseID 为空的行）
+// 活跃协作者按人去重，不能由各 Base 相加；API 调用无法归属到 Base
+func (m Metric) spaceScoped() bool {
+	return m == MetricAPICalls || m == MetricActiveCollaborators
+}
+
+// combine 多天的值如何合并：计数累加，占用空间取最后一天，活跃人数取峰值
+func (m Metric) combine(total, value int64) int64 {
+	switch m {
+	case MetricStorageBytes:
+		return value
+	case MetricActiveCollaborators:
+		if value > total {
+			return value
+		}
+		return total
+	default:
+		return total + value
+	}
+}
+
+// DailyUsage 按天汇总的一项用量 ✨
+// BaseID 为空表示空间维度的汇总；Dimension 为指标的细分维度（如 API 调用的令牌ID）
+type DailyUsage struct {
+	SpaceID   string
+	BaseID    string
+	Day       time.Time
+	Metric    Metric
+	Dimension string
+	Value     int64
+}
+
+// Day 时间所在的自然日（UTC 零点）
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+const (
+	dateLayout       = "2006-01-02"
+	defaultRangeDays = 30
+	maxRangeDays     = 366
+)
+
+// ParseRange 解析查询区间（含首尾两天，格式 2006-01-02）
+// 均为空时默认最近 30 天；只给一端时另一端按 30 天补齐；区间最长 366 天
+func ParseRange(from, to string, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if to != "" {
+		if end, err = time.Parse(dateLayout, to); err != nil {
+			return start, end, errors.New("to 格式应为 YYYY-MM-DD")
+		}
+	}
+	if from != "" {
+		if start, err = time.Parse(dateLayout, from); err != nil {
+			return start, end, errors.New("from 格式应为 YYYY-MM-DD")
+		}
+	}
+	switch {
+	case from == "" && to == "":
+		end = Day(now)
+		start = end.AddDate(0, 0, -(defaultRangeDays - 1))
+	case from == "":
+		start = end.AddDate(0, 0, -(defaultRangeDays - 1))
+	case to == "":
+		end = start.AddDate(0, 0, defaultRangeDays-1)
+	}
+	if end.Before(start) {
+		return start, end, errors.New("to 不能早于 from")
+	}
+	if int(end.Sub(start).Hours()/24)+1 > maxRangeDays {
+		return start, end, errors.New("查询区间不能超过 366 天")
+	}
+	return start, end, nil
+}
+
+// Values 各指标的值
+type Values map[Metric]int64
+
+// DayUsage 空间一天的用量
+type DayUsage struct {
+	Day    time.Time `json:"day"`
+	Values Values    `json:"values"`
+}
+
+// BaseUsage Base 在区间内的用量
+type BaseUsage struct {
+	BaseID   string `json:"base_id"`
+	BaseName string `json:"base_name,omitempty"`
+	Values   Values `json:"values"`
+}
+
+// TokenUsage 服务令牌在区间内的 API 调用次数
+type TokenUsage struct {
+	TokenID string `json:"token_id"`
+	Name    string `json:"name,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+	Calls   int64  `json:"calls"`
+}
+
+// Report 空间用量报表
+type Report struct {
+	SpaceID string        `json:"space_id"`
+	From    time.Time     `json:"from"`
+	To      time.Time     `json:"to"`
+	Totals  Values        `json:"totals"`
+	Days    []*DayUsage   `json:"days"`
+	Bases   []*BaseUsage  `json:"bases"`
+	Tokens  []*TokenUsage `json:"tokens"`
+}
+
+// BuildReport 由区间内的汇总行生成报表
+// 每天都会出现在 Days 中（无数据时为 0）；Bases 按新建记录数倒序，Tokens 按调用次数倒序
+func BuildReport(spaceID string, from, to time.Time, rows []*DailyUsage) *Report {
+	report := &Report{SpaceID: spaceID, From: from, To: to, Bases: []*BaseUsage{}, Tokens: []*TokenUsage{}}
+
+	days := make(map[time.Time]Values)
+	bases := make(map[string]map[time.Time]Values)
+	tokens := make(map[string]int64)
+	for _, row := range rows {
+		day := Day(row.Day)
+		if row.BaseID == "" {
+			if !row.Metric.spaceScoped() {
+				continue
+			}
+			addValue(days, day, row.Metric, row.Value)
+			if row.Metric == MetricAPICalls && row.Dimension != "" {
+				tokens[row.Dimension] += row.Value
+			}
+			continue
+		}
+		if !row.Metric.spaceScoped() {
+			addValue(days, day, row.Metric, row.Value)
+		}
+		if bases[row.BaseID] == nil {
+			bases[row.BaseID] = make(map[time.Time]Values)
+		}
+		addValue(bases[row.BaseID], day, row.Metric, row.Value)
+	}
+
+	for day := Day(from); !day.After(Day(to)); day = day.AddDate(0, 0, 1) {
+		values := Values{}
+		for _, metric := range Metrics {
+			values[metric] = days[day][metric]
+		}
+		report.Days = append(report.Days, &DayUsage{Day: day, Values: values})
+	}
+	report.Totals = combineDays(days)
+
+	for baseID, baseDays := range bases {
+		report.Bases = append(report.Bases, &BaseUsage{BaseID: baseID, Values: combineDays(baseDays)})
+	}
+	sort.Slice(report.Bases, func(i, j int) bool {
+		a, b := report.Bases[i], report.Bases[j]
+		if a.Values[MetricRecordsCreated] != b.Values[MetricRecordsCreated] {
+			return a.Values[MetricRecordsCreated] > b.Values[MetricRecordsCreated]
+		}
+		return a.BaseID < b.BaseID
+	})
+
+	for tokenID, calls := range tokens {
+		report.Tokens = append(report.Tokens, &TokenUsage{TokenID: tokenID, Calls: calls})
+	}
+	sort.Slice(report.Tokens, func(i, j int) bool {
+		a, b := report.Tokens[i], report.Tokens[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return a.TokenID < b.TokenID
+	})
+	return report
+}
+
+func addValue(days map[time.Time]Values, day time.Time, metric Metric, value int64) {
+	if days[day] == nil {
+		days[day] = Values{}
+	}
+	days[day][metric] += value
+}
+
+// combineDays 按日期顺序合并多天的值
+func combineDays(days map[time.Time]Values) Values {
+	ordered := make([]time.Time, 0, len(days))
+	for day := range days {
+		ordered = append(ordered, day)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Before(ordered[j]) })
+
+	totals := Values{}
+	for _, metric := range Metrics {
+		totals[metric] = 0
+	}
+	for _, day := range ordered {
+		for metric, value := range days[day] {
+			totals[metric] = metric.combine(totals[metric], value)
+		}
+	}
+	return totals
+}
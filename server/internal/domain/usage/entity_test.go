@@ -0,0 +1,75 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestParseRange(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	from, to, err := ParseRange("", "", now)
+	if err != nil || !to.Equal(day("2026-03-10")) || !from.Equal(day("2026-02-09")) {
+		t.Errorf("默认区间错误: %v ~ %v, %v", from, to, err)
+	}
+	from, to, err = ParseRange("2026-01-01", "", now)
+	if err != nil || !from.Equal(day("2026-01-01")) || !to.Equal(day("2026-01-30")) {
+		t.Errorf("只给 from 时区间错误: %v ~ %v, %v", from, to, err)
+	}
+
+	invalid := map[string][2]string{
+		"格式错误": {"2026/01/01", ""},
+		"倒序":   {"2026-02-01", "2026-01-01"},
+		"过长":   {"2025-01-01", "2026-01-02"},
+	}
+	for name, r := range invalid {
+		if _, _, err := ParseRange(r[0], r[1], now); err == nil {
+			t.Errorf("%s: 期望校验失败", name)
+		}
+	}
+}
+
+func TestBuildReport(t *testing.T) {
+	rows := []*DailyUsage{
+		{SpaceID: "spc", BaseID: "bse_a", Day: day("2026-03-01"), Metric: MetricRecordsCreated, Value: 5},
+		{SpaceID: "spc", BaseID: "bse_b", Day: day("2026-03-01"), Metric: MetricRecordsCreated, Value: 7},
+		{SpaceID: "spc", BaseID: "bse_a", Day: day("2026-03-02"), Metric: MetricRecordsCreated, Value: 3},
+		{SpaceID: "spc", BaseID: "bse_a", Day: day("2026-03-01"), Metric: MetricStorageBytes, Value: 100},
+		{SpaceID: "spc", BaseID: "bse_a", Day: day("2026-03-02"), Metric: MetricStorageBytes, Value: 80},
+		// 活跃协作者：空间维度按人去重，不由各 Base 相加
+		{SpaceID: "spc", BaseID: "bse_a", Day: day("2026-03-01"), Metric: MetricActiveCollaborators, Value: 2},
+		{SpaceID: "spc", BaseID: "bse_b", Day: day("2026-03-01"), Metric: MetricActiveCollaborators, Value: 2},
+		{SpaceID: "spc", Day: day("2026-03-01"), Metric: MetricActiveCollaborators, Value: 3},
+		{SpaceID: "spc", Day: day("2026-03-02"), Metric: MetricActiveCollaborators, Value: 1},
+		{SpaceID: "spc", Day: day("2026-03-01"), Metric: MetricAPICalls, Dimension: "stk_1", Value: 10},
+		{SpaceID: "spc", Day: day("2026-03-02"), Metric: MetricAPICalls, Dimension: "stk_1", Value: 5},
+		{SpaceID: "spc", Day: day("2026-03-02"), Metric: MetricAPICalls, Dimension: "stk_2", Value: 20},
+	}
+	report := BuildReport("spc", day("2026-03-01"), day("2026-03-03"), rows)
+
+	want := Values{
+		MetricRecordsCreated:      15,
+		MetricAPICalls:            35,
+		MetricAutomationRuns:      0,
+		MetricStorageBytes:        80,
+		MetricActiveCollaborators: 3,
+	}
+	for metric, value := range want {
+		if report.Totals[metric] != value {
+			t.Errorf("%s 合计为 %d，期望 %d", metric, report.Totals[metric], value)
+		}
+	}
+	if len(report.Days) != 3 || report.Days[0].Values[MetricActiveCollaborators] != 3 || report.Days[2].Values[MetricRecordsCreated] != 0 {
+		t.Errorf("按天用量错误: %+v", report.Days)
+	}
+	if len(report.Bases) != 2 || report.Bases[0].BaseID != "bse_a" || report.Bases[0].Values[MetricRecordsCreated] != 8 {
+		t.Errorf("Base 用量错误: %+v", report.Bases)
+	}
+	if len(report.Tokens) != 2 || report.Tokens[0].TokenID != "stk_2" || report.Tokens[1].Calls != 15 {
+		t.Errorf("令牌调用统计错误: %+v", report.Tokens)
+	}
+}
@@ -0,0 +1,23 @@
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 用量汇总仓储接口
+type Repository interface {
+	// Upsert 写入汇总值（同一空间、Base、日期、指标与维度已存在时覆盖）
+	Upsert(ctx context.Context, rows []*DailyUsage) error
+	// Increment 累加汇总值（用于内存中计数后批量写入的指标）
+	Increment(ctx context.Context, rows []*DailyUsage) error
+	// ListBySpace 列出空间在 [from, to] 日期区间内的汇总行
+	ListBySpace(ctx context.Context, spaceID string, from, to time.Time) ([]*DailyUsage, error)
+	// PurgeBefore 删除早于 day 的汇总行，返回数量
+	PurgeBefore(ctx context.Context, day time.Time) (int64, error)
+
+	// CountActivity 统计 [from, to) 内的新建记录、后台任务运行与活跃协作者（Day 为 from）
+	CountActivity(ctx context.Context, from, to time.Time) ([]*DailyUsage, error)
+	// MeasureStorage 统计各 Base 当前的附件占用空间（Day 由调用方设置）
+	MeasureStorage(ctx context.Context) ([]*DailyUsage, error)
+}
@@ -74,11 +74,13 @@ type AccountRepository interface {
 
 // UserFilter 用户过滤器
 type UserFilter struct {
+	IDs       []string // 按ID批量查询
 	Status    *valueobject.UserStatus
 	IsAdmin   *bool
 	IsSystem  *bool
 	Email     *string
 	Name      *string
+	Search    *string // 名称或邮箱模糊匹配（不区分大小写）
 	IsDeleted *bool
 	CreatedBy *string
 	OrderBy   string // name, email, created_at
@@ -448,6 +448,39 @@ func (v *View) GetAllFieldIDs() []string {
 	return fieldIDs
 }
 
+// DetachFields 从过滤、排序、分组和列配置中移除已删除的字段（级联删除时调用，不受锁定限制）
+// 返回视图是否发生了变化
+func (v *View) DetachFields(fieldIDs map[string]bool) bool {
+	changed := false
+	for _, fieldID := range v.GetAllFieldIDs() {
+		if !fieldIDs[fieldID] {
+			continue
+		}
+		changed = true
+		v.filter.RemoveFilterItem(fieldID)
+		v.sort.RemoveSortItem(fieldID)
+		v.group.RemoveGroupItem(fieldID)
+		v.columnMeta.RemoveColumn(fieldID)
+	}
+	if !changed {
+		return false
+	}
+
+	// 空的过滤/排序/分组无法通过校验，置空
+	if v.filter.IsEmpty() {
+		v.filter = nil
+	}
+	if v.sort.IsEmpty() {
+		v.sort = nil
+	}
+	if v.group.IsEmpty() {
+		v.group = nil
+	}
+	v.updatedAt = time.Now()
+	v.version++
+	return true
+}
+
 // Clone 克隆视图（用于复制）
 func (v *View) Clone(newName string, createdBy string) (*View, error) {
 	return &View{
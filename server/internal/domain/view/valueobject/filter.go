@@ -36,12 +36,19 @@ const (
 	FilterItemOpIsAfter  FilterItemOperator = "isAfter"  // 晚于
 	FilterItemOpIsWithin FilterItemOperator = "isWithin" // 在范围内
 
+	// FilterItemOpIsOverdueWorkdays 逾期超过 N 个工作日（按工作空间的工作日历，值为 N，省略时为 0 即早于今天）
+	FilterItemOpIsOverdueWorkdays FilterItemOperator = "isOverdueWorkdays"
+
 	// 数组操作
 	FilterItemOpHasAnyOf     FilterItemOperator = "hasAnyOf"     // 包含任意一个
 	FilterItemOpHasAllOf     FilterItemOperator = "hasAllOf"     // 包含全部
 	FilterItemOpHasNoneOf    FilterItemOperator = "hasNoneOf"    // 不包含任何
 	FilterItemOpIsExactly    FilterItemOperator = "isExactly"    // 完全匹配
 	FilterItemOpIsNotExactly FilterItemOperator = "isNotExactly" // 不完全匹配
+
+	// 地理位置
+	FilterItemOpIsWithinRadius  FilterItemOperator = "isWithinRadius"  // 在指定半径内
+	FilterItemOpIsInBoundingBox FilterItemOperator = "isInBoundingBox" // 在矩形范围内
 )
 
 // Filter 过滤器值对象
@@ -124,6 +131,22 @@ func (fi *FilterItem) Validate() error {
 		return fmt.Errorf("operator %s requires a value", fi.Operator)
 	}
 
+	// 地理位置操作符的值需要结构化校验
+	switch fi.Operator {
+	case FilterItemOpIsWithinRadius:
+		if _, err := ParseGeoRadius(fi.Value); err != nil {
+			return err
+		}
+	case FilterItemOpIsInBoundingBox:
+		if _, err := ParseGeoBoundingBox(fi.Value); err != nil {
+			return err
+		}
+	case FilterItemOpIsOverdueWorkdays:
+		if _, err := ParseOverdueWorkdays(fi.Value); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -148,6 +171,11 @@ func (fi *FilterItem) isValidOperator() bool {
 		FilterItemOpHasNoneOf:    true,
 		FilterItemOpIsExactly:    true,
 		FilterItemOpIsNotExactly: true,
+
+		FilterItemOpIsWithinRadius:  true,
+		FilterItemOpIsInBoundingBox: true,
+
+		FilterItemOpIsOverdueWorkdays: true,
 	}
 	return validOperators[fi.Operator]
 }
@@ -155,8 +183,9 @@ func (fi *FilterItem) isValidOperator() bool {
 // requiresValue 检查操作符是否需要值
 func (fi *FilterItem) requiresValue() bool {
 	noValueOperators := map[FilterItemOperator]bool{
-		FilterItemOpIsEmpty:    true,
-		FilterItemOpIsNotEmpty: true,
+		FilterItemOpIsEmpty:           true,
+		FilterItemOpIsNotEmpty:        true,
+		FilterItemOpIsOverdueWorkdays: true,
 	}
 	return !noValueOperators[fi.Operator]
 }
@@ -205,3 +234,19 @@ func (f *Filter) GetFieldIDs() []string {
 
 	return fieldIDs
 }
+
+// RemoveFilterItem 移除指定字段的过滤项
+func (f *Filter) RemoveFilterItem(fieldID string) {
+	if f == nil {
+		return
+	}
+
+	newItems := make([]FilterItem, 0, len(f.Filters))
+	for _, item := range f.Filters {
+		if item.FieldID != fieldID {
+			newItems = append(newItems, item)
+		}
+	}
+
+	f.Filters = newItems
+}
@@ -0,0 +1,95 @@
+package valueobject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// MaxGeoRadiusMeters 半径过滤的上限（地球周长的一半）
+const MaxGeoRadiusMeters = 20_037_509
+
+// GeoRadius isWithinRadius 过滤值：以 (Lat, Lng) 为圆心、Radius 米为半径
+type GeoRadius struct {
+	Lat    float64 `json:"lat"`
+	Lng    float64 `json:"lng"`
+	Radius float64 `json:"radius"` // 米
+}
+
+// GeoBoundingBox isInBoundingBox 过滤值：西南角 (South, West) 到东北角 (North, East)
+// West 大于 East 时表示跨越 180° 经线的范围
+type GeoBoundingBox struct {
+	South float64 `json:"south"`
+	West  float64 `json:"west"`
+	North float64 `json:"north"`
+	East  float64 `json:"east"`
+}
+
+// CrossesAntimeridian 是否跨越 180° 经线
+func (b GeoBoundingBox) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+// ParseGeoRadius 解析并校验半径过滤值
+func ParseGeoRadius(value interface{}) (*GeoRadius, error) {
+	var r GeoRadius
+	if err := decodeGeoValue(value, &r, "lat", "lng", "radius"); err != nil {
+		return nil, err
+	}
+	if err := validateLatLng(r.Lat, r.Lng); err != nil {
+		return nil, err
+	}
+	if !(r.Radius > 0) || r.Radius > MaxGeoRadiusMeters {
+		return nil, fmt.Errorf("radius must be between 0 and %d meters", MaxGeoRadiusMeters)
+	}
+	return &r, nil
+}
+
+// ParseGeoBoundingBox 解析并校验矩形范围过滤值
+func ParseGeoBoundingBox(value interface{}) (*GeoBoundingBox, error) {
+	var b GeoBoundingBox
+	if err := decodeGeoValue(value, &b, "south", "west", "north", "east"); err != nil {
+		return nil, err
+	}
+	if err := validateLatLng(b.South, b.West); err != nil {
+		return nil, err
+	}
+	if err := validateLatLng(b.North, b.East); err != nil {
+		return nil, err
+	}
+	if b.South > b.North {
+		return nil, fmt.Errorf("bounding box south must not be greater than north")
+	}
+	return &b, nil
+}
+
+// decodeGeoValue 将对象形式的过滤值解码到 target，keys 为必填项
+func decodeGeoValue(value interface{}, target interface{}, keys ...string) error {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("geo filter value must be an object")
+	}
+	for _, key := range keys {
+		if obj[key] == nil {
+			return fmt.Errorf("geo filter value requires %s", key)
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("invalid geo filter value: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid geo filter value: %w", err)
+	}
+	return nil
+}
+
+func validateLatLng(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180")
+	}
+	return nil
+}
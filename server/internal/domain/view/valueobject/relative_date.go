@@ -0,0 +1,116 @@
+package valueobject
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 日期过滤的相对日期关键字，按请求用户的时区（或字段统一的时区）解析为时间范围
+const (
+	RelativeDateToday           = "today"
+	RelativeDateTomorrow        = "tomorrow"
+	RelativeDateYesterday       = "yesterday"
+	RelativeDateNow             = "now"
+	RelativeDateOneWeekAgo      = "oneWeekAgo"
+	RelativeDateOneWeekFromNow  = "oneWeekFromNow"
+	RelativeDateOneMonthAgo     = "oneMonthAgo"
+	RelativeDateOneMonthFromNow = "oneMonthFromNow"
+
+	// 以下关键字只用于 isWithin，表示包含今天在内的一段时间
+	RelativeDatePastWeek  = "pastWeek"
+	RelativeDatePastMonth = "pastMonth"
+	RelativeDatePastYear  = "pastYear"
+	RelativeDateNextWeek  = "nextWeek"
+	RelativeDateNextMonth = "nextMonth"
+	RelativeDateNextYear  = "nextYear"
+)
+
+// DateRange 相对日期对应的时间范围 [Start, End)；now 的范围为空（Start 与 End 相同）
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// relativeDates 关键字（小写）到时间范围的计算，today 为 loc 时区中当天零点
+var relativeDates = map[string]func(today, now time.Time) DateRange{
+	strings.ToLower(RelativeDateToday):           func(today, _ time.Time) DateRange { return dayRange(today) },
+	strings.ToLower(RelativeDateTomorrow):        func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, 0, 1)) },
+	strings.ToLower(RelativeDateYesterday):       func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, 0, -1)) },
+	strings.ToLower(RelativeDateNow):             func(_, now time.Time) DateRange { return DateRange{Start: now, End: now} },
+	strings.ToLower(RelativeDateOneWeekAgo):      func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, 0, -7)) },
+	strings.ToLower(RelativeDateOneWeekFromNow):  func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, 0, 7)) },
+	strings.ToLower(RelativeDateOneMonthAgo):     func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, -1, 0)) },
+	strings.ToLower(RelativeDateOneMonthFromNow): func(today, _ time.Time) DateRange { return dayRange(today.AddDate(0, 1, 0)) },
+	strings.ToLower(RelativeDatePastWeek):        func(today, _ time.Time) DateRange { return DateRange{today.AddDate(0, 0, -7), today.AddDate(0, 0, 1)} },
+	strings.ToLower(RelativeDatePastMonth):       func(today, _ time.Time) DateRange { return DateRange{today.AddDate(0, -1, 0), today.AddDate(0, 0, 1)} },
+	strings.ToLower(RelativeDatePastYear):        func(today, _ time.Time) DateRange { return DateRange{today.AddDate(-1, 0, 0), today.AddDate(0, 0, 1)} },
+	strings.ToLower(RelativeDateNextWeek):        func(today, _ time.Time) DateRange { return DateRange{today, today.AddDate(0, 0, 8)} },
+	strings.ToLower(RelativeDateNextMonth):       func(today, _ time.Time) DateRange { return DateRange{today, today.AddDate(0, 1, 1)} },
+	strings.ToLower(RelativeDateNextYear):        func(today, _ time.Time) DateRange { return DateRange{today, today.AddDate(1, 0, 1)} },
+}
+
+// ResolveRelativeDate 在 loc 时区中解析相对日期关键字（大小写不敏感），非关键字返回 false
+// 日期类关键字为当天零点到次日零点，pastWeek 等为从 7 天前（或 1 个月、1 年前）到明天零点，
+// nextWeek 等为从今天零点到 7 天后（或 1 个月、1 年后）的次日零点
+func ResolveRelativeDate(value interface{}, now time.Time, loc *time.Location) (DateRange, bool) {
+	keyword, ok := value.(string)
+	if !ok {
+		return DateRange{}, false
+	}
+	resolve, ok := relativeDates[strings.ToLower(strings.TrimSpace(keyword))]
+	if !ok {
+		return DateRange{}, false
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	now = now.In(loc)
+	return resolve(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), now), true
+}
+
+// IsRelativeDate 值是否为相对日期关键字
+func IsRelativeDate(value interface{}) bool {
+	keyword, ok := value.(string)
+	if !ok {
+		return false
+	}
+	_, ok = relativeDates[strings.ToLower(strings.TrimSpace(keyword))]
+	return ok
+}
+
+func dayRange(day time.Time) DateRange {
+	return DateRange{Start: day, End: day.AddDate(0, 0, 1)}
+}
+
+// maxOverdueWorkdays isOverdueWorkdays 的工作日数上限
+const maxOverdueWorkdays = 1000
+
+// ParseOverdueWorkdays 解析 isOverdueWorkdays 的值（非负整数，数字或数字字符串，为空时为 0）
+func ParseOverdueWorkdays(value interface{}) (int, error) {
+	var n float64
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return 0, nil
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("isOverdueWorkdays 的值必须是工作日数: %s", v)
+		}
+		n = parsed
+	default:
+		return 0, fmt.Errorf("isOverdueWorkdays 的值必须是工作日数")
+	}
+	if n < 0 || n > maxOverdueWorkdays || n != float64(int(n)) {
+		return 0, fmt.Errorf("isOverdueWorkdays 的值必须是 0 到 %d 之间的整数", maxOverdueWorkdays)
+	}
+	return int(n), nil
+}
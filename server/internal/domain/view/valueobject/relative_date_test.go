@@ -0,0 +1,36 @@
+package valueobject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveRelativeDate(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	now := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC) // 上海 3月2日 02:30
+
+	cases := []struct {
+		keyword    string
+		loc        *time.Location
+		start, end time.Time
+	}{
+		{"today", time.UTC, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"today", shanghai, time.Date(2024, 3, 2, 0, 0, 0, 0, shanghai), time.Date(2024, 3, 3, 0, 0, 0, 0, shanghai)},
+		{"Yesterday", shanghai, time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai), time.Date(2024, 3, 2, 0, 0, 0, 0, shanghai)},
+		{"pastWeek", time.UTC, time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"now", shanghai, now, now},
+	}
+	for _, tc := range cases {
+		r, ok := ResolveRelativeDate(tc.keyword, now, tc.loc)
+		if !ok {
+			t.Fatalf("%s: expected keyword to resolve", tc.keyword)
+		}
+		if !r.Start.Equal(tc.start) || !r.End.Equal(tc.end) {
+			t.Fatalf("%s in %s: expected [%s, %s), got [%s, %s)", tc.keyword, tc.loc, tc.start, tc.end, r.Start, r.End)
+		}
+	}
+
+	if _, ok := ResolveRelativeDate("2024-03-01", now, time.UTC); ok {
+		t.Fatal("具体日期不是相对日期关键字")
+	}
+}
@@ -0,0 +1,67 @@
+package viewprojection
+
+import "time"
+
+// Projection 视图展示投影 ✨
+// 按视图保存已解析好展示值的行（选项名称与颜色、协作者姓名、关联记录标题），
+// 表格渲染直接按视图顺序分页读取，不再逐次解析；投影由 Base 变更流增量维护，
+// Cursor 为已应用的变更序号
+type Projection struct {
+	ViewID    string    `json:"view_id"`
+	BaseID    string    `json:"base_id"`
+	TableID   string    `json:"table_id"`
+	RecordIDs []string  `json:"record_ids"` // 按视图过滤与排序后的记录ID
+	Truncated bool      `json:"truncated"`  // 视图记录超过投影行数上限，只投影前面的记录
+	Cursor    int64     `json:"cursor"`
+	BuiltAt   time.Time `json:"built_at"` // 最近一次全量重建时间
+	ReadAt    time.Time `json:"read_at"`  // 最近一次读取时间（长期未读取的投影由后台清理）
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewProjection 创建空投影（需要全量重建后使用）
+func NewProjection(viewID, baseID, tableID string) *Projection {
+	now := time.Now()
+	return &Projection{
+		ViewID:    viewID,
+		BaseID:    baseID,
+		TableID:   tableID,
+		RecordIDs: []string{},
+		ReadAt:    now,
+		UpdatedAt: now,
+	}
+}
+
+// Page 按视图顺序取一页记录ID
+func (p *Projection) Page(offset, limit int) []string {
+	if offset >= len(p.RecordIDs) {
+		return []string{}
+	}
+	end := offset + limit
+	if end > len(p.RecordIDs) {
+		end = len(p.RecordIDs)
+	}
+	return p.RecordIDs[offset:end]
+}
+
+// Row 投影中的一行
+type Row struct {
+	ViewID    string           `json:"view_id"`
+	RecordID  string           `json:"record_id"`
+	Cells     map[string]*Cell `json:"cells"` // 按字段ID
+	Refs      []string         `json:"refs"`  // 引用的同一 Base 内关联记录（标题变化时据此刷新本行）
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Cell 展示就绪的单元格
+type Cell struct {
+	Value interface{} `json:"value,omitempty"` // 原始值
+	Text  string      `json:"text"`            // 展示文本
+	Items []CellItem  `json:"items,omitempty"` // 选项、协作者或关联记录
+}
+
+// CellItem 单元格中的选项、协作者或关联记录
+type CellItem struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Color string `json:"color,omitempty"`
+}
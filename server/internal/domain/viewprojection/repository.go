@@ -0,0 +1,31 @@
+package viewprojection
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 视图展示投影仓储接口
+type Repository interface {
+	// Find 获取视图的投影（不存在时返回 nil）
+	Find(ctx context.Context, viewID string) (*Projection, error)
+	// Save 创建或更新投影的记录顺序与进度
+	Save(ctx context.Context, p *Projection) error
+	// Touch 更新最近读取时间
+	Touch(ctx context.Context, viewID string, readAt time.Time) error
+	// Delete 删除投影及其全部行
+	Delete(ctx context.Context, viewID string) error
+	// ListReadSince 列出 since 之后读取过的投影（后台按最近读取时间倒序增量维护）
+	ListReadSince(ctx context.Context, since time.Time, limit int) ([]*Projection, error)
+	// DeleteReadBefore 删除 before 之前最后读取的投影及其行，返回删除的投影数
+	DeleteReadBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// FindRows 获取投影中的行（不存在的记录被跳过）
+	FindRows(ctx context.Context, viewID string, recordIDs []string) ([]*Row, error)
+	// SaveRows 创建或更新行及其关联引用
+	SaveRows(ctx context.Context, rows []*Row) error
+	// DeleteRows 删除行及其关联引用
+	DeleteRows(ctx context.Context, viewID string, recordIDs []string) error
+	// FindReferencing 列出引用了给定关联记录的行的记录ID
+	FindReferencing(ctx context.Context, viewID string, refIDs []string) ([]string, error)
+}
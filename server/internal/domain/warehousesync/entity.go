@@ -0,0 +1,139 @@
+package warehousesync
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// DestinationType 目标数据仓库类型
+type DestinationType string
+
+const (
+	DestinationBigQuery  DestinationType = "bigquery"
+	DestinationSnowflake DestinationType = "snowflake"
+)
+
+// Mode 同步触发方式
+type Mode string
+
+const (
+	// ModeSchedule 按间隔全量镜像：写入所有行，源中已不存在的行标记为软删除
+	ModeSchedule Mode = "schedule"
+	// ModeChangeFeed 从变更流增量同步：只写入变化的记录，删除的记录标记为软删除
+	ModeChangeFeed Mode = "change_feed"
+)
+
+// RunStatus 同步运行状态
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunFailed    RunStatus = "failed"
+)
+
+// Destination 目标表位置
+// BigQuery 使用 Project/Dataset/Table；Snowflake 使用 Account/Database/Schema/Table（Warehouse 可选）
+type Destination struct {
+	Type      DestinationType `json:"type"`
+	Project   string          `json:"project,omitempty"`
+	Dataset   string          `json:"dataset,omitempty"`
+	Account   string          `json:"account,omitempty"`
+	User      string          `json:"user,omitempty"`
+	Database  string          `json:"database,omitempty"`
+	Schema    string          `json:"schema,omitempty"`
+	Warehouse string          `json:"warehouse,omitempty"`
+	Role      string          `json:"role,omitempty"`
+	Table     string          `json:"table"`
+}
+
+// Job 数据仓库同步任务 ✨
+// 将一张表（或表的一个视图）镜像到 BigQuery / Snowflake：
+// 目标表按字段自动建列，新增字段时追加列（删除的字段保留原列），
+// 删除的记录以 _deleted 标记软删除而不物理删除
+type Job struct {
+	ID          string        `json:"id"`
+	BaseID      string        `json:"base_id"`
+	TableID     string        `json:"table_id"`
+	ViewID      string        `json:"view_id,omitempty"`
+	Name        string        `json:"name"`
+	Destination Destination   `json:"destination"`
+	Credentials string        `json:"-"` // BigQuery 服务账号 JSON 或 Snowflake 私钥 PEM，不在响应中返回
+	Mode        Mode          `json:"mode"`
+	Interval    time.Duration `json:"interval"` // 全量镜像间隔（变更流模式使用全局轮询间隔）
+	Enabled     bool          `json:"enabled"`
+	Cursor      int64         `json:"cursor"` // 变更流模式已同步的序号
+	NextRunAt   time.Time     `json:"next_run_at"`
+	LastRunAt   *time.Time    `json:"last_run_at,omitempty"`
+	LastStatus  RunStatus     `json:"last_status,omitempty"`
+	LastError   string        `json:"last_error,omitempty"`
+	CreatedBy   string        `json:"created_by"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// NewJob 创建同步任务（创建后立即执行首次全量同步）
+func NewJob(baseID, tableID, viewID, name string, destination Destination, credentials string, mode Mode, interval time.Duration, createdBy string) *Job {
+	now := time.Now()
+	return &Job{
+		ID:          utils.GenerateIDWithPrefix("whs"),
+		BaseID:      baseID,
+		TableID:     tableID,
+		ViewID:      viewID,
+		Name:        name,
+		Destination: destination,
+		Credentials: credentials,
+		Mode:        mode,
+		Interval:    interval,
+		Enabled:     true,
+		NextRunAt:   now,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// NeedsFullSync 本次运行是否需要全量同步（定时模式每次全量，变更流模式首次全量）
+func (j *Job) NeedsFullSync() bool {
+	return j.Mode == ModeSchedule || j.LastRunAt == nil || j.Cursor == 0
+}
+
+// Run 一次同步运行的记录
+type Run struct {
+	ID            string     `json:"id"`
+	JobID         string     `json:"job_id"`
+	Status        RunStatus  `json:"status"`
+	Full          bool       `json:"full"` // 是否为全量同步
+	Upserted      int64      `json:"upserted"`
+	Deleted       int64      `json:"deleted"`
+	AddedColumns  []string   `json:"added_columns,omitempty"` // 本次因字段新增而追加的列
+	Error         string     `json:"error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CursorAtStart int64      `json:"cursor_at_start"`
+}
+
+// NewRun 开始一次同步运行
+func NewRun(job *Job, full bool) *Run {
+	return &Run{
+		ID:            utils.GenerateIDWithPrefix("whr"),
+		JobID:         job.ID,
+		Status:        RunRunning,
+		Full:          full,
+		StartedAt:     time.Now(),
+		CursorAtStart: job.Cursor,
+	}
+}
+
+// Finish 结束运行（err 为空时视为成功）
+func (r *Run) Finish(err error) {
+	now := time.Now()
+	r.FinishedAt = &now
+	if err != nil {
+		r.Status = RunFailed
+		r.Error = err.Error()
+		return
+	}
+	r.Status = RunSucceeded
+}
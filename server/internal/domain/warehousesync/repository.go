@@ -0,0 +1,29 @@
+package warehousesync
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 数据仓库同步仓储接口
+type Repository interface {
+	// Save 创建或更新任务配置（不覆盖同步进度）
+	Save(ctx context.Context, job *Job) error
+	// FindByID 获取任务（不存在时返回 nil）
+	FindByID(ctx context.Context, id string) (*Job, error)
+	// ListByBase 列出 Base 下的任务
+	ListByBase(ctx context.Context, baseID string) ([]*Job, error)
+	// Delete 删除任务及其运行记录
+	Delete(ctx context.Context, id string) error
+	// ClaimDue 领取已到执行时间的启用任务并加租约，租约期内其他实例不会重复领取
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*Job, error)
+	// SaveProgress 保存同步进度与下次执行时间并释放租约
+	SaveProgress(ctx context.Context, job *Job) error
+
+	// SaveRun 创建或更新运行记录
+	SaveRun(ctx context.Context, run *Run) error
+	// ListRuns 按开始时间倒序列出任务的运行记录
+	ListRuns(ctx context.Context, jobID string, limit int) ([]*Run, error)
+	// PurgeRuns 只保留任务最近 keep 条运行记录
+	PurgeRuns(ctx context.Context, jobID string, keep int) error
+}
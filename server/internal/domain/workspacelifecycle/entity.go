@@ -0,0 +1,345 @@
+package workspacelifecycle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// BundleFormat 导出包清单的格式标识
+const BundleFormat = "luckdb-workspace-bundle/v1"
+
+// MaxReasonLength 停用与删除原因的长度上限
+const MaxReasonLength = 500
+
+// State 工作空间生命周期状态
+type State string
+
+const (
+	// StateActive 正常使用
+	StateActive State = "active"
+	// StateSuspended 已停用：只允许读取
+	StateSuspended State = "suspended"
+	// StatePendingDeletion 已计划删除：宽限期内只允许读取，可撤销
+	StatePendingDeletion State = "pending_deletion"
+	// StatePurging 宽限期已结束，后台正在导出并清除
+	StatePurging State = "purging"
+	// StatePurged 已清除：数据表、附件、缓存与事件均已删除，仅保留本记录与导出包
+	StatePurged State = "purged"
+)
+
+// IsValidState 是否为有效的状态
+func IsValidState(state State) bool {
+	switch state {
+	case StateActive, StateSuspended, StatePendingDeletion, StatePurging, StatePurged:
+		return true
+	}
+	return false
+}
+
+// Lifecycle 工作空间生命周期 ✨
+// 管理员可以停用工作空间（只读）或计划删除（宽限期内只读且可撤销）；
+// 宽限期结束后由后台先生成完整导出包，再硬删除全部数据表、附件、缓存与事件
+type Lifecycle struct {
+	SpaceID             string       `json:"space_id"`
+	State               State        `json:"state"`
+	Reason              string       `json:"reason,omitempty"`
+	SuspendedAt         *time.Time   `json:"suspended_at,omitempty"`
+	DeletionRequestedAt *time.Time   `json:"deletion_requested_at,omitempty"`
+	PurgeAfter          *time.Time   `json:"purge_after,omitempty"` // 宽限期结束时间
+	PurgeBundleID       string       `json:"purge_bundle_id,omitempty"`
+	Purge               *PurgeReport `json:"purge,omitempty"`
+	PurgedAt            *time.Time   `json:"purged_at,omitempty"`
+	LastError           string       `json:"last_error,omitempty"` // 最近一次清除失败的原因，下个周期重试
+	UpdatedBy           string       `json:"updated_by,omitempty"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+// PurgeReport 清除结果
+type PurgeReport struct {
+	BundleID         string           `json:"bundle_id"`
+	Bases            int              `json:"bases"`
+	DynamicTables    int              `json:"dynamic_tables"`
+	AttachmentFiles  int              `json:"attachment_files"`            // 删除的附件与缩略图文件数
+	AttachmentErrors int              `json:"attachment_errors,omitempty"` // 删除失败的文件数（元数据已清除，需人工清理）
+	ReleasedBlobs    int              `json:"released_blobs"`              // 释放的去重文件引用数，由回收任务删除文件
+	MetadataRows     map[string]int64 `json:"metadata_rows"`               // 表名 → 删除的行数
+}
+
+// NewLifecycle 创建正常状态的生命周期（尚未保存过的空间）
+func NewLifecycle(spaceID string, now time.Time) *Lifecycle {
+	return &Lifecycle{SpaceID: spaceID, State: StateActive, UpdatedAt: now}
+}
+
+// ReadOnly 是否只允许读取
+func (l *Lifecycle) ReadOnly() bool {
+	return l.State != StateActive
+}
+
+// Due 宽限期是否已结束
+func (l *Lifecycle) Due(now time.Time) bool {
+	return l.State == StatePendingDeletion && l.PurgeAfter != nil && !l.PurgeAfter.After(now)
+}
+
+// Suspend 停用工作空间
+func (l *Lifecycle) Suspend(reason, userID string, now time.Time) error {
+	if l.State != StateActive {
+		return fmt.Errorf("工作空间当前状态为 %s，只有正常状态可以停用", l.State)
+	}
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	l.State = StateSuspended
+	l.Reason = reason
+	l.SuspendedAt = &now
+	l.touch(userID, now)
+	return nil
+}
+
+// Resume 恢复已停用的工作空间（计划删除须先撤销）
+func (l *Lifecycle) Resume(userID string, now time.Time) error {
+	if l.State != StateSuspended {
+		return fmt.Errorf("工作空间当前状态为 %s，只有已停用状态可以恢复", l.State)
+	}
+	l.State = StateActive
+	l.Reason = ""
+	l.SuspendedAt = nil
+	l.touch(userID, now)
+	return nil
+}
+
+// ScheduleDeletion 计划在宽限期后删除（正常或已停用状态均可）
+func (l *Lifecycle) ScheduleDeletion(reason string, grace time.Duration, userID string, now time.Time) error {
+	if l.State != StateActive && l.State != StateSuspended {
+		return fmt.Errorf("工作空间当前状态为 %s，不能计划删除", l.State)
+	}
+	reason, err := normalizeReason(reason)
+	if err != nil {
+		return err
+	}
+	if reason != "" || l.State == StateActive {
+		l.Reason = reason
+	}
+	if l.SuspendedAt == nil {
+		l.SuspendedAt = &now
+	}
+	purgeAfter := now.Add(grace)
+	l.State = StatePendingDeletion
+	l.DeletionRequestedAt = &now
+	l.PurgeAfter = &purgeAfter
+	l.LastError = ""
+	l.touch(userID, now)
+	return nil
+}
+
+// CancelDeletion 撤销计划删除；工作空间保持停用，需要时再显式恢复
+func (l *Lifecycle) CancelDeletion(userID string, now time.Time) error {
+	if l.State != StatePendingDeletion {
+		return fmt.Errorf("工作空间当前状态为 %s，没有待执行的删除计划", l.State)
+	}
+	l.State = StateSuspended
+	l.DeletionRequestedAt = nil
+	l.PurgeAfter = nil
+	l.LastError = ""
+	l.touch(userID, now)
+	return nil
+}
+
+// FinishPurge 结束清除：成功时标记为已清除，失败时回到计划删除状态等待重试
+func (l *Lifecycle) FinishPurge(bundleID string, report *PurgeReport, err error, now time.Time) {
+	l.PurgeBundleID = bundleID
+	l.UpdatedAt = now
+	if err != nil {
+		l.State = StatePendingDeletion
+		l.LastError = err.Error()
+		return
+	}
+	l.State = StatePurged
+	l.Purge = report
+	l.PurgedAt = &now
+	l.LastError = ""
+}
+
+func (l *Lifecycle) touch(userID string, now time.Time) {
+	l.UpdatedBy = userID
+	l.UpdatedAt = now
+}
+
+func normalizeReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > MaxReasonLength {
+		return "", fmt.Errorf("原因不能超过 %d 个字符", MaxReasonLength)
+	}
+	return reason, nil
+}
+
+// BundleStatus 导出包状态
+type BundleStatus string
+
+const (
+	BundleRunning   BundleStatus = "running"
+	BundleCompleted BundleStatus = "completed"
+	BundleFailed    BundleStatus = "failed"
+)
+
+// Bundle 工作空间完整导出包 ✨
+// 包含元数据表的全部相关行、每张数据表的原始行（JSON Lines）与附件文件，写入对象存储；
+// 清除前必须存在计划删除之后生成的完成的导出包
+type Bundle struct {
+	ID         string       `json:"id"`
+	SpaceID    string       `json:"space_id"`
+	Status     BundleStatus `json:"status"`
+	Manifest   *Manifest    `json:"manifest,omitempty"` // 完成后写入
+	Error      string       `json:"error,omitempty"`
+	CreatedBy  string       `json:"created_by"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
+
+// Manifest 导出包清单：与数据文件一起写入对象存储
+type Manifest struct {
+	Format      string              `json:"format"`
+	BundleID    string              `json:"bundle_id"`
+	SpaceID     string              `json:"space_id"`
+	CreatedAt   time.Time           `json:"created_at"`
+	Metadata    []ManifestFile      `json:"metadata"` // 每张元数据表一个文件
+	Tables      []ManifestTable     `json:"tables"`   // 每张数据表一个文件
+	Attachments ManifestAttachments `json:"attachments"`
+}
+
+// ManifestFile 清单中的一张元数据表
+type ManifestFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Rows int64  `json:"rows"`
+}
+
+// ManifestTable 清单中的一张数据表
+type ManifestTable struct {
+	BaseID  string `json:"base_id"`
+	TableID string `json:"table_id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Rows    int64  `json:"rows"`
+}
+
+// ManifestAttachments 清单中的附件文件（保持原有的相对路径）
+type ManifestAttachments struct {
+	Prefix  string `json:"prefix"`
+	Files   int    `json:"files"`
+	Bytes   int64  `json:"bytes"`
+	Missing int    `json:"missing,omitempty"` // 存储中已不存在的文件数
+}
+
+// NewBundle 创建执行中的导出包
+func NewBundle(spaceID, createdBy string, now time.Time) *Bundle {
+	return &Bundle{
+		ID:        utils.GenerateIDWithPrefix("wsb"),
+		SpaceID:   spaceID,
+		Status:    BundleRunning,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Prefix 导出包在对象存储中的目录
+func (b *Bundle) Prefix() string {
+	return fmt.Sprintf("workspace-exports/%s/%s/", b.SpaceID, b.ID)
+}
+
+// ManifestPath 清单文件路径
+func (b *Bundle) ManifestPath() string {
+	return b.Prefix() + "manifest.json"
+}
+
+// MetadataPath 元数据表文件路径
+func (b *Bundle) MetadataPath(table string) string {
+	return b.Prefix() + "metadata/" + table + ".jsonl"
+}
+
+// DataPath 数据表文件路径
+func (b *Bundle) DataPath(baseID, tableID string) string {
+	return b.Prefix() + "data/" + baseID + "/" + tableID + ".jsonl"
+}
+
+// AttachmentPath 附件文件在导出包中的路径
+func (b *Bundle) AttachmentPath(path string) string {
+	return b.Prefix() + "attachments/" + strings.TrimLeft(path, "/")
+}
+
+// Running 是否仍在执行（staleBefore 之前未更新的视为已中断）
+func (b *Bundle) Running(staleBefore time.Time) bool {
+	return b.Status == BundleRunning && !b.UpdatedAt.Before(staleBefore)
+}
+
+// Covers 导出包是否可作为清除前的最终导出：计划删除后工作空间只读，之后生成的导出包即为最终数据
+func (b *Bundle) Covers(l *Lifecycle) bool {
+	return b.Status == BundleCompleted && l.DeletionRequestedAt != nil && !b.CreatedAt.Before(*l.DeletionRequestedAt)
+}
+
+// Finish 结束导出：成功时保存清单
+func (b *Bundle) Finish(manifest *Manifest, err error, now time.Time) {
+	b.Status = BundleCompleted
+	b.Manifest = manifest
+	if err != nil {
+		b.Status = BundleFailed
+		b.Manifest = nil
+		b.Error = err.Error()
+	}
+	b.UpdatedAt = now
+	b.FinishedAt = &now
+}
+
+// TableRef 工作空间中的一张数据表
+type TableRef struct {
+	BaseID  string
+	TableID string
+	Name    string
+	Deleted bool // 已软删除（物理表可能已不存在，不导出数据）
+}
+
+// Inventory 工作空间包含的 Base 与数据表（含已软删除的）
+type Inventory struct {
+	SpaceID string
+	BaseIDs []string
+	Tables  []TableRef
+}
+
+// IDs 空间、Base 与数据表的ID（用于定位元数据中的相关行）
+func (i *Inventory) IDs() []string {
+	ids := make([]string, 0, 1+len(i.BaseIDs)+len(i.Tables))
+	ids = append(ids, i.SpaceID)
+	ids = append(ids, i.BaseIDs...)
+	for _, t := range i.Tables {
+		ids = append(ids, t.TableID)
+	}
+	return ids
+}
+
+// AttachmentFile 附件及其存储文件
+type AttachmentFile struct {
+	ID          string
+	Path        string
+	Thumbnails  []string
+	ContentHash string // 非空时 Path 为按内容去重的共享文件
+	Deleted     bool   // 已软删除（去重文件的引用已在删除时释放）
+}
+
+// Paths 附件的全部文件
+func (a AttachmentFile) Paths() []string {
+	return append([]string{a.Path}, a.Thumbnails...)
+}
+
+// OwnedPaths 清除时可直接删除的文件：共享文件只释放引用，由回收任务删除
+func (a AttachmentFile) OwnedPaths() []string {
+	if a.ContentHash != "" {
+		return a.Thumbnails
+	}
+	return a.Paths()
+}
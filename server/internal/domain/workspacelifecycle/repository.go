@@ -0,0 +1,44 @@
+package workspacelifecycle
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 工作空间生命周期仓储接口
+type Repository interface {
+	// FindBySpace 获取空间的生命周期（从未变更过时返回 nil）
+	FindBySpace(ctx context.Context, spaceID string) (*Lifecycle, error)
+	// Save 创建或更新生命周期
+	Save(ctx context.Context, lifecycle *Lifecycle) error
+	// List 按更新时间倒序列出生命周期（states 为空时不过滤）
+	List(ctx context.Context, states []State) ([]*Lifecycle, error)
+	// ClaimDue 领取宽限期已结束或清除已中断（purging 且 staleBefore 之前未更新）的空间并标记为清除中
+	ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]*Lifecycle, error)
+
+	// SaveBundle 创建或更新导出包
+	SaveBundle(ctx context.Context, bundle *Bundle) error
+	// FindBundle 获取导出包（不存在时返回 nil）
+	FindBundle(ctx context.Context, id string) (*Bundle, error)
+	// ListBundles 按创建时间倒序列出空间的导出包
+	ListBundles(ctx context.Context, spaceID string, limit int) ([]*Bundle, error)
+}
+
+// DataStore 工作空间数据的枚举、导出与清除（直接访问元数据表与物理表）
+type DataStore interface {
+	// Inventory 列出空间的 Base 与数据表，含已软删除的（空间不存在时返回 nil）
+	Inventory(ctx context.Context, spaceID string) (*Inventory, error)
+	// MetadataTables 包含空间相关行的元数据表
+	MetadataTables(ctx context.Context) ([]string, error)
+	// ScanMetadata 分页读取元数据表中属于空间的行
+	ScanMetadata(ctx context.Context, table string, inv *Inventory, batchSize int, fn func(rows []map[string]interface{}) error) error
+	// ScanRows 按记录ID顺序分页读取数据表的全部列
+	ScanRows(ctx context.Context, ref TableRef, batchSize int, fn func(rows []map[string]interface{}) error) error
+	// Attachments 空间数据表中的附件（含已软删除的）
+	Attachments(ctx context.Context, inv *Inventory) ([]AttachmentFile, error)
+	// DropDataTables 删除空间全部 Base 的 Schema 与物理表
+	DropDataTables(ctx context.Context, inv *Inventory) error
+	// PurgeMetadata 在一个事务中删除空间的元数据行（含变更事件）并释放附件的去重文件引用，最后删除空间本身；
+	// 返回各表删除的行数与释放的引用数
+	PurgeMetadata(ctx context.Context, inv *Inventory) (map[string]int64, int, error)
+}
@@ -0,0 +1,116 @@
+package workspacelifecycle
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLifecycleTransitions(t *testing.T) {
+	now := time.Now()
+	l := NewLifecycle("spc1", now)
+	if l.ReadOnly() {
+		t.Fatal("正常状态不应只读")
+	}
+	if err := l.Resume("usr1", now); err == nil {
+		t.Error("正常状态不应可以恢复")
+	}
+	if err := l.Suspend(strings.Repeat("因", MaxReasonLength+1), "usr1", now); err == nil {
+		t.Error("原因过长应失败")
+	}
+
+	if err := l.Suspend(" 欠费 ", "usr1", now); err != nil {
+		t.Fatal(err)
+	}
+	if l.State != StateSuspended || l.Reason != "欠费" || !l.ReadOnly() || l.SuspendedAt == nil {
+		t.Errorf("停用后 = %+v", l)
+	}
+
+	// 停用后计划删除，未给原因时保留停用原因
+	later := now.Add(time.Hour)
+	if err := l.ScheduleDeletion("", 24*time.Hour, "usr2", later); err != nil {
+		t.Fatal(err)
+	}
+	if l.State != StatePendingDeletion || l.Reason != "欠费" || !l.SuspendedAt.Equal(now) || !l.PurgeAfter.Equal(later.Add(24*time.Hour)) {
+		t.Errorf("计划删除后 = %+v", l)
+	}
+	if l.Due(later) || !l.Due(later.Add(24*time.Hour)) {
+		t.Error("宽限期判断错误")
+	}
+	if err := l.Resume("usr1", later); err == nil {
+		t.Error("计划删除中不应直接恢复")
+	}
+
+	// 撤销后保持停用
+	if err := l.CancelDeletion("usr1", later); err != nil {
+		t.Fatal(err)
+	}
+	if l.State != StateSuspended || l.PurgeAfter != nil || l.DeletionRequestedAt != nil {
+		t.Errorf("撤销后 = %+v", l)
+	}
+	if err := l.Resume("usr1", later); err != nil || l.State != StateActive || l.SuspendedAt != nil {
+		t.Errorf("恢复后 = %+v, err = %v", l, err)
+	}
+}
+
+func TestLifecycleFinishPurge(t *testing.T) {
+	now := time.Now()
+	l := NewLifecycle("spc1", now)
+	if err := l.ScheduleDeletion("注销", 0, "usr1", now); err != nil {
+		t.Fatal(err)
+	}
+	l.State = StatePurging
+
+	l.FinishPurge("wsb1", nil, errors.New("连接中断"), now)
+	if l.State != StatePendingDeletion || l.LastError != "连接中断" || !l.Due(now) {
+		t.Errorf("失败后应回到计划删除等待重试: %+v", l)
+	}
+
+	l.FinishPurge("wsb1", &PurgeReport{DynamicTables: 2}, nil, now)
+	if l.State != StatePurged || l.LastError != "" || l.PurgedAt == nil || l.Purge.DynamicTables != 2 {
+		t.Errorf("清除后 = %+v", l)
+	}
+	if err := l.ScheduleDeletion("", time.Hour, "usr1", now); err == nil {
+		t.Error("已清除的空间不应再计划删除")
+	}
+}
+
+func TestBundle(t *testing.T) {
+	now := time.Now()
+	b := NewBundle("spc1", "usr1", now)
+	if !strings.HasPrefix(b.ID, "wsb") || !b.Running(now.Add(-time.Minute)) || b.Running(now.Add(time.Minute)) {
+		t.Errorf("导出包 = %+v", b)
+	}
+	if got := b.AttachmentPath("/2024/01/a.png"); got != b.Prefix()+"attachments/2024/01/a.png" {
+		t.Errorf("附件路径 = %s", got)
+	}
+
+	l := NewLifecycle("spc1", now)
+	_ = l.ScheduleDeletion("", time.Hour, "usr1", now.Add(time.Second))
+	b.Finish(&Manifest{}, nil, now)
+	if b.Covers(l) {
+		t.Error("计划删除之前生成的导出包不能作为最终导出")
+	}
+	after := NewBundle("spc1", "", now.Add(time.Minute))
+	after.Finish(nil, errors.New("失败"), now)
+	if after.Covers(l) || after.Manifest != nil {
+		t.Error("失败的导出包不能作为最终导出")
+	}
+	after.Status, after.Error = BundleCompleted, ""
+	if !after.Covers(l) {
+		t.Error("计划删除之后完成的导出包应可作为最终导出")
+	}
+}
+
+func TestAttachmentFilePaths(t *testing.T) {
+	owned := AttachmentFile{Path: "a.png", Thumbnails: []string{"a_s.png"}}
+	shared := AttachmentFile{Path: "blobs/ab/cd", Thumbnails: []string{"a_s.png"}, ContentHash: "abcd"}
+	if len(owned.OwnedPaths()) != 2 || len(shared.OwnedPaths()) != 1 || len(shared.Paths()) != 2 {
+		t.Errorf("owned = %v, shared = %v", owned.OwnedPaths(), shared.OwnedPaths())
+	}
+	inv := &Inventory{SpaceID: "spc1", BaseIDs: []string{"bse1"}, Tables: []TableRef{{BaseID: "bse1", TableID: "tbl1"}}}
+	if ids := inv.IDs(); strings.Join(ids, ",") != "spc1,bse1,tbl1" {
+		t.Errorf("ids = %v", ids)
+	}
+}
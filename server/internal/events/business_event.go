@@ -31,6 +31,9 @@ const (
 	BusinessEventTypeRecordCreate BusinessEventType = "record.create"
 	BusinessEventTypeRecordUpdate BusinessEventType = "record.update"
 	BusinessEventTypeRecordDelete BusinessEventType = "record.delete"
+	BusinessEventTypeRecordBatch  BusinessEventType = "record.batch"  // 混合批量变更（整批合并为一个事件）
+	BusinessEventTypeRecordLinked BusinessEventType = "record.linked" // 其他 Base 中被关联的记录发生变更
+	BusinessEventTypeRecordLock   BusinessEventType = "record.lock"   // 记录（或字段）被锁定/解锁
 
 	// 计算相关事件
 	BusinessEventTypeCalculationUpdate BusinessEventType = "calculation.update"
@@ -39,19 +42,28 @@ const (
 	BusinessEventTypeViewCreate BusinessEventType = "view.create"
 	BusinessEventTypeViewUpdate BusinessEventType = "view.update"
 	BusinessEventTypeViewDelete BusinessEventType = "view.delete"
+
+	// 长时操作事件（进度、条目级错误、结束状态）
+	BusinessEventTypeOperationUpdate BusinessEventType = "operation.update"
+
+	// 维护模式事件（只读开始或结束，客户端据此显示或隐藏提示横幅）
+	BusinessEventTypeMaintenanceUpdate BusinessEventType = "maintenance.update"
 )
 
 // BusinessEvent 业务事件结构
 type BusinessEvent struct {
-	ID        string            `json:"id"`
-	Type      BusinessEventType `json:"type"`
-	TableID   string            `json:"table_id,omitempty"`
-	RecordID  string            `json:"record_id,omitempty"`
-	FieldID   string            `json:"field_id,omitempty"`
-	Data      interface{}       `json:"data"`
-	UserID    string            `json:"user_id,omitempty"`
-	Timestamp int64             `json:"timestamp"`
-	Version   int64             `json:"version,omitempty"`
+	ID          string            `json:"id"`
+	Type        BusinessEventType `json:"type"`
+	SpaceID     string            `json:"space_id,omitempty"`
+	BaseID      string            `json:"base_id,omitempty"`
+	TableID     string            `json:"table_id,omitempty"`
+	RecordID    string            `json:"record_id,omitempty"`
+	FieldID     string            `json:"field_id,omitempty"`
+	OperationID string            `json:"operation_id,omitempty"`
+	Data        interface{}       `json:"data"`
+	UserID      string            `json:"user_id,omitempty"`
+	Timestamp   int64             `json:"timestamp"`
+	Version     int64             `json:"version,omitempty"`
 }
 
 // BusinessEventSubscriber 业务事件订阅者接口
@@ -79,6 +91,15 @@ type BusinessEventPublisher interface {
 
 	// PublishCalculationEvent 发布计算事件
 	PublishCalculationEvent(ctx context.Context, tableID, recordID string, data interface{}, userID string) error
+
+	// PublishLinkedRecordEvent 向关联字段所在的表发布被关联记录的变更（跨 Base 传播）
+	PublishLinkedRecordEvent(ctx context.Context, baseID, tableID, fieldID string, data interface{}, userID string) error
+
+	// PublishOperationEvent 发布长时操作事件
+	PublishOperationEvent(ctx context.Context, baseID, operationID string, data interface{}, userID string) error
+
+	// PublishMaintenanceEvent 发布维护模式事件（spaceID 与 baseID 均为空时为实例范围）
+	PublishMaintenanceEvent(ctx context.Context, spaceID, baseID string, data interface{}, userID string) error
 }
 
 // BusinessEventManager 业务事件管理器
@@ -284,6 +305,46 @@ func (m *BusinessEventManager) PublishCalculationEvent(ctx context.Context, tabl
 	return m.Publish(event)
 }
 
+// PublishLinkedRecordEvent 向关联字段所在的表发布被关联记录的变更
+func (m *BusinessEventManager) PublishLinkedRecordEvent(ctx context.Context, baseID, tableID, fieldID string, data interface{}, userID string) error {
+	event := &BusinessEvent{
+		Type:    BusinessEventTypeRecordLinked,
+		BaseID:  baseID,
+		TableID: tableID,
+		FieldID: fieldID,
+		Data:    data,
+		UserID:  userID,
+	}
+
+	return m.Publish(event)
+}
+
+// PublishOperationEvent 发布长时操作事件
+func (m *BusinessEventManager) PublishOperationEvent(ctx context.Context, baseID, operationID string, data interface{}, userID string) error {
+	event := &BusinessEvent{
+		Type:        BusinessEventTypeOperationUpdate,
+		BaseID:      baseID,
+		OperationID: operationID,
+		Data:        data,
+		UserID:      userID,
+	}
+
+	return m.Publish(event)
+}
+
+// PublishMaintenanceEvent 发布维护模式事件
+func (m *BusinessEventManager) PublishMaintenanceEvent(ctx context.Context, spaceID, baseID string, data interface{}, userID string) error {
+	event := &BusinessEvent{
+		Type:    BusinessEventTypeMaintenanceUpdate,
+		SpaceID: spaceID,
+		BaseID:  baseID,
+		Data:    data,
+		UserID:  userID,
+	}
+
+	return m.Publish(event)
+}
+
 // GetSubscriberCount 获取订阅者数量
 func (m *BusinessEventManager) GetSubscriberCount() int {
 	m.subMutex.RLock()
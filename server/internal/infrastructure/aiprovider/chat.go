@@ -0,0 +1,204 @@
+package aiprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+// maxResponseBytes 读取服务商响应的大小上限
+const maxResponseBytes = 4 << 20
+
+// chatClient OpenAI 协议客户端（OpenAI、Azure OpenAI 与各类兼容服务），支持 Chat Completions 与 Embeddings
+type chatClient struct {
+	name           string
+	defaultModel   string
+	embeddingModel string // 向量化默认模型（Azure 为部署名称）
+	client         *http.Client
+	// endpoint 返回本次请求的地址（operation 为 chat/completions 或 embeddings）；Azure 的模型即部署名称，体现在地址中
+	endpoint func(model, operation string) string
+	// authorize 设置认证头
+	authorize func(req *http.Request)
+	// sendModel 请求体是否携带 model（Azure 由部署决定模型）
+	sendModel bool
+}
+
+// newChatClient 创建 OpenAI 兼容客户端，apiKey 为空时不发送认证头
+func newChatClient(name, baseURL, apiKey, defaultModel string, client *http.Client) (*chatClient, error) {
+	base, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &chatClient{
+		name:         name,
+		defaultModel: defaultModel,
+		client:       client,
+		endpoint: func(_, operation string) string {
+			return base.String() + "/" + operation
+		},
+		authorize: func(req *http.Request) {
+			if apiKey != "" {
+				req.Header.Set("Authorization", "Bearer "+apiKey)
+			}
+		},
+		sendModel: true,
+	}, nil
+}
+
+// newAzureClient 创建 Azure OpenAI 客户端
+// 地址形如 {base_url}/openai/deployments/{部署名称}/chat/completions?api-version=...（向量化为 /embeddings）
+func newAzureClient(name, baseURL, apiKey, apiVersion, defaultDeployment string, client *http.Client) (*chatClient, error) {
+	base, err := parseBaseURL(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if apiVersion == "" {
+		apiVersion = azureDefaultAPIVersion
+	}
+	query := url.Values{"api-version": {apiVersion}}.Encode()
+	return &chatClient{
+		name:         name,
+		defaultModel: defaultDeployment,
+		client:       client,
+		endpoint: func(deployment, operation string) string {
+			return fmt.Sprintf("%s/openai/deployments/%s/%s?%s", base.String(), url.PathEscape(deployment), operation, query)
+		},
+		authorize: func(req *http.Request) {
+			req.Header.Set("api-key", apiKey)
+		},
+	}, nil
+}
+
+// Name 服务商名称
+func (c *chatClient) Name() string {
+	return c.name
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model,omitempty"`
+	Messages       []chatMessage   `json:"messages"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type errorResponse struct {
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Generate 调用 Chat Completions 接口生成文本
+func (c *chatClient) Generate(ctx context.Context, req *aifield.GenerateRequest) (*aifield.GenerateResult, error) {
+	model := req.Model
+	if model == "" {
+		model = c.defaultModel
+	}
+	if model == "" {
+		return nil, aifield.Permanent(errors.New("model is not configured"))
+	}
+
+	body := chatRequest{MaxTokens: req.MaxTokens, Temperature: req.Temperature}
+	if c.sendModel {
+		body.Model = model
+	}
+	if req.JSON {
+		body.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+	if req.SystemPrompt != "" {
+		body.Messages = append(body.Messages, chatMessage{Role: "system", Content: req.SystemPrompt})
+	}
+	body.Messages = append(body.Messages, chatMessage{Role: "user", Content: req.Prompt})
+
+	var parsed chatResponse
+	if err := c.post(ctx, c.endpoint(model, "chat/completions"), body, &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", c.name)
+	}
+
+	result := &aifield.GenerateResult{
+		Text:             strings.TrimSpace(parsed.Choices[0].Message.Content),
+		Model:            parsed.Model,
+		PromptTokens:     parsed.Usage.PromptTokens,
+		CompletionTokens: parsed.Usage.CompletionTokens,
+	}
+	if result.Model == "" {
+		result.Model = model
+	}
+	return result, nil
+}
+
+// post 发送请求并解析响应
+// 认证失败、参数无效等 4xx 错误标记为不可重试；429 与 5xx 可重试
+func (c *chatClient) post(ctx context.Context, endpoint string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return aifield.Permanent(err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return aifield.Permanent(fmt.Errorf("failed to build request: %w", err))
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.authorize(httpReq)
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", c.name, err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := strings.TrimSpace(string(raw))
+		var parsed errorResponse
+		if json.Unmarshal(raw, &parsed) == nil && parsed.Error != nil && parsed.Error.Message != "" {
+			message = parsed.Error.Message
+		}
+		if len(message) > 500 {
+			message = message[:500]
+		}
+		err := fmt.Errorf("%s returned %d: %s", c.name, resp.StatusCode, message)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return err
+		}
+		return aifield.Permanent(err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("invalid %s response: %w", c.name, err)
+	}
+	return nil
+}
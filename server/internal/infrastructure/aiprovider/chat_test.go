@@ -0,0 +1,181 @@
+package aiprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+// fakeChatServer 记录收到的请求并返回固定回答
+func fakeChatServer(t *testing.T, status int, check func(r *http.Request, body chatRequest)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("请求体无效: %v", err)
+		}
+		if check != nil {
+			check(r, body)
+		}
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte(`{"error":{"message":"bad things"}}`))
+			return
+		}
+		w.Write([]byte(`{"model":"gpt-4o-mini-2024","choices":[{"message":{"role":"assistant","content":"  答案  "}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOpenAICompatibleGenerate(t *testing.T) {
+	srv := fakeChatServer(t, http.StatusOK, func(r *http.Request, body chatRequest) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("请求路径 = %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer sk-test" {
+			t.Errorf("认证头 = %q", r.Header.Get("Authorization"))
+		}
+		if body.Model != "gpt-4o-mini" || len(body.Messages) != 2 || body.Messages[1].Content != "你好" || body.MaxTokens != 64 || body.ResponseFormat != nil {
+			t.Errorf("请求体 = %+v", body)
+		}
+	})
+
+	providers, err := New(config.AIConfig{Providers: map[string]config.AIProviderConfig{
+		"openai": {Type: TypeOpenAI, APIKey: "sk-test", BaseURL: srv.URL + "/v1/", DefaultModel: "gpt-4o-mini"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := providers["openai"].Generate(context.Background(), &aifield.GenerateRequest{
+		SystemPrompt: "简洁回答",
+		Prompt:       "你好",
+		MaxTokens:    64,
+	})
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	if result.Text != "答案" || result.Model != "gpt-4o-mini-2024" || result.TotalTokens() != 15 {
+		t.Errorf("结果 = %+v", result)
+	}
+}
+
+func TestAzureGenerate(t *testing.T) {
+	srv := fakeChatServer(t, http.StatusOK, func(r *http.Request, body chatRequest) {
+		if r.URL.Path != "/openai/deployments/my-gpt/chat/completions" || r.URL.Query().Get("api-version") != "2024-02-01" {
+			t.Errorf("请求地址 = %s", r.URL.String())
+		}
+		if r.Header.Get("api-key") != "azure-key" || r.Header.Get("Authorization") != "" {
+			t.Errorf("认证头错误: %v", r.Header)
+		}
+		if body.Model != "" {
+			t.Errorf("Azure 请求不应携带 model: %q", body.Model)
+		}
+		if body.ResponseFormat == nil || body.ResponseFormat.Type != "json_object" {
+			t.Errorf("JSON 输出应设置 response_format: %+v", body.ResponseFormat)
+		}
+	})
+
+	providers, err := New(config.AIConfig{Providers: map[string]config.AIProviderConfig{
+		"azure": {Type: TypeAzure, APIKey: "azure-key", BaseURL: srv.URL, APIVersion: "2024-02-01", DefaultModel: "my-gpt"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := providers["azure"].Generate(context.Background(), &aifield.GenerateRequest{Prompt: "hi", JSON: true}); err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+}
+
+func TestLocalProviderWithoutKey(t *testing.T) {
+	srv := fakeChatServer(t, http.StatusOK, func(r *http.Request, body chatRequest) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("本地服务不应发送认证头")
+		}
+	})
+	providers, err := New(config.AIConfig{Providers: map[string]config.AIProviderConfig{
+		"local": {Type: TypeLocal, BaseURL: srv.URL, DefaultModel: "llama3.1"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := providers["local"].Generate(context.Background(), &aifield.GenerateRequest{Prompt: "hi"}); err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+}
+
+func TestGenerateErrorClassification(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusUnauthorized:       true,
+		http.StatusBadRequest:         true,
+		http.StatusTooManyRequests:    false,
+		http.StatusServiceUnavailable: false,
+	}
+	for status, permanent := range cases {
+		srv := fakeChatServer(t, status, nil)
+		client, err := newChatClient("openai", srv.URL, "key", "gpt", http.DefaultClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Generate(context.Background(), &aifield.GenerateRequest{Prompt: "hi"})
+		if err == nil || !strings.Contains(err.Error(), "bad things") {
+			t.Errorf("状态 %d 应返回服务商错误信息，得到 %v", status, err)
+		}
+		if aifield.IsPermanent(err) != permanent {
+			t.Errorf("状态 %d 的可重试判断错误", status)
+		}
+	}
+}
+
+func TestNewSkipsInvalidProviders(t *testing.T) {
+	providers, err := New(config.AIConfig{Providers: map[string]config.AIProviderConfig{
+		"openai":    {Type: TypeOpenAI, APIKey: "${OPENAI_API_KEY}"},
+		"anthropic": {Type: "anthropic", APIKey: "key"},
+		"local":     {Type: TypeLocal, BaseURL: "http://localhost:11434/v1"},
+	}})
+	if err == nil {
+		t.Error("无效配置应返回错误")
+	}
+	if len(providers) != 1 || providers["local"] == nil {
+		t.Errorf("应只保留有效的服务商: %v", providers)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("请求体无效: %v", err)
+		}
+		if r.URL.Path != "/v1/embeddings" || body.Model != "text-embedding-3-small" || len(body.Input) != 2 {
+			t.Errorf("请求 = %s %+v", r.URL.Path, body)
+		}
+		// 乱序返回，客户端应按 index 对应输入
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":7,"total_tokens":7}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	providers, err := New(config.AIConfig{Providers: map[string]config.AIProviderConfig{
+		"openai": {Type: TypeOpenAI, APIKey: "sk-test", BaseURL: srv.URL + "/v1", EmbeddingModel: "text-embedding-3-small"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	embedder := Embedders(providers)["openai"]
+	if embedder == nil {
+		t.Fatal("服务商应支持向量化")
+	}
+	result, err := embedder.Embed(context.Background(), "", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("向量化失败: %v", err)
+	}
+	if result.Vectors[0][0] != 1 || result.Vectors[1][1] != 1 || result.Tokens != 7 || result.Model != "text-embedding-3-small" {
+		t.Errorf("结果 = %+v", result)
+	}
+}
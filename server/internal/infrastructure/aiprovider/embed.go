@@ -0,0 +1,79 @@
+package aiprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/semanticsearch"
+)
+
+type embeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Model string `json:"model"`
+	Data  []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Embed 调用 Embeddings 接口向量化文本，model 为空时使用 embedding_model 配置
+func (c *chatClient) Embed(ctx context.Context, model string, inputs []string) (*semanticsearch.EmbedResult, error) {
+	if model == "" {
+		model = c.embeddingModel
+	}
+	if model == "" {
+		return nil, aifield.Permanent(errors.New("embedding model is not configured"))
+	}
+
+	body := embeddingRequest{Input: inputs}
+	if c.sendModel {
+		body.Model = model
+	}
+	var parsed embeddingResponse
+	if err := c.post(ctx, c.endpoint(model, "embeddings"), body, &parsed); err != nil {
+		return nil, err
+	}
+	if len(parsed.Data) != len(inputs) {
+		return nil, fmt.Errorf("%s returned %d embeddings for %d inputs", c.name, len(parsed.Data), len(inputs))
+	}
+
+	// 按 index 排序，保证与输入一一对应
+	sort.Slice(parsed.Data, func(i, j int) bool { return parsed.Data[i].Index < parsed.Data[j].Index })
+	result := &semanticsearch.EmbedResult{
+		Vectors: make([][]float32, len(parsed.Data)),
+		Model:   parsed.Model,
+		Tokens:  parsed.Usage.TotalTokens,
+	}
+	for i, item := range parsed.Data {
+		result.Vectors[i] = item.Embedding
+	}
+	if result.Model == "" {
+		result.Model = model
+	}
+	if result.Tokens == 0 {
+		result.Tokens = parsed.Usage.PromptTokens
+	}
+	return result, nil
+}
+
+// Embedders 从已创建的服务商中筛选支持向量化的服务商
+func Embedders(providers map[string]aifield.Provider) map[string]semanticsearch.Embedder {
+	embedders := make(map[string]semanticsearch.Embedder, len(providers))
+	for name, provider := range providers {
+		if embedder, ok := provider.(semanticsearch.Embedder); ok {
+			embedders[name] = embedder
+		}
+	}
+	return embedders
+}
@@ -0,0 +1,99 @@
+package aiprovider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+// 服务商类型
+const (
+	TypeOpenAI   = "openai"
+	TypeDeepSeek = "deepseek" // OpenAI 兼容接口
+	TypeAzure    = "azure"
+	TypeLocal    = "local" // 本地 OpenAI 兼容服务（Ollama、vLLM、LM Studio 等），无需 API Key
+)
+
+const (
+	openAIDefaultBaseURL   = "https://api.openai.com/v1"
+	azureDefaultAPIVersion = "2024-06-01"
+	defaultTimeout         = 60 * time.Second
+)
+
+// New 根据 ai.providers 配置创建服务商，键为配置中的服务商名称
+// 单个服务商配置无效时跳过并在返回的错误中说明，其余服务商仍然可用
+func New(cfg config.AIConfig) (map[string]aifield.Provider, error) {
+	providers := make(map[string]aifield.Provider, len(cfg.Providers))
+	var errs []error
+	for name, pc := range cfg.Providers {
+		provider, err := newProvider(name, pc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ai provider %s: %w", name, err))
+			continue
+		}
+		providers[name] = provider
+	}
+	return providers, errors.Join(errs...)
+}
+
+func newProvider(name string, pc config.AIProviderConfig) (*chatClient, error) {
+	timeout := defaultTimeout
+	if pc.Timeout > 0 {
+		timeout = time.Duration(pc.Timeout) * time.Second
+	}
+	client := &http.Client{Timeout: timeout}
+
+	providerType := pc.Type
+	if providerType == "" {
+		providerType = name
+	}
+	apiKey := strings.TrimSpace(pc.APIKey)
+
+	var (
+		provider *chatClient
+		err      error
+	)
+	switch providerType {
+	case TypeOpenAI, TypeDeepSeek:
+		if apiKey == "" || strings.HasPrefix(apiKey, "${") {
+			return nil, errors.New("api_key is required")
+		}
+		baseURL := pc.BaseURL
+		if baseURL == "" {
+			baseURL = openAIDefaultBaseURL
+		}
+		provider, err = newChatClient(name, baseURL, apiKey, pc.DefaultModel, client)
+	case TypeLocal:
+		if pc.BaseURL == "" {
+			return nil, errors.New("base_url is required")
+		}
+		provider, err = newChatClient(name, pc.BaseURL, apiKey, pc.DefaultModel, client)
+	case TypeAzure:
+		if apiKey == "" || strings.HasPrefix(apiKey, "${") {
+			return nil, errors.New("api_key is required")
+		}
+		provider, err = newAzureClient(name, pc.BaseURL, apiKey, pc.APIVersion, pc.DefaultModel, client)
+	default:
+		return nil, fmt.Errorf("unsupported provider type: %s", providerType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider.embeddingModel = pc.EmbeddingModel
+	return provider, nil
+}
+
+// parseBaseURL 校验服务地址
+func parseBaseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimRight(raw, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid base_url: %q", raw)
+	}
+	return u, nil
+}
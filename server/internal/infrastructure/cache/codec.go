@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+)
+
+// 缓存条目信封 ✨
+// 所有条目以信封写入，附带写入方的结构版本：
+//
+//	[0xC1 魔数][信封版本][编码 ID][压缩 ID][结构版本长度][结构版本][负载]
+//
+// 结构版本与当前不一致的条目（部署前写入的旧结构、无信封的旧 JSON 条目），
+// 以及信封版本或编码未知的条目（如回滚后读到新版本写入的数据）均按未命中处理，由调用方回源后覆盖，
+// 部署后无需手动清空缓存
+const (
+	envelopeMagic   byte = 0xC1
+	envelopeVersion byte = 2
+	envelopeHeader       = 5
+)
+
+// EntrySchemaVersion 缓存条目结构版本
+// 修改会写入缓存的结构（字段、记录实体等）的序列化形状时递增，部署后旧条目自动失效
+const EntrySchemaVersion = "1"
+
+// 编码与压缩在信封中的 ID，已分配的值不可复用
+const (
+	codecJSON     byte = 1
+	codecMsgpack  byte = 2
+	codecProtobuf byte = 3
+
+	compressionNone   byte = 0
+	compressionSnappy byte = 1
+	compressionZstd   byte = 2
+)
+
+// errNotProtoMessage 值不是 proto.Message，protobuf 编码回退为 JSON
+var errNotProtoMessage = errors.New("value is not a proto.Message")
+
+// Codec 缓存值编码
+type Codec interface {
+	Name() string
+	ID() byte
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// jsonCodec JSON 编码（默认，与旧条目兼容）
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                               { return "json" }
+func (jsonCodec) ID() byte                                   { return codecJSON }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// msgpackCodec msgpack 编码，沿用结构体的 json 标签
+type msgpackCodec struct {
+	handle *codec.MsgpackHandle
+}
+
+func newMsgpackCodec() *msgpackCodec {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return &msgpackCodec{handle: h}
+}
+
+func (c *msgpackCodec) Name() string { return "msgpack" }
+func (c *msgpackCodec) ID() byte     { return codecMsgpack }
+
+func (c *msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var data []byte
+	if err := codec.NewEncoderBytes(&data, c.handle).Encode(v); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (c *msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, c.handle).Decode(v)
+}
+
+// protobufCodec protobuf 编码，只适用于 proto.Message，其余值回退为 JSON
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string { return "protobuf" }
+func (protobufCodec) ID() byte     { return codecProtobuf }
+
+func (protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, errNotProtoMessage
+	}
+	return proto.Marshal(msg)
+}
+
+func (protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return errNotProtoMessage
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// compressor 负载压缩
+type compressor interface {
+	ID() byte
+	Compress(data []byte) []byte
+	Decompress(data []byte) ([]byte, error)
+}
+
+// snappyCompressor snappy 压缩（兼容格式，速度优先）
+type snappyCompressor struct{}
+
+func (snappyCompressor) ID() byte                               { return compressionSnappy }
+func (snappyCompressor) Compress(data []byte) []byte            { return s2.EncodeSnappy(nil, data) }
+func (snappyCompressor) Decompress(data []byte) ([]byte, error) { return s2.Decode(nil, data) }
+
+// zstdCompressor zstd 压缩（压缩率优先），编解码器可并发复用
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func newZstdCompressor() (*zstdCompressor, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdCompressor{encoder: encoder, decoder: decoder}, nil
+}
+
+func (c *zstdCompressor) ID() byte                    { return compressionZstd }
+func (c *zstdCompressor) Compress(data []byte) []byte { return c.encoder.EncodeAll(data, nil) }
+func (c *zstdCompressor) Decompress(data []byte) ([]byte, error) {
+	return c.decoder.DecodeAll(data, nil)
+}
+
+// Serializer 缓存条目序列化器 ✨
+// 写入时使用配置的编码，负载达到阈值时压缩；读取时按信封中的编码与压缩方式解码，
+// 因此只切换编码或压缩方式时旧条目仍可读取，结构版本变化时旧条目按未命中处理
+type Serializer struct {
+	codec       Codec
+	compressor  compressor
+	threshold   int
+	schema      string
+	codecs      map[byte]Codec
+	compressors map[byte]compressor
+}
+
+// NewSerializer 按配置创建序列化器
+func NewSerializer(cfg config.CacheCodecConfig) (*Serializer, error) {
+	schema := EntrySchemaVersion
+	if cfg.SchemaVersion != "" {
+		// 附加配置的版本（如发布版本号），使每次发布都从空缓存开始
+		schema += "+" + cfg.SchemaVersion
+	}
+	if len(schema) > 255 {
+		return nil, fmt.Errorf("cache schema version too long: %s", schema)
+	}
+	s := &Serializer{
+		threshold:   cfg.CompressionThreshold,
+		schema:      schema,
+		codecs:      make(map[byte]Codec),
+		compressors: make(map[byte]compressor),
+	}
+	for _, c := range []Codec{jsonCodec{}, newMsgpackCodec(), protobufCodec{}} {
+		s.codecs[c.ID()] = c
+	}
+	zstdC, err := newZstdCompressor()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd compressor: %w", err)
+	}
+	for _, c := range []compressor{snappyCompressor{}, zstdC} {
+		s.compressors[c.ID()] = c
+	}
+
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		s.codec = s.codecs[codecJSON]
+	case "msgpack":
+		s.codec = s.codecs[codecMsgpack]
+	case "protobuf":
+		s.codec = s.codecs[codecProtobuf]
+	default:
+		return nil, fmt.Errorf("unsupported cache codec: %s", cfg.Format)
+	}
+	switch strings.ToLower(cfg.Compression) {
+	case "", "none":
+	case "snappy":
+		s.compressor = snappyCompressor{}
+	case "zstd":
+		s.compressor = zstdC
+	default:
+		return nil, fmt.Errorf("unsupported cache compression: %s", cfg.Compression)
+	}
+	return s, nil
+}
+
+// Codec 当前写入使用的编码名称
+func (s *Serializer) Codec() string {
+	return s.codec.Name()
+}
+
+// SchemaVersion 当前写入的结构版本
+func (s *Serializer) SchemaVersion() string {
+	return s.schema
+}
+
+// Encode 编码缓存值
+func (s *Serializer) Encode(v interface{}) ([]byte, error) {
+	c := s.codec
+	payload, err := c.Marshal(v)
+	if errors.Is(err, errNotProtoMessage) {
+		c = s.codecs[codecJSON]
+		payload, err = c.Marshal(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	compressionID := compressionNone
+	if s.compressor != nil && len(payload) >= s.threshold {
+		// 压缩后没有变小（如已压缩的内容）时保留原始负载
+		if compressed := s.compressor.Compress(payload); len(compressed) < len(payload) {
+			payload = compressed
+			compressionID = s.compressor.ID()
+		}
+	}
+
+	data := make([]byte, 0, envelopeHeader+len(s.schema)+len(payload))
+	data = append(data, envelopeMagic, envelopeVersion, c.ID(), compressionID, byte(len(s.schema)))
+	data = append(data, s.schema...)
+	return append(data, payload...), nil
+}
+
+// Decode 解码缓存值；过期或无法识别的条目返回 ErrCacheNotFound
+func (s *Serializer) Decode(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] != envelopeMagic {
+		// 无信封的旧 JSON 条目，结构版本未知
+		return s.stale("schema")
+	}
+	if len(data) < envelopeHeader || data[1] != envelopeVersion {
+		return s.stale("format")
+	}
+	schemaEnd := envelopeHeader + int(data[4])
+	if len(data) < schemaEnd {
+		return s.stale("format")
+	}
+	if string(data[envelopeHeader:schemaEnd]) != s.schema {
+		return s.stale("schema")
+	}
+	c, ok := s.codecs[data[2]]
+	if !ok {
+		return s.stale("format")
+	}
+
+	payload := data[schemaEnd:]
+	if compressionID := data[3]; compressionID != compressionNone {
+		decompressor, ok := s.compressors[compressionID]
+		if !ok {
+			return s.stale("format")
+		}
+		var err error
+		if payload, err = decompressor.Decompress(payload); err != nil {
+			return fmt.Errorf("failed to decompress value: %w", err)
+		}
+	}
+	return c.Unmarshal(payload, v)
+}
+
+// stale 记录过期条目并按未命中返回
+func (s *Serializer) stale(reason string) error {
+	metrics.CacheStaleEntriesTotal.WithLabelValues(reason).Inc()
+	return ErrCacheNotFound
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+)
+
+type codecTestEntry struct {
+	ID        string                 `json:"id"`
+	Name      string                 `json:"name"`
+	Count     int                    `json:"count"`
+	CreatedAt time.Time              `json:"created_at"`
+	Options   map[string]interface{} `json:"options"`
+}
+
+func newTestSerializer(t *testing.T, format, compression string, threshold int) *Serializer {
+	t.Helper()
+	s, err := NewSerializer(config.CacheCodecConfig{Format: format, Compression: compression, CompressionThreshold: threshold})
+	if err != nil {
+		t.Fatalf("创建序列化器失败: %v", err)
+	}
+	return s
+}
+
+func TestSerializerRoundTrip(t *testing.T) {
+	entry := codecTestEntry{
+		ID:        "fld_1",
+		Name:      strings.Repeat("名称", 200),
+		Count:     3,
+		CreatedAt: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		Options:   map[string]interface{}{"precision": "2"},
+	}
+	for _, format := range []string{"json", "msgpack", "protobuf"} {
+		for _, compression := range []string{"none", "snappy", "zstd"} {
+			s := newTestSerializer(t, format, compression, 64)
+			data, err := s.Encode(entry)
+			if err != nil {
+				t.Fatalf("%s/%s 编码失败: %v", format, compression, err)
+			}
+			var got codecTestEntry
+			if err := s.Decode(data, &got); err != nil {
+				t.Fatalf("%s/%s 解码失败: %v", format, compression, err)
+			}
+			if got.ID != entry.ID || got.Name != entry.Name || got.Count != entry.Count ||
+				!got.CreatedAt.Equal(entry.CreatedAt) || got.Options["precision"] != "2" {
+				t.Errorf("%s/%s 往返结果不一致: %+v", format, compression, got)
+			}
+		}
+	}
+}
+
+func TestSerializerProtobufMessage(t *testing.T) {
+	s := newTestSerializer(t, "protobuf", "none", 0)
+	data, err := s.Encode(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	if data[0] != envelopeMagic || data[2] != codecProtobuf {
+		t.Fatalf("proto.Message 应使用 protobuf 编码，得到头部 %v", data[:envelopeHeader])
+	}
+	got := &wrapperspb.StringValue{}
+	if err := s.Decode(data, got); err != nil || got.GetValue() != "hello" {
+		t.Fatalf("解码失败: %v, %v", err, got)
+	}
+}
+
+func TestSerializerCompressionThreshold(t *testing.T) {
+	s := newTestSerializer(t, "json", "zstd", 1024)
+
+	small := mustEncode(t, s, map[string]string{"a": "b"})
+	if small[3] != compressionNone {
+		t.Errorf("低于阈值不应压缩，得到头部 %v", small[:envelopeHeader])
+	}
+
+	large := mustEncode(t, s, map[string]string{"a": strings.Repeat("x", 4096)})
+	if large[0] != envelopeMagic || large[3] != compressionZstd {
+		t.Fatalf("超过阈值应压缩，得到头部 %v", large[:envelopeHeader])
+	}
+	if len(large) >= 4096 {
+		t.Errorf("压缩后应变小，得到 %d 字节", len(large))
+	}
+}
+
+func TestSerializerReadsEntriesWrittenWithOtherCodec(t *testing.T) {
+	written := mustEncode(t, newTestSerializer(t, "msgpack", "snappy", 0), codecTestEntry{ID: "fld_msgpack"})
+
+	var got codecTestEntry
+	if err := newTestSerializer(t, "json", "none", 0).Decode(written, &got); err != nil || got.ID != "fld_msgpack" {
+		t.Errorf("切换编码后应能读取旧条目: %v, %+v", err, got)
+	}
+}
+
+func TestSerializerTreatsStaleEntriesAsMiss(t *testing.T) {
+	s := newTestSerializer(t, "json", "none", 0)
+	legacy, _ := json.Marshal(codecTestEntry{ID: "fld_legacy"})
+	header := func(version, codecID, compressionID byte, schema string) []byte {
+		data := []byte{envelopeMagic, version, codecID, compressionID, byte(len(schema))}
+		return append(append(data, schema...), '{', '}')
+	}
+	previous, err := NewSerializer(config.CacheCodecConfig{SchemaVersion: "v1.4.0"})
+	if err != nil {
+		t.Fatalf("创建序列化器失败: %v", err)
+	}
+
+	for name, data := range map[string][]byte{
+		"无信封的旧条目": legacy,
+		"结构版本不一致": mustEncode(t, previous, codecTestEntry{ID: "fld_previous"}),
+		"未知信封版本":  header(envelopeVersion+1, codecJSON, compressionNone, EntrySchemaVersion),
+		"未知编码":    header(envelopeVersion, 99, compressionNone, EntrySchemaVersion),
+		"未知压缩":    header(envelopeVersion, codecJSON, 99, EntrySchemaVersion),
+		"头部截断":    {envelopeMagic, envelopeVersion},
+		"结构版本截断":  {envelopeMagic, envelopeVersion, codecJSON, compressionNone, 10, '1'},
+	} {
+		var got map[string]interface{}
+		if err := s.Decode(data, &got); !errors.Is(err, ErrCacheNotFound) {
+			t.Errorf("%s: 应按未命中处理，得到 %v", name, err)
+		}
+	}
+}
+
+func TestNewSerializerConfig(t *testing.T) {
+	if _, err := NewSerializer(config.CacheCodecConfig{Format: "xml"}); err == nil {
+		t.Error("未知编码应返回错误")
+	}
+	if _, err := NewSerializer(config.CacheCodecConfig{Compression: "lz4"}); err == nil {
+		t.Error("未知压缩方式应返回错误")
+	}
+	if _, err := NewSerializer(config.CacheCodecConfig{SchemaVersion: strings.Repeat("v", 300)}); err == nil {
+		t.Error("过长的结构版本应返回错误")
+	}
+	if got := newTestSerializer(t, "", "", 0).SchemaVersion(); got != EntrySchemaVersion {
+		t.Errorf("未配置时应使用内置结构版本，得到 %s", got)
+	}
+	release, _ := NewSerializer(config.CacheCodecConfig{SchemaVersion: "v1.5.0"})
+	if got := release.SchemaVersion(); got != EntrySchemaVersion+"+v1.5.0" {
+		t.Errorf("配置的版本应附加在内置版本后，得到 %s", got)
+	}
+}
+
+func mustEncode(t *testing.T, s *Serializer, v interface{}) []byte {
+	t.Helper()
+	data, err := s.Encode(v)
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	return data
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,11 +15,17 @@ import (
 
 // RedisClient Redis客户端结构
 type RedisClient struct {
-	client *redis.Client
+	client     *redis.Client
+	serializer *Serializer
 }
 
 // NewRedisClient 创建新的Redis客户端
 func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
+	serializer, err := NewSerializer(cfg.Codec)
+	if err != nil {
+		return nil, err
+	}
+
 	rdb := redis.NewClient(&redis.Options{
 		Addr:        cfg.GetRedisAddr(),
 		Password:    cfg.Password,
@@ -26,6 +33,7 @@ func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
 		PoolSize:    cfg.PoolSize,
 		DialTimeout: cfg.DialTimeout,
 	})
+	rdb.AddHook(newTracingHook(cfg.GetRedisAddr()))
 
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -38,14 +46,16 @@ func NewRedisClient(cfg config.RedisConfig) (*RedisClient, error) {
 	logger.Info("Redis connected successfully",
 		logger.String("addr", cfg.GetRedisAddr()),
 		logger.Int("db", cfg.DB),
+		logger.String("codec", serializer.Codec()),
+		logger.String("cache_schema_version", serializer.SchemaVersion()),
 	)
 
-	return &RedisClient{client: rdb}, nil
+	return &RedisClient{client: rdb, serializer: serializer}, nil
 }
 
 // Set 设置缓存
 func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	data, err := json.Marshal(value)
+	data, err := r.serializer.Encode(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
@@ -55,15 +65,18 @@ func (r *RedisClient) Set(ctx context.Context, key string, value interface{}, ex
 
 // Get 获取缓存
 func (r *RedisClient) Get(ctx context.Context, key string, dest interface{}) error {
-	data, err := r.client.Get(ctx, key).Result()
+	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return ErrCacheNotFound
 		}
-		return fmt.Errorf("failed to get cache: %w", err)
+		return fmt.Errorf("failed to get cache: %w: %w", ErrCacheUnavailable, err)
 	}
 
-	if err := json.Unmarshal([]byte(data), dest); err != nil {
+	if err := r.serializer.Decode(data, dest); err != nil {
+		if errors.Is(err, ErrCacheNotFound) {
+			return err
+		}
 		return fmt.Errorf("failed to unmarshal value: %w", err)
 	}
 
@@ -125,6 +138,47 @@ func (r *RedisClient) SetNX(ctx context.Context, key string, value interface{},
 	return r.client.SetNX(ctx, key, data, expiration).Result()
 }
 
+// CompareAndSet 比较并设置：在 WATCH 事务中读取键到 dest，再写入 update 返回的新值（新值为 nil 时删除键）
+// 键不存在时返回 ErrCacheNotFound，update 返回错误时不写入，读取后键被并发修改时返回 ErrCacheConflict
+func (r *RedisClient) CompareAndSet(ctx context.Context, key string, dest interface{}, update func() (interface{}, time.Duration, error)) error {
+	err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			return ErrCacheNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get cache: %w: %w", ErrCacheUnavailable, err)
+		}
+		if err := r.serializer.Decode(data, dest); err != nil {
+			return fmt.Errorf("failed to unmarshal value: %w", err)
+		}
+
+		value, expiration, err := update()
+		if err != nil {
+			return err
+		}
+		var encoded []byte
+		if value != nil {
+			if encoded, err = r.serializer.Encode(value); err != nil {
+				return fmt.Errorf("failed to marshal value: %w", err)
+			}
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if value == nil {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.Set(ctx, key, encoded, expiration)
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, redis.TxFailedErr) {
+		return ErrCacheConflict
+	}
+	return err
+}
+
 // HSet 设置哈希字段
 func (r *RedisClient) HSet(ctx context.Context, key string, field string, value interface{}) error {
 	data, err := json.Marshal(value)
@@ -280,6 +334,10 @@ var _ CacheService = (*RedisClient)(nil)
 // 缓存错误
 var (
 	ErrCacheNotFound = fmt.Errorf("cache not found")
+	// ErrCacheUnavailable 缓存后端不可用（连接失败、超时等），区别于未命中
+	ErrCacheUnavailable = fmt.Errorf("cache unavailable")
+	// ErrCacheConflict 比较并设置期间键被并发修改
+	ErrCacheConflict = fmt.Errorf("cache key modified concurrently")
 )
 
 // 常用缓存键前缀
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+// tracingHook go-redis 链路追踪钩子 ✨
+// 为每条命令/管道创建客户端跨度，仅在已有链路时记录
+type tracingHook struct {
+	addr string
+}
+
+// newTracingHook 创建 Redis 追踪钩子
+func newTracingHook(addr string) *tracingHook {
+	return &tracingHook{addr: addr}
+}
+
+// DialHook 连接建立不单独追踪
+func (h *tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 单条命令
+func (h *tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !tracing.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmd)
+		}
+
+		ctx, span := h.start(ctx, "cache."+strings.ToLower(cmd.Name()), cmd.Name())
+		defer span.End()
+
+		err := next(ctx, cmd)
+		if err != nil && err != redis.Nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+// ProcessPipelineHook 管道/事务
+func (h *tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !tracing.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmds)
+		}
+
+		ctx, span := h.start(ctx, "cache.pipeline", "PIPELINE")
+		defer span.End()
+		span.SetAttributes(tracing.Int("db.operation.batch.size", len(cmds)))
+
+		err := next(ctx, cmds)
+		if err != nil && err != redis.Nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+}
+
+func (h *tracingHook) start(ctx context.Context, name, operation string) (context.Context, *tracing.Span) {
+	return tracing.Start(ctx, name,
+		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithAttributes(
+			tracing.String("db.system", "redis"),
+			tracing.String("db.operation.name", strings.ToUpper(operation)),
+			tracing.String("server.address", h.addr),
+		))
+}
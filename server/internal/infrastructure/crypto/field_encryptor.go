@@ -0,0 +1,239 @@
+package crypto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// 密文格式：enc:v1:<数据密钥版本>:<base64(nonce|ciphertext)>
+const ciphertextPrefix = "enc:v1:"
+
+// FieldEncryptor 字段值信封加密器
+// 每个工作空间一把数据密钥（DEK），DEK 由 KMS 包裹后存储在 space_data_key 表；
+// 单元格值以 JSON 序列化后用 DEK 做 AES-256-GCM 加密，字段ID作为附加认证数据，
+// 防止密文在字段之间被挪用
+type FieldEncryptor struct {
+	db  *gorm.DB
+	kms KeyManager
+
+	// spaceID:version -> 明文DEK
+	keys sync.Map
+	// spaceID -> 当前版本
+	activeVersions sync.Map
+	// baseID -> spaceID
+	baseSpaces sync.Map
+	createMu   sync.Mutex
+}
+
+// NewFieldEncryptor 创建字段加密器
+func NewFieldEncryptor(db *gorm.DB, kms KeyManager) *FieldEncryptor {
+	return &FieldEncryptor{db: db, kms: kms}
+}
+
+// IsCiphertext 判断存储值是否为密文
+func IsCiphertext(value interface{}) bool {
+	s, ok := value.(string)
+	return ok && strings.HasPrefix(s, ciphertextPrefix)
+}
+
+// Encrypt 加密单元格值（nil 保持为 nil）
+func (e *FieldEncryptor) Encrypt(ctx context.Context, baseID, fieldID string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	spaceID, err := e.spaceForBase(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	version, dek, err := e.activeKey(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("序列化单元格值失败: %w", err)
+	}
+	sealed, err := seal(dek, plaintext, []byte(fieldID))
+	if err != nil {
+		return nil, fmt.Errorf("加密单元格值失败: %w", err)
+	}
+
+	return fmt.Sprintf("%s%d:%s", ciphertextPrefix, version, base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// Decrypt 解密单元格值
+// 非密文（开启加密前写入的历史数据）原样返回
+func (e *FieldEncryptor) Decrypt(ctx context.Context, baseID, fieldID string, stored interface{}) (interface{}, error) {
+	if !IsCiphertext(stored) {
+		return stored, nil
+	}
+
+	version, sealed, err := parseCiphertext(stored.(string))
+	if err != nil {
+		return nil, err
+	}
+	spaceID, err := e.spaceForBase(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := e.key(ctx, spaceID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := open(dek, sealed, []byte(fieldID))
+	if err != nil {
+		return nil, fmt.Errorf("解密单元格值失败: %w", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, fmt.Errorf("反序列化单元格值失败: %w", err)
+	}
+	return value, nil
+}
+
+// parseCiphertext 解析密文格式
+func parseCiphertext(s string) (int, []byte, error) {
+	parts := strings.SplitN(strings.TrimPrefix(s, ciphertextPrefix), ":", 2)
+	if len(parts) != 2 {
+		return 0, nil, fmt.Errorf("密文格式无效")
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, nil, fmt.Errorf("密文密钥版本无效: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, fmt.Errorf("密文编码无效: %w", err)
+	}
+	return version, sealed, nil
+}
+
+// activeKey 获取工作空间当前数据密钥（不存在时生成）
+func (e *FieldEncryptor) activeKey(ctx context.Context, spaceID string) (int, []byte, error) {
+	if v, ok := e.activeVersions.Load(spaceID); ok {
+		dek, err := e.key(ctx, spaceID, v.(int))
+		return v.(int), dek, err
+	}
+
+	var record models.SpaceDataKey
+	err := e.db.WithContext(ctx).
+		Where("space_id = ?", spaceID).
+		Order("version DESC").
+		Take(&record).Error
+	if err == gorm.ErrRecordNotFound {
+		return e.createKey(ctx, spaceID)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("查询工作空间数据密钥失败: %w", err)
+	}
+
+	dek, err := e.unwrap(ctx, &record)
+	if err != nil {
+		return 0, nil, err
+	}
+	e.activeVersions.Store(spaceID, record.Version)
+	return record.Version, dek, nil
+}
+
+// key 获取指定版本的数据密钥
+func (e *FieldEncryptor) key(ctx context.Context, spaceID string, version int) ([]byte, error) {
+	cacheKey := fmt.Sprintf("%s:%d", spaceID, version)
+	if v, ok := e.keys.Load(cacheKey); ok {
+		return v.([]byte), nil
+	}
+
+	var record models.SpaceDataKey
+	if err := e.db.WithContext(ctx).
+		Where("space_id = ? AND version = ?", spaceID, version).
+		Take(&record).Error; err != nil {
+		return nil, fmt.Errorf("查询工作空间数据密钥失败 (version=%d): %w", version, err)
+	}
+	return e.unwrap(ctx, &record)
+}
+
+// createKey 生成并保存首个数据密钥（并发创建时以先写入者为准）
+func (e *FieldEncryptor) createKey(ctx context.Context, spaceID string) (int, []byte, error) {
+	e.createMu.Lock()
+	defer e.createMu.Unlock()
+
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return 0, nil, err
+	}
+	wrapped, err := e.kms.WrapKey(ctx, spaceID, dek)
+	if err != nil {
+		return 0, nil, fmt.Errorf("KMS包裹数据密钥失败: %w", err)
+	}
+
+	record := models.SpaceDataKey{
+		SpaceID:     spaceID,
+		Version:     1,
+		KeyID:       e.kms.KeyID(),
+		WrappedKey:  base64.StdEncoding.EncodeToString(wrapped),
+		CreatedTime: time.Now(),
+	}
+	if err := e.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&record).Error; err != nil {
+		return 0, nil, fmt.Errorf("保存工作空间数据密钥失败: %w", err)
+	}
+
+	// 重新读取，确保使用已持久化的密钥
+	dek, err = e.key(ctx, spaceID, 1)
+	if err != nil {
+		return 0, nil, err
+	}
+	e.activeVersions.Store(spaceID, 1)
+	return 1, dek, nil
+}
+
+// unwrap 解包并缓存数据密钥
+func (e *FieldEncryptor) unwrap(ctx context.Context, record *models.SpaceDataKey) ([]byte, error) {
+	wrapped, err := base64.StdEncoding.DecodeString(record.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("数据密钥编码无效: %w", err)
+	}
+	dek, err := e.kms.UnwrapKey(ctx, record.SpaceID, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("KMS解包数据密钥失败: %w", err)
+	}
+	e.keys.Store(fmt.Sprintf("%s:%d", record.SpaceID, record.Version), dek)
+	return dek, nil
+}
+
+// spaceForBase 解析 Base 所属工作空间
+func (e *FieldEncryptor) spaceForBase(ctx context.Context, baseID string) (string, error) {
+	if v, ok := e.baseSpaces.Load(baseID); ok {
+		return v.(string), nil
+	}
+
+	var row struct {
+		SpaceID string `gorm:"column:space_id"`
+	}
+	if err := e.db.WithContext(ctx).
+		Table("base").
+		Select("space_id").
+		Where("id = ?", baseID).
+		Take(&row).Error; err != nil {
+		return "", fmt.Errorf("解析Base所属空间失败 (base=%s): %w", baseID, err)
+	}
+
+	e.baseSpaces.Store(baseID, row.SpaceID)
+	return row.SpaceID, nil
+}
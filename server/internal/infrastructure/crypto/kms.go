@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+)
+
+// KeyManager 密钥管理服务（KMS）
+// 负责包裹/解包各工作空间的数据密钥，主密钥本身不离开 KMS
+type KeyManager interface {
+	// KeyID 当前主密钥标识
+	KeyID() string
+	// WrapKey 使用工作空间的KEK包裹数据密钥
+	WrapKey(ctx context.Context, spaceID string, dataKey []byte) ([]byte, error)
+	// UnwrapKey 解包数据密钥
+	UnwrapKey(ctx context.Context, spaceID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyManager 本地KMS实现
+// 每个工作空间的KEK = HMAC-SHA256(主密钥, spaceID)，适用于单机/自托管部署
+type LocalKeyManager struct {
+	keyID     string
+	masterKey []byte
+}
+
+// NewLocalKeyManager 创建本地KMS（masterKey 为 base64 编码的 32 字节密钥）
+func NewLocalKeyManager(keyID, masterKey string) (*LocalKeyManager, error) {
+	key, err := base64.StdEncoding.DecodeString(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("主密钥不是有效的base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("主密钥长度必须为32字节，实际为 %d", len(key))
+	}
+	return &LocalKeyManager{keyID: keyID, masterKey: key}, nil
+}
+
+// KeyID 当前主密钥标识
+func (m *LocalKeyManager) KeyID() string {
+	return m.keyID
+}
+
+// WrapKey 包裹数据密钥
+func (m *LocalKeyManager) WrapKey(ctx context.Context, spaceID string, dataKey []byte) ([]byte, error) {
+	return seal(m.kek(spaceID), dataKey, []byte(spaceID))
+}
+
+// UnwrapKey 解包数据密钥
+func (m *LocalKeyManager) UnwrapKey(ctx context.Context, spaceID string, wrapped []byte) ([]byte, error) {
+	return open(m.kek(spaceID), wrapped, []byte(spaceID))
+}
+
+// kek 派生工作空间级密钥加密密钥
+func (m *LocalKeyManager) kek(spaceID string) []byte {
+	mac := hmac.New(sha256.New, m.masterKey)
+	mac.Write([]byte("luckdb-space-kek:" + spaceID))
+	return mac.Sum(nil)
+}
+
+// seal AES-256-GCM 加密，输出 nonce|ciphertext
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open AES-256-GCM 解密
+func open(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("密文长度无效")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
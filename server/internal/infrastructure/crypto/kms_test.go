@@ -0,0 +1,77 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+)
+
+func testMasterKey() string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+}
+
+func TestLocalKeyManagerWrapUnwrap(t *testing.T) {
+	kms, err := NewLocalKeyManager("test", testMasterKey())
+	if err != nil {
+		t.Fatalf("NewLocalKeyManager: %v", err)
+	}
+
+	dek := bytes.Repeat([]byte{1}, 32)
+	wrapped, err := kms.WrapKey(context.Background(), "spc_a", dek)
+	if err != nil {
+		t.Fatalf("WrapKey: %v", err)
+	}
+
+	got, err := kms.UnwrapKey(context.Background(), "spc_a", wrapped)
+	if err != nil {
+		t.Fatalf("UnwrapKey: %v", err)
+	}
+	if !bytes.Equal(got, dek) {
+		t.Fatalf("unwrapped key mismatch")
+	}
+
+	// 其他工作空间的KEK不能解包
+	if _, err := kms.UnwrapKey(context.Background(), "spc_b", wrapped); err == nil {
+		t.Fatalf("expected unwrap with another space to fail")
+	}
+}
+
+func TestLocalKeyManagerRejectsShortKey(t *testing.T) {
+	if _, err := NewLocalKeyManager("test", base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Fatalf("expected error for short master key")
+	}
+}
+
+func TestSealBindsAssociatedData(t *testing.T) {
+	key := bytes.Repeat([]byte{3}, 32)
+	sealed, err := seal(key, []byte(`"secret"`), []byte("fld_a"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	plaintext, err := open(key, sealed, []byte("fld_a"))
+	if err != nil || string(plaintext) != `"secret"` {
+		t.Fatalf("open: %q %v", plaintext, err)
+	}
+	if _, err := open(key, sealed, []byte("fld_b")); err == nil {
+		t.Fatalf("expected open with another field id to fail")
+	}
+}
+
+func TestParseCiphertext(t *testing.T) {
+	if !IsCiphertext("enc:v1:2:AAAA") {
+		t.Fatalf("expected ciphertext")
+	}
+	if IsCiphertext("plain") || IsCiphertext(42) {
+		t.Fatalf("expected non-ciphertext")
+	}
+
+	version, sealed, err := parseCiphertext("enc:v1:2:AAAA")
+	if err != nil || version != 2 || len(sealed) != 3 {
+		t.Fatalf("parseCiphertext: %d %v %v", version, sealed, err)
+	}
+	if _, _, err := parseCiphertext("enc:v1:x"); err == nil {
+		t.Fatalf("expected error for malformed ciphertext")
+	}
+}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// AdvisoryLocker 按名称加锁 ✨
+// PostgreSQL 使用会话级 advisory lock（在单独的连接上持有，多实例之间互斥），
+// 其他数据库只在进程内互斥。同一实例的请求先在进程内排队，避免多个连接同时等待同一把锁
+type AdvisoryLocker struct {
+	db *gorm.DB
+
+	mu    sync.Mutex
+	local map[string]*localLock
+}
+
+// localLock 进程内的锁，没有持有者与等待者时删除
+type localLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewAdvisoryLocker 创建按名称加锁的锁管理器
+func NewAdvisoryLocker(db *gorm.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db, local: make(map[string]*localLock)}
+}
+
+// Lock 获取名为 key 的锁，返回释放函数；等待数据库锁期间 ctx 取消时返回错误
+func (l *AdvisoryLocker) Lock(ctx context.Context, key string) (func(), error) {
+	unlockLocal := l.lockLocal(key)
+	if l.db == nil || l.db.Dialector.Name() != "postgres" {
+		return unlockLocal, nil
+	}
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		unlockLocal()
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		unlockLocal()
+		return nil, fmt.Errorf("获取锁连接失败: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock(hashtext($1))", key); err != nil {
+		conn.Close()
+		unlockLocal()
+		return nil, fmt.Errorf("获取锁 %s 失败: %w", key, err)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			releaseAdvisoryLock(conn, key)
+			unlockLocal()
+		})
+	}, nil
+}
+
+// releaseAdvisoryLock 释放 advisory lock 并归还连接；释放失败时丢弃连接（会话结束时锁随之释放）
+func releaseAdvisoryLock(conn *sql.Conn, key string) {
+	if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", key); err != nil {
+		logger.Warn("释放 advisory lock 失败，丢弃连接", logger.String("key", key), logger.ErrorField(err))
+		conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	}
+	conn.Close()
+}
+
+func (l *AdvisoryLocker) lockLocal(key string) func() {
+	l.mu.Lock()
+	lock := l.local[key]
+	if lock == nil {
+		lock = &localLock{}
+		l.local[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.local, key)
+		}
+		l.mu.Unlock()
+	}
+}
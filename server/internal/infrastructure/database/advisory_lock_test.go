@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAdvisoryLockerSerializesAndCleansUp(t *testing.T) {
+	locker := NewAdvisoryLocker(nil)
+	ctx := context.Background()
+
+	unlock, err := locker.Lock(ctx, "schema:tbl1")
+	if err != nil {
+		t.Fatalf("加锁失败: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		second, _ := locker.Lock(ctx, "schema:tbl1")
+		acquired <- second
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("锁被持有时不应再次获得")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// 其他名称的锁互不影响
+	other, err := locker.Lock(ctx, "schema:tbl2")
+	if err != nil {
+		t.Fatalf("加锁失败: %v", err)
+	}
+	other()
+
+	unlock()
+	second := <-acquired
+	second()
+
+	if n := len(locker.local); n != 0 {
+		t.Fatalf("释放后不应保留锁，剩余 %d 个", n)
+	}
+}
@@ -11,12 +11,16 @@ import (
 	"gorm.io/gorm/schema"
 
 	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
 	appLogger "github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
 
 // Connection 数据库连接结构
 type Connection struct {
 	DB *gorm.DB
+
+	// QueryStats 按动态表聚合的查询统计（未启用慢查询记录时为 nil）
+	QueryStats *QueryStatistics
 }
 
 // NewConnection 创建新的数据库连接
@@ -39,11 +43,19 @@ func NewConnection(cfg config.DatabaseConfig) (*Connection, error) {
 		logLevel = logger.Info
 	}
 
+	// 启用慢查询插件时由插件记录慢查询，SQL日志不再重复输出
+	slowThreshold := 200 * time.Millisecond
+	if cfg.SlowQuery.Enabled {
+		slowThreshold = 0
+	} else if cfg.SlowQuery.Threshold > 0 {
+		slowThreshold = cfg.SlowQuery.Threshold
+	}
+
 	// 创建自定义SQL日志记录器
 	sqlLogger := NewSQLLogger(
 		appLogger.Logger,
 		logger.Config{
-			SlowThreshold:             200 * time.Millisecond, // 降低慢查询阈值
+			SlowThreshold:             slowThreshold,
 			LogLevel:                  logLevel,
 			IgnoreRecordNotFoundError: true,
 			Colorful:                  true, // 启用彩色输出
@@ -67,6 +79,35 @@ func NewConnection(cfg config.DatabaseConfig) (*Connection, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// 注册链路追踪插件（未启用追踪时回调直接返回）✨
+	if err := db.Use(NewTracingPlugin("postgresql")); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+	if err := db.Use(NewMetricsPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register metrics plugin: %w", err)
+	}
+	var queryStats *QueryStatistics
+	if cfg.SlowQuery.Enabled {
+		queryStats = NewQueryStatistics(cfg.SlowQuery.MaxEntries, cfg.SlowQuery.MaxTables)
+		if err := db.Use(NewSlowQueryPlugin(cfg.SlowQuery, queryStats)); err != nil {
+			return nil, fmt.Errorf("failed to register slow query plugin: %w", err)
+		}
+	}
+	if cfg.QueryBudget.Enabled {
+		if err := db.Use(NewQueryBudgetPlugin(cfg.QueryBudget)); err != nil {
+			return nil, fmt.Errorf("failed to register query budget plugin: %w", err)
+		}
+	}
+	if cfg.FaultInjection.Enabled {
+		if err := db.Use(NewFaultInjectionPlugin(faultinject.New("database", cfg.FaultInjection))); err != nil {
+			return nil, fmt.Errorf("failed to register fault injection plugin: %w", err)
+		}
+		appLogger.Warn("⚠️ 数据库故障注入已启用，仅可用于测试与预发环境",
+			appLogger.Float64("error_rate", cfg.FaultInjection.ErrorRate),
+			appLogger.Duration("latency", cfg.FaultInjection.Latency),
+		)
+	}
+
 	// 获取底层sql.DB实例
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -90,7 +131,7 @@ func NewConnection(cfg config.DatabaseConfig) (*Connection, error) {
 		appLogger.String("database", cfg.Name),
 	)
 
-	return &Connection{DB: db}, nil
+	return &Connection{DB: db, QueryStats: queryStats}, nil
 }
 
 // Close 关闭数据库连接
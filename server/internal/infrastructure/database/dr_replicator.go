@@ -0,0 +1,497 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// drTimeColumns 元数据表增量复制依据的时间列（按优先级）
+var drTimeColumns = []string{"updated_time", "updated_at", "created_time", "created_at"}
+
+// DRSnapshotReport 一次灾备快照复制的统计
+type DRSnapshotReport struct {
+	MetadataTables int   `json:"metadataTables"`
+	DataTables     int   `json:"dataTables"`
+	RowsCopied     int64 `json:"rowsCopied"`
+	RowsRemoved    int64 `json:"rowsRemoved"`
+}
+
+// DREventBatch 一批已复制的变更事件
+type DREventBatch struct {
+	Events   int
+	LastSeq  int64
+	LastTime time.Time
+}
+
+// DRAttachment 待复制到备区域的附件文件
+type DRAttachment struct {
+	ID          string
+	Paths       []string // 原文件与缩略图在存储中的路径
+	CreatedTime time.Time
+}
+
+// drDataTable 待复制的 Base 数据表
+type drDataTable struct {
+	spaceTable
+	SpaceID     string    `gorm:"column:space_id"`
+	CreatedTime time.Time `gorm:"column:created_time"`
+}
+
+// DRReplicator 跨区域灾备复制器 ✨
+//
+// 将主区域复制到备区域集群：
+//   - 快照：主库当前 Schema 中的元数据表按主键增量（存在更新/创建时间列时）或全量复制，
+//     Base 数据表沿用跨区域迁移的结构同步、按修改时间增量复制与删除对账
+//   - 变更事件：change_event 按 seq 顺序复制
+//   - 附件：列出待复制的附件文件，由调用方在主、备存储之间复制
+//
+// 备区域缺少的元数据表与列按主库结构创建，主键一并复制
+type DRReplicator struct {
+	primary   *RegionCluster
+	secondary *RegionCluster
+	router    *RegionRouter // 启用数据驻留时只复制位于主区域的数据表
+	exclude   map[string]bool
+	batchSize int
+}
+
+// NewDRReplicator 创建灾备复制器，excludeTables 为不随快照复制的元数据表
+func NewDRReplicator(primary, secondary *RegionCluster, router *RegionRouter, batchSize int, excludeTables ...string) *DRReplicator {
+	if batchSize <= 0 {
+		batchSize = regionCopyBatchSize
+	}
+	exclude := map[string]bool{
+		"schema_migrations":               true,
+		models.ChangeEvent{}.TableName():  true,
+		models.DRCheckpoint{}.TableName(): true,
+		models.DRPromotion{}.TableName():  true,
+	}
+	for _, name := range excludeTables {
+		exclude[name] = true
+	}
+	return &DRReplicator{
+		primary:   primary,
+		secondary: secondary,
+		router:    router,
+		exclude:   exclude,
+		batchSize: batchSize,
+	}
+}
+
+// Ping 检查主区域是否可达
+func (r *DRReplicator) Ping(ctx context.Context) error {
+	sqlDB, err := r.primary.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// ReplicateSnapshot 复制元数据表与 Base 数据表；since 为上次快照的开始时间，零值表示首次全量复制
+func (r *DRReplicator) ReplicateSnapshot(ctx context.Context, since time.Time) (*DRSnapshotReport, error) {
+	report := &DRSnapshotReport{}
+
+	tables, err := r.metadataTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range tables {
+		if err := r.copyMetadataTable(ctx, name, since, report); err != nil {
+			return nil, fmt.Errorf("复制元数据表 %s 失败: %w", name, err)
+		}
+		report.MetadataTables++
+	}
+
+	dataTables, err := r.dataTables(ctx, r.primary.DB)
+	if err != nil {
+		return nil, err
+	}
+	regions := make(map[string]string)
+	createdSchemas := make(map[string]bool)
+	for _, t := range dataTables {
+		if r.router != nil {
+			region, ok := regions[t.SpaceID]
+			if !ok {
+				if region, err = r.router.RegionForSpace(ctx, t.SpaceID); err != nil {
+					return nil, err
+				}
+				regions[t.SpaceID] = region
+			}
+			if region != r.primary.Name {
+				continue
+			}
+		}
+
+		tctx := WithTenant(ctx, t.SpaceID)
+		if !createdSchemas[t.BaseID] {
+			if err := r.secondary.Provider.CreateSchema(tctx, t.BaseID); err != nil {
+				return nil, err
+			}
+			createdSchemas[t.BaseID] = true
+		}
+		if err := copyTableStructure(tctx, r.primary, r.secondary, t.spaceTable); err != nil {
+			return nil, err
+		}
+		var n int64
+		if since.IsZero() || !t.CreatedTime.Before(since) {
+			n, err = copyTableAll(tctx, r.primary, r.secondary, t.spaceTable)
+		} else {
+			n, err = copyTableSince(tctx, r.primary, r.secondary, t.spaceTable, since)
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.RowsCopied += n
+		removed, err := reconcileTableDeletes(tctx, r.primary, r.secondary, t.spaceTable)
+		if err != nil {
+			return nil, err
+		}
+		report.RowsRemoved += removed
+		report.DataTables++
+	}
+	return report, nil
+}
+
+// ReplicateEvents 复制 seq 大于 afterSeq 的变更事件（最多 limit 条）
+func (r *DRReplicator) ReplicateEvents(ctx context.Context, afterSeq int64, limit int) (*DREventBatch, error) {
+	table := models.ChangeEvent{}.TableName()
+	var rows []map[string]interface{}
+	if err := r.primary.DB.WithContext(ctx).
+		Table(table).
+		Where("seq > ?", afterSeq).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("读取变更事件失败: %w", err)
+	}
+	batch := &DREventBatch{Events: len(rows), LastSeq: afterSeq}
+	if len(rows) == 0 {
+		return batch, nil
+	}
+	if err := upsertRowsOn(ctx, r.secondary.DB, table, "id", rows); err != nil {
+		return nil, err
+	}
+	last := rows[len(rows)-1]
+	batch.LastSeq = toInt64(last["seq"])
+	batch.LastTime, _ = last["created_time"].(time.Time)
+	return batch, nil
+}
+
+// PendingEvents 统计尚未复制的变更事件及其中最早的创建时间
+func (r *DRReplicator) PendingEvents(ctx context.Context, afterSeq int64) (int64, *time.Time, error) {
+	return r.pending(r.primary.DB.WithContext(ctx).
+		Model(&models.ChangeEvent{}).
+		Where("seq > ?", afterSeq))
+}
+
+// AttachmentsAfter 按上传时间顺序列出游标之后的附件
+func (r *DRReplicator) AttachmentsAfter(ctx context.Context, after time.Time, afterID string, limit int) ([]DRAttachment, error) {
+	var list []models.Attachment
+	if err := r.attachmentsAfter(ctx, after, afterID).
+		Order("created_time ASC, id ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("读取附件失败: %w", err)
+	}
+	result := make([]DRAttachment, 0, len(list))
+	for _, a := range list {
+		item := DRAttachment{ID: a.ID, Paths: []string{a.Path}, CreatedTime: a.CreatedTime}
+		for _, thumb := range []*string{a.SmallThumbnail, a.LargeThumbnail} {
+			if thumb != nil && *thumb != "" && *thumb != a.Path {
+				item.Paths = append(item.Paths, *thumb)
+			}
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// PendingAttachments 统计游标之后尚未复制的附件及其中最早的上传时间
+func (r *DRReplicator) PendingAttachments(ctx context.Context, after time.Time, afterID string) (int64, *time.Time, error) {
+	return r.pending(r.attachmentsAfter(ctx, after, afterID))
+}
+
+func (r *DRReplicator) attachmentsAfter(ctx context.Context, after time.Time, afterID string) *gorm.DB {
+	return r.primary.DB.WithContext(ctx).
+		Model(&models.Attachment{}).
+		Where("created_time > ? OR (created_time = ? AND id > ?)", after, after, afterID)
+}
+
+func (r *DRReplicator) pending(query *gorm.DB) (int64, *time.Time, error) {
+	var result struct {
+		Count  int64      `gorm:"column:cnt"`
+		Oldest *time.Time `gorm:"column:oldest"`
+	}
+	if err := query.Select("COUNT(*) AS cnt, MIN(created_time) AS oldest").Scan(&result).Error; err != nil {
+		return 0, nil, fmt.Errorf("统计未复制数据失败: %w", err)
+	}
+	return result.Count, result.Oldest, nil
+}
+
+// PrepareSecondary 提升前整理备区域：将各数据表的自增序列推进到当前最大值
+// 数据表列表读取备区域已复制的元数据，主区域不可达时同样可用
+func (r *DRReplicator) PrepareSecondary(ctx context.Context) error {
+	tables, err := r.dataTables(ctx, r.secondary.DB)
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		tctx := WithTenant(ctx, t.SpaceID)
+		var exists bool
+		name := r.secondary.Provider.GenerateTableName(t.BaseID, t.TableID)
+		if err := r.secondary.DB.WithContext(tctx).Raw("SELECT to_regclass(?) IS NOT NULL", name).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := syncTableSequence(tctx, r.secondary, t.spaceTable); err != nil {
+			return fmt.Errorf("同步数据表 %s 序列失败: %w", t.TableID, err)
+		}
+	}
+	return nil
+}
+
+// dataTables 列出所有 Base 数据表
+func (r *DRReplicator) dataTables(ctx context.Context, db *gorm.DB) ([]drDataTable, error) {
+	var tables []drDataTable
+	err := db.WithContext(ctx).
+		Table("table_meta").
+		Select("table_meta.id, table_meta.base_id, base.space_id, table_meta.created_time").
+		Joins("JOIN base ON base.id = table_meta.base_id").
+		Where("table_meta.deleted_time IS NULL").
+		Order("table_meta.base_id, table_meta.id").
+		Find(&tables).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询数据表失败: %w", err)
+	}
+	return tables, nil
+}
+
+// metadataTables 列出主库当前 Schema 中需要复制的元数据表（分区表只复制父表）
+func (r *DRReplicator) metadataTables(ctx context.Context) ([]string, error) {
+	var names []string
+	err := r.primary.DB.WithContext(ctx).Raw(`
+		SELECT c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		ORDER BY c.relname`).
+		Scan(&names).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询元数据表失败: %w", err)
+	}
+	tables := make([]string, 0, len(names))
+	for _, name := range names {
+		if !r.exclude[name] {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// drColumn 元数据表的列
+type drColumn struct {
+	Name    string `gorm:"column:attname"`
+	Type    string `gorm:"column:coltype"`
+	Primary bool   `gorm:"column:is_primary"`
+}
+
+// copyMetadataTable 复制一张元数据表
+// 单列主键的表按主键分批写入（有时间列时只复制 since 之后的变更）并对账删除，其他表在事务中整表替换
+func (r *DRReplicator) copyMetadataTable(ctx context.Context, name string, since time.Time, report *DRSnapshotReport) error {
+	columns, err := r.metadataStructure(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	present := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		present[col.Name] = true
+		if col.Primary {
+			keys = append(keys, col.Name)
+		}
+	}
+	if len(keys) != 1 {
+		n, err := r.replaceTable(ctx, name)
+		report.RowsCopied += n
+		return err
+	}
+	key := keys[0]
+
+	timeColumn := ""
+	if !since.IsZero() {
+		for _, col := range drTimeColumns {
+			if present[col] {
+				timeColumn = col
+				break
+			}
+		}
+	}
+
+	var last interface{}
+	for {
+		query := r.primary.DB.WithContext(ctx).Table(name)
+		if timeColumn != "" {
+			query = query.Where(fmt.Sprintf(`"%s" >= ?`, timeColumn), since)
+		}
+		if last != nil {
+			query = query.Where(fmt.Sprintf(`"%s" > ?`, key), last)
+		}
+		var rows []map[string]interface{}
+		if err := query.Order(fmt.Sprintf(`"%s" ASC`, key)).Limit(r.batchSize).Find(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		if err := upsertRowsOn(ctx, r.secondary.DB, name, key, rows); err != nil {
+			return err
+		}
+		report.RowsCopied += int64(len(rows))
+		last = rows[len(rows)-1][key]
+	}
+
+	removed, err := r.reconcileMetadataDeletes(ctx, name, key)
+	report.RowsRemoved += removed
+	return err
+}
+
+// metadataStructure 读取主库表结构，并在备区域创建缺少的表与列
+func (r *DRReplicator) metadataStructure(ctx context.Context, name string) ([]drColumn, error) {
+	var columns []drColumn
+	err := r.primary.DB.WithContext(ctx).Raw(`
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod) AS coltype,
+			EXISTS (
+				SELECT 1 FROM pg_index i
+				WHERE i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+			) AS is_primary
+		FROM pg_attribute a
+		WHERE a.attrelid = to_regclass(?) AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, name).
+		Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("读取表结构失败: %w", err)
+	}
+
+	var exists bool
+	if err := r.secondary.DB.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", name).Scan(&exists).Error; err != nil {
+		return nil, err
+	}
+	if !exists {
+		defs := make([]string, 0, len(columns)+1)
+		var keys []string
+		for _, col := range columns {
+			defs = append(defs, fmt.Sprintf(`"%s" %s`, col.Name, col.Type))
+			if col.Primary {
+				keys = append(keys, fmt.Sprintf(`"%s"`, col.Name))
+			}
+		}
+		if len(keys) > 0 {
+			defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(keys, ", ")))
+		}
+		create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (%s)`, name, strings.Join(defs, ", "))
+		if err := r.secondary.DB.WithContext(ctx).Exec(create).Error; err != nil {
+			return nil, fmt.Errorf("在备区域创建表失败: %w", err)
+		}
+		return columns, nil
+	}
+
+	for _, col := range columns {
+		alter := fmt.Sprintf(`ALTER TABLE "%s" ADD COLUMN IF NOT EXISTS "%s" %s`, name, col.Name, col.Type)
+		if err := r.secondary.DB.WithContext(ctx).Exec(alter).Error; err != nil {
+			return nil, fmt.Errorf("同步列 %s 失败: %w", col.Name, err)
+		}
+	}
+	return columns, nil
+}
+
+// replaceTable 在备区域事务内整表替换（用于没有单列主键的表）
+func (r *DRReplicator) replaceTable(ctx context.Context, name string) (int64, error) {
+	rows, err := r.primary.DB.WithContext(ctx).Table(name).Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var copied int64
+	err = r.secondary.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf(`DELETE FROM "%s"`, name)).Error; err != nil {
+			return err
+		}
+		batch := make([]map[string]interface{}, 0, r.batchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := tx.Table(name).Create(&batch).Error; err != nil {
+				return fmt.Errorf("写入备区域失败: %w", err)
+			}
+			copied += int64(len(batch))
+			batch = batch[:0]
+			return nil
+		}
+		for rows.Next() {
+			row := map[string]interface{}{}
+			if err := r.primary.DB.ScanRows(rows, &row); err != nil {
+				return err
+			}
+			batch = append(batch, row)
+			if len(batch) >= r.batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return flush()
+	})
+	return copied, err
+}
+
+// reconcileMetadataDeletes 删除备区域中主库已删除的行
+func (r *DRReplicator) reconcileMetadataDeletes(ctx context.Context, name, key string) (int64, error) {
+	var sourceKeys, targetKeys []interface{}
+	if err := r.primary.DB.WithContext(ctx).Table(name).Pluck(key, &sourceKeys).Error; err != nil {
+		return 0, err
+	}
+	if err := r.secondary.DB.WithContext(ctx).Table(name).Pluck(key, &targetKeys).Error; err != nil {
+		return 0, err
+	}
+
+	alive := make(map[string]struct{}, len(sourceKeys))
+	for _, k := range sourceKeys {
+		alive[fmt.Sprint(k)] = struct{}{}
+	}
+	stale := make([]interface{}, 0)
+	for _, k := range targetKeys {
+		if _, ok := alive[fmt.Sprint(k)]; !ok {
+			stale = append(stale, k)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	var removed int64
+	for start := 0; start < len(stale); start += r.batchSize {
+		end := start + r.batchSize
+		if end > len(stale) {
+			end = len(stale)
+		}
+		result := r.secondary.DB.WithContext(ctx).Exec(fmt.Sprintf(`DELETE FROM "%s" WHERE "%s" IN ?`, name, key), stale[start:end])
+		if result.Error != nil {
+			return removed, result.Error
+		}
+		removed += result.RowsAffected
+	}
+	return removed, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
+)
+
+// FaultInjectionPlugin GORM 故障注入插件 ✨
+// 在执行 SQL 前按规则附加延迟或写入注入错误（GORM 遇到错误时跳过执行），
+// 操作名为 query / create / update / delete / row / raw，目标为语句的表名（动态表为物理表名）
+type FaultInjectionPlugin struct {
+	injector *faultinject.Injector
+}
+
+// NewFaultInjectionPlugin 创建故障注入插件
+func NewFaultInjectionPlugin(injector *faultinject.Injector) *FaultInjectionPlugin {
+	return &FaultInjectionPlugin{injector: injector}
+}
+
+// Name 插件名称
+func (p *FaultInjectionPlugin) Name() string {
+	return "luckdb:fault_injection"
+}
+
+// Initialize 注册 GORM 回调
+func (p *FaultInjectionPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("fault_injection:create", p.inject("create")); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("fault_injection:query", p.inject("query")); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("fault_injection:update", p.inject("update")); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("fault_injection:delete", p.inject("delete")); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("fault_injection:row", p.inject("row")); err != nil {
+		return err
+	}
+	return cb.Raw().Before("gorm:raw").Register("fault_injection:raw", p.inject("raw"))
+}
+
+func (p *FaultInjectionPlugin) inject(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement == nil {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			// 原生 SQL（动态表读写）从语句中解析表名
+			_, table = parseDynamicTable(db.Statement.SQL.String())
+		}
+		if err := p.injector.Inject(db.Statement.Context, operation, table); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+}
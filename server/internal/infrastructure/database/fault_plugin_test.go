@@ -0,0 +1,51 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
+)
+
+// openDryRunDB 不连接数据库的 GORM 实例（DryRun 只生成 SQL）
+func openDryRunDB(t *testing.T, injector *faultinject.Injector) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=luckdb"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("创建 GORM 实例失败: %v", err)
+	}
+	if err := db.Use(NewFaultInjectionPlugin(injector)); err != nil {
+		t.Fatalf("注册故障注入插件失败: %v", err)
+	}
+	return db
+}
+
+func TestFaultInjectionPluginFailsMatchingStatements(t *testing.T) {
+	injector := faultinject.New("database", config.FaultInjectionConfig{
+		Enabled:   true,
+		ErrorRate: 1,
+		Targets:   []string{"tbl*"},
+	})
+	db := openDryRunDB(t, injector)
+
+	var rows []map[string]interface{}
+	if err := db.Table("users").Find(&rows).Error; err != nil {
+		t.Errorf("未匹配的表不应注入，得到 %v", err)
+	}
+	if err := db.Table("tblOrders").Find(&rows).Error; !errors.Is(err, faultinject.ErrInjected) {
+		t.Errorf("匹配的表应返回注入错误，得到 %v", err)
+	}
+	if err := db.Exec(`UPDATE "bseA1"."tblOrders" SET "a" = 1`).Error; !errors.Is(err, faultinject.ErrInjected) {
+		t.Errorf("原生 SQL 应按解析出的动态表注入，得到 %v", err)
+	}
+	if stats := injector.Stats(); stats.Failed != 2 {
+		t.Errorf("应注入 2 次失败，得到 %+v", stats)
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// AdminConsoleSession 管理控制台会话（只保存令牌摘要）
+type AdminConsoleSession struct {
+	ID        string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	UserID    string     `gorm:"column:user_id;type:varchar(50);not null;index" json:"user_id"`
+	Email     string     `gorm:"column:email;type:varchar(255)" json:"email"`
+	TokenHash string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	IPAddress string     `gorm:"column:ip_address;type:varchar(64)" json:"ip_address"`
+	UserAgent string     `gorm:"column:user_agent;type:text" json:"user_agent"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AdminConsoleSession) TableName() string {
+	return "admin_console_session"
+}
+
+// FeatureFlagOverride 控制台对功能开关的覆盖
+type FeatureFlagOverride struct {
+	Key       string    `gorm:"column:key;primaryKey;type:varchar(64)" json:"key"`
+	Enabled   bool      `gorm:"column:enabled;not null" json:"enabled"`
+	UpdatedBy string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (FeatureFlagOverride) TableName() string {
+	return "feature_flag_override"
+}
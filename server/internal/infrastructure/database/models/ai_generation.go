@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// AIGenerationJob AI 字段生成任务（一个任务对应一个单元格）
+type AIGenerationJob struct {
+	ID               string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	SpaceID          string     `gorm:"column:space_id;type:varchar(50);index:idx_ai_generation_job_space" json:"space_id"`
+	TableID          string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	FieldID          string     `gorm:"column:field_id;type:varchar(50);not null;index:idx_ai_generation_job_cell" json:"field_id"`
+	RecordID         string     `gorm:"column:record_id;type:varchar(50);not null;index:idx_ai_generation_job_cell" json:"record_id"`
+	Trigger          string     `gorm:"column:trigger_type;type:varchar(20);not null" json:"trigger_type"`
+	Status           string     `gorm:"column:status;type:varchar(20);not null;index:idx_ai_generation_job_status" json:"status"`
+	Attempts         int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	Error            *string    `gorm:"column:error;type:text" json:"error"`
+	Provider         string     `gorm:"column:provider;type:varchar(50)" json:"provider"`
+	Model            string     `gorm:"column:model;type:varchar(100)" json:"model"`
+	PromptTokens     int        `gorm:"column:prompt_tokens;not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int        `gorm:"column:completion_tokens;not null;default:0" json:"completion_tokens"`
+	CreatedBy        string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	NextRunAt        time.Time  `gorm:"column:next_run_at;not null;index:idx_ai_generation_job_status" json:"next_run_at"`
+	CreatedTime      time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime      time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime     *time.Time `gorm:"column:finished_time;index:idx_ai_generation_job_space" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (AIGenerationJob) TableName() string {
+	return "ai_generation_job"
+}
+
+// SpaceAIQuota 工作空间 AI 生成配额
+type SpaceAIQuota struct {
+	SpaceID             string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	MonthlyRequests     int64     `gorm:"column:monthly_requests;not null;default:0" json:"monthly_requests"`
+	MonthlyTokens       int64     `gorm:"column:monthly_tokens;not null;default:0" json:"monthly_tokens"`
+	MaxTokensPerRequest int       `gorm:"column:max_tokens_per_request;not null;default:0" json:"max_tokens_per_request"`
+	UpdatedBy           string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime         time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SpaceAIQuota) TableName() string {
+	return "space_ai_quotas"
+}
@@ -23,6 +23,11 @@ type Attachment struct {
 	FieldID        string         `gorm:"not null;type:varchar(30);index" json:"field_id"`
 	RecordID       string         `gorm:"not null;type:varchar(30);index" json:"record_id"`
 	CreatedBy      string         `gorm:"not null;type:varchar(30)" json:"created_by"`
+	ContentHash    string         `gorm:"column:content_hash;type:varchar(64);index" json:"content_hash,omitempty"`            // 内容 SHA-256，文件存放于 attachment_blobs 对应路径
+	ScanStatus     string         `gorm:"column:scan_status;not null;type:varchar(20);default:clean;index" json:"scan_status"` // 上传后处理状态，历史附件视为已通过
+	ScanDetail     string         `gorm:"column:scan_detail;type:varchar(500)" json:"scan_detail,omitempty"`
+	ScannedTime    *time.Time     `gorm:"column:scanned_time" json:"scanned_time,omitempty"`
+	ScanLeaseUntil *time.Time     `gorm:"column:scan_lease_until" json:"-"` // 扫描租约到期时间
 	CreatedTime    time.Time      `gorm:"autoCreateTime;column:created_time" json:"created_time"`
 	UpdatedTime    time.Time      `gorm:"autoUpdateTime;column:updated_time" json:"updated_time"`
 	DeletedTime    gorm.DeletedAt `gorm:"column:deleted_time" json:"deleted_time,omitempty"`
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// AttachmentBlob 按内容寻址的附件文件及其引用计数
+type AttachmentBlob struct {
+	Hash              string     `gorm:"column:hash;primaryKey;type:varchar(64)" json:"hash"`
+	Size              int64      `gorm:"column:size;not null" json:"size"`
+	MimeType          string     `gorm:"column:mime_type;type:varchar(100)" json:"mime_type"`
+	RefCount          int        `gorm:"column:ref_count;not null;default:0" json:"ref_count"`
+	UnreferencedSince *time.Time `gorm:"column:unreferenced_since;index" json:"unreferenced_since,omitempty"`
+	CreatedTime       time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime       time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (AttachmentBlob) TableName() string {
+	return "attachment_blobs"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// SpaceAttachmentPolicy 工作空间附件策略
+type SpaceAttachmentPolicy struct {
+	SpaceID           string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	MaxFileSizeMB     int       `gorm:"column:max_file_size_mb;not null;default:0" json:"max_file_size_mb"`
+	AllowedTypes      []string  `gorm:"column:allowed_types;serializer:json;type:jsonb" json:"allowed_types"`
+	BlockedExtensions []string  `gorm:"column:blocked_extensions;serializer:json;type:jsonb" json:"blocked_extensions"`
+	ScanRequired      bool      `gorm:"column:scan_required;not null;default:false" json:"scan_required"`
+	WebhookURL        string    `gorm:"column:webhook_url;type:varchar(500)" json:"webhook_url"`
+	WebhookSecret     string    `gorm:"column:webhook_secret;type:varchar(100)" json:"-"`
+	UpdatedBy         string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime       time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SpaceAttachmentPolicy) TableName() string {
+	return "space_attachment_policies"
+}
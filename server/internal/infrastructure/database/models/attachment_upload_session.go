@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// AttachmentUploadSession 可续传上传（分片保存在附件存储中，按行占用保证同一上传只有一个实例接收分片）
+type AttachmentUploadSession struct {
+	ID          string     `gorm:"column:id;primaryKey;type:varchar(30)" json:"id"`
+	Token       string     `gorm:"column:token;not null;type:varchar(50)" json:"-"`
+	UserID      string     `gorm:"column:user_id;not null;type:varchar(50);index" json:"user_id"`
+	Filename    string     `gorm:"column:filename;not null;type:varchar(255)" json:"filename"`
+	Length      int64      `gorm:"column:length;not null" json:"length"`
+	Offset      int64      `gorm:"column:upload_offset;not null;default:0" json:"offset"`
+	Status      string     `gorm:"column:status;not null;type:varchar(20)" json:"status"`
+	Parts       string     `gorm:"column:parts;not null;type:text;default:''" json:"-"` // 已保存分片的结束偏移（逗号结尾，如 "5,11,"）
+	LockToken   string     `gorm:"column:lock_token;not null;type:varchar(30);default:''" json:"-"`
+	LockedUntil *time.Time `gorm:"column:locked_until" json:"-"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	CreatedTime time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (AttachmentUploadSession) TableName() string {
+	return "attachment_upload_sessions"
+}
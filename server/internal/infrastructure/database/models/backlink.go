@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// RecordBacklink 记录提及的反向链接（来源记录的长文本/富文本单元格提及了目标记录）
+type RecordBacklink struct {
+	SourceTableID  string    `gorm:"column:source_table_id;primaryKey;type:varchar(50)" json:"source_table_id"`
+	SourceRecordID string    `gorm:"column:source_record_id;primaryKey;type:varchar(50)" json:"source_record_id"`
+	SourceFieldID  string    `gorm:"column:source_field_id;primaryKey;type:varchar(50)" json:"source_field_id"`
+	TargetTableID  string    `gorm:"column:target_table_id;primaryKey;type:varchar(50);index:idx_record_backlink_target,priority:1" json:"target_table_id"`
+	TargetRecordID string    `gorm:"column:target_record_id;primaryKey;type:varchar(50);index:idx_record_backlink_target,priority:2" json:"target_record_id"`
+	UpdatedTime    time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (RecordBacklink) TableName() string {
+	return "record_backlink"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// BaseBranch Base 的沙盒分支
+type BaseBranch struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Name            string     `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	SourceBaseID    string     `gorm:"column:source_base_id;type:varchar(50);not null;index" json:"source_base_id"`
+	SandboxBaseID   string     `gorm:"column:sandbox_base_id;type:varchar(50);not null;uniqueIndex" json:"sandbox_base_id"`
+	Status          string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	BaseSpec        string     `gorm:"column:base_spec;type:text;not null" json:"-"`
+	LastOperationID string     `gorm:"column:last_operation_id;type:varchar(50)" json:"last_operation_id"`
+	PromotedAt      *time.Time `gorm:"column:promoted_at" json:"promoted_at"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50);not null" json:"created_by"`
+	CreatedAt       time.Time  `gorm:"column:created_at;not null" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;not null" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (BaseBranch) TableName() string {
+	return "base_branches"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SpaceBusinessCalendar 工作空间的工作日历（每周休息日、节假日与调休工作日）
+type SpaceBusinessCalendar struct {
+	SpaceID     string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	Weekends    []int     `gorm:"column:weekends;serializer:json;type:jsonb" json:"weekends"`
+	Holidays    []string  `gorm:"column:holidays;serializer:json;type:jsonb" json:"holidays"`
+	Workdays    []string  `gorm:"column:workdays;serializer:json;type:jsonb" json:"workdays"`
+	UpdatedBy   string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SpaceBusinessCalendar) TableName() string {
+	return "space_business_calendar"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ChangeEvent 变更流发件箱（外部同步的增量数据源）
+type ChangeEvent struct {
+	ID          string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Seq         *int64    `gorm:"column:seq;uniqueIndex:uq_change_event_seq" json:"seq"` // 未编号时为空
+	BaseID      string    `gorm:"column:base_id;type:varchar(50);not null" json:"base_id"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	EntityType  string    `gorm:"column:entity_type;type:varchar(20);not null" json:"entity_type"`
+	Action      string    `gorm:"column:action;type:varchar(20);not null" json:"action"`
+	EntityID    string    `gorm:"column:entity_id;type:varchar(50);not null" json:"entity_id"`
+	Data        *string   `gorm:"column:data;type:text" json:"data"`
+	Version     int64     `gorm:"column:version;not null;default:0" json:"version"`
+	UserID      string    `gorm:"column:user_id;type:varchar(50)" json:"user_id"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (ChangeEvent) TableName() string {
+	return "change_event"
+}
@@ -8,8 +8,10 @@ import (
 type Dashboard struct {
 	ID               string    `gorm:"primaryKey;type:varchar(30)" json:"id"`
 	Name             string    `gorm:"type:varchar(255);not null" json:"name"`
-	BaseID           string    `gorm:"column:base_id;type:varchar(30);not null" json:"base_id"`
+	BaseID           string    `gorm:"column:base_id;type:varchar(30);not null;index" json:"base_id"`
 	Layout           *string   `gorm:"type:text" json:"layout"`
+	EnableShare      bool      `gorm:"column:enable_share;not null;default:false" json:"enable_share"`
+	ShareID          *string   `gorm:"column:share_id;type:varchar(50);uniqueIndex" json:"share_id"`
 	CreatedBy        string    `gorm:"column:created_by;type:varchar(30);not null" json:"created_by"`
 	CreatedTime      time.Time `gorm:"autoCreateTime;column:created_time" json:"created_time"`
 	LastModifiedTime time.Time `gorm:"autoUpdateTime;column:last_modified_time" json:"last_modified_time"`
@@ -20,3 +22,22 @@ type Dashboard struct {
 func (Dashboard) TableName() string {
 	return "dashboard"
 }
+
+// DashboardWidget 仪表板组件模型
+type DashboardWidget struct {
+	ID               string    `gorm:"primaryKey;type:varchar(30)" json:"id"`
+	DashboardID      string    `gorm:"column:dashboard_id;type:varchar(30);not null;index" json:"dashboard_id"`
+	Type             string    `gorm:"type:varchar(20);not null" json:"type"`
+	Name             string    `gorm:"type:varchar(255);not null" json:"name"`
+	TableID          *string   `gorm:"column:table_id;type:varchar(50)" json:"table_id"`
+	ViewID           *string   `gorm:"column:view_id;type:varchar(50)" json:"view_id"`
+	Config           *string   `gorm:"type:jsonb" json:"config"`
+	CreatedBy        string    `gorm:"column:created_by;type:varchar(30);not null" json:"created_by"`
+	CreatedTime      time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	LastModifiedTime time.Time `gorm:"column:last_modified_time;not null" json:"last_modified_time"`
+}
+
+// TableName 指定表名
+func (DashboardWidget) TableName() string {
+	return "dashboard_widget"
+}
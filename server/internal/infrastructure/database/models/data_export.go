@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DataExport Base 数据导出任务
+type DataExport struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID       string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_data_export_base" json:"base_id"`
+	TableIDs     string     `gorm:"column:table_ids;type:text;not null" json:"table_ids"` // JSON 数组
+	Format       string     `gorm:"column:format;type:varchar(20);not null" json:"format"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_data_export_status" json:"status"`
+	Manifest     string     `gorm:"column:manifest;type:text" json:"manifest"` // JSON，完成后写入
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (DataExport) TableName() string {
+	return "data_export"
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// DRCheckpoint 灾备复制流进度（保存在备区域）
+type DRCheckpoint struct {
+	Stream            string     `gorm:"column:stream;primaryKey;type:varchar(30)" json:"stream"`
+	Position          string     `gorm:"column:position;type:varchar(100)" json:"position"`
+	ReplicatedThrough *time.Time `gorm:"column:replicated_through" json:"replicated_through"`
+	Items             int64      `gorm:"column:items;not null;default:0" json:"items"`
+	LastRunTime       *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastSuccessTime   *time.Time `gorm:"column:last_success_time" json:"last_success_time"`
+	LastError         string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil       *time.Time `gorm:"column:locked_until" json:"-"` // 执行租约到期时间
+	UpdatedTime       time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (DRCheckpoint) TableName() string {
+	return "dr_checkpoint"
+}
+
+// DRPromotion 灾备备区域提升记录（保存在备区域）
+type DRPromotion struct {
+	ID           string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Region       string    `gorm:"column:region;type:varchar(50);not null" json:"region"`
+	Forced       bool      `gorm:"column:forced;not null;default:false" json:"forced"`
+	RPOSeconds   *int64    `gorm:"column:rpo_seconds" json:"rpo_seconds"`
+	Reason       string    `gorm:"column:reason;type:text" json:"reason"`
+	PromotedBy   string    `gorm:"column:promoted_by;type:varchar(50)" json:"promoted_by"`
+	PromotedTime time.Time `gorm:"column:promoted_time;not null;index:idx_dr_promotion_time" json:"promoted_time"`
+}
+
+// TableName 指定表名
+func (DRPromotion) TableName() string {
+	return "dr_promotion"
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// DocumentTemplate 文档模板（DOCX/HTML 合并域映射到表字段，生成文件写入附件字段）
+type DocumentTemplate struct {
+	ID            string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID       string    `gorm:"column:table_id;type:varchar(50);not null;index:idx_document_template_table" json:"table_id"`
+	Name          string    `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Format        string    `gorm:"column:format;type:varchar(20);not null" json:"format"`
+	Output        string    `gorm:"column:output;type:varchar(20);not null" json:"output"`
+	FileName      string    `gorm:"column:file_name;type:varchar(255)" json:"file_name"`
+	Size          int64     `gorm:"column:size" json:"size"`
+	MergeFields   string    `gorm:"column:merge_fields;type:text;not null" json:"merge_fields"` // JSON 数组
+	Mappings      string    `gorm:"column:mappings;type:text;not null" json:"mappings"`         // JSON 对象：合并域 → 字段ID
+	TargetFieldID string    `gorm:"column:target_field_id;type:varchar(50);not null" json:"target_field_id"`
+	NameFieldID   string    `gorm:"column:name_field_id;type:varchar(50)" json:"name_field_id"`
+	CreatedBy     string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	UpdatedBy     string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	CreatedTime   time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime   time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (DocumentTemplate) TableName() string {
+	return "document_template"
+}
+
+// DocumentJob 文档生成任务
+type DocumentJob struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TemplateID   string     `gorm:"column:template_id;type:varchar(50);not null;index:idx_document_job_template" json:"template_id"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	ViewID       string     `gorm:"column:view_id;type:varchar(50)" json:"view_id"`
+	RecordIDs    string     `gorm:"column:record_ids;type:text" json:"record_ids"` // JSON 数组
+	TimeZone     string     `gorm:"column:time_zone;type:varchar(64)" json:"time_zone"`
+	Locale       string     `gorm:"column:locale;type:varchar(20)" json:"locale"`
+	Source       string     `gorm:"column:source;type:varchar(20);not null" json:"source"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_document_job_status" json:"status"`
+	Total        int        `gorm:"column:total" json:"total"`
+	Generated    int        `gorm:"column:generated" json:"generated"`
+	Failed       int        `gorm:"column:failed" json:"failed"`
+	Truncated    bool       `gorm:"column:truncated;not null;default:false" json:"truncated"`
+	Results      string     `gorm:"column:results;type:text" json:"results"` // JSON 数组
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (DocumentJob) TableName() string {
+	return "document_job"
+}
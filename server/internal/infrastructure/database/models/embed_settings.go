@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SpaceEmbedSettings 工作空间嵌入设置
+type SpaceEmbedSettings struct {
+	SpaceID            string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	Enabled            bool      `gorm:"column:enabled;not null;default:false" json:"enabled"`
+	AllowedOrigins     []string  `gorm:"column:allowed_origins;serializer:json;type:jsonb" json:"allowed_origins"`
+	MaxTokenTTLMinutes int       `gorm:"column:max_token_ttl_minutes;not null;default:0" json:"max_token_ttl_minutes"`
+	TokenVersion       int       `gorm:"column:token_version;not null;default:0" json:"token_version"`
+	UpdatedBy          string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime        time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SpaceEmbedSettings) TableName() string {
+	return "space_embed_settings"
+}
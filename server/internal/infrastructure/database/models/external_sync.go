@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// ExternalSyncConnector 外部数据同步连接器
+type ExternalSyncConnector struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID          string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_external_sync_connector_base" json:"base_id"`
+	Name            string     `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Source          string     `gorm:"column:source;type:text;not null" json:"source"` // JSON
+	Credentials     string     `gorm:"column:credentials;type:text" json:"-"`
+	Mappings        string     `gorm:"column:mappings;type:text" json:"mappings"` // JSON
+	TargetTableID   string     `gorm:"column:target_table_id;type:varchar(50);not null;uniqueIndex:uniq_external_sync_connector_target" json:"target_table_id"`
+	DeletedFieldID  string     `gorm:"column:deleted_field_id;type:varchar(50)" json:"deleted_field_id"`
+	IntervalSeconds int64      `gorm:"column:interval_seconds;not null;default:0" json:"interval_seconds"`
+	Enabled         bool       `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Cursor          string     `gorm:"column:cursor;type:varchar(255)" json:"cursor"`
+	CursorKey       string     `gorm:"column:cursor_key;type:varchar(255)" json:"cursor_key"`
+	LastFullSync    *time.Time `gorm:"column:last_full_sync" json:"last_full_sync"`
+	NextRunTime     time.Time  `gorm:"column:next_run_time;not null;index:idx_external_sync_connector_next_run" json:"next_run_time"`
+	LastRunTime     *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastStatus      string     `gorm:"column:last_status;type:varchar(20)" json:"last_status"`
+	LastError       string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime     time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (ExternalSyncConnector) TableName() string {
+	return "external_sync_connector"
+}
+
+// ExternalSyncRun 外部数据同步运行记录
+type ExternalSyncRun struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	ConnectorID   string     `gorm:"column:connector_id;type:varchar(50);not null;index:idx_external_sync_run_connector" json:"connector_id"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Full          bool       `gorm:"column:full;not null;default:false" json:"full"`
+	Fetched       int64      `gorm:"column:fetched;not null;default:0" json:"fetched"`
+	Created       int64      `gorm:"column:created;not null;default:0" json:"created"`
+	Updated       int64      `gorm:"column:updated;not null;default:0" json:"updated"`
+	Removed       int64      `gorm:"column:removed;not null;default:0" json:"removed"`
+	Error         string     `gorm:"column:error;type:text" json:"error"`
+	CursorAtStart string     `gorm:"column:cursor_at_start;type:varchar(255)" json:"cursor_at_start"`
+	CursorAtEnd   string     `gorm:"column:cursor_at_end;type:varchar(255)" json:"cursor_at_end"`
+	StartedTime   time.Time  `gorm:"column:started_time;not null" json:"started_time"`
+	FinishedTime  *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (ExternalSyncRun) TableName() string {
+	return "external_sync_run"
+}
+
+// ExternalSyncRow 外部行与目标记录的对应关系
+type ExternalSyncRow struct {
+	ConnectorID    string    `gorm:"column:connector_id;primaryKey;type:varchar(50);index:idx_external_sync_row_synced,priority:1" json:"connector_id"`
+	ExternalKey    string    `gorm:"column:external_key;primaryKey;type:varchar(255)" json:"external_key"`
+	TargetRecordID string    `gorm:"column:target_record_id;type:varchar(50);not null" json:"target_record_id"`
+	SourceDeleted  bool      `gorm:"column:source_deleted;not null;default:false" json:"source_deleted"`
+	SyncedTime     time.Time `gorm:"column:synced_time;not null;index:idx_external_sync_row_synced,priority:2" json:"synced_time"`
+}
+
+// TableName 指定表名
+func (ExternalSyncRow) TableName() string {
+	return "external_sync_row"
+}
@@ -11,6 +11,7 @@ type Field struct {
 	ID                  string         `gorm:"primaryKey;type:varchar(30)" json:"id"`
 	TableID             string         `gorm:"type:varchar(50);not null;index" json:"table_id"`
 	Name                string         `gorm:"type:varchar(255);not null" json:"name"`
+	APIKey              *string        `gorm:"column:api_key;type:varchar(64)" json:"api_key"` // 同表唯一（在线迁移创建部分唯一索引）
 	Description         *string        `gorm:"type:text" json:"description"`
 	Type                string         `gorm:"type:varchar(50);not null" json:"type"`
 	CellValueType       string         `gorm:"type:varchar(50);not null" json:"cell_value_type"`
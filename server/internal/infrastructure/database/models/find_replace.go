@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// FindReplaceChange 查找替换操作中一条记录替换前后的值（用于撤销）
+type FindReplaceChange struct {
+	OperationID string    `gorm:"column:operation_id;primaryKey;type:varchar(50)" json:"operation_id"`
+	RecordID    string    `gorm:"column:record_id;primaryKey;type:varchar(50)" json:"record_id"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	Before      string    `gorm:"column:before_values;type:text;not null" json:"before_values"` // JSON：字段ID → 值
+	After       string    `gorm:"column:after_values;type:text;not null" json:"after_values"`   // JSON：字段ID → 值
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (FindReplaceChange) TableName() string {
+	return "find_replace_change"
+}
+
+// FindReplaceUndo 查找替换操作的撤销登记（每个操作只能撤销一次）
+type FindReplaceUndo struct {
+	OperationID     string    `gorm:"column:operation_id;primaryKey;type:varchar(50)" json:"operation_id"`
+	UndoOperationID string    `gorm:"column:undo_operation_id;type:varchar(50);not null" json:"undo_operation_id"`
+	CreatedTime     time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (FindReplaceUndo) TableName() string {
+	return "find_replace_undo"
+}
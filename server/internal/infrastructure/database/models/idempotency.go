@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// IdempotencyKey 幂等键（未启用 Redis 时的持久化存储）
+type IdempotencyKey struct {
+	Scope       string    `gorm:"column:scope;primaryKey;type:varchar(100)" json:"scope"`
+	Key         string    `gorm:"column:idempotency_key;primaryKey;type:varchar(255)" json:"idempotency_key"`
+	Fingerprint string    `gorm:"column:fingerprint;type:varchar(64);not null" json:"fingerprint"`
+	Method      string    `gorm:"column:method;type:varchar(10);not null" json:"method"`
+	Path        string    `gorm:"column:path;type:varchar(500);not null" json:"path"`
+	Status      string    `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Token       string    `gorm:"column:token;type:varchar(64);not null;default:''" json:"-"`
+	StatusCode  int       `gorm:"column:status_code;not null;default:0" json:"status_code"`
+	ContentType string    `gorm:"column:content_type;type:varchar(100)" json:"content_type"`
+	Body        []byte    `gorm:"column:body;type:bytea" json:"-"`
+	BodyOmitted bool      `gorm:"column:body_omitted;not null;default:false" json:"body_omitted"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	ExpiresAt   time.Time `gorm:"column:expires_at;not null;index" json:"expires_at"`
+}
+
+// TableName 指定表名
+func (IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ImpersonationSession 代入会话（只保存令牌摘要）
+type ImpersonationSession struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	SupportUserID string     `gorm:"column:support_user_id;type:varchar(50);not null;index" json:"support_user_id"`
+	SupportEmail  string     `gorm:"column:support_email;type:varchar(255)" json:"support_email"`
+	TargetUserID  string     `gorm:"column:target_user_id;type:varchar(50);not null;index" json:"target_user_id"`
+	TargetEmail   string     `gorm:"column:target_email;type:varchar(255)" json:"target_email"`
+	Reason        string     `gorm:"column:reason;type:text;not null" json:"reason"`
+	TokenHash     string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	EndedAt       *time.Time `gorm:"column:ended_at" json:"ended_at"`
+	EndedBy       string     `gorm:"column:ended_by;type:varchar(50)" json:"ended_by"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ImpersonationSession) TableName() string {
+	return "impersonation_session"
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// IntegrationHook 集成平台 REST Hook 订阅
+type IntegrationHook struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID          string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_integration_hook_base" json:"base_id"`
+	TableID         string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	Event           string     `gorm:"column:event;type:varchar(50);not null" json:"event"`
+	TargetURL       string     `gorm:"column:target_url;type:text;not null" json:"target_url"`
+	Template        string     `gorm:"column:template;type:text" json:"template"`
+	Cursor          int64      `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	LastError       string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LastDeliveredAt *time.Time `gorm:"column:last_delivered_time" json:"last_delivered_time"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	Failures        int        `gorm:"column:failures;not null;default:0" json:"failures"`
+	MaxFailures     int        `gorm:"column:max_failures;not null;default:0" json:"max_failures"`
+	NotifyOwner     bool       `gorm:"column:notify_owner;not null;default:true" json:"notify_owner"`
+	PausedAt        *time.Time `gorm:"column:paused_time" json:"paused_time"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (IntegrationHook) TableName() string {
+	return "integration_hook"
+}
+
+// IntegrationDelivery REST Hook 失败推送（死信）
+type IntegrationDelivery struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	HookID        string     `gorm:"column:hook_id;type:varchar(50);not null;index:idx_integration_delivery_hook" json:"hook_id"`
+	TableID       string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	Event         string     `gorm:"column:event;type:varchar(50);not null" json:"event"`
+	CursorFrom    int64      `gorm:"column:cursor_from;not null" json:"cursor_from"`
+	CursorTo      int64      `gorm:"column:cursor_to;not null" json:"cursor_to"`
+	ItemCount     int        `gorm:"column:item_count;not null;default:0" json:"item_count"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;index:idx_integration_delivery_hook" json:"status"`
+	StatusCode    int        `gorm:"column:status_code;not null;default:0" json:"status_code"`
+	Error         string     `gorm:"column:error;type:text" json:"error"`
+	Attempts      int        `gorm:"column:attempts;not null;default:1" json:"attempts"`
+	FirstFailedAt time.Time  `gorm:"column:first_failed_time;not null" json:"first_failed_time"`
+	LastFailedAt  time.Time  `gorm:"column:last_failed_time;not null" json:"last_failed_time"`
+	RetriedAt     *time.Time `gorm:"column:retried_time" json:"retried_time"`
+}
+
+// TableName 指定表名
+func (IntegrationDelivery) TableName() string {
+	return "integration_delivery"
+}
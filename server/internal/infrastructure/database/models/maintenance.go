@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// MaintenanceWindow 维护窗口（实例、工作空间或 Base 只读）
+type MaintenanceWindow struct {
+	ID          string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Scope       string     `gorm:"column:scope;type:varchar(20);not null" json:"scope"`
+	ScopeID     string     `gorm:"column:scope_id;type:varchar(50)" json:"scope_id"`
+	Message     string     `gorm:"column:message;type:text" json:"message"`
+	StartedBy   string     `gorm:"column:started_by;type:varchar(50)" json:"started_by"`
+	StartedTime time.Time  `gorm:"column:started_time;not null;index:idx_maintenance_window_started" json:"started_time"`
+	EndsTime    *time.Time `gorm:"column:ends_time" json:"ends_time"`
+	EndedBy     string     `gorm:"column:ended_by;type:varchar(50)" json:"ended_by"`
+	EndedTime   *time.Time `gorm:"column:ended_time;index:idx_maintenance_window_ended" json:"ended_time"`
+}
+
+// TableName 指定表名
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_window"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// OfflineMutation 已处理的离线修改（客户端重复推送时返回原结果，并用于解析本地记录ID）
+type OfflineMutation struct {
+	ID             string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	UserID         string    `gorm:"column:user_id;type:varchar(50);not null;uniqueIndex:uq_offline_mutation;index:idx_offline_mutation_record" json:"user_id"`
+	ClientID       string    `gorm:"column:client_id;type:varchar(100);not null;uniqueIndex:uq_offline_mutation;index:idx_offline_mutation_record" json:"client_id"`
+	MutationID     string    `gorm:"column:mutation_id;type:varchar(100);not null;uniqueIndex:uq_offline_mutation" json:"mutation_id"`
+	TableID        string    `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	ClientRecordID string    `gorm:"column:client_record_id;type:varchar(100);index:idx_offline_mutation_record" json:"client_record_id"`
+	Status         string    `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Result         string    `gorm:"column:result;type:text;not null" json:"result"` // JSON：回放结果
+	CreatedTime    time.Time `gorm:"column:created_time;not null;index" json:"created_time"`
+}
+
+// TableName 指定表名
+func (OfflineMutation) TableName() string {
+	return "offline_mutation"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Operation 长时操作（导入、转换、恢复等后台任务）
+type Operation struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID          string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_operation_base_created" json:"base_id"`
+	Type            string     `gorm:"column:type;type:varchar(50);not null" json:"type"`
+	Status          string     `gorm:"column:status;type:varchar(20);not null;index:idx_operation_status" json:"status"`
+	Params          *string    `gorm:"column:params;type:text" json:"params"`
+	Total           int64      `gorm:"column:total;not null;default:0" json:"total"`
+	Processed       int64      `gorm:"column:processed;not null;default:0" json:"processed"`
+	FailedCount     int64      `gorm:"column:failed_count;not null;default:0" json:"failed_count"`
+	Errors          *string    `gorm:"column:errors;type:text" json:"errors"`
+	Result          *string    `gorm:"column:result;type:text" json:"result"`
+	Error           *string    `gorm:"column:error;type:text" json:"error"`
+	CancelRequested bool       `gorm:"column:cancel_requested;not null;default:false" json:"cancel_requested"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null;index:idx_operation_base_created" json:"created_time"`
+	UpdatedTime     time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	StartedTime     *time.Time `gorm:"column:started_time" json:"started_time"`
+	FinishedTime    *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (Operation) TableName() string {
+	return "operation"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Page 界面页面模型（元素与分享规则以 JSON 保存）
+type Page struct {
+	ID               string    `gorm:"primaryKey;type:varchar(30)" json:"id"`
+	BaseID           string    `gorm:"column:base_id;type:varchar(30);not null;index" json:"base_id"`
+	Name             string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description      string    `gorm:"type:text" json:"description"`
+	Elements         string    `gorm:"type:text" json:"elements"`
+	Rules            string    `gorm:"type:text" json:"rules"`
+	CreatedBy        string    `gorm:"column:created_by;type:varchar(30);not null" json:"created_by"`
+	CreatedTime      time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	LastModifiedTime time.Time `gorm:"column:last_modified_time;not null" json:"last_modified_time"`
+	LastModifiedBy   *string   `gorm:"column:last_modified_by;type:varchar(50)" json:"last_modified_by"`
+}
+
+// TableName 指定表名
+func (Page) TableName() string {
+	return "page"
+}
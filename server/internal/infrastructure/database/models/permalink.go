@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Permalink 记录或视图的永久短链接（同一目标只有一个链接）
+type Permalink struct {
+	Code        string    `gorm:"column:code;primaryKey;type:varchar(20)" json:"code"`
+	TargetKey   string    `gorm:"column:target_key;type:varchar(220);not null;uniqueIndex" json:"target_key"`
+	TargetType  string    `gorm:"column:target_type;type:varchar(20);not null" json:"target_type"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null;index" json:"table_id"`
+	ViewID      string    `gorm:"column:view_id;type:varchar(50)" json:"view_id"`
+	RecordID    string    `gorm:"column:record_id;type:varchar(50)" json:"record_id"`
+	CreatedBy   string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (Permalink) TableName() string {
+	return "permalink"
+}
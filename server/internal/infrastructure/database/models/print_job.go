@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// PrintJob 记录与视图的 PDF 打印任务
+type PrintJob struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TargetType   string     `gorm:"column:target_type;type:varchar(20);not null" json:"target_type"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null;index:idx_print_job_table,priority:1" json:"table_id"`
+	ViewID       string     `gorm:"column:view_id;type:varchar(50)" json:"view_id"`
+	RecordID     string     `gorm:"column:record_id;type:varchar(50)" json:"record_id"`
+	Paper        string     `gorm:"column:paper;type:varchar(20);not null" json:"paper"`
+	Orientation  string     `gorm:"column:orientation;type:varchar(20);not null" json:"orientation"`
+	TimeZone     string     `gorm:"column:time_zone;type:varchar(64)" json:"time_zone"`
+	Locale       string     `gorm:"column:locale;type:varchar(20)" json:"locale"`
+	Source       string     `gorm:"column:source;type:varchar(20);not null" json:"source"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_print_job_status" json:"status"`
+	FileName     string     `gorm:"column:file_name;type:varchar(255)" json:"file_name"`
+	Size         int64      `gorm:"column:size" json:"size"`
+	Pages        int        `gorm:"column:pages" json:"pages"`
+	Rows         int        `gorm:"column:rows" json:"rows"`
+	Truncated    bool       `gorm:"column:truncated;not null;default:false" json:"truncated"`
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50);index:idx_print_job_table,priority:2" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (PrintJob) TableName() string {
+	return "print_job"
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// FieldMaskingPolicy 字段脱敏策略
+type FieldMaskingPolicy struct {
+	ID          string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null;uniqueIndex:uidx_field_masking_policy_field,priority:1" json:"table_id"`
+	FieldID     string    `gorm:"column:field_id;type:varchar(50);not null;uniqueIndex:uidx_field_masking_policy_field,priority:2" json:"field_id"`
+	PIIType     string    `gorm:"column:pii_type;type:varchar(32)" json:"pii_type"`
+	Strategy    string    `gorm:"column:strategy;type:varchar(32);not null" json:"strategy"`
+	ExemptRoles []string  `gorm:"column:exempt_roles;serializer:json;type:jsonb" json:"exempt_roles"`
+	CreatedBy   string    `gorm:"column:created_by;type:varchar(50);not null" json:"created_by"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (FieldMaskingPolicy) TableName() string {
+	return "field_masking_policy"
+}
+
+// FieldPIIFinding 字段PII扫描结果
+type FieldPIIFinding struct {
+	TableID     string    `gorm:"column:table_id;primaryKey;type:varchar(50)" json:"table_id"`
+	FieldID     string    `gorm:"column:field_id;primaryKey;type:varchar(50)" json:"field_id"`
+	PIIType     string    `gorm:"column:pii_type;primaryKey;type:varchar(32)" json:"pii_type"`
+	MatchCount  int       `gorm:"column:match_count;not null" json:"match_count"`
+	SampleSize  int       `gorm:"column:sample_size;not null" json:"sample_size"`
+	RecordIDs   []string  `gorm:"column:record_ids;serializer:json;type:jsonb" json:"record_ids"`
+	ScannedTime time.Time `gorm:"column:scanned_time;not null" json:"scanned_time"`
+}
+
+// TableName 指定表名
+func (FieldPIIFinding) TableName() string {
+	return "field_pii_finding"
+}
+
+// GDPRErasureJob 数据主体擦除任务
+type GDPRErasureJob struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	SpaceID       string     `gorm:"column:space_id;type:varchar(50);not null;index" json:"space_id"`
+	SubjectUserID string     `gorm:"column:subject_user_id;type:varchar(50)" json:"subject_user_id"`
+	SubjectEmail  string     `gorm:"column:subject_email;type:varchar(255)" json:"subject_email"`
+	Mode          string     `gorm:"column:mode;type:varchar(20);not null" json:"mode"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Report        *string    `gorm:"column:report;type:jsonb" json:"report"`
+	Error         *string    `gorm:"column:error;type:text" json:"error"`
+	RequestedBy   string     `gorm:"column:requested_by;type:varchar(50);not null" json:"requested_by"`
+	CreatedTime   time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	FinishedTime  *time.Time `gorm:"column:finished_time" json:"finished_time"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" json:"locked_until"`
+}
+
+// TableName 指定表名
+func (GDPRErasureJob) TableName() string {
+	return "gdpr_erasure_job"
+}
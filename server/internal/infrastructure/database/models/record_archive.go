@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// RecordArchivePolicy 表的记录归档策略
+type RecordArchivePolicy struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null;uniqueIndex" json:"table_id"`
+	Basis        string     `gorm:"column:basis;type:varchar(30);not null" json:"basis"`
+	AfterDays    int        `gorm:"column:after_days;not null" json:"after_days"`
+	Enabled      bool       `gorm:"column:enabled;not null;default:true;index:idx_record_archive_policy_due,priority:1" json:"enabled"`
+	NextRunTime  time.Time  `gorm:"column:next_run_time;not null;index:idx_record_archive_policy_due,priority:2" json:"next_run_time"`
+	LockedUntil  *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	LastRunTime  *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastArchived int        `gorm:"column:last_archived;not null;default:0" json:"last_archived"`
+	LastError    string     `gorm:"column:last_error;type:text" json:"last_error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50);not null" json:"created_by"`
+	UpdatedBy    string     `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (RecordArchivePolicy) TableName() string {
+	return "record_archive_policy"
+}
+
+// ArchivedRecord 归档的记录（冷存储：物理行按 JSON 保存，加密字段保留密文）
+type ArchivedRecord struct {
+	TableID      string                 `gorm:"column:table_id;primaryKey;type:varchar(50);index:idx_record_archive_time,priority:1" json:"table_id"`
+	RecordID     string                 `gorm:"column:record_id;primaryKey;type:varchar(50)" json:"record_id"`
+	Row          map[string]interface{} `gorm:"column:row;serializer:json;type:jsonb;not null" json:"row"`
+	ArchivedBy   string                 `gorm:"column:archived_by;type:varchar(50)" json:"archived_by"`
+	ArchivedTime time.Time              `gorm:"column:archived_time;not null;index:idx_record_archive_time,priority:2" json:"archived_time"`
+}
+
+// TableName 指定表名
+func (ArchivedRecord) TableName() string {
+	return "record_archive"
+}
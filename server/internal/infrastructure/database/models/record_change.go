@@ -32,3 +32,22 @@ func (rc *RecordChange) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// RecordCellChange 单元格修改记录（记录变更日志按单元格的投影，用于查询单元格历史）
+type RecordCellChange struct {
+	ID        string      `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	TableID   string      `gorm:"type:varchar(36);not null;index:idx_record_cell_changes_cell,priority:1" json:"table_id"`
+	RecordID  string      `gorm:"type:varchar(36);not null;index:idx_record_cell_changes_cell,priority:2" json:"record_id"`
+	FieldID   string      `gorm:"type:varchar(36);not null;index:idx_record_cell_changes_cell,priority:3" json:"field_id"`
+	Version   int64       `gorm:"type:bigint;not null;index:idx_record_cell_changes_cell,priority:4" json:"version"`
+	OldValue  interface{} `gorm:"serializer:json;type:jsonb" json:"old_value"`
+	NewValue  interface{} `gorm:"serializer:json;type:jsonb" json:"new_value"`
+	Encrypted bool        `gorm:"not null;default:false" json:"encrypted"` // 值为密文
+	ChangedBy string      `gorm:"type:varchar(36)" json:"changed_by"`
+	ChangedAt time.Time   `gorm:"type:timestamp;not null;index:idx_record_cell_changes_time" json:"changed_at"`
+}
+
+// TableName 指定表名
+func (RecordCellChange) TableName() string {
+	return "record_cell_changes"
+}
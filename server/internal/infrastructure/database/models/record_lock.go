@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RecordLock 记录编辑锁（锁定整条记录或部分字段，只有持有者可以修改）
+type RecordLock struct {
+	ID          string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID     string     `gorm:"column:table_id;type:varchar(50);not null;index:idx_record_lock_record" json:"table_id"`
+	RecordID    string     `gorm:"column:record_id;type:varchar(50);not null;index:idx_record_lock_record" json:"record_id"`
+	FieldIDs    string     `gorm:"column:field_ids;type:text" json:"field_ids"` // JSON 数组，为空表示整条记录
+	OwnerID     string     `gorm:"column:owner_id;type:varchar(50);not null" json:"owner_id"`
+	Reason      string     `gorm:"column:reason;type:varchar(255)" json:"reason"`
+	ExpiresTime *time.Time `gorm:"column:expires_time" json:"expires_time"`
+	CreatedTime time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (RecordLock) TableName() string {
+	return "record_lock"
+}
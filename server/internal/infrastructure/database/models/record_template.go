@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// RecordTemplate 记录模板（预设单元格值，可含当前用户与相对日期占位符）
+type RecordTemplate struct {
+	ID          string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null;uniqueIndex:uq_record_template_name" json:"table_id"`
+	Name        string    `gorm:"column:name;type:varchar(255);not null;uniqueIndex:uq_record_template_name" json:"name"`
+	Description string    `gorm:"column:description;type:text" json:"description"`
+	CellValues  string    `gorm:"column:cell_values;type:text;not null" json:"cell_values"` // JSON 对象：字段ID → 值
+	IsDefault   bool      `gorm:"column:is_default;not null;default:false" json:"is_default"`
+	CreatedBy   string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	UpdatedBy   string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (RecordTemplate) TableName() string {
+	return "record_template"
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// RecurrenceRule 重复记录规则（按计划从记录模板新建记录）
+type RecurrenceRule struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID       string     `gorm:"column:table_id;type:varchar(50);not null;index:idx_recurrence_rule_table" json:"table_id"`
+	TemplateID    string     `gorm:"column:template_id;type:varchar(50);not null" json:"template_id"`
+	Name          string     `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Schedule      string     `gorm:"column:schedule;type:text;not null" json:"schedule"` // JSON
+	CalendarID    string     `gorm:"column:calendar_id;type:varchar(50);index:idx_recurrence_rule_calendar" json:"calendar_id"`
+	HolidayPolicy string     `gorm:"column:holiday_policy;type:varchar(20);not null" json:"holiday_policy"`
+	SkipDates     string     `gorm:"column:skip_dates;type:text;not null" json:"skip_dates"` // JSON 数组
+	CatchUp       string     `gorm:"column:catch_up;type:varchar(20);not null" json:"catch_up"`
+	Enabled       bool       `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Cursor        time.Time  `gorm:"column:cursor_time;not null" json:"cursor_time"`
+	NextRunTime   *time.Time `gorm:"column:next_run_time;index:idx_recurrence_rule_next_run" json:"next_run_time"`
+	LastRunTime   *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastError     string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy     string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime   time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime   time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (RecurrenceRule) TableName() string {
+	return "recurrence_rule"
+}
+
+// RecurrenceRun 重复规则的执行记录（规则与计划时间唯一，保证补跑幂等）
+type RecurrenceRun struct {
+	ID            string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	RuleID        string    `gorm:"column:rule_id;type:varchar(50);not null;uniqueIndex:uq_recurrence_run_scheduled" json:"rule_id"`
+	ScheduledTime time.Time `gorm:"column:scheduled_time;not null;uniqueIndex:uq_recurrence_run_scheduled" json:"scheduled_time"`
+	RunTime       time.Time `gorm:"column:run_time;not null" json:"run_time"`
+	Status        string    `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Reason        string    `gorm:"column:reason;type:varchar(20)" json:"reason"`
+	RecordID      string    `gorm:"column:record_id;type:varchar(50)" json:"record_id"`
+	Error         string    `gorm:"column:error;type:text" json:"error"`
+	CreatedTime   time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (RecurrenceRun) TableName() string {
+	return "recurrence_run"
+}
+
+// HolidayCalendar 节假日日历（按 Base 管理）
+type HolidayCalendar struct {
+	ID          string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID      string    `gorm:"column:base_id;type:varchar(50);not null;index:idx_holiday_calendar_base" json:"base_id"`
+	Name        string    `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Dates       string    `gorm:"column:dates;type:text;not null" json:"dates"` // JSON 数组
+	CreatedBy   string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (HolidayCalendar) TableName() string {
+	return "holiday_calendar"
+}
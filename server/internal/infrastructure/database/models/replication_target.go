@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ReplicationTarget 复制目标（逻辑复制发布 / Debezium 兼容 Webhook）
+type ReplicationTarget struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID          string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_replication_target_base" json:"base_id"`
+	Name            string     `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Mode            string     `gorm:"column:mode;type:varchar(20);not null" json:"mode"`
+	URL             string     `gorm:"column:url;type:text" json:"url"`
+	Secret          string     `gorm:"column:secret;type:varchar(100)" json:"-"`
+	TableIDs        string     `gorm:"column:table_ids;type:text;not null" json:"table_ids"` // JSON 数组
+	Enabled         bool       `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Cursor          int64      `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	LastError       string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LastDeliveredAt *time.Time `gorm:"column:last_delivered_time" json:"last_delivered_time"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime     time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (ReplicationTarget) TableName() string {
+	return "replication_target"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ScanRule 字段的扫码规则（按编码查找记录，未找到时是否新建）
+type ScanRule struct {
+	ID               string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID          string    `gorm:"column:table_id;type:varchar(50);not null;index" json:"table_id"`
+	FieldID          string    `gorm:"column:field_id;type:varchar(50);not null;uniqueIndex" json:"field_id"`
+	OnMissing        string    `gorm:"column:on_missing;type:varchar(20);not null" json:"on_missing"`
+	RecordTemplateID string    `gorm:"column:record_template_id;type:varchar(50)" json:"record_template_id"`
+	CreatedBy        string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	UpdatedBy        string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	CreatedTime      time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime      time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (ScanRule) TableName() string {
+	return "scan_rule"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SchemaMigration 动态表结构变更任务
+type SchemaMigration struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID        string     `gorm:"column:base_id;type:varchar(50);not null" json:"base_id"`
+	TableID       string     `gorm:"column:table_id;type:varchar(50);not null;index" json:"table_id"`
+	FieldID       string     `gorm:"column:field_id;type:varchar(50);not null" json:"field_id"`
+	Operation     string     `gorm:"column:operation;type:varchar(20);not null" json:"operation"`
+	ColumnName    string     `gorm:"column:column_name;type:varchar(100);not null" json:"column_name"`
+	ColumnType    string     `gorm:"column:column_type;type:varchar(50)" json:"column_type"`
+	FieldType     string     `gorm:"column:field_type;type:varchar(50)" json:"field_type"`
+	NotNull       bool       `gorm:"column:not_null;not null;default:false" json:"not_null"`
+	IsUnique      bool       `gorm:"column:is_unique;not null;default:false" json:"is_unique"`
+	BackfillValue *string    `gorm:"column:backfill_value;type:text" json:"backfill_value"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null;index:idx_schema_migration_status" json:"status"`
+	Phase         string     `gorm:"column:phase;type:varchar(20);not null" json:"phase"`
+	TotalRows     int64      `gorm:"column:total_rows;not null;default:0" json:"total_rows"`
+	ProcessedRows int64      `gorm:"column:processed_rows;not null;default:0" json:"processed_rows"`
+	Attempts      int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	Error         *string    `gorm:"column:error;type:text" json:"error"`
+	CreatedBy     string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	NextRunAt     time.Time  `gorm:"column:next_run_at;not null;index:idx_schema_migration_status" json:"next_run_at"`
+	CreatedTime   time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime   time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime  *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (SchemaMigration) TableName() string {
+	return "schema_migration"
+}
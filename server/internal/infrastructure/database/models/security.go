@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// SpaceSecurityPolicy 工作空间安全策略
+type SpaceSecurityPolicy struct {
+	SpaceID               string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	IPAllowlist           []string  `gorm:"column:ip_allowlist;serializer:json;type:jsonb" json:"ip_allowlist"`
+	SessionMaxAgeMinutes  int       `gorm:"column:session_max_age_minutes;not null;default:0" json:"session_max_age_minutes"`
+	MaxConcurrentSessions int       `gorm:"column:max_concurrent_sessions;not null;default:0" json:"max_concurrent_sessions"`
+	ReauthForSensitive    bool      `gorm:"column:reauth_for_sensitive;not null;default:false" json:"reauth_for_sensitive"`
+	ReauthWindowMinutes   int       `gorm:"column:reauth_window_minutes;not null;default:0" json:"reauth_window_minutes"`
+	UpdatedBy             string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime           time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SpaceSecurityPolicy) TableName() string {
+	return "space_security_policy"
+}
+
+// UserSession 用户登录会话
+type UserSession struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	UserID       string     `gorm:"column:user_id;type:varchar(50);not null;index" json:"user_id"`
+	IPAddress    string     `gorm:"column:ip_address;type:varchar(64)" json:"ip_address"`
+	UserAgent    string     `gorm:"column:user_agent;type:text" json:"user_agent"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	LastSeenTime time.Time  `gorm:"column:last_seen_time;not null" json:"last_seen_time"`
+	RevokedTime  *time.Time `gorm:"column:revoked_time;index" json:"revoked_time"`
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_session"
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// SemanticIndex 表的语义索引配置与处理进度
+type SemanticIndex struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	SpaceID       string     `gorm:"column:space_id;type:varchar(50)" json:"space_id"`
+	BaseID        string     `gorm:"column:base_id;type:varchar(50);not null" json:"base_id"`
+	TableID       string     `gorm:"column:table_id;type:varchar(50);not null;uniqueIndex:uq_semantic_index_table" json:"table_id"`
+	FieldIDs      string     `gorm:"column:field_ids;type:text;not null" json:"field_ids"` // JSON 数组
+	Provider      string     `gorm:"column:provider;type:varchar(50);not null" json:"provider"`
+	Model         string     `gorm:"column:model;type:varchar(100)" json:"model"`
+	Dimensions    int        `gorm:"column:dimensions;not null;default:0" json:"dimensions"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	BackfillAfter int64      `gorm:"column:backfill_after;not null;default:0" json:"backfill_after"`
+	Cursor        int64      `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	LastError     string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy     string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime   time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime   time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SemanticIndex) TableName() string {
+	return "semantic_index"
+}
+
+// RecordEmbedding 记录的向量（pgvector，不限定维度；按索引维度建立部分表达式索引）
+type RecordEmbedding struct {
+	TableID     string    `gorm:"column:table_id;primaryKey;type:varchar(50)" json:"table_id"`
+	RecordID    string    `gorm:"column:record_id;primaryKey;type:varchar(50)" json:"record_id"`
+	Embedding   string    `gorm:"column:embedding;type:vector;not null" json:"-"` // pgvector 文本格式 [x,y,...]
+	ContentHash string    `gorm:"column:content_hash;type:varchar(64);not null" json:"content_hash"`
+	Model       string    `gorm:"column:model;type:varchar(100)" json:"model"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (RecordEmbedding) TableName() string {
+	return "record_embedding"
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ServiceToken 服务令牌（只保存摘要）
+type ServiceToken struct {
+	ID         string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Name       string     `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	UserID     string     `gorm:"column:user_id;type:varchar(50);not null;index" json:"user_id"`
+	TokenHash  string     `gorm:"column:token_hash;type:varchar(64);not null;uniqueIndex" json:"-"`
+	Hint       string     `gorm:"column:hint;type:varchar(8)" json:"hint"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ServiceToken) TableName() string {
+	return "service_tokens"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SpaceDataKey 工作空间数据密钥（信封加密）
+// 只保存被 KMS 包裹后的密文，明文数据密钥仅存在于进程内存
+type SpaceDataKey struct {
+	SpaceID     string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	Version     int       `gorm:"column:version;primaryKey" json:"version"`
+	KeyID       string    `gorm:"column:key_id;type:varchar(128);not null" json:"key_id"` // 包裹该数据密钥的主密钥标识
+	WrappedKey  string    `gorm:"column:wrapped_key;type:text;not null" json:"-"`
+	CreatedTime time.Time `gorm:"column:created_time;not null" json:"created_time"`
+}
+
+// TableName 指定表名
+func (SpaceDataKey) TableName() string {
+	return "space_data_key"
+}
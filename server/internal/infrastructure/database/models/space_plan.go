@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SpacePlan 工作空间的套餐
+type SpacePlan struct {
+	SpaceID   string    `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	PlanID    string    `gorm:"column:plan_id;type:varchar(50);not null" json:"plan_id"`
+	UpdatedBy string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedAt time.Time `gorm:"column:updated_at;not null" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SpacePlan) TableName() string {
+	return "space_plan"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SpaceRegion 工作空间数据驻留绑定
+// 记录每个空间的动态数据表所在的区域数据库集群
+type SpaceRegion struct {
+	SpaceID          string     `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	Region           string     `gorm:"column:region;type:varchar(64);not null;index" json:"region"`
+	Status           string     `gorm:"column:status;type:varchar(20);not null;default:active" json:"status"` // active, migrating
+	TargetRegion     *string    `gorm:"column:target_region;type:varchar(64)" json:"target_region"`
+	MigrationError   *string    `gorm:"column:migration_error;type:text" json:"migration_error"`
+	CreatedTime      time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	LastModifiedTime *time.Time `gorm:"column:last_modified_time" json:"last_modified_time"`
+}
+
+// TableName 指定表名
+func (SpaceRegion) TableName() string {
+	return "space_region"
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// SpaceSubscription 工作空间的 Stripe 订阅
+type SpaceSubscription struct {
+	SpaceID           string     `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	CustomerID        string     `gorm:"column:customer_id;type:varchar(100);index" json:"customer_id"`
+	SubscriptionID    string     `gorm:"column:subscription_id;type:varchar(100);index" json:"subscription_id"`
+	PriceID           string     `gorm:"column:price_id;type:varchar(100)" json:"price_id"`
+	Status            string     `gorm:"column:status;type:varchar(30)" json:"status"`
+	PlanID            string     `gorm:"column:plan_id;type:varchar(50);not null" json:"plan_id"`
+	PendingPlanID     string     `gorm:"column:pending_plan_id;type:varchar(50)" json:"pending_plan_id"`
+	CurrentPeriodEnd  time.Time  `gorm:"column:current_period_end" json:"current_period_end"`
+	CancelAtPeriodEnd bool       `gorm:"column:cancel_at_period_end;not null;default:false" json:"cancel_at_period_end"`
+	PastDueSince      *time.Time `gorm:"column:past_due_since" json:"past_due_since"`
+	LastEventAt       time.Time  `gorm:"column:last_event_at" json:"last_event_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at;not null" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SpaceSubscription) TableName() string {
+	return "space_subscription"
+}
+
+// BillingEvent 已处理的 Stripe webhook 事件（用于去重）
+type BillingEvent struct {
+	ID          string    `gorm:"column:id;primaryKey;type:varchar(100)" json:"id"`
+	Type        string    `gorm:"column:type;type:varchar(100);not null" json:"type"`
+	ProcessedAt time.Time `gorm:"column:processed_at;not null;index" json:"processed_at"`
+}
+
+// TableName 指定表名
+func (BillingEvent) TableName() string {
+	return "billing_event"
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// StatusFlow 单选字段的状态流（允许的状态流转与审批角色）
+type StatusFlow struct {
+	ID              string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID         string    `gorm:"column:table_id;type:varchar(50);not null;index" json:"table_id"`
+	FieldID         string    `gorm:"column:field_id;type:varchar(50);not null;uniqueIndex" json:"field_id"`
+	InitialStatuses string    `gorm:"column:initial_statuses;type:text" json:"initial_statuses"` // JSON 数组
+	Transitions     string    `gorm:"column:transitions;type:text;not null" json:"transitions"`  // JSON 数组
+	Enabled         bool      `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	CreatedBy       string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	UpdatedBy       string    `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	CreatedTime     time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime     time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (StatusFlow) TableName() string {
+	return "status_flow"
+}
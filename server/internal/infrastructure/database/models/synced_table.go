@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// SyncedTable 同步表（镜像源视图的只读表）
+type SyncedTable struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	Name            string     `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	SourceBaseID    string     `gorm:"column:source_base_id;type:varchar(50);not null" json:"source_base_id"`
+	SourceTableID   string     `gorm:"column:source_table_id;type:varchar(50);not null" json:"source_table_id"`
+	SourceViewID    string     `gorm:"column:source_view_id;type:varchar(50);not null" json:"source_view_id"`
+	TargetBaseID    string     `gorm:"column:target_base_id;type:varchar(50);not null;index:idx_synced_table_target_base" json:"target_base_id"`
+	TargetTableID   string     `gorm:"column:target_table_id;type:varchar(50);not null;uniqueIndex:uniq_synced_table_target_table" json:"target_table_id"`
+	FieldMap        string     `gorm:"column:field_map;type:text" json:"field_map"` // JSON
+	DeletedFieldID  string     `gorm:"column:deleted_field_id;type:varchar(50)" json:"deleted_field_id"`
+	IntervalSeconds int64      `gorm:"column:interval_seconds;not null;default:0" json:"interval_seconds"`
+	Enabled         bool       `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Cursor          int64      `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	NextRunTime     time.Time  `gorm:"column:next_run_time;not null;index:idx_synced_table_next_run" json:"next_run_time"`
+	LastRunTime     *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastStatus      string     `gorm:"column:last_status;type:varchar(20)" json:"last_status"`
+	LastError       string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime     time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (SyncedTable) TableName() string {
+	return "synced_table"
+}
+
+// SyncedTableRow 同步表源记录与镜像记录的对应关系
+type SyncedTableRow struct {
+	SyncID         string    `gorm:"column:sync_id;primaryKey;type:varchar(50);index:idx_synced_table_row_synced,priority:1" json:"sync_id"`
+	SourceRecordID string    `gorm:"column:source_record_id;primaryKey;type:varchar(50)" json:"source_record_id"`
+	TargetRecordID string    `gorm:"column:target_record_id;type:varchar(50);not null" json:"target_record_id"`
+	SourceDeleted  bool      `gorm:"column:source_deleted;not null;default:false" json:"source_deleted"`
+	SyncedTime     time.Time `gorm:"column:synced_time;not null;index:idx_synced_table_row_synced,priority:2" json:"synced_time"`
+}
+
+// TableName 指定表名
+func (SyncedTableRow) TableName() string {
+	return "synced_table_row"
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// TableHealthReport 表健康检查报告
+type TableHealthReport struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null;index:idx_table_health_report_table" json:"table_id"`
+	Trigger      string     `gorm:"column:trigger_type;type:varchar(20);not null" json:"trigger_type"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_table_health_report_status" json:"status"`
+	Scanned      int        `gorm:"column:scanned;not null;default:0" json:"scanned"`
+	Truncated    bool       `gorm:"column:truncated;not null;default:false" json:"truncated"`
+	Counts       string     `gorm:"column:counts;type:text;not null" json:"counts"` // JSON：问题类型 → 数量
+	Fixable      int        `gorm:"column:fixable;not null;default:0" json:"fixable"`
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null;index:idx_table_health_report_table" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (TableHealthReport) TableName() string {
+	return "table_health_report"
+}
+
+// TableHealthIssue 健康检查发现的问题
+type TableHealthIssue struct {
+	ID        string `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	ReportID  string `gorm:"column:report_id;type:varchar(50);not null;index:idx_table_health_issue_report" json:"report_id"`
+	TableID   string `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	Kind      string `gorm:"column:kind;type:varchar(30);not null;index:idx_table_health_issue_report" json:"kind"`
+	FieldID   string `gorm:"column:field_id;type:varchar(50);not null" json:"field_id"`
+	FieldName string `gorm:"column:field_name;type:varchar(255)" json:"field_name"`
+	RecordID  string `gorm:"column:record_id;type:varchar(50)" json:"record_id"`
+	Value     string `gorm:"column:cell_value;type:text" json:"cell_value"` // JSON
+	Message   string `gorm:"column:message;type:text" json:"message"`
+	Fixable   bool   `gorm:"column:fixable;not null;default:false" json:"fixable"`
+	Status    string `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	FixError  string `gorm:"column:fix_error;type:text" json:"fix_error"`
+}
+
+// TableName 指定表名
+func (TableHealthIssue) TableName() string {
+	return "table_health_issue"
+}
+
+// TableHealthFixJob 健康检查一键修复任务
+type TableHealthFixJob struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	ReportID     string     `gorm:"column:report_id;type:varchar(50);not null;index:idx_table_health_fix_job_report" json:"report_id"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	IssueIDs     string     `gorm:"column:issue_ids;type:text;not null" json:"issue_ids"` // JSON 数组
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_table_health_fix_job_status" json:"status"`
+	Fixed        int        `gorm:"column:fixed;not null;default:0" json:"fixed"`
+	Skipped      int        `gorm:"column:skipped;not null;default:0" json:"skipped"`
+	Failed       int        `gorm:"column:failed;not null;default:0" json:"failed"`
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (TableHealthFixJob) TableName() string {
+	return "table_health_fix_job"
+}
+
+// TableHealthSchedule 表的定时健康检查设置
+type TableHealthSchedule struct {
+	TableID       string     `gorm:"column:table_id;primaryKey;type:varchar(50)" json:"table_id"`
+	IntervalHours int        `gorm:"column:interval_hours;not null" json:"interval_hours"`
+	Enabled       bool       `gorm:"column:enabled;not null" json:"enabled"`
+	NextRunTime   *time.Time `gorm:"column:next_run_time;index:idx_table_health_schedule_next_run" json:"next_run_time"`
+	UpdatedBy     string     `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime   time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (TableHealthSchedule) TableName() string {
+	return "table_health_schedule"
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// TextExtractionRule 附件文本提取规则（源附件字段 → 目标长文本字段）
+type TextExtractionRule struct {
+	ID            string    `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	TableID       string    `gorm:"column:table_id;type:varchar(50);not null;index:idx_text_extraction_rule_table" json:"table_id"`
+	SourceFieldID string    `gorm:"column:source_field_id;type:varchar(50);not null" json:"source_field_id"`
+	TargetFieldID string    `gorm:"column:target_field_id;type:varchar(50);not null;uniqueIndex:uq_text_extraction_rule_target" json:"target_field_id"`
+	Languages     string    `gorm:"column:languages;type:text;not null" json:"languages"` // JSON 数组
+	CreatedBy     string    `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime   time.Time `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime   time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (TextExtractionRule) TableName() string {
+	return "text_extraction_rule"
+}
+
+// TextExtractionJob 附件文本提取任务（一个任务对应一条规则下的一条记录）
+type TextExtractionJob struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	RuleID       string     `gorm:"column:rule_id;type:varchar(50);not null;index:idx_text_extraction_job_cell" json:"rule_id"`
+	TableID      string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	RecordID     string     `gorm:"column:record_id;type:varchar(50);not null;index:idx_text_extraction_job_cell" json:"record_id"`
+	Trigger      string     `gorm:"column:trigger_type;type:varchar(20);not null" json:"trigger_type"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index:idx_text_extraction_job_status" json:"status"`
+	Attempts     int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	Error        *string    `gorm:"column:error;type:text" json:"error"`
+	Engine       string     `gorm:"column:engine;type:varchar(20)" json:"engine"`
+	Files        int        `gorm:"column:files;not null;default:0" json:"files"`
+	Skipped      int        `gorm:"column:skipped;not null;default:0" json:"skipped"`
+	Chars        int        `gorm:"column:chars;not null;default:0" json:"chars"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	NextRunAt    time.Time  `gorm:"column:next_run_at;not null;index:idx_text_extraction_job_status" json:"next_run_at"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (TextExtractionJob) TableName() string {
+	return "text_extraction_job"
+}
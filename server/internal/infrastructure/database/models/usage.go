@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UsageDaily 按天汇总的工作空间用量
+type UsageDaily struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SpaceID     string    `gorm:"column:space_id;type:varchar(50);not null;uniqueIndex:uq_usage_daily,priority:1" json:"space_id"`
+	BaseID      string    `gorm:"column:base_id;type:varchar(50);not null;default:'';uniqueIndex:uq_usage_daily,priority:3" json:"base_id"`
+	Day         time.Time `gorm:"column:day;type:date;not null;uniqueIndex:uq_usage_daily,priority:2;index:idx_usage_daily_day" json:"day"`
+	Metric      string    `gorm:"column:metric;type:varchar(50);not null;uniqueIndex:uq_usage_daily,priority:4" json:"metric"`
+	Dimension   string    `gorm:"column:dimension;type:varchar(100);not null;default:'';uniqueIndex:uq_usage_daily,priority:5" json:"dimension"`
+	Value       int64     `gorm:"column:value;not null;default:0" json:"value"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (UsageDaily) TableName() string {
+	return "usage_daily"
+}
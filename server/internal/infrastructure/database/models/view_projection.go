@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// ViewProjection 视图展示投影（记录顺序以 JSON 保存）
+type ViewProjection struct {
+	ViewID      string    `gorm:"column:view_id;primaryKey;type:varchar(50)" json:"view_id"`
+	BaseID      string    `gorm:"column:base_id;type:varchar(50);not null" json:"base_id"`
+	TableID     string    `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	RecordIDs   string    `gorm:"column:record_ids;type:text" json:"record_ids"` // JSON
+	Truncated   bool      `gorm:"column:truncated;not null;default:false" json:"truncated"`
+	Cursor      int64     `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	BuiltTime   time.Time `gorm:"column:built_time;not null" json:"built_time"`
+	ReadTime    time.Time `gorm:"column:read_time;not null;index:idx_view_projection_read" json:"read_time"`
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (ViewProjection) TableName() string {
+	return "view_projection"
+}
+
+// ViewProjectionRow 视图展示投影中的一行（单元格以 JSON 保存）
+type ViewProjectionRow struct {
+	ViewID      string    `gorm:"column:view_id;primaryKey;type:varchar(50)" json:"view_id"`
+	RecordID    string    `gorm:"column:record_id;primaryKey;type:varchar(50)" json:"record_id"`
+	Cells       string    `gorm:"column:cells;type:text" json:"cells"` // JSON
+	UpdatedTime time.Time `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (ViewProjectionRow) TableName() string {
+	return "view_projection_row"
+}
+
+// ViewProjectionRef 投影行引用的关联记录（关联记录标题变化时据此找到需要刷新的行）
+type ViewProjectionRef struct {
+	ViewID      string `gorm:"column:view_id;primaryKey;type:varchar(50);index:idx_view_projection_ref,priority:1" json:"view_id"`
+	RefRecordID string `gorm:"column:ref_record_id;primaryKey;type:varchar(50);index:idx_view_projection_ref,priority:2" json:"ref_record_id"`
+	RecordID    string `gorm:"column:record_id;primaryKey;type:varchar(50)" json:"record_id"`
+}
+
+// TableName 指定表名
+func (ViewProjectionRef) TableName() string {
+	return "view_projection_ref"
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// WarehouseSyncJob 数据仓库同步任务
+type WarehouseSyncJob struct {
+	ID              string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	BaseID          string     `gorm:"column:base_id;type:varchar(50);not null;index:idx_warehouse_sync_job_base" json:"base_id"`
+	TableID         string     `gorm:"column:table_id;type:varchar(50);not null" json:"table_id"`
+	ViewID          string     `gorm:"column:view_id;type:varchar(50)" json:"view_id"`
+	Name            string     `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Destination     string     `gorm:"column:destination;type:text;not null" json:"destination"` // JSON
+	Credentials     string     `gorm:"column:credentials;type:text" json:"-"`
+	Mode            string     `gorm:"column:mode;type:varchar(20);not null" json:"mode"`
+	IntervalSeconds int64      `gorm:"column:interval_seconds;not null;default:0" json:"interval_seconds"`
+	Enabled         bool       `gorm:"column:enabled;not null;default:true" json:"enabled"`
+	Cursor          int64      `gorm:"column:cursor;not null;default:0" json:"cursor"`
+	NextRunTime     time.Time  `gorm:"column:next_run_time;not null;index:idx_warehouse_sync_job_next_run" json:"next_run_time"`
+	LastRunTime     *time.Time `gorm:"column:last_run_time" json:"last_run_time"`
+	LastStatus      string     `gorm:"column:last_status;type:varchar(20)" json:"last_status"`
+	LastError       string     `gorm:"column:last_error;type:text" json:"last_error"`
+	LockedUntil     *time.Time `gorm:"column:locked_until" json:"locked_until"`
+	CreatedBy       string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime     time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime     time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (WarehouseSyncJob) TableName() string {
+	return "warehouse_sync_job"
+}
+
+// WarehouseSyncRun 数据仓库同步运行记录
+type WarehouseSyncRun struct {
+	ID            string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	JobID         string     `gorm:"column:job_id;type:varchar(50);not null;index:idx_warehouse_sync_run_job" json:"job_id"`
+	Status        string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Full          bool       `gorm:"column:full;not null;default:false" json:"full"`
+	Upserted      int64      `gorm:"column:upserted;not null;default:0" json:"upserted"`
+	Deleted       int64      `gorm:"column:deleted;not null;default:0" json:"deleted"`
+	AddedColumns  string     `gorm:"column:added_columns;type:text" json:"added_columns"` // JSON 数组
+	Error         string     `gorm:"column:error;type:text" json:"error"`
+	CursorAtStart int64      `gorm:"column:cursor_at_start;not null;default:0" json:"cursor_at_start"`
+	StartedTime   time.Time  `gorm:"column:started_time;not null" json:"started_time"`
+	FinishedTime  *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (WarehouseSyncRun) TableName() string {
+	return "warehouse_sync_run"
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// WorkspaceLifecycle 工作空间生命周期（停用、计划删除与清除）
+type WorkspaceLifecycle struct {
+	SpaceID               string     `gorm:"column:space_id;primaryKey;type:varchar(50)" json:"space_id"`
+	State                 string     `gorm:"column:state;type:varchar(20);not null;index:idx_workspace_lifecycle_state" json:"state"`
+	Reason                string     `gorm:"column:reason;type:text" json:"reason"`
+	SuspendedTime         *time.Time `gorm:"column:suspended_time" json:"suspended_time"`
+	DeletionRequestedTime *time.Time `gorm:"column:deletion_requested_time" json:"deletion_requested_time"`
+	PurgeAfter            *time.Time `gorm:"column:purge_after" json:"purge_after"`
+	PurgeBundleID         string     `gorm:"column:purge_bundle_id;type:varchar(50)" json:"purge_bundle_id"`
+	PurgeReport           string     `gorm:"column:purge_report;type:text" json:"purge_report"` // JSON
+	PurgedTime            *time.Time `gorm:"column:purged_time" json:"purged_time"`
+	LastError             string     `gorm:"column:last_error;type:text" json:"last_error"`
+	UpdatedBy             string     `gorm:"column:updated_by;type:varchar(50)" json:"updated_by"`
+	UpdatedTime           time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+}
+
+// TableName 指定表名
+func (WorkspaceLifecycle) TableName() string {
+	return "workspace_lifecycle"
+}
+
+// WorkspaceExportBundle 工作空间完整导出包
+type WorkspaceExportBundle struct {
+	ID           string     `gorm:"column:id;primaryKey;type:varchar(50)" json:"id"`
+	SpaceID      string     `gorm:"column:space_id;type:varchar(50);not null;index:idx_workspace_export_bundle_space" json:"space_id"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Manifest     string     `gorm:"column:manifest;type:text" json:"manifest"` // JSON，完成后写入
+	Error        string     `gorm:"column:error;type:text" json:"error"`
+	CreatedBy    string     `gorm:"column:created_by;type:varchar(50)" json:"created_by"`
+	CreatedTime  time.Time  `gorm:"column:created_time;not null" json:"created_time"`
+	UpdatedTime  time.Time  `gorm:"column:updated_time;not null" json:"updated_time"`
+	FinishedTime *time.Time `gorm:"column:finished_time" json:"finished_time"`
+}
+
+// TableName 指定表名
+func (WorkspaceExportBundle) TableName() string {
+	return "workspace_export_bundle"
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// builtinOnlineMigrations 内置的元数据表在线迁移
+// 新增迁移时版本号递增；需要删除旧列或旧表时拆成两个迁移：扩展（加新结构并回填，新旧代码都能运行）
+// 与收缩（删除旧结构，所有实例升级后执行），不要在一个迁移里同时做
+func builtinOnlineMigrations() []OnlineMigration {
+	return []OnlineMigration{
+		{
+			Version: 1,
+			Name:    "supplementary_indexes",
+			Phase:   PhaseExpand,
+			// GORM AutoMigrate 不会自动创建的复合索引和唯一约束
+			Indexes: []OnlineIndex{
+				{Name: "uq_oauth_authorized_client_user", Table: "oauth_app_authorized", Columns: "client_id, user_id", Unique: true},
+				{Name: "uq_reference_to_from", Table: "reference", Columns: "to_field_id, from_field_id", Unique: true},
+				{Name: "uq_task_reference_to_from", Table: "task_reference", Columns: "to_field_id, from_field_id", Unique: true},
+				{Name: "uq_collab_rt_rid_pid_pt", Table: "collaborator", Columns: "principal_id, principal_type, resource_id, resource_type", Unique: true},
+				{Name: "uq_ops_collection_docid_version", Table: "ops", Columns: "collection, doc_id, version", Unique: true},
+				{Name: "idx_ops_collection_created_time", Table: "ops", Columns: "collection, created_time"},
+				{Name: "idx_comment_record_table", Table: "comment", Columns: "record_id, table_id"},
+				{Name: "uq_comment_subscription", Table: "comment_subscription", Columns: "table_id, record_id", Unique: true},
+				{Name: "idx_record_history_table_record_created", Table: "record_history", Columns: "table_id, record_id, created_time"},
+				{Name: "idx_record_history_table_created", Table: "record_history", Columns: "table_id, created_time"},
+				{Name: "idx_record_trash_table_record", Table: "record_trash", Columns: "table_id, record_id"},
+				{Name: "idx_record_changes_table_changed", Table: "record_changes", Columns: "table_id, changed_at"},
+				{Name: "idx_change_event_base_seq", Table: "change_event", Columns: "base_id, seq"},
+				{Name: "idx_change_event_pending", Table: "change_event", Columns: "created_time, id", Where: "seq IS NULL"},
+				{Name: "idx_attachments_table_field", Table: "attachments_table", Columns: "table_id, field_id"},
+				{Name: "idx_attachments_table_record_field", Table: "attachments_table", Columns: "record_id, table_id, field_id"},
+			},
+		},
+		{
+			Version: 2,
+			Name:    "field_api_key",
+			Phase:   PhaseExpand,
+			// 字段的稳定 API 键：可空列（只改元数据，不重写表），回填后由应用层在创建字段时生成
+			Up: func(ctx context.Context, tx *gorm.DB) error {
+				return tx.Exec(`ALTER TABLE field ADD COLUMN IF NOT EXISTS api_key VARCHAR(64)`).Error
+			},
+			Indexes: []OnlineIndex{
+				{Name: "uq_field_table_api_key", Table: "field", Columns: "table_id, api_key", Where: "deleted_time IS NULL AND api_key IS NOT NULL", Unique: true},
+			},
+			Backfill: backfillFieldAPIKeys,
+		},
+		{
+			Version: 3,
+			Name:    "drop_integration_delivery_response_body",
+			Phase:   PhaseContract,
+			// 失败推送不再保存目标的响应体（推送地址由订阅者指定），删除已保存的内容
+			Up: func(ctx context.Context, tx *gorm.DB) error {
+				return tx.Exec(`ALTER TABLE integration_delivery DROP COLUMN IF EXISTS response_body`).Error
+			},
+		},
+	}
+}
+
+// backfillFieldAPIKeys 为升级前创建的字段生成 API 键（每批处理至多 batchSize 张表，按字段顺序分配，冲突时追加序号）
+func backfillFieldAPIKeys(ctx context.Context, db *gorm.DB, batchSize int) (int64, error) {
+	var tableIDs []string
+	if err := db.WithContext(ctx).Raw(`SELECT DISTINCT table_id FROM field
+		WHERE api_key IS NULL AND deleted_time IS NULL LIMIT ?`, batchSize).Scan(&tableIDs).Error; err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, tableID := range tableIDs {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var rows []struct {
+				ID     string  `gorm:"column:id"`
+				Name   string  `gorm:"column:name"`
+				APIKey *string `gorm:"column:api_key"`
+			}
+			if err := tx.Raw(`SELECT id, name, api_key FROM field
+				WHERE table_id = ? AND deleted_time IS NULL
+				ORDER BY field_order, created_time, id FOR UPDATE`, tableID).Scan(&rows).Error; err != nil {
+				return err
+			}
+			taken := make(map[string]bool, len(rows))
+			for _, row := range rows {
+				if row.APIKey != nil {
+					taken[*row.APIKey] = true
+				}
+			}
+			for _, row := range rows {
+				if row.APIKey != nil {
+					continue
+				}
+				key := valueobject.GenerateAPIKey(row.Name, func(k string) bool { return taken[k] }).String()
+				taken[key] = true
+				if err := tx.Exec(`UPDATE field SET api_key = ? WHERE id = ?`, key, row.ID).Error; err != nil {
+					return err
+				}
+				total++
+			}
+			return nil
+		})
+		if err != nil {
+			return total, fmt.Errorf("表 %s: %w", tableID, err)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,475 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// onlineMigrationLockKey 在线迁移的 advisory lock（滚动部署时多个实例同时启动，只有一个执行迁移）
+const onlineMigrationLockKey int64 = 0x6c75636b6d696772 // "luckmigr"
+
+// onlineMigrationTable 在线迁移的版本记录表
+const onlineMigrationTable = "__online_migrations"
+
+// MigrationPhase 迁移阶段
+type MigrationPhase string
+
+const (
+	// PhaseExpand 扩展：只做向后兼容的变更（加表、加可空列、在线建索引、回填），新旧版本代码可以同时运行，
+	// 新版本启动时自动执行
+	PhaseExpand MigrationPhase = "expand"
+	// PhaseContract 收缩：删除旧列、旧表或加约束，旧版本代码会失败，只能在所有实例都升级后显式执行
+	PhaseContract MigrationPhase = "contract"
+)
+
+// 在线迁移状态
+const (
+	OnlineMigrationPending = "pending"
+	OnlineMigrationRunning = "running"
+	OnlineMigrationApplied = "applied"
+	OnlineMigrationFailed  = "failed"
+)
+
+// OnlineIndex 在线创建的索引（CREATE INDEX CONCURRENTLY，建索引期间不阻塞写入）
+type OnlineIndex struct {
+	Name    string
+	Table   string
+	Columns string // 索引列，如 "table_id, created_time"
+	Where   string // 部分索引条件，可为空
+	Unique  bool
+}
+
+// createSQL 在线建索引的语句
+func (i OnlineIndex) createSQL() string {
+	unique := ""
+	if i.Unique {
+		unique = "UNIQUE "
+	}
+	sql := fmt.Sprintf(`CREATE %sINDEX CONCURRENTLY IF NOT EXISTS "%s" ON "%s" (%s)`, unique, i.Name, i.Table, i.Columns)
+	if i.Where != "" {
+		sql += " WHERE " + i.Where
+	}
+	return sql
+}
+
+// OnlineMigration 元数据表的在线迁移
+// 执行顺序：Up（事务内，受 lock_timeout 限制，拿不到锁时退避重试）→ Indexes（事务外在线创建）→ Backfill（分批回填）；
+// 三步都必须可重复执行，中断或失败后下次从头重跑
+type OnlineMigration struct {
+	Version int
+	Name    string
+	Phase   MigrationPhase
+	// Up 结构变更（可为空）
+	Up func(ctx context.Context, tx *gorm.DB) error
+	// Indexes 在线创建的索引
+	Indexes []OnlineIndex
+	// Backfill 分批回填：每次至多处理 batchSize 行并返回处理的行数，返回 0 时结束（可为空）
+	Backfill func(ctx context.Context, db *gorm.DB, batchSize int) (int64, error)
+}
+
+// ID 迁移标识
+func (m OnlineMigration) ID() string {
+	return fmt.Sprintf("%d_%s", m.Version, m.Name)
+}
+
+// OnlineMigrationRecord 单个迁移的状态
+type OnlineMigrationRecord struct {
+	Version        int            `json:"version"`
+	Name           string         `json:"name"`
+	Phase          MigrationPhase `json:"phase"`
+	Status         string         `json:"status"`
+	StartedAt      *time.Time     `json:"started_at,omitempty"`
+	FinishedAt     *time.Time     `json:"finished_at,omitempty"`
+	BackfilledRows int64          `json:"backfilled_rows"`
+	Error          string         `json:"error,omitempty"`
+}
+
+// InvalidIndex 在线建索引中断后遗留的无效索引（下次执行对应迁移时删除重建）
+type InvalidIndex struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+}
+
+// OnlineMigrationStatus 在线迁移状态
+type OnlineMigrationStatus struct {
+	LatestVersion   int                      `json:"latest_version"`   // 当前代码已知的最新迁移
+	AppliedVersion  int                      `json:"applied_version"`  // 已应用的最大版本
+	PendingExpand   int                      `json:"pending_expand"`   // 待执行的扩展迁移（当前代码可能依赖，非 0 时应尽快执行）
+	PendingContract int                      `json:"pending_contract"` // 待执行的收缩迁移（所有实例升级后执行）
+	Compatible      bool                     `json:"compatible"`       // 当前代码依赖的扩展迁移均已应用
+	Running         bool                     `json:"running"`          // 是否有实例正在执行迁移
+	Migrations      []*OnlineMigrationRecord `json:"migrations"`       // 按版本排列，含数据库中有、当前代码未知的迁移
+	InvalidIndexes  []InvalidIndex           `json:"invalid_indexes"`  // 中断遗留的无效索引
+	UnknownVersions []int                    `json:"unknown_versions"` // 数据库中已应用、当前代码未知的版本（更新的实例已执行过迁移）
+}
+
+// OnlineMigrationResult 一次执行的结果
+type OnlineMigrationResult struct {
+	Applied []string `json:"applied"`
+	Skipped bool     `json:"skipped"` // 其他实例正在执行
+	Error   string   `json:"error,omitempty"`
+}
+
+// OnlineMigrator 元数据表在线迁移执行器（扩展/收缩模式）
+type OnlineMigrator struct {
+	db         *gorm.DB
+	cfg        config.OnlineMigrationConfig
+	migrations []OnlineMigration
+}
+
+// NewOnlineMigrator 创建在线迁移执行器（内置迁移已注册）
+func NewOnlineMigrator(db *gorm.DB, cfg config.OnlineMigrationConfig) *OnlineMigrator {
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = 5 * time.Second
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 5
+	}
+	if cfg.BackfillBatchSize <= 0 {
+		cfg.BackfillBatchSize = 1000
+	}
+	m := &OnlineMigrator{db: db, cfg: cfg}
+	for _, migration := range builtinOnlineMigrations() {
+		m.Register(migration)
+	}
+	return m
+}
+
+// Register 注册在线迁移
+func (m *OnlineMigrator) Register(migration OnlineMigration) {
+	m.migrations = append(m.migrations, migration)
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+}
+
+// Run 执行待应用的迁移；contract 为 false 时只执行扩展迁移
+// 通过 advisory lock 保证同一时刻只有一个实例执行，拿不到锁时直接返回（Skipped）
+func (m *OnlineMigrator) Run(ctx context.Context, contract bool) (*OnlineMigrationResult, error) {
+	result := &OnlineMigrationResult{Applied: []string{}}
+
+	err := m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var locked bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", onlineMigrationLockKey).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("获取迁移锁失败: %w", err)
+		}
+		if !locked {
+			result.Skipped = true
+			return nil
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", onlineMigrationLockKey)
+
+		if err := m.ensureTable(conn); err != nil {
+			return err
+		}
+		records, err := m.loadRecords(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range planOnlineMigrations(m.migrations, records, contract) {
+			if err := m.apply(ctx, conn, migration); err != nil {
+				result.Error = fmt.Sprintf("迁移 %s 失败: %v", migration.ID(), err)
+				return nil
+			}
+			result.Applied = append(result.Applied, migration.ID())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// planOnlineMigrations 本次需要执行的迁移（按版本顺序）
+// 已应用的跳过；收缩迁移只在 contract 时执行，且之前的迁移必须全部已应用或本次会执行
+func planOnlineMigrations(migrations []OnlineMigration, records map[int]*OnlineMigrationRecord, contract bool) []OnlineMigration {
+	var plan []OnlineMigration
+	blocked := false
+	for _, migration := range migrations {
+		if r := records[migration.Version]; r != nil && r.Status == OnlineMigrationApplied {
+			continue
+		}
+		if migration.Phase == PhaseContract {
+			if !contract || blocked {
+				blocked = true // 之后的收缩迁移依赖这次收缩
+				continue
+			}
+		}
+		plan = append(plan, migration)
+	}
+	return plan
+}
+
+// apply 执行单个迁移并记录状态
+func (m *OnlineMigrator) apply(ctx context.Context, conn *gorm.DB, migration OnlineMigration) error {
+	started := time.Now()
+	if err := conn.Exec(fmt.Sprintf(`INSERT INTO "%s" (version, name, phase, status, started_time, backfilled_rows, error)
+		VALUES (?, ?, ?, ?, ?, 0, '')
+		ON CONFLICT (version) DO UPDATE SET status = EXCLUDED.status, started_time = EXCLUDED.started_time, finished_time = NULL, error = ''`,
+		onlineMigrationTable),
+		migration.Version, migration.Name, string(migration.Phase), OnlineMigrationRunning, started).Error; err != nil {
+		return fmt.Errorf("记录迁移状态失败: %w", err)
+	}
+
+	logger.Info("开始在线迁移",
+		logger.String("migration", migration.ID()),
+		logger.String("phase", string(migration.Phase)))
+
+	backfilled, err := m.execute(ctx, conn, migration)
+	status, message := OnlineMigrationApplied, ""
+	if err != nil {
+		status, message = OnlineMigrationFailed, err.Error()
+	}
+	if recErr := conn.Exec(fmt.Sprintf(`UPDATE "%s" SET status = ?, finished_time = ?, backfilled_rows = ?, error = ? WHERE version = ?`, onlineMigrationTable),
+		status, time.Now(), backfilled, message, migration.Version).Error; recErr != nil && err == nil {
+		err = fmt.Errorf("记录迁移状态失败: %w", recErr)
+	}
+
+	if err != nil {
+		logger.Error("在线迁移失败",
+			logger.String("migration", migration.ID()),
+			logger.ErrorField(err))
+		return err
+	}
+	logger.Info("✅ 在线迁移已应用",
+		logger.String("migration", migration.ID()),
+		logger.Int64("backfilled_rows", backfilled),
+		logger.Duration("duration", time.Since(started)))
+	return nil
+}
+
+// execute 依次执行结构变更、在线建索引与分批回填，返回回填的行数
+func (m *OnlineMigrator) execute(ctx context.Context, conn *gorm.DB, migration OnlineMigration) (int64, error) {
+	if migration.Up != nil {
+		if err := m.withLockRetry(ctx, func() error {
+			return conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(fmt.Sprintf("SET LOCAL lock_timeout = '%dms'", m.cfg.LockTimeout.Milliseconds())).Error; err != nil {
+					return err
+				}
+				return migration.Up(ctx, tx)
+			})
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, index := range migration.Indexes {
+		if err := m.createIndex(ctx, conn, index); err != nil {
+			return 0, err
+		}
+	}
+
+	var total int64
+	if migration.Backfill != nil {
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+			n, err := migration.Backfill(ctx, conn, m.cfg.BackfillBatchSize)
+			if err != nil {
+				return total, fmt.Errorf("回填失败（已回填 %d 行）: %w", total, err)
+			}
+			if n == 0 {
+				break
+			}
+			total += n
+			if m.cfg.BackfillPause > 0 {
+				time.Sleep(m.cfg.BackfillPause) // 让出数据库，避免回填挤占业务查询
+			}
+		}
+	}
+	return total, nil
+}
+
+// createIndex 在线建索引；之前中断遗留的无效索引先删除再重建
+func (m *OnlineMigrator) createIndex(ctx context.Context, conn *gorm.DB, index OnlineIndex) error {
+	var invalid bool
+	if err := conn.Raw(`SELECT EXISTS (
+		SELECT 1 FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE c.relname = ? AND NOT i.indisvalid)`, index.Name).Scan(&invalid).Error; err != nil {
+		return fmt.Errorf("检查索引 %s 失败: %w", index.Name, err)
+	}
+	if invalid {
+		logger.Warn("删除中断遗留的无效索引", logger.String("index", index.Name))
+		if err := conn.Exec(fmt.Sprintf(`DROP INDEX CONCURRENTLY IF EXISTS "%s"`, index.Name)).Error; err != nil {
+			return fmt.Errorf("删除无效索引 %s 失败: %w", index.Name, err)
+		}
+	}
+
+	return m.withLockRetry(ctx, func() error {
+		if err := conn.Exec(index.createSQL()).Error; err != nil {
+			return fmt.Errorf("创建索引 %s 失败: %w", index.Name, err)
+		}
+		return nil
+	})
+}
+
+// withLockRetry 拿不到锁（lock_timeout）时指数退避重试，避免 DDL 长时间排队阻塞业务查询
+func (m *OnlineMigrator) withLockRetry(ctx context.Context, fn func() error) error {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !isLockTimeout(err) || attempt >= m.cfg.MaxRetries {
+			return err
+		}
+		logger.Warn("迁移等待锁超时，稍后重试",
+			logger.Int("attempt", attempt),
+			logger.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isLockTimeout 是否为 lock_timeout 导致的失败（SQLSTATE 55P03）
+func isLockTimeout(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "55P03"
+}
+
+// Status 在线迁移状态
+func (m *OnlineMigrator) Status(ctx context.Context) (*OnlineMigrationStatus, error) {
+	conn := m.db.WithContext(ctx)
+	if err := m.ensureTable(conn); err != nil {
+		return nil, err
+	}
+	records, err := m.loadRecords(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	status := buildOnlineMigrationStatus(m.migrations, records)
+
+	var holders int64
+	if err := conn.Raw(`SELECT COUNT(*) FROM pg_locks
+		WHERE locktype = 'advisory' AND granted AND ((classid::bigint << 32) | objid::bigint) = ?`, onlineMigrationLockKey).
+		Scan(&holders).Error; err != nil {
+		return nil, fmt.Errorf("检查迁移锁失败: %w", err)
+	}
+	status.Running = holders > 0
+
+	if err := conn.Raw(`SELECT c.relname AS name, t.relname AS "table"
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE NOT i.indisvalid AND n.nspname = 'public'
+		ORDER BY c.relname`).Scan(&status.InvalidIndexes).Error; err != nil {
+		return nil, fmt.Errorf("检查无效索引失败: %w", err)
+	}
+	return status, nil
+}
+
+// buildOnlineMigrationStatus 合并已注册的迁移与数据库中的记录
+func buildOnlineMigrationStatus(migrations []OnlineMigration, records map[int]*OnlineMigrationRecord) *OnlineMigrationStatus {
+	status := &OnlineMigrationStatus{
+		Compatible:      true,
+		Migrations:      make([]*OnlineMigrationRecord, 0, len(migrations)),
+		InvalidIndexes:  []InvalidIndex{},
+		UnknownVersions: []int{},
+	}
+	known := make(map[int]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		if migration.Version > status.LatestVersion {
+			status.LatestVersion = migration.Version
+		}
+		record := records[migration.Version]
+		if record == nil {
+			record = &OnlineMigrationRecord{
+				Version: migration.Version,
+				Name:    migration.Name,
+				Phase:   migration.Phase,
+				Status:  OnlineMigrationPending,
+			}
+		}
+		if record.Status != OnlineMigrationApplied {
+			if migration.Phase == PhaseContract {
+				status.PendingContract++
+			} else {
+				status.PendingExpand++
+				status.Compatible = false
+			}
+		}
+		status.Migrations = append(status.Migrations, record)
+	}
+
+	for version, record := range records {
+		if record.Status == OnlineMigrationApplied && version > status.AppliedVersion {
+			status.AppliedVersion = version
+		}
+		if !known[version] {
+			status.UnknownVersions = append(status.UnknownVersions, version)
+			status.Migrations = append(status.Migrations, record)
+		}
+	}
+	sort.Ints(status.UnknownVersions)
+	sort.Slice(status.Migrations, func(i, j int) bool {
+		return status.Migrations[i].Version < status.Migrations[j].Version
+	})
+	return status
+}
+
+// ensureTable 创建版本记录表
+func (m *OnlineMigrator) ensureTable(conn *gorm.DB) error {
+	createSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS "%s" (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		phase VARCHAR(20) NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		started_time TIMESTAMP,
+		finished_time TIMESTAMP,
+		backfilled_rows BIGINT NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT ''
+	)`, onlineMigrationTable)
+	if err := conn.Exec(createSQL).Error; err != nil {
+		return fmt.Errorf("创建迁移版本表失败: %w", err)
+	}
+	return nil
+}
+
+// loadRecords 读取迁移记录
+func (m *OnlineMigrator) loadRecords(conn *gorm.DB) (map[int]*OnlineMigrationRecord, error) {
+	var rows []struct {
+		Version        int        `gorm:"column:version"`
+		Name           string     `gorm:"column:name"`
+		Phase          string     `gorm:"column:phase"`
+		Status         string     `gorm:"column:status"`
+		StartedTime    *time.Time `gorm:"column:started_time"`
+		FinishedTime   *time.Time `gorm:"column:finished_time"`
+		BackfilledRows int64      `gorm:"column:backfilled_rows"`
+		Error          string     `gorm:"column:error"`
+	}
+	if err := conn.Table(onlineMigrationTable).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+	}
+	records := make(map[int]*OnlineMigrationRecord, len(rows))
+	for _, row := range rows {
+		records[row.Version] = &OnlineMigrationRecord{
+			Version:        row.Version,
+			Name:           row.Name,
+			Phase:          MigrationPhase(row.Phase),
+			Status:         row.Status,
+			StartedAt:      row.StartedTime,
+			FinishedAt:     row.FinishedTime,
+			BackfilledRows: row.BackfilledRows,
+			Error:          strings.TrimSpace(row.Error),
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestOnlineIndexCreateSQL(t *testing.T) {
+	cases := []struct {
+		index OnlineIndex
+		want  string
+	}{
+		{
+			OnlineIndex{Name: "idx_a", Table: "t", Columns: "a, b"},
+			`CREATE INDEX CONCURRENTLY IF NOT EXISTS "idx_a" ON "t" (a, b)`,
+		},
+		{
+			OnlineIndex{Name: "uq_a", Table: "t", Columns: "a", Unique: true},
+			`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "uq_a" ON "t" (a)`,
+		},
+		{
+			OnlineIndex{Name: "idx_p", Table: "t", Columns: "created_time, id", Where: "seq IS NULL"},
+			`CREATE INDEX CONCURRENTLY IF NOT EXISTS "idx_p" ON "t" (created_time, id) WHERE seq IS NULL`,
+		},
+	}
+	for _, c := range cases {
+		if got := c.index.createSQL(); got != c.want {
+			t.Errorf("createSQL() = %q; want %q", got, c.want)
+		}
+	}
+}
+
+func TestPlanOnlineMigrations(t *testing.T) {
+	migrations := []OnlineMigration{
+		{Version: 1, Name: "a", Phase: PhaseExpand},
+		{Version: 2, Name: "b", Phase: PhaseContract},
+		{Version: 3, Name: "c", Phase: PhaseExpand},
+		{Version: 4, Name: "d", Phase: PhaseContract},
+	}
+	ids := func(plan []OnlineMigration) string {
+		out := ""
+		for _, m := range plan {
+			out += m.Name
+		}
+		return out
+	}
+
+	if got := ids(planOnlineMigrations(migrations, nil, false)); got != "ac" {
+		t.Errorf("不执行收缩时应只执行扩展迁移，得到 %q", got)
+	}
+	if got := ids(planOnlineMigrations(migrations, nil, true)); got != "abcd" {
+		t.Errorf("执行收缩时应按版本全部执行，得到 %q", got)
+	}
+
+	records := map[int]*OnlineMigrationRecord{
+		1: {Version: 1, Status: OnlineMigrationApplied},
+		2: {Version: 2, Status: OnlineMigrationApplied},
+		3: {Version: 3, Status: OnlineMigrationFailed},
+	}
+	if got := ids(planOnlineMigrations(migrations, records, true)); got != "cd" {
+		t.Errorf("已应用的应跳过，失败的应重试，得到 %q", got)
+	}
+
+	// 收缩迁移 2 未执行时，之后的收缩迁移 4 也不能执行
+	records = map[int]*OnlineMigrationRecord{1: {Version: 1, Status: OnlineMigrationApplied}}
+	if got := ids(planOnlineMigrations(migrations, records, false)); got != "c" {
+		t.Errorf("得到 %q", got)
+	}
+}
+
+func TestBuildOnlineMigrationStatus(t *testing.T) {
+	migrations := []OnlineMigration{
+		{Version: 1, Name: "a", Phase: PhaseExpand},
+		{Version: 2, Name: "b", Phase: PhaseContract},
+	}
+
+	status := buildOnlineMigrationStatus(migrations, map[int]*OnlineMigrationRecord{})
+	if status.Compatible || status.PendingExpand != 1 || status.PendingContract != 1 || status.LatestVersion != 2 {
+		t.Errorf("全新数据库状态不正确: %+v", status)
+	}
+
+	status = buildOnlineMigrationStatus(migrations, map[int]*OnlineMigrationRecord{
+		1: {Version: 1, Name: "a", Phase: PhaseExpand, Status: OnlineMigrationApplied},
+		5: {Version: 5, Name: "newer", Phase: PhaseExpand, Status: OnlineMigrationApplied},
+	})
+	if !status.Compatible || status.PendingExpand != 0 || status.PendingContract != 1 {
+		t.Errorf("扩展迁移已应用时应兼容: %+v", status)
+	}
+	if status.AppliedVersion != 5 || len(status.UnknownVersions) != 1 || status.UnknownVersions[0] != 5 {
+		t.Errorf("应报告当前代码未知的版本: %+v", status)
+	}
+	if len(status.Migrations) != 3 || status.Migrations[1].Status != OnlineMigrationPending || status.Migrations[2].Version != 5 {
+		t.Errorf("迁移列表应按版本排列并包含未执行的迁移: %+v", status.Migrations)
+	}
+}
+
+func TestIsLockTimeout(t *testing.T) {
+	if !isLockTimeout(fmt.Errorf("wrap: %w", &pgconn.PgError{Code: "55P03"})) {
+		t.Error("55P03 应识别为等待锁超时")
+	}
+	if isLockTimeout(&pgconn.PgError{Code: "23505"}) || isLockTimeout(fmt.Errorf("other")) {
+		t.Error("其他错误不应识别为等待锁超时")
+	}
+}
+
+func TestBuiltinOnlineMigrations(t *testing.T) {
+	seen := map[int]bool{}
+	names := map[string]bool{}
+	for _, m := range builtinOnlineMigrations() {
+		if seen[m.Version] {
+			t.Errorf("迁移版本 %d 重复", m.Version)
+		}
+		seen[m.Version] = true
+		if m.Phase != PhaseExpand && m.Phase != PhaseContract {
+			t.Errorf("迁移 %s 的阶段无效", m.ID())
+		}
+		for _, idx := range m.Indexes {
+			if names[idx.Name] {
+				t.Errorf("索引 %s 重复", idx.Name)
+			}
+			names[idx.Name] = true
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/easyspace-ai/luckdb/server/pkg/fieldplugin"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
 
@@ -174,12 +175,14 @@ func (p *PostgresProvider) AlterColumn(ctx context.Context, schemaName, tableNam
 	fullTableName := fmt.Sprintf("%s.%s", p.quoteIdentifier(schemaName), p.quoteIdentifier(tableName))
 	quotedColumn := p.quoteIdentifier(columnName)
 
-	// 1. 修改列类型
+	// 1. 修改列类型（显式 USING，无法隐式转换的类型同样按值转换，值不合法时报错）
 	if newDef.Type != "" {
-		sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s",
+		sql := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s",
 			fullTableName,
 			quotedColumn,
 			newDef.Type,
+			quotedColumn,
+			newDef.Type,
 		)
 		if err := p.db.WithContext(ctx).Exec(sql).Error; err != nil {
 			return fmt.Errorf("修改列类型失败: %w", err)
@@ -306,6 +309,22 @@ func (p *PostgresProvider) AddCheckConstraint(ctx context.Context, schemaName, t
 	return nil
 }
 
+// CreateIndex 创建索引（不存在时）
+func (p *PostgresProvider) CreateIndex(ctx context.Context, schemaName, tableName, indexName, definition string) error {
+	fullTableName := fmt.Sprintf("%s.%s", p.quoteIdentifier(schemaName), p.quoteIdentifier(tableName))
+	sql := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s %s",
+		p.quoteIdentifier(indexName),
+		fullTableName,
+		definition,
+	)
+
+	if err := p.db.WithContext(ctx).Exec(sql).Error; err != nil {
+		return fmt.Errorf("创建索引失败: %w", err)
+	}
+
+	return nil
+}
+
 // ==================== 工具方法 ====================
 
 // GenerateTableName 生成完整的表名
@@ -314,11 +333,20 @@ func (p *PostgresProvider) GenerateTableName(baseID, tableID string) string {
 	return fmt.Sprintf("%s.%s", baseID, tableID)
 }
 
+// ResolveTableName 生成完整表名（Schema即baseID，无需解析）
+func (p *PostgresProvider) ResolveTableName(_ context.Context, baseID, tableID string) (string, error) {
+	return p.GenerateTableName(baseID, tableID), nil
+}
+
 // MapFieldTypeToDBType 将字段类型映射到数据库类型
 func (p *PostgresProvider) MapFieldTypeToDBType(fieldType string) string {
 	if dbType, ok := FieldTypeMapping[fieldType]; ok {
 		return dbType
 	}
+	// ✨ 自定义字段类型由插件决定列类型
+	if ft, ok := fieldplugin.Lookup(fieldType); ok {
+		return string(ft.Storage())
+	}
 	// 默认类型
 	return "TEXT"
 }
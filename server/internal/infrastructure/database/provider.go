@@ -61,6 +61,9 @@ type DBProvider interface {
 	// AddCheckConstraint 添加CHECK约束
 	AddCheckConstraint(ctx context.Context, schemaName, tableName, constraintName, checkExpression string) error
 
+	// CreateIndex 创建索引（不存在时），definition 为 ON 表名之后的部分，如 USING GIN (col jsonb_path_ops)
+	CreateIndex(ctx context.Context, schemaName, tableName, indexName, definition string) error
+
 	// ==================== 工具方法 ====================
 
 	// GenerateTableName 生成完整的表名
@@ -68,6 +71,9 @@ type DBProvider interface {
 	// SQLite: schemaName_tableName
 	GenerateTableName(baseID, tableID string) string
 
+	// ResolveTableName 生成完整的表名，需要解析所属Schema时使用请求上下文，解析失败时返回错误
+	ResolveTableName(ctx context.Context, baseID, tableID string) (string, error)
+
 	// MapFieldTypeToDBType 将字段类型映射到数据库类型
 	// 例如：singleLineText -> VARCHAR(255), number -> NUMERIC
 	MapFieldTypeToDBType(fieldType string) string
@@ -106,12 +112,15 @@ var FieldTypeMapping = map[string]string{
 	// 文本类型
 	"singleLineText": "VARCHAR(255)",
 	"longText":       "TEXT",
+	"richText":       "TEXT", // 净化后的 HTML
 
 	// 数字类型
 	"number":   "NUMERIC",
 	"rating":   "INTEGER",
 	"percent":  "NUMERIC",
 	"currency": "NUMERIC",
+	"duration": "NUMERIC", // 秒数
+	"progress": "NUMERIC", // 0-100
 
 	// 日期时间类型
 	"date":             "TIMESTAMP",
@@ -136,6 +145,9 @@ var FieldTypeMapping = map[string]string{
 	// 关联类型
 	"link": "JSONB", // 存储关联记录ID数组
 
+	// 地理位置
+	"location": "JSONB", // 存储经纬度与地址，半径查询使用 earthdistance 表达式索引
+
 	// 计算类型（存储计算结果）
 	"formula":    "TEXT",    // 公式结果（可能是任意类型，用TEXT存储）
 	"rollup":     "NUMERIC", // 聚合结果
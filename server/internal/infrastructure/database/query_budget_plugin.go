@@ -0,0 +1,295 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/querybudget"
+)
+
+const (
+	queryBudgetTimeoutKey = "luckdb:query_budget_timeout"
+
+	// planCheckTimeout 估算行数与 EXPLAIN 的超时（超时后放行查询）
+	planCheckTimeout = 2 * time.Second
+)
+
+var queryBudgetLog = logger.Named("database.query_budget")
+
+// quotedIdentifierRegexp 语句中带引号的标识符（GORM 引用的列名）
+var quotedIdentifierRegexp = regexp.MustCompile(`"([A-Za-z_][A-Za-z0-9_]*)"`)
+
+// identifierRegexp 执行计划表达式中的标识符
+var identifierRegexp = regexp.MustCompile(`"?([A-Za-z_][A-Za-z0-9_]*)"?`)
+
+// QueryBudgetPlugin GORM 查询预算插件 ✨
+// 只处理带预算的读取（HTTP 中间件按路由为请求附加预算，后台任务的查询不受限制）：
+//   - 按预算为语句设置执行超时，超时转换为"查询开销过大"错误；
+//   - 动态表的估算行数超过扫描或排序上限时执行 EXPLAIN（不带 ANALYZE），过滤条件无法使用索引、
+//     需要全表扫描超过扫描上限的表，或无法使用索引的排序超过排序上限时拒绝执行，并给出建立索引或增加过滤的建议。
+//     不带过滤条件的全表读取（如整表计数）只受语句超时限制。
+//
+// 行数低于上限的表不检查执行计划；执行计划只在 PostgreSQL 上检查，检查失败时放行查询
+type QueryBudgetPlugin struct {
+	cfg      config.QueryBudgetConfig
+	postgres bool
+
+	mu        sync.Mutex
+	estimates map[string]tableEstimate // "schema.table" -> 行数估算
+}
+
+// tableEstimate 表行数估算（来自 pg_class.reltuples）
+type tableEstimate struct {
+	rows int64
+	at   time.Time
+}
+
+// queryBudgetTimeout 语句超时前的上下文，用于判断超时是否由预算引起
+type queryBudgetTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+	budget querybudget.Budget
+}
+
+// planNode EXPLAIN (FORMAT JSON) 的计划节点
+type planNode struct {
+	NodeType string     `json:"Node Type"`
+	Relation string     `json:"Relation Name"`
+	PlanRows float64    `json:"Plan Rows"`
+	Filter   string     `json:"Filter"`
+	SortKey  []string   `json:"Sort Key"`
+	Plans    []planNode `json:"Plans"`
+}
+
+// NewQueryBudgetPlugin 创建查询预算插件
+func NewQueryBudgetPlugin(cfg config.QueryBudgetConfig) *QueryBudgetPlugin {
+	if cfg.EstimateTTL <= 0 {
+		cfg.EstimateTTL = 5 * time.Minute
+	}
+	return &QueryBudgetPlugin{cfg: cfg, estimates: make(map[string]tableEstimate)}
+}
+
+// Name 插件名称
+func (p *QueryBudgetPlugin) Name() string {
+	return "luckdb:query_budget"
+}
+
+// Initialize 注册 GORM 回调
+func (p *QueryBudgetPlugin) Initialize(db *gorm.DB) error {
+	p.postgres = db.Dialector.Name() == "postgres"
+	cb := db.Callback()
+
+	if err := cb.Query().Before("gorm:query").Register("query_budget:before_query", p.before); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("query_budget:after_query", p.after(true)); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("query_budget:before_row", p.before); err != nil {
+		return err
+	}
+	// Rows 在回调返回后才读取结果，不能提前取消上下文（超时到期后自动释放）
+	return cb.Row().After("gorm:row").Register("query_budget:after_row", p.after(false))
+}
+
+func (p *QueryBudgetPlugin) before(db *gorm.DB) {
+	if db.Error != nil || db.Statement == nil || db.DryRun {
+		return
+	}
+	ctx := db.Statement.Context
+	budget, ok := querybudget.FromContext(ctx)
+	if !ok || budget.IsZero() {
+		return
+	}
+
+	if p.postgres && (budget.MaxRowsScanned > 0 || budget.MaxSortRows > 0) {
+		if exceeded := p.checkPlan(db, budget); exceeded != nil {
+			metrics.DBQueryBudgetRejectionsTotal.WithLabelValues(budget.Class, string(exceeded.Limit)).Inc()
+			queryBudgetLog.Warn(ctx, "查询超出预算，已拒绝执行",
+				logger.String("class", budget.Class),
+				logger.String("limit", string(exceeded.Limit)),
+				logger.String("table", exceeded.Table),
+				logger.Int64("estimated", exceeded.Estimated),
+			)
+			_ = db.AddError(exceeded.AppError())
+			return
+		}
+	}
+
+	if budget.StatementTimeout > 0 {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= budget.StatementTimeout {
+			return
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, budget.StatementTimeout)
+		db.Statement.Context = timeoutCtx
+		db.InstanceSet(queryBudgetTimeoutKey, &queryBudgetTimeout{parent: ctx, cancel: cancel, budget: budget})
+	}
+}
+
+// after 恢复语句上下文，预算超时引起的错误转换为"查询开销过大"
+func (p *QueryBudgetPlugin) after(release bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(queryBudgetTimeoutKey)
+		if !ok {
+			return
+		}
+		timeout := v.(*queryBudgetTimeout)
+		expired := errors.Is(db.Statement.Context.Err(), context.DeadlineExceeded) && timeout.parent.Err() == nil
+		if release {
+			timeout.cancel()
+		}
+		db.Statement.Context = timeout.parent
+
+		if db.Error != nil && expired {
+			_, table := parseDynamicTable(db.Statement.SQL.String())
+			metrics.DBQueryBudgetRejectionsTotal.WithLabelValues(timeout.budget.Class, string(querybudget.LimitStatementTimeout)).Inc()
+			db.Error = querybudget.Timeout(timeout.budget, table).AppError()
+		}
+	}
+}
+
+// checkPlan 大表上按执行计划检查扫描与排序行数
+func (p *QueryBudgetPlugin) checkPlan(db *gorm.DB, budget querybudget.Budget) *querybudget.ExceededError {
+	// 未生成 SQL 时先按表名判断是否为动态表，非动态表不生成 SQL（交给 gorm:query）
+	target := db.Statement.SQL.String()
+	if target == "" {
+		target = "FROM " + db.Statement.Table
+	}
+	schema, table := parseDynamicTable(target)
+	if table == "" {
+		return nil
+	}
+	threshold := budget.MaxRowsScanned
+	if budget.MaxSortRows > 0 && (threshold <= 0 || budget.MaxSortRows < threshold) {
+		threshold = budget.MaxSortRows
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(db.Statement.Context), planCheckTimeout)
+	defer cancel()
+	rows := p.estimate(ctx, db, schema, table)
+	if rows <= threshold {
+		return nil
+	}
+
+	callbacks.BuildQuerySQL(db)
+	if db.Error != nil {
+		return nil
+	}
+	sql := db.Statement.SQL.String()
+	plan, err := p.explain(ctx, db, sql, db.Statement.Vars)
+	if err != nil {
+		queryBudgetLog.Debug(ctx, "获取执行计划失败，放行查询", logger.String("table", table), logger.ErrorField(err))
+		return nil
+	}
+	return evaluatePlan(budget, table, rows, plan, statementColumns(sql, schema, table))
+}
+
+// estimate 表的估算行数（按 EstimateTTL 缓存；未分析过或查询失败时为 0）
+func (p *QueryBudgetPlugin) estimate(ctx context.Context, db *gorm.DB, schema, table string) int64 {
+	key := schema + "." + table
+	p.mu.Lock()
+	cached, ok := p.estimates[key]
+	p.mu.Unlock()
+	if ok && time.Since(cached.at) < p.cfg.EstimateTTL {
+		return cached.rows
+	}
+
+	var rows int64
+	// 直接使用底层连接池，绕过 GORM 回调
+	if sqlDB, err := db.DB(); err == nil {
+		err = sqlDB.QueryRowContext(ctx,
+			`SELECT c.reltuples::bigint FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND c.relname = $2`,
+			schema, table).Scan(&rows)
+		if err != nil || rows < 0 {
+			rows = 0
+		}
+	}
+	p.mu.Lock()
+	p.estimates[key] = tableEstimate{rows: rows, at: time.Now()}
+	p.mu.Unlock()
+	return rows
+}
+
+// explain 执行 EXPLAIN (FORMAT JSON)（只生成计划，不执行语句）
+func (p *QueryBudgetPlugin) explain(ctx context.Context, db *gorm.DB, sql string, vars []interface{}) (*planNode, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	if err := sqlDB.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+sql, vars...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return nil, errors.New("empty plan")
+	}
+	return &plans[0].Plan, nil
+}
+
+// evaluatePlan 检查执行计划：带过滤条件的全表扫描（按表的估算行数）与不在 LIMIT 下的排序（按排序行数）
+// columns 为语句中引用的列，用于从过滤条件与排序键中提取建议建立索引的列
+func evaluatePlan(budget querybudget.Budget, table string, tableRows int64, root *planNode, columns map[string]bool) *querybudget.ExceededError {
+	var walk func(node *planNode, parent string) *querybudget.ExceededError
+	walk = func(node *planNode, parent string) *querybudget.ExceededError {
+		switch node.NodeType {
+		case "Seq Scan":
+			if budget.MaxRowsScanned > 0 && node.Relation == table && node.Filter != "" && tableRows > budget.MaxRowsScanned {
+				return querybudget.TooManyRows(budget, querybudget.LimitRowsScanned, table, tableRows, planColumns([]string{node.Filter}, columns))
+			}
+		case "Sort", "Incremental Sort":
+			// LIMIT 下的排序为 top-N 堆排序，内存占用受 LIMIT 限制
+			if budget.MaxSortRows > 0 && parent != "Limit" && int64(node.PlanRows) > budget.MaxSortRows {
+				return querybudget.TooManyRows(budget, querybudget.LimitSortRows, table, int64(node.PlanRows), planColumns(node.SortKey, columns))
+			}
+		}
+		for i := range node.Plans {
+			if exceeded := walk(&node.Plans[i], node.NodeType); exceeded != nil {
+				return exceeded
+			}
+		}
+		return nil
+	}
+	return walk(root, "")
+}
+
+// statementColumns 语句中带引号的列名（排除 Schema 与表名）
+func statementColumns(sql, schema, table string) map[string]bool {
+	columns := make(map[string]bool)
+	for _, m := range quotedIdentifierRegexp.FindAllStringSubmatch(sql, -1) {
+		if m[1] != schema && m[1] != table {
+			columns[m[1]] = true
+		}
+	}
+	return columns
+}
+
+// planColumns 计划表达式中出现的语句列（按出现顺序去重）
+func planColumns(expressions []string, columns map[string]bool) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, expr := range expressions {
+		for _, m := range identifierRegexp.FindAllStringSubmatch(expr, -1) {
+			if columns[m[1]] && !seen[m[1]] {
+				seen[m[1]] = true
+				result = append(result, m[1])
+			}
+		}
+	}
+	return result
+}
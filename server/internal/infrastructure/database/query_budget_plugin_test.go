@@ -0,0 +1,83 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/querybudget"
+)
+
+func parsePlanForTest(t *testing.T, raw string) *planNode {
+	t.Helper()
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		t.Fatalf("解析执行计划失败: %v", err)
+	}
+	return &plans[0].Plan
+}
+
+func TestEvaluatePlanRejectsFilteredSeqScan(t *testing.T) {
+	sql := `SELECT "__id" FROM "bseA1"."tblOrders" WHERE "fld_status" = $1 ORDER BY "fld_due" LIMIT 100`
+	plan := parsePlanForTest(t, `[{"Plan": {"Node Type": "Limit", "Plan Rows": 100, "Plans": [
+		{"Node Type": "Sort", "Plan Rows": 250000, "Sort Key": ["fld_due"], "Plans": [
+			{"Node Type": "Seq Scan", "Relation Name": "tblOrders", "Plan Rows": 250000, "Filter": "((fld_status)::text = 'open'::text)"}
+		]}
+	]}}]`)
+	budget := querybudget.Budget{Class: "interactive", MaxRowsScanned: 1000000, MaxSortRows: 100000}
+	columns := statementColumns(sql, "bseA1", "tblOrders")
+
+	exceeded := evaluatePlan(budget, "tblOrders", 3000000, plan, columns)
+	if exceeded == nil || exceeded.Limit != querybudget.LimitRowsScanned || exceeded.Estimated != 3000000 {
+		t.Fatalf("带过滤的全表扫描应超出扫描上限，得到 %+v", exceeded)
+	}
+	if len(exceeded.Suggestions) != 2 || !reflect.DeepEqual(exceeded.Suggestions[0].Columns, []string{"fld_status"}) {
+		t.Fatalf("应建议为过滤列建立索引，得到 %+v", exceeded.Suggestions)
+	}
+
+	// LIMIT 下的排序为 top-N，不受排序上限限制；表行数未超出扫描上限时通过
+	if exceeded := evaluatePlan(budget, "tblOrders", 500000, plan, columns); exceeded != nil {
+		t.Fatalf("未超出预算的计划不应被拒绝，得到 %+v", exceeded)
+	}
+}
+
+func TestEvaluatePlanRejectsLargeSort(t *testing.T) {
+	sql := `SELECT "__id" FROM "bseA1"."tblOrders" ORDER BY "fld_due" DESC, "__id"`
+	plan := parsePlanForTest(t, `[{"Plan": {"Node Type": "Sort", "Plan Rows": 3000000, "Sort Key": ["fld_due DESC", "__id"], "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "tblOrders", "Plan Rows": 3000000}
+	]}}]`)
+	budget := querybudget.Budget{Class: "interactive", MaxRowsScanned: 1000000, MaxSortRows: 100000}
+
+	exceeded := evaluatePlan(budget, "tblOrders", 3000000, plan, statementColumns(sql, "bseA1", "tblOrders"))
+	if exceeded == nil || exceeded.Limit != querybudget.LimitSortRows || exceeded.Max != 100000 {
+		t.Fatalf("无法使用索引的大排序应超出排序上限，得到 %+v", exceeded)
+	}
+	if !reflect.DeepEqual(exceeded.Suggestions[0].Columns, []string{"fld_due", "__id"}) {
+		t.Fatalf("应建议为排序列建立索引，得到 %+v", exceeded.Suggestions)
+	}
+
+	// 不带过滤条件的全表扫描只受语句超时限制
+	budget.MaxSortRows = 0
+	if exceeded := evaluatePlan(budget, "tblOrders", 3000000, plan, nil); exceeded != nil {
+		t.Fatalf("无过滤的全表扫描不应按扫描上限拒绝，得到 %+v", exceeded)
+	}
+}
+
+func TestExceededErrorIsTypedAppError(t *testing.T) {
+	exceeded := querybudget.TooManyRows(querybudget.Budget{Class: "interactive", MaxRowsScanned: 10}, querybudget.LimitRowsScanned, "tblOrders", 20, nil)
+	err := fmt.Errorf("从物理表查询列表失败: %w", exceeded.AppError())
+
+	appErr := pkgerrors.Database(err, "查询记录失败")
+	if appErr.Code != "QUERY_TOO_EXPENSIVE" || appErr.HTTPStatus != 422 || appErr.Retryable {
+		t.Fatalf("应保留查询开销过大的错误码，得到 %+v", appErr)
+	}
+	var typed *querybudget.ExceededError
+	if !errors.As(err, &typed) || typed.Table != "tblOrders" {
+		t.Fatalf("应能取得预算详情，得到 %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
 )
 
 // QueryOptimizer 查询优化器
@@ -93,7 +95,7 @@ func (qo *QueryOptimizer) OptimizedFind(ctx context.Context, dest interface{}, q
 	// 缓存结果
 	if err == nil && qo.cache != nil {
 		go func() {
-			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			cacheCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 5*time.Second)
 			defer cancel()
 			qo.cache.Set(cacheCtx, cacheKey, dest, 10*time.Minute)
 		}()
@@ -136,7 +138,7 @@ func (qo *QueryOptimizer) OptimizedFirst(ctx context.Context, dest interface{},
 	// 缓存结果
 	if err == nil && qo.cache != nil {
 		go func() {
-			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			cacheCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 5*time.Second)
 			defer cancel()
 			qo.cache.Set(cacheCtx, cacheKey, dest, 10*time.Minute)
 		}()
@@ -178,7 +180,7 @@ func (qo *QueryOptimizer) OptimizedCount(ctx context.Context, query string, args
 	// 缓存结果
 	if err == nil && qo.cache != nil {
 		go func() {
-			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			cacheCtx, cancel := context.WithTimeout(tracing.Detach(ctx), 5*time.Second)
 			defer cancel()
 			qo.cache.Set(cacheCtx, cacheKey, count, 5*time.Minute)
 		}()
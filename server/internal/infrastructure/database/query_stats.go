@@ -0,0 +1,290 @@
+package database
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TableQueryStats 单张动态表的查询统计
+type TableQueryStats struct {
+	Schema        string        `json:"schema"`   // 所在 Schema（Base ID 或租户 Schema）
+	Table         string        `json:"table"`    // 物理表名（即 Table ID）
+	Queries       int64         `json:"queries"`  // 查询次数
+	SlowQueries   int64         `json:"slow"`     // 慢查询次数
+	Errors        int64         `json:"errors"`   // 失败次数
+	RowsAffected  int64         `json:"rows"`     // 累计返回/影响行数
+	TotalDuration time.Duration `json:"total_ns"` // 累计耗时
+	MaxDuration   time.Duration `json:"max_ns"`   // 最大单次耗时
+	LastSlowAt    *time.Time    `json:"last_slow_at,omitempty"`
+}
+
+// AvgDuration 平均耗时
+func (s TableQueryStats) AvgDuration() time.Duration {
+	if s.Queries == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Queries)
+}
+
+// SlowQueryEntry 一条慢查询记录
+type SlowQueryEntry struct {
+	Schema       string        `json:"schema,omitempty"`
+	Table        string        `json:"table,omitempty"`
+	Operation    string        `json:"operation"`
+	SQL          string        `json:"sql"`
+	Duration     time.Duration `json:"duration_ns"`
+	RowsAffected int64         `json:"rows"`
+	Error        string        `json:"error,omitempty"`
+	Hints        []string      `json:"hints,omitempty"` // 基于语句的执行计划提示
+	Plan         []string      `json:"plan,omitempty"`  // EXPLAIN 输出（启用 explain 时）
+	TraceID      string        `json:"trace_id,omitempty"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+// QueryRecord 一次查询的观测结果
+type QueryRecord struct {
+	Schema       string
+	Table        string
+	Duration     time.Duration
+	RowsAffected int64
+	Failed       bool
+	Slow         bool
+}
+
+// QueryStatistics 按动态表聚合的查询统计与最近慢查询（进程内，重启清零）
+type QueryStatistics struct {
+	mu        sync.RWMutex
+	tables    map[string]*TableQueryStats
+	maxTables int
+	dropped   int64 // 达到表数量上限后未统计的查询数
+
+	slow      []*SlowQueryEntry // 环形缓冲
+	slowNext  int
+	slowTotal int64
+}
+
+// NewQueryStatistics 创建查询统计
+func NewQueryStatistics(maxEntries, maxTables int) *QueryStatistics {
+	if maxEntries <= 0 {
+		maxEntries = 200
+	}
+	if maxTables <= 0 {
+		maxTables = 5000
+	}
+	return &QueryStatistics{
+		tables:    make(map[string]*TableQueryStats),
+		maxTables: maxTables,
+		slow:      make([]*SlowQueryEntry, 0, maxEntries),
+	}
+}
+
+// Record 记录一次动态表查询
+func (s *QueryStatistics) Record(r QueryRecord) {
+	key := r.Schema + "." + r.Table
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.tables[key]
+	if !ok {
+		if len(s.tables) >= s.maxTables {
+			s.dropped++
+			return
+		}
+		stat = &TableQueryStats{Schema: r.Schema, Table: r.Table}
+		s.tables[key] = stat
+	}
+
+	stat.Queries++
+	stat.RowsAffected += r.RowsAffected
+	stat.TotalDuration += r.Duration
+	if r.Duration > stat.MaxDuration {
+		stat.MaxDuration = r.Duration
+	}
+	if r.Failed {
+		stat.Errors++
+	}
+	if r.Slow {
+		stat.SlowQueries++
+		now := time.Now()
+		stat.LastSlowAt = &now
+	}
+}
+
+// AddSlowQuery 追加慢查询记录（超过容量时覆盖最旧的一条）
+func (s *QueryStatistics) AddSlowQuery(entry *SlowQueryEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.slowTotal++
+	if len(s.slow) < cap(s.slow) {
+		s.slow = append(s.slow, entry)
+		return
+	}
+	s.slow[s.slowNext] = entry
+	s.slowNext = (s.slowNext + 1) % len(s.slow)
+}
+
+// setPlan 回填异步 EXPLAIN 的执行计划
+func (s *QueryStatistics) setPlan(entry *SlowQueryEntry, plan []string, hints []string) {
+	s.mu.Lock()
+	entry.Plan = plan
+	entry.Hints = appendUnique(entry.Hints, hints...)
+	s.mu.Unlock()
+}
+
+// Tables 全部动态表统计快照
+func (s *QueryStatistics) Tables() []TableQueryStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]TableQueryStats, 0, len(s.tables))
+	for _, stat := range s.tables {
+		result = append(result, *stat)
+	}
+	return result
+}
+
+// SlowQueries 最近的慢查询（按时间倒序）
+func (s *QueryStatistics) SlowQueries() []SlowQueryEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]SlowQueryEntry, 0, len(s.slow))
+	for _, entry := range s.slow {
+		e := *entry
+		e.Hints = append([]string(nil), entry.Hints...)
+		e.Plan = append([]string(nil), entry.Plan...)
+		result = append(result, e)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.After(result[j].Timestamp)
+	})
+	return result
+}
+
+// Totals 累计慢查询条数与因表数量上限未统计的查询数
+func (s *QueryStatistics) Totals() (slowTotal, dropped int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.slowTotal, s.dropped
+}
+
+// Reset 清空统计
+func (s *QueryStatistics) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tables = make(map[string]*TableQueryStats)
+	s.slow = s.slow[:0]
+	s.slowNext = 0
+	s.slowTotal = 0
+	s.dropped = 0
+}
+
+// ==================== 表名解析与执行计划提示 ====================
+
+// systemSchemas 非动态表所在的 Schema
+var systemSchemas = map[string]bool{
+	"":                   true,
+	"public":             true,
+	"pg_catalog":         true,
+	"information_schema": true,
+}
+
+var (
+	qualifiedTableRegexp = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+"?([A-Za-z0-9_]+)"?\s*\.\s*"?([A-Za-z0-9_]+)"?`)
+	whereRegexp          = regexp.MustCompile(`(?i)\bwhere\b`)
+	stringLiteralRegexp  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	leadingWildcardRegex = regexp.MustCompile(`(?i)\bI?LIKE\s+'%`)
+	offsetRegexp         = regexp.MustCompile(`(?i)\boffset\s+(\d+)`)
+	limitRegexp          = regexp.MustCompile(`(?i)\blimit\b`)
+	orderByRegexp        = regexp.MustCompile(`(?i)\border\s+by\b`)
+	countRegexp          = regexp.MustCompile(`(?i)\bcount\s*\(`)
+	inListRegexp         = regexp.MustCompile(`(?i)\bin\s*\(([^()]*)\)`)
+	seqScanRegexp        = regexp.MustCompile(`Seq Scan on ([^\s]+)`)
+)
+
+const (
+	largeOffset  = 10000
+	largeRows    = 10000
+	largeInList  = 1000
+	maxPlanLines = 20
+)
+
+// parseDynamicTable 从 SQL 中解析 Schema 限定的动态表（schema.table），系统表返回空
+func parseDynamicTable(sql string) (schema, table string) {
+	m := qualifiedTableRegexp.FindStringSubmatch(sql)
+	if m == nil || systemSchemas[strings.ToLower(m[1])] {
+		return "", ""
+	}
+	return m[1], m[2]
+}
+
+// planHints 基于语句文本给出常见的低效模式提示（不访问数据库）
+func planHints(operation, sql string, rows int64) []string {
+	var hints []string
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+
+	switch operation {
+	case "SELECT", "UPDATE", "DELETE":
+		if !whereRegexp.MatchString(sql) {
+			if countRegexp.MatchString(sql) {
+				hints = append(hints, "全表计数：动态表上的 COUNT(*) 无过滤条件，考虑缓存计数")
+			} else if operation != "SELECT" || !limitRegexp.MatchString(sql) {
+				hints = append(hints, "缺少 WHERE 条件，可能全表扫描")
+			}
+		}
+	}
+	if leadingWildcardRegex.MatchString(sql) {
+		hints = append(hints, "前导通配符 LIKE 无法使用 B-tree 索引，考虑 pg_trgm 或全文索引")
+	}
+	if m := offsetRegexp.FindStringSubmatch(sql); m != nil {
+		if offset, _ := strconv.Atoi(m[1]); offset >= largeOffset {
+			hints = append(hints, "大偏移分页（OFFSET "+m[1]+"），建议改用游标分页")
+		}
+	}
+	if strings.HasPrefix(upper, "SELECT") && orderByRegexp.MatchString(sql) && !limitRegexp.MatchString(sql) {
+		hints = append(hints, "排序未限制返回行数")
+	}
+	for _, m := range inListRegexp.FindAllStringSubmatch(sql, -1) {
+		if strings.Count(m[1], ",")+1 >= largeInList {
+			hints = append(hints, "IN 列表过长，考虑分批或改用临时表/ANY(数组)")
+			break
+		}
+	}
+	if rows >= largeRows {
+		hints = append(hints, "返回/影响行数过多（"+strconv.FormatInt(rows, 10)+"）")
+	}
+	return hints
+}
+
+// explainHints 从 EXPLAIN 输出中提取提示（如动态表上的顺序扫描）
+func explainHints(plan []string) []string {
+	var hints []string
+	for _, line := range plan {
+		if m := seqScanRegexp.FindStringSubmatch(line); m != nil {
+			hints = appendUnique(hints, "执行计划包含顺序扫描："+m[1]+"，检查过滤/排序字段是否有索引")
+		}
+	}
+	return hints
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		exists := false
+		for _, v := range list {
+			if v == item {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+)
+
+func TestParseDynamicTable(t *testing.T) {
+	cases := []struct {
+		sql           string
+		schema, table string
+	}{
+		{`SELECT * FROM "bseA1"."tblB2" WHERE __id = $1`, "bseA1", "tblB2"},
+		{`INSERT INTO ws_spc1.tblB2 ("__id") VALUES ($1)`, "ws_spc1", "tblB2"},
+		{`UPDATE "bseA1" . "tblB2" SET "a" = $1`, "bseA1", "tblB2"},
+		{`SELECT * FROM "record_history" WHERE table_id = $1`, "", ""},
+		{`SELECT * FROM public.users`, "", ""},
+		{`SELECT * FROM information_schema.columns`, "", ""},
+	}
+	for _, c := range cases {
+		schema, table := parseDynamicTable(c.sql)
+		if schema != c.schema || table != c.table {
+			t.Errorf("parseDynamicTable(%q) = %q, %q; want %q, %q", c.sql, schema, table, c.schema, c.table)
+		}
+	}
+}
+
+func TestPlanHints(t *testing.T) {
+	has := func(hints []string, substr string) bool {
+		for _, h := range hints {
+			if strings.Contains(h, substr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hints := planHints("SELECT", `SELECT * FROM "b"."t" ORDER BY "a"`, 10); !has(hints, "WHERE") || !has(hints, "排序") {
+		t.Errorf("无过滤、无限制排序应提示，得到 %v", hints)
+	}
+	if hints := planHints("SELECT", `SELECT count(*) FROM "b"."t"`, 1); !has(hints, "全表计数") {
+		t.Errorf("全表计数应提示，得到 %v", hints)
+	}
+	if hints := planHints("SELECT", `SELECT * FROM "b"."t" WHERE "name" ILIKE '%abc%' LIMIT 20 OFFSET 20000`, 20); !has(hints, "前导通配符") || !has(hints, "OFFSET 20000") {
+		t.Errorf("前导通配符与大偏移应提示，得到 %v", hints)
+	}
+	if hints := planHints("SELECT", `SELECT * FROM "b"."t" WHERE __id = 'rec1' LIMIT 1`, 1); len(hints) != 0 {
+		t.Errorf("主键查询不应有提示，得到 %v", hints)
+	}
+	ids := strings.TrimSuffix(strings.Repeat("'x',", largeInList), ",")
+	if hints := planHints("DELETE", `DELETE FROM "b"."t" WHERE __id IN (`+ids+`)`, 5); !has(hints, "IN 列表") {
+		t.Errorf("超长 IN 列表应提示，得到 %v", hints)
+	}
+	if hints := explainHints([]string{"Limit  (cost=0.00..1.00)", "  ->  Seq Scan on tblB2  (cost=0.00..100.00)"}); !has(hints, "tblB2") {
+		t.Errorf("执行计划中的顺序扫描应提示，得到 %v", hints)
+	}
+}
+
+func TestQueryStatistics(t *testing.T) {
+	stats := NewQueryStatistics(2, 1)
+
+	stats.Record(QueryRecord{Schema: "bse1", Table: "tbl1", Duration: 10 * time.Millisecond, RowsAffected: 3})
+	stats.Record(QueryRecord{Schema: "bse1", Table: "tbl1", Duration: 30 * time.Millisecond, Slow: true, Failed: true})
+	stats.Record(QueryRecord{Schema: "bse1", Table: "tbl2", Duration: time.Millisecond}) // 超过表数量上限
+
+	tables := stats.Tables()
+	if len(tables) != 1 {
+		t.Fatalf("应只统计 1 张表，得到 %d", len(tables))
+	}
+	got := tables[0]
+	if got.Queries != 2 || got.SlowQueries != 1 || got.Errors != 1 || got.RowsAffected != 3 ||
+		got.MaxDuration != 30*time.Millisecond || got.AvgDuration() != 20*time.Millisecond || got.LastSlowAt == nil {
+		t.Errorf("统计不正确: %+v", got)
+	}
+
+	base := time.Now()
+	for i := 0; i < 3; i++ {
+		stats.AddSlowQuery(&SlowQueryEntry{SQL: string(rune('a' + i)), Timestamp: base.Add(time.Duration(i) * time.Second)})
+	}
+	slow := stats.SlowQueries()
+	if len(slow) != 2 || slow[0].SQL != "c" || slow[1].SQL != "b" {
+		t.Errorf("环形缓冲应保留最近两条并按时间倒序，得到 %+v", slow)
+	}
+	if total, dropped := stats.Totals(); total != 3 || dropped != 1 {
+		t.Errorf("Totals() = %d, %d; want 3, 1", total, dropped)
+	}
+
+	stats.Reset()
+	if len(stats.Tables()) != 0 || len(stats.SlowQueries()) != 0 {
+		t.Errorf("Reset 后应清空统计")
+	}
+}
+
+func TestSlowQueryPluginRedactsValues(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=luckdb"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("创建 GORM 实例失败: %v", err)
+	}
+	stats := NewQueryStatistics(10, 10)
+	if err := db.Use(NewSlowQueryPlugin(config.DBSlowQueryConfig{Threshold: time.Nanosecond}, stats)); err != nil {
+		t.Fatalf("注册慢查询插件失败: %v", err)
+	}
+
+	db.Exec(`UPDATE "bseA1"."tblB2" SET "phone" = ?, "note" = 'it''s secret' WHERE "name" LIKE ?`, "13800000000", "%alice%")
+	slow := stats.SlowQueries()
+	if len(slow) != 1 {
+		t.Fatalf("应记录一条慢查询，得到 %d 条", len(slow))
+	}
+	for _, value := range []string{"13800000000", "alice", "secret"} {
+		if strings.Contains(slow[0].SQL, value) {
+			t.Fatalf("慢查询记录不应包含参数值 %q: %s", value, slow[0].SQL)
+		}
+	}
+	if !strings.Contains(slow[0].SQL, `"note" = '?'`) {
+		t.Errorf("内联字面量应替换为占位符: %s", slow[0].SQL)
+	}
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// writeSQLRegexp 原生 SQL 中的写操作
+var writeSQLRegexp = regexp.MustCompile(`(?i)\b(insert\s+into|update\s+\S+\s+set|delete\s+from|truncate)\b`)
+
+// tableExprRegexp Table("schema.table") 生成的表达式
+var tableExprRegexp = regexp.MustCompile(`^"?([A-Za-z0-9_]+)"?\s*\.\s*"?([A-Za-z0-9_]+)"?$`)
+
+type regionGuardBypassKey struct{}
+
+// withRegionGuardBypass 跳过区域校验（跨区域迁移需要读写非当前区域的集群）
+func withRegionGuardBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, regionGuardBypassKey{}, true)
+}
+
+func regionGuardBypassed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	bypass, _ := ctx.Value(regionGuardBypassKey{}).(bool)
+	return bypass
+}
+
+// RegionGuardPlugin 数据驻留区域校验插件 ✨
+// 注册在每个区域集群上，在执行动态表语句前校验该集群是否为表所属空间的当前区域：
+//   - 未经路由、落到错误集群的读写直接失败，而不是静默读到空数据或写入错误区域；
+//   - 迁移切换窗口内拒绝所有写入，保证最后一轮追赶后源区域不再变化。
+//
+// 系统表与非 Schema 限定的元数据表不受影响
+type RegionGuardPlugin struct {
+	router *RegionRouter
+	region string
+}
+
+// NewRegionGuardPlugin 创建区域校验插件（region 为插件所在集群的区域）
+func NewRegionGuardPlugin(router *RegionRouter, region string) *RegionGuardPlugin {
+	return &RegionGuardPlugin{router: router, region: region}
+}
+
+// Name 插件名称
+func (p *RegionGuardPlugin) Name() string {
+	return "luckdb:region_guard"
+}
+
+// Initialize 注册 GORM 回调
+func (p *RegionGuardPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("region_guard:create", p.guard(true)); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("region_guard:query", p.guard(false)); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("region_guard:update", p.guard(true)); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("region_guard:delete", p.guard(true)); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("region_guard:row", p.guard(false)); err != nil {
+		return err
+	}
+	return cb.Raw().Before("gorm:raw").Register("region_guard:raw", p.guard(false))
+}
+
+func (p *RegionGuardPlugin) guard(write bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement == nil {
+			return
+		}
+		ctx := db.Statement.Context
+		if regionGuardBypassed(ctx) {
+			return
+		}
+		schema := statementSchema(db.Statement)
+		if schema == "" {
+			return
+		}
+		spaceID, err := p.router.spaceForSchema(ctx, schema)
+		if err != nil {
+			_ = db.AddError(err)
+			return
+		}
+		if spaceID == "" {
+			return
+		}
+		isWrite := write || writeSQLRegexp.MatchString(db.Statement.SQL.String())
+		if err := p.router.CheckAccess(ctx, spaceID, p.region, isWrite); err != nil {
+			_ = db.AddError(err)
+		}
+	}
+}
+
+// statementSchema 语句访问的动态表所在 Schema（系统表返回空）
+func statementSchema(stmt *gorm.Statement) string {
+	if stmt.TableExpr != nil {
+		if m := tableExprRegexp.FindStringSubmatch(strings.TrimSpace(stmt.TableExpr.SQL)); m != nil {
+			if systemSchemas[strings.ToLower(m[1])] {
+				return ""
+			}
+			return m[1]
+		}
+	}
+	schema, _ := parseDynamicTable(stmt.SQL.String())
+	return schema
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// regionGuardFixture 单个 DryRun 集群（区域 eu）上的区域校验，绑定记录由 bindings 提供
+type regionGuardFixture struct {
+	db       *gorm.DB
+	router   *RegionRouter
+	now      time.Time
+	bindings map[string]*models.SpaceRegion
+	loads    int
+}
+
+func newRegionGuardFixture(t *testing.T) *regionGuardFixture {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=luckdb"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("创建 GORM 实例失败: %v", err)
+	}
+
+	fx := &regionGuardFixture{db: db, now: time.Now(), bindings: map[string]*models.SpaceRegion{}}
+	fx.router = NewRegionRouter(db, nil, "us")
+	fx.router.AddCluster("eu", db, nil)
+	fx.router.now = func() time.Time { return fx.now }
+	fx.router.loadBinding = func(_ context.Context, spaceID string) (*models.SpaceRegion, error) {
+		fx.loads++
+		return fx.bindings[spaceID], nil
+	}
+	fx.router.schemaSpaces.Store("bseA1", "spc1")
+	if err := db.Use(NewRegionGuardPlugin(fx.router, "eu")); err != nil {
+		t.Fatalf("注册区域校验插件失败: %v", err)
+	}
+	return fx
+}
+
+// bind 修改绑定记录并让缓存过期
+func (fx *regionGuardFixture) bind(region, status string, since time.Time) {
+	fx.bindings["spc1"] = &models.SpaceRegion{SpaceID: "spc1", Region: region, Status: status, CreatedTime: since, LastModifiedTime: &since}
+	fx.now = fx.now.Add(regionBindingTTL)
+}
+
+func (fx *regionGuardFixture) read(ctx context.Context) error {
+	var rows []map[string]interface{}
+	return fx.db.WithContext(ctx).Table("bseA1.tblB2").Find(&rows).Error
+}
+
+func (fx *regionGuardFixture) write(ctx context.Context) error {
+	return fx.db.WithContext(ctx).Exec(`UPDATE "bseA1"."tblB2" SET "a" = 1`).Error
+}
+
+func TestRegionBindingCacheExpires(t *testing.T) {
+	fx := newRegionGuardFixture(t)
+	ctx := context.Background()
+
+	fx.bindings["spc1"] = &models.SpaceRegion{SpaceID: "spc1", Region: "eu", Status: SpaceRegionActive}
+	for i := 0; i < 3; i++ {
+		if region, err := fx.router.RegionForSpace(ctx, "spc1"); err != nil || region != "eu" {
+			t.Fatalf("应解析到 eu，得到 %q, %v", region, err)
+		}
+	}
+	if fx.loads != 1 {
+		t.Fatalf("缓存有效期内应只加载一次，得到 %d 次", fx.loads)
+	}
+
+	// 其他实例完成迁移后，本实例在缓存过期后看到新区域
+	fx.bind("us", SpaceRegionActive, fx.now)
+	if region, _ := fx.router.RegionForSpace(ctx, "spc1"); region != "us" {
+		t.Fatalf("缓存过期后应重新加载绑定，得到 %q", region)
+	}
+
+	// 未绑定的空间使用默认区域
+	if region, _ := fx.router.RegionForSpace(ctx, "spc2"); region != "us" {
+		t.Fatalf("未绑定的空间应使用默认区域，得到 %q", region)
+	}
+}
+
+func TestRegionGuardRejectsMisroutedStatements(t *testing.T) {
+	fx := newRegionGuardFixture(t)
+	ctx := context.Background()
+
+	fx.bind("us", SpaceRegionActive, fx.now)
+	if err := fx.read(ctx); !errors.Is(err, ErrRegionMismatch) {
+		t.Fatalf("在非当前区域读取应失败，得到 %v", err)
+	}
+	if err := fx.write(ctx); !errors.Is(err, ErrRegionMismatch) {
+		t.Fatalf("在非当前区域写入应失败，得到 %v", err)
+	}
+	if err := fx.read(withRegionGuardBypass(ctx)); err != nil {
+		t.Fatalf("迁移器跳过校验，得到 %v", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := fx.db.Table("users").Find(&rows).Error; err != nil {
+		t.Fatalf("元数据表不受影响，得到 %v", err)
+	}
+
+	fx.bind("eu", SpaceRegionActive, fx.now)
+	if err := fx.read(ctx); err != nil {
+		t.Fatalf("当前区域读取应放行，得到 %v", err)
+	}
+	if err := fx.write(ctx); err != nil {
+		t.Fatalf("当前区域写入应放行，得到 %v", err)
+	}
+}
+
+func TestRegionGuardFencesWritesDuringCutover(t *testing.T) {
+	fx := newRegionGuardFixture(t)
+	ctx := context.Background()
+
+	fx.bind("eu", SpaceRegionCutover, fx.now)
+	if err := fx.read(ctx); err != nil {
+		t.Fatalf("切换窗口内读取应放行，得到 %v", err)
+	}
+	if err := fx.write(ctx); !errors.Is(err, ErrRegionCutover) {
+		t.Fatalf("切换窗口内写入应被拒绝，得到 %v", err)
+	}
+	if err := fx.db.Table("bseA1.tblB2").Create(map[string]interface{}{"__id": "rec1"}).Error; !errors.Is(err, ErrRegionCutover) {
+		t.Fatalf("切换窗口内插入应被拒绝，得到 %v", err)
+	}
+
+	// 迁移进程中断后切换窗口过期，源区域恢复写入
+	fx.bind("eu", SpaceRegionCutover, fx.now.Add(-regionCutoverMaxAge))
+	if err := fx.write(ctx); err != nil {
+		t.Fatalf("过期的切换窗口不应继续拒绝写入，得到 %v", err)
+	}
+}
@@ -0,0 +1,448 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+const (
+	regionCopyBatchSize   = 1000
+	regionCatchUpMaxPass  = 5
+	regionCatchUpMinDelta = 100
+)
+
+// RegionMigrationReport 空间跨区域迁移报告
+type RegionMigrationReport struct {
+	SpaceID      string        `json:"spaceId"`
+	SourceRegion string        `json:"sourceRegion"`
+	TargetRegion string        `json:"targetRegion"`
+	Tables       int           `json:"tables"`
+	RowsCopied   int64         `json:"rowsCopied"`
+	RowsRemoved  int64         `json:"rowsRemoved"`
+	CatchUpPass  int           `json:"catchUpPass"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// RegionMigrator 工作空间跨区域在线迁移
+//
+// 流程：
+//  1. 标记绑定为 migrating（读写仍在源区域）
+//  2. 在目标区域创建Schema/物理表，按 __auto_number 分批全量复制
+//  3. 按 __last_modified_time 多轮增量追赶，直到增量足够小
+//  4. 标记绑定为 cutover 并等待各实例的绑定缓存过期，此后所有写入被 RegionGuardPlugin 拒绝
+//  5. 在源表上加 SHARE 锁等待进行中的写事务结束，完成最后一轮追赶、对账删除与序列同步
+//  6. 切换绑定到目标区域
+//
+// 源区域数据保留，确认无误后通过 DropSourceData 清理
+type RegionMigrator struct {
+	router *RegionRouter
+}
+
+// NewRegionMigrator 创建跨区域迁移器
+func NewRegionMigrator(router *RegionRouter) *RegionMigrator {
+	return &RegionMigrator{router: router}
+}
+
+type spaceTable struct {
+	BaseID  string `gorm:"column:base_id"`
+	TableID string `gorm:"column:id"`
+}
+
+// MigrateSpace 将空间在线迁移到目标区域
+func (m *RegionMigrator) MigrateSpace(ctx context.Context, spaceID, targetRegion string) (*RegionMigrationReport, error) {
+	start := time.Now()
+
+	sourceRegion, err := m.router.RegionForSpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if sourceRegion == targetRegion {
+		return nil, fmt.Errorf("空间已位于区域 %s", targetRegion)
+	}
+	source, err := m.router.Cluster(sourceRegion)
+	if err != nil {
+		return nil, err
+	}
+	target, err := m.router.Cluster(targetRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := m.router.GetBinding(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	if binding != nil && (binding.Status == SpaceRegionMigrating || binding.Status == SpaceRegionCutover) {
+		return nil, fmt.Errorf("空间 %s 正在迁移中", spaceID)
+	}
+	if binding == nil {
+		if err := m.router.bindSpace(ctx, spaceID, sourceRegion); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.router.updateBinding(ctx, spaceID, map[string]interface{}{
+		"status":          SpaceRegionMigrating,
+		"target_region":   targetRegion,
+		"migration_error": nil,
+	}); err != nil {
+		return nil, err
+	}
+
+	report := &RegionMigrationReport{SpaceID: spaceID, SourceRegion: sourceRegion, TargetRegion: targetRegion}
+	if err := m.migrate(withRegionGuardBypass(WithTenant(ctx, spaceID)), spaceID, source, target, report); err != nil {
+		// 恢复源区域读写（切换窗口超过 regionCutoverMaxAge 后同样会自动恢复）
+		if resetErr := m.router.updateBinding(context.WithoutCancel(ctx), spaceID, map[string]interface{}{
+			"status":          SpaceRegionActive,
+			"target_region":   nil,
+			"migration_error": err.Error(),
+		}); resetErr != nil {
+			logger.Error("恢复空间区域绑定失败",
+				logger.String("space_id", spaceID),
+				logger.ErrorField(resetErr))
+		}
+		logger.Error("空间跨区域迁移失败",
+			logger.String("space_id", spaceID),
+			logger.String("target_region", targetRegion),
+			logger.ErrorField(err))
+		return nil, err
+	}
+
+	report.Duration = time.Since(start)
+	logger.Info("✅ 空间跨区域迁移完成",
+		logger.String("space_id", spaceID),
+		logger.String("source_region", sourceRegion),
+		logger.String("target_region", targetRegion),
+		logger.Int64("rows_copied", report.RowsCopied))
+	return report, nil
+}
+
+func (m *RegionMigrator) migrate(ctx context.Context, spaceID string, source, target *RegionCluster, report *RegionMigrationReport) error {
+	tables, err := m.spaceTables(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	report.Tables = len(tables)
+
+	// 1. 结构 + 全量复制
+	copyStart := time.Now()
+	createdSchemas := make(map[string]bool)
+	for _, t := range tables {
+		if !createdSchemas[t.BaseID] {
+			if err := target.Provider.CreateSchema(ctx, t.BaseID); err != nil {
+				return err
+			}
+			createdSchemas[t.BaseID] = true
+		}
+		if err := copyTableStructure(ctx, source, target, t); err != nil {
+			return err
+		}
+		n, err := copyTableAll(ctx, source, target, t)
+		if err != nil {
+			return err
+		}
+		report.RowsCopied += n
+	}
+
+	// 2. 增量追赶
+	since := copyStart
+	for pass := 1; pass <= regionCatchUpMaxPass; pass++ {
+		passStart := time.Now()
+		var delta int64
+		for _, t := range tables {
+			n, err := copyTableSince(ctx, source, target, t, since)
+			if err != nil {
+				return err
+			}
+			delta += n
+		}
+		report.RowsCopied += delta
+		report.CatchUpPass = pass
+		since = passStart
+		if delta < regionCatchUpMinDelta {
+			break
+		}
+	}
+
+	// 3. 进入切换窗口：等待各实例看到 cutover 状态后，源区域不再有新的写入
+	if err := m.router.updateBinding(ctx, spaceID, map[string]interface{}{
+		"status": SpaceRegionCutover,
+	}); err != nil {
+		return fmt.Errorf("进入切换窗口失败: %w", err)
+	}
+	select {
+	case <-time.After(regionBindingTTL + time.Second):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// 4. 锁住源表等待进行中的写事务结束，完成最后一轮追赶与对账后切换绑定
+	return source.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked := &RegionCluster{Name: source.Name, DB: tx, Provider: source.Provider}
+		if len(tables) > 0 {
+			names := make([]string, 0, len(tables))
+			for _, t := range tables {
+				names = append(names, source.Provider.GenerateTableName(t.BaseID, t.TableID))
+			}
+			if err := tx.Exec("LOCK TABLE " + strings.Join(names, ", ") + " IN SHARE MODE").Error; err != nil {
+				return fmt.Errorf("锁定源表失败: %w", err)
+			}
+		}
+
+		for _, t := range tables {
+			n, err := copyTableSince(ctx, locked, target, t, since)
+			if err != nil {
+				return err
+			}
+			report.RowsCopied += n
+			removed, err := reconcileTableDeletes(ctx, locked, target, t)
+			if err != nil {
+				return err
+			}
+			report.RowsRemoved += removed
+			if err := syncTableSequence(ctx, target, t); err != nil {
+				return err
+			}
+		}
+
+		if err := m.router.updateBinding(ctx, spaceID, map[string]interface{}{
+			"region":        target.Name,
+			"status":        SpaceRegionActive,
+			"target_region": nil,
+		}); err != nil {
+			return fmt.Errorf("切换区域绑定失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// DropSourceData 清理迁移后的源区域数据
+func (m *RegionMigrator) DropSourceData(ctx context.Context, spaceID, sourceRegion string) error {
+	current, err := m.router.RegionForSpace(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	if current == sourceRegion {
+		return fmt.Errorf("区域 %s 仍是空间的当前区域，拒绝删除", sourceRegion)
+	}
+	source, err := m.router.Cluster(sourceRegion)
+	if err != nil {
+		return err
+	}
+	tables, err := m.spaceTables(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	ctx = WithTenant(ctx, spaceID)
+	for _, t := range tables {
+		if err := source.Provider.DropPhysicalTable(ctx, t.BaseID, t.TableID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spaceTables 列出空间内所有物理表（元数据位于主库）
+func (m *RegionMigrator) spaceTables(ctx context.Context, spaceID string) ([]spaceTable, error) {
+	var tables []spaceTable
+	err := m.router.primary.WithContext(ctx).
+		Table("table_meta").
+		Select("table_meta.id, table_meta.base_id").
+		Joins("JOIN base ON base.id = table_meta.base_id").
+		Where("base.space_id = ? AND table_meta.deleted_time IS NULL", spaceID).
+		Find(&tables).Error
+	if err != nil {
+		return nil, fmt.Errorf("查询空间物理表失败: %w", err)
+	}
+	return tables, nil
+}
+
+// copyTableStructure 在目标区域创建物理表，并补齐用户字段列
+func copyTableStructure(ctx context.Context, source, target *RegionCluster, t spaceTable) error {
+	fullName := target.Provider.GenerateTableName(t.BaseID, t.TableID)
+
+	var exists bool
+	if err := target.DB.WithContext(ctx).Raw("SELECT to_regclass(?) IS NOT NULL", fullName).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		if err := target.Provider.CreatePhysicalTable(ctx, t.BaseID, t.TableID); err != nil {
+			return err
+		}
+	}
+
+	var columns []struct {
+		Name string `gorm:"column:attname"`
+		Type string `gorm:"column:coltype"`
+	}
+	err := source.DB.WithContext(ctx).Raw(`
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod) AS coltype
+		FROM pg_attribute a
+		WHERE a.attrelid = to_regclass(?) AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, source.Provider.GenerateTableName(t.BaseID, t.TableID)).
+		Scan(&columns).Error
+	if err != nil {
+		return fmt.Errorf("读取源表结构失败: %w", err)
+	}
+
+	for _, col := range columns {
+		alter := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS "%s" %s`, fullName, col.Name, col.Type)
+		if err := target.DB.WithContext(ctx).Exec(alter).Error; err != nil {
+			return fmt.Errorf("同步列 %s 失败: %w", col.Name, err)
+		}
+	}
+	return nil
+}
+
+// copyTableAll 按 __auto_number 分批全量复制
+func copyTableAll(ctx context.Context, source, target *RegionCluster, t spaceTable) (int64, error) {
+	srcName := source.Provider.GenerateTableName(t.BaseID, t.TableID)
+	dstName := target.Provider.GenerateTableName(t.BaseID, t.TableID)
+
+	var copied int64
+	var lastAuto int64
+	for {
+		var rows []map[string]interface{}
+		if err := source.DB.WithContext(ctx).
+			Table(srcName).
+			Where("__auto_number > ?", lastAuto).
+			Order("__auto_number ASC").
+			Limit(regionCopyBatchSize).
+			Find(&rows).Error; err != nil {
+			return copied, fmt.Errorf("读取源数据失败: %w", err)
+		}
+		if len(rows) == 0 {
+			return copied, nil
+		}
+		if err := upsertRows(ctx, target.DB, dstName, rows); err != nil {
+			return copied, err
+		}
+		copied += int64(len(rows))
+		lastAuto = toInt64(rows[len(rows)-1]["__auto_number"])
+	}
+}
+
+// copyTableSince 复制指定时间之后创建或修改的记录
+func copyTableSince(ctx context.Context, source, target *RegionCluster, t spaceTable, since time.Time) (int64, error) {
+	srcName := source.Provider.GenerateTableName(t.BaseID, t.TableID)
+	dstName := target.Provider.GenerateTableName(t.BaseID, t.TableID)
+
+	var copied int64
+	var lastAuto int64
+	for {
+		var rows []map[string]interface{}
+		if err := source.DB.WithContext(ctx).
+			Table(srcName).
+			Where("(__last_modified_time >= ? OR __created_time >= ?) AND __auto_number > ?", since, since, lastAuto).
+			Order("__auto_number ASC").
+			Limit(regionCopyBatchSize).
+			Find(&rows).Error; err != nil {
+			return copied, fmt.Errorf("读取增量数据失败: %w", err)
+		}
+		if len(rows) == 0 {
+			return copied, nil
+		}
+		if err := upsertRows(ctx, target.DB, dstName, rows); err != nil {
+			return copied, err
+		}
+		copied += int64(len(rows))
+		lastAuto = toInt64(rows[len(rows)-1]["__auto_number"])
+	}
+}
+
+// reconcileTableDeletes 删除目标区域中源区域已删除的记录
+func reconcileTableDeletes(ctx context.Context, source, target *RegionCluster, t spaceTable) (int64, error) {
+	var sourceIDs []string
+	if err := source.DB.WithContext(ctx).
+		Table(source.Provider.GenerateTableName(t.BaseID, t.TableID)).
+		Pluck("__id", &sourceIDs).Error; err != nil {
+		return 0, err
+	}
+	var targetIDs []string
+	dstName := target.Provider.GenerateTableName(t.BaseID, t.TableID)
+	if err := target.DB.WithContext(ctx).Table(dstName).Pluck("__id", &targetIDs).Error; err != nil {
+		return 0, err
+	}
+
+	alive := make(map[string]struct{}, len(sourceIDs))
+	for _, id := range sourceIDs {
+		alive[id] = struct{}{}
+	}
+	stale := make([]string, 0)
+	for _, id := range targetIDs {
+		if _, ok := alive[id]; !ok {
+			stale = append(stale, id)
+		}
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	result := target.DB.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s WHERE __id IN ?", dstName), stale)
+	return result.RowsAffected, result.Error
+}
+
+// syncTableSequence 将目标表的自增序列推进到当前最大值
+func syncTableSequence(ctx context.Context, target *RegionCluster, t spaceTable) error {
+	dstName := target.Provider.GenerateTableName(t.BaseID, t.TableID)
+	sql := fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence(?, '__auto_number'), COALESCE((SELECT MAX(__auto_number) FROM %s), 1))",
+		dstName,
+	)
+	return target.DB.WithContext(ctx).Exec(sql, dstName).Error
+}
+
+// upsertRows 以 __id 为冲突键写入目标表
+func upsertRows(ctx context.Context, db *gorm.DB, tableName string, rows []map[string]interface{}) error {
+	return upsertRowsOn(ctx, db, tableName, "__id", rows)
+}
+
+// upsertRowsOn 以指定列为冲突键写入目标表
+// map 形式的行没有 GORM Schema，需要显式列出冲突时更新的列
+func upsertRowsOn(ctx context.Context, db *gorm.DB, tableName, key string, rows []map[string]interface{}) error {
+	updateCols := make([]string, 0, len(rows[0]))
+	for col := range rows[0] {
+		if col != key {
+			updateCols = append(updateCols, col)
+		}
+	}
+	sort.Strings(updateCols)
+
+	onConflict := clause.OnConflict{Columns: []clause.Column{{Name: key}}, DoNothing: true}
+	if len(updateCols) > 0 {
+		onConflict = clause.OnConflict{
+			Columns:   []clause.Column{{Name: key}},
+			DoUpdates: clause.AssignmentColumns(updateCols),
+		}
+	}
+	err := db.WithContext(ctx).
+		Table(tableName).
+		Clauses(onConflict).
+		Create(&rows).Error
+	if err != nil {
+		return fmt.Errorf("写入目标区域失败: %w", err)
+	}
+	return nil
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int32:
+		return int64(n)
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// RegionRoutingProvider 按数据驻留区域路由的 DBProvider
+// 所有DDL按 baseID 解析所属空间的区域，再委托给该区域集群的Provider执行
+type RegionRoutingProvider struct {
+	router   *RegionRouter
+	fallback DBProvider
+}
+
+// NewRegionRoutingProvider 创建区域路由Provider（fallback 为默认区域Provider）
+func NewRegionRoutingProvider(router *RegionRouter, fallback DBProvider) *RegionRoutingProvider {
+	return &RegionRoutingProvider{
+		router:   router,
+		fallback: fallback,
+	}
+}
+
+// Router 获取区域路由器
+func (p *RegionRoutingProvider) Router() *RegionRouter {
+	return p.router
+}
+
+// providerFor 解析 baseID 所在区域的Provider
+func (p *RegionRoutingProvider) providerFor(ctx context.Context, baseID string) DBProvider {
+	cluster, err := p.router.ClusterForBase(ctx, baseID)
+	if err != nil {
+		logger.Warn("数据区域解析失败，使用默认区域",
+			logger.String("base_id", baseID),
+			logger.ErrorField(err))
+		return p.fallback
+	}
+	return cluster.Provider
+}
+
+// CreateSchema 在所属区域创建Schema
+func (p *RegionRoutingProvider) CreateSchema(ctx context.Context, baseID string) error {
+	return p.providerFor(ctx, baseID).CreateSchema(ctx, baseID)
+}
+
+// DropSchema 在所属区域删除Schema
+func (p *RegionRoutingProvider) DropSchema(ctx context.Context, baseID string) error {
+	return p.providerFor(ctx, baseID).DropSchema(ctx, baseID)
+}
+
+// DropTenantSchema 在空间所属区域删除整个租户Schema（该区域未启用工作空间隔离时无操作）
+func (p *RegionRoutingProvider) DropTenantSchema(ctx context.Context, spaceID string) error {
+	region, err := p.router.RegionForSpace(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	cluster, err := p.router.Cluster(region)
+	if err != nil {
+		return err
+	}
+	if tenant, ok := cluster.Provider.(*TenantSchemaProvider); ok {
+		return tenant.DropTenantSchema(ctx, spaceID)
+	}
+	return nil
+}
+
+// CreatePhysicalTable 在所属区域创建物理表
+func (p *RegionRoutingProvider) CreatePhysicalTable(ctx context.Context, baseID, tableName string) error {
+	return p.providerFor(ctx, baseID).CreatePhysicalTable(ctx, baseID, tableName)
+}
+
+// DropPhysicalTable 在所属区域删除物理表
+func (p *RegionRoutingProvider) DropPhysicalTable(ctx context.Context, baseID, tableName string) error {
+	return p.providerFor(ctx, baseID).DropPhysicalTable(ctx, baseID, tableName)
+}
+
+// AddColumn 添加列
+func (p *RegionRoutingProvider) AddColumn(ctx context.Context, baseID, tableName string, columnDef ColumnDefinition) error {
+	return p.providerFor(ctx, baseID).AddColumn(ctx, baseID, tableName, columnDef)
+}
+
+// AlterColumn 修改列
+func (p *RegionRoutingProvider) AlterColumn(ctx context.Context, baseID, tableName, columnName string, newDef ColumnDefinition) error {
+	return p.providerFor(ctx, baseID).AlterColumn(ctx, baseID, tableName, columnName, newDef)
+}
+
+// DropColumn 删除列
+func (p *RegionRoutingProvider) DropColumn(ctx context.Context, baseID, tableName, columnName string) error {
+	return p.providerFor(ctx, baseID).DropColumn(ctx, baseID, tableName, columnName)
+}
+
+// AddUniqueConstraint 添加唯一性约束
+func (p *RegionRoutingProvider) AddUniqueConstraint(ctx context.Context, baseID, tableName, columnName, constraintName string) error {
+	return p.providerFor(ctx, baseID).AddUniqueConstraint(ctx, baseID, tableName, columnName, constraintName)
+}
+
+// DropConstraint 删除约束
+func (p *RegionRoutingProvider) DropConstraint(ctx context.Context, baseID, tableName, constraintName string) error {
+	return p.providerFor(ctx, baseID).DropConstraint(ctx, baseID, tableName, constraintName)
+}
+
+// SetNotNull 设置NOT NULL
+func (p *RegionRoutingProvider) SetNotNull(ctx context.Context, baseID, tableName, columnName string) error {
+	return p.providerFor(ctx, baseID).SetNotNull(ctx, baseID, tableName, columnName)
+}
+
+// DropNotNull 移除NOT NULL
+func (p *RegionRoutingProvider) DropNotNull(ctx context.Context, baseID, tableName, columnName string) error {
+	return p.providerFor(ctx, baseID).DropNotNull(ctx, baseID, tableName, columnName)
+}
+
+// AddCheckConstraint 添加CHECK约束
+func (p *RegionRoutingProvider) AddCheckConstraint(ctx context.Context, baseID, tableName, constraintName, checkExpression string) error {
+	return p.providerFor(ctx, baseID).AddCheckConstraint(ctx, baseID, tableName, constraintName, checkExpression)
+}
+
+// CreateIndex 在所属区域创建索引
+func (p *RegionRoutingProvider) CreateIndex(ctx context.Context, baseID, tableName, indexName, definition string) error {
+	return p.providerFor(ctx, baseID).CreateIndex(ctx, baseID, tableName, indexName, definition)
+}
+
+// GenerateTableName 生成完整表名（各区域命名规则一致）
+func (p *RegionRoutingProvider) GenerateTableName(baseID, tableID string) string {
+	return p.providerFor(context.Background(), baseID).GenerateTableName(baseID, tableID)
+}
+
+// ResolveTableName 由所属区域的Provider解析完整表名
+func (p *RegionRoutingProvider) ResolveTableName(ctx context.Context, baseID, tableID string) (string, error) {
+	return p.providerFor(ctx, baseID).ResolveTableName(ctx, baseID, tableID)
+}
+
+// MapFieldTypeToDBType 字段类型映射
+func (p *RegionRoutingProvider) MapFieldTypeToDBType(fieldType string) string {
+	return p.fallback.MapFieldTypeToDBType(fieldType)
+}
+
+// DriverName 返回驱动名称
+func (p *RegionRoutingProvider) DriverName() string {
+	return p.fallback.DriverName()
+}
+
+// SupportsSchema 是否支持Schema
+func (p *RegionRoutingProvider) SupportsSchema() bool {
+	return p.fallback.SupportsSchema()
+}
@@ -0,0 +1,352 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// 空间区域绑定状态
+const (
+	SpaceRegionActive    = "active"
+	SpaceRegionMigrating = "migrating" // 复制中：读写仍在源区域
+	SpaceRegionCutover   = "cutover"   // 切换窗口：拒绝写入，完成最后一轮追赶后切换区域
+)
+
+const (
+	// regionBindingTTL 区域绑定缓存有效期，其他实例最迟在该时间后看到绑定变更
+	regionBindingTTL = 10 * time.Second
+	// regionCutoverMaxAge 切换窗口的最长持续时间，超过后视为迁移已中断，恢复源区域写入
+	regionCutoverMaxAge = 10 * time.Minute
+)
+
+var (
+	// ErrRegionCutover 空间正在切换区域，暂时拒绝写入
+	ErrRegionCutover = errors.New("空间正在切换数据区域，请稍后重试")
+	// ErrRegionMismatch 访问的集群不是空间数据所在的区域
+	ErrRegionMismatch = errors.New("数据不在当前区域")
+)
+
+// regionBinding 缓存的区域绑定
+type regionBinding struct {
+	region   string
+	status   string
+	since    time.Time // 状态最后修改时间
+	loadedAt time.Time
+}
+
+// RegionCluster 区域数据库集群
+type RegionCluster struct {
+	Name     string
+	DB       *gorm.DB
+	Provider DBProvider
+}
+
+// RegionRouter 数据驻留路由器
+// 元数据（space/base/table_meta/field 等控制面表）始终存放在主库，
+// 每个工作空间的动态数据表（数据面）存放在其绑定区域的集群中
+type RegionRouter struct {
+	primary       *gorm.DB
+	defaultRegion string
+
+	mu       sync.RWMutex
+	clusters map[string]*RegionCluster
+
+	// spaceID -> *regionBinding 缓存（regionBindingTTL 后重新加载，迁移在其他实例上发起时同样生效）
+	bindings sync.Map
+	// baseID -> spaceID 缓存
+	baseSpaces sync.Map
+	// 物理表 Schema -> spaceID 缓存（见 spaceForSchema）
+	schemaSpaces sync.Map
+	// tenantPrefix 工作空间隔离时租户Schema的前缀（为空表示按 Base 划分Schema）
+	tenantPrefix string
+
+	now         func() time.Time
+	loadBinding func(ctx context.Context, spaceID string) (*models.SpaceRegion, error)
+}
+
+// NewRegionRouter 创建数据驻留路由器
+// primary 为主库（同时作为默认区域的集群）
+func NewRegionRouter(primary *gorm.DB, primaryProvider DBProvider, defaultRegion string) *RegionRouter {
+	r := &RegionRouter{
+		primary:       primary,
+		defaultRegion: defaultRegion,
+		clusters:      make(map[string]*RegionCluster),
+		now:           time.Now,
+	}
+	r.loadBinding = r.GetBinding
+	r.AddCluster(defaultRegion, primary, primaryProvider)
+	return r
+}
+
+// AddCluster 注册区域集群
+func (r *RegionRouter) AddCluster(region string, db *gorm.DB, provider DBProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clusters[region] = &RegionCluster{Name: region, DB: db, Provider: provider}
+}
+
+// SetTenantSchemaPrefix 启用工作空间隔离时设置租户Schema前缀
+func (r *RegionRouter) SetTenantSchemaPrefix(prefix string) {
+	if prefix == "" {
+		prefix = DefaultTenantSchemaPrefix
+	}
+	r.tenantPrefix = prefix
+}
+
+// EnableGuard 在所有区域集群上注册区域校验插件（见 RegionGuardPlugin）
+func (r *RegionRouter) EnableGuard() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name, cluster := range r.clusters {
+		if err := cluster.DB.Use(NewRegionGuardPlugin(r, name)); err != nil {
+			return fmt.Errorf("注册区域 %s 的校验插件失败: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// DefaultRegion 默认区域
+func (r *RegionRouter) DefaultRegion() string {
+	return r.defaultRegion
+}
+
+// Regions 列出所有已注册区域
+func (r *RegionRouter) Regions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	regions := make([]string, 0, len(r.clusters))
+	for name := range r.clusters {
+		regions = append(regions, name)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Cluster 获取区域集群
+func (r *RegionRouter) Cluster(region string) (*RegionCluster, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cluster, ok := r.clusters[region]
+	if !ok {
+		return nil, fmt.Errorf("未知的数据区域: %s", region)
+	}
+	return cluster, nil
+}
+
+// RegionForSpace 获取空间绑定的区域（未绑定时使用默认区域）
+func (r *RegionRouter) RegionForSpace(ctx context.Context, spaceID string) (string, error) {
+	binding, err := r.binding(ctx, spaceID)
+	if err != nil {
+		return "", err
+	}
+	return binding.region, nil
+}
+
+// binding 读取空间的区域绑定（缓存 regionBindingTTL）
+func (r *RegionRouter) binding(ctx context.Context, spaceID string) (*regionBinding, error) {
+	now := r.now()
+	if v, ok := r.bindings.Load(spaceID); ok {
+		if cached := v.(*regionBinding); now.Sub(cached.loadedAt) < regionBindingTTL {
+			return cached, nil
+		}
+	}
+
+	record, err := r.loadBinding(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	binding := &regionBinding{region: r.defaultRegion, status: SpaceRegionActive, loadedAt: now}
+	if record != nil {
+		binding.region = record.Region
+		binding.status = record.Status
+		binding.since = record.CreatedTime
+		if record.LastModifiedTime != nil {
+			binding.since = *record.LastModifiedTime
+		}
+	}
+	r.bindings.Store(spaceID, binding)
+	return binding, nil
+}
+
+// writeFenced 是否处于拒绝写入的切换窗口（超过 regionCutoverMaxAge 视为迁移已中断，源区域仍是当前区域）
+func (b *regionBinding) writeFenced(now time.Time) bool {
+	return b.status == SpaceRegionCutover && now.Sub(b.since) < regionCutoverMaxAge
+}
+
+// CheckAccess 校验在 region 集群上读写空间数据是否合法
+// 集群不是空间当前区域时返回 ErrRegionMismatch；切换窗口内写入返回 ErrRegionCutover
+func (r *RegionRouter) CheckAccess(ctx context.Context, spaceID, region string, write bool) error {
+	binding, err := r.binding(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	if binding.region != region {
+		return fmt.Errorf("%w: 空间 %s 位于区域 %s，访问的是 %s", ErrRegionMismatch, spaceID, binding.region, region)
+	}
+	if write && binding.writeFenced(r.now()) {
+		return ErrRegionCutover
+	}
+	return nil
+}
+
+// GetBinding 查询空间的区域绑定记录（不存在时返回 nil）
+func (r *RegionRouter) GetBinding(ctx context.Context, spaceID string) (*models.SpaceRegion, error) {
+	var binding models.SpaceRegion
+	err := r.primary.WithContext(ctx).Where("space_id = ?", spaceID).Take(&binding).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询空间区域绑定失败: %w", err)
+	}
+	return &binding, nil
+}
+
+// BindSpace 将空间绑定到指定区域
+// 仅用于尚无数据表的空间：已有数据时改变区域会使数据不可见，必须使用 RegionMigrator 在线迁移
+func (r *RegionRouter) BindSpace(ctx context.Context, spaceID, region string) error {
+	if _, err := r.Cluster(region); err != nil {
+		return err
+	}
+	current, err := r.RegionForSpace(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	if current != region {
+		var tables int64
+		if err := r.primary.WithContext(ctx).
+			Table("table_meta").
+			Joins("JOIN base ON base.id = table_meta.base_id").
+			Where("base.space_id = ? AND table_meta.deleted_time IS NULL", spaceID).
+			Count(&tables).Error; err != nil {
+			return fmt.Errorf("查询空间数据表失败: %w", err)
+		}
+		if tables > 0 {
+			return fmt.Errorf("空间 %s 已有 %d 张数据表，请使用在线迁移更换区域", spaceID, tables)
+		}
+	}
+	return r.bindSpace(ctx, spaceID, region)
+}
+
+// bindSpace 写入区域绑定（不检查已有数据）
+func (r *RegionRouter) bindSpace(ctx context.Context, spaceID, region string) error {
+	now := time.Now()
+	binding := models.SpaceRegion{
+		SpaceID:          spaceID,
+		Region:           region,
+		Status:           SpaceRegionActive,
+		CreatedTime:      now,
+		LastModifiedTime: &now,
+	}
+	err := r.primary.WithContext(ctx).
+		Where("space_id = ?", spaceID).
+		Assign(map[string]interface{}{
+			"region":             region,
+			"status":             SpaceRegionActive,
+			"target_region":      nil,
+			"migration_error":    nil,
+			"last_modified_time": now,
+		}).
+		FirstOrCreate(&binding).Error
+	if err != nil {
+		return fmt.Errorf("保存空间区域绑定失败: %w", err)
+	}
+
+	r.bindings.Delete(spaceID)
+	return nil
+}
+
+// updateBinding 更新绑定状态并清除本实例缓存（其他实例在 regionBindingTTL 内刷新）
+func (r *RegionRouter) updateBinding(ctx context.Context, spaceID string, updates map[string]interface{}) error {
+	updates["last_modified_time"] = time.Now()
+	err := r.primary.WithContext(ctx).
+		Model(&models.SpaceRegion{}).
+		Where("space_id = ?", spaceID).
+		Updates(updates).Error
+	r.bindings.Delete(spaceID)
+	return err
+}
+
+// SpaceForBase 解析 Base 所属空间（优先使用上下文中的租户）
+func (r *RegionRouter) SpaceForBase(ctx context.Context, baseID string) (string, error) {
+	if spaceID, ok := TenantFrom(ctx); ok {
+		return spaceID, nil
+	}
+	if v, ok := r.baseSpaces.Load(baseID); ok {
+		return v.(string), nil
+	}
+
+	var row struct {
+		SpaceID string `gorm:"column:space_id"`
+	}
+	if err := r.primary.WithContext(ctx).
+		Table("base").
+		Select("space_id").
+		Where("id = ?", baseID).
+		Take(&row).Error; err != nil {
+		return "", fmt.Errorf("解析Base所属空间失败 (base=%s): %w", baseID, err)
+	}
+
+	r.baseSpaces.Store(baseID, row.SpaceID)
+	return row.SpaceID, nil
+}
+
+// spaceForSchema 解析物理表 Schema 所属空间（不是数据表 Schema 时返回空）
+// 按 Base 划分Schema时 Schema 即 baseID；工作空间隔离时为租户前缀 + 小写的 spaceID
+func (r *RegionRouter) spaceForSchema(ctx context.Context, schema string) (string, error) {
+	if v, ok := r.schemaSpaces.Load(schema); ok {
+		return v.(string), nil
+	}
+
+	var row struct {
+		SpaceID string `gorm:"column:space_id"`
+	}
+	query := r.primary.WithContext(ctx).Table("base").Select("space_id").Where("id = ?", schema)
+	if r.tenantPrefix != "" && strings.HasPrefix(schema, r.tenantPrefix) {
+		query = r.primary.WithContext(ctx).Table("space").Select("id AS space_id").
+			Where("LOWER(id) = ?", strings.TrimPrefix(schema, r.tenantPrefix))
+	}
+	err := query.Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 不缓存：Base 可能尚未提交
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("解析Schema所属空间失败 (schema=%s): %w", schema, err)
+	}
+
+	r.schemaSpaces.Store(schema, row.SpaceID)
+	return row.SpaceID, nil
+}
+
+// ClusterForBase 获取 Base 数据所在的区域集群
+func (r *RegionRouter) ClusterForBase(ctx context.Context, baseID string) (*RegionCluster, error) {
+	spaceID, err := r.SpaceForBase(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	region, err := r.RegionForSpace(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return r.Cluster(region)
+}
+
+// DBForBase 获取 Base 数据面的数据库连接
+// 解析失败时回退到主库，保证单区域部署行为不变
+func (r *RegionRouter) DBForBase(ctx context.Context, baseID string) *gorm.DB {
+	cluster, err := r.ClusterForBase(ctx, baseID)
+	if err != nil {
+		return r.primary
+	}
+	return cluster.DB
+}
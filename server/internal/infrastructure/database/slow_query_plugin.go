@@ -0,0 +1,245 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+const (
+	slowQueryStartKey = "luckdb:slow_query_start"
+
+	// maxSlowStatementLength 慢查询记录中 SQL 的最大长度
+	maxSlowStatementLength = 2000
+
+	explainTimeout = 2 * time.Second
+)
+
+var slowQueryLog = logger.Named("database.slow_query")
+
+// SlowQueryPlugin GORM 慢查询插件 ✨
+// 按动态表（schema.table）汇总查询次数与耗时，记录超过阈值的查询及其执行计划提示
+type SlowQueryPlugin struct {
+	cfg   config.DBSlowQueryConfig
+	stats *QueryStatistics
+
+	explaining atomic.Bool // 同一时间最多执行一条 EXPLAIN，避免慢查询风暴时加重数据库负担
+}
+
+// NewSlowQueryPlugin 创建慢查询插件
+func NewSlowQueryPlugin(cfg config.DBSlowQueryConfig, stats *QueryStatistics) *SlowQueryPlugin {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 200 * time.Millisecond
+	}
+	return &SlowQueryPlugin{cfg: cfg, stats: stats}
+}
+
+// Name 插件名称
+func (p *SlowQueryPlugin) Name() string {
+	return "luckdb:slow_query"
+}
+
+// Initialize 注册 GORM 回调
+func (p *SlowQueryPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("slow_query:before_create", p.before); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("slow_query:after_create", p.after("INSERT")); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("slow_query:before_query", p.before); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("slow_query:after_query", p.after("SELECT")); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("slow_query:before_update", p.before); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("slow_query:after_update", p.after("UPDATE")); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("slow_query:before_delete", p.before); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("slow_query:after_delete", p.after("DELETE")); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("slow_query:before_row", p.before); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("slow_query:after_row", p.after("")); err != nil {
+		return err
+	}
+	if err := cb.Raw().Before("gorm:raw").Register("slow_query:before_raw", p.before); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("slow_query:after_raw", p.after(""))
+}
+
+func (p *SlowQueryPlugin) before(db *gorm.DB) {
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+func (p *SlowQueryPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(slowQueryStartKey)
+		if !ok || db.Statement == nil {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+		slow := elapsed >= p.cfg.Threshold
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+
+		sql := db.Statement.SQL.String()
+		op := operation
+		if op == "" {
+			op = sqlOperation(sql)
+		}
+
+		schema, table := parseDynamicTable(sql)
+		if table != "" {
+			p.stats.Record(QueryRecord{
+				Schema:       schema,
+				Table:        table,
+				Duration:     elapsed,
+				RowsAffected: db.Statement.RowsAffected,
+				Failed:       failed,
+				Slow:         slow,
+			})
+		}
+		if slow {
+			p.recordSlow(db, op, schema, table, elapsed)
+		}
+	}
+}
+
+// recordSlow 记录慢查询：保存到最近慢查询列表并输出日志
+func (p *SlowQueryPlugin) recordSlow(db *gorm.DB, operation, schema, table string, elapsed time.Duration) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// 只保存参数化的语句（内联的字符串字面量同样脱敏），记录值不进入日志与统计接口；
+	// 提示仍基于绑定参数后的语句判断（如 LIKE 的前导通配符）
+	sql := db.Statement.SQL.String()
+	entry := &SlowQueryEntry{
+		Schema:       schema,
+		Table:        table,
+		Operation:    operation,
+		SQL:          truncateStatement(redactLiterals(sql)),
+		Duration:     elapsed,
+		RowsAffected: db.Statement.RowsAffected,
+		Hints:        planHints(operation, db.Dialector.Explain(sql, db.Statement.Vars...), db.Statement.RowsAffected),
+		TraceID:      tracing.TraceIDFromContext(ctx),
+		Timestamp:    time.Now(),
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		entry.Error = db.Error.Error()
+	}
+	p.stats.AddSlowQuery(entry)
+
+	slowQueryLog.Warn(ctx, "慢查询",
+		logger.String("operation", operation),
+		logger.String("schema", schema),
+		logger.String("table", table),
+		logger.Duration("duration", elapsed),
+		logger.Int64("rows", entry.RowsAffected),
+		logger.Strings("hints", entry.Hints),
+		logger.String("sql", entry.SQL),
+	)
+
+	if p.cfg.Explain && operation == "SELECT" && entry.Error == "" {
+		p.explain(ctx, db, sql, append([]interface{}(nil), db.Statement.Vars...), entry)
+	}
+}
+
+// explain 异步执行 EXPLAIN（参数化，不带 ANALYZE，不会真正执行语句）并回填执行计划
+func (p *SlowQueryPlugin) explain(ctx context.Context, db *gorm.DB, sql string, vars []interface{}, entry *SlowQueryEntry) {
+	if !p.explaining.CompareAndSwap(false, true) {
+		return
+	}
+	// 直接使用底层连接池，绕过 GORM 回调，避免 EXPLAIN 自身被统计
+	sqlDB, err := db.DB()
+	if err != nil {
+		p.explaining.Store(false)
+		return
+	}
+
+	go func() {
+		defer p.explaining.Store(false)
+
+		// 保留触发查询的链路，执行计划日志可与慢查询关联
+		ctx, cancel := context.WithTimeout(tracing.Detach(ctx), explainTimeout)
+		defer cancel()
+
+		rows, err := sqlDB.QueryContext(ctx, "EXPLAIN "+sql, vars...)
+		if err != nil {
+			slowQueryLog.Debug(ctx, "获取慢查询执行计划失败", logger.ErrorField(err))
+			return
+		}
+		defer rows.Close()
+
+		var plan []string
+		for rows.Next() && len(plan) < maxPlanLines {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return
+			}
+			// 执行计划的过滤条件中带有绑定的参数值
+			plan = append(plan, redactLiterals(line))
+		}
+		if len(plan) == 0 {
+			return
+		}
+
+		hints := explainHints(plan)
+		p.stats.setPlan(entry, plan, hints)
+		slowQueryLog.Info(ctx, "慢查询执行计划",
+			logger.String("table", entry.Table),
+			logger.String("plan", strings.Join(plan, "\n")),
+			logger.Strings("hints", hints),
+		)
+	}()
+}
+
+// redactLiterals 将语句中的字符串字面量替换为 '?'
+func redactLiterals(sql string) string {
+	return stringLiteralRegexp.ReplaceAllString(sql, "'?'")
+}
+
+// sqlOperation 从原生 SQL 推断操作类型
+func sqlOperation(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if i := strings.IndexAny(sql, " \t\n("); i > 0 {
+		sql = sql[:i]
+	}
+	switch op := strings.ToUpper(sql); op {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH":
+		return op
+	default:
+		return "RAW"
+	}
+}
+
+func truncateStatement(sql string) string {
+	if len(sql) > maxSlowStatementLength {
+		return sql[:maxSlowStatementLength] + "..."
+	}
+	return sql
+}
@@ -213,6 +213,11 @@ func (s *SQLiteProvider) AddCheckConstraint(ctx context.Context, schemaName, tab
 	return fmt.Errorf("SQLite不支持添加CHECK约束到现有表")
 }
 
+// CreateIndex SQLite不支持 GIN/GiST 等索引类型
+func (s *SQLiteProvider) CreateIndex(ctx context.Context, schemaName, tableName, indexName, definition string) error {
+	return fmt.Errorf("SQLite不支持创建该类型的索引")
+}
+
 // ==================== 工具方法 ====================
 
 // GenerateTableName 生成完整的表名（带前缀）
@@ -221,16 +226,24 @@ func (s *SQLiteProvider) GenerateTableName(baseID, tableID string) string {
 	return fmt.Sprintf("%s_%s", baseID, tableID)
 }
 
+// ResolveTableName 生成完整表名（无需解析）
+func (s *SQLiteProvider) ResolveTableName(_ context.Context, baseID, tableID string) (string, error) {
+	return s.GenerateTableName(baseID, tableID), nil
+}
+
 // MapFieldTypeToDBType 将字段类型映射到SQLite类型
 func (s *SQLiteProvider) MapFieldTypeToDBType(fieldType string) string {
 	// SQLite类型系统较简单，映射到5种存储类
 	sqliteMapping := map[string]string{
 		"singleLineText": "TEXT",
 		"longText":       "TEXT",
+		"richText":       "TEXT",
 		"number":         "REAL",
 		"rating":         "INTEGER",
 		"percent":        "REAL",
 		"currency":       "REAL",
+		"duration":       "REAL",
+		"progress":       "REAL",
 		"date":           "DATETIME",
 		"checkbox":       "INTEGER", // 0 or 1
 		"singleSelect":   "TEXT",
@@ -246,6 +259,7 @@ func (s *SQLiteProvider) MapFieldTypeToDBType(fieldType string) string {
 		"url":            "TEXT",
 		"email":          "TEXT",
 		"phone":          "TEXT",
+		"location":       "TEXT", // JSON string
 	}
 
 	if dbType, ok := sqliteMapping[fieldType]; ok {
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// TenantMigration 租户级迁移
+// 在每个租户Schema内独立执行，版本记录保存在租户Schema的 __tenant_migrations 表中
+type TenantMigration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, tx *gorm.DB, spaceID, schema string) error
+}
+
+// TenantMigrationResult 单个租户的迁移结果
+type TenantMigrationResult struct {
+	SpaceID  string   `json:"spaceId"`
+	Schema   string   `json:"schema"`
+	Applied  []string `json:"applied"`
+	Error    string   `json:"error,omitempty"`
+	Duration string   `json:"duration"`
+}
+
+// TenantMigrator 租户迁移执行器
+type TenantMigrator struct {
+	db         *gorm.DB
+	provider   DBProvider
+	prefix     string
+	migrations []TenantMigration
+}
+
+// NewTenantMigrator 创建租户迁移执行器（内置迁移已注册）
+// provider 需为底层Provider（非 TenantSchemaProvider），Schema名称由迁移器直接计算
+func NewTenantMigrator(db *gorm.DB, provider DBProvider, prefix string) *TenantMigrator {
+	if prefix == "" {
+		prefix = DefaultTenantSchemaPrefix
+	}
+	m := &TenantMigrator{
+		db:       db,
+		provider: provider,
+		prefix:   prefix,
+	}
+	m.Register(TenantMigration{
+		Version: 1,
+		Name:    "adopt_base_schemas",
+		Up:      adoptBaseSchemas,
+	})
+	return m
+}
+
+// Register 注册租户迁移
+func (m *TenantMigrator) Register(migration TenantMigration) {
+	m.migrations = append(m.migrations, migration)
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+}
+
+// MigrateAll 对所有未删除的空间执行租户迁移
+// 单个租户失败不影响其他租户，失败信息记录在结果中
+func (m *TenantMigrator) MigrateAll(ctx context.Context) ([]TenantMigrationResult, error) {
+	var spaceIDs []string
+	if err := m.db.WithContext(ctx).
+		Table("space").
+		Where("deleted_time IS NULL").
+		Order("created_time ASC").
+		Pluck("id", &spaceIDs).Error; err != nil {
+		return nil, fmt.Errorf("查询空间列表失败: %w", err)
+	}
+
+	results := make([]TenantMigrationResult, 0, len(spaceIDs))
+	for _, spaceID := range spaceIDs {
+		results = append(results, m.MigrateTenant(ctx, spaceID))
+	}
+	return results, nil
+}
+
+// MigrateTenant 对单个租户执行所有待应用的迁移
+func (m *TenantMigrator) MigrateTenant(ctx context.Context, spaceID string) (result TenantMigrationResult) {
+	start := time.Now()
+	schema := TenantSchemaName(m.prefix, spaceID)
+	result = TenantMigrationResult{SpaceID: spaceID, Schema: schema, Applied: []string{}}
+
+	defer func() {
+		result.Duration = time.Since(start).String()
+	}()
+
+	if err := m.provider.CreateSchema(ctx, schema); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	versionTable := fmt.Sprintf(`"%s"."__tenant_migrations"`, schema)
+	createSQL := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version INTEGER PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`, versionTable)
+	if err := m.db.WithContext(ctx).Exec(createSQL).Error; err != nil {
+		result.Error = fmt.Sprintf("创建迁移版本表失败: %v", err)
+		return result
+	}
+
+	var applied []int
+	if err := m.db.WithContext(ctx).Table(versionTable).Pluck("version", &applied).Error; err != nil {
+		result.Error = fmt.Sprintf("读取迁移版本失败: %v", err)
+		return result
+	}
+	appliedSet := make(map[int]bool, len(applied))
+	for _, v := range applied {
+		appliedSet[v] = true
+	}
+
+	for _, migration := range m.migrations {
+		if appliedSet[migration.Version] {
+			continue
+		}
+
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(ctx, tx, spaceID, schema); err != nil {
+				return err
+			}
+			return tx.Exec(
+				fmt.Sprintf("INSERT INTO %s (version, name) VALUES (?, ?)", versionTable),
+				migration.Version, migration.Name,
+			).Error
+		})
+		if err != nil {
+			result.Error = fmt.Sprintf("迁移 %d_%s 失败: %v", migration.Version, migration.Name, err)
+			logger.Error("租户迁移失败",
+				logger.String("space_id", spaceID),
+				logger.Int("version", migration.Version),
+				logger.ErrorField(err))
+			return result
+		}
+
+		result.Applied = append(result.Applied, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		logger.Info("✅ 租户迁移已应用",
+			logger.String("space_id", spaceID),
+			logger.String("schema", schema),
+			logger.Int("version", migration.Version))
+	}
+
+	return result
+}
+
+// adoptBaseSchemas 将旧模式（每个Base独立Schema）下的物理表迁入租户Schema
+// ALTER TABLE ... SET SCHEMA 只修改目录信息，不复制数据
+func adoptBaseSchemas(ctx context.Context, tx *gorm.DB, spaceID, schema string) error {
+	var tables []struct {
+		ID     string `gorm:"column:id"`
+		BaseID string `gorm:"column:base_id"`
+	}
+	if err := tx.WithContext(ctx).
+		Table("table_meta").
+		Select("table_meta.id, table_meta.base_id").
+		Joins("JOIN base ON base.id = table_meta.base_id").
+		Where("base.space_id = ? AND table_meta.deleted_time IS NULL", spaceID).
+		Find(&tables).Error; err != nil {
+		return fmt.Errorf("查询空间物理表失败: %w", err)
+	}
+
+	for _, t := range tables {
+		var exists bool
+		if err := tx.WithContext(ctx).Raw(
+			"SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = ? AND table_name = ?)",
+			t.BaseID, t.ID,
+		).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		moveSQL := fmt.Sprintf(`ALTER TABLE "%s"."%s" SET SCHEMA "%s"`, t.BaseID, t.ID, schema)
+		if err := tx.WithContext(ctx).Exec(moveSQL).Error; err != nil {
+			return fmt.Errorf("迁移物理表 %s 失败: %w", t.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,329 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// TenancyMode 多租户隔离模式
+type TenancyMode string
+
+const (
+	// TenancyModeBase 默认模式：每个Base独立Schema（对齐原 Teable 项目）
+	TenancyModeBase TenancyMode = "base"
+	// TenancyModeWorkspace 企业隔离模式：每个Space（工作空间）独立Schema，空间内所有Base的物理表共享该Schema
+	TenancyModeWorkspace TenancyMode = "workspace"
+)
+
+// DefaultTenantSchemaPrefix 默认的租户Schema前缀
+const DefaultTenantSchemaPrefix = "ws_"
+
+// unresolvedTenantSchema 无法解析所属空间时使用的Schema（不会被创建，访问必然失败）
+const unresolvedTenantSchema = "luckdb_unresolved_tenant"
+
+// tenantResolveTimeout 无上下文时查询Base所属空间的超时
+const tenantResolveTimeout = 5 * time.Second
+
+type tenantCtxKey string
+
+const tenantKey tenantCtxKey = "tenant_space_id"
+
+// WithTenant 将租户（Space ID）写入上下文
+// 仓储层优先使用上下文中的租户解析Schema，避免额外的元数据查询
+func WithTenant(ctx context.Context, spaceID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, tenantKey, spaceID)
+}
+
+// TenantFrom 从上下文中提取租户（Space ID）
+func TenantFrom(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	if s, ok := ctx.Value(tenantKey).(string); ok && s != "" {
+		return s, true
+	}
+	return "", false
+}
+
+// TenantSchemaName 根据Space ID生成租户Schema名称
+func TenantSchemaName(prefix, spaceID string) string {
+	if prefix == "" {
+		prefix = DefaultTenantSchemaPrefix
+	}
+	return strings.ToLower(prefix + spaceID)
+}
+
+// TenantSchemaProvider 按工作空间隔离的 DBProvider 装饰器
+// 调用方仍然以 baseID 作为 schemaName 传入（与原有代码保持一致），
+// 由装饰器将其解析为所属工作空间的租户Schema，再委托给底层Provider执行
+type TenantSchemaProvider struct {
+	inner  DBProvider
+	db     *gorm.DB
+	prefix string
+
+	// baseID -> spaceID 缓存（Base不会跨空间移动，缓存无需过期）
+	baseSpaces sync.Map
+}
+
+// NewTenantSchemaProvider 创建工作空间隔离的Provider
+func NewTenantSchemaProvider(inner DBProvider, db *gorm.DB, prefix string) *TenantSchemaProvider {
+	if prefix == "" {
+		prefix = DefaultTenantSchemaPrefix
+	}
+	return &TenantSchemaProvider{
+		inner:  inner,
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+// Inner 获取被装饰的底层Provider
+func (p *TenantSchemaProvider) Inner() DBProvider {
+	return p.inner
+}
+
+// ResolveSchema 将 baseID 解析为租户Schema名称
+// 解析顺序：上下文租户 -> 缓存 -> base 元数据表；无法确定所属空间时返回错误（不会退回 Base Schema）
+func (p *TenantSchemaProvider) ResolveSchema(ctx context.Context, baseID string) (string, error) {
+	if spaceID, ok := TenantFrom(ctx); ok {
+		return TenantSchemaName(p.prefix, spaceID), nil
+	}
+
+	spaceID, err := p.spaceOfBase(ctx, baseID)
+	if err != nil {
+		return "", err
+	}
+	return TenantSchemaName(p.prefix, spaceID), nil
+}
+
+// RegisterBase 预先登记 Base 所属的空间（创建Base时元数据尚未落库）
+func (p *TenantSchemaProvider) RegisterBase(baseID, spaceID string) {
+	p.baseSpaces.Store(baseID, spaceID)
+}
+
+// spaceOfBase 查询 Base 所属的空间
+func (p *TenantSchemaProvider) spaceOfBase(ctx context.Context, baseID string) (string, error) {
+	if v, ok := p.baseSpaces.Load(baseID); ok {
+		return v.(string), nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var row struct {
+		SpaceID string `gorm:"column:space_id"`
+	}
+	err := p.db.WithContext(ctx).
+		Table("base").
+		Select("space_id").
+		Where("id = ?", baseID).
+		Take(&row).Error
+	if err != nil {
+		return "", fmt.Errorf("解析Base所属空间失败 (base=%s): %w", baseID, err)
+	}
+	if row.SpaceID == "" {
+		return "", fmt.Errorf("Base未归属任何空间 (base=%s)", baseID)
+	}
+
+	p.baseSpaces.Store(baseID, row.SpaceID)
+	return row.SpaceID, nil
+}
+
+// ==================== Schema管理 ====================
+
+// CreateSchema 创建租户Schema（幂等，同一空间的多个Base共享）
+func (p *TenantSchemaProvider) CreateSchema(ctx context.Context, baseID string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.CreateSchema(ctx, schema)
+}
+
+// DropSchema 删除Base时只删除该Base的物理表，租户Schema本身保留
+func (p *TenantSchemaProvider) DropSchema(ctx context.Context, baseID string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+
+	var tableIDs []string
+	if err := p.db.WithContext(ctx).
+		Table("table_meta").
+		Where("base_id = ?", baseID).
+		Pluck("id", &tableIDs).Error; err != nil {
+		return fmt.Errorf("查询Base物理表失败: %w", err)
+	}
+
+	for _, tableID := range tableIDs {
+		if err := p.inner.DropPhysicalTable(ctx, schema, tableID); err != nil {
+			return err
+		}
+	}
+
+	p.baseSpaces.Delete(baseID)
+	return nil
+}
+
+// DropTenantSchema 删除整个租户Schema（删除空间时使用）
+func (p *TenantSchemaProvider) DropTenantSchema(ctx context.Context, spaceID string) error {
+	return p.inner.DropSchema(ctx, TenantSchemaName(p.prefix, spaceID))
+}
+
+// ==================== 动态表管理 ====================
+
+// CreatePhysicalTable 在租户Schema中创建物理表
+func (p *TenantSchemaProvider) CreatePhysicalTable(ctx context.Context, baseID, tableName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.CreatePhysicalTable(ctx, schema, tableName)
+}
+
+// DropPhysicalTable 删除租户Schema中的物理表
+func (p *TenantSchemaProvider) DropPhysicalTable(ctx context.Context, baseID, tableName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.DropPhysicalTable(ctx, schema, tableName)
+}
+
+// ==================== 列管理 ====================
+
+// AddColumn 添加列
+func (p *TenantSchemaProvider) AddColumn(ctx context.Context, baseID, tableName string, columnDef ColumnDefinition) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.AddColumn(ctx, schema, tableName, columnDef)
+}
+
+// AlterColumn 修改列
+func (p *TenantSchemaProvider) AlterColumn(ctx context.Context, baseID, tableName, columnName string, newDef ColumnDefinition) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.AlterColumn(ctx, schema, tableName, columnName, newDef)
+}
+
+// DropColumn 删除列
+func (p *TenantSchemaProvider) DropColumn(ctx context.Context, baseID, tableName, columnName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.DropColumn(ctx, schema, tableName, columnName)
+}
+
+// ==================== 约束管理 ====================
+
+// AddUniqueConstraint 添加唯一性约束
+func (p *TenantSchemaProvider) AddUniqueConstraint(ctx context.Context, baseID, tableName, columnName, constraintName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.AddUniqueConstraint(ctx, schema, tableName, columnName, constraintName)
+}
+
+// DropConstraint 删除约束
+func (p *TenantSchemaProvider) DropConstraint(ctx context.Context, baseID, tableName, constraintName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.DropConstraint(ctx, schema, tableName, constraintName)
+}
+
+// SetNotNull 设置NOT NULL
+func (p *TenantSchemaProvider) SetNotNull(ctx context.Context, baseID, tableName, columnName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.SetNotNull(ctx, schema, tableName, columnName)
+}
+
+// DropNotNull 移除NOT NULL
+func (p *TenantSchemaProvider) DropNotNull(ctx context.Context, baseID, tableName, columnName string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.DropNotNull(ctx, schema, tableName, columnName)
+}
+
+// AddCheckConstraint 添加CHECK约束
+func (p *TenantSchemaProvider) AddCheckConstraint(ctx context.Context, baseID, tableName, constraintName, checkExpression string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.AddCheckConstraint(ctx, schema, tableName, constraintName, checkExpression)
+}
+
+// ==================== 工具方法 ====================
+
+// CreateIndex 创建索引
+func (p *TenantSchemaProvider) CreateIndex(ctx context.Context, baseID, tableName, indexName, definition string) error {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return p.inner.CreateIndex(ctx, schema, tableName, indexName, definition)
+}
+
+// ResolveTableName 解析完整表名：租户Schema.tableID（无法确定所属空间时返回错误）
+func (p *TenantSchemaProvider) ResolveTableName(ctx context.Context, baseID, tableID string) (string, error) {
+	schema, err := p.ResolveSchema(ctx, baseID)
+	if err != nil {
+		return "", err
+	}
+	return p.inner.GenerateTableName(schema, tableID), nil
+}
+
+// GenerateTableName 生成完整表名：租户Schema.tableID
+// 接口不携带上下文：优先使用已缓存的空间，未命中时以有限超时查询；
+// 解析失败时返回不存在的Schema下的表名，使语句失败而不是落到其他Schema，调用方应尽量使用 ResolveTableName
+func (p *TenantSchemaProvider) GenerateTableName(baseID, tableID string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), tenantResolveTimeout)
+	defer cancel()
+	name, err := p.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		logger.Error("租户Schema解析失败",
+			logger.String("base_id", baseID),
+			logger.ErrorField(err))
+		return p.inner.GenerateTableName(unresolvedTenantSchema, tableID)
+	}
+	return name
+}
+
+// MapFieldTypeToDBType 字段类型映射
+func (p *TenantSchemaProvider) MapFieldTypeToDBType(fieldType string) string {
+	return p.inner.MapFieldTypeToDBType(fieldType)
+}
+
+// DriverName 返回驱动名称
+func (p *TenantSchemaProvider) DriverName() string {
+	return p.inner.DriverName()
+}
+
+// SupportsSchema 是否支持Schema
+func (p *TenantSchemaProvider) SupportsSchema() bool {
+	return p.inner.SupportsSchema()
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// recordingProvider 记录收到的 Schema 名称
+type recordingProvider struct {
+	DBProvider
+	schemas []string
+}
+
+func (p *recordingProvider) AddColumn(_ context.Context, schemaName, _ string, _ ColumnDefinition) error {
+	p.schemas = append(p.schemas, schemaName)
+	return nil
+}
+
+func (p *recordingProvider) GenerateTableName(schemaName, tableID string) string {
+	return schemaName + "." + tableID
+}
+
+func newTenantProviderFixture(t *testing.T) (*TenantSchemaProvider, *recordingProvider) {
+	t.Helper()
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	// DryRun 下查询不返回任何行，未登记的 Base 均无法解析
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=luckdb"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("创建 GORM 实例失败: %v", err)
+	}
+	inner := &recordingProvider{}
+	return NewTenantSchemaProvider(inner, db, ""), inner
+}
+
+func TestTenantProviderResolvesWorkspaceSchema(t *testing.T) {
+	provider, inner := newTenantProviderFixture(t)
+	ctx := context.Background()
+
+	provider.RegisterBase("bse1", "SPC1")
+	if name, err := provider.ResolveTableName(ctx, "bse1", "tbl1"); err != nil || name != "ws_spc1.tbl1" {
+		t.Fatalf("应解析到工作空间Schema，得到 %q, %v", name, err)
+	}
+	if name, err := provider.ResolveTableName(WithTenant(ctx, "spc2"), "bse9", "tbl1"); err != nil || name != "ws_spc2.tbl1" {
+		t.Fatalf("应优先使用上下文中的租户，得到 %q, %v", name, err)
+	}
+	if err := provider.AddColumn(ctx, "bse1", "tbl1", ColumnDefinition{Name: "a", Type: "TEXT"}); err != nil {
+		t.Fatalf("添加列失败: %v", err)
+	}
+	if len(inner.schemas) != 1 || inner.schemas[0] != "ws_spc1" {
+		t.Fatalf("DDL 应在工作空间Schema执行，得到 %v", inner.schemas)
+	}
+}
+
+func TestTenantProviderRefusesUnresolvedBase(t *testing.T) {
+	provider, inner := newTenantProviderFixture(t)
+	ctx := context.Background()
+
+	if _, err := provider.ResolveTableName(ctx, "bseMissing", "tbl1"); err == nil {
+		t.Fatal("无法确定所属空间时应返回错误")
+	}
+	if err := provider.AddColumn(ctx, "bseMissing", "tbl1", ColumnDefinition{Name: "a", Type: "TEXT"}); err == nil {
+		t.Fatal("无法确定所属空间时 DDL 应失败")
+	}
+	if len(inner.schemas) != 0 {
+		t.Fatalf("不应退回 Base Schema 执行 DDL，得到 %v", inner.schemas)
+	}
+	if name := provider.GenerateTableName("bseMissing", "tbl1"); name != unresolvedTenantSchema+".tbl1" {
+		t.Fatalf("无法解析时不应生成 Base Schema 下的表名，得到 %q", name)
+	}
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+// maxTracedStatementLength 写入跨度的 SQL 最大长度（避免超大批量语句撑爆导出）
+const maxTracedStatementLength = 2048
+
+const tracingSpanKey = "luckdb:tracing_span"
+
+// TracingPlugin GORM 链路追踪插件 ✨
+// 为每条 SQL 创建客户端跨度（父跨度取自 db.WithContext(ctx)），记录语句、表名、影响行数与错误
+type TracingPlugin struct {
+	dbSystem string
+}
+
+// NewTracingPlugin 创建 GORM 追踪插件
+func NewTracingPlugin(dbSystem string) *TracingPlugin {
+	return &TracingPlugin{dbSystem: dbSystem}
+}
+
+// Name 插件名称
+func (p *TracingPlugin) Name() string {
+	return "luckdb:tracing"
+}
+
+// Initialize 注册 GORM 回调
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	if err := cb.Create().Before("gorm:create").Register("tracing:before_create", p.before("INSERT")); err != nil {
+		return err
+	}
+	if err := cb.Create().After("gorm:create").Register("tracing:after_create", p.after); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tracing:before_query", p.before("SELECT")); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("tracing:after_query", p.after); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tracing:before_update", p.before("UPDATE")); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("tracing:after_update", p.after); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tracing:before_delete", p.before("DELETE")); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("tracing:after_delete", p.after); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tracing:before_row", p.before("")); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("tracing:after_row", p.after); err != nil {
+		return err
+	}
+	if err := cb.Raw().Before("gorm:raw").Register("tracing:before_raw", p.before("")); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("tracing:after_raw", p.after)
+}
+
+// before 开始跨度
+func (p *TracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !tracing.Enabled() || db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		// 仅在已有请求/任务链路时记录，避免启动迁移等产生大量孤立跨度
+		if !tracing.SpanContextFromContext(db.Statement.Context).IsValid() {
+			return
+		}
+
+		name := "db." + strings.ToLower(operation)
+		if operation == "" {
+			name = "db.exec"
+		}
+		ctx, span := tracing.Start(db.Statement.Context, name,
+			tracing.WithSpanKind(tracing.SpanKindClient),
+			tracing.WithAttributes(tracing.String("db.system", p.dbSystem)))
+		if span == nil {
+			return
+		}
+		if operation != "" {
+			span.SetAttributes(tracing.String("db.operation.name", operation))
+		}
+		db.Statement.Context = ctx
+		db.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+// after 结束跨度
+func (p *TracingPlugin) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(*tracing.Span)
+	if !ok || span == nil {
+		return
+	}
+	defer span.End()
+
+	statement := db.Statement.SQL.String()
+	if len(statement) > maxTracedStatementLength {
+		statement = statement[:maxTracedStatementLength] + "..."
+	}
+	span.SetAttributes(
+		tracing.String("db.query.text", statement),
+		tracing.Int64("db.response.rows_affected", db.Statement.RowsAffected),
+	)
+	if table := db.Statement.Table; table != "" {
+		span.SetAttributes(tracing.String("db.collection.name", table))
+	}
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+	}
+}
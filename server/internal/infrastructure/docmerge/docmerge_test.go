@@ -0,0 +1,143 @@
+package docmerge
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+const testDocument = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+	// 合并域被拆成多个文本块
+	`<w:p><w:r><w:t xml:space="preserve">Dear {{Cus</w:t></w:r><w:proofErr w:type="spellStart"/><w:r><w:rPr><w:b/></w:rPr><w:t>tomer}}</w:t></w:r><w:r><w:t>, hi</w:t></w:r></w:p>` +
+	// 简单 MERGEFIELD 域
+	`<w:p><w:fldSimple w:instr=" MERGEFIELD  Amount \* MERGEFORMAT "><w:r><w:rPr><w:i/></w:rPr><w:t>«Amount»</w:t></w:r></w:fldSimple></w:p>` +
+	// 复杂 MERGEFIELD 域（名称带引号）
+	`<w:p><w:r><w:fldChar w:fldCharType="begin"/></w:r><w:r><w:instrText xml:space="preserve"> MERGEFIELD "Due </w:instrText></w:r>` +
+	`<w:r><w:instrText>Date"</w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+	`<w:r><w:rPr><w:u/></w:rPr><w:t>«Due Date»</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r><w:r><w:t> {{Unknown}}</w:t></w:r></w:p>` +
+	`</w:body></w:document>`
+
+const testHeader = `<w:hdr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:p><w:r><w:t>{{ Customer }}</w:t></w:r></w:p></w:hdr>`
+
+func testDOCX(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"[Content_Types].xml": `<Types/>`,
+		"word/document.xml":   testDocument,
+		"word/header1.xml":    testHeader,
+		"word/styles.xml":     `<w:styles>{{Customer}}</w:styles>`,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func readDOCXPart(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if f.Name == name {
+			content, err := readPart(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return content
+		}
+	}
+	t.Fatalf("缺少 %s", name)
+	return ""
+}
+
+func testValues(values map[string]string) Resolver {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestFieldsDOCX(t *testing.T) {
+	names, err := Fields(FormatDOCX, testDOCX(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 去重，样式等其他部件不解析
+	if got := strings.Join(names, "|"); got != "Customer|Unknown|Due Date|Amount" {
+		t.Errorf("合并域错误: %s", got)
+	}
+
+	if _, err := Fields(FormatDOCX, []byte("not a zip")); err == nil {
+		t.Error("无效文件应报错")
+	}
+}
+
+func TestMergeDOCX(t *testing.T) {
+	data, err := Merge(FormatDOCX, testDOCX(t), testValues(map[string]string{
+		"Customer": "A & B\n<Ltd>",
+		"Amount":   "1,200.00",
+		"Due Date": "2026-11-01",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := readDOCXPart(t, data, "word/document.xml")
+
+	for _, want := range []string{
+		// 值写入合并域起始的文本块，换行转为 <w:br/>，跨越的文本块中合并域的剩余部分被移除
+		`<w:t xml:space="preserve">Dear A &amp; B</w:t><w:br/><w:t xml:space="preserve">&lt;Ltd&gt;</w:t>`,
+		`<w:rPr><w:b/></w:rPr><w:t xml:space="preserve"></w:t>`,
+		`<w:t xml:space="preserve">, hi</w:t>`,
+		// 域替换为沿用结果格式的文本
+		`<w:r><w:rPr><w:i/></w:rPr><w:t xml:space="preserve">1,200.00</w:t></w:r>`,
+		`<w:p><w:r><w:rPr><w:u/></w:rPr><w:t xml:space="preserve">2026-11-01</w:t></w:r><w:r>`,
+		// 没有值的合并域保留原文
+		`{{Unknown}}`,
+	} {
+		if !strings.Contains(document, want) {
+			t.Errorf("正文缺少 %s:\n%s", want, document)
+		}
+	}
+	if strings.Contains(document, "fldChar") || strings.Contains(document, "fldSimple") {
+		t.Errorf("合并后的域应被移除:\n%s", document)
+	}
+	if header := readDOCXPart(t, data, "word/header1.xml"); !strings.Contains(header, "A &amp; B") {
+		t.Errorf("页眉未替换:\n%s", header)
+	}
+	if styles := readDOCXPart(t, data, "word/styles.xml"); styles != `<w:styles>{{Customer}}</w:styles>` {
+		t.Errorf("其他部件应原样复制: %s", styles)
+	}
+}
+
+func TestMergeHTML(t *testing.T) {
+	template := `<h1 title="{{Name}}">Hello {{ Name }}</h1><p>{{Notes}} {{Missing}}</p>`
+	data, err := Merge(FormatHTML, []byte(template), testValues(map[string]string{
+		"Name":  `<b>"A"</b>`,
+		"Notes": "line1\nline2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<h1 title="&lt;b&gt;&#34;A&#34;&lt;/b&gt;">Hello &lt;b&gt;&#34;A&#34;&lt;/b&gt;</h1><p>line1<br>line2 {{Missing}}</p>`
+	if string(data) != want {
+		t.Errorf("期望 %s\n得到 %s", want, data)
+	}
+
+	names, _ := Fields(FormatHTML, []byte(template))
+	if strings.Join(names, "|") != "Name|Notes|Missing" {
+		t.Errorf("合并域错误: %v", names)
+	}
+}
@@ -0,0 +1,261 @@
+package docmerge
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxPartSize 单个 DOCX 部件解压后的大小上限，防止压缩炸弹
+const maxPartSize = 32 << 20
+
+var (
+	// contentPartPattern 含正文文本的部件：正文、页眉、页脚、脚注与尾注
+	contentPartPattern = regexp.MustCompile(`^word/(document|header\d*|footer\d*|footnotes|endnotes)\.xml$`)
+
+	paragraphPattern   = regexp.MustCompile(`(?s)<w:p(?:\s[^>]*)?>.*?</w:p>`)
+	textPattern        = regexp.MustCompile(`(?s)(<w:t(?:\s[^>]*)?>)(.*?)</w:t>`)
+	runPattern         = regexp.MustCompile(`(?s)<w:r(?:\s[^>]*)?>.*?</w:r>`)
+	runPropsPattern    = regexp.MustCompile(`(?s)<w:rPr>.*?</w:rPr>`)
+	instrTextPattern   = regexp.MustCompile(`(?s)<w:instrText(?:\s[^>]*)?>(.*?)</w:instrText>`)
+	fieldCharPattern   = regexp.MustCompile(`<w:fldChar\s[^>]*w:fldCharType="(begin|separate|end)"`)
+	simpleFieldPattern = regexp.MustCompile(`(?s)<w:fldSimple\s[^>]*?w:instr="([^"]*)"[^>]*?(?:/>|>(.*?)</w:fldSimple>)`)
+	mergeFieldPattern  = regexp.MustCompile(`^\s*MERGEFIELD\s+(?:"([^"]+)"|(\S+))`)
+)
+
+// mergeDOCX 替换 DOCX 正文、页眉页脚与脚注中的合并域，其余部件原样复制
+func mergeDOCX(data []byte, resolve Resolver) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("无效的 DOCX 文件: %w", err)
+	}
+	hasDocument := false
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			hasDocument = true
+		}
+	}
+	if !hasDocument {
+		return nil, fmt.Errorf("无效的 DOCX 文件: 缺少 word/document.xml")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if !contentPartPattern.MatchString(f.Name) {
+			if err := zw.Copy(f); err != nil {
+				return nil, fmt.Errorf("复制 %s 失败: %w", f.Name, err)
+			}
+			continue
+		}
+		content, err := readPart(f)
+		if err != nil {
+			return nil, err
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: zip.Deflate, Modified: f.Modified})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(w, mergePart(content, resolve)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func readPart(f *zip.File) (string, error) {
+	if f.UncompressedSize64 > maxPartSize {
+		return "", fmt.Errorf("%s 过大", f.Name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(io.LimitReader(r, maxPartSize+1))
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+	}
+	if len(content) > maxPartSize {
+		return "", fmt.Errorf("%s 过大", f.Name)
+	}
+	return string(content), nil
+}
+
+// mergePart 逐段落替换 {{字段名}} 与 MERGEFIELD 域（替换后的值不会再被当作合并域）
+func mergePart(content string, resolve Resolver) string {
+	content = paragraphPattern.ReplaceAllStringFunc(content, func(paragraph string) string {
+		return mergeComplexFields(mergePlaceholders(paragraph, resolve), resolve)
+	})
+	return simpleFieldPattern.ReplaceAllStringFunc(content, func(field string) string {
+		m := simpleFieldPattern.FindStringSubmatch(field)
+		value, ok := mergeFieldValue(m[1], resolve)
+		if !ok {
+			return field
+		}
+		return textRun(runPropsPattern.FindString(m[2]), value)
+	})
+}
+
+// mergePlaceholders 替换段落中的 {{字段名}}。Word 常把一段文字拆成多个文本块（如拼写检查、格式变化），
+// 合并域可能跨越多个 <w:t>：先拼接段落的全部文本定位合并域，再把替换后的文本写回各文本块，
+// 值写入合并域起始处所在的文本块，沿用其格式
+func mergePlaceholders(paragraph string, resolve Resolver) string {
+	matches := textPattern.FindAllStringSubmatchIndex(paragraph, -1)
+	if len(matches) == 0 {
+		return paragraph
+	}
+	starts := make([]int, len(matches))
+	var joined strings.Builder
+	for i, m := range matches {
+		starts[i] = joined.Len()
+		joined.WriteString(html.UnescapeString(paragraph[m[4]:m[5]]))
+	}
+	text := joined.String()
+	locs := placeholderPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		return paragraph
+	}
+
+	segments := make([]strings.Builder, len(matches))
+	// segment 偏移量所在的文本块（空文本块跳过）
+	segment := func(offset int) int {
+		return sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+	}
+	copyText := func(from, to int) {
+		for i := range starts {
+			end := len(text)
+			if i+1 < len(starts) {
+				end = starts[i+1]
+			}
+			if a, b := max(from, starts[i]), min(to, end); a < b {
+				segments[i].WriteString(text[a:b])
+			}
+		}
+	}
+	last, replaced := 0, false
+	for _, loc := range locs {
+		value, ok := resolve(text[loc[2]:loc[3]])
+		if !ok {
+			continue
+		}
+		copyText(last, loc[0])
+		segments[segment(loc[0])].WriteString(value)
+		last, replaced = loc[1], true
+	}
+	if !replaced {
+		return paragraph
+	}
+	copyText(last, len(text))
+
+	var b strings.Builder
+	prev := 0
+	for i, m := range matches {
+		b.WriteString(paragraph[prev:m[0]])
+		b.WriteString(textElement(paragraph[m[2]:m[3]], segments[i].String()))
+		prev = m[1]
+	}
+	b.WriteString(paragraph[prev:])
+	return b.String()
+}
+
+// mergeComplexFields 替换段落中由 fldChar 组成的 MERGEFIELD 域：begin 到 end 之间的文本块
+// 替换为一个写有值的文本块，沿用域结果的格式；嵌套的域不处理
+func mergeComplexFields(paragraph string, resolve Resolver) string {
+	runs := runPattern.FindAllStringIndex(paragraph, -1)
+	type field struct {
+		start  int
+		instr  strings.Builder
+		props  string
+		result bool
+		nested int
+	}
+	var b strings.Builder
+	var current *field
+	last := 0
+	for _, r := range runs {
+		run := paragraph[r[0]:r[1]]
+		kind := ""
+		if m := fieldCharPattern.FindStringSubmatch(run); m != nil {
+			kind = m[1]
+		}
+		switch {
+		case kind == "begin":
+			if current != nil {
+				current.nested++
+				continue
+			}
+			current = &field{start: r[0], props: runPropsPattern.FindString(run)}
+		case current == nil:
+		case current.nested > 0:
+			if kind == "end" {
+				current.nested--
+			}
+		case kind == "separate":
+			current.result = true
+		case kind == "end":
+			if value, ok := mergeFieldValue(current.instr.String(), resolve); ok {
+				b.WriteString(paragraph[last:current.start])
+				b.WriteString(textRun(current.props, value))
+				last = r[1]
+			}
+			current = nil
+		case current.result:
+			if props := runPropsPattern.FindString(run); props != "" && textPattern.MatchString(run) {
+				current.props = props
+			}
+		default:
+			for _, m := range instrTextPattern.FindAllStringSubmatch(run, -1) {
+				current.instr.WriteString(html.UnescapeString(m[1]))
+			}
+		}
+	}
+	if last == 0 {
+		return paragraph
+	}
+	b.WriteString(paragraph[last:])
+	return b.String()
+}
+
+// mergeFieldValue 解析 MERGEFIELD 域代码中的名称并取值（非 MERGEFIELD 域返回 false）
+func mergeFieldValue(instr string, resolve Resolver) (string, bool) {
+	m := mergeFieldPattern.FindStringSubmatch(html.UnescapeString(instr))
+	if m == nil {
+		return "", false
+	}
+	name := m[1]
+	if name == "" {
+		name = m[2]
+	}
+	return resolve(strings.TrimSpace(name))
+}
+
+// textRun 写有文本的 <w:r>，props 为沿用的 <w:rPr>
+func textRun(props, value string) string {
+	return "<w:r>" + props + textElement("<w:t>", value) + "</w:r>"
+}
+
+// textElement 写有文本的 <w:t>（保留首尾空格），换行写为 <w:br/>
+func textElement(open, value string) string {
+	if !strings.Contains(open, "xml:space") {
+		open = `<w:t xml:space="preserve">`
+	}
+	lines := strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString(`</w:t><w:br/><w:t xml:space="preserve">`)
+		}
+		xml.EscapeText(&b, []byte(line))
+	}
+	return open + b.String() + "</w:t>"
+}
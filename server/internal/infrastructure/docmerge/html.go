@@ -0,0 +1,34 @@
+package docmerge
+
+import (
+	"html"
+	"strings"
+)
+
+// mergeHTML 替换 HTML 模板中的合并域：值经过 HTML 转义，正文中的换行写为 <br>，
+// 标签属性中的换行写为空格
+func mergeHTML(text string, resolve Resolver) string {
+	locs := placeholderPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range locs {
+		value, ok := resolve(text[loc[2]:loc[3]])
+		if !ok {
+			continue
+		}
+		value = html.EscapeString(strings.ReplaceAll(value, "\r\n", "\n"))
+		if inTag := strings.LastIndexByte(text[:loc[0]], '<') > strings.LastIndexByte(text[:loc[0]], '>'); inTag {
+			value = strings.ReplaceAll(value, "\n", " ")
+		} else {
+			value = strings.ReplaceAll(value, "\n", "<br>")
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(value)
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
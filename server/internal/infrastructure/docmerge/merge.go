@@ -0,0 +1,51 @@
+// Package docmerge 文档模板合并 ✨
+// 将 DOCX 或 HTML 模板中的合并域替换为记录的值。合并域写作 {{字段名}}；DOCX 模板还支持 Word 的
+// MERGEFIELD 域（邮件合并中插入的域）。只替换文本，模板的排版、样式与图片保持不变。
+package docmerge
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Format 模板格式
+type Format string
+
+const (
+	FormatDOCX Format = "docx"
+	FormatHTML Format = "html"
+)
+
+// Resolver 返回合并域的值；返回 false 时保留合并域原文
+type Resolver func(name string) (string, bool)
+
+// placeholderPattern {{字段名}} 合并域，名称两侧的空白忽略
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// Fields 列出模板中的合并域（去重，按段落顺序）
+func Fields(format Format, data []byte) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	collect := func(name string) (string, bool) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		return "", false
+	}
+	if _, err := Merge(format, data, collect); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Merge 替换模板中的合并域，返回生成的文件内容
+func Merge(format Format, data []byte, resolve Resolver) ([]byte, error) {
+	switch format {
+	case FormatDOCX:
+		return mergeDOCX(data, resolve)
+	case FormatHTML:
+		return []byte(mergeHTML(string(data), resolve)), nil
+	}
+	return nil, fmt.Errorf("不支持的模板格式 %q", format)
+}
@@ -0,0 +1,188 @@
+package externalsource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+// maxRESTResponseBytes 单页响应的最大字节数
+const maxRESTResponseBytes = 32 << 20
+
+// restReader 从返回 JSON 的 REST 接口读取数据
+// 续读标记为下一页地址，只允许与配置地址同源（避免把 Authorization 头发往其他主机）
+type restReader struct {
+	source        externalsync.Source
+	base          *url.URL
+	authorization string
+	client        *http.Client
+}
+
+func newRESTReader(source externalsync.Source, authorization string, client *http.Client, allowPrivate bool) (*restReader, error) {
+	base, err := url.Parse(source.URL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("invalid url: %q", source.URL)
+	}
+	if !allowPrivate {
+		if err := netguard.CheckURL(base); err != nil {
+			return nil, err
+		}
+	}
+	if source.CursorColumn != "" && source.CursorParam == "" {
+		return nil, fmt.Errorf("cursor param is required when cursor column is set")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &restReader{source: source, base: base, authorization: authorization, client: client}, nil
+}
+
+// Head REST 接口不支持读取游标最大值（全量同步改用读取到的最大值）
+func (r *restReader) Head(ctx context.Context) (string, error) {
+	return "", nil
+}
+
+// Read 读取一页数据（增量读取时携带游标值，接口应返回更新时间不早于该值的行）
+func (r *restReader) Read(ctx context.Context, q Query) (*Page, error) {
+	target, err := r.pageURL(q)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if r.authorization != "" {
+		req.Header.Set("Authorization", r.authorization)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request source: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRESTResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("source responded %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	if len(body) > maxRESTResponseBytes {
+		return nil, fmt.Errorf("source response exceeds %d bytes", maxRESTResponseBytes)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid source response: %w", err)
+	}
+	items, ok := lookupPath(document, r.source.ItemsPath).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("source response has no array at %q", r.source.ItemsPath)
+	}
+
+	// 增量读取时游标只前进：本页最大更新时间早于请求游标时保持原游标
+	page := &Page{Records: make([]Record, 0, len(items))}
+	if q.Since != nil && r.source.CursorColumn != "" {
+		page.Last = &Cursor{Value: q.Since.Value}
+	}
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		record := make(Record, len(object))
+		for key, value := range object {
+			record[key] = jsonValue(value)
+		}
+		page.Records = append(page.Records, record)
+
+		if r.source.CursorColumn == "" {
+			continue
+		}
+		value := KeyString(record[r.source.CursorColumn])
+		if value != "" && (page.Last == nil || (Cursor{Value: value}).After(*page.Last)) {
+			page.Last = &Cursor{Value: value}
+		}
+	}
+	if r.source.NextPath != "" && len(page.Records) > 0 {
+		if next, ok := lookupPath(document, r.source.NextPath).(string); ok && next != "" {
+			page.NextToken = next
+		}
+	}
+	return page, nil
+}
+
+// pageURL 第一页为配置地址（增量时追加游标参数），之后为上一页返回的同源地址
+func (r *restReader) pageURL(q Query) (*url.URL, error) {
+	if q.PageToken != "" {
+		next, err := r.base.Parse(q.PageToken)
+		if err != nil {
+			return nil, fmt.Errorf("invalid next page url: %w", err)
+		}
+		if next.Scheme != r.base.Scheme || next.Host != r.base.Host {
+			return nil, fmt.Errorf("next page url must be on %s", r.base.Host)
+		}
+		return next, nil
+	}
+	target := *r.base
+	if q.Since != nil && r.source.CursorParam != "" {
+		values := target.Query()
+		values.Set(r.source.CursorParam, q.Since.Value)
+		target.RawQuery = values.Encode()
+	}
+	return &target, nil
+}
+
+// Close REST 读取器无需释放资源
+func (r *restReader) Close() error {
+	return nil
+}
+
+// lookupPath 按以点分隔的路径读取 JSON 值（路径为空时返回整个文档）
+func lookupPath(document interface{}, path string) interface{} {
+	if path == "" {
+		return document
+	}
+	current := document
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = object[part]
+	}
+	return current
+}
+
+// jsonValue 将 json.Number 转换为 int64 或 float64
+func jsonValue(value interface{}) interface{} {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if i, err := number.Int64(); err == nil {
+		return i
+	}
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+	return number.String()
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,131 @@
+package externalsource
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+)
+
+// identifierPattern 表名与列名只允许字母、数字与下划线（表名可带一个 schema 前缀）
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Record 一行外部数据（列名 -> 值），时间值转换为 RFC3339 字符串，[]byte 转换为字符串
+type Record map[string]interface{}
+
+// Cursor 增量读取位置：更新时间列的值与该值下最后一行的键（Key 为空时包含等于 Value 的行）
+type Cursor struct {
+	Value string `json:"v"`
+	Key   string `json:"k,omitempty"`
+}
+
+// After 是否位于 other 之后：值均为数字时按数值比较，否则按字符串比较（值相同时比较键）
+// 用于不支持 Head 的数据源（REST）在多页之间取最大更新时间
+func (c Cursor) After(other Cursor) bool {
+	if c.Value != other.Value {
+		a, errA := strconv.ParseFloat(c.Value, 64)
+		b, errB := strconv.ParseFloat(other.Value, 64)
+		if errA == nil && errB == nil {
+			return a > b
+		}
+		return c.Value > other.Value
+	}
+	return c.Key > other.Key
+}
+
+// Query 一次读取请求
+type Query struct {
+	Since     *Cursor // 非空时只读取游标之后更新的行（按更新时间、键升序），否则按键升序读取全部行
+	PageToken string  // 上一页返回的续读标记
+	Limit     int
+}
+
+// Page 一页读取结果
+type Page struct {
+	Records   []Record
+	Last      *Cursor // 本页更新时间最大的行的位置（增量读取或数据源不支持 Head 时提供）
+	NextToken string  // 为空表示已读完
+}
+
+// Reader 外部数据源读取器
+type Reader interface {
+	// Head 更新时间列当前的最大值，全量同步前读取，之后的增量从该值继续（不支持时返回空字符串）
+	Head(ctx context.Context) (string, error)
+	// Read 读取一页数据
+	Read(ctx context.Context, q Query) (*Page, error)
+	// Close 释放连接
+	Close() error
+}
+
+// New 根据数据源类型创建读取器（不发起连接，只校验配置）
+// columns 为需要读取的列（键列与更新时间列自动加入）；
+// credentials：数据库为连接串，REST 为 Authorization 请求头的值（可为空）；
+// allowPrivate 为 false 时拒绝连接回环、内网等地址（REST 请求使用的 httpClient 需自行做连接时检查）
+func New(source externalsync.Source, credentials string, columns []string, httpClient *http.Client, allowPrivate bool) (Reader, error) {
+	if !identifierPattern.MatchString(source.KeyColumn) {
+		return nil, fmt.Errorf("invalid key column: %q", source.KeyColumn)
+	}
+	if source.CursorColumn != "" && !identifierPattern.MatchString(source.CursorColumn) {
+		return nil, fmt.Errorf("invalid cursor column: %q", source.CursorColumn)
+	}
+	columns = withSourceColumns(source, columns)
+
+	switch source.Type {
+	case externalsync.SourcePostgres, externalsync.SourceMySQL:
+		for _, col := range columns {
+			if !identifierPattern.MatchString(col) {
+				return nil, fmt.Errorf("invalid column: %q", col)
+			}
+		}
+		return newSQLReader(source, credentials, columns, allowPrivate)
+	case externalsync.SourceREST:
+		return newRESTReader(source, credentials, httpClient, allowPrivate)
+	default:
+		return nil, fmt.Errorf("unsupported source type: %s", source.Type)
+	}
+}
+
+// withSourceColumns 追加键列与更新时间列（去重，保持顺序）
+func withSourceColumns(source externalsync.Source, columns []string) []string {
+	seen := make(map[string]bool, len(columns)+2)
+	result := make([]string, 0, len(columns)+2)
+	for _, col := range append([]string{source.KeyColumn, source.CursorColumn}, columns...) {
+		if col == "" || seen[col] {
+			continue
+		}
+		seen[col] = true
+		result = append(result, col)
+	}
+	return result
+}
+
+// normalizeValue 统一外部值的表示：时间转为 RFC3339 字符串，[]byte 转为字符串
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// KeyString 行键的字符串形式（为空表示该行没有键，不能同步）
+func KeyString(value interface{}) string {
+	switch v := normalizeValue(value).(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,146 @@
+package externalsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+func TestSQLQueryIncrementalContinuesAfterKey(t *testing.T) {
+	reader := &sqlReader{
+		dialect: postgresDialect{},
+		source:  externalsync.Source{Type: externalsync.SourcePostgres, KeyColumn: "id", CursorColumn: "updated_at"},
+		table:   `"public"."customers"`,
+		columns: []string{"id", "updated_at", "name"},
+	}
+
+	query, args, err := reader.buildQuery(Query{Since: &Cursor{Value: "2024-01-01T00:00:00Z", Key: "7"}, Limit: 100})
+	if err != nil {
+		t.Fatalf("构造查询失败: %v", err)
+	}
+	want := `SELECT "id", "updated_at", "name" FROM "public"."customers" WHERE ("updated_at" > $1 OR ("updated_at" = $2 AND "id" > $3)) ORDER BY "updated_at", "id" LIMIT 100`
+	if query != want {
+		t.Errorf("查询不正确:\n%s\n期望:\n%s", query, want)
+	}
+	if len(args) != 3 || args[2] != "7" {
+		t.Errorf("参数不正确: %v", args)
+	}
+
+	// 全量同步后的游标没有键，包含等于该值的行
+	query, _, _ = reader.buildQuery(Query{Since: &Cursor{Value: "2024-01-01T00:00:00Z"}, Limit: 100})
+	if !strings.Contains(query, `WHERE "updated_at" >= $1`) {
+		t.Errorf("没有键的游标应包含等于该值的行: %s", query)
+	}
+}
+
+func TestSQLQueryFullPagesByKey(t *testing.T) {
+	reader := &sqlReader{
+		dialect: mysqlDialect{},
+		source:  externalsync.Source{Type: externalsync.SourceMySQL, KeyColumn: "id"},
+		table:   "`customers`",
+		columns: []string{"id", "name"},
+	}
+	query, args, err := reader.buildQuery(Query{PageToken: `{"v":"","k":"42"}`, Limit: 50})
+	if err != nil {
+		t.Fatalf("构造查询失败: %v", err)
+	}
+	want := "SELECT `id`, `name` FROM `customers` WHERE `id` > ? ORDER BY `id` LIMIT 50"
+	if query != want || len(args) != 1 || args[0] != "42" {
+		t.Errorf("查询不正确: %s %v", query, args)
+	}
+	if _, _, err := reader.buildQuery(Query{Since: &Cursor{Value: "1"}}); err == nil {
+		t.Error("未配置更新时间列时增量读取应失败")
+	}
+}
+
+func TestNewRejectsUnsafeIdentifiers(t *testing.T) {
+	source := externalsync.Source{Type: externalsync.SourcePostgres, Table: "customers", KeyColumn: "id"}
+	if _, err := New(source, "postgres://localhost/db", []string{"name; DROP TABLE x"}, nil, true); err == nil {
+		t.Error("非法列名应被拒绝")
+	}
+	source.Table = `customers"--`
+	if _, err := New(source, "postgres://localhost/db", []string{"name"}, nil, true); err == nil {
+		t.Error("非法表名应被拒绝")
+	}
+}
+
+func TestNewRejectsPrivateAddresses(t *testing.T) {
+	mysqlSource := externalsync.Source{Type: externalsync.SourceMySQL, Table: "customers", KeyColumn: "id"}
+	for _, dsn := range []string{"user:pw@tcp(10.0.0.8:3306)/db", "user:pw@tcp(localhost:3306)/db", "user:pw@unix(/tmp/mysql.sock)/db"} {
+		if _, err := New(mysqlSource, dsn, nil, nil, false); !errors.Is(err, netguard.ErrPrivateAddress) {
+			t.Errorf("%s 应被拒绝，得到 %v", dsn, err)
+		}
+	}
+	restSource := externalsync.Source{Type: externalsync.SourceREST, URL: "http://169.254.169.254/latest", KeyColumn: "id"}
+	if _, err := New(restSource, "", nil, nil, false); !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Errorf("元数据地址应被拒绝，得到 %v", err)
+	}
+
+	// PostgreSQL 的主机在连接时检查（域名可能解析到内网地址）
+	pgSource := externalsync.Source{Type: externalsync.SourcePostgres, Table: "customers", KeyColumn: "id", CursorColumn: "updated_at"}
+	reader, err := New(pgSource, "postgres://user:pw@127.0.0.1:1/db?sslmode=disable&connect_timeout=1", nil, nil, false)
+	if err != nil {
+		t.Fatalf("创建读取器失败: %v", err)
+	}
+	defer reader.Close()
+	if _, err := reader.Head(context.Background()); !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Errorf("连接回环地址应被拒绝，得到 %v", err)
+	}
+}
+
+func TestRESTReaderFollowsSameOriginPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			if r.URL.Query().Get("since") != "2024-01-01" {
+				t.Errorf("增量读取应携带游标参数: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"data":{"items":[{"id":1,"updated":"2024-01-03","name":"a"},{"id":2,"updated":"2024-01-02","name":"b"}]},"next":"/items?page=2"}`)
+		case "2":
+			fmt.Fprint(w, `{"data":{"items":[{"id":3,"updated":"2024-01-02","name":"c"}]},"next":"http://evil.example/items?page=3"}`)
+		}
+	}))
+	defer server.Close()
+
+	reader, err := New(externalsync.Source{
+		Type: externalsync.SourceREST, URL: server.URL + "/items", ItemsPath: "data.items", NextPath: "next",
+		CursorParam: "since", KeyColumn: "id", CursorColumn: "updated",
+	}, "Bearer token", nil, server.Client(), true)
+	if err != nil {
+		t.Fatalf("创建读取器失败: %v", err)
+	}
+
+	since := &Cursor{Value: "2024-01-01"}
+	page, err := reader.Read(context.Background(), Query{Since: since})
+	if err != nil {
+		t.Fatalf("读取失败: %v", err)
+	}
+	if len(page.Records) != 2 || page.Records[0]["id"] != int64(1) {
+		t.Fatalf("记录不正确: %v", page.Records)
+	}
+	if page.Last == nil || page.Last.Value != "2024-01-03" {
+		t.Errorf("游标应为本页最大更新时间: %+v", page.Last)
+	}
+
+	page, err = reader.Read(context.Background(), Query{Since: page.Last, PageToken: page.NextToken})
+	if err != nil {
+		t.Fatalf("读取第二页失败: %v", err)
+	}
+	if page.Last == nil || page.Last.Value != "2024-01-03" {
+		t.Errorf("游标不应后退: %+v", page.Last)
+	}
+	if _, err := reader.Read(context.Background(), Query{PageToken: page.NextToken}); err == nil {
+		t.Error("其他主机的下一页地址应被拒绝")
+	}
+}
@@ -0,0 +1,282 @@
+package externalsource
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+	"github.com/easyspace-ai/luckdb/server/pkg/netguard"
+)
+
+// sqlDialect 各数据库的 SQL 方言差异
+type sqlDialect interface {
+	driver() string
+	quote(identifier string) string
+	placeholder(n int) string
+	// formatCursor 将时间格式化为可与更新时间列比较的字符串
+	formatCursor(t time.Time) string
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) driver() string                 { return "postgres" }
+func (postgresDialect) quote(identifier string) string { return `"` + identifier + `"` }
+func (postgresDialect) placeholder(n int) string       { return fmt.Sprintf("$%d", n) }
+func (postgresDialect) formatCursor(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) driver() string                 { return "mysql" }
+func (mysqlDialect) quote(identifier string) string { return "`" + identifier + "`" }
+func (mysqlDialect) placeholder(int) string         { return "?" }
+func (mysqlDialect) formatCursor(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999")
+}
+
+// sqlReader 通过 database/sql 读取外部数据库表
+type sqlReader struct {
+	dialect sqlDialect
+	source  externalsync.Source
+	table   string // 已转义的表名
+	columns []string
+	db      *sql.DB
+}
+
+func newSQLReader(source externalsync.Source, dsn string, columns []string, allowPrivate bool) (*sqlReader, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, fmt.Errorf("connection string is required")
+	}
+	var dialect sqlDialect = postgresDialect{}
+	if source.Type == externalsync.SourceMySQL {
+		dialect = mysqlDialect{}
+	}
+
+	parts := strings.Split(source.Table, ".")
+	if len(parts) > 2 {
+		return nil, fmt.Errorf("invalid table: %q", source.Table)
+	}
+	for i, part := range parts {
+		if !identifierPattern.MatchString(part) {
+			return nil, fmt.Errorf("invalid table: %q", source.Table)
+		}
+		parts[i] = dialect.quote(part)
+	}
+
+	var connector driver.Connector
+	if source.Type == externalsync.SourceMySQL {
+		// 时间列解析为 UTC 的 time.Time，游标按 UTC 比较
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mysql connection string: %w", err)
+		}
+		cfg.ParseTime = true
+		cfg.Loc = time.UTC
+		if !allowPrivate {
+			if cfg.Net != "tcp" {
+				return nil, fmt.Errorf("%w: network %s", netguard.ErrPrivateAddress, cfg.Net)
+			}
+			if err := netguard.CheckHost(cfg.Addr); err != nil {
+				return nil, err
+			}
+			cfg.Net = guardedMySQLNet
+		}
+		if connector, err = mysql.NewConnector(cfg); err != nil {
+			return nil, fmt.Errorf("invalid mysql connection string: %w", err)
+		}
+	} else {
+		pqConnector, err := pq.NewConnector(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid postgres connection string: %w", err)
+		}
+		if !allowPrivate {
+			pqConnector.Dialer(guardedPQDialer{dialer: netguard.NewDialer(0, false)})
+		}
+		connector = pqConnector
+	}
+
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(2)
+	db.SetConnMaxIdleTime(time.Minute)
+	return &sqlReader{
+		dialect: dialect,
+		source:  source,
+		table:   strings.Join(parts, "."),
+		columns: columns,
+		db:      db,
+	}, nil
+}
+
+// guardedMySQLNet 连接前检查解析后地址的 MySQL 网络名（在 init 中注册）
+// 域名在每次连接时解析，由 netguard.Control 拒绝内网地址，防止 DNS 重新绑定
+const guardedMySQLNet = "externalsource-guarded-tcp"
+
+func init() {
+	dialer := netguard.NewDialer(0, false)
+	mysql.RegisterDialContext(guardedMySQLNet, func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "tcp", addr)
+	})
+}
+
+// guardedPQDialer 连接前检查解析后地址的 PostgreSQL Dialer（Unix 套接字同样被拒绝）
+type guardedPQDialer struct {
+	dialer *net.Dialer
+}
+
+func (d guardedPQDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dialer.Dial(network, address)
+}
+
+func (d guardedPQDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	dialer := *d.dialer
+	dialer.Timeout = timeout
+	return dialer.Dial(network, address)
+}
+
+func (d guardedPQDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.dialer.DialContext(ctx, network, address)
+}
+
+// Head 更新时间列当前的最大值
+func (r *sqlReader) Head(ctx context.Context) (string, error) {
+	if r.source.CursorColumn == "" {
+		return "", nil
+	}
+	var value interface{}
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s", r.dialect.quote(r.source.CursorColumn), r.table)
+	if err := r.db.QueryRowContext(ctx, query).Scan(&value); err != nil {
+		return "", fmt.Errorf("failed to read cursor head: %w", err)
+	}
+	return r.cursorValue(value), nil
+}
+
+// Read 读取一页数据
+func (r *sqlReader) Read(ctx context.Context, q Query) (*Page, error) {
+	query, args, err := r.buildQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source table: %w", err)
+	}
+	defer rows.Close()
+
+	page := &Page{}
+	var lastKey, lastCursor interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(r.columns))
+		pointers := make([]interface{}, len(r.columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan source row: %w", err)
+		}
+		record := make(Record, len(r.columns))
+		for i, col := range r.columns {
+			record[col] = normalizeValue(values[i])
+			switch col {
+			case r.source.KeyColumn:
+				lastKey = values[i]
+			case r.source.CursorColumn:
+				lastCursor = values[i]
+			}
+		}
+		page.Records = append(page.Records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read source rows: %w", err)
+	}
+
+	if len(page.Records) == 0 {
+		return page, nil
+	}
+	last := &Cursor{Value: r.cursorValue(lastCursor), Key: KeyString(lastKey)}
+	if q.Since != nil {
+		page.Last = last
+	}
+	if q.Limit > 0 && len(page.Records) >= q.Limit {
+		token, err := json.Marshal(last)
+		if err != nil {
+			return nil, err
+		}
+		page.NextToken = string(token)
+	}
+	return page, nil
+}
+
+// buildQuery 构造分页查询
+// 增量读取按 (更新时间, 键) 升序，从游标（或续读标记）之后继续；全量读取按键升序
+func (r *sqlReader) buildQuery(q Query) (string, []interface{}, error) {
+	var after *Cursor
+	if q.PageToken != "" {
+		after = &Cursor{}
+		if err := json.Unmarshal([]byte(q.PageToken), after); err != nil {
+			return "", nil, fmt.Errorf("invalid page token: %w", err)
+		}
+	}
+
+	var args []interface{}
+	param := func(value interface{}) string {
+		args = append(args, value)
+		return r.dialect.placeholder(len(args))
+	}
+	key := r.dialect.quote(r.source.KeyColumn)
+
+	selected := make([]string, 0, len(r.columns))
+	for _, col := range r.columns {
+		selected = append(selected, r.dialect.quote(col))
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selected, ", "), r.table)
+
+	if q.Since != nil {
+		if r.source.CursorColumn == "" {
+			return "", nil, fmt.Errorf("cursor column is required for incremental reads")
+		}
+		cursor := r.dialect.quote(r.source.CursorColumn)
+		pos := q.Since
+		if after != nil {
+			pos = after
+		}
+		if pos.Key == "" {
+			query += fmt.Sprintf(" WHERE %s >= %s", cursor, param(pos.Value))
+		} else {
+			query += fmt.Sprintf(" WHERE (%s > %s OR (%s = %s AND %s > %s))",
+				cursor, param(pos.Value), cursor, param(pos.Value), key, param(pos.Key))
+		}
+		query += fmt.Sprintf(" ORDER BY %s, %s", cursor, key)
+	} else {
+		if after != nil {
+			query += fmt.Sprintf(" WHERE %s > %s", key, param(after.Key))
+		}
+		query += fmt.Sprintf(" ORDER BY %s", key)
+	}
+	if q.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", q.Limit)
+	}
+	return query, args, nil
+}
+
+// cursorValue 更新时间列值的字符串形式（时间按方言格式化）
+func (r *sqlReader) cursorValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return r.dialect.formatCursor(t)
+	}
+	return KeyString(value)
+}
+
+// Close 释放连接
+func (r *sqlReader) Close() error {
+	return r.db.Close()
+}
@@ -0,0 +1,125 @@
+// Package faultinject 缓存与数据库的故障注入 ✨
+// 按配置在操作前附加延迟、按概率返回错误，只用于测试与预发环境验证降级行为
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+)
+
+// ErrInjected 注入的故障，可用 errors.Is 判断
+var ErrInjected = errors.New("fault injected")
+
+// Stats 注入统计
+type Stats struct {
+	Calls   int64 `json:"calls"`   // 命中规则的操作次数
+	Delayed int64 `json:"delayed"` // 附加了延迟的次数
+	Failed  int64 `json:"failed"`  // 返回注入错误的次数
+}
+
+// Injector 故障注入器（并发安全）
+type Injector struct {
+	name string
+	cfg  config.FaultInjectionConfig
+
+	mu   sync.Mutex
+	rand *rand.Rand
+
+	calls   atomic.Int64
+	delayed atomic.Int64
+	failed  atomic.Int64
+}
+
+// New 创建故障注入器，name 用于错误信息（如 "cache"、"database"）
+func New(name string, cfg config.FaultInjectionConfig) *Injector {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{name: name, cfg: cfg, rand: rand.New(rand.NewSource(seed))}
+}
+
+// Enabled 是否启用
+func (i *Injector) Enabled() bool {
+	return i != nil && i.cfg.Enabled
+}
+
+// Applies 操作与目标是否命中注入规则
+func (i *Injector) Applies(operation, target string) bool {
+	if !i.Enabled() {
+		return false
+	}
+	if len(i.cfg.Operations) > 0 && !containsFold(i.cfg.Operations, operation) {
+		return false
+	}
+	if len(i.cfg.Targets) > 0 {
+		for _, pattern := range i.cfg.Targets {
+			if matched, _ := path.Match(pattern, target); matched {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Inject 对命中规则的操作附加延迟，并按概率返回包装了 ErrInjected 的错误
+// 延迟期间 ctx 取消时返回 ctx.Err()
+func (i *Injector) Inject(ctx context.Context, operation, target string) error {
+	if !i.Applies(operation, target) {
+		return nil
+	}
+	i.calls.Add(1)
+
+	delay, fail := i.roll()
+	if delay > 0 {
+		i.delayed.Add(1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if fail {
+		i.failed.Add(1)
+		return fmt.Errorf("%s %s %s: %w", i.name, operation, target, ErrInjected)
+	}
+	return nil
+}
+
+// Stats 注入统计
+func (i *Injector) Stats() Stats {
+	return Stats{Calls: i.calls.Load(), Delayed: i.delayed.Load(), Failed: i.failed.Load()}
+}
+
+// roll 抽取本次的延迟与是否失败
+func (i *Injector) roll() (time.Duration, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delay := i.cfg.Latency
+	if i.cfg.Jitter > 0 {
+		delay += time.Duration(i.rand.Int63n(int64(i.cfg.Jitter)))
+	}
+	fail := i.cfg.ErrorRate > 0 && i.rand.Float64() < i.cfg.ErrorRate
+	return delay, fail
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+)
+
+func TestInjectorMatchesOperationsAndTargets(t *testing.T) {
+	injector := New("cache", config.FaultInjectionConfig{
+		Enabled:    true,
+		ErrorRate:  1,
+		Operations: []string{"get"},
+		Targets:    []string{"field:*"},
+	})
+	ctx := context.Background()
+
+	if err := injector.Inject(ctx, "get", "field:id:fld1"); !errors.Is(err, ErrInjected) {
+		t.Errorf("命中规则时应返回注入错误，得到 %v", err)
+	}
+	if err := injector.Inject(ctx, "GET", "record:tbl1:rec1"); err != nil {
+		t.Errorf("目标不匹配时不应注入，得到 %v", err)
+	}
+	if err := injector.Inject(ctx, "set", "field:id:fld1"); err != nil {
+		t.Errorf("操作不匹配时不应注入，得到 %v", err)
+	}
+	if stats := injector.Stats(); stats.Calls != 1 || stats.Failed != 1 {
+		t.Errorf("统计应只计入命中的操作，得到 %+v", stats)
+	}
+
+	disabled := New("cache", config.FaultInjectionConfig{ErrorRate: 1})
+	if err := disabled.Inject(ctx, "get", "field:id:fld1"); err != nil {
+		t.Errorf("未启用时不应注入，得到 %v", err)
+	}
+}
+
+func TestInjectorErrorRateIsReproducibleWithSeed(t *testing.T) {
+	cfg := config.FaultInjectionConfig{Enabled: true, ErrorRate: 0.3, Seed: 42}
+	run := func() []bool {
+		injector := New("database", cfg)
+		results := make([]bool, 200)
+		for i := range results {
+			results[i] = injector.Inject(context.Background(), "query", "tbl") != nil
+		}
+		return results
+	}
+
+	first, second := run(), run()
+	failed := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("相同种子的注入序列应一致，第 %d 次不同", i)
+		}
+		if first[i] {
+			failed++
+		}
+	}
+	if failed < 30 || failed > 90 {
+		t.Errorf("失败次数应接近 30%%，得到 %d/200", failed)
+	}
+}
+
+func TestInjectorLatencyRespectsContext(t *testing.T) {
+	injector := New("database", config.FaultInjectionConfig{Enabled: true, Latency: 20 * time.Millisecond})
+	start := time.Now()
+	if err := injector.Inject(context.Background(), "query", "tbl"); err != nil {
+		t.Fatalf("只配置延迟时不应返回错误，得到 %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("应至少延迟 20ms，实际 %v", elapsed)
+	}
+
+	slow := New("database", config.FaultInjectionConfig{Enabled: true, Latency: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Inject(ctx, "query", "tbl"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("延迟期间 ctx 超时应返回超时错误，得到 %v", err)
+	}
+	if stats := slow.Stats(); stats.Delayed != 1 {
+		t.Errorf("统计应记录延迟次数，得到 %+v", stats)
+	}
+}
@@ -0,0 +1,221 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compactReader 测试用的 Thrift Compact 解码器，结构体解码为 字段ID → 值
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) interface{} {
+	switch typ {
+	case compactBoolTrue:
+		return true
+	case compactBoolFalse:
+		return false
+	case compactI32, compactI64:
+		return r.varint()
+	case compactBinary:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case compactList:
+		header := r.byte()
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(header & 0x0F)
+		}
+		return list
+	case compactStruct:
+		return r.structure()
+	}
+	panic("unexpected compact type")
+}
+
+func (r *compactReader) structure() map[int16]interface{} {
+	fields := map[int16]interface{}{}
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(header & 0x0F)
+		last = id
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "record_id", Type: TypeString, Required: true},
+		{Name: "amount", Type: TypeDouble},
+		{Name: "count", Type: TypeInt64},
+		{Name: "done", Type: TypeBoolean},
+		{Name: "due", Type: TypeTimestamp},
+		{Name: "tags", Type: TypeJSON},
+	}
+	due := time.Date(2026, 3, 1, 8, 30, 0, 123000, time.UTC)
+	rows := [][]interface{}{
+		{"rec1", 12.5, int64(3), true, due, `["a","b"]`},
+		{"rec2", nil, int64(-7), false, nil, nil},
+		{"rec3", math.Pi, nil, true, due.Add(time.Hour), `[]`},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write([]interface{}{nil, nil, nil, nil, nil, nil}); err == nil {
+		t.Error("必填列写入空值应失败")
+	}
+	if err := w.Write([]interface{}{"rec4", "x", nil, nil, nil, nil}); err == nil {
+		t.Error("类型不匹配应失败")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatal("缺少 PAR1 标记")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.structure()
+
+	if meta[3].(int64) != 3 {
+		t.Fatalf("num_rows = %v", meta[3])
+	}
+	schema := meta[2].([]interface{})
+	if len(schema) != len(columns)+1 || schema[0].(map[int16]interface{})[5].(int64) != int64(len(columns)) {
+		t.Fatalf("schema = %v", schema)
+	}
+	groups := meta[4].([]interface{})
+	if len(groups) != 2 {
+		t.Fatalf("行组数 = %d", len(groups))
+	}
+
+	decoder, _ := zstd.NewReader(nil)
+	defer decoder.Close()
+	got := make([][]interface{}, len(columns))
+	for _, g := range groups {
+		group := g.(map[int16]interface{})
+		numRows := int(group[3].(int64))
+		for c, chunk := range group[1].([]interface{}) {
+			colMeta := chunk.(map[int16]interface{})[3].(map[int16]interface{})
+			offset := int(colMeta[9].(int64))
+			page := &compactReader{data: data[offset:]}
+			header := page.structure()
+			compressed := int(header[3].(int64))
+			body, err := decoder.DecodeAll(data[offset+page.pos:offset+page.pos+compressed], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(header[2].(int64)) != len(body) {
+				t.Fatalf("页大小不一致")
+			}
+			got[c] = append(got[c], decodePage(t, columns[c], body, numRows)...)
+		}
+	}
+
+	for r, row := range rows {
+		for c, want := range row {
+			if ts, ok := want.(time.Time); ok {
+				want = ts.UnixMicro()
+			}
+			if got[c][r] != want {
+				t.Errorf("行 %d 列 %s = %v, 期望 %v", r, columns[c].Name, got[c][r], want)
+			}
+		}
+	}
+}
+
+// decodePage 解码数据页（RLE 定义级别 + PLAIN 值）
+func decodePage(t *testing.T, col Column, body []byte, numRows int) []interface{} {
+	levels := make([]byte, 0, numRows)
+	pos := 0
+	if col.Required {
+		for i := 0; i < numRows; i++ {
+			levels = append(levels, 1)
+		}
+	} else {
+		n := int(binary.LittleEndian.Uint32(body))
+		r := &compactReader{data: body[4 : 4+n]}
+		for r.pos < len(r.data) {
+			run := int(r.uvarint() >> 1)
+			v := r.byte()
+			for i := 0; i < run; i++ {
+				levels = append(levels, v)
+			}
+		}
+		pos = 4 + n
+	}
+	if len(levels) != numRows {
+		t.Fatalf("列 %s 定义级别数 = %d", col.Name, len(levels))
+	}
+
+	values := make([]interface{}, numRows)
+	bit := 0
+	for i, level := range levels {
+		if level == 0 {
+			continue
+		}
+		switch col.Type {
+		case TypeString, TypeJSON:
+			n := int(binary.LittleEndian.Uint32(body[pos:]))
+			values[i] = string(body[pos+4 : pos+4+n])
+			pos += 4 + n
+		case TypeInt64, TypeTimestamp:
+			values[i] = int64(binary.LittleEndian.Uint64(body[pos:]))
+			pos += 8
+		case TypeDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(body[pos:]))
+			pos += 8
+		case TypeBoolean:
+			values[i] = body[pos+bit/8]&(1<<(uint(bit)%8)) != 0
+			bit++
+		}
+	}
+	return values
+}
@@ -0,0 +1,144 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift Compact 协议的类型编号
+const (
+	compactBoolTrue  byte = 1
+	compactBoolFalse byte = 2
+	compactI32       byte = 5
+	compactI64       byte = 6
+	compactBinary    byte = 8
+	compactList      byte = 9
+	compactStruct    byte = 12
+)
+
+// compactWriter Thrift Compact 协议编码器（只实现 Parquet 元数据用到的类型）
+// 字段头按与上一个字段ID的差值编码，每层结构体各自记录上一个字段ID
+type compactWriter struct {
+	buf  bytes.Buffer
+	last []int16
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{last: []int16{0}}
+}
+
+// Finish 结束顶层结构体并返回编码结果
+func (w *compactWriter) Finish() []byte {
+	w.buf.WriteByte(0)
+	return w.buf.Bytes()
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := w.last[len(w.last)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.uvarint(uint64(uint16((id << 1) ^ (id >> 15))))
+	}
+	w.last[len(w.last)-1] = id
+}
+
+func (w *compactWriter) structBegin() {
+	w.last = append(w.last, 0)
+}
+
+func (w *compactWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+func (w *compactWriter) i32(v int32) {
+	w.uvarint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (w *compactWriter) i64(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) binary(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+func (w *compactWriter) listHeader(elemType byte, n int) {
+	if n < 15 {
+		w.buf.WriteByte(byte(n)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xF0 | elemType)
+	w.uvarint(uint64(n))
+}
+
+// BoolField 写入布尔字段（值编码在字段头中）
+func (w *compactWriter) BoolField(id int16, v bool) {
+	if v {
+		w.fieldHeader(id, compactBoolTrue)
+	} else {
+		w.fieldHeader(id, compactBoolFalse)
+	}
+}
+
+// I32Field 写入 i32 字段
+func (w *compactWriter) I32Field(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.i32(v)
+}
+
+// I64Field 写入 i64 字段
+func (w *compactWriter) I64Field(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.i64(v)
+}
+
+// StringField 写入字符串字段
+func (w *compactWriter) StringField(id int16, v string) {
+	w.fieldHeader(id, compactBinary)
+	w.binary(v)
+}
+
+// StructField 写入结构体字段，body 中写入结构体的字段
+func (w *compactWriter) StructField(id int16, body func()) {
+	w.fieldHeader(id, compactStruct)
+	w.structBegin()
+	body()
+	w.structEnd()
+}
+
+// I32ListField 写入 i32 列表字段
+func (w *compactWriter) I32ListField(id int16, values []int32) {
+	w.fieldHeader(id, compactList)
+	w.listHeader(compactI32, len(values))
+	for _, v := range values {
+		w.i32(v)
+	}
+}
+
+// StringListField 写入字符串列表字段
+func (w *compactWriter) StringListField(id int16, values []string) {
+	w.fieldHeader(id, compactList)
+	w.listHeader(compactBinary, len(values))
+	for _, v := range values {
+		w.binary(v)
+	}
+}
+
+// StructListField 写入结构体列表字段，body(i) 中写入第 i 个结构体的字段
+func (w *compactWriter) StructListField(id int16, n int, body func(i int)) {
+	w.fieldHeader(id, compactList)
+	w.listHeader(compactStruct, n)
+	for i := 0; i < n; i++ {
+		w.structBegin()
+		body(i)
+		w.structEnd()
+	}
+}
@@ -0,0 +1,420 @@
+// Package parquet 流式写入 Parquet 文件 ✨
+//
+// 只实现导出所需的子集：扁平（无嵌套）列，数据页 v1，值使用 PLAIN 编码、
+// 定义级别使用 RLE 编码，页按 ZSTD 压缩；每个行组的每一列写为一个数据页。
+// 写入时只在内存中缓存一个行组，适合逐行读取大表并写入临时文件
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Type 列类型（物理类型 + 逻辑类型）
+type Type string
+
+const (
+	TypeString    Type = "string"    // BYTE_ARRAY，UTF8
+	TypeJSON      Type = "json"      // BYTE_ARRAY，JSON 文本
+	TypeInt64     Type = "int64"     // INT64
+	TypeDouble    Type = "double"    // DOUBLE
+	TypeBoolean   Type = "boolean"   // BOOLEAN
+	TypeTimestamp Type = "timestamp" // INT64，TIMESTAMP(MICROS, UTC)
+)
+
+// Column 列定义
+type Column struct {
+	Name     string
+	Type     Type
+	Required bool // 不允许空值
+}
+
+// Parquet 枚举值（parquet.thrift）
+const (
+	physicalBoolean   int32 = 0
+	physicalInt64     int32 = 2
+	physicalDouble    int32 = 5
+	physicalByteArray int32 = 6
+
+	repetitionRequired int32 = 0
+	repetitionOptional int32 = 1
+
+	convertedUTF8            int32 = 0
+	convertedTimestampMicros int32 = 10
+	convertedJSON            int32 = 19
+
+	encodingPlain int32 = 0
+	encodingRLE   int32 = 3
+
+	codecZSTD int32 = 6
+
+	pageTypeData int32 = 0
+)
+
+var magic = []byte("PAR1")
+
+// DefaultRowGroupSize 默认每个行组的行数
+const DefaultRowGroupSize = 10000
+
+// createdBy 写入文件元数据的生成程序
+const createdBy = "luckdb parquet writer"
+
+// columnBuffer 当前行组中一列的缓存
+type columnBuffer struct {
+	levels []byte // 定义级别：1 有值，0 为空
+	values []byte // PLAIN 编码的非空值（布尔列为逐个字节，写页时再按位打包）
+}
+
+// columnChunk 已写出的列块
+type columnChunk struct {
+	offset       int64
+	compressed   int64
+	uncompressed int64
+	numValues    int64
+}
+
+// rowGroup 已写出的行组
+type rowGroup struct {
+	chunks  []columnChunk
+	numRows int64
+}
+
+// Writer 流式 Parquet 写入器
+type Writer struct {
+	out          io.Writer
+	offset       int64
+	columns      []Column
+	rowGroupSize int
+	buffers      []columnBuffer
+	rows         int
+	groups       []rowGroup
+	numRows      int64
+	encoder      *zstd.Encoder
+	closed       bool
+}
+
+// NewWriter 创建写入器并写出文件头，rowGroupSize <= 0 时使用默认值
+func NewWriter(out io.Writer, columns []Column, rowGroupSize int) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet: at least one column is required")
+	}
+	seen := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		switch col.Type {
+		case TypeString, TypeJSON, TypeInt64, TypeDouble, TypeBoolean, TypeTimestamp:
+		default:
+			return nil, fmt.Errorf("parquet: unsupported column type %q", col.Type)
+		}
+		if col.Name == "" || seen[col.Name] {
+			return nil, fmt.Errorf("parquet: empty or duplicate column name %q", col.Name)
+		}
+		seen[col.Name] = true
+	}
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		out:          out,
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		buffers:      make([]columnBuffer, len(columns)),
+		encoder:      encoder,
+	}
+	if err := w.write(magic); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// NumRows 已写入的行数
+func (w *Writer) NumRows() int64 {
+	return w.numRows + int64(w.rows)
+}
+
+// Write 写入一行，values 与列一一对应：
+// string / json 列为 string，int64 列为 int64，double 列为 float64，boolean 列为 bool，
+// timestamp 列为 time.Time；nil 表示空值
+func (w *Writer) Write(values []interface{}) error {
+	if w.closed {
+		return fmt.Errorf("parquet: writer is closed")
+	}
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("parquet: expected %d values, got %d", len(w.columns), len(values))
+	}
+	for i, value := range values {
+		if err := w.buffers[i].append(w.columns[i], value); err != nil {
+			return err
+		}
+	}
+	w.rows++
+	if w.rows >= w.rowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close 写出剩余的行组与文件尾（不关闭底层 io.Writer）
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	defer w.encoder.Close()
+	if err := w.flush(); err != nil {
+		return err
+	}
+	footer := w.footer()
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer)))); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
+
+func (w *Writer) write(p []byte) error {
+	n, err := w.out.Write(p)
+	w.offset += int64(n)
+	return err
+}
+
+// append 追加一个值到列缓存
+func (b *columnBuffer) append(col Column, value interface{}) error {
+	if value == nil {
+		if col.Required {
+			return fmt.Errorf("parquet: column %q is required", col.Name)
+		}
+		b.levels = append(b.levels, 0)
+		return nil
+	}
+
+	switch col.Type {
+	case TypeString, TypeJSON:
+		v, ok := value.(string)
+		if !ok {
+			return typeError(col, value)
+		}
+		b.values = binary.LittleEndian.AppendUint32(b.values, uint32(len(v)))
+		b.values = append(b.values, v...)
+	case TypeInt64:
+		v, ok := value.(int64)
+		if !ok {
+			return typeError(col, value)
+		}
+		b.values = binary.LittleEndian.AppendUint64(b.values, uint64(v))
+	case TypeDouble:
+		v, ok := value.(float64)
+		if !ok {
+			return typeError(col, value)
+		}
+		b.values = binary.LittleEndian.AppendUint64(b.values, math.Float64bits(v))
+	case TypeBoolean:
+		v, ok := value.(bool)
+		if !ok {
+			return typeError(col, value)
+		}
+		if v {
+			b.values = append(b.values, 1)
+		} else {
+			b.values = append(b.values, 0)
+		}
+	case TypeTimestamp:
+		v, ok := value.(time.Time)
+		if !ok {
+			return typeError(col, value)
+		}
+		b.values = binary.LittleEndian.AppendUint64(b.values, uint64(v.UnixMicro()))
+	}
+	b.levels = append(b.levels, 1)
+	return nil
+}
+
+func typeError(col Column, value interface{}) error {
+	return fmt.Errorf("parquet: column %q (%s) cannot hold %T", col.Name, col.Type, value)
+}
+
+// flush 将当前行组的每一列写为一个压缩的数据页
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	group := rowGroup{chunks: make([]columnChunk, len(w.columns)), numRows: int64(w.rows)}
+	for i, col := range w.columns {
+		chunk, err := w.writePage(col, &w.buffers[i], w.rows)
+		if err != nil {
+			return err
+		}
+		group.chunks[i] = chunk
+		w.buffers[i] = columnBuffer{}
+	}
+	w.groups = append(w.groups, group)
+	w.numRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+// writePage 编码并写出一个数据页（页头 + ZSTD 压缩的页体）
+func (w *Writer) writePage(col Column, buf *columnBuffer, numValues int) (columnChunk, error) {
+	var body []byte
+	if !col.Required {
+		levels := encodeLevels(buf.levels)
+		body = binary.LittleEndian.AppendUint32(body, uint32(len(levels)))
+		body = append(body, levels...)
+	}
+	if col.Type == TypeBoolean {
+		body = append(body, packBooleans(buf.values)...)
+	} else {
+		body = append(body, buf.values...)
+	}
+	compressed := w.encoder.EncodeAll(body, nil)
+
+	header := newCompactWriter()
+	header.I32Field(1, pageTypeData)
+	header.I32Field(2, int32(len(body)))
+	header.I32Field(3, int32(len(compressed)))
+	header.StructField(5, func() {
+		header.I32Field(1, int32(numValues))
+		header.I32Field(2, encodingPlain)
+		header.I32Field(3, encodingRLE)
+		header.I32Field(4, encodingRLE)
+	})
+	headerBytes := header.Finish()
+
+	chunk := columnChunk{
+		offset:       w.offset,
+		compressed:   int64(len(headerBytes) + len(compressed)),
+		uncompressed: int64(len(headerBytes) + len(body)),
+		numValues:    int64(numValues),
+	}
+	if err := w.write(headerBytes); err != nil {
+		return chunk, err
+	}
+	if err := w.write(compressed); err != nil {
+		return chunk, err
+	}
+	return chunk, nil
+}
+
+// encodeLevels 按 RLE/位打包混合编码写出定义级别（位宽 1，全部使用 RLE 段）
+func encodeLevels(levels []byte) []byte {
+	var out []byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		out = binary.AppendUvarint(out, uint64(j-i)<<1)
+		out = append(out, levels[i])
+		i = j
+	}
+	return out
+}
+
+// packBooleans 按位打包布尔值（低位在前）
+func packBooleans(values []byte) []byte {
+	out := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v != 0 {
+			out[i/8] |= 1 << (uint(i) % 8)
+		}
+	}
+	return out
+}
+
+// footer 编码文件元数据（FileMetaData）
+func (w *Writer) footer() []byte {
+	meta := newCompactWriter()
+	meta.I32Field(1, 1)
+	meta.StructListField(2, len(w.columns)+1, func(i int) {
+		if i == 0 {
+			meta.StringField(4, "schema")
+			meta.I32Field(5, int32(len(w.columns)))
+			return
+		}
+		writeSchemaElement(meta, w.columns[i-1])
+	})
+	meta.I64Field(3, w.numRows)
+	meta.StructListField(4, len(w.groups), func(g int) {
+		group := w.groups[g]
+		var total int64
+		for _, chunk := range group.chunks {
+			total += chunk.uncompressed
+		}
+		meta.StructListField(1, len(group.chunks), func(c int) {
+			chunk := group.chunks[c]
+			col := w.columns[c]
+			meta.I64Field(2, chunk.offset)
+			meta.StructField(3, func() {
+				meta.I32Field(1, physicalType(col.Type))
+				meta.I32ListField(2, []int32{encodingPlain, encodingRLE})
+				meta.StringListField(3, []string{col.Name})
+				meta.I32Field(4, codecZSTD)
+				meta.I64Field(5, chunk.numValues)
+				meta.I64Field(6, chunk.uncompressed)
+				meta.I64Field(7, chunk.compressed)
+				meta.I64Field(9, chunk.offset)
+			})
+		})
+		meta.I64Field(2, total)
+		meta.I64Field(3, group.numRows)
+	})
+	meta.StringField(6, createdBy)
+	return meta.Finish()
+}
+
+// writeSchemaElement 编码列的 SchemaElement（同时写入 ConvertedType 与 LogicalType 以兼容旧读取器）
+func writeSchemaElement(meta *compactWriter, col Column) {
+	meta.I32Field(1, physicalType(col.Type))
+	repetition := repetitionOptional
+	if col.Required {
+		repetition = repetitionRequired
+	}
+	meta.I32Field(3, repetition)
+	meta.StringField(4, col.Name)
+	switch col.Type {
+	case TypeString:
+		meta.I32Field(6, convertedUTF8)
+		meta.StructField(10, func() {
+			meta.StructField(1, func() {}) // STRING
+		})
+	case TypeJSON:
+		meta.I32Field(6, convertedJSON)
+		meta.StructField(10, func() {
+			meta.StructField(12, func() {}) // JSON
+		})
+	case TypeTimestamp:
+		meta.I32Field(6, convertedTimestampMicros)
+		meta.StructField(10, func() {
+			meta.StructField(8, func() { // TIMESTAMP
+				meta.BoolField(1, true) // isAdjustedToUTC
+				meta.StructField(2, func() {
+					meta.StructField(2, func() {}) // MICROS
+				})
+			})
+		})
+	}
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case TypeInt64, TypeTimestamp:
+		return physicalInt64
+	case TypeDouble:
+		return physicalDouble
+	case TypeBoolean:
+		return physicalBoolean
+	default:
+		return physicalByteArray
+	}
+}
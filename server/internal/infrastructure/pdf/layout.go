@@ -0,0 +1,248 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Margin 页边距（点），页脚绘制在下边距内
+const Margin = 40
+
+// MinColumnWidth 表格列的最小宽度（点）
+const MinColumnWidth = 45
+
+var (
+	titleStyle    = Style{Size: 16, Bold: true}
+	subtitleStyle = Style{Size: 9, Gray: 0.45}
+	labelStyle    = Style{Size: 9, Bold: true, Gray: 0.35}
+	bodyStyle     = Style{Size: 10}
+	cellStyle     = Style{Size: 8.5}
+	headCellStyle = Style{Size: 8.5, Bold: true}
+	footerStyle   = Style{Size: 8, Gray: 0.5}
+)
+
+const (
+	titleLineHeight    = 20
+	subtitleLineHeight = 13
+	bodyLineHeight     = 14
+	tableHeadHeight    = 18
+	tableRowHeight     = 16
+	cellPadding        = 4
+	maxTitleLines      = 3
+)
+
+// Header 文档开头的标题与副标题
+type Header struct {
+	Title    string
+	Subtitle string
+}
+
+// DetailRow 详情页的一行（标签与值）
+type DetailRow struct {
+	Label string
+	Value string
+}
+
+// TableColumn 表格列
+type TableColumn struct {
+	Title string
+	Width float64 // 列宽（点），由 FitColumns 计算
+}
+
+// cursor 排版位置：当前页面与下一行顶部到页面顶部的距离
+type cursor struct {
+	doc  *Document
+	page *Page
+	y    float64
+}
+
+func newCursor(doc *Document) *cursor {
+	c := &cursor{doc: doc}
+	c.newPage()
+	return c
+}
+
+func (c *cursor) newPage() {
+	c.page = c.doc.AddPage()
+	c.y = Margin
+}
+
+// bottom 内容区域的下边界
+func (c *cursor) bottom() float64 {
+	return c.doc.size.Height - Margin
+}
+
+// header 绘制标题、副标题与分隔线
+func (c *cursor) header(header Header) {
+	lines := Wrap(header.Title, titleStyle, c.doc.ContentWidth())
+	if len(lines) > maxTitleLines {
+		lines = append(lines[:maxTitleLines-1], Truncate(strings.Join(lines[maxTitleLines-1:], " "), titleStyle, c.doc.ContentWidth()))
+	}
+	for _, line := range lines {
+		c.page.Text(Margin, c.y+15, titleStyle, line)
+		c.y += titleLineHeight
+	}
+	if header.Subtitle != "" {
+		c.page.Text(Margin, c.y+10, subtitleStyle, Truncate(header.Subtitle, subtitleStyle, c.doc.ContentWidth()))
+		c.y += subtitleLineHeight
+	}
+	c.y += 8
+	c.page.Line(Margin, c.y, Margin+c.doc.ContentWidth(), c.y, 0.8, 0.75)
+	c.y += 14
+}
+
+// ContentWidth 页面内容区域的宽度（点）
+func (d *Document) ContentWidth() float64 {
+	return d.size.Width - 2*Margin
+}
+
+// RenderDetail 将记录排版为标签、值两列（值按宽度折行，长值跨页继续）
+func RenderDetail(doc *Document, header Header, rows []DetailRow) {
+	c := newCursor(doc)
+	c.header(header)
+
+	labelWidth := c.doc.ContentWidth() * 0.3
+	if labelWidth > 150 {
+		labelWidth = 150
+	}
+	const gap = 12
+	valueX := Margin + labelWidth + gap
+	valueWidth := c.doc.ContentWidth() - labelWidth - gap
+
+	for _, row := range rows {
+		labels := Wrap(row.Label, labelStyle, labelWidth)
+		value := row.Value
+		if strings.TrimSpace(value) == "" {
+			value = "-"
+		}
+		values := Wrap(value, bodyStyle, valueWidth)
+		n := len(labels)
+		if len(values) > n {
+			n = len(values)
+		}
+		// 行的前两行不与标题分开
+		if keep := float64(min(n, 2)) * bodyLineHeight; c.y+keep > c.bottom() {
+			c.newPage()
+		}
+		for i := 0; i < n; i++ {
+			if c.y+bodyLineHeight > c.bottom() {
+				c.newPage()
+			}
+			if i < len(labels) {
+				c.page.Text(Margin, c.y+10.5, labelStyle, labels[i])
+			}
+			if i < len(values) {
+				c.page.Text(valueX, c.y+10.5, bodyStyle, values[i])
+			}
+			c.y += bodyLineHeight
+		}
+		c.y += 5
+		if c.y <= c.bottom() {
+			c.page.Line(Margin, c.y, Margin+c.doc.ContentWidth(), c.y, 0.5, 0.9)
+		}
+		c.y += 7
+	}
+}
+
+// FitColumns 按页面宽度缩放列宽：所有列按比例缩放到内容宽度，缩放后有列窄于 MinColumnWidth 时
+// 从末尾省略列，返回的宽度只包含可以显示的列
+func FitColumns(widths []float64, available float64) []float64 {
+	shown := len(widths)
+	for ; shown > 0; shown-- {
+		total := 0.0
+		for _, w := range widths[:shown] {
+			total += w
+		}
+		if total <= 0 {
+			continue
+		}
+		scale := available / total
+		fits := true
+		for _, w := range widths[:shown] {
+			if w*scale < MinColumnWidth {
+				fits = false
+				break
+			}
+		}
+		if fits || shown == 1 {
+			fitted := make([]float64, shown)
+			for i, w := range widths[:shown] {
+				fitted[i] = w * scale
+			}
+			return fitted
+		}
+	}
+	return nil
+}
+
+// RenderTable 分页绘制表格（每页重复表头，单元格只显示一行，超出部分截断），
+// 多于列数的单元格被忽略；note 非空时绘制在表格之后
+func RenderTable(doc *Document, header Header, columns []TableColumn, rows [][]string, note string) {
+	c := newCursor(doc)
+	c.header(header)
+
+	width := 0.0
+	for _, column := range columns {
+		width += column.Width
+	}
+	head := func() {
+		c.page.FillRect(Margin, c.y, width, tableHeadHeight, 0.93)
+		x := float64(Margin)
+		for _, column := range columns {
+			c.page.Text(x+cellPadding, c.y+12.5, headCellStyle, Truncate(column.Title, headCellStyle, column.Width-2*cellPadding))
+			x += column.Width
+		}
+		c.y += tableHeadHeight
+	}
+	head()
+
+	for _, row := range rows {
+		if c.y+tableRowHeight > c.bottom() {
+			c.newPage()
+			head()
+		}
+		x := float64(Margin)
+		for i, column := range columns {
+			if i < len(row) && row[i] != "" {
+				c.page.Text(x+cellPadding, c.y+11, cellStyle, Truncate(row[i], cellStyle, column.Width-2*cellPadding))
+			}
+			x += column.Width
+		}
+		c.y += tableRowHeight
+		c.page.Line(Margin, c.y, Margin+width, c.y, 0.4, 0.88)
+	}
+
+	if note != "" {
+		if c.y+subtitleLineHeight+10 > c.bottom() {
+			c.newPage()
+		}
+		c.y += 10
+		c.page.Text(Margin, c.y+10, subtitleStyle, Truncate(note, subtitleStyle, c.doc.ContentWidth()))
+	}
+}
+
+// RenderText 将纯文本排版为正文（保留换行，按宽度折行，长内容跨页继续）
+func RenderText(doc *Document, text string) {
+	c := newCursor(doc)
+	for _, line := range Wrap(text, bodyStyle, doc.ContentWidth()) {
+		if c.y+bodyLineHeight > c.bottom() {
+			c.newPage()
+		}
+		c.page.Text(Margin, c.y+10.5, bodyStyle, line)
+		c.y += bodyLineHeight
+	}
+}
+
+// AddFooters 为每一页添加页脚：左侧为给定文本，右侧为页码（第几页 / 总页数）
+func AddFooters(doc *Document, text string) {
+	total := len(doc.pages)
+	baseline := doc.size.Height - Margin + 20
+	for i, page := range doc.pages {
+		number := fmt.Sprintf("%d / %d", i+1, total)
+		numberWidth := TextWidth(number, footerStyle)
+		if text != "" {
+			page.Text(Margin, baseline, footerStyle, Truncate(text, footerStyle, doc.ContentWidth()-numberWidth-20))
+		}
+		page.Text(doc.size.Width-Margin-numberWidth, baseline, footerStyle, number)
+	}
+}
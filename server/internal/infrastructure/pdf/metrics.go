@@ -0,0 +1,125 @@
+package pdf
+
+import "strings"
+
+// wideWidth 全角字符的字宽（千分之一字号）
+const wideWidth = 1000
+
+// ellipsis 截断文本时追加的省略号
+const ellipsis = "..."
+
+// helveticaWidths Helvetica 中 ASCII 32–126 的字宽（AFM，千分之一字号）
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space – /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 – ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ – O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P – _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` – o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p – ~
+}
+
+// helveticaBoldWidths Helvetica-Bold 中 ASCII 32–126 的字宽（AFM，千分之一字号）
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278, // space – /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611, // 0 – ?
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, // @ – O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556, // P – _
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, // ` – o
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584, // p – ~
+}
+
+// isWide 字符是否使用 CJK 字体（ASCII 可打印字符之外的字符）
+func isWide(r rune) bool {
+	return r < 0x20 || r > 0x7E
+}
+
+// normalize 将制表符替换为空格并去掉其他控制字符
+func normalize(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t':
+			return ' '
+		case r < 0x20 || r == 0x7F:
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// runeWidth 字符宽度（点）
+func runeWidth(r rune, style Style) float64 {
+	if isWide(r) {
+		return wideWidth * style.Size / 1000
+	}
+	widths := &helveticaWidths
+	if style.Bold {
+		widths = &helveticaBoldWidths
+	}
+	return float64(widths[r-0x20]) * style.Size / 1000
+}
+
+// TextWidth 单行文本的宽度（点）
+func TextWidth(text string, style Style) float64 {
+	width := 0.0
+	for _, r := range normalize(text) {
+		width += runeWidth(r, style)
+	}
+	return width
+}
+
+// Truncate 截断单行文本使其不超过给定宽度（换行视为空格，截断时追加省略号）
+func Truncate(text string, style Style, width float64) string {
+	text = normalize(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", " "), "\n", " "))
+	if TextWidth(text, style) <= width {
+		return text
+	}
+	limit := width - TextWidth(ellipsis, style)
+	used := 0.0
+	var b strings.Builder
+	for _, r := range text {
+		w := runeWidth(r, style)
+		if used+w > limit {
+			break
+		}
+		used += w
+		b.WriteRune(r)
+	}
+	return strings.TrimRight(b.String(), " ") + ellipsis
+}
+
+// Wrap 按宽度将文本折为多行：保留原有换行，西文在空格处断行，CJK 字符前后均可断行，
+// 单个词超过宽度时强制断开
+func Wrap(text string, style Style, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lines = append(lines, wrapParagraph([]rune(normalize(paragraph)), style, width)...)
+	}
+	return lines
+}
+
+func wrapParagraph(runes []rune, style Style, width float64) []string {
+	var lines []string
+	start, breakAt := 0, -1
+	used := 0.0
+	for i := 0; i < len(runes); {
+		w := runeWidth(runes[i], style)
+		if used+w > width && i > start {
+			end := i
+			if canBreak := runes[i] == ' ' || isWide(runes[i]) || isWide(runes[i-1]); !canBreak && breakAt > start {
+				end = breakAt
+			}
+			lines = append(lines, strings.TrimRight(string(runes[start:end]), " "))
+			for end < len(runes) && runes[end] == ' ' {
+				end++
+			}
+			start, i, breakAt, used = end, end, -1, 0
+			continue
+		}
+		used += w
+		if runes[i] == ' ' || isWide(runes[i]) || (i+1 < len(runes) && isWide(runes[i+1])) {
+			breakAt = i + 1
+		}
+		i++
+	}
+	return append(lines, strings.TrimRight(string(runes[start:]), " "))
+}
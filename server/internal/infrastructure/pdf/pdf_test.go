@@ -0,0 +1,131 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextWidth(t *testing.T) {
+	style := Style{Size: 10}
+	if got := TextWidth("Hi", style); got != (722+222)*10.0/1000 {
+		t.Errorf("Hi 宽度错误: %v", got)
+	}
+	if got := TextWidth("中文", style); got != 20 {
+		t.Errorf("全角字符宽度应为字号，得到 %v", got)
+	}
+	if bold := TextWidth("a", Style{Size: 10, Bold: true}); bold != 5.56 {
+		t.Errorf("粗体宽度错误: %v", bold)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	style := Style{Size: 10}
+	lines := Wrap("hello world again\n\nsecond", style, TextWidth("hello world", style))
+	want := []string{"hello world", "again", "", "second"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("期望 %q，得到 %q", want, lines)
+	}
+
+	// CJK 字符之间可以断行
+	if lines := Wrap("一二三四五", style, 30); strings.Join(lines, "|") != "一二三|四五" {
+		t.Errorf("CJK 断行错误: %q", lines)
+	}
+	// 单个词超过宽度时强制断开
+	if lines := Wrap("abcdefghij", style, TextWidth("abcd", style)); len(lines) < 2 || lines[0] != "abcd" {
+		t.Errorf("长词应强制断开: %q", lines)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	style := Style{Size: 10}
+	if got := Truncate("short", style, 100); got != "short" {
+		t.Errorf("不应截断: %q", got)
+	}
+	got := Truncate("a much longer line\nwith a break", style, 60)
+	if !strings.HasSuffix(got, ellipsis) || strings.Contains(got, "\n") || TextWidth(got, style) > 60 {
+		t.Errorf("截断结果错误: %q", got)
+	}
+}
+
+func TestFitColumns(t *testing.T) {
+	fitted := FitColumns([]float64{100, 300}, 200)
+	if len(fitted) != 2 || fitted[0] != 50 || fitted[1] != 150 {
+		t.Errorf("应按比例缩放: %v", fitted)
+	}
+	// 缩放后窄于最小宽度的列从末尾省略
+	fitted = FitColumns([]float64{100, 100, 100, 100, 100, 100}, 200)
+	if len(fitted) != 4 || fitted[0] != 50 {
+		t.Errorf("应省略末尾的列: %v", fitted)
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	doc := New(A4)
+	RenderText(doc, strings.Repeat("第一段\n\n", 60))
+	if len(doc.Pages()) != 3 {
+		t.Errorf("121 行正文应跨 3 页，得到 %d 页", len(doc.Pages()))
+	}
+}
+
+func TestDocumentWriteTo(t *testing.T) {
+	doc := New(A4)
+	doc.SetInfo("发票 Invoice", time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC))
+	RenderDetail(doc, Header{Title: "发票 (Invoice)", Subtitle: "订单"}, []DetailRow{
+		{Label: "金额", Value: "¥1,200.00"},
+		{Label: "备注", Value: strings.Repeat("很长的备注 ", 800)},
+	})
+	AddFooters(doc, "发票")
+	if len(doc.Pages()) < 2 {
+		t.Fatalf("长内容应跨页，得到 %d 页", len(doc.Pages()))
+	}
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if int(n) != len(data) || !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("文件结构错误")
+	}
+
+	// startxref 指向交叉引用表，表中每个偏移量指向对应的对象
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("缺少 startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref 偏移量错误")
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	if len(entries) != firstPageObject-1+2*len(doc.Pages()) {
+		t.Fatalf("交叉引用条目数错误: %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		if prefix := strconv.Itoa(i+1) + " 0 obj"; !bytes.HasPrefix(data[offset:], []byte(prefix)) {
+			t.Fatalf("对象 %d 的偏移量错误", i+1)
+		}
+	}
+
+	// 内容流：西文使用 Helvetica 字面量（括号已转义），中文使用 UTF-16 十六进制
+	stream := data[bytes.Index(data, []byte("stream\n"))+len("stream\n"):]
+	r, err := zlib.NewReader(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(r)
+	if !bytes.Contains(content, []byte(`/F2 16 Tf ( \(Invoice\)) Tj`)) {
+		t.Errorf("西文文本错误:\n%s", content)
+	}
+	if !bytes.Contains(content, []byte("<53D17968>")) {
+		t.Errorf("中文文本应以 UTF-16 编码:\n%s", content)
+	}
+}
@@ -0,0 +1,268 @@
+// Package pdf 生成简单的 PDF 文档 ✨
+//
+// 只实现打印记录与视图所需的子集：文本、直线与灰度填充矩形，内容流按 Flate 压缩。
+// 西文（ASCII 可打印字符）使用内置的 Helvetica / Helvetica-Bold，按 AFM 字宽排版；
+// 其他字符（中日韩文字、全角符号等）使用 Adobe-GB1 预置的 STSong-Light（Type0/CID 字体，
+// 不嵌入字形，由阅读器提供），按全角宽度排版。坐标以页面左上角为原点、单位为点（1/72 英寸）
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// Size 页面尺寸（点）
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4     = Size{Width: 595.28, Height: 841.89}
+	Letter = Size{Width: 612, Height: 792}
+)
+
+// Landscape 横向页面
+func (s Size) Landscape() Size {
+	if s.Width < s.Height {
+		return Size{Width: s.Height, Height: s.Width}
+	}
+	return s
+}
+
+// Style 文本样式
+type Style struct {
+	Size float64 // 字号（点）
+	Bold bool
+	Gray float64 // 灰度，0 为黑色、1 为白色
+}
+
+// Document PDF 文档（页面内容保存在内存中，写出前可继续在任意页面绘制，如页脚）
+type Document struct {
+	size    Size
+	title   string
+	created time.Time
+	pages   []*Page
+}
+
+// Page 页面
+type Page struct {
+	size    Size
+	content bytes.Buffer
+}
+
+// New 创建文档
+func New(size Size) *Document {
+	return &Document{size: size}
+}
+
+// SetInfo 设置文档标题与创建时间（写入文档信息字典）
+func (d *Document) SetInfo(title string, created time.Time) {
+	d.title = title
+	d.created = created
+}
+
+// Size 页面尺寸
+func (d *Document) Size() Size {
+	return d.size
+}
+
+// AddPage 添加页面
+func (d *Document) AddPage() *Page {
+	page := &Page{size: d.size}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages 已添加的页面
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Text 在 (x, y) 处绘制单行文本，y 为基线到页面顶部的距离
+func (p *Page) Text(x, y float64, style Style, text string) {
+	runs := splitRuns(normalize(text))
+	if len(runs) == 0 {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT %s g %s G %s %s Td\n", num(style.Gray), num(style.Gray), num(x), num(p.size.Height-y))
+	for _, r := range runs {
+		if r.wide {
+			// CJK 字体没有粗体，使用描边加粗
+			if style.Bold {
+				fmt.Fprintf(&p.content, "2 Tr %s w ", num(style.Size/30))
+			}
+			fmt.Fprintf(&p.content, "/F3 %s Tf <%s> Tj", num(style.Size), utf16Hex(r.text))
+			if style.Bold {
+				p.content.WriteString(" 0 Tr")
+			}
+			p.content.WriteString("\n")
+			continue
+		}
+		font := "/F1"
+		if style.Bold {
+			font = "/F2"
+		}
+		fmt.Fprintf(&p.content, "%s %s Tf (%s) Tj\n", font, num(style.Size), escapeLiteral(r.text))
+	}
+	p.content.WriteString("ET\n")
+}
+
+// Line 绘制直线
+func (p *Page) Line(x1, y1, x2, y2, width, gray float64) {
+	fmt.Fprintf(&p.content, "%s G %s w %s %s m %s %s l S\n",
+		num(gray), num(width), num(x1), num(p.size.Height-y1), num(x2), num(p.size.Height-y2))
+}
+
+// FillRect 填充矩形，(x, y) 为左上角
+func (p *Page) FillRect(x, y, width, height, gray float64) {
+	fmt.Fprintf(&p.content, "%s g %s %s %s %s re f\n",
+		num(gray), num(x), num(p.size.Height-y-height), num(width), num(height))
+}
+
+// 固定对象编号：目录、页面树、三个字体及其 CID 字体与字体描述、信息字典，页面对象从 firstPageObject 开始
+const (
+	objCatalog = iota + 1
+	objPages
+	objHelvetica
+	objHelveticaBold
+	objSong
+	objSongCID
+	objSongDescriptor
+	objInfo
+	firstPageObject
+)
+
+// WriteTo 写出 PDF 文件
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	out := &countingWriter{w: w}
+	offsets := make([]int64, firstPageObject+2*len(d.pages))
+	object := func(id int, body string) {
+		offsets[id] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", id, body)
+	}
+
+	io.WriteString(out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObject+2*i)
+	}
+	object(objCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", objPages))
+	object(objPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] "+
+		"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R /F3 %d 0 R >> >> >>",
+		strings.Join(kids, " "), len(d.pages), num(d.size.Width), num(d.size.Height),
+		objHelvetica, objHelveticaBold, objSong))
+	object(objHelvetica, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object(objHelveticaBold, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(objSong, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UTF16-H /DescendantFonts [%d 0 R] >>", objSongCID))
+	object(objSongCID, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 4 >> /FontDescriptor %d 0 R /DW %d >>",
+		objSongDescriptor, wideWidth))
+	object(objSongDescriptor, "<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] "+
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	info := "<< /Producer (LuckDB)"
+	if d.title != "" {
+		info += " /Title <FEFF" + utf16Hex(d.title) + ">"
+	}
+	if !d.created.IsZero() {
+		info += " /CreationDate (D:" + d.created.UTC().Format("20060102150405") + "Z)"
+	}
+	object(objInfo, info+" >>")
+
+	for i, page := range d.pages {
+		pageID := firstPageObject + 2*i
+		object(pageID, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R >>", objPages, pageID+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(page.content.Bytes())
+		if err := zw.Close(); err != nil {
+			return out.n, err
+		}
+		offsets[pageID+1] = out.n
+		fmt.Fprintf(out, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", pageID+1, compressed.Len())
+		out.Write(compressed.Bytes())
+		io.WriteString(out, "\nendstream\nendobj\n")
+	}
+
+	xref := out.n
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets), objCatalog, objInfo, xref)
+	return out.n, out.err
+}
+
+// countingWriter 记录已写出的字节数（用于交叉引用表），并保留第一个写入错误
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+// run 使用同一字体的一段文本
+type run struct {
+	text string
+	wide bool
+}
+
+// splitRuns 按字体拆分文本
+func splitRuns(text string) []run {
+	var runs []run
+	var current strings.Builder
+	wide := false
+	for _, r := range text {
+		if current.Len() > 0 && isWide(r) != wide {
+			runs = append(runs, run{text: current.String(), wide: wide})
+			current.Reset()
+		}
+		wide = isWide(r)
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		runs = append(runs, run{text: current.String(), wide: wide})
+	}
+	return runs
+}
+
+// escapeLiteral 转义字面量字符串中的特殊字符
+func escapeLiteral(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(text)
+}
+
+// utf16Hex 文本的 UTF-16BE 十六进制编码
+func utf16Hex(text string) string {
+	var b strings.Builder
+	for _, unit := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	return b.String()
+}
+
+// num 数值的紧凑表示（最多两位小数）
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
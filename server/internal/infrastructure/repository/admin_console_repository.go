@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/adminconsole"
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// AdminConsoleRepositoryImpl 管理控制台仓储GORM实现
+type AdminConsoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAdminConsoleRepository 创建管理控制台仓储
+func NewAdminConsoleRepository(db *gorm.DB) adminconsole.Repository {
+	return &AdminConsoleRepositoryImpl{db: db}
+}
+
+// CreateSession 保存新会话
+func (r *AdminConsoleRepositoryImpl) CreateSession(ctx context.Context, session *adminconsole.Session) error {
+	model := models.AdminConsoleSession{
+		ID:        session.ID,
+		UserID:    session.UserID,
+		Email:     session.Email,
+		TokenHash: session.TokenHash,
+		IPAddress: session.IPAddress,
+		UserAgent: session.UserAgent,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create admin console session: %w", err)
+	}
+	return nil
+}
+
+// FindSessionByHash 按令牌摘要查找会话
+func (r *AdminConsoleRepositoryImpl) FindSessionByHash(ctx context.Context, tokenHash string) (*adminconsole.Session, error) {
+	var model models.AdminConsoleSession
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin console session: %w", err)
+	}
+	return &adminconsole.Session{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Email:     model.Email,
+		TokenHash: model.TokenHash,
+		IPAddress: model.IPAddress,
+		UserAgent: model.UserAgent,
+		ExpiresAt: model.ExpiresAt,
+		RevokedAt: model.RevokedAt,
+		CreatedAt: model.CreatedAt,
+	}, nil
+}
+
+// RevokeSession 注销会话
+func (r *AdminConsoleRepositoryImpl) RevokeSession(ctx context.Context, sessionID string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.AdminConsoleSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke admin console session: %w", err)
+	}
+	return nil
+}
+
+// RecordAudit 写入控制台操作的审计日志（元数据标记 console 与会话）
+func (r *AdminConsoleRepositoryImpl) RecordAudit(ctx context.Context, entry *adminconsole.AuditEntry) error {
+	detail := map[string]interface{}{"console": true}
+	for k, v := range entry.Detail {
+		detail[k] = v
+	}
+	metadata, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal admin console metadata: %w", err)
+	}
+
+	meta := string(metadata)
+	audit := models.AuditLog{
+		ID:           utils.GenerateIDWithPrefix("aud"),
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		Status:       "success",
+		Severity:     "warning",
+		Metadata:     &meta,
+		CreatedTime:  entry.OccurredAt,
+	}
+	if entry.Failed {
+		audit.Status = "failure"
+	}
+	if entry.ResourceID != "" {
+		audit.ResourceID = &entry.ResourceID
+	}
+	if entry.ActorID != "" {
+		audit.UserID = &entry.ActorID
+	}
+	if entry.ActorEmail != "" {
+		audit.UserEmail = &entry.ActorEmail
+	}
+	if entry.SessionID != "" {
+		audit.SessionID = &entry.SessionID
+	}
+	if entry.IPAddress != "" {
+		audit.IPAddress = &entry.IPAddress
+	}
+	if err := r.db.WithContext(ctx).Omit("User", "Organization", "Space", "Base", "Table", "Record", "Field").
+		Create(&audit).Error; err != nil {
+		return fmt.Errorf("failed to record admin console audit log: %w", err)
+	}
+	return nil
+}
+
+// CountFailedLogins 统计 since 之后同一邮箱或同一来源 IP 的登录失败次数（按审计日志统计，多实例共享）
+func (r *AdminConsoleRepositoryImpl) CountFailedLogins(ctx context.Context, email, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.AuditLog{}).
+		Where("action = ? AND created_time > ?", adminconsole.ActionLoginFailed, since).
+		Where("user_email = ? OR ip_address = ?", email, ip).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count admin console failed logins: %w", err)
+	}
+	return count, nil
+}
+
+// ListFeatureOverrides 列出全部功能开关覆盖
+func (r *AdminConsoleRepositoryImpl) ListFeatureOverrides(ctx context.Context) ([]*adminconsole.FeatureOverride, error) {
+	var list []models.FeatureFlagOverride
+	if err := r.db.WithContext(ctx).Order("key").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flag overrides: %w", err)
+	}
+	overrides := make([]*adminconsole.FeatureOverride, 0, len(list))
+	for _, m := range list {
+		overrides = append(overrides, &adminconsole.FeatureOverride{
+			Key:       m.Key,
+			Enabled:   m.Enabled,
+			UpdatedBy: m.UpdatedBy,
+			UpdatedAt: m.UpdatedAt,
+		})
+	}
+	return overrides, nil
+}
+
+// SaveFeatureOverride 保存功能开关覆盖（已存在时覆盖）
+func (r *AdminConsoleRepositoryImpl) SaveFeatureOverride(ctx context.Context, override *adminconsole.FeatureOverride) error {
+	model := models.FeatureFlagOverride{
+		Key:       override.Key,
+		Enabled:   override.Enabled,
+		UpdatedBy: override.UpdatedBy,
+		UpdatedAt: override.UpdatedAt,
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_by", "updated_at"}),
+	}).Create(&model).Error
+	if err != nil {
+		return fmt.Errorf("failed to save feature flag override: %w", err)
+	}
+	return nil
+}
+
+// DeleteFeatureOverride 删除功能开关覆盖
+func (r *AdminConsoleRepositoryImpl) DeleteFeatureOverride(ctx context.Context, key string) error {
+	if err := r.db.WithContext(ctx).Where("key = ?", key).Delete(&models.FeatureFlagOverride{}).Error; err != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", err)
+	}
+	return nil
+}
+
+// ==================== 跨工作空间检索 ====================
+
+// AdminDirectoryImpl 跨工作空间检索与所有权变更的GORM实现
+type AdminDirectoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAdminDirectory 创建跨工作空间检索
+func NewAdminDirectory(db *gorm.DB) adminconsole.Directory {
+	return &AdminDirectoryImpl{db: db}
+}
+
+// spaceSummaryRow 工作空间检索结果行
+type spaceSummaryRow struct {
+	ID         string    `gorm:"column:id"`
+	Name       string    `gorm:"column:name"`
+	CreatedBy  string    `gorm:"column:created_by"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	OwnerID    *string   `gorm:"column:owner_id"`
+	OwnerEmail *string   `gorm:"column:owner_email"`
+	Members    int64     `gorm:"column:members"`
+	Bases      int64     `gorm:"column:bases"`
+	Tables     int64     `gorm:"column:tables"`
+}
+
+// SearchSpaces 检索工作空间（按ID精确匹配，按名称与所有者邮箱模糊匹配）
+func (r *AdminDirectoryImpl) SearchSpaces(ctx context.Context, filter adminconsole.SpaceFilter) ([]*adminconsole.SpaceSummary, int64, error) {
+	query := r.db.WithContext(ctx).Table("space AS s").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT principal_id FROM collaborators
+			WHERE resource_id = s.id AND role_name = ? AND principal_type = ?
+			ORDER BY created_time LIMIT 1
+		) o ON TRUE`, string(collaboratorEntity.RoleOwner), string(collaboratorEntity.PrincipalTypeUser)).
+		Joins("LEFT JOIN users u ON u.id = o.principal_id").
+		Where("s.deleted_time IS NULL")
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		query = query.Where("(s.id = ? OR s.name ILIKE ? OR u.email ILIKE ?)", filter.Query, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count spaces: %w", err)
+	}
+
+	query = query.Select(`s.id, s.name, s.created_by, s.created_time AS created_at,
+		o.principal_id AS owner_id, u.email AS owner_email,
+		(SELECT COUNT(*) FROM collaborators c WHERE c.resource_id = s.id) AS members,
+		(SELECT COUNT(*) FROM base b WHERE b.space_id = s.id AND b.deleted_time IS NULL) AS bases,
+		(SELECT COUNT(*) FROM table_meta t JOIN base b ON b.id = t.base_id
+			WHERE b.space_id = s.id AND b.deleted_time IS NULL AND t.deleted_time IS NULL) AS tables`).
+		Order("s.created_time DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var rows []spaceSummaryRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to search spaces: %w", err)
+	}
+	spaces := make([]*adminconsole.SpaceSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := &adminconsole.SpaceSummary{
+			ID:        row.ID,
+			Name:      row.Name,
+			CreatedBy: row.CreatedBy,
+			CreatedAt: row.CreatedAt,
+			Members:   row.Members,
+			Bases:     row.Bases,
+			Tables:    row.Tables,
+		}
+		if row.OwnerID != nil {
+			summary.OwnerID = *row.OwnerID
+		}
+		if row.OwnerEmail != nil {
+			summary.OwnerEmail = *row.OwnerEmail
+		}
+		spaces = append(spaces, summary)
+	}
+	return spaces, total, nil
+}
+
+// Stats 实例统计
+func (r *AdminDirectoryImpl) Stats(ctx context.Context, activeSince time.Time) (*adminconsole.SystemStats, error) {
+	db := r.db.WithContext(ctx)
+	stats := &adminconsole.SystemStats{ActiveSince: activeSince}
+
+	counts := []struct {
+		dest  *int64
+		query *gorm.DB
+	}{
+		{&stats.Users, db.Table("users").Where("deleted_time IS NULL")},
+		{&stats.ActiveUsers, db.Table("users").Where("deleted_time IS NULL AND last_sign_time >= ?", activeSince)},
+		{&stats.Admins, db.Table("users").Where("deleted_time IS NULL AND is_admin = ?", true)},
+		{&stats.Spaces, db.Table("space").Where("deleted_time IS NULL")},
+		{&stats.Bases, db.Table("base").Where("deleted_time IS NULL")},
+		{&stats.Tables, db.Table("table_meta").Where("deleted_time IS NULL")},
+		{&stats.Attachments, db.Table("attachments").Where("deleted_time IS NULL")},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.dest).Error; err != nil {
+			return nil, fmt.Errorf("failed to collect stats: %w", err)
+		}
+	}
+	if err := db.Table("attachments").Where("deleted_time IS NULL").
+		Select("COALESCE(SUM(size), 0)").Scan(&stats.AttachmentBytes).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum attachment size: %w", err)
+	}
+	return stats, nil
+}
+
+// SpaceOwners 工作空间当前的所有者
+func (r *AdminDirectoryImpl) SpaceOwners(ctx context.Context, spaceID string) ([]string, error) {
+	var owners []string
+	err := r.db.WithContext(ctx).Model(&CollaboratorModel{}).
+		Where("resource_id = ? AND role_name = ?", spaceID, string(collaboratorEntity.RoleOwner)).
+		Order("created_time").
+		Pluck("principal_id", &owners).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list space owners: %w", err)
+	}
+	return owners, nil
+}
+
+// TransferOwnership 将工作空间转让给新所有者，原所有者降为创建者
+func (r *AdminDirectoryImpl) TransferOwnership(ctx context.Context, spaceID, newOwnerID, actorID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Model(&CollaboratorModel{}).
+			Where("resource_id = ? AND role_name = ? AND principal_id <> ?", spaceID, string(collaboratorEntity.RoleOwner), newOwnerID).
+			Updates(map[string]interface{}{
+				"role_name":          string(collaboratorEntity.RoleCreator),
+				"last_modified_time": now,
+				"last_modified_by":   actorID,
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to demote previous owners: %w", err)
+		}
+
+		var existing CollaboratorModel
+		err = tx.Where("resource_id = ? AND principal_id = ?", spaceID, newOwnerID).Take(&existing).Error
+		switch {
+		case err == nil:
+			err = tx.Model(&CollaboratorModel{}).Where("id = ?", existing.ID).
+				Updates(map[string]interface{}{
+					"role_name":          string(collaboratorEntity.RoleOwner),
+					"last_modified_time": now,
+					"last_modified_by":   actorID,
+				}).Error
+		case err == gorm.ErrRecordNotFound:
+			collab, newErr := collaboratorEntity.NewCollaborator(spaceID, collaboratorEntity.ResourceTypeSpace,
+				newOwnerID, collaboratorEntity.PrincipalTypeUser, collaboratorEntity.RoleOwner, actorID)
+			if newErr != nil {
+				return newErr
+			}
+			err = tx.Create(&CollaboratorModel{
+				ID:               collab.ID(),
+				ResourceID:       spaceID,
+				ResourceType:     string(collaboratorEntity.ResourceTypeSpace),
+				PrincipalID:      newOwnerID,
+				PrincipalType:    string(collaboratorEntity.PrincipalTypeUser),
+				RoleName:         string(collaboratorEntity.RoleOwner),
+				CreatedBy:        actorID,
+				CreatedTime:      now,
+				LastModifiedTime: &now,
+			}).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to assign new owner: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// AIGenerationRepositoryImpl AI 字段生成任务与配额GORM实现
+type AIGenerationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAIGenerationRepository 创建 AI 字段生成仓储
+func NewAIGenerationRepository(db *gorm.DB) aifield.Repository {
+	return &AIGenerationRepositoryImpl{db: db}
+}
+
+// Enqueue 写入待执行任务，同一单元格已有待执行任务时跳过
+// 使用上下文中的事务连接：记录写入回滚时任务一并丢弃
+func (r *AIGenerationRepositoryImpl) Enqueue(ctx context.Context, jobs ...*aifield.Job) (int, error) {
+	db := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx)
+	created := 0
+	for _, job := range jobs {
+		var pending int64
+		if err := db.Model(&models.AIGenerationJob{}).
+			Where("field_id = ? AND record_id = ? AND status = ?", job.FieldID, job.RecordID, string(aifield.StatusPending)).
+			Count(&pending).Error; err != nil {
+			return created, fmt.Errorf("failed to check pending ai generation job: %w", err)
+		}
+		if pending > 0 {
+			continue
+		}
+		model := toAIGenerationJobModel(job)
+		if err := db.Create(&model).Error; err != nil {
+			return created, fmt.Errorf("failed to enqueue ai generation job: %w", err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// Claim 领取一批可执行任务（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *AIGenerationRepositoryImpl) Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*aifield.Job, error) {
+	var claimed []*aifield.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.AIGenerationJob{}).
+			Where("(status = ? AND next_run_at <= ?) OR (status = ? AND updated_time < ?)",
+				string(aifield.StatusPending), now,
+				string(aifield.StatusRunning), staleBefore).
+			Where(`NOT EXISTS (SELECT 1 FROM ai_generation_job other
+				WHERE other.field_id = ai_generation_job.field_id AND other.record_id = ai_generation_job.record_id
+				AND other.id <> ai_generation_job.id AND other.status = ? AND other.updated_time >= ?)`,
+				string(aifield.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.AIGenerationJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.AIGenerationJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(aifield.StatusRunning),
+				"attempts":     gorm.Expr("attempts + 1"),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(aifield.StatusRunning)
+			list[i].Attempts++
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromAIGenerationJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim ai generation jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+// Save 保存任务状态
+func (r *AIGenerationRepositoryImpl) Save(ctx context.Context, job *aifield.Job) error {
+	model := toAIGenerationJobModel(job)
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save ai generation job: %w", err)
+	}
+	return nil
+}
+
+// ListByField 列出字段最近的任务
+func (r *AIGenerationRepositoryImpl) ListByField(ctx context.Context, fieldID, recordID string, limit int) ([]*aifield.Job, error) {
+	query := r.db.WithContext(ctx).Where("field_id = ?", fieldID)
+	if recordID != "" {
+		query = query.Where("record_id = ?", recordID)
+	}
+	var list []models.AIGenerationJob
+	if err := query.Order("created_time DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list ai generation jobs: %w", err)
+	}
+	jobs := make([]*aifield.Job, 0, len(list))
+	for i := range list {
+		jobs = append(jobs, fromAIGenerationJobModel(&list[i]))
+	}
+	return jobs, nil
+}
+
+// DeleteFinishedBefore 删除过期的已结束任务
+func (r *AIGenerationRepositoryImpl) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND finished_time < ?", []string{string(aifield.StatusSucceeded), string(aifield.StatusFailed)}, before).
+		Delete(&models.AIGenerationJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete ai generation jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// MonthlyUsage 统计空间自 since 起调用服务商的次数与令牌数
+// 以 provider 非空判断：未调用服务商的清空任务不计入，生成后写回失败的任务仍计入
+func (r *AIGenerationRepositoryImpl) MonthlyUsage(ctx context.Context, spaceID string, since time.Time) (*aifield.Usage, error) {
+	var row struct {
+		Requests int64
+		Tokens   int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.AIGenerationJob{}).
+		Select("COUNT(*) AS requests, COALESCE(SUM(prompt_tokens + completion_tokens), 0) AS tokens").
+		Where("space_id = ? AND provider <> '' AND finished_time >= ?", spaceID, since).
+		Scan(&row).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum ai generation usage: %w", err)
+	}
+	return &aifield.Usage{PeriodStart: since, Requests: row.Requests, Tokens: row.Tokens}, nil
+}
+
+// GetQuota 获取空间配额
+func (r *AIGenerationRepositoryImpl) GetQuota(ctx context.Context, spaceID string) (*aifield.Quota, error) {
+	var model models.SpaceAIQuota
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ai quota: %w", err)
+	}
+	return &aifield.Quota{
+		SpaceID:             model.SpaceID,
+		MonthlyRequests:     model.MonthlyRequests,
+		MonthlyTokens:       model.MonthlyTokens,
+		MaxTokensPerRequest: model.MaxTokensPerRequest,
+		UpdatedBy:           model.UpdatedBy,
+		UpdatedTime:         model.UpdatedTime,
+	}, nil
+}
+
+// SaveQuota 保存空间配额
+func (r *AIGenerationRepositoryImpl) SaveQuota(ctx context.Context, quota *aifield.Quota) error {
+	model := models.SpaceAIQuota{
+		SpaceID:             quota.SpaceID,
+		MonthlyRequests:     quota.MonthlyRequests,
+		MonthlyTokens:       quota.MonthlyTokens,
+		MaxTokensPerRequest: quota.MaxTokensPerRequest,
+		UpdatedBy:           quota.UpdatedBy,
+		UpdatedTime:         quota.UpdatedTime,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save ai quota: %w", err)
+	}
+	return nil
+}
+
+func toAIGenerationJobModel(j *aifield.Job) models.AIGenerationJob {
+	model := models.AIGenerationJob{
+		ID:               j.ID,
+		SpaceID:          j.SpaceID,
+		TableID:          j.TableID,
+		FieldID:          j.FieldID,
+		RecordID:         j.RecordID,
+		Trigger:          string(j.Trigger),
+		Status:           string(j.Status),
+		Attempts:         j.Attempts,
+		Provider:         j.Provider,
+		Model:            j.Model,
+		PromptTokens:     j.PromptTokens,
+		CompletionTokens: j.CompletionTokens,
+		CreatedBy:        j.CreatedBy,
+		NextRunAt:        j.NextRunAt,
+		CreatedTime:      j.CreatedAt,
+		UpdatedTime:      j.UpdatedAt,
+		FinishedTime:     j.FinishedAt,
+	}
+	if j.Error != "" {
+		model.Error = &j.Error
+	}
+	return model
+}
+
+func fromAIGenerationJobModel(model *models.AIGenerationJob) *aifield.Job {
+	j := &aifield.Job{
+		ID:               model.ID,
+		SpaceID:          model.SpaceID,
+		TableID:          model.TableID,
+		FieldID:          model.FieldID,
+		RecordID:         model.RecordID,
+		Trigger:          aifield.Trigger(model.Trigger),
+		Status:           aifield.Status(model.Status),
+		Attempts:         model.Attempts,
+		Provider:         model.Provider,
+		Model:            model.Model,
+		PromptTokens:     model.PromptTokens,
+		CompletionTokens: model.CompletionTokens,
+		CreatedBy:        model.CreatedBy,
+		NextRunAt:        model.NextRunAt,
+		CreatedAt:        model.CreatedTime,
+		UpdatedAt:        model.UpdatedTime,
+		FinishedAt:       model.FinishedTime,
+	}
+	if model.Error != nil {
+		j.Error = *model.Error
+	}
+	return j
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// AttachmentBlobRepositoryImpl 内容寻址附件引用计数仓储GORM实现
+type AttachmentBlobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentBlobRepository 创建内容寻址附件仓储
+func NewAttachmentBlobRepository(db *gorm.DB) attachment.BlobRepository {
+	return &AttachmentBlobRepositoryImpl{db: db}
+}
+
+// Acquire 增加引用，记录不存在时创建
+// 回收任务锁定记录期间，这里的更新会等待其提交；记录被回收后重新创建，由调用方重新写入文件
+func (r *AttachmentBlobRepositoryImpl) Acquire(ctx context.Context, hash string, size int64, mimeType string) (bool, error) {
+	db := r.db.WithContext(ctx)
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		result := db.Model(&models.AttachmentBlob{}).
+			Where("hash = ?", hash).
+			UpdateColumns(map[string]interface{}{
+				"ref_count":          gorm.Expr("ref_count + 1"),
+				"unreferenced_since": nil,
+				"updated_time":       now,
+			})
+		if result.Error != nil {
+			return false, fmt.Errorf("failed to acquire attachment blob: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return false, nil
+		}
+
+		result = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.AttachmentBlob{
+			Hash:        hash,
+			Size:        size,
+			MimeType:    mimeType,
+			RefCount:    1,
+			CreatedTime: now,
+			UpdatedTime: now,
+		})
+		if result.Error != nil {
+			return false, fmt.Errorf("failed to create attachment blob: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+		// 并发创建，重新按已存在处理
+	}
+	return false, fmt.Errorf("failed to acquire attachment blob: concurrent update")
+}
+
+// Release 减少引用，降为 0 时记录时间
+func (r *AttachmentBlobRepositoryImpl) Release(ctx context.Context, hash string) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).
+		Model(&models.AttachmentBlob{}).
+		Where("hash = ? AND ref_count > 0", hash).
+		UpdateColumns(map[string]interface{}{
+			"ref_count":          gorm.Expr("ref_count - 1"),
+			"unreferenced_since": gorm.Expr("CASE WHEN ref_count <= 1 THEN ? ELSE unreferenced_since END", now),
+			"updated_time":       now,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to release attachment blob: %w", err)
+	}
+	return nil
+}
+
+// CollectUnreferenced 回收无引用的内容（PostgreSQL 使用 SKIP LOCKED，多实例并发回收互不阻塞）
+// 引用计数可能因中途失败而偏小，删除前按附件表重新统计，仍有引用时修正计数并跳过
+func (r *AttachmentBlobRepositoryImpl) CollectUnreferenced(ctx context.Context, before time.Time, limit int, remove func(blob *attachment.Blob) error) ([]*attachment.Blob, error) {
+	var collected []*attachment.Blob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("ref_count = 0 AND unreferenced_since < ?", before).
+			Order("unreferenced_since ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.AttachmentBlob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			model := &list[i]
+			var refs int64
+			if err := tx.Model(&models.Attachment{}).Where("content_hash = ?", model.Hash).Count(&refs).Error; err != nil {
+				return err
+			}
+			if refs > 0 {
+				if err := tx.Model(&models.AttachmentBlob{}).
+					Where("hash = ?", model.Hash).
+					UpdateColumns(map[string]interface{}{
+						"ref_count":          refs,
+						"unreferenced_since": nil,
+						"updated_time":       time.Now(),
+					}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			blob := toDomainBlob(model)
+			if err := remove(blob); err != nil {
+				continue // 保留记录，下次重试
+			}
+			if err := tx.Delete(&models.AttachmentBlob{}, "hash = ?", model.Hash).Error; err != nil {
+				return err
+			}
+			collected = append(collected, blob)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect attachment blobs: %w", err)
+	}
+	return collected, nil
+}
+
+func toDomainBlob(model *models.AttachmentBlob) *attachment.Blob {
+	return &attachment.Blob{
+		Hash:              model.Hash,
+		Size:              model.Size,
+		MimeType:          model.MimeType,
+		RefCount:          model.RefCount,
+		UnreferencedSince: model.UnreferencedSince,
+		CreatedTime:       model.CreatedTime,
+		UpdatedTime:       model.UpdatedTime,
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachmentscan"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// AttachmentPolicyRepositoryImpl 工作空间附件策略仓储GORM实现
+type AttachmentPolicyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAttachmentPolicyRepository 创建附件策略仓储
+func NewAttachmentPolicyRepository(db *gorm.DB) attachmentscan.Repository {
+	return &AttachmentPolicyRepositoryImpl{db: db}
+}
+
+// GetPolicy 获取空间附件策略
+func (r *AttachmentPolicyRepositoryImpl) GetPolicy(ctx context.Context, spaceID string) (*attachmentscan.Policy, error) {
+	var model models.SpaceAttachmentPolicy
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment policy: %w", err)
+	}
+
+	policy := &attachmentscan.Policy{
+		SpaceID:           model.SpaceID,
+		MaxFileSizeMB:     model.MaxFileSizeMB,
+		AllowedTypes:      model.AllowedTypes,
+		BlockedExtensions: model.BlockedExtensions,
+		ScanRequired:      model.ScanRequired,
+		WebhookURL:        model.WebhookURL,
+		WebhookSecret:     model.WebhookSecret,
+		UpdatedBy:         model.UpdatedBy,
+		UpdatedTime:       model.UpdatedTime,
+	}
+	if policy.AllowedTypes == nil {
+		policy.AllowedTypes = []string{}
+	}
+	if policy.BlockedExtensions == nil {
+		policy.BlockedExtensions = []string{}
+	}
+	return policy, nil
+}
+
+// SavePolicy 保存空间附件策略
+func (r *AttachmentPolicyRepositoryImpl) SavePolicy(ctx context.Context, policy *attachmentscan.Policy) error {
+	model := models.SpaceAttachmentPolicy{
+		SpaceID:           policy.SpaceID,
+		MaxFileSizeMB:     policy.MaxFileSizeMB,
+		AllowedTypes:      policy.AllowedTypes,
+		BlockedExtensions: policy.BlockedExtensions,
+		ScanRequired:      policy.ScanRequired,
+		WebhookURL:        policy.WebhookURL,
+		WebhookSecret:     policy.WebhookSecret,
+		UpdatedBy:         policy.UpdatedBy,
+		UpdatedTime:       policy.UpdatedTime,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save attachment policy: %w", err)
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
@@ -57,6 +58,10 @@ func (r *AttachmentRepositoryImpl) CreateAttachment(ctx context.Context, item *a
 		FieldID:        fieldID,
 		RecordID:       recordID,
 		CreatedBy:      createdBy,
+		ContentHash:    item.ContentHash,
+		ScanStatus:     string(item.ScanStatus),
+		ScanDetail:     item.ScanDetail,
+		ScannedTime:    item.ScannedTime,
 		CreatedTime:    item.CreatedTime,
 		UpdatedTime:    item.UpdatedTime,
 	}
@@ -266,6 +271,65 @@ func (r *AttachmentRepositoryImpl) GetAttachmentStats(ctx context.Context, table
 	return &stats, nil
 }
 
+// ClaimPendingScan 领取待扫描的附件（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *AttachmentRepositoryImpl) ClaimPendingScan(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*attachment.AttachmentItem, error) {
+	var claimed []*attachment.AttachmentItem
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Attachment{}).
+			Where("scan_status = ? AND deleted_time IS NULL", string(attachment.ScanStatusPending)).
+			Where("scan_lease_until IS NULL OR scan_lease_until < ?", now).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.Attachment
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.Attachment{}).
+			Where("id IN ?", ids).
+			UpdateColumn("scan_lease_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, r.toDomainEntity(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim pending attachments: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveScanResult 保存处理状态与类型，并释放租约
+func (r *AttachmentRepositoryImpl) SaveScanResult(ctx context.Context, item *attachment.AttachmentItem) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.Attachment{}).
+		Where("id = ?", item.ID).
+		UpdateColumns(map[string]interface{}{
+			"scan_status":      string(item.ScanStatus),
+			"scan_detail":      item.ScanDetail,
+			"scanned_time":     item.ScannedTime,
+			"mime_type":        item.MimeType,
+			"scan_lease_until": nil,
+			"updated_time":     item.UpdatedTime,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save attachment scan result: %w", err)
+	}
+	return nil
+}
+
 // toDomainEntity 转换为领域实体
 func (r *AttachmentRepositoryImpl) toDomainEntity(dbAttachment *models.Attachment) *attachment.AttachmentItem {
 	return &attachment.AttachmentItem{
@@ -280,6 +344,11 @@ func (r *AttachmentRepositoryImpl) toDomainEntity(dbAttachment *models.Attachmen
 		Height:         dbAttachment.Height,
 		SmallThumbnail: dbAttachment.SmallThumbnail,
 		LargeThumbnail: dbAttachment.LargeThumbnail,
+		TableID:        dbAttachment.TableID,
+		ContentHash:    dbAttachment.ContentHash,
+		ScanStatus:     attachment.ScanStatus(dbAttachment.ScanStatus),
+		ScanDetail:     dbAttachment.ScanDetail,
+		ScannedTime:    dbAttachment.ScannedTime,
 		CreatedTime:    dbAttachment.CreatedTime,
 		UpdatedTime:    dbAttachment.UpdatedTime,
 	}
@@ -366,4 +435,3 @@ func (r *UploadTokenRepositoryImpl) CleanupExpiredTokens(ctx context.Context) er
 		Where("expires_at < ?", time.Now()).
 		Delete(&models.UploadToken{}).Error
 }
-
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/backlink"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// BacklinkRepositoryImpl 反向链接仓储GORM实现
+type BacklinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBacklinkRepository 创建反向链接仓储
+func NewBacklinkRepository(db *gorm.DB) backlink.Repository {
+	return &BacklinkRepositoryImpl{db: db}
+}
+
+// ReplaceForSource 替换来源记录的全部反向链接（使用上下文中的事务连接）
+func (r *BacklinkRepositoryImpl) ReplaceForSource(ctx context.Context, sourceTableID, sourceRecordID string, links []*backlink.Backlink) error {
+	db := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx)
+	if err := db.Where("source_table_id = ? AND source_record_id = ?", sourceTableID, sourceRecordID).
+		Delete(&models.RecordBacklink{}).Error; err != nil {
+		return fmt.Errorf("failed to delete backlinks: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	rows := make([]models.RecordBacklink, 0, len(links))
+	for _, link := range links {
+		rows = append(rows, models.RecordBacklink{
+			SourceTableID:  link.SourceTableID,
+			SourceRecordID: link.SourceRecordID,
+			SourceFieldID:  link.SourceFieldID,
+			TargetTableID:  link.TargetTableID,
+			TargetRecordID: link.TargetRecordID,
+			UpdatedTime:    link.UpdatedAt,
+		})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 500).Error; err != nil {
+		return fmt.Errorf("failed to save backlinks: %w", err)
+	}
+	return nil
+}
+
+// DeleteForSource 删除来源记录的全部反向链接
+func (r *BacklinkRepositoryImpl) DeleteForSource(ctx context.Context, sourceTableID, sourceRecordID string) error {
+	if err := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).
+		Where("source_table_id = ? AND source_record_id = ?", sourceTableID, sourceRecordID).
+		Delete(&models.RecordBacklink{}).Error; err != nil {
+		return fmt.Errorf("failed to delete backlinks: %w", err)
+	}
+	return nil
+}
+
+// ListByTarget 列出提及目标记录的位置
+func (r *BacklinkRepositoryImpl) ListByTarget(ctx context.Context, targetTableID, targetRecordID string, limit int) ([]*backlink.Backlink, error) {
+	var rows []models.RecordBacklink
+	if err := r.db.WithContext(ctx).
+		Where("target_table_id = ? AND target_record_id = ?", targetTableID, targetRecordID).
+		Order("updated_time DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list backlinks: %w", err)
+	}
+	links := make([]*backlink.Backlink, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, &backlink.Backlink{
+			SourceTableID:  row.SourceTableID,
+			SourceRecordID: row.SourceRecordID,
+			SourceFieldID:  row.SourceFieldID,
+			TargetTableID:  row.TargetTableID,
+			TargetRecordID: row.TargetRecordID,
+			UpdatedAt:      row.UpdatedTime,
+		})
+	}
+	return links, nil
+}
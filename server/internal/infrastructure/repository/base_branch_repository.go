@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/basebranch"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// BaseBranchRepositoryImpl 沙盒分支仓储GORM实现
+type BaseBranchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBaseBranchRepository 创建沙盒分支仓储
+func NewBaseBranchRepository(db *gorm.DB) basebranch.Repository {
+	return &BaseBranchRepositoryImpl{db: db}
+}
+
+// Create 保存新分支
+func (r *BaseBranchRepositoryImpl) Create(ctx context.Context, branch *basebranch.Branch) error {
+	model := toBaseBranchModel(branch)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create base branch: %w", err)
+	}
+	return nil
+}
+
+// Update 更新分支
+func (r *BaseBranchRepositoryImpl) Update(ctx context.Context, branch *basebranch.Branch) error {
+	if err := r.db.WithContext(ctx).Save(toBaseBranchModel(branch)).Error; err != nil {
+		return fmt.Errorf("failed to update base branch: %w", err)
+	}
+	return nil
+}
+
+// GetByID 按ID查找分支
+func (r *BaseBranchRepositoryImpl) GetByID(ctx context.Context, id string) (*basebranch.Branch, error) {
+	var model models.BaseBranch
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base branch: %w", err)
+	}
+	return toBaseBranchEntity(&model), nil
+}
+
+// ListBySource 列出生产 Base 的分支
+func (r *BaseBranchRepositoryImpl) ListBySource(ctx context.Context, sourceBaseID string) ([]*basebranch.Branch, error) {
+	var list []models.BaseBranch
+	err := r.db.WithContext(ctx).
+		Where("source_base_id = ?", sourceBaseID).
+		Order("created_at DESC").
+		Find(&list).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list base branches: %w", err)
+	}
+	branches := make([]*basebranch.Branch, 0, len(list))
+	for i := range list {
+		branches = append(branches, toBaseBranchEntity(&list[i]))
+	}
+	return branches, nil
+}
+
+func toBaseBranchModel(branch *basebranch.Branch) *models.BaseBranch {
+	return &models.BaseBranch{
+		ID:              branch.ID,
+		Name:            branch.Name,
+		SourceBaseID:    branch.SourceBaseID,
+		SandboxBaseID:   branch.SandboxBaseID,
+		Status:          string(branch.Status),
+		BaseSpec:        branch.BaseSpec,
+		LastOperationID: branch.LastOperationID,
+		PromotedAt:      branch.PromotedAt,
+		CreatedBy:       branch.CreatedBy,
+		CreatedAt:       branch.CreatedAt,
+		UpdatedAt:       branch.UpdatedAt,
+	}
+}
+
+func toBaseBranchEntity(model *models.BaseBranch) *basebranch.Branch {
+	return &basebranch.Branch{
+		ID:              model.ID,
+		Name:            model.Name,
+		SourceBaseID:    model.SourceBaseID,
+		SandboxBaseID:   model.SandboxBaseID,
+		Status:          basebranch.Status(model.Status),
+		BaseSpec:        model.BaseSpec,
+		LastOperationID: model.LastOperationID,
+		PromotedAt:      model.PromotedAt,
+		CreatedBy:       model.CreatedBy,
+		CreatedAt:       model.CreatedAt,
+		UpdatedAt:       model.UpdatedAt,
+	}
+}
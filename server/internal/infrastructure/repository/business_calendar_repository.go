@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// BusinessCalendarRepositoryImpl 工作日历仓储GORM实现
+type BusinessCalendarRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBusinessCalendarRepository 创建工作日历仓储
+func NewBusinessCalendarRepository(db *gorm.DB) businesscalendar.Repository {
+	return &BusinessCalendarRepositoryImpl{db: db}
+}
+
+// Get 获取工作空间的工作日历
+func (r *BusinessCalendarRepositoryImpl) Get(ctx context.Context, spaceID string) (*businesscalendar.Calendar, error) {
+	var model models.SpaceBusinessCalendar
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business calendar: %w", err)
+	}
+
+	calendar := &businesscalendar.Calendar{
+		SpaceID:   model.SpaceID,
+		Weekends:  model.Weekends,
+		Holidays:  model.Holidays,
+		Workdays:  model.Workdays,
+		UpdatedBy: model.UpdatedBy,
+		UpdatedAt: model.UpdatedTime,
+	}
+	if calendar.Weekends == nil {
+		calendar.Weekends = []int{}
+	}
+	if calendar.Holidays == nil {
+		calendar.Holidays = []string{}
+	}
+	if calendar.Workdays == nil {
+		calendar.Workdays = []string{}
+	}
+	return calendar, nil
+}
+
+// Save 保存工作空间的工作日历
+func (r *BusinessCalendarRepositoryImpl) Save(ctx context.Context, calendar *businesscalendar.Calendar) error {
+	model := models.SpaceBusinessCalendar{
+		SpaceID:     calendar.SpaceID,
+		Weekends:    calendar.Weekends,
+		Holidays:    calendar.Holidays,
+		Workdays:    calendar.Workdays,
+		UpdatedBy:   calendar.UpdatedBy,
+		UpdatedTime: calendar.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save business calendar: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除工作空间的工作日历
+func (r *BusinessCalendarRepositoryImpl) Delete(ctx context.Context, spaceID string) error {
+	if err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Delete(&models.SpaceBusinessCalendar{}).Error; err != nil {
+		return fmt.Errorf("failed to delete business calendar: %w", err)
+	}
+	return nil
+}
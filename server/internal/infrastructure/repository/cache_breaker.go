@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+)
+
+var cacheBreakerLog = logger.Named("repository.cache_breaker")
+
+// CircuitState 熔断器状态
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitHalfOpen CircuitState = "half_open"
+	CircuitOpen     CircuitState = "open"
+)
+
+// metricValue 状态在 luckdb_cache_circuit_state 中的取值
+func (s CircuitState) metricValue() float64 {
+	switch s {
+	case CircuitHalfOpen:
+		return 1
+	case CircuitOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// ErrCacheCircuitOpen 缓存熔断中，读取被跳过（调用方按未命中处理）
+var ErrCacheCircuitOpen = errors.New("cache circuit open")
+
+// CacheCircuitSnapshot 熔断器状态快照（健康检查展示）
+type CacheCircuitSnapshot struct {
+	State                CircuitState `json:"state"`
+	ConsecutiveFailures  int          `json:"consecutive_failures"`
+	OpenedAt             *time.Time   `json:"opened_at,omitempty"`
+	Trips                int64        `json:"trips"`
+	ShortCircuits        int64        `json:"short_circuits"`
+	PendingInvalidations int          `json:"pending_invalidations"`
+	DroppedInvalidations int64        `json:"dropped_invalidations"`
+}
+
+// CircuitBreakerCache 带熔断的缓存包装器 ✨
+// 缓存后端连续失败（未命中不算失败）达到阈值后熔断：冷却期内读取直接返回 ErrCacheCircuitOpen、写入直接跳过，
+// 删除暂存下来；冷却结束后放行一次探测，成功则恢复并补发暂存的删除，避免恢复后读到过期数据
+type CircuitBreakerCache struct {
+	inner CacheProvider
+	cfg   config.CacheCircuitBreakerConfig
+	now   func() time.Time
+
+	mu              sync.Mutex
+	state           CircuitState
+	failures        int
+	openedAt        time.Time
+	probing         bool
+	trips           int64
+	shortCircuits   int64
+	dropped         int64
+	pendingKeys     map[string]struct{}
+	pendingPatterns map[string]struct{}
+}
+
+// NewCircuitBreakerCache 创建带熔断的缓存包装器
+func NewCircuitBreakerCache(inner CacheProvider, cfg config.CacheCircuitBreakerConfig) *CircuitBreakerCache {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = 30 * time.Second
+	}
+	if cfg.MaxPendingInvalidations <= 0 {
+		cfg.MaxPendingInvalidations = 10000
+	}
+	metrics.CacheCircuitState.WithLabelValues().Set(CircuitClosed.metricValue())
+	return &CircuitBreakerCache{
+		inner:           inner,
+		cfg:             cfg,
+		now:             time.Now,
+		state:           CircuitClosed,
+		pendingKeys:     make(map[string]struct{}),
+		pendingPatterns: make(map[string]struct{}),
+	}
+}
+
+// Get 读取缓存
+func (c *CircuitBreakerCache) Get(ctx context.Context, key string, dest interface{}) error {
+	skipped, err := c.call(ctx, "get", func() error { return c.inner.Get(ctx, key, dest) })
+	if skipped {
+		return ErrCacheCircuitOpen
+	}
+	return err
+}
+
+// Set 写入缓存（熔断期间跳过）
+func (c *CircuitBreakerCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	_, err := c.call(ctx, "set", func() error { return c.inner.Set(ctx, key, value, ttl) })
+	return err
+}
+
+// Delete 删除缓存（熔断期间暂存，恢复后补发）
+func (c *CircuitBreakerCache) Delete(ctx context.Context, keys ...string) error {
+	skipped, err := c.call(ctx, "delete", func() error { return c.inner.Delete(ctx, keys...) })
+	if skipped {
+		c.queueInvalidations(keys, nil)
+	}
+	return err
+}
+
+// InvalidatePattern 按模式删除缓存（熔断期间暂存，恢复后补发）
+func (c *CircuitBreakerCache) InvalidatePattern(ctx context.Context, pattern string) error {
+	skipped, err := c.call(ctx, "invalidate", func() error { return c.inner.InvalidatePattern(ctx, pattern) })
+	if skipped {
+		c.queueInvalidations(nil, []string{pattern})
+	}
+	return err
+}
+
+// call 按熔断状态执行操作，skipped 表示操作被跳过
+// 探测时先补发暂存的删除，避免恢复后第一次读取就读到熔断期间未能删除的过期数据
+func (c *CircuitBreakerCache) call(ctx context.Context, operation string, fn func() error) (skipped bool, err error) {
+	probe, ok := c.acquire(operation)
+	if !ok {
+		return true, nil
+	}
+	if probe && !c.flushPending(ctx) {
+		return true, nil
+	}
+	err = fn()
+	c.done(probe, err)
+	return false, err
+}
+
+// Snapshot 当前状态
+func (c *CircuitBreakerCache) Snapshot() CacheCircuitSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := CacheCircuitSnapshot{
+		State:                c.state,
+		ConsecutiveFailures:  c.failures,
+		Trips:                c.trips,
+		ShortCircuits:        c.shortCircuits,
+		PendingInvalidations: len(c.pendingKeys) + len(c.pendingPatterns),
+		DroppedInvalidations: c.dropped,
+	}
+	if c.state != CircuitClosed {
+		openedAt := c.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
+
+// acquire 判断操作是否放行；probe 表示本次为冷却结束后的探测
+func (c *CircuitBreakerCache) acquire(operation string) (probe, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch c.state {
+	case CircuitClosed:
+		return false, true
+	case CircuitOpen:
+		if c.now().Sub(c.openedAt) >= c.cfg.CoolDown {
+			c.setState(CircuitHalfOpen)
+			c.probing = true
+			return true, true
+		}
+	}
+	// 熔断中，或半开状态下已有探测在进行
+	c.shortCircuits++
+	metrics.CacheCircuitShortCircuitsTotal.WithLabelValues(operation).Inc()
+	return false, false
+}
+
+// done 记录操作结果
+func (c *CircuitBreakerCache) done(probe bool, err error) {
+	failed := isCacheBackendFailure(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if probe {
+		c.probing = false
+		if failed {
+			c.trip()
+			return
+		}
+		c.failures = 0
+		c.setState(CircuitClosed)
+		return
+	}
+	if failed {
+		c.failures++
+		if c.state == CircuitClosed && c.failures >= c.cfg.FailureThreshold {
+			c.trip()
+		}
+	} else if err == nil || errors.Is(err, cache.ErrCacheNotFound) {
+		c.failures = 0
+	}
+}
+
+// trip 熔断（调用方持有锁）
+func (c *CircuitBreakerCache) trip() {
+	c.trips++
+	c.openedAt = c.now()
+	c.setState(CircuitOpen)
+	cacheBreakerLog.Warn(context.Background(), "缓存连续失败，已熔断",
+		logger.Int("failures", c.failures),
+		logger.Duration("cool_down", c.cfg.CoolDown))
+}
+
+// setState 切换状态并更新指标（调用方持有锁）
+func (c *CircuitBreakerCache) setState(state CircuitState) {
+	if c.state == state {
+		return
+	}
+	c.state = state
+	metrics.CacheCircuitState.WithLabelValues().Set(state.metricValue())
+	metrics.CacheCircuitTransitionsTotal.WithLabelValues(string(state)).Inc()
+}
+
+// queueInvalidations 暂存熔断期间的删除，超过上限时丢弃并计数
+func (c *CircuitBreakerCache) queueInvalidations(keys, patterns []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if len(c.pendingKeys)+len(c.pendingPatterns) >= c.cfg.MaxPendingInvalidations {
+			c.dropped++
+			continue
+		}
+		c.pendingKeys[key] = struct{}{}
+	}
+	for _, pattern := range patterns {
+		if len(c.pendingKeys)+len(c.pendingPatterns) >= c.cfg.MaxPendingInvalidations {
+			c.dropped++
+			continue
+		}
+		c.pendingPatterns[pattern] = struct{}{}
+	}
+}
+
+// takePending 取出暂存的删除（调用方持有锁）
+func (c *CircuitBreakerCache) takePending() ([]string, []string) {
+	keys := make([]string, 0, len(c.pendingKeys))
+	for key := range c.pendingKeys {
+		keys = append(keys, key)
+	}
+	patterns := make([]string, 0, len(c.pendingPatterns))
+	for pattern := range c.pendingPatterns {
+		patterns = append(patterns, pattern)
+	}
+	c.pendingKeys = make(map[string]struct{})
+	c.pendingPatterns = make(map[string]struct{})
+	return keys, patterns
+}
+
+// flushPending 探测时补发暂存的删除；失败时重新暂存并再次熔断
+func (c *CircuitBreakerCache) flushPending(ctx context.Context) bool {
+	c.mu.Lock()
+	keys, patterns := c.takePending()
+	c.mu.Unlock()
+	if len(keys) == 0 && len(patterns) == 0 {
+		return true
+	}
+
+	if len(keys) > 0 {
+		if err := c.inner.Delete(ctx, keys...); err != nil {
+			c.reopen(keys, patterns)
+			return false
+		}
+	}
+	for i, pattern := range patterns {
+		if err := c.inner.InvalidatePattern(ctx, pattern); err != nil {
+			c.reopen(nil, patterns[i:])
+			return false
+		}
+	}
+	cacheBreakerLog.Info(ctx, "缓存已恢复，补发熔断期间的删除",
+		logger.Int("keys", len(keys)),
+		logger.Int("patterns", len(patterns)))
+	return true
+}
+
+// reopen 探测失败：重新暂存未完成的删除并熔断
+func (c *CircuitBreakerCache) reopen(keys, patterns []string) {
+	c.queueInvalidations(keys, patterns)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probing = false
+	c.trip()
+}
+
+// isCacheBackendFailure 是否为缓存后端故障（未命中与调用方取消不算）
+func isCacheBackendFailure(err error) bool {
+	return err != nil && !errors.Is(err, cache.ErrCacheNotFound) && !errors.Is(err, context.Canceled)
+}
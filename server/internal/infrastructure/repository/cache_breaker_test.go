@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository/repotest"
+)
+
+var errRedisDown = errors.New("dial tcp: connection refused")
+
+// switchableCache 可切换为不可用的缓存，记录实际到达后端的调用次数
+type switchableCache struct {
+	*repotest.Cache
+	down  bool
+	calls int
+}
+
+func (c *switchableCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.calls++
+	if c.down {
+		return errRedisDown
+	}
+	return c.Cache.Get(ctx, key, dest)
+}
+
+func (c *switchableCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.calls++
+	if c.down {
+		return errRedisDown
+	}
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func (c *switchableCache) Delete(ctx context.Context, keys ...string) error {
+	c.calls++
+	if c.down {
+		return errRedisDown
+	}
+	return c.Cache.Delete(ctx, keys...)
+}
+
+func (c *switchableCache) InvalidatePattern(ctx context.Context, pattern string) error {
+	c.calls++
+	if c.down {
+		return errRedisDown
+	}
+	return c.Cache.InvalidatePattern(ctx, pattern)
+}
+
+func newTestBreaker() (*CircuitBreakerCache, *switchableCache, *time.Time) {
+	backend := &switchableCache{Cache: repotest.NewCache()}
+	breaker := NewCircuitBreakerCache(backend, config.CacheCircuitBreakerConfig{FailureThreshold: 3, CoolDown: time.Minute})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+	return breaker, backend, &now
+}
+
+func TestCircuitBreakerTripsAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	breaker, backend, _ := newTestBreaker()
+	var value string
+
+	// 未命中不算失败
+	for i := 0; i < 5; i++ {
+		if err := breaker.Get(ctx, "missing", &value); !errors.Is(err, cache.ErrCacheNotFound) {
+			t.Fatalf("未命中应原样返回，得到 %v", err)
+		}
+	}
+	if state := breaker.Snapshot().State; state != CircuitClosed {
+		t.Fatalf("未命中不应熔断，得到 %s", state)
+	}
+
+	backend.down = true
+	for i := 0; i < 3; i++ {
+		_ = breaker.Get(ctx, "k", &value)
+	}
+	snapshot := breaker.Snapshot()
+	if snapshot.State != CircuitOpen || snapshot.Trips != 1 || snapshot.OpenedAt == nil {
+		t.Fatalf("连续 3 次失败后应熔断，得到 %+v", snapshot)
+	}
+
+	calls := backend.calls
+	if err := breaker.Get(ctx, "k", &value); !errors.Is(err, ErrCacheCircuitOpen) {
+		t.Errorf("熔断期间读取应返回 ErrCacheCircuitOpen，得到 %v", err)
+	}
+	if err := breaker.Set(ctx, "k", "v", 0); err != nil {
+		t.Errorf("熔断期间写入应直接跳过，得到 %v", err)
+	}
+	if backend.calls != calls {
+		t.Errorf("熔断期间不应访问后端，多了 %d 次调用", backend.calls-calls)
+	}
+	if got := breaker.Snapshot().ShortCircuits; got != 2 {
+		t.Errorf("应记录 2 次跳过，得到 %d", got)
+	}
+}
+
+func TestCircuitBreakerRecoversAndReplaysInvalidations(t *testing.T) {
+	ctx := context.Background()
+	breaker, backend, now := newTestBreaker()
+	var value string
+	if err := backend.Cache.Set(ctx, "field:id:1", "stale", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.Cache.Set(ctx, "field:table:1", "stale", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	backend.down = true
+	for i := 0; i < 3; i++ {
+		_ = breaker.Get(ctx, "k", &value)
+	}
+	if err := breaker.Delete(ctx, "field:id:1"); err != nil {
+		t.Errorf("熔断期间删除应暂存并返回 nil，得到 %v", err)
+	}
+	if err := breaker.InvalidatePattern(ctx, "field:table:*"); err != nil {
+		t.Errorf("熔断期间按模式删除应暂存并返回 nil，得到 %v", err)
+	}
+	if pending := breaker.Snapshot().PendingInvalidations; pending != 2 {
+		t.Fatalf("应暂存 2 个删除，得到 %d", pending)
+	}
+
+	// 冷却结束后探测失败：重新熔断，暂存保留
+	*now = now.Add(time.Minute)
+	if err := breaker.Get(ctx, "field:id:1", &value); !errors.Is(err, ErrCacheCircuitOpen) {
+		t.Errorf("探测失败时应按熔断处理，得到 %v", err)
+	}
+	if snapshot := breaker.Snapshot(); snapshot.State != CircuitOpen || snapshot.Trips != 2 || snapshot.PendingInvalidations != 2 {
+		t.Fatalf("探测失败后应重新熔断并保留暂存，得到 %+v", snapshot)
+	}
+
+	// 后端恢复：探测先补发删除，读取不会拿到过期数据
+	backend.down = false
+	*now = now.Add(time.Minute)
+	if err := breaker.Get(ctx, "field:id:1", &value); !errors.Is(err, cache.ErrCacheNotFound) {
+		t.Errorf("恢复后应读不到熔断期间删除的键，得到 %q, %v", value, err)
+	}
+	if err := backend.Cache.Get(ctx, "field:table:1", &value); err == nil {
+		t.Error("恢复后应补发按模式删除")
+	}
+	if snapshot := breaker.Snapshot(); snapshot.State != CircuitClosed || snapshot.PendingInvalidations != 0 || snapshot.OpenedAt != nil {
+		t.Errorf("恢复后应关闭熔断并清空暂存，得到 %+v", snapshot)
+	}
+}
+
+func TestCircuitBreakerDropsInvalidationsOverLimit(t *testing.T) {
+	backend := &switchableCache{Cache: repotest.NewCache(), down: true}
+	breaker := NewCircuitBreakerCache(backend, config.CacheCircuitBreakerConfig{FailureThreshold: 1, MaxPendingInvalidations: 2})
+	ctx := context.Background()
+
+	// 第一次删除到达后端并失败（未熔断前不暂存），随后熔断
+	if err := breaker.Delete(ctx, "a"); !errors.Is(err, errRedisDown) {
+		t.Fatalf("熔断前的删除应返回后端错误，得到 %v", err)
+	}
+	_ = breaker.Delete(ctx, "b", "c", "d")
+	snapshot := breaker.Snapshot()
+	if snapshot.PendingInvalidations != 2 || snapshot.DroppedInvalidations != 1 {
+		t.Errorf("超过上限的删除应丢弃并计数，得到 %+v", snapshot)
+	}
+}
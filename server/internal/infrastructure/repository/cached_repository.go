@@ -15,6 +15,13 @@ import (
 	recordValueobject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 	"github.com/easyspace-ai/luckdb/server/pkg/database"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+// 模块日志器：级别可通过 logger.modules 单独调整（如 repository.field: debug），Debug 日志按配置采样
+var (
+	fieldRepoLog  = logger.Named("repository.field")
+	recordRepoLog = logger.Named("repository.record")
 )
 
 // CachedFieldRepository 带缓存的字段仓储包装器
@@ -51,7 +58,7 @@ func (r *CachedFieldRepository) buildCacheKey(prefix, id string) string {
 func (r *CachedFieldRepository) FindByID(ctx context.Context, id fieldValueobject.FieldID) (*fieldEntity.Field, error) {
 	cacheKey := r.buildCacheKey("id", id.String())
 
-	logger.Info("🔍 CachedFieldRepository.FindByID 开始查找",
+	fieldRepoLog.Debug(ctx, "FindByID 开始查找",
 		logger.String("field_id", id.String()),
 		logger.String("cache_key", cacheKey))
 
@@ -64,23 +71,23 @@ func (r *CachedFieldRepository) FindByID(ctx context.Context, id fieldValueobjec
 	var field *fieldEntity.Field
 	if err := r.cacheService.Get(ctx, cacheKey, &field); err == nil {
 		if field != nil {
-			logger.Info("✅ CachedFieldRepository.FindByID 缓存命中",
+			fieldRepoLog.Debug(ctx, "FindByID 缓存命中",
 				logger.String("field_id", id.String()),
 				logger.String("field_name", field.Name().String()))
 			return field, nil
 		}
 		// 如果缓存中存储的是 nil，继续查询数据库（可能是缓存过期或被清除）
-		logger.Warn("⚠️ CachedFieldRepository.FindByID 缓存中字段为nil，继续查询数据库",
+		fieldRepoLog.Debug(ctx, "FindByID 缓存中字段为nil，继续查询数据库",
 			logger.String("field_id", id.String()))
 	}
 
-	logger.Info("🔍 CachedFieldRepository.FindByID 缓存未命中，查询数据库",
+	fieldRepoLog.Debug(ctx, "FindByID 缓存未命中，查询数据库",
 		logger.String("field_id", id.String()))
 
 	// 缓存未命中，查询数据库（直接查询底层仓库，绕过缓存层）
 	// ❌ 关键修复：直接使用底层仓库查询，避免缓存层的问题
 	// 因为缓存可能已经被清除，或者缓存值不准确
-	logger.Info("🔍 CachedFieldRepository.FindByID 直接查询底层仓库",
+	fieldRepoLog.Debug(ctx, "FindByID 直接查询底层仓库",
 		logger.String("field_id", id.String()))
 	
 	// 获取底层仓库（非缓存包装的仓库）
@@ -90,14 +97,14 @@ func (r *CachedFieldRepository) FindByID(ctx context.Context, id fieldValueobjec
 	
 	field, err := baseRepo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error("❌ CachedFieldRepository.FindByID 数据库查询失败",
+		fieldRepoLog.Error(ctx, "FindByID 数据库查询失败",
 			logger.String("field_id", id.String()),
 			logger.ErrorField(err))
 		return nil, err
 	}
 
 	if field == nil {
-		logger.Warn("⚠️ CachedFieldRepository.FindByID 数据库查询结果为空",
+		fieldRepoLog.Debug(ctx, "FindByID 数据库查询结果为空",
 			logger.String("field_id", id.String()))
 		// ❌ 关键修复：不要将 nil 写入缓存，避免缓存污染
 		// 如果字段不存在，返回 nil，但不写入缓存
@@ -105,7 +112,7 @@ func (r *CachedFieldRepository) FindByID(ctx context.Context, id fieldValueobjec
 		return nil, nil
 	}
 
-	logger.Info("✅ CachedFieldRepository.FindByID 数据库查询成功",
+	fieldRepoLog.Debug(ctx, "FindByID 数据库查询成功",
 		logger.String("field_id", id.String()),
 		logger.String("field_name", field.Name().String()),
 		logger.String("table_id", field.TableID()))
@@ -114,11 +121,11 @@ func (r *CachedFieldRepository) FindByID(ctx context.Context, id fieldValueobjec
 	// ❌ 关键修复：确保写入缓存时字段不为 nil
 	if field != nil {
 		if err := r.cacheService.Set(ctx, cacheKey, field, r.ttl); err != nil {
-			logger.Warn("failed to cache field",
+			fieldRepoLog.Warn(ctx, "failed to cache field",
 				logger.String("field_id", id.String()),
 				logger.ErrorField(err))
 		} else {
-			logger.Info("✅ CachedFieldRepository.FindByID 缓存写入成功",
+			fieldRepoLog.Debug(ctx, "FindByID 缓存写入成功",
 				logger.String("field_id", id.String()),
 				logger.String("cache_key", cacheKey))
 		}
@@ -132,7 +139,7 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 	// ✅ 关键修复：在事务中禁用缓存，直接查询数据库
 	// 原因：事务中的查询可能受到隔离级别影响，缓存可能导致数据不一致
 	if database.InTransaction(ctx) {
-		logger.Info("🔍 CachedFieldRepository.FindByTableID 在事务中，禁用缓存，直接查询数据库",
+		fieldRepoLog.Debug(ctx, "FindByTableID 在事务中，禁用缓存，直接查询数据库",
 			logger.String("table_id", tableID))
 		return r.repo.FindByTableID(ctx, tableID)
 	}
@@ -140,7 +147,7 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 	cacheKey := r.buildCacheKey("table", tableID)
 
 	// ✅ 添加详细日志：缓存查询
-	logger.Info("🔍 CachedFieldRepository.FindByTableID 开始查询",
+	fieldRepoLog.Debug(ctx, "FindByTableID 开始查询",
 		logger.String("table_id", tableID),
 		logger.String("cache_key", cacheKey))
 
@@ -152,27 +159,26 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 		// 或者空数组表示"暂时没有字段"，但不应该从缓存读取这个状态
 		// 注意：这个检查只在第一次修复时有用，之后应该不会缓存空数组
 		if len(fields) == 0 {
-			logger.Warn("⚠️ CachedFieldRepository.FindByTableID 缓存命中但为空数组，清除缓存并查询数据库",
+			fieldRepoLog.Warn(ctx, "FindByTableID 缓存命中但为空数组，清除缓存并查询数据库",
 				logger.String("table_id", tableID),
 				logger.String("cache_key", cacheKey))
 			// ✅ 关键修复：异步清除空数组缓存，不阻塞主流程
 			// 如果 context 被取消或操作失败，不影响数据库查询
-			go func() {
-				// 使用新的 context，避免使用可能已取消的请求 context
-				bgCtx := context.Background()
-				if err := r.cacheService.Delete(bgCtx, cacheKey); err != nil {
-					logger.Warn("failed to delete empty cache (async)",
+			// 不随请求取消，保留请求的链路
+			tracing.Go(ctx, "cache.delete_empty", func(ctx context.Context) {
+				if err := r.cacheService.Delete(ctx, cacheKey); err != nil {
+					fieldRepoLog.Warn(ctx, "failed to delete empty cache (async)",
 						logger.String("cache_key", cacheKey),
 						logger.ErrorField(err))
 				} else {
-					logger.Info("✅ CachedFieldRepository.FindByTableID 空数组缓存清除成功（异步）",
+					fieldRepoLog.Debug(ctx, "FindByTableID 空数组缓存清除成功（异步）",
 						logger.String("cache_key", cacheKey))
 				}
-			}()
+			})
 			// 继续查询数据库（不等待缓存清除完成）
 		} else {
 			// ✅ 正常情况：缓存命中且有数据，直接返回（走缓存）
-			logger.Info("✅ CachedFieldRepository.FindByTableID 缓存命中",
+			fieldRepoLog.Debug(ctx, "FindByTableID 缓存命中",
 				logger.String("table_id", tableID),
 				logger.Int("cached_count", len(fields)),
 				logger.String("cache_key", cacheKey))
@@ -180,7 +186,7 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 		}
 	}
 
-	logger.Info("🔍 CachedFieldRepository.FindByTableID 缓存未命中，查询数据库",
+	fieldRepoLog.Debug(ctx, "FindByTableID 缓存未命中，查询数据库",
 		logger.String("table_id", tableID))
 
 	// 缓存未命中，查询数据库
@@ -189,7 +195,7 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 		return nil, err
 	}
 
-	logger.Info("🔍 CachedFieldRepository.FindByTableID 数据库查询完成",
+	fieldRepoLog.Debug(ctx, "FindByTableID 数据库查询完成",
 		logger.String("table_id", tableID),
 		logger.Int("found_count", len(fields)))
 
@@ -199,17 +205,17 @@ func (r *CachedFieldRepository) FindByTableID(ctx context.Context, tableID strin
 	// 下次查询时应该再次查询数据库，而不是从缓存读取空数组
 	if len(fields) > 0 {
 		if err := r.cacheService.Set(ctx, cacheKey, fields, r.ttl); err != nil {
-			logger.Warn("failed to cache fields",
+			fieldRepoLog.Warn(ctx, "failed to cache fields",
 				logger.String("table_id", tableID),
 				logger.ErrorField(err))
 		} else {
-			logger.Info("✅ CachedFieldRepository.FindByTableID 缓存写入成功",
+			fieldRepoLog.Debug(ctx, "FindByTableID 缓存写入成功",
 				logger.String("table_id", tableID),
 				logger.Int("field_count", len(fields)),
 				logger.String("cache_key", cacheKey))
 		}
 	} else {
-		logger.Info("⚠️ CachedFieldRepository.FindByTableID 查询结果为空，不写入缓存",
+		fieldRepoLog.Debug(ctx, "FindByTableID 查询结果为空，不写入缓存",
 			logger.String("table_id", tableID))
 	}
 
@@ -227,7 +233,7 @@ func (r *CachedFieldRepository) Save(ctx context.Context, field *fieldEntity.Fie
 	// 确保后续查询会从数据库获取最新数据
 	r.invalidateCache(ctx, field)
 	
-	logger.Info("✅ CachedFieldRepository.Save 字段保存成功并清除缓存",
+	fieldRepoLog.Debug(ctx, "Save 字段保存成功并清除缓存",
 		logger.String("field_id", field.ID().String()),
 		logger.String("table_id", field.TableID()))
 	
@@ -260,17 +266,17 @@ func (r *CachedFieldRepository) invalidateCache(ctx context.Context, field *fiel
 		r.buildCacheKey("table", tableID),
 	}
 
-	logger.Info("🔍 CachedFieldRepository.invalidateCache 清除字段缓存",
+	fieldRepoLog.Debug(ctx, "invalidateCache 清除字段缓存",
 		logger.String("field_id", fieldID),
 		logger.String("table_id", tableID),
 		logger.Any("cache_keys", keys))
 
 	if err := r.cacheService.Delete(ctx, keys...); err != nil {
-		logger.Warn("failed to invalidate field cache",
+		fieldRepoLog.Warn(ctx, "failed to invalidate field cache",
 			logger.String("field_id", fieldID),
 			logger.ErrorField(err))
 	} else {
-		logger.Info("✅ CachedFieldRepository.invalidateCache 清除字段缓存成功",
+		fieldRepoLog.Debug(ctx, "invalidateCache 清除字段缓存成功",
 			logger.String("field_id", fieldID),
 			logger.Any("cache_keys", keys))
 	}
@@ -284,32 +290,41 @@ func (r *CachedFieldRepository) invalidateCache(ctx context.Context, field *fiel
 	// ❌ 关键修复：为了确保本地缓存也被清除，我们需要直接删除表字段列表的缓存键
 	// 因为本地缓存的模式删除可能不工作，我们直接删除完全匹配的键
 	tableCacheKey := r.buildCacheKey("table", tableID)
-	logger.Info("🔍 CachedFieldRepository.invalidateCache 清除表字段列表缓存",
+	fieldRepoLog.Debug(ctx, "invalidateCache 清除表字段列表缓存",
 		logger.String("table_cache_key", tableCacheKey),
 		logger.String("table_id", tableID))
 	
 	// 先直接删除表字段列表缓存键（确保本地缓存也被清除）
 	if err := r.cacheService.Delete(ctx, tableCacheKey); err != nil {
-		logger.Warn("failed to delete table field list cache",
+		fieldRepoLog.Warn(ctx, "failed to delete table field list cache",
 			logger.String("table_cache_key", tableCacheKey),
 			logger.ErrorField(err))
 	} else {
-		logger.Info("✅ CachedFieldRepository.invalidateCache 清除表字段列表缓存键成功",
+		fieldRepoLog.Debug(ctx, "invalidateCache 清除表字段列表缓存键成功",
 			logger.String("table_cache_key", tableCacheKey))
 	}
 	
 	// 然后使用模式删除（主要用于 Redis，处理可能的变体）
 	pattern := fmt.Sprintf("field:table:%s", tableID)
 	if err := r.cacheService.InvalidatePattern(ctx, pattern); err != nil {
-		logger.Warn("failed to invalidate field pattern cache",
+		fieldRepoLog.Warn(ctx, "failed to invalidate field pattern cache",
 			logger.String("pattern", pattern),
 			logger.ErrorField(err))
 	} else {
-		logger.Info("✅ CachedFieldRepository.invalidateCache 清除表字段列表模式缓存成功",
+		fieldRepoLog.Debug(ctx, "invalidateCache 清除表字段列表模式缓存成功",
 			logger.String("pattern", pattern))
 	}
 }
 
+// InvalidateTable 清除表字段列表缓存（条件请求校验失败时调用，保证客户端重新获取到最新 ETag）
+func (r *CachedFieldRepository) InvalidateTable(ctx context.Context, tableID string) {
+	if err := r.cacheService.Delete(ctx, r.buildCacheKey("table", tableID)); err != nil {
+		fieldRepoLog.Warn(ctx, "failed to invalidate table field list cache",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+	}
+}
+
 // 实现其他接口方法（直接委托给底层repo）
 func (r *CachedFieldRepository) FindByName(ctx context.Context, tableID string, name fieldValueobject.FieldName) (*fieldEntity.Field, error) {
 	return r.repo.FindByName(ctx, tableID, name)
@@ -336,12 +351,17 @@ func (r *CachedFieldRepository) BatchSave(ctx context.Context, fields []*fieldEn
 	tableIDs := make(map[string]bool)
 	for _, field := range fields {
 		tableIDs[field.TableID()] = true
+		if err := r.cacheService.Delete(ctx, r.buildCacheKey("id", field.ID().String())); err != nil {
+			fieldRepoLog.Warn(ctx, "failed to invalidate field cache after batch save",
+				logger.String("field_id", field.ID().String()),
+				logger.ErrorField(err))
+		}
 	}
 
 	for tableID := range tableIDs {
 		cacheKey := r.buildCacheKey("table", tableID)
 		if err := r.cacheService.Delete(ctx, cacheKey); err != nil {
-			logger.Warn("failed to invalidate cache after batch save",
+			fieldRepoLog.Warn(ctx, "failed to invalidate cache after batch save",
 				logger.String("table_id", tableID),
 				logger.ErrorField(err))
 		}
@@ -350,8 +370,23 @@ func (r *CachedFieldRepository) BatchSave(ctx context.Context, fields []*fieldEn
 	return nil
 }
 
+// BatchDelete 批量删除字段（先获取字段信息，删除后清除字段及表字段列表缓存）
 func (r *CachedFieldRepository) BatchDelete(ctx context.Context, ids []fieldValueobject.FieldID) error {
-	return r.repo.BatchDelete(ctx, ids)
+	fields := make([]*fieldEntity.Field, 0, len(ids))
+	for _, id := range ids {
+		if field, _ := r.repo.FindByID(ctx, id); field != nil {
+			fields = append(fields, field)
+		}
+	}
+
+	if err := r.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		r.invalidateCache(ctx, field)
+	}
+	return nil
 }
 
 func (r *CachedFieldRepository) GetVirtualFields(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
@@ -366,8 +401,40 @@ func (r *CachedFieldRepository) GetFieldsByType(ctx context.Context, tableID str
 	return r.repo.GetFieldsByType(ctx, tableID, fieldType)
 }
 
+// UpdateOrder 更新字段排序（清除字段及表字段列表缓存）
 func (r *CachedFieldRepository) UpdateOrder(ctx context.Context, fieldID fieldValueobject.FieldID, order float64) error {
-	return r.repo.UpdateOrder(ctx, fieldID, order)
+	if err := r.repo.UpdateOrder(ctx, fieldID, order); err != nil {
+		return err
+	}
+
+	if field, _ := r.repo.FindByID(ctx, fieldID); field != nil {
+		r.invalidateCache(ctx, field)
+	}
+	return nil
+}
+
+// BatchUpdateOrder 批量重排字段（全部更新后统一清除一次缓存）
+func (r *CachedFieldRepository) BatchUpdateOrder(ctx context.Context, tableID string, fieldIDs []fieldValueobject.FieldID) error {
+	if err := r.repo.BatchUpdateOrder(ctx, tableID, fieldIDs); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fieldIDs)+1)
+	keys = append(keys, r.buildCacheKey("table", tableID))
+	for _, id := range fieldIDs {
+		keys = append(keys, r.buildCacheKey("id", id.String()))
+	}
+	if err := r.cacheService.Delete(ctx, keys...); err != nil {
+		fieldRepoLog.Warn(ctx, "failed to invalidate field cache after reorder",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+	}
+	if err := r.cacheService.InvalidatePattern(ctx, fmt.Sprintf("field:table:%s", tableID)); err != nil {
+		fieldRepoLog.Warn(ctx, "failed to invalidate field pattern cache",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+	}
+	return nil
 }
 
 func (r *CachedFieldRepository) GetMaxOrder(ctx context.Context, tableID string) (float64, error) {
@@ -403,6 +470,19 @@ func NewCachedRecordRepository(
 	}
 }
 
+// encryptedFieldChecker 可判断表中是否含加密字段的仓储
+type encryptedFieldChecker interface {
+	HasEncryptedFields(ctx context.Context, tableID string) bool
+}
+
+// hasEncryptedFields 表中是否含加密字段
+func (r *CachedRecordRepository) hasEncryptedFields(ctx context.Context, tableID string) bool {
+	if checker, ok := r.repo.(encryptedFieldChecker); ok {
+		return checker.HasEncryptedFields(ctx, tableID)
+	}
+	return false
+}
+
 // buildCacheKey 构建缓存键
 func (r *CachedRecordRepository) buildCacheKey(prefix, tableID, recordID string) string {
 	return fmt.Sprintf("record:%s:%s:%s", prefix, tableID, recordID)
@@ -415,7 +495,7 @@ func (r *CachedRecordRepository) FindByTableAndID(ctx context.Context, tableID s
 	// 尝试从缓存获取
 	var record *recordEntity.Record
 	if err := r.cacheService.Get(ctx, cacheKey, &record); err == nil {
-		logger.Debug("record cache hit",
+		recordRepoLog.Debug(ctx, "record cache hit",
 			logger.String("table_id", tableID),
 			logger.String("record_id", id.String()))
 		return record, nil
@@ -427,10 +507,10 @@ func (r *CachedRecordRepository) FindByTableAndID(ctx context.Context, tableID s
 		return nil, err
 	}
 
-	// 写入缓存
-	if record != nil {
+	// 写入缓存（含加密字段的表不缓存明文）
+	if record != nil && !r.hasEncryptedFields(ctx, tableID) {
 		if err := r.cacheService.Set(ctx, cacheKey, record, r.ttl); err != nil {
-			logger.Warn("failed to cache record",
+			recordRepoLog.Warn(ctx, "failed to cache record",
 				logger.String("record_id", id.String()),
 				logger.ErrorField(err))
 		}
@@ -439,6 +519,15 @@ func (r *CachedRecordRepository) FindByTableAndID(ctx context.Context, tableID s
 	return record, nil
 }
 
+// InvalidateRecord 清除单条记录缓存（版本冲突时调用，保证客户端重新获取到最新 ETag）
+func (r *CachedRecordRepository) InvalidateRecord(ctx context.Context, tableID, recordID string) {
+	if err := r.cacheService.Delete(ctx, r.buildCacheKey("id", tableID, recordID)); err != nil {
+		recordRepoLog.Warn(ctx, "failed to invalidate record cache",
+			logger.String("record_id", recordID),
+			logger.ErrorField(err))
+	}
+}
+
 // Save 保存记录（更新后清除缓存）
 func (r *CachedRecordRepository) Save(ctx context.Context, record *recordEntity.Record) error {
 	if err := r.repo.Save(ctx, record); err != nil {
@@ -449,7 +538,7 @@ func (r *CachedRecordRepository) Save(ctx context.Context, record *recordEntity.
 	// 清除记录缓存
 	cacheKey := r.buildCacheKey("id", record.TableID(), record.ID().String())
 	if err := r.cacheService.Delete(ctx, cacheKey); err != nil {
-		logger.Warn("failed to invalidate record cache",
+		recordRepoLog.Warn(ctx, "failed to invalidate record cache",
 			logger.String("record_id", record.ID().String()),
 			logger.ErrorField(err))
 	}
@@ -458,11 +547,11 @@ func (r *CachedRecordRepository) Save(ctx context.Context, record *recordEntity.
 	// 同步清除，确保立即生效，避免竞争条件
 	pattern := fmt.Sprintf("record:list:%s:*", record.TableID())
 	if err := r.cacheService.InvalidatePattern(ctx, pattern); err != nil {
-		logger.Warn("failed to invalidate record list cache",
+		recordRepoLog.Warn(ctx, "failed to invalidate record list cache",
 			logger.String("pattern", pattern),
 			logger.ErrorField(err))
 	} else {
-		logger.Info("✅ CachedRecordRepository.Save 记录列表缓存清除成功",
+		recordRepoLog.Debug(ctx, "Save 记录列表缓存清除成功",
 			logger.String("table_id", record.TableID()),
 			logger.String("record_id", record.ID().String()),
 			logger.String("pattern", pattern))
@@ -476,11 +565,23 @@ func (r *CachedRecordRepository) DeleteByTableAndID(ctx context.Context, tableID
 	if err := r.repo.DeleteByTableAndID(ctx, tableID, id); err != nil {
 		return err
 	}
+	r.invalidateDeleted(ctx, tableID, id)
+	return nil
+}
 
+// DeleteByTableAndIDWithVersion 按版本删除记录（清除缓存；版本冲突时同样清除，避免反复读到旧版本）
+func (r *CachedRecordRepository) DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id recordValueobject.RecordID, version recordValueobject.RecordVersion) error {
+	err := r.repo.DeleteByTableAndIDWithVersion(ctx, tableID, id, version)
+	r.invalidateDeleted(ctx, tableID, id)
+	return err
+}
+
+// invalidateDeleted 清除已删除记录的缓存与表格记录列表缓存
+func (r *CachedRecordRepository) invalidateDeleted(ctx context.Context, tableID string, id recordValueobject.RecordID) {
 	// 清除缓存
 	cacheKey := r.buildCacheKey("id", tableID, id.String())
 	if err := r.cacheService.Delete(ctx, cacheKey); err != nil {
-		logger.Warn("failed to invalidate record cache after delete",
+		recordRepoLog.Warn(ctx, "failed to invalidate record cache after delete",
 			logger.String("record_id", id.String()),
 			logger.ErrorField(err))
 	}
@@ -488,12 +589,10 @@ func (r *CachedRecordRepository) DeleteByTableAndID(ctx context.Context, tableID
 	// 清除表格记录列表缓存
 	pattern := fmt.Sprintf("record:list:%s:*", tableID)
 	if err := r.cacheService.InvalidatePattern(ctx, pattern); err != nil {
-		logger.Warn("failed to invalidate record list cache",
+		recordRepoLog.Warn(ctx, "failed to invalidate record list cache",
 			logger.String("pattern", pattern),
 			logger.ErrorField(err))
 	}
-
-	return nil
 }
 
 // List 列出记录（带缓存，但缓存时间较短）
@@ -511,7 +610,7 @@ func (r *CachedRecordRepository) List(ctx context.Context, filter recordRepo.Rec
 		return nil, 0, err
 	}
 
-	logger.Debug("record list query (no cache)",
+	recordRepoLog.Debug(ctx, "record list query (no cache)",
 		logger.String("table_id", *filter.TableID),
 		logger.Int("record_count", len(records)),
 		logger.Int64("total", total))
@@ -535,7 +634,7 @@ func (r *CachedRecordRepository) BatchSave(ctx context.Context, records []*recor
 		tableIDs[record.TableID()] = true
 		cacheKey := r.buildCacheKey("id", record.TableID(), record.ID().String())
 		if err := r.cacheService.Delete(ctx, cacheKey); err != nil {
-			logger.Warn("failed to invalidate cache after batch save",
+			recordRepoLog.Warn(ctx, "failed to invalidate cache after batch save",
 				logger.String("record_id", record.ID().String()),
 				logger.ErrorField(err))
 		}
@@ -544,7 +643,7 @@ func (r *CachedRecordRepository) BatchSave(ctx context.Context, records []*recor
 	for tableID := range tableIDs {
 		pattern := fmt.Sprintf("record:list:%s:*", tableID)
 		if err := r.cacheService.InvalidatePattern(ctx, pattern); err != nil {
-			logger.Warn("failed to invalidate record list cache",
+			recordRepoLog.Warn(ctx, "failed to invalidate record list cache",
 				logger.String("pattern", pattern),
 				logger.ErrorField(err))
 		}
@@ -569,7 +668,7 @@ func (r *CachedRecordRepository) BatchDelete(ctx context.Context, ids []recordVa
 		// 如果没有缓存，则跳过（缓存已自动失效）
 		pattern := fmt.Sprintf("record:*:*:%s", id.String())
 		if err := r.cacheService.InvalidatePattern(ctx, pattern); err != nil {
-			logger.Warn("failed to invalidate record cache",
+			recordRepoLog.Warn(ctx, "failed to invalidate record cache",
 				logger.String("record_id", id.String()),
 				logger.ErrorField(err))
 		}
@@ -582,8 +681,8 @@ func (r *CachedRecordRepository) Exists(ctx context.Context, id recordValueobjec
 	return r.repo.Exists(ctx, id)
 }
 
-func (r *CachedRecordRepository) FindByIDs(ctx context.Context, tableID string, ids []recordValueobject.RecordID) ([]*recordEntity.Record, error) {
-	return r.repo.FindByIDs(ctx, tableID, ids)
+func (r *CachedRecordRepository) FindByIDs(ctx context.Context, tableID string, ids []recordValueobject.RecordID, fieldIDs ...string) ([]*recordEntity.Record, error) {
+	return r.repo.FindByIDs(ctx, tableID, ids, fieldIDs...)
 }
 
 func (r *CachedRecordRepository) FindByTableID(ctx context.Context, tableID string) ([]*recordEntity.Record, error) {
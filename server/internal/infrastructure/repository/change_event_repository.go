@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// changeFeedListLimit 变更列表默认返回条数
+const changeFeedListLimit = 500
+
+// changeFeedRelayLockKey 变更编号的事务级咨询锁（保证同一时刻只有一个实例分配序号）
+const changeFeedRelayLockKey = 7302651840
+
+// ChangeEventRepositoryImpl 变更流GORM实现
+type ChangeEventRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewChangeEventRepository 创建变更流仓储
+func NewChangeEventRepository(db *gorm.DB) changefeed.Repository {
+	return &ChangeEventRepositoryImpl{db: db}
+}
+
+// Append 写入发件箱
+// 使用上下文中的事务连接：业务事务回滚时变更一并丢弃，提交后才会被中继编号
+func (r *ChangeEventRepositoryImpl) Append(ctx context.Context, changes ...*changefeed.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	rows := make([]models.ChangeEvent, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, toChangeEventModel(change))
+	}
+	if err := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).CreateInBatches(rows, 500).Error; err != nil {
+		return fmt.Errorf("failed to append change events: %w", err)
+	}
+	return nil
+}
+
+// Relay 为已提交的未编号变更分配序号
+// 编号在持有咨询锁的单个事务中完成，序号按编号顺序可见，读取方不会因并发事务的提交顺序而跳过变更
+func (r *ChangeEventRepositoryImpl) Relay(ctx context.Context, batchSize int) (int, error) {
+	var relayed int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", changeFeedRelayLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		result := tx.Exec(`
+			WITH pending AS (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY created_time, id) AS n
+				FROM change_event
+				WHERE seq IS NULL
+				ORDER BY created_time, id
+				LIMIT ?
+			), head AS (
+				SELECT COALESCE(MAX(seq), 0) AS seq FROM change_event
+			)
+			UPDATE change_event SET seq = head.seq + pending.n
+			FROM pending, head
+			WHERE change_event.id = pending.id`, batchSize)
+		if result.Error != nil {
+			return result.Error
+		}
+		relayed = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to relay change events: %w", err)
+	}
+	return int(relayed), nil
+}
+
+// ListAfter 按序号升序列出游标之后的变更
+func (r *ChangeEventRepositoryImpl) ListAfter(ctx context.Context, baseID string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	if filter.After > 0 {
+		var oldest *int64
+		if err := r.db.WithContext(ctx).Model(&models.ChangeEvent{}).
+			Select("MIN(seq)").
+			Where("seq IS NOT NULL").
+			Scan(&oldest).Error; err != nil {
+			return nil, fmt.Errorf("failed to find oldest change event: %w", err)
+		}
+		if oldest != nil && *oldest > filter.After+1 {
+			return nil, changefeed.ErrCursorExpired
+		}
+	}
+
+	return r.list(r.db.WithContext(ctx).Where("base_id = ? AND seq > ?", baseID, filter.After), filter, "seq ASC")
+}
+
+// ListLatest 按序号降序列出最新的变更
+func (r *ChangeEventRepositoryImpl) ListLatest(ctx context.Context, baseID string, filter changefeed.ListFilter) ([]*changefeed.Change, error) {
+	return r.list(r.db.WithContext(ctx).Where("base_id = ? AND seq IS NOT NULL", baseID), filter, "seq DESC")
+}
+
+func (r *ChangeEventRepositoryImpl) list(query *gorm.DB, filter changefeed.ListFilter, order string) ([]*changefeed.Change, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = changeFeedListLimit
+	}
+	if filter.TableID != "" {
+		query = query.Where("table_id = ?", filter.TableID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", string(filter.EntityType))
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", string(filter.Action))
+	}
+
+	var rows []models.ChangeEvent
+	if err := query.Order(order).Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list change events: %w", err)
+	}
+	changes := make([]*changefeed.Change, 0, len(rows))
+	for i := range rows {
+		changes = append(changes, toChangeEntity(&rows[i]))
+	}
+	return changes, nil
+}
+
+// Head 当前最大序号
+func (r *ChangeEventRepositoryImpl) Head(ctx context.Context) (int64, error) {
+	var head *int64
+	if err := r.db.WithContext(ctx).Model(&models.ChangeEvent{}).
+		Select("MAX(seq)").
+		Scan(&head).Error; err != nil {
+		return 0, fmt.Errorf("failed to find change feed head: %w", err)
+	}
+	if head == nil {
+		return 0, nil
+	}
+	return *head, nil
+}
+
+// PurgeBefore 删除过期的已编号变更
+// 始终保留序号最大的一条：编号以最大序号为起点，全部删除会使新序号从头开始，导致已有游标错乱
+func (r *ChangeEventRepositoryImpl) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("seq IS NOT NULL AND created_time < ?", before).
+		Where("seq < (SELECT MAX(seq) FROM change_event)").
+		Delete(&models.ChangeEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge change events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func toChangeEventModel(change *changefeed.Change) models.ChangeEvent {
+	model := models.ChangeEvent{
+		ID:          change.ID,
+		BaseID:      change.BaseID,
+		TableID:     change.TableID,
+		EntityType:  string(change.EntityType),
+		Action:      string(change.Action),
+		EntityID:    change.EntityID,
+		Version:     change.Version,
+		UserID:      change.UserID,
+		CreatedTime: change.OccurredAt,
+	}
+	if len(change.Data) > 0 {
+		data := string(change.Data)
+		model.Data = &data
+	}
+	return model
+}
+
+func toChangeEntity(model *models.ChangeEvent) *changefeed.Change {
+	change := &changefeed.Change{
+		ID:         model.ID,
+		BaseID:     model.BaseID,
+		TableID:    model.TableID,
+		EntityType: changefeed.EntityType(model.EntityType),
+		Action:     changefeed.Action(model.Action),
+		EntityID:   model.EntityID,
+		Version:    model.Version,
+		UserID:     model.UserID,
+		OccurredAt: model.CreatedTime,
+	}
+	if model.Seq != nil {
+		change.Seq = *model.Seq
+	}
+	if model.Data != nil {
+		change.Data = json.RawMessage(*model.Data)
+	}
+	return change
+}
@@ -57,7 +57,7 @@ func (r *collaboratorRepositoryImpl) Create(ctx context.Context, collaborator *e
 	}
 
 	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	return nil
 }
@@ -152,7 +152,7 @@ func (r *collaboratorRepositoryImpl) Update(ctx context.Context, collaborator *e
 		Updates(updates).Error
 
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	return nil
 }
@@ -164,7 +164,7 @@ func (r *collaboratorRepositoryImpl) Delete(ctx context.Context, id string) erro
 		Delete(&CollaboratorModel{}).Error
 
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	return nil
 }
@@ -176,7 +176,7 @@ func (r *collaboratorRepositoryImpl) DeleteByResourceAndPrincipal(ctx context.Co
 		Delete(&CollaboratorModel{}).Error
 
 	if err != nil {
-		return errors.ErrDatabaseOperation.WithDetails(err.Error())
+		return errors.Database(err, "")
 	}
 	return nil
 }
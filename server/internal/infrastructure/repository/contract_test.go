@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableVO "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository/repotest"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// contractDSNEnv 契约测试使用的 PostgreSQL 连接串（数据库需已执行迁移），未设置时跳过
+const contractDSNEnv = "LUCKDB_TEST_DATABASE_URL"
+
+func openContractDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv(contractDSNEnv)
+	if dsn == "" {
+		t.Skipf("未设置 %s，跳过数据库契约测试", contractDSNEnv)
+	}
+	if err := logger.Init(logger.LoggerConfig{Level: "error", Format: "console"}); err != nil {
+		t.Fatalf("初始化日志失败: %v", err)
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("连接数据库失败: %v", err)
+	}
+	return db
+}
+
+func TestGormFieldRepositoryContract(t *testing.T) {
+	db := openContractDB(t)
+	repotest.FieldRepositoryContract(t, func(t *testing.T) fieldRepo.FieldRepository {
+		return repository.NewFieldRepository(db)
+	})
+}
+
+func TestCachedGormFieldRepositoryContract(t *testing.T) {
+	db := openContractDB(t)
+	repotest.FieldRepositoryContract(t, func(t *testing.T) fieldRepo.FieldRepository {
+		return repository.NewCachedFieldRepository(repository.NewFieldRepository(db), repotest.NewCache(), 0)
+	})
+}
+
+func TestDynamicRecordRepositoryContract(t *testing.T) {
+	db := openContractDB(t)
+	repotest.RecordRepositoryContract(t, dynamicRecordSetup(db, false))
+}
+
+func TestCachedDynamicRecordRepositoryContract(t *testing.T) {
+	db := openContractDB(t)
+	repotest.RecordRepositoryContract(t, dynamicRecordSetup(db, true))
+}
+
+// dynamicRecordSetup 为每个子测试创建独立的 Base schema、物理表和一个文本字段，测试结束后删除
+func dynamicRecordSetup(db *gorm.DB, cached bool) func(t *testing.T) repotest.RecordFixture {
+	return func(t *testing.T) repotest.RecordFixture {
+		t.Helper()
+		ctx := context.Background()
+		provider := database.NewPostgresProvider(db)
+		tables := repository.NewTableRepository(db)
+		fields := repository.NewFieldRepository(db)
+
+		baseID := utils.GenerateBaseID()
+		if err := provider.CreateSchema(ctx, baseID); err != nil {
+			t.Fatalf("创建 schema 失败: %v", err)
+		}
+		t.Cleanup(func() { _ = provider.DropSchema(context.Background(), baseID) })
+
+		name, _ := tableVO.NewTableName("契约测试")
+		table, err := tableEntity.NewTable(baseID, name, "usr_contract")
+		if err != nil {
+			t.Fatalf("创建表格实体失败: %v", err)
+		}
+		tableID := table.ID().String()
+		if err := provider.CreatePhysicalTable(ctx, baseID, tableID); err != nil {
+			t.Fatalf("创建物理表失败: %v", err)
+		}
+		table.SetDBTableName(provider.GenerateTableName(baseID, tableID))
+		if err := tables.Save(ctx, table); err != nil {
+			t.Fatalf("保存表格失败: %v", err)
+		}
+		t.Cleanup(func() { _ = tables.Delete(context.Background(), tableID) })
+
+		fieldName, _ := fieldVO.NewFieldName("标题")
+		fieldType, _ := fieldVO.NewFieldType(fieldVO.TypeText)
+		field, err := fieldEntity.NewField(tableID, fieldName, fieldType, "usr_contract")
+		if err != nil {
+			t.Fatalf("创建字段失败: %v", err)
+		}
+		column := database.ColumnDefinition{Name: field.DBFieldName().String(), Type: field.DBFieldType()}
+		if err := provider.AddColumn(ctx, baseID, tableID, column); err != nil {
+			t.Fatalf("添加列失败: %v", err)
+		}
+		if err := fields.Save(ctx, field); err != nil {
+			t.Fatalf("保存字段失败: %v", err)
+		}
+		t.Cleanup(func() { _ = fields.Delete(context.Background(), field.ID()) })
+
+		repo := repository.NewRecordRepositoryDynamic(db, provider, tables, fields)
+		if cached {
+			repo = repository.NewCachedRecordRepository(repo, repotest.NewCache(), 0)
+		}
+		return repotest.RecordFixture{Repo: repo, TableID: tableID, FieldID: field.ID().String()}
+	}
+}
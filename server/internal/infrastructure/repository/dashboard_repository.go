@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/dashboard"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// DashboardRepositoryImpl 仪表盘仓储GORM实现
+type DashboardRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDashboardRepository 创建仪表盘仓储
+func NewDashboardRepository(db *gorm.DB) dashboard.Repository {
+	return &DashboardRepositoryImpl{db: db}
+}
+
+// Save 保存仪表盘
+func (r *DashboardRepositoryImpl) Save(ctx context.Context, d *dashboard.Dashboard) error {
+	model, err := toDashboardModel(d)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Save(model).Error; err != nil {
+		return fmt.Errorf("failed to save dashboard: %w", err)
+	}
+	return nil
+}
+
+// SaveWithWidgets 在同一事务中保存仪表盘和组件
+func (r *DashboardRepositoryImpl) SaveWithWidgets(ctx context.Context, d *dashboard.Dashboard, widgets []*dashboard.Widget) error {
+	model, err := toDashboardModel(d)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(model).Error; err != nil {
+			return fmt.Errorf("failed to save dashboard: %w", err)
+		}
+		for _, w := range widgets {
+			if err := tx.Save(toDashboardWidgetModel(w)).Error; err != nil {
+				return fmt.Errorf("failed to save dashboard widget: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// FindByID 获取仪表盘
+func (r *DashboardRepositoryImpl) FindByID(ctx context.Context, id string) (*dashboard.Dashboard, error) {
+	return r.findOne(ctx, r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+// FindByShareID 通过分享ID获取已开启分享的仪表盘
+func (r *DashboardRepositoryImpl) FindByShareID(ctx context.Context, shareID string) (*dashboard.Dashboard, error) {
+	return r.findOne(ctx, r.db.WithContext(ctx).Where("share_id = ? AND enable_share = ?", shareID, true))
+}
+
+func (r *DashboardRepositoryImpl) findOne(ctx context.Context, query *gorm.DB) (*dashboard.Dashboard, error) {
+	var model models.Dashboard
+	err := query.Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard: %w", err)
+	}
+	return fromDashboardModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的仪表盘
+func (r *DashboardRepositoryImpl) ListByBase(ctx context.Context, baseID string) ([]*dashboard.Dashboard, error) {
+	var list []models.Dashboard
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list dashboards: %w", err)
+	}
+
+	dashboards := make([]*dashboard.Dashboard, 0, len(list))
+	for i := range list {
+		dashboards = append(dashboards, fromDashboardModel(&list[i]))
+	}
+	return dashboards, nil
+}
+
+// Delete 删除仪表盘及其组件
+func (r *DashboardRepositoryImpl) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("dashboard_id = ?", id).Delete(&models.DashboardWidget{}).Error; err != nil {
+			return fmt.Errorf("failed to delete dashboard widgets: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.Dashboard{}).Error; err != nil {
+			return fmt.Errorf("failed to delete dashboard: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveWidget 保存组件
+func (r *DashboardRepositoryImpl) SaveWidget(ctx context.Context, w *dashboard.Widget) error {
+	if err := r.db.WithContext(ctx).Save(toDashboardWidgetModel(w)).Error; err != nil {
+		return fmt.Errorf("failed to save dashboard widget: %w", err)
+	}
+	return nil
+}
+
+// FindWidget 获取组件
+func (r *DashboardRepositoryImpl) FindWidget(ctx context.Context, id string) (*dashboard.Widget, error) {
+	var model models.DashboardWidget
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard widget: %w", err)
+	}
+	return fromDashboardWidgetModel(&model), nil
+}
+
+// ListWidgets 列出仪表盘的组件
+func (r *DashboardRepositoryImpl) ListWidgets(ctx context.Context, dashboardID string) ([]*dashboard.Widget, error) {
+	var list []models.DashboardWidget
+	if err := r.db.WithContext(ctx).
+		Where("dashboard_id = ?", dashboardID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list dashboard widgets: %w", err)
+	}
+
+	widgets := make([]*dashboard.Widget, 0, len(list))
+	for i := range list {
+		widgets = append(widgets, fromDashboardWidgetModel(&list[i]))
+	}
+	return widgets, nil
+}
+
+// DeleteWidget 删除组件
+func (r *DashboardRepositoryImpl) DeleteWidget(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.DashboardWidget{}).Error; err != nil {
+		return fmt.Errorf("failed to delete dashboard widget: %w", err)
+	}
+	return nil
+}
+
+func toDashboardModel(d *dashboard.Dashboard) (*models.Dashboard, error) {
+	layout, err := json.Marshal(d.Layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dashboard layout: %w", err)
+	}
+	layoutStr := string(layout)
+	model := &models.Dashboard{
+		ID:               d.ID,
+		Name:             d.Name,
+		BaseID:           d.BaseID,
+		Layout:           &layoutStr,
+		EnableShare:      d.EnableShare,
+		ShareID:          d.ShareID,
+		CreatedBy:        d.CreatedBy,
+		CreatedTime:      d.CreatedAt,
+		LastModifiedTime: d.UpdatedAt,
+	}
+	if d.LastModifiedBy != "" {
+		model.LastModifiedBy = &d.LastModifiedBy
+	}
+	return model, nil
+}
+
+func fromDashboardModel(m *models.Dashboard) *dashboard.Dashboard {
+	d := &dashboard.Dashboard{
+		ID:          m.ID,
+		BaseID:      m.BaseID,
+		Name:        m.Name,
+		Layout:      []dashboard.LayoutItem{},
+		EnableShare: m.EnableShare,
+		ShareID:     m.ShareID,
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedTime,
+		UpdatedAt:   m.LastModifiedTime,
+	}
+	if m.Layout != nil && *m.Layout != "" {
+		// 布局损坏时按空布局处理，组件仍可通过列表获取
+		_ = json.Unmarshal([]byte(*m.Layout), &d.Layout)
+	}
+	if m.LastModifiedBy != nil {
+		d.LastModifiedBy = *m.LastModifiedBy
+	}
+	return d
+}
+
+func toDashboardWidgetModel(w *dashboard.Widget) *models.DashboardWidget {
+	model := &models.DashboardWidget{
+		ID:               w.ID,
+		DashboardID:      w.DashboardID,
+		Type:             string(w.Type),
+		Name:             w.Name,
+		CreatedBy:        w.CreatedBy,
+		CreatedTime:      w.CreatedAt,
+		LastModifiedTime: w.UpdatedAt,
+	}
+	if w.TableID != "" {
+		model.TableID = &w.TableID
+	}
+	if w.ViewID != "" {
+		model.ViewID = &w.ViewID
+	}
+	if len(w.Config) > 0 {
+		config := string(w.Config)
+		model.Config = &config
+	}
+	return model
+}
+
+func fromDashboardWidgetModel(m *models.DashboardWidget) *dashboard.Widget {
+	w := &dashboard.Widget{
+		ID:          m.ID,
+		DashboardID: m.DashboardID,
+		Type:        dashboard.WidgetType(m.Type),
+		Name:        m.Name,
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedTime,
+		UpdatedAt:   m.LastModifiedTime,
+	}
+	if m.TableID != nil {
+		w.TableID = *m.TableID
+	}
+	if m.ViewID != nil {
+		w.ViewID = *m.ViewID
+	}
+	if m.Config != nil {
+		w.Config = json.RawMessage(*m.Config)
+	}
+	return w
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/dataexport"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// DataExportRepositoryImpl 导出任务GORM实现
+type DataExportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository 创建导出任务仓储
+func NewDataExportRepository(db *gorm.DB) dataexport.Repository {
+	return &DataExportRepositoryImpl{db: db}
+}
+
+// Save 创建或更新导出任务
+func (r *DataExportRepositoryImpl) Save(ctx context.Context, export *dataexport.Export) error {
+	tableIDs, err := json.Marshal(export.TableIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal export tables: %w", err)
+	}
+	var manifest string
+	if export.Manifest != nil {
+		data, err := json.Marshal(export.Manifest)
+		if err != nil {
+			return fmt.Errorf("failed to marshal export manifest: %w", err)
+		}
+		manifest = string(data)
+	}
+	model := models.DataExport{
+		ID:           export.ID,
+		BaseID:       export.BaseID,
+		TableIDs:     string(tableIDs),
+		Format:       export.Format,
+		Status:       string(export.Status),
+		Manifest:     manifest,
+		Error:        export.Error,
+		CreatedBy:    export.CreatedBy,
+		CreatedTime:  export.CreatedAt,
+		UpdatedTime:  export.UpdatedAt,
+		FinishedTime: export.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save data export: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取导出任务
+func (r *DataExportRepositoryImpl) FindByID(ctx context.Context, id string) (*dataexport.Export, error) {
+	var model models.DataExport
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+	return fromDataExportModel(&model), nil
+}
+
+// ListByBase 按创建时间倒序列出 Base 的导出任务
+func (r *DataExportRepositoryImpl) ListByBase(ctx context.Context, baseID string, limit int) ([]*dataexport.Export, error) {
+	var list []models.DataExport
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list data exports: %w", err)
+	}
+	exports := make([]*dataexport.Export, 0, len(list))
+	for i := range list {
+		exports = append(exports, fromDataExportModel(&list[i]))
+	}
+	return exports, nil
+}
+
+// Claim 领取待执行或已中断的导出任务（PostgreSQL 使用 SKIP LOCKED）
+func (r *DataExportRepositoryImpl) Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*dataexport.Export, error) {
+	var claimed []*dataexport.Export
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.DataExport{}).
+			Where("status = ? OR (status = ? AND updated_time < ?)",
+				string(dataexport.StatusPending), string(dataexport.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.DataExport
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.DataExport{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(dataexport.StatusRunning),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(dataexport.StatusRunning)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromDataExportModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim data exports: %w", err)
+	}
+	return claimed, nil
+}
+
+func fromDataExportModel(model *models.DataExport) *dataexport.Export {
+	var tableIDs []string
+	if model.TableIDs != "" {
+		_ = json.Unmarshal([]byte(model.TableIDs), &tableIDs)
+	}
+	var manifest *dataexport.Manifest
+	if model.Manifest != "" {
+		manifest = &dataexport.Manifest{}
+		if err := json.Unmarshal([]byte(model.Manifest), manifest); err != nil {
+			manifest = nil
+		}
+	}
+	return &dataexport.Export{
+		ID:         model.ID,
+		BaseID:     model.BaseID,
+		TableIDs:   tableIDs,
+		Format:     model.Format,
+		Status:     dataexport.Status(model.Status),
+		Manifest:   manifest,
+		Error:      model.Error,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/disasterrecovery"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// DisasterRecoveryRepositoryImpl 灾备仓储GORM实现（db 为备区域连接）
+type DisasterRecoveryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDisasterRecoveryRepository 创建灾备仓储
+func NewDisasterRecoveryRepository(db *gorm.DB) disasterrecovery.Repository {
+	return &DisasterRecoveryRepositoryImpl{db: db}
+}
+
+// FindCheckpoint 获取复制流的进度
+func (r *DisasterRecoveryRepositoryImpl) FindCheckpoint(ctx context.Context, stream disasterrecovery.Stream) (*disasterrecovery.Checkpoint, error) {
+	var model models.DRCheckpoint
+	err := r.db.WithContext(ctx).Where("stream = ?", string(stream)).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dr checkpoint: %w", err)
+	}
+	return &disasterrecovery.Checkpoint{
+		Stream:            disasterrecovery.Stream(model.Stream),
+		Position:          model.Position,
+		ReplicatedThrough: model.ReplicatedThrough,
+		Items:             model.Items,
+		LastRunAt:         model.LastRunTime,
+		LastSuccessAt:     model.LastSuccessTime,
+		LastError:         model.LastError,
+		UpdatedAt:         model.UpdatedTime,
+	}, nil
+}
+
+// SaveCheckpoint 创建或更新复制流的进度
+func (r *DisasterRecoveryRepositoryImpl) SaveCheckpoint(ctx context.Context, cp *disasterrecovery.Checkpoint) error {
+	model := models.DRCheckpoint{
+		Stream:            string(cp.Stream),
+		Position:          cp.Position,
+		ReplicatedThrough: cp.ReplicatedThrough,
+		Items:             cp.Items,
+		LastRunTime:       cp.LastRunAt,
+		LastSuccessTime:   cp.LastSuccessAt,
+		LastError:         cp.LastError,
+		UpdatedTime:       cp.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Omit("locked_until").Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save dr checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Acquire 获取复制流的执行租约
+func (r *DisasterRecoveryRepositoryImpl) Acquire(ctx context.Context, stream disasterrecovery.Stream, now, until time.Time) (bool, error) {
+	db := r.db.WithContext(ctx)
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.DRCheckpoint{Stream: string(stream), UpdatedTime: now}).Error; err != nil {
+		return false, fmt.Errorf("failed to init dr checkpoint: %w", err)
+	}
+	result := db.Model(&models.DRCheckpoint{}).
+		Where("stream = ? AND (locked_until IS NULL OR locked_until < ?)", string(stream), now).
+		Update("locked_until", until)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to acquire dr lease: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Release 释放复制流的执行租约
+func (r *DisasterRecoveryRepositoryImpl) Release(ctx context.Context, stream disasterrecovery.Stream) error {
+	if err := r.db.WithContext(ctx).Model(&models.DRCheckpoint{}).
+		Where("stream = ?", string(stream)).
+		Update("locked_until", nil).Error; err != nil {
+		return fmt.Errorf("failed to release dr lease: %w", err)
+	}
+	return nil
+}
+
+// LatestPromotion 获取最近一次提升记录
+func (r *DisasterRecoveryRepositoryImpl) LatestPromotion(ctx context.Context) (*disasterrecovery.Promotion, error) {
+	var model models.DRPromotion
+	err := r.db.WithContext(ctx).Order("promoted_time DESC").Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dr promotion: %w", err)
+	}
+	return &disasterrecovery.Promotion{
+		ID:         model.ID,
+		Region:     model.Region,
+		Forced:     model.Forced,
+		RPOSeconds: model.RPOSeconds,
+		Reason:     model.Reason,
+		PromotedBy: model.PromotedBy,
+		PromotedAt: model.PromotedTime,
+	}, nil
+}
+
+// SavePromotion 保存提升记录
+func (r *DisasterRecoveryRepositoryImpl) SavePromotion(ctx context.Context, promotion *disasterrecovery.Promotion) error {
+	model := models.DRPromotion{
+		ID:           promotion.ID,
+		Region:       promotion.Region,
+		Forced:       promotion.Forced,
+		RPOSeconds:   promotion.RPOSeconds,
+		Reason:       promotion.Reason,
+		PromotedBy:   promotion.PromotedBy,
+		PromotedTime: promotion.PromotedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save dr promotion: %w", err)
+	}
+	return nil
+}
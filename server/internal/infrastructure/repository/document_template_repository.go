@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/doctemplate"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// DocumentTemplateRepositoryImpl 文档模板与生成任务GORM实现
+type DocumentTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDocumentTemplateRepository 创建文档模板仓储
+func NewDocumentTemplateRepository(db *gorm.DB) doctemplate.Repository {
+	return &DocumentTemplateRepositoryImpl{db: db}
+}
+
+// ListTemplates 列出表的文档模板
+func (r *DocumentTemplateRepositoryImpl) ListTemplates(ctx context.Context, tableID string) ([]*doctemplate.Template, error) {
+	var list []models.DocumentTemplate
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list document templates: %w", err)
+	}
+	templates := make([]*doctemplate.Template, 0, len(list))
+	for i := range list {
+		templates = append(templates, fromDocumentTemplateModel(&list[i]))
+	}
+	return templates, nil
+}
+
+// GetTemplate 获取文档模板
+func (r *DocumentTemplateRepositoryImpl) GetTemplate(ctx context.Context, id string) (*doctemplate.Template, error) {
+	var model models.DocumentTemplate
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document template: %w", err)
+	}
+	return fromDocumentTemplateModel(&model), nil
+}
+
+// SaveTemplate 创建或更新文档模板
+func (r *DocumentTemplateRepositoryImpl) SaveTemplate(ctx context.Context, template *doctemplate.Template) error {
+	mergeFields, err := json.Marshal(template.MergeFields)
+	if err != nil {
+		return err
+	}
+	mappings, err := json.Marshal(template.Mappings)
+	if err != nil {
+		return err
+	}
+	model := models.DocumentTemplate{
+		ID:            template.ID,
+		TableID:       template.TableID,
+		Name:          template.Name,
+		Format:        string(template.Format),
+		Output:        template.Output,
+		FileName:      template.FileName,
+		Size:          template.Size,
+		MergeFields:   string(mergeFields),
+		Mappings:      string(mappings),
+		TargetFieldID: template.TargetFieldID,
+		NameFieldID:   template.NameFieldID,
+		CreatedBy:     template.CreatedBy,
+		UpdatedBy:     template.UpdatedBy,
+		CreatedTime:   template.CreatedAt,
+		UpdatedTime:   template.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save document template: %w", err)
+	}
+	return nil
+}
+
+// DeleteTemplate 删除文档模板及其生成任务
+func (r *DocumentTemplateRepositoryImpl) DeleteTemplate(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.DocumentJob{}).Error; err != nil {
+			return fmt.Errorf("failed to delete document jobs: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.DocumentTemplate{}).Error; err != nil {
+			return fmt.Errorf("failed to delete document template: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveJob 创建或更新生成任务
+func (r *DocumentTemplateRepositoryImpl) SaveJob(ctx context.Context, job *doctemplate.Job) error {
+	recordIDs, err := json.Marshal(job.RecordIDs)
+	if err != nil {
+		return err
+	}
+	results, err := json.Marshal(job.Results)
+	if err != nil {
+		return err
+	}
+	model := models.DocumentJob{
+		ID:           job.ID,
+		TemplateID:   job.TemplateID,
+		TableID:      job.TableID,
+		ViewID:       job.ViewID,
+		RecordIDs:    string(recordIDs),
+		TimeZone:     job.TimeZone,
+		Locale:       job.Locale,
+		Source:       job.Source,
+		Status:       string(job.Status),
+		Total:        job.Total,
+		Generated:    job.Generated,
+		Failed:       job.Failed,
+		Truncated:    job.Truncated,
+		Results:      string(results),
+		Error:        job.Error,
+		CreatedBy:    job.CreatedBy,
+		CreatedTime:  job.CreatedAt,
+		UpdatedTime:  job.UpdatedAt,
+		FinishedTime: job.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save document job: %w", err)
+	}
+	return nil
+}
+
+// FindJob 获取生成任务
+func (r *DocumentTemplateRepositoryImpl) FindJob(ctx context.Context, id string) (*doctemplate.Job, error) {
+	var model models.DocumentJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document job: %w", err)
+	}
+	return fromDocumentJobModel(&model), nil
+}
+
+// ListJobs 按创建时间倒序列出模板的生成任务
+func (r *DocumentTemplateRepositoryImpl) ListJobs(ctx context.Context, templateID string, limit int) ([]*doctemplate.Job, error) {
+	var list []models.DocumentJob
+	if err := r.db.WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list document jobs: %w", err)
+	}
+	jobs := make([]*doctemplate.Job, 0, len(list))
+	for i := range list {
+		jobs = append(jobs, fromDocumentJobModel(&list[i]))
+	}
+	return jobs, nil
+}
+
+// ClaimJobs 领取待执行或已中断的生成任务（PostgreSQL 使用 SKIP LOCKED）
+func (r *DocumentTemplateRepositoryImpl) ClaimJobs(ctx context.Context, now, staleBefore time.Time, limit int) ([]*doctemplate.Job, error) {
+	var claimed []*doctemplate.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.DocumentJob{}).
+			Where("status = ? OR (status = ? AND updated_time < ?)",
+				string(doctemplate.StatusPending), string(doctemplate.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.DocumentJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.DocumentJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(doctemplate.StatusRunning),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(doctemplate.StatusRunning)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromDocumentJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim document jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+func fromDocumentTemplateModel(model *models.DocumentTemplate) *doctemplate.Template {
+	template := &doctemplate.Template{
+		ID:            model.ID,
+		TableID:       model.TableID,
+		Name:          model.Name,
+		Format:        doctemplate.Format(model.Format),
+		Output:        model.Output,
+		FileName:      model.FileName,
+		Size:          model.Size,
+		TargetFieldID: model.TargetFieldID,
+		NameFieldID:   model.NameFieldID,
+		CreatedBy:     model.CreatedBy,
+		UpdatedBy:     model.UpdatedBy,
+		CreatedAt:     model.CreatedTime,
+		UpdatedAt:     model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.MergeFields), &template.MergeFields)
+	_ = json.Unmarshal([]byte(model.Mappings), &template.Mappings)
+	if template.Mappings == nil {
+		template.Mappings = map[string]string{}
+	}
+	return template
+}
+
+func fromDocumentJobModel(model *models.DocumentJob) *doctemplate.Job {
+	job := &doctemplate.Job{
+		ID:         model.ID,
+		TemplateID: model.TemplateID,
+		TableID:    model.TableID,
+		ViewID:     model.ViewID,
+		TimeZone:   model.TimeZone,
+		Locale:     model.Locale,
+		Source:     model.Source,
+		Status:     doctemplate.Status(model.Status),
+		Total:      model.Total,
+		Generated:  model.Generated,
+		Failed:     model.Failed,
+		Truncated:  model.Truncated,
+		Error:      model.Error,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+	_ = json.Unmarshal([]byte(model.RecordIDs), &job.RecordIDs)
+	_ = json.Unmarshal([]byte(model.Results), &job.Results)
+	return job
+}
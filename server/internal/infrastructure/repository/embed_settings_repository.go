@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/embed"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// EmbedSettingsRepositoryImpl 工作空间嵌入设置仓储GORM实现
+type EmbedSettingsRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewEmbedSettingsRepository 创建嵌入设置仓储
+func NewEmbedSettingsRepository(db *gorm.DB) embed.Repository {
+	return &EmbedSettingsRepositoryImpl{db: db}
+}
+
+// GetSettings 获取空间嵌入设置
+func (r *EmbedSettingsRepositoryImpl) GetSettings(ctx context.Context, spaceID string) (*embed.Settings, error) {
+	var model models.SpaceEmbedSettings
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embed settings: %w", err)
+	}
+
+	origins := model.AllowedOrigins
+	if origins == nil {
+		origins = []string{}
+	}
+	return &embed.Settings{
+		SpaceID:            model.SpaceID,
+		Enabled:            model.Enabled,
+		AllowedOrigins:     origins,
+		MaxTokenTTLMinutes: model.MaxTokenTTLMinutes,
+		TokenVersion:       model.TokenVersion,
+		UpdatedBy:          model.UpdatedBy,
+		UpdatedTime:        model.UpdatedTime,
+	}, nil
+}
+
+// SaveSettings 保存空间嵌入设置
+func (r *EmbedSettingsRepositoryImpl) SaveSettings(ctx context.Context, settings *embed.Settings) error {
+	model := models.SpaceEmbedSettings{
+		SpaceID:            settings.SpaceID,
+		Enabled:            settings.Enabled,
+		AllowedOrigins:     settings.AllowedOrigins,
+		MaxTokenTTLMinutes: settings.MaxTokenTTLMinutes,
+		TokenVersion:       settings.TokenVersion,
+		UpdatedBy:          settings.UpdatedBy,
+		UpdatedTime:        settings.UpdatedTime,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save embed settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/externalsync"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// ExternalSyncRepositoryImpl 外部数据同步GORM实现
+type ExternalSyncRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewExternalSyncRepository 创建外部数据同步仓储
+func NewExternalSyncRepository(db *gorm.DB) externalsync.Repository {
+	return &ExternalSyncRepositoryImpl{db: db}
+}
+
+// Save 创建或更新连接器配置
+// 同步进度（cursor、mappings、last_* 等）只由 SaveProgress 写入，避免修改配置时回退并发运行的进度
+func (r *ExternalSyncRepositoryImpl) Save(ctx context.Context, connector *externalsync.Connector) error {
+	model, err := toExternalSyncConnectorModel(connector)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "credentials", "interval_seconds", "enabled", "next_run_time", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save external sync connector: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取连接器
+func (r *ExternalSyncRepositoryImpl) FindByID(ctx context.Context, id string) (*externalsync.Connector, error) {
+	return r.take(ctx, "id = ?", id)
+}
+
+// FindByTargetTable 获取写入该表的连接器
+func (r *ExternalSyncRepositoryImpl) FindByTargetTable(ctx context.Context, tableID string) (*externalsync.Connector, error) {
+	return r.take(ctx, "target_table_id = ?", tableID)
+}
+
+func (r *ExternalSyncRepositoryImpl) take(ctx context.Context, query string, arg interface{}) (*externalsync.Connector, error) {
+	var model models.ExternalSyncConnector
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external sync connector: %w", err)
+	}
+	return fromExternalSyncConnectorModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的连接器
+func (r *ExternalSyncRepositoryImpl) ListByBase(ctx context.Context, baseID string) ([]*externalsync.Connector, error) {
+	var list []models.ExternalSyncConnector
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list external sync connectors: %w", err)
+	}
+	connectors := make([]*externalsync.Connector, 0, len(list))
+	for i := range list {
+		connectors = append(connectors, fromExternalSyncConnectorModel(&list[i]))
+	}
+	return connectors, nil
+}
+
+// Delete 删除连接器、运行记录及行对应关系
+func (r *ExternalSyncRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("connector_id = ?", id).Delete(&models.ExternalSyncRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("connector_id = ?", id).Delete(&models.ExternalSyncRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.ExternalSyncConnector{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete external sync connector: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取到期连接器（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *ExternalSyncRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*externalsync.Connector, error) {
+	var claimed []*externalsync.Connector
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.ExternalSyncConnector{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.ExternalSyncConnector
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.ExternalSyncConnector{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromExternalSyncConnectorModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim external sync connectors: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存同步进度并释放租约
+func (r *ExternalSyncRepositoryImpl) SaveProgress(ctx context.Context, connector *externalsync.Connector) error {
+	mappings, err := json.Marshal(connector.Mappings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal external sync mappings: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Model(&models.ExternalSyncConnector{}).
+		Where("id = ?", connector.ID).
+		Updates(map[string]interface{}{
+			"cursor":           connector.Cursor,
+			"cursor_key":       connector.CursorKey,
+			"last_full_sync":   connector.LastFullSyncAt,
+			"mappings":         string(mappings),
+			"deleted_field_id": connector.DeletedFieldID,
+			"next_run_time":    connector.NextRunAt,
+			"last_run_time":    connector.LastRunAt,
+			"last_status":      string(connector.LastStatus),
+			"last_error":       connector.LastError,
+			"locked_until":     nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save external sync progress: %w", err)
+	}
+	return nil
+}
+
+// SaveRun 创建或更新运行记录
+func (r *ExternalSyncRepositoryImpl) SaveRun(ctx context.Context, run *externalsync.Run) error {
+	model := models.ExternalSyncRun{
+		ID:            run.ID,
+		ConnectorID:   run.ConnectorID,
+		Status:        string(run.Status),
+		Full:          run.Full,
+		Fetched:       run.Fetched,
+		Created:       run.Created,
+		Updated:       run.Updated,
+		Removed:       run.Removed,
+		Error:         run.Error,
+		CursorAtStart: run.CursorAtStart,
+		CursorAtEnd:   run.CursorAtEnd,
+		StartedTime:   run.StartedAt,
+		FinishedTime:  run.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save external sync run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns 按开始时间倒序列出运行记录
+func (r *ExternalSyncRepositoryImpl) ListRuns(ctx context.Context, connectorID string, limit int) ([]*externalsync.Run, error) {
+	var list []models.ExternalSyncRun
+	if err := r.db.WithContext(ctx).
+		Where("connector_id = ?", connectorID).
+		Order("started_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list external sync runs: %w", err)
+	}
+	runs := make([]*externalsync.Run, 0, len(list))
+	for i := range list {
+		model := &list[i]
+		runs = append(runs, &externalsync.Run{
+			ID:            model.ID,
+			ConnectorID:   model.ConnectorID,
+			Status:        externalsync.RunStatus(model.Status),
+			Full:          model.Full,
+			Fetched:       model.Fetched,
+			Created:       model.Created,
+			Updated:       model.Updated,
+			Removed:       model.Removed,
+			Error:         model.Error,
+			CursorAtStart: model.CursorAtStart,
+			CursorAtEnd:   model.CursorAtEnd,
+			StartedAt:     model.StartedTime,
+			FinishedAt:    model.FinishedTime,
+		})
+	}
+	return runs, nil
+}
+
+// PurgeRuns 只保留最近 keep 条运行记录
+func (r *ExternalSyncRepositoryImpl) PurgeRuns(ctx context.Context, connectorID string, keep int) error {
+	keepIDs := r.db.Model(&models.ExternalSyncRun{}).
+		Select("id").
+		Where("connector_id = ?", connectorID).
+		Order("started_time DESC").
+		Limit(keep)
+	if err := r.db.WithContext(ctx).
+		Where("connector_id = ? AND id NOT IN (?)", connectorID, keepIDs).
+		Delete(&models.ExternalSyncRun{}).Error; err != nil {
+		return fmt.Errorf("failed to purge external sync runs: %w", err)
+	}
+	return nil
+}
+
+// FindRows 获取外部行对应的目标记录
+func (r *ExternalSyncRepositoryImpl) FindRows(ctx context.Context, connectorID string, keys []string) ([]*externalsync.Row, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	var list []models.ExternalSyncRow
+	if err := r.db.WithContext(ctx).
+		Where("connector_id = ? AND external_key IN ?", connectorID, keys).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to find external sync rows: %w", err)
+	}
+	return fromExternalSyncRowModels(list), nil
+}
+
+// SaveRows 创建或更新行对应关系
+func (r *ExternalSyncRepositoryImpl) SaveRows(ctx context.Context, rows []*externalsync.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	list := make([]models.ExternalSyncRow, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, models.ExternalSyncRow{
+			ConnectorID:    row.ConnectorID,
+			ExternalKey:    row.ExternalKey,
+			TargetRecordID: row.TargetRecordID,
+			SourceDeleted:  row.SourceDeleted,
+			SyncedTime:     row.SyncedAt,
+		})
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "connector_id"}, {Name: "external_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_record_id", "source_deleted", "synced_time"}),
+	}).CreateInBatches(list, 500).Error; err != nil {
+		return fmt.Errorf("failed to save external sync rows: %w", err)
+	}
+	return nil
+}
+
+// ListStaleRows 列出 before 之前写入且未标记删除的对应关系
+func (r *ExternalSyncRepositoryImpl) ListStaleRows(ctx context.Context, connectorID string, before time.Time, limit int) ([]*externalsync.Row, error) {
+	var list []models.ExternalSyncRow
+	if err := r.db.WithContext(ctx).
+		Where("connector_id = ? AND synced_time < ? AND source_deleted = ?", connectorID, before, false).
+		Order("external_key ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stale external sync rows: %w", err)
+	}
+	return fromExternalSyncRowModels(list), nil
+}
+
+func toExternalSyncConnectorModel(connector *externalsync.Connector) (models.ExternalSyncConnector, error) {
+	source, err := json.Marshal(connector.Source)
+	if err != nil {
+		return models.ExternalSyncConnector{}, fmt.Errorf("failed to marshal external sync source: %w", err)
+	}
+	mappings, err := json.Marshal(connector.Mappings)
+	if err != nil {
+		return models.ExternalSyncConnector{}, fmt.Errorf("failed to marshal external sync mappings: %w", err)
+	}
+	return models.ExternalSyncConnector{
+		ID:              connector.ID,
+		BaseID:          connector.BaseID,
+		Name:            connector.Name,
+		Source:          string(source),
+		Credentials:     connector.Credentials,
+		Mappings:        string(mappings),
+		TargetTableID:   connector.TargetTableID,
+		DeletedFieldID:  connector.DeletedFieldID,
+		IntervalSeconds: int64(connector.Interval / time.Second),
+		Enabled:         connector.Enabled,
+		Cursor:          connector.Cursor,
+		CursorKey:       connector.CursorKey,
+		LastFullSync:    connector.LastFullSyncAt,
+		NextRunTime:     connector.NextRunAt,
+		LastRunTime:     connector.LastRunAt,
+		LastStatus:      string(connector.LastStatus),
+		LastError:       connector.LastError,
+		CreatedBy:       connector.CreatedBy,
+		CreatedTime:     connector.CreatedAt,
+		UpdatedTime:     connector.UpdatedAt,
+	}, nil
+}
+
+func fromExternalSyncConnectorModel(model *models.ExternalSyncConnector) *externalsync.Connector {
+	connector := &externalsync.Connector{
+		ID:             model.ID,
+		BaseID:         model.BaseID,
+		Name:           model.Name,
+		Credentials:    model.Credentials,
+		TargetTableID:  model.TargetTableID,
+		DeletedFieldID: model.DeletedFieldID,
+		Interval:       time.Duration(model.IntervalSeconds) * time.Second,
+		Enabled:        model.Enabled,
+		Cursor:         model.Cursor,
+		CursorKey:      model.CursorKey,
+		LastFullSyncAt: model.LastFullSync,
+		NextRunAt:      model.NextRunTime,
+		LastRunAt:      model.LastRunTime,
+		LastStatus:     externalsync.RunStatus(model.LastStatus),
+		LastError:      model.LastError,
+		CreatedBy:      model.CreatedBy,
+		CreatedAt:      model.CreatedTime,
+		UpdatedAt:      model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Source), &connector.Source)
+	if model.Mappings != "" {
+		_ = json.Unmarshal([]byte(model.Mappings), &connector.Mappings)
+	}
+	return connector
+}
+
+func fromExternalSyncRowModels(list []models.ExternalSyncRow) []*externalsync.Row {
+	rows := make([]*externalsync.Row, 0, len(list))
+	for i := range list {
+		rows = append(rows, &externalsync.Row{
+			ConnectorID:    list[i].ConnectorID,
+			ExternalKey:    list[i].ExternalKey,
+			TargetRecordID: list[i].TargetRecordID,
+			SourceDeleted:  list[i].SourceDeleted,
+			SyncedAt:       list[i].SyncedTime,
+		})
+	}
+	return rows
+}
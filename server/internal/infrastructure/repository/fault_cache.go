@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
+)
+
+// FaultInjectingCache 带故障注入的缓存包装器 ✨
+// 操作名为 get / set / delete / invalidate，目标为缓存键（invalidate 为模式）；
+// 批量删除时逐个键判断，部分键注入失败时其余键照常删除并返回错误，模拟部分失败
+type FaultInjectingCache struct {
+	inner    CacheProvider
+	injector *faultinject.Injector
+}
+
+// NewFaultInjectingCache 创建带故障注入的缓存包装器
+func NewFaultInjectingCache(inner CacheProvider, injector *faultinject.Injector) *FaultInjectingCache {
+	return &FaultInjectingCache{inner: inner, injector: injector}
+}
+
+// Get 读取缓存
+func (c *FaultInjectingCache) Get(ctx context.Context, key string, dest interface{}) error {
+	if err := c.injector.Inject(ctx, "get", key); err != nil {
+		return err
+	}
+	return c.inner.Get(ctx, key, dest)
+}
+
+// Set 写入缓存
+func (c *FaultInjectingCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := c.injector.Inject(ctx, "set", key); err != nil {
+		return err
+	}
+	return c.inner.Set(ctx, key, value, ttl)
+}
+
+// Delete 删除缓存（逐键注入）
+func (c *FaultInjectingCache) Delete(ctx context.Context, keys ...string) error {
+	var injected error
+	remaining := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := c.injector.Inject(ctx, "delete", key); err != nil {
+			if injected == nil {
+				injected = err
+			}
+			continue
+		}
+		remaining = append(remaining, key)
+	}
+	if len(remaining) > 0 {
+		if err := c.inner.Delete(ctx, remaining...); err != nil {
+			return err
+		}
+	}
+	return injected
+}
+
+// InvalidatePattern 按模式删除缓存
+func (c *FaultInjectingCache) InvalidatePattern(ctx context.Context, pattern string) error {
+	if err := c.injector.Inject(ctx, "invalidate", pattern); err != nil {
+		return err
+	}
+	return c.inner.InvalidatePattern(ctx, pattern)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -34,17 +35,17 @@ func (r *FieldRepositoryImpl) Save(ctx context.Context, field *entity.Field) err
 
 	// 检查是否已存在
 	var existing models.Field
-	err = r.db.WithContext(ctx).Where("id = ?", dbField.ID).First(&existing).Error
+	err = database.WithTx(ctx, r.db).WithContext(ctx).Where("id = ?", dbField.ID).First(&existing).Error
 
 	if err == gorm.ErrRecordNotFound {
 		// 创建新字段
-		return r.db.WithContext(ctx).Create(dbField).Error
+		return database.WithTx(ctx, r.db).WithContext(ctx).Create(dbField).Error
 	} else if err != nil {
 		return fmt.Errorf("failed to check existing field: %w", err)
 	}
 
 	// 更新现有字段
-	return r.db.WithContext(ctx).Model(&models.Field{}).
+	return database.WithTx(ctx, r.db).WithContext(ctx).Model(&models.Field{}).
 		Where("id = ?", dbField.ID).
 		Updates(dbField).Error
 }
@@ -58,7 +59,7 @@ func (r *FieldRepositoryImpl) FindByID(ctx context.Context, id valueobject.Field
 		logger.String("field_id", fieldIDStr))
 
 	// ✅ 显式指定 schema
-	err := r.db.WithContext(ctx).
+	err := database.WithTx(ctx, r.db).WithContext(ctx).
 		Table("field").
 		Where("id = ?", fieldIDStr).
 		Where("deleted_time IS NULL").
@@ -181,7 +182,7 @@ func (r *FieldRepositoryImpl) FindByName(ctx context.Context, tableID string, na
 
 // Delete 删除字段（软删除）
 func (r *FieldRepositoryImpl) Delete(ctx context.Context, id valueobject.FieldID) error {
-	return r.db.WithContext(ctx).
+	return database.WithTx(ctx, r.db).WithContext(ctx).
 		Model(&models.Field{}).
 		Where("id = ?", id.String()).
 		Update("deleted_time", gorm.Expr("NOW()")).Error
@@ -295,6 +296,33 @@ func (r *FieldRepositoryImpl) GetFieldsByType(ctx context.Context, tableID strin
 	return mapper.ToFieldList(dbFields)
 }
 
+// FindLinkFieldsTo 查找指向 linkedTableID 的全部关联字段
+// options 以文本存储，先按表ID模糊匹配缩小范围，再按解析后的关联配置精确过滤
+func (r *FieldRepositoryImpl) FindLinkFieldsTo(ctx context.Context, linkedTableID string) ([]*entity.Field, error) {
+	var dbFields []*models.Field
+
+	err := r.db.WithContext(ctx).
+		Where("type = ?", valueobject.TypeLink).
+		Where("options LIKE ?", "%"+linkedTableID+"%").
+		Where("deleted_time IS NULL").
+		Find(&dbFields).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find link fields: %w", err)
+	}
+
+	fields, err := mapper.ToFieldList(dbFields)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.Field, 0, len(fields))
+	for _, field := range fields {
+		if options := field.Options(); options != nil && options.Link != nil && options.Link.LinkedTableID == linkedTableID {
+			result = append(result, field)
+		}
+	}
+	return result, nil
+}
+
 // GetVirtualFields 获取表的所有虚拟字段
 func (r *FieldRepositoryImpl) GetVirtualFields(ctx context.Context, tableID string) ([]*entity.Field, error) {
 	// 虚拟字段包括：formula, rollup, lookup 等计算字段
@@ -381,6 +409,32 @@ func (r *FieldRepositoryImpl) UpdateOrder(ctx context.Context, fieldID valueobje
 		Update("field_order", order).Error
 }
 
+// BatchUpdateOrder 按给定顺序一次性重排表中的字段
+// 只更新属于该表且未删除的字段，任一字段不存在时整体回滚
+func (r *FieldRepositoryImpl) BatchUpdateOrder(ctx context.Context, tableID string, fieldIDs []valueobject.FieldID) error {
+	if len(fieldIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range fieldIDs {
+			result := tx.Model(&models.Field{}).
+				Where("id = ? AND table_id = ? AND deleted_time IS NULL", id.String(), tableID).
+				Updates(map[string]interface{}{
+					"field_order":        float64(i),
+					"last_modified_time": now,
+				})
+			if result.Error != nil {
+				return fmt.Errorf("failed to update field order: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("field %s not found in table %s", id.String(), tableID)
+			}
+		}
+		return nil
+	})
+}
+
 // GetMaxOrder 获取表中字段的最大order值（参考原系统实现）
 func (r *FieldRepositoryImpl) GetMaxOrder(ctx context.Context, tableID string) (float64, error) {
 	var result struct {
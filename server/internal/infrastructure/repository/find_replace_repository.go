@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/findreplace"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// findReplaceChangeBatchSize 批量写入变更记录时每批的行数
+const findReplaceChangeBatchSize = 500
+
+// FindReplaceRepositoryImpl 查找替换变更记录GORM实现
+type FindReplaceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewFindReplaceRepository 创建查找替换变更记录仓储
+func NewFindReplaceRepository(db *gorm.DB) findreplace.Repository {
+	return &FindReplaceRepositoryImpl{db: db}
+}
+
+// SaveChanges 批量写入变更记录（操作中断后重新执行时保留首次记录的替换前值）
+func (r *FindReplaceRepositoryImpl) SaveChanges(ctx context.Context, changes []*findreplace.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	list := make([]models.FindReplaceChange, 0, len(changes))
+	for _, change := range changes {
+		before, err := json.Marshal(change.Before)
+		if err != nil {
+			return fmt.Errorf("failed to marshal find replace change: %w", err)
+		}
+		after, err := json.Marshal(change.After)
+		if err != nil {
+			return fmt.Errorf("failed to marshal find replace change: %w", err)
+		}
+		list = append(list, models.FindReplaceChange{
+			OperationID: change.OperationID,
+			RecordID:    change.RecordID,
+			TableID:     change.TableID,
+			Before:      string(before),
+			After:       string(after),
+			CreatedTime: change.CreatedAt,
+		})
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&list, findReplaceChangeBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to save find replace changes: %w", err)
+	}
+	return nil
+}
+
+// ListChanges 按记录ID顺序分页列出变更记录
+func (r *FindReplaceRepositoryImpl) ListChanges(ctx context.Context, operationID, afterRecordID string, limit int) ([]*findreplace.Change, error) {
+	var list []models.FindReplaceChange
+	if err := r.db.WithContext(ctx).
+		Where("operation_id = ? AND record_id > ?", operationID, afterRecordID).
+		Order("record_id").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list find replace changes: %w", err)
+	}
+	changes := make([]*findreplace.Change, 0, len(list))
+	for i := range list {
+		change := &findreplace.Change{
+			OperationID: list[i].OperationID,
+			TableID:     list[i].TableID,
+			RecordID:    list[i].RecordID,
+			CreatedAt:   list[i].CreatedTime,
+		}
+		if err := json.Unmarshal([]byte(list[i].Before), &change.Before); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal find replace change: %w", err)
+		}
+		if err := json.Unmarshal([]byte(list[i].After), &change.After); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal find replace change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// CountChanges 操作的变更记录数
+func (r *FindReplaceRepositoryImpl) CountChanges(ctx context.Context, operationID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.FindReplaceChange{}).
+		Where("operation_id = ?", operationID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count find replace changes: %w", err)
+	}
+	return count, nil
+}
+
+// ClaimUndo 登记撤销操作（主键冲突说明已被登记，同一撤销操作重复登记视为成功）
+func (r *FindReplaceRepositoryImpl) ClaimUndo(ctx context.Context, operationID, undoOperationID string) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.FindReplaceUndo{
+		OperationID:     operationID,
+		UndoOperationID: undoOperationID,
+		CreatedTime:     time.Now(),
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim find replace undo: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+	existing, err := r.FindUndo(ctx, operationID)
+	if err != nil {
+		return false, err
+	}
+	return existing == undoOperationID, nil
+}
+
+// FindUndo 查找替换操作对应的撤销操作ID
+func (r *FindReplaceRepositoryImpl) FindUndo(ctx context.Context, operationID string) (string, error) {
+	var model models.FindReplaceUndo
+	err := r.db.WithContext(ctx).Where("operation_id = ?", operationID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get find replace undo: %w", err)
+	}
+	return model.UndoOperationID, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/idempotency"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// IdempotencyRepositoryImpl 幂等记录GORM实现
+type IdempotencyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyRepository 创建幂等记录仓储（数据库存储）
+func NewIdempotencyRepository(db *gorm.DB) idempotency.Store {
+	return &IdempotencyRepositoryImpl{db: db}
+}
+
+// Reserve 占用幂等键（主键冲突时返回已有记录；已有记录过期则删除后重新占用）
+func (r *IdempotencyRepositoryImpl) Reserve(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		model := toIdempotencyModel(record)
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return nil, nil
+		}
+
+		var existing models.IdempotencyKey
+		err := r.db.WithContext(ctx).
+			Where("scope = ? AND idempotency_key = ?", record.Scope, record.Key).
+			Take(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			continue // 已被并发释放，重新占用
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		if !existing.ExpiresAt.After(record.CreatedAt) {
+			if err := r.db.WithContext(ctx).
+				Where("scope = ? AND idempotency_key = ? AND expires_at <= ?", record.Scope, record.Key, record.CreatedAt).
+				Delete(&models.IdempotencyKey{}).Error; err != nil {
+				return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+			}
+			continue
+		}
+		return fromIdempotencyModel(&existing), nil
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key: concurrent modification")
+}
+
+// Extend 延长处理中记录的过期时间（条件更新：仍由 record.Token 占用）
+func (r *IdempotencyRepositoryImpl) Extend(ctx context.Context, record *idempotency.Record) error {
+	return r.updateHeld(ctx, record, map[string]interface{}{"expires_at": record.ExpiresAt})
+}
+
+// Complete 保存响应（条件更新：仍由 record.Token 占用）
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, record *idempotency.Record) error {
+	return r.updateHeld(ctx, record, map[string]interface{}{
+		"status":       string(idempotency.StatusCompleted),
+		"status_code":  record.StatusCode,
+		"content_type": record.ContentType,
+		"body":         record.Body,
+		"body_omitted": record.BodyOmitted,
+		"expires_at":   record.ExpiresAt,
+	})
+}
+
+// updateHeld 更新仍由 record.Token 占用的处理中记录，未更新任何行时返回 ErrLockLost
+func (r *IdempotencyRepositoryImpl) updateHeld(ctx context.Context, record *idempotency.Record, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("scope = ? AND idempotency_key = ? AND token = ? AND status = ?",
+			record.Scope, record.Key, record.Token, string(idempotency.StatusProcessing)).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update idempotency key: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return idempotency.ErrLockLost
+	}
+	return nil
+}
+
+// Release 释放处理中的幂等键
+func (r *IdempotencyRepositoryImpl) Release(ctx context.Context, record *idempotency.Record) error {
+	if err := r.db.WithContext(ctx).
+		Where("scope = ? AND idempotency_key = ? AND token = ? AND status = ?",
+			record.Scope, record.Key, record.Token, string(idempotency.StatusProcessing)).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired 删除过期记录
+func (r *IdempotencyRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", before).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func toIdempotencyModel(record *idempotency.Record) models.IdempotencyKey {
+	return models.IdempotencyKey{
+		Scope:       record.Scope,
+		Key:         record.Key,
+		Fingerprint: record.Fingerprint,
+		Method:      record.Method,
+		Path:        record.Path,
+		Status:      string(record.Status),
+		Token:       record.Token,
+		StatusCode:  record.StatusCode,
+		ContentType: record.ContentType,
+		Body:        record.Body,
+		BodyOmitted: record.BodyOmitted,
+		CreatedTime: record.CreatedAt,
+		ExpiresAt:   record.ExpiresAt,
+	}
+}
+
+func fromIdempotencyModel(model *models.IdempotencyKey) *idempotency.Record {
+	return &idempotency.Record{
+		Scope:       model.Scope,
+		Key:         model.Key,
+		Fingerprint: model.Fingerprint,
+		Method:      model.Method,
+		Path:        model.Path,
+		Status:      idempotency.Status(model.Status),
+		Token:       model.Token,
+		StatusCode:  model.StatusCode,
+		ContentType: model.ContentType,
+		Body:        model.Body,
+		BodyOmitted: model.BodyOmitted,
+		CreatedAt:   model.CreatedTime,
+		ExpiresAt:   model.ExpiresAt,
+	}
+}
+
+// RedisIdempotencyStore 幂等记录Redis实现（依赖键的 TTL 自动过期）
+type RedisIdempotencyStore struct {
+	client *cache.RedisClient
+}
+
+// NewRedisIdempotencyStore 创建幂等记录存储（Redis）
+func NewRedisIdempotencyStore(client *cache.RedisClient) idempotency.Store {
+	return &RedisIdempotencyStore{client: client}
+}
+
+// Reserve 通过 SETNX 占用幂等键
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, record *idempotency.Record) (*idempotency.Record, error) {
+	key := idempotencyCacheKey(record.Scope, record.Key)
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.client.SetNX(ctx, key, record, time.Until(record.ExpiresAt))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if ok {
+			return nil, nil
+		}
+
+		var existing idempotency.Record
+		if err := s.client.Get(ctx, key, &existing); err != nil {
+			if errors.Is(err, cache.ErrCacheNotFound) {
+				continue // 在 SETNX 与 GET 之间过期或被释放
+			}
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		return &existing, nil
+	}
+	return nil, fmt.Errorf("failed to reserve idempotency key: concurrent modification")
+}
+
+// Extend 延长处理中记录的过期时间
+func (s *RedisIdempotencyStore) Extend(ctx context.Context, record *idempotency.Record) error {
+	return s.updateHeld(ctx, record, func(existing *idempotency.Record) interface{} {
+		existing.ExpiresAt = record.ExpiresAt
+		return existing
+	})
+}
+
+// Complete 保存响应并按保留时长重设过期时间
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, record *idempotency.Record) error {
+	return s.updateHeld(ctx, record, func(*idempotency.Record) interface{} { return record })
+}
+
+// Release 释放幂等键
+func (s *RedisIdempotencyStore) Release(ctx context.Context, record *idempotency.Record) error {
+	err := s.updateHeld(ctx, record, func(*idempotency.Record) interface{} { return nil })
+	if errors.Is(err, idempotency.ErrLockLost) {
+		return nil // 已过期、已被接管或已释放
+	}
+	return err
+}
+
+// updateHeld 比较并设置：仅当记录仍处理中且由 record.Token 占用时写入 next 返回的值（nil 表示删除）
+func (s *RedisIdempotencyStore) updateHeld(ctx context.Context, record *idempotency.Record, next func(existing *idempotency.Record) interface{}) error {
+	var existing idempotency.Record
+	err := s.client.CompareAndSet(ctx, idempotencyCacheKey(record.Scope, record.Key), &existing, func() (interface{}, time.Duration, error) {
+		if existing.Token != record.Token || existing.Completed() {
+			return nil, 0, idempotency.ErrLockLost
+		}
+		return next(&existing), time.Until(record.ExpiresAt), nil
+	})
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, idempotency.ErrLockLost), errors.Is(err, cache.ErrCacheNotFound), errors.Is(err, cache.ErrCacheConflict):
+		return idempotency.ErrLockLost
+	default:
+		return fmt.Errorf("failed to update idempotency key: %w", err)
+	}
+}
+
+// DeleteExpired Redis 键自动过期，无需清理
+func (s *RedisIdempotencyStore) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func idempotencyCacheKey(scope, key string) string {
+	return "idempotency:" + scope + ":" + key
+}
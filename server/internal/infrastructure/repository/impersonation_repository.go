@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/impersonation"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// ImpersonationRepositoryImpl 代入会话仓储GORM实现
+type ImpersonationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository 创建代入会话仓储
+func NewImpersonationRepository(db *gorm.DB) impersonation.Repository {
+	return &ImpersonationRepositoryImpl{db: db}
+}
+
+// Create 保存新会话
+func (r *ImpersonationRepositoryImpl) Create(ctx context.Context, session *impersonation.Session) error {
+	model := models.ImpersonationSession{
+		ID:            session.ID,
+		SupportUserID: session.SupportUserID,
+		SupportEmail:  session.SupportEmail,
+		TargetUserID:  session.TargetUserID,
+		TargetEmail:   session.TargetEmail,
+		Reason:        session.Reason,
+		TokenHash:     session.TokenHash,
+		ExpiresAt:     session.ExpiresAt,
+		CreatedAt:     session.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+	return nil
+}
+
+// FindByHash 按令牌摘要查找会话
+func (r *ImpersonationRepositoryImpl) FindByHash(ctx context.Context, tokenHash string) (*impersonation.Session, error) {
+	return r.take(ctx, "token_hash = ?", tokenHash)
+}
+
+// FindByID 按ID查找会话
+func (r *ImpersonationRepositoryImpl) FindByID(ctx context.Context, id string) (*impersonation.Session, error) {
+	return r.take(ctx, "id = ?", id)
+}
+
+// List 列出会话
+func (r *ImpersonationRepositoryImpl) List(ctx context.Context, filter impersonation.ListFilter) ([]*impersonation.Session, error) {
+	query := r.db.WithContext(ctx).Model(&models.ImpersonationSession{})
+	if filter.SupportUserID != "" {
+		query = query.Where("support_user_id = ?", filter.SupportUserID)
+	}
+	if filter.TargetUserID != "" {
+		query = query.Where("target_user_id = ?", filter.TargetUserID)
+	}
+	if filter.ActiveAt != nil {
+		query = query.Where("ended_at IS NULL AND expires_at > ?", *filter.ActiveAt)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var list []models.ImpersonationSession
+	if err := query.Order("created_at DESC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list impersonation sessions: %w", err)
+	}
+	sessions := make([]*impersonation.Session, 0, len(list))
+	for i := range list {
+		sessions = append(sessions, toImpersonationEntity(&list[i]))
+	}
+	return sessions, nil
+}
+
+// End 保存会话的结束时间与结束人
+func (r *ImpersonationRepositoryImpl) End(ctx context.Context, session *impersonation.Session) error {
+	err := r.db.WithContext(ctx).Model(&models.ImpersonationSession{}).
+		Where("id = ? AND ended_at IS NULL", session.ID).
+		Updates(map[string]interface{}{
+			"ended_at": session.EndedAt,
+			"ended_by": session.EndedBy,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to end impersonation session: %w", err)
+	}
+	return nil
+}
+
+// RecordEvent 写入会话开始、结束的审计日志
+func (r *ImpersonationRepositoryImpl) RecordEvent(ctx context.Context, session *impersonation.Session, event, actorID string) error {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"impersonated":     true,
+		"impersonation_id": session.ID,
+		"support_user_id":  session.SupportUserID,
+		"reason":           session.Reason,
+		"expires_at":       session.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal impersonation metadata: %w", err)
+	}
+
+	meta := string(metadata)
+	entry := models.AuditLog{
+		ID:           utils.GenerateIDWithPrefix("aud"),
+		Action:       event,
+		ResourceType: "user",
+		ResourceID:   &session.TargetUserID,
+		Description:  &session.Reason,
+		Status:       "success",
+		Severity:     "warning",
+		UserID:       &actorID,
+		SessionID:    &session.ID,
+		Metadata:     &meta,
+		CreatedTime:  time.Now(),
+	}
+	if session.TargetEmail != "" {
+		entry.ResourceName = &session.TargetEmail
+	}
+	return r.createAudit(ctx, &entry)
+}
+
+// RecordAction 写入代入期间请求的审计日志
+// 审计日志的用户为被代入的用户，元数据标记 impersonated 与实际操作的支持人员
+func (r *ImpersonationRepositoryImpl) RecordAction(ctx context.Context, action *impersonation.Action) error {
+	metadata, err := json.Marshal(map[string]interface{}{
+		"impersonated":     true,
+		"impersonation_id": action.SessionID,
+		"support_user_id":  action.SupportUserID,
+		"method":           action.Method,
+		"path":             action.Path,
+		"route":            action.Route,
+		"status_code":      action.Status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal impersonation metadata: %w", err)
+	}
+
+	meta := string(metadata)
+	description := action.Method + " " + action.Path
+	duration := action.Duration.Milliseconds()
+	entry := models.AuditLog{
+		ID:           utils.GenerateIDWithPrefix("aud"),
+		Action:       "impersonated_request",
+		ResourceType: "api",
+		Description:  &description,
+		Status:       "success",
+		Severity:     "info",
+		UserID:       &action.TargetUserID,
+		SessionID:    &action.SessionID,
+		Metadata:     &meta,
+		Duration:     &duration,
+		CreatedTime:  action.OccurredAt,
+	}
+	if action.TargetEmail != "" {
+		entry.UserEmail = &action.TargetEmail
+	}
+	if action.IPAddress != "" {
+		entry.IPAddress = &action.IPAddress
+	}
+	if action.UserAgent != "" {
+		entry.UserAgent = &action.UserAgent
+	}
+	switch {
+	case action.Blocked:
+		code := "IMPERSONATION_BLOCKED"
+		entry.Status = "failure"
+		entry.Severity = "warning"
+		entry.ErrorCode = &code
+	case action.Status >= http.StatusBadRequest:
+		entry.Status = "failure"
+	}
+	return r.createAudit(ctx, &entry)
+}
+
+// NotifyTarget 通知被代入的用户
+func (r *ImpersonationRepositoryImpl) NotifyTarget(ctx context.Context, session *impersonation.Session) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"impersonationId": session.ID,
+		"supportEmail":    session.SupportEmail,
+		"reason":          session.Reason,
+		"expiresAt":       session.ExpiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification data: %w", err)
+	}
+
+	notification := models.Notification{
+		ID:     utils.GenerateNanoID(10),
+		UserID: session.TargetUserID,
+		Type:   "system",
+		Title:  "支持人员正在以你的身份访问",
+		Content: fmt.Sprintf("支持人员 %s 于 %s 开始以你的身份访问，将于 %s 前结束。原因：%s",
+			session.SupportEmail,
+			session.CreatedAt.Format(time.RFC3339),
+			session.ExpiresAt.Format(time.RFC3339),
+			session.Reason),
+		Data:       string(data),
+		Status:     "unread",
+		Priority:   "high",
+		SourceID:   session.ID,
+		SourceType: "impersonation",
+	}
+	if err := r.db.WithContext(ctx).Create(&notification).Error; err != nil {
+		return fmt.Errorf("failed to notify impersonated user: %w", err)
+	}
+	return nil
+}
+
+func (r *ImpersonationRepositoryImpl) createAudit(ctx context.Context, entry *models.AuditLog) error {
+	if err := r.db.WithContext(ctx).Omit("User", "Organization", "Space", "Base", "Table", "Record", "Field").
+		Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record impersonation audit log: %w", err)
+	}
+	return nil
+}
+
+func (r *ImpersonationRepositoryImpl) take(ctx context.Context, query string, arg interface{}) (*impersonation.Session, error) {
+	var model models.ImpersonationSession
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get impersonation session: %w", err)
+	}
+	return toImpersonationEntity(&model), nil
+}
+
+func toImpersonationEntity(model *models.ImpersonationSession) *impersonation.Session {
+	return &impersonation.Session{
+		ID:            model.ID,
+		SupportUserID: model.SupportUserID,
+		SupportEmail:  model.SupportEmail,
+		TargetUserID:  model.TargetUserID,
+		TargetEmail:   model.TargetEmail,
+		Reason:        model.Reason,
+		TokenHash:     model.TokenHash,
+		ExpiresAt:     model.ExpiresAt,
+		EndedAt:       model.EndedAt,
+		EndedBy:       model.EndedBy,
+		CreatedAt:     model.CreatedAt,
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// IntegrationDeliveryRepositoryImpl REST Hook 失败推送GORM实现
+type IntegrationDeliveryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewIntegrationDeliveryRepository 创建失败推送仓储
+func NewIntegrationDeliveryRepository(db *gorm.DB) integration.DeliveryRepository {
+	return &IntegrationDeliveryRepositoryImpl{db: db}
+}
+
+// RecordFailure 记录失败推送（同一批记录反复失败时累加尝试次数）
+func (r *IntegrationDeliveryRepositoryImpl) RecordFailure(ctx context.Context, delivery *integration.Delivery) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.IntegrationDelivery
+		err := tx.Where("hook_id = ? AND cursor_from = ? AND status = ?", delivery.HookID, delivery.CursorFrom, string(integration.DeliveryFailed)).
+			Take(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			model := toIntegrationDeliveryModel(delivery)
+			return tx.Create(&model).Error
+		}
+		if err != nil {
+			return err
+		}
+		delivery.ID = existing.ID
+		delivery.Attempts = existing.Attempts + 1
+		delivery.FirstFailedAt = existing.FirstFailedAt
+		return tx.Model(&models.IntegrationDelivery{}).
+			Where("id = ?", existing.ID).
+			Updates(map[string]interface{}{
+				"cursor_to":        delivery.CursorTo,
+				"item_count":       delivery.ItemCount,
+				"status_code":      delivery.StatusCode,
+				"error":            delivery.Error,
+				"attempts":         delivery.Attempts,
+				"last_failed_time": delivery.LastFailedAt,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record integration delivery failure: %w", err)
+	}
+	return nil
+}
+
+// Resolve 把游标之前的失败记录标记为已解决
+func (r *IntegrationDeliveryRepositoryImpl) Resolve(ctx context.Context, hookID string, cursor int64) error {
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationDelivery{}).
+		Where("hook_id = ? AND cursor_from < ? AND status IN ?", hookID, cursor,
+			[]string{string(integration.DeliveryFailed), string(integration.DeliveryRetried)}).
+		Update("status", string(integration.DeliveryResolved)).Error; err != nil {
+		return fmt.Errorf("failed to resolve integration deliveries: %w", err)
+	}
+	return nil
+}
+
+// List 按最近失败时间倒序列出
+func (r *IntegrationDeliveryRepositoryImpl) List(ctx context.Context, filter integration.DeliveryFilter) ([]*integration.Delivery, error) {
+	if len(filter.HookIDs) == 0 {
+		return []*integration.Delivery{}, nil
+	}
+	query := r.db.WithContext(ctx).Where("hook_id IN ?", filter.HookIDs)
+	if filter.Status != nil {
+		query = query.Where("status = ?", string(*filter.Status))
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var list []models.IntegrationDelivery
+	if err := query.Order("last_failed_time DESC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list integration deliveries: %w", err)
+	}
+	return fromIntegrationDeliveryModels(list), nil
+}
+
+// FindByIDs 批量获取
+func (r *IntegrationDeliveryRepositoryImpl) FindByIDs(ctx context.Context, ids []string) ([]*integration.Delivery, error) {
+	if len(ids) == 0 {
+		return []*integration.Delivery{}, nil
+	}
+	var list []models.IntegrationDelivery
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to get integration deliveries: %w", err)
+	}
+	return fromIntegrationDeliveryModels(list), nil
+}
+
+// MarkRetried 标记为已手动重试
+func (r *IntegrationDeliveryRepositoryImpl) MarkRetried(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationDelivery{}).
+		Where("id IN ? AND status = ?", ids, string(integration.DeliveryFailed)).
+		Updates(map[string]interface{}{
+			"status":       string(integration.DeliveryRetried),
+			"retried_time": at,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to mark integration deliveries retried: %w", err)
+	}
+	return nil
+}
+
+// CountFailed 统计订阅的失败记录数
+func (r *IntegrationDeliveryRepositoryImpl) CountFailed(ctx context.Context, hookIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(hookIDs))
+	if len(hookIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		HookID string
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationDelivery{}).
+		Select("hook_id, COUNT(*) AS count").
+		Where("hook_id IN ? AND status = ?", hookIDs, string(integration.DeliveryFailed)).
+		Group("hook_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count integration deliveries: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.HookID] = row.Count
+	}
+	return counts, nil
+}
+
+func toIntegrationDeliveryModel(d *integration.Delivery) models.IntegrationDelivery {
+	return models.IntegrationDelivery{
+		ID:            d.ID,
+		HookID:        d.HookID,
+		TableID:       d.TableID,
+		Event:         string(d.Event),
+		CursorFrom:    d.CursorFrom,
+		CursorTo:      d.CursorTo,
+		ItemCount:     d.ItemCount,
+		Status:        string(d.Status),
+		StatusCode:    d.StatusCode,
+		Error:         d.Error,
+		Attempts:      d.Attempts,
+		FirstFailedAt: d.FirstFailedAt,
+		LastFailedAt:  d.LastFailedAt,
+		RetriedAt:     d.RetriedAt,
+	}
+}
+
+func fromIntegrationDeliveryModels(list []models.IntegrationDelivery) []*integration.Delivery {
+	deliveries := make([]*integration.Delivery, 0, len(list))
+	for i := range list {
+		m := &list[i]
+		deliveries = append(deliveries, &integration.Delivery{
+			ID:            m.ID,
+			HookID:        m.HookID,
+			TableID:       m.TableID,
+			Event:         integration.Event(m.Event),
+			CursorFrom:    m.CursorFrom,
+			CursorTo:      m.CursorTo,
+			ItemCount:     m.ItemCount,
+			Status:        integration.DeliveryStatus(m.Status),
+			StatusCode:    m.StatusCode,
+			Error:         m.Error,
+			Attempts:      m.Attempts,
+			FirstFailedAt: m.FirstFailedAt,
+			LastFailedAt:  m.LastFailedAt,
+			RetriedAt:     m.RetriedAt,
+		})
+	}
+	return deliveries
+}
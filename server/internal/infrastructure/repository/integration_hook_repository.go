@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// IntegrationHookRepositoryImpl REST Hook 订阅GORM实现
+type IntegrationHookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewIntegrationHookRepository 创建 REST Hook 订阅仓储
+func NewIntegrationHookRepository(db *gorm.DB) integration.Repository {
+	return &IntegrationHookRepositoryImpl{db: db}
+}
+
+// Create 保存新订阅
+func (r *IntegrationHookRepositoryImpl) Create(ctx context.Context, hook *integration.Hook) error {
+	model := toIntegrationHookModel(hook)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create integration hook: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取订阅
+func (r *IntegrationHookRepositoryImpl) FindByID(ctx context.Context, id string) (*integration.Hook, error) {
+	var model models.IntegrationHook
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get integration hook: %w", err)
+	}
+	return fromIntegrationHookModel(&model), nil
+}
+
+// UpdateTemplate 更新订阅的输出模板
+func (r *IntegrationHookRepositoryImpl) UpdateTemplate(ctx context.Context, id, template string) error {
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationHook{}).
+		Where("id = ?", id).
+		Update("template", template).Error; err != nil {
+		return fmt.Errorf("failed to update integration hook template: %w", err)
+	}
+	return nil
+}
+
+// ListByCreator 列出用户创建的订阅
+func (r *IntegrationHookRepositoryImpl) ListByCreator(ctx context.Context, userID string) ([]*integration.Hook, error) {
+	var list []models.IntegrationHook
+	if err := r.db.WithContext(ctx).
+		Where("created_by = ?", userID).
+		Order("created_time DESC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list integration hooks: %w", err)
+	}
+	hooks := make([]*integration.Hook, 0, len(list))
+	for i := range list {
+		hooks = append(hooks, fromIntegrationHookModel(&list[i]))
+	}
+	return hooks, nil
+}
+
+// UpdateDeadLetter 更新暂停阈值与通知设置
+func (r *IntegrationHookRepositoryImpl) UpdateDeadLetter(ctx context.Context, hook *integration.Hook) error {
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationHook{}).
+		Where("id = ?", hook.ID).
+		Updates(map[string]interface{}{
+			"max_failures": hook.MaxFailures,
+			"notify_owner": hook.NotifyOwner,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update integration hook dead-letter settings: %w", err)
+	}
+	return nil
+}
+
+// Resume 恢复已暂停的订阅并释放租约
+func (r *IntegrationHookRepositoryImpl) Resume(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationHook{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"paused_time":  nil,
+			"failures":     0,
+			"locked_until": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to resume integration hook: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除订阅及其失败推送记录
+func (r *IntegrationHookRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hook_id = ?", id).Delete(&models.IntegrationDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.IntegrationHook{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete integration hook: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取订阅（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *IntegrationHookRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*integration.Hook, error) {
+	var claimed []*integration.Hook
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.IntegrationHook{}).
+			Where("paused_time IS NULL").
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("last_delivered_time ASC NULLS FIRST").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.IntegrationHook
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.IntegrationHook{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromIntegrationHookModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim integration hooks: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存推送进度并释放租约
+func (r *IntegrationHookRepositoryImpl) SaveProgress(ctx context.Context, hook *integration.Hook) error {
+	if err := r.db.WithContext(ctx).Model(&models.IntegrationHook{}).
+		Where("id = ?", hook.ID).
+		Updates(map[string]interface{}{
+			"cursor":              hook.Cursor,
+			"last_error":          hook.LastError,
+			"last_delivered_time": hook.LastDeliveredAt,
+			"failures":            hook.Failures,
+			"paused_time":         hook.PausedAt,
+			"locked_until":        nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save integration hook progress: %w", err)
+	}
+	return nil
+}
+
+func toIntegrationHookModel(hook *integration.Hook) models.IntegrationHook {
+	return models.IntegrationHook{
+		ID:              hook.ID,
+		BaseID:          hook.BaseID,
+		TableID:         hook.TableID,
+		Event:           string(hook.Event),
+		TargetURL:       hook.TargetURL,
+		Template:        hook.Template,
+		Cursor:          hook.Cursor,
+		LastError:       hook.LastError,
+		LastDeliveredAt: hook.LastDeliveredAt,
+		Failures:        hook.Failures,
+		MaxFailures:     hook.MaxFailures,
+		NotifyOwner:     hook.NotifyOwner,
+		PausedAt:        hook.PausedAt,
+		CreatedBy:       hook.CreatedBy,
+		CreatedTime:     hook.CreatedAt,
+	}
+}
+
+func fromIntegrationHookModel(model *models.IntegrationHook) *integration.Hook {
+	return &integration.Hook{
+		ID:              model.ID,
+		BaseID:          model.BaseID,
+		TableID:         model.TableID,
+		Event:           integration.Event(model.Event),
+		TargetURL:       model.TargetURL,
+		Template:        model.Template,
+		Cursor:          model.Cursor,
+		LastError:       model.LastError,
+		LastDeliveredAt: model.LastDeliveredAt,
+		Failures:        model.Failures,
+		MaxFailures:     model.MaxFailures,
+		NotifyOwner:     model.NotifyOwner,
+		PausedAt:        model.PausedAt,
+		CreatedBy:       model.CreatedBy,
+		CreatedAt:       model.CreatedTime,
+	}
+}
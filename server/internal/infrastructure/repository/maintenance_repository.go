@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/maintenance"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// MaintenanceRepositoryImpl 维护窗口GORM实现
+type MaintenanceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceRepository 创建维护窗口仓储
+func NewMaintenanceRepository(db *gorm.DB) maintenance.Repository {
+	return &MaintenanceRepositoryImpl{db: db}
+}
+
+// Create 保存新的维护窗口
+func (r *MaintenanceRepositoryImpl) Create(ctx context.Context, window *maintenance.Window) error {
+	model := models.MaintenanceWindow{
+		ID:          window.ID,
+		Scope:       string(window.Scope),
+		ScopeID:     window.ScopeID,
+		Message:     window.Message,
+		StartedBy:   window.StartedBy,
+		StartedTime: window.StartedAt,
+		EndsTime:    window.EndsAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取维护窗口
+func (r *MaintenanceRepositoryImpl) FindByID(ctx context.Context, id string) (*maintenance.Window, error) {
+	var model models.MaintenanceWindow
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance window: %w", err)
+	}
+	return fromMaintenanceWindowModel(&model), nil
+}
+
+// End 结束维护窗口（只更新尚未结束的）
+func (r *MaintenanceRepositoryImpl) End(ctx context.Context, window *maintenance.Window) error {
+	err := r.db.WithContext(ctx).Model(&models.MaintenanceWindow{}).
+		Where("id = ? AND ended_time IS NULL", window.ID).
+		Updates(map[string]interface{}{
+			"ended_by":   window.EndedBy,
+			"ended_time": window.EndedAt,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to end maintenance window: %w", err)
+	}
+	return nil
+}
+
+// ListActive 列出尚未结束的维护窗口
+func (r *MaintenanceRepositoryImpl) ListActive(ctx context.Context) ([]*maintenance.Window, error) {
+	var list []models.MaintenanceWindow
+	if err := r.db.WithContext(ctx).Where("ended_time IS NULL").Order("started_time ASC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	return fromMaintenanceWindowModels(list), nil
+}
+
+// ListRecent 按开始时间倒序列出最近的维护窗口
+func (r *MaintenanceRepositoryImpl) ListRecent(ctx context.Context, limit int) ([]*maintenance.Window, error) {
+	var list []models.MaintenanceWindow
+	if err := r.db.WithContext(ctx).Order("started_time DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	return fromMaintenanceWindowModels(list), nil
+}
+
+// EndExpired 结束计划结束时间已到的维护窗口
+// 逐个按 ended_time IS NULL 条件更新，只返回本次更新成功的窗口，多实例同时执行时不会重复结束
+func (r *MaintenanceRepositoryImpl) EndExpired(ctx context.Context, now time.Time) ([]*maintenance.Window, error) {
+	var list []models.MaintenanceWindow
+	if err := r.db.WithContext(ctx).
+		Where("ended_time IS NULL AND ends_time IS NOT NULL AND ends_time <= ?", now).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expired maintenance windows: %w", err)
+	}
+
+	var ended []*maintenance.Window
+	for i := range list {
+		result := r.db.WithContext(ctx).Model(&models.MaintenanceWindow{}).
+			Where("id = ? AND ended_time IS NULL", list[i].ID).
+			Update("ended_time", now)
+		if result.Error != nil {
+			return ended, fmt.Errorf("failed to end maintenance window: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		list[i].EndedTime = &now
+		ended = append(ended, fromMaintenanceWindowModel(&list[i]))
+	}
+	return ended, nil
+}
+
+func fromMaintenanceWindowModels(list []models.MaintenanceWindow) []*maintenance.Window {
+	windows := make([]*maintenance.Window, 0, len(list))
+	for i := range list {
+		windows = append(windows, fromMaintenanceWindowModel(&list[i]))
+	}
+	return windows
+}
+
+func fromMaintenanceWindowModel(model *models.MaintenanceWindow) *maintenance.Window {
+	return &maintenance.Window{
+		ID:        model.ID,
+		Scope:     maintenance.Scope(model.Scope),
+		ScopeID:   model.ScopeID,
+		Message:   model.Message,
+		StartedBy: model.StartedBy,
+		StartedAt: model.StartedTime,
+		EndsAt:    model.EndsTime,
+		EndedBy:   model.EndedBy,
+		EndedAt:   model.EndedTime,
+	}
+}
@@ -80,6 +80,13 @@ func ToFieldEntity(dbField *models.Field) (*entity.Field, error) {
 		field.UpdateDescription(*dbField.Description)
 	}
 
+	// API 键（升级前创建的字段在回填前为空）
+	if dbField.APIKey != nil && *dbField.APIKey != "" {
+		if apiKey, err := valueobject.NewAPIKey(*dbField.APIKey); err == nil {
+			field.SetAPIKey(apiKey)
+		}
+	}
+
 	// 设置约束
 	field.SetRequired(dbField.IsRequired)
 	field.SetUnique(dbField.IsUnique)
@@ -149,6 +156,12 @@ func ToFieldModel(field *entity.Field) (*models.Field, error) {
 		LastModifiedTime:    &updatedAt,
 	}
 
+	// API 键
+	if !field.APIKey().IsEmpty() {
+		apiKey := field.APIKey().String()
+		dbField.APIKey = &apiKey
+	}
+
 	// Description
 	if field.Description() != nil && *field.Description() != "" {
 		dbField.Description = field.Description()
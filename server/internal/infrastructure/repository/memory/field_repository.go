@@ -0,0 +1,264 @@
+// Package memory 字段与记录仓储的内存实现 ✨
+// 行为与 GORM / 动态表实现保持一致（由 repotest 契约测试约束），
+// 用于在没有数据库的情况下测试上层服务和缓存包装器
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+var (
+	_ repository.FieldRepository = (*FieldRepository)(nil)
+	_ repository.LinkFieldFinder = (*FieldRepository)(nil)
+)
+
+// FieldRepository 内存字段仓储
+// 保存与读取时都复制实体，调用方修改返回值不会影响已保存的数据（与数据库实现一致）
+type FieldRepository struct {
+	mu     sync.RWMutex
+	fields map[string]*entity.Field
+}
+
+// NewFieldRepository 创建内存字段仓储
+func NewFieldRepository() *FieldRepository {
+	return &FieldRepository{fields: make(map[string]*entity.Field)}
+}
+
+// cloneField 复制字段实体
+func cloneField(field *entity.Field) *entity.Field {
+	c := *field
+	return &c
+}
+
+// Save 保存字段（新增或更新）
+func (r *FieldRepository) Save(ctx context.Context, field *entity.Field) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fields[field.ID().String()] = cloneField(field)
+	return nil
+}
+
+// FindByID 根据ID查找字段（不存在时返回 nil）
+func (r *FieldRepository) FindByID(ctx context.Context, id valueobject.FieldID) (*entity.Field, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if field, ok := r.fields[id.String()]; ok {
+		return cloneField(field), nil
+	}
+	return nil, nil
+}
+
+// FindByTableID 查找表的所有字段（按 order 升序）
+func (r *FieldRepository) FindByTableID(ctx context.Context, tableID string) ([]*entity.Field, error) {
+	return r.filter(func(f *entity.Field) bool { return f.TableID() == tableID }), nil
+}
+
+// FindByName 根据表ID和字段名查找字段（不存在时返回 nil）
+func (r *FieldRepository) FindByName(ctx context.Context, tableID string, name valueobject.FieldName) (*entity.Field, error) {
+	matched := r.filter(func(f *entity.Field) bool {
+		return f.TableID() == tableID && f.Name().String() == name.String()
+	})
+	if len(matched) == 0 {
+		return nil, nil
+	}
+	return matched[0], nil
+}
+
+// Delete 删除字段
+func (r *FieldRepository) Delete(ctx context.Context, id valueobject.FieldID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.fields, id.String())
+	return nil
+}
+
+// Exists 检查字段是否存在
+func (r *FieldRepository) Exists(ctx context.Context, id valueobject.FieldID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.fields[id.String()]
+	return ok, nil
+}
+
+// ExistsByName 检查表中是否存在同名字段
+func (r *FieldRepository) ExistsByName(ctx context.Context, tableID string, name valueobject.FieldName, excludeID *valueobject.FieldID) (bool, error) {
+	matched := r.filter(func(f *entity.Field) bool {
+		if excludeID != nil && f.ID().String() == excludeID.String() {
+			return false
+		}
+		return f.TableID() == tableID && f.Name().String() == name.String()
+	})
+	return len(matched) > 0, nil
+}
+
+// List 列出字段（过滤条件与 GORM 实现一致：表、类型、名称包含、是否计算字段）
+func (r *FieldRepository) List(ctx context.Context, filter repository.FieldFilter) ([]*entity.Field, int64, error) {
+	fields := r.filter(func(f *entity.Field) bool {
+		if filter.TableID != nil && f.TableID() != *filter.TableID {
+			return false
+		}
+		if filter.FieldType != nil && !f.Type().Equals(*filter.FieldType) {
+			return false
+		}
+		if filter.Name != nil && !strings.Contains(f.Name().String(), *filter.Name) {
+			return false
+		}
+		if filter.IsComputed != nil && f.IsComputed() != *filter.IsComputed {
+			return false
+		}
+		return true
+	})
+	total := int64(len(fields))
+
+	if filter.OrderBy != "" {
+		less := func(a, b *entity.Field) bool { return a.Order() < b.Order() }
+		switch filter.OrderBy {
+		case "name":
+			less = func(a, b *entity.Field) bool { return a.Name().String() < b.Name().String() }
+		case "created_at", "created_time":
+			less = func(a, b *entity.Field) bool { return a.CreatedAt().Before(b.CreatedAt()) }
+		case "updated_at", "last_modified_time":
+			less = func(a, b *entity.Field) bool { return a.UpdatedAt().Before(b.UpdatedAt()) }
+		}
+		desc := filter.OrderDir == "desc"
+		sort.SliceStable(fields, func(i, j int) bool {
+			if desc {
+				return less(fields[j], fields[i])
+			}
+			return less(fields[i], fields[j])
+		})
+	}
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(fields) {
+			return []*entity.Field{}, total, nil
+		}
+		fields = fields[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(fields) {
+		fields = fields[:filter.Limit]
+	}
+	return fields, total, nil
+}
+
+// BatchSave 批量保存字段
+func (r *FieldRepository) BatchSave(ctx context.Context, fields []*entity.Field) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, field := range fields {
+		r.fields[field.ID().String()] = cloneField(field)
+	}
+	return nil
+}
+
+// BatchDelete 批量删除字段
+func (r *FieldRepository) BatchDelete(ctx context.Context, ids []valueobject.FieldID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		delete(r.fields, id.String())
+	}
+	return nil
+}
+
+// GetVirtualFields 获取表的所有虚拟字段（formula、rollup、lookup）
+func (r *FieldRepository) GetVirtualFields(ctx context.Context, tableID string) ([]*entity.Field, error) {
+	return r.filter(func(f *entity.Field) bool {
+		if f.TableID() != tableID {
+			return false
+		}
+		switch f.Type().String() {
+		case valueobject.TypeFormula, valueobject.TypeRollup, valueobject.TypeLookup:
+			return true
+		}
+		return false
+	}), nil
+}
+
+// GetComputedFields 获取表的所有计算字段
+func (r *FieldRepository) GetComputedFields(ctx context.Context, tableID string) ([]*entity.Field, error) {
+	return r.filter(func(f *entity.Field) bool { return f.TableID() == tableID && f.IsComputed() }), nil
+}
+
+// GetFieldsByType 根据字段类型查找字段
+func (r *FieldRepository) GetFieldsByType(ctx context.Context, tableID string, fieldType valueobject.FieldType) ([]*entity.Field, error) {
+	return r.filter(func(f *entity.Field) bool { return f.TableID() == tableID && f.Type().Equals(fieldType) }), nil
+}
+
+// FindLinkFieldsTo 查找指向 linkedTableID 的全部关联字段
+func (r *FieldRepository) FindLinkFieldsTo(ctx context.Context, linkedTableID string) ([]*entity.Field, error) {
+	return r.filter(func(f *entity.Field) bool {
+		options := f.Options()
+		return f.Type().String() == valueobject.TypeLink && options != nil && options.Link != nil &&
+			options.Link.LinkedTableID == linkedTableID
+	}), nil
+}
+
+// UpdateOrder 更新字段排序
+func (r *FieldRepository) UpdateOrder(ctx context.Context, fieldID valueobject.FieldID, order float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if field, ok := r.fields[fieldID.String()]; ok {
+		field.SetOrder(order)
+	}
+	return nil
+}
+
+// BatchUpdateOrder 按给定顺序重排表中的字段，任一字段不属于该表时不做任何修改
+func (r *FieldRepository) BatchUpdateOrder(ctx context.Context, tableID string, fieldIDs []valueobject.FieldID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range fieldIDs {
+		if field, ok := r.fields[id.String()]; !ok || field.TableID() != tableID {
+			return fmt.Errorf("field %s not found in table %s", id.String(), tableID)
+		}
+	}
+	for i, id := range fieldIDs {
+		r.fields[id.String()].SetOrder(float64(i))
+	}
+	return nil
+}
+
+// GetMaxOrder 获取表中字段的最大 order（没有字段时返回 -1）
+func (r *FieldRepository) GetMaxOrder(ctx context.Context, tableID string) (float64, error) {
+	fields := r.filter(func(f *entity.Field) bool { return f.TableID() == tableID })
+	if len(fields) == 0 {
+		return -1, nil
+	}
+	return fields[len(fields)-1].Order(), nil
+}
+
+// NextID 生成下一个字段ID
+func (r *FieldRepository) NextID() valueobject.FieldID {
+	return valueobject.NewFieldID("")
+}
+
+// filter 按条件筛选字段，返回副本并按 order 升序（相同 order 按创建时间）
+func (r *FieldRepository) filter(match func(*entity.Field) bool) []*entity.Field {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make([]*entity.Field, 0)
+	for _, field := range r.fields {
+		if match(field) {
+			result = append(result, cloneField(field))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Order() != result[j].Order() {
+			return result[i].Order() < result[j].Order()
+		}
+		if !result[i].CreatedAt().Equal(result[j].CreatedAt()) {
+			return result[i].CreatedAt().Before(result[j].CreatedAt())
+		}
+		return result[i].ID().String() < result[j].ID().String()
+	})
+	return result
+}
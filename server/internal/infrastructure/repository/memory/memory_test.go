@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/faultinject"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository/repotest"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+func TestFieldRepositoryContract(t *testing.T) {
+	repotest.FieldRepositoryContract(t, func(t *testing.T) fieldRepo.FieldRepository {
+		return NewFieldRepository()
+	})
+}
+
+func TestCachedFieldRepositoryContract(t *testing.T) {
+	cache := repotest.NewCache()
+	repotest.FieldRepositoryContract(t, func(t *testing.T) fieldRepo.FieldRepository {
+		return repository.NewCachedFieldRepository(NewFieldRepository(), cache, 0)
+	})
+	if cache.Len() == 0 {
+		t.Error("缓存包装器应写入缓存，否则契约测试没有覆盖缓存失效")
+	}
+}
+
+func TestRecordRepositoryContract(t *testing.T) {
+	repotest.RecordRepositoryContract(t, func(t *testing.T) repotest.RecordFixture {
+		repo := NewRecordRepository()
+		tableID := utils.GenerateTableID()
+		repo.AddTable(tableID)
+		return repotest.RecordFixture{Repo: repo, TableID: tableID, FieldID: utils.GenerateFieldID()}
+	})
+}
+
+func TestCachedRecordRepositoryContract(t *testing.T) {
+	cache := repotest.NewCache()
+	repotest.RecordRepositoryContract(t, func(t *testing.T) repotest.RecordFixture {
+		repo := NewRecordRepository()
+		tableID := utils.GenerateTableID()
+		repo.AddTable(tableID)
+		return repotest.RecordFixture{
+			Repo:    repository.NewCachedRecordRepository(repo, cache, 0),
+			TableID: tableID,
+			FieldID: utils.GenerateFieldID(),
+		}
+	})
+	if cache.Len() == 0 {
+		t.Error("缓存包装器应写入缓存，否则契约测试没有覆盖缓存失效")
+	}
+}
+
+// failingCache 所有操作都注入失败的缓存，验证缓存不可用时仓储仍按契约工作
+func failingCache() (repository.CacheProvider, *faultinject.Injector) {
+	injector := faultinject.New("cache", config.FaultInjectionConfig{Enabled: true, ErrorRate: 1})
+	return repository.NewFaultInjectingCache(repotest.NewCache(), injector), injector
+}
+
+func TestCachedRepositoriesDegradeWhenCacheFails(t *testing.T) {
+	cache, injector := failingCache()
+	repotest.FieldRepositoryContract(t, func(t *testing.T) fieldRepo.FieldRepository {
+		return repository.NewCachedFieldRepository(NewFieldRepository(), cache, 0)
+	})
+	repotest.RecordRepositoryContract(t, func(t *testing.T) repotest.RecordFixture {
+		repo := NewRecordRepository()
+		tableID := utils.GenerateTableID()
+		repo.AddTable(tableID)
+		return repotest.RecordFixture{
+			Repo:    repository.NewCachedRecordRepository(repo, cache, 0),
+			TableID: tableID,
+			FieldID: utils.GenerateFieldID(),
+		}
+	})
+	if injector.Stats().Failed == 0 {
+		t.Error("缓存操作应被注入失败，否则没有覆盖降级路径")
+	}
+}
+
+func TestFaultInjectingCachePartialDelete(t *testing.T) {
+	ctx := context.Background()
+	inner := repotest.NewCache()
+	injector := faultinject.New("cache", config.FaultInjectionConfig{Enabled: true, ErrorRate: 1, Targets: []string{"a:*"}})
+	cache := repository.NewFaultInjectingCache(inner, injector)
+	for _, key := range []string{"a:1", "b:1"} {
+		if err := inner.Set(ctx, key, key, 0); err != nil {
+			t.Fatalf("写入缓存失败: %v", err)
+		}
+	}
+
+	if err := cache.Delete(ctx, "a:1", "b:1"); !errors.Is(err, faultinject.ErrInjected) {
+		t.Fatalf("部分键失败时应返回注入错误，得到 %v", err)
+	}
+	var value string
+	if err := inner.Get(ctx, "b:1", &value); err == nil {
+		t.Error("未命中规则的键应照常删除")
+	}
+	if err := inner.Get(ctx, "a:1", &value); err != nil || value != "a:1" {
+		t.Errorf("注入失败的键应保留，得到 %q, %v", value, err)
+	}
+}
@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+var _ repository.RecordRepository = (*RecordRepository)(nil)
+
+// RecordRepository 内存记录仓储
+// 与动态表实现一样按表存放记录：表需先通过 AddTable 创建（对应物理表），
+// 访问未创建的表返回 ErrTableNotFound；更新记录时做乐观锁版本检查
+type RecordRepository struct {
+	mu     sync.RWMutex
+	tables map[string]map[string]*entity.Record
+}
+
+// NewRecordRepository 创建内存记录仓储
+func NewRecordRepository() *RecordRepository {
+	return &RecordRepository{tables: make(map[string]map[string]*entity.Record)}
+}
+
+// AddTable 创建表（已存在时忽略）
+func (r *RecordRepository) AddTable(tableID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tables[tableID]; !ok {
+		r.tables[tableID] = make(map[string]*entity.Record)
+	}
+}
+
+// cloneRecord 复制记录实体（RecordData 不可变，浅拷贝即可）
+func cloneRecord(record *entity.Record) *entity.Record {
+	c := *record
+	return &c
+}
+
+// project 字段投影（fieldIDs 为空时原样返回）
+func project(records []*entity.Record, fieldIDs []string) ([]*entity.Record, error) {
+	if len(fieldIDs) == 0 {
+		return records, nil
+	}
+	projected := make([]*entity.Record, 0, len(records))
+	for _, record := range records {
+		p, err := record.Project(fieldIDs)
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, p)
+	}
+	return projected, nil
+}
+
+// table 获取表中的记录（调用方持有锁）
+func (r *RecordRepository) table(tableID string) (map[string]*entity.Record, error) {
+	records, ok := r.tables[tableID]
+	if !ok {
+		return nil, errors.ErrTableNotFound.WithDetails(tableID)
+	}
+	return records, nil
+}
+
+// Save 保存记录（新增或更新，更新时要求已保存的版本为 record.Version()-1）
+func (r *RecordRepository) Save(ctx context.Context, record *entity.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.save(record)
+}
+
+func (r *RecordRepository) save(record *entity.Record) error {
+	records, err := r.table(record.TableID())
+	if err != nil {
+		return err
+	}
+	if existing, ok := records[record.ID().String()]; ok && existing.Version().Value() != record.Version().Value()-1 {
+		return errors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"type":             "version_conflict",
+			"message":          "记录已被其他用户修改，请刷新后重试",
+			"record_id":        record.ID().String(),
+			"expected_version": record.Version().Value() - 1,
+		})
+	}
+	records[record.ID().String()] = cloneRecord(record)
+	return nil
+}
+
+// FindByID 根据ID查找记录（在所有表中查找）
+func (r *RecordRepository) FindByID(ctx context.Context, id valueobject.RecordID) (*entity.Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, records := range r.tables {
+		if record, ok := records[id.String()]; ok {
+			return cloneRecord(record), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByIDs 根据ID列表查找记录（不存在的ID被忽略，fieldIDs 非空时只返回这些字段的值）
+func (r *RecordRepository) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID, fieldIDs ...string) ([]*entity.Record, error) {
+	if len(ids) == 0 {
+		return []*entity.Record{}, nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records, err := r.table(tableID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.Record, 0, len(ids))
+	for _, id := range ids {
+		if record, ok := records[id.String()]; ok {
+			result = append(result, cloneRecord(record))
+		}
+	}
+	return project(result, fieldIDs)
+}
+
+// FindByTableAndID 根据表ID和记录ID查找记录（不存在时返回 nil）
+func (r *RecordRepository) FindByTableAndID(ctx context.Context, tableID string, id valueobject.RecordID) (*entity.Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records, err := r.table(tableID)
+	if err != nil {
+		return nil, err
+	}
+	if record, ok := records[id.String()]; ok {
+		return cloneRecord(record), nil
+	}
+	return nil, nil
+}
+
+// FindByTableID 查找表的所有记录（按创建时间倒序）
+func (r *RecordRepository) FindByTableID(ctx context.Context, tableID string) ([]*entity.Record, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.sorted(tableID)
+}
+
+// Delete 删除记录（在所有表中查找）
+func (r *RecordRepository) Delete(ctx context.Context, id valueobject.RecordID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, records := range r.tables {
+		delete(records, id.String())
+	}
+	return nil
+}
+
+// DeleteByTableAndID 根据表ID和记录ID删除记录
+func (r *RecordRepository) DeleteByTableAndID(ctx context.Context, tableID string, id valueobject.RecordID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records, err := r.table(tableID)
+	if err != nil {
+		return err
+	}
+	delete(records, id.String())
+	return nil
+}
+
+// DeleteByTableAndIDWithVersion 仅在记录当前版本为 version 时删除
+func (r *RecordRepository) DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records, err := r.table(tableID)
+	if err != nil {
+		return err
+	}
+	if existing, ok := records[id.String()]; !ok || existing.Version().Value() != version.Value() {
+		return errors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"type":             "version_conflict",
+			"message":          "记录已被其他用户修改，请刷新后重试",
+			"record_id":        id.String(),
+			"expected_version": version.Value(),
+		})
+	}
+	delete(records, id.String())
+	return nil
+}
+
+// Exists 检查记录是否存在（在所有表中查找）
+func (r *RecordRepository) Exists(ctx context.Context, id valueobject.RecordID) (bool, error) {
+	record, err := r.FindByID(ctx, id)
+	return record != nil, err
+}
+
+// List 列出记录（需要 TableID，按创建时间倒序分页）
+func (r *RecordRepository) List(ctx context.Context, filter repository.RecordFilter) ([]*entity.Record, int64, error) {
+	if filter.TableID == nil {
+		return nil, 0, errors.ErrBadRequest.WithDetails("TableID is required")
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records, err := r.sorted(*filter.TableID)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int64(len(records))
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(records) {
+			return []*entity.Record{}, total, nil
+		}
+		records = records[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(records) {
+		records = records[:filter.Limit]
+	}
+	records, err = project(records, filter.FieldIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// BatchSave 批量保存记录（任一记录版本冲突时不做任何修改）
+func (r *RecordRepository) BatchSave(ctx context.Context, records []*entity.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := make(map[string]map[string]*entity.Record, len(r.tables))
+	for tableID, table := range r.tables {
+		copied := make(map[string]*entity.Record, len(table))
+		for id, record := range table {
+			copied[id] = record
+		}
+		snapshot[tableID] = copied
+	}
+	for _, record := range records {
+		if err := r.save(record); err != nil {
+			r.tables = snapshot
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchDelete 批量删除记录（在所有表中查找）
+func (r *RecordRepository) BatchDelete(ctx context.Context, ids []valueobject.RecordID) error {
+	for _, id := range ids {
+		if err := r.Delete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountByTableID 统计表的记录数
+func (r *RecordRepository) CountByTableID(ctx context.Context, tableID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	records, err := r.table(tableID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(records)), nil
+}
+
+// FindWithVersion 根据ID和版本查找记录（版本不一致时返回 ErrVersionConflict）
+func (r *RecordRepository) FindWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) (*entity.Record, error) {
+	record, err := r.FindByTableAndID(ctx, tableID, id)
+	if err != nil || record == nil {
+		return nil, err
+	}
+	if record.Version().Value() != version.Value() {
+		return nil, errors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"record_id":        record.ID().String(),
+			"expected_version": version.Value(),
+			"actual_version":   record.Version().Value(),
+		})
+	}
+	return record, nil
+}
+
+// NextID 生成下一个记录ID
+func (r *RecordRepository) NextID() valueobject.RecordID {
+	return valueobject.NewRecordID("")
+}
+
+// sorted 表中的记录副本，按创建时间倒序（调用方持有锁）
+func (r *RecordRepository) sorted(tableID string) ([]*entity.Record, error) {
+	records, err := r.table(tableID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.Record, 0, len(records))
+	for _, record := range records {
+		result = append(result, cloneRecord(record))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt().Equal(result[j].CreatedAt()) {
+			return result[i].CreatedAt().After(result[j].CreatedAt())
+		}
+		return result[i].ID().String() < result[j].ID().String()
+	})
+	return result, nil
+}
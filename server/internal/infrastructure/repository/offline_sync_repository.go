@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/offlinesync"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// OfflineSyncRepositoryImpl 离线修改登记GORM实现
+type OfflineSyncRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOfflineSyncRepository 创建离线修改登记仓储
+func NewOfflineSyncRepository(db *gorm.DB) offlinesync.Repository {
+	return &OfflineSyncRepositoryImpl{db: db}
+}
+
+// FindEntries 查找客户端已处理的修改
+func (r *OfflineSyncRepositoryImpl) FindEntries(ctx context.Context, userID, clientID string, mutationIDs []string) (map[string]*offlinesync.Entry, error) {
+	entries := make(map[string]*offlinesync.Entry, len(mutationIDs))
+	if len(mutationIDs) == 0 {
+		return entries, nil
+	}
+	var list []models.OfflineMutation
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND client_id = ? AND mutation_id IN ?", userID, clientID, mutationIDs).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to find offline mutations: %w", err)
+	}
+	for i := range list {
+		entry := fromOfflineMutationModel(&list[i])
+		entries[entry.MutationID] = entry
+	}
+	return entries, nil
+}
+
+// ResolveRecordIDs 将本地记录ID解析为服务端记录ID
+func (r *OfflineSyncRepositoryImpl) ResolveRecordIDs(ctx context.Context, userID, clientID string, clientRecordIDs []string) (map[string]string, error) {
+	resolved := make(map[string]string, len(clientRecordIDs))
+	if len(clientRecordIDs) == 0 {
+		return resolved, nil
+	}
+	var list []models.OfflineMutation
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND client_id = ? AND client_record_id IN ? AND status = ?",
+			userID, clientID, clientRecordIDs, string(offlinesync.StatusApplied)).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve offline record ids: %w", err)
+	}
+	for i := range list {
+		entry := fromOfflineMutationModel(&list[i])
+		if entry.Result.RecordID != "" {
+			resolved[entry.ClientRecordID] = entry.Result.RecordID
+		}
+	}
+	return resolved, nil
+}
+
+// SaveEntry 登记已处理的修改（同一修改重复登记时保留先登记的结果）
+func (r *OfflineSyncRepositoryImpl) SaveEntry(ctx context.Context, entry *offlinesync.Entry) error {
+	result, err := json.Marshal(entry.Result)
+	if err != nil {
+		return err
+	}
+	model := models.OfflineMutation{
+		ID:             utils.GenerateIDWithPrefix("ofm"),
+		UserID:         entry.UserID,
+		ClientID:       entry.ClientID,
+		MutationID:     entry.MutationID,
+		TableID:        entry.TableID,
+		ClientRecordID: entry.ClientRecordID,
+		Status:         string(entry.Result.Status),
+		Result:         string(result),
+		CreatedTime:    entry.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save offline mutation: %w", err)
+	}
+	return nil
+}
+
+// PurgeBefore 删除早于 before 的登记
+func (r *OfflineSyncRepositoryImpl) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_time < ?", before).Delete(&models.OfflineMutation{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge offline mutations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func fromOfflineMutationModel(model *models.OfflineMutation) *offlinesync.Entry {
+	entry := &offlinesync.Entry{
+		ClientID:       model.ClientID,
+		MutationID:     model.MutationID,
+		UserID:         model.UserID,
+		TableID:        model.TableID,
+		ClientRecordID: model.ClientRecordID,
+		CreatedAt:      model.CreatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Result), &entry.Result)
+	return entry
+}
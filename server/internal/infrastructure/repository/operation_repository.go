@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// operationListLimit 列表默认返回条数
+const operationListLimit = 50
+
+// OperationRepositoryImpl 长时操作GORM实现
+type OperationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOperationRepository 创建长时操作仓储
+func NewOperationRepository(db *gorm.DB) operation.Repository {
+	return &OperationRepositoryImpl{db: db}
+}
+
+// Create 保存新操作
+func (r *OperationRepositoryImpl) Create(ctx context.Context, op *operation.Operation) error {
+	model, err := toOperationModel(op)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create operation: %w", err)
+	}
+	return nil
+}
+
+// Update 保存执行状态与进度
+// cancel_requested 只由 RequestCancel 写入，避免执行器保存进度时覆盖并发的取消请求
+func (r *OperationRepositoryImpl) Update(ctx context.Context, op *operation.Operation) error {
+	model, err := toOperationModel(op)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("id = ?", op.ID).
+		Select("status", "total", "processed", "failed_count", "errors", "result", "error",
+			"updated_time", "started_time", "finished_time").
+		Updates(&model).Error; err != nil {
+		return fmt.Errorf("failed to update operation: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取操作
+func (r *OperationRepositoryImpl) FindByID(ctx context.Context, id string) (*operation.Operation, error) {
+	var model models.Operation
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+	return fromOperationModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的操作
+func (r *OperationRepositoryImpl) ListByBase(ctx context.Context, baseID string, filter operation.ListFilter) ([]*operation.Operation, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = operationListLimit
+	}
+	query := r.db.WithContext(ctx).Where("base_id = ?", baseID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", string(filter.Type))
+	}
+
+	var list []models.Operation
+	if err := query.Order("created_time DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list operations: %w", err)
+	}
+	ops := make([]*operation.Operation, 0, len(list))
+	for i := range list {
+		ops = append(ops, fromOperationModel(&list[i]))
+	}
+	return ops, nil
+}
+
+// RequestCancel 设置取消请求标记
+func (r *OperationRepositoryImpl) RequestCancel(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("id = ? AND status IN ?", id, []string{string(operation.StatusPending), string(operation.StatusRunning)}).
+		Update("cancel_requested", true).Error; err != nil {
+		return fmt.Errorf("failed to request operation cancel: %w", err)
+	}
+	return nil
+}
+
+// CancelPending 取消仍在等待的操作（与 Claim 竞争时以状态条件保证只有一方成功）
+func (r *OperationRepositoryImpl) CancelPending(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("id = ? AND status = ?", id, string(operation.StatusPending)).
+		Updates(map[string]interface{}{
+			"status":           string(operation.StatusCancelled),
+			"cancel_requested": true,
+			"updated_time":     now,
+			"finished_time":    now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel operation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsCancelRequested 操作是否已被请求取消
+func (r *OperationRepositoryImpl) IsCancelRequested(ctx context.Context, id string) (bool, error) {
+	var flags []bool
+	if err := r.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("id = ?", id).
+		Pluck("cancel_requested", &flags).Error; err != nil {
+		return false, fmt.Errorf("failed to get operation cancel flag: %w", err)
+	}
+	return len(flags) > 0 && flags[0], nil
+}
+
+// Claim 领取等待执行的操作（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *OperationRepositoryImpl) Claim(ctx context.Context, now time.Time) (*operation.Operation, error) {
+	var claimed *operation.Operation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.Operation{}).
+			Where("status = ?", string(operation.StatusPending)).
+			Order("created_time ASC")
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var model models.Operation
+		err := query.Take(&model).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		model.Status = string(operation.StatusRunning)
+		model.StartedTime = &now
+		model.UpdatedTime = now
+		if err := tx.Model(&models.Operation{}).
+			Where("id = ?", model.ID).
+			Updates(map[string]interface{}{
+				"status":       model.Status,
+				"started_time": now,
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		claimed = fromOperationModel(&model)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim operation: %w", err)
+	}
+	return claimed, nil
+}
+
+// FailStale 将无心跳的执行中操作标记为失败
+func (r *OperationRepositoryImpl) FailStale(ctx context.Context, staleBefore, now time.Time, reason string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Operation{}).
+		Where("status = ? AND updated_time < ?", string(operation.StatusRunning), staleBefore).
+		Updates(map[string]interface{}{
+			"status":        string(operation.StatusFailed),
+			"error":         reason,
+			"updated_time":  now,
+			"finished_time": now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to fail stale operations: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func toOperationModel(op *operation.Operation) (models.Operation, error) {
+	model := models.Operation{
+		ID:              op.ID,
+		BaseID:          op.BaseID,
+		Type:            string(op.Type),
+		Status:          string(op.Status),
+		Total:           op.Total,
+		Processed:       op.Processed,
+		FailedCount:     op.FailedCount,
+		CancelRequested: op.CancelRequested,
+		CreatedBy:       op.CreatedBy,
+		CreatedTime:     op.CreatedAt,
+		UpdatedTime:     op.UpdatedAt,
+		StartedTime:     op.StartedAt,
+		FinishedTime:    op.FinishedAt,
+	}
+	if len(op.Params) > 0 {
+		params := string(op.Params)
+		model.Params = &params
+	}
+	if len(op.Result) > 0 {
+		result := string(op.Result)
+		model.Result = &result
+	}
+	if op.Error != "" {
+		model.Error = &op.Error
+	}
+	if len(op.Errors) > 0 {
+		data, err := json.Marshal(op.Errors)
+		if err != nil {
+			return model, fmt.Errorf("failed to encode operation errors: %w", err)
+		}
+		errs := string(data)
+		model.Errors = &errs
+	}
+	return model, nil
+}
+
+func fromOperationModel(model *models.Operation) *operation.Operation {
+	op := &operation.Operation{
+		ID:              model.ID,
+		BaseID:          model.BaseID,
+		Type:            operation.Type(model.Type),
+		Status:          operation.Status(model.Status),
+		Total:           model.Total,
+		Processed:       model.Processed,
+		FailedCount:     model.FailedCount,
+		Errors:          []operation.ItemError{},
+		CancelRequested: model.CancelRequested,
+		CreatedBy:       model.CreatedBy,
+		CreatedAt:       model.CreatedTime,
+		UpdatedAt:       model.UpdatedTime,
+		StartedAt:       model.StartedTime,
+		FinishedAt:      model.FinishedTime,
+	}
+	if model.Params != nil {
+		op.Params = json.RawMessage(*model.Params)
+	}
+	if model.Result != nil {
+		op.Result = json.RawMessage(*model.Result)
+	}
+	if model.Error != nil {
+		op.Error = *model.Error
+	}
+	if model.Errors != nil {
+		_ = json.Unmarshal([]byte(*model.Errors), &op.Errors)
+	}
+	return op
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/page"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// PageRepositoryImpl 页面仓储GORM实现
+type PageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPageRepository 创建页面仓储
+func NewPageRepository(db *gorm.DB) page.Repository {
+	return &PageRepositoryImpl{db: db}
+}
+
+// Save 保存页面
+func (r *PageRepositoryImpl) Save(ctx context.Context, p *page.Page) error {
+	model, err := toPageModel(p)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Save(model).Error; err != nil {
+		return fmt.Errorf("failed to save page: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取页面
+func (r *PageRepositoryImpl) FindByID(ctx context.Context, id string) (*page.Page, error) {
+	var model models.Page
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+	return fromPageModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的页面
+func (r *PageRepositoryImpl) ListByBase(ctx context.Context, baseID string) ([]*page.Page, error) {
+	var list []models.Page
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+
+	pages := make([]*page.Page, 0, len(list))
+	for i := range list {
+		pages = append(pages, fromPageModel(&list[i]))
+	}
+	return pages, nil
+}
+
+// Delete 删除页面
+func (r *PageRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Page{}).Error; err != nil {
+		return fmt.Errorf("failed to delete page: %w", err)
+	}
+	return nil
+}
+
+func toPageModel(p *page.Page) (*models.Page, error) {
+	elements, err := json.Marshal(p.Elements)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page elements: %w", err)
+	}
+	rules, err := json.Marshal(p.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page rules: %w", err)
+	}
+	model := &models.Page{
+		ID:               p.ID,
+		BaseID:           p.BaseID,
+		Name:             p.Name,
+		Description:      p.Description,
+		Elements:         string(elements),
+		Rules:            string(rules),
+		CreatedBy:        p.CreatedBy,
+		CreatedTime:      p.CreatedAt,
+		LastModifiedTime: p.UpdatedAt,
+	}
+	if p.LastModifiedBy != "" {
+		model.LastModifiedBy = &p.LastModifiedBy
+	}
+	return model, nil
+}
+
+func fromPageModel(m *models.Page) *page.Page {
+	p := &page.Page{
+		ID:          m.ID,
+		BaseID:      m.BaseID,
+		Name:        m.Name,
+		Description: m.Description,
+		Elements:    []page.Element{},
+		Rules:       []page.AccessRule{},
+		CreatedBy:   m.CreatedBy,
+		CreatedAt:   m.CreatedTime,
+		UpdatedAt:   m.LastModifiedTime,
+	}
+	// 配置损坏时按空配置处理：没有元素，也没有分享规则（只有 Base 编辑者可以访问）
+	if m.Elements != "" {
+		_ = json.Unmarshal([]byte(m.Elements), &p.Elements)
+	}
+	if m.Rules != "" {
+		_ = json.Unmarshal([]byte(m.Rules), &p.Rules)
+	}
+	if m.LastModifiedBy != nil {
+		p.LastModifiedBy = *m.LastModifiedBy
+	}
+	return p
+}
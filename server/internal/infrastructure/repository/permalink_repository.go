@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/permalink"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// PermalinkRepositoryImpl 永久链接仓储GORM实现
+type PermalinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPermalinkRepository 创建永久链接仓储
+func NewPermalinkRepository(db *gorm.DB) permalink.Repository {
+	return &PermalinkRepositoryImpl{db: db}
+}
+
+// GetByCode 按链接码获取
+func (r *PermalinkRepositoryImpl) GetByCode(ctx context.Context, code string) (*permalink.Permalink, error) {
+	return r.take(ctx, "code = ?", code)
+}
+
+// GetByTarget 按目标获取已有链接
+func (r *PermalinkRepositoryImpl) GetByTarget(ctx context.Context, link *permalink.Permalink) (*permalink.Permalink, error) {
+	return r.take(ctx, "target_key = ?", link.TargetKey())
+}
+
+// Create 保存链接（目标键冲突时返回已有链接）
+func (r *PermalinkRepositoryImpl) Create(ctx context.Context, link *permalink.Permalink) (*permalink.Permalink, error) {
+	model := models.Permalink{
+		Code:        link.Code,
+		TargetKey:   link.TargetKey(),
+		TargetType:  string(link.TargetType),
+		TableID:     link.TableID,
+		ViewID:      link.ViewID,
+		RecordID:    link.RecordID,
+		CreatedBy:   link.CreatedBy,
+		CreatedTime: link.CreatedAt,
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "target_key"}}, DoNothing: true}).
+		Create(&model)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to create permalink: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return link, nil
+	}
+	existing, err := r.GetByTarget(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("permalink for %s not found after conflict", link.TargetKey())
+	}
+	return existing, nil
+}
+
+func (r *PermalinkRepositoryImpl) take(ctx context.Context, query string, arg string) (*permalink.Permalink, error) {
+	var model models.Permalink
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permalink: %w", err)
+	}
+	return &permalink.Permalink{
+		Code:       model.Code,
+		TargetType: permalink.TargetType(model.TargetType),
+		TableID:    model.TableID,
+		ViewID:     model.ViewID,
+		RecordID:   model.RecordID,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+	}, nil
+}
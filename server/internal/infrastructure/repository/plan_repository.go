@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// PlanRepositoryImpl 工作空间套餐仓储GORM实现
+type PlanRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPlanRepository 创建工作空间套餐仓储
+func NewPlanRepository(db *gorm.DB) plan.Repository {
+	return &PlanRepositoryImpl{db: db}
+}
+
+// FindAssignment 获取工作空间的套餐
+func (r *PlanRepositoryImpl) FindAssignment(ctx context.Context, spaceID string) (*plan.Assignment, error) {
+	var model models.SpacePlan
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find space plan: %w", err)
+	}
+	return &plan.Assignment{
+		SpaceID:   model.SpaceID,
+		PlanID:    model.PlanID,
+		UpdatedBy: model.UpdatedBy,
+		UpdatedAt: model.UpdatedAt,
+	}, nil
+}
+
+// SaveAssignment 保存工作空间的套餐（已存在时覆盖）
+func (r *PlanRepositoryImpl) SaveAssignment(ctx context.Context, assignment *plan.Assignment) error {
+	model := models.SpacePlan{
+		SpaceID:   assignment.SpaceID,
+		PlanID:    assignment.PlanID,
+		UpdatedBy: assignment.UpdatedBy,
+		UpdatedAt: assignment.UpdatedAt,
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "space_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"plan_id", "updated_by", "updated_at"}),
+	}).Create(&model).Error
+	if err != nil {
+		return fmt.Errorf("failed to save space plan: %w", err)
+	}
+	return nil
+}
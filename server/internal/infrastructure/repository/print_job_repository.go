@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/printjob"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// PrintJobRepositoryImpl 打印任务GORM实现
+type PrintJobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPrintJobRepository 创建打印任务仓储
+func NewPrintJobRepository(db *gorm.DB) printjob.Repository {
+	return &PrintJobRepositoryImpl{db: db}
+}
+
+// Save 创建或更新打印任务
+func (r *PrintJobRepositoryImpl) Save(ctx context.Context, job *printjob.Job) error {
+	model := models.PrintJob{
+		ID:           job.ID,
+		TargetType:   string(job.TargetType),
+		TableID:      job.TableID,
+		ViewID:       job.ViewID,
+		RecordID:     job.RecordID,
+		Paper:        job.Paper,
+		Orientation:  job.Orientation,
+		TimeZone:     job.TimeZone,
+		Locale:       job.Locale,
+		Source:       job.Source,
+		Status:       string(job.Status),
+		FileName:     job.FileName,
+		Size:         job.Size,
+		Pages:        job.Pages,
+		Rows:         job.Rows,
+		Truncated:    job.Truncated,
+		Error:        job.Error,
+		CreatedBy:    job.CreatedBy,
+		CreatedTime:  job.CreatedAt,
+		UpdatedTime:  job.UpdatedAt,
+		FinishedTime: job.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save print job: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取打印任务
+func (r *PrintJobRepositoryImpl) FindByID(ctx context.Context, id string) (*printjob.Job, error) {
+	var model models.PrintJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get print job: %w", err)
+	}
+	return fromPrintJobModel(&model), nil
+}
+
+// ListByTable 按创建时间倒序列出用户在表中创建的打印任务
+func (r *PrintJobRepositoryImpl) ListByTable(ctx context.Context, tableID, createdBy string, limit int) ([]*printjob.Job, error) {
+	var list []models.PrintJob
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ? AND created_by = ?", tableID, createdBy).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list print jobs: %w", err)
+	}
+	jobs := make([]*printjob.Job, 0, len(list))
+	for i := range list {
+		jobs = append(jobs, fromPrintJobModel(&list[i]))
+	}
+	return jobs, nil
+}
+
+// Claim 领取待执行或已中断的打印任务（PostgreSQL 使用 SKIP LOCKED）
+func (r *PrintJobRepositoryImpl) Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*printjob.Job, error) {
+	var claimed []*printjob.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.PrintJob{}).
+			Where("status = ? OR (status = ? AND updated_time < ?)",
+				string(printjob.StatusPending), string(printjob.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.PrintJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.PrintJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(printjob.StatusRunning),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(printjob.StatusRunning)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromPrintJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim print jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+func fromPrintJobModel(model *models.PrintJob) *printjob.Job {
+	return &printjob.Job{
+		ID:          model.ID,
+		TargetType:  printjob.TargetType(model.TargetType),
+		TableID:     model.TableID,
+		ViewID:      model.ViewID,
+		RecordID:    model.RecordID,
+		Paper:       model.Paper,
+		Orientation: model.Orientation,
+		TimeZone:    model.TimeZone,
+		Locale:      model.Locale,
+		Source:      model.Source,
+		Status:      printjob.Status(model.Status),
+		FileName:    model.FileName,
+		Size:        model.Size,
+		Pages:       model.Pages,
+		Rows:        model.Rows,
+		Truncated:   model.Truncated,
+		Error:       model.Error,
+		CreatedBy:   model.CreatedBy,
+		CreatedAt:   model.CreatedTime,
+		UpdatedAt:   model.UpdatedTime,
+		FinishedAt:  model.FinishedTime,
+	}
+}
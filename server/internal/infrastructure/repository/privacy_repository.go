@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// PrivacyRepositoryImpl 隐私策略仓储GORM实现
+type PrivacyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPrivacyRepository 创建隐私策略仓储
+func NewPrivacyRepository(db *gorm.DB) privacy.Repository {
+	return &PrivacyRepositoryImpl{db: db}
+}
+
+// SavePolicy 保存字段脱敏策略
+func (r *PrivacyRepositoryImpl) SavePolicy(ctx context.Context, policy *privacy.MaskingPolicy) error {
+	model := models.FieldMaskingPolicy{
+		ID:          policy.ID,
+		TableID:     policy.TableID,
+		FieldID:     policy.FieldID,
+		PIIType:     string(policy.PIIType),
+		Strategy:    string(policy.Strategy),
+		ExemptRoles: policy.ExemptRoles,
+		CreatedBy:   policy.CreatedBy,
+		CreatedTime: policy.CreatedTime,
+		UpdatedTime: policy.UpdatedTime,
+	}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "table_id"}, {Name: "field_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pii_type", "strategy", "exempt_roles", "updated_time"}),
+		}).
+		Create(&model).Error
+	if err != nil {
+		return fmt.Errorf("failed to save masking policy: %w", err)
+	}
+	return nil
+}
+
+// GetPolicy 获取字段脱敏策略
+func (r *PrivacyRepositoryImpl) GetPolicy(ctx context.Context, tableID, fieldID string) (*privacy.MaskingPolicy, error) {
+	var model models.FieldMaskingPolicy
+	err := r.db.WithContext(ctx).
+		Where("table_id = ? AND field_id = ?", tableID, fieldID).
+		Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get masking policy: %w", err)
+	}
+	return toMaskingPolicy(&model), nil
+}
+
+// ListPolicies 列出表的脱敏策略
+func (r *PrivacyRepositoryImpl) ListPolicies(ctx context.Context, tableID string) ([]*privacy.MaskingPolicy, error) {
+	var list []models.FieldMaskingPolicy
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list masking policies: %w", err)
+	}
+
+	policies := make([]*privacy.MaskingPolicy, 0, len(list))
+	for i := range list {
+		policies = append(policies, toMaskingPolicy(&list[i]))
+	}
+	return policies, nil
+}
+
+// DeletePolicy 删除字段脱敏策略
+func (r *PrivacyRepositoryImpl) DeletePolicy(ctx context.Context, tableID, fieldID string) error {
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ? AND field_id = ?", tableID, fieldID).
+		Delete(&models.FieldMaskingPolicy{}).Error; err != nil {
+		return fmt.Errorf("failed to delete masking policy: %w", err)
+	}
+	return nil
+}
+
+// ReplaceFindings 替换表的PII扫描结果
+func (r *PrivacyRepositoryImpl) ReplaceFindings(ctx context.Context, tableID string, findings []*privacy.PIIFinding) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("table_id = ?", tableID).Delete(&models.FieldPIIFinding{}).Error; err != nil {
+			return fmt.Errorf("failed to clear pii findings: %w", err)
+		}
+		if len(findings) == 0 {
+			return nil
+		}
+
+		list := make([]models.FieldPIIFinding, 0, len(findings))
+		for _, f := range findings {
+			list = append(list, models.FieldPIIFinding{
+				TableID:     f.TableID,
+				FieldID:     f.FieldID,
+				PIIType:     string(f.PIIType),
+				MatchCount:  f.MatchCount,
+				SampleSize:  f.SampleSize,
+				RecordIDs:   f.RecordIDs,
+				ScannedTime: f.ScannedTime,
+			})
+		}
+		if err := tx.Create(&list).Error; err != nil {
+			return fmt.Errorf("failed to save pii findings: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListFindings 列出表的PII扫描结果
+func (r *PrivacyRepositoryImpl) ListFindings(ctx context.Context, tableID string) ([]*privacy.PIIFinding, error) {
+	var list []models.FieldPIIFinding
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("match_count DESC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pii findings: %w", err)
+	}
+
+	findings := make([]*privacy.PIIFinding, 0, len(list))
+	for _, m := range list {
+		findings = append(findings, &privacy.PIIFinding{
+			TableID:     m.TableID,
+			FieldID:     m.FieldID,
+			PIIType:     privacy.PIIType(m.PIIType),
+			MatchCount:  m.MatchCount,
+			SampleSize:  m.SampleSize,
+			RecordIDs:   m.RecordIDs,
+			ScannedTime: m.ScannedTime,
+		})
+	}
+	return findings, nil
+}
+
+func toMaskingPolicy(m *models.FieldMaskingPolicy) *privacy.MaskingPolicy {
+	return &privacy.MaskingPolicy{
+		ID:          m.ID,
+		TableID:     m.TableID,
+		FieldID:     m.FieldID,
+		PIIType:     privacy.PIIType(m.PIIType),
+		Strategy:    privacy.MaskStrategy(m.Strategy),
+		ExemptRoles: m.ExemptRoles,
+		CreatedBy:   m.CreatedBy,
+		CreatedTime: m.CreatedTime,
+		UpdatedTime: m.UpdatedTime,
+	}
+}
+
+// SaveErasureJob 保存数据擦除任务
+func (r *PrivacyRepositoryImpl) SaveErasureJob(ctx context.Context, job *privacy.ErasureJob) error {
+	model := models.GDPRErasureJob{
+		ID:            job.ID,
+		SpaceID:       job.SpaceID,
+		SubjectUserID: job.Subject.UserID,
+		SubjectEmail:  job.Subject.Email,
+		Mode:          string(job.Mode),
+		Status:        string(job.Status),
+		RequestedBy:   job.RequestedBy,
+		CreatedTime:   job.CreatedTime,
+		FinishedTime:  job.FinishedTime,
+		LockedUntil:   job.LockedUntil,
+	}
+	if job.Report != nil {
+		data, err := json.Marshal(job.Report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal erasure report: %w", err)
+		}
+		report := string(data)
+		model.Report = &report
+	}
+	if job.Error != "" {
+		model.Error = &job.Error
+	}
+
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save erasure job: %w", err)
+	}
+	return nil
+}
+
+// GetErasureJob 获取数据擦除任务
+func (r *PrivacyRepositoryImpl) GetErasureJob(ctx context.Context, jobID string) (*privacy.ErasureJob, error) {
+	var model models.GDPRErasureJob
+	err := r.db.WithContext(ctx).Where("id = ?", jobID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get erasure job: %w", err)
+	}
+	return toErasureJob(&model)
+}
+
+// ListErasureJobs 列出空间的数据擦除任务
+func (r *PrivacyRepositoryImpl) ListErasureJobs(ctx context.Context, spaceID string) ([]*privacy.ErasureJob, error) {
+	var list []models.GDPRErasureJob
+	if err := r.db.WithContext(ctx).
+		Where("space_id = ?", spaceID).
+		Order("created_time DESC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list erasure jobs: %w", err)
+	}
+
+	jobs := make([]*privacy.ErasureJob, 0, len(list))
+	for i := range list {
+		job, err := toErasureJob(&list[i])
+		if err != nil {
+			return nil, err
+		}
+		// 列表不返回明细，避免响应过大
+		if job.Report != nil {
+			job.Report.Items = nil
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// ClaimErasureJobs 领取未结束且租约已过期的擦除任务（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *PrivacyRepositoryImpl) ClaimErasureJobs(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*privacy.ErasureJob, error) {
+	var claimed []*privacy.ErasureJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.GDPRErasureJob{}).
+			Where("status IN ?", []string{string(privacy.ErasureStatusPending), string(privacy.ErasureStatusRunning)}).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.GDPRErasureJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.GDPRErasureJob{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].LockedUntil = &leaseUntil
+			job, err := toErasureJob(&list[i])
+			if err != nil {
+				return err
+			}
+			claimed = append(claimed, job)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim erasure jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+func toErasureJob(m *models.GDPRErasureJob) (*privacy.ErasureJob, error) {
+	job := &privacy.ErasureJob{
+		ID:           m.ID,
+		SpaceID:      m.SpaceID,
+		Subject:      privacy.Subject{UserID: m.SubjectUserID, Email: m.SubjectEmail},
+		Mode:         privacy.ErasureMode(m.Mode),
+		Status:       privacy.ErasureStatus(m.Status),
+		RequestedBy:  m.RequestedBy,
+		CreatedTime:  m.CreatedTime,
+		FinishedTime: m.FinishedTime,
+		LockedUntil:  m.LockedUntil,
+	}
+	if m.Error != nil {
+		job.Error = *m.Error
+	}
+	if m.Report != nil && *m.Report != "" {
+		var report privacy.ErasureReport
+		if err := json.Unmarshal([]byte(*m.Report), &report); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal erasure report: %w", err)
+		}
+		job.Report = &report
+	}
+	return job, nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// archiveSystemColumns 物理表的系统列（恢复归档记录时原样写回）
+var archiveSystemColumns = []string{
+	"__id",
+	"__auto_number",
+	"__created_time",
+	"__created_by",
+	"__last_modified_time",
+	"__last_modified_by",
+	"__version",
+}
+
+// archiveTable 读取表所在的 Base、物理表名与字段
+func (r *RecordRepositoryDynamic) archiveTable(ctx context.Context, tableID string) (string, string, []*fieldEntity.Field, error) {
+	table, err := r.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("获取Table信息失败: %w", err)
+	}
+	if table == nil {
+		return "", "", nil, errors.ErrTableNotFound.WithDetails(tableID)
+	}
+	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("获取字段列表失败: %w", err)
+	}
+	baseID := table.BaseID()
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return "", "", nil, err
+	}
+	return baseID, fullTableName, fields, nil
+}
+
+// Archive 将一批最早的记录移入归档表
+// 数据与归档表在同一个库时在一个事务中完成；数据位于其他区域时先写归档再删除物理行，
+// 中途失败只会留下重复的归档行，下次归档时覆盖
+func (r *RecordRepositoryDynamic) Archive(ctx context.Context, tableID string, criteria recordRepo.ArchiveCriteria) ([]string, error) {
+	baseID, fullTableName, _, err := r.archiveTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	basis := "__created_time"
+	if criteria.LastModified {
+		basis = "COALESCE(__last_modified_time, __created_time)"
+	}
+
+	var archived []string
+	move := func(dataTx, archiveTx *gorm.DB) error {
+		var rows []map[string]interface{}
+		if err := dataTx.Table(fullTableName).
+			Where(basis+" < ?", criteria.Before).
+			Order(basis + " ASC").
+			Limit(criteria.Limit).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("查询待归档记录失败: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		entries := make([]models.ArchivedRecord, 0, len(rows))
+		changes := make([]models.RecordChange, 0, len(rows))
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			id := fmt.Sprintf("%v", row["__id"])
+			entries = append(entries, models.ArchivedRecord{
+				TableID:      tableID,
+				RecordID:     id,
+				Row:          snapshotRow(row),
+				ArchivedBy:   criteria.ArchivedBy,
+				ArchivedTime: now,
+			})
+			// 归档对按时间点读取而言等同于删除：asOf 早于归档时仍能读到这些记录
+			changes = append(changes, newRecordChange(tableID, id, recordChangeDelete, row, archiveRowVersion(row), criteria.ArchivedBy))
+			ids = append(ids, id)
+		}
+		if err := archiveTx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "table_id"}, {Name: "record_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"row", "archived_by", "archived_time"}),
+		}).CreateInBatches(entries, 500).Error; err != nil {
+			return fmt.Errorf("写入归档记录失败: %w", err)
+		}
+		if err := dataTx.Table(fullTableName).Where("__id IN ?", ids).Delete(nil).Error; err != nil {
+			return fmt.Errorf("从物理表删除已归档记录失败: %w", err)
+		}
+		if err := r.logRecordChanges(ctx, archiveTx, changes...); err != nil {
+			return err
+		}
+		archived = ids
+		return nil
+	}
+
+	dataDB := r.dataDB(ctx, baseID)
+	if dataDB == r.db {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return move(tx, tx)
+		})
+	} else {
+		err = move(dataDB.WithContext(ctx), r.db.WithContext(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// ListArchived 按归档时间倒序分页读取归档记录（返回存储值）
+func (r *RecordRepositoryDynamic) ListArchived(ctx context.Context, tableID string, offset, limit int) ([]*entity.Record, int64, error) {
+	baseID, _, fields, err := r.archiveTable(ctx, tableID)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := r.CountArchived(ctx, tableID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.ArchivedRecord
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("archived_time DESC, record_id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询归档记录失败: %w", err)
+	}
+
+	records := make([]*entity.Record, 0, len(entries))
+	for i := range entries {
+		rec, err := r.toDomainEntity(ctx, restoreSnapshotRow(entries[i].Row), fields, baseID, tableID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("转换归档记录失败: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, total, nil
+}
+
+// CountArchived 统计表的归档记录数
+func (r *RecordRepositoryDynamic) CountArchived(ctx context.Context, tableID string) (int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.ArchivedRecord{}).
+		Where("table_id = ?", tableID).
+		Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("统计归档记录失败: %w", err)
+	}
+	return total, nil
+}
+
+// Restore 将归档记录写回物理表（保留原记录ID、自增序号与版本）
+// 归档后删除的字段不再写回，归档后新增的字段为空
+func (r *RecordRepositoryDynamic) Restore(ctx context.Context, tableID string, recordIDs []string) ([]string, error) {
+	if len(recordIDs) == 0 {
+		return []string{}, nil
+	}
+	baseID, fullTableName, fields, err := r.archiveTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]bool, len(archiveSystemColumns)+len(fields))
+	for _, col := range archiveSystemColumns {
+		columns[col] = true
+	}
+	for _, field := range fields {
+		columns[field.DBFieldName().String()] = true
+	}
+
+	var restored []string
+	move := func(dataTx, archiveTx *gorm.DB) error {
+		var entries []models.ArchivedRecord
+		if err := archiveTx.Where("table_id = ? AND record_id IN ?", tableID, recordIDs).
+			Find(&entries).Error; err != nil {
+			return fmt.Errorf("查询归档记录失败: %w", err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		rows := make([]map[string]interface{}, 0, len(entries))
+		changes := make([]models.RecordChange, 0, len(entries))
+		ids := make([]string, 0, len(entries))
+		for i := range entries {
+			row := make(map[string]interface{}, len(columns))
+			for col, value := range restoreSnapshotRow(entries[i].Row) {
+				if columns[col] {
+					row[col] = value
+				}
+			}
+			rows = append(rows, row)
+			changes = append(changes, newRecordChange(tableID, entries[i].RecordID, recordChangeCreate, nil, archiveRowVersion(row), ""))
+			ids = append(ids, entries[i].RecordID)
+		}
+		if err := dataTx.Table(fullTableName).Create(&rows).Error; err != nil {
+			return fmt.Errorf("恢复归档记录失败: %w", err)
+		}
+		if err := archiveTx.Where("table_id = ? AND record_id IN ?", tableID, ids).
+			Delete(&models.ArchivedRecord{}).Error; err != nil {
+			return fmt.Errorf("删除归档记录失败: %w", err)
+		}
+		if err := r.logRecordChanges(ctx, archiveTx, changes...); err != nil {
+			return err
+		}
+		restored = ids
+		return nil
+	}
+
+	dataDB := r.dataDB(ctx, baseID)
+	if dataDB == r.db {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return move(tx, tx)
+		})
+	} else {
+		err = move(dataDB.WithContext(ctx), r.db.WithContext(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// archiveRowVersion 读取行的版本号
+func archiveRowVersion(row map[string]interface{}) int64 {
+	switch v := row["__version"].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
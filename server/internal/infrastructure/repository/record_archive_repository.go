@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordarchive"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// RecordArchiveRepositoryImpl 归档策略GORM实现
+type RecordArchiveRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordArchiveRepository 创建归档策略仓储
+func NewRecordArchiveRepository(db *gorm.DB) recordarchive.Repository {
+	return &RecordArchiveRepositoryImpl{db: db}
+}
+
+// FindByTable 获取表的归档策略
+func (r *RecordArchiveRepositoryImpl) FindByTable(ctx context.Context, tableID string) (*recordarchive.Policy, error) {
+	var model models.RecordArchivePolicy
+	err := r.db.WithContext(ctx).Where("table_id = ?", tableID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record archive policy: %w", err)
+	}
+	return fromRecordArchivePolicyModel(&model), nil
+}
+
+// Save 创建或更新归档策略（执行结果与租约只由 SaveProgress 写入）
+func (r *RecordArchiveRepositoryImpl) Save(ctx context.Context, policy *recordarchive.Policy) error {
+	model := models.RecordArchivePolicy{
+		ID:           policy.ID,
+		TableID:      policy.TableID,
+		Basis:        policy.Basis,
+		AfterDays:    policy.AfterDays,
+		Enabled:      policy.Enabled,
+		NextRunTime:  policy.NextRunAt,
+		LastRunTime:  policy.LastRunAt,
+		LastArchived: policy.LastArchived,
+		LastError:    policy.LastError,
+		CreatedBy:    policy.CreatedBy,
+		UpdatedBy:    policy.UpdatedBy,
+		CreatedTime:  policy.CreatedAt,
+		UpdatedTime:  policy.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"basis", "after_days", "enabled", "next_run_time", "updated_by", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save record archive policy: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除归档策略
+func (r *RecordArchiveRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RecordArchivePolicy{}).Error; err != nil {
+		return fmt.Errorf("failed to delete record archive policy: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取到期策略（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *RecordArchiveRepositoryImpl) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*recordarchive.Policy, error) {
+	var claimed []*recordarchive.Policy
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.RecordArchivePolicy{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.RecordArchivePolicy
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.RecordArchivePolicy{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromRecordArchivePolicyModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim record archive policies: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存执行结果并释放租约
+func (r *RecordArchiveRepositoryImpl) SaveProgress(ctx context.Context, policy *recordarchive.Policy) error {
+	if err := r.db.WithContext(ctx).Model(&models.RecordArchivePolicy{}).
+		Where("id = ?", policy.ID).
+		Updates(map[string]interface{}{
+			"next_run_time": policy.NextRunAt,
+			"last_run_time": policy.LastRunAt,
+			"last_archived": policy.LastArchived,
+			"last_error":    policy.LastError,
+			"locked_until":  nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save record archive progress: %w", err)
+	}
+	return nil
+}
+
+func fromRecordArchivePolicyModel(m *models.RecordArchivePolicy) *recordarchive.Policy {
+	return &recordarchive.Policy{
+		ID:           m.ID,
+		TableID:      m.TableID,
+		Basis:        m.Basis,
+		AfterDays:    m.AfterDays,
+		Enabled:      m.Enabled,
+		NextRunAt:    m.NextRunTime,
+		LastRunAt:    m.LastRunTime,
+		LastArchived: m.LastArchived,
+		LastError:    m.LastError,
+		CreatedBy:    m.CreatedBy,
+		UpdatedBy:    m.UpdatedBy,
+		CreatedAt:    m.CreatedTime,
+		UpdatedAt:    m.UpdatedTime,
+	}
+}
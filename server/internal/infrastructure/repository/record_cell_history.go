@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueobject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// projectCellChanges 把一次记录更新投影为逐单元格的修改
+// before 为变更前的物理行，data 为写入的物理值；计算字段不记录（由其他单元格驱动，并非编辑）。
+// 加密字段按明文比较是否修改，保存密文
+func (r *RecordRepositoryDynamic) projectCellChanges(
+	ctx context.Context,
+	baseID string,
+	record *entity.Record,
+	fields []*fieldEntity.Field,
+	before map[string]interface{},
+	data map[string]interface{},
+) []models.RecordCellChange {
+	var changes []models.RecordCellChange
+	now := time.Now()
+	for _, field := range fields {
+		if field.IsComputed() || field.IsVirtual() {
+			continue
+		}
+		dbFieldName := field.DBFieldName().String()
+		newValue, _ := record.Data().Get(field.ID().String())
+
+		change := models.RecordCellChange{
+			ID:        utils.GenerateIDWithPrefix("rcc"),
+			TableID:   record.TableID(),
+			RecordID:  record.ID().String(),
+			FieldID:   field.ID().String(),
+			Version:   record.Version().Value(),
+			ChangedBy: record.UpdatedBy(),
+			ChangedAt: now,
+		}
+		if field.IsEncrypted() {
+			// 旧值无法解密时按已修改处理
+			if oldValue, err := r.decryptCell(ctx, baseID, field, before[dbFieldName]); err == nil && sameCellValue(oldValue, newValue) {
+				continue
+			}
+			change.OldValue = snapshotCellValue(before[dbFieldName])
+			change.NewValue = snapshotCellValue(data[dbFieldName])
+			change.Encrypted = true
+		} else {
+			oldValue := r.convertValueFromDB(field, before[dbFieldName])
+			if sameCellValue(oldValue, newValue) {
+				continue
+			}
+			change.OldValue = snapshotCellValue(oldValue)
+			change.NewValue = snapshotCellValue(newValue)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// logCellChanges 写入单元格修改（与变更日志使用同一事务连接）
+func (r *RecordRepositoryDynamic) logCellChanges(ctx context.Context, db *gorm.DB, changes []models.RecordCellChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).CreateInBatches(changes, 500).Error; err != nil {
+		return fmt.Errorf("写入单元格修改记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListCellHistory 按版本倒序列出单元格的修改（加密字段的值解密后返回）
+func (r *RecordRepositoryDynamic) ListCellHistory(ctx context.Context, tableID, recordID, fieldID string, beforeVersion int64, limit int) ([]*recordRepo.CellChange, error) {
+	query := r.db.WithContext(ctx).
+		Where("table_id = ? AND record_id = ? AND field_id = ?", tableID, recordID, fieldID)
+	if beforeVersion > 0 {
+		query = query.Where("version < ?", beforeVersion)
+	}
+	var list []models.RecordCellChange
+	if err := query.Order("version DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("查询单元格修改记录失败: %w", err)
+	}
+
+	var field *fieldEntity.Field
+	var baseID string
+	for _, m := range list {
+		if m.Encrypted {
+			table, err := r.tableRepo.GetByID(ctx, tableID)
+			if err != nil {
+				return nil, fmt.Errorf("获取Table信息失败: %w", err)
+			}
+			if field, err = r.fieldRepo.FindByID(ctx, fieldValueobject.NewFieldID(fieldID)); err != nil {
+				return nil, fmt.Errorf("获取字段失败: %w", err)
+			}
+			if table != nil {
+				baseID = table.BaseID()
+			}
+			break
+		}
+	}
+
+	changes := make([]*recordRepo.CellChange, 0, len(list))
+	for _, m := range list {
+		change := &recordRepo.CellChange{
+			ID:        m.ID,
+			TableID:   m.TableID,
+			RecordID:  m.RecordID,
+			FieldID:   m.FieldID,
+			OldValue:  m.OldValue,
+			NewValue:  m.NewValue,
+			Version:   m.Version,
+			ChangedBy: m.ChangedBy,
+			ChangedAt: m.ChangedAt,
+		}
+		if m.Encrypted {
+			if field == nil {
+				change.OldValue, change.NewValue = fieldEntity.MaskedCellValue, fieldEntity.MaskedCellValue
+			} else {
+				var err error
+				if change.OldValue, err = r.decryptCell(ctx, baseID, field, m.OldValue); err != nil {
+					return nil, err
+				}
+				if change.NewValue, err = r.decryptCell(ctx, baseID, field, m.NewValue); err != nil {
+					return nil, err
+				}
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// PurgeCellHistory 删除过期的单元格修改记录
+func (r *RecordRepositoryDynamic) PurgeCellHistory(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("changed_at < ?", before).Delete(&models.RecordCellChange{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge record cell changes: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// snapshotCellValue 将单元格值转换为可 JSON 序列化的形式（与变更日志的物理行保持一致）
+func snapshotCellValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case datatypes.JSON:
+		var decoded interface{}
+		if err := json.Unmarshal(v, &decoded); err == nil {
+			return decoded
+		}
+		return string(v)
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// sameCellValue 比较修改前后的单元格值（按序列化结果比较，数字 3 与 3.0、不同时区的同一时刻视为相同）
+func sameCellValue(a, b interface{}) bool {
+	a, b = normalizeCellValue(a), normalizeCellValue(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aj) == string(bj)
+}
+
+// normalizeCellValue 统一空值与时间的表示：空字符串、空数组视为空
+func normalizeCellValue(value interface{}) interface{} {
+	switch v := snapshotCellValue(value).(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC().Format(time.RFC3339Nano)
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	default:
+		return v
+	}
+}
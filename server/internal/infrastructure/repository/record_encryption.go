@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/crypto"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// SetFieldEncryptor 设置字段加密器（启用加密字段的透明加解密）
+func (r *RecordRepositoryDynamic) SetFieldEncryptor(encryptor *crypto.FieldEncryptor) {
+	r.encryptor = encryptor
+}
+
+// HasEncryptedFields 表中是否存在加密字段（用于跳过明文缓存）
+func (r *RecordRepositoryDynamic) HasEncryptedFields(ctx context.Context, tableID string) bool {
+	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		// 无法确定时按存在处理，宁可不缓存
+		return true
+	}
+	for _, field := range fields {
+		if field.IsEncrypted() {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptCell 加密字段写入前加密（非加密字段原样返回）
+// 脱敏占位值不是真实数据，拒绝写入，避免以占位值的密文覆盖原有密文
+func (r *RecordRepositoryDynamic) encryptCell(ctx context.Context, baseID string, field *fieldEntity.Field, value interface{}) (interface{}, error) {
+	if !field.IsEncrypted() || value == nil {
+		return value, nil
+	}
+	if s, ok := value.(string); ok && s == fieldEntity.MaskedCellValue {
+		return nil, fmt.Errorf("字段 %s 的值为脱敏占位值，不能写入", field.ID().String())
+	}
+	if r.encryptor == nil {
+		return nil, fmt.Errorf("字段 %s 已加密，但未配置加密服务", field.ID().String())
+	}
+	return r.encryptor.Encrypt(ctx, baseID, field.ID().String(), value)
+}
+
+// decryptCell 加密字段读取后解密
+// 解密失败时返回错误而不是占位值：占位值一旦随记录再次保存，会覆盖原有密文导致数据永久丢失
+func (r *RecordRepositoryDynamic) decryptCell(ctx context.Context, baseID string, field *fieldEntity.Field, stored interface{}) (interface{}, error) {
+	if !field.IsEncrypted() || !crypto.IsCiphertext(stored) {
+		return stored, nil
+	}
+	if r.encryptor == nil {
+		return nil, fmt.Errorf("字段 %s 已加密，但未配置加密服务", field.ID().String())
+	}
+
+	value, err := r.encryptor.Decrypt(ctx, baseID, field.ID().String(), stored)
+	if err != nil {
+		logger.Error("解密字段值失败",
+			logger.String("field_id", field.ID().String()),
+			logger.ErrorField(err))
+		return nil, fmt.Errorf("解密字段 %s 失败: %w", field.ID().String(), err)
+	}
+	return value, nil
+}
+
+// logValue 日志输出用的字段值（加密字段按配置脱敏）
+func logValue(field *fieldEntity.Field, value interface{}) interface{} {
+	if value == nil || field.RevealsPlaintext(fieldEntity.RevealChannelLogs) {
+		return value
+	}
+	return fieldEntity.MaskedCellValue
+}
+
+// logRecordData 日志输出用的记录数据（key 为字段ID）
+func logRecordData(fields []*fieldEntity.Field, data map[string]interface{}) map[string]interface{} {
+	return fieldEntity.MaskEncryptedValues(fields, data, fieldEntity.RevealChannelLogs)
+}
+
+// logPhysicalData 日志输出用的物理表数据（key 为数据库列名）
+func logPhysicalData(fields []*fieldEntity.Field, data map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		masked[k] = v
+	}
+	for _, field := range fields {
+		column := field.DBFieldName().String()
+		if v, ok := masked[column]; ok {
+			masked[column] = logValue(field, v)
+		}
+	}
+	return masked
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldVO "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func newEncryptedTestField(t *testing.T) *fieldEntity.Field {
+	t.Helper()
+	name, _ := fieldVO.NewFieldName("身份证号")
+	fieldType, _ := fieldVO.NewFieldType(fieldVO.TypeText)
+	field, err := fieldEntity.NewField("tbl1", name, fieldType, "usr1")
+	if err != nil {
+		t.Fatalf("创建字段失败: %v", err)
+	}
+	if err := field.EnableEncryption(false, false); err != nil {
+		t.Fatalf("开启加密失败: %v", err)
+	}
+	return field
+}
+
+func TestDecryptCellFailureReturnsError(t *testing.T) {
+	if logger.Logger == nil {
+		logger.Logger = zap.NewNop()
+	}
+	r := &RecordRepositoryDynamic{}
+	field := newEncryptedTestField(t)
+
+	// 无法解密时返回错误，不能以脱敏占位值代替（占位值随记录保存会覆盖密文）
+	value, err := r.decryptCell(context.Background(), "bse1", field, "enc:v1:1:AAAA")
+	if err == nil || value != nil {
+		t.Fatalf("解密失败应返回错误，得到 %v, %v", value, err)
+	}
+
+	// 非密文原样返回
+	if value, err := r.decryptCell(context.Background(), "bse1", field, nil); err != nil || value != nil {
+		t.Fatalf("空值应原样返回，得到 %v, %v", value, err)
+	}
+}
+
+func TestEncryptCellRejectsMaskedPlaceholder(t *testing.T) {
+	r := &RecordRepositoryDynamic{}
+	field := newEncryptedTestField(t)
+
+	if _, err := r.encryptCell(context.Background(), "bse1", field, fieldEntity.MaskedCellValue); err == nil {
+		t.Fatal("脱敏占位值不应被加密写入")
+	}
+}
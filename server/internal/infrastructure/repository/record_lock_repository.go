@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordlock"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// RecordLockRepositoryImpl 记录锁GORM实现
+type RecordLockRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordLockRepository 创建记录锁仓储
+func NewRecordLockRepository(db *gorm.DB) recordlock.Repository {
+	return &RecordLockRepositoryImpl{db: db}
+}
+
+// Save 保存记录锁，并在同一事务中删除该持有者在同一记录上的其他锁
+func (r *RecordLockRepositoryImpl) Save(ctx context.Context, lock *recordlock.Lock) error {
+	model := models.RecordLock{
+		ID:          lock.ID,
+		TableID:     lock.TableID,
+		RecordID:    lock.RecordID,
+		OwnerID:     lock.OwnerID,
+		Reason:      lock.Reason,
+		ExpiresTime: lock.ExpiresAt,
+		CreatedTime: lock.CreatedAt,
+	}
+	if len(lock.FieldIDs) > 0 {
+		fieldIDs, err := json.Marshal(lock.FieldIDs)
+		if err != nil {
+			return err
+		}
+		model.FieldIDs = string(fieldIDs)
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("table_id = ? AND record_id = ? AND owner_id = ? AND id <> ?",
+			lock.TableID, lock.RecordID, lock.OwnerID, lock.ID).
+			Delete(&models.RecordLock{}).Error; err != nil {
+			return fmt.Errorf("failed to replace record lock: %w", err)
+		}
+		if err := tx.Save(&model).Error; err != nil {
+			return fmt.Errorf("failed to save record lock: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByID 获取记录锁
+func (r *RecordLockRepositoryImpl) FindByID(ctx context.Context, id string) (*recordlock.Lock, error) {
+	var model models.RecordLock
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record lock: %w", err)
+	}
+	return fromRecordLockModel(&model), nil
+}
+
+// ListActive 列出仍有效的记录锁
+func (r *RecordLockRepositoryImpl) ListActive(ctx context.Context, tableID string, recordIDs []string, now time.Time) ([]*recordlock.Lock, error) {
+	query := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Where("expires_time IS NULL OR expires_time > ?", now)
+	if len(recordIDs) > 0 {
+		query = query.Where("record_id IN ?", recordIDs)
+	}
+	var list []models.RecordLock
+	if err := query.Order("created_time ASC").Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list record locks: %w", err)
+	}
+	locks := make([]*recordlock.Lock, 0, len(list))
+	for i := range list {
+		locks = append(locks, fromRecordLockModel(&list[i]))
+	}
+	return locks, nil
+}
+
+// Delete 删除记录锁
+func (r *RecordLockRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RecordLock{}).Error; err != nil {
+		return fmt.Errorf("failed to delete record lock: %w", err)
+	}
+	return nil
+}
+
+func fromRecordLockModel(model *models.RecordLock) *recordlock.Lock {
+	lock := &recordlock.Lock{
+		ID:        model.ID,
+		TableID:   model.TableID,
+		RecordID:  model.RecordID,
+		OwnerID:   model.OwnerID,
+		Reason:    model.Reason,
+		ExpiresAt: model.ExpiresTime,
+		CreatedAt: model.CreatedTime,
+	}
+	if model.FieldIDs != "" {
+		_ = json.Unmarshal([]byte(model.FieldIDs), &lock.FieldIDs)
+	}
+	if lock.FieldIDs == nil {
+		lock.FieldIDs = []string{}
+	}
+	return lock
+}
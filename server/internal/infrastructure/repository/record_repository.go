@@ -68,7 +68,8 @@ func (r *RecordRepositoryImpl) FindByID(ctx context.Context, id valueobject.Reco
 
 // FindByIDs 根据ID列表查找记录（需要提供 tableID）
 // ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
-func (r *RecordRepositoryImpl) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID) ([]*entity.Record, error) {
+// 记录数据整体存储在一列中，字段投影在读取后进行
+func (r *RecordRepositoryImpl) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID, fieldIDs ...string) ([]*entity.Record, error) {
 	if len(ids) == 0 {
 		return []*entity.Record{}, nil
 	}
@@ -90,7 +91,11 @@ func (r *RecordRepositoryImpl) FindByIDs(ctx context.Context, tableID string, id
 		return nil, fmt.Errorf("failed to find records: %w", err)
 	}
 
-	return mapper.ToRecordList(dbRecords)
+	records, err := mapper.ToRecordList(dbRecords)
+	if err != nil {
+		return nil, err
+	}
+	return projectRecords(records, fieldIDs)
 }
 
 // FindByTableAndID 根据表ID和记录ID查找单条记录
@@ -140,6 +145,12 @@ func (r *RecordRepositoryImpl) DeleteByTableAndID(ctx context.Context, tableID s
 		Update("deleted_time", gorm.Expr("NOW()")).Error
 }
 
+// DeleteByTableAndIDWithVersion 按版本删除记录（软删除）
+func (r *RecordRepositoryImpl) DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) error {
+	// 目前数据库模型没有版本字段，暂时忽略版本检查
+	return r.DeleteByTableAndID(ctx, tableID, id)
+}
+
 // Exists 检查记录是否存在
 func (r *RecordRepositoryImpl) Exists(ctx context.Context, id valueobject.RecordID) (bool, error) {
 	var count int64
@@ -204,10 +215,29 @@ func (r *RecordRepositoryImpl) List(ctx context.Context, filter repository.Recor
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to convert records: %w", err)
 	}
+	if records, err = projectRecords(records, filter.FieldIDs); err != nil {
+		return nil, 0, err
+	}
 
 	return records, total, nil
 }
 
+// projectRecords 在内存中对记录做字段投影（fieldIDs 为空时原样返回）
+func projectRecords(records []*entity.Record, fieldIDs []string) ([]*entity.Record, error) {
+	if len(fieldIDs) == 0 {
+		return records, nil
+	}
+	projected := make([]*entity.Record, 0, len(records))
+	for _, record := range records {
+		p, err := record.Project(fieldIDs)
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, p)
+	}
+	return projected, nil
+}
+
 // BatchSave 批量保存记录
 func (r *RecordRepositoryImpl) BatchSave(ctx context.Context, records []*entity.Record) error {
 	if len(records) == 0 {
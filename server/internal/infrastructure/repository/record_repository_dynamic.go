@@ -9,13 +9,16 @@ import (
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/cellvalue"
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/crypto"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
 	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
@@ -29,6 +32,36 @@ type RecordRepositoryDynamic struct {
 	tableRepo  tableRepo.TableRepository
 	fieldRepo  repository.FieldRepository
 	fieldCache *FieldMappingCache // ✅ 字段映射缓存
+
+	// regionRouter 数据驻留路由（可选）：物理表按工作空间所在区域路由
+	regionRouter *database.RegionRouter
+
+	// encryptor 加密字段的信封加密器（可选）
+	encryptor *crypto.FieldEncryptor
+}
+
+// SetRegionRouter 设置数据驻留路由器
+func (r *RecordRepositoryDynamic) SetRegionRouter(router *database.RegionRouter) {
+	r.regionRouter = router
+}
+
+// dataDB 获取物理表（数据面）所在的数据库连接
+// 未启用数据驻留时即为主库
+func (r *RecordRepositoryDynamic) dataDB(ctx context.Context, baseID string) *gorm.DB {
+	if r.regionRouter == nil {
+		return r.db
+	}
+	return r.regionRouter.DBForBase(ctx, baseID)
+}
+
+// txDataDB 获取写入物理表的数据库连接并参与上下文中的事务
+// 数据在主库时复用事务客户端；位于其他区域时该区域连接加入同一事务，随主事务提交或回滚
+func (r *RecordRepositoryDynamic) txDataDB(ctx context.Context, baseID string) (*gorm.DB, error) {
+	db := r.dataDB(ctx, baseID)
+	if db == r.db {
+		return pkgDatabase.WithTx(ctx, r.db), nil
+	}
+	return pkgDatabase.JoinTx(ctx, db)
 }
 
 // GetDB 获取数据库连接（用于事务管理）
@@ -90,11 +123,14 @@ func (r *RecordRepositoryDynamic) FindTableIDByRecordID(ctx context.Context, rec
 		baseID := table.BaseID
 
 		// 3. 构建物理表名
-		fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+		fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+		if err != nil {
+			continue
+		}
 
 		// 4. 查询该表是否包含该记录
 		var count int64
-		err := r.db.WithContext(ctx).
+		err = r.dataDB(ctx, baseID).WithContext(ctx).
 			Table(fullTableName).
 			Where("__id = ?", recordIDStr).
 			Count(&count).Error
@@ -109,12 +145,12 @@ func (r *RecordRepositoryDynamic) FindTableIDByRecordID(ctx context.Context, rec
 		}
 	}
 
-	return "", fmt.Errorf("记录不存在: %s", recordIDStr)
+	return "", errors.ErrRecordNotFound.WithDetails(recordIDStr)
 }
 
 // FindByIDs 根据ID列表查询记录（需要提供 tableID）
 // ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
-func (r *RecordRepositoryDynamic) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID) ([]*entity.Record, error) {
+func (r *RecordRepositoryDynamic) FindByIDs(ctx context.Context, tableID string, ids []valueobject.RecordID, fieldIDs ...string) ([]*entity.Record, error) {
 	if len(ids) == 0 {
 		return []*entity.Record{}, nil
 	}
@@ -140,9 +176,13 @@ func (r *RecordRepositoryDynamic) FindByIDs(ctx context.Context, tableID string,
 	if err != nil {
 		return nil, fmt.Errorf("获取字段列表失败: %w", err)
 	}
+	fields = projectFields(fields, fieldIDs)
 
 	// 3. ✅ 从物理表查询（使用完整表名）
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return nil, err
+	}
 
 	// 构建 SELECT 列
 	selectCols := []string{
@@ -168,7 +208,7 @@ func (r *RecordRepositoryDynamic) FindByIDs(ctx context.Context, tableID string,
 
 	// 查询指定 ID 的记录
 	var results []map[string]interface{}
-	err = r.db.WithContext(ctx).
+	err = r.dataDB(ctx, baseID).WithContext(ctx).
 		Table(fullTableName).
 		Select(selectCols).
 		Where("__id IN ?", recordIDStrs).
@@ -202,7 +242,7 @@ func (r *RecordRepositoryDynamic) FindByIDs(ctx context.Context, tableID string,
 	records := make([]*entity.Record, 0, len(results))
 	for _, result := range results {
 		// 使用辅助方法转换
-		record, err := r.toDomainEntity(result, fields, tableID)
+		record, err := r.toDomainEntity(ctx, result, fields, baseID, tableID)
 		if err != nil {
 			logger.Warn("转换记录实体失败，跳过",
 				logger.String("record_id", fmt.Sprintf("%v", result["__id"])),
@@ -257,7 +297,10 @@ func (r *RecordRepositoryDynamic) FindByTableID(ctx context.Context, tableID str
 	}
 
 	// 3. ✅ 从物理表查询列表（使用完整表名）
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return nil, err
+	}
 
 	// 构建 SELECT 列
 	selectCols := []string{
@@ -277,7 +320,7 @@ func (r *RecordRepositoryDynamic) FindByTableID(ctx context.Context, tableID str
 
 	// 查询所有记录
 	var results []map[string]interface{}
-	if err := r.db.WithContext(ctx).
+	if err := r.dataDB(ctx, baseID).WithContext(ctx).
 		Table(fullTableName).
 		Select(selectCols).
 		Find(&results).Error; err != nil {
@@ -292,7 +335,7 @@ func (r *RecordRepositoryDynamic) FindByTableID(ctx context.Context, tableID str
 	// 5. 转换为 Domain 实体列表
 	records := make([]*entity.Record, 0, len(results))
 	for _, result := range results {
-		record, err := r.toDomainEntity(result, fields, tableID)
+		record, err := r.toDomainEntity(ctx, result, fields, baseID, tableID)
 		if err != nil {
 			logger.Warn("转换记录失败，跳过",
 				logger.String("record_id", fmt.Sprintf("%v", result["__id"])),
@@ -327,20 +370,23 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 		logger.String("table_id", tableID),
 		logger.Int64("version", record.Version().Value()))
 
-	// ✅ 关键修复：使用事务数据库连接（如果存在）
-	db := pkgDatabase.WithTx(ctx, r.db)
-
 	// 1. 获取 Table 信息
 	table, err := r.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
 		return fmt.Errorf("获取Table信息失败: %w", err)
 	}
 	if table == nil {
-		return fmt.Errorf("Table不存在: %s", tableID)
+		return errors.ErrTableNotFound.WithDetails(tableID)
 	}
 
 	baseID := table.BaseID()
 
+	// ✅ 关键修复：使用事务数据库连接（如果存在），数据位于其他区域时加入同一事务
+	db, err := r.txDataDB(ctx, baseID)
+	if err != nil {
+		return err
+	}
+
 	// 2. 获取字段列表
 	logger.Info("🔍 Save 方法：准备调用 FindByTableID",
 		logger.String("record_id", record.ID().String()),
@@ -368,20 +414,24 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 		}()))
 
 	// 3. ✅ 构建数据映射（使用完整表名）
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return err
+	}
 
-	// 5. ✅ 检查记录是否已存在（用于判断INSERT还是UPDATE）
-	var count int64
+	// 5. ✅ 读取当前行（用于判断INSERT还是UPDATE，并作为变更日志的变更前状态）
+	var existingRows []map[string]interface{}
 	err = db.WithContext(ctx).
 		Table(fullTableName).
 		Where("__id = ?", record.ID().String()).
-		Count(&count).Error
+		Limit(1).
+		Find(&existingRows).Error
 
 	if err != nil {
 		return fmt.Errorf("检查记录是否存在失败: %w", err)
 	}
 
-	isNewRecord := count == 0
+	isNewRecord := len(existingRows) == 0
 
 	// 构建数据
 	data := make(map[string]interface{})
@@ -408,7 +458,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 	// ✅ 添加详细日志：记录保存前的字段数据（使用 Info 级别以便调试）
 	logger.Info("记录保存前的字段数据",
 		logger.String("record_id", record.ID().String()),
-		logger.Any("record_data", recordData.ToMap()),
+		logger.Any("record_data", logRecordData(fields, recordData.ToMap())),
 		logger.Int("field_count", len(fields)))
 
 	for _, field := range fields {
@@ -424,7 +474,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 			logger.String("db_field_name", dbFieldName),
 			logger.String("field_type", field.Type().String()),
 			logger.String("db_field_type", field.DBFieldType()),
-			logger.Any("value", value),
+			logger.Any("value", logValue(field, value)),
 			logger.Bool("exists", exists))
 
 		// ✅ 关键修复：使用字段实体的类型转换方法（参考 teable 设计）
@@ -436,13 +486,21 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 			convertedValue = r.wrapJSONBValue(convertedValue)
 		}
 
+		// ✅ 加密字段：以原始单元格值加密后存储为密文
+		if field.IsEncrypted() {
+			convertedValue, err = r.encryptCell(ctx, baseID, field, value)
+			if err != nil {
+				return fmt.Errorf("加密字段值失败: %w", err)
+			}
+		}
+
 		data[dbFieldName] = convertedValue
-		
+
 		// ✅ 添加详细日志：转换后的值（使用 Info 级别以便调试）
 		logger.Info("字段值转换完成",
 			logger.String("field_id", fieldID),
 			logger.String("db_field_name", dbFieldName),
-			logger.Any("converted_value", convertedValue))
+			logger.Any("converted_value", logValue(field, convertedValue)))
 	}
 
 	// ✅ 添加详细日志：最终保存的数据（使用 Info 级别以便调试）
@@ -450,7 +508,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 		logger.String("record_id", record.ID().String()),
 		logger.String("table_id", tableID),
 		logger.String("physical_table", fullTableName),
-		logger.Any("data", data),
+		logger.Any("data", logPhysicalData(fields, data)),
 		logger.Int("field_count", len(fields)),
 		logger.Int("data_keys_count", len(data)))
 
@@ -472,7 +530,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 				logger.String("record_id", record.ID().String()),
 				logger.String("physical_table", fullTableName),
 				logger.ErrorField(result.Error),
-				logger.Any("data", data))
+				logger.Any("data", logPhysicalData(fields, data)))
 		} else {
 			logger.Debug("INSERT 操作成功",
 				logger.String("record_id", record.ID().String()),
@@ -499,7 +557,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 			logger.String("physical_table", fullTableName),
 			logger.Bool("is_new", isNewRecord),
 			logger.ErrorField(result.Error),
-			logger.Any("data", data))
+			logger.Any("data", logPhysicalData(fields, data)))
 		// 使用约束错误处理工具
 		constraintErr := pkgDatabase.HandleDBConstraintError(result.Error, tableID, r.fieldRepo, ctx)
 		return constraintErr
@@ -520,7 +578,7 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 			logger.String("record_id", record.ID().String()),
 			logger.Int64("expected_version", record.Version().Value()-1))
 
-		return errors.ErrConflict.WithDetails(map[string]interface{}{
+		return errors.ErrVersionConflict.WithDetails(map[string]interface{}{
 			"type":             "version_conflict",
 			"message":          "记录已被其他用户修改，请刷新后重试",
 			"record_id":        record.ID().String(),
@@ -537,6 +595,21 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 
 	// ✅ 记录保存到物理表完成（对齐 Teable：不使用 record_meta）
 
+	// 9. ✅ 写入变更日志（快照读取依赖变更前状态），更新时同时写入逐单元格的修改（单元格历史）
+	change := newRecordChange(tableID, record.ID().String(), recordChangeCreate, nil, record.Version().Value(), record.UpdatedBy())
+	if !isNewRecord {
+		change = newRecordChange(tableID, record.ID().String(), recordChangeUpdate, existingRows[0], record.Version().Value(), record.UpdatedBy())
+	}
+	if err := r.logRecordChanges(ctx, pkgDatabase.WithTx(ctx, r.db), change); err != nil {
+		return err
+	}
+	if !isNewRecord {
+		cells := r.projectCellChanges(ctx, baseID, record, fields, existingRows[0], data)
+		if err := r.logCellChanges(ctx, pkgDatabase.WithTx(ctx, r.db), cells); err != nil {
+			return err
+		}
+	}
+
 	logger.Info("✅ 记录保存成功（物理表+乐观锁）",
 		logger.String("record_id", record.ID().String()),
 		logger.String("table_id", tableID),
@@ -561,6 +634,17 @@ func (r *RecordRepositoryDynamic) Delete(ctx context.Context, id valueobject.Rec
 // DeleteByTableAndID 根据表ID和记录ID删除记录（从物理表删除）
 // ✅ 对齐 Teable 架构：所有记录操作都需要 tableID
 func (r *RecordRepositoryDynamic) DeleteByTableAndID(ctx context.Context, tableID string, id valueobject.RecordID) error {
+	return r.deleteByTableAndID(ctx, tableID, id, nil)
+}
+
+// DeleteByTableAndIDWithVersion 按版本删除记录（DELETE ... WHERE __version = ?，检查与删除在同一条语句中完成）
+func (r *RecordRepositoryDynamic) DeleteByTableAndIDWithVersion(ctx context.Context, tableID string, id valueobject.RecordID, version valueobject.RecordVersion) error {
+	expected := version.Value()
+	return r.deleteByTableAndID(ctx, tableID, id, &expected)
+}
+
+// deleteByTableAndID 从物理表删除记录，expectedVersion 非空时只删除该版本
+func (r *RecordRepositoryDynamic) deleteByTableAndID(ctx context.Context, tableID string, id valueobject.RecordID, expectedVersion *int64) error {
 	logger.Info("正在从物理表删除记录",
 		logger.String("table_id", tableID),
 		logger.String("record_id", id.String()))
@@ -571,24 +655,66 @@ func (r *RecordRepositoryDynamic) DeleteByTableAndID(ctx context.Context, tableI
 		return fmt.Errorf("获取Table信息失败: %w", err)
 	}
 	if table == nil {
-		return fmt.Errorf("Table不存在: %s", tableID)
+		return errors.ErrTableNotFound.WithDetails(tableID)
 	}
 
 	baseID := table.BaseID()
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return err
+	}
 
-	// 2. 从物理表删除记录
-	err = r.db.WithContext(ctx).
+	// 使用事务数据库连接（如果存在），数据位于其他区域时加入同一事务
+	db, err := r.txDataDB(ctx, baseID)
+	if err != nil {
+		return err
+	}
+
+	// 2. 读取删除前的行（变更日志的变更前状态）
+	var existingRows []map[string]interface{}
+	if err := db.WithContext(ctx).
 		Table(fullTableName).
 		Where("__id = ?", id.String()).
-		Delete(nil).Error
+		Limit(1).
+		Find(&existingRows).Error; err != nil {
+		return fmt.Errorf("查询待删除记录失败: %w", err)
+	}
 
-	if err != nil {
+	// 3. 从物理表删除记录
+	query := db.WithContext(ctx).
+		Table(fullTableName).
+		Where("__id = ?", id.String())
+	if expectedVersion != nil {
+		query = query.Where("__version = ?", *expectedVersion)
+	}
+	result := query.Delete(nil)
+
+	if result.Error != nil {
 		logger.Error("从物理表删除记录失败",
 			logger.String("table_id", tableID),
 			logger.String("record_id", id.String()),
-			logger.ErrorField(err))
-		return err
+			logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if expectedVersion != nil && result.RowsAffected == 0 {
+		return errors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"type":             "version_conflict",
+			"message":          "记录已被其他用户修改，请刷新后重试",
+			"record_id":        id.String(),
+			"expected_version": *expectedVersion,
+		})
+	}
+
+	// 4. 写入变更日志
+	if len(existingRows) > 0 {
+		var version int64
+		if v, ok := existingRows[0]["__version"].(int64); ok {
+			version = v
+		}
+		change := newRecordChange(tableID, id.String(), recordChangeDelete, existingRows[0], version, "")
+		if err := r.logRecordChanges(ctx, pkgDatabase.WithTx(ctx, r.db), change); err != nil {
+			return err
+		}
 	}
 
 	logger.Info("✅ 从物理表删除记录成功",
@@ -617,11 +743,14 @@ func (r *RecordRepositoryDynamic) CountByTableID(ctx context.Context, tableID st
 	}
 
 	baseID := table.BaseID()
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return 0, err
+	}
 
 	// 2. 从物理表统计
 	var count int64
-	if err := r.db.WithContext(ctx).
+	if err := r.dataDB(ctx, baseID).WithContext(ctx).
 		Table(fullTableName).
 		Count(&count).Error; err != nil {
 		return 0, fmt.Errorf("统计记录数量失败: %w", err)
@@ -643,7 +772,11 @@ func (r *RecordRepositoryDynamic) FindWithVersion(ctx context.Context, tableID s
 
 	// 检查版本是否匹配
 	if record.Version().Value() != expectedVersion.Value() {
-		return nil, fmt.Errorf("版本不匹配: 期望 %d, 实际 %d", expectedVersion.Value(), record.Version().Value())
+		return nil, errors.ErrVersionConflict.WithDetails(map[string]interface{}{
+			"record_id":        record.ID().String(),
+			"expected_version": expectedVersion.Value(),
+			"actual_version":   record.Version().Value(),
+		})
 	}
 
 	return record, nil
@@ -653,7 +786,7 @@ func (r *RecordRepositoryDynamic) FindWithVersion(ctx context.Context, tableID s
 func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.RecordFilter) ([]*entity.Record, int64, error) {
 	// 1. 提取 tableID
 	if filter.TableID == nil {
-		return nil, 0, fmt.Errorf("TableID is required")
+		return nil, 0, errors.ErrBadRequest.WithDetails("TableID is required")
 	}
 	tableID := *filter.TableID
 
@@ -669,7 +802,7 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 		return nil, 0, fmt.Errorf("获取Table信息失败: %w", err)
 	}
 	if table == nil {
-		return nil, 0, fmt.Errorf("Table不存在: %s", tableID)
+		return nil, 0, errors.ErrTableNotFound.WithDetails(tableID)
 	}
 
 	baseID := table.BaseID()
@@ -679,10 +812,14 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 	if err != nil {
 		return nil, 0, fmt.Errorf("获取字段列表失败: %w", err)
 	}
+	fields = projectFields(fields, filter.FieldIDs)
 
 	// 5. ✅ 从物理表查询（带分页和过滤）
 	// 使用完整表名（包含schema）："baseID"."tableID"
-	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	// 构建 SELECT 列
 	selectCols := []string{
@@ -701,7 +838,7 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 	}
 
 	// 构建查询
-	query := r.db.WithContext(ctx).
+	query := r.dataDB(ctx, baseID).WithContext(ctx).
 		Table(fullTableName).
 		Select(selectCols)
 
@@ -750,7 +887,7 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 	// 7. 转换为 Domain 实体列表
 	records := make([]*entity.Record, 0, len(results))
 	for _, result := range results {
-		record, err := r.toDomainEntity(result, fields, tableID)
+		record, err := r.toDomainEntity(ctx, result, fields, baseID, tableID)
 		if err != nil {
 			logger.Warn("转换记录失败，跳过",
 				logger.String("record_id", fmt.Sprintf("%v", result["__id"])),
@@ -763,6 +900,25 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 	return records, total, nil
 }
 
+// projectFields 字段投影：只保留 fieldIDs 中的字段（按表中字段顺序），fieldIDs 为空时返回全部字段
+// 物理表只查询保留字段的列，未知的字段ID被忽略
+func projectFields(fields []*fieldEntity.Field, fieldIDs []string) []*fieldEntity.Field {
+	if len(fieldIDs) == 0 {
+		return fields
+	}
+	wanted := make(map[string]bool, len(fieldIDs))
+	for _, id := range fieldIDs {
+		wanted[id] = true
+	}
+	projected := make([]*fieldEntity.Field, 0, len(fieldIDs))
+	for _, field := range fields {
+		if wanted[field.ID().String()] {
+			projected = append(projected, field)
+		}
+	}
+	return projected
+}
+
 // NextID 生成下一个记录ID
 func (r *RecordRepositoryDynamic) NextID() valueobject.RecordID {
 	return valueobject.NewRecordID("")
@@ -778,8 +934,10 @@ func (r *RecordRepositoryDynamic) Exists(ctx context.Context, id valueobject.Rec
 
 // toDomainEntity 将物理表查询结果转换为 Domain 实体
 func (r *RecordRepositoryDynamic) toDomainEntity(
+	ctx context.Context,
 	result map[string]interface{},
 	fields []*fieldEntity.Field,
+	baseID string,
 	tableID string,
 ) (*entity.Record, error) {
 	// 提取系统字段
@@ -824,13 +982,23 @@ func (r *RecordRepositoryDynamic) toDomainEntity(
 				logger.String("field_id", fieldID),
 				logger.String("db_field_name", dbFieldName),
 				logger.String("field_type", field.Type().String()),
-				logger.Any("raw_value", value),
+				logger.Any("raw_value", logValue(field, value)),
 				logger.String("value_type", fmt.Sprintf("%T", value)))
-			
+
+			// ✅ 加密字段先解密，得到原始单元格值
+			if field.IsEncrypted() {
+				decrypted, err := r.decryptCell(ctx, baseID, field, value)
+				if err != nil {
+					return nil, err
+				}
+				data[fieldID] = decrypted
+				continue
+			}
+
 			// 转换值（从数据库类型到应用类型）
 			convertedValue := r.convertValueFromDB(field, value)
 			data[fieldID] = convertedValue
-			
+
 			logger.Debug("字段值转换完成",
 				logger.String("field_id", fieldID),
 				logger.Any("converted_value", convertedValue))
@@ -846,7 +1014,7 @@ func (r *RecordRepositoryDynamic) toDomainEntity(
 	logger.Debug("记录数据转换完成",
 		logger.String("record_id", fmt.Sprintf("%v", result["__id"])),
 		logger.Int("data_field_count", len(data)),
-		logger.Any("data", data))
+		logger.Any("data", logRecordData(fields, data)))
 
 	recordData, err := valueobject.NewRecordData(data)
 	if err != nil {
@@ -946,7 +1114,7 @@ func (r *RecordRepositoryDynamic) convertValueForDB(field *fieldEntity.Field, va
 		}
 		return false
 
-	case "number", "rating", "percent", "currency", "rollup":
+	case "number", "rating", "percent", "currency", "duration", "progress", "rollup":
 		// 数字类型
 		return value
 
@@ -964,73 +1132,21 @@ func (r *RecordRepositoryDynamic) convertValueForDB(field *fieldEntity.Field, va
 }
 
 // convertValueFromDB 将数据库值转换为应用层值
+// 按字段类型解析为类型化单元格值（JSON 列解码、NUMERIC 读出的字符串转为数字等），无法解析时原样返回
 func (r *RecordRepositoryDynamic) convertValueFromDB(field *fieldEntity.Field, value interface{}) interface{} {
 	if value == nil {
 		return nil
 	}
 
-	fieldType := field.Type().String()
-
-	switch fieldType {
-	case "multipleSelect", "user", "attachment", "link", "lookup":
-		// ✅ JSONB 类型：需要从JSON反序列化
-		// GORM 可能返回 []byte 或 string 类型的 JSON 数据
-		var result interface{}
-
-		switch v := value.(type) {
-		case []byte:
-			if err := json.Unmarshal(v, &result); err != nil {
-				logger.Error("从JSON反序列化字段值失败",
-					logger.String("field_id", field.ID().String()),
-					logger.String("field_type", fieldType),
-					logger.ErrorField(err))
-				return nil
-			}
-		case string:
-			if err := json.Unmarshal([]byte(v), &result); err != nil {
-				logger.Error("从JSON反序列化字段值失败",
-					logger.String("field_id", field.ID().String()),
-					logger.String("field_type", fieldType),
-					logger.ErrorField(err))
-				return nil
-			}
-		case datatypes.JSON:
-			if err := json.Unmarshal(v, &result); err != nil {
-				logger.Error("从JSON反序列化字段值失败",
-					logger.String("field_id", field.ID().String()),
-					logger.String("field_type", fieldType),
-					logger.ErrorField(err))
-				return nil
-			}
-		default:
-			// 如果 GORM 已经反序列化了，直接返回
-			return value
-		}
-
-		return result
-
-	case "checkbox":
-		// 布尔类型
-		if b, ok := value.(bool); ok {
-			return b
-		}
-		return false
-
-	case "number", "rating", "percent", "currency", "rollup":
-		// 数字类型
-		return value
-
-	case "date", "createdTime", "lastModifiedTime":
-		// 时间类型
-		if t, ok := value.(time.Time); ok {
-			return t
-		}
-		return value
-
-	default:
-		// 文本类型
+	cell, err := cellvalue.FromStorage(field.Type().String(), value)
+	if err != nil {
+		logger.Error("解析字段存储值失败",
+			logger.String("field_id", field.ID().String()),
+			logger.String("field_type", field.Type().String()),
+			logger.ErrorField(err))
 		return value
 	}
+	return cell.API()
 }
 
 // ==================== 批量操作方法 ====================
@@ -1060,7 +1176,7 @@ func (r *RecordRepositoryDynamic) BatchCreate(ctx context.Context, records []*en
 		return fmt.Errorf("获取Table信息失败: %w", err)
 	}
 	if table == nil {
-		return fmt.Errorf("Table不存在: %s", tableID)
+		return errors.ErrTableNotFound.WithDetails(tableID)
 	}
 
 	baseID := table.BaseID()
@@ -1071,10 +1187,17 @@ func (r *RecordRepositoryDynamic) BatchCreate(ctx context.Context, records []*en
 		return fmt.Errorf("获取字段列表失败: %w", err)
 	}
 
-	// 3. ✅ 开启事务（原子性保证）
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	// 3. ✅ 开启事务（原子性保证；上下文已有事务时作为其子事务）
+	db, err := r.txDataDB(ctx, baseID)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 3.1 使用完整表名（包含schema）："baseID"."tableID"
-		fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
+		fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+		if err != nil {
+			return err
+		}
 
 		// 3.2 批量插入到物理表
 		dataList := make([]map[string]interface{}, 0, len(records))
@@ -1093,6 +1216,14 @@ func (r *RecordRepositoryDynamic) BatchCreate(ctx context.Context, records []*en
 				fieldID := field.ID().String()
 				dbFieldName := field.DBFieldName().String()
 				value, _ := recordData.Get(fieldID)
+				if field.IsEncrypted() {
+					encrypted, err := r.encryptCell(ctx, baseID, field, value)
+					if err != nil {
+						return fmt.Errorf("加密字段值失败: %w", err)
+					}
+					data[dbFieldName] = encrypted
+					continue
+				}
 				data[dbFieldName] = r.convertValueForDB(field, value)
 			}
 
@@ -1104,7 +1235,16 @@ func (r *RecordRepositoryDynamic) BatchCreate(ctx context.Context, records []*en
 			return fmt.Errorf("批量插入物理表失败: %w", err)
 		}
 
-		return nil
+		// 3.4 写入变更日志（数据与日志同在主库时使用同一事务）
+		logDB := tx
+		if r.dataDB(ctx, baseID) != r.db {
+			logDB = pkgDatabase.WithTx(ctx, r.db)
+		}
+		changes := make([]models.RecordChange, 0, len(records))
+		for _, record := range records {
+			changes = append(changes, newRecordChange(tableID, record.ID().String(), recordChangeCreate, nil, record.Version().Value(), record.CreatedBy()))
+		}
+		return r.logRecordChanges(ctx, logDB, changes...)
 	})
 }
 
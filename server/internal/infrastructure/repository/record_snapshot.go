@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 记录变更类型
+const (
+	recordChangeCreate = "create"
+	recordChangeUpdate = "update"
+	recordChangeDelete = "delete"
+)
+
+// newRecordChange 构造变更日志
+// before 为变更前的物理行（新建记录为 nil），加密字段保留密文
+func newRecordChange(tableID, recordID, changeType string, before map[string]interface{}, version int64, changedBy string) models.RecordChange {
+	return models.RecordChange{
+		ID:         utils.GenerateIDWithPrefix("rch"),
+		RecordID:   recordID,
+		TableID:    tableID,
+		ChangeType: changeType,
+		OldData:    snapshotRow(before),
+		ChangedBy:  changedBy,
+		ChangedAt:  time.Now(),
+		Version:    version,
+	}
+}
+
+// logRecordChanges 写入变更日志
+// 调用方传入与数据写入相同的事务连接，保证变更日志与数据一起提交或回滚
+func (r *RecordRepositoryDynamic) logRecordChanges(ctx context.Context, db *gorm.DB, changes ...models.RecordChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := db.WithContext(ctx).CreateInBatches(changes, 500).Error; err != nil {
+		return fmt.Errorf("写入记录变更日志失败: %w", err)
+	}
+	return nil
+}
+
+// ListAsOf 读取 asOf 时刻的记录
+// 当前数据与变更日志在同一个可重复读的只读事务中读取，两次查询看到一致的数据库状态
+func (r *RecordRepositoryDynamic) ListAsOf(ctx context.Context, tableID string, asOf time.Time, after int64, limit, maxChanges int) (*recordRepo.SnapshotPage, error) {
+	table, err := r.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取Table信息失败: %w", err)
+	}
+	if table == nil {
+		return nil, errors.ErrTableNotFound.WithDetails(tableID)
+	}
+	baseID := table.BaseID()
+
+	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取字段列表失败: %w", err)
+	}
+	fullTableName, err := r.dbProvider.ResolveTableName(ctx, baseID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	selectCols := []string{
+		"__id",
+		"__auto_number",
+		"__created_time",
+		"__created_by",
+		"__last_modified_time",
+		"__last_modified_by",
+		"__version",
+	}
+	for _, field := range fields {
+		selectCols = append(selectCols, field.DBFieldName().String())
+	}
+
+	var rows []map[string]interface{}
+	var total int64
+	read := func(dataTx, logTx *gorm.DB) error {
+		// 1. asOf 之后每条记录的首次变更（其变更前状态即 asOf 时刻的状态）
+		var changes []models.RecordChange
+		if err := logTx.Model(&models.RecordChange{}).
+			Select("DISTINCT ON (record_id) record_id, change_type, old_data, changed_at").
+			Where("table_id = ? AND changed_at > ?", tableID, asOf).
+			Order("record_id, changed_at ASC").
+			Limit(maxChanges + 1).
+			Find(&changes).Error; err != nil {
+			return fmt.Errorf("查询记录变更日志失败: %w", err)
+		}
+		if len(changes) > maxChanges {
+			return record.ErrSnapshotTooOld
+		}
+
+		affected := make([]string, 0, len(changes))
+		var before []map[string]interface{}
+		for _, change := range changes {
+			affected = append(affected, change.RecordID)
+			if change.ChangeType != recordChangeCreate && change.OldData != nil {
+				before = append(before, restoreSnapshotRow(change.OldData))
+			}
+		}
+
+		// 2. 当前数据中未变更的部分
+		current := dataTx.Table(fullTableName)
+		if len(affected) > 0 {
+			current = current.Where("__id NOT IN ?", affected)
+		}
+		var unchanged int64
+		if err := current.Session(&gorm.Session{}).Count(&unchanged).Error; err != nil {
+			return fmt.Errorf("统计记录数量失败: %w", err)
+		}
+		total = unchanged + int64(len(before))
+
+		var currentRows []map[string]interface{}
+		if err := current.Select(selectCols).
+			Where("__auto_number > ?", after).
+			Order("__auto_number ASC").
+			Limit(limit + 1).
+			Find(&currentRows).Error; err != nil {
+			return fmt.Errorf("查询记录失败: %w", err)
+		}
+
+		rows = mergeSnapshotRows(currentRows, before, after, limit+1)
+		return nil
+	}
+
+	dataDB := r.dataDB(ctx, baseID)
+	if dataDB == r.db {
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return read(tx, tx)
+		}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	} else {
+		// 数据位于其他区域：变更日志与数据分属两个库，无法在同一事务中读取
+		err = read(dataDB.WithContext(ctx), r.db.WithContext(ctx))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := &recordRepo.SnapshotPage{Total: total}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.NextCursor = snapshotAutoNumber(rows[len(rows)-1])
+	}
+	page.Records = make([]*entity.Record, 0, len(rows))
+	for _, row := range rows {
+		rec, err := r.toDomainEntity(ctx, row, fields, baseID, tableID)
+		if err != nil {
+			return nil, fmt.Errorf("转换快照记录失败: %w", err)
+		}
+		page.Records = append(page.Records, rec)
+	}
+	return page, nil
+}
+
+// StatesAsOf 读取 asOf 之后变更过的记录在 asOf 时刻的状态
+// 每条记录在 asOf 之后的首次变更保存了变更前的物理行；首次变更为新建时记录在 asOf 时尚不存在
+func (r *RecordRepositoryDynamic) StatesAsOf(ctx context.Context, tableID string, recordIDs []string, asOf time.Time, maxChanges int) ([]*recordRepo.RecordStateAsOf, error) {
+	table, err := r.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取Table信息失败: %w", err)
+	}
+	if table == nil {
+		return nil, errors.ErrTableNotFound.WithDetails(tableID)
+	}
+	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取字段列表失败: %w", err)
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.RecordChange{}).
+		Select("DISTINCT ON (record_id) record_id, change_type, old_data, changed_at").
+		Where("table_id = ? AND changed_at > ?", tableID, asOf)
+	if len(recordIDs) > 0 {
+		query = query.Where("record_id IN ?", recordIDs)
+	}
+	var changes []models.RecordChange
+	if err := query.Order("record_id, changed_at ASC").
+		Limit(maxChanges + 1).
+		Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("查询记录变更日志失败: %w", err)
+	}
+	if len(changes) > maxChanges {
+		return nil, record.ErrSnapshotTooOld
+	}
+
+	states := make([]*recordRepo.RecordStateAsOf, 0, len(changes))
+	for _, change := range changes {
+		state := &recordRepo.RecordStateAsOf{RecordID: change.RecordID}
+		if change.ChangeType != recordChangeCreate && change.OldData != nil {
+			rec, err := r.toDomainEntity(ctx, restoreSnapshotRow(change.OldData), fields, table.BaseID(), tableID)
+			if err != nil {
+				return nil, fmt.Errorf("转换快照记录失败: %w", err)
+			}
+			state.Record = rec
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// PurgeChanges 删除过期的变更日志
+func (r *RecordRepositoryDynamic) PurgeChanges(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("changed_at < ?", before).Delete(&models.RecordChange{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge record changes: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// mergeSnapshotRows 按自增序号合并未变更的当前行与变更前状态，只保留游标之后的前 n 条
+// current 已按自增序号升序
+func mergeSnapshotRows(current, before []map[string]interface{}, after int64, n int) []map[string]interface{} {
+	restored := make([]map[string]interface{}, 0, len(before))
+	for _, row := range before {
+		if snapshotAutoNumber(row) > after {
+			restored = append(restored, row)
+		}
+	}
+	sort.Slice(restored, func(i, j int) bool {
+		return snapshotAutoNumber(restored[i]) < snapshotAutoNumber(restored[j])
+	})
+
+	merged := make([]map[string]interface{}, 0, n)
+	i, j := 0, 0
+	for len(merged) < n && (i < len(current) || j < len(restored)) {
+		if j >= len(restored) || (i < len(current) && snapshotAutoNumber(current[i]) < snapshotAutoNumber(restored[j])) {
+			merged = append(merged, current[i])
+			i++
+		} else {
+			merged = append(merged, restored[j])
+			j++
+		}
+	}
+	return merged
+}
+
+// snapshotRow 将物理行转换为可 JSON 序列化的形式（[]byte 按文本保存，时间统一为 RFC3339）
+func snapshotRow(row map[string]interface{}) map[string]interface{} {
+	if row == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		switch val := v.(type) {
+		case []byte:
+			out[k] = string(val)
+		case time.Time:
+			out[k] = val.UTC().Format(time.RFC3339Nano)
+		default:
+			out[k] = val
+		}
+	}
+	return out
+}
+
+// restoreSnapshotRow 还原变更日志中的物理行（系统列恢复为查询结果中的类型）
+func restoreSnapshotRow(row map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		out[k] = v
+	}
+	for _, col := range []string{"__created_time", "__last_modified_time"} {
+		if str, ok := out[col].(string); ok {
+			if t, err := time.Parse(time.RFC3339Nano, str); err == nil {
+				out[col] = t
+			}
+		}
+	}
+	for _, col := range []string{"__version", "__auto_number"} {
+		if f, ok := out[col].(float64); ok {
+			out[col] = int64(f)
+		}
+	}
+	return out
+}
+
+// snapshotAutoNumber 读取行的自增序号
+func snapshotAutoNumber(row map[string]interface{}) int64 {
+	switch v := row["__auto_number"].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
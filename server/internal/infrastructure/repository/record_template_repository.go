@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordtemplate"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// RecordTemplateRepositoryImpl 记录模板GORM实现
+type RecordTemplateRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecordTemplateRepository 创建记录模板仓储
+func NewRecordTemplateRepository(db *gorm.DB) recordtemplate.Repository {
+	return &RecordTemplateRepositoryImpl{db: db}
+}
+
+// ListByTable 列出表的记录模板
+func (r *RecordTemplateRepositoryImpl) ListByTable(ctx context.Context, tableID string) ([]*recordtemplate.Template, error) {
+	var list []models.RecordTemplate
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("is_default DESC, created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list record templates: %w", err)
+	}
+	templates := make([]*recordtemplate.Template, 0, len(list))
+	for i := range list {
+		templates = append(templates, fromRecordTemplateModel(&list[i]))
+	}
+	return templates, nil
+}
+
+// Get 获取记录模板
+func (r *RecordTemplateRepositoryImpl) Get(ctx context.Context, id string) (*recordtemplate.Template, error) {
+	var model models.RecordTemplate
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record template: %w", err)
+	}
+	return fromRecordTemplateModel(&model), nil
+}
+
+// Save 保存记录模板（默认模板在同一事务中取消同表其他模板的默认标记）
+func (r *RecordTemplateRepositoryImpl) Save(ctx context.Context, template *recordtemplate.Template) error {
+	values, err := json.Marshal(template.Values)
+	if err != nil {
+		return err
+	}
+	model := models.RecordTemplate{
+		ID:          template.ID,
+		TableID:     template.TableID,
+		Name:        template.Name,
+		Description: template.Description,
+		CellValues:  string(values),
+		IsDefault:   template.IsDefault,
+		CreatedBy:   template.CreatedBy,
+		UpdatedBy:   template.UpdatedBy,
+		CreatedTime: template.CreatedAt,
+		UpdatedTime: template.UpdatedAt,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if template.IsDefault {
+			if err := tx.Model(&models.RecordTemplate{}).
+				Where("table_id = ? AND id <> ? AND is_default = ?", template.TableID, template.ID, true).
+				Update("is_default", false).Error; err != nil {
+				return fmt.Errorf("failed to reset default record template: %w", err)
+			}
+		}
+		if err := tx.Save(&model).Error; err != nil {
+			return fmt.Errorf("failed to save record template: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete 删除记录模板
+func (r *RecordTemplateRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RecordTemplate{}).Error; err != nil {
+		return fmt.Errorf("failed to delete record template: %w", err)
+	}
+	return nil
+}
+
+func fromRecordTemplateModel(model *models.RecordTemplate) *recordtemplate.Template {
+	template := &recordtemplate.Template{
+		ID:          model.ID,
+		TableID:     model.TableID,
+		Name:        model.Name,
+		Description: model.Description,
+		IsDefault:   model.IsDefault,
+		CreatedBy:   model.CreatedBy,
+		UpdatedBy:   model.UpdatedBy,
+		CreatedAt:   model.CreatedTime,
+		UpdatedAt:   model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.CellValues), &template.Values)
+	if template.Values == nil {
+		template.Values = map[string]interface{}{}
+	}
+	return template
+}
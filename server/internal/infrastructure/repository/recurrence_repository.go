@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recurrence"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// RecurrenceRepositoryImpl 重复记录规则GORM实现
+type RecurrenceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecurrenceRepository 创建重复记录规则仓储
+func NewRecurrenceRepository(db *gorm.DB) recurrence.Repository {
+	return &RecurrenceRepositoryImpl{db: db}
+}
+
+// ListRules 列出表的重复规则
+func (r *RecurrenceRepositoryImpl) ListRules(ctx context.Context, tableID string) ([]*recurrence.Rule, error) {
+	var list []models.RecurrenceRule
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list recurrence rules: %w", err)
+	}
+	rules := make([]*recurrence.Rule, 0, len(list))
+	for i := range list {
+		rules = append(rules, fromRecurrenceRuleModel(&list[i]))
+	}
+	return rules, nil
+}
+
+// GetRule 获取重复规则
+func (r *RecurrenceRepositoryImpl) GetRule(ctx context.Context, id string) (*recurrence.Rule, error) {
+	var model models.RecurrenceRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurrence rule: %w", err)
+	}
+	return fromRecurrenceRuleModel(&model), nil
+}
+
+// SaveRule 创建或更新规则（最近执行时间与租约只由 SaveProgress 写入）
+func (r *RecurrenceRepositoryImpl) SaveRule(ctx context.Context, rule *recurrence.Rule) error {
+	model, err := toRecurrenceRuleModel(rule)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"template_id", "name", "schedule", "calendar_id", "holiday_policy", "skip_dates", "catch_up",
+			"enabled", "cursor_time", "next_run_time", "last_error", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save recurrence rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule 删除规则及其执行记录
+func (r *RecurrenceRepositoryImpl) DeleteRule(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&models.RecurrenceRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.RecurrenceRule{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete recurrence rule: %w", err)
+	}
+	return nil
+}
+
+// CountRulesByCalendar 使用节假日日历的规则数
+func (r *RecurrenceRepositoryImpl) CountRulesByCalendar(ctx context.Context, calendarID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RecurrenceRule{}).
+		Where("calendar_id = ?", calendarID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count recurrence rules: %w", err)
+	}
+	return count, nil
+}
+
+// ClaimDue 领取到期规则（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *RecurrenceRepositoryImpl) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*recurrence.Rule, error) {
+	var claimed []*recurrence.Rule
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.RecurrenceRule{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.RecurrenceRule
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.RecurrenceRule{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromRecurrenceRuleModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim recurrence rules: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存执行进度并释放租约
+func (r *RecurrenceRepositoryImpl) SaveProgress(ctx context.Context, rule *recurrence.Rule) error {
+	if err := r.db.WithContext(ctx).Model(&models.RecurrenceRule{}).
+		Where("id = ?", rule.ID).
+		Updates(map[string]interface{}{
+			"cursor_time":   rule.Cursor,
+			"next_run_time": rule.NextRunAt,
+			"last_run_time": rule.LastRunAt,
+			"last_error":    rule.LastError,
+			"locked_until":  nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save recurrence progress: %w", err)
+	}
+	return nil
+}
+
+// HasRun 规则在该计划时间是否已有执行记录
+func (r *RecurrenceRepositoryImpl) HasRun(ctx context.Context, ruleID string, scheduledAt time.Time) (bool, error) {
+	var ids []string
+	if err := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).Model(&models.RecurrenceRun{}).
+		Where("rule_id = ? AND scheduled_time = ?", ruleID, scheduledAt).
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return false, fmt.Errorf("failed to check recurrence run: %w", err)
+	}
+	return len(ids) > 0, nil
+}
+
+// SaveRun 写入执行记录（使用上下文中的事务连接：记录创建回滚时执行记录一并丢弃）
+func (r *RecurrenceRepositoryImpl) SaveRun(ctx context.Context, run *recurrence.Run) error {
+	model := models.RecurrenceRun{
+		ID:            run.ID,
+		RuleID:        run.RuleID,
+		ScheduledTime: run.ScheduledAt,
+		RunTime:       run.RunAt,
+		Status:        string(run.Status),
+		Reason:        run.Reason,
+		RecordID:      run.RecordID,
+		Error:         run.Error,
+		CreatedTime:   run.CreatedAt,
+	}
+	if err := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save recurrence run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns 按计划时间倒序列出执行记录
+func (r *RecurrenceRepositoryImpl) ListRuns(ctx context.Context, ruleID string, limit int) ([]*recurrence.Run, error) {
+	var list []models.RecurrenceRun
+	if err := r.db.WithContext(ctx).
+		Where("rule_id = ?", ruleID).
+		Order("scheduled_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list recurrence runs: %w", err)
+	}
+	runs := make([]*recurrence.Run, 0, len(list))
+	for i := range list {
+		model := &list[i]
+		runs = append(runs, &recurrence.Run{
+			ID:          model.ID,
+			RuleID:      model.RuleID,
+			ScheduledAt: model.ScheduledTime,
+			RunAt:       model.RunTime,
+			Status:      recurrence.RunStatus(model.Status),
+			Reason:      model.Reason,
+			RecordID:    model.RecordID,
+			Error:       model.Error,
+			CreatedAt:   model.CreatedTime,
+		})
+	}
+	return runs, nil
+}
+
+// PurgeRuns 只保留最近 keep 条执行记录
+func (r *RecurrenceRepositoryImpl) PurgeRuns(ctx context.Context, ruleID string, keep int) error {
+	keepIDs := r.db.Model(&models.RecurrenceRun{}).
+		Select("id").
+		Where("rule_id = ?", ruleID).
+		Order("scheduled_time DESC").
+		Limit(keep)
+	if err := r.db.WithContext(ctx).
+		Where("rule_id = ? AND id NOT IN (?)", ruleID, keepIDs).
+		Delete(&models.RecurrenceRun{}).Error; err != nil {
+		return fmt.Errorf("failed to purge recurrence runs: %w", err)
+	}
+	return nil
+}
+
+// ListCalendars 列出 Base 的节假日日历
+func (r *RecurrenceRepositoryImpl) ListCalendars(ctx context.Context, baseID string) ([]*recurrence.Calendar, error) {
+	var list []models.HolidayCalendar
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list holiday calendars: %w", err)
+	}
+	calendars := make([]*recurrence.Calendar, 0, len(list))
+	for i := range list {
+		calendars = append(calendars, fromHolidayCalendarModel(&list[i]))
+	}
+	return calendars, nil
+}
+
+// GetCalendar 获取节假日日历
+func (r *RecurrenceRepositoryImpl) GetCalendar(ctx context.Context, id string) (*recurrence.Calendar, error) {
+	var model models.HolidayCalendar
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holiday calendar: %w", err)
+	}
+	return fromHolidayCalendarModel(&model), nil
+}
+
+// SaveCalendar 创建或更新节假日日历
+func (r *RecurrenceRepositoryImpl) SaveCalendar(ctx context.Context, calendar *recurrence.Calendar) error {
+	dates, err := json.Marshal(calendar.Dates)
+	if err != nil {
+		return err
+	}
+	model := models.HolidayCalendar{
+		ID:          calendar.ID,
+		BaseID:      calendar.BaseID,
+		Name:        calendar.Name,
+		Dates:       string(dates),
+		CreatedBy:   calendar.CreatedBy,
+		CreatedTime: calendar.CreatedAt,
+		UpdatedTime: calendar.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save holiday calendar: %w", err)
+	}
+	return nil
+}
+
+// DeleteCalendar 删除节假日日历
+func (r *RecurrenceRepositoryImpl) DeleteCalendar(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.HolidayCalendar{}).Error; err != nil {
+		return fmt.Errorf("failed to delete holiday calendar: %w", err)
+	}
+	return nil
+}
+
+func toRecurrenceRuleModel(rule *recurrence.Rule) (models.RecurrenceRule, error) {
+	schedule, err := json.Marshal(rule.Schedule)
+	if err != nil {
+		return models.RecurrenceRule{}, fmt.Errorf("failed to marshal recurrence schedule: %w", err)
+	}
+	skipDates, err := json.Marshal(rule.SkipDates)
+	if err != nil {
+		return models.RecurrenceRule{}, fmt.Errorf("failed to marshal skip dates: %w", err)
+	}
+	return models.RecurrenceRule{
+		ID:            rule.ID,
+		TableID:       rule.TableID,
+		TemplateID:    rule.TemplateID,
+		Name:          rule.Name,
+		Schedule:      string(schedule),
+		CalendarID:    rule.CalendarID,
+		HolidayPolicy: string(rule.HolidayPolicy),
+		SkipDates:     string(skipDates),
+		CatchUp:       string(rule.CatchUp),
+		Enabled:       rule.Enabled,
+		Cursor:        rule.Cursor,
+		NextRunTime:   rule.NextRunAt,
+		LastRunTime:   rule.LastRunAt,
+		LastError:     rule.LastError,
+		CreatedBy:     rule.CreatedBy,
+		CreatedTime:   rule.CreatedAt,
+		UpdatedTime:   rule.UpdatedAt,
+	}, nil
+}
+
+func fromRecurrenceRuleModel(model *models.RecurrenceRule) *recurrence.Rule {
+	rule := &recurrence.Rule{
+		ID:            model.ID,
+		TableID:       model.TableID,
+		TemplateID:    model.TemplateID,
+		Name:          model.Name,
+		CalendarID:    model.CalendarID,
+		HolidayPolicy: recurrence.HolidayPolicy(model.HolidayPolicy),
+		CatchUp:       recurrence.CatchUp(model.CatchUp),
+		Enabled:       model.Enabled,
+		Cursor:        model.Cursor,
+		NextRunAt:     model.NextRunTime,
+		LastRunAt:     model.LastRunTime,
+		LastError:     model.LastError,
+		CreatedBy:     model.CreatedBy,
+		CreatedAt:     model.CreatedTime,
+		UpdatedAt:     model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Schedule), &rule.Schedule)
+	_ = json.Unmarshal([]byte(model.SkipDates), &rule.SkipDates)
+	if rule.SkipDates == nil {
+		rule.SkipDates = []string{}
+	}
+	return rule
+}
+
+func fromHolidayCalendarModel(model *models.HolidayCalendar) *recurrence.Calendar {
+	calendar := &recurrence.Calendar{
+		ID:        model.ID,
+		BaseID:    model.BaseID,
+		Name:      model.Name,
+		CreatedBy: model.CreatedBy,
+		CreatedAt: model.CreatedTime,
+		UpdatedAt: model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Dates), &calendar.Dates)
+	if calendar.Dates == nil {
+		calendar.Dates = []string{}
+	}
+	return calendar
+}
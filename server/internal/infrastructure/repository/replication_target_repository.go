@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/replication"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// ReplicationTargetRepositoryImpl 复制目标GORM实现
+type ReplicationTargetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReplicationTargetRepository 创建复制目标仓储
+func NewReplicationTargetRepository(db *gorm.DB) replication.Repository {
+	return &ReplicationTargetRepositoryImpl{db: db}
+}
+
+// Save 创建或更新目标配置
+// 投递进度（cursor、last_error 等）只由 SaveProgress 写入，避免修改配置时回退并发投递的进度
+func (r *ReplicationTargetRepositoryImpl) Save(ctx context.Context, target *replication.Target) error {
+	model, err := toReplicationTargetModel(target)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "url", "table_ids", "enabled", "updated_time"}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save replication target: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取目标
+func (r *ReplicationTargetRepositoryImpl) FindByID(ctx context.Context, id string) (*replication.Target, error) {
+	var model models.ReplicationTarget
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replication target: %w", err)
+	}
+	return fromReplicationTargetModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的目标
+func (r *ReplicationTargetRepositoryImpl) ListByBase(ctx context.Context, baseID string) ([]*replication.Target, error) {
+	var list []models.ReplicationTarget
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list replication targets: %w", err)
+	}
+	targets := make([]*replication.Target, 0, len(list))
+	for i := range list {
+		targets = append(targets, fromReplicationTargetModel(&list[i]))
+	}
+	return targets, nil
+}
+
+// Delete 删除目标
+func (r *ReplicationTargetRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.ReplicationTarget{}).Error; err != nil {
+		return fmt.Errorf("failed to delete replication target: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取待投递的 Webhook 目标（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *ReplicationTargetRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*replication.Target, error) {
+	var claimed []*replication.Target
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.ReplicationTarget{}).
+			Where("mode = ? AND enabled = ?", string(replication.ModeWebhook), true).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("last_delivered_time ASC NULLS FIRST").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.ReplicationTarget
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.ReplicationTarget{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromReplicationTargetModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim replication targets: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存投递进度并释放租约
+func (r *ReplicationTargetRepositoryImpl) SaveProgress(ctx context.Context, target *replication.Target) error {
+	if err := r.db.WithContext(ctx).Model(&models.ReplicationTarget{}).
+		Where("id = ?", target.ID).
+		Updates(map[string]interface{}{
+			"cursor":              target.Cursor,
+			"last_error":          target.LastError,
+			"last_delivered_time": target.LastDeliveredAt,
+			"locked_until":        nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save replication progress: %w", err)
+	}
+	return nil
+}
+
+// SyncPublication 创建或更新 PostgreSQL 发布
+// 发布名与表名均来自服务端生成的ID，仍按标识符转义后拼接
+func (r *ReplicationTargetRepositoryImpl) SyncPublication(ctx context.Context, name string, tables []string) error {
+	if r.db.Dialector.Name() != "postgres" {
+		return fmt.Errorf("logical replication publications require PostgreSQL")
+	}
+	quoted := make([]string, 0, len(tables))
+	for _, table := range tables {
+		quoted = append(quoted, quoteQualifiedIdentifier(table))
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var exists bool
+		if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM pg_publication WHERE pubname = ?)", name).
+			Scan(&exists).Error; err != nil {
+			return fmt.Errorf("failed to check publication: %w", err)
+		}
+
+		// 空发布不能使用 SET TABLE，先删除后按需重建
+		if exists && len(quoted) == 0 {
+			if err := tx.Exec("DROP PUBLICATION " + quoteIdentifier(name)).Error; err != nil {
+				return fmt.Errorf("failed to drop publication: %w", err)
+			}
+			exists = false
+		}
+
+		var stmt string
+		switch {
+		case !exists && len(quoted) == 0:
+			stmt = "CREATE PUBLICATION " + quoteIdentifier(name)
+		case !exists:
+			stmt = "CREATE PUBLICATION " + quoteIdentifier(name) + " FOR TABLE " + strings.Join(quoted, ", ")
+		default:
+			stmt = "ALTER PUBLICATION " + quoteIdentifier(name) + " SET TABLE " + strings.Join(quoted, ", ")
+		}
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to sync publication: %w", err)
+		}
+		return nil
+	})
+}
+
+// DropPublication 删除 PostgreSQL 发布
+func (r *ReplicationTargetRepositoryImpl) DropPublication(ctx context.Context, name string) error {
+	if r.db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Exec("DROP PUBLICATION IF EXISTS " + quoteIdentifier(name)).Error; err != nil {
+		return fmt.Errorf("failed to drop publication: %w", err)
+	}
+	return nil
+}
+
+// quoteIdentifier 转义 PostgreSQL 标识符
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// quoteQualifiedIdentifier 转义 schema 限定的表名（bse_xxx.tbl_yyy）
+func quoteQualifiedIdentifier(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func toReplicationTargetModel(target *replication.Target) (models.ReplicationTarget, error) {
+	tableIDs, err := json.Marshal(target.TableIDs)
+	if err != nil {
+		return models.ReplicationTarget{}, fmt.Errorf("failed to marshal replication tables: %w", err)
+	}
+	return models.ReplicationTarget{
+		ID:              target.ID,
+		BaseID:          target.BaseID,
+		Name:            target.Name,
+		Mode:            string(target.Mode),
+		URL:             target.URL,
+		Secret:          target.Secret,
+		TableIDs:        string(tableIDs),
+		Enabled:         target.Enabled,
+		Cursor:          target.Cursor,
+		LastError:       target.LastError,
+		LastDeliveredAt: target.LastDeliveredAt,
+		CreatedBy:       target.CreatedBy,
+		CreatedTime:     target.CreatedAt,
+		UpdatedTime:     target.UpdatedAt,
+	}, nil
+}
+
+func fromReplicationTargetModel(model *models.ReplicationTarget) *replication.Target {
+	target := &replication.Target{
+		ID:              model.ID,
+		BaseID:          model.BaseID,
+		Name:            model.Name,
+		Mode:            replication.Mode(model.Mode),
+		URL:             model.URL,
+		Secret:          model.Secret,
+		Enabled:         model.Enabled,
+		Cursor:          model.Cursor,
+		LastError:       model.LastError,
+		LastDeliveredAt: model.LastDeliveredAt,
+		CreatedBy:       model.CreatedBy,
+		CreatedAt:       model.CreatedTime,
+		UpdatedAt:       model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.TableIDs), &target.TableIDs)
+	if target.TableIDs == nil {
+		target.TableIDs = []string{}
+	}
+	return target
+}
@@ -0,0 +1,113 @@
+// Package repotest 字段与记录仓储的契约测试 ✨
+// 同一套用例可以运行在 GORM / 动态表实现、内存实现以及缓存包装器之上，
+// 保证各实现对上层服务表现一致（例如写入后立即可读、乐观锁版本冲突）
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
+)
+
+// ErrCacheMiss 缓存未命中（与 Redis 缓存一致）
+var ErrCacheMiss = cache.ErrCacheNotFound
+
+// Cache 进程内缓存，实现 repository.CacheProvider，用于测试缓存包装器
+// 写入与读取时复制实体（模拟 Redis 的序列化），读取方修改返回值不会影响缓存内容；不处理 TTL
+type Cache struct {
+	mu    sync.Mutex
+	items map[string]reflect.Value
+}
+
+// NewCache 创建进程内缓存
+func NewCache() *Cache {
+	return &Cache{items: make(map[string]reflect.Value)}
+}
+
+// Get 读取缓存到 dest（dest 必须是指针，且缓存值可赋值给 *dest）
+func (c *Cache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.items[key]
+	if !ok {
+		return ErrCacheMiss
+	}
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("cache dest must be a non-nil pointer, got %T", dest)
+	}
+	if !value.Type().AssignableTo(target.Elem().Type()) {
+		return fmt.Errorf("cache value %s is not assignable to %s", value.Type(), target.Elem().Type())
+	}
+	target.Elem().Set(cloneValue(value))
+	return nil
+}
+
+// Set 写入缓存（写入 nil 等同于删除）
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value == nil {
+		delete(c.items, key)
+		return nil
+	}
+	c.items[key] = cloneValue(reflect.ValueOf(value))
+	return nil
+}
+
+// Delete 删除缓存
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
+
+// InvalidatePattern 按通配符模式删除缓存（* 匹配任意字符）
+func (c *Cache) InvalidatePattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.items {
+		if matched, _ := path.Match(pattern, key); matched {
+			delete(c.items, key)
+		}
+	}
+	return nil
+}
+
+// Len 缓存条目数
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// cloneValue 复制结构体指针与切片（逐个复制元素），其他值原样返回
+func cloneValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() || value.Elem().Kind() != reflect.Struct {
+			return value
+		}
+		c := reflect.New(value.Elem().Type())
+		c.Elem().Set(value.Elem())
+		return c
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			c.Index(i).Set(cloneValue(value.Index(i)))
+		}
+		return c
+	}
+	return value
+}
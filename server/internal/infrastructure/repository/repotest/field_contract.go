@@ -0,0 +1,253 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// FieldRepositoryContract 字段仓储契约测试
+// newRepo 为每个子测试返回仓储；用例各自生成新的表ID，仓储中已有的数据不影响结果
+func FieldRepositoryContract(t *testing.T, newRepo func(t *testing.T) repository.FieldRepository) {
+	t.Run("SaveAndFind", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		field := newContractField(t, tableID, "标题", 0)
+		mustSaveField(t, repo, field)
+
+		found, err := repo.FindByID(ctx, field.ID())
+		if err != nil {
+			t.Fatalf("FindByID 失败: %v", err)
+		}
+		if found == nil || found.Name().String() != "标题" || found.TableID() != tableID || !found.Type().Equals(field.Type()) {
+			t.Fatalf("FindByID 应返回保存的字段，得到 %s", describeField(found))
+		}
+		if missing, err := repo.FindByID(ctx, valueobject.NewFieldID("")); err != nil || missing != nil {
+			t.Errorf("字段不存在时应返回 nil, nil，得到 %v, %v", missing, err)
+		}
+		if exists, err := repo.Exists(ctx, field.ID()); err != nil || !exists {
+			t.Errorf("Exists 应返回 true，得到 %v, %v", exists, err)
+		}
+
+		name := fieldName(t, "标题")
+		byName, err := repo.FindByName(ctx, tableID, name)
+		if err != nil || byName == nil || byName.ID().String() != field.ID().String() {
+			t.Errorf("FindByName 应返回保存的字段，得到 %s, %v", describeField(byName), err)
+		}
+		if exists, _ := repo.ExistsByName(ctx, tableID, name, nil); !exists {
+			t.Error("ExistsByName 应找到同名字段")
+		}
+		id := field.ID()
+		if exists, _ := repo.ExistsByName(ctx, tableID, name, &id); exists {
+			t.Error("ExistsByName 应排除指定的字段")
+		}
+		if exists, _ := repo.ExistsByName(ctx, utils.GenerateTableID(), name, nil); exists {
+			t.Error("ExistsByName 不应匹配其他表的字段")
+		}
+	})
+
+	t.Run("FindByTableIDOrdersByOrder", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		if max, err := repo.GetMaxOrder(ctx, tableID); err != nil || max != -1 {
+			t.Errorf("没有字段时 GetMaxOrder 应返回 -1，得到 %v, %v", max, err)
+		}
+		c := newContractField(t, tableID, "C", 2)
+		a := newContractField(t, tableID, "A", 0)
+		b := newContractField(t, tableID, "B", 1)
+		other := newContractField(t, utils.GenerateTableID(), "A", 0)
+		for _, field := range []*entity.Field{c, a, b, other} {
+			mustSaveField(t, repo, field)
+		}
+
+		assertFieldNames(t, repo, tableID, "A", "B", "C")
+		if max, err := repo.GetMaxOrder(ctx, tableID); err != nil || max != 2 {
+			t.Errorf("GetMaxOrder 应返回 2，得到 %v, %v", max, err)
+		}
+	})
+
+	t.Run("SaveIsVisibleAfterRead", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		field := newContractField(t, tableID, "旧名称", 0)
+		mustSaveField(t, repo, field)
+		warmFields(t, repo, tableID, field.ID())
+
+		if err := field.Rename(fieldName(t, "新名称")); err != nil {
+			t.Fatalf("重命名失败: %v", err)
+		}
+		mustSaveField(t, repo, field)
+
+		found, _ := repo.FindByID(ctx, field.ID())
+		if found == nil || found.Name().String() != "新名称" {
+			t.Errorf("保存后 FindByID 应读到新名称，得到 %s", describeField(found))
+		}
+		assertFieldNames(t, repo, tableID, "新名称")
+	})
+
+	t.Run("DeleteIsVisibleAfterRead", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		kept := newContractField(t, tableID, "保留", 0)
+		deleted := newContractField(t, tableID, "删除", 1)
+		mustSaveField(t, repo, kept)
+		mustSaveField(t, repo, deleted)
+		warmFields(t, repo, tableID, deleted.ID())
+
+		if err := repo.Delete(ctx, deleted.ID()); err != nil {
+			t.Fatalf("Delete 失败: %v", err)
+		}
+		if found, _ := repo.FindByID(ctx, deleted.ID()); found != nil {
+			t.Errorf("删除后 FindByID 应返回 nil，得到 %s", describeField(found))
+		}
+		if exists, _ := repo.Exists(ctx, deleted.ID()); exists {
+			t.Error("删除后 Exists 应返回 false")
+		}
+		assertFieldNames(t, repo, tableID, "保留")
+	})
+
+	t.Run("BatchSaveAndBatchDelete", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		a := newContractField(t, tableID, "A", 0)
+		b := newContractField(t, tableID, "B", 1)
+		if err := repo.BatchSave(ctx, []*entity.Field{a, b}); err != nil {
+			t.Fatalf("BatchSave 失败: %v", err)
+		}
+		warmFields(t, repo, tableID, a.ID())
+
+		if err := a.Rename(fieldName(t, "A2")); err != nil {
+			t.Fatalf("重命名失败: %v", err)
+		}
+		if err := repo.BatchSave(ctx, []*entity.Field{a}); err != nil {
+			t.Fatalf("BatchSave 失败: %v", err)
+		}
+		if found, _ := repo.FindByID(ctx, a.ID()); found == nil || found.Name().String() != "A2" {
+			t.Errorf("批量保存后 FindByID 应读到新名称，得到 %s", describeField(found))
+		}
+		assertFieldNames(t, repo, tableID, "A2", "B")
+
+		if err := repo.BatchDelete(ctx, []valueobject.FieldID{a.ID(), b.ID()}); err != nil {
+			t.Fatalf("BatchDelete 失败: %v", err)
+		}
+		if found, _ := repo.FindByID(ctx, a.ID()); found != nil {
+			t.Errorf("批量删除后 FindByID 应返回 nil，得到 %s", describeField(found))
+		}
+		assertFieldNames(t, repo, tableID)
+	})
+
+	t.Run("UpdateOrder", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		a := newContractField(t, tableID, "A", 0)
+		b := newContractField(t, tableID, "B", 1)
+		mustSaveField(t, repo, a)
+		mustSaveField(t, repo, b)
+		warmFields(t, repo, tableID, b.ID())
+
+		if err := repo.UpdateOrder(ctx, b.ID(), -1); err != nil {
+			t.Fatalf("UpdateOrder 失败: %v", err)
+		}
+		if found, _ := repo.FindByID(ctx, b.ID()); found == nil || found.Order() != -1 {
+			t.Errorf("UpdateOrder 后 FindByID 应读到新顺序，得到 %s", describeField(found))
+		}
+		assertFieldNames(t, repo, tableID, "B", "A")
+	})
+
+	t.Run("BatchUpdateOrder", func(t *testing.T) {
+		repo, ctx, tableID := newRepo(t), context.Background(), utils.GenerateTableID()
+		a := newContractField(t, tableID, "A", 0)
+		b := newContractField(t, tableID, "B", 1)
+		c := newContractField(t, tableID, "C", 2)
+		for _, field := range []*entity.Field{a, b, c} {
+			mustSaveField(t, repo, field)
+		}
+		warmFields(t, repo, tableID, a.ID())
+
+		if err := repo.BatchUpdateOrder(ctx, tableID, []valueobject.FieldID{c.ID(), a.ID(), b.ID()}); err != nil {
+			t.Fatalf("BatchUpdateOrder 失败: %v", err)
+		}
+		assertFieldNames(t, repo, tableID, "C", "A", "B")
+		if found, _ := repo.FindByID(ctx, a.ID()); found == nil || found.Order() != 1 {
+			t.Errorf("重排后 FindByID 应读到新顺序，得到 %s", describeField(found))
+		}
+
+		foreign := newContractField(t, utils.GenerateTableID(), "D", 0)
+		mustSaveField(t, repo, foreign)
+		if err := repo.BatchUpdateOrder(ctx, tableID, []valueobject.FieldID{a.ID(), b.ID(), foreign.ID()}); err == nil {
+			t.Error("包含其他表字段时 BatchUpdateOrder 应报错")
+		}
+		assertFieldNames(t, repo, tableID, "C", "A", "B")
+	})
+}
+
+// newContractField 创建文本字段并设置顺序
+func newContractField(t *testing.T, tableID, name string, order float64) *entity.Field {
+	t.Helper()
+	fieldType, err := valueobject.NewFieldType(valueobject.TypeText)
+	if err != nil {
+		t.Fatalf("创建字段类型失败: %v", err)
+	}
+	field, err := entity.NewField(tableID, fieldName(t, name), fieldType, "usr_contract")
+	if err != nil {
+		t.Fatalf("创建字段失败: %v", err)
+	}
+	field.SetOrder(order)
+	return field
+}
+
+func fieldName(t *testing.T, name string) valueobject.FieldName {
+	t.Helper()
+	fn, err := valueobject.NewFieldName(name)
+	if err != nil {
+		t.Fatalf("创建字段名失败: %v", err)
+	}
+	return fn
+}
+
+func mustSaveField(t *testing.T, repo repository.FieldRepository, field *entity.Field) {
+	t.Helper()
+	if err := repo.Save(context.Background(), field); err != nil {
+		t.Fatalf("保存字段失败: %v", err)
+	}
+}
+
+// warmFields 先读一次字段和表字段列表（缓存实现会写入缓存），用于验证后续写入能让缓存失效
+func warmFields(t *testing.T, repo repository.FieldRepository, tableID string, id valueobject.FieldID) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := repo.FindByID(ctx, id); err != nil {
+		t.Fatalf("FindByID 失败: %v", err)
+	}
+	if _, err := repo.FindByTableID(ctx, tableID); err != nil {
+		t.Fatalf("FindByTableID 失败: %v", err)
+	}
+}
+
+// assertFieldNames 断言表字段按顺序排列的名称
+func assertFieldNames(t *testing.T, repo repository.FieldRepository, tableID string, names ...string) {
+	t.Helper()
+	fields, err := repo.FindByTableID(context.Background(), tableID)
+	if err != nil {
+		t.Fatalf("FindByTableID 失败: %v", err)
+	}
+	got := make([]string, 0, len(fields))
+	for _, field := range fields {
+		got = append(got, field.Name().String())
+	}
+	if len(got) != len(names) {
+		t.Fatalf("FindByTableID 应返回 %v，得到 %v", names, got)
+	}
+	for i := range names {
+		if got[i] != names[i] {
+			t.Fatalf("FindByTableID 应返回 %v，得到 %v", names, got)
+		}
+	}
+}
+
+// describeField 失败信息中的字段描述
+func describeField(field *entity.Field) string {
+	if field == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%s(order=%v)", field.Name().String(), field.Order())
+}
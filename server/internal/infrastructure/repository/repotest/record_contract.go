@@ -0,0 +1,247 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// RecordFixture 记录契约测试的准备结果：仓储、一张空表及表中的一个文本字段
+type RecordFixture struct {
+	Repo    repository.RecordRepository
+	TableID string
+	FieldID string
+}
+
+// RecordRepositoryContract 记录仓储契约测试
+// 只覆盖带 tableID 的方法（FindByID、Delete、Exists 在动态表实现中已废弃）；
+// setup 为每个子测试准备一张新表
+func RecordRepositoryContract(t *testing.T, setup func(t *testing.T) RecordFixture) {
+	t.Run("SaveAndFind", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+
+		found, err := f.Repo.FindByTableAndID(ctx, f.TableID, record.ID())
+		if err != nil {
+			t.Fatalf("FindByTableAndID 失败: %v", err)
+		}
+		assertRecord(t, f, found, "a", 1)
+		if missing, err := f.Repo.FindByTableAndID(ctx, f.TableID, valueobject.NewRecordID("")); err != nil || missing != nil {
+			t.Errorf("记录不存在时应返回 nil, nil，得到 %v, %v", missing, err)
+		}
+
+		records, err := f.Repo.FindByIDs(ctx, f.TableID, []valueobject.RecordID{record.ID(), valueobject.NewRecordID("")})
+		if err != nil || len(records) != 1 || records[0].ID().String() != record.ID().String() {
+			t.Errorf("FindByIDs 应只返回存在的记录，得到 %v, %v", records, err)
+		}
+		if records, err := f.Repo.FindByIDs(ctx, f.TableID, nil); err != nil || len(records) != 0 {
+			t.Errorf("FindByIDs 空列表应返回空结果，得到 %v, %v", records, err)
+		}
+		if count, err := f.Repo.CountByTableID(ctx, f.TableID); err != nil || count != 1 {
+			t.Errorf("CountByTableID 应返回 1，得到 %v, %v", count, err)
+		}
+	})
+
+	t.Run("UpdateBumpsVersion", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+
+		loaded := mustFindRecord(t, f, record.ID())
+		updateRecord(t, f, loaded, "b")
+		mustSaveRecord(t, f.Repo, loaded)
+
+		assertRecord(t, f, mustFindRecord(t, f, record.ID()), "b", 2)
+
+		v1, _ := valueobject.NewRecordVersion(1)
+		if _, err := f.Repo.FindWithVersion(ctx, f.TableID, record.ID(), v1); !isAppError(err, errors.ErrVersionConflict) {
+			t.Errorf("旧版本 FindWithVersion 应返回版本冲突，得到 %v", err)
+		}
+		v2, _ := valueobject.NewRecordVersion(2)
+		found, err := f.Repo.FindWithVersion(ctx, f.TableID, record.ID(), v2)
+		if err != nil {
+			t.Fatalf("FindWithVersion 失败: %v", err)
+		}
+		assertRecord(t, f, found, "b", 2)
+	})
+
+	t.Run("StaleSaveConflicts", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+
+		first := mustFindRecord(t, f, record.ID())
+		second := mustFindRecord(t, f, record.ID())
+		updateRecord(t, f, first, "first")
+		mustSaveRecord(t, f.Repo, first)
+
+		updateRecord(t, f, second, "second")
+		if err := f.Repo.Save(ctx, second); !isAppError(err, errors.ErrVersionConflict) {
+			t.Errorf("基于旧版本保存应返回版本冲突，得到 %v", err)
+		}
+		assertRecord(t, f, mustFindRecord(t, f, record.ID()), "first", 2)
+	})
+
+	t.Run("DeleteIsVisibleAfterRead", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+		mustFindRecord(t, f, record.ID())
+
+		if err := f.Repo.DeleteByTableAndID(ctx, f.TableID, record.ID()); err != nil {
+			t.Fatalf("DeleteByTableAndID 失败: %v", err)
+		}
+		if found, err := f.Repo.FindByTableAndID(ctx, f.TableID, record.ID()); err != nil || found != nil {
+			t.Errorf("删除后 FindByTableAndID 应返回 nil，得到 %v, %v", found, err)
+		}
+		if count, _ := f.Repo.CountByTableID(ctx, f.TableID); count != 0 {
+			t.Errorf("删除后 CountByTableID 应返回 0，得到 %d", count)
+		}
+	})
+
+	t.Run("DeleteWithStaleVersionConflicts", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+
+		loaded := mustFindRecord(t, f, record.ID())
+		updateRecord(t, f, loaded, "b")
+		mustSaveRecord(t, f.Repo, loaded)
+
+		v1, _ := valueobject.NewRecordVersion(1)
+		if err := f.Repo.DeleteByTableAndIDWithVersion(ctx, f.TableID, record.ID(), v1); !isAppError(err, errors.ErrVersionConflict) {
+			t.Errorf("按旧版本删除应返回版本冲突，得到 %v", err)
+		}
+		assertRecord(t, f, mustFindRecord(t, f, record.ID()), "b", 2)
+
+		v2, _ := valueobject.NewRecordVersion(2)
+		if err := f.Repo.DeleteByTableAndIDWithVersion(ctx, f.TableID, record.ID(), v2); err != nil {
+			t.Fatalf("按当前版本删除失败: %v", err)
+		}
+		if found, err := f.Repo.FindByTableAndID(ctx, f.TableID, record.ID()); err != nil || found != nil {
+			t.Errorf("删除后 FindByTableAndID 应返回 nil，得到 %v, %v", found, err)
+		}
+	})
+
+	t.Run("ListPaginates", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		for _, value := range []string{"a", "b", "c"} {
+			mustSaveRecord(t, f.Repo, newContractRecord(t, f, value))
+		}
+
+		tableID := f.TableID
+		records, total, err := f.Repo.List(ctx, repository.RecordFilter{TableID: &tableID, Limit: 2})
+		if err != nil || total != 3 || len(records) != 2 {
+			t.Errorf("List 第一页应返回 2 条、总数 3，得到 %d 条、总数 %d, %v", len(records), total, err)
+		}
+		records, total, err = f.Repo.List(ctx, repository.RecordFilter{TableID: &tableID, Limit: 2, Offset: 2})
+		if err != nil || total != 3 || len(records) != 1 {
+			t.Errorf("List 第二页应返回 1 条、总数 3，得到 %d 条、总数 %d, %v", len(records), total, err)
+		}
+		if _, _, err := f.Repo.List(ctx, repository.RecordFilter{}); err == nil {
+			t.Error("缺少 TableID 时 List 应报错")
+		}
+	})
+
+	t.Run("Projection", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		record := newContractRecord(t, f, "a")
+		mustSaveRecord(t, f.Repo, record)
+		other := utils.GenerateFieldID()
+
+		records, err := f.Repo.FindByIDs(ctx, f.TableID, []valueobject.RecordID{record.ID()}, f.FieldID)
+		if err != nil || len(records) != 1 {
+			t.Fatalf("FindByIDs 投影应返回记录，得到 %v, %v", records, err)
+		}
+		assertRecord(t, f, records[0], "a", 1)
+		records, err = f.Repo.FindByIDs(ctx, f.TableID, []valueobject.RecordID{record.ID()}, other)
+		if err != nil || len(records) != 1 || records[0].Data().HasField(f.FieldID) {
+			t.Errorf("FindByIDs 投影不应返回未请求的字段，得到 %v, %v", records, err)
+		}
+
+		tableID := f.TableID
+		records, _, err = f.Repo.List(ctx, repository.RecordFilter{TableID: &tableID, FieldIDs: []string{other}})
+		if err != nil || len(records) != 1 || records[0].Data().HasField(f.FieldID) || records[0].Version().Value() != 1 {
+			t.Errorf("List 投影只应返回请求的字段（系统属性保留），得到 %v, %v", records, err)
+		}
+		// 投影读取不影响之后的完整读取（缓存实现不能缓存投影结果）
+		assertRecord(t, f, mustFindRecord(t, f, record.ID()), "a", 1)
+	})
+
+	t.Run("UnknownTable", func(t *testing.T) {
+		f, ctx := setup(t), context.Background()
+		missing := utils.GenerateTableID()
+		if _, err := f.Repo.FindByTableAndID(ctx, missing, valueobject.NewRecordID("")); !isAppError(err, errors.ErrTableNotFound) {
+			t.Errorf("表不存在时 FindByTableAndID 应返回 ErrTableNotFound，得到 %v", err)
+		}
+		if _, err := f.Repo.CountByTableID(ctx, missing); !isAppError(err, errors.ErrTableNotFound) {
+			t.Errorf("表不存在时 CountByTableID 应返回 ErrTableNotFound，得到 %v", err)
+		}
+	})
+}
+
+// newContractRecord 创建文本字段值为 value 的新记录
+func newContractRecord(t *testing.T, f RecordFixture, value string) *entity.Record {
+	t.Helper()
+	data, err := valueobject.NewRecordData(map[string]interface{}{f.FieldID: value})
+	if err != nil {
+		t.Fatalf("创建记录数据失败: %v", err)
+	}
+	record, err := entity.NewRecord(f.TableID, data, "usr_contract")
+	if err != nil {
+		t.Fatalf("创建记录失败: %v", err)
+	}
+	return record
+}
+
+func updateRecord(t *testing.T, f RecordFixture, record *entity.Record, value string) {
+	t.Helper()
+	data, err := valueobject.NewRecordData(map[string]interface{}{f.FieldID: value})
+	if err != nil {
+		t.Fatalf("创建记录数据失败: %v", err)
+	}
+	if err := record.Update(data, "usr_contract"); err != nil {
+		t.Fatalf("更新记录失败: %v", err)
+	}
+}
+
+func mustSaveRecord(t *testing.T, repo repository.RecordRepository, record *entity.Record) {
+	t.Helper()
+	if err := repo.Save(context.Background(), record); err != nil {
+		t.Fatalf("保存记录失败: %v", err)
+	}
+}
+
+func mustFindRecord(t *testing.T, f RecordFixture, id valueobject.RecordID) *entity.Record {
+	t.Helper()
+	record, err := f.Repo.FindByTableAndID(context.Background(), f.TableID, id)
+	if err != nil || record == nil {
+		t.Fatalf("FindByTableAndID 应返回记录，得到 %v, %v", record, err)
+	}
+	return record
+}
+
+// assertRecord 断言记录的文本字段值与版本
+func assertRecord(t *testing.T, f RecordFixture, record *entity.Record, value string, version int64) {
+	t.Helper()
+	if record == nil {
+		t.Fatal("记录不应为 nil")
+	}
+	if got, _ := record.Data().Get(f.FieldID); got != value {
+		t.Errorf("字段值应为 %q，得到 %v", value, got)
+	}
+	if record.Version().Value() != version {
+		t.Errorf("版本应为 %d，得到 %d", version, record.Version().Value())
+	}
+}
+
+func isAppError(err error, target *errors.AppError) bool {
+	appErr, ok := errors.IsAppError(err)
+	return ok && appErr.Code == target.Code
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/scanrule"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// ScanRuleRepositoryImpl 扫码规则GORM实现
+type ScanRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewScanRuleRepository 创建扫码规则仓储
+func NewScanRuleRepository(db *gorm.DB) scanrule.Repository {
+	return &ScanRuleRepositoryImpl{db: db}
+}
+
+// Save 保存扫码规则
+func (r *ScanRuleRepositoryImpl) Save(ctx context.Context, rule *scanrule.Rule) error {
+	model := models.ScanRule{
+		ID:               rule.ID,
+		TableID:          rule.TableID,
+		FieldID:          rule.FieldID,
+		OnMissing:        rule.OnMissing,
+		RecordTemplateID: rule.RecordTemplateID,
+		CreatedBy:        rule.CreatedBy,
+		UpdatedBy:        rule.UpdatedBy,
+		CreatedTime:      rule.CreatedAt,
+		UpdatedTime:      rule.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save scan rule: %w", err)
+	}
+	return nil
+}
+
+// FindByField 获取字段的扫码规则
+func (r *ScanRuleRepositoryImpl) FindByField(ctx context.Context, fieldID string) (*scanrule.Rule, error) {
+	var model models.ScanRule
+	err := r.db.WithContext(ctx).Where("field_id = ?", fieldID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan rule: %w", err)
+	}
+	return fromScanRuleModel(&model), nil
+}
+
+// ListByTable 列出表的扫码规则
+func (r *ScanRuleRepositoryImpl) ListByTable(ctx context.Context, tableID string) ([]*scanrule.Rule, error) {
+	var list []models.ScanRule
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list scan rules: %w", err)
+	}
+	rules := make([]*scanrule.Rule, 0, len(list))
+	for i := range list {
+		rules = append(rules, fromScanRuleModel(&list[i]))
+	}
+	return rules, nil
+}
+
+// Delete 删除扫码规则
+func (r *ScanRuleRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.ScanRule{}).Error; err != nil {
+		return fmt.Errorf("failed to delete scan rule: %w", err)
+	}
+	return nil
+}
+
+func fromScanRuleModel(model *models.ScanRule) *scanrule.Rule {
+	return &scanrule.Rule{
+		ID:               model.ID,
+		TableID:          model.TableID,
+		FieldID:          model.FieldID,
+		OnMissing:        model.OnMissing,
+		RecordTemplateID: model.RecordTemplateID,
+		CreatedBy:        model.CreatedBy,
+		UpdatedBy:        model.UpdatedBy,
+		CreatedAt:        model.CreatedTime,
+		UpdatedAt:        model.UpdatedTime,
+	}
+}
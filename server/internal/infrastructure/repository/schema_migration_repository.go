@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemamigration"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// SchemaMigrationRepositoryImpl 结构变更任务GORM实现
+type SchemaMigrationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSchemaMigrationRepository 创建结构变更任务仓储
+func NewSchemaMigrationRepository(db *gorm.DB) schemamigration.Repository {
+	return &SchemaMigrationRepositoryImpl{db: db}
+}
+
+// Save 保存任务
+func (r *SchemaMigrationRepositoryImpl) Save(ctx context.Context, migration *schemamigration.Migration) error {
+	model := toSchemaMigrationModel(migration)
+	if err := database.WithTx(ctx, r.db).WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save schema migration: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取任务
+func (r *SchemaMigrationRepositoryImpl) FindByID(ctx context.Context, id string) (*schemamigration.Migration, error) {
+	var model models.SchemaMigration
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema migration: %w", err)
+	}
+	return fromSchemaMigrationModel(&model), nil
+}
+
+// ListByTable 列出表格最近的结构变更任务
+func (r *SchemaMigrationRepositoryImpl) ListByTable(ctx context.Context, tableID string, limit int) ([]*schemamigration.Migration, error) {
+	var list []models.SchemaMigration
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list schema migrations: %w", err)
+	}
+
+	migrations := make([]*schemamigration.Migration, 0, len(list))
+	for i := range list {
+		migrations = append(migrations, fromSchemaMigrationModel(&list[i]))
+	}
+	return migrations, nil
+}
+
+// Claim 领取可执行任务（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *SchemaMigrationRepositoryImpl) Claim(ctx context.Context, now, staleBefore time.Time) (*schemamigration.Migration, error) {
+	var claimed *schemamigration.Migration
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.SchemaMigration{}).
+			Where("(status = ? AND next_run_at <= ?) OR (status = ? AND updated_time < ?)",
+				string(schemamigration.StatusPending), now,
+				string(schemamigration.StatusRunning), staleBefore).
+			Where(`NOT EXISTS (SELECT 1 FROM schema_migration other
+				WHERE other.table_id = schema_migration.table_id AND other.id <> schema_migration.id
+				AND other.status = ? AND other.updated_time >= ?)`,
+				string(schemamigration.StatusRunning), staleBefore).
+			Order("created_time ASC")
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var model models.SchemaMigration
+		err := query.Take(&model).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		model.Status = string(schemamigration.StatusRunning)
+		model.Attempts++
+		model.UpdatedTime = now
+		if err := tx.Model(&models.SchemaMigration{}).
+			Where("id = ?", model.ID).
+			Updates(map[string]interface{}{
+				"status":       model.Status,
+				"attempts":     model.Attempts,
+				"updated_time": model.UpdatedTime,
+			}).Error; err != nil {
+			return err
+		}
+		claimed = fromSchemaMigrationModel(&model)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim schema migration: %w", err)
+	}
+	return claimed, nil
+}
+
+func toSchemaMigrationModel(m *schemamigration.Migration) models.SchemaMigration {
+	model := models.SchemaMigration{
+		ID:            m.ID,
+		BaseID:        m.BaseID,
+		TableID:       m.TableID,
+		FieldID:       m.FieldID,
+		Operation:     string(m.Operation),
+		ColumnName:    m.Column,
+		ColumnType:    m.ColumnType,
+		FieldType:     m.FieldType,
+		NotNull:       m.NotNull,
+		IsUnique:      m.Unique,
+		BackfillValue: m.BackfillValue,
+		Status:        string(m.Status),
+		Phase:         string(m.Phase),
+		TotalRows:     m.TotalRows,
+		ProcessedRows: m.ProcessedRows,
+		Attempts:      m.Attempts,
+		CreatedBy:     m.CreatedBy,
+		NextRunAt:     m.NextRunAt,
+		CreatedTime:   m.CreatedAt,
+		UpdatedTime:   m.UpdatedAt,
+		FinishedTime:  m.FinishedAt,
+	}
+	if m.Error != "" {
+		model.Error = &m.Error
+	}
+	return model
+}
+
+func fromSchemaMigrationModel(model *models.SchemaMigration) *schemamigration.Migration {
+	m := &schemamigration.Migration{
+		ID:            model.ID,
+		BaseID:        model.BaseID,
+		TableID:       model.TableID,
+		FieldID:       model.FieldID,
+		Operation:     schemamigration.Operation(model.Operation),
+		Column:        model.ColumnName,
+		ColumnType:    model.ColumnType,
+		FieldType:     model.FieldType,
+		NotNull:       model.NotNull,
+		Unique:        model.IsUnique,
+		BackfillValue: model.BackfillValue,
+		Status:        schemamigration.Status(model.Status),
+		Phase:         schemamigration.Phase(model.Phase),
+		TotalRows:     model.TotalRows,
+		ProcessedRows: model.ProcessedRows,
+		Attempts:      model.Attempts,
+		CreatedBy:     model.CreatedBy,
+		NextRunAt:     model.NextRunAt,
+		CreatedAt:     model.CreatedTime,
+		UpdatedAt:     model.UpdatedTime,
+		FinishedAt:    model.FinishedTime,
+	}
+	if model.Error != nil {
+		m.Error = *model.Error
+	}
+	return m
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// SecurityRepositoryImpl 工作空间安全策略与会话仓储GORM实现
+type SecurityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSecurityRepository 创建安全策略仓储
+func NewSecurityRepository(db *gorm.DB) security.Repository {
+	return &SecurityRepositoryImpl{db: db}
+}
+
+// GetPolicy 获取空间安全策略
+func (r *SecurityRepositoryImpl) GetPolicy(ctx context.Context, spaceID string) (*security.Policy, error) {
+	var model models.SpaceSecurityPolicy
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get security policy: %w", err)
+	}
+
+	allowlist := model.IPAllowlist
+	if allowlist == nil {
+		allowlist = []string{}
+	}
+	return &security.Policy{
+		SpaceID:               model.SpaceID,
+		IPAllowlist:           allowlist,
+		SessionMaxAgeMinutes:  model.SessionMaxAgeMinutes,
+		MaxConcurrentSessions: model.MaxConcurrentSessions,
+		ReauthForSensitive:    model.ReauthForSensitive,
+		ReauthWindowMinutes:   model.ReauthWindowMinutes,
+		UpdatedBy:             model.UpdatedBy,
+		UpdatedTime:           model.UpdatedTime,
+	}, nil
+}
+
+// SavePolicy 保存空间安全策略
+func (r *SecurityRepositoryImpl) SavePolicy(ctx context.Context, policy *security.Policy) error {
+	model := models.SpaceSecurityPolicy{
+		SpaceID:               policy.SpaceID,
+		IPAllowlist:           policy.IPAllowlist,
+		SessionMaxAgeMinutes:  policy.SessionMaxAgeMinutes,
+		MaxConcurrentSessions: policy.MaxConcurrentSessions,
+		ReauthForSensitive:    policy.ReauthForSensitive,
+		ReauthWindowMinutes:   policy.ReauthWindowMinutes,
+		UpdatedBy:             policy.UpdatedBy,
+		UpdatedTime:           policy.UpdatedTime,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save security policy: %w", err)
+	}
+	return nil
+}
+
+// CreateSession 创建会话
+func (r *SecurityRepositoryImpl) CreateSession(ctx context.Context, session *security.Session) error {
+	model := models.UserSession{
+		ID:           session.ID,
+		UserID:       session.UserID,
+		IPAddress:    session.IPAddress,
+		UserAgent:    session.UserAgent,
+		CreatedTime:  session.CreatedTime,
+		LastSeenTime: session.LastSeenTime,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+// GetSession 获取会话
+func (r *SecurityRepositoryImpl) GetSession(ctx context.Context, sessionID string) (*security.Session, error) {
+	var model models.UserSession
+	err := r.db.WithContext(ctx).Where("id = ?", sessionID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return toSession(&model), nil
+}
+
+// TouchSession 更新会话最近活跃时间
+func (r *SecurityRepositoryImpl) TouchSession(ctx context.Context, sessionID string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserSession{}).
+		Where("id = ?", sessionID).
+		Update("last_seen_time", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return nil
+}
+
+// RevokeSession 吊销会话
+func (r *SecurityRepositoryImpl) RevokeSession(ctx context.Context, sessionID string) error {
+	if err := r.db.WithContext(ctx).
+		Model(&models.UserSession{}).
+		Where("id = ? AND revoked_time IS NULL", sessionID).
+		Update("revoked_time", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+// ListActiveSessions 列出用户未吊销的会话
+func (r *SecurityRepositoryImpl) ListActiveSessions(ctx context.Context, userID string) ([]*security.Session, error) {
+	var list []models.UserSession
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_time IS NULL", userID).
+		Order("created_time DESC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	sessions := make([]*security.Session, 0, len(list))
+	for i := range list {
+		sessions = append(sessions, toSession(&list[i]))
+	}
+	return sessions, nil
+}
+
+// RecordViolation 记录策略违规审计日志
+func (r *SecurityRepositoryImpl) RecordViolation(ctx context.Context, v *security.Violation) error {
+	metadata, err := json.Marshal(map[string]string{
+		"rule":       string(v.Rule),
+		"method":     v.Method,
+		"path":       v.Path,
+		"session_id": v.SessionID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal violation metadata: %w", err)
+	}
+
+	rule := string(v.Rule)
+	meta := string(metadata)
+	entry := models.AuditLog{
+		ID:           utils.GenerateIDWithPrefix("aud"),
+		Action:       "security_policy_violation",
+		ResourceType: "space",
+		ResourceID:   &v.SpaceID,
+		Description:  &v.Detail,
+		Status:       "failure",
+		Severity:     "warning",
+		IPAddress:    &v.IPAddress,
+		UserAgent:    &v.UserAgent,
+		SpaceID:      &v.SpaceID,
+		ErrorCode:    &rule,
+		Metadata:     &meta,
+		CreatedTime:  time.Now(),
+	}
+	if v.UserID != "" {
+		entry.UserID = &v.UserID
+	}
+	if v.SessionID != "" {
+		entry.SessionID = &v.SessionID
+	}
+
+	if err := r.db.WithContext(ctx).Omit("User", "Organization", "Space", "Base", "Table", "Record", "Field").
+		Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record security violation: %w", err)
+	}
+	return nil
+}
+
+func toSession(m *models.UserSession) *security.Session {
+	return &security.Session{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		IPAddress:    m.IPAddress,
+		UserAgent:    m.UserAgent,
+		CreatedTime:  m.CreatedTime,
+		LastSeenTime: m.LastSeenTime,
+		RevokedTime:  m.RevokedTime,
+	}
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/semanticsearch"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// hnswMaxDimensions pgvector HNSW 索引支持的最大维度，超出时按精确扫描检索
+const hnswMaxDimensions = 2000
+
+// SemanticSearchRepositoryImpl 语义索引与向量GORM实现（向量存储依赖 pgvector 扩展）
+type SemanticSearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSemanticSearchRepository 创建语义索引仓储
+func NewSemanticSearchRepository(db *gorm.DB) semanticsearch.Repository {
+	return &SemanticSearchRepositoryImpl{db: db}
+}
+
+// GetIndex 获取表的索引
+func (r *SemanticSearchRepositoryImpl) GetIndex(ctx context.Context, tableID string) (*semanticsearch.Index, error) {
+	var model models.SemanticIndex
+	err := r.db.WithContext(ctx).Where("table_id = ?", tableID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get semantic index: %w", err)
+	}
+	return fromSemanticIndexModel(&model), nil
+}
+
+// SaveIndex 创建或更新索引配置（重建时进度一并重置）
+func (r *SemanticSearchRepositoryImpl) SaveIndex(ctx context.Context, index *semanticsearch.Index) error {
+	model, err := toSemanticIndexModel(index)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"field_ids", "provider", "model", "dimensions", "status",
+			"backfill_after", "cursor", "last_error", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save semantic index: %w", err)
+	}
+	return nil
+}
+
+// DeleteIndex 删除索引、向量及近似最近邻索引
+func (r *SemanticSearchRepositoryImpl) DeleteIndex(ctx context.Context, index *semanticsearch.Index) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.resetVectors(tx, index); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", index.ID).Delete(&models.SemanticIndex{}).Error; err != nil {
+			return fmt.Errorf("failed to delete semantic index: %w", err)
+		}
+		return nil
+	})
+}
+
+// ResetVectors 删除表的全部向量及近似最近邻索引（重建索引前调用，避免新旧维度混用）
+func (r *SemanticSearchRepositoryImpl) ResetVectors(ctx context.Context, index *semanticsearch.Index) error {
+	return r.resetVectors(r.db.WithContext(ctx), index)
+}
+
+func (r *SemanticSearchRepositoryImpl) resetVectors(db *gorm.DB, index *semanticsearch.Index) error {
+	if index.Dimensions > 0 && db.Dialector.Name() == "postgres" {
+		if err := db.Exec("DROP INDEX IF EXISTS " + quoteIdentifier(vectorIndexName(index))).Error; err != nil {
+			return fmt.Errorf("failed to drop vector index: %w", err)
+		}
+	}
+	if err := db.Where("table_id = ?", index.TableID).Delete(&models.RecordEmbedding{}).Error; err != nil {
+		return fmt.Errorf("failed to delete record embeddings: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取待处理的索引（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *SemanticSearchRepositoryImpl) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*semanticsearch.Index, error) {
+	var claimed []*semanticsearch.Index
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.SemanticIndex{}).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("updated_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.SemanticIndex
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.SemanticIndex{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromSemanticIndexModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim semantic indexes: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存处理进度并释放租约
+// 领取后配置被修改（updated_time 变化）时只释放租约，不覆盖重建后的进度
+func (r *SemanticSearchRepositoryImpl) SaveProgress(ctx context.Context, index *semanticsearch.Index) error {
+	db := r.db.WithContext(ctx)
+	result := db.Model(&models.SemanticIndex{}).
+		Where("id = ? AND updated_time = ?", index.ID, index.UpdatedAt).
+		Updates(map[string]interface{}{
+			"dimensions":     index.Dimensions,
+			"status":         string(index.Status),
+			"backfill_after": index.BackfillAfter,
+			"cursor":         index.Cursor,
+			"last_error":     index.LastError,
+			"locked_until":   nil,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save semantic index progress: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		if err := db.Model(&models.SemanticIndex{}).Where("id = ?", index.ID).
+			Update("locked_until", nil).Error; err != nil {
+			return fmt.Errorf("failed to release semantic index: %w", err)
+		}
+	}
+	return nil
+}
+
+// EnsureVectorIndex 按索引维度创建 HNSW 部分表达式索引（余弦距离），与 Nearest 的查询表达式一致
+func (r *SemanticSearchRepositoryImpl) EnsureVectorIndex(ctx context.Context, index *semanticsearch.Index) error {
+	if index.Dimensions <= 0 || index.Dimensions > hnswMaxDimensions || r.db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON record_embedding USING hnsw ((embedding::vector(%d)) vector_cosine_ops) WHERE table_id = %s",
+		quoteIdentifier(vectorIndexName(index)), index.Dimensions, quoteLiteral(index.TableID))
+	if err := r.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+		return fmt.Errorf("failed to create vector index: %w", err)
+	}
+	return nil
+}
+
+// ContentHashes 返回记录当前向量对应的文本摘要
+func (r *SemanticSearchRepositoryImpl) ContentHashes(ctx context.Context, tableID string, recordIDs []string) (map[string]string, error) {
+	hashes := make(map[string]string, len(recordIDs))
+	if len(recordIDs) == 0 {
+		return hashes, nil
+	}
+	var rows []models.RecordEmbedding
+	if err := r.db.WithContext(ctx).
+		Select("record_id", "content_hash").
+		Where("table_id = ? AND record_id IN ?", tableID, recordIDs).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get embedding hashes: %w", err)
+	}
+	for _, row := range rows {
+		hashes[row.RecordID] = row.ContentHash
+	}
+	return hashes, nil
+}
+
+// UpsertVectors 写入或替换记录的向量
+// 在同一事务中以共享锁确认索引仍是领取时的版本：与重建互斥，避免旧模型的向量在清空后写回
+func (r *SemanticSearchRepositoryImpl) UpsertVectors(ctx context.Context, index *semanticsearch.Index, vectors []*semanticsearch.Vector) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+	now := time.Now()
+	placeholders := make([]string, 0, len(vectors))
+	args := make([]interface{}, 0, len(vectors)*6)
+	for _, v := range vectors {
+		placeholders = append(placeholders, "(?, ?, ?::vector, ?, ?, ?)")
+		args = append(args, v.TableID, v.RecordID, vectorLiteral(v.Embedding), v.ContentHash, v.Model, now)
+	}
+	stmt := "INSERT INTO record_embedding (table_id, record_id, embedding, content_hash, model, updated_time) VALUES " +
+		strings.Join(placeholders, ", ") +
+		" ON CONFLICT (table_id, record_id) DO UPDATE SET embedding = EXCLUDED.embedding, content_hash = EXCLUDED.content_hash," +
+		" model = EXCLUDED.model, updated_time = EXCLUDED.updated_time"
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Model(&models.SemanticIndex{}).
+			Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id = ? AND updated_time = ?", index.ID, index.UpdatedAt).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to lock semantic index: %w", err)
+		}
+		if len(ids) == 0 {
+			return semanticsearch.ErrIndexChanged
+		}
+		if err := tx.Exec(stmt, args...).Error; err != nil {
+			return fmt.Errorf("failed to upsert record embeddings: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteVectors 删除记录的向量
+func (r *SemanticSearchRepositoryImpl) DeleteVectors(ctx context.Context, tableID string, recordIDs []string) error {
+	if len(recordIDs) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ? AND record_id IN ?", tableID, recordIDs).
+		Delete(&models.RecordEmbedding{}).Error; err != nil {
+		return fmt.Errorf("failed to delete record embeddings: %w", err)
+	}
+	return nil
+}
+
+// Nearest 按余弦距离返回最相近的记录，得分为余弦相似度
+func (r *SemanticSearchRepositoryImpl) Nearest(ctx context.Context, index *semanticsearch.Index, query []float32, limit int) ([]semanticsearch.Match, error) {
+	if index.Dimensions <= 0 || len(query) != index.Dimensions {
+		return nil, fmt.Errorf("query has %d dimensions, index has %d", len(query), index.Dimensions)
+	}
+	distance := fmt.Sprintf("embedding::vector(%d) <=> ?::vector(%d)", index.Dimensions, index.Dimensions)
+	literal := vectorLiteral(query)
+	var rows []struct {
+		RecordID string
+		Distance float64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.RecordEmbedding{}).
+		Select("record_id, "+distance+" AS distance", literal).
+		Where("table_id = ?", index.TableID).
+		Order(clause.Expr{SQL: distance, Vars: []interface{}{literal}}).
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to search record embeddings: %w", err)
+	}
+	matches := make([]semanticsearch.Match, 0, len(rows))
+	for _, row := range rows {
+		matches = append(matches, semanticsearch.Match{RecordID: row.RecordID, Score: 1 - row.Distance})
+	}
+	return matches, nil
+}
+
+// vectorIndexName 近似最近邻索引名（按表与维度区分）
+func vectorIndexName(index *semanticsearch.Index) string {
+	return fmt.Sprintf("idx_record_embedding_%s_%d", index.TableID, index.Dimensions)
+}
+
+// vectorLiteral pgvector 文本格式
+func vectorLiteral(values []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// quoteLiteral 转义 SQL 字符串字面量（DDL 中无法使用绑定参数）
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func toSemanticIndexModel(index *semanticsearch.Index) (models.SemanticIndex, error) {
+	fieldIDs, err := json.Marshal(index.FieldIDs)
+	if err != nil {
+		return models.SemanticIndex{}, fmt.Errorf("failed to marshal semantic index fields: %w", err)
+	}
+	return models.SemanticIndex{
+		ID:            index.ID,
+		SpaceID:       index.SpaceID,
+		BaseID:        index.BaseID,
+		TableID:       index.TableID,
+		FieldIDs:      string(fieldIDs),
+		Provider:      index.Provider,
+		Model:         index.Model,
+		Dimensions:    index.Dimensions,
+		Status:        string(index.Status),
+		BackfillAfter: index.BackfillAfter,
+		Cursor:        index.Cursor,
+		LastError:     index.LastError,
+		CreatedBy:     index.CreatedBy,
+		CreatedTime:   index.CreatedAt,
+		UpdatedTime:   index.UpdatedAt,
+	}, nil
+}
+
+func fromSemanticIndexModel(model *models.SemanticIndex) *semanticsearch.Index {
+	index := &semanticsearch.Index{
+		ID:            model.ID,
+		SpaceID:       model.SpaceID,
+		BaseID:        model.BaseID,
+		TableID:       model.TableID,
+		Provider:      model.Provider,
+		Model:         model.Model,
+		Dimensions:    model.Dimensions,
+		Status:        semanticsearch.Status(model.Status),
+		BackfillAfter: model.BackfillAfter,
+		Cursor:        model.Cursor,
+		LastError:     model.LastError,
+		LeaseUntil:    model.LockedUntil,
+		CreatedBy:     model.CreatedBy,
+		CreatedAt:     model.CreatedTime,
+		UpdatedAt:     model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.FieldIDs), &index.FieldIDs)
+	if index.FieldIDs == nil {
+		index.FieldIDs = []string{}
+	}
+	return index
+}
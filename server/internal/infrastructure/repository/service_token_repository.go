@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/servicetoken"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// ServiceTokenRepositoryImpl 服务令牌仓储GORM实现
+type ServiceTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewServiceTokenRepository 创建服务令牌仓储
+func NewServiceTokenRepository(db *gorm.DB) servicetoken.Repository {
+	return &ServiceTokenRepositoryImpl{db: db}
+}
+
+// Create 保存新令牌
+func (r *ServiceTokenRepositoryImpl) Create(ctx context.Context, token *servicetoken.Token) error {
+	model := models.ServiceToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		UserID:    token.UserID,
+		TokenHash: token.TokenHash,
+		Hint:      token.Hint,
+		ExpiresAt: token.ExpiresAt,
+		CreatedAt: token.CreatedAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to create service token: %w", err)
+	}
+	return nil
+}
+
+// GetByHash 按摘要查找令牌
+func (r *ServiceTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*servicetoken.Token, error) {
+	return r.take(ctx, "token_hash = ?", tokenHash)
+}
+
+// GetByID 按ID查找令牌
+func (r *ServiceTokenRepositoryImpl) GetByID(ctx context.Context, id string) (*servicetoken.Token, error) {
+	return r.take(ctx, "id = ?", id)
+}
+
+// ListByUser 列出用户的令牌
+func (r *ServiceTokenRepositoryImpl) ListByUser(ctx context.Context, userID string) ([]*servicetoken.Token, error) {
+	var list []models.ServiceToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&list).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service tokens: %w", err)
+	}
+	tokens := make([]*servicetoken.Token, 0, len(list))
+	for i := range list {
+		tokens = append(tokens, toServiceTokenEntity(&list[i]))
+	}
+	return tokens, nil
+}
+
+// Revoke 吊销令牌
+func (r *ServiceTokenRepositoryImpl) Revoke(ctx context.Context, id string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.ServiceToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke service token: %w", err)
+	}
+	return nil
+}
+
+// TouchLastUsed 更新最近使用时间
+func (r *ServiceTokenRepositoryImpl) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&models.ServiceToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+	if err != nil {
+		return fmt.Errorf("failed to touch service token: %w", err)
+	}
+	return nil
+}
+
+func (r *ServiceTokenRepositoryImpl) take(ctx context.Context, query string, arg interface{}) (*servicetoken.Token, error) {
+	var model models.ServiceToken
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service token: %w", err)
+	}
+	return toServiceTokenEntity(&model), nil
+}
+
+func toServiceTokenEntity(model *models.ServiceToken) *servicetoken.Token {
+	return &servicetoken.Token{
+		ID:         model.ID,
+		Name:       model.Name,
+		UserID:     model.UserID,
+		TokenHash:  model.TokenHash,
+		Hint:       model.Hint,
+		ExpiresAt:  model.ExpiresAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+		CreatedAt:  model.CreatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/statusflow"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// StatusFlowRepositoryImpl 状态流GORM实现
+type StatusFlowRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStatusFlowRepository 创建状态流仓储
+func NewStatusFlowRepository(db *gorm.DB) statusflow.Repository {
+	return &StatusFlowRepositoryImpl{db: db}
+}
+
+// Save 保存状态流
+func (r *StatusFlowRepositoryImpl) Save(ctx context.Context, flow *statusflow.Flow) error {
+	initial, err := json.Marshal(flow.InitialStatuses)
+	if err != nil {
+		return err
+	}
+	transitions, err := json.Marshal(flow.Transitions)
+	if err != nil {
+		return err
+	}
+	model := models.StatusFlow{
+		ID:              flow.ID,
+		TableID:         flow.TableID,
+		FieldID:         flow.FieldID,
+		InitialStatuses: string(initial),
+		Transitions:     string(transitions),
+		Enabled:         flow.Enabled,
+		CreatedBy:       flow.CreatedBy,
+		UpdatedBy:       flow.UpdatedBy,
+		CreatedTime:     flow.CreatedAt,
+		UpdatedTime:     flow.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save status flow: %w", err)
+	}
+	return nil
+}
+
+// FindByField 获取字段的状态流
+func (r *StatusFlowRepositoryImpl) FindByField(ctx context.Context, fieldID string) (*statusflow.Flow, error) {
+	var model models.StatusFlow
+	err := r.db.WithContext(ctx).Where("field_id = ?", fieldID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status flow: %w", err)
+	}
+	return fromStatusFlowModel(&model), nil
+}
+
+// ListByTable 列出表的状态流
+func (r *StatusFlowRepositoryImpl) ListByTable(ctx context.Context, tableID string) ([]*statusflow.Flow, error) {
+	var list []models.StatusFlow
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list status flows: %w", err)
+	}
+	flows := make([]*statusflow.Flow, 0, len(list))
+	for i := range list {
+		flows = append(flows, fromStatusFlowModel(&list[i]))
+	}
+	return flows, nil
+}
+
+// Delete 删除状态流
+func (r *StatusFlowRepositoryImpl) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.StatusFlow{}).Error; err != nil {
+		return fmt.Errorf("failed to delete status flow: %w", err)
+	}
+	return nil
+}
+
+func fromStatusFlowModel(model *models.StatusFlow) *statusflow.Flow {
+	flow := &statusflow.Flow{
+		ID:        model.ID,
+		TableID:   model.TableID,
+		FieldID:   model.FieldID,
+		Enabled:   model.Enabled,
+		CreatedBy: model.CreatedBy,
+		UpdatedBy: model.UpdatedBy,
+		CreatedAt: model.CreatedTime,
+		UpdatedAt: model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.InitialStatuses), &flow.InitialStatuses)
+	_ = json.Unmarshal([]byte(model.Transitions), &flow.Transitions)
+	if flow.InitialStatuses == nil {
+		flow.InitialStatuses = []string{}
+	}
+	return flow
+}
@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// SubjectDataStoreImpl 数据主体数据定位实现（直接访问物理表）
+type SubjectDataStoreImpl struct {
+	db           *gorm.DB
+	dbProvider   database.DBProvider
+	regionRouter *database.RegionRouter
+}
+
+// NewSubjectDataStore 创建数据主体数据定位器
+func NewSubjectDataStore(db *gorm.DB, dbProvider database.DBProvider) *SubjectDataStoreImpl {
+	return &SubjectDataStoreImpl{
+		db:         db,
+		dbProvider: dbProvider,
+	}
+}
+
+// SetRegionRouter 设置数据驻留路由器（动态数据表按空间区域存放）
+func (s *SubjectDataStoreImpl) SetRegionRouter(router *database.RegionRouter) {
+	s.regionRouter = router
+}
+
+// dataDB 获取 Base 数据面连接
+func (s *SubjectDataStoreImpl) dataDB(ctx context.Context, baseID string) *gorm.DB {
+	if s.regionRouter == nil {
+		return s.db
+	}
+	return s.regionRouter.DBForBase(ctx, baseID)
+}
+
+// ResolveSubject 补全数据主体的用户ID/邮箱
+func (s *SubjectDataStoreImpl) ResolveSubject(ctx context.Context, subject privacy.Subject) (privacy.Subject, error) {
+	if subject.UserID != "" && subject.Email != "" {
+		return subject, nil
+	}
+
+	var user models.User
+	query := s.db.WithContext(ctx).Unscoped().Select("id", "email")
+	if subject.UserID != "" {
+		query = query.Where("id = ?", subject.UserID)
+	} else {
+		query = query.Where("LOWER(email) = LOWER(?)", subject.Email)
+	}
+	err := query.Take(&user).Error
+	if err == gorm.ErrRecordNotFound {
+		// 非注册用户（例如仅在单元格中出现的邮箱）也允许按已有标识处理
+		return subject, nil
+	}
+	if err != nil {
+		return subject, fmt.Errorf("failed to resolve subject: %w", err)
+	}
+
+	if subject.UserID == "" {
+		subject.UserID = user.ID
+	}
+	if subject.Email == "" {
+		subject.Email = user.Email
+	}
+	return subject, nil
+}
+
+// spaceTable 空间内的数据表
+type spaceTable struct {
+	ID     string `gorm:"column:id"`
+	BaseID string `gorm:"column:base_id"`
+}
+
+// listSpaceTables 列出空间内所有未删除的数据表
+func (s *SubjectDataStoreImpl) listSpaceTables(ctx context.Context, spaceID string) ([]spaceTable, error) {
+	var tables []spaceTable
+	if err := s.db.WithContext(ctx).
+		Table("table_meta").
+		Select("table_meta.id, table_meta.base_id").
+		Joins("JOIN base ON base.id = table_meta.base_id").
+		Where("base.space_id = ? AND table_meta.deleted_time IS NULL AND base.deleted_time IS NULL", spaceID).
+		Order("table_meta.base_id, table_meta.id").
+		Find(&tables).Error; err != nil {
+		return nil, fmt.Errorf("failed to list space tables: %w", err)
+	}
+	return tables, nil
+}
+
+// FindReferences 查找空间内引用数据主体的记录、评论和附件
+// 记录按整行 JSON 文本匹配（加密字段中的密文无法匹配，只能通过创建人/修改人命中）
+func (s *SubjectDataStoreImpl) FindReferences(ctx context.Context, spaceID string, subject privacy.Subject) ([]privacy.SubjectReference, error) {
+	tables, err := s.listSpaceTables(ctx, spaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]privacy.SubjectReference, 0)
+	tableBase := make(map[string]string, len(tables))
+	tableIDs := make([]string, 0, len(tables))
+	for _, t := range tables {
+		tableBase[t.ID] = t.BaseID
+		tableIDs = append(tableIDs, t.ID)
+
+		records, err := s.findRecordReferences(ctx, t, subject)
+		if isUndefinedTable(err) {
+			// 物理表不存在（创建中途失败等）时其中没有数据
+			logger.Warn("数据表的物理表不存在，跳过",
+				logger.String("table_id", t.ID),
+				logger.ErrorField(err))
+			continue
+		}
+		if err != nil {
+			// 其他失败必须返回，否则复查会把没有查到的数据当作已擦除
+			return nil, fmt.Errorf("failed to find subject records in table %s: %w", t.ID, err)
+		}
+		refs = append(refs, records...)
+	}
+	if len(tableIDs) == 0 {
+		return refs, nil
+	}
+
+	comments, err := s.findCommentReferences(ctx, tableIDs, tableBase, subject)
+	if err != nil {
+		return nil, err
+	}
+	refs = append(refs, comments...)
+
+	if subject.UserID != "" {
+		attachments, err := s.findAttachmentReferences(ctx, tableIDs, tableBase, subject)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, attachments...)
+	}
+
+	return refs, nil
+}
+
+// isUndefinedTable 是否为表不存在导致的失败（SQLSTATE 42P01）
+func isUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "42P01"
+}
+
+func (s *SubjectDataStoreImpl) findRecordReferences(ctx context.Context, t spaceTable, subject privacy.Subject) ([]privacy.SubjectReference, error) {
+	fullTableName, err := s.dbProvider.ResolveTableName(ctx, t.BaseID, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]string, 0, 4)
+	args := make([]interface{}, 0, 4)
+	if subject.UserID != "" {
+		conditions = append(conditions, "t.__created_by = ?", "t.__last_modified_by = ?")
+		args = append(args, subject.UserID, subject.UserID)
+	}
+	for _, term := range subject.Terms() {
+		conditions = append(conditions, "to_jsonb(t)::text ILIKE ?")
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+
+	var rows []struct {
+		ID        string `gorm:"column:__id"`
+		CreatedBy string `gorm:"column:__created_by"`
+	}
+	if err := s.dataDB(ctx, t.BaseID).WithContext(ctx).
+		Table(fullTableName+" AS t").
+		Select("t.__id, t.__created_by").
+		Where(strings.Join(conditions, " OR "), args...).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	refs := make([]privacy.SubjectReference, 0, len(rows))
+	for _, row := range rows {
+		refs = append(refs, privacy.SubjectReference{
+			Kind:     privacy.ReferenceRecord,
+			ID:       row.ID,
+			BaseID:   t.BaseID,
+			TableID:  t.ID,
+			RecordID: row.ID,
+			Owned:    subject.UserID != "" && row.CreatedBy == subject.UserID,
+		})
+	}
+	return refs, nil
+}
+
+func (s *SubjectDataStoreImpl) findCommentReferences(ctx context.Context, tableIDs []string, tableBase map[string]string, subject privacy.Subject) ([]privacy.SubjectReference, error) {
+	conditions := make([]string, 0, 3)
+	args := make([]interface{}, 0, 3)
+	if subject.UserID != "" {
+		conditions = append(conditions, "created_by = ?")
+		args = append(args, subject.UserID)
+	}
+	for _, term := range subject.Terms() {
+		conditions = append(conditions, "content ILIKE ?")
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+
+	// 已软删除的评论同样包含个人数据，一并处理
+	var comments []models.Comment
+	if err := s.db.WithContext(ctx).
+		Where("table_id IN ?", tableIDs).
+		Where(strings.Join(conditions, " OR "), args...).
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("failed to find subject comments: %w", err)
+	}
+
+	refs := make([]privacy.SubjectReference, 0, len(comments))
+	for _, c := range comments {
+		refs = append(refs, privacy.SubjectReference{
+			Kind:     privacy.ReferenceComment,
+			ID:       c.ID,
+			BaseID:   tableBase[c.TableID],
+			TableID:  c.TableID,
+			RecordID: c.RecordID,
+			Owned:    subject.UserID != "" && c.CreatedBy == subject.UserID,
+		})
+	}
+	return refs, nil
+}
+
+func (s *SubjectDataStoreImpl) findAttachmentReferences(ctx context.Context, tableIDs []string, tableBase map[string]string, subject privacy.Subject) ([]privacy.SubjectReference, error) {
+	var attachments []models.Attachment
+	if err := s.db.WithContext(ctx).Unscoped().
+		Where("table_id IN ? AND created_by = ?", tableIDs, subject.UserID).
+		Find(&attachments).Error; err != nil {
+		return nil, fmt.Errorf("failed to find subject attachments: %w", err)
+	}
+
+	refs := make([]privacy.SubjectReference, 0, len(attachments))
+	for _, a := range attachments {
+		refs = append(refs, privacy.SubjectReference{
+			Kind:     privacy.ReferenceAttachment,
+			ID:       a.ID,
+			BaseID:   tableBase[a.TableID],
+			TableID:  a.TableID,
+			RecordID: a.RecordID,
+			Owned:    true,
+		})
+	}
+	return refs, nil
+}
+
+// AnonymizeRecordAuthorship 将记录的创建人/修改人替换为匿名用户
+func (s *SubjectDataStoreImpl) AnonymizeRecordAuthorship(ctx context.Context, ref privacy.SubjectReference, userID string) error {
+	if userID == "" {
+		return nil
+	}
+	fullTableName, err := s.dbProvider.ResolveTableName(ctx, ref.BaseID, ref.TableID)
+	if err != nil {
+		return err
+	}
+	db := s.dataDB(ctx, ref.BaseID).WithContext(ctx)
+
+	for _, column := range []string{"__created_by", "__last_modified_by"} {
+		if err := db.Table(fullTableName).
+			Where("__id = ? AND "+column+" = ?", ref.RecordID, userID).
+			Update(column, privacy.AnonymousUserID).Error; err != nil {
+			return fmt.Errorf("failed to anonymize record %s: %w", column, err)
+		}
+	}
+	return nil
+}
+
+// GetComment 获取评论内容
+func (s *SubjectDataStoreImpl) GetComment(ctx context.Context, commentID string) (map[string]interface{}, error) {
+	var comment models.Comment
+	if err := s.db.WithContext(ctx).Where("id = ?", commentID).Take(&comment).Error; err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return map[string]interface{}{
+		"id":           comment.ID,
+		"table_id":     comment.TableID,
+		"record_id":    comment.RecordID,
+		"quote_id":     comment.QuoteID,
+		"content":      comment.Content,
+		"reaction":     comment.Reaction,
+		"created_by":   comment.CreatedBy,
+		"created_time": comment.CreatedTime,
+	}, nil
+}
+
+// AnonymizeComment 匿名化评论
+func (s *SubjectDataStoreImpl) AnonymizeComment(ctx context.Context, commentID string, subject privacy.Subject) error {
+	var comment models.Comment
+	if err := s.db.WithContext(ctx).Where("id = ?", commentID).Take(&comment).Error; err != nil {
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"last_modified_time": time.Now(),
+	}
+	if subject.UserID != "" && comment.CreatedBy == subject.UserID {
+		updates["created_by"] = privacy.AnonymousUserID
+	}
+	if comment.Content != nil {
+		if redacted, changed := privacy.RedactValue(*comment.Content, subject); changed {
+			updates["content"] = redacted
+		}
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&models.Comment{}).
+		Where("id = ?", commentID).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to anonymize comment: %w", err)
+	}
+	return nil
+}
+
+// DeleteComment 删除评论（GDPR 擦除为物理删除）
+func (s *SubjectDataStoreImpl) DeleteComment(ctx context.Context, commentID string) error {
+	if err := s.db.WithContext(ctx).
+		Where("id = ?", commentID).
+		Delete(&models.Comment{}).Error; err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// GetAttachment 获取附件元数据
+func (s *SubjectDataStoreImpl) GetAttachment(ctx context.Context, attachmentID string) (map[string]interface{}, error) {
+	var a models.Attachment
+	if err := s.db.WithContext(ctx).Unscoped().Where("id = ?", attachmentID).Take(&a).Error; err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	filePath := a.Path
+	if a.ContentHash != "" {
+		filePath = attachment.BlobPath(a.ContentHash)
+	}
+	return map[string]interface{}{
+		"id":           a.ID,
+		"name":         a.Name,
+		"path":         filePath,
+		"size":         a.Size,
+		"mime_type":    a.MimeType,
+		"table_id":     a.TableID,
+		"field_id":     a.FieldID,
+		"record_id":    a.RecordID,
+		"created_by":   a.CreatedBy,
+		"created_time": a.CreatedTime,
+	}, nil
+}
+
+// AnonymizeAttachment 将附件上传人替换为匿名用户
+func (s *SubjectDataStoreImpl) AnonymizeAttachment(ctx context.Context, attachmentID string) error {
+	if err := s.db.WithContext(ctx).Unscoped().
+		Model(&models.Attachment{}).
+		Where("id = ?", attachmentID).
+		Update("created_by", privacy.AnonymousUserID).Error; err != nil {
+		return fmt.Errorf("failed to anonymize attachment: %w", err)
+	}
+	return nil
+}
+
+// DeleteAttachment 物理删除附件元数据，返回需要清理的存储路径
+func (s *SubjectDataStoreImpl) DeleteAttachment(ctx context.Context, attachmentID string) ([]string, error) {
+	var a models.Attachment
+	if err := s.db.WithContext(ctx).Unscoped().Where("id = ?", attachmentID).Take(&a).Error; err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Unscoped().Delete(&a).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	// 按内容寻址的文件可能被其他附件共用，只释放引用，由回收任务清理
+	var paths []string
+	if a.ContentHash != "" {
+		if err := NewAttachmentBlobRepository(s.db).Release(ctx, a.ContentHash); err != nil {
+			return nil, err
+		}
+	} else {
+		paths = append(paths, a.Path)
+	}
+	for _, thumb := range []*string{a.SmallThumbnail, a.LargeThumbnail} {
+		if thumb != nil && *thumb != "" {
+			paths = append(paths, *thumb)
+		}
+	}
+	return paths, nil
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// SubscriptionRepositoryImpl Stripe 订阅仓储GORM实现
+type SubscriptionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository 创建 Stripe 订阅仓储
+func NewSubscriptionRepository(db *gorm.DB) subscription.Repository {
+	return &SubscriptionRepositoryImpl{db: db}
+}
+
+// FindBySpace 按工作空间查找订阅
+func (r *SubscriptionRepositoryImpl) FindBySpace(ctx context.Context, spaceID string) (*subscription.Subscription, error) {
+	return r.take(ctx, "space_id = ?", spaceID)
+}
+
+// FindBySubscriptionID 按 Stripe 订阅ID查找
+func (r *SubscriptionRepositoryImpl) FindBySubscriptionID(ctx context.Context, subscriptionID string) (*subscription.Subscription, error) {
+	return r.take(ctx, "subscription_id = ?", subscriptionID)
+}
+
+// FindByCustomerID 按 Stripe 客户ID查找（同一客户有多个工作空间时取最近更新的）
+func (r *SubscriptionRepositoryImpl) FindByCustomerID(ctx context.Context, customerID string) (*subscription.Subscription, error) {
+	var model models.SpaceSubscription
+	err := r.db.WithContext(ctx).Where("customer_id = ?", customerID).Order("updated_at DESC").Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+	return toSubscriptionEntity(&model), nil
+}
+
+// Save 保存订阅
+func (r *SubscriptionRepositoryImpl) Save(ctx context.Context, sub *subscription.Subscription) error {
+	model := models.SpaceSubscription{
+		SpaceID:           sub.SpaceID,
+		CustomerID:        sub.CustomerID,
+		SubscriptionID:    sub.SubscriptionID,
+		PriceID:           sub.PriceID,
+		Status:            string(sub.Status),
+		PlanID:            sub.PlanID,
+		PendingPlanID:     sub.PendingPlanID,
+		CurrentPeriodEnd:  sub.CurrentPeriodEnd,
+		CancelAtPeriodEnd: sub.CancelAtPeriodEnd,
+		PastDueSince:      sub.PastDueSince,
+		LastEventAt:       sub.LastEventAt,
+		UpdatedAt:         sub.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save subscription: %w", err)
+	}
+	return nil
+}
+
+// ListDue 列出有待生效降级或处于欠费中的订阅
+func (r *SubscriptionRepositoryImpl) ListDue(ctx context.Context) ([]*subscription.Subscription, error) {
+	var list []models.SpaceSubscription
+	err := r.db.WithContext(ctx).
+		Where("pending_plan_id <> '' OR past_due_since IS NOT NULL").
+		Find(&list).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due subscriptions: %w", err)
+	}
+	subs := make([]*subscription.Subscription, 0, len(list))
+	for i := range list {
+		subs = append(subs, toSubscriptionEntity(&list[i]))
+	}
+	return subs, nil
+}
+
+// EventProcessed 事件是否已处理过
+func (r *SubscriptionRepositoryImpl) EventProcessed(ctx context.Context, eventID string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.BillingEvent{}).Where("id = ?", eventID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check billing event: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RecordEvent 记录已处理的事件（重复记录时忽略）
+func (r *SubscriptionRepositoryImpl) RecordEvent(ctx context.Context, eventID, eventType string, at time.Time) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models.BillingEvent{
+		ID:          eventID,
+		Type:        eventType,
+		ProcessedAt: at,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record billing event: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepositoryImpl) take(ctx context.Context, query string, arg interface{}) (*subscription.Subscription, error) {
+	var model models.SpaceSubscription
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+	return toSubscriptionEntity(&model), nil
+}
+
+func toSubscriptionEntity(model *models.SpaceSubscription) *subscription.Subscription {
+	return &subscription.Subscription{
+		SpaceID:           model.SpaceID,
+		CustomerID:        model.CustomerID,
+		SubscriptionID:    model.SubscriptionID,
+		PriceID:           model.PriceID,
+		Status:            subscription.Status(model.Status),
+		PlanID:            model.PlanID,
+		PendingPlanID:     model.PendingPlanID,
+		CurrentPeriodEnd:  model.CurrentPeriodEnd,
+		CancelAtPeriodEnd: model.CancelAtPeriodEnd,
+		PastDueSince:      model.PastDueSince,
+		LastEventAt:       model.LastEventAt,
+		UpdatedAt:         model.UpdatedAt,
+	}
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/syncedtable"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// SyncedTableRepositoryImpl 同步表GORM实现
+type SyncedTableRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSyncedTableRepository 创建同步表仓储
+func NewSyncedTableRepository(db *gorm.DB) syncedtable.Repository {
+	return &SyncedTableRepositoryImpl{db: db}
+}
+
+// Save 创建或更新同步配置
+// 同步进度（cursor、field_map、last_* 等）只由 SaveProgress 写入，避免修改配置时回退并发运行的进度
+func (r *SyncedTableRepositoryImpl) Save(ctx context.Context, sync *syncedtable.Sync) error {
+	model, err := toSyncedTableModel(sync)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "interval_seconds", "enabled", "next_run_time", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save synced table: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取同步表
+func (r *SyncedTableRepositoryImpl) FindByID(ctx context.Context, id string) (*syncedtable.Sync, error) {
+	return r.take(ctx, "id = ?", id)
+}
+
+// FindByTargetTable 获取镜像到该表的同步
+func (r *SyncedTableRepositoryImpl) FindByTargetTable(ctx context.Context, tableID string) (*syncedtable.Sync, error) {
+	return r.take(ctx, "target_table_id = ?", tableID)
+}
+
+func (r *SyncedTableRepositoryImpl) take(ctx context.Context, query string, arg interface{}) (*syncedtable.Sync, error) {
+	var model models.SyncedTable
+	err := r.db.WithContext(ctx).Where(query, arg).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get synced table: %w", err)
+	}
+	return fromSyncedTableModel(&model), nil
+}
+
+// ListByTargetBase 列出镜像到该 Base 的同步表
+func (r *SyncedTableRepositoryImpl) ListByTargetBase(ctx context.Context, baseID string) ([]*syncedtable.Sync, error) {
+	var list []models.SyncedTable
+	if err := r.db.WithContext(ctx).
+		Where("target_base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list synced tables: %w", err)
+	}
+	syncs := make([]*syncedtable.Sync, 0, len(list))
+	for i := range list {
+		syncs = append(syncs, fromSyncedTableModel(&list[i]))
+	}
+	return syncs, nil
+}
+
+// Delete 删除同步表配置及记录对应关系
+func (r *SyncedTableRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sync_id = ?", id).Delete(&models.SyncedTableRow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.SyncedTable{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete synced table: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取到期同步（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *SyncedTableRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*syncedtable.Sync, error) {
+	var claimed []*syncedtable.Sync
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.SyncedTable{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.SyncedTable
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.SyncedTable{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromSyncedTableModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim synced tables: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存同步进度并释放租约
+func (r *SyncedTableRepositoryImpl) SaveProgress(ctx context.Context, sync *syncedtable.Sync) error {
+	fieldMap, err := json.Marshal(sync.FieldMap)
+	if err != nil {
+		return fmt.Errorf("failed to marshal synced table field map: %w", err)
+	}
+	if err := r.db.WithContext(ctx).Model(&models.SyncedTable{}).
+		Where("id = ?", sync.ID).
+		Updates(map[string]interface{}{
+			"cursor":           sync.Cursor,
+			"field_map":        string(fieldMap),
+			"deleted_field_id": sync.DeletedFieldID,
+			"next_run_time":    sync.NextRunAt,
+			"last_run_time":    sync.LastRunAt,
+			"last_status":      string(sync.LastStatus),
+			"last_error":       sync.LastError,
+			"locked_until":     nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save synced table progress: %w", err)
+	}
+	return nil
+}
+
+// FindRows 获取源记录对应的镜像记录
+func (r *SyncedTableRepositoryImpl) FindRows(ctx context.Context, syncID string, sourceRecordIDs []string) ([]*syncedtable.Row, error) {
+	if len(sourceRecordIDs) == 0 {
+		return nil, nil
+	}
+	var list []models.SyncedTableRow
+	if err := r.db.WithContext(ctx).
+		Where("sync_id = ? AND source_record_id IN ?", syncID, sourceRecordIDs).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to find synced table rows: %w", err)
+	}
+	return fromSyncedTableRowModels(list), nil
+}
+
+// SaveRows 创建或更新记录对应关系
+func (r *SyncedTableRepositoryImpl) SaveRows(ctx context.Context, rows []*syncedtable.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	list := make([]models.SyncedTableRow, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, models.SyncedTableRow{
+			SyncID:         row.SyncID,
+			SourceRecordID: row.SourceRecordID,
+			TargetRecordID: row.TargetRecordID,
+			SourceDeleted:  row.SourceDeleted,
+			SyncedTime:     row.SyncedAt,
+		})
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "sync_id"}, {Name: "source_record_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_record_id", "source_deleted", "synced_time"}),
+	}).CreateInBatches(list, 500).Error; err != nil {
+		return fmt.Errorf("failed to save synced table rows: %w", err)
+	}
+	return nil
+}
+
+// ListStaleRows 列出 before 之前写入且未标记删除的对应关系
+func (r *SyncedTableRepositoryImpl) ListStaleRows(ctx context.Context, syncID string, before time.Time, limit int) ([]*syncedtable.Row, error) {
+	var list []models.SyncedTableRow
+	if err := r.db.WithContext(ctx).
+		Where("sync_id = ? AND synced_time < ? AND source_deleted = ?", syncID, before, false).
+		Order("source_record_id ASC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stale synced table rows: %w", err)
+	}
+	return fromSyncedTableRowModels(list), nil
+}
+
+func toSyncedTableModel(sync *syncedtable.Sync) (models.SyncedTable, error) {
+	fieldMap, err := json.Marshal(sync.FieldMap)
+	if err != nil {
+		return models.SyncedTable{}, fmt.Errorf("failed to marshal synced table field map: %w", err)
+	}
+	return models.SyncedTable{
+		ID:              sync.ID,
+		Name:            sync.Name,
+		SourceBaseID:    sync.SourceBaseID,
+		SourceTableID:   sync.SourceTableID,
+		SourceViewID:    sync.SourceViewID,
+		TargetBaseID:    sync.TargetBaseID,
+		TargetTableID:   sync.TargetTableID,
+		FieldMap:        string(fieldMap),
+		DeletedFieldID:  sync.DeletedFieldID,
+		IntervalSeconds: int64(sync.Interval / time.Second),
+		Enabled:         sync.Enabled,
+		Cursor:          sync.Cursor,
+		NextRunTime:     sync.NextRunAt,
+		LastRunTime:     sync.LastRunAt,
+		LastStatus:      string(sync.LastStatus),
+		LastError:       sync.LastError,
+		CreatedBy:       sync.CreatedBy,
+		CreatedTime:     sync.CreatedAt,
+		UpdatedTime:     sync.UpdatedAt,
+	}, nil
+}
+
+func fromSyncedTableModel(model *models.SyncedTable) *syncedtable.Sync {
+	sync := &syncedtable.Sync{
+		ID:             model.ID,
+		Name:           model.Name,
+		SourceBaseID:   model.SourceBaseID,
+		SourceTableID:  model.SourceTableID,
+		SourceViewID:   model.SourceViewID,
+		TargetBaseID:   model.TargetBaseID,
+		TargetTableID:  model.TargetTableID,
+		FieldMap:       map[string]string{},
+		DeletedFieldID: model.DeletedFieldID,
+		Interval:       time.Duration(model.IntervalSeconds) * time.Second,
+		Enabled:        model.Enabled,
+		Cursor:         model.Cursor,
+		NextRunAt:      model.NextRunTime,
+		LastRunAt:      model.LastRunTime,
+		LastStatus:     syncedtable.RunStatus(model.LastStatus),
+		LastError:      model.LastError,
+		CreatedBy:      model.CreatedBy,
+		CreatedAt:      model.CreatedTime,
+		UpdatedAt:      model.UpdatedTime,
+	}
+	if model.FieldMap != "" {
+		_ = json.Unmarshal([]byte(model.FieldMap), &sync.FieldMap)
+	}
+	return sync
+}
+
+func fromSyncedTableRowModels(list []models.SyncedTableRow) []*syncedtable.Row {
+	rows := make([]*syncedtable.Row, 0, len(list))
+	for i := range list {
+		rows = append(rows, &syncedtable.Row{
+			SyncID:         list[i].SyncID,
+			SourceRecordID: list[i].SourceRecordID,
+			TargetRecordID: list[i].TargetRecordID,
+			SourceDeleted:  list[i].SourceDeleted,
+			SyncedAt:       list[i].SyncedTime,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,546 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/tablehealth"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// tableHealthIssueBatchSize 批量写入问题时每批的行数
+const tableHealthIssueBatchSize = 500
+
+// TableHealthRepositoryImpl 表健康检查GORM实现
+type TableHealthRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTableHealthRepository 创建表健康检查仓储
+func NewTableHealthRepository(db *gorm.DB) tablehealth.Repository {
+	return &TableHealthRepositoryImpl{db: db}
+}
+
+// SaveReport 创建或更新检查报告
+func (r *TableHealthRepositoryImpl) SaveReport(ctx context.Context, report *tablehealth.Report) error {
+	counts, err := json.Marshal(report.Counts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal health report counts: %w", err)
+	}
+	model := models.TableHealthReport{
+		ID:           report.ID,
+		TableID:      report.TableID,
+		Trigger:      string(report.Trigger),
+		Status:       string(report.Status),
+		Scanned:      report.Scanned,
+		Truncated:    report.Truncated,
+		Counts:       string(counts),
+		Fixable:      report.Fixable,
+		Error:        report.Error,
+		CreatedBy:    report.CreatedBy,
+		CreatedTime:  report.CreatedAt,
+		UpdatedTime:  report.UpdatedAt,
+		FinishedTime: report.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save health report: %w", err)
+	}
+	return nil
+}
+
+// GetReport 获取检查报告
+func (r *TableHealthRepositoryImpl) GetReport(ctx context.Context, id string) (*tablehealth.Report, error) {
+	var model models.TableHealthReport
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health report: %w", err)
+	}
+	return fromTableHealthReportModel(&model), nil
+}
+
+// ListReports 按创建时间倒序列出检查报告
+func (r *TableHealthRepositoryImpl) ListReports(ctx context.Context, tableID string, limit int) ([]*tablehealth.Report, error) {
+	var list []models.TableHealthReport
+	if err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list health reports: %w", err)
+	}
+	reports := make([]*tablehealth.Report, 0, len(list))
+	for i := range list {
+		reports = append(reports, fromTableHealthReportModel(&list[i]))
+	}
+	return reports, nil
+}
+
+// ActiveReport 表中未结束的检查
+func (r *TableHealthRepositoryImpl) ActiveReport(ctx context.Context, tableID string) (*tablehealth.Report, error) {
+	var model models.TableHealthReport
+	err := r.db.WithContext(ctx).
+		Where("table_id = ? AND status IN ?", tableID, []string{string(tablehealth.StatusPending), string(tablehealth.StatusRunning)}).
+		Order("created_time ASC").
+		Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active health report: %w", err)
+	}
+	return fromTableHealthReportModel(&model), nil
+}
+
+// ClaimReports 领取待执行或已中断的检查（PostgreSQL 使用 SKIP LOCKED）
+func (r *TableHealthRepositoryImpl) ClaimReports(ctx context.Context, now, staleBefore time.Time, limit int) ([]*tablehealth.Report, error) {
+	var claimed []*tablehealth.Report
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.TableHealthReport{}).
+			Where("status = ? OR (status = ? AND updated_time < ?)",
+				string(tablehealth.StatusPending), string(tablehealth.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.TableHealthReport
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.TableHealthReport{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(tablehealth.StatusRunning),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(tablehealth.StatusRunning)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromTableHealthReportModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim health reports: %w", err)
+	}
+	return claimed, nil
+}
+
+// PurgeReports 只保留最近 keep 份报告，连同其问题与修复任务
+func (r *TableHealthRepositoryImpl) PurgeReports(ctx context.Context, tableID string, keep int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keepIDs := tx.Model(&models.TableHealthReport{}).
+			Select("id").
+			Where("table_id = ?", tableID).
+			Order("created_time DESC").
+			Limit(keep)
+		var stale []string
+		if err := tx.Model(&models.TableHealthReport{}).
+			Where("table_id = ? AND id NOT IN (?)", tableID, keepIDs).
+			Pluck("id", &stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
+		if err := tx.Where("report_id IN ?", stale).Delete(&models.TableHealthIssue{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("report_id IN ?", stale).Delete(&models.TableHealthFixJob{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", stale).Delete(&models.TableHealthReport{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge health reports: %w", err)
+	}
+	return nil
+}
+
+// SaveIssues 批量写入问题
+func (r *TableHealthRepositoryImpl) SaveIssues(ctx context.Context, issues []*tablehealth.Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	list := make([]models.TableHealthIssue, 0, len(issues))
+	for _, issue := range issues {
+		model, err := toTableHealthIssueModel(issue)
+		if err != nil {
+			return err
+		}
+		list = append(list, model)
+	}
+	if err := r.db.WithContext(ctx).CreateInBatches(&list, tableHealthIssueBatchSize).Error; err != nil {
+		return fmt.Errorf("failed to save health issues: %w", err)
+	}
+	return nil
+}
+
+// DeleteIssues 删除报告的全部问题
+func (r *TableHealthRepositoryImpl) DeleteIssues(ctx context.Context, reportID string) error {
+	if err := r.db.WithContext(ctx).Where("report_id = ?", reportID).Delete(&models.TableHealthIssue{}).Error; err != nil {
+		return fmt.Errorf("failed to delete health issues: %w", err)
+	}
+	return nil
+}
+
+// ListIssues 按条件分页列出问题（字段级问题在前，其余按记录排列）
+func (r *TableHealthRepositoryImpl) ListIssues(ctx context.Context, filter tablehealth.IssueFilter) ([]*tablehealth.Issue, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.TableHealthIssue{}).Where("report_id = ?", filter.ReportID)
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", string(filter.Kind))
+	}
+	if filter.FieldID != "" {
+		query = query.Where("field_id = ?", filter.FieldID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count health issues: %w", err)
+	}
+	var list []models.TableHealthIssue
+	if err := query.
+		Order("record_id ASC, field_id ASC, kind ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&list).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list health issues: %w", err)
+	}
+	return fromTableHealthIssueModels(list), total, nil
+}
+
+// GetIssues 获取报告中指定的问题
+func (r *TableHealthRepositoryImpl) GetIssues(ctx context.Context, reportID string, ids []string) ([]*tablehealth.Issue, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var list []models.TableHealthIssue
+	if err := r.db.WithContext(ctx).
+		Where("report_id = ? AND id IN ?", reportID, ids).
+		Order("record_id ASC, field_id ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to get health issues: %w", err)
+	}
+	return fromTableHealthIssueModels(list), nil
+}
+
+// OpenFixableIssueIDs 报告中待修复的问题ID
+func (r *TableHealthRepositoryImpl) OpenFixableIssueIDs(ctx context.Context, reportID string, kinds []tablehealth.IssueKind, limit int) ([]string, error) {
+	query := r.db.WithContext(ctx).Model(&models.TableHealthIssue{}).
+		Where("report_id = ? AND fixable = ? AND status = ?", reportID, true, string(tablehealth.IssueOpen))
+	if len(kinds) > 0 {
+		values := make([]string, 0, len(kinds))
+		for _, kind := range kinds {
+			values = append(values, string(kind))
+		}
+		query = query.Where("kind IN ?", values)
+	}
+	var ids []string
+	if err := query.Order("record_id ASC, field_id ASC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list fixable health issues: %w", err)
+	}
+	return ids, nil
+}
+
+// UpdateIssue 保存问题的处理状态
+func (r *TableHealthRepositoryImpl) UpdateIssue(ctx context.Context, issue *tablehealth.Issue) error {
+	if err := r.db.WithContext(ctx).Model(&models.TableHealthIssue{}).
+		Where("id = ?", issue.ID).
+		Updates(map[string]interface{}{
+			"status":    string(issue.Status),
+			"fix_error": issue.FixError,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update health issue: %w", err)
+	}
+	return nil
+}
+
+// SaveFixJob 创建或更新修复任务
+func (r *TableHealthRepositoryImpl) SaveFixJob(ctx context.Context, job *tablehealth.FixJob) error {
+	issueIDs, err := json.Marshal(job.IssueIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal fix job issues: %w", err)
+	}
+	model := models.TableHealthFixJob{
+		ID:           job.ID,
+		ReportID:     job.ReportID,
+		TableID:      job.TableID,
+		IssueIDs:     string(issueIDs),
+		Status:       string(job.Status),
+		Fixed:        job.Fixed,
+		Skipped:      job.Skipped,
+		Failed:       job.Failed,
+		Error:        job.Error,
+		CreatedBy:    job.CreatedBy,
+		CreatedTime:  job.CreatedAt,
+		UpdatedTime:  job.UpdatedAt,
+		FinishedTime: job.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save fix job: %w", err)
+	}
+	return nil
+}
+
+// GetFixJob 获取修复任务
+func (r *TableHealthRepositoryImpl) GetFixJob(ctx context.Context, id string) (*tablehealth.FixJob, error) {
+	var model models.TableHealthFixJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fix job: %w", err)
+	}
+	return fromTableHealthFixJobModel(&model), nil
+}
+
+// ActiveFixJob 报告中未结束的修复任务
+func (r *TableHealthRepositoryImpl) ActiveFixJob(ctx context.Context, reportID string) (*tablehealth.FixJob, error) {
+	var model models.TableHealthFixJob
+	err := r.db.WithContext(ctx).
+		Where("report_id = ? AND status IN ?", reportID, []string{string(tablehealth.StatusPending), string(tablehealth.StatusRunning)}).
+		Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active fix job: %w", err)
+	}
+	return fromTableHealthFixJobModel(&model), nil
+}
+
+// ClaimFixJobs 领取待执行或已中断的修复任务（PostgreSQL 使用 SKIP LOCKED）
+func (r *TableHealthRepositoryImpl) ClaimFixJobs(ctx context.Context, now, staleBefore time.Time, limit int) ([]*tablehealth.FixJob, error) {
+	var claimed []*tablehealth.FixJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.TableHealthFixJob{}).
+			Where("status = ? OR (status = ? AND updated_time < ?)",
+				string(tablehealth.StatusPending), string(tablehealth.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.TableHealthFixJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.TableHealthFixJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(tablehealth.StatusRunning),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(tablehealth.StatusRunning)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromTableHealthFixJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim fix jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+// GetSchedule 获取表的定时检查设置
+func (r *TableHealthRepositoryImpl) GetSchedule(ctx context.Context, tableID string) (*tablehealth.Schedule, error) {
+	var model models.TableHealthSchedule
+	err := r.db.WithContext(ctx).Where("table_id = ?", tableID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health schedule: %w", err)
+	}
+	return fromTableHealthScheduleModel(&model), nil
+}
+
+// SaveSchedule 创建或更新定时检查设置
+func (r *TableHealthRepositoryImpl) SaveSchedule(ctx context.Context, schedule *tablehealth.Schedule) error {
+	model := models.TableHealthSchedule{
+		TableID:       schedule.TableID,
+		IntervalHours: schedule.IntervalHours,
+		Enabled:       schedule.Enabled,
+		NextRunTime:   schedule.NextRunAt,
+		UpdatedBy:     schedule.UpdatedBy,
+		UpdatedTime:   schedule.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save health schedule: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueSchedules 领取到期的定时检查并推后下次检查时间（PostgreSQL 使用 SKIP LOCKED）
+func (r *TableHealthRepositoryImpl) ClaimDueSchedules(ctx context.Context, now time.Time, limit int) ([]*tablehealth.Schedule, error) {
+	var claimed []*tablehealth.Schedule
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.TableHealthSchedule{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.TableHealthSchedule
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			schedule := fromTableHealthScheduleModel(&list[i])
+			schedule.Advance(now)
+			if err := tx.Model(&models.TableHealthSchedule{}).
+				Where("table_id = ?", schedule.TableID).
+				Update("next_run_time", schedule.NextRunAt).Error; err != nil {
+				return err
+			}
+			claimed = append(claimed, schedule)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim health schedules: %w", err)
+	}
+	return claimed, nil
+}
+
+func fromTableHealthReportModel(model *models.TableHealthReport) *tablehealth.Report {
+	counts := map[tablehealth.IssueKind]int{}
+	if model.Counts != "" {
+		_ = json.Unmarshal([]byte(model.Counts), &counts)
+	}
+	return &tablehealth.Report{
+		ID:         model.ID,
+		TableID:    model.TableID,
+		Trigger:    tablehealth.Trigger(model.Trigger),
+		Status:     tablehealth.Status(model.Status),
+		Scanned:    model.Scanned,
+		Truncated:  model.Truncated,
+		Counts:     counts,
+		Fixable:    model.Fixable,
+		Error:      model.Error,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+}
+
+func toTableHealthIssueModel(issue *tablehealth.Issue) (models.TableHealthIssue, error) {
+	var value string
+	if issue.Value != nil {
+		data, err := json.Marshal(issue.Value)
+		if err != nil {
+			return models.TableHealthIssue{}, fmt.Errorf("failed to marshal health issue value: %w", err)
+		}
+		value = string(data)
+	}
+	return models.TableHealthIssue{
+		ID:        issue.ID,
+		ReportID:  issue.ReportID,
+		TableID:   issue.TableID,
+		Kind:      string(issue.Kind),
+		FieldID:   issue.FieldID,
+		FieldName: issue.FieldName,
+		RecordID:  issue.RecordID,
+		Value:     value,
+		Message:   issue.Message,
+		Fixable:   issue.Fixable,
+		Status:    string(issue.Status),
+		FixError:  issue.FixError,
+	}, nil
+}
+
+func fromTableHealthIssueModels(list []models.TableHealthIssue) []*tablehealth.Issue {
+	issues := make([]*tablehealth.Issue, 0, len(list))
+	for i := range list {
+		model := &list[i]
+		var value interface{}
+		if model.Value != "" {
+			_ = json.Unmarshal([]byte(model.Value), &value)
+		}
+		issues = append(issues, &tablehealth.Issue{
+			ID:        model.ID,
+			ReportID:  model.ReportID,
+			TableID:   model.TableID,
+			Kind:      tablehealth.IssueKind(model.Kind),
+			FieldID:   model.FieldID,
+			FieldName: model.FieldName,
+			RecordID:  model.RecordID,
+			Value:     value,
+			Message:   model.Message,
+			Fixable:   model.Fixable,
+			Status:    tablehealth.IssueStatus(model.Status),
+			FixError:  model.FixError,
+		})
+	}
+	return issues
+}
+
+func fromTableHealthFixJobModel(model *models.TableHealthFixJob) *tablehealth.FixJob {
+	var issueIDs []string
+	if model.IssueIDs != "" {
+		_ = json.Unmarshal([]byte(model.IssueIDs), &issueIDs)
+	}
+	return &tablehealth.FixJob{
+		ID:         model.ID,
+		ReportID:   model.ReportID,
+		TableID:    model.TableID,
+		IssueIDs:   issueIDs,
+		Status:     tablehealth.Status(model.Status),
+		Fixed:      model.Fixed,
+		Skipped:    model.Skipped,
+		Failed:     model.Failed,
+		Error:      model.Error,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+}
+
+func fromTableHealthScheduleModel(model *models.TableHealthSchedule) *tablehealth.Schedule {
+	return &tablehealth.Schedule{
+		TableID:       model.TableID,
+		IntervalHours: model.IntervalHours,
+		Enabled:       model.Enabled,
+		NextRunAt:     model.NextRunTime,
+		UpdatedBy:     model.UpdatedBy,
+		UpdatedAt:     model.UpdatedTime,
+	}
+}
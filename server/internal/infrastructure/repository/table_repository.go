@@ -12,6 +12,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository/mapper"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
 )
 
 // TableRepositoryImpl 表格仓储实现
@@ -101,7 +102,7 @@ func (r *TableRepositoryImpl) ListByBaseID(ctx context.Context, baseID string) (
 
 // Delete 删除表格
 func (r *TableRepositoryImpl) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).
+	return database.WithTx(ctx, r.db).WithContext(ctx).
 		Model(&models.Table{}).
 		Where("id = ?", id).
 		Update("deleted_time", gorm.Expr("NOW()")).Error
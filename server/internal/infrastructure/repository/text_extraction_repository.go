@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/textextract"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// TextExtractionRepositoryImpl 附件文本提取规则与任务GORM实现
+type TextExtractionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTextExtractionRepository 创建附件文本提取仓储
+func NewTextExtractionRepository(db *gorm.DB) textextract.Repository {
+	return &TextExtractionRepositoryImpl{db: db}
+}
+
+// ListRules 列出表的提取规则
+func (r *TextExtractionRepositoryImpl) ListRules(ctx context.Context, tableID string) ([]*textextract.Rule, error) {
+	var list []models.TextExtractionRule
+	if err := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list text extraction rules: %w", err)
+	}
+	rules := make([]*textextract.Rule, 0, len(list))
+	for i := range list {
+		rules = append(rules, fromTextExtractionRuleModel(&list[i]))
+	}
+	return rules, nil
+}
+
+// GetRule 获取提取规则
+func (r *TextExtractionRepositoryImpl) GetRule(ctx context.Context, id string) (*textextract.Rule, error) {
+	var model models.TextExtractionRule
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get text extraction rule: %w", err)
+	}
+	return fromTextExtractionRuleModel(&model), nil
+}
+
+// SaveRule 保存提取规则
+func (r *TextExtractionRepositoryImpl) SaveRule(ctx context.Context, rule *textextract.Rule) error {
+	languages, err := json.Marshal(rule.Languages)
+	if err != nil {
+		return err
+	}
+	model := models.TextExtractionRule{
+		ID:            rule.ID,
+		TableID:       rule.TableID,
+		SourceFieldID: rule.SourceFieldID,
+		TargetFieldID: rule.TargetFieldID,
+		Languages:     string(languages),
+		CreatedBy:     rule.CreatedBy,
+		CreatedTime:   rule.CreatedAt,
+		UpdatedTime:   rule.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save text extraction rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule 删除提取规则及其任务
+func (r *TextExtractionRepositoryImpl) DeleteRule(ctx context.Context, rule *textextract.Rule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", rule.ID).Delete(&models.TextExtractionJob{}).Error; err != nil {
+			return fmt.Errorf("failed to delete text extraction jobs: %w", err)
+		}
+		if err := tx.Where("id = ?", rule.ID).Delete(&models.TextExtractionRule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete text extraction rule: %w", err)
+		}
+		return nil
+	})
+}
+
+// Enqueue 写入待执行任务，同一规则与记录已有待执行任务时跳过
+// 使用上下文中的事务连接：记录写入回滚时任务一并丢弃
+func (r *TextExtractionRepositoryImpl) Enqueue(ctx context.Context, jobs ...*textextract.Job) (int, error) {
+	db := pkgDatabase.WithTx(ctx, r.db).WithContext(ctx)
+	created := 0
+	for _, job := range jobs {
+		var pending int64
+		if err := db.Model(&models.TextExtractionJob{}).
+			Where("rule_id = ? AND record_id = ? AND status = ?", job.RuleID, job.RecordID, string(textextract.StatusPending)).
+			Count(&pending).Error; err != nil {
+			return created, fmt.Errorf("failed to check pending text extraction job: %w", err)
+		}
+		if pending > 0 {
+			continue
+		}
+		model := toTextExtractionJobModel(job)
+		if err := db.Create(&model).Error; err != nil {
+			return created, fmt.Errorf("failed to enqueue text extraction job: %w", err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// Claim 领取一批可执行任务（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *TextExtractionRepositoryImpl) Claim(ctx context.Context, now, staleBefore time.Time, limit int) ([]*textextract.Job, error) {
+	var claimed []*textextract.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.TextExtractionJob{}).
+			Where("(status = ? AND next_run_at <= ?) OR (status = ? AND updated_time < ?)",
+				string(textextract.StatusPending), now,
+				string(textextract.StatusRunning), staleBefore).
+			Where(`NOT EXISTS (SELECT 1 FROM text_extraction_job other
+				WHERE other.rule_id = text_extraction_job.rule_id AND other.record_id = text_extraction_job.record_id
+				AND other.id <> text_extraction_job.id AND other.status = ? AND other.updated_time >= ?)`,
+				string(textextract.StatusRunning), staleBefore).
+			Order("created_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.TextExtractionJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.TextExtractionJob{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       string(textextract.StatusRunning),
+				"attempts":     gorm.Expr("attempts + 1"),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Status = string(textextract.StatusRunning)
+			list[i].Attempts++
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromTextExtractionJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim text extraction jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+// Save 保存任务状态
+func (r *TextExtractionRepositoryImpl) Save(ctx context.Context, job *textextract.Job) error {
+	model := toTextExtractionJobModel(job)
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save text extraction job: %w", err)
+	}
+	return nil
+}
+
+// ListByRule 列出规则最近的任务
+func (r *TextExtractionRepositoryImpl) ListByRule(ctx context.Context, ruleID, recordID string, limit int) ([]*textextract.Job, error) {
+	query := r.db.WithContext(ctx).Where("rule_id = ?", ruleID)
+	if recordID != "" {
+		query = query.Where("record_id = ?", recordID)
+	}
+	var list []models.TextExtractionJob
+	if err := query.Order("created_time DESC").Limit(limit).Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list text extraction jobs: %w", err)
+	}
+	jobs := make([]*textextract.Job, 0, len(list))
+	for i := range list {
+		jobs = append(jobs, fromTextExtractionJobModel(&list[i]))
+	}
+	return jobs, nil
+}
+
+// DeleteFinishedBefore 删除过期的已结束任务
+func (r *TextExtractionRepositoryImpl) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status IN ? AND finished_time < ?", []string{string(textextract.StatusSucceeded), string(textextract.StatusFailed)}, before).
+		Delete(&models.TextExtractionJob{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete text extraction jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func fromTextExtractionRuleModel(model *models.TextExtractionRule) *textextract.Rule {
+	rule := &textextract.Rule{
+		ID:            model.ID,
+		TableID:       model.TableID,
+		SourceFieldID: model.SourceFieldID,
+		TargetFieldID: model.TargetFieldID,
+		CreatedBy:     model.CreatedBy,
+		CreatedAt:     model.CreatedTime,
+		UpdatedAt:     model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Languages), &rule.Languages)
+	if rule.Languages == nil {
+		rule.Languages = []string{}
+	}
+	return rule
+}
+
+func toTextExtractionJobModel(j *textextract.Job) models.TextExtractionJob {
+	model := models.TextExtractionJob{
+		ID:           j.ID,
+		RuleID:       j.RuleID,
+		TableID:      j.TableID,
+		RecordID:     j.RecordID,
+		Trigger:      string(j.Trigger),
+		Status:       string(j.Status),
+		Attempts:     j.Attempts,
+		Engine:       j.Engine,
+		Files:        j.Files,
+		Skipped:      j.Skipped,
+		Chars:        j.Chars,
+		CreatedBy:    j.CreatedBy,
+		NextRunAt:    j.NextRunAt,
+		CreatedTime:  j.CreatedAt,
+		UpdatedTime:  j.UpdatedAt,
+		FinishedTime: j.FinishedAt,
+	}
+	if j.Error != "" {
+		model.Error = &j.Error
+	}
+	return model
+}
+
+func fromTextExtractionJobModel(model *models.TextExtractionJob) *textextract.Job {
+	j := &textextract.Job{
+		ID:         model.ID,
+		RuleID:     model.RuleID,
+		TableID:    model.TableID,
+		RecordID:   model.RecordID,
+		Trigger:    textextract.Trigger(model.Trigger),
+		Status:     textextract.Status(model.Status),
+		Attempts:   model.Attempts,
+		Engine:     model.Engine,
+		Files:      model.Files,
+		Skipped:    model.Skipped,
+		Chars:      model.Chars,
+		CreatedBy:  model.CreatedBy,
+		NextRunAt:  model.NextRunAt,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+	if model.Error != nil {
+		j.Error = *model.Error
+	}
+	return j
+}
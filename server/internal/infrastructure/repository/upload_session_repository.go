@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// UploadSessionRepositoryImpl 可续传上传仓储GORM实现
+type UploadSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository 创建可续传上传仓储
+func NewUploadSessionRepository(db *gorm.DB) attachment.UploadSessionRepository {
+	return &UploadSessionRepositoryImpl{db: db}
+}
+
+// CreateSession 创建上传
+func (r *UploadSessionRepositoryImpl) CreateSession(ctx context.Context, session *attachment.UploadSession) error {
+	model := &models.AttachmentUploadSession{
+		ID:          session.ID,
+		Token:       session.Token,
+		UserID:      session.UserID,
+		Filename:    session.Filename,
+		Length:      session.Length,
+		Offset:      session.Offset,
+		Status:      string(session.Status),
+		ExpiresAt:   session.ExpiresAt,
+		CreatedTime: session.CreatedTime,
+		UpdatedTime: session.UpdatedTime,
+	}
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return nil
+}
+
+// GetSession 获取上传
+func (r *UploadSessionRepositoryImpl) GetSession(ctx context.Context, id string) (*attachment.UploadSession, error) {
+	var model models.AttachmentUploadSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+	return toDomainUploadSession(&model), nil
+}
+
+// AcquireLock 按行占用上传（占用过期后其他实例可以接管）
+func (r *UploadSessionRepositoryImpl) AcquireLock(ctx context.Context, id, token string, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AttachmentUploadSession{}).
+		Where("id = ? AND (lock_token = ? OR locked_until IS NULL OR locked_until < ?)", id, token, time.Now()).
+		UpdateColumns(map[string]interface{}{
+			"lock_token":   token,
+			"locked_until": until,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to lock upload session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseLock 释放占用（占用已被接管时不影响新的持有者）
+func (r *UploadSessionRepositoryImpl) ReleaseLock(ctx context.Context, id, token string) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.AttachmentUploadSession{}).
+		Where("id = ? AND lock_token = ?", id, token).
+		UpdateColumns(map[string]interface{}{
+			"lock_token":   "",
+			"locked_until": nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to unlock upload session: %w", err)
+	}
+	return nil
+}
+
+// AdvanceOffset 按占用与当前偏移条件更新并追加分片，防止并发分片覆盖
+func (r *UploadSessionRepositoryImpl) AdvanceOffset(ctx context.Context, id, token string, from, to int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AttachmentUploadSession{}).
+		Where("id = ? AND lock_token = ? AND upload_offset = ?", id, token, from).
+		UpdateColumns(map[string]interface{}{
+			"upload_offset": to,
+			"parts":         gorm.Expr("parts || ?", strconv.FormatInt(to, 10)+","),
+			"updated_time":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to advance upload offset: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkCompleted 标记已合并
+func (r *UploadSessionRepositoryImpl) MarkCompleted(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).
+		Model(&models.AttachmentUploadSession{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"status":       string(attachment.UploadSessionCompleted),
+			"updated_time": time.Now(),
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to complete upload session: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession 删除上传
+func (r *UploadSessionRepositoryImpl) DeleteSession(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Delete(&models.AttachmentUploadSession{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete upload session: %w", err)
+	}
+	return nil
+}
+
+// ListExpired 列出过期的上传
+func (r *UploadSessionRepositoryImpl) ListExpired(ctx context.Context, before time.Time, limit int) ([]*attachment.UploadSession, error) {
+	var list []models.AttachmentUploadSession
+	err := r.db.WithContext(ctx).
+		Where("expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired upload sessions: %w", err)
+	}
+	sessions := make([]*attachment.UploadSession, 0, len(list))
+	for i := range list {
+		sessions = append(sessions, toDomainUploadSession(&list[i]))
+	}
+	return sessions, nil
+}
+
+func toDomainUploadSession(model *models.AttachmentUploadSession) *attachment.UploadSession {
+	var parts []int64
+	for _, part := range strings.Split(model.Parts, ",") {
+		if to, err := strconv.ParseInt(part, 10, 64); err == nil {
+			parts = append(parts, to)
+		}
+	}
+	return &attachment.UploadSession{
+		ID:          model.ID,
+		Token:       model.Token,
+		UserID:      model.UserID,
+		Filename:    model.Filename,
+		Length:      model.Length,
+		Offset:      model.Offset,
+		Status:      attachment.UploadSessionStatus(model.Status),
+		Parts:       parts,
+		ExpiresAt:   model.ExpiresAt,
+		CreatedTime: model.CreatedTime,
+		UpdatedTime: model.UpdatedTime,
+	}
+}
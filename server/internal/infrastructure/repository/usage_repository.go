@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/usage"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// usageConflictColumns 汇总行的唯一键
+var usageConflictColumns = []clause.Column{
+	{Name: "space_id"}, {Name: "day"}, {Name: "base_id"}, {Name: "metric"}, {Name: "dimension"},
+}
+
+// UsageRepositoryImpl 用量汇总GORM实现
+type UsageRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUsageRepository 创建用量汇总仓储
+func NewUsageRepository(db *gorm.DB) usage.Repository {
+	return &UsageRepositoryImpl{db: db}
+}
+
+// Upsert 写入汇总值（已存在时覆盖）
+func (r *UsageRepositoryImpl) Upsert(ctx context.Context, rows []*usage.DailyUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   usageConflictColumns,
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_time"}),
+	}).CreateInBatches(toUsageModels(rows), 500).Error; err != nil {
+		return fmt.Errorf("failed to upsert usage: %w", err)
+	}
+	return nil
+}
+
+// Increment 累加汇总值
+func (r *UsageRepositoryImpl) Increment(ctx context.Context, rows []*usage.DailyUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: usageConflictColumns,
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":        gorm.Expr("usage_daily.value + EXCLUDED.value"),
+			"updated_time": gorm.Expr("EXCLUDED.updated_time"),
+		}),
+	}).CreateInBatches(toUsageModels(rows), 500).Error; err != nil {
+		return fmt.Errorf("failed to increment usage: %w", err)
+	}
+	return nil
+}
+
+// ListBySpace 列出空间在日期区间内的汇总行
+func (r *UsageRepositoryImpl) ListBySpace(ctx context.Context, spaceID string, from, to time.Time) ([]*usage.DailyUsage, error) {
+	var list []models.UsageDaily
+	if err := r.db.WithContext(ctx).
+		Where("space_id = ? AND day >= ? AND day <= ?", spaceID, from, to).
+		Order("day ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	rows := make([]*usage.DailyUsage, 0, len(list))
+	for _, model := range list {
+		rows = append(rows, &usage.DailyUsage{
+			SpaceID:   model.SpaceID,
+			BaseID:    model.BaseID,
+			Day:       model.Day,
+			Metric:    usage.Metric(model.Metric),
+			Dimension: model.Dimension,
+			Value:     model.Value,
+		})
+	}
+	return rows, nil
+}
+
+// PurgeBefore 删除早于 day 的汇总行
+func (r *UsageRepositoryImpl) PurgeBefore(ctx context.Context, day time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("day < ?", day).Delete(&models.UsageDaily{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge usage: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// usageCount 统计查询结果
+type usageCount struct {
+	SpaceID string
+	BaseID  string
+	Value   int64
+}
+
+// CountActivity 统计区间内的新建记录、后台任务运行与活跃协作者
+// 新建记录与活跃协作者来自变更流发件箱，后台任务包括长时操作与数据仓库同步运行
+func (r *UsageRepositoryImpl) CountActivity(ctx context.Context, from, to time.Time) ([]*usage.DailyUsage, error) {
+	queries := []struct {
+		metric usage.Metric
+		sql    string
+	}{
+		{usage.MetricRecordsCreated, `
+			SELECT b.space_id, e.base_id, COUNT(*) AS value
+			FROM change_event e JOIN base b ON b.id = e.base_id
+			WHERE e.entity_type = 'record' AND e.action = 'create'
+				AND e.created_time >= @from AND e.created_time < @to
+			GROUP BY b.space_id, e.base_id`},
+		{usage.MetricActiveCollaborators, `
+			SELECT b.space_id, e.base_id, COUNT(DISTINCT e.user_id) AS value
+			FROM change_event e JOIN base b ON b.id = e.base_id
+			WHERE e.user_id <> '' AND e.created_time >= @from AND e.created_time < @to
+			GROUP BY b.space_id, e.base_id`},
+		{usage.MetricActiveCollaborators, `
+			SELECT b.space_id, '' AS base_id, COUNT(DISTINCT e.user_id) AS value
+			FROM change_event e JOIN base b ON b.id = e.base_id
+			WHERE e.user_id <> '' AND e.created_time >= @from AND e.created_time < @to
+			GROUP BY b.space_id`},
+		{usage.MetricAutomationRuns, `
+			SELECT b.space_id, runs.base_id, COUNT(*) AS value
+			FROM (
+				SELECT o.base_id FROM operation o
+				WHERE o.created_time >= @from AND o.created_time < @to
+				UNION ALL
+				SELECT j.base_id FROM warehouse_sync_run w JOIN warehouse_sync_job j ON j.id = w.job_id
+				WHERE w.started_time >= @from AND w.started_time < @to
+			) runs JOIN base b ON b.id = runs.base_id
+			GROUP BY b.space_id, runs.base_id`},
+	}
+
+	var rows []*usage.DailyUsage
+	for _, q := range queries {
+		var counts []usageCount
+		if err := r.db.WithContext(ctx).Raw(q.sql, map[string]interface{}{"from": from, "to": to}).
+			Scan(&counts).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", q.metric, err)
+		}
+		for _, count := range counts {
+			rows = append(rows, &usage.DailyUsage{
+				SpaceID: count.SpaceID,
+				BaseID:  count.BaseID,
+				Day:     from,
+				Metric:  q.metric,
+				Value:   count.Value,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// MeasureStorage 统计各 Base 当前的附件占用空间（不含已删除的附件、表与 Base）
+func (r *UsageRepositoryImpl) MeasureStorage(ctx context.Context) ([]*usage.DailyUsage, error) {
+	var counts []usageCount
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT b.space_id, t.base_id, COALESCE(SUM(a.size), 0) AS value
+		FROM attachments a
+		JOIN table_meta t ON t.id = a.table_id
+		JOIN base b ON b.id = t.base_id
+		WHERE a.deleted_time IS NULL AND t.deleted_time IS NULL AND b.deleted_time IS NULL
+		GROUP BY b.space_id, t.base_id`).
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to measure storage: %w", err)
+	}
+	rows := make([]*usage.DailyUsage, 0, len(counts))
+	for _, count := range counts {
+		rows = append(rows, &usage.DailyUsage{
+			SpaceID: count.SpaceID,
+			BaseID:  count.BaseID,
+			Metric:  usage.MetricStorageBytes,
+			Value:   count.Value,
+		})
+	}
+	return rows, nil
+}
+
+func toUsageModels(rows []*usage.DailyUsage) []models.UsageDaily {
+	now := time.Now()
+	list := make([]models.UsageDaily, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, models.UsageDaily{
+			SpaceID:     row.SpaceID,
+			BaseID:      row.BaseID,
+			Day:         usage.Day(row.Day),
+			Metric:      string(row.Metric),
+			Dimension:   row.Dimension,
+			Value:       row.Value,
+			UpdatedTime: now,
+		})
+	}
+	return list
+}
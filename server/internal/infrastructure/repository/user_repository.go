@@ -198,12 +198,19 @@ func (r *UserRepositoryImpl) List(ctx context.Context, filter repository.UserFil
 		Where("deleted_time IS NULL")
 
 	// 应用过滤条件
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
 	if filter.Email != nil {
 		query = query.Where("email LIKE ?", "%"+*filter.Email+"%")
 	}
 	if filter.Name != nil {
 		query = query.Where("name LIKE ?", "%"+*filter.Name+"%")
 	}
+	if filter.Search != nil {
+		pattern := "%" + *filter.Search + "%"
+		query = query.Where("(name ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
 	if filter.IsAdmin != nil {
 		query = query.Where("is_admin = ?", *filter.IsAdmin)
 	}
@@ -267,12 +274,19 @@ func (r *UserRepositoryImpl) Count(ctx context.Context, filter repository.UserFi
 		Where("deleted_time IS NULL")
 
 	// 应用过滤条件
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
 	if filter.Email != nil {
 		query = query.Where("email LIKE ?", "%"+*filter.Email+"%")
 	}
 	if filter.Name != nil {
 		query = query.Where("name LIKE ?", "%"+*filter.Name+"%")
 	}
+	if filter.Search != nil {
+		pattern := "%" + *filter.Search + "%"
+		query = query.Where("(name ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
 	if filter.IsAdmin != nil {
 		query = query.Where("is_admin = ?", *filter.IsAdmin)
 	}
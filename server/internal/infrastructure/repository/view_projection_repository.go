@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/viewprojection"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// ViewProjectionRepositoryImpl 视图展示投影GORM实现
+type ViewProjectionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewViewProjectionRepository 创建视图展示投影仓储
+func NewViewProjectionRepository(db *gorm.DB) viewprojection.Repository {
+	return &ViewProjectionRepositoryImpl{db: db}
+}
+
+// Find 获取视图的投影
+func (r *ViewProjectionRepositoryImpl) Find(ctx context.Context, viewID string) (*viewprojection.Projection, error) {
+	var model models.ViewProjection
+	err := r.db.WithContext(ctx).Where("view_id = ?", viewID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view projection: %w", err)
+	}
+	return fromViewProjectionModel(&model), nil
+}
+
+// Save 创建或更新投影
+func (r *ViewProjectionRepositoryImpl) Save(ctx context.Context, p *viewprojection.Projection) error {
+	recordIDs, err := json.Marshal(p.RecordIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal view projection order: %w", err)
+	}
+	model := models.ViewProjection{
+		ViewID:      p.ViewID,
+		BaseID:      p.BaseID,
+		TableID:     p.TableID,
+		RecordIDs:   string(recordIDs),
+		Truncated:   p.Truncated,
+		Cursor:      p.Cursor,
+		BuiltTime:   p.BuiltAt,
+		ReadTime:    p.ReadAt,
+		UpdatedTime: p.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "view_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"base_id", "table_id", "record_ids", "truncated", "cursor", "built_time", "read_time", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save view projection: %w", err)
+	}
+	return nil
+}
+
+// Touch 更新最近读取时间
+func (r *ViewProjectionRepositoryImpl) Touch(ctx context.Context, viewID string, readAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.ViewProjection{}).
+		Where("view_id = ?", viewID).
+		Update("read_time", readAt).Error; err != nil {
+		return fmt.Errorf("failed to touch view projection: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除投影及其全部行
+func (r *ViewProjectionRepositoryImpl) Delete(ctx context.Context, viewID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteViewProjections(tx, []string{viewID})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete view projection: %w", err)
+	}
+	return nil
+}
+
+// ListReadSince 列出 since 之后读取过的投影
+func (r *ViewProjectionRepositoryImpl) ListReadSince(ctx context.Context, since time.Time, limit int) ([]*viewprojection.Projection, error) {
+	var list []models.ViewProjection
+	if err := r.db.WithContext(ctx).
+		Where("read_time >= ?", since).
+		Order("read_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list view projections: %w", err)
+	}
+	projections := make([]*viewprojection.Projection, 0, len(list))
+	for i := range list {
+		projections = append(projections, fromViewProjectionModel(&list[i]))
+	}
+	return projections, nil
+}
+
+// DeleteReadBefore 删除长期未读取的投影
+func (r *ViewProjectionRepositoryImpl) DeleteReadBefore(ctx context.Context, before time.Time) (int64, error) {
+	var viewIDs []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ViewProjection{}).
+			Where("read_time < ?", before).
+			Pluck("view_id", &viewIDs).Error; err != nil {
+			return err
+		}
+		if len(viewIDs) == 0 {
+			return nil
+		}
+		return deleteViewProjections(tx, viewIDs)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete idle view projections: %w", err)
+	}
+	return int64(len(viewIDs)), nil
+}
+
+func deleteViewProjections(tx *gorm.DB, viewIDs []string) error {
+	if err := tx.Where("view_id IN ?", viewIDs).Delete(&models.ViewProjectionRef{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("view_id IN ?", viewIDs).Delete(&models.ViewProjectionRow{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("view_id IN ?", viewIDs).Delete(&models.ViewProjection{}).Error
+}
+
+// FindRows 获取投影中的行
+func (r *ViewProjectionRepositoryImpl) FindRows(ctx context.Context, viewID string, recordIDs []string) ([]*viewprojection.Row, error) {
+	if len(recordIDs) == 0 {
+		return nil, nil
+	}
+	var list []models.ViewProjectionRow
+	if err := r.db.WithContext(ctx).
+		Where("view_id = ? AND record_id IN ?", viewID, recordIDs).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to find view projection rows: %w", err)
+	}
+	rows := make([]*viewprojection.Row, 0, len(list))
+	for i := range list {
+		row := &viewprojection.Row{
+			ViewID:    list[i].ViewID,
+			RecordID:  list[i].RecordID,
+			Cells:     map[string]*viewprojection.Cell{},
+			UpdatedAt: list[i].UpdatedTime,
+		}
+		if list[i].Cells != "" {
+			_ = json.Unmarshal([]byte(list[i].Cells), &row.Cells)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// SaveRows 创建或更新行，并替换这些行的关联引用
+func (r *ViewProjectionRepositoryImpl) SaveRows(ctx context.Context, rows []*viewprojection.Row) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	list := make([]models.ViewProjectionRow, 0, len(rows))
+	var refs []models.ViewProjectionRef
+	byView := make(map[string][]string)
+	for _, row := range rows {
+		cells, err := json.Marshal(row.Cells)
+		if err != nil {
+			return fmt.Errorf("failed to marshal view projection cells: %w", err)
+		}
+		list = append(list, models.ViewProjectionRow{
+			ViewID:      row.ViewID,
+			RecordID:    row.RecordID,
+			Cells:       string(cells),
+			UpdatedTime: row.UpdatedAt,
+		})
+		byView[row.ViewID] = append(byView[row.ViewID], row.RecordID)
+		seen := make(map[string]bool, len(row.Refs))
+		for _, ref := range row.Refs {
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, models.ViewProjectionRef{ViewID: row.ViewID, RefRecordID: ref, RecordID: row.RecordID})
+			}
+		}
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "view_id"}, {Name: "record_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"cells", "updated_time"}),
+		}).CreateInBatches(list, 500).Error; err != nil {
+			return err
+		}
+		for viewID, recordIDs := range byView {
+			if err := tx.Where("view_id = ? AND record_id IN ?", viewID, recordIDs).Delete(&models.ViewProjectionRef{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(refs) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(refs, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save view projection rows: %w", err)
+	}
+	return nil
+}
+
+// DeleteRows 删除行及其关联引用
+func (r *ViewProjectionRepositoryImpl) DeleteRows(ctx context.Context, viewID string, recordIDs []string) error {
+	if len(recordIDs) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("view_id = ? AND record_id IN ?", viewID, recordIDs).Delete(&models.ViewProjectionRef{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("view_id = ? AND record_id IN ?", viewID, recordIDs).Delete(&models.ViewProjectionRow{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete view projection rows: %w", err)
+	}
+	return nil
+}
+
+// FindReferencing 列出引用了给定关联记录的行
+func (r *ViewProjectionRepositoryImpl) FindReferencing(ctx context.Context, viewID string, refIDs []string) ([]string, error) {
+	if len(refIDs) == 0 {
+		return nil, nil
+	}
+	var recordIDs []string
+	if err := r.db.WithContext(ctx).Model(&models.ViewProjectionRef{}).
+		Distinct("record_id").
+		Where("view_id = ? AND ref_record_id IN ?", viewID, refIDs).
+		Pluck("record_id", &recordIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find view projection references: %w", err)
+	}
+	return recordIDs, nil
+}
+
+func fromViewProjectionModel(model *models.ViewProjection) *viewprojection.Projection {
+	p := &viewprojection.Projection{
+		ViewID:    model.ViewID,
+		BaseID:    model.BaseID,
+		TableID:   model.TableID,
+		RecordIDs: []string{},
+		Truncated: model.Truncated,
+		Cursor:    model.Cursor,
+		BuiltAt:   model.BuiltTime,
+		ReadAt:    model.ReadTime,
+		UpdatedAt: model.UpdatedTime,
+	}
+	if model.RecordIDs != "" {
+		_ = json.Unmarshal([]byte(model.RecordIDs), &p.RecordIDs)
+	}
+	return p
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
 
 	"gorm.io/gorm"
 )
@@ -47,7 +48,7 @@ func (r *ViewRepositoryImpl) Update(ctx context.Context, view *entity.View) erro
 		return fmt.Errorf("failed to convert view to model: %w", err)
 	}
 
-	if err := r.db.WithContext(ctx).Save(model).Error; err != nil {
+	if err := database.WithTx(ctx, r.db).WithContext(ctx).Save(model).Error; err != nil {
 		return fmt.Errorf("failed to update view: %w", err)
 	}
 
@@ -57,7 +58,7 @@ func (r *ViewRepositoryImpl) Update(ctx context.Context, view *entity.View) erro
 // FindByID 根据ID查找视图
 func (r *ViewRepositoryImpl) FindByID(ctx context.Context, id string) (*entity.View, error) {
 	var model models.View
-	err := r.db.WithContext(ctx).
+	err := database.WithTx(ctx, r.db).WithContext(ctx).
 		Where("id = ? AND deleted_time IS NULL", id).
 		First(&model).Error
 
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// WarehouseSyncRepositoryImpl 数据仓库同步GORM实现
+type WarehouseSyncRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWarehouseSyncRepository 创建数据仓库同步仓储
+func NewWarehouseSyncRepository(db *gorm.DB) warehousesync.Repository {
+	return &WarehouseSyncRepositoryImpl{db: db}
+}
+
+// Save 创建或更新任务配置
+// 同步进度（cursor、last_* 等）只由 SaveProgress 写入，避免修改配置时回退并发运行的进度
+func (r *WarehouseSyncRepositoryImpl) Save(ctx context.Context, job *warehousesync.Job) error {
+	model, err := toWarehouseSyncJobModel(job)
+	if err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "view_id", "destination", "credentials", "mode", "interval_seconds",
+			"enabled", "next_run_time", "updated_time",
+		}),
+	}).Create(&model).Error; err != nil {
+		return fmt.Errorf("failed to save warehouse sync job: %w", err)
+	}
+	return nil
+}
+
+// FindByID 获取任务
+func (r *WarehouseSyncRepositoryImpl) FindByID(ctx context.Context, id string) (*warehousesync.Job, error) {
+	var model models.WarehouseSyncJob
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouse sync job: %w", err)
+	}
+	return fromWarehouseSyncJobModel(&model), nil
+}
+
+// ListByBase 列出 Base 下的任务
+func (r *WarehouseSyncRepositoryImpl) ListByBase(ctx context.Context, baseID string) ([]*warehousesync.Job, error) {
+	var list []models.WarehouseSyncJob
+	if err := r.db.WithContext(ctx).
+		Where("base_id = ?", baseID).
+		Order("created_time ASC").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list warehouse sync jobs: %w", err)
+	}
+	jobs := make([]*warehousesync.Job, 0, len(list))
+	for i := range list {
+		jobs = append(jobs, fromWarehouseSyncJobModel(&list[i]))
+	}
+	return jobs, nil
+}
+
+// Delete 删除任务及其运行记录
+func (r *WarehouseSyncRepositoryImpl) Delete(ctx context.Context, id string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", id).Delete(&models.WarehouseSyncRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.WarehouseSyncJob{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete warehouse sync job: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue 领取到期任务（PostgreSQL 使用 SKIP LOCKED，多实例并发领取互不阻塞）
+func (r *WarehouseSyncRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]*warehousesync.Job, error) {
+	var claimed []*warehousesync.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.WarehouseSyncJob{}).
+			Where("enabled = ? AND next_run_time <= ?", true, now).
+			Where("locked_until IS NULL OR locked_until < ?", now).
+			Order("next_run_time ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.WarehouseSyncJob
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].ID)
+		}
+		if err := tx.Model(&models.WarehouseSyncJob{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leaseUntil).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			claimed = append(claimed, fromWarehouseSyncJobModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim warehouse sync jobs: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveProgress 保存同步进度并释放租约
+func (r *WarehouseSyncRepositoryImpl) SaveProgress(ctx context.Context, job *warehousesync.Job) error {
+	if err := r.db.WithContext(ctx).Model(&models.WarehouseSyncJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"cursor":        job.Cursor,
+			"next_run_time": job.NextRunAt,
+			"last_run_time": job.LastRunAt,
+			"last_status":   string(job.LastStatus),
+			"last_error":    job.LastError,
+			"locked_until":  nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to save warehouse sync progress: %w", err)
+	}
+	return nil
+}
+
+// SaveRun 创建或更新运行记录
+func (r *WarehouseSyncRepositoryImpl) SaveRun(ctx context.Context, run *warehousesync.Run) error {
+	addedColumns, err := json.Marshal(run.AddedColumns)
+	if err != nil {
+		return fmt.Errorf("failed to marshal added columns: %w", err)
+	}
+	model := models.WarehouseSyncRun{
+		ID:            run.ID,
+		JobID:         run.JobID,
+		Status:        string(run.Status),
+		Full:          run.Full,
+		Upserted:      run.Upserted,
+		Deleted:       run.Deleted,
+		AddedColumns:  string(addedColumns),
+		Error:         run.Error,
+		CursorAtStart: run.CursorAtStart,
+		StartedTime:   run.StartedAt,
+		FinishedTime:  run.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save warehouse sync run: %w", err)
+	}
+	return nil
+}
+
+// ListRuns 按开始时间倒序列出运行记录
+func (r *WarehouseSyncRepositoryImpl) ListRuns(ctx context.Context, jobID string, limit int) ([]*warehousesync.Run, error) {
+	var list []models.WarehouseSyncRun
+	if err := r.db.WithContext(ctx).
+		Where("job_id = ?", jobID).
+		Order("started_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list warehouse sync runs: %w", err)
+	}
+	runs := make([]*warehousesync.Run, 0, len(list))
+	for i := range list {
+		model := &list[i]
+		run := &warehousesync.Run{
+			ID:            model.ID,
+			JobID:         model.JobID,
+			Status:        warehousesync.RunStatus(model.Status),
+			Full:          model.Full,
+			Upserted:      model.Upserted,
+			Deleted:       model.Deleted,
+			Error:         model.Error,
+			CursorAtStart: model.CursorAtStart,
+			StartedAt:     model.StartedTime,
+			FinishedAt:    model.FinishedTime,
+		}
+		_ = json.Unmarshal([]byte(model.AddedColumns), &run.AddedColumns)
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// PurgeRuns 只保留最近 keep 条运行记录
+func (r *WarehouseSyncRepositoryImpl) PurgeRuns(ctx context.Context, jobID string, keep int) error {
+	keepIDs := r.db.Model(&models.WarehouseSyncRun{}).
+		Select("id").
+		Where("job_id = ?", jobID).
+		Order("started_time DESC").
+		Limit(keep)
+	if err := r.db.WithContext(ctx).
+		Where("job_id = ? AND id NOT IN (?)", jobID, keepIDs).
+		Delete(&models.WarehouseSyncRun{}).Error; err != nil {
+		return fmt.Errorf("failed to purge warehouse sync runs: %w", err)
+	}
+	return nil
+}
+
+func toWarehouseSyncJobModel(job *warehousesync.Job) (models.WarehouseSyncJob, error) {
+	destination, err := json.Marshal(job.Destination)
+	if err != nil {
+		return models.WarehouseSyncJob{}, fmt.Errorf("failed to marshal warehouse destination: %w", err)
+	}
+	return models.WarehouseSyncJob{
+		ID:              job.ID,
+		BaseID:          job.BaseID,
+		TableID:         job.TableID,
+		ViewID:          job.ViewID,
+		Name:            job.Name,
+		Destination:     string(destination),
+		Credentials:     job.Credentials,
+		Mode:            string(job.Mode),
+		IntervalSeconds: int64(job.Interval / time.Second),
+		Enabled:         job.Enabled,
+		Cursor:          job.Cursor,
+		NextRunTime:     job.NextRunAt,
+		LastRunTime:     job.LastRunAt,
+		LastStatus:      string(job.LastStatus),
+		LastError:       job.LastError,
+		CreatedBy:       job.CreatedBy,
+		CreatedTime:     job.CreatedAt,
+		UpdatedTime:     job.UpdatedAt,
+	}, nil
+}
+
+func fromWarehouseSyncJobModel(model *models.WarehouseSyncJob) *warehousesync.Job {
+	job := &warehousesync.Job{
+		ID:          model.ID,
+		BaseID:      model.BaseID,
+		TableID:     model.TableID,
+		ViewID:      model.ViewID,
+		Name:        model.Name,
+		Credentials: model.Credentials,
+		Mode:        warehousesync.Mode(model.Mode),
+		Interval:    time.Duration(model.IntervalSeconds) * time.Second,
+		Enabled:     model.Enabled,
+		Cursor:      model.Cursor,
+		NextRunAt:   model.NextRunTime,
+		LastRunAt:   model.LastRunTime,
+		LastStatus:  warehousesync.RunStatus(model.LastStatus),
+		LastError:   model.LastError,
+		CreatedBy:   model.CreatedBy,
+		CreatedAt:   model.CreatedTime,
+		UpdatedAt:   model.UpdatedTime,
+	}
+	_ = json.Unmarshal([]byte(model.Destination), &job.Destination)
+	return job
+}
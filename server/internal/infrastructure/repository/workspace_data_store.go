@@ -0,0 +1,341 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/workspacelifecycle"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// workspaceScopeColumns 元数据表中引用空间、Base 或数据表的列（ID 带类型前缀，全局唯一）
+var workspaceScopeColumns = []string{"space_id", "base_id", "table_id", "resource_id", "aggregate_id"}
+
+// workspaceRetainedTables 清除后仍保留的表：生命周期与导出包记录清除结果，审计日志按保留策略单独清理
+var workspaceRetainedTables = map[string]bool{
+	"workspace_lifecycle":     true,
+	"workspace_export_bundle": true,
+	"audit_log":               true,
+}
+
+// workspaceSpaceTable 空间本身所在的表（按 id 匹配，最后删除）
+const workspaceSpaceTable = "space"
+
+// WorkspaceDataStoreImpl 工作空间数据的枚举、导出与清除实现
+// 元数据表按列自动发现：包含 workspaceScopeColumns 中任一列的表都视为可能包含空间的数据
+type WorkspaceDataStoreImpl struct {
+	db           *gorm.DB
+	dbProvider   database.DBProvider
+	regionRouter *database.RegionRouter
+}
+
+// NewWorkspaceDataStore 创建工作空间数据存储
+func NewWorkspaceDataStore(db *gorm.DB, dbProvider database.DBProvider) *WorkspaceDataStoreImpl {
+	return &WorkspaceDataStoreImpl{
+		db:         db,
+		dbProvider: dbProvider,
+	}
+}
+
+// SetRegionRouter 设置数据驻留路由器（动态数据表按空间区域存放）
+func (s *WorkspaceDataStoreImpl) SetRegionRouter(router *database.RegionRouter) {
+	s.regionRouter = router
+}
+
+// dataDB 获取 Base 数据面连接
+func (s *WorkspaceDataStoreImpl) dataDB(ctx context.Context, baseID string) *gorm.DB {
+	if s.regionRouter == nil {
+		return s.db
+	}
+	return s.regionRouter.DBForBase(ctx, baseID)
+}
+
+// Inventory 列出空间的 Base 与数据表（含已软删除的）
+func (s *WorkspaceDataStoreImpl) Inventory(ctx context.Context, spaceID string) (*workspacelifecycle.Inventory, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.Space{}).Where("id = ?", spaceID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to get space: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	inv := &workspacelifecycle.Inventory{SpaceID: spaceID}
+	if err := s.db.WithContext(ctx).Unscoped().
+		Model(&models.Base{}).
+		Where("space_id = ?", spaceID).
+		Order("id").
+		Pluck("id", &inv.BaseIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list space bases: %w", err)
+	}
+	if len(inv.BaseIDs) == 0 {
+		return inv, nil
+	}
+
+	var tables []models.Table
+	if err := s.db.WithContext(ctx).Unscoped().
+		Select("id", "base_id", "name", "deleted_time").
+		Where("base_id IN ?", inv.BaseIDs).
+		Order("base_id, id").
+		Find(&tables).Error; err != nil {
+		return nil, fmt.Errorf("failed to list space tables: %w", err)
+	}
+	for _, t := range tables {
+		inv.Tables = append(inv.Tables, workspacelifecycle.TableRef{
+			BaseID:  t.BaseID,
+			TableID: t.ID,
+			Name:    t.Name,
+			Deleted: t.DeletedTime.Valid,
+		})
+	}
+	return inv, nil
+}
+
+// MetadataTables 包含空间相关行的元数据表（按名称排序）
+func (s *WorkspaceDataStoreImpl) MetadataTables(ctx context.Context) ([]string, error) {
+	db := s.db.WithContext(ctx)
+	names, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	sort.Strings(names)
+
+	var tables []string
+	for _, name := range names {
+		if workspaceRetainedTables[name] {
+			continue
+		}
+		columns, _, err := s.scopeColumns(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) > 0 {
+			tables = append(tables, name)
+		}
+	}
+	return tables, nil
+}
+
+// scopeColumns 表中用于定位空间数据的列与排序用的主键列
+// SQLite 下动态数据表与元数据表在同一个库中，带 __id 列的表视为数据表
+func (s *WorkspaceDataStoreImpl) scopeColumns(ctx context.Context, table string) ([]string, []string, error) {
+	types, err := s.db.WithContext(ctx).Migrator().ColumnTypes(table)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	present := make(map[string]bool, len(types))
+	var keys []string
+	for _, ct := range types {
+		present[ct.Name()] = true
+		if pk, ok := ct.PrimaryKey(); ok && pk {
+			keys = append(keys, ct.Name())
+		}
+	}
+	if present["__id"] {
+		return nil, nil, nil
+	}
+	if table == workspaceSpaceTable {
+		return []string{"id"}, []string{"id"}, nil
+	}
+	var columns []string
+	for _, col := range workspaceScopeColumns {
+		if present[col] {
+			columns = append(columns, col)
+		}
+	}
+	return columns, keys, nil
+}
+
+// scope 构造元数据表中属于空间的行的查询条件
+func (s *WorkspaceDataStoreImpl) scope(ctx context.Context, table string, inv *workspacelifecycle.Inventory) (string, []interface{}, []string, error) {
+	columns, keys, err := s.scopeColumns(ctx, table)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(columns) == 0 {
+		return "", nil, nil, fmt.Errorf("table %s has no workspace scope column", table)
+	}
+
+	ids := inv.IDs()
+	conds := make([]string, 0, len(columns))
+	args := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		conds = append(conds, quoteIdentifier(col)+" IN ?")
+		args = append(args, ids)
+	}
+	return strings.Join(conds, " OR "), args, keys, nil
+}
+
+// ScanMetadata 分页读取元数据表中属于空间的行（按主键排序）
+func (s *WorkspaceDataStoreImpl) ScanMetadata(ctx context.Context, table string, inv *workspacelifecycle.Inventory, batchSize int, fn func(rows []map[string]interface{}) error) error {
+	cond, args, keys, err := s.scope(ctx, table, inv)
+	if err != nil {
+		return err
+	}
+	for offset := 0; ; offset += batchSize {
+		query := s.db.WithContext(ctx).Table(table).Where(cond, args...)
+		for _, key := range keys {
+			query = query.Order(quoteIdentifier(key))
+		}
+
+		var rows []map[string]interface{}
+		if err := query.Offset(offset).Limit(batchSize).Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to read table %s: %w", table, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// ScanRows 按记录ID顺序分页读取数据表的全部列
+func (s *WorkspaceDataStoreImpl) ScanRows(ctx context.Context, ref workspacelifecycle.TableRef, batchSize int, fn func(rows []map[string]interface{}) error) error {
+	db := s.dataDB(ctx, ref.BaseID)
+	tableName, err := s.dbProvider.ResolveTableName(ctx, ref.BaseID, ref.TableID)
+	if err != nil {
+		return err
+	}
+	after := ""
+	for {
+		var rows []map[string]interface{}
+		if err := db.WithContext(ctx).
+			Table(tableName).
+			Where("__id > ?", after).
+			Order("__id ASC").
+			Limit(batchSize).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to read table %s: %w", ref.TableID, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		after = fmt.Sprint(rows[len(rows)-1]["__id"])
+	}
+}
+
+// Attachments 空间数据表中的附件（含已软删除的）
+func (s *WorkspaceDataStoreImpl) Attachments(ctx context.Context, inv *workspacelifecycle.Inventory) ([]workspacelifecycle.AttachmentFile, error) {
+	if len(inv.Tables) == 0 {
+		return nil, nil
+	}
+	tableIDs := make([]string, 0, len(inv.Tables))
+	for _, t := range inv.Tables {
+		tableIDs = append(tableIDs, t.TableID)
+	}
+
+	var list []models.Attachment
+	if err := s.db.WithContext(ctx).Unscoped().
+		Select("id", "path", "small_thumbnail", "large_thumbnail", "content_hash", "deleted_time").
+		Where("table_id IN ?", tableIDs).
+		Order("id").
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list space attachments: %w", err)
+	}
+	files := make([]workspacelifecycle.AttachmentFile, 0, len(list))
+	for _, a := range list {
+		file := workspacelifecycle.AttachmentFile{
+			ID:          a.ID,
+			Path:        a.Path,
+			ContentHash: a.ContentHash,
+			Deleted:     a.DeletedTime.Valid,
+		}
+		for _, thumb := range []*string{a.SmallThumbnail, a.LargeThumbnail} {
+			if thumb != nil && *thumb != "" {
+				file.Thumbnails = append(file.Thumbnails, *thumb)
+			}
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// DropDataTables 删除空间全部 Base 的 Schema 与物理表；启用工作空间隔离时同时删除租户Schema
+func (s *WorkspaceDataStoreImpl) DropDataTables(ctx context.Context, inv *workspacelifecycle.Inventory) error {
+	ctx = database.WithTenant(ctx, inv.SpaceID)
+	for _, baseID := range inv.BaseIDs {
+		if err := s.dbProvider.DropSchema(ctx, baseID); err != nil {
+			return fmt.Errorf("failed to drop base %s: %w", baseID, err)
+		}
+	}
+	if tenant, ok := s.dbProvider.(interface {
+		DropTenantSchema(ctx context.Context, spaceID string) error
+	}); ok {
+		if err := tenant.DropTenantSchema(ctx, inv.SpaceID); err != nil {
+			return fmt.Errorf("failed to drop tenant schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// PurgeMetadata 在一个事务中删除空间的元数据行并释放附件的去重文件引用，最后删除空间本身
+func (s *WorkspaceDataStoreImpl) PurgeMetadata(ctx context.Context, inv *workspacelifecycle.Inventory) (map[string]int64, int, error) {
+	tables, err := s.MetadataTables(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	files, err := s.Attachments(ctx, inv)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deleted := make(map[string]int64)
+	released := 0
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 按内容寻址的文件可能被其他空间共用，只释放引用，由回收任务清理
+		blobs := NewAttachmentBlobRepository(tx)
+		for _, file := range files {
+			if file.ContentHash == "" || file.Deleted {
+				continue
+			}
+			if err := blobs.Release(ctx, file.ContentHash); err != nil {
+				return err
+			}
+			released++
+		}
+
+		for _, table := range tables {
+			if table == workspaceSpaceTable {
+				continue
+			}
+			cond, args, _, err := s.scope(ctx, table, inv)
+			if err != nil {
+				return err
+			}
+			result := tx.Exec("DELETE FROM "+quoteIdentifier(table)+" WHERE "+cond, args...)
+			if result.Error != nil {
+				return fmt.Errorf("failed to purge table %s: %w", table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				deleted[table] = result.RowsAffected
+			}
+		}
+
+		result := tx.Unscoped().Where("id = ?", inv.SpaceID).Delete(&models.Space{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete space: %w", result.Error)
+		}
+		deleted[workspaceSpaceTable] = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return deleted, released, nil
+}
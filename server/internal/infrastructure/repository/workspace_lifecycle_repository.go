@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/workspacelifecycle"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// WorkspaceLifecycleRepositoryImpl 工作空间生命周期GORM实现
+type WorkspaceLifecycleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWorkspaceLifecycleRepository 创建工作空间生命周期仓储
+func NewWorkspaceLifecycleRepository(db *gorm.DB) workspacelifecycle.Repository {
+	return &WorkspaceLifecycleRepositoryImpl{db: db}
+}
+
+// FindBySpace 获取空间的生命周期
+func (r *WorkspaceLifecycleRepositoryImpl) FindBySpace(ctx context.Context, spaceID string) (*workspacelifecycle.Lifecycle, error) {
+	var model models.WorkspaceLifecycle
+	err := r.db.WithContext(ctx).Where("space_id = ?", spaceID).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace lifecycle: %w", err)
+	}
+	return fromWorkspaceLifecycleModel(&model), nil
+}
+
+// Save 创建或更新生命周期
+func (r *WorkspaceLifecycleRepositoryImpl) Save(ctx context.Context, lifecycle *workspacelifecycle.Lifecycle) error {
+	var report string
+	if lifecycle.Purge != nil {
+		data, err := json.Marshal(lifecycle.Purge)
+		if err != nil {
+			return fmt.Errorf("failed to marshal purge report: %w", err)
+		}
+		report = string(data)
+	}
+	model := models.WorkspaceLifecycle{
+		SpaceID:               lifecycle.SpaceID,
+		State:                 string(lifecycle.State),
+		Reason:                lifecycle.Reason,
+		SuspendedTime:         lifecycle.SuspendedAt,
+		DeletionRequestedTime: lifecycle.DeletionRequestedAt,
+		PurgeAfter:            lifecycle.PurgeAfter,
+		PurgeBundleID:         lifecycle.PurgeBundleID,
+		PurgeReport:           report,
+		PurgedTime:            lifecycle.PurgedAt,
+		LastError:             lifecycle.LastError,
+		UpdatedBy:             lifecycle.UpdatedBy,
+		UpdatedTime:           lifecycle.UpdatedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save workspace lifecycle: %w", err)
+	}
+	return nil
+}
+
+// List 按更新时间倒序列出生命周期
+func (r *WorkspaceLifecycleRepositoryImpl) List(ctx context.Context, states []workspacelifecycle.State) ([]*workspacelifecycle.Lifecycle, error) {
+	query := r.db.WithContext(ctx).Order("updated_time DESC")
+	if len(states) > 0 {
+		values := make([]string, 0, len(states))
+		for _, state := range states {
+			values = append(values, string(state))
+		}
+		query = query.Where("state IN ?", values)
+	}
+	var list []models.WorkspaceLifecycle
+	if err := query.Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list workspace lifecycles: %w", err)
+	}
+	lifecycles := make([]*workspacelifecycle.Lifecycle, 0, len(list))
+	for i := range list {
+		lifecycles = append(lifecycles, fromWorkspaceLifecycleModel(&list[i]))
+	}
+	return lifecycles, nil
+}
+
+// ClaimDue 领取宽限期已结束或清除已中断的空间（PostgreSQL 使用 SKIP LOCKED）
+func (r *WorkspaceLifecycleRepositoryImpl) ClaimDue(ctx context.Context, now, staleBefore time.Time, limit int) ([]*workspacelifecycle.Lifecycle, error) {
+	var claimed []*workspacelifecycle.Lifecycle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.WorkspaceLifecycle{}).
+			Where("(state = ? AND purge_after <= ?) OR (state = ? AND updated_time < ?)",
+				string(workspacelifecycle.StatePendingDeletion), now,
+				string(workspacelifecycle.StatePurging), staleBefore).
+			Order("purge_after ASC").
+			Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var list []models.WorkspaceLifecycle
+		if err := query.Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]string, 0, len(list))
+		for i := range list {
+			ids = append(ids, list[i].SpaceID)
+		}
+		if err := tx.Model(&models.WorkspaceLifecycle{}).
+			Where("space_id IN ?", ids).
+			Updates(map[string]interface{}{
+				"state":        string(workspacelifecycle.StatePurging),
+				"updated_time": now,
+			}).Error; err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].State = string(workspacelifecycle.StatePurging)
+			list[i].UpdatedTime = now
+			claimed = append(claimed, fromWorkspaceLifecycleModel(&list[i]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim workspace deletions: %w", err)
+	}
+	return claimed, nil
+}
+
+// SaveBundle 创建或更新导出包
+func (r *WorkspaceLifecycleRepositoryImpl) SaveBundle(ctx context.Context, bundle *workspacelifecycle.Bundle) error {
+	var manifest string
+	if bundle.Manifest != nil {
+		data, err := json.Marshal(bundle.Manifest)
+		if err != nil {
+			return fmt.Errorf("failed to marshal bundle manifest: %w", err)
+		}
+		manifest = string(data)
+	}
+	model := models.WorkspaceExportBundle{
+		ID:           bundle.ID,
+		SpaceID:      bundle.SpaceID,
+		Status:       string(bundle.Status),
+		Manifest:     manifest,
+		Error:        bundle.Error,
+		CreatedBy:    bundle.CreatedBy,
+		CreatedTime:  bundle.CreatedAt,
+		UpdatedTime:  bundle.UpdatedAt,
+		FinishedTime: bundle.FinishedAt,
+	}
+	if err := r.db.WithContext(ctx).Save(&model).Error; err != nil {
+		return fmt.Errorf("failed to save workspace export bundle: %w", err)
+	}
+	return nil
+}
+
+// FindBundle 获取导出包
+func (r *WorkspaceLifecycleRepositoryImpl) FindBundle(ctx context.Context, id string) (*workspacelifecycle.Bundle, error) {
+	var model models.WorkspaceExportBundle
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workspace export bundle: %w", err)
+	}
+	return fromWorkspaceExportBundleModel(&model), nil
+}
+
+// ListBundles 按创建时间倒序列出空间的导出包
+func (r *WorkspaceLifecycleRepositoryImpl) ListBundles(ctx context.Context, spaceID string, limit int) ([]*workspacelifecycle.Bundle, error) {
+	var list []models.WorkspaceExportBundle
+	if err := r.db.WithContext(ctx).
+		Where("space_id = ?", spaceID).
+		Order("created_time DESC").
+		Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, fmt.Errorf("failed to list workspace export bundles: %w", err)
+	}
+	bundles := make([]*workspacelifecycle.Bundle, 0, len(list))
+	for i := range list {
+		bundles = append(bundles, fromWorkspaceExportBundleModel(&list[i]))
+	}
+	return bundles, nil
+}
+
+func fromWorkspaceLifecycleModel(model *models.WorkspaceLifecycle) *workspacelifecycle.Lifecycle {
+	var report *workspacelifecycle.PurgeReport
+	if model.PurgeReport != "" {
+		report = &workspacelifecycle.PurgeReport{}
+		if err := json.Unmarshal([]byte(model.PurgeReport), report); err != nil {
+			report = nil
+		}
+	}
+	return &workspacelifecycle.Lifecycle{
+		SpaceID:             model.SpaceID,
+		State:               workspacelifecycle.State(model.State),
+		Reason:              model.Reason,
+		SuspendedAt:         model.SuspendedTime,
+		DeletionRequestedAt: model.DeletionRequestedTime,
+		PurgeAfter:          model.PurgeAfter,
+		PurgeBundleID:       model.PurgeBundleID,
+		Purge:               report,
+		PurgedAt:            model.PurgedTime,
+		LastError:           model.LastError,
+		UpdatedBy:           model.UpdatedBy,
+		UpdatedAt:           model.UpdatedTime,
+	}
+}
+
+func fromWorkspaceExportBundleModel(model *models.WorkspaceExportBundle) *workspacelifecycle.Bundle {
+	var manifest *workspacelifecycle.Manifest
+	if model.Manifest != "" {
+		manifest = &workspacelifecycle.Manifest{}
+		if err := json.Unmarshal([]byte(model.Manifest), manifest); err != nil {
+			manifest = nil
+		}
+	}
+	return &workspacelifecycle.Bundle{
+		ID:         model.ID,
+		SpaceID:    model.SpaceID,
+		Status:     workspacelifecycle.BundleStatus(model.Status),
+		Manifest:   manifest,
+		Error:      model.Error,
+		CreatedBy:  model.CreatedBy,
+		CreatedAt:  model.CreatedTime,
+		UpdatedAt:  model.UpdatedTime,
+		FinishedAt: model.FinishedTime,
+	}
+}
@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachmentscan"
+)
+
+// icapDefaultPort ICAP 默认端口
+const icapDefaultPort = "1344"
+
+// encapsulatedResponseHeader 随 RESPMOD 请求封装的 HTTP 响应头
+const encapsulatedResponseHeader = "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\n\r\n"
+
+// ICAP ICAP 客户端（RESPMOD，兼容 c-icap、Kaspersky、Symantec 等网关）
+type ICAP struct {
+	url     *url.URL
+	timeout time.Duration
+}
+
+// NewICAP 创建 ICAP 客户端，serviceURL 形如 icap://127.0.0.1:1344/avscan
+func NewICAP(serviceURL string, timeout time.Duration) (*ICAP, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil || u.Scheme != "icap" || u.Host == "" {
+		return nil, fmt.Errorf("invalid ICAP url: %q", serviceURL)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), icapDefaultPort)
+	}
+	return &ICAP{url: u, timeout: timeout}, nil
+}
+
+// Name 引擎名称
+func (c *ICAP) Name() string {
+	return EngineICAP
+}
+
+// Scan 以 RESPMOD 提交文件内容
+// 204 表示无需修改（未发现威胁）；200 时以 X-Infection-Found / X-Violations-Found 头或被替换为错误页的封装响应判定为感染
+func (c *ICAP) Scan(ctx context.Context, content io.Reader) (*attachmentscan.Verdict, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.url.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ICAP server: %w", err)
+	}
+	defer conn.Close()
+	if at := deadline(ctx, c.timeout); !at.IsZero() {
+		_ = conn.SetDeadline(at)
+	}
+
+	w := bufio.NewWriterSize(conn, chunkSize+16)
+	fmt.Fprintf(w, "RESPMOD %s ICAP/1.0\r\n", c.url.String())
+	fmt.Fprintf(w, "Host: %s\r\n", c.url.Host)
+	fmt.Fprintf(w, "Allow: 204\r\n")
+	fmt.Fprintf(w, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(encapsulatedResponseHeader))
+	w.WriteString(encapsulatedResponseHeader)
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			fmt.Fprintf(w, "%x\r\n", n)
+			w.Write(buf[:n])
+			w.WriteString("\r\n")
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read attachment: %w", readErr)
+		}
+	}
+	w.WriteString("0\r\n\r\n")
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to send ICAP request: %w", err)
+	}
+
+	return readICAPResponse(bufio.NewReader(conn))
+}
+
+// readICAPResponse 解析 ICAP 响应
+func readICAPResponse(r *bufio.Reader) (*attachmentscan.Verdict, error) {
+	tp := textproto.NewReader(r)
+	status, err := tp.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ICAP response: %w", err)
+	}
+	parts := strings.SplitN(status, " ", 3)
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "ICAP/") {
+		return nil, fmt.Errorf("malformed ICAP status line: %q", status)
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ICAP headers: %w", err)
+	}
+
+	switch parts[1] {
+	case "204":
+		return &attachmentscan.Verdict{}, nil
+	case "200":
+	default:
+		return nil, fmt.Errorf("ICAP server returned %s", strings.Join(parts[1:], " "))
+	}
+
+	for _, name := range []string{"X-Infection-Found", "X-Violations-Found", "X-Virus-Id"} {
+		if value := header.Get(name); value != "" {
+			return &attachmentscan.Verdict{Infected: true, Signature: icapThreatName(value)}, nil
+		}
+	}
+	// 未携带感染头时，网关通常以错误页替换原响应
+	if strings.Contains(header.Get("Encapsulated"), "res-hdr") {
+		resp, err := http.ReadResponse(r, nil)
+		if err == nil && resp.StatusCode >= http.StatusBadRequest {
+			return &attachmentscan.Verdict{Infected: true, Signature: "blocked by ICAP server"}, nil
+		}
+	}
+	return &attachmentscan.Verdict{}, nil
+}
+
+// icapThreatName 从 X-Infection-Found（Type=0; Resolution=2; Threat=Eicar;）中取出威胁名称
+func icapThreatName(value string) string {
+	for _, part := range strings.Split(value, ";") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(part), "Threat="); ok {
+			return name
+		}
+	}
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachmentscan"
+)
+
+// 扫描引擎
+const (
+	EngineNone   = "none"
+	EngineClamAV = "clamav"
+	EngineICAP   = "icap"
+)
+
+// chunkSize 向扫描引擎发送文件内容的分块大小
+const chunkSize = 64 << 10
+
+// New 根据配置创建扫描引擎；未配置引擎时返回 nil
+func New(cfg config.AttachmentScanConfig) (attachmentscan.Scanner, error) {
+	switch cfg.Engine {
+	case "", EngineNone:
+		return nil, nil
+	case EngineClamAV:
+		if cfg.ClamAVAddress == "" {
+			return nil, fmt.Errorf("attachment_scan.clamav_address is required")
+		}
+		return NewClamAV(cfg.ClamAVAddress, cfg.Timeout), nil
+	case EngineICAP:
+		return NewICAP(cfg.ICAPURL, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unsupported attachment scan engine: %s", cfg.Engine)
+	}
+}
+
+// deadline 连接的读写截止时间：取上下文截止时间与超时中较早者
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var at time.Time
+	if timeout > 0 {
+		at = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (at.IsZero() || d.Before(at)) {
+		at = d
+	}
+	return at
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serveOnce 启动只处理一个连接的测试服务
+func serveOnce(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handle(conn)
+	}()
+	return ln.Addr().String()
+}
+
+// fakeClamd 读取 INSTREAM 内容，包含 EICAR 时报告感染
+func fakeClamd(t *testing.T) string {
+	return serveOnce(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		if cmd, err := r.ReadString(0); err != nil || cmd != "zINSTREAM\x00" {
+			conn.Write([]byte("UNKNOWN COMMAND\x00"))
+			return
+		}
+		var body strings.Builder
+		size := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(r, size); err != nil {
+				return
+			}
+			n := binary.BigEndian.Uint32(size)
+			if n == 0 {
+				break
+			}
+			chunk := make([]byte, n)
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return
+			}
+			body.Write(chunk)
+		}
+		if strings.Contains(body.String(), "EICAR") {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	})
+}
+
+func TestClamAVScan(t *testing.T) {
+	ctx := context.Background()
+	verdict, err := NewClamAV("tcp://"+fakeClamd(t), time.Second).Scan(ctx, strings.NewReader("hello"))
+	if err != nil || verdict.Infected {
+		t.Fatalf("期望未感染，得到 %+v, %v", verdict, err)
+	}
+	verdict, err = NewClamAV(fakeClamd(t), time.Second).Scan(ctx, strings.NewReader(eicar))
+	if err != nil || !verdict.Infected || verdict.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("期望感染，得到 %+v, %v", verdict, err)
+	}
+	if _, err := parseClamAVReply("INSTREAM size limit exceeded. ERROR"); err == nil {
+		t.Error("ERROR 响应应返回错误")
+	}
+}
+
+// fakeICAP 读取 RESPMOD 请求，包含 EICAR 时返回感染头，否则返回 204
+func fakeICAP(t *testing.T, infectedReply string) string {
+	return serveOnce(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		var request strings.Builder
+		for !strings.HasSuffix(request.String(), "0\r\n\r\n") {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			request.WriteString(line)
+		}
+		if !strings.HasPrefix(request.String(), "RESPMOD icap://") || !strings.Contains(request.String(), "Encapsulated: res-hdr=0, res-body=") {
+			conn.Write([]byte("ICAP/1.0 400 Bad Request\r\n\r\n"))
+			return
+		}
+		if strings.Contains(request.String(), "EICAR") {
+			conn.Write([]byte(infectedReply))
+		} else {
+			conn.Write([]byte("ICAP/1.0 204 No Content\r\nISTag: \"test\"\r\n\r\n"))
+		}
+	})
+}
+
+func TestICAPScan(t *testing.T) {
+	ctx := context.Background()
+	withHeader := "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=EICAR-Test-File;\r\nEncapsulated: null-body=0\r\n\r\n"
+	blockPage := "ICAP/1.0 200 OK\r\nEncapsulated: res-hdr=0, res-body=38\r\n\r\nHTTP/1.1 403 Forbidden\r\nServer: x\r\n\r\n0\r\n\r\n"
+
+	client, err := NewICAP("icap://"+fakeICAP(t, withHeader)+"/avscan", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict, err := client.Scan(ctx, strings.NewReader("hello")); err != nil || verdict.Infected {
+		t.Fatalf("期望未感染，得到 %+v, %v", verdict, err)
+	}
+
+	client, _ = NewICAP("icap://"+fakeICAP(t, withHeader)+"/avscan", time.Second)
+	verdict, err := client.Scan(ctx, strings.NewReader(eicar))
+	if err != nil || !verdict.Infected || verdict.Signature != "EICAR-Test-File" {
+		t.Fatalf("期望按感染头判定，得到 %+v, %v", verdict, err)
+	}
+
+	client, _ = NewICAP("icap://"+fakeICAP(t, blockPage)+"/avscan", time.Second)
+	verdict, err = client.Scan(ctx, strings.NewReader(eicar))
+	if err != nil || !verdict.Infected {
+		t.Fatalf("期望按错误页判定感染，得到 %+v, %v", verdict, err)
+	}
+
+	if _, err := NewICAP("http://127.0.0.1/avscan", time.Second); err == nil {
+		t.Error("非 icap 地址应返回错误")
+	}
+}
@@ -0,0 +1,303 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"github.com/nfnt/resize"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+)
+
+// defaultImageQuality 派生图默认 JPEG 质量
+const defaultImageQuality = 82
+
+// ImageTransformer 图片派生图生成（缩放、旋转、格式转换与元数据去除）
+type ImageTransformer struct {
+	maxPixels int
+	quality   int
+}
+
+// NewImageTransformer 创建图片处理器
+// maxPixels 限制原图像素数，防止解码超大图片（解压炸弹）耗尽内存；quality 为默认 JPEG 质量
+func NewImageTransformer(maxPixels, quality int) *ImageTransformer {
+	if quality <= 0 || quality > 100 {
+		quality = defaultImageQuality
+	}
+	return &ImageTransformer{maxPixels: maxPixels, quality: quality}
+}
+
+// Transform 生成派生图
+// 只去除元数据且原图为 JPEG / PNG 时直接删除元数据段，不重新编码；其余情况解码后按 EXIF 方向摆正再处理
+func (t *ImageTransformer) Transform(source []byte, sourceMimeType string, opts *attachment.ImageTransform) ([]byte, string, error) {
+	format := opts.OutputFormat(sourceMimeType)
+	orientation := jpegOrientation(source)
+	if opts.StripOnly() && orientation <= 1 {
+		switch {
+		case format == attachment.ImageFormatJPEG && isJPEG(source):
+			if stripped, err := stripJPEGMetadata(source); err == nil {
+				return stripped, format, nil
+			}
+		case format == attachment.ImageFormatPNG && isPNG(source):
+			if stripped, err := stripPNGMetadata(source); err == nil {
+				return stripped, format, nil
+			}
+		}
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, "", fmt.Errorf("unsupported image: %w", err)
+	}
+	if t.maxPixels > 0 && cfg.Width*cfg.Height > t.maxPixels {
+		return nil, "", fmt.Errorf("image too large: %dx%d", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	img = orient(img, orientation)
+	switch opts.Rotate {
+	case 90:
+		img = orient(img, 6)
+	case 180:
+		img = orient(img, 3)
+	case 270:
+		img = orient(img, 8)
+	}
+	img = fitImage(img, opts)
+
+	var buf bytes.Buffer
+	switch format {
+	case attachment.ImageFormatPNG:
+		err = png.Encode(&buf, img)
+	case attachment.ImageFormatGIF:
+		err = gif.Encode(&buf, img, nil)
+	default:
+		quality := opts.Quality
+		if quality == 0 {
+			quality = t.quality
+		}
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), format, nil
+}
+
+// fitImage 按缩放方式调整尺寸（不放大原图，fill 除外）
+func fitImage(img image.Image, opts *attachment.ImageTransform) image.Image {
+	if opts.Width == 0 && opts.Height == 0 {
+		return img
+	}
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if opts.Fit == attachment.ImageFitFill {
+		return resize.Resize(uint(opts.Width), uint(opts.Height), img, resize.Lanczos3)
+	}
+
+	scaleW, scaleH := math.Inf(1), math.Inf(1)
+	if opts.Width > 0 {
+		scaleW = float64(opts.Width) / float64(w)
+	}
+	if opts.Height > 0 {
+		scaleH = float64(opts.Height) / float64(h)
+	}
+	scale := math.Min(scaleW, scaleH)
+	if opts.Fit == attachment.ImageFitCover {
+		scale = math.Max(scaleW, scaleH)
+	}
+	if scale < 1 {
+		nw := int(math.Max(1, math.Round(float64(w)*scale)))
+		nh := int(math.Max(1, math.Round(float64(h)*scale)))
+		img = resize.Resize(uint(nw), uint(nh), img, resize.Lanczos3)
+		w, h = nw, nh
+	}
+	if opts.Fit != attachment.ImageFitCover || (w <= opts.Width && h <= opts.Height) {
+		return img
+	}
+
+	// 居中裁剪为目标尺寸
+	cw, ch := min(w, opts.Width), min(h, opts.Height)
+	x0 := img.Bounds().Min.X + (w-cw)/2
+	y0 := img.Bounds().Min.Y + (h-ch)/2
+	dst := image.NewNRGBA(image.Rect(0, 0, cw, ch))
+	draw.Draw(dst, dst.Bounds(), img, image.Pt(x0, y0), draw.Src)
+	return dst
+}
+
+// orient 按 EXIF 方向值（1-8）变换图片，使其按正常方向显示
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // 水平镜像
+				sx, sy = w-1-x, y
+			case 3: // 旋转 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // 垂直镜像
+				sx, sy = x, h-1-y
+			case 5: // 沿主对角线翻转
+				sx, sy = y, x
+			case 6: // 顺时针旋转 90°
+				sx, sy = y, h-1-x
+			case 7: // 沿副对角线翻转
+				sx, sy = w-1-y, h-1-x
+			case 8: // 逆时针旋转 90°
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+func isJPEG(data []byte) bool {
+	return len(data) > 3 && data[0] == 0xFF && data[1] == 0xD8
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func isPNG(data []byte) bool {
+	return bytes.HasPrefix(data, pngSignature)
+}
+
+// jpegSegments 遍历 JPEG 扫描数据之前的标记段，fn 返回 false 时停止
+// 返回扫描数据（SOS 段）起始位置
+func jpegSegments(data []byte, fn func(marker byte, segment []byte) bool) (int, error) {
+	if !isJPEG(data) {
+		return 0, fmt.Errorf("not a jpeg")
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 0, fmt.Errorf("invalid jpeg marker at %d", pos)
+		}
+		marker := data[pos+1]
+		if marker == 0xFF { // 填充字节
+			pos++
+			continue
+		}
+		if marker == 0xDA { // SOS：之后为压缩数据
+			return pos, nil
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 0, fmt.Errorf("truncated jpeg segment")
+		}
+		if !fn(marker, data[pos:end]) {
+			return pos, nil
+		}
+		pos = end
+	}
+	return 0, fmt.Errorf("jpeg without scan data")
+}
+
+// stripJPEGMetadata 删除 EXIF / XMP（APP1）、IPTC（APP13）与注释段，保留色彩配置等其余段
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	sos, err := jpegSegments(data, func(marker byte, segment []byte) bool {
+		if marker != 0xE1 && marker != 0xED && marker != 0xFE {
+			out = append(out, segment...)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(out, data[sos:]...), nil
+}
+
+// jpegOrientation 读取 JPEG 的 EXIF 方向值（无法读取时返回 1）
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	_, _ = jpegSegments(data, func(marker byte, segment []byte) bool {
+		if marker != 0xE1 || len(segment) < 10 || string(segment[4:10]) != "Exif\x00\x00" {
+			return true
+		}
+		orientation = exifOrientation(segment[10:])
+		return false
+	})
+	return orientation
+}
+
+// exifOrientation 从 TIFF 结构的 IFD0 中读取 Orientation（0x0112）
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// pngMetadataChunks 去除的 PNG 元数据块
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "iTXt": true, "zTXt": true, "tIME": true}
+
+// stripPNGMetadata 删除 PNG 中的 EXIF 与文本块
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, fmt.Errorf("truncated png chunk")
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("truncated png chunk")
+		}
+		if !pngMetadataChunks[string(data[pos+4:pos+8])] {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return out, nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+)
+
+func testImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 10), G: uint8(y * 10), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegWithExif 生成带 EXIF 方向与注释段的 JPEG
+func jpegWithExif(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(w, h), nil); err != nil {
+		t.Fatal(err)
+	}
+	// TIFF 头（大端）+ IFD0 一个条目：Orientation
+	tiff := []byte{'M', 'M', 0, 42, 0, 0, 0, 8, 0, 1, 0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, byte(orientation), 0, 0, 0, 0, 0, 0}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := append([]byte{0xFF, 0xE1, 0, 0}, payload...)
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	comment := []byte{0xFF, 0xFE, 0, 7, 'g', 'p', 's', '!', '!'}
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	out = append(out, comment...)
+	return append(out, data[2:]...)
+}
+
+func TestImageTransformerStripsJPEGMetadataLosslessly(t *testing.T) {
+	source := jpegWithExif(t, 8, 4, 1)
+	out, format, err := NewImageTransformer(0, 0).Transform(source, "image/jpeg", &attachment.ImageTransform{Fit: attachment.ImageFitContain, Strip: true})
+	if err != nil || format != attachment.ImageFormatJPEG {
+		t.Fatalf("Transform: %v, %s", err, format)
+	}
+	if bytes.Contains(out, []byte("Exif")) || bytes.Contains(out, []byte("gps!!")) {
+		t.Error("EXIF 与注释段应被删除")
+	}
+	if len(source)-len(out) != 4+6+26+9 {
+		t.Errorf("只应删除元数据段，原 %d 字节，现 %d 字节", len(source), len(out))
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(out)); err != nil {
+		t.Errorf("去除后应仍可解码: %v", err)
+	}
+}
+
+func TestImageTransformerAppliesOrientationAndResize(t *testing.T) {
+	// 方向 6：存储为 8x4，显示为 4x8
+	source := jpegWithExif(t, 8, 4, 6)
+	out, _, err := NewImageTransformer(0, 0).Transform(source, "image/jpeg", &attachment.ImageTransform{Fit: attachment.ImageFitContain, Height: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 4 {
+		t.Errorf("期望 2x4，得到 %dx%d", b.Dx(), b.Dy())
+	}
+	if bytes.Contains(out, []byte("Exif")) {
+		t.Error("派生图不应保留 EXIF")
+	}
+}
+
+func TestImageTransformerFitModes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(20, 10)); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name       string
+		transform  attachment.ImageTransform
+		wantW      int
+		wantH      int
+		wantFormat string
+	}{
+		{"contain", attachment.ImageTransform{Fit: attachment.ImageFitContain, Width: 10, Height: 10}, 10, 5, attachment.ImageFormatPNG},
+		{"contain 不放大", attachment.ImageTransform{Fit: attachment.ImageFitContain, Width: 40}, 20, 10, attachment.ImageFormatPNG},
+		{"cover", attachment.ImageTransform{Fit: attachment.ImageFitCover, Width: 6, Height: 6}, 6, 6, attachment.ImageFormatPNG},
+		{"fill", attachment.ImageTransform{Fit: attachment.ImageFitFill, Width: 7, Height: 3}, 7, 3, attachment.ImageFormatPNG},
+		{"rotate", attachment.ImageTransform{Fit: attachment.ImageFitContain, Rotate: 90, Format: "jpeg"}, 10, 20, attachment.ImageFormatJPEG},
+	}
+	transformer := NewImageTransformer(0, 0)
+	for _, tc := range cases {
+		out, format, err := transformer.Transform(buf.Bytes(), "image/png", &tc.transform)
+		if err != nil || format != tc.wantFormat {
+			t.Fatalf("%s: %v, %s", tc.name, err, format)
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(out))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if cfg.Width != tc.wantW || cfg.Height != tc.wantH {
+			t.Errorf("%s: 期望 %dx%d，得到 %dx%d", tc.name, tc.wantW, tc.wantH, cfg.Width, cfg.Height)
+		}
+	}
+
+	if _, _, err := NewImageTransformer(100, 0).Transform(buf.Bytes(), "image/png", &attachment.ImageTransform{Width: 5}); err == nil {
+		t.Error("超过像素上限应返回错误")
+	}
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(4, 4)); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	text := []byte("Author\x00someone")
+	chunk := make([]byte, 8, 12+len(text))
+	binary.BigEndian.PutUint32(chunk, uint32(len(text)))
+	copy(chunk[4:], "tEXt")
+	chunk = append(chunk, text...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	// 插入在 IHDR（8 字节签名 + 25 字节块）之后
+	source := append(append(append([]byte{}, data[:33]...), chunk...), data[33:]...)
+
+	out, err := stripPNGMetadata(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Error("删除文本块后应与原图一致")
+	}
+}
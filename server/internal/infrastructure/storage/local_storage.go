@@ -124,6 +124,18 @@ func (s *LocalStorage) Delete(ctx context.Context, path string) error {
 	return nil
 }
 
+// DeletePrefix 删除前缀（目录）下的全部文件
+func (s *LocalStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	fullPath := filepath.Join(s.basePath, prefix)
+	if fullPath == filepath.Clean(s.basePath) {
+		return fmt.Errorf("refusing to delete storage root")
+	}
+	if err := os.RemoveAll(fullPath); err != nil {
+		return fmt.Errorf("failed to delete prefix: %w", err)
+	}
+	return nil
+}
+
 // Exists 检查文件是否存在
 func (s *LocalStorage) Exists(ctx context.Context, path string) (bool, error) {
 	fullPath := filepath.Join(s.basePath, path)
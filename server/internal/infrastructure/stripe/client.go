@@ -0,0 +1,83 @@
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
+)
+
+const (
+	defaultBaseURL = "https://api.stripe.com"
+	defaultTimeout = 30 * time.Second
+
+	// maxResponseBytes 读取 Stripe 响应的大小上限
+	maxResponseBytes = 1 << 20
+)
+
+// Client Stripe API 客户端（只实现客户门户会话）✨
+type Client struct {
+	baseURL   string
+	secretKey string
+	client    *http.Client
+}
+
+// NewClient 创建 Stripe 客户端，baseURL 为空时使用 Stripe 官方地址
+func NewClient(baseURL, secretKey string) subscription.Portal {
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	return &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// CreatePortalSession 为客户创建门户会话
+// POST /v1/billing_portal/sessions，表单编码，密钥作为 Bearer 令牌
+func (c *Client) CreatePortalSession(ctx context.Context, customerID, returnURL string) (string, error) {
+	form := url.Values{"customer": {customerID}}
+	if returnURL != "" {
+		form.Set("return_url", returnURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/billing_portal/sessions", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("stripe: create portal session: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", fmt.Errorf("stripe: read response: %w", err)
+	}
+
+	var out struct {
+		URL   string `json:"url"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("stripe: invalid response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("stripe: create portal session failed (status %d): %s", resp.StatusCode, out.Error.Message)
+	}
+	if out.URL == "" {
+		return "", fmt.Errorf("stripe: portal session url missing")
+	}
+	return out.URL, nil
+}
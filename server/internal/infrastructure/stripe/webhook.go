@@ -0,0 +1,176 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
+)
+
+// DefaultTolerance 签名时间戳与当前时间允许的最大偏差
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature 签名头缺失、格式错误或与请求体不匹配
+	ErrInvalidSignature = errors.New("stripe: invalid webhook signature")
+	// ErrSignatureExpired 签名时间戳超出允许的偏差（防重放）
+	ErrSignatureExpired = errors.New("stripe: webhook signature timestamp outside tolerance")
+)
+
+// VerifySignature 校验 Stripe-Signature 头
+// 头形如 t=<时间戳>,v1=<签名>[,v1=<签名>]，签名为 HMAC-SHA256(secret, "<时间戳>.<请求体>") 的十六进制；
+// 轮换密钥期间可能携带多个 v1，任一匹配即可
+func VerifySignature(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	var (
+		timestamp  string
+		signatures [][]byte
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	matched := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return ErrInvalidSignature
+	}
+
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	skew := now.Sub(time.Unix(seconds, 0))
+	if skew > tolerance || skew < -tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// Sign 按 Stripe 的格式生成签名头（用于测试与本地调试）
+func Sign(payload []byte, secret string, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// rawEvent Stripe 事件（只解析用到的字段）
+type rawEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// rawSubscription 订阅对象；较新的 API 版本把计费周期放在订阅项上
+type rawSubscription struct {
+	ID                string            `json:"id"`
+	Customer          string            `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// rawInvoice 发票对象；较新的 API 版本把订阅ID放在 parent.subscription_details 中
+type rawInvoice struct {
+	Customer     string `json:"customer"`
+	Subscription string `json:"subscription"`
+	Parent       struct {
+		SubscriptionDetails struct {
+			Subscription string `json:"subscription"`
+		} `json:"subscription_details"`
+	} `json:"parent"`
+}
+
+// ParseEvent 解析 webhook 请求体；不需要处理的事件类型只返回 ID 与类型
+func ParseEvent(payload []byte) (*subscription.Event, error) {
+	var raw rawEvent
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("stripe: invalid event payload: %w", err)
+	}
+	if raw.ID == "" || raw.Type == "" {
+		return nil, errors.New("stripe: event id or type missing")
+	}
+	ev := &subscription.Event{ID: raw.ID, Type: raw.Type, Created: time.Unix(raw.Created, 0).UTC()}
+
+	switch raw.Type {
+	case subscription.EventSubscriptionCreated, subscription.EventSubscriptionUpdated, subscription.EventSubscriptionDeleted:
+		var sub rawSubscription
+		if err := json.Unmarshal(raw.Data.Object, &sub); err != nil {
+			return nil, fmt.Errorf("stripe: invalid subscription object: %w", err)
+		}
+		ev.SpaceID = sub.Metadata["space_id"]
+		ev.CustomerID = sub.Customer
+		ev.SubscriptionID = sub.ID
+		ev.Status = subscription.Status(sub.Status)
+		ev.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+		periodEnd := sub.CurrentPeriodEnd
+		if len(sub.Items.Data) > 0 {
+			ev.PriceID = sub.Items.Data[0].Price.ID
+			if periodEnd == 0 {
+				periodEnd = sub.Items.Data[0].CurrentPeriodEnd
+			}
+		}
+		if periodEnd > 0 {
+			ev.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+		}
+	case subscription.EventPaymentFailed, subscription.EventPaymentSucceeded:
+		var invoice rawInvoice
+		if err := json.Unmarshal(raw.Data.Object, &invoice); err != nil {
+			return nil, fmt.Errorf("stripe: invalid invoice object: %w", err)
+		}
+		ev.CustomerID = invoice.Customer
+		ev.SubscriptionID = invoice.Subscription
+		if ev.SubscriptionID == "" {
+			ev.SubscriptionID = invoice.Parent.SubscriptionDetails.Subscription
+		}
+	}
+	return ev, nil
+}
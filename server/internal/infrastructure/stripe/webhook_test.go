@@ -0,0 +1,98 @@
+package stripe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/subscription"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1767225600, 0)
+	header := Sign(payload, "whsec_test", now)
+
+	if err := VerifySignature(payload, header, "whsec_test", 0, now.Add(time.Minute)); err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+	// 轮换密钥时携带多个 v1
+	rotated := strings.Replace(header, ",v1=", ",v1=00ff,v1=", 1)
+	if err := VerifySignature(payload, rotated, "whsec_test", 0, now); err != nil {
+		t.Fatalf("signature with multiple v1 rejected: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		payload []byte
+		header  string
+		secret  string
+		now     time.Time
+		want    error
+	}{
+		{"tampered body", []byte(`{"id":"evt_2"}`), header, "whsec_test", now, ErrInvalidSignature},
+		{"wrong secret", payload, header, "whsec_other", now, ErrInvalidSignature},
+		{"missing header", payload, "", "whsec_test", now, ErrInvalidSignature},
+		{"no v1", payload, "t=1767225600", "whsec_test", now, ErrInvalidSignature},
+		{"expired", payload, header, "whsec_test", now.Add(DefaultTolerance + time.Second), ErrSignatureExpired},
+		{"future", payload, header, "whsec_test", now.Add(-DefaultTolerance - time.Second), ErrSignatureExpired},
+	}
+	for _, tc := range cases {
+		if err := VerifySignature(tc.payload, tc.header, tc.secret, 0, tc.now); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestParseSubscriptionEvent(t *testing.T) {
+	payload := []byte(`{
+		"id": "evt_1",
+		"type": "customer.subscription.updated",
+		"created": 1767225600,
+		"data": {"object": {
+			"id": "sub_1",
+			"customer": "cus_1",
+			"status": "active",
+			"cancel_at_period_end": true,
+			"metadata": {"space_id": "spc1"},
+			"items": {"data": [{"current_period_end": 1769904000, "price": {"id": "price_team"}}]}
+		}}
+	}`)
+	ev, err := ParseEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	if ev.ID != "evt_1" || ev.Type != subscription.EventSubscriptionUpdated || ev.Created.Unix() != 1767225600 {
+		t.Fatalf("unexpected envelope: %+v", ev)
+	}
+	if ev.SpaceID != "spc1" || ev.CustomerID != "cus_1" || ev.SubscriptionID != "sub_1" || ev.PriceID != "price_team" {
+		t.Fatalf("unexpected subscription fields: %+v", ev)
+	}
+	if ev.Status != subscription.StatusActive || !ev.CancelAtPeriodEnd || ev.CurrentPeriodEnd.Unix() != 1769904000 {
+		t.Fatalf("unexpected status fields: %+v", ev)
+	}
+}
+
+func TestParseInvoiceEvent(t *testing.T) {
+	payload := []byte(`{
+		"id": "evt_2",
+		"type": "invoice.payment_failed",
+		"created": 1767225600,
+		"data": {"object": {
+			"customer": "cus_1",
+			"parent": {"subscription_details": {"subscription": "sub_1"}}
+		}}
+	}`)
+	ev, err := ParseEvent(payload)
+	if err != nil {
+		t.Fatalf("ParseEvent: %v", err)
+	}
+	if ev.CustomerID != "cus_1" || ev.SubscriptionID != "sub_1" {
+		t.Fatalf("unexpected invoice fields: %+v", ev)
+	}
+
+	if _, err := ParseEvent([]byte(`{"type":"invoice.paid"}`)); err == nil {
+		t.Fatal("expected error for event without id")
+	}
+}
@@ -0,0 +1,104 @@
+package textextract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+// Tesseract 调用本机 tesseract 识别图片；配置 pdftotext 后提取 PDF 的文本层
+// 扫描版 PDF 没有文本层，需要使用 Tika 引擎
+type Tesseract struct {
+	tesseractPath string
+	pdfToTextPath string
+}
+
+// NewTesseract 创建 Tesseract 引擎（pdfToTextPath 为空时不处理 PDF）
+func NewTesseract(tesseractPath, pdfToTextPath string) *Tesseract {
+	return &Tesseract{tesseractPath: tesseractPath, pdfToTextPath: pdfToTextPath}
+}
+
+// Name 引擎名称
+func (t *Tesseract) Name() string {
+	return EngineTesseract
+}
+
+// Supports 常见图片；配置 pdftotext 时包括 PDF
+func (t *Tesseract) Supports(mimeType string) bool {
+	mediaType := baseType(mimeType)
+	return imageTypes[mediaType] || (mediaType == "application/pdf" && t.pdfToTextPath != "")
+}
+
+// Extract 图片经标准输入交给 tesseract；PDF 写入临时文件后由 pdftotext 提取
+func (t *Tesseract) Extract(ctx context.Context, content io.Reader, mimeType string, languages []string) (string, error) {
+	if baseType(mimeType) == "application/pdf" {
+		return t.extractPDF(ctx, content)
+	}
+	args := []string{"stdin", "stdout"}
+	if len(languages) > 0 {
+		args = append(args, "-l", strings.Join(languages, "+"))
+	}
+	return run(ctx, exec.CommandContext(ctx, t.tesseractPath, args...), content)
+}
+
+func (t *Tesseract) extractPDF(ctx context.Context, content io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "luckdb-extract-*.pdf")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	_, err = io.Copy(file, content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	return run(ctx, exec.CommandContext(ctx, t.pdfToTextPath, "-enc", "UTF-8", "-layout", file.Name(), "-"), nil)
+}
+
+// run 执行命令并返回标准输出
+// 超时或无法启动命令时可重试；命令以非零状态退出（文件无法识别）时不可重试
+func run(ctx context.Context, cmd *exec.Cmd, stdin io.Reader) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = stdin
+	cmd.Stdout = &limitedWriter{buf: &stdout, remaining: maxOutputBytes}
+	cmd.Stderr = &limitedWriter{buf: &stderr, remaining: 4 << 10}
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return "", aifield.Permanent(fmt.Errorf("%s exited with %d: %s", cmd.Path, exitErr.ExitCode(), strings.TrimSpace(stderr.String())))
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %w", cmd.Path, err)
+	}
+	return stdout.String(), nil
+}
+
+// limitedWriter 超过上限的输出被丢弃
+type limitedWriter struct {
+	buf       *bytes.Buffer
+	remaining int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.remaining > 0 {
+		n := len(p)
+		if n > w.remaining {
+			n = w.remaining
+		}
+		w.buf.Write(p[:n])
+		w.remaining -= n
+	}
+	return len(p), nil
+}
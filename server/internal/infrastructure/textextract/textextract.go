@@ -0,0 +1,53 @@
+package textextract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/textextract"
+)
+
+// 提取引擎
+const (
+	EngineNone      = "none"
+	EngineTika      = "tika"
+	EngineTesseract = "tesseract"
+)
+
+// maxOutputBytes 单个文件提取结果的读取上限
+const maxOutputBytes = 16 << 20
+
+// New 根据配置创建提取引擎；未配置引擎时返回 nil
+func New(cfg config.TextExtractionConfig) (textextract.Extractor, error) {
+	switch cfg.Engine {
+	case "", EngineNone:
+		return nil, nil
+	case EngineTika:
+		return NewTika(cfg.TikaURL, cfg.Timeout)
+	case EngineTesseract:
+		path := cfg.TesseractPath
+		if path == "" {
+			path = "tesseract"
+		}
+		return NewTesseract(path, cfg.PdfToTextPath), nil
+	default:
+		return nil, fmt.Errorf("unsupported text extraction engine: %s", cfg.Engine)
+	}
+}
+
+// imageTypes OCR 可识别的图片类型
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/tiff": true,
+	"image/bmp":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// baseType 去除 MIME 类型中的参数
+func baseType(mimeType string) string {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,95 @@
+package textextract
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+func TestTikaExtract(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut || r.URL.Path != "/tika" {
+			t.Errorf("请求 = %s %s", r.Method, r.URL.Path)
+		}
+		switch string(body) {
+		case "%PDF-scan":
+			if r.Header.Get("Content-Type") != "application/pdf" || r.Header.Get("X-Tika-PDFOcrStrategy") != "auto" ||
+				r.Header.Get("X-Tika-OCRLanguage") != "eng+chi_sim" {
+				t.Errorf("请求头 = %v", r.Header)
+			}
+			w.Write([]byte("发票\n金额 100"))
+		case "broken":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("overloaded"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	extractor, err := New(config.TextExtractionConfig{Engine: EngineTika, TikaURL: srv.URL + "/", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !extractor.Supports("application/pdf") || !extractor.Supports("image/png") || extractor.Supports("video/mp4") {
+		t.Error("支持的类型判断错误")
+	}
+
+	text, err := extractor.Extract(context.Background(), strings.NewReader("%PDF-scan"), "application/pdf; charset=binary", []string{"eng", "chi_sim"})
+	if err != nil || text != "发票\n金额 100" {
+		t.Errorf("提取结果 = %q, %v", text, err)
+	}
+	if _, err := extractor.Extract(context.Background(), strings.NewReader("broken"), "application/pdf", nil); err == nil || !aifield.IsPermanent(err) {
+		t.Errorf("无法解析的文件应返回不可重试错误: %v", err)
+	}
+	if _, err := extractor.Extract(context.Background(), strings.NewReader("x"), "image/png", nil); err == nil || aifield.IsPermanent(err) {
+		t.Errorf("服务不可用应可重试: %v", err)
+	}
+}
+
+func TestTesseractExtract(t *testing.T) {
+	dir := t.TempDir()
+	// 假的 tesseract：输出参数与标准输入；输入为 bad 时以非零状态退出
+	script := filepath.Join(dir, "tesseract")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ninput=$(cat)\n[ \"$input\" = bad ] && { echo 'cannot read image' >&2; exit 1; }\necho \"$@\"\necho \"$input\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	extractor, err := New(config.TextExtractionConfig{Engine: EngineTesseract, TesseractPath: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extractor.Supports("application/pdf") {
+		t.Error("未配置 pdftotext 时不应处理 PDF")
+	}
+	text, err := extractor.Extract(context.Background(), strings.NewReader("图片内容"), "image/png", []string{"chi_sim"})
+	if err != nil || text != "stdin stdout -l chi_sim\n图片内容\n" {
+		t.Errorf("提取结果 = %q, %v", text, err)
+	}
+	_, err = extractor.Extract(context.Background(), strings.NewReader("bad"), "image/png", nil)
+	if err == nil || !aifield.IsPermanent(err) || !strings.Contains(err.Error(), "cannot read image") {
+		t.Errorf("无法识别的图片应返回不可重试错误: %v", err)
+	}
+}
+
+func TestNewEngines(t *testing.T) {
+	if extractor, err := New(config.TextExtractionConfig{Engine: EngineNone}); extractor != nil || err != nil {
+		t.Errorf("none 应返回 nil: %v, %v", extractor, err)
+	}
+	if _, err := New(config.TextExtractionConfig{Engine: EngineTika, TikaURL: "ftp://tika"}); err == nil {
+		t.Error("无效的 Tika 地址应返回错误")
+	}
+	if _, err := New(config.TextExtractionConfig{Engine: "abbyy"}); err == nil {
+		t.Error("不支持的引擎应返回错误")
+	}
+}
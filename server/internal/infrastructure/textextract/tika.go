@@ -0,0 +1,91 @@
+package textextract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/aifield"
+)
+
+// tikaTypes Tika 可提取文本的文档类型（图片由 Tika 调用 Tesseract 识别）
+var tikaTypes = map[string]bool{
+	"application/pdf":               true,
+	"application/rtf":               true,
+	"application/msword":            true,
+	"application/vnd.ms-excel":      true,
+	"application/vnd.ms-powerpoint": true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         true,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": true,
+	"application/vnd.oasis.opendocument.text":                                   true,
+	"application/vnd.oasis.opendocument.spreadsheet":                            true,
+	"application/vnd.oasis.opendocument.presentation":                           true,
+}
+
+// Tika Apache Tika Server 客户端（PUT /tika 返回纯文本）
+type Tika struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewTika 创建 Tika 客户端，serverURL 形如 http://127.0.0.1:9998
+func NewTika(serverURL string, timeout time.Duration) (*Tika, error) {
+	u, err := url.Parse(strings.TrimRight(serverURL, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid tika url: %q", serverURL)
+	}
+	return &Tika{endpoint: u.String() + "/tika", client: &http.Client{Timeout: timeout}}, nil
+}
+
+// Name 引擎名称
+func (t *Tika) Name() string {
+	return EngineTika
+}
+
+// Supports PDF、Office 文档、纯文本与常见图片
+func (t *Tika) Supports(mimeType string) bool {
+	mediaType := baseType(mimeType)
+	return tikaTypes[mediaType] || imageTypes[mediaType] || strings.HasPrefix(mediaType, "text/")
+}
+
+// Extract 上传文件内容并取回纯文本
+// PDF 使用 auto 策略：有文本层的页面直接提取，没有文本层的扫描页经 OCR 识别
+func (t *Tika) Extract(ctx context.Context, content io.Reader, mimeType string, languages []string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, t.endpoint, content)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "text/plain; charset=UTF-8")
+	req.Header.Set("Content-Type", baseType(mimeType))
+	if len(languages) > 0 {
+		req.Header.Set("X-Tika-OCRLanguage", strings.Join(languages, "+"))
+	}
+	if baseType(mimeType) == "application/pdf" {
+		req.Header.Set("X-Tika-PDFOcrStrategy", "auto")
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("tika request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOutputBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read tika response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent:
+		return string(body), nil
+	case resp.StatusCode == http.StatusUnsupportedMediaType || resp.StatusCode == http.StatusUnprocessableEntity:
+		// 格式不支持、文件损坏或加密
+		return "", aifield.Permanent(fmt.Errorf("tika cannot parse %s: status %d", baseType(mimeType), resp.StatusCode))
+	default:
+		return "", fmt.Errorf("tika error: status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,269 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+)
+
+const (
+	bigQueryEndpoint = "https://bigquery.googleapis.com/bigquery/v2"
+	bigQueryScope    = "https://www.googleapis.com/auth/bigquery"
+	bigQueryTokenURI = "https://oauth2.googleapis.com/token"
+)
+
+// bigQueryDialect BigQuery 标准 SQL
+type bigQueryDialect struct {
+	dest warehousesync.Destination
+}
+
+func (d bigQueryDialect) quote(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "") + "`"
+}
+
+func (d bigQueryDialect) table() string {
+	return d.quote(d.dest.Project + "." + d.dest.Dataset + "." + d.dest.Table)
+}
+
+func (d bigQueryDialect) typeName(t ColumnType) string {
+	switch t {
+	case TypeInt64:
+		return "INT64"
+	case TypeFloat64:
+		return "FLOAT64"
+	case TypeBool:
+		return "BOOL"
+	case TypeTimestamp:
+		return "TIMESTAMP"
+	default:
+		return "STRING"
+	}
+}
+
+func (d bigQueryDialect) jsonRows(alias string) string {
+	return "UNNEST(JSON_QUERY_ARRAY(?)) AS " + alias
+}
+
+func (d bigQueryDialect) extract(alias, column string, t ColumnType) string {
+	value := fmt.Sprintf(`JSON_VALUE(%s, '$."%s"')`, alias, strings.ReplaceAll(column, `"`, ""))
+	if t == TypeString {
+		return value
+	}
+	return fmt.Sprintf("SAFE_CAST(%s AS %s)", value, d.typeName(t))
+}
+
+func (d bigQueryDialect) timestampParam() string {
+	return "CAST(? AS TIMESTAMP)"
+}
+
+func (d bigQueryDialect) existingColumns() (string, []string) {
+	return fmt.Sprintf("SELECT column_name FROM %s.INFORMATION_SCHEMA.COLUMNS WHERE table_name = ?",
+		d.quote(d.dest.Project+"."+d.dest.Dataset)), []string{d.dest.Table}
+}
+
+// bigQueryServiceAccount 服务账号 JSON 密钥中使用的字段
+type bigQueryServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// bigQueryExecutor 通过 jobs.query REST 接口执行 SQL
+type bigQueryExecutor struct {
+	project string
+	account bigQueryServiceAccount
+	client  *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+func newBigQueryExecutor(dest warehousesync.Destination, credentials string, client *http.Client) (*bigQueryExecutor, error) {
+	if dest.Project == "" || dest.Dataset == "" {
+		return nil, fmt.Errorf("bigquery destination requires project and dataset")
+	}
+	var account bigQueryServiceAccount
+	if err := json.Unmarshal([]byte(credentials), &account); err != nil || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("invalid bigquery service account key")
+	}
+	// token_uri 来自用户提供的密钥，只允许 Google 的令牌端点，避免向任意地址发送请求
+	switch account.TokenURI {
+	case "":
+		account.TokenURI = bigQueryTokenURI
+	case bigQueryTokenURI:
+	default:
+		return nil, fmt.Errorf("unsupported bigquery token_uri: %s", account.TokenURI)
+	}
+	return &bigQueryExecutor{project: dest.Project, account: account, client: client}, nil
+}
+
+type bigQueryResponse struct {
+	JobComplete  bool `json:"jobComplete"`
+	JobReference struct {
+		JobID    string `json:"jobId"`
+		Location string `json:"location"`
+	} `json:"jobReference"`
+	Rows []struct {
+		F []struct {
+			V interface{} `json:"v"`
+		} `json:"f"`
+	} `json:"rows"`
+	NumDMLAffectedRows string `json:"numDmlAffectedRows"`
+	Errors             []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (e *bigQueryExecutor) query(ctx context.Context, sql string, args ...string) (*result, error) {
+	params := make([]map[string]interface{}, 0, len(args))
+	for _, arg := range args {
+		params = append(params, map[string]interface{}{
+			"parameterType":  map[string]string{"type": "STRING"},
+			"parameterValue": map[string]string{"value": arg},
+		})
+	}
+	body := map[string]interface{}{
+		"query":           sql,
+		"useLegacySql":    false,
+		"parameterMode":   "POSITIONAL",
+		"queryParameters": params,
+		"timeoutMs":       60000,
+	}
+
+	var resp bigQueryResponse
+	if err := e.call(ctx, http.MethodPost, fmt.Sprintf("%s/projects/%s/queries", bigQueryEndpoint, url.PathEscape(e.project)), body, &resp); err != nil {
+		return nil, err
+	}
+	// 未在超时内完成时轮询结果
+	for !resp.JobComplete {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+		endpoint := fmt.Sprintf("%s/projects/%s/queries/%s?location=%s", bigQueryEndpoint,
+			url.PathEscape(e.project), url.PathEscape(resp.JobReference.JobID), url.QueryEscape(resp.JobReference.Location))
+		if err := e.call(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+			return nil, err
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("bigquery: %s", resp.Errors[0].Message)
+	}
+
+	res := &result{}
+	res.Affected, _ = strconv.ParseInt(resp.NumDMLAffectedRows, 10, 64)
+	for _, row := range resp.Rows {
+		values := make([]string, 0, len(row.F))
+		for _, cell := range row.F {
+			values = append(values, fmt.Sprint(cell.V))
+		}
+		res.Rows = append(res.Rows, values)
+	}
+	return res, nil
+}
+
+func (e *bigQueryExecutor) call(ctx context.Context, method, endpoint string, body interface{}, out interface{}) error {
+	token, err := e.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return doJSON(e.client, req, out)
+}
+
+// accessToken 以服务账号签名的 JWT 换取访问令牌（缓存至过期前一分钟）
+func (e *bigQueryExecutor) accessToken(ctx context.Context) (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.token != "" && time.Now().Before(e.tokenExpiry) {
+		return e.token, nil
+	}
+
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(e.account.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid bigquery private key: %w", err)
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   e.account.ClientEmail,
+		"scope": bigQueryScope,
+		"aud":   e.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign bigquery assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSON(e.client, req, &token); err != nil {
+		return "", fmt.Errorf("failed to obtain bigquery access token: %w", err)
+	}
+	e.token = token.AccessToken
+	e.tokenExpiry = now.Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return e.token, nil
+}
+
+// doJSON 发送请求并解析 JSON 响应，非 2xx 响应返回响应体摘要
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := string(data)
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		return fmt.Errorf("status %d: %s", resp.StatusCode, msg)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
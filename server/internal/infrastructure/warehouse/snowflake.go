@@ -0,0 +1,206 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+)
+
+// snowflakeDialect Snowflake SQL
+type snowflakeDialect struct {
+	dest warehousesync.Destination
+}
+
+func (d snowflakeDialect) quote(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+func (d snowflakeDialect) table() string {
+	return d.quote(d.dest.Database) + "." + d.quote(d.dest.Schema) + "." + d.quote(d.dest.Table)
+}
+
+func (d snowflakeDialect) typeName(t ColumnType) string {
+	switch t {
+	case TypeInt64:
+		return "NUMBER(38,0)"
+	case TypeFloat64:
+		return "FLOAT"
+	case TypeBool:
+		return "BOOLEAN"
+	case TypeTimestamp:
+		return "TIMESTAMP_TZ"
+	default:
+		return "VARCHAR"
+	}
+}
+
+func (d snowflakeDialect) jsonRows(alias string) string {
+	return "TABLE(FLATTEN(INPUT => PARSE_JSON(?))) AS " + alias
+}
+
+func (d snowflakeDialect) extract(alias, column string, t ColumnType) string {
+	value := fmt.Sprintf(`%s.value:%s::VARCHAR`, alias, d.quote(column))
+	switch t {
+	case TypeInt64:
+		return "TRY_TO_NUMBER(" + value + ")"
+	case TypeFloat64:
+		return "TRY_TO_DOUBLE(" + value + ")"
+	case TypeBool:
+		return "TRY_TO_BOOLEAN(" + value + ")"
+	case TypeTimestamp:
+		return "TRY_TO_TIMESTAMP_TZ(" + value + ")"
+	default:
+		return value
+	}
+}
+
+func (d snowflakeDialect) timestampParam() string {
+	return "TO_TIMESTAMP_TZ(?)"
+}
+
+func (d snowflakeDialect) existingColumns() (string, []string) {
+	return fmt.Sprintf("SELECT column_name FROM %s.information_schema.columns WHERE table_schema = ? AND table_name = ?",
+		d.quote(d.dest.Database)), []string{d.dest.Schema, d.dest.Table}
+}
+
+// snowflakeExecutor 通过 SQL API（/api/v2/statements）执行 SQL，使用密钥对 JWT 认证
+type snowflakeExecutor struct {
+	dest     warehousesync.Destination
+	endpoint string
+	key      *rsa.PrivateKey
+	issuer   string
+	subject  string
+	client   *http.Client
+}
+
+func newSnowflakeExecutor(dest warehousesync.Destination, credentials string, client *http.Client) (*snowflakeExecutor, error) {
+	if dest.Account == "" || dest.User == "" || dest.Database == "" || dest.Schema == "" {
+		return nil, fmt.Errorf("snowflake destination requires account, user, database and schema")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(credentials))
+	if err != nil {
+		return nil, fmt.Errorf("invalid snowflake private key: %w", err)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snowflake private key: %w", err)
+	}
+	fingerprint := sha256.Sum256(publicKey)
+
+	// 账号标识中的组织分隔符 "." 在 JWT 中需写作 "-"
+	account := strings.ToUpper(strings.ReplaceAll(strings.SplitN(dest.Account, ".snowflakecomputing.com", 2)[0], ".", "-"))
+	user := strings.ToUpper(dest.User)
+	return &snowflakeExecutor{
+		dest:     dest,
+		endpoint: "https://" + strings.ToLower(account) + ".snowflakecomputing.com/api/v2/statements",
+		key:      key,
+		issuer:   account + "." + user + ".SHA256:" + base64.StdEncoding.EncodeToString(fingerprint[:]),
+		subject:  account + "." + user,
+		client:   client,
+	}, nil
+}
+
+type snowflakeResponse struct {
+	Code               string     `json:"code"`
+	Message            string     `json:"message"`
+	StatementHandle    string     `json:"statementHandle"`
+	StatementStatusURL string     `json:"statementStatusUrl"`
+	Data               [][]string `json:"data"`
+	Stats              struct {
+		NumRowsInserted int64 `json:"numRowsInserted"`
+		NumRowsUpdated  int64 `json:"numRowsUpdated"`
+	} `json:"stats"`
+}
+
+func (e *snowflakeExecutor) query(ctx context.Context, sql string, args ...string) (*result, error) {
+	bindings := make(map[string]interface{}, len(args))
+	for i, arg := range args {
+		bindings[strconv.Itoa(i+1)] = map[string]string{"type": "TEXT", "value": arg}
+	}
+	body := map[string]interface{}{
+		"statement": sql,
+		"timeout":   60,
+		"database":  e.dest.Database,
+		"schema":    e.dest.Schema,
+	}
+	if len(bindings) > 0 {
+		body["bindings"] = bindings
+	}
+	if e.dest.Warehouse != "" {
+		body["warehouse"] = e.dest.Warehouse
+	}
+	if e.dest.Role != "" {
+		body["role"] = e.dest.Role
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp snowflakeResponse
+	if err := e.call(ctx, http.MethodPost, e.endpoint, data, &resp); err != nil {
+		return nil, err
+	}
+	// 执行中（code 333334）时按句柄轮询结果
+	for resp.Code == "333334" {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+		if err := e.call(ctx, http.MethodGet, e.endpoint+"/"+url.PathEscape(resp.StatementHandle), nil, &resp); err != nil {
+			return nil, err
+		}
+	}
+	return &result{
+		Rows:     resp.Data,
+		Affected: resp.Stats.NumRowsInserted + resp.Stats.NumRowsUpdated,
+	}, nil
+}
+
+func (e *snowflakeExecutor) call(ctx context.Context, method, endpoint string, body []byte, out *snowflakeResponse) error {
+	token, err := e.sign()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Snowflake-Authorization-Token-Type", "KEYPAIR_JWT")
+	req.Header.Set("Content-Type", "application/json")
+	if err := doJSON(e.client, req, out); err != nil {
+		return fmt.Errorf("snowflake: %w", err)
+	}
+	return nil
+}
+
+// sign 生成密钥对认证 JWT（有效期不超过一小时）
+func (e *snowflakeExecutor) sign() (string, error) {
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": e.issuer,
+		"sub": e.subject,
+		"iat": now.Unix(),
+		"exp": now.Add(59 * time.Minute).Unix(),
+	}).SignedString(e.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign snowflake token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,180 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dialect 各仓库的 SQL 方言差异
+// 行数据以一个 JSON 数组字符串参数传入，由方言展开为每行一个 JSON 元素
+type dialect interface {
+	quote(identifier string) string
+	table() string
+	typeName(t ColumnType) string
+	// jsonRows 将 JSON 数组参数展开为行，每行的元素以 alias 引用
+	jsonRows(alias string) string
+	// extract 从行元素中按列类型取值（无法转换时为 NULL）
+	extract(alias, column string, t ColumnType) string
+	// timestampParam 时间参数（以字符串传入）
+	timestampParam() string
+	// existingColumns 查询目标表已有列名的 SQL
+	existingColumns() (string, []string)
+}
+
+// executor 执行 SQL，参数均以字符串按位置绑定
+type executor interface {
+	query(ctx context.Context, sql string, args ...string) (*result, error)
+}
+
+// result 执行结果
+type result struct {
+	Rows     [][]string
+	Affected int64
+}
+
+// sqlClient 基于方言的通用客户端
+type sqlClient struct {
+	dialect dialect
+	exec    executor
+}
+
+// EnsureTable 创建目标表或追加缺少的列
+func (c *sqlClient) EnsureTable(ctx context.Context, columns []Column) ([]string, error) {
+	columns = withSystemColumns(columns)
+
+	sql, args := c.dialect.existingColumns()
+	res, err := c.exec.query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read warehouse columns: %w", err)
+	}
+	existing := make(map[string]bool, len(res.Rows))
+	for _, row := range res.Rows {
+		if len(row) > 0 {
+			existing[strings.ToLower(row[0])] = true
+		}
+	}
+
+	if len(existing) == 0 {
+		defs := make([]string, 0, len(columns))
+		for _, col := range columns {
+			defs = append(defs, c.dialect.quote(col.Name)+" "+c.dialect.typeName(col.Type))
+		}
+		stmt := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", c.dialect.table(), strings.Join(defs, ", "))
+		if _, err := c.exec.query(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to create warehouse table: %w", err)
+		}
+		return nil, nil
+	}
+
+	var added []string
+	for _, col := range columns {
+		if existing[strings.ToLower(col.Name)] {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s",
+			c.dialect.table(), c.dialect.quote(col.Name), c.dialect.typeName(col.Type))
+		if _, err := c.exec.query(ctx, stmt); err != nil {
+			return added, fmt.Errorf("failed to add warehouse column %s: %w", col.Name, err)
+		}
+		added = append(added, col.Name)
+	}
+	return added, nil
+}
+
+// Upsert 按 record_id 合并写入
+func (c *sqlClient) Upsert(ctx context.Context, columns []Column, rows []Row, syncedAt time.Time) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("failed to encode warehouse rows: %w", err)
+	}
+	// 更新与插入分支各引用一次写入时间
+	at := syncedAt.UTC().Format(time.RFC3339Nano)
+	if _, err := c.exec.query(ctx, c.mergeSQL(columns), string(payload), at, at); err != nil {
+		return fmt.Errorf("failed to merge warehouse rows: %w", err)
+	}
+	return nil
+}
+
+// mergeSQL 生成合并语句（参数依次为：行 JSON 数组、更新时间、插入时间）
+func (c *sqlClient) mergeSQL(columns []Column) string {
+	d := c.dialect
+	selects := make([]string, 0, len(columns)+1)
+	names := make([]string, 0, len(columns)+2)
+	values := make([]string, 0, len(columns)+2)
+	sets := make([]string, 0, len(columns)+2)
+	selects = append(selects, d.extract("r", ColumnRecordID, TypeString)+" AS "+d.quote(ColumnRecordID))
+	for _, col := range columns {
+		q := d.quote(col.Name)
+		selects = append(selects, d.extract("r", col.Name, col.Type)+" AS "+q)
+		names = append(names, q)
+		values = append(values, "S."+q)
+		sets = append(sets, q+" = S."+q)
+	}
+	deleted, synced := d.quote(ColumnDeleted), d.quote(ColumnSyncedAt)
+	names = append(names, d.quote(ColumnRecordID), deleted, synced)
+	values = append(values, "S."+d.quote(ColumnRecordID), "FALSE", d.timestampParam())
+	sets = append(sets, deleted+" = FALSE", synced+" = "+d.timestampParam())
+
+	return fmt.Sprintf(
+		"MERGE INTO %s AS T USING (SELECT %s FROM %s) AS S ON T.%s = S.%s "+
+			"WHEN MATCHED THEN UPDATE SET %s "+
+			"WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)",
+		d.table(), strings.Join(selects, ", "), d.jsonRows("r"),
+		d.quote(ColumnRecordID), d.quote(ColumnRecordID),
+		strings.Join(sets, ", "),
+		strings.Join(names, ", "), strings.Join(values, ", "),
+	)
+}
+
+// MarkDeleted 将指定记录标记为软删除
+func (c *sqlClient) MarkDeleted(ctx context.Context, recordIDs []string, at time.Time) (int64, error) {
+	if len(recordIDs) == 0 {
+		return 0, nil
+	}
+	rows := make([]Row, 0, len(recordIDs))
+	for _, id := range recordIDs {
+		rows = append(rows, Row{ColumnRecordID: id})
+	}
+	payload, err := json.Marshal(rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode warehouse ids: %w", err)
+	}
+	d := c.dialect
+	stmt := fmt.Sprintf("UPDATE %s SET %s = TRUE, %s = %s WHERE %s IN (SELECT %s FROM %s)",
+		d.table(), d.quote(ColumnDeleted), d.quote(ColumnSyncedAt), d.timestampParam(),
+		d.quote(ColumnRecordID), d.extract("r", ColumnRecordID, TypeString), d.jsonRows("r"))
+	res, err := c.exec.query(ctx, stmt, at.UTC().Format(time.RFC3339Nano), string(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark warehouse rows deleted: %w", err)
+	}
+	return res.Affected, nil
+}
+
+// MarkStale 将本次全量镜像未写入的记录标记为软删除
+func (c *sqlClient) MarkStale(ctx context.Context, syncedAt time.Time) (int64, error) {
+	d := c.dialect
+	stmt := fmt.Sprintf("UPDATE %s SET %s = TRUE WHERE %s = FALSE AND %s < %s",
+		d.table(), d.quote(ColumnDeleted), d.quote(ColumnDeleted), d.quote(ColumnSyncedAt), d.timestampParam())
+	res, err := c.exec.query(ctx, stmt, syncedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark stale warehouse rows: %w", err)
+	}
+	return res.Affected, nil
+}
+
+// withSystemColumns 在字段列前追加固定列
+func withSystemColumns(columns []Column) []Column {
+	all := make([]Column, 0, len(columns)+3)
+	all = append(all,
+		Column{Name: ColumnRecordID, Type: TypeString},
+		Column{Name: ColumnDeleted, Type: TypeBool},
+		Column{Name: ColumnSyncedAt, Type: TypeTimestamp},
+	)
+	return append(all, columns...)
+}
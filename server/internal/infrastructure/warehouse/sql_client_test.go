@@ -0,0 +1,83 @@
+package warehouse
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+)
+
+// recordingExecutor 记录执行的语句与参数
+type recordingExecutor struct {
+	sql    []string
+	args   [][]string
+	result *result
+}
+
+func (e *recordingExecutor) query(ctx context.Context, sql string, args ...string) (*result, error) {
+	e.sql = append(e.sql, sql)
+	e.args = append(e.args, args)
+	if e.result != nil {
+		return e.result, nil
+	}
+	return &result{}, nil
+}
+
+func TestEnsureTableAddsOnlyMissingColumns(t *testing.T) {
+	exec := &recordingExecutor{result: &result{Rows: [][]string{{"RECORD_ID"}, {"_deleted"}, {"_synced_at"}, {"fld_a"}}}}
+	client := &sqlClient{dialect: snowflakeDialect{dest: warehousesync.Destination{Database: "DB", Schema: "PUBLIC", Table: "t"}}, exec: exec}
+
+	added, err := client.EnsureTable(context.Background(), []Column{
+		{Name: "fld_a", Type: TypeString},
+		{Name: "fld_b", Type: TypeFloat64},
+	})
+	if err != nil {
+		t.Fatalf("EnsureTable 失败: %v", err)
+	}
+	if len(added) != 1 || added[0] != "fld_b" {
+		t.Fatalf("应只追加 fld_b，得到 %v", added)
+	}
+	last := exec.sql[len(exec.sql)-1]
+	if !strings.Contains(last, `ADD COLUMN IF NOT EXISTS "fld_b" FLOAT`) {
+		t.Errorf("追加列语句不正确: %s", last)
+	}
+}
+
+func TestMergeSQLBindsTimestampForBothBranches(t *testing.T) {
+	exec := &recordingExecutor{}
+	client := &sqlClient{dialect: bigQueryDialect{dest: warehousesync.Destination{Project: "p", Dataset: "d", Table: "t"}}, exec: exec}
+
+	err := client.Upsert(context.Background(), []Column{{Name: "fld_n", Type: TypeInt64}},
+		[]Row{{ColumnRecordID: "rec1", "fld_n": 3}}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("Upsert 失败: %v", err)
+	}
+	stmt := exec.sql[0]
+	if got, want := strings.Count(stmt, "?"), len(exec.args[0]); got != want {
+		t.Fatalf("占位符 %d 个，参数 %d 个", got, want)
+	}
+	if !strings.Contains(stmt, "SAFE_CAST(JSON_VALUE(r, '$.\"fld_n\"') AS INT64)") {
+		t.Errorf("数值列应安全转换: %s", stmt)
+	}
+	if !strings.HasPrefix(stmt, "MERGE INTO `p.d.t` AS T") {
+		t.Errorf("目标表引用不正确: %s", stmt)
+	}
+}
+
+func TestBigQueryRejectsCustomTokenURI(t *testing.T) {
+	dest := warehousesync.Destination{Type: warehousesync.DestinationBigQuery, Project: "p", Dataset: "d", Table: "t"}
+
+	exec, err := newBigQueryExecutor(dest, `{"client_email":"sa@p.iam.gserviceaccount.com","private_key":"key"}`, nil)
+	if err != nil || exec.account.TokenURI != bigQueryTokenURI {
+		t.Fatalf("未指定 token_uri 时应使用 Google 令牌端点，得到 %+v, %v", exec, err)
+	}
+
+	for _, uri := range []string{"http://169.254.169.254/computeMetadata/v1/", "https://attacker.example/token"} {
+		credentials := `{"client_email":"sa@p.iam.gserviceaccount.com","private_key":"key","token_uri":"` + uri + `"}`
+		if _, err := newBigQueryExecutor(dest, credentials, nil); err == nil {
+			t.Errorf("token_uri %s 应被拒绝", uri)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package warehouse
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/warehousesync"
+)
+
+// ColumnType 目标列类型（各仓库映射为各自的类型名）
+type ColumnType string
+
+const (
+	TypeString    ColumnType = "string"
+	TypeInt64     ColumnType = "int64"
+	TypeFloat64   ColumnType = "float64"
+	TypeBool      ColumnType = "bool"
+	TypeTimestamp ColumnType = "timestamp"
+)
+
+// 目标表的固定列
+const (
+	ColumnRecordID = "record_id"  // 记录ID（合并键）
+	ColumnDeleted  = "_deleted"   // 软删除标记
+	ColumnSyncedAt = "_synced_at" // 最近一次写入时间
+)
+
+// Column 目标列
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+// Row 一行数据（列名 -> 值），时间值为 RFC3339 字符串
+type Row map[string]interface{}
+
+// Client 数据仓库客户端
+// 所有写入均以 record_id 为键合并（MERGE），重复写入同一批数据结果不变
+type Client interface {
+	// EnsureTable 创建目标表或追加缺少的列（不删除、不修改已有列），返回追加的列名
+	EnsureTable(ctx context.Context, columns []Column) ([]string, error)
+	// Upsert 按 record_id 合并写入，并清除软删除标记
+	Upsert(ctx context.Context, columns []Column, rows []Row, syncedAt time.Time) error
+	// MarkDeleted 将指定记录标记为软删除
+	MarkDeleted(ctx context.Context, recordIDs []string, at time.Time) (int64, error)
+	// MarkStale 将 syncedAt 之前未被写入的记录标记为软删除（全量镜像后调用）
+	MarkStale(ctx context.Context, syncedAt time.Time) (int64, error)
+}
+
+// New 根据目标类型创建客户端
+// credentials：BigQuery 为服务账号 JSON 密钥，Snowflake 为 PKCS#8 PEM 私钥（密钥对认证）
+func New(destination warehousesync.Destination, credentials string, httpClient *http.Client) (Client, error) {
+	if destination.Table == "" {
+		return nil, fmt.Errorf("destination table is required")
+	}
+	switch destination.Type {
+	case warehousesync.DestinationBigQuery:
+		exec, err := newBigQueryExecutor(destination, credentials, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return &sqlClient{dialect: bigQueryDialect{dest: destination}, exec: exec}, nil
+	case warehousesync.DestinationSnowflake:
+		exec, err := newSnowflakeExecutor(destination, credentials, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return &sqlClient{dialect: snowflakeDialect{dest: destination}, exec: exec}, nil
+	default:
+		return nil, fmt.Errorf("unsupported warehouse type: %s", destination.Type)
+	}
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/adminconsole"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// AdminConsoleHandler 管理控制台处理器
+type AdminConsoleHandler struct {
+	service *application.AdminConsoleService
+}
+
+// NewAdminConsoleHandler 创建管理控制台处理器
+func NewAdminConsoleHandler(service *application.AdminConsoleService) *AdminConsoleHandler {
+	return &AdminConsoleHandler{service: service}
+}
+
+// consoleClient 请求的来源（客户端 IP 只采用 server.trusted_proxies 中代理转发的地址，见 setupRouter）
+func consoleClient(c *gin.Context) application.ConsoleClient {
+	return application.ConsoleClient{IPAddress: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// consoleSession 当前控制台会话（由 AdminConsoleAuthMiddleware 设置）
+func consoleSession(c *gin.Context) *adminconsole.Session {
+	if v, ok := c.Get("console_session"); ok {
+		if session, ok := v.(*adminconsole.Session); ok {
+			return session
+		}
+	}
+	return nil
+}
+
+// Login 管理员登录控制台（令牌明文只在响应中返回一次）
+// POST /api/v1/console/auth/login
+func (h *AdminConsoleHandler) Login(c *gin.Context) {
+	var req application.ConsoleLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.service.Login(c.Request.Context(), req, consoleClient(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result, "登录成功")
+}
+
+// GetSession 当前控制台会话
+// GET /api/v1/console/auth/session
+func (h *AdminConsoleHandler) GetSession(c *gin.Context) {
+	response.Success(c, consoleSession(c), "获取成功")
+}
+
+// Logout 注销控制台会话
+// POST /api/v1/console/auth/logout
+func (h *AdminConsoleHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(c.Request.Context(), consoleSession(c)); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil, "已退出控制台")
+}
+
+// ListSpaces 跨工作空间检索
+// GET /api/v1/console/spaces
+func (h *AdminConsoleHandler) ListSpaces(c *gin.Context) {
+	var req application.ListConsoleSpacesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.service.ListSpaces(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result, "获取成功")
+}
+
+// GetSpaceUsage 工作空间的套餐与资源用量
+// GET /api/v1/console/spaces/:spaceId/usage
+func (h *AdminConsoleHandler) GetSpaceUsage(c *gin.Context) {
+	summary, err := h.service.GetSpaceUsage(c.Request.Context(), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, summary, "获取成功")
+}
+
+// TransferSpaceOwnership 转让工作空间所有权
+// POST /api/v1/console/spaces/:spaceId/transfer-ownership
+func (h *AdminConsoleHandler) TransferSpaceOwnership(c *gin.Context) {
+	var req application.TransferSpaceOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.service.TransferSpaceOwnership(c.Request.Context(), consoleSession(c), c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result, "所有权已转让")
+}
+
+// ListUsers 检索用户
+// GET /api/v1/console/users
+func (h *AdminConsoleHandler) ListUsers(c *gin.Context) {
+	var req application.ListConsoleUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.service.ListUsers(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result, "获取成功")
+}
+
+// ResetUserPassword 重置用户密码（未指定密码时生成临时密码，只在响应中返回一次）
+// POST /api/v1/console/users/:userId/reset-password
+func (h *AdminConsoleHandler) ResetUserPassword(c *gin.Context) {
+	var req application.ResetUserPasswordRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	result, err := h.service.ResetUserPassword(c.Request.Context(), consoleSession(c), c.Param("userId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, result, "密码已重置")
+}
+
+// GetStats 实例统计
+// GET /api/v1/console/stats
+func (h *AdminConsoleHandler) GetStats(c *gin.Context) {
+	stats, err := h.service.Stats(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, stats, "获取成功")
+}
+
+// ListFeatures 功能开关的生效定义及控制台覆盖
+// GET /api/v1/console/features
+func (h *AdminConsoleHandler) ListFeatures(c *gin.Context) {
+	features, err := h.service.ListFeatures(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, features, "获取成功")
+}
+
+// SetFeature 覆盖功能开关
+// PUT /api/v1/console/features/:key
+func (h *AdminConsoleHandler) SetFeature(c *gin.Context) {
+	var req application.SetFeatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	override, err := h.service.SetFeature(c.Request.Context(), consoleSession(c), c.Param("key"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, override, "功能开关已更新")
+}
+
+// ClearFeature 删除功能开关覆盖，恢复为配置文件的定义
+// DELETE /api/v1/console/features/:key
+func (h *AdminConsoleHandler) ClearFeature(c *gin.Context) {
+	if err := h.service.ClearFeature(c.Request.Context(), consoleSession(c), c.Param("key")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil, "已恢复为配置文件的定义")
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// AIFieldHandler AI 字段生成与配额HTTP处理器
+type AIFieldHandler struct {
+	aiFieldService *application.AIFieldService
+}
+
+// NewAIFieldHandler 创建 AI 字段处理器
+func NewAIFieldHandler(aiFieldService *application.AIFieldService) *AIFieldHandler {
+	return &AIFieldHandler{
+		aiFieldService: aiFieldService,
+	}
+}
+
+// Regenerate 重新生成 AI 字段（不指定记录时重新生成整列）
+// POST /api/v1/fields/:fieldId/ai/regenerate
+func (h *AIFieldHandler) Regenerate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RegenerateAIFieldRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	result, err := h.aiFieldService.Regenerate(c.Request.Context(), userID, c.Param("fieldId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "已提交重新生成")
+}
+
+// ListJobs 列出 AI 字段最近的生成任务
+// GET /api/v1/fields/:fieldId/ai/jobs?recordId=
+func (h *AIFieldHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.aiFieldService.ListJobs(c.Request.Context(), userID, c.Param("fieldId"), c.Query("recordId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取生成任务成功")
+}
+
+// GetQuota 获取空间 AI 配额与本月用量
+// GET /api/v1/spaces/:spaceId/ai-quota
+func (h *AIFieldHandler) GetQuota(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	quota, err := h.aiFieldService.GetQuota(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, quota, "获取 AI 配额成功")
+}
+
+// UpdateQuota 更新空间 AI 配额
+// PUT /api/v1/spaces/:spaceId/ai-quota
+func (h *AIFieldHandler) UpdateQuota(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateAIQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	quota, err := h.aiFieldService.UpdateQuota(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, quota, "更新 AI 配额成功")
+}
@@ -152,6 +152,13 @@ func (h *AttachmentHandler) NotifyUpload(c *gin.Context) {
 // @Param path path string true "文件路径"
 // @Param token query string false "访问令牌"
 // @Param response-content-disposition query string false "响应内容配置"
+// @Param width query int false "派生图宽度"
+// @Param height query int false "派生图高度"
+// @Param fit query string false "缩放方式：contain/cover/fill"
+// @Param rotate query int false "顺时针旋转角度"
+// @Param format query string false "输出格式：jpeg/png/gif"
+// @Param quality query int false "JPEG 质量"
+// @Param strip query bool false "去除 EXIF 等元数据"
 // @Success 200 {file} binary
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
@@ -171,8 +178,20 @@ func (h *AttachmentHandler) ReadFile(c *gin.Context) {
 	token := c.Query("token")
 	responseContentDisposition := c.Query("response-content-disposition")
 
+	// 携带图片参数时返回派生图
+	transform, err := attachment.ParseImageTransform(c.Query)
+	if err != nil {
+		h.handleError(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
 	// 读取文件
-	response, err := h.attachmentService.ReadFile(c.Request.Context(), path, token)
+	var response *attachment.ReadResponse
+	if transform != nil {
+		response, err = h.attachmentService.ReadImage(c.Request.Context(), path, transform)
+	} else {
+		response, err = h.attachmentService.ReadFile(c.Request.Context(), path, token)
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// AttachmentScanHandler 附件策略与扫描HTTP处理器
+type AttachmentScanHandler struct {
+	scanService *application.AttachmentScanService
+}
+
+// NewAttachmentScanHandler 创建附件策略与扫描处理器
+func NewAttachmentScanHandler(scanService *application.AttachmentScanService) *AttachmentScanHandler {
+	return &AttachmentScanHandler{
+		scanService: scanService,
+	}
+}
+
+// GetPolicy 获取空间附件策略
+// GET /api/v1/spaces/:spaceId/attachment-policy
+func (h *AttachmentScanHandler) GetPolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	policy, err := h.scanService.GetPolicy(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "获取附件策略成功")
+}
+
+// UpdatePolicy 更新空间附件策略
+// PUT /api/v1/spaces/:spaceId/attachment-policy
+func (h *AttachmentScanHandler) UpdatePolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateAttachmentPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	policy, err := h.scanService.UpdatePolicy(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "更新附件策略成功")
+}
+
+// Rescan 重新扫描附件
+// POST /api/v1/attachments/:id/rescan
+func (h *AttachmentScanHandler) Rescan(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	item, err := h.scanService.Rescan(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, item, "已重新提交扫描")
+}
@@ -37,6 +37,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.ClientIP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	resp, err := h.authService.Register(c.Request.Context(), req)
 	if err != nil {
@@ -61,6 +63,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.ClientIP = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
 
 	resp, err := h.authService.Login(c.Request.Context(), req)
 	if err != nil {
@@ -81,6 +85,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// 优先从认证中间件获取用户ID
 	userID := c.GetString("user_id")
+	sessionID := ""
+	if claims := contextClaims(c); claims != nil {
+		sessionID = claims.SessionID
+	}
 
 	// 如果没有通过中间件，尝试从 Authorization header 解析 token
 	if userID == "" {
@@ -94,6 +102,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 				claims, err := h.authService.ValidateToken(c.Request.Context(), token)
 				if err == nil && claims != nil {
 					userID = claims.UserID
+					sessionID = claims.SessionID
 				}
 			}
 		}
@@ -107,7 +116,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 
 	// 执行登出操作
-	if err := h.authService.Logout(c.Request.Context(), userID); err != nil {
+	if err := h.authService.Logout(c.Request.Context(), userID, sessionID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -175,3 +184,90 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 
 	response.Success(c, claims, "获取用户信息成功")
 }
+
+// Reauthenticate 重新认证（敏感操作前验证密码）
+// @Summary 重新认证
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ReauthRequest true "重新认证请求"
+// @Success 200 {object} dto.TokenResponse
+// @Router /auth/reauth [post]
+func (h *AuthHandler) Reauthenticate(c *gin.Context) {
+	claims := contextClaims(c)
+	if claims == nil {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	var req dto.ReauthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	resp, err := h.authService.Reauthenticate(c.Request.Context(), claims, req.Password)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "重新认证成功")
+}
+
+// ListSessions 列出当前用户的活跃会话
+// @Summary 列出活跃会话
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} security.Session
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sessions, "获取会话列表成功")
+}
+
+// RevokeSession 吊销当前用户的指定会话
+// @Summary 吊销会话
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "会话ID"
+// @Success 200 {object} gin.H
+// @Router /auth/sessions/{sessionId} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, c.Param("sessionId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "会话已吊销")
+}
+
+// contextClaims 获取认证中间件写入的令牌声明
+func contextClaims(c *gin.Context) *dto.TokenClaims {
+	if v, ok := c.Get("token_claims"); ok {
+		if claims, ok := v.(*dto.TokenClaims); ok {
+			return claims
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// AutomationScriptHandler 自动化运行脚本HTTP处理器
+type AutomationScriptHandler struct {
+	scriptService *application.ScriptActionService
+}
+
+// NewAutomationScriptHandler 创建运行脚本处理器
+func NewAutomationScriptHandler(scriptService *application.ScriptActionService) *AutomationScriptHandler {
+	return &AutomationScriptHandler{
+		scriptService: scriptService,
+	}
+}
+
+// TestRun 试运行脚本，返回结果、输出与日志（脚本出错时 status 为 failed）
+// POST /api/v1/automation-scripts/run
+func (h *AutomationScriptHandler) TestRun(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RunScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.scriptService.TestRun(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "脚本执行完成")
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// BacklinkHandler 记录反向链接HTTP处理器
+type BacklinkHandler struct {
+	backlinkService *application.BacklinkService
+}
+
+// NewBacklinkHandler 创建反向链接处理器
+func NewBacklinkHandler(backlinkService *application.BacklinkService) *BacklinkHandler {
+	return &BacklinkHandler{
+		backlinkService: backlinkService,
+	}
+}
+
+// List 列出在长文本/富文本中提及了该记录的位置
+// GET /api/v1/tables/:tableId/records/:recordId/backlinks
+func (h *BacklinkHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	items, err := h.backlinkService.ListBacklinks(c.Request.Context(), userID, c.Param("tableId"), c.Param("recordId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, items, "获取反向链接成功")
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// BaseBranchHandler Base 沙盒分支处理器
+type BaseBranchHandler struct {
+	branchService *application.BaseBranchService
+}
+
+// NewBaseBranchHandler 创建沙盒分支处理器
+func NewBaseBranchHandler(branchService *application.BaseBranchService) *BaseBranchHandler {
+	return &BaseBranchHandler{branchService: branchService}
+}
+
+// CreateBranch 创建沙盒分支（复制生产结构，不复制记录）
+// POST /api/v1/bases/:baseId/branches
+func (h *BaseBranchHandler) CreateBranch(c *gin.Context) {
+	var req application.CreateBaseBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	branch, err := h.branchService.CreateBranch(c.Request.Context(), c.GetString("user_id"), c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, branch, "创建沙盒分支成功")
+}
+
+// ListBranches 列出 Base 的沙盒分支
+// GET /api/v1/bases/:baseId/branches
+func (h *BaseBranchHandler) ListBranches(c *gin.Context) {
+	branches, err := h.branchService.ListBranches(c.Request.Context(), c.GetString("user_id"), c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, branches, "获取沙盒分支列表成功")
+}
+
+// GetBranch 获取沙盒分支
+// GET /api/v1/base-branches/:branchId
+func (h *BaseBranchHandler) GetBranch(c *gin.Context) {
+	branch, err := h.branchService.GetBranch(c.Request.Context(), c.GetString("user_id"), c.Param("branchId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, branch, "获取沙盒分支成功")
+}
+
+// DiffBranch 预览合并到生产的变更与冲突
+// GET /api/v1/base-branches/:branchId/diff
+func (h *BaseBranchHandler) DiffBranch(c *gin.Context) {
+	plan, err := h.branchService.DiffBranch(c.Request.Context(), c.GetString("user_id"), c.Param("branchId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, plan, "预览合并成功")
+}
+
+// PromoteBranch 发起合并到生产（长时操作），存在冲突时返回 409
+// POST /api/v1/base-branches/:branchId/promote
+func (h *BaseBranchHandler) PromoteBranch(c *gin.Context) {
+	op, err := h.branchService.PromoteBranch(c.Request.Context(), c.GetString("user_id"), c.Param("branchId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, application.NewOperationSnapshot(op), "已发起合并")
+}
+
+// DiscardBranch 丢弃沙盒分支并删除沙盒 Base
+// DELETE /api/v1/base-branches/:branchId
+func (h *BaseBranchHandler) DiscardBranch(c *gin.Context) {
+	if err := h.branchService.DiscardBranch(c.Request.Context(), c.GetString("user_id"), c.Param("branchId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "沙盒分支已丢弃")
+}
@@ -0,0 +1,115 @@
+package http
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// maxStripeWebhookBytes Stripe webhook 请求体大小上限
+const maxStripeWebhookBytes = 1 << 20
+
+// BillingHandler 套餐、用量与订阅HTTP处理器
+type BillingHandler struct {
+	planService    *application.PlanService
+	billingService *application.BillingService
+}
+
+// NewBillingHandler 创建套餐、用量与订阅处理器
+func NewBillingHandler(planService *application.PlanService, billingService *application.BillingService) *BillingHandler {
+	return &BillingHandler{planService: planService, billingService: billingService}
+}
+
+// ListPlans 列出套餐及其限额
+// GET /api/v1/billing/plans
+func (h *BillingHandler) ListPlans(c *gin.Context) {
+	response.Success(c, h.planService.Plans(), "获取套餐成功")
+}
+
+// GetSpaceUsage 获取工作空间的套餐与用量（仅空间所有者）
+// GET /api/v1/spaces/:spaceId/billing/usage
+func (h *BillingHandler) GetSpaceUsage(c *gin.Context) {
+	summary, err := h.planService.GetUsage(c.Request.Context(), c.GetString("user_id"), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, summary, "获取套餐用量成功")
+}
+
+// AdminGetSpacePlan 获取工作空间的套餐与用量（管理员）
+// GET /api/v1/admin/spaces/:spaceId/billing/plan
+func (h *BillingHandler) AdminGetSpacePlan(c *gin.Context) {
+	summary, err := h.planService.AdminGetUsage(c.Request.Context(), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, summary, "获取套餐用量成功")
+}
+
+// AdminAssignPlan 为工作空间分配套餐（管理员）
+// PUT /api/v1/admin/spaces/:spaceId/billing/plan
+func (h *BillingHandler) AdminAssignPlan(c *gin.Context) {
+	var req application.AssignPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	summary, err := h.planService.AssignPlan(c.Request.Context(), c.GetString("user_id"), c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, summary, "套餐已更新")
+}
+
+// StripeWebhook 接收 Stripe webhook（按签名校验，无需登录）
+// POST /api/v1/billing/stripe/webhook
+func (h *BillingHandler) StripeWebhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxStripeWebhookBytes))
+	if err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("读取请求体失败"))
+		return
+	}
+	if err := h.billingService.HandleStripeWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, nil, "已处理")
+}
+
+// GetSubscription 获取工作空间的 Stripe 订阅（仅空间所有者）
+// GET /api/v1/spaces/:spaceId/billing/subscription
+func (h *BillingHandler) GetSubscription(c *gin.Context) {
+	sub, err := h.billingService.GetSubscription(c.Request.Context(), c.GetString("user_id"), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, sub, "获取订阅成功")
+}
+
+// CreatePortalSession 创建 Stripe 客户门户会话（仅空间所有者）
+// POST /api/v1/spaces/:spaceId/billing/portal-session
+func (h *BillingHandler) CreatePortalSession(c *gin.Context) {
+	var req application.CreatePortalSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	session, err := h.billingService.CreatePortalSession(c.Request.Context(), c.GetString("user_id"), c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, session, "客户门户会话已创建")
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// BusinessCalendarHandler 工作日历处理器
+type BusinessCalendarHandler struct {
+	service *application.BusinessCalendarService
+}
+
+// NewBusinessCalendarHandler 创建工作日历处理器
+func NewBusinessCalendarHandler(service *application.BusinessCalendarService) *BusinessCalendarHandler {
+	return &BusinessCalendarHandler{service: service}
+}
+
+// GetCalendar 获取工作空间的工作日历（未配置时返回默认日历）
+// GET /api/v1/spaces/:spaceId/business-calendar
+func (h *BusinessCalendarHandler) GetCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	calendar, err := h.service.GetCalendar(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, calendar, "获取工作日历成功")
+}
+
+// SaveCalendar 保存工作空间的工作日历
+// PUT /api/v1/spaces/:spaceId/business-calendar
+func (h *BusinessCalendarHandler) SaveCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveBusinessCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	calendar, err := h.service.SaveCalendar(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, calendar, "保存工作日历成功")
+}
+
+// ResetCalendar 恢复默认工作日历
+// DELETE /api/v1/spaces/:spaceId/business-calendar
+func (h *BusinessCalendarHandler) ResetCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	calendar, err := h.service.ResetCalendar(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, calendar, "已恢复默认工作日历")
+}
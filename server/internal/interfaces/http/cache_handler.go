@@ -0,0 +1,50 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// CacheHandler 缓存管理处理器（仅管理员）
+type CacheHandler struct {
+	cacheService *application.CacheService
+}
+
+// NewCacheHandler 创建缓存管理处理器
+func NewCacheHandler(cacheService *application.CacheService) *CacheHandler {
+	return &CacheHandler{cacheService: cacheService}
+}
+
+// FlushCacheRequest 清理缓存请求（不指定表时清空全部缓存）
+type FlushCacheRequest struct {
+	TableID string `json:"tableId"`
+}
+
+// FlushCache 清理缓存
+// POST /api/v1/admin/cache/flush
+func (h *CacheHandler) FlushCache(c *gin.Context) {
+	var req FlushCacheRequest
+	// 请求体可选
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	var err error
+	if req.TableID != "" {
+		err = h.cacheService.InvalidateTableCache(c.Request.Context(), req.TableID)
+	} else {
+		err = h.cacheService.Flush(c.Request.Context())
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "缓存已清理")
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// CellValidationHandler 单元格值批量校验HTTP处理器
+type CellValidationHandler struct {
+	cellValidationService *application.CellValidationService
+}
+
+// NewCellValidationHandler 创建单元格值批量校验处理器
+func NewCellValidationHandler(cellValidationService *application.CellValidationService) *CellValidationHandler {
+	return &CellValidationHandler{
+		cellValidationService: cellValidationService,
+	}
+}
+
+// ValidateCells 批量校验单元格值（不写入，逐单元格返回错误）
+// POST /api/v1/tables/:tableId/records/validate
+func (h *CellValidationHandler) ValidateCells(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.ValidateCellsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.cellValidationService.ValidateCells(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "校验完成")
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/changefeed"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ChangeFeedHandler 变更流HTTP处理器
+type ChangeFeedHandler struct {
+	changeFeedService *application.ChangeFeedService
+}
+
+// NewChangeFeedHandler 创建变更流处理器
+func NewChangeFeedHandler(changeFeedService *application.ChangeFeedService) *ChangeFeedHandler {
+	return &ChangeFeedHandler{
+		changeFeedService: changeFeedService,
+	}
+}
+
+// ListChanges 按游标增量拉取 Base 的记录、字段、视图变更
+// GET /api/v1/bases/:baseId/changes?cursor=&limit=&tableId=
+func (h *ChangeFeedHandler) ListChanges(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	filter := changefeed.ListFilter{TableID: c.Query("tableId")}
+	if cursor := c.Query("cursor"); cursor != "" {
+		n, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || n < 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("cursor 无效"))
+			return
+		}
+		filter.After = n
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		filter.Limit = n
+	}
+
+	page, err := h.changeFeedService.ListChanges(c.Request.Context(), userID, c.Param("baseId"), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, page, "获取变更成功")
+}
+
+// GetHead 获取当前最新游标（全量同步前记录，之后从该游标增量拉取）
+// GET /api/v1/bases/:baseId/changes/head
+func (h *ChangeFeedHandler) GetHead(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	head, err := h.changeFeedService.Head(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"cursor": head}, "获取变更游标成功")
+}
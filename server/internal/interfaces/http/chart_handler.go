@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ChartHandler 图表数据HTTP处理器
+type ChartHandler struct {
+	chartService *application.ChartService
+}
+
+// NewChartHandler 创建图表数据处理器
+func NewChartHandler(chartService *application.ChartService) *ChartHandler {
+	return &ChartHandler{
+		chartService: chartService,
+	}
+}
+
+// QueryChartData 按维度与指标聚合表格数据
+// POST /api/v1/tables/:tableId/chart-data
+func (h *ChartHandler) QueryChartData(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.ChartDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	data, err := h.chartService.QueryChartData(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, data, "获取图表数据成功")
+}
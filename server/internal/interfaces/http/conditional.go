@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/etag"
+)
+
+// 条件请求（ETag / If-Match / If-None-Match）辅助函数 ✨
+
+// setETag 设置 ETag；响应因用户而异（权限、脱敏），只允许私有缓存并要求每次重新验证
+func setETag(c *gin.Context, tag string) {
+	c.Header("ETag", tag)
+	c.Header("Cache-Control", "private, no-cache")
+}
+
+// notModified 设置 ETag，If-None-Match 命中时返回 304（调用方应直接返回）
+func notModified(c *gin.Context, tag string) bool {
+	setETag(c, tag)
+	if header := c.GetHeader("If-None-Match"); header != "" && etag.MatchNone(header, tag) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// recordETag 记录 ETag（版本号 + 响应内容摘要）
+func recordETag(resp *dto.RecordResponse) string {
+	data, _ := json.Marshal(resp)
+	return etag.Versioned(int64(resp.Version), data)
+}
+
+// ifMatchVersion 从 If-Match 解析期望的记录版本（未提供或为 * 时返回 nil）
+func ifMatchVersion(c *gin.Context) (*int, error) {
+	header := c.GetHeader("If-Match")
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	tags := etag.Tags(header)
+	if len(tags) != 1 {
+		return nil, errors.ErrPreconditionFailed.WithDetails("If-Match 只支持单个记录 ETag")
+	}
+	version, ok := etag.Version(tags[0])
+	if !ok {
+		return nil, errors.ErrPreconditionFailed.WithDetails("无法识别的记录 ETag: " + tags[0])
+	}
+	v := int(version)
+	return &v, nil
+}
+
+// preconditionError 通过 If-Match 发起的写入遇到版本冲突时返回 412（而非 409）
+func preconditionError(err error) error {
+	if appErr, ok := errors.IsAppError(err); ok && appErr.Code == errors.ErrVersionConflict.Code {
+		return errors.ErrPreconditionFailed.WithDetails(appErr.Details)
+	}
+	return err
+}
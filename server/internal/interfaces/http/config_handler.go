@@ -0,0 +1,90 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ConfigHandler 配置热更新与功能开关处理器
+type ConfigHandler struct {
+	configService *application.ConfigService
+}
+
+// NewConfigHandler 创建配置处理器
+func NewConfigHandler(configService *application.ConfigService) *ConfigHandler {
+	return &ConfigHandler{configService: configService}
+}
+
+// GetMyFeatures 当前用户的功能开关结果
+// @Summary 当前用户的功能开关
+// @Description 按当前用户与指定工作空间评估全部功能开关，前端据此灰度展示新功能（只能指定自己可以访问的空间）
+// @Tags Features
+// @Produce json
+// @Param space_id query string false "工作空间ID"
+// @Success 200 {object} response.Response{data=map[string]bool}
+// @Failure 403 {object} response.Response
+// @Router /features [get]
+func (h *ConfigHandler) GetMyFeatures(c *gin.Context) {
+	decisions, err := h.configService.EvaluateUserFeatures(c.Request.Context(), c.GetString("user_id"), c.Query("space_id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	features := make(map[string]bool)
+	for _, d := range decisions {
+		features[d.Key] = d.Enabled
+	}
+	response.Success(c, features, "获取成功")
+}
+
+// ListFeatures 功能开关定义（仅管理员）
+// @Summary 功能开关定义
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Response{data=[]featureflag.Flag}
+// @Router /admin/features [get]
+func (h *ConfigHandler) ListFeatures(c *gin.Context) {
+	response.Success(c, h.configService.Features(), "获取成功")
+}
+
+// EvaluateFeature 评估功能开关（仅管理员，用于排查灰度结果）
+// @Summary 评估功能开关
+// @Tags Admin
+// @Produce json
+// @Param key path string true "开关名"
+// @Param space_id query string false "工作空间ID"
+// @Param user_id query string false "用户ID"
+// @Success 200 {object} response.Response{data=featureflag.Decision}
+// @Router /admin/features/{key}/evaluate [get]
+func (h *ConfigHandler) EvaluateFeature(c *gin.Context) {
+	subject := featureflag.Subject{SpaceID: c.Query("space_id"), UserID: c.Query("user_id")}
+	response.Success(c, h.configService.EvaluateFeature(c.Param("key"), subject), "获取成功")
+}
+
+// GetStatus 配置热更新状态（仅管理员）
+// @Summary 配置热更新状态
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Response{data=application.ConfigReloadStatus}
+// @Router /admin/config/status [get]
+func (h *ConfigHandler) GetStatus(c *gin.Context) {
+	response.Success(c, h.configService.Status(), "获取成功")
+}
+
+// Reload 立即重新加载配置（仅管理员）
+// @Summary 重新加载配置
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Response{data=application.ConfigReloadStatus}
+// @Router /admin/config/reload [post]
+func (h *ConfigHandler) Reload(c *gin.Context) {
+	if err := h.configService.Reload(c.Request.Context(), application.ConfigSourceManual); err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, h.configService.Status(), "配置已重新加载")
+}
@@ -0,0 +1,316 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// DashboardHandler 仪表盘HTTP处理器
+type DashboardHandler struct {
+	dashboardService *application.DashboardService
+}
+
+// NewDashboardHandler 创建仪表盘处理器
+func NewDashboardHandler(dashboardService *application.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+	}
+}
+
+// ListDashboards 列出 Base 下的仪表盘
+// GET /api/v1/bases/:baseId/dashboards
+func (h *DashboardHandler) ListDashboards(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	list, err := h.dashboardService.ListDashboards(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, list, "获取仪表盘列表成功")
+}
+
+// CreateDashboard 创建仪表盘
+// POST /api/v1/bases/:baseId/dashboards
+func (h *DashboardHandler) CreateDashboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateDashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	d, err := h.dashboardService.CreateDashboard(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, d, "创建仪表盘成功")
+}
+
+// GetDashboard 获取仪表盘及其组件
+// GET /api/v1/dashboards/:dashboardId
+func (h *DashboardHandler) GetDashboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	detail, err := h.dashboardService.GetDashboard(c.Request.Context(), userID, c.Param("dashboardId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, detail, "获取仪表盘成功")
+}
+
+// RenameDashboard 重命名仪表盘
+// PATCH /api/v1/dashboards/:dashboardId
+func (h *DashboardHandler) RenameDashboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RenameDashboardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	d, err := h.dashboardService.RenameDashboard(c.Request.Context(), userID, c.Param("dashboardId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, d, "更新仪表盘成功")
+}
+
+// UpdateLayout 保存仪表盘布局
+// PATCH /api/v1/dashboards/:dashboardId/layout
+func (h *DashboardHandler) UpdateLayout(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateDashboardLayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	d, err := h.dashboardService.UpdateLayout(c.Request.Context(), userID, c.Param("dashboardId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, d, "更新仪表盘布局成功")
+}
+
+// DeleteDashboard 删除仪表盘
+// DELETE /api/v1/dashboards/:dashboardId
+func (h *DashboardHandler) DeleteDashboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.dashboardService.DeleteDashboard(c.Request.Context(), userID, c.Param("dashboardId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除仪表盘成功")
+}
+
+// DuplicateDashboard 复制仪表盘
+// POST /api/v1/dashboards/:dashboardId/duplicate
+func (h *DashboardHandler) DuplicateDashboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.DuplicateDashboardRequest
+	// 请求体可选
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	detail, err := h.dashboardService.DuplicateDashboard(c.Request.Context(), userID, c.Param("dashboardId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, detail, "复制仪表盘成功")
+}
+
+// EnableShare 开启仪表盘分享
+// POST /api/v1/dashboards/:dashboardId/enable-share
+func (h *DashboardHandler) EnableShare(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	shareID, err := h.dashboardService.EnableShare(c.Request.Context(), userID, c.Param("dashboardId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto.EnableShareResponse{ShareID: shareID}, "分享已启用")
+}
+
+// DisableShare 关闭仪表盘分享
+// POST /api/v1/dashboards/:dashboardId/disable-share
+func (h *DashboardHandler) DisableShare(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.dashboardService.DisableShare(c.Request.Context(), userID, c.Param("dashboardId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "分享已禁用")
+}
+
+// AddWidget 添加组件
+// POST /api/v1/dashboards/:dashboardId/widgets
+func (h *DashboardHandler) AddWidget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.WidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	w, err := h.dashboardService.AddWidget(c.Request.Context(), userID, c.Param("dashboardId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, w, "添加组件成功")
+}
+
+// UpdateWidget 更新组件
+// PATCH /api/v1/dashboards/:dashboardId/widgets/:widgetId
+func (h *DashboardHandler) UpdateWidget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.WidgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	w, err := h.dashboardService.UpdateWidget(c.Request.Context(), userID, c.Param("dashboardId"), c.Param("widgetId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, w, "更新组件成功")
+}
+
+// DeleteWidget 删除组件
+// DELETE /api/v1/dashboards/:dashboardId/widgets/:widgetId
+func (h *DashboardHandler) DeleteWidget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.dashboardService.DeleteWidget(c.Request.Context(), userID, c.Param("dashboardId"), c.Param("widgetId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除组件成功")
+}
+
+// GetWidgetData 查询组件数据
+// GET /api/v1/dashboards/:dashboardId/widgets/:widgetId/data
+func (h *DashboardHandler) GetWidgetData(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	data, err := h.dashboardService.GetWidgetData(c.Request.Context(), userID, c.Param("dashboardId"), c.Param("widgetId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, data, "获取组件数据成功")
+}
+
+// GetSharedDashboard 通过分享ID获取仪表盘
+// GET /api/v1/share/dashboards/:shareId
+func (h *DashboardHandler) GetSharedDashboard(c *gin.Context) {
+	detail, err := h.dashboardService.GetSharedDashboard(c.Request.Context(), c.Param("shareId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, detail, "操作成功")
+}
+
+// GetSharedWidgetData 通过分享ID查询组件数据
+// GET /api/v1/share/dashboards/:shareId/widgets/:widgetId/data
+func (h *DashboardHandler) GetSharedWidgetData(c *gin.Context) {
+	data, err := h.dashboardService.GetSharedWidgetData(c.Request.Context(), c.Param("shareId"), c.Param("widgetId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, data, "操作成功")
+}
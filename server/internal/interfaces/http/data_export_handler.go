@@ -0,0 +1,83 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// DataExportHandler Base 数据导出HTTP处理器
+type DataExportHandler struct {
+	exportService *application.DataExportService
+}
+
+// NewDataExportHandler 创建数据导出处理器
+func NewDataExportHandler(exportService *application.DataExportService) *DataExportHandler {
+	return &DataExportHandler{
+		exportService: exportService,
+	}
+}
+
+// CreateExport 创建导出任务（请求体可省略，默认导出全部表）
+// POST /api/v1/bases/:baseId/exports
+func (h *DataExportHandler) CreateExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateDataExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	export, err := h.exportService.CreateExport(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, export, "创建导出任务成功")
+}
+
+// ListExports 列出 Base 最近的导出任务
+// GET /api/v1/bases/:baseId/exports
+func (h *DataExportHandler) ListExports(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	exports, err := h.exportService.ListExports(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, exports, "获取导出任务成功")
+}
+
+// GetExport 获取导出任务（完成后包含清单与下载链接）
+// GET /api/v1/data-exports/:exportId
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), userID, c.Param("exportId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, export, "获取导出任务成功")
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// DeliveryMonitorHandler 推送保障与死信管理HTTP处理器
+type DeliveryMonitorHandler struct {
+	monitorService *application.DeliveryMonitorService
+}
+
+// NewDeliveryMonitorHandler 创建推送保障处理器
+func NewDeliveryMonitorHandler(monitorService *application.DeliveryMonitorService) *DeliveryMonitorHandler {
+	return &DeliveryMonitorHandler{
+		monitorService: monitorService,
+	}
+}
+
+// Overview 订阅状态与失败统计
+// GET /api/v1/delivery-monitor/overview
+func (h *DeliveryMonitorHandler) Overview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	overview, err := h.monitorService.Overview(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, overview, "获取成功")
+}
+
+// ListDeliveries 失败推送列表
+// GET /api/v1/integrations/deliveries?hookId=&status=&limit=
+func (h *DeliveryMonitorHandler) ListDeliveries(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	deliveries, err := h.monitorService.ListDeliveries(c.Request.Context(), userID, c.Query("hookId"), integration.DeliveryStatus(c.Query("status")), limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, deliveries, "获取成功")
+}
+
+// RetryDeliveries 批量重试失败推送
+// POST /api/v1/integrations/deliveries/retry
+func (h *DeliveryMonitorHandler) RetryDeliveries(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.DeliveryRetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.monitorService.RetryDeliveries(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "已重新推送")
+}
+
+// ResumeHook 恢复已暂停的订阅
+// POST /api/v1/integrations/hooks/:hookId/resume
+func (h *DeliveryMonitorHandler) ResumeHook(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	hook, err := h.monitorService.ResumeHook(c.Request.Context(), userID, c.Param("hookId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, hook, "订阅已恢复")
+}
+
+// UpdateDeadLetter 更新订阅的死信设置
+// PATCH /api/v1/integrations/hooks/:hookId/dead-letter
+func (h *DeliveryMonitorHandler) UpdateDeadLetter(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.DeadLetterSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	hook, err := h.monitorService.UpdateDeadLetter(c.Request.Context(), userID, c.Param("hookId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, hook, "死信设置已更新")
+}
+
+// ListFailedRuns 失败的自动化运行
+// GET /api/v1/automation-runs/failed?workflowId=&limit=
+func (h *DeliveryMonitorHandler) ListFailedRuns(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	runs, err := h.monitorService.ListFailedRuns(c.Request.Context(), userID, c.Query("workflowId"), limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, runs, "获取成功")
+}
+
+// RetryRuns 批量重试失败的自动化运行
+// POST /api/v1/automation-runs/retry
+func (h *DeliveryMonitorHandler) RetryRuns(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.DeliveryRetryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	runs, err := h.monitorService.RetryRuns(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, runs, "已重新运行")
+}
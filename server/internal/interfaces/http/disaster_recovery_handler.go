@@ -0,0 +1,63 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// DisasterRecoveryHandler 跨区域灾备处理器（仅管理员）
+type DisasterRecoveryHandler struct {
+	service *application.DisasterRecoveryService
+}
+
+// NewDisasterRecoveryHandler 创建跨区域灾备处理器
+func NewDisasterRecoveryHandler(service *application.DisasterRecoveryService) *DisasterRecoveryHandler {
+	return &DisasterRecoveryHandler{service: service}
+}
+
+// GetStatus 获取灾备状态（各复制流进度、RPO 与复制延迟）
+func (h *DisasterRecoveryHandler) GetStatus(c *gin.Context) {
+	report, err := h.service.Status(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, report, "获取成功")
+}
+
+// GetRunbook 获取故障切换手册
+func (h *DisasterRecoveryHandler) GetRunbook(c *gin.Context) {
+	runbook, err := h.service.Runbook(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, runbook, "获取成功")
+}
+
+// Promote 提升备区域
+func (h *DisasterRecoveryHandler) Promote(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.PromoteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	promotion, err := h.service.Promote(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, promotion, "备区域已提升")
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// DocumentTemplateHandler 文档模板HTTP处理器
+type DocumentTemplateHandler struct {
+	documentService *application.DocumentTemplateService
+}
+
+// NewDocumentTemplateHandler 创建文档模板处理器
+func NewDocumentTemplateHandler(documentService *application.DocumentTemplateService) *DocumentTemplateHandler {
+	return &DocumentTemplateHandler{
+		documentService: documentService,
+	}
+}
+
+// ListTemplates 列出表的文档模板
+// GET /api/v1/tables/:tableId/document-templates
+func (h *DocumentTemplateHandler) ListTemplates(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	templates, err := h.documentService.ListTemplates(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, templates, "获取文档模板成功")
+}
+
+// CreateTemplate 以已上传的附件创建文档模板
+// POST /api/v1/tables/:tableId/document-templates
+func (h *DocumentTemplateHandler) CreateTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateDocumentTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	template, err := h.documentService.CreateTemplate(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template, "创建文档模板成功")
+}
+
+// GetTemplate 获取文档模板
+// GET /api/v1/document-templates/:templateId
+func (h *DocumentTemplateHandler) GetTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	template, err := h.documentService.GetTemplate(c.Request.Context(), userID, c.Param("templateId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template, "获取文档模板成功")
+}
+
+// UpdateTemplate 更新文档模板（可替换模板文件）
+// PATCH /api/v1/document-templates/:templateId
+func (h *DocumentTemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateDocumentTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	template, err := h.documentService.UpdateTemplate(c.Request.Context(), userID, c.Param("templateId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template, "更新文档模板成功")
+}
+
+// DeleteTemplate 删除文档模板
+// DELETE /api/v1/document-templates/:templateId
+func (h *DocumentTemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.documentService.DeleteTemplate(c.Request.Context(), userID, c.Param("templateId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除文档模板成功")
+}
+
+// Generate 为记录或视图中的记录创建文档生成任务
+// POST /api/v1/document-templates/:templateId/generate
+func (h *DocumentTemplateHandler) Generate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.GenerateDocumentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	job, err := h.documentService.Generate(c.Request.Context(), userID, c.Param("templateId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "创建文档生成任务成功")
+}
+
+// ListJobs 列出模板最近的生成任务
+// GET /api/v1/document-templates/:templateId/jobs
+func (h *DocumentTemplateHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.documentService.ListJobs(c.Request.Context(), userID, c.Param("templateId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取文档生成任务成功")
+}
+
+// GetJob 获取文档生成任务（包含每条记录的生成结果）
+// GET /api/v1/document-jobs/:jobId
+func (h *DocumentTemplateHandler) GetJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.documentService.GetJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "获取文档生成任务成功")
+}
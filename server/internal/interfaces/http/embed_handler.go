@@ -0,0 +1,168 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// EmbedHandler 嵌入视图HTTP处理器
+type EmbedHandler struct {
+	embedService *application.EmbedService
+}
+
+// NewEmbedHandler 创建嵌入视图处理器
+func NewEmbedHandler(embedService *application.EmbedService) *EmbedHandler {
+	return &EmbedHandler{
+		embedService: embedService,
+	}
+}
+
+// GetSettings 获取空间嵌入设置
+// GET /api/v1/spaces/:spaceId/embed-settings
+func (h *EmbedHandler) GetSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	settings, err := h.embedService.GetSettings(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, settings, "获取嵌入设置成功")
+}
+
+// UpdateSettings 更新空间嵌入设置
+// PUT /api/v1/spaces/:spaceId/embed-settings
+func (h *EmbedHandler) UpdateSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateEmbedSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	settings, err := h.embedService.UpdateSettings(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, settings, "更新嵌入设置成功")
+}
+
+// RevokeTokens 吊销空间内全部嵌入令牌
+// POST /api/v1/spaces/:spaceId/embed-settings/revoke-tokens
+func (h *EmbedHandler) RevokeTokens(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	settings, err := h.embedService.RevokeTokens(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, settings, "嵌入令牌已吊销")
+}
+
+// CreateToken 为视图签发嵌入令牌
+// POST /api/v1/views/:viewId/embed-tokens
+func (h *EmbedHandler) CreateToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateEmbedTokenRequest
+	// 请求体可选
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	token, err := h.embedService.CreateToken(c.Request.Context(), userID, c.Param("viewId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, token, "签发嵌入令牌成功")
+}
+
+// GetView 获取嵌入视图元数据
+// GET /api/v1/embed/view
+func (h *EmbedHandler) GetView(c *gin.Context) {
+	session := embedSession(c)
+
+	view, err := h.embedService.GetView(c.Request.Context(), session)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, view, "获取视图成功")
+}
+
+// ListRecords 获取嵌入视图的记录
+// GET /api/v1/embed/records?page=&pageSize=
+func (h *EmbedHandler) ListRecords(c *gin.Context) {
+	session := embedSession(c)
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+
+	records, err := h.embedService.ListRecords(c.Request.Context(), session, page, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, records, "获取记录成功")
+}
+
+// UpdateRecord 通过 editor 令牌更新记录
+// PATCH /api/v1/embed/records/:recordId
+func (h *EmbedHandler) UpdateRecord(c *gin.Context) {
+	session := embedSession(c)
+
+	var req struct {
+		Fields map[string]interface{} `json:"fields" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	record, err := h.embedService.UpdateRecord(c.Request.Context(), session, c.Param("recordId"), req.Fields)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, record, "更新记录成功")
+}
+
+// embedSession 获取 EmbedMiddleware 验证后的嵌入会话
+func embedSession(c *gin.Context) *application.EmbedSession {
+	session, _ := c.MustGet(embedSessionKey).(*application.EmbedSession)
+	return session
+}
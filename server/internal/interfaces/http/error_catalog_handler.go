@@ -0,0 +1,19 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// GetErrorCatalog 获取错误码目录
+// @Summary 错误码目录
+// @Description 返回所有错误码及其HTTP/gRPC映射、类别和可重试性，供客户端程序化处理错误
+// @Tags Errors
+// @Produce json
+// @Success 200 {array} errors.CatalogEntry
+// @Router /errors/catalog [get]
+func GetErrorCatalog(c *gin.Context) {
+	response.Success(c, errors.Catalog(), "获取错误码目录成功")
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ExternalSyncHandler 外部数据同步HTTP处理器
+type ExternalSyncHandler struct {
+	externalSyncService *application.ExternalSyncService
+}
+
+// NewExternalSyncHandler 创建外部数据同步处理器
+func NewExternalSyncHandler(externalSyncService *application.ExternalSyncService) *ExternalSyncHandler {
+	return &ExternalSyncHandler{
+		externalSyncService: externalSyncService,
+	}
+}
+
+// CreateConnector 创建外部数据同步
+// POST /api/v1/bases/:baseId/external-syncs
+func (h *ExternalSyncHandler) CreateConnector(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateExternalSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	connector, err := h.externalSyncService.CreateConnector(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, connector, "创建外部数据同步成功")
+}
+
+// ListConnectors 列出 Base 的外部数据同步
+// GET /api/v1/bases/:baseId/external-syncs
+func (h *ExternalSyncHandler) ListConnectors(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	connectors, err := h.externalSyncService.ListConnectors(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, connectors, "获取外部数据同步列表成功")
+}
+
+// GetConnector 获取外部数据同步及最近一次运行状态
+// GET /api/v1/external-syncs/:connectorId
+func (h *ExternalSyncHandler) GetConnector(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	connector, err := h.externalSyncService.GetConnector(c.Request.Context(), userID, c.Param("connectorId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, connector, "获取外部数据同步成功")
+}
+
+// UpdateConnector 更新外部数据同步
+// PATCH /api/v1/external-syncs/:connectorId
+func (h *ExternalSyncHandler) UpdateConnector(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateExternalSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	connector, err := h.externalSyncService.UpdateConnector(c.Request.Context(), userID, c.Param("connectorId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, connector, "更新外部数据同步成功")
+}
+
+// TriggerConnector 立即执行一次同步
+// POST /api/v1/external-syncs/:connectorId/run
+func (h *ExternalSyncHandler) TriggerConnector(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	connector, err := h.externalSyncService.TriggerConnector(c.Request.Context(), userID, c.Param("connectorId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, connector, "已安排同步")
+}
+
+// ListRuns 获取同步运行记录
+// GET /api/v1/external-syncs/:connectorId/runs
+func (h *ExternalSyncHandler) ListRuns(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	runs, err := h.externalSyncService.ListRuns(c.Request.Context(), userID, c.Param("connectorId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, runs, "获取同步记录成功")
+}
+
+// DeleteConnector 删除外部数据同步
+// DELETE /api/v1/external-syncs/:connectorId
+func (h *ExternalSyncHandler) DeleteConnector(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.externalSyncService.DeleteConnector(c.Request.Context(), userID, c.Param("connectorId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除外部数据同步成功")
+}
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -11,15 +13,20 @@ import (
 	"github.com/easyspace-ai/luckdb/server/pkg/response"
 )
 
+// schemaVersionHeader 表结构版本响应头（用于请求字段增量）
+const schemaVersionHeader = "X-Schema-Version"
+
 // FieldHandler 字段HTTP处理器
 type FieldHandler struct {
-	fieldService *application.FieldService
+	fieldService     *application.FieldService
+	referenceService *application.ReferenceService
 }
 
 // NewFieldHandler 创建字段处理器
-func NewFieldHandler(fieldService *application.FieldService) *FieldHandler {
+func NewFieldHandler(fieldService *application.FieldService, referenceService *application.ReferenceService) *FieldHandler {
 	return &FieldHandler{
-		fieldService: fieldService,
+		fieldService:     fieldService,
+		referenceService: referenceService,
 	}
 }
 
@@ -42,7 +49,15 @@ func (h *FieldHandler) CreateField(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.fieldService.CreateField(c.Request.Context(), req, userID)
+	// If-Match 携带表结构 ETag 时，仅在表结构未变化时创建
+	ctx, unlock, err := h.lockSchemaPrecondition(c, req.TableID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer unlock()
+
+	resp, err := h.fieldService.CreateField(ctx, req, userID)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -77,7 +92,14 @@ func (h *FieldHandler) UpdateField(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.fieldService.UpdateField(c.Request.Context(), fieldID, req)
+	ctx, unlock, err := h.lockFieldSchemaPrecondition(c, fieldID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer unlock()
+
+	resp, err := h.fieldService.UpdateField(ctx, fieldID, req)
 	if err != nil {
 		fmt.Printf("[FieldHandler.UpdateField] 更新失败, fieldId: %s, error: %v\n", fieldID, err)
 		response.Error(c, err)
@@ -87,11 +109,67 @@ func (h *FieldHandler) UpdateField(c *gin.Context) {
 	response.Success(c, resp, "更新字段成功")
 }
 
-// DeleteField 删除字段
+// ReorderFields 按给定顺序一次性重排表中的全部字段
+// PATCH /api/v1/tables/:tableId/fields/order
+func (h *FieldHandler) ReorderFields(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req dto.ReorderFieldsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	tableID := c.Param("tableId")
+	ctx, unlock, err := h.lockSchemaPrecondition(c, tableID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer unlock()
+
+	fields, err := h.fieldService.ReorderFields(ctx, tableID, req, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, fields, "更新字段顺序成功")
+}
+
+// DeleteField 删除字段（?cascade=block|detach|delete 指定依赖的处理方式）
 func (h *FieldHandler) DeleteField(c *gin.Context) {
 	fieldID := c.Param("fieldId")
 
-	if err := h.fieldService.DeleteField(c.Request.Context(), fieldID); err != nil {
+	ctx, unlock, err := h.lockFieldSchemaPrecondition(c, fieldID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer unlock()
+
+	if h.referenceService != nil {
+		policy, err := application.ParseCascadePolicy(c.Query("cascade"))
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		result, err := h.referenceService.DeleteField(ctx, c.GetString("user_id"), fieldID, policy)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		response.Success(c, result, "删除字段成功")
+		return
+	}
+
+	if err := h.fieldService.DeleteField(ctx, fieldID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -99,15 +177,108 @@ func (h *FieldHandler) DeleteField(c *gin.Context) {
 	response.Success(c, nil, "删除字段成功")
 }
 
+// GetFieldReferences 获取引用了该字段的字段、视图、自动化和 Webhook
+func (h *FieldHandler) GetFieldReferences(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	report, err := h.referenceService.FieldReferences(c.Request.Context(), userID, c.Param("fieldId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report, "获取字段引用成功")
+}
+
+// ListFieldOptionSchemas 获取全部字段类型的选项 JSON Schema ✨
+func (h *FieldHandler) ListFieldOptionSchemas(c *gin.Context) {
+	response.Success(c, h.fieldService.OptionSchemas(), "获取字段选项Schema成功")
+}
+
+// GetFieldOptionSchema 获取字段类型的选项 JSON Schema ✨
+func (h *FieldHandler) GetFieldOptionSchema(c *gin.Context) {
+	schema, err := h.fieldService.OptionSchema(c.Param("fieldType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, schema, "获取字段选项Schema成功")
+}
+
 // ListFields 列出表格的所有字段
 func (h *FieldHandler) ListFields(c *gin.Context) {
 	tableID := c.Param("tableId")
 
-	resp, err := h.fieldService.ListFields(c.Request.Context(), tableID)
+	// 先取表结构版本再读字段，客户端之后以该版本请求增量
+	version, err := h.fieldService.SchemaVersion(c.Request.Context(), tableID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	resp, tag, err := h.fieldService.ListFieldsWithETag(c.Request.Context(), tableID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	c.Header(schemaVersionHeader, strconv.FormatInt(version, 10))
+	if notModified(c, tag) {
+		return
+	}
 
 	response.Success(c, resp, "获取字段列表成功")
 }
+
+// ListFieldsDelta 获取表结构版本之后变化的字段
+// GET /api/v1/tables/:tableId/fields/delta?since=
+func (h *FieldHandler) ListFieldsDelta(c *gin.Context) {
+	since, err := strconv.ParseInt(c.Query("since"), 10, 64)
+	if err != nil || since < 0 {
+		response.Error(c, errors.ErrBadRequest.WithDetails("since 须为列表响应 X-Schema-Version 返回的版本"))
+		return
+	}
+
+	resp, err := h.fieldService.ListFieldsDelta(c.Request.Context(), c.Param("tableId"), since)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Header(schemaVersionHeader, strconv.FormatInt(resp.Version, 10))
+
+	response.Success(c, resp, "获取字段增量成功")
+}
+
+// lockSchemaPrecondition If-Match 携带表结构 ETag 时持有表结构锁并校验表结构未变化，返回持有锁的 context；
+// 之后的结构变更在同一把锁内执行，校验与变更之间表结构不会被其他请求修改
+func (h *FieldHandler) lockSchemaPrecondition(c *gin.Context, tableID string) (context.Context, func(), error) {
+	ctx := c.Request.Context()
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return ctx, func() {}, nil
+	}
+	ctx, unlock, err := h.fieldService.LockSchema(ctx, tableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := h.fieldService.CheckSchemaPrecondition(ctx, tableID, ifMatch); err != nil {
+		unlock()
+		return nil, nil, err
+	}
+	return ctx, unlock, nil
+}
+
+// lockFieldSchemaPrecondition 按字段所属表执行 lockSchemaPrecondition
+func (h *FieldHandler) lockFieldSchemaPrecondition(c *gin.Context, fieldID string) (context.Context, func(), error) {
+	if c.GetHeader("If-Match") == "" {
+		return c.Request.Context(), func() {}, nil
+	}
+	field, err := h.fieldService.GetField(c.Request.Context(), fieldID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return h.lockSchemaPrecondition(c, field.TableID)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/findreplace"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// FindReplaceHandler 批量查找替换HTTP处理器
+type FindReplaceHandler struct {
+	findReplaceService *application.FindReplaceService
+}
+
+// NewFindReplaceHandler 创建批量查找替换处理器
+func NewFindReplaceHandler(findReplaceService *application.FindReplaceService) *FindReplaceHandler {
+	return &FindReplaceHandler{
+		findReplaceService: findReplaceService,
+	}
+}
+
+// Preview 预览查找替换（请求体与 find_replace 操作参数相同，table_id 取自路径）
+// POST /api/v1/tables/:tableId/find-replace/preview
+func (h *FindReplaceHandler) Preview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var spec findreplace.Spec
+	if err := c.ShouldBindJSON(&spec); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	preview, err := h.findReplaceService.Preview(c.Request.Context(), userID, c.Param("tableId"), spec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "查找替换预览成功")
+}
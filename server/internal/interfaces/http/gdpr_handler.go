@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/privacy"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// GDPRHandler 数据主体请求HTTP处理器
+type GDPRHandler struct {
+	gdprService *application.GDPRService
+}
+
+// NewGDPRHandler 创建数据主体请求处理器
+func NewGDPRHandler(gdprService *application.GDPRService) *GDPRHandler {
+	return &GDPRHandler{
+		gdprService: gdprService,
+	}
+}
+
+// subjectFromQuery 从查询参数解析数据主体
+func subjectFromQuery(c *gin.Context) privacy.Subject {
+	return privacy.Subject{
+		UserID: c.Query("userId"),
+		Email:  c.Query("email"),
+	}
+}
+
+// FindSubjectData 查找数据主体的所有数据
+func (h *GDPRHandler) FindSubjectData(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	summary, err := h.gdprService.FindSubjectData(c.Request.Context(), userID, c.Param("spaceId"), subjectFromQuery(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, summary, "查询数据主体数据成功")
+}
+
+// ExportSubjectData 导出数据主体的数据（zip 归档）
+func (h *GDPRHandler) ExportSubjectData(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	// 先写入内存，出错时仍可返回标准错误响应
+	var buf bytes.Buffer
+	if err := h.gdprService.ExportSubjectData(c.Request.Context(), userID, c.Param("spaceId"), subjectFromQuery(c), &buf); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("subject-export-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// StartErasure 发起数据擦除任务
+func (h *GDPRHandler) StartErasure(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.StartErasureRequest
+	if err := ValidateBindJSON(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	job, err := h.gdprService.StartErasure(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "擦除任务已开始")
+}
+
+// ListErasureJobs 列出数据擦除任务
+func (h *GDPRHandler) ListErasureJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.gdprService.ListErasureJobs(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取擦除任务成功")
+}
+
+// GetErasureJob 获取数据擦除任务及报告
+func (h *GDPRHandler) GetErasureJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.gdprService.GetErasureJob(c.Request.Context(), userID, c.Param("spaceId"), c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "获取擦除任务成功")
+}
+
+// VerifyErasureJob 核验数据擦除报告
+func (h *GDPRHandler) VerifyErasureJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	result, err := h.gdprService.VerifyErasureJob(c.Request.Context(), userID, c.Param("spaceId"), c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "核验擦除报告成功")
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ImpersonationHandler 支持人员代入处理器
+type ImpersonationHandler struct {
+	service *application.ImpersonationService
+}
+
+// NewImpersonationHandler 创建代入处理器
+func NewImpersonationHandler(service *application.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service}
+}
+
+// StartSession 开始代入（令牌明文只在响应中返回一次）
+// POST /api/v1/impersonation/sessions
+func (h *ImpersonationHandler) StartSession(c *gin.Context) {
+	claims := contextClaims(c)
+	if claims == nil {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	session, err := h.service.Start(c.Request.Context(), claims, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, session, "代入已开始")
+}
+
+// ListSessions 列出代入会话（支持人员为自己发起的会话，管理员为全部）
+// GET /api/v1/impersonation/sessions
+func (h *ImpersonationHandler) ListSessions(c *gin.Context) {
+	claims := contextClaims(c)
+	if claims == nil {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.ListImpersonationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	sessions, err := h.service.List(c.Request.Context(), claims, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, sessions, "获取成功")
+}
+
+// EndSession 结束代入会话
+// POST /api/v1/impersonation/sessions/:sessionId/end
+func (h *ImpersonationHandler) EndSession(c *gin.Context) {
+	claims := contextClaims(c)
+	if claims == nil {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	session, err := h.service.End(c.Request.Context(), claims, c.Param("sessionId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, session, "代入已结束")
+}
+
+// ListReceived 列出以当前用户身份进行的代入会话
+// GET /api/v1/impersonation/received
+func (h *ImpersonationHandler) ListReceived(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	sessions, err := h.service.ListReceived(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, sessions, "获取成功")
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/integration"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// IntegrationHandler 集成平台（Zapier / Make）HTTP处理器
+// 触发器接口直接返回 JSON 数组（平台的轮询触发器要求数组响应），其余接口使用统一响应格式
+type IntegrationHandler struct {
+	integrationService *application.IntegrationService
+}
+
+// NewIntegrationHandler 创建集成平台处理器
+func NewIntegrationHandler(integrationService *application.IntegrationService) *IntegrationHandler {
+	return &IntegrationHandler{
+		integrationService: integrationService,
+	}
+}
+
+// PollTrigger 轮询触发器
+// GET /api/v1/integrations/tables/:tableId/triggers/:event?cursor=&limit=
+// 下次轮询的游标通过 X-Next-Cursor 响应头返回
+func (h *IntegrationHandler) PollTrigger(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var cursor *int64
+	if raw := c.Query("cursor"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value < 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("cursor 无效"))
+			return
+		}
+		cursor = &value
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	items, next, err := h.integrationService.PollTrigger(c.Request.Context(), userID, c.Param("tableId"), integration.Event(c.Param("event")), cursor, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if cursor != nil {
+		c.Header("X-Next-Cursor", strconv.FormatInt(next, 10))
+	}
+	c.JSON(200, items)
+}
+
+// Subscribe 订阅 REST Hook
+// POST /api/v1/integrations/tables/:tableId/hooks
+func (h *IntegrationHandler) Subscribe(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SubscribeHookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	hook, err := h.integrationService.Subscribe(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, hook, "订阅成功")
+}
+
+// Unsubscribe 退订 REST Hook
+// DELETE /api/v1/integrations/hooks/:hookId
+func (h *IntegrationHandler) Unsubscribe(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.integrationService.Unsubscribe(c.Request.Context(), userID, c.Param("hookId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "退订成功")
+}
+
+// UpdateHookTemplate 更新订阅的输出模板
+// PUT /api/v1/integrations/hooks/:hookId/template
+func (h *IntegrationHandler) UpdateHookTemplate(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateHookTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	hook, err := h.integrationService.UpdateHookTemplate(c.Request.Context(), userID, c.Param("hookId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, hook, "输出模板已更新")
+}
+
+// InputFields 动作的动态输入字段
+// GET /api/v1/integrations/tables/:tableId/input-fields
+func (h *IntegrationHandler) InputFields(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	fields, err := h.integrationService.InputFields(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, fields, "获取输入字段成功")
+}
+
+// CreateRecord 新建记录动作
+// POST /api/v1/integrations/tables/:tableId/actions/create-record
+func (h *IntegrationHandler) CreateRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.IntegrationRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	item, err := h.integrationService.CreateRecord(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, item, "创建记录成功")
+}
+
+// UpdateRecord 更新记录动作
+// POST /api/v1/integrations/tables/:tableId/actions/update-record
+func (h *IntegrationHandler) UpdateRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.IntegrationRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	item, err := h.integrationService.UpdateRecord(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, item, "更新记录成功")
+}
+
+// FindRecords 查找记录动作
+// POST /api/v1/integrations/tables/:tableId/actions/find-records
+func (h *IntegrationHandler) FindRecords(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.IntegrationFindRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	items, err := h.integrationService.FindRecords(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.JSON(200, items)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// MaintenanceHandler 维护模式处理器
+type MaintenanceHandler struct {
+	service *application.MaintenanceService
+}
+
+// NewMaintenanceHandler 创建维护模式处理器
+func NewMaintenanceHandler(service *application.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{service: service}
+}
+
+// GetStatus 资源所在范围的维护状态（客户端据此显示提示横幅）
+// GET /api/v1/maintenance/status?spaceId=&baseId=&tableId=
+func (h *MaintenanceHandler) GetStatus(c *gin.Context) {
+	status := h.service.Status(c.Request.Context(), application.ResourceRefs{
+		SpaceID: c.Query("spaceId"),
+		BaseID:  c.Query("baseId"),
+		TableID: c.Query("tableId"),
+	})
+	response.Success(c, status, "获取成功")
+}
+
+// GetOverview 当前生效与最近的维护窗口（仅管理员）
+// GET /api/v1/admin/maintenance
+func (h *MaintenanceHandler) GetOverview(c *gin.Context) {
+	overview, err := h.service.Overview(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, overview, "获取成功")
+}
+
+// StartWindow 开启维护模式（仅管理员）
+// POST /api/v1/admin/maintenance
+func (h *MaintenanceHandler) StartWindow(c *gin.Context) {
+	var req application.StartMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	window, err := h.service.StartWindow(c.Request.Context(), c.GetString("user_id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, window, "维护模式已开启")
+}
+
+// EndWindow 结束维护模式（仅管理员）
+// POST /api/v1/admin/maintenance/:windowId/end
+func (h *MaintenanceHandler) EndWindow(c *gin.Context) {
+	window, err := h.service.EndWindow(c.Request.Context(), c.GetString("user_id"), c.Param("windowId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, window, "维护模式已结束")
+}
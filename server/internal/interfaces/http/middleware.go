@@ -1,18 +1,148 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/businesscalendar"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/idempotency"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/impersonation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/plan"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/internal/realtime"
+	"github.com/easyspace-ai/luckdb/server/internal/sharedb"
 	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
+	"github.com/easyspace-ai/luckdb/server/pkg/metrics"
+	"github.com/easyspace-ai/luckdb/server/pkg/querybudget"
 	"github.com/easyspace-ai/luckdb/server/pkg/response"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+	"github.com/easyspace-ai/luckdb/server/pkg/userlocale"
 )
 
+// TracingMiddleware 链路追踪中间件 ✨
+// 从 traceparent 继承上游链路，为每个请求创建服务端跨度，并通过响应头回传 traceparent
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.Start(ctx, "HTTP "+c.Request.Method,
+			tracing.WithSpanKind(tracing.SpanKindServer),
+			tracing.WithAttributes(
+				tracing.String("http.request.method", c.Request.Method),
+				tracing.String("url.path", c.Request.URL.Path),
+				tracing.String("client.address", c.ClientIP()),
+				tracing.String("user_agent.original", c.Request.UserAgent()),
+			))
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		if sc := tracing.SpanContextFromContext(ctx); sc.IsValid() {
+			c.Header(tracing.TraceparentHeader, tracing.FormatTraceparent(sc))
+			c.Set("trace_id", sc.TraceID.String())
+		}
+
+		c.Next()
+
+		// 路由模板在匹配后才可用，用于低基数的跨度名称
+		if route := c.FullPath(); route != "" {
+			span.SetName("HTTP " + c.Request.Method + " " + route)
+			span.SetAttributes(tracing.String("http.route", route))
+		}
+		status := c.Writer.Status()
+		span.SetAttributes(tracing.Int("http.response.status_code", status))
+		if userID := c.GetString("user_id"); userID != "" {
+			span.SetAttributes(tracing.String("enduser.id", userID))
+		}
+		if status >= 500 {
+			message := fmt.Sprintf("HTTP %d", status)
+			if len(c.Errors) > 0 {
+				message = c.Errors.Last().Error()
+			}
+			span.SetStatus(tracing.StatusError, message)
+		}
+	}
+}
+
+// MetricsMiddleware HTTP 指标中间件 ✨
+// 按路由模板（而非实际路径）统计请求数与耗时，避免标签基数爆炸；耗时样本附带 trace_id exemplar
+// 需在 TracingMiddleware 之后注册
+func MetricsMiddleware() gin.HandlerFunc {
+	inFlight := metrics.HTTPRequestsInFlight.WithLabelValues()
+	return func(c *gin.Context) {
+		start := time.Now()
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		metrics.HTTPRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+
+		var exemplar map[string]string
+		if traceID := tracing.TraceIDFromContext(c.Request.Context()); traceID != "" {
+			exemplar = map[string]string{"trace_id": traceID}
+		}
+		metrics.HTTPRequestDuration.WithLabelValues(method, route).
+			ObserveWithExemplar(time.Since(start).Seconds(), exemplar)
+	}
+}
+
+// QueryBudgetMiddleware 查询预算中间件 ✨
+// 按路由模板将请求归入预算类别（未配置的路由使用默认预算），预算随请求上下文传到数据库层执行
+func QueryBudgetMiddleware(cfg config.QueryBudgetConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+	budgetOf := func(class string, classCfg config.QueryBudgetClassConfig) querybudget.Budget {
+		return querybudget.Budget{
+			Class:            class,
+			StatementTimeout: classCfg.StatementTimeout,
+			MaxRowsScanned:   classCfg.MaxRowsScanned,
+			MaxSortRows:      classCfg.MaxSortRows,
+		}
+	}
+	defaultBudget := budgetOf("default", cfg.Default)
+	routes := make(map[string]querybudget.Budget)
+	for class, classCfg := range cfg.Classes {
+		for _, route := range classCfg.Routes {
+			routes[strings.Join(strings.Fields(route), " ")] = budgetOf(class, classCfg)
+		}
+	}
+
+	return func(c *gin.Context) {
+		budget, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			budget = defaultBudget
+		}
+		if !budget.IsZero() {
+			c.Request = c.Request.WithContext(querybudget.WithBudget(c.Request.Context(), budget))
+		}
+		c.Next()
+	}
+}
+
 // JWTAuthMiddleware JWT认证中间件
 func JWTAuthMiddleware(authService *application.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -78,11 +208,548 @@ func JWTAuthMiddleware(authService *application.AuthService) gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("is_admin", claims.IsAdmin)
+		c.Set("token_claims", claims)
+
+		if claims.ImpersonationID != "" {
+			impersonatedRequest(c, authService.ImpersonationService(), claims)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// impersonatedRequest 代入令牌的请求：拒绝凭据、账号与计费等敏感接口，
+// 并在请求结束后写入标记为代入操作的审计日志（被拒绝的请求同样记录）
+func impersonatedRequest(c *gin.Context, impersonationService *application.ImpersonationService, claims *dto.TokenClaims) {
+	start := time.Now()
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	req := application.ImpersonatedRequest{
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		Route:     route,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	c.Set("impersonator_id", claims.ImpersonatorID)
+	c.Header("X-Impersonated-By", claims.ImpersonatorID)
+
+	if impersonation.Blocked(req.Method, route) {
+		response.Error(c, errors.ErrForbidden.WithDetails("代入期间不允许访问该接口"))
+		c.Abort()
+		req.Blocked = true
+	} else {
+		c.Next()
+	}
+
+	req.Status = c.Writer.Status()
+	req.Duration = time.Since(start)
+	impersonationService.Record(c.Request.Context(), claims, req)
+}
+
+// AdminRequiredMiddleware 管理员权限中间件（需在 JWTAuthMiddleware 之后使用）
+func AdminRequiredMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("is_admin") {
+			response.Error(c, errors.ErrForbidden.WithDetails("需要管理员权限"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminConsoleAuthMiddleware 管理控制台认证中间件 ✨
+// 只接受控制台令牌（lac_，通过 Authorization: Bearer 携带），普通登录令牌、服务令牌与代入令牌一律拒绝；
+// 每次请求都检查来源 IP 并确认用户仍是有效的管理员
+func AdminConsoleAuthMiddleware(consoleService *application.AdminConsoleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		session, err := consoleService.Authenticate(c.Request.Context(), token, consoleClient(c))
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(authctx.WithUser(c.Request.Context(), session.UserID))
+		c.Set("user_id", session.UserID)
+		c.Set("console_session", session)
+		c.Next()
+	}
+}
+
+// WorkspaceSecurityMiddleware 工作空间安全策略中间件（需在 JWTAuthMiddleware 之后使用）
+// 按路径中的 spaceId/baseId/tableId/fieldId/viewId/recordId（及附件ID）解析请求所属空间并执行该空间的策略；
+// 携带了资源ID却无法解析所属空间的请求直接拒绝
+func WorkspaceSecurityMiddleware(securityService *application.SecurityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok, err := buildEnforceRequest(c, securityService)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
 
+		if err := securityService.Enforce(c.Request.Context(), req); err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// WorkspaceLifecycleMiddleware 工作空间只读中间件（需在 JWTAuthMiddleware 之后使用）✨
+// 已停用或计划删除的工作空间拒绝写请求；管理接口（/admin）不受限制，以便管理员恢复或迁移
+func WorkspaceLifecycleMiddleware(lifecycleService *application.WorkspaceLifecycleService, securityService *application.SecurityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) || strings.HasPrefix(c.FullPath(), "/api/v1/admin/") {
+			c.Next()
+			return
+		}
+		req, ok, err := buildEnforceRequest(c, securityService)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if err := lifecycleService.CheckWritable(c.Request.Context(), req.SpaceID); err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FeatureGateMiddleware 功能开关中间件（需在 JWTAuthMiddleware 之后使用）✨
+// 按路径中的资源解析请求所属空间，开关对当前用户与该空间关闭时返回功能不可用；不属于某个空间的请求按用户评估
+func FeatureGateMiddleware(key string, securityService *application.SecurityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		spaceID, err := securityService.ResolveRequestSpace(ctx, requestResourceRefs(c))
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if !featureflag.Enabled(ctx, key, spaceID) {
+			response.Error(c, errors.ErrFeatureNotAvailable.WithDetails("该功能尚未对当前工作空间开放"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// UserLocaleMiddleware 用户时区与语言中间件（需在 JWTAuthMiddleware 之后使用）✨
+// 时区优先取 X-Time-Zone 请求头（客户端所在时区），其次取用户配置；语言取用户配置，
+// 用户没有配置时取 Accept-Language 的首选语言。读取配置失败时不影响请求（按 UTC 处理）
+func UserLocaleMiddleware(userConfigService *application.UserConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.Next()
+			return
+		}
+
+		locale, found, err := userConfigService.Locale(c.Request.Context(), userID)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if tz := strings.TrimSpace(c.GetHeader("X-Time-Zone")); tz != "" {
+			if _, err := time.LoadLocation(tz); err == nil {
+				locale.TimeZone = tz
+			}
+		}
+		if !found {
+			preferred, _, _ := strings.Cut(c.GetHeader("Accept-Language"), ",")
+			preferred, _, _ = strings.Cut(preferred, ";")
+			locale.Language = strings.TrimSpace(preferred)
+		}
+		c.Request = c.Request.WithContext(userlocale.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
+
+// BusinessCalendarMiddleware 工作日历中间件 ✨
+// 将按表查找工作空间工作日历的方式写入请求 context，视图过滤的 isOverdueWorkdays 等据此计算（只在使用时查找）
+func BusinessCalendarMiddleware(calendarService *application.BusinessCalendarService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(businesscalendar.WithResolver(c.Request.Context(), calendarService.ForTable))
 		c.Next()
 	}
 }
 
+// MaintenanceMiddleware 维护模式只读中间件（需在 JWTAuthMiddleware 之后使用）✨
+// 记录、字段与表的写服务自行检查；其余写请求（视图、评论、Base 等）由本中间件按路径中的资源拒绝。
+// 管理接口（/admin）不受限制，以便管理员结束维护或执行迁移
+func MaintenanceMiddleware(maintenanceService *application.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutatingMethod(c.Request.Method) || strings.HasPrefix(c.FullPath(), "/api/v1/admin/") {
+			c.Next()
+			return
+		}
+
+		err := maintenanceService.CheckWritable(c.Request.Context(), application.ResourceRefs{
+			SpaceID: c.Param("spaceId"),
+			BaseID:  c.Param("baseId"),
+			TableID: c.Param("tableId"),
+			FieldID: c.Param("fieldId"),
+			ViewID:  c.Param("viewId"),
+		})
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// UsageMiddleware 服务令牌 API 调用计数中间件（按令牌与所属空间计入用量统计）✨
+// 调用前检查套餐的 API 调用限额：超过硬限额时拒绝，超过软限额时在响应头中给出警告
+func UsageMiddleware(usageService *application.UsageService, planService *application.PlanService, securityService *application.SecurityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims := contextClaims(c)
+		if claims == nil || claims.ServiceTokenID == "" {
+			c.Next()
+			return
+		}
+		spaceID := securityService.ResolveSpace(c.Request.Context(), application.ResourceRefs{
+			SpaceID: c.Param("spaceId"),
+			BaseID:  c.Param("baseId"),
+			TableID: c.Param("tableId"),
+			FieldID: c.Param("fieldId"),
+			ViewID:  c.Param("viewId"),
+		})
+
+		level, err := planService.CheckAPICall(c.Request.Context(), spaceID)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if level != plan.LevelOK {
+			c.Header("X-Plan-Limit-Warning", string(plan.LimitAPICalls))
+		}
+
+		c.Next()
+		usageService.RecordAPICall(spaceID, claims.ServiceTokenID)
+	}
+}
+
+// SensitiveOperationMiddleware 敏感操作中间件（空间开启后须在有效期内重新认证）
+func SensitiveOperationMiddleware(securityService *application.SecurityService, operation security.SensitiveOperation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok, err := buildEnforceRequest(c, securityService)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if err := securityService.EnforceSensitive(c.Request.Context(), req, operation); err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RealtimeSecurityMiddleware 实时连接的工作空间安全策略中间件（WebSocket 与 SSE，需在 JWTAuthMiddleware 之后使用）✨
+// 实时连接本身不属于某个空间：为 ShareDB 连接设置按集合的访问检查、为 SSE 订阅设置按频道的检查，
+// 每次读取、订阅或提交前解析所属空间并执行该空间的策略，无法解析所属空间时拒绝；
+// 所属空间未开启实时协作网关（realtime_gateway）时拒绝，客户端回退到轮询
+func RealtimeSecurityMiddleware(securityService *application.SecurityService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		template := newEnforceRequest(c, "")
+		enforce := func(ctx context.Context, refs application.ResourceRefs) error {
+			spaceID, err := securityService.ResolveRequestSpace(ctx, refs)
+			if err != nil || spaceID == "" {
+				return err
+			}
+			if !featureflag.Enabled(ctx, featureflag.KeyRealtimeGateway, spaceID) {
+				return errors.ErrFeatureNotAvailable.WithDetails("该工作空间未开启实时协作")
+			}
+			req := template
+			req.SpaceID = spaceID
+			return securityService.Enforce(ctx, req)
+		}
+
+		c.Set(sharedb.ConnectionGuardKey, sharedb.ConnectionGuard(func(ctx context.Context, collection string) error {
+			return enforce(ctx, application.ResourceRefs{TableID: sharedb.ParseCollection(collection).TableID})
+		}))
+		c.Set(realtime.SubscriptionGuardKey, realtime.SubscriptionGuard(func(ctx context.Context, channel string) error {
+			return enforce(ctx, channelResourceRefs(channel))
+		}))
+		c.Next()
+	}
+}
+
+// channelResourceRefs SSE 频道对应的资源（global、operation 等频道不属于某个空间）
+func channelResourceRefs(channel string) application.ResourceRefs {
+	kind, id, _ := strings.Cut(channel, ":")
+	switch kind {
+	case "space":
+		return application.ResourceRefs{SpaceID: id}
+	case "base":
+		return application.ResourceRefs{BaseID: id}
+	case "table":
+		return application.ResourceRefs{TableID: id}
+	case "record":
+		return application.ResourceRefs{RecordID: id}
+	}
+	return application.ResourceRefs{}
+}
+
+// embedSessionKey 嵌入会话在 gin.Context 中的键
+const embedSessionKey = "embed_session"
+
+// EmbedMiddleware 嵌入接口中间件 ✨
+// 令牌通过 token 查询参数或 X-Embed-Token 头携带（CORS 预检只能使用查询参数）。
+// 响应的 frame-ancestors 与 CORS 由令牌所属空间的嵌入设置决定，不在允许列表中的来源一律拒绝
+func EmbedMiddleware(embedService *application.EmbedService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Del("Access-Control-Allow-Origin")
+		header.Del("X-Frame-Options") // 由 frame-ancestors 取代
+		header.Set("Content-Security-Policy", "frame-ancestors 'none'")
+		header.Set("Cache-Control", "no-store")
+		header.Add("Vary", "Origin")
+
+		token := c.Query("token")
+		if token == "" {
+			token = c.GetHeader("X-Embed-Token")
+		}
+		origin := c.GetHeader("Origin")
+
+		if c.Request.Method == http.MethodOptions {
+			settings, err := embedService.SettingsForToken(c.Request.Context(), token)
+			if err != nil || !settings.Enabled || !settings.AllowsOrigin(origin) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Methods", "GET, PATCH, OPTIONS")
+			header.Set("Access-Control-Allow-Headers", "Content-Type, X-Embed-Token")
+			header.Set("Access-Control-Max-Age", "600")
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		session, err := embedService.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		header.Set("Content-Security-Policy", session.Settings.FrameAncestors())
+		if origin != "" {
+			if !session.Settings.AllowsOrigin(origin) {
+				response.Error(c, errors.ErrForbidden.WithDetails("该来源不允许访问嵌入内容"))
+				c.Abort()
+				return
+			}
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+
+		c.Set(embedSessionKey, session)
+		c.Next()
+	}
+}
+
+// maxIdempotentRequestBody 参与幂等指纹计算的最大请求体（更大的请求不做幂等处理，如附件上传）
+const maxIdempotentRequestBody = 8 << 20
+
+// IdempotencyMiddleware 幂等键中间件（需在 JWTAuthMiddleware 之后使用）✨
+// 写请求携带 Idempotency-Key 时，同一用户相同键的重试直接重放首次响应；
+// 键被用于内容不同的请求返回 422，首次请求仍在处理中返回 409
+func IdempotencyMiddleware(idempotencyService *application.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.HeaderKey)
+		if key == "" || !idempotencyService.Enabled() || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		userID := c.GetString("user_id")
+		if userID == "" || c.Request.ContentLength > maxIdempotentRequestBody {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentRequestBody+1))
+			if err != nil {
+				response.Error(c, errors.ErrBadRequest.WithDetails("读取请求体失败"))
+				c.Abort()
+				return
+			}
+			if len(data) > maxIdempotentRequestBody {
+				c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), c.Request.Body))
+				c.Next()
+				return
+			}
+			body = data
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx := c.Request.Context()
+		record := &idempotency.Record{
+			Scope:       userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			Fingerprint: idempotency.Fingerprint(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, body),
+		}
+		replay, err := idempotencyService.Begin(ctx, record)
+		if err != nil {
+			if errors.Is(err, errors.ErrIdempotencyInProgress) {
+				c.Header("Retry-After", "1")
+			}
+			response.Error(c, err)
+			c.Abort()
+			return
+		}
+		if replay != nil {
+			c.Header(idempotency.HeaderReplayed, "true")
+			if replay.BodyOmitted {
+				response.Error(c, errors.ErrIdempotencyNotStored.WithDetails(map[string]interface{}{
+					"status_code": replay.StatusCode,
+				}))
+				c.Abort()
+				return
+			}
+			c.Data(replay.StatusCode, replay.ContentType, replay.Body)
+			c.Abort()
+			return
+		}
+
+		// 长时间处理（如导入）期间续期占用，避免超过 LockTimeout 后被重试请求接管而重复执行
+		stopKeepAlive := idempotencyService.KeepAlive(context.WithoutCancel(ctx), record)
+		writer := &idempotencyWriter{ResponseWriter: c.Writer, limit: idempotencyService.MaxBodySize()}
+		c.Writer = writer
+		completed := false
+		defer func() {
+			if !completed {
+				stopKeepAlive()
+				idempotencyService.Release(context.WithoutCancel(ctx), record) // 处理过程中 panic
+			}
+		}()
+
+		c.Next()
+
+		completed = true
+		stopKeepAlive()
+		idempotencyService.Complete(context.WithoutCancel(ctx), record,
+			writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes())
+	}
+}
+
+// idempotencyWriter 捕获响应体以便重放
+// 最多缓冲 limit+1 字节：超过 limit 的响应不会被保存，无需缓冲完整内容
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *idempotencyWriter) capture(data []byte) {
+	if remaining := w.limit + 1 - w.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			data = data[:remaining]
+		}
+		w.body.Write(data)
+	}
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// buildEnforceRequest 从请求上下文构造策略检查请求
+// 请求不携带资源ID时返回 false；携带了资源ID却无法解析所属空间时返回错误
+func buildEnforceRequest(c *gin.Context, securityService *application.SecurityService) (application.EnforceRequest, bool, error) {
+	spaceID, err := securityService.ResolveRequestSpace(c.Request.Context(), requestResourceRefs(c))
+	if err != nil {
+		return application.EnforceRequest{}, false, err
+	}
+	if spaceID == "" {
+		return application.EnforceRequest{}, false, nil
+	}
+	return newEnforceRequest(c, spaceID), true, nil
+}
+
+// requestResourceRefs 请求路径中的资源ID（/attachments/:id 的 id 为附件ID）
+func requestResourceRefs(c *gin.Context) application.ResourceRefs {
+	refs := application.ResourceRefs{
+		SpaceID:  c.Param("spaceId"),
+		BaseID:   c.Param("baseId"),
+		TableID:  c.Param("tableId"),
+		FieldID:  c.Param("fieldId"),
+		ViewID:   c.Param("viewId"),
+		RecordID: c.Param("recordId"),
+	}
+	if strings.HasPrefix(c.FullPath(), "/api/v1/attachments/:id") {
+		refs.AttachmentID = c.Param("id")
+	}
+	return refs
+}
+
+// newEnforceRequest 以请求的用户、会话与来源构造指定空间的策略检查请求
+func newEnforceRequest(c *gin.Context, spaceID string) application.EnforceRequest {
+	req := application.EnforceRequest{
+		SpaceID:   spaceID,
+		UserID:    c.GetString("user_id"),
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Method:    c.Request.Method,
+		Path:      c.FullPath(),
+	}
+	if claims := contextClaims(c); claims != nil {
+		req.SessionID = claims.SessionID
+		req.AuthTime = claims.AuthTime
+		if claims.ReauthTime != nil {
+			req.ReauthTime = *claims.ReauthTime
+		}
+	}
+	return req
+}
+
 // ValidateBindJSON 统一的JSON绑定和验证辅助函数
 // 用于替代直接调用 ShouldBindJSON，提供更详细的错误信息
 func ValidateBindJSON(c *gin.Context, obj interface{}) error {
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// stubPolicyRepo 固定的空间安全策略
+type stubPolicyRepo struct {
+	security.Repository
+	policy     *security.Policy
+	violations int
+}
+
+func (r *stubPolicyRepo) GetPolicy(context.Context, string) (*security.Policy, error) {
+	return r.policy, nil
+}
+
+func (r *stubPolicyRepo) RecordViolation(context.Context, *security.Violation) error {
+	r.violations++
+	return nil
+}
+
+func newIPAllowlistRouter(t *testing.T, trustedProxies []string) (*gin.Engine, *stubPolicyRepo) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop()
+
+	repo := &stubPolicyRepo{policy: &security.Policy{SpaceID: "spc1", IPAllowlist: []string{"10.0.0.0/8"}}}
+	securityService := application.NewSecurityService(repo, nil, nil, nil, nil, nil)
+
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatalf("设置受信任代理失败: %v", err)
+	}
+	router.GET("/spaces/:spaceId", WorkspaceSecurityMiddleware(securityService), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return router, repo
+}
+
+func serveFrom(router *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/spaces/spc1", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestWorkspaceSecurityIgnoresSpoofedForwardedFor(t *testing.T) {
+	router, repo := newIPAllowlistRouter(t, nil)
+
+	// 未配置受信任代理：伪造的 X-Forwarded-For 不能绕过白名单
+	if code := serveFrom(router, "203.0.113.5:40000", "10.1.2.3"); code != http.StatusForbidden {
+		t.Fatalf("来自不受信任对端的 X-Forwarded-For 应被忽略，得到 %d", code)
+	}
+	if repo.violations != 1 {
+		t.Fatalf("应记录一次违规，得到 %d", repo.violations)
+	}
+
+	// 对端地址本身在白名单内时放行
+	if code := serveFrom(router, "10.1.2.3:40000", ""); code != http.StatusNoContent {
+		t.Fatalf("白名单内的对端地址应放行，得到 %d", code)
+	}
+}
+
+func TestWorkspaceSecurityUsesForwardedForFromTrustedProxy(t *testing.T) {
+	router, _ := newIPAllowlistRouter(t, []string{"192.0.2.0/24"})
+
+	if code := serveFrom(router, "192.0.2.10:40000", "10.1.2.3"); code != http.StatusNoContent {
+		t.Fatalf("受信任代理转发的客户端IP应生效，得到 %d", code)
+	}
+	if code := serveFrom(router, "192.0.2.10:40000", "203.0.113.5"); code != http.StatusForbidden {
+		t.Fatalf("受信任代理转发的白名单外IP应被拒绝，得到 %d", code)
+	}
+	if code := serveFrom(router, "203.0.113.5:40000", "10.1.2.3"); code != http.StatusForbidden {
+		t.Fatalf("不受信任对端的 X-Forwarded-For 应被忽略，得到 %d", code)
+	}
+}
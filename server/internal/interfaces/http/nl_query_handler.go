@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// NLQueryHandler 自然语言查询HTTP处理器
+type NLQueryHandler struct {
+	nlQueryService *application.NLQueryService
+}
+
+// NewNLQueryHandler 创建自然语言查询处理器
+func NewNLQueryHandler(nlQueryService *application.NLQueryService) *NLQueryHandler {
+	return &NLQueryHandler{
+		nlQueryService: nlQueryService,
+	}
+}
+
+// Query 用自然语言查询表中的记录，返回生成的过滤与排序条件及匹配的记录
+// POST /api/v1/tables/:tableId/nl-query
+func (h *NLQueryHandler) Query(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.NLQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.nlQueryService.Query(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "查询成功")
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// OfflineSyncHandler 离线同步HTTP处理器
+type OfflineSyncHandler struct {
+	offlineSyncService *application.OfflineSyncService
+}
+
+// NewOfflineSyncHandler 创建离线同步处理器
+func NewOfflineSyncHandler(offlineSyncService *application.OfflineSyncService) *OfflineSyncHandler {
+	return &OfflineSyncHandler{
+		offlineSyncService: offlineSyncService,
+	}
+}
+
+// Snapshot 分页拉取表的记录快照
+// GET /api/v1/bases/:baseId/offline-sync/snapshot?tableId=&after=&limit=
+func (h *OfflineSyncHandler) Snapshot(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	tableID := c.Query("tableId")
+	if tableID == "" {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("tableId 不能为空"))
+		return
+	}
+	var after int64
+	if value := c.Query("after"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n < 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("after 无效"))
+			return
+		}
+		after = n
+	}
+	var limit int
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		limit = n
+	}
+
+	page, err := h.offlineSyncService.Snapshot(c.Request.Context(), userID, c.Param("baseId"), tableID, after, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, page, "获取快照成功")
+}
+
+// Push 推送离线期间的修改，逐条返回回放结果
+// POST /api/v1/bases/:baseId/offline-sync/push
+func (h *OfflineSyncHandler) Push(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.OfflinePushRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.offlineSyncService.Push(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "推送离线修改成功")
+}
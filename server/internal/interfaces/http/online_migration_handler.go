@@ -0,0 +1,29 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// OnlineMigrationHandler 在线迁移处理器
+type OnlineMigrationHandler struct {
+	service *application.OnlineMigrationService
+}
+
+// NewOnlineMigrationHandler 创建在线迁移处理器
+func NewOnlineMigrationHandler(service *application.OnlineMigrationService) *OnlineMigrationHandler {
+	return &OnlineMigrationHandler{service: service}
+}
+
+// GetStatus 元数据表在线迁移状态（仅管理员）
+// GET /api/v1/admin/migrations/status
+func (h *OnlineMigrationHandler) GetStatus(c *gin.Context) {
+	status, err := h.service.Status(c.Request.Context())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, status, "获取成功")
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/operation"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// OperationHandler 长时操作HTTP处理器
+type OperationHandler struct {
+	operationService *application.OperationService
+}
+
+// NewOperationHandler 创建长时操作处理器
+func NewOperationHandler(operationService *application.OperationService) *OperationHandler {
+	return &OperationHandler{
+		operationService: operationService,
+	}
+}
+
+// StartOperation 发起长时操作，返回操作ID供轮询或订阅
+// POST /api/v1/bases/:baseId/operations
+func (h *OperationHandler) StartOperation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.StartOperationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	op, err := h.operationService.StartOperation(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, application.NewOperationSnapshot(op), "操作已创建")
+}
+
+// ListOperations 列出 Base 下的长时操作
+// GET /api/v1/bases/:baseId/operations?status=&type=&limit=
+func (h *OperationHandler) ListOperations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	filter := operation.ListFilter{
+		Status: operation.Status(c.Query("status")),
+		Type:   operation.Type(c.Query("type")),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		filter.Limit = n
+	}
+
+	ops, err := h.operationService.ListOperations(c.Request.Context(), userID, c.Param("baseId"), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]application.OperationSnapshot, 0, len(ops))
+	for _, op := range ops {
+		list = append(list, application.NewOperationSnapshot(op))
+	}
+	response.Success(c, list, "获取操作列表成功")
+}
+
+// GetOperation 获取长时操作进度与结果
+// GET /api/v1/operations/:operationId
+func (h *OperationHandler) GetOperation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	op, err := h.operationService.GetOperation(c.Request.Context(), userID, c.Param("operationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, application.NewOperationSnapshot(op), "获取操作成功")
+}
+
+// CancelOperation 取消长时操作
+// POST /api/v1/operations/:operationId/cancel
+func (h *OperationHandler) CancelOperation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	op, err := h.operationService.CancelOperation(c.Request.Context(), userID, c.Param("operationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, application.NewOperationSnapshot(op), "已请求取消操作")
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// PageHandler 界面页面HTTP处理器
+type PageHandler struct {
+	pageService *application.PageService
+}
+
+// NewPageHandler 创建界面页面处理器
+func NewPageHandler(pageService *application.PageService) *PageHandler {
+	return &PageHandler{
+		pageService: pageService,
+	}
+}
+
+// CreatePage 创建页面
+// POST /api/v1/bases/:baseId/pages
+func (h *PageHandler) CreatePage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreatePageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	p, err := h.pageService.CreatePage(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, p, "创建页面成功")
+}
+
+// ListPages 列出当前用户可以访问的页面
+// GET /api/v1/bases/:baseId/pages
+func (h *PageHandler) ListPages(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	pages, err := h.pageService.ListPages(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, pages, "获取页面列表成功")
+}
+
+// GetPage 获取页面配置
+// GET /api/v1/pages/:pageId
+func (h *PageHandler) GetPage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	p, err := h.pageService.GetPage(c.Request.Context(), userID, c.Param("pageId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, p, "获取页面成功")
+}
+
+// UpdatePage 更新页面配置
+// PATCH /api/v1/pages/:pageId
+func (h *PageHandler) UpdatePage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdatePageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	p, err := h.pageService.UpdatePage(c.Request.Context(), userID, c.Param("pageId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, p, "更新页面成功")
+}
+
+// DeletePage 删除页面
+// DELETE /api/v1/pages/:pageId
+func (h *PageHandler) DeletePage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.pageService.DeletePage(c.Request.Context(), userID, c.Param("pageId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除页面成功")
+}
+
+// RenderPage 获取页面渲染数据
+// GET /api/v1/pages/:pageId/render
+func (h *PageHandler) RenderPage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	render, err := h.pageService.RenderPage(c.Request.Context(), userID, c.Param("pageId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, render, "获取页面成功")
+}
+
+// ListElementRecords 分页读取页面元素的记录
+// GET /api/v1/pages/:pageId/elements/:elementId/records?page=&pageSize=
+func (h *PageHandler) ListElementRecords(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+
+	records, err := h.pageService.ListElementRecords(c.Request.Context(), userID, c.Param("pageId"), c.Param("elementId"), page, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, records, "获取记录成功")
+}
+
+// GetElementRecord 读取页面元素中的单条记录
+// GET /api/v1/pages/:pageId/elements/:elementId/records/:recordId
+func (h *PageHandler) GetElementRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	record, err := h.pageService.GetElementRecord(c.Request.Context(), userID, c.Param("pageId"), c.Param("elementId"), c.Param("recordId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, record, "获取记录成功")
+}
+
+// UpdateElementRecord 通过页面元素更新记录
+// PATCH /api/v1/pages/:pageId/elements/:elementId/records/:recordId
+func (h *PageHandler) UpdateElementRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdatePageRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	record, err := h.pageService.UpdateElementRecord(c.Request.Context(), userID, c.Param("pageId"), c.Param("elementId"), c.Param("recordId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, record, "更新记录成功")
+}
+
+// RunButton 点击页面按钮，触发绑定的工作流
+// POST /api/v1/pages/:pageId/elements/:elementId/run
+func (h *PageHandler) RunButton(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RunPageButtonRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	run, err := h.pageService.RunButton(c.Request.Context(), userID, c.Param("pageId"), c.Param("elementId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, run, "触发工作流成功")
+}
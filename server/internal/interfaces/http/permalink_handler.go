@@ -0,0 +1,92 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/permalink"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// PermalinkHandler 永久链接处理器
+type PermalinkHandler struct {
+	service *application.PermalinkService
+}
+
+// NewPermalinkHandler 创建永久链接处理器
+func NewPermalinkHandler(service *application.PermalinkService) *PermalinkHandler {
+	return &PermalinkHandler{service: service}
+}
+
+// CreatePermalink 获取或创建记录、视图的永久链接
+// POST /api/v1/permalinks
+func (h *PermalinkHandler) CreatePermalink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreatePermalinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	link, err := h.service.CreatePermalink(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, link, "获取永久链接成功")
+}
+
+// ResolvePermalink 解析永久链接，返回目标当前的位置
+// GET /api/v1/permalinks/:code
+func (h *PermalinkHandler) ResolvePermalink(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	location, err := h.service.Resolve(c.Request.Context(), userID, c.Param("code"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, location, "解析链接成功")
+}
+
+// PreviewPermalink 永久链接预览
+// GET /api/v1/permalinks/:code/preview
+func (h *PermalinkHandler) PreviewPermalink(c *gin.Context) {
+	h.preview(c, c.Param("code"))
+}
+
+// Unfurl 按完整链接获取预览（供聊天工具集成展开消息中的链接）
+// GET /api/v1/permalinks/unfurl?url=
+func (h *PermalinkHandler) Unfurl(c *gin.Context) {
+	code, ok := permalink.ParseCode(c.Query("url"))
+	if !ok {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("不是有效的永久链接"))
+		return
+	}
+	h.preview(c, code)
+}
+
+func (h *PermalinkHandler) preview(c *gin.Context, code string) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	preview, err := h.service.Preview(c.Request.Context(), userID, code)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, preview, "获取链接预览成功")
+}
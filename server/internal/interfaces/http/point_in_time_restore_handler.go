@@ -0,0 +1,69 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// PointInTimeRestoreHandler 按时间点恢复HTTP处理器
+type PointInTimeRestoreHandler struct {
+	restoreService *application.PointInTimeRestoreService
+}
+
+// NewPointInTimeRestoreHandler 创建按时间点恢复处理器
+func NewPointInTimeRestoreHandler(restoreService *application.PointInTimeRestoreService) *PointInTimeRestoreHandler {
+	return &PointInTimeRestoreHandler{
+		restoreService: restoreService,
+	}
+}
+
+// Preview 预览恢复将要写入的差异
+// POST /api/v1/tables/:tableId/point-in-time-restore/preview
+func (h *PointInTimeRestoreHandler) Preview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.PointInTimeRestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	preview, err := h.restoreService.Preview(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "获取恢复预览成功")
+}
+
+// Restore 将记录或一列恢复到指定时间点
+// POST /api/v1/tables/:tableId/point-in-time-restore
+func (h *PointInTimeRestoreHandler) Restore(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.PointInTimeRestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.restoreService.Restore(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "按时间点恢复完成")
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// PrintHandler PDF 打印HTTP处理器
+type PrintHandler struct {
+	printService *application.PrintService
+}
+
+// NewPrintHandler 创建打印处理器
+func NewPrintHandler(printService *application.PrintService) *PrintHandler {
+	return &PrintHandler{
+		printService: printService,
+	}
+}
+
+// CreateJob 创建记录或视图的打印任务
+// POST /api/v1/print-jobs
+func (h *PrintHandler) CreateJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreatePrintJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	job, err := h.printService.CreateJob(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "创建打印任务成功")
+}
+
+// ListJobs 列出当前用户在表中最近的打印任务
+// GET /api/v1/tables/:tableId/print-jobs
+func (h *PrintHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.printService.ListJobs(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取打印任务成功")
+}
+
+// GetJob 获取打印任务（完成后包含下载链接）
+// GET /api/v1/print-jobs/:jobId
+func (h *PrintHandler) GetJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.printService.GetJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "获取打印任务成功")
+}
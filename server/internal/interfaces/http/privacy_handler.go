@@ -0,0 +1,111 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// PrivacyHandler PII检测与字段脱敏HTTP处理器
+type PrivacyHandler struct {
+	privacyService *application.PrivacyService
+}
+
+// NewPrivacyHandler 创建隐私处理器
+func NewPrivacyHandler(privacyService *application.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+	}
+}
+
+// ScanTable 扫描表格中的PII字段
+func (h *PrivacyHandler) ScanTable(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	findings, err := h.privacyService.ScanTable(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, findings, "PII扫描完成")
+}
+
+// ListFindings 获取最近一次PII扫描结果
+func (h *PrivacyHandler) ListFindings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	findings, err := h.privacyService.ListFindings(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, findings, "获取PII扫描结果成功")
+}
+
+// ListMaskingPolicies 获取表格的脱敏策略
+func (h *PrivacyHandler) ListMaskingPolicies(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	policies, err := h.privacyService.ListMaskingPolicies(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policies, "获取脱敏策略成功")
+}
+
+// SetMaskingPolicy 设置字段脱敏策略
+func (h *PrivacyHandler) SetMaskingPolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SetMaskingPolicyRequest
+	if err := ValidateBindJSON(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	policy, err := h.privacyService.SetMaskingPolicy(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "设置脱敏策略成功")
+}
+
+// DeleteMaskingPolicy 删除字段脱敏策略
+func (h *PrivacyHandler) DeleteMaskingPolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.privacyService.DeleteMaskingPolicy(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除脱敏策略成功")
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// QueryStatsHandler 动态表查询统计处理器（仅管理员）
+type QueryStatsHandler struct {
+	service *application.QueryStatsService
+}
+
+// NewQueryStatsHandler 创建查询统计处理器
+func NewQueryStatsHandler(service *application.QueryStatsService) *QueryStatsHandler {
+	return &QueryStatsHandler{service: service}
+}
+
+// GetSummary 统计概览
+// @Summary 查询统计概览
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Response{data=application.QueryStatsSummary}
+// @Router /admin/query-stats [get]
+func (h *QueryStatsHandler) GetSummary(c *gin.Context) {
+	response.Success(c, h.service.Summary(c.Request.Context()), "获取成功")
+}
+
+// ListTables 按动态表列出查询统计
+// @Summary 动态表查询统计
+// @Description 按累计耗时（默认）、平均/最大耗时、慢查询次数、查询次数或失败次数降序
+// @Tags Admin
+// @Produce json
+// @Param base_id query string false "Base ID"
+// @Param sort query string false "排序字段：total/avg/max/slow/queries/errors"
+// @Param limit query int false "返回条数（默认 50，最大 500）"
+// @Success 200 {object} response.Response{data=[]application.TableQueryStatsView}
+// @Router /admin/query-stats/tables [get]
+func (h *QueryStatsHandler) ListTables(c *gin.Context) {
+	stats, err := h.service.ListTables(c.Request.Context(), queryStatsFilter(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, stats, "获取成功")
+}
+
+// ListBases 按 Base 汇总查询统计
+// @Summary Base 查询统计
+// @Tags Admin
+// @Produce json
+// @Param sort query string false "排序字段：total/avg/max/slow/queries/errors"
+// @Param limit query int false "返回条数（默认 50，最大 500）"
+// @Success 200 {object} response.Response{data=[]application.BaseQueryStatsView}
+// @Router /admin/query-stats/bases [get]
+func (h *QueryStatsHandler) ListBases(c *gin.Context) {
+	stats, err := h.service.ListBases(c.Request.Context(), queryStatsFilter(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, stats, "获取成功")
+}
+
+// ListSlowQueries 最近的慢查询
+// @Summary 最近的慢查询
+// @Tags Admin
+// @Produce json
+// @Param base_id query string false "Base ID"
+// @Param table_id query string false "Table ID"
+// @Param limit query int false "返回条数（默认 50，最大 500）"
+// @Success 200 {object} response.Response{data=[]application.SlowQueryView}
+// @Router /admin/query-stats/slow-queries [get]
+func (h *QueryStatsHandler) ListSlowQueries(c *gin.Context) {
+	entries, err := h.service.ListSlowQueries(c.Request.Context(), queryStatsFilter(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, entries, "获取成功")
+}
+
+// Reset 清空统计
+// @Summary 清空查询统计
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /admin/query-stats [delete]
+func (h *QueryStatsHandler) Reset(c *gin.Context) {
+	h.service.Reset()
+	response.Success(c, nil, "已清空")
+}
+
+func queryStatsFilter(c *gin.Context) application.QueryStatsFilter {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	return application.QueryStatsFilter{
+		BaseID:  c.Query("base_id"),
+		TableID: c.Query("table_id"),
+		Sort:    c.Query("sort"),
+		Limit:   limit,
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// RecordArchiveHandler 记录归档HTTP处理器
+type RecordArchiveHandler struct {
+	archiveService *application.RecordArchiveService
+}
+
+// NewRecordArchiveHandler 创建记录归档处理器
+func NewRecordArchiveHandler(archiveService *application.RecordArchiveService) *RecordArchiveHandler {
+	return &RecordArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// GetPolicy 获取表的归档策略
+// GET /api/v1/tables/:tableId/archive-policy
+func (h *RecordArchiveHandler) GetPolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	policy, err := h.archiveService.GetPolicy(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "获取归档策略成功")
+}
+
+// SavePolicy 创建或更新表的归档策略
+// PUT /api/v1/tables/:tableId/archive-policy
+func (h *RecordArchiveHandler) SavePolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveArchivePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	policy, err := h.archiveService.SavePolicy(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "保存归档策略成功")
+}
+
+// DeletePolicy 删除表的归档策略
+// DELETE /api/v1/tables/:tableId/archive-policy
+func (h *RecordArchiveHandler) DeletePolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.archiveService.DeletePolicy(c.Request.Context(), userID, c.Param("tableId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除归档策略成功")
+}
+
+// Run 按归档策略立即执行一次归档
+// POST /api/v1/tables/:tableId/archive-policy/run
+func (h *RecordArchiveHandler) Run(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	result, err := h.archiveService.RunNow(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "归档完成")
+}
+
+// Restore 将归档记录恢复到表中
+// POST /api/v1/tables/:tableId/records/archived/restore
+func (h *RecordArchiveHandler) Restore(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RestoreArchivedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.archiveService.Restore(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "恢复归档记录成功")
+}
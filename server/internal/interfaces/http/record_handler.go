@@ -61,7 +61,7 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 
 	logger.Info("JSON绑定成功",
 		logger.String("table_id", req.TableID),
-		logger.Any("data", req.Data))
+		logger.Int("field_count", len(req.Data)))
 
 	userID := c.GetString("user_id")
 	if userID == "" {
@@ -70,6 +70,17 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 		return
 	}
 
+	// ✨ fieldKeyType=apiKey 时数据按字段 API 键传入
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.recordService.ResolveFieldKeys(c.Request.Context(), req.TableID, keyType, req.Data); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	logger.Info("开始调用 RecordService.CreateRecord",
 		logger.String("table_id", req.TableID),
 		logger.String("user_id", userID))
@@ -79,7 +90,7 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 		// 记录详细错误信息
 		logger.Error("创建记录失败",
 			logger.String("table_id", req.TableID),
-			logger.Any("data", req.Data),
+			logger.Int("field_count", len(req.Data)),
 			logger.String("user_id", userID),
 			logger.ErrorField(err))
 		response.Error(c, err)
@@ -90,10 +101,35 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 		logger.String("table_id", req.TableID),
 		logger.String("record_id", resp.ID))
 
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), req.TableID, keyType, resp); err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	// ✅ 虚拟字段计算已在 Service 事务内完成，无需异步计算
 	response.Success(c, resp, "创建记录成功")
 }
 
+// projectionKeys 读取字段投影参数 fields（可重复，按 fieldKeyType 指定字段ID、名称或 API 键）
+// 字段ID与 API 键可以用逗号分隔；字段名称可能包含逗号，按原样使用
+func projectionKeys(c *gin.Context, keyType string) []string {
+	var keys []string
+	for _, value := range c.QueryArray("fields") {
+		if keyType == application.FieldKeyTypeName {
+			if value = strings.TrimSpace(value); value != "" {
+				keys = append(keys, value)
+			}
+			continue
+		}
+		for _, key := range strings.Split(value, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
 // GetRecord 获取记录详情
 func (h *RecordHandler) GetRecord(c *gin.Context) {
 	tableID := c.Param("tableId")
@@ -111,12 +147,40 @@ func (h *RecordHandler) GetRecord(c *gin.Context) {
 		tableID = foundTableID
 	}
 
-	resp, err := h.recordService.GetRecord(c.Request.Context(), tableID, recordID)
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	fieldIDs, err := h.recordService.ResolveProjection(c.Request.Context(), tableID, keyType, projectionKeys(c, keyType))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	resp, err := h.recordService.GetRecordWithFields(c.Request.Context(), tableID, recordID, fieldIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// ✨ 展开关联记录（ETag 包含展开内容，关联记录变化时同样失效）
+	if err := h.expandRecords(c, tableID, resp); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.formatRecords(c, tableID, resp); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), tableID, keyType, resp); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if notModified(c, recordETag(resp)) {
+		return
+	}
+
 	response.Success(c, resp, "获取记录成功")
 }
 
@@ -139,14 +203,52 @@ func (h *RecordHandler) UpdateRecord(c *gin.Context) {
 		return
 	}
 
+	// ✨ 请求体中的 fieldKeyType 优先于查询参数
+	fieldKeyType := req.FieldKeyType
+	if fieldKeyType == "" {
+		fieldKeyType = c.Query("fieldKeyType")
+	}
+	keyType, err := application.ParseFieldKeyType(fieldKeyType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if req.Record != nil {
+		err = h.recordService.ResolveFieldKeys(ctx, tableID, keyType, req.Record.Fields)
+	} else {
+		err = h.recordService.ResolveFieldKeys(ctx, tableID, keyType, req.Data)
+	}
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
 	userID := c.GetString("user_id")
 	if userID == "" {
 		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
 		return
 	}
 
+	// If-Match 携带的记录 ETag 优先于请求体中的 version（乐观锁）
+	ifMatch, err := ifMatchVersion(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if ifMatch != nil {
+		req.Version = ifMatch
+	}
+
 	resp, err := h.recordService.UpdateRecord(ctx, tableID, recordID, req, userID)
 	if err != nil {
+		if ifMatch != nil {
+			err = preconditionError(err)
+		}
+		response.Error(c, err)
+		return
+	}
+	setETag(c, recordETag(resp))
+	if err := h.recordService.ApplyFieldKeyType(ctx, tableID, keyType, resp); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -173,11 +275,17 @@ func (h *RecordHandler) DeleteRecord(c *gin.Context) {
 		return
 	}
 
-	if err := h.recordService.DeleteRecord(c.Request.Context(), tableID, recordID); err != nil {
+	ifMatch, err := ifMatchVersion(c)
+	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	if err := h.recordService.DeleteRecordWithVersion(c.Request.Context(), tableID, recordID, ifMatch); err != nil {
+		response.Error(c, preconditionError(err))
+		return
+	}
+
 	response.Success(c, nil, "删除记录成功")
 }
 
@@ -201,12 +309,31 @@ func (h *RecordHandler) BatchCreateRecords(c *gin.Context) {
 		return
 	}
 
-	// 3. 调用Service
+	// 3. ✨ fieldKeyType=apiKey 时数据按字段 API 键传入
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	rows := make([]map[string]interface{}, 0, len(req.Records))
+	for _, item := range req.Records {
+		rows = append(rows, item.Fields)
+	}
+	if err := h.recordService.ResolveFieldKeys(c.Request.Context(), tableID, keyType, rows...); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 4. 调用Service
 	resp, err := h.recordService.BatchCreateRecords(c.Request.Context(), tableID, req, userID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), tableID, keyType, resp.Records...); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	// 4. ✅ 严格使用response.Success
 	response.Success(c, resp, "批量创建记录成功")
@@ -237,12 +364,31 @@ func (h *RecordHandler) BatchUpdateRecords(c *gin.Context) {
 		return
 	}
 
-	// 4. 调用Service（传递 tableID）
+	// 4. ✨ fieldKeyType=apiKey 时数据按字段 API 键传入
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	rows := make([]map[string]interface{}, 0, len(req.Records))
+	for _, item := range req.Records {
+		rows = append(rows, item.Fields)
+	}
+	if err := h.recordService.ResolveFieldKeys(c.Request.Context(), tableID, keyType, rows...); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 5. 调用Service（传递 tableID）
 	resp, err := h.recordService.BatchUpdateRecords(c.Request.Context(), tableID, req, userID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), tableID, keyType, resp.Records...); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	// 4. ✅ 严格使用response.Success
 	response.Success(c, resp, "批量更新记录成功")
@@ -277,6 +423,64 @@ func (h *RecordHandler) BatchDeleteRecords(c *gin.Context) {
 	response.Success(c, resp, "批量删除记录成功")
 }
 
+// MutateRecords 混合批量创建/更新/删除记录（部分失败语义，逐条返回结果）
+// POST /api/v1/tables/:tableId/records:batch
+func (h *RecordHandler) MutateRecords(c *gin.Context) {
+	if c.Param("action") != ":batch" {
+		response.Error(c, errors.ErrNotFound.WithDetails("路由不存在"))
+		return
+	}
+	tableID := c.Param("tableId")
+
+	// 1. 参数绑定
+	var req dto.RecordMutationBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	// 2. 获取用户ID
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	// 3. ✨ fieldKeyType=apiKey 时数据按字段 API 键传入
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	rows := make([]map[string]interface{}, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		rows = append(rows, op.Fields)
+	}
+	if err := h.recordService.ResolveFieldKeys(c.Request.Context(), tableID, keyType, rows...); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 4. 调用Service
+	resp, err := h.recordService.MutateRecords(c.Request.Context(), tableID, req, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	records := make([]*dto.RecordResponse, 0, len(resp.Results))
+	for _, result := range resp.Results {
+		if result.Record != nil {
+			records = append(records, result.Record)
+		}
+	}
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), tableID, keyType, records...); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "批量变更记录成功")
+}
+
 // ListRecords 列出表格的所有记录
 func (h *RecordHandler) ListRecords(c *gin.Context) {
 	tableID := c.Param("tableId")
@@ -308,12 +512,39 @@ func (h *RecordHandler) ListRecords(c *gin.Context) {
         }
     }
 
-	// 调用 Service 获取记录列表和总数
-	records, total, err := h.recordService.ListRecords(c.Request.Context(), tableID, limit, offset)
+	// 调用 Service 获取记录列表和总数（includeArchived=true 时在当前记录之后接续归档记录）
+	list := h.recordService.ListRecordsWithFields
+	if c.Query("includeArchived") == "true" {
+		list = h.recordService.ListRecordsIncludingArchived
+	}
+	keyType, err := application.ParseFieldKeyType(c.Query("fieldKeyType"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	// ✨ 字段投影：只读取并返回 fields 指定的字段
+	fieldIDs, err := h.recordService.ResolveProjection(c.Request.Context(), tableID, keyType, projectionKeys(c, keyType))
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	records, total, err := list(c.Request.Context(), tableID, limit, offset, fieldIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.expandRecords(c, tableID, records...); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.formatRecords(c, tableID, records...); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if err := h.recordService.ApplyFieldKeyType(c.Request.Context(), tableID, keyType, records...); err != nil {
+		response.Error(c, err)
+		return
+	}
 
 	// 计算总页数
     totalPages := int((total + int64(limit) - 1) / int64(limit))
@@ -330,8 +561,151 @@ func (h *RecordHandler) ListRecords(c *gin.Context) {
 	response.PaginatedSuccess(c, records, pagination, "获取记录列表成功")
 }
 
+// ListRecordsSnapshot 按时间点读取表记录（分页导出时保证各页一致）
+// GET /api/v1/tables/:tableId/records/snapshot?asOf=&cursor=&limit=
+func (h *RecordHandler) ListRecordsSnapshot(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.RecordSnapshotRequest
+	if asOf := c.Query("asOf"); asOf != "" {
+		t, err := time.Parse(time.RFC3339Nano, asOf)
+		if err != nil {
+			response.Error(c, errors.ErrBadRequest.WithDetails("asOf 必须为 RFC3339 时间"))
+			return
+		}
+		req.AsOf = &t
+	}
+	if cursor := c.Query("cursor"); cursor != "" {
+		n, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || n < 0 {
+			response.Error(c, errors.ErrBadRequest.WithDetails("cursor 无效"))
+			return
+		}
+		req.Cursor = n
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.Error(c, errors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		req.Limit = n
+	}
+
+	snapshot, err := h.recordService.ListRecordsAsOf(c.Request.Context(), tableID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, snapshot, "获取表快照成功")
+}
+
+// ListArchivedRecords 分页读取表的归档记录（按归档时间倒序）
+// GET /api/v1/tables/:tableId/records/archived?limit=&offset=
+func (h *RecordHandler) ListArchivedRecords(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	records, total, err := h.recordService.ListArchivedRecords(c.Request.Context(), tableID, limit, offset)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	pagination := response.Pagination{
+		Page:       (offset / limit) + 1,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+	response.PaginatedSuccess(c, records, pagination, "获取归档记录成功")
+}
+
+// GetCellHistory 获取单元格修改历史（按版本倒序分页）
+// GET /api/v1/tables/:tableId/records/:recordId/fields/:fieldId/history?cursor=&limit=
+func (h *RecordHandler) GetCellHistory(c *gin.Context) {
+	tableID := c.Param("tableId")
+	recordID := c.Param("recordId")
+	fieldID := c.Param("fieldId")
+
+	var req dto.CellHistoryRequest
+	if cursor := c.Query("cursor"); cursor != "" {
+		n, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || n < 0 {
+			response.Error(c, errors.ErrBadRequest.WithDetails("cursor 无效"))
+			return
+		}
+		req.Cursor = n
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			response.Error(c, errors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		req.Limit = n
+	}
+
+	history, err := h.recordService.GetCellHistory(c.Request.Context(), tableID, recordID, fieldID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, history, "获取单元格历史成功")
+}
+
 // ==================== 辅助方法 ====================
 
+// expandRecords 处理 expand 查询参数
+// expand=fld1,fld2 展开指定字段，expand=* 展开全部关联/查找/用户字段；expandDepth 控制嵌套层数
+func (h *RecordHandler) expandRecords(c *gin.Context, tableID string, records ...*dto.RecordResponse) error {
+	expand := strings.TrimSpace(c.Query("expand"))
+	if expand == "" {
+		return nil
+	}
+
+	var opts application.RecordExpandOptions
+	if expand != "*" {
+		for _, fieldID := range strings.Split(expand, ",") {
+			if fieldID = strings.TrimSpace(fieldID); fieldID != "" {
+				opts.FieldIDs = append(opts.FieldIDs, fieldID)
+			}
+		}
+	}
+	if depthStr := c.Query("expandDepth"); depthStr != "" {
+		depth, err := strconv.Atoi(depthStr)
+		if err != nil || depth < 1 {
+			return errors.ErrBadRequest.WithDetails("expandDepth 必须为正整数")
+		}
+		opts.Depth = depth
+	}
+
+	return h.recordService.ExpandRecords(c.Request.Context(), c.GetString("user_id"), tableID, records, opts)
+}
+
+// formatRecords 请求 includeFormatted=true 时计算每个单元格的展示文本
+// 时区取 timeZone 参数，语言取 locale 参数（未提供时使用请求用户的时区与语言）
+func (h *RecordHandler) formatRecords(c *gin.Context, tableID string, records ...*dto.RecordResponse) error {
+	if c.Query("includeFormatted") != "true" {
+		return nil
+	}
+	opts := application.RecordFormatOptions{
+		TimeZone: strings.TrimSpace(c.Query("timeZone")),
+		Locale:   strings.TrimSpace(c.Query("locale")),
+	}
+	return h.recordService.FormatRecords(c.Request.Context(), tableID, records, opts)
+}
+
 // calculateVirtualFieldsAsync 异步计算虚拟字段
 func (h *RecordHandler) calculateVirtualFieldsAsync(tableID, recordID string, req dto.UpdateRecordRequest) {
 	// ⚠️ 关键：添加延迟确保主线程的数据库事务已提交
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// RecordLockHandler 记录锁HTTP处理器
+type RecordLockHandler struct {
+	recordLockService *application.RecordLockService
+}
+
+// NewRecordLockHandler 创建记录锁处理器
+func NewRecordLockHandler(recordLockService *application.RecordLockService) *RecordLockHandler {
+	return &RecordLockHandler{
+		recordLockService: recordLockService,
+	}
+}
+
+// List 列出表中仍有效的记录锁（可用 recordIds 逗号分隔指定记录）
+// GET /api/v1/tables/:tableId/record-locks
+func (h *RecordLockHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var recordIDs []string
+	if raw := c.Query("recordIds"); raw != "" {
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				recordIDs = append(recordIDs, id)
+			}
+		}
+	}
+
+	locks, err := h.recordLockService.ListLocks(c.Request.Context(), userID, c.Param("tableId"), recordIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, locks, "获取记录锁成功")
+}
+
+// Lock 锁定记录或其中部分字段
+// POST /api/v1/tables/:tableId/records/:recordId/lock
+func (h *RecordLockHandler) Lock(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.LockRecordRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	lock, err := h.recordLockService.LockRecord(c.Request.Context(), userID, c.Param("tableId"), c.Param("recordId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, lock, "锁定记录成功")
+}
+
+// Unlock 解锁
+// DELETE /api/v1/tables/:tableId/record-locks/:lockId
+func (h *RecordLockHandler) Unlock(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.recordLockService.UnlockRecord(c.Request.Context(), userID, c.Param("tableId"), c.Param("lockId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "解锁成功")
+}
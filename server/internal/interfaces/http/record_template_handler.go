@@ -0,0 +1,148 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// RecordTemplateHandler 记录模板HTTP处理器
+type RecordTemplateHandler struct {
+	recordTemplateService *application.RecordTemplateService
+}
+
+// NewRecordTemplateHandler 创建记录模板处理器
+func NewRecordTemplateHandler(recordTemplateService *application.RecordTemplateService) *RecordTemplateHandler {
+	return &RecordTemplateHandler{
+		recordTemplateService: recordTemplateService,
+	}
+}
+
+// List 列出表的记录模板
+// GET /api/v1/tables/:tableId/record-templates
+func (h *RecordTemplateHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	templates, err := h.recordTemplateService.ListTemplates(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, templates, "获取记录模板成功")
+}
+
+// Create 创建记录模板
+// POST /api/v1/tables/:tableId/record-templates
+func (h *RecordTemplateHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveRecordTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	template, err := h.recordTemplateService.CreateTemplate(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template, "创建记录模板成功")
+}
+
+// Update 更新记录模板
+// PATCH /api/v1/record-templates/:templateId
+func (h *RecordTemplateHandler) Update(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateRecordTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	template, err := h.recordTemplateService.UpdateTemplate(c.Request.Context(), userID, c.Param("templateId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, template, "更新记录模板成功")
+}
+
+// Delete 删除记录模板
+// DELETE /api/v1/record-templates/:templateId
+func (h *RecordTemplateHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.recordTemplateService.DeleteTemplate(c.Request.Context(), userID, c.Param("templateId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除记录模板成功")
+}
+
+// Resolve 获取模板解析占位符后的值（用于预填新建表单）
+// GET /api/v1/record-templates/:templateId/values
+func (h *RecordTemplateHandler) Resolve(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	values, err := h.recordTemplateService.ResolveTemplate(c.Request.Context(), userID, c.Param("templateId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, values, "获取模板值成功")
+}
+
+// CreateRecord 从模板新建记录
+// POST /api/v1/record-templates/:templateId/records
+func (h *RecordTemplateHandler) CreateRecord(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateFromRecordTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	record, err := h.recordTemplateService.CreateFromTemplate(c.Request.Context(), userID, c.Param("templateId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, record, "创建记录成功")
+}
@@ -0,0 +1,235 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// RecurrenceHandler 重复记录规则HTTP处理器
+type RecurrenceHandler struct {
+	recurrenceService *application.RecurrenceService
+}
+
+// NewRecurrenceHandler 创建重复记录规则处理器
+func NewRecurrenceHandler(recurrenceService *application.RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{
+		recurrenceService: recurrenceService,
+	}
+}
+
+// ListRules 列出表的重复规则
+// GET /api/v1/tables/:tableId/recurrence-rules
+func (h *RecurrenceHandler) ListRules(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	rules, err := h.recurrenceService.ListRules(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rules, "获取重复规则成功")
+}
+
+// CreateRule 创建重复规则
+// POST /api/v1/tables/:tableId/recurrence-rules
+func (h *RecurrenceHandler) CreateRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateRecurrenceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	rule, err := h.recurrenceService.CreateRule(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "创建重复规则成功")
+}
+
+// UpdateRule 更新重复规则
+// PATCH /api/v1/recurrence-rules/:ruleId
+func (h *RecurrenceHandler) UpdateRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateRecurrenceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	rule, err := h.recurrenceService.UpdateRule(c.Request.Context(), userID, c.Param("ruleId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "更新重复规则成功")
+}
+
+// DeleteRule 删除重复规则
+// DELETE /api/v1/recurrence-rules/:ruleId
+func (h *RecurrenceHandler) DeleteRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.recurrenceService.DeleteRule(c.Request.Context(), userID, c.Param("ruleId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除重复规则成功")
+}
+
+// ListRuns 获取重复规则的执行记录
+// GET /api/v1/recurrence-rules/:ruleId/runs
+func (h *RecurrenceHandler) ListRuns(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	runs, err := h.recurrenceService.ListRuns(c.Request.Context(), userID, c.Param("ruleId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, runs, "获取执行记录成功")
+}
+
+// Preview 预览重复规则接下来的执行
+// GET /api/v1/recurrence-rules/:ruleId/preview
+func (h *RecurrenceHandler) Preview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	count := 10
+	if raw := c.Query("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("count 必须为正整数"))
+			return
+		}
+		count = n
+	}
+
+	occurrences, err := h.recurrenceService.PreviewRule(c.Request.Context(), userID, c.Param("ruleId"), count)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, occurrences, "获取执行预览成功")
+}
+
+// ListCalendars 列出 Base 的节假日日历
+// GET /api/v1/bases/:baseId/holiday-calendars
+func (h *RecurrenceHandler) ListCalendars(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	calendars, err := h.recurrenceService.ListCalendars(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, calendars, "获取节假日日历成功")
+}
+
+// CreateCalendar 创建节假日日历
+// POST /api/v1/bases/:baseId/holiday-calendars
+func (h *RecurrenceHandler) CreateCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveHolidayCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	calendar, err := h.recurrenceService.CreateCalendar(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, calendar, "创建节假日日历成功")
+}
+
+// UpdateCalendar 更新节假日日历
+// PATCH /api/v1/holiday-calendars/:calendarId
+func (h *RecurrenceHandler) UpdateCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateHolidayCalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	calendar, err := h.recurrenceService.UpdateCalendar(c.Request.Context(), userID, c.Param("calendarId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, calendar, "更新节假日日历成功")
+}
+
+// DeleteCalendar 删除节假日日历
+// DELETE /api/v1/holiday-calendars/:calendarId
+func (h *RecurrenceHandler) DeleteCalendar(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.recurrenceService.DeleteCalendar(c.Request.Context(), userID, c.Param("calendarId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除节假日日历成功")
+}
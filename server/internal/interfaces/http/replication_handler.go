@@ -0,0 +1,122 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ReplicationHandler 复制目标HTTP处理器
+type ReplicationHandler struct {
+	replicationService *application.ReplicationService
+}
+
+// NewReplicationHandler 创建复制目标处理器
+func NewReplicationHandler(replicationService *application.ReplicationService) *ReplicationHandler {
+	return &ReplicationHandler{
+		replicationService: replicationService,
+	}
+}
+
+// CreateTarget 创建复制目标（publication 或 webhook）
+// POST /api/v1/bases/:baseId/replication-targets
+func (h *ReplicationHandler) CreateTarget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateReplicationTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	target, err := h.replicationService.CreateTarget(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, target, "创建复制目标成功")
+}
+
+// ListTargets 列出 Base 的复制目标
+// GET /api/v1/bases/:baseId/replication-targets
+func (h *ReplicationHandler) ListTargets(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	list, err := h.replicationService.ListTargets(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, list, "获取复制目标列表成功")
+}
+
+// GetTarget 获取复制目标及投递状态
+// GET /api/v1/replication-targets/:targetId
+func (h *ReplicationHandler) GetTarget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	target, err := h.replicationService.GetTarget(c.Request.Context(), userID, c.Param("targetId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, target, "获取复制目标成功")
+}
+
+// UpdateTarget 更新复制目标（所选表、启停、推送地址）
+// PATCH /api/v1/replication-targets/:targetId
+func (h *ReplicationHandler) UpdateTarget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateReplicationTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	target, err := h.replicationService.UpdateTarget(c.Request.Context(), userID, c.Param("targetId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, target, "更新复制目标成功")
+}
+
+// DeleteTarget 删除复制目标
+// DELETE /api/v1/replication-targets/:targetId
+func (h *ReplicationHandler) DeleteTarget(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.replicationService.DeleteTarget(c.Request.Context(), userID, c.Param("targetId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除复制目标成功")
+}
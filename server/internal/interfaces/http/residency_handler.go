@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+	"github.com/easyspace-ai/luckdb/server/pkg/tracing"
+)
+
+// ResidencyHandler 数据驻留管理处理器（仅管理员）
+type ResidencyHandler struct {
+	router   *database.RegionRouter
+	migrator *database.RegionMigrator
+}
+
+// NewResidencyHandler 创建数据驻留管理处理器
+func NewResidencyHandler(router *database.RegionRouter) *ResidencyHandler {
+	return &ResidencyHandler{
+		router:   router,
+		migrator: database.NewRegionMigrator(router),
+	}
+}
+
+// BindRegionRequest 绑定/迁移区域请求
+type BindRegionRequest struct {
+	Region string `json:"region" binding:"required"`
+}
+
+// ListRegions 列出可用数据区域
+func (h *ResidencyHandler) ListRegions(c *gin.Context) {
+	response.Success(c, gin.H{
+		"defaultRegion": h.router.DefaultRegion(),
+		"regions":       h.router.Regions(),
+	}, "获取成功")
+}
+
+// GetSpaceRegion 获取空间的数据区域绑定
+func (h *ResidencyHandler) GetSpaceRegion(c *gin.Context) {
+	spaceID := c.Param("spaceId")
+
+	binding, err := h.router.GetBinding(c.Request.Context(), spaceID)
+	if err != nil {
+		response.Error(c, errors.Database(err, ""))
+		return
+	}
+	if binding == nil {
+		response.Success(c, gin.H{
+			"space_id": spaceID,
+			"region":   h.router.DefaultRegion(),
+			"status":   database.SpaceRegionActive,
+		}, "获取成功")
+		return
+	}
+
+	response.Success(c, binding, "获取成功")
+}
+
+// BindSpaceRegion 绑定空间区域（仅用于尚未写入数据的空间）
+func (h *ResidencyHandler) BindSpaceRegion(c *gin.Context) {
+	spaceID := c.Param("spaceId")
+
+	var req BindRegionRequest
+	if err := ValidateBindJSON(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.router.BindSpace(c.Request.Context(), spaceID, req.Region); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	response.Success(c, gin.H{"space_id": spaceID, "region": req.Region}, "绑定成功")
+}
+
+// MigrateSpaceRegion 发起空间跨区域在线迁移（异步执行，通过 GetSpaceRegion 查看进度）
+func (h *ResidencyHandler) MigrateSpaceRegion(c *gin.Context) {
+	spaceID := c.Param("spaceId")
+
+	var req BindRegionRequest
+	if err := ValidateBindJSON(c, &req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	if _, err := h.router.Cluster(req.Region); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	// 迁移不随请求结束取消，保留请求的链路
+	tracing.Go(c.Request.Context(), "residency.migrate_space", func(ctx context.Context) {
+		report, err := h.migrator.MigrateSpace(ctx, spaceID, req.Region)
+		if err != nil {
+			logger.Error("空间跨区域迁移失败",
+				logger.String("space_id", spaceID),
+				logger.String("target_region", req.Region),
+				logger.ErrorField(err))
+			return
+		}
+		logger.Info("空间迁移报告",
+			logger.String("space_id", spaceID),
+			logger.Any("report", report))
+	})
+
+	response.Success(c, gin.H{
+		"space_id":      spaceID,
+		"target_region": req.Region,
+		"status":        database.SpaceRegionMigrating,
+	}, "迁移已开始")
+}
+
+// DropSourceRegionData 清理迁移完成后遗留在源区域的数据
+func (h *ResidencyHandler) DropSourceRegionData(c *gin.Context) {
+	spaceID := c.Param("spaceId")
+	region := c.Param("region")
+
+	if err := h.migrator.DropSourceData(c.Request.Context(), spaceID, region); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	response.Success(c, nil, "清理成功")
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+const (
+	tusVersion         = "1.0.0"
+	tusExtensions      = "creation,termination,expiration"
+	tusOffsetMediaType = "application/offset+octet-stream"
+)
+
+// ResumableUploadHandler 可续传上传HTTP处理器（tus 1.0.0 core + creation/termination/expiration 扩展）
+// 客户端先调用签名接口获取上传令牌，通过 Upload-Metadata 传入 token 与 filename；上传完成后照常调用 notify
+type ResumableUploadHandler struct {
+	uploadService *application.ResumableUploadService
+}
+
+// NewResumableUploadHandler 创建可续传上传处理器
+func NewResumableUploadHandler(uploadService *application.ResumableUploadService) *ResumableUploadHandler {
+	return &ResumableUploadHandler{
+		uploadService: uploadService,
+	}
+}
+
+// Options 返回服务端支持的协议版本与扩展
+// OPTIONS /api/v1/attachments/uploads
+func (h *ResumableUploadHandler) Options(c *gin.Context) {
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", tusExtensions)
+	if maxSize := h.uploadService.MaxSize(); maxSize > 0 {
+		c.Header("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Create 创建上传
+// POST /api/v1/attachments/uploads
+func (h *ResumableUploadHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("Upload-Length 无效"))
+		return
+	}
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	session, err := h.uploadService.Create(c.Request.Context(), userID, application.CreateUploadSessionRequest{
+		Token:    metadata["token"],
+		Filename: metadata["filename"],
+		Length:   length,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+session.ID)
+	setUploadHeaders(c, session)
+	c.Status(http.StatusCreated)
+}
+
+// Head 查询已接收的偏移
+// HEAD /api/v1/attachments/uploads/:uploadId
+func (h *ResumableUploadHandler) Head(c *gin.Context) {
+	session, err := h.uploadService.Get(c.Request.Context(), c.GetString("user_id"), c.Param("uploadId"))
+	if err != nil {
+		c.Header("Tus-Resumable", tusVersion)
+		c.Status(pkgerrors.GetHTTPStatus(err))
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	c.Header("Upload-Length", strconv.FormatInt(session.Length, 10))
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+// Patch 追加分片
+// PATCH /api/v1/attachments/uploads/:uploadId
+func (h *ResumableUploadHandler) Patch(c *gin.Context) {
+	if c.ContentType() != tusOffsetMediaType {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("Content-Type 必须为 "+tusOffsetMediaType))
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("Upload-Offset 无效"))
+		return
+	}
+
+	session, err := h.uploadService.Append(c.Request.Context(), c.GetString("user_id"), c.Param("uploadId"), offset, c.Request.Body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	c.Header("Tus-Resumable", tusVersion)
+	setUploadHeaders(c, session)
+	c.Status(http.StatusNoContent)
+}
+
+// Terminate 取消上传
+// DELETE /api/v1/attachments/uploads/:uploadId
+func (h *ResumableUploadHandler) Terminate(c *gin.Context) {
+	if err := h.uploadService.Terminate(c.Request.Context(), c.GetString("user_id"), c.Param("uploadId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+	c.Header("Tus-Resumable", tusVersion)
+	c.Status(http.StatusNoContent)
+}
+
+// GetProgress 查询上传进度
+// GET /api/v1/attachments/uploads/:uploadId
+func (h *ResumableUploadHandler) GetProgress(c *gin.Context) {
+	progress, err := h.uploadService.Progress(c.Request.Context(), c.GetString("user_id"), c.Param("uploadId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, progress, "获取上传进度成功")
+}
+
+// setUploadHeaders 设置偏移与过期时间响应头
+func setUploadHeaders(c *gin.Context, session *attachment.UploadSession) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// parseUploadMetadata 解析 Upload-Metadata（逗号分隔的 "key base64(value)"）
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata 中 %s 不是有效的 Base64", key)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/richtext"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// maxRichTextConvertBytes 单次转换的内容上限
+const maxRichTextConvertBytes = 1 << 20
+
+// RichTextHandler 富文本转换HTTP处理器
+type RichTextHandler struct{}
+
+// NewRichTextHandler 创建富文本转换处理器
+func NewRichTextHandler() *RichTextHandler {
+	return &RichTextHandler{}
+}
+
+// MarkdownToHTMLRequest Markdown 转 HTML 请求
+type MarkdownToHTMLRequest struct {
+	Markdown string `json:"markdown"`
+}
+
+// HTMLToMarkdownRequest HTML 转 Markdown 请求
+type HTMLToMarkdownRequest struct {
+	HTML string `json:"html"`
+}
+
+// MarkdownToHTML 将 Markdown 转换为净化后的 HTML
+// POST /api/v1/rich-text/markdown-to-html
+func (h *RichTextHandler) MarkdownToHTML(c *gin.Context) {
+	var req MarkdownToHTMLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	if len(req.Markdown) > maxRichTextConvertBytes {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("内容过大"))
+		return
+	}
+
+	response.Success(c, gin.H{"html": richtext.MarkdownToHTML(req.Markdown)}, "转换成功")
+}
+
+// HTMLToMarkdown 将 HTML 净化后转换为 Markdown
+// POST /api/v1/rich-text/html-to-markdown
+func (h *RichTextHandler) HTMLToMarkdown(c *gin.Context) {
+	var req HTMLToMarkdownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	if len(req.HTML) > maxRichTextConvertBytes {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("内容过大"))
+		return
+	}
+
+	response.Success(c, gin.H{"markdown": richtext.HTMLToMarkdown(req.HTML)}, "转换成功")
+}
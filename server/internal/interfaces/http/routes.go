@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"github.com/easyspace-ai/luckdb/server/internal/container"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/security"
+	"github.com/easyspace-ai/luckdb/server/pkg/featureflag"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
 
@@ -23,12 +25,22 @@ func SetupRoutes(router *gin.Engine, cont *container.Container, staticFiles embe
 	// 监控端点（无需认证）
 	setupMonitoringRoutes(v1, cont)
 
+	// 错误码目录（无需认证）✨
+	v1.GET("/errors/catalog", GetErrorCatalog)
+
 	// 认证相关路由（无需JWT中间件）
 	setupAuthRoutes(v1, cont)
 
 	// 需要JWT认证的路由组
 	authRequired := v1.Group("")
 	authRequired.Use(JWTAuthMiddleware(cont.AuthService()))
+	authRequired.Use(UserLocaleMiddleware(cont.UserConfigService()))                                         // 用户时区与语言（相对日期过滤、公式、展示文本）✨
+	authRequired.Use(BusinessCalendarMiddleware(cont.BusinessCalendarService()))                             // 工作空间工作日历（按工作日过滤）✨
+	authRequired.Use(WorkspaceSecurityMiddleware(cont.SecurityService()))                                    // 工作空间安全策略 ✨
+	authRequired.Use(WorkspaceLifecycleMiddleware(cont.WorkspaceLifecycleService(), cont.SecurityService())) // 已停用或计划删除的工作空间只读 ✨
+	authRequired.Use(MaintenanceMiddleware(cont.MaintenanceService()))                                       // 维护模式只读 ✨
+	authRequired.Use(IdempotencyMiddleware(cont.IdempotencyService()))                                       // 写请求幂等键（记录创建、导入、自动化触发等）✨
+	authRequired.Use(UsageMiddleware(cont.UsageService(), cont.PlanService(), cont.SecurityService()))       // 服务令牌 API 调用计数 ✨
 	{
 		// 用户相关路由
 		setupUserRoutes(authRequired, cont)
@@ -48,17 +60,175 @@ func SetupRoutes(router *gin.Engine, cont *container.Container, staticFiles embe
 		// 字段相关路由
 		setupFieldRoutes(authRequired, cont)
 
+		// 表结构变更进度路由 ✨
+		setupSchemaMigrationRoutes(authRequired, cont)
+
+		// Base 结构即代码路由 ✨
+		setupSchemaSpecRoutes(authRequired, cont)
+
+		// Base 沙盒分支路由 ✨
+		setupBaseBranchRoutes(authRequired, cont)
+
 		// 记录相关路由
 		setupRecordRoutes(authRequired, cont)
 
 		// 视图相关路由
 		setupViewRoutes(authRequired, cont)
 
+		// 仪表盘相关路由 ✨
+		setupDashboardRoutes(authRequired, cont)
+
+		// 长时操作路由 ✨
+		setupOperationRoutes(authRequired, cont)
+
+		// 变更流路由（外部同步）✨
+		setupChangeFeedRoutes(authRequired, cont)
+
+		// 复制目标路由（数据仓库同步）✨
+		setupReplicationRoutes(authRequired, cont)
+
+		// 数据仓库同步路由 ✨
+		setupWarehouseSyncRoutes(authRequired, cont)
+
+		// Base 数据导出路由（Parquet）✨
+		setupDataExportRoutes(authRequired, cont)
+
+		// 集成平台路由（Zapier / Make）✨
+		setupIntegrationRoutes(authRequired, cont)
+		setupAutomationScriptRoutes(authRequired, cont)
+		setupDeliveryMonitorRoutes(authRequired, cont)
+
 		// 附件相关路由 ✨
 		setupAttachmentRoutes(authRequired, cont)
 
+		// 数据驻留管理路由（仅管理员）✨
+		setupResidencyRoutes(authRequired, cont)
+		setupDisasterRecoveryRoutes(authRequired, cont)
+
+		// 工作空间停用、计划删除与导出包路由（仅管理员）✨
+		setupWorkspaceLifecycleRoutes(authRequired, cont)
+
+		// 维护模式状态与管理路由 ✨
+		setupMaintenanceRoutes(authRequired, cont)
+
+		// 元数据表在线迁移状态路由（仅管理员）✨
+		setupOnlineMigrationRoutes(authRequired, cont)
+
+		// 支持人员代入 ✨
+		setupImpersonationRoutes(authRequired, cont)
+
+		// 动态表查询统计路由（仅管理员）✨
+		setupQueryStatsRoutes(authRequired, cont)
+
+		// 功能开关与配置热更新路由 ✨
+		setupConfigRoutes(authRequired, cont)
+
+		// PII检测与字段脱敏路由 ✨
+		setupPrivacyRoutes(authRequired, cont)
+
+		// GDPR 数据主体导出与擦除路由（仅空间所有者）✨
+		setupGDPRRoutes(authRequired, cont)
+
+		// 工作空间安全策略路由（仅空间所有者）✨
+		setupSecurityRoutes(authRequired, cont)
+
+		// 嵌入设置与嵌入令牌路由 ✨
+		setupEmbedRoutes(authRequired, cont)
+
+		// 工作空间用量统计路由（仅空间所有者）✨
+		setupUsageRoutes(authRequired, cont)
+
+		// 套餐限额与用量路由 ✨
+		setupBillingRoutes(authRequired, cont)
+
+		// 附件策略与重新扫描路由（仅空间所有者）✨
+		setupAttachmentScanRoutes(authRequired, cont)
+
+		// 可续传附件上传路由（tus 协议）✨
+		setupResumableUploadRoutes(authRequired, cont)
+
+		// 富文本与 Markdown 转换路由 ✨
+		setupRichTextRoutes(authRequired)
+
+		// AI 字段生成与空间配额路由 ✨
+		setupAIFieldRoutes(authRequired, cont)
+
+		// 语义索引与记录搜索路由 ✨
+		setupSemanticSearchRoutes(authRequired, cont)
+
+		// 自然语言查询路由 ✨
+		setupNLQueryRoutes(authRequired, cont)
+
+		// 附件文本提取路由 ✨
+		setupTextExtractionRoutes(authRequired, cont)
+
+		// 记录模板路由 ✨
+		setupRecordTemplateRoutes(authRequired, cont)
+
+		// 记录编辑锁路由 ✨
+		setupRecordLockRoutes(authRequired, cont)
+
+		// 状态流（审批流）路由 ✨
+		setupStatusFlowRoutes(authRequired, cont)
+
+		// 记录归档路由 ✨
+		setupRecordArchiveRoutes(authRequired, cont)
+
+		// 按时间点恢复记录或列路由 ✨
+		setupPointInTimeRestoreRoutes(authRequired, cont)
+
+		// 重复记录规则路由 ✨
+		setupRecurrenceRoutes(authRequired, cont)
+
+		// 工作空间工作日历路由 ✨
+		setupBusinessCalendarRoutes(authRequired, cont)
+
+		// 永久链接路由 ✨
+		setupPermalinkRoutes(authRequired, cont)
+
+		// 记录提及的反向链接路由 ✨
+		setupBacklinkRoutes(authRequired, cont)
+
+		// PDF 打印路由 ✨
+		setupPrintRoutes(authRequired, cont)
+
+		// 文档模板路由 ✨
+		setupDocumentTemplateRoutes(authRequired, cont)
+
+		// 扫码路由 ✨
+		setupScanRoutes(authRequired, cont)
+
+		// 离线同步路由 ✨
+		setupOfflineSyncRoutes(authRequired, cont)
+
+		// 表健康检查路由 ✨
+		setupTableHealthRoutes(authRequired, cont)
+
+		// 批量查找替换路由 ✨
+		setupFindReplaceRoutes(authRequired, cont)
+
+		// 单元格值批量校验路由 ✨
+		setupCellValidationRoutes(authRequired, cont)
+
+		// 同步表路由 ✨
+		setupSyncedTableRoutes(authRequired, cont)
+
+		// 外部数据同步路由 ✨
+		setupExternalSyncRoutes(authRequired, cont)
+
+		// 界面页面路由 ✨
+		setupPageRoutes(authRequired, cont)
 	}
 
+	// 嵌入访问路由（使用嵌入令牌，无需登录）✨
+	setupEmbedAccessRoutes(v1, cont)
+
+	// Stripe webhook（按签名校验，无需登录）✨
+	setupStripeWebhookRoutes(v1, cont)
+
+	// 管理控制台路由（独立认证域，使用控制台令牌）✨
+	setupAdminConsoleRoutes(v1, cont)
+
 	// WebSocket 路由（需要认证）✨
 	//setupWebSocketRoutes(router, cont)
 
@@ -98,7 +268,7 @@ func setupSpaceRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		spaces.GET("", handler.ListSpaces)
 		spaces.GET("/:spaceId", handler.GetSpace)
 		spaces.PATCH("/:spaceId", handler.UpdateSpace) // ✅ 部分更新使用PATCH
-		spaces.DELETE("/:spaceId", handler.DeleteSpace)
+		spaces.DELETE("/:spaceId", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationSchemaDelete), handler.DeleteSpace)
 
 		// Space协作者管理 ✨
 		spaces.GET("/:spaceId/collaborators", collabHandler.ListSpaceCollaborators)
@@ -125,7 +295,7 @@ func setupBaseRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	{
 		bases.GET("/:baseId", handler.GetBase)
 		bases.PATCH("/:baseId", handler.UpdateBase)
-		bases.DELETE("/:baseId", handler.DeleteBase)
+		bases.DELETE("/:baseId", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationSchemaDelete), handler.DeleteBase)
 
 		// Base子资源
 		// Note: GET /:baseId/tables 由TableHandler处理（避免重复注册）
@@ -142,7 +312,8 @@ func setupBaseRoutes(rg *gin.RouterGroup, cont *container.Container) {
 
 // setupTableRoutes 设置表格路由
 func setupTableRoutes(rg *gin.RouterGroup, cont *container.Container) {
-	handler := NewTableHandler(cont.TableService())
+	handler := NewTableHandler(cont.TableService(), cont.ReferenceService())
+	chartHandler := NewChartHandler(cont.ChartService())
 
 	// Base下的表格
 	bases := rg.Group("/bases")
@@ -156,25 +327,30 @@ func setupTableRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	{
 		tables.GET("/:tableId", handler.GetTable)
 		tables.PATCH("/:tableId", handler.UpdateTable) // ✅ 部分更新使用PATCH
-		tables.DELETE("/:tableId", handler.DeleteTable)
+		tables.DELETE("/:tableId", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationSchemaDelete), handler.DeleteTable)
 
 		// 表管理路由
-		tables.PUT("/:tableId/rename", handler.RenameTable)          // 重命名表
-		tables.POST("/:tableId/duplicate", handler.DuplicateTable)   // 复制表
-		tables.GET("/:tableId/usage", handler.GetTableUsage)         // 获取表用量
-		tables.GET("/:tableId/menu", handler.GetTableManagementMenu) // 获取表管理菜单
+		tables.PUT("/:tableId/rename", handler.RenameTable)              // 重命名表
+		tables.POST("/:tableId/duplicate", handler.DuplicateTable)       // 复制表
+		tables.GET("/:tableId/usage", handler.GetTableUsage)             // 获取表用量
+		tables.GET("/:tableId/menu", handler.GetTableManagementMenu)     // 获取表管理菜单
+		tables.GET("/:tableId/references", handler.GetTableReferences)   // 获取引用该表的对象 ✨
+		tables.POST("/:tableId/chart-data", chartHandler.QueryChartData) // 图表数据聚合 ✨
 	}
 }
 
 // setupFieldRoutes 设置字段路由
 func setupFieldRoutes(rg *gin.RouterGroup, cont *container.Container) {
-	handler := NewFieldHandler(cont.FieldService())
+	handler := NewFieldHandler(cont.FieldService(), cont.ReferenceService())
+	impactHandler := NewSchemaImpactHandler(cont.SchemaImpactService())
 
 	// 表格下的字段
 	tables := rg.Group("/tables")
 	{
 		tables.GET("/:tableId/fields", handler.ListFields)
+		tables.GET("/:tableId/fields/delta", handler.ListFieldsDelta) // 按表结构版本获取字段增量 ✨
 		tables.POST("/:tableId/fields", handler.CreateField)
+		tables.PATCH("/:tableId/fields/order", handler.ReorderFields) // 批量调整字段顺序 ✨
 	}
 
 	// 字段路由
@@ -182,8 +358,47 @@ func setupFieldRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	{
 		fields.GET("/:fieldId", handler.GetField)
 		fields.PATCH("/:fieldId", handler.UpdateField) // ✅ 部分更新使用PATCH
-		fields.DELETE("/:fieldId", handler.DeleteField)
+		fields.DELETE("/:fieldId", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationSchemaDelete), handler.DeleteField)
+
+		// 破坏性操作影响预览（不执行变更）✨
+		fields.GET("/:fieldId/delete-preview", impactHandler.PreviewDeleteField)
+		fields.GET("/:fieldId/convert-preview", impactHandler.PreviewConvertField)
+		fields.GET("/:fieldId/references", handler.GetFieldReferences)
 	}
+
+	// 字段类型的选项 Schema（客户端据此渲染选项编辑器）✨
+	rg.GET("/field-option-schemas", handler.ListFieldOptionSchemas)
+	rg.GET("/field-option-schemas/:fieldType", handler.GetFieldOptionSchema)
+}
+
+// setupSchemaMigrationRoutes 设置表结构变更进度路由
+func setupSchemaMigrationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSchemaMigrationHandler(cont.SchemaMigrationService())
+
+	rg.GET("/tables/:tableId/schema-migrations", handler.ListTableMigrations)
+	rg.GET("/schema-migrations/:migrationId", handler.GetMigration)
+	rg.POST("/schema-migrations/:migrationId/retry", handler.RetryMigration)
+}
+
+// setupSchemaSpecRoutes 设置 Base 结构描述路由
+func setupSchemaSpecRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSchemaSpecHandler(cont.SchemaSpecService())
+
+	rg.GET("/bases/:baseId/schema", handler.ExportSchema)
+	rg.POST("/bases/:baseId/schema/diff", handler.DiffSchema)
+	rg.POST("/bases/:baseId/schema/apply", handler.ApplySchema)
+}
+
+// setupBaseBranchRoutes 设置 Base 沙盒分支路由
+func setupBaseBranchRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBaseBranchHandler(cont.BaseBranchService())
+
+	rg.POST("/bases/:baseId/branches", handler.CreateBranch)
+	rg.GET("/bases/:baseId/branches", handler.ListBranches)
+	rg.GET("/base-branches/:branchId", handler.GetBranch)
+	rg.GET("/base-branches/:branchId/diff", handler.DiffBranch)
+	rg.POST("/base-branches/:branchId/promote", handler.PromoteBranch)
+	rg.DELETE("/base-branches/:branchId", handler.DiscardBranch)
 }
 
 // setupRecordRoutes 设置记录路由
@@ -202,15 +417,22 @@ func setupRecordRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		tables.GET("/:tableId/records", handler.ListRecords)
 		tables.POST("/:tableId/records", handler.CreateRecord)
 		tables.POST("/:tableId/records/batch", handler.BatchCreateRecords)
+		tables.GET("/:tableId/records/snapshot", handler.ListRecordsSnapshot) // 按时间点读取（一致性分页导出）
+		tables.GET("/:tableId/records/archived", handler.ListArchivedRecords) // 归档记录 ✨
 
 		// 单条记录操作（需要 tableId 和 recordId）
 		tables.GET("/:tableId/records/:recordId", handler.GetRecord)
 		tables.PATCH("/:tableId/records/:recordId", handler.UpdateRecord) // ✅ 对齐 Teable
 		tables.DELETE("/:tableId/records/:recordId", handler.DeleteRecord)
+		tables.GET("/:tableId/records/:recordId/fields/:fieldId/history", handler.GetCellHistory) // 单元格修改历史 ✨
 
 		// 批量操作
 		tables.PATCH("/:tableId/records/batch", handler.BatchUpdateRecords)
 		tables.DELETE("/:tableId/records/batch", handler.BatchDeleteRecords)
+
+		// 混合批量变更（POST /tables/:tableId/records:batch）
+		// gin 不支持路径段内的字面冒号，这里用段内参数承接，由处理器校验后缀为 ":batch"
+		tables.POST("/:tableId/records:action", handler.MutateRecords)
 	}
 
 	// 记录路由（保留旧路由以兼容，但标记为废弃）
@@ -254,12 +476,27 @@ func setupAuthRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		auth.POST("/logout", handler.Logout)        // 登出
 		auth.POST("/refresh", handler.RefreshToken) // 刷新Token
 		auth.GET("/me", handler.GetCurrentUser)     // 获取当前用户信息
+
+		// 会话管理（需要认证）✨
+		session := auth.Group("", JWTAuthMiddleware(cont.AuthService()))
+		session.POST("/reauth", handler.Reauthenticate)               // 重新认证
+		session.GET("/sessions", handler.ListSessions)                // 活跃会话列表
+		session.DELETE("/sessions/:sessionId", handler.RevokeSession) // 吊销会话
+
+		// 服务令牌（CLI、CI 等非交互客户端）✨
+		tokenHandler := NewServiceTokenHandler(cont.ServiceTokenService())
+		session.POST("/service-tokens", tokenHandler.CreateToken)
+		session.GET("/service-tokens", tokenHandler.ListTokens)
+		session.DELETE("/service-tokens/:tokenId", tokenHandler.RevokeToken)
 	}
 }
 
 // setupViewRoutes 设置视图路由
 func setupViewRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	handler := NewViewHandler(cont.ViewService())
+	summaryHandler := NewViewSummaryHandler(cont.ViewSummaryService())
+	projectionHandler := NewViewProjectionHandler(cont.ViewProjectionService())
+	windowHandler := NewViewWindowHandler(cont.ViewWindowService())
 
 	// 表格下的视图
 	tables := rg.Group("/tables")
@@ -297,6 +534,15 @@ func setupViewRoutes(rg *gin.RouterGroup, cont *container.Container) {
 
 		// 复制功能
 		views.POST("/:viewId/duplicate", handler.DuplicateView) // 复制视图
+
+		// 汇总栏
+		views.GET("/:viewId/summary", summaryHandler.GetViewSummary) // 记录数与字段汇总 ✨
+
+		// 展示行（预先解析选项、协作者与关联标题）
+		views.GET("/:viewId/display-rows", projectionHandler.ListRows) // 按视图顺序分页 ✨
+
+		// 虚拟滚动窗口（行窗口 + 分组边界 + 总数）
+		views.GET("/:viewId/window", FeatureGateMiddleware(featureflag.KeyFilterEngine, cont.SecurityService()), windowHandler.GetWindow) // 按偏移读取行窗口 ✨
 	}
 
 	// 分享视图访问
@@ -306,6 +552,499 @@ func setupViewRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	}
 }
 
+// setupOperationRoutes 设置长时操作路由
+func setupOperationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewOperationHandler(cont.OperationService())
+
+	rg.GET("/bases/:baseId/operations", handler.ListOperations)
+	rg.POST("/bases/:baseId/operations", handler.StartOperation)
+	rg.GET("/operations/:operationId", handler.GetOperation)
+	rg.POST("/operations/:operationId/cancel", handler.CancelOperation)
+}
+
+// setupChangeFeedRoutes 设置变更流路由
+func setupChangeFeedRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewChangeFeedHandler(cont.ChangeFeedService())
+
+	rg.GET("/bases/:baseId/changes", handler.ListChanges)
+	rg.GET("/bases/:baseId/changes/head", handler.GetHead)
+}
+
+// setupReplicationRoutes 设置复制目标路由
+func setupReplicationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewReplicationHandler(cont.ReplicationService())
+
+	rg.POST("/bases/:baseId/replication-targets", handler.CreateTarget)
+	rg.GET("/bases/:baseId/replication-targets", handler.ListTargets)
+	rg.GET("/replication-targets/:targetId", handler.GetTarget)
+	rg.PATCH("/replication-targets/:targetId", handler.UpdateTarget)
+	rg.DELETE("/replication-targets/:targetId", handler.DeleteTarget)
+}
+
+// setupWarehouseSyncRoutes 设置数据仓库同步路由
+func setupWarehouseSyncRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewWarehouseSyncHandler(cont.WarehouseSyncService())
+
+	rg.POST("/bases/:baseId/warehouse-syncs", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationDataExport), handler.CreateJob)
+	rg.GET("/bases/:baseId/warehouse-syncs", handler.ListJobs)
+	rg.GET("/warehouse-syncs/:jobId", handler.GetJob)
+	rg.PATCH("/warehouse-syncs/:jobId", handler.UpdateJob)
+	rg.DELETE("/warehouse-syncs/:jobId", handler.DeleteJob)
+	rg.POST("/warehouse-syncs/:jobId/run", handler.TriggerJob)
+	rg.GET("/warehouse-syncs/:jobId/runs", handler.ListRuns)
+}
+
+// setupDataExportRoutes 设置 Base 数据导出路由
+func setupDataExportRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewDataExportHandler(cont.DataExportService())
+
+	rg.POST("/bases/:baseId/exports", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationDataExport), handler.CreateExport)
+	rg.GET("/bases/:baseId/exports", handler.ListExports)
+	rg.GET("/data-exports/:exportId", handler.GetExport)
+}
+
+// setupIntegrationRoutes 设置集成平台路由（触发器、REST Hook 与动作）
+func setupIntegrationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewIntegrationHandler(cont.IntegrationService())
+
+	integrations := rg.Group("/integrations")
+	{
+		integrations.GET("/tables/:tableId/triggers/:event", handler.PollTrigger)
+		integrations.POST("/tables/:tableId/hooks", handler.Subscribe)
+		integrations.DELETE("/hooks/:hookId", handler.Unsubscribe)
+		integrations.PUT("/hooks/:hookId/template", handler.UpdateHookTemplate)
+		integrations.GET("/tables/:tableId/input-fields", handler.InputFields)
+		integrations.POST("/tables/:tableId/actions/create-record", handler.CreateRecord)
+		integrations.POST("/tables/:tableId/actions/update-record", handler.UpdateRecord)
+		integrations.POST("/tables/:tableId/actions/find-records", handler.FindRecords)
+	}
+}
+
+// setupDeliveryMonitorRoutes 设置推送保障与死信管理路由
+func setupDeliveryMonitorRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewDeliveryMonitorHandler(cont.DeliveryMonitorService())
+
+	rg.GET("/delivery-monitor/overview", handler.Overview)
+	rg.GET("/integrations/deliveries", handler.ListDeliveries)
+	rg.POST("/integrations/deliveries/retry", handler.RetryDeliveries)
+	rg.POST("/integrations/hooks/:hookId/resume", handler.ResumeHook)
+	rg.PATCH("/integrations/hooks/:hookId/dead-letter", handler.UpdateDeadLetter)
+	rg.GET("/automation-runs/failed", handler.ListFailedRuns)
+	rg.POST("/automation-runs/retry", handler.RetryRuns)
+}
+
+// setupAutomationScriptRoutes 设置自动化运行脚本路由
+func setupAutomationScriptRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewAutomationScriptHandler(cont.ScriptActionService())
+
+	rg.POST("/automation-scripts/run", handler.TestRun)
+}
+
+// setupEmbedRoutes 设置嵌入设置与嵌入令牌路由
+func setupEmbedRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewEmbedHandler(cont.EmbedService())
+
+	rg.GET("/spaces/:spaceId/embed-settings", handler.GetSettings)
+	rg.PUT("/spaces/:spaceId/embed-settings", handler.UpdateSettings)
+	rg.POST("/spaces/:spaceId/embed-settings/revoke-tokens", handler.RevokeTokens)
+	rg.POST("/views/:viewId/embed-tokens", handler.CreateToken)
+}
+
+// setupUsageRoutes 设置工作空间用量统计路由
+func setupUsageRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewUsageHandler(cont.UsageService())
+
+	rg.GET("/spaces/:spaceId/usage", handler.GetSpaceUsage)
+}
+
+// setupBillingRoutes 设置套餐限额与用量路由
+func setupBillingRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBillingHandler(cont.PlanService(), cont.BillingService())
+
+	rg.GET("/billing/plans", handler.ListPlans)
+	rg.GET("/spaces/:spaceId/billing/usage", handler.GetSpaceUsage) // 仅空间所有者
+	rg.GET("/spaces/:spaceId/billing/subscription", handler.GetSubscription)
+	rg.POST("/spaces/:spaceId/billing/portal-session", handler.CreatePortalSession)
+
+	admin := rg.Group("/admin/spaces/:spaceId/billing")
+	admin.Use(AdminRequiredMiddleware())
+	{
+		admin.GET("/plan", handler.AdminGetSpacePlan)
+		admin.PUT("/plan", handler.AdminAssignPlan)
+	}
+}
+
+// setupStripeWebhookRoutes 设置 Stripe webhook 路由
+func setupStripeWebhookRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBillingHandler(cont.PlanService(), cont.BillingService())
+
+	rg.POST("/billing/stripe/webhook", handler.StripeWebhook)
+}
+
+// setupAdminConsoleRoutes 设置管理控制台路由（登录无需认证，其余接口需要控制台令牌）
+func setupAdminConsoleRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewAdminConsoleHandler(cont.AdminConsoleService())
+
+	console := rg.Group("/console")
+	console.POST("/auth/login", handler.Login)
+
+	authed := console.Group("")
+	authed.Use(AdminConsoleAuthMiddleware(cont.AdminConsoleService()))
+	{
+		authed.GET("/auth/session", handler.GetSession)
+		authed.POST("/auth/logout", handler.Logout)
+
+		authed.GET("/spaces", handler.ListSpaces)
+		authed.GET("/spaces/:spaceId/usage", handler.GetSpaceUsage)
+		authed.POST("/spaces/:spaceId/transfer-ownership", handler.TransferSpaceOwnership)
+
+		authed.GET("/users", handler.ListUsers)
+		authed.POST("/users/:userId/reset-password", handler.ResetUserPassword)
+
+		authed.GET("/stats", handler.GetStats)
+
+		authed.GET("/features", handler.ListFeatures)
+		authed.PUT("/features/:key", handler.SetFeature)
+		authed.DELETE("/features/:key", handler.ClearFeature)
+	}
+}
+
+// setupAttachmentScanRoutes 设置附件策略与重新扫描路由
+func setupAttachmentScanRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewAttachmentScanHandler(cont.AttachmentScanService())
+
+	rg.GET("/spaces/:spaceId/attachment-policy", handler.GetPolicy)
+	rg.PUT("/spaces/:spaceId/attachment-policy", handler.UpdatePolicy)
+	rg.POST("/attachments/:id/rescan", handler.Rescan)
+}
+
+// setupAIFieldRoutes 设置 AI 字段生成与空间配额路由
+func setupAIFieldRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewAIFieldHandler(cont.AIFieldService())
+
+	rg.POST("/fields/:fieldId/ai/regenerate", handler.Regenerate)
+	rg.GET("/fields/:fieldId/ai/jobs", handler.ListJobs)
+	rg.GET("/spaces/:spaceId/ai-quota", handler.GetQuota)
+	rg.PUT("/spaces/:spaceId/ai-quota", handler.UpdateQuota)
+}
+
+// setupSemanticSearchRoutes 设置语义索引与记录搜索路由
+func setupSemanticSearchRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSemanticSearchHandler(cont.SemanticSearchService())
+
+	rg.GET("/tables/:tableId/semantic-index", handler.GetIndex)
+	rg.PUT("/tables/:tableId/semantic-index", handler.PutIndex)
+	rg.DELETE("/tables/:tableId/semantic-index", handler.DeleteIndex)
+	rg.POST("/tables/:tableId/semantic-search", handler.Search)
+}
+
+// setupNLQueryRoutes 设置自然语言查询路由
+func setupNLQueryRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewNLQueryHandler(cont.NLQueryService())
+
+	rg.POST("/tables/:tableId/nl-query", handler.Query)
+}
+
+// setupTextExtractionRoutes 设置附件文本提取规则与任务路由
+func setupTextExtractionRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewTextExtractionHandler(cont.TextExtractionService())
+
+	rg.GET("/tables/:tableId/text-extraction-rules", handler.ListRules)
+	rg.POST("/tables/:tableId/text-extraction-rules", handler.CreateRule)
+	rg.DELETE("/text-extraction-rules/:ruleId", handler.DeleteRule)
+	rg.POST("/text-extraction-rules/:ruleId/run", handler.Run)
+	rg.GET("/text-extraction-rules/:ruleId/jobs", handler.ListJobs)
+}
+
+// setupRecordTemplateRoutes 设置记录模板路由
+func setupRecordTemplateRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewRecordTemplateHandler(cont.RecordTemplateService())
+
+	rg.GET("/tables/:tableId/record-templates", handler.List)
+	rg.POST("/tables/:tableId/record-templates", handler.Create)
+	rg.PATCH("/record-templates/:templateId", handler.Update)
+	rg.DELETE("/record-templates/:templateId", handler.Delete)
+	rg.GET("/record-templates/:templateId/values", handler.Resolve)
+	rg.POST("/record-templates/:templateId/records", handler.CreateRecord)
+}
+
+// setupRecordLockRoutes 设置记录编辑锁路由
+func setupRecordLockRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewRecordLockHandler(cont.RecordLockService())
+
+	rg.GET("/tables/:tableId/record-locks", handler.List)
+	rg.POST("/tables/:tableId/records/:recordId/lock", handler.Lock)
+	rg.DELETE("/tables/:tableId/record-locks/:lockId", handler.Unlock)
+}
+
+// setupStatusFlowRoutes 设置状态流（审批流）路由
+func setupStatusFlowRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewStatusFlowHandler(cont.StatusFlowService())
+
+	rg.GET("/tables/:tableId/status-flows", handler.List)
+	rg.PUT("/tables/:tableId/status-flows/:fieldId", handler.Save)
+	rg.DELETE("/tables/:tableId/status-flows/:fieldId", handler.Delete)
+}
+
+// setupRecordArchiveRoutes 设置记录归档策略与恢复路由
+func setupRecordArchiveRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewRecordArchiveHandler(cont.RecordArchiveService())
+
+	rg.GET("/tables/:tableId/archive-policy", handler.GetPolicy)
+	rg.PUT("/tables/:tableId/archive-policy", handler.SavePolicy)
+	rg.DELETE("/tables/:tableId/archive-policy", handler.DeletePolicy)
+	rg.POST("/tables/:tableId/archive-policy/run", handler.Run)
+	rg.POST("/tables/:tableId/records/archived/restore", handler.Restore)
+}
+
+// setupPointInTimeRestoreRoutes 设置按时间点恢复路由
+func setupPointInTimeRestoreRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewPointInTimeRestoreHandler(cont.PointInTimeRestoreService())
+
+	rg.POST("/tables/:tableId/point-in-time-restore/preview", handler.Preview)
+	rg.POST("/tables/:tableId/point-in-time-restore", handler.Restore)
+}
+
+// setupRecurrenceRoutes 设置重复记录规则与节假日日历路由
+func setupRecurrenceRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewRecurrenceHandler(cont.RecurrenceService())
+
+	rg.GET("/tables/:tableId/recurrence-rules", handler.ListRules)
+	rg.POST("/tables/:tableId/recurrence-rules", handler.CreateRule)
+	rg.PATCH("/recurrence-rules/:ruleId", handler.UpdateRule)
+	rg.DELETE("/recurrence-rules/:ruleId", handler.DeleteRule)
+	rg.GET("/recurrence-rules/:ruleId/runs", handler.ListRuns)
+	rg.GET("/recurrence-rules/:ruleId/preview", handler.Preview)
+
+	rg.GET("/bases/:baseId/holiday-calendars", handler.ListCalendars)
+	rg.POST("/bases/:baseId/holiday-calendars", handler.CreateCalendar)
+	rg.PATCH("/holiday-calendars/:calendarId", handler.UpdateCalendar)
+	rg.DELETE("/holiday-calendars/:calendarId", handler.DeleteCalendar)
+}
+
+// setupBusinessCalendarRoutes 设置工作空间工作日历路由
+func setupBusinessCalendarRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBusinessCalendarHandler(cont.BusinessCalendarService())
+
+	rg.GET("/spaces/:spaceId/business-calendar", handler.GetCalendar)
+	rg.PUT("/spaces/:spaceId/business-calendar", handler.SaveCalendar)
+	rg.DELETE("/spaces/:spaceId/business-calendar", handler.ResetCalendar)
+}
+
+// setupPermalinkRoutes 设置记录与视图永久链接路由
+func setupPermalinkRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewPermalinkHandler(cont.PermalinkService())
+
+	rg.POST("/permalinks", handler.CreatePermalink)
+	rg.GET("/permalinks/unfurl", handler.Unfurl)
+	rg.GET("/permalinks/:code", handler.ResolvePermalink)
+	rg.GET("/permalinks/:code/preview", handler.PreviewPermalink)
+}
+
+// setupBacklinkRoutes 设置记录反向链接路由
+func setupBacklinkRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBacklinkHandler(cont.BacklinkService())
+
+	rg.GET("/tables/:tableId/records/:recordId/backlinks", handler.List)
+}
+
+// setupPrintRoutes 设置记录与视图的 PDF 打印路由
+func setupPrintRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewPrintHandler(cont.PrintService())
+
+	rg.POST("/print-jobs", handler.CreateJob)
+	rg.GET("/print-jobs/:jobId", handler.GetJob)
+	rg.GET("/tables/:tableId/print-jobs", handler.ListJobs)
+}
+
+// setupDocumentTemplateRoutes 设置文档模板与文档生成路由
+func setupDocumentTemplateRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewDocumentTemplateHandler(cont.DocumentTemplateService())
+
+	rg.GET("/tables/:tableId/document-templates", handler.ListTemplates)
+	rg.POST("/tables/:tableId/document-templates", handler.CreateTemplate)
+
+	rg.GET("/document-templates/:templateId", handler.GetTemplate)
+	rg.PATCH("/document-templates/:templateId", handler.UpdateTemplate)
+	rg.DELETE("/document-templates/:templateId", handler.DeleteTemplate)
+	rg.POST("/document-templates/:templateId/generate", handler.Generate)
+	rg.GET("/document-templates/:templateId/jobs", handler.ListJobs)
+	rg.GET("/document-jobs/:jobId", handler.GetJob)
+}
+
+// setupScanRoutes 设置扫码查找记录与扫码规则路由
+func setupScanRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewScanHandler(cont.ScanService())
+
+	rg.POST("/tables/:tableId/scans", handler.Scan)
+	rg.GET("/tables/:tableId/scan-rules", handler.ListRules)
+	rg.PUT("/tables/:tableId/scan-rules/:fieldId", handler.SaveRule)
+	rg.DELETE("/tables/:tableId/scan-rules/:fieldId", handler.DeleteRule)
+}
+
+// setupOfflineSyncRoutes 设置移动端离线同步路由（增量变更复用 /bases/:baseId/changes）
+func setupOfflineSyncRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewOfflineSyncHandler(cont.OfflineSyncService())
+
+	rg.GET("/bases/:baseId/offline-sync/snapshot", handler.Snapshot)
+	rg.POST("/bases/:baseId/offline-sync/push", handler.Push)
+}
+
+// setupTableHealthRoutes 设置表健康检查路由
+func setupTableHealthRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewTableHealthHandler(cont.TableHealthService())
+
+	rg.POST("/tables/:tableId/health-reports", handler.RunCheck)
+	rg.GET("/tables/:tableId/health-reports", handler.ListReports)
+	rg.GET("/tables/:tableId/health-schedule", handler.GetSchedule)
+	rg.PUT("/tables/:tableId/health-schedule", handler.UpdateSchedule)
+
+	rg.GET("/health-reports/:reportId", handler.GetReport)
+	rg.GET("/health-reports/:reportId/issues", handler.ListIssues)
+	rg.POST("/health-reports/:reportId/fixes", handler.CreateFixJob)
+	rg.GET("/health-fix-jobs/:jobId", handler.GetFixJob)
+}
+
+// setupFindReplaceRoutes 设置批量查找替换路由
+// 替换与撤销通过 POST /bases/:baseId/operations 发起（type 为 find_replace / find_replace_undo）
+func setupFindReplaceRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewFindReplaceHandler(cont.FindReplaceService())
+
+	rg.POST("/tables/:tableId/find-replace/preview", handler.Preview)
+}
+
+// setupCellValidationRoutes 设置单元格值批量校验路由
+func setupCellValidationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewCellValidationHandler(cont.CellValidationService())
+
+	rg.POST("/tables/:tableId/records/validate", handler.ValidateCells)
+}
+
+// setupSyncedTableRoutes 设置同步表路由
+func setupSyncedTableRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSyncedTableHandler(cont.SyncedTableService())
+
+	rg.POST("/bases/:baseId/synced-tables", handler.CreateSync)
+	rg.GET("/bases/:baseId/synced-tables", handler.ListSyncs)
+	rg.GET("/synced-tables/:syncId", handler.GetSync)
+	rg.PATCH("/synced-tables/:syncId", handler.UpdateSync)
+	rg.DELETE("/synced-tables/:syncId", handler.DeleteSync)
+	rg.POST("/synced-tables/:syncId/run", handler.TriggerSync)
+}
+
+// setupExternalSyncRoutes 设置外部数据同步路由
+func setupExternalSyncRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewExternalSyncHandler(cont.ExternalSyncService())
+
+	rg.POST("/bases/:baseId/external-syncs", handler.CreateConnector)
+	rg.GET("/bases/:baseId/external-syncs", handler.ListConnectors)
+	rg.GET("/external-syncs/:connectorId", handler.GetConnector)
+	rg.PATCH("/external-syncs/:connectorId", handler.UpdateConnector)
+	rg.DELETE("/external-syncs/:connectorId", handler.DeleteConnector)
+	rg.POST("/external-syncs/:connectorId/run", handler.TriggerConnector)
+	rg.GET("/external-syncs/:connectorId/runs", handler.ListRuns)
+}
+
+// setupPageRoutes 设置界面页面路由
+// 页面配置需要 Base 编辑权限；渲染、记录读写与按钮按页面分享规则校验
+func setupPageRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewPageHandler(cont.PageService())
+
+	rg.POST("/bases/:baseId/pages", handler.CreatePage)
+	rg.GET("/bases/:baseId/pages", handler.ListPages)
+
+	pages := rg.Group("/pages")
+	{
+		pages.GET("/:pageId", handler.GetPage)
+		pages.PATCH("/:pageId", handler.UpdatePage)
+		pages.DELETE("/:pageId", handler.DeletePage)
+		pages.GET("/:pageId/render", handler.RenderPage)
+		pages.GET("/:pageId/elements/:elementId/records", handler.ListElementRecords)
+		pages.GET("/:pageId/elements/:elementId/records/:recordId", handler.GetElementRecord)
+		pages.PATCH("/:pageId/elements/:elementId/records/:recordId", handler.UpdateElementRecord)
+		pages.POST("/:pageId/elements/:elementId/run", handler.RunButton)
+	}
+}
+
+// setupResumableUploadRoutes 设置可续传上传路由
+func setupResumableUploadRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewResumableUploadHandler(cont.ResumableUploadService())
+
+	uploads := rg.Group("/attachments/uploads")
+	{
+		uploads.OPTIONS("", handler.Options)
+		uploads.POST("", handler.Create)
+		uploads.HEAD("/:uploadId", handler.Head)
+		uploads.PATCH("/:uploadId", handler.Patch)
+		uploads.DELETE("/:uploadId", handler.Terminate)
+		uploads.GET("/:uploadId", handler.GetProgress)
+	}
+}
+
+// setupRichTextRoutes 设置富文本转换路由
+func setupRichTextRoutes(rg *gin.RouterGroup) {
+	handler := NewRichTextHandler()
+
+	richText := rg.Group("/rich-text")
+	{
+		richText.POST("/markdown-to-html", handler.MarkdownToHTML)
+		richText.POST("/html-to-markdown", handler.HTMLToMarkdown)
+	}
+}
+
+// setupEmbedAccessRoutes 设置嵌入访问路由（CORS 预检由 EmbedMiddleware 按空间设置处理）
+func setupEmbedAccessRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewEmbedHandler(cont.EmbedService())
+
+	embed := rg.Group("/embed")
+	embed.Use(EmbedMiddleware(cont.EmbedService()))
+	{
+		embed.GET("/view", handler.GetView)
+		embed.GET("/records", handler.ListRecords)
+		embed.PATCH("/records/:recordId", handler.UpdateRecord)
+		embed.OPTIONS("/*path", func(c *gin.Context) {}) // 预检在中间件中完成
+	}
+}
+
+// setupDashboardRoutes 设置仪表盘路由
+
+func setupDashboardRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewDashboardHandler(cont.DashboardService())
+
+	// Base下的仪表盘
+	bases := rg.Group("/bases")
+	{
+		bases.GET("/:baseId/dashboards", handler.ListDashboards)
+		bases.POST("/:baseId/dashboards", handler.CreateDashboard)
+	}
+
+	dashboards := rg.Group("/dashboards")
+	{
+		dashboards.GET("/:dashboardId", handler.GetDashboard)
+		dashboards.PATCH("/:dashboardId", handler.RenameDashboard)
+		dashboards.DELETE("/:dashboardId", handler.DeleteDashboard)
+		dashboards.PATCH("/:dashboardId/layout", handler.UpdateLayout)
+		dashboards.POST("/:dashboardId/duplicate", handler.DuplicateDashboard)
+
+		// 分享功能
+		dashboards.POST("/:dashboardId/enable-share", handler.EnableShare)
+		dashboards.POST("/:dashboardId/disable-share", handler.DisableShare)
+
+		// 组件
+		dashboards.POST("/:dashboardId/widgets", handler.AddWidget)
+		dashboards.PATCH("/:dashboardId/widgets/:widgetId", handler.UpdateWidget)
+		dashboards.DELETE("/:dashboardId/widgets/:widgetId", handler.DeleteWidget)
+		dashboards.GET("/:dashboardId/widgets/:widgetId/data", handler.GetWidgetData) // 图表数据 / 视图汇总
+	}
+
+	// 分享仪表盘访问
+	share := rg.Group("/share")
+	{
+		share.GET("/dashboards/:shareId", handler.GetSharedDashboard)
+		share.GET("/dashboards/:shareId/widgets/:widgetId/data", handler.GetSharedWidgetData)
+	}
+}
+
 // setupAttachmentRoutes 设置附件路由 ✨
 func setupAttachmentRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	handler := NewAttachmentHandler(cont.AttachmentService(), logger.Logger)
@@ -348,6 +1087,176 @@ func setupAttachmentRoutes(rg *gin.RouterGroup, cont *container.Container) {
 // setupWebSocketRoutes 设置WebSocket路由 ✨
 // 旧 WebSocket 路由已移除
 
+// setupResidencyRoutes 设置数据驻留管理路由（未启用数据驻留时不注册）
+func setupResidencyRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	regionRouter := cont.RegionRouter()
+	if regionRouter == nil {
+		return
+	}
+	handler := NewResidencyHandler(regionRouter)
+
+	residency := rg.Group("/admin/residency")
+	residency.Use(AdminRequiredMiddleware())
+	{
+		residency.GET("/regions", handler.ListRegions)
+		residency.GET("/spaces/:spaceId", handler.GetSpaceRegion)
+		residency.PUT("/spaces/:spaceId", handler.BindSpaceRegion)
+		residency.POST("/spaces/:spaceId/migrate", handler.MigrateSpaceRegion)
+		residency.DELETE("/spaces/:spaceId/regions/:region", handler.DropSourceRegionData)
+	}
+}
+
+// setupDisasterRecoveryRoutes 设置跨区域灾备路由（未启用灾备时不注册）
+func setupDisasterRecoveryRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	service := cont.DisasterRecoveryService()
+	if service == nil {
+		return
+	}
+	handler := NewDisasterRecoveryHandler(service)
+
+	dr := rg.Group("/admin/disaster-recovery")
+	dr.Use(AdminRequiredMiddleware())
+	{
+		dr.GET("/status", handler.GetStatus)
+		dr.GET("/runbook", handler.GetRunbook)
+		dr.POST("/promote", handler.Promote)
+	}
+}
+
+// setupMaintenanceRoutes 设置维护模式路由
+func setupMaintenanceRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewMaintenanceHandler(cont.MaintenanceService())
+
+	rg.GET("/maintenance/status", handler.GetStatus)
+
+	admin := rg.Group("/admin/maintenance")
+	admin.Use(AdminRequiredMiddleware())
+	{
+		admin.GET("", handler.GetOverview)
+		admin.POST("", handler.StartWindow)
+		admin.POST("/:windowId/end", handler.EndWindow)
+	}
+}
+
+// setupOnlineMigrationRoutes 设置在线迁移状态路由
+func setupOnlineMigrationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewOnlineMigrationHandler(cont.OnlineMigrationService())
+
+	admin := rg.Group("/admin/migrations")
+	admin.Use(AdminRequiredMiddleware())
+	{
+		admin.GET("/status", handler.GetStatus)
+	}
+}
+
+// setupWorkspaceLifecycleRoutes 设置工作空间生命周期路由
+func setupWorkspaceLifecycleRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewWorkspaceLifecycleHandler(cont.WorkspaceLifecycleService())
+
+	admin := rg.Group("/admin")
+	admin.Use(AdminRequiredMiddleware())
+	{
+		admin.GET("/workspace-lifecycles", handler.ListLifecycles)
+
+		lifecycle := admin.Group("/spaces/:spaceId/lifecycle")
+		lifecycle.GET("", handler.GetLifecycle)
+		lifecycle.POST("/suspend", handler.Suspend)
+		lifecycle.POST("/resume", handler.Resume)
+		lifecycle.POST("/schedule-deletion", handler.ScheduleDeletion)
+		lifecycle.POST("/cancel-deletion", handler.CancelDeletion)
+		lifecycle.POST("/bundles", handler.CreateBundle)
+		lifecycle.GET("/bundles", handler.ListBundles)
+		lifecycle.GET("/bundles/:bundleId", handler.GetBundle)
+	}
+}
+
+// setupImpersonationRoutes 设置支持人员代入路由
+func setupImpersonationRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewImpersonationHandler(cont.ImpersonationService())
+
+	impersonation := rg.Group("/impersonation")
+	{
+		impersonation.POST("/sessions", handler.StartSession)
+		impersonation.GET("/sessions", handler.ListSessions)
+		impersonation.POST("/sessions/:sessionId/end", handler.EndSession)
+		impersonation.GET("/received", handler.ListReceived) // 以当前用户身份进行的代入
+	}
+}
+
+// setupQueryStatsRoutes 设置慢查询与动态表查询统计路由
+func setupQueryStatsRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewQueryStatsHandler(cont.QueryStatsService())
+
+	stats := rg.Group("/admin/query-stats")
+	stats.Use(AdminRequiredMiddleware())
+	{
+		stats.GET("", handler.GetSummary)
+		stats.GET("/tables", handler.ListTables)
+		stats.GET("/bases", handler.ListBases)
+		stats.GET("/slow-queries", handler.ListSlowQueries)
+		stats.DELETE("", handler.Reset)
+	}
+}
+
+// setupConfigRoutes 设置功能开关与配置热更新路由
+func setupConfigRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewConfigHandler(cont.ConfigService())
+
+	rg.GET("/features", handler.GetMyFeatures)
+
+	admin := rg.Group("/admin")
+	admin.Use(AdminRequiredMiddleware())
+	{
+		admin.GET("/features", handler.ListFeatures)
+		admin.GET("/features/:key/evaluate", handler.EvaluateFeature)
+		admin.GET("/config/status", handler.GetStatus)
+		admin.POST("/config/reload", handler.Reload)
+
+		cacheHandler := NewCacheHandler(cont.CacheService())
+		admin.POST("/cache/flush", cacheHandler.FlushCache)
+	}
+}
+
+// setupPrivacyRoutes 设置PII检测与字段脱敏路由
+func setupPrivacyRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewPrivacyHandler(cont.PrivacyService())
+
+	tables := rg.Group("/tables")
+	{
+		tables.POST("/:tableId/pii-scan", handler.ScanTable)
+		tables.GET("/:tableId/pii-findings", handler.ListFindings)
+		tables.GET("/:tableId/masking-policies", handler.ListMaskingPolicies)
+		tables.PUT("/:tableId/masking-policies/:fieldId", handler.SetMaskingPolicy)
+		tables.DELETE("/:tableId/masking-policies/:fieldId", handler.DeleteMaskingPolicy)
+	}
+}
+
+// setupGDPRRoutes 设置数据主体请求路由
+func setupGDPRRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewGDPRHandler(cont.GDPRService())
+
+	spaces := rg.Group("/spaces")
+	{
+		spaces.GET("/:spaceId/gdpr/subject", handler.FindSubjectData)
+		spaces.GET("/:spaceId/gdpr/export", SensitiveOperationMiddleware(cont.SecurityService(), security.OperationDataExport), handler.ExportSubjectData)
+		spaces.GET("/:spaceId/gdpr/erasures", handler.ListErasureJobs)
+		spaces.POST("/:spaceId/gdpr/erasures", handler.StartErasure)
+		spaces.GET("/:spaceId/gdpr/erasures/:jobId", handler.GetErasureJob)
+		spaces.POST("/:spaceId/gdpr/erasures/:jobId/verify", handler.VerifyErasureJob)
+	}
+}
+
+// setupSecurityRoutes 设置工作空间安全策略路由
+func setupSecurityRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSecurityHandler(cont.SecurityService())
+
+	spaces := rg.Group("/spaces")
+	{
+		spaces.GET("/:spaceId/security-policy", handler.GetPolicy)
+		spaces.PUT("/:spaceId/security-policy", handler.UpdatePolicy)
+	}
+}
+
 // setupMonitoringRoutes 设置监控路由
 func setupMonitoringRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	handler := NewMonitoringHandler(cont.DB())
@@ -420,7 +1329,7 @@ func setupRealtimeRoutes(router *gin.Engine, cont *container.Container) {
 
 	// SSE 路由（需要认证）
 	router.GET("/api/realtime", JWTAuthMiddleware(cont.AuthService()), cont.RealtimeManager().HandleSSE)
-	router.POST("/api/realtime", JWTAuthMiddleware(cont.AuthService()), cont.RealtimeManager().HandleSSESubscription)
+	router.POST("/api/realtime", JWTAuthMiddleware(cont.AuthService()), RealtimeSecurityMiddleware(cont.SecurityService()), cont.RealtimeManager().HandleSSESubscription)
 }
 
 // setupShareDBRoutes 设置 ShareDB 路由
@@ -455,8 +1364,8 @@ func setupWebSocketRoutes(router *gin.Engine, cont *container.Container) {
 	}
 
 	// ShareDB 协作 WebSocket 路由（需要认证）
-	router.GET("/socket", JWTAuthMiddleware(cont.AuthService()), cont.RealtimeManager().HandleShareDBWebSocket)
-	router.GET("/socket/*path", JWTAuthMiddleware(cont.AuthService()), cont.RealtimeManager().HandleShareDBWebSocket)
+	router.GET("/socket", JWTAuthMiddleware(cont.AuthService()), RealtimeSecurityMiddleware(cont.SecurityService()), cont.RealtimeManager().HandleShareDBWebSocket)
+	router.GET("/socket/*path", JWTAuthMiddleware(cont.AuthService()), RealtimeSecurityMiddleware(cont.SecurityService()), cont.RealtimeManager().HandleShareDBWebSocket)
 
 }
 
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ScanHandler 扫码HTTP处理器
+type ScanHandler struct {
+	scanService *application.ScanService
+}
+
+// NewScanHandler 创建扫码处理器
+func NewScanHandler(scanService *application.ScanService) *ScanHandler {
+	return &ScanHandler{
+		scanService: scanService,
+	}
+}
+
+// Scan 按扫到的编码查找记录（未找到时按字段的扫码规则新建）
+// POST /api/v1/tables/:tableId/scans
+func (h *ScanHandler) Scan(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.ScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.scanService.Scan(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "扫码成功")
+}
+
+// ListRules 列出表的扫码规则
+// GET /api/v1/tables/:tableId/scan-rules
+func (h *ScanHandler) ListRules(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	rules, err := h.scanService.ListRules(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rules, "获取扫码规则成功")
+}
+
+// SaveRule 创建或更新字段的扫码规则
+// PUT /api/v1/tables/:tableId/scan-rules/:fieldId
+func (h *ScanHandler) SaveRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveScanRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	rule, err := h.scanService.SaveRule(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "保存扫码规则成功")
+}
+
+// DeleteRule 删除字段的扫码规则
+// DELETE /api/v1/tables/:tableId/scan-rules/:fieldId
+func (h *ScanHandler) DeleteRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.scanService.DeleteRule(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除扫码规则成功")
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SchemaImpactHandler 破坏性表结构操作影响预览HTTP处理器
+type SchemaImpactHandler struct {
+	schemaImpactService *application.SchemaImpactService
+}
+
+// NewSchemaImpactHandler 创建影响预览处理器
+func NewSchemaImpactHandler(schemaImpactService *application.SchemaImpactService) *SchemaImpactHandler {
+	return &SchemaImpactHandler{
+		schemaImpactService: schemaImpactService,
+	}
+}
+
+// PreviewDeleteField 预览删除字段的影响（不执行删除）
+func (h *SchemaImpactHandler) PreviewDeleteField(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	preview, err := h.schemaImpactService.PreviewDeleteField(c.Request.Context(), userID, c.Param("fieldId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "获取删除影响成功")
+}
+
+// PreviewConvertField 预览字段类型转换的影响（?type=目标类型，不执行转换）
+func (h *SchemaImpactHandler) PreviewConvertField(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	targetType := c.Query("type")
+	if targetType == "" {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("缺少目标类型参数 type"))
+		return
+	}
+
+	preview, err := h.schemaImpactService.PreviewConvertField(c.Request.Context(), userID, c.Param("fieldId"), targetType)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "获取类型转换影响成功")
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemamigration"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SchemaMigrationHandler 表结构变更进度HTTP处理器
+type SchemaMigrationHandler struct {
+	schemaMigrationService *application.SchemaMigrationService
+}
+
+// NewSchemaMigrationHandler 创建表结构变更处理器
+func NewSchemaMigrationHandler(schemaMigrationService *application.SchemaMigrationService) *SchemaMigrationHandler {
+	return &SchemaMigrationHandler{
+		schemaMigrationService: schemaMigrationService,
+	}
+}
+
+// schemaMigrationResponse 结构变更任务（附带回填进度）
+type schemaMigrationResponse struct {
+	*schemamigration.Migration
+	Progress float64 `json:"progress"`
+}
+
+func toSchemaMigrationResponse(m *schemamigration.Migration) schemaMigrationResponse {
+	return schemaMigrationResponse{Migration: m, Progress: m.Progress()}
+}
+
+// ListTableMigrations 获取表格最近的结构变更任务
+func (h *SchemaMigrationHandler) ListTableMigrations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	migrations, err := h.schemaMigrationService.ListTableMigrations(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	list := make([]schemaMigrationResponse, 0, len(migrations))
+	for _, m := range migrations {
+		list = append(list, toSchemaMigrationResponse(m))
+	}
+	response.Success(c, list, "获取表结构变更成功")
+}
+
+// GetMigration 获取结构变更任务进度
+func (h *SchemaMigrationHandler) GetMigration(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	m, err := h.schemaMigrationService.GetMigration(c.Request.Context(), userID, c.Param("migrationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSchemaMigrationResponse(m), "获取表结构变更成功")
+}
+
+// RetryMigration 重新执行失败的结构变更任务（处理冲突数据后调用）
+func (h *SchemaMigrationHandler) RetryMigration(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	m, err := h.schemaMigrationService.RetryMigration(c.Request.Context(), userID, c.Param("migrationId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, toSchemaMigrationResponse(m), "已重新执行表结构变更")
+}
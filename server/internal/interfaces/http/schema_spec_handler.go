@@ -0,0 +1,86 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SchemaSpecHandler Base 结构描述（schema as code）处理器
+type SchemaSpecHandler struct {
+	schemaSpecService *application.SchemaSpecService
+}
+
+// NewSchemaSpecHandler 创建结构描述处理器
+func NewSchemaSpecHandler(schemaSpecService *application.SchemaSpecService) *SchemaSpecHandler {
+	return &SchemaSpecHandler{schemaSpecService: schemaSpecService}
+}
+
+// ExportSchema 导出 Base 结构描述（YAML）
+// GET /api/v1/bases/:baseId/schema
+func (h *SchemaSpecHandler) ExportSchema(c *gin.Context) {
+	spec, err := h.schemaSpecService.Export(c.Request.Context(), c.GetString("user_id"), c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	data, err := spec.Marshal()
+	if err != nil {
+		response.Error(c, pkgerrors.ErrInternalServer.WithDetails(err.Error()))
+		return
+	}
+
+	response.Success(c, &application.SchemaSpecDocument{YAML: string(data)}, "导出结构描述成功")
+}
+
+// DiffSchema 对比结构描述与 Base 当前结构
+// POST /api/v1/bases/:baseId/schema/diff
+func (h *SchemaSpecHandler) DiffSchema(c *gin.Context) {
+	spec, req, ok := bindSchemaSpec(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.schemaSpecService.Diff(c.Request.Context(), c.GetString("user_id"), c.Param("baseId"), spec, req.Prune)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, plan, "对比结构描述成功")
+}
+
+// ApplySchema 应用结构描述（幂等，已一致时不做任何变更）
+// POST /api/v1/bases/:baseId/schema/apply
+func (h *SchemaSpecHandler) ApplySchema(c *gin.Context) {
+	spec, req, ok := bindSchemaSpec(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.schemaSpecService.Apply(c.Request.Context(), c.GetString("user_id"), c.Param("baseId"), spec, req.Prune)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, plan, "应用结构描述成功")
+}
+
+// bindSchemaSpec 解析请求中的结构描述
+func bindSchemaSpec(c *gin.Context) (*schemaspec.Spec, *application.SchemaSpecRequest, bool) {
+	var req application.SchemaSpecRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return nil, nil, false
+	}
+	spec, err := schemaspec.Parse([]byte(req.YAML))
+	if err != nil {
+		response.Error(c, pkgerrors.ErrValidationFailed.WithDetails(err.Error()))
+		return nil, nil, false
+	}
+	return spec, &req, true
+}
@@ -0,0 +1,61 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SecurityHandler 工作空间安全策略HTTP处理器
+type SecurityHandler struct {
+	securityService *application.SecurityService
+}
+
+// NewSecurityHandler 创建工作空间安全策略处理器
+func NewSecurityHandler(securityService *application.SecurityService) *SecurityHandler {
+	return &SecurityHandler{
+		securityService: securityService,
+	}
+}
+
+// GetPolicy 获取空间安全策略
+func (h *SecurityHandler) GetPolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	policy, err := h.securityService.GetPolicy(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "获取安全策略成功")
+}
+
+// UpdatePolicy 更新空间安全策略
+func (h *SecurityHandler) UpdatePolicy(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateSecurityPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	policy, err := h.securityService.UpdatePolicy(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, policy, "安全策略已更新")
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SemanticSearchHandler 语义索引与记录搜索HTTP处理器
+type SemanticSearchHandler struct {
+	semanticSearchService *application.SemanticSearchService
+}
+
+// NewSemanticSearchHandler 创建语义搜索处理器
+func NewSemanticSearchHandler(semanticSearchService *application.SemanticSearchService) *SemanticSearchHandler {
+	return &SemanticSearchHandler{
+		semanticSearchService: semanticSearchService,
+	}
+}
+
+// GetIndex 获取表的语义索引（未建立时返回 null）
+// GET /api/v1/tables/:tableId/semantic-index
+func (h *SemanticSearchHandler) GetIndex(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	index, err := h.semanticSearchService.GetIndex(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, index, "获取语义索引成功")
+}
+
+// PutIndex 创建或修改表的语义索引
+// PUT /api/v1/tables/:tableId/semantic-index
+func (h *SemanticSearchHandler) PutIndex(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.PutSemanticIndexRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	index, err := h.semanticSearchService.PutIndex(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, index, "保存语义索引成功")
+}
+
+// DeleteIndex 删除表的语义索引
+// DELETE /api/v1/tables/:tableId/semantic-index
+func (h *SemanticSearchHandler) DeleteIndex(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.semanticSearchService.DeleteIndex(c.Request.Context(), userID, c.Param("tableId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除语义索引成功")
+}
+
+// Search 搜索表中的记录（语义检索与关键词匹配）
+// POST /api/v1/tables/:tableId/semantic-search
+func (h *SemanticSearchHandler) Search(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SemanticSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	result, err := h.semanticSearchService.Search(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "搜索成功")
+}
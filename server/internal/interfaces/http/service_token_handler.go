@@ -0,0 +1,83 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ServiceTokenHandler 服务令牌处理器
+type ServiceTokenHandler struct {
+	serviceTokenService *application.ServiceTokenService
+}
+
+// NewServiceTokenHandler 创建服务令牌处理器
+func NewServiceTokenHandler(serviceTokenService *application.ServiceTokenService) *ServiceTokenHandler {
+	return &ServiceTokenHandler{serviceTokenService: serviceTokenService}
+}
+
+// CreateToken 创建服务令牌（明文只在响应中返回一次）
+// POST /api/v1/auth/service-tokens
+func (h *ServiceTokenHandler) CreateToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+	// 服务令牌不能再签发服务令牌，须使用登录令牌
+	if claims := contextClaims(c); claims != nil && claims.ServiceTokenID != "" {
+		response.Error(c, pkgerrors.ErrForbidden.WithDetails("请使用登录令牌创建服务令牌"))
+		return
+	}
+
+	var req application.CreateServiceTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	token, err := h.serviceTokenService.CreateToken(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, token, "创建服务令牌成功")
+}
+
+// ListTokens 列出当前用户的服务令牌
+// GET /api/v1/auth/service-tokens
+func (h *ServiceTokenHandler) ListTokens(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	tokens, err := h.serviceTokenService.ListTokens(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tokens, "获取服务令牌列表成功")
+}
+
+// RevokeToken 吊销服务令牌
+// DELETE /api/v1/auth/service-tokens/:tokenId
+func (h *ServiceTokenHandler) RevokeToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.serviceTokenService.RevokeToken(c.Request.Context(), userID, c.Param("tokenId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "服务令牌已吊销")
+}
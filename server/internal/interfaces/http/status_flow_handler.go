@@ -0,0 +1,80 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// StatusFlowHandler 状态流HTTP处理器
+type StatusFlowHandler struct {
+	statusFlowService *application.StatusFlowService
+}
+
+// NewStatusFlowHandler 创建状态流处理器
+func NewStatusFlowHandler(statusFlowService *application.StatusFlowService) *StatusFlowHandler {
+	return &StatusFlowHandler{
+		statusFlowService: statusFlowService,
+	}
+}
+
+// List 列出表的状态流
+// GET /api/v1/tables/:tableId/status-flows
+func (h *StatusFlowHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	flows, err := h.statusFlowService.ListFlows(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, flows, "获取状态流成功")
+}
+
+// Save 创建或更新单选字段的状态流
+// PUT /api/v1/tables/:tableId/status-flows/:fieldId
+func (h *StatusFlowHandler) Save(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SaveStatusFlowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	flow, err := h.statusFlowService.SaveFlow(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, flow, "保存状态流成功")
+}
+
+// Delete 删除字段的状态流
+// DELETE /api/v1/tables/:tableId/status-flows/:fieldId
+func (h *StatusFlowHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.statusFlowService.DeleteFlow(c.Request.Context(), userID, c.Param("tableId"), c.Param("fieldId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除状态流成功")
+}
@@ -0,0 +1,140 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// SyncedTableHandler 同步表HTTP处理器
+type SyncedTableHandler struct {
+	syncedTableService *application.SyncedTableService
+}
+
+// NewSyncedTableHandler 创建同步表处理器
+func NewSyncedTableHandler(syncedTableService *application.SyncedTableService) *SyncedTableHandler {
+	return &SyncedTableHandler{
+		syncedTableService: syncedTableService,
+	}
+}
+
+// CreateSync 在目标 Base 中创建镜像其他 Base 视图的同步表
+// POST /api/v1/bases/:baseId/synced-tables
+func (h *SyncedTableHandler) CreateSync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateSyncedTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	sync, err := h.syncedTableService.CreateSync(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sync, "创建同步表成功")
+}
+
+// ListSyncs 列出 Base 中的同步表
+// GET /api/v1/bases/:baseId/synced-tables
+func (h *SyncedTableHandler) ListSyncs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	syncs, err := h.syncedTableService.ListSyncs(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, syncs, "获取同步表列表成功")
+}
+
+// GetSync 获取同步表及最近一次同步状态
+// GET /api/v1/synced-tables/:syncId
+func (h *SyncedTableHandler) GetSync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	sync, err := h.syncedTableService.GetSync(c.Request.Context(), userID, c.Param("syncId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sync, "获取同步表成功")
+}
+
+// UpdateSync 更新同步表
+// PATCH /api/v1/synced-tables/:syncId
+func (h *SyncedTableHandler) UpdateSync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateSyncedTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	sync, err := h.syncedTableService.UpdateSync(c.Request.Context(), userID, c.Param("syncId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sync, "更新同步表成功")
+}
+
+// TriggerSync 立即同步一次
+// POST /api/v1/synced-tables/:syncId/run
+func (h *SyncedTableHandler) TriggerSync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	sync, err := h.syncedTableService.TriggerSync(c.Request.Context(), userID, c.Param("syncId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, sync, "已安排同步")
+}
+
+// DeleteSync 停止同步（镜像表保留并恢复为可编辑）
+// DELETE /api/v1/synced-tables/:syncId
+func (h *SyncedTableHandler) DeleteSync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.syncedTableService.DeleteSync(c.Request.Context(), userID, c.Param("syncId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除同步表成功")
+}
@@ -12,13 +12,15 @@ import (
 
 // TableHandler 表格HTTP处理器
 type TableHandler struct {
-	tableService *application.TableService
+	tableService     *application.TableService
+	referenceService *application.ReferenceService
 }
 
 // NewTableHandler 创建表格处理器
-func NewTableHandler(tableService *application.TableService) *TableHandler {
+func NewTableHandler(tableService *application.TableService, referenceService *application.ReferenceService) *TableHandler {
 	return &TableHandler{
-		tableService: tableService,
+		tableService:     tableService,
+		referenceService: referenceService,
 	}
 }
 
@@ -78,9 +80,27 @@ func (h *TableHandler) UpdateTable(c *gin.Context) {
 }
 
 // DeleteTable 删除表格
+// DELETE /api/v1/tables/:tableId?cascade=block|detach|delete
 func (h *TableHandler) DeleteTable(c *gin.Context) {
 	tableID := c.Param("tableId")
 
+	if h.referenceService != nil {
+		policy, err := application.ParseCascadePolicy(c.Query("cascade"))
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		result, err := h.referenceService.DeleteTable(c.Request.Context(), c.GetString("user_id"), tableID, policy)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+
+		response.Success(c, result, "删除表格成功")
+		return
+	}
+
 	if err := h.tableService.DeleteTable(c.Request.Context(), tableID); err != nil {
 		response.Error(c, err)
 		return
@@ -89,6 +109,24 @@ func (h *TableHandler) DeleteTable(c *gin.Context) {
 	response.Success(c, nil, "删除表格成功")
 }
 
+// GetTableReferences 获取其他表格中引用了该表格的字段、视图、自动化和 Webhook
+// GET /api/v1/tables/:tableId/references
+func (h *TableHandler) GetTableReferences(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	report, err := h.referenceService.TableReferences(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report, "获取表格引用成功")
+}
+
 // ListTables 列出Base下的所有表格
 func (h *TableHandler) ListTables(c *gin.Context) {
 	baseID := c.Param("baseId")
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/tablehealth"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// TableHealthHandler 表健康检查HTTP处理器
+type TableHealthHandler struct {
+	tableHealthService *application.TableHealthService
+}
+
+// NewTableHealthHandler 创建表健康检查处理器
+func NewTableHealthHandler(tableHealthService *application.TableHealthService) *TableHealthHandler {
+	return &TableHealthHandler{
+		tableHealthService: tableHealthService,
+	}
+}
+
+// RunCheck 发起健康检查
+// POST /api/v1/tables/:tableId/health-reports
+func (h *TableHealthHandler) RunCheck(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	report, err := h.tableHealthService.RunCheck(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report, "发起健康检查成功")
+}
+
+// ListReports 列出表的检查报告
+// GET /api/v1/tables/:tableId/health-reports
+func (h *TableHealthHandler) ListReports(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	reports, err := h.tableHealthService.ListReports(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, reports, "获取健康检查报告成功")
+}
+
+// GetReport 获取检查报告
+// GET /api/v1/health-reports/:reportId
+func (h *TableHealthHandler) GetReport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	report, err := h.tableHealthService.GetReport(c.Request.Context(), userID, c.Param("reportId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report, "获取健康检查报告成功")
+}
+
+// ListIssues 分页列出报告中的问题
+// GET /api/v1/health-reports/:reportId/issues?kind=&fieldId=&status=&limit=&offset=
+func (h *TableHealthHandler) ListIssues(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	filter := tablehealth.IssueFilter{
+		Kind:    tablehealth.IssueKind(c.Query("kind")),
+		FieldID: c.Query("fieldId"),
+		Status:  tablehealth.IssueStatus(c.Query("status")),
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("limit 必须为正整数"))
+			return
+		}
+		filter.Limit = n
+	}
+	if raw := c.Query("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails("offset 必须为非负整数"))
+			return
+		}
+		filter.Offset = n
+	}
+
+	issues, err := h.tableHealthService.ListIssues(c.Request.Context(), userID, c.Param("reportId"), filter)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, issues, "获取健康检查问题成功")
+}
+
+// CreateFixJob 创建一键修复任务
+// POST /api/v1/health-reports/:reportId/fixes
+func (h *TableHealthHandler) CreateFixJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateTableHealthFixRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	job, err := h.tableHealthService.CreateFixJob(c.Request.Context(), userID, c.Param("reportId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "创建修复任务成功")
+}
+
+// GetFixJob 获取修复任务
+// GET /api/v1/health-fix-jobs/:jobId
+func (h *TableHealthHandler) GetFixJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.tableHealthService.GetFixJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "获取修复任务成功")
+}
+
+// GetSchedule 获取定时检查设置
+// GET /api/v1/tables/:tableId/health-schedule
+func (h *TableHealthHandler) GetSchedule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	schedule, err := h.tableHealthService.GetSchedule(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, schedule, "获取定时检查设置成功")
+}
+
+// UpdateSchedule 更新定时检查设置
+// PUT /api/v1/tables/:tableId/health-schedule
+func (h *TableHealthHandler) UpdateSchedule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateTableHealthScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	schedule, err := h.tableHealthService.UpdateSchedule(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, schedule, "更新定时检查设置成功")
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// TextExtractionHandler 附件文本提取HTTP处理器
+type TextExtractionHandler struct {
+	textExtractionService *application.TextExtractionService
+}
+
+// NewTextExtractionHandler 创建附件文本提取处理器
+func NewTextExtractionHandler(textExtractionService *application.TextExtractionService) *TextExtractionHandler {
+	return &TextExtractionHandler{
+		textExtractionService: textExtractionService,
+	}
+}
+
+// ListRules 列出表的附件文本提取规则
+// GET /api/v1/tables/:tableId/text-extraction-rules
+func (h *TextExtractionHandler) ListRules(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	rules, err := h.textExtractionService.ListRules(c.Request.Context(), userID, c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rules, "获取文本提取规则成功")
+}
+
+// CreateRule 创建附件文本提取规则
+// POST /api/v1/tables/:tableId/text-extraction-rules
+func (h *TextExtractionHandler) CreateRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateTextExtractionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	rule, err := h.textExtractionService.CreateRule(c.Request.Context(), userID, c.Param("tableId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "创建文本提取规则成功")
+}
+
+// DeleteRule 删除附件文本提取规则
+// DELETE /api/v1/text-extraction-rules/:ruleId
+func (h *TextExtractionHandler) DeleteRule(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.textExtractionService.DeleteRule(c.Request.Context(), userID, c.Param("ruleId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除文本提取规则成功")
+}
+
+// Run 手动执行提取（不指定记录时处理整表）
+// POST /api/v1/text-extraction-rules/:ruleId/run
+func (h *TextExtractionHandler) Run(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.RunTextExtractionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	result, err := h.textExtractionService.Run(c.Request.Context(), userID, c.Param("ruleId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "已提交文本提取")
+}
+
+// ListJobs 列出提取规则最近的任务
+// GET /api/v1/text-extraction-rules/:ruleId/jobs?recordId=
+func (h *TextExtractionHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.textExtractionService.ListJobs(c.Request.Context(), userID, c.Param("ruleId"), c.Query("recordId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取文本提取任务成功")
+}
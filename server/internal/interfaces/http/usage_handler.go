@@ -0,0 +1,33 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// UsageHandler 工作空间用量统计HTTP处理器
+type UsageHandler struct {
+	usageService *application.UsageService
+}
+
+// NewUsageHandler 创建用量统计处理器
+func NewUsageHandler(usageService *application.UsageService) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetSpaceUsage 获取空间用量报表（仅空间所有者）
+// GET /api/v1/spaces/:spaceId/usage?from=2006-01-02&to=2006-01-02
+func (h *UsageHandler) GetSpaceUsage(c *gin.Context) {
+	report, err := h.usageService.GetSpaceReport(c.Request.Context(), c.GetString("user_id"), c.Param("spaceId"),
+		c.Query("from"), c.Query("to"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, report, "获取用量统计成功")
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ViewProjectionHandler 视图展示行HTTP处理器
+type ViewProjectionHandler struct {
+	viewProjectionService *application.ViewProjectionService
+}
+
+// NewViewProjectionHandler 创建视图展示行处理器
+func NewViewProjectionHandler(viewProjectionService *application.ViewProjectionService) *ViewProjectionHandler {
+	return &ViewProjectionHandler{
+		viewProjectionService: viewProjectionService,
+	}
+}
+
+// ListRows 按视图顺序分页获取展示就绪的行
+// GET /api/v1/views/:viewId/display-rows?offset=0&limit=100
+func (h *ViewProjectionHandler) ListRows(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	page, err := h.viewProjectionService.GetRows(c.Request.Context(), userID, c.Param("viewId"), offset, limit)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, page, "获取视图展示行成功")
+}
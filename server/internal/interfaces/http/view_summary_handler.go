@@ -0,0 +1,50 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ViewSummaryHandler 视图汇总栏HTTP处理器
+type ViewSummaryHandler struct {
+	viewSummaryService *application.ViewSummaryService
+}
+
+// NewViewSummaryHandler 创建视图汇总处理器
+func NewViewSummaryHandler(viewSummaryService *application.ViewSummaryService) *ViewSummaryHandler {
+	return &ViewSummaryHandler{
+		viewSummaryService: viewSummaryService,
+	}
+}
+
+// GetViewSummary 获取视图当前筛选条件下的记录数与字段汇总
+// GET /api/v1/views/:viewId/summary?fieldIds=fld1,fld2
+func (h *ViewSummaryHandler) GetViewSummary(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var fieldIDs []string
+	if raw := c.Query("fieldIds"); raw != "" {
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				fieldIDs = append(fieldIDs, id)
+			}
+		}
+	}
+
+	summary, err := h.viewSummaryService.GetViewSummary(c.Request.Context(), userID, c.Param("viewId"), fieldIDs)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, summary, "获取视图汇总成功")
+}
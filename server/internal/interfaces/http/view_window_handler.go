@@ -0,0 +1,44 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ViewWindowHandler 视图窗口HTTP处理器
+type ViewWindowHandler struct {
+	viewWindowService *application.ViewWindowService
+}
+
+// NewViewWindowHandler 创建视图窗口处理器
+func NewViewWindowHandler(viewWindowService *application.ViewWindowService) *ViewWindowHandler {
+	return &ViewWindowHandler{
+		viewWindowService: viewWindowService,
+	}
+}
+
+// GetWindow 按视图的过滤、分组与排序读取一个行窗口及其分组边界
+// GET /api/v1/views/:viewId/window?offset=0&count=100
+func (h *ViewWindowHandler) GetWindow(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	count, _ := strconv.Atoi(c.Query("count"))
+
+	window, err := h.viewWindowService.GetWindow(c.Request.Context(), userID, c.Param("viewId"), offset, count)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, window, "获取视图窗口成功")
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// WarehouseSyncHandler 数据仓库同步HTTP处理器
+type WarehouseSyncHandler struct {
+	warehouseSyncService *application.WarehouseSyncService
+}
+
+// NewWarehouseSyncHandler 创建数据仓库同步处理器
+func NewWarehouseSyncHandler(warehouseSyncService *application.WarehouseSyncService) *WarehouseSyncHandler {
+	return &WarehouseSyncHandler{
+		warehouseSyncService: warehouseSyncService,
+	}
+}
+
+// CreateJob 创建同步任务
+// POST /api/v1/bases/:baseId/warehouse-syncs
+func (h *WarehouseSyncHandler) CreateJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.CreateWarehouseSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	job, err := h.warehouseSyncService.CreateJob(c.Request.Context(), userID, c.Param("baseId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "创建同步任务成功")
+}
+
+// ListJobs 列出 Base 的同步任务
+// GET /api/v1/bases/:baseId/warehouse-syncs
+func (h *WarehouseSyncHandler) ListJobs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	jobs, err := h.warehouseSyncService.ListJobs(c.Request.Context(), userID, c.Param("baseId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, jobs, "获取同步任务列表成功")
+}
+
+// GetJob 获取同步任务及最近一次运行状态
+// GET /api/v1/warehouse-syncs/:jobId
+func (h *WarehouseSyncHandler) GetJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.warehouseSyncService.GetJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "获取同步任务成功")
+}
+
+// UpdateJob 更新同步任务
+// PATCH /api/v1/warehouse-syncs/:jobId
+func (h *WarehouseSyncHandler) UpdateJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.UpdateWarehouseSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	job, err := h.warehouseSyncService.UpdateJob(c.Request.Context(), userID, c.Param("jobId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "更新同步任务成功")
+}
+
+// TriggerJob 立即执行一次同步
+// POST /api/v1/warehouse-syncs/:jobId/run
+func (h *WarehouseSyncHandler) TriggerJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	job, err := h.warehouseSyncService.TriggerJob(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "已安排同步")
+}
+
+// ListRuns 获取同步运行记录
+// GET /api/v1/warehouse-syncs/:jobId/runs
+func (h *WarehouseSyncHandler) ListRuns(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	runs, err := h.warehouseSyncService.ListRuns(c.Request.Context(), userID, c.Param("jobId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, runs, "获取同步记录成功")
+}
+
+// DeleteJob 删除同步任务
+// DELETE /api/v1/warehouse-syncs/:jobId
+func (h *WarehouseSyncHandler) DeleteJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	if err := h.warehouseSyncService.DeleteJob(c.Request.Context(), userID, c.Param("jobId")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "删除同步任务成功")
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// WorkspaceLifecycleHandler 工作空间生命周期处理器（仅管理员）
+type WorkspaceLifecycleHandler struct {
+	service *application.WorkspaceLifecycleService
+}
+
+// NewWorkspaceLifecycleHandler 创建工作空间生命周期处理器
+func NewWorkspaceLifecycleHandler(service *application.WorkspaceLifecycleService) *WorkspaceLifecycleHandler {
+	return &WorkspaceLifecycleHandler{service: service}
+}
+
+// ListLifecycles 列出变更过状态的工作空间（可按 state 过滤）
+func (h *WorkspaceLifecycleHandler) ListLifecycles(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context(), c.Query("state"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, list, "获取成功")
+}
+
+// GetLifecycle 获取工作空间的生命周期
+func (h *WorkspaceLifecycleHandler) GetLifecycle(c *gin.Context) {
+	lifecycle, err := h.service.Get(c.Request.Context(), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, lifecycle, "获取成功")
+}
+
+// Suspend 停用工作空间
+func (h *WorkspaceLifecycleHandler) Suspend(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.SuspendWorkspaceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	lifecycle, err := h.service.Suspend(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, lifecycle, "工作空间已停用")
+}
+
+// Resume 恢复已停用的工作空间
+func (h *WorkspaceLifecycleHandler) Resume(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	lifecycle, err := h.service.Resume(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, lifecycle, "工作空间已恢复")
+}
+
+// ScheduleDeletion 计划在宽限期后删除工作空间
+func (h *WorkspaceLifecycleHandler) ScheduleDeletion(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	var req application.ScheduleWorkspaceDeletionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	lifecycle, err := h.service.ScheduleDeletion(c.Request.Context(), userID, c.Param("spaceId"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, lifecycle, "已计划删除")
+}
+
+// CancelDeletion 撤销计划删除
+func (h *WorkspaceLifecycleHandler) CancelDeletion(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	lifecycle, err := h.service.CancelDeletion(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, lifecycle, "已撤销删除计划")
+}
+
+// CreateBundle 生成工作空间的完整导出包
+func (h *WorkspaceLifecycleHandler) CreateBundle(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	bundle, err := h.service.CreateBundle(c.Request.Context(), userID, c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, bundle, "导出已开始")
+}
+
+// ListBundles 列出工作空间的导出包
+func (h *WorkspaceLifecycleHandler) ListBundles(c *gin.Context) {
+	bundles, err := h.service.ListBundles(c.Request.Context(), c.Param("spaceId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, bundles, "获取成功")
+}
+
+// GetBundle 获取导出包（完成后附带清单下载链接）
+func (h *WorkspaceLifecycleHandler) GetBundle(c *gin.Context) {
+	bundle, err := h.service.GetBundle(c.Request.Context(), c.Param("spaceId"), c.Param("bundleId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	response.Success(c, bundle, "获取成功")
+}
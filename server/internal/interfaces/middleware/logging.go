@@ -26,6 +26,8 @@ func DefaultLoggingConfig() LoggingConfig {
 	return LoggingConfig{
 		SkipPaths: []string{
 			"/health",
+			"/healthz",
+			"/readyz",
 			"/ping",
 			"/metrics",
 		},
@@ -49,6 +51,8 @@ func RequestIDMiddleware() gin.HandlerFunc {
 
 		c.Set("request_id", requestID)
 		c.Header("X-Request-ID", requestID)
+		// 写入请求 ctx，供 logger.Ctx / 模块日志器自动附带 request_id
+		c.Request = c.Request.WithContext(logger.ContextWithRequestID(c.Request.Context(), requestID))
 		c.Next()
 	}
 }
@@ -0,0 +1,422 @@
+package jsvm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// 脚本沙箱 ✨
+//
+// 为自动化的"运行脚本"动作执行用户脚本。与钩子、插件使用的运行时池不同，每次执行都创建全新的运行时，
+// 脚本之间不共享任何全局状态；运行时中只有受限的 API：
+//   - input：触发时的输入（只读的普通对象）
+//   - console.log/info/warn/error：输出被捕获为本次执行的日志，不写入服务日志
+//   - output.set(key, value)：设置命名输出，供后续步骤使用
+//   - records.query(tableId, options) / records.update(tableId, recordId, fields)：读写记录（权限由宿主校验）
+//   - fetch(url, options)：同步的 HTTP 请求，返回 {status, ok, headers, body, json()}
+//
+// 没有 require、文件系统、定时器与进程访问。脚本代码作为函数体执行，可以用 return 返回结果
+
+// ScriptLimits 脚本执行限制
+type ScriptLimits struct {
+	Timeout          time.Duration // 最长执行时间（含宿主 API 调用）
+	MaxMemoryBytes   uint64        // 执行期间堆内存增长上限（按进程堆采样，近似值）
+	MaxCallStackSize int           // 最大调用栈深度
+	MaxOutputBytes   int           // 日志与输出的最大字节数，超出部分截断
+	MaxAPICalls      int           // records 与 fetch 的最大调用次数
+}
+
+// ScriptHost 脚本可调用的宿主 API，由应用层实现并负责权限校验
+// 返回值需可序列化为 JSON，传入脚本前会转换为普通对象
+type ScriptHost interface {
+	QueryRecords(ctx context.Context, tableID string, options map[string]interface{}) (interface{}, error)
+	UpdateRecord(ctx context.Context, tableID, recordID string, fields map[string]interface{}) (interface{}, error)
+	Fetch(ctx context.Context, req *ScriptFetchRequest) (*ScriptFetchResponse, error)
+}
+
+// ScriptFetchRequest 脚本发起的 HTTP 请求
+type ScriptFetchRequest struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	Body    string
+}
+
+// ScriptFetchResponse HTTP 响应
+type ScriptFetchResponse struct {
+	Status  int
+	Headers map[string]string
+	Body    string
+}
+
+// ScriptLogEntry 脚本输出的一行日志
+type ScriptLogEntry struct {
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// ScriptResult 脚本执行结果（执行失败时也会返回已捕获的日志与输出）
+type ScriptResult struct {
+	Result    interface{}            `json:"result,omitempty"`
+	Outputs   map[string]interface{} `json:"outputs,omitempty"`
+	Logs      []ScriptLogEntry       `json:"logs"`
+	Truncated bool                   `json:"truncated,omitempty"` // 日志或输出超出上限被截断
+	APICalls  int                    `json:"apiCalls"`
+	Duration  time.Duration          `json:"duration"`
+}
+
+var (
+	// ErrScriptTimeout 脚本执行超时
+	ErrScriptTimeout = errors.New("脚本执行超时")
+	// ErrScriptMemoryLimit 脚本内存超出上限
+	ErrScriptMemoryLimit = errors.New("脚本内存超出上限")
+	// ErrScriptAPILimit 脚本 API 调用次数超出上限
+	ErrScriptAPILimit = errors.New("脚本 API 调用次数超出上限")
+	// ErrScriptCanceled 脚本执行被取消
+	ErrScriptCanceled = errors.New("脚本执行被取消")
+)
+
+// ScriptError 脚本自身抛出的异常或语法错误
+type ScriptError struct {
+	Message string
+}
+
+func (e *ScriptError) Error() string { return "脚本错误: " + e.Message }
+
+// memoryCheckInterval 内存采样间隔
+const memoryCheckInterval = 20 * time.Millisecond
+
+// RunScript 在全新的沙箱运行时中执行脚本
+func RunScript(ctx context.Context, limits ScriptLimits, host ScriptHost, script string, input map[string]interface{}) (*ScriptResult, error) {
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	vm := goja.New()
+	if limits.MaxCallStackSize > 0 {
+		vm.SetMaxCallStackSize(limits.MaxCallStackSize)
+	}
+	s := &scriptSession{ctx: ctx, vm: vm, limits: limits, host: host, result: &ScriptResult{Logs: []ScriptLogEntry{}}}
+	if err := s.install(input); err != nil {
+		return s.result, err
+	}
+
+	// 超时、取消与内存超限时中断运行时；宿主 API 调用中的中断在调用返回后生效
+	done := make(chan struct{})
+	defer close(done)
+	go s.watch(done)
+
+	value, err := vm.RunString("(function () {\n" + script + "\n})()")
+	s.result.Duration = time.Since(start)
+	if err != nil {
+		return s.result, s.classify(err)
+	}
+	if exported := exportJSON(value); exported != nil {
+		s.result.Result = s.limitValue(exported)
+	}
+	return s.result, nil
+}
+
+// scriptSession 一次脚本执行的状态
+type scriptSession struct {
+	ctx    context.Context
+	vm     *goja.Runtime
+	limits ScriptLimits
+	host   ScriptHost
+
+	mu          sync.Mutex
+	result      *ScriptResult
+	outputBytes int
+}
+
+func (s *scriptSession) install(input map[string]interface{}) error {
+	plainInput, err := toPlain(input)
+	if err != nil {
+		return fmt.Errorf("脚本输入无法序列化: %w", err)
+	}
+	if plainInput == nil {
+		plainInput = map[string]interface{}{}
+	}
+
+	console := s.vm.NewObject()
+	for _, level := range []string{"log", "info", "warn", "error"} {
+		level := level
+		_ = console.Set(level, func(call goja.FunctionCall) goja.Value {
+			s.log(level, call.Arguments)
+			return goja.Undefined()
+		})
+	}
+
+	output := s.vm.NewObject()
+	_ = output.Set("set", func(key string, value goja.Value) {
+		exported := exportJSON(value)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.result.Outputs == nil {
+			s.result.Outputs = map[string]interface{}{}
+		}
+		s.result.Outputs[key] = s.limitValueLocked(exported)
+	})
+
+	records := s.vm.NewObject()
+	_ = records.Set("query", func(tableID string, options map[string]interface{}) goja.Value {
+		s.beginCall()
+		result, err := s.host.QueryRecords(s.ctx, tableID, options)
+		return s.hostResult(result, err)
+	})
+	_ = records.Set("update", func(tableID, recordID string, fields map[string]interface{}) goja.Value {
+		s.beginCall()
+		result, err := s.host.UpdateRecord(s.ctx, tableID, recordID, fields)
+		return s.hostResult(result, err)
+	})
+
+	for name, value := range map[string]interface{}{
+		"input":   plainInput,
+		"console": console,
+		"output":  output,
+		"records": records,
+		"fetch":   s.fetch,
+	} {
+		if err := s.vm.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch 同步 HTTP 请求：fetch(url, {method, headers, body})
+func (s *scriptSession) fetch(url string, options map[string]interface{}) goja.Value {
+	s.beginCall()
+	req := &ScriptFetchRequest{URL: url, Method: "GET", Headers: map[string]string{}}
+	if method, ok := options["method"].(string); ok && method != "" {
+		req.Method = strings.ToUpper(method)
+	}
+	if headers, ok := options["headers"].(map[string]interface{}); ok {
+		for key, value := range headers {
+			req.Headers[key] = fmt.Sprint(value)
+		}
+	}
+	switch body := options["body"].(type) {
+	case nil:
+	case string:
+		req.Body = body
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			panic(s.vm.NewTypeError("fetch body 无法序列化: %v", err))
+		}
+		req.Body = string(data)
+	}
+
+	resp, err := s.host.Fetch(s.ctx, req)
+	if err != nil {
+		s.throw(err)
+	}
+	obj := s.vm.NewObject()
+	_ = obj.Set("status", resp.Status)
+	_ = obj.Set("ok", resp.Status >= 200 && resp.Status < 300)
+	_ = obj.Set("headers", resp.Headers)
+	_ = obj.Set("body", resp.Body)
+	_ = obj.Set("json", func() goja.Value {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(resp.Body), &decoded); err != nil {
+			panic(s.vm.NewTypeError("响应不是有效的 JSON: %v", err))
+		}
+		return s.vm.ToValue(decoded)
+	})
+	return obj
+}
+
+// beginCall 统计宿主 API 调用次数，超出上限时中止脚本
+func (s *scriptSession) beginCall() {
+	s.mu.Lock()
+	s.result.APICalls++
+	exceeded := s.limits.MaxAPICalls > 0 && s.result.APICalls > s.limits.MaxAPICalls
+	s.mu.Unlock()
+	if exceeded {
+		s.vm.Interrupt(ErrScriptAPILimit)
+		panic(s.vm.NewGoError(ErrScriptAPILimit))
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.vm.Interrupt(s.contextError())
+		panic(s.vm.NewGoError(s.contextError()))
+	}
+}
+
+func (s *scriptSession) hostResult(result interface{}, err error) goja.Value {
+	if err != nil {
+		s.throw(err)
+	}
+	plain, err := toPlain(result)
+	if err != nil {
+		s.throw(err)
+	}
+	return s.vm.ToValue(plain)
+}
+
+// throw 将宿主错误作为脚本异常抛出，脚本可以 try/catch
+func (s *scriptSession) throw(err error) {
+	if ctxErr := s.ctx.Err(); ctxErr != nil {
+		s.vm.Interrupt(s.contextError())
+	}
+	panic(s.vm.NewGoError(err))
+}
+
+func (s *scriptSession) log(level string, args []goja.Value) {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = formatLogArg(arg)
+	}
+	message := strings.Join(parts, " ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reserve(len(message)) {
+		return
+	}
+	s.result.Logs = append(s.result.Logs, ScriptLogEntry{Level: level, Message: message, Time: time.Now()})
+}
+
+func (s *scriptSession) limitValue(value interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limitValueLocked(value)
+}
+
+// limitValueLocked 按序列化后的大小计入输出上限，超出时丢弃该值
+func (s *scriptSession) limitValueLocked(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil || !s.reserve(len(data)) {
+		return nil
+	}
+	return value
+}
+
+// reserve 占用输出配额（调用方持有锁）
+func (s *scriptSession) reserve(n int) bool {
+	if s.limits.MaxOutputBytes > 0 && s.outputBytes+n > s.limits.MaxOutputBytes {
+		s.result.Truncated = true
+		return false
+	}
+	s.outputBytes += n
+	return true
+}
+
+// watch 监视超时、取消与内存，触发时中断运行时
+func (s *scriptSession) watch(done <-chan struct{}) {
+	var baseline uint64
+	if s.limits.MaxMemoryBytes > 0 {
+		baseline = heapBytes()
+	}
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.ctx.Done():
+			s.vm.Interrupt(s.contextError())
+			return
+		case <-ticker.C:
+			if s.limits.MaxMemoryBytes > 0 {
+				if current := heapBytes(); current > baseline && current-baseline > s.limits.MaxMemoryBytes {
+					s.vm.Interrupt(ErrScriptMemoryLimit)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *scriptSession) contextError() error {
+	if errors.Is(s.ctx.Err(), context.DeadlineExceeded) {
+		return ErrScriptTimeout
+	}
+	return ErrScriptCanceled
+}
+
+// classify 将运行时错误归类为限制错误或脚本错误
+func (s *scriptSession) classify(err error) error {
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		if cause, ok := interrupted.Value().(error); ok {
+			return cause
+		}
+		return ErrScriptCanceled
+	}
+	var stackOverflow *goja.StackOverflowError
+	if errors.As(err, &stackOverflow) {
+		return &ScriptError{Message: "调用栈超出上限"}
+	}
+	var exception *goja.Exception
+	if errors.As(err, &exception) {
+		return &ScriptError{Message: exception.Value().String()}
+	}
+	return &ScriptError{Message: err.Error()}
+}
+
+// heapBytes 当前进程的堆对象字节数
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+// exportJSON 导出脚本值为可序列化的普通值（函数等无法序列化的值返回 nil）
+func exportJSON(value goja.Value) interface{} {
+	if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+		return nil
+	}
+	plain, err := toPlain(value.Export())
+	if err != nil {
+		return nil
+	}
+	return plain
+}
+
+// toPlain 通过 JSON 往返转换为 map/slice/基础类型，避免把 Go 结构体暴露给脚本
+func toPlain(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var plain interface{}
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+func formatLogArg(arg goja.Value) string {
+	if arg == nil || goja.IsUndefined(arg) {
+		return "undefined"
+	}
+	if goja.IsNull(arg) {
+		return "null"
+	}
+	if _, ok := arg.Export().(string); ok {
+		return arg.String()
+	}
+	if data, err := json.Marshal(arg.Export()); err == nil {
+		return string(data)
+	}
+	return arg.String()
+}
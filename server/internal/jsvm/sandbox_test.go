@@ -0,0 +1,110 @@
+package jsvm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeScriptHost struct {
+	updates []map[string]interface{}
+}
+
+func (h *fakeScriptHost) QueryRecords(ctx context.Context, tableID string, options map[string]interface{}) (interface{}, error) {
+	if tableID != "tbl1" {
+		return nil, errors.New("没有权限访问该表格")
+	}
+	return []map[string]interface{}{{"id": "rec1", "fields": map[string]interface{}{"数量": 2}}}, nil
+}
+
+func (h *fakeScriptHost) UpdateRecord(ctx context.Context, tableID, recordID string, fields map[string]interface{}) (interface{}, error) {
+	h.updates = append(h.updates, fields)
+	return map[string]interface{}{"id": recordID, "fields": fields}, nil
+}
+
+func (h *fakeScriptHost) Fetch(ctx context.Context, req *ScriptFetchRequest) (*ScriptFetchResponse, error) {
+	body, _ := json.Marshal(map[string]string{"echo": req.Method + " " + req.Body})
+	return &ScriptFetchResponse{Status: 200, Body: string(body)}, nil
+}
+
+func TestRunScript_APIAndOutputCapture(t *testing.T) {
+	host := &fakeScriptHost{}
+	result, err := RunScript(context.Background(), ScriptLimits{Timeout: time.Second}, host, `
+		const rows = records.query("tbl1", {limit: 1});
+		console.log("rows", rows.length, input.recordId);
+		records.update("tbl1", rows[0].id, {"数量": rows[0].fields["数量"] + 1});
+		try { records.query("tbl2") } catch (e) { console.warn("denied") }
+		const resp = fetch("https://example.com", {method: "post", body: {a: 1}});
+		output.set("echo", resp.json().echo);
+		return {ok: resp.ok};
+	`, map[string]interface{}{"recordId": "rec1"})
+	if err != nil {
+		t.Fatalf("RunScript: %v", err)
+	}
+
+	if len(host.updates) != 1 || host.updates[0]["数量"] != int64(3) {
+		t.Fatalf("unexpected updates: %#v", host.updates)
+	}
+	if len(result.Logs) != 2 || result.Logs[0].Message != "rows 1 rec1" || result.Logs[1].Level != "warn" {
+		t.Fatalf("unexpected logs: %#v", result.Logs)
+	}
+	if result.Outputs["echo"] != `POST {"a":1}` {
+		t.Fatalf("unexpected outputs: %#v", result.Outputs)
+	}
+	if res, _ := result.Result.(map[string]interface{}); res["ok"] != true {
+		t.Fatalf("unexpected result: %#v", result.Result)
+	}
+	if result.APICalls != 4 {
+		t.Fatalf("expected 4 API calls, got %d", result.APICalls)
+	}
+}
+
+func TestRunScript_Limits(t *testing.T) {
+	host := &fakeScriptHost{}
+
+	if _, err := RunScript(context.Background(), ScriptLimits{Timeout: 50 * time.Millisecond}, host, `while (true) {}`, nil); !errors.Is(err, ErrScriptTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if _, err := RunScript(context.Background(), ScriptLimits{Timeout: time.Second, MaxAPICalls: 2}, host,
+		`for (let i = 0; i < 5; i++) { try { records.query("tbl1") } catch (e) {} }`, nil); !errors.Is(err, ErrScriptAPILimit) {
+		t.Fatalf("expected API limit, got %v", err)
+	}
+	if _, err := RunScript(context.Background(), ScriptLimits{Timeout: 5 * time.Second, MaxMemoryBytes: 8 << 20}, host,
+		`const a = []; while (true) { a.push("x".repeat(1024)) }`, nil); !errors.Is(err, ErrScriptMemoryLimit) {
+		t.Fatalf("expected memory limit, got %v", err)
+	}
+
+	result, err := RunScript(context.Background(), ScriptLimits{Timeout: time.Second, MaxOutputBytes: 10}, host,
+		`console.log("12345"); console.log("67890"); console.log("overflow")`, nil)
+	if err != nil || len(result.Logs) != 2 || !result.Truncated {
+		t.Fatalf("expected truncated logs, got %#v (%v)", result, err)
+	}
+}
+
+func TestRunScript_ScriptErrorsAndSandbox(t *testing.T) {
+	host := &fakeScriptHost{}
+
+	result, err := RunScript(context.Background(), ScriptLimits{Timeout: time.Second}, host,
+		`console.log("before"); throw new Error("坏数据")`, nil)
+	var scriptErr *ScriptError
+	if !errors.As(err, &scriptErr) || !strings.Contains(scriptErr.Message, "坏数据") {
+		t.Fatalf("expected script error, got %v", err)
+	}
+	if len(result.Logs) != 1 {
+		t.Fatalf("expected logs captured before the error, got %#v", result.Logs)
+	}
+
+	result, err = RunScript(context.Background(), ScriptLimits{Timeout: time.Second}, host,
+		`return [typeof require, typeof process, typeof setTimeout]`, nil)
+	if err != nil {
+		t.Fatalf("RunScript: %v", err)
+	}
+	for _, kind := range result.Result.([]interface{}) {
+		if kind != "undefined" {
+			t.Fatalf("expected no host globals, got %v", result.Result)
+		}
+	}
+}
@@ -115,6 +115,21 @@ func (m *Manager) GetStats() map[string]interface{} {
 	return stats
 }
 
+// ConnectionCounts 各通道当前连接数（用于指标）
+func (m *Manager) ConnectionCounts() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := map[string]int{}
+	if m.sharedbService != nil {
+		counts["sharedb"] = m.sharedbService.ConnectionCount()
+	}
+	if m.sseManager != nil {
+		counts["sse"] = m.sseManager.ClientCount()
+	}
+	return counts
+}
+
 // HandleShareDBWebSocket 处理 ShareDB WebSocket 连接
 func (m *Manager) HandleShareDBWebSocket(c *gin.Context) {
 	if m.sharedbService != nil {
@@ -48,6 +48,12 @@ type SSEMessage struct {
 	Timestamp int64       `json:"timestamp"`
 }
 
+// SubscriptionGuard 订阅检查：SSE 连接不属于某个空间，更新订阅时按频道检查（由 HTTP 层设置）
+type SubscriptionGuard func(ctx context.Context, channel string) error
+
+// SubscriptionGuardKey gin.Context 中订阅检查的键（值类型为 SubscriptionGuard）
+const SubscriptionGuardKey = "sse_subscription_guard"
+
 // SSEBroker SSE 消息代理
 type SSEBroker struct {
 	clients  map[string]*SSEClient
@@ -98,9 +104,13 @@ func (sm *SSEManager) startBusinessEventSubscription() {
 		events.BusinessEventTypeRecordUpdate,
 		events.BusinessEventTypeRecordDelete,
 		events.BusinessEventTypeCalculationUpdate,
+		events.BusinessEventTypeRecordLinked,
+		events.BusinessEventTypeRecordLock,
 		events.BusinessEventTypeViewCreate,
 		events.BusinessEventTypeViewUpdate,
 		events.BusinessEventTypeViewDelete,
+		events.BusinessEventTypeOperationUpdate,
+		events.BusinessEventTypeMaintenanceUpdate,
 	}
 
 	eventChan, err := sm.businessEvents.Subscribe(sm.ctx, eventTypes)
@@ -142,6 +152,7 @@ func (sm *SSEManager) handleBusinessEvent(event *events.BusinessEvent) {
 	case events.BusinessEventTypeRecordCreate,
 		events.BusinessEventTypeRecordUpdate,
 		events.BusinessEventTypeRecordDelete,
+		events.BusinessEventTypeRecordLock,
 		events.BusinessEventTypeCalculationUpdate:
 		// 记录相关事件：广播到表级别频道
 		if event.TableID != "" {
@@ -169,6 +180,32 @@ func (sm *SSEManager) handleBusinessEvent(event *events.BusinessEvent) {
 		events.BusinessEventTypeTableDelete:
 		// 表相关事件：广播到全局频道
 		sm.broker.BroadcastToChannel("global", sseMessage)
+
+	case events.BusinessEventTypeRecordLinked:
+		// 跨 Base 关联记录变更：广播到关联字段所在表的频道
+		if event.TableID != "" {
+			sm.broker.BroadcastToChannel(fmt.Sprintf("table:%s", event.TableID), sseMessage)
+		}
+
+	case events.BusinessEventTypeOperationUpdate:
+		// 长时操作事件：广播到操作频道和 Base 频道
+		if event.OperationID != "" {
+			sm.broker.BroadcastToChannel(fmt.Sprintf("operation:%s", event.OperationID), sseMessage)
+		}
+		if event.BaseID != "" {
+			sm.broker.BroadcastToChannel(fmt.Sprintf("base:%s", event.BaseID), sseMessage)
+		}
+
+	case events.BusinessEventTypeMaintenanceUpdate:
+		// 维护模式事件：Base 范围广播到 Base 频道，工作空间范围广播到空间频道，实例范围广播到全局频道
+		switch {
+		case event.BaseID != "":
+			sm.broker.BroadcastToChannel(fmt.Sprintf("base:%s", event.BaseID), sseMessage)
+		case event.SpaceID != "":
+			sm.broker.BroadcastToChannel(fmt.Sprintf("space:%s", event.SpaceID), sseMessage)
+		default:
+			sm.broker.BroadcastToChannel("global", sseMessage)
+		}
 	}
 
 	sm.logger.Debug("业务事件已转换为SSE消息",
@@ -250,6 +287,18 @@ func (sm *SSEManager) HandleSubscription(c *gin.Context) {
 		return
 	}
 
+	// 按频道检查订阅（任一频道未通过则整体拒绝）
+	if v, ok := c.Get(SubscriptionGuardKey); ok {
+		if guard, ok := v.(SubscriptionGuard); ok {
+			for _, channel := range req.Subscriptions {
+				if err := guard(c.Request.Context(), channel); err != nil {
+					c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "channel": channel})
+					return
+				}
+			}
+		}
+	}
+
 	// 更新订阅
 	if err := sm.updateSubscriptions(client, req.Subscriptions); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update subscriptions"})
@@ -481,6 +530,13 @@ func (sm *SSEManager) GetStats() map[string]interface{} {
 	}
 }
 
+// ClientCount 当前连接的客户端数
+func (sm *SSEManager) ClientCount() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return len(sm.clients)
+}
+
 // Shutdown 关闭管理器
 func (sm *SSEManager) Shutdown() error {
 	sm.cancel()
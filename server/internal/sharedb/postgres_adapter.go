@@ -35,6 +35,11 @@ func NewPostgresAdapter(db *gorm.DB, logger *zap.Logger, recordRepo repository.R
 	}
 }
 
+// SetSnapshotMasker 设置记录快照的字段脱敏（与 REST 读取接口一致，按连接用户的角色脱敏）
+func (a *PostgresAdapter) SetSnapshotMasker(masker SnapshotMasker) {
+	a.recordAdapter.masker = masker
+}
+
 // Query 查询文档
 func (a *PostgresAdapter) Query(ctx context.Context, collection string, query interface{}, projection map[string]bool) ([]string, error) {
 	collectionInfo := ParseCollection(collection)
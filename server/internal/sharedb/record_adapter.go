@@ -15,8 +15,12 @@ type RecordAdapter struct {
 	db         *gorm.DB
 	logger     *zap.Logger
 	recordRepo repository.RecordRepository
+	masker     SnapshotMasker
 }
 
+// SnapshotMasker 快照字段脱敏：按上下文中的用户返回脱敏后的字段数据
+type SnapshotMasker func(ctx context.Context, tableID string, fields map[string]interface{}) map[string]interface{}
+
 // NewRecordAdapter 创建记录适配器
 func NewRecordAdapter(db *gorm.DB, logger *zap.Logger, recordRepo repository.RecordRepository) *RecordAdapter {
 	return &RecordAdapter{
@@ -53,6 +57,9 @@ func (a *RecordAdapter) GetSnapshot(ctx context.Context, tableID, recordID strin
 	// 客户端期望的数据格式：{ "data": { "fieldId": "value" } }
 	// 这与客户端操作路径 ["data", fieldId] 保持一致
 	recordDataMap := record.Data().ToMap()
+	if a.masker != nil {
+		recordDataMap = a.masker(ctx, tableID, recordDataMap)
+	}
 	snapshotData := map[string]interface{}{
 		"data": recordDataMap, // 直接使用 data 字段，与客户端操作路径一致
 	}
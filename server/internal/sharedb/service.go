@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/monitoring"
 	"github.com/easyspace-ai/luckdb/server/pkg/sharedb"
@@ -257,6 +258,9 @@ func (s *ShareDBService) HandleWebSocket(c *gin.Context) {
 		CreatedAt:     time.Now(), // 记录连接创建时间用于超时检查
 		subCancelFuncs: make(map[string]context.CancelFunc),
 	}
+	if guard, ok := c.Get(ConnectionGuardKey); ok {
+		connection.Guard, _ = guard.(ConnectionGuard)
+	}
 
 	// 注册连接
 	s.connections.Store(connection.ID, connection)
@@ -449,6 +453,13 @@ func (s *ShareDBService) handleMessage(conn *websocket.Conn, connection *Connect
 		}
 	}
 
+	// 连接级访问检查（按集合所属空间执行安全策略等）
+	if connection.Guard != nil && msg.Collection != "" {
+		if err := connection.Guard(s.ctx, msg.Collection); err != nil {
+			return s.sendError(conn, msg, errors.NewShareDBError(errors.ErrTablePermissionDenied, err.Error()))
+		}
+	}
+
 	// 根据消息类型处理
 	switch msg.Action {
 	case "hs": // handshake
@@ -485,7 +496,7 @@ func (s *ShareDBService) handleHandshake(conn *websocket.Conn, connection *Conne
 // handleFetch 处理获取请求
 func (s *ShareDBService) handleFetch(conn *websocket.Conn, connection *Connection, msg *Message) error {
 	// 获取文档快照
-	snapshot, err := s.adapter.GetSnapshot(s.ctx, msg.Collection, msg.DocID, nil)
+	snapshot, err := s.adapter.GetSnapshot(authctx.WithUser(s.ctx, connection.UserID), msg.Collection, msg.DocID, nil)
 	if err != nil {
 		return err
 	}
@@ -612,7 +623,7 @@ func (s *ShareDBService) handleSubscribe(conn *websocket.Conn, connection *Conne
 		zap.String("collection", msg.Collection),
 		zap.String("doc_id", msg.DocID))
 	
-	snapshot, err := s.adapter.GetSnapshot(s.ctx, msg.Collection, msg.DocID, nil)
+	snapshot, err := s.adapter.GetSnapshot(authctx.WithUser(s.ctx, connection.UserID), msg.Collection, msg.DocID, nil)
 	if err != nil {
 		s.logger.Warn("获取文档快照失败，返回空数据（记录可能尚未创建）",
 			zap.Error(err),
@@ -952,6 +963,16 @@ func (s *ShareDBService) sendError(conn *websocket.Conn, msg *Message, err *erro
 	return s.sendMessage(conn, &response)
 }
 
+// ConnectionCount 当前 WebSocket 连接数
+func (s *ShareDBService) ConnectionCount() int {
+	count := 0
+	s.connections.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
+
 // GetStats 获取统计信息
 func (s *ShareDBService) GetStats() map[string]interface{} {
 	stats := map[string]interface{}{
@@ -961,12 +982,7 @@ func (s *ShareDBService) GetStats() map[string]interface{} {
 	}
 
 	// 统计连接数
-	connectionCount := 0
-	s.connections.Range(func(key, value interface{}) bool {
-		connectionCount++
-		return true
-	})
-	stats["connections"] = connectionCount
+	stats["connections"] = s.ConnectionCount()
 
 	// 统计文档数
 	documentCount := 0
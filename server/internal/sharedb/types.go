@@ -109,10 +109,17 @@ type Connection struct {
 	LastSeen      time.Time
 	IsActive      bool
 	CreatedAt     time.Time
+	Guard         ConnectionGuard // 可选：按集合检查访问（由 HTTP 层在建立连接时设置）
 	mu            sync.RWMutex
 	subCancelFuncs map[string]context.CancelFunc // channel -> cancelFunc
 }
 
+// ConnectionGuard 连接级访问检查：连接本身不属于某个空间，每次读取、订阅或提交前按集合检查
+type ConnectionGuard func(ctx context.Context, collection string) error
+
+// ConnectionGuardKey gin.Context 中连接访问检查的键（值类型为 ConnectionGuard）
+const ConnectionGuardKey = "sharedb_connection_guard"
+
 // PresenceData 在线状态数据
 type PresenceData struct {
 	UserID    string                 `json:"userId"`
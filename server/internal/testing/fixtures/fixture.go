@@ -0,0 +1,96 @@
+package fixtures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+// LoadT 供测试使用：加载失败时终止测试，测试结束时自动清理
+func (s *Seeder) LoadT(t testing.TB, userID string, spec *Spec) *Fixture {
+	t.Helper()
+	fixture, err := s.Load(context.Background(), userID, spec)
+	if err != nil {
+		t.Fatalf("加载测试数据失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := fixture.Cleanup(context.Background()); err != nil {
+			t.Errorf("清理测试数据失败: %v", err)
+		}
+	})
+	return fixture
+}
+
+// Fixture 已创建的数据，按名称查询 ID
+type Fixture struct {
+	SpaceID string
+	UserID  string
+
+	services Services
+	bases    map[string]*BaseFixture
+	order    []string
+	cleaned  bool
+}
+
+// BaseFixture 已创建的 Base
+type BaseFixture struct {
+	ID string
+
+	tables  map[string]string
+	fields  map[string]map[string]string
+	records map[string]string
+}
+
+// Base 按名称获取 Base，不存在时返回 nil
+func (f *Fixture) Base(name string) *BaseFixture {
+	return f.bases[name]
+}
+
+// TableID 表名对应的 ID，不存在时返回空字符串
+func (b *BaseFixture) TableID(table string) string {
+	return b.tables[table]
+}
+
+// FieldID 字段名对应的 ID，不存在时返回空字符串
+func (b *BaseFixture) FieldID(table, field string) string {
+	return b.fields[table][field]
+}
+
+// RecordID 记录 key 对应的 ID，不存在时返回空字符串
+func (b *BaseFixture) RecordID(key string) string {
+	return b.records[key]
+}
+
+func (b *BaseFixture) requireField(table, field string) (string, error) {
+	id := b.FieldID(table, field)
+	if id == "" {
+		return "", fmt.Errorf("表 %s 中未找到字段 %s", table, field)
+	}
+	return id, nil
+}
+
+// Cleanup 删除已创建的 Base（连同物理表）与空间，可重复调用
+func (f *Fixture) Cleanup(ctx context.Context) error {
+	if f.cleaned {
+		return nil
+	}
+	f.cleaned = true
+	ctx = authctx.WithUser(ctx, f.UserID)
+
+	var errs []error
+	for i := len(f.order) - 1; i >= 0; i-- {
+		name := f.order[i]
+		if err := f.services.Bases.DeleteBase(ctx, f.bases[name].ID); err != nil {
+			errs = append(errs, fmt.Errorf("删除 Base %s 失败: %w", name, err))
+		}
+	}
+	if f.SpaceID != "" {
+		if err := f.services.Spaces.DeleteSpace(ctx, f.SpaceID); err != nil {
+			errs = append(errs, fmt.Errorf("删除空间失败: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
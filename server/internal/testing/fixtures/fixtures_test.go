@@ -0,0 +1,238 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+)
+
+// fakeServices 记录调用顺序的应用服务替身，ID 由名称推导，便于断言
+type fakeServices struct {
+	calls   []string
+	schemas map[string]*schemaspec.Spec
+	records map[string]map[string]interface{}
+	uploads map[string]string
+	failOn  string
+}
+
+func newFakeServices() *fakeServices {
+	return &fakeServices{
+		schemas: make(map[string]*schemaspec.Spec),
+		records: make(map[string]map[string]interface{}),
+		uploads: make(map[string]string),
+	}
+}
+
+func (f *fakeServices) services() Services {
+	return Services{Spaces: f, Bases: f, Schemas: f, Tables: f, Fields: f, Records: f, Attachments: f}
+}
+
+func (f *fakeServices) record(call string) error {
+	f.calls = append(f.calls, call)
+	if f.failOn != "" && call == f.failOn {
+		return fmt.Errorf("失败: %s", call)
+	}
+	return nil
+}
+
+func (f *fakeServices) CreateSpace(ctx context.Context, req dto.CreateSpaceRequest, userID string) (*dto.SpaceResponse, error) {
+	return &dto.SpaceResponse{ID: "spc_" + req.Name}, f.record("CreateSpace " + req.Name)
+}
+
+func (f *fakeServices) DeleteSpace(ctx context.Context, spaceID string) error {
+	return f.record("DeleteSpace " + spaceID)
+}
+
+func (f *fakeServices) CreateBase(ctx context.Context, req dto.CreateBaseRequest, userID string) (*dto.BaseResponse, error) {
+	return &dto.BaseResponse{ID: "bse_" + req.Name}, f.record("CreateBase " + req.Name + " in " + req.SpaceID)
+}
+
+func (f *fakeServices) DeleteBase(ctx context.Context, baseID string) error {
+	return f.record("DeleteBase " + baseID)
+}
+
+func (f *fakeServices) Apply(ctx context.Context, userID, baseID string, spec *schemaspec.Spec, prune bool) (*schemaspec.Plan, error) {
+	f.schemas[baseID] = spec
+	return &schemaspec.Plan{}, f.record("Apply " + baseID)
+}
+
+func (f *fakeServices) ListTables(ctx context.Context, baseID string) ([]*dto.TableResponse, error) {
+	var tables []*dto.TableResponse
+	for _, table := range f.schemas[baseID].Tables {
+		tables = append(tables, &dto.TableResponse{ID: "tbl_" + table.Name, Name: table.Name, BaseID: baseID})
+	}
+	return tables, nil
+}
+
+func (f *fakeServices) ListFields(ctx context.Context, tableID string) ([]*dto.FieldResponse, error) {
+	name := strings.TrimPrefix(tableID, "tbl_")
+	var fields []*dto.FieldResponse
+	for _, spec := range f.schemas {
+		if table := spec.Table(name); table != nil {
+			for _, field := range table.Fields {
+				fields = append(fields, &dto.FieldResponse{ID: "fld_" + field.Name, TableID: tableID, Name: field.Name, Type: field.Type})
+			}
+		}
+	}
+	return fields, nil
+}
+
+func (f *fakeServices) CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error) {
+	id := fmt.Sprintf("rec_%d", len(f.records)+1)
+	f.records[id] = req.Data
+	return &dto.RecordResponse{ID: id, TableID: req.TableID}, f.record("CreateRecord " + req.TableID)
+}
+
+func (f *fakeServices) UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error) {
+	for key, value := range req.Data {
+		f.records[recordID][key] = value
+	}
+	return &dto.RecordResponse{ID: recordID, TableID: tableID}, f.record("UpdateRecord " + recordID)
+}
+
+func (f *fakeServices) GenerateSignature(ctx context.Context, userID string, req *attachment.SignatureRequest) (*attachment.SignatureResponse, error) {
+	return &attachment.SignatureResponse{Token: "tok_" + req.RecordID}, f.record("GenerateSignature " + req.RecordID)
+}
+
+func (f *fakeServices) UploadFile(ctx context.Context, token string, reader io.Reader, filename string, size int64) error {
+	content, _ := io.ReadAll(reader)
+	f.uploads[filename] = string(content)
+	return f.record("UploadFile " + filename)
+}
+
+func (f *fakeServices) NotifyUpload(ctx context.Context, token, filename string) (*attachment.NotifyResponse, error) {
+	item := &attachment.AttachmentItem{ID: "act_" + filename, Name: filename}
+	return &attachment.NotifyResponse{Attachment: item, Success: true}, f.record("NotifyUpload " + filename)
+}
+
+func loadDemoSpec(t *testing.T) *Spec {
+	t.Helper()
+	data, err := os.ReadFile("testdata/demo.yaml")
+	if err != nil {
+		t.Fatalf("读取示例失败: %v", err)
+	}
+	spec, err := Parse(data)
+	if err != nil {
+		t.Fatalf("解析示例失败: %v", err)
+	}
+	return spec
+}
+
+func TestSeederLoadsInDeterministicOrder(t *testing.T) {
+	services := newFakeServices()
+	fixture, err := NewSeeder(services.services()).Load(context.Background(), "usr_1", loadDemoSpec(t))
+	if err != nil {
+		t.Fatalf("加载失败: %v", err)
+	}
+
+	want := []string{
+		"CreateSpace 演示空间",
+		"CreateBase 销售 in spc_演示空间",
+		"Apply bse_销售",
+		"CreateRecord tbl_订单",
+		"CreateRecord tbl_客户",
+		"CreateRecord tbl_客户",
+		"GenerateSignature rec_1",
+		"UploadFile contract.txt",
+		"NotifyUpload contract.txt",
+		"UpdateRecord rec_1",
+	}
+	if !reflect.DeepEqual(services.calls, want) {
+		t.Fatalf("调用顺序不符:\n得到 %v\n期望 %v", services.calls, want)
+	}
+
+	base := fixture.Base("销售")
+	if base == nil || base.ID != "bse_销售" || base.TableID("订单") != "tbl_订单" || base.FieldID("订单", "金额") != "fld_金额" {
+		t.Fatalf("名称映射不正确: %+v", base)
+	}
+	if base.RecordID("acme") != "rec_2" || base.RecordID("globex") != "rec_3" {
+		t.Fatalf("记录 key 映射不正确: %v", base.records)
+	}
+
+	order := services.records["rec_1"]
+	if order["fld_编号"] != "SO-001" {
+		t.Errorf("普通字段应在创建时写入，得到 %v", order)
+	}
+	links, _ := order["fld_客户"].([]interface{})
+	if len(links) != 1 || links[0].(map[string]interface{})["id"] != "rec_2" {
+		t.Errorf("关联字段应解析为记录 ID，得到 %v", order["fld_客户"])
+	}
+	files, _ := order["fld_合同"].([]interface{})
+	if len(files) != 1 || files[0].(map[string]interface{})["id"] != "act_contract.txt" || services.uploads["contract.txt"] != "合同正文" {
+		t.Errorf("附件应上传后写入，得到 %v", order["fld_合同"])
+	}
+}
+
+func TestFixtureCleanupDeletesInReverseOrder(t *testing.T) {
+	services := newFakeServices()
+	spec := loadDemoSpec(t)
+	other := *spec.Bases[0]
+	other.Name = "库存"
+	other.Records = nil
+	spec.Bases = append(spec.Bases, &other)
+
+	fixture, err := NewSeeder(services.services()).Load(context.Background(), "usr_1", spec)
+	if err != nil {
+		t.Fatalf("加载失败: %v", err)
+	}
+	services.calls = nil
+	if err := fixture.Cleanup(context.Background()); err != nil {
+		t.Fatalf("清理失败: %v", err)
+	}
+	if err := fixture.Cleanup(context.Background()); err != nil {
+		t.Fatalf("重复清理应无操作: %v", err)
+	}
+	want := []string{"DeleteBase bse_库存", "DeleteBase bse_销售", "DeleteSpace spc_演示空间"}
+	if !reflect.DeepEqual(services.calls, want) {
+		t.Fatalf("清理顺序不符: 得到 %v，期望 %v", services.calls, want)
+	}
+}
+
+func TestSeederCleansUpOnFailure(t *testing.T) {
+	services := newFakeServices()
+	services.failOn = "UploadFile contract.txt"
+	if _, err := NewSeeder(services.services()).Load(context.Background(), "usr_1", loadDemoSpec(t)); err == nil {
+		t.Fatal("上传失败时应返回错误")
+	}
+	tail := services.calls[len(services.calls)-2:]
+	if !reflect.DeepEqual(tail, []string{"DeleteBase bse_销售", "DeleteSpace spc_演示空间"}) {
+		t.Errorf("失败后应清理已创建的数据，得到 %v", services.calls)
+	}
+}
+
+func TestParseRejectsUnknownReferences(t *testing.T) {
+	cases := map[string]string{
+		"未知的表":    "records:\n      - table: 供应商\n        values: {}",
+		"未知的字段":   "records:\n      - table: 客户\n        values: {电话: 1}",
+		"未知的关联":   "records:\n      - table: 订单\n        values: {客户: [nobody]}",
+		"重复的 key": "records:\n      - {table: 客户, key: a, values: {}}\n      - {table: 客户, key: a, values: {}}",
+	}
+	const base = `
+space: s
+bases:
+  - name: b
+    schema:
+      version: 1
+      tables:
+        - name: 客户
+          fields: [{name: 名称, type: singleLineText}]
+        - name: 订单
+          fields: [{name: 编号, type: singleLineText}, {name: 客户, type: link, options: {foreignTableId: 客户}}]
+    `
+	if _, err := Parse([]byte(base + "records: []\n")); err != nil {
+		t.Fatalf("基础描述应解析成功: %v", err)
+	}
+	for name, records := range cases {
+		if _, err := Parse([]byte(base + records + "\n")); err == nil {
+			t.Errorf("%s: 应校验失败", name)
+		}
+	}
+}
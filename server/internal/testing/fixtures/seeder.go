@@ -0,0 +1,298 @@
+package fixtures
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/container"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+// SpaceService 空间的创建与删除
+type SpaceService interface {
+	CreateSpace(ctx context.Context, req dto.CreateSpaceRequest, userID string) (*dto.SpaceResponse, error)
+	DeleteSpace(ctx context.Context, spaceID string) error
+}
+
+// BaseService Base 的创建与删除
+type BaseService interface {
+	CreateBase(ctx context.Context, req dto.CreateBaseRequest, userID string) (*dto.BaseResponse, error)
+	DeleteBase(ctx context.Context, baseID string) error
+}
+
+// SchemaApplier 按结构描述建表建字段
+type SchemaApplier interface {
+	Apply(ctx context.Context, userID, baseID string, spec *schemaspec.Spec, prune bool) (*schemaspec.Plan, error)
+}
+
+// TableLister 列出 Base 的表
+type TableLister interface {
+	ListTables(ctx context.Context, baseID string) ([]*dto.TableResponse, error)
+}
+
+// FieldLister 列出表的字段
+type FieldLister interface {
+	ListFields(ctx context.Context, tableID string) ([]*dto.FieldResponse, error)
+}
+
+// RecordWriter 记录的创建与更新
+type RecordWriter interface {
+	CreateRecord(ctx context.Context, req dto.CreateRecordRequest, userID string) (*dto.RecordResponse, error)
+	UpdateRecord(ctx context.Context, tableID, recordID string, req dto.UpdateRecordRequest, userID string) (*dto.RecordResponse, error)
+}
+
+// AttachmentUploader 附件上传（签名、上传、确认）
+type AttachmentUploader interface {
+	GenerateSignature(ctx context.Context, userID string, req *attachment.SignatureRequest) (*attachment.SignatureResponse, error)
+	UploadFile(ctx context.Context, token string, reader io.Reader, filename string, size int64) error
+	NotifyUpload(ctx context.Context, token, filename string) (*attachment.NotifyResponse, error)
+}
+
+// Services Seeder 依赖的应用服务
+// Attachments 可以为空，此时数据描述中不能包含附件值
+type Services struct {
+	Spaces      SpaceService
+	Bases       BaseService
+	Schemas     SchemaApplier
+	Tables      TableLister
+	Fields      FieldLister
+	Records     RecordWriter
+	Attachments AttachmentUploader
+}
+
+// Seeder 按数据描述创建空间、Base、表结构与记录
+type Seeder struct {
+	services Services
+}
+
+// NewSeeder 创建 Seeder
+func NewSeeder(services Services) *Seeder {
+	return &Seeder{services: services}
+}
+
+// NewSeederFromContainer 使用容器中的应用服务创建 Seeder（容器需已初始化）
+func NewSeederFromContainer(c *container.Container) *Seeder {
+	services := Services{
+		Spaces:  c.SpaceService(),
+		Bases:   c.BaseService(),
+		Schemas: c.SchemaSpecService(),
+		Tables:  c.TableService(),
+		Fields:  c.FieldService(),
+		Records: c.RecordService(),
+	}
+	if uploader := c.AttachmentService(); uploader != nil {
+		services.Attachments = uploader
+	}
+	return NewSeeder(services)
+}
+
+// Load 以 userID 的身份创建数据描述中的全部数据
+// 顺序固定：空间 → Base（按声明顺序）→ 表结构 → 记录普通字段值 → 关联与附件；
+// 任一步失败时清理已创建的数据并返回错误
+func (s *Seeder) Load(ctx context.Context, userID string, spec *Spec) (*Fixture, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	ctx = authctx.WithUser(ctx, userID)
+	fixture := &Fixture{UserID: userID, services: s.services, bases: make(map[string]*BaseFixture)}
+
+	space, err := s.services.Spaces.CreateSpace(ctx, dto.CreateSpaceRequest{Name: spec.Space}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("创建空间 %s 失败: %w", spec.Space, err)
+	}
+	fixture.SpaceID = space.ID
+
+	for _, baseSpec := range spec.Bases {
+		if err := s.loadBase(ctx, fixture, baseSpec); err != nil {
+			return nil, errors.Join(fmt.Errorf("Base %s: %w", baseSpec.Name, err), fixture.Cleanup(ctx))
+		}
+	}
+	return fixture, nil
+}
+
+func (s *Seeder) loadBase(ctx context.Context, fixture *Fixture, spec *BaseSpec) error {
+	base, err := s.services.Bases.CreateBase(ctx, dto.CreateBaseRequest{Name: spec.Name, SpaceID: fixture.SpaceID}, fixture.UserID)
+	if err != nil {
+		return fmt.Errorf("创建 Base 失败: %w", err)
+	}
+	bf := &BaseFixture{
+		ID:      base.ID,
+		tables:  make(map[string]string),
+		fields:  make(map[string]map[string]string),
+		records: make(map[string]string),
+	}
+	fixture.bases[spec.Name] = bf
+	fixture.order = append(fixture.order, spec.Name)
+
+	if _, err := s.services.Schemas.Apply(ctx, fixture.UserID, base.ID, spec.Schema, false); err != nil {
+		return fmt.Errorf("应用表结构失败: %w", err)
+	}
+	if err := s.resolveSchema(ctx, bf); err != nil {
+		return err
+	}
+
+	// 第一轮只写普通字段，保证关联目标无论声明先后都已存在
+	recordIDs := make([]string, len(spec.Records))
+	for i, record := range spec.Records {
+		data, err := s.plainValues(bf, spec, record)
+		if err != nil {
+			return err
+		}
+		created, err := s.services.Records.CreateRecord(ctx, dto.CreateRecordRequest{TableID: bf.tables[record.Table], Data: data}, fixture.UserID)
+		if err != nil {
+			return fmt.Errorf("创建第 %d 条记录失败: %w", i+1, err)
+		}
+		recordIDs[i] = created.ID
+		if record.Key != "" {
+			bf.records[record.Key] = created.ID
+		}
+	}
+
+	for i, record := range spec.Records {
+		data, err := s.deferredValues(ctx, fixture.UserID, bf, spec, record, recordIDs[i])
+		if err != nil {
+			return fmt.Errorf("第 %d 条记录: %w", i+1, err)
+		}
+		if len(data) == 0 {
+			continue
+		}
+		if _, err := s.services.Records.UpdateRecord(ctx, bf.tables[record.Table], recordIDs[i], dto.UpdateRecordRequest{Data: data}, fixture.UserID); err != nil {
+			return fmt.Errorf("更新第 %d 条记录的关联与附件失败: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// resolveSchema 记录表名、字段名到 ID 的映射
+func (s *Seeder) resolveSchema(ctx context.Context, bf *BaseFixture) error {
+	tables, err := s.services.Tables.ListTables(ctx, bf.ID)
+	if err != nil {
+		return fmt.Errorf("列出表失败: %w", err)
+	}
+	for _, table := range tables {
+		bf.tables[table.Name] = table.ID
+		fields, err := s.services.Fields.ListFields(ctx, table.ID)
+		if err != nil {
+			return fmt.Errorf("列出表 %s 的字段失败: %w", table.Name, err)
+		}
+		bf.fields[table.Name] = make(map[string]string, len(fields))
+		for _, field := range fields {
+			bf.fields[table.Name][field.Name] = field.ID
+		}
+	}
+	return nil
+}
+
+// plainValues 除关联与附件外的字段值，键转换为字段 ID
+func (s *Seeder) plainValues(bf *BaseFixture, spec *BaseSpec, record *RecordSpec) (map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(record.Values))
+	for _, name := range sortedNames(record.Values) {
+		value := record.Values[name]
+		if isDeferred(spec, record.Table, name) {
+			continue
+		}
+		fieldID, err := bf.requireField(record.Table, name)
+		if err != nil {
+			return nil, err
+		}
+		data[fieldID] = value
+	}
+	return data, nil
+}
+
+// deferredValues 关联与附件字段值：关联 key 解析为记录 ID，附件先上传再写入
+func (s *Seeder) deferredValues(ctx context.Context, userID string, bf *BaseFixture, spec *BaseSpec, record *RecordSpec, recordID string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	for _, name := range sortedNames(record.Values) {
+		value := record.Values[name]
+		if !isDeferred(spec, record.Table, name) {
+			continue
+		}
+		fieldID, err := bf.requireField(record.Table, name)
+		if err != nil {
+			return nil, err
+		}
+		switch spec.Schema.Table(record.Table).Field(name).Type {
+		case valueobject.TypeLink:
+			keys, _ := linkKeys(value)
+			links := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				links = append(links, map[string]interface{}{"id": bf.records[key]})
+			}
+			data[fieldID] = links
+		case valueobject.TypeAttachment:
+			specs, _ := attachmentSpecs(value)
+			items, err := s.upload(ctx, userID, bf.tables[record.Table], fieldID, recordID, specs)
+			if err != nil {
+				return nil, fmt.Errorf("上传字段 %s 的附件失败: %w", name, err)
+			}
+			data[fieldID] = items
+		}
+	}
+	return data, nil
+}
+
+// upload 上传附件，返回可写入单元格的附件列表
+func (s *Seeder) upload(ctx context.Context, userID, tableID, fieldID, recordID string, specs []AttachmentSpec) ([]interface{}, error) {
+	if len(specs) == 0 {
+		return []interface{}{}, nil
+	}
+	if s.services.Attachments == nil {
+		return nil, fmt.Errorf("未配置附件服务")
+	}
+	items := make([]interface{}, 0, len(specs))
+	for _, spec := range specs {
+		signature, err := s.services.Attachments.GenerateSignature(ctx, userID, &attachment.SignatureRequest{
+			TableID: tableID, FieldID: fieldID, RecordID: recordID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.services.Attachments.UploadFile(ctx, signature.Token, strings.NewReader(spec.Content), spec.Name, int64(len(spec.Content))); err != nil {
+			return nil, err
+		}
+		notified, err := s.services.Attachments.NotifyUpload(ctx, signature.Token, spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		if notified == nil || notified.Attachment == nil {
+			return nil, fmt.Errorf("附件 %s 上传后未返回附件信息", spec.Name)
+		}
+		// 转为通用结构，与接口写入的单元格值一致
+		raw, err := json.Marshal(notified.Attachment)
+		if err != nil {
+			return nil, err
+		}
+		var item map[string]interface{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// isDeferred 关联与附件字段在第二轮写入
+func isDeferred(spec *BaseSpec, tableName, fieldName string) bool {
+	field := spec.Schema.Table(tableName).Field(fieldName)
+	return field != nil && (field.Type == valueobject.TypeLink || field.Type == valueobject.TypeAttachment)
+}
+
+// sortedNames 按字段名排序，保证上传与写入顺序稳定
+func sortedNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,180 @@
+// Package fixtures 声明式的测试与演示数据 ✨
+// 用一份 YAML 描述空间、Base、表结构（schemaspec）和记录（含关联与附件），
+// 由 Seeder 通过应用服务按固定顺序创建，并提供按名称查询 ID 与统一清理
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/schemaspec"
+)
+
+// Spec 数据描述
+type Spec struct {
+	Space string      `yaml:"space"`
+	Bases []*BaseSpec `yaml:"bases"`
+}
+
+// BaseSpec Base 描述
+// 记录按声明顺序创建；关联字段的值填写同一 Base 中记录的 key
+type BaseSpec struct {
+	Name    string           `yaml:"name"`
+	Schema  *schemaspec.Spec `yaml:"schema"`
+	Records []*RecordSpec    `yaml:"records,omitempty"`
+}
+
+// RecordSpec 记录描述
+// Values 以字段名为键；关联字段填写记录 key 列表，附件字段填写 AttachmentSpec 列表
+type RecordSpec struct {
+	Table  string                 `yaml:"table"`
+	Key    string                 `yaml:"key,omitempty"`
+	Values map[string]interface{} `yaml:"values"`
+}
+
+// AttachmentSpec 附件描述（内容为文本）
+type AttachmentSpec struct {
+	Name    string `yaml:"name"`
+	Content string `yaml:"content"`
+}
+
+// Parse 解析 YAML 数据描述并校验（不允许未知字段）
+func Parse(data []byte) (*Spec, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var spec Spec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("解析数据描述失败: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate 校验数据描述：名称唯一、记录引用的表与字段存在、关联的记录 key 已声明
+func (s *Spec) Validate() error {
+	if strings.TrimSpace(s.Space) == "" {
+		return fmt.Errorf("空间名称不能为空")
+	}
+	bases := make(map[string]bool, len(s.Bases))
+	for _, base := range s.Bases {
+		if base == nil || strings.TrimSpace(base.Name) == "" {
+			return fmt.Errorf("Base 名称不能为空")
+		}
+		if bases[base.Name] {
+			return fmt.Errorf("Base %s 重复声明", base.Name)
+		}
+		bases[base.Name] = true
+		if err := base.validate(); err != nil {
+			return fmt.Errorf("Base %s: %w", base.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b *BaseSpec) validate() error {
+	if b.Schema == nil {
+		return fmt.Errorf("缺少表结构")
+	}
+	if err := b.Schema.Validate(); err != nil {
+		return err
+	}
+
+	keys := make(map[string]bool)
+	for i, record := range b.Records {
+		if record == nil {
+			return fmt.Errorf("第 %d 条记录为空", i+1)
+		}
+		if b.Schema.Table(record.Table) == nil {
+			return fmt.Errorf("第 %d 条记录引用了未声明的表 %s", i+1, record.Table)
+		}
+		if record.Key == "" {
+			continue
+		}
+		if _, ok := keys[record.Key]; ok {
+			return fmt.Errorf("记录 key %s 重复声明", record.Key)
+		}
+		keys[record.Key] = true
+	}
+
+	for i, record := range b.Records {
+		table := b.Schema.Table(record.Table)
+		for name, value := range record.Values {
+			field := table.Field(name)
+			if field == nil {
+				return fmt.Errorf("第 %d 条记录引用了表 %s 中未声明的字段 %s", i+1, record.Table, name)
+			}
+			switch field.Type {
+			case valueobject.TypeLink:
+				refs, err := linkKeys(value)
+				if err != nil {
+					return fmt.Errorf("第 %d 条记录的字段 %s: %w", i+1, name, err)
+				}
+				for _, ref := range refs {
+					if _, ok := keys[ref]; !ok {
+						return fmt.Errorf("第 %d 条记录的字段 %s 关联了未声明的记录 %s", i+1, name, ref)
+					}
+				}
+			case valueobject.TypeAttachment:
+				if _, err := attachmentSpecs(value); err != nil {
+					return fmt.Errorf("第 %d 条记录的字段 %s: %w", i+1, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// linkKeys 解析关联字段的值：单个 key 或 key 列表
+func linkKeys(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		keys := make([]string, 0, len(v))
+		for _, item := range v {
+			key, ok := item.(string)
+			if !ok || key == "" {
+				return nil, fmt.Errorf("关联值应为记录 key 列表")
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+	case []string:
+		return v, nil
+	}
+	return nil, fmt.Errorf("关联值应为记录 key 列表")
+}
+
+// attachmentSpecs 解析附件字段的值：{name, content} 列表
+func attachmentSpecs(value interface{}) ([]AttachmentSpec, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []AttachmentSpec:
+		return v, nil
+	case []interface{}:
+		specs := make([]AttachmentSpec, 0, len(v))
+		for _, item := range v {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("附件值应为 {name, content} 列表")
+			}
+			name, _ := m["name"].(string)
+			content, _ := m["content"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("附件名称不能为空")
+			}
+			specs = append(specs, AttachmentSpec{Name: name, Content: content})
+		}
+		return specs, nil
+	}
+	return nil, fmt.Errorf("附件值应为 {name, content} 列表")
+}
@@ -4,6 +4,7 @@ package integration
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/container"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/internal/testing/fixtures"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
 
@@ -242,6 +244,16 @@ func (s *IntegrationTestSuite) CreateTestTable(baseID, name string) string {
 	return table.ID
 }
 
+// LoadFixture 按数据描述文件创建空间、Base、表与记录，测试结束时自动清理 ✨
+func (s *IntegrationTestSuite) LoadFixture(userID, path string) *fixtures.Fixture {
+	data, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	spec, err := fixtures.Parse(data)
+	s.Require().NoError(err)
+
+	return fixtures.NewSeederFromContainer(s.container).LoadT(s.T(), userID, spec)
+}
+
 // AssertEventPublished 断言事件已发布
 func (s *IntegrationTestSuite) AssertEventPublished(eventType string) {
 	// 实现事件断言逻辑
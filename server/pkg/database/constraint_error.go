@@ -92,7 +92,7 @@ func handlePostgresError(pgErr *pgconn.PgError, tableID string, fieldRepo fieldR
 		return handleCheckViolation(pgErr)
 
 	case PgDeadlock:
-		return errors.ErrDatabaseOperation.WithDetails(map[string]interface{}{
+		return errors.ErrTransactionConflict.WithCause(pgErr).WithDetails(map[string]interface{}{
 			"type":    "deadlock",
 			"message": "检测到死锁，请重试",
 			"detail":  pgErr.Detail,
@@ -104,7 +104,7 @@ func handlePostgresError(pgErr *pgconn.PgError, tableID string, fieldRepo fieldR
 			logger.String("code", pgErr.Code),
 			logger.String("message", pgErr.Message),
 			logger.String("detail", pgErr.Detail))
-		return errors.ErrDatabaseOperation.WithDetails(pgErr.Message)
+		return errors.Database(pgErr, "")
 	}
 }
 
@@ -152,7 +152,7 @@ func handleSQLiteError(sqliteErr sqlite3.Error, tableID string, fieldRepo fieldR
 		})
 
 	case SqliteBusy:
-		return errors.ErrDatabaseOperation.WithDetails(map[string]interface{}{
+		return errors.ErrTransactionConflict.WithCause(sqliteErr).WithDetails(map[string]interface{}{
 			"type":    "locked",
 			"message": "数据库被锁定，请稍后重试",
 		})
@@ -33,6 +33,9 @@ type TxContext struct {
 	Events    []interface{} // 事务内收集的事件
 	Callbacks []func()      // 事务提交后回调
 	mu        sync.Mutex
+
+	// joined 加入本事务的其他数据库连接（如其他区域集群）上的事务
+	joined map[*gorm.DB]*gorm.DB
 }
 
 // AddEvent 添加事务事件
@@ -69,6 +72,40 @@ func (tc *TxContext) ExecuteCallbacks() {
 	}
 }
 
+// commitJoined 提交加入的事务（在主事务提交前调用）
+func (tc *TxContext) commitJoined() error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	for db, tx := range tc.joined {
+		if err := tx.Commit().Error; err != nil {
+			delete(tc.joined, db)
+			tc.rollbackJoinedLocked()
+			return fmt.Errorf("提交加入的事务失败: %w", err)
+		}
+		delete(tc.joined, db)
+	}
+	return nil
+}
+
+// rollbackJoined 回滚加入的事务
+func (tc *TxContext) rollbackJoined() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.rollbackJoinedLocked()
+}
+
+func (tc *TxContext) rollbackJoinedLocked() {
+	for db, tx := range tc.joined {
+		if err := tx.Rollback().Error; err != nil {
+			logger.Warn("回滚加入的事务失败",
+				logger.String("tx_id", tc.ID),
+				logger.ErrorField(err))
+		}
+		delete(tc.joined, db)
+	}
+}
+
 // WithTx 使用事务客户端
 // 如果上下文中已有事务，则复用；否则返回原始 db
 func WithTx(ctx context.Context, db *gorm.DB) *gorm.DB {
@@ -78,6 +115,32 @@ func WithTx(ctx context.Context, db *gorm.DB) *gorm.DB {
 	return db
 }
 
+// JoinTx 让另一个数据库连接（如数据驻留的区域集群）加入上下文中的事务
+// 首次调用时在该连接上开启事务，之后复用；主事务提交前先提交加入的事务，主事务失败时一并回滚。
+// 两个连接之间不是两阶段提交：加入的事务提交后主事务提交失败时无法撤销（仅发生在提交阶段的故障）。
+// 不在事务中时返回原始 db
+func JoinTx(ctx context.Context, db *gorm.DB) (*gorm.DB, error) {
+	txCtx := GetTxContext(ctx)
+	if txCtx == nil || txCtx.Tx == nil {
+		return db, nil
+	}
+
+	txCtx.mu.Lock()
+	defer txCtx.mu.Unlock()
+	if tx, ok := txCtx.joined[db]; ok {
+		return tx, nil
+	}
+	tx := db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, fmt.Errorf("加入事务失败: %w", tx.Error)
+	}
+	if txCtx.joined == nil {
+		txCtx.joined = make(map[*gorm.DB]*gorm.DB)
+	}
+	txCtx.joined[db] = tx
+	return tx, nil
+}
+
 // GetTxContext 从上下文中获取事务上下文
 func GetTxContext(ctx context.Context) *TxContext {
 	if ctx == nil {
@@ -179,10 +242,12 @@ func Transaction(ctx context.Context, db *gorm.DB, opts *TransactionOptions, fn
 
 			// 执行业务逻辑
 			if err := fn(txCtxWithTx); err != nil {
+				txContext.rollbackJoined()
 				return err
 			}
 
-			return nil
+			// 加入的事务先于主事务提交
+			return txContext.commitJoined()
 		})
 
 		if err == nil {
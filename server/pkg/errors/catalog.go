@@ -0,0 +1,181 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Category 错误类别
+// 类别决定 gRPC 状态码与默认的可重试性，客户端与自动化重试逻辑据此做出反应
+type Category string
+
+const (
+	CategoryInvalidArgument  Category = "invalid_argument"
+	CategoryUnauthenticated  Category = "unauthenticated"
+	CategoryPermissionDenied Category = "permission_denied"
+	CategoryNotFound         Category = "not_found"
+	CategoryConflict         Category = "conflict"
+	CategoryAborted          Category = "aborted" // 并发冲突（事务序列化失败、死锁），可重试
+	CategoryRateLimited      Category = "rate_limited"
+	CategoryCanceled         Category = "canceled"
+	CategoryTimeout          Category = "timeout"
+	CategoryUnavailable      Category = "unavailable" // 依赖暂不可用，可重试
+	CategoryNotImplemented   Category = "not_implemented"
+	CategoryInternal         Category = "internal"
+)
+
+// Retryable 该类别的错误默认是否可重试
+func (c Category) Retryable() bool {
+	switch c {
+	case CategoryAborted, CategoryRateLimited, CategoryTimeout, CategoryUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// GRPCCode 与 google.golang.org/grpc/codes 取值一致的状态码（避免引入 gRPC 依赖）
+type GRPCCode uint32
+
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+// GRPCCode 类别对应的 gRPC 状态码
+func (c Category) GRPCCode() GRPCCode {
+	switch c {
+	case CategoryInvalidArgument:
+		return GRPCInvalidArgument
+	case CategoryUnauthenticated:
+		return GRPCUnauthenticated
+	case CategoryPermissionDenied:
+		return GRPCPermissionDenied
+	case CategoryNotFound:
+		return GRPCNotFound
+	case CategoryConflict:
+		return GRPCAlreadyExists
+	case CategoryAborted:
+		return GRPCAborted
+	case CategoryRateLimited:
+		return GRPCResourceExhausted
+	case CategoryCanceled:
+		return GRPCCanceled
+	case CategoryTimeout:
+		return GRPCDeadlineExceeded
+	case CategoryUnavailable:
+		return GRPCUnavailable
+	case CategoryNotImplemented:
+		return GRPCUnimplemented
+	case CategoryInternal:
+		return GRPCInternal
+	default:
+		return GRPCUnknown
+	}
+}
+
+// StatusClientClosedRequest 客户端取消请求（nginx 约定的 499）
+const StatusClientClosedRequest = 499
+
+// categoryForStatus 根据 HTTP 状态码推断错误类别
+func categoryForStatus(httpStatus int) Category {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusRequestEntityTooLarge:
+		return CategoryInvalidArgument
+	case http.StatusUnauthorized:
+		return CategoryUnauthenticated
+	case http.StatusForbidden:
+		return CategoryPermissionDenied
+	case http.StatusNotFound:
+		return CategoryNotFound
+	case http.StatusConflict:
+		return CategoryConflict
+	case http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case StatusClientClosedRequest:
+		return CategoryCanceled
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return CategoryTimeout
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return CategoryUnavailable
+	case http.StatusNotImplemented:
+		return CategoryNotImplemented
+	default:
+		if httpStatus >= 400 && httpStatus < 500 {
+			return CategoryInvalidArgument
+		}
+		return CategoryInternal
+	}
+}
+
+// CatalogEntry 错误目录条目（对外公开的错误码说明）
+type CatalogEntry struct {
+	Code        string   `json:"code"`
+	NumericCode int      `json:"numeric_code"`
+	Category    Category `json:"category"`
+	HTTPStatus  int      `json:"http_status"`
+	GRPCCode    GRPCCode `json:"grpc_code"`
+	Retryable   bool     `json:"retryable"`
+	Message     string   `json:"message"` // 可直接展示给用户的消息
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]CatalogEntry{}
+)
+
+// register 将预定义错误登记到错误目录（同一错误码以首次登记为准）
+func register(e *AppError) *AppError {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if _, exists := catalog[e.Code]; !exists {
+		catalog[e.Code] = CatalogEntry{
+			Code:        e.Code,
+			NumericCode: NumericCodeFromString(e.Code, e.HTTPStatus),
+			Category:    e.Category,
+			HTTPStatus:  e.HTTPStatus,
+			GRPCCode:    e.Category.GRPCCode(),
+			Retryable:   e.Retryable,
+			Message:     e.Message,
+		}
+	}
+	return e
+}
+
+// Lookup 查询错误码的目录条目
+func Lookup(code string) (CatalogEntry, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	entry, ok := catalog[code]
+	return entry, ok
+}
+
+// Catalog 返回完整错误目录（按错误码排序）
+func Catalog() []CatalogEntry {
+	catalogMu.RLock()
+	entries := make([]CatalogEntry, 0, len(catalog))
+	for _, entry := range catalog {
+		entries = append(entries, entry)
+	}
+	catalogMu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
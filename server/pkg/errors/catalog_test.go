@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestCatalogEntries(t *testing.T) {
+	entry, ok := Lookup("TABLE_NOT_FOUND")
+	if !ok {
+		t.Fatal("TABLE_NOT_FOUND should be registered")
+	}
+	if entry.HTTPStatus != 404 || entry.GRPCCode != GRPCNotFound || entry.Retryable || entry.NumericCode != CodeTableNotFound {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	conn, _ := Lookup("DATABASE_CONNECTION_ERROR")
+	if !conn.Retryable || conn.GRPCCode != GRPCUnavailable || conn.HTTPStatus != 500 {
+		t.Fatalf("database connection error should be retryable/unavailable: %+v", conn)
+	}
+
+	if len(Catalog()) < 50 {
+		t.Fatalf("catalog too small: %d", len(Catalog()))
+	}
+}
+
+func TestWithDetailsDoesNotMutateShared(t *testing.T) {
+	err := ErrNotFound.WithDetails("x").WithMessage("custom")
+	if ErrNotFound.Details != nil || ErrNotFound.Message != "资源不存在" {
+		t.Fatal("predefined error was mutated")
+	}
+	if !Is(fmt.Errorf("wrap: %w", err), ErrNotFound) {
+		t.Fatal("errors.Is should match by code")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name      string
+		err       error
+		code      string
+		retryable bool
+	}{
+		{"app error passthrough", fmt.Errorf("ctx: %w", ErrViewNotFound), "VIEW_NOT_FOUND", false},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), "TIMEOUT_ERROR", true},
+		{"record not found", gorm.ErrRecordNotFound, "NOT_FOUND", false},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, "RESOURCE_EXISTS", false},
+		{"serialization failure", fmt.Errorf("tx: %w", &pgconn.PgError{Code: "40001"}), "TRANSACTION_CONFLICT", true},
+		{"connection exception", &pgconn.PgError{Code: "08006"}, "DATABASE_CONNECTION_ERROR", true},
+		{"unknown", fmt.Errorf("boom"), "INTERNAL_SERVER_ERROR", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := From(tc.err)
+			if got.Code != tc.code || got.Retryable != tc.retryable || IsRetryable(tc.err) != tc.retryable {
+				t.Fatalf("From(%v) = %s retryable=%v, want %s retryable=%v", tc.err, got.Code, got.Retryable, tc.code, tc.retryable)
+			}
+		})
+	}
+
+	if IsPermanent(fmt.Errorf("boom")) {
+		t.Error("unclassified errors should not be permanent")
+	}
+	if !IsPermanent(ErrValidationFailed) {
+		t.Error("validation errors should be permanent")
+	}
+	if UserMessage(fmt.Errorf("pq: secret detail")) != "服务器内部错误" {
+		t.Error("unknown errors should not leak raw messages")
+	}
+
+	dbErr := Database(&pgconn.PgError{Code: "40P01", Message: "deadlock"}, "保存记录失败")
+	if details, _ := dbErr.Details.(string); dbErr.Code != "TRANSACTION_CONFLICT" || !strings.HasPrefix(details, "保存记录失败: ") {
+		t.Fatalf("unexpected database error: %s %v", dbErr.Code, dbErr.Details)
+	}
+}
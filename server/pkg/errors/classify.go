@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// From 将任意错误归一化为应用错误
+// 已是（或包装了）应用错误时原样返回；可识别的底层错误（超时、取消、数据库错误）映射到目录中的错误码；
+// 其余错误统一视为内部错误，原始信息仅保留在 cause 中，不暴露给用户
+func From(err error) *AppError {
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := IsAppError(err); ok {
+		return appErr
+	}
+	if classified := classify(err); classified != nil {
+		return classified
+	}
+	return ErrInternalServer.WithCause(err)
+}
+
+// Database 将仓储层错误转换为应用错误
+// operation 描述失败的操作（如 "查找表格失败"），与原始错误一起写入 Details 便于排查
+func Database(err error, operation string) *AppError {
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := IsAppError(err); ok {
+		return appErr
+	}
+
+	appErr := classify(err)
+	if appErr == nil {
+		appErr = ErrDatabaseOperation.WithCause(err)
+	}
+	if operation != "" {
+		appErr.Details = operation + ": " + err.Error()
+	} else {
+		appErr.Details = err.Error()
+	}
+	return appErr
+}
+
+// IsRetryable 错误是否可重试（未能识别的错误视为不可重试）
+func IsRetryable(err error) bool {
+	appErr := From(err)
+	return appErr != nil && appErr.Retryable
+}
+
+// IsPermanent 错误是否确定不可重试
+// 用于已有重试循环：仅在错误被明确归类为不可重试时提前放弃，未识别的错误保持原有重试行为
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+	if appErr, ok := IsAppError(err); ok {
+		return !appErr.Retryable
+	}
+	if classified := classify(err); classified != nil {
+		return !classified.Retryable
+	}
+	return false
+}
+
+// GRPCCodeOf 错误对应的 gRPC 状态码
+func GRPCCodeOf(err error) GRPCCode {
+	if err == nil {
+		return GRPCOK
+	}
+	return From(err).GRPCCode()
+}
+
+// UserMessage 可直接展示给用户的错误消息
+func UserMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return From(err).Message
+}
+
+// Postgres SQLSTATE 错误码
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+	pgLockNotAvailable     = "55P03"
+)
+
+// classify 识别常见的底层错误，无法识别时返回 nil
+func classify(err error) *AppError {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.WithCause(err)
+	case errors.Is(err, context.Canceled):
+		return ErrRequestCanceled.WithCause(err)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound.WithCause(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrResourceExists.WithCause(err)
+	case errors.Is(err, driver.ErrBadConn):
+		return ErrDatabaseConnection.WithCause(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return classifyPostgres(pgErr, err)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrTimeout.WithCause(err)
+		}
+		return ErrDatabaseConnection.WithCause(err)
+	}
+	return nil
+}
+
+// classifyPostgres 按 SQLSTATE 映射 Postgres 错误
+func classifyPostgres(pgErr *pgconn.PgError, err error) *AppError {
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return ErrResourceExists.WithCause(err)
+	case pgForeignKeyViolation:
+		return ErrConflict.WithCause(err)
+	case pgNotNullViolation:
+		return ErrRequiredField.WithCause(err)
+	case pgCheckViolation:
+		return ErrInvalidValue.WithCause(err)
+	case pgSerializationFailure, pgDeadlockDetected, pgLockNotAvailable:
+		return ErrTransactionConflict.WithCause(err)
+	case pgQueryCanceled:
+		return ErrTimeout.WithCause(err)
+	}
+
+	switch {
+	case strings.HasPrefix(pgErr.Code, "08"), // connection_exception
+		strings.HasPrefix(pgErr.Code, "53"),  // insufficient_resources
+		strings.HasPrefix(pgErr.Code, "57P"): // admin_shutdown / crash_shutdown / cannot_connect_now
+		return ErrDatabaseConnection.WithCause(err)
+	case strings.HasPrefix(pgErr.Code, "22"): // data_exception
+		return ErrInvalidValue.WithCause(err)
+	}
+	return nil
+}
+
+// Is 同标准库 errors.Is（便于以本包别名 errors 导入时使用）
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As 同标准库 errors.As
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
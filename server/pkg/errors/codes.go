@@ -53,7 +53,19 @@ const (
 	CodeDuplicateRecord = 409102 // 记录重复
 	CodeDuplicateView   = 409103 // 视图名重复
 
+	// 并发冲突 (409xxx)
+	CodeVersionConflict     = 409201 // 乐观锁版本冲突
+	CodeTransactionConflict = 409202 // 事务序列化失败/死锁（可重试）
+	CodeIdempotencyInFlight = 409203 // 相同幂等键的请求处理中（可重试）
+	CodeRecordLocked        = 409204 // 记录（或字段）被其他用户锁定
+
+	CodePreconditionFailed = 412001 // If-Match 条件不满足
+
+	CodeQueryTooExpensive = 422101 // 查询开销超出请求类别的预算
+
 	CodeTooManyReq = 429001
+
+	CodeRequestCanceled = 499001
 )
 
 const (
@@ -84,6 +96,8 @@ var stringToNumeric = map[string]int{
 	"NOT_FOUND":             CodeNotFound,
 	"CONFLICT":              CodeConflict,
 	"TOO_MANY_REQUESTS":     CodeTooManyReq,
+	"REQUEST_CANCELED":      CodeRequestCanceled,
+	"PRECONDITION_FAILED":   CodePreconditionFailed,
 	"INTERNAL_SERVER_ERROR": CodeInternalError,
 
 	// 用户
@@ -103,9 +117,11 @@ var stringToNumeric = map[string]int{
 	"INVALID_REFRESH_TOKEN": CodeInvalidToken,
 
 	// 空间
-	"SPACE_NOT_FOUND":      CodeSpaceNotFound,
-	"SPACE_EXISTS":         CodeConflict,
-	"SPACE_NOT_ACCESSIBLE": CodeForbidden,
+	"SPACE_NOT_FOUND":       CodeSpaceNotFound,
+	"SPACE_EXISTS":          CodeConflict,
+	"SPACE_NOT_ACCESSIBLE":  CodeForbidden,
+	"SPACE_READ_ONLY":       CodeForbidden,
+	"MAINTENANCE_READ_ONLY": CodeServiceUnavailable,
 
 	// 基础
 	"BASE_NOT_FOUND":      CodeBaseNotFound,
@@ -127,6 +143,8 @@ var stringToNumeric = map[string]int{
 	"RECORD_NOT_FOUND":    CodeRecordNotFound,
 	"RECORD_EXISTS":       CodeConflict,
 	"INVALID_RECORD_DATA": CodeBadRequest,
+	"VERSION_CONFLICT":    CodeVersionConflict,
+	"RECORD_LOCKED":       CodeRecordLocked,
 
 	// 视图
 	"VIEW_NOT_FOUND":    CodeViewNotFound,
@@ -150,6 +168,11 @@ var stringToNumeric = map[string]int{
 	"DATABASE_TRANSACTION_ERROR": CodeDatabaseTransaction,
 	"DATABASE_OPERATION_ERROR":   CodeDatabaseOperation,
 	"TIMEOUT_ERROR":              CodeTimeout,
+	"TRANSACTION_CONFLICT":       CodeTransactionConflict,
+	"QUERY_TOO_EXPENSIVE":        CodeQueryTooExpensive,
+
+	// 幂等键
+	"IDEMPOTENCY_IN_PROGRESS": CodeIdempotencyInFlight,
 
 	// 缓存
 	"CACHE_CONNECTION_ERROR": CodeInternalError,
@@ -188,6 +211,7 @@ var stringToNumeric = map[string]int{
 	"OPERATION_NOT_ALLOWED": CodeForbidden,
 	"RESOURCE_IN_USE":       CodeConflict,
 	"QUOTA_EXCEEDED":        CodeForbidden,
+	"PLAN_LIMIT_EXCEEDED":   CodeForbidden,
 	"FEATURE_NOT_AVAILABLE": CodeServiceUnavailable,
 	"NOT_IMPLEMENTED":       CodeNotImplemented,
 }
@@ -1,22 +1,59 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
 
 // AppError 应用错误结构
+// Message 为可展示给用户的消息；底层原因通过 cause 保留，仅用于日志与 errors.Is/As
 type AppError struct {
 	Code       string      `json:"code"`
 	Message    string      `json:"message"`
 	Details    interface{} `json:"details,omitempty"`
+	Retryable  bool        `json:"retryable,omitempty"`
 	HTTPStatus int         `json:"-"`
+	Category   Category    `json:"-"`
+
+	cause error
 }
 
 func (e *AppError) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
 	return e.Message
 }
 
+// Unwrap 返回底层原因
+func (e *AppError) Unwrap() error {
+	return e.cause
+}
+
+// Is 按错误码比较，使 errors.Is(err, ErrTableNotFound) 对副本同样成立
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// GRPCCode 错误对应的 gRPC 状态码
+func (e *AppError) GRPCCode() GRPCCode {
+	return e.Category.GRPCCode()
+}
+
+// NumericCode 错误对应的数字业务码
+func (e *AppError) NumericCode() int {
+	return NumericCodeFromString(e.Code, e.HTTPStatus)
+}
+
+// WithCause 返回携带底层原因的副本（不修改预定义错误）
+func (e *AppError) WithCause(cause error) *AppError {
+	clone := *e
+	clone.cause = cause
+	return &clone
+}
+
 // ErrorResponse HTTP错误响应结构
 type ErrorResponse struct {
 	Error   string      `json:"error"`
@@ -25,34 +62,46 @@ type ErrorResponse struct {
 	TraceID string      `json:"trace_id,omitempty"`
 }
 
-// New 创建新的应用错误
+// New 创建新的应用错误（类别由HTTP状态码推断，并登记到错误目录）
 func New(code, message string, httpStatus int) *AppError {
-	return &AppError{
+	return define(code, message, httpStatus, categoryForStatus(httpStatus))
+}
+
+// define 以指定类别创建应用错误并登记到错误目录
+func define(code, message string, httpStatus int, category Category) *AppError {
+	return register(&AppError{
 		Code:       code,
 		Message:    message,
 		HTTPStatus: httpStatus,
-	}
+		Category:   category,
+		Retryable:  category.Retryable(),
+	})
 }
 
 // Newf 创建格式化的应用错误
 func Newf(code string, httpStatus int, format string, args ...interface{}) *AppError {
+	category := categoryForStatus(httpStatus)
 	return &AppError{
 		Code:       code,
 		Message:    fmt.Sprintf(format, args...),
 		HTTPStatus: httpStatus,
+		Category:   category,
+		Retryable:  category.Retryable(),
 	}
 }
 
-// WithDetails 添加错误详情
+// WithDetails 返回添加了错误详情的副本（预定义错误是共享的，不能原地修改）
 func (e *AppError) WithDetails(details interface{}) *AppError {
-	e.Details = details
-	return e
+	clone := *e
+	clone.Details = details
+	return &clone
 }
 
-// WithMessage 添加错误消息
+// WithMessage 返回替换了错误消息的副本
 func (e *AppError) WithMessage(message string) *AppError {
-	e.Message = message
-	return e
+	clone := *e
+	clone.Message = message
+	return &clone
 }
 
 // 预定义错误
@@ -65,6 +114,10 @@ var (
 	ErrNotFound        = New("NOT_FOUND", "资源不存在", http.StatusNotFound)
 	ErrConflict        = New("CONFLICT", "资源冲突", http.StatusConflict)
 	ErrTooManyRequests = New("TOO_MANY_REQUESTS", "请求过于频繁", http.StatusTooManyRequests)
+	ErrRequestCanceled = New("REQUEST_CANCELED", "请求已取消", StatusClientClosedRequest)
+
+	// 条件请求错误（If-Match 与资源当前 ETag 不一致）
+	ErrPreconditionFailed = define("PRECONDITION_FAILED", "资源已被修改，请重新获取后重试", http.StatusPreconditionFailed, CategoryConflict)
 
 	// 用户相关错误
 	ErrUserNotFound       = New("USER_NOT_FOUND", "用户不存在", http.StatusNotFound)
@@ -82,10 +135,24 @@ var (
 	ErrRefreshTokenExpired = New("REFRESH_TOKEN_EXPIRED", "刷新令牌已过期", http.StatusUnauthorized)
 	ErrInvalidRefreshToken = New("INVALID_REFRESH_TOKEN", "无效的刷新令牌", http.StatusUnauthorized)
 
+	// 工作空间安全策略错误
+	ErrIPNotAllowed         = New("IP_NOT_ALLOWED", "当前IP不在工作空间白名单内", http.StatusForbidden)
+	ErrSessionExpired       = New("SESSION_EXPIRED", "会话已超过最长时长，请重新登录", http.StatusUnauthorized)
+	ErrSessionLimitExceeded = New("SESSION_LIMIT_EXCEEDED", "并发会话数超出限制，请重新登录", http.StatusUnauthorized)
+	ErrReauthRequired       = New("REAUTH_REQUIRED", "敏感操作需要重新认证", http.StatusForbidden)
+
+	// 幂等键错误
+	ErrIdempotencyKeyInvalid = New("IDEMPOTENCY_KEY_INVALID", "Idempotency-Key 须为 1-255 个可见 ASCII 字符", http.StatusBadRequest)
+	ErrIdempotencyKeyReused  = New("IDEMPOTENCY_KEY_REUSED", "该 Idempotency-Key 已用于内容不同的请求", http.StatusUnprocessableEntity)
+	ErrIdempotencyInProgress = define("IDEMPOTENCY_IN_PROGRESS", "相同 Idempotency-Key 的请求正在处理中，请稍后重试", http.StatusConflict, CategoryAborted)
+	ErrIdempotencyNotStored  = New("IDEMPOTENCY_RESPONSE_NOT_STORED", "相同 Idempotency-Key 的请求已完成，但响应过大未保存，无法重放", http.StatusConflict)
+
 	// 空间相关错误
-	ErrSpaceNotFound      = New("SPACE_NOT_FOUND", "空间不存在", http.StatusNotFound)
-	ErrSpaceExists        = New("SPACE_EXISTS", "空间已存在", http.StatusConflict)
-	ErrSpaceNotAccessible = New("SPACE_NOT_ACCESSIBLE", "无权访问此空间", http.StatusForbidden)
+	ErrSpaceNotFound       = New("SPACE_NOT_FOUND", "空间不存在", http.StatusNotFound)
+	ErrSpaceExists         = New("SPACE_EXISTS", "空间已存在", http.StatusConflict)
+	ErrSpaceNotAccessible  = New("SPACE_NOT_ACCESSIBLE", "无权访问此空间", http.StatusForbidden)
+	ErrSpaceReadOnly       = New("SPACE_READ_ONLY", "工作空间已停用，只允许读取", http.StatusForbidden)
+	ErrMaintenanceReadOnly = New("MAINTENANCE_READ_ONLY", "系统维护中，暂时只允许读取", http.StatusServiceUnavailable)
 
 	// 基础相关错误
 	ErrBaseNotFound      = New("BASE_NOT_FOUND", "基础不存在", http.StatusNotFound)
@@ -107,6 +174,8 @@ var (
 	ErrRecordNotFound    = New("RECORD_NOT_FOUND", "记录不存在", http.StatusNotFound)
 	ErrRecordExists      = New("RECORD_EXISTS", "记录已存在", http.StatusConflict)
 	ErrInvalidRecordData = New("INVALID_RECORD_DATA", "记录数据格式错误", http.StatusBadRequest)
+	ErrVersionConflict   = New("VERSION_CONFLICT", "数据已被修改，请刷新后重试", http.StatusConflict)
+	ErrRecordLocked      = New("RECORD_LOCKED", "记录已被其他用户锁定", http.StatusConflict)
 
 	// 视图相关错误
 	ErrViewNotFound    = New("VIEW_NOT_FOUND", "视图不存在", http.StatusNotFound)
@@ -125,18 +194,20 @@ var (
 	ErrInvalidFileFormat = New("INVALID_FILE_FORMAT", "不支持的文件格式", http.StatusBadRequest)
 
 	// 数据库相关错误
-	ErrDatabaseConnection  = New("DATABASE_CONNECTION_ERROR", "数据库连接错误", http.StatusInternalServerError)
+	ErrDatabaseConnection  = define("DATABASE_CONNECTION_ERROR", "数据库连接错误", http.StatusInternalServerError, CategoryUnavailable)
 	ErrDatabaseQuery       = New("DATABASE_QUERY_ERROR", "数据库查询错误", http.StatusInternalServerError)
 	ErrDatabaseTransaction = New("DATABASE_TRANSACTION_ERROR", "数据库事务错误", http.StatusInternalServerError)
 	ErrDatabaseOperation   = New("DATABASE_OPERATION_ERROR", "数据库操作错误", http.StatusInternalServerError)
 	ErrTimeout             = New("TIMEOUT_ERROR", "操作超时", http.StatusRequestTimeout)
+	ErrTransactionConflict = define("TRANSACTION_CONFLICT", "并发写入冲突，请稍后重试", http.StatusConflict, CategoryAborted)
+	ErrQueryTooExpensive   = New("QUERY_TOO_EXPENSIVE", "查询开销超出限制，请缩小查询范围", http.StatusUnprocessableEntity)
 
 	// 缓存相关错误
-	ErrCacheConnection = New("CACHE_CONNECTION_ERROR", "缓存连接错误", http.StatusInternalServerError)
+	ErrCacheConnection = define("CACHE_CONNECTION_ERROR", "缓存连接错误", http.StatusInternalServerError, CategoryUnavailable)
 	ErrCacheOperation  = New("CACHE_OPERATION_ERROR", "缓存操作错误", http.StatusInternalServerError)
 
 	// 队列相关错误
-	ErrQueueConnection = New("QUEUE_CONNECTION_ERROR", "队列连接错误", http.StatusInternalServerError)
+	ErrQueueConnection = define("QUEUE_CONNECTION_ERROR", "队列连接错误", http.StatusInternalServerError, CategoryUnavailable)
 	ErrQueueOperation  = New("QUEUE_OPERATION_ERROR", "队列操作错误", http.StatusInternalServerError)
 	ErrTaskFailed      = New("TASK_FAILED", "任务执行失败", http.StatusInternalServerError)
 
@@ -170,6 +241,7 @@ var (
 	ErrOperationNotAllowed = New("OPERATION_NOT_ALLOWED", "不允许此操作", http.StatusForbidden)
 	ErrResourceInUse       = New("RESOURCE_IN_USE", "资源正在使用中", http.StatusConflict)
 	ErrQuotaExceeded       = New("QUOTA_EXCEEDED", "配额已超出限制", http.StatusForbidden)
+	ErrPlanLimitExceeded   = New("PLAN_LIMIT_EXCEEDED", "已达到套餐限额", http.StatusForbidden)
 	ErrFeatureNotAvailable = New("FEATURE_NOT_AVAILABLE", "功能不可用", http.StatusServiceUnavailable)
 	ErrNotImplemented      = New("NOT_IMPLEMENTED", "功能暂未实现", http.StatusNotImplemented)
 )
@@ -192,10 +264,14 @@ func NewValidationError(field, message string, value interface{}) *ValidationErr
 
 // Wrap 包装错误
 func Wrap(err error, code, message string, httpStatus int) *AppError {
+	category := categoryForStatus(httpStatus)
 	appErr := &AppError{
 		Code:       code,
 		Message:    message,
 		HTTPStatus: httpStatus,
+		Category:   category,
+		Retryable:  category.Retryable(),
+		cause:      err,
 	}
 
 	if err != nil {
@@ -205,9 +281,10 @@ func Wrap(err error, code, message string, httpStatus int) *AppError {
 	return appErr
 }
 
-// IsAppError 检查是否为应用错误
+// IsAppError 检查是否为应用错误（支持被 %w 包装的应用错误）
 func IsAppError(err error) (*AppError, bool) {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
 		return appErr, true
 	}
 	return nil, false
@@ -215,7 +292,7 @@ func IsAppError(err error) (*AppError, bool) {
 
 // GetHTTPStatus 获取HTTP状态码
 func GetHTTPStatus(err error) int {
-	if appErr, ok := IsAppError(err); ok {
+	if appErr := From(err); appErr != nil {
 		return appErr.HTTPStatus
 	}
 	return http.StatusInternalServerError
@@ -0,0 +1,87 @@
+// Package etag 实体标签（ETag）生成、解析与条件请求匹配
+//
+// 记录使用“版本号-内容摘要”形式的强 ETag：版本号用于 If-Match 乐观锁，
+// 内容摘要保证计算字段、脱敏等不改变版本号的变化也能让客户端重新获取。
+// 表结构（字段列表）使用内容摘要作为 ETag。
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// digestLength 内容摘要长度（十六进制字符数）
+const digestLength = 16
+
+// Digest 以内容摘要作为强 ETag
+func Digest(content []byte) string {
+	return `"` + digest(content) + `"`
+}
+
+// Versioned 以版本号与内容摘要作为强 ETag，如 "12-9f86d081884c7d65"
+func Versioned(version int64, content []byte) string {
+	return `"` + strconv.FormatInt(version, 10) + "-" + digest(content) + `"`
+}
+
+// Version 解析 Versioned 生成的 ETag 中的版本号
+func Version(tag string) (int64, bool) {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "W/") || len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	value := tag[1 : len(tag)-1]
+	if i := strings.IndexByte(value, '-'); i >= 0 {
+		value = value[:i]
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// MatchNone If-None-Match 匹配（弱比较），命中时 GET 应返回 304
+func MatchNone(header, tag string) bool {
+	return match(header, tag, false)
+}
+
+// Match If-Match 匹配（强比较，弱 ETag 永不匹配）
+func Match(header, tag string) bool {
+	return match(header, tag, true)
+}
+
+// Tags 拆分条件请求头中的 ETag 列表
+func Tags(header string) []string {
+	var tags []string
+	for _, part := range strings.Split(header, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			tags = append(tags, part)
+		}
+	}
+	return tags
+}
+
+func match(header, tag string, strong bool) bool {
+	for _, candidate := range Tags(header) {
+		if candidate == "*" {
+			return true
+		}
+		if strong {
+			if !strings.HasPrefix(candidate, "W/") && candidate == tag {
+				return true
+			}
+			continue
+		}
+		if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:digestLength]
+}
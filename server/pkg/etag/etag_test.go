@@ -0,0 +1,46 @@
+package etag
+
+import "testing"
+
+func TestVersioned(t *testing.T) {
+	tag := Versioned(12, []byte(`{"id":"rec1"}`))
+	if version, ok := Version(tag); !ok || version != 12 {
+		t.Fatalf("Version(%s) = %d, %v; want 12, true", tag, version, ok)
+	}
+	if tag == Versioned(12, []byte(`{"id":"rec2"}`)) {
+		t.Errorf("内容不同的 ETag 不应相同")
+	}
+
+	for _, invalid := range []string{"", "*", `W/"12-abc"`, `"abc"`, `"0-abc"`, `12-abc`} {
+		if _, ok := Version(invalid); ok {
+			t.Errorf("Version(%q) 应解析失败", invalid)
+		}
+	}
+	if version, ok := Version(`"7"`); !ok || version != 7 {
+		t.Errorf("仅含版本号的 ETag 应可解析，得到 %d, %v", version, ok)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tag := Digest([]byte("fields"))
+
+	cases := []struct {
+		header      string
+		match, none bool
+	}{
+		{tag, true, true},
+		{"*", true, true},
+		{`"other", ` + tag, true, true},
+		{"W/" + tag, false, true}, // If-Match 使用强比较
+		{`"other"`, false, false},
+		{"", false, false},
+	}
+	for _, c := range cases {
+		if got := Match(c.header, tag); got != c.match {
+			t.Errorf("Match(%q) = %v, want %v", c.header, got, c.match)
+		}
+		if got := MatchNone(c.header, tag); got != c.none {
+			t.Errorf("MatchNone(%q) = %v, want %v", c.header, got, c.none)
+		}
+	}
+}
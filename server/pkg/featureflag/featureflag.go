@@ -0,0 +1,177 @@
+// Package featureflag 功能开关
+//
+// 按工作空间/用户评估功能开关，支持总开关、黑白名单与按比例灰度（同一主体的结果稳定），
+// 开关定义来自配置文件或远程配置，热更新时整体替换，评估无锁。
+package featureflag
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"sync/atomic"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+// 灰度分桶依据
+const (
+	RolloutBySpace = "space" // 按工作空间（默认；同一空间内所有成员结果一致）
+	RolloutByUser  = "user"  // 按用户
+)
+
+// 内置功能开关（配置默认全量开启，通过 features.<key> 灰度或回退）
+const (
+	KeyRealtimeGateway = "realtime_gateway" // 实时协作网关（WebSocket 协作与订阅）
+	KeyFilterEngine    = "filter_engine"    // 服务端视图过滤引擎（虚拟滚动窗口）
+)
+
+// 评估原因
+const (
+	ReasonUnknown   = "unknown"   // 未定义的开关
+	ReasonDisabled  = "disabled"  // 总开关关闭
+	ReasonDenied    = "denied"    // 命中黑名单
+	ReasonAllowlist = "allowlist" // 命中白名单
+	ReasonRollout   = "rollout"   // 落入灰度比例
+	ReasonDefault   = "default"   // 未命中任何规则
+)
+
+// Flag 功能开关定义
+type Flag struct {
+	Key         string   `json:"key"`
+	Description string   `json:"description,omitempty"`
+	Enabled     bool     `json:"enabled"`              // 总开关，关闭时对所有主体关闭
+	Rollout     int      `json:"rollout"`              // 灰度比例 0-100
+	RolloutBy   string   `json:"rollout_by,omitempty"` // space / user
+	Spaces      []string `json:"spaces,omitempty"`     // 始终开启的工作空间
+	Users       []string `json:"users,omitempty"`      // 始终开启的用户
+	DenySpaces  []string `json:"deny_spaces,omitempty"`
+	DenyUsers   []string `json:"deny_users,omitempty"`
+}
+
+// Subject 评估主体
+type Subject struct {
+	SpaceID string
+	UserID  string
+}
+
+// Decision 评估结果
+type Decision struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason"`
+}
+
+// Evaluator 功能开关评估器
+type Evaluator struct {
+	flags atomic.Pointer[map[string]Flag]
+}
+
+// NewEvaluator 创建评估器
+func NewEvaluator(flags ...Flag) *Evaluator {
+	e := &Evaluator{}
+	e.Set(flags)
+	return e
+}
+
+// Default 全局评估器（由配置服务在启动与热更新时设置）
+var Default = NewEvaluator()
+
+// Set 整体替换开关定义
+func (e *Evaluator) Set(flags []Flag) {
+	next := make(map[string]Flag, len(flags))
+	for _, f := range flags {
+		next[f.Key] = f
+	}
+	e.flags.Store(&next)
+}
+
+// Flags 当前开关定义（按 key 排序）
+func (e *Evaluator) Flags() []Flag {
+	current := *e.flags.Load()
+	result := make([]Flag, 0, len(current))
+	for _, f := range current {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Evaluate 评估开关：黑名单 > 总开关 > 白名单 > 灰度比例
+func (e *Evaluator) Evaluate(key string, subject Subject) Decision {
+	flag, ok := (*e.flags.Load())[key]
+	if !ok {
+		return Decision{Key: key, Reason: ReasonUnknown}
+	}
+	return flag.Evaluate(subject)
+}
+
+// Enabled 开关是否对主体开启
+func (e *Evaluator) Enabled(key string, subject Subject) bool {
+	return e.Evaluate(key, subject).Enabled
+}
+
+// EvaluateAll 评估全部开关
+func (e *Evaluator) EvaluateAll(subject Subject) []Decision {
+	flags := e.Flags()
+	result := make([]Decision, 0, len(flags))
+	for _, f := range flags {
+		result = append(result, f.Evaluate(subject))
+	}
+	return result
+}
+
+// Evaluate 评估单个开关
+func (f Flag) Evaluate(s Subject) Decision {
+	d := Decision{Key: f.Key}
+
+	if contains(f.DenyUsers, s.UserID) || contains(f.DenySpaces, s.SpaceID) {
+		d.Reason = ReasonDenied
+		return d
+	}
+	if !f.Enabled {
+		d.Reason = ReasonDisabled
+		return d
+	}
+	if contains(f.Users, s.UserID) || contains(f.Spaces, s.SpaceID) {
+		d.Enabled, d.Reason = true, ReasonAllowlist
+		return d
+	}
+
+	id := s.SpaceID
+	if f.RolloutBy == RolloutByUser || id == "" {
+		id = s.UserID
+	}
+	if f.Rollout >= 100 || (f.Rollout > 0 && id != "" && Bucket(f.Key, id) < f.Rollout) {
+		d.Enabled, d.Reason = true, ReasonRollout
+		return d
+	}
+	d.Reason = ReasonDefault
+	return d
+}
+
+// Bucket 主体在开关上的灰度分桶（0-99）；按 key 分散，避免所有开关总是命中同一批主体
+func Bucket(key, id string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{':'})
+	h.Write([]byte(id))
+	return int(h.Sum32() % 100)
+}
+
+func contains(list []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled 使用全局评估器判断开关是否对当前用户（取自 ctx）与指定工作空间开启
+func Enabled(ctx context.Context, key, spaceID string) bool {
+	userID, _ := authctx.UserFrom(ctx)
+	return Default.Enabled(key, Subject{SpaceID: spaceID, UserID: userID})
+}
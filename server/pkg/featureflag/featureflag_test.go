@@ -0,0 +1,83 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+func TestEvaluateOrder(t *testing.T) {
+	e := NewEvaluator(
+		Flag{Key: "off", Enabled: false, Users: []string{"usr1"}},
+		Flag{Key: "allow", Enabled: true, Spaces: []string{"spc1"}, DenyUsers: []string{"usr2"}},
+		Flag{Key: "all", Enabled: true, Rollout: 100, DenySpaces: []string{"spc9"}},
+	)
+
+	cases := []struct {
+		key     string
+		subject Subject
+		enabled bool
+		reason  string
+	}{
+		{"missing", Subject{UserID: "usr1"}, false, ReasonUnknown},
+		{"off", Subject{UserID: "usr1"}, false, ReasonDisabled},
+		{"allow", Subject{SpaceID: "spc1", UserID: "usr1"}, true, ReasonAllowlist},
+		{"allow", Subject{SpaceID: "spc1", UserID: "usr2"}, false, ReasonDenied},
+		{"allow", Subject{SpaceID: "spc2", UserID: "usr1"}, false, ReasonDefault},
+		{"all", Subject{SpaceID: "spc2"}, true, ReasonRollout},
+		{"all", Subject{SpaceID: "spc9"}, false, ReasonDenied},
+	}
+	for _, c := range cases {
+		d := e.Evaluate(c.key, c.subject)
+		if d.Enabled != c.enabled || d.Reason != c.reason {
+			t.Errorf("Evaluate(%s, %+v) = %+v; want enabled=%v reason=%s", c.key, c.subject, d, c.enabled, c.reason)
+		}
+	}
+}
+
+func TestRolloutIsStableAndProportional(t *testing.T) {
+	flag := Flag{Key: "gateway", Enabled: true, Rollout: 30}
+
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		s := Subject{SpaceID: fmt.Sprintf("spc%d", i), UserID: fmt.Sprintf("usr%d", i%7)}
+		first := flag.Evaluate(s).Enabled
+		if flag.Evaluate(s).Enabled != first {
+			t.Fatalf("同一主体的灰度结果应稳定: %+v", s)
+		}
+		if first {
+			enabled++
+		}
+	}
+	if enabled < 2700 || enabled > 3300 {
+		t.Errorf("30%% 灰度命中 %d/10000，偏差过大", enabled)
+	}
+
+	// 按空间灰度时，同一空间的所有成员结果一致
+	perSpace := flag.Evaluate(Subject{SpaceID: "spcX", UserID: "a"}).Enabled
+	for _, user := range []string{"b", "c", "d"} {
+		if flag.Evaluate(Subject{SpaceID: "spcX", UserID: user}).Enabled != perSpace {
+			t.Errorf("按空间灰度时同一空间成员结果应一致")
+		}
+	}
+}
+
+func TestEnabledFromContext(t *testing.T) {
+	old := Default
+	defer func() { Default = old }()
+	Default = NewEvaluator(Flag{Key: "beta", Enabled: true, Users: []string{"usr1"}})
+
+	if !Enabled(authctx.WithUser(context.Background(), "usr1"), "beta", "") {
+		t.Errorf("白名单用户应开启")
+	}
+	if Enabled(context.Background(), "beta", "") {
+		t.Errorf("匿名请求不应命中用户白名单")
+	}
+
+	Default.Set(nil)
+	if Enabled(authctx.WithUser(context.Background(), "usr1"), "beta", "") {
+		t.Errorf("热更新移除开关后应关闭")
+	}
+}
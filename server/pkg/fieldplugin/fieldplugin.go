@@ -0,0 +1,182 @@
+// Package fieldplugin 自定义字段类型扩展点 ✨
+//
+// 企业部署可以在不修改服务代码的情况下增加领域专用的字段类型（如身份证号、车牌号、计量单位）：
+// 实现 FieldType 接口，并在部署的 main 包中通过空白导入，在 init 中调用 Register 注册。
+//
+//	func init() {
+//		fieldplugin.MustRegister(plateNumber{})
+//	}
+//
+// 注册后，字段类型即可用于创建字段，其余环节通过接口回调：
+//   - 校验：写入单元格时调用 Validate，返回值作为存储值
+//   - 存储：物理列类型由 Storage 决定，读出的值经 FromStorage 转换为 API 值
+//   - 格式化：视图展示文本、导出等调用 Format
+//   - 公式：公式引用该字段时使用 FormulaValue 的返回值
+//   - 选项：OptionsSchema 返回选项的 JSON Schema，创建与更新字段时据此校验，并通过字段选项 Schema API 暴露
+//
+// 自定义类型名必须以 "x-" 开头，避免与内置字段类型以及今后新增的内置类型冲突。
+// 自定义字段按基础字段处理：不参与依赖图计算，也不能作为关联字段
+package fieldplugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// StorageType 自定义字段的物理列类型
+type StorageType string
+
+const (
+	StorageText      StorageType = "TEXT"
+	StorageNumeric   StorageType = "NUMERIC"
+	StorageBoolean   StorageType = "BOOLEAN"
+	StorageTimestamp StorageType = "TIMESTAMP"
+	StorageJSON      StorageType = "JSONB"
+)
+
+// Valid 是否为支持的列类型
+func (s StorageType) Valid() bool {
+	switch s {
+	case StorageText, StorageNumeric, StorageBoolean, StorageTimestamp, StorageJSON:
+		return true
+	}
+	return false
+}
+
+// FieldType 自定义字段类型
+//
+// 实现必须是并发安全的：同一个实例会被所有请求共享。options 为字段的自定义选项（可能为 nil），
+// 已按 OptionsSchema 校验
+type FieldType interface {
+	// Name 字段类型名，以 "x-" 开头，如 x-plate-number
+	Name() string
+	// Storage 物理列类型
+	Storage() StorageType
+	// OptionsSchema 选项的 JSON Schema（type 为 object），返回 nil 表示不限制选项
+	OptionsSchema() map[string]interface{}
+	// Validate 校验并规范化写入的单元格值，返回写入物理列的值；value 不为 nil
+	Validate(value interface{}, options map[string]interface{}) (interface{}, error)
+	// FromStorage 将物理列读出的值转换为返回给客户端的值（JSONB 列可能是 []byte 或字符串）
+	FromStorage(stored interface{}) (interface{}, error)
+	// Format 单元格值的展示文本
+	Format(value interface{}, options map[string]interface{}) string
+	// FormulaValue 公式引用该字段时使用的值
+	FormulaValue(value interface{}) interface{}
+}
+
+// Base 提供 FieldType 除 Name 与 Storage 外的默认实现，自定义类型可嵌入后只覆盖需要的方法
+type Base struct{}
+
+// OptionsSchema 不限制选项
+func (Base) OptionsSchema() map[string]interface{} { return nil }
+
+// Validate 原样写入
+func (Base) Validate(value interface{}, options map[string]interface{}) (interface{}, error) {
+	return value, nil
+}
+
+// FromStorage 原样返回，JSON 对象与数组（JSONB 列读出的文本）解码为对应的值
+func (Base) FromStorage(stored interface{}) (interface{}, error) {
+	var data []byte
+	switch v := stored.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return stored, nil
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return string(data), nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(trimmed, &decoded); err != nil {
+		return string(data), nil
+	}
+	return decoded, nil
+}
+
+// Format 字符串原样展示，其余值展示为 JSON
+func (Base) Format(value interface{}, options map[string]interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// FormulaValue 原样使用
+func (Base) FormulaValue(value interface{}) interface{} { return value }
+
+var (
+	// ErrInvalidName 类型名不符合规则
+	ErrInvalidName = errors.New("自定义字段类型名必须以 x- 开头，且只包含小写字母、数字和连字符")
+	// ErrAlreadyRegistered 类型名已注册
+	ErrAlreadyRegistered = errors.New("自定义字段类型已注册")
+
+	namePattern = regexp.MustCompile(`^x-[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	mu       sync.RWMutex
+	registry = map[string]FieldType{}
+)
+
+// Register 注册自定义字段类型
+func Register(ft FieldType) error {
+	if ft == nil {
+		return errors.New("自定义字段类型不能为空")
+	}
+	name := ft.Name()
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if !ft.Storage().Valid() {
+		return fmt.Errorf("自定义字段类型 %s 的列类型无效: %q", name, ft.Storage())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := registry[name]; exists {
+		return fmt.Errorf("%w: %s", ErrAlreadyRegistered, name)
+	}
+	registry[name] = ft
+	return nil
+}
+
+// MustRegister 注册自定义字段类型，失败时 panic（用于 init）
+func MustRegister(ft FieldType) {
+	if err := Register(ft); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup 按类型名查找自定义字段类型
+func Lookup(name string) (FieldType, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	ft, ok := registry[name]
+	return ft, ok
+}
+
+// Names 已注册的自定义字段类型名（排序）
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,73 @@
+package fieldplugin
+
+import (
+	"errors"
+	"testing"
+)
+
+type plateNumber struct{ Base }
+
+func (plateNumber) Name() string         { return "x-plate-number" }
+func (plateNumber) Storage() StorageType { return StorageText }
+
+type badStorage struct{ Base }
+
+func (badStorage) Name() string         { return "x-bad-storage" }
+func (badStorage) Storage() StorageType { return "BLOB" }
+
+type namedType struct {
+	Base
+	name string
+}
+
+func (t namedType) Name() string       { return t.name }
+func (namedType) Storage() StorageType { return StorageJSON }
+
+func TestRegister(t *testing.T) {
+	defer delete(registry, "x-plate-number")
+
+	if err := Register(plateNumber{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := Register(plateNumber{}); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Fatalf("expected duplicate registration to fail, got %v", err)
+	}
+	if _, ok := Lookup("x-plate-number"); !ok {
+		t.Fatal("expected registered type to be found")
+	}
+	if names := Names(); len(names) != 1 || names[0] != "x-plate-number" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	for _, name := range []string{"text", "x-", "x-Plate", "x_plate", "x-plate-"} {
+		if err := Register(namedType{name: name}); !errors.Is(err, ErrInvalidName) {
+			t.Fatalf("expected %q to be rejected, got %v", name, err)
+		}
+	}
+	if err := Register(badStorage{}); err == nil {
+		t.Fatal("expected unsupported storage type to be rejected")
+	}
+}
+
+func TestBaseDefaults(t *testing.T) {
+	var b Base
+	decoded, err := b.FromStorage([]byte(`{"unit":"kg","value":2}`))
+	if err != nil {
+		t.Fatalf("FromStorage: %v", err)
+	}
+	obj, ok := decoded.(map[string]interface{})
+	if !ok || obj["unit"] != "kg" {
+		t.Fatalf("expected JSON column decoded, got %#v", decoded)
+	}
+	for _, text := range []string{"沪A12345", "123"} {
+		if got, _ := b.FromStorage(text); got != text {
+			t.Fatalf("expected plain text kept, got %#v", got)
+		}
+	}
+	if got := b.Format(map[string]interface{}{"unit": "kg"}, nil); got != `{"unit":"kg"}` {
+		t.Fatalf("unexpected format: %s", got)
+	}
+	if got := b.Format(nil, nil); got != "" {
+		t.Fatalf("expected empty text for nil, got %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+type requestIDKey struct{}
+
+// ContextWithRequestID 将请求ID写入 ctx（由 HTTP 中间件调用）
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 获取 ctx 中的请求ID
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextFieldsFunc 从 ctx 提取关联字段（如 trace_id）
+type ContextFieldsFunc func(ctx context.Context) []zap.Field
+
+var (
+	contextFieldsMu    sync.RWMutex
+	contextFieldsFuncs []ContextFieldsFunc
+)
+
+// RegisterContextFields 注册关联字段提取器
+// 供不能被 logger 反向依赖的包使用（例如 tracing 注册 trace_id/span_id）
+func RegisterContextFields(fn ContextFieldsFunc) {
+	contextFieldsMu.Lock()
+	contextFieldsFuncs = append(contextFieldsFuncs, fn)
+	contextFieldsMu.Unlock()
+}
+
+// ContextFields 请求关联字段：request_id、user_id 以及已注册提取器返回的字段
+func ContextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if id := RequestIDFromContext(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if userID, ok := authctx.UserFrom(ctx); ok {
+		fields = append(fields, zap.String("user_id", userID))
+	}
+
+	contextFieldsMu.RLock()
+	funcs := contextFieldsFuncs
+	contextFieldsMu.RUnlock()
+	for _, fn := range funcs {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+// Ctx 返回携带请求关联字段的全局日志实例
+func Ctx(ctx context.Context) *zap.Logger {
+	if Logger == nil {
+		return zap.NewNop()
+	}
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return Logger
+	}
+	return Logger.With(fields...)
+}
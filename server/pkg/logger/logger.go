@@ -20,6 +20,11 @@ type LoggerConfig struct {
 	Level      string `json:"level"`      // debug, info, warn, error
 	Format     string `json:"format"`     // json, console
 	OutputPath string `json:"outputPath"` // stdout, stderr, file path
+
+	// Modules 模块级别覆盖，如 {"repository.field": "debug"}（见 Named）
+	Modules map[string]string `json:"modules"`
+	// Sampling 模块日志的热点采样
+	Sampling SamplingConfig `json:"sampling"`
 }
 
 // Init 初始化日志
@@ -74,11 +79,19 @@ func Init(config LoggerConfig) error {
 
 	writer := zapcore.NewMultiWriteSyncer(writers...)
 
-	// 创建核心
-	core := zapcore.NewCore(encoder, writer, level)
+	// 创建核心：底层核心不过滤级别，全局与各模块日志器分别按各自级别过滤
+	core := zapcore.NewCore(encoder, writer, zapcore.DebugLevel)
+	atomicLevel := zap.NewAtomicLevelAt(level)
+
+	for module, moduleLevel := range config.Modules {
+		if err := SetModuleLevel(module, moduleLevel); err != nil {
+			os.Stderr.WriteString("警告: 模块日志级别无效 " + module + "=" + moduleLevel + "\n")
+		}
+	}
+	current.Store(&state{core: core, level: atomicLevel, sampling: config.Sampling})
 
 	// 创建日志实例
-	Logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	Logger = zap.New(&levelCore{Core: core, level: atomicLevel}, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 	Sugar = Logger.Sugar()
 
 	return nil
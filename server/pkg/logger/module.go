@@ -0,0 +1,260 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig 热点路径日志采样配置
+// 同一模块内同级别、同消息的日志，每个 Tick 周期内先输出 Initial 条，之后每 Thereafter 条输出 1 条。
+// 仅作用于 Debug/Info，Warn 及以上始终输出。
+type SamplingConfig struct {
+	Enabled    bool          `json:"enabled"`
+	Initial    int           `json:"initial"`
+	Thereafter int           `json:"thereafter"`
+	Tick       time.Duration `json:"tick"`
+}
+
+// state Init 后的日志核心状态
+type state struct {
+	core     zapcore.Core // 不做级别过滤的底层核心
+	level    zap.AtomicLevel
+	sampling SamplingConfig
+}
+
+var (
+	current      atomic.Pointer[state]
+	moduleLevels atomic.Pointer[map[string]zapcore.Level]
+	moduleMu     sync.Mutex
+	moduleCache  sync.Map // name -> *Module
+)
+
+// SetLevel 运行时调整全局日志级别
+func SetLevel(level string) error {
+	st := current.Load()
+	if st == nil {
+		return nil
+	}
+	l, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	st.level.SetLevel(l)
+	return nil
+}
+
+// SetModuleLevel 运行时调整模块日志级别（level 为空表示恢复跟随全局级别）
+// 模块名按 "." 分层，未显式配置的子模块继承父模块级别（如 repository.field 继承 repository）
+func SetModuleLevel(module, level string) error {
+	module = strings.TrimSpace(module)
+	var (
+		l   zapcore.Level
+		err error
+	)
+	if level != "" {
+		if l, err = zapcore.ParseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	moduleMu.Lock()
+	defer moduleMu.Unlock()
+	next := map[string]zapcore.Level{}
+	if old := moduleLevels.Load(); old != nil {
+		for k, v := range *old {
+			next[k] = v
+		}
+	}
+	if level == "" {
+		delete(next, module)
+	} else {
+		next[module] = l
+	}
+	moduleLevels.Store(&next)
+	return nil
+}
+
+// ModuleLevels 当前显式配置的模块级别
+func ModuleLevels() map[string]string {
+	result := map[string]string{}
+	if levels := moduleLevels.Load(); levels != nil {
+		for k, v := range *levels {
+			result[k] = v.String()
+		}
+	}
+	return result
+}
+
+// moduleEnabler 模块级别判断（动态读取，调整级别无需重建日志器）
+type moduleEnabler struct {
+	name string
+}
+
+func (e moduleEnabler) Enabled(l zapcore.Level) bool {
+	if levels := moduleLevels.Load(); levels != nil {
+		for name := e.name; name != ""; {
+			if lvl, ok := (*levels)[name]; ok {
+				return l >= lvl
+			}
+			i := strings.LastIndex(name, ".")
+			if i < 0 {
+				break
+			}
+			name = name[:i]
+		}
+	}
+	if st := current.Load(); st != nil {
+		return st.level.Enabled(l)
+	}
+	return l >= zapcore.InfoLevel
+}
+
+// Module 模块日志器 ✨
+// 级别可按模块单独配置，Debug/Info 可按配置采样，每条日志自动附带 ctx 中的关联字段
+type Module struct {
+	name  string
+	built atomic.Pointer[moduleLogger]
+}
+
+type moduleLogger struct {
+	state  *state
+	logger *zap.Logger
+}
+
+// Named 获取（或创建）模块日志器，可作为包级变量使用
+func Named(name string) *Module {
+	if m, ok := moduleCache.Load(name); ok {
+		return m.(*Module)
+	}
+	m, _ := moduleCache.LoadOrStore(name, &Module{name: name})
+	return m.(*Module)
+}
+
+// Name 模块名称
+func (m *Module) Name() string {
+	return m.name
+}
+
+// base 返回当前 Init 状态下的模块日志器（重新 Init 后自动重建）
+func (m *Module) base() *zap.Logger {
+	st := current.Load()
+	if st == nil {
+		return zap.NewNop()
+	}
+	if built := m.built.Load(); built != nil && built.state == st {
+		return built.logger
+	}
+
+	var core zapcore.Core = &levelCore{Core: st.core, level: moduleEnabler{name: m.name}}
+	if st.sampling.Enabled {
+		core = newSampledCore(core, st.sampling)
+	}
+	l := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1), zap.AddStacktrace(zapcore.ErrorLevel)).Named(m.name)
+	m.built.Store(&moduleLogger{state: st, logger: l})
+	return l
+}
+
+// Enabled 模块是否输出该级别（用于跳过昂贵的字段构造）
+func (m *Module) Enabled(level zapcore.Level) bool {
+	return moduleEnabler{name: m.name}.Enabled(level)
+}
+
+// Ctx 返回携带请求关联字段的模块日志实例
+func (m *Module) Ctx(ctx context.Context) *zap.Logger {
+	fields := ContextFields(ctx)
+	if len(fields) == 0 {
+		return m.base().WithOptions(zap.AddCallerSkip(-1))
+	}
+	return m.base().WithOptions(zap.AddCallerSkip(-1)).With(fields...)
+}
+
+// Debug 调试日志
+func (m *Module) Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	if !m.Enabled(zapcore.DebugLevel) {
+		return
+	}
+	m.base().Debug(msg, append(ContextFields(ctx), fields...)...)
+}
+
+// Info 信息日志
+func (m *Module) Info(ctx context.Context, msg string, fields ...zap.Field) {
+	if !m.Enabled(zapcore.InfoLevel) {
+		return
+	}
+	m.base().Info(msg, append(ContextFields(ctx), fields...)...)
+}
+
+// Warn 警告日志
+func (m *Module) Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	m.base().Warn(msg, append(ContextFields(ctx), fields...)...)
+}
+
+// Error 错误日志
+func (m *Module) Error(ctx context.Context, msg string, fields ...zap.Field) {
+	m.base().Error(msg, append(ContextFields(ctx), fields...)...)
+}
+
+// ==================== zapcore 包装 ====================
+
+// levelCore 在底层核心之上按 LevelEnabler 过滤
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l) && c.Core.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// sampledCore Debug/Info 走采样，Warn 及以上直通
+type sampledCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func newSampledCore(core zapcore.Core, cfg SamplingConfig) zapcore.Core {
+	tick := cfg.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+	initial := cfg.Initial
+	if initial <= 0 {
+		initial = 10
+	}
+	thereafter := cfg.Thereafter
+	if thereafter <= 0 {
+		thereafter = 100
+	}
+	return &sampledCore{
+		Core:    core,
+		sampled: zapcore.NewSamplerWithOptions(core, tick, initial, thereafter),
+	}
+}
+
+func (c *sampledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &sampledCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields)}
+}
+
+func (c *sampledCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < zapcore.WarnLevel {
+		return c.sampled.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
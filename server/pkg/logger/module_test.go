@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+)
+
+func useObserver(t *testing.T, level zapcore.Level, sampling SamplingConfig) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	prev := current.Load()
+	prevLevels := moduleLevels.Load()
+	current.Store(&state{core: core, level: zap.NewAtomicLevelAt(level), sampling: sampling})
+	t.Cleanup(func() {
+		current.Store(prev)
+		moduleLevels.Store(prevLevels)
+	})
+	return logs
+}
+
+func TestModuleLevelInheritance(t *testing.T) {
+	logs := useObserver(t, zapcore.InfoLevel, SamplingConfig{})
+	if err := SetModuleLevel("repository", "debug"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	Named("repository.field").Debug(ctx, "field debug")
+	Named("application.record").Debug(ctx, "record debug")
+
+	if logs.Len() != 1 || logs.All()[0].Message != "field debug" {
+		t.Fatalf("期望仅 repository.* 输出 debug，实际 %v", logs.All())
+	}
+
+	if err := SetModuleLevel("repository", ""); err != nil {
+		t.Fatal(err)
+	}
+	Named("repository.field").Debug(ctx, "field debug")
+	if logs.Len() != 1 {
+		t.Fatalf("恢复跟随全局级别后不应输出 debug")
+	}
+}
+
+func TestModuleContextFields(t *testing.T) {
+	logs := useObserver(t, zapcore.InfoLevel, SamplingConfig{})
+
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	ctx = authctx.WithUser(ctx, "usr_1")
+	Named("test.ctx").Info(ctx, "hello", String("k", "v"))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["user_id"] != "usr_1" || fields["k"] != "v" {
+		t.Fatalf("关联字段缺失: %v", fields)
+	}
+}
+
+func TestModuleSamplingSkipsWarnings(t *testing.T) {
+	logs := useObserver(t, zapcore.DebugLevel, SamplingConfig{Enabled: true, Initial: 2, Thereafter: 1000, Tick: time.Minute})
+
+	m := Named("test.sampling")
+	ctx := context.Background()
+	for i := 0; i < 50; i++ {
+		m.Debug(ctx, "hot path")
+		m.Warn(ctx, "warning")
+	}
+
+	debug, warn := 0, 0
+	for _, entry := range logs.All() {
+		switch entry.Level {
+		case zapcore.DebugLevel:
+			debug++
+		case zapcore.WarnLevel:
+			warn++
+		}
+	}
+	if debug != 2 {
+		t.Fatalf("热点 debug 日志应被采样为 2 条，实际 %d", debug)
+	}
+	if warn != 50 {
+		t.Fatalf("warn 日志不应采样，实际 %d", warn)
+	}
+}
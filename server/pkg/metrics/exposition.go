@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+	// scrapeTimeout 抓取时计算指标（如数据库查询）的超时
+	scrapeTimeout = 5 * time.Second
+)
+
+// Handler 指标抓取端点
+// Accept 包含 application/openmetrics-text 时输出 OpenMetrics 格式（含 exemplar），否则输出 Prometheus 文本格式
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		openMetrics := strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text")
+		if openMetrics {
+			w.Header().Set("Content-Type", contentTypeOpenMetrics)
+		} else {
+			w.Header().Set("Content-Type", contentTypeText)
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), scrapeTimeout)
+		defer cancel()
+		_ = r.Write(ctx, w, openMetrics)
+	})
+}
+
+// Handler 全局注册表的抓取端点
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// Write 输出全部指标
+func (r *Registry) Write(ctx context.Context, out io.Writer, openMetrics bool) error {
+	w := bufio.NewWriter(out)
+	for _, c := range r.sorted() {
+		writeFamily(w, c.Desc(), c.collect(ctx), openMetrics)
+	}
+	if openMetrics {
+		w.WriteString("# EOF\n")
+	}
+	return w.Flush()
+}
+
+func writeFamily(w *bufio.Writer, desc Desc, samples []series, openMetrics bool) {
+	familyName := desc.Name
+	if openMetrics && desc.Type == TypeCounter {
+		// OpenMetrics 中计数器族名不含 _total 后缀，样本名含
+		familyName = strings.TrimSuffix(familyName, "_total")
+	}
+	w.WriteString("# HELP " + familyName + " " + escapeHelp(desc.Help) + "\n")
+	w.WriteString("# TYPE " + familyName + " " + string(desc.Type) + "\n")
+
+	for _, s := range samples {
+		switch desc.Type {
+		case TypeHistogram:
+			for i, upper := range s.buckets {
+				writeSample(w, desc.Name+"_bucket", desc.LabelNames, s.labelValues, "le", formatFloat(upper), float64(s.counts[i]))
+				writeExemplar(w, s.exemplars[i], openMetrics)
+			}
+			writeSample(w, desc.Name+"_bucket", desc.LabelNames, s.labelValues, "le", "+Inf", float64(s.count))
+			writeExemplar(w, s.exemplars[len(s.buckets)], openMetrics)
+			writeSample(w, desc.Name+"_sum", desc.LabelNames, s.labelValues, "", "", s.sum)
+			w.WriteByte('\n')
+			writeSample(w, desc.Name+"_count", desc.LabelNames, s.labelValues, "", "", float64(s.count))
+			w.WriteByte('\n')
+		default:
+			writeSample(w, desc.Name, desc.LabelNames, s.labelValues, "", "", s.value)
+			w.WriteByte('\n')
+		}
+	}
+}
+
+// writeSample 输出一行样本（不含换行，便于追加 exemplar）
+func writeSample(w *bufio.Writer, name string, labelNames, labelValues []string, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labelNames) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, label := range labelNames {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escapeLabel(labelValues[i]) + `"`)
+		}
+		if extraName != "" {
+			if len(labelNames) > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(extraName + `="` + extraValue + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+}
+
+// writeExemplar 结束分桶行；OpenMetrics 下附带 exemplar：`# {trace_id="..."} 0.12 1700000000.123`
+func writeExemplar(w *bufio.Writer, ex *Exemplar, openMetrics bool) {
+	if openMetrics && ex != nil {
+		keys := make([]string, 0, len(ex.Labels))
+		for k := range ex.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		w.WriteString(" # {")
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(k + `="` + escapeLabel(ex.Labels[k]) + `"`)
+		}
+		w.WriteString("} " + formatFloat(ex.Value) + " ")
+		w.WriteString(strconv.FormatFloat(float64(ex.Timestamp.UnixMilli())/1000, 'f', 3, 64))
+	}
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
+
+func escapeHelp(v string) string { return helpEscaper.Replace(v) }
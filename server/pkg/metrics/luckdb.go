@@ -0,0 +1,63 @@
+package metrics
+
+// LuckDB 标准指标（命名遵循 Prometheus 约定：luckdb_<子系统>_<名称>_<单位>）
+// 抓取时计算的指标（连接池、队列积压、WebSocket 连接数等）由容器在初始化时注册，见 container.registerMetrics
+var (
+	// HTTP
+	HTTPRequestsTotal = NewCounterVec("luckdb_http_requests_total",
+		"HTTP 请求总数", "method", "route", "status")
+	HTTPRequestDuration = NewHistogramVec("luckdb_http_request_duration_seconds",
+		"HTTP 请求耗时（按路由模板）", DefBuckets, "method", "route")
+	HTTPRequestsInFlight = NewGaugeVec("luckdb_http_requests_in_flight",
+		"正在处理的 HTTP 请求数")
+
+	// 数据库
+	DBQueryDuration = NewHistogramVec("luckdb_db_query_duration_seconds",
+		"SQL 执行耗时", []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}, "operation")
+	DBQueryErrorsTotal = NewCounterVec("luckdb_db_query_errors_total",
+		"SQL 执行失败次数（不含记录不存在）", "operation")
+	DBQueryBudgetRejectionsTotal = NewCounterVec("luckdb_db_query_budget_rejections_total",
+		"超出请求类别查询预算而被拒绝或中止的查询数（limit=statement_timeout/rows_scanned/sort_rows）", "class", "limit")
+
+	// 缓存
+	CacheRequestsTotal = NewCounterVec("luckdb_cache_requests_total",
+		"缓存读取次数（命中按 layer=local/redis 区分，未命中 layer=all，Redis 不可用时 result=error）", "layer", "result")
+	CacheCircuitState = NewGaugeVec("luckdb_cache_circuit_state",
+		"仓储缓存熔断器状态（0=closed, 1=half_open, 2=open）")
+	CacheCircuitTransitionsTotal = NewCounterVec("luckdb_cache_circuit_transitions_total",
+		"仓储缓存熔断器状态切换次数", "state")
+	CacheCircuitShortCircuitsTotal = NewCounterVec("luckdb_cache_circuit_short_circuits_total",
+		"熔断期间跳过的缓存操作次数", "operation")
+	CacheStaleEntriesTotal = NewCounterVec("luckdb_cache_stale_entries_total",
+		"按未命中处理的过期缓存条目数（reason=schema 结构版本不一致，format 信封无法识别）", "reason")
+	ViewQueryCacheRequestsTotal = NewCounterVec("luckdb_view_query_cache_requests_total",
+		"视图查询结果缓存读取次数（result=hit/miss/stale，stale 为依赖已失效）", "result")
+	ViewQueryCacheInvalidationsTotal = NewCounterVec("luckdb_view_query_cache_invalidations_total",
+		"视图查询结果缓存按依赖失效的次数", "dependency")
+
+	// 后台任务
+	JobsProcessedTotal = NewCounterVec("luckdb_jobs_processed_total",
+		"后台任务处理次数", "queue", "result")
+	JobDuration = NewHistogramVec("luckdb_job_duration_seconds",
+		"后台任务执行耗时", []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300}, "queue")
+)
+
+func init() {
+	Default.MustRegister(
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		DBQueryDuration,
+		DBQueryErrorsTotal,
+		DBQueryBudgetRejectionsTotal,
+		CacheRequestsTotal,
+		CacheCircuitState,
+		CacheCircuitTransitionsTotal,
+		CacheCircuitShortCircuitsTotal,
+		CacheStaleEntriesTotal,
+		ViewQueryCacheRequestsTotal,
+		ViewQueryCacheInvalidationsTotal,
+		JobsProcessedTotal,
+		JobDuration,
+	)
+}
@@ -0,0 +1,414 @@
+// Package metrics Prometheus 指标
+//
+// 提供计数器、仪表盘、直方图（支持标签与 OpenMetrics exemplar）以及抓取时计算的指标，
+// 通过 Handler 以 Prometheus 文本格式（或 OpenMetrics 格式，含指向链路的 exemplar）暴露。
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricType 指标类型
+type MetricType string
+
+const (
+	TypeCounter   MetricType = "counter"
+	TypeGauge     MetricType = "gauge"
+	TypeHistogram MetricType = "histogram"
+)
+
+// DefBuckets 默认延迟分桶（秒）
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector 可被注册表收集的指标族
+type Collector interface {
+	Desc() Desc
+	collect(ctx context.Context) []series
+}
+
+// Desc 指标族描述
+type Desc struct {
+	Name       string
+	Help       string
+	Type       MetricType
+	LabelNames []string
+}
+
+// Exemplar 关联到样本的示例（如 trace_id），用于从指标跳转到链路
+type Exemplar struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// series 一组标签下的样本快照
+type series struct {
+	labelValues []string
+
+	value float64 // counter / gauge
+
+	// histogram
+	buckets   []float64
+	counts    []uint64 // 累计值
+	sum       float64
+	count     uint64
+	exemplars []*Exemplar // 与 buckets 对齐，最后一个为 +Inf
+}
+
+// ==================== 注册表 ====================
+
+// Registry 指标注册表
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]Collector
+}
+
+// NewRegistry 创建注册表
+func NewRegistry() *Registry {
+	return &Registry{collectors: map[string]Collector{}}
+}
+
+// Default 全局注册表
+var Default = NewRegistry()
+
+// Register 注册指标族（同名重复注册返回错误）
+func (r *Registry) Register(c Collector) error {
+	desc := c.Desc()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.collectors[desc.Name]; exists {
+		return fmt.Errorf("指标 %s 已注册", desc.Name)
+	}
+	r.collectors[desc.Name] = c
+	return nil
+}
+
+// MustRegister 注册指标族，失败时 panic（用于包初始化）
+func (r *Registry) MustRegister(collectors ...Collector) {
+	for _, c := range collectors {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Unregister 注销指标族
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	delete(r.collectors, name)
+	r.mu.Unlock()
+}
+
+// sorted 按名称排序的指标族（输出稳定）
+func (r *Registry) sorted() []Collector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]Collector, 0, len(names))
+	for _, name := range names {
+		result = append(result, r.collectors[name])
+	}
+	return result
+}
+
+// ==================== 带标签的指标基础 ====================
+
+type vec[T any] struct {
+	desc    Desc
+	mu      sync.RWMutex
+	metrics map[string]*labeled[T]
+	newFn   func() *T
+}
+
+type labeled[T any] struct {
+	values []string
+	metric *T
+}
+
+func newVec[T any](desc Desc, newFn func() *T) vec[T] {
+	return vec[T]{desc: desc, metrics: map[string]*labeled[T]{}, newFn: newFn}
+}
+
+func (v *vec[T]) with(values []string) *T {
+	if len(values) != len(v.desc.LabelNames) {
+		panic(fmt.Sprintf("指标 %s 需要 %d 个标签值，实际 %d", v.desc.Name, len(v.desc.LabelNames), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	m, ok := v.metrics[key]
+	v.mu.RUnlock()
+	if ok {
+		return m.metric
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if m, ok := v.metrics[key]; ok {
+		return m.metric
+	}
+	m = &labeled[T]{values: append([]string(nil), values...), metric: v.newFn()}
+	v.metrics[key] = m
+	return m.metric
+}
+
+func (v *vec[T]) each(fn func(values []string, metric *T)) {
+	v.mu.RLock()
+	items := make([]*labeled[T], 0, len(v.metrics))
+	for _, m := range v.metrics {
+		items = append(items, m)
+	}
+	v.mu.RUnlock()
+
+	sort.Slice(items, func(i, j int) bool {
+		return strings.Join(items[i].values, "\xff") < strings.Join(items[j].values, "\xff")
+	})
+	for _, m := range items {
+		fn(m.values, m.metric)
+	}
+}
+
+// atomicFloat 原子浮点数
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Add(delta float64) {
+	for {
+		old := f.bits.Load()
+		next := math.Float64bits(math.Float64frombits(old) + delta)
+		if f.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+func (f *atomicFloat) Set(v float64) { f.bits.Store(math.Float64bits(v)) }
+
+func (f *atomicFloat) Load() float64 { return math.Float64frombits(f.bits.Load()) }
+
+// ==================== Counter ====================
+
+// Counter 单调递增计数器
+type Counter struct {
+	value atomicFloat
+}
+
+// Inc 加一
+func (c *Counter) Inc() { c.value.Add(1) }
+
+// Add 增加（负值被忽略）
+func (c *Counter) Add(v float64) {
+	if v > 0 {
+		c.value.Add(v)
+	}
+}
+
+// Value 当前值
+func (c *Counter) Value() float64 { return c.value.Load() }
+
+// CounterVec 带标签的计数器族（名称应以 _total 结尾）
+type CounterVec struct {
+	vec[Counter]
+}
+
+// NewCounterVec 创建计数器族
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(Desc{Name: name, Help: help, Type: TypeCounter, LabelNames: labelNames},
+		func() *Counter { return &Counter{} })}
+}
+
+// WithLabelValues 获取指定标签值的计数器
+func (v *CounterVec) WithLabelValues(values ...string) *Counter { return v.with(values) }
+
+// Desc 指标族描述
+func (v *CounterVec) Desc() Desc { return v.desc }
+
+func (v *CounterVec) collect(context.Context) []series {
+	var out []series
+	v.each(func(values []string, c *Counter) {
+		out = append(out, series{labelValues: values, value: c.Value()})
+	})
+	return out
+}
+
+// ==================== Gauge ====================
+
+// Gauge 可增可减的仪表盘
+type Gauge struct {
+	value atomicFloat
+}
+
+// Set 设置值
+func (g *Gauge) Set(v float64) { g.value.Set(v) }
+
+// Inc 加一
+func (g *Gauge) Inc() { g.value.Add(1) }
+
+// Dec 减一
+func (g *Gauge) Dec() { g.value.Add(-1) }
+
+// Add 增减
+func (g *Gauge) Add(v float64) { g.value.Add(v) }
+
+// Value 当前值
+func (g *Gauge) Value() float64 { return g.value.Load() }
+
+// GaugeVec 带标签的仪表盘族
+type GaugeVec struct {
+	vec[Gauge]
+}
+
+// NewGaugeVec 创建仪表盘族
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(Desc{Name: name, Help: help, Type: TypeGauge, LabelNames: labelNames},
+		func() *Gauge { return &Gauge{} })}
+}
+
+// WithLabelValues 获取指定标签值的仪表盘
+func (v *GaugeVec) WithLabelValues(values ...string) *Gauge { return v.with(values) }
+
+// Desc 指标族描述
+func (v *GaugeVec) Desc() Desc { return v.desc }
+
+func (v *GaugeVec) collect(context.Context) []series {
+	var out []series
+	v.each(func(values []string, g *Gauge) {
+		out = append(out, series{labelValues: values, value: g.Value()})
+	})
+	return out
+}
+
+// ==================== GaugeFunc ====================
+
+// Sample 抓取时计算的样本
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// GaugeFunc 抓取时计算的仪表盘族（如连接池状态、队列积压）
+type GaugeFunc struct {
+	desc Desc
+	fn   func(ctx context.Context) []Sample
+}
+
+// NewGaugeFunc 创建抓取时计算的仪表盘族
+func NewGaugeFunc(name, help string, labelNames []string, fn func(ctx context.Context) []Sample) *GaugeFunc {
+	return &GaugeFunc{desc: Desc{Name: name, Help: help, Type: TypeGauge, LabelNames: labelNames}, fn: fn}
+}
+
+// Desc 指标族描述
+func (g *GaugeFunc) Desc() Desc { return g.desc }
+
+func (g *GaugeFunc) collect(ctx context.Context) []series {
+	samples := g.fn(ctx)
+	out := make([]series, 0, len(samples))
+	for _, s := range samples {
+		if len(s.LabelValues) != len(g.desc.LabelNames) {
+			continue
+		}
+		out = append(out, series{labelValues: s.LabelValues, value: s.Value})
+	}
+	return out
+}
+
+// ==================== Histogram ====================
+
+// Histogram 直方图
+type Histogram struct {
+	mu        sync.Mutex
+	buckets   []float64
+	counts    []uint64 // 非累计，最后一个为 +Inf
+	sum       float64
+	count     uint64
+	exemplars []*Exemplar
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets:   buckets,
+		counts:    make([]uint64, len(buckets)+1),
+		exemplars: make([]*Exemplar, len(buckets)+1),
+	}
+}
+
+// Observe 记录观测值
+func (h *Histogram) Observe(v float64) {
+	h.ObserveWithExemplar(v, nil)
+}
+
+// ObserveWithExemplar 记录观测值并在对应分桶上附带 exemplar（如 {"trace_id": "..."}）
+func (h *Histogram) ObserveWithExemplar(v float64, labels map[string]string) {
+	i := sort.SearchFloat64s(h.buckets, v)
+
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += v
+	h.count++
+	if len(labels) > 0 {
+		h.exemplars[i] = &Exemplar{Labels: labels, Value: v, Timestamp: time.Now()}
+	}
+	h.mu.Unlock()
+}
+
+func (h *Histogram) snapshot(values []string) series {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := series{
+		labelValues: values,
+		buckets:     h.buckets,
+		counts:      make([]uint64, len(h.counts)),
+		sum:         h.sum,
+		count:       h.count,
+		exemplars:   append([]*Exemplar(nil), h.exemplars...),
+	}
+	var cumulative uint64
+	for i, c := range h.counts {
+		cumulative += c
+		s.counts[i] = cumulative
+	}
+	return s
+}
+
+// HistogramVec 带标签的直方图族
+type HistogramVec struct {
+	vec[Histogram]
+}
+
+// NewHistogramVec 创建直方图族（buckets 为空时使用 DefBuckets）
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &HistogramVec{newVec(Desc{Name: name, Help: help, Type: TypeHistogram, LabelNames: labelNames},
+		func() *Histogram { return newHistogram(buckets) })}
+}
+
+// WithLabelValues 获取指定标签值的直方图
+func (v *HistogramVec) WithLabelValues(values ...string) *Histogram { return v.with(values) }
+
+// Desc 指标族描述
+func (v *HistogramVec) Desc() Desc { return v.desc }
+
+func (v *HistogramVec) collect(context.Context) []series {
+	var out []series
+	v.each(func(values []string, h *Histogram) {
+		out = append(out, h.snapshot(values))
+	})
+	return out
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTextExposition(t *testing.T) {
+	reg := NewRegistry()
+	requests := NewCounterVec("test_requests_total", "请求数", "route")
+	latency := NewHistogramVec("test_latency_seconds", "耗时", []float64{0.1, 1}, "route")
+	reg.MustRegister(requests, latency)
+
+	requests.WithLabelValues(`/a"b`).Add(2)
+	latency.WithLabelValues("/a").ObserveWithExemplar(0.05, map[string]string{"trace_id": "abc"})
+	latency.WithLabelValues("/a").Observe(5)
+
+	var buf bytes.Buffer
+	if err := reg.Write(context.Background(), &buf, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE test_requests_total counter\n",
+		`test_requests_total{route="/a\"b"} 2` + "\n",
+		`test_latency_seconds_bucket{route="/a",le="0.1"} 1` + "\n",
+		`test_latency_seconds_bucket{route="/a",le="1"} 1` + "\n",
+		`test_latency_seconds_bucket{route="/a",le="+Inf"} 2` + "\n",
+		`test_latency_seconds_count{route="/a"} 2` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("输出缺少 %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "trace_id") || strings.Contains(out, "# EOF") {
+		t.Errorf("Prometheus 文本格式不应包含 exemplar 或 EOF\n%s", out)
+	}
+}
+
+func TestOpenMetricsExemplar(t *testing.T) {
+	reg := NewRegistry()
+	requests := NewCounterVec("test_requests_total", "请求数")
+	latency := NewHistogramVec("test_latency_seconds", "耗时", []float64{0.1}, "route")
+	reg.MustRegister(requests, latency)
+	requests.WithLabelValues().Inc()
+	latency.WithLabelValues("/a").ObserveWithExemplar(0.05, map[string]string{"trace_id": "abc"})
+
+	var buf bytes.Buffer
+	if err := reg.Write(context.Background(), &buf, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "# TYPE test_requests counter\n") {
+		t.Errorf("OpenMetrics 计数器族名应去掉 _total\n%s", out)
+	}
+	if !strings.Contains(out, `test_latency_seconds_bucket{route="/a",le="0.1"} 1 # {trace_id="abc"} 0.05 `) {
+		t.Errorf("分桶缺少 exemplar\n%s", out)
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("OpenMetrics 输出应以 # EOF 结尾")
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	reg := NewRegistry()
+	reg.MustRegister(NewGaugeFunc("test_queue_depth", "积压", []string{"queue"}, func(context.Context) []Sample {
+		return []Sample{{LabelValues: []string{"jobs"}, Value: 3}, {Value: 1}}
+	}))
+
+	var buf bytes.Buffer
+	_ = reg.Write(context.Background(), &buf, false)
+	out := buf.String()
+	if !strings.Contains(out, `test_queue_depth{queue="jobs"} 3`) {
+		t.Errorf("缺少抓取时样本\n%s", out)
+	}
+	if strings.Contains(out, "test_queue_depth 1") {
+		t.Errorf("标签数量不符的样本应被丢弃\n%s", out)
+	}
+	if err := reg.Register(NewGaugeFunc("test_queue_depth", "", nil, nil)); err == nil {
+		t.Errorf("重复注册应返回错误")
+	}
+}
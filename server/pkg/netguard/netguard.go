@@ -0,0 +1,76 @@
+// Package netguard 出站连接的内网地址保护
+//
+// 用户可配置目标地址的出站请求（脚本 fetch、REST Hook、复制推送、外部数据源等）
+// 在建立连接时检查解析后的地址，拒绝回环、内网、链路本地等地址。
+// 在连接时检查（而不是保存地址时解析一次）可以防止 DNS 重新绑定，重定向同样受限。
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress 访问了被禁止的内网地址
+var ErrPrivateAddress = errors.New("不允许访问内网地址")
+
+// IsPublicIP 是否为公网地址
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// Control net.Dialer.Control：连接前检查解析后的地址，非公网地址返回 ErrPrivateAddress
+func Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// NewDialer 创建出站连接的 Dialer（allowPrivate 为 true 时不做限制）
+func NewDialer(timeout time.Duration, allowPrivate bool) *net.Dialer {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = Control
+	}
+	return dialer
+}
+
+// NewHTTPClient 创建出站请求的 HTTP 客户端（不走环境变量代理，否则连接检查只作用于代理地址）
+func NewHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = NewDialer(timeout, allowPrivate).DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// CheckHost 保存配置时的提前检查：拒绝 localhost 与字面量的非公网 IP
+// 域名在连接时才解析，由 Control 兜底
+func CheckHost(host string) error {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// CheckURL 检查出站 URL 的主机（见 CheckHost）
+func CheckURL(u *url.URL) error {
+	return CheckHost(u.Hostname())
+}
@@ -0,0 +1,49 @@
+package netguard
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestControl(t *testing.T) {
+	for _, address := range []string{"127.0.0.1:80", "10.0.0.8:443", "192.168.1.1:5432", "169.254.169.254:80", "[::1]:80", "0.0.0.0:80"} {
+		if err := Control("tcp", address, nil); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("%s 应被拒绝，得到 %v", address, err)
+		}
+	}
+	if err := Control("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("公网地址应放行，得到 %v", err)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	for _, host := range []string{"localhost", "api.localhost", "127.0.0.1", "10.1.2.3:8080", "[::1]:80"} {
+		if err := CheckHost(host); !errors.Is(err, ErrPrivateAddress) {
+			t.Errorf("%s 应被拒绝，得到 %v", host, err)
+		}
+	}
+	for _, host := range []string{"example.com", "hooks.zapier.com:443", "93.184.216.34"} {
+		if err := CheckHost(host); err != nil {
+			t.Errorf("%s 应放行，得到 %v", host, err)
+		}
+	}
+}
+
+func TestHTTPClientRefusesLoopbackAtDialTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if _, err := NewHTTPClient(time.Second, false).Get(server.URL); !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("连接回环地址应被拒绝，得到 %v", err)
+	}
+	resp, err := NewHTTPClient(time.Second, true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("允许内网时应可以连接，得到 %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,120 @@
+// Package querybudget 按请求类别的查询预算 ✨
+// HTTP 中间件按路由为请求附加预算，数据库层在执行查询时按预算设置语句超时，
+// 并在大表上按执行计划估算扫描行数与排序行数，超出时返回带建议的"查询开销过大"错误
+package querybudget
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// Limit 被超出的预算项
+type Limit string
+
+const (
+	LimitStatementTimeout Limit = "statement_timeout" // 单条查询执行超时
+	LimitRowsScanned      Limit = "rows_scanned"      // 预计扫描行数超出上限
+	LimitSortRows         Limit = "sort_rows"         // 无法使用索引的排序行数超出上限
+)
+
+// 建议的处理方式
+const (
+	ActionAddFilter = "add_filter" // 增加过滤条件缩小范围
+	ActionAddIndex  = "add_index"  // 为过滤或排序的列建立索引
+)
+
+// Budget 一类请求的查询预算（各项为 0 时不限制）
+type Budget struct {
+	Class            string
+	StatementTimeout time.Duration // 单条查询的最长执行时间
+	MaxRowsScanned   int64         // 单条查询按执行计划估算的最大扫描行数
+	MaxSortRows      int64         // 无法使用索引、需要在数据库进程内排序的最大行数
+}
+
+// IsZero 预算是否没有任何限制
+func (b Budget) IsZero() bool {
+	return b.StatementTimeout <= 0 && b.MaxRowsScanned <= 0 && b.MaxSortRows <= 0
+}
+
+type budgetKey struct{}
+
+// WithBudget 为上下文附加查询预算
+func WithBudget(ctx context.Context, budget Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, budget)
+}
+
+// FromContext 读取上下文中的查询预算
+func FromContext(ctx context.Context) (Budget, bool) {
+	if ctx == nil {
+		return Budget{}, false
+	}
+	budget, ok := ctx.Value(budgetKey{}).(Budget)
+	return budget, ok
+}
+
+// Suggestion 降低查询开销的建议
+type Suggestion struct {
+	Action  string   `json:"action"`
+	Columns []string `json:"columns,omitempty"` // 建议建立索引的列（动态表的物理列名）
+	Message string   `json:"message"`
+}
+
+// ExceededError 查询超出预算
+type ExceededError struct {
+	Class       string       `json:"class"`
+	Limit       Limit        `json:"limit"`
+	Table       string       `json:"table,omitempty"`
+	Estimated   int64        `json:"estimated,omitempty"` // 估算的行数（超时时为 0）
+	Max         int64        `json:"max"`                 // 预算上限（超时时为毫秒数）
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+func (e *ExceededError) Error() string {
+	switch e.Limit {
+	case LimitStatementTimeout:
+		return fmt.Sprintf("查询超过 %s 类请求的执行时间上限 %dms", e.Class, e.Max)
+	case LimitSortRows:
+		return fmt.Sprintf("查询需要排序约 %d 行，超过 %s 类请求的上限 %d", e.Estimated, e.Class, e.Max)
+	default:
+		return fmt.Sprintf("查询预计扫描约 %d 行，超过 %s 类请求的上限 %d", e.Estimated, e.Class, e.Max)
+	}
+}
+
+// AppError 转换为应用错误（详情为预算项与建议，原始错误可通过 errors.As 取得）
+func (e *ExceededError) AppError() *pkgerrors.AppError {
+	return pkgerrors.ErrQueryTooExpensive.WithDetails(e).WithCause(e)
+}
+
+// Timeout 语句超时错误
+func Timeout(budget Budget, table string) *ExceededError {
+	return &ExceededError{
+		Class: budget.Class,
+		Limit: LimitStatementTimeout,
+		Table: table,
+		Max:   budget.StatementTimeout.Milliseconds(),
+		Suggestions: []Suggestion{
+			{Action: ActionAddFilter, Message: "增加过滤条件或减少排序、分组字段，缩小查询范围"},
+		},
+	}
+}
+
+// TooManyRows 扫描或排序行数超出预算的错误，columns 为参与过滤或排序、建议建立索引的列
+func TooManyRows(budget Budget, limit Limit, table string, estimated int64, columns []string) *ExceededError {
+	e := &ExceededError{Class: budget.Class, Limit: limit, Table: table, Estimated: estimated, Max: budget.MaxRowsScanned}
+	if limit == LimitSortRows {
+		e.Max = budget.MaxSortRows
+	}
+	if len(columns) > 0 {
+		message := "为过滤条件中的列建立索引，避免全表扫描：" + strings.Join(columns, ", ")
+		if limit == LimitSortRows {
+			message = "为排序列建立索引，使数据库可以按索引顺序读取：" + strings.Join(columns, ", ")
+		}
+		e.Suggestions = append(e.Suggestions, Suggestion{Action: ActionAddIndex, Columns: columns, Message: message})
+	}
+	e.Suggestions = append(e.Suggestions, Suggestion{Action: ActionAddFilter, Message: "增加选择性更高的过滤条件，缩小需要读取的记录范围"})
+	return e
+}
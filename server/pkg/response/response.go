@@ -32,8 +32,12 @@ type APIResponse struct {
 }
 
 // ErrorPayload 错误详情载荷（V2）
+// Code/Category/Retryable 供客户端与自动化重试逻辑按错误目录程序化处理
 type ErrorPayload struct {
-	Details interface{} `json:"details,omitempty"`
+	Code      string      `json:"code,omitempty"`
+	Category  string      `json:"category,omitempty"`
+	Retryable bool        `json:"retryable"`
+	Details   interface{} `json:"details,omitempty"`
 }
 
 // metaFromContext 提取元信息
@@ -85,13 +89,17 @@ func Error(c *gin.Context, err error) {
 	httpStatus := http.StatusInternalServerError
 	code := errors.CodeInternalError
 	message := "服务器内部错误"
-	var details interface{}
+	payload := &ErrorPayload{}
 
-	if appErr, ok := err.(*errors.AppError); ok {
+	// 统一归一化为目录中的错误（未识别的错误不暴露原始信息）
+	if appErr := errors.From(err); appErr != nil {
 		httpStatus = appErr.HTTPStatus
-		code = errors.NumericCodeFromString(appErr.Code, appErr.HTTPStatus)
+		code = appErr.NumericCode()
 		message = appErr.Message
-		details = appErr.Details
+		payload.Code = appErr.Code
+		payload.Category = string(appErr.Category)
+		payload.Retryable = appErr.Retryable
+		payload.Details = appErr.Details
 	}
 
 	// 确保响应头已设置
@@ -110,12 +118,10 @@ func Error(c *gin.Context, err error) {
 	}()
 
 	c.JSON(httpStatus, APIResponse{
-		Code:    code,
-		Message: message,
-		Data:    nil,
-		Error: &ErrorPayload{
-			Details: details,
-		},
+		Code:       code,
+		Message:    message,
+		Data:       nil,
+		Error:      payload,
 		RequestID:  reqID,
 		Timestamp:  ts,
 		DurationMs: dur,
@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCursorExpired 游标之后的变更已超出服务端保留期，需要全量同步后从 GetChangeHead 返回的游标继续
+var ErrCursorExpired = errors.New("luckdb: change feed cursor expired")
+
+// TailOptions 变更流订阅选项
+type TailOptions struct {
+	TableID      string        // 只订阅指定表
+	BatchSize    int           // 每次拉取的变更数
+	PollInterval time.Duration // 没有新变更时的轮询间隔
+}
+
+// TailChanges 从 cursor 之后持续拉取变更并依次交给 handle，直到 ctx 取消或 handle 返回错误
+//
+// handle 返回 nil 后才会推进游标；调用方应持久化 change.Seq，重启时从该值继续。
+// 游标过期时返回 ErrCursorExpired
+func (c *Client) TailChanges(ctx context.Context, baseID string, cursor int64, opts TailOptions, handle func(*Change) error) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	for {
+		page, err := c.ListChanges(ctx, baseID, &ListChangesParams{Cursor: cursor, Limit: opts.BatchSize, TableID: opts.TableID})
+		if err != nil {
+			if IsConflict(err) {
+				return ErrCursorExpired
+			}
+			return err
+		}
+		for _, change := range page.Changes {
+			if err := handle(change); err != nil {
+				return err
+			}
+			cursor = change.Seq
+		}
+		if page.HasMore {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiPrefix 所有接口的路径前缀
+const apiPrefix = "/api/v1"
+
+// Client LuckDB API 客户端（可并发使用）
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	retry      RetryPolicy
+
+	mu           sync.RWMutex
+	accessToken  string
+	refreshToken string
+}
+
+// RetryPolicy 重试策略
+// 429 与 503 按 Retry-After 等待；其余可重试错误（网络错误、服务端标记为 retryable 的错误）按指数退避。
+// 写请求自动携带 Idempotency-Key，重试不会重复写入
+type RetryPolicy struct {
+	MaxRetries int           // 最大重试次数，0 表示不重试
+	BaseDelay  time.Duration // 首次退避时长
+	MaxDelay   time.Duration // 单次等待上限（同样限制 Retry-After）
+}
+
+// DefaultRetryPolicy 默认重试策略
+var DefaultRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+// Option 客户端选项
+type Option func(*Client)
+
+// WithToken 使用访问令牌（登录令牌或服务令牌）
+func WithToken(token string) Option {
+	return func(c *Client) { c.accessToken = token }
+}
+
+// WithRefreshToken 设置刷新令牌，访问令牌过期时自动刷新一次
+func WithRefreshToken(token string) Option {
+	return func(c *Client) { c.refreshToken = token }
+}
+
+// WithHTTPClient 使用自定义 HTTP 客户端
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithRetryPolicy 使用自定义重试策略
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// WithUserAgent 设置 User-Agent
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New 创建客户端，baseURL 为服务地址（不含 /api/v1）
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		userAgent:  "luckdb-go-sdk",
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SignIn 使用邮箱和密码登录，之后的请求自动携带访问令牌
+func (c *Client) SignIn(ctx context.Context, email, password string) (*LoginResponse, error) {
+	resp, err := c.Login(ctx, &LoginRequest{Email: email, Password: password})
+	if err != nil {
+		return nil, err
+	}
+	c.SetTokens(resp.AccessToken, resp.RefreshToken)
+	return resp, nil
+}
+
+// SetTokens 设置访问令牌与刷新令牌
+func (c *Client) SetTokens(accessToken, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = accessToken
+	c.refreshToken = refreshToken
+}
+
+// Tokens 当前的访问令牌与刷新令牌（自动刷新后可用于持久化）
+func (c *Client) Tokens() (accessToken, refreshToken string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessToken, c.refreshToken
+}
+
+// request 一次接口调用
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   interface{}
+	auth   bool
+}
+
+// envelope 统一响应结构
+type envelope struct {
+	Code      int             `json:"code"`
+	Message   string          `json:"message"`
+	Data      json.RawMessage `json:"data"`
+	Error     *errorPayload   `json:"error"`
+	RequestID string          `json:"request_id"`
+}
+
+type errorPayload struct {
+	Code      string      `json:"code"`
+	Category  string      `json:"category"`
+	Retryable bool        `json:"retryable"`
+	Details   interface{} `json:"details"`
+}
+
+// do 发送请求并将 data 解码到 out，按重试策略处理可重试错误
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	var payload []byte
+	if req.body != nil {
+		var err error
+		if payload, err = json.Marshal(req.body); err != nil {
+			return fmt.Errorf("luckdb: encode request: %w", err)
+		}
+	}
+	idempotencyKey := ""
+	if req.method == http.MethodPost || req.method == http.MethodPatch {
+		idempotencyKey = newIdempotencyKey()
+	}
+
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, req, payload, idempotencyKey, out)
+		if err == nil {
+			return nil
+		}
+
+		apiErr, isAPIErr := err.(*APIError)
+		if isAPIErr && apiErr.StatusCode == http.StatusUnauthorized && req.auth && !refreshed && c.canRefresh() {
+			refreshed = true
+			if refreshErr := c.refresh(ctx); refreshErr == nil {
+				attempt--
+				continue
+			}
+			return err
+		}
+
+		if attempt >= c.retry.MaxRetries || !retryable(err) {
+			return err
+		}
+		wait := c.backoff(attempt)
+		if isAPIErr && apiErr.RetryAfter > 0 {
+			wait = apiErr.RetryAfter
+			if c.retry.MaxDelay > 0 && wait > c.retry.MaxDelay {
+				wait = c.retry.MaxDelay
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send 发送一次请求
+func (c *Client) send(ctx context.Context, req request, payload []byte, idempotencyKey string, out interface{}) error {
+	target := c.baseURL + apiPrefix + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return fmt.Errorf("luckdb: build request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	if req.auth {
+		if token, _ := c.Tokens(); token != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &networkError{err: err}
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &networkError{err: err}
+	}
+
+	var env envelope
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &env); err != nil && resp.StatusCode < 300 {
+			return fmt.Errorf("luckdb: decode response: %w", err)
+		}
+	}
+	if resp.StatusCode >= 300 {
+		return newAPIError(resp, &env)
+	}
+	if out != nil && len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return fmt.Errorf("luckdb: decode response data: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) canRefresh() bool {
+	_, refreshToken := c.Tokens()
+	return refreshToken != ""
+}
+
+// refresh 使用刷新令牌更新访问令牌
+func (c *Client) refresh(ctx context.Context) error {
+	_, refreshToken := c.Tokens()
+	payload, err := json.Marshal(&RefreshTokenRequest{RefreshToken: refreshToken})
+	if err != nil {
+		return err
+	}
+	var out TokenResponse
+	if err := c.send(ctx, request{method: http.MethodPost, path: "/auth/refresh"}, payload, "", &out); err != nil {
+		return err
+	}
+	if out.RefreshToken == "" {
+		out.RefreshToken = refreshToken
+	}
+	c.SetTokens(out.AccessToken, out.RefreshToken)
+	return nil
+}
+
+// backoff 第 attempt 次重试前的指数退避时长
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.retry.BaseDelay
+	if delay <= 0 {
+		delay = DefaultRetryPolicy.BaseDelay
+	}
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if c.retry.MaxDelay > 0 && delay >= c.retry.MaxDelay {
+			return c.retry.MaxDelay
+		}
+	}
+	return delay
+}
+
+// parseRetryAfter 解析 Retry-After（秒数或 HTTP 日期）
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
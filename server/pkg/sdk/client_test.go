@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func writeEnvelope(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": 200000, "message": "ok", "data": data})
+}
+
+func TestCreateRecordRetriesWithSameIdempotencyKey(t *testing.T) {
+	var calls int32
+	keys := make(chan string, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get("Idempotency-Key")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":429000,"message":"too many requests","error":{"code":"RATE_LIMITED","retryable":true}}`))
+			return
+		}
+		writeEnvelope(w, http.StatusOK, map[string]interface{}{
+			"id":      "rec_1",
+			"tableId": "tbl_1",
+			"data":    map[string]interface{}{"fld_name": "Alice", "fld_age": 30},
+		})
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithToken("token"), WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}))
+	record, err := client.CreateRecord(context.Background(), "tbl_1", nil, &CreateRecordRequest{
+		TableID: "tbl_1",
+		Data:    Fields{}.Set("fld_name", "Alice"),
+	})
+	if err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+	first, second := <-keys, <-keys
+	if first == "" || first != second {
+		t.Fatalf("idempotency key should be stable across retries: %q vs %q", first, second)
+	}
+	if name, _ := record.Data.Text("fld_name"); name != "Alice" {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if age, ok := record.Data.Number("fld_age"); !ok || age != 30 {
+		t.Fatalf("unexpected age %v", age)
+	}
+}
+
+func TestRefreshesExpiredToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/auth/refresh":
+			writeEnvelope(w, http.StatusOK, map[string]string{"accessToken": "fresh", "refreshToken": "refresh-2"})
+		case r.Header.Get("Authorization") != "Bearer fresh":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":401000,"message":"unauthorized","error":{"code":"UNAUTHORIZED"}}`))
+		default:
+			writeEnvelope(w, http.StatusOK, map[string]interface{}{"id": "rec_1"})
+		}
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, WithToken("stale"), WithRefreshToken("refresh-1"))
+	if _, err := client.GetRecord(context.Background(), "tbl_1", "rec_1", nil); err != nil {
+		t.Fatalf("GetRecord: %v", err)
+	}
+	if access, refresh := client.Tokens(); access != "fresh" || refresh != "refresh-2" {
+		t.Fatalf("tokens not rotated: %q %q", access, refresh)
+	}
+}
+
+func TestNotFoundError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code":404000,"message":"记录不存在","error":{"code":"NOT_FOUND"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, WithToken("token")).GetRecord(context.Background(), "tbl_1", "missing", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
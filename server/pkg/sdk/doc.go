@@ -0,0 +1,14 @@
+// Package sdk LuckDB 官方 Go 客户端
+//
+// 接口方法与请求/响应类型由 cmd/sdkgen 根据 api/openapi.yaml 生成（*_gen.go），
+// 认证、重试、分页迭代与变更流订阅为手写部分。
+//
+//	client := sdk.New("https://luckdb.example.com", sdk.WithToken(token))
+//	it := client.Records(tableID, 100)
+//	for it.Next(ctx) {
+//		name, _ := it.Record().Data.Text(nameFieldID)
+//	}
+//	if err := it.Err(); err != nil { ... }
+package sdk
+
+//go:generate go run ../../cmd/sdkgen -spec ../../api/openapi.yaml -out .
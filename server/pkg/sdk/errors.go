@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 错误码（与服务端错误目录一致）
+const (
+	ErrorCodeNotFound        = "NOT_FOUND"
+	ErrorCodeConflict        = "CONFLICT"
+	ErrorCodeUnauthorized    = "UNAUTHORIZED"
+	ErrorCodeForbidden       = "FORBIDDEN"
+	ErrorCodeValidation      = "VALIDATION_FAILED"
+	ErrorCodeTooManyRequests = "TOO_MANY_REQUESTS"
+)
+
+// APIError 服务端返回的错误
+type APIError struct {
+	StatusCode int
+	Code       string // 错误目录中的错误码
+	Category   string
+	Message    string
+	Details    interface{}
+	Retryable  bool
+	RetryAfter time.Duration
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	if e.Details != nil {
+		return fmt.Sprintf("luckdb: %d %s: %s (%v)", e.StatusCode, e.Code, e.Message, e.Details)
+	}
+	return fmt.Sprintf("luckdb: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound 资源是否不存在
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict 是否为冲突错误（版本冲突、变更流游标过期等）
+func IsConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+func newAPIError(resp *http.Response, env *envelope) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    env.Message,
+		RequestID:  env.RequestID,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if env.Error != nil {
+		apiErr.Code = env.Error.Code
+		apiErr.Category = env.Error.Category
+		apiErr.Details = env.Error.Details
+		apiErr.Retryable = env.Error.Retryable
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// networkError 请求未得到响应（连接失败、读取中断等），可以重试
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return "luckdb: " + e.err.Error() }
+func (e *networkError) Unwrap() error { return e.err }
+
+// retryable 错误是否可以重试
+func retryable(err error) bool {
+	var netErr *networkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	}
+	return apiErr.Retryable
+}
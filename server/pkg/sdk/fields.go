@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Fields 记录的单元格值（按字段ID），提供按字段类型读取的方法
+//
+// 服务端返回的 JSON 值在 Go 中为 string、float64、bool、[]interface{}、map[string]interface{}；
+// 读取方法在类型不匹配或值为空时返回 ok=false
+type Fields map[string]interface{}
+
+// Text 文本类字段（单行文本、长文本、单选、邮箱、链接等）
+func (f Fields) Text(fieldID string) (string, bool) {
+	s, ok := f[fieldID].(string)
+	return s, ok
+}
+
+// Number 数值类字段（数字、货币、百分比、评分、时长）
+func (f Fields) Number(fieldID string) (float64, bool) {
+	switch v := f[fieldID].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// Bool 复选框字段（未勾选时服务端可能不返回该字段，此时返回 false, false）
+func (f Fields) Bool(fieldID string) (bool, bool) {
+	b, ok := f[fieldID].(bool)
+	return b, ok
+}
+
+// Time 日期字段（RFC3339 字符串）
+func (f Fields) Time(fieldID string) (time.Time, bool) {
+	switch v := f[fieldID].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", v); err != nil {
+				return time.Time{}, false
+			}
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Strings 多选字段
+func (f Fields) Strings(fieldID string) ([]string, bool) {
+	switch v := f[fieldID].(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// Decode 将字段值解码到任意结构（如附件、关联记录、用户字段）
+func (f Fields) Decode(fieldID string, out interface{}) error {
+	v, ok := f[fieldID]
+	if !ok || v == nil {
+		return fmt.Errorf("luckdb: field %s is empty", fieldID)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// Set 设置字段值（time.Time 按 RFC3339 提交），返回自身便于链式调用
+func (f Fields) Set(fieldID string, value interface{}) Fields {
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+	f[fieldID] = value
+	return f
+}
+
+// Attachment 附件字段中的单个文件
+type Attachment struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	MimeType string `json:"mimetype,omitempty"`
+	URL      string `json:"presignedUrl,omitempty"`
+}
+
+// LinkValue 关联字段中的单条关联记录
+type LinkValue struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// Attachments 附件字段
+func (f Fields) Attachments(fieldID string) ([]Attachment, bool) {
+	var out []Attachment
+	if err := f.Decode(fieldID, &out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// Links 关联字段（单条关联时也返回切片）
+func (f Fields) Links(fieldID string) ([]LinkValue, bool) {
+	var out []LinkValue
+	if err := f.Decode(fieldID, &out); err == nil {
+		return out, true
+	}
+	var single LinkValue
+	if err := f.Decode(fieldID, &single); err != nil || single.ID == "" {
+		return nil, false
+	}
+	return []LinkValue{single}, true
+}
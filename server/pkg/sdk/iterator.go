@@ -0,0 +1,79 @@
+package sdk
+
+import "context"
+
+// RecordIterator 按页遍历表中的全部记录
+//
+//	it := client.Records(tableID, 200)
+//	for it.Next(ctx) {
+//		record := it.Record()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type RecordIterator struct {
+	client   *Client
+	tableID  string
+	pageSize int
+
+	page    int
+	records []*Record
+	index   int
+	done    bool
+	err     error
+}
+
+// Records 创建记录迭代器，pageSize 为每次请求的记录数
+func (c *Client) Records(tableID string, pageSize int) *RecordIterator {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	return &RecordIterator{client: c, tableID: tableID, pageSize: pageSize, index: -1}
+}
+
+// Next 前进到下一条记录，没有更多记录或出错时返回 false
+func (it *RecordIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	it.index++
+	if it.index < len(it.records) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	it.page++
+	page, err := it.client.ListRecords(ctx, it.tableID, &ListRecordsParams{Page: it.page, PerPage: it.pageSize})
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.records = page.List
+	it.index = 0
+	if page.Pagination == nil || it.page >= page.Pagination.TotalPages || len(page.List) < it.pageSize {
+		it.done = true
+	}
+	return len(it.records) > 0
+}
+
+// Record 当前记录
+func (it *RecordIterator) Record() *Record {
+	if it.index < 0 || it.index >= len(it.records) {
+		return nil
+	}
+	return it.records[it.index]
+}
+
+// Err 遍历过程中的错误
+func (it *RecordIterator) Err() error {
+	return it.err
+}
+
+// All 读取全部记录
+func (it *RecordIterator) All(ctx context.Context) ([]*Record, error) {
+	var all []*Record
+	for it.Next(ctx) {
+		all = append(all, it.Record())
+	}
+	return all, it.Err()
+}
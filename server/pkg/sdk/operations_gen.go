@@ -0,0 +1,2480 @@
+// Code generated by sdkgen from api/openapi.yaml. DO NOT EDIT.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// AdminAssignSpacePlan 为工作空间分配套餐（仅管理员）
+// PUT /admin/spaces/{spaceId}/billing/plan
+func (c *Client) AdminAssignSpacePlan(ctx context.Context, spaceID string, body *AssignPlanRequest) (*PlanUsageSummary, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/billing/plan", url.PathEscape(spaceID))
+	var out PlanUsageSummary
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AdminGetSpacePlan 获取工作空间的套餐与本月用量（仅管理员）
+// GET /admin/spaces/{spaceId}/billing/plan
+func (c *Client) AdminGetSpacePlan(ctx context.Context, spaceID string) (*PlanUsageSummary, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/billing/plan", url.PathEscape(spaceID))
+	var out PlanUsageSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ApplyBaseSchema 应用结构描述（幂等，存在冲突时拒绝）
+// POST /bases/{baseId}/schema/apply
+func (c *Client) ApplyBaseSchema(ctx context.Context, baseID string, body *SchemaSpecRequest) (*SchemaPlan, error) {
+	path := fmt.Sprintf("/bases/%s/schema/apply", url.PathEscape(baseID))
+	var out SchemaPlan
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BatchCreateRecordsParams BatchCreateRecords 的查询参数（零值表示不传）
+type BatchCreateRecordsParams struct {
+	FieldKeyType string
+}
+
+// BatchCreateRecords 批量创建记录（最多 1000 条）
+// POST /tables/{tableId}/records/batch
+func (c *Client) BatchCreateRecords(ctx context.Context, tableID string, params *BatchCreateRecordsParams, body *BatchCreateRecordsRequest) (*BatchCreateRecordsResponse, error) {
+	path := fmt.Sprintf("/tables/%s/records/batch", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.FieldKeyType != "" {
+			query.Set("fieldKeyType", params.FieldKeyType)
+		}
+	}
+	var out BatchCreateRecordsResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, query: query, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelOperation 请求取消长时操作
+// POST /operations/{operationId}/cancel
+func (c *Client) CancelOperation(ctx context.Context, operationID string) (*Operation, error) {
+	path := fmt.Sprintf("/operations/%s/cancel", url.PathEscape(operationID))
+	var out Operation
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelWorkspaceDeletion 撤销计划删除，工作空间保持停用（仅管理员）
+// POST /admin/spaces/{spaceId}/lifecycle/cancel-deletion
+func (c *Client) CancelWorkspaceDeletion(ctx context.Context, spaceID string) (*WorkspaceLifecycle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/cancel-deletion", url.PathEscape(spaceID))
+	var out WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleClearFeature 删除功能开关覆盖，恢复为配置文件的定义
+// DELETE /console/features/{key}
+func (c *Client) ConsoleClearFeature(ctx context.Context, key string) error {
+	path := fmt.Sprintf("/console/features/%s", url.PathEscape(key))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// ConsoleGetSpaceUsage 工作空间的套餐与资源用量
+// GET /console/spaces/{spaceId}/usage
+func (c *Client) ConsoleGetSpaceUsage(ctx context.Context, spaceID string) (*PlanUsageSummary, error) {
+	path := fmt.Sprintf("/console/spaces/%s/usage", url.PathEscape(spaceID))
+	var out PlanUsageSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleGetStats 实例统计
+// GET /console/stats
+func (c *Client) ConsoleGetStats(ctx context.Context) (*SystemStats, error) {
+	path := "/console/stats"
+	var out SystemStats
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleListFeatures 功能开关的生效定义及控制台覆盖
+// GET /console/features
+func (c *Client) ConsoleListFeatures(ctx context.Context) ([]*ConsoleFeature, error) {
+	path := "/console/features"
+	var out []*ConsoleFeature
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ConsoleListSpacesParams ConsoleListSpaces 的查询参数（零值表示不传）
+type ConsoleListSpacesParams struct {
+	Q      string
+	Limit  int
+	Offset int
+}
+
+// ConsoleListSpaces 跨工作空间检索（q 匹配工作空间 ID、名称或所有者邮箱）
+// GET /console/spaces
+func (c *Client) ConsoleListSpaces(ctx context.Context, params *ConsoleListSpacesParams) (*ConsoleSpaceList, error) {
+	path := "/console/spaces"
+	query := url.Values{}
+	if params != nil {
+		if params.Q != "" {
+			query.Set("q", params.Q)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	var out ConsoleSpaceList
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleListUsersParams ConsoleListUsers 的查询参数（零值表示不传）
+type ConsoleListUsersParams struct {
+	Q      string
+	Admin  bool
+	Limit  int
+	Offset int
+}
+
+// ConsoleListUsers 检索用户（q 匹配名称或邮箱）
+// GET /console/users
+func (c *Client) ConsoleListUsers(ctx context.Context, params *ConsoleListUsersParams) (*ConsoleUserList, error) {
+	path := "/console/users"
+	query := url.Values{}
+	if params != nil {
+		if params.Q != "" {
+			query.Set("q", params.Q)
+		}
+		if params.Admin {
+			query.Set("admin", "true")
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	var out ConsoleUserList
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleLogin 管理员登录管理控制台，返回只能访问 /console 接口的控制台令牌
+// POST /console/auth/login
+func (c *Client) ConsoleLogin(ctx context.Context, body *ConsoleLoginRequest) (*ConsoleLoginResponse, error) {
+	path := "/console/auth/login"
+	var out ConsoleLoginResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleLogout 注销控制台会话
+// POST /console/auth/logout
+func (c *Client) ConsoleLogout(ctx context.Context) error {
+	path := "/console/auth/logout"
+	return c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, nil)
+}
+
+// ConsoleResetUserPassword 重置用户密码并吊销其全部登录会话（未指定密码时生成临时密码）
+// POST /console/users/{userId}/reset-password
+func (c *Client) ConsoleResetUserPassword(ctx context.Context, userID string, body *ResetUserPasswordRequest) (*ResetUserPasswordResponse, error) {
+	path := fmt.Sprintf("/console/users/%s/reset-password", url.PathEscape(userID))
+	var out ResetUserPasswordResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleSetFeature 覆盖功能开关（关闭时整体关闭，开启时全量开启但拒绝名单仍生效），对所有实例生效
+// PUT /console/features/{key}
+func (c *Client) ConsoleSetFeature(ctx context.Context, key string, body *SetFeatureRequest) (*FeatureOverride, error) {
+	path := fmt.Sprintf("/console/features/%s", url.PathEscape(key))
+	var out FeatureOverride
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsoleTransferSpaceOwnership 将工作空间转让给指定用户，原所有者降为创建者
+// POST /console/spaces/{spaceId}/transfer-ownership
+func (c *Client) ConsoleTransferSpaceOwnership(ctx context.Context, spaceID string, body *TransferSpaceOwnershipRequest) (*SpaceOwnershipTransfer, error) {
+	path := fmt.Sprintf("/console/spaces/%s/transfer-ownership", url.PathEscape(spaceID))
+	var out SpaceOwnershipTransfer
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConvertHTMLToMarkdown 将 HTML 净化后转换为 Markdown
+// POST /rich-text/html-to-markdown
+func (c *Client) ConvertHTMLToMarkdown(ctx context.Context, body *RichTextHTML) (*RichTextMarkdown, error) {
+	path := "/rich-text/html-to-markdown"
+	var out RichTextMarkdown
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConvertMarkdownToHTML 将 Markdown 转换为净化后的 HTML
+// POST /rich-text/markdown-to-html
+func (c *Client) ConvertMarkdownToHTML(ctx context.Context, body *RichTextMarkdown) (*RichTextHTML, error) {
+	path := "/rich-text/markdown-to-html"
+	var out RichTextHTML
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBaseBranch 创建沙盒分支（复制表、字段与视图，不复制记录）
+// POST /bases/{baseId}/branches
+func (c *Client) CreateBaseBranch(ctx context.Context, baseID string, body *CreateBaseBranchRequest) (*BaseBranch, error) {
+	path := fmt.Sprintf("/bases/%s/branches", url.PathEscape(baseID))
+	var out BaseBranch
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBillingPortalSession 创建 Stripe 客户门户会话，用于管理订阅与付款方式（仅空间所有者）
+// POST /spaces/{spaceId}/billing/portal-session
+func (c *Client) CreateBillingPortalSession(ctx context.Context, spaceID string, body *CreatePortalSessionRequest) (*PortalSession, error) {
+	path := fmt.Sprintf("/spaces/%s/billing/portal-session", url.PathEscape(spaceID))
+	var out PortalSession
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateDataExport 创建数据导出任务（Parquet，需要 Base 编辑权限，受空间敏感操作策略约束）
+// POST /bases/{baseId}/exports
+func (c *Client) CreateDataExport(ctx context.Context, baseID string, body *CreateDataExportRequest) (*DataExport, error) {
+	path := fmt.Sprintf("/bases/%s/exports", url.PathEscape(baseID))
+	var out DataExport
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateDocumentTemplate 以已上传的附件创建文档模板（需要表结构管理权限）
+// POST /tables/{tableId}/document-templates
+func (c *Client) CreateDocumentTemplate(ctx context.Context, tableID string, body *CreateDocumentTemplateRequest) (*DocumentTemplate, error) {
+	path := fmt.Sprintf("/tables/%s/document-templates", url.PathEscape(tableID))
+	var out DocumentTemplate
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateExternalSync 创建外部数据同步（按字段对应建立只读目标表，首次全量同步在后台执行）
+// POST /bases/{baseId}/external-syncs
+func (c *Client) CreateExternalSync(ctx context.Context, baseID string, body *CreateExternalSyncRequest) (*ExternalSyncConnector, error) {
+	path := fmt.Sprintf("/bases/%s/external-syncs", url.PathEscape(baseID))
+	var out ExternalSyncConnector
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateHolidayCalendar 创建节假日日历（需要 Base 编辑权限）
+// POST /bases/{baseId}/holiday-calendars
+func (c *Client) CreateHolidayCalendar(ctx context.Context, baseID string, body *SaveHolidayCalendarRequest) (*HolidayCalendar, error) {
+	path := fmt.Sprintf("/bases/%s/holiday-calendars", url.PathEscape(baseID))
+	var out HolidayCalendar
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePage 创建界面页面（需要 Base 编辑权限）
+// POST /bases/{baseId}/pages
+func (c *Client) CreatePage(ctx context.Context, baseID string, body *CreatePageRequest) (*Page, error) {
+	path := fmt.Sprintf("/bases/%s/pages", url.PathEscape(baseID))
+	var out Page
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePermalink 获取或创建记录、视图的永久链接（需要能查看目标）
+// POST /permalinks
+func (c *Client) CreatePermalink(ctx context.Context, body *CreatePermalinkRequest) (*Permalink, error) {
+	path := "/permalinks"
+	var out Permalink
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreatePrintJob 创建记录或视图的 PDF 打印任务（后台生成）
+// POST /print-jobs
+func (c *Client) CreatePrintJob(ctx context.Context, body *CreatePrintJobRequest) (*PrintJob, error) {
+	path := "/print-jobs"
+	var out PrintJob
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRecordParams CreateRecord 的查询参数（零值表示不传）
+type CreateRecordParams struct {
+	FieldKeyType string
+}
+
+// CreateRecord 创建记录
+// POST /tables/{tableId}/records
+func (c *Client) CreateRecord(ctx context.Context, tableID string, params *CreateRecordParams, body *CreateRecordRequest) (*Record, error) {
+	path := fmt.Sprintf("/tables/%s/records", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.FieldKeyType != "" {
+			query.Set("fieldKeyType", params.FieldKeyType)
+		}
+	}
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, query: query, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRecordFromTemplate 从模板新建记录（请求中的值覆盖模板中的同一字段）
+// POST /record-templates/{templateId}/records
+func (c *Client) CreateRecordFromTemplate(ctx context.Context, templateID string, body *CreateFromRecordTemplateRequest) (*Record, error) {
+	path := fmt.Sprintf("/record-templates/%s/records", url.PathEscape(templateID))
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRecordTemplate 创建记录模板（需要表结构管理权限）
+// POST /tables/{tableId}/record-templates
+func (c *Client) CreateRecordTemplate(ctx context.Context, tableID string, body *SaveRecordTemplateRequest) (*RecordTemplate, error) {
+	path := fmt.Sprintf("/tables/%s/record-templates", url.PathEscape(tableID))
+	var out RecordTemplate
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRecurrenceRule 创建重复记录规则（需要表结构管理权限，只处理创建之后的执行）
+// POST /tables/{tableId}/recurrence-rules
+func (c *Client) CreateRecurrenceRule(ctx context.Context, tableID string, body *CreateRecurrenceRuleRequest) (*RecurrenceRule, error) {
+	path := fmt.Sprintf("/tables/%s/recurrence-rules", url.PathEscape(tableID))
+	var out RecurrenceRule
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateServiceToken 创建服务令牌（须使用登录令牌）
+// POST /auth/service-tokens
+func (c *Client) CreateServiceToken(ctx context.Context, body *CreateServiceTokenRequest) (*CreatedServiceToken, error) {
+	path := "/auth/service-tokens"
+	var out CreatedServiceToken
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSpace 创建工作空间
+// POST /spaces
+func (c *Client) CreateSpace(ctx context.Context, body *CreateSpaceRequest) (*Space, error) {
+	path := "/spaces"
+	var out Space
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSyncedTable 创建同步表（需要目标 Base 的管理权限与源表的读取权限，首次全量同步在后台执行）
+// POST /bases/{baseId}/synced-tables
+func (c *Client) CreateSyncedTable(ctx context.Context, baseID string, body *CreateSyncedTableRequest) (*SyncedTable, error) {
+	path := fmt.Sprintf("/bases/%s/synced-tables", url.PathEscape(baseID))
+	var out SyncedTable
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTableHealthFix 创建一键修复任务（移除失效关联、写入转换后的值）
+// POST /health-reports/{reportId}/fixes
+func (c *Client) CreateTableHealthFix(ctx context.Context, reportID string, body *CreateTableHealthFixRequest) (*TableHealthFixJob, error) {
+	path := fmt.Sprintf("/health-reports/%s/fixes", url.PathEscape(reportID))
+	var out TableHealthFixJob
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateTextExtractionRule 创建附件文本提取规则（附件新增或变更时自动提取文本写入目标字段）
+// POST /tables/{tableId}/text-extraction-rules
+func (c *Client) CreateTextExtractionRule(ctx context.Context, tableID string, body *CreateTextExtractionRuleRequest) (*TextExtractionRule, error) {
+	path := fmt.Sprintf("/tables/%s/text-extraction-rules", url.PathEscape(tableID))
+	var out TextExtractionRule
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateWorkspaceBundle 生成工作空间的完整导出包：元数据、全部记录与附件（仅管理员）
+// POST /admin/spaces/{spaceId}/lifecycle/bundles
+func (c *Client) CreateWorkspaceBundle(ctx context.Context, spaceID string) (*WorkspaceExportBundle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/bundles", url.PathEscape(spaceID))
+	var out WorkspaceExportBundle
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteArchivePolicy 删除表的归档策略（已归档的记录保留）
+// DELETE /tables/{tableId}/archive-policy
+func (c *Client) DeleteArchivePolicy(ctx context.Context, tableID string) error {
+	path := fmt.Sprintf("/tables/%s/archive-policy", url.PathEscape(tableID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteDocumentTemplate 删除文档模板（已生成的附件保留）
+// DELETE /document-templates/{templateId}
+func (c *Client) DeleteDocumentTemplate(ctx context.Context, templateID string) error {
+	path := fmt.Sprintf("/document-templates/%s", url.PathEscape(templateID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteExternalSync 删除外部数据同步（目标表保留并恢复为可编辑）
+// DELETE /external-syncs/{connectorId}
+func (c *Client) DeleteExternalSync(ctx context.Context, connectorID string) error {
+	path := fmt.Sprintf("/external-syncs/%s", url.PathEscape(connectorID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteHolidayCalendar 删除节假日日历（仍被重复规则使用时拒绝）
+// DELETE /holiday-calendars/{calendarId}
+func (c *Client) DeleteHolidayCalendar(ctx context.Context, calendarID string) error {
+	path := fmt.Sprintf("/holiday-calendars/%s", url.PathEscape(calendarID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeletePage 删除页面
+// DELETE /pages/{pageId}
+func (c *Client) DeletePage(ctx context.Context, pageID string) error {
+	path := fmt.Sprintf("/pages/%s", url.PathEscape(pageID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteRecord 删除记录
+// DELETE /tables/{tableId}/records/{recordId}
+func (c *Client) DeleteRecord(ctx context.Context, tableID string, recordID string) error {
+	path := fmt.Sprintf("/tables/%s/records/%s", url.PathEscape(tableID), url.PathEscape(recordID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteRecordTemplate 删除记录模板（需要表结构管理权限）
+// DELETE /record-templates/{templateId}
+func (c *Client) DeleteRecordTemplate(ctx context.Context, templateID string) error {
+	path := fmt.Sprintf("/record-templates/%s", url.PathEscape(templateID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteRecurrenceRule 删除重复记录规则（已生成的记录保留）
+// DELETE /recurrence-rules/{ruleId}
+func (c *Client) DeleteRecurrenceRule(ctx context.Context, ruleID string) error {
+	path := fmt.Sprintf("/recurrence-rules/%s", url.PathEscape(ruleID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteScanRule 删除字段的扫码规则（需要表结构管理权限）
+// DELETE /tables/{tableId}/scan-rules/{fieldId}
+func (c *Client) DeleteScanRule(ctx context.Context, tableID string, fieldID string) error {
+	path := fmt.Sprintf("/tables/%s/scan-rules/%s", url.PathEscape(tableID), url.PathEscape(fieldID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteSemanticIndex 删除表的语义索引及全部向量
+// DELETE /tables/{tableId}/semantic-index
+func (c *Client) DeleteSemanticIndex(ctx context.Context, tableID string) error {
+	path := fmt.Sprintf("/tables/%s/semantic-index", url.PathEscape(tableID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteSpace 删除工作空间
+// DELETE /spaces/{spaceId}
+func (c *Client) DeleteSpace(ctx context.Context, spaceID string) error {
+	path := fmt.Sprintf("/spaces/%s", url.PathEscape(spaceID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteStatusFlow 删除字段的状态流
+// DELETE /tables/{tableId}/status-flows/{fieldId}
+func (c *Client) DeleteStatusFlow(ctx context.Context, tableID string, fieldID string) error {
+	path := fmt.Sprintf("/tables/%s/status-flows/%s", url.PathEscape(tableID), url.PathEscape(fieldID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteSyncedTable 停止同步（镜像表保留并恢复为可编辑）
+// DELETE /synced-tables/{syncId}
+func (c *Client) DeleteSyncedTable(ctx context.Context, syncID string) error {
+	path := fmt.Sprintf("/synced-tables/%s", url.PathEscape(syncID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DeleteTextExtractionRule 删除附件文本提取规则及其任务
+// DELETE /text-extraction-rules/{ruleId}
+func (c *Client) DeleteTextExtractionRule(ctx context.Context, ruleID string) error {
+	path := fmt.Sprintf("/text-extraction-rules/%s", url.PathEscape(ruleID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// DiffBaseBranch 预览合并到生产的变更与冲突
+// GET /base-branches/{branchId}/diff
+func (c *Client) DiffBaseBranch(ctx context.Context, branchID string) (*SchemaPlan, error) {
+	path := fmt.Sprintf("/base-branches/%s/diff", url.PathEscape(branchID))
+	var out SchemaPlan
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DiffBaseSchema 对比结构描述与 Base 当前结构
+// POST /bases/{baseId}/schema/diff
+func (c *Client) DiffBaseSchema(ctx context.Context, baseID string, body *SchemaSpecRequest) (*SchemaPlan, error) {
+	path := fmt.Sprintf("/bases/%s/schema/diff", url.PathEscape(baseID))
+	var out SchemaPlan
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DiscardBaseBranch 丢弃沙盒分支并删除沙盒 Base
+// DELETE /base-branches/{branchId}
+func (c *Client) DiscardBaseBranch(ctx context.Context, branchID string) error {
+	path := fmt.Sprintf("/base-branches/%s", url.PathEscape(branchID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// EndImpersonation 结束代入会话（发起的支持人员、被代入用户或管理员）
+// POST /impersonation/sessions/{sessionId}/end
+func (c *Client) EndImpersonation(ctx context.Context, sessionID string) (*ImpersonationSession, error) {
+	path := fmt.Sprintf("/impersonation/sessions/%s/end", url.PathEscape(sessionID))
+	var out ImpersonationSession
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EndMaintenance 结束维护窗口（仅管理员）
+// POST /admin/maintenance/{windowId}/end
+func (c *Client) EndMaintenance(ctx context.Context, windowID string) (*MaintenanceWindow, error) {
+	path := fmt.Sprintf("/admin/maintenance/%s/end", url.PathEscape(windowID))
+	var out MaintenanceWindow
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ExportBaseSchema 导出 Base 结构描述（YAML）
+// GET /bases/{baseId}/schema
+func (c *Client) ExportBaseSchema(ctx context.Context, baseID string) (*SchemaSpecDocument, error) {
+	path := fmt.Sprintf("/bases/%s/schema", url.PathEscape(baseID))
+	var out SchemaSpecDocument
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FlushCache 清理缓存（仅管理员）
+// POST /admin/cache/flush
+func (c *Client) FlushCache(ctx context.Context, body *FlushCacheRequest) error {
+	path := "/admin/cache/flush"
+	return c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, nil)
+}
+
+// GenerateDocuments 为记录或视图中的记录生成文档（后台执行，生成的文件写入附件字段）
+// POST /document-templates/{templateId}/generate
+func (c *Client) GenerateDocuments(ctx context.Context, templateID string, body *GenerateDocumentsRequest) (*DocumentJob, error) {
+	path := fmt.Sprintf("/document-templates/%s/generate", url.PathEscape(templateID))
+	var out DocumentJob
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAIQuota 获取工作空间 AI 配额与本月用量（仅空间所有者）
+// GET /spaces/{spaceId}/ai-quota
+func (c *Client) GetAIQuota(ctx context.Context, spaceID string) (*AIQuotaReport, error) {
+	path := fmt.Sprintf("/spaces/%s/ai-quota", url.PathEscape(spaceID))
+	var out AIQuotaReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetArchivePolicy 获取表的归档策略
+// GET /tables/{tableId}/archive-policy
+func (c *Client) GetArchivePolicy(ctx context.Context, tableID string) (*ArchivePolicy, error) {
+	path := fmt.Sprintf("/tables/%s/archive-policy", url.PathEscape(tableID))
+	var out ArchivePolicy
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAttachmentPolicy 获取工作空间附件策略（仅空间所有者）
+// GET /spaces/{spaceId}/attachment-policy
+func (c *Client) GetAttachmentPolicy(ctx context.Context, spaceID string) (*AttachmentPolicy, error) {
+	path := fmt.Sprintf("/spaces/%s/attachment-policy", url.PathEscape(spaceID))
+	var out AttachmentPolicy
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBaseBranch 获取沙盒分支
+// GET /base-branches/{branchId}
+func (c *Client) GetBaseBranch(ctx context.Context, branchID string) (*BaseBranch, error) {
+	path := fmt.Sprintf("/base-branches/%s", url.PathEscape(branchID))
+	var out BaseBranch
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBusinessCalendar 获取工作空间的工作日历（未配置时返回默认日历）
+// GET /spaces/{spaceId}/business-calendar
+func (c *Client) GetBusinessCalendar(ctx context.Context, spaceID string) (*BusinessCalendar, error) {
+	path := fmt.Sprintf("/spaces/%s/business-calendar", url.PathEscape(spaceID))
+	var out BusinessCalendar
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCellHistoryParams GetCellHistory 的查询参数（零值表示不传）
+type GetCellHistoryParams struct {
+	Cursor int64
+	Limit  int
+}
+
+// GetCellHistory 获取单元格修改历史（值、修改人、时间）
+// GET /tables/{tableId}/records/{recordId}/fields/{fieldId}/history
+func (c *Client) GetCellHistory(ctx context.Context, tableID string, recordID string, fieldID string, params *GetCellHistoryParams) (*CellHistoryResponse, error) {
+	path := fmt.Sprintf("/tables/%s/records/%s/fields/%s/history", url.PathEscape(tableID), url.PathEscape(recordID), url.PathEscape(fieldID))
+	query := url.Values{}
+	if params != nil {
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out CellHistoryResponse
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChangeHead 获取变更流的最新游标（全量同步前记录，之后从该游标增量拉取）
+// GET /bases/{baseId}/changes/head
+func (c *Client) GetChangeHead(ctx context.Context, baseID string) (*ChangeFeedHead, error) {
+	path := fmt.Sprintf("/bases/%s/changes/head", url.PathEscape(baseID))
+	var out ChangeFeedHead
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetConsoleSession 当前控制台会话
+// GET /console/auth/session
+func (c *Client) GetConsoleSession(ctx context.Context) (*ConsoleSession, error) {
+	path := "/console/auth/session"
+	var out ConsoleSession
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDataExport 获取数据导出任务（完成后包含清单与下载链接）
+// GET /data-exports/{exportId}
+func (c *Client) GetDataExport(ctx context.Context, exportID string) (*DataExport, error) {
+	path := fmt.Sprintf("/data-exports/%s", url.PathEscape(exportID))
+	var out DataExport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDisasterRecoveryRunbook 获取故障切换手册：当前状态与各步骤的检查结果（仅管理员）
+// GET /admin/disaster-recovery/runbook
+func (c *Client) GetDisasterRecoveryRunbook(ctx context.Context) (*DRRunbook, error) {
+	path := "/admin/disaster-recovery/runbook"
+	var out DRRunbook
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDisasterRecoveryStatus 获取跨区域灾备状态：各复制流进度、RPO 与复制延迟（仅管理员）
+// GET /admin/disaster-recovery/status
+func (c *Client) GetDisasterRecoveryStatus(ctx context.Context) (*DRStatusReport, error) {
+	path := "/admin/disaster-recovery/status"
+	var out DRStatusReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDocumentJob 获取文档生成任务（包含每条记录的生成结果）
+// GET /document-jobs/{jobId}
+func (c *Client) GetDocumentJob(ctx context.Context, jobID string) (*DocumentJob, error) {
+	path := fmt.Sprintf("/document-jobs/%s", url.PathEscape(jobID))
+	var out DocumentJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDocumentTemplate 获取文档模板
+// GET /document-templates/{templateId}
+func (c *Client) GetDocumentTemplate(ctx context.Context, templateID string) (*DocumentTemplate, error) {
+	path := fmt.Sprintf("/document-templates/%s", url.PathEscape(templateID))
+	var out DocumentTemplate
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetExternalSync 获取外部数据同步及最近一次同步状态
+// GET /external-syncs/{connectorId}
+func (c *Client) GetExternalSync(ctx context.Context, connectorID string) (*ExternalSyncConnector, error) {
+	path := fmt.Sprintf("/external-syncs/%s", url.PathEscape(connectorID))
+	var out ExternalSyncConnector
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFieldOptionSchema 获取字段类型的选项 JSON Schema（创建与更新字段时按此校验 options）
+// GET /field-option-schemas/{fieldType}
+func (c *Client) GetFieldOptionSchema(ctx context.Context, fieldType string) (*FieldOptionSchema, error) {
+	path := fmt.Sprintf("/field-option-schemas/%s", url.PathEscape(fieldType))
+	var out FieldOptionSchema
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMaintenanceOverview 当前生效与最近的维护窗口（仅管理员）
+// GET /admin/maintenance
+func (c *Client) GetMaintenanceOverview(ctx context.Context) (*MaintenanceOverview, error) {
+	path := "/admin/maintenance"
+	var out MaintenanceOverview
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMaintenanceStatusParams GetMaintenanceStatus 的查询参数（零值表示不传）
+type GetMaintenanceStatusParams struct {
+	SpaceID string
+	BaseID  string
+	TableID string
+}
+
+// GetMaintenanceStatus 资源所在的实例、工作空间或 Base 的维护状态（维护中写请求返回 MAINTENANCE_READ_ONLY）
+// GET /maintenance/status
+func (c *Client) GetMaintenanceStatus(ctx context.Context, params *GetMaintenanceStatusParams) (*MaintenanceStatus, error) {
+	path := "/maintenance/status"
+	query := url.Values{}
+	if params != nil {
+		if params.SpaceID != "" {
+			query.Set("spaceId", params.SpaceID)
+		}
+		if params.BaseID != "" {
+			query.Set("baseId", params.BaseID)
+		}
+		if params.TableID != "" {
+			query.Set("tableId", params.TableID)
+		}
+	}
+	var out MaintenanceStatus
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOfflineSnapshotParams GetOfflineSnapshot 的查询参数（零值表示不传）
+type GetOfflineSnapshotParams struct {
+	TableID string
+	After   int64
+	Limit   int
+}
+
+// GetOfflineSnapshot 分页拉取表的记录快照（离线同步的全量部分）
+// GET /bases/{baseId}/offline-sync/snapshot
+func (c *Client) GetOfflineSnapshot(ctx context.Context, baseID string, params *GetOfflineSnapshotParams) (*OfflineSnapshotPage, error) {
+	path := fmt.Sprintf("/bases/%s/offline-sync/snapshot", url.PathEscape(baseID))
+	query := url.Values{}
+	if params != nil {
+		if params.TableID != "" {
+			query.Set("tableId", params.TableID)
+		}
+		if params.After != 0 {
+			query.Set("after", strconv.FormatInt(params.After, 10))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out OfflineSnapshotPage
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOnlineMigrationStatus 元数据表在线迁移状态（仅管理员）
+// GET /admin/migrations/status
+func (c *Client) GetOnlineMigrationStatus(ctx context.Context) (*OnlineMigrationStatus, error) {
+	path := "/admin/migrations/status"
+	var out OnlineMigrationStatus
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOperation 获取长时操作的状态与进度
+// GET /operations/{operationId}
+func (c *Client) GetOperation(ctx context.Context, operationID string) (*Operation, error) {
+	path := fmt.Sprintf("/operations/%s", url.PathEscape(operationID))
+	var out Operation
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPage 获取页面配置与分享规则（需要 Base 编辑权限）
+// GET /pages/{pageId}
+func (c *Client) GetPage(ctx context.Context, pageID string) (*Page, error) {
+	path := fmt.Sprintf("/pages/%s", url.PathEscape(pageID))
+	var out Page
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPageElementRecord 读取页面元素中的单条记录
+// GET /pages/{pageId}/elements/{elementId}/records/{recordId}
+func (c *Client) GetPageElementRecord(ctx context.Context, pageID string, elementID string, recordID string) (*Record, error) {
+	path := fmt.Sprintf("/pages/%s/elements/%s/records/%s", url.PathEscape(pageID), url.PathEscape(elementID), url.PathEscape(recordID))
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPrintJob 获取打印任务（完成后包含下载链接）
+// GET /print-jobs/{jobId}
+func (c *Client) GetPrintJob(ctx context.Context, jobID string) (*PrintJob, error) {
+	path := fmt.Sprintf("/print-jobs/%s", url.PathEscape(jobID))
+	var out PrintJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRecordParams GetRecord 的查询参数（零值表示不传）
+type GetRecordParams struct {
+	FieldKeyType     string
+	Fields           []string
+	IncludeFormatted bool
+	TimeZone         string
+	Locale           string
+}
+
+// GetRecord 获取单条记录
+// GET /tables/{tableId}/records/{recordId}
+func (c *Client) GetRecord(ctx context.Context, tableID string, recordID string, params *GetRecordParams) (*Record, error) {
+	path := fmt.Sprintf("/tables/%s/records/%s", url.PathEscape(tableID), url.PathEscape(recordID))
+	query := url.Values{}
+	if params != nil {
+		if params.FieldKeyType != "" {
+			query.Set("fieldKeyType", params.FieldKeyType)
+		}
+		for _, v := range params.Fields {
+			query.Add("fields", v)
+		}
+		if params.IncludeFormatted {
+			query.Set("includeFormatted", "true")
+		}
+		if params.TimeZone != "" {
+			query.Set("timeZone", params.TimeZone)
+		}
+		if params.Locale != "" {
+			query.Set("locale", params.Locale)
+		}
+	}
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSemanticIndex 获取表的语义索引（未建立时为 null）
+// GET /tables/{tableId}/semantic-index
+func (c *Client) GetSemanticIndex(ctx context.Context, tableID string) (*SemanticIndex, error) {
+	path := fmt.Sprintf("/tables/%s/semantic-index", url.PathEscape(tableID))
+	var out SemanticIndex
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSpace 获取工作空间
+// GET /spaces/{spaceId}
+func (c *Client) GetSpace(ctx context.Context, spaceID string) (*Space, error) {
+	path := fmt.Sprintf("/spaces/%s", url.PathEscape(spaceID))
+	var out Space
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSpacePlanUsage 获取工作空间的套餐与本月用量（仅空间所有者）
+// GET /spaces/{spaceId}/billing/usage
+func (c *Client) GetSpacePlanUsage(ctx context.Context, spaceID string) (*PlanUsageSummary, error) {
+	path := fmt.Sprintf("/spaces/%s/billing/usage", url.PathEscape(spaceID))
+	var out PlanUsageSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSpaceSubscription 获取工作空间的 Stripe 订阅（仅空间所有者，未订阅时为空）
+// GET /spaces/{spaceId}/billing/subscription
+func (c *Client) GetSpaceSubscription(ctx context.Context, spaceID string) (*SpaceSubscription, error) {
+	path := fmt.Sprintf("/spaces/%s/billing/subscription", url.PathEscape(spaceID))
+	var out SpaceSubscription
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSpaceUsageParams GetSpaceUsage 的查询参数（零值表示不传）
+type GetSpaceUsageParams struct {
+	From string
+	To   string
+}
+
+// GetSpaceUsage 获取工作空间用量统计（仅空间所有者，默认最近 30 天）
+// GET /spaces/{spaceId}/usage
+func (c *Client) GetSpaceUsage(ctx context.Context, spaceID string, params *GetSpaceUsageParams) (*UsageReport, error) {
+	path := fmt.Sprintf("/spaces/%s/usage", url.PathEscape(spaceID))
+	query := url.Values{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+	}
+	var out UsageReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSyncedTable 获取同步表及最近一次同步状态
+// GET /synced-tables/{syncId}
+func (c *Client) GetSyncedTable(ctx context.Context, syncID string) (*SyncedTable, error) {
+	path := fmt.Sprintf("/synced-tables/%s", url.PathEscape(syncID))
+	var out SyncedTable
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTableHealthFixJob 获取修复任务
+// GET /health-fix-jobs/{jobId}
+func (c *Client) GetTableHealthFixJob(ctx context.Context, jobID string) (*TableHealthFixJob, error) {
+	path := fmt.Sprintf("/health-fix-jobs/%s", url.PathEscape(jobID))
+	var out TableHealthFixJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTableHealthReport 获取健康检查报告
+// GET /health-reports/{reportId}
+func (c *Client) GetTableHealthReport(ctx context.Context, reportID string) (*TableHealthReport, error) {
+	path := fmt.Sprintf("/health-reports/%s", url.PathEscape(reportID))
+	var out TableHealthReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTableHealthSchedule 获取定时检查设置
+// GET /tables/{tableId}/health-schedule
+func (c *Client) GetTableHealthSchedule(ctx context.Context, tableID string) (*TableHealthSchedule, error) {
+	path := fmt.Sprintf("/tables/%s/health-schedule", url.PathEscape(tableID))
+	var out TableHealthSchedule
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUploadProgress 查询可续传上传的进度
+// GET /attachments/uploads/{uploadId}
+func (c *Client) GetUploadProgress(ctx context.Context, uploadID string) (*UploadProgress, error) {
+	path := fmt.Sprintf("/attachments/uploads/%s", url.PathEscape(uploadID))
+	var out UploadProgress
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetViewWindowParams GetViewWindow 的查询参数（零值表示不传）
+type GetViewWindowParams struct {
+	Offset int
+	Count  int
+}
+
+// GetViewWindow 读取虚拟滚动的行窗口，同时返回与窗口相交的分组边界和总数
+// GET /views/{viewId}/window
+func (c *Client) GetViewWindow(ctx context.Context, viewID string, params *GetViewWindowParams) (*ViewWindow, error) {
+	path := fmt.Sprintf("/views/%s/window", url.PathEscape(viewID))
+	query := url.Values{}
+	if params != nil {
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Count != 0 {
+			query.Set("count", strconv.Itoa(params.Count))
+		}
+	}
+	var out ViewWindow
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWorkspaceBundle 获取导出包，完成后附带清单下载链接（仅管理员）
+// GET /admin/spaces/{spaceId}/lifecycle/bundles/{bundleId}
+func (c *Client) GetWorkspaceBundle(ctx context.Context, spaceID string, bundleID string) (*WorkspaceExportBundle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/bundles/%s", url.PathEscape(spaceID), url.PathEscape(bundleID))
+	var out WorkspaceExportBundle
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWorkspaceLifecycle 获取工作空间的生命周期（仅管理员）
+// GET /admin/spaces/{spaceId}/lifecycle
+func (c *Client) GetWorkspaceLifecycle(ctx context.Context, spaceID string) (*WorkspaceLifecycle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle", url.PathEscape(spaceID))
+	var out WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAIGenerationJobsParams ListAIGenerationJobs 的查询参数（零值表示不传）
+type ListAIGenerationJobsParams struct {
+	RecordID string
+}
+
+// ListAIGenerationJobs 列出 AI 字段最近的生成任务
+// GET /fields/{fieldId}/ai/jobs
+func (c *Client) ListAIGenerationJobs(ctx context.Context, fieldID string, params *ListAIGenerationJobsParams) ([]*AIGenerationJob, error) {
+	path := fmt.Sprintf("/fields/%s/ai/jobs", url.PathEscape(fieldID))
+	query := url.Values{}
+	if params != nil {
+		if params.RecordID != "" {
+			query.Set("recordId", params.RecordID)
+		}
+	}
+	var out []*AIGenerationJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListArchivedRecordsParams ListArchivedRecords 的查询参数（零值表示不传）
+type ListArchivedRecordsParams struct {
+	Limit  int
+	Offset int
+}
+
+// ListArchivedRecords 按归档时间倒序分页读取归档记录（返回存储值）
+// GET /tables/{tableId}/records/archived
+func (c *Client) ListArchivedRecords(ctx context.Context, tableID string, params *ListArchivedRecordsParams) (*RecordPage, error) {
+	path := fmt.Sprintf("/tables/%s/records/archived", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	var out RecordPage
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBaseBranches 列出 Base 的沙盒分支
+// GET /bases/{baseId}/branches
+func (c *Client) ListBaseBranches(ctx context.Context, baseID string) ([]*BaseBranch, error) {
+	path := fmt.Sprintf("/bases/%s/branches", url.PathEscape(baseID))
+	var out []*BaseBranch
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListBases 列出工作空间下的 Base
+// GET /spaces/{spaceId}/bases
+func (c *Client) ListBases(ctx context.Context, spaceID string) ([]*Base, error) {
+	path := fmt.Sprintf("/spaces/%s/bases", url.PathEscape(spaceID))
+	var out []*Base
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListChangesParams ListChanges 的查询参数（零值表示不传）
+type ListChangesParams struct {
+	Cursor  int64
+	Limit   int
+	TableID string
+}
+
+// ListChanges 按游标增量拉取 Base 的记录、字段、视图变更
+// GET /bases/{baseId}/changes
+func (c *Client) ListChanges(ctx context.Context, baseID string, params *ListChangesParams) (*ChangeFeedPage, error) {
+	path := fmt.Sprintf("/bases/%s/changes", url.PathEscape(baseID))
+	query := url.Values{}
+	if params != nil {
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.TableID != "" {
+			query.Set("tableId", params.TableID)
+		}
+	}
+	var out ChangeFeedPage
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListDataExports 列出 Base 最近的数据导出任务
+// GET /bases/{baseId}/exports
+func (c *Client) ListDataExports(ctx context.Context, baseID string) ([]*DataExport, error) {
+	path := fmt.Sprintf("/bases/%s/exports", url.PathEscape(baseID))
+	var out []*DataExport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentJobs 列出模板最近的生成任务
+// GET /document-templates/{templateId}/jobs
+func (c *Client) ListDocumentJobs(ctx context.Context, templateID string) ([]*DocumentJob, error) {
+	path := fmt.Sprintf("/document-templates/%s/jobs", url.PathEscape(templateID))
+	var out []*DocumentJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDocumentTemplates 列出表的文档模板
+// GET /tables/{tableId}/document-templates
+func (c *Client) ListDocumentTemplates(ctx context.Context, tableID string) ([]*DocumentTemplate, error) {
+	path := fmt.Sprintf("/tables/%s/document-templates", url.PathEscape(tableID))
+	var out []*DocumentTemplate
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListExternalSyncRuns 获取最近的同步运行记录
+// GET /external-syncs/{connectorId}/runs
+func (c *Client) ListExternalSyncRuns(ctx context.Context, connectorID string) ([]*ExternalSyncRun, error) {
+	path := fmt.Sprintf("/external-syncs/%s/runs", url.PathEscape(connectorID))
+	var out []*ExternalSyncRun
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListExternalSyncs 列出 Base 的外部数据同步
+// GET /bases/{baseId}/external-syncs
+func (c *Client) ListExternalSyncs(ctx context.Context, baseID string) ([]*ExternalSyncConnector, error) {
+	path := fmt.Sprintf("/bases/%s/external-syncs", url.PathEscape(baseID))
+	var out []*ExternalSyncConnector
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListFieldOptionSchemas 获取全部字段类型的选项 JSON Schema（按字段类型索引）
+// GET /field-option-schemas
+func (c *Client) ListFieldOptionSchemas(ctx context.Context) (map[string]*FieldOptionSchema, error) {
+	path := "/field-option-schemas"
+	var out map[string]*FieldOptionSchema
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListFields 列出表的字段（响应头 X-Schema-Version 为表结构版本，用于请求字段增量）
+// GET /tables/{tableId}/fields
+func (c *Client) ListFields(ctx context.Context, tableID string) ([]*Field, error) {
+	path := fmt.Sprintf("/tables/%s/fields", url.PathEscape(tableID))
+	var out []*Field
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListFieldsDeltaParams ListFieldsDelta 的查询参数（零值表示不传）
+type ListFieldsDeltaParams struct {
+	Since int64
+}
+
+// ListFieldsDelta 获取表结构版本之后变化的字段
+// GET /tables/{tableId}/fields/delta
+func (c *Client) ListFieldsDelta(ctx context.Context, tableID string, params *ListFieldsDeltaParams) (*FieldDelta, error) {
+	path := fmt.Sprintf("/tables/%s/fields/delta", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.Since != 0 {
+			query.Set("since", strconv.FormatInt(params.Since, 10))
+		}
+	}
+	var out FieldDelta
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListHolidayCalendars 列出 Base 的节假日日历
+// GET /bases/{baseId}/holiday-calendars
+func (c *Client) ListHolidayCalendars(ctx context.Context, baseID string) ([]*HolidayCalendar, error) {
+	path := fmt.Sprintf("/bases/%s/holiday-calendars", url.PathEscape(baseID))
+	var out []*HolidayCalendar
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListImpersonationSessionsParams ListImpersonationSessions 的查询参数（零值表示不传）
+type ListImpersonationSessionsParams struct {
+	TargetUserID string
+	Active       bool
+}
+
+// ListImpersonationSessions 列出代入会话（支持人员为自己发起的会话，管理员为全部）
+// GET /impersonation/sessions
+func (c *Client) ListImpersonationSessions(ctx context.Context, params *ListImpersonationSessionsParams) ([]*ImpersonationSession, error) {
+	path := "/impersonation/sessions"
+	query := url.Values{}
+	if params != nil {
+		if params.TargetUserID != "" {
+			query.Set("targetUserId", params.TargetUserID)
+		}
+		if params.Active {
+			query.Set("active", "true")
+		}
+	}
+	var out []*ImpersonationSession
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListPageElementRecordsParams ListPageElementRecords 的查询参数（零值表示不传）
+type ListPageElementRecordsParams struct {
+	Page     int
+	PageSize int
+}
+
+// ListPageElementRecords 分页读取页面元素的记录（只返回元素展示的字段）
+// GET /pages/{pageId}/elements/{elementId}/records
+func (c *Client) ListPageElementRecords(ctx context.Context, pageID string, elementID string, params *ListPageElementRecordsParams) (*PageRecordList, error) {
+	path := fmt.Sprintf("/pages/%s/elements/%s/records", url.PathEscape(pageID), url.PathEscape(elementID))
+	query := url.Values{}
+	if params != nil {
+		if params.Page != 0 {
+			query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.PageSize != 0 {
+			query.Set("pageSize", strconv.Itoa(params.PageSize))
+		}
+	}
+	var out PageRecordList
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPages 列出当前用户可以访问的页面
+// GET /bases/{baseId}/pages
+func (c *Client) ListPages(ctx context.Context, baseID string) ([]*PageSummary, error) {
+	path := fmt.Sprintf("/bases/%s/pages", url.PathEscape(baseID))
+	var out []*PageSummary
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListPlans 列出套餐及其限额
+// GET /billing/plans
+func (c *Client) ListPlans(ctx context.Context) ([]*Plan, error) {
+	path := "/billing/plans"
+	var out []*Plan
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListPrintJobs 列出当前用户在表中最近的打印任务
+// GET /tables/{tableId}/print-jobs
+func (c *Client) ListPrintJobs(ctx context.Context, tableID string) ([]*PrintJob, error) {
+	path := fmt.Sprintf("/tables/%s/print-jobs", url.PathEscape(tableID))
+	var out []*PrintJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListReceivedImpersonations 列出以当前用户身份进行的代入会话
+// GET /impersonation/received
+func (c *Client) ListReceivedImpersonations(ctx context.Context) ([]*ImpersonationSession, error) {
+	path := "/impersonation/received"
+	var out []*ImpersonationSession
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecordBacklinks 列出提及了该记录的来源记录（只包含可查看的记录）
+// GET /tables/{tableId}/records/{recordId}/backlinks
+func (c *Client) ListRecordBacklinks(ctx context.Context, tableID string, recordID string) ([]*RecordBacklink, error) {
+	path := fmt.Sprintf("/tables/%s/records/%s/backlinks", url.PathEscape(tableID), url.PathEscape(recordID))
+	var out []*RecordBacklink
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecordLocksParams ListRecordLocks 的查询参数（零值表示不传）
+type ListRecordLocksParams struct {
+	RecordIds string
+}
+
+// ListRecordLocks 列出表中仍有效的记录锁
+// GET /tables/{tableId}/record-locks
+func (c *Client) ListRecordLocks(ctx context.Context, tableID string, params *ListRecordLocksParams) ([]*RecordLock, error) {
+	path := fmt.Sprintf("/tables/%s/record-locks", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.RecordIds != "" {
+			query.Set("recordIds", params.RecordIds)
+		}
+	}
+	var out []*RecordLock
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecordTemplates 列出表的记录模板（默认模板在前）
+// GET /tables/{tableId}/record-templates
+func (c *Client) ListRecordTemplates(ctx context.Context, tableID string) ([]*RecordTemplate, error) {
+	path := fmt.Sprintf("/tables/%s/record-templates", url.PathEscape(tableID))
+	var out []*RecordTemplate
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecordsParams ListRecords 的查询参数（零值表示不传）
+type ListRecordsParams struct {
+	Page             int
+	PerPage          int
+	IncludeArchived  bool
+	FieldKeyType     string
+	Fields           []string
+	IncludeFormatted bool
+	TimeZone         string
+	Locale           string
+}
+
+// ListRecords 分页获取表中的记录
+// GET /tables/{tableId}/records
+func (c *Client) ListRecords(ctx context.Context, tableID string, params *ListRecordsParams) (*RecordPage, error) {
+	path := fmt.Sprintf("/tables/%s/records", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.Page != 0 {
+			query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.PerPage != 0 {
+			query.Set("perPage", strconv.Itoa(params.PerPage))
+		}
+		if params.IncludeArchived {
+			query.Set("includeArchived", "true")
+		}
+		if params.FieldKeyType != "" {
+			query.Set("fieldKeyType", params.FieldKeyType)
+		}
+		for _, v := range params.Fields {
+			query.Add("fields", v)
+		}
+		if params.IncludeFormatted {
+			query.Set("includeFormatted", "true")
+		}
+		if params.TimeZone != "" {
+			query.Set("timeZone", params.TimeZone)
+		}
+		if params.Locale != "" {
+			query.Set("locale", params.Locale)
+		}
+	}
+	var out RecordPage
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRecordsSnapshotParams ListRecordsSnapshot 的查询参数（零值表示不传）
+type ListRecordsSnapshotParams struct {
+	AsOf   string
+	Cursor int64
+	Limit  int
+}
+
+// ListRecordsSnapshot 按时间点分页读取表记录（导出时各页一致）
+// GET /tables/{tableId}/records/snapshot
+func (c *Client) ListRecordsSnapshot(ctx context.Context, tableID string, params *ListRecordsSnapshotParams) (*RecordSnapshot, error) {
+	path := fmt.Sprintf("/tables/%s/records/snapshot", url.PathEscape(tableID))
+	query := url.Values{}
+	if params != nil {
+		if params.AsOf != "" {
+			query.Set("asOf", params.AsOf)
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out RecordSnapshot
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListRecurrenceRules 列出表的重复记录规则
+// GET /tables/{tableId}/recurrence-rules
+func (c *Client) ListRecurrenceRules(ctx context.Context, tableID string) ([]*RecurrenceRule, error) {
+	path := fmt.Sprintf("/tables/%s/recurrence-rules", url.PathEscape(tableID))
+	var out []*RecurrenceRule
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecurrenceRuns 列出重复规则最近的执行记录
+// GET /recurrence-rules/{ruleId}/runs
+func (c *Client) ListRecurrenceRuns(ctx context.Context, ruleID string) ([]*RecurrenceRun, error) {
+	path := fmt.Sprintf("/recurrence-rules/%s/runs", url.PathEscape(ruleID))
+	var out []*RecurrenceRun
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListScanRules 列出表的扫码规则
+// GET /tables/{tableId}/scan-rules
+func (c *Client) ListScanRules(ctx context.Context, tableID string) ([]*ScanRule, error) {
+	path := fmt.Sprintf("/tables/%s/scan-rules", url.PathEscape(tableID))
+	var out []*ScanRule
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListServiceTokens 列出当前用户的服务令牌
+// GET /auth/service-tokens
+func (c *Client) ListServiceTokens(ctx context.Context) ([]*ServiceToken, error) {
+	path := "/auth/service-tokens"
+	var out []*ServiceToken
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSpaces 列出当前用户的工作空间
+// GET /spaces
+func (c *Client) ListSpaces(ctx context.Context) ([]*Space, error) {
+	path := "/spaces"
+	var out []*Space
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListStatusFlows 列出表中单选字段的状态流
+// GET /tables/{tableId}/status-flows
+func (c *Client) ListStatusFlows(ctx context.Context, tableID string) ([]*StatusFlow, error) {
+	path := fmt.Sprintf("/tables/%s/status-flows", url.PathEscape(tableID))
+	var out []*StatusFlow
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListSyncedTables 列出镜像到该 Base 的同步表
+// GET /bases/{baseId}/synced-tables
+func (c *Client) ListSyncedTables(ctx context.Context, baseID string) ([]*SyncedTable, error) {
+	path := fmt.Sprintf("/bases/%s/synced-tables", url.PathEscape(baseID))
+	var out []*SyncedTable
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTableHealthIssuesParams ListTableHealthIssues 的查询参数（零值表示不传）
+type ListTableHealthIssuesParams struct {
+	Kind    string
+	FieldID string
+	Status  string
+	Limit   int
+	Offset  int
+}
+
+// ListTableHealthIssues 分页列出报告中的问题
+// GET /health-reports/{reportId}/issues
+func (c *Client) ListTableHealthIssues(ctx context.Context, reportID string, params *ListTableHealthIssuesParams) (*TableHealthIssueList, error) {
+	path := fmt.Sprintf("/health-reports/%s/issues", url.PathEscape(reportID))
+	query := url.Values{}
+	if params != nil {
+		if params.Kind != "" {
+			query.Set("kind", params.Kind)
+		}
+		if params.FieldID != "" {
+			query.Set("fieldId", params.FieldID)
+		}
+		if params.Status != "" {
+			query.Set("status", params.Status)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+	}
+	var out TableHealthIssueList
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListTableHealthReports 列出表最近的健康检查报告（需要表结构管理权限）
+// GET /tables/{tableId}/health-reports
+func (c *Client) ListTableHealthReports(ctx context.Context, tableID string) ([]*TableHealthReport, error) {
+	path := fmt.Sprintf("/tables/%s/health-reports", url.PathEscape(tableID))
+	var out []*TableHealthReport
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTables 列出 Base 下的表
+// GET /bases/{baseId}/tables
+func (c *Client) ListTables(ctx context.Context, baseID string) ([]*Table, error) {
+	path := fmt.Sprintf("/bases/%s/tables", url.PathEscape(baseID))
+	var out []*Table
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTextExtractionJobsParams ListTextExtractionJobs 的查询参数（零值表示不传）
+type ListTextExtractionJobsParams struct {
+	RecordID string
+}
+
+// ListTextExtractionJobs 列出提取规则最近的任务
+// GET /text-extraction-rules/{ruleId}/jobs
+func (c *Client) ListTextExtractionJobs(ctx context.Context, ruleID string, params *ListTextExtractionJobsParams) ([]*TextExtractionJob, error) {
+	path := fmt.Sprintf("/text-extraction-rules/%s/jobs", url.PathEscape(ruleID))
+	query := url.Values{}
+	if params != nil {
+		if params.RecordID != "" {
+			query.Set("recordId", params.RecordID)
+		}
+	}
+	var out []*TextExtractionJob
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListTextExtractionRules 列出表的附件文本提取规则
+// GET /tables/{tableId}/text-extraction-rules
+func (c *Client) ListTextExtractionRules(ctx context.Context, tableID string) ([]*TextExtractionRule, error) {
+	path := fmt.Sprintf("/tables/%s/text-extraction-rules", url.PathEscape(tableID))
+	var out []*TextExtractionRule
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListViewDisplayRowsParams ListViewDisplayRows 的查询参数（零值表示不传）
+type ListViewDisplayRowsParams struct {
+	Offset int
+	Limit  int
+}
+
+// ListViewDisplayRows 按视图顺序分页获取展示就绪的行（选项、协作者与关联标题已解析）
+// GET /views/{viewId}/display-rows
+func (c *Client) ListViewDisplayRows(ctx context.Context, viewID string, params *ListViewDisplayRowsParams) (*ViewDisplayPage, error) {
+	path := fmt.Sprintf("/views/%s/display-rows", url.PathEscape(viewID))
+	query := url.Values{}
+	if params != nil {
+		if params.Offset != 0 {
+			query.Set("offset", strconv.Itoa(params.Offset))
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.Itoa(params.Limit))
+		}
+	}
+	var out ViewDisplayPage
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListWorkspaceBundles 列出工作空间的完整导出包（仅管理员）
+// GET /admin/spaces/{spaceId}/lifecycle/bundles
+func (c *Client) ListWorkspaceBundles(ctx context.Context, spaceID string) ([]*WorkspaceExportBundle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/bundles", url.PathEscape(spaceID))
+	var out []*WorkspaceExportBundle
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWorkspaceLifecyclesParams ListWorkspaceLifecycles 的查询参数（零值表示不传）
+type ListWorkspaceLifecyclesParams struct {
+	State string
+}
+
+// ListWorkspaceLifecycles 列出变更过状态的工作空间（仅管理员）
+// GET /admin/workspace-lifecycles
+func (c *Client) ListWorkspaceLifecycles(ctx context.Context, params *ListWorkspaceLifecyclesParams) ([]*WorkspaceLifecycle, error) {
+	path := "/admin/workspace-lifecycles"
+	query := url.Values{}
+	if params != nil {
+		if params.State != "" {
+			query.Set("state", params.State)
+		}
+	}
+	var out []*WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LockRecord 锁定记录或其中部分字段（重复锁定时更新自己的锁，与他人的锁重叠时返回 RECORD_LOCKED）
+// POST /tables/{tableId}/records/{recordId}/lock
+func (c *Client) LockRecord(ctx context.Context, tableID string, recordID string, body *LockRecordRequest) (*RecordLock, error) {
+	path := fmt.Sprintf("/tables/%s/records/%s/lock", url.PathEscape(tableID), url.PathEscape(recordID))
+	var out RecordLock
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Login 使用邮箱和密码登录
+// POST /auth/login
+func (c *Client) Login(ctx context.Context, body *LoginRequest) (*LoginResponse, error) {
+	path := "/auth/login"
+	var out LoginResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// NLQuery 用自然语言查询记录（生成视图过滤与排序条件后执行，结果按当前角色脱敏）
+// POST /tables/{tableId}/nl-query
+func (c *Client) NLQuery(ctx context.Context, tableID string, body *NLQueryRequest) (*NLQueryResult, error) {
+	path := fmt.Sprintf("/tables/%s/nl-query", url.PathEscape(tableID))
+	var out NLQueryResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PointInTimeRestore 将记录或一列恢复到指定时间点（需要记录编辑权限，逐条写入并检查版本）
+// POST /tables/{tableId}/point-in-time-restore
+func (c *Client) PointInTimeRestore(ctx context.Context, tableID string, body *PointInTimeRestoreRequest) (*PointInTimeRestoreResult, error) {
+	path := fmt.Sprintf("/tables/%s/point-in-time-restore", url.PathEscape(tableID))
+	var out PointInTimeRestoreResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewFindReplace 预览批量查找替换的匹配统计与示例（替换与撤销以 find_replace / find_replace_undo 长时操作发起）
+// POST /tables/{tableId}/find-replace/preview
+func (c *Client) PreviewFindReplace(ctx context.Context, tableID string, body *FindReplaceSpec) (*FindReplacePreview, error) {
+	path := fmt.Sprintf("/tables/%s/find-replace/preview", url.PathEscape(tableID))
+	var out FindReplacePreview
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewPermalink 永久链接预览（标题与关键字段）
+// GET /permalinks/{code}/preview
+func (c *Client) PreviewPermalink(ctx context.Context, code string) (*PermalinkPreview, error) {
+	path := fmt.Sprintf("/permalinks/%s/preview", url.PathEscape(code))
+	var out PermalinkPreview
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewPointInTimeRestore 预览将记录或一列恢复到指定时间点时写入的差异
+// POST /tables/{tableId}/point-in-time-restore/preview
+func (c *Client) PreviewPointInTimeRestore(ctx context.Context, tableID string, body *PointInTimeRestoreRequest) (*PointInTimeRestorePreview, error) {
+	path := fmt.Sprintf("/tables/%s/point-in-time-restore/preview", url.PathEscape(tableID))
+	var out PointInTimeRestorePreview
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PreviewRecurrenceRuleParams PreviewRecurrenceRule 的查询参数（零值表示不传）
+type PreviewRecurrenceRuleParams struct {
+	Count int
+}
+
+// PreviewRecurrenceRule 预览重复规则接下来的执行（含被跳过的执行及原因）
+// GET /recurrence-rules/{ruleId}/preview
+func (c *Client) PreviewRecurrenceRule(ctx context.Context, ruleID string, params *PreviewRecurrenceRuleParams) ([]*RecurrenceOccurrence, error) {
+	path := fmt.Sprintf("/recurrence-rules/%s/preview", url.PathEscape(ruleID))
+	query := url.Values{}
+	if params != nil {
+		if params.Count != 0 {
+			query.Set("count", strconv.Itoa(params.Count))
+		}
+	}
+	var out []*RecurrenceOccurrence
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PromoteBaseBranch 发起合并到生产（长时操作，存在冲突时返回 409）
+// POST /base-branches/{branchId}/promote
+func (c *Client) PromoteBaseBranch(ctx context.Context, branchID string) (*Operation, error) {
+	path := fmt.Sprintf("/base-branches/%s/promote", url.PathEscape(branchID))
+	var out Operation
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PromoteDisasterRecovery 提升备区域：主区域可达时先最终同步，之后停止复制（仅管理员）
+// POST /admin/disaster-recovery/promote
+func (c *Client) PromoteDisasterRecovery(ctx context.Context, body *PromoteRequest) (*DRPromotion, error) {
+	path := "/admin/disaster-recovery/promote"
+	var out DRPromotion
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PushOfflineMutations 按顺序推送离线期间的修改，逐条返回写入、冲突或失败结果
+// POST /bases/{baseId}/offline-sync/push
+func (c *Client) PushOfflineMutations(ctx context.Context, baseID string, body *OfflinePushRequest) (*OfflinePushResult, error) {
+	path := fmt.Sprintf("/bases/%s/offline-sync/push", url.PathEscape(baseID))
+	var out OfflinePushResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PutSemanticIndex 创建或修改表的语义索引（需要表结构管理权限，配置变化时重新生成全部向量）
+// PUT /tables/{tableId}/semantic-index
+func (c *Client) PutSemanticIndex(ctx context.Context, tableID string, body *PutSemanticIndexRequest) (*SemanticIndex, error) {
+	path := fmt.Sprintf("/tables/%s/semantic-index", url.PathEscape(tableID))
+	var out SemanticIndex
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的访问令牌
+// POST /auth/refresh
+func (c *Client) RefreshToken(ctx context.Context, body *RefreshTokenRequest) (*TokenResponse, error) {
+	path := "/auth/refresh"
+	var out TokenResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RegenerateAIField 重新生成 AI 字段（需要记录编辑权限，未指定记录时重新生成整列）
+// POST /fields/{fieldId}/ai/regenerate
+func (c *Client) RegenerateAIField(ctx context.Context, fieldID string, body *RegenerateAIFieldRequest) (*RegenerateAIFieldResult, error) {
+	path := fmt.Sprintf("/fields/%s/ai/regenerate", url.PathEscape(fieldID))
+	var out RegenerateAIFieldResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RenderPage 获取页面布局与各元素展示的字段（按分享规则校验）
+// GET /pages/{pageId}/render
+func (c *Client) RenderPage(ctx context.Context, pageID string) (*PageRender, error) {
+	path := fmt.Sprintf("/pages/%s/render", url.PathEscape(pageID))
+	var out PageRender
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReorderFields 按给定顺序一次性重排表中的全部字段（返回按新顺序排列的字段）
+// PATCH /tables/{tableId}/fields/order
+func (c *Client) ReorderFields(ctx context.Context, tableID string, body *ReorderFieldsRequest) ([]*Field, error) {
+	path := fmt.Sprintf("/tables/%s/fields/order", url.PathEscape(tableID))
+	var out []*Field
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RescanAttachment 将附件重新置为待扫描（仅空间所有者）
+// POST /attachments/{attachmentId}/rescan
+func (c *Client) RescanAttachment(ctx context.Context, attachmentID string) (*AttachmentInfo, error) {
+	path := fmt.Sprintf("/attachments/%s/rescan", url.PathEscape(attachmentID))
+	var out AttachmentInfo
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResetBusinessCalendar 删除工作日历，恢复默认日历（需要空间管理权限）
+// DELETE /spaces/{spaceId}/business-calendar
+func (c *Client) ResetBusinessCalendar(ctx context.Context, spaceID string) (*BusinessCalendar, error) {
+	path := fmt.Sprintf("/spaces/%s/business-calendar", url.PathEscape(spaceID))
+	var out BusinessCalendar
+	if err := c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResolvePermalink 解析永久链接，返回目标当前的位置（重新校验访问权限）
+// GET /permalinks/{code}
+func (c *Client) ResolvePermalink(ctx context.Context, code string) (*PermalinkLocation, error) {
+	path := fmt.Sprintf("/permalinks/%s", url.PathEscape(code))
+	var out PermalinkLocation
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResolveRecordTemplate 获取模板解析占位符后的值（用于预填新建表单）
+// GET /record-templates/{templateId}/values
+func (c *Client) ResolveRecordTemplate(ctx context.Context, templateID string) (Fields, error) {
+	path := fmt.Sprintf("/record-templates/%s/values", url.PathEscape(templateID))
+	var out Fields
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RestoreArchivedRecords 将归档记录恢复到表中（保留原记录ID与版本）
+// POST /tables/{tableId}/records/archived/restore
+func (c *Client) RestoreArchivedRecords(ctx context.Context, tableID string, body *RestoreArchivedRequest) (*RestoreArchivedResult, error) {
+	path := fmt.Sprintf("/tables/%s/records/archived/restore", url.PathEscape(tableID))
+	var out RestoreArchivedResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResumeWorkspace 恢复已停用的工作空间（仅管理员）
+// POST /admin/spaces/{spaceId}/lifecycle/resume
+func (c *Client) ResumeWorkspace(ctx context.Context, spaceID string) (*WorkspaceLifecycle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/resume", url.PathEscape(spaceID))
+	var out WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeServiceToken 吊销服务令牌
+// DELETE /auth/service-tokens/{tokenId}
+func (c *Client) RevokeServiceToken(ctx context.Context, tokenID string) error {
+	path := fmt.Sprintf("/auth/service-tokens/%s", url.PathEscape(tokenID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// RunArchivePolicy 按归档策略立即执行一次归档
+// POST /tables/{tableId}/archive-policy/run
+func (c *Client) RunArchivePolicy(ctx context.Context, tableID string) (*ArchiveRunResult, error) {
+	path := fmt.Sprintf("/tables/%s/archive-policy/run", url.PathEscape(tableID))
+	var out ArchiveRunResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunPageButton 点击页面按钮，触发绑定的工作流（需要 interact 访问级别）
+// POST /pages/{pageId}/elements/{elementId}/run
+func (c *Client) RunPageButton(ctx context.Context, pageID string, elementID string, body *RunPageButtonRequest) (*PageButtonRun, error) {
+	path := fmt.Sprintf("/pages/%s/elements/%s/run", url.PathEscape(pageID), url.PathEscape(elementID))
+	var out PageButtonRun
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunTableHealthCheck 发起健康检查（已有未结束的检查时返回该检查）
+// POST /tables/{tableId}/health-reports
+func (c *Client) RunTableHealthCheck(ctx context.Context, tableID string) (*TableHealthReport, error) {
+	path := fmt.Sprintf("/tables/%s/health-reports", url.PathEscape(tableID))
+	var out TableHealthReport
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunTextExtraction 手动执行文本提取（不指定记录时处理整表）
+// POST /text-extraction-rules/{ruleId}/run
+func (c *Client) RunTextExtraction(ctx context.Context, ruleID string, body *RunTextExtractionRequest) (*RunTextExtractionResponse, error) {
+	path := fmt.Sprintf("/text-extraction-rules/%s/run", url.PathEscape(ruleID))
+	var out RunTextExtractionResponse
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveArchivePolicy 创建或更新表的归档策略（需要表结构管理权限）
+// PUT /tables/{tableId}/archive-policy
+func (c *Client) SaveArchivePolicy(ctx context.Context, tableID string, body *SaveArchivePolicyRequest) (*ArchivePolicy, error) {
+	path := fmt.Sprintf("/tables/%s/archive-policy", url.PathEscape(tableID))
+	var out ArchivePolicy
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveBusinessCalendar 保存工作空间的工作日历（需要空间管理权限）
+// PUT /spaces/{spaceId}/business-calendar
+func (c *Client) SaveBusinessCalendar(ctx context.Context, spaceID string, body *SaveBusinessCalendarRequest) (*BusinessCalendar, error) {
+	path := fmt.Sprintf("/spaces/%s/business-calendar", url.PathEscape(spaceID))
+	var out BusinessCalendar
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveScanRule 创建或更新字段的扫码规则（需要表结构管理权限）
+// PUT /tables/{tableId}/scan-rules/{fieldId}
+func (c *Client) SaveScanRule(ctx context.Context, tableID string, fieldID string, body *SaveScanRuleRequest) (*ScanRule, error) {
+	path := fmt.Sprintf("/tables/%s/scan-rules/%s", url.PathEscape(tableID), url.PathEscape(fieldID))
+	var out ScanRule
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveStatusFlow 创建或更新单选字段的状态流（需要表结构管理权限）
+// PUT /tables/{tableId}/status-flows/{fieldId}
+func (c *Client) SaveStatusFlow(ctx context.Context, tableID string, fieldID string, body *SaveStatusFlowRequest) (*StatusFlow, error) {
+	path := fmt.Sprintf("/tables/%s/status-flows/%s", url.PathEscape(tableID), url.PathEscape(fieldID))
+	var out StatusFlow
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ScanRecord 按扫到的编码查找记录（未找到时按字段的扫码规则新建）
+// POST /tables/{tableId}/scans
+func (c *Client) ScanRecord(ctx context.Context, tableID string, body *ScanRequest) (*ScanResult, error) {
+	path := fmt.Sprintf("/tables/%s/scans", url.PathEscape(tableID))
+	var out ScanResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ScheduleWorkspaceDeletion 计划删除工作空间：宽限期内只读，结束后导出并清除全部数据（仅管理员）
+// POST /admin/spaces/{spaceId}/lifecycle/schedule-deletion
+func (c *Client) ScheduleWorkspaceDeletion(ctx context.Context, spaceID string, body *ScheduleWorkspaceDeletionRequest) (*WorkspaceLifecycle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/schedule-deletion", url.PathEscape(spaceID))
+	var out WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SemanticSearch 搜索表中的记录（语义检索与关键词匹配融合排序，结果按当前角色脱敏）
+// POST /tables/{tableId}/semantic-search
+func (c *Client) SemanticSearch(ctx context.Context, tableID string, body *SemanticSearchRequest) (*SemanticSearchResult, error) {
+	path := fmt.Sprintf("/tables/%s/semantic-search", url.PathEscape(tableID))
+	var out SemanticSearchResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartImpersonation 以用户身份开始限时代入（仅支持人员，须使用登录令牌；被代入用户会收到通知）
+// POST /impersonation/sessions
+func (c *Client) StartImpersonation(ctx context.Context, body *StartImpersonationRequest) (*StartedImpersonation, error) {
+	path := "/impersonation/sessions"
+	var out StartedImpersonation
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartMaintenance 将实例、工作空间或 Base 置为只读（仅管理员），通过 maintenance.update 事件通知客户端
+// POST /admin/maintenance
+func (c *Client) StartMaintenance(ctx context.Context, body *StartMaintenanceRequest) (*MaintenanceWindow, error) {
+	path := "/admin/maintenance"
+	var out MaintenanceWindow
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartOperation 发起长时操作（导入等），立即返回等待执行的操作
+// POST /bases/{baseId}/operations
+func (c *Client) StartOperation(ctx context.Context, baseID string, body *StartOperationRequest) (*Operation, error) {
+	path := fmt.Sprintf("/bases/%s/operations", url.PathEscape(baseID))
+	var out Operation
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SuspendWorkspace 停用工作空间，停用后只允许读取（仅管理员）
+// POST /admin/spaces/{spaceId}/lifecycle/suspend
+func (c *Client) SuspendWorkspace(ctx context.Context, spaceID string, body *SuspendWorkspaceRequest) (*WorkspaceLifecycle, error) {
+	path := fmt.Sprintf("/admin/spaces/%s/lifecycle/suspend", url.PathEscape(spaceID))
+	var out WorkspaceLifecycle
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TestRunScript 在沙箱中试运行自动化脚本，返回结果、输出与日志（以当前用户身份读写记录）
+// POST /automation-scripts/run
+func (c *Client) TestRunScript(ctx context.Context, body *RunScriptRequest) (*ScriptRunResult, error) {
+	path := "/automation-scripts/run"
+	var out ScriptRunResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TriggerExternalSync 立即同步一次（在下一个轮询周期执行）
+// POST /external-syncs/{connectorId}/run
+func (c *Client) TriggerExternalSync(ctx context.Context, connectorID string) (*ExternalSyncConnector, error) {
+	path := fmt.Sprintf("/external-syncs/%s/run", url.PathEscape(connectorID))
+	var out ExternalSyncConnector
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// TriggerSyncedTable 立即同步一次（在下一个轮询周期执行）
+// POST /synced-tables/{syncId}/run
+func (c *Client) TriggerSyncedTable(ctx context.Context, syncID string) (*SyncedTable, error) {
+	path := fmt.Sprintf("/synced-tables/%s/run", url.PathEscape(syncID))
+	var out SyncedTable
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnfurlPermalinkParams UnfurlPermalink 的查询参数（零值表示不传）
+type UnfurlPermalinkParams struct {
+	URL string
+}
+
+// UnfurlPermalink 按完整链接获取预览（供聊天工具集成展开消息中的链接）
+// GET /permalinks/unfurl
+func (c *Client) UnfurlPermalink(ctx context.Context, params *UnfurlPermalinkParams) (*PermalinkPreview, error) {
+	path := "/permalinks/unfurl"
+	query := url.Values{}
+	if params != nil {
+		if params.URL != "" {
+			query.Set("url", params.URL)
+		}
+	}
+	var out PermalinkPreview
+	if err := c.do(ctx, request{method: http.MethodGet, path: path, query: query, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnlockRecord 解锁（锁的持有者或表结构管理者）
+// DELETE /tables/{tableId}/record-locks/{lockId}
+func (c *Client) UnlockRecord(ctx context.Context, tableID string, lockID string) error {
+	path := fmt.Sprintf("/tables/%s/record-locks/%s", url.PathEscape(tableID), url.PathEscape(lockID))
+	return c.do(ctx, request{method: http.MethodDelete, path: path, auth: true}, nil)
+}
+
+// UpdateAIQuota 更新工作空间 AI 配额（仅空间所有者）
+// PUT /spaces/{spaceId}/ai-quota
+func (c *Client) UpdateAIQuota(ctx context.Context, spaceID string, body *UpdateAIQuotaRequest) (*AIQuotaReport, error) {
+	path := fmt.Sprintf("/spaces/%s/ai-quota", url.PathEscape(spaceID))
+	var out AIQuotaReport
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAttachmentPolicy 更新工作空间附件策略（仅空间所有者）
+// PUT /spaces/{spaceId}/attachment-policy
+func (c *Client) UpdateAttachmentPolicy(ctx context.Context, spaceID string, body *UpdateAttachmentPolicyRequest) (*AttachmentPolicy, error) {
+	path := fmt.Sprintf("/spaces/%s/attachment-policy", url.PathEscape(spaceID))
+	var out AttachmentPolicy
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateDocumentTemplate 更新文档模板（需要表结构管理权限）
+// PATCH /document-templates/{templateId}
+func (c *Client) UpdateDocumentTemplate(ctx context.Context, templateID string, body *UpdateDocumentTemplateRequest) (*DocumentTemplate, error) {
+	path := fmt.Sprintf("/document-templates/%s", url.PathEscape(templateID))
+	var out DocumentTemplate
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateExternalSync 更新外部数据同步名称、凭据、间隔或启停
+// PATCH /external-syncs/{connectorId}
+func (c *Client) UpdateExternalSync(ctx context.Context, connectorID string, body *UpdateExternalSyncRequest) (*ExternalSyncConnector, error) {
+	path := fmt.Sprintf("/external-syncs/%s", url.PathEscape(connectorID))
+	var out ExternalSyncConnector
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateHolidayCalendar 更新节假日日历（从各规则的下一次执行起生效）
+// PATCH /holiday-calendars/{calendarId}
+func (c *Client) UpdateHolidayCalendar(ctx context.Context, calendarID string, body *UpdateHolidayCalendarRequest) (*HolidayCalendar, error) {
+	path := fmt.Sprintf("/holiday-calendars/%s", url.PathEscape(calendarID))
+	var out HolidayCalendar
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePage 更新页面名称、元素与分享规则
+// PATCH /pages/{pageId}
+func (c *Client) UpdatePage(ctx context.Context, pageID string, body *UpdatePageRequest) (*Page, error) {
+	path := fmt.Sprintf("/pages/%s", url.PathEscape(pageID))
+	var out Page
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdatePageElementRecord 通过页面元素更新记录（只能更新允许编辑的字段，需要 edit 访问级别）
+// PATCH /pages/{pageId}/elements/{elementId}/records/{recordId}
+func (c *Client) UpdatePageElementRecord(ctx context.Context, pageID string, elementID string, recordID string, body *UpdatePageRecordRequest) (*Record, error) {
+	path := fmt.Sprintf("/pages/%s/elements/%s/records/%s", url.PathEscape(pageID), url.PathEscape(elementID), url.PathEscape(recordID))
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRecordParams UpdateRecord 的查询参数（零值表示不传）
+type UpdateRecordParams struct {
+	FieldKeyType string
+}
+
+// UpdateRecord 更新记录（只更新提供的字段）
+// PATCH /tables/{tableId}/records/{recordId}
+func (c *Client) UpdateRecord(ctx context.Context, tableID string, recordID string, params *UpdateRecordParams, body *UpdateRecordRequest) (*Record, error) {
+	path := fmt.Sprintf("/tables/%s/records/%s", url.PathEscape(tableID), url.PathEscape(recordID))
+	query := url.Values{}
+	if params != nil {
+		if params.FieldKeyType != "" {
+			query.Set("fieldKeyType", params.FieldKeyType)
+		}
+	}
+	var out Record
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, query: query, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRecordTemplate 更新记录模板（需要表结构管理权限）
+// PATCH /record-templates/{templateId}
+func (c *Client) UpdateRecordTemplate(ctx context.Context, templateID string, body *UpdateRecordTemplateRequest) (*RecordTemplate, error) {
+	path := fmt.Sprintf("/record-templates/%s", url.PathEscape(templateID))
+	var out RecordTemplate
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateRecurrenceRule 更新重复记录规则（需要表结构管理权限）
+// PATCH /recurrence-rules/{ruleId}
+func (c *Client) UpdateRecurrenceRule(ctx context.Context, ruleID string, body *UpdateRecurrenceRuleRequest) (*RecurrenceRule, error) {
+	path := fmt.Sprintf("/recurrence-rules/%s", url.PathEscape(ruleID))
+	var out RecurrenceRule
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSyncedTable 更新同步表名称、间隔或启停
+// PATCH /synced-tables/{syncId}
+func (c *Client) UpdateSyncedTable(ctx context.Context, syncID string, body *UpdateSyncedTableRequest) (*SyncedTable, error) {
+	path := fmt.Sprintf("/synced-tables/%s", url.PathEscape(syncID))
+	var out SyncedTable
+	if err := c.do(ctx, request{method: http.MethodPatch, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTableHealthSchedule 更新定时检查设置（下次检查从现在起计算）
+// PUT /tables/{tableId}/health-schedule
+func (c *Client) UpdateTableHealthSchedule(ctx context.Context, tableID string, body *UpdateTableHealthScheduleRequest) (*TableHealthSchedule, error) {
+	path := fmt.Sprintf("/tables/%s/health-schedule", url.PathEscape(tableID))
+	var out TableHealthSchedule
+	if err := c.do(ctx, request{method: http.MethodPut, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ValidateCells 按字段类型、选项与约束批量校验单元格值（不写入），逐单元格返回错误
+// POST /tables/{tableId}/records/validate
+func (c *Client) ValidateCells(ctx context.Context, tableID string, body *ValidateCellsRequest) (*ValidateCellsResult, error) {
+	path := fmt.Sprintf("/tables/%s/records/validate", url.PathEscape(tableID))
+	var out ValidateCellsResult
+	if err := c.do(ctx, request{method: http.MethodPost, path: path, body: body, auth: true}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
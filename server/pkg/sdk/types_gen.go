@@ -0,0 +1,2478 @@
+// Code generated by sdkgen from api/openapi.yaml. DO NOT EDIT.
+
+package sdk
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AIGenerationJob AI 字段生成任务（一个任务对应一个单元格）
+type AIGenerationJob struct {
+	ID       string `json:"id,omitempty"`
+	SpaceID  string `json:"space_id,omitempty"`
+	TableID  string `json:"table_id,omitempty"`
+	FieldID  string `json:"field_id,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	// create、source_changed 或 manual
+	Trigger string `json:"trigger,omitempty"`
+	// pending、running、succeeded 或 failed
+	Status   string `json:"status,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// 引用字段均为空而直接清空单元格时为空
+	Provider         string     `json:"provider,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	PromptTokens     int        `json:"prompt_tokens,omitempty"`
+	CompletionTokens int        `json:"completion_tokens,omitempty"`
+	CreatedBy        string     `json:"created_by,omitempty"`
+	NextRunAt        time.Time  `json:"next_run_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at,omitempty"`
+	UpdatedAt        time.Time  `json:"updated_at,omitempty"`
+	FinishedAt       *time.Time `json:"finished_at,omitempty"`
+}
+
+// AIQuota 工作空间 AI 配额，0 表示不限制
+type AIQuota struct {
+	SpaceID             string    `json:"space_id,omitempty"`
+	MonthlyRequests     int64     `json:"monthly_requests,omitempty"`
+	MonthlyTokens       int64     `json:"monthly_tokens,omitempty"`
+	MaxTokensPerRequest int       `json:"max_tokens_per_request,omitempty"`
+	UpdatedBy           string    `json:"updated_by,omitempty"`
+	UpdatedTime         time.Time `json:"updated_time,omitempty"`
+}
+
+// AIQuotaReport 对应 api/openapi.yaml 中的 AIQuotaReport
+type AIQuotaReport struct {
+	Quota *AIQuota `json:"quota,omitempty"`
+	Usage *AIUsage `json:"usage,omitempty"`
+	// 服务端已配置的服务商名称
+	Providers []string `json:"providers,omitempty"`
+}
+
+// AIUsage 对应 api/openapi.yaml 中的 AIUsage
+type AIUsage struct {
+	PeriodStart time.Time `json:"period_start,omitempty"`
+	Requests    int64     `json:"requests,omitempty"`
+	Tokens      int64     `json:"tokens,omitempty"`
+}
+
+// ArchivePolicy 表的记录归档策略（定期将早于阈值的记录移入冷存储，默认查询不再返回）
+type ArchivePolicy struct {
+	ID           string     `json:"id,omitempty"`
+	TableID      string     `json:"table_id,omitempty"`
+	Basis        string     `json:"basis,omitempty"`
+	AfterDays    int        `json:"after_days,omitempty"`
+	Enabled      bool       `json:"enabled,omitempty"`
+	NextRunAt    time.Time  `json:"next_run_at,omitempty"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastArchived int        `json:"last_archived,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	CreatedBy    string     `json:"created_by,omitempty"`
+	UpdatedBy    string     `json:"updated_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at,omitempty"`
+}
+
+// ArchiveRunResult 对应 api/openapi.yaml 中的 ArchiveRunResult
+type ArchiveRunResult struct {
+	Archived int `json:"archived,omitempty"`
+	// 达到单次上限，仍有待归档的记录
+	Remaining bool `json:"remaining,omitempty"`
+}
+
+// AssignPlanRequest 对应 api/openapi.yaml 中的 AssignPlanRequest
+type AssignPlanRequest struct {
+	PlanID string `json:"plan_id"`
+}
+
+// AttachmentInfo 附件信息与上传后处理状态
+type AttachmentInfo struct {
+	ID       string `json:"id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	Mimetype string `json:"mimetype,omitempty"`
+	TableID  string `json:"table_id,omitempty"`
+	// pending、clean、rejected 或 infected，仅 clean 可下载
+	ScanStatus string `json:"scan_status,omitempty"`
+	// 拒绝原因或病毒特征名
+	ScanDetail  string     `json:"scan_detail,omitempty"`
+	ScannedTime *time.Time `json:"scanned_time,omitempty"`
+	CreatedTime time.Time  `json:"created_time,omitempty"`
+}
+
+// AttachmentPolicy 工作空间附件策略，在全局上传限制之上按嗅探出的实际类型校验
+type AttachmentPolicy struct {
+	SpaceID string `json:"space_id,omitempty"`
+	// 0 表示不额外限制
+	MaxFileSizeMb int `json:"max_file_size_mb,omitempty"`
+	// 允许的 MIME 类型，支持 image/* 形式；为空表示不限制
+	AllowedTypes      []string `json:"allowed_types,omitempty"`
+	BlockedExtensions []string `json:"blocked_extensions,omitempty"`
+	// 未配置扫描引擎时附件保持隔离
+	ScanRequired bool `json:"scan_required,omitempty"`
+	// attachment.infected 事件推送地址
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// none、clamav 或 icap
+	ScanEngine string `json:"scan_engine,omitempty"`
+	// 事件签名密钥，仅首次设置推送地址时返回
+	WebhookSecret string    `json:"webhook_secret,omitempty"`
+	UpdatedBy     string    `json:"updated_by,omitempty"`
+	UpdatedTime   time.Time `json:"updated_time,omitempty"`
+}
+
+// Base 对应 api/openapi.yaml 中的 Base
+type Base struct {
+	ID        string    `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Icon      string    `json:"icon,omitempty"`
+	SpaceID   string    `json:"spaceId,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// BaseBranch Base 沙盒分支，sandbox_base_id 为可自由修改的沙盒 Base
+type BaseBranch struct {
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	SourceBaseID  string `json:"source_base_id,omitempty"`
+	SandboxBaseID string `json:"sandbox_base_id,omitempty"`
+	// active 或 discarded
+	Status          string     `json:"status,omitempty"`
+	LastOperationID string     `json:"last_operation_id,omitempty"`
+	PromotedAt      *time.Time `json:"promoted_at,omitempty"`
+	CreatedBy       string     `json:"created_by,omitempty"`
+	CreatedAt       time.Time  `json:"created_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at,omitempty"`
+}
+
+// BatchCreateRecordsRequest 对应 api/openapi.yaml 中的 BatchCreateRecordsRequest
+type BatchCreateRecordsRequest struct {
+	Records []*RecordCreateItem `json:"records"`
+}
+
+// BatchCreateRecordsResponse 对应 api/openapi.yaml 中的 BatchCreateRecordsResponse
+type BatchCreateRecordsResponse struct {
+	Records      []*Record `json:"records,omitempty"`
+	SuccessCount int       `json:"successCount,omitempty"`
+	FailedCount  int       `json:"failedCount,omitempty"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+// BusinessCalendar 工作空间工作日历（未配置时为默认日历：周六、周日休息），供 WORKDAY/NETWORKDAYS、模板工作日偏移（如 @today+3bd）与 isOverdueWorkdays 过滤使用
+type BusinessCalendar struct {
+	SpaceID string `json:"space_id,omitempty"`
+	// 每周休息日，0 为周日 … 6 为周六
+	Weekends []int `json:"weekends,omitempty"`
+	// 节假日（YYYY-MM-DD）
+	Holidays []string `json:"holidays,omitempty"`
+	// 调休上班的日期（YYYY-MM-DD）
+	Workdays  []string  `json:"workdays,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// CellDiffOp 对应 api/openapi.yaml 中的 CellDiffOp
+type CellDiffOp struct {
+	Op   string `json:"op,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// CellHistoryEntry 对应 api/openapi.yaml 中的 CellHistoryEntry
+type CellHistoryEntry struct {
+	Version  int64       `json:"version,omitempty"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+	// 富文本字段的逐词差异
+	Diff      []*CellDiffOp `json:"diff,omitempty"`
+	ChangedBy string        `json:"changedBy,omitempty"`
+	ChangedAt time.Time     `json:"changedAt,omitempty"`
+}
+
+// CellHistoryResponse 单元格修改历史（按版本倒序），后续页原样传回 nextCursor
+type CellHistoryResponse struct {
+	RecordID   string              `json:"recordId,omitempty"`
+	FieldID    string              `json:"fieldId,omitempty"`
+	Entries    []*CellHistoryEntry `json:"entries,omitempty"`
+	NextCursor *int64              `json:"nextCursor,omitempty"`
+}
+
+// CellValidationError 对应 api/openapi.yaml 中的 CellValidationError
+type CellValidationError struct {
+	// 行序号（从 0 开始）
+	Row int `json:"row,omitempty"`
+	// 请求中的字段ID或名称
+	FieldKey  string `json:"fieldKey,omitempty"`
+	FieldID   string `json:"fieldId,omitempty"`
+	FieldName string `json:"fieldName,omitempty"`
+	// 与写入失败时的错误码相同
+	Code    string      `json:"code,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+}
+
+// Change 变更流中的一条变更，data 为记录字段值（删除时为删除前的值）或字段、视图的完整定义
+type Change struct {
+	ID         string          `json:"id,omitempty"`
+	Seq        int64           `json:"seq,omitempty"`
+	BaseID     string          `json:"base_id,omitempty"`
+	TableID    string          `json:"table_id,omitempty"`
+	EntityType string          `json:"entity_type,omitempty"`
+	Action     string          `json:"action,omitempty"`
+	EntityID   string          `json:"entity_id,omitempty"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	Version    int64           `json:"version,omitempty"`
+	UserID     string          `json:"user_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at,omitempty"`
+}
+
+// ChangeFeedFieldDef 对应 api/openapi.yaml 中的 ChangeFeedFieldDef
+type ChangeFeedFieldDef struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type,omitempty"`
+	IsComputed bool   `json:"isComputed,omitempty"`
+}
+
+// ChangeFeedHead 对应 api/openapi.yaml 中的 ChangeFeedHead
+type ChangeFeedHead struct {
+	Cursor int64 `json:"cursor,omitempty"`
+}
+
+// ChangeFeedPage 对应 api/openapi.yaml 中的 ChangeFeedPage
+type ChangeFeedPage struct {
+	Changes    []*Change                        `json:"changes,omitempty"`
+	NextCursor int64                            `json:"nextCursor,omitempty"`
+	HasMore    bool                             `json:"hasMore,omitempty"`
+	Schemas    map[string][]*ChangeFeedFieldDef `json:"schemas,omitempty"`
+}
+
+// ConsoleFeature 功能开关的生效定义（已应用控制台覆盖）及覆盖本身
+type ConsoleFeature struct {
+	Key         string           `json:"key,omitempty"`
+	Description string           `json:"description,omitempty"`
+	Enabled     bool             `json:"enabled,omitempty"`
+	Rollout     int              `json:"rollout,omitempty"`
+	RolloutBy   string           `json:"rollout_by,omitempty"`
+	Spaces      []string         `json:"spaces,omitempty"`
+	Users       []string         `json:"users,omitempty"`
+	DenySpaces  []string         `json:"deny_spaces,omitempty"`
+	DenyUsers   []string         `json:"deny_users,omitempty"`
+	Override    *FeatureOverride `json:"override,omitempty"`
+}
+
+// ConsoleLoginRequest 对应 api/openapi.yaml 中的 ConsoleLoginRequest
+type ConsoleLoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// ConsoleLoginResponse 控制台会话与令牌明文（只返回这一次）
+type ConsoleLoginResponse struct {
+	ID        string    `json:"id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	IpAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Token     string    `json:"token,omitempty"`
+}
+
+// ConsoleSession 对应 api/openapi.yaml 中的 ConsoleSession
+type ConsoleSession struct {
+	ID        string    `json:"id,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	IpAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	RevokedAt time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// ConsoleSpace 对应 api/openapi.yaml 中的 ConsoleSpace
+type ConsoleSpace struct {
+	ID         string    `json:"id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	OwnerID    string    `json:"owner_id,omitempty"`
+	OwnerEmail string    `json:"owner_email,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	Members    int64     `json:"members,omitempty"`
+	Bases      int64     `json:"bases,omitempty"`
+	Tables     int64     `json:"tables,omitempty"`
+}
+
+// ConsoleSpaceList 对应 api/openapi.yaml 中的 ConsoleSpaceList
+type ConsoleSpaceList struct {
+	Spaces []*ConsoleSpace `json:"spaces,omitempty"`
+	Total  int64           `json:"total,omitempty"`
+}
+
+// ConsoleUser 对应 api/openapi.yaml 中的 ConsoleUser
+type ConsoleUser struct {
+	ID            string    `json:"id,omitempty"`
+	Email         string    `json:"email,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	IsAdmin       bool      `json:"is_admin,omitempty"`
+	IsSystem      bool      `json:"is_system,omitempty"`
+	LastSignAt    time.Time `json:"last_sign_at,omitempty"`
+	DeactivatedAt time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
+
+// ConsoleUserList 对应 api/openapi.yaml 中的 ConsoleUserList
+type ConsoleUserList struct {
+	Users []*ConsoleUser `json:"users,omitempty"`
+	Total int64          `json:"total,omitempty"`
+}
+
+// CreateBaseBranchRequest 对应 api/openapi.yaml 中的 CreateBaseBranchRequest
+type CreateBaseBranchRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateDataExportRequest 对应 api/openapi.yaml 中的 CreateDataExportRequest
+type CreateDataExportRequest struct {
+	// 默认 parquet
+	Format string `json:"format,omitempty"`
+	// 为空时导出 Base 的全部表
+	TableIds []string `json:"tableIds,omitempty"`
+}
+
+// CreateDocumentTemplateRequest 对应 api/openapi.yaml 中的 CreateDocumentTemplateRequest
+type CreateDocumentTemplateRequest struct {
+	Name string `json:"name"`
+	// 已上传到本表且通过安全扫描的 .docx 或 .html 附件
+	AttachmentID string `json:"attachmentId"`
+	// 默认与模板格式相同
+	Output        string `json:"output,omitempty"`
+	TargetFieldID string `json:"targetFieldId"`
+	NameFieldID   string `json:"nameFieldId,omitempty"`
+	// 合并域 → 字段ID；未提供的合并域按字段名（不区分大小写）自动匹配
+	Mappings map[string]string `json:"mappings,omitempty"`
+}
+
+// CreateExternalSyncRequest 对应 api/openapi.yaml 中的 CreateExternalSyncRequest
+type CreateExternalSyncRequest struct {
+	// 目标表名称
+	Name   string              `json:"name"`
+	Source *ExternalSyncSource `json:"source"`
+	// 数据库连接串或 REST 的 Authorization 头（不在响应中返回）
+	Credentials string `json:"credentials,omitempty"`
+	// 外部列与目标字段的对应（第一个为主字段）
+	Mappings []*ExternalSyncMappingRequest `json:"mappings"`
+	// 同步间隔，如 15m（为空时使用默认间隔）
+	Interval string `json:"interval,omitempty"`
+}
+
+// CreateFromRecordTemplateRequest 对应 api/openapi.yaml 中的 CreateFromRecordTemplateRequest
+type CreateFromRecordTemplateRequest struct {
+	// 覆盖模板中的值（字段ID → 值）
+	Fields Fields `json:"fields,omitempty"`
+}
+
+// CreatePageRequest 对应 api/openapi.yaml 中的 CreatePageRequest
+type CreatePageRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreatePermalinkRequest 提供 recordId 时为记录链接（viewId 可选），否则为视图链接
+type CreatePermalinkRequest struct {
+	TableID  string `json:"tableId"`
+	ViewID   string `json:"viewId,omitempty"`
+	RecordID string `json:"recordId,omitempty"`
+}
+
+// CreatePortalSessionRequest 对应 api/openapi.yaml 中的 CreatePortalSessionRequest
+type CreatePortalSessionRequest struct {
+	// 离开门户后的返回地址，为空时使用服务端配置
+	ReturnURL string `json:"return_url,omitempty"`
+}
+
+// CreatePrintJobRequest 对应 api/openapi.yaml 中的 CreatePrintJobRequest
+type CreatePrintJobRequest struct {
+	TableID string `json:"tableId"`
+	// 打印视图时必填；打印记录时决定字段的顺序与可见性
+	ViewID string `json:"viewId,omitempty"`
+	// 为空时打印视图
+	RecordID string `json:"recordId,omitempty"`
+	// 默认 a4
+	Paper string `json:"paper,omitempty"`
+	// 默认记录纵向、视图横向
+	Orientation string `json:"orientation,omitempty"`
+}
+
+// CreateRecordRequest 对应 api/openapi.yaml 中的 CreateRecordRequest
+type CreateRecordRequest struct {
+	TableID string `json:"tableId"`
+	Data    Fields `json:"data"`
+}
+
+// CreateRecurrenceRuleRequest 对应 api/openapi.yaml 中的 CreateRecurrenceRuleRequest
+type CreateRecurrenceRuleRequest struct {
+	Name string `json:"name"`
+	// 同一表中的记录模板
+	TemplateID string              `json:"templateId"`
+	Schedule   *RecurrenceSchedule `json:"schedule"`
+	// 同一 Base 的节假日日历
+	CalendarID string `json:"calendarId,omitempty"`
+	// skip 或 shift，默认 skip
+	HolidayPolicy string   `json:"holidayPolicy,omitempty"`
+	SkipDates     []string `json:"skipDates,omitempty"`
+	// all 或 latest，默认 all
+	CatchUp string `json:"catchUp,omitempty"`
+}
+
+// CreateServiceTokenRequest 对应 api/openapi.yaml 中的 CreateServiceTokenRequest
+type CreateServiceTokenRequest struct {
+	Name string `json:"name"`
+	// 有效天数，0 表示不过期
+	ExpiresInDays int `json:"expiresInDays,omitempty"`
+}
+
+// CreateSpaceRequest 对应 api/openapi.yaml 中的 CreateSpaceRequest
+type CreateSpaceRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CreateSyncedTableRequest 对应 api/openapi.yaml 中的 CreateSyncedTableRequest
+type CreateSyncedTableRequest struct {
+	// 镜像表名称
+	Name string `json:"name"`
+	// 其他 Base 中的源表
+	SourceTableID string `json:"sourceTableId"`
+	// 源表的视图（按视图的过滤条件与可见字段同步）
+	SourceViewID string `json:"sourceViewId"`
+	// 增量同步间隔，如 5m（为空时使用默认间隔）
+	Interval string `json:"interval,omitempty"`
+}
+
+// CreateTableHealthFixRequest issueIds 为空时修复报告中全部待修复的问题
+type CreateTableHealthFixRequest struct {
+	// 只修复这些类型（orphaned_link、type_coercion）
+	Kinds    []string `json:"kinds,omitempty"`
+	IssueIds []string `json:"issueIds,omitempty"`
+}
+
+// CreateTextExtractionRuleRequest 对应 api/openapi.yaml 中的 CreateTextExtractionRuleRequest
+type CreateTextExtractionRuleRequest struct {
+	SourceFieldID string   `json:"sourceFieldId"`
+	TargetFieldID string   `json:"targetFieldId"`
+	Languages     []string `json:"languages,omitempty"`
+}
+
+// CreatedServiceToken 新建的服务令牌，secret 为明文令牌，只返回这一次
+type CreatedServiceToken struct {
+	ID        string     `json:"id,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Hint      string     `json:"hint,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	Secret    string     `json:"secret,omitempty"`
+}
+
+// DRPromotion 对应 api/openapi.yaml 中的 DRPromotion
+type DRPromotion struct {
+	ID     string `json:"id,omitempty"`
+	Region string `json:"region,omitempty"`
+	// 是否跳过了最终同步
+	Forced bool `json:"forced,omitempty"`
+	// 提升时的 RPO
+	RpoSeconds int64     `json:"rpo_seconds,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	PromotedBy string    `json:"promoted_by,omitempty"`
+	PromotedAt time.Time `json:"promoted_at,omitempty"`
+}
+
+// DRRunbook 对应 api/openapi.yaml 中的 DRRunbook
+type DRRunbook struct {
+	Report *DRStatusReport  `json:"report,omitempty"`
+	Steps  []*DRRunbookStep `json:"steps,omitempty"`
+}
+
+// DRRunbookStep 对应 api/openapi.yaml 中的 DRRunbookStep
+type DRRunbookStep struct {
+	Key    string `json:"key,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// DRStatusReport 对应 api/openapi.yaml 中的 DRStatusReport
+type DRStatusReport struct {
+	// 备区域
+	Region           string            `json:"region,omitempty"`
+	Role             string            `json:"role,omitempty"`
+	Promotion        *DRPromotion      `json:"promotion,omitempty"`
+	PrimaryReachable bool              `json:"primary_reachable,omitempty"`
+	Streams          []*DRStreamStatus `json:"streams,omitempty"`
+	// 各复制流 RPO 的最大值
+	RpoSeconds *int64 `json:"rpo_seconds,omitempty"`
+	// 各复制流复制延迟的最大值
+	LagSeconds       *int64    `json:"lag_seconds,omitempty"`
+	RpoTargetSeconds int64     `json:"rpo_target_seconds,omitempty"`
+	WithinTarget     bool      `json:"within_target,omitempty"`
+	GeneratedAt      time.Time `json:"generated_at,omitempty"`
+}
+
+// DRStreamStatus 对应 api/openapi.yaml 中的 DRStreamStatus
+type DRStreamStatus struct {
+	Stream string `json:"stream,omitempty"`
+	// 已复制到的位置（变更事件为 seq，附件为上传时间游标）
+	Position string `json:"position,omitempty"`
+	// 主区域在该时间之前的变更已全部复制
+	ReplicatedThrough time.Time `json:"replicated_through,omitempty"`
+	// 累计复制的行数、事件数或附件数
+	Items         int64     `json:"items,omitempty"`
+	LastSuccessAt time.Time `json:"last_success_at,omitempty"`
+	LastError     string    `json:"last_error,omitempty"`
+	// 主区域中尚未复制的条数，无法统计时为空
+	PendingItems int64 `json:"pending_items,omitempty"`
+	// 此时故障可能丢失的数据时间范围，从未复制时为空
+	RpoSeconds *int64 `json:"rpo_seconds,omitempty"`
+	// 距最近一次成功复制的时长
+	LagSeconds *int64 `json:"lag_seconds,omitempty"`
+}
+
+// DataExport Base 数据导出任务（每张表一个 Parquet 文件，连同清单写入对象存储）
+type DataExport struct {
+	ID         string              `json:"id,omitempty"`
+	BaseID     string              `json:"base_id,omitempty"`
+	TableIds   []string            `json:"table_ids,omitempty"`
+	Format     string              `json:"format,omitempty"`
+	Status     string              `json:"status,omitempty"`
+	Manifest   *DataExportManifest `json:"manifest,omitempty"`
+	Error      string              `json:"error,omitempty"`
+	CreatedBy  string              `json:"created_by,omitempty"`
+	CreatedAt  time.Time           `json:"created_at,omitempty"`
+	UpdatedAt  time.Time           `json:"updated_at,omitempty"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+	// 清单下载链接（仅获取已完成的任务时返回）
+	ManifestURL string `json:"manifest_url,omitempty"`
+	// 表ID → 数据文件下载链接（仅获取已完成的任务时返回）
+	FileUrls map[string]string `json:"file_urls,omitempty"`
+}
+
+// DataExportManifest 导出清单（同时以 manifest.json 写入对象存储）
+type DataExportManifest struct {
+	Format    string                     `json:"format,omitempty"`
+	ExportID  string                     `json:"export_id,omitempty"`
+	BaseID    string                     `json:"base_id,omitempty"`
+	CreatedAt time.Time                  `json:"created_at,omitempty"`
+	Tables    []*DataExportManifestTable `json:"tables,omitempty"`
+}
+
+// DataExportManifestColumn 对应 api/openapi.yaml 中的 DataExportManifestColumn
+type DataExportManifestColumn struct {
+	// Parquet 列名（字段列为字段的物理列名）
+	Name      string `json:"name,omitempty"`
+	FieldID   string `json:"field_id,omitempty"`
+	FieldName string `json:"field_name,omitempty"`
+	FieldType string `json:"field_type,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Nullable  bool   `json:"nullable,omitempty"`
+}
+
+// DataExportManifestTable 对应 api/openapi.yaml 中的 DataExportManifestTable
+type DataExportManifestTable struct {
+	TableID string `json:"table_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// 对象存储中的路径
+	Path    string                      `json:"path,omitempty"`
+	Rows    int64                       `json:"rows,omitempty"`
+	Bytes   int64                       `json:"bytes,omitempty"`
+	Columns []*DataExportManifestColumn `json:"columns,omitempty"`
+}
+
+// DocumentJob 文档生成任务
+type DocumentJob struct {
+	ID         string               `json:"id,omitempty"`
+	TemplateID string               `json:"template_id,omitempty"`
+	TableID    string               `json:"table_id,omitempty"`
+	ViewID     string               `json:"view_id,omitempty"`
+	RecordIds  []string             `json:"record_ids,omitempty"`
+	TimeZone   string               `json:"time_zone,omitempty"`
+	Locale     string               `json:"locale,omitempty"`
+	Source     string               `json:"source,omitempty"`
+	Status     string               `json:"status,omitempty"`
+	Total      int                  `json:"total,omitempty"`
+	Generated  int                  `json:"generated,omitempty"`
+	Failed     int                  `json:"failed,omitempty"`
+	Truncated  bool                 `json:"truncated,omitempty"`
+	Results    []*DocumentJobResult `json:"results,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	CreatedBy  string               `json:"created_by,omitempty"`
+	CreatedAt  time.Time            `json:"created_at,omitempty"`
+	UpdatedAt  time.Time            `json:"updated_at,omitempty"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+}
+
+// DocumentJobResult 对应 api/openapi.yaml 中的 DocumentJobResult
+type DocumentJobResult struct {
+	RecordID     string `json:"record_id,omitempty"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+	FileName     string `json:"file_name,omitempty"`
+	Size         int64  `json:"size,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// DocumentTemplate 文档模板（DOCX/HTML 合并域映射到字段，生成的文件写入附件字段）
+type DocumentTemplate struct {
+	ID      string `json:"id,omitempty"`
+	TableID string `json:"table_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Format  string `json:"format,omitempty"`
+	// 生成文件的格式（HTML 模板可生成 PDF）
+	Output   string `json:"output,omitempty"`
+	FileName string `json:"file_name,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	// 模板中的合并域（{{字段名}} 或 Word 的 MERGEFIELD）
+	MergeFields []string `json:"merge_fields,omitempty"`
+	// 合并域 → 字段ID，未映射的合并域生成时为空
+	Mappings map[string]string `json:"mappings,omitempty"`
+	// 写入生成文件的附件字段
+	TargetFieldID string `json:"target_field_id,omitempty"`
+	// 生成文件名取该字段的值，为空时取主字段
+	NameFieldID string    `json:"name_field_id,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	UpdatedBy   string    `json:"updated_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// ExternalSyncConnector 外部数据同步（按间隔从外部数据库表或 REST 接口拉取行到只读同步表）
+type ExternalSyncConnector struct {
+	ID       string                 `json:"id,omitempty"`
+	BaseID   string                 `json:"base_id,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Source   *ExternalSyncSource    `json:"source,omitempty"`
+	Mappings []*ExternalSyncMapping `json:"mappings,omitempty"`
+	// 目标表（只读，只能由同步写入）
+	TargetTableID string `json:"target_table_id,omitempty"`
+	// 目标表中的“源记录已删除”复选框字段
+	DeletedFieldID string `json:"deleted_field_id,omitempty"`
+	// 同步间隔（纳秒）
+	Interval int64 `json:"interval,omitempty"`
+	Enabled  bool  `json:"enabled,omitempty"`
+	// 已同步到的更新时间
+	Cursor string `json:"cursor,omitempty"`
+	// 该更新时间下最后同步的行键
+	CursorKey      string     `json:"cursor_key,omitempty"`
+	LastFullSyncAt *time.Time `json:"last_full_sync_at,omitempty"`
+	NextRunAt      time.Time  `json:"next_run_at,omitempty"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	// succeeded 或 failed
+	LastStatus string    `json:"last_status,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// ExternalSyncMapping 对应 api/openapi.yaml 中的 ExternalSyncMapping
+type ExternalSyncMapping struct {
+	Column  string `json:"column,omitempty"`
+	FieldID string `json:"field_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// ExternalSyncMappingRequest 对应 api/openapi.yaml 中的 ExternalSyncMappingRequest
+type ExternalSyncMappingRequest struct {
+	Column string `json:"column"`
+	// 目标字段名称（为空时使用列名）
+	Name string `json:"name,omitempty"`
+	// 目标字段类型（为空时为 singleLineText）
+	Type string `json:"type,omitempty"`
+}
+
+// ExternalSyncRun 对应 api/openapi.yaml 中的 ExternalSyncRun
+type ExternalSyncRun struct {
+	ID          string `json:"id,omitempty"`
+	ConnectorID string `json:"connector_id,omitempty"`
+	// running、succeeded 或 failed
+	Status string `json:"status,omitempty"`
+	// 是否为全量同步
+	Full    bool  `json:"full,omitempty"`
+	Fetched int64 `json:"fetched,omitempty"`
+	Created int64 `json:"created,omitempty"`
+	Updated int64 `json:"updated,omitempty"`
+	// 标记为源记录已删除的行数
+	Removed       int64      `json:"removed,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	CursorAtStart string     `json:"cursor_at_start,omitempty"`
+	CursorAtEnd   string     `json:"cursor_at_end,omitempty"`
+	StartedAt     time.Time  `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// ExternalSyncSource 外部数据源（postgres / mysql 使用 table，rest 使用 url 与 JSON 路径）
+type ExternalSyncSource struct {
+	// postgres、mysql 或 rest
+	Type string `json:"type"`
+	// 源表，可带 schema 前缀
+	Table string `json:"table,omitempty"`
+	// 返回 JSON 的接口地址
+	URL string `json:"url,omitempty"`
+	// 行数组的 JSON 路径（以点分隔，为空时整个响应即为数组）
+	ItemsPath string `json:"items_path,omitempty"`
+	// 下一页地址的 JSON 路径（下一页必须与 url 同源）
+	NextPath string `json:"next_path,omitempty"`
+	// 增量读取时携带游标值的查询参数
+	CursorParam string `json:"cursor_param,omitempty"`
+	// 行的唯一键
+	KeyColumn string `json:"key_column"`
+	// 更新时间列，为空时每次全量同步
+	CursorColumn string `json:"cursor_column,omitempty"`
+}
+
+// FeatureOverride 对应 api/openapi.yaml 中的 FeatureOverride
+type FeatureOverride struct {
+	Key       string    `json:"key,omitempty"`
+	Enabled   bool      `json:"enabled,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Field 对应 api/openapi.yaml 中的 Field
+type Field struct {
+	ID      string `json:"id,omitempty"`
+	TableID string `json:"tableId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// 外部 API 使用的稳定标识（同表唯一，重命名后不变）
+	ApiKey      string                 `json:"apiKey,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Options     map[string]interface{} `json:"options,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Unique      bool                   `json:"unique,omitempty"`
+	IsPrimary   bool                   `json:"isPrimary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+}
+
+// FieldDelta since 版本之后新建或修改的字段（当前定义）与已删除的字段ID
+type FieldDelta struct {
+	// 新的表结构版本，下次增量的 since
+	Version int64 `json:"version,omitempty"`
+	// 版本超出变更保留期，fields 为全部字段，应整体替换本地字段列表
+	Reset   bool     `json:"reset,omitempty"`
+	Fields  []*Field `json:"fields,omitempty"`
+	Deleted []string `json:"deleted,omitempty"`
+}
+
+// FieldOptionSchema 字段类型选项的 JSON Schema（子集：type、properties、required、items、enum、minimum/maximum、minLength/maxLength），未登记的选项键不受限制
+type FieldOptionSchema struct {
+	Type        string `json:"type,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// 选项名到其 JSON Schema 的映射
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Required   []string               `json:"required,omitempty"`
+}
+
+// FindReplaceFieldCount 对应 api/openapi.yaml 中的 FindReplaceFieldCount
+type FindReplaceFieldCount struct {
+	FieldID     string `json:"field_id,omitempty"`
+	FieldName   string `json:"field_name,omitempty"`
+	Matched     int    `json:"matched,omitempty"`
+	Occurrences int    `json:"occurrences,omitempty"`
+}
+
+// FindReplacePreview 对应 api/openapi.yaml 中的 FindReplacePreview
+type FindReplacePreview struct {
+	Scanned int `json:"scanned,omitempty"`
+	// 包含匹配的记录数
+	Matched     int                      `json:"matched,omitempty"`
+	Occurrences int                      `json:"occurrences,omitempty"`
+	Fields      []*FindReplaceFieldCount `json:"fields,omitempty"`
+	Samples     []*FindReplaceSample     `json:"samples,omitempty"`
+	Truncated   bool                     `json:"truncated,omitempty"`
+}
+
+// FindReplaceSample 对应 api/openapi.yaml 中的 FindReplaceSample
+type FindReplaceSample struct {
+	RecordID string `json:"record_id,omitempty"`
+	FieldID  string `json:"field_id,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// FindReplaceSpec 对应 api/openapi.yaml 中的 FindReplaceSpec
+type FindReplaceSpec struct {
+	// 预览时取自路径，作为 find_replace 操作参数时必填
+	TableID string `json:"table_id,omitempty"`
+	// 只处理视图过滤范围内的记录
+	ViewID   string   `json:"view_id,omitempty"`
+	FieldIds []string `json:"field_ids"`
+	Find     string   `json:"find"`
+	Replace  string   `json:"replace,omitempty"`
+	// 按正则匹配，replace 中可用 $1、${name} 引用分组
+	Regex         bool `json:"regex,omitempty"`
+	CaseSensitive bool `json:"case_sensitive,omitempty"`
+	WholeWord     bool `json:"whole_word,omitempty"`
+}
+
+// FlushCacheRequest 对应 api/openapi.yaml 中的 FlushCacheRequest
+type FlushCacheRequest struct {
+	// 只清理该表相关的缓存，为空时清空全部缓存
+	TableID string `json:"tableId,omitempty"`
+}
+
+// GenerateDocumentsRequest 对应 api/openapi.yaml 中的 GenerateDocumentsRequest
+type GenerateDocumentsRequest struct {
+	RecordIds []string `json:"recordIds,omitempty"`
+	// 未提供 recordIds 时为视图中的记录生成（超过上限时只生成前面的部分）
+	ViewID string `json:"viewId,omitempty"`
+}
+
+// HolidayCalendar 节假日日历（按 Base 管理，供多条重复规则共用）
+type HolidayCalendar struct {
+	ID        string    `json:"id,omitempty"`
+	BaseID    string    `json:"base_id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Dates     []string  `json:"dates,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// ImpersonationSession 对应 api/openapi.yaml 中的 ImpersonationSession
+type ImpersonationSession struct {
+	ID            string     `json:"id,omitempty"`
+	SupportUserID string     `json:"support_user_id,omitempty"`
+	SupportEmail  string     `json:"support_email,omitempty"`
+	TargetUserID  string     `json:"target_user_id,omitempty"`
+	TargetEmail   string     `json:"target_email,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	EndedBy       string     `json:"ended_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at,omitempty"`
+}
+
+// InvalidIndex 对应 api/openapi.yaml 中的 InvalidIndex
+type InvalidIndex struct {
+	Name  string `json:"name,omitempty"`
+	Table string `json:"table,omitempty"`
+}
+
+// LockRecordRequest 对应 api/openapi.yaml 中的 LockRecordRequest
+type LockRecordRequest struct {
+	// 锁定的字段，为空时锁定整条记录
+	FieldIds []string `json:"fieldIds,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	// 有效期（秒），为 0 时在解锁前一直有效
+	TtlSeconds int `json:"ttlSeconds,omitempty"`
+}
+
+// LoginRequest 对应 api/openapi.yaml 中的 LoginRequest
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginResponse 对应 api/openapi.yaml 中的 LoginResponse
+type LoginResponse struct {
+	User         *User  `json:"user,omitempty"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// MaintenanceOverview 对应 api/openapi.yaml 中的 MaintenanceOverview
+type MaintenanceOverview struct {
+	Active []*MaintenanceWindow `json:"active,omitempty"`
+	Recent []*MaintenanceWindow `json:"recent,omitempty"`
+}
+
+// MaintenanceStatus 对应 api/openapi.yaml 中的 MaintenanceStatus
+type MaintenanceStatus struct {
+	ReadOnly bool                 `json:"read_only,omitempty"`
+	Windows  []*MaintenanceWindow `json:"windows,omitempty"`
+}
+
+// MaintenanceWindow 对应 api/openapi.yaml 中的 MaintenanceWindow
+type MaintenanceWindow struct {
+	// 配置文件开启的实例只读为 config
+	ID      string `json:"id,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	ScopeID string `json:"scope_id,omitempty"`
+	// 展示给用户的提示
+	Message   string    `json:"message,omitempty"`
+	StartedBy string    `json:"started_by,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	// 计划结束时间，到期自动结束
+	EndsAt  time.Time `json:"ends_at,omitempty"`
+	EndedBy string    `json:"ended_by,omitempty"`
+	EndedAt time.Time `json:"ended_at,omitempty"`
+}
+
+// NLQueryRequest 对应 api/openapi.yaml 中的 NLQueryRequest
+type NLQueryRequest struct {
+	// 自然语言问题（最多 500 个字符）
+	Question string `json:"question"`
+	// IANA 时区，用于换算“本周”等相对日期，默认 UTC
+	TimeZone string `json:"timeZone,omitempty"`
+	// 返回的记录数，默认 20，不超过服务端配置的上限
+	Limit int `json:"limit,omitempty"`
+}
+
+// NLQueryResult 对应 api/openapi.yaml 中的 NLQueryResult
+type NLQueryResult struct {
+	Question string          `json:"question,omitempty"`
+	Filter   *ViewFilter     `json:"filter,omitempty"`
+	Sort     []*ViewSortItem `json:"sort,omitempty"`
+	// 模型对条件的说明
+	Explanation string    `json:"explanation,omitempty"`
+	Records     []*Record `json:"records,omitempty"`
+	// 满足条件的记录总数
+	Total    int64  `json:"total,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// OfflineConflict 对应 api/openapi.yaml 中的 OfflineConflict
+type OfflineConflict struct {
+	Reason        string                  `json:"reason,omitempty"`
+	ServerVersion int64                   `json:"serverVersion,omitempty"`
+	ServerFields  map[string]interface{}  `json:"serverFields,omitempty"`
+	Fields        []*OfflineFieldConflict `json:"fields,omitempty"`
+}
+
+// OfflineFieldConflict 对应 api/openapi.yaml 中的 OfflineFieldConflict
+type OfflineFieldConflict struct {
+	FieldID string      `json:"fieldId,omitempty"`
+	Base    interface{} `json:"base,omitempty"`
+	Local   interface{} `json:"local,omitempty"`
+	Server  interface{} `json:"server,omitempty"`
+}
+
+// OfflineMutation 对应 api/openapi.yaml 中的 OfflineMutation
+type OfflineMutation struct {
+	// 客户端生成的修改ID，重复推送只执行一次
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	TableID string `json:"tableId"`
+	// 记录ID；新建时为客户端本地ID，之后的修改可以引用
+	RecordID string `json:"recordId,omitempty"`
+	// 客户端修改前看到的记录版本
+	BaseVersion int64 `json:"baseVersion,omitempty"`
+	// 客户端修改前看到的字段值，用于按字段判断冲突
+	Base   map[string]interface{} `json:"base,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// 默认 merge
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// OfflineMutationResult 对应 api/openapi.yaml 中的 OfflineMutationResult
+type OfflineMutationResult struct {
+	MutationID string `json:"mutationId,omitempty"`
+	Status     string `json:"status,omitempty"`
+	// 服务端记录ID（新建时为新记录ID）
+	RecordID  string           `json:"recordId,omitempty"`
+	Version   int64            `json:"version,omitempty"`
+	Conflict  *OfflineConflict `json:"conflict,omitempty"`
+	ErrorCode string           `json:"errorCode,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	// 修改此前已处理过，返回的是当时的结果
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// OfflinePushRequest 对应 api/openapi.yaml 中的 OfflinePushRequest
+type OfflinePushRequest struct {
+	// 客户端（设备安装）的唯一ID
+	ClientID  string             `json:"clientId"`
+	Mutations []*OfflineMutation `json:"mutations"`
+}
+
+// OfflinePushResult 对应 api/openapi.yaml 中的 OfflinePushResult
+type OfflinePushResult struct {
+	Results []*OfflineMutationResult `json:"results,omitempty"`
+}
+
+// OfflineSnapshotPage 表快照的一页；保存第一页的 cursor，快照完成后从该游标经 ListChanges 增量拉取
+type OfflineSnapshotPage struct {
+	TableID string                `json:"tableId,omitempty"`
+	Cursor  int64                 `json:"cursor,omitempty"`
+	Fields  []*ChangeFeedFieldDef `json:"fields,omitempty"`
+	Records []*Record             `json:"records,omitempty"`
+	// 下一页的 after 参数
+	NextAfter int64 `json:"nextAfter,omitempty"`
+	HasMore   bool  `json:"hasMore,omitempty"`
+}
+
+// OnlineMigration 对应 api/openapi.yaml 中的 OnlineMigration
+type OnlineMigration struct {
+	Version int    `json:"version,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// expand 新旧版本代码都能运行；contract 需在所有实例升级后执行
+	Phase          string    `json:"phase,omitempty"`
+	Status         string    `json:"status,omitempty"`
+	StartedAt      time.Time `json:"started_at,omitempty"`
+	FinishedAt     time.Time `json:"finished_at,omitempty"`
+	BackfilledRows int64     `json:"backfilled_rows,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// OnlineMigrationStatus 对应 api/openapi.yaml 中的 OnlineMigrationStatus
+type OnlineMigrationStatus struct {
+	// 当前版本代码已知的最新迁移
+	LatestVersion   int `json:"latest_version,omitempty"`
+	AppliedVersion  int `json:"applied_version,omitempty"`
+	PendingExpand   int `json:"pending_expand,omitempty"`
+	PendingContract int `json:"pending_contract,omitempty"`
+	// 当前版本代码依赖的扩展迁移均已应用
+	Compatible bool `json:"compatible,omitempty"`
+	// 是否有实例正在执行迁移
+	Running        bool               `json:"running,omitempty"`
+	Migrations     []*OnlineMigration `json:"migrations,omitempty"`
+	InvalidIndexes []*InvalidIndex    `json:"invalid_indexes,omitempty"`
+	// 已应用但当前版本代码未知的迁移（更新版本的实例已执行）
+	UnknownVersions []int `json:"unknown_versions,omitempty"`
+}
+
+// Operation 长时操作（导入等），发起后轮询直至 status 为 succeeded、failed 或 cancelled
+type Operation struct {
+	ID          string                `json:"id,omitempty"`
+	BaseID      string                `json:"base_id,omitempty"`
+	Type        string                `json:"type,omitempty"`
+	Status      string                `json:"status,omitempty"`
+	Total       int64                 `json:"total,omitempty"`
+	Processed   int64                 `json:"processed,omitempty"`
+	FailedCount int64                 `json:"failed_count,omitempty"`
+	Errors      []*OperationItemError `json:"errors,omitempty"`
+	Result      json.RawMessage       `json:"result,omitempty"`
+	Error       string                `json:"error,omitempty"`
+	Progress    float64               `json:"progress,omitempty"`
+	CreatedAt   time.Time             `json:"created_at,omitempty"`
+	FinishedAt  *time.Time            `json:"finished_at,omitempty"`
+}
+
+// OperationItemError 对应 api/openapi.yaml 中的 OperationItemError
+type OperationItemError struct {
+	Index   int    `json:"index,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Page 对应 api/openapi.yaml 中的 Page
+type Page struct {
+	ID               string            `json:"id,omitempty"`
+	BaseID           string            `json:"baseId,omitempty"`
+	Name             string            `json:"name,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Elements         []*PageElement    `json:"elements,omitempty"`
+	Rules            []*PageAccessRule `json:"rules,omitempty"`
+	CreatedBy        string            `json:"createdBy,omitempty"`
+	CreatedTime      time.Time         `json:"createdTime,omitempty"`
+	LastModifiedTime time.Time         `json:"lastModifiedTime,omitempty"`
+	LastModifiedBy   string            `json:"lastModifiedBy,omitempty"`
+}
+
+// PageAccessRule 对应 api/openapi.yaml 中的 PageAccessRule
+type PageAccessRule struct {
+	// user 或 role
+	PrincipalType string `json:"principalType"`
+	// 用户ID或 Base/空间角色名
+	PrincipalID string `json:"principalId"`
+	// view、interact（可点击按钮）或 edit（可编辑允许编辑的字段）
+	Access string `json:"access"`
+}
+
+// PageButtonRun 对应 api/openapi.yaml 中的 PageButtonRun
+type PageButtonRun struct {
+	ID          string `json:"id,omitempty"`
+	WorkflowID  string `json:"workflow_id,omitempty"`
+	TriggerType string `json:"trigger_type,omitempty"`
+	Status      string `json:"status,omitempty"`
+	// 触发输入（JSON），包含 pageId、elementId 及记录
+	Input       *string    `json:"input,omitempty"`
+	StartedTime *time.Time `json:"started_time,omitempty"`
+}
+
+// PageElement 对应 api/openapi.yaml 中的 PageElement
+type PageElement struct {
+	// 为空时分配新ID
+	ID string `json:"id,omitempty"`
+	// record_list、detail 或 button
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"`
+	// 记录列表/详情绑定的表格；按钮绑定表格时针对单条记录触发
+	TableID string `json:"tableId,omitempty"`
+	// 记录须满足该视图的过滤条件，列表按视图排序
+	ViewID string `json:"viewId,omitempty"`
+	// 展示的字段（为空时为视图中可见的字段）
+	FieldIds []string `json:"fieldIds,omitempty"`
+	// 允许编辑的字段（须在 fieldIds 中）
+	EditableFieldIds []string `json:"editableFieldIds,omitempty"`
+	// 按钮触发的工作流
+	WorkflowID string `json:"workflowId,omitempty"`
+	X          int    `json:"x,omitempty"`
+	Y          int    `json:"y,omitempty"`
+	W          int    `json:"w,omitempty"`
+	H          int    `json:"h,omitempty"`
+}
+
+// PageElementRender 对应 api/openapi.yaml 中的 PageElementRender
+type PageElementRender struct {
+	ID       string   `json:"id,omitempty"`
+	Type     string   `json:"type,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	TableID  string   `json:"tableId,omitempty"`
+	ViewID   string   `json:"viewId,omitempty"`
+	FieldIds []string `json:"fieldIds,omitempty"`
+	// 访问级别不是 edit 时为空
+	EditableFieldIds []string `json:"editableFieldIds,omitempty"`
+	WorkflowID       string   `json:"workflowId,omitempty"`
+	X                int      `json:"x,omitempty"`
+	Y                int      `json:"y,omitempty"`
+	W                int      `json:"w,omitempty"`
+	H                int      `json:"h,omitempty"`
+	Fields           []*Field `json:"fields,omitempty"`
+}
+
+// PageRecordList 对应 api/openapi.yaml 中的 PageRecordList
+type PageRecordList struct {
+	Records    []*Record             `json:"records,omitempty"`
+	Pagination *PageRecordPagination `json:"pagination,omitempty"`
+}
+
+// PageRecordPagination 对应 api/openapi.yaml 中的 PageRecordPagination
+type PageRecordPagination struct {
+	Total       int64 `json:"total,omitempty"`
+	Page        int   `json:"page,omitempty"`
+	PageSize    int   `json:"pageSize,omitempty"`
+	TotalPages  int   `json:"totalPages,omitempty"`
+	HasNext     bool  `json:"hasNext,omitempty"`
+	HasPrevious bool  `json:"hasPrevious,omitempty"`
+}
+
+// PageRender 对应 api/openapi.yaml 中的 PageRender
+type PageRender struct {
+	ID          string               `json:"id,omitempty"`
+	BaseID      string               `json:"baseId,omitempty"`
+	Name        string               `json:"name,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Access      string               `json:"access,omitempty"`
+	Elements    []*PageElementRender `json:"elements,omitempty"`
+}
+
+// PageSummary 对应 api/openapi.yaml 中的 PageSummary
+type PageSummary struct {
+	ID          string `json:"id,omitempty"`
+	BaseID      string `json:"baseId,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// 当前用户的访问级别
+	Access string `json:"access,omitempty"`
+}
+
+// Pagination 对应 api/openapi.yaml 中的 Pagination
+type Pagination struct {
+	Page       int `json:"page,omitempty"`
+	Limit      int `json:"limit,omitempty"`
+	Total      int `json:"total,omitempty"`
+	TotalPages int `json:"total_pages,omitempty"`
+}
+
+// Permalink 记录或视图的永久短链接（同一目标总是同一个链接，链接本身不授予访问权）
+type Permalink struct {
+	Code       string    `json:"code,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	TargetType string    `json:"targetType,omitempty"`
+	TableID    string    `json:"tableId,omitempty"`
+	ViewID     string    `json:"viewId,omitempty"`
+	RecordID   string    `json:"recordId,omitempty"`
+	CreatedAt  time.Time `json:"createdAt,omitempty"`
+}
+
+// PermalinkLocation 链接目标当前的规范位置
+type PermalinkLocation struct {
+	Code       string `json:"code,omitempty"`
+	TargetType string `json:"targetType,omitempty"`
+	SpaceID    string `json:"spaceId,omitempty"`
+	BaseID     string `json:"baseId,omitempty"`
+	TableID    string `json:"tableId,omitempty"`
+	// 记录链接的视图已删除或无权查看时为空
+	ViewID   string `json:"viewId,omitempty"`
+	RecordID string `json:"recordId,omitempty"`
+	// Web 应用中的路径
+	Path string `json:"path,omitempty"`
+}
+
+// PermalinkPreview 链接预览（供聊天工具展开链接），只包含访问者可见的数据
+type PermalinkPreview struct {
+	URL        string `json:"url,omitempty"`
+	TargetType string `json:"targetType,omitempty"`
+	// 记录的主字段值，视图链接为视图名称
+	Title string `json:"title,omitempty"`
+	// Base / 表 / 视图名称
+	Subtitle string                   `json:"subtitle,omitempty"`
+	Fields   []*PermalinkPreviewField `json:"fields,omitempty"`
+	Location *PermalinkLocation       `json:"location,omitempty"`
+}
+
+// PermalinkPreviewField 对应 api/openapi.yaml 中的 PermalinkPreviewField
+type PermalinkPreviewField struct {
+	FieldID string `json:"fieldId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// 按字段格式化后的展示文本
+	Value string `json:"value,omitempty"`
+}
+
+// Plan 对应 api/openapi.yaml 中的 Plan
+type Plan struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// 键为 rows_per_base、attachment_bytes、automation_runs、api_calls
+	Limits map[string]*PlanLimit `json:"limits,omitempty"`
+}
+
+// PlanBaseRows 对应 api/openapi.yaml 中的 PlanBaseRows
+type PlanBaseRows struct {
+	BaseID   string `json:"base_id,omitempty"`
+	BaseName string `json:"base_name,omitempty"`
+	Rows     int64  `json:"rows,omitempty"`
+	Level    string `json:"level,omitempty"`
+}
+
+// PlanLimit 一项套餐限额，0 或缺省表示不限制。超过软限额时允许并警告，超过硬限额时拒绝
+type PlanLimit struct {
+	Soft int64 `json:"soft,omitempty"`
+	Hard int64 `json:"hard,omitempty"`
+}
+
+// PlanLimitStatus 对应 api/openapi.yaml 中的 PlanLimitStatus
+type PlanLimitStatus struct {
+	Key  string `json:"key,omitempty"`
+	Used int64  `json:"used,omitempty"`
+	Soft int64  `json:"soft,omitempty"`
+	Hard int64  `json:"hard,omitempty"`
+	// ok、warning（超过软限额）或 exceeded（超过硬限额）
+	Level string `json:"level,omitempty"`
+	// rows_per_base 为记录数最多的 Base
+	BaseID string `json:"base_id,omitempty"`
+}
+
+// PlanUsageSummary 工作空间的套餐与当前计费周期（自然月，UTC）的用量
+type PlanUsageSummary struct {
+	SpaceID     string             `json:"space_id,omitempty"`
+	Plan        *Plan              `json:"plan,omitempty"`
+	PeriodStart time.Time          `json:"period_start,omitempty"`
+	PeriodEnd   time.Time          `json:"period_end,omitempty"`
+	Limits      []*PlanLimitStatus `json:"limits,omitempty"`
+	Bases       []*PlanBaseRows    `json:"bases,omitempty"`
+	MeteredAt   time.Time          `json:"metered_at,omitempty"`
+}
+
+// PointInTimeCellDiff 对应 api/openapi.yaml 中的 PointInTimeCellDiff
+type PointInTimeCellDiff struct {
+	RecordID  string `json:"recordId,omitempty"`
+	FieldID   string `json:"fieldId,omitempty"`
+	FieldName string `json:"fieldName,omitempty"`
+	// 当前值
+	Current interface{} `json:"current,omitempty"`
+	// 时间点上的值（恢复后的值）
+	AsOf interface{} `json:"asOf,omitempty"`
+}
+
+// PointInTimeFailure 对应 api/openapi.yaml 中的 PointInTimeFailure
+type PointInTimeFailure struct {
+	RecordID string `json:"recordId,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PointInTimeRestorePreview 对应 api/openapi.yaml 中的 PointInTimeRestorePreview
+type PointInTimeRestorePreview struct {
+	AsOf time.Time `json:"asOf,omitempty"`
+	// 需要恢复的记录数
+	Records int                    `json:"records,omitempty"`
+	Changes []*PointInTimeCellDiff `json:"changes,omitempty"`
+	Skipped []*PointInTimeSkip     `json:"skipped,omitempty"`
+}
+
+// PointInTimeRestoreRequest recordId 与 fieldId 必须且只能提供一个
+type PointInTimeRestoreRequest struct {
+	// 恢复到的时间点（须在变更日志保留期内）
+	AsOf time.Time `json:"asOf"`
+	// 恢复单条记录
+	RecordID string `json:"recordId,omitempty"`
+	// 恢复单条记录时只恢复这些字段，为空时恢复全部可写字段
+	FieldIds []string `json:"fieldIds,omitempty"`
+	// 恢复一列
+	FieldID string `json:"fieldId,omitempty"`
+	// 恢复一列时只恢复这些记录，为空时恢复该时间点之后修改过的全部记录
+	RecordIds []string `json:"recordIds,omitempty"`
+}
+
+// PointInTimeRestoreResult 对应 api/openapi.yaml 中的 PointInTimeRestoreResult
+type PointInTimeRestoreResult struct {
+	AsOf     time.Time             `json:"asOf,omitempty"`
+	Restored []string              `json:"restored,omitempty"`
+	Failed   []*PointInTimeFailure `json:"failed,omitempty"`
+	Skipped  []*PointInTimeSkip    `json:"skipped,omitempty"`
+}
+
+// PointInTimeSkip 对应 api/openapi.yaml 中的 PointInTimeSkip
+type PointInTimeSkip struct {
+	RecordID string `json:"recordId,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PortalSession 对应 api/openapi.yaml 中的 PortalSession
+type PortalSession struct {
+	URL string `json:"url,omitempty"`
+}
+
+// PrintJob 记录或视图的 PDF 打印任务
+type PrintJob struct {
+	ID          string `json:"id,omitempty"`
+	TargetType  string `json:"target_type,omitempty"`
+	TableID     string `json:"table_id,omitempty"`
+	ViewID      string `json:"view_id,omitempty"`
+	RecordID    string `json:"record_id,omitempty"`
+	Paper       string `json:"paper,omitempty"`
+	Orientation string `json:"orientation,omitempty"`
+	TimeZone    string `json:"time_zone,omitempty"`
+	Locale      string `json:"locale,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Status      string `json:"status,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+	Pages       int    `json:"pages,omitempty"`
+	// 视图打印的记录数
+	Rows int `json:"rows,omitempty"`
+	// 视图记录数超过上限，只打印了前面的部分
+	Truncated  bool       `json:"truncated,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// PDF 下载链接（仅获取已完成的任务时返回）
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// PromoteRequest 对应 api/openapi.yaml 中的 PromoteRequest
+type PromoteRequest struct {
+	// 跳过最终同步（主区域不可达时必须指定），接受 RPO 范围内的数据丢失
+	Force  bool   `json:"force,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PutSemanticIndexRequest 对应 api/openapi.yaml 中的 PutSemanticIndexRequest
+type PutSemanticIndexRequest struct {
+	// 参与向量化的文本类字段（最多 20 个，不能是加密字段）
+	FieldIds []string `json:"fieldIds"`
+	// 为空时使用服务端默认服务商
+	Provider string `json:"provider,omitempty"`
+	// 为空时使用服务商配置的 embedding_model
+	Model string `json:"model,omitempty"`
+}
+
+// Record 记录，单元格值按字段ID存放在 data 中
+type Record struct {
+	ID        string                 `json:"id,omitempty"`
+	TableID   string                 `json:"tableId,omitempty"`
+	Data      Fields                 `json:"data,omitempty"`
+	CreatedBy string                 `json:"createdBy,omitempty"`
+	UpdatedBy string                 `json:"updatedBy,omitempty"`
+	CreatedAt time.Time              `json:"createdAt,omitempty"`
+	UpdatedAt time.Time              `json:"updatedAt,omitempty"`
+	Version   int                    `json:"version,omitempty"`
+	Expanded  map[string]interface{} `json:"expanded,omitempty"`
+	// 已归档（includeArchived 查询返回的归档记录）
+	Archived bool `json:"archived,omitempty"`
+	// 单元格的展示文本（按字段格式化选项、时区与语言计算），键与 data 一致；请求 includeFormatted=true 时返回
+	Formatted map[string]string `json:"formatted,omitempty"`
+}
+
+// RecordBacklink 在长文本（@[标题](record:tblId/recId)）或富文本中提及了该记录的位置
+type RecordBacklink struct {
+	TableID   string `json:"tableId,omitempty"`
+	TableName string `json:"tableName,omitempty"`
+	RecordID  string `json:"recordId,omitempty"`
+	// 来源记录主字段的展示文本
+	Title     string    `json:"title,omitempty"`
+	FieldID   string    `json:"fieldId,omitempty"`
+	FieldName string    `json:"fieldName,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// RecordCreateItem 对应 api/openapi.yaml 中的 RecordCreateItem
+type RecordCreateItem struct {
+	Fields Fields `json:"fields"`
+}
+
+// RecordLock 记录编辑锁（锁定期间只有持有者可以修改被锁定的记录或字段）
+type RecordLock struct {
+	ID       string `json:"id,omitempty"`
+	TableID  string `json:"table_id,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	// 锁定的字段，为空表示锁定整条记录
+	FieldIds []string `json:"field_ids,omitempty"`
+	OwnerID  string   `json:"owner_id,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+	// 为空表示在解锁前一直有效
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// RecordPage 对应 api/openapi.yaml 中的 RecordPage
+type RecordPage struct {
+	List       []*Record   `json:"list,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// RecordSnapshot 按时间点读取的一页记录，后续页原样传回 asOf 与 nextCursor
+type RecordSnapshot struct {
+	Records    []*Record `json:"records,omitempty"`
+	Total      int64     `json:"total,omitempty"`
+	AsOf       time.Time `json:"asOf,omitempty"`
+	NextCursor *int64    `json:"nextCursor,omitempty"`
+}
+
+// RecordTemplate 记录模板（预设单元格值，用户字段可写 @me，日期字段可写 @today、@now 及偏移如 @today+7d，工作日偏移如 @today+3bd）
+type RecordTemplate struct {
+	ID          string `json:"id,omitempty"`
+	TableID     string `json:"table_id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// 字段ID → 值
+	Values Fields `json:"values,omitempty"`
+	// 快速新建时使用的模板，每个表最多一个
+	IsDefault bool      `json:"is_default,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// RecurrenceOccurrence 一次计划执行
+type RecurrenceOccurrence struct {
+	// 按计划计算的时间（幂等键）
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	// 实际生成时间（节假日顺延后）
+	RunAt time.Time `json:"run_at,omitempty"`
+	// 非空时本次不生成记录：holiday、skip_date 或 missed
+	Skip string `json:"skip,omitempty"`
+}
+
+// RecurrenceRule 重复记录规则（按计划用记录模板新建记录，以创建人的身份执行）
+type RecurrenceRule struct {
+	ID         string              `json:"id,omitempty"`
+	TableID    string              `json:"table_id,omitempty"`
+	TemplateID string              `json:"template_id,omitempty"`
+	Name       string              `json:"name,omitempty"`
+	Schedule   *RecurrenceSchedule `json:"schedule,omitempty"`
+	// 节假日日历，为空时不考虑节假日
+	CalendarID string `json:"calendar_id,omitempty"`
+	// skip（跳过）或 shift（顺延到下一个非节假日）
+	HolidayPolicy string `json:"holiday_policy,omitempty"`
+	// 单独跳过的日期（YYYY-MM-DD）
+	SkipDates []string `json:"skip_dates,omitempty"`
+	// 停机后补跑方式，all（逐次补建）或 latest（只补最近一次）
+	CatchUp string `json:"catch_up,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+	// 已处理到的计划时间
+	Cursor time.Time `json:"cursor,omitempty"`
+	// 为空表示计划已结束
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	CreatedBy string     `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at,omitempty"`
+}
+
+// RecurrenceRun 重复规则的一次执行结果
+type RecurrenceRun struct {
+	ID          string    `json:"id,omitempty"`
+	RuleID      string    `json:"rule_id,omitempty"`
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+	RunAt       time.Time `json:"run_at,omitempty"`
+	// created、skipped 或 failed
+	Status string `json:"status,omitempty"`
+	// 跳过原因
+	Reason    string    `json:"reason,omitempty"`
+	RecordID  string    `json:"record_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+// RecurrenceSchedule 重复计划（按时区的本地日期与时间计算）
+type RecurrenceSchedule struct {
+	// daily、weekly 或 monthly
+	Frequency string `json:"frequency"`
+	// 每 N 个周期，默认 1（以开始日期所在的天、周、月为起点）
+	Interval int `json:"interval,omitempty"`
+	// 0=周日 … 6=周六；weekly 必填，daily 可用于限定星期
+	Weekdays []int `json:"weekdays,omitempty"`
+	// monthly 必填，1-31，-1 表示月末；当月没有该日期时不执行
+	MonthDays []int `json:"month_days,omitempty"`
+	// HH:MM，默认 00:00
+	Time string `json:"time,omitempty"`
+	// IANA 时区，默认 UTC
+	Timezone string `json:"timezone,omitempty"`
+	// YYYY-MM-DD，默认创建当天
+	StartDate string `json:"start_date,omitempty"`
+	// YYYY-MM-DD，为空时不结束
+	EndDate string `json:"end_date,omitempty"`
+}
+
+// RefreshTokenRequest 对应 api/openapi.yaml 中的 RefreshTokenRequest
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RegenerateAIFieldRequest 对应 api/openapi.yaml 中的 RegenerateAIFieldRequest
+type RegenerateAIFieldRequest struct {
+	// 为空时重新生成整列
+	RecordIds []string `json:"recordIds,omitempty"`
+}
+
+// RegenerateAIFieldResult 对应 api/openapi.yaml 中的 RegenerateAIFieldResult
+type RegenerateAIFieldResult struct {
+	FieldID string `json:"field_id,omitempty"`
+	// 新建的任务数
+	Enqueued int `json:"enqueued,omitempty"`
+	// 已有待执行任务而跳过的单元格数
+	Pending int `json:"pending,omitempty"`
+}
+
+// ReorderFieldsRequest 对应 api/openapi.yaml 中的 ReorderFieldsRequest
+type ReorderFieldsRequest struct {
+	// 表中全部字段的ID，按新顺序排列
+	FieldIds []string `json:"fieldIds"`
+}
+
+// ResetUserPasswordRequest 对应 api/openapi.yaml 中的 ResetUserPasswordRequest
+type ResetUserPasswordRequest struct {
+	// 新密码，为空时生成临时密码
+	Password string `json:"password,omitempty"`
+}
+
+// ResetUserPasswordResponse 对应 api/openapi.yaml 中的 ResetUserPasswordResponse
+type ResetUserPasswordResponse struct {
+	UserID string `json:"user_id,omitempty"`
+	// 生成的临时密码（只返回这一次）
+	TemporaryPassword string `json:"temporary_password,omitempty"`
+	RevokedSessions   int    `json:"revoked_sessions,omitempty"`
+}
+
+// RestoreArchivedRequest 对应 api/openapi.yaml 中的 RestoreArchivedRequest
+type RestoreArchivedRequest struct {
+	RecordIds []string `json:"recordIds"`
+}
+
+// RestoreArchivedResult 对应 api/openapi.yaml 中的 RestoreArchivedResult
+type RestoreArchivedResult struct {
+	Restored []string `json:"restored,omitempty"`
+}
+
+// RichTextHTML 净化后的 HTML（richText 字段的存储格式）；记录提及为 <span data-record-mention="tblId/recId">@标题</span>
+type RichTextHTML struct {
+	Html string `json:"html,omitempty"`
+}
+
+// RichTextMarkdown 对应 api/openapi.yaml 中的 RichTextMarkdown
+type RichTextMarkdown struct {
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// RunPageButtonRequest 对应 api/openapi.yaml 中的 RunPageButtonRequest
+type RunPageButtonRequest struct {
+	// 按钮绑定了表格时必填
+	RecordID string `json:"recordId,omitempty"`
+}
+
+// RunScriptRequest 对应 api/openapi.yaml 中的 RunScriptRequest
+type RunScriptRequest struct {
+	// 脚本代码（作为函数体执行，可用 return 返回结果）
+	Script string `json:"script"`
+	// 脚本中的 input 对象
+	Input map[string]interface{} `json:"input,omitempty"`
+	// 所属工作流（用于审计日志）
+	WorkflowID string `json:"workflowId,omitempty"`
+}
+
+// RunTextExtractionRequest 对应 api/openapi.yaml 中的 RunTextExtractionRequest
+type RunTextExtractionRequest struct {
+	// 为空时处理整表
+	RecordIds []string `json:"recordIds,omitempty"`
+}
+
+// RunTextExtractionResponse 对应 api/openapi.yaml 中的 RunTextExtractionResponse
+type RunTextExtractionResponse struct {
+	RuleID string `json:"rule_id,omitempty"`
+	// 新建的任务数
+	Enqueued int `json:"enqueued,omitempty"`
+	// 已有待执行任务而跳过的记录数
+	Pending int `json:"pending,omitempty"`
+}
+
+// SaveArchivePolicyRequest 对应 api/openapi.yaml 中的 SaveArchivePolicyRequest
+type SaveArchivePolicyRequest struct {
+	// 默认 createdTime
+	Basis string `json:"basis,omitempty"`
+	// 早于多少天的记录归档
+	AfterDays int `json:"afterDays"`
+	// 为空时启用
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// SaveBusinessCalendarRequest 整体替换工作日历，weekends 不传时为周六、周日
+type SaveBusinessCalendarRequest struct {
+	Weekends []int    `json:"weekends,omitempty"`
+	Holidays []string `json:"holidays,omitempty"`
+	Workdays []string `json:"workdays,omitempty"`
+}
+
+// SaveHolidayCalendarRequest 对应 api/openapi.yaml 中的 SaveHolidayCalendarRequest
+type SaveHolidayCalendarRequest struct {
+	Name string `json:"name"`
+	// YYYY-MM-DD
+	Dates []string `json:"dates,omitempty"`
+}
+
+// SaveRecordTemplateRequest 对应 api/openapi.yaml 中的 SaveRecordTemplateRequest
+type SaveRecordTemplateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Values      Fields `json:"values,omitempty"`
+	IsDefault   bool   `json:"isDefault,omitempty"`
+}
+
+// SaveScanRuleRequest 对应 api/openapi.yaml 中的 SaveScanRuleRequest
+type SaveScanRuleRequest struct {
+	// 默认 none
+	OnMissing        string `json:"onMissing,omitempty"`
+	RecordTemplateID string `json:"recordTemplateId,omitempty"`
+}
+
+// SaveStatusFlowRequest 对应 api/openapi.yaml 中的 SaveStatusFlowRequest
+type SaveStatusFlowRequest struct {
+	InitialStatuses []string                `json:"initialStatuses,omitempty"`
+	Transitions     []*StatusFlowTransition `json:"transitions"`
+	// 为空时启用
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ScanRequest 对应 api/openapi.yaml 中的 ScanRequest
+type ScanRequest struct {
+	// 按该字段（文本或数字）精确匹配编码
+	FieldID string `json:"fieldId"`
+	// 扫到的编码，首尾空白与控制字符被忽略
+	Code string `json:"code"`
+}
+
+// ScanResult 扫码结果，未找到且未新建时 record 为空
+type ScanResult struct {
+	Code    string `json:"code,omitempty"`
+	FieldID string `json:"fieldId,omitempty"`
+	Found   bool   `json:"found,omitempty"`
+	// 按扫码规则新建了记录
+	Created bool `json:"created,omitempty"`
+	// 有多条记录匹配，返回最早创建的一条
+	Ambiguous bool    `json:"ambiguous,omitempty"`
+	Record    *Record `json:"record,omitempty"`
+}
+
+// ScanRule 字段的扫码规则
+type ScanRule struct {
+	ID        string `json:"id,omitempty"`
+	TableID   string `json:"table_id,omitempty"`
+	FieldID   string `json:"field_id,omitempty"`
+	OnMissing string `json:"on_missing,omitempty"`
+	// 新建记录时其余单元格取自该记录模板
+	RecordTemplateID string    `json:"record_template_id,omitempty"`
+	CreatedBy        string    `json:"created_by,omitempty"`
+	UpdatedBy        string    `json:"updated_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+}
+
+// ScheduleWorkspaceDeletionRequest 对应 api/openapi.yaml 中的 ScheduleWorkspaceDeletionRequest
+type ScheduleWorkspaceDeletionRequest struct {
+	// 宽限期天数，为 0 时使用默认值
+	GraceDays int    `json:"graceDays,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// SchemaChange 对应 api/openapi.yaml 中的 SchemaChange
+type SchemaChange struct {
+	// create_table、update_field、delete_view 等
+	Kind  string `json:"kind,omitempty"`
+	Table string `json:"table,omitempty"`
+	// 字段名或视图名
+	Name     string   `json:"name,omitempty"`
+	TargetID string   `json:"targetId,omitempty"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// SchemaConflict 对应 api/openapi.yaml 中的 SchemaConflict
+type SchemaConflict struct {
+	Table  string `json:"table,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// SchemaPlan 结构描述与 Base 的差异，或应用时实际执行的变更
+type SchemaPlan struct {
+	Changes   []*SchemaChange   `json:"changes,omitempty"`
+	Conflicts []*SchemaConflict `json:"conflicts,omitempty"`
+}
+
+// SchemaSpecDocument 对应 api/openapi.yaml 中的 SchemaSpecDocument
+type SchemaSpecDocument struct {
+	YAML string `json:"yaml,omitempty"`
+}
+
+// SchemaSpecRequest 对应 api/openapi.yaml 中的 SchemaSpecRequest
+type SchemaSpecRequest struct {
+	// YAML 结构描述
+	YAML string `json:"yaml"`
+	// 删除描述中未声明的表、字段与视图
+	Prune bool `json:"prune,omitempty"`
+}
+
+// ScriptLogEntry 对应 api/openapi.yaml 中的 ScriptLogEntry
+type ScriptLogEntry struct {
+	Level   string    `json:"level,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time,omitempty"`
+}
+
+// ScriptRunResult 对应 api/openapi.yaml 中的 ScriptRunResult
+type ScriptRunResult struct {
+	Status string `json:"status,omitempty"`
+	// 失败原因（超时、内存或 API 调用超限、脚本异常）
+	Error  string      `json:"error,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	// 通过 output.set 设置的输出
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+	Logs    []*ScriptLogEntry      `json:"logs,omitempty"`
+	// 日志或输出超出上限被截断
+	Truncated bool `json:"truncated,omitempty"`
+	ApiCalls  int  `json:"apiCalls,omitempty"`
+	// 执行时间（纳秒）
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// SemanticIndex 表的语义索引（每张表最多一个）
+type SemanticIndex struct {
+	ID       string   `json:"id,omitempty"`
+	SpaceID  string   `json:"space_id,omitempty"`
+	BaseID   string   `json:"base_id,omitempty"`
+	TableID  string   `json:"table_id,omitempty"`
+	FieldIds []string `json:"field_ids,omitempty"`
+	Provider string   `json:"provider,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	// 首次生成向量后确定
+	Dimensions int `json:"dimensions,omitempty"`
+	// backfilling（正在为已有记录生成向量）或 ready
+	Status string `json:"status,omitempty"`
+	// 回填进度（已处理记录的最大自增序号）
+	BackfillAfter int64 `json:"backfill_after,omitempty"`
+	// 已消费的变更流序号
+	Cursor    int64     `json:"cursor,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// SemanticSearchHit 对应 api/openapi.yaml 中的 SemanticSearchHit
+type SemanticSearchHit struct {
+	RecordID string `json:"recordId,omitempty"`
+	// 语义检索为余弦相似度，其余为融合排名得分
+	Score  float64 `json:"score,omitempty"`
+	Record *Record `json:"record,omitempty"`
+}
+
+// SemanticSearchRequest 对应 api/openapi.yaml 中的 SemanticSearchRequest
+type SemanticSearchRequest struct {
+	Query string `json:"query"`
+	// hybrid（默认）、semantic 或 keyword
+	Mode  string `json:"mode,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// SemanticSearchResult 对应 api/openapi.yaml 中的 SemanticSearchResult
+type SemanticSearchResult struct {
+	// 实际使用的搜索方式（语义检索不可用时混合搜索退化为 keyword）
+	Mode        string               `json:"mode,omitempty"`
+	IndexStatus string               `json:"indexStatus,omitempty"`
+	Hits        []*SemanticSearchHit `json:"hits,omitempty"`
+}
+
+// ServiceToken 对应 api/openapi.yaml 中的 ServiceToken
+type ServiceToken struct {
+	ID         string     `json:"id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	UserID     string     `json:"user_id,omitempty"`
+	Hint       string     `json:"hint,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+}
+
+// SetFeatureRequest 对应 api/openapi.yaml 中的 SetFeatureRequest
+type SetFeatureRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// Space 对应 api/openapi.yaml 中的 Space
+type Space struct {
+	ID          string    `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"createdBy,omitempty"`
+	UpdatedBy   string    `json:"updatedBy,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+// SpaceOwnershipTransfer 对应 api/openapi.yaml 中的 SpaceOwnershipTransfer
+type SpaceOwnershipTransfer struct {
+	SpaceID        string   `json:"space_id,omitempty"`
+	NewOwnerID     string   `json:"new_owner_id,omitempty"`
+	PreviousOwners []string `json:"previous_owners,omitempty"`
+}
+
+// SpaceSubscription 工作空间的 Stripe 订阅。降级在期末生效时 pending_plan_id 为待生效的套餐
+type SpaceSubscription struct {
+	SpaceID        string `json:"space_id,omitempty"`
+	CustomerID     string `json:"customer_id,omitempty"`
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	PriceID        string `json:"price_id,omitempty"`
+	// Stripe 订阅状态，如 active、trialing、past_due、canceled
+	Status            string    `json:"status,omitempty"`
+	PlanID            string    `json:"plan_id,omitempty"`
+	PendingPlanID     string    `json:"pending_plan_id,omitempty"`
+	CurrentPeriodEnd  time.Time `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd bool      `json:"cancel_at_period_end,omitempty"`
+	PastDueSince      time.Time `json:"past_due_since,omitempty"`
+	LastEventAt       time.Time `json:"last_event_at,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty"`
+}
+
+// StartImpersonationRequest 对应 api/openapi.yaml 中的 StartImpersonationRequest
+type StartImpersonationRequest struct {
+	TargetUserID string `json:"targetUserId,omitempty"`
+	// 与 targetUserId 二选一
+	TargetEmail string `json:"targetEmail,omitempty"`
+	// 工单号或问题描述
+	Reason string `json:"reason"`
+	// 代入时长，0 表示使用默认值
+	DurationMinutes int `json:"durationMinutes,omitempty"`
+}
+
+// StartMaintenanceRequest 对应 api/openapi.yaml 中的 StartMaintenanceRequest
+type StartMaintenanceRequest struct {
+	Scope string `json:"scope"`
+	// 工作空间或 Base ID，实例范围不需要
+	ScopeID string `json:"scopeId,omitempty"`
+	Message string `json:"message,omitempty"`
+	// 计划结束时间，为空时需要手动结束
+	EndsAt time.Time `json:"endsAt,omitempty"`
+}
+
+// StartOperationRequest 对应 api/openapi.yaml 中的 StartOperationRequest
+type StartOperationRequest struct {
+	// 操作类型，如 record_import、find_replace、find_replace_undo
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// StartedImpersonation 新开始的代入会话，token 为明文代入令牌，只返回这一次
+type StartedImpersonation struct {
+	ID           string    `json:"id,omitempty"`
+	TargetUserID string    `json:"target_user_id,omitempty"`
+	TargetEmail  string    `json:"target_email,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	Token        string    `json:"token,omitempty"`
+}
+
+// StatusFlow 单选字段的状态流（启用后修改该字段必须匹配一条流转规则，流转写入审计日志与变更流）
+type StatusFlow struct {
+	ID      string `json:"id,omitempty"`
+	TableID string `json:"table_id,omitempty"`
+	FieldID string `json:"field_id,omitempty"`
+	// 新记录允许的初始状态，为空时不限制
+	InitialStatuses []string                `json:"initial_statuses,omitempty"`
+	Transitions     []*StatusFlowTransition `json:"transitions,omitempty"`
+	Enabled         bool                    `json:"enabled,omitempty"`
+	CreatedBy       string                  `json:"created_by,omitempty"`
+	UpdatedBy       string                  `json:"updated_by,omitempty"`
+	CreatedAt       time.Time               `json:"created_at,omitempty"`
+	UpdatedAt       time.Time               `json:"updated_at,omitempty"`
+}
+
+// StatusFlowTransition 对应 api/openapi.yaml 中的 StatusFlowTransition
+type StatusFlowTransition struct {
+	// 流转名称，如“提交审批”“批准”
+	Name string `json:"name,omitempty"`
+	// 起始状态（选项名称），"*" 表示任意状态，空字符串表示空
+	From string `json:"from,omitempty"`
+	// 目标状态（选项名称）
+	To string `json:"to,omitempty"`
+	// 执行该流转需要的协作者角色（owner、creator、editor），为空时有记录编辑权限即可
+	ApproverRoles []string `json:"approverRoles,omitempty"`
+}
+
+// SuspendWorkspaceRequest 对应 api/openapi.yaml 中的 SuspendWorkspaceRequest
+type SuspendWorkspaceRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// SyncedTable 同步表（将其他 Base 的视图镜像为只读表，按间隔从源 Base 的变更流增量同步）
+type SyncedTable struct {
+	ID            string `json:"id,omitempty"`
+	Name          string `json:"name,omitempty"`
+	SourceBaseID  string `json:"source_base_id,omitempty"`
+	SourceTableID string `json:"source_table_id,omitempty"`
+	SourceViewID  string `json:"source_view_id,omitempty"`
+	TargetBaseID  string `json:"target_base_id,omitempty"`
+	// 镜像表（只读，只能由同步写入）
+	TargetTableID string `json:"target_table_id,omitempty"`
+	// 源字段ID到镜像字段ID的对应关系
+	FieldMap map[string]string `json:"field_map,omitempty"`
+	// 镜像表中的“源记录已删除”复选框字段
+	DeletedFieldID string `json:"deleted_field_id,omitempty"`
+	// 增量同步间隔（纳秒）
+	Interval int64 `json:"interval,omitempty"`
+	Enabled  bool  `json:"enabled,omitempty"`
+	// 已同步的源 Base 变更流序号
+	Cursor    int64      `json:"cursor,omitempty"`
+	NextRunAt time.Time  `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	// succeeded 或 failed
+	LastStatus string    `json:"last_status,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	CreatedBy  string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at,omitempty"`
+}
+
+// SystemStats 对应 api/openapi.yaml 中的 SystemStats
+type SystemStats struct {
+	Users int64 `json:"users,omitempty"`
+	// active_since 之后登录过的用户
+	ActiveUsers     int64     `json:"active_users,omitempty"`
+	Admins          int64     `json:"admins,omitempty"`
+	Spaces          int64     `json:"spaces,omitempty"`
+	Bases           int64     `json:"bases,omitempty"`
+	Tables          int64     `json:"tables,omitempty"`
+	Attachments     int64     `json:"attachments,omitempty"`
+	AttachmentBytes int64     `json:"attachment_bytes,omitempty"`
+	ActiveSince     time.Time `json:"active_since,omitempty"`
+	GeneratedAt     time.Time `json:"generated_at,omitempty"`
+}
+
+// Table 对应 api/openapi.yaml 中的 Table
+type Table struct {
+	ID          string    `json:"id,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	BaseID      string    `json:"baseId,omitempty"`
+	FieldCount  int       `json:"fieldCount,omitempty"`
+	RecordCount int64     `json:"recordCount,omitempty"`
+	CreatedAt   time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+}
+
+// TableHealthFixJob 一键修复任务；逐条重新检查当前值，记录在此期间被修改时跳过
+type TableHealthFixJob struct {
+	ID       string   `json:"id,omitempty"`
+	ReportID string   `json:"report_id,omitempty"`
+	TableID  string   `json:"table_id,omitempty"`
+	IssueIds []string `json:"issue_ids,omitempty"`
+	// pending、running、completed 或 failed
+	Status     string     `json:"status,omitempty"`
+	Fixed      int        `json:"fixed,omitempty"`
+	Skipped    int        `json:"skipped,omitempty"`
+	Failed     int        `json:"failed,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableHealthIssue 检查发现的一个问题（record_id 为空表示字段级问题）
+type TableHealthIssue struct {
+	ID       string `json:"id,omitempty"`
+	ReportID string `json:"report_id,omitempty"`
+	TableID  string `json:"table_id,omitempty"`
+	// validation、orphaned_link、formula_error 或 type_coercion
+	Kind      string `json:"kind,omitempty"`
+	FieldID   string `json:"field_id,omitempty"`
+	FieldName string `json:"field_name,omitempty"`
+	RecordID  string `json:"record_id,omitempty"`
+	// 发现问题时的单元格值；失效关联为已不存在的记录ID
+	Value   interface{} `json:"value,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Fixable bool        `json:"fixable,omitempty"`
+	// open、fixed、skipped 或 failed
+	Status   string `json:"status,omitempty"`
+	FixError string `json:"fix_error,omitempty"`
+}
+
+// TableHealthIssueList 对应 api/openapi.yaml 中的 TableHealthIssueList
+type TableHealthIssueList struct {
+	Issues []*TableHealthIssue `json:"issues,omitempty"`
+	Total  int64               `json:"total,omitempty"`
+}
+
+// TableHealthReport 表健康检查报告；问题数超过保存上限时只保存前面的问题，统计仍覆盖全部扫描的记录
+type TableHealthReport struct {
+	ID      string `json:"id,omitempty"`
+	TableID string `json:"table_id,omitempty"`
+	// manual 或 scheduled
+	Trigger string `json:"trigger,omitempty"`
+	// pending、running、completed 或 failed
+	Status string `json:"status,omitempty"`
+	// 已检查的记录数
+	Scanned int `json:"scanned,omitempty"`
+	// 记录数超过扫描上限，或问题数超过保存上限
+	Truncated bool `json:"truncated,omitempty"`
+	// 问题类型（validation、orphaned_link、formula_error、type_coercion）→ 数量
+	Counts map[string]int `json:"counts,omitempty"`
+	// 可自动修复的问题数
+	Fixable    int        `json:"fixable,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// TableHealthSchedule 对应 api/openapi.yaml 中的 TableHealthSchedule
+type TableHealthSchedule struct {
+	TableID       string     `json:"table_id,omitempty"`
+	IntervalHours int        `json:"interval_hours,omitempty"`
+	Enabled       bool       `json:"enabled,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	// 定时检查以最后修改人的身份创建
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// TextExtractionJob 附件文本提取任务（一个任务对应一条记录）
+type TextExtractionJob struct {
+	ID       string `json:"id,omitempty"`
+	RuleID   string `json:"rule_id,omitempty"`
+	TableID  string `json:"table_id,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	// create、source_changed 或 manual
+	Trigger string `json:"trigger,omitempty"`
+	// pending、running、succeeded 或 failed
+	Status   string `json:"status,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Engine   string `json:"engine,omitempty"`
+	// 成功提取文本的附件数
+	Files int `json:"files,omitempty"`
+	// 类型不支持、过大、未通过扫描或无法解析而跳过的附件数
+	Skipped int `json:"skipped,omitempty"`
+	// 写入目标字段的字符数
+	Chars      int        `json:"chars,omitempty"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	NextRunAt  time.Time  `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// TextExtractionRule 附件文本提取规则（附件字段 → 长文本字段）
+type TextExtractionRule struct {
+	ID      string `json:"id,omitempty"`
+	TableID string `json:"table_id,omitempty"`
+	// 附件字段
+	SourceFieldID string `json:"source_field_id,omitempty"`
+	// 长文本或单行文本字段
+	TargetFieldID string `json:"target_field_id,omitempty"`
+	// OCR 语言（Tesseract 语言代码），为空时使用服务端默认语言
+	Languages []string  `json:"languages,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// TokenResponse 对应 api/openapi.yaml 中的 TokenResponse
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+}
+
+// TransferSpaceOwnershipRequest 对应 api/openapi.yaml 中的 TransferSpaceOwnershipRequest
+type TransferSpaceOwnershipRequest struct {
+	NewOwnerID string `json:"newOwnerId"`
+}
+
+// UpdateAIQuotaRequest 对应 api/openapi.yaml 中的 UpdateAIQuotaRequest
+type UpdateAIQuotaRequest struct {
+	MonthlyRequests     int64 `json:"monthlyRequests,omitempty"`
+	MonthlyTokens       int64 `json:"monthlyTokens,omitempty"`
+	MaxTokensPerRequest int   `json:"maxTokensPerRequest,omitempty"`
+}
+
+// UpdateAttachmentPolicyRequest 对应 api/openapi.yaml 中的 UpdateAttachmentPolicyRequest
+type UpdateAttachmentPolicyRequest struct {
+	MaxFileSizeMb     int      `json:"maxFileSizeMb,omitempty"`
+	AllowedTypes      []string `json:"allowedTypes,omitempty"`
+	BlockedExtensions []string `json:"blockedExtensions,omitempty"`
+	ScanRequired      bool     `json:"scanRequired,omitempty"`
+	WebhookURL        string   `json:"webhookUrl,omitempty"`
+}
+
+// UpdateDocumentTemplateRequest 对应 api/openapi.yaml 中的 UpdateDocumentTemplateRequest
+type UpdateDocumentTemplateRequest struct {
+	Name string `json:"name,omitempty"`
+	// 替换模板文件，同名合并域保留原有映射
+	AttachmentID  string `json:"attachmentId,omitempty"`
+	Output        string `json:"output,omitempty"`
+	TargetFieldID string `json:"targetFieldId,omitempty"`
+	NameFieldID   string `json:"nameFieldId,omitempty"`
+	// 提供时整体替换映射
+	Mappings map[string]string `json:"mappings,omitempty"`
+}
+
+// UpdateExternalSyncRequest 未提供的属性保持不变
+type UpdateExternalSyncRequest struct {
+	Name        string `json:"name,omitempty"`
+	Credentials string `json:"credentials,omitempty"`
+	Interval    string `json:"interval,omitempty"`
+	Enabled     bool   `json:"enabled,omitempty"`
+}
+
+// UpdateHolidayCalendarRequest 未提供的属性保持不变，dates 提供时整体替换
+type UpdateHolidayCalendarRequest struct {
+	Name  *string  `json:"name,omitempty"`
+	Dates []string `json:"dates,omitempty"`
+}
+
+// UpdatePageRecordRequest 对应 api/openapi.yaml 中的 UpdatePageRecordRequest
+type UpdatePageRecordRequest struct {
+	Data Fields `json:"data"`
+}
+
+// UpdatePageRequest 未提供的属性保持不变，elements 与 rules 整体替换
+type UpdatePageRequest struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Elements    []*PageElement    `json:"elements,omitempty"`
+	Rules       []*PageAccessRule `json:"rules,omitempty"`
+}
+
+// UpdateRecordRequest 对应 api/openapi.yaml 中的 UpdateRecordRequest
+type UpdateRecordRequest struct {
+	Data Fields `json:"data,omitempty"`
+	// 期望的当前版本（乐观锁），为空时不校验
+	Version *int `json:"version,omitempty"`
+}
+
+// UpdateRecordTemplateRequest 未提供的属性保持不变，values 提供时整体替换
+type UpdateRecordTemplateRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Values      Fields `json:"values,omitempty"`
+	IsDefault   *bool  `json:"isDefault,omitempty"`
+}
+
+// UpdateRecurrenceRuleRequest 未提供的属性保持不变；修改计划或重新启用时从当前时间重新开始，不补跑之前的执行
+type UpdateRecurrenceRuleRequest struct {
+	Name       *string             `json:"name,omitempty"`
+	TemplateID *string             `json:"templateId,omitempty"`
+	Schedule   *RecurrenceSchedule `json:"schedule,omitempty"`
+	// 空字符串表示不使用日历
+	CalendarID    *string  `json:"calendarId,omitempty"`
+	HolidayPolicy *string  `json:"holidayPolicy,omitempty"`
+	SkipDates     []string `json:"skipDates,omitempty"`
+	CatchUp       *string  `json:"catchUp,omitempty"`
+	Enabled       *bool    `json:"enabled,omitempty"`
+}
+
+// UpdateSyncedTableRequest 未提供的属性保持不变
+type UpdateSyncedTableRequest struct {
+	Name     string `json:"name,omitempty"`
+	Interval string `json:"interval,omitempty"`
+	Enabled  bool   `json:"enabled,omitempty"`
+}
+
+// UpdateTableHealthScheduleRequest 对应 api/openapi.yaml 中的 UpdateTableHealthScheduleRequest
+type UpdateTableHealthScheduleRequest struct {
+	// 1-720 小时
+	IntervalHours int  `json:"intervalHours"`
+	Enabled       bool `json:"enabled,omitempty"`
+}
+
+// UploadProgress 可续传上传进度（分片通过 tus 协议的 HEAD / PATCH 上传）
+type UploadProgress struct {
+	ID       string `json:"id,omitempty"`
+	UserID   string `json:"user_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	Length   int64  `json:"length,omitempty"`
+	// 已接收的字节数
+	Offset int64 `json:"offset,omitempty"`
+	// uploading 或 completed（已写入存储，等待 notify）
+	Status string `json:"status,omitempty"`
+	// 0-100
+	Progress  float64   `json:"progress,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// UsageBase 对应 api/openapi.yaml 中的 UsageBase
+type UsageBase struct {
+	BaseID   string           `json:"base_id,omitempty"`
+	BaseName string           `json:"base_name,omitempty"`
+	Values   map[string]int64 `json:"values,omitempty"`
+}
+
+// UsageDay 对应 api/openapi.yaml 中的 UsageDay
+type UsageDay struct {
+	Day    time.Time        `json:"day,omitempty"`
+	Values map[string]int64 `json:"values,omitempty"`
+}
+
+// UsageReport 工作空间用量报表。计数类指标按天累加，storage_bytes 取区间最后一天，active_collaborators 取单日峰值
+type UsageReport struct {
+	SpaceID string    `json:"space_id,omitempty"`
+	From    time.Time `json:"from,omitempty"`
+	To      time.Time `json:"to,omitempty"`
+	// 键为 records_created、api_calls、automation_runs、storage_bytes、active_collaborators
+	Totals map[string]int64 `json:"totals,omitempty"`
+	Days   []*UsageDay      `json:"days,omitempty"`
+	Bases  []*UsageBase     `json:"bases,omitempty"`
+	Tokens []*UsageToken    `json:"tokens,omitempty"`
+}
+
+// UsageToken 对应 api/openapi.yaml 中的 UsageToken
+type UsageToken struct {
+	TokenID string `json:"token_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	UserID  string `json:"user_id,omitempty"`
+	Calls   int64  `json:"calls,omitempty"`
+}
+
+// User 对应 api/openapi.yaml 中的 User
+type User struct {
+	ID        string    `json:"id,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Avatar    string    `json:"avatar,omitempty"`
+	IsActive  bool      `json:"isActive,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
+
+// ValidateCellsRequest 对应 api/openapi.yaml 中的 ValidateCellsRequest
+type ValidateCellsRequest struct {
+	// 每行按字段ID或名称给出值（单次最多 1000 行）
+	Rows []Fields `json:"rows"`
+	// create（默认）同时检查必填字段，update 只检查给出的字段
+	Mode string `json:"mode,omitempty"`
+	// 与写入时相同，尝试自动转换类型
+	Typecast bool `json:"typecast,omitempty"`
+}
+
+// ValidateCellsResult 对应 api/openapi.yaml 中的 ValidateCellsResult
+type ValidateCellsResult struct {
+	Valid bool `json:"valid,omitempty"`
+	// 校验（及类型转换）后按字段ID的值
+	Rows   []Fields               `json:"rows,omitempty"`
+	Errors []*CellValidationError `json:"errors,omitempty"`
+}
+
+// ViewDisplayCell 对应 api/openapi.yaml 中的 ViewDisplayCell
+type ViewDisplayCell struct {
+	// 原始值
+	Value interface{} `json:"value,omitempty"`
+	// 展示文本
+	Text string `json:"text,omitempty"`
+	// 选项、协作者或关联记录
+	Items []*ViewDisplayCellItem `json:"items,omitempty"`
+}
+
+// ViewDisplayCellItem 对应 api/openapi.yaml 中的 ViewDisplayCellItem
+type ViewDisplayCellItem struct {
+	ID    string `json:"id,omitempty"`
+	Title string `json:"title,omitempty"`
+	Color string `json:"color,omitempty"`
+}
+
+// ViewDisplayField 对应 api/openapi.yaml 中的 ViewDisplayField
+type ViewDisplayField struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type,omitempty"`
+}
+
+// ViewDisplayPage 按视图顺序的一页展示行（隐藏字段不返回，受脱敏策略保护的字段按当前用户脱敏）
+type ViewDisplayPage struct {
+	ViewID  string              `json:"viewId,omitempty"`
+	TableID string              `json:"tableId,omitempty"`
+	Fields  []*ViewDisplayField `json:"fields,omitempty"`
+	Rows    []*ViewDisplayRow   `json:"rows,omitempty"`
+	Total   int                 `json:"total,omitempty"`
+	Offset  int                 `json:"offset,omitempty"`
+	Limit   int                 `json:"limit,omitempty"`
+	// 视图记录超过投影上限，只能读取前面的记录
+	Truncated bool      `json:"truncated,omitempty"`
+	BuiltAt   time.Time `json:"builtAt,omitempty"`
+}
+
+// ViewDisplayRow 对应 api/openapi.yaml 中的 ViewDisplayRow
+type ViewDisplayRow struct {
+	ID    string                      `json:"id,omitempty"`
+	Cells map[string]*ViewDisplayCell `json:"cells,omitempty"`
+}
+
+// ViewFilter 对应 api/openapi.yaml 中的 ViewFilter
+type ViewFilter struct {
+	// and 或 or
+	Operator string            `json:"operator,omitempty"`
+	Filters  []*ViewFilterItem `json:"filters,omitempty"`
+}
+
+// ViewFilterItem 对应 api/openapi.yaml 中的 ViewFilterItem
+type ViewFilterItem struct {
+	FieldID string `json:"fieldId,omitempty"`
+	// is、isGreater、hasAnyOf、isWithin 等视图过滤操作符
+	Operator string `json:"operator,omitempty"`
+	// 条件值，isWithin 为两个元素的数组
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ViewSortItem 对应 api/openapi.yaml 中的 ViewSortItem
+type ViewSortItem struct {
+	FieldID string `json:"fieldId,omitempty"`
+	// asc 或 desc
+	Order string `json:"order,omitempty"`
+}
+
+// ViewWindow 按视图过滤、分组与排序的一个行窗口（隐藏字段不返回，受脱敏策略保护的字段按当前用户脱敏）；首行之前含标题行共 offset + headersBefore 行
+type ViewWindow struct {
+	ViewID  string `json:"viewId,omitempty"`
+	TableID string `json:"tableId,omitempty"`
+	// 布局代数，变化说明视图数据已变化，之前读取的窗口需要重新读取
+	Generation    int      `json:"generation,omitempty"`
+	Offset        int      `json:"offset,omitempty"`
+	Count         int      `json:"count,omitempty"`
+	Total         int      `json:"total,omitempty"`
+	GroupFieldIds []string `json:"groupFieldIds,omitempty"`
+	// 各级分组标题的总数
+	TotalGroups int `json:"totalGroups,omitempty"`
+	// 分组过多，之后的分组边界未返回
+	GroupsTruncated bool `json:"groupsTruncated,omitempty"`
+	// 首行之前开始的分组标题数
+	HeadersBefore int `json:"headersBefore,omitempty"`
+	// 与窗口相交的分组（父级在子级之前）
+	Groups  []*ViewWindowGroup `json:"groups,omitempty"`
+	Records []*Record          `json:"records,omitempty"`
+}
+
+// ViewWindowGroup 对应 api/openapi.yaml 中的 ViewWindowGroup
+type ViewWindowGroup struct {
+	// 分组层级（0 为一级分组）
+	Level   int    `json:"level,omitempty"`
+	FieldID string `json:"fieldId,omitempty"`
+	// 分组值
+	Value interface{} `json:"value,omitempty"`
+	// 分组首行的行号（从 0 开始，不含标题行）
+	Start int `json:"start,omitempty"`
+	Count int `json:"count,omitempty"`
+}
+
+// WorkspaceBundleAttachments 对应 api/openapi.yaml 中的 WorkspaceBundleAttachments
+type WorkspaceBundleAttachments struct {
+	Prefix string `json:"prefix,omitempty"`
+	Files  int    `json:"files,omitempty"`
+	Bytes  int64  `json:"bytes,omitempty"`
+	// 存储中已不存在的文件数
+	Missing int `json:"missing,omitempty"`
+}
+
+// WorkspaceBundleFile 对应 api/openapi.yaml 中的 WorkspaceBundleFile
+type WorkspaceBundleFile struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+	Rows int64  `json:"rows,omitempty"`
+}
+
+// WorkspaceBundleManifest 对应 api/openapi.yaml 中的 WorkspaceBundleManifest
+type WorkspaceBundleManifest struct {
+	Format      string                      `json:"format,omitempty"`
+	BundleID    string                      `json:"bundle_id,omitempty"`
+	SpaceID     string                      `json:"space_id,omitempty"`
+	CreatedAt   time.Time                   `json:"created_at,omitempty"`
+	Metadata    []*WorkspaceBundleFile      `json:"metadata,omitempty"`
+	Tables      []*WorkspaceBundleTable     `json:"tables,omitempty"`
+	Attachments *WorkspaceBundleAttachments `json:"attachments,omitempty"`
+}
+
+// WorkspaceBundleTable 对应 api/openapi.yaml 中的 WorkspaceBundleTable
+type WorkspaceBundleTable struct {
+	BaseID  string `json:"base_id,omitempty"`
+	TableID string `json:"table_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Rows    int64  `json:"rows,omitempty"`
+}
+
+// WorkspaceExportBundle 对应 api/openapi.yaml 中的 WorkspaceExportBundle
+type WorkspaceExportBundle struct {
+	ID         string                   `json:"id,omitempty"`
+	SpaceID    string                   `json:"space_id,omitempty"`
+	Status     string                   `json:"status,omitempty"`
+	Manifest   *WorkspaceBundleManifest `json:"manifest,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+	CreatedBy  string                   `json:"created_by,omitempty"`
+	CreatedAt  time.Time                `json:"created_at,omitempty"`
+	UpdatedAt  time.Time                `json:"updated_at,omitempty"`
+	FinishedAt time.Time                `json:"finished_at,omitempty"`
+	// 清单下载链接（完成后提供）
+	ManifestURL string `json:"manifest_url,omitempty"`
+}
+
+// WorkspaceLifecycle 对应 api/openapi.yaml 中的 WorkspaceLifecycle
+type WorkspaceLifecycle struct {
+	SpaceID             string    `json:"space_id,omitempty"`
+	State               string    `json:"state,omitempty"`
+	Reason              string    `json:"reason,omitempty"`
+	SuspendedAt         time.Time `json:"suspended_at,omitempty"`
+	DeletionRequestedAt time.Time `json:"deletion_requested_at,omitempty"`
+	// 宽限期结束时间，之后后台清除全部数据
+	PurgeAfter time.Time `json:"purge_after,omitempty"`
+	// 清除前确认的完整导出包
+	PurgeBundleID string                `json:"purge_bundle_id,omitempty"`
+	Purge         *WorkspacePurgeReport `json:"purge,omitempty"`
+	PurgedAt      time.Time             `json:"purged_at,omitempty"`
+	// 最近一次清除失败的原因，下个周期重试
+	LastError string    `json:"last_error,omitempty"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// WorkspacePurgeReport 对应 api/openapi.yaml 中的 WorkspacePurgeReport
+type WorkspacePurgeReport struct {
+	BundleID      string `json:"bundle_id,omitempty"`
+	Bases         int    `json:"bases,omitempty"`
+	DynamicTables int    `json:"dynamic_tables,omitempty"`
+	// 删除的附件与缩略图文件数
+	AttachmentFiles int `json:"attachment_files,omitempty"`
+	// 删除失败的文件数
+	AttachmentErrors int `json:"attachment_errors,omitempty"`
+	// 释放的去重文件引用数
+	ReleasedBlobs int `json:"released_blobs,omitempty"`
+	// 表名 → 删除的行数
+	MetadataRows map[string]int64 `json:"metadata_rows,omitempty"`
+}
@@ -0,0 +1,249 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// ==================== Console 导出器 ====================
+
+// ConsoleExporter 将跨度写入应用日志（本地排查用）
+type ConsoleExporter struct{}
+
+// NewConsoleExporter 创建日志导出器
+func NewConsoleExporter() *ConsoleExporter {
+	return &ConsoleExporter{}
+}
+
+// ExportSpans 导出跨度
+func (e *ConsoleExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	for _, span := range spans {
+		attrs := make(map[string]interface{}, len(span.Attributes))
+		for _, attr := range span.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+		logger.Info("trace span",
+			logger.String("name", span.Name),
+			logger.String("trace_id", span.SpanContext.TraceID.String()),
+			logger.String("span_id", span.SpanContext.SpanID.String()),
+			logger.String("parent_span_id", parentSpanID(span)),
+			logger.Duration("duration", span.EndTime.Sub(span.StartTime)),
+			logger.Int("status", int(span.StatusCode)),
+			logger.Any("attributes", attrs))
+	}
+	return nil
+}
+
+// Shutdown 关闭导出器
+func (e *ConsoleExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// ==================== OTLP/HTTP 导出器 ====================
+
+// OTLPExporter 以 OTLP/HTTP JSON 协议导出到 OTel Collector
+type OTLPExporter struct {
+	endpoint string
+	headers  map[string]string
+	resource otlpResource
+	client   *http.Client
+}
+
+// NewOTLPExporter 创建 OTLP 导出器
+func NewOTLPExporter(cfg Config) *OTLPExporter {
+	attrs := map[string]string{}
+	for k, v := range cfg.ResourceAttributes {
+		attrs[k] = v
+	}
+	attrs["service.name"] = cfg.ServiceName
+	attrs["telemetry.sdk.language"] = "go"
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	resource := otlpResource{}
+	for _, k := range keys {
+		resource.Attributes = append(resource.Attributes, toOTLPAttribute(String(k, attrs[k])))
+	}
+
+	timeout := cfg.ExportTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &OTLPExporter{
+		endpoint: cfg.Endpoint,
+		headers:  cfg.Headers,
+		resource: resource,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// ExportSpans 导出跨度
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return fmt.Errorf("编码OTLP请求失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("OTLP collector 返回 %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// Shutdown 关闭导出器
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// buildRequest 构造 ExportTraceServiceRequest（JSON 映射）
+func (e *OTLPExporter) buildRequest(spans []*SpanData) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			ParentSpanID:      parentSpanID(span),
+			Name:              span.Name,
+			Kind:              int(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Status:            otlpStatus{Code: int(span.StatusCode), Message: span.StatusMessage},
+		}
+		for _, attr := range span.Attributes {
+			s.Attributes = append(s.Attributes, toOTLPAttribute(attr))
+		}
+		for _, event := range span.Events {
+			ev := otlpEvent{Name: event.Name, TimeUnixNano: strconv.FormatInt(event.Time.UnixNano(), 10)}
+			for _, attr := range event.Attributes {
+				ev.Attributes = append(ev.Attributes, toOTLPAttribute(attr))
+			}
+			s.Events = append(s.Events, ev)
+		}
+		out = append(out, s)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: e.resource,
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/easyspace-ai/luckdb/server"},
+			Spans: out,
+		}},
+	}}}
+}
+
+func parentSpanID(span *SpanData) string {
+	if !span.Parent.SpanID.IsValid() {
+		return ""
+	}
+	return span.Parent.SpanID.String()
+}
+
+// OTLP JSON 结构（opentelemetry-proto 的 JSON 映射，ID 使用十六进制）
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	Name         string         `json:"name"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"` // int64 按 JSON 映射编码为字符串
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func toOTLPAttribute(attr Attribute) otlpKeyValue {
+	kv := otlpKeyValue{Key: attr.Key}
+	switch v := attr.Value.(type) {
+	case string:
+		kv.Value.StringValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case int:
+		s := strconv.Itoa(v)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
@@ -0,0 +1,338 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// Exporter 跨度导出器
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []*SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// Config 追踪配置（默认从 OTEL_* 环境变量读取，见 ConfigFromEnv）
+type Config struct {
+	ServiceName        string
+	ResourceAttributes map[string]string
+	Exporter           string // otlp / console / none
+	Endpoint           string // OTLP/HTTP traces 端点（完整URL）
+	Headers            map[string]string
+	Sampler            string  // always_on / always_off / traceidratio / parentbased_*
+	SamplerRatio       float64 // traceidratio 的采样比例
+	BatchSize          int
+	QueueSize          int
+	ScheduleDelay      time.Duration
+	ExportTimeout      time.Duration
+}
+
+// ConfigFromEnv 按 OpenTelemetry 规范的环境变量构造配置
+//
+//	OTEL_SDK_DISABLED=true              关闭追踪
+//	OTEL_TRACES_EXPORTER=otlp|console|none（默认 none）
+//	OTEL_SERVICE_NAME / OTEL_RESOURCE_ATTRIBUTES
+//	OTEL_EXPORTER_OTLP_TRACES_ENDPOINT 或 OTEL_EXPORTER_OTLP_ENDPOINT（追加 /v1/traces）
+//	OTEL_EXPORTER_OTLP_HEADERS=k1=v1,k2=v2
+//	OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG
+//	OTEL_BSP_SCHEDULE_DELAY / OTEL_BSP_MAX_EXPORT_BATCH_SIZE / OTEL_BSP_MAX_QUEUE_SIZE / OTEL_BSP_EXPORT_TIMEOUT
+func ConfigFromEnv(defaultServiceName string) Config {
+	cfg := Config{
+		ServiceName:        envOr("OTEL_SERVICE_NAME", defaultServiceName),
+		ResourceAttributes: parseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES")),
+		Exporter:           strings.ToLower(envOr("OTEL_TRACES_EXPORTER", "none")),
+		Headers:            parseKeyValues(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")),
+		Sampler:            strings.ToLower(envOr("OTEL_TRACES_SAMPLER", "parentbased_always_on")),
+		SamplerRatio:       1,
+		BatchSize:          envInt("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", 512),
+		QueueSize:          envInt("OTEL_BSP_MAX_QUEUE_SIZE", 2048),
+		ScheduleDelay:      time.Duration(envInt("OTEL_BSP_SCHEDULE_DELAY", 5000)) * time.Millisecond,
+		ExportTimeout:      time.Duration(envInt("OTEL_BSP_EXPORT_TIMEOUT", 30000)) * time.Millisecond,
+	}
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		cfg.Exporter = "none"
+	}
+
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		cfg.Endpoint = endpoint
+	} else {
+		cfg.Endpoint = strings.TrimRight(envOr("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"), "/") + "/v1/traces"
+	}
+
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		if ratio, err := strconv.ParseFloat(arg, 64); err == nil && ratio >= 0 && ratio <= 1 {
+			cfg.SamplerRatio = ratio
+		}
+	}
+	return cfg
+}
+
+// Provider 追踪提供者（采样 + 批量导出）
+type Provider struct {
+	config   Config
+	sampler  sampler
+	exporter Exporter
+
+	queue   chan *SpanData
+	flushCh chan chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+	dropped atomic.Int64
+}
+
+var global atomic.Pointer[Provider]
+
+func globalProvider() *Provider {
+	return global.Load()
+}
+
+// Enabled 追踪是否已启用
+func Enabled() bool {
+	return globalProvider() != nil
+}
+
+// Init 按配置初始化全局追踪，返回关闭函数（刷新未导出的跨度）
+// 导出器为 none 时不启用追踪，返回的关闭函数为空操作
+func Init(cfg Config) (func(context.Context) error, error) {
+	var exporter Exporter
+	switch cfg.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "console", "logging":
+		exporter = NewConsoleExporter()
+	case "otlp":
+		exporter = NewOTLPExporter(cfg)
+	default:
+		return nil, fmt.Errorf("不支持的追踪导出器: %s", cfg.Exporter)
+	}
+
+	provider := NewProvider(cfg, exporter)
+	if old := global.Swap(provider); old != nil {
+		_ = old.Shutdown(context.Background())
+	}
+
+	logger.Info("✅ 链路追踪已启用",
+		logger.String("service", cfg.ServiceName),
+		logger.String("exporter", cfg.Exporter),
+		logger.String("endpoint", cfg.Endpoint),
+		logger.String("sampler", cfg.Sampler),
+		logger.Float64("ratio", cfg.SamplerRatio))
+
+	return func(ctx context.Context) error {
+		global.CompareAndSwap(provider, nil)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// InitFromEnv 按环境变量初始化全局追踪
+func InitFromEnv(defaultServiceName string) (func(context.Context) error, error) {
+	return Init(ConfigFromEnv(defaultServiceName))
+}
+
+// NewProvider 创建追踪提供者并启动批量导出协程
+func NewProvider(cfg Config, exporter Exporter) *Provider {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+	if cfg.QueueSize < cfg.BatchSize {
+		cfg.QueueSize = cfg.BatchSize * 4
+	}
+	if cfg.ScheduleDelay <= 0 {
+		cfg.ScheduleDelay = 5 * time.Second
+	}
+	if cfg.ExportTimeout <= 0 {
+		cfg.ExportTimeout = 30 * time.Second
+	}
+
+	p := &Provider{
+		config:   cfg,
+		sampler:  newSampler(cfg.Sampler, cfg.SamplerRatio),
+		exporter: exporter,
+		queue:    make(chan *SpanData, cfg.QueueSize),
+		flushCh:  make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// SetGlobalProvider 设置全局追踪提供者（nil 表示关闭追踪）
+func SetGlobalProvider(p *Provider) {
+	global.Store(p)
+}
+
+// enqueue 提交已结束的跨度（队列满时丢弃，不阻塞业务）
+func (p *Provider) enqueue(span *SpanData) {
+	select {
+	case p.queue <- span:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// ForceFlush 立即导出队列中的跨度
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case p.flushCh <- ack:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown 刷新剩余跨度并关闭导出器
+func (p *Provider) Shutdown(ctx context.Context) error {
+	select {
+	case <-p.done:
+		return nil
+	default:
+	}
+	close(p.done)
+	p.wg.Wait()
+	if dropped := p.dropped.Load(); dropped > 0 {
+		logger.Warn("链路追踪队列已满，部分跨度被丢弃", logger.Int64("dropped", dropped))
+	}
+	return p.exporter.Shutdown(ctx)
+}
+
+// run 批量导出循环
+func (p *Provider) run() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.ScheduleDelay)
+	defer ticker.Stop()
+
+	batch := make([]*SpanData, 0, p.config.BatchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), p.config.ExportTimeout)
+		if err := p.exporter.ExportSpans(ctx, batch); err != nil {
+			logger.Warn("导出链路跨度失败",
+				logger.Int("spans", len(batch)),
+				logger.ErrorField(err))
+		}
+		cancel()
+		batch = make([]*SpanData, 0, p.config.BatchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case span := <-p.queue:
+				batch = append(batch, span)
+				if len(batch) >= p.config.BatchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case span := <-p.queue:
+			batch = append(batch, span)
+			if len(batch) >= p.config.BatchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case ack := <-p.flushCh:
+			drain()
+			close(ack)
+		case <-p.done:
+			drain()
+			return
+		}
+	}
+}
+
+// ==================== 采样 ====================
+
+type sampler struct {
+	parentBased bool
+	ratio       float64 // 1 = 全采样，0 = 不采样
+}
+
+func newSampler(name string, ratio float64) sampler {
+	switch name {
+	case "always_on":
+		return sampler{ratio: 1}
+	case "always_off":
+		return sampler{ratio: 0}
+	case "traceidratio":
+		return sampler{ratio: ratio}
+	case "parentbased_always_off":
+		return sampler{parentBased: true, ratio: 0}
+	case "parentbased_traceidratio":
+		return sampler{parentBased: true, ratio: ratio}
+	default: // parentbased_always_on
+		return sampler{parentBased: true, ratio: 1}
+	}
+}
+
+// shouldSample 采样决策：有父级时跟随父级，否则按 TraceID 比例采样（同一链路决策一致）
+func (s sampler) shouldSample(parent SpanContext, traceID TraceID) bool {
+	if s.parentBased && parent.IsValid() {
+		return parent.Sampled
+	}
+	if s.ratio >= 1 {
+		return true
+	}
+	if s.ratio <= 0 {
+		return false
+	}
+	// 取 TraceID 低 8 字节与阈值比较（与 OTel TraceIDRatioBased 一致）
+	bound := uint64(s.ratio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < bound
+}
+
+// ==================== 环境变量辅助 ====================
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key))); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// parseKeyValues 解析 k1=v1,k2=v2 形式的配置
+func parseKeyValues(raw string) map[string]string {
+	result := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		result[key] = strings.TrimSpace(value)
+	}
+	return result
+}
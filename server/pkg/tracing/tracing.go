@@ -0,0 +1,380 @@
+// Package tracing 分布式链路追踪
+//
+// 遵循 OpenTelemetry 的数据模型与语义约定（TraceID/SpanID、SpanKind、Status、属性键名），
+// 通过 W3C traceparent 传播上下文，并以 OTLP/HTTP(JSON) 协议导出到任意 OTel Collector。
+// 未初始化（或 OTEL_SDK_DISABLED=true）时所有 API 为空操作，开销可忽略。
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// TraceID 链路ID（16字节）
+type TraceID [16]byte
+
+// SpanID 跨度ID（8字节）
+type SpanID [8]byte
+
+// IsValid 是否为有效ID（全零无效）
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+// IsValid 是否为有效ID（全零无效）
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+
+// SpanContext 可跨进程/协程传播的跨度上下文
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+// IsValid 是否为有效的跨度上下文
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanKind 跨度类型（取值与 OTLP 一致）
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+	SpanKindProducer SpanKind = 4
+	SpanKindConsumer SpanKind = 5
+)
+
+// StatusCode 跨度状态（取值与 OTLP 一致）
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute 跨度属性
+type Attribute struct {
+	Key   string
+	Value interface{} // string / bool / int / int64 / float64
+}
+
+// String 字符串属性
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int 整数属性
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+// Int64 整数属性
+func Int64(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Bool 布尔属性
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float64 浮点属性
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Event 跨度内事件（如异常）
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes []Attribute
+}
+
+// SpanData 已结束跨度的只读快照（交给导出器）
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	Parent        SpanContext
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    []Attribute
+	Events        []Event
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+// Span 跨度
+// nil 或未采样的 Span 上所有方法均为空操作
+type Span struct {
+	provider *Provider
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext 返回跨度上下文
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+// IsRecording 跨度是否在记录（已采样且未结束）
+func (s *Span) IsRecording() bool {
+	if s == nil || s.provider == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.ended
+}
+
+// SetName 修改跨度名称（如 HTTP 路由在处理后才确定）
+func (s *Span) SetName(name string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.data.Name = name
+	s.mu.Unlock()
+}
+
+// SetAttributes 设置属性
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent 添加事件
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.data.Events = append(s.data.Events, Event{Name: name, Time: time.Now(), Attributes: attrs})
+	s.mu.Unlock()
+}
+
+// RecordError 记录错误并将状态置为 Error
+func (s *Span) RecordError(err error) {
+	if err == nil || !s.IsRecording() {
+		return
+	}
+	s.AddEvent("exception",
+		String("exception.type", fmt.Sprintf("%T", err)),
+		String("exception.message", err.Error()))
+	s.SetStatus(StatusError, err.Error())
+}
+
+// SetStatus 设置状态
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.data.StatusCode = code
+	if code == StatusError {
+		s.data.StatusMessage = message
+	}
+	s.mu.Unlock()
+}
+
+// End 结束跨度并提交导出
+func (s *Span) End() {
+	if s == nil || s.provider == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.provider.enqueue(&data)
+}
+
+// Finish 记录错误（如有）并结束跨度，便于 defer 配合具名返回值使用
+func (s *Span) Finish(err error) {
+	s.RecordError(err)
+	s.End()
+}
+
+// StartOption 创建跨度的选项
+type StartOption func(*startConfig)
+
+type startConfig struct {
+	kind       SpanKind
+	attributes []Attribute
+}
+
+// WithSpanKind 指定跨度类型
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(c *startConfig) { c.kind = kind }
+}
+
+// WithAttributes 创建时附带属性
+func WithAttributes(attrs ...Attribute) StartOption {
+	return func(c *startConfig) { c.attributes = append(c.attributes, attrs...) }
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// Start 创建子跨度（父跨度取自 ctx）并返回携带新跨度的 ctx
+// 调用方须 defer span.End()
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	provider := globalProvider()
+	if provider == nil {
+		return ctx, nil
+	}
+
+	cfg := startConfig{kind: SpanKindInternal}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID()}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+	} else {
+		sc.TraceID = newTraceID()
+	}
+	sc.Sampled = provider.sampler.shouldSample(parent, sc.TraceID)
+
+	if !sc.Sampled {
+		// 未采样：仍传播上下文，保证下游采样决策一致
+		return context.WithValue(ctx, remoteKey{}, sc), nil
+	}
+
+	span := &Span{
+		provider: provider,
+		data: SpanData{
+			Name:        name,
+			Kind:        cfg.kind,
+			SpanContext: sc,
+			Parent:      parent,
+			StartTime:   time.Now(),
+			Attributes:  cfg.attributes,
+		},
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext 获取 ctx 中的当前跨度（可能为 nil）
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext 获取 ctx 中的跨度上下文（本地跨度优先，其次为传入的远程上下文）
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+// ContextWithRemoteSpanContext 将外部传入的跨度上下文作为父级放入 ctx
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// TraceIDFromContext 当前链路ID（用于日志关联，无链路时为空）
+func TraceIDFromContext(ctx context.Context) string {
+	sc := SpanContextFromContext(ctx)
+	if !sc.TraceID.IsValid() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+// 日志关联：logger.Ctx / logger.Named(...).Ctx 自动附带 trace_id、span_id
+func init() {
+	logger.RegisterContextFields(func(ctx context.Context) []zap.Field {
+		sc := SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			return nil
+		}
+		return []zap.Field{
+			zap.String("trace_id", sc.TraceID.String()),
+			zap.String("span_id", sc.SpanID.String()),
+		}
+	})
+}
+
+// Detach 返回不随请求取消、但保留当前链路的 ctx
+// 用于在请求结束后继续运行的后台协程
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if span := SpanFromContext(ctx); span != nil {
+		return context.WithValue(detached, spanKey{}, span)
+	}
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		return context.WithValue(detached, remoteKey{}, sc)
+	}
+	return detached
+}
+
+// Go 在新协程中执行 fn，并为其创建挂在当前链路下的跨度
+// 协程不随请求取消（见 Detach）；fn panic 时记录到跨度与日志，不会使进程退出
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	ctx = Detach(ctx)
+	go func() {
+		ctx, span := Start(ctx, name, WithSpanKind(SpanKindConsumer))
+		defer span.End()
+		defer func() {
+			if r := recover(); r != nil {
+				span.RecordError(fmt.Errorf("panic: %v", r))
+				logger.Ctx(ctx).Error("后台协程异常",
+					zap.String("goroutine", name),
+					zap.Any("panic", r),
+					zap.Stack("stack"))
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+// ID 生成
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
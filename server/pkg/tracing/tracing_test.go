@@ -0,0 +1,174 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+func (e *memoryExporter) ExportSpans(ctx context.Context, spans []*SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	const header = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(header)
+	if !ok || !sc.Sampled || !sc.Remote {
+		t.Fatalf("ParseTraceparent(%q) = %+v, %v", header, sc, ok)
+	}
+	if got := FormatTraceparent(sc); got != header {
+		t.Fatalf("FormatTraceparent = %q, want %q", got, header)
+	}
+
+	for _, bad := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		if _, ok := ParseTraceparent(bad); ok {
+			t.Errorf("ParseTraceparent(%q) should fail", bad)
+		}
+	}
+}
+
+func TestSpansShareTraceAcrossGoroutines(t *testing.T) {
+	exporter := &memoryExporter{}
+	provider := NewProvider(Config{Sampler: "always_on", ScheduleDelay: time.Hour}, exporter)
+	SetGlobalProvider(provider)
+	defer SetGlobalProvider(nil)
+
+	remote, _ := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	reqCtx, cancel := context.WithCancel(ContextWithRemoteSpanContext(context.Background(), remote))
+	ctx, root := Start(reqCtx, "GET /records", WithSpanKind(SpanKindServer))
+
+	done := make(chan struct{})
+	Go(ctx, "background job", func(jobCtx context.Context) {
+		defer close(done)
+		cancel() // 请求结束不影响后台任务
+		if jobCtx.Err() != nil {
+			t.Error("detached context should not be canceled")
+		}
+		_, child := Start(jobCtx, "db.query")
+		child.RecordError(context.DeadlineExceeded)
+		child.End()
+	})
+	<-done
+	root.End()
+
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(exporter.spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(exporter.spans))
+	}
+	byName := map[string]*SpanData{}
+	for _, s := range exporter.spans {
+		if s.SpanContext.TraceID != remote.TraceID {
+			t.Errorf("span %q has trace %s, want %s", s.Name, s.SpanContext.TraceID, remote.TraceID)
+		}
+		byName[s.Name] = s
+	}
+	if byName["GET /records"].Parent.SpanID != remote.SpanID {
+		t.Error("root span should be parented to remote span")
+	}
+	if byName["background job"].Parent.SpanID != byName["GET /records"].SpanContext.SpanID {
+		t.Error("goroutine span should be parented to request span")
+	}
+	if db := byName["db.query"]; db.StatusCode != StatusError || len(db.Events) != 1 {
+		t.Errorf("db span should record error: %+v", db)
+	}
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	exporter := &memoryExporter{}
+	provider := NewProvider(Config{Sampler: "always_on", ScheduleDelay: time.Hour}, exporter)
+	SetGlobalProvider(provider)
+	defer SetGlobalProvider(nil)
+
+	Go(context.Background(), "panicking job", func(context.Context) {
+		panic("boom")
+	})
+
+	// 跨度在恢复 panic 之后结束
+	deadline := time.Now().Add(time.Second)
+	for {
+		if err := provider.ForceFlush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		exporter.mu.Lock()
+		n := len(exporter.spans)
+		exporter.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("panicking goroutine span was not exported")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if span := exporter.spans[0]; span.StatusCode != StatusError || !strings.Contains(span.StatusMessage, "boom") {
+		t.Errorf("panic should be recorded on span: %+v", span)
+	}
+}
+
+func TestSamplerRatioIsDeterministic(t *testing.T) {
+	s := newSampler("traceidratio", 0.5)
+	low := TraceID{15: 0x01}
+	high := TraceID{8: 0xff, 15: 0xff}
+	if !s.shouldSample(SpanContext{}, low) || s.shouldSample(SpanContext{}, high) {
+		t.Fatal("ratio sampler should compare trace id against bound")
+	}
+
+	parentBased := newSampler("parentbased_always_on", 1)
+	parent := SpanContext{TraceID: high, SpanID: SpanID{1}, Sampled: false}
+	if parentBased.shouldSample(parent, high) {
+		t.Fatal("parent-based sampler should follow unsampled parent")
+	}
+}
+
+func TestOTLPRequestEncoding(t *testing.T) {
+	exporter := NewOTLPExporter(Config{ServiceName: "luckdb", Endpoint: "http://collector/v1/traces"})
+	start := time.Unix(100, 0)
+	req := exporter.buildRequest([]*SpanData{{
+		Name:        "cache.get",
+		Kind:        SpanKindClient,
+		SpanContext: SpanContext{TraceID: TraceID{1}, SpanID: SpanID{2}},
+		StartTime:   start,
+		EndTime:     start.Add(time.Millisecond),
+		Attributes:  []Attribute{String("cache.key", "k"), Bool("cache.hit", true), Int("rows", 3)},
+	}})
+
+	raw, err := json.Marshal(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(raw)
+	for _, want := range []string{
+		`"traceId":"01000000000000000000000000000000"`,
+		`"spanId":"0200000000000000"`,
+		`"startTimeUnixNano":"100000000000"`,
+		`{"key":"service.name","value":{"stringValue":"luckdb"}}`,
+		`{"key":"cache.hit","value":{"boolValue":true}}`,
+		`{"key":"rows","value":{"intValue":"3"}}`,
+		`"kind":3`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("OTLP body missing %s\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package userlocale 请求用户的时区与语言 ✨
+//
+// 认证后的中间件根据用户配置（或客户端的 X-Time-Zone 请求头）写入 context，
+// 视图过滤中的相对日期（today、yesterday …）、公式中的 TODAY()/NOW() 与单元格展示文本据此计算，
+// 而不是使用服务器时区。未写入时按 UTC 处理。
+package userlocale
+
+import (
+	"context"
+	"time"
+)
+
+// Locale 用户的时区与语言
+type Locale struct {
+	TimeZone string // IANA 时区名称，如 Asia/Shanghai
+	Language string // 语言标签，如 zh-CN、en-US
+}
+
+type localeKey struct{}
+
+// WithLocale 将用户的时区与语言写入 context
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext 读取 context 中的用户时区与语言
+func FromContext(ctx context.Context) (Locale, bool) {
+	if ctx == nil {
+		return Locale{}, false
+	}
+	locale, ok := ctx.Value(localeKey{}).(Locale)
+	return locale, ok
+}
+
+// TimeZone 用户时区名称，未设置或无效时为 UTC
+func TimeZone(ctx context.Context) string {
+	return Location(ctx).String()
+}
+
+// Location 用户时区，未设置或无效时为 UTC
+func Location(ctx context.Context) *time.Location {
+	locale, ok := FromContext(ctx)
+	if !ok || locale.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(locale.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Language 用户语言，未设置时为空
+func Language(ctx context.Context) string {
+	locale, _ := FromContext(ctx)
+	return locale.Language
+}
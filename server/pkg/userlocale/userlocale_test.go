@@ -0,0 +1,22 @@
+package userlocale
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocation(t *testing.T) {
+	if loc := Location(context.Background()); loc.String() != "UTC" {
+		t.Fatalf("未设置时应为 UTC，得到 %s", loc)
+	}
+
+	ctx := WithLocale(context.Background(), Locale{TimeZone: "Asia/Shanghai", Language: "zh-CN"})
+	if TimeZone(ctx) != "Asia/Shanghai" || Language(ctx) != "zh-CN" {
+		t.Fatalf("unexpected locale: %s %s", TimeZone(ctx), Language(ctx))
+	}
+
+	ctx = WithLocale(context.Background(), Locale{TimeZone: "Mars/Base"})
+	if TimeZone(ctx) != "UTC" {
+		t.Fatalf("无效时区应回退到 UTC，得到 %s", TimeZone(ctx))
+	}
+}